@@ -45,7 +45,9 @@ import (
 	"github.com/coder/coder/v2/agent/agentfiles"
 	"github.com/coder/coder/v2/agent/agentgit"
 	"github.com/coder/coder/v2/agent/agentproc"
+	"github.com/coder/coder/v2/agent/agentquiesce"
 	"github.com/coder/coder/v2/agent/agentscripts"
+	"github.com/coder/coder/v2/agent/agentservices"
 	"github.com/coder/coder/v2/agent/agentsocket"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/boundarylogproxy"
@@ -114,6 +116,7 @@ type Options struct {
 	Devcontainers                   bool
 	DevcontainerAPIOptions          []agentcontainers.Option // Enable Devcontainers for these to be effective.
 	GitAPIOptions                   []agentgit.Option
+	Quiesce                         agentquiesce.Config
 	Clock                           quartz.Clock
 	SocketServerEnabled             bool
 	SocketPath                      string // Path for the agent socket server socket
@@ -145,6 +148,15 @@ type Client interface {
 	ConnectRPC210WithRole(ctx context.Context, role string) (
 		proto.DRPCAgentClient210, tailnetproto.DRPCTailnetClient28, error,
 	)
+	ConnectRPC211(ctx context.Context) (
+		proto.DRPCAgentClient211, tailnetproto.DRPCTailnetClient28, error,
+	)
+	// ConnectRPC211WithRole is like ConnectRPC211 but sends an explicit
+	// role query parameter to the server. The workspace agent should
+	// use role "agent" to enable connection monitoring.
+	ConnectRPC211WithRole(ctx context.Context, role string) (
+		proto.DRPCAgentClient211, tailnetproto.DRPCTailnetClient28, error,
+	)
 	tailnet.DERPMapRewriter
 	agentsdk.RefreshableSessionTokenProvider
 }
@@ -234,6 +246,7 @@ func New(options Options) Agent {
 		lifecycleReported:       make(chan codersdk.WorkspaceAgentLifecycle, 1),
 		lifecycleStates:         []agentsdk.PostLifecycleRequest{{State: codersdk.WorkspaceAgentLifecycleCreated}},
 		reportConnectionsUpdate: make(chan struct{}, 1),
+		envRefreshes:            make(chan chan error),
 		listeningPortsHandler: listeningPortsHandler{
 			getter:      options.ListeningPortsGetter,
 			ignorePorts: maps.Clone(options.IgnorePorts),
@@ -256,6 +269,7 @@ func New(options Options) Agent {
 		devcontainers:                   options.Devcontainers,
 		containerAPIOptions:             options.DevcontainerAPIOptions,
 		gitAPIOptions:                   options.GitAPIOptions,
+		quiesceConfig:                   options.Quiesce,
 		socketPath:                      options.SocketPath,
 		socketServerEnabled:             options.SocketServerEnabled,
 		agentFirewallLogProxySocketPath: options.AgentFirewallLogProxySocketPath,
@@ -312,7 +326,10 @@ type agent struct {
 	// secrets are held separately from the manifest so that code paths that
 	// only need manifest data cannot accidentally access or leak secret
 	// values. Callers that need secrets must explicitly load this.
-	secrets                            atomic.Pointer[[]agentsdk.WorkspaceSecret]
+	secrets atomic.Pointer[[]agentsdk.WorkspaceSecret]
+	// envRefreshes receives requests to re-fetch the environment variables
+	// and secrets from coderd. The result is sent on the request channel.
+	envRefreshes                       chan chan error
 	reportMetadataInterval             time.Duration
 	statsReportInterval                time.Duration
 	scriptRunner                       *agentscripts.Runner
@@ -353,10 +370,13 @@ type agent struct {
 	containerAPIOptions []agentcontainers.Option
 	containerAPI        *agentcontainers.API
 	gitAPIOptions       []agentgit.Option
+	quiesceConfig       agentquiesce.Config
 
 	filesAPI         *agentfiles.API
 	gitAPI           *agentgit.API
 	processAPI       *agentproc.API
+	quiesceAPI       *agentquiesce.API
+	servicesAPI      *agentservices.API
 	desktopAPI       *agentdesktop.API
 	mcpManager       *agentmcp.Manager
 	mcpAPI           *agentmcp.API
@@ -458,6 +478,7 @@ func (a *agent) init() {
 		GetScriptLogger: func(logSourceID uuid.UUID) agentscripts.ScriptLogger {
 			return a.logSender.GetScriptLogger(logSourceID)
 		},
+		GetScriptSecrets: a.getScriptSecrets,
 	})
 	// Register runner metrics. If the prom registry is nil, the metrics
 	// will not report anywhere.
@@ -482,6 +503,8 @@ func (a *agent) init() {
 		}
 		return ""
 	})
+	a.quiesceAPI = agentquiesce.NewAPI(a.logger.Named("quiesce"), a.execer, a.clock, a.quiesceConfig, a.updateCommandEnv)
+	a.servicesAPI = agentservices.NewAPI(a.logger.Named("services"), a.execer, a.clock, a.updateCommandEnv, a.reportServiceStatus)
 	gitOpts := append([]agentgit.Option{agentgit.WithClock(a.clock)}, a.gitAPIOptions...)
 	a.gitAPI = agentgit.NewAPI(a.logger.Named("git"), pathStore, gitOpts...)
 	desktop := agentdesktop.NewPortableDesktop(
@@ -1176,7 +1199,7 @@ func (a *agent) run() (retErr error) {
 	// ConnectRPC returns the dRPC connection we use for the Agent and Tailnet v2+ APIs.
 	// We pass role "agent" to enable connection monitoring on the server, which tracks
 	// the agent's connectivity state (first_connected_at, last_connected_at, disconnected_at).
-	aAPI, tAPI, err := a.client.ConnectRPC210WithRole(a.hardCtx, "agent")
+	aAPI, tAPI, err := a.client.ConnectRPC211WithRole(a.hardCtx, "agent")
 	if err != nil {
 		return err
 	}
@@ -1307,6 +1330,14 @@ func (a *agent) run() (retErr error) {
 
 	connMan.startAgentAPI("handle manifest", gracefulShutdownBehaviorStop, a.handleManifest(manifestOK))
 
+	connMan.startAgentAPI("refresh env loop", gracefulShutdownBehaviorStop,
+		func(ctx context.Context, aAPI proto.DRPCAgentClient28) error {
+			if err := manifestOK.wait(ctx); err != nil {
+				return xerrors.Errorf("no manifest: %w", err)
+			}
+			return a.refreshEnvLoop(ctx, aAPI)
+		})
+
 	connMan.startAgentAPI("app health reporter", gracefulShutdownBehaviorStop,
 		func(ctx context.Context, aAPI proto.DRPCAgentClient28) error {
 			if err := manifestOK.wait(ctx); err != nil {
@@ -1551,6 +1582,11 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 				a.metrics.startupScriptSeconds.WithLabelValues(label).Set(dur)
 				a.scriptRunner.StartCron()
 
+				// Services usually depend on tools installed by the
+				// startup scripts, so they are started afterwards, even
+				// if a script failed.
+				a.servicesAPI.Start(manifest.Services, manifest.Directory)
+
 				// Startup finished (success or terminal failure): release
 				// the context gate. MCP servers connect below and
 				// re-trigger a push once up, so we don't block readiness
@@ -1678,6 +1714,7 @@ func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(co
 // - Environment variables passed via the agent manifest (overriding predefined and current)
 // - User secret variables passed via the agent manifest (overriding predefined, current, and manifest env vars)
 // - Agent-level environment variables (overriding all)
+// - Git configuration blocking pushes, if the workspace is in read-only mode
 func (a *agent) updateCommandEnv(current []string) (updated []string, err error) {
 	manifest := a.manifest.Load()
 	if manifest == nil {
@@ -1758,6 +1795,15 @@ func (a *agent) updateCommandEnv(current []string) (updated []string, err error)
 		envs[k] = v
 	}
 
+	// Workspaces in read-only mode are being investigated, so their contents
+	// must not be pushed anywhere. This is applied last so that neither the
+	// template nor the environment can override it.
+	if manifest.ReadOnly {
+		if err := a.readOnlyGitEnv(envs); err != nil {
+			return nil, xerrors.Errorf("configure read-only mode: %w", err)
+		}
+	}
+
 	// Prepend the agent script bin directory to the PATH
 	// (this is where Coder modules place their binaries).
 	if _, ok := envs["PATH"]; !ok {
@@ -1899,6 +1945,13 @@ func (a *agent) createTailnet(
 	if err := a.sshServer.UpdateHostSigner(keySeed); err != nil {
 		return nil, xerrors.Errorf("update host signer: %w", err)
 	}
+	if client, ok := a.client.(sshHostCertificateClient); ok {
+		if err := a.trackGoroutine(func() {
+			a.renewSSHHostCertificate(a.hardCtx, client, keySeed)
+		}); err != nil {
+			return nil, err
+		}
+	}
 
 	for _, port := range []int{workspacesdk.AgentSSHPort, workspacesdk.AgentStandardSSHPort} {
 		sshListener, err := network.Listen("tcp", ":"+strconv.Itoa(port))
@@ -2160,6 +2213,20 @@ func (a *agent) Collect(ctx context.Context, networkStats map[netlogtype.Connect
 		stats.TxBytes += int64(counts.TxBytes)
 		// #nosec G115 - Safe conversions for network statistics which we expect to be within int64 range
 		stats.TxPackets += int64(counts.TxPackets)
+
+		// #nosec G115 - Safe conversions for network statistics which we expect to be within int64 range
+		rx, tx := int64(counts.RxBytes), int64(counts.TxBytes)
+		switch connectionClass(conn) {
+		case connectionClassSSH:
+			stats.RxBytesSsh += rx
+			stats.TxBytesSsh += tx
+		case connectionClassReconnectingPTY:
+			stats.RxBytesReconnectingPty += rx
+			stats.TxBytesReconnectingPty += tx
+		default:
+			stats.RxBytesPortForward += rx
+			stats.TxBytesPortForward += tx
+		}
 	}
 
 	// The count of active sessions.
@@ -2401,6 +2468,14 @@ func (a *agent) Close() error {
 		a.logger.Error(a.hardCtx, "process API close", slog.Error(err))
 	}
 
+	if err := a.quiesceAPI.Close(); err != nil {
+		a.logger.Error(a.hardCtx, "quiesce API close", slog.Error(err))
+	}
+
+	if err := a.servicesAPI.Close(); err != nil {
+		a.logger.Error(a.hardCtx, "services API close", slog.Error(err))
+	}
+
 	if err := a.desktopAPI.Close(); err != nil {
 		a.logger.Error(a.hardCtx, "desktop API close", slog.Error(err))
 	}
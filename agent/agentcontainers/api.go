@@ -14,6 +14,7 @@ import (
 	"regexp"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -1384,7 +1385,9 @@ func (api *API) handleDevcontainerDelete(w http.ResponseWriter, r *http.Request)
 }
 
 // handleDevcontainerRecreate handles the HTTP request to recreate a
-// devcontainer by referencing the container.
+// devcontainer by referencing the container. If the no_cache query
+// parameter is true, the image is rebuilt without the build cache so
+// that base images are pulled and features are re-run.
 func (api *API) handleDevcontainerRecreate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	devcontainerID := chi.URLParam(r, "devcontainer")
@@ -1396,6 +1399,18 @@ func (api *API) handleDevcontainerRecreate(w http.ResponseWriter, r *http.Reques
 		})
 		return
 	}
+	var noCache bool
+	if v := r.URL.Query().Get("no_cache"); v != "" {
+		var err error
+		noCache, err = strconv.ParseBool(v)
+		if err != nil {
+			httpapi.Write(ctx, w, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid no_cache query parameter.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
 
 	api.mu.Lock()
 
@@ -1423,8 +1438,12 @@ func (api *API) handleDevcontainerRecreate(w http.ResponseWriter, r *http.Reques
 	api.knownDevcontainers[dc.WorkspaceFolder] = dc
 	api.broadcastUpdatesLocked()
 
+	opts := []DevcontainerCLIUpOptions{WithRemoveExistingContainer()}
+	if noCache {
+		opts = append(opts, WithBuildNoCache())
+	}
 	go func() {
-		_ = api.CreateDevcontainer(dc.WorkspaceFolder, dc.ConfigPath, WithRemoveExistingContainer())
+		_ = api.CreateDevcontainer(dc.WorkspaceFolder, dc.ConfigPath, opts...)
 	}()
 
 	api.mu.Unlock()
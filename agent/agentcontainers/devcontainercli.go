@@ -123,6 +123,14 @@ func WithRemoveExistingContainer() DevcontainerCLIUpOptions {
 	}
 }
 
+// WithBuildNoCache is an option to build the container image without
+// the build cache, re-running all features.
+func WithBuildNoCache() DevcontainerCLIUpOptions {
+	return func(o *DevcontainerCLIUpConfig) {
+		o.Args = append(o.Args, "--build-no-cache")
+	}
+}
+
 // WithUpOutput sets additional stdout and stderr writers for logs
 // during Up operations.
 func WithUpOutput(stdout, stderr io.Writer) DevcontainerCLIUpOptions {
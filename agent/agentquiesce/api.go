@@ -0,0 +1,205 @@
+// Package agentquiesce pauses workspace writes so that external backups of
+// workspace volumes are consistent.
+//
+// Quiescing flushes filesystem buffers and runs the template-defined quiesce
+// script, e.g. to stop a database or freeze a filesystem. The template-defined
+// resume script undoes it, either on request or automatically once the
+// requested timeout elapsed, so that a backup job that dies never leaves the
+// workspace paused.
+package agentquiesce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	// DefaultTimeout is how long the workspace stays quiesced if the request
+	// does not specify a timeout.
+	DefaultTimeout = 5 * time.Minute
+	// MaxTimeout is the longest the workspace may stay quiesced.
+	MaxTimeout = time.Hour
+	// scriptTimeout bounds the quiesce and resume scripts.
+	scriptTimeout = 5 * time.Minute
+)
+
+// Config holds the template-defined scripts.
+type Config struct {
+	// QuiesceScript is run with sh after filesystem buffers were flushed.
+	QuiesceScript string
+	// ResumeScript is run with sh to undo the quiesce script.
+	ResumeScript string
+}
+
+// API exposes quiesce operations through the agent.
+type API struct {
+	logger    slog.Logger
+	execer    agentexec.Execer
+	clock     quartz.Clock
+	config    Config
+	updateEnv func(current []string) (updated []string, err error)
+
+	mu        sync.Mutex
+	resumesAt time.Time
+	timer     *quartz.Timer
+}
+
+// NewAPI creates a new quiesce API handler.
+func NewAPI(logger slog.Logger, execer agentexec.Execer, clock quartz.Clock, config Config, updateEnv func(current []string) (updated []string, err error)) *API {
+	return &API{
+		logger:    logger,
+		execer:    execer,
+		clock:     clock,
+		config:    config,
+		updateEnv: updateEnv,
+	}
+}
+
+// Close resumes the workspace if it is quiesced.
+func (api *API) Close() error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return api.resumeLocked(context.Background())
+}
+
+// Routes returns the HTTP handler for quiesce-related routes.
+func (api *API) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Post("/", api.handleQuiesce)
+	r.Delete("/", api.handleResume)
+	return r
+}
+
+// handleQuiesce quiesces the workspace and returns once it is safe to back
+// up. Quiescing an already quiesced workspace extends the timeout.
+func (api *API) handleQuiesce(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req workspacesdk.QuiesceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Request body must be valid JSON.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	timeout := time.Duration(req.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	if timeout < 0 || timeout > MaxTimeout {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Timeout must be positive and at most %s.", MaxTimeout),
+		})
+		return
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if api.timer == nil {
+		syncFilesystems()
+		if api.config.QuiesceScript != "" {
+			if err := api.runScript(ctx, api.config.QuiesceScript); err != nil {
+				// Undo whatever the script got to before failing.
+				if api.config.ResumeScript != "" {
+					if rerr := api.runScript(ctx, api.config.ResumeScript); rerr != nil {
+						api.logger.Error(ctx, "resume after failed quiesce", slog.Error(rerr))
+					}
+				}
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Failed to quiesce workspace.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+		}
+		api.timer = api.clock.AfterFunc(timeout, api.autoResume, "agentquiesce", "resume")
+	} else {
+		api.timer.Reset(timeout, "agentquiesce", "extend")
+	}
+	api.resumesAt = api.clock.Now().Add(timeout)
+	api.logger.Info(ctx, "workspace quiesced", slog.F("resumes_at", api.resumesAt))
+
+	httpapi.Write(ctx, rw, http.StatusOK, workspacesdk.QuiesceResponse{
+		ResumesAt: api.resumesAt,
+	})
+}
+
+// handleResume resumes a quiesced workspace. Resuming a workspace that is
+// not quiesced is a no-op.
+func (api *API) handleResume(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if err := api.resumeLocked(ctx); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to resume workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+func (api *API) autoResume() {
+	ctx := context.Background()
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	api.logger.Info(ctx, "quiesce timeout elapsed, resuming workspace")
+	if err := api.resumeLocked(ctx); err != nil {
+		api.logger.Error(ctx, "resume quiesced workspace", slog.Error(err))
+	}
+}
+
+func (api *API) resumeLocked(ctx context.Context) error {
+	if api.timer == nil {
+		return nil
+	}
+	api.timer.Stop()
+	api.timer = nil
+	api.resumesAt = time.Time{}
+	if api.config.ResumeScript == "" {
+		return nil
+	}
+	return api.runScript(ctx, api.config.ResumeScript)
+}
+
+func (api *API) runScript(ctx context.Context, script string) error {
+	ctx, cancel := context.WithTimeout(ctx, scriptTimeout)
+	defer cancel()
+
+	cmd := api.execer.CommandContext(ctx, "sh", "-c", script)
+	cmd.Env = os.Environ()
+	if api.updateEnv != nil {
+		env, err := api.updateEnv(cmd.Env)
+		if err != nil {
+			return xerrors.Errorf("update env: %w", err)
+		}
+		cmd.Env = env
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return xerrors.Errorf("run script: %w: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package agentquiesce_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentquiesce"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestAPI(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("scripts are run with sh")
+	}
+
+	quiesce := func(t *testing.T, handler http.Handler, timeout time.Duration) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(workspacesdk.QuiesceRequest{TimeoutSeconds: int64(timeout.Seconds())})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequestWithContext(testutil.Context(t, testutil.WaitLong), http.MethodPost, "/", bytes.NewReader(body))
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	resume := func(t *testing.T, handler http.Handler) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequestWithContext(testutil.Context(t, testutil.WaitLong), http.MethodDelete, "/", nil)
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	// counter returns a script appending to a file and a func reading how
+	// often it ran.
+	counter := func(t *testing.T, name string) (string, func() int) {
+		path := filepath.Join(t.TempDir(), name)
+		return fmt.Sprintf("echo >> %q", path), func() int {
+			b, err := os.ReadFile(path)
+			if os.IsNotExist(err) {
+				return 0
+			}
+			require.NoError(t, err)
+			return bytes.Count(b, []byte("\n"))
+		}
+	}
+
+	t.Run("QuiesceAndResume", func(t *testing.T) {
+		t.Parallel()
+
+		quiesceScript, quiesced := counter(t, "quiesced")
+		resumeScript, resumed := counter(t, "resumed")
+		clock := quartz.NewMock(t)
+		api := agentquiesce.NewAPI(testutil.Logger(t), agentexec.DefaultExecer, clock, agentquiesce.Config{
+			QuiesceScript: quiesceScript,
+			ResumeScript:  resumeScript,
+		}, nil)
+		t.Cleanup(func() { _ = api.Close() })
+		handler := api.Routes()
+
+		w := quiesce(t, handler, time.Minute)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res workspacesdk.QuiesceResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+		require.Equal(t, clock.Now().Add(time.Minute), res.ResumesAt)
+		require.Equal(t, 1, quiesced())
+
+		// Quiescing again only extends the timeout.
+		w = quiesce(t, handler, 2*time.Minute)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Equal(t, 1, quiesced())
+
+		w = resume(t, handler)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		require.Equal(t, 1, resumed())
+
+		// Resuming a workspace that is not quiesced is a no-op.
+		w = resume(t, handler)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		require.Equal(t, 1, resumed())
+	})
+
+	t.Run("AutoResume", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		resumeScript, resumed := counter(t, "resumed")
+		clock := quartz.NewMock(t)
+		api := agentquiesce.NewAPI(testutil.Logger(t), agentexec.DefaultExecer, clock, agentquiesce.Config{
+			ResumeScript: resumeScript,
+		}, nil)
+		t.Cleanup(func() { _ = api.Close() })
+		handler := api.Routes()
+
+		w := quiesce(t, handler, time.Minute)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Equal(t, 0, resumed())
+
+		clock.Advance(time.Minute).MustWait(ctx)
+		require.Eventually(t, func() bool {
+			return resumed() == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("FailedQuiesceResumes", func(t *testing.T) {
+		t.Parallel()
+
+		resumeScript, resumed := counter(t, "resumed")
+		api := agentquiesce.NewAPI(testutil.Logger(t), agentexec.DefaultExecer, quartz.NewMock(t), agentquiesce.Config{
+			QuiesceScript: "exit 1",
+			ResumeScript:  resumeScript,
+		}, nil)
+		t.Cleanup(func() { _ = api.Close() })
+
+		w := quiesce(t, api.Routes(), time.Minute)
+		require.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+		require.Equal(t, 1, resumed())
+	})
+
+	t.Run("InvalidTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		api := agentquiesce.NewAPI(testutil.Logger(t), agentexec.DefaultExecer, quartz.NewMock(t), agentquiesce.Config{}, nil)
+		t.Cleanup(func() { _ = api.Close() })
+
+		w := quiesce(t, api.Routes(), 2*agentquiesce.MaxTimeout)
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package agentquiesce
+
+import "syscall"
+
+// syncFilesystems flushes filesystem buffers to disk.
+func syncFilesystems() {
+	syscall.Sync()
+}
@@ -0,0 +1,5 @@
+package agentquiesce
+
+// syncFilesystems is a no-op on Windows, which has no system-wide flush.
+// Templates can flush volumes in their quiesce script instead.
+func syncFilesystems() {}
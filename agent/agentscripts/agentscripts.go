@@ -56,6 +56,10 @@ type Options struct {
 	SSHServer       *agentssh.Server
 	Filesystem      afero.Fs
 	GetScriptLogger func(logSourceID uuid.UUID) ScriptLogger
+	// GetScriptSecrets returns the template secrets referenced by a script,
+	// keyed by environment variable name. The secrets are only added to the
+	// environment of the script process. It is optional.
+	GetScriptSecrets func(ctx context.Context, scriptID uuid.UUID) (map[string]string, error)
 }
 
 // New creates a runner for the provided scripts.
@@ -317,6 +321,18 @@ func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript,
 	cmd.Env = append(cmd.Env, "CODER_SCRIPT_DATA_DIR="+scriptDataDir)
 	cmd.Env = append(cmd.Env, "CODER_SCRIPT_BIN_DIR="+r.ScriptBinDir())
 
+	// Secrets are fetched right before the script runs and never written
+	// to disk, so they can't leak through the script or its data dir.
+	if r.GetScriptSecrets != nil {
+		secrets, err := r.GetScriptSecrets(ctx, script.ID)
+		if err != nil {
+			return xerrors.Errorf("%s script: get secrets: %w", logPath, err)
+		}
+		for name, value := range secrets {
+			cmd.Env = append(cmd.Env, name+"="+value)
+		}
+	}
+
 	scriptLogger := r.GetScriptLogger(script.LogSourceID)
 	// If ctx is canceled here (or in a writer below), we may be
 	// discarding logs, but that's okay because we're shutting down
@@ -99,6 +99,35 @@ func TestEnv(t *testing.T) {
 	require.Contains(t, log[1].Output, runner.ScriptBinDir())
 }
 
+func TestScriptSecrets(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	fLogger := newFakeScriptLogger()
+	runner := setup(t, func(uuid2 uuid.UUID) agentscripts.ScriptLogger {
+		return fLogger
+	})
+	defer runner.Close()
+	scriptID := uuid.New()
+	runner.GetScriptSecrets = func(_ context.Context, id uuid.UUID) (map[string]string, error) {
+		assert.Equal(t, scriptID, id)
+		return map[string]string{"DATABASE_PASSWORD": "hunter2"}, nil
+	}
+	script := "echo $DATABASE_PASSWORD"
+	if runtime.GOOS == "windows" {
+		script = "cmd.exe /c echo %DATABASE_PASSWORD%"
+	}
+	aAPI := agenttest.NewFakeAgentAPI(t, testutil.Logger(t), nil, nil)
+	err := runner.Init([]codersdk.WorkspaceAgentScript{{
+		ID:          scriptID,
+		LogSourceID: uuid.New(),
+		Script:      script,
+	}}, aAPI.ScriptCompleted)
+	require.NoError(t, err)
+	require.NoError(t, runner.Execute(context.Background(), agentscripts.ExecuteAllScripts))
+	log := testutil.TryReceive(ctx, t, fLogger.logs)
+	require.Contains(t, log.Output, "hunter2")
+}
+
 func TestTimeout(t *testing.T) {
 	t.Parallel()
 	if runtime.GOOS == "darwin" {
@@ -0,0 +1,351 @@
+// Package agentservices supervises the long-running services declared by the
+// template, such as a dev server or a database.
+//
+// Services are started once the startup scripts finished. Every status
+// transition is reported to coderd so that it shows up in the workspace
+// health and timeline, and services are restarted according to their restart
+// policy when their process exits.
+package agentservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	// minBackoff is how long the first automatic restart of a service waits.
+	minBackoff = time.Second
+	// maxBackoff caps the wait between automatic restarts of a crash looping
+	// service.
+	maxBackoff = time.Minute
+	// healthyAfter is how long a service must run before its backoff is
+	// reset.
+	healthyAfter = time.Minute
+	// reportTimeout bounds reporting a single status transition to coderd.
+	reportTimeout = 10 * time.Second
+)
+
+// ReportFunc reports a status transition of a service to coderd.
+type ReportFunc func(ctx context.Context, name string, req agentsdk.PostServiceStatusRequest) error
+
+// API supervises services and exposes restart controls through the agent.
+type API struct {
+	logger    slog.Logger
+	execer    agentexec.Execer
+	clock     quartz.Clock
+	updateEnv func(current []string) (updated []string, err error)
+	report    ReportFunc
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	services map[string]*service
+}
+
+type service struct {
+	config  codersdk.WorkspaceAgentService
+	dir     string
+	restart chan struct{}
+
+	// Guarded by API.mu.
+	status       codersdk.WorkspaceAgentServiceStatus
+	message      string
+	exitCode     *int32
+	restartCount int32
+	updatedAt    *time.Time
+}
+
+// NewAPI creates a new services API handler. Status transitions are passed to
+// report, which may be nil.
+func NewAPI(logger slog.Logger, execer agentexec.Execer, clock quartz.Clock, updateEnv func(current []string) (updated []string, err error), report ReportFunc) *API {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &API{
+		logger:    logger,
+		execer:    execer,
+		clock:     clock,
+		updateEnv: updateEnv,
+		report:    report,
+		ctx:       ctx,
+		cancel:    cancel,
+		services:  make(map[string]*service),
+	}
+}
+
+// Start starts supervising the given services in dir. Services that are
+// already supervised are left untouched, so that a reconnect to coderd does
+// not restart them.
+func (api *API) Start(services []codersdk.WorkspaceAgentService, dir string) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if api.ctx.Err() != nil {
+		return
+	}
+	for _, config := range services {
+		if _, ok := api.services[config.Name]; ok {
+			continue
+		}
+		svc := &service{
+			config:  config,
+			dir:     dir,
+			restart: make(chan struct{}, 1),
+			status:  codersdk.WorkspaceAgentServiceStatusPending,
+		}
+		api.services[config.Name] = svc
+		api.wg.Add(1)
+		go func() {
+			defer api.wg.Done()
+			api.supervise(svc)
+		}()
+	}
+}
+
+// Close stops all services and waits for them to exit.
+func (api *API) Close() error {
+	api.cancel()
+	api.wg.Wait()
+	return nil
+}
+
+// Routes returns the HTTP handler for service-related routes.
+func (api *API) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", api.handleList)
+	r.Post("/{name}/restart", api.handleRestart)
+	return r
+}
+
+// handleList returns the current status of every service.
+func (api *API) handleList(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	api.mu.Lock()
+	services := make([]codersdk.WorkspaceAgentService, 0, len(api.services))
+	for _, svc := range api.services {
+		services = append(services, svc.snapshotLocked())
+	}
+	api.mu.Unlock()
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	httpapi.Write(ctx, rw, http.StatusOK, services)
+}
+
+// handleRestart restarts a service in the background, regardless of its
+// restart policy and status.
+func (api *API) handleRestart(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	api.mu.Lock()
+	svc, ok := api.services[name]
+	api.mu.Unlock()
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Service %q not found.", name),
+		})
+		return
+	}
+
+	// A restart that is already pending covers this request as well.
+	select {
+	case svc.restart <- struct{}{}:
+	default:
+	}
+	httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.Response{
+		Message: fmt.Sprintf("Restarting service %q.", name),
+	})
+}
+
+// supervise runs the process of a service until the API is closed.
+func (api *API) supervise(svc *service) {
+	logger := api.logger.With(slog.F("service", svc.config.Name))
+	backoff := minBackoff
+	for {
+		api.setStatus(svc, codersdk.WorkspaceAgentServiceStatusStarting, "", nil)
+		startedAt := api.clock.Now()
+		exitCode, err := api.run(svc)
+		if api.ctx.Err() != nil {
+			api.setStatus(svc, codersdk.WorkspaceAgentServiceStatusStopped, "The agent is shutting down.", exitCode)
+			return
+		}
+
+		var requested bool
+		select {
+		case <-svc.restart:
+			requested = true
+		default:
+		}
+		if requested {
+			logger.Info(api.ctx, "restarting service on request")
+			api.restarting(svc, "Restart requested.", exitCode)
+			backoff = minBackoff
+			continue
+		}
+
+		failed := err != nil
+		message := "The service exited."
+		if failed {
+			message = err.Error()
+		}
+		policy := svc.config.RestartPolicy
+		if policy == codersdk.WorkspaceAgentServiceRestartPolicyAlways ||
+			(policy == codersdk.WorkspaceAgentServiceRestartPolicyOnFailure && failed) {
+			if api.clock.Since(startedAt) >= healthyAfter {
+				backoff = minBackoff
+			}
+			logger.Info(api.ctx, "service exited, restarting", slog.F("backoff", backoff), slog.Error(err))
+			api.restarting(svc, message, exitCode)
+			if !api.wait(svc, backoff) {
+				return
+			}
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+
+		status := codersdk.WorkspaceAgentServiceStatusStopped
+		if failed {
+			status = codersdk.WorkspaceAgentServiceStatusFailed
+		}
+		logger.Info(api.ctx, "service exited", slog.F("status", status), slog.Error(err))
+		api.setStatus(svc, status, message, exitCode)
+
+		// Stay down until a restart is requested.
+		select {
+		case <-api.ctx.Done():
+			return
+		case <-svc.restart:
+			api.restarting(svc, "Restart requested.", exitCode)
+			backoff = minBackoff
+		}
+	}
+}
+
+// run starts the process of a service and waits for it to exit, or for a
+// restart to be requested. The restart request is put back for the caller.
+func (api *API) run(svc *service) (*int32, error) {
+	ctx, cancel := context.WithCancel(api.ctx)
+	defer cancel()
+
+	cmd := api.execer.CommandContext(ctx, "sh", "-c", svc.config.Command)
+	cmd.Dir = svc.dir
+	cmd.Env = os.Environ()
+	if api.updateEnv != nil {
+		env, err := api.updateEnv(cmd.Env)
+		if err != nil {
+			return nil, xerrors.Errorf("update env: %w", err)
+		}
+		cmd.Env = env
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, xerrors.Errorf("start: %w", err)
+	}
+	api.setStatus(svc, codersdk.WorkspaceAgentServiceStatusRunning, "", nil)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-svc.restart:
+		cancel()
+		err = <-done
+		select {
+		case svc.restart <- struct{}{}:
+		default:
+		}
+	}
+
+	if cmd.ProcessState == nil {
+		return nil, err
+	}
+	exitCode := int32(cmd.ProcessState.ExitCode()) //nolint:gosec // Exit codes fit in an int32.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = xerrors.Errorf("exited with code %d", exitCode)
+	}
+	return &exitCode, err
+}
+
+// wait waits for the backoff of a service to elapse, or for a restart to be
+// requested. It returns false if the API was closed.
+func (api *API) wait(svc *service, d time.Duration) bool {
+	timer := api.clock.NewTimer(d, "agentservices", "backoff")
+	defer timer.Stop()
+	select {
+	case <-api.ctx.Done():
+		return false
+	case <-timer.C:
+	case <-svc.restart:
+	}
+	return true
+}
+
+func (api *API) restarting(svc *service, message string, exitCode *int32) {
+	api.mu.Lock()
+	svc.restartCount++
+	api.mu.Unlock()
+	api.setStatus(svc, codersdk.WorkspaceAgentServiceStatusRestarting, message, exitCode)
+}
+
+// setStatus records a status transition of a service and reports it to
+// coderd.
+func (api *API) setStatus(svc *service, status codersdk.WorkspaceAgentServiceStatus, message string, exitCode *int32) {
+	now := api.clock.Now()
+
+	api.mu.Lock()
+	svc.status = status
+	svc.message = message
+	svc.exitCode = exitCode
+	svc.updatedAt = &now
+	restartCount := svc.restartCount
+	api.mu.Unlock()
+
+	if api.report == nil {
+		return
+	}
+	// Report transitions during shutdown as well.
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+	err := api.report(ctx, svc.config.Name, agentsdk.PostServiceStatusRequest{
+		Status:       status,
+		Message:      message,
+		ExitCode:     exitCode,
+		RestartCount: restartCount,
+	})
+	if err != nil {
+		api.logger.Warn(ctx, "report service status", slog.F("service", svc.config.Name), slog.F("status", status), slog.Error(err))
+	}
+}
+
+func (svc *service) snapshotLocked() codersdk.WorkspaceAgentService {
+	snapshot := svc.config
+	snapshot.Status = svc.status
+	snapshot.Message = svc.message
+	snapshot.ExitCode = svc.exitCode
+	snapshot.RestartCount = svc.restartCount
+	snapshot.UpdatedAt = svc.updatedAt
+	return snapshot
+}
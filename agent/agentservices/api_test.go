@@ -0,0 +1,141 @@
+package agentservices_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentservices"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestAPI(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("services are run with sh")
+	}
+
+	// reporter returns a report func and a func awaiting the next reported
+	// transition.
+	reporter := func(t *testing.T) (agentservices.ReportFunc, func(ctx context.Context) agentsdk.PostServiceStatusRequest) {
+		transitions := make(chan agentsdk.PostServiceStatusRequest, 64)
+		return func(_ context.Context, _ string, req agentsdk.PostServiceStatusRequest) error {
+				transitions <- req
+				return nil
+			}, func(ctx context.Context) agentsdk.PostServiceStatusRequest {
+				t.Helper()
+				return testutil.RequireReceive(ctx, t, transitions)
+			}
+	}
+	service := func(command string, policy codersdk.WorkspaceAgentServiceRestartPolicy) []codersdk.WorkspaceAgentService {
+		return []codersdk.WorkspaceAgentService{{
+			Name:          "web",
+			DisplayName:   "Web",
+			Command:       command,
+			RestartPolicy: policy,
+		}}
+	}
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		report, next := reporter(t)
+		api := agentservices.NewAPI(testutil.Logger(t), agentexec.DefaultExecer, quartz.NewMock(t), nil, report)
+		t.Cleanup(func() { _ = api.Close() })
+
+		api.Start(service("exit 3", codersdk.WorkspaceAgentServiceRestartPolicyNever), t.TempDir())
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusStarting, next(ctx).Status)
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusRunning, next(ctx).Status)
+		failed := next(ctx)
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusFailed, failed.Status)
+		require.NotNil(t, failed.ExitCode)
+		require.EqualValues(t, 3, *failed.ExitCode)
+	})
+
+	t.Run("RestartOnFailure", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		report, next := reporter(t)
+		clock := quartz.NewMock(t)
+		trap := clock.Trap().NewTimer("agentservices", "backoff")
+		defer trap.Close()
+		api := agentservices.NewAPI(testutil.Logger(t), agentexec.DefaultExecer, clock, nil, report)
+		t.Cleanup(func() { _ = api.Close() })
+
+		api.Start(service("exit 1", codersdk.WorkspaceAgentServiceRestartPolicyOnFailure), t.TempDir())
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusStarting, next(ctx).Status)
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusRunning, next(ctx).Status)
+		restarting := next(ctx)
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusRestarting, restarting.Status)
+		require.EqualValues(t, 1, restarting.RestartCount)
+
+		// The first restart waits for the minimum backoff.
+		trap.MustWait(ctx).MustRelease(ctx)
+		clock.Advance(time.Second).MustWait(ctx)
+		starting := next(ctx)
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusStarting, starting.Status)
+		require.EqualValues(t, 1, starting.RestartCount)
+	})
+
+	t.Run("RestartRequested", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		report, next := reporter(t)
+		api := agentservices.NewAPI(testutil.Logger(t), agentexec.DefaultExecer, quartz.NewMock(t), nil, report)
+		t.Cleanup(func() { _ = api.Close() })
+		handler := api.Routes()
+
+		api.Start(service("sleep 300", codersdk.WorkspaceAgentServiceRestartPolicyNever), t.TempDir())
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusStarting, next(ctx).Status)
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusRunning, next(ctx).Status)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "/web/restart", nil)
+		handler.ServeHTTP(w, r)
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusRestarting, next(ctx).Status)
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusStarting, next(ctx).Status)
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusRunning, next(ctx).Status)
+
+		w = httptest.NewRecorder()
+		r = httptest.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+		handler.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var services []codersdk.WorkspaceAgentService
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&services))
+		require.Len(t, services, 1)
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusRunning, services[0].Status)
+		require.EqualValues(t, 1, services[0].RestartCount)
+
+		// Closing the API stops the service.
+		require.NoError(t, api.Close())
+		require.Equal(t, codersdk.WorkspaceAgentServiceStatusStopped, next(ctx).Status)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		api := agentservices.NewAPI(testutil.Logger(t), agentexec.DefaultExecer, quartz.NewMock(t), nil, nil)
+		t.Cleanup(func() { _ = api.Close() })
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "/web/restart", nil)
+		api.Routes().ServeHTTP(w, r)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
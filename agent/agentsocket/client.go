@@ -166,6 +166,12 @@ func (c *Client) UpdateAppStatus(ctx context.Context, req *agentproto.UpdateAppS
 	return c.client.UpdateAppStatus(ctx, req)
 }
 
+// RequestWorkspaceTransition forwards a request to stop or restart the
+// workspace to coderd via the agent.
+func (c *Client) RequestWorkspaceTransition(ctx context.Context, req *agentproto.RequestWorkspaceTransitionRequest) (*agentproto.RequestWorkspaceTransitionResponse, error) {
+	return c.client.RequestWorkspaceTransition(ctx, req)
+}
+
 // ContextSources lists the workspace-context sources registered on the agent.
 func (c *Client) ContextSources(ctx context.Context) ([]ContextSource, error) {
 	resp, err := c.client.ContextSources(ctx, &proto.ContextSourcesRequest{})
@@ -1718,7 +1718,7 @@ var file_agent_agentsocket_proto_agentsocket_proto_rawDesc = []byte{
 	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x63,
 	0x6f, 0x64, 0x65, 0x72, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74,
 	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73,
-	0x68, 0x6f, 0x74, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x32, 0xa6, 0x0b,
+	0x68, 0x6f, 0x74, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x32, 0xac, 0x0c,
 	0x0a, 0x0b, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x4d, 0x0a,
 	0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x21, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x61, 0x67,
 	0x65, 0x6e, 0x74, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e,
@@ -1809,11 +1809,19 @@ var file_agent_agentsocket_proto_agentsocket_proto_rawDesc = []byte{
 	0x78, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x63, 0x6f, 0x64, 0x65,
 	0x72, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x76, 0x31,
 	0x2e, 0x52, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x64, 0x65, 0x72,
-	0x2f, 0x76, 0x32, 0x2f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x73,
-	0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x83, 0x01, 0x0a, 0x1a, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x57, 0x6f,
+	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x72,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x33, 0x5a, 0x31,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f, 0x64, 0x65, 0x72,
+	0x2f, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2f, 0x76, 0x32, 0x2f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1830,39 +1838,41 @@ func file_agent_agentsocket_proto_agentsocket_proto_rawDescGZIP() []byte {
 
 var file_agent_agentsocket_proto_agentsocket_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_agent_agentsocket_proto_agentsocket_proto_goTypes = []interface{}{
-	(*PingRequest)(nil),                   // 0: coder.agentsocket.v1.PingRequest
-	(*PingResponse)(nil),                  // 1: coder.agentsocket.v1.PingResponse
-	(*SyncStartRequest)(nil),              // 2: coder.agentsocket.v1.SyncStartRequest
-	(*SyncStartResponse)(nil),             // 3: coder.agentsocket.v1.SyncStartResponse
-	(*SyncWantRequest)(nil),               // 4: coder.agentsocket.v1.SyncWantRequest
-	(*SyncWantResponse)(nil),              // 5: coder.agentsocket.v1.SyncWantResponse
-	(*SyncCompleteRequest)(nil),           // 6: coder.agentsocket.v1.SyncCompleteRequest
-	(*SyncCompleteResponse)(nil),          // 7: coder.agentsocket.v1.SyncCompleteResponse
-	(*SyncReadyRequest)(nil),              // 8: coder.agentsocket.v1.SyncReadyRequest
-	(*SyncReadyResponse)(nil),             // 9: coder.agentsocket.v1.SyncReadyResponse
-	(*SyncStatusRequest)(nil),             // 10: coder.agentsocket.v1.SyncStatusRequest
-	(*DependencyInfo)(nil),                // 11: coder.agentsocket.v1.DependencyInfo
-	(*SyncStatusResponse)(nil),            // 12: coder.agentsocket.v1.SyncStatusResponse
-	(*SyncListRequest)(nil),               // 13: coder.agentsocket.v1.SyncListRequest
-	(*UnitInfo)(nil),                      // 14: coder.agentsocket.v1.UnitInfo
-	(*SyncListResponse)(nil),              // 15: coder.agentsocket.v1.SyncListResponse
-	(*ContextSource)(nil),                 // 16: coder.agentsocket.v1.ContextSource
-	(*ContextSourcesRequest)(nil),         // 17: coder.agentsocket.v1.ContextSourcesRequest
-	(*ContextSourcesResponse)(nil),        // 18: coder.agentsocket.v1.ContextSourcesResponse
-	(*GetContextSourceRequest)(nil),       // 19: coder.agentsocket.v1.GetContextSourceRequest
-	(*GetContextSourceResponse)(nil),      // 20: coder.agentsocket.v1.GetContextSourceResponse
-	(*AddContextSourceRequest)(nil),       // 21: coder.agentsocket.v1.AddContextSourceRequest
-	(*AddContextSourceResponse)(nil),      // 22: coder.agentsocket.v1.AddContextSourceResponse
-	(*RemoveContextSourceRequest)(nil),    // 23: coder.agentsocket.v1.RemoveContextSourceRequest
-	(*RemoveContextSourceResponse)(nil),   // 24: coder.agentsocket.v1.RemoveContextSourceResponse
-	(*ContextResource)(nil),               // 25: coder.agentsocket.v1.ContextResource
-	(*ContextSnapshot)(nil),               // 26: coder.agentsocket.v1.ContextSnapshot
-	(*ContextSnapshotRequest)(nil),        // 27: coder.agentsocket.v1.ContextSnapshotRequest
-	(*ContextSnapshotResponse)(nil),       // 28: coder.agentsocket.v1.ContextSnapshotResponse
-	(*ResyncContextRequest)(nil),          // 29: coder.agentsocket.v1.ResyncContextRequest
-	(*ResyncContextResponse)(nil),         // 30: coder.agentsocket.v1.ResyncContextResponse
-	(*proto.UpdateAppStatusRequest)(nil),  // 31: coder.agent.v2.UpdateAppStatusRequest
-	(*proto.UpdateAppStatusResponse)(nil), // 32: coder.agent.v2.UpdateAppStatusResponse
+	(*PingRequest)(nil),                              // 0: coder.agentsocket.v1.PingRequest
+	(*PingResponse)(nil),                             // 1: coder.agentsocket.v1.PingResponse
+	(*SyncStartRequest)(nil),                         // 2: coder.agentsocket.v1.SyncStartRequest
+	(*SyncStartResponse)(nil),                        // 3: coder.agentsocket.v1.SyncStartResponse
+	(*SyncWantRequest)(nil),                          // 4: coder.agentsocket.v1.SyncWantRequest
+	(*SyncWantResponse)(nil),                         // 5: coder.agentsocket.v1.SyncWantResponse
+	(*SyncCompleteRequest)(nil),                      // 6: coder.agentsocket.v1.SyncCompleteRequest
+	(*SyncCompleteResponse)(nil),                     // 7: coder.agentsocket.v1.SyncCompleteResponse
+	(*SyncReadyRequest)(nil),                         // 8: coder.agentsocket.v1.SyncReadyRequest
+	(*SyncReadyResponse)(nil),                        // 9: coder.agentsocket.v1.SyncReadyResponse
+	(*SyncStatusRequest)(nil),                        // 10: coder.agentsocket.v1.SyncStatusRequest
+	(*DependencyInfo)(nil),                           // 11: coder.agentsocket.v1.DependencyInfo
+	(*SyncStatusResponse)(nil),                       // 12: coder.agentsocket.v1.SyncStatusResponse
+	(*SyncListRequest)(nil),                          // 13: coder.agentsocket.v1.SyncListRequest
+	(*UnitInfo)(nil),                                 // 14: coder.agentsocket.v1.UnitInfo
+	(*SyncListResponse)(nil),                         // 15: coder.agentsocket.v1.SyncListResponse
+	(*ContextSource)(nil),                            // 16: coder.agentsocket.v1.ContextSource
+	(*ContextSourcesRequest)(nil),                    // 17: coder.agentsocket.v1.ContextSourcesRequest
+	(*ContextSourcesResponse)(nil),                   // 18: coder.agentsocket.v1.ContextSourcesResponse
+	(*GetContextSourceRequest)(nil),                  // 19: coder.agentsocket.v1.GetContextSourceRequest
+	(*GetContextSourceResponse)(nil),                 // 20: coder.agentsocket.v1.GetContextSourceResponse
+	(*AddContextSourceRequest)(nil),                  // 21: coder.agentsocket.v1.AddContextSourceRequest
+	(*AddContextSourceResponse)(nil),                 // 22: coder.agentsocket.v1.AddContextSourceResponse
+	(*RemoveContextSourceRequest)(nil),               // 23: coder.agentsocket.v1.RemoveContextSourceRequest
+	(*RemoveContextSourceResponse)(nil),              // 24: coder.agentsocket.v1.RemoveContextSourceResponse
+	(*ContextResource)(nil),                          // 25: coder.agentsocket.v1.ContextResource
+	(*ContextSnapshot)(nil),                          // 26: coder.agentsocket.v1.ContextSnapshot
+	(*ContextSnapshotRequest)(nil),                   // 27: coder.agentsocket.v1.ContextSnapshotRequest
+	(*ContextSnapshotResponse)(nil),                  // 28: coder.agentsocket.v1.ContextSnapshotResponse
+	(*ResyncContextRequest)(nil),                     // 29: coder.agentsocket.v1.ResyncContextRequest
+	(*ResyncContextResponse)(nil),                    // 30: coder.agentsocket.v1.ResyncContextResponse
+	(*proto.UpdateAppStatusRequest)(nil),             // 31: coder.agent.v2.UpdateAppStatusRequest
+	(*proto.RequestWorkspaceTransitionRequest)(nil),  // 32: coder.agent.v2.RequestWorkspaceTransitionRequest
+	(*proto.UpdateAppStatusResponse)(nil),            // 33: coder.agent.v2.UpdateAppStatusResponse
+	(*proto.RequestWorkspaceTransitionResponse)(nil), // 34: coder.agent.v2.RequestWorkspaceTransitionResponse
 }
 var file_agent_agentsocket_proto_agentsocket_proto_depIdxs = []int32{
 	11, // 0: coder.agentsocket.v1.SyncStatusResponse.dependencies:type_name -> coder.agentsocket.v1.DependencyInfo
@@ -1887,22 +1897,24 @@ var file_agent_agentsocket_proto_agentsocket_proto_depIdxs = []int32{
 	23, // 19: coder.agentsocket.v1.AgentSocket.RemoveContextSource:input_type -> coder.agentsocket.v1.RemoveContextSourceRequest
 	27, // 20: coder.agentsocket.v1.AgentSocket.GetContextSnapshot:input_type -> coder.agentsocket.v1.ContextSnapshotRequest
 	29, // 21: coder.agentsocket.v1.AgentSocket.ResyncContext:input_type -> coder.agentsocket.v1.ResyncContextRequest
-	1,  // 22: coder.agentsocket.v1.AgentSocket.Ping:output_type -> coder.agentsocket.v1.PingResponse
-	3,  // 23: coder.agentsocket.v1.AgentSocket.SyncStart:output_type -> coder.agentsocket.v1.SyncStartResponse
-	5,  // 24: coder.agentsocket.v1.AgentSocket.SyncWant:output_type -> coder.agentsocket.v1.SyncWantResponse
-	7,  // 25: coder.agentsocket.v1.AgentSocket.SyncComplete:output_type -> coder.agentsocket.v1.SyncCompleteResponse
-	9,  // 26: coder.agentsocket.v1.AgentSocket.SyncReady:output_type -> coder.agentsocket.v1.SyncReadyResponse
-	12, // 27: coder.agentsocket.v1.AgentSocket.SyncStatus:output_type -> coder.agentsocket.v1.SyncStatusResponse
-	15, // 28: coder.agentsocket.v1.AgentSocket.SyncList:output_type -> coder.agentsocket.v1.SyncListResponse
-	32, // 29: coder.agentsocket.v1.AgentSocket.UpdateAppStatus:output_type -> coder.agent.v2.UpdateAppStatusResponse
-	18, // 30: coder.agentsocket.v1.AgentSocket.ContextSources:output_type -> coder.agentsocket.v1.ContextSourcesResponse
-	20, // 31: coder.agentsocket.v1.AgentSocket.GetContextSource:output_type -> coder.agentsocket.v1.GetContextSourceResponse
-	22, // 32: coder.agentsocket.v1.AgentSocket.AddContextSource:output_type -> coder.agentsocket.v1.AddContextSourceResponse
-	24, // 33: coder.agentsocket.v1.AgentSocket.RemoveContextSource:output_type -> coder.agentsocket.v1.RemoveContextSourceResponse
-	28, // 34: coder.agentsocket.v1.AgentSocket.GetContextSnapshot:output_type -> coder.agentsocket.v1.ContextSnapshotResponse
-	30, // 35: coder.agentsocket.v1.AgentSocket.ResyncContext:output_type -> coder.agentsocket.v1.ResyncContextResponse
-	22, // [22:36] is the sub-list for method output_type
-	8,  // [8:22] is the sub-list for method input_type
+	32, // 22: coder.agentsocket.v1.AgentSocket.RequestWorkspaceTransition:input_type -> coder.agent.v2.RequestWorkspaceTransitionRequest
+	1,  // 23: coder.agentsocket.v1.AgentSocket.Ping:output_type -> coder.agentsocket.v1.PingResponse
+	3,  // 24: coder.agentsocket.v1.AgentSocket.SyncStart:output_type -> coder.agentsocket.v1.SyncStartResponse
+	5,  // 25: coder.agentsocket.v1.AgentSocket.SyncWant:output_type -> coder.agentsocket.v1.SyncWantResponse
+	7,  // 26: coder.agentsocket.v1.AgentSocket.SyncComplete:output_type -> coder.agentsocket.v1.SyncCompleteResponse
+	9,  // 27: coder.agentsocket.v1.AgentSocket.SyncReady:output_type -> coder.agentsocket.v1.SyncReadyResponse
+	12, // 28: coder.agentsocket.v1.AgentSocket.SyncStatus:output_type -> coder.agentsocket.v1.SyncStatusResponse
+	15, // 29: coder.agentsocket.v1.AgentSocket.SyncList:output_type -> coder.agentsocket.v1.SyncListResponse
+	33, // 30: coder.agentsocket.v1.AgentSocket.UpdateAppStatus:output_type -> coder.agent.v2.UpdateAppStatusResponse
+	18, // 31: coder.agentsocket.v1.AgentSocket.ContextSources:output_type -> coder.agentsocket.v1.ContextSourcesResponse
+	20, // 32: coder.agentsocket.v1.AgentSocket.GetContextSource:output_type -> coder.agentsocket.v1.GetContextSourceResponse
+	22, // 33: coder.agentsocket.v1.AgentSocket.AddContextSource:output_type -> coder.agentsocket.v1.AddContextSourceResponse
+	24, // 34: coder.agentsocket.v1.AgentSocket.RemoveContextSource:output_type -> coder.agentsocket.v1.RemoveContextSourceResponse
+	28, // 35: coder.agentsocket.v1.AgentSocket.GetContextSnapshot:output_type -> coder.agentsocket.v1.ContextSnapshotResponse
+	30, // 36: coder.agentsocket.v1.AgentSocket.ResyncContext:output_type -> coder.agentsocket.v1.ResyncContextResponse
+	34, // 37: coder.agentsocket.v1.AgentSocket.RequestWorkspaceTransition:output_type -> coder.agent.v2.RequestWorkspaceTransitionResponse
+	23, // [23:38] is the sub-list for method output_type
+	8,  // [8:23] is the sub-list for method input_type
 	8,  // [8:8] is the sub-list for extension type_name
 	8,  // [8:8] is the sub-list for extension extendee
 	0,  // [0:8] is the sub-list for field type_name
@@ -53,6 +53,7 @@ type DRPCAgentSocketClient interface {
 	RemoveContextSource(ctx context.Context, in *RemoveContextSourceRequest) (*RemoveContextSourceResponse, error)
 	GetContextSnapshot(ctx context.Context, in *ContextSnapshotRequest) (*ContextSnapshotResponse, error)
 	ResyncContext(ctx context.Context, in *ResyncContextRequest) (*ResyncContextResponse, error)
+	RequestWorkspaceTransition(ctx context.Context, in *proto1.RequestWorkspaceTransitionRequest) (*proto1.RequestWorkspaceTransitionResponse, error)
 }
 
 type drpcAgentSocketClient struct {
@@ -191,6 +192,15 @@ func (c *drpcAgentSocketClient) ResyncContext(ctx context.Context, in *ResyncCon
 	return out, nil
 }
 
+func (c *drpcAgentSocketClient) RequestWorkspaceTransition(ctx context.Context, in *proto1.RequestWorkspaceTransitionRequest) (*proto1.RequestWorkspaceTransitionResponse, error) {
+	out := new(proto1.RequestWorkspaceTransitionResponse)
+	err := c.cc.Invoke(ctx, "/coder.agentsocket.v1.AgentSocket/RequestWorkspaceTransition", drpcEncoding_File_agent_agentsocket_proto_agentsocket_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type DRPCAgentSocketServer interface {
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
 	SyncStart(context.Context, *SyncStartRequest) (*SyncStartResponse, error)
@@ -206,6 +216,7 @@ type DRPCAgentSocketServer interface {
 	RemoveContextSource(context.Context, *RemoveContextSourceRequest) (*RemoveContextSourceResponse, error)
 	GetContextSnapshot(context.Context, *ContextSnapshotRequest) (*ContextSnapshotResponse, error)
 	ResyncContext(context.Context, *ResyncContextRequest) (*ResyncContextResponse, error)
+	RequestWorkspaceTransition(context.Context, *proto1.RequestWorkspaceTransitionRequest) (*proto1.RequestWorkspaceTransitionResponse, error)
 }
 
 type DRPCAgentSocketUnimplementedServer struct{}
@@ -266,9 +277,13 @@ func (s *DRPCAgentSocketUnimplementedServer) ResyncContext(context.Context, *Res
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCAgentSocketUnimplementedServer) RequestWorkspaceTransition(context.Context, *proto1.RequestWorkspaceTransitionRequest) (*proto1.RequestWorkspaceTransitionResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 type DRPCAgentSocketDescription struct{}
 
-func (DRPCAgentSocketDescription) NumMethods() int { return 14 }
+func (DRPCAgentSocketDescription) NumMethods() int { return 15 }
 
 func (DRPCAgentSocketDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -398,6 +413,15 @@ func (DRPCAgentSocketDescription) Method(n int) (string, drpc.Encoding, drpc.Rec
 						in1.(*ResyncContextRequest),
 					)
 			}, DRPCAgentSocketServer.ResyncContext, true
+	case 14:
+		return "/coder.agentsocket.v1.AgentSocket/RequestWorkspaceTransition", drpcEncoding_File_agent_agentsocket_proto_agentsocket_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCAgentSocketServer).
+					RequestWorkspaceTransition(
+						ctx,
+						in1.(*proto1.RequestWorkspaceTransitionRequest),
+					)
+			}, DRPCAgentSocketServer.RequestWorkspaceTransition, true
 	default:
 		return "", nil, nil, nil, false
 	}
@@ -630,3 +654,19 @@ func (x *drpcAgentSocket_ResyncContextStream) SendAndClose(m *ResyncContextRespo
 	}
 	return x.CloseSend()
 }
+
+type DRPCAgentSocket_RequestWorkspaceTransitionStream interface {
+	drpc.Stream
+	SendAndClose(*proto1.RequestWorkspaceTransitionResponse) error
+}
+
+type drpcAgentSocket_RequestWorkspaceTransitionStream struct {
+	drpc.Stream
+}
+
+func (x *drpcAgentSocket_RequestWorkspaceTransitionStream) SendAndClose(m *proto1.RequestWorkspaceTransitionResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_agent_agentsocket_proto_agentsocket_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
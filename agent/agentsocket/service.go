@@ -43,12 +43,12 @@ type DRPCAgentSocketService struct {
 	logger         slog.Logger
 
 	mu       sync.Mutex
-	agentAPI agentproto.DRPCAgentClient28
+	agentAPI agentproto.DRPCAgentClient211
 }
 
 // SetAgentAPI sets the agent API client used to forward requests
 // to coderd. This is called when the agent connects to coderd.
-func (s *DRPCAgentSocketService) SetAgentAPI(api agentproto.DRPCAgentClient28) {
+func (s *DRPCAgentSocketService) SetAgentAPI(api agentproto.DRPCAgentClient211) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.agentAPI = api
@@ -228,6 +228,20 @@ func (s *DRPCAgentSocketService) UpdateAppStatus(ctx context.Context, req *agent
 	return api.UpdateAppStatus(ctx, req)
 }
 
+// RequestWorkspaceTransition forwards a request to stop or restart the
+// workspace to coderd via the agent API. Returns an error if the agent is not
+// connected.
+func (s *DRPCAgentSocketService) RequestWorkspaceTransition(ctx context.Context, req *agentproto.RequestWorkspaceTransitionRequest) (*agentproto.RequestWorkspaceTransitionResponse, error) {
+	s.mu.Lock()
+	api := s.agentAPI
+	s.mu.Unlock()
+
+	if api == nil {
+		return nil, ErrAgentAPINotConnected
+	}
+	return api.RequestWorkspaceTransition(ctx, req)
+}
+
 // ContextSources lists the workspace-context sources registered on the agent.
 func (s *DRPCAgentSocketService) ContextSources(_ context.Context, _ *proto.ContextSourcesRequest) (*proto.ContextSourcesResponse, error) {
 	if s.contextManager == nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
@@ -14,17 +15,23 @@ import (
 	"github.com/coder/coder/v2/testutil"
 )
 
-// fakeAgentAPI implements just the UpdateAppStatus method of
-// DRPCAgentClient28 for testing. Calling any other method will panic.
+// fakeAgentAPI implements just the UpdateAppStatus and
+// RequestWorkspaceTransition methods of DRPCAgentClient211 for testing.
+// Calling any other method will panic.
 type fakeAgentAPI struct {
-	agentproto.DRPCAgentClient28
-	updateAppStatus func(context.Context, *agentproto.UpdateAppStatusRequest) (*agentproto.UpdateAppStatusResponse, error)
+	agentproto.DRPCAgentClient211
+	updateAppStatus            func(context.Context, *agentproto.UpdateAppStatusRequest) (*agentproto.UpdateAppStatusResponse, error)
+	requestWorkspaceTransition func(context.Context, *agentproto.RequestWorkspaceTransitionRequest) (*agentproto.RequestWorkspaceTransitionResponse, error)
 }
 
 func (m *fakeAgentAPI) UpdateAppStatus(ctx context.Context, req *agentproto.UpdateAppStatusRequest) (*agentproto.UpdateAppStatusResponse, error) {
 	return m.updateAppStatus(ctx, req)
 }
 
+func (m *fakeAgentAPI) RequestWorkspaceTransition(ctx context.Context, req *agentproto.RequestWorkspaceTransitionRequest) (*agentproto.RequestWorkspaceTransitionResponse, error) {
+	return m.requestWorkspaceTransition(ctx, req)
+}
+
 // newSocketClient creates a DRPC client connected to the Unix socket at the given path.
 func newSocketClient(ctx context.Context, t *testing.T, socketPath string) *agentsocket.Client {
 	t.Helper()
@@ -488,4 +495,62 @@ func TestDRPCAgentSocketService(t *testing.T) {
 			require.ErrorContains(t, err, "not connected")
 		})
 	})
+
+	t.Run("RequestWorkspaceTransition", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("NotConnected", func(t *testing.T) {
+			t.Parallel()
+
+			socketPath := testutil.AgentSocketPath(t)
+			ctx := testutil.Context(t, testutil.WaitShort)
+			server, err := agentsocket.NewServer(
+				slog.Make().Leveled(slog.LevelDebug),
+				agentsocket.WithPath(socketPath),
+			)
+			require.NoError(t, err)
+			defer server.Close()
+
+			client := newSocketClient(ctx, t, socketPath)
+
+			_, err = client.RequestWorkspaceTransition(ctx, &agentproto.RequestWorkspaceTransitionRequest{
+				Transition: agentproto.RequestWorkspaceTransitionRequest_STOP,
+			})
+			require.ErrorContains(t, err, "not connected")
+		})
+
+		t.Run("ForwardsToAgentAPI", func(t *testing.T) {
+			t.Parallel()
+
+			socketPath := testutil.AgentSocketPath(t)
+			ctx := testutil.Context(t, testutil.WaitShort)
+			server, err := agentsocket.NewServer(
+				slog.Make().Leveled(slog.LevelDebug),
+				agentsocket.WithPath(socketPath),
+			)
+			require.NoError(t, err)
+			defer server.Close()
+
+			buildID := uuid.New()
+			var gotReq *agentproto.RequestWorkspaceTransitionRequest
+			mock := &fakeAgentAPI{
+				requestWorkspaceTransition: func(_ context.Context, req *agentproto.RequestWorkspaceTransitionRequest) (*agentproto.RequestWorkspaceTransitionResponse, error) {
+					gotReq = req
+					return &agentproto.RequestWorkspaceTransitionResponse{WorkspaceBuildId: buildID[:]}, nil
+				},
+			}
+			server.SetAgentAPI(mock)
+
+			client := newSocketClient(ctx, t, socketPath)
+
+			resp, err := client.RequestWorkspaceTransition(ctx, &agentproto.RequestWorkspaceTransitionRequest{
+				Transition: agentproto.RequestWorkspaceTransitionRequest_RESTART,
+			})
+			require.NoError(t, err)
+			require.Equal(t, buildID[:], resp.WorkspaceBuildId)
+
+			require.NotNil(t, gotReq)
+			require.Equal(t, agentproto.RequestWorkspaceTransitionRequest_RESTART, gotReq.Transition)
+		})
+	})
 }
@@ -2,6 +2,7 @@ package agentssh
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -1364,6 +1365,31 @@ func (s *Server) UpdateHostSigner(seed int64) error {
 	return nil
 }
 
+// UpdateHostCertificate presents cert alongside the host key it certifies, so
+// that clients trusting the deployment's SSH host CA can verify the agent.
+// A previous certificate for the same key type is replaced.
+func (s *Server) UpdateHostCertificate(cert *gossh.Certificate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	certKey := cert.Key.Marshal()
+	for _, signer := range s.srv.HostSigners {
+		if _, ok := signer.PublicKey().(*gossh.Certificate); ok {
+			continue
+		}
+		if !bytes.Equal(signer.PublicKey().Marshal(), certKey) {
+			continue
+		}
+		certSigner, err := gossh.NewCertSigner(cert, signer)
+		if err != nil {
+			return xerrors.Errorf("create certificate signer: %w", err)
+		}
+		s.srv.AddHostKey(certSigner)
+		return nil
+	}
+	return xerrors.New("no host key matches the certificate")
+}
+
 // CoderSigner generates a deterministic SSH signer based on the provided seed.
 // It uses RSA with a key size of 2048 bits.
 func CoderSigner(seed int64) (gossh.Signer, error) {
@@ -182,6 +182,18 @@ func (c *Client) ConnectRPC210WithRole(ctx context.Context, _ string) (
 	return c.ConnectRPC210(ctx)
 }
 
+func (c *Client) ConnectRPC211(ctx context.Context) (
+	agentproto.DRPCAgentClient211, proto.DRPCTailnetClient28, error,
+) {
+	return c.ConnectRPC210(ctx)
+}
+
+func (c *Client) ConnectRPC211WithRole(ctx context.Context, _ string) (
+	agentproto.DRPCAgentClient211, proto.DRPCTailnetClient28, error,
+) {
+	return c.ConnectRPC211(ctx)
+}
+
 func (c *Client) ConnectRPC29(ctx context.Context) (
 	agentproto.DRPCAgentClient29, proto.DRPCTailnetClient28, error,
 ) {
@@ -324,6 +336,10 @@ func (f *FakeAgentAPI) ContextStatePushes() []*agentproto.PushContextStateReques
 	return out
 }
 
+func (*FakeAgentAPI) RequestWorkspaceTransition(context.Context, *agentproto.RequestWorkspaceTransitionRequest) (*agentproto.RequestWorkspaceTransitionResponse, error) {
+	panic("unimplemented")
+}
+
 func (f *FakeAgentAPI) GetManifest(context.Context, *agentproto.GetManifestRequest) (*agentproto.Manifest, error) {
 	return f.manifest, nil
 }
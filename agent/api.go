@@ -32,6 +32,8 @@ func (a *agent) apiHandler() http.Handler {
 	r.Mount("/api/v0", a.filesAPI.Routes())
 	r.Mount("/api/v0/git", a.gitAPI.Routes())
 	r.Mount("/api/v0/processes", a.processAPI.Routes())
+	r.Mount("/api/v0/quiesce", a.quiesceAPI.Routes())
+	r.Mount("/api/v0/services", a.servicesAPI.Routes())
 	r.Mount("/api/v0/desktop", a.desktopAPI.Routes())
 	r.Mount("/api/v0/mcp", a.mcpAPI.Routes())
 	r.Mount("/api/v0/context-config", a.contextConfigAPI.Routes())
@@ -60,7 +62,10 @@ func (a *agent) apiHandler() http.Handler {
 	promHandler := PrometheusMetricsHandler(a.prometheusRegistry, a.logger)
 
 	r.Get("/api/v0/listening-ports", a.listeningPortsHandler.handler)
+	r.Post("/api/v0/env/refresh", a.HandleRefreshEnv)
 	r.Get("/api/v0/netcheck", a.HandleNetcheck)
+	r.Post("/api/v0/netcheck", a.HandleRunNetcheck)
+	r.Post("/api/v0/support-bundle", a.HandleSupportBundle)
 	r.Get("/debug/logs", a.HandleHTTPDebugLogs)
 	r.Get("/debug/magicsock", a.HandleHTTPDebugMagicsock)
 	r.Get("/debug/magicsock/debug-logging/{state}", a.HandleHTTPMagicsockDebugLoggingState)
@@ -84,7 +89,7 @@ type listeningPortsHandler struct {
 // handler returns a list of listening ports. This is tested by coderd's
 // TestWorkspaceAgentListeningPorts test.
 func (lp *listeningPortsHandler) handler(rw http.ResponseWriter, r *http.Request) {
-	ports, err := lp.getter.GetListeningPorts()
+	ports, err := lp.listeningPorts()
 	if err != nil {
 		httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Could not scan for listening ports.",
@@ -93,6 +98,19 @@ func (lp *listeningPortsHandler) handler(rw http.ResponseWriter, r *http.Request
 		return
 	}
 
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentListeningPortsResponse{
+		Ports: ports,
+	})
+}
+
+// listeningPorts returns the listening ports, excluding low and ignored
+// ports.
+func (lp *listeningPortsHandler) listeningPorts() ([]codersdk.WorkspaceAgentListeningPort, error) {
+	ports, err := lp.getter.GetListeningPorts()
+	if err != nil {
+		return nil, err
+	}
+
 	filteredPorts := make([]codersdk.WorkspaceAgentListeningPort, 0, len(ports))
 	for _, port := range ports {
 		if port.Port < workspacesdk.AgentMinimumListeningPort {
@@ -105,8 +123,5 @@ func (lp *listeningPortsHandler) handler(rw http.ResponseWriter, r *http.Request
 		}
 		filteredPorts = append(filteredPorts, port)
 	}
-
-	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentListeningPortsResponse{
-		Ports: filteredPorts,
-	})
+	return filteredPorts, nil
 }
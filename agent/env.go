@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"golang.org/x/xerrors"
+	googleproto "google.golang.org/protobuf/proto"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// refreshEnvLoop serves requests sent on envRefreshes for as long as the
+// connection to coderd is up.
+func (a *agent) refreshEnvLoop(ctx context.Context, aAPI proto.DRPCAgentClient28) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case errCh := <-a.envRefreshes:
+			errCh <- a.refreshEnv(ctx, aAPI)
+		}
+	}
+}
+
+// refreshEnv re-fetches the manifest and replaces the environment variables,
+// secrets and read-only mode applied to new sessions. Everything else in the
+// manifest is left untouched, since it is only applied once on startup.
+func (a *agent) refreshEnv(ctx context.Context, aAPI proto.DRPCAgentClient28) error {
+	mpRaw, err := aAPI.GetManifest(ctx, &proto.GetManifestRequest{})
+	if err != nil {
+		return xerrors.Errorf("fetch manifest: %w", err)
+	}
+
+	// Strip secrets from the proto manifest immediately to avoid accidental leakage.
+	secrets := agentsdk.SecretsFromProto(mpRaw.Secrets)
+	mpRaw.Secrets = nil
+	mp, ok := googleproto.Clone(mpRaw).(*proto.Manifest)
+	if !ok {
+		return xerrors.Errorf("clone manifest: type mismatch")
+	}
+	fetched, err := agentsdk.ManifestFromProto(mp)
+	if err != nil {
+		return xerrors.Errorf("convert manifest: %w", err)
+	}
+
+	current := a.manifest.Load()
+	if current == nil {
+		return xerrors.New("no manifest")
+	}
+	manifest := *current
+	manifest.EnvironmentVariables = fetched.EnvironmentVariables
+	manifest.ReadOnly = fetched.ReadOnly
+	a.manifest.Store(&manifest)
+	a.secrets.Store(&secrets)
+
+	homeDir, err := a.envInfo.HomeDir()
+	if err != nil {
+		a.logger.Warn(ctx, "failed to resolve home directory for secret files", slog.Error(err))
+	}
+	writeSecretFiles(ctx, a.logger, a.filesystem, homeDir, secrets)
+
+	a.logger.Info(ctx, "refreshed environment variables and secrets",
+		slog.F("env_count", len(manifest.EnvironmentVariables)),
+		slog.F("secret_count", len(secrets)),
+	)
+	return nil
+}
+
+// HandleRefreshEnv re-resolves the environment variables and secrets of the
+// agent from coderd. Only sessions started after the refresh see the new
+// values. The response lists the names of the exported variables, never
+// their values.
+func (a *agent) HandleRefreshEnv(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	errCh := make(chan error, 1)
+	select {
+	case <-ctx.Done():
+		httpapi.Write(ctx, rw, http.StatusServiceUnavailable, codersdk.Response{
+			Message: "Agent is not connected to coderd.",
+		})
+		return
+	case a.envRefreshes <- errCh:
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case err = <-errCh:
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to refresh environment.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var names []string
+	if manifest := a.manifest.Load(); manifest != nil {
+		for k := range manifest.EnvironmentVariables {
+			names = append(names, k)
+		}
+	}
+	if secrets := a.secrets.Load(); secrets != nil {
+		for _, secret := range *secrets {
+			if secret.EnvName != "" {
+				names = append(names, secret.EnvName)
+			}
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+	if names == nil {
+		names = []string{}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, workspacesdk.RefreshEnvResponse{
+		EnvironmentVariables: names,
+	})
+}
@@ -3,6 +3,9 @@ package agent
 import (
 	"net/http"
 
+	"tailscale.com/net/netcheck"
+
+	"github.com/coder/coder/v2/coderd/healthcheck/derphealth"
 	"github.com/coder/coder/v2/coderd/healthcheck/health"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
@@ -29,3 +32,69 @@ func (a *agent) HandleNetcheck(rw http.ResponseWriter, r *http.Request) {
 		Interfaces: ifReport,
 	})
 }
+
+// HandleRunNetcheck runs a network diagnostic from the agent. Unlike
+// HandleNetcheck, which returns the last known network info, it probes every
+// DERP region and STUN server, so it may take several seconds.
+func (a *agent) HandleRunNetcheck(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	network := a.TailnetConn()
+	derpMap := network.DERPMap()
+	if derpMap == nil {
+		httpapi.Write(ctx, rw, http.StatusServiceUnavailable, codersdk.Response{
+			Message: "Agent has not received a DERP map yet",
+		})
+		return
+	}
+
+	ifReport, err := healthsdk.RunInterfacesReport()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to run interfaces report",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var derpReport derphealth.Report
+	derpReport.Run(ctx, &derphealth.ReportOptions{
+		DERPMap: derpMap,
+	})
+
+	report := healthsdk.AgentNetworkDiagnosticsReport{
+		BaseReport: healthsdk.BaseReport{
+			Severity: health.SeverityOK,
+			Warnings: []health.Message{},
+		},
+		DERP:             healthsdk.DERPHealthReport(derpReport),
+		Interfaces:       ifReport,
+		DirectConnection: directConnectionReport(derpReport.Netcheck, network.GetBlockEndpoints()),
+	}
+	for _, sub := range []healthsdk.BaseReport{report.DERP.BaseReport, report.Interfaces.BaseReport} {
+		if sub.Severity.Value() > report.Severity.Value() {
+			report.Severity = sub.Severity
+		}
+		report.Warnings = append(report.Warnings, sub.Warnings...)
+	}
+	// The server compares the time to its own clock when it receives the
+	// report, so it's taken last.
+	report.Time = a.clock.Now()
+
+	httpapi.Write(ctx, rw, http.StatusOK, report)
+}
+
+func directConnectionReport(nc *netcheck.Report, blockEndpoints bool) healthsdk.DirectConnectionReport {
+	report := healthsdk.DirectConnectionReport{
+		Disabled: blockEndpoints,
+	}
+	if nc != nil {
+		report.UDP = nc.UDP
+		report.HardNAT = nc.MappingVariesByDestIP.EqualBool(true)
+		report.PortMapping = nc.UPnP.EqualBool(true) || nc.PMP.EqualBool(true) || nc.PCP.EqualBool(true)
+		report.PreferredDERP = nc.PreferredDERP
+	}
+	// A hard NAT makes direct connections unlikely, unless a port mapping
+	// can be created on the router.
+	report.Feasible = !report.Disabled && report.UDP && (!report.HardNAT || report.PortMapping)
+	return report
+}
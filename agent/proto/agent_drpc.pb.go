@@ -58,6 +58,7 @@ type DRPCAgentClient interface {
 	ReportBoundaryLogs(ctx context.Context, in *ReportBoundaryLogsRequest) (*ReportBoundaryLogsResponse, error)
 	UpdateAppStatus(ctx context.Context, in *UpdateAppStatusRequest) (*UpdateAppStatusResponse, error)
 	PushContextState(ctx context.Context, in *PushContextStateRequest) (*PushContextStateResponse, error)
+	RequestWorkspaceTransition(ctx context.Context, in *RequestWorkspaceTransitionRequest) (*RequestWorkspaceTransitionResponse, error)
 }
 
 type drpcAgentClient struct {
@@ -241,6 +242,15 @@ func (c *drpcAgentClient) PushContextState(ctx context.Context, in *PushContextS
 	return out, nil
 }
 
+func (c *drpcAgentClient) RequestWorkspaceTransition(ctx context.Context, in *RequestWorkspaceTransitionRequest) (*RequestWorkspaceTransitionResponse, error) {
+	out := new(RequestWorkspaceTransitionResponse)
+	err := c.cc.Invoke(ctx, "/coder.agent.v2.Agent/RequestWorkspaceTransition", drpcEncoding_File_agent_proto_agent_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type DRPCAgentServer interface {
 	GetManifest(context.Context, *GetManifestRequest) (*Manifest, error)
 	GetServiceBanner(context.Context, *GetServiceBannerRequest) (*ServiceBanner, error)
@@ -261,6 +271,7 @@ type DRPCAgentServer interface {
 	ReportBoundaryLogs(context.Context, *ReportBoundaryLogsRequest) (*ReportBoundaryLogsResponse, error)
 	UpdateAppStatus(context.Context, *UpdateAppStatusRequest) (*UpdateAppStatusResponse, error)
 	PushContextState(context.Context, *PushContextStateRequest) (*PushContextStateResponse, error)
+	RequestWorkspaceTransition(context.Context, *RequestWorkspaceTransitionRequest) (*RequestWorkspaceTransitionResponse, error)
 }
 
 type DRPCAgentUnimplementedServer struct{}
@@ -341,9 +352,13 @@ func (s *DRPCAgentUnimplementedServer) PushContextState(context.Context, *PushCo
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCAgentUnimplementedServer) RequestWorkspaceTransition(context.Context, *RequestWorkspaceTransitionRequest) (*RequestWorkspaceTransitionResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 type DRPCAgentDescription struct{}
 
-func (DRPCAgentDescription) NumMethods() int { return 19 }
+func (DRPCAgentDescription) NumMethods() int { return 20 }
 
 func (DRPCAgentDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -518,6 +533,15 @@ func (DRPCAgentDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 						in1.(*PushContextStateRequest),
 					)
 			}, DRPCAgentServer.PushContextState, true
+	case 19:
+		return "/coder.agent.v2.Agent/RequestWorkspaceTransition", drpcEncoding_File_agent_proto_agent_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCAgentServer).
+					RequestWorkspaceTransition(
+						ctx,
+						in1.(*RequestWorkspaceTransitionRequest),
+					)
+			}, DRPCAgentServer.RequestWorkspaceTransition, true
 	default:
 		return "", nil, nil, nil, false
 	}
@@ -830,3 +854,19 @@ func (x *drpcAgent_PushContextStateStream) SendAndClose(m *PushContextStateRespo
 	}
 	return x.CloseSend()
 }
+
+type DRPCAgent_RequestWorkspaceTransitionStream interface {
+	drpc.Stream
+	SendAndClose(*RequestWorkspaceTransitionResponse) error
+}
+
+type drpcAgent_RequestWorkspaceTransitionStream struct {
+	drpc.Stream
+}
+
+func (x *drpcAgent_RequestWorkspaceTransitionStream) SendAndClose(m *RequestWorkspaceTransitionResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_agent_proto_agent_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
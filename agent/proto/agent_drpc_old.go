@@ -99,3 +99,11 @@ type DRPCAgentClient210 interface {
 	DRPCAgentClient29
 	PushContextState(ctx context.Context, in *PushContextStateRequest) (*PushContextStateResponse, error)
 }
+
+// DRPCAgentClient211 is the Agent API at v2.11. It adds the
+// RequestWorkspaceTransition RPC, and agents dialing it enforce the allowed
+// ports that coderd sets on the nodes of clients.
+type DRPCAgentClient211 interface {
+	DRPCAgentClient210
+	RequestWorkspaceTransition(ctx context.Context, in *RequestWorkspaceTransitionRequest) (*RequestWorkspaceTransitionResponse, error)
+}
@@ -1,6 +1,7 @@
 package resourcesmonitor
 
 import (
+	"github.com/spf13/afero"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/clistat"
@@ -16,11 +17,16 @@ type Statter interface {
 type Fetcher interface {
 	FetchMemory() (total int64, used int64, err error)
 	FetchVolume(volume string) (total int64, used int64, err error)
+	// FetchMemoryEvents returns the cumulative OOM kill counter and the
+	// current memory pressure of the agent's cgroup. It returns
+	// ErrMemoryEventsUnavailable if the platform does not expose them.
+	FetchMemoryEvents() (oomKills int64, pressure float64, err error)
 }
 
 type fetcher struct {
 	Statter
 	isContainerized bool
+	fs              afero.Fs
 }
 
 //nolint:revive
@@ -30,7 +36,11 @@ func NewFetcher(f Statter) (*fetcher, error) {
 		return nil, xerrors.Errorf("check is containerized: %w", err)
 	}
 
-	return &fetcher{f, isContainerized}, nil
+	return &fetcher{
+		Statter:         f,
+		isContainerized: isContainerized,
+		fs:              afero.NewOsFs(),
+	}, nil
 }
 
 func (f *fetcher) FetchMemory() (total int64, used int64, err error) {
@@ -79,3 +89,12 @@ func (f *fetcher) FetchVolume(volume string) (total int64, used int64, err error
 
 	return int64(*vol.Total), int64(vol.Used), nil
 }
+
+func (f *fetcher) FetchMemoryEvents() (oomKills int64, pressure float64, err error) {
+	events, err := ReadMemoryEvents(f.fs)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return events.OOMKills, events.Pressure, nil
+}
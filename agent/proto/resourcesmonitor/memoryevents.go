@@ -0,0 +1,134 @@
+package resourcesmonitor
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+)
+
+// ErrMemoryEventsUnavailable is returned by ReadMemoryEvents when the
+// agent is not running in a cgroup that exposes OOM kill counters, for
+// example on macOS, Windows or a host without a memory controller.
+var ErrMemoryEventsUnavailable = xerrors.New("memory events unavailable")
+
+const (
+	// cgroupV2MemoryEvents holds the "oom_kill" counter on cgroup v2.
+	cgroupV2MemoryEvents = "/sys/fs/cgroup/memory.events"
+	// cgroupV1OOMControl holds the "oom_kill" counter on cgroup v1.
+	cgroupV1OOMControl = "/sys/fs/cgroup/memory/memory.oom_control"
+	// cgroupV2MemoryPressure holds the PSI memory stall averages of the
+	// current cgroup.
+	cgroupV2MemoryPressure = "/sys/fs/cgroup/memory.pressure"
+	// procMemoryPressure holds the system-wide PSI memory stall averages.
+	procMemoryPressure = "/proc/pressure/memory"
+)
+
+// MemoryEvents is a snapshot of the memory event counters of the cgroup
+// the agent runs in.
+type MemoryEvents struct {
+	// OOMKills is the total number of processes killed by the OOM killer
+	// since the cgroup was created.
+	OOMKills int64
+	// Pressure is the "full avg10" PSI value: the percentage of time in
+	// the last 10 seconds during which all non-idle tasks were stalled on
+	// memory. It is zero when PSI is not enabled.
+	Pressure float64
+}
+
+// ReadMemoryEvents reads the OOM kill counter and memory pressure of the
+// current cgroup. Both cgroup v2 and v1 are supported.
+func ReadMemoryEvents(fs afero.Fs) (MemoryEvents, error) {
+	var events MemoryEvents
+
+	found := false
+	for _, path := range []string{cgroupV2MemoryEvents, cgroupV1OOMControl} {
+		data, err := afero.ReadFile(fs, path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return MemoryEvents{}, xerrors.Errorf("read %s: %w", path, err)
+		}
+
+		kills, ok, err := parseOOMKills(data)
+		if err != nil {
+			return MemoryEvents{}, xerrors.Errorf("parse %s: %w", path, err)
+		}
+		if !ok {
+			continue
+		}
+		events.OOMKills = kills
+		found = true
+		break
+	}
+	if !found {
+		return MemoryEvents{}, ErrMemoryEventsUnavailable
+	}
+
+	// Pressure stall information is optional: kernels built without
+	// CONFIG_PSI or booted with psi=0 do not expose it.
+	for _, path := range []string{cgroupV2MemoryPressure, procMemoryPressure} {
+		data, err := afero.ReadFile(fs, path)
+		if err != nil {
+			continue
+		}
+
+		pressure, ok, err := parseFullAvg10(data)
+		if err != nil {
+			return MemoryEvents{}, xerrors.Errorf("parse %s: %w", path, err)
+		}
+		if ok {
+			events.Pressure = pressure
+			break
+		}
+	}
+
+	return events, nil
+}
+
+// parseOOMKills extracts the "oom_kill" counter from a flat keyed file
+// such as memory.events or memory.oom_control.
+func parseOOMKills(data []byte) (int64, bool, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok || key != "oom_kill" {
+			continue
+		}
+		kills, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return 0, false, xerrors.Errorf("parse oom_kill %q: %w", value, err)
+		}
+		return kills, true, nil
+	}
+	return 0, false, scanner.Err()
+}
+
+// parseFullAvg10 extracts the avg10 value from the "full" line of a PSI
+// file, e.g. "full avg10=1.50 avg60=0.30 avg300=0.06 total=123".
+func parseFullAvg10(data []byte) (float64, bool, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "full" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			value, ok := strings.CutPrefix(field, "avg10=")
+			if !ok {
+				continue
+			}
+			avg, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0, false, xerrors.Errorf("parse avg10 %q: %w", value, err)
+			}
+			return avg, true, nil
+		}
+	}
+	return 0, false, scanner.Err()
+}
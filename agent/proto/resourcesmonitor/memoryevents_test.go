@@ -0,0 +1,75 @@
+package resourcesmonitor_test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/proto/resourcesmonitor"
+)
+
+func TestReadMemoryEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CgroupV2", func(t *testing.T) {
+		t.Parallel()
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/memory.events", []byte(
+			"low 0\nhigh 0\nmax 12\noom 3\noom_kill 2\noom_group_kill 0\n",
+		), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/memory.pressure", []byte(
+			"some avg10=20.00 avg60=5.00 avg300=1.00 total=1000\nfull avg10=15.50 avg60=4.00 avg300=0.80 total=800\n",
+		), 0o644))
+
+		events, err := resourcesmonitor.ReadMemoryEvents(fs)
+		require.NoError(t, err)
+		require.Equal(t, resourcesmonitor.MemoryEvents{OOMKills: 2, Pressure: 15.5}, events)
+	})
+
+	t.Run("CgroupV1", func(t *testing.T) {
+		t.Parallel()
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/memory/memory.oom_control", []byte(
+			"oom_kill_disable 0\nunder_oom 0\noom_kill 4\n",
+		), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "/proc/pressure/memory", []byte(
+			"some avg10=1.00 avg60=0.00 avg300=0.00 total=10\nfull avg10=0.50 avg60=0.00 avg300=0.00 total=5\n",
+		), 0o644))
+
+		events, err := resourcesmonitor.ReadMemoryEvents(fs)
+		require.NoError(t, err)
+		require.Equal(t, resourcesmonitor.MemoryEvents{OOMKills: 4, Pressure: 0.5}, events)
+	})
+
+	t.Run("NoPressure", func(t *testing.T) {
+		t.Parallel()
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/memory.events", []byte("oom_kill 1\n"), 0o644))
+
+		events, err := resourcesmonitor.ReadMemoryEvents(fs)
+		require.NoError(t, err)
+		require.Equal(t, resourcesmonitor.MemoryEvents{OOMKills: 1}, events)
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := resourcesmonitor.ReadMemoryEvents(afero.NewMemMapFs())
+		require.ErrorIs(t, err, resourcesmonitor.ErrMemoryEventsUnavailable)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		t.Parallel()
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/memory.events", []byte("oom_kill lots\n"), 0o644))
+
+		_, err := resourcesmonitor.ReadMemoryEvents(fs)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, resourcesmonitor.ErrMemoryEventsUnavailable)
+	})
+}
@@ -12,6 +12,9 @@ type Datapoint struct {
 	CollectedAt time.Time
 	Memory      *MemoryDatapoint
 	Volumes     []*VolumeDatapoint
+	// OOMKills is the number of OOM kills since the previous datapoint.
+	OOMKills       int64
+	MemoryPressure float64
 }
 
 type MemoryDatapoint struct {
@@ -61,7 +64,9 @@ func (q *Queue) ItemsAsProto() []*proto.PushResourcesMonitoringUsageRequest_Data
 
 	for _, item := range q.items {
 		protoItem := &proto.PushResourcesMonitoringUsageRequest_Datapoint{
-			CollectedAt: timestamppb.New(item.CollectedAt),
+			CollectedAt:    timestamppb.New(item.CollectedAt),
+			OomKills:       item.OOMKills,
+			MemoryPressure: item.MemoryPressure,
 		}
 		if item.Memory != nil {
 			protoItem.Memory = &proto.PushResourcesMonitoringUsageRequest_Datapoint_MemoryUsage{
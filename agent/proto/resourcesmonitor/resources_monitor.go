@@ -2,6 +2,7 @@ package resourcesmonitor
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"cdr.dev/slog/v3"
@@ -16,6 +17,15 @@ type monitor struct {
 	resourcesFetcher Fetcher
 	datapointsPusher datapointsPusher
 	queue            *Queue
+
+	// lastOOMKills is the cumulative OOM kill counter seen on the
+	// previous tick, or -1 before the first successful read. Only the
+	// difference is reported so kills that happened before the agent
+	// started are not attributed to this session.
+	lastOOMKills int64
+	// memoryEventsUnavailable is set once the platform turned out not to
+	// expose memory events, so we stop trying on every tick.
+	memoryEventsUnavailable bool
 }
 
 //nolint:revive
@@ -27,6 +37,7 @@ func NewResourcesMonitor(logger slog.Logger, clock quartz.Clock, config *proto.G
 		resourcesFetcher: resourcesFetcher,
 		datapointsPusher: datapointsPusher,
 		queue:            NewQueue(int(config.Config.NumDatapoints)),
+		lastOOMKills:     -1,
 	}
 }
 
@@ -71,6 +82,8 @@ func (m *monitor) Start(ctx context.Context) error {
 			})
 		}
 
+		m.collectMemoryEvents(ctx, &datapoint)
+
 		m.queue.Push(datapoint)
 
 		if m.queue.IsFull() {
@@ -91,3 +104,28 @@ func (m *monitor) Start(ctx context.Context) error {
 
 	return nil
 }
+
+func (m *monitor) collectMemoryEvents(ctx context.Context, datapoint *Datapoint) {
+	if m.memoryEventsUnavailable {
+		return
+	}
+
+	oomKills, pressure, err := m.resourcesFetcher.FetchMemoryEvents()
+	if errors.Is(err, ErrMemoryEventsUnavailable) {
+		m.logger.Debug(ctx, "memory events are not available on this platform")
+		m.memoryEventsUnavailable = true
+		return
+	}
+	if err != nil {
+		m.logger.Error(ctx, "failed to fetch memory events", slog.Error(err))
+		return
+	}
+
+	// The counter only goes backwards if the cgroup was recreated, in
+	// which case we start over from the new value.
+	if m.lastOOMKills >= 0 && oomKills > m.lastOOMKills {
+		datapoint.OOMKills = oomKills - m.lastOOMKills
+	}
+	m.lastOOMKills = oomKills
+	datapoint.MemoryPressure = pressure
+}
@@ -29,8 +29,12 @@ type fetcher struct {
 	totalVolume int64
 	usedVolume  int64
 
-	errMemory error
-	errVolume error
+	oomKills       int64
+	memoryPressure float64
+
+	errMemory       error
+	errVolume       error
+	errMemoryEvents error
 }
 
 func (r *fetcher) FetchMemory() (total int64, used int64, err error) {
@@ -41,6 +45,10 @@ func (r *fetcher) FetchVolume(_ string) (total int64, used int64, err error) {
 	return r.totalVolume, r.usedVolume, r.errVolume
 }
 
+func (r *fetcher) FetchMemoryEvents() (oomKills int64, pressure float64, err error) {
+	return r.oomKills, r.memoryPressure, r.errMemoryEvents
+}
+
 func TestPushResourcesMonitoringWithConfig(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -232,3 +240,96 @@ func TestPushResourcesMonitoringWithConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestPushResourcesMonitoringMemoryEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ReportsOOMKillDeltas", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var (
+			logger = slog.Make(sloghuman.Sink(os.Stdout))
+			clk    = quartz.NewMock(t)
+			config = &proto.GetResourcesMonitoringConfigurationResponse{
+				Config: &proto.GetResourcesMonitoringConfigurationResponse_Config{
+					NumDatapoints:             3,
+					CollectionIntervalSeconds: 1,
+				},
+			}
+			// Kills that happened before the agent started must not be
+			// reported.
+			f    = &fetcher{oomKills: 5, memoryPressure: 12.5}
+			reqs []*proto.PushResourcesMonitoringUsageRequest
+		)
+
+		pusher := &datapointsPusherMock{
+			PushResourcesMonitoringUsageFunc: func(_ context.Context, req *proto.PushResourcesMonitoringUsageRequest) (*proto.PushResourcesMonitoringUsageResponse, error) {
+				reqs = append(reqs, req)
+				return &proto.PushResourcesMonitoringUsageResponse{}, nil
+			},
+		}
+
+		monitor := resourcesmonitor.NewResourcesMonitor(logger, clk, config, f, pusher)
+		require.NoError(t, monitor.Start(ctx))
+
+		for _, kills := range []int64{5, 7, 7} {
+			f.oomKills = kills
+			_, waiter := clk.AdvanceNext()
+			require.NoError(t, waiter.Wait(ctx))
+		}
+
+		require.Len(t, reqs, 1)
+		require.Len(t, reqs[0].Datapoints, 3)
+		require.EqualValues(t, 0, reqs[0].Datapoints[0].OomKills)
+		require.EqualValues(t, 2, reqs[0].Datapoints[1].OomKills)
+		require.EqualValues(t, 0, reqs[0].Datapoints[2].OomKills)
+		for _, datapoint := range reqs[0].Datapoints {
+			require.Equal(t, 12.5, datapoint.MemoryPressure)
+		}
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var (
+			logger = slog.Make(sloghuman.Sink(os.Stdout))
+			clk    = quartz.NewMock(t)
+			config = &proto.GetResourcesMonitoringConfigurationResponse{
+				Config: &proto.GetResourcesMonitoringConfigurationResponse_Config{
+					NumDatapoints:             2,
+					CollectionIntervalSeconds: 1,
+				},
+			}
+			f    = &fetcher{errMemoryEvents: resourcesmonitor.ErrMemoryEventsUnavailable}
+			reqs []*proto.PushResourcesMonitoringUsageRequest
+		)
+
+		pusher := &datapointsPusherMock{
+			PushResourcesMonitoringUsageFunc: func(_ context.Context, req *proto.PushResourcesMonitoringUsageRequest) (*proto.PushResourcesMonitoringUsageResponse, error) {
+				reqs = append(reqs, req)
+				return &proto.PushResourcesMonitoringUsageResponse{}, nil
+			},
+		}
+
+		monitor := resourcesmonitor.NewResourcesMonitor(logger, clk, config, f, pusher)
+		require.NoError(t, monitor.Start(ctx))
+
+		for i := 0; i < 2; i++ {
+			_, waiter := clk.AdvanceNext()
+			require.NoError(t, waiter.Wait(ctx))
+		}
+
+		// Datapoints are still pushed without memory events.
+		require.Len(t, reqs, 1)
+		for _, datapoint := range reqs[0].Datapoints {
+			require.Zero(t, datapoint.OomKills)
+			require.Zero(t, datapoint.MemoryPressure)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+)
+
+// readOnlyPrePushHook rejects every push from a repository. It is installed
+// as the only git hook of sessions started in a workspace in read-only mode.
+const readOnlyPrePushHook = `#!/bin/sh
+echo "This workspace is in read-only mode, pushing is disabled." >&2
+exit 1
+`
+
+// readOnlyGitEnv configures git in sessions of a workspace in read-only mode
+// to run hooks from a directory whose pre-push hook rejects every push. The
+// configuration is passed in the environment, so it takes precedence over the
+// git configuration files of the workspace.
+func (a *agent) readOnlyGitEnv(envs map[string]string) error {
+	hooksDir := filepath.Join(a.tempDir, "coder-read-only", "hooks")
+	if err := a.filesystem.MkdirAll(hooksDir, 0o755); err != nil {
+		return xerrors.Errorf("create hooks directory: %w", err)
+	}
+	//nolint:gosec // The hook must be executable.
+	if err := afero.WriteFile(a.filesystem, filepath.Join(hooksDir, "pre-push"), []byte(readOnlyPrePushHook), 0o755); err != nil {
+		return xerrors.Errorf("write pre-push hook: %w", err)
+	}
+	// Git on Windows resolves with UNIX-style paths.
+	setGitConfigEnv(envs, "core.hooksPath", filepath.ToSlash(hooksDir))
+	return nil
+}
+
+// setGitConfigEnv adds a git configuration entry to envs with the
+// GIT_CONFIG_COUNT mechanism, keeping the entries that are already set.
+func setGitConfigEnv(envs map[string]string, key, value string) {
+	count, err := strconv.Atoi(envs["GIT_CONFIG_COUNT"])
+	if err != nil || count < 0 {
+		count = 0
+	}
+	envs[fmt.Sprintf("GIT_CONFIG_KEY_%d", count)] = key
+	envs[fmt.Sprintf("GIT_CONFIG_VALUE_%d", count)] = value
+	envs["GIT_CONFIG_COUNT"] = strconv.Itoa(count + 1)
+}
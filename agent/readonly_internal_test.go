@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyGitEnv(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	a := &agent{
+		filesystem: fs,
+		tempDir:    "/tmp",
+	}
+
+	// Git configuration set by the template is kept.
+	envs := map[string]string{
+		"GIT_CONFIG_COUNT":   "1",
+		"GIT_CONFIG_KEY_0":   "user.name",
+		"GIT_CONFIG_VALUE_0": "coder",
+	}
+	err := a.readOnlyGitEnv(envs)
+	require.NoError(t, err)
+
+	hooksDir := filepath.ToSlash(filepath.Join("/tmp", "coder-read-only", "hooks"))
+	require.Equal(t, map[string]string{
+		"GIT_CONFIG_COUNT":   "2",
+		"GIT_CONFIG_KEY_0":   "user.name",
+		"GIT_CONFIG_VALUE_0": "coder",
+		"GIT_CONFIG_KEY_1":   "core.hooksPath",
+		"GIT_CONFIG_VALUE_1": hooksDir,
+	}, envs)
+
+	hook, err := afero.ReadFile(fs, filepath.Join("/tmp", "coder-read-only", "hooks", "pre-push"))
+	require.NoError(t, err)
+	require.Equal(t, readOnlyPrePushHook, string(hook))
+
+	// A malformed count is replaced rather than appended to.
+	envs = map[string]string{"GIT_CONFIG_COUNT": "invalid"}
+	setGitConfigEnv(envs, "core.hooksPath", hooksDir)
+	require.Equal(t, map[string]string{
+		"GIT_CONFIG_COUNT":   "1",
+		"GIT_CONFIG_KEY_0":   "core.hooksPath",
+		"GIT_CONFIG_VALUE_0": hooksDir,
+	}, envs)
+}
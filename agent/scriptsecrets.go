@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// scriptSecretsClient is implemented by clients that can fetch the template
+// secrets referenced by a script from coderd.
+type scriptSecretsClient interface {
+	ScriptSecrets(ctx context.Context, req agentsdk.ScriptSecretsRequest) (agentsdk.ScriptSecretsResponse, error)
+}
+
+// getScriptSecrets returns the template secrets referenced by a script, keyed
+// by environment variable name.
+func (a *agent) getScriptSecrets(ctx context.Context, scriptID uuid.UUID) (map[string]string, error) {
+	client, ok := a.client.(scriptSecretsClient)
+	if !ok {
+		return nil, nil
+	}
+	resp, err := client.ScriptSecrets(ctx, agentsdk.ScriptSecretsRequest{ScriptID: scriptID})
+	if err != nil {
+		// Older versions of coderd don't serve script secrets, and scripts
+		// that coderd doesn't know about have none.
+		var sdkErr *codersdk.Error
+		if errors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.Env, nil
+}
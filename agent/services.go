@@ -0,0 +1,34 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// serviceStatusClient is implemented by clients that can report the status of
+// services supervised by the agent to coderd.
+type serviceStatusClient interface {
+	PostServiceStatus(ctx context.Context, name string, req agentsdk.PostServiceStatusRequest) error
+}
+
+// reportServiceStatus reports a status transition of a service to coderd.
+func (a *agent) reportServiceStatus(ctx context.Context, name string, req agentsdk.PostServiceStatusRequest) error {
+	client, ok := a.client.(serviceStatusClient)
+	if !ok {
+		return nil
+	}
+	err := client.PostServiceStatus(ctx, name, req)
+	if err != nil {
+		// Older versions of coderd don't accept service statuses.
+		var sdkErr *codersdk.Error
+		if errors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// sshHostCertificateRetryInterval is how long the agent waits before asking
+// for a host certificate again after a failure. It is also the floor for the
+// renewal interval.
+const sshHostCertificateRetryInterval = 5 * time.Minute
+
+// sshHostCertificateClient is implemented by clients that can have coderd
+// sign the agent's SSH host key.
+type sshHostCertificateClient interface {
+	PostSSHHostCertificate(ctx context.Context, req agentsdk.PostSSHHostCertificateRequest) (agentsdk.SSHHostCertificateResponse, error)
+}
+
+// renewSSHHostCertificate obtains a host certificate for the agent's SSH host
+// key and renews it at half its lifetime until ctx is canceled.
+func (a *agent) renewSSHHostCertificate(ctx context.Context, client sshHostCertificateClient, keySeed int64) {
+	logger := a.logger.Named("ssh-host-certificate")
+
+	signer, err := agentssh.CoderSigner(keySeed)
+	if err != nil {
+		logger.Error(ctx, "create host signer", slog.Error(err))
+		return
+	}
+	req := agentsdk.PostSSHHostCertificateRequest{
+		PublicKey: string(gossh.MarshalAuthorizedKey(signer.PublicKey())),
+	}
+
+	for {
+		next := sshHostCertificateRetryInterval
+		resp, err := client.PostSSHHostCertificate(ctx, req)
+		if err == nil {
+			err = a.applySSHHostCertificate(resp)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			var sdkErr *codersdk.Error
+			if errors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound {
+				// Older deployments don't issue host certificates, and
+				// clients fall back to ignoring the host key.
+				logger.Debug(ctx, "coderd does not issue ssh host certificates")
+				return
+			}
+			logger.Warn(ctx, "failed to obtain ssh host certificate", slog.Error(err))
+		} else {
+			logger.Debug(ctx, "updated ssh host certificate", slog.F("expires_at", resp.ExpiresAt))
+			next = max(resp.ExpiresAt.Sub(a.clock.Now())/2, sshHostCertificateRetryInterval)
+		}
+
+		timer := a.clock.NewTimer(next, "agent", "ssh_host_certificate")
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (a *agent) applySSHHostCertificate(resp agentsdk.SSHHostCertificateResponse) error {
+	parsed, _, _, _, err := gossh.ParseAuthorizedKey([]byte(resp.Certificate))
+	if err != nil {
+		return xerrors.Errorf("parse certificate: %w", err)
+	}
+	cert, ok := parsed.(*gossh.Certificate)
+	if !ok {
+		return xerrors.Errorf("expected certificate, got %s", parsed.Type())
+	}
+	return a.sshServer.UpdateHostCertificate(cert)
+}
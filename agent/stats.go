@@ -7,14 +7,39 @@ import (
 	"time"
 
 	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"tailscale.com/types/netlogtype"
 
 	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
 )
 
 const maxConns = 2048
 
+type connClass int
+
+const (
+	connectionClassPortForward connClass = iota
+	connectionClassSSH
+	connectionClassReconnectingPTY
+)
+
+// connectionClass classifies a tailnet connection by the agent port it
+// targets, so that traffic can be attributed for egress accounting. Either
+// side of the connection may be the agent, depending on packet direction.
+func connectionClass(conn netlogtype.Connection) connClass {
+	for _, port := range []uint16{conn.Src.Port(), conn.Dst.Port()} {
+		switch port {
+		case workspacesdk.AgentSSHPort, workspacesdk.AgentStandardSSHPort:
+			return connectionClassSSH
+		case workspacesdk.AgentReconnectingPTYPort:
+			return connectionClassReconnectingPTY
+		}
+	}
+	return connectionClassPortForward
+}
+
 type networkStatsSource interface {
 	SetConnStatsCallback(maxPeriod time.Duration, maxConns int, dump func(start, end time.Time, virtual, physical map[netlogtype.Connection]netlogtype.Counts))
 }
@@ -86,8 +111,10 @@ func (s *statsReporter) callback(_, _ time.Time, virtual, _ map[netlogtype.Conne
 // this that use it.  There is no retry and we fail on the first error since
 // this will be inside a larger retry loop.
 func (s *statsReporter) reportLoop(ctx context.Context, dest statsDest) error {
-	// send an initial, blank report to get the interval
-	resp, err := dest.UpdateStats(ctx, &proto.UpdateStatsRequest{})
+	// send an initial, blank report to get the interval. It carries the time
+	// of the agent, so the server learns its clock skew as soon as it
+	// connects.
+	resp, err := dest.UpdateStats(ctx, &proto.UpdateStatsRequest{SentAt: timestamppb.Now()})
 	if err != nil {
 		return xerrors.Errorf("initial update: %w", err)
 	}
@@ -133,7 +160,7 @@ func (s *statsReporter) reportLocked(
 	s.L.Unlock()
 	defer s.L.Lock()
 	stats := s.collector.Collect(ctx, networkStats)
-	resp, err := dest.UpdateStats(ctx, &proto.UpdateStatsRequest{Stats: stats})
+	resp, err := dest.UpdateStats(ctx, &proto.UpdateStatsRequest{Stats: stats, SentAt: timestamppb.Now()})
 	if err != nil {
 		return err
 	}
@@ -16,6 +16,30 @@ import (
 	"github.com/coder/coder/v2/testutil"
 )
 
+func TestConnectionClass(t *testing.T) {
+	t.Parallel()
+
+	agentAddr := netip.MustParseAddr("fd7a:115c:a1e0::1")
+	peerAddr := netip.MustParseAddr("fd7a:115c:a1e0::2")
+	conn := func(agentPort uint16) netlogtype.Connection {
+		return netlogtype.Connection{
+			Proto: ipproto.TCP,
+			Src:   netip.AddrPortFrom(agentAddr, agentPort),
+			Dst:   netip.AddrPortFrom(peerAddr, 54321),
+		}
+	}
+
+	require.Equal(t, connectionClassSSH, connectionClass(conn(1)))
+	require.Equal(t, connectionClassSSH, connectionClass(conn(22)))
+	require.Equal(t, connectionClassReconnectingPTY, connectionClass(conn(2)))
+	require.Equal(t, connectionClassPortForward, connectionClass(conn(8080)))
+
+	// Direction does not matter.
+	reversed := conn(22)
+	reversed.Src, reversed.Dst = reversed.Dst, reversed.Src
+	require.Equal(t, connectionClassSSH, connectionClass(reversed))
+}
+
 func TestStatsReporter(t *testing.T) {
 	t.Parallel()
 	ctx := testutil.Context(t, testutil.WaitShort)
@@ -38,6 +62,7 @@ func TestStatsReporter(t *testing.T) {
 	req := testutil.TryReceive(ctx, t, fDest.reqs)
 	require.NotNil(t, req)
 	require.Nil(t, req.Stats)
+	require.NotNil(t, req.SentAt)
 	interval := time.Second * 34
 	testutil.RequireSend(ctx, t, fDest.resps, &proto.UpdateStatsResponse{ReportInterval: durationpb.New(interval)})
 
@@ -101,6 +126,7 @@ func TestStatsReporter(t *testing.T) {
 	update := testutil.TryReceive(ctx, t, fDest.reqs)
 	require.NotNil(t, update)
 	require.Equal(t, stats, update.Stats)
+	require.WithinDuration(t, time.Now(), update.SentAt.AsTime(), testutil.WaitShort)
 	testutil.RequireSend(ctx, t, fDest.resps, &proto.UpdateStatsResponse{ReportInterval: durationpb.New(interval)})
 
 	// second update -- netStat0 and netStats1 are accumulated and reported
@@ -0,0 +1,247 @@
+package agent
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/clistat"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/healthsdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+const (
+	// supportBundleLogMaxBytes is how much of the end of each log file is
+	// included in a support bundle.
+	supportBundleLogMaxBytes = 1024 * 1024
+	// supportBundleLogsMaxBytes caps the log data of a support bundle so it
+	// stays well below the size of files coderd accepts.
+	supportBundleLogsMaxBytes = 6 * 1024 * 1024
+)
+
+// supportBundleEnvVars are the environment variables included in a support
+// bundle. Other variables are left out since they may contain secrets.
+var supportBundleEnvVars = []string{"HOME", "USER", "SHELL", "PATH", "LANG", "TMPDIR", "TERM"}
+
+// HandleSupportBundle collects a diagnostics bundle for troubleshooting the
+// workspace. The response is a zip archive; sections that fail to collect
+// are recorded in its manifest instead of failing the request.
+func (a *agent) HandleSupportBundle(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	manifest := workspacesdk.SupportBundleManifest{
+		CollectedAt: time.Now(),
+		Errors:      map[string]string{},
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	writeJSON := func(name string, v any) {
+		w, err := zw.Create(name)
+		if err == nil {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(v)
+		}
+		if err != nil {
+			manifest.Errors[name] = err.Error()
+		}
+	}
+
+	if logs, err := a.supportBundleLogs(ctx, zw); err != nil {
+		manifest.Errors["logs"] = err.Error()
+	} else {
+		manifest.Logs = logs
+	}
+
+	if report, err := a.supportBundleNetcheck(); err != nil {
+		manifest.Errors["netcheck.json"] = err.Error()
+	} else {
+		writeJSON("netcheck.json", report)
+	}
+
+	if ports, err := a.listeningPortsHandler.listeningPorts(); err != nil {
+		manifest.Errors["listening_ports.json"] = err.Error()
+	} else {
+		writeJSON("listening_ports.json", codersdk.WorkspaceAgentListeningPortsResponse{Ports: ports})
+	}
+
+	writeJSON("environment.json", supportBundleEnvironment())
+
+	if disks, err := supportBundleDiskUsage(); err != nil {
+		manifest.Errors["disk_usage.json"] = err.Error()
+	} else {
+		writeJSON("disk_usage.json", disks)
+	}
+
+	writeJSON("manifest.json", manifest)
+	if err := zw.Close(); err != nil {
+		a.logger.Error(ctx, "close support bundle archive", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create support bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/zip")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(buf.Bytes())
+}
+
+// supportBundleLogs adds the end of the agent and script logs in the log
+// directory to the archive and returns their names.
+func (a *agent) supportBundleLogs(ctx context.Context, zw *zip.Writer) ([]string, error) {
+	// Confine reads to logDir so a symlink there cannot escape it.
+	root, err := os.OpenRoot(a.logDir)
+	if err != nil {
+		return nil, xerrors.Errorf("open log dir: %w", err)
+	}
+	defer root.Close()
+
+	entries, err := fs.ReadDir(root.FS(), ".")
+	if err != nil {
+		return nil, xerrors.Errorf("read log dir: %w", err)
+	}
+
+	var (
+		names []string
+		total int64
+	)
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || !strings.HasPrefix(name, "coder-") || filepath.Ext(name) != ".log" {
+			continue
+		}
+		if total >= supportBundleLogsMaxBytes {
+			break
+		}
+		n, err := copyLogTail(zw, root, name, min(supportBundleLogMaxBytes, supportBundleLogsMaxBytes-total))
+		if err != nil {
+			a.logger.Warn(ctx, "add log to support bundle", slog.F("name", name), slog.Error(err))
+			continue
+		}
+		total += n
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// copyLogTail copies up to limit bytes from the end of the named log file
+// into the archive.
+func copyLogTail(zw *zip.Writer, root *os.Root, name string, limit int64) (int64, error) {
+	f, err := root.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if offset := info.Size() - limit; offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return 0, err
+		}
+	}
+	w, err := zw.Create("logs/" + name)
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(w, io.LimitReader(f, limit))
+}
+
+func (a *agent) supportBundleNetcheck() (healthsdk.AgentNetcheckReport, error) {
+	conn := a.TailnetConn()
+	if conn == nil {
+		return healthsdk.AgentNetcheckReport{}, xerrors.New("network is not ready")
+	}
+	ifReport, err := healthsdk.RunInterfacesReport()
+	if err != nil {
+		return healthsdk.AgentNetcheckReport{}, xerrors.Errorf("run interfaces report: %w", err)
+	}
+	return healthsdk.AgentNetcheckReport{
+		BaseReport: healthsdk.BaseReport{
+			Severity: health.SeverityOK,
+		},
+		NetInfo:    conn.GetNetInfo(),
+		Interfaces: ifReport,
+	}, nil
+}
+
+// supportBundleEnvironment reports a fixed set of environment variables and
+// common problems with them, such as a missing home directory.
+func supportBundleEnvironment() workspacesdk.SupportBundleEnvironment {
+	env := workspacesdk.SupportBundleEnvironment{
+		Variables: make(map[string]string, len(supportBundleEnvVars)),
+	}
+	for _, key := range supportBundleEnvVars {
+		if value, ok := os.LookupEnv(key); ok {
+			env.Variables[key] = value
+		}
+	}
+
+	if home := env.Variables["HOME"]; home == "" {
+		env.Problems = append(env.Problems, "HOME is not set.")
+	} else if info, err := os.Stat(home); err != nil || !info.IsDir() {
+		env.Problems = append(env.Problems, "HOME "+home+" is not a directory.")
+	}
+	if shell := env.Variables["SHELL"]; shell != "" {
+		if _, err := os.Stat(shell); err != nil {
+			env.Problems = append(env.Problems, "SHELL "+shell+" does not exist.")
+		}
+	}
+	if env.Variables["PATH"] == "" {
+		env.Problems = append(env.Problems, "PATH is empty.")
+	}
+	for _, dir := range filepath.SplitList(env.Variables["PATH"]) {
+		if _, err := os.Stat(dir); err != nil {
+			env.Problems = append(env.Problems, "PATH entry "+dir+" does not exist.")
+		}
+	}
+	return env
+}
+
+// supportBundleDiskUsage reports the usage of the volumes holding the root,
+// home and temporary directories.
+func supportBundleDiskUsage() ([]workspacesdk.SupportBundleDiskUsage, error) {
+	st, err := clistat.New()
+	if err != nil {
+		return nil, xerrors.Errorf("create stat fetcher: %w", err)
+	}
+	paths := []string{string(filepath.Separator), os.TempDir()}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, home)
+	}
+	usage := make([]workspacesdk.SupportBundleDiskUsage, 0, len(paths))
+	for _, path := range paths {
+		res, err := st.Disk(clistat.PrefixDefault, path)
+		if err != nil {
+			usage = append(usage, workspacesdk.SupportBundleDiskUsage{Path: path, Error: err.Error()})
+			continue
+		}
+		du := workspacesdk.SupportBundleDiskUsage{
+			Path:      path,
+			UsedBytes: int64(res.Used),
+		}
+		if res.Total != nil {
+			du.TotalBytes = int64(*res.Total)
+		}
+		usage = append(usage, du)
+	}
+	return usage, nil
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/coder/coder/v2/agent/agentcontainers"
 	"github.com/coder/coder/v2/agent/agentcontextconfig"
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentquiesce"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/boundarylogproxy"
 	"github.com/coder/coder/v2/agent/reaper"
@@ -64,6 +65,8 @@ func workspaceAgent() *serpent.Command {
 		socketServerEnabled             bool
 		socketPath                      string
 		agentFirewallLogProxySocketPath string
+		quiesceScript                   string
+		resumeScript                    string
 	)
 	agentAuth := &AgentAuth{}
 	cmd := &serpent.Command{
@@ -337,6 +340,10 @@ func workspaceAgent() *serpent.Command {
 						agentcontainers.WithProjectDiscovery(devcontainerProjectDiscovery),
 						agentcontainers.WithDiscoveryAutostart(devcontainerDiscoveryAutostart),
 					},
+					Quiesce: agentquiesce.Config{
+						QuiesceScript: quiesceScript,
+						ResumeScript:  resumeScript,
+					},
 					SocketPath:                      socketPath,
 					SocketServerEnabled:             socketServerEnabled,
 					AgentFirewallLogProxySocketPath: agentFirewallLogProxySocketPath,
@@ -538,6 +545,18 @@ func workspaceAgent() *serpent.Command {
 			Description: "Allow the agent to autostart devcontainer projects it discovers based on their configuration.",
 			Value:       serpent.BoolOf(&devcontainerDiscoveryAutostart),
 		},
+		{
+			Flag:        "quiesce-script",
+			Env:         "CODER_AGENT_QUIESCE_SCRIPT",
+			Description: "A shell script run after flushing filesystems when the workspace is quiesced for a backup, e.g. to pause services.",
+			Value:       serpent.StringOf(&quiesceScript),
+		},
+		{
+			Flag:        "resume-script",
+			Env:         "CODER_AGENT_RESUME_SCRIPT",
+			Description: "A shell script run when a quiesced workspace is resumed, either on request or once the quiesce timeout elapsed.",
+			Value:       serpent.StringOf(&resumeScript),
+		},
 		{
 			Flag:        "socket-server-enabled",
 			Default:     "true",
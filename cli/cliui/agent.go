@@ -478,6 +478,7 @@ type ConnDiags struct {
 	AgentNetcheck      *healthsdk.AgentNetcheckReport
 	ClientIPIsAWS      bool
 	AgentIPIsAWS       bool
+	AgentClockSkew     *time.Duration
 	Verbose            bool
 	TroubleshootingURL string
 }
@@ -524,6 +525,18 @@ func (d ConnDiags) splitDiagnostics() (general, client, agent []string) {
 		}
 	}
 
+	if d.AgentClockSkew != nil {
+		skew := *d.AgentClockSkew
+		direction := "ahead of"
+		if skew < 0 {
+			direction = "behind"
+		}
+		if skew.Abs() > codersdk.WorkspaceAgentClockSkewThreshold {
+			general = append(general,
+				fmt.Sprintf("❗ The clock of the agent is %s %s the Coder server, which may break TLS connections and token validation\n   %s#clock-skew", skew.Abs().Round(time.Second), direction, d.TroubleshootingURL))
+		}
+	}
+
 	if d.PingP2P && !d.Verbose {
 		return general, client, agent
 	}
@@ -993,6 +993,19 @@ func TestConnDiagnostics(t *testing.T) {
 				`Agent IP address is within an AWS range (AWS uses hard NAT)`,
 			},
 		},
+		{
+			name: "AgentClockSkew",
+			diags: cliui.ConnDiags{
+				ConnInfo: workspacesdk.AgentConnectionInfo{
+					DERPMap: &tailcfg.DERPMap{},
+				},
+				PingP2P:        true,
+				AgentClockSkew: ptr.Ref(-5 * time.Minute),
+			},
+			want: []string{
+				`The clock of the agent is 5m0s behind the Coder server`,
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -160,6 +160,10 @@ func (r *RootCmd) ping() *serpent.Command {
 				PingP2P:            didP2p,
 				TroubleshootingURL: appearanceConfig.DocsURL + "/admin/networking/troubleshooting",
 			}
+			if workspaceAgent.ClockSkewMilliseconds != nil {
+				skew := time.Duration(*workspaceAgent.ClockSkewMilliseconds) * time.Millisecond
+				connDiags.AgentClockSkew = &skew
+			}
 
 			awsRanges, err := cliutil.FetchAWSIPRanges(diagCtx, cliutil.AWSIPRangesURL)
 			if err != nil {
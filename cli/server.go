@@ -66,6 +66,8 @@ import (
 	"github.com/coder/coder/v2/coderd/aibridged"
 	"github.com/coder/coder/v2/coderd/authlink"
 	"github.com/coder/coder/v2/coderd/autobuild"
+	"github.com/coder/coder/v2/coderd/buildlogarchive"
+	"github.com/coder/coder/v2/coderd/cloudresources"
 	"github.com/coder/coder/v2/coderd/cryptokeys"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/awsiamrds"
@@ -75,6 +77,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/migrations"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/devtunnel"
+	"github.com/coder/coder/v2/coderd/dormancyhook"
 	"github.com/coder/coder/v2/coderd/entitlements"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
@@ -91,6 +94,9 @@ import (
 	"github.com/coder/coder/v2/coderd/runtimeconfig"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/templaterestart"
+	"github.com/coder/coder/v2/coderd/templaterollout"
+	"github.com/coder/coder/v2/coderd/templatescan"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/ptr"
@@ -98,6 +104,8 @@ import (
 	stringutil "github.com/coder/coder/v2/coderd/util/strings"
 	"github.com/coder/coder/v2/coderd/webpush"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
+	"github.com/coder/coder/v2/coderd/workspacedns"
+	"github.com/coder/coder/v2/coderd/workspacelease"
 	"github.com/coder/coder/v2/coderd/workspacestats"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/coderd/x/nats"
@@ -442,6 +450,9 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			if !vals.TLS.Enable && vals.HTTPAddress.String() == "" {
 				return xerrors.Errorf("TLS is disabled. Enable with --tls-enable or specify a HTTP address")
 			}
+			if minTTL, maxTTL := vals.TemplateDefaultTTLMin.Value(), vals.TemplateDefaultTTLMax.Value(); maxTTL > 0 && minTTL > maxTTL {
+				return xerrors.Errorf("--template-default-ttl-min (%s) cannot be greater than --template-default-ttl-max (%s)", minTTL, maxTTL)
+			}
 
 			if vals.AccessURL.String() != "" &&
 				!(vals.AccessURL.Scheme == "http" || vals.AccessURL.Scheme == "https") {
@@ -457,10 +468,13 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			// was specified.
 			loginRateLimit := 60
 			filesRateLimit := 12
+			workspaceHeartbeatRateLimit := 12
 			if vals.RateLimit.DisableAll {
 				vals.RateLimit.API = -1
+				vals.RateLimit.WorkspaceBuilds = -1
 				loginRateLimit = -1
 				filesRateLimit = -1
+				workspaceHeartbeatRateLimit = -1
 			}
 
 			PrintLogo(inv, "Coder")
@@ -506,6 +520,11 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				traceCloseErr := shutdownWithTimeout(closeTracing, 5*time.Second)
 				logger.Debug(ctx, "tracing closed", slog.Error(traceCloseErr))
 			}()
+			workspaceBuildTracerProvider, closeWorkspaceBuildTracing := ConfigureWorkspaceBuildTraceProvider(ctx, logger, vals, tracerProvider)
+			defer func() {
+				traceCloseErr := shutdownWithTimeout(closeWorkspaceBuildTracing, 5*time.Second)
+				logger.Debug(ctx, "workspace build tracing closed", slog.Error(traceCloseErr))
+			}()
 
 			configSSHOptions, err := vals.SSHConfig.ParseOptions()
 			if err != nil {
@@ -766,6 +785,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				APIRateLimit:                int(vals.RateLimit.API.Value()),
 				LoginRateLimit:              loginRateLimit,
 				FilesRateLimit:              filesRateLimit,
+				WorkspaceHeartbeatRateLimit: workspaceHeartbeatRateLimit,
 				HTTPClient:                  httpClient,
 				TemplateScheduleStore:       &atomic.Pointer[schedule.TemplateScheduleStore]{},
 				UserQuietHoursScheduleStore: &atomic.Pointer[schedule.UserQuietHoursScheduleStore]{},
@@ -777,6 +797,12 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			if httpServers.TLSConfig != nil {
 				options.TLSCertificates = httpServers.TLSConfig.Certificates
 			}
+			options.WorkspaceBuildRateLimit = httpmw.BuildRateLimitConfig{
+				PerMinute:       int(vals.RateLimit.WorkspaceBuilds.Value()),
+				Burst:           int(vals.RateLimit.WorkspaceBuildsBurst.Value()),
+				AllowedTokenIDs: vals.RateLimit.WorkspaceBuildsAllowlist.Value(),
+			}
+			options.WorkspaceBuildTracerProvider = workspaceBuildTracerProvider
 
 			if vals.StrictTransportSecurity > 0 {
 				options.StrictTransportSecurityCfg, err = httpmw.HSTSConfigOptions(
@@ -1006,6 +1032,13 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			}
 			options.WebPushDispatcher = webpusher
 
+			if dir := vals.Retention.BuildLogsArchiveDir.String(); dir != "" {
+				options.BuildLogArchive, err = buildlogarchive.NewDirStore(dir)
+				if err != nil {
+					return xerrors.Errorf("create build log archive: %w", err)
+				}
+			}
+
 			githubOAuth2ConfigParams, err := getGithubOAuth2ConfigParams(ctx, options.Database, vals)
 			if err != nil {
 				return xerrors.Errorf("get github oauth2 config params: %w", err)
@@ -1344,8 +1377,11 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			autobuildTicker := time.NewTicker(vals.AutobuildPollInterval.Value())
 			defer autobuildTicker.Stop()
 			autobuildExecutor := autobuild.NewExecutor(
-				ctx, options.Database, options.Pubsub, coderAPI.FileCache, options.PrometheusRegistry, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, coderAPI.BuildUsageChecker, logger, autobuildTicker.C, options.NotificationsEnqueuer, coderAPI.Experiments, coderAPI.WorkspaceBuilderMetrics)
+				ctx, options.Database, options.Pubsub, coderAPI.FileCache, options.PrometheusRegistry, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, coderAPI.BuildUsageChecker, logger, autobuildTicker.C, options.NotificationsEnqueuer, coderAPI.Experiments, coderAPI.WorkspaceBuilderMetrics).
+				WithCostBudgets(vals.WorkspaceMonthlyCostBudget.Value(), vals.UserMonthlyCostBudget.Value()).
+				WithDormancyHooks(vals.WorkspaceDormancyHookURL.String() != "")
 			autobuildExecutor.Run()
+			coderAPI.LifecycleExecutor.Store(autobuildExecutor)
 
 			jobReaperTicker := time.NewTicker(vals.JobReaperDetectorInterval.Value())
 			defer jobReaperTicker.Stop()
@@ -1353,6 +1389,65 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			jobReaper.Start()
 			defer jobReaper.Close()
 
+			if scannerURL := vals.Provisioner.TemplateScannerURL.String(); scannerURL != "" {
+				templateScanTicker := time.NewTicker(templatescan.PollInterval)
+				defer templateScanTicker.Stop()
+				templateScanner := templatescan.New(ctx, options.Database, logger.Named("templatescan"), &http.Client{}, scannerURL, templateScanTicker.C)
+				templateScanner.Start()
+				defer templateScanner.Close()
+			}
+
+			if checkerURL := vals.Provisioner.CloudResourceCheckerURL.String(); checkerURL != "" {
+				cloudResourceTicker := time.NewTicker(cloudresources.PollInterval)
+				defer cloudResourceTicker.Stop()
+				cloudResourceChecker := cloudresources.NewWebhookChecker(&http.Client{}, checkerURL)
+				cloudResourceVerifier := cloudresources.New(ctx, options.Database, logger.Named("cloudresources"), cloudResourceChecker, cloudResourceTicker.C)
+				cloudResourceVerifier.Start()
+				defer cloudResourceVerifier.Close()
+			}
+
+			if dnsDomain, dnsURL := vals.WorkspaceDNSDomain.String(), vals.WorkspaceDNSProviderURL.String(); dnsDomain != "" && dnsURL != "" {
+				dnsTicker := time.NewTicker(workspacedns.PollInterval)
+				defer dnsTicker.Stop()
+				dnsProvider := workspacedns.NewWebhookProvider(&http.Client{}, dnsURL)
+				dnsRegistrar := workspacedns.New(ctx, options.Database, logger.Named("workspacedns"), dnsProvider, dnsDomain, vals.AccessURL.Value().Hostname(), dnsTicker.C)
+				dnsRegistrar.Start()
+				defer dnsRegistrar.Close()
+			}
+
+			if hookURL := vals.WorkspaceDormancyHookURL.String(); hookURL != "" {
+				dormancyHookTicker := time.NewTicker(dormancyhook.PollInterval)
+				defer dormancyHookTicker.Stop()
+				dormancyHook := dormancyhook.NewWebhookHook(&http.Client{}, hookURL)
+				dormancyHookRunner := dormancyhook.New(ctx, options.Database, logger.Named("dormancyhook"), dormancyHook, dormancyHookTicker.C)
+				dormancyHookRunner.Start()
+				defer dormancyHookRunner.Close()
+			}
+
+			workspaceLeaseTicker := time.NewTicker(workspacelease.PollInterval)
+			defer workspaceLeaseTicker.Stop()
+			workspaceLeaseReaper := workspacelease.New(ctx, options.Database, options.Pubsub, coderAPI.FileCache, coderAPI.BuildUsageChecker, logger.Named("workspacelease"), workspaceLeaseTicker.C)
+			workspaceLeaseReaper.Start()
+			defer workspaceLeaseReaper.Close()
+
+			templateRolloutTicker := time.NewTicker(templaterollout.PollInterval)
+			defer templateRolloutTicker.Stop()
+			templateRolloutController := templaterollout.New(ctx, options.Database, logger.Named("templaterollout"), templateRolloutTicker.C)
+			templateRolloutController.Start()
+			defer templateRolloutController.Close()
+
+			templateRestartTicker := time.NewTicker(templaterestart.PollInterval)
+			defer templateRestartTicker.Stop()
+			templateRestartController := templaterestart.New(ctx, options.Database, options.Pubsub, coderAPI.FileCache, coderAPI.BuildUsageChecker, coderAPI.UserQuietHoursScheduleStore, logger.Named("templaterestart"), templateRestartTicker.C)
+			templateRestartController.Start()
+			defer templateRestartController.Close()
+
+			buildLogArchiveTicker := time.NewTicker(buildlogarchive.PollInterval)
+			defer buildLogArchiveTicker.Stop()
+			buildLogArchiver := buildlogarchive.New(ctx, options.Database, options.BuildLogArchive, vals.Retention.BuildLogs.Value(), logger.Named("buildlogarchive"), buildLogArchiveTicker.C)
+			buildLogArchiver.Start()
+			defer buildLogArchiver.Close()
+
 			waitForProvisionerJobs := false
 			// Currently there is no way to ask the server to shut
 			// itself down, so any exit signal will result in a non-zero
@@ -2882,6 +2977,37 @@ func ConfigureTraceProviderWithService(
 	return tracerProvider, sqlDriver, closeTracing
 }
 
+// ConfigureWorkspaceBuildTraceProvider returns the trace provider that
+// workspace builds are exported to. It returns nil when exporting workspace
+// builds is disabled. Without a dedicated endpoint, builds are exported with
+// the application trace provider.
+func ConfigureWorkspaceBuildTraceProvider(
+	ctx context.Context,
+	logger slog.Logger,
+	cfg *codersdk.DeploymentValues,
+	tracerProvider trace.TracerProvider,
+) (trace.TracerProvider, func(context.Context) error) {
+	closeTracing := func(context.Context) error { return nil }
+	if !cfg.Trace.WorkspaceBuilds.Value() {
+		return nil, closeTracing
+	}
+
+	if endpoint := cfg.Trace.WorkspaceBuildsEndpoint.String(); endpoint != "" {
+		sdkTracerProvider, closeSDKTracing, err := tracing.EndpointTracerProvider(ctx, "coderd", endpoint)
+		if err != nil {
+			logger.Warn(ctx, "start workspace build trace exporter", slog.Error(err))
+			return nil, closeTracing
+		}
+		return sdkTracerProvider, closeSDKTracing
+	}
+
+	if !cfg.Trace.Enable.Value() && cfg.Trace.HoneycombAPIKey == "" {
+		logger.Warn(ctx, "workspace build tracing is enabled, but tracing is not; enable --trace or set --trace-workspace-builds-endpoint")
+		return nil, closeTracing
+	}
+	return tracerProvider, closeTracing
+}
+
 func ConfigureHTTPServers(logger slog.Logger, inv *serpent.Invocation, cfg *codersdk.DeploymentValues) (_ *HTTPServers, err error) {
 	ctx := inv.Context()
 	httpServers := &HTTPServers{}
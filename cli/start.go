@@ -150,7 +150,7 @@ func buildWorkspaceStartRequest(inv *serpent.Invocation, client *codersdk.Client
 		}
 	}
 
-	lastBuildParameters, err := client.WorkspaceBuildParameters(inv.Context(), workspace.LatestBuild.ID)
+	lastBuildParameters, err := client.WorkspaceBuildParametersForRebuild(inv.Context(), workspace.LatestBuild.ID)
 	if err != nil {
 		return codersdk.CreateWorkspaceBuildRequest{}, err
 	}
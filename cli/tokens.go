@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/cliui"
@@ -58,6 +59,8 @@ func (r *RootCmd) createToken() *serpent.Command {
 		user          string
 		scopes        []string
 		allowList     []codersdk.APIAllowListTarget
+		templateIDs   []string
+		workspace     string
 	)
 	cmd := &serpent.Command{
 		Use:   "create",
@@ -106,6 +109,20 @@ func (r *RootCmd) createToken() *serpent.Command {
 			if len(allowList) > 0 {
 				req.AllowList = append([]codersdk.APIAllowListTarget(nil), allowList...)
 			}
+			for _, id := range templateIDs {
+				templateID, err := uuid.Parse(id)
+				if err != nil {
+					return xerrors.Errorf("parse template ID %q: %w", id, err)
+				}
+				req.TemplateIDs = append(req.TemplateIDs, templateID)
+			}
+			if workspace != "" {
+				ws, err := client.ResolveWorkspace(inv.Context(), workspace)
+				if err != nil {
+					return xerrors.Errorf("get workspace %q: %w", workspace, err)
+				}
+				req.WorkspaceID = ws.ID
+			}
 
 			res, err := client.CreateToken(inv.Context(), userID, req)
 			if err != nil {
@@ -149,6 +166,16 @@ func (r *RootCmd) createToken() *serpent.Command {
 			Description: "Repeatable allow-list entry (`<type>:<uuid>`, e.g. workspace:1234-...).",
 			Value:       AllowListFlagOf(&allowList),
 		},
+		{
+			Flag:        "template",
+			Description: "Repeatable template ID to restrict the token to. Workspaces built from other templates cannot be accessed with the token.",
+			Value:       serpent.StringArrayOf(&templateIDs),
+		},
+		{
+			Flag:        "workspace",
+			Description: "Restrict the token to a single workspace you own, by name or ID. The token can only read, build, and connect to that workspace. Cannot be combined with --scope, --allow, or --template.",
+			Value:       serpent.StringOf(&workspace),
+		},
 	}
 
 	return cmd
@@ -21,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/agentapi/resourcesmonitor"
 	"github.com/coder/coder/v2/coderd/appearance"
 	"github.com/coder/coder/v2/coderd/boundaryusage"
+	"github.com/coder/coder/v2/coderd/buildtrace"
 	"github.com/coder/coder/v2/coderd/connectionlog"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
@@ -59,6 +60,7 @@ type API struct {
 	*SubAgentAPI
 	*BoundaryLogsAPI
 	*ContextAPI
+	*WorkspaceTransitionAPI
 	*tailnet.DRPCService
 
 	cachedWorkspaceFields *CachedWorkspaceFields
@@ -72,6 +74,7 @@ type Options struct {
 	AgentID           uuid.UUID
 	OwnerID           uuid.UUID
 	WorkspaceID       uuid.UUID
+	WorkspaceBuildID  uuid.UUID
 	OrganizationID    uuid.UUID
 	TemplateVersionID uuid.UUID
 
@@ -95,7 +98,11 @@ type Options struct {
 	NetworkTelemetryHandler           func(batch []*tailnetproto.TelemetryEvent)
 	BoundaryUsageTracker              *boundaryusage.Tracker
 	LifecycleMetrics                  *LifecycleMetrics
+	BuildTraces                       *buildtrace.Exporter
 	PortSharer                        *atomic.Pointer[portsharing.PortSharer]
+	// RequestWorkspaceTransitionFn queues a self-requested stop or restart
+	// of the workspace. Nil disables RequestWorkspaceTransition.
+	RequestWorkspaceTransitionFn func(ctx context.Context, ownerID, workspaceID uuid.UUID, restart bool) (uuid.UUID, error)
 
 	AccessURL                 *url.URL
 	AppHostname               string
@@ -143,12 +150,14 @@ func New(opts Options, workspace database.Workspace, agent database.WorkspaceAge
 	}
 
 	api.ResourcesMonitoringAPI = &ResourcesMonitoringAPI{
-		AgentID:               opts.AgentID,
-		WorkspaceID:           opts.WorkspaceID,
-		Clock:                 opts.Clock,
-		Database:              opts.Database,
-		NotificationsEnqueuer: opts.NotificationsEnqueuer,
-		Debounce:              30 * time.Minute,
+		AgentID:                  opts.AgentID,
+		WorkspaceID:              opts.WorkspaceID,
+		Log:                      opts.Log,
+		Clock:                    opts.Clock,
+		Database:                 opts.Database,
+		NotificationsEnqueuer:    opts.NotificationsEnqueuer,
+		PublishWorkspaceUpdateFn: api.publishWorkspaceUpdate,
+		Debounce:                 30 * time.Minute,
 
 		Config: resourcesmonitor.Config{
 			NumDatapoints:      20,
@@ -175,10 +184,12 @@ func New(opts Options, workspace database.Workspace, agent database.WorkspaceAge
 	api.LifecycleAPI = &LifecycleAPI{
 		AgentFn:                  api.agent,
 		WorkspaceID:              opts.WorkspaceID,
+		WorkspaceBuildID:         opts.WorkspaceBuildID,
 		Database:                 opts.Database,
 		Log:                      opts.Log,
 		PublishWorkspaceUpdateFn: api.publishWorkspaceUpdate,
 		Metrics:                  opts.LifecycleMetrics,
+		BuildTraces:              opts.BuildTraces,
 	}
 
 	api.AppsAPI = &AppsAPI{
@@ -259,6 +270,13 @@ func New(opts Options, workspace database.Workspace, agent database.WorkspaceAge
 		DirtyMarker: opts.ContextDirtyMarker,
 	}
 
+	api.WorkspaceTransitionAPI = &WorkspaceTransitionAPI{
+		WorkspaceID:                  opts.WorkspaceID,
+		Database:                     opts.Database,
+		Log:                          opts.Log,
+		RequestWorkspaceTransitionFn: opts.RequestWorkspaceTransitionFn,
+	}
+
 	// Start background cache refresh loop to handle workspace changes
 	// like prebuild claims where owner_id and other fields may be modified in the DB.
 	go api.startCacheRefreshLoop(opts.AuthenticatedCtx)
@@ -14,6 +14,7 @@ import (
 
 	"cdr.dev/slog/v3"
 	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/buildtrace"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/wspubsub"
@@ -28,12 +29,14 @@ func WithAPIVersion(ctx context.Context, version string) context.Context {
 type LifecycleAPI struct {
 	AgentFn                  func(context.Context) (database.WorkspaceAgent, error)
 	WorkspaceID              uuid.UUID
+	WorkspaceBuildID         uuid.UUID
 	Database                 database.Store
 	Log                      slog.Logger
 	PublishWorkspaceUpdateFn func(context.Context, uuid.UUID, wspubsub.WorkspaceEventKind) error
 
 	TimeNowFn       func() time.Time // defaults to dbtime.Now()
 	Metrics         *LifecycleMetrics
+	BuildTraces     *buildtrace.Exporter
 	emitMetricsOnce sync.Once
 }
 
@@ -52,6 +55,7 @@ func (a *LifecycleAPI) UpdateLifecycle(ctx context.Context, req *agentproto.Upda
 
 	logger := a.Log.With(
 		slog.F("workspace_id", a.WorkspaceID),
+		slog.F("correlation_id", a.WorkspaceBuildID),
 		slog.F("payload", req),
 	)
 	logger.Debug(ctx, "workspace agent state report")
@@ -138,6 +142,7 @@ func (a *LifecycleAPI) UpdateLifecycle(ctx context.Context, req *agentproto.Upda
 		if !workspaceAgent.ParentID.Valid {
 			a.emitMetricsOnce.Do(func() {
 				a.emitBuildDurationMetric(ctx, workspaceAgent.ResourceID)
+				a.exportBuildTrace(ctx, workspaceAgent.ResourceID)
 			})
 		}
 	}
@@ -202,3 +207,21 @@ func (a *LifecycleAPI) UpdateStartup(ctx context.Context, req *agentproto.Update
 
 	return req.Startup, nil
 }
+
+// exportBuildTrace exports the build as a trace once all of its agents have
+// reached a terminal startup state.
+func (a *LifecycleAPI) exportBuildTrace(ctx context.Context, resourceID uuid.UUID) {
+	if a.BuildTraces == nil {
+		return
+	}
+
+	buildInfo, err := a.Database.GetWorkspaceBuildMetricsByResourceID(ctx, resourceID)
+	if err != nil {
+		a.Log.Warn(ctx, "failed to get build info for trace", slog.Error(err))
+		return
+	}
+	if !buildInfo.AllAgentsReady {
+		return
+	}
+	a.BuildTraces.ExportAsync(a.WorkspaceBuildID)
+}
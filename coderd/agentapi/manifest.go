@@ -44,6 +44,7 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		metadata      []database.WorkspaceAgentMetadatum
 		workspace     database.Workspace
 		devcontainers []database.WorkspaceAgentDevcontainer
+		services      []database.WorkspaceAgentService
 	)
 
 	workspaceAgent, err := a.AgentFn(ctx)
@@ -85,6 +86,11 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		}
 		return nil
 	})
+	eg.Go(func() (err error) {
+		// nolint:gocritic // This is necessary to fetch agent services!
+		services, err = a.Database.GetWorkspaceAgentServicesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+		return err
+	})
 	err = eg.Wait()
 	if err != nil {
 		return nil, xerrors.Errorf("fetching workspace agent data: %w", err)
@@ -143,6 +149,7 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		DisableDirectConnections: a.DisableDirectConnections,
 		DerpForceWebsockets:      a.DerpForceWebSockets,
 		ParentId:                 parentID,
+		ReadOnly:                 workspace.ReadOnlyAt.Valid,
 
 		DerpMap:       tailnet.DERPMapToProto(a.DerpMapFn()),
 		Scripts:       dbAgentScriptsToProto(scripts),
@@ -150,6 +157,7 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		Metadata:      dbAgentMetadataToProtoDescription(metadata),
 		Devcontainers: dbAgentDevcontainersToProto(devcontainers),
 		Secrets:       dbUserSecretsToProto(userSecrets),
+		Services:      dbAgentServicesToProto(services),
 	}, nil
 }
 
@@ -275,6 +283,19 @@ func dbAgentDevcontainersToProto(devcontainers []database.WorkspaceAgentDevconta
 	return ret
 }
 
+func dbAgentServicesToProto(services []database.WorkspaceAgentService) []*agentproto.WorkspaceAgentService {
+	ret := make([]*agentproto.WorkspaceAgentService, len(services))
+	for i, service := range services {
+		ret[i] = &agentproto.WorkspaceAgentService{
+			Name:          service.Name,
+			DisplayName:   service.DisplayName,
+			Command:       service.Command,
+			RestartPolicy: string(service.RestartPolicy),
+		}
+	}
+	return ret
+}
+
 func dbUserSecretsToProto(secrets []database.UserSecret) []*agentproto.WorkspaceSecret {
 	ret := make([]*agentproto.WorkspaceSecret, 0, len(secrets))
 	for _, s := range secrets {
@@ -335,6 +335,7 @@ func TestGetManifest(t *testing.T) {
 			Keys:             nil, // all
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), agent.ID).Return(devcontainers, nil)
+		mDB.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return(nil, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
 		mDB.EXPECT().ListUserSecretsWithValues(gomock.Any(), workspace.OwnerID).Return(nil, nil)
 
@@ -364,6 +365,7 @@ func TestGetManifest(t *testing.T) {
 			Metadata:      protoMetadata,
 			Devcontainers: protoDevcontainers,
 			Secrets:       []*agentproto.WorkspaceSecret{},
+			Services:      []*agentproto.WorkspaceAgentService{},
 		}
 
 		// Log got and expected with spew.
@@ -402,6 +404,7 @@ func TestGetManifest(t *testing.T) {
 			Keys:             nil, // all
 		}).Return([]database.WorkspaceAgentMetadatum{}, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), childAgent.ID).Return([]database.WorkspaceAgentDevcontainer{}, nil)
+		mDB.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{childAgent.ID}).Return(nil, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
 		mDB.EXPECT().ListUserSecretsWithValues(gomock.Any(), workspace.OwnerID).Return(nil, nil)
 
@@ -431,6 +434,7 @@ func TestGetManifest(t *testing.T) {
 			Metadata:      []*agentproto.WorkspaceAgentMetadata_Description{},
 			Devcontainers: []*agentproto.WorkspaceAgentDevcontainer{},
 			Secrets:       []*agentproto.WorkspaceSecret{},
+			Services:      []*agentproto.WorkspaceAgentService{},
 		}
 
 		require.Equal(t, expected, got)
@@ -465,6 +469,7 @@ func TestGetManifest(t *testing.T) {
 			Keys:             nil,
 		}).Return([]database.WorkspaceAgentMetadatum{}, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), childAgent.ID).Return([]database.WorkspaceAgentDevcontainer{}, nil)
+		mDB.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{childAgent.ID}).Return(nil, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
 
 		// Return a mix of secrets: env-only, file-only, both, and
@@ -495,6 +500,40 @@ func TestGetManifest(t *testing.T) {
 		require.Equal(t, []byte("both-val"), got.Secrets[2].Value)
 	})
 
+	t.Run("ReadOnly", func(t *testing.T) {
+		t.Parallel()
+
+		mDB := dbmock.NewMockStore(gomock.NewController(t))
+
+		api := &agentapi.ManifestAPI{
+			AccessURL:   &url.URL{Scheme: "https", Host: "example.com"},
+			AppHostname: "*--apps.example.com",
+
+			AgentFn:     func(ctx context.Context) (database.WorkspaceAgent, error) { return childAgent, nil },
+			WorkspaceID: workspace.ID,
+			Database:    mDB,
+			DerpMapFn:   derpMapFn,
+		}
+
+		readOnlyWorkspace := workspace
+		readOnlyWorkspace.ReadOnlyAt = sql.NullTime{Valid: true, Time: dbtime.Now()}
+
+		mDB.EXPECT().GetWorkspaceAppsByAgentID(gomock.Any(), childAgent.ID).Return([]database.WorkspaceApp{}, nil)
+		mDB.EXPECT().GetWorkspaceAgentScriptsByAgentIDs(gomock.Any(), []uuid.UUID{childAgent.ID}).Return([]database.GetWorkspaceAgentScriptsByAgentIDsRow{}, nil)
+		mDB.EXPECT().GetWorkspaceAgentMetadata(gomock.Any(), database.GetWorkspaceAgentMetadataParams{
+			WorkspaceAgentID: childAgent.ID,
+			Keys:             nil,
+		}).Return([]database.WorkspaceAgentMetadatum{}, nil)
+		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), childAgent.ID).Return([]database.WorkspaceAgentDevcontainer{}, nil)
+		mDB.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{childAgent.ID}).Return(nil, nil)
+		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(readOnlyWorkspace, nil)
+		mDB.EXPECT().ListUserSecretsWithValues(gomock.Any(), workspace.OwnerID).Return(nil, nil)
+
+		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
+		require.NoError(t, err)
+		require.True(t, got.ReadOnly)
+	})
+
 	t.Run("NoAppHostname", func(t *testing.T) {
 		t.Parallel()
 
@@ -584,6 +623,7 @@ func TestGetManifest(t *testing.T) {
 			Keys:             nil, // all
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), agent.ID).Return(devcontainers, nil)
+		mDB.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), []uuid.UUID{agent.ID}).Return(nil, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
 		mDB.EXPECT().ListUserSecretsWithValues(gomock.Any(), workspace.OwnerID).Return(nil, nil)
 
@@ -612,6 +652,7 @@ func TestGetManifest(t *testing.T) {
 			Metadata:      protoMetadata,
 			Devcontainers: protoDevcontainers,
 			Secrets:       []*agentproto.WorkspaceSecret{},
+			Services:      []*agentproto.WorkspaceAgentService{},
 		}
 
 		// Log got and expected with spew.
@@ -93,6 +93,9 @@ type Batcher struct {
 	// Used to only log at warn level for dropped keys infrequently, as it could be noisy in failure scenarios.
 	warnTicker *quartz.Ticker
 
+	// recordHistory also appends every flushed value to the metadata history.
+	recordHistory bool
+
 	// ctx is the context for the batcher. Used to check if shutdown has begun.
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -123,6 +126,15 @@ func WithLogger(log slog.Logger) Option {
 	}
 }
 
+// WithHistory records the flushed values in the agent metadata history, so
+// they can be graphed over time. Only the latest value of each key within a
+// flush interval is recorded.
+func WithHistory(enabled bool) Option {
+	return func(b *Batcher) {
+		b.recordHistory = enabled
+	}
+}
+
 func WithClock(clock quartz.Clock) Option {
 	return func(b *Batcher) {
 		b.clock = clock
@@ -360,6 +372,20 @@ func (b *Batcher) flush(ctx context.Context, reason string) {
 		return
 	}
 
+	if b.recordHistory {
+		err = b.store.InsertWorkspaceAgentMetadataHistory(ctx, database.InsertWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: agentIDs,
+			Key:              keys,
+			Value:            values,
+			Error:            errors,
+			CollectedAt:      collectedAt,
+		})
+		if err != nil && !database.IsQueryCanceledError(err) {
+			// The latest values were stored, so still notify subscribers.
+			b.log.Error(ctx, "error recording workspace agent metadata history", slog.Error(err))
+		}
+	}
+
 	// Build list of unique agent IDs for pubsub notification.
 	uniqueAgentIDs := make([]uuid.UUID, 0, len(agentKeys))
 	for agentID := range agentKeys {
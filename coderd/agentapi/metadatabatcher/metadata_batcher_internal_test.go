@@ -756,6 +756,62 @@ func TestMetadataBatcher_TimestampOrdering(t *testing.T) {
 	require.Equal(t, float64(1), prom_testutil.ToFloat64(b.Metrics.MetadataTotal))
 }
 
+func TestMetadataBatcher_History(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	log := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+	ctrl := gomock.NewController(t)
+	store := dbmock.NewMockStore(ctrl)
+	ps := psmock.NewMockPubsub(ctrl)
+	clock := quartz.NewMock(t)
+
+	reg := prometheus.NewRegistry()
+	b, err := NewBatcher(ctx, reg, store, ps,
+		WithLogger(log),
+		WithClock(clock),
+		WithHistory(true),
+	)
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	agent := uuid.New()
+	collectedAt := clock.Now()
+	psCap := newPubsubCapture(t)
+
+	store.EXPECT().
+		BatchUpdateWorkspaceAgentMetadata(gomock.Any(), gomock.Any()).
+		Return(nil).
+		Times(1)
+	// The flushed value is also recorded in the history.
+	store.EXPECT().
+		InsertWorkspaceAgentMetadataHistory(gomock.Any(), database.InsertWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: []uuid.UUID{agent},
+			Key:              []string{"gpu_temperature"},
+			Value:            []string{"71"},
+			Error:            []string{""},
+			CollectedAt:      []time.Time{collectedAt},
+		}).
+		Return(nil).
+		Times(1)
+	ps.EXPECT().
+		Publish(gomock.Any(), gomock.Any()).
+		Do(psCap.capture).
+		Return(nil).
+		Times(1)
+
+	require.NoError(t, b.Add(agent, []string{"gpu_temperature"}, []string{"71"}, []string{""}, []time.Time{collectedAt}))
+	testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+		return len(b.updateCh) == 0 && int(b.currentBatchLen.Load()) == 1
+	}, testutil.IntervalFast)
+
+	clock.Advance(defaultMetadataFlushInterval).MustWait(ctx)
+	testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+		return psCap.count() == 1
+	}, testutil.IntervalFast)
+	psCap.requireContainsAll([]uuid.UUID{agent})
+}
+
 func TestMetadataBatcher_PubsubChunking(t *testing.T) {
 	t.Parallel()
 
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -18,9 +19,16 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/quartz"
 )
 
+// MemoryPressureThreshold is the "full avg10" memory pressure, in percent,
+// above which a workspace is considered to be under severe memory pressure.
+// At this level every process of the workspace is stalled on memory for at
+// least a tenth of the time.
+const MemoryPressureThreshold = 10.0
+
 type ResourcesMonitoringAPI struct {
 	AgentID     uuid.UUID
 	WorkspaceID uuid.UUID
@@ -29,6 +37,9 @@ type ResourcesMonitoringAPI struct {
 	Clock                 quartz.Clock
 	Database              database.Store
 	NotificationsEnqueuer notifications.Enqueuer
+	// PublishWorkspaceUpdateFn is optional. It is called when memory events
+	// are recorded so that clients refresh the health of the agent.
+	PublishWorkspaceUpdateFn func(ctx context.Context, agentID uuid.UUID, kind wspubsub.WorkspaceEventKind) error
 
 	Debounce time.Duration
 	Config   resourcesmonitor.Config
@@ -37,6 +48,12 @@ type ResourcesMonitoringAPI struct {
 	memoryMonitor  database.WorkspaceAgentMemoryResourceMonitor
 	volumeMonitors []database.WorkspaceAgentVolumeResourceMonitor
 	monitorsLock   sync.RWMutex
+
+	// The agent resends a sliding window of datapoints on every push, so we
+	// remember the newest datapoint whose memory events were recorded and
+	// whether the workspace was under memory pressure at that point.
+	lastMemoryEventAt   time.Time
+	underMemoryPressure bool
 }
 
 // InitMonitors fetches resource monitors from the database and caches them.
@@ -106,6 +123,10 @@ func (a *ResourcesMonitoringAPI) PushResourcesMonitoringUsage(ctx context.Contex
 		err = errors.Join(err, xerrors.Errorf("monitor volume: %w", volumeErr))
 	}
 
+	if memoryEventsErr := a.recordMemoryEvents(ctx, req.Datapoints); memoryEventsErr != nil {
+		err = errors.Join(err, xerrors.Errorf("record memory events: %w", memoryEventsErr))
+	}
+
 	return &proto.PushResourcesMonitoringUsageResponse{}, err
 }
 
@@ -275,3 +296,99 @@ func (a *ResourcesMonitoringAPI) monitorVolumes(ctx context.Context, datapoints
 
 	return nil
 }
+
+// recordMemoryEvents stores the OOM kills reported by the agent and the
+// moments the workspace came under severe memory pressure. Unlike memory
+// monitors, which are configured by the template, memory events are always
+// recorded so that users can tell why their processes vanished.
+func (a *ResourcesMonitoringAPI) recordMemoryEvents(ctx context.Context, datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint) error {
+	var (
+		oomKills int64
+		recorded bool
+	)
+
+	for _, datapoint := range datapoints {
+		collectedAt := datapoint.GetCollectedAt().AsTime()
+		if !collectedAt.After(a.lastMemoryEventAt) {
+			continue
+		}
+
+		if datapoint.GetOomKills() > 0 {
+			if err := a.insertMemoryEvent(ctx, collectedAt, database.WorkspaceAgentMemoryEventKindOomKill, datapoint); err != nil {
+				return err
+			}
+			oomKills += datapoint.GetOomKills()
+			recorded = true
+		}
+
+		// Only the transition into memory pressure is an event, otherwise a
+		// workspace that is constantly swapping would flood the timeline.
+		underPressure := datapoint.GetMemoryPressure() >= MemoryPressureThreshold
+		if underPressure && !a.underMemoryPressure {
+			if err := a.insertMemoryEvent(ctx, collectedAt, database.WorkspaceAgentMemoryEventKindMemoryPressure, datapoint); err != nil {
+				return err
+			}
+			recorded = true
+		}
+
+		a.underMemoryPressure = underPressure
+		a.lastMemoryEventAt = collectedAt
+	}
+
+	if recorded && a.PublishWorkspaceUpdateFn != nil {
+		if err := a.PublishWorkspaceUpdateFn(ctx, a.AgentID, wspubsub.WorkspaceEventKindAgentMemoryEvent); err != nil {
+			a.Log.Warn(ctx, "failed to publish workspace update", slog.Error(err))
+		}
+	}
+
+	if oomKills == 0 {
+		return nil
+	}
+
+	workspace, err := a.Database.GetWorkspaceByID(ctx, a.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace by id: %w", err)
+	}
+
+	// This notification is disabled by default, users opt into it from
+	// their notification preferences.
+	if _, err := a.NotificationsEnqueuer.EnqueueWithData(
+		// nolint:gocritic // We need to be able to send the notification.
+		dbauthz.AsNotifier(ctx),
+		workspace.OwnerID,
+		notifications.TemplateWorkspaceOutOfMemoryKill,
+		map[string]string{
+			"workspace": workspace.Name,
+			"oom_kills": fmt.Sprintf("%d", oomKills),
+		},
+		map[string]any{
+			// Every OOM kill is worth a notification, so circumvent the
+			// daily deduplication of identical notifications.
+			"timestamp": a.Clock.Now(),
+		},
+		"workspace-monitor-memory",
+		workspace.ID,
+		workspace.OwnerID,
+		workspace.OrganizationID,
+	); err != nil {
+		return xerrors.Errorf("notify workspace OOM kill: %w", err)
+	}
+
+	return nil
+}
+
+func (a *ResourcesMonitoringAPI) insertMemoryEvent(ctx context.Context, collectedAt time.Time, kind database.WorkspaceAgentMemoryEventKind, datapoint *proto.PushResourcesMonitoringUsageRequest_Datapoint) error {
+	//nolint:gocritic // We need to be able to record memory events here.
+	_, err := a.Database.InsertWorkspaceAgentMemoryEvent(dbauthz.AsResourceMonitor(ctx), database.InsertWorkspaceAgentMemoryEventParams{
+		ID:               uuid.New(),
+		WorkspaceAgentID: a.AgentID,
+		CreatedAt:        dbtime.Time(collectedAt),
+		Kind:             kind,
+		OomKills:         int32(min(datapoint.GetOomKills(), math.MaxInt32)),
+		MemoryPressure:   datapoint.GetMemoryPressure(),
+	})
+	if err != nil {
+		return xerrors.Errorf("insert %s memory event: %w", kind, err)
+	}
+	return nil
+}
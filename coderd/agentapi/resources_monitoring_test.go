@@ -964,3 +964,93 @@ func requireVolumeData(t *testing.T, notif *notificationstest.FakeNotification)
 
 	return volumesData.([]map[string]any)
 }
+
+func TestMemoryEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OOMKills", func(t *testing.T) {
+		t.Parallel()
+
+		api, user, clock, notifyEnq := resourceMonitorAPI(t)
+		require.NoError(t, api.InitMonitors(context.Background()))
+
+		collectedAt := clock.Now()
+		datapoints := make([]*agentproto.PushResourcesMonitoringUsageRequest_Datapoint, 0, 4)
+		for _, kills := range []int64{0, 2, 0} {
+			collectedAt = collectedAt.Add(10 * time.Second)
+			datapoints = append(datapoints, &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				CollectedAt: timestamppb.New(collectedAt),
+				OomKills:    kills,
+			})
+		}
+
+		_, err := api.PushResourcesMonitoringUsage(context.Background(), &agentproto.PushResourcesMonitoringUsageRequest{
+			Datapoints: datapoints,
+		})
+		require.NoError(t, err)
+
+		sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceOutOfMemoryKill))
+		require.Len(t, sent, 1)
+		require.Equal(t, user.ID, sent[0].UserID)
+		require.Equal(t, "2", sent[0].Labels["oom_kills"])
+
+		// The agent sends a sliding window of datapoints, the datapoints
+		// that were already processed must not be recorded twice.
+		collectedAt = collectedAt.Add(10 * time.Second)
+		datapoints = append(datapoints[1:], &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+			CollectedAt: timestamppb.New(collectedAt),
+		})
+		_, err = api.PushResourcesMonitoringUsage(context.Background(), &agentproto.PushResourcesMonitoringUsageRequest{
+			Datapoints: datapoints,
+		})
+		require.NoError(t, err)
+
+		events, err := api.Database.GetWorkspaceAgentMemoryEventsByAgentIDs(context.Background(), database.GetWorkspaceAgentMemoryEventsByAgentIDsParams{
+			IDs: []uuid.UUID{api.AgentID},
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, database.WorkspaceAgentMemoryEventKindOomKill, events[0].Kind)
+		require.EqualValues(t, 2, events[0].OomKills)
+
+		sent = notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceOutOfMemoryKill))
+		require.Len(t, sent, 1)
+	})
+
+	t.Run("MemoryPressure", func(t *testing.T) {
+		t.Parallel()
+
+		api, _, clock, notifyEnq := resourceMonitorAPI(t)
+		require.NoError(t, api.InitMonitors(context.Background()))
+
+		collectedAt := clock.Now()
+		datapoints := make([]*agentproto.PushResourcesMonitoringUsageRequest_Datapoint, 0, 5)
+		for _, pressure := range []float64{0, 20, 35, 1, 15} {
+			collectedAt = collectedAt.Add(10 * time.Second)
+			datapoints = append(datapoints, &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				CollectedAt:    timestamppb.New(collectedAt),
+				MemoryPressure: pressure,
+			})
+		}
+
+		_, err := api.PushResourcesMonitoringUsage(context.Background(), &agentproto.PushResourcesMonitoringUsageRequest{
+			Datapoints: datapoints,
+		})
+		require.NoError(t, err)
+
+		// Only entering memory pressure is recorded, staying under
+		// pressure is not a new event.
+		events, err := api.Database.GetWorkspaceAgentMemoryEventsByAgentIDs(context.Background(), database.GetWorkspaceAgentMemoryEventsByAgentIDsParams{
+			IDs: []uuid.UUID{api.AgentID},
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		for _, event := range events {
+			require.Equal(t, database.WorkspaceAgentMemoryEventKindMemoryPressure, event.Kind)
+		}
+		require.Equal(t, 15.0, events[0].MemoryPressure)
+		require.Equal(t, 20.0, events[1].MemoryPressure)
+
+		require.Empty(t, notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceOutOfMemoryKill)))
+	})
+}
@@ -2,6 +2,8 @@ package agentapi
 
 import (
 	"context"
+	"database/sql"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,8 +29,18 @@ type StatsAPI struct {
 	Experiments               codersdk.Experiments
 
 	TimeNowFn func() time.Time // defaults to dbtime.Now()
+
+	clockSkewMu sync.Mutex
+	// lastClockSkew is the clock skew of the agent last written to the
+	// database.
+	lastClockSkew *time.Duration
 }
 
+// clockSkewPrecision is the smallest change in the clock skew of an agent that
+// is written to the database. The latency of each report makes the measured
+// skew vary slightly, which isn't worth a write.
+const clockSkewPrecision = time.Second
+
 func (a *StatsAPI) now() time.Time {
 	if a.TimeNowFn != nil {
 		return a.TimeNowFn()
@@ -40,6 +52,13 @@ func (a *StatsAPI) UpdateStats(ctx context.Context, req *agentproto.UpdateStatsR
 	res := &agentproto.UpdateStatsResponse{
 		ReportInterval: durationpb.New(a.AgentStatsRefreshInterval),
 	}
+	if req.SentAt != nil {
+		err := a.recordClockSkew(ctx, req.SentAt.AsTime())
+		if err != nil {
+			// The clock skew is informational, so it doesn't fail the report.
+			a.Log.Warn(ctx, "failed to record agent clock skew", slog.Error(err))
+		}
+	}
 	// An empty stat means it's just looking for the report interval.
 	if req.Stats == nil {
 		return res, nil
@@ -87,3 +106,25 @@ func (a *StatsAPI) UpdateStats(ctx context.Context, req *agentproto.UpdateStatsR
 
 	return res, nil
 }
+
+// recordClockSkew compares the time the agent sent a report at to the time it
+// was received. The measured skew includes the latency of the report, which is
+// negligible next to codersdk.WorkspaceAgentClockSkewThreshold.
+func (a *StatsAPI) recordClockSkew(ctx context.Context, sentAt time.Time) error {
+	skew := sentAt.Sub(a.now())
+
+	a.clockSkewMu.Lock()
+	defer a.clockSkewMu.Unlock()
+	if a.lastClockSkew != nil && (skew-*a.lastClockSkew).Abs() < clockSkewPrecision {
+		return nil
+	}
+	err := a.Database.UpdateWorkspaceAgentClockSkewByID(ctx, database.UpdateWorkspaceAgentClockSkewByIDParams{
+		ID:          a.AgentID,
+		ClockSkewMs: sql.NullInt64{Int64: skew.Milliseconds(), Valid: true},
+	})
+	if err != nil {
+		return xerrors.Errorf("update workspace agent clock skew: %w", err)
+	}
+	a.lastClockSkew = &skew
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/agentapi"
@@ -101,6 +102,10 @@ func TestUpdateStats(t *testing.T) {
 					RxBytes:                     1000,
 					TxPackets:                   130,
 					TxBytes:                     2000,
+					RxBytesSsh:                  600,
+					TxBytesSsh:                  1500,
+					RxBytesPortForward:          400,
+					TxBytesPortForward:          500,
 					SessionCountVscode:          1,
 					SessionCountJetbrains:       2,
 					SessionCountReconnectingPty: 3,
@@ -147,10 +152,23 @@ func TestUpdateStats(t *testing.T) {
 		}
 		defer wut.Close()
 
+		// Egress is accumulated per workspace and day.
+		dbM.EXPECT().UpsertWorkspaceEgressDaily(gomock.Any(), database.UpsertWorkspaceEgressDailyParams{
+			WorkspaceID:        workspace.ID,
+			TemplateID:         template.ID,
+			OwnerID:            user.ID,
+			Date:               now.UTC().Truncate(24 * time.Hour),
+			RxBytesSsh:         600,
+			TxBytesSsh:         1500,
+			RxBytesPortForward: 400,
+			TxBytesPortForward: 500,
+		}).Return(nil)
+
 		// We expect an activity bump because ConnectionCount > 0.
 		dbM.EXPECT().ActivityBumpWorkspace(gomock.Any(), database.ActivityBumpWorkspaceParams{
-			WorkspaceID:   workspace.ID,
-			NextAutostart: time.Time{}.UTC(),
+			WorkspaceID:     workspace.ID,
+			NextAutostart:   time.Time{}.UTC(),
+			ConnectionTypes: []string{"ssh", "vscode", "jetbrains", "reconnecting_pty"},
 		}).Return(nil)
 
 		// Workspace last used at gets bumped.
@@ -286,6 +304,48 @@ func TestUpdateStats(t *testing.T) {
 		}, resp)
 	})
 
+	t.Run("ClockSkew", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			now = dbtime.Now()
+			dbM = dbmock.NewMockStore(gomock.NewController(t))
+		)
+		api := agentapi.StatsAPI{
+			AgentID:   agent.ID,
+			AgentName: agent.Name,
+			Workspace: &workspaceAsCacheFields,
+			Database:  dbM,
+			StatsReporter: workspacestats.NewReporter(workspacestats.ReporterOptions{
+				Database: dbM,
+				Pubsub:   pubsub.NewInMemory(),
+			}),
+			AgentStatsRefreshInterval: 10 * time.Second,
+			TimeNowFn: func() time.Time {
+				return now
+			},
+		}
+		expectSkew := func(skew time.Duration) {
+			dbM.EXPECT().UpdateWorkspaceAgentClockSkewByID(gomock.Any(), database.UpdateWorkspaceAgentClockSkewByIDParams{
+				ID:          agent.ID,
+				ClockSkewMs: sql.NullInt64{Int64: skew.Milliseconds(), Valid: true},
+			}).Return(nil).Times(1)
+		}
+		report := func(sentAt time.Time) {
+			_, err := api.UpdateStats(context.Background(), &agentproto.UpdateStatsRequest{
+				SentAt: timestamppb.New(sentAt),
+			})
+			require.NoError(t, err)
+		}
+
+		expectSkew(90 * time.Second)
+		report(now.Add(90 * time.Second))
+		// Small changes of the skew aren't written.
+		report(now.Add(90*time.Second + 300*time.Millisecond))
+		expectSkew(-2 * time.Second)
+		report(now.Add(-2 * time.Second))
+	})
+
 	t.Run("AutostartAwareBump", func(t *testing.T) {
 		t.Parallel()
 
@@ -375,8 +435,9 @@ func TestUpdateStats(t *testing.T) {
 		// We expect an activity bump because ConnectionCount > 0. However, the
 		// next autostart time will be set on the bump.
 		dbM.EXPECT().ActivityBumpWorkspace(gomock.Any(), database.ActivityBumpWorkspaceParams{
-			WorkspaceID:   workspace.ID,
-			NextAutostart: nextAutostart,
+			WorkspaceID:     workspace.ID,
+			NextAutostart:   nextAutostart,
+			ConnectionTypes: []string{},
 		}).Return(nil)
 
 		// Workspace last used at gets bumped.
@@ -487,8 +548,9 @@ func TestUpdateStats(t *testing.T) {
 
 		// We expect an activity bump because ConnectionCount > 0.
 		dbM.EXPECT().ActivityBumpWorkspace(gomock.Any(), database.ActivityBumpWorkspaceParams{
-			WorkspaceID:   workspace.ID,
-			NextAutostart: time.Time{}.UTC(),
+			WorkspaceID:     workspace.ID,
+			NextAutostart:   time.Time{}.UTC(),
+			ConnectionTypes: []string{},
 		}).Return(nil)
 
 		// Workspace last used at gets bumped.
@@ -622,8 +684,9 @@ func TestUpdateStats(t *testing.T) {
 
 		// We expect an activity bump because ConnectionCount > 0.
 		dbM.EXPECT().ActivityBumpWorkspace(gomock.Any(), database.ActivityBumpWorkspaceParams{
-			WorkspaceID:   workspace.ID,
-			NextAutostart: time.Time{}.UTC(),
+			WorkspaceID:     workspace.ID,
+			NextAutostart:   time.Time{}.UTC(),
+			ConnectionTypes: []string{"ssh", "vscode", "jetbrains", "reconnecting_pty"},
 		}).Return(nil)
 
 		// Workspace last used at gets bumped.
@@ -0,0 +1,73 @@
+package agentapi
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+// WorkspaceTransitionAPI implements the v2.11 RequestWorkspaceTransition RPC,
+// which lets tooling inside a workspace stop or restart its own workspace
+// without a user API token. The owner of the workspace must have opted in.
+type WorkspaceTransitionAPI struct {
+	WorkspaceID uuid.UUID
+	Database    database.Store
+	Log         slog.Logger
+	// RequestWorkspaceTransitionFn queues a build of the workspace on behalf
+	// of its owner, with the build reason "self_requested". With restart, the
+	// workspace is started again once it stopped.
+	RequestWorkspaceTransitionFn func(ctx context.Context, ownerID, workspaceID uuid.UUID, restart bool) (uuid.UUID, error)
+}
+
+func (a *WorkspaceTransitionAPI) RequestWorkspaceTransition(ctx context.Context, req *agentproto.RequestWorkspaceTransitionRequest) (*agentproto.RequestWorkspaceTransitionResponse, error) {
+	var restart bool
+	switch req.GetTransition() {
+	case agentproto.RequestWorkspaceTransitionRequest_STOP:
+	case agentproto.RequestWorkspaceTransitionRequest_RESTART:
+		restart = true
+	default:
+		return nil, xerrors.Errorf("unknown workspace transition %q", req.GetTransition())
+	}
+	if a.RequestWorkspaceTransitionFn == nil {
+		return nil, xerrors.New("requesting workspace transitions is not supported")
+	}
+
+	// The owner is not cached, since claiming a prebuilt workspace changes
+	// it.
+	workspace, err := a.Database.GetWorkspaceByID(ctx, a.WorkspaceID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace: %w", err)
+	}
+	if workspace.IsPrebuild() {
+		return nil, xerrors.New("prebuilt workspaces cannot request their own builds")
+	}
+
+	// The agent cannot read the preferences of the owner of its workspace.
+	//nolint:gocritic // Only the preference of the owner of the workspace is read.
+	allowed, err := a.Database.GetUserWorkspaceSelfRequestedBuildsAllowed(dbauthz.AsSystemRestricted(ctx), workspace.OwnerID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace self-requested builds preference: %w", err)
+	}
+	if !allowed {
+		return nil, xerrors.New("the workspace owner does not allow workspaces to request their own builds")
+	}
+
+	buildID, err := a.RequestWorkspaceTransitionFn(ctx, workspace.OwnerID, workspace.ID, restart)
+	if err != nil {
+		return nil, xerrors.Errorf("request workspace transition: %w", err)
+	}
+	a.Log.Info(ctx, "workspace requested its own build",
+		slog.F("workspace_id", a.WorkspaceID),
+		slog.F("workspace_build_id", buildID),
+		slog.F("restart", restart))
+
+	return &agentproto.RequestWorkspaceTransitionResponse{
+		WorkspaceBuildId: buildID[:],
+	}, nil
+}
@@ -0,0 +1,92 @@
+package agentapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"cdr.dev/slog/v3/sloggers/slogtest"
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRequestWorkspaceTransition(t *testing.T) {
+	t.Parallel()
+
+	workspace := database.Workspace{
+		ID:      uuid.New(),
+		OwnerID: uuid.New(),
+	}
+
+	t.Run("Restart", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmock.NewMockStore(gomock.NewController(t))
+		db.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+		db.EXPECT().GetUserWorkspaceSelfRequestedBuildsAllowed(gomock.Any(), workspace.OwnerID).Return(true, nil)
+
+		buildID := uuid.New()
+		api := &agentapi.WorkspaceTransitionAPI{
+			WorkspaceID: workspace.ID,
+			Database:    db,
+			Log:         slogtest.Make(t, nil),
+			RequestWorkspaceTransitionFn: func(_ context.Context, ownerID, workspaceID uuid.UUID, restart bool) (uuid.UUID, error) {
+				require.Equal(t, workspace.OwnerID, ownerID)
+				require.Equal(t, workspace.ID, workspaceID)
+				require.True(t, restart)
+				return buildID, nil
+			},
+		}
+
+		resp, err := api.RequestWorkspaceTransition(ctx, &agentproto.RequestWorkspaceTransitionRequest{
+			Transition: agentproto.RequestWorkspaceTransitionRequest_RESTART,
+		})
+		require.NoError(t, err)
+		require.Equal(t, buildID[:], resp.GetWorkspaceBuildId())
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmock.NewMockStore(gomock.NewController(t))
+		db.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+		db.EXPECT().GetUserWorkspaceSelfRequestedBuildsAllowed(gomock.Any(), workspace.OwnerID).Return(false, nil)
+
+		api := &agentapi.WorkspaceTransitionAPI{
+			WorkspaceID: workspace.ID,
+			Database:    db,
+			Log:         slogtest.Make(t, nil),
+			RequestWorkspaceTransitionFn: func(context.Context, uuid.UUID, uuid.UUID, bool) (uuid.UUID, error) {
+				t.Fatal("unexpected workspace transition")
+				return uuid.Nil, nil
+			},
+		}
+
+		_, err := api.RequestWorkspaceTransition(ctx, &agentproto.RequestWorkspaceTransitionRequest{
+			Transition: agentproto.RequestWorkspaceTransitionRequest_STOP,
+		})
+		require.ErrorContains(t, err, "does not allow")
+	})
+
+	t.Run("UnspecifiedTransition", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		api := &agentapi.WorkspaceTransitionAPI{
+			WorkspaceID: workspace.ID,
+			Database:    dbmock.NewMockStore(gomock.NewController(t)),
+			Log:         slogtest.Make(t, nil),
+		}
+
+		_, err := api.RequestWorkspaceTransition(ctx, &agentproto.RequestWorkspaceTransitionRequest{})
+		require.ErrorContains(t, err, "unknown workspace transition")
+	})
+}
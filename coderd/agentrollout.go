@@ -0,0 +1,92 @@
+package coderd
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/agentrollout"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// agentRolloutChannel returns the channel the agents of a workspace are
+// served from, given the rollout channel of its template.
+func (api *API) agentRolloutChannel(templateChannel database.AgentRolloutChannel, workspaceID uuid.UUID) codersdk.AgentRolloutChannel {
+	return agentrollout.Channel(
+		templateChannel,
+		workspaceID,
+		api.DeploymentValues.AgentBetaBinariesDir.Value() != "",
+		api.DeploymentValues.AgentBetaRolloutPercent.Value(),
+	)
+}
+
+// The agent bootstrap script downloads the agent from here on every workspace
+// start, so agents move to a new channel the next time they restart. Stable
+// agents are redirected to the binaries bundled with the server.
+//
+// @Summary Get workspace agent binary
+// @ID get-workspace-agent-binary
+// @Security CoderSessionToken
+// @Produce application/octet-stream
+// @Tags Agents
+// @Param file path string true "Binary file name, e.g. coder-linux-amd64"
+// @Success 200
+// @Success 307
+// @Router /api/v2/workspaceagents/me/binary/{file} [get]
+func (api *API) workspaceAgentBinary(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	file := chi.URLParam(r, "file")
+	if !agentrollout.ValidBinaryName(file) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid agent binary name.",
+			Detail:  "Binary names look like \"coder-linux-amd64\".",
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// nolint:gocritic // Agents cannot read their template, but only need its rollout channel.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if api.agentRolloutChannel(template.AgentRolloutChannel, workspace.ID) == codersdk.AgentRolloutChannelBeta {
+		path := filepath.Join(api.DeploymentValues.AgentBetaBinariesDir.Value(), file)
+		_, err := os.Stat(path)
+		if err == nil {
+			rw.Header().Set(codersdk.AgentRolloutChannelHeader, string(codersdk.AgentRolloutChannelBeta))
+			http.ServeFile(rw, r, path)
+			return
+		}
+		api.Logger.Warn(ctx, "beta agent binary unavailable, serving stable agent",
+			slog.F("workspace_id", workspace.ID),
+			slog.F("file", file),
+			slog.Error(err),
+		)
+	}
+
+	rw.Header().Set(codersdk.AgentRolloutChannelHeader, string(codersdk.AgentRolloutChannelStable))
+	http.Redirect(rw, r, "/bin/"+file, http.StatusTemporaryRedirect)
+}
@@ -0,0 +1,53 @@
+// Package agentrollout assigns workspaces to agent rollout channels.
+//
+// Templates opt into a channel. Workspaces of templates on the beta channel
+// are bucketed by workspace ID, and only the buckets below the deployment's
+// rollout percentage are served the beta agent. Bucketing is stable, so
+// raising the percentage only ever moves workspaces from stable to beta.
+package agentrollout
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Buckets is the number of buckets workspaces are distributed across, so a
+// rollout percentage maps to whole buckets.
+const Buckets = 100
+
+// Bucket returns the rollout bucket of a workspace, in [0, Buckets).
+func Bucket(workspaceID uuid.UUID) int64 {
+	// The last 8 bytes of a v4 UUID are random apart from the variant bits,
+	// which are identical for every workspace.
+	return int64(binary.BigEndian.Uint64(workspaceID[8:]) % Buckets) // #nosec G115 - Buckets fits in an int64.
+}
+
+// Channel returns the channel agents of a workspace are served from. A
+// workspace only receives the beta agent when its template is on the beta
+// channel, beta binaries are available, and its bucket is within
+// betaPercent.
+func Channel(templateChannel database.AgentRolloutChannel, workspaceID uuid.UUID, betaAvailable bool, betaPercent int64) codersdk.AgentRolloutChannel {
+	if templateChannel != database.AgentRolloutChannelBeta || !betaAvailable {
+		return codersdk.AgentRolloutChannelStable
+	}
+	if Bucket(workspaceID) >= betaPercent {
+		return codersdk.AgentRolloutChannelStable
+	}
+	return codersdk.AgentRolloutChannelBeta
+}
+
+// ValidBinaryName reports whether name is the file name of an agent binary,
+// e.g. coder-linux-amd64 or coder-windows-amd64.exe. Names that could escape
+// the binaries directory are rejected.
+func ValidBinaryName(name string) bool {
+	if !strings.HasPrefix(name, "coder-") {
+		return false
+	}
+	return filepath.Base(name) == name && !strings.ContainsAny(name, `/\`) && !strings.Contains(name, "..")
+}
@@ -0,0 +1,64 @@
+package agentrollout_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/agentrollout"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestChannel(t *testing.T) {
+	t.Parallel()
+
+	// Bucket 37.
+	workspaceID := uuid.MustParse("00000000-0000-4000-8000-00000000001d")
+	require.EqualValues(t, 37, agentrollout.Bucket(workspaceID))
+
+	for _, tc := range []struct {
+		name            string
+		templateChannel database.AgentRolloutChannel
+		betaAvailable   bool
+		betaPercent     int64
+		expected        codersdk.AgentRolloutChannel
+	}{
+		{"Stable", database.AgentRolloutChannelStable, true, 100, codersdk.AgentRolloutChannelStable},
+		{"Beta", database.AgentRolloutChannelBeta, true, 100, codersdk.AgentRolloutChannelBeta},
+		{"BetaUnavailable", database.AgentRolloutChannelBeta, false, 100, codersdk.AgentRolloutChannelStable},
+		{"BetaOutsidePercent", database.AgentRolloutChannelBeta, true, 37, codersdk.AgentRolloutChannelStable},
+		{"BetaWithinPercent", database.AgentRolloutChannelBeta, true, 38, codersdk.AgentRolloutChannelBeta},
+		{"BetaZeroPercent", database.AgentRolloutChannelBeta, true, 0, codersdk.AgentRolloutChannelStable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, agentrollout.Channel(tc.templateChannel, workspaceID, tc.betaAvailable, tc.betaPercent))
+		})
+	}
+}
+
+func TestBucketDistribution(t *testing.T) {
+	t.Parallel()
+
+	var beta int
+	for range 10000 {
+		if agentrollout.Bucket(uuid.New()) < 25 {
+			beta++
+		}
+	}
+	// 25% of 10000, with plenty of room for randomness.
+	require.InDelta(t, 2500, beta, 300)
+}
+
+func TestValidBinaryName(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, agentrollout.ValidBinaryName("coder-linux-amd64"))
+	require.True(t, agentrollout.ValidBinaryName("coder-windows-amd64.exe"))
+	require.False(t, agentrollout.ValidBinaryName("coder"))
+	require.False(t, agentrollout.ValidBinaryName("coder-../../etc/passwd"))
+	require.False(t, agentrollout.ValidBinaryName(`coder-linux\amd64`))
+	require.False(t, agentrollout.ValidBinaryName("other-linux-amd64"))
+}
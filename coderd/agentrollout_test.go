@@ -0,0 +1,95 @@
+package coderd_test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentBinary(t *testing.T) {
+	t.Parallel()
+
+	betaDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(betaDir, "coder-linux-amd64"), []byte("beta agent"), 0o600))
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		DeploymentValues: coderdtest.DeploymentValues(t, func(dv *codersdk.DeploymentValues) {
+			dv.AgentBetaBinariesDir = betaDir
+			dv.AgentBetaRolloutPercent = 100
+		}),
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionGraph: echo.ProvisionGraphWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	require.Equal(t, codersdk.AgentRolloutChannelStable, template.AgentRolloutChannel)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	httpClient := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	download := func(file string) *http.Response {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.URL.JoinPath("/api/v2/workspaceagents/me/binary", file).String(), nil)
+		require.NoError(t, err)
+		req.Header.Set(codersdk.SessionTokenHeader, authToken)
+		res, err := httpClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = res.Body.Close() })
+		return res
+	}
+	agentChannel := func() codersdk.AgentRolloutChannel {
+		ws, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		return ws.LatestBuild.Resources[0].Agents[0].RolloutChannel
+	}
+
+	// Stable agents are redirected to the binaries bundled with the server.
+	res := download("coder-linux-amd64")
+	require.Equal(t, http.StatusTemporaryRedirect, res.StatusCode)
+	require.Equal(t, "/bin/coder-linux-amd64", res.Header.Get("Location"))
+	require.Equal(t, string(codersdk.AgentRolloutChannelStable), res.Header.Get(codersdk.AgentRolloutChannelHeader))
+	require.Equal(t, codersdk.AgentRolloutChannelStable, agentChannel())
+
+	template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		AgentRolloutChannel: ptr.Ref(codersdk.AgentRolloutChannelBeta),
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.AgentRolloutChannelBeta, template.AgentRolloutChannel)
+
+	res = download("coder-linux-amd64")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, string(codersdk.AgentRolloutChannelBeta), res.Header.Get(codersdk.AgentRolloutChannelHeader))
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "beta agent", string(body))
+	require.Equal(t, codersdk.AgentRolloutChannelBeta, agentChannel())
+
+	// Binaries missing from the beta directory fall back to stable.
+	res = download("coder-darwin-arm64")
+	require.Equal(t, http.StatusTemporaryRedirect, res.StatusCode)
+	require.Equal(t, string(codersdk.AgentRolloutChannelStable), res.Header.Get(codersdk.AgentRolloutChannelHeader))
+
+	res = download("sshd")
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
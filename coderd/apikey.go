@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -65,10 +66,32 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Workspace restricted tokens derive their scopes and allow list from
+	// the workspace, so they cannot be combined with explicit ones.
+	if createToken.WorkspaceID != uuid.Nil &&
+		(len(createToken.Scopes) > 0 || createToken.Scope != "" || len(createToken.AllowList) > 0 || len(createToken.TemplateIDs) > 0) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to create API key.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "workspace_id",
+				Detail: "cannot be combined with scopes, allow_list, or template_ids",
+			}},
+		})
+		return
+	}
+
 	// Map and validate requested scope.
 	// Accept legacy special scopes (all, application_connect) and external scopes.
-	// Default to coder:all scopes for backward compatibility.
+	// Default to coder:all scopes for backward compatibility. Template
+	// restricted tokens default to the narrower workspace creation scope,
+	// since they are intended for automation against specific templates.
 	scopes := database.APIKeyScopes{database.ApiKeyScopeCoderAll}
+	if len(createToken.TemplateIDs) > 0 {
+		scopes = database.APIKeyScopes{database.ApiKeyScopeCoderWorkspacescreate}
+	}
+	if createToken.WorkspaceID != uuid.Nil {
+		scopes = workspaceTokenScopes
+	}
 	if len(createToken.Scopes) > 0 {
 		scopes = make(database.APIKeyScopes, 0, len(createToken.Scopes))
 		for _, s := range createToken.Scopes {
@@ -146,6 +169,22 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		params.AllowList = dbAllowList
 	}
 
+	if len(createToken.TemplateIDs) > 0 {
+		templateIDs, ok := api.validateTokenTemplateIDs(ctx, rw, createToken.TemplateIDs)
+		if !ok {
+			return
+		}
+		params.TemplateIDs = templateIDs
+	}
+
+	if createToken.WorkspaceID != uuid.Nil {
+		allowList, ok := api.workspaceTokenAllowList(ctx, rw, r, user, createToken.WorkspaceID)
+		if !ok {
+			return
+		}
+		params.AllowList = allowList
+	}
+
 	if createToken.Lifetime != 0 {
 		err := api.validateAPIKeyLifetime(ctx, user.ID, createToken.Lifetime)
 		if err != nil {
@@ -181,6 +220,96 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.GenerateAPIKeyResponse{Key: cookie.Value})
 }
 
+// validateTokenTemplateIDs deduplicates the requested template restrictions
+// and ensures each template exists and is visible to the caller. Templates
+// the caller cannot read are reported as not found so that template IDs
+// cannot be probed through token creation.
+func (api *API) validateTokenTemplateIDs(ctx context.Context, rw http.ResponseWriter, ids []uuid.UUID) ([]uuid.UUID, bool) {
+	templateIDs := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if slices.Contains(templateIDs, id) {
+			continue
+		}
+		_, err := api.Database.GetTemplateByID(ctx, id)
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Failed to create API key.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "template_ids",
+					Detail: fmt.Sprintf("template %q not found", id),
+				}},
+			})
+			return nil, false
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return nil, false
+		}
+		templateIDs = append(templateIDs, id)
+	}
+	return templateIDs, true
+}
+
+// workspaceTokenScopes are the scopes of workspace restricted tokens. Together
+// with the allow list from workspaceTokenAllowList they permit reading,
+// building, and connecting to a single workspace.
+var workspaceTokenScopes = database.APIKeyScopes{
+	database.ApiKeyScopeCoderWorkspacesoperate,
+	database.ApiKeyScopeCoderWorkspacesaccess,
+	database.ApiKeyScopeUserRead,
+}
+
+// workspaceTokenAllowList returns the allow list of a token restricted to the
+// given workspace. Besides the workspace itself, it admits the workspace's
+// template and owner, which are read when building or looking the workspace
+// up by name. Only the owner of a workspace may create a token for it.
+func (api *API) workspaceTokenAllowList(ctx context.Context, rw http.ResponseWriter, r *http.Request, user database.User, workspaceID uuid.UUID) (database.AllowList, bool) {
+	workspace, err := api.Database.GetWorkspaceByID(ctx, workspaceID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to create API key.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "workspace_id",
+				Detail: fmt.Sprintf("workspace %q not found", workspaceID),
+			}},
+		})
+		return nil, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	if workspace.OwnerID != user.ID || httpmw.APIKey(r).UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only the owner of a workspace can create a token restricted to it.",
+		})
+		return nil, false
+	}
+
+	allowList, err := rbac.NormalizeAllowList([]rbac.AllowListElement{
+		{Type: rbac.ResourceWorkspace.Type, ID: workspace.ID.String()},
+		{Type: rbac.ResourceTemplate.Type, ID: workspace.TemplateID.String()},
+		{Type: rbac.ResourceUser.Type, ID: workspace.OwnerID.String()},
+		// Organization members use the user ID as their ID, so this admits
+		// the owner's memberships only.
+		{Type: rbac.ResourceOrganizationMember.Type, ID: workspace.OwnerID.String()},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error building allow list.",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	return database.AllowList(allowList), true
+}
+
 // Creates a new session key, used for logging in via the CLI.
 //
 // @Summary Create new session key
@@ -17,6 +17,10 @@ import (
 	"github.com/coder/coder/v2/cryptorand"
 )
 
+// ImpersonationTokenNamePrefix prefixes the names of the tokens issued to
+// impersonate a user. The rest of the name is the ID of the impersonation.
+const ImpersonationTokenNamePrefix = "impersonation_"
+
 type CreateParams struct {
 	UserID    uuid.UUID
 	LoginType database.LoginType
@@ -39,6 +43,9 @@ type CreateParams struct {
 	// AllowList is an optional, normalized allow-list
 	// of resource type and uuid entries. If empty, defaults to wildcard.
 	AllowList database.AllowList
+	// TemplateIDs optionally restricts workspace operations to workspaces
+	// built from these templates. If empty, the key is unrestricted.
+	TemplateIDs []uuid.UUID
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -131,6 +138,7 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 		Scopes:       scopes,
 		AllowList:    params.AllowList,
 		TokenName:    params.TokenName,
+		TemplateIDs:  params.TemplateIDs,
 	}, token, nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -130,6 +131,226 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, "*:*", keys[0].AllowList[0].String())
 }
 
+func TestTokenTemplateRestricted(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	allowed := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	denied := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	allowedWorkspace := coderdtest.CreateWorkspace(t, client, allowed.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, allowedWorkspace.LatestBuild.ID)
+	deniedWorkspace := coderdtest.CreateWorkspace(t, client, denied.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, deniedWorkspace.LatestBuild.ID)
+
+	res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		TemplateIDs: []uuid.UUID{allowed.ID},
+	})
+	require.NoError(t, err)
+
+	keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, []uuid.UUID{allowed.ID}, keys[0].TemplateIDs)
+	require.Equal(t, []codersdk.APIKeyScope{"coder:workspaces.create"}, keys[0].Scopes)
+
+	tokenClient := codersdk.New(client.URL)
+	tokenClient.SetSessionToken(res.Key)
+
+	t.Run("Workspace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := tokenClient.Workspace(ctx, allowedWorkspace.ID)
+		require.NoError(t, err)
+
+		_, err = tokenClient.Workspace(ctx, deniedWorkspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("BuildByNumber", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := tokenClient.WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(ctx, codersdk.Me, allowedWorkspace.Name, "1")
+		require.NoError(t, err)
+
+		_, err = tokenClient.WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(ctx, codersdk.Me, deniedWorkspace.Name, "1")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := tokenClient.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.Workspaces)
+		// The Create subtest may add workspaces concurrently, so only
+		// assert that nothing from the denied template is returned.
+		for _, ws := range res.Workspaces {
+			require.Equal(t, allowed.ID, ws.TemplateID)
+		}
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := tokenClient.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: denied.ID,
+			Name:       coderdtest.RandomUsername(t),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = tokenClient.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: allowed.ID,
+			Name:       coderdtest.RandomUsername(t),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("UnknownTemplate", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TemplateIDs: []uuid.UUID{uuid.New()},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestTokenWorkspaceRestricted(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	allowed := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, allowed.LatestBuild.ID)
+	denied := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, denied.LatestBuild.ID)
+
+	res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		WorkspaceID: allowed.ID,
+	})
+	require.NoError(t, err)
+
+	keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.ElementsMatch(t, []codersdk.APIKeyScope{"coder:workspaces.operate", "coder:workspaces.access", "user:read"}, keys[0].Scopes)
+	require.Contains(t, keys[0].AllowList, codersdk.APIAllowListTarget{Type: codersdk.ResourceWorkspace, ID: allowed.ID.String()})
+
+	tokenClient := codersdk.New(client.URL)
+	tokenClient.SetSessionToken(res.Key)
+
+	t.Run("Workspace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := tokenClient.Workspace(ctx, allowed.ID)
+		require.NoError(t, err)
+		_, err = tokenClient.WorkspaceByOwnerAndName(ctx, codersdk.Me, allowed.Name, codersdk.WorkspaceOptions{})
+		require.NoError(t, err)
+
+		_, err = tokenClient.Workspace(ctx, denied.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Stopping the workspace would race with the other subtests, so this
+		// uses a workspace and token of its own.
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			WorkspaceID: workspace.ID,
+		})
+		require.NoError(t, err)
+		buildClient := codersdk.New(client.URL)
+		buildClient.SetSessionToken(res.Key)
+
+		_, err = buildClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+
+		_, err = buildClient.CreateWorkspaceBuild(ctx, denied.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("OtherResources", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := tokenClient.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+		require.Error(t, err)
+	})
+
+	t.Run("NotOwner", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		memberWorkspace := coderdtest.CreateWorkspace(t, memberClient, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, memberWorkspace.LatestBuild.ID)
+
+		// Admins cannot mint a workspace token on behalf of its owner.
+		_, err := client.CreateToken(ctx, member.ID.String(), codersdk.CreateTokenRequest{
+			WorkspaceID: memberWorkspace.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Members cannot mint a token for a workspace they do not own.
+		_, err = memberClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			WorkspaceID: allowed.ID,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("CombinedWithScopes", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			WorkspaceID: allowed.ID,
+			Scopes:      []codersdk.APIKeyScope{"coder:all"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
 // Ensure backward-compat: when a token is created using the legacy singular
 // scope names ("all" or "application_connect"), the API returns the same
 // legacy value in the deprecated singular Scope field while also supporting
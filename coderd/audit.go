@@ -183,6 +183,7 @@ func (api *API) generateFakeAuditLog(rw http.ResponseWriter, r *http.Request) {
 		RequestID:        params.RequestID,
 		ResourceIcon:     "",
 		OrganizationID:   params.OrganizationID,
+		CorrelationID:    uuid.NullUUID{UUID: params.CorrelationID, Valid: params.CorrelationID != uuid.Nil},
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -280,6 +281,10 @@ func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogs
 		IsDeleted:        isDeleted,
 	}
 
+	if dblog.AuditLog.CorrelationID.Valid {
+		alog.CorrelationID = &dblog.AuditLog.CorrelationID.UUID
+	}
+
 	if dblog.AuditLog.OrganizationID != uuid.Nil {
 		alog.Organization = &codersdk.MinimalOrganization{
 			ID:          dblog.AuditLog.OrganizationID,
@@ -513,6 +518,24 @@ func (api *API) auditLogIsResourceDeleted(ctx context.Context, alog database.Get
 			api.Logger.Error(ctx, "unable to fetch user secret", slog.Error(err))
 		}
 		return false
+	case database.ResourceTypeTemplateSecret:
+		_, err := api.Database.GetTemplateSecretByID(ctx, alog.AuditLog.ResourceID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return true
+		}
+		if err != nil && !dbauthz.IsNotAuthorizedError(err) {
+			api.Logger.Error(ctx, "unable to fetch template secret", slog.Error(err))
+		}
+		return false
+	case database.ResourceTypeVCSEventMapping:
+		_, err := api.Database.GetVCSEventMappingByID(ctx, alog.AuditLog.ResourceID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return true
+		}
+		if err != nil && !dbauthz.IsNotAuthorizedError(err) {
+			api.Logger.Error(ctx, "unable to fetch vcs event mapping", slog.Error(err))
+		}
+		return false
 	default:
 		return false
 	}
@@ -579,6 +602,39 @@ func (api *API) auditLogResourceLink(ctx context.Context, alog database.GetAudit
 		}
 		return fmt.Sprintf("/@%s/%s", workspace.OwnerName, workspace.Name)
 
+	case database.ResourceTypeWorkspaceAppShareLink:
+		link, err := api.Database.GetWorkspaceAppShareLinkByID(ctx, alog.AuditLog.ResourceID)
+		if err != nil {
+			return ""
+		}
+		workspace, err := api.Database.GetWorkspaceByID(ctx, link.WorkspaceID)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("/@%s/%s", workspace.OwnerName, workspace.Name)
+
+	case database.ResourceTypeTemplateSecret:
+		secret, err := api.Database.GetTemplateSecretByID(ctx, alog.AuditLog.ResourceID)
+		if err != nil {
+			return ""
+		}
+		template, err := api.Database.GetTemplateByID(ctx, secret.TemplateID)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("/templates/%s", template.Name)
+
+	case database.ResourceTypeVCSEventMapping:
+		mapping, err := api.Database.GetVCSEventMappingByID(ctx, alog.AuditLog.ResourceID)
+		if err != nil {
+			return ""
+		}
+		template, err := api.Database.GetTemplateByID(ctx, mapping.TemplateID)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("/templates/%s", template.Name)
+
 	case database.ResourceTypeOauth2ProviderApp:
 		return fmt.Sprintf("/deployment/oauth2-provider/apps/%s", alog.AuditLog.ResourceID)
 
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"slices"
+	"strings"
+)
+
+// ACLSubjectType is the type of subject whose role in an access control list
+// changed.
+type ACLSubjectType string
+
+const (
+	ACLSubjectUser  ACLSubjectType = "user"
+	ACLSubjectGroup ACLSubjectType = "group"
+)
+
+// ACLChange is the change of a single subject's role in the access control
+// list of a template or workspace. OldRole is empty if the subject gained
+// access, and NewRole is empty if it lost access.
+type ACLChange struct {
+	SubjectType ACLSubjectType `json:"subject_type"`
+	SubjectID   string         `json:"subject_id"`
+	OldRole     string         `json:"old_role"`
+	NewRole     string         `json:"new_role"`
+}
+
+// ACLAdditionalFields are the additional fields of audit logs for access
+// control list updates. They record the permission changes in a structured
+// form, so access reviews do not have to compare raw ACL diffs.
+type ACLAdditionalFields struct {
+	ACLChanges []ACLChange `json:"acl_changes"`
+}
+
+// DiffACL returns the role changes between two access control lists, given as
+// maps of subject IDs to role names. Subjects without a role must be omitted
+// or map to an empty role. Changes are sorted by subject ID.
+func DiffACL(subjectType ACLSubjectType, oldRoles, newRoles map[string]string) []ACLChange {
+	changes := []ACLChange{}
+	for id, newRole := range newRoles {
+		if oldRole := oldRoles[id]; oldRole != newRole {
+			changes = append(changes, ACLChange{
+				SubjectType: subjectType,
+				SubjectID:   id,
+				OldRole:     oldRole,
+				NewRole:     newRole,
+			})
+		}
+	}
+	for id, oldRole := range oldRoles {
+		if _, ok := newRoles[id]; ok || oldRole == "" {
+			continue
+		}
+		changes = append(changes, ACLChange{
+			SubjectType: subjectType,
+			SubjectID:   id,
+			OldRole:     oldRole,
+		})
+	}
+	slices.SortFunc(changes, func(a, b ACLChange) int {
+		return strings.Compare(a.SubjectID, b.SubjectID)
+	})
+	return changes
+}
@@ -0,0 +1,31 @@
+package audit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+)
+
+func TestDiffACL(t *testing.T) {
+	t.Parallel()
+
+	changes := audit.DiffACL(audit.ACLSubjectUser, map[string]string{
+		"a": "use",
+		"b": "admin",
+		"c": "use",
+	}, map[string]string{
+		"a": "use",
+		"b": "use",
+		"d": "admin",
+		"e": "",
+	})
+	require.Equal(t, []audit.ACLChange{
+		{SubjectType: audit.ACLSubjectUser, SubjectID: "b", OldRole: "admin", NewRole: "use"},
+		{SubjectType: audit.ACLSubjectUser, SubjectID: "c", OldRole: "use"},
+		{SubjectType: audit.ACLSubjectUser, SubjectID: "d", NewRole: "admin"},
+	}, changes)
+
+	require.Empty(t, audit.DiffACL(audit.ACLSubjectGroup, nil, nil))
+}
@@ -21,6 +21,7 @@ type Auditable interface {
 		database.WorkspaceProxy |
 		database.AuditOAuthConvertState |
 		database.HealthSettings |
+		database.DeploymentBundle |
 		database.NotificationsSettings |
 		database.OAuth2ProviderApp |
 		database.OAuth2ProviderAppSecret |
@@ -41,7 +42,10 @@ type Auditable interface {
 		database.AuditableGroupAIBudget |
 		database.AuditableUserAIBudgetOverride |
 		database.UserSecret |
-		database.UserSkill
+		database.UserSkill |
+		database.WorkspaceAppShareLink |
+		database.TemplateSecret |
+		database.VCSEventMapping
 }
 
 // Map is a map of changed fields in an audited resource. It maps field names to
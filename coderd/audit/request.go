@@ -49,6 +49,10 @@ type Request[T Auditable] struct {
 	// overridden such as in the case of new user authentication when the Audit
 	// Action is 'register', not 'login'.
 	Action database.AuditAction
+
+	// CorrelationID is an optional field that ties the audit log to the
+	// workspace build that caused it.
+	CorrelationID uuid.UUID
 }
 
 // UpdateOrganizationID can be used if the organization ID is not known
@@ -57,6 +61,12 @@ func (r *Request[T]) UpdateOrganizationID(id uuid.UUID) {
 	r.params.OrganizationID = id
 }
 
+// SetAdditionalFields replaces the additional fields of the audit log. It can
+// be used if the fields are only known once the request has been handled.
+func (r *Request[T]) SetAdditionalFields(fields interface{}) {
+	r.params.AdditionalFields = fields
+}
+
 type BackgroundAuditParams[T Auditable] struct {
 	Audit Auditor
 	Log   slog.Logger
@@ -71,6 +81,14 @@ type BackgroundAuditParams[T Auditable] struct {
 	UserAgent      string
 	// todo: this should automatically marshal an interface{} instead of accepting a raw message.
 	AdditionalFields json.RawMessage
+	// CorrelationID is the workspace build the audit log relates to, if any.
+	CorrelationID uuid.UUID
+	// ImpersonationID and ImpersonatorID identify the impersonation the
+	// audited action was taken under, if any. Background audits of requests
+	// made with an impersonation token pick it up from the request context
+	// instead.
+	ImpersonationID uuid.UUID
+	ImpersonatorID  uuid.UUID
 
 	New T
 	Old T
@@ -108,6 +126,8 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return string(typed.ToLoginType)
 	case database.HealthSettings:
 		return "" // no target?
+	case database.DeploymentBundle:
+		return typed.Filename
 	case database.NotificationsSettings:
 		return "" // no target?
 	case database.PrebuildsSettings:
@@ -156,6 +176,12 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return typed.Name
 	case database.UserSkill:
 		return typed.Name
+	case database.WorkspaceAppShareLink:
+		return typed.AppSlug
+	case database.TemplateSecret:
+		return typed.Name
+	case database.VCSEventMapping:
+		return typed.Name
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceTarget", tgt))
 	}
@@ -194,6 +220,9 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 	case database.HealthSettings:
 		// Artificial ID for auditing purposes
 		return typed.ID
+	case database.DeploymentBundle:
+		// Artificial ID for auditing purposes
+		return typed.ID
 	case database.NotificationsSettings:
 		// Artificial ID for auditing purposes
 		return typed.ID
@@ -238,6 +267,12 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 		return typed.ID
 	case database.UserSkill:
 		return typed.ID
+	case database.WorkspaceAppShareLink:
+		return typed.ID
+	case database.TemplateSecret:
+		return typed.ID
+	case database.VCSEventMapping:
+		return typed.ID
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceID", tgt))
 	}
@@ -269,6 +304,8 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeConvertLogin
 	case database.HealthSettings:
 		return database.ResourceTypeHealthSettings
+	case database.DeploymentBundle:
+		return database.ResourceTypeDeploymentBundle
 	case database.NotificationsSettings:
 		return database.ResourceTypeNotificationsSettings
 	case database.PrebuildsSettings:
@@ -311,6 +348,12 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeUserSecret
 	case database.UserSkill:
 		return database.ResourceTypeUserSkill
+	case database.WorkspaceAppShareLink:
+		return database.ResourceTypeWorkspaceAppShareLink
+	case database.TemplateSecret:
+		return database.ResourceTypeTemplateSecret
+	case database.VCSEventMapping:
+		return database.ResourceTypeVCSEventMapping
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceType", typed))
 	}
@@ -343,6 +386,9 @@ func ResourceRequiresOrgID[T Auditable]() bool {
 	case database.HealthSettings:
 		// Artificial ID for auditing purposes
 		return false
+	case database.DeploymentBundle:
+		// Bundles describe the whole deployment.
+		return false
 	case database.NotificationsSettings:
 		// Artificial ID for auditing purposes
 		return false
@@ -398,6 +444,15 @@ func ResourceRequiresOrgID[T Auditable]() bool {
 	case database.UserSkill:
 		// User skills are global to the user across organizations.
 		return false
+	case database.WorkspaceAppShareLink:
+		// Share links are org-scoped through their workspace.
+		return true
+	case database.TemplateSecret:
+		// Template secrets are org-scoped through their template.
+		return true
+	case database.VCSEventMapping:
+		// VCS event mappings are org-scoped through their template.
+		return true
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceRequiresOrgID", tgt))
 	}
@@ -493,6 +548,10 @@ func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request
 			}
 		}
 
+		if impersonation, ok := httpmw.UserImpersonationFromContext(logCtx); ok {
+			additionalFieldsRaw = withImpersonation(logCtx, p.Log, additionalFieldsRaw, impersonation.ID, impersonation.ImpersonatorID)
+		}
+
 		var userID uuid.UUID
 		key, ok := httpmw.APIKeyOptional(p.Request)
 		switch {
@@ -529,6 +588,7 @@ func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request
 			RequestID:        httpmw.RequestID(p.Request),
 			AdditionalFields: additionalFieldsRaw,
 			OrganizationID:   requireOrgID[T](logCtx, p.OrganizationID, p.Log),
+			CorrelationID:    correlationID(req.CorrelationID),
 		}
 		err := p.Audit.Export(ctx, auditLog)
 		if err != nil {
@@ -563,6 +623,12 @@ func BackgroundAudit[T Auditable](ctx context.Context, p *BackgroundAuditParams[
 	if p.AdditionalFields == nil {
 		p.AdditionalFields = json.RawMessage("{}")
 	}
+	if impersonation, ok := httpmw.UserImpersonationFromContext(ctx); ok {
+		p.ImpersonationID, p.ImpersonatorID = impersonation.ID, impersonation.ImpersonatorID
+	}
+	if p.ImpersonationID != uuid.Nil {
+		p.AdditionalFields = withImpersonation(ctx, p.Log, p.AdditionalFields, p.ImpersonationID, p.ImpersonatorID)
+	}
 
 	auditLog := database.AuditLog{
 		ID:             uuid.New(),
@@ -580,6 +646,7 @@ func BackgroundAudit[T Auditable](ctx context.Context, p *BackgroundAuditParams[
 		StatusCode:       int32(p.Status),
 		RequestID:        p.RequestID,
 		AdditionalFields: p.AdditionalFields,
+		CorrelationID:    correlationID(p.CorrelationID),
 	}
 	err = p.Audit.Export(ctx, auditLog)
 	if err != nil {
@@ -590,8 +657,38 @@ func BackgroundAudit[T Auditable](ctx context.Context, p *BackgroundAuditParams[
 	}
 }
 
+func correlationID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+// withImpersonation adds the impersonation an action was taken under to the
+// additional fields of its audit log, so that actions taken with an
+// impersonation token are attributed to the impersonator as well as the
+// impersonated user.
+func withImpersonation(ctx context.Context, log slog.Logger, fields json.RawMessage, impersonationID, impersonatorID uuid.UUID) json.RawMessage {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(fields, &all); err != nil {
+		log.Warn(ctx, "unmarshal additional fields", slog.Error(err))
+	}
+	if all == nil {
+		all = map[string]json.RawMessage{}
+	}
+	all["impersonation_id"] = json.RawMessage(strconv.Quote(impersonationID.String()))
+	all["impersonator_id"] = json.RawMessage(strconv.Quote(impersonatorID.String()))
+	data, err := json.Marshal(all)
+	if err != nil {
+		log.Warn(ctx, "marshal additional fields", slog.Error(err))
+		return fields
+	}
+	return data
+}
+
 type WorkspaceBuildBaggage struct {
 	IP string
+	// ImpersonationID and ImpersonatorID are set when the build was started
+	// with an impersonation token.
+	ImpersonationID uuid.UUID
+	ImpersonatorID  uuid.UUID
 }
 
 func (b WorkspaceBuildBaggage) Props() ([]baggage.Property, error) {
@@ -599,12 +696,35 @@ func (b WorkspaceBuildBaggage) Props() ([]baggage.Property, error) {
 	if err != nil {
 		return nil, xerrors.Errorf("create ip kv property: %w", err)
 	}
+	props := []baggage.Property{ipProp}
 
-	return []baggage.Property{ipProp}, nil
+	if b.ImpersonationID != uuid.Nil {
+		impersonationProp, err := baggage.NewKeyValueProperty("impersonation_id", b.ImpersonationID.String())
+		if err != nil {
+			return nil, xerrors.Errorf("create impersonation_id kv property: %w", err)
+		}
+		impersonatorProp, err := baggage.NewKeyValueProperty("impersonator_id", b.ImpersonatorID.String())
+		if err != nil {
+			return nil, xerrors.Errorf("create impersonator_id kv property: %w", err)
+		}
+		props = append(props, impersonationProp, impersonatorProp)
+	}
+
+	return props, nil
+}
+
+// WithImpersonation records the impersonation the request in ctx was made
+// under, if any.
+func (b WorkspaceBuildBaggage) WithImpersonation(ctx context.Context) WorkspaceBuildBaggage {
+	if impersonation, ok := httpmw.UserImpersonationFromContext(ctx); ok {
+		b.ImpersonationID = impersonation.ID
+		b.ImpersonatorID = impersonation.ImpersonatorID
+	}
+	return b
 }
 
 func WorkspaceBuildBaggageFromRequest(r *http.Request) WorkspaceBuildBaggage {
-	return WorkspaceBuildBaggage{IP: r.RemoteAddr}
+	return WorkspaceBuildBaggage{IP: r.RemoteAddr}.WithImpersonation(r.Context())
 }
 
 type Baggage interface {
@@ -638,6 +758,12 @@ func BaggageFromContext(ctx context.Context) WorkspaceBuildBaggage {
 		switch prop.Key() {
 		case "ip":
 			d.IP, _ = prop.Value()
+		case "impersonation_id":
+			value, _ := prop.Value()
+			d.ImpersonationID, _ = uuid.Parse(value)
+		case "impersonator_id":
+			value, _ := prop.Value()
+			d.ImpersonatorID, _ = uuid.Parse(value)
 		default:
 		}
 	}
@@ -278,6 +278,7 @@ func TestAuditLogsFilter(t *testing.T) {
 			Action:         codersdk.AuditActionStart,
 			ResourceType:   codersdk.ResourceTypeWorkspaceBuild,
 			ResourceID:     workspace.LatestBuild.ID,
+			CorrelationID:  workspace.LatestBuild.ID,
 			Time:           time.Date(2022, 8, 15, 14, 30, 45, 100, time.UTC), // 2022-8-15 14:30:45
 		})
 		require.NoError(t, err)
@@ -288,6 +289,7 @@ func TestAuditLogsFilter(t *testing.T) {
 			Action:         codersdk.AuditActionStop,
 			ResourceType:   codersdk.ResourceTypeWorkspaceBuild,
 			ResourceID:     workspace.LatestBuild.ID,
+			CorrelationID:  workspace.LatestBuild.ID,
 			Time:           time.Date(2022, 8, 15, 14, 30, 45, 100, time.UTC), // 2022-8-15 14:30:45
 		})
 		require.NoError(t, err)
@@ -452,6 +454,11 @@ func TestAuditLogsFilter(t *testing.T) {
 				SearchQuery:    "resource_type:workspace_app request_id:" + openRequestID.String(),
 				ExpectedResult: 2,
 			},
+			{
+				Name:           "FilterOnCorrelationID",
+				SearchQuery:    "correlation_id:" + workspace.LatestBuild.ID.String(),
+				ExpectedResult: 2,
+			},
 		}
 
 		for _, testCase := range testCases {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -52,19 +53,50 @@ type Executor struct {
 	reg                     prometheus.Registerer
 	experiments             codersdk.Experiments
 	workspaceBuilderMetrics *wsbuilder.Metrics
+	// workspaceCostBudget and userCostBudget are the monthly cost budgets of
+	// a single workspace and of all workspaces of a user. 0 disables them.
+	workspaceCostBudget int64
+	userCostBudget      int64
+	// dormancyHooks schedules a dormancy hook for every workspace marked
+	// dormant by the executor.
+	dormancyHooks bool
 
 	metrics executorMetrics
+
+	statusMu sync.Mutex
+	status   codersdk.LifecycleExecutorStatus
 }
 
 type executorMetrics struct {
 	autobuildExecutionDuration prometheus.Histogram
+	transitions                *prometheus.CounterVec
+	lastTick                   prometheus.Gauge
+	workspacesEvaluated        prometheus.Gauge
+	deferredWorkspaces         *prometheus.GaugeVec
 }
 
+// Metric label values for the status of a lifecycle transition.
+const (
+	transitionStatusSuccess = "success"
+	transitionStatusFailed  = "failed"
+	// transitionStatusSkipped is used when a transition was due but no
+	// provisioner was available to run it.
+	transitionStatusSkipped = "skipped"
+)
+
 // Stats contains information about one run of Executor.
 type Stats struct {
 	Transitions map[uuid.UUID]database.WorkspaceTransition
 	Elapsed     time.Duration
 	Errors      map[uuid.UUID]error
+	// Evaluated is the number of workspaces considered for a transition.
+	Evaluated int
+	// Attempted contains the reason of every transition the executor tried
+	// to run, keyed by workspace ID.
+	Attempted map[uuid.UUID]database.BuildReason
+	// Deferred contains the workspaces with a transition due that the
+	// executor could not run, keyed by workspace ID.
+	Deferred map[uuid.UUID]codersdk.LifecycleExecutorDeferReason
 }
 
 // New returns a new wsactions executor.
@@ -94,6 +126,30 @@ func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, fc *f
 				Help:      "Duration of each autobuild execution.",
 				Buckets:   prometheus.DefBuckets,
 			}),
+			transitions: factory.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "coderd",
+				Subsystem: "lifecycle",
+				Name:      "transitions_total",
+				Help:      "Total number of lifecycle transitions attempted by the executor, by organization, template, reason (autostart, autostop, dormancy, ...), and status.",
+			}, []string{"organization_name", "template_name", "reason", "status"}),
+			lastTick: factory.NewGauge(prometheus.GaugeOpts{
+				Namespace: "coderd",
+				Subsystem: "lifecycle",
+				Name:      "last_tick_timestamp_seconds",
+				Help:      "Unix timestamp of the last completed run of the executor.",
+			}),
+			workspacesEvaluated: factory.NewGauge(prometheus.GaugeOpts{
+				Namespace: "coderd",
+				Subsystem: "lifecycle",
+				Name:      "workspaces_evaluated",
+				Help:      "Number of workspaces evaluated for a transition in the last run of the executor.",
+			}),
+			deferredWorkspaces: factory.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: "coderd",
+				Subsystem: "lifecycle",
+				Name:      "deferred_workspaces",
+				Help:      "Number of workspaces with a transition due that the last run of the executor could not run, by reason.",
+			}, []string{"reason"}),
 		},
 	}
 	return le
@@ -106,6 +162,22 @@ func (e *Executor) WithStatsChannel(ch chan<- Stats) *Executor {
 	return e
 }
 
+// WithCostBudgets will cause Executor to stop running workspaces whose cost
+// this month exceeds workspaceBudget, or whose owner's workspaces together
+// exceed userBudget. A budget of 0 is disabled.
+func (e *Executor) WithCostBudgets(workspaceBudget, userBudget int64) *Executor {
+	e.workspaceCostBudget = workspaceBudget
+	e.userCostBudget = userBudget
+	return e
+}
+
+// WithDormancyHooks will cause Executor to schedule a dormancy hook for every
+// workspace it marks dormant.
+func (e *Executor) WithDormancyHooks(enabled bool) *Executor {
+	e.dormancyHooks = enabled
+	return e
+}
+
 // Run will cause executor to start or stop workspaces on every
 // tick from its channel. It will stop when its context is Done, or when
 // its channel is closed.
@@ -121,6 +193,7 @@ func (e *Executor) Run() {
 				}
 				stats := e.runOnce(t)
 				e.metrics.autobuildExecutionDuration.Observe(stats.Elapsed.Seconds())
+				e.recordStatus(t, stats)
 				if e.statsCh != nil {
 					select {
 					case <-ctx.Done():
@@ -171,6 +244,8 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	stats := Stats{
 		Transitions: make(map[uuid.UUID]database.WorkspaceTransition),
 		Errors:      make(map[uuid.UUID]error),
+		Attempted:   make(map[uuid.UUID]database.BuildReason),
+		Deferred:    make(map[uuid.UUID]codersdk.LifecycleExecutorDeferReason),
 	}
 	// we build the map of transitions concurrently, so need a mutex to serialize writes to the map
 	statsMu := sync.Mutex{}
@@ -195,6 +270,21 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		return stats
 	}
 
+	// Workspaces over a monthly cost budget must be stopped even when no
+	// other lifecycle action is due.
+	overBudget, err := e.costBudgetViolations(t)
+	if err != nil {
+		e.log.Error(e.ctx, "get workspaces over cost budget", slog.Error(err))
+	}
+	for id := range overBudget {
+		if !slices.ContainsFunc(workspaces, func(w database.GetWorkspacesEligibleForLifecycleActionRow) bool {
+			return w.ID == id
+		}) {
+			workspaces = append(workspaces, database.GetWorkspacesEligibleForLifecycleActionRow{ID: id})
+		}
+	}
+	stats.Evaluated = len(workspaces)
+
 	// Sort the workspaces by build template version ID so that we can group
 	// identical template versions together. This is a slight (and imperfect)
 	// optimization.
@@ -235,11 +325,15 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					shouldRemind          bool
 					reminderDeadline      time.Time
 					reminderBuildID       uuid.UUID
+					lifetimeWarning       *database.WorkspaceLifetimeWarning
 					nextBuild             *database.WorkspaceBuild
 					activeTemplateVersion database.TemplateVersion
 					ws                    database.Workspace
 					tmpl                  database.Template
 					didAutoUpdate         bool
+					transitionReason      database.BuildReason
+					deferReason           codersdk.LifecycleExecutorDeferReason
+					budgetViolation       *database.GetWorkspacesExceedingCostBudgetRow
 				)
 				err := e.db.InTx(func(tx database.Store) error {
 					var err error
@@ -315,6 +409,70 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						return xerrors.Errorf("get next transition: %w", err)
 					}
 
+					// Workspaces over a monthly cost budget are stopped, and are
+					// not autostarted again until the budget resets.
+					if violation, ok := overBudget[ws.ID]; ok {
+						switch {
+						case isEligibleForBudgetStop(latestBuild, latestJob):
+							nextTransition, reason = database.WorkspaceTransitionStop, database.BuildReasonBudgetExceeded
+							budgetViolation = &violation
+						case reason == database.BuildReasonAutostart:
+							log.Debug(e.ctx, "skipping autostart, workspace is over its monthly cost budget")
+							deferReason = codersdk.LifecycleExecutorDeferReasonCostBudgetExceeded
+							return nil
+						}
+					}
+
+					// The owner may have paused the schedules of their workspaces,
+					// for example while they are on leave.
+					switch reason {
+					case database.BuildReasonAutostart, database.BuildReasonAutostop, database.BuildReasonTaskAutoPause:
+						pause, err := tx.GetUserSchedulePause(e.ctx, ws.OwnerID)
+						if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+							return xerrors.Errorf("get user schedule pause: %w", err)
+						}
+						if err == nil && isPausedBySchedulePause(pause, user, ws, latestBuild, latestJob, templateSchedule, reason, currentTick) {
+							log.Debug(e.ctx, "skipping workspace, owner paused their schedules", slog.F("paused_until", pause.PausedUntil))
+							return nil
+						}
+					}
+
+					// Workspaces running expensive resources, such as GPUs, are
+					// stopped once they have been idle for longer than the
+					// template's idle reclaim policy allows, well before their
+					// regular autostop deadline.
+					if reason == "" && isEligibleForIdleReclaim(ws, latestBuild, latestJob, templateSchedule, currentTick) {
+						matched, err := matchesIdleReclaimResources(e.ctx, tx, latestBuild.JobID, templateSchedule.IdleReclaim)
+						if err != nil {
+							return xerrors.Errorf("match idle reclaim resources: %w", err)
+						}
+						if matched {
+							nextTransition, reason = database.WorkspaceTransitionStop, database.BuildReasonAutostop
+							// Use task-specific reason for AI task workspaces.
+							if ws.TaskID.Valid {
+								reason = database.BuildReasonTaskAutoPause
+							}
+							log.Info(e.ctx, "reclaiming idle workspace",
+								slog.F("last_used_at", ws.LastUsedAt),
+								slog.F("idle_reclaim_ttl", templateSchedule.IdleReclaim.TTL),
+								slog.F("resource_selector", templateSchedule.IdleReclaim.ResourceSelector),
+							)
+						}
+					}
+
+					// A template admin may have exempted the workspace from the
+					// template's dormancy policy.
+					if reason == database.BuildReasonDormancy {
+						exempt, err := hasDormancyExemption(e.ctx, tx, ws.ID, currentTick)
+						if err != nil {
+							return xerrors.Errorf("get dormancy exemption: %w", err)
+						}
+						if exempt {
+							log.Debug(e.ctx, "skipping workspace, exempt from dormancy")
+							return nil
+						}
+					}
+
 					// No transition is due. The workspace may still need a one-time
 					// autostop reminder; reuse the lock and transaction we already
 					// hold to stamp the marker.
@@ -335,6 +493,37 @@ func (e *Executor) runOnce(t time.Time) Stats {
 							reminderBuildID = latestBuild.ID
 							shouldRemind = true
 						}
+						// Warn the owner as the workspace approaches the
+						// template's max lifetime. The marker ensures every
+						// warning is sent at most once.
+						if expiresAt, ok := templateSchedule.MaxLifetime.ExpiresAt(ws.CreatedAt); ok && (templateSchedule.MaxLifetime.Action == database.MaxLifetimeActionDelete || latestBuild.Transition == database.WorkspaceTransitionStart) {
+							daysBefore, ok := maxLifetimeWarningDays(expiresAt, currentTick)
+							if !ok {
+								return nil
+							}
+							warning, err := tx.GetWorkspaceLifetimeWarningByWorkspaceID(e.ctx, ws.ID)
+							if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+								return xerrors.Errorf("get workspace lifetime warning: %w", err)
+							}
+							if err == nil && warning.ExpiresAt.Equal(expiresAt) && warning.DaysBefore <= int32(daysBefore) {
+								return nil
+							}
+							warning = database.WorkspaceLifetimeWarning{
+								WorkspaceID: ws.ID,
+								ExpiresAt:   expiresAt,
+								DaysBefore:  int32(daysBefore),
+								SentAt:      dbtime.Now(),
+							}
+							if err := tx.UpsertWorkspaceLifetimeWarning(e.ctx, database.UpsertWorkspaceLifetimeWarningParams{
+								WorkspaceID: warning.WorkspaceID,
+								ExpiresAt:   warning.ExpiresAt,
+								DaysBefore:  warning.DaysBefore,
+								SentAt:      warning.SentAt,
+							}); err != nil {
+								return xerrors.Errorf("stamp workspace lifetime warning marker: %w", err)
+							}
+							lifetimeWarning = &warning
+						}
 						return nil
 					}
 
@@ -353,8 +542,10 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					if err != nil {
 						return xerrors.Errorf("check provisioner availability: %w", err)
 					}
+					transitionReason = reason
 					if !hasProvisioners {
 						log.Warn(e.ctx, "skipping autostart - no available provisioners")
+						deferReason = codersdk.LifecycleExecutorDeferReasonNoProvisioners
 						return nil // Skip this workspace
 					}
 
@@ -398,6 +589,16 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						if err != nil {
 							return xerrors.Errorf("update workspace dormant deleting at: %w", err)
 						}
+						if e.dormancyHooks {
+							_, err = tx.UpsertWorkspaceDormancyHook(e.ctx, database.UpsertWorkspaceDormancyHookParams{
+								WorkspaceID: ws.ID,
+								Event:       database.WorkspaceDormancyHookEventDormant,
+								Now:         dbtime.Now(),
+							})
+							if err != nil {
+								return xerrors.Errorf("schedule workspace dormancy hook: %w", err)
+							}
+						}
 
 						auditLog = &auditParams{
 							Old: wsOld.WorkspaceTable(),
@@ -419,7 +620,16 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						)
 					}
 
-					if reason == database.BuildReasonAutodelete {
+					if reason == database.BuildReasonAutodelete && isEligibleForTrialExpiry(ws, latestBuild, latestJob, currentTick) {
+						log.Info(e.ctx, "deleted expired trial workspace",
+							slog.F("expires_at", ws.ExpiresAt.Time),
+						)
+					} else if reason == database.BuildReasonAutodelete && isEligibleForMaxLifetimeExpiry(ws, latestBuild, latestJob, templateSchedule, currentTick) {
+						log.Info(e.ctx, "deleted workspace past its max lifetime",
+							slog.F("created_at", ws.CreatedAt),
+							slog.F("max_lifetime", templateSchedule.MaxLifetime.Duration),
+						)
+					} else if reason == database.BuildReasonAutodelete {
 						log.Info(e.ctx, "deleted workspace",
 							slog.F("dormant_at", ws.DormantAt.Time),
 							slog.F("time_til_dormant_autodelete", templateSchedule.TimeTilDormantAutoDelete),
@@ -463,7 +673,28 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					// incorrect notifications.
 					didAutoUpdate = false
 					shouldNotifyTaskPause = false
+					budgetViolation = nil
+					transitionReason = ""
+					deferReason = ""
+				}
+				if transitionReason != "" {
+					status := transitionStatusSuccess
+					switch {
+					case err != nil:
+						status = transitionStatusFailed
+					case deferReason != "":
+						status = transitionStatusSkipped
+					}
+					e.metrics.transitions.WithLabelValues(ws.OrganizationName, ws.TemplateName, string(transitionReason), status).Inc()
+				}
+				statsMu.Lock()
+				switch {
+				case deferReason != "":
+					stats.Deferred[wsID] = deferReason
+				case transitionReason != "":
+					stats.Attempted[wsID] = transitionReason
 				}
+				statsMu.Unlock()
 				if auditLog != nil {
 					// If the transition didn't succeed then updating the workspace
 					// to indicate dormant didn't either.
@@ -557,6 +788,56 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						}
 					}
 				}
+				if budgetViolation != nil && nextBuild != nil {
+					kind, budget, cost := "user", e.userCostBudget, budgetViolation.UserCost
+					if e.workspaceCostBudget > 0 && budgetViolation.WorkspaceCost > float64(e.workspaceCostBudget) {
+						kind, budget, cost = "workspace", e.workspaceCostBudget, budgetViolation.WorkspaceCost
+					}
+					log.Info(e.ctx, "stopped workspace over monthly cost budget",
+						slog.F("budget_kind", kind),
+						slog.F("budget", budget),
+						slog.F("cost", cost),
+					)
+					if _, err := e.notificationsEnqueuer.Enqueue(
+						e.ctx,
+						ws.OwnerID,
+						notifications.TemplateWorkspaceBudgetExceeded,
+						map[string]string{
+							"workspace":   ws.Name,
+							"budget_kind": kind,
+							"budget":      strconv.FormatInt(budget, 10),
+							"cost":        strconv.FormatFloat(cost, 'f', 2, 64),
+						},
+						"lifecycle_executor",
+						// Associate this notification with all the related entities.
+						ws.ID, ws.OwnerID, ws.TemplateID, ws.OrganizationID,
+					); err != nil {
+						log.Warn(e.ctx, "failed to notify of workspace over cost budget", slog.Error(err))
+					}
+				}
+				if lifetimeWarning != nil {
+					action := "deleted"
+					if tmpl.MaxLifetimeAction == database.MaxLifetimeActionStop {
+						action = "stopped"
+					}
+					// At-most-once: the marker is already committed, so a failed
+					// enqueue only logs (no retry).
+					if _, err := e.notificationsEnqueuer.Enqueue(
+						e.ctx,
+						ws.OwnerID,
+						notifications.TemplateWorkspaceExpiring,
+						map[string]string{
+							"workspace":     ws.Name,
+							"action":        action,
+							"timeTilExpiry": humanize.Time(lifetimeWarning.ExpiresAt),
+						},
+						"lifecycle_executor",
+						// Associate this notification with all the related entities.
+						ws.ID, ws.OwnerID, ws.TemplateID, ws.OrganizationID,
+					); err != nil {
+						log.Warn(e.ctx, "failed to notify of workspace reaching its max lifetime", slog.F("days_before", lifetimeWarning.DaysBefore), slog.Error(err))
+					}
+				}
 				if shouldRemind {
 					// At-most-once: the marker is already committed, so a failed
 					// enqueue only logs (no retry).
@@ -598,6 +879,117 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	return stats
 }
 
+// Status returns the outcome of the most recent run of the executor.
+func (e *Executor) Status() codersdk.LifecycleExecutorStatus {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+	status := e.status
+	status.Failures = slices.Clone(e.status.Failures)
+	status.Deferred = slices.Clone(e.status.Deferred)
+	return status
+}
+
+// recordStatus stores the outcome of the run started at t, so that it can be
+// inspected through Status and the executor's metrics.
+func (e *Executor) recordStatus(t time.Time, stats Stats) {
+	status := codersdk.LifecycleExecutorStatus{
+		LastTickAt:           &t,
+		LastTickDurationMS:   stats.Elapsed.Milliseconds(),
+		WorkspacesEvaluated:  stats.Evaluated,
+		TransitionsAttempted: len(stats.Attempted),
+		Failures:             make([]codersdk.LifecycleExecutorFailure, 0, len(stats.Errors)),
+		Deferred:             make([]codersdk.LifecycleExecutorDeferral, 0, len(stats.Deferred)),
+	}
+	for id := range stats.Attempted {
+		if _, failed := stats.Errors[id]; failed {
+			status.TransitionsFailed++
+		} else {
+			status.TransitionsSucceeded++
+		}
+	}
+	for id, err := range stats.Errors {
+		status.Failures = append(status.Failures, codersdk.LifecycleExecutorFailure{
+			WorkspaceID: id,
+			BuildReason: codersdk.BuildReason(stats.Attempted[id]),
+			Error:       err.Error(),
+		})
+	}
+	deferred := make(map[codersdk.LifecycleExecutorDeferReason]int)
+	for id, reason := range stats.Deferred {
+		status.Deferred = append(status.Deferred, codersdk.LifecycleExecutorDeferral{
+			WorkspaceID: id,
+			Reason:      reason,
+		})
+		deferred[reason]++
+	}
+	slices.SortFunc(status.Failures, func(a, b codersdk.LifecycleExecutorFailure) int {
+		return strings.Compare(a.WorkspaceID.String(), b.WorkspaceID.String())
+	})
+	slices.SortFunc(status.Deferred, func(a, b codersdk.LifecycleExecutorDeferral) int {
+		return strings.Compare(a.WorkspaceID.String(), b.WorkspaceID.String())
+	})
+
+	e.metrics.lastTick.Set(float64(t.Unix()))
+	e.metrics.workspacesEvaluated.Set(float64(stats.Evaluated))
+	for _, reason := range codersdk.AllLifecycleExecutorDeferReasons {
+		e.metrics.deferredWorkspaces.WithLabelValues(string(reason)).Set(float64(deferred[reason]))
+	}
+
+	e.statusMu.Lock()
+	e.status = status
+	e.statusMu.Unlock()
+}
+
+// costBudgetViolations accrues the estimated cost of running workspaces for
+// the month of t and returns the workspaces over a monthly cost budget, keyed
+// by workspace ID. Nothing is accrued while both budgets are disabled, and
+// only the replica holding the accrual lock accrues on a given tick.
+func (e *Executor) costBudgetViolations(t time.Time) (map[uuid.UUID]database.GetWorkspacesExceedingCostBudgetRow, error) {
+	if e.workspaceCostBudget <= 0 && e.userCostBudget <= 0 {
+		return nil, nil
+	}
+
+	month := costMonth(t)
+	err := e.db.InTx(func(tx database.Store) error {
+		ok, err := tx.TryAcquireLock(e.ctx, database.GenLockID("lifecycle-executor:cost-accrual"))
+		if err != nil {
+			return xerrors.Errorf("try acquire cost accrual lock: %w", err)
+		}
+		if !ok {
+			e.log.Debug(e.ctx, "unable to acquire cost accrual lock, skipping")
+			return nil
+		}
+		return tx.AccrueWorkspaceEstimatedCosts(e.ctx, database.AccrueWorkspaceEstimatedCostsParams{
+			Now:   dbtime.Time(t),
+			Month: month,
+		})
+	}, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("accrue workspace estimated costs: %w", err)
+	}
+
+	rows, err := e.db.GetWorkspacesExceedingCostBudget(e.ctx, database.GetWorkspacesExceedingCostBudgetParams{
+		Month:           month,
+		WorkspaceBudget: e.workspaceCostBudget,
+		UserBudget:      e.userCostBudget,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get workspaces exceeding cost budget: %w", err)
+	}
+	violations := make(map[uuid.UUID]database.GetWorkspacesExceedingCostBudgetRow, len(rows))
+	for _, row := range rows {
+		violations[row.WorkspaceID] = row
+	}
+	return violations, nil
+}
+
+// costMonth returns the start of the UTC calendar month containing t, which
+// is the period workspace costs are accrued and budgeted in.
+func costMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
 // autostopReminderActiveThreshold is how recently a workspace must have been
 // used to count as "active" and suppress the autostop reminder. A default
 // deployment refreshes last_used_at within ~90s, but the agent stats interval
@@ -682,6 +1074,20 @@ func getNextTransition(
 	error,
 ) {
 	switch {
+	case isEligibleForTrialExpiry(ws, latestBuild, latestJob, currentTick):
+		// Expired trial workspaces are stopped first so that the delete
+		// build runs against a stopped workspace.
+		if latestBuild.Transition == database.WorkspaceTransitionStart {
+			return database.WorkspaceTransitionStop, database.BuildReasonAutostop, nil
+		}
+		return database.WorkspaceTransitionDelete, database.BuildReasonAutodelete, nil
+	case isEligibleForMaxLifetimeExpiry(ws, latestBuild, latestJob, templateSchedule, currentTick):
+		// As with trial workspaces, running workspaces are stopped before
+		// they are deleted.
+		if latestBuild.Transition == database.WorkspaceTransitionStart {
+			return database.WorkspaceTransitionStop, database.BuildReasonAutostop, nil
+		}
+		return database.WorkspaceTransitionDelete, database.BuildReasonAutodelete, nil
 	case isEligibleForAutostop(user, ws, latestBuild, latestJob, currentTick):
 		// Use task-specific reason for AI task workspaces.
 		if ws.TaskID.Valid {
@@ -730,6 +1136,11 @@ func isEligibleForAutostart(user database.User, ws database.Workspace, build dat
 		return false
 	}
 
+	// Workspaces past their max lifetime stay stopped.
+	if expiresAt, ok := templateSchedule.MaxLifetime.ExpiresAt(ws.CreatedAt); ok && !currentTick.Before(expiresAt) {
+		return false
+	}
+
 	// If the last transition for the workspace was not 'stop' then the workspace
 	// cannot be started.
 	if build.Transition != database.WorkspaceTransitionStop {
@@ -786,6 +1197,51 @@ func isEligibleForDormantStop(ws database.Workspace, templateSchedule schedule.T
 		currentTick.Sub(ws.LastUsedAt) > templateSchedule.TimeTilDormant
 }
 
+// isPausedBySchedulePause returns true if the schedule pause of the owner of
+// the workspace suspends a transition with the given reason. Autostarts that
+// were due during the pause are skipped once it ends, so the workspace starts
+// on its first scheduled time after the pause.
+func isPausedBySchedulePause(pause database.UserSchedulePause, user database.User, ws database.Workspace, build database.WorkspaceBuild, job database.ProvisionerJob, templateSchedule schedule.TemplateScheduleOptions, reason database.BuildReason, currentTick time.Time) bool {
+	paused := currentTick.Before(pause.PausedUntil)
+	switch reason {
+	case database.BuildReasonAutostart:
+		if paused {
+			return true
+		}
+		// The workspace was built after the pause, so no autostart was missed.
+		if !build.CreatedAt.Before(pause.PausedUntil) {
+			return false
+		}
+		next, err := schedule.NextAllowedAutostart(pause.PausedUntil, ws.AutostartSchedule.String, templateSchedule)
+		return err == nil && currentTick.Before(next)
+	case database.BuildReasonAutostop, database.BuildReasonTaskAutoPause:
+		// Only autostops at the deadline of builds are paused. Workspaces of
+		// suspended users, workspaces past their lifetime and workspaces due
+		// to the autostop requirement still stop.
+		return paused && pause.PauseAutostop &&
+			user.Status == database.UserStatusActive &&
+			(build.MaxDeadline.IsZero() || currentTick.Before(build.MaxDeadline)) &&
+			!isEligibleForTrialExpiry(ws, build, job, currentTick) &&
+			!isEligibleForMaxLifetimeExpiry(ws, build, job, templateSchedule, currentTick) &&
+			isEligibleForAutostop(user, ws, build, job, currentTick)
+	default:
+		return false
+	}
+}
+
+// hasDormancyExemption returns true if the workspace has an unexpired
+// dormancy exemption.
+func hasDormancyExemption(ctx context.Context, db database.Store, workspaceID uuid.UUID, currentTick time.Time) (bool, error) {
+	exemption, err := db.GetWorkspaceDormancyExemptionByWorkspaceID(ctx, workspaceID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return currentTick.Before(exemption.ExpiresAt), nil
+}
+
 func isEligibleForDelete(ws database.Workspace, templateSchedule schedule.TemplateScheduleOptions, lastBuild database.WorkspaceBuild, lastJob database.ProvisionerJob, currentTick time.Time) bool {
 	eligible := ws.DormantAt.Valid && ws.DeletingAt.Valid &&
 		// Dormant workspaces should only be deleted if a time_til_dormant_autodelete value is specified.
@@ -803,6 +1259,127 @@ func isEligibleForDelete(ws database.Workspace, templateSchedule schedule.Templa
 	return eligible
 }
 
+// isEligibleForTrialExpiry returns true if the workspace has a hard expiry,
+// inherited from the template's trial workspace TTL, that has passed. Activity,
+// schedules and dormancy exemptions do not extend it.
+func isEligibleForTrialExpiry(ws database.Workspace, lastBuild database.WorkspaceBuild, lastJob database.ProvisionerJob, currentTick time.Time) bool {
+	if !ws.ExpiresAt.Valid || currentTick.Before(ws.ExpiresAt.Time) {
+		return false
+	}
+
+	// Wait for the in-flight build to finish before transitioning.
+	if !lastJob.Finished() {
+		return false
+	}
+
+	// As with dormant workspaces, wait 24 hours before retrying a failed
+	// delete.
+	if lastBuild.Transition == database.WorkspaceTransitionDelete && lastJob.JobStatus == database.ProvisionerJobStatusFailed {
+		return currentTick.Sub(lastJob.FinishedAt()) > time.Hour*24
+	}
+
+	return true
+}
+
+// isEligibleForMaxLifetimeExpiry returns true if the workspace has outlived the
+// template's max lifetime. Running workspaces are always eligible, stopped
+// workspaces only if the template deletes expired workspaces.
+func isEligibleForMaxLifetimeExpiry(ws database.Workspace, lastBuild database.WorkspaceBuild, lastJob database.ProvisionerJob, templateSchedule schedule.TemplateScheduleOptions, currentTick time.Time) bool {
+	expiresAt, ok := templateSchedule.MaxLifetime.ExpiresAt(ws.CreatedAt)
+	if !ok || currentTick.Before(expiresAt) {
+		return false
+	}
+
+	// Wait for the in-flight build to finish before transitioning.
+	if !lastJob.Finished() {
+		return false
+	}
+
+	if templateSchedule.MaxLifetime.Action == database.MaxLifetimeActionStop {
+		return lastBuild.Transition == database.WorkspaceTransitionStart
+	}
+
+	// As with dormant workspaces, wait 24 hours before retrying a failed
+	// delete.
+	if lastBuild.Transition == database.WorkspaceTransitionDelete && lastJob.JobStatus == database.ProvisionerJobStatusFailed {
+		return currentTick.Sub(lastJob.FinishedAt()) > time.Hour*24
+	}
+
+	return true
+}
+
+// isEligibleForIdleReclaim returns true if the workspace is running and has
+// been idle for longer than the idle reclaim TTL of its template. Idleness is
+// measured from the last usage reported by the workspace agents, or from the
+// end of the build if the workspace has not been used since. Callers must
+// also check that the workspace resources match the resource selector.
+func isEligibleForIdleReclaim(ws database.Workspace, build database.WorkspaceBuild, job database.ProvisionerJob, templateSchedule schedule.TemplateScheduleOptions, currentTick time.Time) bool {
+	if !templateSchedule.IdleReclaim.Enabled() {
+		return false
+	}
+
+	// Only successfully started workspaces are reclaimed.
+	if build.Transition != database.WorkspaceTransitionStart || job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		return false
+	}
+
+	// Dormant workspaces are handled by the dormancy policy.
+	if ws.DormantAt.Valid {
+		return false
+	}
+
+	idleSince := ws.LastUsedAt
+	if job.CompletedAt.Valid && job.CompletedAt.Time.After(idleSince) {
+		idleSince = job.CompletedAt.Time
+	}
+	return currentTick.Sub(idleSince) >= templateSchedule.IdleReclaim.TTL
+}
+
+// matchesIdleReclaimResources returns true if any resource provisioned by the
+// job has metadata matching the resource selector of the idle reclaim policy.
+func matchesIdleReclaimResources(ctx context.Context, db database.Store, jobID uuid.UUID, idleReclaim schedule.TemplateIdleReclaim) (bool, error) {
+	resources, err := db.GetWorkspaceResourcesByJobID(ctx, jobID)
+	if err != nil {
+		return false, xerrors.Errorf("get workspace resources: %w", err)
+	}
+	if len(resources) == 0 {
+		return false, nil
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	metadata, err := db.GetWorkspaceResourceMetadataByResourceIDs(ctx, resourceIDs)
+	if err != nil {
+		return false, xerrors.Errorf("get workspace resource metadata: %w", err)
+	}
+	return idleReclaim.Matches(metadata), nil
+}
+
+// maxLifetimeWarningDays returns the smallest number of days in
+// schedule.MaxLifetimeWarningDays that the workspace is within of its max
+// lifetime. The boolean is false if no warning is due.
+func maxLifetimeWarningDays(expiresAt time.Time, currentTick time.Time) (int, bool) {
+	remaining := expiresAt.Sub(currentTick)
+	if remaining <= 0 {
+		return 0, false
+	}
+	days, ok := 0, false
+	for _, d := range schedule.MaxLifetimeWarningDays {
+		if remaining <= time.Duration(d)*24*time.Hour && (!ok || d < days) {
+			days, ok = d, true
+		}
+	}
+	return days, ok
+}
+
+// isEligibleForBudgetStop returns true if a workspace over its monthly cost
+// budget is running and can be stopped.
+func isEligibleForBudgetStop(build database.WorkspaceBuild, job database.ProvisionerJob) bool {
+	return build.Transition == database.WorkspaceTransitionStart &&
+		job.JobStatus == database.ProvisionerJobStatusSucceeded
+}
+
 // isEligibleForFailedCleanup returns true if the workspace is eligible to be
 // stopped due to a failed build. A failed start is cleaned up by stopping it,
 // and a failed stop is retried by issuing another stop. In both cases the
@@ -479,3 +479,145 @@ func Test_isEligibleForAutostart(t *testing.T) {
 		})
 	}
 }
+
+func Test_isPausedBySchedulePause(t *testing.T) {
+	t.Parallel()
+
+	// 5s after the daily autostart.
+	tick := time.Date(2024, 3, 4, 9, 0, 5, 0, time.UTC)
+	user := database.User{Status: database.UserStatusActive}
+	workspace := database.Workspace{
+		AutostartSchedule: sql.NullString{
+			Valid:  true,
+			String: "CRON_TZ=UTC 0 9 * * *",
+		},
+	}
+	stopped := database.WorkspaceBuild{
+		Transition: database.WorkspaceTransitionStop,
+		CreatedAt:  tick.Add(-30 * 24 * time.Hour),
+	}
+	started := database.WorkspaceBuild{
+		Transition: database.WorkspaceTransitionStart,
+		CreatedAt:  tick.Add(-9 * time.Hour),
+		Deadline:   tick.Add(-time.Minute),
+	}
+	job := database.ProvisionerJob{
+		JobStatus: database.ProvisionerJobStatusSucceeded,
+	}
+	templateSchedule := schedule.TemplateScheduleOptions{
+		UserAutostartEnabled: true,
+		AutostartRequirement: schedule.TemplateAutostartRequirement{
+			DaysOfWeek: 0b01111111,
+		},
+	}
+
+	testCases := []struct {
+		Name   string
+		User   database.User
+		Build  database.WorkspaceBuild
+		Pause  database.UserSchedulePause
+		Reason database.BuildReason
+
+		ExpectedResponse bool
+	}{
+		{
+			Name:             "AutostartDuringPause",
+			User:             user,
+			Build:            stopped,
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(24 * time.Hour)},
+			Reason:           database.BuildReasonAutostart,
+			ExpectedResponse: true,
+		},
+		{
+			// The pause ended after the autostart of today, so the
+			// workspace must wait for the autostart of tomorrow.
+			Name:             "AutostartMissedDuringPause",
+			User:             user,
+			Build:            stopped,
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(-4 * time.Second)},
+			Reason:           database.BuildReasonAutostart,
+			ExpectedResponse: true,
+		},
+		{
+			Name:             "AutostartAfterPause",
+			User:             user,
+			Build:            stopped,
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(-2 * time.Hour)},
+			Reason:           database.BuildReasonAutostart,
+			ExpectedResponse: false,
+		},
+		{
+			Name: "AutostartBuiltAfterPause",
+			User: user,
+			Build: func(b database.WorkspaceBuild) database.WorkspaceBuild {
+				cpy := b
+				cpy.CreatedAt = tick.Add(-2 * time.Second)
+				return cpy
+			}(stopped),
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(-4 * time.Second)},
+			Reason:           database.BuildReasonAutostart,
+			ExpectedResponse: false,
+		},
+		{
+			Name:             "AutostopNotPaused",
+			User:             user,
+			Build:            started,
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(24 * time.Hour)},
+			Reason:           database.BuildReasonAutostop,
+			ExpectedResponse: false,
+		},
+		{
+			Name:             "AutostopPaused",
+			User:             user,
+			Build:            started,
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(24 * time.Hour), PauseAutostop: true},
+			Reason:           database.BuildReasonAutostop,
+			ExpectedResponse: true,
+		},
+		{
+			Name:             "AutostopAfterPause",
+			User:             user,
+			Build:            started,
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(-time.Hour), PauseAutostop: true},
+			Reason:           database.BuildReasonAutostop,
+			ExpectedResponse: false,
+		},
+		{
+			Name:             "AutostopSuspendedUser",
+			User:             database.User{Status: database.UserStatusSuspended},
+			Build:            started,
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(24 * time.Hour), PauseAutostop: true},
+			Reason:           database.BuildReasonAutostop,
+			ExpectedResponse: false,
+		},
+		{
+			Name: "AutostopRequirement",
+			User: user,
+			Build: func(b database.WorkspaceBuild) database.WorkspaceBuild {
+				cpy := b
+				cpy.MaxDeadline = b.Deadline
+				return cpy
+			}(started),
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(24 * time.Hour), PauseAutostop: true},
+			Reason:           database.BuildReasonAutostop,
+			ExpectedResponse: false,
+		},
+		{
+			Name:             "Dormancy",
+			User:             user,
+			Build:            started,
+			Pause:            database.UserSchedulePause{PausedUntil: tick.Add(24 * time.Hour), PauseAutostop: true},
+			Reason:           database.BuildReasonDormancy,
+			ExpectedResponse: false,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			paused := isPausedBySchedulePause(c.Pause, c.User, workspace, c.Build, job, templateSchedule, c.Reason, tick)
+			require.Equal(t, c.ExpectedResponse, paused)
+		})
+	}
+}
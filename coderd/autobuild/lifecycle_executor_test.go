@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
@@ -20,6 +21,7 @@ import (
 	"cdr.dev/slog/v3/sloggers/slogtest"
 	"github.com/coder/coder/v2/coderd/autobuild"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/promhelp"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
@@ -556,6 +558,114 @@ func TestExecutorAutostopOK(t *testing.T) {
 	assert.Equal(t, codersdk.BuildReasonAutostop, workspace.LatestBuild.Reason)
 }
 
+func TestExecutorTransitionMetrics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sched      = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
+		tickCh     = make(chan time.Time)
+		statsCh    = make(chan autobuild.Stats)
+		reg        = prometheus.NewRegistry()
+		client, db = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			AutobuildTicker:             tickCh,
+			IncludeProvisionerDaemon:    true,
+			AutobuildStats:              statsCh,
+			AutobuildPrometheusRegistry: reg,
+		})
+		// Given: we have a user with a workspace that has autostart enabled
+		workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = ptr.Ref(sched.String())
+		})
+	)
+	transitionLabels := func(reason database.BuildReason) prometheus.Labels {
+		return prometheus.Labels{
+			"organization_name": workspace.OrganizationName,
+			"template_name":     workspace.TemplateName,
+			"reason":            string(reason),
+			"status":            "success",
+		}
+	}
+
+	// Given: workspace is stopped
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
+	p, err := coderdtest.GetProvisionerForTags(db, time.Now(), workspace.OrganizationID, nil)
+	require.NoError(t, err)
+
+	// When: the autobuild executor ticks after the scheduled time
+	tickTime := coderdtest.NextAutostartTick(t, workspace)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	tickCh <- tickTime
+
+	// Then: the workspace is started and the autostart is counted
+	stats := <-statsCh
+	require.Len(t, stats.Errors, 0)
+	require.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[workspace.ID])
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_lifecycle_transitions_total", transitionLabels(database.BuildReasonAutostart)))
+
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.NotZero(t, workspace.LatestBuild.Deadline)
+
+	// When: the autobuild executor ticks after the deadline
+	tickTime = workspace.LatestBuild.Deadline.Time.Add(time.Minute)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	tickCh <- tickTime
+	close(tickCh)
+
+	// Then: the workspace is stopped and the autostop is counted
+	stats = <-statsCh
+	require.Len(t, stats.Errors, 0)
+	require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_lifecycle_transitions_total", transitionLabels(database.BuildReasonAutostop)))
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_lifecycle_transitions_total", transitionLabels(database.BuildReasonAutostart)))
+}
+
+func TestExecutorStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tickCh     = make(chan time.Time)
+		statsCh    = make(chan autobuild.Stats)
+		client, db = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+		})
+		workspace = mustProvisionWorkspace(t, client)
+	)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Before the first tick, there is nothing to report.
+	status, err := client.DebugLifecycleExecutor(ctx)
+	require.NoError(t, err)
+	require.Nil(t, status.LastTickAt)
+
+	p, err := coderdtest.GetProvisionerForTags(db, time.Now(), workspace.OrganizationID, nil)
+	require.NoError(t, err)
+
+	tickTime := workspace.LatestBuild.Deadline.Time.Add(time.Minute)
+	go func() {
+		coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+		tickCh <- tickTime
+		close(tickCh)
+	}()
+	stats := <-statsCh
+	require.Len(t, stats.Errors, 0)
+
+	status, err = client.DebugLifecycleExecutor(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, status.LastTickAt)
+	require.True(t, tickTime.Equal(*status.LastTickAt))
+	require.NotEqual(t, uuid.Nil, status.ReplicaID)
+	require.Equal(t, 1, status.WorkspacesEvaluated)
+	require.Equal(t, 1, status.TransitionsAttempted)
+	require.Equal(t, 1, status.TransitionsSucceeded)
+	require.Zero(t, status.TransitionsFailed)
+	require.Empty(t, status.Failures)
+	require.Empty(t, status.Deferred)
+}
+
 func TestExecutorAutostopExtend(t *testing.T) {
 	t.Parallel()
 
@@ -1367,6 +1477,307 @@ func TestExecutorInactiveWorkspace(t *testing.T) {
 	})
 }
 
+func TestExecutorDormancyExemption(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ticker         = make(chan time.Time)
+		statCh         = make(chan autobuild.Stats)
+		timeTilDormant = time.Minute
+		client, db     = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			AutobuildTicker:          ticker,
+			AutobuildStats:           statCh,
+			IncludeProvisionerDaemon: true,
+			TemplateScheduleStore: schedule.MockTemplateScheduleStore{
+				SetFn: func(ctx context.Context, db database.Store, template database.Template, options schedule.TemplateScheduleOptions) (database.Template, error) {
+					template.TimeTilDormant = int64(options.TimeTilDormant)
+					return schedule.NewAGPLTemplateScheduleStore().Set(ctx, db, template, options)
+				},
+				GetFn: func(_ context.Context, _ database.Store, _ uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+					return schedule.TemplateScheduleOptions{
+						UserAutostopEnabled: true,
+						TimeTilDormant:      timeTilDormant,
+					}, nil
+				},
+			},
+		})
+		admin   = coderdtest.CreateFirstUser(t, client)
+		version = coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+	)
+
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+		ctr.TimeTilDormantMillis = ptr.Ref(timeTilDormant.Milliseconds())
+	})
+	userClient, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	workspace := coderdtest.CreateWorkspace(t, userClient, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, userClient, workspace.LatestBuild.ID)
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
+	_ = coderdtest.AwaitWorkspaceBuildJobCompleted(t, userClient, workspace.LatestBuild.ID)
+
+	p, err := coderdtest.GetProvisionerForTags(db, time.Now(), workspace.OrganizationID, nil)
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	tickTime := workspace.LastUsedAt.Add(timeTilDormant * 3)
+	_, err = client.PutWorkspaceDormancyExemption(ctx, workspace.ID, codersdk.PutWorkspaceDormancyExemptionRequest{
+		Reason:    "Long-running research job",
+		ExpiresAt: tickTime.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	// The exemption keeps the workspace active.
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	stats := testutil.TryReceive(ctx, t, statCh)
+	require.Len(t, stats.Transitions, 0)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.Nil(t, workspace.DormantAt)
+
+	// Once the exemption expires the template policy applies again.
+	tickTime = tickTime.Add(2 * time.Hour)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	_ = testutil.TryReceive(ctx, t, statCh)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.NotNil(t, workspace.DormantAt)
+}
+
+func TestExecutorTrialWorkspaceExpiry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ticker     = make(chan time.Time)
+		statCh     = make(chan autobuild.Stats)
+		trialTTL   = time.Hour
+		client, db = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			AutobuildTicker:          ticker,
+			AutobuildStats:           statCh,
+			IncludeProvisionerDaemon: true,
+		})
+		admin   = coderdtest.CreateFirstUser(t, client)
+		version = coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+	)
+
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+		ctr.TrialWorkspaceTTLMillis = ptr.Ref(trialTTL.Milliseconds())
+	})
+	require.Equal(t, trialTTL.Milliseconds(), template.TrialWorkspaceTTLMillis)
+	userClient, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	workspace := coderdtest.CreateWorkspace(t, userClient, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, userClient, workspace.LatestBuild.ID)
+	require.NotNil(t, workspace.ExpiresAt)
+	require.WithinDuration(t, workspace.CreatedAt.Add(trialTTL), *workspace.ExpiresAt, time.Second)
+
+	p, err := coderdtest.GetProvisionerForTags(db, time.Now(), workspace.OrganizationID, nil)
+	require.NoError(t, err)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Nothing happens before the workspace expires.
+	tickTime := workspace.ExpiresAt.Add(-time.Minute)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	stats := testutil.TryReceive(ctx, t, statCh)
+	require.Len(t, stats.Transitions, 0)
+
+	// Once expired, the running workspace is stopped first...
+	tickTime = workspace.ExpiresAt.Add(time.Minute)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	stats = testutil.TryReceive(ctx, t, statCh)
+	require.Len(t, stats.Errors, 0)
+	require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.Equal(t, codersdk.BuildReasonAutostop, workspace.LatestBuild.Reason)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	// ...and then deleted.
+	tickTime = tickTime.Add(time.Minute)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	stats = testutil.TryReceive(ctx, t, statCh)
+	require.Len(t, stats.Errors, 0)
+	require.Equal(t, database.WorkspaceTransitionDelete, stats.Transitions[workspace.ID])
+}
+
+func TestExecutorMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ticker      = make(chan time.Time)
+		statCh      = make(chan autobuild.Stats)
+		notifyEnq   = notificationstest.FakeEnqueuer{}
+		maxLifetime = 2 * 24 * time.Hour
+		client, db  = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			AutobuildTicker:          ticker,
+			AutobuildStats:           statCh,
+			IncludeProvisionerDaemon: true,
+			NotificationsEnqueuer:    &notifyEnq,
+			TemplateScheduleStore: schedule.MockTemplateScheduleStore{
+				SetFn: func(ctx context.Context, db database.Store, template database.Template, options schedule.TemplateScheduleOptions) (database.Template, error) {
+					template.MaxLifetime = int64(options.MaxLifetime.Duration)
+					template.MaxLifetimeAction = options.MaxLifetime.Action
+					return schedule.NewAGPLTemplateScheduleStore().Set(ctx, db, template, options)
+				},
+				GetFn: func(_ context.Context, _ database.Store, _ uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+					return schedule.TemplateScheduleOptions{
+						UserAutostopEnabled: true,
+						MaxLifetime: schedule.TemplateMaxLifetime{
+							Duration: maxLifetime,
+							Action:   database.MaxLifetimeActionDelete,
+						},
+					}, nil
+				},
+			},
+		})
+		admin   = coderdtest.CreateFirstUser(t, client)
+		version = coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+	)
+
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+	userClient, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	workspace := coderdtest.CreateWorkspace(t, userClient, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, userClient, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		MaxLifetimeMillis: ptr.Ref(maxLifetime.Milliseconds()),
+	})
+	require.NoError(t, err)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.NotNil(t, workspace.ExpiresAt)
+	require.WithinDuration(t, workspace.CreatedAt.Add(maxLifetime), *workspace.ExpiresAt, time.Second)
+
+	p, err := coderdtest.GetProvisionerForTags(db, time.Now(), workspace.OrganizationID, nil)
+	require.NoError(t, err)
+
+	// Two days before the expiry, the 3 day warning is sent once.
+	notifyEnq.Clear()
+	for range 2 {
+		tickTime := workspace.ExpiresAt.Add(-maxLifetime + time.Minute)
+		coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+		ticker <- tickTime
+		stats := testutil.TryReceive(ctx, t, statCh)
+		require.Len(t, stats.Transitions, 0)
+	}
+	sent := notifyEnq.Sent()
+	require.Len(t, sent, 1)
+	require.Equal(t, notifications.TemplateWorkspaceExpiring, sent[0].TemplateID)
+	require.Equal(t, workspace.OwnerID, sent[0].UserID)
+	require.Equal(t, workspace.Name, sent[0].Labels["workspace"])
+	require.Equal(t, "deleted", sent[0].Labels["action"])
+	require.Contains(t, sent[0].Targets, workspace.ID)
+
+	// The 1 day warning follows.
+	tickTime := workspace.ExpiresAt.Add(-12 * time.Hour)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	_ = testutil.TryReceive(ctx, t, statCh)
+	require.Len(t, notifyEnq.Sent(), 2)
+
+	// Once expired, the running workspace is stopped first...
+	tickTime = workspace.ExpiresAt.Add(time.Minute)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	stats := testutil.TryReceive(ctx, t, statCh)
+	require.Len(t, stats.Errors, 0)
+	require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	// ...and then deleted.
+	tickTime = tickTime.Add(time.Minute)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	stats = testutil.TryReceive(ctx, t, statCh)
+	require.Len(t, stats.Errors, 0)
+	require.Equal(t, database.WorkspaceTransitionDelete, stats.Transitions[workspace.ID])
+}
+
+func TestExecutorIdleReclaim(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ticker     = make(chan time.Time)
+		statCh     = make(chan autobuild.Stats)
+		idleTTL    = 15 * time.Minute
+		client, db = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			AutobuildTicker:          ticker,
+			AutobuildStats:           statCh,
+			IncludeProvisionerDaemon: true,
+			TemplateScheduleStore: schedule.MockTemplateScheduleStore{
+				SetFn: func(ctx context.Context, db database.Store, template database.Template, options schedule.TemplateScheduleOptions) (database.Template, error) {
+					template.IdleReclaimTTL = int64(idleTTL)
+					template.IdleReclaimResourceSelector = "gpu=true"
+					return schedule.NewAGPLTemplateScheduleStore().Set(ctx, db, template, options)
+				},
+				GetFn: func(_ context.Context, _ database.Store, _ uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+					return schedule.TemplateScheduleOptions{
+						UserAutostopEnabled: true,
+						IdleReclaim: schedule.TemplateIdleReclaim{
+							TTL:              idleTTL,
+							ResourceSelector: "gpu=true",
+						},
+					}, nil
+				},
+			},
+		})
+		admin = coderdtest.CreateFirstUser(t, client)
+	)
+
+	// Both templates reclaim idle GPU workspaces, but only one of them
+	// provisions a GPU.
+	gpuVersion := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, echo.WithResources([]*proto.Resource{{
+		Name: "dev",
+		Type: "compute",
+		Metadata: []*proto.Resource_Metadata{{
+			Key:   "gpu",
+			Value: "true",
+		}},
+	}}))
+	cpuVersion := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, echo.WithResources([]*proto.Resource{{
+		Name: "dev",
+		Type: "compute",
+	}}))
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, gpuVersion.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, cpuVersion.ID)
+	gpuTemplate := coderdtest.CreateTemplate(t, client, admin.OrganizationID, gpuVersion.ID)
+	cpuTemplate := coderdtest.CreateTemplate(t, client, admin.OrganizationID, cpuVersion.ID)
+	userClient, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	gpuWorkspace := coderdtest.CreateWorkspace(t, userClient, gpuTemplate.ID)
+	cpuWorkspace := coderdtest.CreateWorkspace(t, userClient, cpuTemplate.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, userClient, gpuWorkspace.LatestBuild.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, userClient, cpuWorkspace.LatestBuild.ID)
+
+	p, err := coderdtest.GetProvisionerForTags(db, time.Now(), gpuWorkspace.OrganizationID, nil)
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Workspaces idle for less than the TTL keep running.
+	tickTime := time.Now().Add(idleTTL / 2)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	stats := testutil.TryReceive(ctx, t, statCh)
+	require.Len(t, stats.Errors, 0)
+	require.Len(t, stats.Transitions, 0)
+
+	// Once idle for longer, only the GPU workspace is stopped.
+	tickTime = time.Now().Add(idleTTL * 2)
+	coderdtest.UpdateProvisionerLastSeenAt(t, db, p.ID, tickTime)
+	ticker <- tickTime
+	stats = testutil.TryReceive(ctx, t, statCh)
+	require.Len(t, stats.Errors, 0)
+	require.Len(t, stats.Transitions, 1)
+	require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[gpuWorkspace.ID])
+
+	gpuWorkspace = coderdtest.MustWorkspace(t, client, gpuWorkspace.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, gpuWorkspace.LatestBuild.ID)
+	require.Equal(t, codersdk.BuildReasonAutostop, build.Reason)
+}
+
 func TestNotifications(t *testing.T) {
 	t.Parallel()
 
@@ -2387,6 +2798,7 @@ func TestExecutorAutostartSkipsWhenNoProvisionersAvailable(t *testing.T) {
 	tickCh <- next
 	stats := <-statsCh
 	assert.Len(t, stats.Transitions, 0, "should not create builds when no provisioners available")
+	assert.Equal(t, codersdk.LifecycleExecutorDeferReasonNoProvisioners, stats.Deferred[workspace.ID])
 
 	daemon2Closer := coderdtest.NewTaggedProvisionerDaemon(t, api, "name", provisionerDaemonTags)
 	t.Cleanup(func() {
@@ -2413,6 +2825,8 @@ func TestExecutorAutostartSkipsWhenNoProvisionersAvailable(t *testing.T) {
 	stats = <-statsCh
 
 	assert.Len(t, stats.Transitions, 1, "should create builds when provisioners are available")
+	assert.Empty(t, stats.Deferred)
+	assert.Equal(t, database.BuildReasonAutostart, stats.Attempted[workspace.ID])
 }
 
 func TestExecutorTaskWorkspace(t *testing.T) {
@@ -0,0 +1,149 @@
+// Package buildfailure extracts the root cause of a failed workspace build
+// from its provisioner logs.
+//
+// The error of a failed provisioner job is usually generic, e.g. "exit status
+// 1", while the logs contain the error reported by the Terraform provider and
+// the resource it failed for. Summarize uses a few heuristics over the
+// error-level logs to surface those instead, and Excerpt returns the last
+// lines of the logs for context.
+package buildfailure
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const errorPrefix = "Error: "
+
+// ExcerptLines is the number of log lines in the excerpts of failed builds
+// included in notifications.
+const ExcerptLines = 15
+
+var (
+	// contextRe matches the source context logged for a diagnostic, e.g.
+	// `on main.tf line 42, in resource "aws_instance" "dev":`.
+	contextRe = regexp.MustCompile(`^on \S+ line \d+, in (resource|data) "([^"]+)" "([^"]+)":$`)
+	// sourceRe matches the source location logged for a diagnostic without
+	// context, e.g. `on main.tf line 42:`.
+	sourceRe = regexp.MustCompile(`^on \S+ line \d+:$`)
+	// addressRe matches the resource address rendered by the Terraform CLI,
+	// e.g. `with aws_instance.dev[0],`.
+	addressRe = regexp.MustCompile(`^with (\S+),$`)
+	// snippetRe matches a line of the source snippet, e.g.
+	// `42: resource "aws_instance" "dev" {`.
+	snippetRe = regexp.MustCompile(`^\d+: `)
+)
+
+// diagnostic is an error reported by Terraform, reassembled from the log
+// lines it was split into.
+type diagnostic struct {
+	message         string
+	detail          string
+	resourceAddress string
+}
+
+// Summarize returns the root cause of a failed build given the error of its
+// job and its error-level logs, ordered by ID. The first error diagnostic in
+// the logs is used, since later errors are often a consequence of it. If the
+// logs contain no diagnostic, the last error line or the job error is used.
+// Nil is returned if there is nothing to summarize.
+func Summarize(jobError string, logs []database.ProvisionerJobLog) *codersdk.WorkspaceBuildFailureSummary {
+	var (
+		diag     *diagnostic
+		lastLine string
+	)
+	for _, log := range logs {
+		if log.Level != database.LogLevelError {
+			continue
+		}
+		line := strings.TrimSpace(log.Output)
+		if line == "" {
+			continue
+		}
+		lastLine = line
+
+		if strings.HasPrefix(line, errorPrefix) {
+			if diag != nil {
+				// Only the first diagnostic is summarized.
+				break
+			}
+			diag = &diagnostic{message: strings.TrimSpace(strings.TrimPrefix(line, errorPrefix))}
+			continue
+		}
+		if diag == nil {
+			continue
+		}
+		if m := contextRe.FindStringSubmatch(line); m != nil {
+			if diag.resourceAddress == "" {
+				diag.resourceAddress = m[2] + "." + m[3]
+				if m[1] == "data" {
+					diag.resourceAddress = "data." + diag.resourceAddress
+				}
+			}
+			continue
+		}
+		if m := addressRe.FindStringSubmatch(line); m != nil {
+			// The address rendered by the CLI is more precise, since it
+			// includes modules and indexes.
+			diag.resourceAddress = m[1]
+			continue
+		}
+		if sourceRe.MatchString(line) || snippetRe.MatchString(line) || isBoxDrawing(line) {
+			continue
+		}
+		if diag.detail == "" {
+			diag.detail = line
+		}
+	}
+
+	switch {
+	case diag != nil:
+		return &codersdk.WorkspaceBuildFailureSummary{
+			Message:         diag.message,
+			Detail:          diag.detail,
+			ResourceAddress: diag.resourceAddress,
+		}
+	case lastLine != "":
+		summary := &codersdk.WorkspaceBuildFailureSummary{Message: lastLine}
+		if jobError != lastLine {
+			summary.Detail = jobError
+		}
+		return summary
+	case jobError != "":
+		return &codersdk.WorkspaceBuildFailureSummary{Message: jobError}
+	default:
+		return nil
+	}
+}
+
+// Excerpt returns the last n relevant lines of the logs of a failed build,
+// given its logs ordered by ID. Debug and trace logs, and blank lines, are
+// omitted.
+func Excerpt(logs []database.ProvisionerJobLog, n int) []string {
+	lines := make([]string, 0, n)
+	for i := len(logs) - 1; i >= 0 && len(lines) < n; i-- {
+		if logs[i].Level == database.LogLevelDebug || logs[i].Level == database.LogLevelTrace {
+			continue
+		}
+		line := strings.TrimRight(logs[i].Output, " \t\r\n")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	slices.Reverse(lines)
+	return lines
+}
+
+// isBoxDrawing reports whether line is part of the frame Terraform draws
+// around diagnostics and expression values.
+func isBoxDrawing(line string) bool {
+	return strings.HasPrefix(line, "│") ||
+		strings.HasPrefix(line, "├") ||
+		strings.HasPrefix(line, "╷") ||
+		strings.HasPrefix(line, "╵")
+}
@@ -0,0 +1,154 @@
+package buildfailure_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/buildfailure"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	logs := func(level database.LogLevel, lines ...string) []database.ProvisionerJobLog {
+		out := make([]database.ProvisionerJobLog, 0, len(lines))
+		for i, line := range lines {
+			out = append(out, database.ProvisionerJobLog{ID: int64(i + 1), Level: level, Output: line})
+		}
+		return out
+	}
+
+	for _, tc := range []struct {
+		name     string
+		jobError string
+		logs     []database.ProvisionerJobLog
+		expected *codersdk.WorkspaceBuildFailureSummary
+	}{
+		{
+			name:     "Empty",
+			expected: nil,
+		},
+		{
+			name:     "JobErrorOnly",
+			jobError: "exit status 1",
+			expected: &codersdk.WorkspaceBuildFailureSummary{Message: "exit status 1"},
+		},
+		{
+			name:     "Diagnostic",
+			jobError: "exit status 1",
+			logs: logs(database.LogLevelError,
+				"Error: creating EC2 Instance: UnauthorizedOperation: You are not authorized to perform this operation.",
+				`on main.tf line 42, in resource "aws_instance" "dev":`,
+				`  42: resource "aws_instance" "dev" {`,
+				"",
+				"status code: 403, request id: 5e0c1a2b",
+			),
+			expected: &codersdk.WorkspaceBuildFailureSummary{
+				Message:         "creating EC2 Instance: UnauthorizedOperation: You are not authorized to perform this operation.",
+				Detail:          "status code: 403, request id: 5e0c1a2b",
+				ResourceAddress: "aws_instance.dev",
+			},
+		},
+		{
+			name: "DataSource",
+			logs: logs(database.LogLevelError,
+				"Error: Unable to find image",
+				`on main.tf line 7, in data "docker_registry_image" "base":`,
+			),
+			expected: &codersdk.WorkspaceBuildFailureSummary{
+				Message:         "Unable to find image",
+				ResourceAddress: "data.docker_registry_image.base",
+			},
+		},
+		{
+			name: "CLIAddress",
+			logs: logs(database.LogLevelError,
+				"Error: deleting volume: volume is in use",
+				"with module.home.docker_volume.home[0],",
+				`on home/main.tf line 3, in resource "docker_volume" "home":`,
+			),
+			expected: &codersdk.WorkspaceBuildFailureSummary{
+				Message:         "deleting volume: volume is in use",
+				ResourceAddress: "module.home.docker_volume.home[0]",
+			},
+		},
+		{
+			name: "FirstDiagnostic",
+			logs: logs(database.LogLevelError,
+				"Error: quota exceeded",
+				`on main.tf line 1, in resource "google_compute_disk" "home":`,
+				"Error: dependency failed",
+				`on main.tf line 9, in resource "google_compute_instance" "dev":`,
+			),
+			expected: &codersdk.WorkspaceBuildFailureSummary{
+				Message:         "quota exceeded",
+				ResourceAddress: "google_compute_disk.home",
+			},
+		},
+		{
+			name: "ValueFrame",
+			logs: logs(database.LogLevelError,
+				"Error: Invalid index",
+				"on main.tf line 5:",
+				"  5:   image = var.images[2]",
+				"    ├────────────────",
+				"    │ var.images is list of string with 2 elements",
+				"The given key does not identify an element in this collection value.",
+			),
+			expected: &codersdk.WorkspaceBuildFailureSummary{
+				Message: "Invalid index",
+				Detail:  "The given key does not identify an element in this collection value.",
+			},
+		},
+		{
+			name:     "NoDiagnostic",
+			jobError: "exit status 1",
+			logs:     logs(database.LogLevelError, "plan failed", "provider crashed"),
+			expected: &codersdk.WorkspaceBuildFailureSummary{
+				Message: "provider crashed",
+				Detail:  "exit status 1",
+			},
+		},
+		{
+			name:     "IgnoresOtherLevels",
+			jobError: "exit status 1",
+			logs:     logs(database.LogLevelInfo, "Error: not an error"),
+			expected: &codersdk.WorkspaceBuildFailureSummary{Message: "exit status 1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, buildfailure.Summarize(tc.jobError, tc.logs))
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	t.Parallel()
+
+	logs := []database.ProvisionerJobLog{
+		{ID: 1, Level: database.LogLevelInfo, Output: "Terraform 1.9.8"},
+		{ID: 2, Level: database.LogLevelInfo, Output: "docker_volume.home: Creating..."},
+		{ID: 3, Level: database.LogLevelDebug, Output: "provider: plugin process exited"},
+		{ID: 4, Level: database.LogLevelError, Output: ""},
+		{ID: 5, Level: database.LogLevelError, Output: "Error: Unable to create volume  "},
+		{ID: 6, Level: database.LogLevelError, Output: `  on main.tf line 3, in resource "docker_volume" "home":`},
+	}
+
+	require.Equal(t, []string{
+		"docker_volume.home: Creating...",
+		"Error: Unable to create volume",
+		`  on main.tf line 3, in resource "docker_volume" "home":`,
+	}, buildfailure.Excerpt(logs, 3))
+	require.Len(t, buildfailure.Excerpt(logs, buildfailure.ExcerptLines), 4)
+	require.Empty(t, buildfailure.Excerpt(nil, buildfailure.ExcerptLines))
+}
@@ -0,0 +1,201 @@
+// Package buildhook invokes the pre-build and post-build hooks of templates.
+//
+// A hook is a webhook configured on a template. Before a workspace build of
+// the template is provisioned, coderd POSTs a Request to the pre-build hook,
+// which may reject the build. After the build succeeded, coderd POSTs a
+// Request to the post-build hook. Both hooks may report the external
+// provisioning steps they performed, e.g. allocating an IP address, which are
+// recorded in the build logs.
+package buildhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// AttemptTimeout bounds a single request to a hook.
+	AttemptTimeout = 30 * time.Second
+
+	// MaxAttempts is how often a hook is invoked before giving up. Only
+	// connection errors, timeouts and 5xx responses are retried.
+	MaxAttempts = 3
+
+	// DefaultRetryBackoff is how long to wait before retrying a hook. It is
+	// doubled after every attempt.
+	DefaultRetryBackoff = time.Second
+
+	// maxResponseBodySize is how much of a hook response is read.
+	maxResponseBodySize = 64 << 10
+)
+
+// Event is the stage of the build a hook is invoked for.
+type Event string
+
+const (
+	EventPreBuild  Event = "pre_build"
+	EventPostBuild Event = "post_build"
+)
+
+// Workspace identifies the workspace a hook is invoked for.
+type Workspace struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	OwnerID        uuid.UUID `json:"owner_id"`
+	OwnerName      string    `json:"owner_name"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	TemplateID     uuid.UUID `json:"template_id"`
+	TemplateName   string    `json:"template_name"`
+}
+
+// Build identifies the workspace build a hook is invoked for.
+type Build struct {
+	ID                uuid.UUID                    `json:"id"`
+	BuildNumber       int32                        `json:"build_number"`
+	Transition        codersdk.WorkspaceTransition `json:"transition"`
+	Reason            string                       `json:"reason"`
+	TemplateVersionID uuid.UUID                    `json:"template_version_id"`
+	InitiatorID       uuid.UUID                    `json:"initiator_id"`
+}
+
+// Request is the body POSTed to a hook.
+type Request struct {
+	Event     Event     `json:"event"`
+	Workspace Workspace `json:"workspace"`
+	Build     Build     `json:"build"`
+}
+
+// Step is an external provisioning step performed by a hook.
+type Step struct {
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Response is the body a hook may respond with. An empty body is valid.
+type Response struct {
+	// Message is shown to the user when a pre-build hook rejects the build.
+	Message string `json:"message,omitempty"`
+	// Steps are recorded in the build logs.
+	Steps []Step `json:"steps,omitempty"`
+}
+
+// RejectedError is returned when a hook responds with a 4xx status. For the
+// pre-build hook this rejects the build.
+type RejectedError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *RejectedError) Error() string {
+	return e.Message
+}
+
+// IsRejected reports whether err is a RejectedError.
+func IsRejected(err error) bool {
+	var rejected *RejectedError
+	return errors.As(err, &rejected)
+}
+
+// Client invokes build hooks.
+type Client struct {
+	client       *http.Client
+	retryBackoff time.Duration
+}
+
+// New returns a Client that invokes hooks with client.
+func New(client *http.Client) *Client {
+	return &Client{
+		client:       client,
+		retryBackoff: DefaultRetryBackoff,
+	}
+}
+
+// WithRetryBackoff overrides the initial backoff between attempts. This
+// should only be used in tests.
+func (c *Client) WithRetryBackoff(d time.Duration) *Client {
+	c.retryBackoff = d
+	return c
+}
+
+// Invoke POSTs req to url and returns its response. Any 2xx response is a
+// success. A 4xx response is returned as a RejectedError and is not retried.
+func (c *Client) Invoke(ctx context.Context, url string, req Request) (Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, xerrors.Errorf("marshal build hook request: %w", err)
+	}
+
+	backoff := c.retryBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, url, body)
+		if err == nil || IsRejected(err) {
+			return resp, err
+		}
+		if attempt >= MaxAttempts {
+			return Response{}, xerrors.Errorf("build hook failed after %d attempts: %w", attempt, err)
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return Response{}, xerrors.Errorf("build hook failed after %d attempts: %w", attempt, err)
+		case <-t.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, url string, body []byte) (Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, AttemptTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Response{}, xerrors.Errorf("create build hook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	res, err := c.client.Do(httpReq)
+	if err != nil {
+		return Response{}, xerrors.Errorf("send build hook request: %w", err)
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(res.Body, maxResponseBodySize))
+	if err != nil {
+		return Response{}, xerrors.Errorf("read build hook response: %w", err)
+	}
+	var resp Response
+	// Hooks may respond with plain text, e.g. a rejection reason.
+	jsonErr := json.Unmarshal(raw, &resp)
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		if len(bytes.TrimSpace(raw)) > 0 && jsonErr != nil {
+			return Response{}, xerrors.Errorf("decode build hook response: %w", jsonErr)
+		}
+		return resp, nil
+	case res.StatusCode >= 400 && res.StatusCode < 500:
+		msg := resp.Message
+		if jsonErr != nil {
+			msg = strings.TrimSpace(string(raw))
+		}
+		if msg == "" {
+			msg = http.StatusText(res.StatusCode)
+		}
+		return Response{}, &RejectedError{StatusCode: res.StatusCode, Message: msg}
+	default:
+		return Response{}, xerrors.Errorf("build hook responded with status %d: %s", res.StatusCode, bytes.TrimSpace(raw))
+	}
+}
@@ -0,0 +1,140 @@
+package buildhook_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/buildhook"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	req := buildhook.Request{
+		Event: buildhook.EventPreBuild,
+		Workspace: buildhook.Workspace{
+			ID:   uuid.New(),
+			Name: "dev",
+		},
+		Build: buildhook.Build{
+			ID:          uuid.New(),
+			BuildNumber: 1,
+			Transition:  "start",
+		},
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var got buildhook.Request
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&got)) {
+				return
+			}
+			assert.Equal(t, req, got)
+			_ = json.NewEncoder(rw).Encode(buildhook.Response{
+				Steps: []buildhook.Step{{Name: "allocate ip", Detail: "10.0.0.7"}},
+			})
+		}))
+		defer srv.Close()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := buildhook.New(srv.Client()).Invoke(ctx, srv.URL, req)
+		require.NoError(t, err)
+		require.Equal(t, []buildhook.Step{{Name: "allocate ip", Detail: "10.0.0.7"}}, resp.Steps)
+	})
+
+	t.Run("EmptyBody", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := buildhook.New(srv.Client()).Invoke(ctx, srv.URL, req)
+		require.NoError(t, err)
+		require.Empty(t, resp.Steps)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			rw.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(rw).Encode(buildhook.Response{Message: "no addresses left in pool"})
+		}))
+		defer srv.Close()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := buildhook.New(srv.Client()).Invoke(ctx, srv.URL, req)
+		var rejected *buildhook.RejectedError
+		require.ErrorAs(t, err, &rejected)
+		require.Equal(t, http.StatusForbidden, rejected.StatusCode)
+		require.Equal(t, "no addresses left in pool", rejected.Message)
+		// Rejections are not retried.
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("RejectedPlainText", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.WriteHeader(http.StatusConflict)
+			_, _ = rw.Write([]byte("quota exceeded\n"))
+		}))
+		defer srv.Close()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := buildhook.New(srv.Client()).Invoke(ctx, srv.URL, req)
+		require.True(t, buildhook.IsRejected(err))
+		require.EqualError(t, err, "quota exceeded")
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) < buildhook.MaxAttempts {
+				rw.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := buildhook.New(srv.Client()).WithRetryBackoff(time.Millisecond).Invoke(ctx, srv.URL, req)
+		require.NoError(t, err)
+		require.EqualValues(t, buildhook.MaxAttempts, calls.Load())
+	})
+
+	t.Run("RetriesExhausted", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := buildhook.New(srv.Client()).WithRetryBackoff(time.Millisecond).Invoke(ctx, srv.URL, req)
+		require.ErrorContains(t, err, "status 503")
+		require.False(t, buildhook.IsRejected(err))
+		require.EqualValues(t, buildhook.MaxAttempts, calls.Load())
+	})
+}
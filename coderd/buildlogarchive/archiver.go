@@ -0,0 +1,187 @@
+// Package buildlogarchive enforces the build log retention policy.
+//
+// Provisioner job logs older than the retention of their template, or the
+// deployment-wide retention, are removed from the database. If an archive
+// Store is configured the logs are archived to it first, so they remain
+// retrievable through the API.
+package buildlogarchive
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/util/slice"
+)
+
+const (
+	// PollInterval is how often the archiver looks for expired build logs.
+	PollInterval = 10 * time.Minute
+
+	// MaxJobsPerRun is the maximum number of jobs whose logs are archived in
+	// a single run.
+	MaxJobsPerRun = 100
+)
+
+// Archiver archives or deletes expired build logs on every tick from its
+// channel.
+type Archiver struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db               database.Store
+	store            Store
+	defaultRetention time.Duration
+	log              slog.Logger
+	tick             <-chan time.Time
+	stats            chan<- Stats
+}
+
+// Stats contains statistics about the last run of the archiver.
+type Stats struct {
+	// ArchivedJobIDs contains the IDs of all jobs whose logs were removed
+	// from the database by the run.
+	ArchivedJobIDs []uuid.UUID
+	// Error is set if the jobs with expired logs could not be loaded. Jobs
+	// whose logs fail to archive are logged and retried on the next run.
+	Error error
+}
+
+// New returns a new archiver. Logs of templates without a build log
+// retention of their own are kept for defaultRetention, and forever if it is
+// zero. If store is nil, expired logs are deleted instead of archived.
+func New(ctx context.Context, db database.Store, store Store, defaultRetention time.Duration, log slog.Logger, tick <-chan time.Time) *Archiver {
+	//nolint:gocritic // The archiver processes provisioner jobs of all users.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Archiver{
+		ctx:              ctx,
+		cancel:           cancel,
+		done:             make(chan struct{}),
+		db:               db,
+		store:            store,
+		defaultRetention: defaultRetention,
+		log:              log,
+		tick:             tick,
+		stats:            nil,
+	}
+}
+
+// WithStatsChannel will cause Archiver to push a Stats to ch after every
+// tick. This push is blocking, so if ch is not read, the archiver will hang.
+// This should only be used in tests.
+func (a *Archiver) WithStatsChannel(ch chan<- Stats) *Archiver {
+	a.stats = ch
+	return a
+}
+
+// Start will cause the archiver to process expired build logs on every tick
+// from its channel. It will stop when its context is Done, or when its
+// channel is closed.
+//
+// Start should only be called once.
+func (a *Archiver) Start() {
+	go func() {
+		defer close(a.done)
+		defer a.cancel()
+
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case t, ok := <-a.tick:
+				if !ok {
+					return
+				}
+				stats := a.run(t)
+				if stats.Error != nil {
+					a.log.Warn(a.ctx, "error archiving build logs once", slog.Error(stats.Error))
+				}
+				if a.stats != nil {
+					select {
+					case <-a.ctx.Done():
+						return
+					case a.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the archiver.
+func (a *Archiver) Close() {
+	a.cancel()
+	<-a.done
+}
+
+func (a *Archiver) run(t time.Time) Stats {
+	stats := Stats{
+		ArchivedJobIDs: []uuid.UUID{},
+	}
+
+	jobIDs, err := a.db.GetProvisionerJobsWithExpiredLogs(a.ctx, database.GetProvisionerJobsWithExpiredLogsParams{
+		Now:              dbtime.Time(t),
+		DefaultRetention: int64(a.defaultRetention),
+		LimitCount:       MaxJobsPerRun,
+	})
+	if err != nil {
+		stats.Error = xerrors.Errorf("get provisioner jobs with expired logs: %w", err)
+		return stats
+	}
+
+	for _, jobID := range jobIDs {
+		err := a.archive(t, jobID)
+		if err != nil {
+			// Keep the logs so the next run retries the job.
+			a.log.Warn(a.ctx, "failed to archive build logs", slog.F("job_id", jobID), slog.Error(err))
+			continue
+		}
+		stats.ArchivedJobIDs = append(stats.ArchivedJobIDs, jobID)
+	}
+
+	return stats
+}
+
+// archive writes the logs of a job to the store, if any, and removes them
+// from the database.
+func (a *Archiver) archive(t time.Time, jobID uuid.UUID) error {
+	logs, err := a.db.GetProvisionerLogsAfterID(a.ctx, database.GetProvisionerLogsAfterIDParams{
+		JobID: jobID,
+	})
+	if err != nil {
+		return xerrors.Errorf("get provisioner job logs: %w", err)
+	}
+
+	var key string
+	if a.store != nil && len(logs) > 0 {
+		key = ObjectKey(jobID)
+		err = Write(a.ctx, a.store, key, slice.List(logs, db2sdk.ProvisionerJobLog))
+		if err != nil {
+			return xerrors.Errorf("write archive: %w", err)
+		}
+	}
+
+	return a.db.InTx(func(tx database.Store) error {
+		_, err := tx.DeleteProvisionerJobLogsByJobID(a.ctx, jobID)
+		if err != nil {
+			return xerrors.Errorf("delete provisioner job logs: %w", err)
+		}
+		err = tx.InsertProvisionerJobLogArchive(a.ctx, database.InsertProvisionerJobLogArchiveParams{
+			JobID:      jobID,
+			ArchivedAt: dbtime.Time(t),
+			ObjectKey:  key,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert provisioner job log archive: %w", err)
+		}
+		return nil
+	}, nil)
+}
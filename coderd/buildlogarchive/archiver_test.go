@@ -0,0 +1,86 @@
+package buildlogarchive_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/buildlogarchive"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestArchiver(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{})
+		now  = dbtime.Now()
+	)
+	first := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OwnerID:        user.ID,
+		OrganizationID: org.ID,
+	}).Succeeded(dbfake.WithJobCompletedAt(now.Add(-48 * time.Hour))).Do()
+	latest := dbfake.WorkspaceBuild(t, db, first.Workspace).Seed(database.WorkspaceBuild{
+		BuildNumber: 2,
+	}).Succeeded(dbfake.WithJobCompletedAt(now.Add(-48 * time.Hour))).Do()
+	expiredLog := dbgen.ProvisionerJobLog(t, db, database.ProvisionerJobLog{JobID: first.Build.JobID, Output: "expired"})
+	dbgen.ProvisionerJobLog(t, db, database.ProvisionerJobLog{JobID: latest.Build.JobID, Output: "latest"})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	store, err := buildlogarchive.NewDirStore(t.TempDir())
+	require.NoError(t, err)
+	tickCh := make(chan time.Time)
+	statsCh := make(chan buildlogarchive.Stats)
+	archiver := buildlogarchive.New(ctx, db, store, 24*time.Hour, log, tickCh).WithStatsChannel(statsCh)
+	archiver.Start()
+	t.Cleanup(archiver.Close)
+
+	tickCh <- now
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Contains(t, stats.ArchivedJobIDs, first.Build.JobID)
+	require.NotContains(t, stats.ArchivedJobIDs, latest.Build.JobID)
+
+	//nolint:gocritic // Test asserts on provisioner jobs of all users.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	// The logs of the older build are moved to the archive.
+	logs, err := db.GetProvisionerLogsAfterID(sysCtx, database.GetProvisionerLogsAfterIDParams{JobID: first.Build.JobID})
+	require.NoError(t, err)
+	require.Empty(t, logs)
+	archive, err := db.GetProvisionerJobLogArchiveByJobID(sysCtx, first.Build.JobID)
+	require.NoError(t, err)
+	require.Equal(t, buildlogarchive.ObjectKey(first.Build.JobID), archive.ObjectKey)
+	archived, err := buildlogarchive.Read(ctx, store, archive.ObjectKey)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	require.Equal(t, expiredLog.ID, archived[0].ID)
+	require.Equal(t, "expired", archived[0].Output)
+
+	// The logs of the latest build are kept regardless of their age.
+	logs, err = db.GetProvisionerLogsAfterID(sysCtx, database.GetProvisionerLogsAfterIDParams{JobID: latest.Build.JobID})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+
+	// Archived jobs are not processed again.
+	tickCh <- now
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.NotContains(t, stats.ArchivedJobIDs, first.Build.JobID)
+}
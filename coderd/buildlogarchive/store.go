@@ -0,0 +1,127 @@
+package buildlogarchive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Store persists archived build logs. Implementations must be safe for
+// concurrent use, and Put must replace any existing object with the same key
+// so archiving a job again after a crash is harmless.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// ObjectKey returns the key the logs of a provisioner job are archived under.
+func ObjectKey(jobID uuid.UUID) string {
+	return jobID.String() + ".json.gz"
+}
+
+// DirStore is a Store that keeps each archive as a file in a directory. The
+// directory is typically an object storage bucket mounted into the
+// filesystem.
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore returns a DirStore rooted at dir, creating the directory if it
+// does not exist.
+func NewDirStore(dir string) (*DirStore, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("create build log archive directory: %w", err)
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+func (s *DirStore) Put(_ context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so a partially written archive is
+	// never visible under its final key.
+	f, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return xerrors.Errorf("create temporary file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	if _, err := io.Copy(f, r); err != nil {
+		return xerrors.Errorf("write archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return xerrors.Errorf("close archive: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return xerrors.Errorf("rename archive: %w", err)
+	}
+	return nil
+}
+
+func (s *DirStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("open archive: %w", err)
+	}
+	return f, nil
+}
+
+func (s *DirStore) path(key string) (string, error) {
+	if key == "" || filepath.Base(key) != key || key[0] == '.' {
+		return "", xerrors.Errorf("invalid archive key %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+// Write archives logs under key as gzip-compressed JSON.
+func Write(ctx context.Context, store Store, key string, logs []codersdk.ProvisionerJobLog) error {
+	pr, pw := io.Pipe()
+	go func() {
+		zw := gzip.NewWriter(pw)
+		err := json.NewEncoder(zw).Encode(logs)
+		if err == nil {
+			err = zw.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	err := store.Put(ctx, key, pr)
+	// Unblock the encoder if Put returned without draining the pipe.
+	_ = pr.CloseWithError(io.ErrClosedPipe)
+	return err
+}
+
+// Read returns the logs archived under key.
+func Read(ctx context.Context, store Store, key string) ([]codersdk.ProvisionerJobLog, error) {
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	zr, err := gzip.NewReader(rc)
+	if err != nil {
+		return nil, xerrors.Errorf("decompress archive: %w", err)
+	}
+	defer zr.Close()
+	var logs []codersdk.ProvisionerJobLog
+	if err := json.NewDecoder(zr).Decode(&logs); err != nil {
+		return nil, xerrors.Errorf("decode archive: %w", err)
+	}
+	return logs, nil
+}
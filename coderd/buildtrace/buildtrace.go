@@ -0,0 +1,248 @@
+// Package buildtrace exports the lifecycle of workspace builds as
+// OpenTelemetry traces.
+//
+// A trace is exported once a build is finished, which is when its provisioner
+// job completed for builds without agents, or when all agents are ready. The
+// trace is recorded from the same timings that are returned by the workspace
+// build timings endpoint, so the spans use their historical start and end
+// times:
+//
+//	workspace_build
+//	├── provisioner.queue
+//	├── provisioner.<stage>       (init, plan, graph, apply)
+//	│   └── <action> <resource>
+//	├── agent.connect             (one per agent)
+//	└── agent.script              (one per script run)
+package buildtrace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+// TracerName is the name of the tracer that creates the build spans.
+const TracerName = "coderd.buildtrace"
+
+// Exporter exports workspace builds as traces to a tracer provider.
+type Exporter struct {
+	db     database.Store
+	log    slog.Logger
+	tracer trace.Tracer
+}
+
+// New returns an exporter that records the builds as spans with the tracer
+// provider. If the provider is nil, builds are not exported.
+func New(db database.Store, log slog.Logger, tp trace.TracerProvider) *Exporter {
+	if tp == nil {
+		return nil
+	}
+	return &Exporter{
+		db:     db,
+		log:    log,
+		tracer: tp.Tracer(TracerName),
+	}
+}
+
+// ExportAsync exports the build in the background and logs any error. It is
+// safe to call on a nil exporter.
+func (e *Exporter) ExportAsync(buildID uuid.UUID) {
+	if e == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := e.Export(ctx, buildID)
+		if err != nil {
+			e.log.Warn(ctx, "export workspace build trace",
+				slog.F("workspace_build_id", buildID),
+				slog.Error(err),
+			)
+		}
+	}()
+}
+
+// Export records the spans of the build. Timings with a zero start or end
+// time are skipped.
+func (e *Exporter) Export(ctx context.Context, buildID uuid.UUID) error {
+	if e == nil {
+		return nil
+	}
+	//nolint:gocritic // The exporter reads the timings of any build.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	build, err := e.db.GetWorkspaceBuildByID(ctx, buildID)
+	if err != nil {
+		return xerrors.Errorf("get workspace build: %w", err)
+	}
+	workspace, err := e.db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	job, err := e.db.GetProvisionerJobByID(ctx, build.JobID)
+	if err != nil {
+		return xerrors.Errorf("get provisioner job: %w", err)
+	}
+	provisionerTimings, err := e.db.GetProvisionerJobTimingsByJobID(ctx, build.JobID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get provisioner job timings: %w", err)
+	}
+	scriptTimings, err := e.db.GetWorkspaceAgentScriptTimingsByBuildID(ctx, build.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get workspace agent script timings: %w", err)
+	}
+	resources, err := e.db.GetWorkspaceResourcesByJobID(ctx, build.JobID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get workspace resources: %w", err)
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	agents, err := e.db.GetWorkspaceAgentsByResourceIDs(ctx, resourceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get workspace agents: %w", err)
+	}
+
+	// The build ends when the last agent is ready, or when the job
+	// completed if that is later.
+	end := job.CompletedAt.Time
+	for _, agent := range agents {
+		if agent.ReadyAt.Valid && agent.ReadyAt.Time.After(end) {
+			end = agent.ReadyAt.Time
+		}
+	}
+	for _, t := range scriptTimings {
+		if t.EndedAt.After(end) {
+			end = t.EndedAt
+		}
+	}
+	if end.IsZero() {
+		return xerrors.New("workspace build has not finished")
+	}
+
+	buildAttrs := []attribute.KeyValue{
+		attribute.String("coder.workspace.id", workspace.ID.String()),
+		attribute.String("coder.workspace.name", workspace.Name),
+		attribute.String("coder.workspace.owner_id", workspace.OwnerID.String()),
+		attribute.String("coder.workspace_build.id", build.ID.String()),
+		attribute.Int64("coder.workspace_build.number", int64(build.BuildNumber)),
+		attribute.String("coder.workspace_build.transition", string(build.Transition)),
+		attribute.String("coder.template.id", workspace.TemplateID.String()),
+		attribute.String("coder.template_version.id", build.TemplateVersionID.String()),
+		attribute.String("coder.organization.id", workspace.OrganizationID.String()),
+		attribute.String("coder.provisioner_job.id", job.ID.String()),
+	}
+
+	ctx, root := e.tracer.Start(ctx, "workspace_build",
+		trace.WithNewRoot(),
+		trace.WithTimestamp(job.CreatedAt),
+		trace.WithAttributes(buildAttrs...),
+	)
+	switch {
+	case job.Error.Valid:
+		root.SetStatus(codes.Error, job.Error.String)
+	case job.CanceledAt.Valid:
+		root.SetStatus(codes.Error, "canceled")
+	}
+
+	if job.StartedAt.Valid {
+		e.span(ctx, "provisioner.queue", job.CreatedAt, job.StartedAt.Time, "", buildAttrs)
+	}
+
+	// Provisioner timings are grouped by their stage, in the order the
+	// stages were run.
+	var stages []database.ProvisionerJobTimingStage
+	byStage := map[database.ProvisionerJobTimingStage][]database.ProvisionerJobTiming{}
+	for _, t := range provisionerTimings {
+		if t.StartedAt.IsZero() || t.EndedAt.IsZero() {
+			continue
+		}
+		if _, ok := byStage[t.Stage]; !ok {
+			stages = append(stages, t.Stage)
+		}
+		byStage[t.Stage] = append(byStage[t.Stage], t)
+	}
+	for _, stage := range stages {
+		timings := byStage[stage]
+		start, stop := timings[0].StartedAt, timings[0].EndedAt
+		for _, t := range timings[1:] {
+			if t.StartedAt.Before(start) {
+				start = t.StartedAt
+			}
+			if t.EndedAt.After(stop) {
+				stop = t.EndedAt
+			}
+		}
+		stageCtx, stageSpan := e.tracer.Start(ctx, "provisioner."+string(stage),
+			trace.WithTimestamp(start),
+			trace.WithAttributes(buildAttrs...),
+		)
+		for _, t := range timings {
+			e.span(stageCtx, t.Action+" "+t.Resource, t.StartedAt, t.EndedAt, "", buildAttrs,
+				attribute.String("coder.provisioner.source", t.Source),
+				attribute.String("coder.provisioner.action", t.Action),
+				attribute.String("coder.provisioner.resource", t.Resource),
+			)
+		}
+		stageSpan.End(trace.WithTimestamp(stop))
+	}
+
+	for _, agent := range agents {
+		if !agent.FirstConnectedAt.Valid {
+			continue
+		}
+		e.span(ctx, "agent.connect", agent.CreatedAt, agent.FirstConnectedAt.Time, "", buildAttrs,
+			attribute.String("coder.workspace_agent.id", agent.ID.String()),
+			attribute.String("coder.workspace_agent.name", agent.Name),
+		)
+	}
+
+	for _, t := range scriptTimings {
+		if t.StartedAt.IsZero() || t.EndedAt.IsZero() {
+			continue
+		}
+		var errMsg string
+		if t.Status != database.WorkspaceAgentScriptTimingStatusOk {
+			errMsg = string(t.Status)
+		}
+		e.span(ctx, "agent.script", t.StartedAt, t.EndedAt, errMsg, buildAttrs,
+			attribute.String("coder.workspace_agent.id", t.WorkspaceAgentID.String()),
+			attribute.String("coder.workspace_agent.name", t.WorkspaceAgentName),
+			attribute.String("coder.workspace_agent_script.id", t.ScriptID.String()),
+			attribute.String("coder.workspace_agent_script.display_name", t.DisplayName),
+			attribute.String("coder.workspace_agent_script.stage", string(t.Stage)),
+			attribute.String("coder.workspace_agent_script.status", string(t.Status)),
+			attribute.Int64("coder.workspace_agent_script.exit_code", int64(t.ExitCode)),
+		)
+	}
+
+	root.End(trace.WithTimestamp(end))
+	return nil
+}
+
+// span records a finished span. If errMsg is set, the span has an error
+// status.
+func (e *Exporter) span(ctx context.Context, name string, start, end time.Time, errMsg string, buildAttrs []attribute.KeyValue, attrs ...attribute.KeyValue) {
+	_, span := e.tracer.Start(ctx, name,
+		trace.WithTimestamp(start),
+		trace.WithAttributes(buildAttrs...),
+		trace.WithAttributes(attrs...),
+	)
+	if errMsg != "" {
+		span.SetStatus(codes.Error, errMsg)
+	}
+	span.End(trace.WithTimestamp(end))
+}
@@ -0,0 +1,135 @@
+package buildtrace_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/buildtrace"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{})
+		now  = dbtime.Now()
+	)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	}).Do()
+
+	for _, timing := range []database.ProvisionerJobTiming{
+		{Stage: database.ProvisionerJobTimingStagePlan, Action: "create", Resource: "docker_container.main", StartedAt: now.Add(-3 * time.Minute), EndedAt: now.Add(-2 * time.Minute)},
+		{Stage: database.ProvisionerJobTimingStageApply, Action: "create", Resource: "docker_container.main", StartedAt: now.Add(-2 * time.Minute), EndedAt: now.Add(-time.Minute)},
+		{Stage: database.ProvisionerJobTimingStageApply, Action: "create", Resource: "docker_volume.home", StartedAt: now.Add(-2 * time.Minute), EndedAt: now.Add(-90 * time.Second)},
+	} {
+		_, err := db.InsertProvisionerJobTimings(ctx, database.InsertProvisionerJobTimingsParams{
+			JobID:     r.Build.JobID,
+			StartedAt: []time.Time{timing.StartedAt},
+			EndedAt:   []time.Time{timing.EndedAt},
+			Stage:     []database.ProvisionerJobTimingStage{timing.Stage},
+			Source:    []string{"docker"},
+			Action:    []string{timing.Action},
+			Resource:  []string{timing.Resource},
+		})
+		require.NoError(t, err)
+	}
+
+	resource := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: r.Build.JobID})
+	agent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{
+		ResourceID:       resource.ID,
+		CreatedAt:        now.Add(-time.Minute),
+		FirstConnectedAt: sql.NullTime{Time: now.Add(-30 * time.Second), Valid: true},
+		LifecycleState:   database.WorkspaceAgentLifecycleStateReady,
+		StartedAt:        sql.NullTime{Time: now.Add(-30 * time.Second), Valid: true},
+		ReadyAt:          sql.NullTime{Time: now.Add(time.Minute), Valid: true},
+	})
+	script := dbgen.WorkspaceAgentScript(t, db, database.WorkspaceAgentScript{
+		WorkspaceAgentID: agent.ID,
+		DisplayName:      "Install dependencies",
+	})
+	dbgen.WorkspaceAgentScriptTiming(t, db, database.WorkspaceAgentScriptTiming{
+		ScriptID:  script.ID,
+		StartedAt: now.Add(-20 * time.Second),
+		EndedAt:   now.Add(time.Minute),
+		ExitCode:  1,
+		Status:    database.WorkspaceAgentScriptTimingStatusExitFailure,
+	})
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	defer func() { _ = tp.Shutdown(ctx) }()
+
+	exporter := buildtrace.New(db, testutil.Logger(t), tp)
+	err := exporter.Export(ctx, r.Build.ID)
+	require.NoError(t, err)
+
+	spans := map[string][]sdktrace.ReadOnlySpan{}
+	for _, span := range sr.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+	require.Len(t, spans["workspace_build"], 1)
+	require.Len(t, spans["provisioner.queue"], 1)
+	require.Len(t, spans["provisioner.plan"], 1)
+	require.Len(t, spans["provisioner.apply"], 1)
+	require.Len(t, spans["create docker_container.main"], 2)
+	require.Len(t, spans["create docker_volume.home"], 1)
+	require.Len(t, spans["agent.connect"], 1)
+	require.Len(t, spans["agent.script"], 1)
+
+	root := spans["workspace_build"][0]
+	require.False(t, root.Parent().IsValid())
+	require.Contains(t, root.Attributes(), attribute.String("coder.workspace.id", r.Workspace.ID.String()))
+	require.Contains(t, root.Attributes(), attribute.String("coder.workspace_build.id", r.Build.ID.String()))
+	// The build ends once the agent is ready.
+	require.WithinDuration(t, now.Add(time.Minute), root.EndTime(), time.Millisecond)
+
+	// Every span has the IDs of the build.
+	for _, span := range sr.Ended() {
+		require.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID())
+		require.Contains(t, span.Attributes(), attribute.String("coder.workspace_build.id", r.Build.ID.String()))
+	}
+
+	// Resources are children of the stage they were run in.
+	apply := spans["provisioner.apply"][0]
+	require.Equal(t, root.SpanContext().SpanID(), apply.Parent().SpanID())
+	require.WithinDuration(t, now.Add(-2*time.Minute), apply.StartTime(), time.Millisecond)
+	require.WithinDuration(t, now.Add(-time.Minute), apply.EndTime(), time.Millisecond)
+	require.Equal(t, apply.SpanContext().SpanID(), spans["create docker_volume.home"][0].Parent().SpanID())
+
+	connect := spans["agent.connect"][0]
+	require.Contains(t, connect.Attributes(), attribute.String("coder.workspace_agent.id", agent.ID.String()))
+	require.WithinDuration(t, now.Add(-time.Minute), connect.StartTime(), time.Millisecond)
+
+	scriptSpan := spans["agent.script"][0]
+	require.Equal(t, codes.Error, scriptSpan.Status().Code)
+	require.Contains(t, scriptSpan.Attributes(), attribute.String("coder.workspace_agent_script.display_name", "Install dependencies"))
+
+	// Unknown builds can't be exported.
+	err = exporter.Export(ctx, uuid.New())
+	require.Error(t, err)
+}
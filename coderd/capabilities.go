@@ -0,0 +1,51 @@
+package coderd
+
+import (
+	"net/http"
+
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	provisionerdproto "github.com/coder/coder/v2/provisionerd/proto"
+	tailnetproto "github.com/coder/coder/v2/tailnet/proto"
+)
+
+// @Summary Get deployment capabilities
+// @ID get-deployment-capabilities
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.Capabilities
+// @Router /api/v2/capabilities [get]
+func (api *API) capabilities(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	features := make(map[codersdk.FeatureName]bool, len(codersdk.FeatureNames))
+	for _, name := range codersdk.FeatureNames {
+		features[name] = api.Entitlements.Enabled(name)
+	}
+
+	experiments := api.Experiments
+	if experiments == nil {
+		experiments = codersdk.Experiments{}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Capabilities{
+		APIVersions: codersdk.CapabilitiesAPIVersions{
+			Server:      buildinfo.Version(),
+			Agent:       agentproto.CurrentVersion.String(),
+			Provisioner: provisionerdproto.CurrentVersion.String(),
+			Tailnet:     tailnetproto.CurrentVersion.String(),
+		},
+		Experiments: experiments,
+		Features:    features,
+		Flags: map[codersdk.CapabilityFlag]bool{
+			codersdk.CapabilityFlagDynamicParameters: true,
+			codersdk.CapabilityFlagPrebuilds:         api.Entitlements.Enabled(codersdk.FeatureWorkspacePrebuilds),
+			codersdk.CapabilityFlagPathApps:          !api.DeploymentValues.DisablePathApps.Value(),
+			codersdk.CapabilityFlagSubdomainApps:     api.AppHostname != "",
+			codersdk.CapabilityFlagWorkspaceSharing:  !api.DeploymentValues.DisableWorkspaceSharing.Value(),
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	provisionerdproto "github.com/coder/coder/v2/provisionerd/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	cfg := coderdtest.DeploymentValues(t)
+	cfg.Experiments = []string{string(codersdk.ExperimentExample)}
+	cfg.DisablePathApps = true
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues: cfg,
+		AppHostname:      "*.test.coder.com",
+	})
+	_ = coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	capabilities, err := client.Capabilities(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, buildinfo.Version(), capabilities.APIVersions.Server)
+	require.Equal(t, provisionerdproto.CurrentVersion.String(), capabilities.APIVersions.Provisioner)
+	require.NotEmpty(t, capabilities.APIVersions.Agent)
+	require.NotEmpty(t, capabilities.APIVersions.Tailnet)
+	require.True(t, capabilities.Experiments.Enabled(codersdk.ExperimentExample))
+
+	// Every feature and flag is reported so that clients can tell a disabled
+	// capability apart from one that the server does not know about.
+	require.Len(t, capabilities.Features, len(codersdk.FeatureNames))
+	require.Len(t, capabilities.Flags, len(codersdk.CapabilityFlags))
+	require.False(t, capabilities.FeatureEnabled(codersdk.FeatureWorkspacePrebuilds))
+	require.False(t, capabilities.Enabled(codersdk.CapabilityFlagPrebuilds))
+	require.True(t, capabilities.Enabled(codersdk.CapabilityFlagDynamicParameters))
+	require.True(t, capabilities.Enabled(codersdk.CapabilityFlagSubdomainApps))
+	require.False(t, capabilities.Enabled(codersdk.CapabilityFlagPathApps))
+	require.False(t, capabilities.Enabled("unknown"))
+}
@@ -0,0 +1,88 @@
+package cloudresources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/util/xio"
+)
+
+// ErrUnsupported is returned by a Checker that cannot check resources of the
+// given type.
+var ErrUnsupported = xerrors.New("unsupported resource type")
+
+// Resource is a resource at a cloud provider that was created by a workspace.
+type Resource struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	// Type is the type of the resource, e.g. "aws_instance".
+	Type string `json:"type"`
+	// Name is the name of the resource in the template.
+	Name string `json:"name"`
+	// CloudID is the identifier of the resource at its cloud provider.
+	CloudID string `json:"cloud_id"`
+}
+
+// Checker checks whether resources still exist at their cloud provider.
+// Implementations return ErrUnsupported for resource types they cannot
+// check.
+type Checker interface {
+	Exists(ctx context.Context, resource Resource) (bool, error)
+}
+
+// WebhookResponse is the body the cloud resource checker webhook responds
+// with.
+type WebhookResponse struct {
+	Exists bool `json:"exists"`
+}
+
+// WebhookChecker is a Checker that delegates every check to a webhook, so
+// any cloud provider can be integrated without changes to Coder.
+type WebhookChecker struct {
+	client *http.Client
+	url    string
+}
+
+var _ Checker = (*WebhookChecker)(nil)
+
+// NewWebhookChecker returns a Checker that POSTs every Resource to url as
+// JSON. The webhook responds with a WebhookResponse, or with 501 Not
+// Implemented if it cannot check resources of the type.
+func NewWebhookChecker(client *http.Client, url string) *WebhookChecker {
+	return &WebhookChecker{
+		client: client,
+		url:    url,
+	}
+}
+
+func (c *WebhookChecker) Exists(ctx context.Context, resource Resource) (bool, error) {
+	body, err := json.Marshal(resource)
+	if err != nil {
+		return false, xerrors.Errorf("marshal cloud resource: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return false, xerrors.Errorf("create cloud resource check request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, xerrors.Errorf("send cloud resource check request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotImplemented {
+		return false, ErrUnsupported
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return false, xerrors.Errorf("cloud resource checker responded with status %d: %s", res.StatusCode, xio.ReadErrorBody(res.Body))
+	}
+	var resp WebhookResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return false, xerrors.Errorf("decode cloud resource check response: %w", err)
+	}
+	return resp.Exists, nil
+}
@@ -0,0 +1,212 @@
+// Package cloudresources verifies that the cloud resources created by a
+// workspace are gone once the workspace is deleted.
+//
+// The builds of a workspace report the identifiers of their resources at the
+// cloud provider, which are recorded in the database. After a workspace was
+// deleted, the verifier asks a pluggable Checker whether each identifier
+// still exists, and flags those that do as leaked.
+package cloudresources
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+const (
+	// PollInterval is how often the verifier looks for resources of deleted
+	// workspaces.
+	PollInterval = 5 * time.Minute
+
+	// GracePeriod is how long after a deletion build completed its resources
+	// are verified. Some providers delete resources asynchronously, so they
+	// may briefly remain visible after the deletion.
+	GracePeriod = 15 * time.Minute
+
+	// CheckerTimeout bounds a single call to the checker.
+	CheckerTimeout = 30 * time.Second
+
+	// MaxResourcesPerRun is the maximum number of resources verified in a
+	// single run.
+	MaxResourcesPerRun = 100
+)
+
+// Verifier verifies the resources of deleted workspaces on every tick from
+// its channel.
+type Verifier struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db      database.Store
+	log     slog.Logger
+	checker Checker
+	tick    <-chan time.Time
+	stats   chan<- Stats
+}
+
+// Stats contains statistics about the last run of the verifier.
+type Stats struct {
+	// Deleted contains the resources that no longer exist.
+	Deleted []Resource
+	// Leaked contains the resources that still exist.
+	Leaked []Resource
+	// Unknown contains the resources that could not be checked.
+	Unknown []Resource
+	// Error is set if the resources could not be loaded or a verification
+	// result could not be stored. Resources the checker failed on are
+	// reported in Unknown instead.
+	Error error
+}
+
+// New returns a new verifier that checks resources with checker.
+func New(ctx context.Context, db database.Store, log slog.Logger, checker Checker, tick <-chan time.Time) *Verifier {
+	//nolint:gocritic // The verifier checks resources of all workspaces.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Verifier{
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		db:      db,
+		log:     log,
+		checker: checker,
+		tick:    tick,
+		stats:   nil,
+	}
+}
+
+// WithStatsChannel will cause Verifier to push a Stats to ch after every
+// tick. This push is blocking, so if ch is not read, the verifier will hang.
+// This should only be used in tests.
+func (v *Verifier) WithStatsChannel(ch chan<- Stats) *Verifier {
+	v.stats = ch
+	return v
+}
+
+// Start will cause the verifier to verify resources on every tick from its
+// channel. It will stop when its context is Done, or when its channel is
+// closed.
+//
+// Start should only be called once.
+func (v *Verifier) Start() {
+	go func() {
+		defer close(v.done)
+		defer v.cancel()
+
+		for {
+			select {
+			case <-v.ctx.Done():
+				return
+			case t, ok := <-v.tick:
+				if !ok {
+					return
+				}
+				stats := v.run(t)
+				if stats.Error != nil {
+					v.log.Warn(v.ctx, "error verifying cloud resources once", slog.Error(stats.Error))
+				}
+				if v.stats != nil {
+					select {
+					case <-v.ctx.Done():
+						return
+					case v.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the verifier.
+func (v *Verifier) Close() {
+	v.cancel()
+	<-v.done
+}
+
+func (v *Verifier) run(t time.Time) Stats {
+	stats := Stats{
+		Deleted: []Resource{},
+		Leaked:  []Resource{},
+		Unknown: []Resource{},
+	}
+
+	rows, err := v.db.GetUnverifiedWorkspaceCloudResources(v.ctx, database.GetUnverifiedWorkspaceCloudResourcesParams{
+		CompletedBefore: dbtime.Time(t.Add(-GracePeriod)),
+		LimitCount:      MaxResourcesPerRun,
+	})
+	if err != nil {
+		stats.Error = xerrors.Errorf("get unverified workspace cloud resources: %w", err)
+		return stats
+	}
+
+	for _, row := range rows {
+		resource := Resource{
+			WorkspaceID: row.WorkspaceCloudResource.WorkspaceID,
+			Type:        row.WorkspaceCloudResource.ResourceType,
+			Name:        row.WorkspaceCloudResource.ResourceName,
+			CloudID:     row.WorkspaceCloudResource.CloudID,
+		}
+		log := v.log.With(
+			slog.F("workspace_id", resource.WorkspaceID),
+			slog.F("resource_type", resource.Type),
+			slog.F("cloud_id", resource.CloudID),
+		)
+
+		status, verificationError := v.verify(resource)
+		switch status {
+		case database.CloudResourceVerificationStatusDeleted:
+			stats.Deleted = append(stats.Deleted, resource)
+		case database.CloudResourceVerificationStatusLeaked:
+			log.Warn(v.ctx, "cloud resource still exists after workspace deletion", slog.F("deletion_build_id", row.DeletionBuildID))
+			stats.Leaked = append(stats.Leaked, resource)
+		default:
+			log.Debug(v.ctx, "unable to verify cloud resource", slog.F("error", verificationError))
+			stats.Unknown = append(stats.Unknown, resource)
+		}
+
+		// Resources that could not be checked are not retried, so that a
+		// checker that keeps failing cannot block the verification of other
+		// resources.
+		err := v.db.UpdateWorkspaceCloudResourceVerification(v.ctx, database.UpdateWorkspaceCloudResourceVerificationParams{
+			VerificationStatus:  status,
+			VerificationBuildID: uuid.NullUUID{UUID: row.DeletionBuildID, Valid: true},
+			VerificationError:   verificationError,
+			VerifiedAt:          sql.NullTime{Time: dbtime.Time(t), Valid: true},
+			WorkspaceID:         resource.WorkspaceID,
+			ResourceType:        resource.Type,
+			CloudID:             resource.CloudID,
+		})
+		if err != nil {
+			stats.Error = xerrors.Errorf("update workspace cloud resource verification: %w", err)
+			return stats
+		}
+	}
+
+	return stats
+}
+
+// verify checks whether resource still exists, and returns its verification
+// status along with the reason it could not be checked, if any.
+func (v *Verifier) verify(resource Resource) (database.CloudResourceVerificationStatus, string) {
+	ctx, cancel := context.WithTimeout(v.ctx, CheckerTimeout)
+	defer cancel()
+	exists, err := v.checker.Exists(ctx, resource)
+	switch {
+	case xerrors.Is(err, ErrUnsupported):
+		return database.CloudResourceVerificationStatusUnknown, "The checker does not support resources of this type."
+	case err != nil:
+		return database.CloudResourceVerificationStatusUnknown, err.Error()
+	case exists:
+		return database.CloudResourceVerificationStatusLeaked, ""
+	default:
+		return database.CloudResourceVerificationStatusDeleted, ""
+	}
+}
@@ -0,0 +1,147 @@
+package cloudresources_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/cloudresources"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestVerifier(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{})
+		now  = dbtime.Now()
+	)
+	createWorkspace := func(resources ...cloudresources.Resource) database.WorkspaceTable {
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+		}).Do()
+		for _, resource := range resources {
+			err := db.UpsertWorkspaceCloudResource(t.Context(), database.UpsertWorkspaceCloudResourceParams{
+				WorkspaceID:      r.Workspace.ID,
+				ResourceType:     resource.Type,
+				CloudID:          resource.CloudID,
+				ResourceName:     resource.Name,
+				WorkspaceBuildID: r.Build.ID,
+				Now:              now,
+			})
+			require.NoError(t, err)
+		}
+		return r.Workspace
+	}
+
+	// The checker reports instances as still existing and volumes as gone,
+	// and does not support any other type.
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var resource cloudresources.Resource
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&resource)) {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch resource.Type {
+		case "aws_instance":
+			_ = json.NewEncoder(rw).Encode(cloudresources.WebhookResponse{Exists: true})
+		case "aws_ebs_volume":
+			_ = json.NewEncoder(rw).Encode(cloudresources.WebhookResponse{Exists: false})
+		default:
+			rw.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	deleted := createWorkspace(
+		cloudresources.Resource{Type: "aws_instance", Name: "dev", CloudID: "i-0123456789abcdef0"},
+		cloudresources.Resource{Type: "aws_ebs_volume", Name: "home", CloudID: "vol-0123456789abcdef0"},
+		cloudresources.Resource{Type: "aws_s3_bucket", Name: "cache", CloudID: "cache-bucket"},
+	)
+	running := createWorkspace(
+		cloudresources.Resource{Type: "aws_instance", Name: "dev", CloudID: "i-0fedcba9876543210"},
+	)
+	deletion := dbfake.WorkspaceBuild(t, db, deleted).Seed(database.WorkspaceBuild{
+		Transition:  database.WorkspaceTransitionDelete,
+		BuildNumber: 2,
+	}).Succeeded(dbfake.WithJobCompletedAt(now)).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	authzDB := dbauthz.New(db, rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry()), log, coderdtest.AccessControlStorePointer())
+	checker := cloudresources.NewWebhookChecker(srv.Client(), srv.URL)
+	tickCh := make(chan time.Time)
+	statsCh := make(chan cloudresources.Stats)
+	verifier := cloudresources.New(ctx, authzDB, log, checker, tickCh).WithStatsChannel(statsCh)
+	verifier.Start()
+	t.Cleanup(verifier.Close)
+
+	// Resources are not verified during the grace period.
+	tickCh <- now.Add(time.Minute)
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.Deleted)
+	require.Empty(t, stats.Leaked)
+	require.Empty(t, stats.Unknown)
+
+	verifiedAt := now.Add(cloudresources.GracePeriod + time.Minute)
+	tickCh <- verifiedAt
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []cloudresources.Resource{{WorkspaceID: deleted.ID, Type: "aws_ebs_volume", Name: "home", CloudID: "vol-0123456789abcdef0"}}, stats.Deleted)
+	require.Equal(t, []cloudresources.Resource{{WorkspaceID: deleted.ID, Type: "aws_instance", Name: "dev", CloudID: "i-0123456789abcdef0"}}, stats.Leaked)
+	require.Equal(t, []cloudresources.Resource{{WorkspaceID: deleted.ID, Type: "aws_s3_bucket", Name: "cache", CloudID: "cache-bucket"}}, stats.Unknown)
+
+	resources, err := db.GetWorkspaceCloudResourcesByWorkspaceID(ctx, deleted.ID)
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+	for _, resource := range resources {
+		require.Equal(t, uuid.NullUUID{UUID: deletion.Build.ID, Valid: true}, resource.VerificationBuildID)
+		require.True(t, resource.VerifiedAt.Valid)
+		require.WithinDuration(t, verifiedAt, resource.VerifiedAt.Time, time.Second)
+	}
+
+	leaked, err := db.GetLeakedWorkspaceCloudResources(ctx)
+	require.NoError(t, err)
+	require.Len(t, leaked, 1)
+	require.Equal(t, "i-0123456789abcdef0", leaked[0].WorkspaceCloudResource.CloudID)
+	require.Equal(t, deleted.Name, leaked[0].WorkspaceName)
+
+	// The resources of the running workspace are never verified, and
+	// verified resources are not checked again.
+	tickCh <- verifiedAt.Add(time.Minute)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.Deleted)
+	require.Empty(t, stats.Leaked)
+	require.Empty(t, stats.Unknown)
+
+	resources, err = db.GetWorkspaceCloudResourcesByWorkspaceID(ctx, running.ID)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	require.Equal(t, database.CloudResourceVerificationStatusUnverified, resources[0].VerificationStatus)
+}
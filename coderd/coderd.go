@@ -51,9 +51,12 @@ import (
 	_ "github.com/coder/coder/v2/coderd/apidoc" // Used for swagger docs.
 	"github.com/coder/coder/v2/coderd/appearance"
 	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/autobuild"
 	"github.com/coder/coder/v2/coderd/awsidentity"
 	"github.com/coder/coder/v2/coderd/azureidentity"
 	"github.com/coder/coder/v2/coderd/boundaryusage"
+	"github.com/coder/coder/v2/coderd/buildlogarchive"
+	"github.com/coder/coder/v2/coderd/buildtrace"
 	"github.com/coder/coder/v2/coderd/connectionlog"
 	"github.com/coder/coder/v2/coderd/cryptokeys"
 	"github.com/coder/coder/v2/coderd/database"
@@ -224,6 +227,9 @@ type Options struct {
 	UserQuietHoursScheduleStore    *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	AccessControlStore             *atomic.Pointer[dbauthz.AccessControlStore]
 	UsageInserter                  *atomic.Pointer[usage.Inserter]
+	// LifecycleExecutor is set once the autobuild executor of this replica is
+	// running, so that its status can be served by the debug endpoints.
+	LifecycleExecutor *atomic.Pointer[autobuild.Executor]
 	// CoordinatorResumeTokenProvider is used to provide and validate resume
 	// tokens issued by and passed to the coordinator DRPC API.
 	CoordinatorResumeTokenProvider tailnet.ResumeTokenProvider
@@ -244,6 +250,12 @@ type Options struct {
 	APIRateLimit   int
 	LoginRateLimit int
 	FilesRateLimit int
+	// WorkspaceHeartbeatRateLimit is the minutely rate limit of workspace
+	// heartbeats per user and workspace.
+	WorkspaceHeartbeatRateLimit int
+	// WorkspaceBuildRateLimit limits endpoints that create workspace builds
+	// per token and per IP address. It is disabled if unset.
+	WorkspaceBuildRateLimit httpmw.BuildRateLimitConfig
 
 	MetricsCacheRefreshInterval time.Duration
 	AgentStatsRefreshInterval   time.Duration
@@ -318,13 +330,24 @@ type Options struct {
 	// (a *NATSCA); VerifyingKey returns a specific CA by sequence. The key
 	// rotator is the sole creator of nats_ca rows, so this cache is read-only.
 	NATSCACache cryptokeys.SigningKeycache
-	Clock       quartz.Clock
+	// SSHHostCAKeyCache serves the CA that signs workspace agent SSH host
+	// certificates. Its keys are ed25519 seeds; see cryptokeys.SSHHostCASigner.
+	SSHHostCAKeyCache cryptokeys.SigningKeycache
+	Clock             quartz.Clock
 	// Acquirer acquires provisioner jobs. Defaults to provisionerdserver.Acquirer
 	// backed by Database and Pubsub.
 	Acquirer *provisionerdserver.Acquirer
 
 	// WebPushDispatcher is a way to send notifications over Web Push.
 	WebPushDispatcher webpush.Dispatcher
+
+	// BuildLogArchive stores provisioner job logs removed from the database
+	// by the build log retention. Nil if archiving is disabled.
+	BuildLogArchive buildlogarchive.Store
+
+	// WorkspaceBuildTracerProvider exports finished workspace builds as
+	// traces. Builds are not exported if it is nil.
+	WorkspaceBuildTracerProvider trace.TracerProvider
 }
 
 // @title Coder API
@@ -455,6 +478,9 @@ func New(options *Options) *API {
 	if options.FilesRateLimit == 0 {
 		options.FilesRateLimit = 12
 	}
+	if options.WorkspaceHeartbeatRateLimit == 0 {
+		options.WorkspaceHeartbeatRateLimit = 12
+	}
 	if options.Clock == nil {
 		options.Clock = quartz.NewReal()
 	}
@@ -506,6 +532,9 @@ func New(options *Options) *API {
 		inserter := usage.NewAGPLInserter()
 		options.UsageInserter.Store(&inserter)
 	}
+	if options.LifecycleExecutor == nil {
+		options.LifecycleExecutor = &atomic.Pointer[autobuild.Executor]{}
+	}
 	if options.OneTimePasscodeValidityPeriod == 0 {
 		options.OneTimePasscodeValidityPeriod = 20 * time.Minute
 	}
@@ -601,6 +630,17 @@ func New(options *Options) *API {
 		}
 	}
 
+	if options.SSHHostCAKeyCache == nil {
+		options.SSHHostCAKeyCache, err = cryptokeys.NewSigningCache(ctx,
+			options.Logger.Named("ssh_host_ca_keycache"),
+			fetcher,
+			codersdk.CryptoKeyFeatureSSHHostCA,
+		)
+		if err != nil {
+			options.Logger.Fatal(ctx, "failed to properly instantiate ssh host ca signing cache", slog.Error(err))
+		}
+	}
+
 	if options.CoordinatorResumeTokenProvider == nil {
 		fetcher := &cryptokeys.DBFetcher{
 			DB: options.Database,
@@ -929,6 +969,7 @@ func New(options *Options) *API {
 		api.lifecycleMetrics = agentapi.NewLifecycleMetrics(options.PrometheusRegistry)
 		api.workspaceAgentRPCMetrics = NewWorkspaceAgentRPCMetrics(options.PrometheusRegistry, options.Logger)
 	}
+	api.BuildTraces = buildtrace.New(options.Database, options.Logger.Named("buildtrace"), options.WorkspaceBuildTracerProvider)
 	api.NetworkTelemetryBatcher = tailnet.NewNetworkTelemetryBatcher(
 		quartz.NewReal(),
 		api.Options.NetworkTelemetryBatchFrequency,
@@ -966,6 +1007,7 @@ func New(options *Options) *API {
 	// Initialize the metadata batcher for batching agent metadata updates.
 	batcherOpts := []metadatabatcher.Option{
 		metadatabatcher.WithLogger(options.Logger.Named("metadata_batcher")),
+		metadatabatcher.WithHistory(options.DeploymentValues.Retention.WorkspaceAgentMetadataHistory.Value() > 0),
 	}
 	batcherOpts = append(batcherOpts, options.MetadataBatcherOptions...)
 	api.metadataBatcher, err = metadatabatcher.NewBatcher(
@@ -1070,6 +1112,12 @@ func New(options *Options) *API {
 	// API rate limit middleware. The counter is local and not shared between
 	// replicas or instances of this middleware.
 	apiRateLimiter := httpmw.RateLimit(options.APIRateLimit, time.Minute)
+	buildRateLimitConfig := options.WorkspaceBuildRateLimit
+	if buildRateLimitConfig.Clock == nil {
+		buildRateLimitConfig.Clock = options.Clock
+	}
+	buildRateLimitConfig.Limited = httpmw.NewBuildRateLimitMetrics(options.PrometheusRegistry)
+	buildRateLimiter := httpmw.BuildRateLimit(buildRateLimitConfig)
 
 	// Register DERP on expvar HTTP handler, which we serve below in the router, c.f. expvar.Handler()
 	expDERPOnce.Do(func() {
@@ -1274,7 +1322,7 @@ func New(options *Options) *API {
 
 			r.Route("/{user}", func(r chi.Router) {
 				r.Use(httpmw.ExtractOrganizationMembersParam(options.Database, api.HTTPAuth.Authorize))
-				r.Post("/", api.tasksCreate)
+				r.With(buildRateLimiter).Post("/", api.tasksCreate)
 
 				r.Route("/{task}", func(r chi.Router) {
 					r.Use(httpmw.ExtractTaskParam(options.Database))
@@ -1497,10 +1545,15 @@ func New(options *Options) *API {
 		r.Get("/auth/scopes", api.listExternalScopes)
 
 		r.Get("/buildinfo", buildInfoHandler(buildInfo))
+		r.Route("/capabilities", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.capabilities)
+		})
 		// /regions is overridden in the enterprise version
 		r.Group(func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/regions", api.regions)
+			r.Get("/regions/recommendation", api.regionRecommendation)
 		})
 		r.Route("/derp-map", func(r chi.Router) {
 			// r.Use(apiKeyMiddleware)
@@ -1511,6 +1564,9 @@ func New(options *Options) *API {
 			r.Get("/config", api.deploymentValues)
 			r.Get("/stats", api.deploymentStats)
 			r.Get("/ssh", api.sshConfig)
+			r.Get("/ssh-host-ca", api.sshHostCA)
+			r.Get("/provisioner-cache", api.provisionerCacheSettings)
+			r.Put("/provisioner-cache", api.putProvisionerCacheSettings)
 		})
 		r.Route("/experiments", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -1518,6 +1574,18 @@ func New(options *Options) *API {
 			r.Get("/", api.handleExperimentsGet)
 		})
 		r.Get("/updatecheck", api.updateCheck)
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Post("/offboarding/preview", api.postOffboardingPreview)
+			r.Route("/orphaned-workspaces", func(r chi.Router) {
+				r.Get("/", api.orphanedWorkspaces)
+				r.Route("/{workspace}", func(r chi.Router) {
+					r.Use(httpmw.ExtractWorkspaceParam(options.Database))
+					r.Post("/adopt", api.postAdoptOrphanedWorkspace)
+					r.Post("/delete", api.postDeleteOrphanedWorkspace)
+				})
+			})
+		})
 		r.Route("/audit", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1570,6 +1638,11 @@ func New(options *Options) *API {
 				r.Get("/device", api.externalAuthDeviceByID)
 			})
 		})
+		r.Route("/integrations/vcs", func(r chi.Router) {
+			// Webhook deliveries are authenticated by the signing secrets of
+			// the VCS event mappings instead of an API key.
+			r.Post("/events", api.postVCSEvent)
+		})
 		r.Route("/organizations", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1581,6 +1654,7 @@ func New(options *Options) *API {
 				)
 				r.Get("/", api.organization)
 				r.Post("/templateversions", api.postTemplateVersionsByOrganization)
+				r.Post("/templateversions/validate", api.postValidateTemplateVersion)
 				r.Route("/templates", func(r chi.Router) {
 					r.Post("/", api.postTemplateByOrganization)
 					r.Get("/", api.templatesByOrganization())
@@ -1593,6 +1667,14 @@ func New(options *Options) *API {
 						})
 					})
 				})
+				r.Route("/shared-volumes", func(r chi.Router) {
+					r.Get("/", api.sharedVolumes)
+					r.Post("/", api.postSharedVolume)
+					r.Route("/{sharedvolume}/attachments", func(r chi.Router) {
+						r.Get("/", api.sharedVolumeAttachments)
+						r.Delete("/{attachment}", api.deleteSharedVolumeAttachment)
+					})
+				})
 				r.Get("/paginated-members", api.paginatedMembers)
 				r.Route("/members", func(r chi.Router) {
 					r.Get("/", api.listMembers)
@@ -1620,7 +1702,7 @@ func New(options *Options) *API {
 							r.Delete("/", api.deleteOrganizationMember)
 							r.Put("/roles", api.putMemberRoles)
 							r.Route("/workspaces", func(r chi.Router) {
-								r.Post("/", api.postWorkspacesByOrganization)
+								r.With(buildRateLimiter).Post("/", api.postWorkspacesByOrganization)
 								r.Get("/available-users", api.workspaceAvailableUsers)
 							})
 						})
@@ -1646,9 +1728,51 @@ func New(options *Options) *API {
 					httpmw.ExtractTemplateParam(options.Database),
 				)
 				r.Get("/daus", api.templateDAUs)
+				r.Get("/creation-schema", api.templateCreationSchema)
+				r.Get("/health-reports", api.templateHealthReports)
+				r.With(buildRateLimiter).Post("/leases", api.postWorkspaceLease)
 				r.Get("/", api.template)
 				r.Delete("/", api.deleteTemplate)
 				r.Patch("/", api.patchTemplateMeta)
+				r.Route("/network-policy", func(r chi.Router) {
+					r.Get("/", api.templateNetworkPolicy)
+					r.Put("/", api.putTemplateNetworkPolicy)
+					r.Delete("/", api.deleteTemplateNetworkPolicy)
+				})
+				r.Route("/preset-cohorts", func(r chi.Router) {
+					r.Get("/", api.templatePresetCohorts)
+					r.Put("/", api.putTemplatePresetCohorts)
+				})
+				r.Post("/schedule-policy/simulate", api.postTemplateSchedulePolicySimulation)
+				r.Route("/rollouts", func(r chi.Router) {
+					r.Get("/", api.templateVersionRollouts)
+					r.Post("/", api.postTemplateVersionRollout)
+					r.Get("/{rollout}", api.templateVersionRollout)
+					r.Post("/{rollout}/abort", api.postAbortTemplateVersionRollout)
+				})
+				r.Route("/user-presets", func(r chi.Router) {
+					r.Get("/", api.templateUserPresets)
+					r.Post("/", api.postTemplateUserPreset)
+					r.Delete("/{preset}", api.deleteTemplateUserPreset)
+				})
+				r.Route("/restarts", func(r chi.Router) {
+					r.Get("/", api.templateWorkspaceRestarts)
+					r.Post("/", api.postTemplateWorkspaceRestart)
+					r.Get("/{restart}", api.templateWorkspaceRestart)
+					r.Post("/{restart}/abort", api.postAbortTemplateWorkspaceRestart)
+					r.Post("/{restart}/resume", api.postResumeTemplateWorkspaceRestart)
+				})
+				r.Route("/secrets", func(r chi.Router) {
+					r.Get("/", api.templateSecrets)
+					r.Post("/", api.postTemplateSecret)
+					r.Patch("/{name}", api.patchTemplateSecret)
+					r.Delete("/{name}", api.deleteTemplateSecret)
+				})
+				r.Route("/vcs-mappings", func(r chi.Router) {
+					r.Get("/", api.templateVCSEventMappings)
+					r.Post("/", api.postTemplateVCSEventMapping)
+					r.Delete("/{mapping}", api.deleteTemplateVCSEventMapping)
+				})
 				r.Route("/versions", func(r chi.Router) {
 					r.Post("/archive", api.postArchiveTemplateVersions)
 					r.Get("/", api.templateVersionsByTemplate)
@@ -1673,11 +1797,15 @@ func New(options *Options) *API {
 			r.Get("/schema", templateVersionSchemaDeprecated)
 			r.Get("/parameters", templateVersionParametersDeprecated)
 			r.Get("/rich-parameters", api.templateVersionRichParameters)
+			r.Get("/parameters/schema", api.templateVersionParametersSchema)
 			r.Get("/external-auth", api.templateVersionExternalAuth)
 			r.Get("/variables", api.templateVersionVariables)
+			r.Get("/scan", api.templateVersionScan)
 			r.Get("/presets", api.templateVersionPresets)
 			r.Get("/resources", api.templateVersionResources)
 			r.Get("/logs", api.templateVersionLogs)
+			r.Get("/files", api.templateVersionFiles)
+			r.Get("/files/*", api.templateVersionFile)
 			r.Route("/dry-run", func(r chi.Router) {
 				r.Post("/", api.postTemplateVersionDryRun)
 				r.Get("/{jobID}", api.templateVersionDryRun)
@@ -1686,6 +1814,10 @@ func New(options *Options) *API {
 				r.Get("/{jobID}/matched-provisioners", api.templateVersionDryRunMatchedProvisioners)
 				r.Patch("/{jobID}/cancel", api.patchTemplateVersionDryRunCancel)
 			})
+			r.Route("/dry-run-matrix", func(r chi.Router) {
+				r.Post("/", api.postTemplateVersionDryRunMatrix)
+				r.Get("/", api.templateVersionDryRunMatrix)
+			})
 
 			r.Group(func(r chi.Router) {
 				r.Route("/dynamic-parameters", func(r chi.Router) {
@@ -1757,7 +1889,7 @@ func New(options *Options) *API {
 						// Creating workspaces does not require permissions on the user, only the
 						// organization member. This endpoint should match the authz story of
 						// postWorkspacesByOrganization
-						r.Post("/workspaces", api.postUserWorkspaces)
+						r.With(buildRateLimiter).Post("/workspaces", api.postUserWorkspaces)
 						r.Route("/workspace/{workspacename}", func(r chi.Router) {
 							r.Get("/", api.workspaceByOwnerAndName)
 							r.Get("/builds/{buildnumber}", api.workspaceBuildByBuildNumber)
@@ -1807,6 +1939,12 @@ func New(options *Options) *API {
 							})
 						})
 
+						r.Post("/impersonate", api.postUserImpersonation)
+						r.Route("/impersonations", func(r chi.Router) {
+							r.Get("/", api.userImpersonations)
+							r.Post("/{impersonation}/consent", api.postUserImpersonationConsent)
+						})
+
 						r.Route("/organizations", func(r chi.Router) {
 							r.Get("/", api.organizationsByUser)
 							r.Get("/{organizationname}", api.organizationByUserAndName)
@@ -1814,6 +1952,13 @@ func New(options *Options) *API {
 
 						r.Get("/gitsshkey", api.gitSSHKey)
 						r.Put("/gitsshkey", api.regenerateGitSSHKey)
+						r.Get("/jobs", api.userProvisionerJobs)
+						r.Get("/workspace-report", api.userWorkspaceReport)
+						r.Route("/schedule-pause", func(r chi.Router) {
+							r.Get("/", api.userSchedulePause)
+							r.Put("/", api.putUserSchedulePause)
+							r.Delete("/", api.deleteUserSchedulePause)
+						})
 						r.Route("/secrets", func(r chi.Router) {
 							r.Post("/", api.postUserSecret)
 							r.Post("/batch", api.postUserSecretsBatch)
@@ -1830,6 +1975,8 @@ func New(options *Options) *API {
 								r.Put("/", api.putUserNotificationPreferences)
 							})
 						})
+						r.Get("/notification-preferences", api.userScopedNotificationPreferences)
+						r.Put("/notification-preferences", api.putUserScopedNotificationPreferences)
 						r.Route("/webpush", func(r chi.Router) {
 							r.Post("/subscription", api.postUserWebpushSubscription)
 							r.Delete("/subscription", api.deleteUserWebpushSubscription)
@@ -1866,7 +2013,14 @@ func New(options *Options) *API {
 				r.Get("/gitauth", api.workspaceAgentsGitAuth)
 				r.Get("/external-auth", api.workspaceAgentsExternalAuth)
 				r.Get("/gitsshkey", api.agentGitSSHKey)
+				r.Post("/ssh-host-certificate", api.workspaceAgentSSHHostCertificate)
+				r.Post("/script-secrets", api.workspaceAgentScriptSecrets)
+				r.Get("/binary/{file}", api.workspaceAgentBinary)
 				r.Post("/log-source", api.workspaceAgentPostLogSource)
+				r.Post("/bootstrap-progress", api.workspaceAgentPostBootstrapProgress)
+				r.Post("/services/{name}/status", api.workspaceAgentPostServiceStatus)
+				r.Get("/network-policy", api.workspaceAgentNetworkPolicy)
+				r.Post("/network-policy-violations", api.workspaceAgentPostNetworkPolicyViolations)
 				r.Get("/reinit", api.workspaceAgentReinit)
 				r.Route("/experimental", func(r chi.Router) {
 					r.Post("/chat-context/refresh", api.workspaceAgentRefreshChatContext)
@@ -1889,14 +2043,22 @@ func New(options *Options) *API {
 				r.Get("/", api.workspaceAgent)
 				r.Get("/watch-metadata", api.watchWorkspaceAgentMetadataSSE)
 				r.Get("/watch-metadata-ws", api.watchWorkspaceAgentMetadataWS)
+				r.Get("/metadata/{key}/history", api.workspaceAgentMetadataHistory)
+				r.Get("/bootstrap-progress", api.workspaceAgentBootstrapProgress)
+				r.Get("/services", api.workspaceAgentServices)
+				r.Post("/services/{name}/restart", api.workspaceAgentRestartService)
 				r.Get("/startup-logs", api.workspaceAgentLogsDeprecated)
 				r.Get("/logs", api.workspaceAgentLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Post("/netcheck", api.postWorkspaceAgentNetcheck)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/containers", api.workspaceAgentListContainers)
 				r.Get("/containers/watch", api.watchWorkspaceAgentContainers)
 				r.Delete("/containers/devcontainers/{devcontainer}", api.workspaceAgentDeleteDevcontainer)
 				r.Post("/containers/devcontainers/{devcontainer}/recreate", api.workspaceAgentRecreateDevcontainer)
+				r.Post("/containers/{name}/recreate", api.workspaceAgentRebuildDevcontainer)
+				r.Get("/files", api.workspaceAgentDownloadFile)
+				r.Post("/files", api.workspaceAgentUploadFile)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
 				// PTY is part of workspaceAppServer.
@@ -1907,6 +2069,8 @@ func New(options *Options) *API {
 				apiKeyMiddleware,
 			)
 			r.Get("/", api.workspaces)
+			r.With(buildRateLimiter).Post("/import-state", api.postImportWorkspaceState)
+			r.Get("/leaked-cloud-resources", api.leakedCloudResources)
 			r.Route("/{workspace}", func(r chi.Router) {
 				r.Use(
 					httpmw.ExtractWorkspaceParam(options.Database),
@@ -1915,7 +2079,7 @@ func New(options *Options) *API {
 				r.Patch("/", api.patchWorkspace)
 				r.Route("/builds", func(r chi.Router) {
 					r.Get("/", api.workspaceBuilds)
-					r.Post("/", api.postWorkspaceBuilds)
+					r.With(buildRateLimiter).Post("/", api.postWorkspaceBuilds)
 				})
 				r.Route("/autostart", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceAutostart)
@@ -1926,8 +2090,36 @@ func New(options *Options) *API {
 				r.Get("/watch", api.watchWorkspaceSSE)
 				r.Get("/watch-ws", api.watchWorkspaceWS)
 				r.Put("/extend", api.putExtendWorkspace)
+				r.Post("/bump", api.postWorkspaceBump)
 				r.Post("/usage", api.postWorkspaceUsage)
+				r.With(httpmw.RateLimit(options.WorkspaceHeartbeatRateLimit, time.Minute)).Post("/heartbeat", api.postWorkspaceHeartbeat)
 				r.Put("/dormant", api.putWorkspaceDormant)
+				r.Put("/read-only", api.putWorkspaceReadOnly)
+				r.With(buildRateLimiter).Post("/archive", api.postWorkspaceArchive)
+				r.With(buildRateLimiter).Post("/unarchive", api.postWorkspaceUnarchive)
+				r.Route("/quiesce", func(r chi.Router) {
+					r.Post("/", api.postWorkspaceQuiesce)
+					r.Delete("/", api.deleteWorkspaceQuiesce)
+				})
+				r.Post("/support-bundle", api.postWorkspaceSupportBundle)
+				r.Post("/refresh-env", api.postWorkspaceRefreshEnv)
+				r.Route("/dormancy-exemption", func(r chi.Router) {
+					r.Get("/", api.workspaceDormancyExemption)
+					r.Put("/", api.putWorkspaceDormancyExemption)
+					r.Delete("/", api.deleteWorkspaceDormancyExemption)
+				})
+				r.Get("/dormancy-hook", api.workspaceDormancyHook)
+				r.Route("/network-policy", func(r chi.Router) {
+					r.Get("/", api.workspaceNetworkPolicy)
+					r.Put("/", api.putWorkspaceNetworkPolicy)
+					r.Delete("/", api.deleteWorkspaceNetworkPolicy)
+					r.Get("/violations", api.workspaceNetworkPolicyViolations)
+				})
+				r.Get("/memory-events", api.workspaceMemoryEvents)
+				r.Route("/cost", func(r chi.Router) {
+					r.Get("/", api.workspaceCost)
+					r.Put("/", api.putWorkspaceCost)
+				})
 				r.Put("/favorite", api.putFavoriteWorkspace)
 				r.Delete("/favorite", api.deleteFavoriteWorkspace)
 				r.Put("/autoupdates", api.putWorkspaceAutoupdates)
@@ -1937,15 +2129,32 @@ func New(options *Options) *API {
 					r.Post("/", api.postWorkspaceAgentPortShare)
 					r.Delete("/", api.deleteWorkspaceAgentPortShare)
 				})
+				r.Route("/share-links", func(r chi.Router) {
+					r.Get("/", api.workspaceAppShareLinks)
+					r.Post("/", api.postWorkspaceAppShareLink)
+					r.Delete("/{sharelink}", api.deleteWorkspaceAppShareLink)
+				})
 				r.Get("/timings", api.workspaceTimings)
+				r.Get("/cloud-resources", api.workspaceCloudResources)
 				r.Route("/acl", func(r chi.Router) {
 					r.Get("/", api.workspaceACL)
 					r.Patch("/", api.patchWorkspaceACL)
 					r.Delete("/", api.deleteWorkspaceACL)
 				})
+				r.Route("/port-forward-acl", func(r chi.Router) {
+					r.Get("/", api.workspacePortForwardACL)
+					r.Patch("/", api.patchWorkspacePortForwardACL)
+				})
 				r.Get("/agent-connection-watch", api.workspaceAgentConnWatcher.WorkspaceAgentConnectionWatch)
 			})
 		})
+		r.Route("/workspaceleases/{workspacelease}", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+			)
+			r.Get("/", api.workspaceLease)
+			r.Delete("/", api.releaseWorkspaceLease)
+		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1953,8 +2162,12 @@ func New(options *Options) *API {
 				httpmw.ExtractWorkspaceParam(options.Database),
 			)
 			r.Get("/", api.workspaceBuild)
+			r.Post("/annotations", api.postWorkspaceBuildAnnotation)
 			r.Patch("/cancel", api.patchCancelWorkspaceBuild)
+			r.Get("/inputs", api.workspaceBuildInputs)
 			r.Get("/logs", api.workspaceBuildLogs)
+			r.Get("/logs/archive", api.workspaceBuildArchivedLogs)
+			r.Get("/logs/stages", api.workspaceBuildLogStages)
 			r.Get("/parameters", api.workspaceBuildParameters)
 			r.Get("/resources", api.workspaceBuildResourcesDeprecated)
 			r.Get("/state", api.workspaceBuildState)
@@ -1979,6 +2192,17 @@ func New(options *Options) *API {
 				// handler and the login page.
 				r.Get("/", api.workspaceApplicationAuth)
 			})
+			r.Route("/share-links", func(r chi.Router) {
+				// Visitors of share links don't have a Coder account, they
+				// authenticate with the OIDC provider instead.
+				r.Get("/{sharelink}", api.workspaceAppShareLink)
+				r.Route("/oidc/callback", func(r chi.Router) {
+					r.Use(
+						httpmw.ExtractOAuth2(options.OIDCConfig, options.HTTPClient, options.DeploymentValues.HTTPCookies, oidcAuthURLParams, options.OIDCConfig.PKCESupported(), []string{options.AccessURL.Hostname()}, options.AccessURL.Scheme),
+					)
+					r.Get("/", api.workspaceAppShareLinkOIDC)
+				})
+			})
 		})
 		r.Route("/insights", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -2003,8 +2227,12 @@ func New(options *Options) *API {
 				r.Get("/user-activity", api.insightsUserActivity)
 				r.Get("/user-latency", api.insightsUserLatency)
 				r.Get("/templates", api.insightsTemplates)
+				r.Get("/developer-days", api.insightsActiveDeveloperDays)
 			})
 			r.Get("/user-status-counts", api.insightsUserStatusCounts)
+			r.Get("/workspace-egress", api.insightsWorkspaceEgress)
+			r.Get("/prebuilds", api.insightsPrebuilds)
+			r.Get("/autostop", api.insightsAutostop)
 		})
 		r.Route("/debug", func(r chi.Router) {
 			r.Use(
@@ -2045,6 +2273,8 @@ func New(options *Options) *API {
 			r.Method("GET", "/expvar", expvar.Handler()) // contains DERP metrics as well as cmdline and memstats
 
 			r.Post("/profile", api.debugCollectProfile)
+			r.Get("/deployment-bundle", api.debugDeploymentBundle)
+			r.Get("/lifecycle-executor", api.debugLifecycleExecutor)
 
 			r.Route("/pprof", func(r chi.Router) {
 				r.Use(func(next http.Handler) http.Handler {
@@ -2142,7 +2372,7 @@ func New(options *Options) *API {
 
 			r.Route("/{user}", func(r chi.Router) {
 				r.Use(httpmw.ExtractOrganizationMembersParam(options.Database, api.HTTPAuth.Authorize))
-				r.Post("/", api.tasksCreate)
+				r.With(buildRateLimiter).Post("/", api.tasksCreate)
 
 				r.Route("/{task}", func(r chi.Router) {
 					r.Use(httpmw.ExtractTaskParam(options.Database))
@@ -2356,6 +2586,9 @@ type API struct {
 	wsWatcher                *httpapi.WSWatcher
 
 	Acquirer *provisionerdserver.Acquirer
+	// BuildTraces exports finished workspace builds as traces. It is nil
+	// unless a workspace build tracer provider is configured.
+	BuildTraces *buildtrace.Exporter
 	// dbRolluper rolls up template usage stats from raw agent and app
 	// stats. This is used to provide insights in the WebUI.
 	dbRolluper *dbrollup.Rolluper
@@ -2458,6 +2691,7 @@ func (api *API) Close() error {
 	_ = api.OIDCConvertKeyCache.Close()
 	_ = api.AppSigningKeyCache.Close()
 	_ = api.AppEncryptionKeyCache.Close()
+	_ = api.SSHHostCAKeyCache.Close()
 	if api.NATSCACache != nil {
 		_ = api.NATSCACache.Close()
 	}
@@ -2604,6 +2838,7 @@ func (api *API) CreateInMemoryTaggedProvisionerDaemon(dialCtx context.Context, n
 			AISeatTracker:       api.AISeatTracker,
 			Clock:               api.Clock,
 			HeartbeatFn:         options.heartbeatFn,
+			BuildTraces:         api.BuildTraces,
 		},
 		api.NotificationsEnqueuer,
 		&api.PrebuildsReconciler,
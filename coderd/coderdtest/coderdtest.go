@@ -61,6 +61,7 @@ import (
 	"github.com/coder/coder/v2/coderd/autobuild"
 	"github.com/coder/coder/v2/coderd/awsidentity"
 	"github.com/coder/coder/v2/coderd/azureidentity"
+	"github.com/coder/coder/v2/coderd/buildlogarchive"
 	"github.com/coder/coder/v2/coderd/connectionlog"
 	"github.com/coder/coder/v2/coderd/cryptokeys"
 	"github.com/coder/coder/v2/coderd/database"
@@ -130,6 +131,7 @@ type Options struct {
 	SSHKeygenAlgorithm             gitsshkey.Algorithm
 	AutobuildTicker                <-chan time.Time
 	AutobuildStats                 chan<- autobuild.Stats
+	AutobuildPrometheusRegistry    prometheus.Registerer
 	Auditor                        audit.Auditor
 	TLSCertificates                []tls.Certificate
 	ExternalAuthConfigs            []*externalauth.Config
@@ -145,9 +147,10 @@ type Options struct {
 	HealthcheckRefresh time.Duration
 
 	// All rate limits default to -1 (unlimited) in tests if not set.
-	APIRateLimit   int
-	LoginRateLimit int
-	FilesRateLimit int
+	APIRateLimit                int
+	LoginRateLimit              int
+	FilesRateLimit              int
+	WorkspaceHeartbeatRateLimit int
 
 	// OneTimePasscodeValidityPeriod specifies how long a one time passcode should be valid for.
 	OneTimePasscodeValidityPeriod time.Duration
@@ -190,6 +193,7 @@ type Options struct {
 	MetadataBatcherOptions []metadatabatcher.Option
 
 	WebpushDispatcher                  webpush.Dispatcher
+	BuildLogArchive                    buildlogarchive.Store
 	WorkspaceAppsStatsCollectorOptions workspaceapps.StatsCollectorOptions
 	AllowWorkspaceRenames              bool
 	NewTicker                          func(duration time.Duration) (<-chan time.Time, func())
@@ -273,6 +277,9 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			close(options.AutobuildStats)
 		})
 	}
+	if options.AutobuildPrometheusRegistry == nil {
+		options.AutobuildPrometheusRegistry = prometheus.NewRegistry()
+	}
 
 	if options.Authorizer == nil {
 		defAuth := rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry())
@@ -375,6 +382,9 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 	if options.FilesRateLimit == 0 {
 		options.FilesRateLimit = -1
 	}
+	if options.WorkspaceHeartbeatRateLimit == 0 {
+		options.WorkspaceHeartbeatRateLimit = -1
+	}
 	if options.StatsBatcher == nil {
 		ctx, cancel := context.WithCancel(context.Background())
 		t.Cleanup(cancel)
@@ -424,7 +434,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		options.Database,
 		options.Pubsub,
 		files.New(prometheus.NewRegistry(), options.Authorizer),
-		prometheus.NewRegistry(),
+		options.AutobuildPrometheusRegistry,
 		&templateScheduleStore,
 		&auditor,
 		accessControlStore,
@@ -434,9 +444,13 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		options.NotificationsEnqueuer,
 		experiments,
 		options.WorkspaceBuilderMetrics,
-	).WithStatsChannel(options.AutobuildStats)
+	).WithStatsChannel(options.AutobuildStats).
+		WithCostBudgets(options.DeploymentValues.WorkspaceMonthlyCostBudget.Value(), options.DeploymentValues.UserMonthlyCostBudget.Value()).
+		WithDormancyHooks(options.DeploymentValues.WorkspaceDormancyHookURL.String() != "")
 
 	lifecycleExecutor.Run()
+	var lifecycleExecutorPtr atomic.Pointer[autobuild.Executor]
+	lifecycleExecutorPtr.Store(lifecycleExecutor)
 
 	jobReaperTicker := time.NewTicker(options.DeploymentValues.JobReaperDetectorInterval.Value())
 	defer jobReaperTicker.Stop()
@@ -641,15 +655,18 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			APIRateLimit:                       options.APIRateLimit,
 			LoginRateLimit:                     options.LoginRateLimit,
 			FilesRateLimit:                     options.FilesRateLimit,
+			WorkspaceHeartbeatRateLimit:        options.WorkspaceHeartbeatRateLimit,
 			Authorizer:                         options.Authorizer,
 			Telemetry:                          options.TelemetryReporter,
 			TemplateScheduleStore:              &templateScheduleStore,
 			AccessControlStore:                 accessControlStore,
+			LifecycleExecutor:                  &lifecycleExecutorPtr,
 			TLSCertificates:                    options.TLSCertificates,
 			TrialGenerator:                     options.TrialGenerator,
 			RefreshEntitlements:                options.RefreshEntitlements,
 			TailnetCoordinator:                 options.Coordinator,
 			WebPushDispatcher:                  options.WebpushDispatcher,
+			BuildLogArchive:                    options.BuildLogArchive,
 			BaseDERPMap:                        derpMap,
 			DERPMapUpdateFrequency:             150 * time.Millisecond,
 			CoordinatorResumeTokenProvider:     options.CoordinatorResumeTokenProvider,
@@ -368,7 +368,9 @@ func assertSecurityDefined(t *testing.T, comment SwaggerComment) {
 		comment.router == "/api/v2/users/login" ||
 		comment.router == "/api/v2/users/otp/request" ||
 		comment.router == "/api/v2/users/otp/change-password" ||
-		comment.router == "/api/v2/init-script/{os}/{arch}" {
+		comment.router == "/api/v2/init-script/{os}/{arch}" ||
+		comment.router == "/api/v2/applications/share-links/{sharelink}" ||
+		comment.router == "/api/v2/applications/share-links/oidc/callback" {
 		return // endpoints do not require authorization
 	}
 	if comment.router == "/api/v2/ai-gateway/serve" {
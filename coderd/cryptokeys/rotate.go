@@ -47,6 +47,7 @@ var defaultRotatedFeatures = []database.CryptoKeyFeature{
 	database.CryptoKeyFeatureWorkspaceAppsAPIKey,
 	database.CryptoKeyFeatureOIDCConvert,
 	database.CryptoKeyFeatureTailnetResume,
+	database.CryptoKeyFeatureSSHHostCA,
 }
 
 // DefaultRotatedFeatures returns the crypto key features the rotator manages by
@@ -277,6 +278,9 @@ func generateNewSecret(feature database.CryptoKeyFeature, startsAt time.Time, ke
 		return generateKey(64)
 	case database.CryptoKeyFeatureNATSCA:
 		return generateCASecret(startsAt, keyDuration)
+	case database.CryptoKeyFeatureSSHHostCA:
+		// An ed25519 seed, from which the CA key pair is derived.
+		return generateKey(32)
 	}
 	return "", xerrors.Errorf("unknown feature: %s", feature)
 }
@@ -305,6 +309,10 @@ func tokenDuration(feature database.CryptoKeyFeature) time.Duration {
 		// valid for NATSCAOverlap past the active-signing window. Keeping the
 		// row (and thus its trust-root status) beyond cert expiry is pointless.
 		return NATSCAOverlap
+	case database.CryptoKeyFeatureSSHHostCA:
+		// Host certificates signed just before rotation must keep verifying
+		// until they expire, so clients need to keep trusting the old CA.
+		return SSHHostCertificateDuration
 	default:
 		return 0
 	}
@@ -0,0 +1,81 @@
+package cryptokeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+)
+
+// SSHHostCertificateDuration is how long an SSH host certificate issued to a
+// workspace agent stays valid. Agents renew well before expiry, so this only
+// bounds how long a certificate from a stolen host key remains usable.
+const SSHHostCertificateDuration = time.Hour * 24
+
+// sshHostCertificateBackdate backdates the ValidAfter of issued certificates
+// so that clients with mildly skewed clocks still accept them.
+const sshHostCertificateBackdate = time.Minute * 5
+
+// SSHHostCASigner derives the SSH host CA signer from an ssh_host_ca secret as
+// returned by a SigningKeycache for that feature. The secret is an ed25519
+// seed, so the CA key pair is fully determined by the stored row.
+func SSHHostCASigner(secret interface{}) (ssh.Signer, error) {
+	seed, ok := secret.([]byte)
+	if !ok {
+		return nil, xerrors.Errorf("expected []byte secret, got %T", secret)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, xerrors.Errorf("expected %d byte seed, got %d", ed25519.SeedSize, len(seed))
+	}
+	signer, err := ssh.NewSignerFromKey(ed25519.NewKeyFromSeed(seed))
+	if err != nil {
+		return nil, xerrors.Errorf("new signer: %w", err)
+	}
+	return signer, nil
+}
+
+// SSHHostCAPublicKey returns the public key of the SSH host CA stored in a
+// hex-encoded ssh_host_ca crypto key secret.
+func SSHHostCAPublicKey(secret string) (ssh.PublicKey, error) {
+	seed, err := hex.DecodeString(secret)
+	if err != nil {
+		return nil, xerrors.Errorf("decode key: %w", err)
+	}
+	signer, err := SSHHostCASigner(seed)
+	if err != nil {
+		return nil, err
+	}
+	return signer.PublicKey(), nil
+}
+
+// SignSSHHostCertificate signs hostKey with the CA, producing a host
+// certificate valid for the given principals (host names) from now until
+// now+SSHHostCertificateDuration.
+func SignSSHHostCertificate(ca ssh.Signer, hostKey ssh.PublicKey, keyID string, principals []string, now time.Time) (*ssh.Certificate, error) {
+	if len(principals) == 0 {
+		return nil, xerrors.New("at least one principal is required")
+	}
+
+	var serial [8]byte
+	if _, err := rand.Read(serial[:]); err != nil {
+		return nil, xerrors.Errorf("generate serial: %w", err)
+	}
+
+	cert := &ssh.Certificate{
+		Key:             hostKey,
+		Serial:          binary.BigEndian.Uint64(serial[:]),
+		CertType:        ssh.HostCert,
+		KeyId:           keyID,
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(now.Add(-sshHostCertificateBackdate).Unix()), //nolint:gosec // Unix times are positive.
+		ValidBefore:     uint64(now.Add(SSHHostCertificateDuration).Unix()),  //nolint:gosec // Unix times are positive.
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		return nil, xerrors.Errorf("sign certificate: %w", err)
+	}
+	return cert, nil
+}
@@ -0,0 +1,53 @@
+package cryptokeys_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/coder/coder/v2/coderd/cryptokeys"
+)
+
+func TestSignSSHHostCertificate(t *testing.T) {
+	t.Parallel()
+
+	seed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	ca, err := cryptokeys.SSHHostCASigner(seed)
+	require.NoError(t, err)
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	require.NoError(t, err)
+
+	now := time.Now()
+	cert, err := cryptokeys.SignSSHHostCertificate(ca, hostSigner.PublicKey(), "agent", []string{"dev.main.alice.coder"}, now)
+	require.NoError(t, err)
+	require.Equal(t, uint32(ssh.HostCert), cert.CertType)
+
+	checker := &ssh.CertChecker{
+		IsHostAuthority: func(auth ssh.PublicKey, _ string) bool {
+			return string(auth.Marshal()) == string(ca.PublicKey().Marshal())
+		},
+	}
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 22}
+	require.NoError(t, checker.CheckHostKey("dev.main.alice.coder:22", addr, cert))
+	require.Error(t, checker.CheckHostKey("other.coder:22", addr, cert))
+
+	// The same seed always yields the same CA.
+	again, err := cryptokeys.SSHHostCASigner(seed)
+	require.NoError(t, err)
+	require.Equal(t, ca.PublicKey().Marshal(), again.PublicKey().Marshal())
+
+	_, err = cryptokeys.SSHHostCASigner([]byte("short"))
+	require.Error(t, err)
+	_, err = cryptokeys.SignSSHHostCertificate(ca, hostSigner.PublicKey(), "agent", nil, now)
+	require.Error(t, err)
+}
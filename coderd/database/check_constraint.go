@@ -44,8 +44,10 @@ const (
 	CheckMcpServerConfigsAuthTypeCheck                       CheckConstraint = "mcp_server_configs_auth_type_check"                        // mcp_server_configs
 	CheckMcpServerConfigsAvailabilityCheck                   CheckConstraint = "mcp_server_configs_availability_check"                     // mcp_server_configs
 	CheckMcpServerConfigsTransportCheck                      CheckConstraint = "mcp_server_configs_transport_check"                        // mcp_server_configs
+	CheckOrganizationsProvisionerDaemonQuotaCheck            CheckConstraint = "organizations_provisioner_daemon_quota_check"              // organizations
 	CheckMaxProvisionerLogsLength                            CheckConstraint = "max_provisioner_logs_length"                               // provisioner_jobs
 	CheckNatsPortValidTcp                                    CheckConstraint = "nats_port_valid_tcp"                                       // replicas
+	CheckWorkspaceAgentBootstrapProgressPercentCheck         CheckConstraint = "workspace_agent_bootstrap_progress_percent_check"          // workspace_agent_bootstrap_progress
 	CheckMaxLogsLength                                       CheckConstraint = "max_logs_length"                                           // workspace_agents
 	CheckSubsystemsNotNone                                   CheckConstraint = "subsystems_not_none"                                       // workspace_agents
 	CheckWorkspaceBuildsDeadlineBelowMaxDeadline             CheckConstraint = "workspace_builds_deadline_below_max_deadline"              // workspace_builds
@@ -60,6 +62,9 @@ const (
 	CheckUserSkillsDescriptionSize                           CheckConstraint = "user_skills_description_size"                              // user_skills
 	CheckUserSkillsNameFormat                                CheckConstraint = "user_skills_name_format"                                   // user_skills
 	CheckUserSkillsNameSize                                  CheckConstraint = "user_skills_name_size"                                     // user_skills
+	CheckWorkspaceAgentMemoryEventsOomKillsCheck             CheckConstraint = "workspace_agent_memory_events_oom_kills_check"             // workspace_agent_memory_events
+	CheckWorkspaceAgentNetworkPolicyViolationsCountCheck     CheckConstraint = "workspace_agent_network_policy_violations_count_check"     // workspace_agent_network_policy_violations
+	CheckWorkspaceAgentNetworkPolicyViolationsPortCheck      CheckConstraint = "workspace_agent_network_policy_violations_port_check"      // workspace_agent_network_policy_violations
 	CheckWorkspaceBuildOrchestrationsAttemptCountCheck       CheckConstraint = "workspace_build_orchestrations_attempt_count_check"        // workspace_build_orchestrations
 	CheckWorkspaceBuildOrchestrationsChildLogLevelCheck      CheckConstraint = "workspace_build_orchestrations_child_log_level_check"      // workspace_build_orchestrations
 	CheckWorkspaceBuildOrchestrationsChildParametersCheck    CheckConstraint = "workspace_build_orchestrations_child_parameters_check"     // workspace_build_orchestrations
@@ -67,4 +72,5 @@ const (
 	CheckWorkspaceBuildOrchestrationsCompletedChildCheck     CheckConstraint = "workspace_build_orchestrations_completed_child_check"      // workspace_build_orchestrations
 	CheckWorkspaceBuildOrchestrationsNextRetryAfterCheck     CheckConstraint = "workspace_build_orchestrations_next_retry_after_check"     // workspace_build_orchestrations
 	CheckWorkspaceBuildOrchestrationsStatusCheck             CheckConstraint = "workspace_build_orchestrations_status_check"               // workspace_build_orchestrations
+	CheckWorkspacePortForwardAclsPortsCheck                  CheckConstraint = "workspace_port_forward_acls_ports_check"                   // workspace_port_forward_acls
 )
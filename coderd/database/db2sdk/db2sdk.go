@@ -185,6 +185,7 @@ func TemplateVersionParameterFromPreview(param previewtypes.Parameter) (codersdk
 		Icon:                 param.Icon,
 		Required:             param.Required,
 		Ephemeral:            param.Ephemeral,
+		Sensitive:            ptr.NilToEmpty(param.Styling.MaskInput),
 		Options:              slice.List(param.Options, TemplateVersionParameterOptionFromPreview),
 		// Validation set after
 	}
@@ -249,6 +250,10 @@ func TemplateVersionParameter(param database.TemplateVersionParameter) (codersdk
 		ValidationMonotonic:  codersdk.ValidationMonotonicOrder(param.ValidationMonotonic),
 		Required:             param.Required,
 		Ephemeral:            param.Ephemeral,
+		Sensitive:            param.Sensitive,
+		Group:                param.GroupName,
+		GroupOrder:           param.GroupOrder,
+		Page:                 param.Page,
 	}, nil
 }
 
@@ -611,6 +616,11 @@ func WorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator,
 	if dbAgent.ReadyAt.Valid {
 		workspaceAgent.ReadyAt = &dbAgent.ReadyAt.Time
 	}
+	var clockSkew time.Duration
+	if dbAgent.ClockSkewMs.Valid {
+		workspaceAgent.ClockSkewMilliseconds = &dbAgent.ClockSkewMs.Int64
+		clockSkew = time.Duration(dbAgent.ClockSkewMs.Int64) * time.Millisecond
+	}
 
 	switch {
 	case workspaceAgent.Status != codersdk.WorkspaceAgentConnected && workspaceAgent.LifecycleState == codersdk.WorkspaceAgentLifecycleOff:
@@ -632,6 +642,8 @@ func WorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator,
 		workspaceAgent.Health.Reason = "agent startup script exited with an error"
 	case workspaceAgent.LifecycleState.ShuttingDown():
 		workspaceAgent.Health.Reason = "agent is shutting down"
+	case clockSkew.Abs() > codersdk.WorkspaceAgentClockSkewThreshold:
+		workspaceAgent.Health.Reason = fmt.Sprintf("agent clock is off by %s", clockSkew.Abs().Round(time.Second))
 	default:
 		workspaceAgent.Health.Healthy = true
 	}
@@ -706,6 +718,9 @@ func Apps(dbApps []database.WorkspaceApp, statuses []database.WorkspaceAppStatus
 			OpenIn:   codersdk.WorkspaceAppOpenIn(dbApp.OpenIn),
 			Tooltip:  dbApp.Tooltip,
 			Statuses: WorkspaceAppStatuses(statuses),
+
+			MaxSessionDurationSeconds: dbApp.MaxSessionDurationSeconds,
+			SessionIdleTimeoutSeconds: dbApp.SessionIdleTimeoutSeconds,
 		})
 	}
 	return apps
@@ -947,6 +962,13 @@ func TemplateRoleActions(role codersdk.TemplateRole) []policy.Action {
 	switch role {
 	case codersdk.TemplateRoleAdmin:
 		return []policy.Action{policy.WildcardSymbol}
+	case codersdk.TemplateRoleManage:
+		// Managers can edit the template's settings, ACL, and versions, but
+		// deleting the template is left to template admins.
+		return slice.Omit(
+			rbac.ResourceTemplate.AvailableActions(),
+			policy.ActionDelete,
+		)
 	case codersdk.TemplateRoleUse:
 		return []policy.Action{policy.ActionRead, policy.ActionUse}
 	}
@@ -2089,6 +2111,22 @@ func ChatDiffStatus(chatID uuid.UUID, status *database.ChatDiffStatus) codersdk.
 	return result
 }
 
+// UserImpersonation converts a database UserImpersonation to an SDK
+// UserImpersonation.
+func UserImpersonation(impersonation database.UserImpersonation) codersdk.UserImpersonation {
+	return codersdk.UserImpersonation{
+		ID:              impersonation.ID,
+		UserID:          impersonation.UserID,
+		ImpersonatorID:  impersonation.ImpersonatorID,
+		Reason:          impersonation.Reason,
+		Lifetime:        time.Duration(impersonation.LifetimeSeconds) * time.Second,
+		ConsentRequired: impersonation.ConsentRequired,
+		ConsentedAt:     nullTimePtr(impersonation.ConsentedAt),
+		IssuedAt:        nullTimePtr(impersonation.IssuedAt),
+		CreatedAt:       impersonation.CreatedAt,
+	}
+}
+
 // UserSecret converts a database ListUserSecretsRow (metadata only,
 // no value) to an SDK UserSecret.
 func UserSecret(secret database.ListUserSecretsRow) codersdk.UserSecret {
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
+	"tailscale.com/tailcfg"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
@@ -21,6 +22,8 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/testutil"
 )
 
 func TestProvisionerJobStatus(t *testing.T) {
@@ -210,6 +213,39 @@ func TestTemplateVersionParameter_BadDescription(t *testing.T) {
 	req.NotEmpty(sdk.DescriptionPlaintext, "broke the markdown parser with %v", desc)
 }
 
+func TestWorkspaceAgent_ClockSkew(t *testing.T) {
+	t.Parallel()
+
+	coordinator := tailnet.NewCoordinator(testutil.Logger(t))
+	t.Cleanup(func() { _ = coordinator.Close() })
+
+	now := dbtime.Now()
+	convert := func(skew sql.NullInt64) codersdk.WorkspaceAgent {
+		agent, err := db2sdk.WorkspaceAgent(&tailcfg.DERPMap{}, coordinator, database.WorkspaceAgent{
+			ID:               uuid.New(),
+			CreatedAt:        now,
+			FirstConnectedAt: sql.NullTime{Time: now, Valid: true},
+			LastConnectedAt:  sql.NullTime{Time: now, Valid: true},
+			LifecycleState:   database.WorkspaceAgentLifecycleStateReady,
+			ClockSkewMs:      skew,
+		}, nil, nil, nil, time.Minute, "")
+		require.NoError(t, err)
+		return agent
+	}
+
+	agent := convert(sql.NullInt64{})
+	require.Nil(t, agent.ClockSkewMilliseconds)
+	require.True(t, agent.Health.Healthy)
+
+	agent = convert(sql.NullInt64{Int64: 1200, Valid: true})
+	require.Equal(t, int64(1200), *agent.ClockSkewMilliseconds)
+	require.True(t, agent.Health.Healthy)
+
+	agent = convert(sql.NullInt64{Int64: -150000, Valid: true})
+	require.False(t, agent.Health.Healthy)
+	require.Equal(t, "agent clock is off by 2m30s", agent.Health.Reason)
+}
+
 func TestChatDebugRunSummary(t *testing.T) {
 	t.Parallel()
 
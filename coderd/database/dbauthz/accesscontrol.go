@@ -2,11 +2,14 @@ package dbauthz
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/deprecation"
 )
 
 // AccessControlStore fetches access control-related configuration
@@ -20,12 +23,21 @@ type AccessControlStore interface {
 type TemplateAccessControl struct {
 	RequireActiveVersion bool
 	Deprecated           string
+	// DeprecationCutoff is the time until which a deprecated template may
+	// still be used to create workspaces.
+	DeprecationCutoff sql.NullTime
 }
 
 func (t TemplateAccessControl) IsDeprecated() bool {
 	return t.Deprecated != ""
 }
 
+// BlocksCreation returns true if the template is deprecated and can no
+// longer be used to create workspaces at now.
+func (t TemplateAccessControl) BlocksCreation(now time.Time) bool {
+	return deprecation.Blocked(t.Deprecated, t.DeprecationCutoff, now)
+}
+
 // AGPLTemplateAccessControlStore always returns the defaults for access control
 // settings.
 type AGPLTemplateAccessControlStore struct{}
@@ -39,7 +51,8 @@ func (AGPLTemplateAccessControlStore) GetTemplateAccessControl(t database.Templa
 		// existing deprecated templates. This is erroring on the safe side
 		// if a license expires, we should not allow deprecated templates
 		// to be used for new workspaces.
-		Deprecated: t.Deprecated,
+		Deprecated:        t.Deprecated,
+		DeprecationCutoff: t.DeprecationCutoff,
 	}
 }
 
@@ -130,6 +130,62 @@ func New(db database.Store, authorizer rbac.Authorizer, logger slog.Logger, acs
 	}
 }
 
+func (q *querier) CountRunningProvisionerJobsByWorkerID(ctx context.Context, workerID uuid.UUID) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return 0, err
+	}
+	return q.db.CountRunningProvisionerJobsByWorkerID(ctx, workerID)
+}
+
+func (q *querier) DeleteUserSchedulePause(ctx context.Context, userID uuid.UUID) error {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, u); err != nil {
+		return err
+	}
+	return q.db.DeleteUserSchedulePause(ctx, userID)
+}
+
+func (q *querier) GetUserSchedulePause(ctx context.Context, userID uuid.UUID) (database.UserSchedulePause, error) {
+	// Pauses aren't personal, the lifecycle executor reads them for the owners
+	// of workspaces.
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return database.UserSchedulePause{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, u); err != nil {
+		return database.UserSchedulePause{}, err
+	}
+	return q.db.GetUserSchedulePause(ctx, userID)
+}
+
+func (q *querier) RotateProvisionerKey(ctx context.Context, arg database.RotateProvisionerKeyParams) (database.ProvisionerKey, error) {
+	fetch := func(ctx context.Context, arg database.RotateProvisionerKeyParams) (database.ProvisionerKey, error) {
+		return q.db.GetProvisionerKeyByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.RotateProvisionerKey)(ctx, arg)
+}
+
+func (q *querier) UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg database.UpdateProvisionerKeyLastUsedAtParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateProvisionerKeyLastUsedAtParams) (database.ProvisionerKey, error) {
+		return q.db.GetProvisionerKeyByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateProvisionerKeyLastUsedAt)(ctx, arg)
+}
+
+func (q *querier) UpsertUserSchedulePause(ctx context.Context, arg database.UpsertUserSchedulePauseParams) (database.UserSchedulePause, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserSchedulePause{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, u); err != nil {
+		return database.UserSchedulePause{}, err
+	}
+	return q.db.UpsertUserSchedulePause(ctx, arg)
+}
+
 func (q *querier) Wrappers() []string {
 	return append(q.db.Wrappers(), wrapname)
 }
@@ -393,10 +449,11 @@ var (
 				Identifier:  rbac.RoleIdentifier{Name: "notifier"},
 				DisplayName: "Notifier",
 				Site: rbac.Permissions(map[string][]policy.Action{
-					rbac.ResourceNotificationMessage.Type: {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate, policy.ActionDelete},
-					rbac.ResourceInboxNotification.Type:   {policy.ActionCreate},
-					rbac.ResourceWebpushSubscription.Type: {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate, policy.ActionDelete},
-					rbac.ResourceDeploymentConfig.Type:    {policy.ActionRead, policy.ActionUpdate}, // To read and upsert VAPID keys
+					rbac.ResourceNotificationMessage.Type:  {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceNotificationTemplate.Type: {policy.ActionRead}, // To read webhook overrides
+					rbac.ResourceInboxNotification.Type:    {policy.ActionCreate},
+					rbac.ResourceWebpushSubscription.Type:  {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate, policy.ActionDelete},
+					rbac.ResourceDeploymentConfig.Type:     {policy.ActionRead, policy.ActionUpdate}, // To read and upsert VAPID keys
 				}),
 				User:    []rbac.Permission{},
 				ByOrgID: map[string]rbac.OrgPermissions{},
@@ -514,7 +571,7 @@ var (
 					rbac.ResourceProvisionerDaemon.Type:           {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate},
 					rbac.ResourceUser.Type:                        rbac.ResourceUser.AvailableActions(),
 					rbac.ResourceWorkspaceDormant.Type:            {policy.ActionUpdate, policy.ActionDelete, policy.ActionWorkspaceStop},
-					rbac.ResourceWorkspace.Type:                   {policy.ActionUpdate, policy.ActionDelete, policy.ActionWorkspaceStart, policy.ActionWorkspaceStop, policy.ActionSSH, policy.ActionCreateAgent, policy.ActionDeleteAgent, policy.ActionUpdateAgent},
+					rbac.ResourceWorkspace.Type:                   {policy.ActionUpdate, policy.ActionDelete, policy.ActionWorkspaceStart, policy.ActionWorkspaceStop, policy.ActionWorkspaceExtend, policy.ActionSSH, policy.ActionCreateAgent, policy.ActionDeleteAgent, policy.ActionUpdateAgent},
 					rbac.ResourceWorkspaceProxy.Type:              {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
 					rbac.ResourceWorkspaceBuildOrchestration.Type: {policy.ActionUpdate, policy.ActionRead},
 					rbac.ResourceDeploymentConfig.Type:            {policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
@@ -786,7 +843,7 @@ var (
 				Site: rbac.Permissions(map[string][]policy.Action{
 					rbac.ResourceAIProvider.Type:       {policy.ActionRead},
 					rbac.ResourceChat.Type:             {policy.ActionCreate, policy.ActionRead, policy.ActionUpdate, policy.ActionDelete},
-					rbac.ResourceWorkspace.Type:        {policy.ActionRead, policy.ActionUpdate},
+					rbac.ResourceWorkspace.Type:        {policy.ActionRead, policy.ActionUpdate, policy.ActionWorkspaceExtend},
 					rbac.ResourceDeploymentConfig.Type: {policy.ActionRead},
 					rbac.ResourceUser.Type:             {policy.ActionReadPersonal},
 				}),
@@ -1725,6 +1782,13 @@ func scopedOrgRoleIdentifiers(names []string, orgID uuid.UUID) []rbac.RoleIdenti
 	return out
 }
 
+func (q *querier) AccrueWorkspaceEstimatedCosts(ctx context.Context, arg database.AccrueWorkspaceEstimatedCostsParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.AccrueWorkspaceEstimatedCosts(ctx, arg)
+}
+
 func (q *querier) AcquireLock(ctx context.Context, id int64) error {
 	return q.db.AcquireLock(ctx, id)
 }
@@ -1755,10 +1819,16 @@ func (q *querier) AcquireStaleChatDiffStatuses(ctx context.Context, limitVal int
 }
 
 func (q *querier) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
-	fetch := func(ctx context.Context, arg database.ActivityBumpWorkspaceParams) (database.Workspace, error) {
-		return q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	// Bumping the deadline postpones autostop, so it requires the extend
+	// action rather than update.
+	if err := q.authorizeContext(ctx, policy.ActionWorkspaceExtend, workspace); err != nil {
+		return err
 	}
-	return update(q.log, q.auth, fetch, q.db.ActivityBumpWorkspace)(ctx, arg)
+	return q.db.ActivityBumpWorkspace(ctx, arg)
 }
 
 func (q *querier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
@@ -1915,6 +1985,20 @@ func (q *querier) ClaimPrebuiltWorkspace(ctx context.Context, arg database.Claim
 	return q.db.ClaimPrebuiltWorkspace(ctx, arg)
 }
 
+func (q *querier) ClaimTemplateVersionScans(ctx context.Context, arg database.ClaimTemplateVersionScansParams) ([]database.TemplateVersionScan, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.ClaimTemplateVersionScans(ctx, arg)
+}
+
+func (q *querier) ClaimWorkspaceDormancyHooks(ctx context.Context, arg database.ClaimWorkspaceDormancyHooksParams) ([]database.WorkspaceDormancyHook, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.ClaimWorkspaceDormancyHooks(ctx, arg)
+}
+
 func (q *querier) CleanTailnetCoordinators(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -1951,6 +2035,19 @@ func (q *querier) CountAIBridgeSessions(ctx context.Context, arg database.CountA
 	return q.db.CountAuthorizedAIBridgeSessions(ctx, arg, prep)
 }
 
+func (q *querier) CountActiveWorkspaceLeasesByAPIKeyID(ctx context.Context, apiKeyID string) (int64, error) {
+	key, err := q.db.GetAPIKeyByID(ctx, apiKeyID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionRead, key); err != nil {
+		return 0, err
+	}
+
+	return q.db.CountActiveWorkspaceLeasesByAPIKeyID(ctx, apiKeyID)
+}
+
 func (q *querier) CountAuditLogs(ctx context.Context, arg database.CountAuditLogsParams) (int64, error) {
 	// Shortcut if the user is an owner. The SQL filter is noticeable,
 	// and this is an easy win for owners. Which is the common case.
@@ -2248,6 +2345,13 @@ func (q *querier) DeleteExpiredAPIKeys(ctx context.Context, arg database.DeleteE
 	return q.db.DeleteExpiredAPIKeys(ctx, arg)
 }
 
+func (q *querier) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteExpiredIdempotencyKeys(ctx, before)
+}
+
 func (q *querier) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	return fetchAndExec(q.log, q.auth, policy.ActionUpdatePersonal, func(ctx context.Context, arg database.DeleteExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		//nolint:gosimple
@@ -2304,6 +2408,13 @@ func (q *querier) DeleteMCPServerUserToken(ctx context.Context, arg database.Del
 	return q.db.DeleteMCPServerUserToken(ctx, arg)
 }
 
+func (q *querier) DeleteNotificationTemplateWebhook(ctx context.Context, notificationTemplateID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationTemplate); err != nil {
+		return err
+	}
+	return q.db.DeleteNotificationTemplateWebhook(ctx, notificationTemplateID)
+}
+
 func (q *querier) DeleteOAuth2ProviderAppByClientID(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceOauth2App); err != nil {
 		return err
@@ -2446,6 +2557,27 @@ func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold tim
 	return q.db.DeleteOldWorkspaceAgentLogs(ctx, threshold)
 }
 
+func (q *querier) DeleteOldWorkspaceAgentMemoryEvents(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentMemoryEvents(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, arg database.DeleteOldWorkspaceAgentMetadataHistoryParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldWorkspaceAgentMetadataHistory(ctx, arg)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentNetworkPolicyViolations(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentNetworkPolicyViolations(ctx, beforeTime)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -2475,6 +2607,13 @@ func (q *querier) DeleteOrganizationMember(ctx context.Context, arg database.Del
 	}, q.db.DeleteOrganizationMember)(ctx, arg)
 }
 
+func (q *querier) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceProvisionerJobs); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteProvisionerJobLogsByJobID(ctx, jobID)
+}
+
 func (q *querier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
 	return deleteQ(q.log, q.auth, q.db.GetProvisionerKeyByID, q.db.DeleteProvisionerKey)(ctx, id)
 }
@@ -2537,6 +2676,43 @@ func (q *querier) DeleteTask(ctx context.Context, arg database.DeleteTaskParams)
 	return q.db.DeleteTask(ctx, arg)
 }
 
+func (q *querier) DeleteTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateNetworkPolicy(ctx, templateID)
+}
+
+func (q *querier) DeleteTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplatePresetCohortsByTemplateID(ctx, templateID)
+}
+
+func (q *querier) DeleteTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.DeleteTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return q.db.DeleteTemplateSecretByTemplateIDAndName(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateUserPresetByID(ctx context.Context, id uuid.UUID) error {
+	return fetchAndExec(q.log, q.auth, policy.ActionUpdatePersonal, q.db.GetTemplateUserPresetByID, q.db.DeleteTemplateUserPresetByID)(ctx, id)
+}
+
 func (q *querier) DeleteUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	// Removing a user's AI budget override affects both the user (clearing
 	// their per-user spend cap) and the group it was attributed to.
@@ -2592,6 +2768,13 @@ func (q *querier) DeleteUserChatCompactionThreshold(ctx context.Context, arg dat
 	return q.db.DeleteUserChatCompactionThreshold(ctx, arg)
 }
 
+func (q *querier) DeleteUserNotificationScopedPreferences(ctx context.Context, arg database.DeleteUserNotificationScopedPreferencesParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
+		return -1, err
+	}
+	return q.db.DeleteUserNotificationScopedPreferences(ctx, arg)
+}
+
 func (q *querier) DeleteUserSecretByUserIDAndName(ctx context.Context, arg database.DeleteUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
 	obj := rbac.ResourceUserSecret.WithOwner(arg.UserID.String())
 	if err := q.authorizeContext(ctx, policy.ActionDelete, obj); err != nil {
@@ -2608,6 +2791,17 @@ func (q *querier) DeleteUserSkillByUserIDAndName(ctx context.Context, arg databa
 	return q.db.DeleteUserSkillByUserIDAndName(ctx, arg)
 }
 
+func (q *querier) DeleteVCSEventMappingByTemplateIDAndID(ctx context.Context, arg database.DeleteVCSEventMappingByTemplateIDAndIDParams) (database.VCSEventMapping, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	return q.db.DeleteVCSEventMappingByTemplateIDAndID(ctx, arg)
+}
+
 func (q *querier) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceWebpushSubscription.WithOwner(arg.UserID.String())); err != nil {
 		return err
@@ -2642,6 +2836,13 @@ func (q *querier) DeleteWorkspaceACLsByOrganization(ctx context.Context, params
 	return q.db.DeleteWorkspaceACLsByOrganization(ctx, params)
 }
 
+func (q *querier) DeleteWorkspaceAgentCrashLoop(ctx context.Context, agentID uuid.UUID) error {
+	if err := q.authorizeWorkspaceByAgentID(ctx, agentID, policy.ActionUpdate); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceAgentCrashLoop(ctx, agentID)
+}
+
 func (q *querier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
 	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
 	if err != nil {
@@ -2669,6 +2870,76 @@ func (q *querier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context,
 	return q.db.DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID)
 }
 
+func (q *querier) DeleteWorkspaceDNSRecordByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceDNSRecordByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) DeleteWorkspaceDormancyExemption(ctx context.Context, workspaceID uuid.UUID) error {
+	w, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	template, err := q.db.GetTemplateByID(ctx, w.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	// Exemptions override the template dormancy policy, so managing them
+	// requires permission to update the template.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspaceDormancyExemption(ctx, workspaceID)
+}
+
+func (q *querier) DeleteWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) error {
+	w, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	template, err := q.db.GetTemplateByID(ctx, w.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	// Workspace policies override the template policy and workspace owners
+	// must not be able to loosen their own egress restrictions, so managing
+	// them requires permission to update the template.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspaceNetworkPolicy(ctx, workspaceID)
+}
+
+func (q *querier) DeleteWorkspacePortForwardACL(ctx context.Context, arg database.DeleteWorkspacePortForwardACLParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionShare, workspace); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspacePortForwardACL(ctx, arg)
+}
+
+func (q *querier) DeleteWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionShare, workspace); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspacePortForwardACLsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, id)
 	if err != nil {
@@ -2682,6 +2953,34 @@ func (q *querier) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID)
 	return q.db.DeleteWorkspaceSubAgentByID(ctx, id)
 }
 
+func (q *querier) DetachSharedVolumeAttachmentByID(ctx context.Context, arg database.DetachSharedVolumeAttachmentByIDParams) (database.SharedVolumeAttachment, error) {
+	attachment, err := q.db.GetSharedVolumeAttachmentByID(ctx, arg.ID)
+	if err != nil {
+		return database.SharedVolumeAttachment{}, err
+	}
+	volume, err := q.db.GetSharedVolumeByID(ctx, attachment.SharedVolumeID)
+	if err != nil {
+		return database.SharedVolumeAttachment{}, err
+	}
+	// Shared volumes are attached by templates, so they are managed by the
+	// users that can manage the templates of the organization.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceTemplate.InOrg(volume.OrganizationID)); err != nil {
+		return database.SharedVolumeAttachment{}, err
+	}
+	return q.db.DetachSharedVolumeAttachmentByID(ctx, arg)
+}
+
+func (q *querier) DetachSharedVolumeAttachmentsByWorkspaceID(ctx context.Context, arg database.DetachSharedVolumeAttachmentsByWorkspaceIDParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return err
+	}
+	return q.db.DetachSharedVolumeAttachmentsByWorkspaceID(ctx, arg)
+}
+
 func (q *querier) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	if flag.Lookup("test.v") == nil {
 		return xerrors.Errorf("DisableForeignKeysAndTriggers is only allowed in tests")
@@ -2947,6 +3246,36 @@ func (q *querier) GetActivePresetPrebuildSchedules(ctx context.Context) ([]datab
 	return q.db.GetActivePresetPrebuildSchedules(ctx)
 }
 
+func (q *querier) GetActiveSharedVolumeAttachmentsBySharedVolumeID(ctx context.Context, sharedVolumeID uuid.UUID) ([]database.GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow, error) {
+	volume, err := q.db.GetSharedVolumeByID(ctx, sharedVolumeID)
+	if err != nil {
+		return nil, err
+	}
+	// Attachments reveal workspaces across the organization.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTemplate.InOrg(volume.OrganizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetActiveSharedVolumeAttachmentsBySharedVolumeID(ctx, sharedVolumeID)
+}
+
+func (q *querier) GetActiveTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, template); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.GetActiveTemplateVersionRolloutByTemplateID(ctx, templateID)
+}
+
+func (q *querier) GetActiveTemplateVersionRollouts(ctx context.Context) ([]database.TemplateVersionRollout, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetActiveTemplateVersionRollouts(ctx)
+}
+
 func (q *querier) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return 0, err
@@ -3038,6 +3367,28 @@ func (q *querier) GetAutoArchiveInactiveChatCandidates(ctx context.Context, arg
 	return q.db.GetAutoArchiveInactiveChatCandidates(ctx, arg)
 }
 
+func (q *querier) GetAutostopInsights(ctx context.Context, arg database.GetAutostopInsightsParams) ([]database.GetAutostopInsightsRow, error) {
+	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
+		for _, templateID := range arg.TemplateIDs {
+			template, err := q.db.GetTemplateByID(ctx, templateID)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, template); err != nil {
+				return nil, err
+			}
+		}
+		if len(arg.TemplateIDs) == 0 {
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate.All()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return q.db.GetAutostopInsights(ctx, arg)
+}
+
 func (q *querier) GetBoundaryLogByID(ctx context.Context, id uuid.UUID) (database.BoundaryLog, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceBoundaryLog); err != nil {
 		return database.BoundaryLog{}, err
@@ -3801,6 +4152,13 @@ func (q *querier) GetEnabledMCPServerConfigs(ctx context.Context) ([]database.MC
 	return q.db.GetEnabledMCPServerConfigs(ctx)
 }
 
+func (q *querier) GetExpiredWorkspaceLeases(ctx context.Context, arg database.GetExpiredWorkspaceLeasesParams) ([]database.WorkspaceLease, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetExpiredWorkspaceLeases(ctx, arg)
+}
+
 // GetExternalAgentTokensByTemplateID is used for scaletesting purposes; the
 // scaletest agentfake path calls this query directly via a connection to the
 // database. There is no production code path that uses this method, and it is
@@ -3967,6 +4325,20 @@ func (q *querier) GetHighestGroupAIBudgetByUser(ctx context.Context, userID uuid
 	return q.db.GetHighestGroupAIBudgetByUser(ctx, userID)
 }
 
+func (q *querier) GetIdempotencyKey(ctx context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error) {
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return database.IdempotencyKey{}, err
+	}
+	return q.db.GetIdempotencyKey(ctx, arg)
+}
+
+func (q *querier) GetInProgressTemplateWorkspaceRestarts(ctx context.Context) ([]database.TemplateWorkspaceRestart, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetInProgressTemplateWorkspaceRestarts(ctx)
+}
+
 func (q *querier) GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (database.InboxNotification, error) {
 	return fetchWithAction(q.log, q.auth, policy.ActionRead, q.db.GetInboxNotificationByID)(ctx, id)
 }
@@ -3998,13 +4370,38 @@ func (q *querier) GetLatestCryptoKeyByFeature(ctx context.Context, feature datab
 	return q.db.GetLatestCryptoKeyByFeature(ctx, feature)
 }
 
-func (q *querier) GetLatestWorkspaceAgentContextSnapshot(ctx context.Context, workspaceAgentID uuid.UUID) (database.WorkspaceAgentContextSnapshot, error) {
-	if err := q.authorizeWorkspaceByAgentID(ctx, workspaceAgentID, policy.ActionRead); err != nil {
-		return database.WorkspaceAgentContextSnapshot{}, err
+func (q *querier) GetLatestUserWorkspaceReportByUserID(ctx context.Context, userID uuid.UUID) (database.UserWorkspaceReport, error) {
+	user, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return database.UserWorkspaceReport{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, user); err != nil {
+		return database.UserWorkspaceReport{}, err
+	}
+	return q.db.GetLatestUserWorkspaceReportByUserID(ctx, userID)
+}
+
+func (q *querier) GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentBootstrapProgress, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(ctx, ids)
+}
+
+func (q *querier) GetLatestWorkspaceAgentContextSnapshot(ctx context.Context, workspaceAgentID uuid.UUID) (database.WorkspaceAgentContextSnapshot, error) {
+	if err := q.authorizeWorkspaceByAgentID(ctx, workspaceAgentID, policy.ActionRead); err != nil {
+		return database.WorkspaceAgentContextSnapshot{}, err
 	}
 	return q.db.GetLatestWorkspaceAgentContextSnapshot(ctx, workspaceAgentID)
 }
 
+func (q *querier) GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentServiceTransition, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetLatestWorkspaceAppStatusByAppID(ctx context.Context, appID uuid.UUID) (database.WorkspaceAppStatus, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return database.WorkspaceAppStatus{}, err
@@ -4051,6 +4448,14 @@ func (q *querier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, id
 	return q.db.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, ids)
 }
 
+func (q *querier) GetLeakedWorkspaceCloudResources(ctx context.Context) ([]database.GetLeakedWorkspaceCloudResourcesRow, error) {
+	// Leaked resources are listed across all workspaces of the deployment.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWorkspace); err != nil {
+		return nil, err
+	}
+	return q.db.GetLeakedWorkspaceCloudResources(ctx)
+}
+
 func (q *querier) GetLicenseByID(ctx context.Context, id int32) (database.License, error) {
 	return fetch(q.log, q.auth, q.db.GetLicenseByID)(ctx, id)
 }
@@ -4123,6 +4528,13 @@ func (q *querier) GetNotificationMessagesByStatus(ctx context.Context, arg datab
 	return q.db.GetNotificationMessagesByStatus(ctx, arg)
 }
 
+func (q *querier) GetNotificationMessagesByTemplateID(ctx context.Context, arg database.GetNotificationMessagesByTemplateIDParams) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationMessage); err != nil {
+		return nil, err
+	}
+	return q.db.GetNotificationMessagesByTemplateID(ctx, arg)
+}
+
 func (q *querier) GetNotificationReportGeneratorLogByTemplate(ctx context.Context, arg uuid.UUID) (database.NotificationReportGeneratorLog, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return database.NotificationReportGeneratorLog{}, err
@@ -4137,6 +4549,13 @@ func (q *querier) GetNotificationTemplateByID(ctx context.Context, id uuid.UUID)
 	return q.db.GetNotificationTemplateByID(ctx, id)
 }
 
+func (q *querier) GetNotificationTemplateWebhookByTemplateID(ctx context.Context, notificationTemplateID uuid.UUID) (database.NotificationTemplateWebhook, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationTemplate); err != nil {
+		return database.NotificationTemplateWebhook{}, err
+	}
+	return q.db.GetNotificationTemplateWebhookByTemplateID(ctx, notificationTemplateID)
+}
+
 func (q *querier) GetNotificationTemplatesByKind(ctx context.Context, kind database.NotificationTemplateKind) ([]database.NotificationTemplate, error) {
 	// Anyone can read the 'system' and 'custom' notification templates.
 	if kind == database.NotificationTemplateKindSystem || kind == database.NotificationTemplateKindCustom {
@@ -4306,6 +4725,12 @@ func (q *querier) GetOrganizationsWithPrebuildStatus(ctx context.Context, arg da
 	return q.db.GetOrganizationsWithPrebuildStatus(ctx, arg)
 }
 
+func (q *querier) GetOrphanedWorkspaces(ctx context.Context) ([]database.GetOrphanedWorkspacesRow, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, func(ctx context.Context, _ interface{}) ([]database.GetOrphanedWorkspacesRow, error) {
+		return q.db.GetOrphanedWorkspaces(ctx)
+	})(ctx, nil)
+}
+
 func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	version, err := q.db.GetTemplateVersionByJobID(ctx, jobID)
 	if err != nil {
@@ -4327,6 +4752,13 @@ func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUI
 	return q.db.GetParameterSchemasByJobID(ctx, jobID)
 }
 
+func (q *querier) GetPrebuildClaimInsights(ctx context.Context, arg database.GetPrebuildClaimInsightsParams) ([]database.GetPrebuildClaimInsightsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate.All()); err != nil {
+		return nil, err
+	}
+	return q.db.GetPrebuildClaimInsights(ctx, arg)
+}
+
 func (q *querier) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	// GetPrebuildMetrics returns metrics related to prebuilt workspaces,
 	// such as the number of created and failed prebuilt workspaces.
@@ -4382,6 +4814,13 @@ func (q *querier) GetPresetParametersByTemplateVersionID(ctx context.Context, ar
 	return q.db.GetPresetParametersByTemplateVersionID(ctx, args)
 }
 
+func (q *querier) GetPresetPrebuildPoolState(ctx context.Context, arg database.GetPresetPrebuildPoolStateParams) (database.GetPresetPrebuildPoolStateRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate.All()); err != nil {
+		return database.GetPresetPrebuildPoolStateRow{}, err
+	}
+	return q.db.GetPresetPrebuildPoolState(ctx, arg)
+}
+
 func (q *querier) GetPresetsAtFailureLimit(ctx context.Context, hardLimit int64) ([]database.GetPresetsAtFailureLimitRow, error) {
 	// GetPresetsAtFailureLimit returns a list of template version presets that have reached the hard failure limit.
 	// Request the same authorization permissions as GetPresetsBackoff, since the methods are similar.
@@ -4423,6 +4862,11 @@ func (q *querier) GetPreviousTemplateVersion(ctx context.Context, arg database.G
 	return q.db.GetPreviousTemplateVersion(ctx, arg)
 }
 
+func (q *querier) GetProvisionerCacheSettings(ctx context.Context) (string, error) {
+	// No authz checks
+	return q.db.GetProvisionerCacheSettings(ctx)
+}
+
 func (q *querier) GetProvisionerDaemons(ctx context.Context) ([]database.ProvisionerDaemon, error) {
 	fetch := func(ctx context.Context, _ interface{}) ([]database.ProvisionerDaemon, error) {
 		return q.db.GetProvisionerDaemons(ctx)
@@ -4476,6 +4920,29 @@ func (q *querier) GetProvisionerJobByIDWithLock(ctx context.Context, id uuid.UUI
 	return job, nil
 }
 
+func (q *querier) GetProvisionerJobErrorLogsByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]database.ProvisionerJobLog, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobErrorLogsByJobIDs(ctx, jobIds)
+}
+
+func (q *querier) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	// Authorized read on job lets the actor also read the logs archive.
+	_, err := q.GetProvisionerJobByID(ctx, jobID)
+	if err != nil {
+		return database.ProvisionerJobLogArchive{}, err
+	}
+	return q.db.GetProvisionerJobLogArchiveByJobID(ctx, jobID)
+}
+
+func (q *querier) GetProvisionerJobLogArchivesByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]database.ProvisionerJobLogArchive, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobLogArchivesByJobIDs(ctx, jobIds)
+}
+
 func (q *querier) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	_, err := q.GetProvisionerJobByID(ctx, jobID)
 	if err != nil {
@@ -4490,6 +4957,14 @@ func (q *querier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context,
 	return q.db.GetProvisionerJobsByIDsWithQueuePosition(ctx, ids)
 }
 
+func (q *querier) GetProvisionerJobsByInitiatorWithQueuePosition(ctx context.Context, initiatorID uuid.UUID) ([]database.GetProvisionerJobsByInitiatorWithQueuePositionRow, error) {
+	// Users may always list the jobs they started themselves.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(initiatorID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobsByInitiatorWithQueuePosition(ctx, initiatorID)
+}
+
 func (q *querier) GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx context.Context, arg database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerParams) ([]database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerRow, error) {
 	// TODO: Remove this once we have a proper rbac check for provisioner jobs.
 	// Details in https://github.com/coder/coder/issues/16160
@@ -4510,6 +4985,13 @@ func (q *querier) GetProvisionerJobsToBeReaped(ctx context.Context, arg database
 	return q.db.GetProvisionerJobsToBeReaped(ctx, arg)
 }
 
+func (q *querier) GetProvisionerJobsWithExpiredLogs(ctx context.Context, arg database.GetProvisionerJobsWithExpiredLogsParams) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobsWithExpiredLogs(ctx, arg)
+}
+
 func (q *querier) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (database.ProvisionerKey, error) {
 	return fetch(q.log, q.auth, q.db.GetProvisionerKeyByHashedSecret)(ctx, hashedSecret)
 }
@@ -4583,6 +5065,54 @@ func (q *querier) GetRuntimeConfig(ctx context.Context, key string) (string, err
 	return q.db.GetRuntimeConfig(ctx, key)
 }
 
+func (q *querier) GetSensitiveWorkspaceBuildParameters(ctx context.Context) ([]database.WorkspaceBuildParameter, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetSensitiveWorkspaceBuildParameters(ctx)
+}
+
+func (q *querier) GetSharedVolumeAttachmentByID(ctx context.Context, id uuid.UUID) (database.SharedVolumeAttachment, error) {
+	attachment, err := q.db.GetSharedVolumeAttachmentByID(ctx, id)
+	if err != nil {
+		return database.SharedVolumeAttachment{}, err
+	}
+	volume, err := q.db.GetSharedVolumeByID(ctx, attachment.SharedVolumeID)
+	if err != nil {
+		return database.SharedVolumeAttachment{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTemplate.InOrg(volume.OrganizationID)); err != nil {
+		return database.SharedVolumeAttachment{}, err
+	}
+	return attachment, nil
+}
+
+func (q *querier) GetSharedVolumeByID(ctx context.Context, id uuid.UUID) (database.SharedVolume, error) {
+	volume, err := q.db.GetSharedVolumeByID(ctx, id)
+	if err != nil {
+		return database.SharedVolume{}, err
+	}
+	// Shared volumes are visible to everyone in the organization.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOrganization.WithID(volume.OrganizationID).InOrg(volume.OrganizationID)); err != nil {
+		return database.SharedVolume{}, err
+	}
+	return volume, nil
+}
+
+func (q *querier) GetSharedVolumeByOrganizationIDAndName(ctx context.Context, arg database.GetSharedVolumeByOrganizationIDAndNameParams) (database.SharedVolume, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOrganization.WithID(arg.OrganizationID).InOrg(arg.OrganizationID)); err != nil {
+		return database.SharedVolume{}, err
+	}
+	return q.db.GetSharedVolumeByOrganizationIDAndName(ctx, arg)
+}
+
+func (q *querier) GetSharedVolumesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.GetSharedVolumesByOrganizationIDRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOrganization.WithID(organizationID).InOrg(organizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetSharedVolumesByOrganizationID(ctx, organizationID)
+}
+
 func (q *querier) GetStaleChats(ctx context.Context, staleThreshold time.Time) ([]database.Chat, error) {
 	// GetStaleChats is a system-level operation used by the chat processor for recovery.
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceChat); err != nil {
@@ -4659,6 +5189,32 @@ func (q *querier) GetTelemetryTaskEvents(ctx context.Context, arg database.GetTe
 	return q.db.GetTelemetryTaskEvents(ctx, arg)
 }
 
+func (q *querier) GetTemplateActiveDeveloperDays(ctx context.Context, arg database.GetTemplateActiveDeveloperDaysParams) ([]database.GetTemplateActiveDeveloperDaysRow, error) {
+	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
+		for _, templateID := range arg.TemplateIDs {
+			template, err := q.db.GetTemplateByID(ctx, templateID)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, template); err != nil {
+				return nil, err
+			}
+		}
+		if len(arg.TemplateIDs) == 0 {
+			obj := rbac.ResourceTemplate.All()
+			if arg.OrganizationID != uuid.Nil {
+				obj = rbac.ResourceTemplate.InOrg(arg.OrganizationID)
+			}
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, obj); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return q.db.GetTemplateActiveDeveloperDays(ctx, arg)
+}
+
 func (q *querier) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
@@ -4690,6 +5246,25 @@ func (q *querier) GetTemplateByOrganizationAndName(ctx context.Context, arg data
 	return fetch(q.log, q.auth, q.db.GetTemplateByOrganizationAndName)(ctx, arg)
 }
 
+func (q *querier) GetTemplateHealthReportsByTemplateID(ctx context.Context, arg database.GetTemplateHealthReportsByTemplateIDParams) ([]database.TemplateHealthReport, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateHealthReportsByTemplateID(ctx, arg)
+}
+
+// Only used by the report generator.
+func (q *querier) GetTemplateHealthStats(ctx context.Context, since time.Time) ([]database.GetTemplateHealthStatsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateHealthStats(ctx, since)
+}
+
 func (q *querier) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return database.GetTemplateInsightsRow{}, err
@@ -4712,6 +5287,17 @@ func (q *querier) GetTemplateInsightsByTemplate(ctx context.Context, arg databas
 	return q.db.GetTemplateInsightsByTemplate(ctx, arg)
 }
 
+func (q *querier) GetTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) (database.TemplateNetworkPolicy, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return database.TemplateNetworkPolicy{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, template); err != nil {
+		return database.TemplateNetworkPolicy{}, err
+	}
+	return q.db.GetTemplateNetworkPolicy(ctx, templateID)
+}
+
 func (q *querier) GetTemplateParameterInsights(ctx context.Context, arg database.GetTemplateParameterInsightsParams) ([]database.GetTemplateParameterInsightsRow, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
@@ -4719,6 +5305,17 @@ func (q *querier) GetTemplateParameterInsights(ctx context.Context, arg database
 	return q.db.GetTemplateParameterInsights(ctx, arg)
 }
 
+func (q *querier) GetTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplatePresetCohort, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplatePresetCohortsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
 	// GetTemplatePresetsWithPrebuilds retrieves template versions with configured presets and prebuilds.
 	// Presets and prebuilds are part of the template, so if you can access templates - you can access them as well.
@@ -4767,6 +5364,55 @@ func (q *querier) GetTemplateRankingSignalsByOwnerID(ctx context.Context, arg da
 	return q.db.GetTemplateRankingSignalsByOwnerID(ctx, arg)
 }
 
+func (q *querier) GetTemplateSecretByID(ctx context.Context, id uuid.UUID) (database.TemplateSecret, error) {
+	secret, err := q.db.GetTemplateSecretByID(ctx, id)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, secret.TemplateID)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+func (q *querier) GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.GetTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return q.db.GetTemplateSecretByTemplateIDAndName(ctx, arg)
+}
+
+func (q *querier) GetTemplateSecrets(ctx context.Context) ([]database.TemplateSecret, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateSecrets(ctx)
+}
+
+func (q *querier) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateSecret, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err == nil {
+		// The values are fetched by the system on behalf of workspace
+		// agents, which can't manage the template.
+		return q.db.GetTemplateSecretsByTemplateID(ctx, templateID)
+	}
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateSecretsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
@@ -4774,6 +5420,17 @@ func (q *querier) GetTemplateUsageStats(ctx context.Context, arg database.GetTem
 	return q.db.GetTemplateUsageStats(ctx, arg)
 }
 
+func (q *querier) GetTemplateUserPresetByID(ctx context.Context, id uuid.UUID) (database.TemplateUserPreset, error) {
+	return fetchWithAction(q.log, q.auth, policy.ActionReadPersonal, q.db.GetTemplateUserPresetByID)(ctx, id)
+}
+
+func (q *querier) GetTemplateUserPresetsByTemplateIDAndUserID(ctx context.Context, arg database.GetTemplateUserPresetsByTemplateIDAndUserIDParams) ([]database.TemplateUserPreset, error) {
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateUserPresetsByTemplateIDAndUserID(ctx, arg)
+}
+
 func (q *querier) GetTemplateVersionByID(ctx context.Context, tvid uuid.UUID) (database.TemplateVersion, error) {
 	tv, err := q.db.GetTemplateVersionByID(ctx, tvid)
 	if err != nil {
@@ -4849,6 +5506,45 @@ func (q *querier) GetTemplateVersionParameters(ctx context.Context, templateVers
 	return q.db.GetTemplateVersionParameters(ctx, templateVersionID)
 }
 
+func (q *querier) GetTemplateVersionRolloutBuildStats(ctx context.Context, arg database.GetTemplateVersionRolloutBuildStatsParams) (database.GetTemplateVersionRolloutBuildStatsRow, error) {
+	// An actor can read the build stats of a template version if they can
+	// read the version.
+	if _, err := q.GetTemplateVersionByID(ctx, arg.TemplateVersionID); err != nil {
+		return database.GetTemplateVersionRolloutBuildStatsRow{}, err
+	}
+	return q.db.GetTemplateVersionRolloutBuildStats(ctx, arg)
+}
+
+func (q *querier) GetTemplateVersionRolloutByID(ctx context.Context, id uuid.UUID) (database.TemplateVersionRollout, error) {
+	rollout, err := q.db.GetTemplateVersionRolloutByID(ctx, id)
+	if err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	if _, err := q.GetTemplateByID(ctx, rollout.TemplateID); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return rollout, nil
+}
+
+func (q *querier) GetTemplateVersionRolloutsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateVersionRollout, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionRolloutsByTemplateID(ctx, templateID)
+}
+
+func (q *querier) GetTemplateVersionScanByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionScan, error) {
+	// Scans follow the same access control as the template version.
+	if _, err := q.GetTemplateVersionByID(ctx, templateVersionID); err != nil {
+		return database.TemplateVersionScan{}, err
+	}
+	return q.db.GetTemplateVersionScanByTemplateVersionID(ctx, templateVersionID)
+}
+
 func (q *querier) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	// The template_version_terraform_values table should follow the same access
 	// control as the template_version table. Rather than reimplement the checks,
@@ -4938,6 +5634,48 @@ func (q *querier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetTemplateVersionsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetTemplateWorkspaceRestartBuildStats(ctx context.Context, arg database.GetTemplateWorkspaceRestartBuildStatsParams) (database.GetTemplateWorkspaceRestartBuildStatsRow, error) {
+	// An actor can read the build stats of a restart if they can read the
+	// restart.
+	if _, err := q.GetTemplateWorkspaceRestartByID(ctx, arg.RestartID); err != nil {
+		return database.GetTemplateWorkspaceRestartBuildStatsRow{}, err
+	}
+	return q.db.GetTemplateWorkspaceRestartBuildStats(ctx, arg)
+}
+
+func (q *querier) GetTemplateWorkspaceRestartByID(ctx context.Context, id uuid.UUID) (database.TemplateWorkspaceRestart, error) {
+	restart, err := q.db.GetTemplateWorkspaceRestartByID(ctx, id)
+	if err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	if _, err := q.GetTemplateByID(ctx, restart.TemplateID); err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	return restart, nil
+}
+
+func (q *querier) GetTemplateWorkspaceRestartCandidates(ctx context.Context, arg database.GetTemplateWorkspaceRestartCandidatesParams) ([]database.GetTemplateWorkspaceRestartCandidatesRow, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateWorkspaceRestartCandidates(ctx, arg)
+}
+
+func (q *querier) GetTemplateWorkspaceRestartsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateWorkspaceRestart, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateWorkspaceRestartsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplates(ctx context.Context) ([]database.Template, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -4967,6 +5705,13 @@ func (q *querier) GetUnexpiredLicenses(ctx context.Context) ([]database.License,
 	return q.db.GetUnexpiredLicenses(ctx)
 }
 
+func (q *querier) GetUnverifiedWorkspaceCloudResources(ctx context.Context, arg database.GetUnverifiedWorkspaceCloudResourcesParams) ([]database.GetUnverifiedWorkspaceCloudResourcesRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetUnverifiedWorkspaceCloudResources(ctx, arg)
+}
+
 func (q *querier) GetUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(userID)); err != nil {
 		return database.UserAIBudgetOverride{}, err
@@ -5157,6 +5902,35 @@ func (q *querier) GetUserGroupSpendLimit(ctx context.Context, arg database.GetUs
 	return q.db.GetUserGroupSpendLimit(ctx, arg)
 }
 
+func (q *querier) GetUserImpersonationByAPIKeyID(ctx context.Context, apiKeyID string) (database.UserImpersonation, error) {
+	impersonation, err := q.db.GetUserImpersonationByAPIKeyID(ctx, apiKeyID)
+	if err != nil {
+		return database.UserImpersonation{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUserObject(impersonation.UserID)); err != nil {
+		return database.UserImpersonation{}, err
+	}
+	return impersonation, nil
+}
+
+func (q *querier) GetUserImpersonationByID(ctx context.Context, id uuid.UUID) (database.UserImpersonation, error) {
+	impersonation, err := q.db.GetUserImpersonationByID(ctx, id)
+	if err != nil {
+		return database.UserImpersonation{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUserObject(impersonation.UserID)); err != nil {
+		return database.UserImpersonation{}, err
+	}
+	return impersonation, nil
+}
+
+func (q *querier) GetUserImpersonationsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserImpersonation, error) {
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUserObject(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserImpersonationsByUserID(ctx, userID)
+}
+
 func (q *querier) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
 	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
@@ -5207,6 +5981,13 @@ func (q *querier) GetUserNotificationPreferences(ctx context.Context, userID uui
 	return q.db.GetUserNotificationPreferences(ctx, userID)
 }
 
+func (q *querier) GetUserNotificationScopedPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationScopedPreference, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationPreference.WithOwner(userID.String())); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserNotificationScopedPreferences(ctx, userID)
+}
+
 func (q *querier) GetUserSecretByID(ctx context.Context, id uuid.UUID) (database.UserSecret, error) {
 	return fetch(q.log, q.auth, q.db.GetUserSecretByID)(ctx, id)
 }
@@ -5294,6 +6075,25 @@ func (q *querier) GetUserWorkspaceBuildParameters(ctx context.Context, params da
 	return q.db.GetUserWorkspaceBuildParameters(ctx, params)
 }
 
+// Only used by the report generator.
+func (q *querier) GetUserWorkspaceReportStats(ctx context.Context, arg database.GetUserWorkspaceReportStatsParams) ([]database.GetUserWorkspaceReportStatsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserWorkspaceReportStats(ctx, arg)
+}
+
+func (q *querier) GetUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, user); err != nil {
+		return false, err
+	}
+	return q.db.GetUserWorkspaceSelfRequestedBuildsAllowed(ctx, userID)
+}
+
 func (q *querier) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	// This does the filtering in SQL.
 	prep, err := prepareSQLFilter(ctx, q.auth, policy.ActionRead, rbac.ResourceUser.Type)
@@ -5315,6 +6115,50 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetVCSEventMappingByID(ctx context.Context, id uuid.UUID) (database.VCSEventMapping, error) {
+	mapping, err := q.db.GetVCSEventMappingByID(ctx, id)
+	if err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, mapping.TemplateID)
+	if err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	return mapping, nil
+}
+
+func (q *querier) GetVCSEventMappings(ctx context.Context) ([]database.VCSEventMapping, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetVCSEventMappings(ctx)
+}
+
+func (q *querier) GetVCSEventMappingsByRepository(ctx context.Context, repository string) ([]database.VCSEventMapping, error) {
+	// Events are received without authentication, and verified against the
+	// signing secrets of the mappings by the system.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetVCSEventMappingsByRepository(ctx, repository)
+}
+
+func (q *querier) GetVCSEventMappingsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.VCSEventMapping, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	// Mappings include their signing secrets, so they are only visible to
+	// the users that can manage the template.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetVCSEventMappingsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWebpushSubscription.WithOwner(userID.String())); err != nil {
 		return nil, err
@@ -5344,6 +6188,20 @@ func (q *querier) GetWorkspaceAgentAndWorkspaceByID(ctx context.Context, id uuid
 	return fetch(q.log, q.auth, q.db.GetWorkspaceAgentAndWorkspaceByID)(ctx, id)
 }
 
+func (q *querier) GetWorkspaceAgentBootstrapProgressByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentBootstrapProgress, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, workspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentBootstrapProgressByAgentID(ctx, workspaceAgentID)
+}
+
 func (q *querier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgent, error) {
 	// Fast path: Check if we have a workspace RBAC object in context.
 	// In the agent API this is set at agent connection time to avoid the expensive
@@ -5366,6 +6224,20 @@ func (q *querier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (data
 	return q.db.GetWorkspaceAgentByID(ctx, id)
 }
 
+func (q *querier) GetWorkspaceAgentCrashLoopByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentCrashLoop, error) {
+	if err := q.authorizeWorkspaceByAgentID(ctx, agentID, policy.ActionRead); err != nil {
+		return database.WorkspaceAgentCrashLoop{}, err
+	}
+	return q.db.GetWorkspaceAgentCrashLoopByAgentID(ctx, agentID)
+}
+
+func (q *querier) GetWorkspaceAgentCrashLoopsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentCrashLoop, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentCrashLoopsByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	_, err := q.GetWorkspaceAgentByID(ctx, workspaceAgentID)
 	if err != nil {
@@ -5397,6 +6269,20 @@ func (q *querier) GetWorkspaceAgentLogsAfter(ctx context.Context, arg database.G
 	return q.db.GetWorkspaceAgentLogsAfter(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAgentMemoryEventCountsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentMemoryEventCountsByAgentIDsParams) ([]database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentMemoryEventCountsByAgentIDs(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentMemoryEventsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentMemoryEventsByAgentIDsParams) ([]database.WorkspaceAgentMemoryEvent, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentMemoryEventsByAgentIDs(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, arg database.GetWorkspaceAgentMetadataParams) ([]database.WorkspaceAgentMetadatum, error) {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
 	if err != nil {
@@ -5411,6 +6297,34 @@ func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, arg database.Ge
 	return q.db.GetWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.GetWorkspaceAgentMetadataHistoryRow, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentMetadataHistory(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsParams) ([]database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDsParams) ([]database.WorkspaceAgentNetworkPolicyViolation, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
 	if err != nil {
@@ -5439,6 +6353,20 @@ func (q *querier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []
 	return q.db.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
 }
 
+func (q *querier) GetWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentServiceTransition, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentServiceTransitionsByAgentIDs(ctx, ids)
+}
+
+func (q *querier) GetWorkspaceAgentServicesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentService, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentServicesByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceAgentStats(ctx context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	return q.db.GetWorkspaceAgentStats(ctx, createdAfter)
 }
@@ -5555,6 +6483,33 @@ func (q *querier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg datab
 	return q.db.GetWorkspaceAppByAgentIDAndSlug(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAppShareLinkByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAppShareLink, error) {
+	link, err := q.db.GetWorkspaceAppShareLinkByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceAppShareLink{}, err
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, link.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceAppShareLink{}, err
+	}
+	// reading a share link is more akin to reading the workspace.
+	if err := q.authorizeContext(ctx, policy.ActionRead, workspace); err != nil {
+		return database.WorkspaceAppShareLink{}, err
+	}
+	return link, nil
+}
+
+func (q *querier) GetWorkspaceAppShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAppShareLink, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, workspace); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAppShareLinksByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceAppStatusesByAppIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAppStatus, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -5593,6 +6548,13 @@ func (q *querier) GetWorkspaceBuildAgentsByInstanceID(ctx context.Context, authI
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetWorkspaceBuildAgentsByInstanceID)(ctx, authInstanceID)
 }
 
+func (q *querier) GetWorkspaceBuildAnnotationsByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]database.GetWorkspaceBuildAnnotationsByBuildIDsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildAnnotationsByBuildIDs(ctx, workspaceBuildIds)
+}
+
 func (q *querier) GetWorkspaceBuildByID(ctx context.Context, buildID uuid.UUID) (database.WorkspaceBuild, error) {
 	build, err := q.db.GetWorkspaceBuildByID(ctx, buildID)
 	if err != nil {
@@ -5624,6 +6586,15 @@ func (q *querier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Conte
 	return q.db.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceBuildInputsByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildInput, error) {
+	// Authorized call to get the workspace build. If we can read the build,
+	// we can read its inputs.
+	if _, err := q.GetWorkspaceBuildByID(ctx, workspaceBuildID); err != nil {
+		return database.WorkspaceBuildInput{}, err
+	}
+	return q.db.GetWorkspaceBuildInputsByBuildID(ctx, workspaceBuildID)
+}
+
 func (q *querier) GetWorkspaceBuildMetricsByResourceID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceBuildMetricsByResourceIDRow, error) {
 	// Verify access to the resource first.
 	if _, err := q.GetWorkspaceResourceByID(ctx, id); err != nil {
@@ -5632,6 +6603,13 @@ func (q *querier) GetWorkspaceBuildMetricsByResourceID(ctx context.Context, id u
 	return q.db.GetWorkspaceBuildMetricsByResourceID(ctx, id)
 }
 
+func (q *querier) GetWorkspaceBuildParameterChangesByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]database.WorkspaceBuildParameterChange, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildParameterChangesByBuildIDs(ctx, workspaceBuildIds)
+}
+
 func (q *querier) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	// Authorized call to get the workspace build. If we can read the build,
 	// we can read the params.
@@ -5680,16 +6658,120 @@ func (q *querier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (database.
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByID)(ctx, id)
 }
 
-func (q *querier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg database.GetWorkspaceByOwnerIDAndNameParams) (database.Workspace, error) {
-	return fetch(q.log, q.auth, q.db.GetWorkspaceByOwnerIDAndName)(ctx, arg)
+func (q *querier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg database.GetWorkspaceByOwnerIDAndNameParams) (database.Workspace, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceByOwnerIDAndName)(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceByResourceID(ctx context.Context, resourceID uuid.UUID) (database.Workspace, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceByResourceID)(ctx, resourceID)
+}
+
+func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (database.Workspace, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
+}
+
+func (q *querier) GetWorkspaceCloudResourcesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceCloudResource, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, workspace); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceCloudResourcesByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceDNSRecordChanges(ctx context.Context, arg database.GetWorkspaceDNSRecordChangesParams) ([]database.GetWorkspaceDNSRecordChangesRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceDNSRecordChanges(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceDNSRecordsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceDNSRecord, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceDNSRecordsByWorkspaceIDs(ctx, ids)
+}
+
+func (q *querier) GetWorkspaceDormancyExemptionByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceDormancyExemption, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return database.WorkspaceDormancyExemption{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionRead, w); err != nil {
+		return database.WorkspaceDormancyExemption{}, err
+	}
+
+	return q.db.GetWorkspaceDormancyExemptionByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceDormancyHookByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceDormancyHook, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return database.WorkspaceDormancyHook{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionRead, w); err != nil {
+		return database.WorkspaceDormancyHook{}, err
+	}
+
+	return q.db.GetWorkspaceDormancyHookByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceEgressInsights(ctx context.Context, arg database.GetWorkspaceEgressInsightsParams) ([]database.GetWorkspaceEgressInsightsRow, error) {
+	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
+		for _, templateID := range arg.TemplateIDs {
+			template, err := q.db.GetTemplateByID(ctx, templateID)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, template); err != nil {
+				return nil, err
+			}
+		}
+		if len(arg.TemplateIDs) == 0 {
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate.All()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return q.db.GetWorkspaceEgressInsights(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceLeaseByID(ctx context.Context, id uuid.UUID) (database.WorkspaceLease, error) {
+	lease, err := q.db.GetWorkspaceLeaseByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceLease{}, err
+	}
+
+	w, err := q.db.GetWorkspaceByID(ctx, lease.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceLease{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionRead, w); err != nil {
+		return database.WorkspaceLease{}, err
+	}
+
+	return lease, nil
 }
 
-func (q *querier) GetWorkspaceByResourceID(ctx context.Context, resourceID uuid.UUID) (database.Workspace, error) {
-	return fetch(q.log, q.auth, q.db.GetWorkspaceByResourceID)(ctx, resourceID)
-}
+func (q *querier) GetWorkspaceLifetimeWarningByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceLifetimeWarning, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return database.WorkspaceLifetimeWarning{}, err
+	}
 
-func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (database.Workspace, error) {
-	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
+	if err := q.authorizeContext(ctx, policy.ActionRead, w); err != nil {
+		return database.WorkspaceLifetimeWarning{}, err
+	}
+
+	return q.db.GetWorkspaceLifetimeWarningByWorkspaceID(ctx, workspaceID)
 }
 
 func (q *querier) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
@@ -5706,6 +6788,52 @@ func (q *querier) GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetWorkspaceModulesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceMonthlyCost(ctx context.Context, arg database.GetWorkspaceMonthlyCostParams) (database.WorkspaceMonthlyCost, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceMonthlyCost{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionRead, w); err != nil {
+		return database.WorkspaceMonthlyCost{}, err
+	}
+
+	return q.db.GetWorkspaceMonthlyCost(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceNetworkPolicy, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return database.WorkspaceNetworkPolicy{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, workspace); err != nil {
+		return database.WorkspaceNetworkPolicy{}, err
+	}
+	return q.db.GetWorkspaceNetworkPolicy(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspacePortForwardACL(ctx context.Context, arg database.GetWorkspacePortForwardACLParams) (database.WorkspacePortForwardACL, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspacePortForwardACL{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, workspace); err != nil {
+		return database.WorkspacePortForwardACL{}, err
+	}
+	return q.db.GetWorkspacePortForwardACL(ctx, arg)
+}
+
+func (q *querier) GetWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortForwardACL, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, workspace); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacePortForwardACLsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, func(ctx context.Context, _ interface{}) ([]database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxies(ctx)
@@ -5727,6 +6855,20 @@ func (q *querier) GetWorkspaceProxyByName(ctx context.Context, name string) (dat
 	return fetch(q.log, q.auth, q.db.GetWorkspaceProxyByName)(ctx, name)
 }
 
+func (q *querier) GetWorkspaceRegionAssignmentCounts(ctx context.Context) ([]database.GetWorkspaceRegionAssignmentCountsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceRegionAssignmentCounts(ctx)
+}
+
+func (q *querier) GetWorkspaceRegionAssignmentsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceRegionAssignment, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceRegionAssignmentsByWorkspaceIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	// TODO: Optimize this
 	resource, err := q.db.GetWorkspaceResourceByID(ctx, id)
@@ -5854,6 +6996,13 @@ func (q *querier) GetWorkspacesEligibleForLifecycleAction(ctx context.Context, n
 	return q.db.GetWorkspacesEligibleForLifecycleAction(ctx, now)
 }
 
+func (q *querier) GetWorkspacesExceedingCostBudget(ctx context.Context, arg database.GetWorkspacesExceedingCostBudgetParams) ([]database.GetWorkspacesExceedingCostBudgetRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacesExceedingCostBudget(ctx, arg)
+}
+
 func (q *querier) GetWorkspacesForWorkspaceMetrics(ctx context.Context) ([]database.GetWorkspacesForWorkspaceMetricsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWorkspace); err != nil {
 		return nil, err
@@ -6177,6 +7326,13 @@ func (q *querier) InsertGroupMember(ctx context.Context, arg database.InsertGrou
 	return update(q.log, q.auth, fetch, q.db.InsertGroupMember)(ctx, arg)
 }
 
+func (q *querier) InsertIdempotencyKey(ctx context.Context, arg database.InsertIdempotencyKeyParams) (database.IdempotencyKey, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return database.IdempotencyKey{}, err
+	}
+	return q.db.InsertIdempotencyKey(ctx, arg)
+}
+
 func (q *querier) InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
 	return insert(q.log, q.auth, rbac.ResourceInboxNotification.WithOwner(arg.UserID.String()), q.db.InsertInboxNotification)(ctx, arg)
 }
@@ -6275,6 +7431,13 @@ func (q *querier) InsertOrganizationMember(ctx context.Context, arg database.Ins
 	return insert(q.log, q.auth, obj, q.db.InsertOrganizationMember)(ctx, arg)
 }
 
+func (q *querier) InsertPrebuildClaimAttempt(ctx context.Context, arg database.InsertPrebuildClaimAttemptParams) (database.PrebuildClaimAttempt, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.PrebuildClaimAttempt{}, err
+	}
+	return q.db.InsertPrebuildClaimAttempt(ctx, arg)
+}
+
 func (q *querier) InsertPreset(ctx context.Context, arg database.InsertPresetParams) (database.TemplateVersionPreset, error) {
 	err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceTemplate)
 	if err != nil {
@@ -6308,6 +7471,13 @@ func (q *querier) InsertProvisionerJob(ctx context.Context, arg database.InsertP
 	return q.db.InsertProvisionerJob(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerJobLogArchive(ctx context.Context, arg database.InsertProvisionerJobLogArchiveParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceProvisionerJobs); err != nil {
+		return err
+	}
+	return q.db.InsertProvisionerJobLogArchive(ctx, arg)
+}
+
 func (q *querier) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	// TODO: Remove this once we have a proper rbac check for provisioner jobs.
 	// Details in https://github.com/coder/coder/issues/16160
@@ -6332,6 +7502,24 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertSharedVolume(ctx context.Context, arg database.InsertSharedVolumeParams) (database.SharedVolume, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceTemplate.InOrg(arg.OrganizationID)); err != nil {
+		return database.SharedVolume{}, err
+	}
+	return q.db.InsertSharedVolume(ctx, arg)
+}
+
+func (q *querier) InsertSharedVolumeAttachment(ctx context.Context, arg database.InsertSharedVolumeAttachmentParams) (database.SharedVolumeAttachment, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.SharedVolumeAttachment{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.SharedVolumeAttachment{}, err
+	}
+	return q.db.InsertSharedVolumeAttachment(ctx, arg)
+}
+
 func (q *querier) InsertTask(ctx context.Context, arg database.InsertTaskParams) (database.TaskTable, error) {
 	// Ensure the actor can access the specified template version (and thus its template).
 	if _, err := q.GetTemplateVersionByID(ctx, arg.TemplateVersionID); err != nil {
@@ -6365,6 +7553,35 @@ func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplat
 	return q.db.InsertTemplate(ctx, arg)
 }
 
+func (q *querier) InsertTemplateHealthReport(ctx context.Context, arg database.InsertTemplateHealthReportParams) (database.TemplateHealthReport, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.TemplateHealthReport{}, err
+	}
+	return q.db.InsertTemplateHealthReport(ctx, arg)
+}
+
+func (q *querier) InsertTemplatePresetCohort(ctx context.Context, arg database.InsertTemplatePresetCohortParams) (database.TemplatePresetCohort, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplatePresetCohort{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplatePresetCohort{}, err
+	}
+	return q.db.InsertTemplatePresetCohort(ctx, arg)
+}
+
+func (q *querier) InsertTemplateSecret(ctx context.Context, arg database.InsertTemplateSecretParams) (database.TemplateSecret, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return q.db.InsertTemplateSecret(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	if !arg.TemplateID.Valid {
 		// Making a new template version is the same permission as creating a new template.
@@ -6395,6 +7612,17 @@ func (q *querier) InsertTemplateVersionParameter(ctx context.Context, arg databa
 	return q.db.InsertTemplateVersionParameter(ctx, arg)
 }
 
+func (q *querier) InsertTemplateVersionRollout(ctx context.Context, arg database.InsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.InsertTemplateVersionRollout(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg database.InsertTemplateVersionTerraformValuesByJobIDParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -6416,6 +7644,32 @@ func (q *querier) InsertTemplateVersionWorkspaceTag(ctx context.Context, arg dat
 	return q.db.InsertTemplateVersionWorkspaceTag(ctx, arg)
 }
 
+func (q *querier) InsertTemplateWorkspaceRestart(ctx context.Context, arg database.InsertTemplateWorkspaceRestartParams) (database.TemplateWorkspaceRestart, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	return q.db.InsertTemplateWorkspaceRestart(ctx, arg)
+}
+
+func (q *querier) InsertTemplateWorkspaceRestartBuild(ctx context.Context, arg database.InsertTemplateWorkspaceRestartBuildParams) error {
+	restart, err := q.db.GetTemplateWorkspaceRestartByID(ctx, arg.RestartID)
+	if err != nil {
+		return err
+	}
+	template, err := q.db.GetTemplateByID(ctx, restart.TemplateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return err
+	}
+	return q.db.InsertTemplateWorkspaceRestartBuild(ctx, arg)
+}
+
 func (q *querier) InsertUsageEvent(ctx context.Context, arg database.InsertUsageEventParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceUsageEvent); err != nil {
 		return err
@@ -6442,6 +7696,13 @@ func (q *querier) InsertUserGroupsByID(ctx context.Context, arg database.InsertU
 	return q.db.InsertUserGroupsByID(ctx, arg)
 }
 
+func (q *querier) InsertUserImpersonation(ctx context.Context, arg database.InsertUserImpersonationParams) (database.UserImpersonation, error) {
+	if err := q.authorizeContext(ctx, policy.ActionImpersonate, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return database.UserImpersonation{}, err
+	}
+	return q.db.InsertUserImpersonation(ctx, arg)
+}
+
 // TODO: Should this be in system.go?
 func (q *querier) InsertUserLink(ctx context.Context, arg database.InsertUserLinkParams) (database.UserLink, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceUserObject(arg.UserID)); err != nil {
@@ -6458,6 +7719,24 @@ func (q *querier) InsertUserSkill(ctx context.Context, arg database.InsertUserSk
 	return q.db.InsertUserSkill(ctx, arg)
 }
 
+func (q *querier) InsertUserWorkspaceReport(ctx context.Context, arg database.InsertUserWorkspaceReportParams) (database.UserWorkspaceReport, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.UserWorkspaceReport{}, err
+	}
+	return q.db.InsertUserWorkspaceReport(ctx, arg)
+}
+
+func (q *querier) InsertVCSEventMapping(ctx context.Context, arg database.InsertVCSEventMappingParams) (database.VCSEventMapping, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	return q.db.InsertVCSEventMapping(ctx, arg)
+}
+
 func (q *querier) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
 		return database.WorkspaceAgentVolumeResourceMonitor{}, err
@@ -6508,6 +7787,19 @@ func (q *querier) InsertWorkspaceAgent(ctx context.Context, arg database.InsertW
 	return q.db.InsertWorkspaceAgent(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentBootstrapProgress(ctx context.Context, arg database.InsertWorkspaceAgentBootstrapProgressParams) (database.WorkspaceAgentBootstrapProgress, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return database.WorkspaceAgentBootstrapProgress{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentBootstrapProgress{}, err
+	}
+
+	return q.db.InsertWorkspaceAgentBootstrapProgress(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentDevcontainers(ctx context.Context, arg database.InsertWorkspaceAgentDevcontainersParams) ([]database.WorkspaceAgentDevcontainer, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWorkspaceAgentDevcontainers); err != nil {
 		return nil, err
@@ -6525,6 +7817,14 @@ func (q *querier) InsertWorkspaceAgentLogs(ctx context.Context, arg database.Ins
 	return q.db.InsertWorkspaceAgentLogs(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentMemoryEvent(ctx context.Context, arg database.InsertWorkspaceAgentMemoryEventParams) (database.WorkspaceAgentMemoryEvent, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
+		return database.WorkspaceAgentMemoryEvent{}, err
+	}
+
+	return q.db.InsertWorkspaceAgentMemoryEvent(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database.InsertWorkspaceAgentMetadataParams) error {
 	// We don't check for workspace ownership here since the agent metadata may
 	// be associated with an orphaned agent used by a dry run build.
@@ -6535,6 +7835,28 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	// Could be any workspace agent and checking auth to each workspace agent is overkill for
+	// the purpose of this function.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspace.All()); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceAgentMetadataHistory(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceAgentNetworkPolicyViolations(ctx context.Context, arg database.InsertWorkspaceAgentNetworkPolicyViolationsParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.InsertWorkspaceAgentNetworkPolicyViolations(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.WorkspaceAgentScriptTiming{}, err
@@ -6549,6 +7871,26 @@ func (q *querier) InsertWorkspaceAgentScripts(ctx context.Context, arg database.
 	return q.db.InsertWorkspaceAgentScripts(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentServiceTransition(ctx context.Context, arg database.InsertWorkspaceAgentServiceTransitionParams) (database.WorkspaceAgentServiceTransition, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return database.WorkspaceAgentServiceTransition{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentServiceTransition{}, err
+	}
+
+	return q.db.InsertWorkspaceAgentServiceTransition(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceAgentServices(ctx context.Context, arg database.InsertWorkspaceAgentServicesParams) ([]database.WorkspaceAgentService, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return []database.WorkspaceAgentService{}, err
+	}
+	return q.db.InsertWorkspaceAgentServices(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentStats(ctx context.Context, arg database.InsertWorkspaceAgentStatsParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -6557,6 +7899,17 @@ func (q *querier) InsertWorkspaceAgentStats(ctx context.Context, arg database.In
 	return q.db.InsertWorkspaceAgentStats(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAppShareLink(ctx context.Context, arg database.InsertWorkspaceAppShareLinkParams) (database.WorkspaceAppShareLink, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceAppShareLink{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionShare, workspace); err != nil {
+		return database.WorkspaceAppShareLink{}, err
+	}
+	return q.db.InsertWorkspaceAppShareLink(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAppStats(ctx context.Context, arg database.InsertWorkspaceAppStatsParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -6611,6 +7964,32 @@ func (q *querier) InsertWorkspaceBuild(ctx context.Context, arg database.InsertW
 	return q.db.InsertWorkspaceBuild(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceBuildAnnotation(ctx context.Context, arg database.InsertWorkspaceBuildAnnotationParams) (database.WorkspaceBuildAnnotation, error) {
+	build, err := q.db.GetWorkspaceBuildByID(ctx, arg.WorkspaceBuildID)
+	if err != nil {
+		return database.WorkspaceBuildAnnotation{}, err
+	}
+
+	workspace, err := q.db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceBuildAnnotation{}, err
+	}
+
+	// Annotating a build requires the same permission as updating the
+	// workspace it belongs to.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceBuildAnnotation{}, err
+	}
+	return q.db.InsertWorkspaceBuildAnnotation(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceBuildInputs(ctx context.Context, arg database.InsertWorkspaceBuildInputsParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceBuildInputs(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceBuildOrchestration(ctx context.Context, arg database.InsertWorkspaceBuildOrchestrationParams) (database.WorkspaceBuildOrchestration, error) {
 	// Read through the raw q.db to fetch the authz context; authorization
 	// happens via q.authorizeContext below, as in InsertWorkspaceBuild.
@@ -6671,6 +8050,31 @@ func (q *querier) InsertWorkspaceBuildOrchestration(ctx context.Context, arg dat
 	return q.db.InsertWorkspaceBuildOrchestration(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceBuildParameterChanges(ctx context.Context, arg database.InsertWorkspaceBuildParameterChangesParams) error {
+	// Parameter changes are recorded alongside the build parameters, so
+	// they require the same permission.
+	build, err := q.db.GetWorkspaceBuildByID(ctx, arg.WorkspaceBuildID)
+	if err != nil {
+		return err
+	}
+
+	workspace, err := q.db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	action, err := workspaceTransitionAction(build.Transition)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizePrebuiltWorkspace(ctx, action, workspace); err != nil {
+		return err
+	}
+
+	return q.db.InsertWorkspaceBuildParameterChanges(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	// TODO: Optimize this. We always have the workspace and build already fetched.
 	build, err := q.db.GetWorkspaceBuildByID(ctx, arg.WorkspaceBuildID)
@@ -6696,6 +8100,19 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceLease(ctx context.Context, arg database.InsertWorkspaceLeaseParams) (database.WorkspaceLease, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceLease{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.WorkspaceLease{}, err
+	}
+
+	return q.db.InsertWorkspaceLease(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.WorkspaceModule{}, err
@@ -7046,6 +8463,24 @@ func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.Regis
 	return updateWithReturn(q.log, q.auth, fetch, q.db.RegisterWorkspaceProxy)(ctx, arg)
 }
 
+func (q *querier) ReleaseWorkspaceLease(ctx context.Context, arg database.ReleaseWorkspaceLeaseParams) (database.WorkspaceLease, error) {
+	lease, err := q.db.GetWorkspaceLeaseByID(ctx, arg.ID)
+	if err != nil {
+		return database.WorkspaceLease{}, err
+	}
+
+	w, err := q.db.GetWorkspaceByID(ctx, lease.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceLease{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.WorkspaceLease{}, err
+	}
+
+	return q.db.ReleaseWorkspaceLease(ctx, arg)
+}
+
 func (q *querier) RemoveUserFromGroups(ctx context.Context, arg database.RemoveUserFromGroupsParams) ([]uuid.UUID, error) {
 	// This is a system function to clear user groups in group sync.
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -7084,11 +8519,41 @@ func (q *querier) ResolveUserChatSpendLimit(ctx context.Context, arg database.Re
 	return q.db.ResolveUserChatSpendLimit(ctx, arg)
 }
 
+func (q *querier) ResumeTemplateWorkspaceRestart(ctx context.Context, arg database.ResumeTemplateWorkspaceRestartParams) (database.TemplateWorkspaceRestart, error) {
+	restart, err := q.db.GetTemplateWorkspaceRestartByID(ctx, arg.ID)
+	if err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, restart.TemplateID)
+	if err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	return q.db.ResumeTemplateWorkspaceRestart(ctx, arg)
+}
+
 func (q *querier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
 	}
-	return q.db.RevokeDBCryptKey(ctx, activeKeyDigest)
+	return q.db.RevokeDBCryptKey(ctx, activeKeyDigest)
+}
+
+func (q *querier) RevokeWorkspaceAppShareLink(ctx context.Context, arg database.RevokeWorkspaceAppShareLinkParams) (database.WorkspaceAppShareLink, error) {
+	link, err := q.db.GetWorkspaceAppShareLinkByID(ctx, arg.ID)
+	if err != nil {
+		return database.WorkspaceAppShareLink{}, err
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, link.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceAppShareLink{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionShare, workspace); err != nil {
+		return database.WorkspaceAppShareLink{}, err
+	}
+	return q.db.RevokeWorkspaceAppShareLink(ctx, arg)
 }
 
 func (q *querier) SelectUsageEventsForPublishing(ctx context.Context, arg time.Time) ([]database.UsageEvent, error) {
@@ -7593,6 +9058,13 @@ func (q *querier) UpdateEncryptedAIProviderSettings(ctx context.Context, arg dat
 	return q.db.UpdateEncryptedAIProviderSettings(ctx, arg)
 }
 
+func (q *querier) UpdateEncryptedTemplateSecret(ctx context.Context, arg database.UpdateEncryptedTemplateSecretParams) (database.TemplateSecret, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return q.db.UpdateEncryptedTemplateSecret(ctx, arg)
+}
+
 func (q *querier) UpdateEncryptedUserAIProviderKey(ctx context.Context, arg database.UpdateEncryptedUserAIProviderKeyParams) (database.UserAIProviderKey, error) {
 	// Encrypted user-owned provider keys can be rewritten on any row so
 	// dbcrypt rotation can move every key to a new digest. This is a
@@ -7603,6 +9075,20 @@ func (q *querier) UpdateEncryptedUserAIProviderKey(ctx context.Context, arg data
 	return q.db.UpdateEncryptedUserAIProviderKey(ctx, arg)
 }
 
+func (q *querier) UpdateEncryptedVCSEventMapping(ctx context.Context, arg database.UpdateEncryptedVCSEventMappingParams) (database.VCSEventMapping, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	return q.db.UpdateEncryptedVCSEventMapping(ctx, arg)
+}
+
+func (q *querier) UpdateEncryptedWorkspaceBuildParameter(ctx context.Context, arg database.UpdateEncryptedWorkspaceBuildParameterParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateEncryptedWorkspaceBuildParameter(ctx, arg)
+}
+
 func (q *querier) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	fetch := func(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		return q.db.GetExternalAuthLink(ctx, database.GetExternalAuthLinkParams{UserID: arg.UserID, ProviderID: arg.ProviderID})
@@ -7776,6 +9262,13 @@ func (q *querier) UpdateOrganizationDeletedByID(ctx context.Context, arg databas
 	return deleteQ(q.log, q.auth, q.db.GetOrganizationByID, deleteF)(ctx, arg.ID)
 }
 
+func (q *querier) UpdateOrganizationProvisionerDaemonSettings(ctx context.Context, arg database.UpdateOrganizationProvisionerDaemonSettingsParams) (database.Organization, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOrganizationProvisionerDaemonSettingsParams) (database.Organization, error) {
+		return q.db.GetOrganizationByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOrganizationProvisionerDaemonSettings)(ctx, arg)
+}
+
 func (q *querier) UpdateOrganizationWorkspaceSharingSettings(ctx context.Context, arg database.UpdateOrganizationWorkspaceSharingSettingsParams) (database.Organization, error) {
 	fetch := func(ctx context.Context, arg database.UpdateOrganizationWorkspaceSharingSettingsParams) (database.Organization, error) {
 		return q.db.GetOrganizationByID(ctx, arg.ID)
@@ -7854,6 +9347,13 @@ func (q *querier) UpdateProvisionerJobLogsOverflowed(ctx context.Context, arg da
 	return q.db.UpdateProvisionerJobLogsOverflowed(ctx, arg)
 }
 
+func (q *querier) UpdateProvisionerJobTimeoutByID(ctx context.Context, arg database.UpdateProvisionerJobTimeoutByIDParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceProvisionerJobs); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerJobTimeoutByID(ctx, arg)
+}
+
 func (q *querier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	// TODO: Remove this once we have a proper rbac check for provisioner jobs.
 	// Details in https://github.com/coder/coder/issues/16160
@@ -7938,6 +9438,13 @@ func (q *querier) UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx context.
 	return q.db.UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx, arg)
 }
 
+func (q *querier) UpdateProvisionerJobWithRequeueByID(ctx context.Context, arg database.UpdateProvisionerJobWithRequeueByIDParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceProvisionerJobs); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerJobWithRequeueByID(ctx, arg)
+}
+
 func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -8033,6 +9540,17 @@ func (q *querier) UpdateTemplateScheduleByID(ctx context.Context, arg database.U
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateScheduleByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.UpdateTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return q.db.UpdateTemplateSecretByTemplateIDAndName(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	// An actor is allowed to update the template version if they are authorized to update the template.
 	tv, err := q.db.GetTemplateVersionByID(ctx, arg.ID)
@@ -8055,6 +9573,28 @@ func (q *querier) UpdateTemplateVersionByID(ctx context.Context, arg database.Up
 	return q.db.UpdateTemplateVersionByID(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateVersionDeprecationByID(ctx context.Context, arg database.UpdateTemplateVersionDeprecationByIDParams) error {
+	// An actor is allowed to deprecate the template version if they are authorized to update the template.
+	tv, err := q.db.GetTemplateVersionByID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+	var obj rbac.Objecter
+	if !tv.TemplateID.Valid {
+		obj = rbac.ResourceTemplate.InOrg(tv.OrganizationID)
+	} else {
+		tpl, err := q.db.GetTemplateByID(ctx, tv.TemplateID.UUID)
+		if err != nil {
+			return err
+		}
+		obj = tpl
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, obj); err != nil {
+		return err
+	}
+	return q.db.UpdateTemplateVersionDeprecationByID(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg database.UpdateTemplateVersionDescriptionByJobIDParams) error {
 	// An actor is allowed to update the template version description if they are authorized to update the template.
 	tv, err := q.db.GetTemplateVersionByJobID(ctx, arg.JobID)
@@ -8121,6 +9661,73 @@ func (q *querier) UpdateTemplateVersionFlagsByJobID(ctx context.Context, arg dat
 	return q.db.UpdateTemplateVersionFlagsByJobID(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateVersionRolloutStage(ctx context.Context, arg database.UpdateTemplateVersionRolloutStageParams) (database.TemplateVersionRollout, error) {
+	rollout, err := q.db.GetTemplateVersionRolloutByID(ctx, arg.ID)
+	if err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, rollout.TemplateID)
+	if err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.UpdateTemplateVersionRolloutStage(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateVersionRolloutStatus(ctx context.Context, arg database.UpdateTemplateVersionRolloutStatusParams) (database.TemplateVersionRollout, error) {
+	rollout, err := q.db.GetTemplateVersionRolloutByID(ctx, arg.ID)
+	if err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, rollout.TemplateID)
+	if err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.UpdateTemplateVersionRolloutStatus(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateVersionScanByTemplateVersionID(ctx context.Context, arg database.UpdateTemplateVersionScanByTemplateVersionIDParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateTemplateVersionScanByTemplateVersionID(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateWorkspaceRestartLastBatchAt(ctx context.Context, arg database.UpdateTemplateWorkspaceRestartLastBatchAtParams) error {
+	restart, err := q.db.GetTemplateWorkspaceRestartByID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+	template, err := q.db.GetTemplateByID(ctx, restart.TemplateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return err
+	}
+	return q.db.UpdateTemplateWorkspaceRestartLastBatchAt(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateWorkspaceRestartStatus(ctx context.Context, arg database.UpdateTemplateWorkspaceRestartStatusParams) (database.TemplateWorkspaceRestart, error) {
+	restart, err := q.db.GetTemplateWorkspaceRestartByID(ctx, arg.ID)
+	if err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, restart.TemplateID)
+	if err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateWorkspaceRestart{}, err
+	}
+	return q.db.UpdateTemplateWorkspaceRestartStatus(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.TemplateID)
@@ -8238,6 +9845,28 @@ func (q *querier) UpdateUserHashedPassword(ctx context.Context, arg database.Upd
 	return q.db.UpdateUserHashedPassword(ctx, arg)
 }
 
+func (q *querier) UpdateUserImpersonationConsentedAt(ctx context.Context, arg database.UpdateUserImpersonationConsentedAtParams) (database.UserImpersonation, error) {
+	impersonation, err := q.db.GetUserImpersonationByID(ctx, arg.ID)
+	if err != nil {
+		return database.UserImpersonation{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(impersonation.UserID)); err != nil {
+		return database.UserImpersonation{}, err
+	}
+	return q.db.UpdateUserImpersonationConsentedAt(ctx, arg)
+}
+
+func (q *querier) UpdateUserImpersonationIssued(ctx context.Context, arg database.UpdateUserImpersonationIssuedParams) (database.UserImpersonation, error) {
+	impersonation, err := q.db.GetUserImpersonationByID(ctx, arg.ID)
+	if err != nil {
+		return database.UserImpersonation{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionImpersonate, rbac.ResourceUserObject(impersonation.UserID)); err != nil {
+		return database.UserImpersonation{}, err
+	}
+	return q.db.UpdateUserImpersonationIssued(ctx, arg)
+}
+
 func (q *querier) UpdateUserLastSeenAt(ctx context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
 	fetch := func(ctx context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
 		return q.db.GetUserByID(ctx, arg.ID)
@@ -8432,6 +10061,24 @@ func (q *querier) UpdateUserThinkingDisplayMode(ctx context.Context, arg databas
 	return q.db.UpdateUserThinkingDisplayMode(ctx, arg)
 }
 
+func (q *querier) UpdateUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, arg database.UpdateUserWorkspaceSelfRequestedBuildsAllowedParams) (bool, error) {
+	user, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return false, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, user); err != nil {
+		return false, err
+	}
+	return q.db.UpdateUserWorkspaceSelfRequestedBuildsAllowed(ctx, arg)
+}
+
+func (q *querier) UpdateVCSEventMappingLastTriggeredAt(ctx context.Context, arg database.UpdateVCSEventMappingLastTriggeredAtParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateVCSEventMappingLastTriggeredAt(ctx, arg)
+}
+
 func (q *querier) UpdateVolumeResourceMonitor(ctx context.Context, arg database.UpdateVolumeResourceMonitorParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
 		return err
@@ -8463,6 +10110,19 @@ func (q *querier) UpdateWorkspaceACLByID(ctx context.Context, arg database.Updat
 	return fetchAndExec(q.log, q.auth, policy.ActionShare, fetch, q.db.UpdateWorkspaceACLByID)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAgentClockSkewByID(ctx context.Context, arg database.UpdateWorkspaceAgentClockSkewByIDParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpdateWorkspaceAgentClockSkewByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -8589,6 +10249,13 @@ func (q *querier) UpdateWorkspaceAppHealthByID(ctx context.Context, arg database
 	return q.db.UpdateWorkspaceAppHealthByID(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceArchivedAt(ctx context.Context, arg database.UpdateWorkspaceArchivedAtParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceArchivedAtParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceArchivedAt)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
 	workspace, err := q.db.GetWorkspaceByID(ctx, arg.ID)
 	if err != nil {
@@ -8706,6 +10373,13 @@ func (q *querier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context,
 	return q.db.UpdateWorkspaceBuildProvisionerStateByID(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceCloudResourceVerification(ctx context.Context, arg database.UpdateWorkspaceCloudResourceVerificationParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceCloudResourceVerification(ctx, arg)
+}
+
 // Deprecated: Use SoftDeleteWorkspaceByID
 func (q *querier) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	// TODO deleteQ me, placeholder for database.Store
@@ -8716,6 +10390,13 @@ func (q *querier) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.U
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceDeletedByID)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceDormancyHookStatus(ctx context.Context, arg database.UpdateWorkspaceDormancyHookStatusParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.UpdateWorkspaceDormancyHookStatus(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 		w, err := q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -8741,6 +10422,22 @@ func (q *querier) UpdateWorkspaceNextStartAt(ctx context.Context, arg database.U
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceNextStartAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.WorkspaceTable, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.ID)
+	if err != nil {
+		return database.WorkspaceTable{}, err
+	}
+	// Transferring a workspace takes it away from its owner, so only users
+	// that can update every workspace of the organization may do it.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspace.InOrg(w.OrganizationID)); err != nil {
+		return database.WorkspaceTable{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(w.OrganizationID)); err != nil {
+		return database.WorkspaceTable{}, err
+	}
+	return q.db.UpdateWorkspaceOwnerByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -8755,6 +10452,20 @@ func (q *querier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyDeleted)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceReadOnlyAt(ctx context.Context, arg database.UpdateWorkspaceReadOnlyAtParams) (database.WorkspaceTable, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.ID)
+	if err != nil {
+		return database.WorkspaceTable{}, err
+	}
+	// Read-only mode is placed on a workspace during investigations, so it
+	// must not be possible for the owner of the workspace to lift it. Only
+	// users that can update every workspace of the organization may toggle it.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspace.InOrg(w.OrganizationID)); err != nil {
+		return database.WorkspaceTable{}, err
+	}
+	return q.db.UpdateWorkspaceReadOnlyAt(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceTTLParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -9050,6 +10761,13 @@ func (q *querier) UpsertNotificationReportGeneratorLog(ctx context.Context, arg
 	return q.db.UpsertNotificationReportGeneratorLog(ctx, arg)
 }
 
+func (q *querier) UpsertNotificationTemplateWebhook(ctx context.Context, arg database.UpsertNotificationTemplateWebhookParams) (database.NotificationTemplateWebhook, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationTemplate); err != nil {
+		return database.NotificationTemplateWebhook{}, err
+	}
+	return q.db.UpsertNotificationTemplateWebhook(ctx, arg)
+}
+
 func (q *querier) UpsertNotificationsSettings(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
 		return err
@@ -9071,6 +10789,13 @@ func (q *querier) UpsertPrebuildsSettings(ctx context.Context, value string) err
 	return q.db.UpsertPrebuildsSettings(ctx, value)
 }
 
+func (q *querier) UpsertProvisionerCacheSettings(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return err
+	}
+	return q.db.UpsertProvisionerCacheSettings(ctx, value)
+}
+
 func (q *querier) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	res := rbac.ResourceProvisionerDaemon.InOrg(arg.OrganizationID)
 	if arg.Tags[provisionersdk.TagScope] == provisionersdk.ScopeUser {
@@ -9143,6 +10868,17 @@ func (q *querier) UpsertTelemetryItem(ctx context.Context, arg database.UpsertTe
 	return q.db.UpsertTelemetryItem(ctx, arg)
 }
 
+func (q *querier) UpsertTemplateNetworkPolicy(ctx context.Context, arg database.UpsertTemplateNetworkPolicyParams) (database.TemplateNetworkPolicy, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateNetworkPolicy{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateNetworkPolicy{}, err
+	}
+	return q.db.UpsertTemplateNetworkPolicy(ctx, arg)
+}
+
 func (q *querier) UpsertTemplateUsageStats(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -9150,6 +10886,13 @@ func (q *querier) UpsertTemplateUsageStats(ctx context.Context) error {
 	return q.db.UpsertTemplateUsageStats(ctx)
 }
 
+func (q *querier) UpsertTemplateUserPreset(ctx context.Context, arg database.UpsertTemplateUserPresetParams) (database.TemplateUserPreset, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return database.TemplateUserPreset{}, err
+	}
+	return q.db.UpsertTemplateUserPreset(ctx, arg)
+}
+
 func (q *querier) UpsertUserAIBudgetOverride(ctx context.Context, arg database.UpsertUserAIBudgetOverrideParams) (database.UserAIBudgetOverride, error) {
 	// Setting a user's AI budget override affects both the user (their
 	// per-user spend cap) and the group (spend attribution).
@@ -9203,6 +10946,13 @@ func (q *querier) UpsertUserChatPersonalModelOverride(ctx context.Context, arg d
 	return q.db.UpsertUserChatPersonalModelOverride(ctx, arg)
 }
 
+func (q *querier) UpsertUserNotificationScopedPreferences(ctx context.Context, arg database.UpsertUserNotificationScopedPreferencesParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
+		return -1, err
+	}
+	return q.db.UpsertUserNotificationScopedPreferences(ctx, arg)
+}
+
 func (q *querier) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
 		return err
@@ -9224,6 +10974,13 @@ func (q *querier) UpsertWorkspaceAgentContextSnapshot(ctx context.Context, arg d
 	return q.db.UpsertWorkspaceAgentContextSnapshot(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceAgentCrashLoop(ctx context.Context, arg database.UpsertWorkspaceAgentCrashLoopParams) (database.WorkspaceAgentCrashLoop, error) {
+	if err := q.authorizeWorkspaceByAgentID(ctx, arg.AgentID, policy.ActionUpdate); err != nil {
+		return database.WorkspaceAgentCrashLoop{}, err
+	}
+	return q.db.UpsertWorkspaceAgentCrashLoop(ctx, arg)
+}
+
 func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
 	if err != nil {
@@ -9262,6 +11019,125 @@ func (q *querier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg databa
 	return q.db.UpsertWorkspaceAppAuditSession(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceCloudResource(ctx context.Context, arg database.UpsertWorkspaceCloudResourceParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceCloudResource(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceDNSRecord(ctx context.Context, arg database.UpsertWorkspaceDNSRecordParams) (database.WorkspaceDNSRecord, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceDNSRecord{}, err
+	}
+	return q.db.UpsertWorkspaceDNSRecord(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceDormancyExemption(ctx context.Context, arg database.UpsertWorkspaceDormancyExemptionParams) (database.WorkspaceDormancyExemption, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceDormancyExemption{}, err
+	}
+
+	template, err := q.db.GetTemplateByID(ctx, w.TemplateID)
+	if err != nil {
+		return database.WorkspaceDormancyExemption{}, err
+	}
+
+	// Exemptions override the template dormancy policy, so granting them
+	// requires permission to update the template.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.WorkspaceDormancyExemption{}, err
+	}
+
+	return q.db.UpsertWorkspaceDormancyExemption(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceDormancyHook(ctx context.Context, arg database.UpsertWorkspaceDormancyHookParams) (database.WorkspaceDormancyHook, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceDormancyHook{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.WorkspaceDormancyHook{}, err
+	}
+
+	return q.db.UpsertWorkspaceDormancyHook(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceEgressDaily(ctx context.Context, arg database.UpsertWorkspaceEgressDailyParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceEgressDaily(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceLifetimeWarning(ctx context.Context, arg database.UpsertWorkspaceLifetimeWarningParams) error {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return err
+	}
+
+	return q.db.UpsertWorkspaceLifetimeWarning(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceMonthlyCost(ctx context.Context, arg database.UpsertWorkspaceMonthlyCostParams) (database.WorkspaceMonthlyCost, error) {
+	// Imported costs are enforced against deployment-wide budgets, so only
+	// deployment administrators may import them.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return database.WorkspaceMonthlyCost{}, err
+	}
+	return q.db.UpsertWorkspaceMonthlyCost(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceNetworkPolicy(ctx context.Context, arg database.UpsertWorkspaceNetworkPolicyParams) (database.WorkspaceNetworkPolicy, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceNetworkPolicy{}, err
+	}
+
+	template, err := q.db.GetTemplateByID(ctx, w.TemplateID)
+	if err != nil {
+		return database.WorkspaceNetworkPolicy{}, err
+	}
+
+	// Workspace policies override the template policy and workspace owners
+	// must not be able to loosen their own egress restrictions, so managing
+	// them requires permission to update the template.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.WorkspaceNetworkPolicy{}, err
+	}
+
+	return q.db.UpsertWorkspaceNetworkPolicy(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspacePortForwardACL(ctx context.Context, arg database.UpsertWorkspacePortForwardACLParams) (database.WorkspacePortForwardACL, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspacePortForwardACL{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionShare, workspace); err != nil {
+		return database.WorkspacePortForwardACL{}, err
+	}
+	return q.db.UpsertWorkspacePortForwardACL(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceRegionAssignment(ctx context.Context, arg database.UpsertWorkspaceRegionAssignmentParams) (database.WorkspaceRegionAssignment, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceRegionAssignment{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.WorkspaceRegionAssignment{}, err
+	}
+	return q.db.UpsertWorkspaceRegionAssignment(ctx, arg)
+}
+
 func (q *querier) UsageEventExistsByID(ctx context.Context, id string) (bool, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUsageEvent); err != nil {
 		return false, err
@@ -2302,6 +2302,22 @@ func (s *MethodTestSuite) TestProvisionerJob() {
 		dbm.EXPECT().GetProvisionerLogsAfterID(gomock.Any(), arg).Return([]database.ProvisionerJobLog{}, nil).AnyTimes()
 		check.Args(arg).Asserts(ws, policy.ActionRead).Returns([]database.ProvisionerJobLog{})
 	}))
+	s.Run("GetProvisionerJobErrorLogsByJobIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetProvisionerJobErrorLogsByJobIDs(gomock.Any(), ids).Return([]database.ProvisionerJobLog{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetProvisionerJobLogArchiveByJobID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		j := testutil.Fake(s.T(), faker, database.ProvisionerJob{Type: database.ProvisionerJobTypeWorkspaceBuild})
+		build := testutil.Fake(s.T(), faker, database.WorkspaceBuild{JobID: j.ID, WorkspaceID: ws.ID})
+		archive := testutil.Fake(s.T(), faker, database.ProvisionerJobLogArchive{JobID: j.ID})
+		dbm.EXPECT().GetProvisionerJobByID(gomock.Any(), j.ID).Return(j, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceBuildByJobID(gomock.Any(), j.ID).Return(build, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetProvisionerJobLogArchiveByJobID(gomock.Any(), j.ID).Return(archive, nil).AnyTimes()
+		check.Args(j.ID).Asserts(ws, policy.ActionRead).Returns(archive)
+	}))
 	s.Run("Build/GetProvisionerJobByIDWithLock", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		ws := testutil.Fake(s.T(), faker, database.Workspace{})
 		j := testutil.Fake(s.T(), faker, database.ProvisionerJob{Type: database.ProvisionerJobTypeWorkspaceBuild})
@@ -2499,6 +2515,17 @@ func (s *MethodTestSuite) TestOrganization() {
 		dbm.EXPECT().InsertOrganization(gomock.Any(), arg).Return(database.Organization{ID: arg.ID, Name: arg.Name}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceOrganization, policy.ActionCreate)
 	}))
+	s.Run("UpdateOrganizationProvisionerDaemonSettings", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		org := testutil.Fake(s.T(), faker, database.Organization{})
+		arg := database.UpdateOrganizationProvisionerDaemonSettingsParams{
+			ID:                      org.ID,
+			ProvisionerTagNamespace: "acme",
+			ProvisionerDaemonQuota:  5,
+		}
+		dbm.EXPECT().GetOrganizationByID(gomock.Any(), org.ID).Return(org, nil).AnyTimes()
+		dbm.EXPECT().UpdateOrganizationProvisionerDaemonSettings(gomock.Any(), arg).Return(org, nil).AnyTimes()
+		check.Args(arg).Asserts(org, policy.ActionUpdate).Returns(org)
+	}))
 	s.Run("UpdateOrganizationWorkspaceSharingSettings", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		org := testutil.Fake(s.T(), faker, database.Organization{})
 		arg := database.UpdateOrganizationWorkspaceSharingSettingsParams{
@@ -2713,6 +2740,15 @@ func (s *MethodTestSuite) TestTemplate() {
 		dbm.EXPECT().GetTemplateVersionTerraformValues(gomock.Any(), tv.ID).Return(val, nil).AnyTimes()
 		check.Args(tv.ID).Asserts(t, policy.ActionRead)
 	}))
+	s.Run("GetTemplateVersionScanByTemplateVersionID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		t := testutil.Fake(s.T(), faker, database.Template{})
+		tv := testutil.Fake(s.T(), faker, database.TemplateVersion{TemplateID: uuid.NullUUID{UUID: t.ID, Valid: true}})
+		scan := database.TemplateVersionScan{TemplateVersionID: tv.ID, Status: database.TemplateVersionScanStatusCompleted}
+		dbm.EXPECT().GetTemplateVersionByID(gomock.Any(), tv.ID).Return(tv, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), t.ID).Return(t, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateVersionScanByTemplateVersionID(gomock.Any(), tv.ID).Return(scan, nil).AnyTimes()
+		check.Args(tv.ID).Asserts(t, policy.ActionRead).Returns(scan)
+	}))
 	s.Run("HasTemplateVersionsUsingCachedModuleFileInOrg", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.HasTemplateVersionsUsingCachedModuleFileInOrgParams{FileID: uuid.New(), OrganizationID: uuid.New()}
 		dbm.EXPECT().HasTemplateVersionsUsingCachedModuleFileInOrg(gomock.Any(), arg).Return(true, nil).AnyTimes()
@@ -2898,6 +2934,15 @@ func (s *MethodTestSuite) TestTemplate() {
 		dbm.EXPECT().UpdateTemplateVersionByID(gomock.Any(), arg).Return(nil).AnyTimes()
 		check.Args(arg).Asserts(t1, policy.ActionUpdate)
 	}))
+	s.Run("UpdateTemplateVersionDeprecationByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		t1 := testutil.Fake(s.T(), faker, database.Template{})
+		tv := testutil.Fake(s.T(), faker, database.TemplateVersion{TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true}})
+		arg := database.UpdateTemplateVersionDeprecationByIDParams{ID: tv.ID, Deprecated: "foo", UpdatedAt: tv.UpdatedAt}
+		dbm.EXPECT().GetTemplateVersionByID(gomock.Any(), tv.ID).Return(tv, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), t1.ID).Return(t1, nil).AnyTimes()
+		dbm.EXPECT().UpdateTemplateVersionDeprecationByID(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateTemplateVersionDescriptionByJobID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		tv := database.TemplateVersion{ID: uuid.New(), JobID: uuid.New(), TemplateID: uuid.NullUUID{UUID: uuid.New(), Valid: true}}
 		t1 := database.Template{ID: tv.TemplateID.UUID}
@@ -2931,6 +2976,16 @@ func (s *MethodTestSuite) TestTemplate() {
 		dbm.EXPECT().GetUserActivityInsights(gomock.Any(), arg).Return([]database.GetUserActivityInsightsRow{}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights).Returns([]database.GetUserActivityInsightsRow{})
 	}))
+	s.Run("GetWorkspaceEgressInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceEgressInsightsParams{}
+		dbm.EXPECT().GetWorkspaceEgressInsights(gomock.Any(), arg).Return([]database.GetWorkspaceEgressInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights).Returns([]database.GetWorkspaceEgressInsightsRow{})
+	}))
+	s.Run("GetAutostopInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetAutostopInsightsParams{}
+		dbm.EXPECT().GetAutostopInsights(gomock.Any(), arg).Return([]database.GetAutostopInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights).Returns([]database.GetAutostopInsightsRow{})
+	}))
 	s.Run("GetTemplateParameterInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.GetTemplateParameterInsightsParams{}
 		dbm.EXPECT().GetTemplateParameterInsights(gomock.Any(), arg).Return([]database.GetTemplateParameterInsightsRow{}, nil).AnyTimes()
@@ -2951,6 +3006,11 @@ func (s *MethodTestSuite) TestTemplate() {
 		dbm.EXPECT().GetTelemetryTaskEvents(gomock.Any(), arg).Return([]database.GetTelemetryTaskEventsRow{}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceTask.All(), policy.ActionRead)
 	}))
+	s.Run("GetTemplateActiveDeveloperDays", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetTemplateActiveDeveloperDaysParams{}
+		dbm.EXPECT().GetTemplateActiveDeveloperDays(gomock.Any(), arg).Return([]database.GetTemplateActiveDeveloperDaysRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
 	s.Run("GetTemplateAppInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.GetTemplateAppInsightsParams{}
 		dbm.EXPECT().GetTemplateAppInsights(gomock.Any(), arg).Return([]database.GetTemplateAppInsightsRow{}, nil).AnyTimes()
@@ -2977,6 +3037,338 @@ func (s *MethodTestSuite) TestTemplate() {
 		dbm.EXPECT().UpdatePresetsLastInvalidatedAt(gomock.Any(), arg).Return([]database.UpdatePresetsLastInvalidatedAtRow{}, nil).AnyTimes()
 		check.Args(arg).Asserts(t1, policy.ActionUpdate)
 	}))
+	s.Run("GetTemplateNetworkPolicy", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		np := database.TemplateNetworkPolicy{TemplateID: tpl.ID}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateNetworkPolicy(gomock.Any(), tpl.ID).Return(np, nil).AnyTimes()
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead).Returns(np)
+	}))
+	s.Run("UpsertTemplateNetworkPolicy", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.UpsertTemplateNetworkPolicyParams{
+			TemplateID:     tpl.ID,
+			AllowedDomains: []string{"*.github.com"},
+			AllowedCIDRs:   []string{},
+			UpdatedAt:      dbtime.Now(),
+		}
+		np := database.TemplateNetworkPolicy{
+			TemplateID:     arg.TemplateID,
+			AllowedDomains: arg.AllowedDomains,
+			AllowedCIDRs:   arg.AllowedCIDRs,
+			UpdatedAt:      arg.UpdatedAt,
+		}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().UpsertTemplateNetworkPolicy(gomock.Any(), arg).Return(np, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(np)
+	}))
+	s.Run("DeleteTemplateNetworkPolicy", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().DeleteTemplateNetworkPolicy(gomock.Any(), tpl.ID).Return(nil).AnyTimes()
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetTemplatePresetCohortsByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		cohorts := []database.TemplatePresetCohort{{TemplateID: tpl.ID, PresetName: "ML team"}}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplatePresetCohortsByTemplateID(gomock.Any(), tpl.ID).Return(cohorts, nil).AnyTimes()
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead).Returns(cohorts)
+	}))
+	s.Run("GetTemplateHealthReportsByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.GetTemplateHealthReportsByTemplateIDParams{TemplateID: tpl.ID}
+		reports := []database.TemplateHealthReport{{ID: uuid.New(), TemplateID: tpl.ID}}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateHealthReportsByTemplateID(gomock.Any(), arg).Return(reports, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionViewInsights).Returns(reports)
+	}))
+	s.Run("InsertTemplatePresetCohort", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.InsertTemplatePresetCohortParams{
+			TemplateID: tpl.ID,
+			PresetName: "ML team",
+			GroupIds:   []uuid.UUID{uuid.New()},
+			Roles:      []string{},
+			CreatedAt:  dbtime.Now(),
+		}
+		cohort := database.TemplatePresetCohort{
+			TemplateID: arg.TemplateID,
+			PresetName: arg.PresetName,
+			GroupIds:   arg.GroupIds,
+			Roles:      arg.Roles,
+			CreatedAt:  arg.CreatedAt,
+		}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().InsertTemplatePresetCohort(gomock.Any(), arg).Return(cohort, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(cohort)
+	}))
+	s.Run("DeleteTemplatePresetCohortsByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().DeleteTemplatePresetCohortsByTemplateID(gomock.Any(), tpl.ID).Return(nil).AnyTimes()
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertTemplateVersionRollout", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.InsertTemplateVersionRolloutParams{
+			ID:                uuid.New(),
+			TemplateID:        tpl.ID,
+			TemplateVersionID: uuid.New(),
+			PreviousVersionID: tpl.ActiveVersionID,
+			Stages:            []int32{10, 50},
+			SoakDuration:      int64(time.Hour),
+			MaxFailureRate:    0.1,
+			MinBuilds:         5,
+			CreatedBy:         uuid.New(),
+			CreatedAt:         dbtime.Now(),
+		}
+		rollout := database.TemplateVersionRollout{ID: arg.ID, TemplateID: tpl.ID}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().InsertTemplateVersionRollout(gomock.Any(), arg).Return(rollout, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(rollout)
+	}))
+	s.Run("GetTemplateVersionRolloutByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		rollout := database.TemplateVersionRollout{ID: uuid.New(), TemplateID: tpl.ID}
+		dbm.EXPECT().GetTemplateVersionRolloutByID(gomock.Any(), rollout.ID).Return(rollout, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		check.Args(rollout.ID).Asserts(tpl, policy.ActionRead).Returns(rollout)
+	}))
+	s.Run("GetTemplateVersionRolloutsByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		rollouts := []database.TemplateVersionRollout{{ID: uuid.New(), TemplateID: tpl.ID}}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateVersionRolloutsByTemplateID(gomock.Any(), tpl.ID).Return(rollouts, nil).AnyTimes()
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead).Returns(rollouts)
+	}))
+	s.Run("GetActiveTemplateVersionRolloutByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		rollout := database.TemplateVersionRollout{ID: uuid.New(), TemplateID: tpl.ID, Status: database.TemplateVersionRolloutStatusInProgress}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetActiveTemplateVersionRolloutByTemplateID(gomock.Any(), tpl.ID).Return(rollout, nil).AnyTimes()
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead).Returns(rollout)
+	}))
+	s.Run("GetActiveTemplateVersionRollouts", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().GetActiveTemplateVersionRollouts(gomock.Any()).Return([]database.TemplateVersionRollout{}, nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.TemplateVersionRollout{})
+	}))
+	s.Run("GetTemplateVersionRolloutBuildStats", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		tv := testutil.Fake(s.T(), faker, database.TemplateVersion{TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true}})
+		arg := database.GetTemplateVersionRolloutBuildStatsParams{TemplateVersionID: tv.ID, Since: dbtime.Now()}
+		stats := database.GetTemplateVersionRolloutBuildStatsRow{Builds: 4, FailedBuilds: 1}
+		dbm.EXPECT().GetTemplateVersionByID(gomock.Any(), tv.ID).Return(tv, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateVersionRolloutBuildStats(gomock.Any(), arg).Return(stats, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionRead).Returns(stats)
+	}))
+	s.Run("UpdateTemplateVersionRolloutStage", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		rollout := database.TemplateVersionRollout{ID: uuid.New(), TemplateID: tpl.ID}
+		arg := database.UpdateTemplateVersionRolloutStageParams{ID: rollout.ID, CurrentStage: 1, StageStartedAt: dbtime.Now()}
+		dbm.EXPECT().GetTemplateVersionRolloutByID(gomock.Any(), rollout.ID).Return(rollout, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().UpdateTemplateVersionRolloutStage(gomock.Any(), arg).Return(rollout, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(rollout)
+	}))
+	s.Run("UpdateTemplateVersionRolloutStatus", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		rollout := database.TemplateVersionRollout{ID: uuid.New(), TemplateID: tpl.ID}
+		arg := database.UpdateTemplateVersionRolloutStatusParams{ID: rollout.ID, Status: database.TemplateVersionRolloutStatusAborted, UpdatedAt: dbtime.Now()}
+		dbm.EXPECT().GetTemplateVersionRolloutByID(gomock.Any(), rollout.ID).Return(rollout, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().UpdateTemplateVersionRolloutStatus(gomock.Any(), arg).Return(rollout, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(rollout)
+	}))
+	s.Run("InsertTemplateSecret", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.InsertTemplateSecretParams{ID: uuid.New(), TemplateID: tpl.ID, Name: "API_TOKEN", Value: "secret"}
+		secret := database.TemplateSecret{ID: arg.ID, TemplateID: tpl.ID, Name: arg.Name}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().InsertTemplateSecret(gomock.Any(), arg).Return(secret, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(secret)
+	}))
+	s.Run("GetTemplateSecretByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		secret := database.TemplateSecret{ID: uuid.New(), TemplateID: tpl.ID, Name: "API_TOKEN"}
+		dbm.EXPECT().GetTemplateSecretByID(gomock.Any(), secret.ID).Return(secret, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		check.Args(secret.ID).Asserts(tpl, policy.ActionUpdate).Returns(secret)
+	}))
+	s.Run("GetTemplateSecretByTemplateIDAndName", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.GetTemplateSecretByTemplateIDAndNameParams{TemplateID: tpl.ID, Name: "API_TOKEN"}
+		secret := database.TemplateSecret{ID: uuid.New(), TemplateID: tpl.ID, Name: arg.Name}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateSecretByTemplateIDAndName(gomock.Any(), arg).Return(secret, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(secret)
+	}))
+	s.Run("GetTemplateSecretsByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		secrets := []database.TemplateSecret{{ID: uuid.New(), TemplateID: tpl.ID, Name: "API_TOKEN"}}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateSecretsByTemplateID(gomock.Any(), tpl.ID).Return(secrets, nil).AnyTimes()
+		check.Args(tpl.ID).Asserts(rbac.ResourceSystem, policy.ActionRead, tpl, policy.ActionUpdate).FailSystemObjectChecks()
+	}))
+	s.Run("GetTemplateSecrets", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		secrets := []database.TemplateSecret{{ID: uuid.New(), TemplateID: uuid.New(), Name: "API_TOKEN"}}
+		dbm.EXPECT().GetTemplateSecrets(gomock.Any()).Return(secrets, nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(secrets)
+	}))
+	s.Run("UpdateTemplateSecretByTemplateIDAndName", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.UpdateTemplateSecretByTemplateIDAndNameParams{TemplateID: tpl.ID, Name: "API_TOKEN", UpdateValue: true, Value: "new", UpdatedAt: dbtime.Now()}
+		secret := database.TemplateSecret{ID: uuid.New(), TemplateID: tpl.ID, Name: arg.Name}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().UpdateTemplateSecretByTemplateIDAndName(gomock.Any(), arg).Return(secret, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(secret)
+	}))
+	s.Run("UpdateEncryptedTemplateSecret", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		secret := database.TemplateSecret{ID: uuid.New(), TemplateID: uuid.New(), Name: "API_TOKEN"}
+		arg := database.UpdateEncryptedTemplateSecretParams{ID: secret.ID, Value: "encrypted"}
+		dbm.EXPECT().UpdateEncryptedTemplateSecret(gomock.Any(), arg).Return(secret, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns(secret)
+	}))
+	s.Run("DeleteTemplateSecretByTemplateIDAndName", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.DeleteTemplateSecretByTemplateIDAndNameParams{TemplateID: tpl.ID, Name: "API_TOKEN"}
+		secret := database.TemplateSecret{ID: uuid.New(), TemplateID: tpl.ID, Name: arg.Name}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().DeleteTemplateSecretByTemplateIDAndName(gomock.Any(), arg).Return(secret, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(secret)
+	}))
+	s.Run("InsertTemplateWorkspaceRestart", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.InsertTemplateWorkspaceRestartParams{ID: uuid.New(), TemplateID: tpl.ID, TemplateVersionID: tpl.ActiveVersionID, BatchSize: 10, CreatedAt: dbtime.Now()}
+		restart := database.TemplateWorkspaceRestart{ID: arg.ID, TemplateID: tpl.ID}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().InsertTemplateWorkspaceRestart(gomock.Any(), arg).Return(restart, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(restart)
+	}))
+	s.Run("GetTemplateWorkspaceRestartByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		restart := database.TemplateWorkspaceRestart{ID: uuid.New(), TemplateID: tpl.ID}
+		dbm.EXPECT().GetTemplateWorkspaceRestartByID(gomock.Any(), restart.ID).Return(restart, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		check.Args(restart.ID).Asserts(tpl, policy.ActionRead).Returns(restart)
+	}))
+	s.Run("GetTemplateWorkspaceRestartsByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		restarts := []database.TemplateWorkspaceRestart{{ID: uuid.New(), TemplateID: tpl.ID}}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateWorkspaceRestartsByTemplateID(gomock.Any(), tpl.ID).Return(restarts, nil).AnyTimes()
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionRead).Returns(restarts)
+	}))
+	s.Run("GetInProgressTemplateWorkspaceRestarts", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().GetInProgressTemplateWorkspaceRestarts(gomock.Any()).Return([]database.TemplateWorkspaceRestart{}, nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.TemplateWorkspaceRestart{})
+	}))
+	s.Run("GetTemplateWorkspaceRestartCandidates", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.GetTemplateWorkspaceRestartCandidatesParams{RestartID: uuid.New(), TemplateID: tpl.ID, TemplateVersionID: tpl.ActiveVersionID}
+		candidates := []database.GetTemplateWorkspaceRestartCandidatesRow{{ID: uuid.New(), OwnerID: uuid.New()}}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateWorkspaceRestartCandidates(gomock.Any(), arg).Return(candidates, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionRead).Returns(candidates)
+	}))
+	s.Run("GetTemplateWorkspaceRestartBuildStats", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		restart := database.TemplateWorkspaceRestart{ID: uuid.New(), TemplateID: tpl.ID}
+		arg := database.GetTemplateWorkspaceRestartBuildStatsParams{RestartID: restart.ID, Since: dbtime.Now()}
+		stats := database.GetTemplateWorkspaceRestartBuildStatsRow{Restarted: 4, Succeeded: 3, Failed: 1}
+		dbm.EXPECT().GetTemplateWorkspaceRestartByID(gomock.Any(), restart.ID).Return(restart, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateWorkspaceRestartBuildStats(gomock.Any(), arg).Return(stats, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionRead).Returns(stats)
+	}))
+	s.Run("InsertTemplateWorkspaceRestartBuild", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		restart := database.TemplateWorkspaceRestart{ID: uuid.New(), TemplateID: tpl.ID}
+		arg := database.InsertTemplateWorkspaceRestartBuildParams{RestartID: restart.ID, WorkspaceID: uuid.New(), CreatedAt: dbtime.Now()}
+		dbm.EXPECT().GetTemplateWorkspaceRestartByID(gomock.Any(), restart.ID).Return(restart, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().InsertTemplateWorkspaceRestartBuild(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateWorkspaceRestartLastBatchAt", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		restart := database.TemplateWorkspaceRestart{ID: uuid.New(), TemplateID: tpl.ID}
+		arg := database.UpdateTemplateWorkspaceRestartLastBatchAtParams{ID: restart.ID, LastBatchAt: sql.NullTime{Time: dbtime.Now(), Valid: true}}
+		dbm.EXPECT().GetTemplateWorkspaceRestartByID(gomock.Any(), restart.ID).Return(restart, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().UpdateTemplateWorkspaceRestartLastBatchAt(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateWorkspaceRestartStatus", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		restart := database.TemplateWorkspaceRestart{ID: uuid.New(), TemplateID: tpl.ID}
+		arg := database.UpdateTemplateWorkspaceRestartStatusParams{ID: restart.ID, Status: database.TemplateWorkspaceRestartStatusAborted, UpdatedAt: dbtime.Now()}
+		dbm.EXPECT().GetTemplateWorkspaceRestartByID(gomock.Any(), restart.ID).Return(restart, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().UpdateTemplateWorkspaceRestartStatus(gomock.Any(), arg).Return(restart, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(restart)
+	}))
+	s.Run("ResumeTemplateWorkspaceRestart", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		restart := database.TemplateWorkspaceRestart{ID: uuid.New(), TemplateID: tpl.ID}
+		arg := database.ResumeTemplateWorkspaceRestartParams{ID: restart.ID, UpdatedAt: dbtime.Now()}
+		dbm.EXPECT().GetTemplateWorkspaceRestartByID(gomock.Any(), restart.ID).Return(restart, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().ResumeTemplateWorkspaceRestart(gomock.Any(), arg).Return(restart, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(restart)
+	}))
+	s.Run("InsertVCSEventMapping", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.InsertVCSEventMappingParams{ID: uuid.New(), TemplateID: tpl.ID, Name: "main", Repository: "coder/coder", Action: database.VCSEventActionRefreshPrebuilds, SigningSecret: "secret"}
+		mapping := database.VCSEventMapping{ID: arg.ID, TemplateID: tpl.ID, Name: arg.Name}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().InsertVCSEventMapping(gomock.Any(), arg).Return(mapping, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(mapping)
+	}))
+	s.Run("GetVCSEventMappingByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		mapping := database.VCSEventMapping{ID: uuid.New(), TemplateID: tpl.ID, Name: "main"}
+		dbm.EXPECT().GetVCSEventMappingByID(gomock.Any(), mapping.ID).Return(mapping, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		check.Args(mapping.ID).Asserts(tpl, policy.ActionUpdate).Returns(mapping)
+	}))
+	s.Run("GetVCSEventMappingsByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		mappings := []database.VCSEventMapping{{ID: uuid.New(), TemplateID: tpl.ID, Name: "main"}}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().GetVCSEventMappingsByTemplateID(gomock.Any(), tpl.ID).Return(mappings, nil).AnyTimes()
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionUpdate).Returns(mappings)
+	}))
+	s.Run("GetVCSEventMappingsByRepository", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		mappings := []database.VCSEventMapping{{ID: uuid.New(), TemplateID: uuid.New(), Name: "main", Repository: "coder/coder"}}
+		dbm.EXPECT().GetVCSEventMappingsByRepository(gomock.Any(), "coder/coder").Return(mappings, nil).AnyTimes()
+		check.Args("coder/coder").Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(mappings)
+	}))
+	s.Run("GetVCSEventMappings", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		mappings := []database.VCSEventMapping{{ID: uuid.New(), TemplateID: uuid.New(), Name: "main", Repository: "coder/coder"}}
+		dbm.EXPECT().GetVCSEventMappings(gomock.Any()).Return(mappings, nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(mappings)
+	}))
+	s.Run("UpdateEncryptedVCSEventMapping", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		mapping := database.VCSEventMapping{ID: uuid.New(), TemplateID: uuid.New(), Name: "main"}
+		arg := database.UpdateEncryptedVCSEventMappingParams{ID: mapping.ID, SigningSecret: "encrypted"}
+		dbm.EXPECT().UpdateEncryptedVCSEventMapping(gomock.Any(), arg).Return(mapping, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns(mapping)
+	}))
+	s.Run("UpdateVCSEventMappingLastTriggeredAt", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpdateVCSEventMappingLastTriggeredAtParams{ID: uuid.New(), LastTriggeredAt: sql.NullTime{Time: dbtime.Now(), Valid: true}}
+		dbm.EXPECT().UpdateVCSEventMappingLastTriggeredAt(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteVCSEventMappingByTemplateIDAndID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.DeleteVCSEventMappingByTemplateIDAndIDParams{TemplateID: tpl.ID, ID: uuid.New()}
+		mapping := database.VCSEventMapping{ID: arg.ID, TemplateID: tpl.ID, Name: "main"}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().DeleteVCSEventMappingByTemplateIDAndID(gomock.Any(), arg).Return(mapping, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(mapping)
+	}))
 }
 
 func (s *MethodTestSuite) TestUser() {
@@ -3117,6 +3509,27 @@ func (s *MethodTestSuite) TestUser() {
 		dbm.EXPECT().GetUserAppearanceSettings(gomock.Any(), u.ID).Return(settings, nil).AnyTimes()
 		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns(settings)
 	}))
+	s.Run("GetUserSchedulePause", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		u := testutil.Fake(s.T(), faker, database.User{})
+		pause := database.UserSchedulePause{UserID: u.ID, PausedUntil: dbtime.Now().Add(time.Hour)}
+		dbm.EXPECT().GetUserByID(gomock.Any(), u.ID).Return(u, nil).AnyTimes()
+		dbm.EXPECT().GetUserSchedulePause(gomock.Any(), u.ID).Return(pause, nil).AnyTimes()
+		check.Args(u.ID).Asserts(u, policy.ActionRead).Returns(pause)
+	}))
+	s.Run("UpsertUserSchedulePause", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		u := testutil.Fake(s.T(), faker, database.User{})
+		arg := database.UpsertUserSchedulePauseParams{UserID: u.ID, PausedUntil: dbtime.Now().Add(time.Hour)}
+		pause := database.UserSchedulePause{UserID: u.ID, PausedUntil: arg.PausedUntil}
+		dbm.EXPECT().GetUserByID(gomock.Any(), u.ID).Return(u, nil).AnyTimes()
+		dbm.EXPECT().UpsertUserSchedulePause(gomock.Any(), arg).Return(pause, nil).AnyTimes()
+		check.Args(arg).Asserts(u, policy.ActionUpdatePersonal).Returns(pause)
+	}))
+	s.Run("DeleteUserSchedulePause", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		u := testutil.Fake(s.T(), faker, database.User{})
+		dbm.EXPECT().GetUserByID(gomock.Any(), u.ID).Return(u, nil).AnyTimes()
+		dbm.EXPECT().DeleteUserSchedulePause(gomock.Any(), u.ID).Return(nil).AnyTimes()
+		check.Args(u.ID).Asserts(u, policy.ActionUpdatePersonal).Returns()
+	}))
 	s.Run("UpdateUserThemePreference", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		u := testutil.Fake(s.T(), faker, database.User{})
 		uc := database.UserConfig{UserID: u.ID, Key: "theme_preference", Value: "dark"}
@@ -3163,6 +3576,19 @@ func (s *MethodTestSuite) TestUser() {
 		dbm.EXPECT().GetUserTaskNotificationAlertDismissed(gomock.Any(), u.ID).Return(false, nil).AnyTimes()
 		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns(false)
 	}))
+	s.Run("GetUserWorkspaceSelfRequestedBuildsAllowed", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		u := testutil.Fake(s.T(), faker, database.User{})
+		dbm.EXPECT().GetUserByID(gomock.Any(), u.ID).Return(u, nil).AnyTimes()
+		dbm.EXPECT().GetUserWorkspaceSelfRequestedBuildsAllowed(gomock.Any(), u.ID).Return(true, nil).AnyTimes()
+		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns(true)
+	}))
+	s.Run("GetLatestUserWorkspaceReportByUserID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		u := testutil.Fake(s.T(), faker, database.User{})
+		report := database.UserWorkspaceReport{ID: uuid.New(), UserID: u.ID}
+		dbm.EXPECT().GetUserByID(gomock.Any(), u.ID).Return(u, nil).AnyTimes()
+		dbm.EXPECT().GetLatestUserWorkspaceReportByUserID(gomock.Any(), u.ID).Return(report, nil).AnyTimes()
+		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns(report)
+	}))
 	s.Run("GetUserChatCustomPrompt", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		u := testutil.Fake(s.T(), faker, database.User{})
 		dbm.EXPECT().GetUserByID(gomock.Any(), u.ID).Return(u, nil).AnyTimes()
@@ -3348,6 +3774,13 @@ func (s *MethodTestSuite) TestUser() {
 		dbm.EXPECT().UpdateUserTaskNotificationAlertDismissed(gomock.Any(), arg).Return(false, nil).AnyTimes()
 		check.Args(arg).Asserts(user, policy.ActionUpdatePersonal).Returns(userConfigValue)
 	}))
+	s.Run("UpdateUserWorkspaceSelfRequestedBuildsAllowed", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		user := testutil.Fake(s.T(), faker, database.User{})
+		arg := database.UpdateUserWorkspaceSelfRequestedBuildsAllowedParams{UserID: user.ID, WorkspaceSelfRequestedBuildsAllowed: true}
+		dbm.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil).AnyTimes()
+		dbm.EXPECT().UpdateUserWorkspaceSelfRequestedBuildsAllowed(gomock.Any(), arg).Return(true, nil).AnyTimes()
+		check.Args(arg).Asserts(user, policy.ActionUpdatePersonal).Returns(true)
+	}))
 	s.Run("UpdateUserStatus", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		u := testutil.Fake(s.T(), faker, database.User{})
 		arg := database.UpdateUserStatusParams{ID: u.ID, Status: u.Status, UpdatedAt: u.UpdatedAt}
@@ -3756,6 +4189,175 @@ func (s *MethodTestSuite) TestWorkspace() {
 		dbm.EXPECT().BatchUpdateWorkspaceAgentMetadata(gomock.Any(), arg).Return(nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceWorkspace.All(), policy.ActionUpdate).Returns()
 	}))
+	s.Run("GetWorkspaceAgentMetadataHistory", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		arg := database.GetWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: agt.ID,
+			Key:              "test",
+		}
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceAgentMetadataHistory(gomock.Any(), arg).Return([]database.GetWorkspaceAgentMetadataHistoryRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionRead).Returns([]database.GetWorkspaceAgentMetadataHistoryRow{})
+	}))
+	s.Run("InsertWorkspaceAgentMetadataHistory", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		arg := database.InsertWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: []uuid.UUID{agt.ID},
+			Key:              []string{"key1"},
+			Value:            []string{"value1"},
+			Error:            []string{""},
+			CollectedAt:      []time.Time{dbtime.Now()},
+		}
+		dbm.EXPECT().InsertWorkspaceAgentMetadataHistory(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceWorkspace.All(), policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceAgentBootstrapProgressByAgentID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceAgentBootstrapProgressByAgentID(gomock.Any(), agt.ID).Return([]database.WorkspaceAgentBootstrapProgress{}, nil).AnyTimes()
+		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns([]database.WorkspaceAgentBootstrapProgress{})
+	}))
+	s.Run("InsertWorkspaceAgentBootstrapProgress", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		arg := database.InsertWorkspaceAgentBootstrapProgressParams{
+			ID:               uuid.New(),
+			WorkspaceAgentID: agt.ID,
+			CreatedAt:        dbtime.Now(),
+			Name:             "cloning repo",
+			Percent:          sql.NullInt32{Int32: 40, Valid: true},
+		}
+		row := database.WorkspaceAgentBootstrapProgress{
+			ID:               arg.ID,
+			WorkspaceAgentID: arg.WorkspaceAgentID,
+			CreatedAt:        arg.CreatedAt,
+			Name:             arg.Name,
+			Percent:          arg.Percent,
+		}
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().InsertWorkspaceAgentBootstrapProgress(gomock.Any(), arg).Return(row, nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionUpdate).Returns(row)
+	}))
+	s.Run("InsertWorkspaceAgentServiceTransition", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		arg := database.InsertWorkspaceAgentServiceTransitionParams{
+			ID:               uuid.New(),
+			WorkspaceAgentID: agt.ID,
+			ServiceName:      "dev-server",
+			CreatedAt:        dbtime.Now(),
+			Status:           database.WorkspaceAgentServiceStatusRunning,
+		}
+		row := database.WorkspaceAgentServiceTransition{
+			ID:               arg.ID,
+			WorkspaceAgentID: arg.WorkspaceAgentID,
+			ServiceName:      arg.ServiceName,
+			CreatedAt:        arg.CreatedAt,
+			Status:           arg.Status,
+		}
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().InsertWorkspaceAgentServiceTransition(gomock.Any(), arg).Return(row, nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionUpdate).Returns(row)
+	}))
+	s.Run("GetWorkspaceNetworkPolicy", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		np := database.WorkspaceNetworkPolicy{WorkspaceID: w.ID}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceNetworkPolicy(gomock.Any(), w.ID).Return(np, nil).AnyTimes()
+		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns(np)
+	}))
+	s.Run("UpsertWorkspaceNetworkPolicy", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		w := testutil.Fake(s.T(), faker, database.Workspace{TemplateID: tpl.ID})
+		arg := database.UpsertWorkspaceNetworkPolicyParams{
+			WorkspaceID:    w.ID,
+			AllowedDomains: []string{"github.com"},
+			AllowedCIDRs:   []string{"10.0.0.0/8"},
+			UpdatedAt:      dbtime.Now(),
+		}
+		np := database.WorkspaceNetworkPolicy{
+			WorkspaceID:    arg.WorkspaceID,
+			AllowedDomains: arg.AllowedDomains,
+			AllowedCIDRs:   arg.AllowedCIDRs,
+			UpdatedAt:      arg.UpdatedAt,
+		}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().UpsertWorkspaceNetworkPolicy(gomock.Any(), arg).Return(np, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(np)
+	}))
+	s.Run("DeleteWorkspaceNetworkPolicy", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		w := testutil.Fake(s.T(), faker, database.Workspace{TemplateID: tpl.ID})
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().DeleteWorkspaceNetworkPolicy(gomock.Any(), w.ID).Return(nil).AnyTimes()
+		check.Args(w.ID).Asserts(tpl, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspacePortForwardACL", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.GetWorkspacePortForwardACLParams{WorkspaceID: w.ID, UserID: uuid.New()}
+		pfa := database.WorkspacePortForwardACL{WorkspaceID: w.ID, UserID: arg.UserID, Ports: []int32{8080}}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspacePortForwardACL(gomock.Any(), arg).Return(pfa, nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionRead).Returns(pfa)
+	}))
+	s.Run("GetWorkspacePortForwardACLsByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		pfas := []database.WorkspacePortForwardACL{{WorkspaceID: w.ID, UserID: uuid.New(), Ports: []int32{8080}}}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspacePortForwardACLsByWorkspaceID(gomock.Any(), w.ID).Return(pfas, nil).AnyTimes()
+		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns(pfas)
+	}))
+	s.Run("UpsertWorkspacePortForwardACL", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.UpsertWorkspacePortForwardACLParams{
+			WorkspaceID: w.ID,
+			UserID:      uuid.New(),
+			Ports:       []int32{8080},
+			CreatedAt:   dbtime.Now(),
+		}
+		pfa := database.WorkspacePortForwardACL{
+			WorkspaceID: arg.WorkspaceID,
+			UserID:      arg.UserID,
+			Ports:       arg.Ports,
+			CreatedAt:   arg.CreatedAt,
+			UpdatedAt:   arg.CreatedAt,
+		}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().UpsertWorkspacePortForwardACL(gomock.Any(), arg).Return(pfa, nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionShare).Returns(pfa)
+	}))
+	s.Run("DeleteWorkspacePortForwardACL", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.DeleteWorkspacePortForwardACLParams{WorkspaceID: w.ID, UserID: uuid.New()}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().DeleteWorkspacePortForwardACL(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionShare).Returns()
+	}))
+	s.Run("DeleteWorkspacePortForwardACLsByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().DeleteWorkspacePortForwardACLsByWorkspaceID(gomock.Any(), w.ID).Return(nil).AnyTimes()
+		check.Args(w.ID).Asserts(w, policy.ActionShare).Returns()
+	}))
+	s.Run("InsertWorkspaceAgentNetworkPolicyViolations", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		arg := database.InsertWorkspaceAgentNetworkPolicyViolationsParams{
+			ID:               []uuid.UUID{uuid.New()},
+			WorkspaceAgentID: agt.ID,
+			CreatedAt:        dbtime.Now(),
+			Destination:      []string{"example.com"},
+			Port:             []int32{443},
+			Count:            []int32{1},
+		}
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().InsertWorkspaceAgentNetworkPolicyViolations(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionUpdate).Returns()
+	}))
 	s.Run("GetWorkspaceAgentsByInstanceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		w := testutil.Fake(s.T(), faker, database.Workspace{})
 		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
@@ -3788,6 +4390,14 @@ func (s *MethodTestSuite) TestWorkspace() {
 		dbm.EXPECT().UpdateWorkspaceAgentLifecycleStateByID(gomock.Any(), arg).Return(nil).AnyTimes()
 		check.Args(arg).Asserts(w, policy.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceAgentClockSkewByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		arg := database.UpdateWorkspaceAgentClockSkewByIDParams{ID: agt.ID, ClockSkewMs: sql.NullInt64{Int64: 1500, Valid: true}}
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().UpdateWorkspaceAgentClockSkewByID(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAgentMetadata", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		w := testutil.Fake(s.T(), faker, database.Workspace{})
 		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
@@ -3909,6 +4519,30 @@ func (s *MethodTestSuite) TestWorkspace() {
 		dbm.EXPECT().GetWorkspaceBuildParameters(gomock.Any(), build.ID).Return([]database.WorkspaceBuildParameter{p1, p2}, nil).AnyTimes()
 		check.Args(build.ID).Asserts(ws, policy.ActionRead).Returns([]database.WorkspaceBuildParameter{p1, p2})
 	}))
+	s.Run("GetSensitiveWorkspaceBuildParameters", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		p := testutil.Fake(s.T(), faker, database.WorkspaceBuildParameter{Sensitive: true})
+		dbm.EXPECT().GetSensitiveWorkspaceBuildParameters(gomock.Any()).Return([]database.WorkspaceBuildParameter{p}, nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.WorkspaceBuildParameter{p})
+	}))
+	s.Run("UpdateEncryptedWorkspaceBuildParameter", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpdateEncryptedWorkspaceBuildParameterParams{WorkspaceBuildID: uuid.New(), Name: "password", Value: "encrypted"}
+		dbm.EXPECT().UpdateEncryptedWorkspaceBuildParameter(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceBuildInputsByBuildID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		build := testutil.Fake(s.T(), faker, database.WorkspaceBuild{WorkspaceID: ws.ID})
+		inputs := database.WorkspaceBuildInput{WorkspaceBuildID: build.ID, Inputs: json.RawMessage(`{}`)}
+		dbm.EXPECT().GetWorkspaceBuildByID(gomock.Any(), build.ID).Return(build, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceBuildInputsByBuildID(gomock.Any(), build.ID).Return(inputs, nil).AnyTimes()
+		check.Args(build.ID).Asserts(ws, policy.ActionRead).Returns(inputs)
+	}))
+	s.Run("InsertWorkspaceBuildInputs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.InsertWorkspaceBuildInputsParams{WorkspaceBuildID: uuid.New(), Inputs: json.RawMessage(`{}`)}
+		dbm.EXPECT().InsertWorkspaceBuildInputs(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate).Returns()
+	}))
 	s.Run("GetWorkspaceBuildsByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		ws := testutil.Fake(s.T(), faker, database.Workspace{})
 		b1 := testutil.Fake(s.T(), faker, database.WorkspaceBuild{})
@@ -4266,6 +4900,25 @@ func (s *MethodTestSuite) TestWorkspace() {
 		dbm.EXPECT().InsertWorkspaceBuildParameters(gomock.Any(), arg).Return(nil).AnyTimes()
 		check.Args(arg).Asserts(w, policy.ActionWorkspaceStart)
 	}))
+	s.Run("Start/InsertWorkspaceBuildParameterChanges", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		b := testutil.Fake(s.T(), faker, database.WorkspaceBuild{
+			WorkspaceID: w.ID,
+			Transition:  database.WorkspaceTransitionStart,
+		})
+		arg := database.InsertWorkspaceBuildParameterChangesParams{
+			WorkspaceBuildID: b.ID,
+			Name:             []string{"region"},
+			Kind:             []database.WorkspaceBuildParameterChangeKind{database.WorkspaceBuildParameterChangeKindModified},
+			PreviousValue:    []string{"us-east"},
+			NewValue:         []string{"eu-west"},
+			Redacted:         []bool{false},
+		}
+		dbm.EXPECT().GetWorkspaceBuildByID(gomock.Any(), b.ID).Return(b, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().InsertWorkspaceBuildParameterChanges(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionWorkspaceStart)
+	}))
 	s.Run("Stop/InsertWorkspaceBuildParameters", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		w := testutil.Fake(s.T(), faker, database.Workspace{})
 		b := testutil.Fake(s.T(), faker, database.WorkspaceBuild{
@@ -4298,6 +4951,27 @@ func (s *MethodTestSuite) TestWorkspace() {
 		dbm.EXPECT().InsertWorkspaceBuildParameters(gomock.Any(), arg).Return(nil).AnyTimes()
 		check.Args(arg).Asserts(w, policy.ActionDelete)
 	}))
+	s.Run("InsertWorkspaceBuildAnnotation", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		b := testutil.Fake(s.T(), faker, database.WorkspaceBuild{WorkspaceID: w.ID})
+		a := testutil.Fake(s.T(), faker, database.WorkspaceBuildAnnotation{WorkspaceBuildID: b.ID})
+		arg := database.InsertWorkspaceBuildAnnotationParams{
+			ID:               a.ID,
+			WorkspaceBuildID: b.ID,
+			UserID:           a.UserID,
+			Note:             a.Note,
+			CreatedAt:        a.CreatedAt,
+		}
+		dbm.EXPECT().GetWorkspaceBuildByID(gomock.Any(), b.ID).Return(b, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().InsertWorkspaceBuildAnnotation(gomock.Any(), arg).Return(a, nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionUpdate).Returns(a)
+	}))
+	s.Run("GetWorkspaceBuildAnnotationsByBuildIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetWorkspaceBuildAnnotationsByBuildIDs(gomock.Any(), ids).Return([]database.GetWorkspaceBuildAnnotationsByBuildIDsRow{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
 	s.Run("UpdateWorkspace", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		w := testutil.Fake(s.T(), faker, database.Workspace{})
 		expected := testutil.Fake(s.T(), faker, database.WorkspaceTable{ID: w.ID})
@@ -4393,12 +5067,42 @@ func (s *MethodTestSuite) TestWorkspace() {
 			UpdatedAt:        b.UpdatedAt,
 		}).Asserts(w, policy.ActionUpdate)
 	}))
-	s.Run("SoftDeleteWorkspaceByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+	s.Run("SoftDeleteWorkspaceByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		w.Deleted = true
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().UpdateWorkspaceDeletedByID(gomock.Any(), database.UpdateWorkspaceDeletedByIDParams{ID: w.ID, Deleted: true}).Return(nil).AnyTimes()
+		check.Args(w.ID).Asserts(w, policy.ActionDelete).Returns()
+	}))
+	s.Run("UpdateWorkspaceArchivedAt", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.UpdateWorkspaceArchivedAtParams{ID: w.ID, ArchivedAt: sql.NullTime{Valid: true, Time: dbtime.Now()}}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().UpdateWorkspaceArchivedAt(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionUpdate).Returns()
+	}))
+	s.Run("UpdateWorkspaceReadOnlyAt", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.UpdateWorkspaceReadOnlyAtParams{ID: w.ID, ReadOnlyAt: sql.NullTime{Valid: true, Time: dbtime.Now()}}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().UpdateWorkspaceReadOnlyAt(gomock.Any(), arg).Return(w.WorkspaceTable(), nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceWorkspace.InOrg(w.OrganizationID), policy.ActionUpdate).Returns(w.WorkspaceTable())
+	}))
+	s.Run("UpdateWorkspaceOwnerByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		w := testutil.Fake(s.T(), faker, database.Workspace{})
-		w.Deleted = true
+		arg := database.UpdateWorkspaceOwnerByIDParams{ID: w.ID, OwnerID: uuid.New(), Name: w.Name, UpdatedAt: dbtime.Now()}
 		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
-		dbm.EXPECT().UpdateWorkspaceDeletedByID(gomock.Any(), database.UpdateWorkspaceDeletedByIDParams{ID: w.ID, Deleted: true}).Return(nil).AnyTimes()
-		check.Args(w.ID).Asserts(w, policy.ActionDelete).Returns()
+		dbm.EXPECT().UpdateWorkspaceOwnerByID(gomock.Any(), arg).Return(w.WorkspaceTable(), nil).AnyTimes()
+		check.Args(arg).Asserts(
+			rbac.ResourceWorkspace.InOrg(w.OrganizationID), policy.ActionUpdate,
+			rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(w.OrganizationID), policy.ActionCreate,
+		).Returns(w.WorkspaceTable())
+	}))
+	s.Run("GetOrphanedWorkspaces", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		row := database.GetOrphanedWorkspacesRow{WorkspaceTable: w.WorkspaceTable(), OwnerDeleted: true}
+		dbm.EXPECT().GetOrphanedWorkspaces(gomock.Any()).Return([]database.GetOrphanedWorkspacesRow{row}, nil).AnyTimes()
+		check.Args().Asserts(row, policy.ActionRead).Returns([]database.GetOrphanedWorkspacesRow{row})
 	}))
 	s.Run("UpdateWorkspaceDeletedByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		w := testutil.Fake(s.T(), faker, database.Workspace{Deleted: true})
@@ -4451,7 +5155,7 @@ func (s *MethodTestSuite) TestWorkspace() {
 		arg := database.ActivityBumpWorkspaceParams{WorkspaceID: w.ID}
 		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
 		dbm.EXPECT().ActivityBumpWorkspace(gomock.Any(), arg).Return(nil).AnyTimes()
-		check.Args(arg).Asserts(w, policy.ActionUpdate).Returns()
+		check.Args(arg).Asserts(w, policy.ActionWorkspaceExtend).Returns()
 	}))
 	s.Run("FavoriteWorkspace", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		w := testutil.Fake(s.T(), faker, database.Workspace{})
@@ -4536,6 +5240,114 @@ func (s *MethodTestSuite) TestWorkspacePortSharing() {
 		dbm.EXPECT().ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(gomock.Any(), tpl.ID).Return(nil).AnyTimes()
 		check.Args(tpl.ID).Asserts(tpl, policy.ActionUpdate).Returns()
 	}))
+	s.Run("GetWorkspaceDormancyExemptionByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		ex := testutil.Fake(s.T(), faker, database.WorkspaceDormancyExemption{WorkspaceID: ws.ID})
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceDormancyExemptionByWorkspaceID(gomock.Any(), ws.ID).Return(ex, nil).AnyTimes()
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead).Returns(ex)
+	}))
+	s.Run("UpsertWorkspaceDormancyExemption", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		ws := testutil.Fake(s.T(), faker, database.Workspace{TemplateID: tpl.ID})
+		ex := testutil.Fake(s.T(), faker, database.WorkspaceDormancyExemption{WorkspaceID: ws.ID})
+		arg := database.UpsertWorkspaceDormancyExemptionParams{
+			WorkspaceID: ws.ID,
+			Reason:      ex.Reason,
+			ExpiresAt:   ex.ExpiresAt,
+			ApprovedBy:  ex.ApprovedBy,
+			CreatedAt:   ex.CreatedAt,
+		}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().UpsertWorkspaceDormancyExemption(gomock.Any(), arg).Return(ex, nil).AnyTimes()
+		check.Args(arg).Asserts(tpl, policy.ActionUpdate).Returns(ex)
+	}))
+	s.Run("DeleteWorkspaceDormancyExemption", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		tpl := testutil.Fake(s.T(), faker, database.Template{})
+		ws := testutil.Fake(s.T(), faker, database.Workspace{TemplateID: tpl.ID})
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), tpl.ID).Return(tpl, nil).AnyTimes()
+		dbm.EXPECT().DeleteWorkspaceDormancyExemption(gomock.Any(), ws.ID).Return(nil).AnyTimes()
+		check.Args(ws.ID).Asserts(tpl, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceDormancyHookByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		hook := testutil.Fake(s.T(), faker, database.WorkspaceDormancyHook{WorkspaceID: ws.ID})
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceDormancyHookByWorkspaceID(gomock.Any(), ws.ID).Return(hook, nil).AnyTimes()
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead).Returns(hook)
+	}))
+	s.Run("UpsertWorkspaceDormancyHook", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		hook := testutil.Fake(s.T(), faker, database.WorkspaceDormancyHook{WorkspaceID: ws.ID})
+		arg := database.UpsertWorkspaceDormancyHookParams{WorkspaceID: ws.ID, Event: database.WorkspaceDormancyHookEventDormant, Now: dbtime.Now()}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().UpsertWorkspaceDormancyHook(gomock.Any(), arg).Return(hook, nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionUpdate).Returns(hook)
+	}))
+	s.Run("GetWorkspaceLifetimeWarningByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		warning := testutil.Fake(s.T(), faker, database.WorkspaceLifetimeWarning{WorkspaceID: ws.ID})
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceLifetimeWarningByWorkspaceID(gomock.Any(), ws.ID).Return(warning, nil).AnyTimes()
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead).Returns(warning)
+	}))
+	s.Run("UpsertWorkspaceLifetimeWarning", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.UpsertWorkspaceLifetimeWarningParams{WorkspaceID: ws.ID, ExpiresAt: dbtime.Now(), DaysBefore: 7, SentAt: dbtime.Now()}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().UpsertWorkspaceLifetimeWarning(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceMonthlyCost", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		cost := testutil.Fake(s.T(), faker, database.WorkspaceMonthlyCost{WorkspaceID: ws.ID})
+		arg := database.GetWorkspaceMonthlyCostParams{WorkspaceID: ws.ID, Month: cost.Month}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceMonthlyCost(gomock.Any(), arg).Return(cost, nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionRead).Returns(cost)
+	}))
+	s.Run("UpsertWorkspaceMonthlyCost", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpsertWorkspaceMonthlyCostParams{WorkspaceID: uuid.New(), Cost: 42}
+		dbm.EXPECT().UpsertWorkspaceMonthlyCost(gomock.Any(), arg).Return(database.WorkspaceMonthlyCost{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
+	}))
+	s.Run("InsertWorkspaceLease", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		lease := testutil.Fake(s.T(), faker, database.WorkspaceLease{WorkspaceID: ws.ID})
+		arg := database.InsertWorkspaceLeaseParams{ID: lease.ID, WorkspaceID: ws.ID}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().InsertWorkspaceLease(gomock.Any(), arg).Return(lease, nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionUpdate).Returns(lease)
+	}))
+	s.Run("GetWorkspaceLeaseByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		lease := testutil.Fake(s.T(), faker, database.WorkspaceLease{WorkspaceID: ws.ID})
+		dbm.EXPECT().GetWorkspaceLeaseByID(gomock.Any(), lease.ID).Return(lease, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		check.Args(lease.ID).Asserts(ws, policy.ActionRead).Returns(lease)
+	}))
+	s.Run("ReleaseWorkspaceLease", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		lease := testutil.Fake(s.T(), faker, database.WorkspaceLease{WorkspaceID: ws.ID})
+		arg := database.ReleaseWorkspaceLeaseParams{ID: lease.ID, ReleasedAt: sql.NullTime{Time: dbtime.Now(), Valid: true}}
+		dbm.EXPECT().GetWorkspaceLeaseByID(gomock.Any(), lease.ID).Return(lease, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().ReleaseWorkspaceLease(gomock.Any(), arg).Return(lease, nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionUpdate).Returns(lease)
+	}))
+	s.Run("CountActiveWorkspaceLeasesByAPIKeyID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		key := testutil.Fake(s.T(), faker, database.APIKey{})
+		dbm.EXPECT().GetAPIKeyByID(gomock.Any(), key.ID).Return(key, nil).AnyTimes()
+		dbm.EXPECT().CountActiveWorkspaceLeasesByAPIKeyID(gomock.Any(), key.ID).Return(int64(1), nil).AnyTimes()
+		check.Args(key.ID).Asserts(key, policy.ActionRead).Returns(int64(1))
+	}))
+	s.Run("GetExpiredWorkspaceLeases", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetExpiredWorkspaceLeasesParams{Now: dbtime.Now(), MaxLeases: 10}
+		dbm.EXPECT().GetExpiredWorkspaceLeases(gomock.Any(), arg).Return([]database.WorkspaceLease{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
 }
 
 func (s *MethodTestSuite) TestTasks() {
@@ -4713,6 +5525,22 @@ func (s *MethodTestSuite) TestProvisionerKeys() {
 		dbm.EXPECT().DeleteProvisionerKey(gomock.Any(), pk.ID).Return(nil).AnyTimes()
 		check.Args(pk.ID).Asserts(pk, policy.ActionDelete).Returns()
 	}))
+	s.Run("RotateProvisionerKey", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		org := testutil.Fake(s.T(), faker, database.Organization{})
+		pk := testutil.Fake(s.T(), faker, database.ProvisionerKey{OrganizationID: org.ID})
+		arg := database.RotateProvisionerKeyParams{ID: pk.ID, HashedSecret: []byte("bar")}
+		dbm.EXPECT().GetProvisionerKeyByID(gomock.Any(), pk.ID).Return(pk, nil).AnyTimes()
+		dbm.EXPECT().RotateProvisionerKey(gomock.Any(), arg).Return(pk, nil).AnyTimes()
+		check.Args(arg).Asserts(pk, policy.ActionUpdate).Returns(pk)
+	}))
+	s.Run("UpdateProvisionerKeyLastUsedAt", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		org := testutil.Fake(s.T(), faker, database.Organization{})
+		pk := testutil.Fake(s.T(), faker, database.ProvisionerKey{OrganizationID: org.ID})
+		arg := database.UpdateProvisionerKeyLastUsedAtParams{ID: pk.ID, LastUsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true}}
+		dbm.EXPECT().GetProvisionerKeyByID(gomock.Any(), pk.ID).Return(pk, nil).AnyTimes()
+		dbm.EXPECT().UpdateProvisionerKeyLastUsedAt(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(pk, policy.ActionUpdate).Returns()
+	}))
 }
 
 func (s *MethodTestSuite) TestExtraMethods() {
@@ -5144,6 +5972,11 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().DeleteOldWorkspaceAgentStats(gomock.Any()).Return(nil).AnyTimes()
 		check.Args().Asserts(rbac.ResourceSystem, policy.ActionDelete)
 	}))
+	s.Run("CountRunningProvisionerJobsByWorkerID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		workerID := uuid.New()
+		dbm.EXPECT().CountRunningProvisionerJobsByWorkerID(gomock.Any(), workerID).Return(int64(0), nil).AnyTimes()
+		check.Args(workerID).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead).Returns(int64(0))
+	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		ts := dbtime.Now()
 		dbm.EXPECT().GetProvisionerJobsCreatedAfter(gomock.Any(), ts).Return([]database.ProvisionerJob{}, nil).AnyTimes()
@@ -5179,6 +6012,11 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			Asserts(rbac.ResourceSystem, policy.ActionRead).
 			Returns([]database.WorkspaceApp{a, b})
 	}))
+	s.Run("GetWorkspaceBuildParameterChangesByBuildIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetWorkspaceBuildParameterChangesByBuildIDs(gomock.Any(), ids).Return([]database.WorkspaceBuildParameterChange{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
 	s.Run("GetWorkspaceResourcesByJobIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		ids := []uuid.UUID{uuid.New(), uuid.New()}
 		dbm.EXPECT().GetWorkspaceResourcesByJobIDs(gomock.Any(), ids).Return([]database.WorkspaceResource{}, nil).AnyTimes()
@@ -5272,6 +6110,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().UpdateProvisionerJobWithCompleteWithStartedAtByID(gomock.Any(), arg).Return(nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
 	}))
+	s.Run("UpdateProvisionerJobWithRequeueByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		j := testutil.Fake(s.T(), faker, database.ProvisionerJob{})
+		arg := database.UpdateProvisionerJobWithRequeueByIDParams{ID: j.ID, UpdatedAt: dbtime.Now()}
+		dbm.EXPECT().UpdateProvisionerJobWithRequeueByID(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
+	}))
 	s.Run("UpdateProvisionerJobByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		j := testutil.Fake(s.T(), faker, database.ProvisionerJob{})
 		arg := database.UpdateProvisionerJobByIDParams{ID: j.ID, UpdatedAt: dbtime.Now()}
@@ -5290,6 +6134,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().UpdateProvisionerJobLogsOverflowed(gomock.Any(), arg).Return(nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
 	}))
+	s.Run("UpdateProvisionerJobTimeoutByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		j := testutil.Fake(s.T(), faker, database.ProvisionerJob{})
+		arg := database.UpdateProvisionerJobTimeoutByIDParams{ID: j.ID, Timeout: int64(time.Hour)}
+		dbm.EXPECT().UpdateProvisionerJobTimeoutByID(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
+	}))
 	s.Run("InsertProvisionerJob", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.InsertProvisionerJobParams{
 			ID:            uuid.New(),
@@ -5353,11 +6203,88 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().DeleteOldWorkspaceAgentLogs(gomock.Any(), t).Return(int64(0), nil).AnyTimes()
 		check.Args(t).Asserts(rbac.ResourceSystem, policy.ActionDelete)
 	}))
+	s.Run("DeleteOldWorkspaceAgentMetadataHistory", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.DeleteOldWorkspaceAgentMetadataHistoryParams{}
+		dbm.EXPECT().DeleteOldWorkspaceAgentMetadataHistory(gomock.Any(), arg).Return(int64(0), nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
 	s.Run("InsertWorkspaceAgentStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.InsertWorkspaceAgentStatsParams{}
 		dbm.EXPECT().InsertWorkspaceAgentStats(gomock.Any(), arg).Return(xerrors.New("any error")).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate).Errors(errMatchAny)
 	}))
+	s.Run("ClaimTemplateVersionScans", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.ClaimTemplateVersionScansParams{MaxScans: 10}
+		dbm.EXPECT().ClaimTemplateVersionScans(gomock.Any(), arg).Return([]database.TemplateVersionScan{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("UpdateTemplateVersionScanByTemplateVersionID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpdateTemplateVersionScanByTemplateVersionIDParams{TemplateVersionID: uuid.New(), Status: database.TemplateVersionScanStatusCompleted}
+		dbm.EXPECT().UpdateTemplateVersionScanByTemplateVersionID(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("ClaimWorkspaceDormancyHooks", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.ClaimWorkspaceDormancyHooksParams{LimitCount: 10}
+		dbm.EXPECT().ClaimWorkspaceDormancyHooks(gomock.Any(), arg).Return([]database.WorkspaceDormancyHook{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceDormancyHookStatus", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpdateWorkspaceDormancyHookStatusParams{WorkspaceID: uuid.New(), Event: database.WorkspaceDormancyHookEventActivate, Status: database.WorkspaceDormancyHookStatusSucceeded}
+		dbm.EXPECT().UpdateWorkspaceDormancyHookStatus(gomock.Any(), arg).Return(int64(1), nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceDNSRecordChanges", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceDNSRecordChangesParams{Domain: "apps.example.com", Target: "coder.example.com", MaxChanges: 10}
+		dbm.EXPECT().GetWorkspaceDNSRecordChanges(gomock.Any(), arg).Return([]database.GetWorkspaceDNSRecordChangesRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceDNSRecordsByWorkspaceIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetWorkspaceDNSRecordsByWorkspaceIDs(gomock.Any(), ids).Return([]database.WorkspaceDNSRecord{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceDNSRecord", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpsertWorkspaceDNSRecordParams{WorkspaceID: uuid.New(), Name: "dev--alice.apps.example.com"}
+		dbm.EXPECT().UpsertWorkspaceDNSRecord(gomock.Any(), arg).Return(database.WorkspaceDNSRecord{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("DeleteWorkspaceDNSRecordByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		id := uuid.New()
+		dbm.EXPECT().DeleteWorkspaceDNSRecordByWorkspaceID(gomock.Any(), id).Return(nil).AnyTimes()
+		check.Args(id).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("GetWorkspaceRegionAssignmentsByWorkspaceIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetWorkspaceRegionAssignmentsByWorkspaceIDs(gomock.Any(), ids).Return([]database.WorkspaceRegionAssignment{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceRegionAssignmentCounts", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().GetWorkspaceRegionAssignmentCounts(gomock.Any()).Return([]database.GetWorkspaceRegionAssignmentCountsRow{}, nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceRegionAssignment", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.UpsertWorkspaceRegionAssignmentParams{WorkspaceID: ws.ID, RegionID: uuid.New(), CreatedAt: dbtime.Now()}
+		assignment := database.WorkspaceRegionAssignment{WorkspaceID: ws.ID, RegionID: arg.RegionID, CreatedAt: arg.CreatedAt}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().UpsertWorkspaceRegionAssignment(gomock.Any(), arg).Return(assignment, nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionUpdate).Returns(assignment)
+	}))
+	s.Run("AccrueWorkspaceEstimatedCosts", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.AccrueWorkspaceEstimatedCostsParams{Now: dbtime.Now()}
+		dbm.EXPECT().AccrueWorkspaceEstimatedCosts(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspacesExceedingCostBudget", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspacesExceedingCostBudgetParams{WorkspaceBudget: 100, UserBudget: 500}
+		dbm.EXPECT().GetWorkspacesExceedingCostBudget(gomock.Any(), arg).Return([]database.GetWorkspacesExceedingCostBudgetRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceEgressDaily", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpsertWorkspaceEgressDailyParams{}
+		dbm.EXPECT().UpsertWorkspaceEgressDaily(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
 	s.Run("InsertWorkspaceAppStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.InsertWorkspaceAppStatsParams{}
 		dbm.EXPECT().InsertWorkspaceAppStats(gomock.Any(), arg).Return(nil).AnyTimes()
@@ -5381,6 +6308,11 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().InsertWorkspaceAgentScripts(gomock.Any(), arg).Return([]database.WorkspaceAgentScript{}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
 	}))
+	s.Run("InsertWorkspaceAgentServices", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.InsertWorkspaceAgentServicesParams{}
+		dbm.EXPECT().InsertWorkspaceAgentServices(gomock.Any(), arg).Return([]database.WorkspaceAgentService{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
 	s.Run("InsertWorkspaceAgentMetadata", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.InsertWorkspaceAgentMetadataParams{}
 		dbm.EXPECT().InsertWorkspaceAgentMetadata(gomock.Any(), arg).Return(nil).AnyTimes()
@@ -5454,6 +6386,26 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().GetProvisionerJobsToBeReaped(gomock.Any(), arg).Return([]database.ProvisionerJob{}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
 	}))
+	s.Run("GetProvisionerJobsWithExpiredLogs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetProvisionerJobsWithExpiredLogsParams{}
+		dbm.EXPECT().GetProvisionerJobsWithExpiredLogs(gomock.Any(), arg).Return([]uuid.UUID{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
+	}))
+	s.Run("GetProvisionerJobLogArchivesByJobIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetProvisionerJobLogArchivesByJobIDs(gomock.Any(), ids).Return([]database.ProvisionerJobLogArchive{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
+	}))
+	s.Run("InsertProvisionerJobLogArchive", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.InsertProvisionerJobLogArchiveParams{JobID: uuid.New()}
+		dbm.EXPECT().InsertProvisionerJobLogArchive(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
+	}))
+	s.Run("DeleteProvisionerJobLogsByJobID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		id := uuid.New()
+		dbm.EXPECT().DeleteProvisionerJobLogsByJobID(gomock.Any(), id).Return(int64(0), nil).AnyTimes()
+		check.Args(id).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
+	}))
 	s.Run("InsertMissingGroups", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.InsertMissingGroupsParams{}
 		dbm.EXPECT().InsertMissingGroups(gomock.Any(), arg).Return([]database.Group{}, xerrors.New("any error")).AnyTimes()
@@ -5531,6 +6483,54 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().GetWorkspaceAgentScriptsByAgentIDs(gomock.Any(), ids).Return([]database.GetWorkspaceAgentScriptsByAgentIDsRow{}, nil).AnyTimes()
 		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("GetLatestWorkspaceAgentBootstrapProgressByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(gomock.Any(), ids).Return([]database.WorkspaceAgentBootstrapProgress{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentServicesByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetWorkspaceAgentServicesByAgentIDs(gomock.Any(), ids).Return([]database.WorkspaceAgentService{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentServiceTransitionsByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetWorkspaceAgentServiceTransitionsByAgentIDs(gomock.Any(), ids).Return([]database.WorkspaceAgentServiceTransition{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetLatestWorkspaceAgentServiceTransitionsByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(gomock.Any(), ids).Return([]database.WorkspaceAgentServiceTransition{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDsParams{IDs: []uuid.UUID{uuid.New()}}
+		dbm.EXPECT().GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(gomock.Any(), arg).Return([]database.WorkspaceAgentNetworkPolicyViolation{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsParams{IDs: []uuid.UUID{uuid.New()}}
+		dbm.EXPECT().GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(gomock.Any(), arg).Return([]database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("DeleteOldWorkspaceAgentNetworkPolicyViolations", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().DeleteOldWorkspaceAgentNetworkPolicyViolations(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("GetWorkspaceAgentMemoryEventsByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceAgentMemoryEventsByAgentIDsParams{IDs: []uuid.UUID{uuid.New()}}
+		dbm.EXPECT().GetWorkspaceAgentMemoryEventsByAgentIDs(gomock.Any(), arg).Return([]database.WorkspaceAgentMemoryEvent{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentMemoryEventCountsByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceAgentMemoryEventCountsByAgentIDsParams{IDs: []uuid.UUID{uuid.New()}}
+		dbm.EXPECT().GetWorkspaceAgentMemoryEventCountsByAgentIDs(gomock.Any(), arg).Return([]database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("DeleteOldWorkspaceAgentMemoryEvents", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().DeleteOldWorkspaceAgentMemoryEvents(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
 	s.Run("GetWorkspaceAgentLogSourcesByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		ids := []uuid.UUID{uuid.New()}
 		dbm.EXPECT().GetWorkspaceAgentLogSourcesByAgentIDs(gomock.Any(), ids).Return([]database.WorkspaceAgentLogSource{}, nil).AnyTimes()
@@ -5541,6 +6541,11 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().GetProvisionerJobsByIDsWithQueuePosition(gomock.Any(), arg).Return([]database.GetProvisionerJobsByIDsWithQueuePositionRow{}, nil).AnyTimes()
 		check.Args(arg).Asserts()
 	}))
+	s.Run("GetProvisionerJobsByInitiatorWithQueuePosition", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		userID := uuid.New()
+		dbm.EXPECT().GetProvisionerJobsByInitiatorWithQueuePosition(gomock.Any(), userID).Return([]database.GetProvisionerJobsByInitiatorWithQueuePositionRow{}, nil).AnyTimes()
+		check.Args(userID).Asserts(rbac.ResourceUserObject(userID), policy.ActionRead)
+	}))
 	s.Run("GetReplicaByID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		id := uuid.New()
 		dbm.EXPECT().GetReplicaByID(gomock.Any(), id).Return(database.Replica{}, sql.ErrNoRows).AnyTimes()
@@ -5583,6 +6588,26 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().GetWorkspaceBuildStatsByTemplates(gomock.Any(), at).Return([]database.GetWorkspaceBuildStatsByTemplatesRow{}, nil).AnyTimes()
 		check.Args(at).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("GetTemplateHealthStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		at := dbtime.Now()
+		dbm.EXPECT().GetTemplateHealthStats(gomock.Any(), at).Return([]database.GetTemplateHealthStatsRow{}, nil).AnyTimes()
+		check.Args(at).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("InsertTemplateHealthReport", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.InsertTemplateHealthReportParams{ID: uuid.New(), TemplateID: uuid.New(), PeriodEnd: dbtime.Now()}
+		dbm.EXPECT().InsertTemplateHealthReport(gomock.Any(), arg).Return(database.TemplateHealthReport{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("GetUserWorkspaceReportStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetUserWorkspaceReportStatsParams{Since: dbtime.Now(), Until: dbtime.Now()}
+		dbm.EXPECT().GetUserWorkspaceReportStats(gomock.Any(), arg).Return([]database.GetUserWorkspaceReportStatsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("InsertUserWorkspaceReport", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.InsertUserWorkspaceReportParams{ID: uuid.New(), UserID: uuid.New(), PeriodEnd: dbtime.Now()}
+		dbm.EXPECT().InsertUserWorkspaceReport(gomock.Any(), arg).Return(database.UserWorkspaceReport{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
 	s.Run("UpsertNotificationReportGeneratorLog", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.UpsertNotificationReportGeneratorLogParams{NotificationTemplateID: uuid.New(), LastGeneratedAt: dbtime.Now()}
 		dbm.EXPECT().UpsertNotificationReportGeneratorLog(gomock.Any(), arg).Return(nil).AnyTimes()
@@ -5727,6 +6752,11 @@ func (s *MethodTestSuite) TestNotifications() {
 		dbm.EXPECT().GetNotificationMessagesByStatus(gomock.Any(), arg).Return([]database.NotificationMessage{}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead)
 	}))
+	s.Run("GetNotificationMessagesByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetNotificationMessagesByTemplateIDParams{NotificationTemplateID: notifications.TemplateWorkspaceDormant, Limit: 10}
+		dbm.EXPECT().GetNotificationMessagesByTemplateID(gomock.Any(), arg).Return([]database.NotificationMessage{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead)
+	}))
 
 	// webpush subscriptions
 	s.Run("GetWebpushSubscriptionsByUserID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
@@ -5774,6 +6804,19 @@ func (s *MethodTestSuite) TestNotifications() {
 		dbm.EXPECT().UpdateNotificationTemplateMethodByID(gomock.Any(), arg).Return(database.NotificationTemplate{}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceNotificationTemplate, policy.ActionUpdate)
 	}))
+	s.Run("GetNotificationTemplateWebhookByTemplateID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().GetNotificationTemplateWebhookByTemplateID(gomock.Any(), notifications.TemplateWorkspaceDormant).Return(database.NotificationTemplateWebhook{}, nil).AnyTimes()
+		check.Args(notifications.TemplateWorkspaceDormant).Asserts(rbac.ResourceNotificationTemplate, policy.ActionRead)
+	}))
+	s.Run("UpsertNotificationTemplateWebhook", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpsertNotificationTemplateWebhookParams{NotificationTemplateID: notifications.TemplateWorkspaceDormant, Endpoint: "https://hooks.example.com"}
+		dbm.EXPECT().UpsertNotificationTemplateWebhook(gomock.Any(), arg).Return(database.NotificationTemplateWebhook{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceNotificationTemplate, policy.ActionUpdate)
+	}))
+	s.Run("DeleteNotificationTemplateWebhook", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().DeleteNotificationTemplateWebhook(gomock.Any(), notifications.TemplateWorkspaceDormant).Return(nil).AnyTimes()
+		check.Args(notifications.TemplateWorkspaceDormant).Asserts(rbac.ResourceNotificationTemplate, policy.ActionUpdate)
+	}))
 
 	// Notification preferences
 	s.Run("GetUserNotificationPreferences", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
@@ -5787,6 +6830,23 @@ func (s *MethodTestSuite) TestNotifications() {
 		dbm.EXPECT().UpdateUserNotificationPreferences(gomock.Any(), arg).Return(int64(2), nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
 	}))
+	s.Run("GetUserNotificationScopedPreferences", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		user := testutil.Fake(s.T(), faker, database.User{})
+		dbm.EXPECT().GetUserNotificationScopedPreferences(gomock.Any(), user.ID).Return([]database.NotificationScopedPreference{}, nil).AnyTimes()
+		check.Args(user.ID).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionRead)
+	}))
+	s.Run("UpsertUserNotificationScopedPreferences", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		user := testutil.Fake(s.T(), faker, database.User{})
+		arg := database.UpsertUserNotificationScopedPreferencesParams{UserID: user.ID, NotificationTemplateIds: []uuid.UUID{notifications.TemplateWorkspaceDormant}, Scopes: []database.NotificationPreferenceScope{database.NotificationPreferenceScopeWorkspace}, TargetIds: []uuid.UUID{uuid.New()}, Disableds: []bool{true}}
+		dbm.EXPECT().UpsertUserNotificationScopedPreferences(gomock.Any(), arg).Return(int64(1), nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
+	}))
+	s.Run("DeleteUserNotificationScopedPreferences", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		user := testutil.Fake(s.T(), faker, database.User{})
+		arg := database.DeleteUserNotificationScopedPreferencesParams{UserID: user.ID, NotificationTemplateIds: []uuid.UUID{notifications.TemplateWorkspaceDormant}, TargetIds: []uuid.UUID{uuid.New()}}
+		dbm.EXPECT().DeleteUserNotificationScopedPreferences(gomock.Any(), arg).Return(int64(1), nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
+	}))
 
 	s.Run("GetInboxNotificationsByUserID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		u := testutil.Fake(s.T(), faker, database.User{})
@@ -5934,6 +6994,14 @@ func (s *MethodTestSuite) TestPrebuilds() {
 		dbm.EXPECT().UpsertPrebuildsSettings(gomock.Any(), "foo").Return(nil).AnyTimes()
 		check.Args("foo").Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
 	}))
+	s.Run("GetProvisionerCacheSettings", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().GetProvisionerCacheSettings(gomock.Any()).Return("{}", nil).AnyTimes()
+		check.Args().Asserts()
+	}))
+	s.Run("UpsertProvisionerCacheSettings", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().UpsertProvisionerCacheSettings(gomock.Any(), "foo").Return(nil).AnyTimes()
+		check.Args("foo").Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
+	}))
 	s.Run("CountInProgressPrebuilds", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		dbm.EXPECT().CountInProgressPrebuilds(gomock.Any()).Return([]database.CountInProgressPrebuildsRow{}, nil).AnyTimes()
 		check.Args().Asserts(rbac.ResourceWorkspace.All(), policy.ActionRead)
@@ -5951,6 +7019,21 @@ func (s *MethodTestSuite) TestPrebuilds() {
 		dbm.EXPECT().GetPresetsBackoff(gomock.Any(), t0).Return([]database.GetPresetsBackoffRow{}, nil).AnyTimes()
 		check.Args(t0).Asserts(rbac.ResourceTemplate.All(), policy.ActionViewInsights)
 	}))
+	s.Run("GetPrebuildClaimInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetPrebuildClaimInsightsParams{}
+		dbm.EXPECT().GetPrebuildClaimInsights(gomock.Any(), arg).Return([]database.GetPrebuildClaimInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate.All(), policy.ActionViewInsights)
+	}))
+	s.Run("GetPresetPrebuildPoolState", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetPresetPrebuildPoolStateParams{PresetID: uuid.New()}
+		dbm.EXPECT().GetPresetPrebuildPoolState(gomock.Any(), arg).Return(database.GetPresetPrebuildPoolStateRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate.All(), policy.ActionViewInsights)
+	}))
+	s.Run("InsertPrebuildClaimAttempt", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.InsertPrebuildClaimAttemptParams{ID: uuid.New(), PresetID: uuid.New()}
+		dbm.EXPECT().InsertPrebuildClaimAttempt(gomock.Any(), arg).Return(database.PrebuildClaimAttempt{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
 	s.Run("GetRunningPrebuiltWorkspaces", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		dbm.EXPECT().GetRunningPrebuiltWorkspaces(gomock.Any()).Return([]database.GetRunningPrebuiltWorkspacesRow{}, nil).AnyTimes()
 		check.Args().Asserts(rbac.ResourceWorkspace.All(), policy.ActionRead)
@@ -6315,6 +7398,17 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 		check.Args(arg).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionUpdate)
 	}))
 
+	s.Run("InsertWorkspaceAgentMemoryEvent", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		arg := database.InsertWorkspaceAgentMemoryEventParams{
+			ID:               uuid.New(),
+			WorkspaceAgentID: uuid.New(),
+			Kind:             database.WorkspaceAgentMemoryEventKindOomKill,
+			OomKills:         1,
+		}
+		dbm.EXPECT().InsertWorkspaceAgentMemoryEvent(gomock.Any(), arg).Return(database.WorkspaceAgentMemoryEvent{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionUpdate)
+	}))
+
 	s.Run("UpdateVolumeResourceMonitor", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		arg := database.UpdateVolumeResourceMonitorParams{
 			AgentID: uuid.New(),
@@ -6409,6 +7503,269 @@ func (s *MethodTestSuite) TestWorkspaceAgentContext() {
 	}))
 }
 
+func (s *MethodTestSuite) TestWorkspaceAgentCrashLoops() {
+	s.Run("GetWorkspaceAgentCrashLoopByAgentID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceAgentCrashLoopByAgentID(gomock.Any(), agt.ID).Return(database.WorkspaceAgentCrashLoop{}, nil).AnyTimes()
+		check.Args(agt.ID).Asserts(w, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentCrashLoopsByAgentIDs", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetWorkspaceAgentCrashLoopsByAgentIDs(gomock.Any(), ids).Return([]database.WorkspaceAgentCrashLoop{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceAgentCrashLoop", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		arg := database.UpsertWorkspaceAgentCrashLoopParams{
+			AgentID:            agt.ID,
+			ShortConnections:   1,
+			LastLifecycleState: database.WorkspaceAgentLifecycleStateStartError,
+		}
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().UpsertWorkspaceAgentCrashLoop(gomock.Any(), arg).Return(database.WorkspaceAgentCrashLoop{}, nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspaceAgentCrashLoop", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().DeleteWorkspaceAgentCrashLoop(gomock.Any(), agt.ID).Return(nil).AnyTimes()
+		check.Args(agt.ID).Asserts(w, policy.ActionUpdate).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestIdempotencyKeys() {
+	s.Run("GetIdempotencyKey", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		key := testutil.Fake(s.T(), faker, database.IdempotencyKey{})
+		arg := database.GetIdempotencyKeyParams{UserID: key.UserID, Operation: key.Operation, Key: key.Key}
+		dbm.EXPECT().GetIdempotencyKey(gomock.Any(), arg).Return(key, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceUserObject(key.UserID), policy.ActionReadPersonal).Returns(key)
+	}))
+	s.Run("InsertIdempotencyKey", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		key := testutil.Fake(s.T(), faker, database.IdempotencyKey{})
+		arg := database.InsertIdempotencyKeyParams{UserID: key.UserID, Operation: key.Operation, Key: key.Key}
+		dbm.EXPECT().InsertIdempotencyKey(gomock.Any(), arg).Return(key, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceUserObject(key.UserID), policy.ActionUpdatePersonal).Returns(key)
+	}))
+	s.Run("DeleteExpiredIdempotencyKeys", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().DeleteExpiredIdempotencyKeys(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestTemplateUserPresets() {
+	s.Run("UpsertTemplateUserPreset", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		preset := testutil.Fake(s.T(), faker, database.TemplateUserPreset{Parameters: json.RawMessage("[]")})
+		arg := database.UpsertTemplateUserPresetParams{
+			ID:         preset.ID,
+			TemplateID: preset.TemplateID,
+			UserID:     preset.UserID,
+			Name:       preset.Name,
+			Parameters: preset.Parameters,
+			Now:        preset.CreatedAt,
+		}
+		dbm.EXPECT().UpsertTemplateUserPreset(gomock.Any(), arg).Return(preset, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceUserObject(preset.UserID), policy.ActionUpdatePersonal).Returns(preset)
+	}))
+	s.Run("GetTemplateUserPresetByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		preset := testutil.Fake(s.T(), faker, database.TemplateUserPreset{Parameters: json.RawMessage("[]")})
+		dbm.EXPECT().GetTemplateUserPresetByID(gomock.Any(), preset.ID).Return(preset, nil).AnyTimes()
+		check.Args(preset.ID).Asserts(preset, policy.ActionReadPersonal).Returns(preset)
+	}))
+	s.Run("GetTemplateUserPresetsByTemplateIDAndUserID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		preset := testutil.Fake(s.T(), faker, database.TemplateUserPreset{Parameters: json.RawMessage("[]")})
+		arg := database.GetTemplateUserPresetsByTemplateIDAndUserIDParams{TemplateID: preset.TemplateID, UserID: preset.UserID}
+		dbm.EXPECT().GetTemplateUserPresetsByTemplateIDAndUserID(gomock.Any(), arg).Return([]database.TemplateUserPreset{preset}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceUserObject(preset.UserID), policy.ActionReadPersonal).Returns([]database.TemplateUserPreset{preset})
+	}))
+	s.Run("DeleteTemplateUserPresetByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		preset := testutil.Fake(s.T(), faker, database.TemplateUserPreset{Parameters: json.RawMessage("[]")})
+		dbm.EXPECT().GetTemplateUserPresetByID(gomock.Any(), preset.ID).Return(preset, nil).AnyTimes()
+		dbm.EXPECT().DeleteTemplateUserPresetByID(gomock.Any(), preset.ID).Return(nil).AnyTimes()
+		check.Args(preset.ID).Asserts(preset, policy.ActionUpdatePersonal).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestWorkspaceCloudResources() {
+	s.Run("UpsertWorkspaceCloudResource", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpsertWorkspaceCloudResourceParams{
+			WorkspaceID:      uuid.New(),
+			ResourceType:     "aws_instance",
+			CloudID:          "i-0123456789abcdef0",
+			ResourceName:     "dev",
+			WorkspaceBuildID: uuid.New(),
+			Now:              dbtime.Now(),
+		}
+		dbm.EXPECT().UpsertWorkspaceCloudResource(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("GetWorkspaceCloudResourcesByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		resources := []database.WorkspaceCloudResource{{WorkspaceID: w.ID, ResourceType: "aws_instance", CloudID: "i-0123456789abcdef0"}}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceCloudResourcesByWorkspaceID(gomock.Any(), w.ID).Return(resources, nil).AnyTimes()
+		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns(resources)
+	}))
+	s.Run("GetUnverifiedWorkspaceCloudResources", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetUnverifiedWorkspaceCloudResourcesParams{CompletedBefore: dbtime.Now(), LimitCount: 10}
+		dbm.EXPECT().GetUnverifiedWorkspaceCloudResources(gomock.Any(), arg).Return([]database.GetUnverifiedWorkspaceCloudResourcesRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpdateWorkspaceCloudResourceVerification", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpdateWorkspaceCloudResourceVerificationParams{
+			VerificationStatus: database.CloudResourceVerificationStatusLeaked,
+			WorkspaceID:        uuid.New(),
+			ResourceType:       "aws_instance",
+			CloudID:            "i-0123456789abcdef0",
+		}
+		dbm.EXPECT().UpdateWorkspaceCloudResourceVerification(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetLeakedWorkspaceCloudResources", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().GetLeakedWorkspaceCloudResources(gomock.Any()).Return([]database.GetLeakedWorkspaceCloudResourcesRow{}, nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceWorkspace, policy.ActionRead)
+	}))
+}
+
+func (s *MethodTestSuite) TestSharedVolumes() {
+	s.Run("InsertSharedVolume", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.InsertSharedVolumeParams{ID: uuid.New(), OrganizationID: uuid.New(), Name: "datasets"}
+		dbm.EXPECT().InsertSharedVolume(gomock.Any(), arg).Return(database.SharedVolume{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate.InOrg(arg.OrganizationID), policy.ActionUpdate)
+	}))
+	s.Run("GetSharedVolumeByID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		volume := database.SharedVolume{ID: uuid.New(), OrganizationID: uuid.New(), Name: "datasets"}
+		dbm.EXPECT().GetSharedVolumeByID(gomock.Any(), volume.ID).Return(volume, nil).AnyTimes()
+		check.Args(volume.ID).Asserts(rbac.ResourceOrganization.WithID(volume.OrganizationID).InOrg(volume.OrganizationID), policy.ActionRead).Returns(volume)
+	}))
+	s.Run("GetSharedVolumeByOrganizationIDAndName", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetSharedVolumeByOrganizationIDAndNameParams{OrganizationID: uuid.New(), Name: "datasets"}
+		dbm.EXPECT().GetSharedVolumeByOrganizationIDAndName(gomock.Any(), arg).Return(database.SharedVolume{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceOrganization.WithID(arg.OrganizationID).InOrg(arg.OrganizationID), policy.ActionRead)
+	}))
+	s.Run("GetSharedVolumesByOrganizationID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		orgID := uuid.New()
+		dbm.EXPECT().GetSharedVolumesByOrganizationID(gomock.Any(), orgID).Return([]database.GetSharedVolumesByOrganizationIDRow{}, nil).AnyTimes()
+		check.Args(orgID).Asserts(rbac.ResourceOrganization.WithID(orgID).InOrg(orgID), policy.ActionRead)
+	}))
+	s.Run("GetActiveSharedVolumeAttachmentsBySharedVolumeID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		volume := database.SharedVolume{ID: uuid.New(), OrganizationID: uuid.New(), Name: "datasets"}
+		dbm.EXPECT().GetSharedVolumeByID(gomock.Any(), volume.ID).Return(volume, nil).AnyTimes()
+		dbm.EXPECT().GetActiveSharedVolumeAttachmentsBySharedVolumeID(gomock.Any(), volume.ID).Return([]database.GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow{}, nil).AnyTimes()
+		check.Args(volume.ID).Asserts(rbac.ResourceTemplate.InOrg(volume.OrganizationID), policy.ActionRead)
+	}))
+	s.Run("GetSharedVolumeAttachmentByID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		volume := database.SharedVolume{ID: uuid.New(), OrganizationID: uuid.New(), Name: "datasets"}
+		attachment := database.SharedVolumeAttachment{ID: uuid.New(), SharedVolumeID: volume.ID}
+		dbm.EXPECT().GetSharedVolumeAttachmentByID(gomock.Any(), attachment.ID).Return(attachment, nil).AnyTimes()
+		dbm.EXPECT().GetSharedVolumeByID(gomock.Any(), volume.ID).Return(volume, nil).AnyTimes()
+		check.Args(attachment.ID).Asserts(rbac.ResourceTemplate.InOrg(volume.OrganizationID), policy.ActionRead).Returns(attachment)
+	}))
+	s.Run("InsertSharedVolumeAttachment", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.InsertSharedVolumeAttachmentParams{
+			ID:             uuid.New(),
+			SharedVolumeID: uuid.New(),
+			WorkspaceID:    w.ID,
+			MountPath:      "/mnt/datasets",
+			AccessMode:     database.SharedVolumeAccessModeReadOnly,
+		}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().InsertSharedVolumeAttachment(gomock.Any(), arg).Return(database.SharedVolumeAttachment{}, nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("DetachSharedVolumeAttachmentsByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.DetachSharedVolumeAttachmentsByWorkspaceIDParams{WorkspaceID: w.ID, DetachedAt: dbtime.Now()}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), w.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().DetachSharedVolumeAttachmentsByWorkspaceID(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("DetachSharedVolumeAttachmentByID", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		volume := database.SharedVolume{ID: uuid.New(), OrganizationID: uuid.New(), Name: "datasets"}
+		attachment := database.SharedVolumeAttachment{ID: uuid.New(), SharedVolumeID: volume.ID}
+		arg := database.DetachSharedVolumeAttachmentByIDParams{ID: attachment.ID, DetachedAt: dbtime.Now(), DetachedBy: uuid.New()}
+		dbm.EXPECT().GetSharedVolumeAttachmentByID(gomock.Any(), attachment.ID).Return(attachment, nil).AnyTimes()
+		dbm.EXPECT().GetSharedVolumeByID(gomock.Any(), volume.ID).Return(volume, nil).AnyTimes()
+		dbm.EXPECT().DetachSharedVolumeAttachmentByID(gomock.Any(), arg).Return(attachment, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate.InOrg(volume.OrganizationID), policy.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestUserImpersonations() {
+	s.Run("GetUserImpersonationByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		imp := testutil.Fake(s.T(), faker, database.UserImpersonation{})
+		dbm.EXPECT().GetUserImpersonationByID(gomock.Any(), imp.ID).Return(imp, nil).AnyTimes()
+		check.Args(imp.ID).Asserts(rbac.ResourceUserObject(imp.UserID), policy.ActionReadPersonal).Returns(imp)
+	}))
+	s.Run("GetUserImpersonationByAPIKeyID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		imp := testutil.Fake(s.T(), faker, database.UserImpersonation{})
+		dbm.EXPECT().GetUserImpersonationByAPIKeyID(gomock.Any(), "key").Return(imp, nil).AnyTimes()
+		check.Args("key").Asserts(rbac.ResourceUserObject(imp.UserID), policy.ActionReadPersonal).Returns(imp)
+	}))
+	s.Run("GetUserImpersonationsByUserID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		u := testutil.Fake(s.T(), faker, database.User{})
+		dbm.EXPECT().GetUserImpersonationsByUserID(gomock.Any(), u.ID).Return([]database.UserImpersonation{}, nil).AnyTimes()
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal)
+	}))
+	s.Run("InsertUserImpersonation", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		u := testutil.Fake(s.T(), faker, database.User{})
+		arg := database.InsertUserImpersonationParams{UserID: u.ID, ImpersonatorID: uuid.New(), Reason: "debugging"}
+		dbm.EXPECT().InsertUserImpersonation(gomock.Any(), arg).Return(database.UserImpersonation{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionImpersonate)
+	}))
+	s.Run("UpdateUserImpersonationConsentedAt", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		imp := testutil.Fake(s.T(), faker, database.UserImpersonation{})
+		arg := database.UpdateUserImpersonationConsentedAtParams{ID: imp.ID, ConsentedAt: sql.NullTime{Time: dbtime.Now(), Valid: true}}
+		dbm.EXPECT().GetUserImpersonationByID(gomock.Any(), imp.ID).Return(imp, nil).AnyTimes()
+		dbm.EXPECT().UpdateUserImpersonationConsentedAt(gomock.Any(), arg).Return(imp, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceUserObject(imp.UserID), policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateUserImpersonationIssued", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		imp := testutil.Fake(s.T(), faker, database.UserImpersonation{})
+		arg := database.UpdateUserImpersonationIssuedParams{ID: imp.ID, APIKeyID: sql.NullString{String: "key", Valid: true}, IssuedAt: sql.NullTime{Time: dbtime.Now(), Valid: true}}
+		dbm.EXPECT().GetUserImpersonationByID(gomock.Any(), imp.ID).Return(imp, nil).AnyTimes()
+		dbm.EXPECT().UpdateUserImpersonationIssued(gomock.Any(), arg).Return(imp, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceUserObject(imp.UserID), policy.ActionImpersonate)
+	}))
+}
+
+func (s *MethodTestSuite) TestWorkspaceAppShareLinks() {
+	s.Run("GetWorkspaceAppShareLinkByID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		link := testutil.Fake(s.T(), faker, database.WorkspaceAppShareLink{WorkspaceID: ws.ID})
+		dbm.EXPECT().GetWorkspaceAppShareLinkByID(gomock.Any(), link.ID).Return(link, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		check.Args(link.ID).Asserts(ws, policy.ActionRead).Returns(link)
+	}))
+	s.Run("GetWorkspaceAppShareLinksByWorkspaceID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		link := testutil.Fake(s.T(), faker, database.WorkspaceAppShareLink{WorkspaceID: ws.ID})
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceAppShareLinksByWorkspaceID(gomock.Any(), ws.ID).Return([]database.WorkspaceAppShareLink{link}, nil).AnyTimes()
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead).Returns([]database.WorkspaceAppShareLink{link})
+	}))
+	s.Run("InsertWorkspaceAppShareLink", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.InsertWorkspaceAppShareLinkParams{ID: uuid.New(), WorkspaceID: ws.ID, AgentName: "main", AppSlug: "code-server"}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().InsertWorkspaceAppShareLink(gomock.Any(), arg).Return(database.WorkspaceAppShareLink{}, nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionShare)
+	}))
+	s.Run("RevokeWorkspaceAppShareLink", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		link := testutil.Fake(s.T(), faker, database.WorkspaceAppShareLink{WorkspaceID: ws.ID})
+		arg := database.RevokeWorkspaceAppShareLinkParams{ID: link.ID, RevokedAt: sql.NullTime{Time: dbtime.Now(), Valid: true}}
+		dbm.EXPECT().GetWorkspaceAppShareLinkByID(gomock.Any(), link.ID).Return(link, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().RevokeWorkspaceAppShareLink(gomock.Any(), arg).Return(link, nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionShare).Returns(link)
+	}))
+}
+
 func (s *MethodTestSuite) TestResourcesProvisionerdserver() {
 	createAgent := func(t *testing.T, db database.Store) (database.WorkspaceAgent, database.WorkspaceTable) {
 		t.Helper()
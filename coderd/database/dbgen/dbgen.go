@@ -77,6 +77,7 @@ func AuditLog(t testing.TB, db database.Store, seed database.AuditLog) database.
 		AdditionalFields: takeFirstSlice(seed.AdditionalFields, []byte("{}")),
 		RequestID:        takeFirst(seed.RequestID, uuid.New()),
 		ResourceIcon:     takeFirst(seed.ResourceIcon, ""),
+		CorrelationID:    seed.CorrelationID,
 	})
 	require.NoError(t, err, "insert audit log")
 	return log
@@ -555,6 +556,9 @@ func Template(t testing.TB, db database.Store, seed database.Template) database.
 		MaxPortSharingLevel:          takeFirst(seed.MaxPortSharingLevel, database.AppSharingLevelOwner),
 		UseClassicParameterFlow:      takeFirst(seed.UseClassicParameterFlow, false),
 		CorsBehavior:                 takeFirst(seed.CorsBehavior, database.CorsBehaviorSimple),
+		ProvisionerPlanTimeout:       seed.ProvisionerPlanTimeout,
+		ProvisionerApplyTimeout:      seed.ProvisionerApplyTimeout,
+		TrialWorkspaceTTL:            seed.TrialWorkspaceTTL,
 	})
 	require.NoError(t, err, "insert template")
 
@@ -824,6 +828,7 @@ func Workspace(t testing.TB, db database.Store, orig database.WorkspaceTable) da
 		Ttl:               orig.Ttl,
 		AutomaticUpdates:  takeFirst(orig.AutomaticUpdates, database.AutomaticUpdatesNever),
 		NextStartAt:       orig.NextStartAt,
+		ExpiresAt:         orig.ExpiresAt,
 	})
 	require.NoError(t, err, "insert workspace")
 	if orig.Deleted {
@@ -842,6 +847,22 @@ func Workspace(t testing.TB, db database.Store, orig database.WorkspaceTable) da
 		require.NoError(t, err, "set workspace as dormant")
 		workspace.DormantAt = orig.DormantAt
 	}
+	if orig.ArchivedAt.Valid {
+		err = db.UpdateWorkspaceArchivedAt(genCtx, database.UpdateWorkspaceArchivedAtParams{
+			ID:         workspace.ID,
+			ArchivedAt: orig.ArchivedAt,
+		})
+		require.NoError(t, err, "set workspace as archived")
+		workspace.ArchivedAt = orig.ArchivedAt
+	}
+	if orig.ReadOnlyAt.Valid {
+		_, err = db.UpdateWorkspaceReadOnlyAt(genCtx, database.UpdateWorkspaceReadOnlyAtParams{
+			ID:         workspace.ID,
+			ReadOnlyAt: orig.ReadOnlyAt,
+		})
+		require.NoError(t, err, "set workspace as read-only")
+		workspace.ReadOnlyAt = orig.ReadOnlyAt
+	}
 	if len(orig.UserACL) > 0 || len(orig.GroupACL) > 0 {
 		userACL := orig.UserACL
 		if userACL == nil {
@@ -955,13 +976,15 @@ func WorkspaceBuildParameters(t testing.TB, db database.Store, orig []database.W
 	}
 
 	var (
-		names  = make([]string, 0, len(orig))
-		values = make([]string, 0, len(orig))
-		params []database.WorkspaceBuildParameter
+		names     = make([]string, 0, len(orig))
+		values    = make([]string, 0, len(orig))
+		sensitive = make([]bool, 0, len(orig))
+		params    []database.WorkspaceBuildParameter
 	)
 	for _, param := range orig {
 		names = append(names, param.Name)
 		values = append(values, param.Value)
+		sensitive = append(sensitive, param.Sensitive)
 	}
 	err := db.InTx(func(tx database.Store) error {
 		id := takeFirst(orig[0].WorkspaceBuildID, uuid.New())
@@ -969,6 +992,7 @@ func WorkspaceBuildParameters(t testing.TB, db database.Store, orig []database.W
 			WorkspaceBuildID: id,
 			Name:             names,
 			Value:            values,
+			Sensitive:        sensitive,
 		})
 		if err != nil {
 			return err
@@ -1369,6 +1393,9 @@ func WorkspaceApp(t testing.TB, db database.Store, orig database.WorkspaceApp) d
 		Hidden:               orig.Hidden,
 		OpenIn:               takeFirst(orig.OpenIn, database.WorkspaceAppOpenInSlimWindow),
 		Tooltip:              takeFirst(orig.Tooltip, testutil.GetRandomName(t)),
+
+		MaxSessionDurationSeconds: orig.MaxSessionDurationSeconds,
+		SessionIdleTimeoutSeconds: orig.SessionIdleTimeoutSeconds,
 	})
 	require.NoError(t, err, "insert app")
 	return resource
@@ -1639,6 +1666,9 @@ func TemplateVersionParameter(t testing.TB, db database.Store, orig database.Tem
 		DisplayName:         takeFirst(orig.DisplayName, testutil.GetRandomName(t)),
 		DisplayOrder:        takeFirst(orig.DisplayOrder, 0),
 		Ephemeral:           takeFirst(orig.Ephemeral, false),
+		GroupName:           orig.GroupName,
+		GroupOrder:          orig.GroupOrder,
+		Page:                orig.Page,
 	})
 	require.NoError(t, err, "insert template version parameter")
 	return version
@@ -70,6 +70,54 @@ type queryMetricsStore struct {
 	dbMetrics      *metricsStore
 }
 
+func (m queryMetricsStore) CountRunningProvisionerJobsByWorkerID(ctx context.Context, workerID uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountRunningProvisionerJobsByWorkerID(ctx, workerID)
+	m.queryLatencies.WithLabelValues("CountRunningProvisionerJobsByWorkerID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "CountRunningProvisionerJobsByWorkerID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteUserSchedulePause(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserSchedulePause(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserSchedulePause").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteUserSchedulePause").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) GetUserSchedulePause(ctx context.Context, userID uuid.UUID) (database.UserSchedulePause, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserSchedulePause(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserSchedulePause").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUserSchedulePause").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) RotateProvisionerKey(ctx context.Context, arg database.RotateProvisionerKeyParams) (database.ProvisionerKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.RotateProvisionerKey(ctx, arg)
+	m.queryLatencies.WithLabelValues("RotateProvisionerKey").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "RotateProvisionerKey").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg database.UpdateProvisionerKeyLastUsedAtParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateProvisionerKeyLastUsedAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateProvisionerKeyLastUsedAt").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateProvisionerKeyLastUsedAt").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) UpsertUserSchedulePause(ctx context.Context, arg database.UpsertUserSchedulePauseParams) (database.UserSchedulePause, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserSchedulePause(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserSchedulePause").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertUserSchedulePause").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) Wrappers() []string {
 	return append(m.s.Wrappers(), wrapname)
 }
@@ -105,6 +153,14 @@ func (m queryMetricsStore) DeleteOrganization(ctx context.Context, id uuid.UUID)
 	return r0
 }
 
+func (m queryMetricsStore) AccrueWorkspaceEstimatedCosts(ctx context.Context, arg database.AccrueWorkspaceEstimatedCostsParams) error {
+	start := time.Now()
+	r0 := m.s.AccrueWorkspaceEstimatedCosts(ctx, arg)
+	m.queryLatencies.WithLabelValues("AccrueWorkspaceEstimatedCosts").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "AccrueWorkspaceEstimatedCosts").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error {
 	start := time.Now()
 	r0 := m.s.AcquireLock(ctx, pgAdvisoryXactLock)
@@ -281,6 +337,22 @@ func (m queryMetricsStore) ClaimPrebuiltWorkspace(ctx context.Context, arg datab
 	return r0, r1
 }
 
+func (m queryMetricsStore) ClaimTemplateVersionScans(ctx context.Context, arg database.ClaimTemplateVersionScansParams) ([]database.TemplateVersionScan, error) {
+	start := time.Now()
+	r0, r1 := m.s.ClaimTemplateVersionScans(ctx, arg)
+	m.queryLatencies.WithLabelValues("ClaimTemplateVersionScans").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "ClaimTemplateVersionScans").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) ClaimWorkspaceDormancyHooks(ctx context.Context, arg database.ClaimWorkspaceDormancyHooksParams) ([]database.WorkspaceDormancyHook, error) {
+	start := time.Now()
+	r0, r1 := m.s.ClaimWorkspaceDormancyHooks(ctx, arg)
+	m.queryLatencies.WithLabelValues("ClaimWorkspaceDormancyHooks").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "ClaimWorkspaceDormancyHooks").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) CleanTailnetCoordinators(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.CleanTailnetCoordinators(ctx)
@@ -321,6 +393,14 @@ func (m queryMetricsStore) CountAIBridgeSessions(ctx context.Context, arg databa
 	return r0, r1
 }
 
+func (m queryMetricsStore) CountActiveWorkspaceLeasesByAPIKeyID(ctx context.Context, apiKeyID string) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountActiveWorkspaceLeasesByAPIKeyID(ctx, apiKeyID)
+	m.queryLatencies.WithLabelValues("CountActiveWorkspaceLeasesByAPIKeyID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "CountActiveWorkspaceLeasesByAPIKeyID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) CountAuditLogs(ctx context.Context, arg database.CountAuditLogsParams) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.CountAuditLogs(ctx, arg)
@@ -585,6 +665,14 @@ func (m queryMetricsStore) DeleteExpiredAPIKeys(ctx context.Context, arg databas
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteExpiredIdempotencyKeys(ctx, before)
+	m.queryLatencies.WithLabelValues("DeleteExpiredIdempotencyKeys").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteExpiredIdempotencyKeys").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteExternalAuthLink(ctx, arg)
@@ -641,6 +729,14 @@ func (m queryMetricsStore) DeleteMCPServerUserToken(ctx context.Context, arg dat
 	return r0
 }
 
+func (m queryMetricsStore) DeleteNotificationTemplateWebhook(ctx context.Context, notificationTemplateID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteNotificationTemplateWebhook(ctx, notificationTemplateID)
+	m.queryLatencies.WithLabelValues("DeleteNotificationTemplateWebhook").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteNotificationTemplateWebhook").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) DeleteOAuth2ProviderAppByClientID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppByClientID(ctx, id)
@@ -793,6 +889,30 @@ func (m queryMetricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, thre
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteOldWorkspaceAgentMemoryEvents(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceAgentMemoryEvents(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentMemoryEvents").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteOldWorkspaceAgentMemoryEvents").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, arg database.DeleteOldWorkspaceAgentMetadataHistoryParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOldWorkspaceAgentMetadataHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentMetadataHistory").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteOldWorkspaceAgentMetadataHistory").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteOldWorkspaceAgentNetworkPolicyViolations(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceAgentNetworkPolicyViolations(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentNetworkPolicyViolations").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteOldWorkspaceAgentNetworkPolicyViolations").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldWorkspaceAgentStats(ctx)
@@ -817,6 +937,14 @@ func (m queryMetricsStore) DeleteOrganizationMember(ctx context.Context, arg dat
 	return r0
 }
 
+func (m queryMetricsStore) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteProvisionerJobLogsByJobID(ctx, jobID)
+	m.queryLatencies.WithLabelValues("DeleteProvisionerJobLogsByJobID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteProvisionerJobLogsByJobID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteProvisionerKey(ctx, id)
@@ -881,6 +1009,38 @@ func (m queryMetricsStore) DeleteTask(ctx context.Context, arg database.DeleteTa
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateNetworkPolicy(ctx, templateID)
+	m.queryLatencies.WithLabelValues("DeleteTemplateNetworkPolicy").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteTemplateNetworkPolicy").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplatePresetCohortsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("DeleteTemplatePresetCohortsByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteTemplatePresetCohortsByTemplateID").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.DeleteTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteTemplateSecretByTemplateIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTemplateSecretByTemplateIDAndName").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteTemplateSecretByTemplateIDAndName").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteTemplateUserPresetByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateUserPresetByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteTemplateUserPresetByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteTemplateUserPresetByID").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) DeleteUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteUserAIBudgetOverride(ctx, userID)
@@ -913,6 +1073,14 @@ func (m queryMetricsStore) DeleteUserChatCompactionThreshold(ctx context.Context
 	return r0
 }
 
+func (m queryMetricsStore) DeleteUserNotificationScopedPreferences(ctx context.Context, arg database.DeleteUserNotificationScopedPreferencesParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteUserNotificationScopedPreferences(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteUserNotificationScopedPreferences").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteUserNotificationScopedPreferences").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) DeleteUserSecretByUserIDAndName(ctx context.Context, arg database.DeleteUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteUserSecretByUserIDAndName(ctx, arg)
@@ -929,6 +1097,14 @@ func (m queryMetricsStore) DeleteUserSkillByUserIDAndName(ctx context.Context, a
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteVCSEventMappingByTemplateIDAndID(ctx context.Context, arg database.DeleteVCSEventMappingByTemplateIDAndIDParams) (database.VCSEventMapping, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteVCSEventMappingByTemplateIDAndID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteVCSEventMappingByTemplateIDAndID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteVCSEventMappingByTemplateIDAndID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx, arg)
@@ -961,6 +1137,14 @@ func (m queryMetricsStore) DeleteWorkspaceACLsByOrganization(ctx context.Context
 	return r0
 }
 
+func (m queryMetricsStore) DeleteWorkspaceAgentCrashLoop(ctx context.Context, agentID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceAgentCrashLoop(ctx, agentID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceAgentCrashLoop").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteWorkspaceAgentCrashLoop").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteWorkspaceAgentPortShare(ctx, arg)
@@ -977,6 +1161,46 @@ func (m queryMetricsStore) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.
 	return r0
 }
 
+func (m queryMetricsStore) DeleteWorkspaceDNSRecordByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceDNSRecordByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceDNSRecordByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteWorkspaceDNSRecordByWorkspaceID").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceDormancyExemption(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceDormancyExemption(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceDormancyExemption").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteWorkspaceDormancyExemption").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceNetworkPolicy(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceNetworkPolicy").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteWorkspaceNetworkPolicy").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspacePortForwardACL(ctx context.Context, arg database.DeleteWorkspacePortForwardACLParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspacePortForwardACL(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteWorkspacePortForwardACL").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteWorkspacePortForwardACL").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspacePortForwardACLsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspacePortForwardACLsByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteWorkspacePortForwardACLsByWorkspaceID").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteWorkspaceSubAgentByID(ctx, id)
@@ -985,6 +1209,22 @@ func (m queryMetricsStore) DeleteWorkspaceSubAgentByID(ctx context.Context, id u
 	return r0
 }
 
+func (m queryMetricsStore) DetachSharedVolumeAttachmentByID(ctx context.Context, arg database.DetachSharedVolumeAttachmentByIDParams) (database.SharedVolumeAttachment, error) {
+	start := time.Now()
+	r0, r1 := m.s.DetachSharedVolumeAttachmentByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DetachSharedVolumeAttachmentByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DetachSharedVolumeAttachmentByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) DetachSharedVolumeAttachmentsByWorkspaceID(ctx context.Context, arg database.DetachSharedVolumeAttachmentsByWorkspaceIDParams) error {
+	start := time.Now()
+	r0 := m.s.DetachSharedVolumeAttachmentsByWorkspaceID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DetachSharedVolumeAttachmentsByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DetachSharedVolumeAttachmentsByWorkspaceID").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DisableForeignKeysAndTriggers(ctx)
@@ -1273,6 +1513,30 @@ func (m queryMetricsStore) GetActivePresetPrebuildSchedules(ctx context.Context)
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetActiveSharedVolumeAttachmentsBySharedVolumeID(ctx context.Context, sharedVolumeID uuid.UUID) ([]database.GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActiveSharedVolumeAttachmentsBySharedVolumeID(ctx, sharedVolumeID)
+	m.queryLatencies.WithLabelValues("GetActiveSharedVolumeAttachmentsBySharedVolumeID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetActiveSharedVolumeAttachmentsBySharedVolumeID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetActiveTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActiveTemplateVersionRolloutByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetActiveTemplateVersionRolloutByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetActiveTemplateVersionRolloutByTemplateID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetActiveTemplateVersionRollouts(ctx context.Context) ([]database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActiveTemplateVersionRollouts(ctx)
+	m.queryLatencies.WithLabelValues("GetActiveTemplateVersionRollouts").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetActiveTemplateVersionRollouts").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetActiveUserCount(ctx, includeSystem)
@@ -1369,6 +1633,14 @@ func (m queryMetricsStore) GetAutoArchiveInactiveChatCandidates(ctx context.Cont
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetAutostopInsights(ctx context.Context, arg database.GetAutostopInsightsParams) ([]database.GetAutostopInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAutostopInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAutostopInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetAutostopInsights").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetBoundaryLogByID(ctx context.Context, id uuid.UUID) (database.BoundaryLog, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetBoundaryLogByID(ctx, id)
@@ -2049,6 +2321,14 @@ func (m queryMetricsStore) GetEnabledMCPServerConfigs(ctx context.Context) ([]da
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetExpiredWorkspaceLeases(ctx context.Context, arg database.GetExpiredWorkspaceLeasesParams) ([]database.WorkspaceLease, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetExpiredWorkspaceLeases(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetExpiredWorkspaceLeases").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetExpiredWorkspaceLeases").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetExternalAgentTokensByTemplateID(ctx context.Context, arg database.GetExternalAgentTokensByTemplateIDParams) ([]database.GetExternalAgentTokensByTemplateIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetExternalAgentTokensByTemplateID(ctx, arg)
@@ -2225,6 +2505,22 @@ func (m queryMetricsStore) GetHighestGroupAIBudgetByUser(ctx context.Context, us
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetIdempotencyKey(ctx context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetIdempotencyKey(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetIdempotencyKey").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetIdempotencyKey").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetInProgressTemplateWorkspaceRestarts(ctx context.Context) ([]database.TemplateWorkspaceRestart, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetInProgressTemplateWorkspaceRestarts(ctx)
+	m.queryLatencies.WithLabelValues("GetInProgressTemplateWorkspaceRestarts").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetInProgressTemplateWorkspaceRestarts").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (database.InboxNotification, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetInboxNotificationByID(ctx, id)
@@ -2265,6 +2561,22 @@ func (m queryMetricsStore) GetLatestCryptoKeyByFeature(ctx context.Context, feat
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetLatestUserWorkspaceReportByUserID(ctx context.Context, userID uuid.UUID) (database.UserWorkspaceReport, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestUserWorkspaceReportByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetLatestUserWorkspaceReportByUserID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetLatestUserWorkspaceReportByUserID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentBootstrapProgress, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetLatestWorkspaceAgentBootstrapProgressByAgentIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetLatestWorkspaceAgentBootstrapProgressByAgentIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetLatestWorkspaceAgentContextSnapshot(ctx context.Context, workspaceAgentID uuid.UUID) (database.WorkspaceAgentContextSnapshot, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetLatestWorkspaceAgentContextSnapshot(ctx, workspaceAgentID)
@@ -2273,6 +2585,14 @@ func (m queryMetricsStore) GetLatestWorkspaceAgentContextSnapshot(ctx context.Co
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentServiceTransition, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetLatestWorkspaceAgentServiceTransitionsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetLatestWorkspaceAgentServiceTransitionsByAgentIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetLatestWorkspaceAppStatusByAppID(ctx context.Context, appID uuid.UUID) (database.WorkspaceAppStatus, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetLatestWorkspaceAppStatusByAppID(ctx, appID)
@@ -2313,6 +2633,14 @@ func (m queryMetricsStore) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Co
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetLeakedWorkspaceCloudResources(ctx context.Context) ([]database.GetLeakedWorkspaceCloudResourcesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLeakedWorkspaceCloudResources(ctx)
+	m.queryLatencies.WithLabelValues("GetLeakedWorkspaceCloudResources").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetLeakedWorkspaceCloudResources").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetLicenseByID(ctx context.Context, id int32) (database.License, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetLicenseByID(ctx, id)
@@ -2401,6 +2729,14 @@ func (m queryMetricsStore) GetNotificationMessagesByStatus(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetNotificationMessagesByTemplateID(ctx context.Context, arg database.GetNotificationMessagesByTemplateIDParams) ([]database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationMessagesByTemplateID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetNotificationMessagesByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetNotificationMessagesByTemplateID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetNotificationReportGeneratorLogByTemplate(ctx context.Context, templateID uuid.UUID) (database.NotificationReportGeneratorLog, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationReportGeneratorLogByTemplate(ctx, templateID)
@@ -2417,6 +2753,14 @@ func (m queryMetricsStore) GetNotificationTemplateByID(ctx context.Context, id u
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetNotificationTemplateWebhookByTemplateID(ctx context.Context, notificationTemplateID uuid.UUID) (database.NotificationTemplateWebhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationTemplateWebhookByTemplateID(ctx, notificationTemplateID)
+	m.queryLatencies.WithLabelValues("GetNotificationTemplateWebhookByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetNotificationTemplateWebhookByTemplateID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetNotificationTemplatesByKind(ctx context.Context, kind database.NotificationTemplateKind) ([]database.NotificationTemplate, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationTemplatesByKind(ctx, kind)
@@ -2593,6 +2937,14 @@ func (m queryMetricsStore) GetOrganizationsWithPrebuildStatus(ctx context.Contex
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetOrphanedWorkspaces(ctx context.Context) ([]database.GetOrphanedWorkspacesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrphanedWorkspaces(ctx)
+	m.queryLatencies.WithLabelValues("GetOrphanedWorkspaces").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetOrphanedWorkspaces").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetParameterSchemasByJobID(ctx, jobID)
@@ -2601,6 +2953,14 @@ func (m queryMetricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetPrebuildClaimInsights(ctx context.Context, arg database.GetPrebuildClaimInsightsParams) ([]database.GetPrebuildClaimInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPrebuildClaimInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetPrebuildClaimInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetPrebuildClaimInsights").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPrebuildMetrics(ctx)
@@ -2649,6 +3009,14 @@ func (m queryMetricsStore) GetPresetParametersByTemplateVersionID(ctx context.Co
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetPresetPrebuildPoolState(ctx context.Context, arg database.GetPresetPrebuildPoolStateParams) (database.GetPresetPrebuildPoolStateRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPresetPrebuildPoolState(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetPresetPrebuildPoolState").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetPresetPrebuildPoolState").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetPresetsAtFailureLimit(ctx context.Context, hardLimit int64) ([]database.GetPresetsAtFailureLimitRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPresetsAtFailureLimit(ctx, hardLimit)
@@ -2681,6 +3049,14 @@ func (m queryMetricsStore) GetPreviousTemplateVersion(ctx context.Context, arg d
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetProvisionerCacheSettings(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerCacheSettings(ctx)
+	m.queryLatencies.WithLabelValues("GetProvisionerCacheSettings").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetProvisionerCacheSettings").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetProvisionerDaemons(ctx context.Context) ([]database.ProvisionerDaemon, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerDaemons(ctx)
@@ -2729,6 +3105,30 @@ func (m queryMetricsStore) GetProvisionerJobByIDWithLock(ctx context.Context, id
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetProvisionerJobErrorLogsByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]database.ProvisionerJobLog, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobErrorLogsByJobIDs(ctx, jobIds)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobErrorLogsByJobIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetProvisionerJobErrorLogsByJobIDs").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobLogArchiveByJobID(ctx, jobID)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobLogArchiveByJobID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetProvisionerJobLogArchiveByJobID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetProvisionerJobLogArchivesByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]database.ProvisionerJobLogArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobLogArchivesByJobIDs(ctx, jobIds)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobLogArchivesByJobIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetProvisionerJobLogArchivesByJobIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobTimingsByJobID(ctx, jobID)
@@ -2745,6 +3145,14 @@ func (m queryMetricsStore) GetProvisionerJobsByIDsWithQueuePosition(ctx context.
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetProvisionerJobsByInitiatorWithQueuePosition(ctx context.Context, initiatorID uuid.UUID) ([]database.GetProvisionerJobsByInitiatorWithQueuePositionRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobsByInitiatorWithQueuePosition(ctx, initiatorID)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobsByInitiatorWithQueuePosition").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetProvisionerJobsByInitiatorWithQueuePosition").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx context.Context, arg database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerParams) ([]database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx, arg)
@@ -2769,6 +3177,14 @@ func (m queryMetricsStore) GetProvisionerJobsToBeReaped(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetProvisionerJobsWithExpiredLogs(ctx context.Context, arg database.GetProvisionerJobsWithExpiredLogsParams) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobsWithExpiredLogs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobsWithExpiredLogs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetProvisionerJobsWithExpiredLogs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerKeyByHashedSecret(ctx, hashedSecret)
@@ -2857,6 +3273,46 @@ func (m queryMetricsStore) GetRuntimeConfig(ctx context.Context, key string) (st
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetSensitiveWorkspaceBuildParameters(ctx context.Context) ([]database.WorkspaceBuildParameter, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSensitiveWorkspaceBuildParameters(ctx)
+	m.queryLatencies.WithLabelValues("GetSensitiveWorkspaceBuildParameters").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetSensitiveWorkspaceBuildParameters").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSharedVolumeAttachmentByID(ctx context.Context, id uuid.UUID) (database.SharedVolumeAttachment, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSharedVolumeAttachmentByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetSharedVolumeAttachmentByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetSharedVolumeAttachmentByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSharedVolumeByID(ctx context.Context, id uuid.UUID) (database.SharedVolume, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSharedVolumeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetSharedVolumeByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetSharedVolumeByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSharedVolumeByOrganizationIDAndName(ctx context.Context, arg database.GetSharedVolumeByOrganizationIDAndNameParams) (database.SharedVolume, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSharedVolumeByOrganizationIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetSharedVolumeByOrganizationIDAndName").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetSharedVolumeByOrganizationIDAndName").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSharedVolumesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.GetSharedVolumesByOrganizationIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSharedVolumesByOrganizationID(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetSharedVolumesByOrganizationID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetSharedVolumesByOrganizationID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetStaleChats(ctx context.Context, staleThreshold time.Time) ([]database.Chat, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetStaleChats(ctx, staleThreshold)
@@ -2945,6 +3401,14 @@ func (m queryMetricsStore) GetTelemetryTaskEvents(ctx context.Context, createdAf
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateActiveDeveloperDays(ctx context.Context, arg database.GetTemplateActiveDeveloperDaysParams) ([]database.GetTemplateActiveDeveloperDaysRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateActiveDeveloperDays(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateActiveDeveloperDays").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateActiveDeveloperDays").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateAppInsights(ctx, arg)
@@ -2985,6 +3449,22 @@ func (m queryMetricsStore) GetTemplateByOrganizationAndName(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateHealthReportsByTemplateID(ctx context.Context, arg database.GetTemplateHealthReportsByTemplateIDParams) ([]database.TemplateHealthReport, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateHealthReportsByTemplateID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateHealthReportsByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateHealthReportsByTemplateID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateHealthStats(ctx context.Context, since time.Time) ([]database.GetTemplateHealthStatsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateHealthStats(ctx, since)
+	m.queryLatencies.WithLabelValues("GetTemplateHealthStats").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateHealthStats").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateInsights(ctx, arg)
@@ -3009,6 +3489,14 @@ func (m queryMetricsStore) GetTemplateInsightsByTemplate(ctx context.Context, ar
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) (database.TemplateNetworkPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateNetworkPolicy(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateNetworkPolicy").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateNetworkPolicy").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateParameterInsights(ctx context.Context, arg database.GetTemplateParameterInsightsParams) ([]database.GetTemplateParameterInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateParameterInsights(ctx, arg)
@@ -3017,6 +3505,14 @@ func (m queryMetricsStore) GetTemplateParameterInsights(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplatePresetCohort, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplatePresetCohortsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplatePresetCohortsByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplatePresetCohortsByTemplateID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplatePresetsWithPrebuilds(ctx, templateID)
@@ -3033,19 +3529,67 @@ func (m queryMetricsStore) GetTemplateRankingSignalsByOwnerID(ctx context.Contex
 	return r0, r1
 }
 
-func (m queryMetricsStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
+func (m queryMetricsStore) GetTemplateSecretByID(ctx context.Context, id uuid.UUID) (database.TemplateSecret, error) {
 	start := time.Now()
-	r0, r1 := m.s.GetTemplateUsageStats(ctx, arg)
-	m.queryLatencies.WithLabelValues("GetTemplateUsageStats").Observe(time.Since(start).Seconds())
-	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateUsageStats").Inc()
+	r0, r1 := m.s.GetTemplateSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateSecretByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateSecretByID").Inc()
 	return r0, r1
 }
 
-func (m queryMetricsStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersion, error) {
+func (m queryMetricsStore) GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.GetTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
 	start := time.Now()
-	r0, r1 := m.s.GetTemplateVersionByID(ctx, id)
-	m.queryLatencies.WithLabelValues("GetTemplateVersionByID").Observe(time.Since(start).Seconds())
-	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateVersionByID").Inc()
+	r0, r1 := m.s.GetTemplateSecretByTemplateIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateSecretByTemplateIDAndName").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateSecretByTemplateIDAndName").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateSecrets(ctx context.Context) ([]database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateSecrets(ctx)
+	m.queryLatencies.WithLabelValues("GetTemplateSecrets").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateSecrets").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateSecretsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateSecretsByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateSecretsByTemplateID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateUsageStats(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateUsageStats").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateUsageStats").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateUserPresetByID(ctx context.Context, id uuid.UUID) (database.TemplateUserPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateUserPresetByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateUserPresetByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateUserPresetByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateUserPresetsByTemplateIDAndUserID(ctx context.Context, arg database.GetTemplateUserPresetsByTemplateIDAndUserIDParams) ([]database.TemplateUserPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateUserPresetsByTemplateIDAndUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateUserPresetsByTemplateIDAndUserID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateUserPresetsByTemplateIDAndUserID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersion, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateVersionByID").Inc()
 	return r0, r1
 }
 
@@ -3073,6 +3617,38 @@ func (m queryMetricsStore) GetTemplateVersionParameters(ctx context.Context, tem
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateVersionRolloutBuildStats(ctx context.Context, arg database.GetTemplateVersionRolloutBuildStatsParams) (database.GetTemplateVersionRolloutBuildStatsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionRolloutBuildStats(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionRolloutBuildStats").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateVersionRolloutBuildStats").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateVersionRolloutByID(ctx context.Context, id uuid.UUID) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionRolloutByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionRolloutByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateVersionRolloutByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateVersionRolloutsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionRolloutsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionRolloutsByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateVersionRolloutsByTemplateID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateVersionScanByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionScan, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionScanByTemplateVersionID(ctx, templateVersionID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionScanByTemplateVersionID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateVersionScanByTemplateVersionID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateVersionTerraformValues(ctx, templateVersionID)
@@ -3121,6 +3697,38 @@ func (m queryMetricsStore) GetTemplateVersionsCreatedAfter(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateWorkspaceRestartBuildStats(ctx context.Context, arg database.GetTemplateWorkspaceRestartBuildStatsParams) (database.GetTemplateWorkspaceRestartBuildStatsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateWorkspaceRestartBuildStats(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateWorkspaceRestartBuildStats").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateWorkspaceRestartBuildStats").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateWorkspaceRestartByID(ctx context.Context, id uuid.UUID) (database.TemplateWorkspaceRestart, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateWorkspaceRestartByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateWorkspaceRestartByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateWorkspaceRestartByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateWorkspaceRestartCandidates(ctx context.Context, arg database.GetTemplateWorkspaceRestartCandidatesParams) ([]database.GetTemplateWorkspaceRestartCandidatesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateWorkspaceRestartCandidates(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateWorkspaceRestartCandidates").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateWorkspaceRestartCandidates").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateWorkspaceRestartsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateWorkspaceRestart, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateWorkspaceRestartsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateWorkspaceRestartsByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateWorkspaceRestartsByTemplateID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplates(ctx context.Context) ([]database.Template, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplates(ctx)
@@ -3153,6 +3761,14 @@ func (m queryMetricsStore) GetUnexpiredLicenses(ctx context.Context) ([]database
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUnverifiedWorkspaceCloudResources(ctx context.Context, arg database.GetUnverifiedWorkspaceCloudResourcesParams) ([]database.GetUnverifiedWorkspaceCloudResourcesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUnverifiedWorkspaceCloudResources(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUnverifiedWorkspaceCloudResources").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUnverifiedWorkspaceCloudResources").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserAIBudgetOverride(ctx, userID)
@@ -3321,6 +3937,30 @@ func (m queryMetricsStore) GetUserGroupSpendLimit(ctx context.Context, userID da
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserImpersonationByAPIKeyID(ctx context.Context, apiKeyID string) (database.UserImpersonation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserImpersonationByAPIKeyID(ctx, apiKeyID)
+	m.queryLatencies.WithLabelValues("GetUserImpersonationByAPIKeyID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUserImpersonationByAPIKeyID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserImpersonationByID(ctx context.Context, id uuid.UUID) (database.UserImpersonation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserImpersonationByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetUserImpersonationByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUserImpersonationByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserImpersonationsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserImpersonation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserImpersonationsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserImpersonationsByUserID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUserImpersonationsByUserID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserLatencyInsights(ctx, arg)
@@ -3361,6 +4001,14 @@ func (m queryMetricsStore) GetUserNotificationPreferences(ctx context.Context, u
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserNotificationScopedPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationScopedPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserNotificationScopedPreferences(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserNotificationScopedPreferences").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUserNotificationScopedPreferences").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserSecretByID(ctx context.Context, id uuid.UUID) (database.UserSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserSecretByID(ctx, id)
@@ -3433,6 +4081,22 @@ func (m queryMetricsStore) GetUserWorkspaceBuildParameters(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserWorkspaceReportStats(ctx context.Context, arg database.GetUserWorkspaceReportStatsParams) ([]database.GetUserWorkspaceReportStatsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserWorkspaceReportStats(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserWorkspaceReportStats").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUserWorkspaceReportStats").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, userID uuid.UUID) (bool, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserWorkspaceSelfRequestedBuildsAllowed(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserWorkspaceSelfRequestedBuildsAllowed").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUserWorkspaceSelfRequestedBuildsAllowed").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUsers(ctx, arg)
@@ -3449,6 +4113,38 @@ func (m queryMetricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) (
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetVCSEventMappingByID(ctx context.Context, id uuid.UUID) (database.VCSEventMapping, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetVCSEventMappingByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetVCSEventMappingByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetVCSEventMappingByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetVCSEventMappings(ctx context.Context) ([]database.VCSEventMapping, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetVCSEventMappings(ctx)
+	m.queryLatencies.WithLabelValues("GetVCSEventMappings").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetVCSEventMappings").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetVCSEventMappingsByRepository(ctx context.Context, repository string) ([]database.VCSEventMapping, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetVCSEventMappingsByRepository(ctx, repository)
+	m.queryLatencies.WithLabelValues("GetVCSEventMappingsByRepository").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetVCSEventMappingsByRepository").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetVCSEventMappingsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.VCSEventMapping, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetVCSEventMappingsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetVCSEventMappingsByTemplateID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetVCSEventMappingsByTemplateID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWebpushSubscriptionsByUserID(ctx, userID)
@@ -3481,6 +4177,14 @@ func (m queryMetricsStore) GetWorkspaceAgentAndWorkspaceByID(ctx context.Context
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentBootstrapProgressByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentBootstrapProgress, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentBootstrapProgressByAgentID(ctx, workspaceAgentID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentBootstrapProgressByAgentID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentBootstrapProgressByAgentID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentByID(ctx, id)
@@ -3489,6 +4193,22 @@ func (m queryMetricsStore) GetWorkspaceAgentByID(ctx context.Context, id uuid.UU
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentCrashLoopByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentCrashLoop, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentCrashLoopByAgentID(ctx, agentID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentCrashLoopByAgentID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentCrashLoopByAgentID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAgentCrashLoopsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentCrashLoop, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentCrashLoopsByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentCrashLoopsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentCrashLoopsByAgentIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentDevcontainersByAgentID(ctx, workspaceAgentID)
@@ -3521,6 +4241,22 @@ func (m queryMetricsStore) GetWorkspaceAgentLogsAfter(ctx context.Context, arg d
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentMemoryEventCountsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentMemoryEventCountsByAgentIDsParams) ([]database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentMemoryEventCountsByAgentIDs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentMemoryEventCountsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentMemoryEventCountsByAgentIDs").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAgentMemoryEventsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentMemoryEventsByAgentIDsParams) ([]database.WorkspaceAgentMemoryEvent, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentMemoryEventsByAgentIDs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentMemoryEventsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentMemoryEventsByAgentIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentMetadata(ctx context.Context, arg database.GetWorkspaceAgentMetadataParams) ([]database.WorkspaceAgentMetadatum, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentMetadata(ctx, arg)
@@ -3529,6 +4265,30 @@ func (m queryMetricsStore) GetWorkspaceAgentMetadata(ctx context.Context, arg da
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.GetWorkspaceAgentMetadataHistoryRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentMetadataHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentMetadataHistory").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentMetadataHistory").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsParams) ([]database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDsParams) ([]database.WorkspaceAgentNetworkPolicyViolation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentPortShare(ctx, arg)
@@ -3553,6 +4313,22 @@ func (m queryMetricsStore) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Contex
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentServiceTransition, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentServiceTransitionsByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentServiceTransitionsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentServiceTransitionsByAgentIDs").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAgentServicesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentService, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentServicesByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentServicesByAgentIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentServicesByAgentIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentStats(ctx, createdAt)
@@ -3657,6 +4433,22 @@ func (m queryMetricsStore) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAppShareLinkByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAppShareLink, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAppShareLinkByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAppShareLinkByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAppShareLinkByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAppShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAppShareLink, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAppShareLinksByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAppShareLinksByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAppShareLinksByWorkspaceID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAppStatusesByAppIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAppStatus, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAppStatusesByAppIDs(ctx, ids)
@@ -3697,6 +4489,14 @@ func (m queryMetricsStore) GetWorkspaceBuildAgentsByInstanceID(ctx context.Conte
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceBuildAnnotationsByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]database.GetWorkspaceBuildAnnotationsByBuildIDsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildAnnotationsByBuildIDs(ctx, workspaceBuildIds)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBuildAnnotationsByBuildIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceBuildAnnotationsByBuildIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceBuildByID(ctx, id)
@@ -3721,6 +4521,14 @@ func (m queryMetricsStore) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx cont
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceBuildInputsByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildInput, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildInputsByBuildID(ctx, workspaceBuildID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBuildInputsByBuildID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceBuildInputsByBuildID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceBuildMetricsByResourceID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceBuildMetricsByResourceIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceBuildMetricsByResourceID(ctx, id)
@@ -3729,6 +4537,14 @@ func (m queryMetricsStore) GetWorkspaceBuildMetricsByResourceID(ctx context.Cont
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceBuildParameterChangesByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]database.WorkspaceBuildParameterChange, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildParameterChangesByBuildIDs(ctx, workspaceBuildIds)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBuildParameterChangesByBuildIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceBuildParameterChangesByBuildIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceBuildParameters(ctx, workspaceBuildID)
@@ -3809,6 +4625,70 @@ func (m queryMetricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, wor
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceCloudResourcesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceCloudResource, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCloudResourcesByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCloudResourcesByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceCloudResourcesByWorkspaceID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceDNSRecordChanges(ctx context.Context, arg database.GetWorkspaceDNSRecordChangesParams) ([]database.GetWorkspaceDNSRecordChangesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceDNSRecordChanges(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceDNSRecordChanges").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceDNSRecordChanges").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceDNSRecordsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceDNSRecord, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceDNSRecordsByWorkspaceIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceDNSRecordsByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceDNSRecordsByWorkspaceIDs").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceDormancyExemptionByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceDormancyExemption, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceDormancyExemptionByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceDormancyExemptionByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceDormancyExemptionByWorkspaceID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceDormancyHookByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceDormancyHook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceDormancyHookByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceDormancyHookByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceDormancyHookByWorkspaceID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceEgressInsights(ctx context.Context, arg database.GetWorkspaceEgressInsightsParams) ([]database.GetWorkspaceEgressInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceEgressInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceEgressInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceEgressInsights").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceLeaseByID(ctx context.Context, id uuid.UUID) (database.WorkspaceLease, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceLeaseByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceLeaseByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceLeaseByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceLifetimeWarningByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceLifetimeWarning, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceLifetimeWarningByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceLifetimeWarningByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceLifetimeWarningByWorkspaceID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceModulesByJobID(ctx, jobID)
@@ -3825,6 +4705,38 @@ func (m queryMetricsStore) GetWorkspaceModulesCreatedAfter(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceMonthlyCost(ctx context.Context, arg database.GetWorkspaceMonthlyCostParams) (database.WorkspaceMonthlyCost, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceMonthlyCost(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceMonthlyCost").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceMonthlyCost").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceNetworkPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceNetworkPolicy(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceNetworkPolicy").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceNetworkPolicy").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspacePortForwardACL(ctx context.Context, arg database.GetWorkspacePortForwardACLParams) (database.WorkspacePortForwardACL, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePortForwardACL(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspacePortForwardACL").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspacePortForwardACL").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortForwardACL, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePortForwardACLsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspacePortForwardACLsByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspacePortForwardACLsByWorkspaceID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceProxies(ctx)
@@ -3857,6 +4769,22 @@ func (m queryMetricsStore) GetWorkspaceProxyByName(ctx context.Context, name str
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceRegionAssignmentCounts(ctx context.Context) ([]database.GetWorkspaceRegionAssignmentCountsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceRegionAssignmentCounts(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspaceRegionAssignmentCounts").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceRegionAssignmentCounts").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceRegionAssignmentsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceRegionAssignment, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceRegionAssignmentsByWorkspaceIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceRegionAssignmentsByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceRegionAssignmentsByWorkspaceIDs").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceResourceByID(ctx, id)
@@ -3945,6 +4873,14 @@ func (m queryMetricsStore) GetWorkspacesEligibleForLifecycleAction(ctx context.C
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspacesExceedingCostBudget(ctx context.Context, arg database.GetWorkspacesExceedingCostBudgetParams) ([]database.GetWorkspacesExceedingCostBudgetRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacesExceedingCostBudget(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspacesExceedingCostBudget").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspacesExceedingCostBudget").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspacesForWorkspaceMetrics(ctx context.Context) ([]database.GetWorkspacesForWorkspaceMetricsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspacesForWorkspaceMetrics(ctx)
@@ -4241,6 +5177,14 @@ func (m queryMetricsStore) InsertGroupMember(ctx context.Context, arg database.I
 	return r0
 }
 
+func (m queryMetricsStore) InsertIdempotencyKey(ctx context.Context, arg database.InsertIdempotencyKeyParams) (database.IdempotencyKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertIdempotencyKey(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertIdempotencyKey").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertIdempotencyKey").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertInboxNotification(ctx, arg)
@@ -4329,6 +5273,14 @@ func (m queryMetricsStore) InsertOrganizationMember(ctx context.Context, arg dat
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertPrebuildClaimAttempt(ctx context.Context, arg database.InsertPrebuildClaimAttemptParams) (database.PrebuildClaimAttempt, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertPrebuildClaimAttempt(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertPrebuildClaimAttempt").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertPrebuildClaimAttempt").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertPreset(ctx context.Context, arg database.InsertPresetParams) (database.TemplateVersionPreset, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertPreset(ctx, arg)
@@ -4361,6 +5313,14 @@ func (m queryMetricsStore) InsertProvisionerJob(ctx context.Context, arg databas
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertProvisionerJobLogArchive(ctx context.Context, arg database.InsertProvisionerJobLogArchiveParams) error {
+	start := time.Now()
+	r0 := m.s.InsertProvisionerJobLogArchive(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertProvisionerJobLogArchive").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertProvisionerJobLogArchive").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertProvisionerJobLogs(ctx, arg)
@@ -4393,6 +5353,22 @@ func (m queryMetricsStore) InsertReplica(ctx context.Context, arg database.Inser
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertSharedVolume(ctx context.Context, arg database.InsertSharedVolumeParams) (database.SharedVolume, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertSharedVolume(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSharedVolume").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertSharedVolume").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertSharedVolumeAttachment(ctx context.Context, arg database.InsertSharedVolumeAttachmentParams) (database.SharedVolumeAttachment, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertSharedVolumeAttachment(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSharedVolumeAttachment").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertSharedVolumeAttachment").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertTask(ctx context.Context, arg database.InsertTaskParams) (database.TaskTable, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertTask(ctx, arg)
@@ -4425,6 +5401,30 @@ func (m queryMetricsStore) InsertTemplate(ctx context.Context, arg database.Inse
 	return r0
 }
 
+func (m queryMetricsStore) InsertTemplateHealthReport(ctx context.Context, arg database.InsertTemplateHealthReportParams) (database.TemplateHealthReport, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateHealthReport(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateHealthReport").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertTemplateHealthReport").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertTemplatePresetCohort(ctx context.Context, arg database.InsertTemplatePresetCohortParams) (database.TemplatePresetCohort, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplatePresetCohort(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplatePresetCohort").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertTemplatePresetCohort").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertTemplateSecret(ctx context.Context, arg database.InsertTemplateSecretParams) (database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateSecret").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertTemplateSecret").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	start := time.Now()
 	r0 := m.s.InsertTemplateVersion(ctx, arg)
@@ -4441,6 +5441,14 @@ func (m queryMetricsStore) InsertTemplateVersionParameter(ctx context.Context, a
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertTemplateVersionRollout(ctx context.Context, arg database.InsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateVersionRollout(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateVersionRollout").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertTemplateVersionRollout").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg database.InsertTemplateVersionTerraformValuesByJobIDParams) error {
 	start := time.Now()
 	r0 := m.s.InsertTemplateVersionTerraformValuesByJobID(ctx, arg)
@@ -4465,6 +5473,22 @@ func (m queryMetricsStore) InsertTemplateVersionWorkspaceTag(ctx context.Context
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertTemplateWorkspaceRestart(ctx context.Context, arg database.InsertTemplateWorkspaceRestartParams) (database.TemplateWorkspaceRestart, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateWorkspaceRestart(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateWorkspaceRestart").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertTemplateWorkspaceRestart").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertTemplateWorkspaceRestartBuild(ctx context.Context, arg database.InsertTemplateWorkspaceRestartBuildParams) error {
+	start := time.Now()
+	r0 := m.s.InsertTemplateWorkspaceRestartBuild(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateWorkspaceRestartBuild").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertTemplateWorkspaceRestartBuild").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) InsertUsageEvent(ctx context.Context, arg database.InsertUsageEventParams) error {
 	start := time.Now()
 	r0 := m.s.InsertUsageEvent(ctx, arg)
@@ -4489,6 +5513,14 @@ func (m queryMetricsStore) InsertUserGroupsByID(ctx context.Context, arg databas
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertUserImpersonation(ctx context.Context, arg database.InsertUserImpersonationParams) (database.UserImpersonation, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertUserImpersonation(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserImpersonation").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertUserImpersonation").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertUserLink(ctx context.Context, arg database.InsertUserLinkParams) (database.UserLink, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertUserLink(ctx, arg)
@@ -4505,6 +5537,22 @@ func (m queryMetricsStore) InsertUserSkill(ctx context.Context, arg database.Ins
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertUserWorkspaceReport(ctx context.Context, arg database.InsertUserWorkspaceReportParams) (database.UserWorkspaceReport, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertUserWorkspaceReport(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserWorkspaceReport").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertUserWorkspaceReport").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertVCSEventMapping(ctx context.Context, arg database.InsertVCSEventMappingParams) (database.VCSEventMapping, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertVCSEventMapping(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertVCSEventMapping").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertVCSEventMapping").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertVolumeResourceMonitor(ctx, arg)
@@ -4537,6 +5585,14 @@ func (m queryMetricsStore) InsertWorkspaceAgent(ctx context.Context, arg databas
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWorkspaceAgentBootstrapProgress(ctx context.Context, arg database.InsertWorkspaceAgentBootstrapProgressParams) (database.WorkspaceAgentBootstrapProgress, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentBootstrapProgress(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentBootstrapProgress").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceAgentBootstrapProgress").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceAgentDevcontainers(ctx context.Context, arg database.InsertWorkspaceAgentDevcontainersParams) ([]database.WorkspaceAgentDevcontainer, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentDevcontainers(ctx, arg)
@@ -4561,6 +5617,14 @@ func (m queryMetricsStore) InsertWorkspaceAgentLogs(ctx context.Context, arg dat
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWorkspaceAgentMemoryEvent(ctx context.Context, arg database.InsertWorkspaceAgentMemoryEventParams) (database.WorkspaceAgentMemoryEvent, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentMemoryEvent(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentMemoryEvent").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceAgentMemoryEvent").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg database.InsertWorkspaceAgentMetadataParams) error {
 	start := time.Now()
 	r0 := m.s.InsertWorkspaceAgentMetadata(ctx, arg)
@@ -4569,6 +5633,22 @@ func (m queryMetricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg
 	return r0
 }
 
+func (m queryMetricsStore) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceAgentMetadataHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentMetadataHistory").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceAgentMetadataHistory").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) InsertWorkspaceAgentNetworkPolicyViolations(ctx context.Context, arg database.InsertWorkspaceAgentNetworkPolicyViolationsParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceAgentNetworkPolicyViolations(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentNetworkPolicyViolations").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceAgentNetworkPolicyViolations").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentScriptTimings(ctx, arg)
@@ -4585,6 +5665,22 @@ func (m queryMetricsStore) InsertWorkspaceAgentScripts(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWorkspaceAgentServiceTransition(ctx context.Context, arg database.InsertWorkspaceAgentServiceTransitionParams) (database.WorkspaceAgentServiceTransition, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentServiceTransition(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentServiceTransition").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceAgentServiceTransition").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceAgentServices(ctx context.Context, arg database.InsertWorkspaceAgentServicesParams) ([]database.WorkspaceAgentService, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentServices(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentServices").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceAgentServices").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceAgentStats(ctx context.Context, arg database.InsertWorkspaceAgentStatsParams) error {
 	start := time.Now()
 	r0 := m.s.InsertWorkspaceAgentStats(ctx, arg)
@@ -4593,6 +5689,14 @@ func (m queryMetricsStore) InsertWorkspaceAgentStats(ctx context.Context, arg da
 	return r0
 }
 
+func (m queryMetricsStore) InsertWorkspaceAppShareLink(ctx context.Context, arg database.InsertWorkspaceAppShareLinkParams) (database.WorkspaceAppShareLink, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAppShareLink(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAppShareLink").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceAppShareLink").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceAppStats(ctx context.Context, arg database.InsertWorkspaceAppStatsParams) error {
 	start := time.Now()
 	r0 := m.s.InsertWorkspaceAppStats(ctx, arg)
@@ -4617,6 +5721,22 @@ func (m queryMetricsStore) InsertWorkspaceBuild(ctx context.Context, arg databas
 	return r0
 }
 
+func (m queryMetricsStore) InsertWorkspaceBuildAnnotation(ctx context.Context, arg database.InsertWorkspaceBuildAnnotationParams) (database.WorkspaceBuildAnnotation, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceBuildAnnotation(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceBuildAnnotation").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceBuildAnnotation").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceBuildInputs(ctx context.Context, arg database.InsertWorkspaceBuildInputsParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceBuildInputs(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceBuildInputs").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceBuildInputs").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) InsertWorkspaceBuildOrchestration(ctx context.Context, arg database.InsertWorkspaceBuildOrchestrationParams) (database.WorkspaceBuildOrchestration, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceBuildOrchestration(ctx, arg)
@@ -4625,6 +5745,14 @@ func (m queryMetricsStore) InsertWorkspaceBuildOrchestration(ctx context.Context
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWorkspaceBuildParameterChanges(ctx context.Context, arg database.InsertWorkspaceBuildParameterChangesParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceBuildParameterChanges(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceBuildParameterChanges").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceBuildParameterChanges").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) InsertWorkspaceBuildParameters(ctx context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	start := time.Now()
 	r0 := m.s.InsertWorkspaceBuildParameters(ctx, arg)
@@ -4633,6 +5761,14 @@ func (m queryMetricsStore) InsertWorkspaceBuildParameters(ctx context.Context, a
 	return r0
 }
 
+func (m queryMetricsStore) InsertWorkspaceLease(ctx context.Context, arg database.InsertWorkspaceLeaseParams) (database.WorkspaceLease, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceLease(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceLease").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceLease").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceModule(ctx, arg)
@@ -4969,6 +6105,14 @@ func (m queryMetricsStore) RegisterWorkspaceProxy(ctx context.Context, arg datab
 	return r0, r1
 }
 
+func (m queryMetricsStore) ReleaseWorkspaceLease(ctx context.Context, arg database.ReleaseWorkspaceLeaseParams) (database.WorkspaceLease, error) {
+	start := time.Now()
+	r0, r1 := m.s.ReleaseWorkspaceLease(ctx, arg)
+	m.queryLatencies.WithLabelValues("ReleaseWorkspaceLease").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "ReleaseWorkspaceLease").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) RemoveUserFromGroups(ctx context.Context, arg database.RemoveUserFromGroupsParams) ([]uuid.UUID, error) {
 	start := time.Now()
 	r0, r1 := m.s.RemoveUserFromGroups(ctx, arg)
@@ -5001,6 +6145,14 @@ func (m queryMetricsStore) ResolveUserChatSpendLimit(ctx context.Context, userID
 	return r0, r1
 }
 
+func (m queryMetricsStore) ResumeTemplateWorkspaceRestart(ctx context.Context, arg database.ResumeTemplateWorkspaceRestartParams) (database.TemplateWorkspaceRestart, error) {
+	start := time.Now()
+	r0, r1 := m.s.ResumeTemplateWorkspaceRestart(ctx, arg)
+	m.queryLatencies.WithLabelValues("ResumeTemplateWorkspaceRestart").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "ResumeTemplateWorkspaceRestart").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	start := time.Now()
 	r0 := m.s.RevokeDBCryptKey(ctx, activeKeyDigest)
@@ -5009,6 +6161,14 @@ func (m queryMetricsStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest
 	return r0
 }
 
+func (m queryMetricsStore) RevokeWorkspaceAppShareLink(ctx context.Context, arg database.RevokeWorkspaceAppShareLinkParams) (database.WorkspaceAppShareLink, error) {
+	start := time.Now()
+	r0, r1 := m.s.RevokeWorkspaceAppShareLink(ctx, arg)
+	m.queryLatencies.WithLabelValues("RevokeWorkspaceAppShareLink").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "RevokeWorkspaceAppShareLink").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) SelectUsageEventsForPublishing(ctx context.Context, now time.Time) ([]database.UsageEvent, error) {
 	start := time.Now()
 	r0, r1 := m.s.SelectUsageEventsForPublishing(ctx, now)
@@ -5361,6 +6521,14 @@ func (m queryMetricsStore) UpdateEncryptedAIProviderSettings(ctx context.Context
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateEncryptedTemplateSecret(ctx context.Context, arg database.UpdateEncryptedTemplateSecretParams) (database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateEncryptedTemplateSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateEncryptedTemplateSecret").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateEncryptedTemplateSecret").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateEncryptedUserAIProviderKey(ctx context.Context, arg database.UpdateEncryptedUserAIProviderKeyParams) (database.UserAIProviderKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateEncryptedUserAIProviderKey(ctx, arg)
@@ -5369,6 +6537,22 @@ func (m queryMetricsStore) UpdateEncryptedUserAIProviderKey(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateEncryptedVCSEventMapping(ctx context.Context, arg database.UpdateEncryptedVCSEventMappingParams) (database.VCSEventMapping, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateEncryptedVCSEventMapping(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateEncryptedVCSEventMapping").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateEncryptedVCSEventMapping").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateEncryptedWorkspaceBuildParameter(ctx context.Context, arg database.UpdateEncryptedWorkspaceBuildParameterParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateEncryptedWorkspaceBuildParameter(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateEncryptedWorkspaceBuildParameter").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateEncryptedWorkspaceBuildParameter").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateExternalAuthLink(ctx, arg)
@@ -5489,6 +6673,14 @@ func (m queryMetricsStore) UpdateOrganizationDeletedByID(ctx context.Context, ar
 	return r0
 }
 
+func (m queryMetricsStore) UpdateOrganizationProvisionerDaemonSettings(ctx context.Context, arg database.UpdateOrganizationProvisionerDaemonSettingsParams) (database.Organization, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOrganizationProvisionerDaemonSettings(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOrganizationProvisionerDaemonSettings").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateOrganizationProvisionerDaemonSettings").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateOrganizationWorkspaceSharingSettings(ctx context.Context, arg database.UpdateOrganizationWorkspaceSharingSettingsParams) (database.Organization, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateOrganizationWorkspaceSharingSettings(ctx, arg)
@@ -5553,6 +6745,14 @@ func (m queryMetricsStore) UpdateProvisionerJobLogsOverflowed(ctx context.Contex
 	return r0
 }
 
+func (m queryMetricsStore) UpdateProvisionerJobTimeoutByID(ctx context.Context, arg database.UpdateProvisionerJobTimeoutByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateProvisionerJobTimeoutByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateProvisionerJobTimeoutByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateProvisionerJobTimeoutByID").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateProvisionerJobWithCancelByID(ctx, arg)
@@ -5577,6 +6777,14 @@ func (m queryMetricsStore) UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx
 	return r0
 }
 
+func (m queryMetricsStore) UpdateProvisionerJobWithRequeueByID(ctx context.Context, arg database.UpdateProvisionerJobWithRequeueByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateProvisionerJobWithRequeueByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateProvisionerJobWithRequeueByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateProvisionerJobWithRequeueByID").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateReplica(ctx, arg)
@@ -5657,6 +6865,14 @@ func (m queryMetricsStore) UpdateTemplateScheduleByID(ctx context.Context, arg d
 	return r0
 }
 
+func (m queryMetricsStore) UpdateTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.UpdateTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateSecretByTemplateIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateSecretByTemplateIDAndName").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateTemplateSecretByTemplateIDAndName").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTemplateVersionByID(ctx, arg)
@@ -5665,6 +6881,14 @@ func (m queryMetricsStore) UpdateTemplateVersionByID(ctx context.Context, arg da
 	return r0
 }
 
+func (m queryMetricsStore) UpdateTemplateVersionDeprecationByID(ctx context.Context, arg database.UpdateTemplateVersionDeprecationByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateTemplateVersionDeprecationByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateVersionDeprecationByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateTemplateVersionDeprecationByID").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg database.UpdateTemplateVersionDescriptionByJobIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTemplateVersionDescriptionByJobID(ctx, arg)
@@ -5689,6 +6913,46 @@ func (m queryMetricsStore) UpdateTemplateVersionFlagsByJobID(ctx context.Context
 	return r0
 }
 
+func (m queryMetricsStore) UpdateTemplateVersionRolloutStage(ctx context.Context, arg database.UpdateTemplateVersionRolloutStageParams) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateVersionRolloutStage(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateVersionRolloutStage").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateTemplateVersionRolloutStage").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateTemplateVersionRolloutStatus(ctx context.Context, arg database.UpdateTemplateVersionRolloutStatusParams) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateVersionRolloutStatus(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateVersionRolloutStatus").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateTemplateVersionRolloutStatus").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateTemplateVersionScanByTemplateVersionID(ctx context.Context, arg database.UpdateTemplateVersionScanByTemplateVersionIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateTemplateVersionScanByTemplateVersionID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateVersionScanByTemplateVersionID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateTemplateVersionScanByTemplateVersionID").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) UpdateTemplateWorkspaceRestartLastBatchAt(ctx context.Context, arg database.UpdateTemplateWorkspaceRestartLastBatchAtParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateTemplateWorkspaceRestartLastBatchAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateWorkspaceRestartLastBatchAt").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateTemplateWorkspaceRestartLastBatchAt").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) UpdateTemplateWorkspaceRestartStatus(ctx context.Context, arg database.UpdateTemplateWorkspaceRestartStatusParams) (database.TemplateWorkspaceRestart, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateWorkspaceRestartStatus(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateWorkspaceRestartStatus").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateTemplateWorkspaceRestartStatus").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTemplateWorkspacesLastUsedAt(ctx, arg)
@@ -5777,6 +7041,22 @@ func (m queryMetricsStore) UpdateUserHashedPassword(ctx context.Context, arg dat
 	return r0
 }
 
+func (m queryMetricsStore) UpdateUserImpersonationConsentedAt(ctx context.Context, arg database.UpdateUserImpersonationConsentedAtParams) (database.UserImpersonation, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserImpersonationConsentedAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserImpersonationConsentedAt").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateUserImpersonationConsentedAt").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateUserImpersonationIssued(ctx context.Context, arg database.UpdateUserImpersonationIssuedParams) (database.UserImpersonation, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserImpersonationIssued(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserImpersonationIssued").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateUserImpersonationIssued").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateUserLastSeenAt(ctx context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserLastSeenAt(ctx, arg)
@@ -5929,6 +7209,22 @@ func (m queryMetricsStore) UpdateUserThinkingDisplayMode(ctx context.Context, ar
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, arg database.UpdateUserWorkspaceSelfRequestedBuildsAllowedParams) (bool, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserWorkspaceSelfRequestedBuildsAllowed(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserWorkspaceSelfRequestedBuildsAllowed").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateUserWorkspaceSelfRequestedBuildsAllowed").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateVCSEventMappingLastTriggeredAt(ctx context.Context, arg database.UpdateVCSEventMappingLastTriggeredAtParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateVCSEventMappingLastTriggeredAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateVCSEventMappingLastTriggeredAt").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateVCSEventMappingLastTriggeredAt").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpdateVolumeResourceMonitor(ctx context.Context, arg database.UpdateVolumeResourceMonitorParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateVolumeResourceMonitor(ctx, arg)
@@ -5953,6 +7249,14 @@ func (m queryMetricsStore) UpdateWorkspaceACLByID(ctx context.Context, arg datab
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceAgentClockSkewByID(ctx context.Context, arg database.UpdateWorkspaceAgentClockSkewByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAgentClockSkewByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentClockSkewByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateWorkspaceAgentClockSkewByID").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
@@ -6017,6 +7321,14 @@ func (m queryMetricsStore) UpdateWorkspaceAppHealthByID(ctx context.Context, arg
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceArchivedAt(ctx context.Context, arg database.UpdateWorkspaceArchivedAtParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceArchivedAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceArchivedAt").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateWorkspaceArchivedAt").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceAutomaticUpdates(ctx, arg)
@@ -6105,6 +7417,14 @@ func (m queryMetricsStore) UpdateWorkspaceBuildProvisionerStateByID(ctx context.
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceCloudResourceVerification(ctx context.Context, arg database.UpdateWorkspaceCloudResourceVerificationParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceCloudResourceVerification(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceCloudResourceVerification").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateWorkspaceCloudResourceVerification").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceDeletedByID(ctx, arg)
@@ -6113,6 +7433,14 @@ func (m queryMetricsStore) UpdateWorkspaceDeletedByID(ctx context.Context, arg d
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceDormancyHookStatus(ctx context.Context, arg database.UpdateWorkspaceDormancyHookStatusParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceDormancyHookStatus(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceDormancyHookStatus").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateWorkspaceDormancyHookStatus").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateWorkspaceDormantDeletingAt(ctx, arg)
@@ -6137,6 +7465,14 @@ func (m queryMetricsStore) UpdateWorkspaceNextStartAt(ctx context.Context, arg d
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.WorkspaceTable, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceOwnerByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceOwnerByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateWorkspaceOwnerByID").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateWorkspaceProxy(ctx, arg)
@@ -6153,6 +7489,14 @@ func (m queryMetricsStore) UpdateWorkspaceProxyDeleted(ctx context.Context, arg
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceReadOnlyAt(ctx context.Context, arg database.UpdateWorkspaceReadOnlyAtParams) (database.WorkspaceTable, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceReadOnlyAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceReadOnlyAt").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateWorkspaceReadOnlyAt").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceTTL(ctx, arg)
@@ -6457,6 +7801,14 @@ func (m queryMetricsStore) UpsertNotificationReportGeneratorLog(ctx context.Cont
 	return r0
 }
 
+func (m queryMetricsStore) UpsertNotificationTemplateWebhook(ctx context.Context, arg database.UpsertNotificationTemplateWebhookParams) (database.NotificationTemplateWebhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertNotificationTemplateWebhook(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertNotificationTemplateWebhook").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertNotificationTemplateWebhook").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertNotificationsSettings(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertNotificationsSettings(ctx, value)
@@ -6481,6 +7833,14 @@ func (m queryMetricsStore) UpsertPrebuildsSettings(ctx context.Context, value st
 	return r0
 }
 
+func (m queryMetricsStore) UpsertProvisionerCacheSettings(ctx context.Context, value string) error {
+	start := time.Now()
+	r0 := m.s.UpsertProvisionerCacheSettings(ctx, value)
+	m.queryLatencies.WithLabelValues("UpsertProvisionerCacheSettings").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertProvisionerCacheSettings").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertProvisionerDaemon(ctx, arg)
@@ -6545,6 +7905,14 @@ func (m queryMetricsStore) UpsertTelemetryItem(ctx context.Context, arg database
 	return r0
 }
 
+func (m queryMetricsStore) UpsertTemplateNetworkPolicy(ctx context.Context, arg database.UpsertTemplateNetworkPolicyParams) (database.TemplateNetworkPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateNetworkPolicy(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateNetworkPolicy").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertTemplateNetworkPolicy").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.UpsertTemplateUsageStats(ctx)
@@ -6553,6 +7921,14 @@ func (m queryMetricsStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	return r0
 }
 
+func (m queryMetricsStore) UpsertTemplateUserPreset(ctx context.Context, arg database.UpsertTemplateUserPresetParams) (database.TemplateUserPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateUserPreset(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateUserPreset").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertTemplateUserPreset").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertUserAIBudgetOverride(ctx context.Context, arg database.UpsertUserAIBudgetOverrideParams) (database.UserAIBudgetOverride, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertUserAIBudgetOverride(ctx, arg)
@@ -6585,6 +7961,14 @@ func (m queryMetricsStore) UpsertUserChatPersonalModelOverride(ctx context.Conte
 	return r0
 }
 
+func (m queryMetricsStore) UpsertUserNotificationScopedPreferences(ctx context.Context, arg database.UpsertUserNotificationScopedPreferencesParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserNotificationScopedPreferences(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserNotificationScopedPreferences").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertUserNotificationScopedPreferences").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertWebpushVAPIDKeys(ctx, arg)
@@ -6609,6 +7993,14 @@ func (m queryMetricsStore) UpsertWorkspaceAgentContextSnapshot(ctx context.Conte
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertWorkspaceAgentCrashLoop(ctx context.Context, arg database.UpsertWorkspaceAgentCrashLoopParams) (database.WorkspaceAgentCrashLoop, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceAgentCrashLoop(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentCrashLoop").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceAgentCrashLoop").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertWorkspaceAgentPortShare(ctx, arg)
@@ -6633,6 +8025,86 @@ func (m queryMetricsStore) UpsertWorkspaceAppAuditSession(ctx context.Context, a
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertWorkspaceCloudResource(ctx context.Context, arg database.UpsertWorkspaceCloudResourceParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceCloudResource(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceCloudResource").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceCloudResource").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceDNSRecord(ctx context.Context, arg database.UpsertWorkspaceDNSRecordParams) (database.WorkspaceDNSRecord, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceDNSRecord(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceDNSRecord").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceDNSRecord").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertWorkspaceDormancyExemption(ctx context.Context, arg database.UpsertWorkspaceDormancyExemptionParams) (database.WorkspaceDormancyExemption, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceDormancyExemption(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceDormancyExemption").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceDormancyExemption").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertWorkspaceDormancyHook(ctx context.Context, arg database.UpsertWorkspaceDormancyHookParams) (database.WorkspaceDormancyHook, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceDormancyHook(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceDormancyHook").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceDormancyHook").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertWorkspaceEgressDaily(ctx context.Context, arg database.UpsertWorkspaceEgressDailyParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceEgressDaily(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceEgressDaily").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceEgressDaily").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceLifetimeWarning(ctx context.Context, arg database.UpsertWorkspaceLifetimeWarningParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceLifetimeWarning(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceLifetimeWarning").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceLifetimeWarning").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceMonthlyCost(ctx context.Context, arg database.UpsertWorkspaceMonthlyCostParams) (database.WorkspaceMonthlyCost, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceMonthlyCost(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceMonthlyCost").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceMonthlyCost").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertWorkspaceNetworkPolicy(ctx context.Context, arg database.UpsertWorkspaceNetworkPolicyParams) (database.WorkspaceNetworkPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceNetworkPolicy(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceNetworkPolicy").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceNetworkPolicy").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertWorkspacePortForwardACL(ctx context.Context, arg database.UpsertWorkspacePortForwardACLParams) (database.WorkspacePortForwardACL, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspacePortForwardACL(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspacePortForwardACL").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspacePortForwardACL").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertWorkspaceRegionAssignment(ctx context.Context, arg database.UpsertWorkspaceRegionAssignmentParams) (database.WorkspaceRegionAssignment, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceRegionAssignment(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceRegionAssignment").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceRegionAssignment").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UsageEventExistsByID(ctx context.Context, id string) (bool, error) {
 	start := time.Now()
 	r0, r1 := m.s.UsageEventExistsByID(ctx, id)
@@ -45,6 +45,20 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AccrueWorkspaceEstimatedCosts mocks base method.
+func (m *MockStore) AccrueWorkspaceEstimatedCosts(ctx context.Context, arg database.AccrueWorkspaceEstimatedCostsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueWorkspaceEstimatedCosts", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccrueWorkspaceEstimatedCosts indicates an expected call of AccrueWorkspaceEstimatedCosts.
+func (mr *MockStoreMockRecorder) AccrueWorkspaceEstimatedCosts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueWorkspaceEstimatedCosts", reflect.TypeOf((*MockStore)(nil).AccrueWorkspaceEstimatedCosts), ctx, arg)
+}
+
 // AcquireLock mocks base method.
 func (m *MockStore) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error {
 	m.ctrl.T.Helper()
@@ -367,6 +381,36 @@ func (mr *MockStoreMockRecorder) ClaimPrebuiltWorkspace(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPrebuiltWorkspace", reflect.TypeOf((*MockStore)(nil).ClaimPrebuiltWorkspace), ctx, arg)
 }
 
+// ClaimTemplateVersionScans mocks base method.
+func (m *MockStore) ClaimTemplateVersionScans(ctx context.Context, arg database.ClaimTemplateVersionScansParams) ([]database.TemplateVersionScan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimTemplateVersionScans", ctx, arg)
+	ret0, _ := ret[0].([]database.TemplateVersionScan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimTemplateVersionScans indicates an expected call of ClaimTemplateVersionScans.
+func (mr *MockStoreMockRecorder) ClaimTemplateVersionScans(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimTemplateVersionScans", reflect.TypeOf((*MockStore)(nil).ClaimTemplateVersionScans), ctx, arg)
+}
+
+// ClaimWorkspaceDormancyHooks mocks base method.
+func (m *MockStore) ClaimWorkspaceDormancyHooks(ctx context.Context, arg database.ClaimWorkspaceDormancyHooksParams) ([]database.WorkspaceDormancyHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWorkspaceDormancyHooks", ctx, arg)
+	ret0, _ := ret[0].([]database.WorkspaceDormancyHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWorkspaceDormancyHooks indicates an expected call of ClaimWorkspaceDormancyHooks.
+func (mr *MockStoreMockRecorder) ClaimWorkspaceDormancyHooks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWorkspaceDormancyHooks", reflect.TypeOf((*MockStore)(nil).ClaimWorkspaceDormancyHooks), ctx, arg)
+}
+
 // CleanTailnetCoordinators mocks base method.
 func (m *MockStore) CleanTailnetCoordinators(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -438,6 +482,21 @@ func (mr *MockStoreMockRecorder) CountAIBridgeSessions(ctx, arg any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAIBridgeSessions", reflect.TypeOf((*MockStore)(nil).CountAIBridgeSessions), ctx, arg)
 }
 
+// CountActiveWorkspaceLeasesByAPIKeyID mocks base method.
+func (m *MockStore) CountActiveWorkspaceLeasesByAPIKeyID(ctx context.Context, apiKeyID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveWorkspaceLeasesByAPIKeyID", ctx, apiKeyID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveWorkspaceLeasesByAPIKeyID indicates an expected call of CountActiveWorkspaceLeasesByAPIKeyID.
+func (mr *MockStoreMockRecorder) CountActiveWorkspaceLeasesByAPIKeyID(ctx, apiKeyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveWorkspaceLeasesByAPIKeyID", reflect.TypeOf((*MockStore)(nil).CountActiveWorkspaceLeasesByAPIKeyID), ctx, apiKeyID)
+}
+
 // CountAuditLogs mocks base method.
 func (m *MockStore) CountAuditLogs(ctx context.Context, arg database.CountAuditLogsParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -588,6 +647,21 @@ func (mr *MockStoreMockRecorder) CountPendingNonActivePrebuilds(ctx any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPendingNonActivePrebuilds", reflect.TypeOf((*MockStore)(nil).CountPendingNonActivePrebuilds), ctx)
 }
 
+// CountRunningProvisionerJobsByWorkerID mocks base method.
+func (m *MockStore) CountRunningProvisionerJobsByWorkerID(ctx context.Context, workerID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRunningProvisionerJobsByWorkerID", ctx, workerID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRunningProvisionerJobsByWorkerID indicates an expected call of CountRunningProvisionerJobsByWorkerID.
+func (mr *MockStoreMockRecorder) CountRunningProvisionerJobsByWorkerID(ctx, workerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRunningProvisionerJobsByWorkerID", reflect.TypeOf((*MockStore)(nil).CountRunningProvisionerJobsByWorkerID), ctx, workerID)
+}
+
 // CountUnreadInboxNotificationsByUserID mocks base method.
 func (m *MockStore) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
@@ -963,6 +1037,20 @@ func (mr *MockStoreMockRecorder) DeleteExpiredAPIKeys(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredAPIKeys", reflect.TypeOf((*MockStore)(nil).DeleteExpiredAPIKeys), ctx, arg)
 }
 
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockStore) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockStoreMockRecorder) DeleteExpiredIdempotencyKeys(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockStore)(nil).DeleteExpiredIdempotencyKeys), ctx, before)
+}
+
 // DeleteExternalAuthLink mocks base method.
 func (m *MockStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	m.ctrl.T.Helper()
@@ -1063,6 +1151,20 @@ func (mr *MockStoreMockRecorder) DeleteMCPServerUserToken(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMCPServerUserToken", reflect.TypeOf((*MockStore)(nil).DeleteMCPServerUserToken), ctx, arg)
 }
 
+// DeleteNotificationTemplateWebhook mocks base method.
+func (m *MockStore) DeleteNotificationTemplateWebhook(ctx context.Context, notificationTemplateID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationTemplateWebhook", ctx, notificationTemplateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotificationTemplateWebhook indicates an expected call of DeleteNotificationTemplateWebhook.
+func (mr *MockStoreMockRecorder) DeleteNotificationTemplateWebhook(ctx, notificationTemplateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationTemplateWebhook", reflect.TypeOf((*MockStore)(nil).DeleteNotificationTemplateWebhook), ctx, notificationTemplateID)
+}
+
 // DeleteOAuth2ProviderAppByClientID mocks base method.
 func (m *MockStore) DeleteOAuth2ProviderAppByClientID(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -1338,6 +1440,49 @@ func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentLogs(ctx, threshold any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentLogs), ctx, threshold)
 }
 
+// DeleteOldWorkspaceAgentMemoryEvents mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentMemoryEvents(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentMemoryEvents", ctx, beforeTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceAgentMemoryEvents indicates an expected call of DeleteOldWorkspaceAgentMemoryEvents.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentMemoryEvents(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentMemoryEvents", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentMemoryEvents), ctx, beforeTime)
+}
+
+// DeleteOldWorkspaceAgentMetadataHistory mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, arg database.DeleteOldWorkspaceAgentMetadataHistoryParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentMetadataHistory", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOldWorkspaceAgentMetadataHistory indicates an expected call of DeleteOldWorkspaceAgentMetadataHistory.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentMetadataHistory(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentMetadataHistory", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentMetadataHistory), ctx, arg)
+}
+
+// DeleteOldWorkspaceAgentNetworkPolicyViolations mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentNetworkPolicyViolations(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentNetworkPolicyViolations", ctx, beforeTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceAgentNetworkPolicyViolations indicates an expected call of DeleteOldWorkspaceAgentNetworkPolicyViolations.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentNetworkPolicyViolations(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentNetworkPolicyViolations", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentNetworkPolicyViolations), ctx, beforeTime)
+}
+
 // DeleteOldWorkspaceAgentStats mocks base method.
 func (m *MockStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -1381,6 +1526,21 @@ func (mr *MockStoreMockRecorder) DeleteOrganizationMember(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganizationMember", reflect.TypeOf((*MockStore)(nil).DeleteOrganizationMember), ctx, arg)
 }
 
+// DeleteProvisionerJobLogsByJobID mocks base method.
+func (m *MockStore) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProvisionerJobLogsByJobID", ctx, jobID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteProvisionerJobLogsByJobID indicates an expected call of DeleteProvisionerJobLogsByJobID.
+func (mr *MockStoreMockRecorder) DeleteProvisionerJobLogsByJobID(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProvisionerJobLogsByJobID", reflect.TypeOf((*MockStore)(nil).DeleteProvisionerJobLogsByJobID), ctx, jobID)
+}
+
 // DeleteProvisionerKey mocks base method.
 func (m *MockStore) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -1497,6 +1657,63 @@ func (mr *MockStoreMockRecorder) DeleteTask(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockStore)(nil).DeleteTask), ctx, arg)
 }
 
+// DeleteTemplateNetworkPolicy mocks base method.
+func (m *MockStore) DeleteTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateNetworkPolicy", ctx, templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplateNetworkPolicy indicates an expected call of DeleteTemplateNetworkPolicy.
+func (mr *MockStoreMockRecorder) DeleteTemplateNetworkPolicy(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateNetworkPolicy", reflect.TypeOf((*MockStore)(nil).DeleteTemplateNetworkPolicy), ctx, templateID)
+}
+
+// DeleteTemplatePresetCohortsByTemplateID mocks base method.
+func (m *MockStore) DeleteTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplatePresetCohortsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplatePresetCohortsByTemplateID indicates an expected call of DeleteTemplatePresetCohortsByTemplateID.
+func (mr *MockStoreMockRecorder) DeleteTemplatePresetCohortsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplatePresetCohortsByTemplateID", reflect.TypeOf((*MockStore)(nil).DeleteTemplatePresetCohortsByTemplateID), ctx, templateID)
+}
+
+// DeleteTemplateSecretByTemplateIDAndName mocks base method.
+func (m *MockStore) DeleteTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.DeleteTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateSecretByTemplateIDAndName", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTemplateSecretByTemplateIDAndName indicates an expected call of DeleteTemplateSecretByTemplateIDAndName.
+func (mr *MockStoreMockRecorder) DeleteTemplateSecretByTemplateIDAndName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateSecretByTemplateIDAndName", reflect.TypeOf((*MockStore)(nil).DeleteTemplateSecretByTemplateIDAndName), ctx, arg)
+}
+
+// DeleteTemplateUserPresetByID mocks base method.
+func (m *MockStore) DeleteTemplateUserPresetByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateUserPresetByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplateUserPresetByID indicates an expected call of DeleteTemplateUserPresetByID.
+func (mr *MockStoreMockRecorder) DeleteTemplateUserPresetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateUserPresetByID", reflect.TypeOf((*MockStore)(nil).DeleteTemplateUserPresetByID), ctx, id)
+}
+
 // DeleteUserAIBudgetOverride mocks base method.
 func (m *MockStore) DeleteUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	m.ctrl.T.Helper()
@@ -1554,6 +1771,35 @@ func (mr *MockStoreMockRecorder) DeleteUserChatCompactionThreshold(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserChatCompactionThreshold", reflect.TypeOf((*MockStore)(nil).DeleteUserChatCompactionThreshold), ctx, arg)
 }
 
+// DeleteUserNotificationScopedPreferences mocks base method.
+func (m *MockStore) DeleteUserNotificationScopedPreferences(ctx context.Context, arg database.DeleteUserNotificationScopedPreferencesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserNotificationScopedPreferences", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserNotificationScopedPreferences indicates an expected call of DeleteUserNotificationScopedPreferences.
+func (mr *MockStoreMockRecorder) DeleteUserNotificationScopedPreferences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserNotificationScopedPreferences", reflect.TypeOf((*MockStore)(nil).DeleteUserNotificationScopedPreferences), ctx, arg)
+}
+
+// DeleteUserSchedulePause mocks base method.
+func (m *MockStore) DeleteUserSchedulePause(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSchedulePause", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSchedulePause indicates an expected call of DeleteUserSchedulePause.
+func (mr *MockStoreMockRecorder) DeleteUserSchedulePause(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSchedulePause", reflect.TypeOf((*MockStore)(nil).DeleteUserSchedulePause), ctx, userID)
+}
+
 // DeleteUserSecretByUserIDAndName mocks base method.
 func (m *MockStore) DeleteUserSecretByUserIDAndName(ctx context.Context, arg database.DeleteUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
 	m.ctrl.T.Helper()
@@ -1584,6 +1830,21 @@ func (mr *MockStoreMockRecorder) DeleteUserSkillByUserIDAndName(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSkillByUserIDAndName", reflect.TypeOf((*MockStore)(nil).DeleteUserSkillByUserIDAndName), ctx, arg)
 }
 
+// DeleteVCSEventMappingByTemplateIDAndID mocks base method.
+func (m *MockStore) DeleteVCSEventMappingByTemplateIDAndID(ctx context.Context, arg database.DeleteVCSEventMappingByTemplateIDAndIDParams) (database.VCSEventMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVCSEventMappingByTemplateIDAndID", ctx, arg)
+	ret0, _ := ret[0].(database.VCSEventMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVCSEventMappingByTemplateIDAndID indicates an expected call of DeleteVCSEventMappingByTemplateIDAndID.
+func (mr *MockStoreMockRecorder) DeleteVCSEventMappingByTemplateIDAndID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVCSEventMappingByTemplateIDAndID", reflect.TypeOf((*MockStore)(nil).DeleteVCSEventMappingByTemplateIDAndID), ctx, arg)
+}
+
 // DeleteWebpushSubscriptionByUserIDAndEndpoint mocks base method.
 func (m *MockStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	m.ctrl.T.Helper()
@@ -1640,6 +1901,20 @@ func (mr *MockStoreMockRecorder) DeleteWorkspaceACLsByOrganization(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceACLsByOrganization", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceACLsByOrganization), ctx, arg)
 }
 
+// DeleteWorkspaceAgentCrashLoop mocks base method.
+func (m *MockStore) DeleteWorkspaceAgentCrashLoop(ctx context.Context, agentID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentCrashLoop", ctx, agentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceAgentCrashLoop indicates an expected call of DeleteWorkspaceAgentCrashLoop.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentCrashLoop(ctx, agentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentCrashLoop", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentCrashLoop), ctx, agentID)
+}
+
 // DeleteWorkspaceAgentPortShare mocks base method.
 func (m *MockStore) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
 	m.ctrl.T.Helper()
@@ -1668,6 +1943,76 @@ func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortSharesByTemplate(ctx, t
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortSharesByTemplate", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortSharesByTemplate), ctx, templateID)
 }
 
+// DeleteWorkspaceDNSRecordByWorkspaceID mocks base method.
+func (m *MockStore) DeleteWorkspaceDNSRecordByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceDNSRecordByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceDNSRecordByWorkspaceID indicates an expected call of DeleteWorkspaceDNSRecordByWorkspaceID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceDNSRecordByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceDNSRecordByWorkspaceID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceDNSRecordByWorkspaceID), ctx, workspaceID)
+}
+
+// DeleteWorkspaceDormancyExemption mocks base method.
+func (m *MockStore) DeleteWorkspaceDormancyExemption(ctx context.Context, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceDormancyExemption", ctx, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceDormancyExemption indicates an expected call of DeleteWorkspaceDormancyExemption.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceDormancyExemption(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceDormancyExemption", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceDormancyExemption), ctx, workspaceID)
+}
+
+// DeleteWorkspaceNetworkPolicy mocks base method.
+func (m *MockStore) DeleteWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceNetworkPolicy", ctx, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceNetworkPolicy indicates an expected call of DeleteWorkspaceNetworkPolicy.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceNetworkPolicy(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceNetworkPolicy", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceNetworkPolicy), ctx, workspaceID)
+}
+
+// DeleteWorkspacePortForwardACL mocks base method.
+func (m *MockStore) DeleteWorkspacePortForwardACL(ctx context.Context, arg database.DeleteWorkspacePortForwardACLParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspacePortForwardACL", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspacePortForwardACL indicates an expected call of DeleteWorkspacePortForwardACL.
+func (mr *MockStoreMockRecorder) DeleteWorkspacePortForwardACL(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspacePortForwardACL", reflect.TypeOf((*MockStore)(nil).DeleteWorkspacePortForwardACL), ctx, arg)
+}
+
+// DeleteWorkspacePortForwardACLsByWorkspaceID mocks base method.
+func (m *MockStore) DeleteWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspacePortForwardACLsByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspacePortForwardACLsByWorkspaceID indicates an expected call of DeleteWorkspacePortForwardACLsByWorkspaceID.
+func (mr *MockStoreMockRecorder) DeleteWorkspacePortForwardACLsByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspacePortForwardACLsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspacePortForwardACLsByWorkspaceID), ctx, workspaceID)
+}
+
 // DeleteWorkspaceSubAgentByID mocks base method.
 func (m *MockStore) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -1682,6 +2027,35 @@ func (mr *MockStoreMockRecorder) DeleteWorkspaceSubAgentByID(ctx, id any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceSubAgentByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceSubAgentByID), ctx, id)
 }
 
+// DetachSharedVolumeAttachmentByID mocks base method.
+func (m *MockStore) DetachSharedVolumeAttachmentByID(ctx context.Context, arg database.DetachSharedVolumeAttachmentByIDParams) (database.SharedVolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachSharedVolumeAttachmentByID", ctx, arg)
+	ret0, _ := ret[0].(database.SharedVolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachSharedVolumeAttachmentByID indicates an expected call of DetachSharedVolumeAttachmentByID.
+func (mr *MockStoreMockRecorder) DetachSharedVolumeAttachmentByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachSharedVolumeAttachmentByID", reflect.TypeOf((*MockStore)(nil).DetachSharedVolumeAttachmentByID), ctx, arg)
+}
+
+// DetachSharedVolumeAttachmentsByWorkspaceID mocks base method.
+func (m *MockStore) DetachSharedVolumeAttachmentsByWorkspaceID(ctx context.Context, arg database.DetachSharedVolumeAttachmentsByWorkspaceIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachSharedVolumeAttachmentsByWorkspaceID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachSharedVolumeAttachmentsByWorkspaceID indicates an expected call of DetachSharedVolumeAttachmentsByWorkspaceID.
+func (mr *MockStoreMockRecorder) DetachSharedVolumeAttachmentsByWorkspaceID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachSharedVolumeAttachmentsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).DetachSharedVolumeAttachmentsByWorkspaceID), ctx, arg)
+}
+
 // DisableForeignKeysAndTriggers mocks base method.
 func (m *MockStore) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -2218,6 +2592,51 @@ func (mr *MockStoreMockRecorder) GetActivePresetPrebuildSchedules(ctx any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivePresetPrebuildSchedules", reflect.TypeOf((*MockStore)(nil).GetActivePresetPrebuildSchedules), ctx)
 }
 
+// GetActiveSharedVolumeAttachmentsBySharedVolumeID mocks base method.
+func (m *MockStore) GetActiveSharedVolumeAttachmentsBySharedVolumeID(ctx context.Context, sharedVolumeID uuid.UUID) ([]database.GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSharedVolumeAttachmentsBySharedVolumeID", ctx, sharedVolumeID)
+	ret0, _ := ret[0].([]database.GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSharedVolumeAttachmentsBySharedVolumeID indicates an expected call of GetActiveSharedVolumeAttachmentsBySharedVolumeID.
+func (mr *MockStoreMockRecorder) GetActiveSharedVolumeAttachmentsBySharedVolumeID(ctx, sharedVolumeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSharedVolumeAttachmentsBySharedVolumeID", reflect.TypeOf((*MockStore)(nil).GetActiveSharedVolumeAttachmentsBySharedVolumeID), ctx, sharedVolumeID)
+}
+
+// GetActiveTemplateVersionRolloutByTemplateID mocks base method.
+func (m *MockStore) GetActiveTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveTemplateVersionRolloutByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveTemplateVersionRolloutByTemplateID indicates an expected call of GetActiveTemplateVersionRolloutByTemplateID.
+func (mr *MockStoreMockRecorder) GetActiveTemplateVersionRolloutByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveTemplateVersionRolloutByTemplateID", reflect.TypeOf((*MockStore)(nil).GetActiveTemplateVersionRolloutByTemplateID), ctx, templateID)
+}
+
+// GetActiveTemplateVersionRollouts mocks base method.
+func (m *MockStore) GetActiveTemplateVersionRollouts(ctx context.Context) ([]database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveTemplateVersionRollouts", ctx)
+	ret0, _ := ret[0].([]database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveTemplateVersionRollouts indicates an expected call of GetActiveTemplateVersionRollouts.
+func (mr *MockStoreMockRecorder) GetActiveTemplateVersionRollouts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveTemplateVersionRollouts", reflect.TypeOf((*MockStore)(nil).GetActiveTemplateVersionRollouts), ctx)
+}
+
 // GetActiveUserCount mocks base method.
 func (m *MockStore) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
 	m.ctrl.T.Helper()
@@ -2518,6 +2937,21 @@ func (mr *MockStoreMockRecorder) GetAutoArchiveInactiveChatCandidates(ctx, arg a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutoArchiveInactiveChatCandidates", reflect.TypeOf((*MockStore)(nil).GetAutoArchiveInactiveChatCandidates), ctx, arg)
 }
 
+// GetAutostopInsights mocks base method.
+func (m *MockStore) GetAutostopInsights(ctx context.Context, arg database.GetAutostopInsightsParams) ([]database.GetAutostopInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutostopInsights", ctx, arg)
+	ret0, _ := ret[0].([]database.GetAutostopInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutostopInsights indicates an expected call of GetAutostopInsights.
+func (mr *MockStoreMockRecorder) GetAutostopInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutostopInsights", reflect.TypeOf((*MockStore)(nil).GetAutostopInsights), ctx, arg)
+}
+
 // GetBoundaryLogByID mocks base method.
 func (m *MockStore) GetBoundaryLogByID(ctx context.Context, id uuid.UUID) (database.BoundaryLog, error) {
 	m.ctrl.T.Helper()
@@ -3793,6 +4227,21 @@ func (mr *MockStoreMockRecorder) GetEnabledMCPServerConfigs(ctx any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnabledMCPServerConfigs", reflect.TypeOf((*MockStore)(nil).GetEnabledMCPServerConfigs), ctx)
 }
 
+// GetExpiredWorkspaceLeases mocks base method.
+func (m *MockStore) GetExpiredWorkspaceLeases(ctx context.Context, arg database.GetExpiredWorkspaceLeasesParams) ([]database.WorkspaceLease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredWorkspaceLeases", ctx, arg)
+	ret0, _ := ret[0].([]database.WorkspaceLease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredWorkspaceLeases indicates an expected call of GetExpiredWorkspaceLeases.
+func (mr *MockStoreMockRecorder) GetExpiredWorkspaceLeases(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredWorkspaceLeases", reflect.TypeOf((*MockStore)(nil).GetExpiredWorkspaceLeases), ctx, arg)
+}
+
 // GetExternalAgentTokensByTemplateID mocks base method.
 func (m *MockStore) GetExternalAgentTokensByTemplateID(ctx context.Context, arg database.GetExternalAgentTokensByTemplateIDParams) ([]database.GetExternalAgentTokensByTemplateIDRow, error) {
 	m.ctrl.T.Helper()
@@ -4123,10 +4572,40 @@ func (mr *MockStoreMockRecorder) GetHighestGroupAIBudgetByUser(ctx, userID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestGroupAIBudgetByUser", reflect.TypeOf((*MockStore)(nil).GetHighestGroupAIBudgetByUser), ctx, userID)
 }
 
-// GetInboxNotificationByID mocks base method.
-func (m *MockStore) GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (database.InboxNotification, error) {
+// GetIdempotencyKey mocks base method.
+func (m *MockStore) GetIdempotencyKey(ctx context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetInboxNotificationByID", ctx, id)
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(database.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetIdempotencyKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetIdempotencyKey), ctx, arg)
+}
+
+// GetInProgressTemplateWorkspaceRestarts mocks base method.
+func (m *MockStore) GetInProgressTemplateWorkspaceRestarts(ctx context.Context) ([]database.TemplateWorkspaceRestart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInProgressTemplateWorkspaceRestarts", ctx)
+	ret0, _ := ret[0].([]database.TemplateWorkspaceRestart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInProgressTemplateWorkspaceRestarts indicates an expected call of GetInProgressTemplateWorkspaceRestarts.
+func (mr *MockStoreMockRecorder) GetInProgressTemplateWorkspaceRestarts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInProgressTemplateWorkspaceRestarts", reflect.TypeOf((*MockStore)(nil).GetInProgressTemplateWorkspaceRestarts), ctx)
+}
+
+// GetInboxNotificationByID mocks base method.
+func (m *MockStore) GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (database.InboxNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboxNotificationByID", ctx, id)
 	ret0, _ := ret[0].(database.InboxNotification)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
@@ -4198,6 +4677,36 @@ func (mr *MockStoreMockRecorder) GetLatestCryptoKeyByFeature(ctx, feature any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestCryptoKeyByFeature", reflect.TypeOf((*MockStore)(nil).GetLatestCryptoKeyByFeature), ctx, feature)
 }
 
+// GetLatestUserWorkspaceReportByUserID mocks base method.
+func (m *MockStore) GetLatestUserWorkspaceReportByUserID(ctx context.Context, userID uuid.UUID) (database.UserWorkspaceReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestUserWorkspaceReportByUserID", ctx, userID)
+	ret0, _ := ret[0].(database.UserWorkspaceReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestUserWorkspaceReportByUserID indicates an expected call of GetLatestUserWorkspaceReportByUserID.
+func (mr *MockStoreMockRecorder) GetLatestUserWorkspaceReportByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestUserWorkspaceReportByUserID", reflect.TypeOf((*MockStore)(nil).GetLatestUserWorkspaceReportByUserID), ctx, userID)
+}
+
+// GetLatestWorkspaceAgentBootstrapProgressByAgentIDs mocks base method.
+func (m *MockStore) GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentBootstrapProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestWorkspaceAgentBootstrapProgressByAgentIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.WorkspaceAgentBootstrapProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestWorkspaceAgentBootstrapProgressByAgentIDs indicates an expected call of GetLatestWorkspaceAgentBootstrapProgressByAgentIDs.
+func (mr *MockStoreMockRecorder) GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceAgentBootstrapProgressByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceAgentBootstrapProgressByAgentIDs), ctx, ids)
+}
+
 // GetLatestWorkspaceAgentContextSnapshot mocks base method.
 func (m *MockStore) GetLatestWorkspaceAgentContextSnapshot(ctx context.Context, workspaceAgentID uuid.UUID) (database.WorkspaceAgentContextSnapshot, error) {
 	m.ctrl.T.Helper()
@@ -4213,6 +4722,21 @@ func (mr *MockStoreMockRecorder) GetLatestWorkspaceAgentContextSnapshot(ctx, wor
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceAgentContextSnapshot", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceAgentContextSnapshot), ctx, workspaceAgentID)
 }
 
+// GetLatestWorkspaceAgentServiceTransitionsByAgentIDs mocks base method.
+func (m *MockStore) GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentServiceTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestWorkspaceAgentServiceTransitionsByAgentIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.WorkspaceAgentServiceTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestWorkspaceAgentServiceTransitionsByAgentIDs indicates an expected call of GetLatestWorkspaceAgentServiceTransitionsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceAgentServiceTransitionsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceAgentServiceTransitionsByAgentIDs), ctx, ids)
+}
+
 // GetLatestWorkspaceAppStatusByAppID mocks base method.
 func (m *MockStore) GetLatestWorkspaceAppStatusByAppID(ctx context.Context, appID uuid.UUID) (database.WorkspaceAppStatus, error) {
 	m.ctrl.T.Helper()
@@ -4288,6 +4812,21 @@ func (mr *MockStoreMockRecorder) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, ids
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceBuildsByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceBuildsByWorkspaceIDs), ctx, ids)
 }
 
+// GetLeakedWorkspaceCloudResources mocks base method.
+func (m *MockStore) GetLeakedWorkspaceCloudResources(ctx context.Context) ([]database.GetLeakedWorkspaceCloudResourcesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeakedWorkspaceCloudResources", ctx)
+	ret0, _ := ret[0].([]database.GetLeakedWorkspaceCloudResourcesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeakedWorkspaceCloudResources indicates an expected call of GetLeakedWorkspaceCloudResources.
+func (mr *MockStoreMockRecorder) GetLeakedWorkspaceCloudResources(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeakedWorkspaceCloudResources", reflect.TypeOf((*MockStore)(nil).GetLeakedWorkspaceCloudResources), ctx)
+}
+
 // GetLicenseByID mocks base method.
 func (m *MockStore) GetLicenseByID(ctx context.Context, id int32) (database.License, error) {
 	m.ctrl.T.Helper()
@@ -4453,6 +4992,21 @@ func (mr *MockStoreMockRecorder) GetNotificationMessagesByStatus(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationMessagesByStatus", reflect.TypeOf((*MockStore)(nil).GetNotificationMessagesByStatus), ctx, arg)
 }
 
+// GetNotificationMessagesByTemplateID mocks base method.
+func (m *MockStore) GetNotificationMessagesByTemplateID(ctx context.Context, arg database.GetNotificationMessagesByTemplateIDParams) ([]database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationMessagesByTemplateID", ctx, arg)
+	ret0, _ := ret[0].([]database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationMessagesByTemplateID indicates an expected call of GetNotificationMessagesByTemplateID.
+func (mr *MockStoreMockRecorder) GetNotificationMessagesByTemplateID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationMessagesByTemplateID", reflect.TypeOf((*MockStore)(nil).GetNotificationMessagesByTemplateID), ctx, arg)
+}
+
 // GetNotificationReportGeneratorLogByTemplate mocks base method.
 func (m *MockStore) GetNotificationReportGeneratorLogByTemplate(ctx context.Context, templateID uuid.UUID) (database.NotificationReportGeneratorLog, error) {
 	m.ctrl.T.Helper()
@@ -4483,6 +5037,21 @@ func (mr *MockStoreMockRecorder) GetNotificationTemplateByID(ctx, id any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationTemplateByID", reflect.TypeOf((*MockStore)(nil).GetNotificationTemplateByID), ctx, id)
 }
 
+// GetNotificationTemplateWebhookByTemplateID mocks base method.
+func (m *MockStore) GetNotificationTemplateWebhookByTemplateID(ctx context.Context, notificationTemplateID uuid.UUID) (database.NotificationTemplateWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationTemplateWebhookByTemplateID", ctx, notificationTemplateID)
+	ret0, _ := ret[0].(database.NotificationTemplateWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationTemplateWebhookByTemplateID indicates an expected call of GetNotificationTemplateWebhookByTemplateID.
+func (mr *MockStoreMockRecorder) GetNotificationTemplateWebhookByTemplateID(ctx, notificationTemplateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationTemplateWebhookByTemplateID", reflect.TypeOf((*MockStore)(nil).GetNotificationTemplateWebhookByTemplateID), ctx, notificationTemplateID)
+}
+
 // GetNotificationTemplatesByKind mocks base method.
 func (m *MockStore) GetNotificationTemplatesByKind(ctx context.Context, kind database.NotificationTemplateKind) ([]database.NotificationTemplate, error) {
 	m.ctrl.T.Helper()
@@ -4813,6 +5382,21 @@ func (mr *MockStoreMockRecorder) GetOrganizationsWithPrebuildStatus(ctx, arg any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationsWithPrebuildStatus", reflect.TypeOf((*MockStore)(nil).GetOrganizationsWithPrebuildStatus), ctx, arg)
 }
 
+// GetOrphanedWorkspaces mocks base method.
+func (m *MockStore) GetOrphanedWorkspaces(ctx context.Context) ([]database.GetOrphanedWorkspacesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrphanedWorkspaces", ctx)
+	ret0, _ := ret[0].([]database.GetOrphanedWorkspacesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrphanedWorkspaces indicates an expected call of GetOrphanedWorkspaces.
+func (mr *MockStoreMockRecorder) GetOrphanedWorkspaces(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrphanedWorkspaces", reflect.TypeOf((*MockStore)(nil).GetOrphanedWorkspaces), ctx)
+}
+
 // GetParameterSchemasByJobID mocks base method.
 func (m *MockStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	m.ctrl.T.Helper()
@@ -4828,6 +5412,21 @@ func (mr *MockStoreMockRecorder) GetParameterSchemasByJobID(ctx, jobID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameterSchemasByJobID", reflect.TypeOf((*MockStore)(nil).GetParameterSchemasByJobID), ctx, jobID)
 }
 
+// GetPrebuildClaimInsights mocks base method.
+func (m *MockStore) GetPrebuildClaimInsights(ctx context.Context, arg database.GetPrebuildClaimInsightsParams) ([]database.GetPrebuildClaimInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrebuildClaimInsights", ctx, arg)
+	ret0, _ := ret[0].([]database.GetPrebuildClaimInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrebuildClaimInsights indicates an expected call of GetPrebuildClaimInsights.
+func (mr *MockStoreMockRecorder) GetPrebuildClaimInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrebuildClaimInsights", reflect.TypeOf((*MockStore)(nil).GetPrebuildClaimInsights), ctx, arg)
+}
+
 // GetPrebuildMetrics mocks base method.
 func (m *MockStore) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	m.ctrl.T.Helper()
@@ -4918,6 +5517,21 @@ func (mr *MockStoreMockRecorder) GetPresetParametersByTemplateVersionID(ctx, tem
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPresetParametersByTemplateVersionID", reflect.TypeOf((*MockStore)(nil).GetPresetParametersByTemplateVersionID), ctx, templateVersionID)
 }
 
+// GetPresetPrebuildPoolState mocks base method.
+func (m *MockStore) GetPresetPrebuildPoolState(ctx context.Context, arg database.GetPresetPrebuildPoolStateParams) (database.GetPresetPrebuildPoolStateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPresetPrebuildPoolState", ctx, arg)
+	ret0, _ := ret[0].(database.GetPresetPrebuildPoolStateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPresetPrebuildPoolState indicates an expected call of GetPresetPrebuildPoolState.
+func (mr *MockStoreMockRecorder) GetPresetPrebuildPoolState(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPresetPrebuildPoolState", reflect.TypeOf((*MockStore)(nil).GetPresetPrebuildPoolState), ctx, arg)
+}
+
 // GetPresetsAtFailureLimit mocks base method.
 func (m *MockStore) GetPresetsAtFailureLimit(ctx context.Context, hardLimit int64) ([]database.GetPresetsAtFailureLimitRow, error) {
 	m.ctrl.T.Helper()
@@ -4978,6 +5592,21 @@ func (mr *MockStoreMockRecorder) GetPreviousTemplateVersion(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreviousTemplateVersion", reflect.TypeOf((*MockStore)(nil).GetPreviousTemplateVersion), ctx, arg)
 }
 
+// GetProvisionerCacheSettings mocks base method.
+func (m *MockStore) GetProvisionerCacheSettings(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerCacheSettings", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerCacheSettings indicates an expected call of GetProvisionerCacheSettings.
+func (mr *MockStoreMockRecorder) GetProvisionerCacheSettings(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerCacheSettings", reflect.TypeOf((*MockStore)(nil).GetProvisionerCacheSettings), ctx)
+}
+
 // GetProvisionerDaemons mocks base method.
 func (m *MockStore) GetProvisionerDaemons(ctx context.Context) ([]database.ProvisionerDaemon, error) {
 	m.ctrl.T.Helper()
@@ -5068,6 +5697,51 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobByIDWithLock(ctx, id any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobByIDWithLock", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobByIDWithLock), ctx, id)
 }
 
+// GetProvisionerJobErrorLogsByJobIDs mocks base method.
+func (m *MockStore) GetProvisionerJobErrorLogsByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]database.ProvisionerJobLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobErrorLogsByJobIDs", ctx, jobIds)
+	ret0, _ := ret[0].([]database.ProvisionerJobLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobErrorLogsByJobIDs indicates an expected call of GetProvisionerJobErrorLogsByJobIDs.
+func (mr *MockStoreMockRecorder) GetProvisionerJobErrorLogsByJobIDs(ctx, jobIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobErrorLogsByJobIDs", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobErrorLogsByJobIDs), ctx, jobIds)
+}
+
+// GetProvisionerJobLogArchiveByJobID mocks base method.
+func (m *MockStore) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobLogArchiveByJobID", ctx, jobID)
+	ret0, _ := ret[0].(database.ProvisionerJobLogArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobLogArchiveByJobID indicates an expected call of GetProvisionerJobLogArchiveByJobID.
+func (mr *MockStoreMockRecorder) GetProvisionerJobLogArchiveByJobID(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobLogArchiveByJobID", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobLogArchiveByJobID), ctx, jobID)
+}
+
+// GetProvisionerJobLogArchivesByJobIDs mocks base method.
+func (m *MockStore) GetProvisionerJobLogArchivesByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]database.ProvisionerJobLogArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobLogArchivesByJobIDs", ctx, jobIds)
+	ret0, _ := ret[0].([]database.ProvisionerJobLogArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobLogArchivesByJobIDs indicates an expected call of GetProvisionerJobLogArchivesByJobIDs.
+func (mr *MockStoreMockRecorder) GetProvisionerJobLogArchivesByJobIDs(ctx, jobIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobLogArchivesByJobIDs", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobLogArchivesByJobIDs), ctx, jobIds)
+}
+
 // GetProvisionerJobTimingsByJobID mocks base method.
 func (m *MockStore) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	m.ctrl.T.Helper()
@@ -5098,6 +5772,21 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobsByIDsWithQueuePosition(ctx, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsByIDsWithQueuePosition", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsByIDsWithQueuePosition), ctx, arg)
 }
 
+// GetProvisionerJobsByInitiatorWithQueuePosition mocks base method.
+func (m *MockStore) GetProvisionerJobsByInitiatorWithQueuePosition(ctx context.Context, initiatorID uuid.UUID) ([]database.GetProvisionerJobsByInitiatorWithQueuePositionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobsByInitiatorWithQueuePosition", ctx, initiatorID)
+	ret0, _ := ret[0].([]database.GetProvisionerJobsByInitiatorWithQueuePositionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobsByInitiatorWithQueuePosition indicates an expected call of GetProvisionerJobsByInitiatorWithQueuePosition.
+func (mr *MockStoreMockRecorder) GetProvisionerJobsByInitiatorWithQueuePosition(ctx, initiatorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsByInitiatorWithQueuePosition", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsByInitiatorWithQueuePosition), ctx, initiatorID)
+}
+
 // GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner mocks base method.
 func (m *MockStore) GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx context.Context, arg database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerParams) ([]database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerRow, error) {
 	m.ctrl.T.Helper()
@@ -5143,6 +5832,21 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobsToBeReaped(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsToBeReaped", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsToBeReaped), ctx, arg)
 }
 
+// GetProvisionerJobsWithExpiredLogs mocks base method.
+func (m *MockStore) GetProvisionerJobsWithExpiredLogs(ctx context.Context, arg database.GetProvisionerJobsWithExpiredLogsParams) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobsWithExpiredLogs", ctx, arg)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobsWithExpiredLogs indicates an expected call of GetProvisionerJobsWithExpiredLogs.
+func (mr *MockStoreMockRecorder) GetProvisionerJobsWithExpiredLogs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsWithExpiredLogs", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsWithExpiredLogs), ctx, arg)
+}
+
 // GetProvisionerKeyByHashedSecret mocks base method.
 func (m *MockStore) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (database.ProvisionerKey, error) {
 	m.ctrl.T.Helper()
@@ -5308,6 +6012,81 @@ func (mr *MockStoreMockRecorder) GetRuntimeConfig(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimeConfig", reflect.TypeOf((*MockStore)(nil).GetRuntimeConfig), ctx, key)
 }
 
+// GetSensitiveWorkspaceBuildParameters mocks base method.
+func (m *MockStore) GetSensitiveWorkspaceBuildParameters(ctx context.Context) ([]database.WorkspaceBuildParameter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSensitiveWorkspaceBuildParameters", ctx)
+	ret0, _ := ret[0].([]database.WorkspaceBuildParameter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSensitiveWorkspaceBuildParameters indicates an expected call of GetSensitiveWorkspaceBuildParameters.
+func (mr *MockStoreMockRecorder) GetSensitiveWorkspaceBuildParameters(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSensitiveWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).GetSensitiveWorkspaceBuildParameters), ctx)
+}
+
+// GetSharedVolumeAttachmentByID mocks base method.
+func (m *MockStore) GetSharedVolumeAttachmentByID(ctx context.Context, id uuid.UUID) (database.SharedVolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedVolumeAttachmentByID", ctx, id)
+	ret0, _ := ret[0].(database.SharedVolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedVolumeAttachmentByID indicates an expected call of GetSharedVolumeAttachmentByID.
+func (mr *MockStoreMockRecorder) GetSharedVolumeAttachmentByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedVolumeAttachmentByID", reflect.TypeOf((*MockStore)(nil).GetSharedVolumeAttachmentByID), ctx, id)
+}
+
+// GetSharedVolumeByID mocks base method.
+func (m *MockStore) GetSharedVolumeByID(ctx context.Context, id uuid.UUID) (database.SharedVolume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedVolumeByID", ctx, id)
+	ret0, _ := ret[0].(database.SharedVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedVolumeByID indicates an expected call of GetSharedVolumeByID.
+func (mr *MockStoreMockRecorder) GetSharedVolumeByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedVolumeByID", reflect.TypeOf((*MockStore)(nil).GetSharedVolumeByID), ctx, id)
+}
+
+// GetSharedVolumeByOrganizationIDAndName mocks base method.
+func (m *MockStore) GetSharedVolumeByOrganizationIDAndName(ctx context.Context, arg database.GetSharedVolumeByOrganizationIDAndNameParams) (database.SharedVolume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedVolumeByOrganizationIDAndName", ctx, arg)
+	ret0, _ := ret[0].(database.SharedVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedVolumeByOrganizationIDAndName indicates an expected call of GetSharedVolumeByOrganizationIDAndName.
+func (mr *MockStoreMockRecorder) GetSharedVolumeByOrganizationIDAndName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedVolumeByOrganizationIDAndName", reflect.TypeOf((*MockStore)(nil).GetSharedVolumeByOrganizationIDAndName), ctx, arg)
+}
+
+// GetSharedVolumesByOrganizationID mocks base method.
+func (m *MockStore) GetSharedVolumesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.GetSharedVolumesByOrganizationIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedVolumesByOrganizationID", ctx, organizationID)
+	ret0, _ := ret[0].([]database.GetSharedVolumesByOrganizationIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedVolumesByOrganizationID indicates an expected call of GetSharedVolumesByOrganizationID.
+func (mr *MockStoreMockRecorder) GetSharedVolumesByOrganizationID(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedVolumesByOrganizationID", reflect.TypeOf((*MockStore)(nil).GetSharedVolumesByOrganizationID), ctx, organizationID)
+}
+
 // GetStaleChats mocks base method.
 func (m *MockStore) GetStaleChats(ctx context.Context, staleThreshold time.Time) ([]database.Chat, error) {
 	m.ctrl.T.Helper()
@@ -5473,6 +6252,21 @@ func (mr *MockStoreMockRecorder) GetTelemetryTaskEvents(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTelemetryTaskEvents", reflect.TypeOf((*MockStore)(nil).GetTelemetryTaskEvents), ctx, arg)
 }
 
+// GetTemplateActiveDeveloperDays mocks base method.
+func (m *MockStore) GetTemplateActiveDeveloperDays(ctx context.Context, arg database.GetTemplateActiveDeveloperDaysParams) ([]database.GetTemplateActiveDeveloperDaysRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateActiveDeveloperDays", ctx, arg)
+	ret0, _ := ret[0].([]database.GetTemplateActiveDeveloperDaysRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateActiveDeveloperDays indicates an expected call of GetTemplateActiveDeveloperDays.
+func (mr *MockStoreMockRecorder) GetTemplateActiveDeveloperDays(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateActiveDeveloperDays", reflect.TypeOf((*MockStore)(nil).GetTemplateActiveDeveloperDays), ctx, arg)
+}
+
 // GetTemplateAppInsights mocks base method.
 func (m *MockStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -5563,6 +6357,36 @@ func (mr *MockStoreMockRecorder) GetTemplateGroupRoles(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateGroupRoles", reflect.TypeOf((*MockStore)(nil).GetTemplateGroupRoles), ctx, id)
 }
 
+// GetTemplateHealthReportsByTemplateID mocks base method.
+func (m *MockStore) GetTemplateHealthReportsByTemplateID(ctx context.Context, arg database.GetTemplateHealthReportsByTemplateIDParams) ([]database.TemplateHealthReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateHealthReportsByTemplateID", ctx, arg)
+	ret0, _ := ret[0].([]database.TemplateHealthReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateHealthReportsByTemplateID indicates an expected call of GetTemplateHealthReportsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateHealthReportsByTemplateID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateHealthReportsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateHealthReportsByTemplateID), ctx, arg)
+}
+
+// GetTemplateHealthStats mocks base method.
+func (m *MockStore) GetTemplateHealthStats(ctx context.Context, since time.Time) ([]database.GetTemplateHealthStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateHealthStats", ctx, since)
+	ret0, _ := ret[0].([]database.GetTemplateHealthStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateHealthStats indicates an expected call of GetTemplateHealthStats.
+func (mr *MockStoreMockRecorder) GetTemplateHealthStats(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateHealthStats", reflect.TypeOf((*MockStore)(nil).GetTemplateHealthStats), ctx, since)
+}
+
 // GetTemplateInsights mocks base method.
 func (m *MockStore) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -5608,6 +6432,21 @@ func (mr *MockStoreMockRecorder) GetTemplateInsightsByTemplate(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateInsightsByTemplate", reflect.TypeOf((*MockStore)(nil).GetTemplateInsightsByTemplate), ctx, arg)
 }
 
+// GetTemplateNetworkPolicy mocks base method.
+func (m *MockStore) GetTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) (database.TemplateNetworkPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateNetworkPolicy", ctx, templateID)
+	ret0, _ := ret[0].(database.TemplateNetworkPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateNetworkPolicy indicates an expected call of GetTemplateNetworkPolicy.
+func (mr *MockStoreMockRecorder) GetTemplateNetworkPolicy(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateNetworkPolicy", reflect.TypeOf((*MockStore)(nil).GetTemplateNetworkPolicy), ctx, templateID)
+}
+
 // GetTemplateParameterInsights mocks base method.
 func (m *MockStore) GetTemplateParameterInsights(ctx context.Context, arg database.GetTemplateParameterInsightsParams) ([]database.GetTemplateParameterInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -5623,6 +6462,21 @@ func (mr *MockStoreMockRecorder) GetTemplateParameterInsights(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateParameterInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateParameterInsights), ctx, arg)
 }
 
+// GetTemplatePresetCohortsByTemplateID mocks base method.
+func (m *MockStore) GetTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplatePresetCohort, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplatePresetCohortsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplatePresetCohort)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplatePresetCohortsByTemplateID indicates an expected call of GetTemplatePresetCohortsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplatePresetCohortsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplatePresetCohortsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplatePresetCohortsByTemplateID), ctx, templateID)
+}
+
 // GetTemplatePresetsWithPrebuilds mocks base method.
 func (m *MockStore) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
 	m.ctrl.T.Helper()
@@ -5653,68 +6507,158 @@ func (mr *MockStoreMockRecorder) GetTemplateRankingSignalsByOwnerID(ctx, arg any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateRankingSignalsByOwnerID", reflect.TypeOf((*MockStore)(nil).GetTemplateRankingSignalsByOwnerID), ctx, arg)
 }
 
-// GetTemplateUsageStats mocks base method.
-func (m *MockStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
+// GetTemplateSecretByID mocks base method.
+func (m *MockStore) GetTemplateSecretByID(ctx context.Context, id uuid.UUID) (database.TemplateSecret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTemplateUsageStats", ctx, arg)
-	ret0, _ := ret[0].([]database.TemplateUsageStat)
+	ret := m.ctrl.Call(m, "GetTemplateSecretByID", ctx, id)
+	ret0, _ := ret[0].(database.TemplateSecret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTemplateUsageStats indicates an expected call of GetTemplateUsageStats.
-func (mr *MockStoreMockRecorder) GetTemplateUsageStats(ctx, arg any) *gomock.Call {
+// GetTemplateSecretByID indicates an expected call of GetTemplateSecretByID.
+func (mr *MockStoreMockRecorder) GetTemplateSecretByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUsageStats", reflect.TypeOf((*MockStore)(nil).GetTemplateUsageStats), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateSecretByID", reflect.TypeOf((*MockStore)(nil).GetTemplateSecretByID), ctx, id)
 }
 
-// GetTemplateUserRoles mocks base method.
-func (m *MockStore) GetTemplateUserRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateUser, error) {
+// GetTemplateSecretByTemplateIDAndName mocks base method.
+func (m *MockStore) GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.GetTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTemplateUserRoles", ctx, id)
-	ret0, _ := ret[0].([]database.TemplateUser)
+	ret := m.ctrl.Call(m, "GetTemplateSecretByTemplateIDAndName", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateSecret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTemplateUserRoles indicates an expected call of GetTemplateUserRoles.
-func (mr *MockStoreMockRecorder) GetTemplateUserRoles(ctx, id any) *gomock.Call {
+// GetTemplateSecretByTemplateIDAndName indicates an expected call of GetTemplateSecretByTemplateIDAndName.
+func (mr *MockStoreMockRecorder) GetTemplateSecretByTemplateIDAndName(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUserRoles", reflect.TypeOf((*MockStore)(nil).GetTemplateUserRoles), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateSecretByTemplateIDAndName", reflect.TypeOf((*MockStore)(nil).GetTemplateSecretByTemplateIDAndName), ctx, arg)
 }
 
-// GetTemplateVersionByID mocks base method.
-func (m *MockStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersion, error) {
+// GetTemplateSecrets mocks base method.
+func (m *MockStore) GetTemplateSecrets(ctx context.Context) ([]database.TemplateSecret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTemplateVersionByID", ctx, id)
-	ret0, _ := ret[0].(database.TemplateVersion)
+	ret := m.ctrl.Call(m, "GetTemplateSecrets", ctx)
+	ret0, _ := ret[0].([]database.TemplateSecret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTemplateVersionByID indicates an expected call of GetTemplateVersionByID.
-func (mr *MockStoreMockRecorder) GetTemplateVersionByID(ctx, id any) *gomock.Call {
+// GetTemplateSecrets indicates an expected call of GetTemplateSecrets.
+func (mr *MockStoreMockRecorder) GetTemplateSecrets(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionByID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateSecrets", reflect.TypeOf((*MockStore)(nil).GetTemplateSecrets), ctx)
 }
 
-// GetTemplateVersionByJobID mocks base method.
-func (m *MockStore) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (database.TemplateVersion, error) {
+// GetTemplateSecretsByTemplateID mocks base method.
+func (m *MockStore) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateSecret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTemplateVersionByJobID", ctx, jobID)
-	ret0, _ := ret[0].(database.TemplateVersion)
+	ret := m.ctrl.Call(m, "GetTemplateSecretsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplateSecret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTemplateVersionByJobID indicates an expected call of GetTemplateVersionByJobID.
-func (mr *MockStoreMockRecorder) GetTemplateVersionByJobID(ctx, jobID any) *gomock.Call {
+// GetTemplateSecretsByTemplateID indicates an expected call of GetTemplateSecretsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateSecretsByTemplateID(ctx, templateID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionByJobID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionByJobID), ctx, jobID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateSecretsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateSecretsByTemplateID), ctx, templateID)
 }
 
-// GetTemplateVersionByTemplateIDAndName mocks base method.
-func (m *MockStore) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg database.GetTemplateVersionByTemplateIDAndNameParams) (database.TemplateVersion, error) {
+// GetTemplateUsageStats mocks base method.
+func (m *MockStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateUsageStats", ctx, arg)
+	ret0, _ := ret[0].([]database.TemplateUsageStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateUsageStats indicates an expected call of GetTemplateUsageStats.
+func (mr *MockStoreMockRecorder) GetTemplateUsageStats(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUsageStats", reflect.TypeOf((*MockStore)(nil).GetTemplateUsageStats), ctx, arg)
+}
+
+// GetTemplateUserPresetByID mocks base method.
+func (m *MockStore) GetTemplateUserPresetByID(ctx context.Context, id uuid.UUID) (database.TemplateUserPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateUserPresetByID", ctx, id)
+	ret0, _ := ret[0].(database.TemplateUserPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateUserPresetByID indicates an expected call of GetTemplateUserPresetByID.
+func (mr *MockStoreMockRecorder) GetTemplateUserPresetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUserPresetByID", reflect.TypeOf((*MockStore)(nil).GetTemplateUserPresetByID), ctx, id)
+}
+
+// GetTemplateUserPresetsByTemplateIDAndUserID mocks base method.
+func (m *MockStore) GetTemplateUserPresetsByTemplateIDAndUserID(ctx context.Context, arg database.GetTemplateUserPresetsByTemplateIDAndUserIDParams) ([]database.TemplateUserPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateUserPresetsByTemplateIDAndUserID", ctx, arg)
+	ret0, _ := ret[0].([]database.TemplateUserPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateUserPresetsByTemplateIDAndUserID indicates an expected call of GetTemplateUserPresetsByTemplateIDAndUserID.
+func (mr *MockStoreMockRecorder) GetTemplateUserPresetsByTemplateIDAndUserID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUserPresetsByTemplateIDAndUserID", reflect.TypeOf((*MockStore)(nil).GetTemplateUserPresetsByTemplateIDAndUserID), ctx, arg)
+}
+
+// GetTemplateUserRoles mocks base method.
+func (m *MockStore) GetTemplateUserRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateUserRoles", ctx, id)
+	ret0, _ := ret[0].([]database.TemplateUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateUserRoles indicates an expected call of GetTemplateUserRoles.
+func (mr *MockStoreMockRecorder) GetTemplateUserRoles(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUserRoles", reflect.TypeOf((*MockStore)(nil).GetTemplateUserRoles), ctx, id)
+}
+
+// GetTemplateVersionByID mocks base method.
+func (m *MockStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionByID", ctx, id)
+	ret0, _ := ret[0].(database.TemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionByID indicates an expected call of GetTemplateVersionByID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionByID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionByID), ctx, id)
+}
+
+// GetTemplateVersionByJobID mocks base method.
+func (m *MockStore) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (database.TemplateVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionByJobID", ctx, jobID)
+	ret0, _ := ret[0].(database.TemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionByJobID indicates an expected call of GetTemplateVersionByJobID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionByJobID(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionByJobID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionByJobID), ctx, jobID)
+}
+
+// GetTemplateVersionByTemplateIDAndName mocks base method.
+func (m *MockStore) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg database.GetTemplateVersionByTemplateIDAndNameParams) (database.TemplateVersion, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTemplateVersionByTemplateIDAndName", ctx, arg)
 	ret0, _ := ret[0].(database.TemplateVersion)
@@ -5743,6 +6687,66 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionParameters(ctx, templateVersi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionParameters", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionParameters), ctx, templateVersionID)
 }
 
+// GetTemplateVersionRolloutBuildStats mocks base method.
+func (m *MockStore) GetTemplateVersionRolloutBuildStats(ctx context.Context, arg database.GetTemplateVersionRolloutBuildStatsParams) (database.GetTemplateVersionRolloutBuildStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionRolloutBuildStats", ctx, arg)
+	ret0, _ := ret[0].(database.GetTemplateVersionRolloutBuildStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionRolloutBuildStats indicates an expected call of GetTemplateVersionRolloutBuildStats.
+func (mr *MockStoreMockRecorder) GetTemplateVersionRolloutBuildStats(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionRolloutBuildStats", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionRolloutBuildStats), ctx, arg)
+}
+
+// GetTemplateVersionRolloutByID mocks base method.
+func (m *MockStore) GetTemplateVersionRolloutByID(ctx context.Context, id uuid.UUID) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionRolloutByID", ctx, id)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionRolloutByID indicates an expected call of GetTemplateVersionRolloutByID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionRolloutByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionRolloutByID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionRolloutByID), ctx, id)
+}
+
+// GetTemplateVersionRolloutsByTemplateID mocks base method.
+func (m *MockStore) GetTemplateVersionRolloutsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionRolloutsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionRolloutsByTemplateID indicates an expected call of GetTemplateVersionRolloutsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionRolloutsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionRolloutsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionRolloutsByTemplateID), ctx, templateID)
+}
+
+// GetTemplateVersionScanByTemplateVersionID mocks base method.
+func (m *MockStore) GetTemplateVersionScanByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionScan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionScanByTemplateVersionID", ctx, templateVersionID)
+	ret0, _ := ret[0].(database.TemplateVersionScan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionScanByTemplateVersionID indicates an expected call of GetTemplateVersionScanByTemplateVersionID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionScanByTemplateVersionID(ctx, templateVersionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionScanByTemplateVersionID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionScanByTemplateVersionID), ctx, templateVersionID)
+}
+
 // GetTemplateVersionTerraformValues mocks base method.
 func (m *MockStore) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	m.ctrl.T.Helper()
@@ -5833,6 +6837,66 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionsCreatedAfter(ctx, createdAt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionsCreatedAfter), ctx, createdAt)
 }
 
+// GetTemplateWorkspaceRestartBuildStats mocks base method.
+func (m *MockStore) GetTemplateWorkspaceRestartBuildStats(ctx context.Context, arg database.GetTemplateWorkspaceRestartBuildStatsParams) (database.GetTemplateWorkspaceRestartBuildStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateWorkspaceRestartBuildStats", ctx, arg)
+	ret0, _ := ret[0].(database.GetTemplateWorkspaceRestartBuildStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateWorkspaceRestartBuildStats indicates an expected call of GetTemplateWorkspaceRestartBuildStats.
+func (mr *MockStoreMockRecorder) GetTemplateWorkspaceRestartBuildStats(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateWorkspaceRestartBuildStats", reflect.TypeOf((*MockStore)(nil).GetTemplateWorkspaceRestartBuildStats), ctx, arg)
+}
+
+// GetTemplateWorkspaceRestartByID mocks base method.
+func (m *MockStore) GetTemplateWorkspaceRestartByID(ctx context.Context, id uuid.UUID) (database.TemplateWorkspaceRestart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateWorkspaceRestartByID", ctx, id)
+	ret0, _ := ret[0].(database.TemplateWorkspaceRestart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateWorkspaceRestartByID indicates an expected call of GetTemplateWorkspaceRestartByID.
+func (mr *MockStoreMockRecorder) GetTemplateWorkspaceRestartByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateWorkspaceRestartByID", reflect.TypeOf((*MockStore)(nil).GetTemplateWorkspaceRestartByID), ctx, id)
+}
+
+// GetTemplateWorkspaceRestartCandidates mocks base method.
+func (m *MockStore) GetTemplateWorkspaceRestartCandidates(ctx context.Context, arg database.GetTemplateWorkspaceRestartCandidatesParams) ([]database.GetTemplateWorkspaceRestartCandidatesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateWorkspaceRestartCandidates", ctx, arg)
+	ret0, _ := ret[0].([]database.GetTemplateWorkspaceRestartCandidatesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateWorkspaceRestartCandidates indicates an expected call of GetTemplateWorkspaceRestartCandidates.
+func (mr *MockStoreMockRecorder) GetTemplateWorkspaceRestartCandidates(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateWorkspaceRestartCandidates", reflect.TypeOf((*MockStore)(nil).GetTemplateWorkspaceRestartCandidates), ctx, arg)
+}
+
+// GetTemplateWorkspaceRestartsByTemplateID mocks base method.
+func (m *MockStore) GetTemplateWorkspaceRestartsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateWorkspaceRestart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateWorkspaceRestartsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplateWorkspaceRestart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateWorkspaceRestartsByTemplateID indicates an expected call of GetTemplateWorkspaceRestartsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateWorkspaceRestartsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateWorkspaceRestartsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateWorkspaceRestartsByTemplateID), ctx, templateID)
+}
+
 // GetTemplates mocks base method.
 func (m *MockStore) GetTemplates(ctx context.Context) ([]database.Template, error) {
 	m.ctrl.T.Helper()
@@ -5893,6 +6957,21 @@ func (mr *MockStoreMockRecorder) GetUnexpiredLicenses(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnexpiredLicenses", reflect.TypeOf((*MockStore)(nil).GetUnexpiredLicenses), ctx)
 }
 
+// GetUnverifiedWorkspaceCloudResources mocks base method.
+func (m *MockStore) GetUnverifiedWorkspaceCloudResources(ctx context.Context, arg database.GetUnverifiedWorkspaceCloudResourcesParams) ([]database.GetUnverifiedWorkspaceCloudResourcesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnverifiedWorkspaceCloudResources", ctx, arg)
+	ret0, _ := ret[0].([]database.GetUnverifiedWorkspaceCloudResourcesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnverifiedWorkspaceCloudResources indicates an expected call of GetUnverifiedWorkspaceCloudResources.
+func (mr *MockStoreMockRecorder) GetUnverifiedWorkspaceCloudResources(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnverifiedWorkspaceCloudResources", reflect.TypeOf((*MockStore)(nil).GetUnverifiedWorkspaceCloudResources), ctx, arg)
+}
+
 // GetUserAIBudgetOverride mocks base method.
 func (m *MockStore) GetUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	m.ctrl.T.Helper()
@@ -6208,6 +7287,51 @@ func (mr *MockStoreMockRecorder) GetUserGroupSpendLimit(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGroupSpendLimit", reflect.TypeOf((*MockStore)(nil).GetUserGroupSpendLimit), ctx, arg)
 }
 
+// GetUserImpersonationByAPIKeyID mocks base method.
+func (m *MockStore) GetUserImpersonationByAPIKeyID(ctx context.Context, apiKeyID string) (database.UserImpersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserImpersonationByAPIKeyID", ctx, apiKeyID)
+	ret0, _ := ret[0].(database.UserImpersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserImpersonationByAPIKeyID indicates an expected call of GetUserImpersonationByAPIKeyID.
+func (mr *MockStoreMockRecorder) GetUserImpersonationByAPIKeyID(ctx, apiKeyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserImpersonationByAPIKeyID", reflect.TypeOf((*MockStore)(nil).GetUserImpersonationByAPIKeyID), ctx, apiKeyID)
+}
+
+// GetUserImpersonationByID mocks base method.
+func (m *MockStore) GetUserImpersonationByID(ctx context.Context, id uuid.UUID) (database.UserImpersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserImpersonationByID", ctx, id)
+	ret0, _ := ret[0].(database.UserImpersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserImpersonationByID indicates an expected call of GetUserImpersonationByID.
+func (mr *MockStoreMockRecorder) GetUserImpersonationByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserImpersonationByID", reflect.TypeOf((*MockStore)(nil).GetUserImpersonationByID), ctx, id)
+}
+
+// GetUserImpersonationsByUserID mocks base method.
+func (m *MockStore) GetUserImpersonationsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserImpersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserImpersonationsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]database.UserImpersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserImpersonationsByUserID indicates an expected call of GetUserImpersonationsByUserID.
+func (mr *MockStoreMockRecorder) GetUserImpersonationsByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserImpersonationsByUserID", reflect.TypeOf((*MockStore)(nil).GetUserImpersonationsByUserID), ctx, userID)
+}
+
 // GetUserLatencyInsights mocks base method.
 func (m *MockStore) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -6283,6 +7407,36 @@ func (mr *MockStoreMockRecorder) GetUserNotificationPreferences(ctx, userID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationPreferences", reflect.TypeOf((*MockStore)(nil).GetUserNotificationPreferences), ctx, userID)
 }
 
+// GetUserNotificationScopedPreferences mocks base method.
+func (m *MockStore) GetUserNotificationScopedPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationScopedPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationScopedPreferences", ctx, userID)
+	ret0, _ := ret[0].([]database.NotificationScopedPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationScopedPreferences indicates an expected call of GetUserNotificationScopedPreferences.
+func (mr *MockStoreMockRecorder) GetUserNotificationScopedPreferences(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationScopedPreferences", reflect.TypeOf((*MockStore)(nil).GetUserNotificationScopedPreferences), ctx, userID)
+}
+
+// GetUserSchedulePause mocks base method.
+func (m *MockStore) GetUserSchedulePause(ctx context.Context, userID uuid.UUID) (database.UserSchedulePause, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSchedulePause", ctx, userID)
+	ret0, _ := ret[0].(database.UserSchedulePause)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSchedulePause indicates an expected call of GetUserSchedulePause.
+func (mr *MockStoreMockRecorder) GetUserSchedulePause(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSchedulePause", reflect.TypeOf((*MockStore)(nil).GetUserSchedulePause), ctx, userID)
+}
+
 // GetUserSecretByID mocks base method.
 func (m *MockStore) GetUserSecretByID(ctx context.Context, id uuid.UUID) (database.UserSecret, error) {
 	m.ctrl.T.Helper()
@@ -6418,6 +7572,36 @@ func (mr *MockStoreMockRecorder) GetUserWorkspaceBuildParameters(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).GetUserWorkspaceBuildParameters), ctx, arg)
 }
 
+// GetUserWorkspaceReportStats mocks base method.
+func (m *MockStore) GetUserWorkspaceReportStats(ctx context.Context, arg database.GetUserWorkspaceReportStatsParams) ([]database.GetUserWorkspaceReportStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWorkspaceReportStats", ctx, arg)
+	ret0, _ := ret[0].([]database.GetUserWorkspaceReportStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWorkspaceReportStats indicates an expected call of GetUserWorkspaceReportStats.
+func (mr *MockStoreMockRecorder) GetUserWorkspaceReportStats(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWorkspaceReportStats", reflect.TypeOf((*MockStore)(nil).GetUserWorkspaceReportStats), ctx, arg)
+}
+
+// GetUserWorkspaceSelfRequestedBuildsAllowed mocks base method.
+func (m *MockStore) GetUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWorkspaceSelfRequestedBuildsAllowed", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWorkspaceSelfRequestedBuildsAllowed indicates an expected call of GetUserWorkspaceSelfRequestedBuildsAllowed.
+func (mr *MockStoreMockRecorder) GetUserWorkspaceSelfRequestedBuildsAllowed(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWorkspaceSelfRequestedBuildsAllowed", reflect.TypeOf((*MockStore)(nil).GetUserWorkspaceSelfRequestedBuildsAllowed), ctx, userID)
+}
+
 // GetUsers mocks base method.
 func (m *MockStore) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	m.ctrl.T.Helper()
@@ -6448,6 +7632,66 @@ func (mr *MockStoreMockRecorder) GetUsersByIDs(ctx, ids any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockStore)(nil).GetUsersByIDs), ctx, ids)
 }
 
+// GetVCSEventMappingByID mocks base method.
+func (m *MockStore) GetVCSEventMappingByID(ctx context.Context, id uuid.UUID) (database.VCSEventMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVCSEventMappingByID", ctx, id)
+	ret0, _ := ret[0].(database.VCSEventMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVCSEventMappingByID indicates an expected call of GetVCSEventMappingByID.
+func (mr *MockStoreMockRecorder) GetVCSEventMappingByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVCSEventMappingByID", reflect.TypeOf((*MockStore)(nil).GetVCSEventMappingByID), ctx, id)
+}
+
+// GetVCSEventMappings mocks base method.
+func (m *MockStore) GetVCSEventMappings(ctx context.Context) ([]database.VCSEventMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVCSEventMappings", ctx)
+	ret0, _ := ret[0].([]database.VCSEventMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVCSEventMappings indicates an expected call of GetVCSEventMappings.
+func (mr *MockStoreMockRecorder) GetVCSEventMappings(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVCSEventMappings", reflect.TypeOf((*MockStore)(nil).GetVCSEventMappings), ctx)
+}
+
+// GetVCSEventMappingsByRepository mocks base method.
+func (m *MockStore) GetVCSEventMappingsByRepository(ctx context.Context, repository string) ([]database.VCSEventMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVCSEventMappingsByRepository", ctx, repository)
+	ret0, _ := ret[0].([]database.VCSEventMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVCSEventMappingsByRepository indicates an expected call of GetVCSEventMappingsByRepository.
+func (mr *MockStoreMockRecorder) GetVCSEventMappingsByRepository(ctx, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVCSEventMappingsByRepository", reflect.TypeOf((*MockStore)(nil).GetVCSEventMappingsByRepository), ctx, repository)
+}
+
+// GetVCSEventMappingsByTemplateID mocks base method.
+func (m *MockStore) GetVCSEventMappingsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.VCSEventMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVCSEventMappingsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.VCSEventMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVCSEventMappingsByTemplateID indicates an expected call of GetVCSEventMappingsByTemplateID.
+func (mr *MockStoreMockRecorder) GetVCSEventMappingsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVCSEventMappingsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetVCSEventMappingsByTemplateID), ctx, templateID)
+}
+
 // GetWebpushSubscriptionsByUserID mocks base method.
 func (m *MockStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	m.ctrl.T.Helper()
@@ -6508,6 +7752,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentAndWorkspaceByID(ctx, id any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentAndWorkspaceByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentAndWorkspaceByID), ctx, id)
 }
 
+// GetWorkspaceAgentBootstrapProgressByAgentID mocks base method.
+func (m *MockStore) GetWorkspaceAgentBootstrapProgressByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentBootstrapProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentBootstrapProgressByAgentID", ctx, workspaceAgentID)
+	ret0, _ := ret[0].([]database.WorkspaceAgentBootstrapProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentBootstrapProgressByAgentID indicates an expected call of GetWorkspaceAgentBootstrapProgressByAgentID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentBootstrapProgressByAgentID(ctx, workspaceAgentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentBootstrapProgressByAgentID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentBootstrapProgressByAgentID), ctx, workspaceAgentID)
+}
+
 // GetWorkspaceAgentByID mocks base method.
 func (m *MockStore) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgent, error) {
 	m.ctrl.T.Helper()
@@ -6523,6 +7782,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentByID), ctx, id)
 }
 
+// GetWorkspaceAgentCrashLoopByAgentID mocks base method.
+func (m *MockStore) GetWorkspaceAgentCrashLoopByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentCrashLoop, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentCrashLoopByAgentID", ctx, agentID)
+	ret0, _ := ret[0].(database.WorkspaceAgentCrashLoop)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentCrashLoopByAgentID indicates an expected call of GetWorkspaceAgentCrashLoopByAgentID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentCrashLoopByAgentID(ctx, agentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentCrashLoopByAgentID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentCrashLoopByAgentID), ctx, agentID)
+}
+
+// GetWorkspaceAgentCrashLoopsByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentCrashLoopsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentCrashLoop, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentCrashLoopsByAgentIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.WorkspaceAgentCrashLoop)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentCrashLoopsByAgentIDs indicates an expected call of GetWorkspaceAgentCrashLoopsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentCrashLoopsByAgentIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentCrashLoopsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentCrashLoopsByAgentIDs), ctx, ids)
+}
+
 // GetWorkspaceAgentDevcontainersByAgentID mocks base method.
 func (m *MockStore) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	m.ctrl.T.Helper()
@@ -6583,6 +7872,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentLogsAfter(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentLogsAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentLogsAfter), ctx, arg)
 }
 
+// GetWorkspaceAgentMemoryEventCountsByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentMemoryEventCountsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentMemoryEventCountsByAgentIDsParams) ([]database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentMemoryEventCountsByAgentIDs", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentMemoryEventCountsByAgentIDs indicates an expected call of GetWorkspaceAgentMemoryEventCountsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentMemoryEventCountsByAgentIDs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMemoryEventCountsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMemoryEventCountsByAgentIDs), ctx, arg)
+}
+
+// GetWorkspaceAgentMemoryEventsByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentMemoryEventsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentMemoryEventsByAgentIDsParams) ([]database.WorkspaceAgentMemoryEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentMemoryEventsByAgentIDs", ctx, arg)
+	ret0, _ := ret[0].([]database.WorkspaceAgentMemoryEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentMemoryEventsByAgentIDs indicates an expected call of GetWorkspaceAgentMemoryEventsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentMemoryEventsByAgentIDs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMemoryEventsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMemoryEventsByAgentIDs), ctx, arg)
+}
+
 // GetWorkspaceAgentMetadata mocks base method.
 func (m *MockStore) GetWorkspaceAgentMetadata(ctx context.Context, arg database.GetWorkspaceAgentMetadataParams) ([]database.WorkspaceAgentMetadatum, error) {
 	m.ctrl.T.Helper()
@@ -6598,6 +7917,51 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentMetadata(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMetadata), ctx, arg)
 }
 
+// GetWorkspaceAgentMetadataHistory mocks base method.
+func (m *MockStore) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.GetWorkspaceAgentMetadataHistoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentMetadataHistory", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentMetadataHistoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentMetadataHistory indicates an expected call of GetWorkspaceAgentMetadataHistory.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentMetadataHistory(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMetadataHistory", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMetadataHistory), ctx, arg)
+}
+
+// GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsParams) ([]database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs indicates an expected call of GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs), ctx, arg)
+}
+
+// GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(ctx context.Context, arg database.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDsParams) ([]database.WorkspaceAgentNetworkPolicyViolation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs", ctx, arg)
+	ret0, _ := ret[0].([]database.WorkspaceAgentNetworkPolicyViolation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs indicates an expected call of GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs), ctx, arg)
+}
+
 // GetWorkspaceAgentPortShare mocks base method.
 func (m *MockStore) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	m.ctrl.T.Helper()
@@ -6643,6 +8007,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentScriptsByAgentIDs(ctx, ids any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentScriptsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentScriptsByAgentIDs), ctx, ids)
 }
 
+// GetWorkspaceAgentServiceTransitionsByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentServiceTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentServiceTransitionsByAgentIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.WorkspaceAgentServiceTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentServiceTransitionsByAgentIDs indicates an expected call of GetWorkspaceAgentServiceTransitionsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentServiceTransitionsByAgentIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentServiceTransitionsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentServiceTransitionsByAgentIDs), ctx, ids)
+}
+
+// GetWorkspaceAgentServicesByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentServicesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentServicesByAgentIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.WorkspaceAgentService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentServicesByAgentIDs indicates an expected call of GetWorkspaceAgentServicesByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentServicesByAgentIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentServicesByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentServicesByAgentIDs), ctx, ids)
+}
+
 // GetWorkspaceAgentStats mocks base method.
 func (m *MockStore) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	m.ctrl.T.Helper()
@@ -6838,6 +8232,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAppByAgentIDAndSlug(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAppByAgentIDAndSlug", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAppByAgentIDAndSlug), ctx, arg)
 }
 
+// GetWorkspaceAppShareLinkByID mocks base method.
+func (m *MockStore) GetWorkspaceAppShareLinkByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAppShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAppShareLinkByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceAppShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAppShareLinkByID indicates an expected call of GetWorkspaceAppShareLinkByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAppShareLinkByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAppShareLinkByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAppShareLinkByID), ctx, id)
+}
+
+// GetWorkspaceAppShareLinksByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceAppShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAppShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAppShareLinksByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspaceAppShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAppShareLinksByWorkspaceID indicates an expected call of GetWorkspaceAppShareLinksByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAppShareLinksByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAppShareLinksByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAppShareLinksByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceAppStatusesByAppIDs mocks base method.
 func (m *MockStore) GetWorkspaceAppStatusesByAppIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAppStatus, error) {
 	m.ctrl.T.Helper()
@@ -6913,6 +8337,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildAgentsByInstanceID(ctx, authIn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildAgentsByInstanceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildAgentsByInstanceID), ctx, authInstanceID)
 }
 
+// GetWorkspaceBuildAnnotationsByBuildIDs mocks base method.
+func (m *MockStore) GetWorkspaceBuildAnnotationsByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]database.GetWorkspaceBuildAnnotationsByBuildIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildAnnotationsByBuildIDs", ctx, workspaceBuildIds)
+	ret0, _ := ret[0].([]database.GetWorkspaceBuildAnnotationsByBuildIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildAnnotationsByBuildIDs indicates an expected call of GetWorkspaceBuildAnnotationsByBuildIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildAnnotationsByBuildIDs(ctx, workspaceBuildIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildAnnotationsByBuildIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildAnnotationsByBuildIDs), ctx, workspaceBuildIds)
+}
+
 // GetWorkspaceBuildByID mocks base method.
 func (m *MockStore) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
@@ -6946,16 +8385,31 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildByJobID(ctx, jobID any) *gomoc
 // GetWorkspaceBuildByWorkspaceIDAndBuildNumber mocks base method.
 func (m *MockStore) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWorkspaceBuildByWorkspaceIDAndBuildNumber", ctx, arg)
-	ret0, _ := ret[0].(database.WorkspaceBuild)
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildByWorkspaceIDAndBuildNumber", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceBuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildByWorkspaceIDAndBuildNumber indicates an expected call of GetWorkspaceBuildByWorkspaceIDAndBuildNumber.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildByWorkspaceIDAndBuildNumber", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildByWorkspaceIDAndBuildNumber), ctx, arg)
+}
+
+// GetWorkspaceBuildInputsByBuildID mocks base method.
+func (m *MockStore) GetWorkspaceBuildInputsByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (database.WorkspaceBuildInput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildInputsByBuildID", ctx, workspaceBuildID)
+	ret0, _ := ret[0].(database.WorkspaceBuildInput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetWorkspaceBuildByWorkspaceIDAndBuildNumber indicates an expected call of GetWorkspaceBuildByWorkspaceIDAndBuildNumber.
-func (mr *MockStoreMockRecorder) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, arg any) *gomock.Call {
+// GetWorkspaceBuildInputsByBuildID indicates an expected call of GetWorkspaceBuildInputsByBuildID.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildInputsByBuildID(ctx, workspaceBuildID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildByWorkspaceIDAndBuildNumber", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildByWorkspaceIDAndBuildNumber), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildInputsByBuildID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildInputsByBuildID), ctx, workspaceBuildID)
 }
 
 // GetWorkspaceBuildMetricsByResourceID mocks base method.
@@ -6973,6 +8427,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildMetricsByResourceID(ctx, id an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildMetricsByResourceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildMetricsByResourceID), ctx, id)
 }
 
+// GetWorkspaceBuildParameterChangesByBuildIDs mocks base method.
+func (m *MockStore) GetWorkspaceBuildParameterChangesByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]database.WorkspaceBuildParameterChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildParameterChangesByBuildIDs", ctx, workspaceBuildIds)
+	ret0, _ := ret[0].([]database.WorkspaceBuildParameterChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildParameterChangesByBuildIDs indicates an expected call of GetWorkspaceBuildParameterChangesByBuildIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildParameterChangesByBuildIDs(ctx, workspaceBuildIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildParameterChangesByBuildIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildParameterChangesByBuildIDs), ctx, workspaceBuildIds)
+}
+
 // GetWorkspaceBuildParameters mocks base method.
 func (m *MockStore) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	m.ctrl.T.Helper()
@@ -7123,6 +8592,126 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(ctx, workspaceAppI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), ctx, workspaceAppID)
 }
 
+// GetWorkspaceCloudResourcesByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceCloudResourcesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceCloudResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceCloudResourcesByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspaceCloudResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceCloudResourcesByWorkspaceID indicates an expected call of GetWorkspaceCloudResourcesByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceCloudResourcesByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCloudResourcesByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCloudResourcesByWorkspaceID), ctx, workspaceID)
+}
+
+// GetWorkspaceDNSRecordChanges mocks base method.
+func (m *MockStore) GetWorkspaceDNSRecordChanges(ctx context.Context, arg database.GetWorkspaceDNSRecordChangesParams) ([]database.GetWorkspaceDNSRecordChangesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceDNSRecordChanges", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceDNSRecordChangesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceDNSRecordChanges indicates an expected call of GetWorkspaceDNSRecordChanges.
+func (mr *MockStoreMockRecorder) GetWorkspaceDNSRecordChanges(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceDNSRecordChanges", reflect.TypeOf((*MockStore)(nil).GetWorkspaceDNSRecordChanges), ctx, arg)
+}
+
+// GetWorkspaceDNSRecordsByWorkspaceIDs mocks base method.
+func (m *MockStore) GetWorkspaceDNSRecordsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceDNSRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceDNSRecordsByWorkspaceIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.WorkspaceDNSRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceDNSRecordsByWorkspaceIDs indicates an expected call of GetWorkspaceDNSRecordsByWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceDNSRecordsByWorkspaceIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceDNSRecordsByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceDNSRecordsByWorkspaceIDs), ctx, ids)
+}
+
+// GetWorkspaceDormancyExemptionByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceDormancyExemptionByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceDormancyExemption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceDormancyExemptionByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceDormancyExemption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceDormancyExemptionByWorkspaceID indicates an expected call of GetWorkspaceDormancyExemptionByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceDormancyExemptionByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceDormancyExemptionByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceDormancyExemptionByWorkspaceID), ctx, workspaceID)
+}
+
+// GetWorkspaceDormancyHookByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceDormancyHookByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceDormancyHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceDormancyHookByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceDormancyHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceDormancyHookByWorkspaceID indicates an expected call of GetWorkspaceDormancyHookByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceDormancyHookByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceDormancyHookByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceDormancyHookByWorkspaceID), ctx, workspaceID)
+}
+
+// GetWorkspaceEgressInsights mocks base method.
+func (m *MockStore) GetWorkspaceEgressInsights(ctx context.Context, arg database.GetWorkspaceEgressInsightsParams) ([]database.GetWorkspaceEgressInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceEgressInsights", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceEgressInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceEgressInsights indicates an expected call of GetWorkspaceEgressInsights.
+func (mr *MockStoreMockRecorder) GetWorkspaceEgressInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceEgressInsights", reflect.TypeOf((*MockStore)(nil).GetWorkspaceEgressInsights), ctx, arg)
+}
+
+// GetWorkspaceLeaseByID mocks base method.
+func (m *MockStore) GetWorkspaceLeaseByID(ctx context.Context, id uuid.UUID) (database.WorkspaceLease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceLeaseByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceLease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceLeaseByID indicates an expected call of GetWorkspaceLeaseByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceLeaseByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceLeaseByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceLeaseByID), ctx, id)
+}
+
+// GetWorkspaceLifetimeWarningByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceLifetimeWarningByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceLifetimeWarning, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceLifetimeWarningByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceLifetimeWarning)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceLifetimeWarningByWorkspaceID indicates an expected call of GetWorkspaceLifetimeWarningByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceLifetimeWarningByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceLifetimeWarningByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceLifetimeWarningByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceModulesByJobID mocks base method.
 func (m *MockStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	m.ctrl.T.Helper()
@@ -7153,6 +8742,66 @@ func (mr *MockStoreMockRecorder) GetWorkspaceModulesCreatedAfter(ctx, createdAt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceModulesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceModulesCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspaceMonthlyCost mocks base method.
+func (m *MockStore) GetWorkspaceMonthlyCost(ctx context.Context, arg database.GetWorkspaceMonthlyCostParams) (database.WorkspaceMonthlyCost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceMonthlyCost", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceMonthlyCost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceMonthlyCost indicates an expected call of GetWorkspaceMonthlyCost.
+func (mr *MockStoreMockRecorder) GetWorkspaceMonthlyCost(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceMonthlyCost", reflect.TypeOf((*MockStore)(nil).GetWorkspaceMonthlyCost), ctx, arg)
+}
+
+// GetWorkspaceNetworkPolicy mocks base method.
+func (m *MockStore) GetWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceNetworkPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceNetworkPolicy", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceNetworkPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceNetworkPolicy indicates an expected call of GetWorkspaceNetworkPolicy.
+func (mr *MockStoreMockRecorder) GetWorkspaceNetworkPolicy(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceNetworkPolicy", reflect.TypeOf((*MockStore)(nil).GetWorkspaceNetworkPolicy), ctx, workspaceID)
+}
+
+// GetWorkspacePortForwardACL mocks base method.
+func (m *MockStore) GetWorkspacePortForwardACL(ctx context.Context, arg database.GetWorkspacePortForwardACLParams) (database.WorkspacePortForwardACL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePortForwardACL", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspacePortForwardACL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePortForwardACL indicates an expected call of GetWorkspacePortForwardACL.
+func (mr *MockStoreMockRecorder) GetWorkspacePortForwardACL(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePortForwardACL", reflect.TypeOf((*MockStore)(nil).GetWorkspacePortForwardACL), ctx, arg)
+}
+
+// GetWorkspacePortForwardACLsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortForwardACL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePortForwardACLsByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspacePortForwardACL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePortForwardACLsByWorkspaceID indicates an expected call of GetWorkspacePortForwardACLsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspacePortForwardACLsByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePortForwardACLsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspacePortForwardACLsByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceProxies mocks base method.
 func (m *MockStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -7213,6 +8862,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceProxyByName(ctx, name any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceProxyByName", reflect.TypeOf((*MockStore)(nil).GetWorkspaceProxyByName), ctx, name)
 }
 
+// GetWorkspaceRegionAssignmentCounts mocks base method.
+func (m *MockStore) GetWorkspaceRegionAssignmentCounts(ctx context.Context) ([]database.GetWorkspaceRegionAssignmentCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceRegionAssignmentCounts", ctx)
+	ret0, _ := ret[0].([]database.GetWorkspaceRegionAssignmentCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceRegionAssignmentCounts indicates an expected call of GetWorkspaceRegionAssignmentCounts.
+func (mr *MockStoreMockRecorder) GetWorkspaceRegionAssignmentCounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceRegionAssignmentCounts", reflect.TypeOf((*MockStore)(nil).GetWorkspaceRegionAssignmentCounts), ctx)
+}
+
+// GetWorkspaceRegionAssignmentsByWorkspaceIDs mocks base method.
+func (m *MockStore) GetWorkspaceRegionAssignmentsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceRegionAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceRegionAssignmentsByWorkspaceIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.WorkspaceRegionAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceRegionAssignmentsByWorkspaceIDs indicates an expected call of GetWorkspaceRegionAssignmentsByWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceRegionAssignmentsByWorkspaceIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceRegionAssignmentsByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceRegionAssignmentsByWorkspaceIDs), ctx, ids)
+}
+
 // GetWorkspaceResourceByID mocks base method.
 func (m *MockStore) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	m.ctrl.T.Helper()
@@ -7378,6 +9057,21 @@ func (mr *MockStoreMockRecorder) GetWorkspacesEligibleForLifecycleAction(ctx, no
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacesEligibleForLifecycleAction", reflect.TypeOf((*MockStore)(nil).GetWorkspacesEligibleForLifecycleAction), ctx, now)
 }
 
+// GetWorkspacesExceedingCostBudget mocks base method.
+func (m *MockStore) GetWorkspacesExceedingCostBudget(ctx context.Context, arg database.GetWorkspacesExceedingCostBudgetParams) ([]database.GetWorkspacesExceedingCostBudgetRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacesExceedingCostBudget", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspacesExceedingCostBudgetRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacesExceedingCostBudget indicates an expected call of GetWorkspacesExceedingCostBudget.
+func (mr *MockStoreMockRecorder) GetWorkspacesExceedingCostBudget(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacesExceedingCostBudget", reflect.TypeOf((*MockStore)(nil).GetWorkspacesExceedingCostBudget), ctx, arg)
+}
+
 // GetWorkspacesForWorkspaceMetrics mocks base method.
 func (m *MockStore) GetWorkspacesForWorkspaceMetrics(ctx context.Context) ([]database.GetWorkspacesForWorkspaceMetricsRow, error) {
 	m.ctrl.T.Helper()
@@ -7942,6 +9636,21 @@ func (mr *MockStoreMockRecorder) InsertGroupMember(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroupMember", reflect.TypeOf((*MockStore)(nil).InsertGroupMember), ctx, arg)
 }
 
+// InsertIdempotencyKey mocks base method.
+func (m *MockStore) InsertIdempotencyKey(ctx context.Context, arg database.InsertIdempotencyKeyParams) (database.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(database.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertIdempotencyKey indicates an expected call of InsertIdempotencyKey.
+func (mr *MockStoreMockRecorder) InsertIdempotencyKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIdempotencyKey", reflect.TypeOf((*MockStore)(nil).InsertIdempotencyKey), ctx, arg)
+}
+
 // InsertInboxNotification mocks base method.
 func (m *MockStore) InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
 	m.ctrl.T.Helper()
@@ -8107,6 +9816,21 @@ func (mr *MockStoreMockRecorder) InsertOrganizationMember(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOrganizationMember", reflect.TypeOf((*MockStore)(nil).InsertOrganizationMember), ctx, arg)
 }
 
+// InsertPrebuildClaimAttempt mocks base method.
+func (m *MockStore) InsertPrebuildClaimAttempt(ctx context.Context, arg database.InsertPrebuildClaimAttemptParams) (database.PrebuildClaimAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPrebuildClaimAttempt", ctx, arg)
+	ret0, _ := ret[0].(database.PrebuildClaimAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertPrebuildClaimAttempt indicates an expected call of InsertPrebuildClaimAttempt.
+func (mr *MockStoreMockRecorder) InsertPrebuildClaimAttempt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPrebuildClaimAttempt", reflect.TypeOf((*MockStore)(nil).InsertPrebuildClaimAttempt), ctx, arg)
+}
+
 // InsertPreset mocks base method.
 func (m *MockStore) InsertPreset(ctx context.Context, arg database.InsertPresetParams) (database.TemplateVersionPreset, error) {
 	m.ctrl.T.Helper()
@@ -8167,6 +9891,20 @@ func (mr *MockStoreMockRecorder) InsertProvisionerJob(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJob", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJob), ctx, arg)
 }
 
+// InsertProvisionerJobLogArchive mocks base method.
+func (m *MockStore) InsertProvisionerJobLogArchive(ctx context.Context, arg database.InsertProvisionerJobLogArchiveParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProvisionerJobLogArchive", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertProvisionerJobLogArchive indicates an expected call of InsertProvisionerJobLogArchive.
+func (mr *MockStoreMockRecorder) InsertProvisionerJobLogArchive(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJobLogArchive", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJobLogArchive), ctx, arg)
+}
+
 // InsertProvisionerJobLogs mocks base method.
 func (m *MockStore) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	m.ctrl.T.Helper()
@@ -8227,6 +9965,36 @@ func (mr *MockStoreMockRecorder) InsertReplica(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReplica", reflect.TypeOf((*MockStore)(nil).InsertReplica), ctx, arg)
 }
 
+// InsertSharedVolume mocks base method.
+func (m *MockStore) InsertSharedVolume(ctx context.Context, arg database.InsertSharedVolumeParams) (database.SharedVolume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSharedVolume", ctx, arg)
+	ret0, _ := ret[0].(database.SharedVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSharedVolume indicates an expected call of InsertSharedVolume.
+func (mr *MockStoreMockRecorder) InsertSharedVolume(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSharedVolume", reflect.TypeOf((*MockStore)(nil).InsertSharedVolume), ctx, arg)
+}
+
+// InsertSharedVolumeAttachment mocks base method.
+func (m *MockStore) InsertSharedVolumeAttachment(ctx context.Context, arg database.InsertSharedVolumeAttachmentParams) (database.SharedVolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSharedVolumeAttachment", ctx, arg)
+	ret0, _ := ret[0].(database.SharedVolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSharedVolumeAttachment indicates an expected call of InsertSharedVolumeAttachment.
+func (mr *MockStoreMockRecorder) InsertSharedVolumeAttachment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSharedVolumeAttachment", reflect.TypeOf((*MockStore)(nil).InsertSharedVolumeAttachment), ctx, arg)
+}
+
 // InsertTask mocks base method.
 func (m *MockStore) InsertTask(ctx context.Context, arg database.InsertTaskParams) (database.TaskTable, error) {
 	m.ctrl.T.Helper()
@@ -8284,6 +10052,51 @@ func (mr *MockStoreMockRecorder) InsertTemplate(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplate", reflect.TypeOf((*MockStore)(nil).InsertTemplate), ctx, arg)
 }
 
+// InsertTemplateHealthReport mocks base method.
+func (m *MockStore) InsertTemplateHealthReport(ctx context.Context, arg database.InsertTemplateHealthReportParams) (database.TemplateHealthReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateHealthReport", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateHealthReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateHealthReport indicates an expected call of InsertTemplateHealthReport.
+func (mr *MockStoreMockRecorder) InsertTemplateHealthReport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateHealthReport", reflect.TypeOf((*MockStore)(nil).InsertTemplateHealthReport), ctx, arg)
+}
+
+// InsertTemplatePresetCohort mocks base method.
+func (m *MockStore) InsertTemplatePresetCohort(ctx context.Context, arg database.InsertTemplatePresetCohortParams) (database.TemplatePresetCohort, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplatePresetCohort", ctx, arg)
+	ret0, _ := ret[0].(database.TemplatePresetCohort)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplatePresetCohort indicates an expected call of InsertTemplatePresetCohort.
+func (mr *MockStoreMockRecorder) InsertTemplatePresetCohort(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplatePresetCohort", reflect.TypeOf((*MockStore)(nil).InsertTemplatePresetCohort), ctx, arg)
+}
+
+// InsertTemplateSecret mocks base method.
+func (m *MockStore) InsertTemplateSecret(ctx context.Context, arg database.InsertTemplateSecretParams) (database.TemplateSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateSecret", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateSecret indicates an expected call of InsertTemplateSecret.
+func (mr *MockStoreMockRecorder) InsertTemplateSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateSecret", reflect.TypeOf((*MockStore)(nil).InsertTemplateSecret), ctx, arg)
+}
+
 // InsertTemplateVersion mocks base method.
 func (m *MockStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	m.ctrl.T.Helper()
@@ -8313,6 +10126,21 @@ func (mr *MockStoreMockRecorder) InsertTemplateVersionParameter(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionParameter", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionParameter), ctx, arg)
 }
 
+// InsertTemplateVersionRollout mocks base method.
+func (m *MockStore) InsertTemplateVersionRollout(ctx context.Context, arg database.InsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateVersionRollout", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateVersionRollout indicates an expected call of InsertTemplateVersionRollout.
+func (mr *MockStoreMockRecorder) InsertTemplateVersionRollout(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionRollout", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionRollout), ctx, arg)
+}
+
 // InsertTemplateVersionTerraformValuesByJobID mocks base method.
 func (m *MockStore) InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg database.InsertTemplateVersionTerraformValuesByJobIDParams) error {
 	m.ctrl.T.Helper()
@@ -8357,6 +10185,35 @@ func (mr *MockStoreMockRecorder) InsertTemplateVersionWorkspaceTag(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionWorkspaceTag", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionWorkspaceTag), ctx, arg)
 }
 
+// InsertTemplateWorkspaceRestart mocks base method.
+func (m *MockStore) InsertTemplateWorkspaceRestart(ctx context.Context, arg database.InsertTemplateWorkspaceRestartParams) (database.TemplateWorkspaceRestart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateWorkspaceRestart", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateWorkspaceRestart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateWorkspaceRestart indicates an expected call of InsertTemplateWorkspaceRestart.
+func (mr *MockStoreMockRecorder) InsertTemplateWorkspaceRestart(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateWorkspaceRestart", reflect.TypeOf((*MockStore)(nil).InsertTemplateWorkspaceRestart), ctx, arg)
+}
+
+// InsertTemplateWorkspaceRestartBuild mocks base method.
+func (m *MockStore) InsertTemplateWorkspaceRestartBuild(ctx context.Context, arg database.InsertTemplateWorkspaceRestartBuildParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateWorkspaceRestartBuild", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertTemplateWorkspaceRestartBuild indicates an expected call of InsertTemplateWorkspaceRestartBuild.
+func (mr *MockStoreMockRecorder) InsertTemplateWorkspaceRestartBuild(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateWorkspaceRestartBuild", reflect.TypeOf((*MockStore)(nil).InsertTemplateWorkspaceRestartBuild), ctx, arg)
+}
+
 // InsertUsageEvent mocks base method.
 func (m *MockStore) InsertUsageEvent(ctx context.Context, arg database.InsertUsageEventParams) error {
 	m.ctrl.T.Helper()
@@ -8401,6 +10258,21 @@ func (mr *MockStoreMockRecorder) InsertUserGroupsByID(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserGroupsByID", reflect.TypeOf((*MockStore)(nil).InsertUserGroupsByID), ctx, arg)
 }
 
+// InsertUserImpersonation mocks base method.
+func (m *MockStore) InsertUserImpersonation(ctx context.Context, arg database.InsertUserImpersonationParams) (database.UserImpersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserImpersonation", ctx, arg)
+	ret0, _ := ret[0].(database.UserImpersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUserImpersonation indicates an expected call of InsertUserImpersonation.
+func (mr *MockStoreMockRecorder) InsertUserImpersonation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserImpersonation", reflect.TypeOf((*MockStore)(nil).InsertUserImpersonation), ctx, arg)
+}
+
 // InsertUserLink mocks base method.
 func (m *MockStore) InsertUserLink(ctx context.Context, arg database.InsertUserLinkParams) (database.UserLink, error) {
 	m.ctrl.T.Helper()
@@ -8431,6 +10303,36 @@ func (mr *MockStoreMockRecorder) InsertUserSkill(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserSkill", reflect.TypeOf((*MockStore)(nil).InsertUserSkill), ctx, arg)
 }
 
+// InsertUserWorkspaceReport mocks base method.
+func (m *MockStore) InsertUserWorkspaceReport(ctx context.Context, arg database.InsertUserWorkspaceReportParams) (database.UserWorkspaceReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserWorkspaceReport", ctx, arg)
+	ret0, _ := ret[0].(database.UserWorkspaceReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUserWorkspaceReport indicates an expected call of InsertUserWorkspaceReport.
+func (mr *MockStoreMockRecorder) InsertUserWorkspaceReport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserWorkspaceReport", reflect.TypeOf((*MockStore)(nil).InsertUserWorkspaceReport), ctx, arg)
+}
+
+// InsertVCSEventMapping mocks base method.
+func (m *MockStore) InsertVCSEventMapping(ctx context.Context, arg database.InsertVCSEventMappingParams) (database.VCSEventMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertVCSEventMapping", ctx, arg)
+	ret0, _ := ret[0].(database.VCSEventMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertVCSEventMapping indicates an expected call of InsertVCSEventMapping.
+func (mr *MockStoreMockRecorder) InsertVCSEventMapping(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertVCSEventMapping", reflect.TypeOf((*MockStore)(nil).InsertVCSEventMapping), ctx, arg)
+}
+
 // InsertVolumeResourceMonitor mocks base method.
 func (m *MockStore) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	m.ctrl.T.Helper()
@@ -8485,10 +10387,25 @@ func (m *MockStore) InsertWorkspaceAgent(ctx context.Context, arg database.Inser
 	return ret0, ret1
 }
 
-// InsertWorkspaceAgent indicates an expected call of InsertWorkspaceAgent.
-func (mr *MockStoreMockRecorder) InsertWorkspaceAgent(ctx, arg any) *gomock.Call {
+// InsertWorkspaceAgent indicates an expected call of InsertWorkspaceAgent.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgent", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgent), ctx, arg)
+}
+
+// InsertWorkspaceAgentBootstrapProgress mocks base method.
+func (m *MockStore) InsertWorkspaceAgentBootstrapProgress(ctx context.Context, arg database.InsertWorkspaceAgentBootstrapProgressParams) (database.WorkspaceAgentBootstrapProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentBootstrapProgress", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAgentBootstrapProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentBootstrapProgress indicates an expected call of InsertWorkspaceAgentBootstrapProgress.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentBootstrapProgress(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgent", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgent), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentBootstrapProgress", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentBootstrapProgress), ctx, arg)
 }
 
 // InsertWorkspaceAgentDevcontainers mocks base method.
@@ -8536,6 +10453,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentLogs(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentLogs", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentLogs), ctx, arg)
 }
 
+// InsertWorkspaceAgentMemoryEvent mocks base method.
+func (m *MockStore) InsertWorkspaceAgentMemoryEvent(ctx context.Context, arg database.InsertWorkspaceAgentMemoryEventParams) (database.WorkspaceAgentMemoryEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentMemoryEvent", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAgentMemoryEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentMemoryEvent indicates an expected call of InsertWorkspaceAgentMemoryEvent.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMemoryEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMemoryEvent", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMemoryEvent), ctx, arg)
+}
+
 // InsertWorkspaceAgentMetadata mocks base method.
 func (m *MockStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg database.InsertWorkspaceAgentMetadataParams) error {
 	m.ctrl.T.Helper()
@@ -8550,6 +10482,34 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadata(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadata), ctx, arg)
 }
 
+// InsertWorkspaceAgentMetadataHistory mocks base method.
+func (m *MockStore) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentMetadataHistory", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceAgentMetadataHistory indicates an expected call of InsertWorkspaceAgentMetadataHistory.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadataHistory(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadataHistory", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadataHistory), ctx, arg)
+}
+
+// InsertWorkspaceAgentNetworkPolicyViolations mocks base method.
+func (m *MockStore) InsertWorkspaceAgentNetworkPolicyViolations(ctx context.Context, arg database.InsertWorkspaceAgentNetworkPolicyViolationsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentNetworkPolicyViolations", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceAgentNetworkPolicyViolations indicates an expected call of InsertWorkspaceAgentNetworkPolicyViolations.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentNetworkPolicyViolations(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentNetworkPolicyViolations", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentNetworkPolicyViolations), ctx, arg)
+}
+
 // InsertWorkspaceAgentScriptTimings mocks base method.
 func (m *MockStore) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	m.ctrl.T.Helper()
@@ -8580,6 +10540,36 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentScripts(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentScripts", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentScripts), ctx, arg)
 }
 
+// InsertWorkspaceAgentServiceTransition mocks base method.
+func (m *MockStore) InsertWorkspaceAgentServiceTransition(ctx context.Context, arg database.InsertWorkspaceAgentServiceTransitionParams) (database.WorkspaceAgentServiceTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentServiceTransition", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAgentServiceTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentServiceTransition indicates an expected call of InsertWorkspaceAgentServiceTransition.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentServiceTransition(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentServiceTransition", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentServiceTransition), ctx, arg)
+}
+
+// InsertWorkspaceAgentServices mocks base method.
+func (m *MockStore) InsertWorkspaceAgentServices(ctx context.Context, arg database.InsertWorkspaceAgentServicesParams) ([]database.WorkspaceAgentService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentServices", ctx, arg)
+	ret0, _ := ret[0].([]database.WorkspaceAgentService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentServices indicates an expected call of InsertWorkspaceAgentServices.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentServices(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentServices", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentServices), ctx, arg)
+}
+
 // InsertWorkspaceAgentStats mocks base method.
 func (m *MockStore) InsertWorkspaceAgentStats(ctx context.Context, arg database.InsertWorkspaceAgentStatsParams) error {
 	m.ctrl.T.Helper()
@@ -8594,6 +10584,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentStats(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentStats), ctx, arg)
 }
 
+// InsertWorkspaceAppShareLink mocks base method.
+func (m *MockStore) InsertWorkspaceAppShareLink(ctx context.Context, arg database.InsertWorkspaceAppShareLinkParams) (database.WorkspaceAppShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAppShareLink", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAppShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAppShareLink indicates an expected call of InsertWorkspaceAppShareLink.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAppShareLink(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAppShareLink", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAppShareLink), ctx, arg)
+}
+
 // InsertWorkspaceAppStats mocks base method.
 func (m *MockStore) InsertWorkspaceAppStats(ctx context.Context, arg database.InsertWorkspaceAppStatsParams) error {
 	m.ctrl.T.Helper()
@@ -8637,6 +10642,35 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuild(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuild", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuild), ctx, arg)
 }
 
+// InsertWorkspaceBuildAnnotation mocks base method.
+func (m *MockStore) InsertWorkspaceBuildAnnotation(ctx context.Context, arg database.InsertWorkspaceBuildAnnotationParams) (database.WorkspaceBuildAnnotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildAnnotation", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceBuildAnnotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceBuildAnnotation indicates an expected call of InsertWorkspaceBuildAnnotation.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildAnnotation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildAnnotation", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildAnnotation), ctx, arg)
+}
+
+// InsertWorkspaceBuildInputs mocks base method.
+func (m *MockStore) InsertWorkspaceBuildInputs(ctx context.Context, arg database.InsertWorkspaceBuildInputsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildInputs", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceBuildInputs indicates an expected call of InsertWorkspaceBuildInputs.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildInputs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildInputs", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildInputs), ctx, arg)
+}
+
 // InsertWorkspaceBuildOrchestration mocks base method.
 func (m *MockStore) InsertWorkspaceBuildOrchestration(ctx context.Context, arg database.InsertWorkspaceBuildOrchestrationParams) (database.WorkspaceBuildOrchestration, error) {
 	m.ctrl.T.Helper()
@@ -8652,6 +10686,20 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuildOrchestration(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildOrchestration", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildOrchestration), ctx, arg)
 }
 
+// InsertWorkspaceBuildParameterChanges mocks base method.
+func (m *MockStore) InsertWorkspaceBuildParameterChanges(ctx context.Context, arg database.InsertWorkspaceBuildParameterChangesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildParameterChanges", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceBuildParameterChanges indicates an expected call of InsertWorkspaceBuildParameterChanges.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildParameterChanges(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildParameterChanges", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildParameterChanges), ctx, arg)
+}
+
 // InsertWorkspaceBuildParameters mocks base method.
 func (m *MockStore) InsertWorkspaceBuildParameters(ctx context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
 	m.ctrl.T.Helper()
@@ -8666,6 +10714,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuildParameters(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildParameters), ctx, arg)
 }
 
+// InsertWorkspaceLease mocks base method.
+func (m *MockStore) InsertWorkspaceLease(ctx context.Context, arg database.InsertWorkspaceLeaseParams) (database.WorkspaceLease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceLease", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceLease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceLease indicates an expected call of InsertWorkspaceLease.
+func (mr *MockStoreMockRecorder) InsertWorkspaceLease(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceLease", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceLease), ctx, arg)
+}
+
 // InsertWorkspaceModule mocks base method.
 func (m *MockStore) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	m.ctrl.T.Helper()
@@ -9383,6 +11446,21 @@ func (mr *MockStoreMockRecorder) RegisterWorkspaceProxy(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWorkspaceProxy", reflect.TypeOf((*MockStore)(nil).RegisterWorkspaceProxy), ctx, arg)
 }
 
+// ReleaseWorkspaceLease mocks base method.
+func (m *MockStore) ReleaseWorkspaceLease(ctx context.Context, arg database.ReleaseWorkspaceLeaseParams) (database.WorkspaceLease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseWorkspaceLease", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceLease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseWorkspaceLease indicates an expected call of ReleaseWorkspaceLease.
+func (mr *MockStoreMockRecorder) ReleaseWorkspaceLease(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseWorkspaceLease", reflect.TypeOf((*MockStore)(nil).ReleaseWorkspaceLease), ctx, arg)
+}
+
 // RemoveUserFromGroups mocks base method.
 func (m *MockStore) RemoveUserFromGroups(ctx context.Context, arg database.RemoveUserFromGroupsParams) ([]uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -9443,6 +11521,21 @@ func (mr *MockStoreMockRecorder) ResolveUserChatSpendLimit(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveUserChatSpendLimit", reflect.TypeOf((*MockStore)(nil).ResolveUserChatSpendLimit), ctx, arg)
 }
 
+// ResumeTemplateWorkspaceRestart mocks base method.
+func (m *MockStore) ResumeTemplateWorkspaceRestart(ctx context.Context, arg database.ResumeTemplateWorkspaceRestartParams) (database.TemplateWorkspaceRestart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeTemplateWorkspaceRestart", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateWorkspaceRestart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeTemplateWorkspaceRestart indicates an expected call of ResumeTemplateWorkspaceRestart.
+func (mr *MockStoreMockRecorder) ResumeTemplateWorkspaceRestart(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeTemplateWorkspaceRestart", reflect.TypeOf((*MockStore)(nil).ResumeTemplateWorkspaceRestart), ctx, arg)
+}
+
 // RevokeDBCryptKey mocks base method.
 func (m *MockStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	m.ctrl.T.Helper()
@@ -9457,6 +11550,36 @@ func (mr *MockStoreMockRecorder) RevokeDBCryptKey(ctx, activeKeyDigest any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDBCryptKey", reflect.TypeOf((*MockStore)(nil).RevokeDBCryptKey), ctx, activeKeyDigest)
 }
 
+// RevokeWorkspaceAppShareLink mocks base method.
+func (m *MockStore) RevokeWorkspaceAppShareLink(ctx context.Context, arg database.RevokeWorkspaceAppShareLinkParams) (database.WorkspaceAppShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeWorkspaceAppShareLink", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAppShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeWorkspaceAppShareLink indicates an expected call of RevokeWorkspaceAppShareLink.
+func (mr *MockStoreMockRecorder) RevokeWorkspaceAppShareLink(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeWorkspaceAppShareLink", reflect.TypeOf((*MockStore)(nil).RevokeWorkspaceAppShareLink), ctx, arg)
+}
+
+// RotateProvisionerKey mocks base method.
+func (m *MockStore) RotateProvisionerKey(ctx context.Context, arg database.RotateProvisionerKeyParams) (database.ProvisionerKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateProvisionerKey", ctx, arg)
+	ret0, _ := ret[0].(database.ProvisionerKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateProvisionerKey indicates an expected call of RotateProvisionerKey.
+func (mr *MockStoreMockRecorder) RotateProvisionerKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateProvisionerKey", reflect.TypeOf((*MockStore)(nil).RotateProvisionerKey), ctx, arg)
+}
+
 // SelectUsageEventsForPublishing mocks base method.
 func (m *MockStore) SelectUsageEventsForPublishing(ctx context.Context, now time.Time) ([]database.UsageEvent, error) {
 	m.ctrl.T.Helper()
@@ -10101,6 +12224,21 @@ func (mr *MockStoreMockRecorder) UpdateEncryptedAIProviderSettings(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEncryptedAIProviderSettings", reflect.TypeOf((*MockStore)(nil).UpdateEncryptedAIProviderSettings), ctx, arg)
 }
 
+// UpdateEncryptedTemplateSecret mocks base method.
+func (m *MockStore) UpdateEncryptedTemplateSecret(ctx context.Context, arg database.UpdateEncryptedTemplateSecretParams) (database.TemplateSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEncryptedTemplateSecret", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEncryptedTemplateSecret indicates an expected call of UpdateEncryptedTemplateSecret.
+func (mr *MockStoreMockRecorder) UpdateEncryptedTemplateSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEncryptedTemplateSecret", reflect.TypeOf((*MockStore)(nil).UpdateEncryptedTemplateSecret), ctx, arg)
+}
+
 // UpdateEncryptedUserAIProviderKey mocks base method.
 func (m *MockStore) UpdateEncryptedUserAIProviderKey(ctx context.Context, arg database.UpdateEncryptedUserAIProviderKeyParams) (database.UserAIProviderKey, error) {
 	m.ctrl.T.Helper()
@@ -10116,6 +12254,35 @@ func (mr *MockStoreMockRecorder) UpdateEncryptedUserAIProviderKey(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEncryptedUserAIProviderKey", reflect.TypeOf((*MockStore)(nil).UpdateEncryptedUserAIProviderKey), ctx, arg)
 }
 
+// UpdateEncryptedVCSEventMapping mocks base method.
+func (m *MockStore) UpdateEncryptedVCSEventMapping(ctx context.Context, arg database.UpdateEncryptedVCSEventMappingParams) (database.VCSEventMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEncryptedVCSEventMapping", ctx, arg)
+	ret0, _ := ret[0].(database.VCSEventMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEncryptedVCSEventMapping indicates an expected call of UpdateEncryptedVCSEventMapping.
+func (mr *MockStoreMockRecorder) UpdateEncryptedVCSEventMapping(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEncryptedVCSEventMapping", reflect.TypeOf((*MockStore)(nil).UpdateEncryptedVCSEventMapping), ctx, arg)
+}
+
+// UpdateEncryptedWorkspaceBuildParameter mocks base method.
+func (m *MockStore) UpdateEncryptedWorkspaceBuildParameter(ctx context.Context, arg database.UpdateEncryptedWorkspaceBuildParameterParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEncryptedWorkspaceBuildParameter", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEncryptedWorkspaceBuildParameter indicates an expected call of UpdateEncryptedWorkspaceBuildParameter.
+func (mr *MockStoreMockRecorder) UpdateEncryptedWorkspaceBuildParameter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEncryptedWorkspaceBuildParameter", reflect.TypeOf((*MockStore)(nil).UpdateEncryptedWorkspaceBuildParameter), ctx, arg)
+}
+
 // UpdateExternalAuthLink mocks base method.
 func (m *MockStore) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	m.ctrl.T.Helper()
@@ -10337,6 +12504,21 @@ func (mr *MockStoreMockRecorder) UpdateOrganizationDeletedByID(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrganizationDeletedByID", reflect.TypeOf((*MockStore)(nil).UpdateOrganizationDeletedByID), ctx, arg)
 }
 
+// UpdateOrganizationProvisionerDaemonSettings mocks base method.
+func (m *MockStore) UpdateOrganizationProvisionerDaemonSettings(ctx context.Context, arg database.UpdateOrganizationProvisionerDaemonSettingsParams) (database.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrganizationProvisionerDaemonSettings", ctx, arg)
+	ret0, _ := ret[0].(database.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrganizationProvisionerDaemonSettings indicates an expected call of UpdateOrganizationProvisionerDaemonSettings.
+func (mr *MockStoreMockRecorder) UpdateOrganizationProvisionerDaemonSettings(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrganizationProvisionerDaemonSettings", reflect.TypeOf((*MockStore)(nil).UpdateOrganizationProvisionerDaemonSettings), ctx, arg)
+}
+
 // UpdateOrganizationWorkspaceSharingSettings mocks base method.
 func (m *MockStore) UpdateOrganizationWorkspaceSharingSettings(ctx context.Context, arg database.UpdateOrganizationWorkspaceSharingSettingsParams) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -10452,6 +12634,20 @@ func (mr *MockStoreMockRecorder) UpdateProvisionerJobLogsOverflowed(ctx, arg any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobLogsOverflowed", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobLogsOverflowed), ctx, arg)
 }
 
+// UpdateProvisionerJobTimeoutByID mocks base method.
+func (m *MockStore) UpdateProvisionerJobTimeoutByID(ctx context.Context, arg database.UpdateProvisionerJobTimeoutByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProvisionerJobTimeoutByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProvisionerJobTimeoutByID indicates an expected call of UpdateProvisionerJobTimeoutByID.
+func (mr *MockStoreMockRecorder) UpdateProvisionerJobTimeoutByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobTimeoutByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobTimeoutByID), ctx, arg)
+}
+
 // UpdateProvisionerJobWithCancelByID mocks base method.
 func (m *MockStore) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	m.ctrl.T.Helper()
@@ -10494,6 +12690,34 @@ func (mr *MockStoreMockRecorder) UpdateProvisionerJobWithCompleteWithStartedAtBy
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobWithCompleteWithStartedAtByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobWithCompleteWithStartedAtByID), ctx, arg)
 }
 
+// UpdateProvisionerJobWithRequeueByID mocks base method.
+func (m *MockStore) UpdateProvisionerJobWithRequeueByID(ctx context.Context, arg database.UpdateProvisionerJobWithRequeueByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProvisionerJobWithRequeueByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProvisionerJobWithRequeueByID indicates an expected call of UpdateProvisionerJobWithRequeueByID.
+func (mr *MockStoreMockRecorder) UpdateProvisionerJobWithRequeueByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobWithRequeueByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobWithRequeueByID), ctx, arg)
+}
+
+// UpdateProvisionerKeyLastUsedAt mocks base method.
+func (m *MockStore) UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg database.UpdateProvisionerKeyLastUsedAtParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProvisionerKeyLastUsedAt", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProvisionerKeyLastUsedAt indicates an expected call of UpdateProvisionerKeyLastUsedAt.
+func (mr *MockStoreMockRecorder) UpdateProvisionerKeyLastUsedAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerKeyLastUsedAt", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerKeyLastUsedAt), ctx, arg)
+}
+
 // UpdateReplica mocks base method.
 func (m *MockStore) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	m.ctrl.T.Helper()
@@ -10632,66 +12856,168 @@ func (m *MockStore) UpdateTemplateScheduleByID(ctx context.Context, arg database
 	return ret0
 }
 
-// UpdateTemplateScheduleByID indicates an expected call of UpdateTemplateScheduleByID.
-func (mr *MockStoreMockRecorder) UpdateTemplateScheduleByID(ctx, arg any) *gomock.Call {
+// UpdateTemplateScheduleByID indicates an expected call of UpdateTemplateScheduleByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateScheduleByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateScheduleByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateScheduleByID), ctx, arg)
+}
+
+// UpdateTemplateSecretByTemplateIDAndName mocks base method.
+func (m *MockStore) UpdateTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.UpdateTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateSecretByTemplateIDAndName", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplateSecretByTemplateIDAndName indicates an expected call of UpdateTemplateSecretByTemplateIDAndName.
+func (mr *MockStoreMockRecorder) UpdateTemplateSecretByTemplateIDAndName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateSecretByTemplateIDAndName", reflect.TypeOf((*MockStore)(nil).UpdateTemplateSecretByTemplateIDAndName), ctx, arg)
+}
+
+// UpdateTemplateVersionByID mocks base method.
+func (m *MockStore) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateVersionByID indicates an expected call of UpdateTemplateVersionByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionByID), ctx, arg)
+}
+
+// UpdateTemplateVersionDeprecationByID mocks base method.
+func (m *MockStore) UpdateTemplateVersionDeprecationByID(ctx context.Context, arg database.UpdateTemplateVersionDeprecationByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionDeprecationByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateVersionDeprecationByID indicates an expected call of UpdateTemplateVersionDeprecationByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionDeprecationByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionDeprecationByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionDeprecationByID), ctx, arg)
+}
+
+// UpdateTemplateVersionDescriptionByJobID mocks base method.
+func (m *MockStore) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg database.UpdateTemplateVersionDescriptionByJobIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionDescriptionByJobID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateVersionDescriptionByJobID indicates an expected call of UpdateTemplateVersionDescriptionByJobID.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionDescriptionByJobID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionDescriptionByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionDescriptionByJobID), ctx, arg)
+}
+
+// UpdateTemplateVersionExternalAuthProvidersByJobID mocks base method.
+func (m *MockStore) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg database.UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionExternalAuthProvidersByJobID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateVersionExternalAuthProvidersByJobID indicates an expected call of UpdateTemplateVersionExternalAuthProvidersByJobID.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionExternalAuthProvidersByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionExternalAuthProvidersByJobID), ctx, arg)
+}
+
+// UpdateTemplateVersionFlagsByJobID mocks base method.
+func (m *MockStore) UpdateTemplateVersionFlagsByJobID(ctx context.Context, arg database.UpdateTemplateVersionFlagsByJobIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionFlagsByJobID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateVersionFlagsByJobID indicates an expected call of UpdateTemplateVersionFlagsByJobID.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionFlagsByJobID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionFlagsByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionFlagsByJobID), ctx, arg)
+}
+
+// UpdateTemplateVersionRolloutStage mocks base method.
+func (m *MockStore) UpdateTemplateVersionRolloutStage(ctx context.Context, arg database.UpdateTemplateVersionRolloutStageParams) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionRolloutStage", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplateVersionRolloutStage indicates an expected call of UpdateTemplateVersionRolloutStage.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionRolloutStage(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateScheduleByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateScheduleByID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionRolloutStage", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionRolloutStage), ctx, arg)
 }
 
-// UpdateTemplateVersionByID mocks base method.
-func (m *MockStore) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
+// UpdateTemplateVersionRolloutStatus mocks base method.
+func (m *MockStore) UpdateTemplateVersionRolloutStatus(ctx context.Context, arg database.UpdateTemplateVersionRolloutStatusParams) (database.TemplateVersionRollout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTemplateVersionByID", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionRolloutStatus", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateTemplateVersionByID indicates an expected call of UpdateTemplateVersionByID.
-func (mr *MockStoreMockRecorder) UpdateTemplateVersionByID(ctx, arg any) *gomock.Call {
+// UpdateTemplateVersionRolloutStatus indicates an expected call of UpdateTemplateVersionRolloutStatus.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionRolloutStatus(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionByID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionRolloutStatus", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionRolloutStatus), ctx, arg)
 }
 
-// UpdateTemplateVersionDescriptionByJobID mocks base method.
-func (m *MockStore) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg database.UpdateTemplateVersionDescriptionByJobIDParams) error {
+// UpdateTemplateVersionScanByTemplateVersionID mocks base method.
+func (m *MockStore) UpdateTemplateVersionScanByTemplateVersionID(ctx context.Context, arg database.UpdateTemplateVersionScanByTemplateVersionIDParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTemplateVersionDescriptionByJobID", ctx, arg)
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionScanByTemplateVersionID", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateTemplateVersionDescriptionByJobID indicates an expected call of UpdateTemplateVersionDescriptionByJobID.
-func (mr *MockStoreMockRecorder) UpdateTemplateVersionDescriptionByJobID(ctx, arg any) *gomock.Call {
+// UpdateTemplateVersionScanByTemplateVersionID indicates an expected call of UpdateTemplateVersionScanByTemplateVersionID.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionScanByTemplateVersionID(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionDescriptionByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionDescriptionByJobID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionScanByTemplateVersionID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionScanByTemplateVersionID), ctx, arg)
 }
 
-// UpdateTemplateVersionExternalAuthProvidersByJobID mocks base method.
-func (m *MockStore) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg database.UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
+// UpdateTemplateWorkspaceRestartLastBatchAt mocks base method.
+func (m *MockStore) UpdateTemplateWorkspaceRestartLastBatchAt(ctx context.Context, arg database.UpdateTemplateWorkspaceRestartLastBatchAtParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTemplateVersionExternalAuthProvidersByJobID", ctx, arg)
+	ret := m.ctrl.Call(m, "UpdateTemplateWorkspaceRestartLastBatchAt", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateTemplateVersionExternalAuthProvidersByJobID indicates an expected call of UpdateTemplateVersionExternalAuthProvidersByJobID.
-func (mr *MockStoreMockRecorder) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx, arg any) *gomock.Call {
+// UpdateTemplateWorkspaceRestartLastBatchAt indicates an expected call of UpdateTemplateWorkspaceRestartLastBatchAt.
+func (mr *MockStoreMockRecorder) UpdateTemplateWorkspaceRestartLastBatchAt(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionExternalAuthProvidersByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionExternalAuthProvidersByJobID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateWorkspaceRestartLastBatchAt", reflect.TypeOf((*MockStore)(nil).UpdateTemplateWorkspaceRestartLastBatchAt), ctx, arg)
 }
 
-// UpdateTemplateVersionFlagsByJobID mocks base method.
-func (m *MockStore) UpdateTemplateVersionFlagsByJobID(ctx context.Context, arg database.UpdateTemplateVersionFlagsByJobIDParams) error {
+// UpdateTemplateWorkspaceRestartStatus mocks base method.
+func (m *MockStore) UpdateTemplateWorkspaceRestartStatus(ctx context.Context, arg database.UpdateTemplateWorkspaceRestartStatusParams) (database.TemplateWorkspaceRestart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTemplateVersionFlagsByJobID", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateTemplateWorkspaceRestartStatus", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateWorkspaceRestart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateTemplateVersionFlagsByJobID indicates an expected call of UpdateTemplateVersionFlagsByJobID.
-func (mr *MockStoreMockRecorder) UpdateTemplateVersionFlagsByJobID(ctx, arg any) *gomock.Call {
+// UpdateTemplateWorkspaceRestartStatus indicates an expected call of UpdateTemplateWorkspaceRestartStatus.
+func (mr *MockStoreMockRecorder) UpdateTemplateWorkspaceRestartStatus(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionFlagsByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionFlagsByJobID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateWorkspaceRestartStatus", reflect.TypeOf((*MockStore)(nil).UpdateTemplateWorkspaceRestartStatus), ctx, arg)
 }
 
 // UpdateTemplateWorkspacesLastUsedAt mocks base method.
@@ -10853,6 +13179,36 @@ func (mr *MockStoreMockRecorder) UpdateUserHashedPassword(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserHashedPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserHashedPassword), ctx, arg)
 }
 
+// UpdateUserImpersonationConsentedAt mocks base method.
+func (m *MockStore) UpdateUserImpersonationConsentedAt(ctx context.Context, arg database.UpdateUserImpersonationConsentedAtParams) (database.UserImpersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserImpersonationConsentedAt", ctx, arg)
+	ret0, _ := ret[0].(database.UserImpersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserImpersonationConsentedAt indicates an expected call of UpdateUserImpersonationConsentedAt.
+func (mr *MockStoreMockRecorder) UpdateUserImpersonationConsentedAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserImpersonationConsentedAt", reflect.TypeOf((*MockStore)(nil).UpdateUserImpersonationConsentedAt), ctx, arg)
+}
+
+// UpdateUserImpersonationIssued mocks base method.
+func (m *MockStore) UpdateUserImpersonationIssued(ctx context.Context, arg database.UpdateUserImpersonationIssuedParams) (database.UserImpersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserImpersonationIssued", ctx, arg)
+	ret0, _ := ret[0].(database.UserImpersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserImpersonationIssued indicates an expected call of UpdateUserImpersonationIssued.
+func (mr *MockStoreMockRecorder) UpdateUserImpersonationIssued(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserImpersonationIssued", reflect.TypeOf((*MockStore)(nil).UpdateUserImpersonationIssued), ctx, arg)
+}
+
 // UpdateUserLastSeenAt mocks base method.
 func (m *MockStore) UpdateUserLastSeenAt(ctx context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
 	m.ctrl.T.Helper()
@@ -11138,6 +13494,35 @@ func (mr *MockStoreMockRecorder) UpdateUserThinkingDisplayMode(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserThinkingDisplayMode", reflect.TypeOf((*MockStore)(nil).UpdateUserThinkingDisplayMode), ctx, arg)
 }
 
+// UpdateUserWorkspaceSelfRequestedBuildsAllowed mocks base method.
+func (m *MockStore) UpdateUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, arg database.UpdateUserWorkspaceSelfRequestedBuildsAllowedParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserWorkspaceSelfRequestedBuildsAllowed", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserWorkspaceSelfRequestedBuildsAllowed indicates an expected call of UpdateUserWorkspaceSelfRequestedBuildsAllowed.
+func (mr *MockStoreMockRecorder) UpdateUserWorkspaceSelfRequestedBuildsAllowed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserWorkspaceSelfRequestedBuildsAllowed", reflect.TypeOf((*MockStore)(nil).UpdateUserWorkspaceSelfRequestedBuildsAllowed), ctx, arg)
+}
+
+// UpdateVCSEventMappingLastTriggeredAt mocks base method.
+func (m *MockStore) UpdateVCSEventMappingLastTriggeredAt(ctx context.Context, arg database.UpdateVCSEventMappingLastTriggeredAtParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVCSEventMappingLastTriggeredAt", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVCSEventMappingLastTriggeredAt indicates an expected call of UpdateVCSEventMappingLastTriggeredAt.
+func (mr *MockStoreMockRecorder) UpdateVCSEventMappingLastTriggeredAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVCSEventMappingLastTriggeredAt", reflect.TypeOf((*MockStore)(nil).UpdateVCSEventMappingLastTriggeredAt), ctx, arg)
+}
+
 // UpdateVolumeResourceMonitor mocks base method.
 func (m *MockStore) UpdateVolumeResourceMonitor(ctx context.Context, arg database.UpdateVolumeResourceMonitorParams) error {
 	m.ctrl.T.Helper()
@@ -11181,6 +13566,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceACLByID(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceACLByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceACLByID), ctx, arg)
 }
 
+// UpdateWorkspaceAgentClockSkewByID mocks base method.
+func (m *MockStore) UpdateWorkspaceAgentClockSkewByID(ctx context.Context, arg database.UpdateWorkspaceAgentClockSkewByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAgentClockSkewByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAgentClockSkewByID indicates an expected call of UpdateWorkspaceAgentClockSkewByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentClockSkewByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentClockSkewByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentClockSkewByID), ctx, arg)
+}
+
 // UpdateWorkspaceAgentConnectionByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -11293,6 +13692,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceAppHealthByID(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAppHealthByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAppHealthByID), ctx, arg)
 }
 
+// UpdateWorkspaceArchivedAt mocks base method.
+func (m *MockStore) UpdateWorkspaceArchivedAt(ctx context.Context, arg database.UpdateWorkspaceArchivedAtParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceArchivedAt", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceArchivedAt indicates an expected call of UpdateWorkspaceArchivedAt.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceArchivedAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceArchivedAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceArchivedAt), ctx, arg)
+}
+
 // UpdateWorkspaceAutomaticUpdates mocks base method.
 func (m *MockStore) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
 	m.ctrl.T.Helper()
@@ -11451,6 +13864,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceBuildProvisionerStateByID(ctx, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBuildProvisionerStateByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBuildProvisionerStateByID), ctx, arg)
 }
 
+// UpdateWorkspaceCloudResourceVerification mocks base method.
+func (m *MockStore) UpdateWorkspaceCloudResourceVerification(ctx context.Context, arg database.UpdateWorkspaceCloudResourceVerificationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceCloudResourceVerification", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceCloudResourceVerification indicates an expected call of UpdateWorkspaceCloudResourceVerification.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceCloudResourceVerification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceCloudResourceVerification", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceCloudResourceVerification), ctx, arg)
+}
+
 // UpdateWorkspaceDeletedByID mocks base method.
 func (m *MockStore) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	m.ctrl.T.Helper()
@@ -11465,6 +13892,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceDeletedByID(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDeletedByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDeletedByID), ctx, arg)
 }
 
+// UpdateWorkspaceDormancyHookStatus mocks base method.
+func (m *MockStore) UpdateWorkspaceDormancyHookStatus(ctx context.Context, arg database.UpdateWorkspaceDormancyHookStatusParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceDormancyHookStatus", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceDormancyHookStatus indicates an expected call of UpdateWorkspaceDormancyHookStatus.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceDormancyHookStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDormancyHookStatus", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDormancyHookStatus), ctx, arg)
+}
+
 // UpdateWorkspaceDormantDeletingAt mocks base method.
 func (m *MockStore) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 	m.ctrl.T.Helper()
@@ -11508,6 +13950,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceNextStartAt(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceNextStartAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceNextStartAt), ctx, arg)
 }
 
+// UpdateWorkspaceOwnerByID mocks base method.
+func (m *MockStore) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.WorkspaceTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceOwnerByID", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceOwnerByID indicates an expected call of UpdateWorkspaceOwnerByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceOwnerByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceOwnerByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceOwnerByID), ctx, arg)
+}
+
 // UpdateWorkspaceProxy mocks base method.
 func (m *MockStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -11537,6 +13994,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceProxyDeleted(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceProxyDeleted", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceProxyDeleted), ctx, arg)
 }
 
+// UpdateWorkspaceReadOnlyAt mocks base method.
+func (m *MockStore) UpdateWorkspaceReadOnlyAt(ctx context.Context, arg database.UpdateWorkspaceReadOnlyAtParams) (database.WorkspaceTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceReadOnlyAt", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceReadOnlyAt indicates an expected call of UpdateWorkspaceReadOnlyAt.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceReadOnlyAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceReadOnlyAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceReadOnlyAt), ctx, arg)
+}
+
 // UpdateWorkspaceTTL mocks base method.
 func (m *MockStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	m.ctrl.T.Helper()
@@ -12079,6 +14551,21 @@ func (mr *MockStoreMockRecorder) UpsertNotificationReportGeneratorLog(ctx, arg a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationReportGeneratorLog", reflect.TypeOf((*MockStore)(nil).UpsertNotificationReportGeneratorLog), ctx, arg)
 }
 
+// UpsertNotificationTemplateWebhook mocks base method.
+func (m *MockStore) UpsertNotificationTemplateWebhook(ctx context.Context, arg database.UpsertNotificationTemplateWebhookParams) (database.NotificationTemplateWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationTemplateWebhook", ctx, arg)
+	ret0, _ := ret[0].(database.NotificationTemplateWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationTemplateWebhook indicates an expected call of UpsertNotificationTemplateWebhook.
+func (mr *MockStoreMockRecorder) UpsertNotificationTemplateWebhook(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationTemplateWebhook", reflect.TypeOf((*MockStore)(nil).UpsertNotificationTemplateWebhook), ctx, arg)
+}
+
 // UpsertNotificationsSettings mocks base method.
 func (m *MockStore) UpsertNotificationsSettings(ctx context.Context, value string) error {
 	m.ctrl.T.Helper()
@@ -12121,6 +14608,20 @@ func (mr *MockStoreMockRecorder) UpsertPrebuildsSettings(ctx, value any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPrebuildsSettings", reflect.TypeOf((*MockStore)(nil).UpsertPrebuildsSettings), ctx, value)
 }
 
+// UpsertProvisionerCacheSettings mocks base method.
+func (m *MockStore) UpsertProvisionerCacheSettings(ctx context.Context, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertProvisionerCacheSettings", ctx, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertProvisionerCacheSettings indicates an expected call of UpsertProvisionerCacheSettings.
+func (mr *MockStoreMockRecorder) UpsertProvisionerCacheSettings(ctx, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertProvisionerCacheSettings", reflect.TypeOf((*MockStore)(nil).UpsertProvisionerCacheSettings), ctx, value)
+}
+
 // UpsertProvisionerDaemon mocks base method.
 func (m *MockStore) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	m.ctrl.T.Helper()
@@ -12238,6 +14739,21 @@ func (mr *MockStoreMockRecorder) UpsertTelemetryItem(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTelemetryItem", reflect.TypeOf((*MockStore)(nil).UpsertTelemetryItem), ctx, arg)
 }
 
+// UpsertTemplateNetworkPolicy mocks base method.
+func (m *MockStore) UpsertTemplateNetworkPolicy(ctx context.Context, arg database.UpsertTemplateNetworkPolicyParams) (database.TemplateNetworkPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateNetworkPolicy", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateNetworkPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateNetworkPolicy indicates an expected call of UpsertTemplateNetworkPolicy.
+func (mr *MockStoreMockRecorder) UpsertTemplateNetworkPolicy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateNetworkPolicy", reflect.TypeOf((*MockStore)(nil).UpsertTemplateNetworkPolicy), ctx, arg)
+}
+
 // UpsertTemplateUsageStats mocks base method.
 func (m *MockStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -12252,6 +14768,21 @@ func (mr *MockStoreMockRecorder) UpsertTemplateUsageStats(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateUsageStats", reflect.TypeOf((*MockStore)(nil).UpsertTemplateUsageStats), ctx)
 }
 
+// UpsertTemplateUserPreset mocks base method.
+func (m *MockStore) UpsertTemplateUserPreset(ctx context.Context, arg database.UpsertTemplateUserPresetParams) (database.TemplateUserPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateUserPreset", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateUserPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateUserPreset indicates an expected call of UpsertTemplateUserPreset.
+func (mr *MockStoreMockRecorder) UpsertTemplateUserPreset(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateUserPreset", reflect.TypeOf((*MockStore)(nil).UpsertTemplateUserPreset), ctx, arg)
+}
+
 // UpsertUserAIBudgetOverride mocks base method.
 func (m *MockStore) UpsertUserAIBudgetOverride(ctx context.Context, arg database.UpsertUserAIBudgetOverrideParams) (database.UserAIBudgetOverride, error) {
 	m.ctrl.T.Helper()
@@ -12310,6 +14841,36 @@ func (mr *MockStoreMockRecorder) UpsertUserChatPersonalModelOverride(ctx, arg an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserChatPersonalModelOverride", reflect.TypeOf((*MockStore)(nil).UpsertUserChatPersonalModelOverride), ctx, arg)
 }
 
+// UpsertUserNotificationScopedPreferences mocks base method.
+func (m *MockStore) UpsertUserNotificationScopedPreferences(ctx context.Context, arg database.UpsertUserNotificationScopedPreferencesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserNotificationScopedPreferences", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserNotificationScopedPreferences indicates an expected call of UpsertUserNotificationScopedPreferences.
+func (mr *MockStoreMockRecorder) UpsertUserNotificationScopedPreferences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserNotificationScopedPreferences", reflect.TypeOf((*MockStore)(nil).UpsertUserNotificationScopedPreferences), ctx, arg)
+}
+
+// UpsertUserSchedulePause mocks base method.
+func (m *MockStore) UpsertUserSchedulePause(ctx context.Context, arg database.UpsertUserSchedulePauseParams) (database.UserSchedulePause, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserSchedulePause", ctx, arg)
+	ret0, _ := ret[0].(database.UserSchedulePause)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserSchedulePause indicates an expected call of UpsertUserSchedulePause.
+func (mr *MockStoreMockRecorder) UpsertUserSchedulePause(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserSchedulePause", reflect.TypeOf((*MockStore)(nil).UpsertUserSchedulePause), ctx, arg)
+}
+
 // UpsertWebpushVAPIDKeys mocks base method.
 func (m *MockStore) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	m.ctrl.T.Helper()
@@ -12354,6 +14915,21 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentContextSnapshot(ctx, arg an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentContextSnapshot", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentContextSnapshot), ctx, arg)
 }
 
+// UpsertWorkspaceAgentCrashLoop mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentCrashLoop(ctx context.Context, arg database.UpsertWorkspaceAgentCrashLoopParams) (database.WorkspaceAgentCrashLoop, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentCrashLoop", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAgentCrashLoop)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceAgentCrashLoop indicates an expected call of UpsertWorkspaceAgentCrashLoop.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentCrashLoop(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentCrashLoop", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentCrashLoop), ctx, arg)
+}
+
 // UpsertWorkspaceAgentPortShare mocks base method.
 func (m *MockStore) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	m.ctrl.T.Helper()
@@ -12399,6 +14975,153 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAppAuditSession(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAppAuditSession", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAppAuditSession), ctx, arg)
 }
 
+// UpsertWorkspaceCloudResource mocks base method.
+func (m *MockStore) UpsertWorkspaceCloudResource(ctx context.Context, arg database.UpsertWorkspaceCloudResourceParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceCloudResource", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceCloudResource indicates an expected call of UpsertWorkspaceCloudResource.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceCloudResource(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceCloudResource", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceCloudResource), ctx, arg)
+}
+
+// UpsertWorkspaceDNSRecord mocks base method.
+func (m *MockStore) UpsertWorkspaceDNSRecord(ctx context.Context, arg database.UpsertWorkspaceDNSRecordParams) (database.WorkspaceDNSRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceDNSRecord", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceDNSRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceDNSRecord indicates an expected call of UpsertWorkspaceDNSRecord.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceDNSRecord(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceDNSRecord", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceDNSRecord), ctx, arg)
+}
+
+// UpsertWorkspaceDormancyExemption mocks base method.
+func (m *MockStore) UpsertWorkspaceDormancyExemption(ctx context.Context, arg database.UpsertWorkspaceDormancyExemptionParams) (database.WorkspaceDormancyExemption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceDormancyExemption", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceDormancyExemption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceDormancyExemption indicates an expected call of UpsertWorkspaceDormancyExemption.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceDormancyExemption(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceDormancyExemption", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceDormancyExemption), ctx, arg)
+}
+
+// UpsertWorkspaceDormancyHook mocks base method.
+func (m *MockStore) UpsertWorkspaceDormancyHook(ctx context.Context, arg database.UpsertWorkspaceDormancyHookParams) (database.WorkspaceDormancyHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceDormancyHook", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceDormancyHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceDormancyHook indicates an expected call of UpsertWorkspaceDormancyHook.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceDormancyHook(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceDormancyHook", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceDormancyHook), ctx, arg)
+}
+
+// UpsertWorkspaceEgressDaily mocks base method.
+func (m *MockStore) UpsertWorkspaceEgressDaily(ctx context.Context, arg database.UpsertWorkspaceEgressDailyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceEgressDaily", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceEgressDaily indicates an expected call of UpsertWorkspaceEgressDaily.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceEgressDaily(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceEgressDaily", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceEgressDaily), ctx, arg)
+}
+
+// UpsertWorkspaceLifetimeWarning mocks base method.
+func (m *MockStore) UpsertWorkspaceLifetimeWarning(ctx context.Context, arg database.UpsertWorkspaceLifetimeWarningParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceLifetimeWarning", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceLifetimeWarning indicates an expected call of UpsertWorkspaceLifetimeWarning.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceLifetimeWarning(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceLifetimeWarning", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceLifetimeWarning), ctx, arg)
+}
+
+// UpsertWorkspaceMonthlyCost mocks base method.
+func (m *MockStore) UpsertWorkspaceMonthlyCost(ctx context.Context, arg database.UpsertWorkspaceMonthlyCostParams) (database.WorkspaceMonthlyCost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceMonthlyCost", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceMonthlyCost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceMonthlyCost indicates an expected call of UpsertWorkspaceMonthlyCost.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceMonthlyCost(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceMonthlyCost", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceMonthlyCost), ctx, arg)
+}
+
+// UpsertWorkspaceNetworkPolicy mocks base method.
+func (m *MockStore) UpsertWorkspaceNetworkPolicy(ctx context.Context, arg database.UpsertWorkspaceNetworkPolicyParams) (database.WorkspaceNetworkPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceNetworkPolicy", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceNetworkPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceNetworkPolicy indicates an expected call of UpsertWorkspaceNetworkPolicy.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceNetworkPolicy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceNetworkPolicy", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceNetworkPolicy), ctx, arg)
+}
+
+// UpsertWorkspacePortForwardACL mocks base method.
+func (m *MockStore) UpsertWorkspacePortForwardACL(ctx context.Context, arg database.UpsertWorkspacePortForwardACLParams) (database.WorkspacePortForwardACL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspacePortForwardACL", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspacePortForwardACL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspacePortForwardACL indicates an expected call of UpsertWorkspacePortForwardACL.
+func (mr *MockStoreMockRecorder) UpsertWorkspacePortForwardACL(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspacePortForwardACL", reflect.TypeOf((*MockStore)(nil).UpsertWorkspacePortForwardACL), ctx, arg)
+}
+
+// UpsertWorkspaceRegionAssignment mocks base method.
+func (m *MockStore) UpsertWorkspaceRegionAssignment(ctx context.Context, arg database.UpsertWorkspaceRegionAssignmentParams) (database.WorkspaceRegionAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceRegionAssignment", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceRegionAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceRegionAssignment indicates an expected call of UpsertWorkspaceRegionAssignment.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceRegionAssignment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceRegionAssignment", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceRegionAssignment), ctx, arg)
+}
+
 // UsageEventExistsByID mocks base method.
 func (m *MockStore) UsageEventExistsByID(ctx context.Context, id string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -33,12 +33,20 @@ const (
 	boundaryLogsBatchSize = 10000
 	// Batch size for boundary session deletion.
 	boundarySessionsBatchSize = 10000
+	// Batch size for workspace agent metadata history deletion.
+	workspaceAgentMetadataHistoryBatchSize = 10000
 	// Telemetry heartbeats are used to deduplicate events across replicas. We
 	// don't need to persist heartbeat rows for longer than 24 hours, as they
 	// are only used for deduplication across replicas. The time needs to be
 	// long enough to cover the maximum interval of a heartbeat event (currently
 	// 1 hour) plus some buffer.
 	maxTelemetryHeartbeatAge = 24 * time.Hour
+	// Network policy violations only feed into workspace health, which looks
+	// at the last few minutes, so a week is plenty for troubleshooting.
+	maxWorkspaceAgentNetworkPolicyViolationAge = 7 * 24 * time.Hour
+	// Memory events back the workspace memory timeline, which users look at
+	// to understand why their processes were killed.
+	maxWorkspaceAgentMemoryEventAge = 7 * 24 * time.Hour
 	// Operational handoff state; terminal rows are kept for debugging, then
 	// purged.
 	workspaceBuildOrchestrationTerminalRetention = 24 * time.Hour
@@ -239,6 +247,17 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 		if err := tx.DeleteOldTelemetryLocks(ctx, deleteOldTelemetryLocksBefore); err != nil {
 			return xerrors.Errorf("failed to delete old telemetry locks: %w", err)
 		}
+		if err := tx.DeleteExpiredIdempotencyKeys(ctx, dbtime.Time(start)); err != nil {
+			return xerrors.Errorf("failed to delete expired idempotency keys: %w", err)
+		}
+		deleteOldNetworkPolicyViolationsBefore := start.Add(-maxWorkspaceAgentNetworkPolicyViolationAge)
+		if err := tx.DeleteOldWorkspaceAgentNetworkPolicyViolations(ctx, deleteOldNetworkPolicyViolationsBefore); err != nil {
+			return xerrors.Errorf("failed to delete old workspace agent network policy violations: %w", err)
+		}
+		deleteOldMemoryEventsBefore := start.Add(-maxWorkspaceAgentMemoryEventAge)
+		if err := tx.DeleteOldWorkspaceAgentMemoryEvents(ctx, deleteOldMemoryEventsBefore); err != nil {
+			return xerrors.Errorf("failed to delete old workspace agent memory events: %w", err)
+		}
 
 		deleteOldAuditLogConnectionEventsBefore := start.Add(-maxAuditLogConnectionEventAge)
 		if err := tx.DeleteOldAuditLogConnectionEvents(ctx, database.DeleteOldAuditLogConnectionEventsParams{
@@ -305,6 +324,18 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 			}
 		}
 
+		var purgedWorkspaceAgentMetadataHistory int64
+		workspaceAgentMetadataHistoryRetention := i.vals.Retention.WorkspaceAgentMetadataHistory.Value()
+		if workspaceAgentMetadataHistoryRetention > 0 {
+			purgedWorkspaceAgentMetadataHistory, err = tx.DeleteOldWorkspaceAgentMetadataHistory(ctx, database.DeleteOldWorkspaceAgentMetadataHistoryParams{
+				BeforeTime: start.Add(-workspaceAgentMetadataHistoryRetention),
+				LimitCount: workspaceAgentMetadataHistoryBatchSize,
+			})
+			if err != nil {
+				return xerrors.Errorf("failed to delete old workspace agent metadata history: %w", err)
+			}
+		}
+
 		deleteOldWorkspaceBuildOrchestrationsBefore := start.Add(-workspaceBuildOrchestrationTerminalRetention)
 		purgedWorkspaceBuildOrchestrations, err := tx.DeleteOldWorkspaceBuildOrchestrations(ctx, database.DeleteOldWorkspaceBuildOrchestrationsParams{
 			BeforeTime: deleteOldWorkspaceBuildOrchestrationsBefore,
@@ -362,6 +393,7 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 			slog.F("audit_logs", purgedAuditLogs),
 			slog.F("boundary_logs", purgedBoundaryLogs),
 			slog.F("boundary_sessions", purgedBoundarySessions),
+			slog.F("workspace_agent_metadata_history", purgedWorkspaceAgentMetadataHistory),
 			slog.F("workspace_build_orchestrations", purgedWorkspaceBuildOrchestrations),
 			slog.F("chats", purgedChats),
 			slog.F("chat_files", purgedChatFiles),
@@ -378,6 +410,7 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 			i.recordsPurged.WithLabelValues("audit_logs").Add(float64(purgedAuditLogs))
 			i.recordsPurged.WithLabelValues("boundary_logs").Add(float64(purgedBoundaryLogs))
 			i.recordsPurged.WithLabelValues("boundary_sessions").Add(float64(purgedBoundarySessions))
+			i.recordsPurged.WithLabelValues("workspace_agent_metadata_history").Add(float64(purgedWorkspaceAgentMetadataHistory))
 			i.recordsPurged.WithLabelValues("workspace_build_orchestrations").Add(float64(purgedWorkspaceBuildOrchestrations))
 			i.recordsPurged.WithLabelValues("chats").Add(float64(purgedChats))
 			i.recordsPurged.WithLabelValues("chat_debug_runs").Add(float64(purgedChatDebugRuns))
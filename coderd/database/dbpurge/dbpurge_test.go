@@ -253,6 +253,7 @@ func TestMetrics(t *testing.T) {
 		mDB.EXPECT().DeleteOldNotificationMessages(gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().ExpirePrebuildsAPIKeys(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldTelemetryLocks(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mDB.EXPECT().DeleteExpiredIdempotencyKeys(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldWorkspaceBuildOrchestrations(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
 		mDB.EXPECT().DeleteOldAuditLogConnectionEvents(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().BackfillChatMessagesSearchTsv(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
@@ -305,6 +306,7 @@ func TestMetrics(t *testing.T) {
 		mDB.EXPECT().DeleteOldNotificationMessages(gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().ExpirePrebuildsAPIKeys(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldTelemetryLocks(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mDB.EXPECT().DeleteExpiredIdempotencyKeys(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldWorkspaceBuildOrchestrations(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
 		mDB.EXPECT().DeleteOldAuditLogConnectionEvents(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().BackfillChatMessagesSearchTsv(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
@@ -2083,6 +2085,153 @@ func TestDeleteOldBoundarySessions(t *testing.T) {
 	}
 }
 
+func TestDeleteOldWorkspaceAgentMetadataHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	now := time.Date(2025, 1, 15, 7, 30, 0, 0, time.UTC)
+	clk := quartz.NewMock(t)
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	user := dbgen.User(t, db, database.User{})
+	org := dbgen.Organization(t, db, database.Organization{})
+	tv := dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, CreatedBy: user.ID})
+	tmpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, ActiveVersionID: tv.ID, CreatedBy: user.ID})
+	ws := dbgen.Workspace(t, db, database.WorkspaceTable{
+		OwnerID:        user.ID,
+		OrganizationID: org.ID,
+		TemplateID:     tmpl.ID,
+	})
+	wb := mustCreateWorkspaceBuild(t, db, org, tv, ws.ID, now, 1)
+	agent := mustCreateAgent(t, db, wb)
+
+	err := db.InsertWorkspaceAgentMetadataHistory(ctx, database.InsertWorkspaceAgentMetadataHistoryParams{
+		WorkspaceAgentID: []uuid.UUID{agent.ID, agent.ID},
+		Key:              []string{"gpu_temperature", "gpu_temperature"},
+		Value:            []string{"65", "71"},
+		Error:            []string{"", ""},
+		CollectedAt:      []time.Time{now.Add(-8 * 24 * time.Hour), now.Add(-time.Hour)},
+	})
+	require.NoError(t, err)
+
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{
+		Retention: codersdk.RetentionConfig{
+			WorkspaceAgentMetadataHistory: serpent.Duration(7 * 24 * time.Hour),
+		},
+	}, prometheus.NewRegistry(), dbpurge.WithClock(clk))
+	defer closer.Close()
+	testutil.TryReceive(ctx, t, done)
+
+	history, err := db.GetWorkspaceAgentMetadataHistory(ctx, database.GetWorkspaceAgentMetadataHistoryParams{
+		WorkspaceAgentID: agent.ID,
+		Key:              "gpu_temperature",
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 1, "only the value within the retention period should remain")
+	require.Equal(t, "71", history[0].Value)
+}
+
+func TestDeleteOldWorkspaceAgentNetworkPolicyViolations(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	now := time.Date(2025, 1, 15, 7, 30, 0, 0, time.UTC)
+	clk := quartz.NewMock(t)
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	user := dbgen.User(t, db, database.User{})
+	org := dbgen.Organization(t, db, database.Organization{})
+	tv := dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, CreatedBy: user.ID})
+	tmpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, ActiveVersionID: tv.ID, CreatedBy: user.ID})
+	ws := dbgen.Workspace(t, db, database.WorkspaceTable{
+		OwnerID:        user.ID,
+		OrganizationID: org.ID,
+		TemplateID:     tmpl.ID,
+	})
+	wb := mustCreateWorkspaceBuild(t, db, org, tv, ws.ID, now, 1)
+	agent := mustCreateAgent(t, db, wb)
+
+	for _, createdAt := range []time.Time{now.Add(-8 * 24 * time.Hour), now.Add(-time.Hour)} {
+		err := db.InsertWorkspaceAgentNetworkPolicyViolations(ctx, database.InsertWorkspaceAgentNetworkPolicyViolationsParams{
+			ID:               []uuid.UUID{uuid.New()},
+			WorkspaceAgentID: agent.ID,
+			CreatedAt:        createdAt,
+			Destination:      []string{"example.com"},
+			Port:             []int32{443},
+			Count:            []int32{1},
+		})
+		require.NoError(t, err)
+	}
+
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{}, prometheus.NewRegistry(), dbpurge.WithClock(clk))
+	defer closer.Close()
+	testutil.TryReceive(ctx, t, done)
+
+	violations, err := db.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(ctx, database.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDsParams{
+		IDs:          []uuid.UUID{agent.ID},
+		CreatedAfter: time.Time{},
+	})
+	require.NoError(t, err)
+	require.Len(t, violations, 1, "only the violation within the last week should remain")
+	require.Equal(t, now.Add(-time.Hour), violations[0].CreatedAt.UTC())
+}
+
+func TestDeleteOldWorkspaceAgentMemoryEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	now := time.Date(2025, 1, 15, 7, 30, 0, 0, time.UTC)
+	clk := quartz.NewMock(t)
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	user := dbgen.User(t, db, database.User{})
+	org := dbgen.Organization(t, db, database.Organization{})
+	tv := dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, CreatedBy: user.ID})
+	tmpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, ActiveVersionID: tv.ID, CreatedBy: user.ID})
+	ws := dbgen.Workspace(t, db, database.WorkspaceTable{
+		OwnerID:        user.ID,
+		OrganizationID: org.ID,
+		TemplateID:     tmpl.ID,
+	})
+	wb := mustCreateWorkspaceBuild(t, db, org, tv, ws.ID, now, 1)
+	agent := mustCreateAgent(t, db, wb)
+
+	for _, createdAt := range []time.Time{now.Add(-8 * 24 * time.Hour), now.Add(-time.Hour)} {
+		_, err := db.InsertWorkspaceAgentMemoryEvent(ctx, database.InsertWorkspaceAgentMemoryEventParams{
+			ID:               uuid.New(),
+			WorkspaceAgentID: agent.ID,
+			CreatedAt:        createdAt,
+			Kind:             database.WorkspaceAgentMemoryEventKindOomKill,
+			OomKills:         1,
+		})
+		require.NoError(t, err)
+	}
+
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{}, prometheus.NewRegistry(), dbpurge.WithClock(clk))
+	defer closer.Close()
+	testutil.TryReceive(ctx, t, done)
+
+	events, err := db.GetWorkspaceAgentMemoryEventsByAgentIDs(ctx, database.GetWorkspaceAgentMemoryEventsByAgentIDsParams{
+		IDs:          []uuid.UUID{agent.ID},
+		CreatedAfter: time.Time{},
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1, "only the event within the last week should remain")
+	require.Equal(t, now.Add(-time.Hour), events[0].CreatedAt.UTC())
+}
+
 func TestDeleteExpiredAPIKeys(t *testing.T) {
 	t.Parallel()
 
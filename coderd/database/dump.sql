@@ -10,6 +10,11 @@ CREATE TYPE agent_key_scope_enum AS ENUM (
     'no_user_data'
 );
 
+CREATE TYPE agent_rollout_channel AS ENUM (
+    'stable',
+    'beta'
+);
+
 CREATE TYPE ai_provider_type AS ENUM (
     'openai',
     'anthropic',
@@ -273,7 +278,10 @@ CREATE TYPE api_key_scope AS ENUM (
     'workspace_build_orchestration:create',
     'workspace_build_orchestration:delete',
     'workspace_build_orchestration:read',
-    'workspace_build_orchestration:update'
+    'workspace_build_orchestration:update',
+    'workspace:extend',
+    'workspace_dormant:extend',
+    'user:impersonate'
 );
 
 CREATE TYPE app_sharing_level AS ENUM (
@@ -296,7 +304,10 @@ CREATE TYPE audit_action AS ENUM (
     'connect',
     'disconnect',
     'open',
-    'close'
+    'close',
+    'upload',
+    'download',
+    'impersonate'
 );
 
 COMMENT ON TYPE audit_action IS 'NOTE: `connect`, `disconnect`, `open`, and `close` are deprecated and no longer used - these events are now tracked in the connection_logs table.';
@@ -320,7 +331,11 @@ CREATE TYPE build_reason AS ENUM (
     'jetbrains_connection',
     'task_auto_pause',
     'task_manual_pause',
-    'task_resume'
+    'task_resume',
+    'budget_exceeded',
+    'archive',
+    'unarchive',
+    'self_requested'
 );
 
 CREATE TYPE chat_client_type AS ENUM (
@@ -368,6 +383,13 @@ CREATE TYPE chat_status AS ENUM (
     'interrupting'
 );
 
+CREATE TYPE cloud_resource_verification_status AS ENUM (
+    'unverified',
+    'deleted',
+    'leaked',
+    'unknown'
+);
+
 CREATE TYPE connection_status AS ENUM (
     'connected',
     'disconnected'
@@ -397,7 +419,8 @@ CREATE TYPE crypto_key_feature AS ENUM (
     'workspace_apps_api_key',
     'oidc_convert',
     'tailnet_resume',
-    'nats_ca'
+    'nats_ca',
+    'ssh_host_ca'
 );
 
 CREATE TYPE display_app AS ENUM (
@@ -413,6 +436,11 @@ CREATE TYPE group_source AS ENUM (
     'oidc'
 );
 
+CREATE TYPE idempotency_key_operation AS ENUM (
+    'create_workspace',
+    'create_workspace_build'
+);
+
 CREATE TYPE inbox_notification_read_status AS ENUM (
     'all',
     'unread',
@@ -443,6 +471,11 @@ CREATE TYPE login_type AS ENUM (
 
 COMMENT ON TYPE login_type IS 'Specifies the method of authentication. "none" is a special case in which no authentication method is allowed.';
 
+CREATE TYPE max_lifetime_action AS ENUM (
+    'delete',
+    'stop'
+);
+
 CREATE TYPE name_organization_pair AS (
 	name text,
 	organization_id uuid
@@ -464,6 +497,11 @@ CREATE TYPE notification_method AS ENUM (
     'inbox'
 );
 
+CREATE TYPE notification_preference_scope AS ENUM (
+    'workspace',
+    'template'
+);
+
 CREATE TYPE notification_template_kind AS ENUM (
     'system',
     'custom'
@@ -512,6 +550,14 @@ CREATE TYPE port_share_protocol AS ENUM (
     'https'
 );
 
+CREATE TYPE prebuild_claim_miss_reason AS ENUM (
+    'reconciliation_lag',
+    'capacity',
+    'failures'
+);
+
+COMMENT ON TYPE prebuild_claim_miss_reason IS 'Why no prebuilt workspace could be claimed: reconciliation_lag means prebuilt workspaces were still being provisioned, capacity means the pool was drained by claims, and failures means prebuilt workspaces of the preset failed to build.';
+
 CREATE TYPE prebuild_status AS ENUM (
     'healthy',
     'hard_limited',
@@ -595,7 +641,11 @@ CREATE TYPE resource_type AS ENUM (
     'group_ai_budget',
     'user_skill',
     'ai_gateway_key',
-    'user_ai_budget_override'
+    'user_ai_budget_override',
+    'workspace_app_share_link',
+    'template_secret',
+    'deployment_bundle',
+    'vcs_event_mapping'
 );
 
 CREATE TYPE shareable_workspace_owners AS ENUM (
@@ -604,6 +654,11 @@ CREATE TYPE shareable_workspace_owners AS ENUM (
     'service_accounts'
 );
 
+CREATE TYPE shared_volume_access_mode AS ENUM (
+    'read_only',
+    'read_write'
+);
+
 CREATE TYPE startup_script_behavior AS ENUM (
     'blocking',
     'non-blocking'
@@ -627,6 +682,26 @@ CREATE TYPE task_status AS ENUM (
     'error'
 );
 
+CREATE TYPE template_version_rollout_status AS ENUM (
+    'in_progress',
+    'completed',
+    'rolled_back',
+    'aborted'
+);
+
+CREATE TYPE template_version_scan_status AS ENUM (
+    'running',
+    'completed',
+    'failed'
+);
+
+CREATE TYPE template_workspace_restart_status AS ENUM (
+    'in_progress',
+    'paused',
+    'completed',
+    'aborted'
+);
+
 CREATE TYPE user_status AS ENUM (
     'active',
     'suspended',
@@ -635,6 +710,12 @@ CREATE TYPE user_status AS ENUM (
 
 COMMENT ON TYPE user_status IS 'Defines the users status: active, dormant, or suspended.';
 
+CREATE TYPE vcs_event_action AS ENUM (
+    'start_workspace',
+    'refresh_prebuilds',
+    'update_workspaces'
+);
+
 CREATE TYPE workspace_agent_context_body_kind AS ENUM (
     'instruction_file',
     'skill',
@@ -666,6 +747,11 @@ CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'off'
 );
 
+CREATE TYPE workspace_agent_memory_event_kind AS ENUM (
+    'oom_kill',
+    'memory_pressure'
+);
+
 CREATE TYPE workspace_agent_monitor_state AS ENUM (
     'OK',
     'NOK'
@@ -688,6 +774,20 @@ CREATE TYPE workspace_agent_script_timing_status AS ENUM (
 
 COMMENT ON TYPE workspace_agent_script_timing_status IS 'What the exit status of the script is.';
 
+CREATE TYPE workspace_agent_service_restart_policy AS ENUM (
+    'always',
+    'on_failure',
+    'never'
+);
+
+CREATE TYPE workspace_agent_service_status AS ENUM (
+    'starting',
+    'running',
+    'restarting',
+    'stopped',
+    'failed'
+);
+
 CREATE TYPE workspace_agent_subsystem AS ENUM (
     'envbuilder',
     'envbox',
@@ -715,6 +815,29 @@ CREATE TYPE workspace_app_status_state AS ENUM (
     'idle'
 );
 
+CREATE TYPE workspace_build_parameter_change_kind AS ENUM (
+    'added',
+    'removed',
+    'modified'
+);
+
+CREATE TYPE workspace_cost_source AS ENUM (
+    'estimate',
+    'billing_export'
+);
+
+CREATE TYPE workspace_dormancy_hook_event AS ENUM (
+    'dormant',
+    'activate'
+);
+
+CREATE TYPE workspace_dormancy_hook_status AS ENUM (
+    'pending',
+    'running',
+    'succeeded',
+    'failed'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
@@ -1052,7 +1175,27 @@ $$;
 CREATE FUNCTION inhibit_enqueue_if_disabled() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
+DECLARE
+	scoped_disabled boolean;
 BEGIN
+	-- A preference for the workspace or template that the message is about
+	-- takes precedence over the preference for the notification template.
+	-- Workspace preferences are more specific than template ones.
+	SELECT notification_scoped_preferences.disabled INTO scoped_disabled
+	FROM notification_scoped_preferences
+	WHERE notification_scoped_preferences.user_id = NEW.user_id
+		AND notification_scoped_preferences.notification_template_id = NEW.notification_template_id
+		AND notification_scoped_preferences.target_id = ANY(NEW.targets)
+	ORDER BY (notification_scoped_preferences.scope = 'workspace') DESC
+	LIMIT 1;
+
+	IF FOUND THEN
+		IF scoped_disabled THEN
+			RAISE EXCEPTION 'cannot enqueue message: notification is not enabled';
+		END IF;
+		RETURN NEW;
+	END IF;
+
 	-- Fail the insertion if one of the following:
 	--  * the user has disabled this notification.
 	--  * the notification template is disabled by default and hasn't
@@ -1716,11 +1859,14 @@ CREATE TABLE api_keys (
     token_name text DEFAULT ''::text NOT NULL,
     scopes api_key_scope[] NOT NULL,
     allow_list text[] NOT NULL,
+    template_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
     CONSTRAINT api_keys_allow_list_not_empty CHECK ((array_length(allow_list, 1) > 0))
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.template_ids IS 'template_ids restricts workspace operations performed with the key to workspaces built from these templates. An empty array means the key is not restricted to any template.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -1736,9 +1882,12 @@ CREATE TABLE audit_logs (
     status_code integer NOT NULL,
     additional_fields jsonb NOT NULL,
     request_id uuid NOT NULL,
-    resource_icon text NOT NULL
+    resource_icon text NOT NULL,
+    correlation_id uuid
 );
 
+COMMENT ON COLUMN audit_logs.correlation_id IS 'The workspace build the entry is related to. All audit entries caused by one build share this ID.';
+
 CREATE TABLE boundary_logs (
     id uuid NOT NULL,
     session_id uuid NOT NULL,
@@ -2429,6 +2578,23 @@ CREATE VIEW group_members_expanded AS
      JOIN groups ON ((groups.id = all_members.group_id)))
   WHERE (users.deleted = false);
 
+CREATE TABLE idempotency_keys (
+    user_id uuid NOT NULL,
+    operation idempotency_key_operation NOT NULL,
+    key text NOT NULL,
+    request_hash bytea NOT NULL,
+    workspace_id uuid NOT NULL,
+    workspace_build_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE idempotency_keys IS 'Idempotency keys of workspace and workspace build creation requests. A retried request with the same key returns the workspace or build created by the original request instead of creating another one.';
+
+COMMENT ON COLUMN idempotency_keys.user_id IS 'The user that initiated the request. Keys are scoped to the user.';
+
+COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 hash of the request, so a key cannot be reused for a different request.';
+
 CREATE TABLE inbox_notifications (
     id uuid NOT NULL,
     user_id uuid NOT NULL,
@@ -2559,6 +2725,36 @@ CREATE TABLE notification_report_generator_logs (
 
 COMMENT ON TABLE notification_report_generator_logs IS 'Log of generated reports for users.';
 
+CREATE TABLE notification_scoped_preferences (
+    user_id uuid NOT NULL,
+    notification_template_id uuid NOT NULL,
+    scope notification_preference_scope NOT NULL,
+    target_id uuid NOT NULL,
+    disabled boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE notification_scoped_preferences IS 'Notification preferences of users that only apply to messages about a single workspace or template. They take precedence over notification_preferences.';
+
+COMMENT ON COLUMN notification_scoped_preferences.target_id IS 'ID of the workspace or template, matched against the targets of enqueued messages.';
+
+CREATE TABLE notification_template_webhooks (
+    notification_template_id uuid NOT NULL,
+    endpoint text DEFAULT ''::text NOT NULL,
+    payload_template text DEFAULT ''::text NOT NULL,
+    signing_secret text DEFAULT ''::text NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE notification_template_webhooks IS 'Overrides for the webhook delivery of the messages of a notification template.';
+
+COMMENT ON COLUMN notification_template_webhooks.endpoint IS 'Empty defers to the deployment-level webhook endpoint.';
+
+COMMENT ON COLUMN notification_template_webhooks.payload_template IS 'Go template rendering the request body. Empty sends the default JSON payload.';
+
+COMMENT ON COLUMN notification_template_webhooks.signing_secret IS 'Secret used to sign request bodies with HMAC-SHA256. Empty disables signing.';
+
 CREATE TABLE notification_templates (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -2714,13 +2910,20 @@ CREATE TABLE organizations (
     icon text DEFAULT ''::text NOT NULL,
     deleted boolean DEFAULT false NOT NULL,
     shareable_workspace_owners shareable_workspace_owners DEFAULT 'everyone'::shareable_workspace_owners NOT NULL,
-    default_org_member_roles text[] NOT NULL
+    default_org_member_roles text[] NOT NULL,
+    provisioner_tag_namespace text DEFAULT ''::text NOT NULL,
+    provisioner_daemon_quota integer DEFAULT 0 NOT NULL,
+    CONSTRAINT organizations_provisioner_daemon_quota_check CHECK ((provisioner_daemon_quota >= 0))
 );
 
 COMMENT ON COLUMN organizations.shareable_workspace_owners IS 'Controls whose workspaces can be shared: none, everyone, or service_accounts.';
 
 COMMENT ON COLUMN organizations.default_org_member_roles IS 'Roles granted to every member of this organization at request time. The set is unioned into each member''s effective roles when GetAuthorizationUserRoles runs, so changes propagate to all members on the next request. Deployments can use this column to revoke capabilities that would otherwise be considered normal organization member permissions.';
 
+COMMENT ON COLUMN organizations.provisioner_tag_namespace IS 'When set, provisioner daemons registered in this organization may only use tag keys prefixed with "<namespace>/", apart from the reserved scope and owner tags.';
+
+COMMENT ON COLUMN organizations.provisioner_daemon_quota IS 'Maximum number of provisioner daemons that may be connected to this organization at once. Zero means unlimited.';
+
 CREATE TABLE parameter_schemas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2753,6 +2956,21 @@ CREATE TABLE parameter_values (
     destination_scheme parameter_destination_scheme NOT NULL
 );
 
+CREATE TABLE prebuild_claim_attempts (
+    id uuid NOT NULL,
+    preset_id uuid NOT NULL,
+    workspace_id uuid,
+    claimed boolean NOT NULL,
+    miss_reason prebuild_claim_miss_reason,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE prebuild_claim_attempts IS 'Attempts to claim a prebuilt workspace when a workspace is created with a preset that has prebuilds configured.';
+
+COMMENT ON COLUMN prebuild_claim_attempts.workspace_id IS 'The claimed prebuilt workspace. NULL if no prebuilt workspace could be claimed.';
+
+COMMENT ON COLUMN prebuild_claim_attempts.miss_reason IS 'Why no prebuilt workspace could be claimed. NULL if the claim succeeded.';
+
 CREATE TABLE provisioner_daemons (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2769,6 +2987,16 @@ CREATE TABLE provisioner_daemons (
 
 COMMENT ON COLUMN provisioner_daemons.api_version IS 'The API version of the provisioner daemon';
 
+CREATE TABLE provisioner_job_log_archives (
+    job_id uuid NOT NULL,
+    archived_at timestamp with time zone NOT NULL,
+    object_key text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_log_archives IS 'Provisioner jobs whose logs were removed from the database by the build log retention policy.';
+
+COMMENT ON COLUMN provisioner_job_log_archives.object_key IS 'Key of the archived logs in the build log archive. Empty if the logs were deleted without being archived.';
+
 CREATE TABLE provisioner_job_logs (
     job_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2852,6 +3080,7 @@ CASE
 END) STORED NOT NULL,
     logs_length integer DEFAULT 0 NOT NULL,
     logs_overflowed boolean DEFAULT false NOT NULL,
+    timeout bigint DEFAULT 0 NOT NULL,
     CONSTRAINT max_provisioner_logs_length CHECK ((logs_length <= 1048576))
 );
 
@@ -2861,15 +3090,26 @@ COMMENT ON COLUMN provisioner_jobs.logs_length IS 'Total length of provisioner l
 
 COMMENT ON COLUMN provisioner_jobs.logs_overflowed IS 'Whether the provisioner logs overflowed in length';
 
+COMMENT ON COLUMN provisioner_jobs.timeout IS 'Maximum duration of the job in nanoseconds, taken from its template when the job is acquired. 0 means the job is not subject to a timeout.';
+
 CREATE TABLE provisioner_keys (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
     organization_id uuid NOT NULL,
     name character varying(64) NOT NULL,
     hashed_secret bytea NOT NULL,
-    tags jsonb NOT NULL
+    tags jsonb NOT NULL,
+    last_used_at timestamp with time zone,
+    previous_hashed_secret bytea,
+    previous_secret_expires_at timestamp with time zone
 );
 
+COMMENT ON COLUMN provisioner_keys.last_used_at IS 'The last time a provisioner daemon authenticated with the key. NULL if the key was never used.';
+
+COMMENT ON COLUMN provisioner_keys.previous_hashed_secret IS 'The hashed secret the key was rotated away from. It stays valid until previous_secret_expires_at, so that daemons can be moved to the new secret without downtime.';
+
+COMMENT ON COLUMN provisioner_keys.previous_secret_expires_at IS 'The time the previous secret of the key stops being valid at. NULL if the key was never rotated.';
+
 CREATE TABLE replicas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2894,6 +3134,38 @@ COMMENT ON COLUMN replicas.cluster_host IS 'Hostname or IP address the replica i
 
 COMMENT ON COLUMN replicas.nats_port IS 'Port number for NATS clustering. 0 means NATS is disabled.';
 
+CREATE TABLE shared_volume_attachments (
+    id uuid NOT NULL,
+    shared_volume_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    workspace_build_id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    mount_path text NOT NULL,
+    access_mode shared_volume_access_mode NOT NULL,
+    attached_at timestamp with time zone NOT NULL,
+    detached_at timestamp with time zone,
+    detached_by uuid
+);
+
+COMMENT ON TABLE shared_volume_attachments IS 'Attachments of shared volumes to workspace agents. An attachment is active until it is detached by a later build of the workspace or by an administrator.';
+
+COMMENT ON COLUMN shared_volume_attachments.detached_by IS 'The user that force-detached the volume, or NULL if it was detached by a build.';
+
+CREATE TABLE shared_volumes (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    name text NOT NULL,
+    display_name text DEFAULT ''::text NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    template_id uuid,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE shared_volumes IS 'Persistent volumes that multiple workspaces of an organization can attach.';
+
+COMMENT ON COLUMN shared_volumes.template_id IS 'The template that declared the volume on its first build, or NULL if the volume was declared for the organization.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value text NOT NULL
@@ -2993,6 +3265,7 @@ CREATE TABLE workspace_agents (
     parent_id uuid,
     api_key_scope agent_key_scope_enum DEFAULT 'all'::agent_key_scope_enum NOT NULL,
     deleted boolean DEFAULT false NOT NULL,
+    clock_skew_ms bigint,
     CONSTRAINT max_logs_length CHECK ((logs_length <= 1048576)),
     CONSTRAINT subsystems_not_none CHECK ((NOT ('none'::workspace_agent_subsystem = ANY (subsystems))))
 );
@@ -3023,6 +3296,8 @@ COMMENT ON COLUMN workspace_agents.api_key_scope IS 'Defines the scope of the AP
 
 COMMENT ON COLUMN workspace_agents.deleted IS 'Indicates whether or not the agent has been deleted. This is currently only applicable to sub agents.';
 
+COMMENT ON COLUMN workspace_agents.clock_skew_ms IS 'The offset of the clock of the agent from the clock of coderd in milliseconds, as last reported by the agent. Positive values mean the clock of the agent is ahead. NULL if the agent never reported it.';
+
 CREATE TABLE workspace_apps (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -3043,7 +3318,9 @@ CREATE TABLE workspace_apps (
     hidden boolean DEFAULT false NOT NULL,
     open_in workspace_app_open_in DEFAULT 'slim-window'::workspace_app_open_in NOT NULL,
     display_group text,
-    tooltip character varying(2048) DEFAULT ''::character varying NOT NULL
+    tooltip character varying(2048) DEFAULT ''::character varying NOT NULL,
+    max_session_duration_seconds integer DEFAULT 0 NOT NULL,
+    session_idle_timeout_seconds integer DEFAULT 0 NOT NULL
 );
 
 COMMENT ON COLUMN workspace_apps.display_order IS 'Specifies the order in which to display agent app in user interfaces.';
@@ -3052,6 +3329,10 @@ COMMENT ON COLUMN workspace_apps.hidden IS 'Determines if the app is not shown i
 
 COMMENT ON COLUMN workspace_apps.tooltip IS 'Markdown text that is displayed when hovering over workspace apps.';
 
+COMMENT ON COLUMN workspace_apps.max_session_duration_seconds IS 'Maximum lifetime of a session used to access the app, in seconds. Zero means unlimited.';
+
+COMMENT ON COLUMN workspace_apps.session_idle_timeout_seconds IS 'Duration of inactivity after which a session used to access the app expires, in seconds. Zero means unlimited.';
+
 CREATE TABLE workspace_builds (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -3095,12 +3376,21 @@ CREATE TABLE workspaces (
     next_start_at timestamp with time zone,
     group_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
     user_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    expires_at timestamp with time zone,
+    archived_at timestamp with time zone,
+    read_only_at timestamp with time zone,
     CONSTRAINT group_acl_is_object CHECK ((jsonb_typeof(group_acl) = 'object'::text)),
     CONSTRAINT user_acl_is_object CHECK ((jsonb_typeof(user_acl) = 'object'::text))
 );
 
 COMMENT ON COLUMN workspaces.favorite IS 'Favorite is true if the workspace owner has favorited the workspace.';
 
+COMMENT ON COLUMN workspaces.expires_at IS 'The time after which a trial workspace is stopped and then deleted regardless of activity. NULL means the workspace does not expire.';
+
+COMMENT ON COLUMN workspaces.archived_at IS 'The time the workspace was archived. Archived workspaces have no provisioned resources but keep their parameters and metadata, and must be unarchived before they can be started.';
+
+COMMENT ON COLUMN workspaces.read_only_at IS 'The time the workspace was placed in read-only mode. Users can still connect to a read-only workspace, but its agent blocks outbound git pushes and no new builds can be created until the mode is lifted.';
+
 CREATE VIEW tasks_with_status AS
  SELECT tasks.id,
     tasks.organization_id,
@@ -3200,6 +3490,66 @@ COMMENT ON COLUMN telemetry_locks.event_type IS 'The type of event that was sent
 
 COMMENT ON COLUMN telemetry_locks.period_ending_at IS 'The heartbeat period end timestamp.';
 
+CREATE TABLE template_health_reports (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    period_start timestamp with time zone NOT NULL,
+    period_end timestamp with time zone NOT NULL,
+    total_builds bigint NOT NULL,
+    failed_builds bigint NOT NULL,
+    average_start_seconds double precision NOT NULL,
+    total_workspaces bigint NOT NULL,
+    outdated_workspaces bigint NOT NULL,
+    dormant_workspaces bigint NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_health_reports IS 'Periodic health reports of templates, sent to template admins by the report generator.';
+
+COMMENT ON COLUMN template_health_reports.average_start_seconds IS 'Average time it took successful start builds of the period to complete, from when they were queued. Zero if there were none.';
+
+COMMENT ON COLUMN template_health_reports.outdated_workspaces IS 'Workspaces whose latest build does not use the active template version, at the end of the period.';
+
+CREATE TABLE template_network_policies (
+    template_id uuid NOT NULL,
+    allowed_domains text[] DEFAULT '{}'::text[] NOT NULL,
+    allowed_cidrs text[] DEFAULT '{}'::text[] NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_network_policies IS 'Default outbound network policy declared for the workspaces of a template. Enforcement (CNI plugins, egress proxies) happens outside of Coder.';
+
+CREATE TABLE template_preset_cohorts (
+    template_id uuid NOT NULL,
+    preset_name text NOT NULL,
+    group_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    roles text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_preset_cohorts IS 'Restricts the presets of a template with a given name, in every version of the template, to a cohort of users. Presets without a cohort are available to everyone who can use the template.';
+
+COMMENT ON COLUMN template_preset_cohorts.group_ids IS 'Members of any of these groups are part of the cohort.';
+
+COMMENT ON COLUMN template_preset_cohorts.roles IS 'Names of site-wide roles, or of roles in the organization of the template. Users with any of these roles are part of the cohort.';
+
+CREATE TABLE template_secrets (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    name text NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    value text NOT NULL,
+    value_key_id text,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_secrets IS 'Secrets declared by a template that agent scripts can reference. They are fetched by the agent at run time and only exposed to the environment of the scripts that reference them, so they never end up in Terraform state.';
+
+COMMENT ON COLUMN template_secrets.name IS 'The name of the secret, which is also the environment variable it is exposed as.';
+
+COMMENT ON COLUMN template_secrets.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted.';
+
 CREATE TABLE template_usage_stats (
     start_time timestamp with time zone NOT NULL,
     end_time timestamp with time zone NOT NULL,
@@ -3241,6 +3591,20 @@ COMMENT ON COLUMN template_usage_stats.jetbrains_mins IS 'Total minutes the user
 
 COMMENT ON COLUMN template_usage_stats.app_usage_mins IS 'Object with app names as keys and total minutes used as values. Null means no app usage was recorded.';
 
+CREATE TABLE template_user_presets (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    parameters jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_user_presets IS 'Named sets of parameter values that a user saved for a template. Unlike template version presets, they are private to the user.';
+
+COMMENT ON COLUMN template_user_presets.parameters IS 'Array of objects with the name and value of each parameter.';
+
 CREATE TABLE template_version_parameters (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -3260,6 +3624,10 @@ CREATE TABLE template_version_parameters (
     display_order integer DEFAULT 0 NOT NULL,
     ephemeral boolean DEFAULT false NOT NULL,
     form_type parameter_form_type DEFAULT ''::parameter_form_type NOT NULL,
+    sensitive boolean DEFAULT false NOT NULL,
+    group_name text DEFAULT ''::text NOT NULL,
+    group_order integer DEFAULT 0 NOT NULL,
+    page integer DEFAULT 0 NOT NULL,
     CONSTRAINT validation_monotonic_order CHECK ((validation_monotonic = ANY (ARRAY['increasing'::text, 'decreasing'::text, ''::text])))
 );
 
@@ -3297,6 +3665,14 @@ COMMENT ON COLUMN template_version_parameters.ephemeral IS 'The value of an ephe
 
 COMMENT ON COLUMN template_version_parameters.form_type IS 'Specify what form_type should be used to render the parameter in the UI. Unsupported values are rejected.';
 
+COMMENT ON COLUMN template_version_parameters.sensitive IS 'The values of a sensitive parameter are encrypted at rest and redacted in API responses.';
+
+COMMENT ON COLUMN template_version_parameters.group_name IS 'Name of the group of the parameter, used to present parameters in sections. Empty if the parameter is not grouped.';
+
+COMMENT ON COLUMN template_version_parameters.group_order IS 'Specifies the order in which to display the group of the parameter. All parameters of a group share it.';
+
+COMMENT ON COLUMN template_version_parameters.page IS 'Page of a multi-page form on which to display the parameter. All parameters of a group share it.';
+
 CREATE TABLE template_version_preset_parameters (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
     template_version_preset_id uuid NOT NULL,
@@ -3330,6 +3706,53 @@ COMMENT ON COLUMN template_version_presets.description IS 'Short text describing
 
 COMMENT ON COLUMN template_version_presets.icon IS 'URL or path to an icon representing the preset (max 256 characters).';
 
+CREATE TABLE template_version_rollouts (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    previous_version_id uuid NOT NULL,
+    group_id uuid,
+    stages integer[] DEFAULT '{}'::integer[] NOT NULL,
+    current_stage integer DEFAULT 0 NOT NULL,
+    soak_duration bigint NOT NULL,
+    max_failure_rate double precision NOT NULL,
+    min_builds integer NOT NULL,
+    status template_version_rollout_status DEFAULT 'in_progress'::template_version_rollout_status NOT NULL,
+    status_message text DEFAULT ''::text NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    stage_started_at timestamp with time zone NOT NULL,
+    completed_at timestamp with time zone
+);
+
+COMMENT ON TABLE template_version_rollouts IS 'Staged promotions of a template version. Builds of workspaces in the cohort of the current stage that use the active version get the rolled out version instead, until the rollout is completed and the version is promoted, or rolled back.';
+
+COMMENT ON COLUMN template_version_rollouts.previous_version_id IS 'The active version of the template when the rollout was created. The rollout is aborted if the active version changes before it is completed.';
+
+COMMENT ON COLUMN template_version_rollouts.group_id IS 'The workspaces of the members of this group are part of the cohort of every stage.';
+
+COMMENT ON COLUMN template_version_rollouts.stages IS 'The percentage of workspaces in the cohort of each stage.';
+
+COMMENT ON COLUMN template_version_rollouts.soak_duration IS 'How long each stage is observed before the rollout continues, in nanoseconds.';
+
+COMMENT ON COLUMN template_version_rollouts.max_failure_rate IS 'The rollout is rolled back once the share of failed start builds of the version in a stage exceeds this rate.';
+
+COMMENT ON COLUMN template_version_rollouts.min_builds IS 'The number of completed start builds in a stage before its failure rate is considered.';
+
+CREATE TABLE template_version_scans (
+    template_version_id uuid NOT NULL,
+    status template_version_scan_status NOT NULL,
+    findings jsonb DEFAULT '[]'::jsonb NOT NULL,
+    error text DEFAULT ''::text NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    completed_at timestamp with time zone
+);
+
+COMMENT ON TABLE template_version_scans IS 'Results of submitting template version files and plan output to the configured template scanner.';
+
+COMMENT ON COLUMN template_version_scans.findings IS 'Findings reported by the scanner, as a JSON array of objects with severity, rule_id, message and path.';
+
 CREATE TABLE template_version_terraform_values (
     template_version_id uuid NOT NULL,
     updated_at timestamp with time zone DEFAULT now() NOT NULL,
@@ -3380,13 +3803,19 @@ CREATE TABLE template_versions (
     archived boolean DEFAULT false NOT NULL,
     source_example_id text,
     has_ai_task boolean,
-    has_external_agent boolean
+    has_external_agent boolean,
+    deprecated text DEFAULT ''::text NOT NULL,
+    deprecation_cutoff timestamp with time zone
 );
 
 COMMENT ON COLUMN template_versions.external_auth_providers IS 'IDs of External auth providers for a specific template version';
 
 COMMENT ON COLUMN template_versions.message IS 'Message describing the changes in this version of the template, similar to a Git commit message. Like a commit message, this should be a short, high-level description of the changes in this version of the template. This message is immutable and should not be updated after the fact.';
 
+COMMENT ON COLUMN template_versions.deprecated IS 'If set to a non empty string, the template version is deprecated. The message is shown to users building workspaces with it.';
+
+COMMENT ON COLUMN template_versions.deprecation_cutoff IS 'If set, the deprecated template version may still be used for builds until this time. After it, new builds with it are blocked.';
+
 CREATE VIEW template_version_with_user AS
  SELECT template_versions.id,
     template_versions.template_id,
@@ -3403,6 +3832,8 @@ CREATE VIEW template_version_with_user AS
     template_versions.source_example_id,
     template_versions.has_ai_task,
     template_versions.has_external_agent,
+    template_versions.deprecated,
+    template_versions.deprecation_cutoff,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username,
     COALESCE(visible_users.name, ''::text) AS created_by_name
@@ -3417,6 +3848,51 @@ CREATE TABLE template_version_workspace_tags (
     value text NOT NULL
 );
 
+CREATE TABLE template_workspace_restart_builds (
+    restart_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    workspace_build_id uuid,
+    error text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_workspace_restart_builds IS 'The workspaces restarted by a template workspace restart. Every workspace is restarted at most once.';
+
+COMMENT ON COLUMN template_workspace_restart_builds.error IS 'Why the build could not be created, in which case workspace_build_id is NULL.';
+
+CREATE TABLE template_workspace_restarts (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    batch_size integer NOT NULL,
+    batch_interval bigint NOT NULL,
+    honor_quiet_hours boolean NOT NULL,
+    max_failure_rate double precision NOT NULL,
+    min_builds integer NOT NULL,
+    status template_workspace_restart_status DEFAULT 'in_progress'::template_workspace_restart_status NOT NULL,
+    status_message text DEFAULT ''::text NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    stats_since timestamp with time zone NOT NULL,
+    last_batch_at timestamp with time zone,
+    completed_at timestamp with time zone
+);
+
+COMMENT ON TABLE template_workspace_restarts IS 'Restarts of the outdated running workspaces of a template on the version that was active when the restart was created, in batches.';
+
+COMMENT ON COLUMN template_workspace_restarts.template_version_id IS 'The version workspaces are restarted on. The restart is aborted if the active version of the template changes before it is completed.';
+
+COMMENT ON COLUMN template_workspace_restarts.batch_interval IS 'The minimum time between batches, in nanoseconds.';
+
+COMMENT ON COLUMN template_workspace_restarts.honor_quiet_hours IS 'Whether workspaces are only restarted during the quiet hours of their owner.';
+
+COMMENT ON COLUMN template_workspace_restarts.max_failure_rate IS 'The restart is paused once the share of failed restarts since stats_since exceeds this rate.';
+
+COMMENT ON COLUMN template_workspace_restarts.min_builds IS 'The number of completed restarts since stats_since before the failure rate is considered.';
+
+COMMENT ON COLUMN template_workspace_restarts.stats_since IS 'The failure rate only considers restarts since this time. It is reset when a paused restart is resumed.';
+
 CREATE TABLE templates (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -3449,7 +3925,28 @@ CREATE TABLE templates (
     use_classic_parameter_flow boolean DEFAULT false NOT NULL,
     cors_behavior cors_behavior DEFAULT 'simple'::cors_behavior NOT NULL,
     disable_module_cache boolean DEFAULT false NOT NULL,
-    time_til_autostop_notify bigint DEFAULT 0 NOT NULL
+    time_til_autostop_notify bigint DEFAULT 0 NOT NULL,
+    provisioner_plan_timeout bigint DEFAULT 0 NOT NULL,
+    provisioner_apply_timeout bigint DEFAULT 0 NOT NULL,
+    trial_workspace_ttl bigint DEFAULT 0 NOT NULL,
+    requeue_reaped_builds boolean DEFAULT false NOT NULL,
+    agent_rollout_channel agent_rollout_channel DEFAULT 'stable'::agent_rollout_channel NOT NULL,
+    allow_targeted_builds boolean DEFAULT false NOT NULL,
+    reconfirm_parameters text[] DEFAULT '{}'::text[] NOT NULL,
+    deprecation_cutoff timestamp with time zone,
+    nightly_stop_time text DEFAULT ''::text NOT NULL,
+    build_log_retention bigint DEFAULT 0 NOT NULL,
+    max_lifetime bigint DEFAULT 0 NOT NULL,
+    max_lifetime_action max_lifetime_action DEFAULT 'delete'::max_lifetime_action NOT NULL,
+    idle_reclaim_ttl bigint DEFAULT 0 NOT NULL,
+    idle_reclaim_resource_selector text DEFAULT ''::text NOT NULL,
+    activity_bump_connection_types text[] DEFAULT '{}'::text[] NOT NULL,
+    activity_bump_max_per_day bigint DEFAULT 0 NOT NULL,
+    pre_build_hook_url text DEFAULT ''::text NOT NULL,
+    post_build_hook_url text DEFAULT ''::text NOT NULL,
+    auto_assign_region boolean DEFAULT false NOT NULL,
+    notify_build_failures boolean DEFAULT false NOT NULL,
+    disable_provider_cache boolean DEFAULT false NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -3474,6 +3971,48 @@ COMMENT ON COLUMN templates.use_classic_parameter_flow IS 'Determines whether to
 
 COMMENT ON COLUMN templates.time_til_autostop_notify IS 'How long before the workspace autostop deadline to send a reminder notification, in nanoseconds. 0 disables the notification.';
 
+COMMENT ON COLUMN templates.provisioner_plan_timeout IS 'Maximum duration of template version import and dry-run jobs, in nanoseconds. 0 means the jobs are not subject to a timeout.';
+
+COMMENT ON COLUMN templates.provisioner_apply_timeout IS 'Maximum duration of workspace build jobs, in nanoseconds. 0 means the jobs are not subject to a timeout.';
+
+COMMENT ON COLUMN templates.trial_workspace_ttl IS 'Hard lifetime of workspaces created from this template, in nanoseconds. Expired workspaces are stopped and then deleted regardless of activity. 0 disables the expiry.';
+
+COMMENT ON COLUMN templates.requeue_reaped_builds IS 'Whether workspace builds reaped by the job reaper because their provisioner stopped responding are automatically requeued once.';
+
+COMMENT ON COLUMN templates.agent_rollout_channel IS 'The channel workspace agents of this template download their binary from on start.';
+
+COMMENT ON COLUMN templates.allow_targeted_builds IS 'Allows workspace builds that only replace selected Terraform resources of the workspace.';
+
+COMMENT ON COLUMN templates.reconfirm_parameters IS 'Names of parameters whose values users must re-enter when updating a workspace to a new template version.';
+
+COMMENT ON COLUMN templates.deprecation_cutoff IS 'If set, the deprecated template may still be used to create workspaces until this time. After it, creation is blocked.';
+
+COMMENT ON COLUMN templates.nightly_stop_time IS 'If set, running workspaces of this template are stopped every day at this time (HH:MM), regardless of activity. The time is interpreted in the timezone of the workspace owner''s quiet hours schedule.';
+
+COMMENT ON COLUMN templates.build_log_retention IS 'How long provisioner job logs of this template are kept before they are archived or deleted, in nanoseconds. 0 uses the deployment-wide retention.';
+
+COMMENT ON COLUMN templates.max_lifetime IS 'Maximum lifetime of workspaces created from this template, measured from their creation, in nanoseconds. Workspaces past it are stopped or deleted regardless of activity. 0 disables the limit.';
+
+COMMENT ON COLUMN templates.max_lifetime_action IS 'What happens to workspaces that exceed the max lifetime of the template.';
+
+COMMENT ON COLUMN templates.idle_reclaim_ttl IS 'How long running workspaces with a resource matching idle_reclaim_resource_selector may be idle before they are stopped, in nanoseconds. Typically much shorter than the default TTL, to reclaim expensive resources such as GPUs. 0 disables idle reclaim.';
+
+COMMENT ON COLUMN templates.idle_reclaim_resource_selector IS 'A key=value pair matched against the metadata of workspace resources to select the workspaces subject to idle reclaim.';
+
+COMMENT ON COLUMN templates.activity_bump_connection_types IS 'The connection types whose activity bumps the deadline of workspaces, e.g. ssh or vscode. Empty means all connection types bump the deadline.';
+
+COMMENT ON COLUMN templates.activity_bump_max_per_day IS 'The maximum total duration activity may bump the deadline of a workspace per UTC day, in nanoseconds. 0 means unlimited.';
+
+COMMENT ON COLUMN templates.pre_build_hook_url IS 'If set, coderd POSTs to this URL before every workspace build of the template is provisioned. The hook may reject the build.';
+
+COMMENT ON COLUMN templates.post_build_hook_url IS 'If set, coderd POSTs to this URL after every successful workspace build of the template. The hook may report external provisioning steps, which are recorded in the build logs.';
+
+COMMENT ON COLUMN templates.auto_assign_region IS 'If set, workspaces created from the template are assigned the region recommended for the latencies reported by the creating client.';
+
+COMMENT ON COLUMN templates.notify_build_failures IS 'If set, the owners of workspaces created from the template, and the initiators of their builds, are notified of every failed build along with an excerpt of its logs.';
+
+COMMENT ON COLUMN templates.disable_provider_cache IS 'If set, provisioners install the Terraform providers of the template from their registries during workspace builds instead of reusing them from the shared plugin cache.';
+
 CREATE VIEW template_with_names AS
  SELECT templates.id,
     templates.created_at,
@@ -3507,6 +4046,27 @@ CREATE VIEW template_with_names AS
     templates.cors_behavior,
     templates.disable_module_cache,
     templates.time_til_autostop_notify,
+    templates.provisioner_plan_timeout,
+    templates.provisioner_apply_timeout,
+    templates.trial_workspace_ttl,
+    templates.requeue_reaped_builds,
+    templates.agent_rollout_channel,
+    templates.allow_targeted_builds,
+    templates.reconfirm_parameters,
+    templates.deprecation_cutoff,
+    templates.nightly_stop_time,
+    templates.build_log_retention,
+    templates.max_lifetime,
+    templates.max_lifetime_action,
+    templates.idle_reclaim_ttl,
+    templates.idle_reclaim_resource_selector,
+    templates.activity_bump_connection_types,
+    templates.activity_bump_max_per_day,
+    templates.pre_build_hook_url,
+    templates.post_build_hook_url,
+    templates.auto_assign_region,
+    templates.notify_build_failures,
+    templates.disable_provider_cache,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username,
     COALESCE(visible_users.name, ''::text) AS created_by_name,
@@ -3596,6 +4156,29 @@ CREATE TABLE user_deleted (
 
 COMMENT ON TABLE user_deleted IS 'Tracks when users were deleted';
 
+CREATE TABLE user_impersonations (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    impersonator_id uuid NOT NULL,
+    reason text NOT NULL,
+    lifetime_seconds bigint NOT NULL,
+    consent_required boolean NOT NULL,
+    consented_at timestamp with time zone,
+    api_key_id text,
+    issued_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_impersonations IS 'Requests of support staff to act as a user. A token impersonating the user is issued once the request is granted.';
+
+COMMENT ON COLUMN user_impersonations.user_id IS 'The impersonated user.';
+
+COMMENT ON COLUMN user_impersonations.lifetime_seconds IS 'The lifetime of the token issued for the request.';
+
+COMMENT ON COLUMN user_impersonations.consented_at IS 'When the impersonated user consented to the request. Only set when consent is required.';
+
+COMMENT ON COLUMN user_impersonations.api_key_id IS 'The token issued for the request. Cleared once the token is deleted.';
+
 CREATE TABLE user_links (
     user_id uuid NOT NULL,
     login_type login_type NOT NULL,
@@ -3614,6 +4197,20 @@ COMMENT ON COLUMN user_links.oauth_refresh_token_key_id IS 'The ID of the key us
 
 COMMENT ON COLUMN user_links.claims IS 'Claims from the IDP for the linked user. Includes both id_token and userinfo claims. ';
 
+CREATE TABLE user_schedule_pauses (
+    user_id uuid NOT NULL,
+    paused_until timestamp with time zone NOT NULL,
+    pause_autostop boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_schedule_pauses IS 'Pauses of the autostart schedules of the workspaces of users, for example while they are on leave.';
+
+COMMENT ON COLUMN user_schedule_pauses.paused_until IS 'The time the pause ends at. Autostarts that were due during the pause are skipped.';
+
+COMMENT ON COLUMN user_schedule_pauses.pause_autostop IS 'Whether autostops at the deadline of builds are paused as well. Dormancy and deletion are never paused.';
+
 CREATE TABLE user_secrets (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
     user_id uuid NOT NULL,
@@ -3650,6 +4247,50 @@ CREATE TABLE user_status_changes (
 
 COMMENT ON TABLE user_status_changes IS 'Tracks the history of user status changes';
 
+CREATE TABLE user_workspace_reports (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    period_start timestamp with time zone NOT NULL,
+    period_end timestamp with time zone NOT NULL,
+    summary jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_workspace_reports IS 'Periodic summaries of the workspaces of users, sent to them by the report generator.';
+
+COMMENT ON COLUMN user_workspace_reports.summary IS 'Builds of the workspaces of the user during the period, and the workspaces that will be deleted or become dormant soon, as served by the API.';
+
+CREATE TABLE vcs_event_mappings (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    name text NOT NULL,
+    repository text NOT NULL,
+    branch text DEFAULT ''::text NOT NULL,
+    action vcs_event_action NOT NULL,
+    workspace_id uuid,
+    signing_secret text NOT NULL,
+    signing_secret_key_id text,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    last_triggered_at timestamp with time zone,
+    CONSTRAINT vcs_event_mappings_workspace_id_check CHECK (((action = 'start_workspace'::vcs_event_action) = (workspace_id IS NOT NULL)))
+);
+
+COMMENT ON TABLE vcs_event_mappings IS 'Maps the push events of a VCS repository, received by the VCS webhook receiver, to an action on a template or one of its workspaces.';
+
+COMMENT ON COLUMN vcs_event_mappings.repository IS 'The full name of the repository, e.g. "coder/coder". Matched case-insensitively.';
+
+COMMENT ON COLUMN vcs_event_mappings.branch IS 'The branch pushes must be made to. Empty matches every branch.';
+
+COMMENT ON COLUMN vcs_event_mappings.workspace_id IS 'The workspace started by the start_workspace action. NULL for the other actions.';
+
+COMMENT ON COLUMN vcs_event_mappings.signing_secret IS 'The secret of the repository webhook, used to verify the signature of events. GitHub signs the body with HMAC-SHA256, GitLab sends the secret as is.';
+
+COMMENT ON COLUMN vcs_event_mappings.signing_secret_key_id IS 'The ID of the key used to encrypt the signing secret. If this is NULL, the signing secret is not encrypted.';
+
+COMMENT ON COLUMN vcs_event_mappings.created_by IS 'The user the actions of the mapping are performed as.';
+
 CREATE TABLE webpush_subscriptions (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
     user_id uuid NOT NULL,
@@ -3659,6 +4300,28 @@ CREATE TABLE webpush_subscriptions (
     endpoint_auth_key text NOT NULL
 );
 
+CREATE TABLE workspace_activity_bumps (
+    workspace_id uuid NOT NULL,
+    date date NOT NULL,
+    bumped bigint DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE workspace_activity_bumps IS 'The total duration, in nanoseconds, activity bumped the deadline of each workspace on the given UTC day. Used to enforce the activity_bump_max_per_day of templates.';
+
+CREATE TABLE workspace_agent_bootstrap_progress (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    name text NOT NULL,
+    percent integer,
+    message text DEFAULT ''::text NOT NULL,
+    CONSTRAINT workspace_agent_bootstrap_progress_percent_check CHECK (((percent >= 0) AND (percent <= 100)))
+);
+
+COMMENT ON TABLE workspace_agent_bootstrap_progress IS 'Named progress milestones reported by the startup scripts of a workspace agent. The most recent milestone is the current one.';
+
+COMMENT ON COLUMN workspace_agent_bootstrap_progress.percent IS 'Completion of the milestone in percent, if the script reported it.';
+
 CREATE TABLE workspace_agent_context_resources (
     workspace_agent_id uuid NOT NULL,
     source text NOT NULL,
@@ -3709,6 +4372,25 @@ COMMENT ON COLUMN workspace_agent_context_snapshots.snapshot_error IS 'Singular
 
 COMMENT ON COLUMN workspace_agent_context_snapshots.received_at IS 'Time at which coderd received the push.';
 
+CREATE TABLE workspace_agent_crash_loops (
+    agent_id uuid NOT NULL,
+    short_connections integer NOT NULL,
+    last_disconnected_at timestamp with time zone NOT NULL,
+    last_disconnect_reason text DEFAULT ''::text NOT NULL,
+    last_lifecycle_state workspace_agent_lifecycle_state NOT NULL,
+    crash_looping_since timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_agent_crash_loops IS 'Tracks agents that repeatedly disconnect shortly after connecting. The row is removed once the agent stays connected.';
+
+COMMENT ON COLUMN workspace_agent_crash_loops.short_connections IS 'The number of consecutive connections of the agent that ended before becoming stable.';
+
+COMMENT ON COLUMN workspace_agent_crash_loops.last_disconnect_reason IS 'Why the last connection of the agent ended.';
+
+COMMENT ON COLUMN workspace_agent_crash_loops.last_lifecycle_state IS 'The lifecycle state of the agent when its last connection ended.';
+
+COMMENT ON COLUMN workspace_agent_crash_loops.crash_looping_since IS 'When the agent was detected to be crash looping. Reconnects are backed off while set.';
+
 CREATE TABLE workspace_agent_devcontainers (
     id uuid NOT NULL,
     workspace_agent_id uuid NOT NULL,
@@ -3750,6 +4432,24 @@ CREATE UNLOGGED TABLE workspace_agent_logs (
     log_source_id uuid DEFAULT '00000000-0000-0000-0000-000000000000'::uuid NOT NULL
 );
 
+CREATE TABLE workspace_agent_memory_events (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    kind workspace_agent_memory_event_kind NOT NULL,
+    oom_kills integer DEFAULT 0 NOT NULL,
+    memory_pressure double precision DEFAULT 0 NOT NULL,
+    CONSTRAINT workspace_agent_memory_events_oom_kills_check CHECK ((oom_kills >= 0))
+);
+
+COMMENT ON TABLE workspace_agent_memory_events IS 'OOM kills and severe memory pressure reported by the resources monitor of a workspace agent.';
+
+COMMENT ON COLUMN workspace_agent_memory_events.created_at IS 'When the agent collected the datapoint the event was derived from.';
+
+COMMENT ON COLUMN workspace_agent_memory_events.oom_kills IS 'Number of processes killed by the OOM killer since the previous datapoint. Zero for memory pressure events.';
+
+COMMENT ON COLUMN workspace_agent_memory_events.memory_pressure IS 'Percentage of time in the last 10 seconds during which all non-idle tasks of the workspace were stalled on memory.';
+
 CREATE TABLE workspace_agent_memory_resource_monitors (
     agent_id uuid NOT NULL,
     enabled boolean NOT NULL,
@@ -3775,6 +4475,33 @@ CREATE UNLOGGED TABLE workspace_agent_metadata (
 
 COMMENT ON COLUMN workspace_agent_metadata.display_order IS 'Specifies the order in which to display agent metadata in user interfaces.';
 
+CREATE TABLE workspace_agent_metadata_history (
+    workspace_agent_id uuid NOT NULL,
+    key character varying(127) NOT NULL,
+    value character varying(65535) DEFAULT ''::character varying NOT NULL,
+    error character varying(65535) DEFAULT ''::character varying NOT NULL,
+    collected_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_metadata_history IS 'Historical values of workspace agent metadata. Only recorded when a metadata history retention is configured, and purged after it.';
+
+CREATE TABLE workspace_agent_network_policy_violations (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    destination text NOT NULL,
+    port integer NOT NULL,
+    count integer NOT NULL,
+    CONSTRAINT workspace_agent_network_policy_violations_count_check CHECK ((count > 0)),
+    CONSTRAINT workspace_agent_network_policy_violations_port_check CHECK (((port >= 0) AND (port <= 65535)))
+);
+
+COMMENT ON TABLE workspace_agent_network_policy_violations IS 'Outbound connections reported by a workspace agent that were not allowed by the network policy of its workspace.';
+
+COMMENT ON COLUMN workspace_agent_network_policy_violations.destination IS 'The domain or IP address the workspace tried to connect to.';
+
+COMMENT ON COLUMN workspace_agent_network_policy_violations.count IS 'Number of blocked connections to the destination since the previous report.';
+
 CREATE TABLE workspace_agent_port_share (
     workspace_id uuid NOT NULL,
     agent_name text NOT NULL,
@@ -3807,6 +4534,34 @@ CREATE TABLE workspace_agent_scripts (
     id uuid DEFAULT gen_random_uuid() NOT NULL
 );
 
+CREATE TABLE workspace_agent_service_transitions (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    service_name text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    status workspace_agent_service_status NOT NULL,
+    message text DEFAULT ''::text NOT NULL,
+    exit_code integer,
+    restart_count integer DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_service_transitions IS 'Status transitions of the services of a workspace agent. The most recent transition is the current status of the service.';
+
+COMMENT ON COLUMN workspace_agent_service_transitions.exit_code IS 'Exit code of the service process, if the transition was caused by the process exiting.';
+
+COMMENT ON COLUMN workspace_agent_service_transitions.restart_count IS 'Number of times the agent restarted the service since the agent started.';
+
+CREATE TABLE workspace_agent_services (
+    workspace_agent_id uuid NOT NULL,
+    name text NOT NULL,
+    display_name text NOT NULL,
+    command text NOT NULL,
+    restart_policy workspace_agent_service_restart_policy NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_services IS 'Long-running processes declared by the template that the workspace agent supervises.';
+
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -3881,6 +4636,26 @@ COMMENT ON COLUMN workspace_app_audit_sessions.started_at IS 'The time the user
 
 COMMENT ON COLUMN workspace_app_audit_sessions.updated_at IS 'The time the session was last updated.';
 
+CREATE TABLE workspace_app_share_links (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    agent_name text NOT NULL,
+    app_slug text NOT NULL,
+    hashed_secret bytea NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    revoked_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_app_share_links IS 'Expiring links that grant visitors authenticated by the OIDC provider of the deployment access to a workspace app, without a Coder account.';
+
+COMMENT ON COLUMN workspace_app_share_links.agent_name IS 'The agent of the shared app. Apps are recreated on every build, so they are referenced by agent name and slug.';
+
+COMMENT ON COLUMN workspace_app_share_links.hashed_secret IS 'The SHA-256 hash of the secret included in the link.';
+
+COMMENT ON COLUMN workspace_app_share_links.revoked_at IS 'When the link was revoked. Revoked links no longer grant access.';
+
 CREATE TABLE workspace_app_stats (
     id bigint NOT NULL,
     user_id uuid NOT NULL,
@@ -3936,6 +4711,26 @@ CREATE TABLE workspace_app_statuses (
     uri text
 );
 
+CREATE TABLE workspace_build_annotations (
+    id uuid NOT NULL,
+    workspace_build_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    note text NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_annotations IS 'Free-form notes attached to a workspace build by users, e.g. why the build was run.';
+
+CREATE TABLE workspace_build_inputs (
+    workspace_build_id uuid NOT NULL,
+    inputs jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_inputs IS 'Immutable record of everything a workspace build was provisioned with, captured when its provisioner job is acquired. Used to reproduce a build in another deployment.';
+
+COMMENT ON COLUMN workspace_build_inputs.inputs IS 'The build input bundle. Secrets, such as sensitive template variables and owner tokens, are never included.';
+
 CREATE TABLE workspace_build_orchestrations (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -3974,16 +4769,35 @@ COMMENT ON COLUMN workspace_build_orchestrations.attempt_count IS 'Counts retrya
 
 COMMENT ON COLUMN workspace_build_orchestrations.next_retry_after IS 'When set, the orchestrator skips this pending row until the timestamp has passed.';
 
+CREATE TABLE workspace_build_parameter_changes (
+    workspace_build_id uuid NOT NULL,
+    name text NOT NULL,
+    kind workspace_build_parameter_change_kind NOT NULL,
+    previous_value text DEFAULT ''::text NOT NULL,
+    new_value text DEFAULT ''::text NOT NULL,
+    redacted boolean DEFAULT false NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_parameter_changes IS 'Rich parameter values that differ between a workspace build and the previous build of the same workspace.';
+
+COMMENT ON COLUMN workspace_build_parameter_changes.redacted IS 'Whether the values were withheld because the parameter is ephemeral. previous_value and new_value are empty when set.';
+
 CREATE TABLE workspace_build_parameters (
     workspace_build_id uuid NOT NULL,
     name text NOT NULL,
-    value text NOT NULL
+    value text NOT NULL,
+    sensitive boolean DEFAULT false NOT NULL,
+    value_key_id text
 );
 
 COMMENT ON COLUMN workspace_build_parameters.name IS 'Parameter name';
 
 COMMENT ON COLUMN workspace_build_parameters.value IS 'Parameter value';
 
+COMMENT ON COLUMN workspace_build_parameters.sensitive IS 'Whether the parameter was sensitive in the template version of the build.';
+
+COMMENT ON COLUMN workspace_build_parameters.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted.';
+
 CREATE VIEW workspace_build_with_user AS
  SELECT workspace_builds.id,
     workspace_builds.created_at,
@@ -4036,6 +4850,114 @@ CREATE VIEW workspace_latest_builds AS
   WHERE (workspaces.deleted = false)
   ORDER BY workspaces.id;
 
+CREATE TABLE workspace_cloud_resources (
+    workspace_id uuid NOT NULL,
+    resource_type text NOT NULL,
+    cloud_id text NOT NULL,
+    resource_name text NOT NULL,
+    workspace_build_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    verification_status cloud_resource_verification_status DEFAULT 'unverified'::cloud_resource_verification_status NOT NULL,
+    verification_build_id uuid,
+    verification_error text DEFAULT ''::text NOT NULL,
+    verified_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_cloud_resources IS 'Identifiers of the cloud resources reported by the builds of a workspace. Once the workspace is deleted, each identifier is checked with the cloud provider to detect resources that were leaked by the deletion.';
+
+COMMENT ON COLUMN workspace_cloud_resources.cloud_id IS 'Identifier of the resource at its cloud provider, e.g. the id attribute of a Terraform resource.';
+
+COMMENT ON COLUMN workspace_cloud_resources.workspace_build_id IS 'The latest build that reported the resource.';
+
+COMMENT ON COLUMN workspace_cloud_resources.verification_build_id IS 'The deletion build after which the resource was verified.';
+
+CREATE TABLE workspace_dns_records (
+    workspace_id uuid NOT NULL,
+    name text NOT NULL,
+    target text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_dns_records IS 'DNS names registered with the configured workspace DNS provider for running workspaces.';
+
+COMMENT ON COLUMN workspace_dns_records.target IS 'The host the DNS name was pointed at when it was registered.';
+
+CREATE TABLE workspace_dormancy_exemptions (
+    workspace_id uuid NOT NULL,
+    reason text NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    approved_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_dormancy_exemptions IS 'Exempts a workspace from being marked dormant by the template time_til_dormant policy until expires_at.';
+
+COMMENT ON COLUMN workspace_dormancy_exemptions.approved_by IS 'The template administrator that granted the exemption.';
+
+CREATE TABLE workspace_dormancy_hooks (
+    workspace_id uuid NOT NULL,
+    event workspace_dormancy_hook_event NOT NULL,
+    status workspace_dormancy_hook_status DEFAULT 'pending'::workspace_dormancy_hook_status NOT NULL,
+    error text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_dormancy_hooks IS 'The latest dormancy lifecycle hook invocation of a workspace. Hooks move the storage of dormant workspaces to cheaper tiers and restore it on activation.';
+
+COMMENT ON COLUMN workspace_dormancy_hooks.event IS 'The lifecycle event the hook is invoked for. An activate hook must succeed before a dormant workspace can be started.';
+
+COMMENT ON COLUMN workspace_dormancy_hooks.error IS 'The error returned by the last failed invocation of the hook.';
+
+CREATE TABLE workspace_egress_daily (
+    workspace_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    date date NOT NULL,
+    rx_bytes_ssh bigint DEFAULT 0 NOT NULL,
+    tx_bytes_ssh bigint DEFAULT 0 NOT NULL,
+    rx_bytes_reconnecting_pty bigint DEFAULT 0 NOT NULL,
+    tx_bytes_reconnecting_pty bigint DEFAULT 0 NOT NULL,
+    rx_bytes_port_forward bigint DEFAULT 0 NOT NULL,
+    tx_bytes_port_forward bigint DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE workspace_egress_daily IS 'Bytes received and transmitted by workspace agents, aggregated per workspace and UTC day and split by connection class. Used for network chargeback.';
+
+CREATE TABLE workspace_leases (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    api_key_id text NOT NULL,
+    credential_key_id text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    released_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_leases IS 'Time-bound leases on ephemeral workspaces, typically created by CI jobs. A workspace is deleted once its lease expires or is released.';
+
+COMMENT ON COLUMN workspace_leases.api_key_id IS 'The API key that created the lease. Concurrency caps are enforced per key.';
+
+COMMENT ON COLUMN workspace_leases.credential_key_id IS 'The API key minted for the lease holder. It is deleted when the lease is released.';
+
+CREATE TABLE workspace_lifetime_warnings (
+    workspace_id uuid NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    days_before integer NOT NULL,
+    sent_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_lifetime_warnings IS 'The most recent warning sent to the owner of a workspace approaching the max lifetime of its template.';
+
+COMMENT ON COLUMN workspace_lifetime_warnings.expires_at IS 'The expiry the warning was sent for. A change to the template max lifetime moves the expiry and re-arms the warnings.';
+
+COMMENT ON COLUMN workspace_lifetime_warnings.days_before IS 'How many days before the expiry the warning was sent for.';
+
 CREATE TABLE workspace_modules (
     id uuid NOT NULL,
     job_id uuid NOT NULL,
@@ -4046,6 +4968,42 @@ CREATE TABLE workspace_modules (
     created_at timestamp with time zone NOT NULL
 );
 
+CREATE TABLE workspace_monthly_costs (
+    workspace_id uuid NOT NULL,
+    month timestamp with time zone NOT NULL,
+    cost double precision DEFAULT 0 NOT NULL,
+    source workspace_cost_source DEFAULT 'estimate'::workspace_cost_source NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_monthly_costs IS 'Cost counters of workspaces per calendar month, used to enforce monthly cost budgets.';
+
+COMMENT ON COLUMN workspace_monthly_costs.month IS 'The start of the UTC calendar month the cost was accrued in.';
+
+COMMENT ON COLUMN workspace_monthly_costs.cost IS 'The cost accrued in the month, in the same units as the daily cost of workspace resources.';
+
+COMMENT ON COLUMN workspace_monthly_costs.source IS 'Estimated counters accrue the daily cost of the running build. Counters imported from a billing export replace the estimate and are not accrued.';
+
+CREATE TABLE workspace_network_policies (
+    workspace_id uuid NOT NULL,
+    allowed_domains text[] DEFAULT '{}'::text[] NOT NULL,
+    allowed_cidrs text[] DEFAULT '{}'::text[] NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_network_policies IS 'Outbound network policy declared for a single workspace. Replaces the policy of the template of the workspace.';
+
+CREATE TABLE workspace_port_forward_acls (
+    workspace_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    ports integer[] NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT workspace_port_forward_acls_ports_check CHECK ((cardinality(ports) > 0))
+);
+
+COMMENT ON TABLE workspace_port_forward_acls IS 'Ports of a shared workspace that a user may connect to. Users the workspace is shared with that have no row may connect to any port.';
+
 CREATE VIEW workspace_prebuild_builds AS
  SELECT workspace_builds.id,
     workspace_builds.workspace_id,
@@ -4150,6 +5108,16 @@ CREATE SEQUENCE workspace_proxies_region_id_seq
 
 ALTER SEQUENCE workspace_proxies_region_id_seq OWNED BY workspace_proxies.region_id;
 
+CREATE TABLE workspace_region_assignments (
+    workspace_id uuid NOT NULL,
+    region_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_region_assignments IS 'The region assigned to a workspace when it was created from a template with auto_assign_region set.';
+
+COMMENT ON COLUMN workspace_region_assignments.region_id IS 'The ID of the workspace proxy, or the deployment ID for the primary region.';
+
 CREATE TABLE workspace_resource_metadata (
     workspace_resource_id uuid NOT NULL,
     key character varying(1024) NOT NULL,
@@ -4186,6 +5154,9 @@ CREATE VIEW workspaces_expanded AS
     workspaces.next_start_at,
     workspaces.group_acl,
     workspaces.user_acl,
+    workspaces.expires_at,
+    workspaces.archived_at,
+    workspaces.read_only_at,
     visible_users.avatar_url AS owner_avatar_url,
     visible_users.username AS owner_username,
     visible_users.name AS owner_name,
@@ -4359,6 +5330,9 @@ ALTER TABLE ONLY groups
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY idempotency_keys
+    ADD CONSTRAINT idempotency_keys_pkey PRIMARY KEY (user_id, operation, key);
+
 ALTER TABLE ONLY inbox_notifications
     ADD CONSTRAINT inbox_notifications_pkey PRIMARY KEY (id);
 
@@ -4392,6 +5366,12 @@ ALTER TABLE ONLY notification_preferences
 ALTER TABLE ONLY notification_report_generator_logs
     ADD CONSTRAINT notification_report_generator_logs_pkey PRIMARY KEY (notification_template_id);
 
+ALTER TABLE ONLY notification_scoped_preferences
+    ADD CONSTRAINT notification_scoped_preferences_pkey PRIMARY KEY (user_id, notification_template_id, target_id);
+
+ALTER TABLE ONLY notification_template_webhooks
+    ADD CONSTRAINT notification_template_webhooks_pkey PRIMARY KEY (notification_template_id);
+
 ALTER TABLE ONLY notification_templates
     ADD CONSTRAINT notification_templates_name_key UNIQUE (name);
 
@@ -4437,9 +5417,15 @@ ALTER TABLE ONLY parameter_values
 ALTER TABLE ONLY parameter_values
     ADD CONSTRAINT parameter_values_scope_id_name_key UNIQUE (scope_id, name);
 
+ALTER TABLE ONLY prebuild_claim_attempts
+    ADD CONSTRAINT prebuild_claim_attempts_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY provisioner_daemons
     ADD CONSTRAINT provisioner_daemons_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY provisioner_job_log_archives
+    ADD CONSTRAINT provisioner_job_log_archives_pkey PRIMARY KEY (job_id);
+
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
 
@@ -4449,6 +5435,15 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY shared_volume_attachments
+    ADD CONSTRAINT shared_volume_attachments_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY shared_volumes
+    ADD CONSTRAINT shared_volumes_organization_id_name_key UNIQUE (organization_id, name);
+
+ALTER TABLE ONLY shared_volumes
+    ADD CONSTRAINT shared_volumes_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -4476,9 +5471,27 @@ ALTER TABLE ONLY telemetry_items
 ALTER TABLE ONLY telemetry_locks
     ADD CONSTRAINT telemetry_locks_pkey PRIMARY KEY (event_type, period_ending_at);
 
+ALTER TABLE ONLY template_health_reports
+    ADD CONSTRAINT template_health_reports_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_network_policies
+    ADD CONSTRAINT template_network_policies_pkey PRIMARY KEY (template_id);
+
+ALTER TABLE ONLY template_preset_cohorts
+    ADD CONSTRAINT template_preset_cohorts_pkey PRIMARY KEY (template_id, preset_name);
+
+ALTER TABLE ONLY template_secrets
+    ADD CONSTRAINT template_secrets_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY template_usage_stats
     ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
 
+ALTER TABLE ONLY template_user_presets
+    ADD CONSTRAINT template_user_presets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_user_presets
+    ADD CONSTRAINT template_user_presets_template_id_user_id_name_key UNIQUE (template_id, user_id, name);
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 
@@ -4494,6 +5507,12 @@ ALTER TABLE ONLY template_version_presets
 ALTER TABLE ONLY template_version_presets
     ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_version_scans
+    ADD CONSTRAINT template_version_scans_pkey PRIMARY KEY (template_version_id);
+
 ALTER TABLE ONLY template_version_terraform_values
     ADD CONSTRAINT template_version_terraform_values_template_version_id_key UNIQUE (template_version_id);
 
@@ -4509,6 +5528,12 @@ ALTER TABLE ONLY template_versions
 ALTER TABLE ONLY template_versions
     ADD CONSTRAINT template_versions_template_id_name_key UNIQUE (template_id, name);
 
+ALTER TABLE ONLY template_workspace_restart_builds
+    ADD CONSTRAINT template_workspace_restart_builds_pkey PRIMARY KEY (restart_id, workspace_id);
+
+ALTER TABLE ONLY template_workspace_restarts
+    ADD CONSTRAINT template_workspace_restarts_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
 
@@ -4533,9 +5558,15 @@ ALTER TABLE ONLY user_configs
 ALTER TABLE ONLY user_deleted
     ADD CONSTRAINT user_deleted_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY user_impersonations
+    ADD CONSTRAINT user_impersonations_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
 
+ALTER TABLE ONLY user_schedule_pauses
+    ADD CONSTRAINT user_schedule_pauses_pkey PRIMARY KEY (user_id);
+
 ALTER TABLE ONLY user_secrets
     ADD CONSTRAINT user_secrets_pkey PRIMARY KEY (id);
 
@@ -4545,30 +5576,54 @@ ALTER TABLE ONLY user_skills
 ALTER TABLE ONLY user_status_changes
     ADD CONSTRAINT user_status_changes_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY user_workspace_reports
+    ADD CONSTRAINT user_workspace_reports_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY vcs_event_mappings
+    ADD CONSTRAINT vcs_event_mappings_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_activity_bumps
+    ADD CONSTRAINT workspace_activity_bumps_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_agent_bootstrap_progress
+    ADD CONSTRAINT workspace_agent_bootstrap_progress_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_context_resources
     ADD CONSTRAINT workspace_agent_context_resources_pkey PRIMARY KEY (workspace_agent_id, source);
 
 ALTER TABLE ONLY workspace_agent_context_snapshots
     ADD CONSTRAINT workspace_agent_context_snapshots_pkey PRIMARY KEY (workspace_agent_id);
 
+ALTER TABLE ONLY workspace_agent_crash_loops
+    ADD CONSTRAINT workspace_agent_crash_loops_pkey PRIMARY KEY (agent_id);
+
 ALTER TABLE ONLY workspace_agent_devcontainers
     ADD CONSTRAINT workspace_agent_devcontainers_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
 
+ALTER TABLE ONLY workspace_agent_memory_events
+    ADD CONSTRAINT workspace_agent_memory_events_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_memory_resource_monitors
     ADD CONSTRAINT workspace_agent_memory_resource_monitors_pkey PRIMARY KEY (agent_id);
 
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
 
+ALTER TABLE ONLY workspace_agent_metadata_history
+    ADD CONSTRAINT workspace_agent_metadata_history_pkey PRIMARY KEY (workspace_agent_id, key, collected_at);
+
+ALTER TABLE ONLY workspace_agent_network_policy_violations
+    ADD CONSTRAINT workspace_agent_network_policy_violations_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
 
@@ -4578,6 +5633,12 @@ ALTER TABLE ONLY workspace_agent_script_timings
 ALTER TABLE ONLY workspace_agent_scripts
     ADD CONSTRAINT workspace_agent_scripts_id_key UNIQUE (id);
 
+ALTER TABLE ONLY workspace_agent_service_transitions
+    ADD CONSTRAINT workspace_agent_service_transitions_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_services
+    ADD CONSTRAINT workspace_agent_services_pkey PRIMARY KEY (workspace_agent_id, name);
+
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
@@ -4593,6 +5654,9 @@ ALTER TABLE ONLY workspace_app_audit_sessions
 ALTER TABLE ONLY workspace_app_audit_sessions
     ADD CONSTRAINT workspace_app_audit_sessions_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_app_share_links
+    ADD CONSTRAINT workspace_app_share_links_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_app_stats
     ADD CONSTRAINT workspace_app_stats_pkey PRIMARY KEY (id);
 
@@ -4608,6 +5672,12 @@ ALTER TABLE ONLY workspace_apps
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_build_annotations
+    ADD CONSTRAINT workspace_build_annotations_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_build_inputs
+    ADD CONSTRAINT workspace_build_inputs_pkey PRIMARY KEY (workspace_build_id);
+
 ALTER TABLE ONLY workspace_build_orchestrations
     ADD CONSTRAINT workspace_build_orchestrations_child_build_id_key UNIQUE (child_build_id);
 
@@ -4617,6 +5687,9 @@ ALTER TABLE ONLY workspace_build_orchestrations
 ALTER TABLE ONLY workspace_build_orchestrations
     ADD CONSTRAINT workspace_build_orchestrations_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_build_parameter_changes
+    ADD CONSTRAINT workspace_build_parameter_changes_pkey PRIMARY KEY (workspace_build_id, name);
+
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
 
@@ -4632,12 +5705,45 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_cloud_resources
+    ADD CONSTRAINT workspace_cloud_resources_pkey PRIMARY KEY (workspace_id, resource_type, cloud_id);
+
+ALTER TABLE ONLY workspace_dns_records
+    ADD CONSTRAINT workspace_dns_records_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_dormancy_exemptions
+    ADD CONSTRAINT workspace_dormancy_exemptions_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_dormancy_hooks
+    ADD CONSTRAINT workspace_dormancy_hooks_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_egress_daily
+    ADD CONSTRAINT workspace_egress_daily_pkey PRIMARY KEY (workspace_id, date);
+
+ALTER TABLE ONLY workspace_leases
+    ADD CONSTRAINT workspace_leases_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_lifetime_warnings
+    ADD CONSTRAINT workspace_lifetime_warnings_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_monthly_costs
+    ADD CONSTRAINT workspace_monthly_costs_pkey PRIMARY KEY (workspace_id, month);
+
+ALTER TABLE ONLY workspace_network_policies
+    ADD CONSTRAINT workspace_network_policies_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_port_forward_acls
+    ADD CONSTRAINT workspace_port_forward_acls_pkey PRIMARY KEY (workspace_id, user_id);
+
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
 
+ALTER TABLE ONLY workspace_region_assignments
+    ADD CONSTRAINT workspace_region_assignments_pkey PRIMARY KEY (workspace_id);
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
 
@@ -4724,6 +5830,8 @@ CREATE INDEX idx_audit_log_resource_id ON audit_logs USING btree (resource_id);
 
 CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
 
+CREATE INDEX idx_audit_logs_correlation_id ON audit_logs USING btree (correlation_id) WHERE (correlation_id IS NOT NULL);
+
 CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
 
 CREATE INDEX idx_boundary_logs_captured_at ON boundary_logs USING btree (captured_at);
@@ -4824,6 +5932,8 @@ CREATE INDEX idx_custom_roles_id ON custom_roles USING btree (id);
 
 CREATE UNIQUE INDEX idx_custom_roles_name_lower_organization_id ON custom_roles USING btree (lower(name), COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
 
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys USING btree (expires_at);
+
 CREATE INDEX idx_inbox_notifications_user_id_read_at ON inbox_notifications USING btree (user_id, read_at);
 
 CREATE INDEX idx_inbox_notifications_user_id_template_id_targets ON inbox_notifications USING btree (user_id, template_id, targets);
@@ -4842,6 +5952,8 @@ CREATE INDEX idx_organization_member_user_id_uuid ON organization_members USING
 
 CREATE UNIQUE INDEX idx_organization_name_lower ON organizations USING btree (lower(name)) WHERE (deleted = false);
 
+CREATE INDEX idx_prebuild_claim_attempts_created_at ON prebuild_claim_attempts USING btree (created_at);
+
 CREATE UNIQUE INDEX idx_provisioner_daemons_org_name_owner_key ON provisioner_daemons USING btree (organization_id, name, lower(COALESCE((tags ->> 'owner'::text), ''::text)));
 
 COMMENT ON INDEX idx_provisioner_daemons_org_name_owner_key IS 'Allow unique provisioner daemon names by organization and user';
@@ -4856,6 +5968,8 @@ CREATE INDEX idx_tailnet_tunnels_src_id ON tailnet_tunnels USING hash (src_id);
 
 CREATE INDEX idx_telemetry_locks_period_ending_at ON telemetry_locks USING btree (period_ending_at);
 
+CREATE INDEX idx_template_user_presets_user_id ON template_user_presets USING btree (user_id);
+
 CREATE UNIQUE INDEX idx_template_version_presets_default ON template_version_presets USING btree (template_version_id) WHERE (is_default = true);
 
 CREATE INDEX idx_template_versions_has_ai_task ON template_versions USING btree (has_ai_task);
@@ -4870,16 +5984,24 @@ CREATE INDEX idx_user_ai_provider_keys_ai_provider_id ON user_ai_provider_keys U
 
 CREATE INDEX idx_user_deleted_deleted_at ON user_deleted USING btree (deleted_at);
 
+CREATE INDEX idx_user_impersonations_api_key_id ON user_impersonations USING btree (api_key_id);
+
+CREATE INDEX idx_user_impersonations_user_id_created_at ON user_impersonations USING btree (user_id, created_at DESC);
+
 CREATE INDEX idx_user_status_changes_changed_at ON user_status_changes USING btree (changed_at);
 
 CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE ((deleted = false) AND (email <> ''::text));
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX idx_workspace_app_share_links_workspace_id ON workspace_app_share_links USING btree (workspace_id);
+
 CREATE INDEX idx_workspace_app_statuses_workspace_id_created_at ON workspace_app_statuses USING btree (workspace_id, created_at DESC);
 
 CREATE INDEX idx_workspace_build_orchestrations_pending ON workspace_build_orchestrations USING btree (created_at) WHERE (status = 'pending'::text);
 
+CREATE INDEX idx_workspace_cloud_resources_verification_status ON workspace_cloud_resources USING btree (verification_status);
+
 CREATE INDEX idx_workspace_builds_initiator_id ON workspace_builds USING btree (initiator_id);
 
 CREATE UNIQUE INDEX notification_messages_dedupe_hash_idx ON notification_messages USING btree (dedupe_hash);
@@ -4896,6 +6018,10 @@ COMMENT ON INDEX provisioner_jobs_worker_id_organization_id_completed_at_idx IS
 
 CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
 
+CREATE UNIQUE INDEX shared_volume_attachments_read_write_lock_idx ON shared_volume_attachments USING btree (shared_volume_id) WHERE ((access_mode = 'read_write'::shared_volume_access_mode) AND (detached_at IS NULL));
+
+CREATE INDEX shared_volume_attachments_workspace_id_idx ON shared_volume_attachments USING btree (workspace_id) WHERE (detached_at IS NULL);
+
 CREATE INDEX tasks_organization_id_idx ON tasks USING btree (organization_id);
 
 CREATE INDEX tasks_owner_id_idx ON tasks USING btree (owner_id);
@@ -4906,6 +6032,10 @@ COMMENT ON INDEX tasks_owner_id_name_unique_idx IS 'Index to ensure uniqueness f
 
 CREATE INDEX tasks_workspace_id_idx ON tasks USING btree (workspace_id);
 
+CREATE UNIQUE INDEX template_secrets_template_id_name_idx ON template_secrets USING btree (template_id, name);
+
+CREATE INDEX template_health_reports_template_id_idx ON template_health_reports USING btree (template_id, period_end DESC);
+
 CREATE INDEX template_usage_stats_start_time_idx ON template_usage_stats USING btree (start_time DESC);
 
 COMMENT ON INDEX template_usage_stats_start_time_idx IS 'Index for querying MAX(start_time).';
@@ -4914,6 +6044,14 @@ CREATE UNIQUE INDEX template_usage_stats_start_time_template_id_user_id_idx ON t
 
 COMMENT ON INDEX template_usage_stats_start_time_template_id_user_id_idx IS 'Index for primary key.';
 
+CREATE UNIQUE INDEX template_version_rollouts_in_progress_idx ON template_version_rollouts USING btree (template_id) WHERE (status = 'in_progress'::template_version_rollout_status);
+
+CREATE INDEX template_version_rollouts_template_id_idx ON template_version_rollouts USING btree (template_id, created_at DESC);
+
+CREATE UNIQUE INDEX template_workspace_restarts_active_idx ON template_workspace_restarts USING btree (template_id) WHERE (status = ANY (ARRAY['in_progress'::template_workspace_restart_status, 'paused'::template_workspace_restart_status]));
+
+CREATE INDEX template_workspace_restarts_template_id_idx ON template_workspace_restarts USING btree (template_id, created_at DESC);
+
 CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX user_links_linked_id_login_type_idx ON user_links USING btree (linked_id, login_type) WHERE (linked_id <> ''::text);
@@ -4926,20 +6064,36 @@ CREATE UNIQUE INDEX user_secrets_user_name_idx ON user_secrets USING btree (user
 
 CREATE UNIQUE INDEX user_skills_user_id_name_idx ON user_skills USING btree (user_id, name);
 
+CREATE INDEX user_workspace_reports_user_id_idx ON user_workspace_reports USING btree (user_id, period_end DESC);
+
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE ((deleted = false) AND (email <> ''::text));
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX vcs_event_mappings_repository_idx ON vcs_event_mappings USING btree (lower(repository));
+
+CREATE UNIQUE INDEX vcs_event_mappings_template_id_name_idx ON vcs_event_mappings USING btree (template_id, name);
+
 CREATE UNIQUE INDEX webpush_subscriptions_user_id_endpoint_idx ON webpush_subscriptions USING btree (user_id, endpoint);
 
+CREATE INDEX workspace_agent_bootstrap_progress_workspace_agent_id_idx ON workspace_agent_bootstrap_progress USING btree (workspace_agent_id, created_at);
+
 CREATE INDEX workspace_agent_devcontainers_workspace_agent_id ON workspace_agent_devcontainers USING btree (workspace_agent_id);
 
 COMMENT ON INDEX workspace_agent_devcontainers_workspace_agent_id IS 'Workspace agent foreign key and query index';
 
+CREATE INDEX workspace_agent_memory_events_workspace_agent_id_idx ON workspace_agent_memory_events USING btree (workspace_agent_id, created_at);
+
+CREATE INDEX workspace_agent_metadata_history_collected_at_idx ON workspace_agent_metadata_history USING btree (collected_at);
+
+CREATE INDEX workspace_agent_network_policy_violations_workspace_agent_id_idx ON workspace_agent_network_policy_violations USING btree (workspace_agent_id, created_at);
+
 CREATE INDEX workspace_agent_scripts_workspace_agent_id_idx ON workspace_agent_scripts USING btree (workspace_agent_id);
 
 COMMENT ON INDEX workspace_agent_scripts_workspace_agent_id_idx IS 'Foreign key support index for faster lookups';
 
+CREATE INDEX workspace_agent_service_transitions_workspace_agent_id_idx ON workspace_agent_service_transitions USING btree (workspace_agent_id, created_at);
+
 CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_logs USING btree (agent_id, id);
 
 CREATE INDEX workspace_agent_stats_template_id_created_at_user_id_idx ON workspace_agent_stats USING btree (template_id, created_at, user_id) INCLUDE (session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, connection_median_latency_ms) WHERE (connection_count > 0);
@@ -4960,16 +6114,34 @@ CREATE INDEX workspace_app_stats_workspace_id_idx ON workspace_app_stats USING b
 
 CREATE INDEX workspace_app_statuses_app_id_idx ON workspace_app_statuses USING btree (app_id, created_at DESC);
 
+CREATE INDEX workspace_build_annotations_workspace_build_id_idx ON workspace_build_annotations USING btree (workspace_build_id);
+
+CREATE INDEX workspace_dormancy_hooks_status_idx ON workspace_dormancy_hooks USING btree (status, updated_at) WHERE (status = ANY (ARRAY['pending'::workspace_dormancy_hook_status, 'running'::workspace_dormancy_hook_status]));
+
+CREATE INDEX workspace_egress_daily_date_idx ON workspace_egress_daily USING btree (date);
+
+CREATE INDEX workspace_leases_api_key_id_idx ON workspace_leases USING btree (api_key_id) WHERE (released_at IS NULL);
+
+CREATE INDEX workspace_leases_expires_at_idx ON workspace_leases USING btree (expires_at) WHERE (released_at IS NULL);
+
 CREATE INDEX workspace_modules_created_at_idx ON workspace_modules USING btree (created_at);
 
 CREATE INDEX workspace_next_start_at_idx ON workspaces USING btree (next_start_at) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
 
+CREATE INDEX workspace_region_assignments_region_id_idx ON workspace_region_assignments USING btree (region_id);
+
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
 
 CREATE INDEX workspace_template_id_idx ON workspaces USING btree (template_id) WHERE (deleted = false);
 
+CREATE INDEX workspaces_created_at_idx ON workspaces USING btree (created_at) WHERE (deleted = false);
+
+CREATE INDEX workspaces_last_used_at_idx ON workspaces USING btree (last_used_at) WHERE (deleted = false);
+
+CREATE INDEX workspaces_lower_name_idx ON workspaces USING btree (lower((name)::text)) WHERE (deleted = false);
+
 CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
 
 CREATE OR REPLACE VIEW provisioner_job_stats AS
@@ -5230,6 +6402,15 @@ ALTER TABLE ONLY group_members
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY idempotency_keys
+    ADD CONSTRAINT idempotency_keys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY idempotency_keys
+    ADD CONSTRAINT idempotency_keys_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY idempotency_keys
+    ADD CONSTRAINT idempotency_keys_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY inbox_notifications
     ADD CONSTRAINT inbox_notifications_template_id_fkey FOREIGN KEY (template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
 
@@ -5281,6 +6462,15 @@ ALTER TABLE ONLY notification_preferences
 ALTER TABLE ONLY notification_preferences
     ADD CONSTRAINT notification_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY notification_scoped_preferences
+    ADD CONSTRAINT notification_scoped_preferences_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY notification_scoped_preferences
+    ADD CONSTRAINT notification_scoped_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY notification_template_webhooks
+    ADD CONSTRAINT notification_template_webhooks_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY oauth2_provider_app_codes
     ADD CONSTRAINT oauth2_provider_app_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
 
@@ -5305,12 +6495,21 @@ ALTER TABLE ONLY organization_members
 ALTER TABLE ONLY parameter_schemas
     ADD CONSTRAINT parameter_schemas_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY prebuild_claim_attempts
+    ADD CONSTRAINT prebuild_claim_attempts_preset_id_fkey FOREIGN KEY (preset_id) REFERENCES template_version_presets(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY prebuild_claim_attempts
+    ADD CONSTRAINT prebuild_claim_attempts_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY provisioner_daemons
     ADD CONSTRAINT provisioner_daemons_key_id_fkey FOREIGN KEY (key_id) REFERENCES provisioner_keys(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY provisioner_daemons
     ADD CONSTRAINT provisioner_daemons_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_job_log_archives
+    ADD CONSTRAINT provisioner_job_log_archives_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
@@ -5323,6 +6522,27 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY shared_volume_attachments
+    ADD CONSTRAINT shared_volume_attachments_detached_by_fkey FOREIGN KEY (detached_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY shared_volume_attachments
+    ADD CONSTRAINT shared_volume_attachments_shared_volume_id_fkey FOREIGN KEY (shared_volume_id) REFERENCES shared_volumes(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY shared_volume_attachments
+    ADD CONSTRAINT shared_volume_attachments_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY shared_volume_attachments
+    ADD CONSTRAINT shared_volume_attachments_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY shared_volume_attachments
+    ADD CONSTRAINT shared_volume_attachments_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY shared_volumes
+    ADD CONSTRAINT shared_volumes_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY shared_volumes
+    ADD CONSTRAINT shared_volumes_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY tailnet_peers
     ADD CONSTRAINT tailnet_peers_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
@@ -5353,6 +6573,27 @@ ALTER TABLE ONLY tasks
 ALTER TABLE ONLY tasks
     ADD CONSTRAINT tasks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_health_reports
+    ADD CONSTRAINT template_health_reports_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_network_policies
+    ADD CONSTRAINT template_network_policies_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_preset_cohorts
+    ADD CONSTRAINT template_preset_cohorts_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_secrets
+    ADD CONSTRAINT template_secrets_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_secrets
+    ADD CONSTRAINT template_secrets_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
+ALTER TABLE ONLY template_user_presets
+    ADD CONSTRAINT template_user_presets_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_user_presets
+    ADD CONSTRAINT template_user_presets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -5365,6 +6606,24 @@ ALTER TABLE ONLY template_version_preset_prebuild_schedules
 ALTER TABLE ONLY template_version_presets
     ADD CONSTRAINT template_version_presets_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_previous_version_id_fkey FOREIGN KEY (previous_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_scans
+    ADD CONSTRAINT template_version_scans_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_terraform_values
     ADD CONSTRAINT template_version_terraform_values_cached_module_files_fkey FOREIGN KEY (cached_module_files) REFERENCES files(id);
 
@@ -5386,6 +6645,24 @@ ALTER TABLE ONLY template_versions
 ALTER TABLE ONLY template_versions
     ADD CONSTRAINT template_versions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_workspace_restart_builds
+    ADD CONSTRAINT template_workspace_restart_builds_restart_id_fkey FOREIGN KEY (restart_id) REFERENCES template_workspace_restarts(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_workspace_restart_builds
+    ADD CONSTRAINT template_workspace_restart_builds_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_workspace_restart_builds
+    ADD CONSTRAINT template_workspace_restart_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_workspace_restarts
+    ADD CONSTRAINT template_workspace_restarts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE ONLY template_workspace_restarts
+    ADD CONSTRAINT template_workspace_restarts_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_workspace_restarts
+    ADD CONSTRAINT template_workspace_restarts_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
 
@@ -5413,6 +6690,15 @@ ALTER TABLE ONLY user_configs
 ALTER TABLE ONLY user_deleted
     ADD CONSTRAINT user_deleted_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
+ALTER TABLE ONLY user_impersonations
+    ADD CONSTRAINT user_impersonations_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY user_impersonations
+    ADD CONSTRAINT user_impersonations_impersonator_id_fkey FOREIGN KEY (impersonator_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_impersonations
+    ADD CONSTRAINT user_impersonations_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_oauth_access_token_key_id_fkey FOREIGN KEY (oauth_access_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 
@@ -5422,6 +6708,9 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_schedule_pauses
+    ADD CONSTRAINT user_schedule_pauses_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY user_secrets
     ADD CONSTRAINT user_secrets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
@@ -5434,15 +6723,39 @@ ALTER TABLE ONLY user_skills
 ALTER TABLE ONLY user_status_changes
     ADD CONSTRAINT user_status_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
+ALTER TABLE ONLY user_workspace_reports
+    ADD CONSTRAINT user_workspace_reports_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY vcs_event_mappings
+    ADD CONSTRAINT vcs_event_mappings_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE ONLY vcs_event_mappings
+    ADD CONSTRAINT vcs_event_mappings_signing_secret_key_id_fkey FOREIGN KEY (signing_secret_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
+ALTER TABLE ONLY vcs_event_mappings
+    ADD CONSTRAINT vcs_event_mappings_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY vcs_event_mappings
+    ADD CONSTRAINT vcs_event_mappings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_activity_bumps
+    ADD CONSTRAINT workspace_activity_bumps_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_bootstrap_progress
+    ADD CONSTRAINT workspace_agent_bootstrap_progress_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_context_resources
     ADD CONSTRAINT workspace_agent_context_resources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_agent_context_snapshots
     ADD CONSTRAINT workspace_agent_context_snapshots_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_crash_loops
+    ADD CONSTRAINT workspace_agent_crash_loops_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_devcontainers
     ADD CONSTRAINT workspace_agent_devcontainers_subagent_id_fkey FOREIGN KEY (subagent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -5452,12 +6765,21 @@ ALTER TABLE ONLY workspace_agent_devcontainers
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_memory_events
+    ADD CONSTRAINT workspace_agent_memory_events_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_memory_resource_monitors
     ADD CONSTRAINT workspace_agent_memory_resource_monitors_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_metadata_history
+    ADD CONSTRAINT workspace_agent_metadata_history_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_network_policy_violations
+    ADD CONSTRAINT workspace_agent_network_policy_violations_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
@@ -5467,6 +6789,12 @@ ALTER TABLE ONLY workspace_agent_script_timings
 ALTER TABLE ONLY workspace_agent_scripts
     ADD CONSTRAINT workspace_agent_scripts_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_service_transitions
+    ADD CONSTRAINT workspace_agent_service_transitions_service_fkey FOREIGN KEY (workspace_agent_id, service_name) REFERENCES workspace_agent_services(workspace_agent_id, name) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_services
+    ADD CONSTRAINT workspace_agent_services_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -5482,6 +6810,12 @@ ALTER TABLE ONLY workspace_agents
 ALTER TABLE ONLY workspace_app_audit_sessions
     ADD CONSTRAINT workspace_app_audit_sessions_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_app_share_links
+    ADD CONSTRAINT workspace_app_share_links_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_app_share_links
+    ADD CONSTRAINT workspace_app_share_links_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_app_stats
     ADD CONSTRAINT workspace_app_stats_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id);
 
@@ -5503,6 +6837,15 @@ ALTER TABLE ONLY workspace_app_statuses
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_build_annotations
+    ADD CONSTRAINT workspace_build_annotations_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_annotations
+    ADD CONSTRAINT workspace_build_annotations_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_inputs
+    ADD CONSTRAINT workspace_build_inputs_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_build_orchestrations
     ADD CONSTRAINT workspace_build_orchestrations_child_build_workspace_id_fkey FOREIGN KEY (child_build_id, workspace_id) REFERENCES workspace_builds(id, workspace_id) ON DELETE CASCADE;
 
@@ -5518,6 +6861,12 @@ ALTER TABLE ONLY workspace_build_orchestrations
 ALTER TABLE ONLY workspace_build_orchestrations
     ADD CONSTRAINT workspace_build_orchestrations_parent_build_workspace_id_fkey FOREIGN KEY (parent_build_id, workspace_id) REFERENCES workspace_builds(id, workspace_id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_build_parameter_changes
+    ADD CONSTRAINT workspace_build_parameter_changes_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_parameters
+    ADD CONSTRAINT workspace_build_parameters_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
 
@@ -5533,9 +6882,66 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_cloud_resources
+    ADD CONSTRAINT workspace_cloud_resources_verification_build_id_fkey FOREIGN KEY (verification_build_id) REFERENCES workspace_builds(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY workspace_cloud_resources
+    ADD CONSTRAINT workspace_cloud_resources_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_cloud_resources
+    ADD CONSTRAINT workspace_cloud_resources_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_dormancy_exemptions
+    ADD CONSTRAINT workspace_dormancy_exemptions_approved_by_fkey FOREIGN KEY (approved_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_dns_records
+    ADD CONSTRAINT workspace_dns_records_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_dormancy_exemptions
+    ADD CONSTRAINT workspace_dormancy_exemptions_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_dormancy_hooks
+    ADD CONSTRAINT workspace_dormancy_hooks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_egress_daily
+    ADD CONSTRAINT workspace_egress_daily_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_egress_daily
+    ADD CONSTRAINT workspace_egress_daily_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_egress_daily
+    ADD CONSTRAINT workspace_egress_daily_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_leases
+    ADD CONSTRAINT workspace_leases_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_leases
+    ADD CONSTRAINT workspace_leases_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_leases
+    ADD CONSTRAINT workspace_leases_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_lifetime_warnings
+    ADD CONSTRAINT workspace_lifetime_warnings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_modules
     ADD CONSTRAINT workspace_modules_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_monthly_costs
+    ADD CONSTRAINT workspace_monthly_costs_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_network_policies
+    ADD CONSTRAINT workspace_network_policies_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_port_forward_acls
+    ADD CONSTRAINT workspace_port_forward_acls_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_port_forward_acls
+    ADD CONSTRAINT workspace_port_forward_acls_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_region_assignments
+    ADD CONSTRAINT workspace_region_assignments_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
@@ -0,0 +1,4 @@
+ALTER TABLE api_keys
+    ADD COLUMN template_ids uuid[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.template_ids IS 'template_ids restricts workspace operations performed with the key to workspaces built from these templates. An empty array means the key is not restricted to any template.';
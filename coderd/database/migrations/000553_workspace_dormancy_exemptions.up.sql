@@ -0,0 +1,14 @@
+CREATE TABLE workspace_dormancy_exemptions (
+    workspace_id UUID PRIMARY KEY NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    approved_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+COMMENT ON TABLE workspace_dormancy_exemptions IS
+    'Exempts a workspace from being marked dormant by the template time_til_dormant policy until expires_at.';
+
+COMMENT ON COLUMN workspace_dormancy_exemptions.approved_by IS
+    'The template administrator that granted the exemption.';
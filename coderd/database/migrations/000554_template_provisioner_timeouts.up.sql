@@ -0,0 +1,28 @@
+ALTER TABLE templates
+	ADD COLUMN provisioner_plan_timeout bigint DEFAULT 0 NOT NULL,
+	ADD COLUMN provisioner_apply_timeout bigint DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN templates.provisioner_plan_timeout IS 'Maximum duration of template version import and dry-run jobs, in nanoseconds. 0 means the jobs are not subject to a timeout.';
+
+COMMENT ON COLUMN templates.provisioner_apply_timeout IS 'Maximum duration of workspace build jobs, in nanoseconds. 0 means the jobs are not subject to a timeout.';
+
+ALTER TABLE provisioner_jobs
+	ADD COLUMN timeout bigint DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN provisioner_jobs.timeout IS 'Maximum duration of the job in nanoseconds, taken from its template when the job is acquired. 0 means the job is not subject to a timeout.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
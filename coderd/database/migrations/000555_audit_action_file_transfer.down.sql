@@ -0,0 +1 @@
+-- No-op, enum values can't be dropped.
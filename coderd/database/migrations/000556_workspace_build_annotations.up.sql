@@ -0,0 +1,12 @@
+CREATE TABLE workspace_build_annotations (
+    id UUID PRIMARY KEY NOT NULL,
+    workspace_build_id UUID NOT NULL REFERENCES workspace_builds(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX workspace_build_annotations_workspace_build_id_idx ON workspace_build_annotations (workspace_build_id);
+
+COMMENT ON TABLE workspace_build_annotations IS
+    'Free-form notes attached to a workspace build by users, e.g. why the build was run.';
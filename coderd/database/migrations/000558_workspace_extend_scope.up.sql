@@ -0,0 +1,2 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'workspace:extend';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'workspace_dormant:extend';
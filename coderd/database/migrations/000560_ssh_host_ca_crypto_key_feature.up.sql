@@ -0,0 +1 @@
+ALTER TYPE crypto_key_feature ADD VALUE IF NOT EXISTS 'ssh_host_ca';
@@ -0,0 +1,18 @@
+CREATE TABLE workspace_egress_daily (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    template_id UUID NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    rx_bytes_ssh BIGINT NOT NULL DEFAULT 0,
+    tx_bytes_ssh BIGINT NOT NULL DEFAULT 0,
+    rx_bytes_reconnecting_pty BIGINT NOT NULL DEFAULT 0,
+    tx_bytes_reconnecting_pty BIGINT NOT NULL DEFAULT 0,
+    rx_bytes_port_forward BIGINT NOT NULL DEFAULT 0,
+    tx_bytes_port_forward BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (workspace_id, date)
+);
+
+CREATE INDEX workspace_egress_daily_date_idx ON workspace_egress_daily (date);
+
+COMMENT ON TABLE workspace_egress_daily IS
+    'Bytes received and transmitted by workspace agents, aggregated per workspace and UTC day and split by connection class. Used for network chargeback.';
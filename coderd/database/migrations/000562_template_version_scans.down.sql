@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS template_version_scans;
+
+DROP TYPE IF EXISTS template_version_scan_status;
@@ -0,0 +1,20 @@
+CREATE TYPE template_version_scan_status AS ENUM (
+    'running',
+    'completed',
+    'failed'
+);
+
+CREATE TABLE template_version_scans (
+    template_version_id UUID NOT NULL PRIMARY KEY REFERENCES template_versions(id) ON DELETE CASCADE,
+    status template_version_scan_status NOT NULL,
+    findings JSONB NOT NULL DEFAULT '[]'::jsonb,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMENT ON TABLE template_version_scans IS
+    'Results of submitting template version files and plan output to the configured template scanner.';
+
+COMMENT ON COLUMN template_version_scans.findings IS
+    'Findings reported by the scanner, as a JSON array of objects with severity, rule_id, message and path.';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workspace_build_parameter_changes;
+
+DROP TYPE IF EXISTS workspace_build_parameter_change_kind;
@@ -0,0 +1,21 @@
+CREATE TYPE workspace_build_parameter_change_kind AS ENUM (
+    'added',
+    'removed',
+    'modified'
+);
+
+CREATE TABLE workspace_build_parameter_changes (
+    workspace_build_id UUID NOT NULL REFERENCES workspace_builds(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    kind workspace_build_parameter_change_kind NOT NULL,
+    previous_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT '',
+    redacted BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (workspace_build_id, name)
+);
+
+COMMENT ON TABLE workspace_build_parameter_changes IS
+    'Rich parameter values that differ between a workspace build and the previous build of the same workspace.';
+
+COMMENT ON COLUMN workspace_build_parameter_changes.redacted IS
+    'Whether the values were withheld because the parameter is ephemeral. previous_value and new_value are empty when set.';
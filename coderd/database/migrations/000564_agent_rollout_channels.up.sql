@@ -0,0 +1,25 @@
+CREATE TYPE agent_rollout_channel AS ENUM (
+	'stable',
+	'beta'
+);
+
+ALTER TABLE templates
+	ADD COLUMN agent_rollout_channel agent_rollout_channel DEFAULT 'stable'::agent_rollout_channel NOT NULL;
+
+COMMENT ON COLUMN templates.agent_rollout_channel IS 'The channel workspace agents of this template download their binary from on start.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
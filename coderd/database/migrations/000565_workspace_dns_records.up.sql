@@ -0,0 +1,13 @@
+CREATE TABLE workspace_dns_records (
+    workspace_id UUID NOT NULL PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    target TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE workspace_dns_records IS
+    'DNS names registered with the configured workspace DNS provider for running workspaces.';
+
+COMMENT ON COLUMN workspace_dns_records.target IS
+    'The host the DNS name was pointed at when it was registered.';
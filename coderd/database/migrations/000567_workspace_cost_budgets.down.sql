@@ -0,0 +1,5 @@
+DELETE FROM notification_templates WHERE id = 'b9fa160a-a261-4135-8f68-fb60fc019457';
+
+DROP TABLE IF EXISTS workspace_monthly_costs;
+
+DROP TYPE IF EXISTS workspace_cost_source;
@@ -0,0 +1,42 @@
+-- It's not possible to delete enum values.
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'budget_exceeded';
+
+CREATE TYPE workspace_cost_source AS ENUM (
+    'estimate',
+    'billing_export'
+);
+
+CREATE TABLE workspace_monthly_costs (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    month TIMESTAMP WITH TIME ZONE NOT NULL,
+    cost DOUBLE PRECISION DEFAULT 0 NOT NULL,
+    source workspace_cost_source DEFAULT 'estimate'::workspace_cost_source NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (workspace_id, month)
+);
+
+COMMENT ON TABLE workspace_monthly_costs IS
+    'Cost counters of workspaces per calendar month, used to enforce monthly cost budgets.';
+
+COMMENT ON COLUMN workspace_monthly_costs.month IS
+    'The start of the UTC calendar month the cost was accrued in.';
+
+COMMENT ON COLUMN workspace_monthly_costs.cost IS
+    'The cost accrued in the month, in the same units as the daily cost of workspace resources.';
+
+COMMENT ON COLUMN workspace_monthly_costs.source IS
+    'Estimated counters accrue the daily cost of the running build. Counters imported from a billing export replace the estimate and are not accrued.';
+
+INSERT INTO notification_templates (
+    id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+) VALUES (
+    'b9fa160a-a261-4135-8f68-fb60fc019457',
+    'Workspace Budget Exceeded',
+    E'Your workspace "{{.Labels.workspace}}" was stopped',
+    E'Your workspace **{{.Labels.workspace}}** was stopped because the {{.Labels.budget_kind}} monthly cost budget of {{.Labels.budget}} was exceeded (cost this month: {{.Labels.cost}}).\n\nIt will be stopped again if started before the budget resets at the start of next month.',
+    '[{"label": "View workspace", "url": "{{base_url}}/@{{.UserUsername}}/{{.Labels.workspace}}"}]'::jsonb,
+    'Workspace Events',
+    NULL,
+    'system'::notification_template_kind,
+    true
+);
@@ -0,0 +1,13 @@
+CREATE TABLE workspace_agent_metadata_history (
+    workspace_agent_id UUID NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+    key VARCHAR(127) NOT NULL,
+    value VARCHAR(65535) NOT NULL DEFAULT '',
+    error VARCHAR(65535) NOT NULL DEFAULT '',
+    collected_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (workspace_agent_id, key, collected_at)
+);
+
+CREATE INDEX workspace_agent_metadata_history_collected_at_idx ON workspace_agent_metadata_history (collected_at);
+
+COMMENT ON TABLE workspace_agent_metadata_history IS
+    'Historical values of workspace agent metadata. Only recorded when a metadata history retention is configured, and purged after it.';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS workspace_dormancy_hooks;
+
+DROP TYPE IF EXISTS workspace_dormancy_hook_status;
+
+DROP TYPE IF EXISTS workspace_dormancy_hook_event;
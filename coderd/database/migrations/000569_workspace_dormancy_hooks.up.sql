@@ -0,0 +1,31 @@
+CREATE TYPE workspace_dormancy_hook_event AS ENUM (
+    'dormant',
+    'activate'
+);
+
+CREATE TYPE workspace_dormancy_hook_status AS ENUM (
+    'pending',
+    'running',
+    'succeeded',
+    'failed'
+);
+
+CREATE TABLE workspace_dormancy_hooks (
+    workspace_id UUID NOT NULL PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    event workspace_dormancy_hook_event NOT NULL,
+    status workspace_dormancy_hook_status DEFAULT 'pending'::workspace_dormancy_hook_status NOT NULL,
+    error TEXT DEFAULT ''::text NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX workspace_dormancy_hooks_status_idx ON workspace_dormancy_hooks USING btree (status, updated_at) WHERE (status = ANY (ARRAY['pending'::workspace_dormancy_hook_status, 'running'::workspace_dormancy_hook_status]));
+
+COMMENT ON TABLE workspace_dormancy_hooks IS
+    'The latest dormancy lifecycle hook invocation of a workspace. Hooks move the storage of dormant workspaces to cheaper tiers and restore it on activation.';
+
+COMMENT ON COLUMN workspace_dormancy_hooks.event IS
+    'The lifecycle event the hook is invoked for. An activate hook must succeed before a dormant workspace can be started.';
+
+COMMENT ON COLUMN workspace_dormancy_hooks.error IS
+    'The error returned by the last failed invocation of the hook.';
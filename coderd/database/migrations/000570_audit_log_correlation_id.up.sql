@@ -0,0 +1,6 @@
+ALTER TABLE audit_logs ADD COLUMN correlation_id UUID;
+
+COMMENT ON COLUMN audit_logs.correlation_id IS
+    'The workspace build the entry is related to. All audit entries caused by one build share this ID.';
+
+CREATE INDEX idx_audit_logs_correlation_id ON audit_logs USING btree (correlation_id) WHERE (correlation_id IS NOT NULL);
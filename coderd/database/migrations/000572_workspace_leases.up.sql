@@ -0,0 +1,24 @@
+CREATE TABLE workspace_leases (
+    id UUID NOT NULL PRIMARY KEY,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    template_id UUID NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    api_key_id TEXT NOT NULL,
+    credential_key_id TEXT DEFAULT ''::text NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    released_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX workspace_leases_api_key_id_idx ON workspace_leases USING btree (api_key_id) WHERE (released_at IS NULL);
+
+CREATE INDEX workspace_leases_expires_at_idx ON workspace_leases USING btree (expires_at) WHERE (released_at IS NULL);
+
+COMMENT ON TABLE workspace_leases IS
+    'Time-bound leases on ephemeral workspaces, typically created by CI jobs. A workspace is deleted once its lease expires or is released.';
+
+COMMENT ON COLUMN workspace_leases.api_key_id IS
+    'The API key that created the lease. Concurrency caps are enforced per key.';
+
+COMMENT ON COLUMN workspace_leases.credential_key_id IS
+    'The API key minted for the lease holder. It is deleted when the lease is released.';
@@ -0,0 +1,62 @@
+ALTER TABLE templates
+	ADD COLUMN deprecation_cutoff timestamp with time zone;
+
+COMMENT ON COLUMN templates.deprecation_cutoff IS 'If set, the deprecated template may still be used to create workspaces until this time. After it, creation is blocked.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+ALTER TABLE template_versions
+	ADD COLUMN deprecated text DEFAULT ''::text NOT NULL,
+	ADD COLUMN deprecation_cutoff timestamp with time zone;
+
+COMMENT ON COLUMN template_versions.deprecated IS 'If set to a non empty string, the template version is deprecated. The message is shown to users building workspaces with it.';
+
+COMMENT ON COLUMN template_versions.deprecation_cutoff IS 'If set, the deprecated template version may still be used for builds until this time. After it, new builds with it are blocked.';
+
+DROP VIEW template_version_with_user;
+
+CREATE VIEW template_version_with_user AS
+SELECT
+    template_versions.id,
+    template_versions.template_id,
+    template_versions.organization_id,
+    template_versions.created_at,
+    template_versions.updated_at,
+    template_versions.name,
+    template_versions.readme,
+    template_versions.job_id,
+    template_versions.created_by,
+    template_versions.external_auth_providers,
+    template_versions.message,
+    template_versions.archived,
+    template_versions.source_example_id,
+    template_versions.has_ai_task,
+    template_versions.has_external_agent,
+    template_versions.deprecated,
+    template_versions.deprecation_cutoff,
+    COALESCE(visible_users.avatar_url, '' :: text) AS created_by_avatar_url,
+    COALESCE(visible_users.username, '' :: text) AS created_by_username,
+    COALESCE(visible_users.name, '' :: text) AS created_by_name
+FROM
+    (
+        template_versions
+        LEFT JOIN visible_users ON (
+            (template_versions.created_by = visible_users.id)
+        )
+    );
+
+COMMENT ON VIEW template_version_with_user IS 'Joins in the username + avatar url of the created by user.';
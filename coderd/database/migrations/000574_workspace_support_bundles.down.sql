@@ -0,0 +1 @@
+DELETE FROM notification_templates WHERE id = '5e2fb2a8-5b43-4d2c-b8f5-0a6c3f3d1b7e';
@@ -0,0 +1,13 @@
+INSERT INTO notification_templates (
+    id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+) VALUES (
+    '5e2fb2a8-5b43-4d2c-b8f5-0a6c3f3d1b7e',
+    'Workspace Support Bundle Created',
+    E'Support bundle collected for workspace "{{.Labels.workspace}}"',
+    E'**{{.Labels.initiator}}** collected a support bundle for the workspace **{{.Labels.workspace}}** owned by **{{.Labels.workspace_owner_username}}**.{{if .Labels.message}}\n\n{{.Labels.message}}{{end}}',
+    '[{"label": "Download bundle", "url": "{{base_url}}/api/v2/files/{{.Labels.file_id}}"}, {"label": "View workspace", "url": "{{base_url}}/@{{.Labels.workspace_owner_username}}/{{.Labels.workspace}}"}]'::jsonb,
+    'Workspace Events',
+    NULL,
+    'system'::notification_template_kind,
+    true
+);
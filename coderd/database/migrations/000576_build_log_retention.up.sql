@@ -0,0 +1,32 @@
+ALTER TABLE templates
+	ADD COLUMN build_log_retention bigint DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN templates.build_log_retention IS 'How long provisioner job logs of this template are kept before they are archived or deleted, in nanoseconds. 0 uses the deployment-wide retention.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+CREATE TABLE provisioner_job_log_archives (
+    job_id UUID NOT NULL PRIMARY KEY REFERENCES provisioner_jobs(id) ON DELETE CASCADE,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    object_key TEXT DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_log_archives IS
+    'Provisioner jobs whose logs were removed from the database by the build log retention policy.';
+
+COMMENT ON COLUMN provisioner_job_log_archives.object_key IS
+    'Key of the archived logs in the build log archive. Empty if the logs were deleted without being archived.';
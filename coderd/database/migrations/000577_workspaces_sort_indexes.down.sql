@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS workspaces_lower_name_idx;
+DROP INDEX IF EXISTS workspaces_last_used_at_idx;
+DROP INDEX IF EXISTS workspaces_created_at_idx;
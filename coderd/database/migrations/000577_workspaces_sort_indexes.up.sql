@@ -0,0 +1,7 @@
+-- Support the sort: term of the workspaces filter query. The sort is passed
+-- as a bound parameter, so the planner folds the CASE expressions in the
+-- ORDER BY of GetWorkspaces down to the selected key, which these indexes
+-- can then return in order.
+CREATE INDEX IF NOT EXISTS workspaces_created_at_idx ON workspaces USING btree (created_at) WHERE (deleted = false);
+CREATE INDEX IF NOT EXISTS workspaces_last_used_at_idx ON workspaces USING btree (last_used_at) WHERE (deleted = false);
+CREATE INDEX IF NOT EXISTS workspaces_lower_name_idx ON workspaces USING btree (lower((name)::text)) WHERE (deleted = false);
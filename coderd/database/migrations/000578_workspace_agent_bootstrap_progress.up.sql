@@ -0,0 +1,16 @@
+CREATE TABLE workspace_agent_bootstrap_progress (
+    id UUID PRIMARY KEY,
+    workspace_agent_id UUID NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    name TEXT NOT NULL,
+    percent INTEGER CHECK (percent >= 0 AND percent <= 100),
+    message TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX workspace_agent_bootstrap_progress_workspace_agent_id_idx ON workspace_agent_bootstrap_progress (workspace_agent_id, created_at);
+
+COMMENT ON TABLE workspace_agent_bootstrap_progress IS
+    'Named progress milestones reported by the startup scripts of a workspace agent. The most recent milestone is the current one.';
+
+COMMENT ON COLUMN workspace_agent_bootstrap_progress.percent IS
+    'Completion of the milestone in percent, if the script reported it.';
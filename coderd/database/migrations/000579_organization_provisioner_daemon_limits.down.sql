@@ -0,0 +1,3 @@
+ALTER TABLE organizations
+	DROP COLUMN provisioner_daemon_quota,
+	DROP COLUMN provisioner_tag_namespace;
@@ -0,0 +1,7 @@
+ALTER TABLE organizations
+	ADD COLUMN provisioner_tag_namespace text DEFAULT ''::text NOT NULL,
+	ADD COLUMN provisioner_daemon_quota integer DEFAULT 0 NOT NULL CHECK (provisioner_daemon_quota >= 0);
+
+COMMENT ON COLUMN organizations.provisioner_tag_namespace IS 'When set, provisioner daemons registered in this organization may only use tag keys prefixed with "<namespace>/", apart from the reserved scope and owner tags.';
+
+COMMENT ON COLUMN organizations.provisioner_daemon_quota IS 'Maximum number of provisioner daemons that may be connected to this organization at once. Zero means unlimited.';
@@ -0,0 +1,3 @@
+ALTER TABLE workspace_apps
+	DROP COLUMN session_idle_timeout_seconds,
+	DROP COLUMN max_session_duration_seconds;
@@ -0,0 +1,7 @@
+ALTER TABLE workspace_apps
+	ADD COLUMN max_session_duration_seconds integer DEFAULT 0 NOT NULL,
+	ADD COLUMN session_idle_timeout_seconds integer DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN workspace_apps.max_session_duration_seconds IS 'Maximum lifetime of a session used to access the app, in seconds. Zero means unlimited.';
+
+COMMENT ON COLUMN workspace_apps.session_idle_timeout_seconds IS 'Duration of inactivity after which a session used to access the app expires, in seconds. Zero means unlimited.';
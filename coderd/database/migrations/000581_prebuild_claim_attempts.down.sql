@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS prebuild_claim_attempts;
+
+DROP TYPE IF EXISTS prebuild_claim_miss_reason;
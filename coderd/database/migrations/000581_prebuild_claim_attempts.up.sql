@@ -0,0 +1,24 @@
+CREATE TYPE prebuild_claim_miss_reason AS ENUM (
+	'reconciliation_lag',
+	'capacity',
+	'failures'
+);
+
+COMMENT ON TYPE prebuild_claim_miss_reason IS 'Why no prebuilt workspace could be claimed: reconciliation_lag means prebuilt workspaces were still being provisioned, capacity means the pool was drained by claims, and failures means prebuilt workspaces of the preset failed to build.';
+
+CREATE TABLE prebuild_claim_attempts (
+	id uuid NOT NULL PRIMARY KEY,
+	preset_id uuid NOT NULL REFERENCES template_version_presets(id) ON DELETE CASCADE,
+	workspace_id uuid REFERENCES workspaces(id) ON DELETE CASCADE,
+	claimed boolean NOT NULL,
+	miss_reason prebuild_claim_miss_reason,
+	created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE prebuild_claim_attempts IS 'Attempts to claim a prebuilt workspace when a workspace is created with a preset that has prebuilds configured.';
+
+COMMENT ON COLUMN prebuild_claim_attempts.workspace_id IS 'The claimed prebuilt workspace. NULL if no prebuilt workspace could be claimed.';
+
+COMMENT ON COLUMN prebuild_claim_attempts.miss_reason IS 'Why no prebuilt workspace could be claimed. NULL if the claim succeeded.';
+
+CREATE INDEX idx_prebuild_claim_attempts_created_at ON prebuild_claim_attempts USING btree (created_at);
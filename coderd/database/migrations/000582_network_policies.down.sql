@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workspace_agent_network_policy_violations;
+DROP TABLE IF EXISTS workspace_network_policies;
+DROP TABLE IF EXISTS template_network_policies;
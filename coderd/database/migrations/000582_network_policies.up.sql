@@ -0,0 +1,34 @@
+CREATE TABLE template_network_policies (
+	template_id uuid NOT NULL PRIMARY KEY REFERENCES templates(id) ON DELETE CASCADE,
+	allowed_domains text[] NOT NULL DEFAULT '{}'::text[],
+	allowed_cidrs text[] NOT NULL DEFAULT '{}'::text[],
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_network_policies IS 'Default outbound network policy declared for the workspaces of a template. Enforcement (CNI plugins, egress proxies) happens outside of Coder.';
+
+CREATE TABLE workspace_network_policies (
+	workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+	allowed_domains text[] NOT NULL DEFAULT '{}'::text[],
+	allowed_cidrs text[] NOT NULL DEFAULT '{}'::text[],
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_network_policies IS 'Outbound network policy declared for a single workspace. Replaces the policy of the template of the workspace.';
+
+CREATE TABLE workspace_agent_network_policy_violations (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	destination text NOT NULL,
+	port integer NOT NULL CHECK (port >= 0 AND port <= 65535),
+	count integer NOT NULL CHECK (count > 0)
+);
+
+COMMENT ON TABLE workspace_agent_network_policy_violations IS 'Outbound connections reported by a workspace agent that were not allowed by the network policy of its workspace.';
+
+COMMENT ON COLUMN workspace_agent_network_policy_violations.destination IS 'The domain or IP address the workspace tried to connect to.';
+
+COMMENT ON COLUMN workspace_agent_network_policy_violations.count IS 'Number of blocked connections to the destination since the previous report.';
+
+CREATE INDEX workspace_agent_network_policy_violations_workspace_agent_id_idx ON workspace_agent_network_policy_violations USING btree (workspace_agent_id, created_at);
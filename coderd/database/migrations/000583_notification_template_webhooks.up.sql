@@ -0,0 +1,15 @@
+CREATE TABLE notification_template_webhooks (
+	notification_template_id uuid NOT NULL PRIMARY KEY REFERENCES notification_templates(id) ON DELETE CASCADE,
+	endpoint text NOT NULL DEFAULT '',
+	payload_template text NOT NULL DEFAULT '',
+	signing_secret text NOT NULL DEFAULT '',
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE notification_template_webhooks IS 'Overrides for the webhook delivery of the messages of a notification template.';
+
+COMMENT ON COLUMN notification_template_webhooks.endpoint IS 'Empty defers to the deployment-level webhook endpoint.';
+
+COMMENT ON COLUMN notification_template_webhooks.payload_template IS 'Go template rendering the request body. Empty sends the default JSON payload.';
+
+COMMENT ON COLUMN notification_template_webhooks.signing_secret IS 'Secret used to sign request bodies with HMAC-SHA256. Empty disables signing.';
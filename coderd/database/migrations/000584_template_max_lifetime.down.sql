@@ -0,0 +1,25 @@
+DELETE FROM notification_templates WHERE id = '939d8a0f-98b3-44f7-8c6a-3bf2b273f814';
+
+DROP TABLE IF EXISTS workspace_lifetime_warnings;
+
+DROP VIEW template_with_names;
+
+ALTER TABLE templates
+	DROP COLUMN max_lifetime,
+	DROP COLUMN max_lifetime_action;
+
+DROP TYPE IF EXISTS max_lifetime_action;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
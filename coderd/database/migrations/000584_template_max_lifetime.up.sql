@@ -0,0 +1,55 @@
+CREATE TYPE max_lifetime_action AS ENUM (
+    'delete',
+    'stop'
+);
+
+ALTER TABLE templates
+	ADD COLUMN max_lifetime bigint DEFAULT 0 NOT NULL,
+	ADD COLUMN max_lifetime_action max_lifetime_action DEFAULT 'delete'::max_lifetime_action NOT NULL;
+
+COMMENT ON COLUMN templates.max_lifetime IS 'Maximum lifetime of workspaces created from this template, measured from their creation, in nanoseconds. Workspaces past it are stopped or deleted regardless of activity. 0 disables the limit.';
+
+COMMENT ON COLUMN templates.max_lifetime_action IS 'What happens to workspaces that exceed the max lifetime of the template.';
+
+CREATE TABLE workspace_lifetime_warnings (
+    workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    expires_at timestamp with time zone NOT NULL,
+    days_before integer NOT NULL,
+    sent_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_lifetime_warnings IS 'The most recent warning sent to the owner of a workspace approaching the max lifetime of its template.';
+
+COMMENT ON COLUMN workspace_lifetime_warnings.expires_at IS 'The expiry the warning was sent for. A change to the template max lifetime moves the expiry and re-arms the warnings.';
+
+COMMENT ON COLUMN workspace_lifetime_warnings.days_before IS 'How many days before the expiry the warning was sent for.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+INSERT INTO notification_templates (
+    id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+) VALUES (
+    '939d8a0f-98b3-44f7-8c6a-3bf2b273f814',
+    'Workspace Expiring',
+    E'Your workspace "{{.Labels.workspace}}" will be {{.Labels.action}} {{.Labels.timeTilExpiry}}',
+    E'Your workspace **{{.Labels.workspace}}** will be {{.Labels.action}} {{.Labels.timeTilExpiry}} because it reaches the maximum lifetime set by its template.\n\nActivity does not extend this deadline. Save your work before then.',
+    '[{"label": "View workspace", "url": "{{base_url}}/@{{.UserUsername}}/{{.Labels.workspace}}"}]'::jsonb,
+    'Workspace Events',
+    NULL,
+    'system'::notification_template_kind,
+    true
+);
@@ -0,0 +1,3 @@
+DELETE FROM notification_templates WHERE id = 'e9a80589-5ad5-415d-816d-d6943f27938d';
+
+DROP TABLE IF EXISTS workspace_agent_crash_loops;
@@ -0,0 +1,32 @@
+CREATE TABLE workspace_agent_crash_loops (
+    agent_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_agents(id) ON DELETE CASCADE,
+    short_connections integer NOT NULL,
+    last_disconnected_at timestamp with time zone NOT NULL,
+    last_disconnect_reason text DEFAULT ''::text NOT NULL,
+    last_lifecycle_state workspace_agent_lifecycle_state NOT NULL,
+    crash_looping_since timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_agent_crash_loops IS 'Tracks agents that repeatedly disconnect shortly after connecting. The row is removed once the agent stays connected.';
+
+COMMENT ON COLUMN workspace_agent_crash_loops.short_connections IS 'The number of consecutive connections of the agent that ended before becoming stable.';
+
+COMMENT ON COLUMN workspace_agent_crash_loops.last_disconnect_reason IS 'Why the last connection of the agent ended.';
+
+COMMENT ON COLUMN workspace_agent_crash_loops.last_lifecycle_state IS 'The lifecycle state of the agent when its last connection ended.';
+
+COMMENT ON COLUMN workspace_agent_crash_loops.crash_looping_since IS 'When the agent was detected to be crash looping. Reconnects are backed off while set.';
+
+INSERT INTO notification_templates (
+    id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+) VALUES (
+    'e9a80589-5ad5-415d-816d-d6943f27938d',
+    'Workspace Agent Crash Looping',
+    E'Agent "{{.Labels.agent}}" of workspace "{{.Labels.workspace}}" is crash looping',
+    E'The agent **{{.Labels.agent}}** of your workspace **{{.Labels.workspace}}** disconnected {{.Labels.connections}} times in a row shortly after connecting.\n\nLast exit: {{.Labels.last_exit}}.\n\nReconnects are slowed down until the agent stays connected. Check the agent logs and startup scripts.',
+    '[{"label": "View workspace", "url": "{{base_url}}/@{{.UserUsername}}/{{.Labels.workspace}}"}]'::jsonb,
+    'Workspace Events',
+    NULL,
+    'system'::notification_template_kind,
+    true
+);
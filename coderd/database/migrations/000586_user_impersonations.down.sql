@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_impersonations;
+
+-- No-op for the api_key_scope and audit_action enums: keep enum values to avoid dependency
+-- churn.
@@ -0,0 +1,33 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'user:impersonate';
+
+ALTER TYPE audit_action
+	ADD VALUE IF NOT EXISTS 'impersonate';
+
+CREATE TABLE user_impersonations (
+    id uuid NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    impersonator_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason text NOT NULL,
+    lifetime_seconds bigint NOT NULL,
+    consent_required boolean NOT NULL,
+    consented_at timestamp with time zone,
+    api_key_id text REFERENCES api_keys(id) ON DELETE SET NULL,
+    issued_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_impersonations IS 'Requests of support staff to act as a user. A token impersonating the user is issued once the request is granted.';
+
+COMMENT ON COLUMN user_impersonations.user_id IS 'The impersonated user.';
+
+COMMENT ON COLUMN user_impersonations.lifetime_seconds IS 'The lifetime of the token issued for the request.';
+
+COMMENT ON COLUMN user_impersonations.consented_at IS 'When the impersonated user consented to the request. Only set when consent is required.';
+
+COMMENT ON COLUMN user_impersonations.api_key_id IS 'The token issued for the request. Cleared once the token is deleted.';
+
+CREATE INDEX idx_user_impersonations_user_id_created_at ON user_impersonations USING btree (user_id, created_at DESC);
+
+-- Requests made with an impersonation token look it up by its key to audit
+-- the impersonator.
+CREATE INDEX idx_user_impersonations_api_key_id ON user_impersonations USING btree (api_key_id);
@@ -0,0 +1,9 @@
+CREATE TABLE workspace_build_inputs (
+    workspace_build_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_builds(id) ON DELETE CASCADE,
+    inputs jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_inputs IS 'Immutable record of everything a workspace build was provisioned with, captured when its provisioner job is acquired. Used to reproduce a build in another deployment.';
+
+COMMENT ON COLUMN workspace_build_inputs.inputs IS 'The build input bundle. Secrets, such as sensitive template variables and owner tokens, are never included.';
@@ -0,0 +1,23 @@
+ALTER TABLE templates
+	ADD COLUMN idle_reclaim_ttl bigint DEFAULT 0 NOT NULL,
+	ADD COLUMN idle_reclaim_resource_selector text DEFAULT ''::text NOT NULL;
+
+COMMENT ON COLUMN templates.idle_reclaim_ttl IS 'How long running workspaces with a resource matching idle_reclaim_resource_selector may be idle before they are stopped, in nanoseconds. Typically much shorter than the default TTL, to reclaim expensive resources such as GPUs. 0 disables idle reclaim.';
+
+COMMENT ON COLUMN templates.idle_reclaim_resource_selector IS 'A key=value pair matched against the metadata of workspace resources to select the workspaces subject to idle reclaim.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS workspace_app_share_links;
+
+-- No-op for the resource_type enum: keep enum values to avoid dependency
+-- churn.
@@ -0,0 +1,23 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'workspace_app_share_link';
+
+CREATE TABLE workspace_app_share_links (
+    id uuid NOT NULL PRIMARY KEY,
+    workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    agent_name text NOT NULL,
+    app_slug text NOT NULL,
+    hashed_secret bytea NOT NULL,
+    created_by uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    revoked_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_app_share_links IS 'Expiring links that grant visitors authenticated by the OIDC provider of the deployment access to a workspace app, without a Coder account.';
+
+COMMENT ON COLUMN workspace_app_share_links.agent_name IS 'The agent of the shared app. Apps are recreated on every build, so they are referenced by agent name and slug.';
+
+COMMENT ON COLUMN workspace_app_share_links.hashed_secret IS 'The SHA-256 hash of the secret included in the link.';
+
+COMMENT ON COLUMN workspace_app_share_links.revoked_at IS 'When the link was revoked. Revoked links no longer grant access.';
+
+CREATE INDEX idx_workspace_app_share_links_workspace_id ON workspace_app_share_links USING btree (workspace_id);
@@ -0,0 +1,32 @@
+ALTER TABLE templates
+	ADD COLUMN activity_bump_connection_types text[] DEFAULT '{}'::text[] NOT NULL,
+	ADD COLUMN activity_bump_max_per_day bigint DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN templates.activity_bump_connection_types IS 'The connection types whose activity bumps the deadline of workspaces, e.g. ssh or vscode. Empty means all connection types bump the deadline.';
+
+COMMENT ON COLUMN templates.activity_bump_max_per_day IS 'The maximum total duration activity may bump the deadline of a workspace per UTC day, in nanoseconds. 0 means unlimited.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+CREATE TABLE workspace_activity_bumps (
+    workspace_id UUID NOT NULL PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    bumped BIGINT NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE workspace_activity_bumps IS
+    'The total duration, in nanoseconds, activity bumped the deadline of each workspace on the given UTC day. Used to enforce the activity_bump_max_per_day of templates.';
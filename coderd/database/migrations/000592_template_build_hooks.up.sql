@@ -0,0 +1,23 @@
+ALTER TABLE templates
+	ADD COLUMN pre_build_hook_url text DEFAULT ''::text NOT NULL,
+	ADD COLUMN post_build_hook_url text DEFAULT ''::text NOT NULL;
+
+COMMENT ON COLUMN templates.pre_build_hook_url IS 'If set, coderd POSTs to this URL before every workspace build of the template is provisioned. The hook may reject the build.';
+
+COMMENT ON COLUMN templates.post_build_hook_url IS 'If set, coderd POSTs to this URL after every successful workspace build of the template. The hook may report external provisioning steps, which are recorded in the build logs.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
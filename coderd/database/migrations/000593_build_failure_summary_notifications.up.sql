@@ -0,0 +1,5 @@
+UPDATE notification_templates SET body_template = E'Automatic build of your workspace **{{.Labels.name}}** failed.\n\n' ||
+					E'The specified reason was "**{{.Labels.reason}}**".' ||
+					E'{{if .Labels.failure_summary}}\n\nError: **{{.Labels.failure_summary}}**{{end}}' WHERE id = '381df2a9-c0c0-4749-420f-80a9280c66f9';
+UPDATE notification_templates SET body_template = E'A manual build of the workspace **{{.Labels.name}}** using the template **{{.Labels.template_name}}** failed (version: **{{.Labels.template_version_name}}**).\nThe workspace build was initiated by **{{.Labels.initiator}}**.' ||
+					E'{{if .Labels.failure_summary}}\n\nError: **{{.Labels.failure_summary}}**{{end}}' WHERE id = '2faeee0f-26cb-4e96-821c-85ccb9f71513';
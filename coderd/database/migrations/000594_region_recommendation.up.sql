@@ -0,0 +1,34 @@
+ALTER TABLE templates
+	ADD COLUMN auto_assign_region boolean DEFAULT false NOT NULL;
+
+COMMENT ON COLUMN templates.auto_assign_region IS 'If set, workspaces created from the template are assigned the region recommended for the latencies reported by the creating client.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+CREATE TABLE workspace_region_assignments (
+    workspace_id UUID NOT NULL PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    region_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE workspace_region_assignments IS
+    'The region assigned to a workspace when it was created from a template with auto_assign_region set.';
+
+COMMENT ON COLUMN workspace_region_assignments.region_id IS
+    'The ID of the workspace proxy, or the deployment ID for the primary region.';
+
+CREATE INDEX workspace_region_assignments_region_id_idx ON workspace_region_assignments USING btree (region_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS template_version_rollouts;
+
+DROP TYPE IF EXISTS template_version_rollout_status;
@@ -0,0 +1,51 @@
+CREATE TYPE template_version_rollout_status AS ENUM (
+    'in_progress',
+    'completed',
+    'rolled_back',
+    'aborted'
+);
+
+CREATE TABLE template_version_rollouts (
+    id UUID NOT NULL PRIMARY KEY,
+    template_id UUID NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+    template_version_id UUID NOT NULL REFERENCES template_versions(id) ON DELETE CASCADE,
+    previous_version_id UUID NOT NULL REFERENCES template_versions(id) ON DELETE CASCADE,
+    group_id UUID REFERENCES groups(id) ON DELETE SET NULL,
+    stages INTEGER[] DEFAULT '{}'::integer[] NOT NULL,
+    current_stage INTEGER DEFAULT 0 NOT NULL,
+    soak_duration BIGINT NOT NULL,
+    max_failure_rate DOUBLE PRECISION NOT NULL,
+    min_builds INTEGER NOT NULL,
+    status template_version_rollout_status DEFAULT 'in_progress'::template_version_rollout_status NOT NULL,
+    status_message TEXT DEFAULT ''::text NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    stage_started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX template_version_rollouts_template_id_idx ON template_version_rollouts USING btree (template_id, created_at DESC);
+
+CREATE UNIQUE INDEX template_version_rollouts_in_progress_idx ON template_version_rollouts USING btree (template_id) WHERE (status = 'in_progress'::template_version_rollout_status);
+
+COMMENT ON TABLE template_version_rollouts IS
+    'Staged promotions of a template version. Builds of workspaces in the cohort of the current stage that use the active version get the rolled out version instead, until the rollout is completed and the version is promoted, or rolled back.';
+
+COMMENT ON COLUMN template_version_rollouts.previous_version_id IS
+    'The active version of the template when the rollout was created. The rollout is aborted if the active version changes before it is completed.';
+
+COMMENT ON COLUMN template_version_rollouts.group_id IS
+    'The workspaces of the members of this group are part of the cohort of every stage.';
+
+COMMENT ON COLUMN template_version_rollouts.stages IS
+    'The percentage of workspaces in the cohort of each stage.';
+
+COMMENT ON COLUMN template_version_rollouts.soak_duration IS
+    'How long each stage is observed before the rollout continues, in nanoseconds.';
+
+COMMENT ON COLUMN template_version_rollouts.max_failure_rate IS
+    'The rollout is rolled back once the share of failed start builds of the version in a stage exceeds this rate.';
+
+COMMENT ON COLUMN template_version_rollouts.min_builds IS
+    'The number of completed start builds in a stage before its failure rate is considered.';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS template_secrets;
+
+-- No-op for the resource_type enum: keep enum values to avoid dependency
+-- churn.
@@ -0,0 +1,20 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'template_secret';
+
+CREATE TABLE template_secrets (
+    id uuid NOT NULL PRIMARY KEY,
+    template_id uuid NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    value text NOT NULL,
+    value_key_id text REFERENCES dbcrypt_keys(active_key_digest),
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_secrets IS 'Secrets declared by a template that agent scripts can reference. They are fetched by the agent at run time and only exposed to the environment of the scripts that reference them, so they never end up in Terraform state.';
+
+COMMENT ON COLUMN template_secrets.name IS 'The name of the secret, which is also the environment variable it is exposed as.';
+
+COMMENT ON COLUMN template_secrets.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted.';
+
+CREATE UNIQUE INDEX template_secrets_template_id_name_idx ON template_secrets USING btree (template_id, name);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS template_workspace_restart_builds;
+
+DROP TABLE IF EXISTS template_workspace_restarts;
+
+DROP TYPE IF EXISTS template_workspace_restart_status;
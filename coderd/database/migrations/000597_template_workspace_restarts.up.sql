@@ -0,0 +1,65 @@
+CREATE TYPE template_workspace_restart_status AS ENUM (
+    'in_progress',
+    'paused',
+    'completed',
+    'aborted'
+);
+
+CREATE TABLE template_workspace_restarts (
+    id UUID NOT NULL PRIMARY KEY,
+    template_id UUID NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+    template_version_id UUID NOT NULL REFERENCES template_versions(id) ON DELETE CASCADE,
+    batch_size INTEGER NOT NULL,
+    batch_interval BIGINT NOT NULL,
+    honor_quiet_hours BOOLEAN NOT NULL,
+    max_failure_rate DOUBLE PRECISION NOT NULL,
+    min_builds INTEGER NOT NULL,
+    status template_workspace_restart_status DEFAULT 'in_progress'::template_workspace_restart_status NOT NULL,
+    status_message TEXT DEFAULT ''::text NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    stats_since TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_batch_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX template_workspace_restarts_template_id_idx ON template_workspace_restarts USING btree (template_id, created_at DESC);
+
+CREATE UNIQUE INDEX template_workspace_restarts_active_idx ON template_workspace_restarts USING btree (template_id) WHERE (status IN ('in_progress'::template_workspace_restart_status, 'paused'::template_workspace_restart_status));
+
+COMMENT ON TABLE template_workspace_restarts IS
+    'Restarts of the outdated running workspaces of a template on the version that was active when the restart was created, in batches.';
+
+COMMENT ON COLUMN template_workspace_restarts.template_version_id IS
+    'The version workspaces are restarted on. The restart is aborted if the active version of the template changes before it is completed.';
+
+COMMENT ON COLUMN template_workspace_restarts.batch_interval IS
+    'The minimum time between batches, in nanoseconds.';
+
+COMMENT ON COLUMN template_workspace_restarts.honor_quiet_hours IS
+    'Whether workspaces are only restarted during the quiet hours of their owner.';
+
+COMMENT ON COLUMN template_workspace_restarts.max_failure_rate IS
+    'The restart is paused once the share of failed restarts since stats_since exceeds this rate.';
+
+COMMENT ON COLUMN template_workspace_restarts.min_builds IS
+    'The number of completed restarts since stats_since before the failure rate is considered.';
+
+COMMENT ON COLUMN template_workspace_restarts.stats_since IS
+    'The failure rate only considers restarts since this time. It is reset when a paused restart is resumed.';
+
+CREATE TABLE template_workspace_restart_builds (
+    restart_id UUID NOT NULL REFERENCES template_workspace_restarts(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    workspace_build_id UUID REFERENCES workspace_builds(id) ON DELETE CASCADE,
+    error TEXT DEFAULT ''::text NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (restart_id, workspace_id)
+);
+
+COMMENT ON TABLE template_workspace_restart_builds IS
+    'The workspaces restarted by a template workspace restart. Every workspace is restarted at most once.';
+
+COMMENT ON COLUMN template_workspace_restart_builds.error IS
+    'Why the build could not be created, in which case workspace_build_id is NULL.';
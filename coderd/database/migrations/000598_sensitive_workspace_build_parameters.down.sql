@@ -0,0 +1,6 @@
+ALTER TABLE workspace_build_parameters
+    DROP COLUMN IF EXISTS value_key_id,
+    DROP COLUMN IF EXISTS sensitive;
+
+ALTER TABLE template_version_parameters
+    DROP COLUMN IF EXISTS sensitive;
@@ -0,0 +1,12 @@
+ALTER TABLE template_version_parameters
+    ADD COLUMN sensitive boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN template_version_parameters.sensitive IS 'The values of a sensitive parameter are encrypted at rest and redacted in API responses.';
+
+ALTER TABLE workspace_build_parameters
+    ADD COLUMN sensitive boolean NOT NULL DEFAULT false,
+    ADD COLUMN value_key_id text REFERENCES dbcrypt_keys(active_key_digest);
+
+COMMENT ON COLUMN workspace_build_parameters.sensitive IS 'Whether the parameter was sensitive in the template version of the build.';
+
+COMMENT ON COLUMN workspace_build_parameters.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted.';
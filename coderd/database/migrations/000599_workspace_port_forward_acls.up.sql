@@ -0,0 +1,10 @@
+CREATE TABLE workspace_port_forward_acls (
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	ports integer[] NOT NULL CHECK (cardinality(ports) > 0),
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (workspace_id, user_id)
+);
+
+COMMENT ON TABLE workspace_port_forward_acls IS 'Ports of a shared workspace that a user may connect to. Users the workspace is shared with that have no row may connect to any port.';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS idempotency_keys;
+
+DROP TYPE IF EXISTS idempotency_key_operation;
@@ -0,0 +1,24 @@
+CREATE TYPE idempotency_key_operation AS ENUM (
+	'create_workspace',
+	'create_workspace_build'
+);
+
+CREATE TABLE idempotency_keys (
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	operation idempotency_key_operation NOT NULL,
+	key text NOT NULL,
+	request_hash bytea NOT NULL,
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	workspace_build_id uuid NOT NULL REFERENCES workspace_builds(id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id, operation, key)
+);
+
+COMMENT ON TABLE idempotency_keys IS 'Idempotency keys of workspace and workspace build creation requests. A retried request with the same key returns the workspace or build created by the original request instead of creating another one.';
+
+COMMENT ON COLUMN idempotency_keys.user_id IS 'The user that initiated the request. Keys are scoped to the user.';
+
+COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 hash of the request, so a key cannot be reused for a different request.';
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys USING btree (expires_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workspace_cloud_resources;
+
+DROP TYPE IF EXISTS cloud_resource_verification_status;
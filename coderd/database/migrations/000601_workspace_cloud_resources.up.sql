@@ -0,0 +1,31 @@
+CREATE TYPE cloud_resource_verification_status AS ENUM (
+	'unverified',
+	'deleted',
+	'leaked',
+	'unknown'
+);
+
+CREATE TABLE workspace_cloud_resources (
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	resource_type text NOT NULL,
+	cloud_id text NOT NULL,
+	resource_name text NOT NULL,
+	workspace_build_id uuid NOT NULL REFERENCES workspace_builds(id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	verification_status cloud_resource_verification_status NOT NULL DEFAULT 'unverified',
+	verification_build_id uuid REFERENCES workspace_builds(id) ON DELETE SET NULL,
+	verification_error text NOT NULL DEFAULT '',
+	verified_at timestamp with time zone,
+	PRIMARY KEY (workspace_id, resource_type, cloud_id)
+);
+
+COMMENT ON TABLE workspace_cloud_resources IS 'Identifiers of the cloud resources reported by the builds of a workspace. Once the workspace is deleted, each identifier is checked with the cloud provider to detect resources that were leaked by the deletion.';
+
+COMMENT ON COLUMN workspace_cloud_resources.cloud_id IS 'Identifier of the resource at its cloud provider, e.g. the id attribute of a Terraform resource.';
+
+COMMENT ON COLUMN workspace_cloud_resources.workspace_build_id IS 'The latest build that reported the resource.';
+
+COMMENT ON COLUMN workspace_cloud_resources.verification_build_id IS 'The deletion build after which the resource was verified.';
+
+CREATE INDEX idx_workspace_cloud_resources_verification_status ON workspace_cloud_resources USING btree (verification_status);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS template_user_presets;
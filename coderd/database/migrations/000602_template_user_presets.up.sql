@@ -0,0 +1,16 @@
+CREATE TABLE template_user_presets (
+	id uuid PRIMARY KEY,
+	template_id uuid NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name text NOT NULL,
+	parameters jsonb NOT NULL DEFAULT '[]'::jsonb,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	UNIQUE (template_id, user_id, name)
+);
+
+COMMENT ON TABLE template_user_presets IS 'Named sets of parameter values that a user saved for a template. Unlike template version presets, they are private to the user.';
+
+COMMENT ON COLUMN template_user_presets.parameters IS 'Array of objects with the name and value of each parameter.';
+
+CREATE INDEX idx_template_user_presets_user_id ON template_user_presets USING btree (user_id);
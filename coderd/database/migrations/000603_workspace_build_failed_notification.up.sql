@@ -0,0 +1,39 @@
+ALTER TABLE templates
+	ADD COLUMN notify_build_failures boolean DEFAULT false NOT NULL;
+
+COMMENT ON COLUMN templates.notify_build_failures IS 'If set, the owners of workspaces created from the template, and the initiators of their builds, are notified of every failed build along with an excerpt of its logs.';
+
+DROP VIEW template_with_names;
+
+CREATE VIEW template_with_names AS
+SELECT templates.*,
+	   COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+	   COALESCE(visible_users.username, ''::text) AS created_by_username,
+	   COALESCE(visible_users.name, ''::text) AS created_by_name,
+	   COALESCE(organizations.name, ''::text) AS organization_name,
+	   COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+	   COALESCE(organizations.icon, ''::text) AS organization_icon
+FROM ((templates
+	LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+INSERT INTO notification_templates (
+    id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+) VALUES (
+    '7c1a8d3e-5f2b-4e69-9a0d-3b6e4f81c527',
+    'Workspace Build Failed',
+    E'Workspace "{{.Labels.name}}" failed to {{.Labels.transition}}',
+    E'The workspace **{{.Labels.name}}** using the template **{{.Labels.template_name}}** failed to {{.Labels.transition}} in build #{{.Labels.workspace_build_number}}.\n\n' ||
+    E'The build was initiated by **{{.Labels.initiator}}** (reason: {{.Labels.reason}}).' ||
+    E'{{if .Labels.failure_summary}}\n\nError: **{{.Labels.failure_summary}}**{{end}}' ||
+    E'{{if .Data.log_lines}}\n\nLast log lines:\n\n' ||
+        E'{{range $line := .Data.log_lines}}- `{{$line}}`\n{{end}}' ||
+    E'{{end}}',
+    '[{"label": "View build logs", "url": "{{base_url}}/@{{.Labels.workspace_owner_username}}/{{.Labels.name}}/builds/{{.Labels.workspace_build_number}}"}]'::jsonb,
+    'Workspace Events',
+    NULL,
+    'system'::notification_template_kind,
+    true
+);
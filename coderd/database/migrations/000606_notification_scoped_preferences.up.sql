@@ -0,0 +1,66 @@
+CREATE TYPE notification_preference_scope AS ENUM (
+	'workspace',
+	'template'
+);
+
+CREATE TABLE notification_scoped_preferences (
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	notification_template_id uuid NOT NULL REFERENCES notification_templates(id) ON DELETE CASCADE,
+	scope notification_preference_scope NOT NULL,
+	target_id uuid NOT NULL,
+	disabled boolean NOT NULL DEFAULT FALSE,
+	created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, notification_template_id, target_id)
+);
+
+COMMENT ON TABLE notification_scoped_preferences IS 'Notification preferences of users that only apply to messages about a single workspace or template. They take precedence over notification_preferences.';
+COMMENT ON COLUMN notification_scoped_preferences.target_id IS 'ID of the workspace or template, matched against the targets of enqueued messages.';
+
+CREATE OR REPLACE FUNCTION inhibit_enqueue_if_disabled()
+	RETURNS TRIGGER AS
+$$
+DECLARE
+	scoped_disabled boolean;
+BEGIN
+	-- A preference for the workspace or template that the message is about
+	-- takes precedence over the preference for the notification template.
+	-- Workspace preferences are more specific than template ones.
+	SELECT notification_scoped_preferences.disabled INTO scoped_disabled
+	FROM notification_scoped_preferences
+	WHERE notification_scoped_preferences.user_id = NEW.user_id
+		AND notification_scoped_preferences.notification_template_id = NEW.notification_template_id
+		AND notification_scoped_preferences.target_id = ANY(NEW.targets)
+	ORDER BY (notification_scoped_preferences.scope = 'workspace') DESC
+	LIMIT 1;
+
+	IF FOUND THEN
+		IF scoped_disabled THEN
+			RAISE EXCEPTION 'cannot enqueue message: notification is not enabled';
+		END IF;
+		RETURN NEW;
+	END IF;
+
+	-- Fail the insertion if one of the following:
+	--  * the user has disabled this notification.
+	--  * the notification template is disabled by default and hasn't
+	--    been explicitly enabled by the user.
+	IF EXISTS (
+		SELECT 1 FROM notification_templates
+		LEFT JOIN notification_preferences
+			ON  notification_preferences.notification_template_id = notification_templates.id
+			AND notification_preferences.user_id = NEW.user_id
+		WHERE notification_templates.id = NEW.notification_template_id AND (
+			-- Case 1: The user has explicitly disabled this template
+			notification_preferences.disabled = TRUE
+			OR
+			-- Case 2: The template is disabled by default AND the user hasn't enabled it
+			(notification_templates.enabled_by_default = FALSE AND notification_preferences.notification_template_id IS NULL)
+		)
+	) THEN
+		RAISE EXCEPTION 'cannot enqueue message: notification is not enabled';
+	END IF;
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
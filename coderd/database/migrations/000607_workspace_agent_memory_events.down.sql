@@ -0,0 +1,5 @@
+DELETE FROM notification_templates WHERE id = '2229d63e-e11d-481b-8b20-8d6e30b430b6';
+
+DROP TABLE IF EXISTS workspace_agent_memory_events;
+
+DROP TYPE IF EXISTS workspace_agent_memory_event_kind;
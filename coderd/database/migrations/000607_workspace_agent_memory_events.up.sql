@@ -0,0 +1,37 @@
+CREATE TYPE workspace_agent_memory_event_kind AS ENUM (
+	'oom_kill',
+	'memory_pressure'
+);
+
+CREATE TABLE workspace_agent_memory_events (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	kind workspace_agent_memory_event_kind NOT NULL,
+	oom_kills integer NOT NULL DEFAULT 0 CHECK (oom_kills >= 0),
+	memory_pressure double precision NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE workspace_agent_memory_events IS 'OOM kills and severe memory pressure reported by the resources monitor of a workspace agent.';
+
+COMMENT ON COLUMN workspace_agent_memory_events.created_at IS 'When the agent collected the datapoint the event was derived from.';
+
+COMMENT ON COLUMN workspace_agent_memory_events.oom_kills IS 'Number of processes killed by the OOM killer since the previous datapoint. Zero for memory pressure events.';
+
+COMMENT ON COLUMN workspace_agent_memory_events.memory_pressure IS 'Percentage of time in the last 10 seconds during which all non-idle tasks of the workspace were stalled on memory.';
+
+CREATE INDEX workspace_agent_memory_events_workspace_agent_id_idx ON workspace_agent_memory_events USING btree (workspace_agent_id, created_at);
+
+INSERT INTO notification_templates (
+    id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+) VALUES (
+    '2229d63e-e11d-481b-8b20-8d6e30b430b6',
+    'Workspace Out Of Memory Kill',
+    E'Processes in workspace "{{.Labels.workspace}}" were killed for running out of memory',
+    E'The kernel killed {{.Labels.oom_kills}} process(es) in your workspace **{{.Labels.workspace}}** because it ran out of memory.\n\nIf this keeps happening, ask your template administrator for more memory or reduce the memory usage of your workspace.',
+    '[{"label": "View workspace", "url": "{{base_url}}/@{{.UserUsername}}/{{.Labels.workspace}}"}]'::jsonb,
+    'Workspace Events',
+    NULL,
+    'system'::notification_template_kind,
+    false
+);
@@ -0,0 +1 @@
+-- Postgres does not support removing enum values.
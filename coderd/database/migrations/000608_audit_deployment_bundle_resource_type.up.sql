@@ -0,0 +1,2 @@
+-- Audit log resource type for deployment diagnostics bundle downloads.
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'deployment_bundle';
@@ -0,0 +1,14 @@
+CREATE TABLE template_preset_cohorts (
+	template_id uuid NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+	preset_name text NOT NULL,
+	group_ids uuid[] NOT NULL DEFAULT '{}'::uuid[],
+	roles text[] NOT NULL DEFAULT '{}'::text[],
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_id, preset_name)
+);
+
+COMMENT ON TABLE template_preset_cohorts IS 'Restricts the presets of a template with a given name, in every version of the template, to a cohort of users. Presets without a cohort are available to everyone who can use the template.';
+
+COMMENT ON COLUMN template_preset_cohorts.group_ids IS 'Members of any of these groups are part of the cohort.';
+
+COMMENT ON COLUMN template_preset_cohorts.roles IS 'Names of site-wide roles, or of roles in the organization of the template. Users with any of these roles are part of the cohort.';
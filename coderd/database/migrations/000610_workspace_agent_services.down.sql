@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS workspace_agent_service_transitions;
+
+DROP TABLE IF EXISTS workspace_agent_services;
+
+DROP TYPE IF EXISTS workspace_agent_service_status;
+
+DROP TYPE IF EXISTS workspace_agent_service_restart_policy;
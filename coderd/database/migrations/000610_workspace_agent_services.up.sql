@@ -0,0 +1,45 @@
+CREATE TYPE workspace_agent_service_restart_policy AS ENUM (
+	'always',
+	'on_failure',
+	'never'
+);
+
+CREATE TYPE workspace_agent_service_status AS ENUM (
+	'starting',
+	'running',
+	'restarting',
+	'stopped',
+	'failed'
+);
+
+CREATE TABLE workspace_agent_services (
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	name text NOT NULL,
+	display_name text NOT NULL,
+	command text NOT NULL,
+	restart_policy workspace_agent_service_restart_policy NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (workspace_agent_id, name)
+);
+
+COMMENT ON TABLE workspace_agent_services IS 'Long-running processes declared by the template that the workspace agent supervises.';
+
+CREATE TABLE workspace_agent_service_transitions (
+	id uuid PRIMARY KEY,
+	workspace_agent_id uuid NOT NULL,
+	service_name text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	status workspace_agent_service_status NOT NULL,
+	message text NOT NULL DEFAULT '',
+	exit_code integer,
+	restart_count integer NOT NULL DEFAULT 0,
+	CONSTRAINT workspace_agent_service_transitions_service_fkey FOREIGN KEY (workspace_agent_id, service_name) REFERENCES workspace_agent_services(workspace_agent_id, name) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE workspace_agent_service_transitions IS 'Status transitions of the services of a workspace agent. The most recent transition is the current status of the service.';
+
+COMMENT ON COLUMN workspace_agent_service_transitions.exit_code IS 'Exit code of the service process, if the transition was caused by the process exiting.';
+
+COMMENT ON COLUMN workspace_agent_service_transitions.restart_count IS 'Number of times the agent restarted the service since the agent started.';
+
+CREATE INDEX workspace_agent_service_transitions_workspace_agent_id_idx ON workspace_agent_service_transitions USING btree (workspace_agent_id, created_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS shared_volume_attachments;
+
+DROP TABLE IF EXISTS shared_volumes;
+
+DROP TYPE IF EXISTS shared_volume_access_mode;
@@ -0,0 +1,42 @@
+CREATE TYPE shared_volume_access_mode AS ENUM (
+	'read_only',
+	'read_write'
+);
+
+CREATE TABLE shared_volumes (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+	name text NOT NULL,
+	display_name text NOT NULL DEFAULT '',
+	description text NOT NULL DEFAULT '',
+	template_id uuid REFERENCES templates(id) ON DELETE SET NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	CONSTRAINT shared_volumes_organization_id_name_key UNIQUE (organization_id, name)
+);
+
+COMMENT ON TABLE shared_volumes IS 'Persistent volumes that multiple workspaces of an organization can attach.';
+
+COMMENT ON COLUMN shared_volumes.template_id IS 'The template that declared the volume on its first build, or NULL if the volume was declared for the organization.';
+
+CREATE TABLE shared_volume_attachments (
+	id uuid PRIMARY KEY,
+	shared_volume_id uuid NOT NULL REFERENCES shared_volumes(id) ON DELETE CASCADE,
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	workspace_build_id uuid NOT NULL REFERENCES workspace_builds(id) ON DELETE CASCADE,
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	mount_path text NOT NULL,
+	access_mode shared_volume_access_mode NOT NULL,
+	attached_at timestamp with time zone NOT NULL,
+	detached_at timestamp with time zone,
+	detached_by uuid REFERENCES users(id) ON DELETE SET NULL
+);
+
+COMMENT ON TABLE shared_volume_attachments IS 'Attachments of shared volumes to workspace agents. An attachment is active until it is detached by a later build of the workspace or by an administrator.';
+
+COMMENT ON COLUMN shared_volume_attachments.detached_by IS 'The user that force-detached the volume, or NULL if it was detached by a build.';
+
+-- A read-write attachment locks the volume against other writers.
+CREATE UNIQUE INDEX shared_volume_attachments_read_write_lock_idx ON shared_volume_attachments USING btree (shared_volume_id) WHERE ((access_mode = 'read_write'::shared_volume_access_mode) AND (detached_at IS NULL));
+
+CREATE INDEX shared_volume_attachments_workspace_id_idx ON shared_volume_attachments USING btree (workspace_id) WHERE (detached_at IS NULL);
@@ -0,0 +1,3 @@
+DELETE FROM notification_templates WHERE id = 'b5e4a1c7-3d62-4f8e-9a05-6c2d8e7f1b39';
+
+DROP TABLE IF EXISTS template_health_reports;
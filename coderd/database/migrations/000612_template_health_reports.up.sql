@@ -0,0 +1,47 @@
+CREATE TABLE template_health_reports (
+	id uuid NOT NULL PRIMARY KEY,
+	template_id uuid NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+	period_start timestamp with time zone NOT NULL,
+	period_end timestamp with time zone NOT NULL,
+	total_builds bigint NOT NULL,
+	failed_builds bigint NOT NULL,
+	average_start_seconds double precision NOT NULL,
+	total_workspaces bigint NOT NULL,
+	outdated_workspaces bigint NOT NULL,
+	dormant_workspaces bigint NOT NULL,
+	created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_health_reports IS 'Periodic health reports of templates, sent to template admins by the report generator.';
+
+COMMENT ON COLUMN template_health_reports.average_start_seconds IS 'Average time it took successful start builds of the period to complete, from when they were queued. Zero if there were none.';
+
+COMMENT ON COLUMN template_health_reports.outdated_workspaces IS 'Workspaces whose latest build does not use the active template version, at the end of the period.';
+
+CREATE INDEX template_health_reports_template_id_idx ON template_health_reports USING btree (template_id, period_end DESC);
+
+INSERT INTO notification_templates (
+    id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+) VALUES (
+    'b5e4a1c7-3d62-4f8e-9a05-6c2d8e7f1b39',
+    'Report: Template Health',
+    E'Template health report',
+    E'Here is how your templates performed over the last {{.Data.report_frequency}}:
+{{range $template := .Data.templates}}
+**{{$template.display_name}}**
+
+- Failed builds: {{$template.failed_builds}} of {{$template.total_builds}} ({{$template.failure_rate}}%)
+
+- Average start time: {{$template.average_start_seconds}} seconds
+
+- Outdated workspaces: {{$template.outdated_workspaces}} of {{$template.total_workspaces}}
+
+- Dormant workspaces: {{$template.dormant_workspaces}} of {{$template.total_workspaces}}
+{{end}}
+We recommend reviewing templates with failing builds or outdated workspaces before users run into problems.',
+    '[{"label": "View templates", "url": "{{base_url}}/templates"}]'::jsonb,
+    'Template Events',
+    NULL,
+    'system'::notification_template_kind,
+    true
+);
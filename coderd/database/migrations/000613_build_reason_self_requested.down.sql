@@ -0,0 +1,2 @@
+-- No-op for the build_reason enum: keep enum values to avoid dependency
+-- churn.
@@ -0,0 +1,2 @@
+-- It's not possible to delete enum values.
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'self_requested';
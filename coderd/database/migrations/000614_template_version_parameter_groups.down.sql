@@ -0,0 +1,4 @@
+ALTER TABLE template_version_parameters
+    DROP COLUMN IF EXISTS page,
+    DROP COLUMN IF EXISTS group_order,
+    DROP COLUMN IF EXISTS group_name;
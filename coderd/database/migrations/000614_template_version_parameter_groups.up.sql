@@ -0,0 +1,10 @@
+ALTER TABLE template_version_parameters
+    ADD COLUMN group_name text NOT NULL DEFAULT '',
+    ADD COLUMN group_order integer NOT NULL DEFAULT 0,
+    ADD COLUMN page integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN template_version_parameters.group_name IS 'Name of the group of the parameter, used to present parameters in sections. Empty if the parameter is not grouped.';
+
+COMMENT ON COLUMN template_version_parameters.group_order IS 'Specifies the order in which to display the group of the parameter. All parameters of a group share it.';
+
+COMMENT ON COLUMN template_version_parameters.page IS 'Page of a multi-page form on which to display the parameter. All parameters of a group share it.';
@@ -0,0 +1,3 @@
+DELETE FROM notification_templates WHERE id = 'e3b7c9d2-5a1f-4c8e-b6d0-9f2a4e7c1b85';
+
+DROP TABLE IF EXISTS user_workspace_reports;
@@ -0,0 +1,42 @@
+CREATE TABLE user_workspace_reports (
+	id uuid NOT NULL PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	period_start timestamp with time zone NOT NULL,
+	period_end timestamp with time zone NOT NULL,
+	summary jsonb NOT NULL,
+	created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_workspace_reports IS 'Periodic summaries of the workspaces of users, sent to them by the report generator.';
+
+COMMENT ON COLUMN user_workspace_reports.summary IS 'Builds of the workspaces of the user during the period, and the workspaces that will be deleted or become dormant soon, as served by the API.';
+
+CREATE INDEX user_workspace_reports_user_id_idx ON user_workspace_reports USING btree (user_id, period_end DESC);
+
+INSERT INTO notification_templates (
+    id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+) VALUES (
+    'e3b7c9d2-5a1f-4c8e-b6d0-9f2a4e7c1b85',
+    'Report: Workspace Summary',
+    E'Your workspace summary',
+    E'Here is what happened to your workspaces over the last {{.Data.report_frequency}}:
+
+- Builds: {{.Data.total_builds}}
+
+- Failed builds: {{.Data.failed_builds}}
+{{if .Data.upcoming_deletions}}
+**Upcoming deletions**
+{{range $workspace := .Data.upcoming_deletions}}
+- {{$workspace.name}} will be deleted on {{$workspace.deleting_at}}
+{{end}}{{end}}{{if .Data.dormancy_warnings}}
+**Dormancy warnings**
+{{range $workspace := .Data.dormancy_warnings}}
+- {{$workspace.name}} will become dormant on {{$workspace.dormant_at}}
+{{end}}{{end}}
+Using a workspace, for example by connecting to it, keeps it from becoming dormant.',
+    '[{"label": "View workspaces", "url": "{{base_url}}/workspaces"}]'::jsonb,
+    'Workspace Events',
+    NULL,
+    'system'::notification_template_kind,
+    true
+);
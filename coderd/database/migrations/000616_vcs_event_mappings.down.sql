@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS vcs_event_mappings;
+
+DROP TYPE IF EXISTS vcs_event_action;
+
+-- No-op for the resource_type enum: keep enum values to avoid dependency
+-- churn.
@@ -0,0 +1,42 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'vcs_event_mapping';
+
+CREATE TYPE vcs_event_action AS ENUM (
+	'start_workspace',
+	'refresh_prebuilds',
+	'update_workspaces'
+);
+
+CREATE TABLE vcs_event_mappings (
+	id uuid NOT NULL PRIMARY KEY,
+	template_id uuid NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+	name text NOT NULL,
+	repository text NOT NULL,
+	branch text NOT NULL DEFAULT '',
+	action vcs_event_action NOT NULL,
+	workspace_id uuid REFERENCES workspaces(id) ON DELETE CASCADE,
+	signing_secret text NOT NULL,
+	signing_secret_key_id text REFERENCES dbcrypt_keys(active_key_digest),
+	created_by uuid NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	last_triggered_at timestamp with time zone,
+	CONSTRAINT vcs_event_mappings_workspace_id_check CHECK (((action = 'start_workspace'::vcs_event_action) = (workspace_id IS NOT NULL)))
+);
+
+COMMENT ON TABLE vcs_event_mappings IS 'Maps the push events of a VCS repository, received by the VCS webhook receiver, to an action on a template or one of its workspaces.';
+
+COMMENT ON COLUMN vcs_event_mappings.repository IS 'The full name of the repository, e.g. "coder/coder". Matched case-insensitively.';
+
+COMMENT ON COLUMN vcs_event_mappings.branch IS 'The branch pushes must be made to. Empty matches every branch.';
+
+COMMENT ON COLUMN vcs_event_mappings.workspace_id IS 'The workspace started by the start_workspace action. NULL for the other actions.';
+
+COMMENT ON COLUMN vcs_event_mappings.signing_secret IS 'The secret of the repository webhook, used to verify the signature of events. GitHub signs the body with HMAC-SHA256, GitLab sends the secret as is.';
+
+COMMENT ON COLUMN vcs_event_mappings.signing_secret_key_id IS 'The ID of the key used to encrypt the signing secret. If this is NULL, the signing secret is not encrypted.';
+
+COMMENT ON COLUMN vcs_event_mappings.created_by IS 'The user the actions of the mapping are performed as.';
+
+CREATE UNIQUE INDEX vcs_event_mappings_template_id_name_idx ON vcs_event_mappings USING btree (template_id, name);
+
+CREATE INDEX vcs_event_mappings_repository_idx ON vcs_event_mappings USING btree (lower(repository));
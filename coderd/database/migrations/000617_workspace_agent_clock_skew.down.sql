@@ -0,0 +1 @@
+ALTER TABLE workspace_agents DROP COLUMN clock_skew_ms;
@@ -0,0 +1,3 @@
+ALTER TABLE workspace_agents ADD COLUMN clock_skew_ms bigint;
+
+COMMENT ON COLUMN workspace_agents.clock_skew_ms IS 'The offset of the clock of the agent from the clock of coderd in milliseconds, as last reported by the agent. Positive values mean the clock of the agent is ahead. NULL if the agent never reported it.';
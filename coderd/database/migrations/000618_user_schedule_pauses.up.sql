@@ -0,0 +1,13 @@
+CREATE TABLE user_schedule_pauses (
+	user_id uuid NOT NULL PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	paused_until timestamp with time zone NOT NULL,
+	pause_autostop boolean NOT NULL DEFAULT false,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_schedule_pauses IS 'Pauses of the autostart schedules of the workspaces of users, for example while they are on leave.';
+
+COMMENT ON COLUMN user_schedule_pauses.paused_until IS 'The time the pause ends at. Autostarts that were due during the pause are skipped.';
+
+COMMENT ON COLUMN user_schedule_pauses.pause_autostop IS 'Whether autostops at the deadline of builds are paused as well. Dormancy and deletion are never paused.';
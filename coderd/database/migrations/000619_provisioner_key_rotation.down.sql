@@ -0,0 +1,4 @@
+ALTER TABLE provisioner_keys
+    DROP COLUMN IF EXISTS previous_secret_expires_at,
+    DROP COLUMN IF EXISTS previous_hashed_secret,
+    DROP COLUMN IF EXISTS last_used_at;
@@ -0,0 +1,10 @@
+ALTER TABLE provisioner_keys
+    ADD COLUMN last_used_at timestamp with time zone,
+    ADD COLUMN previous_hashed_secret bytea,
+    ADD COLUMN previous_secret_expires_at timestamp with time zone;
+
+COMMENT ON COLUMN provisioner_keys.last_used_at IS 'The last time a provisioner daemon authenticated with the key. NULL if the key was never used.';
+
+COMMENT ON COLUMN provisioner_keys.previous_hashed_secret IS 'The hashed secret the key was rotated away from. It stays valid until previous_secret_expires_at, so that daemons can be moved to the new secret without downtime.';
+
+COMMENT ON COLUMN provisioner_keys.previous_secret_expires_at IS 'The time the previous secret of the key stops being valid at. NULL if the key was never rotated.';
@@ -0,0 +1,21 @@
+INSERT INTO workspace_dormancy_exemptions (
+	workspace_id,
+	reason,
+	expires_at,
+	approved_by,
+	created_at,
+	updated_at
+)
+SELECT
+	id,
+	'Long-running research job',
+	NOW() + INTERVAL '7 days',
+	owner_id,
+	NOW(),
+	NOW()
+FROM
+	workspaces
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,18 @@
+INSERT INTO workspace_build_annotations (
+	id,
+	workspace_build_id,
+	user_id,
+	note,
+	created_at
+)
+SELECT
+	gen_random_uuid(),
+	workspace_builds.id,
+	workspace_builds.initiator_id,
+	'Rebuilt to pick up CVE fix',
+	NOW()
+FROM
+	workspace_builds
+ORDER BY
+	workspace_builds.created_at, workspace_builds.id
+LIMIT 1;
@@ -0,0 +1,24 @@
+INSERT INTO workspace_egress_daily (
+	workspace_id,
+	template_id,
+	owner_id,
+	date,
+	rx_bytes_ssh,
+	tx_bytes_ssh,
+	rx_bytes_port_forward,
+	tx_bytes_port_forward
+)
+SELECT
+	workspaces.id,
+	workspaces.template_id,
+	workspaces.owner_id,
+	CURRENT_DATE,
+	1024,
+	4096,
+	512,
+	2048
+FROM
+	workspaces
+ORDER BY
+	workspaces.created_at, workspaces.id
+LIMIT 1;
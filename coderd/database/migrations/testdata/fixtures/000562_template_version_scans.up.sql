@@ -0,0 +1,18 @@
+INSERT INTO template_version_scans (
+	template_version_id,
+	status,
+	findings,
+	started_at,
+	completed_at
+)
+SELECT
+	template_versions.id,
+	'completed',
+	'[{"severity": "high", "rule_id": "privileged-container", "message": "Container runs privileged.", "path": "main.tf"}]'::jsonb,
+	NOW() - INTERVAL '1 minute',
+	NOW()
+FROM
+	template_versions
+ORDER BY
+	template_versions.created_at, template_versions.id
+LIMIT 1;
@@ -0,0 +1,18 @@
+INSERT INTO workspace_build_parameter_changes (
+	workspace_build_id,
+	name,
+	kind,
+	previous_value,
+	new_value
+)
+SELECT
+	workspace_builds.id,
+	'region',
+	'modified',
+	'us-east',
+	'eu-west'
+FROM
+	workspace_builds
+ORDER BY
+	workspace_builds.created_at, workspace_builds.id
+LIMIT 1;
@@ -0,0 +1,21 @@
+INSERT INTO workspace_dns_records (
+	workspace_id,
+	name,
+	target,
+	created_at,
+	updated_at
+)
+SELECT
+	workspaces.id,
+	workspaces.name || '--' || users.username || '.apps.example.com',
+	'coder.example.com',
+	NOW(),
+	NOW()
+FROM
+	workspaces
+JOIN
+	users ON users.id = workspaces.owner_id
+ORDER BY
+	workspaces.created_at, workspaces.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,19 @@
+INSERT INTO workspace_monthly_costs (
+	workspace_id,
+	month,
+	cost,
+	source,
+	updated_at
+)
+SELECT
+	id,
+	date_trunc('month', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+	12.5,
+	'estimate',
+	NOW()
+FROM
+	workspaces
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
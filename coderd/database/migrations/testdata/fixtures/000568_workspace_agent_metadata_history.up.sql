@@ -0,0 +1,16 @@
+INSERT INTO workspace_agent_metadata_history (
+	workspace_agent_id,
+	key,
+	value,
+	collected_at
+)
+SELECT
+	workspace_agents.id,
+	'gpu_temperature',
+	'71',
+	NOW()
+FROM
+	workspace_agents
+ORDER BY
+	workspace_agents.created_at, workspace_agents.id
+LIMIT 1;
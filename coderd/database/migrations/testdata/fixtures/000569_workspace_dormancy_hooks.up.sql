@@ -0,0 +1,21 @@
+INSERT INTO workspace_dormancy_hooks (
+	workspace_id,
+	event,
+	status,
+	error,
+	created_at,
+	updated_at
+)
+SELECT
+	id,
+	'dormant',
+	'succeeded',
+	'',
+	NOW(),
+	NOW()
+FROM
+	workspaces
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
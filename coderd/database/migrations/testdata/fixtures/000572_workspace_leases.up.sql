@@ -0,0 +1,25 @@
+INSERT INTO workspace_leases (
+	id,
+	workspace_id,
+	template_id,
+	owner_id,
+	api_key_id,
+	credential_key_id,
+	created_at,
+	expires_at
+)
+SELECT
+	'c4d1a8e2-5b3f-4e7a-9d6c-1f2e3a4b5c6d',
+	id,
+	template_id,
+	owner_id,
+	'lease-fixture',
+	'',
+	NOW(),
+	NOW() + INTERVAL '1 hour'
+FROM
+	workspaces
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
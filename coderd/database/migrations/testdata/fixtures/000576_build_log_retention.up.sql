@@ -0,0 +1,17 @@
+INSERT INTO provisioner_job_log_archives (
+	job_id,
+	archived_at,
+	object_key
+)
+SELECT
+	id,
+	NOW(),
+	id::text || '.json.gz'
+FROM
+	provisioner_jobs
+WHERE
+	completed_at IS NOT NULL
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
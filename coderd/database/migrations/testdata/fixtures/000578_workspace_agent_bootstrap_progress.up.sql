@@ -0,0 +1,20 @@
+INSERT INTO workspace_agent_bootstrap_progress (
+	id,
+	workspace_agent_id,
+	created_at,
+	name,
+	percent,
+	message
+)
+SELECT
+	'7c8dd5f4-27b4-4a59-8d5e-4b0b5f1d2a31',
+	workspace_agents.id,
+	NOW(),
+	'cloning repo',
+	40,
+	'github.com/coder/coder'
+FROM
+	workspace_agents
+ORDER BY
+	workspace_agents.created_at, workspace_agents.id
+LIMIT 1;
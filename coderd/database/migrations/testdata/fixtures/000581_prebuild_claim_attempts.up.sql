@@ -0,0 +1,21 @@
+INSERT INTO prebuild_claim_attempts (
+	id,
+	preset_id,
+	workspace_id,
+	claimed,
+	miss_reason,
+	created_at
+)
+SELECT
+	'9e0b7f3a-2c4d-4f8e-a6b1-5d3c2e1f0a9b',
+	id,
+	NULL,
+	false,
+	'capacity',
+	NOW()
+FROM
+	template_version_presets
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,54 @@
+INSERT INTO template_network_policies (
+	template_id,
+	allowed_domains,
+	allowed_cidrs,
+	updated_at
+)
+SELECT
+	templates.id,
+	ARRAY['github.com', '*.githubusercontent.com'],
+	ARRAY['10.0.0.0/8'],
+	NOW()
+FROM
+	templates
+ORDER BY
+	templates.created_at, templates.id
+LIMIT 1;
+
+INSERT INTO workspace_network_policies (
+	workspace_id,
+	allowed_domains,
+	allowed_cidrs,
+	updated_at
+)
+SELECT
+	workspaces.id,
+	ARRAY['registry.npmjs.org'],
+	ARRAY[]::text[],
+	NOW()
+FROM
+	workspaces
+ORDER BY
+	workspaces.created_at, workspaces.id
+LIMIT 1;
+
+INSERT INTO workspace_agent_network_policy_violations (
+	id,
+	workspace_agent_id,
+	created_at,
+	destination,
+	port,
+	count
+)
+SELECT
+	'3f0c9d6e-5b1a-4c2e-9f7d-8a6b4e2d1c05',
+	workspace_agents.id,
+	NOW(),
+	'example.com',
+	443,
+	3
+FROM
+	workspace_agents
+ORDER BY
+	workspace_agents.created_at, workspace_agents.id
+LIMIT 1;
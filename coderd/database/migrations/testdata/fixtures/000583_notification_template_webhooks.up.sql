@@ -0,0 +1,18 @@
+INSERT INTO notification_template_webhooks (
+	notification_template_id,
+	endpoint,
+	payload_template,
+	signing_secret,
+	updated_at
+)
+SELECT
+	notification_templates.id,
+	'https://hooks.example.com/coder',
+	'{"text": "{{ .Title }}"}',
+	'secret',
+	NOW()
+FROM
+	notification_templates
+ORDER BY
+	notification_templates.id
+LIMIT 1;
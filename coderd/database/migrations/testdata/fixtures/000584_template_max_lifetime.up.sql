@@ -0,0 +1,17 @@
+INSERT INTO workspace_lifetime_warnings (
+	workspace_id,
+	expires_at,
+	days_before,
+	sent_at
+)
+SELECT
+	id,
+	NOW() + INTERVAL '7 days',
+	7,
+	NOW()
+FROM
+	workspaces
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,21 @@
+INSERT INTO workspace_agent_crash_loops (
+	agent_id,
+	short_connections,
+	last_disconnected_at,
+	last_disconnect_reason,
+	last_lifecycle_state,
+	crash_looping_since
+)
+SELECT
+	id,
+	5,
+	NOW(),
+	'ping timeout',
+	'start_error',
+	NOW()
+FROM
+	workspace_agents
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
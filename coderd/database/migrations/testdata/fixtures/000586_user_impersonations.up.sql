@@ -0,0 +1,29 @@
+INSERT INTO user_impersonations (
+	id,
+	user_id,
+	impersonator_id,
+	reason,
+	lifetime_seconds,
+	consent_required,
+	consented_at,
+	api_key_id,
+	issued_at,
+	created_at
+)
+SELECT
+	'6b3b5a9e-2d0f-4a4e-9d35-0f6f2e1c8a11',
+	id,
+	id,
+	'Debugging a failing workspace build',
+	3600,
+	true,
+	NOW(),
+	NULL,
+	NULL,
+	NOW()
+FROM
+	users
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
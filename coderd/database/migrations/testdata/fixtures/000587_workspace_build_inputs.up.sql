@@ -0,0 +1,15 @@
+INSERT INTO workspace_build_inputs (
+	workspace_build_id,
+	inputs,
+	created_at
+)
+SELECT
+	id,
+	'{"template_version_hash": "", "parameters": [], "variables": [], "provisioner_tags": {}}'::jsonb,
+	NOW()
+FROM
+	workspace_builds
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
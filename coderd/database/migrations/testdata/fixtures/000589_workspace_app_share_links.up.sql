@@ -0,0 +1,27 @@
+INSERT INTO workspace_app_share_links (
+	id,
+	workspace_id,
+	agent_name,
+	app_slug,
+	hashed_secret,
+	created_by,
+	created_at,
+	expires_at,
+	revoked_at
+)
+SELECT
+	'3f1d8c2a-7b4e-4f6a-9c0d-5e2b1a8f4c77',
+	id,
+	'main',
+	'code-server',
+	'\xdeadbeef'::bytea,
+	owner_id,
+	NOW(),
+	NOW() + INTERVAL '1 day',
+	NULL
+FROM
+	workspaces
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,15 @@
+INSERT INTO workspace_activity_bumps (
+	workspace_id,
+	date,
+	bumped
+)
+SELECT
+	id,
+	'2024-01-01',
+	3600000000000
+FROM
+	workspaces
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,15 @@
+INSERT INTO workspace_region_assignments (
+	workspace_id,
+	region_id,
+	created_at
+)
+SELECT
+	workspaces.id,
+	'00000000-0000-0000-0000-000000000000',
+	NOW()
+FROM
+	workspaces
+ORDER BY
+	workspaces.created_at, workspaces.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,39 @@
+INSERT INTO template_version_rollouts (
+	id,
+	template_id,
+	template_version_id,
+	previous_version_id,
+	stages,
+	current_stage,
+	soak_duration,
+	max_failure_rate,
+	min_builds,
+	status,
+	created_by,
+	created_at,
+	updated_at,
+	stage_started_at,
+	completed_at
+)
+SELECT
+	'3c1d7a2e-5f0b-4b8e-9d6a-2f4e8c1b7a90',
+	templates.id,
+	templates.active_version_id,
+	templates.active_version_id,
+	'{10, 50}',
+	0,
+	3600000000000,
+	0.1,
+	5,
+	'aborted',
+	templates.created_by,
+	NOW(),
+	NOW(),
+	NOW(),
+	NOW()
+FROM
+	templates
+ORDER BY
+	templates.created_at, templates.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
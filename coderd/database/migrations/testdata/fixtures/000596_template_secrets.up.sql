@@ -0,0 +1,25 @@
+INSERT INTO template_secrets (
+	id,
+	template_id,
+	name,
+	description,
+	value,
+	value_key_id,
+	created_at,
+	updated_at
+)
+SELECT
+	'7e2b9d41-0c6f-4a3e-8b5d-1f9a6c2e4d83',
+	id,
+	'DATABASE_PASSWORD',
+	'Password of the development database.',
+	'hunter2',
+	NULL,
+	NOW(),
+	NOW()
+FROM
+	templates
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,59 @@
+INSERT INTO template_workspace_restarts (
+	id,
+	template_id,
+	template_version_id,
+	batch_size,
+	batch_interval,
+	honor_quiet_hours,
+	max_failure_rate,
+	min_builds,
+	status,
+	created_by,
+	created_at,
+	updated_at,
+	stats_since,
+	last_batch_at,
+	completed_at
+)
+SELECT
+	'8b2f6d1c-4e7a-4c3b-9f5d-1a6e2c8b4d70',
+	templates.id,
+	templates.active_version_id,
+	10,
+	600000000000,
+	true,
+	0.2,
+	5,
+	'completed',
+	templates.created_by,
+	NOW(),
+	NOW(),
+	NOW(),
+	NOW(),
+	NOW()
+FROM
+	templates
+ORDER BY
+	templates.created_at, templates.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
+
+INSERT INTO template_workspace_restart_builds (
+	restart_id,
+	workspace_id,
+	workspace_build_id,
+	created_at
+)
+SELECT
+	'8b2f6d1c-4e7a-4c3b-9f5d-1a6e2c8b4d70',
+	workspace_builds.workspace_id,
+	workspace_builds.id,
+	NOW()
+FROM
+	workspace_builds
+JOIN
+	template_workspace_restarts ON template_workspace_restarts.id = '8b2f6d1c-4e7a-4c3b-9f5d-1a6e2c8b4d70'
+ORDER BY
+	workspace_builds.created_at, workspace_builds.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
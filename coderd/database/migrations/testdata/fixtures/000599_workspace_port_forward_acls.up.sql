@@ -0,0 +1,18 @@
+INSERT INTO workspace_port_forward_acls (
+	workspace_id,
+	user_id,
+	ports,
+	created_at,
+	updated_at
+)
+SELECT
+	workspaces.id,
+	users.id,
+	ARRAY[8080, 5432],
+	NOW(),
+	NOW()
+FROM
+	workspaces, users
+ORDER BY
+	workspaces.created_at, workspaces.id, users.created_at, users.id
+LIMIT 1;
@@ -0,0 +1,27 @@
+INSERT INTO idempotency_keys (
+	user_id,
+	operation,
+	key,
+	request_hash,
+	workspace_id,
+	workspace_build_id,
+	created_at,
+	expires_at
+)
+SELECT
+	workspaces.owner_id,
+	'create_workspace_build',
+	'fixture-key',
+	'\x00',
+	workspaces.id,
+	workspace_builds.id,
+	NOW(),
+	NOW() + INTERVAL '1 day'
+FROM
+	workspace_builds
+JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+ORDER BY
+	workspace_builds.created_at, workspace_builds.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
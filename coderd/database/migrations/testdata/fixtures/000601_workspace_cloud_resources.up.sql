@@ -0,0 +1,23 @@
+INSERT INTO workspace_cloud_resources (
+	workspace_id,
+	resource_type,
+	cloud_id,
+	resource_name,
+	workspace_build_id,
+	created_at,
+	updated_at
+)
+SELECT
+	workspace_builds.workspace_id,
+	'aws_instance',
+	'i-0123456789abcdef0',
+	'dev',
+	workspace_builds.id,
+	NOW(),
+	NOW()
+FROM
+	workspace_builds
+ORDER BY
+	workspace_builds.created_at, workspace_builds.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,23 @@
+INSERT INTO template_user_presets (
+	id,
+	template_id,
+	user_id,
+	name,
+	parameters,
+	created_at,
+	updated_at
+)
+SELECT
+	'6d3f9b5c-2e7a-4f1b-9c8d-0a1b2c3d4e5f',
+	templates.id,
+	templates.created_by,
+	'my usual config',
+	'[{"name": "region", "value": "us-east-1"}]'::jsonb,
+	NOW(),
+	NOW()
+FROM
+	templates
+ORDER BY
+	templates.created_at, templates.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
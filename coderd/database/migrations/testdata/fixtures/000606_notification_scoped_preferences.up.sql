@@ -0,0 +1,23 @@
+INSERT INTO notification_scoped_preferences (
+	user_id,
+	notification_template_id,
+	scope,
+	target_id,
+	disabled,
+	created_at,
+	updated_at
+)
+SELECT
+	workspaces.owner_id,
+	notification_templates.id,
+	'workspace',
+	workspaces.id,
+	TRUE,
+	NOW(),
+	NOW()
+FROM
+	workspaces, notification_templates
+ORDER BY
+	workspaces.created_at, workspaces.id, notification_templates.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
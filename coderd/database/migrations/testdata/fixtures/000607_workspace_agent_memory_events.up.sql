@@ -0,0 +1,21 @@
+INSERT INTO workspace_agent_memory_events (
+	id,
+	workspace_agent_id,
+	created_at,
+	kind,
+	oom_kills,
+	memory_pressure
+)
+SELECT
+	'5b0c1e6a-2f4d-4b8e-9c3a-7d1e6f2a8b40',
+	id,
+	NOW(),
+	'oom_kill',
+	2,
+	0
+FROM
+	workspace_agents
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
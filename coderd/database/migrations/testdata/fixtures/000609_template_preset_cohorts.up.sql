@@ -0,0 +1,19 @@
+INSERT INTO template_preset_cohorts (
+	template_id,
+	preset_name,
+	group_ids,
+	roles,
+	created_at
+)
+SELECT
+	templates.id,
+	'ML team',
+	ARRAY[templates.organization_id]::uuid[],
+	ARRAY['template-admin']::text[],
+	NOW()
+FROM
+	templates
+ORDER BY
+	templates.created_at, templates.id
+LIMIT 1
+ON CONFLICT DO NOTHING;
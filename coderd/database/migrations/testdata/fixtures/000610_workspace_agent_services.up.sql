@@ -0,0 +1,45 @@
+INSERT INTO workspace_agent_services (
+	workspace_agent_id,
+	name,
+	display_name,
+	command,
+	restart_policy,
+	created_at
+)
+SELECT
+	workspace_agents.id,
+	'dev-server',
+	'Dev Server',
+	'npm run dev',
+	'on_failure',
+	NOW()
+FROM
+	workspace_agents
+ORDER BY
+	workspace_agents.created_at, workspace_agents.id
+LIMIT 1;
+
+INSERT INTO workspace_agent_service_transitions (
+	id,
+	workspace_agent_id,
+	service_name,
+	created_at,
+	status,
+	message,
+	exit_code,
+	restart_count
+)
+SELECT
+	'5e1f3c2a-8b4d-4f6e-9a7c-1d2b3e4f5a6b',
+	workspace_agent_id,
+	name,
+	NOW(),
+	'running',
+	'',
+	NULL,
+	0
+FROM
+	workspace_agent_services
+ORDER BY
+	created_at, workspace_agent_id, name
+LIMIT 1;
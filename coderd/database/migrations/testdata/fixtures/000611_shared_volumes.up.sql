@@ -0,0 +1,57 @@
+INSERT INTO shared_volumes (
+	id,
+	organization_id,
+	name,
+	display_name,
+	description,
+	template_id,
+	created_at,
+	updated_at
+)
+SELECT
+	'8c2d4e6f-1a3b-4c5d-9e7f-2b4d6f8a0c1e',
+	organizations.id,
+	'datasets',
+	'Datasets',
+	'Shared training data.',
+	NULL,
+	NOW(),
+	NOW()
+FROM
+	organizations
+ORDER BY
+	organizations.created_at, organizations.id
+LIMIT 1;
+
+INSERT INTO shared_volume_attachments (
+	id,
+	shared_volume_id,
+	workspace_id,
+	workspace_build_id,
+	workspace_agent_id,
+	mount_path,
+	access_mode,
+	attached_at,
+	detached_at,
+	detached_by
+)
+SELECT
+	'3f5a7b9c-2d4e-4f6a-8b0c-1d3e5f7a9b2c',
+	'8c2d4e6f-1a3b-4c5d-9e7f-2b4d6f8a0c1e',
+	workspace_builds.workspace_id,
+	workspace_builds.id,
+	workspace_agents.id,
+	'/mnt/datasets',
+	'read_only',
+	NOW(),
+	NULL,
+	NULL
+FROM
+	workspace_agents
+	JOIN workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+	JOIN workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+WHERE
+	EXISTS (SELECT 1 FROM shared_volumes WHERE id = '8c2d4e6f-1a3b-4c5d-9e7f-2b4d6f8a0c1e')
+ORDER BY
+	workspace_agents.created_at, workspace_agents.id
+LIMIT 1;
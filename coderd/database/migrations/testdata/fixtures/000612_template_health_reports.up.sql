@@ -0,0 +1,31 @@
+INSERT INTO template_health_reports (
+	id,
+	template_id,
+	period_start,
+	period_end,
+	total_builds,
+	failed_builds,
+	average_start_seconds,
+	total_workspaces,
+	outdated_workspaces,
+	dormant_workspaces,
+	created_at
+)
+SELECT
+	'd2f6a8b1-7c3e-4e59-8a14-0b9c5d7e3f62',
+	id,
+	NOW() - INTERVAL '7 days',
+	NOW(),
+	55,
+	4,
+	42.5,
+	20,
+	3,
+	1,
+	NOW()
+FROM
+	templates
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,21 @@
+INSERT INTO user_workspace_reports (
+	id,
+	user_id,
+	period_start,
+	period_end,
+	summary,
+	created_at
+)
+SELECT
+	'a4c8e2f6-1b3d-4f7a-9c5e-2d6b8f0a3c71',
+	id,
+	NOW() - INTERVAL '7 days',
+	NOW(),
+	'{"total_builds": 3, "failed_builds": 1, "workspaces": []}'::jsonb,
+	NOW()
+FROM
+	users
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
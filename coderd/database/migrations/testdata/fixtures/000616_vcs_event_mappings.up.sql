@@ -0,0 +1,33 @@
+INSERT INTO vcs_event_mappings (
+	id,
+	template_id,
+	name,
+	repository,
+	branch,
+	action,
+	workspace_id,
+	signing_secret,
+	created_by,
+	created_at,
+	updated_at,
+	last_triggered_at
+)
+SELECT
+	'3c8f1a6e-9d2b-4e7a-b5c4-6a1d8e2f9b07',
+	id,
+	'refresh-prebuilds-on-main',
+	'coder/coder',
+	'main',
+	'refresh_prebuilds',
+	NULL,
+	'webhook-secret',
+	created_by,
+	NOW(),
+	NOW(),
+	NULL
+FROM
+	templates
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,19 @@
+INSERT INTO user_schedule_pauses (
+	user_id,
+	paused_until,
+	pause_autostop,
+	created_at,
+	updated_at
+)
+SELECT
+	id,
+	NOW() + INTERVAL '14 days',
+	false,
+	NOW(),
+	NOW()
+FROM
+	users
+ORDER BY
+	created_at, id
+LIMIT 1
+ON CONFLICT DO NOTHING;
@@ -383,6 +383,12 @@ func (k APIKey) ScopeSet() APIKeyScopeSet {
 	}
 }
 
+// AllowsTemplate returns true if the key may operate on workspaces built from
+// the given template. Keys without template restrictions allow every template.
+func (k APIKey) AllowsTemplate(templateID uuid.UUID) bool {
+	return len(k.TemplateIDs) == 0 || slices.Contains(k.TemplateIDs, templateID)
+}
+
 func (k APIKey) RBACObject() rbac.Object {
 	return rbac.ResourceApiKey.WithIDString(k.ID).
 		WithOwner(k.UserID.String())
@@ -395,6 +401,12 @@ func (t Template) RBACObject() rbac.Object {
 		WithGroupACL(t.GroupACL)
 }
 
+// RBACObject for a template user preset is the personal data of its user, so
+// it is private to the user.
+func (p TemplateUserPreset) RBACObject() rbac.Object {
+	return rbac.ResourceUserObject(p.UserID)
+}
+
 func (t GetFileTemplatesRow) RBACObject() rbac.Object {
 	return rbac.ResourceTemplate.WithID(t.TemplateID).
 		InOrg(t.TemplateOrganizationID).
@@ -506,6 +518,9 @@ func (w Workspace) WorkspaceTable() WorkspaceTable {
 		NextStartAt:       w.NextStartAt,
 		GroupACL:          w.GroupACL,
 		UserACL:           w.UserACL,
+		ExpiresAt:         w.ExpiresAt,
+		ArchivedAt:        w.ArchivedAt,
+		ReadOnlyAt:        w.ReadOnlyAt,
 	}
 }
 
@@ -795,6 +810,9 @@ func ConvertWorkspaceRows(rows []GetWorkspacesRow) ([]Workspace, error) {
 			TemplateDisplayName:     r.TemplateDisplayName,
 			TemplateIcon:            r.TemplateIcon,
 			TemplateDescription:     r.TemplateDescription,
+			ExpiresAt:               r.ExpiresAt,
+			ArchivedAt:              r.ArchivedAt,
+			ReadOnlyAt:              r.ReadOnlyAt,
 			NextStartAt:             r.NextStartAt,
 			TaskID:                  r.TaskID,
 		}
@@ -1002,6 +1020,11 @@ func (r GetWorkspaceBuildAgentsByInstanceIDRow) RBACObject() rbac.Object {
 	return r.WorkspaceTable.RBACObject()
 }
 
+// An orphaned workspace still belongs to its former owner until it is adopted.
+func (r GetOrphanedWorkspacesRow) RBACObject() rbac.Object {
+	return r.WorkspaceTable.RBACObject()
+}
+
 // UpsertConnectionLogParams contains the parameters for upserting a
 // connection log entry. This struct is hand-maintained (not generated
 // by sqlc) because the single-row UpsertConnectionLog query was
@@ -130,6 +130,27 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.CorsBehavior,
 			&i.DisableModuleCache,
 			&i.TimeTilAutostopNotify,
+			&i.ProvisionerPlanTimeout,
+			&i.ProvisionerApplyTimeout,
+			&i.TrialWorkspaceTTL,
+			&i.RequeueReapedBuilds,
+			&i.AgentRolloutChannel,
+			&i.AllowTargetedBuilds,
+			pq.Array(&i.ReconfirmParameters),
+			&i.DeprecationCutoff,
+			&i.NightlyStopTime,
+			&i.BuildLogRetention,
+			&i.MaxLifetime,
+			&i.MaxLifetimeAction,
+			&i.IdleReclaimTTL,
+			&i.IdleReclaimResourceSelector,
+			pq.Array(&i.ActivityBumpConnectionTypes),
+			&i.ActivityBumpMaxPerDay,
+			&i.PreBuildHookURL,
+			&i.PostBuildHookURL,
+			&i.AutoAssignRegion,
+			&i.NotifyBuildFailures,
+			&i.DisableProviderCache,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
@@ -275,12 +296,17 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 		arg.Dormant,
 		arg.LastUsedBefore,
 		arg.LastUsedAfter,
+		arg.CreatedBefore,
+		arg.CreatedAfter,
+		arg.TemplateVersionID,
+		arg.TemplateVersionName,
 		arg.UsingActive,
 		arg.HasAITask,
 		arg.HasExternalAgent,
 		arg.Shared,
 		arg.SharedWithUserID,
 		arg.SharedWithGroupID,
+		arg.Sort,
 		arg.RequesterID,
 		arg.Offset,
 		arg.Limit,
@@ -312,6 +338,9 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 			&i.NextStartAt,
 			&i.GroupACL,
 			&i.UserACL,
+			&i.ExpiresAt,
+			&i.ArchivedAt,
+			&i.ReadOnlyAt,
 			&i.OwnerAvatarUrl,
 			&i.OwnerUsername,
 			&i.OwnerName,
@@ -504,6 +533,7 @@ func (q *sqlQuerier) GetAuthorizedAuditLogsOffset(ctx context.Context, arg GetAu
 		arg.DateTo,
 		arg.BuildReason,
 		arg.RequestID,
+		arg.CorrelationID,
 		arg.OffsetOpt,
 		arg.LimitOpt,
 	)
@@ -530,6 +560,7 @@ func (q *sqlQuerier) GetAuthorizedAuditLogsOffset(ctx context.Context, arg GetAu
 			&i.AuditLog.AdditionalFields,
 			&i.AuditLog.RequestID,
 			&i.AuditLog.ResourceIcon,
+			&i.AuditLog.CorrelationID,
 			&i.UserUsername,
 			&i.UserName,
 			&i.UserEmail,
@@ -587,6 +618,7 @@ func (q *sqlQuerier) CountAuthorizedAuditLogs(ctx context.Context, arg CountAudi
 		arg.DateTo,
 		arg.BuildReason,
 		arg.RequestID,
+		arg.CorrelationID,
 		arg.CountCap,
 	)
 	if err != nil {
@@ -465,6 +465,9 @@ const (
 	ApiKeyScopeWorkspaceBuildOrchestrationDelete   APIKeyScope = "workspace_build_orchestration:delete"
 	ApiKeyScopeWorkspaceBuildOrchestrationRead     APIKeyScope = "workspace_build_orchestration:read"
 	ApiKeyScopeWorkspaceBuildOrchestrationUpdate   APIKeyScope = "workspace_build_orchestration:update"
+	ApiKeyScopeWorkspaceExtend                     APIKeyScope = "workspace:extend"
+	ApiKeyScopeWorkspaceDormantExtend              APIKeyScope = "workspace_dormant:extend"
+	ApiKeyScopeUserImpersonate                     APIKeyScope = "user:impersonate"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -739,7 +742,10 @@ func (e APIKeyScope) Valid() bool {
 		ApiKeyScopeWorkspaceBuildOrchestrationCreate,
 		ApiKeyScopeWorkspaceBuildOrchestrationDelete,
 		ApiKeyScopeWorkspaceBuildOrchestrationRead,
-		ApiKeyScopeWorkspaceBuildOrchestrationUpdate:
+		ApiKeyScopeWorkspaceBuildOrchestrationUpdate,
+		ApiKeyScopeWorkspaceExtend,
+		ApiKeyScopeWorkspaceDormantExtend,
+		ApiKeyScopeUserImpersonate:
 		return true
 	}
 	return false
@@ -983,6 +989,9 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 		ApiKeyScopeWorkspaceBuildOrchestrationDelete,
 		ApiKeyScopeWorkspaceBuildOrchestrationRead,
 		ApiKeyScopeWorkspaceBuildOrchestrationUpdate,
+		ApiKeyScopeWorkspaceExtend,
+		ApiKeyScopeWorkspaceDormantExtend,
+		ApiKeyScopeUserImpersonate,
 	}
 }
 
@@ -1044,6 +1053,64 @@ func AllAgentKeyScopeEnumValues() []AgentKeyScopeEnum {
 	}
 }
 
+type AgentRolloutChannel string
+
+const (
+	AgentRolloutChannelStable AgentRolloutChannel = "stable"
+	AgentRolloutChannelBeta   AgentRolloutChannel = "beta"
+)
+
+func (e *AgentRolloutChannel) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AgentRolloutChannel(s)
+	case string:
+		*e = AgentRolloutChannel(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AgentRolloutChannel: %T", src)
+	}
+	return nil
+}
+
+type NullAgentRolloutChannel struct {
+	AgentRolloutChannel AgentRolloutChannel `json:"agent_rollout_channel"`
+	Valid               bool                `json:"valid"` // Valid is true if AgentRolloutChannel is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAgentRolloutChannel) Scan(value interface{}) error {
+	if value == nil {
+		ns.AgentRolloutChannel, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AgentRolloutChannel.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAgentRolloutChannel) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AgentRolloutChannel), nil
+}
+
+func (e AgentRolloutChannel) Valid() bool {
+	switch e {
+	case AgentRolloutChannelStable,
+		AgentRolloutChannelBeta:
+		return true
+	}
+	return false
+}
+
+func AllAgentRolloutChannelValues() []AgentRolloutChannel {
+	return []AgentRolloutChannel{
+		AgentRolloutChannelStable,
+		AgentRolloutChannelBeta,
+	}
+}
+
 type AppSharingLevel string
 
 const (
@@ -1125,6 +1192,9 @@ const (
 	AuditActionDisconnect           AuditAction = "disconnect"
 	AuditActionOpen                 AuditAction = "open"
 	AuditActionClose                AuditAction = "close"
+	AuditActionUpload               AuditAction = "upload"
+	AuditActionDownload             AuditAction = "download"
+	AuditActionImpersonate          AuditAction = "impersonate"
 )
 
 func (e *AuditAction) Scan(src interface{}) error {
@@ -1176,7 +1246,10 @@ func (e AuditAction) Valid() bool {
 		AuditActionConnect,
 		AuditActionDisconnect,
 		AuditActionOpen,
-		AuditActionClose:
+		AuditActionClose,
+		AuditActionUpload,
+		AuditActionDownload,
+		AuditActionImpersonate:
 		return true
 	}
 	return false
@@ -1197,6 +1270,9 @@ func AllAuditActionValues() []AuditAction {
 		AuditActionDisconnect,
 		AuditActionOpen,
 		AuditActionClose,
+		AuditActionUpload,
+		AuditActionDownload,
+		AuditActionImpersonate,
 	}
 }
 
@@ -1275,6 +1351,10 @@ const (
 	BuildReasonTaskAutoPause       BuildReason = "task_auto_pause"
 	BuildReasonTaskManualPause     BuildReason = "task_manual_pause"
 	BuildReasonTaskResume          BuildReason = "task_resume"
+	BuildReasonBudgetExceeded      BuildReason = "budget_exceeded"
+	BuildReasonArchive             BuildReason = "archive"
+	BuildReasonUnarchive           BuildReason = "unarchive"
+	BuildReasonSelfRequested       BuildReason = "self_requested"
 )
 
 func (e *BuildReason) Scan(src interface{}) error {
@@ -1327,7 +1407,11 @@ func (e BuildReason) Valid() bool {
 		BuildReasonJetbrainsConnection,
 		BuildReasonTaskAutoPause,
 		BuildReasonTaskManualPause,
-		BuildReasonTaskResume:
+		BuildReasonTaskResume,
+		BuildReasonBudgetExceeded,
+		BuildReasonArchive,
+		BuildReasonUnarchive,
+		BuildReasonSelfRequested:
 		return true
 	}
 	return false
@@ -1349,6 +1433,10 @@ func AllBuildReasonValues() []BuildReason {
 		BuildReasonTaskAutoPause,
 		BuildReasonTaskManualPause,
 		BuildReasonTaskResume,
+		BuildReasonBudgetExceeded,
+		BuildReasonArchive,
+		BuildReasonUnarchive,
+		BuildReasonSelfRequested,
 	}
 }
 
@@ -1788,6 +1876,70 @@ func AllChatStatusValues() []ChatStatus {
 	}
 }
 
+type CloudResourceVerificationStatus string
+
+const (
+	CloudResourceVerificationStatusUnverified CloudResourceVerificationStatus = "unverified"
+	CloudResourceVerificationStatusDeleted    CloudResourceVerificationStatus = "deleted"
+	CloudResourceVerificationStatusLeaked     CloudResourceVerificationStatus = "leaked"
+	CloudResourceVerificationStatusUnknown    CloudResourceVerificationStatus = "unknown"
+)
+
+func (e *CloudResourceVerificationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CloudResourceVerificationStatus(s)
+	case string:
+		*e = CloudResourceVerificationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CloudResourceVerificationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullCloudResourceVerificationStatus struct {
+	CloudResourceVerificationStatus CloudResourceVerificationStatus `json:"cloud_resource_verification_status"`
+	Valid                           bool                            `json:"valid"` // Valid is true if CloudResourceVerificationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCloudResourceVerificationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.CloudResourceVerificationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CloudResourceVerificationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCloudResourceVerificationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CloudResourceVerificationStatus), nil
+}
+
+func (e CloudResourceVerificationStatus) Valid() bool {
+	switch e {
+	case CloudResourceVerificationStatusUnverified,
+		CloudResourceVerificationStatusDeleted,
+		CloudResourceVerificationStatusLeaked,
+		CloudResourceVerificationStatusUnknown:
+		return true
+	}
+	return false
+}
+
+func AllCloudResourceVerificationStatusValues() []CloudResourceVerificationStatus {
+	return []CloudResourceVerificationStatus{
+		CloudResourceVerificationStatusUnverified,
+		CloudResourceVerificationStatusDeleted,
+		CloudResourceVerificationStatusLeaked,
+		CloudResourceVerificationStatusUnknown,
+	}
+}
+
 type ConnectionStatus string
 
 const (
@@ -2040,6 +2192,7 @@ const (
 	CryptoKeyFeatureOIDCConvert         CryptoKeyFeature = "oidc_convert"
 	CryptoKeyFeatureTailnetResume       CryptoKeyFeature = "tailnet_resume"
 	CryptoKeyFeatureNATSCA              CryptoKeyFeature = "nats_ca"
+	CryptoKeyFeatureSSHHostCA           CryptoKeyFeature = "ssh_host_ca"
 )
 
 func (e *CryptoKeyFeature) Scan(src interface{}) error {
@@ -2083,7 +2236,8 @@ func (e CryptoKeyFeature) Valid() bool {
 		CryptoKeyFeatureWorkspaceAppsAPIKey,
 		CryptoKeyFeatureOIDCConvert,
 		CryptoKeyFeatureTailnetResume,
-		CryptoKeyFeatureNATSCA:
+		CryptoKeyFeatureNATSCA,
+		CryptoKeyFeatureSSHHostCA:
 		return true
 	}
 	return false
@@ -2096,6 +2250,7 @@ func AllCryptoKeyFeatureValues() []CryptoKeyFeature {
 		CryptoKeyFeatureOIDCConvert,
 		CryptoKeyFeatureTailnetResume,
 		CryptoKeyFeatureNATSCA,
+		CryptoKeyFeatureSSHHostCA,
 	}
 }
 
@@ -2224,6 +2379,64 @@ func AllGroupSourceValues() []GroupSource {
 	}
 }
 
+type IdempotencyKeyOperation string
+
+const (
+	IdempotencyKeyOperationCreateWorkspace      IdempotencyKeyOperation = "create_workspace"
+	IdempotencyKeyOperationCreateWorkspaceBuild IdempotencyKeyOperation = "create_workspace_build"
+)
+
+func (e *IdempotencyKeyOperation) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = IdempotencyKeyOperation(s)
+	case string:
+		*e = IdempotencyKeyOperation(s)
+	default:
+		return fmt.Errorf("unsupported scan type for IdempotencyKeyOperation: %T", src)
+	}
+	return nil
+}
+
+type NullIdempotencyKeyOperation struct {
+	IdempotencyKeyOperation IdempotencyKeyOperation `json:"idempotency_key_operation"`
+	Valid                   bool                    `json:"valid"` // Valid is true if IdempotencyKeyOperation is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullIdempotencyKeyOperation) Scan(value interface{}) error {
+	if value == nil {
+		ns.IdempotencyKeyOperation, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.IdempotencyKeyOperation.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullIdempotencyKeyOperation) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.IdempotencyKeyOperation), nil
+}
+
+func (e IdempotencyKeyOperation) Valid() bool {
+	switch e {
+	case IdempotencyKeyOperationCreateWorkspace,
+		IdempotencyKeyOperationCreateWorkspaceBuild:
+		return true
+	}
+	return false
+}
+
+func AllIdempotencyKeyOperationValues() []IdempotencyKeyOperation {
+	return []IdempotencyKeyOperation{
+		IdempotencyKeyOperationCreateWorkspace,
+		IdempotencyKeyOperationCreateWorkspaceBuild,
+	}
+}
+
 type InboxNotificationReadStatus string
 
 const (
@@ -2481,6 +2694,64 @@ func AllLoginTypeValues() []LoginType {
 	}
 }
 
+type MaxLifetimeAction string
+
+const (
+	MaxLifetimeActionDelete MaxLifetimeAction = "delete"
+	MaxLifetimeActionStop   MaxLifetimeAction = "stop"
+)
+
+func (e *MaxLifetimeAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MaxLifetimeAction(s)
+	case string:
+		*e = MaxLifetimeAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MaxLifetimeAction: %T", src)
+	}
+	return nil
+}
+
+type NullMaxLifetimeAction struct {
+	MaxLifetimeAction MaxLifetimeAction `json:"max_lifetime_action"`
+	Valid             bool              `json:"valid"` // Valid is true if MaxLifetimeAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMaxLifetimeAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.MaxLifetimeAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MaxLifetimeAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMaxLifetimeAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MaxLifetimeAction), nil
+}
+
+func (e MaxLifetimeAction) Valid() bool {
+	switch e {
+	case MaxLifetimeActionDelete,
+		MaxLifetimeActionStop:
+		return true
+	}
+	return false
+}
+
+func AllMaxLifetimeActionValues() []MaxLifetimeAction {
+	return []MaxLifetimeAction{
+		MaxLifetimeActionDelete,
+		MaxLifetimeActionStop,
+	}
+}
+
 type NotificationMessageStatus string
 
 const (
@@ -2615,6 +2886,64 @@ func AllNotificationMethodValues() []NotificationMethod {
 	}
 }
 
+type NotificationPreferenceScope string
+
+const (
+	NotificationPreferenceScopeWorkspace NotificationPreferenceScope = "workspace"
+	NotificationPreferenceScopeTemplate  NotificationPreferenceScope = "template"
+)
+
+func (e *NotificationPreferenceScope) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationPreferenceScope(s)
+	case string:
+		*e = NotificationPreferenceScope(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationPreferenceScope: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationPreferenceScope struct {
+	NotificationPreferenceScope NotificationPreferenceScope `json:"notification_preference_scope"`
+	Valid                       bool                        `json:"valid"` // Valid is true if NotificationPreferenceScope is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationPreferenceScope) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationPreferenceScope, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationPreferenceScope.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationPreferenceScope) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationPreferenceScope), nil
+}
+
+func (e NotificationPreferenceScope) Valid() bool {
+	switch e {
+	case NotificationPreferenceScopeWorkspace,
+		NotificationPreferenceScopeTemplate:
+		return true
+	}
+	return false
+}
+
+func AllNotificationPreferenceScopeValues() []NotificationPreferenceScope {
+	return []NotificationPreferenceScope{
+		NotificationPreferenceScopeWorkspace,
+		NotificationPreferenceScopeTemplate,
+	}
+}
+
 type NotificationTemplateKind string
 
 const (
@@ -3055,6 +3384,68 @@ func AllPortShareProtocolValues() []PortShareProtocol {
 	}
 }
 
+// Why no prebuilt workspace could be claimed: reconciliation_lag means prebuilt workspaces were still being provisioned, capacity means the pool was drained by claims, and failures means prebuilt workspaces of the preset failed to build.
+type PrebuildClaimMissReason string
+
+const (
+	PrebuildClaimMissReasonReconciliationLag PrebuildClaimMissReason = "reconciliation_lag"
+	PrebuildClaimMissReasonCapacity          PrebuildClaimMissReason = "capacity"
+	PrebuildClaimMissReasonFailures          PrebuildClaimMissReason = "failures"
+)
+
+func (e *PrebuildClaimMissReason) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PrebuildClaimMissReason(s)
+	case string:
+		*e = PrebuildClaimMissReason(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PrebuildClaimMissReason: %T", src)
+	}
+	return nil
+}
+
+type NullPrebuildClaimMissReason struct {
+	PrebuildClaimMissReason PrebuildClaimMissReason `json:"prebuild_claim_miss_reason"`
+	Valid                   bool                    `json:"valid"` // Valid is true if PrebuildClaimMissReason is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPrebuildClaimMissReason) Scan(value interface{}) error {
+	if value == nil {
+		ns.PrebuildClaimMissReason, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PrebuildClaimMissReason.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPrebuildClaimMissReason) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PrebuildClaimMissReason), nil
+}
+
+func (e PrebuildClaimMissReason) Valid() bool {
+	switch e {
+	case PrebuildClaimMissReasonReconciliationLag,
+		PrebuildClaimMissReasonCapacity,
+		PrebuildClaimMissReasonFailures:
+		return true
+	}
+	return false
+}
+
+func AllPrebuildClaimMissReasonValues() []PrebuildClaimMissReason {
+	return []PrebuildClaimMissReason{
+		PrebuildClaimMissReasonReconciliationLag,
+		PrebuildClaimMissReasonCapacity,
+		PrebuildClaimMissReasonFailures,
+	}
+}
+
 type PrebuildStatus string
 
 const (
@@ -3528,6 +3919,10 @@ const (
 	ResourceTypeUserSkill                   ResourceType = "user_skill"
 	ResourceTypeAIGatewayKey                ResourceType = "ai_gateway_key"
 	ResourceTypeUserAIBudgetOverride        ResourceType = "user_ai_budget_override"
+	ResourceTypeWorkspaceAppShareLink       ResourceType = "workspace_app_share_link"
+	ResourceTypeTemplateSecret              ResourceType = "template_secret"
+	ResourceTypeDeploymentBundle            ResourceType = "deployment_bundle"
+	ResourceTypeVCSEventMapping             ResourceType = "vcs_event_mapping"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -3601,7 +3996,11 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeGroupAIBudget,
 		ResourceTypeUserSkill,
 		ResourceTypeAIGatewayKey,
-		ResourceTypeUserAIBudgetOverride:
+		ResourceTypeUserAIBudgetOverride,
+		ResourceTypeWorkspaceAppShareLink,
+		ResourceTypeTemplateSecret,
+		ResourceTypeDeploymentBundle,
+		ResourceTypeVCSEventMapping:
 		return true
 	}
 	return false
@@ -3644,6 +4043,10 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeUserSkill,
 		ResourceTypeAIGatewayKey,
 		ResourceTypeUserAIBudgetOverride,
+		ResourceTypeWorkspaceAppShareLink,
+		ResourceTypeTemplateSecret,
+		ResourceTypeDeploymentBundle,
+		ResourceTypeVCSEventMapping,
 	}
 }
 
@@ -3708,79 +4111,137 @@ func AllShareableWorkspaceOwnersValues() []ShareableWorkspaceOwners {
 	}
 }
 
-type StartupScriptBehavior string
+type SharedVolumeAccessMode string
 
 const (
-	StartupScriptBehaviorBlocking    StartupScriptBehavior = "blocking"
-	StartupScriptBehaviorNonBlocking StartupScriptBehavior = "non-blocking"
+	SharedVolumeAccessModeReadOnly  SharedVolumeAccessMode = "read_only"
+	SharedVolumeAccessModeReadWrite SharedVolumeAccessMode = "read_write"
 )
 
-func (e *StartupScriptBehavior) Scan(src interface{}) error {
+func (e *SharedVolumeAccessMode) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = StartupScriptBehavior(s)
+		*e = SharedVolumeAccessMode(s)
 	case string:
-		*e = StartupScriptBehavior(s)
+		*e = SharedVolumeAccessMode(s)
 	default:
-		return fmt.Errorf("unsupported scan type for StartupScriptBehavior: %T", src)
+		return fmt.Errorf("unsupported scan type for SharedVolumeAccessMode: %T", src)
 	}
 	return nil
 }
 
-type NullStartupScriptBehavior struct {
-	StartupScriptBehavior StartupScriptBehavior `json:"startup_script_behavior"`
-	Valid                 bool                  `json:"valid"` // Valid is true if StartupScriptBehavior is not NULL
+type NullSharedVolumeAccessMode struct {
+	SharedVolumeAccessMode SharedVolumeAccessMode `json:"shared_volume_access_mode"`
+	Valid                  bool                   `json:"valid"` // Valid is true if SharedVolumeAccessMode is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullStartupScriptBehavior) Scan(value interface{}) error {
+func (ns *NullSharedVolumeAccessMode) Scan(value interface{}) error {
 	if value == nil {
-		ns.StartupScriptBehavior, ns.Valid = "", false
+		ns.SharedVolumeAccessMode, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.StartupScriptBehavior.Scan(value)
+	return ns.SharedVolumeAccessMode.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullStartupScriptBehavior) Value() (driver.Value, error) {
+func (ns NullSharedVolumeAccessMode) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.StartupScriptBehavior), nil
+	return string(ns.SharedVolumeAccessMode), nil
 }
 
-func (e StartupScriptBehavior) Valid() bool {
+func (e SharedVolumeAccessMode) Valid() bool {
 	switch e {
-	case StartupScriptBehaviorBlocking,
-		StartupScriptBehaviorNonBlocking:
+	case SharedVolumeAccessModeReadOnly,
+		SharedVolumeAccessModeReadWrite:
 		return true
 	}
 	return false
 }
 
-func AllStartupScriptBehaviorValues() []StartupScriptBehavior {
-	return []StartupScriptBehavior{
-		StartupScriptBehaviorBlocking,
-		StartupScriptBehaviorNonBlocking,
+func AllSharedVolumeAccessModeValues() []SharedVolumeAccessMode {
+	return []SharedVolumeAccessMode{
+		SharedVolumeAccessModeReadOnly,
+		SharedVolumeAccessModeReadWrite,
 	}
 }
 
-type TailnetStatus string
+type StartupScriptBehavior string
 
 const (
-	TailnetStatusOk   TailnetStatus = "ok"
-	TailnetStatusLost TailnetStatus = "lost"
+	StartupScriptBehaviorBlocking    StartupScriptBehavior = "blocking"
+	StartupScriptBehaviorNonBlocking StartupScriptBehavior = "non-blocking"
 )
 
-func (e *TailnetStatus) Scan(src interface{}) error {
+func (e *StartupScriptBehavior) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = TailnetStatus(s)
+		*e = StartupScriptBehavior(s)
 	case string:
-		*e = TailnetStatus(s)
+		*e = StartupScriptBehavior(s)
 	default:
-		return fmt.Errorf("unsupported scan type for TailnetStatus: %T", src)
+		return fmt.Errorf("unsupported scan type for StartupScriptBehavior: %T", src)
+	}
+	return nil
+}
+
+type NullStartupScriptBehavior struct {
+	StartupScriptBehavior StartupScriptBehavior `json:"startup_script_behavior"`
+	Valid                 bool                  `json:"valid"` // Valid is true if StartupScriptBehavior is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStartupScriptBehavior) Scan(value interface{}) error {
+	if value == nil {
+		ns.StartupScriptBehavior, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StartupScriptBehavior.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStartupScriptBehavior) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StartupScriptBehavior), nil
+}
+
+func (e StartupScriptBehavior) Valid() bool {
+	switch e {
+	case StartupScriptBehaviorBlocking,
+		StartupScriptBehaviorNonBlocking:
+		return true
+	}
+	return false
+}
+
+func AllStartupScriptBehaviorValues() []StartupScriptBehavior {
+	return []StartupScriptBehavior{
+		StartupScriptBehaviorBlocking,
+		StartupScriptBehaviorNonBlocking,
+	}
+}
+
+type TailnetStatus string
+
+const (
+	TailnetStatusOk   TailnetStatus = "ok"
+	TailnetStatusLost TailnetStatus = "lost"
+)
+
+func (e *TailnetStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TailnetStatus(s)
+	case string:
+		*e = TailnetStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TailnetStatus: %T", src)
 	}
 	return nil
 }
@@ -3894,6 +4355,195 @@ func AllTaskStatusValues() []TaskStatus {
 	}
 }
 
+type TemplateVersionRolloutStatus string
+
+const (
+	TemplateVersionRolloutStatusInProgress TemplateVersionRolloutStatus = "in_progress"
+	TemplateVersionRolloutStatusCompleted  TemplateVersionRolloutStatus = "completed"
+	TemplateVersionRolloutStatusRolledBack TemplateVersionRolloutStatus = "rolled_back"
+	TemplateVersionRolloutStatusAborted    TemplateVersionRolloutStatus = "aborted"
+)
+
+func (e *TemplateVersionRolloutStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateVersionRolloutStatus(s)
+	case string:
+		*e = TemplateVersionRolloutStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateVersionRolloutStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateVersionRolloutStatus struct {
+	TemplateVersionRolloutStatus TemplateVersionRolloutStatus `json:"template_version_rollout_status"`
+	Valid                        bool                         `json:"valid"` // Valid is true if TemplateVersionRolloutStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateVersionRolloutStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateVersionRolloutStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateVersionRolloutStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateVersionRolloutStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateVersionRolloutStatus), nil
+}
+
+func (e TemplateVersionRolloutStatus) Valid() bool {
+	switch e {
+	case TemplateVersionRolloutStatusInProgress,
+		TemplateVersionRolloutStatusCompleted,
+		TemplateVersionRolloutStatusRolledBack,
+		TemplateVersionRolloutStatusAborted:
+		return true
+	}
+	return false
+}
+
+func AllTemplateVersionRolloutStatusValues() []TemplateVersionRolloutStatus {
+	return []TemplateVersionRolloutStatus{
+		TemplateVersionRolloutStatusInProgress,
+		TemplateVersionRolloutStatusCompleted,
+		TemplateVersionRolloutStatusRolledBack,
+		TemplateVersionRolloutStatusAborted,
+	}
+}
+
+type TemplateVersionScanStatus string
+
+const (
+	TemplateVersionScanStatusRunning   TemplateVersionScanStatus = "running"
+	TemplateVersionScanStatusCompleted TemplateVersionScanStatus = "completed"
+	TemplateVersionScanStatusFailed    TemplateVersionScanStatus = "failed"
+)
+
+func (e *TemplateVersionScanStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateVersionScanStatus(s)
+	case string:
+		*e = TemplateVersionScanStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateVersionScanStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateVersionScanStatus struct {
+	TemplateVersionScanStatus TemplateVersionScanStatus `json:"template_version_scan_status"`
+	Valid                     bool                      `json:"valid"` // Valid is true if TemplateVersionScanStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateVersionScanStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateVersionScanStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateVersionScanStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateVersionScanStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateVersionScanStatus), nil
+}
+
+func (e TemplateVersionScanStatus) Valid() bool {
+	switch e {
+	case TemplateVersionScanStatusRunning,
+		TemplateVersionScanStatusCompleted,
+		TemplateVersionScanStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllTemplateVersionScanStatusValues() []TemplateVersionScanStatus {
+	return []TemplateVersionScanStatus{
+		TemplateVersionScanStatusRunning,
+		TemplateVersionScanStatusCompleted,
+		TemplateVersionScanStatusFailed,
+	}
+}
+
+type TemplateWorkspaceRestartStatus string
+
+const (
+	TemplateWorkspaceRestartStatusInProgress TemplateWorkspaceRestartStatus = "in_progress"
+	TemplateWorkspaceRestartStatusPaused     TemplateWorkspaceRestartStatus = "paused"
+	TemplateWorkspaceRestartStatusCompleted  TemplateWorkspaceRestartStatus = "completed"
+	TemplateWorkspaceRestartStatusAborted    TemplateWorkspaceRestartStatus = "aborted"
+)
+
+func (e *TemplateWorkspaceRestartStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateWorkspaceRestartStatus(s)
+	case string:
+		*e = TemplateWorkspaceRestartStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateWorkspaceRestartStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateWorkspaceRestartStatus struct {
+	TemplateWorkspaceRestartStatus TemplateWorkspaceRestartStatus `json:"template_workspace_restart_status"`
+	Valid                          bool                           `json:"valid"` // Valid is true if TemplateWorkspaceRestartStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateWorkspaceRestartStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateWorkspaceRestartStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateWorkspaceRestartStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateWorkspaceRestartStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateWorkspaceRestartStatus), nil
+}
+
+func (e TemplateWorkspaceRestartStatus) Valid() bool {
+	switch e {
+	case TemplateWorkspaceRestartStatusInProgress,
+		TemplateWorkspaceRestartStatusPaused,
+		TemplateWorkspaceRestartStatusCompleted,
+		TemplateWorkspaceRestartStatusAborted:
+		return true
+	}
+	return false
+}
+
+func AllTemplateWorkspaceRestartStatusValues() []TemplateWorkspaceRestartStatus {
+	return []TemplateWorkspaceRestartStatus{
+		TemplateWorkspaceRestartStatusInProgress,
+		TemplateWorkspaceRestartStatusPaused,
+		TemplateWorkspaceRestartStatusCompleted,
+		TemplateWorkspaceRestartStatusAborted,
+	}
+}
+
 // Defines the users status: active, dormant, or suspended.
 type UserStatus string
 
@@ -3956,6 +4606,67 @@ func AllUserStatusValues() []UserStatus {
 	}
 }
 
+type VCSEventAction string
+
+const (
+	VCSEventActionStartWorkspace   VCSEventAction = "start_workspace"
+	VCSEventActionRefreshPrebuilds VCSEventAction = "refresh_prebuilds"
+	VCSEventActionUpdateWorkspaces VCSEventAction = "update_workspaces"
+)
+
+func (e *VCSEventAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = VCSEventAction(s)
+	case string:
+		*e = VCSEventAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for VCSEventAction: %T", src)
+	}
+	return nil
+}
+
+type NullVCSEventAction struct {
+	VCSEventAction VCSEventAction `json:"vcs_event_action"`
+	Valid          bool           `json:"valid"` // Valid is true if VCSEventAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullVCSEventAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.VCSEventAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.VCSEventAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullVCSEventAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.VCSEventAction), nil
+}
+
+func (e VCSEventAction) Valid() bool {
+	switch e {
+	case VCSEventActionStartWorkspace,
+		VCSEventActionRefreshPrebuilds,
+		VCSEventActionUpdateWorkspaces:
+		return true
+	}
+	return false
+}
+
+func AllVCSEventActionValues() []VCSEventAction {
+	return []VCSEventAction{
+		VCSEventActionStartWorkspace,
+		VCSEventActionRefreshPrebuilds,
+		VCSEventActionUpdateWorkspaces,
+	}
+}
+
 type WorkspaceAgentContextBodyKind string
 
 const (
@@ -4178,6 +4889,64 @@ func AllWorkspaceAgentLifecycleStateValues() []WorkspaceAgentLifecycleState {
 	}
 }
 
+type WorkspaceAgentMemoryEventKind string
+
+const (
+	WorkspaceAgentMemoryEventKindOomKill        WorkspaceAgentMemoryEventKind = "oom_kill"
+	WorkspaceAgentMemoryEventKindMemoryPressure WorkspaceAgentMemoryEventKind = "memory_pressure"
+)
+
+func (e *WorkspaceAgentMemoryEventKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentMemoryEventKind(s)
+	case string:
+		*e = WorkspaceAgentMemoryEventKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentMemoryEventKind: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentMemoryEventKind struct {
+	WorkspaceAgentMemoryEventKind WorkspaceAgentMemoryEventKind `json:"workspace_agent_memory_event_kind"`
+	Valid                         bool                          `json:"valid"` // Valid is true if WorkspaceAgentMemoryEventKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentMemoryEventKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentMemoryEventKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentMemoryEventKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentMemoryEventKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentMemoryEventKind), nil
+}
+
+func (e WorkspaceAgentMemoryEventKind) Valid() bool {
+	switch e {
+	case WorkspaceAgentMemoryEventKindOomKill,
+		WorkspaceAgentMemoryEventKindMemoryPressure:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentMemoryEventKindValues() []WorkspaceAgentMemoryEventKind {
+	return []WorkspaceAgentMemoryEventKind{
+		WorkspaceAgentMemoryEventKindOomKill,
+		WorkspaceAgentMemoryEventKindMemoryPressure,
+	}
+}
+
 type WorkspaceAgentMonitorState string
 
 const (
@@ -4363,256 +5132,625 @@ func AllWorkspaceAgentScriptTimingStatusValues() []WorkspaceAgentScriptTimingSta
 	}
 }
 
-type WorkspaceAgentSubsystem string
+type WorkspaceAgentServiceRestartPolicy string
 
 const (
-	WorkspaceAgentSubsystemEnvbuilder WorkspaceAgentSubsystem = "envbuilder"
-	WorkspaceAgentSubsystemEnvbox     WorkspaceAgentSubsystem = "envbox"
-	WorkspaceAgentSubsystemNone       WorkspaceAgentSubsystem = "none"
-	WorkspaceAgentSubsystemExectrace  WorkspaceAgentSubsystem = "exectrace"
+	WorkspaceAgentServiceRestartPolicyAlways    WorkspaceAgentServiceRestartPolicy = "always"
+	WorkspaceAgentServiceRestartPolicyOnFailure WorkspaceAgentServiceRestartPolicy = "on_failure"
+	WorkspaceAgentServiceRestartPolicyNever     WorkspaceAgentServiceRestartPolicy = "never"
 )
 
-func (e *WorkspaceAgentSubsystem) Scan(src interface{}) error {
+func (e *WorkspaceAgentServiceRestartPolicy) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = WorkspaceAgentSubsystem(s)
+		*e = WorkspaceAgentServiceRestartPolicy(s)
 	case string:
-		*e = WorkspaceAgentSubsystem(s)
+		*e = WorkspaceAgentServiceRestartPolicy(s)
 	default:
-		return fmt.Errorf("unsupported scan type for WorkspaceAgentSubsystem: %T", src)
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentServiceRestartPolicy: %T", src)
 	}
 	return nil
 }
 
-type NullWorkspaceAgentSubsystem struct {
-	WorkspaceAgentSubsystem WorkspaceAgentSubsystem `json:"workspace_agent_subsystem"`
-	Valid                   bool                    `json:"valid"` // Valid is true if WorkspaceAgentSubsystem is not NULL
+type NullWorkspaceAgentServiceRestartPolicy struct {
+	WorkspaceAgentServiceRestartPolicy WorkspaceAgentServiceRestartPolicy `json:"workspace_agent_service_restart_policy"`
+	Valid                              bool                               `json:"valid"` // Valid is true if WorkspaceAgentServiceRestartPolicy is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullWorkspaceAgentSubsystem) Scan(value interface{}) error {
+func (ns *NullWorkspaceAgentServiceRestartPolicy) Scan(value interface{}) error {
 	if value == nil {
-		ns.WorkspaceAgentSubsystem, ns.Valid = "", false
+		ns.WorkspaceAgentServiceRestartPolicy, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.WorkspaceAgentSubsystem.Scan(value)
+	return ns.WorkspaceAgentServiceRestartPolicy.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullWorkspaceAgentSubsystem) Value() (driver.Value, error) {
+func (ns NullWorkspaceAgentServiceRestartPolicy) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.WorkspaceAgentSubsystem), nil
+	return string(ns.WorkspaceAgentServiceRestartPolicy), nil
+}
+
+func (e WorkspaceAgentServiceRestartPolicy) Valid() bool {
+	switch e {
+	case WorkspaceAgentServiceRestartPolicyAlways,
+		WorkspaceAgentServiceRestartPolicyOnFailure,
+		WorkspaceAgentServiceRestartPolicyNever:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentServiceRestartPolicyValues() []WorkspaceAgentServiceRestartPolicy {
+	return []WorkspaceAgentServiceRestartPolicy{
+		WorkspaceAgentServiceRestartPolicyAlways,
+		WorkspaceAgentServiceRestartPolicyOnFailure,
+		WorkspaceAgentServiceRestartPolicyNever,
+	}
+}
+
+type WorkspaceAgentServiceStatus string
+
+const (
+	WorkspaceAgentServiceStatusStarting   WorkspaceAgentServiceStatus = "starting"
+	WorkspaceAgentServiceStatusRunning    WorkspaceAgentServiceStatus = "running"
+	WorkspaceAgentServiceStatusRestarting WorkspaceAgentServiceStatus = "restarting"
+	WorkspaceAgentServiceStatusStopped    WorkspaceAgentServiceStatus = "stopped"
+	WorkspaceAgentServiceStatusFailed     WorkspaceAgentServiceStatus = "failed"
+)
+
+func (e *WorkspaceAgentServiceStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentServiceStatus(s)
+	case string:
+		*e = WorkspaceAgentServiceStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentServiceStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentServiceStatus struct {
+	WorkspaceAgentServiceStatus WorkspaceAgentServiceStatus `json:"workspace_agent_service_status"`
+	Valid                       bool                        `json:"valid"` // Valid is true if WorkspaceAgentServiceStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentServiceStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentServiceStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentServiceStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentServiceStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentServiceStatus), nil
+}
+
+func (e WorkspaceAgentServiceStatus) Valid() bool {
+	switch e {
+	case WorkspaceAgentServiceStatusStarting,
+		WorkspaceAgentServiceStatusRunning,
+		WorkspaceAgentServiceStatusRestarting,
+		WorkspaceAgentServiceStatusStopped,
+		WorkspaceAgentServiceStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentServiceStatusValues() []WorkspaceAgentServiceStatus {
+	return []WorkspaceAgentServiceStatus{
+		WorkspaceAgentServiceStatusStarting,
+		WorkspaceAgentServiceStatusRunning,
+		WorkspaceAgentServiceStatusRestarting,
+		WorkspaceAgentServiceStatusStopped,
+		WorkspaceAgentServiceStatusFailed,
+	}
+}
+
+type WorkspaceAgentSubsystem string
+
+const (
+	WorkspaceAgentSubsystemEnvbuilder WorkspaceAgentSubsystem = "envbuilder"
+	WorkspaceAgentSubsystemEnvbox     WorkspaceAgentSubsystem = "envbox"
+	WorkspaceAgentSubsystemNone       WorkspaceAgentSubsystem = "none"
+	WorkspaceAgentSubsystemExectrace  WorkspaceAgentSubsystem = "exectrace"
+)
+
+func (e *WorkspaceAgentSubsystem) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentSubsystem(s)
+	case string:
+		*e = WorkspaceAgentSubsystem(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentSubsystem: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentSubsystem struct {
+	WorkspaceAgentSubsystem WorkspaceAgentSubsystem `json:"workspace_agent_subsystem"`
+	Valid                   bool                    `json:"valid"` // Valid is true if WorkspaceAgentSubsystem is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentSubsystem) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentSubsystem, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentSubsystem.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentSubsystem) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentSubsystem), nil
+}
+
+func (e WorkspaceAgentSubsystem) Valid() bool {
+	switch e {
+	case WorkspaceAgentSubsystemEnvbuilder,
+		WorkspaceAgentSubsystemEnvbox,
+		WorkspaceAgentSubsystemNone,
+		WorkspaceAgentSubsystemExectrace:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentSubsystemValues() []WorkspaceAgentSubsystem {
+	return []WorkspaceAgentSubsystem{
+		WorkspaceAgentSubsystemEnvbuilder,
+		WorkspaceAgentSubsystemEnvbox,
+		WorkspaceAgentSubsystemNone,
+		WorkspaceAgentSubsystemExectrace,
+	}
+}
+
+type WorkspaceAppHealth string
+
+const (
+	WorkspaceAppHealthDisabled     WorkspaceAppHealth = "disabled"
+	WorkspaceAppHealthInitializing WorkspaceAppHealth = "initializing"
+	WorkspaceAppHealthHealthy      WorkspaceAppHealth = "healthy"
+	WorkspaceAppHealthUnhealthy    WorkspaceAppHealth = "unhealthy"
+)
+
+func (e *WorkspaceAppHealth) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAppHealth(s)
+	case string:
+		*e = WorkspaceAppHealth(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAppHealth: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAppHealth struct {
+	WorkspaceAppHealth WorkspaceAppHealth `json:"workspace_app_health"`
+	Valid              bool               `json:"valid"` // Valid is true if WorkspaceAppHealth is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAppHealth) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAppHealth, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAppHealth.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAppHealth) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAppHealth), nil
+}
+
+func (e WorkspaceAppHealth) Valid() bool {
+	switch e {
+	case WorkspaceAppHealthDisabled,
+		WorkspaceAppHealthInitializing,
+		WorkspaceAppHealthHealthy,
+		WorkspaceAppHealthUnhealthy:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAppHealthValues() []WorkspaceAppHealth {
+	return []WorkspaceAppHealth{
+		WorkspaceAppHealthDisabled,
+		WorkspaceAppHealthInitializing,
+		WorkspaceAppHealthHealthy,
+		WorkspaceAppHealthUnhealthy,
+	}
+}
+
+type WorkspaceAppOpenIn string
+
+const (
+	WorkspaceAppOpenInTab        WorkspaceAppOpenIn = "tab"
+	WorkspaceAppOpenInWindow     WorkspaceAppOpenIn = "window"
+	WorkspaceAppOpenInSlimWindow WorkspaceAppOpenIn = "slim-window"
+)
+
+func (e *WorkspaceAppOpenIn) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAppOpenIn(s)
+	case string:
+		*e = WorkspaceAppOpenIn(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAppOpenIn: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAppOpenIn struct {
+	WorkspaceAppOpenIn WorkspaceAppOpenIn `json:"workspace_app_open_in"`
+	Valid              bool               `json:"valid"` // Valid is true if WorkspaceAppOpenIn is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAppOpenIn) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAppOpenIn, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAppOpenIn.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAppOpenIn) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAppOpenIn), nil
+}
+
+func (e WorkspaceAppOpenIn) Valid() bool {
+	switch e {
+	case WorkspaceAppOpenInTab,
+		WorkspaceAppOpenInWindow,
+		WorkspaceAppOpenInSlimWindow:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAppOpenInValues() []WorkspaceAppOpenIn {
+	return []WorkspaceAppOpenIn{
+		WorkspaceAppOpenInTab,
+		WorkspaceAppOpenInWindow,
+		WorkspaceAppOpenInSlimWindow,
+	}
+}
+
+type WorkspaceAppStatusState string
+
+const (
+	WorkspaceAppStatusStateWorking  WorkspaceAppStatusState = "working"
+	WorkspaceAppStatusStateComplete WorkspaceAppStatusState = "complete"
+	WorkspaceAppStatusStateFailure  WorkspaceAppStatusState = "failure"
+	WorkspaceAppStatusStateIdle     WorkspaceAppStatusState = "idle"
+)
+
+func (e *WorkspaceAppStatusState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAppStatusState(s)
+	case string:
+		*e = WorkspaceAppStatusState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAppStatusState: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAppStatusState struct {
+	WorkspaceAppStatusState WorkspaceAppStatusState `json:"workspace_app_status_state"`
+	Valid                   bool                    `json:"valid"` // Valid is true if WorkspaceAppStatusState is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAppStatusState) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAppStatusState, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAppStatusState.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAppStatusState) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAppStatusState), nil
+}
+
+func (e WorkspaceAppStatusState) Valid() bool {
+	switch e {
+	case WorkspaceAppStatusStateWorking,
+		WorkspaceAppStatusStateComplete,
+		WorkspaceAppStatusStateFailure,
+		WorkspaceAppStatusStateIdle:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAppStatusStateValues() []WorkspaceAppStatusState {
+	return []WorkspaceAppStatusState{
+		WorkspaceAppStatusStateWorking,
+		WorkspaceAppStatusStateComplete,
+		WorkspaceAppStatusStateFailure,
+		WorkspaceAppStatusStateIdle,
+	}
+}
+
+type WorkspaceBuildParameterChangeKind string
+
+const (
+	WorkspaceBuildParameterChangeKindAdded    WorkspaceBuildParameterChangeKind = "added"
+	WorkspaceBuildParameterChangeKindRemoved  WorkspaceBuildParameterChangeKind = "removed"
+	WorkspaceBuildParameterChangeKindModified WorkspaceBuildParameterChangeKind = "modified"
+)
+
+func (e *WorkspaceBuildParameterChangeKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceBuildParameterChangeKind(s)
+	case string:
+		*e = WorkspaceBuildParameterChangeKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceBuildParameterChangeKind: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceBuildParameterChangeKind struct {
+	WorkspaceBuildParameterChangeKind WorkspaceBuildParameterChangeKind `json:"workspace_build_parameter_change_kind"`
+	Valid                             bool                              `json:"valid"` // Valid is true if WorkspaceBuildParameterChangeKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceBuildParameterChangeKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceBuildParameterChangeKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceBuildParameterChangeKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceBuildParameterChangeKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceBuildParameterChangeKind), nil
 }
 
-func (e WorkspaceAgentSubsystem) Valid() bool {
+func (e WorkspaceBuildParameterChangeKind) Valid() bool {
 	switch e {
-	case WorkspaceAgentSubsystemEnvbuilder,
-		WorkspaceAgentSubsystemEnvbox,
-		WorkspaceAgentSubsystemNone,
-		WorkspaceAgentSubsystemExectrace:
+	case WorkspaceBuildParameterChangeKindAdded,
+		WorkspaceBuildParameterChangeKindRemoved,
+		WorkspaceBuildParameterChangeKindModified:
 		return true
 	}
 	return false
 }
 
-func AllWorkspaceAgentSubsystemValues() []WorkspaceAgentSubsystem {
-	return []WorkspaceAgentSubsystem{
-		WorkspaceAgentSubsystemEnvbuilder,
-		WorkspaceAgentSubsystemEnvbox,
-		WorkspaceAgentSubsystemNone,
-		WorkspaceAgentSubsystemExectrace,
+func AllWorkspaceBuildParameterChangeKindValues() []WorkspaceBuildParameterChangeKind {
+	return []WorkspaceBuildParameterChangeKind{
+		WorkspaceBuildParameterChangeKindAdded,
+		WorkspaceBuildParameterChangeKindRemoved,
+		WorkspaceBuildParameterChangeKindModified,
 	}
 }
 
-type WorkspaceAppHealth string
+type WorkspaceCostSource string
 
 const (
-	WorkspaceAppHealthDisabled     WorkspaceAppHealth = "disabled"
-	WorkspaceAppHealthInitializing WorkspaceAppHealth = "initializing"
-	WorkspaceAppHealthHealthy      WorkspaceAppHealth = "healthy"
-	WorkspaceAppHealthUnhealthy    WorkspaceAppHealth = "unhealthy"
+	WorkspaceCostSourceEstimate      WorkspaceCostSource = "estimate"
+	WorkspaceCostSourceBillingExport WorkspaceCostSource = "billing_export"
 )
 
-func (e *WorkspaceAppHealth) Scan(src interface{}) error {
+func (e *WorkspaceCostSource) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = WorkspaceAppHealth(s)
+		*e = WorkspaceCostSource(s)
 	case string:
-		*e = WorkspaceAppHealth(s)
+		*e = WorkspaceCostSource(s)
 	default:
-		return fmt.Errorf("unsupported scan type for WorkspaceAppHealth: %T", src)
+		return fmt.Errorf("unsupported scan type for WorkspaceCostSource: %T", src)
 	}
 	return nil
 }
 
-type NullWorkspaceAppHealth struct {
-	WorkspaceAppHealth WorkspaceAppHealth `json:"workspace_app_health"`
-	Valid              bool               `json:"valid"` // Valid is true if WorkspaceAppHealth is not NULL
+type NullWorkspaceCostSource struct {
+	WorkspaceCostSource WorkspaceCostSource `json:"workspace_cost_source"`
+	Valid               bool                `json:"valid"` // Valid is true if WorkspaceCostSource is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullWorkspaceAppHealth) Scan(value interface{}) error {
+func (ns *NullWorkspaceCostSource) Scan(value interface{}) error {
 	if value == nil {
-		ns.WorkspaceAppHealth, ns.Valid = "", false
+		ns.WorkspaceCostSource, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.WorkspaceAppHealth.Scan(value)
+	return ns.WorkspaceCostSource.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullWorkspaceAppHealth) Value() (driver.Value, error) {
+func (ns NullWorkspaceCostSource) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.WorkspaceAppHealth), nil
+	return string(ns.WorkspaceCostSource), nil
 }
 
-func (e WorkspaceAppHealth) Valid() bool {
+func (e WorkspaceCostSource) Valid() bool {
 	switch e {
-	case WorkspaceAppHealthDisabled,
-		WorkspaceAppHealthInitializing,
-		WorkspaceAppHealthHealthy,
-		WorkspaceAppHealthUnhealthy:
+	case WorkspaceCostSourceEstimate,
+		WorkspaceCostSourceBillingExport:
 		return true
 	}
 	return false
 }
 
-func AllWorkspaceAppHealthValues() []WorkspaceAppHealth {
-	return []WorkspaceAppHealth{
-		WorkspaceAppHealthDisabled,
-		WorkspaceAppHealthInitializing,
-		WorkspaceAppHealthHealthy,
-		WorkspaceAppHealthUnhealthy,
+func AllWorkspaceCostSourceValues() []WorkspaceCostSource {
+	return []WorkspaceCostSource{
+		WorkspaceCostSourceEstimate,
+		WorkspaceCostSourceBillingExport,
 	}
 }
 
-type WorkspaceAppOpenIn string
+type WorkspaceDormancyHookEvent string
 
 const (
-	WorkspaceAppOpenInTab        WorkspaceAppOpenIn = "tab"
-	WorkspaceAppOpenInWindow     WorkspaceAppOpenIn = "window"
-	WorkspaceAppOpenInSlimWindow WorkspaceAppOpenIn = "slim-window"
+	WorkspaceDormancyHookEventDormant  WorkspaceDormancyHookEvent = "dormant"
+	WorkspaceDormancyHookEventActivate WorkspaceDormancyHookEvent = "activate"
 )
 
-func (e *WorkspaceAppOpenIn) Scan(src interface{}) error {
+func (e *WorkspaceDormancyHookEvent) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = WorkspaceAppOpenIn(s)
+		*e = WorkspaceDormancyHookEvent(s)
 	case string:
-		*e = WorkspaceAppOpenIn(s)
+		*e = WorkspaceDormancyHookEvent(s)
 	default:
-		return fmt.Errorf("unsupported scan type for WorkspaceAppOpenIn: %T", src)
+		return fmt.Errorf("unsupported scan type for WorkspaceDormancyHookEvent: %T", src)
 	}
 	return nil
 }
 
-type NullWorkspaceAppOpenIn struct {
-	WorkspaceAppOpenIn WorkspaceAppOpenIn `json:"workspace_app_open_in"`
-	Valid              bool               `json:"valid"` // Valid is true if WorkspaceAppOpenIn is not NULL
+type NullWorkspaceDormancyHookEvent struct {
+	WorkspaceDormancyHookEvent WorkspaceDormancyHookEvent `json:"workspace_dormancy_hook_event"`
+	Valid                      bool                       `json:"valid"` // Valid is true if WorkspaceDormancyHookEvent is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullWorkspaceAppOpenIn) Scan(value interface{}) error {
+func (ns *NullWorkspaceDormancyHookEvent) Scan(value interface{}) error {
 	if value == nil {
-		ns.WorkspaceAppOpenIn, ns.Valid = "", false
+		ns.WorkspaceDormancyHookEvent, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.WorkspaceAppOpenIn.Scan(value)
+	return ns.WorkspaceDormancyHookEvent.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullWorkspaceAppOpenIn) Value() (driver.Value, error) {
+func (ns NullWorkspaceDormancyHookEvent) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.WorkspaceAppOpenIn), nil
+	return string(ns.WorkspaceDormancyHookEvent), nil
 }
 
-func (e WorkspaceAppOpenIn) Valid() bool {
+func (e WorkspaceDormancyHookEvent) Valid() bool {
 	switch e {
-	case WorkspaceAppOpenInTab,
-		WorkspaceAppOpenInWindow,
-		WorkspaceAppOpenInSlimWindow:
+	case WorkspaceDormancyHookEventDormant,
+		WorkspaceDormancyHookEventActivate:
 		return true
 	}
 	return false
 }
 
-func AllWorkspaceAppOpenInValues() []WorkspaceAppOpenIn {
-	return []WorkspaceAppOpenIn{
-		WorkspaceAppOpenInTab,
-		WorkspaceAppOpenInWindow,
-		WorkspaceAppOpenInSlimWindow,
+func AllWorkspaceDormancyHookEventValues() []WorkspaceDormancyHookEvent {
+	return []WorkspaceDormancyHookEvent{
+		WorkspaceDormancyHookEventDormant,
+		WorkspaceDormancyHookEventActivate,
 	}
 }
 
-type WorkspaceAppStatusState string
+type WorkspaceDormancyHookStatus string
 
 const (
-	WorkspaceAppStatusStateWorking  WorkspaceAppStatusState = "working"
-	WorkspaceAppStatusStateComplete WorkspaceAppStatusState = "complete"
-	WorkspaceAppStatusStateFailure  WorkspaceAppStatusState = "failure"
-	WorkspaceAppStatusStateIdle     WorkspaceAppStatusState = "idle"
+	WorkspaceDormancyHookStatusPending   WorkspaceDormancyHookStatus = "pending"
+	WorkspaceDormancyHookStatusRunning   WorkspaceDormancyHookStatus = "running"
+	WorkspaceDormancyHookStatusSucceeded WorkspaceDormancyHookStatus = "succeeded"
+	WorkspaceDormancyHookStatusFailed    WorkspaceDormancyHookStatus = "failed"
 )
 
-func (e *WorkspaceAppStatusState) Scan(src interface{}) error {
+func (e *WorkspaceDormancyHookStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = WorkspaceAppStatusState(s)
+		*e = WorkspaceDormancyHookStatus(s)
 	case string:
-		*e = WorkspaceAppStatusState(s)
+		*e = WorkspaceDormancyHookStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for WorkspaceAppStatusState: %T", src)
+		return fmt.Errorf("unsupported scan type for WorkspaceDormancyHookStatus: %T", src)
 	}
 	return nil
 }
 
-type NullWorkspaceAppStatusState struct {
-	WorkspaceAppStatusState WorkspaceAppStatusState `json:"workspace_app_status_state"`
-	Valid                   bool                    `json:"valid"` // Valid is true if WorkspaceAppStatusState is not NULL
+type NullWorkspaceDormancyHookStatus struct {
+	WorkspaceDormancyHookStatus WorkspaceDormancyHookStatus `json:"workspace_dormancy_hook_status"`
+	Valid                       bool                        `json:"valid"` // Valid is true if WorkspaceDormancyHookStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullWorkspaceAppStatusState) Scan(value interface{}) error {
+func (ns *NullWorkspaceDormancyHookStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.WorkspaceAppStatusState, ns.Valid = "", false
+		ns.WorkspaceDormancyHookStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.WorkspaceAppStatusState.Scan(value)
+	return ns.WorkspaceDormancyHookStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullWorkspaceAppStatusState) Value() (driver.Value, error) {
+func (ns NullWorkspaceDormancyHookStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.WorkspaceAppStatusState), nil
+	return string(ns.WorkspaceDormancyHookStatus), nil
 }
 
-func (e WorkspaceAppStatusState) Valid() bool {
+func (e WorkspaceDormancyHookStatus) Valid() bool {
 	switch e {
-	case WorkspaceAppStatusStateWorking,
-		WorkspaceAppStatusStateComplete,
-		WorkspaceAppStatusStateFailure,
-		WorkspaceAppStatusStateIdle:
+	case WorkspaceDormancyHookStatusPending,
+		WorkspaceDormancyHookStatusRunning,
+		WorkspaceDormancyHookStatusSucceeded,
+		WorkspaceDormancyHookStatusFailed:
 		return true
 	}
 	return false
 }
 
-func AllWorkspaceAppStatusStateValues() []WorkspaceAppStatusState {
-	return []WorkspaceAppStatusState{
-		WorkspaceAppStatusStateWorking,
-		WorkspaceAppStatusStateComplete,
-		WorkspaceAppStatusStateFailure,
-		WorkspaceAppStatusStateIdle,
+func AllWorkspaceDormancyHookStatusValues() []WorkspaceDormancyHookStatus {
+	return []WorkspaceDormancyHookStatus{
+		WorkspaceDormancyHookStatusPending,
+		WorkspaceDormancyHookStatusRunning,
+		WorkspaceDormancyHookStatusSucceeded,
+		WorkspaceDormancyHookStatusFailed,
 	}
 }
 
@@ -4864,6 +6002,8 @@ type APIKey struct {
 	TokenName       string       `db:"token_name" json:"token_name"`
 	Scopes          APIKeyScopes `db:"scopes" json:"scopes"`
 	AllowList       AllowList    `db:"allow_list" json:"allow_list"`
+	// template_ids restricts workspace operations performed with the key to workspaces built from these templates. An empty array means the key is not restricted to any template.
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
 }
 
 type AuditLog struct {
@@ -4882,6 +6022,8 @@ type AuditLog struct {
 	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
 	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
+	// The workspace build the entry is related to. All audit entries caused by one build share this ID.
+	CorrelationID uuid.NullUUID `db:"correlation_id" json:"correlation_id"`
 }
 
 // Persisted boundary audit events. Each row is a single audit event processed by a Boundary proxy.
@@ -5380,6 +6522,20 @@ type GroupMemberTable struct {
 	GroupID uuid.UUID `db:"group_id" json:"group_id"`
 }
 
+// Idempotency keys of workspace and workspace build creation requests. A retried request with the same key returns the workspace or build created by the original request instead of creating another one.
+type IdempotencyKey struct {
+	// The user that initiated the request. Keys are scoped to the user.
+	UserID    uuid.UUID               `db:"user_id" json:"user_id"`
+	Operation IdempotencyKeyOperation `db:"operation" json:"operation"`
+	Key       string                  `db:"key" json:"key"`
+	// SHA-256 hash of the request, so a key cannot be reused for a different request.
+	RequestHash      []byte    `db:"request_hash" json:"request_hash"`
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt        time.Time `db:"expires_at" json:"expires_at"`
+}
+
 type InboxNotification struct {
 	ID         uuid.UUID       `db:"id" json:"id"`
 	UserID     uuid.UUID       `db:"user_id" json:"user_id"`
@@ -5494,6 +6650,18 @@ type NotificationReportGeneratorLog struct {
 	LastGeneratedAt        time.Time `db:"last_generated_at" json:"last_generated_at"`
 }
 
+// Notification preferences of users that only apply to messages about a single workspace or template. They take precedence over notification_preferences.
+type NotificationScopedPreference struct {
+	UserID                 uuid.UUID                   `db:"user_id" json:"user_id"`
+	NotificationTemplateID uuid.UUID                   `db:"notification_template_id" json:"notification_template_id"`
+	Scope                  NotificationPreferenceScope `db:"scope" json:"scope"`
+	// ID of the workspace or template, matched against the targets of enqueued messages.
+	TargetID  uuid.UUID `db:"target_id" json:"target_id"`
+	Disabled  bool      `db:"disabled" json:"disabled"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 // Templates from which to create notification messages.
 type NotificationTemplate struct {
 	ID            uuid.UUID      `db:"id" json:"id"`
@@ -5508,6 +6676,18 @@ type NotificationTemplate struct {
 	EnabledByDefault bool                     `db:"enabled_by_default" json:"enabled_by_default"`
 }
 
+// Overrides for the webhook delivery of the messages of a notification template.
+type NotificationTemplateWebhook struct {
+	NotificationTemplateID uuid.UUID `db:"notification_template_id" json:"notification_template_id"`
+	// Empty defers to the deployment-level webhook endpoint.
+	Endpoint string `db:"endpoint" json:"endpoint"`
+	// Go template rendering the request body. Empty sends the default JSON payload.
+	PayloadTemplate string `db:"payload_template" json:"payload_template"`
+	// Secret used to sign request bodies with HMAC-SHA256. Empty disables signing.
+	SigningSecret string    `db:"signing_secret" json:"signing_secret"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
 // A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.
 type OAuth2ProviderApp struct {
 	ID          uuid.UUID `db:"id" json:"id"`
@@ -5619,6 +6799,10 @@ type Organization struct {
 	ShareableWorkspaceOwners ShareableWorkspaceOwners `db:"shareable_workspace_owners" json:"shareable_workspace_owners"`
 	// Roles granted to every member of this organization at request time. The set is unioned into each member's effective roles when GetAuthorizationUserRoles runs, so changes propagate to all members on the next request. Deployments can use this column to revoke capabilities that would otherwise be considered normal organization member permissions.
 	DefaultOrgMemberRoles []string `db:"default_org_member_roles" json:"default_org_member_roles"`
+	// When set, provisioner daemons registered in this organization may only use tag keys prefixed with "<namespace>/", apart from the reserved scope and owner tags.
+	ProvisionerTagNamespace string `db:"provisioner_tag_namespace" json:"provisioner_tag_namespace"`
+	// Maximum number of provisioner daemons that may be connected to this organization at once. Zero means unlimited.
+	ProvisionerDaemonQuota int32 `db:"provisioner_daemon_quota" json:"provisioner_daemon_quota"`
 }
 
 type OrganizationMember struct {
@@ -5661,6 +6845,18 @@ type ParameterValue struct {
 	DestinationScheme ParameterDestinationScheme `db:"destination_scheme" json:"destination_scheme"`
 }
 
+// Attempts to claim a prebuilt workspace when a workspace is created with a preset that has prebuilds configured.
+type PrebuildClaimAttempt struct {
+	ID       uuid.UUID `db:"id" json:"id"`
+	PresetID uuid.UUID `db:"preset_id" json:"preset_id"`
+	// The claimed prebuilt workspace. NULL if no prebuilt workspace could be claimed.
+	WorkspaceID uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	Claimed     bool          `db:"claimed" json:"claimed"`
+	// Why no prebuilt workspace could be claimed. NULL if the claim succeeded.
+	MissReason NullPrebuildClaimMissReason `db:"miss_reason" json:"miss_reason"`
+	CreatedAt  time.Time                   `db:"created_at" json:"created_at"`
+}
+
 type ProvisionerDaemon struct {
 	ID           uuid.UUID         `db:"id" json:"id"`
 	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
@@ -5701,6 +6897,8 @@ type ProvisionerJob struct {
 	LogsLength int32 `db:"logs_length" json:"logs_length"`
 	// Whether the provisioner logs overflowed in length
 	LogsOverflowed bool `db:"logs_overflowed" json:"logs_overflowed"`
+	// Maximum duration of the job in nanoseconds, taken from its template when the job is acquired. 0 means the job is not subject to a timeout.
+	Timeout int64 `db:"timeout" json:"timeout"`
 }
 
 type ProvisionerJobLog struct {
@@ -5713,6 +6911,14 @@ type ProvisionerJobLog struct {
 	ID        int64     `db:"id" json:"id"`
 }
 
+// Provisioner jobs whose logs were removed from the database by the build log retention policy.
+type ProvisionerJobLogArchive struct {
+	JobID      uuid.UUID `db:"job_id" json:"job_id"`
+	ArchivedAt time.Time `db:"archived_at" json:"archived_at"`
+	// Key of the archived logs in the build log archive. Empty if the logs were deleted without being archived.
+	ObjectKey string `db:"object_key" json:"object_key"`
+}
+
 type ProvisionerJobStat struct {
 	JobID          uuid.UUID            `db:"job_id" json:"job_id"`
 	JobStatus      ProvisionerJobStatus `db:"job_status" json:"job_status"`
@@ -5747,6 +6953,12 @@ type ProvisionerKey struct {
 	Name           string    `db:"name" json:"name"`
 	HashedSecret   []byte    `db:"hashed_secret" json:"hashed_secret"`
 	Tags           StringMap `db:"tags" json:"tags"`
+	// The last time a provisioner daemon authenticated with the key. NULL if the key was never used.
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	// The hashed secret the key was rotated away from. It stays valid until previous_secret_expires_at, so that daemons can be moved to the new secret without downtime.
+	PreviousHashedSecret []byte `db:"previous_hashed_secret" json:"previous_hashed_secret"`
+	// The time the previous secret of the key stops being valid at. NULL if the key was never rotated.
+	PreviousSecretExpiresAt sql.NullTime `db:"previous_secret_expires_at" json:"previous_secret_expires_at"`
 }
 
 type Replica struct {
@@ -5769,6 +6981,34 @@ type Replica struct {
 	NATSPort int32 `db:"nats_port" json:"nats_port"`
 }
 
+// Persistent volumes that multiple workspaces of an organization can attach.
+type SharedVolume struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	DisplayName    string    `db:"display_name" json:"display_name"`
+	Description    string    `db:"description" json:"description"`
+	// The template that declared the volume on its first build, or NULL if the volume was declared for the organization.
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	CreatedAt  time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// Attachments of shared volumes to workspace agents. An attachment is active until it is detached by a later build of the workspace or by an administrator.
+type SharedVolumeAttachment struct {
+	ID               uuid.UUID              `db:"id" json:"id"`
+	SharedVolumeID   uuid.UUID              `db:"shared_volume_id" json:"shared_volume_id"`
+	WorkspaceID      uuid.UUID              `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID              `db:"workspace_build_id" json:"workspace_build_id"`
+	WorkspaceAgentID uuid.UUID              `db:"workspace_agent_id" json:"workspace_agent_id"`
+	MountPath        string                 `db:"mount_path" json:"mount_path"`
+	AccessMode       SharedVolumeAccessMode `db:"access_mode" json:"access_mode"`
+	AttachedAt       time.Time              `db:"attached_at" json:"attached_at"`
+	DetachedAt       sql.NullTime           `db:"detached_at" json:"detached_at"`
+	// The user that force-detached the volume, or NULL if it was detached by a build.
+	DetachedBy uuid.NullUUID `db:"detached_by" json:"detached_by"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -5870,44 +7110,65 @@ type TelemetryLock struct {
 
 // Joins in the display name information such as username, avatar, and organization name.
 type Template struct {
-	ID                            uuid.UUID       `db:"id" json:"id"`
-	CreatedAt                     time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt                     time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID                uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Deleted                       bool            `db:"deleted" json:"deleted"`
-	Name                          string          `db:"name" json:"name"`
-	Provisioner                   ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID               uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description                   string          `db:"description" json:"description"`
-	DefaultTTL                    int64           `db:"default_ttl" json:"default_ttl"`
-	CreatedBy                     uuid.UUID       `db:"created_by" json:"created_by"`
-	Icon                          string          `db:"icon" json:"icon"`
-	UserACL                       TemplateACL     `db:"user_acl" json:"user_acl"`
-	GroupACL                      TemplateACL     `db:"group_acl" json:"group_acl"`
-	DisplayName                   string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs  bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	AllowUserAutostart            bool            `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop             bool            `db:"allow_user_autostop" json:"allow_user_autostop"`
-	FailureTTL                    int64           `db:"failure_ttl" json:"failure_ttl"`
-	TimeTilDormant                int64           `db:"time_til_dormant" json:"time_til_dormant"`
-	TimeTilDormantAutoDelete      int64           `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
-	AutostopRequirementDaysOfWeek int16           `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
-	AutostopRequirementWeeks      int64           `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
-	AutostartBlockDaysOfWeek      int16           `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
-	RequireActiveVersion          bool            `db:"require_active_version" json:"require_active_version"`
-	Deprecated                    string          `db:"deprecated" json:"deprecated"`
-	ActivityBump                  int64           `db:"activity_bump" json:"activity_bump"`
-	MaxPortSharingLevel           AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
-	UseClassicParameterFlow       bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
-	CorsBehavior                  CorsBehavior    `db:"cors_behavior" json:"cors_behavior"`
-	DisableModuleCache            bool            `db:"disable_module_cache" json:"disable_module_cache"`
-	TimeTilAutostopNotify         int64           `db:"time_til_autostop_notify" json:"time_til_autostop_notify"`
-	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
-	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
-	CreatedByName                 string          `db:"created_by_name" json:"created_by_name"`
-	OrganizationName              string          `db:"organization_name" json:"organization_name"`
-	OrganizationDisplayName       string          `db:"organization_display_name" json:"organization_display_name"`
-	OrganizationIcon              string          `db:"organization_icon" json:"organization_icon"`
+	ID                            uuid.UUID           `db:"id" json:"id"`
+	CreatedAt                     time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt                     time.Time           `db:"updated_at" json:"updated_at"`
+	OrganizationID                uuid.UUID           `db:"organization_id" json:"organization_id"`
+	Deleted                       bool                `db:"deleted" json:"deleted"`
+	Name                          string              `db:"name" json:"name"`
+	Provisioner                   ProvisionerType     `db:"provisioner" json:"provisioner"`
+	ActiveVersionID               uuid.UUID           `db:"active_version_id" json:"active_version_id"`
+	Description                   string              `db:"description" json:"description"`
+	DefaultTTL                    int64               `db:"default_ttl" json:"default_ttl"`
+	CreatedBy                     uuid.UUID           `db:"created_by" json:"created_by"`
+	Icon                          string              `db:"icon" json:"icon"`
+	UserACL                       TemplateACL         `db:"user_acl" json:"user_acl"`
+	GroupACL                      TemplateACL         `db:"group_acl" json:"group_acl"`
+	DisplayName                   string              `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs  bool                `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	AllowUserAutostart            bool                `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop             bool                `db:"allow_user_autostop" json:"allow_user_autostop"`
+	FailureTTL                    int64               `db:"failure_ttl" json:"failure_ttl"`
+	TimeTilDormant                int64               `db:"time_til_dormant" json:"time_til_dormant"`
+	TimeTilDormantAutoDelete      int64               `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
+	AutostopRequirementDaysOfWeek int16               `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
+	AutostopRequirementWeeks      int64               `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	AutostartBlockDaysOfWeek      int16               `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
+	RequireActiveVersion          bool                `db:"require_active_version" json:"require_active_version"`
+	Deprecated                    string              `db:"deprecated" json:"deprecated"`
+	ActivityBump                  int64               `db:"activity_bump" json:"activity_bump"`
+	MaxPortSharingLevel           AppSharingLevel     `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	UseClassicParameterFlow       bool                `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	CorsBehavior                  CorsBehavior        `db:"cors_behavior" json:"cors_behavior"`
+	DisableModuleCache            bool                `db:"disable_module_cache" json:"disable_module_cache"`
+	TimeTilAutostopNotify         int64               `db:"time_til_autostop_notify" json:"time_til_autostop_notify"`
+	ProvisionerPlanTimeout        int64               `db:"provisioner_plan_timeout" json:"provisioner_plan_timeout"`
+	ProvisionerApplyTimeout       int64               `db:"provisioner_apply_timeout" json:"provisioner_apply_timeout"`
+	TrialWorkspaceTTL             int64               `db:"trial_workspace_ttl" json:"trial_workspace_ttl"`
+	RequeueReapedBuilds           bool                `db:"requeue_reaped_builds" json:"requeue_reaped_builds"`
+	AgentRolloutChannel           AgentRolloutChannel `db:"agent_rollout_channel" json:"agent_rollout_channel"`
+	AllowTargetedBuilds           bool                `db:"allow_targeted_builds" json:"allow_targeted_builds"`
+	ReconfirmParameters           []string            `db:"reconfirm_parameters" json:"reconfirm_parameters"`
+	DeprecationCutoff             sql.NullTime        `db:"deprecation_cutoff" json:"deprecation_cutoff"`
+	NightlyStopTime               string              `db:"nightly_stop_time" json:"nightly_stop_time"`
+	BuildLogRetention             int64               `db:"build_log_retention" json:"build_log_retention"`
+	MaxLifetime                   int64               `db:"max_lifetime" json:"max_lifetime"`
+	MaxLifetimeAction             MaxLifetimeAction   `db:"max_lifetime_action" json:"max_lifetime_action"`
+	IdleReclaimTTL                int64               `db:"idle_reclaim_ttl" json:"idle_reclaim_ttl"`
+	IdleReclaimResourceSelector   string              `db:"idle_reclaim_resource_selector" json:"idle_reclaim_resource_selector"`
+	ActivityBumpConnectionTypes   []string            `db:"activity_bump_connection_types" json:"activity_bump_connection_types"`
+	ActivityBumpMaxPerDay         int64               `db:"activity_bump_max_per_day" json:"activity_bump_max_per_day"`
+	PreBuildHookURL               string              `db:"pre_build_hook_url" json:"pre_build_hook_url"`
+	PostBuildHookURL              string              `db:"post_build_hook_url" json:"post_build_hook_url"`
+	AutoAssignRegion              bool                `db:"auto_assign_region" json:"auto_assign_region"`
+	NotifyBuildFailures           bool                `db:"notify_build_failures" json:"notify_build_failures"`
+	DisableProviderCache          bool                `db:"disable_provider_cache" json:"disable_provider_cache"`
+	CreatedByAvatarURL            string              `db:"created_by_avatar_url" json:"created_by_avatar_url"`
+	CreatedByUsername             string              `db:"created_by_username" json:"created_by_username"`
+	CreatedByName                 string              `db:"created_by_name" json:"created_by_name"`
+	OrganizationName              string              `db:"organization_name" json:"organization_name"`
+	OrganizationDisplayName       string              `db:"organization_display_name" json:"organization_display_name"`
+	OrganizationIcon              string              `db:"organization_icon" json:"organization_icon"`
 }
 
 type TemplateTable struct {
@@ -5954,6 +7215,98 @@ type TemplateTable struct {
 	DisableModuleCache      bool         `db:"disable_module_cache" json:"disable_module_cache"`
 	// How long before the workspace autostop deadline to send a reminder notification, in nanoseconds. 0 disables the notification.
 	TimeTilAutostopNotify int64 `db:"time_til_autostop_notify" json:"time_til_autostop_notify"`
+	// Maximum duration of template version import and dry-run jobs, in nanoseconds. 0 means the jobs are not subject to a timeout.
+	ProvisionerPlanTimeout int64 `db:"provisioner_plan_timeout" json:"provisioner_plan_timeout"`
+	// Maximum duration of workspace build jobs, in nanoseconds. 0 means the jobs are not subject to a timeout.
+	ProvisionerApplyTimeout int64 `db:"provisioner_apply_timeout" json:"provisioner_apply_timeout"`
+	// Hard lifetime of workspaces created from this template, in nanoseconds. Expired workspaces are stopped and then deleted regardless of activity. 0 disables the expiry.
+	TrialWorkspaceTTL int64 `db:"trial_workspace_ttl" json:"trial_workspace_ttl"`
+	// Whether workspace builds reaped by the job reaper because their provisioner stopped responding are automatically requeued once.
+	RequeueReapedBuilds bool `db:"requeue_reaped_builds" json:"requeue_reaped_builds"`
+	// The channel workspace agents of this template download their binary from on start.
+	AgentRolloutChannel AgentRolloutChannel `db:"agent_rollout_channel" json:"agent_rollout_channel"`
+	// Allows workspace builds that only replace selected Terraform resources of the workspace.
+	AllowTargetedBuilds bool `db:"allow_targeted_builds" json:"allow_targeted_builds"`
+	// Names of parameters whose values users must re-enter when updating a workspace to a new template version.
+	ReconfirmParameters []string `db:"reconfirm_parameters" json:"reconfirm_parameters"`
+	// If set, the deprecated template may still be used to create workspaces until this time. After it, creation is blocked.
+	DeprecationCutoff sql.NullTime `db:"deprecation_cutoff" json:"deprecation_cutoff"`
+	// If set, running workspaces of this template are stopped every day at this time (HH:MM), regardless of activity. The time is interpreted in the timezone of the workspace owner's quiet hours schedule.
+	NightlyStopTime string `db:"nightly_stop_time" json:"nightly_stop_time"`
+	// How long provisioner job logs of this template are kept before they are archived or deleted, in nanoseconds. 0 uses the deployment-wide retention.
+	BuildLogRetention int64 `db:"build_log_retention" json:"build_log_retention"`
+	// Maximum lifetime of workspaces created from this template, measured from their creation, in nanoseconds. Workspaces past it are stopped or deleted regardless of activity. 0 disables the limit.
+	MaxLifetime int64 `db:"max_lifetime" json:"max_lifetime"`
+	// What happens to workspaces that exceed the max lifetime of the template.
+	MaxLifetimeAction MaxLifetimeAction `db:"max_lifetime_action" json:"max_lifetime_action"`
+	// How long running workspaces with a resource matching idle_reclaim_resource_selector may be idle before they are stopped, in nanoseconds. Typically much shorter than the default TTL, to reclaim expensive resources such as GPUs. 0 disables idle reclaim.
+	IdleReclaimTTL int64 `db:"idle_reclaim_ttl" json:"idle_reclaim_ttl"`
+	// A key=value pair matched against the metadata of workspace resources to select the workspaces subject to idle reclaim.
+	IdleReclaimResourceSelector string `db:"idle_reclaim_resource_selector" json:"idle_reclaim_resource_selector"`
+	// The connection types whose activity bumps the deadline of workspaces, e.g. ssh or vscode. Empty means all connection types bump the deadline.
+	ActivityBumpConnectionTypes []string `db:"activity_bump_connection_types" json:"activity_bump_connection_types"`
+	// The maximum total duration activity may bump the deadline of a workspace per UTC day, in nanoseconds. 0 means unlimited.
+	ActivityBumpMaxPerDay int64 `db:"activity_bump_max_per_day" json:"activity_bump_max_per_day"`
+	// If set, coderd POSTs to this URL before every workspace build of the template is provisioned. The hook may reject the build.
+	PreBuildHookURL string `db:"pre_build_hook_url" json:"pre_build_hook_url"`
+	// If set, coderd POSTs to this URL after every successful workspace build of the template. The hook may report external provisioning steps, which are recorded in the build logs.
+	PostBuildHookURL string `db:"post_build_hook_url" json:"post_build_hook_url"`
+	// If set, workspaces created from the template are assigned the region recommended for the latencies reported by the creating client.
+	AutoAssignRegion bool `db:"auto_assign_region" json:"auto_assign_region"`
+	// If set, the owners of workspaces created from the template, and the initiators of their builds, are notified of every failed build along with an excerpt of its logs.
+	NotifyBuildFailures bool `db:"notify_build_failures" json:"notify_build_failures"`
+	// If set, provisioners install the Terraform providers of the template from their registries during workspace builds instead of reusing them from the shared plugin cache.
+	DisableProviderCache bool `db:"disable_provider_cache" json:"disable_provider_cache"`
+}
+
+// Periodic health reports of templates, sent to template admins by the report generator.
+type TemplateHealthReport struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	TemplateID   uuid.UUID `db:"template_id" json:"template_id"`
+	PeriodStart  time.Time `db:"period_start" json:"period_start"`
+	PeriodEnd    time.Time `db:"period_end" json:"period_end"`
+	TotalBuilds  int64     `db:"total_builds" json:"total_builds"`
+	FailedBuilds int64     `db:"failed_builds" json:"failed_builds"`
+	// Average time it took successful start builds of the period to complete, from when they were queued. Zero if there were none.
+	AverageStartSeconds float64 `db:"average_start_seconds" json:"average_start_seconds"`
+	TotalWorkspaces     int64   `db:"total_workspaces" json:"total_workspaces"`
+	// Workspaces whose latest build does not use the active template version, at the end of the period.
+	OutdatedWorkspaces int64     `db:"outdated_workspaces" json:"outdated_workspaces"`
+	DormantWorkspaces  int64     `db:"dormant_workspaces" json:"dormant_workspaces"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+}
+
+// Default outbound network policy declared for the workspaces of a template. Enforcement (CNI plugins, egress proxies) happens outside of Coder.
+type TemplateNetworkPolicy struct {
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	AllowedDomains []string  `db:"allowed_domains" json:"allowed_domains"`
+	AllowedCIDRs   []string  `db:"allowed_cidrs" json:"allowed_cidrs"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Restricts the presets of a template with a given name, in every version of the template, to a cohort of users. Presets without a cohort are available to everyone who can use the template.
+type TemplatePresetCohort struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	PresetName string    `db:"preset_name" json:"preset_name"`
+	// Members of any of these groups are part of the cohort.
+	GroupIds []uuid.UUID `db:"group_ids" json:"group_ids"`
+	// Names of site-wide roles, or of roles in the organization of the template. Users with any of these roles are part of the cohort.
+	Roles     []string  `db:"roles" json:"roles"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Secrets declared by a template that agent scripts can reference. They are fetched by the agent at run time and only exposed to the environment of the scripts that reference them, so they never end up in Terraform state.
+type TemplateSecret struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	// The name of the secret, which is also the environment variable it is exposed as.
+	Name        string `db:"name" json:"name"`
+	Description string `db:"description" json:"description"`
+	Value       string `db:"value" json:"value"`
+	// The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted.
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
 }
 
 // Records aggregated usage statistics for templates/users. All usage is rounded up to the nearest minute.
@@ -5984,6 +7337,18 @@ type TemplateUsageStat struct {
 	AppUsageMins StringMapOfInt `db:"app_usage_mins" json:"app_usage_mins"`
 }
 
+// Named sets of parameter values that a user saved for a template. Unlike template version presets, they are private to the user.
+type TemplateUserPreset struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Name       string    `db:"name" json:"name"`
+	// Array of objects with the name and value of each parameter.
+	Parameters json.RawMessage `db:"parameters" json:"parameters"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time       `db:"updated_at" json:"updated_at"`
+}
+
 // Joins in the username + avatar url of the created by user.
 type TemplateVersion struct {
 	ID                    uuid.UUID       `db:"id" json:"id"`
@@ -6001,6 +7366,8 @@ type TemplateVersion struct {
 	SourceExampleID       sql.NullString  `db:"source_example_id" json:"source_example_id"`
 	HasAITask             sql.NullBool    `db:"has_ai_task" json:"has_ai_task"`
 	HasExternalAgent      sql.NullBool    `db:"has_external_agent" json:"has_external_agent"`
+	Deprecated            string          `db:"deprecated" json:"deprecated"`
+	DeprecationCutoff     sql.NullTime    `db:"deprecation_cutoff" json:"deprecation_cutoff"`
 	CreatedByAvatarURL    string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername     string          `db:"created_by_username" json:"created_by_username"`
 	CreatedByName         string          `db:"created_by_name" json:"created_by_name"`
@@ -6042,6 +7409,14 @@ type TemplateVersionParameter struct {
 	Ephemeral bool `db:"ephemeral" json:"ephemeral"`
 	// Specify what form_type should be used to render the parameter in the UI. Unsupported values are rejected.
 	FormType ParameterFormType `db:"form_type" json:"form_type"`
+	// The values of a sensitive parameter are encrypted at rest and redacted in API responses.
+	Sensitive bool `db:"sensitive" json:"sensitive"`
+	// Name of the group of the parameter, used to present parameters in sections. Empty if the parameter is not grouped.
+	GroupName string `db:"group_name" json:"group_name"`
+	// Specifies the order in which to display the group of the parameter. All parameters of a group share it.
+	GroupOrder int32 `db:"group_order" json:"group_order"`
+	// Page of a multi-page form on which to display the parameter. All parameters of a group share it.
+	Page int32 `db:"page" json:"page"`
 }
 
 type TemplateVersionPreset struct {
@@ -6093,6 +7468,48 @@ type TemplateVersionTable struct {
 	SourceExampleID  sql.NullString `db:"source_example_id" json:"source_example_id"`
 	HasAITask        sql.NullBool   `db:"has_ai_task" json:"has_ai_task"`
 	HasExternalAgent sql.NullBool   `db:"has_external_agent" json:"has_external_agent"`
+	// If set to a non empty string, the template version is deprecated. The message is shown to users building workspaces with it.
+	Deprecated string `db:"deprecated" json:"deprecated"`
+	// If set, the deprecated template version may still be used for builds until this time. After it, new builds with it are blocked.
+	DeprecationCutoff sql.NullTime `db:"deprecation_cutoff" json:"deprecation_cutoff"`
+}
+
+// Staged promotions of a template version. Builds of workspaces in the cohort of the current stage that use the active version get the rolled out version instead, until the rollout is completed and the version is promoted, or rolled back.
+type TemplateVersionRollout struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	// The active version of the template when the rollout was created. The rollout is aborted if the active version changes before it is completed.
+	PreviousVersionID uuid.UUID `db:"previous_version_id" json:"previous_version_id"`
+	// The workspaces of the members of this group are part of the cohort of every stage.
+	GroupID uuid.NullUUID `db:"group_id" json:"group_id"`
+	// The percentage of workspaces in the cohort of each stage.
+	Stages       []int32 `db:"stages" json:"stages"`
+	CurrentStage int32   `db:"current_stage" json:"current_stage"`
+	// How long each stage is observed before the rollout continues, in nanoseconds.
+	SoakDuration int64 `db:"soak_duration" json:"soak_duration"`
+	// The rollout is rolled back once the share of failed start builds of the version in a stage exceeds this rate.
+	MaxFailureRate float64 `db:"max_failure_rate" json:"max_failure_rate"`
+	// The number of completed start builds in a stage before its failure rate is considered.
+	MinBuilds      int32                        `db:"min_builds" json:"min_builds"`
+	Status         TemplateVersionRolloutStatus `db:"status" json:"status"`
+	StatusMessage  string                       `db:"status_message" json:"status_message"`
+	CreatedBy      uuid.UUID                    `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time                    `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time                    `db:"updated_at" json:"updated_at"`
+	StageStartedAt time.Time                    `db:"stage_started_at" json:"stage_started_at"`
+	CompletedAt    sql.NullTime                 `db:"completed_at" json:"completed_at"`
+}
+
+// Results of submitting template version files and plan output to the configured template scanner.
+type TemplateVersionScan struct {
+	TemplateVersionID uuid.UUID                 `db:"template_version_id" json:"template_version_id"`
+	Status            TemplateVersionScanStatus `db:"status" json:"status"`
+	// Findings reported by the scanner, as a JSON array of objects with severity, rule_id, message and path.
+	Findings    json.RawMessage `db:"findings" json:"findings"`
+	Error       string          `db:"error" json:"error"`
+	StartedAt   time.Time       `db:"started_at" json:"started_at"`
+	CompletedAt sql.NullTime    `db:"completed_at" json:"completed_at"`
 }
 
 type TemplateVersionTerraformValue struct {
@@ -6128,6 +7545,42 @@ type TemplateVersionWorkspaceTag struct {
 	Value             string    `db:"value" json:"value"`
 }
 
+// Restarts of the outdated running workspaces of a template on the version that was active when the restart was created, in batches.
+type TemplateWorkspaceRestart struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	// The version workspaces are restarted on. The restart is aborted if the active version of the template changes before it is completed.
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	BatchSize         int32     `db:"batch_size" json:"batch_size"`
+	// The minimum time between batches, in nanoseconds.
+	BatchInterval int64 `db:"batch_interval" json:"batch_interval"`
+	// Whether workspaces are only restarted during the quiet hours of their owner.
+	HonorQuietHours bool `db:"honor_quiet_hours" json:"honor_quiet_hours"`
+	// The restart is paused once the share of failed restarts since stats_since exceeds this rate.
+	MaxFailureRate float64 `db:"max_failure_rate" json:"max_failure_rate"`
+	// The number of completed restarts since stats_since before the failure rate is considered.
+	MinBuilds     int32                          `db:"min_builds" json:"min_builds"`
+	Status        TemplateWorkspaceRestartStatus `db:"status" json:"status"`
+	StatusMessage string                         `db:"status_message" json:"status_message"`
+	CreatedBy     uuid.UUID                      `db:"created_by" json:"created_by"`
+	CreatedAt     time.Time                      `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time                      `db:"updated_at" json:"updated_at"`
+	// The failure rate only considers restarts since this time. It is reset when a paused restart is resumed.
+	StatsSince  time.Time    `db:"stats_since" json:"stats_since"`
+	LastBatchAt sql.NullTime `db:"last_batch_at" json:"last_batch_at"`
+	CompletedAt sql.NullTime `db:"completed_at" json:"completed_at"`
+}
+
+// The workspaces restarted by a template workspace restart. Every workspace is restarted at most once.
+type TemplateWorkspaceRestartBuild struct {
+	RestartID        uuid.UUID     `db:"restart_id" json:"restart_id"`
+	WorkspaceID      uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.NullUUID `db:"workspace_build_id" json:"workspace_build_id"`
+	// Why the build could not be created, in which case workspace_build_id is NULL.
+	Error     string    `db:"error" json:"error"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
 // usage_events contains usage data that is collected from the product and potentially shipped to the usage collector service.
 type UsageEvent struct {
 	// For "discrete" event types, this is a random UUID. For "heartbeat" event types, this is a combination of the event type and a truncated timestamp.
@@ -6218,6 +7671,24 @@ type UserDeleted struct {
 	DeletedAt time.Time `db:"deleted_at" json:"deleted_at"`
 }
 
+// Requests of support staff to act as a user. A token impersonating the user is issued once the request is granted.
+type UserImpersonation struct {
+	ID uuid.UUID `db:"id" json:"id"`
+	// The impersonated user.
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	ImpersonatorID uuid.UUID `db:"impersonator_id" json:"impersonator_id"`
+	Reason         string    `db:"reason" json:"reason"`
+	// The lifetime of the token issued for the request.
+	LifetimeSeconds int64 `db:"lifetime_seconds" json:"lifetime_seconds"`
+	ConsentRequired bool  `db:"consent_required" json:"consent_required"`
+	// When the impersonated user consented to the request. Only set when consent is required.
+	ConsentedAt sql.NullTime `db:"consented_at" json:"consented_at"`
+	// The token issued for the request. Cleared once the token is deleted.
+	APIKeyID  sql.NullString `db:"api_key_id" json:"api_key_id"`
+	IssuedAt  sql.NullTime   `db:"issued_at" json:"issued_at"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+}
+
 type UserLink struct {
 	UserID            uuid.UUID `db:"user_id" json:"user_id"`
 	LoginType         LoginType `db:"login_type" json:"login_type"`
@@ -6233,6 +7704,17 @@ type UserLink struct {
 	Claims UserLinkClaims `db:"claims" json:"claims"`
 }
 
+// Pauses of the autostart schedules of the workspaces of users, for example while they are on leave.
+type UserSchedulePause struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// The time the pause ends at. Autostarts that were due during the pause are skipped.
+	PausedUntil time.Time `db:"paused_until" json:"paused_until"`
+	// Whether autostops at the deadline of builds are paused as well. Dormancy and deletion are never paused.
+	PauseAutostop bool      `db:"pause_autostop" json:"pause_autostop"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type UserSecret struct {
 	ID          uuid.UUID      `db:"id" json:"id"`
 	UserID      uuid.UUID      `db:"user_id" json:"user_id"`
@@ -6264,6 +7746,40 @@ type UserStatusChange struct {
 	ChangedAt time.Time  `db:"changed_at" json:"changed_at"`
 }
 
+// Periodic summaries of the workspaces of users, sent to them by the report generator.
+type UserWorkspaceReport struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	PeriodStart time.Time `db:"period_start" json:"period_start"`
+	PeriodEnd   time.Time `db:"period_end" json:"period_end"`
+	// Builds of the workspaces of the user during the period, and the workspaces that will be deleted or become dormant soon, as served by the API.
+	Summary   json.RawMessage `db:"summary" json:"summary"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Maps the push events of a VCS repository, received by the VCS webhook receiver, to an action on a template or one of its workspaces.
+type VCSEventMapping struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Name       string    `db:"name" json:"name"`
+	// The full name of the repository, e.g. "coder/coder". Matched case-insensitively.
+	Repository string `db:"repository" json:"repository"`
+	// The branch pushes must be made to. Empty matches every branch.
+	Branch string         `db:"branch" json:"branch"`
+	Action VCSEventAction `db:"action" json:"action"`
+	// The workspace started by the start_workspace action. NULL for the other actions.
+	WorkspaceID uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	// The secret of the repository webhook, used to verify the signature of events. GitHub signs the body with HMAC-SHA256, GitLab sends the secret as is.
+	SigningSecret string `db:"signing_secret" json:"signing_secret"`
+	// The ID of the key used to encrypt the signing secret. If this is NULL, the signing secret is not encrypted.
+	SigningSecretKeyID sql.NullString `db:"signing_secret_key_id" json:"signing_secret_key_id"`
+	// The user the actions of the mapping are performed as.
+	CreatedBy       uuid.UUID    `db:"created_by" json:"created_by"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
+	LastTriggeredAt sql.NullTime `db:"last_triggered_at" json:"last_triggered_at"`
+}
+
 // Visible fields of users are allowed to be joined with other tables for including context of other resources.
 type VisibleUser struct {
 	ID        uuid.UUID `db:"id" json:"id"`
@@ -6301,6 +7817,9 @@ type Workspace struct {
 	NextStartAt             sql.NullTime            `db:"next_start_at" json:"next_start_at"`
 	GroupACL                WorkspaceACL            `db:"group_acl" json:"group_acl"`
 	UserACL                 WorkspaceACL            `db:"user_acl" json:"user_acl"`
+	ExpiresAt               sql.NullTime            `db:"expires_at" json:"expires_at"`
+	ArchivedAt              sql.NullTime            `db:"archived_at" json:"archived_at"`
+	ReadOnlyAt              sql.NullTime            `db:"read_only_at" json:"read_only_at"`
 	OwnerAvatarUrl          string                  `db:"owner_avatar_url" json:"owner_avatar_url"`
 	OwnerUsername           string                  `db:"owner_username" json:"owner_username"`
 	OwnerName               string                  `db:"owner_name" json:"owner_name"`
@@ -6317,6 +7836,13 @@ type Workspace struct {
 	UserACLDisplayInfo      WorkspaceACLDisplayInfo `db:"user_acl_display_info" json:"user_acl_display_info"`
 }
 
+// The total duration, in nanoseconds, activity bumped the deadline of each workspace on the given UTC day. Used to enforce the activity_bump_max_per_day of templates.
+type WorkspaceActivityBump struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Date        time.Time `db:"date" json:"date"`
+	Bumped      int64     `db:"bumped" json:"bumped"`
+}
+
 type WorkspaceAgent struct {
 	ID                   uuid.UUID             `db:"id" json:"id"`
 	CreatedAt            time.Time             `db:"created_at" json:"created_at"`
@@ -6365,6 +7891,19 @@ type WorkspaceAgent struct {
 	APIKeyScope AgentKeyScopeEnum `db:"api_key_scope" json:"api_key_scope"`
 	// Indicates whether or not the agent has been deleted. This is currently only applicable to sub agents.
 	Deleted bool `db:"deleted" json:"deleted"`
+	// The offset of the clock of the agent from the clock of coderd in milliseconds, as last reported by the agent. Positive values mean the clock of the agent is ahead. NULL if the agent never reported it.
+	ClockSkewMs sql.NullInt64 `db:"clock_skew_ms" json:"clock_skew_ms"`
+}
+
+// Named progress milestones reported by the startup scripts of a workspace agent. The most recent milestone is the current one.
+type WorkspaceAgentBootstrapProgress struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	Name             string    `db:"name" json:"name"`
+	// Completion of the milestone in percent, if the script reported it.
+	Percent sql.NullInt32 `db:"percent" json:"percent"`
+	Message string        `db:"message" json:"message"`
 }
 
 // Per-resource state for the latest pushed workspace agent context snapshot.
@@ -6403,6 +7942,20 @@ type WorkspaceAgentContextSnapshot struct {
 	ReceivedAt time.Time `db:"received_at" json:"received_at"`
 }
 
+// Tracks agents that repeatedly disconnect shortly after connecting. The row is removed once the agent stays connected.
+type WorkspaceAgentCrashLoop struct {
+	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
+	// The number of consecutive connections of the agent that ended before becoming stable.
+	ShortConnections   int32     `db:"short_connections" json:"short_connections"`
+	LastDisconnectedAt time.Time `db:"last_disconnected_at" json:"last_disconnected_at"`
+	// Why the last connection of the agent ended.
+	LastDisconnectReason string `db:"last_disconnect_reason" json:"last_disconnect_reason"`
+	// The lifecycle state of the agent when its last connection ended.
+	LastLifecycleState WorkspaceAgentLifecycleState `db:"last_lifecycle_state" json:"last_lifecycle_state"`
+	// When the agent was detected to be crash looping. Reconnects are backed off while set.
+	CrashLoopingSince sql.NullTime `db:"crash_looping_since" json:"crash_looping_since"`
+}
+
 // Workspace agent devcontainer configuration
 type WorkspaceAgentDevcontainer struct {
 	// Unique identifier
@@ -6437,6 +7990,19 @@ type WorkspaceAgentLogSource struct {
 	Icon             string    `db:"icon" json:"icon"`
 }
 
+// OOM kills and severe memory pressure reported by the resources monitor of a workspace agent.
+type WorkspaceAgentMemoryEvent struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	// When the agent collected the datapoint the event was derived from.
+	CreatedAt time.Time                     `db:"created_at" json:"created_at"`
+	Kind      WorkspaceAgentMemoryEventKind `db:"kind" json:"kind"`
+	// Number of processes killed by the OOM killer since the previous datapoint. Zero for memory pressure events.
+	OomKills int32 `db:"oom_kills" json:"oom_kills"`
+	// Percentage of time in the last 10 seconds during which all non-idle tasks of the workspace were stalled on memory.
+	MemoryPressure float64 `db:"memory_pressure" json:"memory_pressure"`
+}
+
 type WorkspaceAgentMemoryResourceMonitor struct {
 	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
 	Enabled        bool                       `db:"enabled" json:"enabled"`
@@ -6461,6 +8027,27 @@ type WorkspaceAgentMetadatum struct {
 	DisplayOrder int32 `db:"display_order" json:"display_order"`
 }
 
+// Historical values of workspace agent metadata. Only recorded when a metadata history retention is configured, and purged after it.
+type WorkspaceAgentMetadataHistory struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              string    `db:"key" json:"key"`
+	Value            string    `db:"value" json:"value"`
+	Error            string    `db:"error" json:"error"`
+	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
+}
+
+// Outbound connections reported by a workspace agent that were not allowed by the network policy of its workspace.
+type WorkspaceAgentNetworkPolicyViolation struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	// The domain or IP address the workspace tried to connect to.
+	Destination string `db:"destination" json:"destination"`
+	Port        int32  `db:"port" json:"port"`
+	// Number of blocked connections to the destination since the previous report.
+	Count int32 `db:"count" json:"count"`
+}
+
 type WorkspaceAgentPortShare struct {
 	WorkspaceID uuid.UUID         `db:"workspace_id" json:"workspace_id"`
 	AgentName   string            `db:"agent_name" json:"agent_name"`
@@ -6493,6 +8080,30 @@ type WorkspaceAgentScriptTiming struct {
 	Status    WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
 }
 
+// Long-running processes declared by the template that the workspace agent supervises.
+type WorkspaceAgentService struct {
+	WorkspaceAgentID uuid.UUID                          `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Name             string                             `db:"name" json:"name"`
+	DisplayName      string                             `db:"display_name" json:"display_name"`
+	Command          string                             `db:"command" json:"command"`
+	RestartPolicy    WorkspaceAgentServiceRestartPolicy `db:"restart_policy" json:"restart_policy"`
+	CreatedAt        time.Time                          `db:"created_at" json:"created_at"`
+}
+
+// Status transitions of the services of a workspace agent. The most recent transition is the current status of the service.
+type WorkspaceAgentServiceTransition struct {
+	ID               uuid.UUID                   `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID                   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	ServiceName      string                      `db:"service_name" json:"service_name"`
+	CreatedAt        time.Time                   `db:"created_at" json:"created_at"`
+	Status           WorkspaceAgentServiceStatus `db:"status" json:"status"`
+	Message          string                      `db:"message" json:"message"`
+	// Exit code of the service process, if the transition was caused by the process exiting.
+	ExitCode sql.NullInt32 `db:"exit_code" json:"exit_code"`
+	// Number of times the agent restarted the service since the agent started.
+	RestartCount int32 `db:"restart_count" json:"restart_count"`
+}
+
 type WorkspaceAgentStat struct {
 	ID                          uuid.UUID       `db:"id" json:"id"`
 	CreatedAt                   time.Time       `db:"created_at" json:"created_at"`
@@ -6549,6 +8160,10 @@ type WorkspaceApp struct {
 	DisplayGroup sql.NullString     `db:"display_group" json:"display_group"`
 	// Markdown text that is displayed when hovering over workspace apps.
 	Tooltip string `db:"tooltip" json:"tooltip"`
+	// Maximum lifetime of a session used to access the app, in seconds. Zero means unlimited.
+	MaxSessionDurationSeconds int32 `db:"max_session_duration_seconds" json:"max_session_duration_seconds"`
+	// Duration of inactivity after which a session used to access the app expires, in seconds. Zero means unlimited.
+	SessionIdleTimeoutSeconds int32 `db:"session_idle_timeout_seconds" json:"session_idle_timeout_seconds"`
 }
 
 // Audit sessions for workspace apps, the data in this table is ephemeral and is used to deduplicate audit log entries for workspace apps. While a session is active, the same data will not be logged again. This table does not store historical data.
@@ -6574,6 +8189,22 @@ type WorkspaceAppAuditSession struct {
 	ID        uuid.UUID `db:"id" json:"id"`
 }
 
+// Expiring links that grant visitors authenticated by the OIDC provider of the deployment access to a workspace app, without a Coder account.
+type WorkspaceAppShareLink struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The agent of the shared app. Apps are recreated on every build, so they are referenced by agent name and slug.
+	AgentName string `db:"agent_name" json:"agent_name"`
+	AppSlug   string `db:"app_slug" json:"app_slug"`
+	// The SHA-256 hash of the secret included in the link.
+	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
+	CreatedBy    uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
+	// When the link was revoked. Revoked links no longer grant access.
+	RevokedAt sql.NullTime `db:"revoked_at" json:"revoked_at"`
+}
+
 // A record of workspace app usage statistics
 type WorkspaceAppStat struct {
 	// The ID of the record
@@ -6633,6 +8264,23 @@ type WorkspaceBuild struct {
 	InitiatorByName          string              `db:"initiator_by_name" json:"initiator_by_name"`
 }
 
+// Free-form notes attached to a workspace build by users, e.g. why the build was run.
+type WorkspaceBuildAnnotation struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	Note             string    `db:"note" json:"note"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+}
+
+// Immutable record of everything a workspace build was provisioned with, captured when its provisioner job is acquired. Used to reproduce a build in another deployment.
+type WorkspaceBuildInput struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	// The build input bundle. Secrets, such as sensitive template variables and owner tokens, are never included.
+	Inputs    json.RawMessage `db:"inputs" json:"inputs"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
 // Tracks durable follow-up workspace build operations, such as server-side restart, where one child build is created after a parent build completes successfully.
 type WorkspaceBuildOrchestration struct {
 	ID        uuid.UUID `db:"id" json:"id"`
@@ -6664,6 +8312,21 @@ type WorkspaceBuildParameter struct {
 	Name string `db:"name" json:"name"`
 	// Parameter value
 	Value string `db:"value" json:"value"`
+	// Whether the parameter was sensitive in the template version of the build.
+	Sensitive bool `db:"sensitive" json:"sensitive"`
+	// The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted.
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+}
+
+// Rich parameter values that differ between a workspace build and the previous build of the same workspace.
+type WorkspaceBuildParameterChange struct {
+	WorkspaceBuildID uuid.UUID                         `db:"workspace_build_id" json:"workspace_build_id"`
+	Name             string                            `db:"name" json:"name"`
+	Kind             WorkspaceBuildParameterChangeKind `db:"kind" json:"kind"`
+	PreviousValue    string                            `db:"previous_value" json:"previous_value"`
+	NewValue         string                            `db:"new_value" json:"new_value"`
+	// Whether the values were withheld because the parameter is ephemeral. previous_value and new_value are empty when set.
+	Redacted bool `db:"redacted" json:"redacted"`
 }
 
 type WorkspaceBuildTable struct {
@@ -6688,6 +8351,57 @@ type WorkspaceBuildTable struct {
 	NotifiedAutostopDeadline time.Time `db:"notified_autostop_deadline" json:"notified_autostop_deadline"`
 }
 
+// Identifiers of the cloud resources reported by the builds of a workspace. Once the workspace is deleted, each identifier is checked with the cloud provider to detect resources that were leaked by the deletion.
+type WorkspaceCloudResource struct {
+	WorkspaceID  uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	ResourceType string    `db:"resource_type" json:"resource_type"`
+	// Identifier of the resource at its cloud provider, e.g. the id attribute of a Terraform resource.
+	CloudID      string `db:"cloud_id" json:"cloud_id"`
+	ResourceName string `db:"resource_name" json:"resource_name"`
+	// The latest build that reported the resource.
+	WorkspaceBuildID   uuid.UUID                       `db:"workspace_build_id" json:"workspace_build_id"`
+	CreatedAt          time.Time                       `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time                       `db:"updated_at" json:"updated_at"`
+	VerificationStatus CloudResourceVerificationStatus `db:"verification_status" json:"verification_status"`
+	// The deletion build after which the resource was verified.
+	VerificationBuildID uuid.NullUUID `db:"verification_build_id" json:"verification_build_id"`
+	VerificationError   string        `db:"verification_error" json:"verification_error"`
+	VerifiedAt          sql.NullTime  `db:"verified_at" json:"verified_at"`
+}
+
+// DNS names registered with the configured workspace DNS provider for running workspaces.
+type WorkspaceDNSRecord struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+	// The host the DNS name was pointed at when it was registered.
+	Target    string    `db:"target" json:"target"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// The latest dormancy lifecycle hook invocation of a workspace. Hooks move the storage of dormant workspaces to cheaper tiers and restore it on activation.
+type WorkspaceDormancyHook struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The lifecycle event the hook is invoked for. An activate hook must succeed before a dormant workspace can be started.
+	Event  WorkspaceDormancyHookEvent  `db:"event" json:"event"`
+	Status WorkspaceDormancyHookStatus `db:"status" json:"status"`
+	// The error returned by the last failed invocation of the hook.
+	Error     string    `db:"error" json:"error"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Exempts a workspace from being marked dormant by the template time_til_dormant policy until expires_at.
+type WorkspaceDormancyExemption struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Reason      string    `db:"reason" json:"reason"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+	// The template administrator that granted the exemption.
+	ApprovedBy uuid.UUID `db:"approved_by" json:"approved_by"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type WorkspaceLatestBuild struct {
 	ID                      uuid.UUID            `db:"id" json:"id"`
 	WorkspaceID             uuid.UUID            `db:"workspace_id" json:"workspace_id"`
@@ -6699,6 +8413,45 @@ type WorkspaceLatestBuild struct {
 	JobStatus               ProvisionerJobStatus `db:"job_status" json:"job_status"`
 }
 
+// Bytes received and transmitted by workspace agents, aggregated per workspace and UTC day and split by connection class. Used for network chargeback.
+type WorkspaceEgressDaily struct {
+	WorkspaceID            uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateID             uuid.UUID `db:"template_id" json:"template_id"`
+	OwnerID                uuid.UUID `db:"owner_id" json:"owner_id"`
+	Date                   time.Time `db:"date" json:"date"`
+	RxBytesSsh             int64     `db:"rx_bytes_ssh" json:"rx_bytes_ssh"`
+	TxBytesSsh             int64     `db:"tx_bytes_ssh" json:"tx_bytes_ssh"`
+	RxBytesReconnectingPty int64     `db:"rx_bytes_reconnecting_pty" json:"rx_bytes_reconnecting_pty"`
+	TxBytesReconnectingPty int64     `db:"tx_bytes_reconnecting_pty" json:"tx_bytes_reconnecting_pty"`
+	RxBytesPortForward     int64     `db:"rx_bytes_port_forward" json:"rx_bytes_port_forward"`
+	TxBytesPortForward     int64     `db:"tx_bytes_port_forward" json:"tx_bytes_port_forward"`
+}
+
+// Time-bound leases on ephemeral workspaces, typically created by CI jobs. A workspace is deleted once its lease expires or is released.
+type WorkspaceLease struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateID  uuid.UUID `db:"template_id" json:"template_id"`
+	OwnerID     uuid.UUID `db:"owner_id" json:"owner_id"`
+	// The API key that created the lease. Concurrency caps are enforced per key.
+	APIKeyID string `db:"api_key_id" json:"api_key_id"`
+	// The API key minted for the lease holder. It is deleted when the lease is released.
+	CredentialKeyID string       `db:"credential_key_id" json:"credential_key_id"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+	ExpiresAt       time.Time    `db:"expires_at" json:"expires_at"`
+	ReleasedAt      sql.NullTime `db:"released_at" json:"released_at"`
+}
+
+// The most recent warning sent to the owner of a workspace approaching the max lifetime of its template.
+type WorkspaceLifetimeWarning struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The expiry the warning was sent for. A change to the template max lifetime moves the expiry and re-arms the warnings.
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+	// How many days before the expiry the warning was sent for.
+	DaysBefore int32     `db:"days_before" json:"days_before"`
+	SentAt     time.Time `db:"sent_at" json:"sent_at"`
+}
+
 type WorkspaceModule struct {
 	ID         uuid.UUID           `db:"id" json:"id"`
 	JobID      uuid.UUID           `db:"job_id" json:"job_id"`
@@ -6709,6 +8462,35 @@ type WorkspaceModule struct {
 	CreatedAt  time.Time           `db:"created_at" json:"created_at"`
 }
 
+// Cost counters of workspaces per calendar month, used to enforce monthly cost budgets.
+type WorkspaceMonthlyCost struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The start of the UTC calendar month the cost was accrued in.
+	Month time.Time `db:"month" json:"month"`
+	// The cost accrued in the month, in the same units as the daily cost of workspace resources.
+	Cost float64 `db:"cost" json:"cost"`
+	// Estimated counters accrue the daily cost of the running build. Counters imported from a billing export replace the estimate and are not accrued.
+	Source    WorkspaceCostSource `db:"source" json:"source"`
+	UpdatedAt time.Time           `db:"updated_at" json:"updated_at"`
+}
+
+// Outbound network policy declared for a single workspace. Replaces the policy of the template of the workspace.
+type WorkspaceNetworkPolicy struct {
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AllowedDomains []string  `db:"allowed_domains" json:"allowed_domains"`
+	AllowedCIDRs   []string  `db:"allowed_cidrs" json:"allowed_cidrs"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Ports of a shared workspace that a user may connect to. Users the workspace is shared with that have no row may connect to any port.
+type WorkspacePortForwardACL struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Ports       []int32   `db:"ports" json:"ports"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type WorkspacePrebuild struct {
 	ID              uuid.UUID     `db:"id" json:"id"`
 	Name            string        `db:"name" json:"name"`
@@ -6751,6 +8533,14 @@ type WorkspaceProxy struct {
 	Version  string `db:"version" json:"version"`
 }
 
+// The region assigned to a workspace when it was created from a template with auto_assign_region set.
+type WorkspaceRegionAssignment struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The ID of the workspace proxy, or the deployment ID for the primary region.
+	RegionID  uuid.UUID `db:"region_id" json:"region_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
 type WorkspaceResource struct {
 	ID           uuid.UUID           `db:"id" json:"id"`
 	CreatedAt    time.Time           `db:"created_at" json:"created_at"`
@@ -6793,4 +8583,9 @@ type WorkspaceTable struct {
 	NextStartAt sql.NullTime `db:"next_start_at" json:"next_start_at"`
 	GroupACL    WorkspaceACL `db:"group_acl" json:"group_acl"`
 	UserACL     WorkspaceACL `db:"user_acl" json:"user_acl"`
+	ExpiresAt   sql.NullTime `db:"expires_at" json:"expires_at"`
+	// The time the workspace was archived. Archived workspaces have no provisioned resources but keep their parameters and metadata, and must be unarchived before they can be started.
+	ArchivedAt sql.NullTime `db:"archived_at" json:"archived_at"`
+	// The time the workspace was placed in read-only mode. Users can still connect to a read-only workspace, but its agent blocks outbound git pushes and no new builds can be created until the mode is lifted.
+	ReadOnlyAt sql.NullTime `db:"read_only_at" json:"read_only_at"`
 }
@@ -13,6 +13,13 @@ import (
 )
 
 type sqlcQuerier interface {
+	// // Adds the estimated cost of every running workspace since the last accrual
+	// // to its counter for the month, based on the daily cost of its latest build.
+	// // Estimated counters of workspaces that are not running only advance their
+	// // accrual time, and counters imported from a billing export are left alone.
+	// // Concurrent runs do not double count, as every run only accrues the time
+	// // since the previous one.
+	AccrueWorkspaceEstimatedCosts(ctx context.Context, arg AccrueWorkspaceEstimatedCostsParams) error
 	// Blocks until the lock is acquired.
 	//
 	// This must be called from within a transaction. The lock will be automatically
@@ -90,11 +97,24 @@ type sqlcQuerier interface {
 	// Used to reject input that would silently match nothing.
 	ChatSearchQueryIsEmpty(ctx context.Context, search string) (bool, error)
 	ClaimPrebuiltWorkspace(ctx context.Context, arg ClaimPrebuiltWorkspaceParams) (ClaimPrebuiltWorkspaceRow, error)
+	// Claims template versions whose import job succeeded after completed_after
+	// and that have not been scanned yet, or whose scan was claimed before
+	// stale_before and never finished. Concurrent callers never claim the same
+	// template version.
+	ClaimTemplateVersionScans(ctx context.Context, arg ClaimTemplateVersionScansParams) ([]TemplateVersionScan, error)
+	// // Marks up to @limit_count pending hooks as running and returns them. Hooks
+	// // that have been running since before @stale_before are claimed again, so
+	// // invocations interrupted by a restart are retried.
+	ClaimWorkspaceDormancyHooks(ctx context.Context, arg ClaimWorkspaceDormancyHooksParams) ([]WorkspaceDormancyHook, error)
 	CleanTailnetCoordinators(ctx context.Context) error
 	CleanTailnetLostPeers(ctx context.Context) error
 	CleanTailnetTunnels(ctx context.Context) error
 	CleanupDeletedMCPServerIDsFromChats(ctx context.Context) error
 	CountAIBridgeSessions(ctx context.Context, arg CountAIBridgeSessionsParams) (int64, error)
+	// Counts the leases created by an API key that have not been released yet.
+	// Expired leases count until the reaper releases them, so a token cannot
+	// exceed its cap while teardown is pending.
+	CountActiveWorkspaceLeasesByAPIKeyID(ctx context.Context, apiKeyID string) (int64, error)
 	CountAuditLogs(ctx context.Context, arg CountAuditLogsParams) (int64, error)
 	// Cheap queue-length check used by ChatMachine.Update when deciding
 	// whether the chat is in a "1" sub-state.
@@ -113,6 +133,8 @@ type sqlcQuerier interface {
 	CountOIDCLinkedIDsByIssuer(ctx context.Context) ([]CountOIDCLinkedIDsByIssuerRow, error)
 	// CountPendingNonActivePrebuilds returns the number of pending prebuilds for non-active template versions
 	CountPendingNonActivePrebuilds(ctx context.Context) ([]CountPendingNonActivePrebuildsRow, error)
+	// Counts the jobs a provisioner daemon acquired that haven't completed yet.
+	CountRunningProvisionerJobsByWorkerID(ctx context.Context, workerID uuid.UUID) (int64, error)
 	CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 	CreateUserSecret(ctx context.Context, arg CreateUserSecretParams) (UserSecret, error)
 	CustomRoles(ctx context.Context, arg CustomRolesParams) ([]CustomRole, error)
@@ -159,6 +181,7 @@ type sqlcQuerier interface {
 	DeleteCryptoKey(ctx context.Context, arg DeleteCryptoKeyParams) (CryptoKey, error)
 	DeleteCustomRole(ctx context.Context, arg DeleteCustomRoleParams) error
 	DeleteExpiredAPIKeys(ctx context.Context, arg DeleteExpiredAPIKeysParams) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) error
 	DeleteExternalAuthLink(ctx context.Context, arg DeleteExternalAuthLinkParams) error
 	DeleteGroupAIBudget(ctx context.Context, groupID uuid.UUID) (GroupAIBudget, error)
 	DeleteGroupByID(ctx context.Context, id uuid.UUID) error
@@ -166,6 +189,7 @@ type sqlcQuerier interface {
 	DeleteLicense(ctx context.Context, id int32) (int32, error)
 	DeleteMCPServerConfigByID(ctx context.Context, id uuid.UUID) error
 	DeleteMCPServerUserToken(ctx context.Context, arg DeleteMCPServerUserTokenParams) error
+	DeleteNotificationTemplateWebhook(ctx context.Context, notificationTemplateID uuid.UUID) error
 	DeleteOAuth2ProviderAppByClientID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) error
@@ -219,9 +243,15 @@ type sqlcQuerier interface {
 	// Exception: if the logs are related to the latest build, we keep those around.
 	// Logs can take up a lot of space, so it's important we clean up frequently.
 	DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error)
+	DeleteOldWorkspaceAgentMemoryEvents(ctx context.Context, beforeTime time.Time) error
+	// Deletes agent metadata history older than the given time, bounded by a row
+	// limit to avoid long-running transactions.
+	DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, arg DeleteOldWorkspaceAgentMetadataHistoryParams) (int64, error)
+	DeleteOldWorkspaceAgentNetworkPolicyViolations(ctx context.Context, beforeTime time.Time) error
 	DeleteOldWorkspaceAgentStats(ctx context.Context) error
 	DeleteOldWorkspaceBuildOrchestrations(ctx context.Context, arg DeleteOldWorkspaceBuildOrchestrationsParams) (int64, error)
 	DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error
+	DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) (int64, error)
 	DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error
 	DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error
 	DeleteRuntimeConfig(ctx context.Context, key string) error
@@ -233,18 +263,32 @@ type sqlcQuerier interface {
 	DeleteTailnetPeer(ctx context.Context, arg DeleteTailnetPeerParams) (DeleteTailnetPeerRow, error)
 	DeleteTailnetTunnel(ctx context.Context, arg DeleteTailnetTunnelParams) (DeleteTailnetTunnelRow, error)
 	DeleteTask(ctx context.Context, arg DeleteTaskParams) (uuid.UUID, error)
+	DeleteTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) error
+	DeleteTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) error
+	DeleteTemplateSecretByTemplateIDAndName(ctx context.Context, arg DeleteTemplateSecretByTemplateIDAndNameParams) (TemplateSecret, error)
+	DeleteTemplateUserPresetByID(ctx context.Context, id uuid.UUID) error
 	DeleteUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (UserAIBudgetOverride, error)
 	DeleteUserAIProviderKey(ctx context.Context, arg DeleteUserAIProviderKeyParams) error
 	DeleteUserAIProviderKeysByProviderID(ctx context.Context, aiProviderID uuid.UUID) error
 	DeleteUserChatCompactionThreshold(ctx context.Context, arg DeleteUserChatCompactionThresholdParams) error
+	DeleteUserNotificationScopedPreferences(ctx context.Context, arg DeleteUserNotificationScopedPreferencesParams) (int64, error)
+	DeleteUserSchedulePause(ctx context.Context, userID uuid.UUID) error
 	DeleteUserSecretByUserIDAndName(ctx context.Context, arg DeleteUserSecretByUserIDAndNameParams) (UserSecret, error)
 	DeleteUserSkillByUserIDAndName(ctx context.Context, arg DeleteUserSkillByUserIDAndNameParams) (UserSkill, error)
+	DeleteVCSEventMappingByTemplateIDAndID(ctx context.Context, arg DeleteVCSEventMappingByTemplateIDAndIDParams) (VCSEventMapping, error)
 	DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg DeleteWebpushSubscriptionByUserIDAndEndpointParams) error
 	DeleteWebpushSubscriptions(ctx context.Context, ids []uuid.UUID) error
 	DeleteWorkspaceACLByID(ctx context.Context, id uuid.UUID) error
 	DeleteWorkspaceACLsByOrganization(ctx context.Context, arg DeleteWorkspaceACLsByOrganizationParams) error
+	// Removes the crash loop tracking of an agent once it stays connected.
+	DeleteWorkspaceAgentCrashLoop(ctx context.Context, agentID uuid.UUID) error
 	DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error
 	DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error
+	DeleteWorkspaceDNSRecordByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error
+	DeleteWorkspaceDormancyExemption(ctx context.Context, workspaceID uuid.UUID) error
+	DeleteWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) error
+	DeleteWorkspacePortForwardACL(ctx context.Context, arg DeleteWorkspacePortForwardACLParams) error
+	DeleteWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error
 	// Soft-deletes a single sub-agent (a child agent such as a devcontainer
 	// agent). Called from the DeleteSubAgent RPC when a sub-agent is torn
 	// down, which can happen mid-build without a full workspace rebuild.
@@ -255,6 +299,11 @@ type sqlcQuerier interface {
 	// agents are never hard-deleted, so the rows would otherwise orphan
 	// forever.
 	DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error
+	// Force-detaches an active attachment, releasing the read-write lock it holds.
+	DetachSharedVolumeAttachmentByID(ctx context.Context, arg DetachSharedVolumeAttachmentByIDParams) (SharedVolumeAttachment, error)
+	// Detaches the volumes attached by previous builds of a workspace. Every build
+	// attaches the volumes it declares anew.
+	DetachSharedVolumeAttachmentsByWorkspaceID(ctx context.Context, arg DetachSharedVolumeAttachmentsByWorkspaceIDParams) error
 	// Disable foreign keys and triggers for all tables.
 	// Deprecated: disable foreign keys was created to aid in migrating off
 	// of the test-only in-memory database. Do not use this in new code.
@@ -340,6 +389,9 @@ type sqlcQuerier interface {
 	GetActiveAISeatCount(ctx context.Context) (int64, error)
 	GetActiveChatsByAgentID(ctx context.Context, agentID uuid.UUID) ([]Chat, error)
 	GetActivePresetPrebuildSchedules(ctx context.Context) ([]TemplateVersionPresetPrebuildSchedule, error)
+	GetActiveSharedVolumeAttachmentsBySharedVolumeID(ctx context.Context, sharedVolumeID uuid.UUID) ([]GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow, error)
+	GetActiveTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateVersionRollout, error)
+	GetActiveTemplateVersionRollouts(ctx context.Context) ([]TemplateVersionRollout, error)
 	GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error)
 	GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error)
 	// For PG Coordinator HTMLDebug
@@ -369,6 +421,12 @@ type sqlcQuerier interface {
 	// auto-archive. Activity is computed across the root family. The query
 	// limits roots, not total family members.
 	GetAutoArchiveInactiveChatCandidates(ctx context.Context, arg GetAutoArchiveInactiveChatCandidatesParams) ([]GetAutoArchiveInactiveChatCandidatesRow, error)
+	// GetAutostopInsights returns, per template, how long workspaces ran and how
+	// long they were stopped by scheduling policies within [start_time, end_time).
+	// A workspace is running from a successful start build until its next
+	// successful build, and stopped by a policy from a successful stop build with
+	// the autostop, dormancy or failedstop reason until its next successful build.
+	GetAutostopInsights(ctx context.Context, arg GetAutostopInsightsParams) ([]GetAutostopInsightsRow, error)
 	GetBoundaryLogByID(ctx context.Context, id uuid.UUID) (BoundaryLog, error)
 	GetBoundarySessionByID(ctx context.Context, id uuid.UUID) (GetBoundarySessionByIDRow, error)
 	GetChatACLByID(ctx context.Context, id uuid.UUID) (GetChatACLByIDRow, error)
@@ -547,6 +605,7 @@ type sqlcQuerier interface {
 	GetEnabledChatModelConfigByID(ctx context.Context, id uuid.UUID) (ChatModelConfig, error)
 	GetEnabledChatModelConfigs(ctx context.Context) ([]GetEnabledChatModelConfigsRow, error)
 	GetEnabledMCPServerConfigs(ctx context.Context) ([]MCPServerConfig, error)
+	GetExpiredWorkspaceLeases(ctx context.Context, arg GetExpiredWorkspaceLeasesParams) ([]WorkspaceLease, error)
 	// GetExternalAgentTokensByTemplateID returns the auth tokens for all
 	// non-deleted external agents on the latest build of every running workspace
 	// of the given template. "Running" means the latest build has
@@ -610,6 +669,8 @@ type sqlcQuerier interface {
 	// (group_id == organization_id) is included. Returns no rows when the user has
 	// no budgeted groups. Callers should treat sql.ErrNoRows as "no group budget".
 	GetHighestGroupAIBudgetByUser(ctx context.Context, userID uuid.UUID) (GetHighestGroupAIBudgetByUserRow, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetInProgressTemplateWorkspaceRestarts(ctx context.Context) ([]TemplateWorkspaceRestart, error)
 	GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (InboxNotification, error)
 	// Fetches inbox notifications for a user filtered by templates and targets
 	// param user_id: The user ID
@@ -620,7 +681,12 @@ type sqlcQuerier interface {
 	GetLastChatMessageByRole(ctx context.Context, arg GetLastChatMessageByRoleParams) (ChatMessage, error)
 	GetLastUpdateCheck(ctx context.Context) (string, error)
 	GetLatestCryptoKeyByFeature(ctx context.Context, feature CryptoKeyFeature) (CryptoKey, error)
+	GetLatestUserWorkspaceReportByUserID(ctx context.Context, userID uuid.UUID) (UserWorkspaceReport, error)
+	// Returns the current milestone of each of the given agents.
+	GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentBootstrapProgress, error)
 	GetLatestWorkspaceAgentContextSnapshot(ctx context.Context, workspaceAgentID uuid.UUID) (WorkspaceAgentContextSnapshot, error)
+	// Returns the current status of each service of the given agents.
+	GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentServiceTransition, error)
 	GetLatestWorkspaceAppStatusByAppID(ctx context.Context, appID uuid.UUID) (WorkspaceAppStatus, error)
 	// id DESC is a stability tiebreaker, not an insertion-order signal: back-to-back
 	// inserts can share a created_at on platforms with coarse time.Now() resolution,
@@ -630,6 +696,9 @@ type sqlcQuerier interface {
 	GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error)
 	GetLatestWorkspaceBuildWithStatusByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (GetLatestWorkspaceBuildWithStatusByWorkspaceIDRow, error)
 	GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error)
+	// Returns the resources that still existed at their cloud provider after the
+	// workspace that created them was deleted, most recently verified first.
+	GetLeakedWorkspaceCloudResources(ctx context.Context) ([]GetLeakedWorkspaceCloudResourcesRow, error)
 	GetLicenseByID(ctx context.Context, id int32) (License, error)
 	GetLicenses(ctx context.Context) ([]License, error)
 	GetLogoURL(ctx context.Context) (string, error)
@@ -643,9 +712,13 @@ type sqlcQuerier interface {
 	// when the transaction ends.
 	GetNextPendingWorkspaceBuildOrchestrationForUpdate(ctx context.Context) (WorkspaceBuildOrchestration, error)
 	GetNotificationMessagesByStatus(ctx context.Context, arg GetNotificationMessagesByStatusParams) ([]NotificationMessage, error)
+	// Returns the most recent messages of a notification template, optionally
+	// filtered by delivery method, to report on their delivery status.
+	GetNotificationMessagesByTemplateID(ctx context.Context, arg GetNotificationMessagesByTemplateIDParams) ([]NotificationMessage, error)
 	// Fetch the notification report generator log indicating recent activity.
 	GetNotificationReportGeneratorLogByTemplate(ctx context.Context, templateID uuid.UUID) (NotificationReportGeneratorLog, error)
 	GetNotificationTemplateByID(ctx context.Context, id uuid.UUID) (NotificationTemplate, error)
+	GetNotificationTemplateWebhookByTemplateID(ctx context.Context, notificationTemplateID uuid.UUID) (NotificationTemplateWebhook, error)
 	GetNotificationTemplatesByKind(ctx context.Context, kind NotificationTemplateKind) ([]NotificationTemplate, error)
 	GetNotificationsSettings(ctx context.Context) (string, error)
 	GetOAuth2GithubDefaultEligible(ctx context.Context) (bool, error)
@@ -675,13 +748,25 @@ type sqlcQuerier interface {
 	// GetOrganizationsWithPrebuildStatus returns organizations with prebuilds configured and their
 	// membership status for the prebuilds system user (org membership, group existence, group membership).
 	GetOrganizationsWithPrebuildStatus(ctx context.Context, arg GetOrganizationsWithPrebuildStatusParams) ([]GetOrganizationsWithPrebuildStatusRow, error)
+	// Returns the workspaces whose owner has been deleted, or is no longer a
+	// member of the organization of the workspace. Such workspaces can no longer
+	// be managed by their owner, and are left behind when users are removed
+	// outside of the normal offboarding flow.
+	GetOrphanedWorkspaces(ctx context.Context) ([]GetOrphanedWorkspacesRow, error)
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
+	// GetPrebuildClaimInsights reports, per preset, how many prebuilt workspace
+	// claims were attempted within the given interval, how many of them succeeded,
+	// why the others missed, and how long claimed workspaces took to become ready.
+	GetPrebuildClaimInsights(ctx context.Context, arg GetPrebuildClaimInsightsParams) ([]GetPrebuildClaimInsightsRow, error)
 	GetPrebuildMetrics(ctx context.Context) ([]GetPrebuildMetricsRow, error)
 	GetPrebuildsSettings(ctx context.Context) (string, error)
 	GetPresetByID(ctx context.Context, presetID uuid.UUID) (GetPresetByIDRow, error)
 	GetPresetByWorkspaceBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (TemplateVersionPreset, error)
 	GetPresetParametersByPresetID(ctx context.Context, presetID uuid.UUID) ([]TemplateVersionPresetParameter, error)
 	GetPresetParametersByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPresetParameter, error)
+	// GetPresetPrebuildPoolState summarizes the prebuilt workspaces of a preset. It
+	// is used to explain why no prebuilt workspace of the preset could be claimed.
+	GetPresetPrebuildPoolState(ctx context.Context, arg GetPresetPrebuildPoolStateParams) (GetPresetPrebuildPoolStateRow, error)
 	// GetPresetsAtFailureLimit groups workspace builds by preset ID.
 	// Each preset is associated with exactly one template version ID.
 	// For each preset, the query checks the last hard_limit builds.
@@ -708,6 +793,7 @@ type sqlcQuerier interface {
 	GetPresetsBackoff(ctx context.Context, lookback time.Time) ([]GetPresetsBackoffRow, error)
 	GetPresetsByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPreset, error)
 	GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error)
+	GetProvisionerCacheSettings(ctx context.Context) (string, error)
 	GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error)
 	GetProvisionerDaemonsByOrganization(ctx context.Context, arg GetProvisionerDaemonsByOrganizationParams) ([]ProvisionerDaemon, error)
 	// Current job information.
@@ -720,12 +806,26 @@ type sqlcQuerier interface {
 	// Gets a provisioner job by ID with exclusive lock.
 	// Blocks until the row is available for update.
 	GetProvisionerJobByIDWithLock(ctx context.Context, id uuid.UUID) (ProvisionerJob, error)
+	// Returns the error-level logs of the given jobs. Used to summarize the root
+	// cause of failed builds without fetching their full logs.
+	GetProvisionerJobErrorLogsByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]ProvisionerJobLog, error)
+	GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (ProvisionerJobLogArchive, error)
+	GetProvisionerJobLogArchivesByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]ProvisionerJobLogArchive, error)
 	GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobTiming, error)
 	GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, arg GetProvisionerJobsByIDsWithQueuePositionParams) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error)
+	// Returns the in-flight provisioner jobs started by a user across all
+	// organizations. The columns match
+	// GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner.
+	GetProvisionerJobsByInitiatorWithQueuePosition(ctx context.Context, initiatorID uuid.UUID) ([]GetProvisionerJobsByInitiatorWithQueuePositionRow, error)
 	GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner(ctx context.Context, arg GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerParams) ([]GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerRow, error)
 	GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error)
 	// To avoid repeatedly attempting to reap the same jobs, we randomly order and limit to @max_jobs.
 	GetProvisionerJobsToBeReaped(ctx context.Context, arg GetProvisionerJobsToBeReapedParams) ([]ProvisionerJob, error)
+	// Returns completed provisioner jobs whose logs outlived the build log
+	// retention of their template, or the deployment-wide retention if the
+	// template does not set one. The logs of the latest build of each workspace
+	// are always kept. Jobs that were already archived are skipped.
+	GetProvisionerJobsWithExpiredLogs(ctx context.Context, arg GetProvisionerJobsWithExpiredLogsParams) ([]uuid.UUID, error)
 	GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (ProvisionerKey, error)
 	GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (ProvisionerKey, error)
 	GetProvisionerKeyByName(ctx context.Context, arg GetProvisionerKeyByNameParams) (ProvisionerKey, error)
@@ -739,6 +839,15 @@ type sqlcQuerier interface {
 	GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error)
 	GetRunningPrebuiltWorkspaces(ctx context.Context) ([]GetRunningPrebuiltWorkspacesRow, error)
 	GetRuntimeConfig(ctx context.Context, key string) (string, error)
+	// Used by dbcrypt to encrypt, re-encrypt or decrypt the values of sensitive
+	// parameters.
+	GetSensitiveWorkspaceBuildParameters(ctx context.Context) ([]WorkspaceBuildParameter, error)
+	GetSharedVolumeAttachmentByID(ctx context.Context, id uuid.UUID) (SharedVolumeAttachment, error)
+	GetSharedVolumeByID(ctx context.Context, id uuid.UUID) (SharedVolume, error)
+	GetSharedVolumeByOrganizationIDAndName(ctx context.Context, arg GetSharedVolumeByOrganizationIDAndNameParams) (SharedVolume, error)
+	// Returns the shared volumes of an organization along with the number of
+	// active attachments and the workspace holding the read-write lock, if any.
+	GetSharedVolumesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]GetSharedVolumesByOrganizationIDRow, error)
 	// Find chats that appear stuck and need recovery:
 	//   1. Running chats whose heartbeat has expired (worker crash).
 	//   2. requires_action chats past the timeout threshold (client
@@ -773,6 +882,12 @@ type sqlcQuerier interface {
 	//   because each resume cycle provisions a new app ID. This ensures
 	//   pre-pause statuses contribute to idle duration and active duration.
 	GetTelemetryTaskEvents(ctx context.Context, arg GetTelemetryTaskEventsParams) ([]GetTelemetryTaskEventsRow, error)
+	// GetTemplateActiveDeveloperDays returns the users that were active in
+	// workspaces of each template, for each day between start and end time. A user
+	// counts as active on a day when their session and app usage of the template
+	// adds up to at least min_usage_mins minutes. Days start at the time of day of
+	// start_time, so its time zone decides the day boundaries.
+	GetTemplateActiveDeveloperDays(ctx context.Context, arg GetTemplateActiveDeveloperDaysParams) ([]GetTemplateActiveDeveloperDaysRow, error)
 	// GetTemplateAppInsights returns the aggregate usage of each app in a given
 	// timeframe. The result can be filtered on template_ids, meaning only user data
 	// from workspaces based on those templates will be included.
@@ -783,6 +898,12 @@ type sqlcQuerier interface {
 	GetTemplateAverageBuildTime(ctx context.Context, templateID uuid.NullUUID) (GetTemplateAverageBuildTimeRow, error)
 	GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error)
 	GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error)
+	GetTemplateHealthReportsByTemplateID(ctx context.Context, arg GetTemplateHealthReportsByTemplateIDParams) ([]TemplateHealthReport, error)
+	// Returns the health of the templates that had builds since @since or that
+	// have workspaces. Builds are counted since @since, while workspaces are
+	// counted as they are now. Prebuilt workspaces are left out, as they are
+	// managed by the reconciler rather than by users.
+	GetTemplateHealthStats(ctx context.Context, since time.Time) ([]GetTemplateHealthStatsRow, error)
 	// GetTemplateInsights returns the aggregate user-produced usage of all
 	// workspaces in a given timeframe. The template IDs, active users, and
 	// usage_seconds all reflect any usage in the template, including apps.
@@ -801,11 +922,13 @@ type sqlcQuerier interface {
 	// GetTemplateInsightsByTemplate is used for Prometheus metrics. Keep
 	// in sync with GetTemplateInsights and UpsertTemplateUsageStats.
 	GetTemplateInsightsByTemplate(ctx context.Context, arg GetTemplateInsightsByTemplateParams) ([]GetTemplateInsightsByTemplateRow, error)
+	GetTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) (TemplateNetworkPolicy, error)
 	// GetTemplateParameterInsights does for each template in a given timeframe,
 	// look for the latest workspace build (for every workspace) that has been
 	// created in the timeframe and return the aggregate usage counts of parameter
 	// values.
 	GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error)
+	GetTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplatePresetCohort, error)
 	// GetTemplatePresetsWithPrebuilds retrieves template versions with configured presets and prebuilds.
 	// It also returns the number of desired instances for each preset.
 	// If template_id is specified, only template versions associated with that template will be returned.
@@ -816,17 +939,45 @@ type sqlcQuerier interface {
 	// score is computed in Go (see listtemplates.go) so the ranking policy and
 	// its confidence thresholds live in one place.
 	GetTemplateRankingSignalsByOwnerID(ctx context.Context, arg GetTemplateRankingSignalsByOwnerIDParams) ([]GetTemplateRankingSignalsByOwnerIDRow, error)
+	GetTemplateSecretByID(ctx context.Context, id uuid.UUID) (TemplateSecret, error)
+	GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg GetTemplateSecretByTemplateIDAndNameParams) (TemplateSecret, error)
+	// Returns the secrets of all templates. Used by the dbcrypt key rotation
+	// utility.
+	GetTemplateSecrets(ctx context.Context) ([]TemplateSecret, error)
+	// Returns all columns including the secret value. The API only returns
+	// metadata, and the agent fetches the values referenced by its scripts.
+	GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateSecret, error)
 	GetTemplateUsageStats(ctx context.Context, arg GetTemplateUsageStatsParams) ([]TemplateUsageStat, error)
+	GetTemplateUserPresetByID(ctx context.Context, id uuid.UUID) (TemplateUserPreset, error)
+	GetTemplateUserPresetsByTemplateIDAndUserID(ctx context.Context, arg GetTemplateUserPresetsByTemplateIDAndUserIDParams) ([]TemplateUserPreset, error)
 	GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error)
 	GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error)
+	// Counts the completed start builds of a template version created since the
+	// given time, and how many of them failed. Used to compute the failure rate
+	// of a rollout stage.
+	GetTemplateVersionRolloutBuildStats(ctx context.Context, arg GetTemplateVersionRolloutBuildStatsParams) (GetTemplateVersionRolloutBuildStatsRow, error)
+	GetTemplateVersionRolloutByID(ctx context.Context, id uuid.UUID) (TemplateVersionRollout, error)
+	GetTemplateVersionRolloutsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateVersionRollout, error)
+	GetTemplateVersionScanByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (TemplateVersionScan, error)
 	GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (TemplateVersionTerraformValue, error)
 	GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error)
 	GetTemplateVersionWorkspaceTags(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionWorkspaceTag, error)
 	GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error)
 	GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error)
 	GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error)
+	// Counts the builds of a restart by the status of their provisioner job.
+	// Builds that could not be created count as failed. The completed and failed
+	// builds since the given time are used to compute the failure rate.
+	GetTemplateWorkspaceRestartBuildStats(ctx context.Context, arg GetTemplateWorkspaceRestartBuildStatsParams) (GetTemplateWorkspaceRestartBuildStatsRow, error)
+	GetTemplateWorkspaceRestartByID(ctx context.Context, id uuid.UUID) (TemplateWorkspaceRestart, error)
+	// Returns the running workspaces of the template that are not on the given
+	// version and have not been restarted by the restart yet. The least recently
+	// used workspaces are returned first, since they are the least likely to be
+	// in use. Dormant workspaces and prebuilt workspaces are skipped.
+	GetTemplateWorkspaceRestartCandidates(ctx context.Context, arg GetTemplateWorkspaceRestartCandidatesParams) ([]GetTemplateWorkspaceRestartCandidatesRow, error)
+	GetTemplateWorkspaceRestartsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateWorkspaceRestart, error)
 	GetTemplates(ctx context.Context) ([]Template, error)
 	GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error)
 	// Gets the total number of managed agents created between two dates. Uses the
@@ -839,6 +990,10 @@ type sqlcQuerier interface {
 	// inclusive.
 	GetTotalUsageDCManagedAgentsV1(ctx context.Context, arg GetTotalUsageDCManagedAgentsV1Params) (int64, error)
 	GetUnexpiredLicenses(ctx context.Context) ([]License, error)
+	// Returns the unverified resources of workspaces whose latest build is a
+	// successful deletion that completed before completed_before, along with the
+	// ID of the deletion build.
+	GetUnverifiedWorkspaceCloudResources(ctx context.Context, arg GetUnverifiedWorkspaceCloudResourcesParams) ([]GetUnverifiedWorkspaceCloudResourcesRow, error)
 	GetUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (UserAIBudgetOverride, error)
 	GetUserAIProviderKeyByProviderID(ctx context.Context, arg GetUserAIProviderKeyByProviderIDParams) (UserAIProviderKey, error)
 	// GetUserAIProviderKeys is used by dbcrypt key rotation. Request paths should use
@@ -887,6 +1042,9 @@ type sqlcQuerier interface {
 	// considered (global behavior). Otherwise only groups within the
 	// specified organization are considered.
 	GetUserGroupSpendLimit(ctx context.Context, arg GetUserGroupSpendLimitParams) (int64, error)
+	GetUserImpersonationByAPIKeyID(ctx context.Context, apiKeyID string) (UserImpersonation, error)
+	GetUserImpersonationByID(ctx context.Context, id uuid.UUID) (UserImpersonation, error)
+	GetUserImpersonationsByUserID(ctx context.Context, userID uuid.UUID) ([]UserImpersonation, error)
 	// GetUserLatencyInsights returns the median and 95th percentile connection
 	// latency that users have experienced. The result can be filtered on
 	// template_ids, meaning only user data from workspaces based on those templates
@@ -896,6 +1054,8 @@ type sqlcQuerier interface {
 	GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error)
 	GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error)
 	GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error)
+	GetUserNotificationScopedPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationScopedPreference, error)
+	GetUserSchedulePause(ctx context.Context, userID uuid.UUID) (UserSchedulePause, error)
 	GetUserSecretByID(ctx context.Context, id uuid.UUID) (UserSecret, error)
 	GetUserSecretByUserIDAndName(ctx context.Context, arg GetUserSecretByUserIDAndNameParams) (UserSecret, error)
 	// Returns deployment-wide aggregates for the telemetry snapshot.
@@ -933,25 +1093,64 @@ type sqlcQuerier interface {
 	GetUserTaskNotificationAlertDismissed(ctx context.Context, userID uuid.UUID) (bool, error)
 	GetUserThinkingDisplayMode(ctx context.Context, userID uuid.UUID) (string, error)
 	GetUserWorkspaceBuildParameters(ctx context.Context, arg GetUserWorkspaceBuildParametersParams) ([]GetUserWorkspaceBuildParametersRow, error)
+	// Returns the workspaces of active users that had builds since @since, that
+	// will be deleted before @until, or that will become dormant before @until if
+	// they are not used. Prebuilt workspaces are left out, as they are managed by
+	// the reconciler rather than by users.
+	GetUserWorkspaceReportStats(ctx context.Context, arg GetUserWorkspaceReportStatsParams) ([]GetUserWorkspaceReportStatsRow, error)
+	GetUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, userID uuid.UUID) (bool, error)
 	// This will never return deleted users.
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error)
 	// This shouldn't check for deleted, because it's frequently used
 	// to look up references to actions. eg. a user could build a workspace
 	// for another user, then be deleted... we still want them to appear!
 	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error)
+	GetVCSEventMappingByID(ctx context.Context, id uuid.UUID) (VCSEventMapping, error)
+	// Returns the mappings of all templates. Used by the dbcrypt key rotation
+	// utility.
+	GetVCSEventMappings(ctx context.Context) ([]VCSEventMapping, error)
+	// Returns the mappings of a repository along with their signing secrets, so
+	// that the VCS webhook receiver can verify events.
+	GetVCSEventMappingsByRepository(ctx context.Context, repository string) ([]VCSEventMapping, error)
+	GetVCSEventMappingsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]VCSEventMapping, error)
 	GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]WebpushSubscription, error)
 	GetWebpushVAPIDKeys(ctx context.Context) (GetWebpushVAPIDKeysRow, error)
 	GetWorkspaceACLByID(ctx context.Context, id uuid.UUID) (GetWorkspaceACLByIDRow, error)
 	GetWorkspaceAgentAndWorkspaceByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentAndWorkspaceByIDRow, error)
+	// Returns all milestones reported by an agent, oldest first.
+	GetWorkspaceAgentBootstrapProgressByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentBootstrapProgress, error)
 	GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error)
+	GetWorkspaceAgentCrashLoopByAgentID(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentCrashLoop, error)
+	GetWorkspaceAgentCrashLoopsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentCrashLoop, error)
 	GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentDevcontainer, error)
 	GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentLifecycleStateByIDRow, error)
 	GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentLogSource, error)
 	GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWorkspaceAgentLogsAfterParams) ([]WorkspaceAgentLog, error)
+	// Returns the number of OOM kills and memory pressure events reported by
+	// each of the given agents since the given time. Agents without events are
+	// omitted.
+	GetWorkspaceAgentMemoryEventCountsByAgentIDs(ctx context.Context, arg GetWorkspaceAgentMemoryEventCountsByAgentIDsParams) ([]GetWorkspaceAgentMemoryEventCountsByAgentIDsRow, error)
+	// Returns the memory events reported by the given agents since the given
+	// time, newest first.
+	GetWorkspaceAgentMemoryEventsByAgentIDs(ctx context.Context, arg GetWorkspaceAgentMemoryEventsByAgentIDsParams) ([]WorkspaceAgentMemoryEvent, error)
 	GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorkspaceAgentMetadataParams) ([]WorkspaceAgentMetadatum, error)
+	// Returns the values of an agent metadata key collected since the given time,
+	// oldest first. When bucket_seconds is positive, only the latest value of each
+	// bucket of that many seconds is returned, which downsamples long histories.
+	GetWorkspaceAgentMetadataHistory(ctx context.Context, arg GetWorkspaceAgentMetadataHistoryParams) ([]GetWorkspaceAgentMetadataHistoryRow, error)
+	// Returns the number of blocked connections reported by each of the given
+	// agents since the given time. Agents without violations are omitted.
+	GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(ctx context.Context, arg GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsParams) ([]GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow, error)
+	// Returns the violations reported by the given agents since the given time,
+	// newest first.
+	GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(ctx context.Context, arg GetWorkspaceAgentNetworkPolicyViolationsByAgentIDsParams) ([]WorkspaceAgentNetworkPolicyViolation, error)
 	GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
 	GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]GetWorkspaceAgentScriptTimingsByBuildIDRow, error)
 	GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]GetWorkspaceAgentScriptsByAgentIDsRow, error)
+	// Returns every status transition of the services of the given agents,
+	// oldest first.
+	GetWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentServiceTransition, error)
+	GetWorkspaceAgentServicesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentService, error)
 	GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error)
 	GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsAndLabelsRow, error)
 	// `minute_buckets` could return 0 rows if there are no usage stats since `created_at`.
@@ -966,17 +1165,22 @@ type sqlcQuerier interface {
 	GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgent, error)
 	GetWorkspaceAgentsInLatestBuildByWorkspaceIDs(ctx context.Context, workspaceIds []uuid.UUID) ([]GetWorkspaceAgentsInLatestBuildByWorkspaceIDsRow, error)
 	GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg GetWorkspaceAppByAgentIDAndSlugParams) (WorkspaceApp, error)
+	GetWorkspaceAppShareLinkByID(ctx context.Context, id uuid.UUID) (WorkspaceAppShareLink, error)
+	GetWorkspaceAppShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAppShareLink, error)
 	GetWorkspaceAppStatusesByAppIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAppStatus, error)
 	GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error)
 	GetWorkspaceBuildAgentsByInstanceID(ctx context.Context, authInstanceID string) ([]GetWorkspaceBuildAgentsByInstanceIDRow, error)
+	GetWorkspaceBuildAnnotationsByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]GetWorkspaceBuildAnnotationsByBuildIDsRow, error)
 	GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error)
+	GetWorkspaceBuildInputsByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (WorkspaceBuildInput, error)
 	// Returns build metadata for e2e workspace build duration metrics.
 	// Also checks if all agents are ready and returns the worst status.
 	GetWorkspaceBuildMetricsByResourceID(ctx context.Context, id uuid.UUID) (GetWorkspaceBuildMetricsByResourceIDRow, error)
+	GetWorkspaceBuildParameterChangesByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]WorkspaceBuildParameterChange, error)
 	GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error)
 	// Fetches the provisioner state of a workspace build, joined through to the
 	// template so that dbauthz can enforce policy.ActionUpdate on the template.
@@ -991,8 +1195,27 @@ type sqlcQuerier interface {
 	GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWorkspaceByOwnerIDAndNameParams) (Workspace, error)
 	GetWorkspaceByResourceID(ctx context.Context, resourceID uuid.UUID) (Workspace, error)
 	GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (Workspace, error)
+	GetWorkspaceCloudResourcesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceCloudResource, error)
+	// // Returns the workspaces whose DNS record is out of date: running workspaces
+	// // without a record, or with a record under another name or target, and
+	// // records of workspaces that are no longer running. A workspace is running
+	// // once the provisioner job of its latest start build succeeded. Prebuilt
+	// // workspaces are never registered.
+	GetWorkspaceDNSRecordChanges(ctx context.Context, arg GetWorkspaceDNSRecordChangesParams) ([]GetWorkspaceDNSRecordChangesRow, error)
+	GetWorkspaceDNSRecordsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceDNSRecord, error)
+	GetWorkspaceDormancyExemptionByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceDormancyExemption, error)
+	GetWorkspaceDormancyHookByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceDormancyHook, error)
+	// Returns per workspace, per day egress for the UTC days that overlap
+	// [start_time, end_time), optionally filtered by template.
+	GetWorkspaceEgressInsights(ctx context.Context, arg GetWorkspaceEgressInsightsParams) ([]GetWorkspaceEgressInsightsRow, error)
+	GetWorkspaceLeaseByID(ctx context.Context, id uuid.UUID) (WorkspaceLease, error)
+	GetWorkspaceLifetimeWarningByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceLifetimeWarning, error)
 	GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceModule, error)
 	GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceModule, error)
+	GetWorkspaceMonthlyCost(ctx context.Context, arg GetWorkspaceMonthlyCostParams) (WorkspaceMonthlyCost, error)
+	GetWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) (WorkspaceNetworkPolicy, error)
+	GetWorkspacePortForwardACL(ctx context.Context, arg GetWorkspacePortForwardACLParams) (WorkspacePortForwardACL, error)
+	GetWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortForwardACL, error)
 	GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error)
 	// Finds a workspace proxy that has an access URL or app hostname that matches
 	// the provided hostname. This is to check if a hostname matches any workspace
@@ -1004,6 +1227,9 @@ type sqlcQuerier interface {
 	GetWorkspaceProxyByHostname(ctx context.Context, arg GetWorkspaceProxyByHostnameParams) (WorkspaceProxy, error)
 	GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error)
 	GetWorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error)
+	// Returns the number of non-deleted workspaces assigned to each region.
+	GetWorkspaceRegionAssignmentCounts(ctx context.Context) ([]GetWorkspaceRegionAssignmentCountsRow, error)
+	GetWorkspaceRegionAssignmentsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceRegionAssignment, error)
 	GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (WorkspaceResource, error)
 	GetWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResourceMetadatum, error)
 	GetWorkspaceResourceMetadataCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResourceMetadatum, error)
@@ -1022,6 +1248,11 @@ type sqlcQuerier interface {
 	// dormancy mark (which has no build transition), or a one-time autostop
 	// reminder notification (which only stamps a marker, no transition).
 	GetWorkspacesEligibleForLifecycleAction(ctx context.Context, now time.Time) ([]GetWorkspacesEligibleForLifecycleActionRow, error)
+	// // Returns the workspaces whose cost in the month exceeds the per-workspace
+	// // budget, or whose owner's workspaces together exceed the per-user budget. A
+	// // budget of 0 is disabled. Deleted workspaces are not returned, but their cost
+	// // still counts towards the per-user budget.
+	GetWorkspacesExceedingCostBudget(ctx context.Context, arg GetWorkspacesExceedingCostBudgetParams) ([]GetWorkspacesExceedingCostBudgetRow, error)
 	GetWorkspacesForWorkspaceMetrics(ctx context.Context) ([]GetWorkspacesForWorkspaceMetricsRow, error)
 	// Reports whether the given file is referenced as cached module files by any
 	// template version in the given organization. Used to authorize provisioner
@@ -1099,6 +1330,9 @@ type sqlcQuerier interface {
 	InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error)
 	InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error)
 	InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error
+	// Inserts the key, replacing an expired key of the same user and operation.
+	// Returns no rows if an unexpired key already exists.
+	InsertIdempotencyKey(ctx context.Context, arg InsertIdempotencyKeyParams) (IdempotencyKey, error)
 	InsertInboxNotification(ctx context.Context, arg InsertInboxNotificationParams) (InboxNotification, error)
 	InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error)
 	InsertMCPServerConfig(ctx context.Context, arg InsertMCPServerConfigParams) (MCPServerConfig, error)
@@ -1114,14 +1348,18 @@ type sqlcQuerier interface {
 	InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error)
 	InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error)
 	InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error)
+	InsertPrebuildClaimAttempt(ctx context.Context, arg InsertPrebuildClaimAttemptParams) (PrebuildClaimAttempt, error)
 	InsertPreset(ctx context.Context, arg InsertPresetParams) (TemplateVersionPreset, error)
 	InsertPresetParameters(ctx context.Context, arg InsertPresetParametersParams) ([]TemplateVersionPresetParameter, error)
 	InsertPresetPrebuildSchedule(ctx context.Context, arg InsertPresetPrebuildScheduleParams) (TemplateVersionPresetPrebuildSchedule, error)
 	InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error)
+	InsertProvisionerJobLogArchive(ctx context.Context, arg InsertProvisionerJobLogArchiveParams) error
 	InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error)
 	InsertProvisionerJobTimings(ctx context.Context, arg InsertProvisionerJobTimingsParams) ([]ProvisionerJobTiming, error)
 	InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error)
 	InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error)
+	InsertSharedVolume(ctx context.Context, arg InsertSharedVolumeParams) (SharedVolume, error)
+	InsertSharedVolumeAttachment(ctx context.Context, arg InsertSharedVolumeAttachmentParams) (SharedVolumeAttachment, error)
 	InsertTask(ctx context.Context, arg InsertTaskParams) (TaskTable, error)
 	InsertTelemetryItemIfNotExists(ctx context.Context, arg InsertTelemetryItemIfNotExistsParams) error
 	// Inserts a new lock row into the telemetry_locks table. Replicas should call
@@ -1131,11 +1369,17 @@ type sqlcQuerier interface {
 	// attempt to generate or publish the event to the telemetry service.
 	InsertTelemetryLock(ctx context.Context, arg InsertTelemetryLockParams) error
 	InsertTemplate(ctx context.Context, arg InsertTemplateParams) error
+	InsertTemplateHealthReport(ctx context.Context, arg InsertTemplateHealthReportParams) (TemplateHealthReport, error)
+	InsertTemplatePresetCohort(ctx context.Context, arg InsertTemplatePresetCohortParams) (TemplatePresetCohort, error)
+	InsertTemplateSecret(ctx context.Context, arg InsertTemplateSecretParams) (TemplateSecret, error)
 	InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error
 	InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error)
+	InsertTemplateVersionRollout(ctx context.Context, arg InsertTemplateVersionRolloutParams) (TemplateVersionRollout, error)
 	InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg InsertTemplateVersionTerraformValuesByJobIDParams) error
 	InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error)
 	InsertTemplateVersionWorkspaceTag(ctx context.Context, arg InsertTemplateVersionWorkspaceTagParams) (TemplateVersionWorkspaceTag, error)
+	InsertTemplateWorkspaceRestart(ctx context.Context, arg InsertTemplateWorkspaceRestartParams) (TemplateWorkspaceRestart, error)
+	InsertTemplateWorkspaceRestartBuild(ctx context.Context, arg InsertTemplateWorkspaceRestartBuildParams) error
 	// Duplicate events are ignored intentionally to allow for multiple replicas to
 	// publish heartbeat events.
 	InsertUsageEvent(ctx context.Context, arg InsertUsageEventParams) error
@@ -1143,8 +1387,11 @@ type sqlcQuerier interface {
 	// InsertUserGroupsByID adds a user to all provided groups, if they exist.
 	// If there is a conflict, the user is already a member
 	InsertUserGroupsByID(ctx context.Context, arg InsertUserGroupsByIDParams) ([]uuid.UUID, error)
+	InsertUserImpersonation(ctx context.Context, arg InsertUserImpersonationParams) (UserImpersonation, error)
 	InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error)
 	InsertUserSkill(ctx context.Context, arg InsertUserSkillParams) (UserSkill, error)
+	InsertUserWorkspaceReport(ctx context.Context, arg InsertUserWorkspaceReportParams) (UserWorkspaceReport, error)
+	InsertVCSEventMapping(ctx context.Context, arg InsertVCSEventMappingParams) (VCSEventMapping, error)
 	InsertVolumeResourceMonitor(ctx context.Context, arg InsertVolumeResourceMonitorParams) (WorkspaceAgentVolumeResourceMonitor, error)
 	// Inserts or updates a webpush subscription. The (user_id, endpoint) pair
 	// is unique; re-subscribing the same endpoint replaces the keys instead of
@@ -1153,18 +1400,35 @@ type sqlcQuerier interface {
 	InsertWebpushSubscription(ctx context.Context, arg InsertWebpushSubscriptionParams) (WebpushSubscription, error)
 	InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (WorkspaceTable, error)
 	InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error)
+	InsertWorkspaceAgentBootstrapProgress(ctx context.Context, arg InsertWorkspaceAgentBootstrapProgressParams) (WorkspaceAgentBootstrapProgress, error)
 	InsertWorkspaceAgentDevcontainers(ctx context.Context, arg InsertWorkspaceAgentDevcontainersParams) ([]WorkspaceAgentDevcontainer, error)
 	InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error)
 	InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWorkspaceAgentLogsParams) ([]WorkspaceAgentLog, error)
+	InsertWorkspaceAgentMemoryEvent(ctx context.Context, arg InsertWorkspaceAgentMemoryEventParams) (WorkspaceAgentMemoryEvent, error)
 	InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error
+	// Records a batch of agent metadata values. Values that were already recorded
+	// for the same key and collection time are ignored.
+	InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg InsertWorkspaceAgentMetadataHistoryParams) error
+	InsertWorkspaceAgentNetworkPolicyViolations(ctx context.Context, arg InsertWorkspaceAgentNetworkPolicyViolationsParams) error
 	InsertWorkspaceAgentScriptTimings(ctx context.Context, arg InsertWorkspaceAgentScriptTimingsParams) (WorkspaceAgentScriptTiming, error)
 	InsertWorkspaceAgentScripts(ctx context.Context, arg InsertWorkspaceAgentScriptsParams) ([]WorkspaceAgentScript, error)
+	InsertWorkspaceAgentServiceTransition(ctx context.Context, arg InsertWorkspaceAgentServiceTransitionParams) (WorkspaceAgentServiceTransition, error)
+	InsertWorkspaceAgentServices(ctx context.Context, arg InsertWorkspaceAgentServicesParams) ([]WorkspaceAgentService, error)
 	InsertWorkspaceAgentStats(ctx context.Context, arg InsertWorkspaceAgentStatsParams) error
+	InsertWorkspaceAppShareLink(ctx context.Context, arg InsertWorkspaceAppShareLinkParams) (WorkspaceAppShareLink, error)
 	InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error
 	InsertWorkspaceAppStatus(ctx context.Context, arg InsertWorkspaceAppStatusParams) (WorkspaceAppStatus, error)
 	InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error
+	InsertWorkspaceBuildAnnotation(ctx context.Context, arg InsertWorkspaceBuildAnnotationParams) (WorkspaceBuildAnnotation, error)
+	// The inputs of a build are immutable, so a build whose job is acquired
+	// // again keeps the inputs captured first.
+	InsertWorkspaceBuildInputs(ctx context.Context, arg InsertWorkspaceBuildInputsParams) error
 	InsertWorkspaceBuildOrchestration(ctx context.Context, arg InsertWorkspaceBuildOrchestrationParams) (WorkspaceBuildOrchestration, error)
+	InsertWorkspaceBuildParameterChanges(ctx context.Context, arg InsertWorkspaceBuildParameterChangesParams) error
+	// Sensitive and ValueKeyID may be shorter than Name and Value. Missing
+	// entries insert parameters that are not sensitive and not encrypted.
 	InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error
+	InsertWorkspaceLease(ctx context.Context, arg InsertWorkspaceLeaseParams) (WorkspaceLease, error)
 	InsertWorkspaceModule(ctx context.Context, arg InsertWorkspaceModuleParams) (WorkspaceModule, error)
 	InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error)
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
@@ -1265,6 +1529,9 @@ type sqlcQuerier interface {
 	PopNextQueuedMessage(ctx context.Context, chatID uuid.UUID) (ChatQueuedMessage, error)
 	ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error
 	RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error)
+	// Marks a lease as released. Returns no rows if the lease was already
+	// released, so concurrent releases tear the workspace down only once.
+	ReleaseWorkspaceLease(ctx context.Context, arg ReleaseWorkspaceLeaseParams) (WorkspaceLease, error)
 	RemoveUserFromGroups(ctx context.Context, arg RemoveUserFromGroupsParams) ([]uuid.UUID, error)
 	// Mutates only created_at on the target row; ids are unchanged so
 	// consumers can keep tracking queued messages by id.
@@ -1284,7 +1551,16 @@ type sqlcQuerier interface {
 	// limit_source indicates which tier won: 'user', 'group', 'default',
 	// or 'disabled'.
 	ResolveUserChatSpendLimit(ctx context.Context, arg ResolveUserChatSpendLimitParams) (ResolveUserChatSpendLimitRow, error)
+	// Resumes a paused restart. Failures before the restart was resumed no longer
+	// count towards its failure rate.
+	ResumeTemplateWorkspaceRestart(ctx context.Context, arg ResumeTemplateWorkspaceRestartParams) (TemplateWorkspaceRestart, error)
 	RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error
+	// Links that were already revoked are left untouched, so no rows are
+	// returned.
+	RevokeWorkspaceAppShareLink(ctx context.Context, arg RevokeWorkspaceAppShareLinkParams) (WorkspaceAppShareLink, error)
+	// Replaces the secret of a provisioner key. The current secret becomes the
+	// previous secret, which stays valid until previous_secret_expires_at.
+	RotateProvisionerKey(ctx context.Context, arg RotateProvisionerKeyParams) (ProvisionerKey, error)
 	// Note that this selects from the CTE, not the original table. The CTE is named
 	// the same as the original table to trick sqlc into reusing the existing struct
 	// for the table.
@@ -1442,7 +1718,14 @@ type sqlcQuerier interface {
 	// Used by the dbcrypt key rotation utility to re-encrypt or decrypt
 	// rows in place.
 	UpdateEncryptedAIProviderSettings(ctx context.Context, arg UpdateEncryptedAIProviderSettingsParams) (AIProvider, error)
+	// Updates only the encrypted columns of a secret. Used by the dbcrypt key
+	// rotation utility to re-encrypt or decrypt rows in place.
+	UpdateEncryptedTemplateSecret(ctx context.Context, arg UpdateEncryptedTemplateSecretParams) (TemplateSecret, error)
 	UpdateEncryptedUserAIProviderKey(ctx context.Context, arg UpdateEncryptedUserAIProviderKeyParams) (UserAIProviderKey, error)
+	// Updates only the encrypted columns of a mapping. Used by the dbcrypt key
+	// rotation utility to re-encrypt or decrypt rows in place.
+	UpdateEncryptedVCSEventMapping(ctx context.Context, arg UpdateEncryptedVCSEventMappingParams) (VCSEventMapping, error)
+	UpdateEncryptedWorkspaceBuildParameter(ctx context.Context, arg UpdateEncryptedWorkspaceBuildParameterParams) error
 	UpdateExternalAuthLink(ctx context.Context, arg UpdateExternalAuthLinkParams) (ExternalAuthLink, error)
 	// Optimistic lock: only update the row if the refresh token in the database
 	// still matches the one we read before attempting the refresh. This prevents
@@ -1464,6 +1747,7 @@ type sqlcQuerier interface {
 	UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error)
 	UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error)
 	UpdateOrganizationDeletedByID(ctx context.Context, arg UpdateOrganizationDeletedByIDParams) error
+	UpdateOrganizationProvisionerDaemonSettings(ctx context.Context, arg UpdateOrganizationProvisionerDaemonSettingsParams) (Organization, error)
 	UpdateOrganizationWorkspaceSharingSettings(ctx context.Context, arg UpdateOrganizationWorkspaceSharingSettingsParams) (Organization, error)
 	// Cancels all pending provisioner jobs for prebuilt workspaces on a specific preset from an
 	// inactive template version.
@@ -1475,9 +1759,14 @@ type sqlcQuerier interface {
 	UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error
 	UpdateProvisionerJobLogsLength(ctx context.Context, arg UpdateProvisionerJobLogsLengthParams) error
 	UpdateProvisionerJobLogsOverflowed(ctx context.Context, arg UpdateProvisionerJobLogsOverflowedParams) error
+	UpdateProvisionerJobTimeoutByID(ctx context.Context, arg UpdateProvisionerJobTimeoutByIDParams) error
 	UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error
 	UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error
 	UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteWithStartedAtByIDParams) error
+	// Returns a started job to the queue so that another provisioner daemon can
+	// acquire it.
+	UpdateProvisionerJobWithRequeueByID(ctx context.Context, arg UpdateProvisionerJobWithRequeueByIDParams) error
+	UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg UpdateProvisionerKeyLastUsedAtParams) error
 	UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error)
 	UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg UpdateTailnetPeerStatusByCoordinatorParams) ([]uuid.UUID, error)
 	UpdateTaskPrompt(ctx context.Context, arg UpdateTaskPromptParams) (TaskTable, error)
@@ -1488,10 +1777,21 @@ type sqlcQuerier interface {
 	UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error
 	UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error
 	UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error
+	UpdateTemplateSecretByTemplateIDAndName(ctx context.Context, arg UpdateTemplateSecretByTemplateIDAndNameParams) (TemplateSecret, error)
 	UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error
+	UpdateTemplateVersionDeprecationByID(ctx context.Context, arg UpdateTemplateVersionDeprecationByIDParams) error
 	UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error
 	UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error
 	UpdateTemplateVersionFlagsByJobID(ctx context.Context, arg UpdateTemplateVersionFlagsByJobIDParams) error
+	UpdateTemplateVersionRolloutStage(ctx context.Context, arg UpdateTemplateVersionRolloutStageParams) (TemplateVersionRollout, error)
+	// Finishes an in-progress rollout. No rows are returned if the rollout is
+	// already finished.
+	UpdateTemplateVersionRolloutStatus(ctx context.Context, arg UpdateTemplateVersionRolloutStatusParams) (TemplateVersionRollout, error)
+	UpdateTemplateVersionScanByTemplateVersionID(ctx context.Context, arg UpdateTemplateVersionScanByTemplateVersionIDParams) error
+	UpdateTemplateWorkspaceRestartLastBatchAt(ctx context.Context, arg UpdateTemplateWorkspaceRestartLastBatchAtParams) error
+	// Pauses, completes or aborts an unfinished restart. No rows are returned if
+	// the restart is already finished.
+	UpdateTemplateWorkspaceRestartStatus(ctx context.Context, arg UpdateTemplateWorkspaceRestartStatusParams) (TemplateWorkspaceRestart, error)
 	UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg UpdateTemplateWorkspacesLastUsedAtParams) error
 	UpdateUsageEventsPostPublish(ctx context.Context, arg UpdateUsageEventsPostPublishParams) error
 	UpdateUserAIProviderKey(ctx context.Context, arg UpdateUserAIProviderKeyParams) (UserAIProviderKey, error)
@@ -1503,6 +1803,12 @@ type sqlcQuerier interface {
 	UpdateUserGithubComUserID(ctx context.Context, arg UpdateUserGithubComUserIDParams) error
 	UpdateUserHashedOneTimePasscode(ctx context.Context, arg UpdateUserHashedOneTimePasscodeParams) error
 	UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error
+	// Records the consent of the impersonated user. Requests that were already
+	// // consented to or granted are left untouched, so no rows are returned.
+	UpdateUserImpersonationConsentedAt(ctx context.Context, arg UpdateUserImpersonationConsentedAtParams) (UserImpersonation, error)
+	// Marks the request as granted. A token is only ever issued once per
+	// // request, so no rows are returned for requests that were already granted.
+	UpdateUserImpersonationIssued(ctx context.Context, arg UpdateUserImpersonationIssuedParams) (UserImpersonation, error)
 	UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error)
 	UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error)
 	// Backfills linked_id for legacy user_links that were created before
@@ -1525,9 +1831,12 @@ type sqlcQuerier interface {
 	UpdateUserThemeMode(ctx context.Context, arg UpdateUserThemeModeParams) (UserConfig, error)
 	UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error)
 	UpdateUserThinkingDisplayMode(ctx context.Context, arg UpdateUserThinkingDisplayModeParams) (string, error)
+	UpdateUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, arg UpdateUserWorkspaceSelfRequestedBuildsAllowedParams) (bool, error)
+	UpdateVCSEventMappingLastTriggeredAt(ctx context.Context, arg UpdateVCSEventMappingLastTriggeredAtParams) error
 	UpdateVolumeResourceMonitor(ctx context.Context, arg UpdateVolumeResourceMonitorParams) error
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (WorkspaceTable, error)
 	UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error
+	UpdateWorkspaceAgentClockSkewByID(ctx context.Context, arg UpdateWorkspaceAgentClockSkewByIDParams) error
 	UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error
 	UpdateWorkspaceAgentDirectoryByID(ctx context.Context, arg UpdateWorkspaceAgentDirectoryByIDParams) error
 	UpdateWorkspaceAgentDisplayAppsByID(ctx context.Context, arg UpdateWorkspaceAgentDisplayAppsByIDParams) error
@@ -1536,6 +1845,7 @@ type sqlcQuerier interface {
 	UpdateWorkspaceAgentMetadata(ctx context.Context, arg UpdateWorkspaceAgentMetadataParams) error
 	UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error
 	UpdateWorkspaceAppHealthByID(ctx context.Context, arg UpdateWorkspaceAppHealthByIDParams) error
+	UpdateWorkspaceArchivedAt(ctx context.Context, arg UpdateWorkspaceArchivedAtParams) error
 	UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg UpdateWorkspaceAutomaticUpdatesParams) error
 	UpdateWorkspaceAutostart(ctx context.Context, arg UpdateWorkspaceAutostartParams) error
 	UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error
@@ -1552,13 +1862,21 @@ type sqlcQuerier interface {
 	UpdateWorkspaceBuildOrchestrationFailedByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationFailedByIDParams) (WorkspaceBuildOrchestration, error)
 	UpdateWorkspaceBuildOrchestrationRetryByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationRetryByIDParams) (WorkspaceBuildOrchestration, error)
 	UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error
+	UpdateWorkspaceCloudResourceVerification(ctx context.Context, arg UpdateWorkspaceCloudResourceVerificationParams) error
 	UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateWorkspaceDeletedByIDParams) error
+	// // Records the outcome of a running hook. Nothing is updated if the hook was
+	// // replaced by another event in the meantime.
+	UpdateWorkspaceDormancyHookStatus(ctx context.Context, arg UpdateWorkspaceDormancyHookStatusParams) (int64, error)
 	UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg UpdateWorkspaceDormantDeletingAtParams) (WorkspaceTable, error)
 	UpdateWorkspaceLastUsedAt(ctx context.Context, arg UpdateWorkspaceLastUsedAtParams) error
 	UpdateWorkspaceNextStartAt(ctx context.Context, arg UpdateWorkspaceNextStartAtParams) error
+	// Transfers a workspace to another owner. The name is updated as well, since
+	// it must be unique among the workspaces of the new owner.
+	UpdateWorkspaceOwnerByID(ctx context.Context, arg UpdateWorkspaceOwnerByIDParams) (WorkspaceTable, error)
 	// This allows editing the properties of a workspace proxy.
 	UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error)
 	UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error
+	UpdateWorkspaceReadOnlyAt(ctx context.Context, arg UpdateWorkspaceReadOnlyAtParams) (WorkspaceTable, error)
 	UpdateWorkspaceTTL(ctx context.Context, arg UpdateWorkspaceTTLParams) error
 	UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.Context, arg UpdateWorkspacesDormantDeletingAtByTemplateIDParams) ([]WorkspaceTable, error)
 	UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg UpdateWorkspacesTTLByTemplateIDParams) error
@@ -1618,9 +1936,11 @@ type sqlcQuerier interface {
 	UpsertMCPServerUserToken(ctx context.Context, arg UpsertMCPServerUserTokenParams) (MCPServerUserToken, error)
 	// Insert or update notification report generator logs with recent activity.
 	UpsertNotificationReportGeneratorLog(ctx context.Context, arg UpsertNotificationReportGeneratorLogParams) error
+	UpsertNotificationTemplateWebhook(ctx context.Context, arg UpsertNotificationTemplateWebhookParams) (NotificationTemplateWebhook, error)
 	UpsertNotificationsSettings(ctx context.Context, value string) error
 	UpsertOAuth2GithubDefaultEligible(ctx context.Context, eligible bool) error
 	UpsertPrebuildsSettings(ctx context.Context, value string) error
+	UpsertProvisionerCacheSettings(ctx context.Context, value string) error
 	UpsertProvisionerDaemon(ctx context.Context, arg UpsertProvisionerDaemonParams) (ProvisionerDaemon, error)
 	UpsertRuntimeConfig(ctx context.Context, arg UpsertRuntimeConfigParams) error
 	UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error)
@@ -1629,11 +1949,15 @@ type sqlcQuerier interface {
 	UpsertTaskSnapshot(ctx context.Context, arg UpsertTaskSnapshotParams) error
 	UpsertTaskWorkspaceApp(ctx context.Context, arg UpsertTaskWorkspaceAppParams) (TaskWorkspaceApp, error)
 	UpsertTelemetryItem(ctx context.Context, arg UpsertTelemetryItemParams) error
+	UpsertTemplateNetworkPolicy(ctx context.Context, arg UpsertTemplateNetworkPolicyParams) (TemplateNetworkPolicy, error)
 	// This query aggregates the workspace_agent_stats and workspace_app_stats data
 	// into a single table for efficient storage and querying. Half-hour buckets are
 	// used to store the data, and the minutes are summed for each user and template
 	// combination. The result is stored in the template_usage_stats table.
 	UpsertTemplateUsageStats(ctx context.Context) error
+	// Saves a named set of parameter values of a user for a template. Saving a
+	// preset with the name of an existing one replaces its parameters.
+	UpsertTemplateUserPreset(ctx context.Context, arg UpsertTemplateUserPresetParams) (TemplateUserPreset, error)
 	UpsertUserAIBudgetOverride(ctx context.Context, arg UpsertUserAIBudgetOverrideParams) (UserAIBudgetOverride, error)
 	// UpsertUserAIProviderKey preserves the original id and created_at when the
 	// user/provider pair already exists. On conflict, callers provide id and
@@ -1641,9 +1965,12 @@ type sqlcQuerier interface {
 	UpsertUserAIProviderKey(ctx context.Context, arg UpsertUserAIProviderKeyParams) (UserAIProviderKey, error)
 	UpsertUserChatDebugLoggingEnabled(ctx context.Context, arg UpsertUserChatDebugLoggingEnabledParams) error
 	UpsertUserChatPersonalModelOverride(ctx context.Context, arg UpsertUserChatPersonalModelOverrideParams) error
+	UpsertUserNotificationScopedPreferences(ctx context.Context, arg UpsertUserNotificationScopedPreferencesParams) (int64, error)
+	UpsertUserSchedulePause(ctx context.Context, arg UpsertUserSchedulePauseParams) (UserSchedulePause, error)
 	UpsertWebpushVAPIDKeys(ctx context.Context, arg UpsertWebpushVAPIDKeysParams) error
 	UpsertWorkspaceAgentContextResource(ctx context.Context, arg UpsertWorkspaceAgentContextResourceParams) (WorkspaceAgentContextResource, error)
 	UpsertWorkspaceAgentContextSnapshot(ctx context.Context, arg UpsertWorkspaceAgentContextSnapshotParams) (WorkspaceAgentContextSnapshot, error)
+	UpsertWorkspaceAgentCrashLoop(ctx context.Context, arg UpsertWorkspaceAgentCrashLoopParams) (WorkspaceAgentCrashLoop, error)
 	UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
 	UpsertWorkspaceApp(ctx context.Context, arg UpsertWorkspaceAppParams) (WorkspaceApp, error)
 	//
@@ -1651,6 +1978,26 @@ type sqlcQuerier interface {
 	// was started. This means that a new row was inserted (no previous session) or
 	// the updated_at is older than stale interval.
 	UpsertWorkspaceAppAuditSession(ctx context.Context, arg UpsertWorkspaceAppAuditSessionParams) (bool, error)
+	// Records a resource reported by a build. A resource reported again by a
+	// later build exists at that time, so any earlier verification is reset.
+	UpsertWorkspaceCloudResource(ctx context.Context, arg UpsertWorkspaceCloudResourceParams) error
+	UpsertWorkspaceDNSRecord(ctx context.Context, arg UpsertWorkspaceDNSRecordParams) (WorkspaceDNSRecord, error)
+	UpsertWorkspaceDormancyExemption(ctx context.Context, arg UpsertWorkspaceDormancyExemptionParams) (WorkspaceDormancyExemption, error)
+	// // Schedules the hook of a workspace for the given event, replacing any
+	// // earlier invocation. The hook runner picks it up on its next tick.
+	UpsertWorkspaceDormancyHook(ctx context.Context, arg UpsertWorkspaceDormancyHookParams) (WorkspaceDormancyHook, error)
+	// Adds the bytes from a single agent stats report to the workspace's total
+	// for the given day.
+	UpsertWorkspaceEgressDaily(ctx context.Context, arg UpsertWorkspaceEgressDailyParams) error
+	// Records that the owner of a workspace was warned about its max lifetime
+	// expiry. Only the most recent warning is kept.
+	UpsertWorkspaceLifetimeWarning(ctx context.Context, arg UpsertWorkspaceLifetimeWarningParams) error
+	// // Imports the cost of a workspace from a billing export. The imported cost
+	// // replaces the estimate for the month.
+	UpsertWorkspaceMonthlyCost(ctx context.Context, arg UpsertWorkspaceMonthlyCostParams) (WorkspaceMonthlyCost, error)
+	UpsertWorkspaceNetworkPolicy(ctx context.Context, arg UpsertWorkspaceNetworkPolicyParams) (WorkspaceNetworkPolicy, error)
+	UpsertWorkspacePortForwardACL(ctx context.Context, arg UpsertWorkspacePortForwardACLParams) (WorkspacePortForwardACL, error)
+	UpsertWorkspaceRegionAssignment(ctx context.Context, arg UpsertWorkspaceRegionAssignmentParams) (WorkspaceRegionAssignment, error)
 	UsageEventExistsByID(ctx context.Context, id string) (bool, error)
 	ValidateGroupIDs(ctx context.Context, groupIds []uuid.UUID) (ValidateGroupIDsRow, error)
 	ValidateUserIDs(ctx context.Context, userIds []uuid.UUID) (ValidateUserIDsRow, error)
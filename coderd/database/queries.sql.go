@@ -54,7 +54,18 @@ WITH latest AS (
 				ELSE
 					(templates.activity_bump / 1000 / 1000 / 1000 || ' seconds')::interval
 			END
-		) AS ttl_interval
+		) AS ttl_interval,
+		templates.activity_bump_max_per_day AS max_bump_per_day,
+		(
+			cardinality(templates.activity_bump_connection_types) = 0
+			OR templates.activity_bump_connection_types && $2 :: text[]
+		) AS connection_type_allowed,
+		COALESCE((
+			SELECT workspace_activity_bumps.bumped
+			FROM workspace_activity_bumps
+			WHERE workspace_activity_bumps.workspace_id = workspaces.id
+				AND workspace_activity_bumps.date = (NOW() AT TIME ZONE 'UTC')::date
+		), 0) AS bumped_today
 	FROM workspace_builds
 	JOIN provisioner_jobs
 		ON provisioner_jobs.id = workspace_builds.job_id
@@ -63,36 +74,71 @@ WITH latest AS (
 	JOIN templates
 		ON templates.id = workspaces.template_id
 	WHERE
-		workspace_builds.workspace_id = $2::uuid
+		workspace_builds.workspace_id = $3::uuid
 		-- Prebuilt workspaces (identified by having the prebuilds system user as owner_id)
 		-- are managed by the reconciliation loop and not subject to activity bumping
 	  	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
 	ORDER BY workspace_builds.build_number DESC
 	LIMIT 1
+),
+bumped AS (
+	UPDATE
+		workspace_builds wb
+	SET
+		updated_at = NOW(),
+		deadline = LEAST(
+			CASE
+				WHEN l.build_max_deadline = '0001-01-01 00:00:00+00'
+				-- Never reduce the deadline from activity.
+				THEN GREATEST(wb.deadline, NOW() + l.ttl_interval)
+				ELSE LEAST(GREATEST(wb.deadline, NOW() + l.ttl_interval), l.build_max_deadline)
+			END,
+			-- Never bump past what remains of the daily limit.
+			CASE
+				WHEN l.max_bump_per_day > 0
+				THEN GREATEST(wb.deadline, NOW()) + ((l.max_bump_per_day - l.bumped_today) / 1000 || ' microseconds')::interval
+				ELSE 'infinity'::timestamptz
+			END
+		)
+	FROM latest l
+	WHERE wb.id = l.build_id
+	AND l.job_completed_at IS NOT NULL
+	-- We only bump if the template has an activity bump duration set.
+	AND l.activity_bump > 0
+	AND l.build_transition = 'start'
+	-- We only bump if the raw interval is positive and non-zero.
+	AND l.ttl_interval > '0 seconds'::interval
+	-- We only bump if workspace shutdown is manual.
+	AND l.build_deadline != '0001-01-01 00:00:00+00'
+	-- We only bump when 5% of the deadline has elapsed.
+	AND l.build_deadline - (l.ttl_interval * 0.95) < NOW()
+	-- We only bump for the connection types allowed by the template.
+	AND l.connection_type_allowed
+	-- We only bump if the daily limit has not been reached.
+	AND (l.max_bump_per_day = 0 OR l.bumped_today < l.max_bump_per_day)
+	RETURNING wb.workspace_id, l.max_bump_per_day, wb.deadline - GREATEST(l.build_deadline, NOW()) AS bump
 )
-UPDATE
-	workspace_builds wb
-SET
-	updated_at = NOW(),
-	deadline = CASE
-		WHEN l.build_max_deadline = '0001-01-01 00:00:00+00'
-		-- Never reduce the deadline from activity.
-		THEN GREATEST(wb.deadline, NOW() + l.ttl_interval)
-		ELSE LEAST(GREATEST(wb.deadline, NOW() + l.ttl_interval), l.build_max_deadline)
-	END
-FROM latest l
-WHERE wb.id = l.build_id
-AND l.job_completed_at IS NOT NULL
-AND l.activity_bump > 0
-AND l.build_transition = 'start'
-AND l.ttl_interval > '0 seconds'::interval
-AND l.build_deadline != '0001-01-01 00:00:00+00'
-AND l.build_deadline - (l.ttl_interval * 0.95) < NOW()
+INSERT INTO workspace_activity_bumps (workspace_id, date, bumped)
+SELECT
+	bumped.workspace_id,
+	(NOW() AT TIME ZONE 'UTC')::date,
+	(EXTRACT(EPOCH FROM bumped.bump) * 1000000000)::bigint
+FROM bumped
+WHERE bumped.max_bump_per_day > 0
+AND bumped.bump > '0 seconds'::interval
+ON CONFLICT (workspace_id) DO UPDATE SET
+	bumped = CASE
+		WHEN workspace_activity_bumps.date = EXCLUDED.date
+		THEN workspace_activity_bumps.bumped + EXCLUDED.bumped
+		ELSE EXCLUDED.bumped
+	END,
+	date = EXCLUDED.date
 `
 
 type ActivityBumpWorkspaceParams struct {
-	NextAutostart time.Time `db:"next_autostart" json:"next_autostart"`
-	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	NextAutostart   time.Time `db:"next_autostart" json:"next_autostart"`
+	ConnectionTypes []string  `db:"connection_types" json:"connection_types"`
+	WorkspaceID     uuid.UUID `db:"workspace_id" json:"workspace_id"`
 }
 
 // Bumps the workspace deadline by the template's configured "activity_bump"
@@ -107,7 +153,7 @@ type ActivityBumpWorkspaceParams struct {
 // We only bump if workspace shutdown is manual.
 // We only bump when 5% of the deadline has elapsed.
 func (q *sqlQuerier) ActivityBumpWorkspace(ctx context.Context, arg ActivityBumpWorkspaceParams) error {
-	_, err := q.db.ExecContext(ctx, activityBumpWorkspace, arg.NextAutostart, arg.WorkspaceID)
+	_, err := q.db.ExecContext(ctx, activityBumpWorkspace, arg.NextAutostart, pq.Array(arg.ConnectionTypes), arg.WorkspaceID)
 	return err
 }
 
@@ -3232,7 +3278,7 @@ func (q *sqlQuerier) ExpirePrebuildsAPIKeys(ctx context.Context, now time.Time)
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list, template_ids
 FROM
 	api_keys
 WHERE
@@ -3258,13 +3304,14 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.TokenName,
 		&i.Scopes,
 		&i.AllowList,
+		pq.Array(&i.TemplateIDs),
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list, template_ids
 FROM
 	api_keys
 WHERE
@@ -3298,12 +3345,13 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.TokenName,
 		&i.Scopes,
 		&i.AllowList,
+		pq.Array(&i.TemplateIDs),
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list, template_ids FROM api_keys WHERE login_type = $1
 AND ($2::bool OR expires_at > now())
 `
 
@@ -3335,6 +3383,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, arg GetAPIKeysBy
 			&i.TokenName,
 			&i.Scopes,
 			&i.AllowList,
+			pq.Array(&i.TemplateIDs),
 		); err != nil {
 			return nil, err
 		}
@@ -3350,7 +3399,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, arg GetAPIKeysBy
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list, template_ids FROM api_keys WHERE login_type = $1 AND user_id = $2
 AND ($3::bool OR expires_at > now())
 `
 
@@ -3383,6 +3432,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.TokenName,
 			&i.Scopes,
 			&i.AllowList,
+			pq.Array(&i.TemplateIDs),
 		); err != nil {
 			return nil, err
 		}
@@ -3398,7 +3448,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list, template_ids FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -3424,6 +3474,7 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.TokenName,
 			&i.Scopes,
 			&i.AllowList,
+			pq.Array(&i.TemplateIDs),
 		); err != nil {
 			return nil, err
 		}
@@ -3440,7 +3491,7 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 
 const getChatGatewayAPIKey = `-- name: GetChatGatewayAPIKey :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list, template_ids
 FROM
 	api_keys
 WHERE
@@ -3479,6 +3530,7 @@ func (q *sqlQuerier) GetChatGatewayAPIKey(ctx context.Context, arg GetChatGatewa
 		&i.TokenName,
 		&i.Scopes,
 		&i.AllowList,
+		pq.Array(&i.TemplateIDs),
 	)
 	return i, err
 }
@@ -3498,7 +3550,8 @@ INSERT INTO
 		login_type,
 		scopes,
 		allow_list,
-		token_name
+		token_name,
+		template_ids
 	)
 VALUES
 	($1,
@@ -3507,7 +3560,9 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+	 -- A nil template list means the key is not template restricted.
+	 COALESCE($14::uuid[], '{}'::uuid[])) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, token_name, scopes, allow_list, template_ids
 `
 
 type InsertAPIKeyParams struct {
@@ -3524,6 +3579,7 @@ type InsertAPIKeyParams struct {
 	Scopes          APIKeyScopes `db:"scopes" json:"scopes"`
 	AllowList       AllowList    `db:"allow_list" json:"allow_list"`
 	TokenName       string       `db:"token_name" json:"token_name"`
+	TemplateIDs     []uuid.UUID  `db:"template_ids" json:"template_ids"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -3541,6 +3597,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.Scopes,
 		arg.AllowList,
 		arg.TokenName,
+		pq.Array(arg.TemplateIDs),
 	)
 	var i APIKey
 	err := row.Scan(
@@ -3557,6 +3614,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.TokenName,
 		&i.Scopes,
 		&i.AllowList,
+		pq.Array(&i.TemplateIDs),
 	)
 	return i, err
 }
@@ -3676,6 +3734,11 @@ SELECT COUNT(*) FROM (
 			WHEN $12::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.request_id = $12
 			ELSE true
 		END
+		-- Filter correlation_id
+		AND CASE
+			WHEN $13::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.correlation_id = $13
+			ELSE true
+		END
 		-- Authorize Filter clause will be injected below in CountAuthorizedAuditLogs
 		-- @authorize_filter
 	-- Avoid a slow scan on a large table with joins. The caller
@@ -3687,7 +3750,7 @@ SELECT COUNT(*) FROM (
 	-- here if disabling the capping on a large table permanently.
 	-- This way the PG planner can plan parallel execution for
 	-- potential large wins.
-	LIMIT NULLIF($13::int, 0) + 1
+	LIMIT NULLIF($14::int, 0) + 1
 ) AS limited_count
 `
 
@@ -3704,6 +3767,7 @@ type CountAuditLogsParams struct {
 	DateTo         time.Time `db:"date_to" json:"date_to"`
 	BuildReason    string    `db:"build_reason" json:"build_reason"`
 	RequestID      uuid.UUID `db:"request_id" json:"request_id"`
+	CorrelationID  uuid.UUID `db:"correlation_id" json:"correlation_id"`
 	CountCap       int32     `db:"count_cap" json:"count_cap"`
 }
 
@@ -3721,6 +3785,7 @@ func (q *sqlQuerier) CountAuditLogs(ctx context.Context, arg CountAuditLogsParam
 		arg.DateTo,
 		arg.BuildReason,
 		arg.RequestID,
+		arg.CorrelationID,
 		arg.CountCap,
 	)
 	var count int64
@@ -3787,7 +3852,7 @@ func (q *sqlQuerier) DeleteOldAuditLogs(ctx context.Context, arg DeleteOldAuditL
 }
 
 const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
-SELECT audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
+SELECT audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon, audit_logs.correlation_id,
 	-- sqlc.embed(users) would be nice but it does not seem to play well with
 	-- left joins.
 	users.username AS user_username,
@@ -3889,13 +3954,18 @@ WHERE
 		WHEN $12::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.request_id = $12
 		ELSE true
 	END
+	-- Filter correlation_id
+	AND CASE
+		WHEN $13::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.correlation_id = $13
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedAuditLogsOffset
 	-- @authorize_filter
 ORDER BY "time" DESC
 LIMIT -- a limit of 0 means "no limit". The audit log table is unbounded
 	-- in size, and is expected to be quite large. Implement a default
 	-- limit of 100 to prevent accidental excessively large queries.
-	COALESCE(NULLIF($14::int, 0), 100) OFFSET $13
+	COALESCE(NULLIF($15::int, 0), 100) OFFSET $14
 `
 
 type GetAuditLogsOffsetParams struct {
@@ -3911,6 +3981,7 @@ type GetAuditLogsOffsetParams struct {
 	DateTo         time.Time `db:"date_to" json:"date_to"`
 	BuildReason    string    `db:"build_reason" json:"build_reason"`
 	RequestID      uuid.UUID `db:"request_id" json:"request_id"`
+	CorrelationID  uuid.UUID `db:"correlation_id" json:"correlation_id"`
 	OffsetOpt      int32     `db:"offset_opt" json:"offset_opt"`
 	LimitOpt       int32     `db:"limit_opt" json:"limit_opt"`
 }
@@ -3950,6 +4021,7 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 		arg.DateTo,
 		arg.BuildReason,
 		arg.RequestID,
+		arg.CorrelationID,
 		arg.OffsetOpt,
 		arg.LimitOpt,
 	)
@@ -3976,6 +4048,7 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 			&i.AuditLog.AdditionalFields,
 			&i.AuditLog.RequestID,
 			&i.AuditLog.ResourceIcon,
+			&i.AuditLog.CorrelationID,
 			&i.UserUsername,
 			&i.UserName,
 			&i.UserEmail,
@@ -4021,7 +4094,8 @@ INSERT INTO audit_logs (
 		status_code,
 		additional_fields,
 		request_id,
-		resource_icon
+		resource_icon,
+		correlation_id
 	)
 VALUES (
 		$1,
@@ -4038,9 +4112,10 @@ VALUES (
 		$12,
 		$13,
 		$14,
-		$15
+		$15,
+		$16
 	)
-RETURNING id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code, additional_fields, request_id, resource_icon
+RETURNING id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code, additional_fields, request_id, resource_icon, correlation_id
 `
 
 type InsertAuditLogParams struct {
@@ -4059,6 +4134,7 @@ type InsertAuditLogParams struct {
 	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
 	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
+	CorrelationID    uuid.NullUUID   `db:"correlation_id" json:"correlation_id"`
 }
 
 func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error) {
@@ -4078,6 +4154,7 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 		arg.AdditionalFields,
 		arg.RequestID,
 		arg.ResourceIcon,
+		arg.CorrelationID,
 	)
 	var i AuditLog
 	err := row.Scan(
@@ -4096,6 +4173,7 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 		&i.AdditionalFields,
 		&i.RequestID,
 		&i.ResourceIcon,
+		&i.CorrelationID,
 	)
 	return i, err
 }
@@ -15540,6 +15618,353 @@ func (q *sqlQuerier) ValidateGroupIDs(ctx context.Context, groupIds []uuid.UUID)
 	return i, err
 }
 
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :exec
+DELETE FROM
+	idempotency_keys
+WHERE
+	expires_at <= $1 :: timestamptz
+`
+
+func (q *sqlQuerier) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredIdempotencyKeys, before)
+	return err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT
+	user_id, operation, key, request_hash, workspace_id, workspace_build_id, created_at, expires_at
+FROM
+	idempotency_keys
+WHERE
+	user_id = $1
+	AND operation = $2
+	AND key = $3
+	AND expires_at > $4 :: timestamptz
+`
+
+type GetIdempotencyKeyParams struct {
+	UserID    uuid.UUID               `db:"user_id" json:"user_id"`
+	Operation IdempotencyKeyOperation `db:"operation" json:"operation"`
+	Key       string                  `db:"key" json:"key"`
+	Now       time.Time               `db:"now" json:"now"`
+}
+
+func (q *sqlQuerier) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey,
+		arg.UserID,
+		arg.Operation,
+		arg.Key,
+		arg.Now,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.UserID,
+		&i.Operation,
+		&i.Key,
+		&i.RequestHash,
+		&i.WorkspaceID,
+		&i.WorkspaceBuildID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const insertIdempotencyKey = `-- name: InsertIdempotencyKey :one
+INSERT INTO
+	idempotency_keys (
+		user_id,
+		operation,
+		key,
+		request_hash,
+		workspace_id,
+		workspace_build_id,
+		created_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (user_id, operation, key) DO UPDATE SET
+	request_hash = EXCLUDED.request_hash,
+	workspace_id = EXCLUDED.workspace_id,
+	workspace_build_id = EXCLUDED.workspace_build_id,
+	created_at = EXCLUDED.created_at,
+	expires_at = EXCLUDED.expires_at
+WHERE
+	idempotency_keys.expires_at <= EXCLUDED.created_at
+RETURNING user_id, operation, key, request_hash, workspace_id, workspace_build_id, created_at, expires_at
+`
+
+type InsertIdempotencyKeyParams struct {
+	UserID           uuid.UUID               `db:"user_id" json:"user_id"`
+	Operation        IdempotencyKeyOperation `db:"operation" json:"operation"`
+	Key              string                  `db:"key" json:"key"`
+	RequestHash      []byte                  `db:"request_hash" json:"request_hash"`
+	WorkspaceID      uuid.UUID               `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID               `db:"workspace_build_id" json:"workspace_build_id"`
+	CreatedAt        time.Time               `db:"created_at" json:"created_at"`
+	ExpiresAt        time.Time               `db:"expires_at" json:"expires_at"`
+}
+
+// Inserts the key, replacing an expired key of the same user and operation.
+// Returns no rows if an unexpired key already exists.
+func (q *sqlQuerier) InsertIdempotencyKey(ctx context.Context, arg InsertIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, insertIdempotencyKey,
+		arg.UserID,
+		arg.Operation,
+		arg.Key,
+		arg.RequestHash,
+		arg.WorkspaceID,
+		arg.WorkspaceBuildID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.UserID,
+		&i.Operation,
+		&i.Key,
+		&i.RequestHash,
+		&i.WorkspaceID,
+		&i.WorkspaceBuildID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getAutostopInsights = `-- name: GetAutostopInsights :many
+WITH builds AS (
+	SELECT
+		w.template_id,
+		wb.transition,
+		wb.reason,
+		pj.completed_at AS started_at,
+		LEAD(pj.completed_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.build_number) AS ended_at
+	FROM
+		workspace_builds wb
+	JOIN
+		provisioner_jobs pj ON pj.id = wb.job_id
+	JOIN
+		workspaces w ON w.id = wb.workspace_id
+	WHERE
+		pj.job_status = 'succeeded'::provisioner_job_status
+		AND pj.completed_at < $1::timestamptz
+		AND CASE WHEN COALESCE(array_length($2::uuid[], 1), 0) > 0 THEN w.template_id = ANY($2::uuid[]) ELSE TRUE END
+),
+periods AS (
+	SELECT
+		template_id,
+		transition,
+		reason,
+		EXTRACT(EPOCH FROM
+			LEAST(COALESCE(ended_at, $1::timestamptz), $1::timestamptz)
+			- GREATEST(started_at, $3::timestamptz)
+		) AS seconds
+	FROM
+		builds
+	WHERE
+		COALESCE(ended_at, $1::timestamptz) > $3::timestamptz
+)
+SELECT
+	t.organization_id,
+	o.name AS organization_name,
+	t.id AS template_id,
+	t.name AS template_name,
+	t.display_name AS template_display_name,
+	COALESCE(SUM(p.seconds) FILTER (WHERE p.transition = 'start'), 0)::bigint AS running_seconds,
+	COALESCE(SUM(p.seconds) FILTER (WHERE p.transition = 'stop' AND p.reason = 'autostop'), 0)::bigint AS autostop_seconds,
+	COALESCE(SUM(p.seconds) FILTER (WHERE p.transition = 'stop' AND p.reason = 'dormancy'), 0)::bigint AS dormancy_seconds,
+	COALESCE(SUM(p.seconds) FILTER (WHERE p.transition = 'stop' AND p.reason = 'failedstop'), 0)::bigint AS failure_ttl_seconds
+FROM
+	periods p
+JOIN
+	templates t ON t.id = p.template_id
+JOIN
+	organizations o ON o.id = t.organization_id
+WHERE
+	p.seconds > 0
+GROUP BY
+	t.organization_id, o.name, t.id, t.name, t.display_name
+ORDER BY
+	o.name, t.name
+`
+
+type GetAutostopInsightsParams struct {
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+}
+
+type GetAutostopInsightsRow struct {
+	OrganizationID      uuid.UUID `db:"organization_id" json:"organization_id"`
+	OrganizationName    string    `db:"organization_name" json:"organization_name"`
+	TemplateID          uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName        string    `db:"template_name" json:"template_name"`
+	TemplateDisplayName string    `db:"template_display_name" json:"template_display_name"`
+	RunningSeconds      int64     `db:"running_seconds" json:"running_seconds"`
+	AutostopSeconds     int64     `db:"autostop_seconds" json:"autostop_seconds"`
+	DormancySeconds     int64     `db:"dormancy_seconds" json:"dormancy_seconds"`
+	FailureTtlSeconds   int64     `db:"failure_ttl_seconds" json:"failure_ttl_seconds"`
+}
+
+// GetAutostopInsights returns, per template, how long workspaces ran and how
+// long they were stopped by scheduling policies within [start_time, end_time).
+// A workspace is running from a successful start build until its next
+// successful build, and stopped by a policy from a successful stop build with
+// the autostop, dormancy or failedstop reason until its next successful build.
+func (q *sqlQuerier) GetAutostopInsights(ctx context.Context, arg GetAutostopInsightsParams) ([]GetAutostopInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAutostopInsights, arg.EndTime, pq.Array(arg.TemplateIDs), arg.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAutostopInsightsRow
+	for rows.Next() {
+		var i GetAutostopInsightsRow
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.OrganizationName,
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.TemplateDisplayName,
+			&i.RunningSeconds,
+			&i.AutostopSeconds,
+			&i.DormancySeconds,
+			&i.FailureTtlSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateActiveDeveloperDays = `-- name: GetTemplateActiveDeveloperDays :many
+WITH
+	ts AS (
+		SELECT
+			d::timestamptz AS from_,
+			LEAST(
+				(d::timestamptz + '1 day'::interval)::timestamptz,
+				$1::timestamptz
+			)::timestamptz AS to_
+		FROM
+			generate_series(
+				$2::timestamptz,
+				-- Subtract 1 μs to avoid creating an extra series.
+				($1::timestamptz) - '1 microsecond'::interval,
+				'1 day'::interval
+			) AS d
+	),
+	active AS (
+		SELECT
+			ts.from_ AS date,
+			tus.template_id,
+			tus.user_id
+		FROM
+			ts
+		JOIN
+			template_usage_stats AS tus
+		ON
+			tus.start_time >= ts.from_
+			AND tus.start_time < ts.to_ -- End time exclusion criteria optimization for index.
+			AND tus.end_time <= ts.to_
+		WHERE
+			CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN tus.template_id = ANY($3::uuid[]) ELSE TRUE END
+		GROUP BY
+			ts.from_, tus.template_id, tus.user_id
+		HAVING
+			SUM(tus.usage_mins) >= $4::int
+	)
+
+SELECT
+	active.date::timestamptz AS date,
+	active.template_id,
+	t.name AS template_name,
+	t.organization_id,
+	o.name AS organization_name,
+	active.user_id
+FROM
+	active
+JOIN
+	templates AS t
+ON
+	t.id = active.template_id
+JOIN
+	organizations AS o
+ON
+	o.id = t.organization_id
+WHERE
+	CASE WHEN $5::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN t.organization_id = $5 ELSE TRUE END
+ORDER BY
+	active.date, o.name, t.name, active.user_id
+`
+
+type GetTemplateActiveDeveloperDaysParams struct {
+	EndTime        time.Time   `db:"end_time" json:"end_time"`
+	StartTime      time.Time   `db:"start_time" json:"start_time"`
+	TemplateIDs    []uuid.UUID `db:"template_ids" json:"template_ids"`
+	MinUsageMins   int32       `db:"min_usage_mins" json:"min_usage_mins"`
+	OrganizationID uuid.UUID   `db:"organization_id" json:"organization_id"`
+}
+
+type GetTemplateActiveDeveloperDaysRow struct {
+	Date             time.Time `db:"date" json:"date"`
+	TemplateID       uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName     string    `db:"template_name" json:"template_name"`
+	OrganizationID   uuid.UUID `db:"organization_id" json:"organization_id"`
+	OrganizationName string    `db:"organization_name" json:"organization_name"`
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// GetTemplateActiveDeveloperDays returns the users that were active in
+// workspaces of each template, for each day between start and end time. A user
+// counts as active on a day when their session and app usage of the template
+// adds up to at least min_usage_mins minutes. Days start at the time of day of
+// start_time, so its time zone decides the day boundaries.
+func (q *sqlQuerier) GetTemplateActiveDeveloperDays(ctx context.Context, arg GetTemplateActiveDeveloperDaysParams) ([]GetTemplateActiveDeveloperDaysRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateActiveDeveloperDays,
+		arg.EndTime,
+		arg.StartTime,
+		pq.Array(arg.TemplateIDs),
+		arg.MinUsageMins,
+		arg.OrganizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateActiveDeveloperDaysRow
+	for rows.Next() {
+		var i GetTemplateActiveDeveloperDaysRow
+		if err := rows.Scan(
+			&i.Date,
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.OrganizationID,
+			&i.OrganizationName,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTemplateAppInsights = `-- name: GetTemplateAppInsights :many
 WITH
 	-- Create a list of all unique apps by template, this is used to
@@ -16185,6 +16610,7 @@ WITH latest_workspace_builds AS (
 		tvp.options
 	FROM latest_workspace_builds wb
 	JOIN template_version_parameters tvp ON (tvp.template_version_id = wb.template_version_id)
+	WHERE NOT tvp.sensitive
 	GROUP BY tvp.name, tvp.type, tvp.display_name, tvp.description, tvp.options
 )
 
@@ -18037,6 +18463,305 @@ func (q *sqlQuerier) UpsertMCPServerUserToken(ctx context.Context, arg UpsertMCP
 	return i, err
 }
 
+const deleteOldWorkspaceAgentNetworkPolicyViolations = `-- name: DeleteOldWorkspaceAgentNetworkPolicyViolations :exec
+DELETE FROM
+	workspace_agent_network_policy_violations
+WHERE
+	created_at < $1 :: timestamptz
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAgentNetworkPolicyViolations(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentNetworkPolicyViolations, beforeTime)
+	return err
+}
+
+const deleteTemplateNetworkPolicy = `-- name: DeleteTemplateNetworkPolicy :exec
+DELETE FROM
+	template_network_policies
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) DeleteTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateNetworkPolicy, templateID)
+	return err
+}
+
+const deleteWorkspaceNetworkPolicy = `-- name: DeleteWorkspaceNetworkPolicy :exec
+DELETE FROM
+	workspace_network_policies
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceNetworkPolicy, workspaceID)
+	return err
+}
+
+const getTemplateNetworkPolicy = `-- name: GetTemplateNetworkPolicy :one
+SELECT
+	template_id, allowed_domains, allowed_cidrs, updated_at
+FROM
+	template_network_policies
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) (TemplateNetworkPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateNetworkPolicy, templateID)
+	var i TemplateNetworkPolicy
+	err := row.Scan(
+		&i.TemplateID,
+		pq.Array(&i.AllowedDomains),
+		pq.Array(&i.AllowedCIDRs),
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs = `-- name: GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs :many
+SELECT
+	workspace_agent_id,
+	SUM(count) :: bigint AS violations
+FROM
+	workspace_agent_network_policy_violations
+WHERE
+	workspace_agent_id = ANY($1 :: uuid [ ])
+	AND created_at >= $2 :: timestamptz
+GROUP BY
+	workspace_agent_id
+`
+
+type GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsParams struct {
+	IDs          []uuid.UUID `db:"ids" json:"ids"`
+	CreatedAfter time.Time   `db:"created_after" json:"created_after"`
+}
+
+type GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Violations       int64     `db:"violations" json:"violations"`
+}
+
+// Returns the number of blocked connections reported by each of the given
+// agents since the given time. Agents without violations are omitted.
+func (q *sqlQuerier) GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(ctx context.Context, arg GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsParams) ([]GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs, pq.Array(arg.IDs), arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow
+	for rows.Next() {
+		var i GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow
+		if err := rows.Scan(&i.WorkspaceAgentID, &i.Violations); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentNetworkPolicyViolationsByAgentIDs = `-- name: GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs :many
+SELECT
+	id, workspace_agent_id, created_at, destination, port, count
+FROM
+	workspace_agent_network_policy_violations
+WHERE
+	workspace_agent_id = ANY($1 :: uuid [ ])
+	AND created_at >= $2 :: timestamptz
+ORDER BY
+	created_at DESC, id DESC
+LIMIT
+	1000
+`
+
+type GetWorkspaceAgentNetworkPolicyViolationsByAgentIDsParams struct {
+	IDs          []uuid.UUID `db:"ids" json:"ids"`
+	CreatedAfter time.Time   `db:"created_after" json:"created_after"`
+}
+
+// Returns the violations reported by the given agents since the given time,
+// newest first.
+func (q *sqlQuerier) GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(ctx context.Context, arg GetWorkspaceAgentNetworkPolicyViolationsByAgentIDsParams) ([]WorkspaceAgentNetworkPolicyViolation, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentNetworkPolicyViolationsByAgentIDs, pq.Array(arg.IDs), arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentNetworkPolicyViolation
+	for rows.Next() {
+		var i WorkspaceAgentNetworkPolicyViolation
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.CreatedAt,
+			&i.Destination,
+			&i.Port,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceNetworkPolicy = `-- name: GetWorkspaceNetworkPolicy :one
+SELECT
+	workspace_id, allowed_domains, allowed_cidrs, updated_at
+FROM
+	workspace_network_policies
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) (WorkspaceNetworkPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceNetworkPolicy, workspaceID)
+	var i WorkspaceNetworkPolicy
+	err := row.Scan(
+		&i.WorkspaceID,
+		pq.Array(&i.AllowedDomains),
+		pq.Array(&i.AllowedCIDRs),
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertWorkspaceAgentNetworkPolicyViolations = `-- name: InsertWorkspaceAgentNetworkPolicyViolations :exec
+INSERT INTO
+	workspace_agent_network_policy_violations (
+		id,
+		workspace_agent_id,
+		created_at,
+		destination,
+		port,
+		count
+	)
+SELECT
+	unnest($1 :: uuid [ ]),
+	$2 :: uuid,
+	$3 :: timestamptz,
+	unnest($4 :: text [ ]),
+	unnest($5 :: integer [ ]),
+	unnest($6 :: integer [ ])
+`
+
+type InsertWorkspaceAgentNetworkPolicyViolationsParams struct {
+	ID               []uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	Destination      []string    `db:"destination" json:"destination"`
+	Port             []int32     `db:"port" json:"port"`
+	Count            []int32     `db:"count" json:"count"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentNetworkPolicyViolations(ctx context.Context, arg InsertWorkspaceAgentNetworkPolicyViolationsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentNetworkPolicyViolations,
+		pq.Array(arg.ID),
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		pq.Array(arg.Destination),
+		pq.Array(arg.Port),
+		pq.Array(arg.Count),
+	)
+	return err
+}
+
+const upsertTemplateNetworkPolicy = `-- name: UpsertTemplateNetworkPolicy :one
+INSERT INTO
+	template_network_policies (
+		template_id,
+		allowed_domains,
+		allowed_cidrs,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (template_id) DO UPDATE SET
+	allowed_domains = EXCLUDED.allowed_domains,
+	allowed_cidrs = EXCLUDED.allowed_cidrs,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_id, allowed_domains, allowed_cidrs, updated_at
+`
+
+type UpsertTemplateNetworkPolicyParams struct {
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	AllowedDomains []string  `db:"allowed_domains" json:"allowed_domains"`
+	AllowedCIDRs   []string  `db:"allowed_cidrs" json:"allowed_cidrs"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertTemplateNetworkPolicy(ctx context.Context, arg UpsertTemplateNetworkPolicyParams) (TemplateNetworkPolicy, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateNetworkPolicy,
+		arg.TemplateID,
+		pq.Array(arg.AllowedDomains),
+		pq.Array(arg.AllowedCIDRs),
+		arg.UpdatedAt,
+	)
+	var i TemplateNetworkPolicy
+	err := row.Scan(
+		&i.TemplateID,
+		pq.Array(&i.AllowedDomains),
+		pq.Array(&i.AllowedCIDRs),
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertWorkspaceNetworkPolicy = `-- name: UpsertWorkspaceNetworkPolicy :one
+INSERT INTO
+	workspace_network_policies (
+		workspace_id,
+		allowed_domains,
+		allowed_cidrs,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	allowed_domains = EXCLUDED.allowed_domains,
+	allowed_cidrs = EXCLUDED.allowed_cidrs,
+	updated_at = EXCLUDED.updated_at
+RETURNING workspace_id, allowed_domains, allowed_cidrs, updated_at
+`
+
+type UpsertWorkspaceNetworkPolicyParams struct {
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AllowedDomains []string  `db:"allowed_domains" json:"allowed_domains"`
+	AllowedCIDRs   []string  `db:"allowed_cidrs" json:"allowed_cidrs"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceNetworkPolicy(ctx context.Context, arg UpsertWorkspaceNetworkPolicyParams) (WorkspaceNetworkPolicy, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceNetworkPolicy,
+		arg.WorkspaceID,
+		pq.Array(arg.AllowedDomains),
+		pq.Array(arg.AllowedCIDRs),
+		arg.UpdatedAt,
+	)
+	var i WorkspaceNetworkPolicy
+	err := row.Scan(
+		&i.WorkspaceID,
+		pq.Array(&i.AllowedDomains),
+		pq.Array(&i.AllowedCIDRs),
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const acquireNotificationMessages = `-- name: AcquireNotificationMessages :many
 WITH acquired AS (
     UPDATE
@@ -18090,12 +18815,19 @@ SELECT
     nt.id                                                                 AS template_id,
     nt.title_template,
     nt.body_template,
-    -- preferences
-    (CASE WHEN np.disabled IS NULL THEN false ELSE np.disabled END)::bool AS disabled
+    -- preferences, a workspace or template scoped preference takes precedence
+    COALESCE(nsp.disabled, np.disabled, false)::bool                      AS disabled
 FROM acquired nm
          JOIN notification_templates nt ON nm.notification_template_id = nt.id
          LEFT JOIN notification_preferences AS np
                    ON (np.user_id = nm.user_id AND np.notification_template_id = nm.notification_template_id)
+         LEFT JOIN LATERAL (SELECT nsp.disabled
+                            FROM notification_scoped_preferences AS nsp
+                            WHERE nsp.user_id = nm.user_id
+                              AND nsp.notification_template_id = nm.notification_template_id
+                              AND nsp.target_id = ANY (nm.targets)
+                            ORDER BY (nsp.scope = 'workspace') DESC
+                            LIMIT 1) AS nsp ON TRUE
 `
 
 type AcquireNotificationMessagesParams struct {
@@ -18250,6 +18982,16 @@ func (q *sqlQuerier) DeleteAllWebpushSubscriptions(ctx context.Context) error {
 	return err
 }
 
+const deleteNotificationTemplateWebhook = `-- name: DeleteNotificationTemplateWebhook :exec
+DELETE FROM notification_template_webhooks
+WHERE notification_template_id = $1::uuid
+`
+
+func (q *sqlQuerier) DeleteNotificationTemplateWebhook(ctx context.Context, notificationTemplateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteNotificationTemplateWebhook, notificationTemplateID)
+	return err
+}
+
 const deleteOldNotificationMessages = `-- name: DeleteOldNotificationMessages :exec
 DELETE
 FROM notification_messages
@@ -18280,6 +19022,29 @@ func (q *sqlQuerier) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Co
 	return err
 }
 
+const deleteUserNotificationScopedPreferences = `-- name: DeleteUserNotificationScopedPreferences :execrows
+DELETE FROM notification_scoped_preferences
+WHERE user_id = $1::uuid
+  AND (notification_template_id, target_id) IN (
+    SELECT UNNEST($2::uuid[]),
+           UNNEST($3::uuid[])
+  )
+`
+
+type DeleteUserNotificationScopedPreferencesParams struct {
+	UserID                  uuid.UUID   `db:"user_id" json:"user_id"`
+	NotificationTemplateIds []uuid.UUID `db:"notification_template_ids" json:"notification_template_ids"`
+	TargetIds               []uuid.UUID `db:"target_ids" json:"target_ids"`
+}
+
+func (q *sqlQuerier) DeleteUserNotificationScopedPreferences(ctx context.Context, arg DeleteUserNotificationScopedPreferencesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserNotificationScopedPreferences, arg.UserID, pq.Array(arg.NotificationTemplateIds), pq.Array(arg.TargetIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteWebpushSubscriptions = `-- name: DeleteWebpushSubscriptions :exec
 DELETE FROM webpush_subscriptions
 WHERE id = ANY($1::uuid[])
@@ -18427,6 +19192,63 @@ func (q *sqlQuerier) GetNotificationMessagesByStatus(ctx context.Context, arg Ge
 	return items, nil
 }
 
+const getNotificationMessagesByTemplateID = `-- name: GetNotificationMessagesByTemplateID :many
+SELECT id, notification_template_id, user_id, method, status, status_reason, created_by, payload, attempt_count, targets, created_at, updated_at, leased_until, next_retry_after, queued_seconds, dedupe_hash
+FROM notification_messages
+WHERE notification_template_id = $1
+	AND ($2::notification_method IS NULL OR method = $2::notification_method)
+ORDER BY created_at DESC, id DESC
+LIMIT $3::int
+`
+
+type GetNotificationMessagesByTemplateIDParams struct {
+	NotificationTemplateID uuid.UUID              `db:"notification_template_id" json:"notification_template_id"`
+	Method                 NullNotificationMethod `db:"method" json:"method"`
+	Limit                  int32                  `db:"limit" json:"limit"`
+}
+
+// Returns the most recent messages of a notification template, optionally
+// filtered by delivery method, to report on their delivery status.
+func (q *sqlQuerier) GetNotificationMessagesByTemplateID(ctx context.Context, arg GetNotificationMessagesByTemplateIDParams) ([]NotificationMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationMessagesByTemplateID, arg.NotificationTemplateID, arg.Method, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationMessage
+	for rows.Next() {
+		var i NotificationMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.NotificationTemplateID,
+			&i.UserID,
+			&i.Method,
+			&i.Status,
+			&i.StatusReason,
+			&i.CreatedBy,
+			&i.Payload,
+			&i.AttemptCount,
+			pq.Array(&i.Targets),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LeasedUntil,
+			&i.NextRetryAfter,
+			&i.QueuedSeconds,
+			&i.DedupeHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNotificationReportGeneratorLogByTemplate = `-- name: GetNotificationReportGeneratorLogByTemplate :one
 SELECT
 	notification_template_id, last_generated_at
@@ -18467,6 +19289,25 @@ func (q *sqlQuerier) GetNotificationTemplateByID(ctx context.Context, id uuid.UU
 	return i, err
 }
 
+const getNotificationTemplateWebhookByTemplateID = `-- name: GetNotificationTemplateWebhookByTemplateID :one
+SELECT notification_template_id, endpoint, payload_template, signing_secret, updated_at
+FROM notification_template_webhooks
+WHERE notification_template_id = $1::uuid
+`
+
+func (q *sqlQuerier) GetNotificationTemplateWebhookByTemplateID(ctx context.Context, notificationTemplateID uuid.UUID) (NotificationTemplateWebhook, error) {
+	row := q.db.QueryRowContext(ctx, getNotificationTemplateWebhookByTemplateID, notificationTemplateID)
+	var i NotificationTemplateWebhook
+	err := row.Scan(
+		&i.NotificationTemplateID,
+		&i.Endpoint,
+		&i.PayloadTemplate,
+		&i.SigningSecret,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getNotificationTemplatesByKind = `-- name: GetNotificationTemplatesByKind :many
 SELECT id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
 FROM notification_templates
@@ -18542,6 +19383,44 @@ func (q *sqlQuerier) GetUserNotificationPreferences(ctx context.Context, userID
 	return items, nil
 }
 
+const getUserNotificationScopedPreferences = `-- name: GetUserNotificationScopedPreferences :many
+SELECT user_id, notification_template_id, scope, target_id, disabled, created_at, updated_at
+FROM notification_scoped_preferences
+WHERE user_id = $1::uuid
+ORDER BY notification_template_id, target_id
+`
+
+func (q *sqlQuerier) GetUserNotificationScopedPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationScopedPreference, error) {
+	rows, err := q.db.QueryContext(ctx, getUserNotificationScopedPreferences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationScopedPreference
+	for rows.Next() {
+		var i NotificationScopedPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.NotificationTemplateID,
+			&i.Scope,
+			&i.TargetID,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWebpushSubscriptionsByUserID = `-- name: GetWebpushSubscriptionsByUserID :many
 SELECT id, user_id, created_at, endpoint, endpoint_p256dh_key, endpoint_auth_key
 FROM webpush_subscriptions
@@ -18691,6 +19570,80 @@ func (q *sqlQuerier) UpsertNotificationReportGeneratorLog(ctx context.Context, a
 	return err
 }
 
+const upsertNotificationTemplateWebhook = `-- name: UpsertNotificationTemplateWebhook :one
+INSERT INTO notification_template_webhooks (notification_template_id, endpoint, payload_template, signing_secret, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (notification_template_id)
+	DO UPDATE SET endpoint = EXCLUDED.endpoint,
+		payload_template = EXCLUDED.payload_template,
+		signing_secret = EXCLUDED.signing_secret,
+		updated_at = EXCLUDED.updated_at
+RETURNING notification_template_id, endpoint, payload_template, signing_secret, updated_at
+`
+
+type UpsertNotificationTemplateWebhookParams struct {
+	NotificationTemplateID uuid.UUID `db:"notification_template_id" json:"notification_template_id"`
+	Endpoint               string    `db:"endpoint" json:"endpoint"`
+	PayloadTemplate        string    `db:"payload_template" json:"payload_template"`
+	SigningSecret          string    `db:"signing_secret" json:"signing_secret"`
+	UpdatedAt              time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertNotificationTemplateWebhook(ctx context.Context, arg UpsertNotificationTemplateWebhookParams) (NotificationTemplateWebhook, error) {
+	row := q.db.QueryRowContext(ctx, upsertNotificationTemplateWebhook,
+		arg.NotificationTemplateID,
+		arg.Endpoint,
+		arg.PayloadTemplate,
+		arg.SigningSecret,
+		arg.UpdatedAt,
+	)
+	var i NotificationTemplateWebhook
+	err := row.Scan(
+		&i.NotificationTemplateID,
+		&i.Endpoint,
+		&i.PayloadTemplate,
+		&i.SigningSecret,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUserNotificationScopedPreferences = `-- name: UpsertUserNotificationScopedPreferences :execrows
+INSERT
+INTO notification_scoped_preferences (user_id, notification_template_id, scope, target_id, disabled)
+SELECT $1::uuid, new_values.notification_template_id, new_values.scope, new_values.target_id, new_values.disabled
+FROM (SELECT UNNEST($2::uuid[])           AS notification_template_id,
+             UNNEST($3::notification_preference_scope[]) AS scope,
+             UNNEST($4::uuid[])                          AS target_id,
+             UNNEST($5::bool[])                           AS disabled) AS new_values
+ON CONFLICT (user_id, notification_template_id, target_id) DO UPDATE
+    SET scope      = EXCLUDED.scope,
+        disabled   = EXCLUDED.disabled,
+        updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertUserNotificationScopedPreferencesParams struct {
+	UserID                  uuid.UUID                     `db:"user_id" json:"user_id"`
+	NotificationTemplateIds []uuid.UUID                   `db:"notification_template_ids" json:"notification_template_ids"`
+	Scopes                  []NotificationPreferenceScope `db:"scopes" json:"scopes"`
+	TargetIds               []uuid.UUID                   `db:"target_ids" json:"target_ids"`
+	Disableds               []bool                        `db:"disableds" json:"disableds"`
+}
+
+func (q *sqlQuerier) UpsertUserNotificationScopedPreferences(ctx context.Context, arg UpsertUserNotificationScopedPreferencesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upsertUserNotificationScopedPreferences,
+		arg.UserID,
+		pq.Array(arg.NotificationTemplateIds),
+		pq.Array(arg.Scopes),
+		pq.Array(arg.TargetIds),
+		pq.Array(arg.Disableds),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const countUnreadInboxNotificationsByUserID = `-- name: CountUnreadInboxNotificationsByUserID :one
 SELECT COUNT(*) FROM inbox_notifications WHERE user_id = $1 AND read_at IS NULL
 `
@@ -20391,7 +21344,7 @@ func (q *sqlQuerier) UpdateMemberRoles(ctx context.Context, arg UpdateMemberRole
 
 const getDefaultOrganization = `-- name: GetDefaultOrganization :one
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles, provisioner_tag_namespace, provisioner_daemon_quota
 FROM
     organizations
 WHERE
@@ -20415,13 +21368,15 @@ func (q *sqlQuerier) GetDefaultOrganization(ctx context.Context) (Organization,
 		&i.Deleted,
 		&i.ShareableWorkspaceOwners,
 		pq.Array(&i.DefaultOrgMemberRoles),
+		&i.ProvisionerTagNamespace,
+		&i.ProvisionerDaemonQuota,
 	)
 	return i, err
 }
 
 const getOrganizationByID = `-- name: GetOrganizationByID :one
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles, provisioner_tag_namespace, provisioner_daemon_quota
 FROM
     organizations
 WHERE
@@ -20443,13 +21398,15 @@ func (q *sqlQuerier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (Org
 		&i.Deleted,
 		&i.ShareableWorkspaceOwners,
 		pq.Array(&i.DefaultOrgMemberRoles),
+		&i.ProvisionerTagNamespace,
+		&i.ProvisionerDaemonQuota,
 	)
 	return i, err
 }
 
 const getOrganizationByName = `-- name: GetOrganizationByName :one
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles, provisioner_tag_namespace, provisioner_daemon_quota
 FROM
     organizations
 WHERE
@@ -20480,6 +21437,8 @@ func (q *sqlQuerier) GetOrganizationByName(ctx context.Context, arg GetOrganizat
 		&i.Deleted,
 		&i.ShareableWorkspaceOwners,
 		pq.Array(&i.DefaultOrgMemberRoles),
+		&i.ProvisionerTagNamespace,
+		&i.ProvisionerDaemonQuota,
 	)
 	return i, err
 }
@@ -20550,7 +21509,7 @@ func (q *sqlQuerier) GetOrganizationResourceCountByID(ctx context.Context, organ
 
 const getOrganizations = `-- name: GetOrganizations :many
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles, provisioner_tag_namespace, provisioner_daemon_quota
 FROM
     organizations
 WHERE
@@ -20596,6 +21555,8 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context, arg GetOrganizationsP
 			&i.Deleted,
 			&i.ShareableWorkspaceOwners,
 			pq.Array(&i.DefaultOrgMemberRoles),
+			&i.ProvisionerTagNamespace,
+			&i.ProvisionerDaemonQuota,
 		); err != nil {
 			return nil, err
 		}
@@ -20612,7 +21573,7 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context, arg GetOrganizationsP
 
 const getOrganizationsByUserID = `-- name: GetOrganizationsByUserID :many
 SELECT
-    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles
+    id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles, provisioner_tag_namespace, provisioner_daemon_quota
 FROM
     organizations
 WHERE
@@ -20659,6 +21620,8 @@ func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, arg GetOrgani
 			&i.Deleted,
 			&i.ShareableWorkspaceOwners,
 			pq.Array(&i.DefaultOrgMemberRoles),
+			&i.ProvisionerTagNamespace,
+			&i.ProvisionerDaemonQuota,
 		); err != nil {
 			return nil, err
 		}
@@ -20678,7 +21641,7 @@ INSERT INTO
     organizations (id, "name", display_name, description, icon, created_at, updated_at, is_default, default_org_member_roles)
 VALUES
     -- If no organizations exist, and this is the first, make it the default.
-    ($1, $2, $3, $4, $5, $6, $7, (SELECT TRUE FROM organizations LIMIT 1) IS NULL, $8) RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles
+    ($1, $2, $3, $4, $5, $6, $7, (SELECT TRUE FROM organizations LIMIT 1) IS NULL, $8) RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles, provisioner_tag_namespace, provisioner_daemon_quota
 `
 
 type InsertOrganizationParams struct {
@@ -20716,6 +21679,8 @@ func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizat
 		&i.Deleted,
 		&i.ShareableWorkspaceOwners,
 		pq.Array(&i.DefaultOrgMemberRoles),
+		&i.ProvisionerTagNamespace,
+		&i.ProvisionerDaemonQuota,
 	)
 	return i, err
 }
@@ -20732,7 +21697,7 @@ SET
     default_org_member_roles = $6
 WHERE
     id = $7
-RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles
+RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles, provisioner_tag_namespace, provisioner_daemon_quota
 `
 
 type UpdateOrganizationParams struct {
@@ -20768,6 +21733,8 @@ func (q *sqlQuerier) UpdateOrganization(ctx context.Context, arg UpdateOrganizat
 		&i.Deleted,
 		&i.ShareableWorkspaceOwners,
 		pq.Array(&i.DefaultOrgMemberRoles),
+		&i.ProvisionerTagNamespace,
+		&i.ProvisionerDaemonQuota,
 	)
 	return i, err
 }
@@ -20792,6 +21759,51 @@ func (q *sqlQuerier) UpdateOrganizationDeletedByID(ctx context.Context, arg Upda
 	return err
 }
 
+const updateOrganizationProvisionerDaemonSettings = `-- name: UpdateOrganizationProvisionerDaemonSettings :one
+UPDATE
+    organizations
+SET
+    provisioner_tag_namespace = $1,
+    provisioner_daemon_quota = $2,
+    updated_at = $3
+WHERE
+    id = $4
+RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles, provisioner_tag_namespace, provisioner_daemon_quota
+`
+
+type UpdateOrganizationProvisionerDaemonSettingsParams struct {
+	ProvisionerTagNamespace string    `db:"provisioner_tag_namespace" json:"provisioner_tag_namespace"`
+	ProvisionerDaemonQuota  int32     `db:"provisioner_daemon_quota" json:"provisioner_daemon_quota"`
+	UpdatedAt               time.Time `db:"updated_at" json:"updated_at"`
+	ID                      uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateOrganizationProvisionerDaemonSettings(ctx context.Context, arg UpdateOrganizationProvisionerDaemonSettingsParams) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, updateOrganizationProvisionerDaemonSettings,
+		arg.ProvisionerTagNamespace,
+		arg.ProvisionerDaemonQuota,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsDefault,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Deleted,
+		&i.ShareableWorkspaceOwners,
+		pq.Array(&i.DefaultOrgMemberRoles),
+		&i.ProvisionerTagNamespace,
+		&i.ProvisionerDaemonQuota,
+	)
+	return i, err
+}
+
 const updateOrganizationWorkspaceSharingSettings = `-- name: UpdateOrganizationWorkspaceSharingSettings :one
 UPDATE
     organizations
@@ -20800,7 +21812,7 @@ SET
     updated_at = $2
 WHERE
     id = $3
-RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles
+RETURNING id, name, description, created_at, updated_at, is_default, display_name, icon, deleted, shareable_workspace_owners, default_org_member_roles, provisioner_tag_namespace, provisioner_daemon_quota
 `
 
 type UpdateOrganizationWorkspaceSharingSettingsParams struct {
@@ -20824,6 +21836,8 @@ func (q *sqlQuerier) UpdateOrganizationWorkspaceSharingSettings(ctx context.Cont
 		&i.Deleted,
 		&i.ShareableWorkspaceOwners,
 		pq.Array(&i.DefaultOrgMemberRoles),
+		&i.ProvisionerTagNamespace,
+		&i.ProvisionerDaemonQuota,
 	)
 	return i, err
 }
@@ -20897,7 +21911,18 @@ SET owner_id   = $1::uuid,
 	-- These fields should not be set on prebuilds, but we defensively reset them here to prevent
 	-- accidental dormancy or deletion by the lifecycle executor.
 	dormant_at = NULL,
-	deleting_at = NULL
+	deleting_at = NULL,
+	-- Trial workspaces expire relative to the claim, not to when the prebuild
+	-- was provisioned.
+	expires_at = (
+		SELECT
+			CASE
+				WHEN templates.trial_workspace_ttl > 0 THEN
+					$3::timestamptz + (INTERVAL '1 millisecond' * (templates.trial_workspace_ttl / 1000000))
+			END
+		FROM templates
+		WHERE templates.id = w.template_id
+	)
 WHERE w.id IN (
 	SELECT p.id
 	FROM workspace_prebuilds p
@@ -21181,6 +22206,135 @@ func (q *sqlQuerier) GetOrganizationsWithPrebuildStatus(ctx context.Context, arg
 	return items, nil
 }
 
+const getPrebuildClaimInsights = `-- name: GetPrebuildClaimInsights :many
+WITH attempts AS (
+	SELECT id, preset_id, workspace_id, claimed, miss_reason, created_at
+	FROM prebuild_claim_attempts
+	WHERE created_at >= $1::timestamptz
+		AND created_at < $2::timestamptz
+),
+claim_builds AS (
+	-- The build that hands a claimed prebuilt workspace over to its new owner
+	-- is the first build created after the claim.
+	SELECT DISTINCT ON (attempts.id)
+		attempts.id AS attempt_id,
+		attempts.created_at AS claimed_at,
+		workspace_builds.job_id
+	FROM attempts
+		INNER JOIN workspace_builds ON workspace_builds.workspace_id = attempts.workspace_id
+			AND workspace_builds.created_at >= attempts.created_at
+	WHERE attempts.claimed
+	ORDER BY attempts.id, workspace_builds.build_number
+),
+time_to_ready AS (
+	-- A claimed workspace is ready once all of its agents are ready.
+	SELECT
+		claim_builds.attempt_id,
+		EXTRACT(EPOCH FROM MAX(workspace_agents.ready_at) - claim_builds.claimed_at)::float AS seconds
+	FROM claim_builds
+		INNER JOIN workspace_resources ON workspace_resources.job_id = claim_builds.job_id
+		INNER JOIN workspace_agents ON workspace_agents.resource_id = workspace_resources.id
+	WHERE NOT workspace_agents.deleted
+		AND workspace_agents.parent_id IS NULL
+	GROUP BY claim_builds.attempt_id, claim_builds.claimed_at
+	HAVING BOOL_AND(workspace_agents.ready_at IS NOT NULL)
+)
+SELECT
+	o.name AS organization_name,
+	t.id AS template_id,
+	t.name AS template_name,
+	tv.name AS template_version_name,
+	tvp.id AS preset_id,
+	tvp.name AS preset_name,
+	COUNT(*) AS claim_attempts,
+	COUNT(*) FILTER (WHERE attempts.claimed) AS claimed,
+	COUNT(*) FILTER (WHERE attempts.miss_reason = 'reconciliation_lag'::prebuild_claim_miss_reason) AS missed_reconciliation_lag,
+	COUNT(*) FILTER (WHERE attempts.miss_reason = 'capacity'::prebuild_claim_miss_reason) AS missed_capacity,
+	COUNT(*) FILTER (WHERE attempts.miss_reason = 'failures'::prebuild_claim_miss_reason) AS missed_failures,
+	COUNT(time_to_ready.seconds) AS ready_count,
+	COALESCE(SUM(time_to_ready.seconds), 0)::float AS time_to_ready_sum_seconds,
+	COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY time_to_ready.seconds), 0)::float AS time_to_ready_p50_seconds,
+	COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY time_to_ready.seconds), 0)::float AS time_to_ready_p95_seconds
+FROM attempts
+	INNER JOIN template_version_presets tvp ON tvp.id = attempts.preset_id
+	INNER JOIN template_versions tv ON tv.id = tvp.template_version_id
+	INNER JOIN templates t ON t.id = tv.template_id
+	INNER JOIN organizations o ON o.id = t.organization_id
+	LEFT JOIN time_to_ready ON time_to_ready.attempt_id = attempts.id
+WHERE CASE
+	WHEN array_length($3::uuid[], 1) > 0 THEN t.id = ANY($3::uuid[])
+	ELSE true
+END
+GROUP BY o.name, t.id, t.name, tv.name, tvp.id, tvp.name
+ORDER BY o.name, t.name, tv.name, tvp.name
+`
+
+type GetPrebuildClaimInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetPrebuildClaimInsightsRow struct {
+	OrganizationName        string    `db:"organization_name" json:"organization_name"`
+	TemplateID              uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName            string    `db:"template_name" json:"template_name"`
+	TemplateVersionName     string    `db:"template_version_name" json:"template_version_name"`
+	PresetID                uuid.UUID `db:"preset_id" json:"preset_id"`
+	PresetName              string    `db:"preset_name" json:"preset_name"`
+	ClaimAttempts           int64     `db:"claim_attempts" json:"claim_attempts"`
+	Claimed                 int64     `db:"claimed" json:"claimed"`
+	MissedReconciliationLag int64     `db:"missed_reconciliation_lag" json:"missed_reconciliation_lag"`
+	MissedCapacity          int64     `db:"missed_capacity" json:"missed_capacity"`
+	MissedFailures          int64     `db:"missed_failures" json:"missed_failures"`
+	ReadyCount              int64     `db:"ready_count" json:"ready_count"`
+	TimeToReadySumSeconds   float64   `db:"time_to_ready_sum_seconds" json:"time_to_ready_sum_seconds"`
+	TimeToReadyP50Seconds   float64   `db:"time_to_ready_p50_seconds" json:"time_to_ready_p50_seconds"`
+	TimeToReadyP95Seconds   float64   `db:"time_to_ready_p95_seconds" json:"time_to_ready_p95_seconds"`
+}
+
+// GetPrebuildClaimInsights reports, per preset, how many prebuilt workspace
+// claims were attempted within the given interval, how many of them succeeded,
+// why the others missed, and how long claimed workspaces took to become ready.
+func (q *sqlQuerier) GetPrebuildClaimInsights(ctx context.Context, arg GetPrebuildClaimInsightsParams) ([]GetPrebuildClaimInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPrebuildClaimInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPrebuildClaimInsightsRow
+	for rows.Next() {
+		var i GetPrebuildClaimInsightsRow
+		if err := rows.Scan(
+			&i.OrganizationName,
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.TemplateVersionName,
+			&i.PresetID,
+			&i.PresetName,
+			&i.ClaimAttempts,
+			&i.Claimed,
+			&i.MissedReconciliationLag,
+			&i.MissedCapacity,
+			&i.MissedFailures,
+			&i.ReadyCount,
+			&i.TimeToReadySumSeconds,
+			&i.TimeToReadyP50Seconds,
+			&i.TimeToReadyP95Seconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPrebuildMetrics = `-- name: GetPrebuildMetrics :many
 SELECT
 	t.name as template_name,
@@ -21241,6 +22395,63 @@ func (q *sqlQuerier) GetPrebuildMetrics(ctx context.Context) ([]GetPrebuildMetri
 	return items, nil
 }
 
+const getPresetPrebuildPoolState = `-- name: GetPresetPrebuildPoolState :one
+SELECT
+	tvp.desired_instances,
+	tvp.prebuild_status,
+	COUNT(wlb.id) FILTER (
+		WHERE wlb.transition = 'start'::workspace_transition
+		AND wlb.job_status IN ('pending'::provisioner_job_status, 'running'::provisioner_job_status)
+	)::int AS in_progress,
+	COUNT(wlb.id) FILTER (
+		WHERE wlb.transition = 'start'::workspace_transition
+		AND wlb.job_status = 'succeeded'::provisioner_job_status
+	)::int AS running,
+	COUNT(wlb.id) FILTER (
+		WHERE wlb.transition = 'start'::workspace_transition
+		AND wlb.job_status = 'failed'::provisioner_job_status
+		AND wlb.created_at >= $1::timestamptz
+	)::int AS recently_failed
+FROM template_version_presets tvp
+	LEFT JOIN workspace_latest_builds wlb ON wlb.template_version_preset_id = tvp.id
+		AND wlb.workspace_id IN (
+			SELECT w.id
+			FROM workspaces w
+			WHERE w.owner_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+				AND NOT w.deleted
+		)
+WHERE tvp.id = $2::uuid
+GROUP BY tvp.id
+`
+
+type GetPresetPrebuildPoolStateParams struct {
+	FailedSince time.Time `db:"failed_since" json:"failed_since"`
+	PresetID    uuid.UUID `db:"preset_id" json:"preset_id"`
+}
+
+type GetPresetPrebuildPoolStateRow struct {
+	DesiredInstances sql.NullInt32  `db:"desired_instances" json:"desired_instances"`
+	PrebuildStatus   PrebuildStatus `db:"prebuild_status" json:"prebuild_status"`
+	InProgress       int32          `db:"in_progress" json:"in_progress"`
+	Running          int32          `db:"running" json:"running"`
+	RecentlyFailed   int32          `db:"recently_failed" json:"recently_failed"`
+}
+
+// GetPresetPrebuildPoolState summarizes the prebuilt workspaces of a preset. It
+// is used to explain why no prebuilt workspace of the preset could be claimed.
+func (q *sqlQuerier) GetPresetPrebuildPoolState(ctx context.Context, arg GetPresetPrebuildPoolStateParams) (GetPresetPrebuildPoolStateRow, error) {
+	row := q.db.QueryRowContext(ctx, getPresetPrebuildPoolState, arg.FailedSince, arg.PresetID)
+	var i GetPresetPrebuildPoolStateRow
+	err := row.Scan(
+		&i.DesiredInstances,
+		&i.PrebuildStatus,
+		&i.InProgress,
+		&i.Running,
+		&i.RecentlyFailed,
+	)
+	return i, err
+}
+
 const getPresetsAtFailureLimit = `-- name: GetPresetsAtFailureLimit :many
 WITH filtered_builds AS (
 	-- Only select builds which are for prebuild creations
@@ -21585,6 +22796,54 @@ func (q *sqlQuerier) GetTemplatePresetsWithPrebuilds(ctx context.Context, templa
 	return items, nil
 }
 
+const insertPrebuildClaimAttempt = `-- name: InsertPrebuildClaimAttempt :one
+INSERT INTO prebuild_claim_attempts (
+	id,
+	preset_id,
+	workspace_id,
+	claimed,
+	miss_reason,
+	created_at
+) VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+) RETURNING id, preset_id, workspace_id, claimed, miss_reason, created_at
+`
+
+type InsertPrebuildClaimAttemptParams struct {
+	ID          uuid.UUID                   `db:"id" json:"id"`
+	PresetID    uuid.UUID                   `db:"preset_id" json:"preset_id"`
+	WorkspaceID uuid.NullUUID               `db:"workspace_id" json:"workspace_id"`
+	Claimed     bool                        `db:"claimed" json:"claimed"`
+	MissReason  NullPrebuildClaimMissReason `db:"miss_reason" json:"miss_reason"`
+	CreatedAt   time.Time                   `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertPrebuildClaimAttempt(ctx context.Context, arg InsertPrebuildClaimAttemptParams) (PrebuildClaimAttempt, error) {
+	row := q.db.QueryRowContext(ctx, insertPrebuildClaimAttempt,
+		arg.ID,
+		arg.PresetID,
+		arg.WorkspaceID,
+		arg.Claimed,
+		arg.MissReason,
+		arg.CreatedAt,
+	)
+	var i PrebuildClaimAttempt
+	err := row.Scan(
+		&i.ID,
+		&i.PresetID,
+		&i.WorkspaceID,
+		&i.Claimed,
+		&i.MissReason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const updatePrebuildProvisionerJobWithCancel = `-- name: UpdatePrebuildProvisionerJobWithCancel :many
 WITH jobs_to_cancel AS (
 	SELECT pj.id, w.id AS workspace_id, w.template_id, wpb.template_version_preset_id
@@ -22579,6 +23838,217 @@ func (q *sqlQuerier) UpsertProvisionerDaemon(ctx context.Context, arg UpsertProv
 	return i, err
 }
 
+const getProvisionerJobLogArchiveByJobID = `-- name: GetProvisionerJobLogArchiveByJobID :one
+SELECT
+	job_id, archived_at, object_key
+FROM
+	provisioner_job_log_archives
+WHERE
+	job_id = $1
+`
+
+func (q *sqlQuerier) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (ProvisionerJobLogArchive, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerJobLogArchiveByJobID, jobID)
+	var i ProvisionerJobLogArchive
+	err := row.Scan(&i.JobID, &i.ArchivedAt, &i.ObjectKey)
+	return i, err
+}
+
+const getProvisionerJobLogArchivesByJobIDs = `-- name: GetProvisionerJobLogArchivesByJobIDs :many
+SELECT
+	job_id, archived_at, object_key
+FROM
+	provisioner_job_log_archives
+WHERE
+	job_id = ANY($1 :: uuid [ ])
+`
+
+func (q *sqlQuerier) GetProvisionerJobLogArchivesByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]ProvisionerJobLogArchive, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobLogArchivesByJobIDs, pq.Array(jobIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobLogArchive
+	for rows.Next() {
+		var i ProvisionerJobLogArchive
+		if err := rows.Scan(&i.JobID, &i.ArchivedAt, &i.ObjectKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProvisionerJobsWithExpiredLogs = `-- name: GetProvisionerJobsWithExpiredLogs :many
+SELECT
+	provisioner_jobs.id
+FROM
+	provisioner_jobs
+LEFT JOIN
+	workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+LEFT JOIN
+	template_versions ON template_versions.id = workspace_builds.template_version_id
+	OR template_versions.job_id = provisioner_jobs.id
+LEFT JOIN
+	templates ON templates.id = template_versions.template_id
+WHERE
+	provisioner_jobs.completed_at IS NOT NULL
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			provisioner_job_log_archives
+		WHERE
+			provisioner_job_log_archives.job_id = provisioner_jobs.id
+	)
+	AND (
+		workspace_builds.id IS NULL
+		OR EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_builds AS later_builds
+			WHERE
+				later_builds.workspace_id = workspace_builds.workspace_id
+				AND later_builds.build_number > workspace_builds.build_number
+		)
+	)
+	AND provisioner_jobs.completed_at < $1::timestamptz - (
+		CASE
+			WHEN COALESCE(templates.build_log_retention, 0) > 0 THEN templates.build_log_retention
+			ELSE $2::bigint
+		END / 1000 * INTERVAL '1 microsecond'
+	)
+	AND (COALESCE(templates.build_log_retention, 0) > 0 OR $2::bigint > 0)
+ORDER BY
+	provisioner_jobs.completed_at ASC
+LIMIT
+	$3
+`
+
+type GetProvisionerJobsWithExpiredLogsParams struct {
+	Now              time.Time `db:"now" json:"now"`
+	DefaultRetention int64     `db:"default_retention" json:"default_retention"`
+	LimitCount       int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Returns completed provisioner jobs whose logs outlived the build log
+// retention of their template, or the deployment-wide retention if the
+// template does not set one. The logs of the latest build of each workspace
+// are always kept. Jobs that were already archived are skipped.
+func (q *sqlQuerier) GetProvisionerJobsWithExpiredLogs(ctx context.Context, arg GetProvisionerJobsWithExpiredLogsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsWithExpiredLogs, arg.Now, arg.DefaultRetention, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertProvisionerJobLogArchive = `-- name: InsertProvisionerJobLogArchive :exec
+INSERT INTO
+	provisioner_job_log_archives (
+		job_id,
+		archived_at,
+		object_key
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (job_id) DO NOTHING
+`
+
+type InsertProvisionerJobLogArchiveParams struct {
+	JobID      uuid.UUID `db:"job_id" json:"job_id"`
+	ArchivedAt time.Time `db:"archived_at" json:"archived_at"`
+	ObjectKey  string    `db:"object_key" json:"object_key"`
+}
+
+func (q *sqlQuerier) InsertProvisionerJobLogArchive(ctx context.Context, arg InsertProvisionerJobLogArchiveParams) error {
+	_, err := q.db.ExecContext(ctx, insertProvisionerJobLogArchive, arg.JobID, arg.ArchivedAt, arg.ObjectKey)
+	return err
+}
+
+const deleteProvisionerJobLogsByJobID = `-- name: DeleteProvisionerJobLogsByJobID :execrows
+DELETE FROM
+	provisioner_job_logs
+WHERE
+	job_id = $1
+`
+
+func (q *sqlQuerier) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProvisionerJobLogsByJobID, jobID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getProvisionerJobErrorLogsByJobIDs = `-- name: GetProvisionerJobErrorLogsByJobIDs :many
+SELECT
+	job_id, created_at, source, level, stage, output, id
+FROM
+	provisioner_job_logs
+WHERE
+	job_id = ANY($1 :: uuid [ ])
+	AND level = 'error'
+ORDER BY
+	id ASC
+`
+
+// Returns the error-level logs of the given jobs. Used to summarize the root
+// cause of failed builds without fetching their full logs.
+func (q *sqlQuerier) GetProvisionerJobErrorLogsByJobIDs(ctx context.Context, jobIds []uuid.UUID) ([]ProvisionerJobLog, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobErrorLogsByJobIDs, pq.Array(jobIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobLog
+	for rows.Next() {
+		var i ProvisionerJobLog
+		if err := rows.Scan(
+			&i.JobID,
+			&i.CreatedAt,
+			&i.Source,
+			&i.Level,
+			&i.Stage,
+			&i.Output,
+			&i.ID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
 SELECT
 	job_id, created_at, source, level, stage, output, id
@@ -22754,7 +24224,7 @@ WHERE
 		SKIP LOCKED
 		LIMIT
 			1
-	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed
+	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed, timeout
 `
 
 type AcquireProvisionerJobParams struct {
@@ -22802,13 +24272,33 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		&i.JobStatus,
 		&i.LogsLength,
 		&i.LogsOverflowed,
+		&i.Timeout,
 	)
 	return i, err
 }
 
+const countRunningProvisionerJobsByWorkerID = `-- name: CountRunningProvisionerJobsByWorkerID :one
+SELECT
+	COUNT(*)
+FROM
+	provisioner_jobs
+WHERE
+	worker_id = $1::uuid
+	AND started_at IS NOT NULL
+	AND completed_at IS NULL
+`
+
+// Counts the jobs a provisioner daemon acquired that haven't completed yet.
+func (q *sqlQuerier) CountRunningProvisionerJobsByWorkerID(ctx context.Context, workerID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRunningProvisionerJobsByWorkerID, workerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed, timeout
 FROM
 	provisioner_jobs
 WHERE
@@ -22840,13 +24330,14 @@ func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (P
 		&i.JobStatus,
 		&i.LogsLength,
 		&i.LogsOverflowed,
+		&i.Timeout,
 	)
 	return i, err
 }
 
 const getProvisionerJobByIDForUpdate = `-- name: GetProvisionerJobByIDForUpdate :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed, timeout
 FROM
 	provisioner_jobs
 WHERE
@@ -22882,13 +24373,14 @@ func (q *sqlQuerier) GetProvisionerJobByIDForUpdate(ctx context.Context, id uuid
 		&i.JobStatus,
 		&i.LogsLength,
 		&i.LogsOverflowed,
+		&i.Timeout,
 	)
 	return i, err
 }
 
 const getProvisionerJobByIDWithLock = `-- name: GetProvisionerJobByIDWithLock :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed, timeout
 FROM
 	provisioner_jobs
 WHERE
@@ -22923,6 +24415,7 @@ func (q *sqlQuerier) GetProvisionerJobByIDWithLock(ctx context.Context, id uuid.
 		&i.JobStatus,
 		&i.LogsLength,
 		&i.LogsOverflowed,
+		&i.Timeout,
 	)
 	return i, err
 }
@@ -23028,7 +24521,7 @@ SELECT
 	-- Step 5: Final SELECT with INNER JOIN provisioner_jobs
 	fj.id,
 	fj.created_at,
-	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status, pj.logs_length, pj.logs_overflowed,
+	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status, pj.logs_length, pj.logs_overflowed, pj.timeout,
 	fj.queue_position,
 	fj.queue_size
 FROM
@@ -23086,6 +24579,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 			&i.ProvisionerJob.JobStatus,
 			&i.ProvisionerJob.LogsLength,
 			&i.ProvisionerJob.LogsOverflowed,
+			&i.ProvisionerJob.Timeout,
 			&i.QueuePosition,
 			&i.QueueSize,
 		); err != nil {
@@ -23102,6 +24596,185 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 	return items, nil
 }
 
+const getProvisionerJobsByInitiatorWithQueuePosition = `-- name: GetProvisionerJobsByInitiatorWithQueuePosition :many
+WITH pending_jobs AS (
+    SELECT
+        id, initiator_id, created_at
+    FROM
+        provisioner_jobs
+    WHERE
+        started_at IS NULL
+    AND
+        canceled_at IS NULL
+    AND
+        completed_at IS NULL
+    AND
+        error IS NULL
+),
+queue_position AS (
+    SELECT
+        id,
+        ROW_NUMBER() OVER (ORDER BY initiator_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid ASC, created_at ASC) AS queue_position
+    FROM
+        pending_jobs
+),
+queue_size AS (
+	SELECT COUNT(*) AS count FROM pending_jobs
+)
+SELECT
+	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status, pj.logs_length, pj.logs_overflowed, pj.timeout,
+    COALESCE(qp.queue_position, 0) AS queue_position,
+    COALESCE(qs.count, 0) AS queue_size,
+	-- Use subquery to utilize ORDER BY in array_agg since it cannot be
+	-- combined with FILTER.
+	(
+		SELECT
+			-- Order for stable output.
+			array_agg(pd.id ORDER BY pd.created_at ASC)::uuid[]
+		FROM
+			provisioner_daemons pd
+		WHERE
+			-- See AcquireProvisionerJob.
+			pj.started_at IS NULL
+			AND pj.organization_id = pd.organization_id
+			AND pj.provisioner = ANY(pd.provisioners)
+			AND provisioner_tagset_contains(pd.tags, pj.tags)
+	) AS available_workers,
+	-- Include template and workspace information.
+	COALESCE(tv.name, '') AS template_version_name,
+	t.id AS template_id,
+	COALESCE(t.name, '') AS template_name,
+	COALESCE(t.display_name, '') AS template_display_name,
+	COALESCE(t.icon, '') AS template_icon,
+	w.id AS workspace_id,
+	COALESCE(w.name, '') AS workspace_name,
+	-- Include the name of the provisioner_daemon associated to the job
+	COALESCE(pd.name, '') AS worker_name,
+	wb.transition as workspace_build_transition
+FROM
+	provisioner_jobs pj
+LEFT JOIN
+	queue_position qp ON qp.id = pj.id
+LEFT JOIN
+	queue_size qs ON TRUE
+LEFT JOIN
+	workspace_builds wb ON wb.id = CASE WHEN pj.input ? 'workspace_build_id' THEN (pj.input->>'workspace_build_id')::uuid END
+LEFT JOIN
+	workspaces w ON (
+		w.id = wb.workspace_id
+		AND w.organization_id = pj.organization_id
+	)
+LEFT JOIN
+	-- We should always have a template version, either explicitly or implicitly via workspace build.
+	template_versions tv ON (
+		tv.id = CASE WHEN pj.input ? 'template_version_id' THEN (pj.input->>'template_version_id')::uuid ELSE wb.template_version_id END
+		AND tv.organization_id = pj.organization_id
+	)
+LEFT JOIN
+	templates t ON (
+		t.id = tv.template_id
+		AND t.organization_id = pj.organization_id
+	)
+LEFT JOIN
+	-- Join to get the daemon name corresponding to the job's worker_id
+	provisioner_daemons pd ON pd.id = pj.worker_id
+WHERE
+	pj.initiator_id = $1::uuid
+	AND pj.job_status IN ('pending', 'running', 'canceling')
+GROUP BY
+	pj.id,
+	qp.queue_position,
+	qs.count,
+	tv.name,
+	t.id,
+	t.name,
+	t.display_name,
+	t.icon,
+	w.id,
+	w.name,
+	pd.name,
+	wb.transition
+ORDER BY
+	pj.created_at DESC
+`
+
+type GetProvisionerJobsByInitiatorWithQueuePositionRow struct {
+	ProvisionerJob           ProvisionerJob          `db:"provisioner_job" json:"provisioner_job"`
+	QueuePosition            int64                   `db:"queue_position" json:"queue_position"`
+	QueueSize                int64                   `db:"queue_size" json:"queue_size"`
+	AvailableWorkers         []uuid.UUID             `db:"available_workers" json:"available_workers"`
+	TemplateVersionName      string                  `db:"template_version_name" json:"template_version_name"`
+	TemplateID               uuid.NullUUID           `db:"template_id" json:"template_id"`
+	TemplateName             string                  `db:"template_name" json:"template_name"`
+	TemplateDisplayName      string                  `db:"template_display_name" json:"template_display_name"`
+	TemplateIcon             string                  `db:"template_icon" json:"template_icon"`
+	WorkspaceID              uuid.NullUUID           `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName            string                  `db:"workspace_name" json:"workspace_name"`
+	WorkerName               string                  `db:"worker_name" json:"worker_name"`
+	WorkspaceBuildTransition NullWorkspaceTransition `db:"workspace_build_transition" json:"workspace_build_transition"`
+}
+
+// Returns the in-flight provisioner jobs started by a user across all
+// organizations. The columns match
+// GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner.
+func (q *sqlQuerier) GetProvisionerJobsByInitiatorWithQueuePosition(ctx context.Context, initiatorID uuid.UUID) ([]GetProvisionerJobsByInitiatorWithQueuePositionRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByInitiatorWithQueuePosition, initiatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProvisionerJobsByInitiatorWithQueuePositionRow
+	for rows.Next() {
+		var i GetProvisionerJobsByInitiatorWithQueuePositionRow
+		if err := rows.Scan(
+			&i.ProvisionerJob.ID,
+			&i.ProvisionerJob.CreatedAt,
+			&i.ProvisionerJob.UpdatedAt,
+			&i.ProvisionerJob.StartedAt,
+			&i.ProvisionerJob.CanceledAt,
+			&i.ProvisionerJob.CompletedAt,
+			&i.ProvisionerJob.Error,
+			&i.ProvisionerJob.OrganizationID,
+			&i.ProvisionerJob.InitiatorID,
+			&i.ProvisionerJob.Provisioner,
+			&i.ProvisionerJob.StorageMethod,
+			&i.ProvisionerJob.Type,
+			&i.ProvisionerJob.Input,
+			&i.ProvisionerJob.WorkerID,
+			&i.ProvisionerJob.FileID,
+			&i.ProvisionerJob.Tags,
+			&i.ProvisionerJob.ErrorCode,
+			&i.ProvisionerJob.TraceMetadata,
+			&i.ProvisionerJob.JobStatus,
+			&i.ProvisionerJob.LogsLength,
+			&i.ProvisionerJob.LogsOverflowed,
+			&i.ProvisionerJob.Timeout,
+			&i.QueuePosition,
+			&i.QueueSize,
+			pq.Array(&i.AvailableWorkers),
+			&i.TemplateVersionName,
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.TemplateDisplayName,
+			&i.TemplateIcon,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.WorkerName,
+			&i.WorkspaceBuildTransition,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner = `-- name: GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner :many
 WITH pending_jobs AS (
     SELECT
@@ -23128,7 +24801,7 @@ queue_size AS (
 	SELECT COUNT(*) AS count FROM pending_jobs
 )
 SELECT
-	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status, pj.logs_length, pj.logs_overflowed,
+	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status, pj.logs_length, pj.logs_overflowed, pj.timeout,
     COALESCE(qp.queue_position, 0) AS queue_position,
     COALESCE(qs.count, 0) AS queue_size,
 	-- Use subquery to utilize ORDER BY in array_agg since it cannot be
@@ -23272,6 +24945,7 @@ func (q *sqlQuerier) GetProvisionerJobsByOrganizationAndStatusWithQueuePositionA
 			&i.ProvisionerJob.JobStatus,
 			&i.ProvisionerJob.LogsLength,
 			&i.ProvisionerJob.LogsOverflowed,
+			&i.ProvisionerJob.Timeout,
 			&i.QueuePosition,
 			&i.QueueSize,
 			pq.Array(&i.AvailableWorkers),
@@ -23299,7 +24973,7 @@ func (q *sqlQuerier) GetProvisionerJobsByOrganizationAndStatusWithQueuePositionA
 }
 
 const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
-SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed FROM provisioner_jobs WHERE created_at > $1
+SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed, timeout FROM provisioner_jobs WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
@@ -23333,6 +25007,7 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 			&i.JobStatus,
 			&i.LogsLength,
 			&i.LogsOverflowed,
+			&i.Timeout,
 		); err != nil {
 			return nil, err
 		}
@@ -23349,7 +25024,7 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 
 const getProvisionerJobsToBeReaped = `-- name: GetProvisionerJobsToBeReaped :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed, timeout
 FROM
 	provisioner_jobs
 WHERE
@@ -23366,19 +25041,34 @@ WHERE
 		AND started_at IS NOT NULL
 		AND completed_at IS NULL
 	)
+	OR
+	(
+		-- If the job has been started but the provisioner daemon running it
+		-- has not sent a heartbeat since @orphaned_since, reap it.
+		updated_at < $3
+		AND started_at IS NOT NULL
+		AND completed_at IS NULL
+		AND worker_id IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM provisioner_daemons
+			WHERE provisioner_daemons.id = provisioner_jobs.worker_id
+			AND provisioner_daemons.last_seen_at >= $3
+		)
+	)
 ORDER BY random()
-LIMIT $3
+LIMIT $4
 `
 
 type GetProvisionerJobsToBeReapedParams struct {
-	PendingSince time.Time `db:"pending_since" json:"pending_since"`
-	HungSince    time.Time `db:"hung_since" json:"hung_since"`
-	MaxJobs      int32     `db:"max_jobs" json:"max_jobs"`
+	PendingSince  time.Time `db:"pending_since" json:"pending_since"`
+	HungSince     time.Time `db:"hung_since" json:"hung_since"`
+	OrphanedSince time.Time `db:"orphaned_since" json:"orphaned_since"`
+	MaxJobs       int32     `db:"max_jobs" json:"max_jobs"`
 }
 
 // To avoid repeatedly attempting to reap the same jobs, we randomly order and limit to @max_jobs.
 func (q *sqlQuerier) GetProvisionerJobsToBeReaped(ctx context.Context, arg GetProvisionerJobsToBeReapedParams) ([]ProvisionerJob, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerJobsToBeReaped, arg.PendingSince, arg.HungSince, arg.MaxJobs)
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsToBeReaped, arg.PendingSince, arg.HungSince, arg.OrphanedSince, arg.MaxJobs)
 	if err != nil {
 		return nil, err
 	}
@@ -23408,6 +25098,7 @@ func (q *sqlQuerier) GetProvisionerJobsToBeReaped(ctx context.Context, arg GetPr
 			&i.JobStatus,
 			&i.LogsLength,
 			&i.LogsOverflowed,
+			&i.Timeout,
 		); err != nil {
 			return nil, err
 		}
@@ -23440,7 +25131,7 @@ INSERT INTO
 		logs_overflowed
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, logs_length, logs_overflowed, timeout
 `
 
 type InsertProvisionerJobParams struct {
@@ -23498,6 +25189,7 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		&i.JobStatus,
 		&i.LogsLength,
 		&i.LogsOverflowed,
+		&i.Timeout,
 	)
 	return i, err
 }
@@ -23583,6 +25275,25 @@ func (q *sqlQuerier) UpdateProvisionerJobByID(ctx context.Context, arg UpdatePro
 	return err
 }
 
+const updateProvisionerJobTimeoutByID = `-- name: UpdateProvisionerJobTimeoutByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	timeout = $2
+WHERE
+	id = $1
+`
+
+type UpdateProvisionerJobTimeoutByIDParams struct {
+	ID      uuid.UUID `db:"id" json:"id"`
+	Timeout int64     `db:"timeout" json:"timeout"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerJobTimeoutByID(ctx context.Context, arg UpdateProvisionerJobTimeoutByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobTimeoutByID, arg.ID, arg.Timeout)
+	return err
+}
+
 const updateProvisionerJobWithCancelByID = `-- name: UpdateProvisionerJobWithCancelByID :exec
 UPDATE
 	provisioner_jobs
@@ -23669,6 +25380,30 @@ func (q *sqlQuerier) UpdateProvisionerJobWithCompleteWithStartedAtByID(ctx conte
 	return err
 }
 
+const updateProvisionerJobWithRequeueByID = `-- name: UpdateProvisionerJobWithRequeueByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2,
+	started_at = NULL,
+	worker_id = NULL
+WHERE
+	id = $1
+	AND completed_at IS NULL
+`
+
+type UpdateProvisionerJobWithRequeueByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Returns a started job to the queue so that another provisioner daemon can
+// acquire it.
+func (q *sqlQuerier) UpdateProvisionerJobWithRequeueByID(ctx context.Context, arg UpdateProvisionerJobWithRequeueByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithRequeueByID, arg.ID, arg.UpdatedAt)
+	return err
+}
+
 const deleteProvisionerKey = `-- name: DeleteProvisionerKey :exec
 DELETE FROM
     provisioner_keys
@@ -23683,11 +25418,14 @@ func (q *sqlQuerier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) err
 
 const getProvisionerKeyByHashedSecret = `-- name: GetProvisionerKeyByHashedSecret :one
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
     hashed_secret = $1
+    -- The previous secret of a rotated key is matched as well, the caller
+    -- checks whether it expired.
+    OR previous_hashed_secret = $1
 `
 
 func (q *sqlQuerier) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (ProvisionerKey, error) {
@@ -23700,13 +25438,16 @@ func (q *sqlQuerier) GetProvisionerKeyByHashedSecret(ctx context.Context, hashed
 		&i.Name,
 		&i.HashedSecret,
 		&i.Tags,
+		&i.LastUsedAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const getProvisionerKeyByID = `-- name: GetProvisionerKeyByID :one
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
@@ -23723,13 +25464,16 @@ func (q *sqlQuerier) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (P
 		&i.Name,
 		&i.HashedSecret,
 		&i.Tags,
+		&i.LastUsedAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const getProvisionerKeyByName = `-- name: GetProvisionerKeyByName :one
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
@@ -23753,6 +25497,9 @@ func (q *sqlQuerier) GetProvisionerKeyByName(ctx context.Context, arg GetProvisi
 		&i.Name,
 		&i.HashedSecret,
 		&i.Tags,
+		&i.LastUsedAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
@@ -23768,7 +25515,7 @@ INSERT INTO
         tags
     )
 VALUES
-    ($1, $2, $3, lower($6), $4, $5) RETURNING id, created_at, organization_id, name, hashed_secret, tags
+    ($1, $2, $3, lower($6), $4, $5) RETURNING id, created_at, organization_id, name, hashed_secret, tags, last_used_at, previous_hashed_secret, previous_secret_expires_at
 `
 
 type InsertProvisionerKeyParams struct {
@@ -23797,13 +25544,16 @@ func (q *sqlQuerier) InsertProvisionerKey(ctx context.Context, arg InsertProvisi
 		&i.Name,
 		&i.HashedSecret,
 		&i.Tags,
+		&i.LastUsedAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const listProvisionerKeysByOrganization = `-- name: ListProvisionerKeysByOrganization :many
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
@@ -23826,6 +25576,9 @@ func (q *sqlQuerier) ListProvisionerKeysByOrganization(ctx context.Context, orga
 			&i.Name,
 			&i.HashedSecret,
 			&i.Tags,
+			&i.LastUsedAt,
+			&i.PreviousHashedSecret,
+			&i.PreviousSecretExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -23842,7 +25595,7 @@ func (q *sqlQuerier) ListProvisionerKeysByOrganization(ctx context.Context, orga
 
 const listProvisionerKeysByOrganizationExcludeReserved = `-- name: ListProvisionerKeysByOrganizationExcludeReserved :many
 SELECT
-    id, created_at, organization_id, name, hashed_secret, tags
+    id, created_at, organization_id, name, hashed_secret, tags, last_used_at, previous_hashed_secret, previous_secret_expires_at
 FROM
     provisioner_keys
 WHERE
@@ -23874,6 +25627,9 @@ func (q *sqlQuerier) ListProvisionerKeysByOrganizationExcludeReserved(ctx contex
 			&i.Name,
 			&i.HashedSecret,
 			&i.Tags,
+			&i.LastUsedAt,
+			&i.PreviousHashedSecret,
+			&i.PreviousSecretExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -23888,6 +25644,62 @@ func (q *sqlQuerier) ListProvisionerKeysByOrganizationExcludeReserved(ctx contex
 	return items, nil
 }
 
+const rotateProvisionerKey = `-- name: RotateProvisionerKey :one
+UPDATE
+    provisioner_keys
+SET
+    previous_hashed_secret = hashed_secret,
+    previous_secret_expires_at = $1,
+    hashed_secret = $2
+WHERE
+    id = $3
+RETURNING id, created_at, organization_id, name, hashed_secret, tags, last_used_at, previous_hashed_secret, previous_secret_expires_at
+`
+
+type RotateProvisionerKeyParams struct {
+	PreviousSecretExpiresAt sql.NullTime `db:"previous_secret_expires_at" json:"previous_secret_expires_at"`
+	HashedSecret            []byte       `db:"hashed_secret" json:"hashed_secret"`
+	ID                      uuid.UUID    `db:"id" json:"id"`
+}
+
+// Replaces the secret of a provisioner key. The current secret becomes the
+// previous secret, which stays valid until previous_secret_expires_at.
+func (q *sqlQuerier) RotateProvisionerKey(ctx context.Context, arg RotateProvisionerKeyParams) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, rotateProvisionerKey, arg.PreviousSecretExpiresAt, arg.HashedSecret, arg.ID)
+	var i ProvisionerKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.Name,
+		&i.HashedSecret,
+		&i.Tags,
+		&i.LastUsedAt,
+		&i.PreviousHashedSecret,
+		&i.PreviousSecretExpiresAt,
+	)
+	return i, err
+}
+
+const updateProvisionerKeyLastUsedAt = `-- name: UpdateProvisionerKeyLastUsedAt :exec
+UPDATE
+    provisioner_keys
+SET
+    last_used_at = $1
+WHERE
+    id = $2
+`
+
+type UpdateProvisionerKeyLastUsedAtParams struct {
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerKeyLastUsedAt(ctx context.Context, arg UpdateProvisionerKeyLastUsedAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerKeyLastUsedAt, arg.LastUsedAt, arg.ID)
+	return err
+}
+
 const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
 SELECT
 	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
@@ -24770,6 +26582,392 @@ func (q *sqlQuerier) UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleP
 	return i, err
 }
 
+const detachSharedVolumeAttachmentByID = `-- name: DetachSharedVolumeAttachmentByID :one
+UPDATE
+	shared_volume_attachments
+SET
+	detached_at = $1::timestamptz,
+	detached_by = $2::uuid
+WHERE
+	id = $3
+	AND detached_at IS NULL
+RETURNING id, shared_volume_id, workspace_id, workspace_build_id, workspace_agent_id, mount_path, access_mode, attached_at, detached_at, detached_by
+`
+
+type DetachSharedVolumeAttachmentByIDParams struct {
+	DetachedAt time.Time `db:"detached_at" json:"detached_at"`
+	DetachedBy uuid.UUID `db:"detached_by" json:"detached_by"`
+	ID         uuid.UUID `db:"id" json:"id"`
+}
+
+// Force-detaches an active attachment, releasing the read-write lock it holds.
+func (q *sqlQuerier) DetachSharedVolumeAttachmentByID(ctx context.Context, arg DetachSharedVolumeAttachmentByIDParams) (SharedVolumeAttachment, error) {
+	row := q.db.QueryRowContext(ctx, detachSharedVolumeAttachmentByID, arg.DetachedAt, arg.DetachedBy, arg.ID)
+	var i SharedVolumeAttachment
+	err := row.Scan(
+		&i.ID,
+		&i.SharedVolumeID,
+		&i.WorkspaceID,
+		&i.WorkspaceBuildID,
+		&i.WorkspaceAgentID,
+		&i.MountPath,
+		&i.AccessMode,
+		&i.AttachedAt,
+		&i.DetachedAt,
+		&i.DetachedBy,
+	)
+	return i, err
+}
+
+const detachSharedVolumeAttachmentsByWorkspaceID = `-- name: DetachSharedVolumeAttachmentsByWorkspaceID :exec
+UPDATE
+	shared_volume_attachments
+SET
+	detached_at = $1::timestamptz
+WHERE
+	workspace_id = $2
+	AND detached_at IS NULL
+`
+
+type DetachSharedVolumeAttachmentsByWorkspaceIDParams struct {
+	DetachedAt  time.Time `db:"detached_at" json:"detached_at"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+}
+
+// Detaches the volumes attached by previous builds of a workspace. Every build
+// attaches the volumes it declares anew.
+func (q *sqlQuerier) DetachSharedVolumeAttachmentsByWorkspaceID(ctx context.Context, arg DetachSharedVolumeAttachmentsByWorkspaceIDParams) error {
+	_, err := q.db.ExecContext(ctx, detachSharedVolumeAttachmentsByWorkspaceID, arg.DetachedAt, arg.WorkspaceID)
+	return err
+}
+
+const getActiveSharedVolumeAttachmentsBySharedVolumeID = `-- name: GetActiveSharedVolumeAttachmentsBySharedVolumeID :many
+SELECT
+	shared_volume_attachments.id, shared_volume_attachments.shared_volume_id, shared_volume_attachments.workspace_id, shared_volume_attachments.workspace_build_id, shared_volume_attachments.workspace_agent_id, shared_volume_attachments.mount_path, shared_volume_attachments.access_mode, shared_volume_attachments.attached_at, shared_volume_attachments.detached_at, shared_volume_attachments.detached_by,
+	workspaces.name AS workspace_name,
+	users.username AS workspace_owner_username,
+	workspace_agents.name AS workspace_agent_name
+FROM
+	shared_volume_attachments
+JOIN
+	workspaces ON workspaces.id = shared_volume_attachments.workspace_id
+JOIN
+	users ON users.id = workspaces.owner_id
+JOIN
+	workspace_agents ON workspace_agents.id = shared_volume_attachments.workspace_agent_id
+WHERE
+	shared_volume_attachments.shared_volume_id = $1
+	AND shared_volume_attachments.detached_at IS NULL
+ORDER BY
+	shared_volume_attachments.attached_at, shared_volume_attachments.id
+`
+
+type GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow struct {
+	SharedVolumeAttachment SharedVolumeAttachment `db:"shared_volume_attachment" json:"shared_volume_attachment"`
+	WorkspaceName          string                 `db:"workspace_name" json:"workspace_name"`
+	WorkspaceOwnerUsername string                 `db:"workspace_owner_username" json:"workspace_owner_username"`
+	WorkspaceAgentName     string                 `db:"workspace_agent_name" json:"workspace_agent_name"`
+}
+
+func (q *sqlQuerier) GetActiveSharedVolumeAttachmentsBySharedVolumeID(ctx context.Context, sharedVolumeID uuid.UUID) ([]GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveSharedVolumeAttachmentsBySharedVolumeID, sharedVolumeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow
+	for rows.Next() {
+		var i GetActiveSharedVolumeAttachmentsBySharedVolumeIDRow
+		if err := rows.Scan(
+			&i.SharedVolumeAttachment.ID,
+			&i.SharedVolumeAttachment.SharedVolumeID,
+			&i.SharedVolumeAttachment.WorkspaceID,
+			&i.SharedVolumeAttachment.WorkspaceBuildID,
+			&i.SharedVolumeAttachment.WorkspaceAgentID,
+			&i.SharedVolumeAttachment.MountPath,
+			&i.SharedVolumeAttachment.AccessMode,
+			&i.SharedVolumeAttachment.AttachedAt,
+			&i.SharedVolumeAttachment.DetachedAt,
+			&i.SharedVolumeAttachment.DetachedBy,
+			&i.WorkspaceName,
+			&i.WorkspaceOwnerUsername,
+			&i.WorkspaceAgentName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSharedVolumeAttachmentByID = `-- name: GetSharedVolumeAttachmentByID :one
+SELECT
+	id, shared_volume_id, workspace_id, workspace_build_id, workspace_agent_id, mount_path, access_mode, attached_at, detached_at, detached_by
+FROM
+	shared_volume_attachments
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetSharedVolumeAttachmentByID(ctx context.Context, id uuid.UUID) (SharedVolumeAttachment, error) {
+	row := q.db.QueryRowContext(ctx, getSharedVolumeAttachmentByID, id)
+	var i SharedVolumeAttachment
+	err := row.Scan(
+		&i.ID,
+		&i.SharedVolumeID,
+		&i.WorkspaceID,
+		&i.WorkspaceBuildID,
+		&i.WorkspaceAgentID,
+		&i.MountPath,
+		&i.AccessMode,
+		&i.AttachedAt,
+		&i.DetachedAt,
+		&i.DetachedBy,
+	)
+	return i, err
+}
+
+const getSharedVolumeByID = `-- name: GetSharedVolumeByID :one
+SELECT
+	id, organization_id, name, display_name, description, template_id, created_at, updated_at
+FROM
+	shared_volumes
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetSharedVolumeByID(ctx context.Context, id uuid.UUID) (SharedVolume, error) {
+	row := q.db.QueryRowContext(ctx, getSharedVolumeByID, id)
+	var i SharedVolume
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Description,
+		&i.TemplateID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getSharedVolumeByOrganizationIDAndName = `-- name: GetSharedVolumeByOrganizationIDAndName :one
+SELECT
+	id, organization_id, name, display_name, description, template_id, created_at, updated_at
+FROM
+	shared_volumes
+WHERE
+	organization_id = $1
+	AND name = $2
+`
+
+type GetSharedVolumeByOrganizationIDAndNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetSharedVolumeByOrganizationIDAndName(ctx context.Context, arg GetSharedVolumeByOrganizationIDAndNameParams) (SharedVolume, error) {
+	row := q.db.QueryRowContext(ctx, getSharedVolumeByOrganizationIDAndName, arg.OrganizationID, arg.Name)
+	var i SharedVolume
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Description,
+		&i.TemplateID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getSharedVolumesByOrganizationID = `-- name: GetSharedVolumesByOrganizationID :many
+SELECT
+	shared_volumes.id, shared_volumes.organization_id, shared_volumes.name, shared_volumes.display_name, shared_volumes.description, shared_volumes.template_id, shared_volumes.created_at, shared_volumes.updated_at,
+	COALESCE(attachments.count, 0)::bigint AS attachment_count,
+	writer.workspace_id AS lock_workspace_id
+FROM
+	shared_volumes
+LEFT JOIN (
+	SELECT
+		shared_volume_id,
+		COUNT(*) AS count
+	FROM
+		shared_volume_attachments
+	WHERE
+		detached_at IS NULL
+	GROUP BY
+		shared_volume_id
+) attachments ON attachments.shared_volume_id = shared_volumes.id
+LEFT JOIN
+	shared_volume_attachments writer
+	ON writer.shared_volume_id = shared_volumes.id
+	AND writer.access_mode = 'read_write'::shared_volume_access_mode
+	AND writer.detached_at IS NULL
+WHERE
+	shared_volumes.organization_id = $1
+ORDER BY
+	shared_volumes.name
+`
+
+type GetSharedVolumesByOrganizationIDRow struct {
+	SharedVolume    SharedVolume  `db:"shared_volume" json:"shared_volume"`
+	AttachmentCount int64         `db:"attachment_count" json:"attachment_count"`
+	LockWorkspaceID uuid.NullUUID `db:"lock_workspace_id" json:"lock_workspace_id"`
+}
+
+// Returns the shared volumes of an organization along with the number of
+// active attachments and the workspace holding the read-write lock, if any.
+func (q *sqlQuerier) GetSharedVolumesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]GetSharedVolumesByOrganizationIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSharedVolumesByOrganizationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSharedVolumesByOrganizationIDRow
+	for rows.Next() {
+		var i GetSharedVolumesByOrganizationIDRow
+		if err := rows.Scan(
+			&i.SharedVolume.ID,
+			&i.SharedVolume.OrganizationID,
+			&i.SharedVolume.Name,
+			&i.SharedVolume.DisplayName,
+			&i.SharedVolume.Description,
+			&i.SharedVolume.TemplateID,
+			&i.SharedVolume.CreatedAt,
+			&i.SharedVolume.UpdatedAt,
+			&i.AttachmentCount,
+			&i.LockWorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertSharedVolume = `-- name: InsertSharedVolume :one
+INSERT INTO
+	shared_volumes (
+		id,
+		organization_id,
+		name,
+		display_name,
+		description,
+		template_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, organization_id, name, display_name, description, template_id, created_at, updated_at
+`
+
+type InsertSharedVolumeParams struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	Name           string        `db:"name" json:"name"`
+	DisplayName    string        `db:"display_name" json:"display_name"`
+	Description    string        `db:"description" json:"description"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertSharedVolume(ctx context.Context, arg InsertSharedVolumeParams) (SharedVolume, error) {
+	row := q.db.QueryRowContext(ctx, insertSharedVolume,
+		arg.ID,
+		arg.OrganizationID,
+		arg.Name,
+		arg.DisplayName,
+		arg.Description,
+		arg.TemplateID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i SharedVolume
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Description,
+		&i.TemplateID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertSharedVolumeAttachment = `-- name: InsertSharedVolumeAttachment :one
+INSERT INTO
+	shared_volume_attachments (
+		id,
+		shared_volume_id,
+		workspace_id,
+		workspace_build_id,
+		workspace_agent_id,
+		mount_path,
+		access_mode,
+		attached_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, shared_volume_id, workspace_id, workspace_build_id, workspace_agent_id, mount_path, access_mode, attached_at, detached_at, detached_by
+`
+
+type InsertSharedVolumeAttachmentParams struct {
+	ID               uuid.UUID              `db:"id" json:"id"`
+	SharedVolumeID   uuid.UUID              `db:"shared_volume_id" json:"shared_volume_id"`
+	WorkspaceID      uuid.UUID              `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID              `db:"workspace_build_id" json:"workspace_build_id"`
+	WorkspaceAgentID uuid.UUID              `db:"workspace_agent_id" json:"workspace_agent_id"`
+	MountPath        string                 `db:"mount_path" json:"mount_path"`
+	AccessMode       SharedVolumeAccessMode `db:"access_mode" json:"access_mode"`
+	AttachedAt       time.Time              `db:"attached_at" json:"attached_at"`
+}
+
+func (q *sqlQuerier) InsertSharedVolumeAttachment(ctx context.Context, arg InsertSharedVolumeAttachmentParams) (SharedVolumeAttachment, error) {
+	row := q.db.QueryRowContext(ctx, insertSharedVolumeAttachment,
+		arg.ID,
+		arg.SharedVolumeID,
+		arg.WorkspaceID,
+		arg.WorkspaceBuildID,
+		arg.WorkspaceAgentID,
+		arg.MountPath,
+		arg.AccessMode,
+		arg.AttachedAt,
+	)
+	var i SharedVolumeAttachment
+	err := row.Scan(
+		&i.ID,
+		&i.SharedVolumeID,
+		&i.WorkspaceID,
+		&i.WorkspaceBuildID,
+		&i.WorkspaceAgentID,
+		&i.MountPath,
+		&i.AccessMode,
+		&i.AttachedAt,
+		&i.DetachedAt,
+		&i.DetachedBy,
+	)
+	return i, err
+}
+
 const deleteRuntimeConfig = `-- name: DeleteRuntimeConfig :exec
 DELETE FROM site_configs
 WHERE site_configs.key = $1
@@ -25195,6 +27393,18 @@ func (q *sqlQuerier) GetPrebuildsSettings(ctx context.Context) (string, error) {
 	return prebuilds_settings, err
 }
 
+const getProvisionerCacheSettings = `-- name: GetProvisionerCacheSettings :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'provisioner_cache_settings'), '{}') :: text AS provisioner_cache_settings
+`
+
+func (q *sqlQuerier) GetProvisionerCacheSettings(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerCacheSettings)
+	var provisioner_cache_settings string
+	err := row.Scan(&provisioner_cache_settings)
+	return provisioner_cache_settings, err
+}
+
 const getRuntimeConfig = `-- name: GetRuntimeConfig :one
 SELECT value FROM site_configs WHERE site_configs.key = $1
 `
@@ -25591,6 +27801,16 @@ func (q *sqlQuerier) UpsertPrebuildsSettings(ctx context.Context, value string)
 	return err
 }
 
+const upsertProvisionerCacheSettings = `-- name: UpsertProvisionerCacheSettings :exec
+INSERT INTO site_configs (key, value) VALUES ('provisioner_cache_settings', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'provisioner_cache_settings'
+`
+
+func (q *sqlQuerier) UpsertProvisionerCacheSettings(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertProvisionerCacheSettings, value)
+	return err
+}
+
 const upsertRuntimeConfig = `-- name: UpsertRuntimeConfig :exec
 INSERT INTO site_configs (key, value) VALUES ($1, $2)
 ON CONFLICT (key) DO UPDATE SET value = $2 WHERE site_configs.key = $1
@@ -26837,6 +29057,347 @@ func (q *sqlQuerier) InsertTelemetryLock(ctx context.Context, arg InsertTelemetr
 	return err
 }
 
+const deleteTemplatePresetCohortsByTemplateID = `-- name: DeleteTemplatePresetCohortsByTemplateID :exec
+DELETE FROM
+	template_preset_cohorts
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) DeleteTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplatePresetCohortsByTemplateID, templateID)
+	return err
+}
+
+const getTemplateHealthReportsByTemplateID = `-- name: GetTemplateHealthReportsByTemplateID :many
+SELECT
+	id, template_id, period_start, period_end, total_builds, failed_builds, average_start_seconds, total_workspaces, outdated_workspaces, dormant_workspaces, created_at
+FROM
+	template_health_reports
+WHERE
+	template_id = $1
+ORDER BY
+	period_end DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($2 :: int, 0)
+`
+
+type GetTemplateHealthReportsByTemplateIDParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	LimitOpt   int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetTemplateHealthReportsByTemplateID(ctx context.Context, arg GetTemplateHealthReportsByTemplateIDParams) ([]TemplateHealthReport, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateHealthReportsByTemplateID, arg.TemplateID, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateHealthReport
+	for rows.Next() {
+		var i TemplateHealthReport
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.TotalBuilds,
+			&i.FailedBuilds,
+			&i.AverageStartSeconds,
+			&i.TotalWorkspaces,
+			&i.OutdatedWorkspaces,
+			&i.DormantWorkspaces,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateHealthStats = `-- name: GetTemplateHealthStats :many
+WITH builds AS (
+	SELECT
+		w.template_id,
+		COUNT(*) AS total_builds,
+		COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds,
+		AVG(EXTRACT(EPOCH FROM (pj.completed_at - pj.created_at)))
+			FILTER (WHERE wb.transition = 'start' AND pj.job_status = 'succeeded') AS average_start_seconds
+	FROM
+		workspace_builds AS wb
+	JOIN
+		workspaces AS w ON wb.workspace_id = w.id
+	JOIN
+		provisioner_jobs AS pj ON wb.job_id = pj.id
+	WHERE
+		wb.created_at >= $1
+		AND pj.completed_at IS NOT NULL
+		AND w.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+	GROUP BY
+		w.template_id
+), fleet AS (
+	SELECT
+		w.template_id,
+		COUNT(*) AS total_workspaces,
+		COUNT(*) FILTER (WHERE wlb.template_version_id != t.active_version_id) AS outdated_workspaces,
+		COUNT(*) FILTER (WHERE w.dormant_at IS NOT NULL) AS dormant_workspaces
+	FROM
+		workspaces AS w
+	JOIN
+		templates AS t ON w.template_id = t.id
+	JOIN
+		workspace_latest_builds AS wlb ON wlb.workspace_id = w.id
+	WHERE
+		w.deleted = false
+		AND w.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+	GROUP BY
+		w.template_id
+)
+SELECT
+	t.id AS template_id,
+	t.name AS template_name,
+	t.display_name AS template_display_name,
+	t.organization_id AS template_organization_id,
+	COALESCE(builds.total_builds, 0)::bigint AS total_builds,
+	COALESCE(builds.failed_builds, 0)::bigint AS failed_builds,
+	COALESCE(builds.average_start_seconds, 0)::double precision AS average_start_seconds,
+	COALESCE(fleet.total_workspaces, 0)::bigint AS total_workspaces,
+	COALESCE(fleet.outdated_workspaces, 0)::bigint AS outdated_workspaces,
+	COALESCE(fleet.dormant_workspaces, 0)::bigint AS dormant_workspaces
+FROM
+	templates AS t
+LEFT JOIN
+	builds ON builds.template_id = t.id
+LEFT JOIN
+	fleet ON fleet.template_id = t.id
+WHERE
+	t.deleted = false
+	AND (builds.template_id IS NOT NULL OR fleet.template_id IS NOT NULL)
+ORDER BY
+	t.name ASC, t.id ASC
+`
+
+type GetTemplateHealthStatsRow struct {
+	TemplateID             uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName           string    `db:"template_name" json:"template_name"`
+	TemplateDisplayName    string    `db:"template_display_name" json:"template_display_name"`
+	TemplateOrganizationID uuid.UUID `db:"template_organization_id" json:"template_organization_id"`
+	TotalBuilds            int64     `db:"total_builds" json:"total_builds"`
+	FailedBuilds           int64     `db:"failed_builds" json:"failed_builds"`
+	AverageStartSeconds    float64   `db:"average_start_seconds" json:"average_start_seconds"`
+	TotalWorkspaces        int64     `db:"total_workspaces" json:"total_workspaces"`
+	OutdatedWorkspaces     int64     `db:"outdated_workspaces" json:"outdated_workspaces"`
+	DormantWorkspaces      int64     `db:"dormant_workspaces" json:"dormant_workspaces"`
+}
+
+// Returns the health of the templates that had builds since @since or that
+// have workspaces. Builds are counted since @since, while workspaces are
+// counted as they are now. Prebuilt workspaces are left out, as they are
+// managed by the reconciler rather than by users.
+func (q *sqlQuerier) GetTemplateHealthStats(ctx context.Context, since time.Time) ([]GetTemplateHealthStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateHealthStats, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateHealthStatsRow
+	for rows.Next() {
+		var i GetTemplateHealthStatsRow
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.TemplateDisplayName,
+			&i.TemplateOrganizationID,
+			&i.TotalBuilds,
+			&i.FailedBuilds,
+			&i.AverageStartSeconds,
+			&i.TotalWorkspaces,
+			&i.OutdatedWorkspaces,
+			&i.DormantWorkspaces,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateHealthReport = `-- name: InsertTemplateHealthReport :one
+INSERT INTO
+	template_health_reports (
+		id,
+		template_id,
+		period_start,
+		period_end,
+		total_builds,
+		failed_builds,
+		average_start_seconds,
+		total_workspaces,
+		outdated_workspaces,
+		dormant_workspaces,
+		created_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8,
+	$9,
+	$10,
+	$11
+)
+RETURNING id, template_id, period_start, period_end, total_builds, failed_builds, average_start_seconds, total_workspaces, outdated_workspaces, dormant_workspaces, created_at
+`
+
+type InsertTemplateHealthReportParams struct {
+	ID                  uuid.UUID `db:"id" json:"id"`
+	TemplateID          uuid.UUID `db:"template_id" json:"template_id"`
+	PeriodStart         time.Time `db:"period_start" json:"period_start"`
+	PeriodEnd           time.Time `db:"period_end" json:"period_end"`
+	TotalBuilds         int64     `db:"total_builds" json:"total_builds"`
+	FailedBuilds        int64     `db:"failed_builds" json:"failed_builds"`
+	AverageStartSeconds float64   `db:"average_start_seconds" json:"average_start_seconds"`
+	TotalWorkspaces     int64     `db:"total_workspaces" json:"total_workspaces"`
+	OutdatedWorkspaces  int64     `db:"outdated_workspaces" json:"outdated_workspaces"`
+	DormantWorkspaces   int64     `db:"dormant_workspaces" json:"dormant_workspaces"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertTemplateHealthReport(ctx context.Context, arg InsertTemplateHealthReportParams) (TemplateHealthReport, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateHealthReport,
+		arg.ID,
+		arg.TemplateID,
+		arg.PeriodStart,
+		arg.PeriodEnd,
+		arg.TotalBuilds,
+		arg.FailedBuilds,
+		arg.AverageStartSeconds,
+		arg.TotalWorkspaces,
+		arg.OutdatedWorkspaces,
+		arg.DormantWorkspaces,
+		arg.CreatedAt,
+	)
+	var i TemplateHealthReport
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.TotalBuilds,
+		&i.FailedBuilds,
+		&i.AverageStartSeconds,
+		&i.TotalWorkspaces,
+		&i.OutdatedWorkspaces,
+		&i.DormantWorkspaces,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTemplatePresetCohortsByTemplateID = `-- name: GetTemplatePresetCohortsByTemplateID :many
+SELECT
+	template_id, preset_name, group_ids, roles, created_at
+FROM
+	template_preset_cohorts
+WHERE
+	template_id = $1
+ORDER BY
+	preset_name ASC
+`
+
+func (q *sqlQuerier) GetTemplatePresetCohortsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplatePresetCohort, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplatePresetCohortsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplatePresetCohort
+	for rows.Next() {
+		var i TemplatePresetCohort
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.PresetName,
+			pq.Array(&i.GroupIds),
+			pq.Array(&i.Roles),
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplatePresetCohort = `-- name: InsertTemplatePresetCohort :one
+INSERT INTO
+	template_preset_cohorts (
+		template_id,
+		preset_name,
+		group_ids,
+		roles,
+		created_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5
+)
+RETURNING template_id, preset_name, group_ids, roles, created_at
+`
+
+type InsertTemplatePresetCohortParams struct {
+	TemplateID uuid.UUID   `db:"template_id" json:"template_id"`
+	PresetName string      `db:"preset_name" json:"preset_name"`
+	GroupIds   []uuid.UUID `db:"group_ids" json:"group_ids"`
+	Roles      []string    `db:"roles" json:"roles"`
+	CreatedAt  time.Time   `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertTemplatePresetCohort(ctx context.Context, arg InsertTemplatePresetCohortParams) (TemplatePresetCohort, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplatePresetCohort,
+		arg.TemplateID,
+		arg.PresetName,
+		pq.Array(arg.GroupIds),
+		pq.Array(arg.Roles),
+		arg.CreatedAt,
+	)
+	var i TemplatePresetCohort
+	err := row.Scan(
+		&i.TemplateID,
+		&i.PresetName,
+		pq.Array(&i.GroupIds),
+		pq.Array(&i.Roles),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
 WITH build_times AS (
 SELECT
@@ -26894,7 +29455,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, templateID
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, time_til_autostop_notify, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, time_til_autostop_notify, provisioner_plan_timeout, provisioner_apply_timeout, trial_workspace_ttl, requeue_reaped_builds, agent_rollout_channel, allow_targeted_builds, reconfirm_parameters, deprecation_cutoff, nightly_stop_time, build_log_retention, max_lifetime, max_lifetime_action, idle_reclaim_ttl, idle_reclaim_resource_selector, activity_bump_connection_types, activity_bump_max_per_day, pre_build_hook_url, post_build_hook_url, auto_assign_region, notify_build_failures, disable_provider_cache, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names
 WHERE
@@ -26939,6 +29500,27 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.CorsBehavior,
 		&i.DisableModuleCache,
 		&i.TimeTilAutostopNotify,
+		&i.ProvisionerPlanTimeout,
+		&i.ProvisionerApplyTimeout,
+		&i.TrialWorkspaceTTL,
+		&i.RequeueReapedBuilds,
+		&i.AgentRolloutChannel,
+		&i.AllowTargetedBuilds,
+		pq.Array(&i.ReconfirmParameters),
+		&i.DeprecationCutoff,
+		&i.NightlyStopTime,
+		&i.BuildLogRetention,
+		&i.MaxLifetime,
+		&i.MaxLifetimeAction,
+		&i.IdleReclaimTTL,
+		&i.IdleReclaimResourceSelector,
+		pq.Array(&i.ActivityBumpConnectionTypes),
+		&i.ActivityBumpMaxPerDay,
+		&i.PreBuildHookURL,
+		&i.PostBuildHookURL,
+		&i.AutoAssignRegion,
+		&i.NotifyBuildFailures,
+		&i.DisableProviderCache,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
@@ -26951,7 +29533,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, time_til_autostop_notify, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, time_til_autostop_notify, provisioner_plan_timeout, provisioner_apply_timeout, trial_workspace_ttl, requeue_reaped_builds, agent_rollout_channel, allow_targeted_builds, reconfirm_parameters, deprecation_cutoff, nightly_stop_time, build_log_retention, max_lifetime, max_lifetime_action, idle_reclaim_ttl, idle_reclaim_resource_selector, activity_bump_connection_types, activity_bump_max_per_day, pre_build_hook_url, post_build_hook_url, auto_assign_region, notify_build_failures, disable_provider_cache, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names AS templates
 WHERE
@@ -27004,6 +29586,27 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.CorsBehavior,
 		&i.DisableModuleCache,
 		&i.TimeTilAutostopNotify,
+		&i.ProvisionerPlanTimeout,
+		&i.ProvisionerApplyTimeout,
+		&i.TrialWorkspaceTTL,
+		&i.RequeueReapedBuilds,
+		&i.AgentRolloutChannel,
+		&i.AllowTargetedBuilds,
+		pq.Array(&i.ReconfirmParameters),
+		&i.DeprecationCutoff,
+		&i.NightlyStopTime,
+		&i.BuildLogRetention,
+		&i.MaxLifetime,
+		&i.MaxLifetimeAction,
+		&i.IdleReclaimTTL,
+		&i.IdleReclaimResourceSelector,
+		pq.Array(&i.ActivityBumpConnectionTypes),
+		&i.ActivityBumpMaxPerDay,
+		&i.PreBuildHookURL,
+		&i.PostBuildHookURL,
+		&i.AutoAssignRegion,
+		&i.NotifyBuildFailures,
+		&i.DisableProviderCache,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
@@ -27015,7 +29618,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, time_til_autostop_notify, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, time_til_autostop_notify, provisioner_plan_timeout, provisioner_apply_timeout, trial_workspace_ttl, requeue_reaped_builds, agent_rollout_channel, allow_targeted_builds, reconfirm_parameters, deprecation_cutoff, nightly_stop_time, build_log_retention, max_lifetime, max_lifetime_action, idle_reclaim_ttl, idle_reclaim_resource_selector, activity_bump_connection_types, activity_bump_max_per_day, pre_build_hook_url, post_build_hook_url, auto_assign_region, notify_build_failures, disable_provider_cache, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -27061,6 +29664,27 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.CorsBehavior,
 			&i.DisableModuleCache,
 			&i.TimeTilAutostopNotify,
+			&i.ProvisionerPlanTimeout,
+			&i.ProvisionerApplyTimeout,
+			&i.TrialWorkspaceTTL,
+			&i.RequeueReapedBuilds,
+			&i.AgentRolloutChannel,
+			&i.AllowTargetedBuilds,
+			pq.Array(&i.ReconfirmParameters),
+			&i.DeprecationCutoff,
+			&i.NightlyStopTime,
+			&i.BuildLogRetention,
+			&i.MaxLifetime,
+			&i.MaxLifetimeAction,
+			&i.IdleReclaimTTL,
+			&i.IdleReclaimResourceSelector,
+			pq.Array(&i.ActivityBumpConnectionTypes),
+			&i.ActivityBumpMaxPerDay,
+			&i.PreBuildHookURL,
+			&i.PostBuildHookURL,
+			&i.AutoAssignRegion,
+			&i.NotifyBuildFailures,
+			&i.DisableProviderCache,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
@@ -27083,7 +29707,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	t.id, t.created_at, t.updated_at, t.organization_id, t.deleted, t.name, t.provisioner, t.active_version_id, t.description, t.default_ttl, t.created_by, t.icon, t.user_acl, t.group_acl, t.display_name, t.allow_user_cancel_workspace_jobs, t.allow_user_autostart, t.allow_user_autostop, t.failure_ttl, t.time_til_dormant, t.time_til_dormant_autodelete, t.autostop_requirement_days_of_week, t.autostop_requirement_weeks, t.autostart_block_days_of_week, t.require_active_version, t.deprecated, t.activity_bump, t.max_port_sharing_level, t.use_classic_parameter_flow, t.cors_behavior, t.disable_module_cache, t.time_til_autostop_notify, t.created_by_avatar_url, t.created_by_username, t.created_by_name, t.organization_name, t.organization_display_name, t.organization_icon
+	t.id, t.created_at, t.updated_at, t.organization_id, t.deleted, t.name, t.provisioner, t.active_version_id, t.description, t.default_ttl, t.created_by, t.icon, t.user_acl, t.group_acl, t.display_name, t.allow_user_cancel_workspace_jobs, t.allow_user_autostart, t.allow_user_autostop, t.failure_ttl, t.time_til_dormant, t.time_til_dormant_autodelete, t.autostop_requirement_days_of_week, t.autostop_requirement_weeks, t.autostart_block_days_of_week, t.require_active_version, t.deprecated, t.activity_bump, t.max_port_sharing_level, t.use_classic_parameter_flow, t.cors_behavior, t.disable_module_cache, t.time_til_autostop_notify, t.provisioner_plan_timeout, t.provisioner_apply_timeout, t.trial_workspace_ttl, t.requeue_reaped_builds, t.agent_rollout_channel, t.allow_targeted_builds, t.reconfirm_parameters, t.deprecation_cutoff, t.nightly_stop_time, t.build_log_retention, t.max_lifetime, t.max_lifetime_action, t.idle_reclaim_ttl, t.idle_reclaim_resource_selector, t.activity_bump_connection_types, t.activity_bump_max_per_day, t.pre_build_hook_url, t.post_build_hook_url, t.auto_assign_region, t.notify_build_failures, t.disable_provider_cache, t.created_by_avatar_url, t.created_by_username, t.created_by_name, t.organization_name, t.organization_display_name, t.organization_icon
 FROM
 	template_with_names AS t
 LEFT JOIN
@@ -27244,6 +29868,27 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.CorsBehavior,
 			&i.DisableModuleCache,
 			&i.TimeTilAutostopNotify,
+			&i.ProvisionerPlanTimeout,
+			&i.ProvisionerApplyTimeout,
+			&i.TrialWorkspaceTTL,
+			&i.RequeueReapedBuilds,
+			&i.AgentRolloutChannel,
+			&i.AllowTargetedBuilds,
+			pq.Array(&i.ReconfirmParameters),
+			&i.DeprecationCutoff,
+			&i.NightlyStopTime,
+			&i.BuildLogRetention,
+			&i.MaxLifetime,
+			&i.MaxLifetimeAction,
+			&i.IdleReclaimTTL,
+			&i.IdleReclaimResourceSelector,
+			pq.Array(&i.ActivityBumpConnectionTypes),
+			&i.ActivityBumpMaxPerDay,
+			&i.PreBuildHookURL,
+			&i.PostBuildHookURL,
+			&i.AutoAssignRegion,
+			&i.NotifyBuildFailures,
+			&i.DisableProviderCache,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
@@ -27283,10 +29928,13 @@ INSERT INTO
 		allow_user_cancel_workspace_jobs,
 		max_port_sharing_level,
 		use_classic_parameter_flow,
-		cors_behavior
+		cors_behavior,
+		provisioner_plan_timeout,
+		provisioner_apply_timeout,
+		trial_workspace_ttl
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 `
 
 type InsertTemplateParams struct {
@@ -27307,6 +29955,9 @@ type InsertTemplateParams struct {
 	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
 	UseClassicParameterFlow      bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
 	CorsBehavior                 CorsBehavior    `db:"cors_behavior" json:"cors_behavior"`
+	ProvisionerPlanTimeout       int64           `db:"provisioner_plan_timeout" json:"provisioner_plan_timeout"`
+	ProvisionerApplyTimeout      int64           `db:"provisioner_apply_timeout" json:"provisioner_apply_timeout"`
+	TrialWorkspaceTTL            int64           `db:"trial_workspace_ttl" json:"trial_workspace_ttl"`
 }
 
 func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) error {
@@ -27328,6 +29979,9 @@ func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParam
 		arg.MaxPortSharingLevel,
 		arg.UseClassicParameterFlow,
 		arg.CorsBehavior,
+		arg.ProvisionerPlanTimeout,
+		arg.ProvisionerApplyTimeout,
+		arg.TrialWorkspaceTTL,
 	)
 	return err
 }
@@ -27358,19 +30012,26 @@ UPDATE
 	templates
 SET
 	require_active_version = $2,
-	deprecated = $3
+	deprecated = $3,
+	deprecation_cutoff = $4
 WHERE
 	id = $1
 `
 
 type UpdateTemplateAccessControlByIDParams struct {
-	ID                   uuid.UUID `db:"id" json:"id"`
-	RequireActiveVersion bool      `db:"require_active_version" json:"require_active_version"`
-	Deprecated           string    `db:"deprecated" json:"deprecated"`
+	ID                   uuid.UUID    `db:"id" json:"id"`
+	RequireActiveVersion bool         `db:"require_active_version" json:"require_active_version"`
+	Deprecated           string       `db:"deprecated" json:"deprecated"`
+	DeprecationCutoff    sql.NullTime `db:"deprecation_cutoff" json:"deprecation_cutoff"`
 }
 
 func (q *sqlQuerier) UpdateTemplateAccessControlByID(ctx context.Context, arg UpdateTemplateAccessControlByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateAccessControlByID, arg.ID, arg.RequireActiveVersion, arg.Deprecated)
+	_, err := q.db.ExecContext(ctx, updateTemplateAccessControlByID,
+		arg.ID,
+		arg.RequireActiveVersion,
+		arg.Deprecated,
+		arg.DeprecationCutoff,
+	)
 	return err
 }
 
@@ -27430,24 +30091,50 @@ SET
 	max_port_sharing_level = $9,
 	use_classic_parameter_flow = $10,
 	cors_behavior = $11,
-	disable_module_cache = $12
+	disable_module_cache = $12,
+	provisioner_plan_timeout = $13,
+	provisioner_apply_timeout = $14,
+	trial_workspace_ttl = $15,
+	requeue_reaped_builds = $16,
+	agent_rollout_channel = $17,
+	allow_targeted_builds = $18,
+	reconfirm_parameters = $19,
+	build_log_retention = $20,
+	pre_build_hook_url = $21,
+	post_build_hook_url = $22,
+	auto_assign_region = $23,
+	notify_build_failures = $24,
+	disable_provider_cache = $25
 WHERE
 	id = $1
 `
 
 type UpdateTemplateMetaByIDParams struct {
-	ID                           uuid.UUID       `db:"id" json:"id"`
-	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
-	Description                  string          `db:"description" json:"description"`
-	Name                         string          `db:"name" json:"name"`
-	Icon                         string          `db:"icon" json:"icon"`
-	DisplayName                  string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
-	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
-	UseClassicParameterFlow      bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
-	CorsBehavior                 CorsBehavior    `db:"cors_behavior" json:"cors_behavior"`
-	DisableModuleCache           bool            `db:"disable_module_cache" json:"disable_module_cache"`
+	ID                           uuid.UUID           `db:"id" json:"id"`
+	UpdatedAt                    time.Time           `db:"updated_at" json:"updated_at"`
+	Description                  string              `db:"description" json:"description"`
+	Name                         string              `db:"name" json:"name"`
+	Icon                         string              `db:"icon" json:"icon"`
+	DisplayName                  string              `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool                `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	GroupACL                     TemplateACL         `db:"group_acl" json:"group_acl"`
+	MaxPortSharingLevel          AppSharingLevel     `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	UseClassicParameterFlow      bool                `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	CorsBehavior                 CorsBehavior        `db:"cors_behavior" json:"cors_behavior"`
+	DisableModuleCache           bool                `db:"disable_module_cache" json:"disable_module_cache"`
+	ProvisionerPlanTimeout       int64               `db:"provisioner_plan_timeout" json:"provisioner_plan_timeout"`
+	ProvisionerApplyTimeout      int64               `db:"provisioner_apply_timeout" json:"provisioner_apply_timeout"`
+	TrialWorkspaceTTL            int64               `db:"trial_workspace_ttl" json:"trial_workspace_ttl"`
+	RequeueReapedBuilds          bool                `db:"requeue_reaped_builds" json:"requeue_reaped_builds"`
+	AgentRolloutChannel          AgentRolloutChannel `db:"agent_rollout_channel" json:"agent_rollout_channel"`
+	AllowTargetedBuilds          bool                `db:"allow_targeted_builds" json:"allow_targeted_builds"`
+	ReconfirmParameters          []string            `db:"reconfirm_parameters" json:"reconfirm_parameters"`
+	BuildLogRetention            int64               `db:"build_log_retention" json:"build_log_retention"`
+	PreBuildHookURL              string              `db:"pre_build_hook_url" json:"pre_build_hook_url"`
+	PostBuildHookURL             string              `db:"post_build_hook_url" json:"post_build_hook_url"`
+	AutoAssignRegion             bool                `db:"auto_assign_region" json:"auto_assign_region"`
+	NotifyBuildFailures          bool                `db:"notify_build_failures" json:"notify_build_failures"`
+	DisableProviderCache         bool                `db:"disable_provider_cache" json:"disable_provider_cache"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -27464,6 +30151,19 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.UseClassicParameterFlow,
 		arg.CorsBehavior,
 		arg.DisableModuleCache,
+		arg.ProvisionerPlanTimeout,
+		arg.ProvisionerApplyTimeout,
+		arg.TrialWorkspaceTTL,
+		arg.RequeueReapedBuilds,
+		arg.AgentRolloutChannel,
+		arg.AllowTargetedBuilds,
+		pq.Array(arg.ReconfirmParameters),
+		arg.BuildLogRetention,
+		arg.PreBuildHookURL,
+		arg.PostBuildHookURL,
+		arg.AutoAssignRegion,
+		arg.NotifyBuildFailures,
+		arg.DisableProviderCache,
 	)
 	return err
 }
@@ -27483,25 +30183,39 @@ SET
 	failure_ttl = $10,
 	time_til_dormant = $11,
 	time_til_dormant_autodelete = $12,
-	time_til_autostop_notify = $13
+	time_til_autostop_notify = $13,
+	nightly_stop_time = $14,
+	max_lifetime = $15,
+	max_lifetime_action = $16,
+	idle_reclaim_ttl = $17,
+	idle_reclaim_resource_selector = $18,
+	activity_bump_connection_types = $19,
+	activity_bump_max_per_day = $20
 WHERE
 	id = $1
 `
 
 type UpdateTemplateScheduleByIDParams struct {
-	ID                            uuid.UUID `db:"id" json:"id"`
-	UpdatedAt                     time.Time `db:"updated_at" json:"updated_at"`
-	AllowUserAutostart            bool      `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop             bool      `db:"allow_user_autostop" json:"allow_user_autostop"`
-	DefaultTTL                    int64     `db:"default_ttl" json:"default_ttl"`
-	ActivityBump                  int64     `db:"activity_bump" json:"activity_bump"`
-	AutostopRequirementDaysOfWeek int16     `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
-	AutostopRequirementWeeks      int64     `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
-	AutostartBlockDaysOfWeek      int16     `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
-	FailureTTL                    int64     `db:"failure_ttl" json:"failure_ttl"`
-	TimeTilDormant                int64     `db:"time_til_dormant" json:"time_til_dormant"`
-	TimeTilDormantAutoDelete      int64     `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
-	TimeTilAutostopNotify         int64     `db:"time_til_autostop_notify" json:"time_til_autostop_notify"`
+	ID                            uuid.UUID         `db:"id" json:"id"`
+	UpdatedAt                     time.Time         `db:"updated_at" json:"updated_at"`
+	AllowUserAutostart            bool              `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop             bool              `db:"allow_user_autostop" json:"allow_user_autostop"`
+	DefaultTTL                    int64             `db:"default_ttl" json:"default_ttl"`
+	ActivityBump                  int64             `db:"activity_bump" json:"activity_bump"`
+	AutostopRequirementDaysOfWeek int16             `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
+	AutostopRequirementWeeks      int64             `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	AutostartBlockDaysOfWeek      int16             `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
+	FailureTTL                    int64             `db:"failure_ttl" json:"failure_ttl"`
+	TimeTilDormant                int64             `db:"time_til_dormant" json:"time_til_dormant"`
+	TimeTilDormantAutoDelete      int64             `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
+	TimeTilAutostopNotify         int64             `db:"time_til_autostop_notify" json:"time_til_autostop_notify"`
+	NightlyStopTime               string            `db:"nightly_stop_time" json:"nightly_stop_time"`
+	MaxLifetime                   int64             `db:"max_lifetime" json:"max_lifetime"`
+	MaxLifetimeAction             MaxLifetimeAction `db:"max_lifetime_action" json:"max_lifetime_action"`
+	IdleReclaimTTL                int64             `db:"idle_reclaim_ttl" json:"idle_reclaim_ttl"`
+	IdleReclaimResourceSelector   string            `db:"idle_reclaim_resource_selector" json:"idle_reclaim_resource_selector"`
+	ActivityBumpConnectionTypes   []string          `db:"activity_bump_connection_types" json:"activity_bump_connection_types"`
+	ActivityBumpMaxPerDay         int64             `db:"activity_bump_max_per_day" json:"activity_bump_max_per_day"`
 }
 
 func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error {
@@ -27519,42 +30233,119 @@ func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateT
 		arg.TimeTilDormant,
 		arg.TimeTilDormantAutoDelete,
 		arg.TimeTilAutostopNotify,
+		arg.NightlyStopTime,
+		arg.MaxLifetime,
+		arg.MaxLifetimeAction,
+		arg.IdleReclaimTTL,
+		arg.IdleReclaimResourceSelector,
+		pq.Array(arg.ActivityBumpConnectionTypes),
+		arg.ActivityBumpMaxPerDay,
 	)
 	return err
 }
 
-const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
-SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, form_type FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+const deleteTemplateSecretByTemplateIDAndName = `-- name: DeleteTemplateSecretByTemplateIDAndName :one
+DELETE FROM template_secrets
+WHERE template_id = $1 AND name = $2
+RETURNING id, template_id, name, description, value, value_key_id, created_at, updated_at
 `
 
-func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
+type DeleteTemplateSecretByTemplateIDAndNameParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Name       string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) DeleteTemplateSecretByTemplateIDAndName(ctx context.Context, arg DeleteTemplateSecretByTemplateIDAndNameParams) (TemplateSecret, error) {
+	row := q.db.QueryRowContext(ctx, deleteTemplateSecretByTemplateIDAndName, arg.TemplateID, arg.Name)
+	var i TemplateSecret
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateSecretByID = `-- name: GetTemplateSecretByID :one
+SELECT id, template_id, name, description, value, value_key_id, created_at, updated_at
+FROM template_secrets
+WHERE id = $1
+`
+
+func (q *sqlQuerier) GetTemplateSecretByID(ctx context.Context, id uuid.UUID) (TemplateSecret, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateSecretByID, id)
+	var i TemplateSecret
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateSecretByTemplateIDAndName = `-- name: GetTemplateSecretByTemplateIDAndName :one
+SELECT id, template_id, name, description, value, value_key_id, created_at, updated_at
+FROM template_secrets
+WHERE template_id = $1 AND name = $2
+`
+
+type GetTemplateSecretByTemplateIDAndNameParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Name       string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg GetTemplateSecretByTemplateIDAndNameParams) (TemplateSecret, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateSecretByTemplateIDAndName, arg.TemplateID, arg.Name)
+	var i TemplateSecret
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateSecrets = `-- name: GetTemplateSecrets :many
+SELECT id, template_id, name, description, value, value_key_id, created_at, updated_at
+FROM template_secrets
+ORDER BY template_id, name ASC
+`
+
+// Returns the secrets of all templates. Used by the dbcrypt key rotation
+// utility.
+func (q *sqlQuerier) GetTemplateSecrets(ctx context.Context) ([]TemplateSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateSecrets)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersionParameter
+	var items []TemplateSecret
 	for rows.Next() {
-		var i TemplateVersionParameter
+		var i TemplateSecret
 		if err := rows.Scan(
-			&i.TemplateVersionID,
+			&i.ID,
+			&i.TemplateID,
 			&i.Name,
 			&i.Description,
-			&i.Type,
-			&i.Mutable,
-			&i.DefaultValue,
-			&i.Icon,
-			&i.Options,
-			&i.ValidationRegex,
-			&i.ValidationMin,
-			&i.ValidationMax,
-			&i.ValidationError,
-			&i.ValidationMonotonic,
-			&i.Required,
-			&i.DisplayName,
-			&i.DisplayOrder,
-			&i.Ephemeral,
-			&i.FormType,
+			&i.Value,
+			&i.ValueKeyID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -27569,428 +30360,362 @@ func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateV
 	return items, nil
 }
 
-const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
-INSERT INTO
-    template_version_parameters (
-        template_version_id,
-        name,
-        description,
-        type,
-        form_type,
-        mutable,
-        default_value,
-        icon,
-        options,
-        validation_regex,
-        validation_min,
-        validation_max,
-        validation_error,
-        validation_monotonic,
-        required,
-        display_name,
-        display_order,
-        ephemeral
-    )
-VALUES
-    (
-        $1,
-        $2,
-        $3,
-        $4,
-        $5,
-        $6,
-        $7,
-        $8,
-        $9,
-        $10,
-        $11,
-        $12,
-        $13,
-        $14,
-        $15,
-        $16,
-        $17,
-        $18
-    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, form_type
+const getTemplateSecretsByTemplateID = `-- name: GetTemplateSecretsByTemplateID :many
+SELECT id, template_id, name, description, value, value_key_id, created_at, updated_at
+FROM template_secrets
+WHERE template_id = $1
+ORDER BY name ASC
 `
 
-type InsertTemplateVersionParameterParams struct {
-	TemplateVersionID   uuid.UUID         `db:"template_version_id" json:"template_version_id"`
-	Name                string            `db:"name" json:"name"`
-	Description         string            `db:"description" json:"description"`
-	Type                string            `db:"type" json:"type"`
-	FormType            ParameterFormType `db:"form_type" json:"form_type"`
-	Mutable             bool              `db:"mutable" json:"mutable"`
-	DefaultValue        string            `db:"default_value" json:"default_value"`
-	Icon                string            `db:"icon" json:"icon"`
-	Options             json.RawMessage   `db:"options" json:"options"`
-	ValidationRegex     string            `db:"validation_regex" json:"validation_regex"`
-	ValidationMin       sql.NullInt32     `db:"validation_min" json:"validation_min"`
-	ValidationMax       sql.NullInt32     `db:"validation_max" json:"validation_max"`
-	ValidationError     string            `db:"validation_error" json:"validation_error"`
-	ValidationMonotonic string            `db:"validation_monotonic" json:"validation_monotonic"`
-	Required            bool              `db:"required" json:"required"`
-	DisplayName         string            `db:"display_name" json:"display_name"`
-	DisplayOrder        int32             `db:"display_order" json:"display_order"`
-	Ephemeral           bool              `db:"ephemeral" json:"ephemeral"`
+// Returns all columns including the secret value. The API only returns
+// metadata, and the agent fetches the values referenced by its scripts.
+func (q *sqlQuerier) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateSecretsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateSecret
+	for rows.Next() {
+		var i TemplateSecret
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.Name,
+			&i.Description,
+			&i.Value,
+			&i.ValueKeyID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
-		arg.TemplateVersionID,
+const insertTemplateSecret = `-- name: InsertTemplateSecret :one
+INSERT INTO template_secrets (
+    id,
+    template_id,
+    name,
+    description,
+    value,
+    value_key_id,
+    created_at,
+    updated_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $7
+) RETURNING id, template_id, name, description, value, value_key_id, created_at, updated_at
+`
+
+type InsertTemplateSecretParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	TemplateID  uuid.UUID      `db:"template_id" json:"template_id"`
+	Name        string         `db:"name" json:"name"`
+	Description string         `db:"description" json:"description"`
+	Value       string         `db:"value" json:"value"`
+	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertTemplateSecret(ctx context.Context, arg InsertTemplateSecretParams) (TemplateSecret, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateSecret,
+		arg.ID,
+		arg.TemplateID,
 		arg.Name,
 		arg.Description,
-		arg.Type,
-		arg.FormType,
-		arg.Mutable,
-		arg.DefaultValue,
-		arg.Icon,
-		arg.Options,
-		arg.ValidationRegex,
-		arg.ValidationMin,
-		arg.ValidationMax,
-		arg.ValidationError,
-		arg.ValidationMonotonic,
-		arg.Required,
-		arg.DisplayName,
-		arg.DisplayOrder,
-		arg.Ephemeral,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.CreatedAt,
 	)
-	var i TemplateVersionParameter
+	var i TemplateSecret
 	err := row.Scan(
-		&i.TemplateVersionID,
+		&i.ID,
+		&i.TemplateID,
 		&i.Name,
 		&i.Description,
-		&i.Type,
-		&i.Mutable,
-		&i.DefaultValue,
-		&i.Icon,
-		&i.Options,
-		&i.ValidationRegex,
-		&i.ValidationMin,
-		&i.ValidationMax,
-		&i.ValidationError,
-		&i.ValidationMonotonic,
-		&i.Required,
-		&i.DisplayName,
-		&i.DisplayOrder,
-		&i.Ephemeral,
-		&i.FormType,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const archiveUnusedTemplateVersions = `-- name: ArchiveUnusedTemplateVersions :many
-UPDATE
-	template_versions
+const updateEncryptedTemplateSecret = `-- name: UpdateEncryptedTemplateSecret :one
+UPDATE template_secrets
 SET
-	archived = true,
-	updated_at = $1
-FROM
-	-- Archive all versions that are returned from this query.
-	(
-		SELECT
-			scoped_template_versions.id
-		FROM
-			-- Scope an archive to a single template and ignore already archived template versions
-			(
-				SELECT
-					id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent
-				FROM
-					template_versions
-				WHERE
-					template_versions.template_id = $2 :: uuid
-					AND
-					archived = false
-					AND
-					-- This allows archiving a specific template version.
-					CASE
-						WHEN $3::uuid  != '00000000-0000-0000-0000-000000000000'::uuid THEN
-							template_versions.id = $3 :: uuid
-						ELSE
-							true
-						END
-			) AS scoped_template_versions
-			LEFT JOIN
-				provisioner_jobs ON scoped_template_versions.job_id = provisioner_jobs.id
-			LEFT JOIN
-				templates ON scoped_template_versions.template_id = templates.id
-		WHERE
-		  -- Actively used template versions (meaning the latest build is using
-		  -- the version) are never archived. A "restart" command on the workspace,
-		  -- even if failed, would use the version. So it cannot be archived until
-		  -- the build is outdated.
-		NOT EXISTS (
-			-- Return all "used" versions, where "used" is defined as being
-			-- used by a latest workspace build.
-			SELECT template_version_id FROM (
-				SELECT
-					DISTINCT ON (workspace_id) template_version_id, transition
-				FROM
-					workspace_builds
-				ORDER BY workspace_id, build_number DESC
-				) AS used_versions
-			WHERE
-				used_versions.transition != 'delete'
-				AND
-				scoped_template_versions.id = used_versions.template_version_id
-		)
-		-- Also never archive the active template version
-		AND active_version_id != scoped_template_versions.id
-		AND CASE
-			-- Optionally, only archive versions that match a given
-			-- job status like 'failed'.
-			WHEN $4 :: provisioner_job_status IS NOT NULL THEN
-				provisioner_jobs.job_status = $4 :: provisioner_job_status
-			ELSE
-				true
-		END
-		-- Pending or running jobs should not be archived, as they are "in progress"
-		AND provisioner_jobs.job_status != 'running'
-		AND provisioner_jobs.job_status != 'pending'
-	) AS archived_versions
-WHERE
-	template_versions.id IN (archived_versions.id)
-RETURNING template_versions.id
+    value = $1,
+    value_key_id = $2::text
+WHERE id = $3
+RETURNING id, template_id, name, description, value, value_key_id, created_at, updated_at
 `
 
-type ArchiveUnusedTemplateVersionsParams struct {
-	UpdatedAt         time.Time                `db:"updated_at" json:"updated_at"`
-	TemplateID        uuid.UUID                `db:"template_id" json:"template_id"`
-	TemplateVersionID uuid.UUID                `db:"template_version_id" json:"template_version_id"`
-	JobStatus         NullProvisionerJobStatus `db:"job_status" json:"job_status"`
+type UpdateEncryptedTemplateSecretParams struct {
+	Value      string         `db:"value" json:"value"`
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+	ID         uuid.UUID      `db:"id" json:"id"`
 }
 
-// Archiving templates is a soft delete action, so is reversible.
-// Archiving prevents the version from being used and discovered
-// by listing.
-// Only unused template versions will be archived, which are any versions not
-// referenced by the latest build of a workspace.
-func (q *sqlQuerier) ArchiveUnusedTemplateVersions(ctx context.Context, arg ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
-	rows, err := q.db.QueryContext(ctx, archiveUnusedTemplateVersions,
-		arg.UpdatedAt,
-		arg.TemplateID,
-		arg.TemplateVersionID,
-		arg.JobStatus,
+// Updates only the encrypted columns of a secret. Used by the dbcrypt key
+// rotation utility to re-encrypt or decrypt rows in place.
+func (q *sqlQuerier) UpdateEncryptedTemplateSecret(ctx context.Context, arg UpdateEncryptedTemplateSecretParams) (TemplateSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateEncryptedTemplateSecret, arg.Value, arg.ValueKeyID, arg.ID)
+	var i TemplateSecret
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []uuid.UUID
-	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
-		}
-		items = append(items, id)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return i, err
 }
 
-const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, created_by_avatar_url, created_by_username, created_by_name
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	created_at < (
-		SELECT created_at
-		FROM template_version_with_user AS tv
-		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
-	)
-	AND organization_id = $1
-	AND template_id = $3
-ORDER BY created_at DESC
-LIMIT 1
+const updateTemplateSecretByTemplateIDAndName = `-- name: UpdateTemplateSecretByTemplateIDAndName :one
+UPDATE template_secrets
+SET
+    value        = CASE WHEN $1::bool THEN $2 ELSE value END,
+    value_key_id = CASE WHEN $1::bool THEN $3 ELSE value_key_id END,
+    description  = CASE WHEN $4::bool THEN $5 ELSE description END,
+    updated_at   = $6
+WHERE template_id = $7 AND name = $8
+RETURNING id, template_id, name, description, value, value_key_id, created_at, updated_at
 `
 
-type GetPreviousTemplateVersionParams struct {
-	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
-	Name           string        `db:"name" json:"name"`
-	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+type UpdateTemplateSecretByTemplateIDAndNameParams struct {
+	UpdateValue       bool           `db:"update_value" json:"update_value"`
+	Value             string         `db:"value" json:"value"`
+	ValueKeyID        sql.NullString `db:"value_key_id" json:"value_key_id"`
+	UpdateDescription bool           `db:"update_description" json:"update_description"`
+	Description       string         `db:"description" json:"description"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+	TemplateID        uuid.UUID      `db:"template_id" json:"template_id"`
+	Name              string         `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getPreviousTemplateVersion, arg.OrganizationID, arg.Name, arg.TemplateID)
-	var i TemplateVersion
+func (q *sqlQuerier) UpdateTemplateSecretByTemplateIDAndName(ctx context.Context, arg UpdateTemplateSecretByTemplateIDAndNameParams) (TemplateSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateSecretByTemplateIDAndName,
+		arg.UpdateValue,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.UpdateDescription,
+		arg.Description,
+		arg.UpdatedAt,
+		arg.TemplateID,
+		arg.Name,
+	)
+	var i TemplateSecret
 	err := row.Scan(
 		&i.ID,
 		&i.TemplateID,
-		&i.OrganizationID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		&i.ExternalAuthProviders,
-		&i.Message,
-		&i.Archived,
-		&i.SourceExampleID,
-		&i.HasAITask,
-		&i.HasExternalAgent,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-		&i.CreatedByName,
 	)
 	return i, err
 }
 
-const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, created_by_avatar_url, created_by_username, created_by_name
-FROM
-	template_version_with_user AS template_versions
+const deleteTemplateUserPresetByID = `-- name: DeleteTemplateUserPresetByID :exec
+DELETE FROM
+	template_user_presets
 WHERE
 	id = $1
 `
 
-func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByID, id)
-	var i TemplateVersion
-	err := row.Scan(
-		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		&i.ExternalAuthProviders,
-		&i.Message,
-		&i.Archived,
-		&i.SourceExampleID,
-		&i.HasAITask,
-		&i.HasExternalAgent,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-		&i.CreatedByName,
-	)
-	return i, err
+func (q *sqlQuerier) DeleteTemplateUserPresetByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateUserPresetByID, id)
+	return err
 }
 
-const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
+const getTemplateUserPresetByID = `-- name: GetTemplateUserPresetByID :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, created_by_avatar_url, created_by_username, created_by_name
+	id, template_id, user_id, name, parameters, created_at, updated_at
 FROM
-	template_version_with_user AS template_versions
+	template_user_presets
 WHERE
-	job_id = $1
+	id = $1
 `
 
-func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByJobID, jobID)
-	var i TemplateVersion
+func (q *sqlQuerier) GetTemplateUserPresetByID(ctx context.Context, id uuid.UUID) (TemplateUserPreset, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateUserPresetByID, id)
+	var i TemplateUserPreset
 	err := row.Scan(
 		&i.ID,
 		&i.TemplateID,
-		&i.OrganizationID,
+		&i.UserID,
+		&i.Name,
+		&i.Parameters,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		&i.ExternalAuthProviders,
-		&i.Message,
-		&i.Archived,
-		&i.SourceExampleID,
-		&i.HasAITask,
-		&i.HasExternalAgent,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-		&i.CreatedByName,
 	)
 	return i, err
 }
 
-const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
+const getTemplateUserPresetsByTemplateIDAndUserID = `-- name: GetTemplateUserPresetsByTemplateIDAndUserID :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, created_by_avatar_url, created_by_username, created_by_name
+	id, template_id, user_id, name, parameters, created_at, updated_at
 FROM
-	template_version_with_user AS template_versions
+	template_user_presets
 WHERE
 	template_id = $1
-	AND "name" = $2
+	AND user_id = $2
+ORDER BY
+	name ASC
 `
 
-type GetTemplateVersionByTemplateIDAndNameParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	Name       string        `db:"name" json:"name"`
+type GetTemplateUserPresetsByTemplateIDAndUserIDParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
 }
 
-func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByTemplateIDAndName, arg.TemplateID, arg.Name)
-	var i TemplateVersion
+func (q *sqlQuerier) GetTemplateUserPresetsByTemplateIDAndUserID(ctx context.Context, arg GetTemplateUserPresetsByTemplateIDAndUserIDParams) ([]TemplateUserPreset, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateUserPresetsByTemplateIDAndUserID, arg.TemplateID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateUserPreset
+	for rows.Next() {
+		var i TemplateUserPreset
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.UserID,
+			&i.Name,
+			&i.Parameters,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTemplateUserPreset = `-- name: UpsertTemplateUserPreset :one
+INSERT INTO
+	template_user_presets (
+		id,
+		template_id,
+		user_id,
+		name,
+		parameters,
+		created_at,
+		updated_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$6
+)
+ON CONFLICT (template_id, user_id, name) DO UPDATE SET
+	parameters = EXCLUDED.parameters,
+	updated_at = EXCLUDED.updated_at
+RETURNING id, template_id, user_id, name, parameters, created_at, updated_at
+`
+
+type UpsertTemplateUserPresetParams struct {
+	ID         uuid.UUID       `db:"id" json:"id"`
+	TemplateID uuid.UUID       `db:"template_id" json:"template_id"`
+	UserID     uuid.UUID       `db:"user_id" json:"user_id"`
+	Name       string          `db:"name" json:"name"`
+	Parameters json.RawMessage `db:"parameters" json:"parameters"`
+	Now        time.Time       `db:"now" json:"now"`
+}
+
+// Saves a named set of parameter values of a user for a template. Saving a
+// preset with the name of an existing one replaces its parameters.
+func (q *sqlQuerier) UpsertTemplateUserPreset(ctx context.Context, arg UpsertTemplateUserPresetParams) (TemplateUserPreset, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateUserPreset,
+		arg.ID,
+		arg.TemplateID,
+		arg.UserID,
+		arg.Name,
+		arg.Parameters,
+		arg.Now,
+	)
+	var i TemplateUserPreset
 	err := row.Scan(
 		&i.ID,
 		&i.TemplateID,
-		&i.OrganizationID,
+		&i.UserID,
+		&i.Name,
+		&i.Parameters,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		&i.ExternalAuthProviders,
-		&i.Message,
-		&i.Archived,
-		&i.SourceExampleID,
-		&i.HasAITask,
-		&i.HasExternalAgent,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-		&i.CreatedByName,
 	)
 	return i, err
 }
 
-const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, created_by_avatar_url, created_by_username, created_by_name
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	id = ANY($1 :: uuid [ ])
+const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
+SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, form_type, sensitive, group_name, group_order, page FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
 `
 
-func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByIDs, pq.Array(ids))
+func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []TemplateVersionParameter
 	for rows.Next() {
-		var i TemplateVersion
+		var i TemplateVersionParameter
 		if err := rows.Scan(
-			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.TemplateVersionID,
 			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			&i.ExternalAuthProviders,
-			&i.Message,
-			&i.Archived,
-			&i.SourceExampleID,
-			&i.HasAITask,
-			&i.HasExternalAgent,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
-			&i.CreatedByName,
+			&i.Description,
+			&i.Type,
+			&i.Mutable,
+			&i.DefaultValue,
+			&i.Icon,
+			&i.Options,
+			&i.ValidationRegex,
+			&i.ValidationMin,
+			&i.ValidationMax,
+			&i.ValidationError,
+			&i.ValidationMonotonic,
+			&i.Required,
+			&i.DisplayName,
+			&i.DisplayOrder,
+			&i.Ephemeral,
+			&i.FormType,
+			&i.Sensitive,
+			&i.GroupName,
+			&i.GroupOrder,
+			&i.Page,
 		); err != nil {
 			return nil, err
 		}
@@ -28005,90 +30730,210 @@ func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UU
 	return items, nil
 }
 
-const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
+const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
+INSERT INTO
+    template_version_parameters (
+        template_version_id,
+        name,
+        description,
+        type,
+        form_type,
+        mutable,
+        default_value,
+        icon,
+        options,
+        validation_regex,
+        validation_min,
+        validation_max,
+        validation_error,
+        validation_monotonic,
+        required,
+        display_name,
+        display_order,
+        ephemeral,
+        sensitive,
+        group_name,
+        group_order,
+        page
+    )
+VALUES
+    (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14,
+        $15,
+        $16,
+        $17,
+        $18,
+        $19,
+        $20,
+        $21,
+        $22
+    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, form_type, sensitive, group_name, group_order, page
+`
+
+type InsertTemplateVersionParameterParams struct {
+	TemplateVersionID   uuid.UUID         `db:"template_version_id" json:"template_version_id"`
+	Name                string            `db:"name" json:"name"`
+	Description         string            `db:"description" json:"description"`
+	Type                string            `db:"type" json:"type"`
+	FormType            ParameterFormType `db:"form_type" json:"form_type"`
+	Mutable             bool              `db:"mutable" json:"mutable"`
+	DefaultValue        string            `db:"default_value" json:"default_value"`
+	Icon                string            `db:"icon" json:"icon"`
+	Options             json.RawMessage   `db:"options" json:"options"`
+	ValidationRegex     string            `db:"validation_regex" json:"validation_regex"`
+	ValidationMin       sql.NullInt32     `db:"validation_min" json:"validation_min"`
+	ValidationMax       sql.NullInt32     `db:"validation_max" json:"validation_max"`
+	ValidationError     string            `db:"validation_error" json:"validation_error"`
+	ValidationMonotonic string            `db:"validation_monotonic" json:"validation_monotonic"`
+	Required            bool              `db:"required" json:"required"`
+	DisplayName         string            `db:"display_name" json:"display_name"`
+	DisplayOrder        int32             `db:"display_order" json:"display_order"`
+	Ephemeral           bool              `db:"ephemeral" json:"ephemeral"`
+	Sensitive           bool              `db:"sensitive" json:"sensitive"`
+	GroupName           string            `db:"group_name" json:"group_name"`
+	GroupOrder          int32             `db:"group_order" json:"group_order"`
+	Page                int32             `db:"page" json:"page"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Description,
+		arg.Type,
+		arg.FormType,
+		arg.Mutable,
+		arg.DefaultValue,
+		arg.Icon,
+		arg.Options,
+		arg.ValidationRegex,
+		arg.ValidationMin,
+		arg.ValidationMax,
+		arg.ValidationError,
+		arg.ValidationMonotonic,
+		arg.Required,
+		arg.DisplayName,
+		arg.DisplayOrder,
+		arg.Ephemeral,
+		arg.Sensitive,
+		arg.GroupName,
+		arg.GroupOrder,
+		arg.Page,
+	)
+	var i TemplateVersionParameter
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Description,
+		&i.Type,
+		&i.Mutable,
+		&i.DefaultValue,
+		&i.Icon,
+		&i.Options,
+		&i.ValidationRegex,
+		&i.ValidationMin,
+		&i.ValidationMax,
+		&i.ValidationError,
+		&i.ValidationMonotonic,
+		&i.Required,
+		&i.DisplayName,
+		&i.DisplayOrder,
+		&i.Ephemeral,
+		&i.FormType,
+		&i.Sensitive,
+		&i.GroupName,
+		&i.GroupOrder,
+		&i.Page,
+	)
+	return i, err
+}
+
+const getActiveTemplateVersionRolloutByTemplateID = `-- name: GetActiveTemplateVersionRolloutByTemplateID :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, created_by_avatar_url, created_by_username, created_by_name
+	id, template_id, template_version_id, previous_version_id, group_id, stages, current_stage, soak_duration, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stage_started_at, completed_at
 FROM
-	template_version_with_user AS template_versions
+	template_version_rollouts
 WHERE
-	template_id = $1 :: uuid
-	  AND CASE
-		-- If no filter is provided, default to returning ALL template versions.
-		-- The called should always provide a filter if they want to omit
-		-- archived versions.
-		WHEN $2 :: boolean IS NULL THEN true
-		ELSE template_versions.archived = $2 :: boolean
-	END
-	AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the created_at field, so select all
-			-- rows after the cursor.
-			(created_at, id) > (
-				SELECT
-					created_at, id
-				FROM
-					template_versions
-				WHERE
-					id = $3
-			)
-		)
-		ELSE true
-	END
-ORDER BY
-    -- Deterministic and consistent ordering of all rows, even if they share
-    -- a timestamp. This is to ensure consistent pagination.
-	(created_at, id) ASC OFFSET $4
-LIMIT
-	-- A null limit means "no limit", so 0 means return all
-	NULLIF($5 :: int, 0)
+	template_id = $1
+	AND status = 'in_progress'::template_version_rollout_status
 `
 
-type GetTemplateVersionsByTemplateIDParams struct {
-	TemplateID uuid.UUID    `db:"template_id" json:"template_id"`
-	Archived   sql.NullBool `db:"archived" json:"archived"`
-	AfterID    uuid.UUID    `db:"after_id" json:"after_id"`
-	OffsetOpt  int32        `db:"offset_opt" json:"offset_opt"`
-	LimitOpt   int32        `db:"limit_opt" json:"limit_opt"`
+func (q *sqlQuerier) GetActiveTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, getActiveTemplateVersionRolloutByTemplateID, templateID)
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.PreviousVersionID,
+		&i.GroupID,
+		pq.Array(&i.Stages),
+		&i.CurrentStage,
+		&i.SoakDuration,
+		&i.MaxFailureRate,
+		&i.MinBuilds,
+		&i.Status,
+		&i.StatusMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StageStartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByTemplateID,
-		arg.TemplateID,
-		arg.Archived,
-		arg.AfterID,
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
+const getActiveTemplateVersionRollouts = `-- name: GetActiveTemplateVersionRollouts :many
+SELECT
+	id, template_id, template_version_id, previous_version_id, group_id, stages, current_stage, soak_duration, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stage_started_at, completed_at
+FROM
+	template_version_rollouts
+WHERE
+	status = 'in_progress'::template_version_rollout_status
+ORDER BY
+	created_at ASC
+`
+
+func (q *sqlQuerier) GetActiveTemplateVersionRollouts(ctx context.Context) ([]TemplateVersionRollout, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveTemplateVersionRollouts)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []TemplateVersionRollout
 	for rows.Next() {
-		var i TemplateVersion
+		var i TemplateVersionRollout
 		if err := rows.Scan(
 			&i.ID,
 			&i.TemplateID,
-			&i.OrganizationID,
+			&i.TemplateVersionID,
+			&i.PreviousVersionID,
+			&i.GroupID,
+			pq.Array(&i.Stages),
+			&i.CurrentStage,
+			&i.SoakDuration,
+			&i.MaxFailureRate,
+			&i.MinBuilds,
+			&i.Status,
+			&i.StatusMessage,
+			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			&i.ExternalAuthProviders,
-			&i.Message,
-			&i.Archived,
-			&i.SourceExampleID,
-			&i.HasAITask,
-			&i.HasExternalAgent,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
-			&i.CreatedByName,
+			&i.StageStartedAt,
+			&i.CompletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -28103,38 +30948,113 @@ func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg Ge
 	return items, nil
 }
 
-const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
-SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, created_by_avatar_url, created_by_username, created_by_name FROM template_version_with_user AS template_versions WHERE created_at > $1
+const getTemplateVersionRolloutBuildStats = `-- name: GetTemplateVersionRolloutBuildStats :one
+SELECT
+	COUNT(*) AS builds,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status = 'failed'::provisioner_job_status) AS failed_builds
+FROM
+	workspace_builds
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_builds.template_version_id = $1
+	AND workspace_builds.transition = 'start'::workspace_transition
+	AND workspace_builds.created_at >= $2
+	AND provisioner_jobs.job_status IN ('succeeded'::provisioner_job_status, 'failed'::provisioner_job_status)
 `
 
-func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsCreatedAfter, createdAt)
+type GetTemplateVersionRolloutBuildStatsParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Since             time.Time `db:"since" json:"since"`
+}
+
+type GetTemplateVersionRolloutBuildStatsRow struct {
+	Builds       int64 `db:"builds" json:"builds"`
+	FailedBuilds int64 `db:"failed_builds" json:"failed_builds"`
+}
+
+// Counts the completed start builds of a template version created since the
+// given time, and how many of them failed. Used to compute the failure rate
+// of a rollout stage.
+func (q *sqlQuerier) GetTemplateVersionRolloutBuildStats(ctx context.Context, arg GetTemplateVersionRolloutBuildStatsParams) (GetTemplateVersionRolloutBuildStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionRolloutBuildStats, arg.TemplateVersionID, arg.Since)
+	var i GetTemplateVersionRolloutBuildStatsRow
+	err := row.Scan(&i.Builds, &i.FailedBuilds)
+	return i, err
+}
+
+const getTemplateVersionRolloutByID = `-- name: GetTemplateVersionRolloutByID :one
+SELECT
+	id, template_id, template_version_id, previous_version_id, group_id, stages, current_stage, soak_duration, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stage_started_at, completed_at
+FROM
+	template_version_rollouts
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionRolloutByID(ctx context.Context, id uuid.UUID) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionRolloutByID, id)
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.PreviousVersionID,
+		&i.GroupID,
+		pq.Array(&i.Stages),
+		&i.CurrentStage,
+		&i.SoakDuration,
+		&i.MaxFailureRate,
+		&i.MinBuilds,
+		&i.Status,
+		&i.StatusMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StageStartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getTemplateVersionRolloutsByTemplateID = `-- name: GetTemplateVersionRolloutsByTemplateID :many
+SELECT
+	id, template_id, template_version_id, previous_version_id, group_id, stages, current_stage, soak_duration, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stage_started_at, completed_at
+FROM
+	template_version_rollouts
+WHERE
+	template_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetTemplateVersionRolloutsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateVersionRollout, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionRolloutsByTemplateID, templateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []TemplateVersionRollout
 	for rows.Next() {
-		var i TemplateVersion
+		var i TemplateVersionRollout
 		if err := rows.Scan(
 			&i.ID,
 			&i.TemplateID,
-			&i.OrganizationID,
+			&i.TemplateVersionID,
+			&i.PreviousVersionID,
+			&i.GroupID,
+			pq.Array(&i.Stages),
+			&i.CurrentStage,
+			&i.SoakDuration,
+			&i.MaxFailureRate,
+			&i.MinBuilds,
+			&i.Status,
+			&i.StatusMessage,
+			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			&i.ExternalAuthProviders,
-			&i.Message,
-			&i.Archived,
-			&i.SourceExampleID,
-			&i.HasAITask,
-			&i.HasExternalAgent,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
-			&i.CreatedByName,
+			&i.StageStartedAt,
+			&i.CompletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -28149,287 +31069,510 @@ func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, create
 	return items, nil
 }
 
-const insertTemplateVersion = `-- name: InsertTemplateVersion :exec
+const insertTemplateVersionRollout = `-- name: InsertTemplateVersionRollout :one
 INSERT INTO
-	template_versions (
+	template_version_rollouts (
 		id,
 		template_id,
-		organization_id,
+		template_version_id,
+		previous_version_id,
+		group_id,
+		stages,
+		soak_duration,
+		max_failure_rate,
+		min_builds,
+		created_by,
 		created_at,
 		updated_at,
-		"name",
-		message,
-		readme,
-		job_id,
-		created_by,
-		source_example_id
+		stage_started_at
 	)
-VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6::integer[],
+	$7,
+	$8,
+	$9,
+	$10,
+	$11,
+	$11,
+	$11
+)
+RETURNING id, template_id, template_version_id, previous_version_id, group_id, stages, current_stage, soak_duration, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stage_started_at, completed_at
 `
 
-type InsertTemplateVersionParams struct {
-	ID              uuid.UUID      `db:"id" json:"id"`
-	TemplateID      uuid.NullUUID  `db:"template_id" json:"template_id"`
-	OrganizationID  uuid.UUID      `db:"organization_id" json:"organization_id"`
-	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
-	Name            string         `db:"name" json:"name"`
-	Message         string         `db:"message" json:"message"`
-	Readme          string         `db:"readme" json:"readme"`
-	JobID           uuid.UUID      `db:"job_id" json:"job_id"`
-	CreatedBy       uuid.UUID      `db:"created_by" json:"created_by"`
-	SourceExampleID sql.NullString `db:"source_example_id" json:"source_example_id"`
+type InsertTemplateVersionRolloutParams struct {
+	ID                uuid.UUID     `db:"id" json:"id"`
+	TemplateID        uuid.UUID     `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID     `db:"template_version_id" json:"template_version_id"`
+	PreviousVersionID uuid.UUID     `db:"previous_version_id" json:"previous_version_id"`
+	GroupID           uuid.NullUUID `db:"group_id" json:"group_id"`
+	Stages            []int32       `db:"stages" json:"stages"`
+	SoakDuration      int64         `db:"soak_duration" json:"soak_duration"`
+	MaxFailureRate    float64       `db:"max_failure_rate" json:"max_failure_rate"`
+	MinBuilds         int32         `db:"min_builds" json:"min_builds"`
+	CreatedBy         uuid.UUID     `db:"created_by" json:"created_by"`
+	CreatedAt         time.Time     `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error {
-	_, err := q.db.ExecContext(ctx, insertTemplateVersion,
+func (q *sqlQuerier) InsertTemplateVersionRollout(ctx context.Context, arg InsertTemplateVersionRolloutParams) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionRollout,
 		arg.ID,
 		arg.TemplateID,
-		arg.OrganizationID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.Name,
-		arg.Message,
-		arg.Readme,
-		arg.JobID,
+		arg.TemplateVersionID,
+		arg.PreviousVersionID,
+		arg.GroupID,
+		pq.Array(arg.Stages),
+		arg.SoakDuration,
+		arg.MaxFailureRate,
+		arg.MinBuilds,
 		arg.CreatedBy,
-		arg.SourceExampleID,
+		arg.CreatedAt,
 	)
-	return err
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.PreviousVersionID,
+		&i.GroupID,
+		pq.Array(&i.Stages),
+		&i.CurrentStage,
+		&i.SoakDuration,
+		&i.MaxFailureRate,
+		&i.MinBuilds,
+		&i.Status,
+		&i.StatusMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StageStartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
 }
 
-const unarchiveTemplateVersion = `-- name: UnarchiveTemplateVersion :exec
+const updateTemplateVersionRolloutStage = `-- name: UpdateTemplateVersionRolloutStage :one
 UPDATE
-	template_versions
+	template_version_rollouts
 SET
-	archived = false,
-	updated_at = $1
+	current_stage = $1,
+	stage_started_at = $2,
+	updated_at = $2
 WHERE
-		id = $2
+	id = $3
+	AND status = 'in_progress'::template_version_rollout_status
+RETURNING id, template_id, template_version_id, previous_version_id, group_id, stages, current_stage, soak_duration, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stage_started_at, completed_at
 `
 
-type UnarchiveTemplateVersionParams struct {
-	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+type UpdateTemplateVersionRolloutStageParams struct {
+	CurrentStage   int32     `db:"current_stage" json:"current_stage"`
+	StageStartedAt time.Time `db:"stage_started_at" json:"stage_started_at"`
+	ID             uuid.UUID `db:"id" json:"id"`
 }
 
-// This will always work regardless of the current state of the template version.
-func (q *sqlQuerier) UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error {
-	_, err := q.db.ExecContext(ctx, unarchiveTemplateVersion, arg.UpdatedAt, arg.TemplateVersionID)
-	return err
+func (q *sqlQuerier) UpdateTemplateVersionRolloutStage(ctx context.Context, arg UpdateTemplateVersionRolloutStageParams) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateVersionRolloutStage, arg.CurrentStage, arg.StageStartedAt, arg.ID)
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.PreviousVersionID,
+		&i.GroupID,
+		pq.Array(&i.Stages),
+		&i.CurrentStage,
+		&i.SoakDuration,
+		&i.MaxFailureRate,
+		&i.MinBuilds,
+		&i.Status,
+		&i.StatusMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StageStartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
 }
 
-const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
+const updateTemplateVersionRolloutStatus = `-- name: UpdateTemplateVersionRolloutStatus :one
 UPDATE
-	template_versions
+	template_version_rollouts
 SET
-	template_id = $2,
+	status = $1,
+	status_message = $2,
 	updated_at = $3,
-	name = $4,
-	message = $5
+	completed_at = $3
 WHERE
-	id = $1
+	id = $4
+	AND status = 'in_progress'::template_version_rollout_status
+RETURNING id, template_id, template_version_id, previous_version_id, group_id, stages, current_stage, soak_duration, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stage_started_at, completed_at
 `
 
-type UpdateTemplateVersionByIDParams struct {
-	ID         uuid.UUID     `db:"id" json:"id"`
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
-	Name       string        `db:"name" json:"name"`
-	Message    string        `db:"message" json:"message"`
+type UpdateTemplateVersionRolloutStatusParams struct {
+	Status        TemplateVersionRolloutStatus `db:"status" json:"status"`
+	StatusMessage string                       `db:"status_message" json:"status_message"`
+	UpdatedAt     time.Time                    `db:"updated_at" json:"updated_at"`
+	ID            uuid.UUID                    `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID,
-		arg.ID,
-		arg.TemplateID,
+// Finishes an in-progress rollout. No rows are returned if the rollout is
+// already finished.
+func (q *sqlQuerier) UpdateTemplateVersionRolloutStatus(ctx context.Context, arg UpdateTemplateVersionRolloutStatusParams) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateVersionRolloutStatus,
+		arg.Status,
+		arg.StatusMessage,
 		arg.UpdatedAt,
-		arg.Name,
-		arg.Message,
+		arg.ID,
 	)
-	return err
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.PreviousVersionID,
+		&i.GroupID,
+		pq.Array(&i.Stages),
+		&i.CurrentStage,
+		&i.SoakDuration,
+		&i.MaxFailureRate,
+		&i.MinBuilds,
+		&i.Status,
+		&i.StatusMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StageStartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
 }
 
-const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
+const archiveUnusedTemplateVersions = `-- name: ArchiveUnusedTemplateVersions :many
 UPDATE
 	template_versions
 SET
-	readme = $2,
-	updated_at = $3
+	archived = true,
+	updated_at = $1
+FROM
+	-- Archive all versions that are returned from this query.
+	(
+		SELECT
+			scoped_template_versions.id
+		FROM
+			-- Scope an archive to a single template and ignore already archived template versions
+			(
+				SELECT
+					id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, deprecated, deprecation_cutoff
+				FROM
+					template_versions
+				WHERE
+					template_versions.template_id = $2 :: uuid
+					AND
+					archived = false
+					AND
+					-- This allows archiving a specific template version.
+					CASE
+						WHEN $3::uuid  != '00000000-0000-0000-0000-000000000000'::uuid THEN
+							template_versions.id = $3 :: uuid
+						ELSE
+							true
+						END
+			) AS scoped_template_versions
+			LEFT JOIN
+				provisioner_jobs ON scoped_template_versions.job_id = provisioner_jobs.id
+			LEFT JOIN
+				templates ON scoped_template_versions.template_id = templates.id
+		WHERE
+		  -- Actively used template versions (meaning the latest build is using
+		  -- the version) are never archived. A "restart" command on the workspace,
+		  -- even if failed, would use the version. So it cannot be archived until
+		  -- the build is outdated.
+		NOT EXISTS (
+			-- Return all "used" versions, where "used" is defined as being
+			-- used by a latest workspace build.
+			SELECT template_version_id FROM (
+				SELECT
+					DISTINCT ON (workspace_id) template_version_id, transition
+				FROM
+					workspace_builds
+				ORDER BY workspace_id, build_number DESC
+				) AS used_versions
+			WHERE
+				used_versions.transition != 'delete'
+				AND
+				scoped_template_versions.id = used_versions.template_version_id
+		)
+		-- Also never archive the active template version
+		AND active_version_id != scoped_template_versions.id
+		AND CASE
+			-- Optionally, only archive versions that match a given
+			-- job status like 'failed'.
+			WHEN $4 :: provisioner_job_status IS NOT NULL THEN
+				provisioner_jobs.job_status = $4 :: provisioner_job_status
+			ELSE
+				true
+		END
+		-- Pending or running jobs should not be archived, as they are "in progress"
+		AND provisioner_jobs.job_status != 'running'
+		AND provisioner_jobs.job_status != 'pending'
+	) AS archived_versions
 WHERE
-	job_id = $1
+	template_versions.id IN (archived_versions.id)
+RETURNING template_versions.id
 `
 
-type UpdateTemplateVersionDescriptionByJobIDParams struct {
-	JobID     uuid.UUID `db:"job_id" json:"job_id"`
-	Readme    string    `db:"readme" json:"readme"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+type ArchiveUnusedTemplateVersionsParams struct {
+	UpdatedAt         time.Time                `db:"updated_at" json:"updated_at"`
+	TemplateID        uuid.UUID                `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID                `db:"template_version_id" json:"template_version_id"`
+	JobStatus         NullProvisionerJobStatus `db:"job_status" json:"job_status"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
-	return err
+// Archiving templates is a soft delete action, so is reversible.
+// Archiving prevents the version from being used and discovered
+// by listing.
+// Only unused template versions will be archived, which are any versions not
+// referenced by the latest build of a workspace.
+func (q *sqlQuerier) ArchiveUnusedTemplateVersions(ctx context.Context, arg ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, archiveUnusedTemplateVersions,
+		arg.UpdatedAt,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.JobStatus,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const updateTemplateVersionExternalAuthProvidersByJobID = `-- name: UpdateTemplateVersionExternalAuthProvidersByJobID :exec
-UPDATE
-	template_versions
-SET
-	external_auth_providers = $2,
-	updated_at = $3
+const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, deprecated, deprecation_cutoff, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
 WHERE
-	job_id = $1
+	created_at < (
+		SELECT created_at
+		FROM template_version_with_user AS tv
+		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
+	)
+	AND organization_id = $1
+	AND template_id = $3
+ORDER BY created_at DESC
+LIMIT 1
 `
 
-type UpdateTemplateVersionExternalAuthProvidersByJobIDParams struct {
-	JobID                 uuid.UUID       `db:"job_id" json:"job_id"`
-	ExternalAuthProviders json.RawMessage `db:"external_auth_providers" json:"external_auth_providers"`
-	UpdatedAt             time.Time       `db:"updated_at" json:"updated_at"`
+type GetPreviousTemplateVersionParams struct {
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	Name           string        `db:"name" json:"name"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionExternalAuthProvidersByJobID, arg.JobID, arg.ExternalAuthProviders, arg.UpdatedAt)
-	return err
+func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getPreviousTemplateVersion, arg.OrganizationID, arg.Name, arg.TemplateID)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		&i.ExternalAuthProviders,
+		&i.Message,
+		&i.Archived,
+		&i.SourceExampleID,
+		&i.HasAITask,
+		&i.HasExternalAgent,
+		&i.Deprecated,
+		&i.DeprecationCutoff,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+		&i.CreatedByName,
+	)
+	return i, err
 }
 
-const updateTemplateVersionFlagsByJobID = `-- name: UpdateTemplateVersionFlagsByJobID :exec
-UPDATE
-	template_versions
-SET
-	has_ai_task = $2,
-	has_external_agent = $3,
-	updated_at = $4
+const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, deprecated, deprecation_cutoff, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
 WHERE
-	job_id = $1
+	id = $1
 `
 
-type UpdateTemplateVersionFlagsByJobIDParams struct {
-	JobID            uuid.UUID    `db:"job_id" json:"job_id"`
-	HasAITask        sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
-	HasExternalAgent sql.NullBool `db:"has_external_agent" json:"has_external_agent"`
-	UpdatedAt        time.Time    `db:"updated_at" json:"updated_at"`
-}
-
-func (q *sqlQuerier) UpdateTemplateVersionFlagsByJobID(ctx context.Context, arg UpdateTemplateVersionFlagsByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionFlagsByJobID,
-		arg.JobID,
-		arg.HasAITask,
-		arg.HasExternalAgent,
-		arg.UpdatedAt,
+func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByID, id)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		&i.ExternalAuthProviders,
+		&i.Message,
+		&i.Archived,
+		&i.SourceExampleID,
+		&i.HasAITask,
+		&i.HasExternalAgent,
+		&i.Deprecated,
+		&i.DeprecationCutoff,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+		&i.CreatedByName,
 	)
-	return err
+	return i, err
 }
 
-const getTemplateVersionTerraformValues = `-- name: GetTemplateVersionTerraformValues :one
+const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
 SELECT
-	template_version_terraform_values.template_version_id, template_version_terraform_values.updated_at, template_version_terraform_values.cached_plan, template_version_terraform_values.cached_module_files, template_version_terraform_values.provisionerd_version
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, deprecated, deprecation_cutoff, created_by_avatar_url, created_by_username, created_by_name
 FROM
-	template_version_terraform_values
+	template_version_with_user AS template_versions
 WHERE
-	template_version_terraform_values.template_version_id = $1
+	job_id = $1
 `
 
-func (q *sqlQuerier) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (TemplateVersionTerraformValue, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionTerraformValues, templateVersionID)
-	var i TemplateVersionTerraformValue
+func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByJobID, jobID)
+	var i TemplateVersion
 	err := row.Scan(
-		&i.TemplateVersionID,
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.CachedPlan,
-		&i.CachedModuleFiles,
-		&i.ProvisionerdVersion,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		&i.ExternalAuthProviders,
+		&i.Message,
+		&i.Archived,
+		&i.SourceExampleID,
+		&i.HasAITask,
+		&i.HasExternalAgent,
+		&i.Deprecated,
+		&i.DeprecationCutoff,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+		&i.CreatedByName,
 	)
 	return i, err
 }
 
-const hasTemplateVersionsUsingCachedModuleFileInOrg = `-- name: HasTemplateVersionsUsingCachedModuleFileInOrg :one
-SELECT EXISTS (
-	SELECT 1
-	FROM template_version_terraform_values tvtv
-	JOIN template_versions tv
-		ON tv.id = tvtv.template_version_id
-	WHERE tvtv.cached_module_files = $1::uuid
-		AND tv.organization_id = $2::uuid
-)
+const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, deprecated, deprecation_cutoff, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	template_id = $1
+	AND "name" = $2
 `
 
-type HasTemplateVersionsUsingCachedModuleFileInOrgParams struct {
-	FileID         uuid.UUID `db:"file_id" json:"file_id"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+type GetTemplateVersionByTemplateIDAndNameParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	Name       string        `db:"name" json:"name"`
 }
 
-// Reports whether the given file is referenced as cached module files by any
-// template version in the given organization. Used to authorize provisioner
-// module-file downloads so a daemon cannot read another organization's cached
-// Terraform module source.
-func (q *sqlQuerier) HasTemplateVersionsUsingCachedModuleFileInOrg(ctx context.Context, arg HasTemplateVersionsUsingCachedModuleFileInOrgParams) (bool, error) {
-	row := q.db.QueryRowContext(ctx, hasTemplateVersionsUsingCachedModuleFileInOrg, arg.FileID, arg.OrganizationID)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByTemplateIDAndName, arg.TemplateID, arg.Name)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		&i.ExternalAuthProviders,
+		&i.Message,
+		&i.Archived,
+		&i.SourceExampleID,
+		&i.HasAITask,
+		&i.HasExternalAgent,
+		&i.Deprecated,
+		&i.DeprecationCutoff,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+		&i.CreatedByName,
+	)
+	return i, err
 }
 
-const insertTemplateVersionTerraformValuesByJobID = `-- name: InsertTemplateVersionTerraformValuesByJobID :exec
-INSERT INTO
-	template_version_terraform_values (
-		template_version_id,
-		cached_plan,
-		cached_module_files,
-		updated_at,
-	    provisionerd_version
-	)
-VALUES
-	(
-		(select id from template_versions where job_id = $1),
-		$2,
-		$3,
-		$4,
-		$5
-	)
-`
-
-type InsertTemplateVersionTerraformValuesByJobIDParams struct {
-	JobID               uuid.UUID       `db:"job_id" json:"job_id"`
-	CachedPlan          json.RawMessage `db:"cached_plan" json:"cached_plan"`
-	CachedModuleFiles   uuid.NullUUID   `db:"cached_module_files" json:"cached_module_files"`
-	UpdatedAt           time.Time       `db:"updated_at" json:"updated_at"`
-	ProvisionerdVersion string          `db:"provisionerd_version" json:"provisionerd_version"`
-}
-
-func (q *sqlQuerier) InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg InsertTemplateVersionTerraformValuesByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, insertTemplateVersionTerraformValuesByJobID,
-		arg.JobID,
-		arg.CachedPlan,
-		arg.CachedModuleFiles,
-		arg.UpdatedAt,
-		arg.ProvisionerdVersion,
-	)
-	return err
-}
-
-const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
-SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1 ORDER BY name
+const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, deprecated, deprecation_cutoff, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	id = ANY($1 :: uuid [ ])
 `
 
-func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionVariables, templateVersionID)
+func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersionVariable
+	var items []TemplateVersion
 	for rows.Next() {
-		var i TemplateVersionVariable
+		var i TemplateVersion
 		if err := rows.Scan(
-			&i.TemplateVersionID,
+			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 			&i.Name,
-			&i.Description,
-			&i.Type,
-			&i.Value,
-			&i.DefaultValue,
-			&i.Required,
-			&i.Sensitive,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			&i.ExternalAuthProviders,
+			&i.Message,
+			&i.Archived,
+			&i.SourceExampleID,
+			&i.HasAITask,
+			&i.HasExternalAgent,
+			&i.Deprecated,
+			&i.DeprecationCutoff,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+			&i.CreatedByName,
 		); err != nil {
 			return nil, err
 		}
@@ -28444,81 +31587,93 @@ func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVe
 	return items, nil
 }
 
-const insertTemplateVersionVariable = `-- name: InsertTemplateVersionVariable :one
-INSERT INTO
-    template_version_variables (
-        template_version_id,
-        name,
-        description,
-        type,
-        value,
-        default_value,
-        required,
-        sensitive
-    )
-VALUES
-    (
-        $1,
-        $2,
-        $3,
-        $4,
-        $5,
-        $6,
-        $7,
-        $8
-    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
+const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, deprecated, deprecation_cutoff, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	template_id = $1 :: uuid
+	  AND CASE
+		-- If no filter is provided, default to returning ALL template versions.
+		-- The called should always provide a filter if they want to omit
+		-- archived versions.
+		WHEN $2 :: boolean IS NULL THEN true
+		ELSE template_versions.archived = $2 :: boolean
+	END
+	AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the created_at field, so select all
+			-- rows after the cursor.
+			(created_at, id) > (
+				SELECT
+					created_at, id
+				FROM
+					template_versions
+				WHERE
+					id = $3
+			)
+		)
+		ELSE true
+	END
+ORDER BY
+    -- Deterministic and consistent ordering of all rows, even if they share
+    -- a timestamp. This is to ensure consistent pagination.
+	(created_at, id) ASC OFFSET $4
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($5 :: int, 0)
 `
 
-type InsertTemplateVersionVariableParams struct {
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
-	Name              string    `db:"name" json:"name"`
-	Description       string    `db:"description" json:"description"`
-	Type              string    `db:"type" json:"type"`
-	Value             string    `db:"value" json:"value"`
-	DefaultValue      string    `db:"default_value" json:"default_value"`
-	Required          bool      `db:"required" json:"required"`
-	Sensitive         bool      `db:"sensitive" json:"sensitive"`
+type GetTemplateVersionsByTemplateIDParams struct {
+	TemplateID uuid.UUID    `db:"template_id" json:"template_id"`
+	Archived   sql.NullBool `db:"archived" json:"archived"`
+	AfterID    uuid.UUID    `db:"after_id" json:"after_id"`
+	OffsetOpt  int32        `db:"offset_opt" json:"offset_opt"`
+	LimitOpt   int32        `db:"limit_opt" json:"limit_opt"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionVariable,
-		arg.TemplateVersionID,
-		arg.Name,
-		arg.Description,
-		arg.Type,
-		arg.Value,
-		arg.DefaultValue,
-		arg.Required,
-		arg.Sensitive,
-	)
-	var i TemplateVersionVariable
-	err := row.Scan(
-		&i.TemplateVersionID,
-		&i.Name,
-		&i.Description,
-		&i.Type,
-		&i.Value,
-		&i.DefaultValue,
-		&i.Required,
-		&i.Sensitive,
+func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByTemplateID,
+		arg.TemplateID,
+		arg.Archived,
+		arg.AfterID,
+		arg.OffsetOpt,
+		arg.LimitOpt,
 	)
-	return i, err
-}
-
-const getTemplateVersionWorkspaceTags = `-- name: GetTemplateVersionWorkspaceTags :many
-SELECT template_version_id, key, value FROM template_version_workspace_tags WHERE template_version_id = $1 ORDER BY LOWER(key) ASC
-`
-
-func (q *sqlQuerier) GetTemplateVersionWorkspaceTags(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionWorkspaceTag, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionWorkspaceTags, templateVersionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersionWorkspaceTag
+	var items []TemplateVersion
 	for rows.Next() {
-		var i TemplateVersionWorkspaceTag
-		if err := rows.Scan(&i.TemplateVersionID, &i.Key, &i.Value); err != nil {
+		var i TemplateVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			&i.ExternalAuthProviders,
+			&i.Message,
+			&i.Archived,
+			&i.SourceExampleID,
+			&i.HasAITask,
+			&i.HasExternalAgent,
+			&i.Deprecated,
+			&i.DeprecationCutoff,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+			&i.CreatedByName,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -28532,196 +31687,40 @@ func (q *sqlQuerier) GetTemplateVersionWorkspaceTags(ctx context.Context, templa
 	return items, nil
 }
 
-const insertTemplateVersionWorkspaceTag = `-- name: InsertTemplateVersionWorkspaceTag :one
-INSERT INTO
-    template_version_workspace_tags (
-        template_version_id,
-        key,
-        value
-    )
-VALUES
-    (
-        $1,
-        $2,
-        $3
-    ) RETURNING template_version_id, key, value
-`
-
-type InsertTemplateVersionWorkspaceTagParams struct {
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
-	Key               string    `db:"key" json:"key"`
-	Value             string    `db:"value" json:"value"`
-}
-
-func (q *sqlQuerier) InsertTemplateVersionWorkspaceTag(ctx context.Context, arg InsertTemplateVersionWorkspaceTagParams) (TemplateVersionWorkspaceTag, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionWorkspaceTag, arg.TemplateVersionID, arg.Key, arg.Value)
-	var i TemplateVersionWorkspaceTag
-	err := row.Scan(&i.TemplateVersionID, &i.Key, &i.Value)
-	return i, err
-}
-
-const disableForeignKeysAndTriggers = `-- name: DisableForeignKeysAndTriggers :exec
-DO $$
-DECLARE
-    table_record record;
-BEGIN
-    FOR table_record IN
-        SELECT table_schema, table_name
-        FROM information_schema.tables
-        WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
-        AND table_type = 'BASE TABLE'
-    LOOP
-        EXECUTE format('ALTER TABLE %I.%I DISABLE TRIGGER ALL',
-                    table_record.table_schema,
-                    table_record.table_name);
-    END LOOP;
-END;
-$$
-`
-
-// Disable foreign keys and triggers for all tables.
-// Deprecated: disable foreign keys was created to aid in migrating off
-// of the test-only in-memory database. Do not use this in new code.
-func (q *sqlQuerier) DisableForeignKeysAndTriggers(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, disableForeignKeysAndTriggers)
-	return err
-}
-
-const getTotalUsageDCManagedAgentsV1 = `-- name: GetTotalUsageDCManagedAgentsV1 :one
-SELECT
-    -- The first cast is necessary since you can't sum strings, and the second
-    -- cast is necessary to make sqlc happy.
-    COALESCE(SUM((usage_data->>'count')::bigint), 0)::bigint AS total_count
-FROM
-    usage_events_daily
-WHERE
-    event_type = 'dc_managed_agents_v1'
-    -- Parentheses are necessary to avoid sqlc from generating an extra
-    -- argument.
-    AND day BETWEEN date_trunc('day', ($1::timestamptz) AT TIME ZONE 'UTC')::date AND date_trunc('day', ($2::timestamptz) AT TIME ZONE 'UTC')::date
-`
-
-type GetTotalUsageDCManagedAgentsV1Params struct {
-	StartDate time.Time `db:"start_date" json:"start_date"`
-	EndDate   time.Time `db:"end_date" json:"end_date"`
-}
-
-// Gets the total number of managed agents created between two dates. Uses the
-// aggregate table to avoid large scans or a complex index on the usage_events
-// table.
-//
-// This has the trade off that we can't count accurately between two exact
-// timestamps. The provided timestamps will be converted to UTC and truncated to
-// the events that happened on and between the two dates. Both dates are
-// inclusive.
-func (q *sqlQuerier) GetTotalUsageDCManagedAgentsV1(ctx context.Context, arg GetTotalUsageDCManagedAgentsV1Params) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getTotalUsageDCManagedAgentsV1, arg.StartDate, arg.EndDate)
-	var total_count int64
-	err := row.Scan(&total_count)
-	return total_count, err
-}
-
-const insertUsageEvent = `-- name: InsertUsageEvent :exec
-INSERT INTO
-    usage_events (
-        id,
-        event_type,
-        event_data,
-        created_at,
-        publish_started_at,
-        published_at,
-        failure_message
-    )
-VALUES
-    ($1, $2, $3, $4, NULL, NULL, NULL)
-ON CONFLICT (id) DO NOTHING
-`
-
-type InsertUsageEventParams struct {
-	ID        string          `db:"id" json:"id"`
-	EventType string          `db:"event_type" json:"event_type"`
-	EventData json.RawMessage `db:"event_data" json:"event_data"`
-	CreatedAt time.Time       `db:"created_at" json:"created_at"`
-}
-
-// Duplicate events are ignored intentionally to allow for multiple replicas to
-// publish heartbeat events.
-func (q *sqlQuerier) InsertUsageEvent(ctx context.Context, arg InsertUsageEventParams) error {
-	_, err := q.db.ExecContext(ctx, insertUsageEvent,
-		arg.ID,
-		arg.EventType,
-		arg.EventData,
-		arg.CreatedAt,
-	)
-	return err
-}
-
-const selectUsageEventsForPublishing = `-- name: SelectUsageEventsForPublishing :many
-WITH usage_events AS (
-    UPDATE
-        usage_events
-    SET
-        publish_started_at = $1::timestamptz
-    WHERE
-        id IN (
-            SELECT
-                potential_event.id
-            FROM
-                usage_events potential_event
-            WHERE
-                -- Do not publish events that have already been published or
-                -- have permanently failed to publish.
-                potential_event.published_at IS NULL
-                -- Do not publish events that are already being published by
-                -- another replica.
-                AND (
-                    potential_event.publish_started_at IS NULL
-                    -- If the event has publish_started_at set, it must be older
-                    -- than an hour ago. This is so we can retry publishing
-                    -- events where the replica exited or couldn't update the
-                    -- row.
-                    -- The parentheses around @now::timestamptz are necessary to
-                    -- avoid sqlc from generating an extra argument.
-                    OR potential_event.publish_started_at < ($1::timestamptz) - INTERVAL '1 hour'
-                )
-                -- Do not publish events older than 30 days. Tallyman will
-                -- always permanently reject these events anyways. This is to
-                -- avoid duplicate events being billed to customers, as
-                -- Metronome will only deduplicate events within 34 days.
-                -- Also, the same parentheses thing here as above.
-                AND potential_event.created_at > ($1::timestamptz) - INTERVAL '30 days'
-            ORDER BY potential_event.created_at ASC
-            FOR UPDATE SKIP LOCKED
-            LIMIT 100
-        )
-    RETURNING id, event_type, event_data, created_at, publish_started_at, published_at, failure_message
-)
-SELECT id, event_type, event_data, created_at, publish_started_at, published_at, failure_message
-FROM usage_events
-ORDER BY created_at ASC
+const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
+SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, has_external_agent, deprecated, deprecation_cutoff, created_by_avatar_url, created_by_username, created_by_name FROM template_version_with_user AS template_versions WHERE created_at > $1
 `
 
-// Note that this selects from the CTE, not the original table. The CTE is named
-// the same as the original table to trick sqlc into reusing the existing struct
-// for the table.
-// The CTE and the reorder is required because UPDATE doesn't guarantee order.
-func (q *sqlQuerier) SelectUsageEventsForPublishing(ctx context.Context, now time.Time) ([]UsageEvent, error) {
-	rows, err := q.db.QueryContext(ctx, selectUsageEventsForPublishing, now)
+func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsCreatedAfter, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UsageEvent
+	var items []TemplateVersion
 	for rows.Next() {
-		var i UsageEvent
+		var i TemplateVersion
 		if err := rows.Scan(
 			&i.ID,
-			&i.EventType,
-			&i.EventData,
+			&i.TemplateID,
+			&i.OrganizationID,
 			&i.CreatedAt,
-			&i.PublishStartedAt,
-			&i.PublishedAt,
-			&i.FailureMessage,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			&i.ExternalAuthProviders,
+			&i.Message,
+			&i.Archived,
+			&i.SourceExampleID,
+			&i.HasAITask,
+			&i.HasExternalAgent,
+			&i.Deprecated,
+			&i.DeprecationCutoff,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+			&i.CreatedByName,
 		); err != nil {
 			return nil, err
 		}
@@ -28736,194 +31735,272 @@ func (q *sqlQuerier) SelectUsageEventsForPublishing(ctx context.Context, now tim
 	return items, nil
 }
 
-const updateUsageEventsPostPublish = `-- name: UpdateUsageEventsPostPublish :exec
-UPDATE
-    usage_events
-SET
-    publish_started_at = NULL,
-    published_at = CASE WHEN input.set_published_at THEN $1::timestamptz ELSE NULL END,
-    failure_message = NULLIF(input.failure_message, '')
-FROM (
-    SELECT
-        UNNEST($2::text[]) AS id,
-        UNNEST($3::text[]) AS failure_message,
-        UNNEST($4::boolean[]) AS set_published_at
-) input
-WHERE
-    input.id = usage_events.id
-    -- If the number of ids, failure messages, and set published ats are not the
-    -- same, do not do anything. Unfortunately you can't really throw from a
-    -- query without writing a function or doing some jank like dividing by
-    -- zero, so this is the best we can do.
-    AND cardinality($2::text[]) = cardinality($3::text[])
-    AND cardinality($2::text[]) = cardinality($4::boolean[])
+const insertTemplateVersion = `-- name: InsertTemplateVersion :exec
+INSERT INTO
+	template_versions (
+		id,
+		template_id,
+		organization_id,
+		created_at,
+		updated_at,
+		"name",
+		message,
+		readme,
+		job_id,
+		created_by,
+		source_example_id
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 `
 
-type UpdateUsageEventsPostPublishParams struct {
-	Now             time.Time `db:"now" json:"now"`
-	IDs             []string  `db:"ids" json:"ids"`
-	FailureMessages []string  `db:"failure_messages" json:"failure_messages"`
-	SetPublishedAts []bool    `db:"set_published_ats" json:"set_published_ats"`
+type InsertTemplateVersionParams struct {
+	ID              uuid.UUID      `db:"id" json:"id"`
+	TemplateID      uuid.NullUUID  `db:"template_id" json:"template_id"`
+	OrganizationID  uuid.UUID      `db:"organization_id" json:"organization_id"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+	Name            string         `db:"name" json:"name"`
+	Message         string         `db:"message" json:"message"`
+	Readme          string         `db:"readme" json:"readme"`
+	JobID           uuid.UUID      `db:"job_id" json:"job_id"`
+	CreatedBy       uuid.UUID      `db:"created_by" json:"created_by"`
+	SourceExampleID sql.NullString `db:"source_example_id" json:"source_example_id"`
 }
 
-func (q *sqlQuerier) UpdateUsageEventsPostPublish(ctx context.Context, arg UpdateUsageEventsPostPublishParams) error {
-	_, err := q.db.ExecContext(ctx, updateUsageEventsPostPublish,
-		arg.Now,
-		pq.Array(arg.IDs),
-		pq.Array(arg.FailureMessages),
-		pq.Array(arg.SetPublishedAts),
+func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplateVersion,
+		arg.ID,
+		arg.TemplateID,
+		arg.OrganizationID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Message,
+		arg.Readme,
+		arg.JobID,
+		arg.CreatedBy,
+		arg.SourceExampleID,
 	)
 	return err
 }
 
-const usageEventExistsByID = `-- name: UsageEventExistsByID :one
-SELECT EXISTS(
-    SELECT 1 FROM usage_events WHERE id = $1
-)::bool
+const unarchiveTemplateVersion = `-- name: UnarchiveTemplateVersion :exec
+UPDATE
+	template_versions
+SET
+	archived = false,
+	updated_at = $1
+WHERE
+		id = $2
 `
 
-func (q *sqlQuerier) UsageEventExistsByID(ctx context.Context, id string) (bool, error) {
-	row := q.db.QueryRowContext(ctx, usageEventExistsByID, id)
-	var column_1 bool
-	err := row.Scan(&column_1)
-	return column_1, err
+type UnarchiveTemplateVersionParams struct {
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
 }
 
-const deleteUserAIProviderKey = `-- name: DeleteUserAIProviderKey :exec
-DELETE FROM
-    user_ai_provider_keys
-WHERE
-    user_id = $1::uuid
-    AND ai_provider_id = $2::uuid
+// This will always work regardless of the current state of the template version.
+func (q *sqlQuerier) UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error {
+	_, err := q.db.ExecContext(ctx, unarchiveTemplateVersion, arg.UpdatedAt, arg.TemplateVersionID)
+	return err
+}
+
+const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
+UPDATE
+	template_versions
+SET
+	template_id = $2,
+	updated_at = $3,
+	name = $4,
+	message = $5
+WHERE
+	id = $1
 `
 
-type DeleteUserAIProviderKeyParams struct {
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
-	AIProviderID uuid.UUID `db:"ai_provider_id" json:"ai_provider_id"`
+type UpdateTemplateVersionByIDParams struct {
+	ID         uuid.UUID     `db:"id" json:"id"`
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
+	Name       string        `db:"name" json:"name"`
+	Message    string        `db:"message" json:"message"`
 }
 
-func (q *sqlQuerier) DeleteUserAIProviderKey(ctx context.Context, arg DeleteUserAIProviderKeyParams) error {
-	_, err := q.db.ExecContext(ctx, deleteUserAIProviderKey, arg.UserID, arg.AIProviderID)
+func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID,
+		arg.ID,
+		arg.TemplateID,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Message,
+	)
 	return err
 }
 
-const deleteUserAIProviderKeysByProviderID = `-- name: DeleteUserAIProviderKeysByProviderID :exec
-DELETE FROM
-    user_ai_provider_keys
+const updateTemplateVersionDeprecationByID = `-- name: UpdateTemplateVersionDeprecationByID :exec
+UPDATE
+	template_versions
+SET
+	deprecated = $1,
+	deprecation_cutoff = $2,
+	updated_at = $3
 WHERE
-    ai_provider_id = $1::uuid
+	id = $4
 `
 
-func (q *sqlQuerier) DeleteUserAIProviderKeysByProviderID(ctx context.Context, aiProviderID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteUserAIProviderKeysByProviderID, aiProviderID)
+type UpdateTemplateVersionDeprecationByIDParams struct {
+	Deprecated        string       `db:"deprecated" json:"deprecated"`
+	DeprecationCutoff sql.NullTime `db:"deprecation_cutoff" json:"deprecation_cutoff"`
+	UpdatedAt         time.Time    `db:"updated_at" json:"updated_at"`
+	ID                uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionDeprecationByID(ctx context.Context, arg UpdateTemplateVersionDeprecationByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionDeprecationByID,
+		arg.Deprecated,
+		arg.DeprecationCutoff,
+		arg.UpdatedAt,
+		arg.ID,
+	)
 	return err
 }
 
-const getUserAIProviderKeyByProviderID = `-- name: GetUserAIProviderKeyByProviderID :one
-SELECT
-    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
-FROM
-    user_ai_provider_keys
+const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
+UPDATE
+	template_versions
+SET
+	readme = $2,
+	updated_at = $3
 WHERE
-    user_id = $1::uuid
-    AND ai_provider_id = $2::uuid
+	job_id = $1
 `
 
-type GetUserAIProviderKeyByProviderIDParams struct {
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
-	AIProviderID uuid.UUID `db:"ai_provider_id" json:"ai_provider_id"`
+type UpdateTemplateVersionDescriptionByJobIDParams struct {
+	JobID     uuid.UUID `db:"job_id" json:"job_id"`
+	Readme    string    `db:"readme" json:"readme"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) GetUserAIProviderKeyByProviderID(ctx context.Context, arg GetUserAIProviderKeyByProviderIDParams) (UserAIProviderKey, error) {
-	row := q.db.QueryRowContext(ctx, getUserAIProviderKeyByProviderID, arg.UserID, arg.AIProviderID)
-	var i UserAIProviderKey
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.AIProviderID,
-		&i.APIKey,
-		&i.ApiKeyKeyID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
+	return err
 }
 
-const getUserAIProviderKeys = `-- name: GetUserAIProviderKeys :many
-SELECT
-    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
-FROM
-    user_ai_provider_keys
-ORDER BY
-    user_id ASC,
-    ai_provider_id ASC,
-    created_at ASC,
-    id ASC
+const updateTemplateVersionExternalAuthProvidersByJobID = `-- name: UpdateTemplateVersionExternalAuthProvidersByJobID :exec
+UPDATE
+	template_versions
+SET
+	external_auth_providers = $2,
+	updated_at = $3
+WHERE
+	job_id = $1
 `
 
-// GetUserAIProviderKeys is used by dbcrypt key rotation. Request paths should use
-// user-scoped lookups instead of this bulk accessor.
-func (q *sqlQuerier) GetUserAIProviderKeys(ctx context.Context) ([]UserAIProviderKey, error) {
-	rows, err := q.db.QueryContext(ctx, getUserAIProviderKeys)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []UserAIProviderKey
-	for rows.Next() {
-		var i UserAIProviderKey
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.AIProviderID,
-			&i.APIKey,
-			&i.ApiKeyKeyID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type UpdateTemplateVersionExternalAuthProvidersByJobIDParams struct {
+	JobID                 uuid.UUID       `db:"job_id" json:"job_id"`
+	ExternalAuthProviders json.RawMessage `db:"external_auth_providers" json:"external_auth_providers"`
+	UpdatedAt             time.Time       `db:"updated_at" json:"updated_at"`
 }
 
-const getUserAIProviderKeysByUserID = `-- name: GetUserAIProviderKeysByUserID :many
+func (q *sqlQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionExternalAuthProvidersByJobID, arg.JobID, arg.ExternalAuthProviders, arg.UpdatedAt)
+	return err
+}
+
+const updateTemplateVersionFlagsByJobID = `-- name: UpdateTemplateVersionFlagsByJobID :exec
+UPDATE
+	template_versions
+SET
+	has_ai_task = $2,
+	has_external_agent = $3,
+	updated_at = $4
+WHERE
+	job_id = $1
+`
+
+type UpdateTemplateVersionFlagsByJobIDParams struct {
+	JobID            uuid.UUID    `db:"job_id" json:"job_id"`
+	HasAITask        sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
+	HasExternalAgent sql.NullBool `db:"has_external_agent" json:"has_external_agent"`
+	UpdatedAt        time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionFlagsByJobID(ctx context.Context, arg UpdateTemplateVersionFlagsByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionFlagsByJobID,
+		arg.JobID,
+		arg.HasAITask,
+		arg.HasExternalAgent,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const claimTemplateVersionScans = `-- name: ClaimTemplateVersionScans :many
+INSERT INTO template_version_scans (template_version_id, status, started_at)
 SELECT
-    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
+	template_versions.id,
+	'running'::template_version_scan_status,
+	$1::timestamptz
 FROM
-    user_ai_provider_keys
+	template_versions
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = template_versions.job_id
+LEFT JOIN
+	template_version_scans ON template_version_scans.template_version_id = template_versions.id
 WHERE
-    user_id = $1::uuid
+	provisioner_jobs.job_status = 'succeeded'::provisioner_job_status
+	AND provisioner_jobs.completed_at >= $2::timestamptz
+	AND (
+		template_version_scans.template_version_id IS NULL
+		OR (
+			template_version_scans.status = 'running'::template_version_scan_status
+			AND template_version_scans.started_at < $3::timestamptz
+		)
+	)
 ORDER BY
-    ai_provider_id ASC,
-    created_at ASC,
-    id ASC
+	provisioner_jobs.completed_at
+LIMIT
+	$4::int
+ON CONFLICT (template_version_id) DO UPDATE SET
+	started_at = EXCLUDED.started_at
+WHERE
+	template_version_scans.status = 'running'::template_version_scan_status
+	AND template_version_scans.started_at < $3::timestamptz
+RETURNING template_version_id, status, findings, error, started_at, completed_at
 `
 
-func (q *sqlQuerier) GetUserAIProviderKeysByUserID(ctx context.Context, userID uuid.UUID) ([]UserAIProviderKey, error) {
-	rows, err := q.db.QueryContext(ctx, getUserAIProviderKeysByUserID, userID)
+type ClaimTemplateVersionScansParams struct {
+	Now            time.Time `db:"now" json:"now"`
+	CompletedAfter time.Time `db:"completed_after" json:"completed_after"`
+	StaleBefore    time.Time `db:"stale_before" json:"stale_before"`
+	MaxScans       int32     `db:"max_scans" json:"max_scans"`
+}
+
+// Claims template versions whose import job succeeded after completed_after
+// and that have not been scanned yet, or whose scan was claimed before
+// stale_before and never finished. Concurrent callers never claim the same
+// template version.
+func (q *sqlQuerier) ClaimTemplateVersionScans(ctx context.Context, arg ClaimTemplateVersionScansParams) ([]TemplateVersionScan, error) {
+	rows, err := q.db.QueryContext(ctx, claimTemplateVersionScans,
+		arg.Now,
+		arg.CompletedAfter,
+		arg.StaleBefore,
+		arg.MaxScans,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UserAIProviderKey
+	var items []TemplateVersionScan
 	for rows.Next() {
-		var i UserAIProviderKey
+		var i TemplateVersionScan
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.AIProviderID,
-			&i.APIKey,
-			&i.ApiKeyKeyID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.TemplateVersionID,
+			&i.Status,
+			&i.Findings,
+			&i.Error,
+			&i.StartedAt,
+			&i.CompletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -28938,180 +32015,170 @@ func (q *sqlQuerier) GetUserAIProviderKeysByUserID(ctx context.Context, userID u
 	return items, nil
 }
 
-const updateEncryptedUserAIProviderKey = `-- name: UpdateEncryptedUserAIProviderKey :one
-UPDATE
-    user_ai_provider_keys
-SET
-    api_key = $1::text,
-    api_key_key_id = $2::text,
-    updated_at = NOW()
+const getTemplateVersionScanByTemplateVersionID = `-- name: GetTemplateVersionScanByTemplateVersionID :one
+SELECT
+	template_version_id, status, findings, error, started_at, completed_at
+FROM
+	template_version_scans
 WHERE
-    id = $3::uuid
-RETURNING
-    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
+	template_version_id = $1
 `
 
-type UpdateEncryptedUserAIProviderKeyParams struct {
-	APIKey      string         `db:"api_key" json:"api_key"`
-	ApiKeyKeyID sql.NullString `db:"api_key_key_id" json:"api_key_key_id"`
-	ID          uuid.UUID      `db:"id" json:"id"`
-}
-
-func (q *sqlQuerier) UpdateEncryptedUserAIProviderKey(ctx context.Context, arg UpdateEncryptedUserAIProviderKeyParams) (UserAIProviderKey, error) {
-	row := q.db.QueryRowContext(ctx, updateEncryptedUserAIProviderKey, arg.APIKey, arg.ApiKeyKeyID, arg.ID)
-	var i UserAIProviderKey
+func (q *sqlQuerier) GetTemplateVersionScanByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (TemplateVersionScan, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionScanByTemplateVersionID, templateVersionID)
+	var i TemplateVersionScan
 	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.AIProviderID,
-		&i.APIKey,
-		&i.ApiKeyKeyID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.TemplateVersionID,
+		&i.Status,
+		&i.Findings,
+		&i.Error,
+		&i.StartedAt,
+		&i.CompletedAt,
 	)
 	return i, err
 }
 
-const updateUserAIProviderKey = `-- name: UpdateUserAIProviderKey :one
+const updateTemplateVersionScanByTemplateVersionID = `-- name: UpdateTemplateVersionScanByTemplateVersionID :exec
 UPDATE
-    user_ai_provider_keys
+	template_version_scans
 SET
-    api_key = $1::text,
-    api_key_key_id = $2::text,
-    updated_at = NOW()
+	status = $1,
+	findings = $2,
+	error = $3,
+	completed_at = $4
 WHERE
-    user_id = $3::uuid
-    AND ai_provider_id = $4::uuid
-RETURNING
-    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
+	template_version_id = $5
 `
 
-type UpdateUserAIProviderKeyParams struct {
-	APIKey       string         `db:"api_key" json:"api_key"`
-	ApiKeyKeyID  sql.NullString `db:"api_key_key_id" json:"api_key_key_id"`
-	UserID       uuid.UUID      `db:"user_id" json:"user_id"`
-	AIProviderID uuid.UUID      `db:"ai_provider_id" json:"ai_provider_id"`
+type UpdateTemplateVersionScanByTemplateVersionIDParams struct {
+	Status            TemplateVersionScanStatus `db:"status" json:"status"`
+	Findings          json.RawMessage           `db:"findings" json:"findings"`
+	Error             string                    `db:"error" json:"error"`
+	CompletedAt       sql.NullTime              `db:"completed_at" json:"completed_at"`
+	TemplateVersionID uuid.UUID                 `db:"template_version_id" json:"template_version_id"`
 }
 
-func (q *sqlQuerier) UpdateUserAIProviderKey(ctx context.Context, arg UpdateUserAIProviderKeyParams) (UserAIProviderKey, error) {
-	row := q.db.QueryRowContext(ctx, updateUserAIProviderKey,
-		arg.APIKey,
-		arg.ApiKeyKeyID,
-		arg.UserID,
-		arg.AIProviderID,
+func (q *sqlQuerier) UpdateTemplateVersionScanByTemplateVersionID(ctx context.Context, arg UpdateTemplateVersionScanByTemplateVersionIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionScanByTemplateVersionID,
+		arg.Status,
+		arg.Findings,
+		arg.Error,
+		arg.CompletedAt,
+		arg.TemplateVersionID,
 	)
-	var i UserAIProviderKey
+	return err
+}
+
+const getTemplateVersionTerraformValues = `-- name: GetTemplateVersionTerraformValues :one
+SELECT
+	template_version_terraform_values.template_version_id, template_version_terraform_values.updated_at, template_version_terraform_values.cached_plan, template_version_terraform_values.cached_module_files, template_version_terraform_values.provisionerd_version
+FROM
+	template_version_terraform_values
+WHERE
+	template_version_terraform_values.template_version_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (TemplateVersionTerraformValue, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionTerraformValues, templateVersionID)
+	var i TemplateVersionTerraformValue
 	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.AIProviderID,
-		&i.APIKey,
-		&i.ApiKeyKeyID,
-		&i.CreatedAt,
+		&i.TemplateVersionID,
 		&i.UpdatedAt,
+		&i.CachedPlan,
+		&i.CachedModuleFiles,
+		&i.ProvisionerdVersion,
 	)
 	return i, err
 }
 
-const upsertUserAIProviderKey = `-- name: UpsertUserAIProviderKey :one
-INSERT INTO user_ai_provider_keys (
-    id,
-    user_id,
-    ai_provider_id,
-    api_key,
-    api_key_key_id,
-    created_at,
-    updated_at
-) VALUES (
-    $1::uuid,
-    $2::uuid,
-    $3::uuid,
-    $4::text,
-    $5::text,
-    $6::timestamptz,
-    $7::timestamptz
+const hasTemplateVersionsUsingCachedModuleFileInOrg = `-- name: HasTemplateVersionsUsingCachedModuleFileInOrg :one
+SELECT EXISTS (
+	SELECT 1
+	FROM template_version_terraform_values tvtv
+	JOIN template_versions tv
+		ON tv.id = tvtv.template_version_id
+	WHERE tvtv.cached_module_files = $1::uuid
+		AND tv.organization_id = $2::uuid
 )
-ON CONFLICT (user_id, ai_provider_id) DO UPDATE
-SET
-    api_key = EXCLUDED.api_key,
-    api_key_key_id = EXCLUDED.api_key_key_id,
-    updated_at = EXCLUDED.updated_at
-RETURNING
-    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
 `
 
-type UpsertUserAIProviderKeyParams struct {
-	ID           uuid.UUID      `db:"id" json:"id"`
-	UserID       uuid.UUID      `db:"user_id" json:"user_id"`
-	AIProviderID uuid.UUID      `db:"ai_provider_id" json:"ai_provider_id"`
-	APIKey       string         `db:"api_key" json:"api_key"`
-	ApiKeyKeyID  sql.NullString `db:"api_key_key_id" json:"api_key_key_id"`
-	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
+type HasTemplateVersionsUsingCachedModuleFileInOrgParams struct {
+	FileID         uuid.UUID `db:"file_id" json:"file_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
 }
 
-// UpsertUserAIProviderKey preserves the original id and created_at when the
-// user/provider pair already exists. On conflict, callers provide id and
-// created_at for the insert path only.
-func (q *sqlQuerier) UpsertUserAIProviderKey(ctx context.Context, arg UpsertUserAIProviderKeyParams) (UserAIProviderKey, error) {
-	row := q.db.QueryRowContext(ctx, upsertUserAIProviderKey,
-		arg.ID,
-		arg.UserID,
-		arg.AIProviderID,
-		arg.APIKey,
-		arg.ApiKeyKeyID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-	)
-	var i UserAIProviderKey
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.AIProviderID,
-		&i.APIKey,
-		&i.ApiKeyKeyID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+// Reports whether the given file is referenced as cached module files by any
+// template version in the given organization. Used to authorize provisioner
+// module-file downloads so a daemon cannot read another organization's cached
+// Terraform module source.
+func (q *sqlQuerier) HasTemplateVersionsUsingCachedModuleFileInOrg(ctx context.Context, arg HasTemplateVersionsUsingCachedModuleFileInOrgParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasTemplateVersionsUsingCachedModuleFileInOrg, arg.FileID, arg.OrganizationID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
 }
 
-const countOIDCLinkedIDsByIssuer = `-- name: CountOIDCLinkedIDsByIssuer :many
-SELECT
-	(CASE
-		WHEN user_links.linked_id = '' THEN ''
-		ELSE split_part(user_links.linked_id, '||', 1)
-	END)::text AS issuer_prefix,
-	COUNT(*)::int AS count
-FROM
-	user_links
-INNER JOIN
-	users ON user_links.user_id = users.id
-WHERE
-	user_links.login_type = 'oidc'
-	AND users.deleted = false
-GROUP BY issuer_prefix
+const insertTemplateVersionTerraformValuesByJobID = `-- name: InsertTemplateVersionTerraformValuesByJobID :exec
+INSERT INTO
+	template_version_terraform_values (
+		template_version_id,
+		cached_plan,
+		cached_module_files,
+		updated_at,
+	    provisionerd_version
+	)
+VALUES
+	(
+		(select id from template_versions where job_id = $1),
+		$2,
+		$3,
+		$4,
+		$5
+	)
 `
 
-type CountOIDCLinkedIDsByIssuerRow struct {
-	IssuerPrefix string `db:"issuer_prefix" json:"issuer_prefix"`
-	Count        int32  `db:"count" json:"count"`
+type InsertTemplateVersionTerraformValuesByJobIDParams struct {
+	JobID               uuid.UUID       `db:"job_id" json:"job_id"`
+	CachedPlan          json.RawMessage `db:"cached_plan" json:"cached_plan"`
+	CachedModuleFiles   uuid.NullUUID   `db:"cached_module_files" json:"cached_module_files"`
+	UpdatedAt           time.Time       `db:"updated_at" json:"updated_at"`
+	ProvisionerdVersion string          `db:"provisionerd_version" json:"provisionerd_version"`
 }
 
-// Groups OIDC user links by their issuer prefix (the part before "||" in
-// linked_id) and returns a count for each. Empty linked_ids are reported
-// with an empty issuer_prefix. Used for analysis before resetting
-// mismatched links.
-func (q *sqlQuerier) CountOIDCLinkedIDsByIssuer(ctx context.Context) ([]CountOIDCLinkedIDsByIssuerRow, error) {
-	rows, err := q.db.QueryContext(ctx, countOIDCLinkedIDsByIssuer)
+func (q *sqlQuerier) InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg InsertTemplateVersionTerraformValuesByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplateVersionTerraformValuesByJobID,
+		arg.JobID,
+		arg.CachedPlan,
+		arg.CachedModuleFiles,
+		arg.UpdatedAt,
+		arg.ProvisionerdVersion,
+	)
+	return err
+}
+
+const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
+SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1 ORDER BY name
+`
+
+func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionVariables, templateVersionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CountOIDCLinkedIDsByIssuerRow
+	var items []TemplateVersionVariable
 	for rows.Next() {
-		var i CountOIDCLinkedIDsByIssuerRow
-		if err := rows.Scan(&i.IssuerPrefix, &i.Count); err != nil {
+		var i TemplateVersionVariable
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.Value,
+			&i.DefaultValue,
+			&i.Required,
+			&i.Sensitive,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -29125,90 +32192,159 @@ func (q *sqlQuerier) CountOIDCLinkedIDsByIssuer(ctx context.Context) ([]CountOID
 	return items, nil
 }
 
-const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
-SELECT
-	user_links.user_id, user_links.login_type, user_links.linked_id, user_links.oauth_access_token, user_links.oauth_refresh_token, user_links.oauth_expiry, user_links.oauth_access_token_key_id, user_links.oauth_refresh_token_key_id, user_links.claims
-FROM
-	user_links
-INNER JOIN
-	users ON user_links.user_id = users.id
-WHERE
-	linked_id = $1
-	AND
-	deleted = false
+const insertTemplateVersionVariable = `-- name: InsertTemplateVersionVariable :one
+INSERT INTO
+    template_version_variables (
+        template_version_id,
+        name,
+        description,
+        type,
+        value,
+        default_value,
+        required,
+        sensitive
+    )
+VALUES
+    (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8
+    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
 `
 
-func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, linkedID)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
-	)
-	return i, err
+type InsertTemplateVersionVariableParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name              string    `db:"name" json:"name"`
+	Description       string    `db:"description" json:"description"`
+	Type              string    `db:"type" json:"type"`
+	Value             string    `db:"value" json:"value"`
+	DefaultValue      string    `db:"default_value" json:"default_value"`
+	Required          bool      `db:"required" json:"required"`
+	Sensitive         bool      `db:"sensitive" json:"sensitive"`
 }
 
-const getUserLinkByUserIDLoginType = `-- name: GetUserLinkByUserIDLoginType :one
-SELECT
-	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
-FROM
-	user_links
-WHERE
-	user_id = $1 AND login_type = $2
+func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionVariable,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Description,
+		arg.Type,
+		arg.Value,
+		arg.DefaultValue,
+		arg.Required,
+		arg.Sensitive,
+	)
+	var i TemplateVersionVariable
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Description,
+		&i.Type,
+		&i.Value,
+		&i.DefaultValue,
+		&i.Required,
+		&i.Sensitive,
+	)
+	return i, err
+}
+
+const getTemplateVersionWorkspaceTags = `-- name: GetTemplateVersionWorkspaceTags :many
+SELECT template_version_id, key, value FROM template_version_workspace_tags WHERE template_version_id = $1 ORDER BY LOWER(key) ASC
 `
 
-type GetUserLinkByUserIDLoginTypeParams struct {
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
+func (q *sqlQuerier) GetTemplateVersionWorkspaceTags(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionWorkspaceTag, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionWorkspaceTags, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionWorkspaceTag
+	for rows.Next() {
+		var i TemplateVersionWorkspaceTag
+		if err := rows.Scan(&i.TemplateVersionID, &i.Key, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
-	)
+const insertTemplateVersionWorkspaceTag = `-- name: InsertTemplateVersionWorkspaceTag :one
+INSERT INTO
+    template_version_workspace_tags (
+        template_version_id,
+        key,
+        value
+    )
+VALUES
+    (
+        $1,
+        $2,
+        $3
+    ) RETURNING template_version_id, key, value
+`
+
+type InsertTemplateVersionWorkspaceTagParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Key               string    `db:"key" json:"key"`
+	Value             string    `db:"value" json:"value"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionWorkspaceTag(ctx context.Context, arg InsertTemplateVersionWorkspaceTagParams) (TemplateVersionWorkspaceTag, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionWorkspaceTag, arg.TemplateVersionID, arg.Key, arg.Value)
+	var i TemplateVersionWorkspaceTag
+	err := row.Scan(&i.TemplateVersionID, &i.Key, &i.Value)
 	return i, err
 }
 
-const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
-SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims FROM user_links WHERE user_id = $1
+const getInProgressTemplateWorkspaceRestarts = `-- name: GetInProgressTemplateWorkspaceRestarts :many
+SELECT
+	id, template_id, template_version_id, batch_size, batch_interval, honor_quiet_hours, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stats_since, last_batch_at, completed_at
+FROM
+	template_workspace_restarts
+WHERE
+	status = 'in_progress'::template_workspace_restart_status
+ORDER BY
+	created_at ASC
 `
 
-func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
-	rows, err := q.db.QueryContext(ctx, getUserLinksByUserID, userID)
+func (q *sqlQuerier) GetInProgressTemplateWorkspaceRestarts(ctx context.Context) ([]TemplateWorkspaceRestart, error) {
+	rows, err := q.db.QueryContext(ctx, getInProgressTemplateWorkspaceRestarts)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UserLink
+	var items []TemplateWorkspaceRestart
 	for rows.Next() {
-		var i UserLink
+		var i TemplateWorkspaceRestart
 		if err := rows.Scan(
-			&i.UserID,
-			&i.LoginType,
-			&i.LinkedID,
-			&i.OAuthAccessToken,
-			&i.OAuthRefreshToken,
-			&i.OAuthExpiry,
-			&i.OAuthAccessTokenKeyID,
-			&i.OAuthRefreshTokenKeyID,
-			&i.Claims,
+			&i.ID,
+			&i.TemplateID,
+			&i.TemplateVersionID,
+			&i.BatchSize,
+			&i.BatchInterval,
+			&i.HonorQuietHours,
+			&i.MaxFailureRate,
+			&i.MinBuilds,
+			&i.Status,
+			&i.StatusMessage,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StatsSince,
+			&i.LastBatchAt,
+			&i.CompletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -29223,113 +32359,174 @@ func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID)
 	return items, nil
 }
 
-const insertUserLink = `-- name: InsertUserLink :one
-INSERT INTO
-	user_links (
-		user_id,
-		login_type,
-		linked_id,
-		oauth_access_token,
-		oauth_access_token_key_id,
-		oauth_refresh_token,
-		oauth_refresh_token_key_id,
-		oauth_expiry,
-		claims
-	)
-VALUES
-	( $1, $2, $3, $4, $5, $6, $7, $8, $9 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+const getTemplateWorkspaceRestartBuildStats = `-- name: GetTemplateWorkspaceRestartBuildStats :one
+SELECT
+	COUNT(*) AS restarted,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status = 'succeeded'::provisioner_job_status) AS succeeded,
+	COUNT(*) FILTER (
+		WHERE template_workspace_restart_builds.error != ''
+		OR provisioner_jobs.job_status IN ('failed'::provisioner_job_status, 'canceled'::provisioner_job_status)
+	) AS failed,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status IN ('pending'::provisioner_job_status, 'running'::provisioner_job_status, 'canceling'::provisioner_job_status)) AS pending,
+	COUNT(*) FILTER (
+		WHERE template_workspace_restart_builds.created_at >= $1
+		AND (
+			template_workspace_restart_builds.error != ''
+			OR provisioner_jobs.job_status IN ('succeeded'::provisioner_job_status, 'failed'::provisioner_job_status, 'canceled'::provisioner_job_status)
+		)
+	) AS completed_since,
+	COUNT(*) FILTER (
+		WHERE template_workspace_restart_builds.created_at >= $1
+		AND (
+			template_workspace_restart_builds.error != ''
+			OR provisioner_jobs.job_status IN ('failed'::provisioner_job_status, 'canceled'::provisioner_job_status)
+		)
+	) AS failed_since
+FROM
+	template_workspace_restart_builds
+LEFT JOIN
+	workspace_builds ON workspace_builds.id = template_workspace_restart_builds.workspace_build_id
+LEFT JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	template_workspace_restart_builds.restart_id = $2
 `
 
-type InsertUserLinkParams struct {
-	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
-	LoginType              LoginType      `db:"login_type" json:"login_type"`
-	LinkedID               string         `db:"linked_id" json:"linked_id"`
-	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
-	Claims                 UserLinkClaims `db:"claims" json:"claims"`
+type GetTemplateWorkspaceRestartBuildStatsParams struct {
+	Since     time.Time `db:"since" json:"since"`
+	RestartID uuid.UUID `db:"restart_id" json:"restart_id"`
 }
 
-func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, insertUserLink,
-		arg.UserID,
-		arg.LoginType,
-		arg.LinkedID,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-		arg.Claims,
+type GetTemplateWorkspaceRestartBuildStatsRow struct {
+	Restarted      int64 `db:"restarted" json:"restarted"`
+	Succeeded      int64 `db:"succeeded" json:"succeeded"`
+	Failed         int64 `db:"failed" json:"failed"`
+	Pending        int64 `db:"pending" json:"pending"`
+	CompletedSince int64 `db:"completed_since" json:"completed_since"`
+	FailedSince    int64 `db:"failed_since" json:"failed_since"`
+}
+
+// Counts the builds of a restart by the status of their provisioner job.
+// Builds that could not be created count as failed. The completed and failed
+// builds since the given time are used to compute the failure rate.
+func (q *sqlQuerier) GetTemplateWorkspaceRestartBuildStats(ctx context.Context, arg GetTemplateWorkspaceRestartBuildStatsParams) (GetTemplateWorkspaceRestartBuildStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateWorkspaceRestartBuildStats, arg.Since, arg.RestartID)
+	var i GetTemplateWorkspaceRestartBuildStatsRow
+	err := row.Scan(
+		&i.Restarted,
+		&i.Succeeded,
+		&i.Failed,
+		&i.Pending,
+		&i.CompletedSince,
+		&i.FailedSince,
 	)
-	var i UserLink
+	return i, err
+}
+
+const getTemplateWorkspaceRestartByID = `-- name: GetTemplateWorkspaceRestartByID :one
+SELECT
+	id, template_id, template_version_id, batch_size, batch_interval, honor_quiet_hours, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stats_since, last_batch_at, completed_at
+FROM
+	template_workspace_restarts
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetTemplateWorkspaceRestartByID(ctx context.Context, id uuid.UUID) (TemplateWorkspaceRestart, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateWorkspaceRestartByID, id)
+	var i TemplateWorkspaceRestart
 	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.BatchSize,
+		&i.BatchInterval,
+		&i.HonorQuietHours,
+		&i.MaxFailureRate,
+		&i.MinBuilds,
+		&i.Status,
+		&i.StatusMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StatsSince,
+		&i.LastBatchAt,
+		&i.CompletedAt,
 	)
 	return i, err
 }
 
-const oIDCClaimFieldValues = `-- name: OIDCClaimFieldValues :many
+const getTemplateWorkspaceRestartCandidates = `-- name: GetTemplateWorkspaceRestartCandidates :many
 SELECT
-	-- DISTINCT to remove duplicates
-	DISTINCT jsonb_array_elements_text(CASE
-		-- When the type is an array, filter out any non-string elements.
-		-- This is to keep the return type consistent.
-		WHEN jsonb_typeof(claims->'merged_claims'->$1::text) = 'array' THEN
-			(
-				SELECT
-					jsonb_agg(element)
-				FROM
-					jsonb_array_elements(claims->'merged_claims'->$1::text) AS element
-				WHERE
-					-- Filtering out non-string elements
-					jsonb_typeof(element) = 'string'
-			)
-		-- Some IDPs return a single string instead of an array of strings.
-		WHEN jsonb_typeof(claims->'merged_claims'->$1::text) = 'string' THEN
-			jsonb_build_array(claims->'merged_claims'->$1::text)
-	END)
+	workspaces.id,
+	workspaces.owner_id
 FROM
-	user_links
+	workspaces
+JOIN LATERAL (
+	SELECT
+		workspace_builds.template_version_id,
+		workspace_builds.transition,
+		provisioner_jobs.job_status
+	FROM
+		workspace_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	WHERE
+		workspace_builds.workspace_id = workspaces.id
+	ORDER BY
+		workspace_builds.build_number DESC
+	LIMIT 1
+) latest_build ON TRUE
 WHERE
-	-- IDP sync only supports string and array (of string) types
-	jsonb_typeof(claims->'merged_claims'->$1::text) = ANY(ARRAY['string', 'array'])
-	AND login_type = 'oidc'
-	AND CASE
-		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
-			user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = $2)
-		ELSE true
-	END
+	workspaces.template_id = $1
+	AND workspaces.deleted = false
+	AND workspaces.dormant_at IS NULL
+	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The prebuilds system user.
+	AND latest_build.template_version_id != $2
+	AND latest_build.transition = 'start'::workspace_transition
+	AND latest_build.job_status = 'succeeded'::provisioner_job_status
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			template_workspace_restart_builds
+		WHERE
+			template_workspace_restart_builds.restart_id = $3
+			AND template_workspace_restart_builds.workspace_id = workspaces.id
+	)
+ORDER BY
+	workspaces.last_used_at ASC,
+	workspaces.id ASC
 `
 
-type OIDCClaimFieldValuesParams struct {
-	ClaimField     string    `db:"claim_field" json:"claim_field"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+type GetTemplateWorkspaceRestartCandidatesParams struct {
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	RestartID         uuid.UUID `db:"restart_id" json:"restart_id"`
 }
 
-func (q *sqlQuerier) OIDCClaimFieldValues(ctx context.Context, arg OIDCClaimFieldValuesParams) ([]string, error) {
-	rows, err := q.db.QueryContext(ctx, oIDCClaimFieldValues, arg.ClaimField, arg.OrganizationID)
+type GetTemplateWorkspaceRestartCandidatesRow struct {
+	ID      uuid.UUID `db:"id" json:"id"`
+	OwnerID uuid.UUID `db:"owner_id" json:"owner_id"`
+}
+
+// Returns the running workspaces of the template that are not on the given
+// version and have not been restarted by the restart yet. The least recently
+// used workspaces are returned first, since they are the least likely to be
+// in use. Dormant workspaces and prebuilt workspaces are skipped.
+func (q *sqlQuerier) GetTemplateWorkspaceRestartCandidates(ctx context.Context, arg GetTemplateWorkspaceRestartCandidatesParams) ([]GetTemplateWorkspaceRestartCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateWorkspaceRestartCandidates, arg.TemplateID, arg.TemplateVersionID, arg.RestartID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []GetTemplateWorkspaceRestartCandidatesRow
 	for rows.Next() {
-		var jsonb_array_elements_text string
-		if err := rows.Scan(&jsonb_array_elements_text); err != nil {
+		var i GetTemplateWorkspaceRestartCandidatesRow
+		if err := rows.Scan(&i.ID, &i.OwnerID); err != nil {
 			return nil, err
 		}
-		items = append(items, jsonb_array_elements_text)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -29340,39 +32537,47 @@ func (q *sqlQuerier) OIDCClaimFieldValues(ctx context.Context, arg OIDCClaimFiel
 	return items, nil
 }
 
-const oIDCClaimFields = `-- name: OIDCClaimFields :many
+const getTemplateWorkspaceRestartsByTemplateID = `-- name: GetTemplateWorkspaceRestartsByTemplateID :many
 SELECT
-	DISTINCT jsonb_object_keys(claims->'merged_claims')
+	id, template_id, template_version_id, batch_size, batch_interval, honor_quiet_hours, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stats_since, last_batch_at, completed_at
 FROM
-	user_links
+	template_workspace_restarts
 WHERE
-    -- Only return rows where the top level key exists
-	claims ? 'merged_claims' AND
-    -- 'null' is the default value for the id_token_claims field
-	-- jsonb 'null' is not the same as SQL NULL. Strip these out.
-	jsonb_typeof(claims->'merged_claims') != 'null' AND
-	login_type = 'oidc'
-	AND CASE WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
-		user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = $1)
-		ELSE true
-	END
+	template_id = $1
+ORDER BY
+	created_at DESC
 `
 
-// OIDCClaimFields returns a list of distinct keys in the the merged_claims fields.
-// This query is used to generate the list of available sync fields for idp sync settings.
-func (q *sqlQuerier) OIDCClaimFields(ctx context.Context, organizationID uuid.UUID) ([]string, error) {
-	rows, err := q.db.QueryContext(ctx, oIDCClaimFields, organizationID)
+func (q *sqlQuerier) GetTemplateWorkspaceRestartsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateWorkspaceRestart, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateWorkspaceRestartsByTemplateID, templateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []TemplateWorkspaceRestart
 	for rows.Next() {
-		var jsonb_object_keys string
-		if err := rows.Scan(&jsonb_object_keys); err != nil {
+		var i TemplateWorkspaceRestart
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.TemplateVersionID,
+			&i.BatchSize,
+			&i.BatchInterval,
+			&i.HonorQuietHours,
+			&i.MaxFailureRate,
+			&i.MinBuilds,
+			&i.Status,
+			&i.StatusMessage,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StatsSince,
+			&i.LastBatchAt,
+			&i.CompletedAt,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, jsonb_object_keys)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -29383,388 +32588,563 @@ func (q *sqlQuerier) OIDCClaimFields(ctx context.Context, organizationID uuid.UU
 	return items, nil
 }
 
-const unlinkOIDCUsersByIssuerMismatch = `-- name: UnlinkOIDCUsersByIssuerMismatch :execrows
-UPDATE user_links
-SET linked_id = ''
-FROM users
-WHERE user_links.user_id = users.id
-	AND user_links.login_type = 'oidc'
-	AND user_links.linked_id != ''
-	AND NOT starts_with(user_links.linked_id, $1)
-	AND users.deleted = false
+const insertTemplateWorkspaceRestart = `-- name: InsertTemplateWorkspaceRestart :one
+INSERT INTO
+	template_workspace_restarts (
+		id,
+		template_id,
+		template_version_id,
+		batch_size,
+		batch_interval,
+		honor_quiet_hours,
+		max_failure_rate,
+		min_builds,
+		created_by,
+		created_at,
+		updated_at,
+		stats_since
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8,
+	$9,
+	$10,
+	$10,
+	$10
+)
+RETURNING id, template_id, template_version_id, batch_size, batch_interval, honor_quiet_hours, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stats_since, last_batch_at, completed_at
 `
 
-// Resets linked_id to ” for OIDC links where the linked_id is non-empty
-// and does not begin with the expected issuer prefix. This allows users to
-// re-authenticate under a new OIDC provider.
-func (q *sqlQuerier) UnlinkOIDCUsersByIssuerMismatch(ctx context.Context, expectedPrefix string) (int64, error) {
-	result, err := q.db.ExecContext(ctx, unlinkOIDCUsersByIssuerMismatch, expectedPrefix)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
+type InsertTemplateWorkspaceRestartParams struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	BatchSize         int32     `db:"batch_size" json:"batch_size"`
+	BatchInterval     int64     `db:"batch_interval" json:"batch_interval"`
+	HonorQuietHours   bool      `db:"honor_quiet_hours" json:"honor_quiet_hours"`
+	MaxFailureRate    float64   `db:"max_failure_rate" json:"max_failure_rate"`
+	MinBuilds         int32     `db:"min_builds" json:"min_builds"`
+	CreatedBy         uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
 }
 
-const updateUserLink = `-- name: UpdateUserLink :one
+func (q *sqlQuerier) InsertTemplateWorkspaceRestart(ctx context.Context, arg InsertTemplateWorkspaceRestartParams) (TemplateWorkspaceRestart, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateWorkspaceRestart,
+		arg.ID,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.BatchSize,
+		arg.BatchInterval,
+		arg.HonorQuietHours,
+		arg.MaxFailureRate,
+		arg.MinBuilds,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i TemplateWorkspaceRestart
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.BatchSize,
+		&i.BatchInterval,
+		&i.HonorQuietHours,
+		&i.MaxFailureRate,
+		&i.MinBuilds,
+		&i.Status,
+		&i.StatusMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StatsSince,
+		&i.LastBatchAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const insertTemplateWorkspaceRestartBuild = `-- name: InsertTemplateWorkspaceRestartBuild :exec
+INSERT INTO
+	template_workspace_restart_builds (
+		restart_id,
+		workspace_id,
+		workspace_build_id,
+		error,
+		created_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5
+)
+`
+
+type InsertTemplateWorkspaceRestartBuildParams struct {
+	RestartID        uuid.UUID     `db:"restart_id" json:"restart_id"`
+	WorkspaceID      uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.NullUUID `db:"workspace_build_id" json:"workspace_build_id"`
+	Error            string        `db:"error" json:"error"`
+	CreatedAt        time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertTemplateWorkspaceRestartBuild(ctx context.Context, arg InsertTemplateWorkspaceRestartBuildParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplateWorkspaceRestartBuild,
+		arg.RestartID,
+		arg.WorkspaceID,
+		arg.WorkspaceBuildID,
+		arg.Error,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const resumeTemplateWorkspaceRestart = `-- name: ResumeTemplateWorkspaceRestart :one
 UPDATE
-	user_links
+	template_workspace_restarts
 SET
-	oauth_access_token = $1,
-	oauth_access_token_key_id = $2,
-	oauth_refresh_token = $3,
-	oauth_refresh_token_key_id = $4,
-	oauth_expiry = $5,
-	claims = $6
+	status = 'in_progress'::template_workspace_restart_status,
+	status_message = '',
+	updated_at = $1,
+	stats_since = $1
 WHERE
-	user_id = $7 AND login_type = $8 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+	id = $2
+	AND status = 'paused'::template_workspace_restart_status
+RETURNING id, template_id, template_version_id, batch_size, batch_interval, honor_quiet_hours, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stats_since, last_batch_at, completed_at
 `
 
-type UpdateUserLinkParams struct {
-	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
-	Claims                 UserLinkClaims `db:"claims" json:"claims"`
-	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
-	LoginType              LoginType      `db:"login_type" json:"login_type"`
+type ResumeTemplateWorkspaceRestartParams struct {
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLink,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-		arg.Claims,
-		arg.UserID,
-		arg.LoginType,
-	)
-	var i UserLink
+// Resumes a paused restart. Failures before the restart was resumed no longer
+// count towards its failure rate.
+func (q *sqlQuerier) ResumeTemplateWorkspaceRestart(ctx context.Context, arg ResumeTemplateWorkspaceRestartParams) (TemplateWorkspaceRestart, error) {
+	row := q.db.QueryRowContext(ctx, resumeTemplateWorkspaceRestart, arg.UpdatedAt, arg.ID)
+	var i TemplateWorkspaceRestart
 	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.BatchSize,
+		&i.BatchInterval,
+		&i.HonorQuietHours,
+		&i.MaxFailureRate,
+		&i.MinBuilds,
+		&i.Status,
+		&i.StatusMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StatsSince,
+		&i.LastBatchAt,
+		&i.CompletedAt,
 	)
 	return i, err
 }
 
-const updateUserLinkedID = `-- name: UpdateUserLinkedID :one
+const updateTemplateWorkspaceRestartLastBatchAt = `-- name: UpdateTemplateWorkspaceRestartLastBatchAt :exec
 UPDATE
-	user_links
+	template_workspace_restarts
 SET
-	linked_id = $1
+	last_batch_at = $1,
+	updated_at = $1
 WHERE
-	user_id = $2 AND login_type = $3 AND linked_id = '' RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+	id = $2
+	AND status = 'in_progress'::template_workspace_restart_status
 `
 
-type UpdateUserLinkedIDParams struct {
-	LinkedID  string    `db:"linked_id" json:"linked_id"`
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
+type UpdateTemplateWorkspaceRestartLastBatchAtParams struct {
+	LastBatchAt sql.NullTime `db:"last_batch_at" json:"last_batch_at"`
+	ID          uuid.UUID    `db:"id" json:"id"`
 }
 
-// Backfills linked_id for legacy user_links that were created before
-// linked_id tracking was added. Only updates when linked_id is empty
-// to avoid overwriting a valid binding.
-func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
-	)
-	return i, err
+func (q *sqlQuerier) UpdateTemplateWorkspaceRestartLastBatchAt(ctx context.Context, arg UpdateTemplateWorkspaceRestartLastBatchAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateWorkspaceRestartLastBatchAt, arg.LastBatchAt, arg.ID)
+	return err
 }
 
-const createUserSecret = `-- name: CreateUserSecret :one
-INSERT INTO user_secrets (
-    id,
-    user_id,
-    name,
-    description,
-    value,
-    value_key_id,
-    env_name,
-    file_path
-) VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5,
-    $6,
-    $7,
-    $8
-) RETURNING id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
+const updateTemplateWorkspaceRestartStatus = `-- name: UpdateTemplateWorkspaceRestartStatus :one
+UPDATE
+	template_workspace_restarts
+SET
+	status = $1,
+	status_message = $2,
+	updated_at = $3,
+	completed_at = CASE
+		WHEN $1::template_workspace_restart_status IN ('completed'::template_workspace_restart_status, 'aborted'::template_workspace_restart_status) THEN $3::timestamptz
+		ELSE NULL
+	END
+WHERE
+	id = $4
+	AND status IN ('in_progress'::template_workspace_restart_status, 'paused'::template_workspace_restart_status)
+RETURNING id, template_id, template_version_id, batch_size, batch_interval, honor_quiet_hours, max_failure_rate, min_builds, status, status_message, created_by, created_at, updated_at, stats_since, last_batch_at, completed_at
 `
 
-type CreateUserSecretParams struct {
-	ID          uuid.UUID      `db:"id" json:"id"`
-	UserID      uuid.UUID      `db:"user_id" json:"user_id"`
-	Name        string         `db:"name" json:"name"`
-	Description string         `db:"description" json:"description"`
-	Value       string         `db:"value" json:"value"`
-	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
-	EnvName     string         `db:"env_name" json:"env_name"`
-	FilePath    string         `db:"file_path" json:"file_path"`
+type UpdateTemplateWorkspaceRestartStatusParams struct {
+	Status        TemplateWorkspaceRestartStatus `db:"status" json:"status"`
+	StatusMessage string                         `db:"status_message" json:"status_message"`
+	UpdatedAt     time.Time                      `db:"updated_at" json:"updated_at"`
+	ID            uuid.UUID                      `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) CreateUserSecret(ctx context.Context, arg CreateUserSecretParams) (UserSecret, error) {
-	row := q.db.QueryRowContext(ctx, createUserSecret,
+// Pauses, completes or aborts an unfinished restart. No rows are returned if
+// the restart is already finished.
+func (q *sqlQuerier) UpdateTemplateWorkspaceRestartStatus(ctx context.Context, arg UpdateTemplateWorkspaceRestartStatusParams) (TemplateWorkspaceRestart, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateWorkspaceRestartStatus,
+		arg.Status,
+		arg.StatusMessage,
+		arg.UpdatedAt,
 		arg.ID,
-		arg.UserID,
-		arg.Name,
-		arg.Description,
-		arg.Value,
-		arg.ValueKeyID,
-		arg.EnvName,
-		arg.FilePath,
 	)
-	var i UserSecret
+	var i TemplateWorkspaceRestart
 	err := row.Scan(
 		&i.ID,
-		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.Value,
-		&i.EnvName,
-		&i.FilePath,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.BatchSize,
+		&i.BatchInterval,
+		&i.HonorQuietHours,
+		&i.MaxFailureRate,
+		&i.MinBuilds,
+		&i.Status,
+		&i.StatusMessage,
+		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.ValueKeyID,
+		&i.StatsSince,
+		&i.LastBatchAt,
+		&i.CompletedAt,
 	)
 	return i, err
 }
 
-const deleteUserSecretByUserIDAndName = `-- name: DeleteUserSecretByUserIDAndName :one
-DELETE FROM user_secrets
-WHERE user_id = $1 AND name = $2
-RETURNING id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
+const disableForeignKeysAndTriggers = `-- name: DisableForeignKeysAndTriggers :exec
+DO $$
+DECLARE
+    table_record record;
+BEGIN
+    FOR table_record IN
+        SELECT table_schema, table_name
+        FROM information_schema.tables
+        WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+        AND table_type = 'BASE TABLE'
+    LOOP
+        EXECUTE format('ALTER TABLE %I.%I DISABLE TRIGGER ALL',
+                    table_record.table_schema,
+                    table_record.table_name);
+    END LOOP;
+END;
+$$
 `
 
-type DeleteUserSecretByUserIDAndNameParams struct {
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-	Name   string    `db:"name" json:"name"`
+// Disable foreign keys and triggers for all tables.
+// Deprecated: disable foreign keys was created to aid in migrating off
+// of the test-only in-memory database. Do not use this in new code.
+func (q *sqlQuerier) DisableForeignKeysAndTriggers(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, disableForeignKeysAndTriggers)
+	return err
 }
 
-func (q *sqlQuerier) DeleteUserSecretByUserIDAndName(ctx context.Context, arg DeleteUserSecretByUserIDAndNameParams) (UserSecret, error) {
-	row := q.db.QueryRowContext(ctx, deleteUserSecretByUserIDAndName, arg.UserID, arg.Name)
-	var i UserSecret
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.Value,
-		&i.EnvName,
-		&i.FilePath,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.ValueKeyID,
-	)
-	return i, err
+const getTotalUsageDCManagedAgentsV1 = `-- name: GetTotalUsageDCManagedAgentsV1 :one
+SELECT
+    -- The first cast is necessary since you can't sum strings, and the second
+    -- cast is necessary to make sqlc happy.
+    COALESCE(SUM((usage_data->>'count')::bigint), 0)::bigint AS total_count
+FROM
+    usage_events_daily
+WHERE
+    event_type = 'dc_managed_agents_v1'
+    -- Parentheses are necessary to avoid sqlc from generating an extra
+    -- argument.
+    AND day BETWEEN date_trunc('day', ($1::timestamptz) AT TIME ZONE 'UTC')::date AND date_trunc('day', ($2::timestamptz) AT TIME ZONE 'UTC')::date
+`
+
+type GetTotalUsageDCManagedAgentsV1Params struct {
+	StartDate time.Time `db:"start_date" json:"start_date"`
+	EndDate   time.Time `db:"end_date" json:"end_date"`
 }
 
-const getUserSecretByID = `-- name: GetUserSecretByID :one
-SELECT id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
-FROM user_secrets
-WHERE id = $1
+// Gets the total number of managed agents created between two dates. Uses the
+// aggregate table to avoid large scans or a complex index on the usage_events
+// table.
+//
+// This has the trade off that we can't count accurately between two exact
+// timestamps. The provided timestamps will be converted to UTC and truncated to
+// the events that happened on and between the two dates. Both dates are
+// inclusive.
+func (q *sqlQuerier) GetTotalUsageDCManagedAgentsV1(ctx context.Context, arg GetTotalUsageDCManagedAgentsV1Params) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getTotalUsageDCManagedAgentsV1, arg.StartDate, arg.EndDate)
+	var total_count int64
+	err := row.Scan(&total_count)
+	return total_count, err
+}
+
+const insertUsageEvent = `-- name: InsertUsageEvent :exec
+INSERT INTO
+    usage_events (
+        id,
+        event_type,
+        event_data,
+        created_at,
+        publish_started_at,
+        published_at,
+        failure_message
+    )
+VALUES
+    ($1, $2, $3, $4, NULL, NULL, NULL)
+ON CONFLICT (id) DO NOTHING
 `
 
-func (q *sqlQuerier) GetUserSecretByID(ctx context.Context, id uuid.UUID) (UserSecret, error) {
-	row := q.db.QueryRowContext(ctx, getUserSecretByID, id)
-	var i UserSecret
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.Value,
-		&i.EnvName,
-		&i.FilePath,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.ValueKeyID,
+type InsertUsageEventParams struct {
+	ID        string          `db:"id" json:"id"`
+	EventType string          `db:"event_type" json:"event_type"`
+	EventData json.RawMessage `db:"event_data" json:"event_data"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Duplicate events are ignored intentionally to allow for multiple replicas to
+// publish heartbeat events.
+func (q *sqlQuerier) InsertUsageEvent(ctx context.Context, arg InsertUsageEventParams) error {
+	_, err := q.db.ExecContext(ctx, insertUsageEvent,
+		arg.ID,
+		arg.EventType,
+		arg.EventData,
+		arg.CreatedAt,
 	)
-	return i, err
+	return err
 }
 
-const getUserSecretByUserIDAndName = `-- name: GetUserSecretByUserIDAndName :one
-SELECT id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
-FROM user_secrets
-WHERE user_id = $1 AND name = $2
+const selectUsageEventsForPublishing = `-- name: SelectUsageEventsForPublishing :many
+WITH usage_events AS (
+    UPDATE
+        usage_events
+    SET
+        publish_started_at = $1::timestamptz
+    WHERE
+        id IN (
+            SELECT
+                potential_event.id
+            FROM
+                usage_events potential_event
+            WHERE
+                -- Do not publish events that have already been published or
+                -- have permanently failed to publish.
+                potential_event.published_at IS NULL
+                -- Do not publish events that are already being published by
+                -- another replica.
+                AND (
+                    potential_event.publish_started_at IS NULL
+                    -- If the event has publish_started_at set, it must be older
+                    -- than an hour ago. This is so we can retry publishing
+                    -- events where the replica exited or couldn't update the
+                    -- row.
+                    -- The parentheses around @now::timestamptz are necessary to
+                    -- avoid sqlc from generating an extra argument.
+                    OR potential_event.publish_started_at < ($1::timestamptz) - INTERVAL '1 hour'
+                )
+                -- Do not publish events older than 30 days. Tallyman will
+                -- always permanently reject these events anyways. This is to
+                -- avoid duplicate events being billed to customers, as
+                -- Metronome will only deduplicate events within 34 days.
+                -- Also, the same parentheses thing here as above.
+                AND potential_event.created_at > ($1::timestamptz) - INTERVAL '30 days'
+            ORDER BY potential_event.created_at ASC
+            FOR UPDATE SKIP LOCKED
+            LIMIT 100
+        )
+    RETURNING id, event_type, event_data, created_at, publish_started_at, published_at, failure_message
+)
+SELECT id, event_type, event_data, created_at, publish_started_at, published_at, failure_message
+FROM usage_events
+ORDER BY created_at ASC
 `
 
-type GetUserSecretByUserIDAndNameParams struct {
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-	Name   string    `db:"name" json:"name"`
+// Note that this selects from the CTE, not the original table. The CTE is named
+// the same as the original table to trick sqlc into reusing the existing struct
+// for the table.
+// The CTE and the reorder is required because UPDATE doesn't guarantee order.
+func (q *sqlQuerier) SelectUsageEventsForPublishing(ctx context.Context, now time.Time) ([]UsageEvent, error) {
+	rows, err := q.db.QueryContext(ctx, selectUsageEventsForPublishing, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UsageEvent
+	for rows.Next() {
+		var i UsageEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.EventData,
+			&i.CreatedAt,
+			&i.PublishStartedAt,
+			&i.PublishedAt,
+			&i.FailureMessage,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) GetUserSecretByUserIDAndName(ctx context.Context, arg GetUserSecretByUserIDAndNameParams) (UserSecret, error) {
-	row := q.db.QueryRowContext(ctx, getUserSecretByUserIDAndName, arg.UserID, arg.Name)
-	var i UserSecret
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.Value,
-		&i.EnvName,
-		&i.FilePath,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.ValueKeyID,
+const updateUsageEventsPostPublish = `-- name: UpdateUsageEventsPostPublish :exec
+UPDATE
+    usage_events
+SET
+    publish_started_at = NULL,
+    published_at = CASE WHEN input.set_published_at THEN $1::timestamptz ELSE NULL END,
+    failure_message = NULLIF(input.failure_message, '')
+FROM (
+    SELECT
+        UNNEST($2::text[]) AS id,
+        UNNEST($3::text[]) AS failure_message,
+        UNNEST($4::boolean[]) AS set_published_at
+) input
+WHERE
+    input.id = usage_events.id
+    -- If the number of ids, failure messages, and set published ats are not the
+    -- same, do not do anything. Unfortunately you can't really throw from a
+    -- query without writing a function or doing some jank like dividing by
+    -- zero, so this is the best we can do.
+    AND cardinality($2::text[]) = cardinality($3::text[])
+    AND cardinality($2::text[]) = cardinality($4::boolean[])
+`
+
+type UpdateUsageEventsPostPublishParams struct {
+	Now             time.Time `db:"now" json:"now"`
+	IDs             []string  `db:"ids" json:"ids"`
+	FailureMessages []string  `db:"failure_messages" json:"failure_messages"`
+	SetPublishedAts []bool    `db:"set_published_ats" json:"set_published_ats"`
+}
+
+func (q *sqlQuerier) UpdateUsageEventsPostPublish(ctx context.Context, arg UpdateUsageEventsPostPublishParams) error {
+	_, err := q.db.ExecContext(ctx, updateUsageEventsPostPublish,
+		arg.Now,
+		pq.Array(arg.IDs),
+		pq.Array(arg.FailureMessages),
+		pq.Array(arg.SetPublishedAts),
 	)
-	return i, err
+	return err
 }
 
-const getUserSecretsTelemetrySummary = `-- name: GetUserSecretsTelemetrySummary :one
-WITH active_users AS (
-    SELECT id AS user_id
-    FROM users
-    WHERE deleted = false
-      AND is_system = false
-      AND status = 'active'::user_status
-),
-per_user AS (
-    SELECT au.user_id, COUNT(us.id)::bigint AS n
-    FROM active_users au
-    LEFT JOIN user_secrets us ON us.user_id = au.user_id
-    GROUP BY au.user_id
-),
-secrets_filtered AS (
-    SELECT us.env_name, us.file_path
-    FROM user_secrets us
-    JOIN active_users au ON au.user_id = us.user_id
-)
+const usageEventExistsByID = `-- name: UsageEventExistsByID :one
+SELECT EXISTS(
+    SELECT 1 FROM usage_events WHERE id = $1
+)::bool
+`
+
+func (q *sqlQuerier) UsageEventExistsByID(ctx context.Context, id string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, usageEventExistsByID, id)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const deleteUserAIProviderKey = `-- name: DeleteUserAIProviderKey :exec
+DELETE FROM
+    user_ai_provider_keys
+WHERE
+    user_id = $1::uuid
+    AND ai_provider_id = $2::uuid
+`
+
+type DeleteUserAIProviderKeyParams struct {
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	AIProviderID uuid.UUID `db:"ai_provider_id" json:"ai_provider_id"`
+}
+
+func (q *sqlQuerier) DeleteUserAIProviderKey(ctx context.Context, arg DeleteUserAIProviderKeyParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserAIProviderKey, arg.UserID, arg.AIProviderID)
+	return err
+}
+
+const deleteUserAIProviderKeysByProviderID = `-- name: DeleteUserAIProviderKeysByProviderID :exec
+DELETE FROM
+    user_ai_provider_keys
+WHERE
+    ai_provider_id = $1::uuid
+`
+
+func (q *sqlQuerier) DeleteUserAIProviderKeysByProviderID(ctx context.Context, aiProviderID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserAIProviderKeysByProviderID, aiProviderID)
+	return err
+}
+
+const getUserAIProviderKeyByProviderID = `-- name: GetUserAIProviderKeyByProviderID :one
 SELECT
-    COUNT(*) FILTER (WHERE n > 0)::bigint                                                       AS users_with_secrets,
-    (SELECT COUNT(*) FROM secrets_filtered)::bigint                                             AS total_secrets,
-    (SELECT COUNT(*) FROM secrets_filtered WHERE env_name != '' AND file_path = '' )::bigint    AS env_name_only,
-    (SELECT COUNT(*) FROM secrets_filtered WHERE env_name = ''  AND file_path != '')::bigint    AS file_path_only,
-    (SELECT COUNT(*) FROM secrets_filtered WHERE env_name != '' AND file_path != '')::bigint    AS both,
-    (SELECT COUNT(*) FROM secrets_filtered WHERE env_name = ''  AND file_path = '' )::bigint    AS neither,
-    COALESCE(MAX(n), 0)::bigint                                                                 AS secrets_per_user_max,
-    COALESCE(percentile_disc(0.25) WITHIN GROUP (ORDER BY n), 0)::bigint                        AS secrets_per_user_p25,
-    COALESCE(percentile_disc(0.50) WITHIN GROUP (ORDER BY n), 0)::bigint                        AS secrets_per_user_p50,
-    COALESCE(percentile_disc(0.75) WITHIN GROUP (ORDER BY n), 0)::bigint                        AS secrets_per_user_p75,
-    COALESCE(percentile_disc(0.90) WITHIN GROUP (ORDER BY n), 0)::bigint                        AS secrets_per_user_p90
-FROM per_user
+    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
+FROM
+    user_ai_provider_keys
+WHERE
+    user_id = $1::uuid
+    AND ai_provider_id = $2::uuid
 `
 
-type GetUserSecretsTelemetrySummaryRow struct {
-	UsersWithSecrets  int64 `db:"users_with_secrets" json:"users_with_secrets"`
-	TotalSecrets      int64 `db:"total_secrets" json:"total_secrets"`
-	EnvNameOnly       int64 `db:"env_name_only" json:"env_name_only"`
-	FilePathOnly      int64 `db:"file_path_only" json:"file_path_only"`
-	Both              int64 `db:"both" json:"both"`
-	Neither           int64 `db:"neither" json:"neither"`
-	SecretsPerUserMax int64 `db:"secrets_per_user_max" json:"secrets_per_user_max"`
-	SecretsPerUserP25 int64 `db:"secrets_per_user_p25" json:"secrets_per_user_p25"`
-	SecretsPerUserP50 int64 `db:"secrets_per_user_p50" json:"secrets_per_user_p50"`
-	SecretsPerUserP75 int64 `db:"secrets_per_user_p75" json:"secrets_per_user_p75"`
-	SecretsPerUserP90 int64 `db:"secrets_per_user_p90" json:"secrets_per_user_p90"`
+type GetUserAIProviderKeyByProviderIDParams struct {
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	AIProviderID uuid.UUID `db:"ai_provider_id" json:"ai_provider_id"`
 }
 
-// Returns deployment-wide aggregates for the telemetry snapshot.
-//
-// The denominator for both user-level counts and the per-user
-// distribution is active non-system users. Specifically:
-//
-//   - deleted = false: Coder soft-deletes by flipping users.deleted
-//     rather than removing rows. The delete_deleted_user_resources()
-//     trigger now removes their user_secrets, but soft-deleted users
-//     are still excluded here so they don't dilute the percentile
-//     distribution as zero-secret entries.
-//   - status = 'active': dormant users (no recent activity) and
-//     suspended users (explicitly disabled) cannot use secrets, so
-//     they shouldn't dilute the percentile distribution as
-//     zero-secret entries.
-//   - is_system = false: internal subjects like the prebuilds user
-//     never use secrets in the normal flow.
-//
-// Status transitions move users in and out of this denominator, so a
-// snapshot's UsersWithSecrets can drop without any secret being
-// deleted.
-//
-// The percentile distribution is computed across all active non-system
-// users, including those with zero secrets, so the percentiles reflect
-// deployment-wide adoption rather than only the power-user subset.
-// percentile_disc returns an actual integer count from the underlying
-// values rather than interpolating between rows.
-func (q *sqlQuerier) GetUserSecretsTelemetrySummary(ctx context.Context) (GetUserSecretsTelemetrySummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getUserSecretsTelemetrySummary)
-	var i GetUserSecretsTelemetrySummaryRow
+func (q *sqlQuerier) GetUserAIProviderKeyByProviderID(ctx context.Context, arg GetUserAIProviderKeyByProviderIDParams) (UserAIProviderKey, error) {
+	row := q.db.QueryRowContext(ctx, getUserAIProviderKeyByProviderID, arg.UserID, arg.AIProviderID)
+	var i UserAIProviderKey
 	err := row.Scan(
-		&i.UsersWithSecrets,
-		&i.TotalSecrets,
-		&i.EnvNameOnly,
-		&i.FilePathOnly,
-		&i.Both,
-		&i.Neither,
-		&i.SecretsPerUserMax,
-		&i.SecretsPerUserP25,
-		&i.SecretsPerUserP50,
-		&i.SecretsPerUserP75,
-		&i.SecretsPerUserP90,
+		&i.ID,
+		&i.UserID,
+		&i.AIProviderID,
+		&i.APIKey,
+		&i.ApiKeyKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const listUserSecrets = `-- name: ListUserSecrets :many
+const getUserAIProviderKeys = `-- name: GetUserAIProviderKeys :many
 SELECT
-    id, user_id, name, description,
-    env_name, file_path,
-    created_at, updated_at
-FROM user_secrets
-WHERE user_id = $1
-ORDER BY name ASC
+    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
+FROM
+    user_ai_provider_keys
+ORDER BY
+    user_id ASC,
+    ai_provider_id ASC,
+    created_at ASC,
+    id ASC
 `
 
-type ListUserSecretsRow struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	UserID      uuid.UUID `db:"user_id" json:"user_id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	EnvName     string    `db:"env_name" json:"env_name"`
-	FilePath    string    `db:"file_path" json:"file_path"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-}
-
-// Returns metadata only (no value or value_key_id) for the
-// REST API list and get endpoints.
-func (q *sqlQuerier) ListUserSecrets(ctx context.Context, userID uuid.UUID) ([]ListUserSecretsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listUserSecrets, userID)
+// GetUserAIProviderKeys is used by dbcrypt key rotation. Request paths should use
+// user-scoped lookups instead of this bulk accessor.
+func (q *sqlQuerier) GetUserAIProviderKeys(ctx context.Context) ([]UserAIProviderKey, error) {
+	rows, err := q.db.QueryContext(ctx, getUserAIProviderKeys)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListUserSecretsRow
+	var items []UserAIProviderKey
 	for rows.Next() {
-		var i ListUserSecretsRow
+		var i UserAIProviderKey
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
-			&i.Name,
-			&i.Description,
-			&i.EnvName,
-			&i.FilePath,
+			&i.AIProviderID,
+			&i.APIKey,
+			&i.ApiKeyKeyID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -29781,36 +33161,36 @@ func (q *sqlQuerier) ListUserSecrets(ctx context.Context, userID uuid.UUID) ([]L
 	return items, nil
 }
 
-const listUserSecretsWithValues = `-- name: ListUserSecretsWithValues :many
-SELECT id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
-FROM user_secrets
-WHERE user_id = $1
-ORDER BY name ASC
+const getUserAIProviderKeysByUserID = `-- name: GetUserAIProviderKeysByUserID :many
+SELECT
+    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
+FROM
+    user_ai_provider_keys
+WHERE
+    user_id = $1::uuid
+ORDER BY
+    ai_provider_id ASC,
+    created_at ASC,
+    id ASC
 `
 
-// Returns all columns including the secret value. Used by the
-// provisioner (build-time injection) and the agent manifest
-// (runtime injection).
-func (q *sqlQuerier) ListUserSecretsWithValues(ctx context.Context, userID uuid.UUID) ([]UserSecret, error) {
-	rows, err := q.db.QueryContext(ctx, listUserSecretsWithValues, userID)
+func (q *sqlQuerier) GetUserAIProviderKeysByUserID(ctx context.Context, userID uuid.UUID) ([]UserAIProviderKey, error) {
+	rows, err := q.db.QueryContext(ctx, getUserAIProviderKeysByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UserSecret
+	var items []UserAIProviderKey
 	for rows.Next() {
-		var i UserSecret
+		var i UserAIProviderKey
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
-			&i.Name,
-			&i.Description,
-			&i.Value,
-			&i.EnvName,
-			&i.FilePath,
+			&i.AIProviderID,
+			&i.APIKey,
+			&i.ApiKeyKeyID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.ValueKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -29825,183 +33205,229 @@ func (q *sqlQuerier) ListUserSecretsWithValues(ctx context.Context, userID uuid.
 	return items, nil
 }
 
-const updateUserSecretByUserIDAndName = `-- name: UpdateUserSecretByUserIDAndName :one
-UPDATE user_secrets
+const updateEncryptedUserAIProviderKey = `-- name: UpdateEncryptedUserAIProviderKey :one
+UPDATE
+    user_ai_provider_keys
 SET
-    value       = CASE WHEN $1::bool THEN $2 ELSE value END,
-    value_key_id = CASE WHEN $1::bool THEN $3 ELSE value_key_id END,
-    description = CASE WHEN $4::bool THEN $5 ELSE description END,
-    env_name    = CASE WHEN $6::bool THEN $7 ELSE env_name END,
-    file_path   = CASE WHEN $8::bool THEN $9 ELSE file_path END,
-    updated_at  = CURRENT_TIMESTAMP
-WHERE user_id = $10 AND name = $11
-RETURNING id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
+    api_key = $1::text,
+    api_key_key_id = $2::text,
+    updated_at = NOW()
+WHERE
+    id = $3::uuid
+RETURNING
+    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
 `
 
-type UpdateUserSecretByUserIDAndNameParams struct {
-	UpdateValue       bool           `db:"update_value" json:"update_value"`
-	Value             string         `db:"value" json:"value"`
-	ValueKeyID        sql.NullString `db:"value_key_id" json:"value_key_id"`
-	UpdateDescription bool           `db:"update_description" json:"update_description"`
-	Description       string         `db:"description" json:"description"`
-	UpdateEnvName     bool           `db:"update_env_name" json:"update_env_name"`
-	EnvName           string         `db:"env_name" json:"env_name"`
-	UpdateFilePath    bool           `db:"update_file_path" json:"update_file_path"`
-	FilePath          string         `db:"file_path" json:"file_path"`
-	UserID            uuid.UUID      `db:"user_id" json:"user_id"`
-	Name              string         `db:"name" json:"name"`
+type UpdateEncryptedUserAIProviderKeyParams struct {
+	APIKey      string         `db:"api_key" json:"api_key"`
+	ApiKeyKeyID sql.NullString `db:"api_key_key_id" json:"api_key_key_id"`
+	ID          uuid.UUID      `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateUserSecretByUserIDAndName(ctx context.Context, arg UpdateUserSecretByUserIDAndNameParams) (UserSecret, error) {
-	row := q.db.QueryRowContext(ctx, updateUserSecretByUserIDAndName,
-		arg.UpdateValue,
-		arg.Value,
-		arg.ValueKeyID,
-		arg.UpdateDescription,
-		arg.Description,
-		arg.UpdateEnvName,
-		arg.EnvName,
-		arg.UpdateFilePath,
-		arg.FilePath,
-		arg.UserID,
-		arg.Name,
-	)
-	var i UserSecret
+func (q *sqlQuerier) UpdateEncryptedUserAIProviderKey(ctx context.Context, arg UpdateEncryptedUserAIProviderKeyParams) (UserAIProviderKey, error) {
+	row := q.db.QueryRowContext(ctx, updateEncryptedUserAIProviderKey, arg.APIKey, arg.ApiKeyKeyID, arg.ID)
+	var i UserAIProviderKey
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.Value,
-		&i.EnvName,
-		&i.FilePath,
+		&i.AIProviderID,
+		&i.APIKey,
+		&i.ApiKeyKeyID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.ValueKeyID,
 	)
 	return i, err
 }
 
-const deleteUserSkillByUserIDAndName = `-- name: DeleteUserSkillByUserIDAndName :one
-DELETE FROM user_skills
-WHERE user_id = $1 AND name = $2
-RETURNING id, user_id, name, description, content, created_at, updated_at
+const updateUserAIProviderKey = `-- name: UpdateUserAIProviderKey :one
+UPDATE
+    user_ai_provider_keys
+SET
+    api_key = $1::text,
+    api_key_key_id = $2::text,
+    updated_at = NOW()
+WHERE
+    user_id = $3::uuid
+    AND ai_provider_id = $4::uuid
+RETURNING
+    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
 `
 
-type DeleteUserSkillByUserIDAndNameParams struct {
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-	Name   string    `db:"name" json:"name"`
+type UpdateUserAIProviderKeyParams struct {
+	APIKey       string         `db:"api_key" json:"api_key"`
+	ApiKeyKeyID  sql.NullString `db:"api_key_key_id" json:"api_key_key_id"`
+	UserID       uuid.UUID      `db:"user_id" json:"user_id"`
+	AIProviderID uuid.UUID      `db:"ai_provider_id" json:"ai_provider_id"`
 }
 
-func (q *sqlQuerier) DeleteUserSkillByUserIDAndName(ctx context.Context, arg DeleteUserSkillByUserIDAndNameParams) (UserSkill, error) {
-	row := q.db.QueryRowContext(ctx, deleteUserSkillByUserIDAndName, arg.UserID, arg.Name)
-	var i UserSkill
+func (q *sqlQuerier) UpdateUserAIProviderKey(ctx context.Context, arg UpdateUserAIProviderKeyParams) (UserAIProviderKey, error) {
+	row := q.db.QueryRowContext(ctx, updateUserAIProviderKey,
+		arg.APIKey,
+		arg.ApiKeyKeyID,
+		arg.UserID,
+		arg.AIProviderID,
+	)
+	var i UserAIProviderKey
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.Content,
+		&i.AIProviderID,
+		&i.APIKey,
+		&i.ApiKeyKeyID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getUserSkillByUserIDAndName = `-- name: GetUserSkillByUserIDAndName :one
-SELECT id, user_id, name, description, content, created_at, updated_at
-FROM user_skills
-WHERE user_id = $1 AND name = $2
+const upsertUserAIProviderKey = `-- name: UpsertUserAIProviderKey :one
+INSERT INTO user_ai_provider_keys (
+    id,
+    user_id,
+    ai_provider_id,
+    api_key,
+    api_key_key_id,
+    created_at,
+    updated_at
+) VALUES (
+    $1::uuid,
+    $2::uuid,
+    $3::uuid,
+    $4::text,
+    $5::text,
+    $6::timestamptz,
+    $7::timestamptz
+)
+ON CONFLICT (user_id, ai_provider_id) DO UPDATE
+SET
+    api_key = EXCLUDED.api_key,
+    api_key_key_id = EXCLUDED.api_key_key_id,
+    updated_at = EXCLUDED.updated_at
+RETURNING
+    id, user_id, ai_provider_id, api_key, api_key_key_id, created_at, updated_at
 `
 
-type GetUserSkillByUserIDAndNameParams struct {
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-	Name   string    `db:"name" json:"name"`
+type UpsertUserAIProviderKeyParams struct {
+	ID           uuid.UUID      `db:"id" json:"id"`
+	UserID       uuid.UUID      `db:"user_id" json:"user_id"`
+	AIProviderID uuid.UUID      `db:"ai_provider_id" json:"ai_provider_id"`
+	APIKey       string         `db:"api_key" json:"api_key"`
+	ApiKeyKeyID  sql.NullString `db:"api_key_key_id" json:"api_key_key_id"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) GetUserSkillByUserIDAndName(ctx context.Context, arg GetUserSkillByUserIDAndNameParams) (UserSkill, error) {
-	row := q.db.QueryRowContext(ctx, getUserSkillByUserIDAndName, arg.UserID, arg.Name)
-	var i UserSkill
+// UpsertUserAIProviderKey preserves the original id and created_at when the
+// user/provider pair already exists. On conflict, callers provide id and
+// created_at for the insert path only.
+func (q *sqlQuerier) UpsertUserAIProviderKey(ctx context.Context, arg UpsertUserAIProviderKeyParams) (UserAIProviderKey, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserAIProviderKey,
+		arg.ID,
+		arg.UserID,
+		arg.AIProviderID,
+		arg.APIKey,
+		arg.ApiKeyKeyID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i UserAIProviderKey
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.Content,
+		&i.AIProviderID,
+		&i.APIKey,
+		&i.ApiKeyKeyID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const insertUserSkill = `-- name: InsertUserSkill :one
-INSERT INTO user_skills (id, user_id, name, description, content)
-VALUES ($1::uuid, $2::uuid, $3::text, $4::text, $5::text)
-RETURNING id, user_id, name, description, content, created_at, updated_at
+const getUserImpersonationByAPIKeyID = `-- name: GetUserImpersonationByAPIKeyID :one
+SELECT
+	id, user_id, impersonator_id, reason, lifetime_seconds, consent_required, consented_at, api_key_id, issued_at, created_at
+FROM
+	user_impersonations
+WHERE
+	api_key_id = $1 :: text
 `
 
-type InsertUserSkillParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	UserID      uuid.UUID `db:"user_id" json:"user_id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	Content     string    `db:"content" json:"content"`
-}
-
-func (q *sqlQuerier) InsertUserSkill(ctx context.Context, arg InsertUserSkillParams) (UserSkill, error) {
-	row := q.db.QueryRowContext(ctx, insertUserSkill,
-		arg.ID,
-		arg.UserID,
-		arg.Name,
-		arg.Description,
-		arg.Content,
-	)
-	var i UserSkill
+func (q *sqlQuerier) GetUserImpersonationByAPIKeyID(ctx context.Context, apiKeyID string) (UserImpersonation, error) {
+	row := q.db.QueryRowContext(ctx, getUserImpersonationByAPIKeyID, apiKeyID)
+	var i UserImpersonation
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.Content,
+		&i.ImpersonatorID,
+		&i.Reason,
+		&i.LifetimeSeconds,
+		&i.ConsentRequired,
+		&i.ConsentedAt,
+		&i.APIKeyID,
+		&i.IssuedAt,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const listUserSkillMetadataByUserID = `-- name: ListUserSkillMetadataByUserID :many
+const getUserImpersonationByID = `-- name: GetUserImpersonationByID :one
 SELECT
-    id, user_id, name, description, created_at, updated_at
-FROM user_skills
-WHERE user_id = $1
-ORDER BY name ASC
+	id, user_id, impersonator_id, reason, lifetime_seconds, consent_required, consented_at, api_key_id, issued_at, created_at
+FROM
+	user_impersonations
+WHERE
+	id = $1
 `
 
-type ListUserSkillMetadataByUserIDRow struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	UserID      uuid.UUID `db:"user_id" json:"user_id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+func (q *sqlQuerier) GetUserImpersonationByID(ctx context.Context, id uuid.UUID) (UserImpersonation, error) {
+	row := q.db.QueryRowContext(ctx, getUserImpersonationByID, id)
+	var i UserImpersonation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ImpersonatorID,
+		&i.Reason,
+		&i.LifetimeSeconds,
+		&i.ConsentRequired,
+		&i.ConsentedAt,
+		&i.APIKeyID,
+		&i.IssuedAt,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) ListUserSkillMetadataByUserID(ctx context.Context, userID uuid.UUID) ([]ListUserSkillMetadataByUserIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, listUserSkillMetadataByUserID, userID)
+const getUserImpersonationsByUserID = `-- name: GetUserImpersonationsByUserID :many
+SELECT
+	id, user_id, impersonator_id, reason, lifetime_seconds, consent_required, consented_at, api_key_id, issued_at, created_at
+FROM
+	user_impersonations
+WHERE
+	user_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetUserImpersonationsByUserID(ctx context.Context, userID uuid.UUID) ([]UserImpersonation, error) {
+	rows, err := q.db.QueryContext(ctx, getUserImpersonationsByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListUserSkillMetadataByUserIDRow
+	var items []UserImpersonation
 	for rows.Next() {
-		var i ListUserSkillMetadataByUserIDRow
+		var i UserImpersonation
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
-			&i.Name,
-			&i.Description,
+			&i.ImpersonatorID,
+			&i.Reason,
+			&i.LifetimeSeconds,
+			&i.ConsentRequired,
+			&i.ConsentedAt,
+			&i.APIKeyID,
+			&i.IssuedAt,
 			&i.CreatedAt,
-			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -30016,62 +33442,172 @@ func (q *sqlQuerier) ListUserSkillMetadataByUserID(ctx context.Context, userID u
 	return items, nil
 }
 
-const updateUserSkillByUserIDAndName = `-- name: UpdateUserSkillByUserIDAndName :one
-UPDATE user_skills
-SET
-    description = $1,
-    content     = $2,
-    updated_at  = now()
-WHERE user_id = $3 AND name = $4
-RETURNING id, user_id, name, description, content, created_at, updated_at
+const insertUserImpersonation = `-- name: InsertUserImpersonation :one
+INSERT INTO
+	user_impersonations (
+		id,
+		user_id,
+		impersonator_id,
+		reason,
+		lifetime_seconds,
+		consent_required,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, user_id, impersonator_id, reason, lifetime_seconds, consent_required, consented_at, api_key_id, issued_at, created_at
 `
 
-type UpdateUserSkillByUserIDAndNameParams struct {
-	Description string    `db:"description" json:"description"`
-	Content     string    `db:"content" json:"content"`
-	UserID      uuid.UUID `db:"user_id" json:"user_id"`
-	Name        string    `db:"name" json:"name"`
+type InsertUserImpersonationParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	ImpersonatorID  uuid.UUID `db:"impersonator_id" json:"impersonator_id"`
+	Reason          string    `db:"reason" json:"reason"`
+	LifetimeSeconds int64     `db:"lifetime_seconds" json:"lifetime_seconds"`
+	ConsentRequired bool      `db:"consent_required" json:"consent_required"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) UpdateUserSkillByUserIDAndName(ctx context.Context, arg UpdateUserSkillByUserIDAndNameParams) (UserSkill, error) {
-	row := q.db.QueryRowContext(ctx, updateUserSkillByUserIDAndName,
-		arg.Description,
-		arg.Content,
+func (q *sqlQuerier) InsertUserImpersonation(ctx context.Context, arg InsertUserImpersonationParams) (UserImpersonation, error) {
+	row := q.db.QueryRowContext(ctx, insertUserImpersonation,
+		arg.ID,
 		arg.UserID,
-		arg.Name,
+		arg.ImpersonatorID,
+		arg.Reason,
+		arg.LifetimeSeconds,
+		arg.ConsentRequired,
+		arg.CreatedAt,
 	)
-	var i UserSkill
+	var i UserImpersonation
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.Name,
-		&i.Description,
-		&i.Content,
+		&i.ImpersonatorID,
+		&i.Reason,
+		&i.LifetimeSeconds,
+		&i.ConsentRequired,
+		&i.ConsentedAt,
+		&i.APIKeyID,
+		&i.IssuedAt,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const allUserIDs = `-- name: AllUserIDs :many
-SELECT DISTINCT id FROM USERS
-	WHERE CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+const updateUserImpersonationConsentedAt = `-- name: UpdateUserImpersonationConsentedAt :one
+UPDATE
+	user_impersonations
+SET
+	consented_at = $1
+WHERE
+	id = $2
+	AND consent_required
+	AND consented_at IS NULL
+	AND issued_at IS NULL
+RETURNING id, user_id, impersonator_id, reason, lifetime_seconds, consent_required, consented_at, api_key_id, issued_at, created_at
 `
 
-// AllUserIDs returns all UserIDs regardless of user status or deletion.
-func (q *sqlQuerier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
-	rows, err := q.db.QueryContext(ctx, allUserIDs, includeSystem)
+type UpdateUserImpersonationConsentedAtParams struct {
+	ConsentedAt sql.NullTime `db:"consented_at" json:"consented_at"`
+	ID          uuid.UUID    `db:"id" json:"id"`
+}
+
+// Records the consent of the impersonated user. Requests that were already
+// consented to or granted are left untouched, so no rows are returned.
+func (q *sqlQuerier) UpdateUserImpersonationConsentedAt(ctx context.Context, arg UpdateUserImpersonationConsentedAtParams) (UserImpersonation, error) {
+	row := q.db.QueryRowContext(ctx, updateUserImpersonationConsentedAt, arg.ConsentedAt, arg.ID)
+	var i UserImpersonation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ImpersonatorID,
+		&i.Reason,
+		&i.LifetimeSeconds,
+		&i.ConsentRequired,
+		&i.ConsentedAt,
+		&i.APIKeyID,
+		&i.IssuedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateUserImpersonationIssued = `-- name: UpdateUserImpersonationIssued :one
+UPDATE
+	user_impersonations
+SET
+	api_key_id = $1,
+	issued_at = $2
+WHERE
+	id = $3
+	AND issued_at IS NULL
+RETURNING id, user_id, impersonator_id, reason, lifetime_seconds, consent_required, consented_at, api_key_id, issued_at, created_at
+`
+
+type UpdateUserImpersonationIssuedParams struct {
+	APIKeyID sql.NullString `db:"api_key_id" json:"api_key_id"`
+	IssuedAt sql.NullTime   `db:"issued_at" json:"issued_at"`
+	ID       uuid.UUID      `db:"id" json:"id"`
+}
+
+// Marks the request as granted. A token is only ever issued once per
+// request, so no rows are returned for requests that were already granted.
+func (q *sqlQuerier) UpdateUserImpersonationIssued(ctx context.Context, arg UpdateUserImpersonationIssuedParams) (UserImpersonation, error) {
+	row := q.db.QueryRowContext(ctx, updateUserImpersonationIssued, arg.APIKeyID, arg.IssuedAt, arg.ID)
+	var i UserImpersonation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ImpersonatorID,
+		&i.Reason,
+		&i.LifetimeSeconds,
+		&i.ConsentRequired,
+		&i.ConsentedAt,
+		&i.APIKeyID,
+		&i.IssuedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const countOIDCLinkedIDsByIssuer = `-- name: CountOIDCLinkedIDsByIssuer :many
+SELECT
+	(CASE
+		WHEN user_links.linked_id = '' THEN ''
+		ELSE split_part(user_links.linked_id, '||', 1)
+	END)::text AS issuer_prefix,
+	COUNT(*)::int AS count
+FROM
+	user_links
+INNER JOIN
+	users ON user_links.user_id = users.id
+WHERE
+	user_links.login_type = 'oidc'
+	AND users.deleted = false
+GROUP BY issuer_prefix
+`
+
+type CountOIDCLinkedIDsByIssuerRow struct {
+	IssuerPrefix string `db:"issuer_prefix" json:"issuer_prefix"`
+	Count        int32  `db:"count" json:"count"`
+}
+
+// Groups OIDC user links by their issuer prefix (the part before "||" in
+// linked_id) and returns a count for each. Empty linked_ids are reported
+// with an empty issuer_prefix. Used for analysis before resetting
+// mismatched links.
+func (q *sqlQuerier) CountOIDCLinkedIDsByIssuer(ctx context.Context) ([]CountOIDCLinkedIDsByIssuerRow, error) {
+	rows, err := q.db.QueryContext(ctx, countOIDCLinkedIDsByIssuer)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []uuid.UUID
+	var items []CountOIDCLinkedIDsByIssuerRow
 	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
+		var i CountOIDCLinkedIDsByIssuerRow
+		if err := rows.Scan(&i.IssuerPrefix, &i.Count); err != nil {
 			return nil, err
 		}
-		items = append(items, id)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -30082,674 +33618,648 @@ func (q *sqlQuerier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid
 	return items, nil
 }
 
-const deleteUserChatCompactionThreshold = `-- name: DeleteUserChatCompactionThreshold :exec
-DELETE FROM user_configs WHERE user_id = $1 AND key = $2
-`
-
-type DeleteUserChatCompactionThresholdParams struct {
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-	Key    string    `db:"key" json:"key"`
-}
-
-func (q *sqlQuerier) DeleteUserChatCompactionThreshold(ctx context.Context, arg DeleteUserChatCompactionThresholdParams) error {
-	_, err := q.db.ExecContext(ctx, deleteUserChatCompactionThreshold, arg.UserID, arg.Key)
-	return err
-}
-
-const getActiveUserCount = `-- name: GetActiveUserCount :one
+const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
 SELECT
-	COUNT(*)
+	user_links.user_id, user_links.login_type, user_links.linked_id, user_links.oauth_access_token, user_links.oauth_refresh_token, user_links.oauth_expiry, user_links.oauth_access_token_key_id, user_links.oauth_refresh_token_key_id, user_links.claims
 FROM
-	users
+	user_links
+INNER JOIN
+	users ON user_links.user_id = users.id
 WHERE
-	status = 'active'::user_status AND deleted = false
-	AND is_service_account = false
-	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+	linked_id = $1
+	AND
+	deleted = false
 `
 
-func (q *sqlQuerier) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getActiveUserCount, includeSystem)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, linkedID)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
+	)
+	return i, err
 }
 
-const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
+const getUserLinkByUserIDLoginType = `-- name: GetUserLinkByUserIDLoginType :one
 SELECT
-	-- username and email are returned just to help for logging purposes
-	-- status is used to enforce 'suspended' users, as all roles are ignored
-	--	when suspended.
-	id, username, status, email,
-	-- All user roles, including their org roles.
-	array_cat(
-		-- All users are members
-		array_append(users.rbac_roles, 'member'),
-		(
-			SELECT
-				-- The roles are returned as a flat array, org scoped and site side.
-				-- Concatenating the organization id scopes the organization roles.
-				array_agg(org_roles || ':' || organization_members.organization_id::text)
-			FROM
-				organization_members
-				JOIN organizations ON organizations.id = organization_members.organization_id,
-				-- All org members get an implied role for their orgs. Most members
-				-- get organization-member, but service accounts will get
-				-- organization-service-account instead. They're largely the same,
-				-- but having them be distinct means we can allow configuring
-				-- service-accounts to have slightly broader permissions, such as
-				-- for workspace sharing.
-				--
-				-- organizations.default_org_member_roles is unioned in so changes
-				-- to org defaults propagate to every member on the next request.
-				unnest(
-					array_cat(
-						array_append(
-							roles,
-							CASE WHEN users.is_service_account THEN
-								'organization-service-account'
-							ELSE
-								'organization-member'
-							END
-						),
-						organizations.default_org_member_roles
-					)
-				) AS org_roles
-			WHERE
-				user_id = users.id
-		)
-	) :: text[] AS roles,
-	-- All groups the user is in.
-	(
-		SELECT
-			array_agg(
-				group_members.group_id :: text
-			)
-		FROM
-			group_members
-		WHERE
-			user_id = users.id
-	) :: text[] AS groups
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
 FROM
-	users
+	user_links
 WHERE
-	users.id = $1
+	user_id = $1 AND login_type = $2
 `
 
-type GetAuthorizationUserRolesRow struct {
-	ID       uuid.UUID  `db:"id" json:"id"`
-	Username string     `db:"username" json:"username"`
-	Status   UserStatus `db:"status" json:"status"`
-	Email    string     `db:"email" json:"email"`
-	Roles    []string   `db:"roles" json:"roles"`
-	Groups   []string   `db:"groups" json:"groups"`
+type GetUserLinkByUserIDLoginTypeParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType LoginType `db:"login_type" json:"login_type"`
 }
 
-// This function returns roles for authorization purposes. Implied member roles
-// are included.
-func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error) {
-	row := q.db.QueryRowContext(ctx, getAuthorizationUserRoles, userID)
-	var i GetAuthorizationUserRolesRow
+func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType)
+	var i UserLink
 	err := row.Scan(
-		&i.ID,
-		&i.Username,
-		&i.Status,
-		&i.Email,
-		pq.Array(&i.Roles),
-		pq.Array(&i.Groups),
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
 	)
 	return i, err
 }
 
-const getUserAgentChatSendShortcut = `-- name: GetUserAgentChatSendShortcut :one
-SELECT
-	value AS agent_chat_send_shortcut
-FROM
-	user_configs
-WHERE
-	user_id = $1
-	AND key = 'preference_agent_chat_send_shortcut'
+const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
+SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims FROM user_links WHERE user_id = $1
 `
 
-func (q *sqlQuerier) GetUserAgentChatSendShortcut(ctx context.Context, userID uuid.UUID) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserAgentChatSendShortcut, userID)
-	var agent_chat_send_shortcut string
-	err := row.Scan(&agent_chat_send_shortcut)
-	return agent_chat_send_shortcut, err
-}
-
-const getUserAppearanceSettings = `-- name: GetUserAppearanceSettings :one
-SELECT
-	COALESCE(MAX(value) FILTER (WHERE key = 'theme_preference'), '')::text AS theme_preference,
-	COALESCE(MAX(value) FILTER (WHERE key = 'theme_mode'), '')::text AS theme_mode,
-	COALESCE(MAX(value) FILTER (WHERE key = 'theme_light'), '')::text AS theme_light,
-	COALESCE(MAX(value) FILTER (WHERE key = 'theme_dark'), '')::text AS theme_dark,
-	COALESCE(MAX(value) FILTER (WHERE key = 'terminal_font'), '')::text AS terminal_font
-FROM
-	user_configs
-WHERE
-	user_id = $1
-	AND key IN (
-		'theme_preference',
-		'theme_mode',
-		'theme_light',
-		'theme_dark',
-		'terminal_font'
-	)
-`
-
-type GetUserAppearanceSettingsRow struct {
-	ThemePreference string `db:"theme_preference" json:"theme_preference"`
-	ThemeMode       string `db:"theme_mode" json:"theme_mode"`
-	ThemeLight      string `db:"theme_light" json:"theme_light"`
-	ThemeDark       string `db:"theme_dark" json:"theme_dark"`
-	TerminalFont    string `db:"terminal_font" json:"terminal_font"`
+func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLinksByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserLink
+	for rows.Next() {
+		var i UserLink
+		if err := rows.Scan(
+			&i.UserID,
+			&i.LoginType,
+			&i.LinkedID,
+			&i.OAuthAccessToken,
+			&i.OAuthRefreshToken,
+			&i.OAuthExpiry,
+			&i.OAuthAccessTokenKeyID,
+			&i.OAuthRefreshTokenKeyID,
+			&i.Claims,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) GetUserAppearanceSettings(ctx context.Context, userID uuid.UUID) (GetUserAppearanceSettingsRow, error) {
-	row := q.db.QueryRowContext(ctx, getUserAppearanceSettings, userID)
-	var i GetUserAppearanceSettingsRow
-	err := row.Scan(
-		&i.ThemePreference,
-		&i.ThemeMode,
-		&i.ThemeLight,
-		&i.ThemeDark,
-		&i.TerminalFont,
+const insertUserLink = `-- name: InsertUserLink :one
+INSERT INTO
+	user_links (
+		user_id,
+		login_type,
+		linked_id,
+		oauth_access_token,
+		oauth_access_token_key_id,
+		oauth_refresh_token,
+		oauth_refresh_token_key_id,
+		oauth_expiry,
+		claims
 	)
-	return i, err
-}
-
-const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
-FROM
-	users
-WHERE
-	(LOWER(username) = LOWER($1) OR ($2 != '' AND LOWER(email) = LOWER($2))) AND
-	deleted = false
-LIMIT
-	1
+VALUES
+	( $1, $2, $3, $4, $5, $6, $7, $8, $9 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
 `
 
-type GetUserByEmailOrUsernameParams struct {
-	Username string      `db:"username" json:"username"`
-	Email    interface{} `db:"email" json:"email"`
+type InsertUserLinkParams struct {
+	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
+	LoginType              LoginType      `db:"login_type" json:"login_type"`
+	LinkedID               string         `db:"linked_id" json:"linked_id"`
+	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+	Claims                 UserLinkClaims `db:"claims" json:"claims"`
 }
 
-func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByEmailOrUsername, arg.Username, arg.Email)
-	var i User
+func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, insertUserLink,
+		arg.UserID,
+		arg.LoginType,
+		arg.LinkedID,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+		arg.Claims,
+	)
+	var i UserLink
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
+		&i.UserID,
 		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-		&i.IsServiceAccount,
-		&i.ChatSpendLimitMicros,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
 	)
 	return i, err
 }
 
-const getUserByID = `-- name: GetUserByID :one
+const oIDCClaimFieldValues = `-- name: OIDCClaimFieldValues :many
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+	-- DISTINCT to remove duplicates
+	DISTINCT jsonb_array_elements_text(CASE
+		-- When the type is an array, filter out any non-string elements.
+		-- This is to keep the return type consistent.
+		WHEN jsonb_typeof(claims->'merged_claims'->$1::text) = 'array' THEN
+			(
+				SELECT
+					jsonb_agg(element)
+				FROM
+					jsonb_array_elements(claims->'merged_claims'->$1::text) AS element
+				WHERE
+					-- Filtering out non-string elements
+					jsonb_typeof(element) = 'string'
+			)
+		-- Some IDPs return a single string instead of an array of strings.
+		WHEN jsonb_typeof(claims->'merged_claims'->$1::text) = 'string' THEN
+			jsonb_build_array(claims->'merged_claims'->$1::text)
+	END)
 FROM
-	users
+	user_links
 WHERE
-	id = $1
-LIMIT
-	1
-`
-
-func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByID, id)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-		&i.IsServiceAccount,
-		&i.ChatSpendLimitMicros,
-	)
-	return i, err
-}
-
-const getUserChatCompactionThreshold = `-- name: GetUserChatCompactionThreshold :one
-SELECT value AS threshold_percent FROM user_configs
-WHERE user_id = $1 AND key = $2
+	-- IDP sync only supports string and array (of string) types
+	jsonb_typeof(claims->'merged_claims'->$1::text) = ANY(ARRAY['string', 'array'])
+	AND login_type = 'oidc'
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
+			user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = $2)
+		ELSE true
+	END
 `
 
-type GetUserChatCompactionThresholdParams struct {
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-	Key    string    `db:"key" json:"key"`
+type OIDCClaimFieldValuesParams struct {
+	ClaimField     string    `db:"claim_field" json:"claim_field"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
 }
 
-func (q *sqlQuerier) GetUserChatCompactionThreshold(ctx context.Context, arg GetUserChatCompactionThresholdParams) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserChatCompactionThreshold, arg.UserID, arg.Key)
-	var threshold_percent string
-	err := row.Scan(&threshold_percent)
-	return threshold_percent, err
+func (q *sqlQuerier) OIDCClaimFieldValues(ctx context.Context, arg OIDCClaimFieldValuesParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, oIDCClaimFieldValues, arg.ClaimField, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var jsonb_array_elements_text string
+		if err := rows.Scan(&jsonb_array_elements_text); err != nil {
+			return nil, err
+		}
+		items = append(items, jsonb_array_elements_text)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getUserChatCustomPrompt = `-- name: GetUserChatCustomPrompt :one
+const oIDCClaimFields = `-- name: OIDCClaimFields :many
 SELECT
-	value as chat_custom_prompt
+	DISTINCT jsonb_object_keys(claims->'merged_claims')
 FROM
-	user_configs
+	user_links
 WHERE
-	user_id = $1
-	AND key = 'chat_custom_prompt'
+    -- Only return rows where the top level key exists
+	claims ? 'merged_claims' AND
+    -- 'null' is the default value for the id_token_claims field
+	-- jsonb 'null' is not the same as SQL NULL. Strip these out.
+	jsonb_typeof(claims->'merged_claims') != 'null' AND
+	login_type = 'oidc'
+	AND CASE WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
+		user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = $1)
+		ELSE true
+	END
 `
 
-func (q *sqlQuerier) GetUserChatCustomPrompt(ctx context.Context, userID uuid.UUID) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserChatCustomPrompt, userID)
-	var chat_custom_prompt string
-	err := row.Scan(&chat_custom_prompt)
-	return chat_custom_prompt, err
+// OIDCClaimFields returns a list of distinct keys in the the merged_claims fields.
+// This query is used to generate the list of available sync fields for idp sync settings.
+func (q *sqlQuerier) OIDCClaimFields(ctx context.Context, organizationID uuid.UUID) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, oIDCClaimFields, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var jsonb_object_keys string
+		if err := rows.Scan(&jsonb_object_keys); err != nil {
+			return nil, err
+		}
+		items = append(items, jsonb_object_keys)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getUserChatDebugLoggingEnabled = `-- name: GetUserChatDebugLoggingEnabled :one
-SELECT
-	COALESCE((
-		SELECT value = 'true'
-		FROM user_configs
-		WHERE user_id = $1
-			AND key = 'chat_debug_logging_enabled'
-	), false) :: boolean AS debug_logging_enabled
+const unlinkOIDCUsersByIssuerMismatch = `-- name: UnlinkOIDCUsersByIssuerMismatch :execrows
+UPDATE user_links
+SET linked_id = ''
+FROM users
+WHERE user_links.user_id = users.id
+	AND user_links.login_type = 'oidc'
+	AND user_links.linked_id != ''
+	AND NOT starts_with(user_links.linked_id, $1)
+	AND users.deleted = false
 `
 
-func (q *sqlQuerier) GetUserChatDebugLoggingEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
-	row := q.db.QueryRowContext(ctx, getUserChatDebugLoggingEnabled, userID)
-	var debug_logging_enabled bool
-	err := row.Scan(&debug_logging_enabled)
-	return debug_logging_enabled, err
+// Resets linked_id to ” for OIDC links where the linked_id is non-empty
+// and does not begin with the expected issuer prefix. This allows users to
+// re-authenticate under a new OIDC provider.
+func (q *sqlQuerier) UnlinkOIDCUsersByIssuerMismatch(ctx context.Context, expectedPrefix string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unlinkOIDCUsersByIssuerMismatch, expectedPrefix)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getUserChatPersonalModelOverride = `-- name: GetUserChatPersonalModelOverride :one
-SELECT value AS personal_model_override FROM user_configs
-WHERE user_id = $1
-	AND key = $2
+const updateUserLink = `-- name: UpdateUserLink :one
+UPDATE
+	user_links
+SET
+	oauth_access_token = $1,
+	oauth_access_token_key_id = $2,
+	oauth_refresh_token = $3,
+	oauth_refresh_token_key_id = $4,
+	oauth_expiry = $5,
+	claims = $6
+WHERE
+	user_id = $7 AND login_type = $8 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
 `
 
-type GetUserChatPersonalModelOverrideParams struct {
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-	Key    string    `db:"key" json:"key"`
+type UpdateUserLinkParams struct {
+	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+	Claims                 UserLinkClaims `db:"claims" json:"claims"`
+	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
+	LoginType              LoginType      `db:"login_type" json:"login_type"`
 }
 
-func (q *sqlQuerier) GetUserChatPersonalModelOverride(ctx context.Context, arg GetUserChatPersonalModelOverrideParams) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserChatPersonalModelOverride, arg.UserID, arg.Key)
-	var personal_model_override string
-	err := row.Scan(&personal_model_override)
-	return personal_model_override, err
+func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLink,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+		arg.Claims,
+		arg.UserID,
+		arg.LoginType,
+	)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
+	)
+	return i, err
 }
 
-const getUserCodeDiffDisplayMode = `-- name: GetUserCodeDiffDisplayMode :one
-SELECT
-	value AS code_diff_display_mode
-FROM
-	user_configs
+const updateUserLinkedID = `-- name: UpdateUserLinkedID :one
+UPDATE
+	user_links
+SET
+	linked_id = $1
 WHERE
-	user_id = $1
-	AND key = 'preference_code_diff_display_mode'
+	user_id = $2 AND login_type = $3 AND linked_id = '' RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
 `
 
-func (q *sqlQuerier) GetUserCodeDiffDisplayMode(ctx context.Context, userID uuid.UUID) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserCodeDiffDisplayMode, userID)
-	var code_diff_display_mode string
-	err := row.Scan(&code_diff_display_mode)
-	return code_diff_display_mode, err
+type UpdateUserLinkedIDParams struct {
+	LinkedID  string    `db:"linked_id" json:"linked_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType LoginType `db:"login_type" json:"login_type"`
 }
 
-const getUserCount = `-- name: GetUserCount :one
-SELECT
-	COUNT(*)
-FROM
-	users
-WHERE
-	deleted = false
-  	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
-`
-
-func (q *sqlQuerier) GetUserCount(ctx context.Context, includeSystem bool) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getUserCount, includeSystem)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+// Backfills linked_id for legacy user_links that were created before
+// linked_id tracking was added. Only updates when linked_id is empty
+// to avoid overwriting a valid binding.
+func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
+	)
+	return i, err
 }
 
-const getUserForChatSyntheticAPIKeyByID = `-- name: GetUserForChatSyntheticAPIKeyByID :one
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
-FROM users
-WHERE id = $1::uuid
+const createUserSecret = `-- name: CreateUserSecret :one
+INSERT INTO user_secrets (
+    id,
+    user_id,
+    name,
+    description,
+    value,
+    value_key_id,
+    env_name,
+    file_path
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+) RETURNING id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
 `
 
-func (q *sqlQuerier) GetUserForChatSyntheticAPIKeyByID(ctx context.Context, id uuid.UUID) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserForChatSyntheticAPIKeyByID, id)
-	var i User
+type CreateUserSecretParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	UserID      uuid.UUID      `db:"user_id" json:"user_id"`
+	Name        string         `db:"name" json:"name"`
+	Description string         `db:"description" json:"description"`
+	Value       string         `db:"value" json:"value"`
+	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
+	EnvName     string         `db:"env_name" json:"env_name"`
+	FilePath    string         `db:"file_path" json:"file_path"`
+}
+
+func (q *sqlQuerier) CreateUserSecret(ctx context.Context, arg CreateUserSecretParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, createUserSecret,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.Description,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.EnvName,
+		arg.FilePath,
+	)
+	var i UserSecret
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.EnvName,
+		&i.FilePath,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-		&i.IsServiceAccount,
-		&i.ChatSpendLimitMicros,
+		&i.ValueKeyID,
 	)
 	return i, err
 }
 
-const getUserShellToolDisplayMode = `-- name: GetUserShellToolDisplayMode :one
-SELECT
-	value AS shell_tool_display_mode
-FROM
-	user_configs
-WHERE
-	user_id = $1
-	AND key = 'preference_shell_tool_display_mode'
+const deleteUserSecretByUserIDAndName = `-- name: DeleteUserSecretByUserIDAndName :one
+DELETE FROM user_secrets
+WHERE user_id = $1 AND name = $2
+RETURNING id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
 `
 
-func (q *sqlQuerier) GetUserShellToolDisplayMode(ctx context.Context, userID uuid.UUID) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserShellToolDisplayMode, userID)
-	var shell_tool_display_mode string
-	err := row.Scan(&shell_tool_display_mode)
-	return shell_tool_display_mode, err
+type DeleteUserSecretByUserIDAndNameParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
 }
 
-const getUserTaskNotificationAlertDismissed = `-- name: GetUserTaskNotificationAlertDismissed :one
-SELECT
-	value::boolean as task_notification_alert_dismissed
-FROM
-	user_configs
-WHERE
-	user_id = $1
-	AND key = 'preference_task_notification_alert_dismissed'
+func (q *sqlQuerier) DeleteUserSecretByUserIDAndName(ctx context.Context, arg DeleteUserSecretByUserIDAndNameParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, deleteUserSecretByUserIDAndName, arg.UserID, arg.Name)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.EnvName,
+		&i.FilePath,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ValueKeyID,
+	)
+	return i, err
+}
+
+const getUserSecretByID = `-- name: GetUserSecretByID :one
+SELECT id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
+FROM user_secrets
+WHERE id = $1
 `
 
-func (q *sqlQuerier) GetUserTaskNotificationAlertDismissed(ctx context.Context, userID uuid.UUID) (bool, error) {
-	row := q.db.QueryRowContext(ctx, getUserTaskNotificationAlertDismissed, userID)
-	var task_notification_alert_dismissed bool
-	err := row.Scan(&task_notification_alert_dismissed)
-	return task_notification_alert_dismissed, err
+func (q *sqlQuerier) GetUserSecretByID(ctx context.Context, id uuid.UUID) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, getUserSecretByID, id)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.EnvName,
+		&i.FilePath,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ValueKeyID,
+	)
+	return i, err
 }
 
-const getUserThinkingDisplayMode = `-- name: GetUserThinkingDisplayMode :one
-SELECT
-	value AS thinking_display_mode
-FROM
-	user_configs
-WHERE
-	user_id = $1
-	AND key = 'preference_thinking_display_mode'
+const getUserSecretByUserIDAndName = `-- name: GetUserSecretByUserIDAndName :one
+SELECT id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
+FROM user_secrets
+WHERE user_id = $1 AND name = $2
 `
 
-func (q *sqlQuerier) GetUserThinkingDisplayMode(ctx context.Context, userID uuid.UUID) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserThinkingDisplayMode, userID)
-	var thinking_display_mode string
-	err := row.Scan(&thinking_display_mode)
-	return thinking_display_mode, err
+type GetUserSecretByUserIDAndNameParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
 }
 
-const getUsers = `-- name: GetUsers :many
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros, COUNT(*) OVER() AS count
-FROM
-	users
-WHERE
-	users.deleted = false
-	AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the username field, so select all
-			-- rows after the cursor.
-			(LOWER(username)) > (
-				SELECT
-					LOWER(username)
-				FROM
-					users
-				WHERE
-					id = $1
-			)
-		)
-		ELSE true
-	END
-	-- Start filters
-	-- Filter by email or username
-	AND CASE
-		WHEN $2 :: text != '' THEN (
-			email ILIKE concat('%', $2, '%')
-			OR username ILIKE concat('%', $2, '%')
-		)
-		ELSE true
-	END
-	-- Filter by name (display name)
-	AND CASE
-		WHEN $3 :: text != '' THEN
-			name ILIKE concat('%', $3, '%')
-		ELSE true
-	END
-	-- Filter by exact username
-	AND CASE
-	  WHEN $4 :: text != '' THEN
-		  lower(username) = lower($4)
-	  ELSE true
-	END
-  	-- Filter by exact email
-  	AND CASE
-	  WHEN $5 :: text != '' THEN
-		  lower(email) = lower($5)
-	  ELSE true
-	END
-	-- Filter by status
-	AND CASE
-		-- @status needs to be a text because it can be empty, If it was
-		-- user_status enum, it would not.
-		WHEN cardinality($6 :: user_status[]) > 0 THEN
-			status = ANY($6 :: user_status[])
-		ELSE true
-	END
-	-- Filter by rbac_roles
-	AND CASE
-		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
-		-- everyone is a member.
-		WHEN cardinality($7 :: text[]) > 0 AND 'member' != ANY($7 :: text[]) THEN
-			rbac_roles && $7 :: text[]
-		ELSE true
-	END
-	-- Filter by last_seen
-	AND CASE
-		WHEN $8 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			last_seen_at <= $8
-		ELSE true
-	END
-	AND CASE
-		WHEN $9 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			last_seen_at >= $9
-		ELSE true
-	END
-	-- Filter by created_at
-	AND CASE
-		WHEN $10 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			created_at <= $10
-		ELSE true
-	END
-	AND CASE
-		WHEN $11 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			created_at >= $11
-		ELSE true
-	END
-	-- Filter by system type
-	AND CASE
-		WHEN $12::bool THEN TRUE
-		ELSE is_system = false
-	END
-	-- Filter by github.com user ID
-	AND CASE
-		WHEN $13 :: bigint != 0 THEN
-			github_com_user_id = $13
-		ELSE true
-	END
-	-- Filter by login_type
-	AND CASE
-		WHEN cardinality($14 :: login_type[]) > 0 THEN
-			login_type = ANY($14 :: login_type[])
-		ELSE true
-	END
-	-- Filter by service account.
-	AND CASE
-		WHEN $15 :: boolean IS NOT NULL THEN
-			is_service_account = $15 :: boolean
-		ELSE true
-	END
-	-- End of filters
+func (q *sqlQuerier) GetUserSecretByUserIDAndName(ctx context.Context, arg GetUserSecretByUserIDAndNameParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, getUserSecretByUserIDAndName, arg.UserID, arg.Name)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.EnvName,
+		&i.FilePath,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ValueKeyID,
+	)
+	return i, err
+}
 
-	-- Authorize Filter clause will be injected below in GetAuthorizedUsers
-	-- @authorize_filter
-ORDER BY
-	-- Deterministic and consistent ordering of all users. This is to ensure consistent pagination.
-	LOWER(username) ASC OFFSET $16
-LIMIT
-	-- A null limit means "no limit", so 0 means return all
-	NULLIF($17 :: int, 0)
+const getUserSecretsTelemetrySummary = `-- name: GetUserSecretsTelemetrySummary :one
+WITH active_users AS (
+    SELECT id AS user_id
+    FROM users
+    WHERE deleted = false
+      AND is_system = false
+      AND status = 'active'::user_status
+),
+per_user AS (
+    SELECT au.user_id, COUNT(us.id)::bigint AS n
+    FROM active_users au
+    LEFT JOIN user_secrets us ON us.user_id = au.user_id
+    GROUP BY au.user_id
+),
+secrets_filtered AS (
+    SELECT us.env_name, us.file_path
+    FROM user_secrets us
+    JOIN active_users au ON au.user_id = us.user_id
+)
+SELECT
+    COUNT(*) FILTER (WHERE n > 0)::bigint                                                       AS users_with_secrets,
+    (SELECT COUNT(*) FROM secrets_filtered)::bigint                                             AS total_secrets,
+    (SELECT COUNT(*) FROM secrets_filtered WHERE env_name != '' AND file_path = '' )::bigint    AS env_name_only,
+    (SELECT COUNT(*) FROM secrets_filtered WHERE env_name = ''  AND file_path != '')::bigint    AS file_path_only,
+    (SELECT COUNT(*) FROM secrets_filtered WHERE env_name != '' AND file_path != '')::bigint    AS both,
+    (SELECT COUNT(*) FROM secrets_filtered WHERE env_name = ''  AND file_path = '' )::bigint    AS neither,
+    COALESCE(MAX(n), 0)::bigint                                                                 AS secrets_per_user_max,
+    COALESCE(percentile_disc(0.25) WITHIN GROUP (ORDER BY n), 0)::bigint                        AS secrets_per_user_p25,
+    COALESCE(percentile_disc(0.50) WITHIN GROUP (ORDER BY n), 0)::bigint                        AS secrets_per_user_p50,
+    COALESCE(percentile_disc(0.75) WITHIN GROUP (ORDER BY n), 0)::bigint                        AS secrets_per_user_p75,
+    COALESCE(percentile_disc(0.90) WITHIN GROUP (ORDER BY n), 0)::bigint                        AS secrets_per_user_p90
+FROM per_user
 `
 
-type GetUsersParams struct {
-	AfterID          uuid.UUID    `db:"after_id" json:"after_id"`
-	Search           string       `db:"search" json:"search"`
-	Name             string       `db:"name" json:"name"`
-	ExactUsername    string       `db:"exact_username" json:"exact_username"`
-	ExactEmail       string       `db:"exact_email" json:"exact_email"`
-	Status           []UserStatus `db:"status" json:"status"`
-	RbacRole         []string     `db:"rbac_role" json:"rbac_role"`
-	LastSeenBefore   time.Time    `db:"last_seen_before" json:"last_seen_before"`
-	LastSeenAfter    time.Time    `db:"last_seen_after" json:"last_seen_after"`
-	CreatedBefore    time.Time    `db:"created_before" json:"created_before"`
-	CreatedAfter     time.Time    `db:"created_after" json:"created_after"`
-	IncludeSystem    bool         `db:"include_system" json:"include_system"`
-	GithubComUserID  int64        `db:"github_com_user_id" json:"github_com_user_id"`
-	LoginType        []LoginType  `db:"login_type" json:"login_type"`
-	IsServiceAccount sql.NullBool `db:"is_service_account" json:"is_service_account"`
-	OffsetOpt        int32        `db:"offset_opt" json:"offset_opt"`
-	LimitOpt         int32        `db:"limit_opt" json:"limit_opt"`
+type GetUserSecretsTelemetrySummaryRow struct {
+	UsersWithSecrets  int64 `db:"users_with_secrets" json:"users_with_secrets"`
+	TotalSecrets      int64 `db:"total_secrets" json:"total_secrets"`
+	EnvNameOnly       int64 `db:"env_name_only" json:"env_name_only"`
+	FilePathOnly      int64 `db:"file_path_only" json:"file_path_only"`
+	Both              int64 `db:"both" json:"both"`
+	Neither           int64 `db:"neither" json:"neither"`
+	SecretsPerUserMax int64 `db:"secrets_per_user_max" json:"secrets_per_user_max"`
+	SecretsPerUserP25 int64 `db:"secrets_per_user_p25" json:"secrets_per_user_p25"`
+	SecretsPerUserP50 int64 `db:"secrets_per_user_p50" json:"secrets_per_user_p50"`
+	SecretsPerUserP75 int64 `db:"secrets_per_user_p75" json:"secrets_per_user_p75"`
+	SecretsPerUserP90 int64 `db:"secrets_per_user_p90" json:"secrets_per_user_p90"`
 }
 
-type GetUsersRow struct {
-	ID                       uuid.UUID      `db:"id" json:"id"`
-	Email                    string         `db:"email" json:"email"`
-	Username                 string         `db:"username" json:"username"`
-	HashedPassword           []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt                time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt                time.Time      `db:"updated_at" json:"updated_at"`
-	Status                   UserStatus     `db:"status" json:"status"`
-	RBACRoles                pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType                LoginType      `db:"login_type" json:"login_type"`
-	AvatarURL                string         `db:"avatar_url" json:"avatar_url"`
-	Deleted                  bool           `db:"deleted" json:"deleted"`
-	LastSeenAt               time.Time      `db:"last_seen_at" json:"last_seen_at"`
-	QuietHoursSchedule       string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
-	Name                     string         `db:"name" json:"name"`
-	GithubComUserID          sql.NullInt64  `db:"github_com_user_id" json:"github_com_user_id"`
-	HashedOneTimePasscode    []byte         `db:"hashed_one_time_passcode" json:"hashed_one_time_passcode"`
-	OneTimePasscodeExpiresAt sql.NullTime   `db:"one_time_passcode_expires_at" json:"one_time_passcode_expires_at"`
-	IsSystem                 bool           `db:"is_system" json:"is_system"`
-	IsServiceAccount         bool           `db:"is_service_account" json:"is_service_account"`
-	ChatSpendLimitMicros     sql.NullInt64  `db:"chat_spend_limit_micros" json:"chat_spend_limit_micros"`
-	Count                    int64          `db:"count" json:"count"`
+// Returns deployment-wide aggregates for the telemetry snapshot.
+//
+// The denominator for both user-level counts and the per-user
+// distribution is active non-system users. Specifically:
+//
+//   - deleted = false: Coder soft-deletes by flipping users.deleted
+//     rather than removing rows. The delete_deleted_user_resources()
+//     trigger now removes their user_secrets, but soft-deleted users
+//     are still excluded here so they don't dilute the percentile
+//     distribution as zero-secret entries.
+//   - status = 'active': dormant users (no recent activity) and
+//     suspended users (explicitly disabled) cannot use secrets, so
+//     they shouldn't dilute the percentile distribution as
+//     zero-secret entries.
+//   - is_system = false: internal subjects like the prebuilds user
+//     never use secrets in the normal flow.
+//
+// Status transitions move users in and out of this denominator, so a
+// snapshot's UsersWithSecrets can drop without any secret being
+// deleted.
+//
+// The percentile distribution is computed across all active non-system
+// users, including those with zero secrets, so the percentiles reflect
+// deployment-wide adoption rather than only the power-user subset.
+// percentile_disc returns an actual integer count from the underlying
+// values rather than interpolating between rows.
+func (q *sqlQuerier) GetUserSecretsTelemetrySummary(ctx context.Context) (GetUserSecretsTelemetrySummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserSecretsTelemetrySummary)
+	var i GetUserSecretsTelemetrySummaryRow
+	err := row.Scan(
+		&i.UsersWithSecrets,
+		&i.TotalSecrets,
+		&i.EnvNameOnly,
+		&i.FilePathOnly,
+		&i.Both,
+		&i.Neither,
+		&i.SecretsPerUserMax,
+		&i.SecretsPerUserP25,
+		&i.SecretsPerUserP50,
+		&i.SecretsPerUserP75,
+		&i.SecretsPerUserP90,
+	)
+	return i, err
 }
 
-// This will never return deleted users.
-func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUsers,
-		arg.AfterID,
-		arg.Search,
-		arg.Name,
-		arg.ExactUsername,
-		arg.ExactEmail,
-		pq.Array(arg.Status),
-		pq.Array(arg.RbacRole),
-		arg.LastSeenBefore,
-		arg.LastSeenAfter,
-		arg.CreatedBefore,
-		arg.CreatedAfter,
-		arg.IncludeSystem,
-		arg.GithubComUserID,
-		pq.Array(arg.LoginType),
-		arg.IsServiceAccount,
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
+const listUserSecrets = `-- name: ListUserSecrets :many
+SELECT
+    id, user_id, name, description,
+    env_name, file_path,
+    created_at, updated_at
+FROM user_secrets
+WHERE user_id = $1
+ORDER BY name ASC
+`
+
+type ListUserSecretsRow struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	EnvName     string    `db:"env_name" json:"env_name"`
+	FilePath    string    `db:"file_path" json:"file_path"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Returns metadata only (no value or value_key_id) for the
+// REST API list and get endpoints.
+func (q *sqlQuerier) ListUserSecrets(ctx context.Context, userID uuid.UUID) ([]ListUserSecretsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserSecrets, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUsersRow
+	var items []ListUserSecretsRow
 	for rows.Next() {
-		var i GetUsersRow
+		var i ListUserSecretsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.EnvName,
+			&i.FilePath,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
-			&i.Name,
-			&i.GithubComUserID,
-			&i.HashedOneTimePasscode,
-			&i.OneTimePasscodeExpiresAt,
-			&i.IsSystem,
-			&i.IsServiceAccount,
-			&i.ChatSpendLimitMicros,
-			&i.Count,
 		); err != nil {
 			return nil, err
 		}
@@ -30764,43 +34274,36 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUse
 	return items, nil
 }
 
-const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros FROM users WHERE id = ANY($1 :: uuid [ ])
+const listUserSecretsWithValues = `-- name: ListUserSecretsWithValues :many
+SELECT id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
+FROM user_secrets
+WHERE user_id = $1
+ORDER BY name ASC
 `
 
-// This shouldn't check for deleted, because it's frequently used
-// to look up references to actions. eg. a user could build a workspace
-// for another user, then be deleted... we still want them to appear!
-func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+// Returns all columns including the secret value. Used by the
+// provisioner (build-time injection) and the agent manifest
+// (runtime injection).
+func (q *sqlQuerier) ListUserSecretsWithValues(ctx context.Context, userID uuid.UUID) ([]UserSecret, error) {
+	rows, err := q.db.QueryContext(ctx, listUserSecretsWithValues, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []UserSecret
 	for rows.Next() {
-		var i User
+		var i UserSecret
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.Value,
+			&i.EnvName,
+			&i.FilePath,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
-			&i.Name,
-			&i.GithubComUserID,
-			&i.HashedOneTimePasscode,
-			&i.OneTimePasscodeExpiresAt,
-			&i.IsSystem,
-			&i.IsServiceAccount,
-			&i.ChatSpendLimitMicros,
+			&i.ValueKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -30815,136 +34318,184 @@ func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User
 	return items, nil
 }
 
-const insertUser = `-- name: InsertUser :one
-INSERT INTO
-	users (
-		id,
-		email,
-		username,
-		name,
-		hashed_password,
-		created_at,
-		updated_at,
-		rbac_roles,
-		login_type,
-		status,
-		is_service_account
-	)
-VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9,
-		-- if the status passed in is empty, fallback to dormant, which is what
-		-- we were doing before.
-		COALESCE(NULLIF($10::text, '')::user_status, 'dormant'::user_status),
-		$11::bool
-	) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+const updateUserSecretByUserIDAndName = `-- name: UpdateUserSecretByUserIDAndName :one
+UPDATE user_secrets
+SET
+    value       = CASE WHEN $1::bool THEN $2 ELSE value END,
+    value_key_id = CASE WHEN $1::bool THEN $3 ELSE value_key_id END,
+    description = CASE WHEN $4::bool THEN $5 ELSE description END,
+    env_name    = CASE WHEN $6::bool THEN $7 ELSE env_name END,
+    file_path   = CASE WHEN $8::bool THEN $9 ELSE file_path END,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE user_id = $10 AND name = $11
+RETURNING id, user_id, name, description, value, env_name, file_path, created_at, updated_at, value_key_id
 `
 
-type InsertUserParams struct {
-	ID               uuid.UUID      `db:"id" json:"id"`
-	Email            string         `db:"email" json:"email"`
-	Username         string         `db:"username" json:"username"`
-	Name             string         `db:"name" json:"name"`
-	HashedPassword   []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
-	RBACRoles        pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType        LoginType      `db:"login_type" json:"login_type"`
-	Status           string         `db:"status" json:"status"`
-	IsServiceAccount bool           `db:"is_service_account" json:"is_service_account"`
+type UpdateUserSecretByUserIDAndNameParams struct {
+	UpdateValue       bool           `db:"update_value" json:"update_value"`
+	Value             string         `db:"value" json:"value"`
+	ValueKeyID        sql.NullString `db:"value_key_id" json:"value_key_id"`
+	UpdateDescription bool           `db:"update_description" json:"update_description"`
+	Description       string         `db:"description" json:"description"`
+	UpdateEnvName     bool           `db:"update_env_name" json:"update_env_name"`
+	EnvName           string         `db:"env_name" json:"env_name"`
+	UpdateFilePath    bool           `db:"update_file_path" json:"update_file_path"`
+	FilePath          string         `db:"file_path" json:"file_path"`
+	UserID            uuid.UUID      `db:"user_id" json:"user_id"`
+	Name              string         `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, insertUser,
-		arg.ID,
-		arg.Email,
-		arg.Username,
+func (q *sqlQuerier) UpdateUserSecretByUserIDAndName(ctx context.Context, arg UpdateUserSecretByUserIDAndNameParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateUserSecretByUserIDAndName,
+		arg.UpdateValue,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.UpdateDescription,
+		arg.Description,
+		arg.UpdateEnvName,
+		arg.EnvName,
+		arg.UpdateFilePath,
+		arg.FilePath,
+		arg.UserID,
 		arg.Name,
-		arg.HashedPassword,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.RBACRoles,
-		arg.LoginType,
-		arg.Status,
-		arg.IsServiceAccount,
 	)
-	var i User
+	var i UserSecret
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.EnvName,
+		&i.FilePath,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
+		&i.ValueKeyID,
+	)
+	return i, err
+}
+
+const deleteUserSkillByUserIDAndName = `-- name: DeleteUserSkillByUserIDAndName :one
+DELETE FROM user_skills
+WHERE user_id = $1 AND name = $2
+RETURNING id, user_id, name, description, content, created_at, updated_at
+`
+
+type DeleteUserSkillByUserIDAndNameParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) DeleteUserSkillByUserIDAndName(ctx context.Context, arg DeleteUserSkillByUserIDAndNameParams) (UserSkill, error) {
+	row := q.db.QueryRowContext(ctx, deleteUserSkillByUserIDAndName, arg.UserID, arg.Name)
+	var i UserSkill
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
 		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-		&i.IsServiceAccount,
-		&i.ChatSpendLimitMicros,
+		&i.Description,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const listUserChatCompactionThresholds = `-- name: ListUserChatCompactionThresholds :many
-SELECT user_id, key, value FROM user_configs
-WHERE user_id = $1
-	AND key LIKE 'chat\_compaction\_threshold\_pct:%'
-ORDER BY key
+const getUserSkillByUserIDAndName = `-- name: GetUserSkillByUserIDAndName :one
+SELECT id, user_id, name, description, content, created_at, updated_at
+FROM user_skills
+WHERE user_id = $1 AND name = $2
 `
 
-func (q *sqlQuerier) ListUserChatCompactionThresholds(ctx context.Context, userID uuid.UUID) ([]UserConfig, error) {
-	rows, err := q.db.QueryContext(ctx, listUserChatCompactionThresholds, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []UserConfig
-	for rows.Next() {
-		var i UserConfig
-		if err := rows.Scan(&i.UserID, &i.Key, &i.Value); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type GetUserSkillByUserIDAndNameParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
 }
 
-const listUserChatPersonalModelOverrides = `-- name: ListUserChatPersonalModelOverrides :many
-SELECT key, value FROM user_configs
+func (q *sqlQuerier) GetUserSkillByUserIDAndName(ctx context.Context, arg GetUserSkillByUserIDAndNameParams) (UserSkill, error) {
+	row := q.db.QueryRowContext(ctx, getUserSkillByUserIDAndName, arg.UserID, arg.Name)
+	var i UserSkill
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertUserSkill = `-- name: InsertUserSkill :one
+INSERT INTO user_skills (id, user_id, name, description, content)
+VALUES ($1::uuid, $2::uuid, $3::text, $4::text, $5::text)
+RETURNING id, user_id, name, description, content, created_at, updated_at
+`
+
+type InsertUserSkillParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Content     string    `db:"content" json:"content"`
+}
+
+func (q *sqlQuerier) InsertUserSkill(ctx context.Context, arg InsertUserSkillParams) (UserSkill, error) {
+	row := q.db.QueryRowContext(ctx, insertUserSkill,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.Description,
+		arg.Content,
+	)
+	var i UserSkill
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listUserSkillMetadataByUserID = `-- name: ListUserSkillMetadataByUserID :many
+SELECT
+    id, user_id, name, description, created_at, updated_at
+FROM user_skills
 WHERE user_id = $1
-	AND key LIKE 'chat\_personal\_model\_override:%'
-ORDER BY key
+ORDER BY name ASC
 `
 
-type ListUserChatPersonalModelOverridesRow struct {
-	Key   string `db:"key" json:"key"`
-	Value string `db:"value" json:"value"`
+type ListUserSkillMetadataByUserIDRow struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) ListUserChatPersonalModelOverrides(ctx context.Context, userID uuid.UUID) ([]ListUserChatPersonalModelOverridesRow, error) {
-	rows, err := q.db.QueryContext(ctx, listUserChatPersonalModelOverrides, userID)
+func (q *sqlQuerier) ListUserSkillMetadataByUserID(ctx context.Context, userID uuid.UUID) ([]ListUserSkillMetadataByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserSkillMetadataByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListUserChatPersonalModelOverridesRow
+	var items []ListUserSkillMetadataByUserIDRow
 	for rows.Next() {
-		var i ListUserChatPersonalModelOverridesRow
-		if err := rows.Scan(&i.Key, &i.Value); err != nil {
+		var i ListUserSkillMetadataByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -30958,49 +34509,62 @@ func (q *sqlQuerier) ListUserChatPersonalModelOverrides(ctx context.Context, use
 	return items, nil
 }
 
-const updateInactiveUsersToDormant = `-- name: UpdateInactiveUsersToDormant :many
-UPDATE
-    users
+const updateUserSkillByUserIDAndName = `-- name: UpdateUserSkillByUserIDAndName :one
+UPDATE user_skills
 SET
-    status = 'dormant'::user_status,
-    updated_at = $1
-WHERE
-    last_seen_at < $2 :: timestamp
-    AND status = 'active'::user_status
-		AND NOT is_system
-RETURNING id, email, username, last_seen_at
+    description = $1,
+    content     = $2,
+    updated_at  = now()
+WHERE user_id = $3 AND name = $4
+RETURNING id, user_id, name, description, content, created_at, updated_at
 `
 
-type UpdateInactiveUsersToDormantParams struct {
-	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
-	LastSeenAfter time.Time `db:"last_seen_after" json:"last_seen_after"`
+type UpdateUserSkillByUserIDAndNameParams struct {
+	Description string    `db:"description" json:"description"`
+	Content     string    `db:"content" json:"content"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Name        string    `db:"name" json:"name"`
 }
 
-type UpdateInactiveUsersToDormantRow struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	Email      string    `db:"email" json:"email"`
-	Username   string    `db:"username" json:"username"`
-	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+func (q *sqlQuerier) UpdateUserSkillByUserIDAndName(ctx context.Context, arg UpdateUserSkillByUserIDAndNameParams) (UserSkill, error) {
+	row := q.db.QueryRowContext(ctx, updateUserSkillByUserIDAndName,
+		arg.Description,
+		arg.Content,
+		arg.UserID,
+		arg.Name,
+	)
+	var i UserSkill
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error) {
-	rows, err := q.db.QueryContext(ctx, updateInactiveUsersToDormant, arg.UpdatedAt, arg.LastSeenAfter)
+const allUserIDs = `-- name: AllUserIDs :many
+SELECT DISTINCT id FROM USERS
+	WHERE CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+`
+
+// AllUserIDs returns all UserIDs regardless of user status or deletion.
+func (q *sqlQuerier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, allUserIDs, includeSystem)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UpdateInactiveUsersToDormantRow
+	var items []uuid.UUID
 	for rows.Next() {
-		var i UpdateInactiveUsersToDormantRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.LastSeenAt,
-		); err != nil {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, id)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -31011,252 +34575,201 @@ func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg Updat
 	return items, nil
 }
 
-const updateUserAgentChatSendShortcut = `-- name: UpdateUserAgentChatSendShortcut :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'preference_agent_chat_send_shortcut', $2::text)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'preference_agent_chat_send_shortcut'
-RETURNING value AS agent_chat_send_shortcut
+const deleteUserChatCompactionThreshold = `-- name: DeleteUserChatCompactionThreshold :exec
+DELETE FROM user_configs WHERE user_id = $1 AND key = $2
 `
 
-type UpdateUserAgentChatSendShortcutParams struct {
-	UserID                uuid.UUID `db:"user_id" json:"user_id"`
-	AgentChatSendShortcut string    `db:"agent_chat_send_shortcut" json:"agent_chat_send_shortcut"`
+type DeleteUserChatCompactionThresholdParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Key    string    `db:"key" json:"key"`
 }
 
-func (q *sqlQuerier) UpdateUserAgentChatSendShortcut(ctx context.Context, arg UpdateUserAgentChatSendShortcutParams) (string, error) {
-	row := q.db.QueryRowContext(ctx, updateUserAgentChatSendShortcut, arg.UserID, arg.AgentChatSendShortcut)
-	var agent_chat_send_shortcut string
-	err := row.Scan(&agent_chat_send_shortcut)
-	return agent_chat_send_shortcut, err
+func (q *sqlQuerier) DeleteUserChatCompactionThreshold(ctx context.Context, arg DeleteUserChatCompactionThresholdParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserChatCompactionThreshold, arg.UserID, arg.Key)
+	return err
 }
 
-const updateUserChatCompactionThreshold = `-- name: UpdateUserChatCompactionThreshold :one
-INSERT INTO user_configs (user_id, key, value)
-VALUES ($1, $2, ($3::int)::text)
-ON CONFLICT ON CONSTRAINT user_configs_pkey
-DO UPDATE SET value = ($3::int)::text
-RETURNING user_id, key, value
+const getActiveUserCount = `-- name: GetActiveUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	status = 'active'::user_status AND deleted = false
+	AND is_service_account = false
+	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
 `
 
-type UpdateUserChatCompactionThresholdParams struct {
-	UserID           uuid.UUID `db:"user_id" json:"user_id"`
-	Key              string    `db:"key" json:"key"`
-	ThresholdPercent int32     `db:"threshold_percent" json:"threshold_percent"`
-}
-
-func (q *sqlQuerier) UpdateUserChatCompactionThreshold(ctx context.Context, arg UpdateUserChatCompactionThresholdParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserChatCompactionThreshold, arg.UserID, arg.Key, arg.ThresholdPercent)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
-	return i, err
+func (q *sqlQuerier) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getActiveUserCount, includeSystem)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-const updateUserChatCustomPrompt = `-- name: UpdateUserChatCustomPrompt :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'chat_custom_prompt', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'chat_custom_prompt'
-RETURNING user_id, key, value
+const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
+SELECT
+	-- username and email are returned just to help for logging purposes
+	-- status is used to enforce 'suspended' users, as all roles are ignored
+	--	when suspended.
+	id, username, status, email,
+	-- All user roles, including their org roles.
+	array_cat(
+		-- All users are members
+		array_append(users.rbac_roles, 'member'),
+		(
+			SELECT
+				-- The roles are returned as a flat array, org scoped and site side.
+				-- Concatenating the organization id scopes the organization roles.
+				array_agg(org_roles || ':' || organization_members.organization_id::text)
+			FROM
+				organization_members
+				JOIN organizations ON organizations.id = organization_members.organization_id,
+				-- All org members get an implied role for their orgs. Most members
+				-- get organization-member, but service accounts will get
+				-- organization-service-account instead. They're largely the same,
+				-- but having them be distinct means we can allow configuring
+				-- service-accounts to have slightly broader permissions, such as
+				-- for workspace sharing.
+				--
+				-- organizations.default_org_member_roles is unioned in so changes
+				-- to org defaults propagate to every member on the next request.
+				unnest(
+					array_cat(
+						array_append(
+							roles,
+							CASE WHEN users.is_service_account THEN
+								'organization-service-account'
+							ELSE
+								'organization-member'
+							END
+						),
+						organizations.default_org_member_roles
+					)
+				) AS org_roles
+			WHERE
+				user_id = users.id
+		)
+	) :: text[] AS roles,
+	-- All groups the user is in.
+	(
+		SELECT
+			array_agg(
+				group_members.group_id :: text
+			)
+		FROM
+			group_members
+		WHERE
+			user_id = users.id
+	) :: text[] AS groups
+FROM
+	users
+WHERE
+	users.id = $1
 `
 
-type UpdateUserChatCustomPromptParams struct {
-	UserID           uuid.UUID `db:"user_id" json:"user_id"`
-	ChatCustomPrompt string    `db:"chat_custom_prompt" json:"chat_custom_prompt"`
+type GetAuthorizationUserRolesRow struct {
+	ID       uuid.UUID  `db:"id" json:"id"`
+	Username string     `db:"username" json:"username"`
+	Status   UserStatus `db:"status" json:"status"`
+	Email    string     `db:"email" json:"email"`
+	Roles    []string   `db:"roles" json:"roles"`
+	Groups   []string   `db:"groups" json:"groups"`
 }
 
-func (q *sqlQuerier) UpdateUserChatCustomPrompt(ctx context.Context, arg UpdateUserChatCustomPromptParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserChatCustomPrompt, arg.UserID, arg.ChatCustomPrompt)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+// This function returns roles for authorization purposes. Implied member roles
+// are included.
+func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error) {
+	row := q.db.QueryRowContext(ctx, getAuthorizationUserRoles, userID)
+	var i GetAuthorizationUserRolesRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Status,
+		&i.Email,
+		pq.Array(&i.Roles),
+		pq.Array(&i.Groups),
+	)
 	return i, err
 }
 
-const updateUserCodeDiffDisplayMode = `-- name: UpdateUserCodeDiffDisplayMode :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'preference_code_diff_display_mode', $2::text)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'preference_code_diff_display_mode'
-RETURNING value AS code_diff_display_mode
+const getUserAgentChatSendShortcut = `-- name: GetUserAgentChatSendShortcut :one
+SELECT
+	value AS agent_chat_send_shortcut
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'preference_agent_chat_send_shortcut'
 `
 
-type UpdateUserCodeDiffDisplayModeParams struct {
-	UserID              uuid.UUID `db:"user_id" json:"user_id"`
-	CodeDiffDisplayMode string    `db:"code_diff_display_mode" json:"code_diff_display_mode"`
-}
-
-func (q *sqlQuerier) UpdateUserCodeDiffDisplayMode(ctx context.Context, arg UpdateUserCodeDiffDisplayModeParams) (string, error) {
-	row := q.db.QueryRowContext(ctx, updateUserCodeDiffDisplayMode, arg.UserID, arg.CodeDiffDisplayMode)
-	var code_diff_display_mode string
-	err := row.Scan(&code_diff_display_mode)
-	return code_diff_display_mode, err
+func (q *sqlQuerier) GetUserAgentChatSendShortcut(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserAgentChatSendShortcut, userID)
+	var agent_chat_send_shortcut string
+	err := row.Scan(&agent_chat_send_shortcut)
+	return agent_chat_send_shortcut, err
 }
 
-const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
-UPDATE
-	users
-SET
-	deleted = true
+const getUserAppearanceSettings = `-- name: GetUserAppearanceSettings :one
+SELECT
+	COALESCE(MAX(value) FILTER (WHERE key = 'theme_preference'), '')::text AS theme_preference,
+	COALESCE(MAX(value) FILTER (WHERE key = 'theme_mode'), '')::text AS theme_mode,
+	COALESCE(MAX(value) FILTER (WHERE key = 'theme_light'), '')::text AS theme_light,
+	COALESCE(MAX(value) FILTER (WHERE key = 'theme_dark'), '')::text AS theme_dark,
+	COALESCE(MAX(value) FILTER (WHERE key = 'terminal_font'), '')::text AS terminal_font
+FROM
+	user_configs
 WHERE
-	id = $1
+	user_id = $1
+	AND key IN (
+		'theme_preference',
+		'theme_mode',
+		'theme_light',
+		'theme_dark',
+		'terminal_font'
+	)
 `
 
-func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, id)
-	return err
+type GetUserAppearanceSettingsRow struct {
+	ThemePreference string `db:"theme_preference" json:"theme_preference"`
+	ThemeMode       string `db:"theme_mode" json:"theme_mode"`
+	ThemeLight      string `db:"theme_light" json:"theme_light"`
+	ThemeDark       string `db:"theme_dark" json:"theme_dark"`
+	TerminalFont    string `db:"terminal_font" json:"terminal_font"`
 }
 
-const updateUserGithubComUserID = `-- name: UpdateUserGithubComUserID :exec
-UPDATE
-	users
-SET
-	github_com_user_id = $2
-WHERE
-	id = $1
-`
-
-type UpdateUserGithubComUserIDParams struct {
-	ID              uuid.UUID     `db:"id" json:"id"`
-	GithubComUserID sql.NullInt64 `db:"github_com_user_id" json:"github_com_user_id"`
-}
-
-func (q *sqlQuerier) UpdateUserGithubComUserID(ctx context.Context, arg UpdateUserGithubComUserIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserGithubComUserID, arg.ID, arg.GithubComUserID)
-	return err
-}
-
-const updateUserHashedOneTimePasscode = `-- name: UpdateUserHashedOneTimePasscode :exec
-UPDATE
-    users
-SET
-    hashed_one_time_passcode = $2,
-    one_time_passcode_expires_at = $3
-WHERE
-    id = $1
-`
-
-type UpdateUserHashedOneTimePasscodeParams struct {
-	ID                       uuid.UUID    `db:"id" json:"id"`
-	HashedOneTimePasscode    []byte       `db:"hashed_one_time_passcode" json:"hashed_one_time_passcode"`
-	OneTimePasscodeExpiresAt sql.NullTime `db:"one_time_passcode_expires_at" json:"one_time_passcode_expires_at"`
-}
-
-func (q *sqlQuerier) UpdateUserHashedOneTimePasscode(ctx context.Context, arg UpdateUserHashedOneTimePasscodeParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserHashedOneTimePasscode, arg.ID, arg.HashedOneTimePasscode, arg.OneTimePasscodeExpiresAt)
-	return err
-}
-
-const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
-UPDATE
-	users
-SET
-	hashed_password = $2,
-	hashed_one_time_passcode = NULL,
-	one_time_passcode_expires_at = NULL
-WHERE
-	id = $1
-`
-
-type UpdateUserHashedPasswordParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
-}
-
-func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
-	return err
-}
-
-const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
-UPDATE
-	users
-SET
-	last_seen_at = $2,
-	updated_at = $3
-WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
-`
-
-type UpdateUserLastSeenAtParams struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
-}
-
-func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
-	var i User
+func (q *sqlQuerier) GetUserAppearanceSettings(ctx context.Context, userID uuid.UUID) (GetUserAppearanceSettingsRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserAppearanceSettings, userID)
+	var i GetUserAppearanceSettingsRow
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-		&i.IsServiceAccount,
-		&i.ChatSpendLimitMicros,
+		&i.ThemePreference,
+		&i.ThemeMode,
+		&i.ThemeLight,
+		&i.ThemeDark,
+		&i.TerminalFont,
 	)
 	return i, err
 }
 
-const updateUserLoginType = `-- name: UpdateUserLoginType :one
-UPDATE
+const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+FROM
 	users
-SET
-	login_type = $1,
-	hashed_password = CASE WHEN $1 = 'password' :: login_type THEN
-		users.hashed_password
-	ELSE
-		-- If the login type is not password, then the password should be
-        -- cleared.
-		'':: bytea
-	END
 WHERE
-	id = $2
-	AND NOT is_system
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+	(LOWER(username) = LOWER($1) OR ($2 != '' AND LOWER(email) = LOWER($2))) AND
+	deleted = false
+LIMIT
+	1
 `
 
-type UpdateUserLoginTypeParams struct {
-	NewLoginType LoginType `db:"new_login_type" json:"new_login_type"`
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+type GetUserByEmailOrUsernameParams struct {
+	Username string      `db:"username" json:"username"`
+	Email    interface{} `db:"email" json:"email"`
 }
 
-func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLoginType, arg.NewLoginType, arg.UserID)
+func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmailOrUsername, arg.Username, arg.Email)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -31283,38 +34796,19 @@ func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLogi
 	return i, err
 }
 
-const updateUserProfile = `-- name: UpdateUserProfile :one
-UPDATE
+const getUserByID = `-- name: GetUserByID :one
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+FROM
 	users
-SET
-	email = $2,
-	username = $3,
-	avatar_url = $4,
-	updated_at = $5,
-	name = $6
 WHERE
 	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+LIMIT
+	1
 `
 
-type UpdateUserProfileParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Email     string    `db:"email" json:"email"`
-	Username  string    `db:"username" json:"username"`
-	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
-	Name      string    `db:"name" json:"name"`
-}
-
-func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserProfile,
-		arg.ID,
-		arg.Email,
-		arg.Username,
-		arg.AvatarURL,
-		arg.UpdatedAt,
-		arg.Name,
-	)
+func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -31341,146 +34835,117 @@ func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfil
 	return i, err
 }
 
-const updateUserQuietHoursSchedule = `-- name: UpdateUserQuietHoursSchedule :one
-UPDATE
-	users
-SET
-	quiet_hours_schedule = $2
-WHERE
-	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+const getUserChatCompactionThreshold = `-- name: GetUserChatCompactionThreshold :one
+SELECT value AS threshold_percent FROM user_configs
+WHERE user_id = $1 AND key = $2
 `
 
-type UpdateUserQuietHoursScheduleParams struct {
-	ID                 uuid.UUID `db:"id" json:"id"`
-	QuietHoursSchedule string    `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+type GetUserChatCompactionThresholdParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Key    string    `db:"key" json:"key"`
 }
 
-func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserQuietHoursSchedule, arg.ID, arg.QuietHoursSchedule)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-		&i.IsServiceAccount,
-		&i.ChatSpendLimitMicros,
-	)
-	return i, err
+func (q *sqlQuerier) GetUserChatCompactionThreshold(ctx context.Context, arg GetUserChatCompactionThresholdParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserChatCompactionThreshold, arg.UserID, arg.Key)
+	var threshold_percent string
+	err := row.Scan(&threshold_percent)
+	return threshold_percent, err
 }
 
-const updateUserRoles = `-- name: UpdateUserRoles :one
-UPDATE
-	users
-SET
-	-- Remove all duplicates from the roles.
-	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+const getUserChatCustomPrompt = `-- name: GetUserChatCustomPrompt :one
+SELECT
+	value as chat_custom_prompt
+FROM
+	user_configs
 WHERE
-	id = $2
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+	user_id = $1
+	AND key = 'chat_custom_prompt'
 `
 
-type UpdateUserRolesParams struct {
-	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
-	ID           uuid.UUID `db:"id" json:"id"`
+func (q *sqlQuerier) GetUserChatCustomPrompt(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserChatCustomPrompt, userID)
+	var chat_custom_prompt string
+	err := row.Scan(&chat_custom_prompt)
+	return chat_custom_prompt, err
 }
 
-func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-		&i.IsServiceAccount,
-		&i.ChatSpendLimitMicros,
-	)
-	return i, err
+const getUserChatDebugLoggingEnabled = `-- name: GetUserChatDebugLoggingEnabled :one
+SELECT
+	COALESCE((
+		SELECT value = 'true'
+		FROM user_configs
+		WHERE user_id = $1
+			AND key = 'chat_debug_logging_enabled'
+	), false) :: boolean AS debug_logging_enabled
+`
+
+func (q *sqlQuerier) GetUserChatDebugLoggingEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getUserChatDebugLoggingEnabled, userID)
+	var debug_logging_enabled bool
+	err := row.Scan(&debug_logging_enabled)
+	return debug_logging_enabled, err
 }
 
-const updateUserShellToolDisplayMode = `-- name: UpdateUserShellToolDisplayMode :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'preference_shell_tool_display_mode', $2::text)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'preference_shell_tool_display_mode'
-RETURNING value AS shell_tool_display_mode
+const getUserChatPersonalModelOverride = `-- name: GetUserChatPersonalModelOverride :one
+SELECT value AS personal_model_override FROM user_configs
+WHERE user_id = $1
+	AND key = $2
 `
 
-type UpdateUserShellToolDisplayModeParams struct {
-	UserID               uuid.UUID `db:"user_id" json:"user_id"`
-	ShellToolDisplayMode string    `db:"shell_tool_display_mode" json:"shell_tool_display_mode"`
+type GetUserChatPersonalModelOverrideParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Key    string    `db:"key" json:"key"`
 }
 
-func (q *sqlQuerier) UpdateUserShellToolDisplayMode(ctx context.Context, arg UpdateUserShellToolDisplayModeParams) (string, error) {
-	row := q.db.QueryRowContext(ctx, updateUserShellToolDisplayMode, arg.UserID, arg.ShellToolDisplayMode)
-	var shell_tool_display_mode string
-	err := row.Scan(&shell_tool_display_mode)
-	return shell_tool_display_mode, err
+func (q *sqlQuerier) GetUserChatPersonalModelOverride(ctx context.Context, arg GetUserChatPersonalModelOverrideParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserChatPersonalModelOverride, arg.UserID, arg.Key)
+	var personal_model_override string
+	err := row.Scan(&personal_model_override)
+	return personal_model_override, err
 }
 
-const updateUserStatus = `-- name: UpdateUserStatus :one
-UPDATE
+const getUserCodeDiffDisplayMode = `-- name: GetUserCodeDiffDisplayMode :one
+SELECT
+	value AS code_diff_display_mode
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'preference_code_diff_display_mode'
+`
+
+func (q *sqlQuerier) GetUserCodeDiffDisplayMode(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserCodeDiffDisplayMode, userID)
+	var code_diff_display_mode string
+	err := row.Scan(&code_diff_display_mode)
+	return code_diff_display_mode, err
+}
+
+const getUserCount = `-- name: GetUserCount :one
+SELECT
+	COUNT(*)
+FROM
 	users
-SET
-	status = $2,
-	updated_at = $3,
-	-- If the user is logging in, set last_seen_at to updated_at.
-	last_seen_at = CASE WHEN $4 :: boolean THEN $3 :: timestamptz ELSE last_seen_at END
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+	deleted = false
+  	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
 `
 
-type UpdateUserStatusParams struct {
-	ID         uuid.UUID  `db:"id" json:"id"`
-	Status     UserStatus `db:"status" json:"status"`
-	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
-	UserIsSeen bool       `db:"user_is_seen" json:"user_is_seen"`
+func (q *sqlQuerier) GetUserCount(ctx context.Context, includeSystem bool) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUserCount, includeSystem)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserStatus,
-		arg.ID,
-		arg.Status,
-		arg.UpdatedAt,
-		arg.UserIsSeen,
-	)
+const getUserForChatSyntheticAPIKeyByID = `-- name: GetUserForChatSyntheticAPIKeyByID :one
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+FROM users
+WHERE id = $1::uuid
+`
+
+func (q *sqlQuerier) GetUserForChatSyntheticAPIKeyByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserForChatSyntheticAPIKeyByID, id)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -31507,339 +34972,4332 @@ func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusP
 	return i, err
 }
 
-const updateUserTaskNotificationAlertDismissed = `-- name: UpdateUserTaskNotificationAlertDismissed :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'preference_task_notification_alert_dismissed', ($2::boolean)::text)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'preference_task_notification_alert_dismissed'
-RETURNING value::boolean AS task_notification_alert_dismissed
+const getUserShellToolDisplayMode = `-- name: GetUserShellToolDisplayMode :one
+SELECT
+	value AS shell_tool_display_mode
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'preference_shell_tool_display_mode'
 `
 
-type UpdateUserTaskNotificationAlertDismissedParams struct {
-	UserID                         uuid.UUID `db:"user_id" json:"user_id"`
-	TaskNotificationAlertDismissed bool      `db:"task_notification_alert_dismissed" json:"task_notification_alert_dismissed"`
+func (q *sqlQuerier) GetUserShellToolDisplayMode(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserShellToolDisplayMode, userID)
+	var shell_tool_display_mode string
+	err := row.Scan(&shell_tool_display_mode)
+	return shell_tool_display_mode, err
 }
 
-func (q *sqlQuerier) UpdateUserTaskNotificationAlertDismissed(ctx context.Context, arg UpdateUserTaskNotificationAlertDismissedParams) (bool, error) {
-	row := q.db.QueryRowContext(ctx, updateUserTaskNotificationAlertDismissed, arg.UserID, arg.TaskNotificationAlertDismissed)
+const getUserTaskNotificationAlertDismissed = `-- name: GetUserTaskNotificationAlertDismissed :one
+SELECT
+	value::boolean as task_notification_alert_dismissed
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'preference_task_notification_alert_dismissed'
+`
+
+func (q *sqlQuerier) GetUserTaskNotificationAlertDismissed(ctx context.Context, userID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getUserTaskNotificationAlertDismissed, userID)
 	var task_notification_alert_dismissed bool
 	err := row.Scan(&task_notification_alert_dismissed)
 	return task_notification_alert_dismissed, err
 }
 
-const updateUserTerminalFont = `-- name: UpdateUserTerminalFont :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'terminal_font', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'terminal_font'
-RETURNING user_id, key, value
-`
-
-type UpdateUserTerminalFontParams struct {
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
-	TerminalFont string    `db:"terminal_font" json:"terminal_font"`
-}
-
-func (q *sqlQuerier) UpdateUserTerminalFont(ctx context.Context, arg UpdateUserTerminalFontParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserTerminalFont, arg.UserID, arg.TerminalFont)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
-	return i, err
-}
-
-const updateUserThemeDark = `-- name: UpdateUserThemeDark :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'theme_dark', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'theme_dark'
-RETURNING user_id, key, value
+const getUserThinkingDisplayMode = `-- name: GetUserThinkingDisplayMode :one
+SELECT
+	value AS thinking_display_mode
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'preference_thinking_display_mode'
 `
 
-type UpdateUserThemeDarkParams struct {
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	ThemeDark string    `db:"theme_dark" json:"theme_dark"`
-}
-
-func (q *sqlQuerier) UpdateUserThemeDark(ctx context.Context, arg UpdateUserThemeDarkParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserThemeDark, arg.UserID, arg.ThemeDark)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
-	return i, err
+func (q *sqlQuerier) GetUserThinkingDisplayMode(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserThinkingDisplayMode, userID)
+	var thinking_display_mode string
+	err := row.Scan(&thinking_display_mode)
+	return thinking_display_mode, err
 }
 
-const updateUserThemeLight = `-- name: UpdateUserThemeLight :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'theme_light', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'theme_light'
-RETURNING user_id, key, value
+const getUserWorkspaceSelfRequestedBuildsAllowed = `-- name: GetUserWorkspaceSelfRequestedBuildsAllowed :one
+SELECT
+	COALESCE((
+		SELECT value = 'true'
+		FROM user_configs
+		WHERE user_id = $1
+			AND key = 'preference_workspace_self_requested_builds_allowed'
+	), false) :: boolean AS workspace_self_requested_builds_allowed
 `
 
-type UpdateUserThemeLightParams struct {
-	UserID     uuid.UUID `db:"user_id" json:"user_id"`
-	ThemeLight string    `db:"theme_light" json:"theme_light"`
-}
-
-func (q *sqlQuerier) UpdateUserThemeLight(ctx context.Context, arg UpdateUserThemeLightParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserThemeLight, arg.UserID, arg.ThemeLight)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
-	return i, err
+func (q *sqlQuerier) GetUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, userID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getUserWorkspaceSelfRequestedBuildsAllowed, userID)
+	var workspace_self_requested_builds_allowed bool
+	err := row.Scan(&workspace_self_requested_builds_allowed)
+	return workspace_self_requested_builds_allowed, err
 }
 
-const updateUserThemeMode = `-- name: UpdateUserThemeMode :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'theme_mode', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
+const getUsers = `-- name: GetUsers :many
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros, COUNT(*) OVER() AS count
+FROM
+	users
+WHERE
+	users.deleted = false
+	AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the username field, so select all
+			-- rows after the cursor.
+			(LOWER(username)) > (
+				SELECT
+					LOWER(username)
+				FROM
+					users
+				WHERE
+					id = $1
+			)
+		)
+		ELSE true
+	END
+	-- Start filters
+	-- Filter by email or username
+	AND CASE
+		WHEN $2 :: text != '' THEN (
+			email ILIKE concat('%', $2, '%')
+			OR username ILIKE concat('%', $2, '%')
+		)
+		ELSE true
+	END
+	-- Filter by name (display name)
+	AND CASE
+		WHEN $3 :: text != '' THEN
+			name ILIKE concat('%', $3, '%')
+		ELSE true
+	END
+	-- Filter by exact username
+	AND CASE
+	  WHEN $4 :: text != '' THEN
+		  lower(username) = lower($4)
+	  ELSE true
+	END
+  	-- Filter by exact email
+  	AND CASE
+	  WHEN $5 :: text != '' THEN
+		  lower(email) = lower($5)
+	  ELSE true
+	END
+	-- Filter by status
+	AND CASE
+		-- @status needs to be a text because it can be empty, If it was
+		-- user_status enum, it would not.
+		WHEN cardinality($6 :: user_status[]) > 0 THEN
+			status = ANY($6 :: user_status[])
+		ELSE true
+	END
+	-- Filter by rbac_roles
+	AND CASE
+		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
+		-- everyone is a member.
+		WHEN cardinality($7 :: text[]) > 0 AND 'member' != ANY($7 :: text[]) THEN
+			rbac_roles && $7 :: text[]
+		ELSE true
+	END
+	-- Filter by last_seen
+	AND CASE
+		WHEN $8 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at <= $8
+		ELSE true
+	END
+	AND CASE
+		WHEN $9 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at >= $9
+		ELSE true
+	END
+	-- Filter by created_at
+	AND CASE
+		WHEN $10 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			created_at <= $10
+		ELSE true
+	END
+	AND CASE
+		WHEN $11 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			created_at >= $11
+		ELSE true
+	END
+	-- Filter by system type
+	AND CASE
+		WHEN $12::bool THEN TRUE
+		ELSE is_system = false
+	END
+	-- Filter by github.com user ID
+	AND CASE
+		WHEN $13 :: bigint != 0 THEN
+			github_com_user_id = $13
+		ELSE true
+	END
+	-- Filter by login_type
+	AND CASE
+		WHEN cardinality($14 :: login_type[]) > 0 THEN
+			login_type = ANY($14 :: login_type[])
+		ELSE true
+	END
+	-- Filter by service account.
+	AND CASE
+		WHEN $15 :: boolean IS NOT NULL THEN
+			is_service_account = $15 :: boolean
+		ELSE true
+	END
+	-- End of filters
+
+	-- Authorize Filter clause will be injected below in GetAuthorizedUsers
+	-- @authorize_filter
+ORDER BY
+	-- Deterministic and consistent ordering of all users. This is to ensure consistent pagination.
+	LOWER(username) ASC OFFSET $16
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($17 :: int, 0)
+`
+
+type GetUsersParams struct {
+	AfterID          uuid.UUID    `db:"after_id" json:"after_id"`
+	Search           string       `db:"search" json:"search"`
+	Name             string       `db:"name" json:"name"`
+	ExactUsername    string       `db:"exact_username" json:"exact_username"`
+	ExactEmail       string       `db:"exact_email" json:"exact_email"`
+	Status           []UserStatus `db:"status" json:"status"`
+	RbacRole         []string     `db:"rbac_role" json:"rbac_role"`
+	LastSeenBefore   time.Time    `db:"last_seen_before" json:"last_seen_before"`
+	LastSeenAfter    time.Time    `db:"last_seen_after" json:"last_seen_after"`
+	CreatedBefore    time.Time    `db:"created_before" json:"created_before"`
+	CreatedAfter     time.Time    `db:"created_after" json:"created_after"`
+	IncludeSystem    bool         `db:"include_system" json:"include_system"`
+	GithubComUserID  int64        `db:"github_com_user_id" json:"github_com_user_id"`
+	LoginType        []LoginType  `db:"login_type" json:"login_type"`
+	IsServiceAccount sql.NullBool `db:"is_service_account" json:"is_service_account"`
+	OffsetOpt        int32        `db:"offset_opt" json:"offset_opt"`
+	LimitOpt         int32        `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetUsersRow struct {
+	ID                       uuid.UUID      `db:"id" json:"id"`
+	Email                    string         `db:"email" json:"email"`
+	Username                 string         `db:"username" json:"username"`
+	HashedPassword           []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt                time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt                time.Time      `db:"updated_at" json:"updated_at"`
+	Status                   UserStatus     `db:"status" json:"status"`
+	RBACRoles                pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType                LoginType      `db:"login_type" json:"login_type"`
+	AvatarURL                string         `db:"avatar_url" json:"avatar_url"`
+	Deleted                  bool           `db:"deleted" json:"deleted"`
+	LastSeenAt               time.Time      `db:"last_seen_at" json:"last_seen_at"`
+	QuietHoursSchedule       string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+	Name                     string         `db:"name" json:"name"`
+	GithubComUserID          sql.NullInt64  `db:"github_com_user_id" json:"github_com_user_id"`
+	HashedOneTimePasscode    []byte         `db:"hashed_one_time_passcode" json:"hashed_one_time_passcode"`
+	OneTimePasscodeExpiresAt sql.NullTime   `db:"one_time_passcode_expires_at" json:"one_time_passcode_expires_at"`
+	IsSystem                 bool           `db:"is_system" json:"is_system"`
+	IsServiceAccount         bool           `db:"is_service_account" json:"is_service_account"`
+	ChatSpendLimitMicros     sql.NullInt64  `db:"chat_spend_limit_micros" json:"chat_spend_limit_micros"`
+	Count                    int64          `db:"count" json:"count"`
+}
+
+// This will never return deleted users.
+func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers,
+		arg.AfterID,
+		arg.Search,
+		arg.Name,
+		arg.ExactUsername,
+		arg.ExactEmail,
+		pq.Array(arg.Status),
+		pq.Array(arg.RbacRole),
+		arg.LastSeenBefore,
+		arg.LastSeenAfter,
+		arg.CreatedBefore,
+		arg.CreatedAfter,
+		arg.IncludeSystem,
+		arg.GithubComUserID,
+		pq.Array(arg.LoginType),
+		arg.IsServiceAccount,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersRow
+	for rows.Next() {
+		var i GetUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.Name,
+			&i.GithubComUserID,
+			&i.HashedOneTimePasscode,
+			&i.OneTimePasscodeExpiresAt,
+			&i.IsSystem,
+			&i.IsServiceAccount,
+			&i.ChatSpendLimitMicros,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros FROM users WHERE id = ANY($1 :: uuid [ ])
+`
+
+// This shouldn't check for deleted, because it's frequently used
+// to look up references to actions. eg. a user could build a workspace
+// for another user, then be deleted... we still want them to appear!
+func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.Name,
+			&i.GithubComUserID,
+			&i.HashedOneTimePasscode,
+			&i.OneTimePasscodeExpiresAt,
+			&i.IsSystem,
+			&i.IsServiceAccount,
+			&i.ChatSpendLimitMicros,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUser = `-- name: InsertUser :one
+INSERT INTO
+	users (
+		id,
+		email,
+		username,
+		name,
+		hashed_password,
+		created_at,
+		updated_at,
+		rbac_roles,
+		login_type,
+		status,
+		is_service_account
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9,
+		-- if the status passed in is empty, fallback to dormant, which is what
+		-- we were doing before.
+		COALESCE(NULLIF($10::text, '')::user_status, 'dormant'::user_status),
+		$11::bool
+	) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+`
+
+type InsertUserParams struct {
+	ID               uuid.UUID      `db:"id" json:"id"`
+	Email            string         `db:"email" json:"email"`
+	Username         string         `db:"username" json:"username"`
+	Name             string         `db:"name" json:"name"`
+	HashedPassword   []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
+	RBACRoles        pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType        LoginType      `db:"login_type" json:"login_type"`
+	Status           string         `db:"status" json:"status"`
+	IsServiceAccount bool           `db:"is_service_account" json:"is_service_account"`
+}
+
+func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, insertUser,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.Name,
+		arg.HashedPassword,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.RBACRoles,
+		arg.LoginType,
+		arg.Status,
+		arg.IsServiceAccount,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+		&i.IsServiceAccount,
+		&i.ChatSpendLimitMicros,
+	)
+	return i, err
+}
+
+const listUserChatCompactionThresholds = `-- name: ListUserChatCompactionThresholds :many
+SELECT user_id, key, value FROM user_configs
+WHERE user_id = $1
+	AND key LIKE 'chat\_compaction\_threshold\_pct:%'
+ORDER BY key
+`
+
+func (q *sqlQuerier) ListUserChatCompactionThresholds(ctx context.Context, userID uuid.UUID) ([]UserConfig, error) {
+	rows, err := q.db.QueryContext(ctx, listUserChatCompactionThresholds, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserConfig
+	for rows.Next() {
+		var i UserConfig
+		if err := rows.Scan(&i.UserID, &i.Key, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserChatPersonalModelOverrides = `-- name: ListUserChatPersonalModelOverrides :many
+SELECT key, value FROM user_configs
+WHERE user_id = $1
+	AND key LIKE 'chat\_personal\_model\_override:%'
+ORDER BY key
+`
+
+type ListUserChatPersonalModelOverridesRow struct {
+	Key   string `db:"key" json:"key"`
+	Value string `db:"value" json:"value"`
+}
+
+func (q *sqlQuerier) ListUserChatPersonalModelOverrides(ctx context.Context, userID uuid.UUID) ([]ListUserChatPersonalModelOverridesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserChatPersonalModelOverrides, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserChatPersonalModelOverridesRow
+	for rows.Next() {
+		var i ListUserChatPersonalModelOverridesRow
+		if err := rows.Scan(&i.Key, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateInactiveUsersToDormant = `-- name: UpdateInactiveUsersToDormant :many
+UPDATE
+    users
+SET
+    status = 'dormant'::user_status,
+    updated_at = $1
+WHERE
+    last_seen_at < $2 :: timestamp
+    AND status = 'active'::user_status
+		AND NOT is_system
+RETURNING id, email, username, last_seen_at
+`
+
+type UpdateInactiveUsersToDormantParams struct {
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	LastSeenAfter time.Time `db:"last_seen_after" json:"last_seen_after"`
+}
+
+type UpdateInactiveUsersToDormantRow struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	Email      string    `db:"email" json:"email"`
+	Username   string    `db:"username" json:"username"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error) {
+	rows, err := q.db.QueryContext(ctx, updateInactiveUsersToDormant, arg.UpdatedAt, arg.LastSeenAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UpdateInactiveUsersToDormantRow
+	for rows.Next() {
+		var i UpdateInactiveUsersToDormantRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserAgentChatSendShortcut = `-- name: UpdateUserAgentChatSendShortcut :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'preference_agent_chat_send_shortcut', $2::text)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
 	value = $2
 WHERE user_configs.user_id = $1
-	AND user_configs.key = 'theme_mode'
+	AND user_configs.key = 'preference_agent_chat_send_shortcut'
+RETURNING value AS agent_chat_send_shortcut
+`
+
+type UpdateUserAgentChatSendShortcutParams struct {
+	UserID                uuid.UUID `db:"user_id" json:"user_id"`
+	AgentChatSendShortcut string    `db:"agent_chat_send_shortcut" json:"agent_chat_send_shortcut"`
+}
+
+func (q *sqlQuerier) UpdateUserAgentChatSendShortcut(ctx context.Context, arg UpdateUserAgentChatSendShortcutParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, updateUserAgentChatSendShortcut, arg.UserID, arg.AgentChatSendShortcut)
+	var agent_chat_send_shortcut string
+	err := row.Scan(&agent_chat_send_shortcut)
+	return agent_chat_send_shortcut, err
+}
+
+const updateUserChatCompactionThreshold = `-- name: UpdateUserChatCompactionThreshold :one
+INSERT INTO user_configs (user_id, key, value)
+VALUES ($1, $2, ($3::int)::text)
+ON CONFLICT ON CONSTRAINT user_configs_pkey
+DO UPDATE SET value = ($3::int)::text
 RETURNING user_id, key, value
 `
 
-type UpdateUserThemeModeParams struct {
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	ThemeMode string    `db:"theme_mode" json:"theme_mode"`
+type UpdateUserChatCompactionThresholdParams struct {
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	Key              string    `db:"key" json:"key"`
+	ThresholdPercent int32     `db:"threshold_percent" json:"threshold_percent"`
+}
+
+func (q *sqlQuerier) UpdateUserChatCompactionThreshold(ctx context.Context, arg UpdateUserChatCompactionThresholdParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserChatCompactionThreshold, arg.UserID, arg.Key, arg.ThresholdPercent)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserChatCustomPrompt = `-- name: UpdateUserChatCustomPrompt :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'chat_custom_prompt', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'chat_custom_prompt'
+RETURNING user_id, key, value
+`
+
+type UpdateUserChatCustomPromptParams struct {
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	ChatCustomPrompt string    `db:"chat_custom_prompt" json:"chat_custom_prompt"`
+}
+
+func (q *sqlQuerier) UpdateUserChatCustomPrompt(ctx context.Context, arg UpdateUserChatCustomPromptParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserChatCustomPrompt, arg.UserID, arg.ChatCustomPrompt)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserCodeDiffDisplayMode = `-- name: UpdateUserCodeDiffDisplayMode :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'preference_code_diff_display_mode', $2::text)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'preference_code_diff_display_mode'
+RETURNING value AS code_diff_display_mode
+`
+
+type UpdateUserCodeDiffDisplayModeParams struct {
+	UserID              uuid.UUID `db:"user_id" json:"user_id"`
+	CodeDiffDisplayMode string    `db:"code_diff_display_mode" json:"code_diff_display_mode"`
+}
+
+func (q *sqlQuerier) UpdateUserCodeDiffDisplayMode(ctx context.Context, arg UpdateUserCodeDiffDisplayModeParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, updateUserCodeDiffDisplayMode, arg.UserID, arg.CodeDiffDisplayMode)
+	var code_diff_display_mode string
+	err := row.Scan(&code_diff_display_mode)
+	return code_diff_display_mode, err
+}
+
+const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
+UPDATE
+	users
+SET
+	deleted = true
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, id)
+	return err
+}
+
+const updateUserGithubComUserID = `-- name: UpdateUserGithubComUserID :exec
+UPDATE
+	users
+SET
+	github_com_user_id = $2
+WHERE
+	id = $1
+`
+
+type UpdateUserGithubComUserIDParams struct {
+	ID              uuid.UUID     `db:"id" json:"id"`
+	GithubComUserID sql.NullInt64 `db:"github_com_user_id" json:"github_com_user_id"`
+}
+
+func (q *sqlQuerier) UpdateUserGithubComUserID(ctx context.Context, arg UpdateUserGithubComUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserGithubComUserID, arg.ID, arg.GithubComUserID)
+	return err
+}
+
+const updateUserHashedOneTimePasscode = `-- name: UpdateUserHashedOneTimePasscode :exec
+UPDATE
+    users
+SET
+    hashed_one_time_passcode = $2,
+    one_time_passcode_expires_at = $3
+WHERE
+    id = $1
+`
+
+type UpdateUserHashedOneTimePasscodeParams struct {
+	ID                       uuid.UUID    `db:"id" json:"id"`
+	HashedOneTimePasscode    []byte       `db:"hashed_one_time_passcode" json:"hashed_one_time_passcode"`
+	OneTimePasscodeExpiresAt sql.NullTime `db:"one_time_passcode_expires_at" json:"one_time_passcode_expires_at"`
+}
+
+func (q *sqlQuerier) UpdateUserHashedOneTimePasscode(ctx context.Context, arg UpdateUserHashedOneTimePasscodeParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserHashedOneTimePasscode, arg.ID, arg.HashedOneTimePasscode, arg.OneTimePasscodeExpiresAt)
+	return err
+}
+
+const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
+UPDATE
+	users
+SET
+	hashed_password = $2,
+	hashed_one_time_passcode = NULL,
+	one_time_passcode_expires_at = NULL
+WHERE
+	id = $1
+`
+
+type UpdateUserHashedPasswordParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+}
+
+func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+	return err
+}
+
+const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
+UPDATE
+	users
+SET
+	last_seen_at = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+`
+
+type UpdateUserLastSeenAtParams struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+		&i.IsServiceAccount,
+		&i.ChatSpendLimitMicros,
+	)
+	return i, err
+}
+
+const updateUserLoginType = `-- name: UpdateUserLoginType :one
+UPDATE
+	users
+SET
+	login_type = $1,
+	hashed_password = CASE WHEN $1 = 'password' :: login_type THEN
+		users.hashed_password
+	ELSE
+		-- If the login type is not password, then the password should be
+        -- cleared.
+		'':: bytea
+	END
+WHERE
+	id = $2
+	AND NOT is_system
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+`
+
+type UpdateUserLoginTypeParams struct {
+	NewLoginType LoginType `db:"new_login_type" json:"new_login_type"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLoginType, arg.NewLoginType, arg.UserID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+		&i.IsServiceAccount,
+		&i.ChatSpendLimitMicros,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE
+	users
+SET
+	email = $2,
+	username = $3,
+	avatar_url = $4,
+	updated_at = $5,
+	name = $6
+WHERE
+	id = $1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+`
+
+type UpdateUserProfileParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Email     string    `db:"email" json:"email"`
+	Username  string    `db:"username" json:"username"`
+	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.AvatarURL,
+		arg.UpdatedAt,
+		arg.Name,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+		&i.IsServiceAccount,
+		&i.ChatSpendLimitMicros,
+	)
+	return i, err
+}
+
+const updateUserQuietHoursSchedule = `-- name: UpdateUserQuietHoursSchedule :one
+UPDATE
+	users
+SET
+	quiet_hours_schedule = $2
+WHERE
+	id = $1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+`
+
+type UpdateUserQuietHoursScheduleParams struct {
+	ID                 uuid.UUID `db:"id" json:"id"`
+	QuietHoursSchedule string    `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+}
+
+func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserQuietHoursSchedule, arg.ID, arg.QuietHoursSchedule)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+		&i.IsServiceAccount,
+		&i.ChatSpendLimitMicros,
+	)
+	return i, err
+}
+
+const updateUserRoles = `-- name: UpdateUserRoles :one
+UPDATE
+	users
+SET
+	-- Remove all duplicates from the roles.
+	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+WHERE
+	id = $2
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+`
+
+type UpdateUserRolesParams struct {
+	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
+	ID           uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+		&i.IsServiceAccount,
+		&i.ChatSpendLimitMicros,
+	)
+	return i, err
+}
+
+const updateUserShellToolDisplayMode = `-- name: UpdateUserShellToolDisplayMode :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'preference_shell_tool_display_mode', $2::text)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'preference_shell_tool_display_mode'
+RETURNING value AS shell_tool_display_mode
+`
+
+type UpdateUserShellToolDisplayModeParams struct {
+	UserID               uuid.UUID `db:"user_id" json:"user_id"`
+	ShellToolDisplayMode string    `db:"shell_tool_display_mode" json:"shell_tool_display_mode"`
+}
+
+func (q *sqlQuerier) UpdateUserShellToolDisplayMode(ctx context.Context, arg UpdateUserShellToolDisplayModeParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, updateUserShellToolDisplayMode, arg.UserID, arg.ShellToolDisplayMode)
+	var shell_tool_display_mode string
+	err := row.Scan(&shell_tool_display_mode)
+	return shell_tool_display_mode, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE
+	users
+SET
+	status = $2,
+	updated_at = $3,
+	-- If the user is logging in, set last_seen_at to updated_at.
+	last_seen_at = CASE WHEN $4 :: boolean THEN $3 :: timestamptz ELSE last_seen_at END
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, is_service_account, chat_spend_limit_micros
+`
+
+type UpdateUserStatusParams struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	Status     UserStatus `db:"status" json:"status"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+	UserIsSeen bool       `db:"user_is_seen" json:"user_is_seen"`
+}
+
+func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus,
+		arg.ID,
+		arg.Status,
+		arg.UpdatedAt,
+		arg.UserIsSeen,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+		&i.IsServiceAccount,
+		&i.ChatSpendLimitMicros,
+	)
+	return i, err
+}
+
+const updateUserTaskNotificationAlertDismissed = `-- name: UpdateUserTaskNotificationAlertDismissed :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'preference_task_notification_alert_dismissed', ($2::boolean)::text)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'preference_task_notification_alert_dismissed'
+RETURNING value::boolean AS task_notification_alert_dismissed
+`
+
+type UpdateUserTaskNotificationAlertDismissedParams struct {
+	UserID                         uuid.UUID `db:"user_id" json:"user_id"`
+	TaskNotificationAlertDismissed bool      `db:"task_notification_alert_dismissed" json:"task_notification_alert_dismissed"`
+}
+
+func (q *sqlQuerier) UpdateUserTaskNotificationAlertDismissed(ctx context.Context, arg UpdateUserTaskNotificationAlertDismissedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTaskNotificationAlertDismissed, arg.UserID, arg.TaskNotificationAlertDismissed)
+	var task_notification_alert_dismissed bool
+	err := row.Scan(&task_notification_alert_dismissed)
+	return task_notification_alert_dismissed, err
+}
+
+const updateUserTerminalFont = `-- name: UpdateUserTerminalFont :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'terminal_font', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'terminal_font'
+RETURNING user_id, key, value
+`
+
+type UpdateUserTerminalFontParams struct {
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	TerminalFont string    `db:"terminal_font" json:"terminal_font"`
+}
+
+func (q *sqlQuerier) UpdateUserTerminalFont(ctx context.Context, arg UpdateUserTerminalFontParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTerminalFont, arg.UserID, arg.TerminalFont)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserThemeDark = `-- name: UpdateUserThemeDark :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'theme_dark', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'theme_dark'
+RETURNING user_id, key, value
+`
+
+type UpdateUserThemeDarkParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	ThemeDark string    `db:"theme_dark" json:"theme_dark"`
+}
+
+func (q *sqlQuerier) UpdateUserThemeDark(ctx context.Context, arg UpdateUserThemeDarkParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserThemeDark, arg.UserID, arg.ThemeDark)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserThemeLight = `-- name: UpdateUserThemeLight :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'theme_light', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'theme_light'
+RETURNING user_id, key, value
+`
+
+type UpdateUserThemeLightParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	ThemeLight string    `db:"theme_light" json:"theme_light"`
+}
+
+func (q *sqlQuerier) UpdateUserThemeLight(ctx context.Context, arg UpdateUserThemeLightParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserThemeLight, arg.UserID, arg.ThemeLight)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserThemeMode = `-- name: UpdateUserThemeMode :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'theme_mode', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'theme_mode'
+RETURNING user_id, key, value
+`
+
+type UpdateUserThemeModeParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	ThemeMode string    `db:"theme_mode" json:"theme_mode"`
+}
+
+func (q *sqlQuerier) UpdateUserThemeMode(ctx context.Context, arg UpdateUserThemeModeParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserThemeMode, arg.UserID, arg.ThemeMode)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserThemePreference = `-- name: UpdateUserThemePreference :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'theme_preference', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'theme_preference'
+RETURNING user_id, key, value
+`
+
+type UpdateUserThemePreferenceParams struct {
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	ThemePreference string    `db:"theme_preference" json:"theme_preference"`
+}
+
+func (q *sqlQuerier) UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserThemePreference, arg.UserID, arg.ThemePreference)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserThinkingDisplayMode = `-- name: UpdateUserThinkingDisplayMode :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'preference_thinking_display_mode', $2::text)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'preference_thinking_display_mode'
+RETURNING value AS thinking_display_mode
+`
+
+type UpdateUserThinkingDisplayModeParams struct {
+	UserID              uuid.UUID `db:"user_id" json:"user_id"`
+	ThinkingDisplayMode string    `db:"thinking_display_mode" json:"thinking_display_mode"`
+}
+
+func (q *sqlQuerier) UpdateUserThinkingDisplayMode(ctx context.Context, arg UpdateUserThinkingDisplayModeParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, updateUserThinkingDisplayMode, arg.UserID, arg.ThinkingDisplayMode)
+	var thinking_display_mode string
+	err := row.Scan(&thinking_display_mode)
+	return thinking_display_mode, err
+}
+
+const updateUserWorkspaceSelfRequestedBuildsAllowed = `-- name: UpdateUserWorkspaceSelfRequestedBuildsAllowed :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'preference_workspace_self_requested_builds_allowed', ($2::boolean)::text)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = ($2::boolean)::text
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'preference_workspace_self_requested_builds_allowed'
+RETURNING value::boolean AS workspace_self_requested_builds_allowed
+`
+
+type UpdateUserWorkspaceSelfRequestedBuildsAllowedParams struct {
+	UserID                              uuid.UUID `db:"user_id" json:"user_id"`
+	WorkspaceSelfRequestedBuildsAllowed bool      `db:"workspace_self_requested_builds_allowed" json:"workspace_self_requested_builds_allowed"`
+}
+
+func (q *sqlQuerier) UpdateUserWorkspaceSelfRequestedBuildsAllowed(ctx context.Context, arg UpdateUserWorkspaceSelfRequestedBuildsAllowedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, updateUserWorkspaceSelfRequestedBuildsAllowed, arg.UserID, arg.WorkspaceSelfRequestedBuildsAllowed)
+	var workspace_self_requested_builds_allowed bool
+	err := row.Scan(&workspace_self_requested_builds_allowed)
+	return workspace_self_requested_builds_allowed, err
+}
+
+const upsertUserChatDebugLoggingEnabled = `-- name: UpsertUserChatDebugLoggingEnabled :exec
+INSERT INTO user_configs (user_id, key, value)
+VALUES (
+	$1,
+	'chat_debug_logging_enabled',
+	CASE
+		WHEN $2::bool THEN 'true'
+		ELSE 'false'
+	END
+)
+ON CONFLICT ON CONSTRAINT user_configs_pkey
+DO UPDATE SET value = CASE
+	WHEN $2::bool THEN 'true'
+	ELSE 'false'
+END
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'chat_debug_logging_enabled'
+`
+
+type UpsertUserChatDebugLoggingEnabledParams struct {
+	UserID              uuid.UUID `db:"user_id" json:"user_id"`
+	DebugLoggingEnabled bool      `db:"debug_logging_enabled" json:"debug_logging_enabled"`
+}
+
+func (q *sqlQuerier) UpsertUserChatDebugLoggingEnabled(ctx context.Context, arg UpsertUserChatDebugLoggingEnabledParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserChatDebugLoggingEnabled, arg.UserID, arg.DebugLoggingEnabled)
+	return err
+}
+
+const upsertUserChatPersonalModelOverride = `-- name: UpsertUserChatPersonalModelOverride :exec
+INSERT INTO user_configs (user_id, key, value)
+VALUES ($1::uuid, $2::text, $3::text)
+ON CONFLICT ON CONSTRAINT user_configs_pkey
+DO UPDATE SET value = $3::text
+`
+
+type UpsertUserChatPersonalModelOverrideParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Key    string    `db:"key" json:"key"`
+	Value  string    `db:"value" json:"value"`
+}
+
+func (q *sqlQuerier) UpsertUserChatPersonalModelOverride(ctx context.Context, arg UpsertUserChatPersonalModelOverrideParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserChatPersonalModelOverride, arg.UserID, arg.Key, arg.Value)
+	return err
+}
+
+const validateUserIDs = `-- name: ValidateUserIDs :one
+WITH input AS (
+	SELECT
+		unnest($1::uuid[]) AS id
+)
+SELECT
+	array_agg(input.id)::uuid[] as invalid_user_ids,
+	COUNT(*) = 0 as ok
+FROM
+	-- Preserve rows where there is not a matching left (users) row for each
+	-- right (input) row...
+	users
+	RIGHT JOIN input ON users.id = input.id
+WHERE
+	-- ...so that we can retain exactly those rows where an input ID does not
+	-- match an existing user...
+	users.id IS NULL OR
+	-- ...or that only matches a user that was deleted.
+	users.deleted = true
+`
+
+type ValidateUserIDsRow struct {
+	InvalidUserIds []uuid.UUID `db:"invalid_user_ids" json:"invalid_user_ids"`
+	Ok             bool        `db:"ok" json:"ok"`
+}
+
+func (q *sqlQuerier) ValidateUserIDs(ctx context.Context, userIds []uuid.UUID) (ValidateUserIDsRow, error) {
+	row := q.db.QueryRowContext(ctx, validateUserIDs, pq.Array(userIds))
+	var i ValidateUserIDsRow
+	err := row.Scan(pq.Array(&i.InvalidUserIds), &i.Ok)
+	return i, err
+}
+
+const deleteUserSchedulePause = `-- name: DeleteUserSchedulePause :exec
+DELETE FROM user_schedule_pauses
+WHERE user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserSchedulePause(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserSchedulePause, userID)
+	return err
+}
+
+const getUserSchedulePause = `-- name: GetUserSchedulePause :one
+SELECT user_id, paused_until, pause_autostop, created_at, updated_at
+FROM user_schedule_pauses
+WHERE user_id = $1
+`
+
+func (q *sqlQuerier) GetUserSchedulePause(ctx context.Context, userID uuid.UUID) (UserSchedulePause, error) {
+	row := q.db.QueryRowContext(ctx, getUserSchedulePause, userID)
+	var i UserSchedulePause
+	err := row.Scan(
+		&i.UserID,
+		&i.PausedUntil,
+		&i.PauseAutostop,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUserSchedulePause = `-- name: UpsertUserSchedulePause :one
+INSERT INTO user_schedule_pauses (
+	user_id,
+	paused_until,
+	pause_autostop,
+	created_at,
+	updated_at
+) VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5
+)
+ON CONFLICT (user_id) DO UPDATE SET
+	paused_until = EXCLUDED.paused_until,
+	pause_autostop = EXCLUDED.pause_autostop,
+	updated_at = EXCLUDED.updated_at
+RETURNING user_id, paused_until, pause_autostop, created_at, updated_at
+`
+
+type UpsertUserSchedulePauseParams struct {
+	UserID        uuid.UUID `db:"user_id" json:"user_id"`
+	PausedUntil   time.Time `db:"paused_until" json:"paused_until"`
+	PauseAutostop bool      `db:"pause_autostop" json:"pause_autostop"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertUserSchedulePause(ctx context.Context, arg UpsertUserSchedulePauseParams) (UserSchedulePause, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserSchedulePause,
+		arg.UserID,
+		arg.PausedUntil,
+		arg.PauseAutostop,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i UserSchedulePause
+	err := row.Scan(
+		&i.UserID,
+		&i.PausedUntil,
+		&i.PauseAutostop,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getLatestUserWorkspaceReportByUserID = `-- name: GetLatestUserWorkspaceReportByUserID :one
+SELECT
+	id, user_id, period_start, period_end, summary, created_at
+FROM
+	user_workspace_reports
+WHERE
+	user_id = $1
+ORDER BY
+	period_end DESC
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetLatestUserWorkspaceReportByUserID(ctx context.Context, userID uuid.UUID) (UserWorkspaceReport, error) {
+	row := q.db.QueryRowContext(ctx, getLatestUserWorkspaceReportByUserID, userID)
+	var i UserWorkspaceReport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.Summary,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserWorkspaceReportStats = `-- name: GetUserWorkspaceReportStats :many
+WITH builds AS (
+	SELECT
+		wb.workspace_id,
+		COUNT(*) AS total_builds,
+		COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds
+	FROM
+		workspace_builds AS wb
+	JOIN
+		provisioner_jobs AS pj ON wb.job_id = pj.id
+	WHERE
+		wb.created_at >= $1
+		AND pj.completed_at IS NOT NULL
+	GROUP BY
+		wb.workspace_id
+)
+SELECT
+	w.id AS workspace_id,
+	w.name AS workspace_name,
+	w.owner_id,
+	t.name AS template_name,
+	t.display_name AS template_display_name,
+	COALESCE(builds.total_builds, 0)::bigint AS total_builds,
+	COALESCE(builds.failed_builds, 0)::bigint AS failed_builds,
+	w.last_used_at,
+	w.dormant_at,
+	w.deleting_at,
+	t.time_til_dormant
+FROM
+	workspaces AS w
+JOIN
+	templates AS t ON w.template_id = t.id
+JOIN
+	users AS u ON w.owner_id = u.id
+LEFT JOIN
+	builds ON builds.workspace_id = w.id
+WHERE
+	w.deleted = false
+	AND u.deleted = false
+	AND u.status = 'active'::user_status
+	AND w.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+	AND (
+		builds.workspace_id IS NOT NULL
+		OR (w.deleting_at IS NOT NULL AND w.deleting_at < $2)
+		OR (
+			w.dormant_at IS NULL
+			AND t.time_til_dormant > 0
+			AND w.last_used_at + (INTERVAL '1 millisecond' * (t.time_til_dormant / 1000000)) < $2
+		)
+	)
+ORDER BY
+	w.owner_id ASC, w.name ASC
+`
+
+type GetUserWorkspaceReportStatsParams struct {
+	Since time.Time `db:"since" json:"since"`
+	Until time.Time `db:"until" json:"until"`
+}
+
+type GetUserWorkspaceReportStatsRow struct {
+	WorkspaceID         uuid.UUID    `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName       string       `db:"workspace_name" json:"workspace_name"`
+	OwnerID             uuid.UUID    `db:"owner_id" json:"owner_id"`
+	TemplateName        string       `db:"template_name" json:"template_name"`
+	TemplateDisplayName string       `db:"template_display_name" json:"template_display_name"`
+	TotalBuilds         int64        `db:"total_builds" json:"total_builds"`
+	FailedBuilds        int64        `db:"failed_builds" json:"failed_builds"`
+	LastUsedAt          time.Time    `db:"last_used_at" json:"last_used_at"`
+	DormantAt           sql.NullTime `db:"dormant_at" json:"dormant_at"`
+	DeletingAt          sql.NullTime `db:"deleting_at" json:"deleting_at"`
+	TimeTilDormant      int64        `db:"time_til_dormant" json:"time_til_dormant"`
+}
+
+// Returns the workspaces of active users that had builds since @since, that
+// will be deleted before @until, or that will become dormant before @until if
+// they are not used. Prebuilt workspaces are left out, as they are managed by
+// the reconciler rather than by users.
+func (q *sqlQuerier) GetUserWorkspaceReportStats(ctx context.Context, arg GetUserWorkspaceReportStatsParams) ([]GetUserWorkspaceReportStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserWorkspaceReportStats, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserWorkspaceReportStatsRow
+	for rows.Next() {
+		var i GetUserWorkspaceReportStatsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.TemplateName,
+			&i.TemplateDisplayName,
+			&i.TotalBuilds,
+			&i.FailedBuilds,
+			&i.LastUsedAt,
+			&i.DormantAt,
+			&i.DeletingAt,
+			&i.TimeTilDormant,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUserWorkspaceReport = `-- name: InsertUserWorkspaceReport :one
+INSERT INTO
+	user_workspace_reports (
+		id,
+		user_id,
+		period_start,
+		period_end,
+		summary,
+		created_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+)
+RETURNING id, user_id, period_start, period_end, summary, created_at
+`
+
+type InsertUserWorkspaceReportParams struct {
+	ID          uuid.UUID       `db:"id" json:"id"`
+	UserID      uuid.UUID       `db:"user_id" json:"user_id"`
+	PeriodStart time.Time       `db:"period_start" json:"period_start"`
+	PeriodEnd   time.Time       `db:"period_end" json:"period_end"`
+	Summary     json.RawMessage `db:"summary" json:"summary"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertUserWorkspaceReport(ctx context.Context, arg InsertUserWorkspaceReportParams) (UserWorkspaceReport, error) {
+	row := q.db.QueryRowContext(ctx, insertUserWorkspaceReport,
+		arg.ID,
+		arg.UserID,
+		arg.PeriodStart,
+		arg.PeriodEnd,
+		arg.Summary,
+		arg.CreatedAt,
+	)
+	var i UserWorkspaceReport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.Summary,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteVCSEventMappingByTemplateIDAndID = `-- name: DeleteVCSEventMappingByTemplateIDAndID :one
+DELETE FROM vcs_event_mappings
+WHERE template_id = $1 AND id = $2
+RETURNING id, template_id, name, repository, branch, action, workspace_id, signing_secret, signing_secret_key_id, created_by, created_at, updated_at, last_triggered_at
+`
+
+type DeleteVCSEventMappingByTemplateIDAndIDParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	ID         uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) DeleteVCSEventMappingByTemplateIDAndID(ctx context.Context, arg DeleteVCSEventMappingByTemplateIDAndIDParams) (VCSEventMapping, error) {
+	row := q.db.QueryRowContext(ctx, deleteVCSEventMappingByTemplateIDAndID, arg.TemplateID, arg.ID)
+	var i VCSEventMapping
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Repository,
+		&i.Branch,
+		&i.Action,
+		&i.WorkspaceID,
+		&i.SigningSecret,
+		&i.SigningSecretKeyID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastTriggeredAt,
+	)
+	return i, err
+}
+
+const getVCSEventMappingByID = `-- name: GetVCSEventMappingByID :one
+SELECT id, template_id, name, repository, branch, action, workspace_id, signing_secret, signing_secret_key_id, created_by, created_at, updated_at, last_triggered_at
+FROM vcs_event_mappings
+WHERE id = $1
+`
+
+func (q *sqlQuerier) GetVCSEventMappingByID(ctx context.Context, id uuid.UUID) (VCSEventMapping, error) {
+	row := q.db.QueryRowContext(ctx, getVCSEventMappingByID, id)
+	var i VCSEventMapping
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Repository,
+		&i.Branch,
+		&i.Action,
+		&i.WorkspaceID,
+		&i.SigningSecret,
+		&i.SigningSecretKeyID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastTriggeredAt,
+	)
+	return i, err
+}
+
+const getVCSEventMappings = `-- name: GetVCSEventMappings :many
+SELECT id, template_id, name, repository, branch, action, workspace_id, signing_secret, signing_secret_key_id, created_by, created_at, updated_at, last_triggered_at
+FROM vcs_event_mappings
+ORDER BY template_id, name ASC
+`
+
+// Returns the mappings of all templates. Used by the dbcrypt key rotation
+// utility.
+func (q *sqlQuerier) GetVCSEventMappings(ctx context.Context) ([]VCSEventMapping, error) {
+	rows, err := q.db.QueryContext(ctx, getVCSEventMappings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VCSEventMapping
+	for rows.Next() {
+		var i VCSEventMapping
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.Name,
+			&i.Repository,
+			&i.Branch,
+			&i.Action,
+			&i.WorkspaceID,
+			&i.SigningSecret,
+			&i.SigningSecretKeyID,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastTriggeredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVCSEventMappingsByRepository = `-- name: GetVCSEventMappingsByRepository :many
+SELECT id, template_id, name, repository, branch, action, workspace_id, signing_secret, signing_secret_key_id, created_by, created_at, updated_at, last_triggered_at
+FROM vcs_event_mappings
+WHERE lower(repository) = lower($1::text)
+ORDER BY created_at ASC, id ASC
+`
+
+// Returns the mappings of a repository along with their signing secrets, so
+// that the VCS webhook receiver can verify events.
+func (q *sqlQuerier) GetVCSEventMappingsByRepository(ctx context.Context, repository string) ([]VCSEventMapping, error) {
+	rows, err := q.db.QueryContext(ctx, getVCSEventMappingsByRepository, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VCSEventMapping
+	for rows.Next() {
+		var i VCSEventMapping
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.Name,
+			&i.Repository,
+			&i.Branch,
+			&i.Action,
+			&i.WorkspaceID,
+			&i.SigningSecret,
+			&i.SigningSecretKeyID,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastTriggeredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVCSEventMappingsByTemplateID = `-- name: GetVCSEventMappingsByTemplateID :many
+SELECT id, template_id, name, repository, branch, action, workspace_id, signing_secret, signing_secret_key_id, created_by, created_at, updated_at, last_triggered_at
+FROM vcs_event_mappings
+WHERE template_id = $1
+ORDER BY name ASC
+`
+
+func (q *sqlQuerier) GetVCSEventMappingsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]VCSEventMapping, error) {
+	rows, err := q.db.QueryContext(ctx, getVCSEventMappingsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VCSEventMapping
+	for rows.Next() {
+		var i VCSEventMapping
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.Name,
+			&i.Repository,
+			&i.Branch,
+			&i.Action,
+			&i.WorkspaceID,
+			&i.SigningSecret,
+			&i.SigningSecretKeyID,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastTriggeredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertVCSEventMapping = `-- name: InsertVCSEventMapping :one
+INSERT INTO vcs_event_mappings (
+    id,
+    template_id,
+    name,
+    repository,
+    branch,
+    action,
+    workspace_id,
+    signing_secret,
+    signing_secret_key_id,
+    created_by,
+    created_at,
+    updated_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $11
+) RETURNING id, template_id, name, repository, branch, action, workspace_id, signing_secret, signing_secret_key_id, created_by, created_at, updated_at, last_triggered_at
+`
+
+type InsertVCSEventMappingParams struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	TemplateID         uuid.UUID      `db:"template_id" json:"template_id"`
+	Name               string         `db:"name" json:"name"`
+	Repository         string         `db:"repository" json:"repository"`
+	Branch             string         `db:"branch" json:"branch"`
+	Action             VCSEventAction `db:"action" json:"action"`
+	WorkspaceID        uuid.NullUUID  `db:"workspace_id" json:"workspace_id"`
+	SigningSecret      string         `db:"signing_secret" json:"signing_secret"`
+	SigningSecretKeyID sql.NullString `db:"signing_secret_key_id" json:"signing_secret_key_id"`
+	CreatedBy          uuid.UUID      `db:"created_by" json:"created_by"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertVCSEventMapping(ctx context.Context, arg InsertVCSEventMappingParams) (VCSEventMapping, error) {
+	row := q.db.QueryRowContext(ctx, insertVCSEventMapping,
+		arg.ID,
+		arg.TemplateID,
+		arg.Name,
+		arg.Repository,
+		arg.Branch,
+		arg.Action,
+		arg.WorkspaceID,
+		arg.SigningSecret,
+		arg.SigningSecretKeyID,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i VCSEventMapping
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Repository,
+		&i.Branch,
+		&i.Action,
+		&i.WorkspaceID,
+		&i.SigningSecret,
+		&i.SigningSecretKeyID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastTriggeredAt,
+	)
+	return i, err
+}
+
+const updateEncryptedVCSEventMapping = `-- name: UpdateEncryptedVCSEventMapping :one
+UPDATE vcs_event_mappings
+SET
+    signing_secret = $1,
+    signing_secret_key_id = $2::text
+WHERE id = $3
+RETURNING id, template_id, name, repository, branch, action, workspace_id, signing_secret, signing_secret_key_id, created_by, created_at, updated_at, last_triggered_at
+`
+
+type UpdateEncryptedVCSEventMappingParams struct {
+	SigningSecret      string         `db:"signing_secret" json:"signing_secret"`
+	SigningSecretKeyID sql.NullString `db:"signing_secret_key_id" json:"signing_secret_key_id"`
+	ID                 uuid.UUID      `db:"id" json:"id"`
+}
+
+// Updates only the encrypted columns of a mapping. Used by the dbcrypt key
+// rotation utility to re-encrypt or decrypt rows in place.
+func (q *sqlQuerier) UpdateEncryptedVCSEventMapping(ctx context.Context, arg UpdateEncryptedVCSEventMappingParams) (VCSEventMapping, error) {
+	row := q.db.QueryRowContext(ctx, updateEncryptedVCSEventMapping, arg.SigningSecret, arg.SigningSecretKeyID, arg.ID)
+	var i VCSEventMapping
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Repository,
+		&i.Branch,
+		&i.Action,
+		&i.WorkspaceID,
+		&i.SigningSecret,
+		&i.SigningSecretKeyID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastTriggeredAt,
+	)
+	return i, err
+}
+
+const updateVCSEventMappingLastTriggeredAt = `-- name: UpdateVCSEventMappingLastTriggeredAt :exec
+UPDATE vcs_event_mappings
+SET last_triggered_at = $1
+WHERE id = $2
+`
+
+type UpdateVCSEventMappingLastTriggeredAtParams struct {
+	LastTriggeredAt sql.NullTime `db:"last_triggered_at" json:"last_triggered_at"`
+	ID              uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateVCSEventMappingLastTriggeredAt(ctx context.Context, arg UpdateVCSEventMappingLastTriggeredAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateVCSEventMappingLastTriggeredAt, arg.LastTriggeredAt, arg.ID)
+	return err
+}
+
+const getLatestWorkspaceAgentBootstrapProgressByAgentIDs = `-- name: GetLatestWorkspaceAgentBootstrapProgressByAgentIDs :many
+SELECT DISTINCT ON (workspace_agent_id)
+	id, workspace_agent_id, created_at, name, percent, message
+FROM
+	workspace_agent_bootstrap_progress
+WHERE
+	workspace_agent_id = ANY($1 :: uuid [ ])
+ORDER BY
+	workspace_agent_id, created_at DESC, id DESC
+`
+
+// Returns the current milestone of each of the given agents.
+func (q *sqlQuerier) GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentBootstrapProgress, error) {
+	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceAgentBootstrapProgressByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentBootstrapProgress
+	for rows.Next() {
+		var i WorkspaceAgentBootstrapProgress
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.CreatedAt,
+			&i.Name,
+			&i.Percent,
+			&i.Message,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentBootstrapProgressByAgentID = `-- name: GetWorkspaceAgentBootstrapProgressByAgentID :many
+SELECT
+	id, workspace_agent_id, created_at, name, percent, message
+FROM
+	workspace_agent_bootstrap_progress
+WHERE
+	workspace_agent_id = $1
+ORDER BY
+	created_at ASC, id ASC
+`
+
+// Returns all milestones reported by an agent, oldest first.
+func (q *sqlQuerier) GetWorkspaceAgentBootstrapProgressByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentBootstrapProgress, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentBootstrapProgressByAgentID, workspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentBootstrapProgress
+	for rows.Next() {
+		var i WorkspaceAgentBootstrapProgress
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.CreatedAt,
+			&i.Name,
+			&i.Percent,
+			&i.Message,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentBootstrapProgress = `-- name: InsertWorkspaceAgentBootstrapProgress :one
+INSERT INTO
+	workspace_agent_bootstrap_progress (
+		id,
+		workspace_agent_id,
+		created_at,
+		name,
+		percent,
+		message
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+RETURNING id, workspace_agent_id, created_at, name, percent, message
+`
+
+type InsertWorkspaceAgentBootstrapProgressParams struct {
+	ID               uuid.UUID     `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID     `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time     `db:"created_at" json:"created_at"`
+	Name             string        `db:"name" json:"name"`
+	Percent          sql.NullInt32 `db:"percent" json:"percent"`
+	Message          string        `db:"message" json:"message"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentBootstrapProgress(ctx context.Context, arg InsertWorkspaceAgentBootstrapProgressParams) (WorkspaceAgentBootstrapProgress, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentBootstrapProgress,
+		arg.ID,
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		arg.Name,
+		arg.Percent,
+		arg.Message,
+	)
+	var i WorkspaceAgentBootstrapProgress
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceAgentID,
+		&i.CreatedAt,
+		&i.Name,
+		&i.Percent,
+		&i.Message,
+	)
+	return i, err
+}
+
+const deleteStaleWorkspaceAgentContextResources = `-- name: DeleteStaleWorkspaceAgentContextResources :exec
+DELETE FROM workspace_agent_context_resources
+WHERE workspace_agent_id = $1
+  AND NOT (source = ANY($2 :: text[]))
+`
+
+type DeleteStaleWorkspaceAgentContextResourcesParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	ActiveSources    []string  `db:"active_sources" json:"active_sources"`
+}
+
+// Deletes any resources for the agent whose source is not in the
+// supplied active set. Atomic alongside the snapshot upsert so the
+// stored snapshot and resource rows always agree.
+func (q *sqlQuerier) DeleteStaleWorkspaceAgentContextResources(ctx context.Context, arg DeleteStaleWorkspaceAgentContextResourcesParams) error {
+	_, err := q.db.ExecContext(ctx, deleteStaleWorkspaceAgentContextResources, arg.WorkspaceAgentID, pq.Array(arg.ActiveSources))
+	return err
+}
+
+const getLatestWorkspaceAgentContextSnapshot = `-- name: GetLatestWorkspaceAgentContextSnapshot :one
+SELECT workspace_agent_id, version, aggregate_hash, snapshot_error, received_at FROM workspace_agent_context_snapshots
+WHERE workspace_agent_id = $1
+`
+
+func (q *sqlQuerier) GetLatestWorkspaceAgentContextSnapshot(ctx context.Context, workspaceAgentID uuid.UUID) (WorkspaceAgentContextSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getLatestWorkspaceAgentContextSnapshot, workspaceAgentID)
+	var i WorkspaceAgentContextSnapshot
+	err := row.Scan(
+		&i.WorkspaceAgentID,
+		&i.Version,
+		&i.AggregateHash,
+		&i.SnapshotError,
+		&i.ReceivedAt,
+	)
+	return i, err
+}
+
+const listWorkspaceAgentContextResources = `-- name: ListWorkspaceAgentContextResources :many
+SELECT workspace_agent_id, source, body_kind, body, content_hash, size_bytes, status, error, source_path, created_at, updated_at FROM workspace_agent_context_resources
+WHERE workspace_agent_id = $1
+ORDER BY source ASC
+`
+
+func (q *sqlQuerier) ListWorkspaceAgentContextResources(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentContextResource, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkspaceAgentContextResources, workspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentContextResource
+	for rows.Next() {
+		var i WorkspaceAgentContextResource
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.Source,
+			&i.BodyKind,
+			&i.Body,
+			&i.ContentHash,
+			&i.SizeBytes,
+			&i.Status,
+			&i.Error,
+			&i.SourcePath,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceAgentContextResource = `-- name: UpsertWorkspaceAgentContextResource :one
+INSERT INTO workspace_agent_context_resources (
+    workspace_agent_id,
+    source,
+    body_kind,
+    body,
+    content_hash,
+    size_bytes,
+    status,
+    error,
+    source_path,
+    created_at,
+    updated_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $10
+)
+ON CONFLICT (workspace_agent_id, source) DO UPDATE SET
+    body_kind = EXCLUDED.body_kind,
+    body = EXCLUDED.body,
+    content_hash = EXCLUDED.content_hash,
+    size_bytes = EXCLUDED.size_bytes,
+    status = EXCLUDED.status,
+    error = EXCLUDED.error,
+    source_path = EXCLUDED.source_path,
+    updated_at = EXCLUDED.updated_at
+RETURNING workspace_agent_id, source, body_kind, body, content_hash, size_bytes, status, error, source_path, created_at, updated_at
+`
+
+type UpsertWorkspaceAgentContextResourceParams struct {
+	WorkspaceAgentID uuid.UUID                           `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Source           string                              `db:"source" json:"source"`
+	BodyKind         WorkspaceAgentContextBodyKind       `db:"body_kind" json:"body_kind"`
+	Body             json.RawMessage                     `db:"body" json:"body"`
+	ContentHash      []byte                              `db:"content_hash" json:"content_hash"`
+	SizeBytes        int64                               `db:"size_bytes" json:"size_bytes"`
+	Status           WorkspaceAgentContextResourceStatus `db:"status" json:"status"`
+	Error            string                              `db:"error" json:"error"`
+	SourcePath       string                              `db:"source_path" json:"source_path"`
+	Now              time.Time                           `db:"now" json:"now"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceAgentContextResource(ctx context.Context, arg UpsertWorkspaceAgentContextResourceParams) (WorkspaceAgentContextResource, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentContextResource,
+		arg.WorkspaceAgentID,
+		arg.Source,
+		arg.BodyKind,
+		arg.Body,
+		arg.ContentHash,
+		arg.SizeBytes,
+		arg.Status,
+		arg.Error,
+		arg.SourcePath,
+		arg.Now,
+	)
+	var i WorkspaceAgentContextResource
+	err := row.Scan(
+		&i.WorkspaceAgentID,
+		&i.Source,
+		&i.BodyKind,
+		&i.Body,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.Status,
+		&i.Error,
+		&i.SourcePath,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertWorkspaceAgentContextSnapshot = `-- name: UpsertWorkspaceAgentContextSnapshot :one
+INSERT INTO workspace_agent_context_snapshots (
+    workspace_agent_id,
+    version,
+    aggregate_hash,
+    snapshot_error,
+    received_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (workspace_agent_id) DO UPDATE SET
+    version = EXCLUDED.version,
+    aggregate_hash = EXCLUDED.aggregate_hash,
+    snapshot_error = EXCLUDED.snapshot_error,
+    received_at = EXCLUDED.received_at
+RETURNING workspace_agent_id, version, aggregate_hash, snapshot_error, received_at
+`
+
+type UpsertWorkspaceAgentContextSnapshotParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Version          int64     `db:"version" json:"version"`
+	AggregateHash    []byte    `db:"aggregate_hash" json:"aggregate_hash"`
+	SnapshotError    string    `db:"snapshot_error" json:"snapshot_error"`
+	ReceivedAt       time.Time `db:"received_at" json:"received_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceAgentContextSnapshot(ctx context.Context, arg UpsertWorkspaceAgentContextSnapshotParams) (WorkspaceAgentContextSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentContextSnapshot,
+		arg.WorkspaceAgentID,
+		arg.Version,
+		arg.AggregateHash,
+		arg.SnapshotError,
+		arg.ReceivedAt,
+	)
+	var i WorkspaceAgentContextSnapshot
+	err := row.Scan(
+		&i.WorkspaceAgentID,
+		&i.Version,
+		&i.AggregateHash,
+		&i.SnapshotError,
+		&i.ReceivedAt,
+	)
+	return i, err
+}
+
+const deleteWorkspaceAgentCrashLoop = `-- name: DeleteWorkspaceAgentCrashLoop :exec
+DELETE FROM
+	workspace_agent_crash_loops
+WHERE
+	agent_id = $1
+`
+
+// Removes the crash loop tracking of an agent once it stays connected.
+func (q *sqlQuerier) DeleteWorkspaceAgentCrashLoop(ctx context.Context, agentID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentCrashLoop, agentID)
+	return err
+}
+
+const getWorkspaceAgentCrashLoopByAgentID = `-- name: GetWorkspaceAgentCrashLoopByAgentID :one
+SELECT
+	agent_id, short_connections, last_disconnected_at, last_disconnect_reason, last_lifecycle_state, crash_looping_since
+FROM
+	workspace_agent_crash_loops
+WHERE
+	agent_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentCrashLoopByAgentID(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentCrashLoop, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentCrashLoopByAgentID, agentID)
+	var i WorkspaceAgentCrashLoop
+	err := row.Scan(
+		&i.AgentID,
+		&i.ShortConnections,
+		&i.LastDisconnectedAt,
+		&i.LastDisconnectReason,
+		&i.LastLifecycleState,
+		&i.CrashLoopingSince,
+	)
+	return i, err
+}
+
+const getWorkspaceAgentCrashLoopsByAgentIDs = `-- name: GetWorkspaceAgentCrashLoopsByAgentIDs :many
+SELECT
+	agent_id, short_connections, last_disconnected_at, last_disconnect_reason, last_lifecycle_state, crash_looping_since
+FROM
+	workspace_agent_crash_loops
+WHERE
+	agent_id = ANY($1 :: uuid [ ])
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentCrashLoopsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentCrashLoop, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentCrashLoopsByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentCrashLoop
+	for rows.Next() {
+		var i WorkspaceAgentCrashLoop
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.ShortConnections,
+			&i.LastDisconnectedAt,
+			&i.LastDisconnectReason,
+			&i.LastLifecycleState,
+			&i.CrashLoopingSince,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceAgentCrashLoop = `-- name: UpsertWorkspaceAgentCrashLoop :one
+INSERT INTO
+	workspace_agent_crash_loops (
+		agent_id,
+		short_connections,
+		last_disconnected_at,
+		last_disconnect_reason,
+		last_lifecycle_state,
+		crash_looping_since
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+)
+ON CONFLICT (agent_id)
+DO UPDATE SET
+	short_connections = $2,
+	last_disconnected_at = $3,
+	last_disconnect_reason = $4,
+	last_lifecycle_state = $5,
+	crash_looping_since = $6
+RETURNING agent_id, short_connections, last_disconnected_at, last_disconnect_reason, last_lifecycle_state, crash_looping_since
+`
+
+type UpsertWorkspaceAgentCrashLoopParams struct {
+	AgentID              uuid.UUID                    `db:"agent_id" json:"agent_id"`
+	ShortConnections     int32                        `db:"short_connections" json:"short_connections"`
+	LastDisconnectedAt   time.Time                    `db:"last_disconnected_at" json:"last_disconnected_at"`
+	LastDisconnectReason string                       `db:"last_disconnect_reason" json:"last_disconnect_reason"`
+	LastLifecycleState   WorkspaceAgentLifecycleState `db:"last_lifecycle_state" json:"last_lifecycle_state"`
+	CrashLoopingSince    sql.NullTime                 `db:"crash_looping_since" json:"crash_looping_since"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceAgentCrashLoop(ctx context.Context, arg UpsertWorkspaceAgentCrashLoopParams) (WorkspaceAgentCrashLoop, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentCrashLoop,
+		arg.AgentID,
+		arg.ShortConnections,
+		arg.LastDisconnectedAt,
+		arg.LastDisconnectReason,
+		arg.LastLifecycleState,
+		arg.CrashLoopingSince,
+	)
+	var i WorkspaceAgentCrashLoop
+	err := row.Scan(
+		&i.AgentID,
+		&i.ShortConnections,
+		&i.LastDisconnectedAt,
+		&i.LastDisconnectReason,
+		&i.LastLifecycleState,
+		&i.CrashLoopingSince,
+	)
+	return i, err
+}
+
+const getWorkspaceAgentDevcontainersByAgentID = `-- name: GetWorkspaceAgentDevcontainersByAgentID :many
+SELECT
+	id, workspace_agent_id, created_at, workspace_folder, config_path, name, subagent_id
+FROM
+	workspace_agent_devcontainers
+WHERE
+	workspace_agent_id = $1
+ORDER BY
+	created_at, id
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentDevcontainer, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentDevcontainersByAgentID, workspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentDevcontainer
+	for rows.Next() {
+		var i WorkspaceAgentDevcontainer
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.CreatedAt,
+			&i.WorkspaceFolder,
+			&i.ConfigPath,
+			&i.Name,
+			&i.SubagentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentDevcontainers = `-- name: InsertWorkspaceAgentDevcontainers :many
+INSERT INTO
+	workspace_agent_devcontainers (workspace_agent_id, created_at, id, name, workspace_folder, config_path, subagent_id)
+SELECT
+	$1::uuid AS workspace_agent_id,
+	$2::timestamptz AS created_at,
+	unnest($3::uuid[]) AS id,
+	unnest($4::text[]) AS name,
+	unnest($5::text[]) AS workspace_folder,
+	unnest($6::text[]) AS config_path,
+	NULLIF(unnest($7::uuid[]), '00000000-0000-0000-0000-000000000000')::uuid AS subagent_id
+RETURNING workspace_agent_devcontainers.id, workspace_agent_devcontainers.workspace_agent_id, workspace_agent_devcontainers.created_at, workspace_agent_devcontainers.workspace_folder, workspace_agent_devcontainers.config_path, workspace_agent_devcontainers.name, workspace_agent_devcontainers.subagent_id
+`
+
+type InsertWorkspaceAgentDevcontainersParams struct {
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	ID               []uuid.UUID `db:"id" json:"id"`
+	Name             []string    `db:"name" json:"name"`
+	WorkspaceFolder  []string    `db:"workspace_folder" json:"workspace_folder"`
+	ConfigPath       []string    `db:"config_path" json:"config_path"`
+	SubagentID       []uuid.UUID `db:"subagent_id" json:"subagent_id"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentDevcontainers(ctx context.Context, arg InsertWorkspaceAgentDevcontainersParams) ([]WorkspaceAgentDevcontainer, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentDevcontainers,
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		pq.Array(arg.ID),
+		pq.Array(arg.Name),
+		pq.Array(arg.WorkspaceFolder),
+		pq.Array(arg.ConfigPath),
+		pq.Array(arg.SubagentID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentDevcontainer
+	for rows.Next() {
+		var i WorkspaceAgentDevcontainer
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.CreatedAt,
+			&i.WorkspaceFolder,
+			&i.ConfigPath,
+			&i.Name,
+			&i.SubagentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteOldWorkspaceAgentMemoryEvents = `-- name: DeleteOldWorkspaceAgentMemoryEvents :exec
+DELETE FROM
+	workspace_agent_memory_events
+WHERE
+	created_at < $1 :: timestamptz
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAgentMemoryEvents(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentMemoryEvents, beforeTime)
+	return err
+}
+
+const getWorkspaceAgentMemoryEventCountsByAgentIDs = `-- name: GetWorkspaceAgentMemoryEventCountsByAgentIDs :many
+SELECT
+	workspace_agent_id,
+	SUM(oom_kills) :: bigint AS oom_kills,
+	COUNT(*) FILTER (WHERE kind = 'memory_pressure') :: bigint AS memory_pressure_events
+FROM
+	workspace_agent_memory_events
+WHERE
+	workspace_agent_id = ANY($1 :: uuid [ ])
+	AND created_at >= $2 :: timestamptz
+GROUP BY
+	workspace_agent_id
+`
+
+type GetWorkspaceAgentMemoryEventCountsByAgentIDsParams struct {
+	IDs          []uuid.UUID `db:"ids" json:"ids"`
+	CreatedAfter time.Time   `db:"created_after" json:"created_after"`
+}
+
+type GetWorkspaceAgentMemoryEventCountsByAgentIDsRow struct {
+	WorkspaceAgentID     uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	OomKills             int64     `db:"oom_kills" json:"oom_kills"`
+	MemoryPressureEvents int64     `db:"memory_pressure_events" json:"memory_pressure_events"`
+}
+
+// Returns the number of OOM kills and memory pressure events reported by
+// each of the given agents since the given time. Agents without events are
+// omitted.
+func (q *sqlQuerier) GetWorkspaceAgentMemoryEventCountsByAgentIDs(ctx context.Context, arg GetWorkspaceAgentMemoryEventCountsByAgentIDsParams) ([]GetWorkspaceAgentMemoryEventCountsByAgentIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentMemoryEventCountsByAgentIDs, pq.Array(arg.IDs), arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentMemoryEventCountsByAgentIDsRow
+	for rows.Next() {
+		var i GetWorkspaceAgentMemoryEventCountsByAgentIDsRow
+		if err := rows.Scan(&i.WorkspaceAgentID, &i.OomKills, &i.MemoryPressureEvents); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentMemoryEventsByAgentIDs = `-- name: GetWorkspaceAgentMemoryEventsByAgentIDs :many
+SELECT
+	id, workspace_agent_id, created_at, kind, oom_kills, memory_pressure
+FROM
+	workspace_agent_memory_events
+WHERE
+	workspace_agent_id = ANY($1 :: uuid [ ])
+	AND created_at >= $2 :: timestamptz
+ORDER BY
+	created_at DESC, id DESC
+LIMIT
+	1000
+`
+
+type GetWorkspaceAgentMemoryEventsByAgentIDsParams struct {
+	IDs          []uuid.UUID `db:"ids" json:"ids"`
+	CreatedAfter time.Time   `db:"created_after" json:"created_after"`
+}
+
+// Returns the memory events reported by the given agents since the given
+// time, newest first.
+func (q *sqlQuerier) GetWorkspaceAgentMemoryEventsByAgentIDs(ctx context.Context, arg GetWorkspaceAgentMemoryEventsByAgentIDsParams) ([]WorkspaceAgentMemoryEvent, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentMemoryEventsByAgentIDs, pq.Array(arg.IDs), arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentMemoryEvent
+	for rows.Next() {
+		var i WorkspaceAgentMemoryEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.OomKills,
+			&i.MemoryPressure,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentMemoryEvent = `-- name: InsertWorkspaceAgentMemoryEvent :one
+INSERT INTO
+	workspace_agent_memory_events (
+		id,
+		workspace_agent_id,
+		created_at,
+		kind,
+		oom_kills,
+		memory_pressure
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, workspace_agent_id, created_at, kind, oom_kills, memory_pressure
+`
+
+type InsertWorkspaceAgentMemoryEventParams struct {
+	ID               uuid.UUID                     `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID                     `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time                     `db:"created_at" json:"created_at"`
+	Kind             WorkspaceAgentMemoryEventKind `db:"kind" json:"kind"`
+	OomKills         int32                         `db:"oom_kills" json:"oom_kills"`
+	MemoryPressure   float64                       `db:"memory_pressure" json:"memory_pressure"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentMemoryEvent(ctx context.Context, arg InsertWorkspaceAgentMemoryEventParams) (WorkspaceAgentMemoryEvent, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentMemoryEvent,
+		arg.ID,
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		arg.Kind,
+		arg.OomKills,
+		arg.MemoryPressure,
+	)
+	var i WorkspaceAgentMemoryEvent
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceAgentID,
+		&i.CreatedAt,
+		&i.Kind,
+		&i.OomKills,
+		&i.MemoryPressure,
+	)
+	return i, err
+}
+
+const deleteOldWorkspaceAgentMetadataHistory = `-- name: DeleteOldWorkspaceAgentMetadataHistory :execrows
+WITH old_history AS (
+	SELECT
+		workspace_agent_id,
+		key,
+		collected_at
+	FROM
+		workspace_agent_metadata_history
+	WHERE
+		collected_at < $1::timestamptz
+	ORDER BY
+		collected_at ASC
+	LIMIT $2
+)
+DELETE FROM
+	workspace_agent_metadata_history
+USING
+	old_history
+WHERE
+	workspace_agent_metadata_history.workspace_agent_id = old_history.workspace_agent_id
+	AND workspace_agent_metadata_history.key = old_history.key
+	AND workspace_agent_metadata_history.collected_at = old_history.collected_at
+`
+
+type DeleteOldWorkspaceAgentMetadataHistoryParams struct {
+	BeforeTime time.Time `db:"before_time" json:"before_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Deletes agent metadata history older than the given time, bounded by a row
+// limit to avoid long-running transactions.
+func (q *sqlQuerier) DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, arg DeleteOldWorkspaceAgentMetadataHistoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentMetadataHistory, arg.BeforeTime, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getWorkspaceAgentMetadataHistory = `-- name: GetWorkspaceAgentMetadataHistory :many
+SELECT DISTINCT ON (bucket)
+	CASE
+		WHEN $1::bigint > 0
+		THEN to_timestamp(floor(extract(epoch FROM collected_at) / $1::bigint) * $1::bigint)
+		ELSE collected_at
+	END::timestamptz AS bucket,
+	value,
+	error,
+	collected_at
+FROM
+	workspace_agent_metadata_history
+WHERE
+	workspace_agent_id = $2
+	AND key = $3
+	AND collected_at >= $4::timestamptz
+ORDER BY
+	bucket ASC, collected_at DESC
+`
+
+type GetWorkspaceAgentMetadataHistoryParams struct {
+	BucketSeconds    int64     `db:"bucket_seconds" json:"bucket_seconds"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              string    `db:"key" json:"key"`
+	Since            time.Time `db:"since" json:"since"`
+}
+
+type GetWorkspaceAgentMetadataHistoryRow struct {
+	Bucket      time.Time `db:"bucket" json:"bucket"`
+	Value       string    `db:"value" json:"value"`
+	Error       string    `db:"error" json:"error"`
+	CollectedAt time.Time `db:"collected_at" json:"collected_at"`
+}
+
+// Returns the values of an agent metadata key collected since the given time,
+// oldest first. When bucket_seconds is positive, only the latest value of each
+// bucket of that many seconds is returned, which downsamples long histories.
+func (q *sqlQuerier) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg GetWorkspaceAgentMetadataHistoryParams) ([]GetWorkspaceAgentMetadataHistoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentMetadataHistory,
+		arg.BucketSeconds,
+		arg.WorkspaceAgentID,
+		arg.Key,
+		arg.Since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentMetadataHistoryRow
+	for rows.Next() {
+		var i GetWorkspaceAgentMetadataHistoryRow
+		if err := rows.Scan(
+			&i.Bucket,
+			&i.Value,
+			&i.Error,
+			&i.CollectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentMetadataHistory = `-- name: InsertWorkspaceAgentMetadataHistory :exec
+INSERT INTO
+	workspace_agent_metadata_history (
+		workspace_agent_id,
+		key,
+		value,
+		error,
+		collected_at
+	)
+SELECT
+	unnest($1::uuid[]),
+	unnest($2::text[]),
+	unnest($3::text[]),
+	unnest($4::text[]),
+	unnest($5::timestamptz[])
+ON CONFLICT DO NOTHING
+`
+
+type InsertWorkspaceAgentMetadataHistoryParams struct {
+	WorkspaceAgentID []uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              []string    `db:"key" json:"key"`
+	Value            []string    `db:"value" json:"value"`
+	Error            []string    `db:"error" json:"error"`
+	CollectedAt      []time.Time `db:"collected_at" json:"collected_at"`
+}
+
+// Records a batch of agent metadata values. Values that were already recorded
+// for the same key and collection time are ignored.
+func (q *sqlQuerier) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg InsertWorkspaceAgentMetadataHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentMetadataHistory,
+		pq.Array(arg.WorkspaceAgentID),
+		pq.Array(arg.Key),
+		pq.Array(arg.Value),
+		pq.Array(arg.Error),
+		pq.Array(arg.CollectedAt),
+	)
+	return err
+}
+
+const deleteWorkspaceAgentPortShare = `-- name: DeleteWorkspaceAgentPortShare :exec
+DELETE FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
+`
+
+type DeleteWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+}
+
+func (q *sqlQuerier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	return err
+}
+
+const deleteWorkspaceAgentPortSharesByTemplate = `-- name: DeleteWorkspaceAgentPortSharesByTemplate :exec
+DELETE FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			template_id = $1
+	)
+`
+
+func (q *sqlQuerier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortSharesByTemplate, templateID)
+	return err
+}
+
+const getWorkspaceAgentPortShare = `-- name: GetWorkspaceAgentPortShare :one
+SELECT
+	workspace_id, agent_name, port, share_level, protocol
+FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
+`
+
+type GetWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	var i WorkspaceAgentPortShare
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
+		&i.Protocol,
+	)
+	return i, err
+}
+
+const listWorkspaceAgentPortShares = `-- name: ListWorkspaceAgentPortShares :many
+SELECT
+	workspace_id, agent_name, port, share_level, protocol
+FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkspaceAgentPortShares, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentPortShare
+	for rows.Next() {
+		var i WorkspaceAgentPortShare
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.AgentName,
+			&i.Port,
+			&i.ShareLevel,
+			&i.Protocol,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reduceWorkspaceAgentShareLevelToAuthenticatedByTemplate = `-- name: ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate :exec
+UPDATE
+	workspace_agent_port_share
+SET
+	share_level = 'authenticated'
+WHERE
+	share_level = 'public'
+	AND workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			template_id = $1
+	)
+`
+
+func (q *sqlQuerier) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, reduceWorkspaceAgentShareLevelToAuthenticatedByTemplate, templateID)
+	return err
+}
+
+const upsertWorkspaceAgentPortShare = `-- name: UpsertWorkspaceAgentPortShare :one
+INSERT INTO
+	workspace_agent_port_share (
+		workspace_id,
+		agent_name,
+		port,
+		share_level,
+		protocol
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5
+)
+ON CONFLICT (
+	workspace_id,
+	agent_name,
+	port
+)
+DO UPDATE SET
+	share_level = $4,
+	protocol = $5
+RETURNING workspace_id, agent_name, port, share_level, protocol
+`
+
+type UpsertWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID         `db:"workspace_id" json:"workspace_id"`
+	AgentName   string            `db:"agent_name" json:"agent_name"`
+	Port        int32             `db:"port" json:"port"`
+	ShareLevel  AppSharingLevel   `db:"share_level" json:"share_level"`
+	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentPortShare,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.Port,
+		arg.ShareLevel,
+		arg.Protocol,
+	)
+	var i WorkspaceAgentPortShare
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
+		&i.Protocol,
+	)
+	return i, err
+}
+
+const fetchMemoryResourceMonitorsByAgentID = `-- name: FetchMemoryResourceMonitorsByAgentID :one
+SELECT
+	agent_id, enabled, threshold, created_at, updated_at, state, debounced_until
+FROM
+	workspace_agent_memory_resource_monitors
+WHERE
+	agent_id = $1
+`
+
+func (q *sqlQuerier) FetchMemoryResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentMemoryResourceMonitor, error) {
+	row := q.db.QueryRowContext(ctx, fetchMemoryResourceMonitorsByAgentID, agentID)
+	var i WorkspaceAgentMemoryResourceMonitor
+	err := row.Scan(
+		&i.AgentID,
+		&i.Enabled,
+		&i.Threshold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.State,
+		&i.DebouncedUntil,
+	)
+	return i, err
+}
+
+const fetchMemoryResourceMonitorsUpdatedAfter = `-- name: FetchMemoryResourceMonitorsUpdatedAfter :many
+SELECT
+	agent_id, enabled, threshold, created_at, updated_at, state, debounced_until
+FROM
+	workspace_agent_memory_resource_monitors
+WHERE
+	updated_at > $1
+`
+
+func (q *sqlQuerier) FetchMemoryResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]WorkspaceAgentMemoryResourceMonitor, error) {
+	rows, err := q.db.QueryContext(ctx, fetchMemoryResourceMonitorsUpdatedAfter, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentMemoryResourceMonitor
+	for rows.Next() {
+		var i WorkspaceAgentMemoryResourceMonitor
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Enabled,
+			&i.Threshold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.State,
+			&i.DebouncedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchVolumesResourceMonitorsByAgentID = `-- name: FetchVolumesResourceMonitorsByAgentID :many
+SELECT
+	agent_id, enabled, threshold, path, created_at, updated_at, state, debounced_until
+FROM
+	workspace_agent_volume_resource_monitors
+WHERE
+	agent_id = $1
+`
+
+func (q *sqlQuerier) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentVolumeResourceMonitor, error) {
+	rows, err := q.db.QueryContext(ctx, fetchVolumesResourceMonitorsByAgentID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentVolumeResourceMonitor
+	for rows.Next() {
+		var i WorkspaceAgentVolumeResourceMonitor
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Enabled,
+			&i.Threshold,
+			&i.Path,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.State,
+			&i.DebouncedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchVolumesResourceMonitorsUpdatedAfter = `-- name: FetchVolumesResourceMonitorsUpdatedAfter :many
+SELECT
+	agent_id, enabled, threshold, path, created_at, updated_at, state, debounced_until
+FROM
+	workspace_agent_volume_resource_monitors
+WHERE
+	updated_at > $1
+`
+
+func (q *sqlQuerier) FetchVolumesResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]WorkspaceAgentVolumeResourceMonitor, error) {
+	rows, err := q.db.QueryContext(ctx, fetchVolumesResourceMonitorsUpdatedAfter, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentVolumeResourceMonitor
+	for rows.Next() {
+		var i WorkspaceAgentVolumeResourceMonitor
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Enabled,
+			&i.Threshold,
+			&i.Path,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.State,
+			&i.DebouncedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertMemoryResourceMonitor = `-- name: InsertMemoryResourceMonitor :one
+INSERT INTO
+	workspace_agent_memory_resource_monitors (
+		agent_id,
+		enabled,
+		state,
+		threshold,
+		created_at,
+		updated_at,
+		debounced_until
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING agent_id, enabled, threshold, created_at, updated_at, state, debounced_until
+`
+
+type InsertMemoryResourceMonitorParams struct {
+	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
+	Enabled        bool                       `db:"enabled" json:"enabled"`
+	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
+	Threshold      int32                      `db:"threshold" json:"threshold"`
+	CreatedAt      time.Time                  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
+	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
+}
+
+func (q *sqlQuerier) InsertMemoryResourceMonitor(ctx context.Context, arg InsertMemoryResourceMonitorParams) (WorkspaceAgentMemoryResourceMonitor, error) {
+	row := q.db.QueryRowContext(ctx, insertMemoryResourceMonitor,
+		arg.AgentID,
+		arg.Enabled,
+		arg.State,
+		arg.Threshold,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.DebouncedUntil,
+	)
+	var i WorkspaceAgentMemoryResourceMonitor
+	err := row.Scan(
+		&i.AgentID,
+		&i.Enabled,
+		&i.Threshold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.State,
+		&i.DebouncedUntil,
+	)
+	return i, err
+}
+
+const insertVolumeResourceMonitor = `-- name: InsertVolumeResourceMonitor :one
+INSERT INTO
+	workspace_agent_volume_resource_monitors (
+		agent_id,
+		path,
+		enabled,
+		state,
+		threshold,
+		created_at,
+		updated_at,
+		debounced_until
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING agent_id, enabled, threshold, path, created_at, updated_at, state, debounced_until
+`
+
+type InsertVolumeResourceMonitorParams struct {
+	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
+	Path           string                     `db:"path" json:"path"`
+	Enabled        bool                       `db:"enabled" json:"enabled"`
+	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
+	Threshold      int32                      `db:"threshold" json:"threshold"`
+	CreatedAt      time.Time                  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
+	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
+}
+
+func (q *sqlQuerier) InsertVolumeResourceMonitor(ctx context.Context, arg InsertVolumeResourceMonitorParams) (WorkspaceAgentVolumeResourceMonitor, error) {
+	row := q.db.QueryRowContext(ctx, insertVolumeResourceMonitor,
+		arg.AgentID,
+		arg.Path,
+		arg.Enabled,
+		arg.State,
+		arg.Threshold,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.DebouncedUntil,
+	)
+	var i WorkspaceAgentVolumeResourceMonitor
+	err := row.Scan(
+		&i.AgentID,
+		&i.Enabled,
+		&i.Threshold,
+		&i.Path,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.State,
+		&i.DebouncedUntil,
+	)
+	return i, err
+}
+
+const updateMemoryResourceMonitor = `-- name: UpdateMemoryResourceMonitor :exec
+UPDATE workspace_agent_memory_resource_monitors
+SET
+	updated_at = $2,
+	state = $3,
+	debounced_until = $4
+WHERE
+	agent_id = $1
+`
+
+type UpdateMemoryResourceMonitorParams struct {
+	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
+	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
+	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
+	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
+}
+
+func (q *sqlQuerier) UpdateMemoryResourceMonitor(ctx context.Context, arg UpdateMemoryResourceMonitorParams) error {
+	_, err := q.db.ExecContext(ctx, updateMemoryResourceMonitor,
+		arg.AgentID,
+		arg.UpdatedAt,
+		arg.State,
+		arg.DebouncedUntil,
+	)
+	return err
+}
+
+const updateVolumeResourceMonitor = `-- name: UpdateVolumeResourceMonitor :exec
+UPDATE workspace_agent_volume_resource_monitors
+SET
+		updated_at = $3,
+		state = $4,
+		debounced_until = $5
+WHERE
+		agent_id = $1 AND path = $2
+`
+
+type UpdateVolumeResourceMonitorParams struct {
+	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
+	Path           string                     `db:"path" json:"path"`
+	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
+	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
+	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
+}
+
+func (q *sqlQuerier) UpdateVolumeResourceMonitor(ctx context.Context, arg UpdateVolumeResourceMonitorParams) error {
+	_, err := q.db.ExecContext(ctx, updateVolumeResourceMonitor,
+		arg.AgentID,
+		arg.Path,
+		arg.UpdatedAt,
+		arg.State,
+		arg.DebouncedUntil,
+	)
+	return err
+}
+
+const batchUpdateWorkspaceAgentMetadata = `-- name: BatchUpdateWorkspaceAgentMetadata :exec
+WITH metadata AS (
+	SELECT
+		unnest($1::uuid[]) AS workspace_agent_id,
+		unnest($2::text[]) AS key,
+		unnest($3::text[]) AS value,
+		unnest($4::text[]) AS error,
+		unnest($5::timestamptz[]) AS collected_at
+)
+UPDATE
+	workspace_agent_metadata wam
+SET
+	value = m.value,
+	error = m.error,
+	collected_at = m.collected_at
+FROM
+	metadata m
+WHERE
+	wam.workspace_agent_id = m.workspace_agent_id
+	AND wam.key = m.key
+`
+
+type BatchUpdateWorkspaceAgentMetadataParams struct {
+	WorkspaceAgentID []uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              []string    `db:"key" json:"key"`
+	Value            []string    `db:"value" json:"value"`
+	Error            []string    `db:"error" json:"error"`
+	CollectedAt      []time.Time `db:"collected_at" json:"collected_at"`
+}
+
+func (q *sqlQuerier) BatchUpdateWorkspaceAgentMetadata(ctx context.Context, arg BatchUpdateWorkspaceAgentMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, batchUpdateWorkspaceAgentMetadata,
+		pq.Array(arg.WorkspaceAgentID),
+		pq.Array(arg.Key),
+		pq.Array(arg.Value),
+		pq.Array(arg.Error),
+		pq.Array(arg.CollectedAt),
+	)
+	return err
+}
+
+const deleteOldWorkspaceAgentLogs = `-- name: DeleteOldWorkspaceAgentLogs :execrows
+WITH
+	latest_builds AS (
+		SELECT
+			workspace_id, max(build_number) AS max_build_number
+		FROM
+			workspace_builds
+		GROUP BY
+			workspace_id
+	),
+	old_agents AS (
+		SELECT
+			wa.id
+		FROM
+			workspace_agents AS wa
+		JOIN
+			workspace_resources AS wr
+		ON
+			wa.resource_id = wr.id
+		JOIN
+			workspace_builds AS wb
+		ON
+			wb.job_id = wr.job_id
+		LEFT JOIN
+			latest_builds
+		ON
+			latest_builds.workspace_id = wb.workspace_id
+		AND
+			latest_builds.max_build_number = wb.build_number
+		WHERE
+			-- Filter out the latest builds for each workspace.
+			latest_builds.workspace_id IS NULL
+		AND CASE
+			-- If the last time the agent connected was before @threshold
+			WHEN wa.last_connected_at IS NOT NULL THEN
+				 wa.last_connected_at < $1 :: timestamptz
+			-- The agent never connected, and was created before @threshold
+			ELSE wa.created_at < $1 :: timestamptz
+		END
+	)
+DELETE FROM workspace_agent_logs WHERE agent_id IN (SELECT id FROM old_agents)
+`
+
+// If an agent hasn't connected within the retention period, we purge its logs.
+// Exception: if the logs are related to the latest build, we keep those around.
+// Logs can take up a lot of space, so it's important we clean up frequently.
+func (q *sqlQuerier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentLogs, threshold)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteWorkspaceSubAgentByID = `-- name: DeleteWorkspaceSubAgentByID :exec
+WITH soft_deleted_agents AS (
+    UPDATE workspace_agents
+    SET deleted = TRUE
+    WHERE id = $1
+        AND parent_id IS NOT NULL
+        AND deleted = FALSE
+    RETURNING id
+), purged_context_resources AS (
+    DELETE FROM workspace_agent_context_resources
+    WHERE workspace_agent_id IN (SELECT id FROM soft_deleted_agents)
+)
+DELETE FROM workspace_agent_context_snapshots
+WHERE workspace_agent_id IN (SELECT id FROM soft_deleted_agents)
+`
+
+// Soft-deletes a single sub-agent (a child agent such as a devcontainer
+// agent). Called from the DeleteSubAgent RPC when a sub-agent is torn
+// down, which can happen mid-build without a full workspace rebuild.
+//
+// Agent context rows are hard-deleted for the same reason as in
+// SoftDeletePriorWorkspaceAgents: they only describe live agents, the
+// rebuild-time soft-delete queries skip already-deleted agents, and
+// agents are never hard-deleted, so the rows would otherwise orphan
+// forever.
+func (q *sqlQuerier) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceSubAgentByID, id)
+	return err
+}
+
+const getAuthenticatedWorkspaceAgentAndBuildByAuthToken = `-- name: GetAuthenticatedWorkspaceAgentAndBuildByAuthToken :one
+SELECT
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl, workspaces.expires_at, workspaces.archived_at, workspaces.read_only_at,
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.clock_skew_ms,
+	workspace_build_with_user.id, workspace_build_with_user.created_at, workspace_build_with_user.updated_at, workspace_build_with_user.workspace_id, workspace_build_with_user.template_version_id, workspace_build_with_user.build_number, workspace_build_with_user.transition, workspace_build_with_user.initiator_id, workspace_build_with_user.job_id, workspace_build_with_user.deadline, workspace_build_with_user.reason, workspace_build_with_user.daily_cost, workspace_build_with_user.max_deadline, workspace_build_with_user.template_version_preset_id, workspace_build_with_user.has_ai_task, workspace_build_with_user.has_external_agent, workspace_build_with_user.notified_autostop_deadline, workspace_build_with_user.initiator_by_avatar_url, workspace_build_with_user.initiator_by_username, workspace_build_with_user.initiator_by_name,
+	tasks.id AS task_id
+FROM
+	workspace_agents
+JOIN
+	workspace_resources
+ON
+	workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_build_with_user
+ON
+	workspace_resources.job_id = workspace_build_with_user.job_id
+JOIN
+	workspaces
+ON
+	workspace_build_with_user.workspace_id = workspaces.id
+LEFT JOIN
+	tasks
+ON
+	tasks.workspace_id = workspaces.id
+WHERE
+	-- This should only match 1 agent, so 1 returned row or 0.
+	workspace_agents.auth_token = $1::uuid
+	AND workspaces.deleted = FALSE
+	-- Filter out deleted sub agents.
+	AND workspace_agents.deleted = FALSE
+	-- Filter out builds that are not the latest, with exception for shutdown case.
+	-- Use CASE for short-circuiting: check normal case first (most common), then shutdown case.
+	AND CASE
+		-- Normal case: Agent's build is the latest build.
+		WHEN workspace_build_with_user.build_number = (
+			SELECT
+				MAX(build_number)
+			FROM
+				workspace_builds
+			WHERE
+				workspace_id = workspace_build_with_user.workspace_id
+		) THEN TRUE
+		-- Shutdown case: Agent from previous START build during STOP build execution.
+		WHEN workspace_build_with_user.transition = 'start'
+			-- Agent's START build job succeeded.
+			AND (SELECT job_status FROM provisioner_jobs WHERE id = workspace_build_with_user.job_id) = 'succeeded'
+			-- Latest build is a STOP build whose job is still active,
+			-- and agent's build is immediately previous.
+			AND EXISTS (
+				SELECT 1
+				FROM workspace_builds latest
+				JOIN provisioner_jobs pj ON pj.id = latest.job_id
+				WHERE latest.workspace_id = workspace_build_with_user.workspace_id
+				AND latest.build_number = workspace_build_with_user.build_number + 1
+				AND latest.build_number = (
+					SELECT MAX(build_number)
+					FROM workspace_builds l2
+					WHERE l2.workspace_id = latest.workspace_id
+				)
+				AND latest.transition = 'stop'
+				AND pj.job_status IN ('pending', 'running')
+			) THEN TRUE
+		ELSE FALSE
+	END
+`
+
+type GetAuthenticatedWorkspaceAgentAndBuildByAuthTokenRow struct {
+	WorkspaceTable WorkspaceTable `db:"workspace_table" json:"workspace_table"`
+	WorkspaceAgent WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
+	WorkspaceBuild WorkspaceBuild `db:"workspace_build" json:"workspace_build"`
+	TaskID         uuid.NullUUID  `db:"task_id" json:"task_id"`
+}
+
+// GetAuthenticatedWorkspaceAgentAndBuildByAuthToken returns an authenticated
+// workspace agent and its associated build. During normal operation, this is
+// the latest build. During shutdown, this may be the previous START build while
+// the STOP build is executing, allowing shutdown scripts to authenticate (see
+// issue #19467).
+func (q *sqlQuerier) GetAuthenticatedWorkspaceAgentAndBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (GetAuthenticatedWorkspaceAgentAndBuildByAuthTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, getAuthenticatedWorkspaceAgentAndBuildByAuthToken, authToken)
+	var i GetAuthenticatedWorkspaceAgentAndBuildByAuthTokenRow
+	err := row.Scan(
+		&i.WorkspaceTable.ID,
+		&i.WorkspaceTable.CreatedAt,
+		&i.WorkspaceTable.UpdatedAt,
+		&i.WorkspaceTable.OwnerID,
+		&i.WorkspaceTable.OrganizationID,
+		&i.WorkspaceTable.TemplateID,
+		&i.WorkspaceTable.Deleted,
+		&i.WorkspaceTable.Name,
+		&i.WorkspaceTable.AutostartSchedule,
+		&i.WorkspaceTable.Ttl,
+		&i.WorkspaceTable.LastUsedAt,
+		&i.WorkspaceTable.DormantAt,
+		&i.WorkspaceTable.DeletingAt,
+		&i.WorkspaceTable.AutomaticUpdates,
+		&i.WorkspaceTable.Favorite,
+		&i.WorkspaceTable.NextStartAt,
+		&i.WorkspaceTable.GroupACL,
+		&i.WorkspaceTable.UserACL,
+		&i.WorkspaceTable.ExpiresAt,
+		&i.WorkspaceTable.ArchivedAt,
+		&i.WorkspaceTable.ReadOnlyAt,
+		&i.WorkspaceAgent.ID,
+		&i.WorkspaceAgent.CreatedAt,
+		&i.WorkspaceAgent.UpdatedAt,
+		&i.WorkspaceAgent.Name,
+		&i.WorkspaceAgent.FirstConnectedAt,
+		&i.WorkspaceAgent.LastConnectedAt,
+		&i.WorkspaceAgent.DisconnectedAt,
+		&i.WorkspaceAgent.ResourceID,
+		&i.WorkspaceAgent.AuthToken,
+		&i.WorkspaceAgent.AuthInstanceID,
+		&i.WorkspaceAgent.Architecture,
+		&i.WorkspaceAgent.EnvironmentVariables,
+		&i.WorkspaceAgent.OperatingSystem,
+		&i.WorkspaceAgent.InstanceMetadata,
+		&i.WorkspaceAgent.ResourceMetadata,
+		&i.WorkspaceAgent.Directory,
+		&i.WorkspaceAgent.Version,
+		&i.WorkspaceAgent.LastConnectedReplicaID,
+		&i.WorkspaceAgent.ConnectionTimeoutSeconds,
+		&i.WorkspaceAgent.TroubleshootingURL,
+		&i.WorkspaceAgent.MOTDFile,
+		&i.WorkspaceAgent.LifecycleState,
+		&i.WorkspaceAgent.ExpandedDirectory,
+		&i.WorkspaceAgent.LogsLength,
+		&i.WorkspaceAgent.LogsOverflowed,
+		&i.WorkspaceAgent.StartedAt,
+		&i.WorkspaceAgent.ReadyAt,
+		pq.Array(&i.WorkspaceAgent.Subsystems),
+		pq.Array(&i.WorkspaceAgent.DisplayApps),
+		&i.WorkspaceAgent.APIVersion,
+		&i.WorkspaceAgent.DisplayOrder,
+		&i.WorkspaceAgent.ParentID,
+		&i.WorkspaceAgent.APIKeyScope,
+		&i.WorkspaceAgent.Deleted,
+		&i.WorkspaceAgent.ClockSkewMs,
+		&i.WorkspaceBuild.ID,
+		&i.WorkspaceBuild.CreatedAt,
+		&i.WorkspaceBuild.UpdatedAt,
+		&i.WorkspaceBuild.WorkspaceID,
+		&i.WorkspaceBuild.TemplateVersionID,
+		&i.WorkspaceBuild.BuildNumber,
+		&i.WorkspaceBuild.Transition,
+		&i.WorkspaceBuild.InitiatorID,
+		&i.WorkspaceBuild.JobID,
+		&i.WorkspaceBuild.Deadline,
+		&i.WorkspaceBuild.Reason,
+		&i.WorkspaceBuild.DailyCost,
+		&i.WorkspaceBuild.MaxDeadline,
+		&i.WorkspaceBuild.TemplateVersionPresetID,
+		&i.WorkspaceBuild.HasAITask,
+		&i.WorkspaceBuild.HasExternalAgent,
+		&i.WorkspaceBuild.NotifiedAutostopDeadline,
+		&i.WorkspaceBuild.InitiatorByAvatarUrl,
+		&i.WorkspaceBuild.InitiatorByUsername,
+		&i.WorkspaceBuild.InitiatorByName,
+		&i.TaskID,
+	)
+	return i, err
+}
+
+const getExternalAgentTokensByTemplateID = `-- name: GetExternalAgentTokensByTemplateID :many
+SELECT
+	workspaces.id               AS workspace_id,
+	workspaces.name             AS workspace_name,
+	workspace_agents.id         AS agent_id,
+	workspace_agents.name       AS agent_name,
+	workspace_agents.auth_token AS agent_token
+FROM
+	workspaces
+JOIN (
+	-- latest build per workspace
+	SELECT DISTINCT ON (workspace_id)
+		id, workspace_id, job_id, transition, has_external_agent
+	FROM
+		workspace_builds
+	ORDER BY
+		workspace_id, build_number DESC
+) AS latest_builds
+ON
+	latest_builds.workspace_id = workspaces.id
+JOIN
+	provisioner_jobs
+ON
+	provisioner_jobs.id = latest_builds.job_id
+JOIN
+	workspace_resources
+ON
+	workspace_resources.job_id = latest_builds.job_id
+JOIN
+	workspace_agents
+ON
+	workspace_agents.resource_id = workspace_resources.id
+WHERE
+	workspaces.template_id = $1
+	AND (
+		$2 :: uuid = '00000000-0000-0000-0000-000000000000' :: uuid
+		OR workspaces.owner_id = $2
+	)
+	AND workspaces.deleted = FALSE
+	AND latest_builds.has_external_agent = TRUE
+	AND latest_builds.transition = 'start' :: workspace_transition
+	AND provisioner_jobs.job_status = 'succeeded' :: provisioner_job_status
+	AND workspace_agents.deleted = FALSE
+	AND workspace_agents.auth_instance_id IS NULL
+`
+
+type GetExternalAgentTokensByTemplateIDParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	OwnerID    uuid.UUID `db:"owner_id" json:"owner_id"`
 }
 
-func (q *sqlQuerier) UpdateUserThemeMode(ctx context.Context, arg UpdateUserThemeModeParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserThemeMode, arg.UserID, arg.ThemeMode)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+type GetExternalAgentTokensByTemplateIDRow struct {
+	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName string    `db:"workspace_name" json:"workspace_name"`
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
+	AgentName     string    `db:"agent_name" json:"agent_name"`
+	AgentToken    uuid.UUID `db:"agent_token" json:"agent_token"`
+}
+
+// GetExternalAgentTokensByTemplateID returns the auth tokens for all
+// non-deleted external agents on the latest build of every running workspace
+// of the given template. "Running" means the latest build has
+// transition=start and job_status=succeeded (matches the workspace-status
+// definition used by coderd/database/queries/workspaces.sql).
+// An owner_id of '00000000-0000-0000-0000-000000000000' (uuid.Nil) means
+// "all owners"; any other value restricts results to workspaces owned by
+// that user.
+func (q *sqlQuerier) GetExternalAgentTokensByTemplateID(ctx context.Context, arg GetExternalAgentTokensByTemplateIDParams) ([]GetExternalAgentTokensByTemplateIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExternalAgentTokensByTemplateID, arg.TemplateID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExternalAgentTokensByTemplateIDRow
+	for rows.Next() {
+		var i GetExternalAgentTokensByTemplateIDRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.AgentID,
+			&i.AgentName,
+			&i.AgentToken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentAndWorkspaceByID = `-- name: GetWorkspaceAgentAndWorkspaceByID :one
+SELECT
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.clock_skew_ms,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl, workspaces.expires_at, workspaces.archived_at, workspaces.read_only_at,
+	users.username as owner_username
+FROM
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	provisioner_jobs ON workspace_resources.job_id = provisioner_jobs.id
+JOIN
+	workspace_builds ON provisioner_jobs.id = workspace_builds.job_id
+JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+JOIN
+	users ON workspaces.owner_id = users.id
+WHERE
+	workspace_agents.id = $1
+	AND workspace_agents.deleted = FALSE
+	AND provisioner_jobs.type = 'workspace_build'::provisioner_job_type
+	AND workspaces.deleted = FALSE
+	AND users.deleted = FALSE
+LIMIT 1
+`
+
+type GetWorkspaceAgentAndWorkspaceByIDRow struct {
+	WorkspaceAgent WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
+	WorkspaceTable WorkspaceTable `db:"workspace_table" json:"workspace_table"`
+	OwnerUsername  string         `db:"owner_username" json:"owner_username"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentAndWorkspaceByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentAndWorkspaceByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentAndWorkspaceByID, id)
+	var i GetWorkspaceAgentAndWorkspaceByIDRow
+	err := row.Scan(
+		&i.WorkspaceAgent.ID,
+		&i.WorkspaceAgent.CreatedAt,
+		&i.WorkspaceAgent.UpdatedAt,
+		&i.WorkspaceAgent.Name,
+		&i.WorkspaceAgent.FirstConnectedAt,
+		&i.WorkspaceAgent.LastConnectedAt,
+		&i.WorkspaceAgent.DisconnectedAt,
+		&i.WorkspaceAgent.ResourceID,
+		&i.WorkspaceAgent.AuthToken,
+		&i.WorkspaceAgent.AuthInstanceID,
+		&i.WorkspaceAgent.Architecture,
+		&i.WorkspaceAgent.EnvironmentVariables,
+		&i.WorkspaceAgent.OperatingSystem,
+		&i.WorkspaceAgent.InstanceMetadata,
+		&i.WorkspaceAgent.ResourceMetadata,
+		&i.WorkspaceAgent.Directory,
+		&i.WorkspaceAgent.Version,
+		&i.WorkspaceAgent.LastConnectedReplicaID,
+		&i.WorkspaceAgent.ConnectionTimeoutSeconds,
+		&i.WorkspaceAgent.TroubleshootingURL,
+		&i.WorkspaceAgent.MOTDFile,
+		&i.WorkspaceAgent.LifecycleState,
+		&i.WorkspaceAgent.ExpandedDirectory,
+		&i.WorkspaceAgent.LogsLength,
+		&i.WorkspaceAgent.LogsOverflowed,
+		&i.WorkspaceAgent.StartedAt,
+		&i.WorkspaceAgent.ReadyAt,
+		pq.Array(&i.WorkspaceAgent.Subsystems),
+		pq.Array(&i.WorkspaceAgent.DisplayApps),
+		&i.WorkspaceAgent.APIVersion,
+		&i.WorkspaceAgent.DisplayOrder,
+		&i.WorkspaceAgent.ParentID,
+		&i.WorkspaceAgent.APIKeyScope,
+		&i.WorkspaceAgent.Deleted,
+		&i.WorkspaceAgent.ClockSkewMs,
+		&i.WorkspaceTable.ID,
+		&i.WorkspaceTable.CreatedAt,
+		&i.WorkspaceTable.UpdatedAt,
+		&i.WorkspaceTable.OwnerID,
+		&i.WorkspaceTable.OrganizationID,
+		&i.WorkspaceTable.TemplateID,
+		&i.WorkspaceTable.Deleted,
+		&i.WorkspaceTable.Name,
+		&i.WorkspaceTable.AutostartSchedule,
+		&i.WorkspaceTable.Ttl,
+		&i.WorkspaceTable.LastUsedAt,
+		&i.WorkspaceTable.DormantAt,
+		&i.WorkspaceTable.DeletingAt,
+		&i.WorkspaceTable.AutomaticUpdates,
+		&i.WorkspaceTable.Favorite,
+		&i.WorkspaceTable.NextStartAt,
+		&i.WorkspaceTable.GroupACL,
+		&i.WorkspaceTable.UserACL,
+		&i.WorkspaceTable.ExpiresAt,
+		&i.WorkspaceTable.ArchivedAt,
+		&i.WorkspaceTable.ReadOnlyAt,
+		&i.OwnerUsername,
+	)
 	return i, err
 }
 
-const updateUserThemePreference = `-- name: UpdateUserThemePreference :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'theme_preference', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'theme_preference'
-RETURNING user_id, key, value
+const getWorkspaceAgentByID = `-- name: GetWorkspaceAgentByID :one
+SELECT
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, clock_skew_ms
+FROM
+	workspace_agents
+WHERE
+	id = $1
+	-- Filter out deleted sub agents.
+	AND deleted = FALSE
 `
 
-type UpdateUserThemePreferenceParams struct {
-	UserID          uuid.UUID `db:"user_id" json:"user_id"`
-	ThemePreference string    `db:"theme_preference" json:"theme_preference"`
+func (q *sqlQuerier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentByID, id)
+	var i WorkspaceAgent
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.FirstConnectedAt,
+		&i.LastConnectedAt,
+		&i.DisconnectedAt,
+		&i.ResourceID,
+		&i.AuthToken,
+		&i.AuthInstanceID,
+		&i.Architecture,
+		&i.EnvironmentVariables,
+		&i.OperatingSystem,
+		&i.InstanceMetadata,
+		&i.ResourceMetadata,
+		&i.Directory,
+		&i.Version,
+		&i.LastConnectedReplicaID,
+		&i.ConnectionTimeoutSeconds,
+		&i.TroubleshootingURL,
+		&i.MOTDFile,
+		&i.LifecycleState,
+		&i.ExpandedDirectory,
+		&i.LogsLength,
+		&i.LogsOverflowed,
+		&i.StartedAt,
+		&i.ReadyAt,
+		pq.Array(&i.Subsystems),
+		pq.Array(&i.DisplayApps),
+		&i.APIVersion,
+		&i.DisplayOrder,
+		&i.ParentID,
+		&i.APIKeyScope,
+		&i.Deleted,
+		&i.ClockSkewMs,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserThemePreference, arg.UserID, arg.ThemePreference)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+const getWorkspaceAgentLifecycleStateByID = `-- name: GetWorkspaceAgentLifecycleStateByID :one
+SELECT
+	lifecycle_state,
+	started_at,
+	ready_at
+FROM
+	workspace_agents
+WHERE
+	id = $1
+`
+
+type GetWorkspaceAgentLifecycleStateByIDRow struct {
+	LifecycleState WorkspaceAgentLifecycleState `db:"lifecycle_state" json:"lifecycle_state"`
+	StartedAt      sql.NullTime                 `db:"started_at" json:"started_at"`
+	ReadyAt        sql.NullTime                 `db:"ready_at" json:"ready_at"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentLifecycleStateByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentLifecycleStateByID, id)
+	var i GetWorkspaceAgentLifecycleStateByIDRow
+	err := row.Scan(&i.LifecycleState, &i.StartedAt, &i.ReadyAt)
 	return i, err
 }
 
-const updateUserThinkingDisplayMode = `-- name: UpdateUserThinkingDisplayMode :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'preference_thinking_display_mode', $2::text)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'preference_thinking_display_mode'
-RETURNING value AS thinking_display_mode
+const getWorkspaceAgentLogSourcesByAgentIDs = `-- name: GetWorkspaceAgentLogSourcesByAgentIDs :many
+SELECT workspace_agent_id, id, created_at, display_name, icon FROM workspace_agent_log_sources WHERE workspace_agent_id = ANY($1 :: uuid [ ])
 `
 
-type UpdateUserThinkingDisplayModeParams struct {
-	UserID              uuid.UUID `db:"user_id" json:"user_id"`
-	ThinkingDisplayMode string    `db:"thinking_display_mode" json:"thinking_display_mode"`
+func (q *sqlQuerier) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentLogSource, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentLogSourcesByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentLogSource
+	for rows.Next() {
+		var i WorkspaceAgentLogSource
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.DisplayName,
+			&i.Icon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) UpdateUserThinkingDisplayMode(ctx context.Context, arg UpdateUserThinkingDisplayModeParams) (string, error) {
-	row := q.db.QueryRowContext(ctx, updateUserThinkingDisplayMode, arg.UserID, arg.ThinkingDisplayMode)
-	var thinking_display_mode string
-	err := row.Scan(&thinking_display_mode)
-	return thinking_display_mode, err
+const getWorkspaceAgentLogsAfter = `-- name: GetWorkspaceAgentLogsAfter :many
+SELECT
+	agent_id, created_at, output, id, level, log_source_id
+FROM
+	workspace_agent_logs
+WHERE
+	agent_id = $1
+	AND (
+		id > $2
+	) ORDER BY id ASC
+`
+
+type GetWorkspaceAgentLogsAfterParams struct {
+	AgentID      uuid.UUID `db:"agent_id" json:"agent_id"`
+	CreatedAfter int64     `db:"created_after" json:"created_after"`
 }
 
-const upsertUserChatDebugLoggingEnabled = `-- name: UpsertUserChatDebugLoggingEnabled :exec
-INSERT INTO user_configs (user_id, key, value)
-VALUES (
-	$1,
-	'chat_debug_logging_enabled',
-	CASE
-		WHEN $2::bool THEN 'true'
-		ELSE 'false'
-	END
-)
-ON CONFLICT ON CONSTRAINT user_configs_pkey
-DO UPDATE SET value = CASE
-	WHEN $2::bool THEN 'true'
-	ELSE 'false'
-END
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'chat_debug_logging_enabled'
+func (q *sqlQuerier) GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWorkspaceAgentLogsAfterParams) ([]WorkspaceAgentLog, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentLogsAfter, arg.AgentID, arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentLog
+	for rows.Next() {
+		var i WorkspaceAgentLog
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.CreatedAt,
+			&i.Output,
+			&i.ID,
+			&i.Level,
+			&i.LogSourceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentMetadata = `-- name: GetWorkspaceAgentMetadata :many
+SELECT
+	workspace_agent_id, display_name, key, script, value, error, timeout, interval, collected_at, display_order
+FROM
+	workspace_agent_metadata
+WHERE
+	workspace_agent_id = $1
+	AND CASE WHEN COALESCE(array_length($2::text[], 1), 0) > 0 THEN key = ANY($2::text[]) ELSE TRUE END
 `
 
-type UpsertUserChatDebugLoggingEnabledParams struct {
-	UserID              uuid.UUID `db:"user_id" json:"user_id"`
-	DebugLoggingEnabled bool      `db:"debug_logging_enabled" json:"debug_logging_enabled"`
+type GetWorkspaceAgentMetadataParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Keys             []string  `db:"keys" json:"keys"`
 }
 
-func (q *sqlQuerier) UpsertUserChatDebugLoggingEnabled(ctx context.Context, arg UpsertUserChatDebugLoggingEnabledParams) error {
-	_, err := q.db.ExecContext(ctx, upsertUserChatDebugLoggingEnabled, arg.UserID, arg.DebugLoggingEnabled)
-	return err
+func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorkspaceAgentMetadataParams) ([]WorkspaceAgentMetadatum, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentMetadata, arg.WorkspaceAgentID, pq.Array(arg.Keys))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentMetadatum
+	for rows.Next() {
+		var i WorkspaceAgentMetadatum
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.DisplayName,
+			&i.Key,
+			&i.Script,
+			&i.Value,
+			&i.Error,
+			&i.Timeout,
+			&i.Interval,
+			&i.CollectedAt,
+			&i.DisplayOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const upsertUserChatPersonalModelOverride = `-- name: UpsertUserChatPersonalModelOverride :exec
-INSERT INTO user_configs (user_id, key, value)
-VALUES ($1::uuid, $2::text, $3::text)
-ON CONFLICT ON CONSTRAINT user_configs_pkey
-DO UPDATE SET value = $3::text
+const getWorkspaceAgentScriptTimingsByBuildID = `-- name: GetWorkspaceAgentScriptTimingsByBuildID :many
+SELECT
+	DISTINCT ON (workspace_agent_script_timings.script_id) workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at, workspace_agent_script_timings.ended_at, workspace_agent_script_timings.exit_code, workspace_agent_script_timings.stage, workspace_agent_script_timings.status,
+	workspace_agent_scripts.display_name,
+	workspace_agents.id as workspace_agent_id,
+	workspace_agents.name as workspace_agent_name
+FROM workspace_agent_script_timings
+INNER JOIN workspace_agent_scripts ON workspace_agent_scripts.id = workspace_agent_script_timings.script_id
+INNER JOIN workspace_agents ON workspace_agents.id = workspace_agent_scripts.workspace_agent_id
+INNER JOIN workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+INNER JOIN workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+WHERE workspace_builds.id = $1
+ORDER BY workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at
 `
 
-type UpsertUserChatPersonalModelOverrideParams struct {
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-	Key    string    `db:"key" json:"key"`
-	Value  string    `db:"value" json:"value"`
+type GetWorkspaceAgentScriptTimingsByBuildIDRow struct {
+	ScriptID           uuid.UUID                        `db:"script_id" json:"script_id"`
+	StartedAt          time.Time                        `db:"started_at" json:"started_at"`
+	EndedAt            time.Time                        `db:"ended_at" json:"ended_at"`
+	ExitCode           int32                            `db:"exit_code" json:"exit_code"`
+	Stage              WorkspaceAgentScriptTimingStage  `db:"stage" json:"stage"`
+	Status             WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
+	DisplayName        string                           `db:"display_name" json:"display_name"`
+	WorkspaceAgentID   uuid.UUID                        `db:"workspace_agent_id" json:"workspace_agent_id"`
+	WorkspaceAgentName string                           `db:"workspace_agent_name" json:"workspace_agent_name"`
 }
 
-func (q *sqlQuerier) UpsertUserChatPersonalModelOverride(ctx context.Context, arg UpsertUserChatPersonalModelOverrideParams) error {
-	_, err := q.db.ExecContext(ctx, upsertUserChatPersonalModelOverride, arg.UserID, arg.Key, arg.Value)
-	return err
+func (q *sqlQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentScriptTimingsByBuildID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentScriptTimingsByBuildIDRow
+	for rows.Next() {
+		var i GetWorkspaceAgentScriptTimingsByBuildIDRow
+		if err := rows.Scan(
+			&i.ScriptID,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.ExitCode,
+			&i.Stage,
+			&i.Status,
+			&i.DisplayName,
+			&i.WorkspaceAgentID,
+			&i.WorkspaceAgentName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentsByInstanceID = `-- name: GetWorkspaceAgentsByInstanceID :many
+SELECT
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, clock_skew_ms
+FROM
+	workspace_agents
+WHERE
+	auth_instance_id = $1 :: TEXT
+	-- Filter out deleted agents.
+	AND deleted = FALSE
+	-- Filter out sub agents, they do not authenticate with auth_instance_id.
+	AND parent_id IS NULL
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentsByInstanceID(ctx context.Context, authInstanceID string) ([]WorkspaceAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsByInstanceID, authInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgent
+	for rows.Next() {
+		var i WorkspaceAgent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.FirstConnectedAt,
+			&i.LastConnectedAt,
+			&i.DisconnectedAt,
+			&i.ResourceID,
+			&i.AuthToken,
+			&i.AuthInstanceID,
+			&i.Architecture,
+			&i.EnvironmentVariables,
+			&i.OperatingSystem,
+			&i.InstanceMetadata,
+			&i.ResourceMetadata,
+			&i.Directory,
+			&i.Version,
+			&i.LastConnectedReplicaID,
+			&i.ConnectionTimeoutSeconds,
+			&i.TroubleshootingURL,
+			&i.MOTDFile,
+			&i.LifecycleState,
+			&i.ExpandedDirectory,
+			&i.LogsLength,
+			&i.LogsOverflowed,
+			&i.StartedAt,
+			&i.ReadyAt,
+			pq.Array(&i.Subsystems),
+			pq.Array(&i.DisplayApps),
+			&i.APIVersion,
+			&i.DisplayOrder,
+			&i.ParentID,
+			&i.APIKeyScope,
+			&i.Deleted,
+			&i.ClockSkewMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const validateUserIDs = `-- name: ValidateUserIDs :one
-WITH input AS (
-	SELECT
-		unnest($1::uuid[]) AS id
-)
+const getWorkspaceAgentsByParentID = `-- name: GetWorkspaceAgentsByParentID :many
 SELECT
-	array_agg(input.id)::uuid[] as invalid_user_ids,
-	COUNT(*) = 0 as ok
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, clock_skew_ms
 FROM
-	-- Preserve rows where there is not a matching left (users) row for each
-	-- right (input) row...
-	users
-	RIGHT JOIN input ON users.id = input.id
+	workspace_agents
 WHERE
-	-- ...so that we can retain exactly those rows where an input ID does not
-	-- match an existing user...
-	users.id IS NULL OR
-	-- ...or that only matches a user that was deleted.
-	users.deleted = true
+	parent_id = $1::uuid
+	AND deleted = FALSE
 `
 
-type ValidateUserIDsRow struct {
-	InvalidUserIds []uuid.UUID `db:"invalid_user_ids" json:"invalid_user_ids"`
-	Ok             bool        `db:"ok" json:"ok"`
-}
-
-func (q *sqlQuerier) ValidateUserIDs(ctx context.Context, userIds []uuid.UUID) (ValidateUserIDsRow, error) {
-	row := q.db.QueryRowContext(ctx, validateUserIDs, pq.Array(userIds))
-	var i ValidateUserIDsRow
-	err := row.Scan(pq.Array(&i.InvalidUserIds), &i.Ok)
-	return i, err
+func (q *sqlQuerier) GetWorkspaceAgentsByParentID(ctx context.Context, parentID uuid.UUID) ([]WorkspaceAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsByParentID, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgent
+	for rows.Next() {
+		var i WorkspaceAgent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.FirstConnectedAt,
+			&i.LastConnectedAt,
+			&i.DisconnectedAt,
+			&i.ResourceID,
+			&i.AuthToken,
+			&i.AuthInstanceID,
+			&i.Architecture,
+			&i.EnvironmentVariables,
+			&i.OperatingSystem,
+			&i.InstanceMetadata,
+			&i.ResourceMetadata,
+			&i.Directory,
+			&i.Version,
+			&i.LastConnectedReplicaID,
+			&i.ConnectionTimeoutSeconds,
+			&i.TroubleshootingURL,
+			&i.MOTDFile,
+			&i.LifecycleState,
+			&i.ExpandedDirectory,
+			&i.LogsLength,
+			&i.LogsOverflowed,
+			&i.StartedAt,
+			&i.ReadyAt,
+			pq.Array(&i.Subsystems),
+			pq.Array(&i.DisplayApps),
+			&i.APIVersion,
+			&i.DisplayOrder,
+			&i.ParentID,
+			&i.APIKeyScope,
+			&i.Deleted,
+			&i.ClockSkewMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const deleteStaleWorkspaceAgentContextResources = `-- name: DeleteStaleWorkspaceAgentContextResources :exec
-DELETE FROM workspace_agent_context_resources
-WHERE workspace_agent_id = $1
-  AND NOT (source = ANY($2 :: text[]))
+const getWorkspaceAgentsByResourceIDs = `-- name: GetWorkspaceAgentsByResourceIDs :many
+SELECT
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, clock_skew_ms
+FROM
+	workspace_agents
+WHERE
+	resource_id = ANY($1 :: uuid [ ])
+	-- Filter out deleted sub agents.
+	AND deleted = FALSE
 `
 
-type DeleteStaleWorkspaceAgentContextResourcesParams struct {
-	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
-	ActiveSources    []string  `db:"active_sources" json:"active_sources"`
-}
-
-// Deletes any resources for the agent whose source is not in the
-// supplied active set. Atomic alongside the snapshot upsert so the
-// stored snapshot and resource rows always agree.
-func (q *sqlQuerier) DeleteStaleWorkspaceAgentContextResources(ctx context.Context, arg DeleteStaleWorkspaceAgentContextResourcesParams) error {
-	_, err := q.db.ExecContext(ctx, deleteStaleWorkspaceAgentContextResources, arg.WorkspaceAgentID, pq.Array(arg.ActiveSources))
-	return err
+func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsByResourceIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgent
+	for rows.Next() {
+		var i WorkspaceAgent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.FirstConnectedAt,
+			&i.LastConnectedAt,
+			&i.DisconnectedAt,
+			&i.ResourceID,
+			&i.AuthToken,
+			&i.AuthInstanceID,
+			&i.Architecture,
+			&i.EnvironmentVariables,
+			&i.OperatingSystem,
+			&i.InstanceMetadata,
+			&i.ResourceMetadata,
+			&i.Directory,
+			&i.Version,
+			&i.LastConnectedReplicaID,
+			&i.ConnectionTimeoutSeconds,
+			&i.TroubleshootingURL,
+			&i.MOTDFile,
+			&i.LifecycleState,
+			&i.ExpandedDirectory,
+			&i.LogsLength,
+			&i.LogsOverflowed,
+			&i.StartedAt,
+			&i.ReadyAt,
+			pq.Array(&i.Subsystems),
+			pq.Array(&i.DisplayApps),
+			&i.APIVersion,
+			&i.DisplayOrder,
+			&i.ParentID,
+			&i.APIKeyScope,
+			&i.Deleted,
+			&i.ClockSkewMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getLatestWorkspaceAgentContextSnapshot = `-- name: GetLatestWorkspaceAgentContextSnapshot :one
-SELECT workspace_agent_id, version, aggregate_hash, snapshot_error, received_at FROM workspace_agent_context_snapshots
-WHERE workspace_agent_id = $1
+const getWorkspaceAgentsByWorkspaceAndBuildNumber = `-- name: GetWorkspaceAgentsByWorkspaceAndBuildNumber :many
+SELECT
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.clock_skew_ms
+FROM
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+WHERE
+	workspace_builds.workspace_id = $1 :: uuid AND
+	workspace_builds.build_number = $2 :: int
+	-- Filter out deleted sub agents.
+	AND workspace_agents.deleted = FALSE
 `
 
-func (q *sqlQuerier) GetLatestWorkspaceAgentContextSnapshot(ctx context.Context, workspaceAgentID uuid.UUID) (WorkspaceAgentContextSnapshot, error) {
-	row := q.db.QueryRowContext(ctx, getLatestWorkspaceAgentContextSnapshot, workspaceAgentID)
-	var i WorkspaceAgentContextSnapshot
-	err := row.Scan(
-		&i.WorkspaceAgentID,
-		&i.Version,
-		&i.AggregateHash,
-		&i.SnapshotError,
-		&i.ReceivedAt,
-	)
-	return i, err
+type GetWorkspaceAgentsByWorkspaceAndBuildNumberParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	BuildNumber int32     `db:"build_number" json:"build_number"`
 }
 
-const listWorkspaceAgentContextResources = `-- name: ListWorkspaceAgentContextResources :many
-SELECT workspace_agent_id, source, body_kind, body, content_hash, size_bytes, status, error, source_path, created_at, updated_at FROM workspace_agent_context_resources
-WHERE workspace_agent_id = $1
-ORDER BY source ASC
-`
-
-func (q *sqlQuerier) ListWorkspaceAgentContextResources(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentContextResource, error) {
-	rows, err := q.db.QueryContext(ctx, listWorkspaceAgentContextResources, workspaceAgentID)
+func (q *sqlQuerier) GetWorkspaceAgentsByWorkspaceAndBuildNumber(ctx context.Context, arg GetWorkspaceAgentsByWorkspaceAndBuildNumberParams) ([]WorkspaceAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsByWorkspaceAndBuildNumber, arg.WorkspaceID, arg.BuildNumber)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentContextResource
+	var items []WorkspaceAgent
 	for rows.Next() {
-		var i WorkspaceAgentContextResource
+		var i WorkspaceAgent
 		if err := rows.Scan(
-			&i.WorkspaceAgentID,
-			&i.Source,
-			&i.BodyKind,
-			&i.Body,
-			&i.ContentHash,
-			&i.SizeBytes,
-			&i.Status,
-			&i.Error,
-			&i.SourcePath,
+			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Name,
+			&i.FirstConnectedAt,
+			&i.LastConnectedAt,
+			&i.DisconnectedAt,
+			&i.ResourceID,
+			&i.AuthToken,
+			&i.AuthInstanceID,
+			&i.Architecture,
+			&i.EnvironmentVariables,
+			&i.OperatingSystem,
+			&i.InstanceMetadata,
+			&i.ResourceMetadata,
+			&i.Directory,
+			&i.Version,
+			&i.LastConnectedReplicaID,
+			&i.ConnectionTimeoutSeconds,
+			&i.TroubleshootingURL,
+			&i.MOTDFile,
+			&i.LifecycleState,
+			&i.ExpandedDirectory,
+			&i.LogsLength,
+			&i.LogsOverflowed,
+			&i.StartedAt,
+			&i.ReadyAt,
+			pq.Array(&i.Subsystems),
+			pq.Array(&i.DisplayApps),
+			&i.APIVersion,
+			&i.DisplayOrder,
+			&i.ParentID,
+			&i.APIKeyScope,
+			&i.Deleted,
+			&i.ClockSkewMs,
 		); err != nil {
 			return nil, err
 		}
@@ -31854,164 +39312,238 @@ func (q *sqlQuerier) ListWorkspaceAgentContextResources(ctx context.Context, wor
 	return items, nil
 }
 
-const upsertWorkspaceAgentContextResource = `-- name: UpsertWorkspaceAgentContextResource :one
-INSERT INTO workspace_agent_context_resources (
-    workspace_agent_id,
-    source,
-    body_kind,
-    body,
-    content_hash,
-    size_bytes,
-    status,
-    error,
-    source_path,
-    created_at,
-    updated_at
-) VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5,
-    $6,
-    $7,
-    $8,
-    $9,
-    $10,
-    $10
-)
-ON CONFLICT (workspace_agent_id, source) DO UPDATE SET
-    body_kind = EXCLUDED.body_kind,
-    body = EXCLUDED.body,
-    content_hash = EXCLUDED.content_hash,
-    size_bytes = EXCLUDED.size_bytes,
-    status = EXCLUDED.status,
-    error = EXCLUDED.error,
-    source_path = EXCLUDED.source_path,
-    updated_at = EXCLUDED.updated_at
-RETURNING workspace_agent_id, source, body_kind, body, content_hash, size_bytes, status, error, source_path, created_at, updated_at
+const getWorkspaceAgentsCreatedAfter = `-- name: GetWorkspaceAgentsCreatedAfter :many
+SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, clock_skew_ms FROM workspace_agents
+WHERE
+	created_at > $1
+	-- Filter out deleted sub agents.
+	AND deleted = FALSE
 `
 
-type UpsertWorkspaceAgentContextResourceParams struct {
-	WorkspaceAgentID uuid.UUID                           `db:"workspace_agent_id" json:"workspace_agent_id"`
-	Source           string                              `db:"source" json:"source"`
-	BodyKind         WorkspaceAgentContextBodyKind       `db:"body_kind" json:"body_kind"`
-	Body             json.RawMessage                     `db:"body" json:"body"`
-	ContentHash      []byte                              `db:"content_hash" json:"content_hash"`
-	SizeBytes        int64                               `db:"size_bytes" json:"size_bytes"`
-	Status           WorkspaceAgentContextResourceStatus `db:"status" json:"status"`
-	Error            string                              `db:"error" json:"error"`
-	SourcePath       string                              `db:"source_path" json:"source_path"`
-	Now              time.Time                           `db:"now" json:"now"`
-}
-
-func (q *sqlQuerier) UpsertWorkspaceAgentContextResource(ctx context.Context, arg UpsertWorkspaceAgentContextResourceParams) (WorkspaceAgentContextResource, error) {
-	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentContextResource,
-		arg.WorkspaceAgentID,
-		arg.Source,
-		arg.BodyKind,
-		arg.Body,
-		arg.ContentHash,
-		arg.SizeBytes,
-		arg.Status,
-		arg.Error,
-		arg.SourcePath,
-		arg.Now,
-	)
-	var i WorkspaceAgentContextResource
-	err := row.Scan(
-		&i.WorkspaceAgentID,
-		&i.Source,
-		&i.BodyKind,
-		&i.Body,
-		&i.ContentHash,
-		&i.SizeBytes,
-		&i.Status,
-		&i.Error,
-		&i.SourcePath,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsCreatedAfter, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgent
+	for rows.Next() {
+		var i WorkspaceAgent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.FirstConnectedAt,
+			&i.LastConnectedAt,
+			&i.DisconnectedAt,
+			&i.ResourceID,
+			&i.AuthToken,
+			&i.AuthInstanceID,
+			&i.Architecture,
+			&i.EnvironmentVariables,
+			&i.OperatingSystem,
+			&i.InstanceMetadata,
+			&i.ResourceMetadata,
+			&i.Directory,
+			&i.Version,
+			&i.LastConnectedReplicaID,
+			&i.ConnectionTimeoutSeconds,
+			&i.TroubleshootingURL,
+			&i.MOTDFile,
+			&i.LifecycleState,
+			&i.ExpandedDirectory,
+			&i.LogsLength,
+			&i.LogsOverflowed,
+			&i.StartedAt,
+			&i.ReadyAt,
+			pq.Array(&i.Subsystems),
+			pq.Array(&i.DisplayApps),
+			&i.APIVersion,
+			&i.DisplayOrder,
+			&i.ParentID,
+			&i.APIKeyScope,
+			&i.Deleted,
+			&i.ClockSkewMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const upsertWorkspaceAgentContextSnapshot = `-- name: UpsertWorkspaceAgentContextSnapshot :one
-INSERT INTO workspace_agent_context_snapshots (
-    workspace_agent_id,
-    version,
-    aggregate_hash,
-    snapshot_error,
-    received_at
-) VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5
+const getWorkspaceAgentsForMetrics = `-- name: GetWorkspaceAgentsForMetrics :many
+SELECT
+    w.id as workspace_id,
+    w.name as workspace_name,
+    u.username as owner_username,
+    t.name as template_name,
+    tv.name as template_version_name,
+    workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.clock_skew_ms
+FROM workspaces w
+JOIN users u ON w.owner_id = u.id
+JOIN templates t ON w.template_id = t.id
+JOIN workspace_builds wb ON w.id = wb.workspace_id
+LEFT JOIN template_versions tv ON wb.template_version_id = tv.id
+JOIN workspace_resources wr ON wb.job_id = wr.job_id
+JOIN workspace_agents ON wr.id = workspace_agents.resource_id
+WHERE w.deleted = false
+AND wb.build_number = (
+    SELECT MAX(wb2.build_number)
+    FROM workspace_builds wb2
+    WHERE wb2.workspace_id = w.id
 )
-ON CONFLICT (workspace_agent_id) DO UPDATE SET
-    version = EXCLUDED.version,
-    aggregate_hash = EXCLUDED.aggregate_hash,
-    snapshot_error = EXCLUDED.snapshot_error,
-    received_at = EXCLUDED.received_at
-RETURNING workspace_agent_id, version, aggregate_hash, snapshot_error, received_at
+AND workspace_agents.deleted = FALSE
 `
 
-type UpsertWorkspaceAgentContextSnapshotParams struct {
-	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
-	Version          int64     `db:"version" json:"version"`
-	AggregateHash    []byte    `db:"aggregate_hash" json:"aggregate_hash"`
-	SnapshotError    string    `db:"snapshot_error" json:"snapshot_error"`
-	ReceivedAt       time.Time `db:"received_at" json:"received_at"`
+type GetWorkspaceAgentsForMetricsRow struct {
+	WorkspaceID         uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName       string         `db:"workspace_name" json:"workspace_name"`
+	OwnerUsername       string         `db:"owner_username" json:"owner_username"`
+	TemplateName        string         `db:"template_name" json:"template_name"`
+	TemplateVersionName sql.NullString `db:"template_version_name" json:"template_version_name"`
+	WorkspaceAgent      WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
 }
 
-func (q *sqlQuerier) UpsertWorkspaceAgentContextSnapshot(ctx context.Context, arg UpsertWorkspaceAgentContextSnapshotParams) (WorkspaceAgentContextSnapshot, error) {
-	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentContextSnapshot,
-		arg.WorkspaceAgentID,
-		arg.Version,
-		arg.AggregateHash,
-		arg.SnapshotError,
-		arg.ReceivedAt,
-	)
-	var i WorkspaceAgentContextSnapshot
-	err := row.Scan(
-		&i.WorkspaceAgentID,
-		&i.Version,
-		&i.AggregateHash,
-		&i.SnapshotError,
-		&i.ReceivedAt,
-	)
-	return i, err
+func (q *sqlQuerier) GetWorkspaceAgentsForMetrics(ctx context.Context) ([]GetWorkspaceAgentsForMetricsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsForMetrics)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentsForMetricsRow
+	for rows.Next() {
+		var i GetWorkspaceAgentsForMetricsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerUsername,
+			&i.TemplateName,
+			&i.TemplateVersionName,
+			&i.WorkspaceAgent.ID,
+			&i.WorkspaceAgent.CreatedAt,
+			&i.WorkspaceAgent.UpdatedAt,
+			&i.WorkspaceAgent.Name,
+			&i.WorkspaceAgent.FirstConnectedAt,
+			&i.WorkspaceAgent.LastConnectedAt,
+			&i.WorkspaceAgent.DisconnectedAt,
+			&i.WorkspaceAgent.ResourceID,
+			&i.WorkspaceAgent.AuthToken,
+			&i.WorkspaceAgent.AuthInstanceID,
+			&i.WorkspaceAgent.Architecture,
+			&i.WorkspaceAgent.EnvironmentVariables,
+			&i.WorkspaceAgent.OperatingSystem,
+			&i.WorkspaceAgent.InstanceMetadata,
+			&i.WorkspaceAgent.ResourceMetadata,
+			&i.WorkspaceAgent.Directory,
+			&i.WorkspaceAgent.Version,
+			&i.WorkspaceAgent.LastConnectedReplicaID,
+			&i.WorkspaceAgent.ConnectionTimeoutSeconds,
+			&i.WorkspaceAgent.TroubleshootingURL,
+			&i.WorkspaceAgent.MOTDFile,
+			&i.WorkspaceAgent.LifecycleState,
+			&i.WorkspaceAgent.ExpandedDirectory,
+			&i.WorkspaceAgent.LogsLength,
+			&i.WorkspaceAgent.LogsOverflowed,
+			&i.WorkspaceAgent.StartedAt,
+			&i.WorkspaceAgent.ReadyAt,
+			pq.Array(&i.WorkspaceAgent.Subsystems),
+			pq.Array(&i.WorkspaceAgent.DisplayApps),
+			&i.WorkspaceAgent.APIVersion,
+			&i.WorkspaceAgent.DisplayOrder,
+			&i.WorkspaceAgent.ParentID,
+			&i.WorkspaceAgent.APIKeyScope,
+			&i.WorkspaceAgent.Deleted,
+			&i.WorkspaceAgent.ClockSkewMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getWorkspaceAgentDevcontainersByAgentID = `-- name: GetWorkspaceAgentDevcontainersByAgentID :many
+const getWorkspaceAgentsInLatestBuildByWorkspaceID = `-- name: GetWorkspaceAgentsInLatestBuildByWorkspaceID :many
 SELECT
-	id, workspace_agent_id, created_at, workspace_folder, config_path, name, subagent_id
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.clock_skew_ms
 FROM
-	workspace_agent_devcontainers
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
 WHERE
-	workspace_agent_id = $1
-ORDER BY
-	created_at, id
+	workspace_builds.workspace_id = $1 :: uuid AND
+	workspace_builds.build_number = (
+    	SELECT
+			MAX(build_number)
+    	FROM
+			workspace_builds AS wb
+    	WHERE
+			wb.workspace_id = $1 :: uuid
+	)
+	-- Filter out deleted sub agents.
+	AND workspace_agents.deleted = FALSE
 `
 
-func (q *sqlQuerier) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentDevcontainer, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentDevcontainersByAgentID, workspaceAgentID)
+func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsInLatestBuildByWorkspaceID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentDevcontainer
+	var items []WorkspaceAgent
 	for rows.Next() {
-		var i WorkspaceAgentDevcontainer
+		var i WorkspaceAgent
 		if err := rows.Scan(
 			&i.ID,
-			&i.WorkspaceAgentID,
 			&i.CreatedAt,
-			&i.WorkspaceFolder,
-			&i.ConfigPath,
+			&i.UpdatedAt,
 			&i.Name,
-			&i.SubagentID,
+			&i.FirstConnectedAt,
+			&i.LastConnectedAt,
+			&i.DisconnectedAt,
+			&i.ResourceID,
+			&i.AuthToken,
+			&i.AuthInstanceID,
+			&i.Architecture,
+			&i.EnvironmentVariables,
+			&i.OperatingSystem,
+			&i.InstanceMetadata,
+			&i.ResourceMetadata,
+			&i.Directory,
+			&i.Version,
+			&i.LastConnectedReplicaID,
+			&i.ConnectionTimeoutSeconds,
+			&i.TroubleshootingURL,
+			&i.MOTDFile,
+			&i.LifecycleState,
+			&i.ExpandedDirectory,
+			&i.LogsLength,
+			&i.LogsOverflowed,
+			&i.StartedAt,
+			&i.ReadyAt,
+			pq.Array(&i.Subsystems),
+			pq.Array(&i.DisplayApps),
+			&i.APIVersion,
+			&i.DisplayOrder,
+			&i.ParentID,
+			&i.APIKeyScope,
+			&i.Deleted,
+			&i.ClockSkewMs,
 		); err != nil {
 			return nil, err
 		}
@@ -32026,55 +39558,84 @@ func (q *sqlQuerier) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context
 	return items, nil
 }
 
-const insertWorkspaceAgentDevcontainers = `-- name: InsertWorkspaceAgentDevcontainers :many
-INSERT INTO
-	workspace_agent_devcontainers (workspace_agent_id, created_at, id, name, workspace_folder, config_path, subagent_id)
+const getWorkspaceAgentsInLatestBuildByWorkspaceIDs = `-- name: GetWorkspaceAgentsInLatestBuildByWorkspaceIDs :many
 SELECT
-	$1::uuid AS workspace_agent_id,
-	$2::timestamptz AS created_at,
-	unnest($3::uuid[]) AS id,
-	unnest($4::text[]) AS name,
-	unnest($5::text[]) AS workspace_folder,
-	unnest($6::text[]) AS config_path,
-	NULLIF(unnest($7::uuid[]), '00000000-0000-0000-0000-000000000000')::uuid AS subagent_id
-RETURNING workspace_agent_devcontainers.id, workspace_agent_devcontainers.workspace_agent_id, workspace_agent_devcontainers.created_at, workspace_agent_devcontainers.workspace_folder, workspace_agent_devcontainers.config_path, workspace_agent_devcontainers.name, workspace_agent_devcontainers.subagent_id
+	workspace_builds.workspace_id,
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.clock_skew_ms
+FROM
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+JOIN (
+	SELECT
+		workspace_id,
+		MAX(build_number) AS build_number
+	FROM
+		workspace_builds
+	WHERE
+		workspace_id = ANY($1 :: uuid [ ])
+	GROUP BY
+		workspace_id
+) AS latest_builds ON
+	latest_builds.workspace_id = workspace_builds.workspace_id AND
+	latest_builds.build_number = workspace_builds.build_number
+WHERE
+	workspace_agents.deleted = FALSE
 `
 
-type InsertWorkspaceAgentDevcontainersParams struct {
-	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
-	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
-	ID               []uuid.UUID `db:"id" json:"id"`
-	Name             []string    `db:"name" json:"name"`
-	WorkspaceFolder  []string    `db:"workspace_folder" json:"workspace_folder"`
-	ConfigPath       []string    `db:"config_path" json:"config_path"`
-	SubagentID       []uuid.UUID `db:"subagent_id" json:"subagent_id"`
+type GetWorkspaceAgentsInLatestBuildByWorkspaceIDsRow struct {
+	WorkspaceID    uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	WorkspaceAgent WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAgentDevcontainers(ctx context.Context, arg InsertWorkspaceAgentDevcontainersParams) ([]WorkspaceAgentDevcontainer, error) {
-	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentDevcontainers,
-		arg.WorkspaceAgentID,
-		arg.CreatedAt,
-		pq.Array(arg.ID),
-		pq.Array(arg.Name),
-		pq.Array(arg.WorkspaceFolder),
-		pq.Array(arg.ConfigPath),
-		pq.Array(arg.SubagentID),
-	)
+func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceIDs(ctx context.Context, workspaceIds []uuid.UUID) ([]GetWorkspaceAgentsInLatestBuildByWorkspaceIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsInLatestBuildByWorkspaceIDs, pq.Array(workspaceIds))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentDevcontainer
+	var items []GetWorkspaceAgentsInLatestBuildByWorkspaceIDsRow
 	for rows.Next() {
-		var i WorkspaceAgentDevcontainer
+		var i GetWorkspaceAgentsInLatestBuildByWorkspaceIDsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.WorkspaceAgentID,
-			&i.CreatedAt,
-			&i.WorkspaceFolder,
-			&i.ConfigPath,
-			&i.Name,
-			&i.SubagentID,
+			&i.WorkspaceID,
+			&i.WorkspaceAgent.ID,
+			&i.WorkspaceAgent.CreatedAt,
+			&i.WorkspaceAgent.UpdatedAt,
+			&i.WorkspaceAgent.Name,
+			&i.WorkspaceAgent.FirstConnectedAt,
+			&i.WorkspaceAgent.LastConnectedAt,
+			&i.WorkspaceAgent.DisconnectedAt,
+			&i.WorkspaceAgent.ResourceID,
+			&i.WorkspaceAgent.AuthToken,
+			&i.WorkspaceAgent.AuthInstanceID,
+			&i.WorkspaceAgent.Architecture,
+			&i.WorkspaceAgent.EnvironmentVariables,
+			&i.WorkspaceAgent.OperatingSystem,
+			&i.WorkspaceAgent.InstanceMetadata,
+			&i.WorkspaceAgent.ResourceMetadata,
+			&i.WorkspaceAgent.Directory,
+			&i.WorkspaceAgent.Version,
+			&i.WorkspaceAgent.LastConnectedReplicaID,
+			&i.WorkspaceAgent.ConnectionTimeoutSeconds,
+			&i.WorkspaceAgent.TroubleshootingURL,
+			&i.WorkspaceAgent.MOTDFile,
+			&i.WorkspaceAgent.LifecycleState,
+			&i.WorkspaceAgent.ExpandedDirectory,
+			&i.WorkspaceAgent.LogsLength,
+			&i.WorkspaceAgent.LogsOverflowed,
+			&i.WorkspaceAgent.StartedAt,
+			&i.WorkspaceAgent.ReadyAt,
+			pq.Array(&i.WorkspaceAgent.Subsystems),
+			pq.Array(&i.WorkspaceAgent.DisplayApps),
+			&i.WorkspaceAgent.APIVersion,
+			&i.WorkspaceAgent.DisplayOrder,
+			&i.WorkspaceAgent.ParentID,
+			&i.WorkspaceAgent.APIKeyScope,
+			&i.WorkspaceAgent.Deleted,
+			&i.WorkspaceAgent.ClockSkewMs,
 		); err != nil {
 			return nil, err
 		}
@@ -32089,99 +39650,112 @@ func (q *sqlQuerier) InsertWorkspaceAgentDevcontainers(ctx context.Context, arg
 	return items, nil
 }
 
-const deleteWorkspaceAgentPortShare = `-- name: DeleteWorkspaceAgentPortShare :exec
-DELETE FROM
-	workspace_agent_port_share
-WHERE
-	workspace_id = $1
-	AND agent_name = $2
-	AND port = $3
-`
-
-type DeleteWorkspaceAgentPortShareParams struct {
-	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	AgentName   string    `db:"agent_name" json:"agent_name"`
-	Port        int32     `db:"port" json:"port"`
-}
-
-func (q *sqlQuerier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error {
-	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
-	return err
-}
-
-const deleteWorkspaceAgentPortSharesByTemplate = `-- name: DeleteWorkspaceAgentPortSharesByTemplate :exec
-DELETE FROM
-	workspace_agent_port_share
-WHERE
-	workspace_id IN (
-		SELECT
-			id
-		FROM
-			workspaces
-		WHERE
-			template_id = $1
-	)
-`
-
-func (q *sqlQuerier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortSharesByTemplate, templateID)
-	return err
-}
-
-const getWorkspaceAgentPortShare = `-- name: GetWorkspaceAgentPortShare :one
+const getWorkspaceBuildAgentsByInstanceID = `-- name: GetWorkspaceBuildAgentsByInstanceID :many
 SELECT
-	workspace_id, agent_name, port, share_level, protocol
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted, workspace_agents.clock_skew_ms,
+	workspace_builds.id AS workspace_build_id,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl, workspaces.expires_at, workspaces.archived_at, workspaces.read_only_at
 FROM
-	workspace_agent_port_share
+	workspace_agents
+JOIN
+	workspace_resources
+ON
+	workspace_resources.id = workspace_agents.resource_id
+JOIN
+	workspace_builds
+ON
+	workspace_builds.job_id = workspace_resources.job_id
+JOIN
+	provisioner_jobs
+ON
+	provisioner_jobs.id = workspace_builds.job_id
+JOIN
+	workspaces
+ON
+	workspaces.id = workspace_builds.workspace_id
 WHERE
-	workspace_id = $1
-	AND agent_name = $2
-	AND port = $3
+	workspace_agents.auth_instance_id = $1 :: TEXT
+	AND workspace_agents.deleted = FALSE
+	AND workspace_agents.parent_id IS NULL
+	AND provisioner_jobs.type = 'workspace_build'::provisioner_job_type
+	AND workspaces.deleted = FALSE
+ORDER BY
+	workspace_agents.created_at DESC
 `
 
-type GetWorkspaceAgentPortShareParams struct {
-	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	AgentName   string    `db:"agent_name" json:"agent_name"`
-	Port        int32     `db:"port" json:"port"`
-}
-
-func (q *sqlQuerier) GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
-	var i WorkspaceAgentPortShare
-	err := row.Scan(
-		&i.WorkspaceID,
-		&i.AgentName,
-		&i.Port,
-		&i.ShareLevel,
-		&i.Protocol,
-	)
-	return i, err
+type GetWorkspaceBuildAgentsByInstanceIDRow struct {
+	WorkspaceAgent   WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
+	WorkspaceBuildID uuid.UUID      `db:"workspace_build_id" json:"workspace_build_id"`
+	WorkspaceTable   WorkspaceTable `db:"workspace_table" json:"workspace_table"`
 }
 
-const listWorkspaceAgentPortShares = `-- name: ListWorkspaceAgentPortShares :many
-SELECT
-	workspace_id, agent_name, port, share_level, protocol
-FROM
-	workspace_agent_port_share
-WHERE
-	workspace_id = $1
-`
-
-func (q *sqlQuerier) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error) {
-	rows, err := q.db.QueryContext(ctx, listWorkspaceAgentPortShares, workspaceID)
+func (q *sqlQuerier) GetWorkspaceBuildAgentsByInstanceID(ctx context.Context, authInstanceID string) ([]GetWorkspaceBuildAgentsByInstanceIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildAgentsByInstanceID, authInstanceID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentPortShare
+	var items []GetWorkspaceBuildAgentsByInstanceIDRow
 	for rows.Next() {
-		var i WorkspaceAgentPortShare
+		var i GetWorkspaceBuildAgentsByInstanceIDRow
 		if err := rows.Scan(
-			&i.WorkspaceID,
-			&i.AgentName,
-			&i.Port,
-			&i.ShareLevel,
-			&i.Protocol,
+			&i.WorkspaceAgent.ID,
+			&i.WorkspaceAgent.CreatedAt,
+			&i.WorkspaceAgent.UpdatedAt,
+			&i.WorkspaceAgent.Name,
+			&i.WorkspaceAgent.FirstConnectedAt,
+			&i.WorkspaceAgent.LastConnectedAt,
+			&i.WorkspaceAgent.DisconnectedAt,
+			&i.WorkspaceAgent.ResourceID,
+			&i.WorkspaceAgent.AuthToken,
+			&i.WorkspaceAgent.AuthInstanceID,
+			&i.WorkspaceAgent.Architecture,
+			&i.WorkspaceAgent.EnvironmentVariables,
+			&i.WorkspaceAgent.OperatingSystem,
+			&i.WorkspaceAgent.InstanceMetadata,
+			&i.WorkspaceAgent.ResourceMetadata,
+			&i.WorkspaceAgent.Directory,
+			&i.WorkspaceAgent.Version,
+			&i.WorkspaceAgent.LastConnectedReplicaID,
+			&i.WorkspaceAgent.ConnectionTimeoutSeconds,
+			&i.WorkspaceAgent.TroubleshootingURL,
+			&i.WorkspaceAgent.MOTDFile,
+			&i.WorkspaceAgent.LifecycleState,
+			&i.WorkspaceAgent.ExpandedDirectory,
+			&i.WorkspaceAgent.LogsLength,
+			&i.WorkspaceAgent.LogsOverflowed,
+			&i.WorkspaceAgent.StartedAt,
+			&i.WorkspaceAgent.ReadyAt,
+			pq.Array(&i.WorkspaceAgent.Subsystems),
+			pq.Array(&i.WorkspaceAgent.DisplayApps),
+			&i.WorkspaceAgent.APIVersion,
+			&i.WorkspaceAgent.DisplayOrder,
+			&i.WorkspaceAgent.ParentID,
+			&i.WorkspaceAgent.APIKeyScope,
+			&i.WorkspaceAgent.Deleted,
+			&i.WorkspaceAgent.ClockSkewMs,
+			&i.WorkspaceBuildID,
+			&i.WorkspaceTable.ID,
+			&i.WorkspaceTable.CreatedAt,
+			&i.WorkspaceTable.UpdatedAt,
+			&i.WorkspaceTable.OwnerID,
+			&i.WorkspaceTable.OrganizationID,
+			&i.WorkspaceTable.TemplateID,
+			&i.WorkspaceTable.Deleted,
+			&i.WorkspaceTable.Name,
+			&i.WorkspaceTable.AutostartSchedule,
+			&i.WorkspaceTable.Ttl,
+			&i.WorkspaceTable.LastUsedAt,
+			&i.WorkspaceTable.DormantAt,
+			&i.WorkspaceTable.DeletingAt,
+			&i.WorkspaceTable.AutomaticUpdates,
+			&i.WorkspaceTable.Favorite,
+			&i.WorkspaceTable.NextStartAt,
+			&i.WorkspaceTable.GroupACL,
+			&i.WorkspaceTable.UserACL,
+			&i.WorkspaceTable.ExpiresAt,
+			&i.WorkspaceTable.ArchivedAt,
+			&i.WorkspaceTable.ReadOnlyAt,
 		); err != nil {
 			return nil, err
 		}
@@ -32196,173 +39770,162 @@ func (q *sqlQuerier) ListWorkspaceAgentPortShares(ctx context.Context, workspace
 	return items, nil
 }
 
-const reduceWorkspaceAgentShareLevelToAuthenticatedByTemplate = `-- name: ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate :exec
-UPDATE
-	workspace_agent_port_share
-SET
-	share_level = 'authenticated'
-WHERE
-	share_level = 'public'
-	AND workspace_id IN (
-		SELECT
-			id
-		FROM
-			workspaces
-		WHERE
-			template_id = $1
-	)
-`
-
-func (q *sqlQuerier) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, reduceWorkspaceAgentShareLevelToAuthenticatedByTemplate, templateID)
-	return err
-}
-
-const upsertWorkspaceAgentPortShare = `-- name: UpsertWorkspaceAgentPortShare :one
+const insertWorkspaceAgent = `-- name: InsertWorkspaceAgent :one
 INSERT INTO
-	workspace_agent_port_share (
-		workspace_id,
-		agent_name,
-		port,
-		share_level,
-		protocol
+	workspace_agents (
+		id,
+		parent_id,
+		created_at,
+		updated_at,
+		name,
+		resource_id,
+		auth_token,
+		auth_instance_id,
+		architecture,
+		environment_variables,
+		operating_system,
+		directory,
+		instance_metadata,
+		resource_metadata,
+		connection_timeout_seconds,
+		troubleshooting_url,
+		motd_file,
+		display_apps,
+		display_order,
+		api_key_scope
 	)
-VALUES (
-	$1,
-	$2,
-	$3,
-	$4,
-	$5
-)
-ON CONFLICT (
-	workspace_id,
-	agent_name,
-	port
-)
-DO UPDATE SET
-	share_level = $4,
-	protocol = $5
-RETURNING workspace_id, agent_name, port, share_level, protocol
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted, clock_skew_ms
 `
 
-type UpsertWorkspaceAgentPortShareParams struct {
-	WorkspaceID uuid.UUID         `db:"workspace_id" json:"workspace_id"`
-	AgentName   string            `db:"agent_name" json:"agent_name"`
-	Port        int32             `db:"port" json:"port"`
-	ShareLevel  AppSharingLevel   `db:"share_level" json:"share_level"`
-	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
-}
-
-func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
-	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentPortShare,
-		arg.WorkspaceID,
-		arg.AgentName,
-		arg.Port,
-		arg.ShareLevel,
-		arg.Protocol,
-	)
-	var i WorkspaceAgentPortShare
-	err := row.Scan(
-		&i.WorkspaceID,
-		&i.AgentName,
-		&i.Port,
-		&i.ShareLevel,
-		&i.Protocol,
-	)
-	return i, err
-}
-
-const fetchMemoryResourceMonitorsByAgentID = `-- name: FetchMemoryResourceMonitorsByAgentID :one
-SELECT
-	agent_id, enabled, threshold, created_at, updated_at, state, debounced_until
-FROM
-	workspace_agent_memory_resource_monitors
-WHERE
-	agent_id = $1
-`
+type InsertWorkspaceAgentParams struct {
+	ID                       uuid.UUID             `db:"id" json:"id"`
+	ParentID                 uuid.NullUUID         `db:"parent_id" json:"parent_id"`
+	CreatedAt                time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt                time.Time             `db:"updated_at" json:"updated_at"`
+	Name                     string                `db:"name" json:"name"`
+	ResourceID               uuid.UUID             `db:"resource_id" json:"resource_id"`
+	AuthToken                uuid.UUID             `db:"auth_token" json:"auth_token"`
+	AuthInstanceID           sql.NullString        `db:"auth_instance_id" json:"auth_instance_id"`
+	Architecture             string                `db:"architecture" json:"architecture"`
+	EnvironmentVariables     pqtype.NullRawMessage `db:"environment_variables" json:"environment_variables"`
+	OperatingSystem          string                `db:"operating_system" json:"operating_system"`
+	Directory                string                `db:"directory" json:"directory"`
+	InstanceMetadata         pqtype.NullRawMessage `db:"instance_metadata" json:"instance_metadata"`
+	ResourceMetadata         pqtype.NullRawMessage `db:"resource_metadata" json:"resource_metadata"`
+	ConnectionTimeoutSeconds int32                 `db:"connection_timeout_seconds" json:"connection_timeout_seconds"`
+	TroubleshootingURL       string                `db:"troubleshooting_url" json:"troubleshooting_url"`
+	MOTDFile                 string                `db:"motd_file" json:"motd_file"`
+	DisplayApps              []DisplayApp          `db:"display_apps" json:"display_apps"`
+	DisplayOrder             int32                 `db:"display_order" json:"display_order"`
+	APIKeyScope              AgentKeyScopeEnum     `db:"api_key_scope" json:"api_key_scope"`
+}
 
-func (q *sqlQuerier) FetchMemoryResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentMemoryResourceMonitor, error) {
-	row := q.db.QueryRowContext(ctx, fetchMemoryResourceMonitorsByAgentID, agentID)
-	var i WorkspaceAgentMemoryResourceMonitor
+func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgent,
+		arg.ID,
+		arg.ParentID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.ResourceID,
+		arg.AuthToken,
+		arg.AuthInstanceID,
+		arg.Architecture,
+		arg.EnvironmentVariables,
+		arg.OperatingSystem,
+		arg.Directory,
+		arg.InstanceMetadata,
+		arg.ResourceMetadata,
+		arg.ConnectionTimeoutSeconds,
+		arg.TroubleshootingURL,
+		arg.MOTDFile,
+		pq.Array(arg.DisplayApps),
+		arg.DisplayOrder,
+		arg.APIKeyScope,
+	)
+	var i WorkspaceAgent
 	err := row.Scan(
-		&i.AgentID,
-		&i.Enabled,
-		&i.Threshold,
+		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.State,
-		&i.DebouncedUntil,
+		&i.Name,
+		&i.FirstConnectedAt,
+		&i.LastConnectedAt,
+		&i.DisconnectedAt,
+		&i.ResourceID,
+		&i.AuthToken,
+		&i.AuthInstanceID,
+		&i.Architecture,
+		&i.EnvironmentVariables,
+		&i.OperatingSystem,
+		&i.InstanceMetadata,
+		&i.ResourceMetadata,
+		&i.Directory,
+		&i.Version,
+		&i.LastConnectedReplicaID,
+		&i.ConnectionTimeoutSeconds,
+		&i.TroubleshootingURL,
+		&i.MOTDFile,
+		&i.LifecycleState,
+		&i.ExpandedDirectory,
+		&i.LogsLength,
+		&i.LogsOverflowed,
+		&i.StartedAt,
+		&i.ReadyAt,
+		pq.Array(&i.Subsystems),
+		pq.Array(&i.DisplayApps),
+		&i.APIVersion,
+		&i.DisplayOrder,
+		&i.ParentID,
+		&i.APIKeyScope,
+		&i.Deleted,
+		&i.ClockSkewMs,
 	)
 	return i, err
 }
 
-const fetchMemoryResourceMonitorsUpdatedAfter = `-- name: FetchMemoryResourceMonitorsUpdatedAfter :many
-SELECT
-	agent_id, enabled, threshold, created_at, updated_at, state, debounced_until
-FROM
-	workspace_agent_memory_resource_monitors
-WHERE
-	updated_at > $1
+const insertWorkspaceAgentLogSources = `-- name: InsertWorkspaceAgentLogSources :many
+INSERT INTO
+		workspace_agent_log_sources (workspace_agent_id, created_at, id, display_name, icon)
+	SELECT
+		$1 :: uuid AS workspace_agent_id,
+		$2 :: timestamptz AS created_at,
+		unnest($3 :: uuid [ ]) AS id,
+		unnest($4 :: VARCHAR(127) [ ]) AS display_name,
+		unnest($5 :: text [ ]) AS icon
+	RETURNING workspace_agent_log_sources.workspace_agent_id, workspace_agent_log_sources.id, workspace_agent_log_sources.created_at, workspace_agent_log_sources.display_name, workspace_agent_log_sources.icon
 `
 
-func (q *sqlQuerier) FetchMemoryResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]WorkspaceAgentMemoryResourceMonitor, error) {
-	rows, err := q.db.QueryContext(ctx, fetchMemoryResourceMonitorsUpdatedAfter, updatedAt)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []WorkspaceAgentMemoryResourceMonitor
-	for rows.Next() {
-		var i WorkspaceAgentMemoryResourceMonitor
-		if err := rows.Scan(
-			&i.AgentID,
-			&i.Enabled,
-			&i.Threshold,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.State,
-			&i.DebouncedUntil,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type InsertWorkspaceAgentLogSourcesParams struct {
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	ID               []uuid.UUID `db:"id" json:"id"`
+	DisplayName      []string    `db:"display_name" json:"display_name"`
+	Icon             []string    `db:"icon" json:"icon"`
 }
 
-const fetchVolumesResourceMonitorsByAgentID = `-- name: FetchVolumesResourceMonitorsByAgentID :many
-SELECT
-	agent_id, enabled, threshold, path, created_at, updated_at, state, debounced_until
-FROM
-	workspace_agent_volume_resource_monitors
-WHERE
-	agent_id = $1
-`
-
-func (q *sqlQuerier) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentVolumeResourceMonitor, error) {
-	rows, err := q.db.QueryContext(ctx, fetchVolumesResourceMonitorsByAgentID, agentID)
+func (q *sqlQuerier) InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentLogSources,
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		pq.Array(arg.ID),
+		pq.Array(arg.DisplayName),
+		pq.Array(arg.Icon),
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentVolumeResourceMonitor
+	var items []WorkspaceAgentLogSource
 	for rows.Next() {
-		var i WorkspaceAgentVolumeResourceMonitor
+		var i WorkspaceAgentLogSource
 		if err := rows.Scan(
-			&i.AgentID,
-			&i.Enabled,
-			&i.Threshold,
-			&i.Path,
+			&i.WorkspaceAgentID,
+			&i.ID,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.State,
-			&i.DebouncedUntil,
+			&i.DisplayName,
+			&i.Icon,
 		); err != nil {
 			return nil, err
 		}
@@ -32377,33 +39940,54 @@ func (q *sqlQuerier) FetchVolumesResourceMonitorsByAgentID(ctx context.Context,
 	return items, nil
 }
 
-const fetchVolumesResourceMonitorsUpdatedAfter = `-- name: FetchVolumesResourceMonitorsUpdatedAfter :many
-SELECT
-	agent_id, enabled, threshold, path, created_at, updated_at, state, debounced_until
-FROM
-	workspace_agent_volume_resource_monitors
-WHERE
-	updated_at > $1
+const insertWorkspaceAgentLogs = `-- name: InsertWorkspaceAgentLogs :many
+WITH new_length AS (
+	UPDATE workspace_agents SET
+	logs_length = logs_length + $6 WHERE workspace_agents.id = $1
+)
+INSERT INTO
+		workspace_agent_logs (agent_id, created_at, output, level, log_source_id)
+	SELECT
+		$1 :: uuid AS agent_id,
+		$2 :: timestamptz AS created_at,
+		unnest($3 :: VARCHAR(1024) [ ]) AS output,
+		unnest($4 :: log_level [ ]) AS level,
+		$5 :: uuid AS log_source_id
+	RETURNING workspace_agent_logs.agent_id, workspace_agent_logs.created_at, workspace_agent_logs.output, workspace_agent_logs.id, workspace_agent_logs.level, workspace_agent_logs.log_source_id
 `
 
-func (q *sqlQuerier) FetchVolumesResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]WorkspaceAgentVolumeResourceMonitor, error) {
-	rows, err := q.db.QueryContext(ctx, fetchVolumesResourceMonitorsUpdatedAfter, updatedAt)
+type InsertWorkspaceAgentLogsParams struct {
+	AgentID      uuid.UUID  `db:"agent_id" json:"agent_id"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	Output       []string   `db:"output" json:"output"`
+	Level        []LogLevel `db:"level" json:"level"`
+	LogSourceID  uuid.UUID  `db:"log_source_id" json:"log_source_id"`
+	OutputLength int32      `db:"output_length" json:"output_length"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWorkspaceAgentLogsParams) ([]WorkspaceAgentLog, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentLogs,
+		arg.AgentID,
+		arg.CreatedAt,
+		pq.Array(arg.Output),
+		pq.Array(arg.Level),
+		arg.LogSourceID,
+		arg.OutputLength,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentVolumeResourceMonitor
+	var items []WorkspaceAgentLog
 	for rows.Next() {
-		var i WorkspaceAgentVolumeResourceMonitor
+		var i WorkspaceAgentLog
 		if err := rows.Scan(
 			&i.AgentID,
-			&i.Enabled,
-			&i.Threshold,
-			&i.Path,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.State,
-			&i.DebouncedUntil,
+			&i.Output,
+			&i.ID,
+			&i.Level,
+			&i.LogSourceID,
 		); err != nil {
 			return nil, err
 		}
@@ -32418,264 +40002,143 @@ func (q *sqlQuerier) FetchVolumesResourceMonitorsUpdatedAfter(ctx context.Contex
 	return items, nil
 }
 
-const insertMemoryResourceMonitor = `-- name: InsertMemoryResourceMonitor :one
+const insertWorkspaceAgentMetadata = `-- name: InsertWorkspaceAgentMetadata :exec
 INSERT INTO
-	workspace_agent_memory_resource_monitors (
-		agent_id,
-		enabled,
-		state,
-		threshold,
-		created_at,
-		updated_at,
-		debounced_until
+	workspace_agent_metadata (
+		workspace_agent_id,
+		display_name,
+		key,
+		script,
+		timeout,
+		interval,
+		display_order
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7) RETURNING agent_id, enabled, threshold, created_at, updated_at, state, debounced_until
+	($1, $2, $3, $4, $5, $6, $7)
 `
 
-type InsertMemoryResourceMonitorParams struct {
-	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
-	Enabled        bool                       `db:"enabled" json:"enabled"`
-	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
-	Threshold      int32                      `db:"threshold" json:"threshold"`
-	CreatedAt      time.Time                  `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
-	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
+type InsertWorkspaceAgentMetadataParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	DisplayName      string    `db:"display_name" json:"display_name"`
+	Key              string    `db:"key" json:"key"`
+	Script           string    `db:"script" json:"script"`
+	Timeout          int64     `db:"timeout" json:"timeout"`
+	Interval         int64     `db:"interval" json:"interval"`
+	DisplayOrder     int32     `db:"display_order" json:"display_order"`
 }
 
-func (q *sqlQuerier) InsertMemoryResourceMonitor(ctx context.Context, arg InsertMemoryResourceMonitorParams) (WorkspaceAgentMemoryResourceMonitor, error) {
-	row := q.db.QueryRowContext(ctx, insertMemoryResourceMonitor,
-		arg.AgentID,
-		arg.Enabled,
-		arg.State,
-		arg.Threshold,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.DebouncedUntil,
-	)
-	var i WorkspaceAgentMemoryResourceMonitor
-	err := row.Scan(
-		&i.AgentID,
-		&i.Enabled,
-		&i.Threshold,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.State,
-		&i.DebouncedUntil,
+func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentMetadata,
+		arg.WorkspaceAgentID,
+		arg.DisplayName,
+		arg.Key,
+		arg.Script,
+		arg.Timeout,
+		arg.Interval,
+		arg.DisplayOrder,
 	)
-	return i, err
+	return err
 }
 
-const insertVolumeResourceMonitor = `-- name: InsertVolumeResourceMonitor :one
+const insertWorkspaceAgentScriptTimings = `-- name: InsertWorkspaceAgentScriptTimings :one
 INSERT INTO
-	workspace_agent_volume_resource_monitors (
-		agent_id,
-		path,
-		enabled,
-		state,
-		threshold,
-		created_at,
-		updated_at,
-		debounced_until
-	)
+    workspace_agent_script_timings (
+        script_id,
+        started_at,
+        ended_at,
+        exit_code,
+        stage,
+        status
+    )
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING agent_id, enabled, threshold, path, created_at, updated_at, state, debounced_until
+    ($1, $2, $3, $4, $5, $6)
+RETURNING workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at, workspace_agent_script_timings.ended_at, workspace_agent_script_timings.exit_code, workspace_agent_script_timings.stage, workspace_agent_script_timings.status
 `
 
-type InsertVolumeResourceMonitorParams struct {
-	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
-	Path           string                     `db:"path" json:"path"`
-	Enabled        bool                       `db:"enabled" json:"enabled"`
-	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
-	Threshold      int32                      `db:"threshold" json:"threshold"`
-	CreatedAt      time.Time                  `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
-	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
+type InsertWorkspaceAgentScriptTimingsParams struct {
+	ScriptID  uuid.UUID                        `db:"script_id" json:"script_id"`
+	StartedAt time.Time                        `db:"started_at" json:"started_at"`
+	EndedAt   time.Time                        `db:"ended_at" json:"ended_at"`
+	ExitCode  int32                            `db:"exit_code" json:"exit_code"`
+	Stage     WorkspaceAgentScriptTimingStage  `db:"stage" json:"stage"`
+	Status    WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
 }
 
-func (q *sqlQuerier) InsertVolumeResourceMonitor(ctx context.Context, arg InsertVolumeResourceMonitorParams) (WorkspaceAgentVolumeResourceMonitor, error) {
-	row := q.db.QueryRowContext(ctx, insertVolumeResourceMonitor,
-		arg.AgentID,
-		arg.Path,
-		arg.Enabled,
-		arg.State,
-		arg.Threshold,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.DebouncedUntil,
+func (q *sqlQuerier) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg InsertWorkspaceAgentScriptTimingsParams) (WorkspaceAgentScriptTiming, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentScriptTimings,
+		arg.ScriptID,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.ExitCode,
+		arg.Stage,
+		arg.Status,
 	)
-	var i WorkspaceAgentVolumeResourceMonitor
+	var i WorkspaceAgentScriptTiming
 	err := row.Scan(
-		&i.AgentID,
-		&i.Enabled,
-		&i.Threshold,
-		&i.Path,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.State,
-		&i.DebouncedUntil,
+		&i.ScriptID,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.ExitCode,
+		&i.Stage,
+		&i.Status,
 	)
 	return i, err
 }
 
-const updateMemoryResourceMonitor = `-- name: UpdateMemoryResourceMonitor :exec
-UPDATE workspace_agent_memory_resource_monitors
-SET
-	updated_at = $2,
-	state = $3,
-	debounced_until = $4
-WHERE
-	agent_id = $1
-`
-
-type UpdateMemoryResourceMonitorParams struct {
-	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
-	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
-	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
-	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
-}
-
-func (q *sqlQuerier) UpdateMemoryResourceMonitor(ctx context.Context, arg UpdateMemoryResourceMonitorParams) error {
-	_, err := q.db.ExecContext(ctx, updateMemoryResourceMonitor,
-		arg.AgentID,
-		arg.UpdatedAt,
-		arg.State,
-		arg.DebouncedUntil,
-	)
-	return err
-}
-
-const updateVolumeResourceMonitor = `-- name: UpdateVolumeResourceMonitor :exec
-UPDATE workspace_agent_volume_resource_monitors
-SET
-		updated_at = $3,
-		state = $4,
-		debounced_until = $5
-WHERE
-		agent_id = $1 AND path = $2
-`
-
-type UpdateVolumeResourceMonitorParams struct {
-	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
-	Path           string                     `db:"path" json:"path"`
-	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
-	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
-	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
-}
-
-func (q *sqlQuerier) UpdateVolumeResourceMonitor(ctx context.Context, arg UpdateVolumeResourceMonitorParams) error {
-	_, err := q.db.ExecContext(ctx, updateVolumeResourceMonitor,
-		arg.AgentID,
-		arg.Path,
-		arg.UpdatedAt,
-		arg.State,
-		arg.DebouncedUntil,
-	)
-	return err
-}
-
-const batchUpdateWorkspaceAgentMetadata = `-- name: BatchUpdateWorkspaceAgentMetadata :exec
-WITH metadata AS (
-	SELECT
-		unnest($1::uuid[]) AS workspace_agent_id,
-		unnest($2::text[]) AS key,
-		unnest($3::text[]) AS value,
-		unnest($4::text[]) AS error,
-		unnest($5::timestamptz[]) AS collected_at
+const softDeletePriorWorkspaceAgents = `-- name: SoftDeletePriorWorkspaceAgents :exec
+WITH soft_deleted_agents AS (
+    UPDATE workspace_agents
+    SET deleted = TRUE
+    WHERE id IN (
+        SELECT wa.id
+        FROM workspace_agents wa
+        JOIN workspace_resources wr ON wr.id = wa.resource_id
+        JOIN workspace_builds wb ON wb.job_id = wr.job_id
+        WHERE wb.workspace_id = $1
+          AND wb.id <> $2
+          AND wa.deleted = FALSE
+    )
+    RETURNING id
+), purged_context_resources AS (
+    DELETE FROM workspace_agent_context_resources
+    WHERE workspace_agent_id IN (SELECT id FROM soft_deleted_agents)
 )
-UPDATE
-	workspace_agent_metadata wam
-SET
-	value = m.value,
-	error = m.error,
-	collected_at = m.collected_at
-FROM
-	metadata m
-WHERE
-	wam.workspace_agent_id = m.workspace_agent_id
-	AND wam.key = m.key
-`
-
-type BatchUpdateWorkspaceAgentMetadataParams struct {
-	WorkspaceAgentID []uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
-	Key              []string    `db:"key" json:"key"`
-	Value            []string    `db:"value" json:"value"`
-	Error            []string    `db:"error" json:"error"`
-	CollectedAt      []time.Time `db:"collected_at" json:"collected_at"`
-}
-
-func (q *sqlQuerier) BatchUpdateWorkspaceAgentMetadata(ctx context.Context, arg BatchUpdateWorkspaceAgentMetadataParams) error {
-	_, err := q.db.ExecContext(ctx, batchUpdateWorkspaceAgentMetadata,
-		pq.Array(arg.WorkspaceAgentID),
-		pq.Array(arg.Key),
-		pq.Array(arg.Value),
-		pq.Array(arg.Error),
-		pq.Array(arg.CollectedAt),
-	)
-	return err
-}
-
-const deleteOldWorkspaceAgentLogs = `-- name: DeleteOldWorkspaceAgentLogs :execrows
-WITH
-	latest_builds AS (
-		SELECT
-			workspace_id, max(build_number) AS max_build_number
-		FROM
-			workspace_builds
-		GROUP BY
-			workspace_id
-	),
-	old_agents AS (
-		SELECT
-			wa.id
-		FROM
-			workspace_agents AS wa
-		JOIN
-			workspace_resources AS wr
-		ON
-			wa.resource_id = wr.id
-		JOIN
-			workspace_builds AS wb
-		ON
-			wb.job_id = wr.job_id
-		LEFT JOIN
-			latest_builds
-		ON
-			latest_builds.workspace_id = wb.workspace_id
-		AND
-			latest_builds.max_build_number = wb.build_number
-		WHERE
-			-- Filter out the latest builds for each workspace.
-			latest_builds.workspace_id IS NULL
-		AND CASE
-			-- If the last time the agent connected was before @threshold
-			WHEN wa.last_connected_at IS NOT NULL THEN
-				 wa.last_connected_at < $1 :: timestamptz
-			-- The agent never connected, and was created before @threshold
-			ELSE wa.created_at < $1 :: timestamptz
-		END
-	)
-DELETE FROM workspace_agent_logs WHERE agent_id IN (SELECT id FROM old_agents)
+DELETE FROM workspace_agent_context_snapshots
+WHERE workspace_agent_id IN (SELECT id FROM soft_deleted_agents)
 `
 
-// If an agent hasn't connected within the retention period, we purge its logs.
-// Exception: if the logs are related to the latest build, we keep those around.
-// Logs can take up a lot of space, so it's important we clean up frequently.
-func (q *sqlQuerier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentLogs, threshold)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
+type SoftDeletePriorWorkspaceAgentsParams struct {
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	CurrentBuildID uuid.UUID `db:"current_build_id" json:"current_build_id"`
 }
 
-const deleteWorkspaceSubAgentByID = `-- name: DeleteWorkspaceSubAgentByID :exec
+// Marks agents from all prior builds of this workspace as deleted,
+// preserving only agents belonging to @current_build_id. Called from
+// provisionerdserver when a workspace build completes, after the new
+// build's agents have been inserted, so running agents are not
+// deleted while a build is still queued or provisioning.
+//
+// Agent context rows (workspace_agent_context_snapshots and
+// workspace_agent_context_resources) only describe live agents, and
+// agents are never un-deleted, so they are hard-deleted here instead
+// of accumulating alongside the soft-deleted agent rows.
+func (q *sqlQuerier) SoftDeletePriorWorkspaceAgents(ctx context.Context, arg SoftDeletePriorWorkspaceAgentsParams) error {
+	_, err := q.db.ExecContext(ctx, softDeletePriorWorkspaceAgents, arg.WorkspaceID, arg.CurrentBuildID)
+	return err
+}
+
+const softDeleteWorkspaceAgentsByWorkspaceID = `-- name: SoftDeleteWorkspaceAgentsByWorkspaceID :exec
 WITH soft_deleted_agents AS (
     UPDATE workspace_agents
     SET deleted = TRUE
-    WHERE id = $1
-        AND parent_id IS NOT NULL
-        AND deleted = FALSE
+    WHERE id IN (
+        SELECT wa.id
+        FROM workspace_agents wa
+        JOIN workspace_resources wr ON wr.id = wa.resource_id
+        JOIN workspace_builds wb ON wb.job_id = wr.job_id
+        WHERE wb.workspace_id = $1
+          AND wa.deleted = FALSE
+    )
     RETURNING id
 ), purged_context_resources AS (
     DELETE FROM workspace_agent_context_resources
@@ -32685,464 +40148,260 @@ DELETE FROM workspace_agent_context_snapshots
 WHERE workspace_agent_id IN (SELECT id FROM soft_deleted_agents)
 `
 
-// Soft-deletes a single sub-agent (a child agent such as a devcontainer
-// agent). Called from the DeleteSubAgent RPC when a sub-agent is torn
-// down, which can happen mid-build without a full workspace rebuild.
+// Marks every non-deleted agent belonging to the given workspace as
+// deleted. Called alongside UpdateWorkspaceDeletedByID when a workspace
+// itself is soft-deleted, so the agent instance-identity auth path
+// (which filters on workspace_agents.deleted) doesn't keep seeing
+// orphaned rows.
 //
 // Agent context rows are hard-deleted for the same reason as in
-// SoftDeletePriorWorkspaceAgents: they only describe live agents, the
-// rebuild-time soft-delete queries skip already-deleted agents, and
-// agents are never hard-deleted, so the rows would otherwise orphan
-// forever.
-func (q *sqlQuerier) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteWorkspaceSubAgentByID, id)
+// SoftDeletePriorWorkspaceAgents.
+func (q *sqlQuerier) SoftDeleteWorkspaceAgentsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, softDeleteWorkspaceAgentsByWorkspaceID, workspaceID)
 	return err
 }
 
-const getAuthenticatedWorkspaceAgentAndBuildByAuthToken = `-- name: GetAuthenticatedWorkspaceAgentAndBuildByAuthToken :one
-SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl,
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted,
-	workspace_build_with_user.id, workspace_build_with_user.created_at, workspace_build_with_user.updated_at, workspace_build_with_user.workspace_id, workspace_build_with_user.template_version_id, workspace_build_with_user.build_number, workspace_build_with_user.transition, workspace_build_with_user.initiator_id, workspace_build_with_user.job_id, workspace_build_with_user.deadline, workspace_build_with_user.reason, workspace_build_with_user.daily_cost, workspace_build_with_user.max_deadline, workspace_build_with_user.template_version_preset_id, workspace_build_with_user.has_ai_task, workspace_build_with_user.has_external_agent, workspace_build_with_user.notified_autostop_deadline, workspace_build_with_user.initiator_by_avatar_url, workspace_build_with_user.initiator_by_username, workspace_build_with_user.initiator_by_name,
-	tasks.id AS task_id
-FROM
+const updateWorkspaceAgentClockSkewByID = `-- name: UpdateWorkspaceAgentClockSkewByID :exec
+UPDATE
 	workspace_agents
-JOIN
-	workspace_resources
-ON
-	workspace_agents.resource_id = workspace_resources.id
-JOIN
-	workspace_build_with_user
-ON
-	workspace_resources.job_id = workspace_build_with_user.job_id
-JOIN
-	workspaces
-ON
-	workspace_build_with_user.workspace_id = workspaces.id
-LEFT JOIN
-	tasks
-ON
-	tasks.workspace_id = workspaces.id
+SET
+	clock_skew_ms = $2
 WHERE
-	-- This should only match 1 agent, so 1 returned row or 0.
-	workspace_agents.auth_token = $1::uuid
-	AND workspaces.deleted = FALSE
-	-- Filter out deleted sub agents.
-	AND workspace_agents.deleted = FALSE
-	-- Filter out builds that are not the latest, with exception for shutdown case.
-	-- Use CASE for short-circuiting: check normal case first (most common), then shutdown case.
-	AND CASE
-		-- Normal case: Agent's build is the latest build.
-		WHEN workspace_build_with_user.build_number = (
-			SELECT
-				MAX(build_number)
-			FROM
-				workspace_builds
-			WHERE
-				workspace_id = workspace_build_with_user.workspace_id
-		) THEN TRUE
-		-- Shutdown case: Agent from previous START build during STOP build execution.
-		WHEN workspace_build_with_user.transition = 'start'
-			-- Agent's START build job succeeded.
-			AND (SELECT job_status FROM provisioner_jobs WHERE id = workspace_build_with_user.job_id) = 'succeeded'
-			-- Latest build is a STOP build whose job is still active,
-			-- and agent's build is immediately previous.
-			AND EXISTS (
-				SELECT 1
-				FROM workspace_builds latest
-				JOIN provisioner_jobs pj ON pj.id = latest.job_id
-				WHERE latest.workspace_id = workspace_build_with_user.workspace_id
-				AND latest.build_number = workspace_build_with_user.build_number + 1
-				AND latest.build_number = (
-					SELECT MAX(build_number)
-					FROM workspace_builds l2
-					WHERE l2.workspace_id = latest.workspace_id
-				)
-				AND latest.transition = 'stop'
-				AND pj.job_status IN ('pending', 'running')
-			) THEN TRUE
-		ELSE FALSE
-	END
+	id = $1
 `
 
-type GetAuthenticatedWorkspaceAgentAndBuildByAuthTokenRow struct {
-	WorkspaceTable WorkspaceTable `db:"workspace_table" json:"workspace_table"`
-	WorkspaceAgent WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
-	WorkspaceBuild WorkspaceBuild `db:"workspace_build" json:"workspace_build"`
-	TaskID         uuid.NullUUID  `db:"task_id" json:"task_id"`
+type UpdateWorkspaceAgentClockSkewByIDParams struct {
+	ID          uuid.UUID     `db:"id" json:"id"`
+	ClockSkewMs sql.NullInt64 `db:"clock_skew_ms" json:"clock_skew_ms"`
 }
 
-// GetAuthenticatedWorkspaceAgentAndBuildByAuthToken returns an authenticated
-// workspace agent and its associated build. During normal operation, this is
-// the latest build. During shutdown, this may be the previous START build while
-// the STOP build is executing, allowing shutdown scripts to authenticate (see
-// issue #19467).
-func (q *sqlQuerier) GetAuthenticatedWorkspaceAgentAndBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (GetAuthenticatedWorkspaceAgentAndBuildByAuthTokenRow, error) {
-	row := q.db.QueryRowContext(ctx, getAuthenticatedWorkspaceAgentAndBuildByAuthToken, authToken)
-	var i GetAuthenticatedWorkspaceAgentAndBuildByAuthTokenRow
-	err := row.Scan(
-		&i.WorkspaceTable.ID,
-		&i.WorkspaceTable.CreatedAt,
-		&i.WorkspaceTable.UpdatedAt,
-		&i.WorkspaceTable.OwnerID,
-		&i.WorkspaceTable.OrganizationID,
-		&i.WorkspaceTable.TemplateID,
-		&i.WorkspaceTable.Deleted,
-		&i.WorkspaceTable.Name,
-		&i.WorkspaceTable.AutostartSchedule,
-		&i.WorkspaceTable.Ttl,
-		&i.WorkspaceTable.LastUsedAt,
-		&i.WorkspaceTable.DormantAt,
-		&i.WorkspaceTable.DeletingAt,
-		&i.WorkspaceTable.AutomaticUpdates,
-		&i.WorkspaceTable.Favorite,
-		&i.WorkspaceTable.NextStartAt,
-		&i.WorkspaceTable.GroupACL,
-		&i.WorkspaceTable.UserACL,
-		&i.WorkspaceAgent.ID,
-		&i.WorkspaceAgent.CreatedAt,
-		&i.WorkspaceAgent.UpdatedAt,
-		&i.WorkspaceAgent.Name,
-		&i.WorkspaceAgent.FirstConnectedAt,
-		&i.WorkspaceAgent.LastConnectedAt,
-		&i.WorkspaceAgent.DisconnectedAt,
-		&i.WorkspaceAgent.ResourceID,
-		&i.WorkspaceAgent.AuthToken,
-		&i.WorkspaceAgent.AuthInstanceID,
-		&i.WorkspaceAgent.Architecture,
-		&i.WorkspaceAgent.EnvironmentVariables,
-		&i.WorkspaceAgent.OperatingSystem,
-		&i.WorkspaceAgent.InstanceMetadata,
-		&i.WorkspaceAgent.ResourceMetadata,
-		&i.WorkspaceAgent.Directory,
-		&i.WorkspaceAgent.Version,
-		&i.WorkspaceAgent.LastConnectedReplicaID,
-		&i.WorkspaceAgent.ConnectionTimeoutSeconds,
-		&i.WorkspaceAgent.TroubleshootingURL,
-		&i.WorkspaceAgent.MOTDFile,
-		&i.WorkspaceAgent.LifecycleState,
-		&i.WorkspaceAgent.ExpandedDirectory,
-		&i.WorkspaceAgent.LogsLength,
-		&i.WorkspaceAgent.LogsOverflowed,
-		&i.WorkspaceAgent.StartedAt,
-		&i.WorkspaceAgent.ReadyAt,
-		pq.Array(&i.WorkspaceAgent.Subsystems),
-		pq.Array(&i.WorkspaceAgent.DisplayApps),
-		&i.WorkspaceAgent.APIVersion,
-		&i.WorkspaceAgent.DisplayOrder,
-		&i.WorkspaceAgent.ParentID,
-		&i.WorkspaceAgent.APIKeyScope,
-		&i.WorkspaceAgent.Deleted,
-		&i.WorkspaceBuild.ID,
-		&i.WorkspaceBuild.CreatedAt,
-		&i.WorkspaceBuild.UpdatedAt,
-		&i.WorkspaceBuild.WorkspaceID,
-		&i.WorkspaceBuild.TemplateVersionID,
-		&i.WorkspaceBuild.BuildNumber,
-		&i.WorkspaceBuild.Transition,
-		&i.WorkspaceBuild.InitiatorID,
-		&i.WorkspaceBuild.JobID,
-		&i.WorkspaceBuild.Deadline,
-		&i.WorkspaceBuild.Reason,
-		&i.WorkspaceBuild.DailyCost,
-		&i.WorkspaceBuild.MaxDeadline,
-		&i.WorkspaceBuild.TemplateVersionPresetID,
-		&i.WorkspaceBuild.HasAITask,
-		&i.WorkspaceBuild.HasExternalAgent,
-		&i.WorkspaceBuild.NotifiedAutostopDeadline,
-		&i.WorkspaceBuild.InitiatorByAvatarUrl,
-		&i.WorkspaceBuild.InitiatorByUsername,
-		&i.WorkspaceBuild.InitiatorByName,
-		&i.TaskID,
-	)
-	return i, err
+func (q *sqlQuerier) UpdateWorkspaceAgentClockSkewByID(ctx context.Context, arg UpdateWorkspaceAgentClockSkewByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentClockSkewByID, arg.ID, arg.ClockSkewMs)
+	return err
 }
 
-const getExternalAgentTokensByTemplateID = `-- name: GetExternalAgentTokensByTemplateID :many
-SELECT
-	workspaces.id               AS workspace_id,
-	workspaces.name             AS workspace_name,
-	workspace_agents.id         AS agent_id,
-	workspace_agents.name       AS agent_name,
-	workspace_agents.auth_token AS agent_token
-FROM
-	workspaces
-JOIN (
-	-- latest build per workspace
-	SELECT DISTINCT ON (workspace_id)
-		id, workspace_id, job_id, transition, has_external_agent
-	FROM
-		workspace_builds
-	ORDER BY
-		workspace_id, build_number DESC
-) AS latest_builds
-ON
-	latest_builds.workspace_id = workspaces.id
-JOIN
-	provisioner_jobs
-ON
-	provisioner_jobs.id = latest_builds.job_id
-JOIN
-	workspace_resources
-ON
-	workspace_resources.job_id = latest_builds.job_id
-JOIN
+const updateWorkspaceAgentConnectionByID = `-- name: UpdateWorkspaceAgentConnectionByID :exec
+UPDATE
 	workspace_agents
-ON
-	workspace_agents.resource_id = workspace_resources.id
+SET
+	first_connected_at = $2,
+	last_connected_at = $3,
+	last_connected_replica_id = $4,
+	disconnected_at = $5,
+	updated_at = $6
 WHERE
-	workspaces.template_id = $1
-	AND (
-		$2 :: uuid = '00000000-0000-0000-0000-000000000000' :: uuid
-		OR workspaces.owner_id = $2
+	id = $1
+`
+
+type UpdateWorkspaceAgentConnectionByIDParams struct {
+	ID                     uuid.UUID     `db:"id" json:"id"`
+	FirstConnectedAt       sql.NullTime  `db:"first_connected_at" json:"first_connected_at"`
+	LastConnectedAt        sql.NullTime  `db:"last_connected_at" json:"last_connected_at"`
+	LastConnectedReplicaID uuid.NullUUID `db:"last_connected_replica_id" json:"last_connected_replica_id"`
+	DisconnectedAt         sql.NullTime  `db:"disconnected_at" json:"disconnected_at"`
+	UpdatedAt              time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentConnectionByID,
+		arg.ID,
+		arg.FirstConnectedAt,
+		arg.LastConnectedAt,
+		arg.LastConnectedReplicaID,
+		arg.DisconnectedAt,
+		arg.UpdatedAt,
 	)
-	AND workspaces.deleted = FALSE
-	AND latest_builds.has_external_agent = TRUE
-	AND latest_builds.transition = 'start' :: workspace_transition
-	AND provisioner_jobs.job_status = 'succeeded' :: provisioner_job_status
-	AND workspace_agents.deleted = FALSE
-	AND workspace_agents.auth_instance_id IS NULL
+	return err
+}
+
+const updateWorkspaceAgentDirectoryByID = `-- name: UpdateWorkspaceAgentDirectoryByID :exec
+UPDATE
+	workspace_agents
+SET
+	directory = $2, updated_at = $3
+WHERE
+	id = $1
 `
 
-type GetExternalAgentTokensByTemplateIDParams struct {
-	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
-	OwnerID    uuid.UUID `db:"owner_id" json:"owner_id"`
+type UpdateWorkspaceAgentDirectoryByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Directory string    `db:"directory" json:"directory"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
-type GetExternalAgentTokensByTemplateIDRow struct {
-	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	WorkspaceName string    `db:"workspace_name" json:"workspace_name"`
-	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
-	AgentName     string    `db:"agent_name" json:"agent_name"`
-	AgentToken    uuid.UUID `db:"agent_token" json:"agent_token"`
+func (q *sqlQuerier) UpdateWorkspaceAgentDirectoryByID(ctx context.Context, arg UpdateWorkspaceAgentDirectoryByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentDirectoryByID, arg.ID, arg.Directory, arg.UpdatedAt)
+	return err
 }
 
-// GetExternalAgentTokensByTemplateID returns the auth tokens for all
-// non-deleted external agents on the latest build of every running workspace
-// of the given template. "Running" means the latest build has
-// transition=start and job_status=succeeded (matches the workspace-status
-// definition used by coderd/database/queries/workspaces.sql).
-// An owner_id of '00000000-0000-0000-0000-000000000000' (uuid.Nil) means
-// "all owners"; any other value restricts results to workspaces owned by
-// that user.
-func (q *sqlQuerier) GetExternalAgentTokensByTemplateID(ctx context.Context, arg GetExternalAgentTokensByTemplateIDParams) ([]GetExternalAgentTokensByTemplateIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, getExternalAgentTokensByTemplateID, arg.TemplateID, arg.OwnerID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetExternalAgentTokensByTemplateIDRow
-	for rows.Next() {
-		var i GetExternalAgentTokensByTemplateIDRow
-		if err := rows.Scan(
-			&i.WorkspaceID,
-			&i.WorkspaceName,
-			&i.AgentID,
-			&i.AgentName,
-			&i.AgentToken,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+const updateWorkspaceAgentDisplayAppsByID = `-- name: UpdateWorkspaceAgentDisplayAppsByID :exec
+UPDATE
+	workspace_agents
+SET
+	display_apps = $2, updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentDisplayAppsByIDParams struct {
+	ID          uuid.UUID    `db:"id" json:"id"`
+	DisplayApps []DisplayApp `db:"display_apps" json:"display_apps"`
+	UpdatedAt   time.Time    `db:"updated_at" json:"updated_at"`
 }
 
-const getWorkspaceAgentAndWorkspaceByID = `-- name: GetWorkspaceAgentAndWorkspaceByID :one
-SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted,
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl,
-	users.username as owner_username
-FROM
+func (q *sqlQuerier) UpdateWorkspaceAgentDisplayAppsByID(ctx context.Context, arg UpdateWorkspaceAgentDisplayAppsByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentDisplayAppsByID, arg.ID, pq.Array(arg.DisplayApps), arg.UpdatedAt)
+	return err
+}
+
+const updateWorkspaceAgentLifecycleStateByID = `-- name: UpdateWorkspaceAgentLifecycleStateByID :exec
+UPDATE
 	workspace_agents
-JOIN
-	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
-JOIN
-	provisioner_jobs ON workspace_resources.job_id = provisioner_jobs.id
-JOIN
-	workspace_builds ON provisioner_jobs.id = workspace_builds.job_id
-JOIN
-	workspaces ON workspace_builds.workspace_id = workspaces.id
-JOIN
-	users ON workspaces.owner_id = users.id
+SET
+	lifecycle_state = $2,
+	started_at = $3,
+	ready_at = $4
 WHERE
-	workspace_agents.id = $1
-	AND workspace_agents.deleted = FALSE
-	AND provisioner_jobs.type = 'workspace_build'::provisioner_job_type
-	AND workspaces.deleted = FALSE
-	AND users.deleted = FALSE
-LIMIT 1
+	id = $1
 `
 
-type GetWorkspaceAgentAndWorkspaceByIDRow struct {
-	WorkspaceAgent WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
-	WorkspaceTable WorkspaceTable `db:"workspace_table" json:"workspace_table"`
-	OwnerUsername  string         `db:"owner_username" json:"owner_username"`
+type UpdateWorkspaceAgentLifecycleStateByIDParams struct {
+	ID             uuid.UUID                    `db:"id" json:"id"`
+	LifecycleState WorkspaceAgentLifecycleState `db:"lifecycle_state" json:"lifecycle_state"`
+	StartedAt      sql.NullTime                 `db:"started_at" json:"started_at"`
+	ReadyAt        sql.NullTime                 `db:"ready_at" json:"ready_at"`
 }
 
-func (q *sqlQuerier) GetWorkspaceAgentAndWorkspaceByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentAndWorkspaceByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceAgentAndWorkspaceByID, id)
-	var i GetWorkspaceAgentAndWorkspaceByIDRow
-	err := row.Scan(
-		&i.WorkspaceAgent.ID,
-		&i.WorkspaceAgent.CreatedAt,
-		&i.WorkspaceAgent.UpdatedAt,
-		&i.WorkspaceAgent.Name,
-		&i.WorkspaceAgent.FirstConnectedAt,
-		&i.WorkspaceAgent.LastConnectedAt,
-		&i.WorkspaceAgent.DisconnectedAt,
-		&i.WorkspaceAgent.ResourceID,
-		&i.WorkspaceAgent.AuthToken,
-		&i.WorkspaceAgent.AuthInstanceID,
-		&i.WorkspaceAgent.Architecture,
-		&i.WorkspaceAgent.EnvironmentVariables,
-		&i.WorkspaceAgent.OperatingSystem,
-		&i.WorkspaceAgent.InstanceMetadata,
-		&i.WorkspaceAgent.ResourceMetadata,
-		&i.WorkspaceAgent.Directory,
-		&i.WorkspaceAgent.Version,
-		&i.WorkspaceAgent.LastConnectedReplicaID,
-		&i.WorkspaceAgent.ConnectionTimeoutSeconds,
-		&i.WorkspaceAgent.TroubleshootingURL,
-		&i.WorkspaceAgent.MOTDFile,
-		&i.WorkspaceAgent.LifecycleState,
-		&i.WorkspaceAgent.ExpandedDirectory,
-		&i.WorkspaceAgent.LogsLength,
-		&i.WorkspaceAgent.LogsOverflowed,
-		&i.WorkspaceAgent.StartedAt,
-		&i.WorkspaceAgent.ReadyAt,
-		pq.Array(&i.WorkspaceAgent.Subsystems),
-		pq.Array(&i.WorkspaceAgent.DisplayApps),
-		&i.WorkspaceAgent.APIVersion,
-		&i.WorkspaceAgent.DisplayOrder,
-		&i.WorkspaceAgent.ParentID,
-		&i.WorkspaceAgent.APIKeyScope,
-		&i.WorkspaceAgent.Deleted,
-		&i.WorkspaceTable.ID,
-		&i.WorkspaceTable.CreatedAt,
-		&i.WorkspaceTable.UpdatedAt,
-		&i.WorkspaceTable.OwnerID,
-		&i.WorkspaceTable.OrganizationID,
-		&i.WorkspaceTable.TemplateID,
-		&i.WorkspaceTable.Deleted,
-		&i.WorkspaceTable.Name,
-		&i.WorkspaceTable.AutostartSchedule,
-		&i.WorkspaceTable.Ttl,
-		&i.WorkspaceTable.LastUsedAt,
-		&i.WorkspaceTable.DormantAt,
-		&i.WorkspaceTable.DeletingAt,
-		&i.WorkspaceTable.AutomaticUpdates,
-		&i.WorkspaceTable.Favorite,
-		&i.WorkspaceTable.NextStartAt,
-		&i.WorkspaceTable.GroupACL,
-		&i.WorkspaceTable.UserACL,
-		&i.OwnerUsername,
+func (q *sqlQuerier) UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentLifecycleStateByID,
+		arg.ID,
+		arg.LifecycleState,
+		arg.StartedAt,
+		arg.ReadyAt,
 	)
-	return i, err
+	return err
 }
 
-const getWorkspaceAgentByID = `-- name: GetWorkspaceAgentByID :one
-SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
-FROM
+const updateWorkspaceAgentLogOverflowByID = `-- name: UpdateWorkspaceAgentLogOverflowByID :exec
+UPDATE
 	workspace_agents
+SET
+	logs_overflowed = $2
 WHERE
 	id = $1
-	-- Filter out deleted sub agents.
-	AND deleted = FALSE
 `
 
-func (q *sqlQuerier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceAgentByID, id)
-	var i WorkspaceAgent
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.FirstConnectedAt,
-		&i.LastConnectedAt,
-		&i.DisconnectedAt,
-		&i.ResourceID,
-		&i.AuthToken,
-		&i.AuthInstanceID,
-		&i.Architecture,
-		&i.EnvironmentVariables,
-		&i.OperatingSystem,
-		&i.InstanceMetadata,
-		&i.ResourceMetadata,
-		&i.Directory,
-		&i.Version,
-		&i.LastConnectedReplicaID,
-		&i.ConnectionTimeoutSeconds,
-		&i.TroubleshootingURL,
-		&i.MOTDFile,
-		&i.LifecycleState,
-		&i.ExpandedDirectory,
-		&i.LogsLength,
-		&i.LogsOverflowed,
-		&i.StartedAt,
-		&i.ReadyAt,
-		pq.Array(&i.Subsystems),
-		pq.Array(&i.DisplayApps),
-		&i.APIVersion,
-		&i.DisplayOrder,
-		&i.ParentID,
-		&i.APIKeyScope,
-		&i.Deleted,
+type UpdateWorkspaceAgentLogOverflowByIDParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	LogsOverflowed bool      `db:"logs_overflowed" json:"logs_overflowed"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, arg UpdateWorkspaceAgentLogOverflowByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentLogOverflowByID, arg.ID, arg.LogsOverflowed)
+	return err
+}
+
+const updateWorkspaceAgentMetadata = `-- name: UpdateWorkspaceAgentMetadata :exec
+WITH metadata AS (
+	SELECT
+		unnest($2::text[]) AS key,
+		unnest($3::text[]) AS value,
+		unnest($4::text[]) AS error,
+		unnest($5::timestamptz[]) AS collected_at
+)
+UPDATE
+	workspace_agent_metadata wam
+SET
+	value = m.value,
+	error = m.error,
+	collected_at = m.collected_at
+FROM
+	metadata m
+WHERE
+	wam.workspace_agent_id = $1
+	AND wam.key = m.key
+`
+
+type UpdateWorkspaceAgentMetadataParams struct {
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              []string    `db:"key" json:"key"`
+	Value            []string    `db:"value" json:"value"`
+	Error            []string    `db:"error" json:"error"`
+	CollectedAt      []time.Time `db:"collected_at" json:"collected_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg UpdateWorkspaceAgentMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentMetadata,
+		arg.WorkspaceAgentID,
+		pq.Array(arg.Key),
+		pq.Array(arg.Value),
+		pq.Array(arg.Error),
+		pq.Array(arg.CollectedAt),
 	)
-	return i, err
+	return err
 }
 
-const getWorkspaceAgentLifecycleStateByID = `-- name: GetWorkspaceAgentLifecycleStateByID :one
-SELECT
-	lifecycle_state,
-	started_at,
-	ready_at
-FROM
+const updateWorkspaceAgentStartupByID = `-- name: UpdateWorkspaceAgentStartupByID :exec
+UPDATE
 	workspace_agents
+SET
+	version = $2,
+	expanded_directory = $3,
+	subsystems = $4,
+	api_version = $5
 WHERE
 	id = $1
 `
 
-type GetWorkspaceAgentLifecycleStateByIDRow struct {
-	LifecycleState WorkspaceAgentLifecycleState `db:"lifecycle_state" json:"lifecycle_state"`
-	StartedAt      sql.NullTime                 `db:"started_at" json:"started_at"`
-	ReadyAt        sql.NullTime                 `db:"ready_at" json:"ready_at"`
+type UpdateWorkspaceAgentStartupByIDParams struct {
+	ID                uuid.UUID                 `db:"id" json:"id"`
+	Version           string                    `db:"version" json:"version"`
+	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
+	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
+	APIVersion        string                    `db:"api_version" json:"api_version"`
 }
 
-func (q *sqlQuerier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentLifecycleStateByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceAgentLifecycleStateByID, id)
-	var i GetWorkspaceAgentLifecycleStateByIDRow
-	err := row.Scan(&i.LifecycleState, &i.StartedAt, &i.ReadyAt)
-	return i, err
+func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
+		arg.ID,
+		arg.Version,
+		arg.ExpandedDirectory,
+		pq.Array(arg.Subsystems),
+		arg.APIVersion,
+	)
+	return err
 }
 
-const getWorkspaceAgentLogSourcesByAgentIDs = `-- name: GetWorkspaceAgentLogSourcesByAgentIDs :many
-SELECT workspace_agent_id, id, created_at, display_name, icon FROM workspace_agent_log_sources WHERE workspace_agent_id = ANY($1 :: uuid [ ])
+const getLatestWorkspaceAgentServiceTransitionsByAgentIDs = `-- name: GetLatestWorkspaceAgentServiceTransitionsByAgentIDs :many
+SELECT DISTINCT ON (workspace_agent_id, service_name)
+	id, workspace_agent_id, service_name, created_at, status, message, exit_code, restart_count
+FROM
+	workspace_agent_service_transitions
+WHERE
+	workspace_agent_id = ANY($1 :: uuid [ ])
+ORDER BY
+	workspace_agent_id, service_name, created_at DESC, id DESC
 `
 
-func (q *sqlQuerier) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentLogSource, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentLogSourcesByAgentIDs, pq.Array(ids))
+// Returns the current status of each service of the given agents.
+func (q *sqlQuerier) GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentServiceTransition, error) {
+	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceAgentServiceTransitionsByAgentIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentLogSource
+	var items []WorkspaceAgentServiceTransition
 	for rows.Next() {
-		var i WorkspaceAgentLogSource
+		var i WorkspaceAgentServiceTransition
 		if err := rows.Scan(
-			&i.WorkspaceAgentID,
 			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.ServiceName,
 			&i.CreatedAt,
-			&i.DisplayName,
-			&i.Icon,
+			&i.Status,
+			&i.Message,
+			&i.ExitCode,
+			&i.RestartCount,
 		); err != nil {
 			return nil, err
 		}
@@ -33157,39 +40416,37 @@ func (q *sqlQuerier) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context,
 	return items, nil
 }
 
-const getWorkspaceAgentLogsAfter = `-- name: GetWorkspaceAgentLogsAfter :many
+const getWorkspaceAgentServiceTransitionsByAgentIDs = `-- name: GetWorkspaceAgentServiceTransitionsByAgentIDs :many
 SELECT
-	agent_id, created_at, output, id, level, log_source_id
+	id, workspace_agent_id, service_name, created_at, status, message, exit_code, restart_count
 FROM
-	workspace_agent_logs
+	workspace_agent_service_transitions
 WHERE
-	agent_id = $1
-	AND (
-		id > $2
-	) ORDER BY id ASC
+	workspace_agent_id = ANY($1 :: uuid [ ])
+ORDER BY
+	created_at ASC, id ASC
 `
 
-type GetWorkspaceAgentLogsAfterParams struct {
-	AgentID      uuid.UUID `db:"agent_id" json:"agent_id"`
-	CreatedAfter int64     `db:"created_after" json:"created_after"`
-}
-
-func (q *sqlQuerier) GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWorkspaceAgentLogsAfterParams) ([]WorkspaceAgentLog, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentLogsAfter, arg.AgentID, arg.CreatedAfter)
+// Returns every status transition of the services of the given agents,
+// oldest first.
+func (q *sqlQuerier) GetWorkspaceAgentServiceTransitionsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentServiceTransition, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentServiceTransitionsByAgentIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentLog
+	var items []WorkspaceAgentServiceTransition
 	for rows.Next() {
-		var i WorkspaceAgentLog
+		var i WorkspaceAgentServiceTransition
 		if err := rows.Scan(
-			&i.AgentID,
-			&i.CreatedAt,
-			&i.Output,
 			&i.ID,
-			&i.Level,
-			&i.LogSourceID,
+			&i.WorkspaceAgentID,
+			&i.ServiceName,
+			&i.CreatedAt,
+			&i.Status,
+			&i.Message,
+			&i.ExitCode,
+			&i.RestartCount,
 		); err != nil {
 			return nil, err
 		}
@@ -33204,41 +40461,145 @@ func (q *sqlQuerier) GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWork
 	return items, nil
 }
 
-const getWorkspaceAgentMetadata = `-- name: GetWorkspaceAgentMetadata :many
+const getWorkspaceAgentServicesByAgentIDs = `-- name: GetWorkspaceAgentServicesByAgentIDs :many
 SELECT
-	workspace_agent_id, display_name, key, script, value, error, timeout, interval, collected_at, display_order
+	workspace_agent_id, name, display_name, command, restart_policy, created_at
 FROM
-	workspace_agent_metadata
+	workspace_agent_services
 WHERE
-	workspace_agent_id = $1
-	AND CASE WHEN COALESCE(array_length($2::text[], 1), 0) > 0 THEN key = ANY($2::text[]) ELSE TRUE END
+	workspace_agent_id = ANY($1 :: uuid [ ])
+ORDER BY
+	workspace_agent_id, name
 `
 
-type GetWorkspaceAgentMetadataParams struct {
-	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
-	Keys             []string  `db:"keys" json:"keys"`
+func (q *sqlQuerier) GetWorkspaceAgentServicesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentService, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentServicesByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentService
+	for rows.Next() {
+		var i WorkspaceAgentService
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.Name,
+			&i.DisplayName,
+			&i.Command,
+			&i.RestartPolicy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorkspaceAgentMetadataParams) ([]WorkspaceAgentMetadatum, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentMetadata, arg.WorkspaceAgentID, pq.Array(arg.Keys))
+const insertWorkspaceAgentServiceTransition = `-- name: InsertWorkspaceAgentServiceTransition :one
+INSERT INTO
+	workspace_agent_service_transitions (
+		id,
+		workspace_agent_id,
+		service_name,
+		created_at,
+		status,
+		message,
+		exit_code,
+		restart_count
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, workspace_agent_id, service_name, created_at, status, message, exit_code, restart_count
+`
+
+type InsertWorkspaceAgentServiceTransitionParams struct {
+	ID               uuid.UUID                   `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID                   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	ServiceName      string                      `db:"service_name" json:"service_name"`
+	CreatedAt        time.Time                   `db:"created_at" json:"created_at"`
+	Status           WorkspaceAgentServiceStatus `db:"status" json:"status"`
+	Message          string                      `db:"message" json:"message"`
+	ExitCode         sql.NullInt32               `db:"exit_code" json:"exit_code"`
+	RestartCount     int32                       `db:"restart_count" json:"restart_count"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentServiceTransition(ctx context.Context, arg InsertWorkspaceAgentServiceTransitionParams) (WorkspaceAgentServiceTransition, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentServiceTransition,
+		arg.ID,
+		arg.WorkspaceAgentID,
+		arg.ServiceName,
+		arg.CreatedAt,
+		arg.Status,
+		arg.Message,
+		arg.ExitCode,
+		arg.RestartCount,
+	)
+	var i WorkspaceAgentServiceTransition
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceAgentID,
+		&i.ServiceName,
+		&i.CreatedAt,
+		&i.Status,
+		&i.Message,
+		&i.ExitCode,
+		&i.RestartCount,
+	)
+	return i, err
+}
+
+const insertWorkspaceAgentServices = `-- name: InsertWorkspaceAgentServices :many
+INSERT INTO
+	workspace_agent_services (workspace_agent_id, name, display_name, command, restart_policy, created_at)
+SELECT
+	$1 :: uuid AS workspace_agent_id,
+	unnest($2 :: text [ ]) AS name,
+	unnest($3 :: text [ ]) AS display_name,
+	unnest($4 :: text [ ]) AS command,
+	unnest($5 :: workspace_agent_service_restart_policy [ ]) AS restart_policy,
+	$6 :: timestamptz AS created_at
+RETURNING workspace_agent_services.workspace_agent_id, workspace_agent_services.name, workspace_agent_services.display_name, workspace_agent_services.command, workspace_agent_services.restart_policy, workspace_agent_services.created_at
+`
+
+type InsertWorkspaceAgentServicesParams struct {
+	WorkspaceAgentID uuid.UUID                            `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Name             []string                             `db:"name" json:"name"`
+	DisplayName      []string                             `db:"display_name" json:"display_name"`
+	Command          []string                             `db:"command" json:"command"`
+	RestartPolicy    []WorkspaceAgentServiceRestartPolicy `db:"restart_policy" json:"restart_policy"`
+	CreatedAt        time.Time                            `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentServices(ctx context.Context, arg InsertWorkspaceAgentServicesParams) ([]WorkspaceAgentService, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentServices,
+		arg.WorkspaceAgentID,
+		pq.Array(arg.Name),
+		pq.Array(arg.DisplayName),
+		pq.Array(arg.Command),
+		pq.Array(arg.RestartPolicy),
+		arg.CreatedAt,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentMetadatum
+	var items []WorkspaceAgentService
 	for rows.Next() {
-		var i WorkspaceAgentMetadatum
+		var i WorkspaceAgentService
 		if err := rows.Scan(
 			&i.WorkspaceAgentID,
+			&i.Name,
 			&i.DisplayName,
-			&i.Key,
-			&i.Script,
-			&i.Value,
-			&i.Error,
-			&i.Timeout,
-			&i.Interval,
-			&i.CollectedAt,
-			&i.DisplayOrder,
+			&i.Command,
+			&i.RestartPolicy,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -33253,52 +40614,355 @@ func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorks
 	return items, nil
 }
 
-const getWorkspaceAgentScriptTimingsByBuildID = `-- name: GetWorkspaceAgentScriptTimingsByBuildID :many
+const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :exec
+DELETE FROM
+	workspace_agent_stats
+WHERE
+	created_at < (
+		SELECT
+			COALESCE(
+				-- When generating initial template usage stats, all the
+				-- raw agent stats are needed, after that only ~30 mins
+				-- from last rollup is needed. Deployment stats seem to
+				-- use between 15 mins and 1 hour of data. We keep a
+				-- little bit more (1 day) just in case.
+				MAX(start_time) - '1 days'::interval,
+				-- Fall back to ~6 months ago if there are no template
+				-- usage stats so that we don't delete the data before
+				-- it's rolled up.
+				NOW() - '180 days'::interval
+			)
+		FROM
+			template_usage_stats
+	)
+	AND created_at < (
+		-- Delete at most in batches of 4 hours (with this batch size, assuming
+		-- 1 iteration / 10 minutes, we can clear out the previous 6 months of
+		-- data in 7.5 days) whilst keeping the DB load low.
+		SELECT
+			COALESCE(MIN(created_at) + '4 hours'::interval, NOW())
+		FROM
+			workspace_agent_stats
+	)
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats)
+	return err
+}
+
+const getDeploymentWorkspaceAgentStats = `-- name: GetDeploymentWorkspaceAgentStats :one
+WITH stats AS (
+    SELECT
+        agent_id,
+        created_at,
+        rx_bytes,
+        tx_bytes,
+        connection_median_latency_ms,
+        session_count_vscode,
+        session_count_ssh,
+        session_count_jetbrains,
+        session_count_reconnecting_pty,
+        ROW_NUMBER() OVER (PARTITION BY agent_id ORDER BY created_at DESC) AS rn
+    FROM workspace_agent_stats
+    WHERE created_at > $1
+)
 SELECT
-	DISTINCT ON (workspace_agent_script_timings.script_id) workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at, workspace_agent_script_timings.ended_at, workspace_agent_script_timings.exit_code, workspace_agent_script_timings.stage, workspace_agent_script_timings.status,
-	workspace_agent_scripts.display_name,
-	workspace_agents.id as workspace_agent_id,
-	workspace_agents.name as workspace_agent_name
-FROM workspace_agent_script_timings
-INNER JOIN workspace_agent_scripts ON workspace_agent_scripts.id = workspace_agent_script_timings.script_id
-INNER JOIN workspace_agents ON workspace_agents.id = workspace_agent_scripts.workspace_agent_id
-INNER JOIN workspace_resources ON workspace_resources.id = workspace_agents.resource_id
-INNER JOIN workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
-WHERE workspace_builds.id = $1
-ORDER BY workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at
+    coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
+    coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
+	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+    coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms) FILTER (WHERE connection_median_latency_ms > 0)), -1)::FLOAT AS workspace_connection_latency_50,
+    coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms) FILTER (WHERE connection_median_latency_ms > 0)), -1)::FLOAT AS workspace_connection_latency_95,
+    coalesce(SUM(session_count_vscode) FILTER (WHERE rn = 1), 0)::bigint AS session_count_vscode,
+    coalesce(SUM(session_count_ssh) FILTER (WHERE rn = 1), 0)::bigint AS session_count_ssh,
+    coalesce(SUM(session_count_jetbrains) FILTER (WHERE rn = 1), 0)::bigint AS session_count_jetbrains,
+    coalesce(SUM(session_count_reconnecting_pty) FILTER (WHERE rn = 1), 0)::bigint AS session_count_reconnecting_pty
+FROM stats
+`
+
+type GetDeploymentWorkspaceAgentStatsRow struct {
+	WorkspaceRxBytes             int64   `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
+	WorkspaceTxBytes             int64   `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
+	WorkspaceConnectionLatency50 float64 `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64 `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	SessionCountVSCode           int64   `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountSSH              int64   `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountJetBrains        int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY  int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+}
+
+func (q *sqlQuerier) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getDeploymentWorkspaceAgentStats, createdAt)
+	var i GetDeploymentWorkspaceAgentStatsRow
+	err := row.Scan(
+		&i.WorkspaceRxBytes,
+		&i.WorkspaceTxBytes,
+		&i.WorkspaceConnectionLatency50,
+		&i.WorkspaceConnectionLatency95,
+		&i.SessionCountVSCode,
+		&i.SessionCountSSH,
+		&i.SessionCountJetBrains,
+		&i.SessionCountReconnectingPTY,
+	)
+	return i, err
+}
+
+const getDeploymentWorkspaceAgentUsageStats = `-- name: GetDeploymentWorkspaceAgentUsageStats :one
+WITH agent_stats AS (
+	SELECT
+		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
+		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
+		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
+		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
+	 FROM workspace_agent_stats
+	 	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+		WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
+),
+minute_buckets AS (
+	SELECT
+		agent_id,
+		date_trunc('minute', created_at) AS minute_bucket,
+		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
+		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
+		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
+		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
+	FROM
+		workspace_agent_stats
+	WHERE
+		created_at >= $1
+		AND created_at < date_trunc('minute', now())  -- Exclude current partial minute
+		AND usage = true
+	GROUP BY
+		agent_id,
+		minute_bucket
+),
+latest_buckets AS (
+	SELECT DISTINCT ON (agent_id)
+		agent_id,
+		minute_bucket,
+		session_count_vscode,
+		session_count_jetbrains,
+		session_count_reconnecting_pty,
+		session_count_ssh
+	FROM
+		minute_buckets
+	ORDER BY
+		agent_id,
+		minute_bucket DESC
+),
+latest_agent_stats AS (
+    SELECT
+		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
+		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
+		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
+		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
+    FROM
+        latest_buckets
+)
+SELECT workspace_rx_bytes, workspace_tx_bytes, workspace_connection_latency_50, workspace_connection_latency_95, session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty FROM agent_stats, latest_agent_stats
+`
+
+type GetDeploymentWorkspaceAgentUsageStatsRow struct {
+	WorkspaceRxBytes             int64   `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
+	WorkspaceTxBytes             int64   `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
+	WorkspaceConnectionLatency50 float64 `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64 `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	SessionCountVSCode           int64   `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountSSH              int64   `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountJetBrains        int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY  int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+}
+
+func (q *sqlQuerier) GetDeploymentWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentUsageStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getDeploymentWorkspaceAgentUsageStats, createdAt)
+	var i GetDeploymentWorkspaceAgentUsageStatsRow
+	err := row.Scan(
+		&i.WorkspaceRxBytes,
+		&i.WorkspaceTxBytes,
+		&i.WorkspaceConnectionLatency50,
+		&i.WorkspaceConnectionLatency95,
+		&i.SessionCountVSCode,
+		&i.SessionCountSSH,
+		&i.SessionCountJetBrains,
+		&i.SessionCountReconnectingPTY,
+	)
+	return i, err
+}
+
+const getWorkspaceAgentStats = `-- name: GetWorkspaceAgentStats :many
+WITH agent_stats AS (
+	SELECT
+		user_id,
+		agent_id,
+		workspace_id,
+		template_id,
+		MIN(created_at)::timestamptz AS aggregated_from,
+		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
+		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
+		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
+		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
+	 FROM workspace_agent_stats
+	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+	WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
+	GROUP BY user_id, agent_id, workspace_id, template_id
+), latest_agent_stats AS (
+	SELECT
+		a.agent_id,
+		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
+		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
+		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
+		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
+	 FROM (
+		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, usage, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
+		FROM workspace_agent_stats WHERE created_at > $1
+	) AS a WHERE a.rn = 1 GROUP BY a.user_id, a.agent_id, a.workspace_id, a.template_id
+)
+SELECT user_id, agent_stats.agent_id, workspace_id, template_id, aggregated_from, workspace_rx_bytes, workspace_tx_bytes, workspace_connection_latency_50, workspace_connection_latency_95, latest_agent_stats.agent_id, session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty FROM agent_stats JOIN latest_agent_stats ON agent_stats.agent_id = latest_agent_stats.agent_id
+`
+
+type GetWorkspaceAgentStatsRow struct {
+	UserID                       uuid.UUID `db:"user_id" json:"user_id"`
+	AgentID                      uuid.UUID `db:"agent_id" json:"agent_id"`
+	WorkspaceID                  uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateID                   uuid.UUID `db:"template_id" json:"template_id"`
+	AggregatedFrom               time.Time `db:"aggregated_from" json:"aggregated_from"`
+	WorkspaceRxBytes             int64     `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
+	WorkspaceTxBytes             int64     `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
+	WorkspaceConnectionLatency50 float64   `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64   `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	AgentID_2                    uuid.UUID `db:"agent_id_2" json:"agent_id_2"`
+	SessionCountVSCode           int64     `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountSSH              int64     `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountJetBrains        int64     `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY  int64     `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStats, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentStatsRow
+	for rows.Next() {
+		var i GetWorkspaceAgentStatsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.AgentID,
+			&i.WorkspaceID,
+			&i.TemplateID,
+			&i.AggregatedFrom,
+			&i.WorkspaceRxBytes,
+			&i.WorkspaceTxBytes,
+			&i.WorkspaceConnectionLatency50,
+			&i.WorkspaceConnectionLatency95,
+			&i.AgentID_2,
+			&i.SessionCountVSCode,
+			&i.SessionCountSSH,
+			&i.SessionCountJetBrains,
+			&i.SessionCountReconnectingPTY,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentStatsAndLabels = `-- name: GetWorkspaceAgentStatsAndLabels :many
+WITH agent_stats AS (
+	SELECT
+		user_id,
+		agent_id,
+		workspace_id,
+		coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
+		coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes
+	 FROM workspace_agent_stats
+		WHERE workspace_agent_stats.created_at > $1
+		GROUP BY user_id, agent_id, workspace_id
+), latest_agent_stats AS (
+	SELECT
+		a.agent_id,
+		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
+		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
+		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
+		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty,
+		coalesce(SUM(connection_count), 0)::bigint AS connection_count,
+		coalesce(MAX(connection_median_latency_ms), 0)::float AS connection_median_latency_ms
+	 FROM (
+		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, usage, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
+		FROM workspace_agent_stats
+		-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+		WHERE created_at > $1 AND connection_median_latency_ms > 0
+	) AS a
+	WHERE a.rn = 1
+	GROUP BY a.user_id, a.agent_id, a.workspace_id
+)
+SELECT
+	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name, rx_bytes, tx_bytes,
+	session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty,
+	connection_count, connection_median_latency_ms
+FROM
+	agent_stats
+JOIN
+	latest_agent_stats
+ON
+	agent_stats.agent_id = latest_agent_stats.agent_id
+JOIN
+	users
+ON
+	users.id = agent_stats.user_id
+JOIN
+	workspace_agents
+ON
+	workspace_agents.id = agent_stats.agent_id
+JOIN
+	workspaces
+ON
+	workspaces.id = agent_stats.workspace_id
 `
 
-type GetWorkspaceAgentScriptTimingsByBuildIDRow struct {
-	ScriptID           uuid.UUID                        `db:"script_id" json:"script_id"`
-	StartedAt          time.Time                        `db:"started_at" json:"started_at"`
-	EndedAt            time.Time                        `db:"ended_at" json:"ended_at"`
-	ExitCode           int32                            `db:"exit_code" json:"exit_code"`
-	Stage              WorkspaceAgentScriptTimingStage  `db:"stage" json:"stage"`
-	Status             WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
-	DisplayName        string                           `db:"display_name" json:"display_name"`
-	WorkspaceAgentID   uuid.UUID                        `db:"workspace_agent_id" json:"workspace_agent_id"`
-	WorkspaceAgentName string                           `db:"workspace_agent_name" json:"workspace_agent_name"`
+type GetWorkspaceAgentStatsAndLabelsRow struct {
+	Username                    string  `db:"username" json:"username"`
+	AgentName                   string  `db:"agent_name" json:"agent_name"`
+	WorkspaceName               string  `db:"workspace_name" json:"workspace_name"`
+	RxBytes                     int64   `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes                     int64   `db:"tx_bytes" json:"tx_bytes"`
+	SessionCountVSCode          int64   `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountSSH             int64   `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountJetBrains       int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	ConnectionCount             int64   `db:"connection_count" json:"connection_count"`
+	ConnectionMedianLatencyMS   float64 `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
 }
 
-func (q *sqlQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentScriptTimingsByBuildID, id)
+func (q *sqlQuerier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsAndLabelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStatsAndLabels, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentScriptTimingsByBuildIDRow
+	var items []GetWorkspaceAgentStatsAndLabelsRow
 	for rows.Next() {
-		var i GetWorkspaceAgentScriptTimingsByBuildIDRow
+		var i GetWorkspaceAgentStatsAndLabelsRow
 		if err := rows.Scan(
-			&i.ScriptID,
-			&i.StartedAt,
-			&i.EndedAt,
-			&i.ExitCode,
-			&i.Stage,
-			&i.Status,
-			&i.DisplayName,
-			&i.WorkspaceAgentID,
-			&i.WorkspaceAgentName,
+			&i.Username,
+			&i.AgentName,
+			&i.WorkspaceName,
+			&i.RxBytes,
+			&i.TxBytes,
+			&i.SessionCountVSCode,
+			&i.SessionCountSSH,
+			&i.SessionCountJetBrains,
+			&i.SessionCountReconnectingPTY,
+			&i.ConnectionCount,
+			&i.ConnectionMedianLatencyMS,
 		); err != nil {
 			return nil, err
 		}
@@ -33313,65 +40977,117 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context
 	return items, nil
 }
 
-const getWorkspaceAgentsByInstanceID = `-- name: GetWorkspaceAgentsByInstanceID :many
-SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
-FROM
-	workspace_agents
-WHERE
-	auth_instance_id = $1 :: TEXT
-	-- Filter out deleted agents.
-	AND deleted = FALSE
-	-- Filter out sub agents, they do not authenticate with auth_instance_id.
-	AND parent_id IS NULL
-ORDER BY
-	created_at DESC
+const getWorkspaceAgentUsageStats = `-- name: GetWorkspaceAgentUsageStats :many
+WITH agent_stats AS (
+	SELECT
+		user_id,
+		agent_id,
+		workspace_id,
+		template_id,
+		MIN(created_at)::timestamptz AS aggregated_from,
+		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
+		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
+		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
+		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
+	FROM workspace_agent_stats
+	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+	WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
+	GROUP BY user_id, agent_id, workspace_id, template_id
+),
+minute_buckets AS (
+	SELECT
+		agent_id,
+		date_trunc('minute', created_at) AS minute_bucket,
+		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
+		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
+		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
+		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
+	FROM
+		workspace_agent_stats
+	WHERE
+		created_at >= $1
+		AND created_at < date_trunc('minute', now())  -- Exclude current partial minute
+		AND usage = true
+	GROUP BY
+		agent_id,
+		minute_bucket,
+		user_id,
+		agent_id,
+		workspace_id,
+		template_id
+),
+latest_buckets AS (
+	SELECT DISTINCT ON (agent_id)
+		agent_id,
+		session_count_vscode,
+		session_count_ssh,
+		session_count_jetbrains,
+		session_count_reconnecting_pty
+	FROM
+		minute_buckets
+	ORDER BY
+		agent_id,
+		minute_bucket DESC
+)
+SELECT user_id,
+agent_stats.agent_id,
+workspace_id,
+template_id,
+aggregated_from,
+workspace_rx_bytes,
+workspace_tx_bytes,
+workspace_connection_latency_50,
+workspace_connection_latency_95,
+coalesce(latest_buckets.agent_id,agent_stats.agent_id) AS agent_id,
+coalesce(session_count_vscode, 0)::bigint AS session_count_vscode,
+coalesce(session_count_ssh, 0)::bigint AS session_count_ssh,
+coalesce(session_count_jetbrains, 0)::bigint AS session_count_jetbrains,
+coalesce(session_count_reconnecting_pty, 0)::bigint AS session_count_reconnecting_pty
+FROM agent_stats LEFT JOIN latest_buckets ON agent_stats.agent_id = latest_buckets.agent_id
 `
 
-func (q *sqlQuerier) GetWorkspaceAgentsByInstanceID(ctx context.Context, authInstanceID string) ([]WorkspaceAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsByInstanceID, authInstanceID)
+type GetWorkspaceAgentUsageStatsRow struct {
+	UserID                       uuid.UUID `db:"user_id" json:"user_id"`
+	AgentID                      uuid.UUID `db:"agent_id" json:"agent_id"`
+	WorkspaceID                  uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateID                   uuid.UUID `db:"template_id" json:"template_id"`
+	AggregatedFrom               time.Time `db:"aggregated_from" json:"aggregated_from"`
+	WorkspaceRxBytes             int64     `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
+	WorkspaceTxBytes             int64     `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
+	WorkspaceConnectionLatency50 float64   `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64   `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	AgentID_2                    uuid.UUID `db:"agent_id_2" json:"agent_id_2"`
+	SessionCountVSCode           int64     `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountSSH              int64     `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountJetBrains        int64     `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY  int64     `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+}
+
+// `minute_buckets` could return 0 rows if there are no usage stats since `created_at`.
+func (q *sqlQuerier) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentUsageStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentUsageStats, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgent
+	var items []GetWorkspaceAgentUsageStatsRow
 	for rows.Next() {
-		var i WorkspaceAgent
+		var i GetWorkspaceAgentUsageStatsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.FirstConnectedAt,
-			&i.LastConnectedAt,
-			&i.DisconnectedAt,
-			&i.ResourceID,
-			&i.AuthToken,
-			&i.AuthInstanceID,
-			&i.Architecture,
-			&i.EnvironmentVariables,
-			&i.OperatingSystem,
-			&i.InstanceMetadata,
-			&i.ResourceMetadata,
-			&i.Directory,
-			&i.Version,
-			&i.LastConnectedReplicaID,
-			&i.ConnectionTimeoutSeconds,
-			&i.TroubleshootingURL,
-			&i.MOTDFile,
-			&i.LifecycleState,
-			&i.ExpandedDirectory,
-			&i.LogsLength,
-			&i.LogsOverflowed,
-			&i.StartedAt,
-			&i.ReadyAt,
-			pq.Array(&i.Subsystems),
-			pq.Array(&i.DisplayApps),
-			&i.APIVersion,
-			&i.DisplayOrder,
-			&i.ParentID,
-			&i.APIKeyScope,
-			&i.Deleted,
+			&i.UserID,
+			&i.AgentID,
+			&i.WorkspaceID,
+			&i.TemplateID,
+			&i.AggregatedFrom,
+			&i.WorkspaceRxBytes,
+			&i.WorkspaceTxBytes,
+			&i.WorkspaceConnectionLatency50,
+			&i.WorkspaceConnectionLatency95,
+			&i.AgentID_2,
+			&i.SessionCountVSCode,
+			&i.SessionCountSSH,
+			&i.SessionCountJetBrains,
+			&i.SessionCountReconnectingPTY,
 		); err != nil {
 			return nil, err
 		}
@@ -33386,60 +41102,96 @@ func (q *sqlQuerier) GetWorkspaceAgentsByInstanceID(ctx context.Context, authIns
 	return items, nil
 }
 
-const getWorkspaceAgentsByParentID = `-- name: GetWorkspaceAgentsByParentID :many
+const getWorkspaceAgentUsageStatsAndLabels = `-- name: GetWorkspaceAgentUsageStatsAndLabels :many
+WITH agent_stats AS (
+	SELECT
+		user_id,
+		agent_id,
+		workspace_id,
+		coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
+		coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes,
+		coalesce(MAX(connection_median_latency_ms), 0)::float AS connection_median_latency_ms
+	FROM workspace_agent_stats
+	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+	WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
+	GROUP BY user_id, agent_id, workspace_id
+), latest_agent_stats AS (
+	SELECT
+		agent_id,
+		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
+		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
+		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
+		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty,
+		coalesce(SUM(connection_count), 0)::bigint AS connection_count
+	FROM workspace_agent_stats
+	-- We only want the latest stats, but those stats might be
+	-- spread across multiple rows.
+	WHERE usage = true AND created_at > now() - '1 minute'::interval
+	GROUP BY user_id, agent_id, workspace_id
+)
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
+	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name, rx_bytes, tx_bytes,
+	coalesce(session_count_vscode, 0)::bigint AS session_count_vscode,
+	coalesce(session_count_ssh, 0)::bigint AS session_count_ssh,
+	coalesce(session_count_jetbrains, 0)::bigint AS session_count_jetbrains,
+	coalesce(session_count_reconnecting_pty, 0)::bigint AS session_count_reconnecting_pty,
+	coalesce(connection_count, 0)::bigint AS connection_count,
+	connection_median_latency_ms
 FROM
+	agent_stats
+LEFT JOIN
+	latest_agent_stats
+ON
+	agent_stats.agent_id = latest_agent_stats.agent_id
+JOIN
+	users
+ON
+	users.id = agent_stats.user_id
+JOIN
 	workspace_agents
-WHERE
-	parent_id = $1::uuid
-	AND deleted = FALSE
+ON
+	workspace_agents.id = agent_stats.agent_id
+JOIN
+	workspaces
+ON
+	workspaces.id = agent_stats.workspace_id
 `
 
-func (q *sqlQuerier) GetWorkspaceAgentsByParentID(ctx context.Context, parentID uuid.UUID) ([]WorkspaceAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsByParentID, parentID)
+type GetWorkspaceAgentUsageStatsAndLabelsRow struct {
+	Username                    string  `db:"username" json:"username"`
+	AgentName                   string  `db:"agent_name" json:"agent_name"`
+	WorkspaceName               string  `db:"workspace_name" json:"workspace_name"`
+	RxBytes                     int64   `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes                     int64   `db:"tx_bytes" json:"tx_bytes"`
+	SessionCountVSCode          int64   `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountSSH             int64   `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountJetBrains       int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	ConnectionCount             int64   `db:"connection_count" json:"connection_count"`
+	ConnectionMedianLatencyMS   float64 `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentUsageStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentUsageStatsAndLabelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentUsageStatsAndLabels, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgent
+	var items []GetWorkspaceAgentUsageStatsAndLabelsRow
 	for rows.Next() {
-		var i WorkspaceAgent
+		var i GetWorkspaceAgentUsageStatsAndLabelsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.FirstConnectedAt,
-			&i.LastConnectedAt,
-			&i.DisconnectedAt,
-			&i.ResourceID,
-			&i.AuthToken,
-			&i.AuthInstanceID,
-			&i.Architecture,
-			&i.EnvironmentVariables,
-			&i.OperatingSystem,
-			&i.InstanceMetadata,
-			&i.ResourceMetadata,
-			&i.Directory,
-			&i.Version,
-			&i.LastConnectedReplicaID,
-			&i.ConnectionTimeoutSeconds,
-			&i.TroubleshootingURL,
-			&i.MOTDFile,
-			&i.LifecycleState,
-			&i.ExpandedDirectory,
-			&i.LogsLength,
-			&i.LogsOverflowed,
-			&i.StartedAt,
-			&i.ReadyAt,
-			pq.Array(&i.Subsystems),
-			pq.Array(&i.DisplayApps),
-			&i.APIVersion,
-			&i.DisplayOrder,
-			&i.ParentID,
-			&i.APIKeyScope,
-			&i.Deleted,
+			&i.Username,
+			&i.AgentName,
+			&i.WorkspaceName,
+			&i.RxBytes,
+			&i.TxBytes,
+			&i.SessionCountVSCode,
+			&i.SessionCountSSH,
+			&i.SessionCountJetBrains,
+			&i.SessionCountReconnectingPTY,
+			&i.ConnectionCount,
+			&i.ConnectionMedianLatencyMS,
 		); err != nil {
 			return nil, err
 		}
@@ -33454,61 +41206,232 @@ func (q *sqlQuerier) GetWorkspaceAgentsByParentID(ctx context.Context, parentID
 	return items, nil
 }
 
-const getWorkspaceAgentsByResourceIDs = `-- name: GetWorkspaceAgentsByResourceIDs :many
-SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
-FROM
-	workspace_agents
-WHERE
-	resource_id = ANY($1 :: uuid [ ])
-	-- Filter out deleted sub agents.
-	AND deleted = FALSE
+const insertWorkspaceAgentStats = `-- name: InsertWorkspaceAgentStats :exec
+INSERT INTO
+	workspace_agent_stats (
+		id,
+		created_at,
+		user_id,
+		workspace_id,
+		template_id,
+		agent_id,
+		connections_by_proto,
+		connection_count,
+		rx_packets,
+		rx_bytes,
+		tx_packets,
+		tx_bytes,
+		session_count_vscode,
+		session_count_jetbrains,
+		session_count_reconnecting_pty,
+		session_count_ssh,
+		connection_median_latency_ms,
+		usage
+	)
+SELECT
+	unnest($1 :: uuid[]) AS id,
+	unnest($2 :: timestamptz[]) AS created_at,
+	unnest($3 :: uuid[]) AS user_id,
+	unnest($4 :: uuid[]) AS workspace_id,
+	unnest($5 :: uuid[]) AS template_id,
+	unnest($6 :: uuid[]) AS agent_id,
+	jsonb_array_elements($7 :: jsonb) AS connections_by_proto,
+	unnest($8 :: bigint[]) AS connection_count,
+	unnest($9 :: bigint[]) AS rx_packets,
+	unnest($10 :: bigint[]) AS rx_bytes,
+	unnest($11 :: bigint[]) AS tx_packets,
+	unnest($12 :: bigint[]) AS tx_bytes,
+	unnest($13 :: bigint[]) AS session_count_vscode,
+	unnest($14 :: bigint[]) AS session_count_jetbrains,
+	unnest($15 :: bigint[]) AS session_count_reconnecting_pty,
+	unnest($16 :: bigint[]) AS session_count_ssh,
+	unnest($17 :: double precision[]) AS connection_median_latency_ms,
+	unnest($18 :: boolean[]) AS usage
+`
+
+type InsertWorkspaceAgentStatsParams struct {
+	ID                          []uuid.UUID     `db:"id" json:"id"`
+	CreatedAt                   []time.Time     `db:"created_at" json:"created_at"`
+	UserID                      []uuid.UUID     `db:"user_id" json:"user_id"`
+	WorkspaceID                 []uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+	TemplateID                  []uuid.UUID     `db:"template_id" json:"template_id"`
+	AgentID                     []uuid.UUID     `db:"agent_id" json:"agent_id"`
+	ConnectionsByProto          json.RawMessage `db:"connections_by_proto" json:"connections_by_proto"`
+	ConnectionCount             []int64         `db:"connection_count" json:"connection_count"`
+	RxPackets                   []int64         `db:"rx_packets" json:"rx_packets"`
+	RxBytes                     []int64         `db:"rx_bytes" json:"rx_bytes"`
+	TxPackets                   []int64         `db:"tx_packets" json:"tx_packets"`
+	TxBytes                     []int64         `db:"tx_bytes" json:"tx_bytes"`
+	SessionCountVSCode          []int64         `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountJetBrains       []int64         `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY []int64         `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	SessionCountSSH             []int64         `db:"session_count_ssh" json:"session_count_ssh"`
+	ConnectionMedianLatencyMS   []float64       `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+	Usage                       []bool          `db:"usage" json:"usage"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentStats(ctx context.Context, arg InsertWorkspaceAgentStatsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentStats,
+		pq.Array(arg.ID),
+		pq.Array(arg.CreatedAt),
+		pq.Array(arg.UserID),
+		pq.Array(arg.WorkspaceID),
+		pq.Array(arg.TemplateID),
+		pq.Array(arg.AgentID),
+		arg.ConnectionsByProto,
+		pq.Array(arg.ConnectionCount),
+		pq.Array(arg.RxPackets),
+		pq.Array(arg.RxBytes),
+		pq.Array(arg.TxPackets),
+		pq.Array(arg.TxBytes),
+		pq.Array(arg.SessionCountVSCode),
+		pq.Array(arg.SessionCountJetBrains),
+		pq.Array(arg.SessionCountReconnectingPTY),
+		pq.Array(arg.SessionCountSSH),
+		pq.Array(arg.ConnectionMedianLatencyMS),
+		pq.Array(arg.Usage),
+	)
+	return err
+}
+
+const upsertWorkspaceAppAuditSession = `-- name: UpsertWorkspaceAppAuditSession :one
+INSERT INTO
+	workspace_app_audit_sessions (
+		id,
+		agent_id,
+		app_id,
+		user_id,
+		ip,
+		user_agent,
+		slug_or_port,
+		status_code,
+		started_at,
+		updated_at
+	)
+VALUES
+	(
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7,
+		$8,
+		$9,
+		$10
+	)
+ON CONFLICT
+	(agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code)
+DO
+	UPDATE
+	SET
+		-- ID is used to know if session was reset on upsert.
+		id = CASE
+			WHEN workspace_app_audit_sessions.updated_at > NOW() - ($11::bigint || ' ms')::interval
+			THEN workspace_app_audit_sessions.id
+			ELSE EXCLUDED.id
+		END,
+		started_at = CASE
+			WHEN workspace_app_audit_sessions.updated_at > NOW() - ($11::bigint || ' ms')::interval
+			THEN workspace_app_audit_sessions.started_at
+			ELSE EXCLUDED.started_at
+		END,
+		updated_at = EXCLUDED.updated_at
+RETURNING
+	id = $1 AS new_or_stale
+`
+
+type UpsertWorkspaceAppAuditSessionParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	AgentID         uuid.UUID `db:"agent_id" json:"agent_id"`
+	AppID           uuid.UUID `db:"app_id" json:"app_id"`
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	Ip              string    `db:"ip" json:"ip"`
+	UserAgent       string    `db:"user_agent" json:"user_agent"`
+	SlugOrPort      string    `db:"slug_or_port" json:"slug_or_port"`
+	StatusCode      int32     `db:"status_code" json:"status_code"`
+	StartedAt       time.Time `db:"started_at" json:"started_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	StaleIntervalMS int64     `db:"stale_interval_ms" json:"stale_interval_ms"`
+}
+
+// The returned boolean, new_or_stale, can be used to deduce if a new session
+// was started. This means that a new row was inserted (no previous session) or
+// the updated_at is older than stale interval.
+func (q *sqlQuerier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg UpsertWorkspaceAppAuditSessionParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceAppAuditSession,
+		arg.ID,
+		arg.AgentID,
+		arg.AppID,
+		arg.UserID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.SlugOrPort,
+		arg.StatusCode,
+		arg.StartedAt,
+		arg.UpdatedAt,
+		arg.StaleIntervalMS,
+	)
+	var new_or_stale bool
+	err := row.Scan(&new_or_stale)
+	return new_or_stale, err
+}
+
+const getLatestWorkspaceAppStatusByAppID = `-- name: GetLatestWorkspaceAppStatusByAppID :one
+SELECT id, created_at, agent_id, app_id, workspace_id, state, message, uri
+FROM workspace_app_statuses
+WHERE app_id = $1::uuid
+ORDER BY created_at DESC, id DESC
+LIMIT 1
 `
 
-func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsByResourceIDs, pq.Array(ids))
+func (q *sqlQuerier) GetLatestWorkspaceAppStatusByAppID(ctx context.Context, appID uuid.UUID) (WorkspaceAppStatus, error) {
+	row := q.db.QueryRowContext(ctx, getLatestWorkspaceAppStatusByAppID, appID)
+	var i WorkspaceAppStatus
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.AgentID,
+		&i.AppID,
+		&i.WorkspaceID,
+		&i.State,
+		&i.Message,
+		&i.Uri,
+	)
+	return i, err
+}
+
+const getLatestWorkspaceAppStatusesByWorkspaceIDs = `-- name: GetLatestWorkspaceAppStatusesByWorkspaceIDs :many
+SELECT DISTINCT ON (workspace_id)
+  id, created_at, agent_id, app_id, workspace_id, state, message, uri
+FROM workspace_app_statuses
+WHERE workspace_id = ANY($1 :: uuid[])
+ORDER BY workspace_id, created_at DESC, id DESC
+`
+
+// id DESC is a stability tiebreaker, not an insertion-order signal: back-to-back
+// inserts can share a created_at on platforms with coarse time.Now() resolution,
+// and id is a random UUID, so this only guarantees a deterministic pick, not the
+// later row. Callers must not depend on sub-microsecond recency here.
+func (q *sqlQuerier) GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAppStatus, error) {
+	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceAppStatusesByWorkspaceIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgent
+	var items []WorkspaceAppStatus
 	for rows.Next() {
-		var i WorkspaceAgent
+		var i WorkspaceAppStatus
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.FirstConnectedAt,
-			&i.LastConnectedAt,
-			&i.DisconnectedAt,
-			&i.ResourceID,
-			&i.AuthToken,
-			&i.AuthInstanceID,
-			&i.Architecture,
-			&i.EnvironmentVariables,
-			&i.OperatingSystem,
-			&i.InstanceMetadata,
-			&i.ResourceMetadata,
-			&i.Directory,
-			&i.Version,
-			&i.LastConnectedReplicaID,
-			&i.ConnectionTimeoutSeconds,
-			&i.TroubleshootingURL,
-			&i.MOTDFile,
-			&i.LifecycleState,
-			&i.ExpandedDirectory,
-			&i.LogsLength,
-			&i.LogsOverflowed,
-			&i.StartedAt,
-			&i.ReadyAt,
-			pq.Array(&i.Subsystems),
-			pq.Array(&i.DisplayApps),
-			&i.APIVersion,
-			&i.DisplayOrder,
-			&i.ParentID,
-			&i.APIKeyScope,
-			&i.Deleted,
+			&i.AgentID,
+			&i.AppID,
+			&i.WorkspaceID,
+			&i.State,
+			&i.Message,
+			&i.Uri,
 		); err != nil {
 			return nil, err
 		}
@@ -33523,137 +41446,68 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 	return items, nil
 }
 
-const getWorkspaceAgentsByWorkspaceAndBuildNumber = `-- name: GetWorkspaceAgentsByWorkspaceAndBuildNumber :many
-SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted
-FROM
-	workspace_agents
-JOIN
-	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
-JOIN
-	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
-WHERE
-	workspace_builds.workspace_id = $1 :: uuid AND
-	workspace_builds.build_number = $2 :: int
-	-- Filter out deleted sub agents.
-	AND workspace_agents.deleted = FALSE
+const getWorkspaceAppByAgentIDAndSlug = `-- name: GetWorkspaceAppByAgentIDAndSlug :one
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip, max_session_duration_seconds, session_idle_timeout_seconds FROM workspace_apps WHERE agent_id = $1 AND slug = $2
 `
 
-type GetWorkspaceAgentsByWorkspaceAndBuildNumberParams struct {
-	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	BuildNumber int32     `db:"build_number" json:"build_number"`
+type GetWorkspaceAppByAgentIDAndSlugParams struct {
+	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
+	Slug    string    `db:"slug" json:"slug"`
 }
 
-func (q *sqlQuerier) GetWorkspaceAgentsByWorkspaceAndBuildNumber(ctx context.Context, arg GetWorkspaceAgentsByWorkspaceAndBuildNumberParams) ([]WorkspaceAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsByWorkspaceAndBuildNumber, arg.WorkspaceID, arg.BuildNumber)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []WorkspaceAgent
-	for rows.Next() {
-		var i WorkspaceAgent
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.FirstConnectedAt,
-			&i.LastConnectedAt,
-			&i.DisconnectedAt,
-			&i.ResourceID,
-			&i.AuthToken,
-			&i.AuthInstanceID,
-			&i.Architecture,
-			&i.EnvironmentVariables,
-			&i.OperatingSystem,
-			&i.InstanceMetadata,
-			&i.ResourceMetadata,
-			&i.Directory,
-			&i.Version,
-			&i.LastConnectedReplicaID,
-			&i.ConnectionTimeoutSeconds,
-			&i.TroubleshootingURL,
-			&i.MOTDFile,
-			&i.LifecycleState,
-			&i.ExpandedDirectory,
-			&i.LogsLength,
-			&i.LogsOverflowed,
-			&i.StartedAt,
-			&i.ReadyAt,
-			pq.Array(&i.Subsystems),
-			pq.Array(&i.DisplayApps),
-			&i.APIVersion,
-			&i.DisplayOrder,
-			&i.ParentID,
-			&i.APIKeyScope,
-			&i.Deleted,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg GetWorkspaceAppByAgentIDAndSlugParams) (WorkspaceApp, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAppByAgentIDAndSlug, arg.AgentID, arg.Slug)
+	var i WorkspaceApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.AgentID,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Command,
+		&i.Url,
+		&i.HealthcheckUrl,
+		&i.HealthcheckInterval,
+		&i.HealthcheckThreshold,
+		&i.Health,
+		&i.Subdomain,
+		&i.SharingLevel,
+		&i.Slug,
+		&i.External,
+		&i.DisplayOrder,
+		&i.Hidden,
+		&i.OpenIn,
+		&i.DisplayGroup,
+		&i.Tooltip,
+		&i.MaxSessionDurationSeconds,
+		&i.SessionIdleTimeoutSeconds,
+	)
+	return i, err
 }
 
-const getWorkspaceAgentsCreatedAfter = `-- name: GetWorkspaceAgentsCreatedAfter :many
-SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted FROM workspace_agents
-WHERE
-	created_at > $1
-	-- Filter out deleted sub agents.
-	AND deleted = FALSE
+const getWorkspaceAppStatusesByAppIDs = `-- name: GetWorkspaceAppStatusesByAppIDs :many
+SELECT id, created_at, agent_id, app_id, workspace_id, state, message, uri FROM workspace_app_statuses WHERE app_id = ANY($1 :: uuid [ ])
+ORDER BY created_at DESC, id DESC
 `
 
-func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsCreatedAfter, createdAt)
+func (q *sqlQuerier) GetWorkspaceAppStatusesByAppIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAppStatus, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppStatusesByAppIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgent
+	var items []WorkspaceAppStatus
 	for rows.Next() {
-		var i WorkspaceAgent
+		var i WorkspaceAppStatus
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.FirstConnectedAt,
-			&i.LastConnectedAt,
-			&i.DisconnectedAt,
-			&i.ResourceID,
-			&i.AuthToken,
-			&i.AuthInstanceID,
-			&i.Architecture,
-			&i.EnvironmentVariables,
-			&i.OperatingSystem,
-			&i.InstanceMetadata,
-			&i.ResourceMetadata,
-			&i.Directory,
-			&i.Version,
-			&i.LastConnectedReplicaID,
-			&i.ConnectionTimeoutSeconds,
-			&i.TroubleshootingURL,
-			&i.MOTDFile,
-			&i.LifecycleState,
-			&i.ExpandedDirectory,
-			&i.LogsLength,
-			&i.LogsOverflowed,
-			&i.StartedAt,
-			&i.ReadyAt,
-			pq.Array(&i.Subsystems),
-			pq.Array(&i.DisplayApps),
-			&i.APIVersion,
-			&i.DisplayOrder,
-			&i.ParentID,
-			&i.APIKeyScope,
-			&i.Deleted,
+			&i.AgentID,
+			&i.AppID,
+			&i.WorkspaceID,
+			&i.State,
+			&i.Message,
+			&i.Uri,
 		); err != nil {
 			return nil, err
 		}
@@ -33668,88 +41522,42 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 	return items, nil
 }
 
-const getWorkspaceAgentsForMetrics = `-- name: GetWorkspaceAgentsForMetrics :many
-SELECT
-    w.id as workspace_id,
-    w.name as workspace_name,
-    u.username as owner_username,
-    t.name as template_name,
-    tv.name as template_version_name,
-    workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted
-FROM workspaces w
-JOIN users u ON w.owner_id = u.id
-JOIN templates t ON w.template_id = t.id
-JOIN workspace_builds wb ON w.id = wb.workspace_id
-LEFT JOIN template_versions tv ON wb.template_version_id = tv.id
-JOIN workspace_resources wr ON wb.job_id = wr.job_id
-JOIN workspace_agents ON wr.id = workspace_agents.resource_id
-WHERE w.deleted = false
-AND wb.build_number = (
-    SELECT MAX(wb2.build_number)
-    FROM workspace_builds wb2
-    WHERE wb2.workspace_id = w.id
-)
-AND workspace_agents.deleted = FALSE
+const getWorkspaceAppsByAgentID = `-- name: GetWorkspaceAppsByAgentID :many
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip, max_session_duration_seconds, session_idle_timeout_seconds FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
 `
 
-type GetWorkspaceAgentsForMetricsRow struct {
-	WorkspaceID         uuid.UUID      `db:"workspace_id" json:"workspace_id"`
-	WorkspaceName       string         `db:"workspace_name" json:"workspace_name"`
-	OwnerUsername       string         `db:"owner_username" json:"owner_username"`
-	TemplateName        string         `db:"template_name" json:"template_name"`
-	TemplateVersionName sql.NullString `db:"template_version_name" json:"template_version_name"`
-	WorkspaceAgent      WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
-}
-
-func (q *sqlQuerier) GetWorkspaceAgentsForMetrics(ctx context.Context) ([]GetWorkspaceAgentsForMetricsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsForMetrics)
+func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsByAgentID, agentID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentsForMetricsRow
+	var items []WorkspaceApp
 	for rows.Next() {
-		var i GetWorkspaceAgentsForMetricsRow
+		var i WorkspaceApp
 		if err := rows.Scan(
-			&i.WorkspaceID,
-			&i.WorkspaceName,
-			&i.OwnerUsername,
-			&i.TemplateName,
-			&i.TemplateVersionName,
-			&i.WorkspaceAgent.ID,
-			&i.WorkspaceAgent.CreatedAt,
-			&i.WorkspaceAgent.UpdatedAt,
-			&i.WorkspaceAgent.Name,
-			&i.WorkspaceAgent.FirstConnectedAt,
-			&i.WorkspaceAgent.LastConnectedAt,
-			&i.WorkspaceAgent.DisconnectedAt,
-			&i.WorkspaceAgent.ResourceID,
-			&i.WorkspaceAgent.AuthToken,
-			&i.WorkspaceAgent.AuthInstanceID,
-			&i.WorkspaceAgent.Architecture,
-			&i.WorkspaceAgent.EnvironmentVariables,
-			&i.WorkspaceAgent.OperatingSystem,
-			&i.WorkspaceAgent.InstanceMetadata,
-			&i.WorkspaceAgent.ResourceMetadata,
-			&i.WorkspaceAgent.Directory,
-			&i.WorkspaceAgent.Version,
-			&i.WorkspaceAgent.LastConnectedReplicaID,
-			&i.WorkspaceAgent.ConnectionTimeoutSeconds,
-			&i.WorkspaceAgent.TroubleshootingURL,
-			&i.WorkspaceAgent.MOTDFile,
-			&i.WorkspaceAgent.LifecycleState,
-			&i.WorkspaceAgent.ExpandedDirectory,
-			&i.WorkspaceAgent.LogsLength,
-			&i.WorkspaceAgent.LogsOverflowed,
-			&i.WorkspaceAgent.StartedAt,
-			&i.WorkspaceAgent.ReadyAt,
-			pq.Array(&i.WorkspaceAgent.Subsystems),
-			pq.Array(&i.WorkspaceAgent.DisplayApps),
-			&i.WorkspaceAgent.APIVersion,
-			&i.WorkspaceAgent.DisplayOrder,
-			&i.WorkspaceAgent.ParentID,
-			&i.WorkspaceAgent.APIKeyScope,
-			&i.WorkspaceAgent.Deleted,
+			&i.ID,
+			&i.CreatedAt,
+			&i.AgentID,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Command,
+			&i.Url,
+			&i.HealthcheckUrl,
+			&i.HealthcheckInterval,
+			&i.HealthcheckThreshold,
+			&i.Health,
+			&i.Subdomain,
+			&i.SharingLevel,
+			&i.Slug,
+			&i.External,
+			&i.DisplayOrder,
+			&i.Hidden,
+			&i.OpenIn,
+			&i.DisplayGroup,
+			&i.Tooltip,
+			&i.MaxSessionDurationSeconds,
+			&i.SessionIdleTimeoutSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -33764,73 +41572,42 @@ func (q *sqlQuerier) GetWorkspaceAgentsForMetrics(ctx context.Context) ([]GetWor
 	return items, nil
 }
 
-const getWorkspaceAgentsInLatestBuildByWorkspaceID = `-- name: GetWorkspaceAgentsInLatestBuildByWorkspaceID :many
-SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted
-FROM
-	workspace_agents
-JOIN
-	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
-JOIN
-	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
-WHERE
-	workspace_builds.workspace_id = $1 :: uuid AND
-	workspace_builds.build_number = (
-    	SELECT
-			MAX(build_number)
-    	FROM
-			workspace_builds AS wb
-    	WHERE
-			wb.workspace_id = $1 :: uuid
-	)
-	-- Filter out deleted sub agents.
-	AND workspace_agents.deleted = FALSE
+const getWorkspaceAppsByAgentIDs = `-- name: GetWorkspaceAppsByAgentIDs :many
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip, max_session_duration_seconds, session_idle_timeout_seconds FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
 `
 
-func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsInLatestBuildByWorkspaceID, workspaceID)
+func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsByAgentIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgent
+	var items []WorkspaceApp
 	for rows.Next() {
-		var i WorkspaceAgent
+		var i WorkspaceApp
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.FirstConnectedAt,
-			&i.LastConnectedAt,
-			&i.DisconnectedAt,
-			&i.ResourceID,
-			&i.AuthToken,
-			&i.AuthInstanceID,
-			&i.Architecture,
-			&i.EnvironmentVariables,
-			&i.OperatingSystem,
-			&i.InstanceMetadata,
-			&i.ResourceMetadata,
-			&i.Directory,
-			&i.Version,
-			&i.LastConnectedReplicaID,
-			&i.ConnectionTimeoutSeconds,
-			&i.TroubleshootingURL,
-			&i.MOTDFile,
-			&i.LifecycleState,
-			&i.ExpandedDirectory,
-			&i.LogsLength,
-			&i.LogsOverflowed,
-			&i.StartedAt,
-			&i.ReadyAt,
-			pq.Array(&i.Subsystems),
-			pq.Array(&i.DisplayApps),
-			&i.APIVersion,
+			&i.AgentID,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Command,
+			&i.Url,
+			&i.HealthcheckUrl,
+			&i.HealthcheckInterval,
+			&i.HealthcheckThreshold,
+			&i.Health,
+			&i.Subdomain,
+			&i.SharingLevel,
+			&i.Slug,
+			&i.External,
 			&i.DisplayOrder,
-			&i.ParentID,
-			&i.APIKeyScope,
-			&i.Deleted,
+			&i.Hidden,
+			&i.OpenIn,
+			&i.DisplayGroup,
+			&i.Tooltip,
+			&i.MaxSessionDurationSeconds,
+			&i.SessionIdleTimeoutSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -33845,83 +41622,42 @@ func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Co
 	return items, nil
 }
 
-const getWorkspaceAgentsInLatestBuildByWorkspaceIDs = `-- name: GetWorkspaceAgentsInLatestBuildByWorkspaceIDs :many
-SELECT
-	workspace_builds.workspace_id,
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted
-FROM
-	workspace_agents
-JOIN
-	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
-JOIN
-	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
-JOIN (
-	SELECT
-		workspace_id,
-		MAX(build_number) AS build_number
-	FROM
-		workspace_builds
-	WHERE
-		workspace_id = ANY($1 :: uuid [ ])
-	GROUP BY
-		workspace_id
-) AS latest_builds ON
-	latest_builds.workspace_id = workspace_builds.workspace_id AND
-	latest_builds.build_number = workspace_builds.build_number
-WHERE
-	workspace_agents.deleted = FALSE
+const getWorkspaceAppsCreatedAfter = `-- name: GetWorkspaceAppsCreatedAfter :many
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip, max_session_duration_seconds, session_idle_timeout_seconds FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
 `
 
-type GetWorkspaceAgentsInLatestBuildByWorkspaceIDsRow struct {
-	WorkspaceID    uuid.UUID      `db:"workspace_id" json:"workspace_id"`
-	WorkspaceAgent WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
-}
-
-func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceIDs(ctx context.Context, workspaceIds []uuid.UUID) ([]GetWorkspaceAgentsInLatestBuildByWorkspaceIDsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsInLatestBuildByWorkspaceIDs, pq.Array(workspaceIds))
+func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsCreatedAfter, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentsInLatestBuildByWorkspaceIDsRow
+	var items []WorkspaceApp
 	for rows.Next() {
-		var i GetWorkspaceAgentsInLatestBuildByWorkspaceIDsRow
+		var i WorkspaceApp
 		if err := rows.Scan(
-			&i.WorkspaceID,
-			&i.WorkspaceAgent.ID,
-			&i.WorkspaceAgent.CreatedAt,
-			&i.WorkspaceAgent.UpdatedAt,
-			&i.WorkspaceAgent.Name,
-			&i.WorkspaceAgent.FirstConnectedAt,
-			&i.WorkspaceAgent.LastConnectedAt,
-			&i.WorkspaceAgent.DisconnectedAt,
-			&i.WorkspaceAgent.ResourceID,
-			&i.WorkspaceAgent.AuthToken,
-			&i.WorkspaceAgent.AuthInstanceID,
-			&i.WorkspaceAgent.Architecture,
-			&i.WorkspaceAgent.EnvironmentVariables,
-			&i.WorkspaceAgent.OperatingSystem,
-			&i.WorkspaceAgent.InstanceMetadata,
-			&i.WorkspaceAgent.ResourceMetadata,
-			&i.WorkspaceAgent.Directory,
-			&i.WorkspaceAgent.Version,
-			&i.WorkspaceAgent.LastConnectedReplicaID,
-			&i.WorkspaceAgent.ConnectionTimeoutSeconds,
-			&i.WorkspaceAgent.TroubleshootingURL,
-			&i.WorkspaceAgent.MOTDFile,
-			&i.WorkspaceAgent.LifecycleState,
-			&i.WorkspaceAgent.ExpandedDirectory,
-			&i.WorkspaceAgent.LogsLength,
-			&i.WorkspaceAgent.LogsOverflowed,
-			&i.WorkspaceAgent.StartedAt,
-			&i.WorkspaceAgent.ReadyAt,
-			pq.Array(&i.WorkspaceAgent.Subsystems),
-			pq.Array(&i.WorkspaceAgent.DisplayApps),
-			&i.WorkspaceAgent.APIVersion,
-			&i.WorkspaceAgent.DisplayOrder,
-			&i.WorkspaceAgent.ParentID,
-			&i.WorkspaceAgent.APIKeyScope,
-			&i.WorkspaceAgent.Deleted,
+			&i.ID,
+			&i.CreatedAt,
+			&i.AgentID,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Command,
+			&i.Url,
+			&i.HealthcheckUrl,
+			&i.HealthcheckInterval,
+			&i.HealthcheckThreshold,
+			&i.Health,
+			&i.Subdomain,
+			&i.SharingLevel,
+			&i.Slug,
+			&i.External,
+			&i.DisplayOrder,
+			&i.Hidden,
+			&i.OpenIn,
+			&i.DisplayGroup,
+			&i.Tooltip,
+			&i.MaxSessionDurationSeconds,
+			&i.SessionIdleTimeoutSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -33936,108 +41672,289 @@ func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceIDs(ctx context.C
 	return items, nil
 }
 
-const getWorkspaceBuildAgentsByInstanceID = `-- name: GetWorkspaceBuildAgentsByInstanceID :many
+const insertWorkspaceAppStatus = `-- name: InsertWorkspaceAppStatus :one
+INSERT INTO workspace_app_statuses (id, created_at, workspace_id, agent_id, app_id, state, message, uri)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, created_at, agent_id, app_id, workspace_id, state, message, uri
+`
+
+type InsertWorkspaceAppStatusParams struct {
+	ID          uuid.UUID               `db:"id" json:"id"`
+	CreatedAt   time.Time               `db:"created_at" json:"created_at"`
+	WorkspaceID uuid.UUID               `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID               `db:"agent_id" json:"agent_id"`
+	AppID       uuid.UUID               `db:"app_id" json:"app_id"`
+	State       WorkspaceAppStatusState `db:"state" json:"state"`
+	Message     string                  `db:"message" json:"message"`
+	Uri         sql.NullString          `db:"uri" json:"uri"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAppStatus(ctx context.Context, arg InsertWorkspaceAppStatusParams) (WorkspaceAppStatus, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAppStatus,
+		arg.ID,
+		arg.CreatedAt,
+		arg.WorkspaceID,
+		arg.AgentID,
+		arg.AppID,
+		arg.State,
+		arg.Message,
+		arg.Uri,
+	)
+	var i WorkspaceAppStatus
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.AgentID,
+		&i.AppID,
+		&i.WorkspaceID,
+		&i.State,
+		&i.Message,
+		&i.Uri,
+	)
+	return i, err
+}
+
+const updateWorkspaceAppHealthByID = `-- name: UpdateWorkspaceAppHealthByID :exec
+UPDATE
+	workspace_apps
+SET
+	health = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAppHealthByIDParams struct {
+	ID     uuid.UUID          `db:"id" json:"id"`
+	Health WorkspaceAppHealth `db:"health" json:"health"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAppHealthByID(ctx context.Context, arg UpdateWorkspaceAppHealthByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAppHealthByID, arg.ID, arg.Health)
+	return err
+}
+
+const upsertWorkspaceApp = `-- name: UpsertWorkspaceApp :one
+INSERT INTO
+    workspace_apps (
+        id,
+        created_at,
+        agent_id,
+        slug,
+        display_name,
+        icon,
+        command,
+        url,
+        external,
+        subdomain,
+        sharing_level,
+        healthcheck_url,
+        healthcheck_interval,
+        healthcheck_threshold,
+        health,
+        display_order,
+        hidden,
+        open_in,
+        display_group,
+        tooltip,
+        max_session_duration_seconds,
+        session_idle_timeout_seconds
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+ON CONFLICT (id) DO UPDATE SET
+    display_name = EXCLUDED.display_name,
+    icon = EXCLUDED.icon,
+    command = EXCLUDED.command,
+    url = EXCLUDED.url,
+    external = EXCLUDED.external,
+    subdomain = EXCLUDED.subdomain,
+    sharing_level = EXCLUDED.sharing_level,
+    healthcheck_url = EXCLUDED.healthcheck_url,
+    healthcheck_interval = EXCLUDED.healthcheck_interval,
+    healthcheck_threshold = EXCLUDED.healthcheck_threshold,
+    health = EXCLUDED.health,
+    display_order = EXCLUDED.display_order,
+    hidden = EXCLUDED.hidden,
+    open_in = EXCLUDED.open_in,
+    display_group = EXCLUDED.display_group,
+    agent_id = EXCLUDED.agent_id,
+    slug = EXCLUDED.slug,
+    tooltip = EXCLUDED.tooltip,
+    max_session_duration_seconds = EXCLUDED.max_session_duration_seconds,
+    session_idle_timeout_seconds = EXCLUDED.session_idle_timeout_seconds
+WHERE
+    -- Prevent cross-tenant/cross-workspace agent rebinding (SEC-91).
+    -- App IDs persist across builds of the same workspace, but agent IDs are
+    -- regenerated every build, so compare by the workspace that owns the agent
+    -- rather than by agent_id. Permit unowned apps to be claimed and permit
+    -- same-workspace rebuilds. If an existing app belongs to a workspace, block
+    -- moves to both different workspaces and template import or dry-run agents
+    -- that resolve to no workspace. The conflicting row is then left untouched,
+    -- and the :one query returns no row, which the caller treats as a
+    -- rejection.
+    NOT EXISTS (
+        SELECT 1
+        FROM workspace_agents AS existing_agent
+        INNER JOIN workspace_resources AS existing_resource
+            ON existing_agent.resource_id = existing_resource.id
+        INNER JOIN workspace_builds AS existing_build
+            ON existing_resource.job_id = existing_build.job_id
+        WHERE existing_agent.id = workspace_apps.agent_id
+    )
+    OR EXISTS (
+        SELECT 1
+        FROM workspace_agents AS existing_agent
+        INNER JOIN workspace_resources AS existing_resource
+            ON existing_agent.resource_id = existing_resource.id
+        INNER JOIN workspace_builds AS existing_build
+            ON existing_resource.job_id = existing_build.job_id
+        INNER JOIN workspace_agents AS incoming_agent
+            ON incoming_agent.id = EXCLUDED.agent_id
+        INNER JOIN workspace_resources AS incoming_resource
+            ON incoming_agent.resource_id = incoming_resource.id
+        INNER JOIN workspace_builds AS incoming_build
+            ON incoming_resource.job_id = incoming_build.job_id
+        WHERE
+            existing_agent.id = workspace_apps.agent_id
+            AND existing_build.workspace_id = incoming_build.workspace_id
+    )
+RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip, max_session_duration_seconds, session_idle_timeout_seconds
+`
+
+type UpsertWorkspaceAppParams struct {
+	ID                        uuid.UUID          `db:"id" json:"id"`
+	CreatedAt                 time.Time          `db:"created_at" json:"created_at"`
+	AgentID                   uuid.UUID          `db:"agent_id" json:"agent_id"`
+	Slug                      string             `db:"slug" json:"slug"`
+	DisplayName               string             `db:"display_name" json:"display_name"`
+	Icon                      string             `db:"icon" json:"icon"`
+	Command                   sql.NullString     `db:"command" json:"command"`
+	Url                       sql.NullString     `db:"url" json:"url"`
+	External                  bool               `db:"external" json:"external"`
+	Subdomain                 bool               `db:"subdomain" json:"subdomain"`
+	SharingLevel              AppSharingLevel    `db:"sharing_level" json:"sharing_level"`
+	HealthcheckUrl            string             `db:"healthcheck_url" json:"healthcheck_url"`
+	HealthcheckInterval       int32              `db:"healthcheck_interval" json:"healthcheck_interval"`
+	HealthcheckThreshold      int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
+	Health                    WorkspaceAppHealth `db:"health" json:"health"`
+	DisplayOrder              int32              `db:"display_order" json:"display_order"`
+	Hidden                    bool               `db:"hidden" json:"hidden"`
+	OpenIn                    WorkspaceAppOpenIn `db:"open_in" json:"open_in"`
+	DisplayGroup              sql.NullString     `db:"display_group" json:"display_group"`
+	Tooltip                   string             `db:"tooltip" json:"tooltip"`
+	MaxSessionDurationSeconds int32              `db:"max_session_duration_seconds" json:"max_session_duration_seconds"`
+	SessionIdleTimeoutSeconds int32              `db:"session_idle_timeout_seconds" json:"session_idle_timeout_seconds"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceApp(ctx context.Context, arg UpsertWorkspaceAppParams) (WorkspaceApp, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceApp,
+		arg.ID,
+		arg.CreatedAt,
+		arg.AgentID,
+		arg.Slug,
+		arg.DisplayName,
+		arg.Icon,
+		arg.Command,
+		arg.Url,
+		arg.External,
+		arg.Subdomain,
+		arg.SharingLevel,
+		arg.HealthcheckUrl,
+		arg.HealthcheckInterval,
+		arg.HealthcheckThreshold,
+		arg.Health,
+		arg.DisplayOrder,
+		arg.Hidden,
+		arg.OpenIn,
+		arg.DisplayGroup,
+		arg.Tooltip,
+		arg.MaxSessionDurationSeconds,
+		arg.SessionIdleTimeoutSeconds,
+	)
+	var i WorkspaceApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.AgentID,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Command,
+		&i.Url,
+		&i.HealthcheckUrl,
+		&i.HealthcheckInterval,
+		&i.HealthcheckThreshold,
+		&i.Health,
+		&i.Subdomain,
+		&i.SharingLevel,
+		&i.Slug,
+		&i.External,
+		&i.DisplayOrder,
+		&i.Hidden,
+		&i.OpenIn,
+		&i.DisplayGroup,
+		&i.Tooltip,
+		&i.MaxSessionDurationSeconds,
+		&i.SessionIdleTimeoutSeconds,
+	)
+	return i, err
+}
+
+const getWorkspaceAppShareLinkByID = `-- name: GetWorkspaceAppShareLinkByID :one
 SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted,
-	workspace_builds.id AS workspace_build_id,
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl
+	id, workspace_id, agent_name, app_slug, hashed_secret, created_by, created_at, expires_at, revoked_at
 FROM
-	workspace_agents
-JOIN
-	workspace_resources
-ON
-	workspace_resources.id = workspace_agents.resource_id
-JOIN
-	workspace_builds
-ON
-	workspace_builds.job_id = workspace_resources.job_id
-JOIN
-	provisioner_jobs
-ON
-	provisioner_jobs.id = workspace_builds.job_id
-JOIN
-	workspaces
-ON
-	workspaces.id = workspace_builds.workspace_id
+	workspace_app_share_links
 WHERE
-	workspace_agents.auth_instance_id = $1 :: TEXT
-	AND workspace_agents.deleted = FALSE
-	AND workspace_agents.parent_id IS NULL
-	AND provisioner_jobs.type = 'workspace_build'::provisioner_job_type
-	AND workspaces.deleted = FALSE
-ORDER BY
-	workspace_agents.created_at DESC
+	id = $1
 `
 
-type GetWorkspaceBuildAgentsByInstanceIDRow struct {
-	WorkspaceAgent   WorkspaceAgent `db:"workspace_agent" json:"workspace_agent"`
-	WorkspaceBuildID uuid.UUID      `db:"workspace_build_id" json:"workspace_build_id"`
-	WorkspaceTable   WorkspaceTable `db:"workspace_table" json:"workspace_table"`
+func (q *sqlQuerier) GetWorkspaceAppShareLinkByID(ctx context.Context, id uuid.UUID) (WorkspaceAppShareLink, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAppShareLinkByID, id)
+	var i WorkspaceAppShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.AppSlug,
+		&i.HashedSecret,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) GetWorkspaceBuildAgentsByInstanceID(ctx context.Context, authInstanceID string) ([]GetWorkspaceBuildAgentsByInstanceIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildAgentsByInstanceID, authInstanceID)
+const getWorkspaceAppShareLinksByWorkspaceID = `-- name: GetWorkspaceAppShareLinksByWorkspaceID :many
+SELECT
+	id, workspace_id, agent_name, app_slug, hashed_secret, created_by, created_at, expires_at, revoked_at
+FROM
+	workspace_app_share_links
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetWorkspaceAppShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAppShareLink, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppShareLinksByWorkspaceID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceBuildAgentsByInstanceIDRow
+	var items []WorkspaceAppShareLink
 	for rows.Next() {
-		var i GetWorkspaceBuildAgentsByInstanceIDRow
+		var i WorkspaceAppShareLink
 		if err := rows.Scan(
-			&i.WorkspaceAgent.ID,
-			&i.WorkspaceAgent.CreatedAt,
-			&i.WorkspaceAgent.UpdatedAt,
-			&i.WorkspaceAgent.Name,
-			&i.WorkspaceAgent.FirstConnectedAt,
-			&i.WorkspaceAgent.LastConnectedAt,
-			&i.WorkspaceAgent.DisconnectedAt,
-			&i.WorkspaceAgent.ResourceID,
-			&i.WorkspaceAgent.AuthToken,
-			&i.WorkspaceAgent.AuthInstanceID,
-			&i.WorkspaceAgent.Architecture,
-			&i.WorkspaceAgent.EnvironmentVariables,
-			&i.WorkspaceAgent.OperatingSystem,
-			&i.WorkspaceAgent.InstanceMetadata,
-			&i.WorkspaceAgent.ResourceMetadata,
-			&i.WorkspaceAgent.Directory,
-			&i.WorkspaceAgent.Version,
-			&i.WorkspaceAgent.LastConnectedReplicaID,
-			&i.WorkspaceAgent.ConnectionTimeoutSeconds,
-			&i.WorkspaceAgent.TroubleshootingURL,
-			&i.WorkspaceAgent.MOTDFile,
-			&i.WorkspaceAgent.LifecycleState,
-			&i.WorkspaceAgent.ExpandedDirectory,
-			&i.WorkspaceAgent.LogsLength,
-			&i.WorkspaceAgent.LogsOverflowed,
-			&i.WorkspaceAgent.StartedAt,
-			&i.WorkspaceAgent.ReadyAt,
-			pq.Array(&i.WorkspaceAgent.Subsystems),
-			pq.Array(&i.WorkspaceAgent.DisplayApps),
-			&i.WorkspaceAgent.APIVersion,
-			&i.WorkspaceAgent.DisplayOrder,
-			&i.WorkspaceAgent.ParentID,
-			&i.WorkspaceAgent.APIKeyScope,
-			&i.WorkspaceAgent.Deleted,
-			&i.WorkspaceBuildID,
-			&i.WorkspaceTable.ID,
-			&i.WorkspaceTable.CreatedAt,
-			&i.WorkspaceTable.UpdatedAt,
-			&i.WorkspaceTable.OwnerID,
-			&i.WorkspaceTable.OrganizationID,
-			&i.WorkspaceTable.TemplateID,
-			&i.WorkspaceTable.Deleted,
-			&i.WorkspaceTable.Name,
-			&i.WorkspaceTable.AutostartSchedule,
-			&i.WorkspaceTable.Ttl,
-			&i.WorkspaceTable.LastUsedAt,
-			&i.WorkspaceTable.DormantAt,
-			&i.WorkspaceTable.DeletingAt,
-			&i.WorkspaceTable.AutomaticUpdates,
-			&i.WorkspaceTable.Favorite,
-			&i.WorkspaceTable.NextStartAt,
-			&i.WorkspaceTable.GroupACL,
-			&i.WorkspaceTable.UserACL,
+			&i.ID,
+			&i.WorkspaceID,
+			&i.AgentName,
+			&i.AppSlug,
+			&i.HashedSecret,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -34052,223 +41969,200 @@ func (q *sqlQuerier) GetWorkspaceBuildAgentsByInstanceID(ctx context.Context, au
 	return items, nil
 }
 
-const insertWorkspaceAgent = `-- name: InsertWorkspaceAgent :one
+const insertWorkspaceAppShareLink = `-- name: InsertWorkspaceAppShareLink :one
 INSERT INTO
-	workspace_agents (
+	workspace_app_share_links (
 		id,
-		parent_id,
+		workspace_id,
+		agent_name,
+		app_slug,
+		hashed_secret,
+		created_by,
 		created_at,
-		updated_at,
-		name,
-		resource_id,
-		auth_token,
-		auth_instance_id,
-		architecture,
-		environment_variables,
-		operating_system,
-		directory,
-		instance_metadata,
-		resource_metadata,
-		connection_timeout_seconds,
-		troubleshooting_url,
-		motd_file,
-		display_apps,
-		display_order,
-		api_key_scope
+		expires_at
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version, display_order, parent_id, api_key_scope, deleted
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, workspace_id, agent_name, app_slug, hashed_secret, created_by, created_at, expires_at, revoked_at
 `
 
-type InsertWorkspaceAgentParams struct {
-	ID                       uuid.UUID             `db:"id" json:"id"`
-	ParentID                 uuid.NullUUID         `db:"parent_id" json:"parent_id"`
-	CreatedAt                time.Time             `db:"created_at" json:"created_at"`
-	UpdatedAt                time.Time             `db:"updated_at" json:"updated_at"`
-	Name                     string                `db:"name" json:"name"`
-	ResourceID               uuid.UUID             `db:"resource_id" json:"resource_id"`
-	AuthToken                uuid.UUID             `db:"auth_token" json:"auth_token"`
-	AuthInstanceID           sql.NullString        `db:"auth_instance_id" json:"auth_instance_id"`
-	Architecture             string                `db:"architecture" json:"architecture"`
-	EnvironmentVariables     pqtype.NullRawMessage `db:"environment_variables" json:"environment_variables"`
-	OperatingSystem          string                `db:"operating_system" json:"operating_system"`
-	Directory                string                `db:"directory" json:"directory"`
-	InstanceMetadata         pqtype.NullRawMessage `db:"instance_metadata" json:"instance_metadata"`
-	ResourceMetadata         pqtype.NullRawMessage `db:"resource_metadata" json:"resource_metadata"`
-	ConnectionTimeoutSeconds int32                 `db:"connection_timeout_seconds" json:"connection_timeout_seconds"`
-	TroubleshootingURL       string                `db:"troubleshooting_url" json:"troubleshooting_url"`
-	MOTDFile                 string                `db:"motd_file" json:"motd_file"`
-	DisplayApps              []DisplayApp          `db:"display_apps" json:"display_apps"`
-	DisplayOrder             int32                 `db:"display_order" json:"display_order"`
-	APIKeyScope              AgentKeyScopeEnum     `db:"api_key_scope" json:"api_key_scope"`
+type InsertWorkspaceAppShareLinkParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	WorkspaceID  uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName    string    `db:"agent_name" json:"agent_name"`
+	AppSlug      string    `db:"app_slug" json:"app_slug"`
+	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
+	CreatedBy    uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error) {
-	row := q.db.QueryRowContext(ctx, insertWorkspaceAgent,
+func (q *sqlQuerier) InsertWorkspaceAppShareLink(ctx context.Context, arg InsertWorkspaceAppShareLinkParams) (WorkspaceAppShareLink, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAppShareLink,
 		arg.ID,
-		arg.ParentID,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.AppSlug,
+		arg.HashedSecret,
+		arg.CreatedBy,
 		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.Name,
-		arg.ResourceID,
-		arg.AuthToken,
-		arg.AuthInstanceID,
-		arg.Architecture,
-		arg.EnvironmentVariables,
-		arg.OperatingSystem,
-		arg.Directory,
-		arg.InstanceMetadata,
-		arg.ResourceMetadata,
-		arg.ConnectionTimeoutSeconds,
-		arg.TroubleshootingURL,
-		arg.MOTDFile,
-		pq.Array(arg.DisplayApps),
-		arg.DisplayOrder,
-		arg.APIKeyScope,
+		arg.ExpiresAt,
 	)
-	var i WorkspaceAgent
+	var i WorkspaceAppShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.AppSlug,
+		&i.HashedSecret,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const revokeWorkspaceAppShareLink = `-- name: RevokeWorkspaceAppShareLink :one
+UPDATE
+	workspace_app_share_links
+SET
+	revoked_at = $1
+WHERE
+	id = $2
+	AND revoked_at IS NULL
+RETURNING id, workspace_id, agent_name, app_slug, hashed_secret, created_by, created_at, expires_at, revoked_at
+`
+
+type RevokeWorkspaceAppShareLinkParams struct {
+	RevokedAt sql.NullTime `db:"revoked_at" json:"revoked_at"`
+	ID        uuid.UUID    `db:"id" json:"id"`
+}
+
+// Links that were already revoked are left untouched, so no rows are
+// returned.
+func (q *sqlQuerier) RevokeWorkspaceAppShareLink(ctx context.Context, arg RevokeWorkspaceAppShareLinkParams) (WorkspaceAppShareLink, error) {
+	row := q.db.QueryRowContext(ctx, revokeWorkspaceAppShareLink, arg.RevokedAt, arg.ID)
+	var i WorkspaceAppShareLink
 	err := row.Scan(
 		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.AppSlug,
+		&i.HashedSecret,
+		&i.CreatedBy,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.FirstConnectedAt,
-		&i.LastConnectedAt,
-		&i.DisconnectedAt,
-		&i.ResourceID,
-		&i.AuthToken,
-		&i.AuthInstanceID,
-		&i.Architecture,
-		&i.EnvironmentVariables,
-		&i.OperatingSystem,
-		&i.InstanceMetadata,
-		&i.ResourceMetadata,
-		&i.Directory,
-		&i.Version,
-		&i.LastConnectedReplicaID,
-		&i.ConnectionTimeoutSeconds,
-		&i.TroubleshootingURL,
-		&i.MOTDFile,
-		&i.LifecycleState,
-		&i.ExpandedDirectory,
-		&i.LogsLength,
-		&i.LogsOverflowed,
-		&i.StartedAt,
-		&i.ReadyAt,
-		pq.Array(&i.Subsystems),
-		pq.Array(&i.DisplayApps),
-		&i.APIVersion,
-		&i.DisplayOrder,
-		&i.ParentID,
-		&i.APIKeyScope,
-		&i.Deleted,
+		&i.ExpiresAt,
+		&i.RevokedAt,
 	)
 	return i, err
 }
 
-const insertWorkspaceAgentLogSources = `-- name: InsertWorkspaceAgentLogSources :many
+const insertWorkspaceAppStats = `-- name: InsertWorkspaceAppStats :exec
 INSERT INTO
-		workspace_agent_log_sources (workspace_agent_id, created_at, id, display_name, icon)
-	SELECT
-		$1 :: uuid AS workspace_agent_id,
-		$2 :: timestamptz AS created_at,
-		unnest($3 :: uuid [ ]) AS id,
-		unnest($4 :: VARCHAR(127) [ ]) AS display_name,
-		unnest($5 :: text [ ]) AS icon
-	RETURNING workspace_agent_log_sources.workspace_agent_id, workspace_agent_log_sources.id, workspace_agent_log_sources.created_at, workspace_agent_log_sources.display_name, workspace_agent_log_sources.icon
+	workspace_app_stats (
+		user_id,
+		workspace_id,
+		agent_id,
+		access_method,
+		slug_or_port,
+		session_id,
+		session_started_at,
+		session_ended_at,
+		requests
+	)
+SELECT
+	unnest($1::uuid[]) AS user_id,
+	unnest($2::uuid[]) AS workspace_id,
+	unnest($3::uuid[]) AS agent_id,
+	unnest($4::text[]) AS access_method,
+	unnest($5::text[]) AS slug_or_port,
+	unnest($6::uuid[]) AS session_id,
+	unnest($7::timestamptz[]) AS session_started_at,
+	unnest($8::timestamptz[]) AS session_ended_at,
+	unnest($9::int[]) AS requests
+ON CONFLICT
+	(user_id, agent_id, session_id)
+DO
+	UPDATE SET
+		session_ended_at = EXCLUDED.session_ended_at,
+		requests = EXCLUDED.requests
+	WHERE
+		workspace_app_stats.user_id = EXCLUDED.user_id
+		AND workspace_app_stats.agent_id = EXCLUDED.agent_id
+		AND workspace_app_stats.session_id = EXCLUDED.session_id
+		-- Since stats are updated in place as time progresses, we only
+		-- want to update this row if it's fresh.
+		AND workspace_app_stats.session_ended_at <= EXCLUDED.session_ended_at
+		AND workspace_app_stats.requests <= EXCLUDED.requests
 `
 
-type InsertWorkspaceAgentLogSourcesParams struct {
-	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
-	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
-	ID               []uuid.UUID `db:"id" json:"id"`
-	DisplayName      []string    `db:"display_name" json:"display_name"`
-	Icon             []string    `db:"icon" json:"icon"`
+type InsertWorkspaceAppStatsParams struct {
+	UserID           []uuid.UUID `db:"user_id" json:"user_id"`
+	WorkspaceID      []uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID          []uuid.UUID `db:"agent_id" json:"agent_id"`
+	AccessMethod     []string    `db:"access_method" json:"access_method"`
+	SlugOrPort       []string    `db:"slug_or_port" json:"slug_or_port"`
+	SessionID        []uuid.UUID `db:"session_id" json:"session_id"`
+	SessionStartedAt []time.Time `db:"session_started_at" json:"session_started_at"`
+	SessionEndedAt   []time.Time `db:"session_ended_at" json:"session_ended_at"`
+	Requests         []int32     `db:"requests" json:"requests"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error) {
-	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentLogSources,
-		arg.WorkspaceAgentID,
-		arg.CreatedAt,
-		pq.Array(arg.ID),
-		pq.Array(arg.DisplayName),
-		pq.Array(arg.Icon),
+func (q *sqlQuerier) InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAppStats,
+		pq.Array(arg.UserID),
+		pq.Array(arg.WorkspaceID),
+		pq.Array(arg.AgentID),
+		pq.Array(arg.AccessMethod),
+		pq.Array(arg.SlugOrPort),
+		pq.Array(arg.SessionID),
+		pq.Array(arg.SessionStartedAt),
+		pq.Array(arg.SessionEndedAt),
+		pq.Array(arg.Requests),
 	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []WorkspaceAgentLogSource
-	for rows.Next() {
-		var i WorkspaceAgentLogSource
-		if err := rows.Scan(
-			&i.WorkspaceAgentID,
-			&i.ID,
-			&i.CreatedAt,
-			&i.DisplayName,
-			&i.Icon,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return err
 }
 
-const insertWorkspaceAgentLogs = `-- name: InsertWorkspaceAgentLogs :many
-WITH new_length AS (
-	UPDATE workspace_agents SET
-	logs_length = logs_length + $6 WHERE workspace_agents.id = $1
-)
-INSERT INTO
-		workspace_agent_logs (agent_id, created_at, output, level, log_source_id)
-	SELECT
-		$1 :: uuid AS agent_id,
-		$2 :: timestamptz AS created_at,
-		unnest($3 :: VARCHAR(1024) [ ]) AS output,
-		unnest($4 :: log_level [ ]) AS level,
-		$5 :: uuid AS log_source_id
-	RETURNING workspace_agent_logs.agent_id, workspace_agent_logs.created_at, workspace_agent_logs.output, workspace_agent_logs.id, workspace_agent_logs.level, workspace_agent_logs.log_source_id
+const getWorkspaceBuildAnnotationsByBuildIDs = `-- name: GetWorkspaceBuildAnnotationsByBuildIDs :many
+SELECT
+	workspace_build_annotations.id, workspace_build_annotations.workspace_build_id, workspace_build_annotations.user_id, workspace_build_annotations.note, workspace_build_annotations.created_at,
+	users.username
+FROM
+	workspace_build_annotations
+INNER JOIN
+	users ON users.id = workspace_build_annotations.user_id
+WHERE
+	workspace_build_annotations.workspace_build_id = ANY($1 :: uuid [ ])
+ORDER BY
+	workspace_build_annotations.created_at ASC,
+	workspace_build_annotations.id ASC
 `
 
-type InsertWorkspaceAgentLogsParams struct {
-	AgentID      uuid.UUID  `db:"agent_id" json:"agent_id"`
-	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
-	Output       []string   `db:"output" json:"output"`
-	Level        []LogLevel `db:"level" json:"level"`
-	LogSourceID  uuid.UUID  `db:"log_source_id" json:"log_source_id"`
-	OutputLength int32      `db:"output_length" json:"output_length"`
+type GetWorkspaceBuildAnnotationsByBuildIDsRow struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	Note             string    `db:"note" json:"note"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	Username         string    `db:"username" json:"username"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWorkspaceAgentLogsParams) ([]WorkspaceAgentLog, error) {
-	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentLogs,
-		arg.AgentID,
-		arg.CreatedAt,
-		pq.Array(arg.Output),
-		pq.Array(arg.Level),
-		arg.LogSourceID,
-		arg.OutputLength,
-	)
+func (q *sqlQuerier) GetWorkspaceBuildAnnotationsByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]GetWorkspaceBuildAnnotationsByBuildIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildAnnotationsByBuildIDs, pq.Array(workspaceBuildIds))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAgentLog
+	var items []GetWorkspaceBuildAnnotationsByBuildIDsRow
 	for rows.Next() {
-		var i WorkspaceAgentLog
+		var i GetWorkspaceBuildAnnotationsByBuildIDsRow
 		if err := rows.Scan(
-			&i.AgentID,
-			&i.CreatedAt,
-			&i.Output,
 			&i.ID,
-			&i.Level,
-			&i.LogSourceID,
+			&i.WorkspaceBuildID,
+			&i.UserID,
+			&i.Note,
+			&i.CreatedAt,
+			&i.Username,
 		); err != nil {
 			return nil, err
 		}
@@ -34283,604 +42177,463 @@ func (q *sqlQuerier) InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWor
 	return items, nil
 }
 
-const insertWorkspaceAgentMetadata = `-- name: InsertWorkspaceAgentMetadata :exec
+const insertWorkspaceBuildAnnotation = `-- name: InsertWorkspaceBuildAnnotation :one
 INSERT INTO
-	workspace_agent_metadata (
-		workspace_agent_id,
-		display_name,
-		key,
-		script,
-		timeout,
-		interval,
-		display_order
-	)
-VALUES
-	($1, $2, $3, $4, $5, $6, $7)
-`
-
-type InsertWorkspaceAgentMetadataParams struct {
-	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
-	DisplayName      string    `db:"display_name" json:"display_name"`
-	Key              string    `db:"key" json:"key"`
-	Script           string    `db:"script" json:"script"`
-	Timeout          int64     `db:"timeout" json:"timeout"`
-	Interval         int64     `db:"interval" json:"interval"`
-	DisplayOrder     int32     `db:"display_order" json:"display_order"`
-}
-
-func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentMetadata,
-		arg.WorkspaceAgentID,
-		arg.DisplayName,
-		arg.Key,
-		arg.Script,
-		arg.Timeout,
-		arg.Interval,
-		arg.DisplayOrder,
+	workspace_build_annotations (
+		id,
+		workspace_build_id,
+		user_id,
+		note,
+		created_at
 	)
-	return err
-}
-
-const insertWorkspaceAgentScriptTimings = `-- name: InsertWorkspaceAgentScriptTimings :one
-INSERT INTO
-    workspace_agent_script_timings (
-        script_id,
-        started_at,
-        ended_at,
-        exit_code,
-        stage,
-        status
-    )
 VALUES
-    ($1, $2, $3, $4, $5, $6)
-RETURNING workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at, workspace_agent_script_timings.ended_at, workspace_agent_script_timings.exit_code, workspace_agent_script_timings.stage, workspace_agent_script_timings.status
+	($1, $2, $3, $4, $5)
+RETURNING id, workspace_build_id, user_id, note, created_at
 `
 
-type InsertWorkspaceAgentScriptTimingsParams struct {
-	ScriptID  uuid.UUID                        `db:"script_id" json:"script_id"`
-	StartedAt time.Time                        `db:"started_at" json:"started_at"`
-	EndedAt   time.Time                        `db:"ended_at" json:"ended_at"`
-	ExitCode  int32                            `db:"exit_code" json:"exit_code"`
-	Stage     WorkspaceAgentScriptTimingStage  `db:"stage" json:"stage"`
-	Status    WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
+type InsertWorkspaceBuildAnnotationParams struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	Note             string    `db:"note" json:"note"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg InsertWorkspaceAgentScriptTimingsParams) (WorkspaceAgentScriptTiming, error) {
-	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentScriptTimings,
-		arg.ScriptID,
-		arg.StartedAt,
-		arg.EndedAt,
-		arg.ExitCode,
-		arg.Stage,
-		arg.Status,
+func (q *sqlQuerier) InsertWorkspaceBuildAnnotation(ctx context.Context, arg InsertWorkspaceBuildAnnotationParams) (WorkspaceBuildAnnotation, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceBuildAnnotation,
+		arg.ID,
+		arg.WorkspaceBuildID,
+		arg.UserID,
+		arg.Note,
+		arg.CreatedAt,
 	)
-	var i WorkspaceAgentScriptTiming
+	var i WorkspaceBuildAnnotation
 	err := row.Scan(
-		&i.ScriptID,
-		&i.StartedAt,
-		&i.EndedAt,
-		&i.ExitCode,
-		&i.Stage,
-		&i.Status,
+		&i.ID,
+		&i.WorkspaceBuildID,
+		&i.UserID,
+		&i.Note,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
-const softDeletePriorWorkspaceAgents = `-- name: SoftDeletePriorWorkspaceAgents :exec
-WITH soft_deleted_agents AS (
-    UPDATE workspace_agents
-    SET deleted = TRUE
-    WHERE id IN (
-        SELECT wa.id
-        FROM workspace_agents wa
-        JOIN workspace_resources wr ON wr.id = wa.resource_id
-        JOIN workspace_builds wb ON wb.job_id = wr.job_id
-        WHERE wb.workspace_id = $1
-          AND wb.id <> $2
-          AND wa.deleted = FALSE
-    )
-    RETURNING id
-), purged_context_resources AS (
-    DELETE FROM workspace_agent_context_resources
-    WHERE workspace_agent_id IN (SELECT id FROM soft_deleted_agents)
-)
-DELETE FROM workspace_agent_context_snapshots
-WHERE workspace_agent_id IN (SELECT id FROM soft_deleted_agents)
-`
-
-type SoftDeletePriorWorkspaceAgentsParams struct {
-	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	CurrentBuildID uuid.UUID `db:"current_build_id" json:"current_build_id"`
-}
-
-// Marks agents from all prior builds of this workspace as deleted,
-// preserving only agents belonging to @current_build_id. Called from
-// provisionerdserver when a workspace build completes, after the new
-// build's agents have been inserted, so running agents are not
-// deleted while a build is still queued or provisioning.
-//
-// Agent context rows (workspace_agent_context_snapshots and
-// workspace_agent_context_resources) only describe live agents, and
-// agents are never un-deleted, so they are hard-deleted here instead
-// of accumulating alongside the soft-deleted agent rows.
-func (q *sqlQuerier) SoftDeletePriorWorkspaceAgents(ctx context.Context, arg SoftDeletePriorWorkspaceAgentsParams) error {
-	_, err := q.db.ExecContext(ctx, softDeletePriorWorkspaceAgents, arg.WorkspaceID, arg.CurrentBuildID)
-	return err
-}
-
-const softDeleteWorkspaceAgentsByWorkspaceID = `-- name: SoftDeleteWorkspaceAgentsByWorkspaceID :exec
-WITH soft_deleted_agents AS (
-    UPDATE workspace_agents
-    SET deleted = TRUE
-    WHERE id IN (
-        SELECT wa.id
-        FROM workspace_agents wa
-        JOIN workspace_resources wr ON wr.id = wa.resource_id
-        JOIN workspace_builds wb ON wb.job_id = wr.job_id
-        WHERE wb.workspace_id = $1
-          AND wa.deleted = FALSE
-    )
-    RETURNING id
-), purged_context_resources AS (
-    DELETE FROM workspace_agent_context_resources
-    WHERE workspace_agent_id IN (SELECT id FROM soft_deleted_agents)
-)
-DELETE FROM workspace_agent_context_snapshots
-WHERE workspace_agent_id IN (SELECT id FROM soft_deleted_agents)
-`
-
-// Marks every non-deleted agent belonging to the given workspace as
-// deleted. Called alongside UpdateWorkspaceDeletedByID when a workspace
-// itself is soft-deleted, so the agent instance-identity auth path
-// (which filters on workspace_agents.deleted) doesn't keep seeing
-// orphaned rows.
-//
-// Agent context rows are hard-deleted for the same reason as in
-// SoftDeletePriorWorkspaceAgents.
-func (q *sqlQuerier) SoftDeleteWorkspaceAgentsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, softDeleteWorkspaceAgentsByWorkspaceID, workspaceID)
-	return err
-}
-
-const updateWorkspaceAgentConnectionByID = `-- name: UpdateWorkspaceAgentConnectionByID :exec
-UPDATE
-	workspace_agents
-SET
-	first_connected_at = $2,
-	last_connected_at = $3,
-	last_connected_replica_id = $4,
-	disconnected_at = $5,
-	updated_at = $6
+const getWorkspaceBuildInputsByBuildID = `-- name: GetWorkspaceBuildInputsByBuildID :one
+SELECT
+	workspace_build_id, inputs, created_at
+FROM
+	workspace_build_inputs
 WHERE
-	id = $1
+	workspace_build_id = $1
 `
 
-type UpdateWorkspaceAgentConnectionByIDParams struct {
-	ID                     uuid.UUID     `db:"id" json:"id"`
-	FirstConnectedAt       sql.NullTime  `db:"first_connected_at" json:"first_connected_at"`
-	LastConnectedAt        sql.NullTime  `db:"last_connected_at" json:"last_connected_at"`
-	LastConnectedReplicaID uuid.NullUUID `db:"last_connected_replica_id" json:"last_connected_replica_id"`
-	DisconnectedAt         sql.NullTime  `db:"disconnected_at" json:"disconnected_at"`
-	UpdatedAt              time.Time     `db:"updated_at" json:"updated_at"`
-}
-
-func (q *sqlQuerier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentConnectionByID,
-		arg.ID,
-		arg.FirstConnectedAt,
-		arg.LastConnectedAt,
-		arg.LastConnectedReplicaID,
-		arg.DisconnectedAt,
-		arg.UpdatedAt,
-	)
-	return err
+func (q *sqlQuerier) GetWorkspaceBuildInputsByBuildID(ctx context.Context, workspaceBuildID uuid.UUID) (WorkspaceBuildInput, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildInputsByBuildID, workspaceBuildID)
+	var i WorkspaceBuildInput
+	err := row.Scan(&i.WorkspaceBuildID, &i.Inputs, &i.CreatedAt)
+	return i, err
 }
 
-const updateWorkspaceAgentDirectoryByID = `-- name: UpdateWorkspaceAgentDirectoryByID :exec
-UPDATE
-	workspace_agents
-SET
-	directory = $2, updated_at = $3
-WHERE
-	id = $1
+const insertWorkspaceBuildInputs = `-- name: InsertWorkspaceBuildInputs :exec
+INSERT INTO
+	workspace_build_inputs (workspace_build_id, inputs, created_at)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (workspace_build_id) DO NOTHING
 `
 
-type UpdateWorkspaceAgentDirectoryByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Directory string    `db:"directory" json:"directory"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+type InsertWorkspaceBuildInputsParams struct {
+	WorkspaceBuildID uuid.UUID       `db:"workspace_build_id" json:"workspace_build_id"`
+	Inputs           json.RawMessage `db:"inputs" json:"inputs"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceAgentDirectoryByID(ctx context.Context, arg UpdateWorkspaceAgentDirectoryByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentDirectoryByID, arg.ID, arg.Directory, arg.UpdatedAt)
+// The inputs of a build are immutable, so a build whose job is acquired
+// again keeps the inputs captured first.
+func (q *sqlQuerier) InsertWorkspaceBuildInputs(ctx context.Context, arg InsertWorkspaceBuildInputsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceBuildInputs, arg.WorkspaceBuildID, arg.Inputs, arg.CreatedAt)
 	return err
 }
 
-const updateWorkspaceAgentDisplayAppsByID = `-- name: UpdateWorkspaceAgentDisplayAppsByID :exec
-UPDATE
-	workspace_agents
-SET
-	display_apps = $2, updated_at = $3
-WHERE
-	id = $1
+const deleteOldWorkspaceBuildOrchestrations = `-- name: DeleteOldWorkspaceBuildOrchestrations :execrows
+WITH deletable AS (
+    SELECT
+        id
+    FROM
+        workspace_build_orchestrations
+    WHERE
+        status IN ('completed', 'failed', 'canceled')
+        AND updated_at < $1::timestamptz
+    ORDER BY
+        updated_at ASC
+    LIMIT $2::int
+)
+DELETE FROM workspace_build_orchestrations
+USING deletable
+WHERE workspace_build_orchestrations.id = deletable.id
 `
 
-type UpdateWorkspaceAgentDisplayAppsByIDParams struct {
-	ID          uuid.UUID    `db:"id" json:"id"`
-	DisplayApps []DisplayApp `db:"display_apps" json:"display_apps"`
-	UpdatedAt   time.Time    `db:"updated_at" json:"updated_at"`
+type DeleteOldWorkspaceBuildOrchestrationsParams struct {
+	BeforeTime time.Time `db:"before_time" json:"before_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceAgentDisplayAppsByID(ctx context.Context, arg UpdateWorkspaceAgentDisplayAppsByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentDisplayAppsByID, arg.ID, pq.Array(arg.DisplayApps), arg.UpdatedAt)
-	return err
+func (q *sqlQuerier) DeleteOldWorkspaceBuildOrchestrations(ctx context.Context, arg DeleteOldWorkspaceBuildOrchestrationsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceBuildOrchestrations, arg.BeforeTime, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const updateWorkspaceAgentLifecycleStateByID = `-- name: UpdateWorkspaceAgentLifecycleStateByID :exec
-UPDATE
-	workspace_agents
-SET
-	lifecycle_state = $2,
-	started_at = $3,
-	ready_at = $4
+const getNextPendingWorkspaceBuildOrchestrationForUpdate = `-- name: GetNextPendingWorkspaceBuildOrchestrationForUpdate :one
+SELECT
+    wbo.id, wbo.created_at, wbo.updated_at, wbo.workspace_id, wbo.parent_build_id, wbo.child_build_id, wbo.child_transition, wbo.child_template_version_id, wbo.child_template_version_preset_id, wbo.child_rich_parameter_values, wbo.child_log_level, wbo.child_reason, wbo.attempt_count, wbo.next_retry_after, wbo.status, wbo.error
+FROM
+    workspace_build_orchestrations wbo
+    JOIN workspace_builds wb ON wbo.parent_build_id = wb.id
+    JOIN provisioner_jobs pj ON wb.job_id = pj.id
 WHERE
-	id = $1
+    wbo.status = 'pending'
+    AND (
+        wbo.next_retry_after IS NULL
+        OR wbo.next_retry_after <= NOW()
+    )
+    -- Include all terminal parent states so pending orchestration
+    -- rows are processed and resolved even when no child build should
+    -- be created.
+    AND pj.job_status IN ('succeeded', 'failed', 'canceled')
+ORDER BY
+    wbo.created_at ASC
+LIMIT 1
+FOR UPDATE OF wbo SKIP LOCKED
 `
 
-type UpdateWorkspaceAgentLifecycleStateByIDParams struct {
-	ID             uuid.UUID                    `db:"id" json:"id"`
-	LifecycleState WorkspaceAgentLifecycleState `db:"lifecycle_state" json:"lifecycle_state"`
-	StartedAt      sql.NullTime                 `db:"started_at" json:"started_at"`
-	ReadyAt        sql.NullTime                 `db:"ready_at" json:"ready_at"`
-}
-
-func (q *sqlQuerier) UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentLifecycleStateByID,
-		arg.ID,
-		arg.LifecycleState,
-		arg.StartedAt,
-		arg.ReadyAt,
+// Must be called from within a transaction. The row lock is released
+// when the transaction ends.
+func (q *sqlQuerier) GetNextPendingWorkspaceBuildOrchestrationForUpdate(ctx context.Context) (WorkspaceBuildOrchestration, error) {
+	row := q.db.QueryRowContext(ctx, getNextPendingWorkspaceBuildOrchestrationForUpdate)
+	var i WorkspaceBuildOrchestration
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.ParentBuildID,
+		&i.ChildBuildID,
+		&i.ChildTransition,
+		&i.ChildTemplateVersionID,
+		&i.ChildTemplateVersionPresetID,
+		&i.ChildRichParameterValues,
+		&i.ChildLogLevel,
+		&i.ChildReason,
+		&i.AttemptCount,
+		&i.NextRetryAfter,
+		&i.Status,
+		&i.Error,
 	)
-	return err
-}
-
-const updateWorkspaceAgentLogOverflowByID = `-- name: UpdateWorkspaceAgentLogOverflowByID :exec
-UPDATE
-	workspace_agents
-SET
-	logs_overflowed = $2
-WHERE
-	id = $1
-`
-
-type UpdateWorkspaceAgentLogOverflowByIDParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	LogsOverflowed bool      `db:"logs_overflowed" json:"logs_overflowed"`
-}
-
-func (q *sqlQuerier) UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, arg UpdateWorkspaceAgentLogOverflowByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentLogOverflowByID, arg.ID, arg.LogsOverflowed)
-	return err
+	return i, err
 }
 
-const updateWorkspaceAgentMetadata = `-- name: UpdateWorkspaceAgentMetadata :exec
-WITH metadata AS (
-	SELECT
-		unnest($2::text[]) AS key,
-		unnest($3::text[]) AS value,
-		unnest($4::text[]) AS error,
-		unnest($5::timestamptz[]) AS collected_at
+const insertWorkspaceBuildOrchestration = `-- name: InsertWorkspaceBuildOrchestration :one
+INSERT INTO workspace_build_orchestrations (
+    id,
+    created_at,
+    updated_at,
+    parent_build_id,
+    workspace_id,
+    child_transition,
+    child_template_version_id,
+    child_template_version_preset_id,
+    child_rich_parameter_values,
+    child_log_level,
+    child_reason,
+    status,
+    error
 )
-UPDATE
-	workspace_agent_metadata wam
-SET
-	value = m.value,
-	error = m.error,
-	collected_at = m.collected_at
-FROM
-	metadata m
-WHERE
-	wam.workspace_agent_id = $1
-	AND wam.key = m.key
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    (SELECT workspace_id FROM workspace_builds WHERE id = $4),
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    'pending',
+    NULL
+)
+RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
 `
 
-type UpdateWorkspaceAgentMetadataParams struct {
-	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
-	Key              []string    `db:"key" json:"key"`
-	Value            []string    `db:"value" json:"value"`
-	Error            []string    `db:"error" json:"error"`
-	CollectedAt      []time.Time `db:"collected_at" json:"collected_at"`
+type InsertWorkspaceBuildOrchestrationParams struct {
+	ID                           uuid.UUID           `db:"id" json:"id"`
+	CreatedAt                    time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt                    time.Time           `db:"updated_at" json:"updated_at"`
+	ParentBuildID                uuid.UUID           `db:"parent_build_id" json:"parent_build_id"`
+	ChildTransition              WorkspaceTransition `db:"child_transition" json:"child_transition"`
+	ChildTemplateVersionID       uuid.NullUUID       `db:"child_template_version_id" json:"child_template_version_id"`
+	ChildTemplateVersionPresetID uuid.NullUUID       `db:"child_template_version_preset_id" json:"child_template_version_preset_id"`
+	ChildRichParameterValues     json.RawMessage     `db:"child_rich_parameter_values" json:"child_rich_parameter_values"`
+	ChildLogLevel                string              `db:"child_log_level" json:"child_log_level"`
+	ChildReason                  NullBuildReason     `db:"child_reason" json:"child_reason"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg UpdateWorkspaceAgentMetadataParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentMetadata,
-		arg.WorkspaceAgentID,
-		pq.Array(arg.Key),
-		pq.Array(arg.Value),
-		pq.Array(arg.Error),
-		pq.Array(arg.CollectedAt),
+func (q *sqlQuerier) InsertWorkspaceBuildOrchestration(ctx context.Context, arg InsertWorkspaceBuildOrchestrationParams) (WorkspaceBuildOrchestration, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceBuildOrchestration,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.ParentBuildID,
+		arg.ChildTransition,
+		arg.ChildTemplateVersionID,
+		arg.ChildTemplateVersionPresetID,
+		arg.ChildRichParameterValues,
+		arg.ChildLogLevel,
+		arg.ChildReason,
+	)
+	var i WorkspaceBuildOrchestration
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.ParentBuildID,
+		&i.ChildBuildID,
+		&i.ChildTransition,
+		&i.ChildTemplateVersionID,
+		&i.ChildTemplateVersionPresetID,
+		&i.ChildRichParameterValues,
+		&i.ChildLogLevel,
+		&i.ChildReason,
+		&i.AttemptCount,
+		&i.NextRetryAfter,
+		&i.Status,
+		&i.Error,
 	)
-	return err
+	return i, err
 }
 
-const updateWorkspaceAgentStartupByID = `-- name: UpdateWorkspaceAgentStartupByID :exec
+const updateWorkspaceBuildOrchestrationCanceledByID = `-- name: UpdateWorkspaceBuildOrchestrationCanceledByID :one
 UPDATE
-	workspace_agents
+    workspace_build_orchestrations
 SET
-	version = $2,
-	expanded_directory = $3,
-	subsystems = $4,
-	api_version = $5
+    status = 'canceled',
+    next_retry_after = NULL,
+    error = NULL,
+    updated_at = $1
 WHERE
-	id = $1
+    id = $2
+    AND status = 'pending'
+RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
 `
 
-type UpdateWorkspaceAgentStartupByIDParams struct {
-	ID                uuid.UUID                 `db:"id" json:"id"`
-	Version           string                    `db:"version" json:"version"`
-	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
-	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
-	APIVersion        string                    `db:"api_version" json:"api_version"`
+type UpdateWorkspaceBuildOrchestrationCanceledByIDParams struct {
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
-		arg.ID,
-		arg.Version,
-		arg.ExpandedDirectory,
-		pq.Array(arg.Subsystems),
-		arg.APIVersion,
+func (q *sqlQuerier) UpdateWorkspaceBuildOrchestrationCanceledByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationCanceledByIDParams) (WorkspaceBuildOrchestration, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildOrchestrationCanceledByID, arg.UpdatedAt, arg.ID)
+	var i WorkspaceBuildOrchestration
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.ParentBuildID,
+		&i.ChildBuildID,
+		&i.ChildTransition,
+		&i.ChildTemplateVersionID,
+		&i.ChildTemplateVersionPresetID,
+		&i.ChildRichParameterValues,
+		&i.ChildLogLevel,
+		&i.ChildReason,
+		&i.AttemptCount,
+		&i.NextRetryAfter,
+		&i.Status,
+		&i.Error,
 	)
-	return err
+	return i, err
 }
 
-const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :exec
-DELETE FROM
-	workspace_agent_stats
+const updateWorkspaceBuildOrchestrationCompletedByID = `-- name: UpdateWorkspaceBuildOrchestrationCompletedByID :one
+UPDATE
+    workspace_build_orchestrations
+SET
+    child_build_id = $1,
+    status = 'completed',
+    next_retry_after = NULL,
+    error = NULL,
+    updated_at = $2
 WHERE
-	created_at < (
-		SELECT
-			COALESCE(
-				-- When generating initial template usage stats, all the
-				-- raw agent stats are needed, after that only ~30 mins
-				-- from last rollup is needed. Deployment stats seem to
-				-- use between 15 mins and 1 hour of data. We keep a
-				-- little bit more (1 day) just in case.
-				MAX(start_time) - '1 days'::interval,
-				-- Fall back to ~6 months ago if there are no template
-				-- usage stats so that we don't delete the data before
-				-- it's rolled up.
-				NOW() - '180 days'::interval
-			)
-		FROM
-			template_usage_stats
-	)
-	AND created_at < (
-		-- Delete at most in batches of 4 hours (with this batch size, assuming
-		-- 1 iteration / 10 minutes, we can clear out the previous 6 months of
-		-- data in 7.5 days) whilst keeping the DB load low.
-		SELECT
-			COALESCE(MIN(created_at) + '4 hours'::interval, NOW())
-		FROM
-			workspace_agent_stats
-	)
+    id = $3
+    AND status = 'pending'
+RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
 `
 
-func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats)
-	return err
+type UpdateWorkspaceBuildOrchestrationCompletedByIDParams struct {
+	ChildBuildID uuid.NullUUID `db:"child_build_id" json:"child_build_id"`
+	UpdatedAt    time.Time     `db:"updated_at" json:"updated_at"`
+	ID           uuid.UUID     `db:"id" json:"id"`
 }
 
-const getDeploymentWorkspaceAgentStats = `-- name: GetDeploymentWorkspaceAgentStats :one
-WITH stats AS (
-    SELECT
-        agent_id,
-        created_at,
-        rx_bytes,
-        tx_bytes,
-        connection_median_latency_ms,
-        session_count_vscode,
-        session_count_ssh,
-        session_count_jetbrains,
-        session_count_reconnecting_pty,
-        ROW_NUMBER() OVER (PARTITION BY agent_id ORDER BY created_at DESC) AS rn
-    FROM workspace_agent_stats
-    WHERE created_at > $1
-)
-SELECT
-    coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
-    coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
-	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
-    coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms) FILTER (WHERE connection_median_latency_ms > 0)), -1)::FLOAT AS workspace_connection_latency_50,
-    coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms) FILTER (WHERE connection_median_latency_ms > 0)), -1)::FLOAT AS workspace_connection_latency_95,
-    coalesce(SUM(session_count_vscode) FILTER (WHERE rn = 1), 0)::bigint AS session_count_vscode,
-    coalesce(SUM(session_count_ssh) FILTER (WHERE rn = 1), 0)::bigint AS session_count_ssh,
-    coalesce(SUM(session_count_jetbrains) FILTER (WHERE rn = 1), 0)::bigint AS session_count_jetbrains,
-    coalesce(SUM(session_count_reconnecting_pty) FILTER (WHERE rn = 1), 0)::bigint AS session_count_reconnecting_pty
-FROM stats
+func (q *sqlQuerier) UpdateWorkspaceBuildOrchestrationCompletedByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationCompletedByIDParams) (WorkspaceBuildOrchestration, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildOrchestrationCompletedByID, arg.ChildBuildID, arg.UpdatedAt, arg.ID)
+	var i WorkspaceBuildOrchestration
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.ParentBuildID,
+		&i.ChildBuildID,
+		&i.ChildTransition,
+		&i.ChildTemplateVersionID,
+		&i.ChildTemplateVersionPresetID,
+		&i.ChildRichParameterValues,
+		&i.ChildLogLevel,
+		&i.ChildReason,
+		&i.AttemptCount,
+		&i.NextRetryAfter,
+		&i.Status,
+		&i.Error,
+	)
+	return i, err
+}
+
+const updateWorkspaceBuildOrchestrationFailedByID = `-- name: UpdateWorkspaceBuildOrchestrationFailedByID :one
+UPDATE
+    workspace_build_orchestrations
+SET
+    status = 'failed',
+    next_retry_after = NULL,
+    error = $1,
+    updated_at = $2
+WHERE
+    id = $3
+    AND status = 'pending'
+RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
 `
 
-type GetDeploymentWorkspaceAgentStatsRow struct {
-	WorkspaceRxBytes             int64   `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
-	WorkspaceTxBytes             int64   `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
-	WorkspaceConnectionLatency50 float64 `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
-	WorkspaceConnectionLatency95 float64 `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
-	SessionCountVSCode           int64   `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountSSH              int64   `db:"session_count_ssh" json:"session_count_ssh"`
-	SessionCountJetBrains        int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY  int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+type UpdateWorkspaceBuildOrchestrationFailedByIDParams struct {
+	Error     sql.NullString `db:"error" json:"error"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID      `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentStatsRow, error) {
-	row := q.db.QueryRowContext(ctx, getDeploymentWorkspaceAgentStats, createdAt)
-	var i GetDeploymentWorkspaceAgentStatsRow
+func (q *sqlQuerier) UpdateWorkspaceBuildOrchestrationFailedByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationFailedByIDParams) (WorkspaceBuildOrchestration, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildOrchestrationFailedByID, arg.Error, arg.UpdatedAt, arg.ID)
+	var i WorkspaceBuildOrchestration
 	err := row.Scan(
-		&i.WorkspaceRxBytes,
-		&i.WorkspaceTxBytes,
-		&i.WorkspaceConnectionLatency50,
-		&i.WorkspaceConnectionLatency95,
-		&i.SessionCountVSCode,
-		&i.SessionCountSSH,
-		&i.SessionCountJetBrains,
-		&i.SessionCountReconnectingPTY,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.ParentBuildID,
+		&i.ChildBuildID,
+		&i.ChildTransition,
+		&i.ChildTemplateVersionID,
+		&i.ChildTemplateVersionPresetID,
+		&i.ChildRichParameterValues,
+		&i.ChildLogLevel,
+		&i.ChildReason,
+		&i.AttemptCount,
+		&i.NextRetryAfter,
+		&i.Status,
+		&i.Error,
 	)
 	return i, err
 }
 
-const getDeploymentWorkspaceAgentUsageStats = `-- name: GetDeploymentWorkspaceAgentUsageStats :one
-WITH agent_stats AS (
-	SELECT
-		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
-		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
-		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
-		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
-	 FROM workspace_agent_stats
-	 	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
-		WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
-),
-minute_buckets AS (
-	SELECT
-		agent_id,
-		date_trunc('minute', created_at) AS minute_bucket,
-		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
-		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
-		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
-		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
-	FROM
-		workspace_agent_stats
-	WHERE
-		created_at >= $1
-		AND created_at < date_trunc('minute', now())  -- Exclude current partial minute
-		AND usage = true
-	GROUP BY
-		agent_id,
-		minute_bucket
-),
-latest_buckets AS (
-	SELECT DISTINCT ON (agent_id)
-		agent_id,
-		minute_bucket,
-		session_count_vscode,
-		session_count_jetbrains,
-		session_count_reconnecting_pty,
-		session_count_ssh
-	FROM
-		minute_buckets
-	ORDER BY
-		agent_id,
-		minute_bucket DESC
-),
-latest_agent_stats AS (
-    SELECT
-		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
-		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
-		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
-		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
-    FROM
-        latest_buckets
-)
-SELECT workspace_rx_bytes, workspace_tx_bytes, workspace_connection_latency_50, workspace_connection_latency_95, session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty FROM agent_stats, latest_agent_stats
+const updateWorkspaceBuildOrchestrationRetryByID = `-- name: UpdateWorkspaceBuildOrchestrationRetryByID :one
+UPDATE
+    workspace_build_orchestrations
+SET
+    attempt_count = attempt_count + 1,
+    next_retry_after = CASE
+        WHEN attempt_count + 1 >= $1::int THEN NULL
+        ELSE $2::timestamptz
+    END,
+    status = CASE
+        WHEN attempt_count + 1 >= $1::int THEN 'failed'
+        ELSE status
+    END,
+    error = $3,
+    updated_at = $4
+WHERE
+    id = $5
+    AND status = 'pending'
+RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
 `
 
-type GetDeploymentWorkspaceAgentUsageStatsRow struct {
-	WorkspaceRxBytes             int64   `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
-	WorkspaceTxBytes             int64   `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
-	WorkspaceConnectionLatency50 float64 `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
-	WorkspaceConnectionLatency95 float64 `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
-	SessionCountVSCode           int64   `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountSSH              int64   `db:"session_count_ssh" json:"session_count_ssh"`
-	SessionCountJetBrains        int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY  int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+type UpdateWorkspaceBuildOrchestrationRetryByIDParams struct {
+	MaxAttemptCount int32          `db:"max_attempt_count" json:"max_attempt_count"`
+	NextRetryAfter  time.Time      `db:"next_retry_after" json:"next_retry_after"`
+	Error           sql.NullString `db:"error" json:"error"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+	ID              uuid.UUID      `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) GetDeploymentWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentUsageStatsRow, error) {
-	row := q.db.QueryRowContext(ctx, getDeploymentWorkspaceAgentUsageStats, createdAt)
-	var i GetDeploymentWorkspaceAgentUsageStatsRow
+func (q *sqlQuerier) UpdateWorkspaceBuildOrchestrationRetryByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationRetryByIDParams) (WorkspaceBuildOrchestration, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildOrchestrationRetryByID,
+		arg.MaxAttemptCount,
+		arg.NextRetryAfter,
+		arg.Error,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i WorkspaceBuildOrchestration
 	err := row.Scan(
-		&i.WorkspaceRxBytes,
-		&i.WorkspaceTxBytes,
-		&i.WorkspaceConnectionLatency50,
-		&i.WorkspaceConnectionLatency95,
-		&i.SessionCountVSCode,
-		&i.SessionCountSSH,
-		&i.SessionCountJetBrains,
-		&i.SessionCountReconnectingPTY,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.ParentBuildID,
+		&i.ChildBuildID,
+		&i.ChildTransition,
+		&i.ChildTemplateVersionID,
+		&i.ChildTemplateVersionPresetID,
+		&i.ChildRichParameterValues,
+		&i.ChildLogLevel,
+		&i.ChildReason,
+		&i.AttemptCount,
+		&i.NextRetryAfter,
+		&i.Status,
+		&i.Error,
 	)
 	return i, err
 }
 
-const getWorkspaceAgentStats = `-- name: GetWorkspaceAgentStats :many
-WITH agent_stats AS (
-	SELECT
-		user_id,
-		agent_id,
-		workspace_id,
-		template_id,
-		MIN(created_at)::timestamptz AS aggregated_from,
-		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
-		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
-		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
-		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
-	 FROM workspace_agent_stats
-	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
-	WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
-	GROUP BY user_id, agent_id, workspace_id, template_id
-), latest_agent_stats AS (
-	SELECT
-		a.agent_id,
-		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
-		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
-		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
-		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
-	 FROM (
-		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, usage, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
-		FROM workspace_agent_stats WHERE created_at > $1
-	) AS a WHERE a.rn = 1 GROUP BY a.user_id, a.agent_id, a.workspace_id, a.template_id
-)
-SELECT user_id, agent_stats.agent_id, workspace_id, template_id, aggregated_from, workspace_rx_bytes, workspace_tx_bytes, workspace_connection_latency_50, workspace_connection_latency_95, latest_agent_stats.agent_id, session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty FROM agent_stats JOIN latest_agent_stats ON agent_stats.agent_id = latest_agent_stats.agent_id
+const getSensitiveWorkspaceBuildParameters = `-- name: GetSensitiveWorkspaceBuildParameters :many
+SELECT
+    workspace_build_id, name, value, sensitive, value_key_id
+FROM
+    workspace_build_parameters
+WHERE
+    sensitive = true
 `
 
-type GetWorkspaceAgentStatsRow struct {
-	UserID                       uuid.UUID `db:"user_id" json:"user_id"`
-	AgentID                      uuid.UUID `db:"agent_id" json:"agent_id"`
-	WorkspaceID                  uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	TemplateID                   uuid.UUID `db:"template_id" json:"template_id"`
-	AggregatedFrom               time.Time `db:"aggregated_from" json:"aggregated_from"`
-	WorkspaceRxBytes             int64     `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
-	WorkspaceTxBytes             int64     `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
-	WorkspaceConnectionLatency50 float64   `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
-	WorkspaceConnectionLatency95 float64   `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
-	AgentID_2                    uuid.UUID `db:"agent_id_2" json:"agent_id_2"`
-	SessionCountVSCode           int64     `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountSSH              int64     `db:"session_count_ssh" json:"session_count_ssh"`
-	SessionCountJetBrains        int64     `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY  int64     `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
-}
-
-func (q *sqlQuerier) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStats, createdAt)
+// Used by dbcrypt to encrypt, re-encrypt or decrypt the values of sensitive
+// parameters.
+func (q *sqlQuerier) GetSensitiveWorkspaceBuildParameters(ctx context.Context) ([]WorkspaceBuildParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getSensitiveWorkspaceBuildParameters)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentStatsRow
+	var items []WorkspaceBuildParameter
 	for rows.Next() {
-		var i GetWorkspaceAgentStatsRow
+		var i WorkspaceBuildParameter
 		if err := rows.Scan(
-			&i.UserID,
-			&i.AgentID,
-			&i.WorkspaceID,
-			&i.TemplateID,
-			&i.AggregatedFrom,
-			&i.WorkspaceRxBytes,
-			&i.WorkspaceTxBytes,
-			&i.WorkspaceConnectionLatency50,
-			&i.WorkspaceConnectionLatency95,
-			&i.AgentID_2,
-			&i.SessionCountVSCode,
-			&i.SessionCountSSH,
-			&i.SessionCountJetBrains,
-			&i.SessionCountReconnectingPTY,
+			&i.WorkspaceBuildID,
+			&i.Name,
+			&i.Value,
+			&i.Sensitive,
+			&i.ValueKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -34895,95 +42648,55 @@ func (q *sqlQuerier) GetWorkspaceAgentStats(ctx context.Context, createdAt time.
 	return items, nil
 }
 
-const getWorkspaceAgentStatsAndLabels = `-- name: GetWorkspaceAgentStatsAndLabels :many
-WITH agent_stats AS (
-	SELECT
-		user_id,
-		agent_id,
-		workspace_id,
-		coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
-		coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes
-	 FROM workspace_agent_stats
-		WHERE workspace_agent_stats.created_at > $1
-		GROUP BY user_id, agent_id, workspace_id
-), latest_agent_stats AS (
-	SELECT
-		a.agent_id,
-		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
-		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
-		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
-		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty,
-		coalesce(SUM(connection_count), 0)::bigint AS connection_count,
-		coalesce(MAX(connection_median_latency_ms), 0)::float AS connection_median_latency_ms
-	 FROM (
-		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, usage, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
-		FROM workspace_agent_stats
-		-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
-		WHERE created_at > $1 AND connection_median_latency_ms > 0
-	) AS a
-	WHERE a.rn = 1
-	GROUP BY a.user_id, a.agent_id, a.workspace_id
-)
-SELECT
-	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name, rx_bytes, tx_bytes,
-	session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty,
-	connection_count, connection_median_latency_ms
-FROM
-	agent_stats
-JOIN
-	latest_agent_stats
-ON
-	agent_stats.agent_id = latest_agent_stats.agent_id
-JOIN
-	users
-ON
-	users.id = agent_stats.user_id
-JOIN
-	workspace_agents
-ON
-	workspace_agents.id = agent_stats.agent_id
-JOIN
-	workspaces
-ON
-	workspaces.id = agent_stats.workspace_id
+const getUserWorkspaceBuildParameters = `-- name: GetUserWorkspaceBuildParameters :many
+SELECT name, value
+FROM (
+    SELECT DISTINCT ON (tvp.name)
+        tvp.name,
+        wbp.value,
+        wb.created_at
+    FROM
+        workspace_build_parameters wbp
+    JOIN
+        workspace_builds wb ON wb.id = wbp.workspace_build_id
+    JOIN
+        workspaces w ON w.id = wb.workspace_id
+    JOIN
+        template_version_parameters tvp ON tvp.template_version_id = wb.template_version_id
+    WHERE
+		w.owner_id = $1
+		AND wb.transition = 'start'
+		AND w.template_id = $2
+		AND tvp.ephemeral = false
+		AND tvp.sensitive = false
+		AND tvp.name = wbp.name
+    ORDER BY
+        tvp.name, wb.created_at DESC
+) q1
+ORDER BY created_at DESC, name
+LIMIT 100
 `
 
-type GetWorkspaceAgentStatsAndLabelsRow struct {
-	Username                    string  `db:"username" json:"username"`
-	AgentName                   string  `db:"agent_name" json:"agent_name"`
-	WorkspaceName               string  `db:"workspace_name" json:"workspace_name"`
-	RxBytes                     int64   `db:"rx_bytes" json:"rx_bytes"`
-	TxBytes                     int64   `db:"tx_bytes" json:"tx_bytes"`
-	SessionCountVSCode          int64   `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountSSH             int64   `db:"session_count_ssh" json:"session_count_ssh"`
-	SessionCountJetBrains       int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
-	ConnectionCount             int64   `db:"connection_count" json:"connection_count"`
-	ConnectionMedianLatencyMS   float64 `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+type GetUserWorkspaceBuildParametersParams struct {
+	OwnerID    uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
 }
 
-func (q *sqlQuerier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsAndLabelsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStatsAndLabels, createdAt)
+type GetUserWorkspaceBuildParametersRow struct {
+	Name  string `db:"name" json:"name"`
+	Value string `db:"value" json:"value"`
+}
+
+func (q *sqlQuerier) GetUserWorkspaceBuildParameters(ctx context.Context, arg GetUserWorkspaceBuildParametersParams) ([]GetUserWorkspaceBuildParametersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserWorkspaceBuildParameters, arg.OwnerID, arg.TemplateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentStatsAndLabelsRow
+	var items []GetUserWorkspaceBuildParametersRow
 	for rows.Next() {
-		var i GetWorkspaceAgentStatsAndLabelsRow
-		if err := rows.Scan(
-			&i.Username,
-			&i.AgentName,
-			&i.WorkspaceName,
-			&i.RxBytes,
-			&i.TxBytes,
-			&i.SessionCountVSCode,
-			&i.SessionCountSSH,
-			&i.SessionCountJetBrains,
-			&i.SessionCountReconnectingPTY,
-			&i.ConnectionCount,
-			&i.ConnectionMedianLatencyMS,
-		); err != nil {
+		var i GetUserWorkspaceBuildParametersRow
+		if err := rows.Scan(&i.Name, &i.Value); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -34997,117 +42710,33 @@ func (q *sqlQuerier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, create
 	return items, nil
 }
 
-const getWorkspaceAgentUsageStats = `-- name: GetWorkspaceAgentUsageStats :many
-WITH agent_stats AS (
-	SELECT
-		user_id,
-		agent_id,
-		workspace_id,
-		template_id,
-		MIN(created_at)::timestamptz AS aggregated_from,
-		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
-		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
-		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
-		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
-	FROM workspace_agent_stats
-	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
-	WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
-	GROUP BY user_id, agent_id, workspace_id, template_id
-),
-minute_buckets AS (
-	SELECT
-		agent_id,
-		date_trunc('minute', created_at) AS minute_bucket,
-		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
-		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
-		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
-		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
-	FROM
-		workspace_agent_stats
-	WHERE
-		created_at >= $1
-		AND created_at < date_trunc('minute', now())  -- Exclude current partial minute
-		AND usage = true
-	GROUP BY
-		agent_id,
-		minute_bucket,
-		user_id,
-		agent_id,
-		workspace_id,
-		template_id
-),
-latest_buckets AS (
-	SELECT DISTINCT ON (agent_id)
-		agent_id,
-		session_count_vscode,
-		session_count_ssh,
-		session_count_jetbrains,
-		session_count_reconnecting_pty
-	FROM
-		minute_buckets
-	ORDER BY
-		agent_id,
-		minute_bucket DESC
-)
-SELECT user_id,
-agent_stats.agent_id,
-workspace_id,
-template_id,
-aggregated_from,
-workspace_rx_bytes,
-workspace_tx_bytes,
-workspace_connection_latency_50,
-workspace_connection_latency_95,
-coalesce(latest_buckets.agent_id,agent_stats.agent_id) AS agent_id,
-coalesce(session_count_vscode, 0)::bigint AS session_count_vscode,
-coalesce(session_count_ssh, 0)::bigint AS session_count_ssh,
-coalesce(session_count_jetbrains, 0)::bigint AS session_count_jetbrains,
-coalesce(session_count_reconnecting_pty, 0)::bigint AS session_count_reconnecting_pty
-FROM agent_stats LEFT JOIN latest_buckets ON agent_stats.agent_id = latest_buckets.agent_id
+const getWorkspaceBuildParameterChangesByBuildIDs = `-- name: GetWorkspaceBuildParameterChangesByBuildIDs :many
+SELECT
+    workspace_build_id, name, kind, previous_value, new_value, redacted
+FROM
+    workspace_build_parameter_changes
+WHERE
+    workspace_build_id = ANY($1 :: uuid[])
+ORDER BY
+    workspace_build_id, name
 `
 
-type GetWorkspaceAgentUsageStatsRow struct {
-	UserID                       uuid.UUID `db:"user_id" json:"user_id"`
-	AgentID                      uuid.UUID `db:"agent_id" json:"agent_id"`
-	WorkspaceID                  uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	TemplateID                   uuid.UUID `db:"template_id" json:"template_id"`
-	AggregatedFrom               time.Time `db:"aggregated_from" json:"aggregated_from"`
-	WorkspaceRxBytes             int64     `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
-	WorkspaceTxBytes             int64     `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
-	WorkspaceConnectionLatency50 float64   `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
-	WorkspaceConnectionLatency95 float64   `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
-	AgentID_2                    uuid.UUID `db:"agent_id_2" json:"agent_id_2"`
-	SessionCountVSCode           int64     `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountSSH              int64     `db:"session_count_ssh" json:"session_count_ssh"`
-	SessionCountJetBrains        int64     `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY  int64     `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
-}
-
-// `minute_buckets` could return 0 rows if there are no usage stats since `created_at`.
-func (q *sqlQuerier) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentUsageStatsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentUsageStats, createdAt)
+func (q *sqlQuerier) GetWorkspaceBuildParameterChangesByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]WorkspaceBuildParameterChange, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildParameterChangesByBuildIDs, pq.Array(workspaceBuildIds))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentUsageStatsRow
+	var items []WorkspaceBuildParameterChange
 	for rows.Next() {
-		var i GetWorkspaceAgentUsageStatsRow
+		var i WorkspaceBuildParameterChange
 		if err := rows.Scan(
-			&i.UserID,
-			&i.AgentID,
-			&i.WorkspaceID,
-			&i.TemplateID,
-			&i.AggregatedFrom,
-			&i.WorkspaceRxBytes,
-			&i.WorkspaceTxBytes,
-			&i.WorkspaceConnectionLatency50,
-			&i.WorkspaceConnectionLatency95,
-			&i.AgentID_2,
-			&i.SessionCountVSCode,
-			&i.SessionCountSSH,
-			&i.SessionCountJetBrains,
-			&i.SessionCountReconnectingPTY,
+			&i.WorkspaceBuildID,
+			&i.Name,
+			&i.Kind,
+			&i.PreviousValue,
+			&i.NewValue,
+			&i.Redacted,
 		); err != nil {
 			return nil, err
 		}
@@ -35122,96 +42751,30 @@ func (q *sqlQuerier) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt
 	return items, nil
 }
 
-const getWorkspaceAgentUsageStatsAndLabels = `-- name: GetWorkspaceAgentUsageStatsAndLabels :many
-WITH agent_stats AS (
-	SELECT
-		user_id,
-		agent_id,
-		workspace_id,
-		coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
-		coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes,
-		coalesce(MAX(connection_median_latency_ms), 0)::float AS connection_median_latency_ms
-	FROM workspace_agent_stats
-	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
-	WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
-	GROUP BY user_id, agent_id, workspace_id
-), latest_agent_stats AS (
-	SELECT
-		agent_id,
-		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
-		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
-		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
-		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty,
-		coalesce(SUM(connection_count), 0)::bigint AS connection_count
-	FROM workspace_agent_stats
-	-- We only want the latest stats, but those stats might be
-	-- spread across multiple rows.
-	WHERE usage = true AND created_at > now() - '1 minute'::interval
-	GROUP BY user_id, agent_id, workspace_id
-)
+const getWorkspaceBuildParameters = `-- name: GetWorkspaceBuildParameters :many
 SELECT
-	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name, rx_bytes, tx_bytes,
-	coalesce(session_count_vscode, 0)::bigint AS session_count_vscode,
-	coalesce(session_count_ssh, 0)::bigint AS session_count_ssh,
-	coalesce(session_count_jetbrains, 0)::bigint AS session_count_jetbrains,
-	coalesce(session_count_reconnecting_pty, 0)::bigint AS session_count_reconnecting_pty,
-	coalesce(connection_count, 0)::bigint AS connection_count,
-	connection_median_latency_ms
+    workspace_build_id, name, value, sensitive, value_key_id
 FROM
-	agent_stats
-LEFT JOIN
-	latest_agent_stats
-ON
-	agent_stats.agent_id = latest_agent_stats.agent_id
-JOIN
-	users
-ON
-	users.id = agent_stats.user_id
-JOIN
-	workspace_agents
-ON
-	workspace_agents.id = agent_stats.agent_id
-JOIN
-	workspaces
-ON
-	workspaces.id = agent_stats.workspace_id
+    workspace_build_parameters
+WHERE
+    workspace_build_id = $1
 `
 
-type GetWorkspaceAgentUsageStatsAndLabelsRow struct {
-	Username                    string  `db:"username" json:"username"`
-	AgentName                   string  `db:"agent_name" json:"agent_name"`
-	WorkspaceName               string  `db:"workspace_name" json:"workspace_name"`
-	RxBytes                     int64   `db:"rx_bytes" json:"rx_bytes"`
-	TxBytes                     int64   `db:"tx_bytes" json:"tx_bytes"`
-	SessionCountVSCode          int64   `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountSSH             int64   `db:"session_count_ssh" json:"session_count_ssh"`
-	SessionCountJetBrains       int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
-	ConnectionCount             int64   `db:"connection_count" json:"connection_count"`
-	ConnectionMedianLatencyMS   float64 `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
-}
-
-func (q *sqlQuerier) GetWorkspaceAgentUsageStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentUsageStatsAndLabelsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentUsageStatsAndLabels, createdAt)
+func (q *sqlQuerier) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildParameters, workspaceBuildID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentUsageStatsAndLabelsRow
+	var items []WorkspaceBuildParameter
 	for rows.Next() {
-		var i GetWorkspaceAgentUsageStatsAndLabelsRow
+		var i WorkspaceBuildParameter
 		if err := rows.Scan(
-			&i.Username,
-			&i.AgentName,
-			&i.WorkspaceName,
-			&i.RxBytes,
-			&i.TxBytes,
-			&i.SessionCountVSCode,
-			&i.SessionCountSSH,
-			&i.SessionCountJetBrains,
-			&i.SessionCountReconnectingPTY,
-			&i.ConnectionCount,
-			&i.ConnectionMedianLatencyMS,
+			&i.WorkspaceBuildID,
+			&i.Name,
+			&i.Value,
+			&i.Sensitive,
+			&i.ValueKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -35226,232 +42789,243 @@ func (q *sqlQuerier) GetWorkspaceAgentUsageStatsAndLabels(ctx context.Context, c
 	return items, nil
 }
 
-const insertWorkspaceAgentStats = `-- name: InsertWorkspaceAgentStats :exec
+const insertWorkspaceBuildParameterChanges = `-- name: InsertWorkspaceBuildParameterChanges :exec
 INSERT INTO
-	workspace_agent_stats (
-		id,
-		created_at,
-		user_id,
-		workspace_id,
-		template_id,
-		agent_id,
-		connections_by_proto,
-		connection_count,
-		rx_packets,
-		rx_bytes,
-		tx_packets,
-		tx_bytes,
-		session_count_vscode,
-		session_count_jetbrains,
-		session_count_reconnecting_pty,
-		session_count_ssh,
-		connection_median_latency_ms,
-		usage
-	)
+    workspace_build_parameter_changes (workspace_build_id, name, kind, previous_value, new_value, redacted)
 SELECT
-	unnest($1 :: uuid[]) AS id,
-	unnest($2 :: timestamptz[]) AS created_at,
-	unnest($3 :: uuid[]) AS user_id,
-	unnest($4 :: uuid[]) AS workspace_id,
-	unnest($5 :: uuid[]) AS template_id,
-	unnest($6 :: uuid[]) AS agent_id,
-	jsonb_array_elements($7 :: jsonb) AS connections_by_proto,
-	unnest($8 :: bigint[]) AS connection_count,
-	unnest($9 :: bigint[]) AS rx_packets,
-	unnest($10 :: bigint[]) AS rx_bytes,
-	unnest($11 :: bigint[]) AS tx_packets,
-	unnest($12 :: bigint[]) AS tx_bytes,
-	unnest($13 :: bigint[]) AS session_count_vscode,
-	unnest($14 :: bigint[]) AS session_count_jetbrains,
-	unnest($15 :: bigint[]) AS session_count_reconnecting_pty,
-	unnest($16 :: bigint[]) AS session_count_ssh,
-	unnest($17 :: double precision[]) AS connection_median_latency_ms,
-	unnest($18 :: boolean[]) AS usage
+    $1 :: uuid AS workspace_build_id,
+    unnest($2 :: text[]) AS name,
+    unnest($3 :: workspace_build_parameter_change_kind[]) AS kind,
+    unnest($4 :: text[]) AS previous_value,
+    unnest($5 :: text[]) AS new_value,
+    unnest($6 :: boolean[]) AS redacted
 `
 
-type InsertWorkspaceAgentStatsParams struct {
-	ID                          []uuid.UUID     `db:"id" json:"id"`
-	CreatedAt                   []time.Time     `db:"created_at" json:"created_at"`
-	UserID                      []uuid.UUID     `db:"user_id" json:"user_id"`
-	WorkspaceID                 []uuid.UUID     `db:"workspace_id" json:"workspace_id"`
-	TemplateID                  []uuid.UUID     `db:"template_id" json:"template_id"`
-	AgentID                     []uuid.UUID     `db:"agent_id" json:"agent_id"`
-	ConnectionsByProto          json.RawMessage `db:"connections_by_proto" json:"connections_by_proto"`
-	ConnectionCount             []int64         `db:"connection_count" json:"connection_count"`
-	RxPackets                   []int64         `db:"rx_packets" json:"rx_packets"`
-	RxBytes                     []int64         `db:"rx_bytes" json:"rx_bytes"`
-	TxPackets                   []int64         `db:"tx_packets" json:"tx_packets"`
-	TxBytes                     []int64         `db:"tx_bytes" json:"tx_bytes"`
-	SessionCountVSCode          []int64         `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountJetBrains       []int64         `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY []int64         `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
-	SessionCountSSH             []int64         `db:"session_count_ssh" json:"session_count_ssh"`
-	ConnectionMedianLatencyMS   []float64       `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
-	Usage                       []bool          `db:"usage" json:"usage"`
+type InsertWorkspaceBuildParameterChangesParams struct {
+	WorkspaceBuildID uuid.UUID                           `db:"workspace_build_id" json:"workspace_build_id"`
+	Name             []string                            `db:"name" json:"name"`
+	Kind             []WorkspaceBuildParameterChangeKind `db:"kind" json:"kind"`
+	PreviousValue    []string                            `db:"previous_value" json:"previous_value"`
+	NewValue         []string                            `db:"new_value" json:"new_value"`
+	Redacted         []bool                              `db:"redacted" json:"redacted"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAgentStats(ctx context.Context, arg InsertWorkspaceAgentStatsParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentStats,
-		pq.Array(arg.ID),
-		pq.Array(arg.CreatedAt),
-		pq.Array(arg.UserID),
-		pq.Array(arg.WorkspaceID),
-		pq.Array(arg.TemplateID),
-		pq.Array(arg.AgentID),
-		arg.ConnectionsByProto,
-		pq.Array(arg.ConnectionCount),
-		pq.Array(arg.RxPackets),
-		pq.Array(arg.RxBytes),
-		pq.Array(arg.TxPackets),
-		pq.Array(arg.TxBytes),
-		pq.Array(arg.SessionCountVSCode),
-		pq.Array(arg.SessionCountJetBrains),
-		pq.Array(arg.SessionCountReconnectingPTY),
-		pq.Array(arg.SessionCountSSH),
-		pq.Array(arg.ConnectionMedianLatencyMS),
-		pq.Array(arg.Usage),
+func (q *sqlQuerier) InsertWorkspaceBuildParameterChanges(ctx context.Context, arg InsertWorkspaceBuildParameterChangesParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceBuildParameterChanges,
+		arg.WorkspaceBuildID,
+		pq.Array(arg.Name),
+		pq.Array(arg.Kind),
+		pq.Array(arg.PreviousValue),
+		pq.Array(arg.NewValue),
+		pq.Array(arg.Redacted),
 	)
 	return err
 }
 
-const upsertWorkspaceAppAuditSession = `-- name: UpsertWorkspaceAppAuditSession :one
+const insertWorkspaceBuildParameters = `-- name: InsertWorkspaceBuildParameters :exec
 INSERT INTO
-	workspace_app_audit_sessions (
-		id,
-		agent_id,
-		app_id,
-		user_id,
-		ip,
-		user_agent,
-		slug_or_port,
-		status_code,
-		started_at,
-		updated_at
-	)
-VALUES
-	(
-		$1,
-		$2,
-		$3,
-		$4,
-		$5,
-		$6,
-		$7,
-		$8,
-		$9,
-		$10
+    workspace_build_parameters (workspace_build_id, name, value, sensitive, value_key_id)
+SELECT
+    $1 :: uuid AS workspace_build_id,
+    p.name,
+    p.value,
+    COALESCE(p.sensitive, false),
+    NULLIF(p.value_key_id, '')
+FROM
+    unnest($2 :: text[], $3 :: text[], $4 :: boolean[], $5 :: text[]) AS p(name, value, sensitive, value_key_id)
+RETURNING workspace_build_id, name, value, sensitive, value_key_id
+`
+
+type InsertWorkspaceBuildParametersParams struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	Name             []string  `db:"name" json:"name"`
+	Value            []string  `db:"value" json:"value"`
+	Sensitive        []bool    `db:"sensitive" json:"sensitive"`
+	ValueKeyID       []string  `db:"value_key_id" json:"value_key_id"`
+}
+
+// Sensitive and ValueKeyID may be shorter than Name and Value. Missing
+// entries insert parameters that are not sensitive and not encrypted.
+func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceBuildParameters,
+		arg.WorkspaceBuildID,
+		pq.Array(arg.Name),
+		pq.Array(arg.Value),
+		pq.Array(arg.Sensitive),
+		pq.Array(arg.ValueKeyID),
 	)
-ON CONFLICT
-	(agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code)
-DO
-	UPDATE
-	SET
-		-- ID is used to know if session was reset on upsert.
-		id = CASE
-			WHEN workspace_app_audit_sessions.updated_at > NOW() - ($11::bigint || ' ms')::interval
-			THEN workspace_app_audit_sessions.id
-			ELSE EXCLUDED.id
-		END,
-		started_at = CASE
-			WHEN workspace_app_audit_sessions.updated_at > NOW() - ($11::bigint || ' ms')::interval
-			THEN workspace_app_audit_sessions.started_at
-			ELSE EXCLUDED.started_at
-		END,
-		updated_at = EXCLUDED.updated_at
-RETURNING
-	id = $1 AS new_or_stale
+	return err
+}
+
+const updateEncryptedWorkspaceBuildParameter = `-- name: UpdateEncryptedWorkspaceBuildParameter :exec
+UPDATE
+    workspace_build_parameters
+SET
+    value = $1,
+    value_key_id = $2
+WHERE
+    workspace_build_id = $3
+    AND name = $4
 `
 
-type UpsertWorkspaceAppAuditSessionParams struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	AgentID         uuid.UUID `db:"agent_id" json:"agent_id"`
-	AppID           uuid.UUID `db:"app_id" json:"app_id"`
-	UserID          uuid.UUID `db:"user_id" json:"user_id"`
-	Ip              string    `db:"ip" json:"ip"`
-	UserAgent       string    `db:"user_agent" json:"user_agent"`
-	SlugOrPort      string    `db:"slug_or_port" json:"slug_or_port"`
-	StatusCode      int32     `db:"status_code" json:"status_code"`
-	StartedAt       time.Time `db:"started_at" json:"started_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
-	StaleIntervalMS int64     `db:"stale_interval_ms" json:"stale_interval_ms"`
+type UpdateEncryptedWorkspaceBuildParameterParams struct {
+	Value            string         `db:"value" json:"value"`
+	ValueKeyID       sql.NullString `db:"value_key_id" json:"value_key_id"`
+	WorkspaceBuildID uuid.UUID      `db:"workspace_build_id" json:"workspace_build_id"`
+	Name             string         `db:"name" json:"name"`
 }
 
-// The returned boolean, new_or_stale, can be used to deduce if a new session
-// was started. This means that a new row was inserted (no previous session) or
-// the updated_at is older than stale interval.
-func (q *sqlQuerier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg UpsertWorkspaceAppAuditSessionParams) (bool, error) {
-	row := q.db.QueryRowContext(ctx, upsertWorkspaceAppAuditSession,
-		arg.ID,
-		arg.AgentID,
-		arg.AppID,
-		arg.UserID,
-		arg.Ip,
-		arg.UserAgent,
-		arg.SlugOrPort,
-		arg.StatusCode,
-		arg.StartedAt,
-		arg.UpdatedAt,
-		arg.StaleIntervalMS,
+func (q *sqlQuerier) UpdateEncryptedWorkspaceBuildParameter(ctx context.Context, arg UpdateEncryptedWorkspaceBuildParameterParams) error {
+	_, err := q.db.ExecContext(ctx, updateEncryptedWorkspaceBuildParameter,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.WorkspaceBuildID,
+		arg.Name,
 	)
-	var new_or_stale bool
-	err := row.Scan(&new_or_stale)
-	return new_or_stale, err
+	return err
 }
 
-const getLatestWorkspaceAppStatusByAppID = `-- name: GetLatestWorkspaceAppStatusByAppID :one
-SELECT id, created_at, agent_id, app_id, workspace_id, state, message, uri
-FROM workspace_app_statuses
-WHERE app_id = $1::uuid
-ORDER BY created_at DESC, id DESC
-LIMIT 1
+const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.has_external_agent, wb.notified_autostop_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
+FROM (
+    SELECT
+        workspace_id, MAX(build_number) as max_build_number
+    FROM
+		workspace_build_with_user AS workspace_builds
+    WHERE
+        workspace_id IN (
+			SELECT
+				id
+			FROM
+				workspaces
+			WHERE
+				template_id = $1
+		)
+    GROUP BY
+        workspace_id
+) m
+JOIN
+	workspace_build_with_user AS wb
+	ON m.workspace_id = wb.workspace_id AND m.max_build_number = wb.build_number
+JOIN
+	provisioner_jobs AS pj
+	ON wb.job_id = pj.id
+WHERE
+	wb.transition = 'start'::workspace_transition
+AND
+	pj.completed_at IS NOT NULL
+`
+
+func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveWorkspaceBuildsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceBuild
+	for rows.Next() {
+		var i WorkspaceBuild
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.TemplateVersionID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.InitiatorID,
+			&i.JobID,
+			&i.Deadline,
+			&i.Reason,
+			&i.DailyCost,
+			&i.MaxDeadline,
+			&i.TemplateVersionPresetID,
+			&i.HasAITask,
+			&i.HasExternalAgent,
+			&i.NotifiedAutostopDeadline,
+			&i.InitiatorByAvatarUrl,
+			&i.InitiatorByUsername,
+			&i.InitiatorByName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFailedWorkspaceBuildsByTemplateID = `-- name: GetFailedWorkspaceBuildsByTemplateID :many
+SELECT
+	tv.name AS template_version_name,
+	u.username AS workspace_owner_username,
+	w.name AS workspace_name,
+	w.id AS workspace_id,
+	wb.build_number AS workspace_build_number
+FROM
+	workspace_build_with_user AS wb
+JOIN
+	workspaces AS w
+ON
+	wb.workspace_id = w.id
+JOIN
+    users AS u
+ON
+    w.owner_id = u.id
+JOIN
+	provisioner_jobs AS pj
+ON
+	wb.job_id = pj.id
+JOIN
+	templates AS t
+ON
+	w.template_id = t.id
+JOIN
+	template_versions AS tv
+ON
+	wb.template_version_id = tv.id
+WHERE
+	w.template_id = $1
+	AND wb.created_at >= $2
+	AND pj.completed_at IS NOT NULL
+	AND pj.job_status = 'failed'
+ORDER BY
+	tv.name ASC, wb.build_number DESC
 `
 
-func (q *sqlQuerier) GetLatestWorkspaceAppStatusByAppID(ctx context.Context, appID uuid.UUID) (WorkspaceAppStatus, error) {
-	row := q.db.QueryRowContext(ctx, getLatestWorkspaceAppStatusByAppID, appID)
-	var i WorkspaceAppStatus
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.AgentID,
-		&i.AppID,
-		&i.WorkspaceID,
-		&i.State,
-		&i.Message,
-		&i.Uri,
-	)
-	return i, err
+type GetFailedWorkspaceBuildsByTemplateIDParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Since      time.Time `db:"since" json:"since"`
 }
 
-const getLatestWorkspaceAppStatusesByWorkspaceIDs = `-- name: GetLatestWorkspaceAppStatusesByWorkspaceIDs :many
-SELECT DISTINCT ON (workspace_id)
-  id, created_at, agent_id, app_id, workspace_id, state, message, uri
-FROM workspace_app_statuses
-WHERE workspace_id = ANY($1 :: uuid[])
-ORDER BY workspace_id, created_at DESC, id DESC
-`
+type GetFailedWorkspaceBuildsByTemplateIDRow struct {
+	TemplateVersionName    string    `db:"template_version_name" json:"template_version_name"`
+	WorkspaceOwnerUsername string    `db:"workspace_owner_username" json:"workspace_owner_username"`
+	WorkspaceName          string    `db:"workspace_name" json:"workspace_name"`
+	WorkspaceID            uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildNumber   int32     `db:"workspace_build_number" json:"workspace_build_number"`
+}
 
-// id DESC is a stability tiebreaker, not an insertion-order signal: back-to-back
-// inserts can share a created_at on platforms with coarse time.Now() resolution,
-// and id is a random UUID, so this only guarantees a deterministic pick, not the
-// later row. Callers must not depend on sub-microsecond recency here.
-func (q *sqlQuerier) GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAppStatus, error) {
-	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceAppStatusesByWorkspaceIDs, pq.Array(ids))
+func (q *sqlQuerier) GetFailedWorkspaceBuildsByTemplateID(ctx context.Context, arg GetFailedWorkspaceBuildsByTemplateIDParams) ([]GetFailedWorkspaceBuildsByTemplateIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFailedWorkspaceBuildsByTemplateID, arg.TemplateID, arg.Since)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAppStatus
+	var items []GetFailedWorkspaceBuildsByTemplateIDRow
 	for rows.Next() {
-		var i WorkspaceAppStatus
+		var i GetFailedWorkspaceBuildsByTemplateIDRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.AgentID,
-			&i.AppID,
+			&i.TemplateVersionName,
+			&i.WorkspaceOwnerUsername,
+			&i.WorkspaceName,
 			&i.WorkspaceID,
-			&i.State,
-			&i.Message,
-			&i.Uri,
+			&i.WorkspaceBuildNumber,
 		); err != nil {
 			return nil, err
 		}
@@ -35466,66 +43040,147 @@ func (q *sqlQuerier) GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx context.Con
 	return items, nil
 }
 
-const getWorkspaceAppByAgentIDAndSlug = `-- name: GetWorkspaceAppByAgentIDAndSlug :one
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip FROM workspace_apps WHERE agent_id = $1 AND slug = $2
+const getLatestWorkspaceBuildByWorkspaceID = `-- name: GetLatestWorkspaceBuildByWorkspaceID :one
+SELECT
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+FROM
+	workspace_build_with_user AS workspace_builds
+WHERE
+	workspace_id = $1
+ORDER BY
+    build_number desc
+LIMIT
+	1
 `
 
-type GetWorkspaceAppByAgentIDAndSlugParams struct {
-	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
-	Slug    string    `db:"slug" json:"slug"`
-}
-
-func (q *sqlQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg GetWorkspaceAppByAgentIDAndSlugParams) (WorkspaceApp, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceAppByAgentIDAndSlug, arg.AgentID, arg.Slug)
-	var i WorkspaceApp
+func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getLatestWorkspaceBuildByWorkspaceID, workspaceID)
+	var i WorkspaceBuild
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
-		&i.AgentID,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Command,
-		&i.Url,
-		&i.HealthcheckUrl,
-		&i.HealthcheckInterval,
-		&i.HealthcheckThreshold,
-		&i.Health,
-		&i.Subdomain,
-		&i.SharingLevel,
-		&i.Slug,
-		&i.External,
-		&i.DisplayOrder,
-		&i.Hidden,
-		&i.OpenIn,
-		&i.DisplayGroup,
-		&i.Tooltip,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.TemplateVersionPresetID,
+		&i.HasAITask,
+		&i.HasExternalAgent,
+		&i.NotifiedAutostopDeadline,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
+		&i.InitiatorByName,
 	)
 	return i, err
 }
 
-const getWorkspaceAppStatusesByAppIDs = `-- name: GetWorkspaceAppStatusesByAppIDs :many
-SELECT id, created_at, agent_id, app_id, workspace_id, state, message, uri FROM workspace_app_statuses WHERE app_id = ANY($1 :: uuid [ ])
-ORDER BY created_at DESC, id DESC
+const getLatestWorkspaceBuildWithStatusByWorkspaceID = `-- name: GetLatestWorkspaceBuildWithStatusByWorkspaceID :one
+SELECT
+	workspace_builds.transition, workspace_builds.build_number, provisioner_jobs.job_status,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl, workspaces.expires_at, workspaces.archived_at, workspaces.read_only_at -- Used for dbauthz fetch() checks
+FROM
+	workspace_builds
+INNER JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+INNER JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+WHERE
+	workspace_builds.workspace_id = $1 AND
+	workspaces.deleted = false
+ORDER BY
+	workspace_builds.build_number desc
+	LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetWorkspaceAppStatusesByAppIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAppStatus, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAppStatusesByAppIDs, pq.Array(ids))
+type GetLatestWorkspaceBuildWithStatusByWorkspaceIDRow struct {
+	Transition     WorkspaceTransition  `db:"transition" json:"transition"`
+	BuildNumber    int32                `db:"build_number" json:"build_number"`
+	JobStatus      ProvisionerJobStatus `db:"job_status" json:"job_status"`
+	WorkspaceTable WorkspaceTable       `db:"workspace_table" json:"workspace_table"`
+}
+
+func (q *sqlQuerier) GetLatestWorkspaceBuildWithStatusByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (GetLatestWorkspaceBuildWithStatusByWorkspaceIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getLatestWorkspaceBuildWithStatusByWorkspaceID, workspaceID)
+	var i GetLatestWorkspaceBuildWithStatusByWorkspaceIDRow
+	err := row.Scan(
+		&i.Transition,
+		&i.BuildNumber,
+		&i.JobStatus,
+		&i.WorkspaceTable.ID,
+		&i.WorkspaceTable.CreatedAt,
+		&i.WorkspaceTable.UpdatedAt,
+		&i.WorkspaceTable.OwnerID,
+		&i.WorkspaceTable.OrganizationID,
+		&i.WorkspaceTable.TemplateID,
+		&i.WorkspaceTable.Deleted,
+		&i.WorkspaceTable.Name,
+		&i.WorkspaceTable.AutostartSchedule,
+		&i.WorkspaceTable.Ttl,
+		&i.WorkspaceTable.LastUsedAt,
+		&i.WorkspaceTable.DormantAt,
+		&i.WorkspaceTable.DeletingAt,
+		&i.WorkspaceTable.AutomaticUpdates,
+		&i.WorkspaceTable.Favorite,
+		&i.WorkspaceTable.NextStartAt,
+		&i.WorkspaceTable.GroupACL,
+		&i.WorkspaceTable.UserACL,
+		&i.WorkspaceTable.ExpiresAt,
+		&i.WorkspaceTable.ArchivedAt,
+		&i.WorkspaceTable.ReadOnlyAt,
+	)
+	return i, err
+}
+
+const getLatestWorkspaceBuildsByWorkspaceIDs = `-- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
+SELECT
+	DISTINCT ON (workspace_id)
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+FROM
+	workspace_build_with_user AS workspace_builds
+WHERE
+	workspace_id = ANY($1 :: uuid [ ])
+ORDER BY
+	workspace_id, build_number DESC -- latest first
+`
+
+func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error) {
+	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceBuildsByWorkspaceIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceAppStatus
+	var items []WorkspaceBuild
 	for rows.Next() {
-		var i WorkspaceAppStatus
+		var i WorkspaceBuild
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.AgentID,
-			&i.AppID,
+			&i.UpdatedAt,
 			&i.WorkspaceID,
-			&i.State,
-			&i.Message,
-			&i.Uri,
+			&i.TemplateVersionID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.InitiatorID,
+			&i.JobID,
+			&i.Deadline,
+			&i.Reason,
+			&i.DailyCost,
+			&i.MaxDeadline,
+			&i.TemplateVersionPresetID,
+			&i.HasAITask,
+			&i.HasExternalAgent,
+			&i.NotifiedAutostopDeadline,
+			&i.InitiatorByAvatarUrl,
+			&i.InitiatorByUsername,
+			&i.InitiatorByName,
 		); err != nil {
 			return nil, err
 		}
@@ -35540,40 +43195,278 @@ func (q *sqlQuerier) GetWorkspaceAppStatusesByAppIDs(ctx context.Context, ids []
 	return items, nil
 }
 
-const getWorkspaceAppsByAgentID = `-- name: GetWorkspaceAppsByAgentID :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
+const getWorkspaceBuildByID = `-- name: GetWorkspaceBuildByID :one
+SELECT
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+FROM
+	workspace_build_with_user AS workspace_builds
+WHERE
+	id = $1
+LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsByAgentID, agentID)
+func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByID, id)
+	var i WorkspaceBuild
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.TemplateVersionPresetID,
+		&i.HasAITask,
+		&i.HasExternalAgent,
+		&i.NotifiedAutostopDeadline,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
+		&i.InitiatorByName,
+	)
+	return i, err
+}
+
+const getWorkspaceBuildByJobID = `-- name: GetWorkspaceBuildByJobID :one
+SELECT
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+FROM
+	workspace_build_with_user AS workspace_builds
+WHERE
+	job_id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByJobID, jobID)
+	var i WorkspaceBuild
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.TemplateVersionPresetID,
+		&i.HasAITask,
+		&i.HasExternalAgent,
+		&i.NotifiedAutostopDeadline,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
+		&i.InitiatorByName,
+	)
+	return i, err
+}
+
+const getWorkspaceBuildByWorkspaceIDAndBuildNumber = `-- name: GetWorkspaceBuildByWorkspaceIDAndBuildNumber :one
+SELECT
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+FROM
+	workspace_build_with_user AS workspace_builds
+WHERE
+	workspace_id = $1
+	AND build_number = $2
+`
+
+type GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	BuildNumber int32     `db:"build_number" json:"build_number"`
+}
+
+func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByWorkspaceIDAndBuildNumber, arg.WorkspaceID, arg.BuildNumber)
+	var i WorkspaceBuild
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.TemplateVersionPresetID,
+		&i.HasAITask,
+		&i.HasExternalAgent,
+		&i.NotifiedAutostopDeadline,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
+		&i.InitiatorByName,
+	)
+	return i, err
+}
+
+const getWorkspaceBuildMetricsByResourceID = `-- name: GetWorkspaceBuildMetricsByResourceID :one
+SELECT
+    wb.created_at,
+    wb.transition,
+    t.name AS template_name,
+    o.name AS organization_name,
+    (w.owner_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0') AS is_prebuild,
+    -- All agents must have ready_at set (terminal startup state)
+    COUNT(*) FILTER (WHERE wa.ready_at IS NULL) = 0 AS all_agents_ready,
+    -- Latest ready_at across all agents (for duration calculation)
+	MAX(wa.ready_at)::timestamptz AS last_agent_ready_at,
+    -- Worst status: error > timeout > ready
+    CASE
+        WHEN bool_or(wa.lifecycle_state = 'start_error') THEN 'error'
+        WHEN bool_or(wa.lifecycle_state = 'start_timeout') THEN 'timeout'
+        ELSE 'success'
+    END AS worst_status
+FROM workspace_builds wb
+JOIN workspaces w ON wb.workspace_id = w.id
+JOIN templates t ON w.template_id = t.id
+JOIN organizations o ON t.organization_id = o.id
+JOIN workspace_resources wr ON wr.job_id = wb.job_id
+JOIN workspace_agents wa ON wa.resource_id = wr.id AND wa.parent_id IS NULL
+WHERE wb.job_id = (SELECT job_id FROM workspace_resources WHERE workspace_resources.id = $1)
+GROUP BY wb.created_at, wb.transition, t.name, o.name, w.owner_id
+`
+
+type GetWorkspaceBuildMetricsByResourceIDRow struct {
+	CreatedAt        time.Time           `db:"created_at" json:"created_at"`
+	Transition       WorkspaceTransition `db:"transition" json:"transition"`
+	TemplateName     string              `db:"template_name" json:"template_name"`
+	OrganizationName string              `db:"organization_name" json:"organization_name"`
+	IsPrebuild       bool                `db:"is_prebuild" json:"is_prebuild"`
+	AllAgentsReady   bool                `db:"all_agents_ready" json:"all_agents_ready"`
+	LastAgentReadyAt time.Time           `db:"last_agent_ready_at" json:"last_agent_ready_at"`
+	WorstStatus      string              `db:"worst_status" json:"worst_status"`
+}
+
+// Returns build metadata for e2e workspace build duration metrics.
+// Also checks if all agents are ready and returns the worst status.
+func (q *sqlQuerier) GetWorkspaceBuildMetricsByResourceID(ctx context.Context, id uuid.UUID) (GetWorkspaceBuildMetricsByResourceIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildMetricsByResourceID, id)
+	var i GetWorkspaceBuildMetricsByResourceIDRow
+	err := row.Scan(
+		&i.CreatedAt,
+		&i.Transition,
+		&i.TemplateName,
+		&i.OrganizationName,
+		&i.IsPrebuild,
+		&i.AllAgentsReady,
+		&i.LastAgentReadyAt,
+		&i.WorstStatus,
+	)
+	return i, err
+}
+
+const getWorkspaceBuildProvisionerStateByID = `-- name: GetWorkspaceBuildProvisionerStateByID :one
+SELECT
+	workspace_builds.provisioner_state,
+	templates.id AS template_id,
+	templates.organization_id AS template_organization_id,
+	templates.user_acl,
+	templates.group_acl
+FROM
+	workspace_builds
+INNER JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+INNER JOIN
+	templates ON templates.id = workspaces.template_id
+WHERE
+	workspace_builds.id = $1
+`
+
+type GetWorkspaceBuildProvisionerStateByIDRow struct {
+	ProvisionerState       []byte      `db:"provisioner_state" json:"provisioner_state"`
+	TemplateID             uuid.UUID   `db:"template_id" json:"template_id"`
+	TemplateOrganizationID uuid.UUID   `db:"template_organization_id" json:"template_organization_id"`
+	UserACL                TemplateACL `db:"user_acl" json:"user_acl"`
+	GroupACL               TemplateACL `db:"group_acl" json:"group_acl"`
+}
+
+// Fetches the provisioner state of a workspace build, joined through to the
+// template so that dbauthz can enforce policy.ActionUpdate on the template.
+// Provisioner state contains sensitive Terraform state and should only be
+// accessible to template administrators.
+func (q *sqlQuerier) GetWorkspaceBuildProvisionerStateByID(ctx context.Context, workspaceBuildID uuid.UUID) (GetWorkspaceBuildProvisionerStateByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildProvisionerStateByID, workspaceBuildID)
+	var i GetWorkspaceBuildProvisionerStateByIDRow
+	err := row.Scan(
+		&i.ProvisionerState,
+		&i.TemplateID,
+		&i.TemplateOrganizationID,
+		&i.UserACL,
+		&i.GroupACL,
+	)
+	return i, err
+}
+
+const getWorkspaceBuildStatsByTemplates = `-- name: GetWorkspaceBuildStatsByTemplates :many
+SELECT
+    w.template_id,
+	t.name AS template_name,
+	t.display_name AS template_display_name,
+	t.organization_id AS template_organization_id,
+    COUNT(*) AS total_builds,
+    COUNT(CASE WHEN pj.job_status = 'failed' THEN 1 END) AS failed_builds
+FROM
+    workspace_build_with_user AS wb
+JOIN
+    workspaces AS w ON
+    wb.workspace_id = w.id
+JOIN
+    provisioner_jobs AS pj ON
+    wb.job_id = pj.id
+JOIN
+    templates AS t ON
+	w.template_id = t.id
+WHERE
+    wb.created_at >= $1
+    AND pj.completed_at IS NOT NULL
+GROUP BY
+    w.template_id, template_name, template_display_name, template_organization_id
+ORDER BY
+    template_name ASC
+`
+
+type GetWorkspaceBuildStatsByTemplatesRow struct {
+	TemplateID             uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName           string    `db:"template_name" json:"template_name"`
+	TemplateDisplayName    string    `db:"template_display_name" json:"template_display_name"`
+	TemplateOrganizationID uuid.UUID `db:"template_organization_id" json:"template_organization_id"`
+	TotalBuilds            int64     `db:"total_builds" json:"total_builds"`
+	FailedBuilds           int64     `db:"failed_builds" json:"failed_builds"`
+}
+
+func (q *sqlQuerier) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]GetWorkspaceBuildStatsByTemplatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildStatsByTemplates, since)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceApp
+	var items []GetWorkspaceBuildStatsByTemplatesRow
 	for rows.Next() {
-		var i WorkspaceApp
+		var i GetWorkspaceBuildStatsByTemplatesRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.AgentID,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Command,
-			&i.Url,
-			&i.HealthcheckUrl,
-			&i.HealthcheckInterval,
-			&i.HealthcheckThreshold,
-			&i.Health,
-			&i.Subdomain,
-			&i.SharingLevel,
-			&i.Slug,
-			&i.External,
-			&i.DisplayOrder,
-			&i.Hidden,
-			&i.OpenIn,
-			&i.DisplayGroup,
-			&i.Tooltip,
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.TemplateDisplayName,
+			&i.TemplateOrganizationID,
+			&i.TotalBuilds,
+			&i.FailedBuilds,
 		); err != nil {
 			return nil, err
 		}
@@ -35588,40 +43481,84 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 	return items, nil
 }
 
-const getWorkspaceAppsByAgentIDs = `-- name: GetWorkspaceAppsByAgentIDs :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
+const getWorkspaceBuildsByWorkspaceID = `-- name: GetWorkspaceBuildsByWorkspaceID :many
+SELECT
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+FROM
+	workspace_build_with_user AS workspace_builds
+WHERE
+	workspace_builds.workspace_id = $1
+	AND workspace_builds.created_at > $2
+    AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the build_number field, so select all
+			-- rows after the cursor.
+			build_number > (
+				SELECT
+					build_number
+				FROM
+					workspace_builds
+				WHERE
+					id = $3
+			)
+		)
+		ELSE true
+END
+ORDER BY
+    build_number desc OFFSET $4
+LIMIT
+    -- A null limit means "no limit", so 0 means return all
+    NULLIF($5 :: int, 0)
 `
 
-func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsByAgentIDs, pq.Array(ids))
+type GetWorkspaceBuildsByWorkspaceIDParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Since       time.Time `db:"since" json:"since"`
+	AfterID     uuid.UUID `db:"after_id" json:"after_id"`
+	OffsetOpt   int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt    int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg GetWorkspaceBuildsByWorkspaceIDParams) ([]WorkspaceBuild, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildsByWorkspaceID,
+		arg.WorkspaceID,
+		arg.Since,
+		arg.AfterID,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceApp
+	var items []WorkspaceBuild
 	for rows.Next() {
-		var i WorkspaceApp
+		var i WorkspaceBuild
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.AgentID,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Command,
-			&i.Url,
-			&i.HealthcheckUrl,
-			&i.HealthcheckInterval,
-			&i.HealthcheckThreshold,
-			&i.Health,
-			&i.Subdomain,
-			&i.SharingLevel,
-			&i.Slug,
-			&i.External,
-			&i.DisplayOrder,
-			&i.Hidden,
-			&i.OpenIn,
-			&i.DisplayGroup,
-			&i.Tooltip,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.TemplateVersionID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.InitiatorID,
+			&i.JobID,
+			&i.Deadline,
+			&i.Reason,
+			&i.DailyCost,
+			&i.MaxDeadline,
+			&i.TemplateVersionPresetID,
+			&i.HasAITask,
+			&i.HasExternalAgent,
+			&i.NotifiedAutostopDeadline,
+			&i.InitiatorByAvatarUrl,
+			&i.InitiatorByUsername,
+			&i.InitiatorByName,
 		); err != nil {
 			return nil, err
 		}
@@ -35636,40 +43573,40 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 	return items, nil
 }
 
-const getWorkspaceAppsCreatedAfter = `-- name: GetWorkspaceAppsCreatedAfter :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
+const getWorkspaceBuildsCreatedAfter = `-- name: GetWorkspaceBuildsCreatedAfter :many
+SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name FROM workspace_build_with_user WHERE created_at > $1
 `
 
-func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsCreatedAfter, createdAt)
+func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildsCreatedAfter, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceApp
+	var items []WorkspaceBuild
 	for rows.Next() {
-		var i WorkspaceApp
+		var i WorkspaceBuild
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.AgentID,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Command,
-			&i.Url,
-			&i.HealthcheckUrl,
-			&i.HealthcheckInterval,
-			&i.HealthcheckThreshold,
-			&i.Health,
-			&i.Subdomain,
-			&i.SharingLevel,
-			&i.Slug,
-			&i.External,
-			&i.DisplayOrder,
-			&i.Hidden,
-			&i.OpenIn,
-			&i.DisplayGroup,
-			&i.Tooltip,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.TemplateVersionID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.InitiatorID,
+			&i.JobID,
+			&i.Deadline,
+			&i.Reason,
+			&i.DailyCost,
+			&i.MaxDeadline,
+			&i.TemplateVersionPresetID,
+			&i.HasAITask,
+			&i.HasExternalAgent,
+			&i.NotifiedAutostopDeadline,
+			&i.InitiatorByAvatarUrl,
+			&i.InitiatorByUsername,
+			&i.InitiatorByName,
 		); err != nil {
 			return nil, err
 		}
@@ -35684,690 +43621,338 @@ func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt
 	return items, nil
 }
 
-const insertWorkspaceAppStatus = `-- name: InsertWorkspaceAppStatus :one
-INSERT INTO workspace_app_statuses (id, created_at, workspace_id, agent_id, app_id, state, message, uri)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, created_at, agent_id, app_id, workspace_id, state, message, uri
-`
-
-type InsertWorkspaceAppStatusParams struct {
-	ID          uuid.UUID               `db:"id" json:"id"`
-	CreatedAt   time.Time               `db:"created_at" json:"created_at"`
-	WorkspaceID uuid.UUID               `db:"workspace_id" json:"workspace_id"`
-	AgentID     uuid.UUID               `db:"agent_id" json:"agent_id"`
-	AppID       uuid.UUID               `db:"app_id" json:"app_id"`
-	State       WorkspaceAppStatusState `db:"state" json:"state"`
-	Message     string                  `db:"message" json:"message"`
-	Uri         sql.NullString          `db:"uri" json:"uri"`
-}
-
-func (q *sqlQuerier) InsertWorkspaceAppStatus(ctx context.Context, arg InsertWorkspaceAppStatusParams) (WorkspaceAppStatus, error) {
-	row := q.db.QueryRowContext(ctx, insertWorkspaceAppStatus,
-		arg.ID,
-		arg.CreatedAt,
-		arg.WorkspaceID,
-		arg.AgentID,
-		arg.AppID,
-		arg.State,
-		arg.Message,
-		arg.Uri,
-	)
-	var i WorkspaceAppStatus
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.AgentID,
-		&i.AppID,
-		&i.WorkspaceID,
-		&i.State,
-		&i.Message,
-		&i.Uri,
-	)
-	return i, err
-}
-
-const updateWorkspaceAppHealthByID = `-- name: UpdateWorkspaceAppHealthByID :exec
-UPDATE
-	workspace_apps
-SET
-	health = $2
-WHERE
-	id = $1
-`
-
-type UpdateWorkspaceAppHealthByIDParams struct {
-	ID     uuid.UUID          `db:"id" json:"id"`
-	Health WorkspaceAppHealth `db:"health" json:"health"`
-}
-
-func (q *sqlQuerier) UpdateWorkspaceAppHealthByID(ctx context.Context, arg UpdateWorkspaceAppHealthByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAppHealthByID, arg.ID, arg.Health)
-	return err
-}
-
-const upsertWorkspaceApp = `-- name: UpsertWorkspaceApp :one
-INSERT INTO
-    workspace_apps (
-        id,
-        created_at,
-        agent_id,
-        slug,
-        display_name,
-        icon,
-        command,
-        url,
-        external,
-        subdomain,
-        sharing_level,
-        healthcheck_url,
-        healthcheck_interval,
-        healthcheck_threshold,
-        health,
-        display_order,
-        hidden,
-        open_in,
-        display_group,
-        tooltip
-    )
-VALUES
-    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
-ON CONFLICT (id) DO UPDATE SET
-    display_name = EXCLUDED.display_name,
-    icon = EXCLUDED.icon,
-    command = EXCLUDED.command,
-    url = EXCLUDED.url,
-    external = EXCLUDED.external,
-    subdomain = EXCLUDED.subdomain,
-    sharing_level = EXCLUDED.sharing_level,
-    healthcheck_url = EXCLUDED.healthcheck_url,
-    healthcheck_interval = EXCLUDED.healthcheck_interval,
-    healthcheck_threshold = EXCLUDED.healthcheck_threshold,
-    health = EXCLUDED.health,
-    display_order = EXCLUDED.display_order,
-    hidden = EXCLUDED.hidden,
-    open_in = EXCLUDED.open_in,
-    display_group = EXCLUDED.display_group,
-    agent_id = EXCLUDED.agent_id,
-    slug = EXCLUDED.slug,
-    tooltip = EXCLUDED.tooltip
-WHERE
-    -- Prevent cross-tenant/cross-workspace agent rebinding (SEC-91).
-    -- App IDs persist across builds of the same workspace, but agent IDs are
-    -- regenerated every build, so compare by the workspace that owns the agent
-    -- rather than by agent_id. Permit unowned apps to be claimed and permit
-    -- same-workspace rebuilds. If an existing app belongs to a workspace, block
-    -- moves to both different workspaces and template import or dry-run agents
-    -- that resolve to no workspace. The conflicting row is then left untouched,
-    -- and the :one query returns no row, which the caller treats as a
-    -- rejection.
-    NOT EXISTS (
-        SELECT 1
-        FROM workspace_agents AS existing_agent
-        INNER JOIN workspace_resources AS existing_resource
-            ON existing_agent.resource_id = existing_resource.id
-        INNER JOIN workspace_builds AS existing_build
-            ON existing_resource.job_id = existing_build.job_id
-        WHERE existing_agent.id = workspace_apps.agent_id
-    )
-    OR EXISTS (
-        SELECT 1
-        FROM workspace_agents AS existing_agent
-        INNER JOIN workspace_resources AS existing_resource
-            ON existing_agent.resource_id = existing_resource.id
-        INNER JOIN workspace_builds AS existing_build
-            ON existing_resource.job_id = existing_build.job_id
-        INNER JOIN workspace_agents AS incoming_agent
-            ON incoming_agent.id = EXCLUDED.agent_id
-        INNER JOIN workspace_resources AS incoming_resource
-            ON incoming_agent.resource_id = incoming_resource.id
-        INNER JOIN workspace_builds AS incoming_build
-            ON incoming_resource.job_id = incoming_build.job_id
-        WHERE
-            existing_agent.id = workspace_apps.agent_id
-            AND existing_build.workspace_id = incoming_build.workspace_id
-    )
-RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden, open_in, display_group, tooltip
-`
-
-type UpsertWorkspaceAppParams struct {
-	ID                   uuid.UUID          `db:"id" json:"id"`
-	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
-	AgentID              uuid.UUID          `db:"agent_id" json:"agent_id"`
-	Slug                 string             `db:"slug" json:"slug"`
-	DisplayName          string             `db:"display_name" json:"display_name"`
-	Icon                 string             `db:"icon" json:"icon"`
-	Command              sql.NullString     `db:"command" json:"command"`
-	Url                  sql.NullString     `db:"url" json:"url"`
-	External             bool               `db:"external" json:"external"`
-	Subdomain            bool               `db:"subdomain" json:"subdomain"`
-	SharingLevel         AppSharingLevel    `db:"sharing_level" json:"sharing_level"`
-	HealthcheckUrl       string             `db:"healthcheck_url" json:"healthcheck_url"`
-	HealthcheckInterval  int32              `db:"healthcheck_interval" json:"healthcheck_interval"`
-	HealthcheckThreshold int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
-	Health               WorkspaceAppHealth `db:"health" json:"health"`
-	DisplayOrder         int32              `db:"display_order" json:"display_order"`
-	Hidden               bool               `db:"hidden" json:"hidden"`
-	OpenIn               WorkspaceAppOpenIn `db:"open_in" json:"open_in"`
-	DisplayGroup         sql.NullString     `db:"display_group" json:"display_group"`
-	Tooltip              string             `db:"tooltip" json:"tooltip"`
-}
-
-func (q *sqlQuerier) UpsertWorkspaceApp(ctx context.Context, arg UpsertWorkspaceAppParams) (WorkspaceApp, error) {
-	row := q.db.QueryRowContext(ctx, upsertWorkspaceApp,
-		arg.ID,
-		arg.CreatedAt,
-		arg.AgentID,
-		arg.Slug,
-		arg.DisplayName,
-		arg.Icon,
-		arg.Command,
-		arg.Url,
-		arg.External,
-		arg.Subdomain,
-		arg.SharingLevel,
-		arg.HealthcheckUrl,
-		arg.HealthcheckInterval,
-		arg.HealthcheckThreshold,
-		arg.Health,
-		arg.DisplayOrder,
-		arg.Hidden,
-		arg.OpenIn,
-		arg.DisplayGroup,
-		arg.Tooltip,
-	)
-	var i WorkspaceApp
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.AgentID,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Command,
-		&i.Url,
-		&i.HealthcheckUrl,
-		&i.HealthcheckInterval,
-		&i.HealthcheckThreshold,
-		&i.Health,
-		&i.Subdomain,
-		&i.SharingLevel,
-		&i.Slug,
-		&i.External,
-		&i.DisplayOrder,
-		&i.Hidden,
-		&i.OpenIn,
-		&i.DisplayGroup,
-		&i.Tooltip,
-	)
-	return i, err
-}
-
-const insertWorkspaceAppStats = `-- name: InsertWorkspaceAppStats :exec
+const insertWorkspaceBuild = `-- name: InsertWorkspaceBuild :exec
 INSERT INTO
-	workspace_app_stats (
-		user_id,
+	workspace_builds (
+		id,
+		created_at,
+		updated_at,
 		workspace_id,
-		agent_id,
-		access_method,
-		slug_or_port,
-		session_id,
-		session_started_at,
-		session_ended_at,
-		requests
-	)
-SELECT
-	unnest($1::uuid[]) AS user_id,
-	unnest($2::uuid[]) AS workspace_id,
-	unnest($3::uuid[]) AS agent_id,
-	unnest($4::text[]) AS access_method,
-	unnest($5::text[]) AS slug_or_port,
-	unnest($6::uuid[]) AS session_id,
-	unnest($7::timestamptz[]) AS session_started_at,
-	unnest($8::timestamptz[]) AS session_ended_at,
-	unnest($9::int[]) AS requests
-ON CONFLICT
-	(user_id, agent_id, session_id)
-DO
-	UPDATE SET
-		session_ended_at = EXCLUDED.session_ended_at,
-		requests = EXCLUDED.requests
-	WHERE
-		workspace_app_stats.user_id = EXCLUDED.user_id
-		AND workspace_app_stats.agent_id = EXCLUDED.agent_id
-		AND workspace_app_stats.session_id = EXCLUDED.session_id
-		-- Since stats are updated in place as time progresses, we only
-		-- want to update this row if it's fresh.
-		AND workspace_app_stats.session_ended_at <= EXCLUDED.session_ended_at
-		AND workspace_app_stats.requests <= EXCLUDED.requests
-`
-
-type InsertWorkspaceAppStatsParams struct {
-	UserID           []uuid.UUID `db:"user_id" json:"user_id"`
-	WorkspaceID      []uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	AgentID          []uuid.UUID `db:"agent_id" json:"agent_id"`
-	AccessMethod     []string    `db:"access_method" json:"access_method"`
-	SlugOrPort       []string    `db:"slug_or_port" json:"slug_or_port"`
-	SessionID        []uuid.UUID `db:"session_id" json:"session_id"`
-	SessionStartedAt []time.Time `db:"session_started_at" json:"session_started_at"`
-	SessionEndedAt   []time.Time `db:"session_ended_at" json:"session_ended_at"`
-	Requests         []int32     `db:"requests" json:"requests"`
-}
-
-func (q *sqlQuerier) InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceAppStats,
-		pq.Array(arg.UserID),
-		pq.Array(arg.WorkspaceID),
-		pq.Array(arg.AgentID),
-		pq.Array(arg.AccessMethod),
-		pq.Array(arg.SlugOrPort),
-		pq.Array(arg.SessionID),
-		pq.Array(arg.SessionStartedAt),
-		pq.Array(arg.SessionEndedAt),
-		pq.Array(arg.Requests),
-	)
-	return err
-}
-
-const deleteOldWorkspaceBuildOrchestrations = `-- name: DeleteOldWorkspaceBuildOrchestrations :execrows
-WITH deletable AS (
-    SELECT
-        id
-    FROM
-        workspace_build_orchestrations
-    WHERE
-        status IN ('completed', 'failed', 'canceled')
-        AND updated_at < $1::timestamptz
-    ORDER BY
-        updated_at ASC
-    LIMIT $2::int
-)
-DELETE FROM workspace_build_orchestrations
-USING deletable
-WHERE workspace_build_orchestrations.id = deletable.id
-`
-
-type DeleteOldWorkspaceBuildOrchestrationsParams struct {
-	BeforeTime time.Time `db:"before_time" json:"before_time"`
-	LimitCount int32     `db:"limit_count" json:"limit_count"`
-}
-
-func (q *sqlQuerier) DeleteOldWorkspaceBuildOrchestrations(ctx context.Context, arg DeleteOldWorkspaceBuildOrchestrationsParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceBuildOrchestrations, arg.BeforeTime, arg.LimitCount)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
-}
-
-const getNextPendingWorkspaceBuildOrchestrationForUpdate = `-- name: GetNextPendingWorkspaceBuildOrchestrationForUpdate :one
-SELECT
-    wbo.id, wbo.created_at, wbo.updated_at, wbo.workspace_id, wbo.parent_build_id, wbo.child_build_id, wbo.child_transition, wbo.child_template_version_id, wbo.child_template_version_preset_id, wbo.child_rich_parameter_values, wbo.child_log_level, wbo.child_reason, wbo.attempt_count, wbo.next_retry_after, wbo.status, wbo.error
-FROM
-    workspace_build_orchestrations wbo
-    JOIN workspace_builds wb ON wbo.parent_build_id = wb.id
-    JOIN provisioner_jobs pj ON wb.job_id = pj.id
-WHERE
-    wbo.status = 'pending'
-    AND (
-        wbo.next_retry_after IS NULL
-        OR wbo.next_retry_after <= NOW()
-    )
-    -- Include all terminal parent states so pending orchestration
-    -- rows are processed and resolved even when no child build should
-    -- be created.
-    AND pj.job_status IN ('succeeded', 'failed', 'canceled')
-ORDER BY
-    wbo.created_at ASC
-LIMIT 1
-FOR UPDATE OF wbo SKIP LOCKED
-`
-
-// Must be called from within a transaction. The row lock is released
-// when the transaction ends.
-func (q *sqlQuerier) GetNextPendingWorkspaceBuildOrchestrationForUpdate(ctx context.Context) (WorkspaceBuildOrchestration, error) {
-	row := q.db.QueryRowContext(ctx, getNextPendingWorkspaceBuildOrchestrationForUpdate)
-	var i WorkspaceBuildOrchestration
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.ParentBuildID,
-		&i.ChildBuildID,
-		&i.ChildTransition,
-		&i.ChildTemplateVersionID,
-		&i.ChildTemplateVersionPresetID,
-		&i.ChildRichParameterValues,
-		&i.ChildLogLevel,
-		&i.ChildReason,
-		&i.AttemptCount,
-		&i.NextRetryAfter,
-		&i.Status,
-		&i.Error,
-	)
-	return i, err
-}
-
-const insertWorkspaceBuildOrchestration = `-- name: InsertWorkspaceBuildOrchestration :one
-INSERT INTO workspace_build_orchestrations (
-    id,
-    created_at,
-    updated_at,
-    parent_build_id,
-    workspace_id,
-    child_transition,
-    child_template_version_id,
-    child_template_version_preset_id,
-    child_rich_parameter_values,
-    child_log_level,
-    child_reason,
-    status,
-    error
-)
-VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    (SELECT workspace_id FROM workspace_builds WHERE id = $4),
-    $5,
-    $6,
-    $7,
-    $8,
-    $9,
-    $10,
-    'pending',
-    NULL
-)
-RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
+		template_version_id,
+		"build_number",
+		transition,
+		initiator_id,
+		job_id,
+		provisioner_state,
+		deadline,
+		max_deadline,
+		reason,
+		template_version_preset_id
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 `
 
-type InsertWorkspaceBuildOrchestrationParams struct {
-	ID                           uuid.UUID           `db:"id" json:"id"`
-	CreatedAt                    time.Time           `db:"created_at" json:"created_at"`
-	UpdatedAt                    time.Time           `db:"updated_at" json:"updated_at"`
-	ParentBuildID                uuid.UUID           `db:"parent_build_id" json:"parent_build_id"`
-	ChildTransition              WorkspaceTransition `db:"child_transition" json:"child_transition"`
-	ChildTemplateVersionID       uuid.NullUUID       `db:"child_template_version_id" json:"child_template_version_id"`
-	ChildTemplateVersionPresetID uuid.NullUUID       `db:"child_template_version_preset_id" json:"child_template_version_preset_id"`
-	ChildRichParameterValues     json.RawMessage     `db:"child_rich_parameter_values" json:"child_rich_parameter_values"`
-	ChildLogLevel                string              `db:"child_log_level" json:"child_log_level"`
-	ChildReason                  NullBuildReason     `db:"child_reason" json:"child_reason"`
+type InsertWorkspaceBuildParams struct {
+	ID                      uuid.UUID           `db:"id" json:"id"`
+	CreatedAt               time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time           `db:"updated_at" json:"updated_at"`
+	WorkspaceID             uuid.UUID           `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionID       uuid.UUID           `db:"template_version_id" json:"template_version_id"`
+	BuildNumber             int32               `db:"build_number" json:"build_number"`
+	Transition              WorkspaceTransition `db:"transition" json:"transition"`
+	InitiatorID             uuid.UUID           `db:"initiator_id" json:"initiator_id"`
+	JobID                   uuid.UUID           `db:"job_id" json:"job_id"`
+	ProvisionerState        []byte              `db:"provisioner_state" json:"provisioner_state"`
+	Deadline                time.Time           `db:"deadline" json:"deadline"`
+	MaxDeadline             time.Time           `db:"max_deadline" json:"max_deadline"`
+	Reason                  BuildReason         `db:"reason" json:"reason"`
+	TemplateVersionPresetID uuid.NullUUID       `db:"template_version_preset_id" json:"template_version_preset_id"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceBuildOrchestration(ctx context.Context, arg InsertWorkspaceBuildOrchestrationParams) (WorkspaceBuildOrchestration, error) {
-	row := q.db.QueryRowContext(ctx, insertWorkspaceBuildOrchestration,
+func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceBuild,
 		arg.ID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.ParentBuildID,
-		arg.ChildTransition,
-		arg.ChildTemplateVersionID,
-		arg.ChildTemplateVersionPresetID,
-		arg.ChildRichParameterValues,
-		arg.ChildLogLevel,
-		arg.ChildReason,
-	)
-	var i WorkspaceBuildOrchestration
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.ParentBuildID,
-		&i.ChildBuildID,
-		&i.ChildTransition,
-		&i.ChildTemplateVersionID,
-		&i.ChildTemplateVersionPresetID,
-		&i.ChildRichParameterValues,
-		&i.ChildLogLevel,
-		&i.ChildReason,
-		&i.AttemptCount,
-		&i.NextRetryAfter,
-		&i.Status,
-		&i.Error,
+		arg.WorkspaceID,
+		arg.TemplateVersionID,
+		arg.BuildNumber,
+		arg.Transition,
+		arg.InitiatorID,
+		arg.JobID,
+		arg.ProvisionerState,
+		arg.Deadline,
+		arg.MaxDeadline,
+		arg.Reason,
+		arg.TemplateVersionPresetID,
 	)
-	return i, err
+	return err
 }
 
-const updateWorkspaceBuildOrchestrationCanceledByID = `-- name: UpdateWorkspaceBuildOrchestrationCanceledByID :one
+const updateWorkspaceBuildCostByID = `-- name: UpdateWorkspaceBuildCostByID :exec
 UPDATE
-    workspace_build_orchestrations
+	workspace_builds
 SET
-    status = 'canceled',
-    next_retry_after = NULL,
-    error = NULL,
-    updated_at = $1
+	daily_cost = $2
 WHERE
-    id = $2
-    AND status = 'pending'
-RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
+	id = $1
 `
 
-type UpdateWorkspaceBuildOrchestrationCanceledByIDParams struct {
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+type UpdateWorkspaceBuildCostByIDParams struct {
 	ID        uuid.UUID `db:"id" json:"id"`
+	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildOrchestrationCanceledByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationCanceledByIDParams) (WorkspaceBuildOrchestration, error) {
-	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildOrchestrationCanceledByID, arg.UpdatedAt, arg.ID)
-	var i WorkspaceBuildOrchestration
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.ParentBuildID,
-		&i.ChildBuildID,
-		&i.ChildTransition,
-		&i.ChildTemplateVersionID,
-		&i.ChildTemplateVersionPresetID,
-		&i.ChildRichParameterValues,
-		&i.ChildLogLevel,
-		&i.ChildReason,
-		&i.AttemptCount,
-		&i.NextRetryAfter,
-		&i.Status,
-		&i.Error,
-	)
-	return i, err
+func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
+	return err
 }
 
-const updateWorkspaceBuildOrchestrationCompletedByID = `-- name: UpdateWorkspaceBuildOrchestrationCompletedByID :one
+const updateWorkspaceBuildDeadlineByID = `-- name: UpdateWorkspaceBuildDeadlineByID :exec
 UPDATE
-    workspace_build_orchestrations
+	workspace_builds
 SET
-    child_build_id = $1,
-    status = 'completed',
-    next_retry_after = NULL,
-    error = NULL,
-    updated_at = $2
+	deadline = $1::timestamptz,
+	max_deadline = $2::timestamptz,
+	updated_at = $3::timestamptz
+FROM
+	workspaces
 WHERE
-    id = $3
-    AND status = 'pending'
-RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
+	workspace_builds.id = $4::uuid
+	AND workspace_builds.workspace_id = workspaces.id
+	-- Prebuilt workspaces (identified by having the prebuilds system user as owner_id)
+	-- are managed by the reconciliation loop, not the lifecycle executor which handles
+	-- deadline and max_deadline
+	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
 `
 
-type UpdateWorkspaceBuildOrchestrationCompletedByIDParams struct {
-	ChildBuildID uuid.NullUUID `db:"child_build_id" json:"child_build_id"`
-	UpdatedAt    time.Time     `db:"updated_at" json:"updated_at"`
-	ID           uuid.UUID     `db:"id" json:"id"`
+type UpdateWorkspaceBuildDeadlineByIDParams struct {
+	Deadline    time.Time `db:"deadline" json:"deadline"`
+	MaxDeadline time.Time `db:"max_deadline" json:"max_deadline"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID          uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildOrchestrationCompletedByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationCompletedByIDParams) (WorkspaceBuildOrchestration, error) {
-	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildOrchestrationCompletedByID, arg.ChildBuildID, arg.UpdatedAt, arg.ID)
-	var i WorkspaceBuildOrchestration
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.ParentBuildID,
-		&i.ChildBuildID,
-		&i.ChildTransition,
-		&i.ChildTemplateVersionID,
-		&i.ChildTemplateVersionPresetID,
-		&i.ChildRichParameterValues,
-		&i.ChildLogLevel,
-		&i.ChildReason,
-		&i.AttemptCount,
-		&i.NextRetryAfter,
-		&i.Status,
-		&i.Error,
+func (q *sqlQuerier) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildDeadlineByID,
+		arg.Deadline,
+		arg.MaxDeadline,
+		arg.UpdatedAt,
+		arg.ID,
 	)
-	return i, err
+	return err
 }
 
-const updateWorkspaceBuildOrchestrationFailedByID = `-- name: UpdateWorkspaceBuildOrchestrationFailedByID :one
+const updateWorkspaceBuildFlagsByID = `-- name: UpdateWorkspaceBuildFlagsByID :exec
 UPDATE
-    workspace_build_orchestrations
+	workspace_builds
 SET
-    status = 'failed',
-    next_retry_after = NULL,
-    error = $1,
-    updated_at = $2
-WHERE
-    id = $3
-    AND status = 'pending'
-RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
+	has_ai_task = $1,
+	has_external_agent = $2,
+	updated_at = $3::timestamptz
+WHERE id = $4::uuid
 `
 
-type UpdateWorkspaceBuildOrchestrationFailedByIDParams struct {
-	Error     sql.NullString `db:"error" json:"error"`
-	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
-	ID        uuid.UUID      `db:"id" json:"id"`
+type UpdateWorkspaceBuildFlagsByIDParams struct {
+	HasAITask        sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
+	HasExternalAgent sql.NullBool `db:"has_external_agent" json:"has_external_agent"`
+	UpdatedAt        time.Time    `db:"updated_at" json:"updated_at"`
+	ID               uuid.UUID    `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildOrchestrationFailedByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationFailedByIDParams) (WorkspaceBuildOrchestration, error) {
-	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildOrchestrationFailedByID, arg.Error, arg.UpdatedAt, arg.ID)
-	var i WorkspaceBuildOrchestration
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.ParentBuildID,
-		&i.ChildBuildID,
-		&i.ChildTransition,
-		&i.ChildTemplateVersionID,
-		&i.ChildTemplateVersionPresetID,
-		&i.ChildRichParameterValues,
-		&i.ChildLogLevel,
-		&i.ChildReason,
-		&i.AttemptCount,
-		&i.NextRetryAfter,
-		&i.Status,
-		&i.Error,
+func (q *sqlQuerier) UpdateWorkspaceBuildFlagsByID(ctx context.Context, arg UpdateWorkspaceBuildFlagsByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildFlagsByID,
+		arg.HasAITask,
+		arg.HasExternalAgent,
+		arg.UpdatedAt,
+		arg.ID,
 	)
-	return i, err
+	return err
 }
 
-const updateWorkspaceBuildOrchestrationRetryByID = `-- name: UpdateWorkspaceBuildOrchestrationRetryByID :one
+const updateWorkspaceBuildNotifiedAutostopDeadline = `-- name: UpdateWorkspaceBuildNotifiedAutostopDeadline :exec
 UPDATE
-    workspace_build_orchestrations
+	workspace_builds
 SET
-    attempt_count = attempt_count + 1,
-    next_retry_after = CASE
-        WHEN attempt_count + 1 >= $1::int THEN NULL
-        ELSE $2::timestamptz
-    END,
-    status = CASE
-        WHEN attempt_count + 1 >= $1::int THEN 'failed'
-        ELSE status
-    END,
-    error = $3,
-    updated_at = $4
+	notified_autostop_deadline = $1::timestamptz,
+	updated_at = $2::timestamptz
+WHERE id = $3::uuid
+`
+
+type UpdateWorkspaceBuildNotifiedAutostopDeadlineParams struct {
+	NotifiedAutostopDeadline time.Time `db:"notified_autostop_deadline" json:"notified_autostop_deadline"`
+	UpdatedAt                time.Time `db:"updated_at" json:"updated_at"`
+	ID                       uuid.UUID `db:"id" json:"id"`
+}
+
+// Stamps the deadline value that an autostop reminder was last sent for. Once
+// this equals the build's deadline the reminder is considered handled and the
+// lifecycle executor will not send another for this deadline, which makes the
+// reminder idempotent and HA-safe. It re-arms automatically when the deadline
+// changes (e.g. an activity bump).
+func (q *sqlQuerier) UpdateWorkspaceBuildNotifiedAutostopDeadline(ctx context.Context, arg UpdateWorkspaceBuildNotifiedAutostopDeadlineParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildNotifiedAutostopDeadline, arg.NotifiedAutostopDeadline, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const updateWorkspaceBuildProvisionerStateByID = `-- name: UpdateWorkspaceBuildProvisionerStateByID :exec
+UPDATE
+	workspace_builds
+SET
+	provisioner_state = $1::bytea,
+	updated_at = $2::timestamptz
+WHERE id = $3::uuid
+`
+
+type UpdateWorkspaceBuildProvisionerStateByIDParams struct {
+	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	ID               uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID, arg.ProvisionerState, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const getLeakedWorkspaceCloudResources = `-- name: GetLeakedWorkspaceCloudResources :many
+SELECT
+	workspace_cloud_resources.workspace_id, workspace_cloud_resources.resource_type, workspace_cloud_resources.cloud_id, workspace_cloud_resources.resource_name, workspace_cloud_resources.workspace_build_id, workspace_cloud_resources.created_at, workspace_cloud_resources.updated_at, workspace_cloud_resources.verification_status, workspace_cloud_resources.verification_build_id, workspace_cloud_resources.verification_error, workspace_cloud_resources.verified_at,
+	workspaces_expanded.name AS workspace_name,
+	workspaces_expanded.owner_id,
+	workspaces_expanded.owner_username,
+	workspaces_expanded.organization_id,
+	workspaces_expanded.template_id,
+	workspaces_expanded.template_name
+FROM
+	workspace_cloud_resources
+JOIN
+	workspaces_expanded ON workspaces_expanded.id = workspace_cloud_resources.workspace_id
 WHERE
-    id = $5
-    AND status = 'pending'
-RETURNING id, created_at, updated_at, workspace_id, parent_build_id, child_build_id, child_transition, child_template_version_id, child_template_version_preset_id, child_rich_parameter_values, child_log_level, child_reason, attempt_count, next_retry_after, status, error
+	workspace_cloud_resources.verification_status = 'leaked'::cloud_resource_verification_status
+ORDER BY
+	workspace_cloud_resources.verified_at DESC,
+	workspace_cloud_resources.workspace_id,
+	workspace_cloud_resources.resource_type,
+	workspace_cloud_resources.cloud_id
 `
 
-type UpdateWorkspaceBuildOrchestrationRetryByIDParams struct {
-	MaxAttemptCount int32          `db:"max_attempt_count" json:"max_attempt_count"`
-	NextRetryAfter  time.Time      `db:"next_retry_after" json:"next_retry_after"`
-	Error           sql.NullString `db:"error" json:"error"`
-	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
-	ID              uuid.UUID      `db:"id" json:"id"`
+type GetLeakedWorkspaceCloudResourcesRow struct {
+	WorkspaceCloudResource WorkspaceCloudResource `db:"workspace_cloud_resource" json:"workspace_cloud_resource"`
+	WorkspaceName          string                 `db:"workspace_name" json:"workspace_name"`
+	OwnerID                uuid.UUID              `db:"owner_id" json:"owner_id"`
+	OwnerUsername          string                 `db:"owner_username" json:"owner_username"`
+	OrganizationID         uuid.UUID              `db:"organization_id" json:"organization_id"`
+	TemplateID             uuid.UUID              `db:"template_id" json:"template_id"`
+	TemplateName           string                 `db:"template_name" json:"template_name"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildOrchestrationRetryByID(ctx context.Context, arg UpdateWorkspaceBuildOrchestrationRetryByIDParams) (WorkspaceBuildOrchestration, error) {
-	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildOrchestrationRetryByID,
-		arg.MaxAttemptCount,
-		arg.NextRetryAfter,
-		arg.Error,
-		arg.UpdatedAt,
-		arg.ID,
-	)
-	var i WorkspaceBuildOrchestration
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.ParentBuildID,
-		&i.ChildBuildID,
-		&i.ChildTransition,
-		&i.ChildTemplateVersionID,
-		&i.ChildTemplateVersionPresetID,
-		&i.ChildRichParameterValues,
-		&i.ChildLogLevel,
-		&i.ChildReason,
-		&i.AttemptCount,
-		&i.NextRetryAfter,
-		&i.Status,
-		&i.Error,
-	)
-	return i, err
+// Returns the resources that still existed at their cloud provider after the
+// workspace that created them was deleted, most recently verified first.
+func (q *sqlQuerier) GetLeakedWorkspaceCloudResources(ctx context.Context) ([]GetLeakedWorkspaceCloudResourcesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getLeakedWorkspaceCloudResources)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLeakedWorkspaceCloudResourcesRow
+	for rows.Next() {
+		var i GetLeakedWorkspaceCloudResourcesRow
+		if err := rows.Scan(
+			&i.WorkspaceCloudResource.WorkspaceID,
+			&i.WorkspaceCloudResource.ResourceType,
+			&i.WorkspaceCloudResource.CloudID,
+			&i.WorkspaceCloudResource.ResourceName,
+			&i.WorkspaceCloudResource.WorkspaceBuildID,
+			&i.WorkspaceCloudResource.CreatedAt,
+			&i.WorkspaceCloudResource.UpdatedAt,
+			&i.WorkspaceCloudResource.VerificationStatus,
+			&i.WorkspaceCloudResource.VerificationBuildID,
+			&i.WorkspaceCloudResource.VerificationError,
+			&i.WorkspaceCloudResource.VerifiedAt,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.OwnerUsername,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.TemplateName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getUserWorkspaceBuildParameters = `-- name: GetUserWorkspaceBuildParameters :many
-SELECT name, value
-FROM (
-    SELECT DISTINCT ON (tvp.name)
-        tvp.name,
-        wbp.value,
-        wb.created_at
-    FROM
-        workspace_build_parameters wbp
-    JOIN
-        workspace_builds wb ON wb.id = wbp.workspace_build_id
-    JOIN
-        workspaces w ON w.id = wb.workspace_id
-    JOIN
-        template_version_parameters tvp ON tvp.template_version_id = wb.template_version_id
-    WHERE
-		w.owner_id = $1
-		AND wb.transition = 'start'
-		AND w.template_id = $2
-		AND tvp.ephemeral = false
-		AND tvp.name = wbp.name
-    ORDER BY
-        tvp.name, wb.created_at DESC
-) q1
-ORDER BY created_at DESC, name
-LIMIT 100
+const getUnverifiedWorkspaceCloudResources = `-- name: GetUnverifiedWorkspaceCloudResources :many
+SELECT
+	workspace_cloud_resources.workspace_id, workspace_cloud_resources.resource_type, workspace_cloud_resources.cloud_id, workspace_cloud_resources.resource_name, workspace_cloud_resources.workspace_build_id, workspace_cloud_resources.created_at, workspace_cloud_resources.updated_at, workspace_cloud_resources.verification_status, workspace_cloud_resources.verification_build_id, workspace_cloud_resources.verification_error, workspace_cloud_resources.verified_at,
+	deletion_builds.id AS deletion_build_id
+FROM
+	workspace_cloud_resources
+JOIN LATERAL (
+	SELECT
+		workspace_builds.id,
+		workspace_builds.transition,
+		provisioner_jobs.job_status,
+		provisioner_jobs.completed_at
+	FROM
+		workspace_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	WHERE
+		workspace_builds.workspace_id = workspace_cloud_resources.workspace_id
+	ORDER BY
+		workspace_builds.build_number DESC
+	LIMIT 1
+) deletion_builds ON true
+WHERE
+	workspace_cloud_resources.verification_status = 'unverified'::cloud_resource_verification_status
+	AND deletion_builds.transition = 'delete'::workspace_transition
+	AND deletion_builds.job_status = 'succeeded'::provisioner_job_status
+	AND deletion_builds.completed_at < $1::timestamptz
+ORDER BY
+	deletion_builds.completed_at,
+	workspace_cloud_resources.workspace_id,
+	workspace_cloud_resources.resource_type,
+	workspace_cloud_resources.cloud_id
+LIMIT
+	$2::int
 `
 
-type GetUserWorkspaceBuildParametersParams struct {
-	OwnerID    uuid.UUID `db:"owner_id" json:"owner_id"`
-	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+type GetUnverifiedWorkspaceCloudResourcesParams struct {
+	CompletedBefore time.Time `db:"completed_before" json:"completed_before"`
+	LimitCount      int32     `db:"limit_count" json:"limit_count"`
 }
 
-type GetUserWorkspaceBuildParametersRow struct {
-	Name  string `db:"name" json:"name"`
-	Value string `db:"value" json:"value"`
+type GetUnverifiedWorkspaceCloudResourcesRow struct {
+	WorkspaceCloudResource WorkspaceCloudResource `db:"workspace_cloud_resource" json:"workspace_cloud_resource"`
+	DeletionBuildID        uuid.UUID              `db:"deletion_build_id" json:"deletion_build_id"`
 }
 
-func (q *sqlQuerier) GetUserWorkspaceBuildParameters(ctx context.Context, arg GetUserWorkspaceBuildParametersParams) ([]GetUserWorkspaceBuildParametersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUserWorkspaceBuildParameters, arg.OwnerID, arg.TemplateID)
+// Returns the unverified resources of workspaces whose latest build is a
+// successful deletion that completed before completed_before, along with the
+// ID of the deletion build.
+func (q *sqlQuerier) GetUnverifiedWorkspaceCloudResources(ctx context.Context, arg GetUnverifiedWorkspaceCloudResourcesParams) ([]GetUnverifiedWorkspaceCloudResourcesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUnverifiedWorkspaceCloudResources, arg.CompletedBefore, arg.LimitCount)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUserWorkspaceBuildParametersRow
+	var items []GetUnverifiedWorkspaceCloudResourcesRow
 	for rows.Next() {
-		var i GetUserWorkspaceBuildParametersRow
-		if err := rows.Scan(&i.Name, &i.Value); err != nil {
+		var i GetUnverifiedWorkspaceCloudResourcesRow
+		if err := rows.Scan(
+			&i.WorkspaceCloudResource.WorkspaceID,
+			&i.WorkspaceCloudResource.ResourceType,
+			&i.WorkspaceCloudResource.CloudID,
+			&i.WorkspaceCloudResource.ResourceName,
+			&i.WorkspaceCloudResource.WorkspaceBuildID,
+			&i.WorkspaceCloudResource.CreatedAt,
+			&i.WorkspaceCloudResource.UpdatedAt,
+			&i.WorkspaceCloudResource.VerificationStatus,
+			&i.WorkspaceCloudResource.VerificationBuildID,
+			&i.WorkspaceCloudResource.VerificationError,
+			&i.WorkspaceCloudResource.VerifiedAt,
+			&i.DeletionBuildID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -36381,25 +43966,301 @@ func (q *sqlQuerier) GetUserWorkspaceBuildParameters(ctx context.Context, arg Ge
 	return items, nil
 }
 
-const getWorkspaceBuildParameters = `-- name: GetWorkspaceBuildParameters :many
+const getWorkspaceCloudResourcesByWorkspaceID = `-- name: GetWorkspaceCloudResourcesByWorkspaceID :many
+SELECT
+	workspace_id, resource_type, cloud_id, resource_name, workspace_build_id, created_at, updated_at, verification_status, verification_build_id, verification_error, verified_at
+FROM
+	workspace_cloud_resources
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at, resource_type, cloud_id
+`
+
+func (q *sqlQuerier) GetWorkspaceCloudResourcesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceCloudResource, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceCloudResourcesByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceCloudResource
+	for rows.Next() {
+		var i WorkspaceCloudResource
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.ResourceType,
+			&i.CloudID,
+			&i.ResourceName,
+			&i.WorkspaceBuildID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.VerificationStatus,
+			&i.VerificationBuildID,
+			&i.VerificationError,
+			&i.VerifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWorkspaceCloudResourceVerification = `-- name: UpdateWorkspaceCloudResourceVerification :exec
+UPDATE
+	workspace_cloud_resources
+SET
+	verification_status = $1,
+	verification_build_id = $2,
+	verification_error = $3,
+	verified_at = $4
+WHERE
+	workspace_id = $5
+	AND resource_type = $6
+	AND cloud_id = $7
+`
+
+type UpdateWorkspaceCloudResourceVerificationParams struct {
+	VerificationStatus  CloudResourceVerificationStatus `db:"verification_status" json:"verification_status"`
+	VerificationBuildID uuid.NullUUID                   `db:"verification_build_id" json:"verification_build_id"`
+	VerificationError   string                          `db:"verification_error" json:"verification_error"`
+	VerifiedAt          sql.NullTime                    `db:"verified_at" json:"verified_at"`
+	WorkspaceID         uuid.UUID                       `db:"workspace_id" json:"workspace_id"`
+	ResourceType        string                          `db:"resource_type" json:"resource_type"`
+	CloudID             string                          `db:"cloud_id" json:"cloud_id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceCloudResourceVerification(ctx context.Context, arg UpdateWorkspaceCloudResourceVerificationParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceCloudResourceVerification,
+		arg.VerificationStatus,
+		arg.VerificationBuildID,
+		arg.VerificationError,
+		arg.VerifiedAt,
+		arg.WorkspaceID,
+		arg.ResourceType,
+		arg.CloudID,
+	)
+	return err
+}
+
+const upsertWorkspaceCloudResource = `-- name: UpsertWorkspaceCloudResource :exec
+INSERT INTO
+	workspace_cloud_resources (
+		workspace_id,
+		resource_type,
+		cloud_id,
+		resource_name,
+		workspace_build_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $6)
+ON CONFLICT (workspace_id, resource_type, cloud_id) DO UPDATE SET
+	resource_name = EXCLUDED.resource_name,
+	workspace_build_id = EXCLUDED.workspace_build_id,
+	updated_at = EXCLUDED.updated_at,
+	verification_status = 'unverified'::cloud_resource_verification_status,
+	verification_build_id = NULL,
+	verification_error = '',
+	verified_at = NULL
+`
+
+type UpsertWorkspaceCloudResourceParams struct {
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	ResourceType     string    `db:"resource_type" json:"resource_type"`
+	CloudID          string    `db:"cloud_id" json:"cloud_id"`
+	ResourceName     string    `db:"resource_name" json:"resource_name"`
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	Now              time.Time `db:"now" json:"now"`
+}
+
+// Records a resource reported by a build. A resource reported again by a
+// later build exists at that time, so any earlier verification is reset.
+func (q *sqlQuerier) UpsertWorkspaceCloudResource(ctx context.Context, arg UpsertWorkspaceCloudResourceParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceCloudResource,
+		arg.WorkspaceID,
+		arg.ResourceType,
+		arg.CloudID,
+		arg.ResourceName,
+		arg.WorkspaceBuildID,
+		arg.Now,
+	)
+	return err
+}
+
+const accrueWorkspaceEstimatedCosts = `-- name: AccrueWorkspaceEstimatedCosts :exec
+WITH running AS (
+	SELECT
+		workspace_latest_builds.workspace_id,
+		workspace_builds.daily_cost
+	FROM
+		workspace_latest_builds
+	JOIN
+		workspace_builds ON workspace_builds.id = workspace_latest_builds.id
+	JOIN
+		workspaces ON workspaces.id = workspace_latest_builds.workspace_id
+	WHERE
+		workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+		AND workspace_latest_builds.transition = 'start'::workspace_transition
+		AND workspace_latest_builds.job_status = 'succeeded'::provisioner_job_status
+		AND workspace_builds.daily_cost > 0
+), idle AS (
+	UPDATE
+		workspace_monthly_costs
+	SET
+		updated_at = $1::timestamptz
+	WHERE
+		month = $2::timestamptz
+		AND source = 'estimate'::workspace_cost_source
+		AND workspace_id NOT IN (SELECT workspace_id FROM running)
+)
+INSERT INTO
+	workspace_monthly_costs (
+		workspace_id,
+		month,
+		cost,
+		source,
+		updated_at
+	)
+SELECT
+	running.workspace_id,
+	$2::timestamptz,
+	0,
+	'estimate'::workspace_cost_source,
+	$1::timestamptz
+FROM
+	running
+ON CONFLICT (workspace_id, month)
+DO UPDATE SET
+	cost = workspace_monthly_costs.cost + (
+		SELECT
+			running.daily_cost
+		FROM
+			running
+		WHERE
+			running.workspace_id = workspace_monthly_costs.workspace_id
+	) * EXTRACT(EPOCH FROM ($1::timestamptz - workspace_monthly_costs.updated_at))::float8 / 86400,
+	updated_at = $1::timestamptz
+WHERE
+	workspace_monthly_costs.source = 'estimate'::workspace_cost_source
+	AND workspace_monthly_costs.updated_at < $1::timestamptz
+`
+
+type AccrueWorkspaceEstimatedCostsParams struct {
+	Now   time.Time `db:"now" json:"now"`
+	Month time.Time `db:"month" json:"month"`
+}
+
+// Adds the estimated cost of every running workspace since the last accrual
+// to its counter for the month, based on the daily cost of its latest build.
+// Estimated counters of workspaces that are not running only advance their
+// accrual time, and counters imported from a billing export are left alone.
+// Concurrent runs do not double count, as every run only accrues the time
+// since the previous one.
+func (q *sqlQuerier) AccrueWorkspaceEstimatedCosts(ctx context.Context, arg AccrueWorkspaceEstimatedCostsParams) error {
+	_, err := q.db.ExecContext(ctx, accrueWorkspaceEstimatedCosts, arg.Now, arg.Month)
+	return err
+}
+
+const getWorkspaceMonthlyCost = `-- name: GetWorkspaceMonthlyCost :one
+SELECT
+	workspace_id, month, cost, source, updated_at
+FROM
+	workspace_monthly_costs
+WHERE
+	workspace_id = $1
+	AND month = $2
+`
+
+type GetWorkspaceMonthlyCostParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Month       time.Time `db:"month" json:"month"`
+}
+
+func (q *sqlQuerier) GetWorkspaceMonthlyCost(ctx context.Context, arg GetWorkspaceMonthlyCostParams) (WorkspaceMonthlyCost, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceMonthlyCost, arg.WorkspaceID, arg.Month)
+	var i WorkspaceMonthlyCost
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.Month,
+		&i.Cost,
+		&i.Source,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWorkspacesExceedingCostBudget = `-- name: GetWorkspacesExceedingCostBudget :many
+WITH costs AS (
+	SELECT
+		workspace_monthly_costs.workspace_id,
+		workspaces.owner_id,
+		workspaces.deleted,
+		workspace_monthly_costs.cost,
+		SUM(workspace_monthly_costs.cost) OVER (PARTITION BY workspaces.owner_id) AS user_cost
+	FROM
+		workspace_monthly_costs
+	JOIN
+		workspaces ON workspaces.id = workspace_monthly_costs.workspace_id
+	WHERE
+		workspace_monthly_costs.month = $1::timestamptz
+)
 SELECT
-    workspace_build_id, name, value
+	workspace_id,
+	owner_id,
+	cost::float8 AS workspace_cost,
+	user_cost::float8 AS user_cost
 FROM
-    workspace_build_parameters
+	costs
 WHERE
-    workspace_build_id = $1
+	NOT deleted
+	AND (
+		($2::bigint > 0 AND cost > $2::bigint)
+		OR ($3::bigint > 0 AND user_cost > $3::bigint)
+	)
+ORDER BY
+	workspace_id
 `
 
-func (q *sqlQuerier) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildParameters, workspaceBuildID)
+type GetWorkspacesExceedingCostBudgetParams struct {
+	Month           time.Time `db:"month" json:"month"`
+	WorkspaceBudget int64     `db:"workspace_budget" json:"workspace_budget"`
+	UserBudget      int64     `db:"user_budget" json:"user_budget"`
+}
+
+type GetWorkspacesExceedingCostBudgetRow struct {
+	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	OwnerID       uuid.UUID `db:"owner_id" json:"owner_id"`
+	WorkspaceCost float64   `db:"workspace_cost" json:"workspace_cost"`
+	UserCost      float64   `db:"user_cost" json:"user_cost"`
+}
+
+// Returns the workspaces whose cost in the month exceeds the per-workspace
+// budget, or whose owner's workspaces together exceed the per-user budget. A
+// budget of 0 is disabled. Deleted workspaces are not returned, but their cost
+// still counts towards the per-user budget.
+func (q *sqlQuerier) GetWorkspacesExceedingCostBudget(ctx context.Context, arg GetWorkspacesExceedingCostBudgetParams) ([]GetWorkspacesExceedingCostBudgetRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacesExceedingCostBudget, arg.Month, arg.WorkspaceBudget, arg.UserBudget)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceBuildParameter
+	var items []GetWorkspacesExceedingCostBudgetRow
 	for rows.Next() {
-		var i WorkspaceBuildParameter
-		if err := rows.Scan(&i.WorkspaceBuildID, &i.Name, &i.Value); err != nil {
+		var i GetWorkspacesExceedingCostBudgetRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.OwnerID,
+			&i.WorkspaceCost,
+			&i.UserCost,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -36413,168 +44274,147 @@ func (q *sqlQuerier) GetWorkspaceBuildParameters(ctx context.Context, workspaceB
 	return items, nil
 }
 
-const insertWorkspaceBuildParameters = `-- name: InsertWorkspaceBuildParameters :exec
+const upsertWorkspaceMonthlyCost = `-- name: UpsertWorkspaceMonthlyCost :one
 INSERT INTO
-    workspace_build_parameters (workspace_build_id, name, value)
-SELECT
-    $1 :: uuid AS workspace_build_id,
-    unnest($2 :: text[]) AS name,
-    unnest($3 :: text[]) AS value
-RETURNING workspace_build_id, name, value
+	workspace_monthly_costs (
+		workspace_id,
+		month,
+		cost,
+		source,
+		updated_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	'billing_export'::workspace_cost_source,
+	$4
+)
+ON CONFLICT (workspace_id, month)
+DO UPDATE SET
+	cost = $3,
+	source = 'billing_export'::workspace_cost_source,
+	updated_at = $4
+RETURNING workspace_id, month, cost, source, updated_at
 `
 
-type InsertWorkspaceBuildParametersParams struct {
-	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
-	Name             []string  `db:"name" json:"name"`
-	Value            []string  `db:"value" json:"value"`
+type UpsertWorkspaceMonthlyCostParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Month       time.Time `db:"month" json:"month"`
+	Cost        float64   `db:"cost" json:"cost"`
+	Now         time.Time `db:"now" json:"now"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceBuildParameters, arg.WorkspaceBuildID, pq.Array(arg.Name), pq.Array(arg.Value))
-	return err
+// Imports the cost of a workspace from a billing export. The imported cost
+// replaces the estimate for the month.
+func (q *sqlQuerier) UpsertWorkspaceMonthlyCost(ctx context.Context, arg UpsertWorkspaceMonthlyCostParams) (WorkspaceMonthlyCost, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceMonthlyCost,
+		arg.WorkspaceID,
+		arg.Month,
+		arg.Cost,
+		arg.Now,
+	)
+	var i WorkspaceMonthlyCost
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.Month,
+		&i.Cost,
+		&i.Source,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.has_external_agent, wb.notified_autostop_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
-FROM (
-    SELECT
-        workspace_id, MAX(build_number) as max_build_number
-    FROM
-		workspace_build_with_user AS workspace_builds
-    WHERE
-        workspace_id IN (
-			SELECT
-				id
-			FROM
-				workspaces
-			WHERE
-				template_id = $1
-		)
-    GROUP BY
-        workspace_id
-) m
-JOIN
-	workspace_build_with_user AS wb
-	ON m.workspace_id = wb.workspace_id AND m.max_build_number = wb.build_number
-JOIN
-	provisioner_jobs AS pj
-	ON wb.job_id = pj.id
+const deleteWorkspaceDNSRecordByWorkspaceID = `-- name: DeleteWorkspaceDNSRecordByWorkspaceID :exec
+DELETE FROM
+	workspace_dns_records
 WHERE
-	wb.transition = 'start'::workspace_transition
-AND
-	pj.completed_at IS NOT NULL
+	workspace_id = $1
 `
 
-func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error) {
-	rows, err := q.db.QueryContext(ctx, getActiveWorkspaceBuildsByTemplateID, templateID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []WorkspaceBuild
-	for rows.Next() {
-		var i WorkspaceBuild
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.WorkspaceID,
-			&i.TemplateVersionID,
-			&i.BuildNumber,
-			&i.Transition,
-			&i.InitiatorID,
-			&i.JobID,
-			&i.Deadline,
-			&i.Reason,
-			&i.DailyCost,
-			&i.MaxDeadline,
-			&i.TemplateVersionPresetID,
-			&i.HasAITask,
-			&i.HasExternalAgent,
-			&i.NotifiedAutostopDeadline,
-			&i.InitiatorByAvatarUrl,
-			&i.InitiatorByUsername,
-			&i.InitiatorByName,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) DeleteWorkspaceDNSRecordByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceDNSRecordByWorkspaceID, workspaceID)
+	return err
 }
 
-const getFailedWorkspaceBuildsByTemplateID = `-- name: GetFailedWorkspaceBuildsByTemplateID :many
+const getWorkspaceDNSRecordChanges = `-- name: GetWorkspaceDNSRecordChanges :many
+WITH desired AS (
+	SELECT
+		workspaces.id AS workspace_id,
+		workspaces.name AS workspace_name,
+		users.username AS owner_username,
+		lower(workspaces.name || '--' || users.username || '.' || $1::text) AS name
+	FROM
+		workspaces
+	JOIN
+		users ON users.id = workspaces.owner_id
+	JOIN
+		workspace_latest_builds ON workspace_latest_builds.workspace_id = workspaces.id
+	WHERE
+		workspaces.deleted = false
+		AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+		AND workspace_latest_builds.transition = 'start'::workspace_transition
+		AND workspace_latest_builds.job_status = 'succeeded'::provisioner_job_status
+)
 SELECT
-	tv.name AS template_version_name,
-	u.username AS workspace_owner_username,
-	w.name AS workspace_name,
-	w.id AS workspace_id,
-	wb.build_number AS workspace_build_number
+	COALESCE(desired.workspace_id, workspace_dns_records.workspace_id)::uuid AS workspace_id,
+	COALESCE(desired.workspace_name, '')::text AS workspace_name,
+	COALESCE(desired.owner_username, '')::text AS owner_username,
+	COALESCE(desired.name, '')::text AS desired_name,
+	COALESCE(workspace_dns_records.name, '')::text AS registered_name,
+	COALESCE(workspace_dns_records.target, '')::text AS registered_target
 FROM
-	workspace_build_with_user AS wb
-JOIN
-	workspaces AS w
-ON
-	wb.workspace_id = w.id
-JOIN
-    users AS u
-ON
-    w.owner_id = u.id
-JOIN
-	provisioner_jobs AS pj
-ON
-	wb.job_id = pj.id
-JOIN
-	templates AS t
-ON
-	w.template_id = t.id
-JOIN
-	template_versions AS tv
-ON
-	wb.template_version_id = tv.id
+	desired
+FULL OUTER JOIN
+	workspace_dns_records ON workspace_dns_records.workspace_id = desired.workspace_id
 WHERE
-	w.template_id = $1
-	AND wb.created_at >= $2
-	AND pj.completed_at IS NOT NULL
-	AND pj.job_status = 'failed'
+	desired.name IS DISTINCT FROM workspace_dns_records.name
+	OR (
+		desired.workspace_id IS NOT NULL
+		AND workspace_dns_records.target != $2::text
+	)
 ORDER BY
-	tv.name ASC, wb.build_number DESC
+	COALESCE(desired.workspace_id, workspace_dns_records.workspace_id)
+LIMIT
+	$3::int
 `
 
-type GetFailedWorkspaceBuildsByTemplateIDParams struct {
-	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
-	Since      time.Time `db:"since" json:"since"`
+type GetWorkspaceDNSRecordChangesParams struct {
+	Domain     string `db:"domain" json:"domain"`
+	Target     string `db:"target" json:"target"`
+	MaxChanges int32  `db:"max_changes" json:"max_changes"`
 }
 
-type GetFailedWorkspaceBuildsByTemplateIDRow struct {
-	TemplateVersionName    string    `db:"template_version_name" json:"template_version_name"`
-	WorkspaceOwnerUsername string    `db:"workspace_owner_username" json:"workspace_owner_username"`
-	WorkspaceName          string    `db:"workspace_name" json:"workspace_name"`
-	WorkspaceID            uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	WorkspaceBuildNumber   int32     `db:"workspace_build_number" json:"workspace_build_number"`
+type GetWorkspaceDNSRecordChangesRow struct {
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName    string    `db:"workspace_name" json:"workspace_name"`
+	OwnerUsername    string    `db:"owner_username" json:"owner_username"`
+	DesiredName      string    `db:"desired_name" json:"desired_name"`
+	RegisteredName   string    `db:"registered_name" json:"registered_name"`
+	RegisteredTarget string    `db:"registered_target" json:"registered_target"`
 }
 
-func (q *sqlQuerier) GetFailedWorkspaceBuildsByTemplateID(ctx context.Context, arg GetFailedWorkspaceBuildsByTemplateIDParams) ([]GetFailedWorkspaceBuildsByTemplateIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFailedWorkspaceBuildsByTemplateID, arg.TemplateID, arg.Since)
+// Returns the workspaces whose DNS record is out of date: running workspaces
+// without a record, or with a record under another name or target, and
+// records of workspaces that are no longer running. A workspace is running
+// once the provisioner job of its latest start build succeeded. Prebuilt
+// workspaces are never registered.
+func (q *sqlQuerier) GetWorkspaceDNSRecordChanges(ctx context.Context, arg GetWorkspaceDNSRecordChangesParams) ([]GetWorkspaceDNSRecordChangesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceDNSRecordChanges, arg.Domain, arg.Target, arg.MaxChanges)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetFailedWorkspaceBuildsByTemplateIDRow
+	var items []GetWorkspaceDNSRecordChangesRow
 	for rows.Next() {
-		var i GetFailedWorkspaceBuildsByTemplateIDRow
+		var i GetWorkspaceDNSRecordChangesRow
 		if err := rows.Scan(
-			&i.TemplateVersionName,
-			&i.WorkspaceOwnerUsername,
-			&i.WorkspaceName,
 			&i.WorkspaceID,
-			&i.WorkspaceBuildNumber,
+			&i.WorkspaceName,
+			&i.OwnerUsername,
+			&i.DesiredName,
+			&i.RegisteredName,
+			&i.RegisteredTarget,
 		); err != nil {
 			return nil, err
 		}
@@ -36589,144 +44429,30 @@ func (q *sqlQuerier) GetFailedWorkspaceBuildsByTemplateID(ctx context.Context, a
 	return items, nil
 }
 
-const getLatestWorkspaceBuildByWorkspaceID = `-- name: GetLatestWorkspaceBuildByWorkspaceID :one
-SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
-FROM
-	workspace_build_with_user AS workspace_builds
-WHERE
-	workspace_id = $1
-ORDER BY
-    build_number desc
-LIMIT
-	1
-`
-
-func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error) {
-	row := q.db.QueryRowContext(ctx, getLatestWorkspaceBuildByWorkspaceID, workspaceID)
-	var i WorkspaceBuild
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.TemplateVersionID,
-		&i.BuildNumber,
-		&i.Transition,
-		&i.InitiatorID,
-		&i.JobID,
-		&i.Deadline,
-		&i.Reason,
-		&i.DailyCost,
-		&i.MaxDeadline,
-		&i.TemplateVersionPresetID,
-		&i.HasAITask,
-		&i.HasExternalAgent,
-		&i.NotifiedAutostopDeadline,
-		&i.InitiatorByAvatarUrl,
-		&i.InitiatorByUsername,
-		&i.InitiatorByName,
-	)
-	return i, err
-}
-
-const getLatestWorkspaceBuildWithStatusByWorkspaceID = `-- name: GetLatestWorkspaceBuildWithStatusByWorkspaceID :one
-SELECT
-	workspace_builds.transition, workspace_builds.build_number, provisioner_jobs.job_status,
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl -- Used for dbauthz fetch() checks
-FROM
-	workspace_builds
-INNER JOIN
-	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
-INNER JOIN
-	workspaces ON workspace_builds.workspace_id = workspaces.id
-WHERE
-	workspace_builds.workspace_id = $1 AND
-	workspaces.deleted = false
-ORDER BY
-	workspace_builds.build_number desc
-	LIMIT
-	1
-`
-
-type GetLatestWorkspaceBuildWithStatusByWorkspaceIDRow struct {
-	Transition     WorkspaceTransition  `db:"transition" json:"transition"`
-	BuildNumber    int32                `db:"build_number" json:"build_number"`
-	JobStatus      ProvisionerJobStatus `db:"job_status" json:"job_status"`
-	WorkspaceTable WorkspaceTable       `db:"workspace_table" json:"workspace_table"`
-}
-
-func (q *sqlQuerier) GetLatestWorkspaceBuildWithStatusByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (GetLatestWorkspaceBuildWithStatusByWorkspaceIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getLatestWorkspaceBuildWithStatusByWorkspaceID, workspaceID)
-	var i GetLatestWorkspaceBuildWithStatusByWorkspaceIDRow
-	err := row.Scan(
-		&i.Transition,
-		&i.BuildNumber,
-		&i.JobStatus,
-		&i.WorkspaceTable.ID,
-		&i.WorkspaceTable.CreatedAt,
-		&i.WorkspaceTable.UpdatedAt,
-		&i.WorkspaceTable.OwnerID,
-		&i.WorkspaceTable.OrganizationID,
-		&i.WorkspaceTable.TemplateID,
-		&i.WorkspaceTable.Deleted,
-		&i.WorkspaceTable.Name,
-		&i.WorkspaceTable.AutostartSchedule,
-		&i.WorkspaceTable.Ttl,
-		&i.WorkspaceTable.LastUsedAt,
-		&i.WorkspaceTable.DormantAt,
-		&i.WorkspaceTable.DeletingAt,
-		&i.WorkspaceTable.AutomaticUpdates,
-		&i.WorkspaceTable.Favorite,
-		&i.WorkspaceTable.NextStartAt,
-		&i.WorkspaceTable.GroupACL,
-		&i.WorkspaceTable.UserACL,
-	)
-	return i, err
-}
-
-const getLatestWorkspaceBuildsByWorkspaceIDs = `-- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
+const getWorkspaceDNSRecordsByWorkspaceIDs = `-- name: GetWorkspaceDNSRecordsByWorkspaceIDs :many
 SELECT
-	DISTINCT ON (workspace_id)
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	workspace_id, name, target, created_at, updated_at
 FROM
-	workspace_build_with_user AS workspace_builds
+	workspace_dns_records
 WHERE
-	workspace_id = ANY($1 :: uuid [ ])
-ORDER BY
-	workspace_id, build_number DESC -- latest first
+	workspace_id = ANY($1::uuid[])
 `
 
-func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error) {
-	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceBuildsByWorkspaceIDs, pq.Array(ids))
+func (q *sqlQuerier) GetWorkspaceDNSRecordsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceDNSRecord, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceDNSRecordsByWorkspaceIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceBuild
+	var items []WorkspaceDNSRecord
 	for rows.Next() {
-		var i WorkspaceBuild
+		var i WorkspaceDNSRecord
 		if err := rows.Scan(
-			&i.ID,
+			&i.WorkspaceID,
+			&i.Name,
+			&i.Target,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.WorkspaceID,
-			&i.TemplateVersionID,
-			&i.BuildNumber,
-			&i.Transition,
-			&i.InitiatorID,
-			&i.JobID,
-			&i.Deadline,
-			&i.Reason,
-			&i.DailyCost,
-			&i.MaxDeadline,
-			&i.TemplateVersionPresetID,
-			&i.HasAITask,
-			&i.HasExternalAgent,
-			&i.NotifiedAutostopDeadline,
-			&i.InitiatorByAvatarUrl,
-			&i.InitiatorByUsername,
-			&i.InitiatorByName,
 		); err != nil {
 			return nil, err
 		}
@@ -36741,278 +44467,396 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 	return items, nil
 }
 
-const getWorkspaceBuildByID = `-- name: GetWorkspaceBuildByID :one
-SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
-FROM
-	workspace_build_with_user AS workspace_builds
-WHERE
-	id = $1
-LIMIT
-	1
+const upsertWorkspaceDNSRecord = `-- name: UpsertWorkspaceDNSRecord :one
+INSERT INTO
+	workspace_dns_records (
+		workspace_id,
+		name,
+		target,
+		created_at,
+		updated_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$4
+)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	name = $2,
+	target = $3,
+	updated_at = $4
+RETURNING workspace_id, name, target, created_at, updated_at
 `
 
-func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByID, id)
-	var i WorkspaceBuild
+type UpsertWorkspaceDNSRecordParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+	Target      string    `db:"target" json:"target"`
+	Now         time.Time `db:"now" json:"now"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceDNSRecord(ctx context.Context, arg UpsertWorkspaceDNSRecordParams) (WorkspaceDNSRecord, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceDNSRecord,
+		arg.WorkspaceID,
+		arg.Name,
+		arg.Target,
+		arg.Now,
+	)
+	var i WorkspaceDNSRecord
 	err := row.Scan(
-		&i.ID,
+		&i.WorkspaceID,
+		&i.Name,
+		&i.Target,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.TemplateVersionID,
-		&i.BuildNumber,
-		&i.Transition,
-		&i.InitiatorID,
-		&i.JobID,
-		&i.Deadline,
-		&i.Reason,
-		&i.DailyCost,
-		&i.MaxDeadline,
-		&i.TemplateVersionPresetID,
-		&i.HasAITask,
-		&i.HasExternalAgent,
-		&i.NotifiedAutostopDeadline,
-		&i.InitiatorByAvatarUrl,
-		&i.InitiatorByUsername,
-		&i.InitiatorByName,
 	)
 	return i, err
 }
 
-const getWorkspaceBuildByJobID = `-- name: GetWorkspaceBuildByJobID :one
+const deleteWorkspaceDormancyExemption = `-- name: DeleteWorkspaceDormancyExemption :exec
+DELETE FROM
+	workspace_dormancy_exemptions
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceDormancyExemption(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceDormancyExemption, workspaceID)
+	return err
+}
+
+const getWorkspaceDormancyExemptionByWorkspaceID = `-- name: GetWorkspaceDormancyExemptionByWorkspaceID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	workspace_id, reason, expires_at, approved_by, created_at, updated_at
 FROM
-	workspace_build_with_user AS workspace_builds
+	workspace_dormancy_exemptions
 WHERE
-	job_id = $1
-LIMIT
-	1
+	workspace_id = $1
 `
 
-func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByJobID, jobID)
-	var i WorkspaceBuild
+func (q *sqlQuerier) GetWorkspaceDormancyExemptionByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceDormancyExemption, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceDormancyExemptionByWorkspaceID, workspaceID)
+	var i WorkspaceDormancyExemption
 	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
 		&i.WorkspaceID,
-		&i.TemplateVersionID,
-		&i.BuildNumber,
-		&i.Transition,
-		&i.InitiatorID,
-		&i.JobID,
-		&i.Deadline,
 		&i.Reason,
-		&i.DailyCost,
-		&i.MaxDeadline,
-		&i.TemplateVersionPresetID,
-		&i.HasAITask,
-		&i.HasExternalAgent,
-		&i.NotifiedAutostopDeadline,
-		&i.InitiatorByAvatarUrl,
-		&i.InitiatorByUsername,
-		&i.InitiatorByName,
+		&i.ExpiresAt,
+		&i.ApprovedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getWorkspaceBuildByWorkspaceIDAndBuildNumber = `-- name: GetWorkspaceBuildByWorkspaceIDAndBuildNumber :one
-SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
-FROM
-	workspace_build_with_user AS workspace_builds
-WHERE
-	workspace_id = $1
-	AND build_number = $2
+const upsertWorkspaceDormancyExemption = `-- name: UpsertWorkspaceDormancyExemption :one
+INSERT INTO
+	workspace_dormancy_exemptions (
+		workspace_id,
+		reason,
+		expires_at,
+		approved_by,
+		created_at,
+		updated_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$5
+)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	reason = $2,
+	expires_at = $3,
+	approved_by = $4,
+	updated_at = $5
+RETURNING workspace_id, reason, expires_at, approved_by, created_at, updated_at
 `
 
-type GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams struct {
+type UpsertWorkspaceDormancyExemptionParams struct {
 	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	BuildNumber int32     `db:"build_number" json:"build_number"`
+	Reason      string    `db:"reason" json:"reason"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+	ApprovedBy  uuid.UUID `db:"approved_by" json:"approved_by"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByWorkspaceIDAndBuildNumber, arg.WorkspaceID, arg.BuildNumber)
-	var i WorkspaceBuild
+func (q *sqlQuerier) UpsertWorkspaceDormancyExemption(ctx context.Context, arg UpsertWorkspaceDormancyExemptionParams) (WorkspaceDormancyExemption, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceDormancyExemption,
+		arg.WorkspaceID,
+		arg.Reason,
+		arg.ExpiresAt,
+		arg.ApprovedBy,
+		arg.CreatedAt,
+	)
+	var i WorkspaceDormancyExemption
 	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
 		&i.WorkspaceID,
-		&i.TemplateVersionID,
-		&i.BuildNumber,
-		&i.Transition,
-		&i.InitiatorID,
-		&i.JobID,
-		&i.Deadline,
 		&i.Reason,
-		&i.DailyCost,
-		&i.MaxDeadline,
-		&i.TemplateVersionPresetID,
-		&i.HasAITask,
-		&i.HasExternalAgent,
-		&i.NotifiedAutostopDeadline,
-		&i.InitiatorByAvatarUrl,
-		&i.InitiatorByUsername,
-		&i.InitiatorByName,
+		&i.ExpiresAt,
+		&i.ApprovedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getWorkspaceBuildMetricsByResourceID = `-- name: GetWorkspaceBuildMetricsByResourceID :one
-SELECT
-    wb.created_at,
-    wb.transition,
-    t.name AS template_name,
-    o.name AS organization_name,
-    (w.owner_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0') AS is_prebuild,
-    -- All agents must have ready_at set (terminal startup state)
-    COUNT(*) FILTER (WHERE wa.ready_at IS NULL) = 0 AS all_agents_ready,
-    -- Latest ready_at across all agents (for duration calculation)
-	MAX(wa.ready_at)::timestamptz AS last_agent_ready_at,
-    -- Worst status: error > timeout > ready
-    CASE
-        WHEN bool_or(wa.lifecycle_state = 'start_error') THEN 'error'
-        WHEN bool_or(wa.lifecycle_state = 'start_timeout') THEN 'timeout'
-        ELSE 'success'
-    END AS worst_status
-FROM workspace_builds wb
-JOIN workspaces w ON wb.workspace_id = w.id
-JOIN templates t ON w.template_id = t.id
-JOIN organizations o ON t.organization_id = o.id
-JOIN workspace_resources wr ON wr.job_id = wb.job_id
-JOIN workspace_agents wa ON wa.resource_id = wr.id AND wa.parent_id IS NULL
-WHERE wb.job_id = (SELECT job_id FROM workspace_resources WHERE workspace_resources.id = $1)
-GROUP BY wb.created_at, wb.transition, t.name, o.name, w.owner_id
+const claimWorkspaceDormancyHooks = `-- name: ClaimWorkspaceDormancyHooks :many
+UPDATE
+	workspace_dormancy_hooks
+SET
+	status = 'running'::workspace_dormancy_hook_status,
+	updated_at = $1
+WHERE
+	workspace_id IN (
+		SELECT
+			workspace_id
+		FROM
+			workspace_dormancy_hooks
+		WHERE
+			status = 'pending'::workspace_dormancy_hook_status
+			OR (
+				status = 'running'::workspace_dormancy_hook_status
+				AND updated_at < $2
+			)
+		ORDER BY
+			updated_at ASC
+		LIMIT
+			$3::int
+		FOR UPDATE SKIP LOCKED
+	)
+RETURNING workspace_id, event, status, error, created_at, updated_at
 `
 
-type GetWorkspaceBuildMetricsByResourceIDRow struct {
-	CreatedAt        time.Time           `db:"created_at" json:"created_at"`
-	Transition       WorkspaceTransition `db:"transition" json:"transition"`
-	TemplateName     string              `db:"template_name" json:"template_name"`
-	OrganizationName string              `db:"organization_name" json:"organization_name"`
-	IsPrebuild       bool                `db:"is_prebuild" json:"is_prebuild"`
-	AllAgentsReady   bool                `db:"all_agents_ready" json:"all_agents_ready"`
-	LastAgentReadyAt time.Time           `db:"last_agent_ready_at" json:"last_agent_ready_at"`
-	WorstStatus      string              `db:"worst_status" json:"worst_status"`
+type ClaimWorkspaceDormancyHooksParams struct {
+	Now         time.Time `db:"now" json:"now"`
+	StaleBefore time.Time `db:"stale_before" json:"stale_before"`
+	LimitCount  int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Marks up to @limit_count pending hooks as running and returns them. Hooks
+// that have been running since before @stale_before are claimed again, so
+// invocations interrupted by a restart are retried.
+func (q *sqlQuerier) ClaimWorkspaceDormancyHooks(ctx context.Context, arg ClaimWorkspaceDormancyHooksParams) ([]WorkspaceDormancyHook, error) {
+	rows, err := q.db.QueryContext(ctx, claimWorkspaceDormancyHooks, arg.Now, arg.StaleBefore, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceDormancyHook
+	for rows.Next() {
+		var i WorkspaceDormancyHook
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.Event,
+			&i.Status,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-// Returns build metadata for e2e workspace build duration metrics.
-// Also checks if all agents are ready and returns the worst status.
-func (q *sqlQuerier) GetWorkspaceBuildMetricsByResourceID(ctx context.Context, id uuid.UUID) (GetWorkspaceBuildMetricsByResourceIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceBuildMetricsByResourceID, id)
-	var i GetWorkspaceBuildMetricsByResourceIDRow
+const getWorkspaceDormancyHookByWorkspaceID = `-- name: GetWorkspaceDormancyHookByWorkspaceID :one
+SELECT
+	workspace_id, event, status, error, created_at, updated_at
+FROM
+	workspace_dormancy_hooks
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceDormancyHookByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceDormancyHook, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceDormancyHookByWorkspaceID, workspaceID)
+	var i WorkspaceDormancyHook
 	err := row.Scan(
+		&i.WorkspaceID,
+		&i.Event,
+		&i.Status,
+		&i.Error,
 		&i.CreatedAt,
-		&i.Transition,
-		&i.TemplateName,
-		&i.OrganizationName,
-		&i.IsPrebuild,
-		&i.AllAgentsReady,
-		&i.LastAgentReadyAt,
-		&i.WorstStatus,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getWorkspaceBuildProvisionerStateByID = `-- name: GetWorkspaceBuildProvisionerStateByID :one
-SELECT
-	workspace_builds.provisioner_state,
-	templates.id AS template_id,
-	templates.organization_id AS template_organization_id,
-	templates.user_acl,
-	templates.group_acl
-FROM
-	workspace_builds
-INNER JOIN
-	workspaces ON workspaces.id = workspace_builds.workspace_id
-INNER JOIN
-	templates ON templates.id = workspaces.template_id
+const updateWorkspaceDormancyHookStatus = `-- name: UpdateWorkspaceDormancyHookStatus :execrows
+UPDATE
+	workspace_dormancy_hooks
+SET
+	status = $1,
+	error = $2,
+	updated_at = $3
 WHERE
-	workspace_builds.id = $1
+	workspace_id = $4
+	AND event = $5
+	AND status = 'running'::workspace_dormancy_hook_status
 `
 
-type GetWorkspaceBuildProvisionerStateByIDRow struct {
-	ProvisionerState       []byte      `db:"provisioner_state" json:"provisioner_state"`
-	TemplateID             uuid.UUID   `db:"template_id" json:"template_id"`
-	TemplateOrganizationID uuid.UUID   `db:"template_organization_id" json:"template_organization_id"`
-	UserACL                TemplateACL `db:"user_acl" json:"user_acl"`
-	GroupACL               TemplateACL `db:"group_acl" json:"group_acl"`
+type UpdateWorkspaceDormancyHookStatusParams struct {
+	Status      WorkspaceDormancyHookStatus `db:"status" json:"status"`
+	Error       string                      `db:"error" json:"error"`
+	Now         time.Time                   `db:"now" json:"now"`
+	WorkspaceID uuid.UUID                   `db:"workspace_id" json:"workspace_id"`
+	Event       WorkspaceDormancyHookEvent  `db:"event" json:"event"`
 }
 
-// Fetches the provisioner state of a workspace build, joined through to the
-// template so that dbauthz can enforce policy.ActionUpdate on the template.
-// Provisioner state contains sensitive Terraform state and should only be
-// accessible to template administrators.
-func (q *sqlQuerier) GetWorkspaceBuildProvisionerStateByID(ctx context.Context, workspaceBuildID uuid.UUID) (GetWorkspaceBuildProvisionerStateByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceBuildProvisionerStateByID, workspaceBuildID)
-	var i GetWorkspaceBuildProvisionerStateByIDRow
+// Records the outcome of a running hook. Nothing is updated if the hook was
+// replaced by another event in the meantime.
+func (q *sqlQuerier) UpdateWorkspaceDormancyHookStatus(ctx context.Context, arg UpdateWorkspaceDormancyHookStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateWorkspaceDormancyHookStatus,
+		arg.Status,
+		arg.Error,
+		arg.Now,
+		arg.WorkspaceID,
+		arg.Event,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertWorkspaceDormancyHook = `-- name: UpsertWorkspaceDormancyHook :one
+INSERT INTO workspace_dormancy_hooks (
+	workspace_id,
+	event,
+	status,
+	error,
+	created_at,
+	updated_at
+) VALUES (
+	$1,
+	$2,
+	'pending'::workspace_dormancy_hook_status,
+	'',
+	$3,
+	$3
+)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	event = EXCLUDED.event,
+	status = EXCLUDED.status,
+	error = EXCLUDED.error,
+	created_at = EXCLUDED.created_at,
+	updated_at = EXCLUDED.updated_at
+RETURNING workspace_id, event, status, error, created_at, updated_at
+`
+
+type UpsertWorkspaceDormancyHookParams struct {
+	WorkspaceID uuid.UUID                  `db:"workspace_id" json:"workspace_id"`
+	Event       WorkspaceDormancyHookEvent `db:"event" json:"event"`
+	Now         time.Time                  `db:"now" json:"now"`
+}
+
+// Schedules the hook of a workspace for the given event, replacing any
+// earlier invocation. The hook runner picks it up on its next tick.
+func (q *sqlQuerier) UpsertWorkspaceDormancyHook(ctx context.Context, arg UpsertWorkspaceDormancyHookParams) (WorkspaceDormancyHook, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceDormancyHook, arg.WorkspaceID, arg.Event, arg.Now)
+	var i WorkspaceDormancyHook
 	err := row.Scan(
-		&i.ProvisionerState,
-		&i.TemplateID,
-		&i.TemplateOrganizationID,
-		&i.UserACL,
-		&i.GroupACL,
+		&i.WorkspaceID,
+		&i.Event,
+		&i.Status,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getWorkspaceBuildStatsByTemplates = `-- name: GetWorkspaceBuildStatsByTemplates :many
+const getWorkspaceEgressInsights = `-- name: GetWorkspaceEgressInsights :many
 SELECT
-    w.template_id,
+	wed.workspace_id,
+	w.name AS workspace_name,
+	wed.owner_id,
+	u.username AS owner_username,
+	wed.template_id,
 	t.name AS template_name,
-	t.display_name AS template_display_name,
-	t.organization_id AS template_organization_id,
-    COUNT(*) AS total_builds,
-    COUNT(CASE WHEN pj.job_status = 'failed' THEN 1 END) AS failed_builds
+	wed.date,
+	wed.rx_bytes_ssh,
+	wed.tx_bytes_ssh,
+	wed.rx_bytes_reconnecting_pty,
+	wed.tx_bytes_reconnecting_pty,
+	wed.rx_bytes_port_forward,
+	wed.tx_bytes_port_forward
 FROM
-    workspace_build_with_user AS wb
+	workspace_egress_daily wed
 JOIN
-    workspaces AS w ON
-    wb.workspace_id = w.id
+	workspaces w ON w.id = wed.workspace_id
 JOIN
-    provisioner_jobs AS pj ON
-    wb.job_id = pj.id
+	users u ON u.id = wed.owner_id
 JOIN
-    templates AS t ON
-	w.template_id = t.id
+	templates t ON t.id = wed.template_id
 WHERE
-    wb.created_at >= $1
-    AND pj.completed_at IS NOT NULL
-GROUP BY
-    w.template_id, template_name, template_display_name, template_organization_id
+	wed.date >= ($1::timestamptz AT TIME ZONE 'UTC')::date
+	AND (wed.date::timestamp AT TIME ZONE 'UTC') < $2::timestamptz
+	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN wed.template_id = ANY($3::uuid[]) ELSE TRUE END
 ORDER BY
-    template_name ASC
+	wed.date, u.username, w.name
 `
 
-type GetWorkspaceBuildStatsByTemplatesRow struct {
+type GetWorkspaceEgressInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetWorkspaceEgressInsightsRow struct {
+	WorkspaceID            uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName          string    `db:"workspace_name" json:"workspace_name"`
+	OwnerID                uuid.UUID `db:"owner_id" json:"owner_id"`
+	OwnerUsername          string    `db:"owner_username" json:"owner_username"`
 	TemplateID             uuid.UUID `db:"template_id" json:"template_id"`
 	TemplateName           string    `db:"template_name" json:"template_name"`
-	TemplateDisplayName    string    `db:"template_display_name" json:"template_display_name"`
-	TemplateOrganizationID uuid.UUID `db:"template_organization_id" json:"template_organization_id"`
-	TotalBuilds            int64     `db:"total_builds" json:"total_builds"`
-	FailedBuilds           int64     `db:"failed_builds" json:"failed_builds"`
+	Date                   time.Time `db:"date" json:"date"`
+	RxBytesSsh             int64     `db:"rx_bytes_ssh" json:"rx_bytes_ssh"`
+	TxBytesSsh             int64     `db:"tx_bytes_ssh" json:"tx_bytes_ssh"`
+	RxBytesReconnectingPty int64     `db:"rx_bytes_reconnecting_pty" json:"rx_bytes_reconnecting_pty"`
+	TxBytesReconnectingPty int64     `db:"tx_bytes_reconnecting_pty" json:"tx_bytes_reconnecting_pty"`
+	RxBytesPortForward     int64     `db:"rx_bytes_port_forward" json:"rx_bytes_port_forward"`
+	TxBytesPortForward     int64     `db:"tx_bytes_port_forward" json:"tx_bytes_port_forward"`
 }
 
-func (q *sqlQuerier) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]GetWorkspaceBuildStatsByTemplatesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildStatsByTemplates, since)
+// Returns per workspace, per day egress for the UTC days that overlap
+// [start_time, end_time), optionally filtered by template.
+func (q *sqlQuerier) GetWorkspaceEgressInsights(ctx context.Context, arg GetWorkspaceEgressInsightsParams) ([]GetWorkspaceEgressInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceEgressInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceBuildStatsByTemplatesRow
+	var items []GetWorkspaceEgressInsightsRow
 	for rows.Next() {
-		var i GetWorkspaceBuildStatsByTemplatesRow
+		var i GetWorkspaceEgressInsightsRow
 		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.OwnerUsername,
 			&i.TemplateID,
 			&i.TemplateName,
-			&i.TemplateDisplayName,
-			&i.TemplateOrganizationID,
-			&i.TotalBuilds,
-			&i.FailedBuilds,
+			&i.Date,
+			&i.RxBytesSsh,
+			&i.TxBytesSsh,
+			&i.RxBytesReconnectingPty,
+			&i.TxBytesReconnectingPty,
+			&i.RxBytesPortForward,
+			&i.TxBytesPortForward,
 		); err != nil {
 			return nil, err
 		}
@@ -37027,84 +44871,132 @@ func (q *sqlQuerier) GetWorkspaceBuildStatsByTemplates(ctx context.Context, sinc
 	return items, nil
 }
 
-const getWorkspaceBuildsByWorkspaceID = `-- name: GetWorkspaceBuildsByWorkspaceID :many
+const upsertWorkspaceEgressDaily = `-- name: UpsertWorkspaceEgressDaily :exec
+INSERT INTO
+	workspace_egress_daily (
+		workspace_id,
+		template_id,
+		owner_id,
+		date,
+		rx_bytes_ssh,
+		tx_bytes_ssh,
+		rx_bytes_reconnecting_pty,
+		tx_bytes_reconnecting_pty,
+		rx_bytes_port_forward,
+		tx_bytes_port_forward
+	)
+VALUES
+	(
+		$1,
+		$2,
+		$3,
+		$4::date,
+		$5,
+		$6,
+		$7,
+		$8,
+		$9,
+		$10
+	)
+ON CONFLICT (workspace_id, date)
+DO UPDATE SET
+	rx_bytes_ssh = workspace_egress_daily.rx_bytes_ssh + EXCLUDED.rx_bytes_ssh,
+	tx_bytes_ssh = workspace_egress_daily.tx_bytes_ssh + EXCLUDED.tx_bytes_ssh,
+	rx_bytes_reconnecting_pty = workspace_egress_daily.rx_bytes_reconnecting_pty + EXCLUDED.rx_bytes_reconnecting_pty,
+	tx_bytes_reconnecting_pty = workspace_egress_daily.tx_bytes_reconnecting_pty + EXCLUDED.tx_bytes_reconnecting_pty,
+	rx_bytes_port_forward = workspace_egress_daily.rx_bytes_port_forward + EXCLUDED.rx_bytes_port_forward,
+	tx_bytes_port_forward = workspace_egress_daily.tx_bytes_port_forward + EXCLUDED.tx_bytes_port_forward
+`
+
+type UpsertWorkspaceEgressDailyParams struct {
+	WorkspaceID            uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateID             uuid.UUID `db:"template_id" json:"template_id"`
+	OwnerID                uuid.UUID `db:"owner_id" json:"owner_id"`
+	Date                   time.Time `db:"date" json:"date"`
+	RxBytesSsh             int64     `db:"rx_bytes_ssh" json:"rx_bytes_ssh"`
+	TxBytesSsh             int64     `db:"tx_bytes_ssh" json:"tx_bytes_ssh"`
+	RxBytesReconnectingPty int64     `db:"rx_bytes_reconnecting_pty" json:"rx_bytes_reconnecting_pty"`
+	TxBytesReconnectingPty int64     `db:"tx_bytes_reconnecting_pty" json:"tx_bytes_reconnecting_pty"`
+	RxBytesPortForward     int64     `db:"rx_bytes_port_forward" json:"rx_bytes_port_forward"`
+	TxBytesPortForward     int64     `db:"tx_bytes_port_forward" json:"tx_bytes_port_forward"`
+}
+
+// Adds the bytes from a single agent stats report to the workspace's total
+// for the given day.
+func (q *sqlQuerier) UpsertWorkspaceEgressDaily(ctx context.Context, arg UpsertWorkspaceEgressDailyParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceEgressDaily,
+		arg.WorkspaceID,
+		arg.TemplateID,
+		arg.OwnerID,
+		arg.Date,
+		arg.RxBytesSsh,
+		arg.TxBytesSsh,
+		arg.RxBytesReconnectingPty,
+		arg.TxBytesReconnectingPty,
+		arg.RxBytesPortForward,
+		arg.TxBytesPortForward,
+	)
+	return err
+}
+
+const countActiveWorkspaceLeasesByAPIKeyID = `-- name: CountActiveWorkspaceLeasesByAPIKeyID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	COUNT(*)
 FROM
-	workspace_build_with_user AS workspace_builds
+	workspace_leases
 WHERE
-	workspace_builds.workspace_id = $1
-	AND workspace_builds.created_at > $2
-    AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the build_number field, so select all
-			-- rows after the cursor.
-			build_number > (
-				SELECT
-					build_number
-				FROM
-					workspace_builds
-				WHERE
-					id = $3
-			)
-		)
-		ELSE true
-END
+	api_key_id = $1
+	AND released_at IS NULL
+`
+
+// Counts the leases created by an API key that have not been released yet.
+// Expired leases count until the reaper releases them, so a token cannot
+// exceed its cap while teardown is pending.
+func (q *sqlQuerier) CountActiveWorkspaceLeasesByAPIKeyID(ctx context.Context, apiKeyID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveWorkspaceLeasesByAPIKeyID, apiKeyID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getExpiredWorkspaceLeases = `-- name: GetExpiredWorkspaceLeases :many
+SELECT
+	id, workspace_id, template_id, owner_id, api_key_id, credential_key_id, created_at, expires_at, released_at
+FROM
+	workspace_leases
+WHERE
+	released_at IS NULL
+	AND expires_at <= $1::timestamptz
 ORDER BY
-    build_number desc OFFSET $4
+	expires_at ASC
 LIMIT
-    -- A null limit means "no limit", so 0 means return all
-    NULLIF($5 :: int, 0)
+	$2::int
 `
 
-type GetWorkspaceBuildsByWorkspaceIDParams struct {
-	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	Since       time.Time `db:"since" json:"since"`
-	AfterID     uuid.UUID `db:"after_id" json:"after_id"`
-	OffsetOpt   int32     `db:"offset_opt" json:"offset_opt"`
-	LimitOpt    int32     `db:"limit_opt" json:"limit_opt"`
+type GetExpiredWorkspaceLeasesParams struct {
+	Now       time.Time `db:"now" json:"now"`
+	MaxLeases int32     `db:"max_leases" json:"max_leases"`
 }
 
-func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg GetWorkspaceBuildsByWorkspaceIDParams) ([]WorkspaceBuild, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildsByWorkspaceID,
-		arg.WorkspaceID,
-		arg.Since,
-		arg.AfterID,
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
+func (q *sqlQuerier) GetExpiredWorkspaceLeases(ctx context.Context, arg GetExpiredWorkspaceLeasesParams) ([]WorkspaceLease, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiredWorkspaceLeases, arg.Now, arg.MaxLeases)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceBuild
+	var items []WorkspaceLease
 	for rows.Next() {
-		var i WorkspaceBuild
+		var i WorkspaceLease
 		if err := rows.Scan(
 			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
 			&i.WorkspaceID,
-			&i.TemplateVersionID,
-			&i.BuildNumber,
-			&i.Transition,
-			&i.InitiatorID,
-			&i.JobID,
-			&i.Deadline,
-			&i.Reason,
-			&i.DailyCost,
-			&i.MaxDeadline,
-			&i.TemplateVersionPresetID,
-			&i.HasAITask,
-			&i.HasExternalAgent,
-			&i.NotifiedAutostopDeadline,
-			&i.InitiatorByAvatarUrl,
-			&i.InitiatorByUsername,
-			&i.InitiatorByName,
+			&i.TemplateID,
+			&i.OwnerID,
+			&i.APIKeyID,
+			&i.CredentialKeyID,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.ReleasedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -37119,236 +45011,178 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 	return items, nil
 }
 
-const getWorkspaceBuildsCreatedAfter = `-- name: GetWorkspaceBuildsCreatedAfter :many
-SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, has_external_agent, notified_autostop_deadline, initiator_by_avatar_url, initiator_by_username, initiator_by_name FROM workspace_build_with_user WHERE created_at > $1
+const getWorkspaceLeaseByID = `-- name: GetWorkspaceLeaseByID :one
+SELECT
+	id, workspace_id, template_id, owner_id, api_key_id, credential_key_id, created_at, expires_at, released_at
+FROM
+	workspace_leases
+WHERE
+	id = $1
 `
 
-func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildsCreatedAfter, createdAt)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []WorkspaceBuild
-	for rows.Next() {
-		var i WorkspaceBuild
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.WorkspaceID,
-			&i.TemplateVersionID,
-			&i.BuildNumber,
-			&i.Transition,
-			&i.InitiatorID,
-			&i.JobID,
-			&i.Deadline,
-			&i.Reason,
-			&i.DailyCost,
-			&i.MaxDeadline,
-			&i.TemplateVersionPresetID,
-			&i.HasAITask,
-			&i.HasExternalAgent,
-			&i.NotifiedAutostopDeadline,
-			&i.InitiatorByAvatarUrl,
-			&i.InitiatorByUsername,
-			&i.InitiatorByName,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) GetWorkspaceLeaseByID(ctx context.Context, id uuid.UUID) (WorkspaceLease, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceLeaseByID, id)
+	var i WorkspaceLease
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.TemplateID,
+		&i.OwnerID,
+		&i.APIKeyID,
+		&i.CredentialKeyID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.ReleasedAt,
+	)
+	return i, err
 }
 
-const insertWorkspaceBuild = `-- name: InsertWorkspaceBuild :exec
+const insertWorkspaceLease = `-- name: InsertWorkspaceLease :one
 INSERT INTO
-	workspace_builds (
+	workspace_leases (
 		id,
-		created_at,
-		updated_at,
 		workspace_id,
-		template_version_id,
-		"build_number",
-		transition,
-		initiator_id,
-		job_id,
-		provisioner_state,
-		deadline,
-		max_deadline,
-		reason,
-		template_version_preset_id
+		template_id,
+		owner_id,
+		api_key_id,
+		credential_key_id,
+		created_at,
+		expires_at
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, workspace_id, template_id, owner_id, api_key_id, credential_key_id, created_at, expires_at, released_at
 `
 
-type InsertWorkspaceBuildParams struct {
-	ID                      uuid.UUID           `db:"id" json:"id"`
-	CreatedAt               time.Time           `db:"created_at" json:"created_at"`
-	UpdatedAt               time.Time           `db:"updated_at" json:"updated_at"`
-	WorkspaceID             uuid.UUID           `db:"workspace_id" json:"workspace_id"`
-	TemplateVersionID       uuid.UUID           `db:"template_version_id" json:"template_version_id"`
-	BuildNumber             int32               `db:"build_number" json:"build_number"`
-	Transition              WorkspaceTransition `db:"transition" json:"transition"`
-	InitiatorID             uuid.UUID           `db:"initiator_id" json:"initiator_id"`
-	JobID                   uuid.UUID           `db:"job_id" json:"job_id"`
-	ProvisionerState        []byte              `db:"provisioner_state" json:"provisioner_state"`
-	Deadline                time.Time           `db:"deadline" json:"deadline"`
-	MaxDeadline             time.Time           `db:"max_deadline" json:"max_deadline"`
-	Reason                  BuildReason         `db:"reason" json:"reason"`
-	TemplateVersionPresetID uuid.NullUUID       `db:"template_version_preset_id" json:"template_version_preset_id"`
+type InsertWorkspaceLeaseParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	WorkspaceID     uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateID      uuid.UUID `db:"template_id" json:"template_id"`
+	OwnerID         uuid.UUID `db:"owner_id" json:"owner_id"`
+	APIKeyID        string    `db:"api_key_id" json:"api_key_id"`
+	CredentialKeyID string    `db:"credential_key_id" json:"credential_key_id"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt       time.Time `db:"expires_at" json:"expires_at"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceBuild,
+func (q *sqlQuerier) InsertWorkspaceLease(ctx context.Context, arg InsertWorkspaceLeaseParams) (WorkspaceLease, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceLease,
 		arg.ID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
 		arg.WorkspaceID,
-		arg.TemplateVersionID,
-		arg.BuildNumber,
-		arg.Transition,
-		arg.InitiatorID,
-		arg.JobID,
-		arg.ProvisionerState,
-		arg.Deadline,
-		arg.MaxDeadline,
-		arg.Reason,
-		arg.TemplateVersionPresetID,
+		arg.TemplateID,
+		arg.OwnerID,
+		arg.APIKeyID,
+		arg.CredentialKeyID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
 	)
-	return err
+	var i WorkspaceLease
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.TemplateID,
+		&i.OwnerID,
+		&i.APIKeyID,
+		&i.CredentialKeyID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.ReleasedAt,
+	)
+	return i, err
 }
 
-const updateWorkspaceBuildCostByID = `-- name: UpdateWorkspaceBuildCostByID :exec
+const releaseWorkspaceLease = `-- name: ReleaseWorkspaceLease :one
 UPDATE
-	workspace_builds
+	workspace_leases
 SET
-	daily_cost = $2
+	released_at = $1
 WHERE
-	id = $1
+	id = $2
+	AND released_at IS NULL
+RETURNING id, workspace_id, template_id, owner_id, api_key_id, credential_key_id, created_at, expires_at, released_at
 `
 
-type UpdateWorkspaceBuildCostByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+type ReleaseWorkspaceLeaseParams struct {
+	ReleasedAt sql.NullTime `db:"released_at" json:"released_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
-	return err
+// Marks a lease as released. Returns no rows if the lease was already
+// released, so concurrent releases tear the workspace down only once.
+func (q *sqlQuerier) ReleaseWorkspaceLease(ctx context.Context, arg ReleaseWorkspaceLeaseParams) (WorkspaceLease, error) {
+	row := q.db.QueryRowContext(ctx, releaseWorkspaceLease, arg.ReleasedAt, arg.ID)
+	var i WorkspaceLease
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.TemplateID,
+		&i.OwnerID,
+		&i.APIKeyID,
+		&i.CredentialKeyID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.ReleasedAt,
+	)
+	return i, err
 }
 
-const updateWorkspaceBuildDeadlineByID = `-- name: UpdateWorkspaceBuildDeadlineByID :exec
-UPDATE
-	workspace_builds
-SET
-	deadline = $1::timestamptz,
-	max_deadline = $2::timestamptz,
-	updated_at = $3::timestamptz
+const getWorkspaceLifetimeWarningByWorkspaceID = `-- name: GetWorkspaceLifetimeWarningByWorkspaceID :one
+SELECT
+	workspace_id, expires_at, days_before, sent_at
 FROM
-	workspaces
+	workspace_lifetime_warnings
 WHERE
-	workspace_builds.id = $4::uuid
-	AND workspace_builds.workspace_id = workspaces.id
-	-- Prebuilt workspaces (identified by having the prebuilds system user as owner_id)
-	-- are managed by the reconciliation loop, not the lifecycle executor which handles
-	-- deadline and max_deadline
-	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
+	workspace_id = $1
 `
 
-type UpdateWorkspaceBuildDeadlineByIDParams struct {
-	Deadline    time.Time `db:"deadline" json:"deadline"`
-	MaxDeadline time.Time `db:"max_deadline" json:"max_deadline"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	ID          uuid.UUID `db:"id" json:"id"`
-}
-
-func (q *sqlQuerier) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildDeadlineByID,
-		arg.Deadline,
-		arg.MaxDeadline,
-		arg.UpdatedAt,
-		arg.ID,
+func (q *sqlQuerier) GetWorkspaceLifetimeWarningByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceLifetimeWarning, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceLifetimeWarningByWorkspaceID, workspaceID)
+	var i WorkspaceLifetimeWarning
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.ExpiresAt,
+		&i.DaysBefore,
+		&i.SentAt,
 	)
-	return err
-}
-
-const updateWorkspaceBuildFlagsByID = `-- name: UpdateWorkspaceBuildFlagsByID :exec
-UPDATE
-	workspace_builds
-SET
-	has_ai_task = $1,
-	has_external_agent = $2,
-	updated_at = $3::timestamptz
-WHERE id = $4::uuid
-`
-
-type UpdateWorkspaceBuildFlagsByIDParams struct {
-	HasAITask        sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
-	HasExternalAgent sql.NullBool `db:"has_external_agent" json:"has_external_agent"`
-	UpdatedAt        time.Time    `db:"updated_at" json:"updated_at"`
-	ID               uuid.UUID    `db:"id" json:"id"`
+	return i, err
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildFlagsByID(ctx context.Context, arg UpdateWorkspaceBuildFlagsByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildFlagsByID,
-		arg.HasAITask,
-		arg.HasExternalAgent,
-		arg.UpdatedAt,
-		arg.ID,
+const upsertWorkspaceLifetimeWarning = `-- name: UpsertWorkspaceLifetimeWarning :exec
+INSERT INTO
+	workspace_lifetime_warnings (
+		workspace_id,
+		expires_at,
+		days_before,
+		sent_at
 	)
-	return err
-}
-
-const updateWorkspaceBuildNotifiedAutostopDeadline = `-- name: UpdateWorkspaceBuildNotifiedAutostopDeadline :exec
-UPDATE
-	workspace_builds
-SET
-	notified_autostop_deadline = $1::timestamptz,
-	updated_at = $2::timestamptz
-WHERE id = $3::uuid
-`
-
-type UpdateWorkspaceBuildNotifiedAutostopDeadlineParams struct {
-	NotifiedAutostopDeadline time.Time `db:"notified_autostop_deadline" json:"notified_autostop_deadline"`
-	UpdatedAt                time.Time `db:"updated_at" json:"updated_at"`
-	ID                       uuid.UUID `db:"id" json:"id"`
-}
-
-// Stamps the deadline value that an autostop reminder was last sent for. Once
-// this equals the build's deadline the reminder is considered handled and the
-// lifecycle executor will not send another for this deadline, which makes the
-// reminder idempotent and HA-safe. It re-arms automatically when the deadline
-// changes (e.g. an activity bump).
-func (q *sqlQuerier) UpdateWorkspaceBuildNotifiedAutostopDeadline(ctx context.Context, arg UpdateWorkspaceBuildNotifiedAutostopDeadlineParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildNotifiedAutostopDeadline, arg.NotifiedAutostopDeadline, arg.UpdatedAt, arg.ID)
-	return err
-}
-
-const updateWorkspaceBuildProvisionerStateByID = `-- name: UpdateWorkspaceBuildProvisionerStateByID :exec
-UPDATE
-	workspace_builds
-SET
-	provisioner_state = $1::bytea,
-	updated_at = $2::timestamptz
-WHERE id = $3::uuid
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4
+)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	expires_at = $2,
+	days_before = $3,
+	sent_at = $4
 `
 
-type UpdateWorkspaceBuildProvisionerStateByIDParams struct {
-	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
-	ID               uuid.UUID `db:"id" json:"id"`
+type UpsertWorkspaceLifetimeWarningParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+	DaysBefore  int32     `db:"days_before" json:"days_before"`
+	SentAt      time.Time `db:"sent_at" json:"sent_at"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID, arg.ProvisionerState, arg.UpdatedAt, arg.ID)
+// Records that the owner of a workspace was warned about its max lifetime
+// expiry. Only the most recent warning is kept.
+func (q *sqlQuerier) UpsertWorkspaceLifetimeWarning(ctx context.Context, arg UpsertWorkspaceLifetimeWarningParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceLifetimeWarning,
+		arg.WorkspaceID,
+		arg.ExpiresAt,
+		arg.DaysBefore,
+		arg.SentAt,
+	)
 	return err
 }
 
@@ -37467,6 +45301,253 @@ func (q *sqlQuerier) InsertWorkspaceModule(ctx context.Context, arg InsertWorksp
 	return i, err
 }
 
+const deleteWorkspacePortForwardACL = `-- name: DeleteWorkspacePortForwardACL :exec
+DELETE FROM
+	workspace_port_forward_acls
+WHERE
+	workspace_id = $1
+	AND user_id = $2
+`
+
+type DeleteWorkspacePortForwardACLParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteWorkspacePortForwardACL(ctx context.Context, arg DeleteWorkspacePortForwardACLParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePortForwardACL, arg.WorkspaceID, arg.UserID)
+	return err
+}
+
+const deleteWorkspacePortForwardACLsByWorkspaceID = `-- name: DeleteWorkspacePortForwardACLsByWorkspaceID :exec
+DELETE FROM
+	workspace_port_forward_acls
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePortForwardACLsByWorkspaceID, workspaceID)
+	return err
+}
+
+const getWorkspacePortForwardACL = `-- name: GetWorkspacePortForwardACL :one
+SELECT
+	workspace_id, user_id, ports, created_at, updated_at
+FROM
+	workspace_port_forward_acls
+WHERE
+	workspace_id = $1
+	AND user_id = $2
+`
+
+type GetWorkspacePortForwardACLParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) GetWorkspacePortForwardACL(ctx context.Context, arg GetWorkspacePortForwardACLParams) (WorkspacePortForwardACL, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspacePortForwardACL, arg.WorkspaceID, arg.UserID)
+	var i WorkspacePortForwardACL
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.UserID,
+		pq.Array(&i.Ports),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWorkspacePortForwardACLsByWorkspaceID = `-- name: GetWorkspacePortForwardACLsByWorkspaceID :many
+SELECT
+	workspace_id, user_id, ports, created_at, updated_at
+FROM
+	workspace_port_forward_acls
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at, user_id
+`
+
+func (q *sqlQuerier) GetWorkspacePortForwardACLsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortForwardACL, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePortForwardACLsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspacePortForwardACL
+	for rows.Next() {
+		var i WorkspacePortForwardACL
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.UserID,
+			pq.Array(&i.Ports),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspacePortForwardACL = `-- name: UpsertWorkspacePortForwardACL :one
+INSERT INTO
+	workspace_port_forward_acls (
+		workspace_id,
+		user_id,
+		ports,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $4)
+ON CONFLICT (workspace_id, user_id) DO UPDATE SET
+	ports = EXCLUDED.ports,
+	updated_at = EXCLUDED.updated_at
+RETURNING workspace_id, user_id, ports, created_at, updated_at
+`
+
+type UpsertWorkspacePortForwardACLParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Ports       []int32   `db:"ports" json:"ports"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspacePortForwardACL(ctx context.Context, arg UpsertWorkspacePortForwardACLParams) (WorkspacePortForwardACL, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspacePortForwardACL,
+		arg.WorkspaceID,
+		arg.UserID,
+		pq.Array(arg.Ports),
+		arg.CreatedAt,
+	)
+	var i WorkspacePortForwardACL
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.UserID,
+		pq.Array(&i.Ports),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceRegionAssignmentCounts = `-- name: GetWorkspaceRegionAssignmentCounts :many
+SELECT
+	workspace_region_assignments.region_id,
+	COUNT(*) AS count
+FROM
+	workspace_region_assignments
+JOIN
+	workspaces ON workspaces.id = workspace_region_assignments.workspace_id
+WHERE
+	workspaces.deleted = false
+GROUP BY
+	workspace_region_assignments.region_id
+`
+
+type GetWorkspaceRegionAssignmentCountsRow struct {
+	RegionID uuid.UUID `db:"region_id" json:"region_id"`
+	Count    int64     `db:"count" json:"count"`
+}
+
+// Returns the number of non-deleted workspaces assigned to each region.
+func (q *sqlQuerier) GetWorkspaceRegionAssignmentCounts(ctx context.Context) ([]GetWorkspaceRegionAssignmentCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceRegionAssignmentCounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceRegionAssignmentCountsRow
+	for rows.Next() {
+		var i GetWorkspaceRegionAssignmentCountsRow
+		if err := rows.Scan(&i.RegionID, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceRegionAssignmentsByWorkspaceIDs = `-- name: GetWorkspaceRegionAssignmentsByWorkspaceIDs :many
+SELECT
+	workspace_id, region_id, created_at
+FROM
+	workspace_region_assignments
+WHERE
+	workspace_id = ANY($1::uuid[])
+`
+
+func (q *sqlQuerier) GetWorkspaceRegionAssignmentsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceRegionAssignment, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceRegionAssignmentsByWorkspaceIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceRegionAssignment
+	for rows.Next() {
+		var i WorkspaceRegionAssignment
+		if err := rows.Scan(&i.WorkspaceID, &i.RegionID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceRegionAssignment = `-- name: UpsertWorkspaceRegionAssignment :one
+INSERT INTO
+	workspace_region_assignments (
+		workspace_id,
+		region_id,
+		created_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3
+)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	region_id = $2,
+	created_at = $3
+RETURNING workspace_id, region_id, created_at
+`
+
+type UpsertWorkspaceRegionAssignmentParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	RegionID    uuid.UUID `db:"region_id" json:"region_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceRegionAssignment(ctx context.Context, arg UpsertWorkspaceRegionAssignmentParams) (WorkspaceRegionAssignment, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceRegionAssignment, arg.WorkspaceID, arg.RegionID, arg.CreatedAt)
+	var i WorkspaceRegionAssignment
+	err := row.Scan(&i.WorkspaceID, &i.RegionID, &i.CreatedAt)
+	return i, err
+}
+
 const getWorkspaceResourceByID = `-- name: GetWorkspaceResourceByID :one
 SELECT
 	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, module_path
@@ -37981,6 +46062,99 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 	return i, err
 }
 
+const getOrphanedWorkspaces = `-- name: GetOrphanedWorkspaces :many
+SELECT
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl, workspaces.expires_at, workspaces.archived_at, workspaces.read_only_at,
+	users.username AS owner_username,
+	users.deleted AS owner_deleted,
+	organizations.name AS organization_name,
+	templates.name AS template_name
+FROM
+	workspaces
+	JOIN users ON users.id = workspaces.owner_id
+	JOIN organizations ON organizations.id = workspaces.organization_id
+	JOIN templates ON templates.id = workspaces.template_id
+WHERE
+	workspaces.deleted = false
+	AND users.is_system = false
+	AND (
+		users.deleted = true
+		OR NOT EXISTS (
+			SELECT
+				1
+			FROM
+				organization_members
+			WHERE
+				organization_members.user_id = workspaces.owner_id
+				AND organization_members.organization_id = workspaces.organization_id
+		)
+	)
+ORDER BY
+	workspaces.created_at ASC,
+	workspaces.id ASC
+`
+
+type GetOrphanedWorkspacesRow struct {
+	WorkspaceTable   WorkspaceTable `db:"workspace_table" json:"workspace_table"`
+	OwnerUsername    string         `db:"owner_username" json:"owner_username"`
+	OwnerDeleted     bool           `db:"owner_deleted" json:"owner_deleted"`
+	OrganizationName string         `db:"organization_name" json:"organization_name"`
+	TemplateName     string         `db:"template_name" json:"template_name"`
+}
+
+// Returns the workspaces whose owner has been deleted, or is no longer a
+// member of the organization of the workspace. Such workspaces can no longer
+// be managed by their owner, and are left behind when users are removed
+// outside of the normal offboarding flow.
+func (q *sqlQuerier) GetOrphanedWorkspaces(ctx context.Context) ([]GetOrphanedWorkspacesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrphanedWorkspaces)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrphanedWorkspacesRow
+	for rows.Next() {
+		var i GetOrphanedWorkspacesRow
+		if err := rows.Scan(
+			&i.WorkspaceTable.ID,
+			&i.WorkspaceTable.CreatedAt,
+			&i.WorkspaceTable.UpdatedAt,
+			&i.WorkspaceTable.OwnerID,
+			&i.WorkspaceTable.OrganizationID,
+			&i.WorkspaceTable.TemplateID,
+			&i.WorkspaceTable.Deleted,
+			&i.WorkspaceTable.Name,
+			&i.WorkspaceTable.AutostartSchedule,
+			&i.WorkspaceTable.Ttl,
+			&i.WorkspaceTable.LastUsedAt,
+			&i.WorkspaceTable.DormantAt,
+			&i.WorkspaceTable.DeletingAt,
+			&i.WorkspaceTable.AutomaticUpdates,
+			&i.WorkspaceTable.Favorite,
+			&i.WorkspaceTable.NextStartAt,
+			&i.WorkspaceTable.GroupACL,
+			&i.WorkspaceTable.UserACL,
+			&i.WorkspaceTable.ExpiresAt,
+			&i.WorkspaceTable.ArchivedAt,
+			&i.WorkspaceTable.ReadOnlyAt,
+			&i.OwnerUsername,
+			&i.OwnerDeleted,
+			&i.OrganizationName,
+			&i.TemplateName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRegularWorkspaceCreateMetrics = `-- name: GetRegularWorkspaceCreateMetrics :many
 WITH first_success_build AS (
 	-- Earliest successful 'start' build per workspace
@@ -38185,7 +46359,7 @@ func (q *sqlQuerier) GetWorkspaceACLByID(ctx context.Context, id uuid.UUID) (Get
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -38235,6 +46409,9 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.NextStartAt,
 		&i.GroupACL,
 		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -38255,7 +46432,7 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
 FROM
 	workspaces_expanded
 WHERE
@@ -38286,6 +46463,9 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.NextStartAt,
 		&i.GroupACL,
 		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -38306,7 +46486,7 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -38344,6 +46524,9 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.NextStartAt,
 		&i.GroupACL,
 		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -38364,7 +46547,7 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 
 const getWorkspaceByResourceID = `-- name: GetWorkspaceByResourceID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -38409,6 +46592,9 @@ func (q *sqlQuerier) GetWorkspaceByResourceID(ctx context.Context, resourceID uu
 		&i.NextStartAt,
 		&i.GroupACL,
 		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -38429,7 +46615,7 @@ func (q *sqlQuerier) GetWorkspaceByResourceID(ctx context.Context, resourceID uu
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description, task_id, group_acl_display_info, user_acl_display_info
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -38486,6 +46672,9 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.NextStartAt,
 		&i.GroupACL,
 		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -38549,7 +46738,7 @@ SELECT
 ),
 filtered_workspaces AS (
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl, workspaces.owner_avatar_url, workspaces.owner_username, workspaces.owner_name, workspaces.organization_name, workspaces.organization_display_name, workspaces.organization_icon, workspaces.organization_description, workspaces.template_name, workspaces.template_display_name, workspaces.template_icon, workspaces.template_description, workspaces.task_id, workspaces.group_acl_display_info, workspaces.user_acl_display_info,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl, workspaces.expires_at, workspaces.archived_at, workspaces.read_only_at, workspaces.owner_avatar_url, workspaces.owner_username, workspaces.owner_name, workspaces.organization_name, workspaces.organization_display_name, workspaces.organization_icon, workspaces.organization_description, workspaces.template_name, workspaces.template_display_name, workspaces.template_icon, workspaces.template_description, workspaces.task_id, workspaces.group_acl_display_info, workspaces.user_acl_display_info,
 	latest_build.template_version_id,
 	latest_build.template_version_name,
 	latest_build.completed_at as latest_build_completed_at,
@@ -38598,7 +46787,7 @@ LEFT JOIN LATERAL (
 ) latest_build ON TRUE
 LEFT JOIN LATERAL (
 	SELECT
-		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, time_til_autostop_notify
+		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, time_til_autostop_notify, provisioner_plan_timeout, provisioner_apply_timeout, trial_workspace_ttl, requeue_reaped_builds, agent_rollout_channel, allow_targeted_builds, reconfirm_parameters, deprecation_cutoff, nightly_stop_time, build_log_retention, max_lifetime, max_lifetime_action, idle_reclaim_ttl, idle_reclaim_resource_selector, activity_bump_connection_types, activity_bump_max_per_day, pre_build_hook_url, post_build_hook_url, auto_assign_region, notify_build_failures, disable_provider_cache
 	FROM
 		templates
 	WHERE
@@ -38692,7 +46881,10 @@ WHERE
 			ON
 				LOWER(workspace_build_parameters.name) = build_params.name AND
 				LOWER(workspace_build_parameters.value) = build_params.value AND
-				workspace_build_parameters.workspace_build_id = latest_build.id
+				workspace_build_parameters.workspace_build_id = latest_build.id AND
+				-- Values of sensitive parameters are encrypted and must not
+				-- be searchable.
+				NOT workspace_build_parameters.sensitive
 		)
 		ELSE true
 	END
@@ -38785,15 +46977,37 @@ WHERE
 				  workspaces.last_used_at >= $17
 		  ELSE true
 	END
+	-- Filter by created_at
+	AND CASE
+		WHEN $18 :: timestamp with time zone > '0001-01-01 00:00:00Z' THEN
+			workspaces.created_at <= $18
+		ELSE true
+	END
+	AND CASE
+		WHEN $19 :: timestamp with time zone > '0001-01-01 00:00:00Z' THEN
+			workspaces.created_at >= $19
+		ELSE true
+	END
+	-- Filter by the template version of the latest build
+	AND CASE
+		WHEN $20 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			latest_build.template_version_id = $20
+		ELSE true
+	END
+	AND CASE
+		WHEN $21 :: text != '' THEN
+			lower(latest_build.template_version_name) = lower($21)
+		ELSE true
+	END
   	AND CASE
-		  WHEN $18 :: boolean IS NOT NULL THEN
-			  (latest_build.template_version_id = template.active_version_id) = $18 :: boolean
+		  WHEN $22 :: boolean IS NOT NULL THEN
+			  (latest_build.template_version_id = template.active_version_id) = $22 :: boolean
 		  ELSE true
 	END
 	-- Filter by has_ai_task, checks if this is a task workspace.
 	AND CASE
-		WHEN $19::boolean IS NOT NULL
-		THEN $19::boolean = EXISTS (
+		WHEN $23::boolean IS NOT NULL
+		THEN $23::boolean = EXISTS (
 			SELECT
 				1
 			FROM
@@ -38807,26 +47021,26 @@ WHERE
 	END
 	-- Filter by has_external_agent in latest build
 	AND CASE
-		WHEN $20 :: boolean IS NOT NULL THEN
-			latest_build.has_external_agent = $20 :: boolean
+		WHEN $24 :: boolean IS NOT NULL THEN
+			latest_build.has_external_agent = $24 :: boolean
 		ELSE true
 	END
 	-- Filter by shared status
 	AND CASE
-		WHEN $21 :: boolean IS NOT NULL THEN
-			(workspaces.user_acl != '{}'::jsonb OR workspaces.group_acl != '{}'::jsonb) = $21 :: boolean
+		WHEN $25 :: boolean IS NOT NULL THEN
+			(workspaces.user_acl != '{}'::jsonb OR workspaces.group_acl != '{}'::jsonb) = $25 :: boolean
 		ELSE true
 	END
 	-- Filter by shared_with_user_id
 	AND CASE
-		WHEN $22 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
-			workspaces.user_acl ? ($22 :: uuid) :: text
+		WHEN $26 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspaces.user_acl ? ($26 :: uuid) :: text
 		ELSE true
 	END
 	-- Filter by shared_with_group_id
 	AND CASE
-		WHEN $23 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
-			workspaces.group_acl ? ($23 :: uuid) :: text
+		WHEN $27 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspaces.group_acl ? ($27 :: uuid) :: text
 		ELSE true
 	END
 
@@ -38834,12 +47048,23 @@ WHERE
 	-- @authorize_filter
 ), filtered_workspaces_order AS (
 	SELECT
-		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.next_start_at, fw.group_acl, fw.user_acl, fw.owner_avatar_url, fw.owner_username, fw.owner_name, fw.organization_name, fw.organization_display_name, fw.organization_icon, fw.organization_description, fw.template_name, fw.template_display_name, fw.template_icon, fw.template_description, fw.task_id, fw.group_acl_display_info, fw.user_acl_display_info, fw.template_version_id, fw.template_version_name, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status, fw.latest_build_has_external_agent
+		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.next_start_at, fw.group_acl, fw.user_acl, fw.expires_at, fw.archived_at, fw.read_only_at, fw.owner_avatar_url, fw.owner_username, fw.owner_name, fw.organization_name, fw.organization_display_name, fw.organization_icon, fw.organization_description, fw.template_name, fw.template_display_name, fw.template_icon, fw.template_description, fw.task_id, fw.group_acl_display_info, fw.user_acl_display_info, fw.template_version_id, fw.template_version_name, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status, fw.latest_build_has_external_agent
 	FROM
 		filtered_workspaces fw
 	ORDER BY
+		-- An explicit sort takes precedence over the default ordering below,
+		-- which then only breaks ties. Each key is a plain column or
+		-- LOWER(name), matching the workspaces_*_idx sort indexes, so that
+		-- once the sort is bound the planner reduces these CASE expressions to
+		-- the one key selected and can read it from the index.
+		CASE WHEN $28 :: text = 'last_used_asc' THEN last_used_at END ASC,
+		CASE WHEN $28 :: text = 'last_used_desc' THEN last_used_at END DESC,
+		CASE WHEN $28 :: text = 'name_asc' THEN LOWER(name) END ASC,
+		CASE WHEN $28 :: text = 'name_desc' THEN LOWER(name) END DESC,
+		CASE WHEN $28 :: text = 'created_at_asc' THEN created_at END ASC,
+		CASE WHEN $28 :: text = 'created_at_desc' THEN created_at END DESC,
 		-- To ensure that 'favorite' workspaces show up first in the list only for their owner.
-		CASE WHEN favorite AND owner_username = (SELECT users.username FROM users WHERE users.id = $24) THEN 0 ELSE 1 END ASC,
+		CASE WHEN favorite AND owner_username = (SELECT users.username FROM users WHERE users.id = $29) THEN 0 ELSE 1 END ASC,
 		(latest_build_completed_at IS NOT NULL AND
 			latest_build_canceled_at IS NULL AND
 			latest_build_error IS NULL AND
@@ -38848,14 +47073,14 @@ WHERE
 		LOWER(name) ASC
 	LIMIT
 		CASE
-			WHEN $26 :: integer > 0 THEN
-				$26
+			WHEN $31 :: integer > 0 THEN
+				$31
 		END
 	OFFSET
-		$25
+		$30
 ), filtered_workspaces_order_with_summary AS (
 	SELECT
-		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.next_start_at, fwo.group_acl, fwo.user_acl, fwo.owner_avatar_url, fwo.owner_username, fwo.owner_name, fwo.organization_name, fwo.organization_display_name, fwo.organization_icon, fwo.organization_description, fwo.template_name, fwo.template_display_name, fwo.template_icon, fwo.template_description, fwo.task_id, fwo.group_acl_display_info, fwo.user_acl_display_info, fwo.template_version_id, fwo.template_version_name, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status, fwo.latest_build_has_external_agent
+		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.next_start_at, fwo.group_acl, fwo.user_acl, fwo.expires_at, fwo.archived_at, fwo.read_only_at, fwo.owner_avatar_url, fwo.owner_username, fwo.owner_name, fwo.organization_name, fwo.organization_display_name, fwo.organization_icon, fwo.organization_description, fwo.template_name, fwo.template_display_name, fwo.template_icon, fwo.template_description, fwo.task_id, fwo.group_acl_display_info, fwo.user_acl_display_info, fwo.template_version_id, fwo.template_version_name, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status, fwo.latest_build_has_external_agent
 	FROM
 		filtered_workspaces_order fwo
 	-- Return a technical summary row with total count of workspaces.
@@ -38880,6 +47105,9 @@ WHERE
 		'0001-01-01 00:00:00+00'::timestamptz, -- next_start_at
 		'{}'::jsonb, -- group_acl
 		'{}'::jsonb, -- user_acl
+		'0001-01-01 00:00:00+00'::timestamptz, -- expires_at
+		'0001-01-01 00:00:00+00'::timestamptz, -- archived_at
+		'0001-01-01 00:00:00+00'::timestamptz, -- read_only_at
 		'', -- owner_avatar_url
 		'', -- owner_username
 		'', -- owner_name
@@ -38904,7 +47132,7 @@ WHERE
 		'unknown'::provisioner_job_status, -- latest_build_status
 		false -- latest_build_has_external_agent
 	WHERE
-		$27 :: boolean = true
+		$32 :: boolean = true
 ), total_count AS (
 	SELECT
 		count(*) AS count
@@ -38912,7 +47140,7 @@ WHERE
 		filtered_workspaces
 )
 SELECT
-	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.next_start_at, fwos.group_acl, fwos.user_acl, fwos.owner_avatar_url, fwos.owner_username, fwos.owner_name, fwos.organization_name, fwos.organization_display_name, fwos.organization_icon, fwos.organization_description, fwos.template_name, fwos.template_display_name, fwos.template_icon, fwos.template_description, fwos.task_id, fwos.group_acl_display_info, fwos.user_acl_display_info, fwos.template_version_id, fwos.template_version_name, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status, fwos.latest_build_has_external_agent,
+	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.next_start_at, fwos.group_acl, fwos.user_acl, fwos.expires_at, fwos.archived_at, fwos.read_only_at, fwos.owner_avatar_url, fwos.owner_username, fwos.owner_name, fwos.organization_name, fwos.organization_display_name, fwos.organization_icon, fwos.organization_description, fwos.template_name, fwos.template_display_name, fwos.template_icon, fwos.template_description, fwos.task_id, fwos.group_acl_display_info, fwos.user_acl_display_info, fwos.template_version_id, fwos.template_version_name, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status, fwos.latest_build_has_external_agent,
 	tc.count
 FROM
 	filtered_workspaces_order_with_summary fwos
@@ -38938,12 +47166,17 @@ type GetWorkspacesParams struct {
 	Dormant                               bool         `db:"dormant" json:"dormant"`
 	LastUsedBefore                        time.Time    `db:"last_used_before" json:"last_used_before"`
 	LastUsedAfter                         time.Time    `db:"last_used_after" json:"last_used_after"`
+	CreatedBefore                         time.Time    `db:"created_before" json:"created_before"`
+	CreatedAfter                          time.Time    `db:"created_after" json:"created_after"`
+	TemplateVersionID                     uuid.UUID    `db:"template_version_id" json:"template_version_id"`
+	TemplateVersionName                   string       `db:"template_version_name" json:"template_version_name"`
 	UsingActive                           sql.NullBool `db:"using_active" json:"using_active"`
 	HasAITask                             sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
 	HasExternalAgent                      sql.NullBool `db:"has_external_agent" json:"has_external_agent"`
 	Shared                                sql.NullBool `db:"shared" json:"shared"`
 	SharedWithUserID                      uuid.UUID    `db:"shared_with_user_id" json:"shared_with_user_id"`
 	SharedWithGroupID                     uuid.UUID    `db:"shared_with_group_id" json:"shared_with_group_id"`
+	Sort                                  string       `db:"sort" json:"sort"`
 	RequesterID                           uuid.UUID    `db:"requester_id" json:"requester_id"`
 	Offset                                int32        `db:"offset_" json:"offset_"`
 	Limit                                 int32        `db:"limit_" json:"limit_"`
@@ -38969,6 +47202,9 @@ type GetWorkspacesRow struct {
 	NextStartAt                 sql.NullTime         `db:"next_start_at" json:"next_start_at"`
 	GroupACL                    json.RawMessage      `db:"group_acl" json:"group_acl"`
 	UserACL                     json.RawMessage      `db:"user_acl" json:"user_acl"`
+	ExpiresAt                   sql.NullTime         `db:"expires_at" json:"expires_at"`
+	ArchivedAt                  sql.NullTime         `db:"archived_at" json:"archived_at"`
+	ReadOnlyAt                  sql.NullTime         `db:"read_only_at" json:"read_only_at"`
 	OwnerAvatarUrl              string               `db:"owner_avatar_url" json:"owner_avatar_url"`
 	OwnerUsername               string               `db:"owner_username" json:"owner_username"`
 	OwnerName                   string               `db:"owner_name" json:"owner_name"`
@@ -39016,12 +47252,17 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 		arg.Dormant,
 		arg.LastUsedBefore,
 		arg.LastUsedAfter,
+		arg.CreatedBefore,
+		arg.CreatedAfter,
+		arg.TemplateVersionID,
+		arg.TemplateVersionName,
 		arg.UsingActive,
 		arg.HasAITask,
 		arg.HasExternalAgent,
 		arg.Shared,
 		arg.SharedWithUserID,
 		arg.SharedWithGroupID,
+		arg.Sort,
 		arg.RequesterID,
 		arg.Offset,
 		arg.Limit,
@@ -39053,6 +47294,9 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.NextStartAt,
 			&i.GroupACL,
 			&i.UserACL,
+			&i.ExpiresAt,
+			&i.ArchivedAt,
+			&i.ReadOnlyAt,
 			&i.OwnerAvatarUrl,
 			&i.OwnerUsername,
 			&i.OwnerName,
@@ -39170,7 +47414,7 @@ func (q *sqlQuerier) GetWorkspacesAndAgentsByOwnerID(ctx context.Context, ownerI
 }
 
 const getWorkspacesByTemplateID = `-- name: GetWorkspacesByTemplateID :many
-SELECT id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl FROM workspaces WHERE template_id = $1 AND deleted = false
+SELECT id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at FROM workspaces WHERE template_id = $1 AND deleted = false
 `
 
 func (q *sqlQuerier) GetWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceTable, error) {
@@ -39201,6 +47445,9 @@ func (q *sqlQuerier) GetWorkspacesByTemplateID(ctx context.Context, templateID u
 			&i.NextStartAt,
 			&i.GroupACL,
 			&i.UserACL,
+			&i.ExpiresAt,
+			&i.ArchivedAt,
+			&i.ReadOnlyAt,
 		); err != nil {
 			return nil, err
 		}
@@ -39286,10 +47533,18 @@ WHERE
 		--   * The workspace is not dormant.
 		--   * The template has set a time 'til dormant.
 		--   * The workspace has been unused for longer than the time 'til dormancy.
+		--   * The workspace does not have an unexpired dormancy exemption.
 		(
 			workspaces.dormant_at IS NULL AND
 			templates.time_til_dormant > 0 AND
-			($1 :: timestamptz) - workspaces.last_used_at > (INTERVAL '1 millisecond' * (templates.time_til_dormant / 1000000))
+			($1 :: timestamptz) - workspaces.last_used_at > (INTERVAL '1 millisecond' * (templates.time_til_dormant / 1000000)) AND
+			NOT EXISTS (
+				SELECT 1
+				FROM workspace_dormancy_exemptions
+				WHERE
+					workspace_dormancy_exemptions.workspace_id = workspaces.id AND
+					workspace_dormancy_exemptions.expires_at > $1 :: timestamptz
+			)
 		) OR
 
 		-- A workspace may be eligible for deletion if the following are true:
@@ -39340,6 +47595,68 @@ WHERE
 			($1 :: timestamptz) - provisioner_jobs.completed_at > (INTERVAL '1 millisecond' * (templates.failure_ttl / 1000000))
 		) OR
 
+		-- A trial workspace may be eligible to be stopped and then deleted if the
+		-- following are true:
+		--   * The workspace has an expiry and it has passed.
+		--   * The provisioner job has finished.
+		--   * If there was a prior attempt to delete the workspace that failed:
+		--      * This attempt was at least 24 hours ago.
+		(
+			workspaces.expires_at IS NOT NULL AND
+			workspaces.expires_at <= $1 :: timestamptz AND
+			(
+				provisioner_jobs.canceled_at IS NOT NULL OR
+				provisioner_jobs.completed_at IS NOT NULL
+			) AND
+			CASE
+				WHEN (
+					workspace_builds.transition = 'delete'::workspace_transition AND
+					provisioner_jobs.job_status = 'failed'::provisioner_job_status
+				) THEN (
+					($1 :: timestamptz) - (CASE
+						WHEN provisioner_jobs.canceled_at IS NOT NULL THEN provisioner_jobs.canceled_at
+						ELSE provisioner_jobs.completed_at
+					END) > INTERVAL '24 hours'
+				)
+				ELSE true
+			END
+		) OR
+
+		-- A workspace may be eligible for a max lifetime warning or expiry if
+		-- the following are true:
+		--   * The template has a max lifetime set.
+		--   * The workspace is within 7 days (the earliest warning) of reaching it.
+		--   * The provisioner job has finished.
+		--   * The template deletes expired workspaces, or the workspace is running.
+		(
+			templates.max_lifetime > 0 AND
+			workspaces.created_at + (INTERVAL '1 millisecond' * (templates.max_lifetime / 1000000)) - INTERVAL '7 days' <= $1 :: timestamptz AND
+			(
+				provisioner_jobs.canceled_at IS NOT NULL OR
+				provisioner_jobs.completed_at IS NOT NULL
+			) AND
+			(
+				templates.max_lifetime_action = 'delete'::max_lifetime_action OR
+				workspace_builds.transition = 'start'::workspace_transition
+			)
+		) OR
+
+		-- A workspace may be eligible for idle reclaim if the following are true:
+		--   * The template has an idle reclaim policy.
+		--   * The latest build is a successfully provisioned start build.
+		--   * The workspace is not dormant.
+		--   * It has not been used for longer than the idle reclaim TTL since
+		--     the build finished.
+		-- Whether its resources match the resource selector of the policy is
+		-- checked by the lifecycle executor.
+		(
+			templates.idle_reclaim_ttl > 0 AND
+			provisioner_jobs.job_status = 'succeeded'::provisioner_job_status AND
+			workspace_builds.transition = 'start'::workspace_transition AND
+			workspaces.dormant_at IS NULL AND
+			GREATEST(workspaces.last_used_at, provisioner_jobs.completed_at) + (INTERVAL '1 millisecond' * (templates.idle_reclaim_ttl / 1000000)) <= $1 :: timestamptz
+		) OR
+
 		-- A workspace may be eligible for an autostop reminder if the following are true:
 		--   * The latest build is a successfully provisioned start build.
 		--   * The workspace is not dormant and its owner is not suspended.
@@ -39392,6 +47709,12 @@ WHERE
 		)
 	)
   	AND workspaces.deleted = 'false'
+	-- Archived workspaces have no resources to act on and stay archived until
+	-- they are explicitly unarchived.
+	AND workspaces.archived_at IS NULL
+	-- Read-only workspaces are under investigation, so their resources must
+	-- be left untouched until the mode is lifted.
+	AND workspaces.read_only_at IS NULL
   	-- Prebuilt workspaces (identified by having the prebuilds system user as owner_id)
 	-- should not be considered by the lifecycle executor, as they are handled by the
 	-- prebuilds reconciliation loop.
@@ -39503,10 +47826,11 @@ INSERT INTO
 		ttl,
 		last_used_at,
 		automatic_updates,
-		next_start_at
+		next_start_at,
+		expires_at
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at
 `
 
 type InsertWorkspaceParams struct {
@@ -39522,6 +47846,7 @@ type InsertWorkspaceParams struct {
 	LastUsedAt        time.Time        `db:"last_used_at" json:"last_used_at"`
 	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 	NextStartAt       sql.NullTime     `db:"next_start_at" json:"next_start_at"`
+	ExpiresAt         sql.NullTime     `db:"expires_at" json:"expires_at"`
 }
 
 func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (WorkspaceTable, error) {
@@ -39538,6 +47863,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		arg.LastUsedAt,
 		arg.AutomaticUpdates,
 		arg.NextStartAt,
+		arg.ExpiresAt,
 	)
 	var i WorkspaceTable
 	err := row.Scan(
@@ -39559,6 +47885,9 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.NextStartAt,
 		&i.GroupACL,
 		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
 	)
 	return i, err
 }
@@ -39598,7 +47927,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at
 `
 
 type UpdateWorkspaceParams struct {
@@ -39628,6 +47957,9 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.NextStartAt,
 		&i.GroupACL,
 		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
 	)
 	return i, err
 }
@@ -39653,6 +47985,25 @@ func (q *sqlQuerier) UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorks
 	return err
 }
 
+const updateWorkspaceArchivedAt = `-- name: UpdateWorkspaceArchivedAt :exec
+UPDATE
+	workspaces
+SET
+	archived_at = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceArchivedAtParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	ArchivedAt sql.NullTime `db:"archived_at" json:"archived_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceArchivedAt(ctx context.Context, arg UpdateWorkspaceArchivedAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceArchivedAt, arg.ID, arg.ArchivedAt)
+	return err
+}
+
 const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
 UPDATE
 	workspaces
@@ -39745,7 +48096,7 @@ WHERE
 	-- dormant_at and deleting_at
 	AND owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
 RETURNING
-    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl
+    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.group_acl, workspaces.user_acl, workspaces.expires_at, workspaces.archived_at, workspaces.read_only_at
 `
 
 type UpdateWorkspaceDormantDeletingAtParams struct {
@@ -39775,6 +48126,9 @@ func (q *sqlQuerier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg U
 		&i.NextStartAt,
 		&i.GroupACL,
 		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
 	)
 	return i, err
 }
@@ -39821,6 +48175,106 @@ func (q *sqlQuerier) UpdateWorkspaceNextStartAt(ctx context.Context, arg UpdateW
 	return err
 }
 
+const updateWorkspaceOwnerByID = `-- name: UpdateWorkspaceOwnerByID :one
+UPDATE
+	workspaces
+SET
+	owner_id = $2,
+	name = $3,
+	updated_at = $4
+WHERE
+	id = $1
+	AND deleted = false
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at
+`
+
+type UpdateWorkspaceOwnerByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	OwnerID   uuid.UUID `db:"owner_id" json:"owner_id"`
+	Name      string    `db:"name" json:"name"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Transfers a workspace to another owner. The name is updated as well, since
+// it must be unique among the workspaces of the new owner.
+func (q *sqlQuerier) UpdateWorkspaceOwnerByID(ctx context.Context, arg UpdateWorkspaceOwnerByIDParams) (WorkspaceTable, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceOwnerByID,
+		arg.ID,
+		arg.OwnerID,
+		arg.Name,
+		arg.UpdatedAt,
+	)
+	var i WorkspaceTable
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+		&i.Favorite,
+		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
+	)
+	return i, err
+}
+
+const updateWorkspaceReadOnlyAt = `-- name: UpdateWorkspaceReadOnlyAt :one
+UPDATE
+	workspaces
+SET
+	read_only_at = $2
+WHERE
+	id = $1
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at
+`
+
+type UpdateWorkspaceReadOnlyAtParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	ReadOnlyAt sql.NullTime `db:"read_only_at" json:"read_only_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceReadOnlyAt(ctx context.Context, arg UpdateWorkspaceReadOnlyAtParams) (WorkspaceTable, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceReadOnlyAt, arg.ID, arg.ReadOnlyAt)
+	var i WorkspaceTable
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+		&i.Favorite,
+		&i.NextStartAt,
+		&i.GroupACL,
+		&i.UserACL,
+		&i.ExpiresAt,
+		&i.ArchivedAt,
+		&i.ReadOnlyAt,
+	)
+	return i, err
+}
+
 const updateWorkspaceTTL = `-- name: UpdateWorkspaceTTL :exec
 UPDATE
 	workspaces
@@ -39861,7 +48315,7 @@ WHERE
 	-- should not have their dormant or deleting at set, as these are handled by the
     -- prebuilds reconciliation loop.
 	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, group_acl, user_acl, expires_at, archived_at, read_only_at
 `
 
 type UpdateWorkspacesDormantDeletingAtByTemplateIDParams struct {
@@ -39898,6 +48352,9 @@ func (q *sqlQuerier) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.C
 			&i.NextStartAt,
 			&i.GroupACL,
 			&i.UserACL,
+			&i.ExpiresAt,
+			&i.ArchivedAt,
+			&i.ReadOnlyAt,
 		); err != nil {
 			return nil, err
 		}
@@ -5,6 +5,11 @@
 --
 -- Max deadline is respected, and the deadline will never be bumped past it.
 -- The deadline will never decrease.
+--
+-- If the template restricts the connection types that bump the deadline, the
+-- workspace is only bumped if one of the given connection types is allowed.
+-- The total bump per UTC day is limited by the template's
+-- activity_bump_max_per_day, and tracked in workspace_activity_bumps.
 -- name: ActivityBumpWorkspace :exec
 WITH latest AS (
 	SELECT
@@ -44,7 +49,18 @@ WITH latest AS (
 				ELSE
 					(templates.activity_bump / 1000 / 1000 / 1000 || ' seconds')::interval
 			END
-		) AS ttl_interval
+		) AS ttl_interval,
+		templates.activity_bump_max_per_day AS max_bump_per_day,
+		(
+			cardinality(templates.activity_bump_connection_types) = 0
+			OR templates.activity_bump_connection_types && @connection_types :: text[]
+		) AS connection_type_allowed,
+		COALESCE((
+			SELECT workspace_activity_bumps.bumped
+			FROM workspace_activity_bumps
+			WHERE workspace_activity_bumps.workspace_id = workspaces.id
+				AND workspace_activity_bumps.date = (NOW() AT TIME ZONE 'UTC')::date
+		), 0) AS bumped_today
 	FROM workspace_builds
 	JOIN provisioner_jobs
 		ON provisioner_jobs.id = workspace_builds.job_id
@@ -59,27 +75,58 @@ WITH latest AS (
 	  	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
 	ORDER BY workspace_builds.build_number DESC
 	LIMIT 1
+),
+bumped AS (
+	UPDATE
+		workspace_builds wb
+	SET
+		updated_at = NOW(),
+		deadline = LEAST(
+			CASE
+				WHEN l.build_max_deadline = '0001-01-01 00:00:00+00'
+				-- Never reduce the deadline from activity.
+				THEN GREATEST(wb.deadline, NOW() + l.ttl_interval)
+				ELSE LEAST(GREATEST(wb.deadline, NOW() + l.ttl_interval), l.build_max_deadline)
+			END,
+			-- Never bump past what remains of the daily limit.
+			CASE
+				WHEN l.max_bump_per_day > 0
+				THEN GREATEST(wb.deadline, NOW()) + ((l.max_bump_per_day - l.bumped_today) / 1000 || ' microseconds')::interval
+				ELSE 'infinity'::timestamptz
+			END
+		)
+	FROM latest l
+	WHERE wb.id = l.build_id
+	AND l.job_completed_at IS NOT NULL
+	-- We only bump if the template has an activity bump duration set.
+	AND l.activity_bump > 0
+	AND l.build_transition = 'start'
+	-- We only bump if the raw interval is positive and non-zero.
+	AND l.ttl_interval > '0 seconds'::interval
+	-- We only bump if workspace shutdown is manual.
+	AND l.build_deadline != '0001-01-01 00:00:00+00'
+	-- We only bump when 5% of the deadline has elapsed.
+	AND l.build_deadline - (l.ttl_interval * 0.95) < NOW()
+	-- We only bump for the connection types allowed by the template.
+	AND l.connection_type_allowed
+	-- We only bump if the daily limit has not been reached.
+	AND (l.max_bump_per_day = 0 OR l.bumped_today < l.max_bump_per_day)
+	RETURNING wb.workspace_id, l.max_bump_per_day, wb.deadline - GREATEST(l.build_deadline, NOW()) AS bump
 )
-UPDATE
-	workspace_builds wb
-SET
-	updated_at = NOW(),
-	deadline = CASE
-		WHEN l.build_max_deadline = '0001-01-01 00:00:00+00'
-		-- Never reduce the deadline from activity.
-		THEN GREATEST(wb.deadline, NOW() + l.ttl_interval)
-		ELSE LEAST(GREATEST(wb.deadline, NOW() + l.ttl_interval), l.build_max_deadline)
-	END
-FROM latest l
-WHERE wb.id = l.build_id
-AND l.job_completed_at IS NOT NULL
--- We only bump if the template has an activity bump duration set.
-AND l.activity_bump > 0
-AND l.build_transition = 'start'
--- We only bump if the raw interval is positive and non-zero.
-AND l.ttl_interval > '0 seconds'::interval
--- We only bump if workspace shutdown is manual.
-AND l.build_deadline != '0001-01-01 00:00:00+00'
--- We only bump when 5% of the deadline has elapsed.
-AND l.build_deadline - (l.ttl_interval * 0.95) < NOW()
+INSERT INTO workspace_activity_bumps (workspace_id, date, bumped)
+SELECT
+	bumped.workspace_id,
+	(NOW() AT TIME ZONE 'UTC')::date,
+	(EXTRACT(EPOCH FROM bumped.bump) * 1000000000)::bigint
+FROM bumped
+-- Bumps are only tracked for templates with a daily limit.
+WHERE bumped.max_bump_per_day > 0
+AND bumped.bump > '0 seconds'::interval
+ON CONFLICT (workspace_id) DO UPDATE SET
+	bumped = CASE
+		WHEN workspace_activity_bumps.date = EXCLUDED.date
+		THEN workspace_activity_bumps.bumped + EXCLUDED.bumped
+		ELSE EXCLUDED.bumped
+	END,
+	date = EXCLUDED.date
 ;
@@ -65,7 +65,8 @@ INSERT INTO
 		login_type,
 		scopes,
 		allow_list,
-		token_name
+		token_name,
+		template_ids
 	)
 VALUES
 	(@id,
@@ -74,7 +75,9 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE @lifetime_seconds::bigint
 	 END
-	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scopes, @allow_list, @token_name) RETURNING *;
+	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scopes, @allow_list, @token_name,
+	 -- A nil template list means the key is not template restricted.
+	 COALESCE(@template_ids::uuid[], '{}'::uuid[])) RETURNING *;
 
 -- name: UpdateAPIKeyByID :exec
 UPDATE
@@ -103,6 +103,11 @@ WHERE
 		WHEN @request_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.request_id = @request_id
 		ELSE true
 	END
+	-- Filter correlation_id
+	AND CASE
+		WHEN @correlation_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.correlation_id = @correlation_id
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedAuditLogsOffset
 	-- @authorize_filter
 ORDER BY "time" DESC
@@ -127,7 +132,8 @@ INSERT INTO audit_logs (
 		status_code,
 		additional_fields,
 		request_id,
-		resource_icon
+		resource_icon,
+		correlation_id
 	)
 VALUES (
 		$1,
@@ -144,7 +150,8 @@ VALUES (
 		$12,
 		$13,
 		$14,
-		$15
+		$15,
+		$16
 	)
 RETURNING *;
 
@@ -235,6 +242,11 @@ SELECT COUNT(*) FROM (
 			WHEN @request_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.request_id = @request_id
 			ELSE true
 		END
+		-- Filter correlation_id
+		AND CASE
+			WHEN @correlation_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.correlation_id = @correlation_id
+			ELSE true
+		END
 		-- Authorize Filter clause will be injected below in CountAuthorizedAuditLogs
 		-- @authorize_filter
 	-- Avoid a slow scan on a large table with joins. The caller
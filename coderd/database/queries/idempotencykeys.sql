@@ -0,0 +1,42 @@
+-- name: GetIdempotencyKey :one
+SELECT
+	*
+FROM
+	idempotency_keys
+WHERE
+	user_id = @user_id
+	AND operation = @operation
+	AND key = @key
+	AND expires_at > @now :: timestamptz;
+
+-- name: InsertIdempotencyKey :one
+-- Inserts the key, replacing an expired key of the same user and operation.
+-- Returns no rows if an unexpired key already exists.
+INSERT INTO
+	idempotency_keys (
+		user_id,
+		operation,
+		key,
+		request_hash,
+		workspace_id,
+		workspace_build_id,
+		created_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (user_id, operation, key) DO UPDATE SET
+	request_hash = EXCLUDED.request_hash,
+	workspace_id = EXCLUDED.workspace_id,
+	workspace_build_id = EXCLUDED.workspace_build_id,
+	created_at = EXCLUDED.created_at,
+	expires_at = EXCLUDED.expires_at
+WHERE
+	idempotency_keys.expires_at <= EXCLUDED.created_at
+RETURNING *;
+
+-- name: DeleteExpiredIdempotencyKeys :exec
+DELETE FROM
+	idempotency_keys
+WHERE
+	expires_at <= @before :: timestamptz;
@@ -466,6 +466,71 @@ ON
 GROUP BY
 	ts.from_, ts.to_;
 
+-- name: GetTemplateActiveDeveloperDays :many
+-- GetTemplateActiveDeveloperDays returns the users that were active in
+-- workspaces of each template, for each day between start and end time. A user
+-- counts as active on a day when their session and app usage of the template
+-- adds up to at least min_usage_mins minutes. Days start at the time of day of
+-- start_time, so its time zone decides the day boundaries.
+WITH
+	ts AS (
+		SELECT
+			d::timestamptz AS from_,
+			LEAST(
+				(d::timestamptz + '1 day'::interval)::timestamptz,
+				@end_time::timestamptz
+			)::timestamptz AS to_
+		FROM
+			generate_series(
+				@start_time::timestamptz,
+				-- Subtract 1 μs to avoid creating an extra series.
+				(@end_time::timestamptz) - '1 microsecond'::interval,
+				'1 day'::interval
+			) AS d
+	),
+	active AS (
+		SELECT
+			ts.from_ AS date,
+			tus.template_id,
+			tus.user_id
+		FROM
+			ts
+		JOIN
+			template_usage_stats AS tus
+		ON
+			tus.start_time >= ts.from_
+			AND tus.start_time < ts.to_ -- End time exclusion criteria optimization for index.
+			AND tus.end_time <= ts.to_
+		WHERE
+			CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN tus.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+		GROUP BY
+			ts.from_, tus.template_id, tus.user_id
+		HAVING
+			SUM(tus.usage_mins) >= @min_usage_mins::int
+	)
+
+SELECT
+	active.date::timestamptz AS date,
+	active.template_id,
+	t.name AS template_name,
+	t.organization_id,
+	o.name AS organization_name,
+	active.user_id
+FROM
+	active
+JOIN
+	templates AS t
+ON
+	t.id = active.template_id
+JOIN
+	organizations AS o
+ON
+	o.id = t.organization_id
+WHERE
+	CASE WHEN @organization_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN t.organization_id = @organization_id ELSE TRUE END
+ORDER BY
+	active.date, o.name, t.name, active.user_id;
+
 -- name: GetTemplateUsageStats :many
 SELECT
 	*
@@ -785,6 +850,7 @@ WITH latest_workspace_builds AS (
 		tvp.options
 	FROM latest_workspace_builds wb
 	JOIN template_version_parameters tvp ON (tvp.template_version_id = wb.template_version_id)
+	WHERE NOT tvp.sensitive
 	GROUP BY tvp.name, tvp.type, tvp.display_name, tvp.description, tvp.options
 )
 
@@ -898,3 +964,64 @@ FROM ranked_status_change_per_user_per_date rscpupd
 CROSS JOIN statuses
 GROUP BY rscpupd.date, statuses.new_status
 ORDER BY rscpupd.date;
+
+-- name: GetAutostopInsights :many
+-- GetAutostopInsights returns, per template, how long workspaces ran and how
+-- long they were stopped by scheduling policies within [start_time, end_time).
+-- A workspace is running from a successful start build until its next
+-- successful build, and stopped by a policy from a successful stop build with
+-- the autostop, dormancy or failedstop reason until its next successful build.
+WITH builds AS (
+	SELECT
+		w.template_id,
+		wb.transition,
+		wb.reason,
+		pj.completed_at AS started_at,
+		LEAD(pj.completed_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.build_number) AS ended_at
+	FROM
+		workspace_builds wb
+	JOIN
+		provisioner_jobs pj ON pj.id = wb.job_id
+	JOIN
+		workspaces w ON w.id = wb.workspace_id
+	WHERE
+		pj.job_status = 'succeeded'::provisioner_job_status
+		AND pj.completed_at < @end_time::timestamptz
+		AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN w.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+),
+periods AS (
+	SELECT
+		template_id,
+		transition,
+		reason,
+		EXTRACT(EPOCH FROM
+			LEAST(COALESCE(ended_at, @end_time::timestamptz), @end_time::timestamptz)
+			- GREATEST(started_at, @start_time::timestamptz)
+		) AS seconds
+	FROM
+		builds
+	WHERE
+		COALESCE(ended_at, @end_time::timestamptz) > @start_time::timestamptz
+)
+SELECT
+	t.organization_id,
+	o.name AS organization_name,
+	t.id AS template_id,
+	t.name AS template_name,
+	t.display_name AS template_display_name,
+	COALESCE(SUM(p.seconds) FILTER (WHERE p.transition = 'start'), 0)::bigint AS running_seconds,
+	COALESCE(SUM(p.seconds) FILTER (WHERE p.transition = 'stop' AND p.reason = 'autostop'), 0)::bigint AS autostop_seconds,
+	COALESCE(SUM(p.seconds) FILTER (WHERE p.transition = 'stop' AND p.reason = 'dormancy'), 0)::bigint AS dormancy_seconds,
+	COALESCE(SUM(p.seconds) FILTER (WHERE p.transition = 'stop' AND p.reason = 'failedstop'), 0)::bigint AS failure_ttl_seconds
+FROM
+	periods p
+JOIN
+	templates t ON t.id = p.template_id
+JOIN
+	organizations o ON o.id = t.organization_id
+WHERE
+	p.seconds > 0
+GROUP BY
+	t.organization_id, o.name, t.id, t.name, t.display_name
+ORDER BY
+	o.name, t.name;
@@ -0,0 +1,112 @@
+-- name: GetTemplateNetworkPolicy :one
+SELECT
+	*
+FROM
+	template_network_policies
+WHERE
+	template_id = $1;
+
+-- name: UpsertTemplateNetworkPolicy :one
+INSERT INTO
+	template_network_policies (
+		template_id,
+		allowed_domains,
+		allowed_cidrs,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (template_id) DO UPDATE SET
+	allowed_domains = EXCLUDED.allowed_domains,
+	allowed_cidrs = EXCLUDED.allowed_cidrs,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteTemplateNetworkPolicy :exec
+DELETE FROM
+	template_network_policies
+WHERE
+	template_id = $1;
+
+-- name: GetWorkspaceNetworkPolicy :one
+SELECT
+	*
+FROM
+	workspace_network_policies
+WHERE
+	workspace_id = $1;
+
+-- name: UpsertWorkspaceNetworkPolicy :one
+INSERT INTO
+	workspace_network_policies (
+		workspace_id,
+		allowed_domains,
+		allowed_cidrs,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	allowed_domains = EXCLUDED.allowed_domains,
+	allowed_cidrs = EXCLUDED.allowed_cidrs,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteWorkspaceNetworkPolicy :exec
+DELETE FROM
+	workspace_network_policies
+WHERE
+	workspace_id = $1;
+
+-- name: InsertWorkspaceAgentNetworkPolicyViolations :exec
+INSERT INTO
+	workspace_agent_network_policy_violations (
+		id,
+		workspace_agent_id,
+		created_at,
+		destination,
+		port,
+		count
+	)
+SELECT
+	unnest(@id :: uuid [ ]),
+	@workspace_agent_id :: uuid,
+	@created_at :: timestamptz,
+	unnest(@destination :: text [ ]),
+	unnest(@port :: integer [ ]),
+	unnest(@count :: integer [ ]);
+
+-- name: GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs :many
+-- Returns the violations reported by the given agents since the given time,
+-- newest first.
+SELECT
+	*
+FROM
+	workspace_agent_network_policy_violations
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+	AND created_at >= @created_after :: timestamptz
+ORDER BY
+	created_at DESC, id DESC
+LIMIT
+	1000;
+
+-- name: GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs :many
+-- Returns the number of blocked connections reported by each of the given
+-- agents since the given time. Agents without violations are omitted.
+SELECT
+	workspace_agent_id,
+	SUM(count) :: bigint AS violations
+FROM
+	workspace_agent_network_policy_violations
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+	AND created_at >= @created_after :: timestamptz
+GROUP BY
+	workspace_agent_id;
+
+-- name: DeleteOldWorkspaceAgentNetworkPolicyViolations :exec
+DELETE FROM
+	workspace_agent_network_policy_violations
+WHERE
+	created_at < @before_time :: timestamptz;
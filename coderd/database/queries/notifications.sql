@@ -88,12 +88,19 @@ SELECT
     nt.id                                                                 AS template_id,
     nt.title_template,
     nt.body_template,
-    -- preferences
-    (CASE WHEN np.disabled IS NULL THEN false ELSE np.disabled END)::bool AS disabled
+    -- preferences, a workspace or template scoped preference takes precedence
+    COALESCE(nsp.disabled, np.disabled, false)::bool                      AS disabled
 FROM acquired nm
          JOIN notification_templates nt ON nm.notification_template_id = nt.id
          LEFT JOIN notification_preferences AS np
-                   ON (np.user_id = nm.user_id AND np.notification_template_id = nm.notification_template_id);
+                   ON (np.user_id = nm.user_id AND np.notification_template_id = nm.notification_template_id)
+         LEFT JOIN LATERAL (SELECT nsp.disabled
+                            FROM notification_scoped_preferences AS nsp
+                            WHERE nsp.user_id = nm.user_id
+                              AND nsp.notification_template_id = nm.notification_template_id
+                              AND nsp.target_id = ANY (nm.targets)
+                            ORDER BY (nsp.scope = 'workspace') DESC
+                            LIMIT 1) AS nsp ON TRUE;
 
 -- name: BulkMarkNotificationMessagesFailed :execrows
 UPDATE notification_messages
@@ -143,6 +150,16 @@ FROM notification_messages
 WHERE status = @status
 LIMIT sqlc.arg('limit')::int;
 
+-- name: GetNotificationMessagesByTemplateID :many
+-- Returns the most recent messages of a notification template, optionally
+-- filtered by delivery method, to report on their delivery status.
+SELECT *
+FROM notification_messages
+WHERE notification_template_id = @notification_template_id
+	AND (sqlc.narg('method')::notification_method IS NULL OR method = sqlc.narg('method')::notification_method)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit')::int;
+
 -- name: GetUserNotificationPreferences :many
 SELECT *
 FROM notification_preferences
@@ -158,6 +175,33 @@ ON CONFLICT (user_id, notification_template_id) DO UPDATE
     SET disabled   = EXCLUDED.disabled,
         updated_at = CURRENT_TIMESTAMP;
 
+-- name: GetUserNotificationScopedPreferences :many
+SELECT *
+FROM notification_scoped_preferences
+WHERE user_id = @user_id::uuid
+ORDER BY notification_template_id, target_id;
+
+-- name: UpsertUserNotificationScopedPreferences :execrows
+INSERT
+INTO notification_scoped_preferences (user_id, notification_template_id, scope, target_id, disabled)
+SELECT @user_id::uuid, new_values.notification_template_id, new_values.scope, new_values.target_id, new_values.disabled
+FROM (SELECT UNNEST(@notification_template_ids::uuid[])           AS notification_template_id,
+             UNNEST(@scopes::notification_preference_scope[]) AS scope,
+             UNNEST(@target_ids::uuid[])                          AS target_id,
+             UNNEST(@disableds::bool[])                           AS disabled) AS new_values
+ON CONFLICT (user_id, notification_template_id, target_id) DO UPDATE
+    SET scope      = EXCLUDED.scope,
+        disabled   = EXCLUDED.disabled,
+        updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteUserNotificationScopedPreferences :execrows
+DELETE FROM notification_scoped_preferences
+WHERE user_id = @user_id::uuid
+  AND (notification_template_id, target_id) IN (
+    SELECT UNNEST(@notification_template_ids::uuid[]),
+           UNNEST(@target_ids::uuid[])
+  );
+
 -- name: UpdateNotificationTemplateMethodByID :one
 UPDATE notification_templates
 SET method = sqlc.narg('method')::notification_method
@@ -175,6 +219,25 @@ FROM notification_templates
 WHERE kind = @kind::notification_template_kind
 ORDER BY name ASC;
 
+-- name: GetNotificationTemplateWebhookByTemplateID :one
+SELECT *
+FROM notification_template_webhooks
+WHERE notification_template_id = @notification_template_id::uuid;
+
+-- name: UpsertNotificationTemplateWebhook :one
+INSERT INTO notification_template_webhooks (notification_template_id, endpoint, payload_template, signing_secret, updated_at)
+VALUES (@notification_template_id, @endpoint, @payload_template, @signing_secret, @updated_at)
+ON CONFLICT (notification_template_id)
+	DO UPDATE SET endpoint = EXCLUDED.endpoint,
+		payload_template = EXCLUDED.payload_template,
+		signing_secret = EXCLUDED.signing_secret,
+		updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteNotificationTemplateWebhook :exec
+DELETE FROM notification_template_webhooks
+WHERE notification_template_id = @notification_template_id::uuid;
+
 -- name: GetNotificationReportGeneratorLogByTemplate :one
 -- Fetch the notification report generator log indicating recent activity.
 SELECT
@@ -144,6 +144,17 @@ WHERE
     id = @id AND
     is_default = false;
 
+-- name: UpdateOrganizationProvisionerDaemonSettings :one
+UPDATE
+    organizations
+SET
+    provisioner_tag_namespace = @provisioner_tag_namespace,
+    provisioner_daemon_quota = @provisioner_daemon_quota,
+    updated_at = @updated_at
+WHERE
+    id = @id
+RETURNING *;
+
 -- name: UpdateOrganizationWorkspaceSharingSettings :one
 UPDATE
     organizations
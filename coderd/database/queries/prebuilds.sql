@@ -15,7 +15,18 @@ SET owner_id   = @new_user_id::uuid,
 	-- These fields should not be set on prebuilds, but we defensively reset them here to prevent
 	-- accidental dormancy or deletion by the lifecycle executor.
 	dormant_at = NULL,
-	deleting_at = NULL
+	deleting_at = NULL,
+	-- Trial workspaces expire relative to the claim, not to when the prebuild
+	-- was provisioned.
+	expires_at = (
+		SELECT
+			CASE
+				WHEN templates.trial_workspace_ttl > 0 THEN
+					@now::timestamptz + (INTERVAL '1 millisecond' * (templates.trial_workspace_ttl / 1000000))
+			END
+		FROM templates
+		WHERE templates.id = w.template_id
+	)
 WHERE w.id IN (
 	SELECT p.id
 	FROM workspace_prebuilds p
@@ -372,3 +383,115 @@ FROM orgs_with_prebuilds owp
 LEFT JOIN prebuild_groups pg ON pg.organization_id = owp.id
 LEFT JOIN prebuild_user_membership pum ON pum.organization_id = owp.id
 LEFT JOIN prebuild_group_membership pgm ON pgm.organization_id = owp.id;
+
+-- name: InsertPrebuildClaimAttempt :one
+INSERT INTO prebuild_claim_attempts (
+	id,
+	preset_id,
+	workspace_id,
+	claimed,
+	miss_reason,
+	created_at
+) VALUES (
+	@id,
+	@preset_id,
+	@workspace_id,
+	@claimed,
+	@miss_reason,
+	@created_at
+) RETURNING *;
+
+-- name: GetPresetPrebuildPoolState :one
+-- GetPresetPrebuildPoolState summarizes the prebuilt workspaces of a preset. It
+-- is used to explain why no prebuilt workspace of the preset could be claimed.
+SELECT
+	tvp.desired_instances,
+	tvp.prebuild_status,
+	COUNT(wlb.id) FILTER (
+		WHERE wlb.transition = 'start'::workspace_transition
+		AND wlb.job_status IN ('pending'::provisioner_job_status, 'running'::provisioner_job_status)
+	)::int AS in_progress,
+	COUNT(wlb.id) FILTER (
+		WHERE wlb.transition = 'start'::workspace_transition
+		AND wlb.job_status = 'succeeded'::provisioner_job_status
+	)::int AS running,
+	COUNT(wlb.id) FILTER (
+		WHERE wlb.transition = 'start'::workspace_transition
+		AND wlb.job_status = 'failed'::provisioner_job_status
+		AND wlb.created_at >= @failed_since::timestamptz
+	)::int AS recently_failed
+FROM template_version_presets tvp
+	LEFT JOIN workspace_latest_builds wlb ON wlb.template_version_preset_id = tvp.id
+		AND wlb.workspace_id IN (
+			SELECT w.id
+			FROM workspaces w
+			WHERE w.owner_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+				AND NOT w.deleted
+		)
+WHERE tvp.id = @preset_id::uuid
+GROUP BY tvp.id;
+
+-- name: GetPrebuildClaimInsights :many
+-- GetPrebuildClaimInsights reports, per preset, how many prebuilt workspace
+-- claims were attempted within the given interval, how many of them succeeded,
+-- why the others missed, and how long claimed workspaces took to become ready.
+WITH attempts AS (
+	SELECT id, preset_id, workspace_id, claimed, miss_reason, created_at
+	FROM prebuild_claim_attempts
+	WHERE created_at >= @start_time::timestamptz
+		AND created_at < @end_time::timestamptz
+),
+claim_builds AS (
+	-- The build that hands a claimed prebuilt workspace over to its new owner
+	-- is the first build created after the claim.
+	SELECT DISTINCT ON (attempts.id)
+		attempts.id AS attempt_id,
+		attempts.created_at AS claimed_at,
+		workspace_builds.job_id
+	FROM attempts
+		INNER JOIN workspace_builds ON workspace_builds.workspace_id = attempts.workspace_id
+			AND workspace_builds.created_at >= attempts.created_at
+	WHERE attempts.claimed
+	ORDER BY attempts.id, workspace_builds.build_number
+),
+time_to_ready AS (
+	-- A claimed workspace is ready once all of its agents are ready.
+	SELECT
+		claim_builds.attempt_id,
+		EXTRACT(EPOCH FROM MAX(workspace_agents.ready_at) - claim_builds.claimed_at)::float AS seconds
+	FROM claim_builds
+		INNER JOIN workspace_resources ON workspace_resources.job_id = claim_builds.job_id
+		INNER JOIN workspace_agents ON workspace_agents.resource_id = workspace_resources.id
+	WHERE NOT workspace_agents.deleted
+		AND workspace_agents.parent_id IS NULL
+	GROUP BY claim_builds.attempt_id, claim_builds.claimed_at
+	HAVING BOOL_AND(workspace_agents.ready_at IS NOT NULL)
+)
+SELECT
+	o.name AS organization_name,
+	t.id AS template_id,
+	t.name AS template_name,
+	tv.name AS template_version_name,
+	tvp.id AS preset_id,
+	tvp.name AS preset_name,
+	COUNT(*) AS claim_attempts,
+	COUNT(*) FILTER (WHERE attempts.claimed) AS claimed,
+	COUNT(*) FILTER (WHERE attempts.miss_reason = 'reconciliation_lag'::prebuild_claim_miss_reason) AS missed_reconciliation_lag,
+	COUNT(*) FILTER (WHERE attempts.miss_reason = 'capacity'::prebuild_claim_miss_reason) AS missed_capacity,
+	COUNT(*) FILTER (WHERE attempts.miss_reason = 'failures'::prebuild_claim_miss_reason) AS missed_failures,
+	COUNT(time_to_ready.seconds) AS ready_count,
+	COALESCE(SUM(time_to_ready.seconds), 0)::float AS time_to_ready_sum_seconds,
+	COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY time_to_ready.seconds), 0)::float AS time_to_ready_p50_seconds,
+	COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY time_to_ready.seconds), 0)::float AS time_to_ready_p95_seconds
+FROM attempts
+	INNER JOIN template_version_presets tvp ON tvp.id = attempts.preset_id
+	INNER JOIN template_versions tv ON tv.id = tvp.template_version_id
+	INNER JOIN templates t ON t.id = tv.template_id
+	INNER JOIN organizations o ON o.id = t.organization_id
+	LEFT JOIN time_to_ready ON time_to_ready.attempt_id = attempts.id
+WHERE CASE
+	WHEN array_length(@template_ids::uuid[], 1) > 0 THEN t.id = ANY(@template_ids::uuid[])
+	ELSE true
+END
+GROUP BY o.name, t.id, t.name, tv.name, tvp.id, tvp.name
+ORDER BY o.name, t.name, tv.name, tvp.name;
@@ -0,0 +1,76 @@
+-- name: GetProvisionerJobsWithExpiredLogs :many
+-- Returns completed provisioner jobs whose logs outlived the build log
+-- retention of their template, or the deployment-wide retention if the
+-- template does not set one. The logs of the latest build of each workspace
+-- are always kept. Jobs that were already archived are skipped.
+SELECT
+	provisioner_jobs.id
+FROM
+	provisioner_jobs
+LEFT JOIN
+	workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+LEFT JOIN
+	template_versions ON template_versions.id = workspace_builds.template_version_id
+	OR template_versions.job_id = provisioner_jobs.id
+LEFT JOIN
+	templates ON templates.id = template_versions.template_id
+WHERE
+	provisioner_jobs.completed_at IS NOT NULL
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			provisioner_job_log_archives
+		WHERE
+			provisioner_job_log_archives.job_id = provisioner_jobs.id
+	)
+	AND (
+		workspace_builds.id IS NULL
+		OR EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_builds AS later_builds
+			WHERE
+				later_builds.workspace_id = workspace_builds.workspace_id
+				AND later_builds.build_number > workspace_builds.build_number
+		)
+	)
+	AND provisioner_jobs.completed_at < @now::timestamptz - (
+		CASE
+			WHEN COALESCE(templates.build_log_retention, 0) > 0 THEN templates.build_log_retention
+			ELSE @default_retention::bigint
+		END / 1000 * INTERVAL '1 microsecond'
+	)
+	AND (COALESCE(templates.build_log_retention, 0) > 0 OR @default_retention::bigint > 0)
+ORDER BY
+	provisioner_jobs.completed_at ASC
+LIMIT
+	@limit_count;
+
+-- name: InsertProvisionerJobLogArchive :exec
+INSERT INTO
+	provisioner_job_log_archives (
+		job_id,
+		archived_at,
+		object_key
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (job_id) DO NOTHING;
+
+-- name: GetProvisionerJobLogArchiveByJobID :one
+SELECT
+	*
+FROM
+	provisioner_job_log_archives
+WHERE
+	job_id = $1;
+
+-- name: GetProvisionerJobLogArchivesByJobIDs :many
+SELECT
+	*
+FROM
+	provisioner_job_log_archives
+WHERE
+	job_id = ANY(@job_ids :: uuid [ ]);
@@ -9,6 +9,19 @@ WHERE
 		id > @created_after
 	) ORDER BY id ASC;
 
+-- name: GetProvisionerJobErrorLogsByJobIDs :many
+-- Returns the error-level logs of the given jobs. Used to summarize the root
+-- cause of failed builds without fetching their full logs.
+SELECT
+	*
+FROM
+	provisioner_job_logs
+WHERE
+	job_id = ANY(@job_ids :: uuid [ ])
+	AND level = 'error'
+ORDER BY
+	id ASC;
+
 -- name: InsertProvisionerJobLogs :many
 INSERT INTO
 	provisioner_job_logs
@@ -35,3 +48,9 @@ SET
 	logs_length = logs_length + $2
 WHERE
 	id = $1;
+
+-- name: DeleteProvisionerJobLogsByJobID :execrows
+DELETE FROM
+	provisioner_job_logs
+WHERE
+	job_id = $1;
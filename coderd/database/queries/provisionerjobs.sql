@@ -142,6 +142,110 @@ FROM
 ORDER BY
 	fj.created_at;
 
+-- name: GetProvisionerJobsByInitiatorWithQueuePosition :many
+-- Returns the in-flight provisioner jobs started by a user across all
+-- organizations. The columns match
+-- GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner.
+WITH pending_jobs AS (
+    SELECT
+        id, initiator_id, created_at
+    FROM
+        provisioner_jobs
+    WHERE
+        started_at IS NULL
+    AND
+        canceled_at IS NULL
+    AND
+        completed_at IS NULL
+    AND
+        error IS NULL
+),
+queue_position AS (
+    SELECT
+        id,
+        ROW_NUMBER() OVER (ORDER BY initiator_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid ASC, created_at ASC) AS queue_position
+    FROM
+        pending_jobs
+),
+queue_size AS (
+	SELECT COUNT(*) AS count FROM pending_jobs
+)
+SELECT
+	sqlc.embed(pj),
+    COALESCE(qp.queue_position, 0) AS queue_position,
+    COALESCE(qs.count, 0) AS queue_size,
+	-- Use subquery to utilize ORDER BY in array_agg since it cannot be
+	-- combined with FILTER.
+	(
+		SELECT
+			-- Order for stable output.
+			array_agg(pd.id ORDER BY pd.created_at ASC)::uuid[]
+		FROM
+			provisioner_daemons pd
+		WHERE
+			-- See AcquireProvisionerJob.
+			pj.started_at IS NULL
+			AND pj.organization_id = pd.organization_id
+			AND pj.provisioner = ANY(pd.provisioners)
+			AND provisioner_tagset_contains(pd.tags, pj.tags)
+	) AS available_workers,
+	-- Include template and workspace information.
+	COALESCE(tv.name, '') AS template_version_name,
+	t.id AS template_id,
+	COALESCE(t.name, '') AS template_name,
+	COALESCE(t.display_name, '') AS template_display_name,
+	COALESCE(t.icon, '') AS template_icon,
+	w.id AS workspace_id,
+	COALESCE(w.name, '') AS workspace_name,
+	-- Include the name of the provisioner_daemon associated to the job
+	COALESCE(pd.name, '') AS worker_name,
+	wb.transition as workspace_build_transition
+FROM
+	provisioner_jobs pj
+LEFT JOIN
+	queue_position qp ON qp.id = pj.id
+LEFT JOIN
+	queue_size qs ON TRUE
+LEFT JOIN
+	workspace_builds wb ON wb.id = CASE WHEN pj.input ? 'workspace_build_id' THEN (pj.input->>'workspace_build_id')::uuid END
+LEFT JOIN
+	workspaces w ON (
+		w.id = wb.workspace_id
+		AND w.organization_id = pj.organization_id
+	)
+LEFT JOIN
+	-- We should always have a template version, either explicitly or implicitly via workspace build.
+	template_versions tv ON (
+		tv.id = CASE WHEN pj.input ? 'template_version_id' THEN (pj.input->>'template_version_id')::uuid ELSE wb.template_version_id END
+		AND tv.organization_id = pj.organization_id
+	)
+LEFT JOIN
+	templates t ON (
+		t.id = tv.template_id
+		AND t.organization_id = pj.organization_id
+	)
+LEFT JOIN
+	-- Join to get the daemon name corresponding to the job's worker_id
+	provisioner_daemons pd ON pd.id = pj.worker_id
+WHERE
+	pj.initiator_id = @initiator_id::uuid
+	AND pj.job_status IN ('pending', 'running', 'canceling')
+GROUP BY
+	pj.id,
+	qp.queue_position,
+	qs.count,
+	tv.name,
+	t.id,
+	t.name,
+	t.display_name,
+	t.icon,
+	w.id,
+	w.name,
+	pd.name,
+	wb.transition
+ORDER BY
+	pj.created_at DESC;
+
 -- name: GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisioner :many
 WITH pending_jobs AS (
     SELECT
@@ -279,6 +383,14 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateProvisionerJobTimeoutByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	timeout = $2
+WHERE
+	id = $1;
+
 -- name: UpdateProvisionerJobWithCancelByID :exec
 UPDATE
 	provisioner_jobs
@@ -311,6 +423,19 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateProvisionerJobWithRequeueByID :exec
+-- Returns a started job to the queue so that another provisioner daemon can
+-- acquire it.
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2,
+	started_at = NULL,
+	worker_id = NULL
+WHERE
+	id = $1
+	AND completed_at IS NULL;
+
 -- name: GetProvisionerJobsToBeReaped :many
 SELECT
 	*
@@ -330,6 +455,20 @@ WHERE
 		AND started_at IS NOT NULL
 		AND completed_at IS NULL
 	)
+	OR
+	(
+		-- If the job has been started but the provisioner daemon running it
+		-- has not sent a heartbeat since @orphaned_since, reap it.
+		updated_at < @orphaned_since
+		AND started_at IS NOT NULL
+		AND completed_at IS NULL
+		AND worker_id IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM provisioner_daemons
+			WHERE provisioner_daemons.id = provisioner_jobs.worker_id
+			AND provisioner_daemons.last_seen_at >= @orphaned_since
+		)
+	)
 -- To avoid repeatedly attempting to reap the same jobs, we randomly order and limit to @max_jobs.
 ORDER BY random()
 LIMIT @max_jobs;
@@ -350,3 +489,14 @@ RETURNING *;
 SELECT * FROM provisioner_job_timings
 WHERE job_id = $1
 ORDER BY started_at ASC;
+
+-- name: CountRunningProvisionerJobsByWorkerID :one
+-- Counts the jobs a provisioner daemon acquired that haven't completed yet.
+SELECT
+	COUNT(*)
+FROM
+	provisioner_jobs
+WHERE
+	worker_id = @worker_id::uuid
+	AND started_at IS NOT NULL
+	AND completed_at IS NULL;
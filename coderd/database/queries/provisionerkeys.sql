@@ -25,7 +25,10 @@ SELECT
 FROM
     provisioner_keys
 WHERE
-    hashed_secret = $1;
+    hashed_secret = @hashed_secret
+    -- The previous secret of a rotated key is matched as well, the caller
+    -- checks whether it expired.
+    OR previous_hashed_secret = @hashed_secret;
 
 -- name: GetProvisionerKeyByName :one
 SELECT
@@ -67,3 +70,24 @@ DELETE FROM
     provisioner_keys
 WHERE
     id = $1;
+
+-- name: RotateProvisionerKey :one
+-- Replaces the secret of a provisioner key. The current secret becomes the
+-- previous secret, which stays valid until previous_secret_expires_at.
+UPDATE
+    provisioner_keys
+SET
+    previous_hashed_secret = hashed_secret,
+    previous_secret_expires_at = @previous_secret_expires_at,
+    hashed_secret = @hashed_secret
+WHERE
+    id = @id
+RETURNING *;
+
+-- name: UpdateProvisionerKeyLastUsedAt :exec
+UPDATE
+    provisioner_keys
+SET
+    last_used_at = @last_used_at
+WHERE
+    id = @id;
@@ -0,0 +1,129 @@
+-- name: InsertSharedVolume :one
+INSERT INTO
+	shared_volumes (
+		id,
+		organization_id,
+		name,
+		display_name,
+		description,
+		template_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetSharedVolumeByID :one
+SELECT
+	*
+FROM
+	shared_volumes
+WHERE
+	id = $1;
+
+-- name: GetSharedVolumeByOrganizationIDAndName :one
+SELECT
+	*
+FROM
+	shared_volumes
+WHERE
+	organization_id = $1
+	AND name = $2;
+
+-- name: GetSharedVolumesByOrganizationID :many
+-- Returns the shared volumes of an organization along with the number of
+-- active attachments and the workspace holding the read-write lock, if any.
+SELECT
+	sqlc.embed(shared_volumes),
+	COALESCE(attachments.count, 0)::bigint AS attachment_count,
+	writer.workspace_id AS lock_workspace_id
+FROM
+	shared_volumes
+LEFT JOIN (
+	SELECT
+		shared_volume_id,
+		COUNT(*) AS count
+	FROM
+		shared_volume_attachments
+	WHERE
+		detached_at IS NULL
+	GROUP BY
+		shared_volume_id
+) attachments ON attachments.shared_volume_id = shared_volumes.id
+LEFT JOIN
+	shared_volume_attachments writer
+	ON writer.shared_volume_id = shared_volumes.id
+	AND writer.access_mode = 'read_write'::shared_volume_access_mode
+	AND writer.detached_at IS NULL
+WHERE
+	shared_volumes.organization_id = @organization_id
+ORDER BY
+	shared_volumes.name;
+
+-- name: InsertSharedVolumeAttachment :one
+INSERT INTO
+	shared_volume_attachments (
+		id,
+		shared_volume_id,
+		workspace_id,
+		workspace_build_id,
+		workspace_agent_id,
+		mount_path,
+		access_mode,
+		attached_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetSharedVolumeAttachmentByID :one
+SELECT
+	*
+FROM
+	shared_volume_attachments
+WHERE
+	id = $1;
+
+-- name: GetActiveSharedVolumeAttachmentsBySharedVolumeID :many
+SELECT
+	sqlc.embed(shared_volume_attachments),
+	workspaces.name AS workspace_name,
+	users.username AS workspace_owner_username,
+	workspace_agents.name AS workspace_agent_name
+FROM
+	shared_volume_attachments
+JOIN
+	workspaces ON workspaces.id = shared_volume_attachments.workspace_id
+JOIN
+	users ON users.id = workspaces.owner_id
+JOIN
+	workspace_agents ON workspace_agents.id = shared_volume_attachments.workspace_agent_id
+WHERE
+	shared_volume_attachments.shared_volume_id = @shared_volume_id
+	AND shared_volume_attachments.detached_at IS NULL
+ORDER BY
+	shared_volume_attachments.attached_at, shared_volume_attachments.id;
+
+-- name: DetachSharedVolumeAttachmentsByWorkspaceID :exec
+-- Detaches the volumes attached by previous builds of a workspace. Every build
+-- attaches the volumes it declares anew.
+UPDATE
+	shared_volume_attachments
+SET
+	detached_at = @detached_at::timestamptz
+WHERE
+	workspace_id = @workspace_id
+	AND detached_at IS NULL;
+
+-- name: DetachSharedVolumeAttachmentByID :one
+-- Force-detaches an active attachment, releasing the read-write lock it holds.
+UPDATE
+	shared_volume_attachments
+SET
+	detached_at = @detached_at::timestamptz,
+	detached_by = @detached_by::uuid
+WHERE
+	id = @id
+	AND detached_at IS NULL
+RETURNING *;
@@ -84,6 +84,15 @@ SELECT
 INSERT INTO site_configs (key, value) VALUES ('prebuilds_settings', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'prebuilds_settings';
 
+-- name: GetProvisionerCacheSettings :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'provisioner_cache_settings'), '{}') :: text AS provisioner_cache_settings
+;
+
+-- name: UpsertProvisionerCacheSettings :exec
+INSERT INTO site_configs (key, value) VALUES ('provisioner_cache_settings', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'provisioner_cache_settings';
+
 -- name: GetRuntimeConfig :one
 SELECT value FROM site_configs WHERE site_configs.key = $1;
 
@@ -0,0 +1,107 @@
+-- name: GetTemplateHealthStats :many
+-- Returns the health of the templates that had builds since @since or that
+-- have workspaces. Builds are counted since @since, while workspaces are
+-- counted as they are now. Prebuilt workspaces are left out, as they are
+-- managed by the reconciler rather than by users.
+WITH builds AS (
+	SELECT
+		w.template_id,
+		COUNT(*) AS total_builds,
+		COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds,
+		AVG(EXTRACT(EPOCH FROM (pj.completed_at - pj.created_at)))
+			FILTER (WHERE wb.transition = 'start' AND pj.job_status = 'succeeded') AS average_start_seconds
+	FROM
+		workspace_builds AS wb
+	JOIN
+		workspaces AS w ON wb.workspace_id = w.id
+	JOIN
+		provisioner_jobs AS pj ON wb.job_id = pj.id
+	WHERE
+		wb.created_at >= @since
+		AND pj.completed_at IS NOT NULL
+		AND w.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+	GROUP BY
+		w.template_id
+), fleet AS (
+	SELECT
+		w.template_id,
+		COUNT(*) AS total_workspaces,
+		COUNT(*) FILTER (WHERE wlb.template_version_id != t.active_version_id) AS outdated_workspaces,
+		COUNT(*) FILTER (WHERE w.dormant_at IS NOT NULL) AS dormant_workspaces
+	FROM
+		workspaces AS w
+	JOIN
+		templates AS t ON w.template_id = t.id
+	JOIN
+		workspace_latest_builds AS wlb ON wlb.workspace_id = w.id
+	WHERE
+		w.deleted = false
+		AND w.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+	GROUP BY
+		w.template_id
+)
+SELECT
+	t.id AS template_id,
+	t.name AS template_name,
+	t.display_name AS template_display_name,
+	t.organization_id AS template_organization_id,
+	COALESCE(builds.total_builds, 0)::bigint AS total_builds,
+	COALESCE(builds.failed_builds, 0)::bigint AS failed_builds,
+	COALESCE(builds.average_start_seconds, 0)::double precision AS average_start_seconds,
+	COALESCE(fleet.total_workspaces, 0)::bigint AS total_workspaces,
+	COALESCE(fleet.outdated_workspaces, 0)::bigint AS outdated_workspaces,
+	COALESCE(fleet.dormant_workspaces, 0)::bigint AS dormant_workspaces
+FROM
+	templates AS t
+LEFT JOIN
+	builds ON builds.template_id = t.id
+LEFT JOIN
+	fleet ON fleet.template_id = t.id
+WHERE
+	t.deleted = false
+	AND (builds.template_id IS NOT NULL OR fleet.template_id IS NOT NULL)
+ORDER BY
+	t.name ASC, t.id ASC;
+
+-- name: InsertTemplateHealthReport :one
+INSERT INTO
+	template_health_reports (
+		id,
+		template_id,
+		period_start,
+		period_end,
+		total_builds,
+		failed_builds,
+		average_start_seconds,
+		total_workspaces,
+		outdated_workspaces,
+		dormant_workspaces,
+		created_at
+	)
+VALUES (
+	@id,
+	@template_id,
+	@period_start,
+	@period_end,
+	@total_builds,
+	@failed_builds,
+	@average_start_seconds,
+	@total_workspaces,
+	@outdated_workspaces,
+	@dormant_workspaces,
+	@created_at
+)
+RETURNING *;
+
+-- name: GetTemplateHealthReportsByTemplateID :many
+SELECT
+	*
+FROM
+	template_health_reports
+WHERE
+	template_id = @template_id
+ORDER BY
+	period_end DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
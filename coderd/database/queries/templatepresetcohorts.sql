@@ -0,0 +1,33 @@
+-- name: GetTemplatePresetCohortsByTemplateID :many
+SELECT
+	*
+FROM
+	template_preset_cohorts
+WHERE
+	template_id = @template_id
+ORDER BY
+	preset_name ASC;
+
+-- name: InsertTemplatePresetCohort :one
+INSERT INTO
+	template_preset_cohorts (
+		template_id,
+		preset_name,
+		group_ids,
+		roles,
+		created_at
+	)
+VALUES (
+	@template_id,
+	@preset_name,
+	@group_ids,
+	@roles,
+	@created_at
+)
+RETURNING *;
+
+-- name: DeleteTemplatePresetCohortsByTemplateID :exec
+DELETE FROM
+	template_preset_cohorts
+WHERE
+	template_id = @template_id;
@@ -137,10 +137,13 @@ INSERT INTO
 		allow_user_cancel_workspace_jobs,
 		max_port_sharing_level,
 		use_classic_parameter_flow,
-		cors_behavior
+		cors_behavior,
+		provisioner_plan_timeout,
+		provisioner_apply_timeout,
+		trial_workspace_ttl
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17);
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20);
 
 -- name: UpdateTemplateActiveVersionByID :exec
 UPDATE
@@ -174,7 +177,20 @@ SET
 	max_port_sharing_level = $9,
 	use_classic_parameter_flow = $10,
 	cors_behavior = $11,
-	disable_module_cache = $12
+	disable_module_cache = $12,
+	provisioner_plan_timeout = $13,
+	provisioner_apply_timeout = $14,
+	trial_workspace_ttl = $15,
+	requeue_reaped_builds = $16,
+	agent_rollout_channel = $17,
+	allow_targeted_builds = $18,
+	reconfirm_parameters = $19,
+	build_log_retention = $20,
+	pre_build_hook_url = $21,
+	post_build_hook_url = $22,
+	auto_assign_region = $23,
+	notify_build_failures = $24,
+	disable_provider_cache = $25
 WHERE
 	id = $1
 ;
@@ -194,7 +210,14 @@ SET
 	failure_ttl = $10,
 	time_til_dormant = $11,
 	time_til_dormant_autodelete = $12,
-	time_til_autostop_notify = $13
+	time_til_autostop_notify = $13,
+	nightly_stop_time = $14,
+	max_lifetime = $15,
+	max_lifetime_action = $16,
+	idle_reclaim_ttl = $17,
+	idle_reclaim_resource_selector = $18,
+	activity_bump_connection_types = $19,
+	activity_bump_max_per_day = $20
 WHERE
 	id = $1
 ;
@@ -246,7 +269,8 @@ UPDATE
 	templates
 SET
 	require_active_version = $2,
-	deprecated = $3
+	deprecated = $3,
+	deprecation_cutoff = $4
 WHERE
 	id = $1
 ;
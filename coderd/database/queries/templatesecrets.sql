@@ -0,0 +1,70 @@
+-- name: GetTemplateSecretByID :one
+SELECT *
+FROM template_secrets
+WHERE id = @id;
+
+-- name: GetTemplateSecretByTemplateIDAndName :one
+SELECT *
+FROM template_secrets
+WHERE template_id = @template_id AND name = @name;
+
+-- name: GetTemplateSecretsByTemplateID :many
+-- Returns all columns including the secret value. The API only returns
+-- metadata, and the agent fetches the values referenced by its scripts.
+SELECT *
+FROM template_secrets
+WHERE template_id = @template_id
+ORDER BY name ASC;
+
+-- name: GetTemplateSecrets :many
+-- Returns the secrets of all templates. Used by the dbcrypt key rotation
+-- utility.
+SELECT *
+FROM template_secrets
+ORDER BY template_id, name ASC;
+
+-- name: InsertTemplateSecret :one
+INSERT INTO template_secrets (
+    id,
+    template_id,
+    name,
+    description,
+    value,
+    value_key_id,
+    created_at,
+    updated_at
+) VALUES (
+    @id,
+    @template_id,
+    @name,
+    @description,
+    @value,
+    @value_key_id,
+    @created_at,
+    @created_at
+) RETURNING *;
+
+-- name: UpdateTemplateSecretByTemplateIDAndName :one
+UPDATE template_secrets
+SET
+    value        = CASE WHEN @update_value::bool THEN @value ELSE value END,
+    value_key_id = CASE WHEN @update_value::bool THEN @value_key_id ELSE value_key_id END,
+    description  = CASE WHEN @update_description::bool THEN @description ELSE description END,
+    updated_at   = @updated_at
+WHERE template_id = @template_id AND name = @name
+RETURNING *;
+
+-- name: UpdateEncryptedTemplateSecret :one
+-- Updates only the encrypted columns of a secret. Used by the dbcrypt key
+-- rotation utility to re-encrypt or decrypt rows in place.
+UPDATE template_secrets
+SET
+    value = @value,
+    value_key_id = sqlc.narg('value_key_id')::text
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteTemplateSecretByTemplateIDAndName :one
+DELETE FROM template_secrets
+WHERE template_id = @template_id AND name = @name
+RETURNING *;
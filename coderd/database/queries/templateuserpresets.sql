@@ -0,0 +1,51 @@
+-- name: UpsertTemplateUserPreset :one
+-- Saves a named set of parameter values of a user for a template. Saving a
+-- preset with the name of an existing one replaces its parameters.
+INSERT INTO
+	template_user_presets (
+		id,
+		template_id,
+		user_id,
+		name,
+		parameters,
+		created_at,
+		updated_at
+	)
+VALUES (
+	@id,
+	@template_id,
+	@user_id,
+	@name,
+	@parameters,
+	@now,
+	@now
+)
+ON CONFLICT (template_id, user_id, name) DO UPDATE SET
+	parameters = EXCLUDED.parameters,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetTemplateUserPresetByID :one
+SELECT
+	*
+FROM
+	template_user_presets
+WHERE
+	id = @id;
+
+-- name: GetTemplateUserPresetsByTemplateIDAndUserID :many
+SELECT
+	*
+FROM
+	template_user_presets
+WHERE
+	template_id = @template_id
+	AND user_id = @user_id
+ORDER BY
+	name ASC;
+
+-- name: DeleteTemplateUserPresetByID :exec
+DELETE FROM
+	template_user_presets
+WHERE
+	id = @id;
@@ -18,7 +18,11 @@ INSERT INTO
         required,
         display_name,
         display_order,
-        ephemeral
+        ephemeral,
+        sensitive,
+        group_name,
+        group_order,
+        page
     )
 VALUES
     (
@@ -39,7 +43,11 @@ VALUES
         $15,
         $16,
         $17,
-        $18
+        $18,
+        $19,
+        $20,
+        $21,
+        $22
     ) RETURNING *;
 
 -- name: GetTemplateVersionParameters :many
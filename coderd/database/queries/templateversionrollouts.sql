@@ -0,0 +1,114 @@
+-- name: InsertTemplateVersionRollout :one
+INSERT INTO
+	template_version_rollouts (
+		id,
+		template_id,
+		template_version_id,
+		previous_version_id,
+		group_id,
+		stages,
+		soak_duration,
+		max_failure_rate,
+		min_builds,
+		created_by,
+		created_at,
+		updated_at,
+		stage_started_at
+	)
+VALUES (
+	@id,
+	@template_id,
+	@template_version_id,
+	@previous_version_id,
+	@group_id,
+	@stages::integer[],
+	@soak_duration,
+	@max_failure_rate,
+	@min_builds,
+	@created_by,
+	@created_at,
+	@created_at,
+	@created_at
+)
+RETURNING *;
+
+-- name: GetTemplateVersionRolloutByID :one
+SELECT
+	*
+FROM
+	template_version_rollouts
+WHERE
+	id = @id;
+
+-- name: GetTemplateVersionRolloutsByTemplateID :many
+SELECT
+	*
+FROM
+	template_version_rollouts
+WHERE
+	template_id = @template_id
+ORDER BY
+	created_at DESC;
+
+-- name: GetActiveTemplateVersionRolloutByTemplateID :one
+SELECT
+	*
+FROM
+	template_version_rollouts
+WHERE
+	template_id = @template_id
+	AND status = 'in_progress'::template_version_rollout_status;
+
+-- name: GetActiveTemplateVersionRollouts :many
+SELECT
+	*
+FROM
+	template_version_rollouts
+WHERE
+	status = 'in_progress'::template_version_rollout_status
+ORDER BY
+	created_at ASC;
+
+-- name: GetTemplateVersionRolloutBuildStats :one
+-- Counts the completed start builds of a template version created since the
+-- given time, and how many of them failed. Used to compute the failure rate
+-- of a rollout stage.
+SELECT
+	COUNT(*) AS builds,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status = 'failed'::provisioner_job_status) AS failed_builds
+FROM
+	workspace_builds
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_builds.template_version_id = @template_version_id
+	AND workspace_builds.transition = 'start'::workspace_transition
+	AND workspace_builds.created_at >= @since
+	AND provisioner_jobs.job_status IN ('succeeded'::provisioner_job_status, 'failed'::provisioner_job_status);
+
+-- name: UpdateTemplateVersionRolloutStage :one
+UPDATE
+	template_version_rollouts
+SET
+	current_stage = @current_stage,
+	stage_started_at = @stage_started_at,
+	updated_at = @stage_started_at
+WHERE
+	id = @id
+	AND status = 'in_progress'::template_version_rollout_status
+RETURNING *;
+
+-- name: UpdateTemplateVersionRolloutStatus :one
+-- Finishes an in-progress rollout. No rows are returned if the rollout is
+-- already finished.
+UPDATE
+	template_version_rollouts
+SET
+	status = @status,
+	status_message = @status_message,
+	updated_at = @updated_at,
+	completed_at = @updated_at
+WHERE
+	id = @id
+	AND status = 'in_progress'::template_version_rollout_status
+RETURNING *;
@@ -104,6 +104,16 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateTemplateVersionDeprecationByID :exec
+UPDATE
+	template_versions
+SET
+	deprecated = @deprecated,
+	deprecation_cutoff = @deprecation_cutoff,
+	updated_at = @updated_at
+WHERE
+	id = @id;
+
 -- name: UpdateTemplateVersionDescriptionByJobID :exec
 UPDATE
 	template_versions
@@ -0,0 +1,55 @@
+-- name: ClaimTemplateVersionScans :many
+-- Claims template versions whose import job succeeded after completed_after
+-- and that have not been scanned yet, or whose scan was claimed before
+-- stale_before and never finished. Concurrent callers never claim the same
+-- template version.
+INSERT INTO template_version_scans (template_version_id, status, started_at)
+SELECT
+	template_versions.id,
+	'running'::template_version_scan_status,
+	@now::timestamptz
+FROM
+	template_versions
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = template_versions.job_id
+LEFT JOIN
+	template_version_scans ON template_version_scans.template_version_id = template_versions.id
+WHERE
+	provisioner_jobs.job_status = 'succeeded'::provisioner_job_status
+	AND provisioner_jobs.completed_at >= @completed_after::timestamptz
+	AND (
+		template_version_scans.template_version_id IS NULL
+		OR (
+			template_version_scans.status = 'running'::template_version_scan_status
+			AND template_version_scans.started_at < @stale_before::timestamptz
+		)
+	)
+ORDER BY
+	provisioner_jobs.completed_at
+LIMIT
+	@max_scans::int
+ON CONFLICT (template_version_id) DO UPDATE SET
+	started_at = EXCLUDED.started_at
+WHERE
+	template_version_scans.status = 'running'::template_version_scan_status
+	AND template_version_scans.started_at < @stale_before::timestamptz
+RETURNING *;
+
+-- name: GetTemplateVersionScanByTemplateVersionID :one
+SELECT
+	*
+FROM
+	template_version_scans
+WHERE
+	template_version_id = @template_version_id;
+
+-- name: UpdateTemplateVersionScanByTemplateVersionID :exec
+UPDATE
+	template_version_scans
+SET
+	status = @status,
+	findings = @findings,
+	error = @error,
+	completed_at = @completed_at
+WHERE
+	template_version_id = @template_version_id;
@@ -0,0 +1,200 @@
+-- name: InsertTemplateWorkspaceRestart :one
+INSERT INTO
+	template_workspace_restarts (
+		id,
+		template_id,
+		template_version_id,
+		batch_size,
+		batch_interval,
+		honor_quiet_hours,
+		max_failure_rate,
+		min_builds,
+		created_by,
+		created_at,
+		updated_at,
+		stats_since
+	)
+VALUES (
+	@id,
+	@template_id,
+	@template_version_id,
+	@batch_size,
+	@batch_interval,
+	@honor_quiet_hours,
+	@max_failure_rate,
+	@min_builds,
+	@created_by,
+	@created_at,
+	@created_at,
+	@created_at
+)
+RETURNING *;
+
+-- name: GetTemplateWorkspaceRestartByID :one
+SELECT
+	*
+FROM
+	template_workspace_restarts
+WHERE
+	id = @id;
+
+-- name: GetTemplateWorkspaceRestartsByTemplateID :many
+SELECT
+	*
+FROM
+	template_workspace_restarts
+WHERE
+	template_id = @template_id
+ORDER BY
+	created_at DESC;
+
+-- name: GetInProgressTemplateWorkspaceRestarts :many
+SELECT
+	*
+FROM
+	template_workspace_restarts
+WHERE
+	status = 'in_progress'::template_workspace_restart_status
+ORDER BY
+	created_at ASC;
+
+-- name: GetTemplateWorkspaceRestartCandidates :many
+-- Returns the running workspaces of the template that are not on the given
+-- version and have not been restarted by the restart yet. The least recently
+-- used workspaces are returned first, since they are the least likely to be
+-- in use. Dormant workspaces and prebuilt workspaces are skipped.
+SELECT
+	workspaces.id,
+	workspaces.owner_id
+FROM
+	workspaces
+JOIN LATERAL (
+	SELECT
+		workspace_builds.template_version_id,
+		workspace_builds.transition,
+		provisioner_jobs.job_status
+	FROM
+		workspace_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	WHERE
+		workspace_builds.workspace_id = workspaces.id
+	ORDER BY
+		workspace_builds.build_number DESC
+	LIMIT 1
+) latest_build ON TRUE
+WHERE
+	workspaces.template_id = @template_id
+	AND workspaces.deleted = false
+	AND workspaces.dormant_at IS NULL
+	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The prebuilds system user.
+	AND latest_build.template_version_id != @template_version_id
+	AND latest_build.transition = 'start'::workspace_transition
+	AND latest_build.job_status = 'succeeded'::provisioner_job_status
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			template_workspace_restart_builds
+		WHERE
+			template_workspace_restart_builds.restart_id = @restart_id
+			AND template_workspace_restart_builds.workspace_id = workspaces.id
+	)
+ORDER BY
+	workspaces.last_used_at ASC,
+	workspaces.id ASC;
+
+-- name: GetTemplateWorkspaceRestartBuildStats :one
+-- Counts the builds of a restart by the status of their provisioner job.
+-- Builds that could not be created count as failed. The completed and failed
+-- builds since the given time are used to compute the failure rate.
+SELECT
+	COUNT(*) AS restarted,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status = 'succeeded'::provisioner_job_status) AS succeeded,
+	COUNT(*) FILTER (
+		WHERE template_workspace_restart_builds.error != ''
+		OR provisioner_jobs.job_status IN ('failed'::provisioner_job_status, 'canceled'::provisioner_job_status)
+	) AS failed,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status IN ('pending'::provisioner_job_status, 'running'::provisioner_job_status, 'canceling'::provisioner_job_status)) AS pending,
+	COUNT(*) FILTER (
+		WHERE template_workspace_restart_builds.created_at >= @since
+		AND (
+			template_workspace_restart_builds.error != ''
+			OR provisioner_jobs.job_status IN ('succeeded'::provisioner_job_status, 'failed'::provisioner_job_status, 'canceled'::provisioner_job_status)
+		)
+	) AS completed_since,
+	COUNT(*) FILTER (
+		WHERE template_workspace_restart_builds.created_at >= @since
+		AND (
+			template_workspace_restart_builds.error != ''
+			OR provisioner_jobs.job_status IN ('failed'::provisioner_job_status, 'canceled'::provisioner_job_status)
+		)
+	) AS failed_since
+FROM
+	template_workspace_restart_builds
+LEFT JOIN
+	workspace_builds ON workspace_builds.id = template_workspace_restart_builds.workspace_build_id
+LEFT JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	template_workspace_restart_builds.restart_id = @restart_id;
+
+-- name: InsertTemplateWorkspaceRestartBuild :exec
+INSERT INTO
+	template_workspace_restart_builds (
+		restart_id,
+		workspace_id,
+		workspace_build_id,
+		error,
+		created_at
+	)
+VALUES (
+	@restart_id,
+	@workspace_id,
+	@workspace_build_id,
+	@error,
+	@created_at
+);
+
+-- name: UpdateTemplateWorkspaceRestartLastBatchAt :exec
+UPDATE
+	template_workspace_restarts
+SET
+	last_batch_at = @last_batch_at,
+	updated_at = @last_batch_at
+WHERE
+	id = @id
+	AND status = 'in_progress'::template_workspace_restart_status;
+
+-- name: UpdateTemplateWorkspaceRestartStatus :one
+-- Pauses, completes or aborts an unfinished restart. No rows are returned if
+-- the restart is already finished.
+UPDATE
+	template_workspace_restarts
+SET
+	status = @status,
+	status_message = @status_message,
+	updated_at = @updated_at,
+	completed_at = CASE
+		WHEN @status::template_workspace_restart_status IN ('completed'::template_workspace_restart_status, 'aborted'::template_workspace_restart_status) THEN @updated_at::timestamptz
+		ELSE NULL
+	END
+WHERE
+	id = @id
+	AND status IN ('in_progress'::template_workspace_restart_status, 'paused'::template_workspace_restart_status)
+RETURNING *;
+
+-- name: ResumeTemplateWorkspaceRestart :one
+-- Resumes a paused restart. Failures before the restart was resumed no longer
+-- count towards its failure rate.
+UPDATE
+	template_workspace_restarts
+SET
+	status = 'in_progress'::template_workspace_restart_status,
+	status_message = '',
+	updated_at = @updated_at,
+	stats_since = @updated_at
+WHERE
+	id = @id
+	AND status = 'paused'::template_workspace_restart_status
+RETURNING *;
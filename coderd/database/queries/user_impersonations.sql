@@ -0,0 +1,66 @@
+-- name: InsertUserImpersonation :one
+INSERT INTO
+	user_impersonations (
+		id,
+		user_id,
+		impersonator_id,
+		reason,
+		lifetime_seconds,
+		consent_required,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: GetUserImpersonationByID :one
+SELECT
+	*
+FROM
+	user_impersonations
+WHERE
+	id = $1;
+
+-- name: GetUserImpersonationByAPIKeyID :one
+SELECT
+	*
+FROM
+	user_impersonations
+WHERE
+	api_key_id = @api_key_id :: text;
+
+-- name: GetUserImpersonationsByUserID :many
+SELECT
+	*
+FROM
+	user_impersonations
+WHERE
+	user_id = $1
+ORDER BY
+	created_at DESC;
+
+-- name: UpdateUserImpersonationConsentedAt :one
+-- Records the consent of the impersonated user. Requests that were already
+-- consented to or granted are left untouched, so no rows are returned.
+UPDATE
+	user_impersonations
+SET
+	consented_at = @consented_at
+WHERE
+	id = @id
+	AND consent_required
+	AND consented_at IS NULL
+	AND issued_at IS NULL
+RETURNING *;
+
+-- name: UpdateUserImpersonationIssued :one
+-- Marks the request as granted. A token is only ever issued once per
+-- request, so no rows are returned for requests that were already granted.
+UPDATE
+	user_impersonations
+SET
+	api_key_id = @api_key_id,
+	issued_at = @issued_at
+WHERE
+	id = @id
+	AND issued_at IS NULL
+RETURNING *;
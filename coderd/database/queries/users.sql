@@ -324,6 +324,29 @@ WHERE user_configs.user_id = @user_id
 	AND user_configs.key = 'preference_task_notification_alert_dismissed'
 RETURNING value::boolean AS task_notification_alert_dismissed;
 
+-- name: GetUserWorkspaceSelfRequestedBuildsAllowed :one
+SELECT
+	COALESCE((
+		SELECT value = 'true'
+		FROM user_configs
+		WHERE user_id = @user_id
+			AND key = 'preference_workspace_self_requested_builds_allowed'
+	), false) :: boolean AS workspace_self_requested_builds_allowed;
+
+-- name: UpdateUserWorkspaceSelfRequestedBuildsAllowed :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	(@user_id, 'preference_workspace_self_requested_builds_allowed', (@workspace_self_requested_builds_allowed::boolean)::text)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = (@workspace_self_requested_builds_allowed::boolean)::text
+WHERE user_configs.user_id = @user_id
+	AND user_configs.key = 'preference_workspace_self_requested_builds_allowed'
+RETURNING value::boolean AS workspace_self_requested_builds_allowed;
+
 -- name: GetUserThinkingDisplayMode :one
 SELECT
 	value AS thinking_display_mode
@@ -0,0 +1,28 @@
+-- name: GetUserSchedulePause :one
+SELECT *
+FROM user_schedule_pauses
+WHERE user_id = @user_id;
+
+-- name: UpsertUserSchedulePause :one
+INSERT INTO user_schedule_pauses (
+	user_id,
+	paused_until,
+	pause_autostop,
+	created_at,
+	updated_at
+) VALUES (
+	@user_id,
+	@paused_until,
+	@pause_autostop,
+	@created_at,
+	@updated_at
+)
+ON CONFLICT (user_id) DO UPDATE SET
+	paused_until = EXCLUDED.paused_until,
+	pause_autostop = EXCLUDED.pause_autostop,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteUserSchedulePause :exec
+DELETE FROM user_schedule_pauses
+WHERE user_id = @user_id;
@@ -0,0 +1,88 @@
+-- name: GetUserWorkspaceReportStats :many
+-- Returns the workspaces of active users that had builds since @since, that
+-- will be deleted before @until, or that will become dormant before @until if
+-- they are not used. Prebuilt workspaces are left out, as they are managed by
+-- the reconciler rather than by users.
+WITH builds AS (
+	SELECT
+		wb.workspace_id,
+		COUNT(*) AS total_builds,
+		COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds
+	FROM
+		workspace_builds AS wb
+	JOIN
+		provisioner_jobs AS pj ON wb.job_id = pj.id
+	WHERE
+		wb.created_at >= @since
+		AND pj.completed_at IS NOT NULL
+	GROUP BY
+		wb.workspace_id
+)
+SELECT
+	w.id AS workspace_id,
+	w.name AS workspace_name,
+	w.owner_id,
+	t.name AS template_name,
+	t.display_name AS template_display_name,
+	COALESCE(builds.total_builds, 0)::bigint AS total_builds,
+	COALESCE(builds.failed_builds, 0)::bigint AS failed_builds,
+	w.last_used_at,
+	w.dormant_at,
+	w.deleting_at,
+	t.time_til_dormant
+FROM
+	workspaces AS w
+JOIN
+	templates AS t ON w.template_id = t.id
+JOIN
+	users AS u ON w.owner_id = u.id
+LEFT JOIN
+	builds ON builds.workspace_id = w.id
+WHERE
+	w.deleted = false
+	AND u.deleted = false
+	AND u.status = 'active'::user_status
+	AND w.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+	AND (
+		builds.workspace_id IS NOT NULL
+		OR (w.deleting_at IS NOT NULL AND w.deleting_at < @until)
+		OR (
+			w.dormant_at IS NULL
+			AND t.time_til_dormant > 0
+			AND w.last_used_at + (INTERVAL '1 millisecond' * (t.time_til_dormant / 1000000)) < @until
+		)
+	)
+ORDER BY
+	w.owner_id ASC, w.name ASC;
+
+-- name: InsertUserWorkspaceReport :one
+INSERT INTO
+	user_workspace_reports (
+		id,
+		user_id,
+		period_start,
+		period_end,
+		summary,
+		created_at
+	)
+VALUES (
+	@id,
+	@user_id,
+	@period_start,
+	@period_end,
+	@summary,
+	@created_at
+)
+RETURNING *;
+
+-- name: GetLatestUserWorkspaceReportByUserID :one
+SELECT
+	*
+FROM
+	user_workspace_reports
+WHERE
+	user_id = @user_id
+ORDER BY
+	period_end DESC
+LIMIT
+	1;
@@ -0,0 +1,74 @@
+-- name: GetVCSEventMappingByID :one
+SELECT *
+FROM vcs_event_mappings
+WHERE id = @id;
+
+-- name: GetVCSEventMappingsByTemplateID :many
+SELECT *
+FROM vcs_event_mappings
+WHERE template_id = @template_id
+ORDER BY name ASC;
+
+-- name: GetVCSEventMappingsByRepository :many
+-- Returns the mappings of a repository along with their signing secrets, so
+-- that the VCS webhook receiver can verify events.
+SELECT *
+FROM vcs_event_mappings
+WHERE lower(repository) = lower(@repository::text)
+ORDER BY created_at ASC, id ASC;
+
+-- name: GetVCSEventMappings :many
+-- Returns the mappings of all templates. Used by the dbcrypt key rotation
+-- utility.
+SELECT *
+FROM vcs_event_mappings
+ORDER BY template_id, name ASC;
+
+-- name: InsertVCSEventMapping :one
+INSERT INTO vcs_event_mappings (
+    id,
+    template_id,
+    name,
+    repository,
+    branch,
+    action,
+    workspace_id,
+    signing_secret,
+    signing_secret_key_id,
+    created_by,
+    created_at,
+    updated_at
+) VALUES (
+    @id,
+    @template_id,
+    @name,
+    @repository,
+    @branch,
+    @action,
+    @workspace_id,
+    @signing_secret,
+    @signing_secret_key_id,
+    @created_by,
+    @created_at,
+    @created_at
+) RETURNING *;
+
+-- name: UpdateVCSEventMappingLastTriggeredAt :exec
+UPDATE vcs_event_mappings
+SET last_triggered_at = @last_triggered_at
+WHERE id = @id;
+
+-- name: UpdateEncryptedVCSEventMapping :one
+-- Updates only the encrypted columns of a mapping. Used by the dbcrypt key
+-- rotation utility to re-encrypt or decrypt rows in place.
+UPDATE vcs_event_mappings
+SET
+    signing_secret = @signing_secret,
+    signing_secret_key_id = sqlc.narg('signing_secret_key_id')::text
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteVCSEventMappingByTemplateIDAndID :one
+DELETE FROM vcs_event_mappings
+WHERE template_id = @template_id AND id = @id
+RETURNING *;
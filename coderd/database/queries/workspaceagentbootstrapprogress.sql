@@ -0,0 +1,35 @@
+-- name: InsertWorkspaceAgentBootstrapProgress :one
+INSERT INTO
+	workspace_agent_bootstrap_progress (
+		id,
+		workspace_agent_id,
+		created_at,
+		name,
+		percent,
+		message
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetWorkspaceAgentBootstrapProgressByAgentID :many
+-- Returns all milestones reported by an agent, oldest first.
+SELECT
+	*
+FROM
+	workspace_agent_bootstrap_progress
+WHERE
+	workspace_agent_id = $1
+ORDER BY
+	created_at ASC, id ASC;
+
+-- name: GetLatestWorkspaceAgentBootstrapProgressByAgentIDs :many
+-- Returns the current milestone of each of the given agents.
+SELECT DISTINCT ON (workspace_agent_id)
+	*
+FROM
+	workspace_agent_bootstrap_progress
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	workspace_agent_id, created_at DESC, id DESC;
@@ -0,0 +1,49 @@
+-- name: GetWorkspaceAgentCrashLoopByAgentID :one
+SELECT
+	*
+FROM
+	workspace_agent_crash_loops
+WHERE
+	agent_id = $1;
+
+-- name: GetWorkspaceAgentCrashLoopsByAgentIDs :many
+SELECT
+	*
+FROM
+	workspace_agent_crash_loops
+WHERE
+	agent_id = ANY(@ids :: uuid [ ]);
+
+-- name: UpsertWorkspaceAgentCrashLoop :one
+INSERT INTO
+	workspace_agent_crash_loops (
+		agent_id,
+		short_connections,
+		last_disconnected_at,
+		last_disconnect_reason,
+		last_lifecycle_state,
+		crash_looping_since
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+)
+ON CONFLICT (agent_id)
+DO UPDATE SET
+	short_connections = $2,
+	last_disconnected_at = $3,
+	last_disconnect_reason = $4,
+	last_lifecycle_state = $5,
+	crash_looping_since = $6
+RETURNING *;
+
+-- name: DeleteWorkspaceAgentCrashLoop :exec
+-- Removes the crash loop tracking of an agent once it stays connected.
+DELETE FROM
+	workspace_agent_crash_loops
+WHERE
+	agent_id = $1;
@@ -0,0 +1,49 @@
+-- name: InsertWorkspaceAgentMemoryEvent :one
+INSERT INTO
+	workspace_agent_memory_events (
+		id,
+		workspace_agent_id,
+		created_at,
+		kind,
+		oom_kills,
+		memory_pressure
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: GetWorkspaceAgentMemoryEventsByAgentIDs :many
+-- Returns the memory events reported by the given agents since the given
+-- time, newest first.
+SELECT
+	*
+FROM
+	workspace_agent_memory_events
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+	AND created_at >= @created_after :: timestamptz
+ORDER BY
+	created_at DESC, id DESC
+LIMIT
+	1000;
+
+-- name: GetWorkspaceAgentMemoryEventCountsByAgentIDs :many
+-- Returns the number of OOM kills and memory pressure events reported by
+-- each of the given agents since the given time. Agents without events are
+-- omitted.
+SELECT
+	workspace_agent_id,
+	SUM(oom_kills) :: bigint AS oom_kills,
+	COUNT(*) FILTER (WHERE kind = 'memory_pressure') :: bigint AS memory_pressure_events
+FROM
+	workspace_agent_memory_events
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+	AND created_at >= @created_after :: timestamptz
+GROUP BY
+	workspace_agent_id;
+
+-- name: DeleteOldWorkspaceAgentMemoryEvents :exec
+DELETE FROM
+	workspace_agent_memory_events
+WHERE
+	created_at < @before_time :: timestamptz;
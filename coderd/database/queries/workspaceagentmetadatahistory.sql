@@ -0,0 +1,65 @@
+-- name: InsertWorkspaceAgentMetadataHistory :exec
+-- Records a batch of agent metadata values. Values that were already recorded
+-- for the same key and collection time are ignored.
+INSERT INTO
+	workspace_agent_metadata_history (
+		workspace_agent_id,
+		key,
+		value,
+		error,
+		collected_at
+	)
+SELECT
+	unnest(@workspace_agent_id::uuid[]),
+	unnest(@key::text[]),
+	unnest(@value::text[]),
+	unnest(@error::text[]),
+	unnest(@collected_at::timestamptz[])
+ON CONFLICT DO NOTHING;
+
+-- name: GetWorkspaceAgentMetadataHistory :many
+-- Returns the values of an agent metadata key collected since the given time,
+-- oldest first. When bucket_seconds is positive, only the latest value of each
+-- bucket of that many seconds is returned, which downsamples long histories.
+SELECT DISTINCT ON (bucket)
+	CASE
+		WHEN @bucket_seconds::bigint > 0
+		THEN to_timestamp(floor(extract(epoch FROM collected_at) / @bucket_seconds::bigint) * @bucket_seconds::bigint)
+		ELSE collected_at
+	END::timestamptz AS bucket,
+	value,
+	error,
+	collected_at
+FROM
+	workspace_agent_metadata_history
+WHERE
+	workspace_agent_id = @workspace_agent_id
+	AND key = @key
+	AND collected_at >= @since::timestamptz
+ORDER BY
+	bucket ASC, collected_at DESC;
+
+-- name: DeleteOldWorkspaceAgentMetadataHistory :execrows
+-- Deletes agent metadata history older than the given time, bounded by a row
+-- limit to avoid long-running transactions.
+WITH old_history AS (
+	SELECT
+		workspace_agent_id,
+		key,
+		collected_at
+	FROM
+		workspace_agent_metadata_history
+	WHERE
+		collected_at < @before_time::timestamptz
+	ORDER BY
+		collected_at ASC
+	LIMIT @limit_count
+)
+DELETE FROM
+	workspace_agent_metadata_history
+USING
+	old_history
+WHERE
+	workspace_agent_metadata_history.workspace_agent_id = old_history.workspace_agent_id
+	AND workspace_agent_metadata_history.key = old_history.key
+	AND workspace_agent_metadata_history.collected_at = old_history.collected_at;
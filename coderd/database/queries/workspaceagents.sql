@@ -110,6 +110,14 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspaceAgentClockSkewByID :exec
+UPDATE
+	workspace_agents
+SET
+	clock_skew_ms = $2
+WHERE
+	id = $1;
+
 -- name: UpdateWorkspaceAgentStartupByID :exec
 UPDATE
 	workspace_agents
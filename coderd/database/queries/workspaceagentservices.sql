@@ -0,0 +1,60 @@
+-- name: InsertWorkspaceAgentServices :many
+INSERT INTO
+	workspace_agent_services (workspace_agent_id, name, display_name, command, restart_policy, created_at)
+SELECT
+	@workspace_agent_id :: uuid AS workspace_agent_id,
+	unnest(@name :: text [ ]) AS name,
+	unnest(@display_name :: text [ ]) AS display_name,
+	unnest(@command :: text [ ]) AS command,
+	unnest(@restart_policy :: workspace_agent_service_restart_policy [ ]) AS restart_policy,
+	@created_at :: timestamptz AS created_at
+RETURNING workspace_agent_services.*;
+
+-- name: GetWorkspaceAgentServicesByAgentIDs :many
+SELECT
+	*
+FROM
+	workspace_agent_services
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	workspace_agent_id, name;
+
+-- name: InsertWorkspaceAgentServiceTransition :one
+INSERT INTO
+	workspace_agent_service_transitions (
+		id,
+		workspace_agent_id,
+		service_name,
+		created_at,
+		status,
+		message,
+		exit_code,
+		restart_count
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetWorkspaceAgentServiceTransitionsByAgentIDs :many
+-- Returns every status transition of the services of the given agents,
+-- oldest first.
+SELECT
+	*
+FROM
+	workspace_agent_service_transitions
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	created_at ASC, id ASC;
+
+-- name: GetLatestWorkspaceAgentServiceTransitionsByAgentIDs :many
+-- Returns the current status of each service of the given agents.
+SELECT DISTINCT ON (workspace_agent_id, service_name)
+	*
+FROM
+	workspace_agent_service_transitions
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	workspace_agent_id, service_name, created_at DESC, id DESC;
@@ -0,0 +1,44 @@
+-- name: InsertWorkspaceAppShareLink :one
+INSERT INTO
+	workspace_app_share_links (
+		id,
+		workspace_id,
+		agent_name,
+		app_slug,
+		hashed_secret,
+		created_by,
+		created_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: GetWorkspaceAppShareLinkByID :one
+SELECT
+	*
+FROM
+	workspace_app_share_links
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceAppShareLinksByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_app_share_links
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC;
+
+-- name: RevokeWorkspaceAppShareLink :one
+-- Links that were already revoked are left untouched, so no rows are
+-- returned.
+UPDATE
+	workspace_app_share_links
+SET
+	revoked_at = @revoked_at
+WHERE
+	id = @id
+	AND revoked_at IS NULL
+RETURNING *;
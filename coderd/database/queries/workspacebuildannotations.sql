@@ -0,0 +1,26 @@
+-- name: InsertWorkspaceBuildAnnotation :one
+INSERT INTO
+	workspace_build_annotations (
+		id,
+		workspace_build_id,
+		user_id,
+		note,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetWorkspaceBuildAnnotationsByBuildIDs :many
+SELECT
+	workspace_build_annotations.*,
+	users.username
+FROM
+	workspace_build_annotations
+INNER JOIN
+	users ON users.id = workspace_build_annotations.user_id
+WHERE
+	workspace_build_annotations.workspace_build_id = ANY(@workspace_build_ids :: uuid [ ])
+ORDER BY
+	workspace_build_annotations.created_at ASC,
+	workspace_build_annotations.id ASC;
@@ -0,0 +1,16 @@
+-- name: InsertWorkspaceBuildInputs :exec
+-- The inputs of a build are immutable, so a build whose job is acquired
+-- again keeps the inputs captured first.
+INSERT INTO
+	workspace_build_inputs (workspace_build_id, inputs, created_at)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (workspace_build_id) DO NOTHING;
+
+-- name: GetWorkspaceBuildInputsByBuildID :one
+SELECT
+	*
+FROM
+	workspace_build_inputs
+WHERE
+	workspace_build_id = $1;
@@ -1,10 +1,16 @@
 -- name: InsertWorkspaceBuildParameters :exec
+-- Sensitive and ValueKeyID may be shorter than Name and Value. Missing
+-- entries insert parameters that are not sensitive and not encrypted.
 INSERT INTO
-    workspace_build_parameters (workspace_build_id, name, value)
+    workspace_build_parameters (workspace_build_id, name, value, sensitive, value_key_id)
 SELECT
     @workspace_build_id :: uuid AS workspace_build_id,
-    unnest(@name :: text[]) AS name,
-    unnest(@value :: text[]) AS value
+    p.name,
+    p.value,
+    COALESCE(p.sensitive, false),
+    NULLIF(p.value_key_id, '')
+FROM
+    unnest(@name :: text[], @value :: text[], @sensitive :: boolean[], @value_key_id :: text[]) AS p(name, value, sensitive, value_key_id)
 RETURNING *;
 
 -- name: GetWorkspaceBuildParameters :many
@@ -15,6 +21,26 @@ FROM
 WHERE
     workspace_build_id = $1;
 
+-- name: GetSensitiveWorkspaceBuildParameters :many
+-- Used by dbcrypt to encrypt, re-encrypt or decrypt the values of sensitive
+-- parameters.
+SELECT
+    *
+FROM
+    workspace_build_parameters
+WHERE
+    sensitive = true;
+
+-- name: UpdateEncryptedWorkspaceBuildParameter :exec
+UPDATE
+    workspace_build_parameters
+SET
+    value = @value,
+    value_key_id = @value_key_id
+WHERE
+    workspace_build_id = @workspace_build_id
+    AND name = @name;
+
 -- name: GetUserWorkspaceBuildParameters :many
 SELECT name, value
 FROM (
@@ -35,6 +61,7 @@ FROM (
 		AND wb.transition = 'start'
 		AND w.template_id = $2
 		AND tvp.ephemeral = false
+		AND tvp.sensitive = false
 		AND tvp.name = wbp.name
     ORDER BY
         tvp.name, wb.created_at DESC
@@ -42,3 +69,24 @@ FROM (
 ORDER BY created_at DESC, name
 LIMIT 100;
 
+
+-- name: InsertWorkspaceBuildParameterChanges :exec
+INSERT INTO
+    workspace_build_parameter_changes (workspace_build_id, name, kind, previous_value, new_value, redacted)
+SELECT
+    @workspace_build_id :: uuid AS workspace_build_id,
+    unnest(@name :: text[]) AS name,
+    unnest(@kind :: workspace_build_parameter_change_kind[]) AS kind,
+    unnest(@previous_value :: text[]) AS previous_value,
+    unnest(@new_value :: text[]) AS new_value,
+    unnest(@redacted :: boolean[]) AS redacted;
+
+-- name: GetWorkspaceBuildParameterChangesByBuildIDs :many
+SELECT
+    *
+FROM
+    workspace_build_parameter_changes
+WHERE
+    workspace_build_id = ANY(@workspace_build_ids :: uuid[])
+ORDER BY
+    workspace_build_id, name;
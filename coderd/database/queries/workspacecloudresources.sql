@@ -0,0 +1,107 @@
+-- name: GetLeakedWorkspaceCloudResources :many
+-- Returns the resources that still existed at their cloud provider after the
+-- workspace that created them was deleted, most recently verified first.
+SELECT
+	sqlc.embed(workspace_cloud_resources),
+	workspaces_expanded.name AS workspace_name,
+	workspaces_expanded.owner_id,
+	workspaces_expanded.owner_username,
+	workspaces_expanded.organization_id,
+	workspaces_expanded.template_id,
+	workspaces_expanded.template_name
+FROM
+	workspace_cloud_resources
+JOIN
+	workspaces_expanded ON workspaces_expanded.id = workspace_cloud_resources.workspace_id
+WHERE
+	workspace_cloud_resources.verification_status = 'leaked'::cloud_resource_verification_status
+ORDER BY
+	workspace_cloud_resources.verified_at DESC,
+	workspace_cloud_resources.workspace_id,
+	workspace_cloud_resources.resource_type,
+	workspace_cloud_resources.cloud_id;
+
+-- name: GetUnverifiedWorkspaceCloudResources :many
+-- Returns the unverified resources of workspaces whose latest build is a
+-- successful deletion that completed before completed_before, along with the
+-- ID of the deletion build.
+SELECT
+	sqlc.embed(workspace_cloud_resources),
+	deletion_builds.id AS deletion_build_id
+FROM
+	workspace_cloud_resources
+JOIN LATERAL (
+	SELECT
+		workspace_builds.id,
+		workspace_builds.transition,
+		provisioner_jobs.job_status,
+		provisioner_jobs.completed_at
+	FROM
+		workspace_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	WHERE
+		workspace_builds.workspace_id = workspace_cloud_resources.workspace_id
+	ORDER BY
+		workspace_builds.build_number DESC
+	LIMIT 1
+) deletion_builds ON true
+WHERE
+	workspace_cloud_resources.verification_status = 'unverified'::cloud_resource_verification_status
+	AND deletion_builds.transition = 'delete'::workspace_transition
+	AND deletion_builds.job_status = 'succeeded'::provisioner_job_status
+	AND deletion_builds.completed_at < @completed_before::timestamptz
+ORDER BY
+	deletion_builds.completed_at,
+	workspace_cloud_resources.workspace_id,
+	workspace_cloud_resources.resource_type,
+	workspace_cloud_resources.cloud_id
+LIMIT
+	@limit_count::int;
+
+-- name: GetWorkspaceCloudResourcesByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_cloud_resources
+WHERE
+	workspace_id = @workspace_id
+ORDER BY
+	created_at, resource_type, cloud_id;
+
+-- name: UpdateWorkspaceCloudResourceVerification :exec
+UPDATE
+	workspace_cloud_resources
+SET
+	verification_status = @verification_status,
+	verification_build_id = @verification_build_id,
+	verification_error = @verification_error,
+	verified_at = @verified_at
+WHERE
+	workspace_id = @workspace_id
+	AND resource_type = @resource_type
+	AND cloud_id = @cloud_id;
+
+-- name: UpsertWorkspaceCloudResource :exec
+-- Records a resource reported by a build. A resource reported again by a
+-- later build exists at that time, so any earlier verification is reset.
+INSERT INTO
+	workspace_cloud_resources (
+		workspace_id,
+		resource_type,
+		cloud_id,
+		resource_name,
+		workspace_build_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	(@workspace_id, @resource_type, @cloud_id, @resource_name, @workspace_build_id, @now, @now)
+ON CONFLICT (workspace_id, resource_type, cloud_id) DO UPDATE SET
+	resource_name = EXCLUDED.resource_name,
+	workspace_build_id = EXCLUDED.workspace_build_id,
+	updated_at = EXCLUDED.updated_at,
+	verification_status = 'unverified'::cloud_resource_verification_status,
+	verification_build_id = NULL,
+	verification_error = '',
+	verified_at = NULL;
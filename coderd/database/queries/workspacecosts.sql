@@ -0,0 +1,131 @@
+-- name: AccrueWorkspaceEstimatedCosts :exec
+-- Adds the estimated cost of every running workspace since the last accrual
+-- to its counter for the month, based on the daily cost of its latest build.
+-- Estimated counters of workspaces that are not running only advance their
+-- accrual time, and counters imported from a billing export are left alone.
+-- Concurrent runs do not double count, as every run only accrues the time
+-- since the previous one.
+WITH running AS (
+	SELECT
+		workspace_latest_builds.workspace_id,
+		workspace_builds.daily_cost
+	FROM
+		workspace_latest_builds
+	JOIN
+		workspace_builds ON workspace_builds.id = workspace_latest_builds.id
+	JOIN
+		workspaces ON workspaces.id = workspace_latest_builds.workspace_id
+	WHERE
+		workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+		AND workspace_latest_builds.transition = 'start'::workspace_transition
+		AND workspace_latest_builds.job_status = 'succeeded'::provisioner_job_status
+		AND workspace_builds.daily_cost > 0
+), idle AS (
+	UPDATE
+		workspace_monthly_costs
+	SET
+		updated_at = @now::timestamptz
+	WHERE
+		month = @month::timestamptz
+		AND source = 'estimate'::workspace_cost_source
+		AND workspace_id NOT IN (SELECT workspace_id FROM running)
+)
+INSERT INTO
+	workspace_monthly_costs (
+		workspace_id,
+		month,
+		cost,
+		source,
+		updated_at
+	)
+SELECT
+	running.workspace_id,
+	@month::timestamptz,
+	0,
+	'estimate'::workspace_cost_source,
+	@now::timestamptz
+FROM
+	running
+ON CONFLICT (workspace_id, month)
+DO UPDATE SET
+	cost = workspace_monthly_costs.cost + (
+		SELECT
+			running.daily_cost
+		FROM
+			running
+		WHERE
+			running.workspace_id = workspace_monthly_costs.workspace_id
+	) * EXTRACT(EPOCH FROM (@now::timestamptz - workspace_monthly_costs.updated_at))::float8 / 86400,
+	updated_at = @now::timestamptz
+WHERE
+	workspace_monthly_costs.source = 'estimate'::workspace_cost_source
+	AND workspace_monthly_costs.updated_at < @now::timestamptz;
+
+-- name: GetWorkspaceMonthlyCost :one
+SELECT
+	*
+FROM
+	workspace_monthly_costs
+WHERE
+	workspace_id = @workspace_id
+	AND month = @month;
+
+-- name: GetWorkspacesExceedingCostBudget :many
+-- Returns the workspaces whose cost in the month exceeds the per-workspace
+-- budget, or whose owner's workspaces together exceed the per-user budget. A
+-- budget of 0 is disabled. Deleted workspaces are not returned, but their cost
+-- still counts towards the per-user budget.
+WITH costs AS (
+	SELECT
+		workspace_monthly_costs.workspace_id,
+		workspaces.owner_id,
+		workspaces.deleted,
+		workspace_monthly_costs.cost,
+		SUM(workspace_monthly_costs.cost) OVER (PARTITION BY workspaces.owner_id) AS user_cost
+	FROM
+		workspace_monthly_costs
+	JOIN
+		workspaces ON workspaces.id = workspace_monthly_costs.workspace_id
+	WHERE
+		workspace_monthly_costs.month = @month::timestamptz
+)
+SELECT
+	workspace_id,
+	owner_id,
+	cost::float8 AS workspace_cost,
+	user_cost::float8 AS user_cost
+FROM
+	costs
+WHERE
+	NOT deleted
+	AND (
+		(@workspace_budget::bigint > 0 AND cost > @workspace_budget::bigint)
+		OR (@user_budget::bigint > 0 AND user_cost > @user_budget::bigint)
+	)
+ORDER BY
+	workspace_id;
+
+-- name: UpsertWorkspaceMonthlyCost :one
+-- Imports the cost of a workspace from a billing export. The imported cost
+-- replaces the estimate for the month.
+INSERT INTO
+	workspace_monthly_costs (
+		workspace_id,
+		month,
+		cost,
+		source,
+		updated_at
+	)
+VALUES (
+	@workspace_id,
+	@month,
+	@cost,
+	'billing_export'::workspace_cost_source,
+	@now
+)
+ON CONFLICT (workspace_id, month)
+DO UPDATE SET
+	cost = @cost,
+	source = 'billing_export'::workspace_cost_source,
+	updated_at = @now
+RETURNING *;
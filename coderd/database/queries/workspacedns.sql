@@ -0,0 +1,82 @@
+-- name: GetWorkspaceDNSRecordChanges :many
+-- Returns the workspaces whose DNS record is out of date: running workspaces
+-- without a record, or with a record under another name or target, and
+-- records of workspaces that are no longer running. A workspace is running
+-- once the provisioner job of its latest start build succeeded. Prebuilt
+-- workspaces are never registered.
+WITH desired AS (
+	SELECT
+		workspaces.id AS workspace_id,
+		workspaces.name AS workspace_name,
+		users.username AS owner_username,
+		lower(workspaces.name || '--' || users.username || '.' || @domain::text) AS name
+	FROM
+		workspaces
+	JOIN
+		users ON users.id = workspaces.owner_id
+	JOIN
+		workspace_latest_builds ON workspace_latest_builds.workspace_id = workspaces.id
+	WHERE
+		workspaces.deleted = false
+		AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+		AND workspace_latest_builds.transition = 'start'::workspace_transition
+		AND workspace_latest_builds.job_status = 'succeeded'::provisioner_job_status
+)
+SELECT
+	COALESCE(desired.workspace_id, workspace_dns_records.workspace_id)::uuid AS workspace_id,
+	COALESCE(desired.workspace_name, '')::text AS workspace_name,
+	COALESCE(desired.owner_username, '')::text AS owner_username,
+	COALESCE(desired.name, '')::text AS desired_name,
+	COALESCE(workspace_dns_records.name, '')::text AS registered_name,
+	COALESCE(workspace_dns_records.target, '')::text AS registered_target
+FROM
+	desired
+FULL OUTER JOIN
+	workspace_dns_records ON workspace_dns_records.workspace_id = desired.workspace_id
+WHERE
+	desired.name IS DISTINCT FROM workspace_dns_records.name
+	OR (
+		desired.workspace_id IS NOT NULL
+		AND workspace_dns_records.target != @target::text
+	)
+ORDER BY
+	COALESCE(desired.workspace_id, workspace_dns_records.workspace_id)
+LIMIT
+	@max_changes::int;
+
+-- name: GetWorkspaceDNSRecordsByWorkspaceIDs :many
+SELECT
+	*
+FROM
+	workspace_dns_records
+WHERE
+	workspace_id = ANY(@ids::uuid[]);
+
+-- name: UpsertWorkspaceDNSRecord :one
+INSERT INTO
+	workspace_dns_records (
+		workspace_id,
+		name,
+		target,
+		created_at,
+		updated_at
+	)
+VALUES (
+	@workspace_id,
+	@name,
+	@target,
+	@now,
+	@now
+)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	name = @name,
+	target = @target,
+	updated_at = @now
+RETURNING *;
+
+-- name: DeleteWorkspaceDNSRecordByWorkspaceID :exec
+DELETE FROM
+	workspace_dns_records
+WHERE
+	workspace_id = @workspace_id;
@@ -0,0 +1,39 @@
+-- name: GetWorkspaceDormancyExemptionByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_dormancy_exemptions
+WHERE
+	workspace_id = $1;
+
+-- name: UpsertWorkspaceDormancyExemption :one
+INSERT INTO
+	workspace_dormancy_exemptions (
+		workspace_id,
+		reason,
+		expires_at,
+		approved_by,
+		created_at,
+		updated_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$5
+)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	reason = $2,
+	expires_at = $3,
+	approved_by = $4,
+	updated_at = $5
+RETURNING *;
+
+-- name: DeleteWorkspaceDormancyExemption :exec
+DELETE FROM
+	workspace_dormancy_exemptions
+WHERE
+	workspace_id = $1;
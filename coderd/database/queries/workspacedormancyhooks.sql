@@ -0,0 +1,76 @@
+-- name: UpsertWorkspaceDormancyHook :one
+-- Schedules the hook of a workspace for the given event, replacing any
+-- earlier invocation. The hook runner picks it up on its next tick.
+INSERT INTO workspace_dormancy_hooks (
+	workspace_id,
+	event,
+	status,
+	error,
+	created_at,
+	updated_at
+) VALUES (
+	@workspace_id,
+	@event,
+	'pending'::workspace_dormancy_hook_status,
+	'',
+	@now,
+	@now
+)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	event = EXCLUDED.event,
+	status = EXCLUDED.status,
+	error = EXCLUDED.error,
+	created_at = EXCLUDED.created_at,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetWorkspaceDormancyHookByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_dormancy_hooks
+WHERE
+	workspace_id = @workspace_id;
+
+-- name: ClaimWorkspaceDormancyHooks :many
+-- Marks up to @limit_count pending hooks as running and returns them. Hooks
+-- that have been running since before @stale_before are claimed again, so
+-- invocations interrupted by a restart are retried.
+UPDATE
+	workspace_dormancy_hooks
+SET
+	status = 'running'::workspace_dormancy_hook_status,
+	updated_at = @now
+WHERE
+	workspace_id IN (
+		SELECT
+			workspace_id
+		FROM
+			workspace_dormancy_hooks
+		WHERE
+			status = 'pending'::workspace_dormancy_hook_status
+			OR (
+				status = 'running'::workspace_dormancy_hook_status
+				AND updated_at < @stale_before
+			)
+		ORDER BY
+			updated_at ASC
+		LIMIT
+			@limit_count::int
+		FOR UPDATE SKIP LOCKED
+	)
+RETURNING *;
+
+-- name: UpdateWorkspaceDormancyHookStatus :execrows
+-- Records the outcome of a running hook. Nothing is updated if the hook was
+-- replaced by another event in the meantime.
+UPDATE
+	workspace_dormancy_hooks
+SET
+	status = @status,
+	error = @error,
+	updated_at = @now
+WHERE
+	workspace_id = @workspace_id
+	AND event = @event
+	AND status = 'running'::workspace_dormancy_hook_status;
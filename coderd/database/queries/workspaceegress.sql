@@ -0,0 +1,69 @@
+-- name: UpsertWorkspaceEgressDaily :exec
+-- Adds the bytes from a single agent stats report to the workspace's total
+-- for the given day.
+INSERT INTO
+	workspace_egress_daily (
+		workspace_id,
+		template_id,
+		owner_id,
+		date,
+		rx_bytes_ssh,
+		tx_bytes_ssh,
+		rx_bytes_reconnecting_pty,
+		tx_bytes_reconnecting_pty,
+		rx_bytes_port_forward,
+		tx_bytes_port_forward
+	)
+VALUES
+	(
+		@workspace_id,
+		@template_id,
+		@owner_id,
+		@date::date,
+		@rx_bytes_ssh,
+		@tx_bytes_ssh,
+		@rx_bytes_reconnecting_pty,
+		@tx_bytes_reconnecting_pty,
+		@rx_bytes_port_forward,
+		@tx_bytes_port_forward
+	)
+ON CONFLICT (workspace_id, date)
+DO UPDATE SET
+	rx_bytes_ssh = workspace_egress_daily.rx_bytes_ssh + EXCLUDED.rx_bytes_ssh,
+	tx_bytes_ssh = workspace_egress_daily.tx_bytes_ssh + EXCLUDED.tx_bytes_ssh,
+	rx_bytes_reconnecting_pty = workspace_egress_daily.rx_bytes_reconnecting_pty + EXCLUDED.rx_bytes_reconnecting_pty,
+	tx_bytes_reconnecting_pty = workspace_egress_daily.tx_bytes_reconnecting_pty + EXCLUDED.tx_bytes_reconnecting_pty,
+	rx_bytes_port_forward = workspace_egress_daily.rx_bytes_port_forward + EXCLUDED.rx_bytes_port_forward,
+	tx_bytes_port_forward = workspace_egress_daily.tx_bytes_port_forward + EXCLUDED.tx_bytes_port_forward;
+
+-- name: GetWorkspaceEgressInsights :many
+-- Returns per workspace, per day egress for the UTC days that overlap
+-- [start_time, end_time), optionally filtered by template.
+SELECT
+	wed.workspace_id,
+	w.name AS workspace_name,
+	wed.owner_id,
+	u.username AS owner_username,
+	wed.template_id,
+	t.name AS template_name,
+	wed.date,
+	wed.rx_bytes_ssh,
+	wed.tx_bytes_ssh,
+	wed.rx_bytes_reconnecting_pty,
+	wed.tx_bytes_reconnecting_pty,
+	wed.rx_bytes_port_forward,
+	wed.tx_bytes_port_forward
+FROM
+	workspace_egress_daily wed
+JOIN
+	workspaces w ON w.id = wed.workspace_id
+JOIN
+	users u ON u.id = wed.owner_id
+JOIN
+	templates t ON t.id = wed.template_id
+WHERE
+	wed.date >= (@start_time::timestamptz AT TIME ZONE 'UTC')::date
+	AND (wed.date::timestamp AT TIME ZONE 'UTC') < @end_time::timestamptz
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN wed.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+ORDER BY
+	wed.date, u.username, w.name;
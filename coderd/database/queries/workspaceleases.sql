@@ -0,0 +1,59 @@
+-- name: InsertWorkspaceLease :one
+INSERT INTO
+	workspace_leases (
+		id,
+		workspace_id,
+		template_id,
+		owner_id,
+		api_key_id,
+		credential_key_id,
+		created_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: GetWorkspaceLeaseByID :one
+SELECT
+	*
+FROM
+	workspace_leases
+WHERE
+	id = $1;
+
+-- name: CountActiveWorkspaceLeasesByAPIKeyID :one
+-- Counts the leases created by an API key that have not been released yet.
+-- Expired leases count until the reaper releases them, so a token cannot
+-- exceed its cap while teardown is pending.
+SELECT
+	COUNT(*)
+FROM
+	workspace_leases
+WHERE
+	api_key_id = $1
+	AND released_at IS NULL;
+
+-- name: GetExpiredWorkspaceLeases :many
+SELECT
+	*
+FROM
+	workspace_leases
+WHERE
+	released_at IS NULL
+	AND expires_at <= @now::timestamptz
+ORDER BY
+	expires_at ASC
+LIMIT
+	@max_leases::int;
+
+-- name: ReleaseWorkspaceLease :one
+-- Marks a lease as released. Returns no rows if the lease was already
+-- released, so concurrent releases tear the workspace down only once.
+UPDATE
+	workspace_leases
+SET
+	released_at = @released_at
+WHERE
+	id = @id
+	AND released_at IS NULL
+RETURNING *;
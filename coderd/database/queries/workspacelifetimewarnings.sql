@@ -0,0 +1,29 @@
+-- name: GetWorkspaceLifetimeWarningByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_lifetime_warnings
+WHERE
+	workspace_id = $1;
+
+-- name: UpsertWorkspaceLifetimeWarning :exec
+-- Records that the owner of a workspace was warned about its max lifetime
+-- expiry. Only the most recent warning is kept.
+INSERT INTO
+	workspace_lifetime_warnings (
+		workspace_id,
+		expires_at,
+		days_before,
+		sent_at
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4
+)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	expires_at = $2,
+	days_before = $3,
+	sent_at = $4;
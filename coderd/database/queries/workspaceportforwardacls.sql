@@ -0,0 +1,47 @@
+-- name: GetWorkspacePortForwardACLsByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_port_forward_acls
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at, user_id;
+
+-- name: GetWorkspacePortForwardACL :one
+SELECT
+	*
+FROM
+	workspace_port_forward_acls
+WHERE
+	workspace_id = $1
+	AND user_id = $2;
+
+-- name: UpsertWorkspacePortForwardACL :one
+INSERT INTO
+	workspace_port_forward_acls (
+		workspace_id,
+		user_id,
+		ports,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $4)
+ON CONFLICT (workspace_id, user_id) DO UPDATE SET
+	ports = EXCLUDED.ports,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteWorkspacePortForwardACL :exec
+DELETE FROM
+	workspace_port_forward_acls
+WHERE
+	workspace_id = $1
+	AND user_id = $2;
+
+-- name: DeleteWorkspacePortForwardACLsByWorkspaceID :exec
+DELETE FROM
+	workspace_port_forward_acls
+WHERE
+	workspace_id = $1;
@@ -0,0 +1,39 @@
+-- name: GetWorkspaceRegionAssignmentsByWorkspaceIDs :many
+SELECT
+	*
+FROM
+	workspace_region_assignments
+WHERE
+	workspace_id = ANY(@ids::uuid[]);
+
+-- name: GetWorkspaceRegionAssignmentCounts :many
+-- Returns the number of non-deleted workspaces assigned to each region.
+SELECT
+	workspace_region_assignments.region_id,
+	COUNT(*) AS count
+FROM
+	workspace_region_assignments
+JOIN
+	workspaces ON workspaces.id = workspace_region_assignments.workspace_id
+WHERE
+	workspaces.deleted = false
+GROUP BY
+	workspace_region_assignments.region_id;
+
+-- name: UpsertWorkspaceRegionAssignment :one
+INSERT INTO
+	workspace_region_assignments (
+		workspace_id,
+		region_id,
+		created_at
+	)
+VALUES (
+	@workspace_id,
+	@region_id,
+	@created_at
+)
+ON CONFLICT (workspace_id)
+DO UPDATE SET
+	region_id = @region_id,
+	created_at = @created_at
+RETURNING *;
@@ -252,7 +252,10 @@ WHERE
 			ON
 				LOWER(workspace_build_parameters.name) = build_params.name AND
 				LOWER(workspace_build_parameters.value) = build_params.value AND
-				workspace_build_parameters.workspace_build_id = latest_build.id
+				workspace_build_parameters.workspace_build_id = latest_build.id AND
+				-- Values of sensitive parameters are encrypted and must not
+				-- be searchable.
+				NOT workspace_build_parameters.sensitive
 		)
 		ELSE true
 	END
@@ -345,6 +348,28 @@ WHERE
 				  workspaces.last_used_at >= @last_used_after
 		  ELSE true
 	END
+	-- Filter by created_at
+	AND CASE
+		WHEN @created_before :: timestamp with time zone > '0001-01-01 00:00:00Z' THEN
+			workspaces.created_at <= @created_before
+		ELSE true
+	END
+	AND CASE
+		WHEN @created_after :: timestamp with time zone > '0001-01-01 00:00:00Z' THEN
+			workspaces.created_at >= @created_after
+		ELSE true
+	END
+	-- Filter by the template version of the latest build
+	AND CASE
+		WHEN @template_version_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			latest_build.template_version_id = @template_version_id
+		ELSE true
+	END
+	AND CASE
+		WHEN @template_version_name :: text != '' THEN
+			lower(latest_build.template_version_name) = lower(@template_version_name)
+		ELSE true
+	END
   	AND CASE
 		  WHEN sqlc.narg('using_active') :: boolean IS NOT NULL THEN
 			  (latest_build.template_version_id = template.active_version_id) = sqlc.narg('using_active') :: boolean
@@ -398,6 +423,17 @@ WHERE
 	FROM
 		filtered_workspaces fw
 	ORDER BY
+		-- An explicit sort takes precedence over the default ordering below,
+		-- which then only breaks ties. Each key is a plain column or
+		-- LOWER(name), matching the workspaces_*_idx sort indexes, so that
+		-- once the sort is bound the planner reduces these CASE expressions to
+		-- the one key selected and can read it from the index.
+		CASE WHEN @sort :: text = 'last_used_asc' THEN last_used_at END ASC,
+		CASE WHEN @sort :: text = 'last_used_desc' THEN last_used_at END DESC,
+		CASE WHEN @sort :: text = 'name_asc' THEN LOWER(name) END ASC,
+		CASE WHEN @sort :: text = 'name_desc' THEN LOWER(name) END DESC,
+		CASE WHEN @sort :: text = 'created_at_asc' THEN created_at END ASC,
+		CASE WHEN @sort :: text = 'created_at_desc' THEN created_at END DESC,
 		-- To ensure that 'favorite' workspaces show up first in the list only for their owner.
 		CASE WHEN favorite AND owner_username = (SELECT users.username FROM users WHERE users.id = @requester_id) THEN 0 ELSE 1 END ASC,
 		(latest_build_completed_at IS NOT NULL AND
@@ -440,6 +476,9 @@ WHERE
 		'0001-01-01 00:00:00+00'::timestamptz, -- next_start_at
 		'{}'::jsonb, -- group_acl
 		'{}'::jsonb, -- user_acl
+		'0001-01-01 00:00:00+00'::timestamptz, -- expires_at
+		'0001-01-01 00:00:00+00'::timestamptz, -- archived_at
+		'0001-01-01 00:00:00+00'::timestamptz, -- read_only_at
 		'', -- owner_avatar_url
 		'', -- owner_username
 		'', -- owner_name
@@ -556,6 +595,41 @@ FROM
 LEFT JOIN user_usage u ON u.template_id = t.template_id
 LEFT JOIN org_usage o ON o.template_id = t.template_id;
 
+-- name: GetOrphanedWorkspaces :many
+-- Returns the workspaces whose owner has been deleted, or is no longer a
+-- member of the organization of the workspace. Such workspaces can no longer
+-- be managed by their owner, and are left behind when users are removed
+-- outside of the normal offboarding flow.
+SELECT
+	sqlc.embed(workspaces),
+	users.username AS owner_username,
+	users.deleted AS owner_deleted,
+	organizations.name AS organization_name,
+	templates.name AS template_name
+FROM
+	workspaces
+	JOIN users ON users.id = workspaces.owner_id
+	JOIN organizations ON organizations.id = workspaces.organization_id
+	JOIN templates ON templates.id = workspaces.template_id
+WHERE
+	workspaces.deleted = false
+	AND users.is_system = false
+	AND (
+		users.deleted = true
+		OR NOT EXISTS (
+			SELECT
+				1
+			FROM
+				organization_members
+			WHERE
+				organization_members.user_id = workspaces.owner_id
+				AND organization_members.organization_id = workspaces.organization_id
+		)
+	)
+ORDER BY
+	workspaces.created_at ASC,
+	workspaces.id ASC;
+
 -- name: InsertWorkspace :one
 INSERT INTO
 	workspaces (
@@ -570,10 +644,11 @@ INSERT INTO
 		ttl,
 		last_used_at,
 		automatic_updates,
-		next_start_at
+		next_start_at,
+		expires_at
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING *;
 
 -- name: UpdateWorkspaceDeletedByID :exec
 UPDATE
@@ -583,6 +658,23 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspaceArchivedAt :exec
+UPDATE
+	workspaces
+SET
+	archived_at = $2
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceReadOnlyAt :one
+UPDATE
+	workspaces
+SET
+	read_only_at = $2
+WHERE
+	id = $1
+RETURNING *;
+
 -- name: UpdateWorkspace :one
 UPDATE
 	workspaces
@@ -593,6 +685,20 @@ WHERE
 	AND deleted = false
 RETURNING *;
 
+-- name: UpdateWorkspaceOwnerByID :one
+-- Transfers a workspace to another owner. The name is updated as well, since
+-- it must be unique among the workspaces of the new owner.
+UPDATE
+	workspaces
+SET
+	owner_id = $2,
+	name = $3,
+	updated_at = $4
+WHERE
+	id = $1
+	AND deleted = false
+RETURNING *;
+
 -- name: UpdateWorkspaceAutostart :exec
 UPDATE
 	workspaces
@@ -814,10 +920,18 @@ WHERE
 		--   * The workspace is not dormant.
 		--   * The template has set a time 'til dormant.
 		--   * The workspace has been unused for longer than the time 'til dormancy.
+		--   * The workspace does not have an unexpired dormancy exemption.
 		(
 			workspaces.dormant_at IS NULL AND
 			templates.time_til_dormant > 0 AND
-			(@now :: timestamptz) - workspaces.last_used_at > (INTERVAL '1 millisecond' * (templates.time_til_dormant / 1000000))
+			(@now :: timestamptz) - workspaces.last_used_at > (INTERVAL '1 millisecond' * (templates.time_til_dormant / 1000000)) AND
+			NOT EXISTS (
+				SELECT 1
+				FROM workspace_dormancy_exemptions
+				WHERE
+					workspace_dormancy_exemptions.workspace_id = workspaces.id AND
+					workspace_dormancy_exemptions.expires_at > @now :: timestamptz
+			)
 		) OR
 
 		-- A workspace may be eligible for deletion if the following are true:
@@ -868,6 +982,68 @@ WHERE
 			(@now :: timestamptz) - provisioner_jobs.completed_at > (INTERVAL '1 millisecond' * (templates.failure_ttl / 1000000))
 		) OR
 
+		-- A trial workspace may be eligible to be stopped and then deleted if the
+		-- following are true:
+		--   * The workspace has an expiry and it has passed.
+		--   * The provisioner job has finished.
+		--   * If there was a prior attempt to delete the workspace that failed:
+		--      * This attempt was at least 24 hours ago.
+		(
+			workspaces.expires_at IS NOT NULL AND
+			workspaces.expires_at <= @now :: timestamptz AND
+			(
+				provisioner_jobs.canceled_at IS NOT NULL OR
+				provisioner_jobs.completed_at IS NOT NULL
+			) AND
+			CASE
+				WHEN (
+					workspace_builds.transition = 'delete'::workspace_transition AND
+					provisioner_jobs.job_status = 'failed'::provisioner_job_status
+				) THEN (
+					(@now :: timestamptz) - (CASE
+						WHEN provisioner_jobs.canceled_at IS NOT NULL THEN provisioner_jobs.canceled_at
+						ELSE provisioner_jobs.completed_at
+					END) > INTERVAL '24 hours'
+				)
+				ELSE true
+			END
+		) OR
+
+		-- A workspace may be eligible for a max lifetime warning or expiry if
+		-- the following are true:
+		--   * The template has a max lifetime set.
+		--   * The workspace is within 7 days (the earliest warning) of reaching it.
+		--   * The provisioner job has finished.
+		--   * The template deletes expired workspaces, or the workspace is running.
+		(
+			templates.max_lifetime > 0 AND
+			workspaces.created_at + (INTERVAL '1 millisecond' * (templates.max_lifetime / 1000000)) - INTERVAL '7 days' <= @now :: timestamptz AND
+			(
+				provisioner_jobs.canceled_at IS NOT NULL OR
+				provisioner_jobs.completed_at IS NOT NULL
+			) AND
+			(
+				templates.max_lifetime_action = 'delete'::max_lifetime_action OR
+				workspace_builds.transition = 'start'::workspace_transition
+			)
+		) OR
+
+		-- A workspace may be eligible for idle reclaim if the following are true:
+		--   * The template has an idle reclaim policy.
+		--   * The latest build is a successfully provisioned start build.
+		--   * The workspace is not dormant.
+		--   * It has not been used for longer than the idle reclaim TTL since
+		--     the build finished.
+		-- Whether its resources match the resource selector of the policy is
+		-- checked by the lifecycle executor.
+		(
+			templates.idle_reclaim_ttl > 0 AND
+			provisioner_jobs.job_status = 'succeeded'::provisioner_job_status AND
+			workspace_builds.transition = 'start'::workspace_transition AND
+			workspaces.dormant_at IS NULL AND
+			GREATEST(workspaces.last_used_at, provisioner_jobs.completed_at) + (INTERVAL '1 millisecond' * (templates.idle_reclaim_ttl / 1000000)) <= @now :: timestamptz
+		) OR
+
 		-- A workspace may be eligible for an autostop reminder if the following are true:
 		--   * The latest build is a successfully provisioned start build.
 		--   * The workspace is not dormant and its owner is not suspended.
@@ -920,6 +1096,12 @@ WHERE
 		)
 	)
   	AND workspaces.deleted = 'false'
+	-- Archived workspaces have no resources to act on and stay archived until
+	-- they are explicitly unarchived.
+	AND workspaces.archived_at IS NULL
+	-- Read-only workspaces are under investigation, so their resources must
+	-- be left untouched until the mode is lifted.
+	AND workspaces.read_only_at IS NULL
   	-- Prebuilt workspaces (identified by having the prebuilds system user as owner_id)
 	-- should not be considered by the lifecycle executor, as they are handled by the
 	-- prebuilds reconciliation loop.
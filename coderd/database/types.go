@@ -29,6 +29,16 @@ type AuditOAuthConvertState struct {
 	UserID      uuid.UUID `db:"user_id" json:"user_id"`
 }
 
+// DeploymentBundle is never stored in the database. It describes a
+// diagnostics bundle downloaded from the debug endpoints and is provided
+// for audit logging purposes.
+type DeploymentBundle struct {
+	ID       uuid.UUID `db:"id" json:"id"`
+	Filename string    `db:"filename" json:"filename"`
+	// The files included in the bundle.
+	Files []string `db:"files" json:"files"`
+}
+
 type HealthSettings struct {
 	ID                    uuid.UUID `db:"id" json:"id"`
 	DismissedHealthchecks []string  `db:"dismissed_healthchecks" json:"dismissed_healthchecks"`
@@ -49,6 +49,7 @@ const (
 	UniqueGroupMembersUserIDGroupIDKey                        UniqueConstraint = "group_members_user_id_group_id_key"                              // ALTER TABLE ONLY group_members ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
 	UniqueGroupsNameOrganizationIDKey                         UniqueConstraint = "groups_name_organization_id_key"                                 // ALTER TABLE ONLY groups ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
 	UniqueGroupsPkey                                          UniqueConstraint = "groups_pkey"                                                     // ALTER TABLE ONLY groups ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
+	UniqueIdempotencyKeysPkey                                 UniqueConstraint = "idempotency_keys_pkey"                                           // ALTER TABLE ONLY idempotency_keys ADD CONSTRAINT idempotency_keys_pkey PRIMARY KEY (user_id, operation, key);
 	UniqueInboxNotificationsPkey                              UniqueConstraint = "inbox_notifications_pkey"                                        // ALTER TABLE ONLY inbox_notifications ADD CONSTRAINT inbox_notifications_pkey PRIMARY KEY (id);
 	UniqueJfrogXrayScansPkey                                  UniqueConstraint = "jfrog_xray_scans_pkey"                                           // ALTER TABLE ONLY jfrog_xray_scans ADD CONSTRAINT jfrog_xray_scans_pkey PRIMARY KEY (agent_id, workspace_id);
 	UniqueLicensesJWTKey                                      UniqueConstraint = "licenses_jwt_key"                                                // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
@@ -60,6 +61,8 @@ const (
 	UniqueNotificationMessagesPkey                            UniqueConstraint = "notification_messages_pkey"                                      // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
 	UniqueNotificationPreferencesPkey                         UniqueConstraint = "notification_preferences_pkey"                                   // ALTER TABLE ONLY notification_preferences ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, notification_template_id);
 	UniqueNotificationReportGeneratorLogsPkey                 UniqueConstraint = "notification_report_generator_logs_pkey"                         // ALTER TABLE ONLY notification_report_generator_logs ADD CONSTRAINT notification_report_generator_logs_pkey PRIMARY KEY (notification_template_id);
+	UniqueNotificationScopedPreferencesPkey                   UniqueConstraint = "notification_scoped_preferences_pkey"                            // ALTER TABLE ONLY notification_scoped_preferences ADD CONSTRAINT notification_scoped_preferences_pkey PRIMARY KEY (user_id, notification_template_id, target_id);
+	UniqueNotificationTemplateWebhooksPkey                    UniqueConstraint = "notification_template_webhooks_pkey"                             // ALTER TABLE ONLY notification_template_webhooks ADD CONSTRAINT notification_template_webhooks_pkey PRIMARY KEY (notification_template_id);
 	UniqueNotificationTemplatesNameKey                        UniqueConstraint = "notification_templates_name_key"                                 // ALTER TABLE ONLY notification_templates ADD CONSTRAINT notification_templates_name_key UNIQUE (name);
 	UniqueNotificationTemplatesPkey                           UniqueConstraint = "notification_templates_pkey"                                     // ALTER TABLE ONLY notification_templates ADD CONSTRAINT notification_templates_pkey PRIMARY KEY (id);
 	UniqueOauth2ProviderAppCodesPkey                          UniqueConstraint = "oauth2_provider_app_codes_pkey"                                  // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
@@ -75,10 +78,15 @@ const (
 	UniqueParameterSchemasPkey                                UniqueConstraint = "parameter_schemas_pkey"                                          // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_pkey PRIMARY KEY (id);
 	UniqueParameterValuesPkey                                 UniqueConstraint = "parameter_values_pkey"                                           // ALTER TABLE ONLY parameter_values ADD CONSTRAINT parameter_values_pkey PRIMARY KEY (id);
 	UniqueParameterValuesScopeIDNameKey                       UniqueConstraint = "parameter_values_scope_id_name_key"                              // ALTER TABLE ONLY parameter_values ADD CONSTRAINT parameter_values_scope_id_name_key UNIQUE (scope_id, name);
+	UniquePrebuildClaimAttemptsPkey                           UniqueConstraint = "prebuild_claim_attempts_pkey"                                    // ALTER TABLE ONLY prebuild_claim_attempts ADD CONSTRAINT prebuild_claim_attempts_pkey PRIMARY KEY (id);
 	UniqueProvisionerDaemonsPkey                              UniqueConstraint = "provisioner_daemons_pkey"                                        // ALTER TABLE ONLY provisioner_daemons ADD CONSTRAINT provisioner_daemons_pkey PRIMARY KEY (id);
+	UniqueProvisionerJobLogArchivesPkey                       UniqueConstraint = "provisioner_job_log_archives_pkey"                               // ALTER TABLE ONLY provisioner_job_log_archives ADD CONSTRAINT provisioner_job_log_archives_pkey PRIMARY KEY (job_id);
 	UniqueProvisionerJobLogsPkey                              UniqueConstraint = "provisioner_job_logs_pkey"                                       // ALTER TABLE ONLY provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
 	UniqueProvisionerJobsPkey                                 UniqueConstraint = "provisioner_jobs_pkey"                                           // ALTER TABLE ONLY provisioner_jobs ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 	UniqueProvisionerKeysPkey                                 UniqueConstraint = "provisioner_keys_pkey"                                           // ALTER TABLE ONLY provisioner_keys ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
+	UniqueSharedVolumeAttachmentsPkey                         UniqueConstraint = "shared_volume_attachments_pkey"                                  // ALTER TABLE ONLY shared_volume_attachments ADD CONSTRAINT shared_volume_attachments_pkey PRIMARY KEY (id);
+	UniqueSharedVolumesOrganizationIDNameKey                  UniqueConstraint = "shared_volumes_organization_id_name_key"                         // ALTER TABLE ONLY shared_volumes ADD CONSTRAINT shared_volumes_organization_id_name_key UNIQUE (organization_id, name);
+	UniqueSharedVolumesPkey                                   UniqueConstraint = "shared_volumes_pkey"                                             // ALTER TABLE ONLY shared_volumes ADD CONSTRAINT shared_volumes_pkey PRIMARY KEY (id);
 	UniqueSiteConfigsKeyKey                                   UniqueConstraint = "site_configs_key_key"                                            // ALTER TABLE ONLY site_configs ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 	UniqueTailnetCoordinatorsPkey                             UniqueConstraint = "tailnet_coordinators_pkey"                                       // ALTER TABLE ONLY tailnet_coordinators ADD CONSTRAINT tailnet_coordinators_pkey PRIMARY KEY (id);
 	UniqueTailnetPeersPkey                                    UniqueConstraint = "tailnet_peers_pkey"                                              // ALTER TABLE ONLY tailnet_peers ADD CONSTRAINT tailnet_peers_pkey PRIMARY KEY (id, coordinator_id);
@@ -88,17 +96,27 @@ const (
 	UniqueTasksPkey                                           UniqueConstraint = "tasks_pkey"                                                      // ALTER TABLE ONLY tasks ADD CONSTRAINT tasks_pkey PRIMARY KEY (id);
 	UniqueTelemetryItemsPkey                                  UniqueConstraint = "telemetry_items_pkey"                                            // ALTER TABLE ONLY telemetry_items ADD CONSTRAINT telemetry_items_pkey PRIMARY KEY (key);
 	UniqueTelemetryLocksPkey                                  UniqueConstraint = "telemetry_locks_pkey"                                            // ALTER TABLE ONLY telemetry_locks ADD CONSTRAINT telemetry_locks_pkey PRIMARY KEY (event_type, period_ending_at);
+	UniqueTemplateHealthReportsPkey                           UniqueConstraint = "template_health_reports_pkey"                                    // ALTER TABLE ONLY template_health_reports ADD CONSTRAINT template_health_reports_pkey PRIMARY KEY (id);
+	UniqueTemplateNetworkPoliciesPkey                         UniqueConstraint = "template_network_policies_pkey"                                  // ALTER TABLE ONLY template_network_policies ADD CONSTRAINT template_network_policies_pkey PRIMARY KEY (template_id);
+	UniqueTemplatePresetCohortsPkey                           UniqueConstraint = "template_preset_cohorts_pkey"                                    // ALTER TABLE ONLY template_preset_cohorts ADD CONSTRAINT template_preset_cohorts_pkey PRIMARY KEY (template_id, preset_name);
+	UniqueTemplateSecretsPkey                                 UniqueConstraint = "template_secrets_pkey"                                           // ALTER TABLE ONLY template_secrets ADD CONSTRAINT template_secrets_pkey PRIMARY KEY (id);
 	UniqueTemplateUsageStatsPkey                              UniqueConstraint = "template_usage_stats_pkey"                                       // ALTER TABLE ONLY template_usage_stats ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
+	UniqueTemplateUserPresetsPkey                             UniqueConstraint = "template_user_presets_pkey"                                      // ALTER TABLE ONLY template_user_presets ADD CONSTRAINT template_user_presets_pkey PRIMARY KEY (id);
+	UniqueTemplateUserPresetsTemplateIDUserIDNameKey          UniqueConstraint = "template_user_presets_template_id_user_id_name_key"              // ALTER TABLE ONLY template_user_presets ADD CONSTRAINT template_user_presets_template_id_user_id_name_key UNIQUE (template_id, user_id, name);
 	UniqueTemplateVersionParametersTemplateVersionIDNameKey   UniqueConstraint = "template_version_parameters_template_version_id_name_key"        // ALTER TABLE ONLY template_version_parameters ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 	UniqueTemplateVersionPresetParametersPkey                 UniqueConstraint = "template_version_preset_parameters_pkey"                         // ALTER TABLE ONLY template_version_preset_parameters ADD CONSTRAINT template_version_preset_parameters_pkey PRIMARY KEY (id);
 	UniqueTemplateVersionPresetPrebuildSchedulesPkey          UniqueConstraint = "template_version_preset_prebuild_schedules_pkey"                 // ALTER TABLE ONLY template_version_preset_prebuild_schedules ADD CONSTRAINT template_version_preset_prebuild_schedules_pkey PRIMARY KEY (id);
 	UniqueTemplateVersionPresetsIDTemplateVersionIDKey        UniqueConstraint = "template_version_presets_id_template_version_id_key"             // ALTER TABLE ONLY template_version_presets ADD CONSTRAINT template_version_presets_id_template_version_id_key UNIQUE (id, template_version_id);
 	UniqueTemplateVersionPresetsPkey                          UniqueConstraint = "template_version_presets_pkey"                                   // ALTER TABLE ONLY template_version_presets ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (id);
+	UniqueTemplateVersionRolloutsPkey                         UniqueConstraint = "template_version_rollouts_pkey"                                  // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_pkey PRIMARY KEY (id);
+	UniqueTemplateVersionScansPkey                            UniqueConstraint = "template_version_scans_pkey"                                     // ALTER TABLE ONLY template_version_scans ADD CONSTRAINT template_version_scans_pkey PRIMARY KEY (template_version_id);
 	UniqueTemplateVersionTerraformValuesTemplateVersionIDKey  UniqueConstraint = "template_version_terraform_values_template_version_id_key"       // ALTER TABLE ONLY template_version_terraform_values ADD CONSTRAINT template_version_terraform_values_template_version_id_key UNIQUE (template_version_id);
 	UniqueTemplateVersionVariablesTemplateVersionIDNameKey    UniqueConstraint = "template_version_variables_template_version_id_name_key"         // ALTER TABLE ONLY template_version_variables ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
 	UniqueTemplateVersionWorkspaceTagsTemplateVersionIDKeyKey UniqueConstraint = "template_version_workspace_tags_template_version_id_key_key"     // ALTER TABLE ONLY template_version_workspace_tags ADD CONSTRAINT template_version_workspace_tags_template_version_id_key_key UNIQUE (template_version_id, key);
 	UniqueTemplateVersionsPkey                                UniqueConstraint = "template_versions_pkey"                                          // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_pkey PRIMARY KEY (id);
 	UniqueTemplateVersionsTemplateIDNameKey                   UniqueConstraint = "template_versions_template_id_name_key"                          // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_template_id_name_key UNIQUE (template_id, name);
+	UniqueTemplateWorkspaceRestartBuildsPkey                  UniqueConstraint = "template_workspace_restart_builds_pkey"                          // ALTER TABLE ONLY template_workspace_restart_builds ADD CONSTRAINT template_workspace_restart_builds_pkey PRIMARY KEY (restart_id, workspace_id);
+	UniqueTemplateWorkspaceRestartsPkey                       UniqueConstraint = "template_workspace_restarts_pkey"                                // ALTER TABLE ONLY template_workspace_restarts ADD CONSTRAINT template_workspace_restarts_pkey PRIMARY KEY (id);
 	UniqueTemplatesPkey                                       UniqueConstraint = "templates_pkey"                                                  // ALTER TABLE ONLY templates ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
 	UniqueUsageEventsDailyPkey                                UniqueConstraint = "usage_events_daily_pkey"                                         // ALTER TABLE ONLY usage_events_daily ADD CONSTRAINT usage_events_daily_pkey PRIMARY KEY (day, event_type);
 	UniqueUsageEventsPkey                                     UniqueConstraint = "usage_events_pkey"                                               // ALTER TABLE ONLY usage_events ADD CONSTRAINT usage_events_pkey PRIMARY KEY (id);
@@ -107,41 +125,68 @@ const (
 	UniqueUserAIProviderKeysUserIDAIProviderIDKey             UniqueConstraint = "user_ai_provider_keys_user_id_ai_provider_id_key"                // ALTER TABLE ONLY user_ai_provider_keys ADD CONSTRAINT user_ai_provider_keys_user_id_ai_provider_id_key UNIQUE (user_id, ai_provider_id);
 	UniqueUserConfigsPkey                                     UniqueConstraint = "user_configs_pkey"                                               // ALTER TABLE ONLY user_configs ADD CONSTRAINT user_configs_pkey PRIMARY KEY (user_id, key);
 	UniqueUserDeletedPkey                                     UniqueConstraint = "user_deleted_pkey"                                               // ALTER TABLE ONLY user_deleted ADD CONSTRAINT user_deleted_pkey PRIMARY KEY (id);
+	UniqueUserImpersonationsPkey                              UniqueConstraint = "user_impersonations_pkey"                                        // ALTER TABLE ONLY user_impersonations ADD CONSTRAINT user_impersonations_pkey PRIMARY KEY (id);
 	UniqueUserLinksPkey                                       UniqueConstraint = "user_links_pkey"                                                 // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
+	UniqueUserSchedulePausesPkey                              UniqueConstraint = "user_schedule_pauses_pkey"                                       // ALTER TABLE ONLY user_schedule_pauses ADD CONSTRAINT user_schedule_pauses_pkey PRIMARY KEY (user_id);
 	UniqueUserSecretsPkey                                     UniqueConstraint = "user_secrets_pkey"                                               // ALTER TABLE ONLY user_secrets ADD CONSTRAINT user_secrets_pkey PRIMARY KEY (id);
 	UniqueUserSkillsPkey                                      UniqueConstraint = "user_skills_pkey"                                                // ALTER TABLE ONLY user_skills ADD CONSTRAINT user_skills_pkey PRIMARY KEY (id);
 	UniqueUserStatusChangesPkey                               UniqueConstraint = "user_status_changes_pkey"                                        // ALTER TABLE ONLY user_status_changes ADD CONSTRAINT user_status_changes_pkey PRIMARY KEY (id);
+	UniqueUserWorkspaceReportsPkey                            UniqueConstraint = "user_workspace_reports_pkey"                                     // ALTER TABLE ONLY user_workspace_reports ADD CONSTRAINT user_workspace_reports_pkey PRIMARY KEY (id);
 	UniqueUsersPkey                                           UniqueConstraint = "users_pkey"                                                      // ALTER TABLE ONLY users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+	UniqueVcsEventMappingsPkey                                UniqueConstraint = "vcs_event_mappings_pkey"                                         // ALTER TABLE ONLY vcs_event_mappings ADD CONSTRAINT vcs_event_mappings_pkey PRIMARY KEY (id);
 	UniqueWebpushSubscriptionsPkey                            UniqueConstraint = "webpush_subscriptions_pkey"                                      // ALTER TABLE ONLY webpush_subscriptions ADD CONSTRAINT webpush_subscriptions_pkey PRIMARY KEY (id);
+	UniqueWorkspaceActivityBumpsPkey                          UniqueConstraint = "workspace_activity_bumps_pkey"                                   // ALTER TABLE ONLY workspace_activity_bumps ADD CONSTRAINT workspace_activity_bumps_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceAgentBootstrapProgressPkey                 UniqueConstraint = "workspace_agent_bootstrap_progress_pkey"                         // ALTER TABLE ONLY workspace_agent_bootstrap_progress ADD CONSTRAINT workspace_agent_bootstrap_progress_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAgentContextResourcesPkey                  UniqueConstraint = "workspace_agent_context_resources_pkey"                          // ALTER TABLE ONLY workspace_agent_context_resources ADD CONSTRAINT workspace_agent_context_resources_pkey PRIMARY KEY (workspace_agent_id, source);
 	UniqueWorkspaceAgentContextSnapshotsPkey                  UniqueConstraint = "workspace_agent_context_snapshots_pkey"                          // ALTER TABLE ONLY workspace_agent_context_snapshots ADD CONSTRAINT workspace_agent_context_snapshots_pkey PRIMARY KEY (workspace_agent_id);
+	UniqueWorkspaceAgentCrashLoopsPkey                        UniqueConstraint = "workspace_agent_crash_loops_pkey"                                // ALTER TABLE ONLY workspace_agent_crash_loops ADD CONSTRAINT workspace_agent_crash_loops_pkey PRIMARY KEY (agent_id);
 	UniqueWorkspaceAgentDevcontainersPkey                     UniqueConstraint = "workspace_agent_devcontainers_pkey"                              // ALTER TABLE ONLY workspace_agent_devcontainers ADD CONSTRAINT workspace_agent_devcontainers_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAgentLogSourcesPkey                        UniqueConstraint = "workspace_agent_log_sources_pkey"                                // ALTER TABLE ONLY workspace_agent_log_sources ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
+	UniqueWorkspaceAgentMemoryEventsPkey                      UniqueConstraint = "workspace_agent_memory_events_pkey"                              // ALTER TABLE ONLY workspace_agent_memory_events ADD CONSTRAINT workspace_agent_memory_events_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAgentMemoryResourceMonitorsPkey            UniqueConstraint = "workspace_agent_memory_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_memory_resource_monitors ADD CONSTRAINT workspace_agent_memory_resource_monitors_pkey PRIMARY KEY (agent_id);
 	UniqueWorkspaceAgentMetadataPkey                          UniqueConstraint = "workspace_agent_metadata_pkey"                                   // ALTER TABLE ONLY workspace_agent_metadata ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
+	UniqueWorkspaceAgentMetadataHistoryPkey                   UniqueConstraint = "workspace_agent_metadata_history_pkey"                           // ALTER TABLE ONLY workspace_agent_metadata_history ADD CONSTRAINT workspace_agent_metadata_history_pkey PRIMARY KEY (workspace_agent_id, key, collected_at);
+	UniqueWorkspaceAgentNetworkPolicyViolationsPkey           UniqueConstraint = "workspace_agent_network_policy_violations_pkey"                  // ALTER TABLE ONLY workspace_agent_network_policy_violations ADD CONSTRAINT workspace_agent_network_policy_violations_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAgentPortSharePkey                         UniqueConstraint = "workspace_agent_port_share_pkey"                                 // ALTER TABLE ONLY workspace_agent_port_share ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
 	UniqueWorkspaceAgentScriptTimingsScriptIDStartedAtKey     UniqueConstraint = "workspace_agent_script_timings_script_id_started_at_key"         // ALTER TABLE ONLY workspace_agent_script_timings ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
 	UniqueWorkspaceAgentScriptsIDKey                          UniqueConstraint = "workspace_agent_scripts_id_key"                                  // ALTER TABLE ONLY workspace_agent_scripts ADD CONSTRAINT workspace_agent_scripts_id_key UNIQUE (id);
+	UniqueWorkspaceAgentServiceTransitionsPkey                UniqueConstraint = "workspace_agent_service_transitions_pkey"                        // ALTER TABLE ONLY workspace_agent_service_transitions ADD CONSTRAINT workspace_agent_service_transitions_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAgentServicesPkey                          UniqueConstraint = "workspace_agent_services_pkey"                                   // ALTER TABLE ONLY workspace_agent_services ADD CONSTRAINT workspace_agent_services_pkey PRIMARY KEY (workspace_agent_id, name);
 	UniqueWorkspaceAgentStartupLogsPkey                       UniqueConstraint = "workspace_agent_startup_logs_pkey"                               // ALTER TABLE ONLY workspace_agent_logs ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAgentVolumeResourceMonitorsPkey            UniqueConstraint = "workspace_agent_volume_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_volume_resource_monitors ADD CONSTRAINT workspace_agent_volume_resource_monitors_pkey PRIMARY KEY (agent_id, path);
 	UniqueWorkspaceAgentsPkey                                 UniqueConstraint = "workspace_agents_pkey"                                           // ALTER TABLE ONLY workspace_agents ADD CONSTRAINT workspace_agents_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAppAuditSessionsAgentIDAppIDUserIDIpUseKey UniqueConstraint = "workspace_app_audit_sessions_agent_id_app_id_user_id_ip_use_key" // ALTER TABLE ONLY workspace_app_audit_sessions ADD CONSTRAINT workspace_app_audit_sessions_agent_id_app_id_user_id_ip_use_key UNIQUE (agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code);
 	UniqueWorkspaceAppAuditSessionsPkey                       UniqueConstraint = "workspace_app_audit_sessions_pkey"                               // ALTER TABLE ONLY workspace_app_audit_sessions ADD CONSTRAINT workspace_app_audit_sessions_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAppShareLinksPkey                          UniqueConstraint = "workspace_app_share_links_pkey"                                  // ALTER TABLE ONLY workspace_app_share_links ADD CONSTRAINT workspace_app_share_links_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAppStatsPkey                               UniqueConstraint = "workspace_app_stats_pkey"                                        // ALTER TABLE ONLY workspace_app_stats ADD CONSTRAINT workspace_app_stats_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAppStatsUserIDAgentIDSessionIDKey          UniqueConstraint = "workspace_app_stats_user_id_agent_id_session_id_key"             // ALTER TABLE ONLY workspace_app_stats ADD CONSTRAINT workspace_app_stats_user_id_agent_id_session_id_key UNIQUE (user_id, agent_id, session_id);
 	UniqueWorkspaceAppStatusesPkey                            UniqueConstraint = "workspace_app_statuses_pkey"                                     // ALTER TABLE ONLY workspace_app_statuses ADD CONSTRAINT workspace_app_statuses_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAppsAgentIDSlugIndex                       UniqueConstraint = "workspace_apps_agent_id_slug_idx"                                // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_agent_id_slug_idx UNIQUE (agent_id, slug);
 	UniqueWorkspaceAppsPkey                                   UniqueConstraint = "workspace_apps_pkey"                                             // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_pkey PRIMARY KEY (id);
+	UniqueWorkspaceBuildAnnotationsPkey                       UniqueConstraint = "workspace_build_annotations_pkey"                                // ALTER TABLE ONLY workspace_build_annotations ADD CONSTRAINT workspace_build_annotations_pkey PRIMARY KEY (id);
+	UniqueWorkspaceBuildInputsPkey                            UniqueConstraint = "workspace_build_inputs_pkey"                                     // ALTER TABLE ONLY workspace_build_inputs ADD CONSTRAINT workspace_build_inputs_pkey PRIMARY KEY (workspace_build_id);
 	UniqueWorkspaceBuildOrchestrationsChildBuildIDKey         UniqueConstraint = "workspace_build_orchestrations_child_build_id_key"               // ALTER TABLE ONLY workspace_build_orchestrations ADD CONSTRAINT workspace_build_orchestrations_child_build_id_key UNIQUE (child_build_id);
 	UniqueWorkspaceBuildOrchestrationsParentBuildIDKey        UniqueConstraint = "workspace_build_orchestrations_parent_build_id_key"              // ALTER TABLE ONLY workspace_build_orchestrations ADD CONSTRAINT workspace_build_orchestrations_parent_build_id_key UNIQUE (parent_build_id);
 	UniqueWorkspaceBuildOrchestrationsPkey                    UniqueConstraint = "workspace_build_orchestrations_pkey"                             // ALTER TABLE ONLY workspace_build_orchestrations ADD CONSTRAINT workspace_build_orchestrations_pkey PRIMARY KEY (id);
+	UniqueWorkspaceBuildParameterChangesPkey                  UniqueConstraint = "workspace_build_parameter_changes_pkey"                          // ALTER TABLE ONLY workspace_build_parameter_changes ADD CONSTRAINT workspace_build_parameter_changes_pkey PRIMARY KEY (workspace_build_id, name);
 	UniqueWorkspaceBuildParametersWorkspaceBuildIDNameKey     UniqueConstraint = "workspace_build_parameters_workspace_build_id_name_key"          // ALTER TABLE ONLY workspace_build_parameters ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
 	UniqueWorkspaceBuildsIDWorkspaceIDKey                     UniqueConstraint = "workspace_builds_id_workspace_id_key"                            // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_id_workspace_id_key UNIQUE (id, workspace_id);
 	UniqueWorkspaceBuildsJobIDKey                             UniqueConstraint = "workspace_builds_job_id_key"                                     // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_job_id_key UNIQUE (job_id);
 	UniqueWorkspaceBuildsPkey                                 UniqueConstraint = "workspace_builds_pkey"                                           // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_pkey PRIMARY KEY (id);
 	UniqueWorkspaceBuildsWorkspaceIDBuildNumberKey            UniqueConstraint = "workspace_builds_workspace_id_build_number_key"                  // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
+	UniqueWorkspaceCloudResourcesPkey                         UniqueConstraint = "workspace_cloud_resources_pkey"                                  // ALTER TABLE ONLY workspace_cloud_resources ADD CONSTRAINT workspace_cloud_resources_pkey PRIMARY KEY (workspace_id, resource_type, cloud_id);
+	UniqueWorkspaceDnsRecordsPkey                             UniqueConstraint = "workspace_dns_records_pkey"                                      // ALTER TABLE ONLY workspace_dns_records ADD CONSTRAINT workspace_dns_records_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceDormancyExemptionsPkey                     UniqueConstraint = "workspace_dormancy_exemptions_pkey"                              // ALTER TABLE ONLY workspace_dormancy_exemptions ADD CONSTRAINT workspace_dormancy_exemptions_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceDormancyHooksPkey                          UniqueConstraint = "workspace_dormancy_hooks_pkey"                                   // ALTER TABLE ONLY workspace_dormancy_hooks ADD CONSTRAINT workspace_dormancy_hooks_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceEgressDailyPkey                            UniqueConstraint = "workspace_egress_daily_pkey"                                     // ALTER TABLE ONLY workspace_egress_daily ADD CONSTRAINT workspace_egress_daily_pkey PRIMARY KEY (workspace_id, date);
+	UniqueWorkspaceLeasesPkey                                 UniqueConstraint = "workspace_leases_pkey"                                           // ALTER TABLE ONLY workspace_leases ADD CONSTRAINT workspace_leases_pkey PRIMARY KEY (id);
+	UniqueWorkspaceLifetimeWarningsPkey                       UniqueConstraint = "workspace_lifetime_warnings_pkey"                                // ALTER TABLE ONLY workspace_lifetime_warnings ADD CONSTRAINT workspace_lifetime_warnings_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceMonthlyCostsPkey                           UniqueConstraint = "workspace_monthly_costs_pkey"                                    // ALTER TABLE ONLY workspace_monthly_costs ADD CONSTRAINT workspace_monthly_costs_pkey PRIMARY KEY (workspace_id, month);
+	UniqueWorkspaceNetworkPoliciesPkey                        UniqueConstraint = "workspace_network_policies_pkey"                                 // ALTER TABLE ONLY workspace_network_policies ADD CONSTRAINT workspace_network_policies_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspacePortForwardAclsPkey                        UniqueConstraint = "workspace_port_forward_acls_pkey"                                // ALTER TABLE ONLY workspace_port_forward_acls ADD CONSTRAINT workspace_port_forward_acls_pkey PRIMARY KEY (workspace_id, user_id);
 	UniqueWorkspaceProxiesPkey                                UniqueConstraint = "workspace_proxies_pkey"                                          // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 	UniqueWorkspaceProxiesRegionIDUnique                      UniqueConstraint = "workspace_proxies_region_id_unique"                              // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
+	UniqueWorkspaceRegionAssignmentsPkey                      UniqueConstraint = "workspace_region_assignments_pkey"                               // ALTER TABLE ONLY workspace_region_assignments ADD CONSTRAINT workspace_region_assignments_pkey PRIMARY KEY (workspace_id);
 	UniqueWorkspaceResourceMetadataName                       UniqueConstraint = "workspace_resource_metadata_name"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
 	UniqueWorkspaceResourceMetadataPkey                       UniqueConstraint = "workspace_resource_metadata_pkey"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_pkey PRIMARY KEY (id);
 	UniqueWorkspaceResourcesPkey                              UniqueConstraint = "workspace_resources_pkey"                                        // ALTER TABLE ONLY workspace_resources ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
@@ -165,8 +210,12 @@ const (
 	UniqueNotificationMessagesDedupeHashIndex                 UniqueConstraint = "notification_messages_dedupe_hash_idx"                           // CREATE UNIQUE INDEX notification_messages_dedupe_hash_idx ON notification_messages USING btree (dedupe_hash);
 	UniqueOrganizationsSingleDefaultOrg                       UniqueConstraint = "organizations_single_default_org"                                // CREATE UNIQUE INDEX organizations_single_default_org ON organizations USING btree (is_default) WHERE (is_default = true);
 	UniqueProvisionerKeysOrganizationIDNameIndex              UniqueConstraint = "provisioner_keys_organization_id_name_idx"                       // CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
+	UniqueSharedVolumeAttachmentsReadWriteLockIndex           UniqueConstraint = "shared_volume_attachments_read_write_lock_idx"                   // CREATE UNIQUE INDEX shared_volume_attachments_read_write_lock_idx ON shared_volume_attachments USING btree (shared_volume_id) WHERE ((access_mode = 'read_write'::shared_volume_access_mode) AND (detached_at IS NULL));
 	UniqueTasksOwnerIDNameUniqueIndex                         UniqueConstraint = "tasks_owner_id_name_unique_idx"                                  // CREATE UNIQUE INDEX tasks_owner_id_name_unique_idx ON tasks USING btree (owner_id, lower(name)) WHERE (deleted_at IS NULL);
+	UniqueTemplateSecretsTemplateIDNameIndex                  UniqueConstraint = "template_secrets_template_id_name_idx"                           // CREATE UNIQUE INDEX template_secrets_template_id_name_idx ON template_secrets USING btree (template_id, name);
 	UniqueTemplateUsageStatsStartTimeTemplateIDUserIDIndex    UniqueConstraint = "template_usage_stats_start_time_template_id_user_id_idx"         // CREATE UNIQUE INDEX template_usage_stats_start_time_template_id_user_id_idx ON template_usage_stats USING btree (start_time, template_id, user_id);
+	UniqueTemplateVersionRolloutsInProgressIndex              UniqueConstraint = "template_version_rollouts_in_progress_idx"                       // CREATE UNIQUE INDEX template_version_rollouts_in_progress_idx ON template_version_rollouts USING btree (template_id) WHERE (status = 'in_progress'::template_version_rollout_status);
+	UniqueTemplateWorkspaceRestartsActiveIndex                UniqueConstraint = "template_workspace_restarts_active_idx"                          // CREATE UNIQUE INDEX template_workspace_restarts_active_idx ON template_workspace_restarts USING btree (template_id) WHERE (status = ANY (ARRAY['in_progress'::template_workspace_restart_status, 'paused'::template_workspace_restart_status]));
 	UniqueTemplatesOrganizationIDNameIndex                    UniqueConstraint = "templates_organization_id_name_idx"                              // CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 	UniqueUserLinksLinkedIDLoginTypeIndex                     UniqueConstraint = "user_links_linked_id_login_type_idx"                             // CREATE UNIQUE INDEX user_links_linked_id_login_type_idx ON user_links USING btree (linked_id, login_type) WHERE (linked_id <> ''::text);
 	UniqueUserSecretsUserEnvNameIndex                         UniqueConstraint = "user_secrets_user_env_name_idx"                                  // CREATE UNIQUE INDEX user_secrets_user_env_name_idx ON user_secrets USING btree (user_id, env_name) WHERE (env_name <> ''::text);
@@ -175,6 +224,7 @@ const (
 	UniqueUserSkillsUserIDNameIndex                           UniqueConstraint = "user_skills_user_id_name_idx"                                    // CREATE UNIQUE INDEX user_skills_user_id_name_idx ON user_skills USING btree (user_id, name);
 	UniqueUsersEmailLowerIndex                                UniqueConstraint = "users_email_lower_idx"                                           // CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE ((deleted = false) AND (email <> ''::text));
 	UniqueUsersUsernameLowerIndex                             UniqueConstraint = "users_username_lower_idx"                                        // CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
+	UniqueVcsEventMappingsTemplateIDNameIndex                 UniqueConstraint = "vcs_event_mappings_template_id_name_idx"                         // CREATE UNIQUE INDEX vcs_event_mappings_template_id_name_idx ON vcs_event_mappings USING btree (template_id, name);
 	UniqueWebpushSubscriptionsUserIDEndpointIndex             UniqueConstraint = "webpush_subscriptions_user_id_endpoint_idx"                      // CREATE UNIQUE INDEX webpush_subscriptions_user_id_endpoint_idx ON webpush_subscriptions USING btree (user_id, endpoint);
 	UniqueWorkspaceAppAuditSessionsUniqueIndex                UniqueConstraint = "workspace_app_audit_sessions_unique_index"                       // CREATE UNIQUE INDEX workspace_app_audit_sessions_unique_index ON workspace_app_audit_sessions USING btree (agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code);
 	UniqueWorkspaceProxiesLowerNameIndex                      UniqueConstraint = "workspace_proxies_lower_name_idx"                                // CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
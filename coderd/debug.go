@@ -19,6 +19,7 @@ import (
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
+	"tailscale.com/util/singleflight"
 
 	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/coderd/audit"
@@ -85,19 +86,7 @@ func (api *API) debugDeploymentHealth(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	resChan := api.healthCheckGroup.DoChan("", func() (*healthsdk.HealthcheckReport, error) {
-		// Create a new context not tied to the request.
-		ctx, cancel := context.WithTimeout(context.Background(), api.Options.HealthcheckTimeout)
-		defer cancel()
-
-		// Create and store progress tracker for timeout diagnostics.
-		report := api.HealthcheckFunc(ctx, apiKey, &api.healthCheckProgress)
-		if report != nil { // Only store non-nil reports.
-			api.healthCheckCache.Store(report)
-		}
-		api.healthCheckProgress.Reset()
-		return report, nil
-	})
+	resChan := api.runHealthcheck(apiKey)
 
 	select {
 	case <-ctx.Done():
@@ -121,6 +110,24 @@ func (api *API) debugDeploymentHealth(rw http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// runHealthcheck runs the deployment healthcheck in the background, sharing
+// the result with any concurrent callers, and caches the report.
+func (api *API) runHealthcheck(apiKey string) <-chan singleflight.Result[*healthsdk.HealthcheckReport] {
+	return api.healthCheckGroup.DoChan("", func() (*healthsdk.HealthcheckReport, error) {
+		// Create a new context not tied to the request.
+		ctx, cancel := context.WithTimeout(context.Background(), api.Options.HealthcheckTimeout)
+		defer cancel()
+
+		// Create and store progress tracker for timeout diagnostics.
+		report := api.HealthcheckFunc(ctx, apiKey, &api.healthCheckProgress)
+		if report != nil { // Only store non-nil reports.
+			api.healthCheckCache.Store(report)
+		}
+		api.healthCheckProgress.Reset()
+		return report, nil
+	})
+}
+
 func formatHealthcheck(ctx context.Context, rw http.ResponseWriter, r *http.Request, hc healthsdk.HealthcheckReport, dismissed ...healthsdk.HealthSection) {
 	// Mark any sections previously marked as dismissed.
 	for _, d := range dismissed {
@@ -321,6 +328,32 @@ func _debugDERPTraffic(http.ResponseWriter, *http.Request) {} //nolint:unused
 // @x-apidocgen {"skip": true}
 func _debugExpVar(http.ResponseWriter, *http.Request) {} //nolint:unused
 
+// @Summary Get lifecycle executor status
+// @Description The lifecycle executor autostarts, autostops, and marks
+// @Description workspaces dormant. Every replica runs its own executor, so
+// @Description the status is that of the replica serving the request.
+// @ID get-lifecycle-executor-status
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Success 200 {object} codersdk.LifecycleExecutorStatus
+// @Router /api/v2/debug/lifecycle-executor [get]
+func (api *API) debugLifecycleExecutor(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	executor := api.LifecycleExecutor.Load()
+	if executor == nil {
+		httpapi.Write(ctx, rw, http.StatusServiceUnavailable, codersdk.Response{
+			Message: "The lifecycle executor is not running on this replica.",
+		})
+		return
+	}
+
+	status := executor.Status()
+	status.ReplicaID = api.ID
+	httpapi.Write(ctx, rw, http.StatusOK, status)
+}
+
 func loadDismissedHealthchecks(ctx context.Context, db database.Store, logger slog.Logger) []healthsdk.HealthSection {
 	dismissedHealthchecks := []healthsdk.HealthSection{}
 	settingsJSON, err := db.GetHealthSettings(ctx)
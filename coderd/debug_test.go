@@ -17,7 +17,9 @@ import (
 
 	"cdr.dev/slog/v3/sloggers/slogtest"
 	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
@@ -625,3 +627,112 @@ func extractTarGzFiles(t *testing.T, data []byte) map[string]bool {
 	}
 	return files
 }
+
+func TestDebugDeploymentBundle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.OIDC.ClientSecret = "super-secret-oidc-client-secret"
+		auditor := audit.NewMock()
+		client, closer, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+			DeploymentValues: dv,
+			Auditor:          auditor,
+			HealthcheckFunc: func(context.Context, string, *healthcheck.Progress) *healthsdk.HealthcheckReport {
+				return &healthsdk.HealthcheckReport{
+					Time:    time.Now(),
+					Healthy: true,
+				}
+			},
+			HealthcheckRefresh: time.Hour,
+		})
+		defer closer.Close()
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		asserter := coderdtest.AssertRBAC(t, api, client)
+
+		body, err := client.DeploymentBundle(ctx)
+		require.NoError(t, err)
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+
+		files := extractTarGzContents(t, data)
+		require.Contains(t, files, "manifest.json")
+		require.Contains(t, files, "deployment/config.json")
+		require.Contains(t, files, "deployment/health.json")
+		require.Contains(t, files, "provisioners/daemons.json")
+		require.Contains(t, files, "provisioners/jobs.json")
+		require.Contains(t, files, "metrics/api_errors.json")
+
+		var manifest struct {
+			Files  []string          `json:"files"`
+			Errors map[string]string `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+		require.Contains(t, manifest.Files, "deployment/config.json")
+		// Query metrics are not collected by the test server, which must
+		// not prevent the rest of the bundle from being produced.
+		require.Contains(t, manifest.Errors, "metrics/slow_queries.json")
+
+		var config codersdk.DeploymentConfig
+		require.NoError(t, json.Unmarshal(files["deployment/config.json"], &config))
+		require.Empty(t, config.Values.OIDC.ClientSecret.Value())
+		require.NotContains(t, string(data), "super-secret-oidc-client-secret")
+
+		var health healthsdk.HealthcheckReport
+		require.NoError(t, json.Unmarshal(files["deployment/health.json"], &health))
+		require.True(t, health.Healthy)
+
+		asserter.AssertChecked(t, policy.ActionRead, rbac.ResourceDebugInfo)
+
+		logs := auditor.AuditLogs()
+		require.NotEmpty(t, logs)
+		last := logs[len(logs)-1]
+		require.Equal(t, database.ResourceTypeDeploymentBundle, last.ResourceType)
+		require.Equal(t, database.AuditActionDownload, last.Action)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		client := coderdtest.New(t, nil)
+		firstUser := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+
+		_, err := memberClient.DeploymentBundle(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+// extractTarGzContents extracts the contents of every file of a tar.gz
+// archive.
+func extractTarGzContents(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = content
+	}
+	return files
+}
@@ -0,0 +1,412 @@
+package coderd
+
+import (
+	"archive/tar"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	dto "github.com/prometheus/client_model/go"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// deploymentBundleJobsWindow is how far back provisioner jobs are
+	// summarized in a deployment bundle.
+	deploymentBundleJobsWindow = 24 * time.Hour
+	// deploymentBundleSlowQueries is the number of queries with the highest
+	// mean latency included in a deployment bundle.
+	deploymentBundleSlowQueries = 25
+
+	apiRequestsProcessedMetric = "coderd_api_requests_processed_total"
+	dbQueryLatenciesMetric     = "coderd_db_query_latencies_seconds"
+)
+
+// deploymentBundleManifest describes the contents of a deployment bundle.
+type deploymentBundleManifest struct {
+	CreatedAt    time.Time `json:"created_at"`
+	Version      string    `json:"version"`
+	DeploymentID string    `json:"deployment_id"`
+	Files        []string  `json:"files"`
+	// Errors maps the files that could not be collected to the reason.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// deploymentBundleRouteErrors summarizes the responses of a single API route
+// since the server started.
+type deploymentBundleRouteErrors struct {
+	Method          string  `json:"method"`
+	Path            string  `json:"path"`
+	Requests        uint64  `json:"requests"`
+	ClientErrors    uint64  `json:"client_errors"`
+	ServerErrors    uint64  `json:"server_errors"`
+	ServerErrorRate float64 `json:"server_error_rate"`
+}
+
+// deploymentBundleQueryStats summarizes the latency of a single database
+// query since the server started.
+type deploymentBundleQueryStats struct {
+	Query        string  `json:"query"`
+	Count        uint64  `json:"count"`
+	TotalSeconds float64 `json:"total_seconds"`
+	MeanSeconds  float64 `json:"mean_seconds"`
+}
+
+// deploymentBundleJobs summarizes the provisioner jobs created since a point
+// in time.
+type deploymentBundleJobs struct {
+	Since             time.Time                             `json:"since"`
+	Total             int                                   `json:"total"`
+	ByStatus          map[codersdk.ProvisionerJobStatus]int `json:"by_status"`
+	FailedByErrorCode map[string]int                        `json:"failed_by_error_code"`
+	FailureRate       float64                               `json:"failure_rate"`
+}
+
+// deploymentBundleFile is a single file of a deployment bundle. Collect
+// returns the value that is written to the file as JSON.
+type deploymentBundleFile struct {
+	Name    string
+	Collect func(ctx context.Context) (any, error)
+}
+
+// @Summary Get deployment diagnostics bundle
+// @ID get-deployment-diagnostics-bundle
+// @Security CoderSessionToken
+// @Produce application/gzip
+// @Tags Debug
+// @Success 200
+// @Router /api/v2/debug/deployment-bundle [get]
+func (api *API) debugDeploymentBundle(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		now      = api.Clock.Now()
+		filename = fmt.Sprintf("coderd-bundle-%d.tar.gz", now.Unix())
+		apiKey   = httpmw.APITokenFromRequest(r)
+	)
+	aReq, commitAudit := audit.InitRequest[database.DeploymentBundle](rw, &audit.RequestParams{
+		Audit:   *api.Auditor.Load(),
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDownload,
+	})
+	defer commitAudit()
+	aReq.New = database.DeploymentBundle{
+		ID:       uuid.New(),
+		Filename: filename,
+	}
+
+	files := []deploymentBundleFile{
+		{Name: "deployment/config.json", Collect: api.deploymentBundleConfig},
+		{Name: "deployment/health.json", Collect: func(ctx context.Context) (any, error) {
+			return api.deploymentBundleHealth(ctx, apiKey)
+		}},
+		{Name: "provisioners/daemons.json", Collect: api.deploymentBundleProvisionerDaemons},
+		{Name: "provisioners/jobs.json", Collect: func(ctx context.Context) (any, error) {
+			return api.deploymentBundleProvisionerJobs(ctx, now.Add(-deploymentBundleJobsWindow))
+		}},
+		{Name: "metrics/api_errors.json", Collect: api.deploymentBundleAPIErrors},
+		{Name: "metrics/slow_queries.json", Collect: api.deploymentBundleSlowQueries},
+	}
+
+	manifest := deploymentBundleManifest{
+		CreatedAt:    now,
+		Version:      buildinfo.Version(),
+		DeploymentID: api.DeploymentID,
+		Files:        []string{},
+		Errors:       map[string]string{},
+	}
+	contents := map[string][]byte{}
+	// A section that fails to collect must not prevent the rest of the
+	// bundle from being downloaded, so errors are recorded in the manifest.
+	for _, file := range files {
+		value, err := file.Collect(ctx)
+		if err == nil {
+			contents[file.Name], err = json.MarshalIndent(value, "", "  ")
+		}
+		if err != nil {
+			api.Logger.Warn(ctx, "collect deployment bundle file",
+				slog.F("file", file.Name),
+				slog.Error(err),
+			)
+			manifest.Errors[file.Name] = err.Error()
+			continue
+		}
+		manifest.Files = append(manifest.Files, file.Name)
+	}
+	aReq.New.Files = manifest.Files
+
+	archive, err := writeDeploymentBundle(manifest, contents)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to write deployment bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/gzip")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(archive)
+}
+
+// writeDeploymentBundle builds the tar.gz archive of a deployment bundle.
+// The manifest is always the first file.
+func writeDeploymentBundle(manifest deploymentBundleManifest, contents map[string][]byte) ([]byte, error) {
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, xerrors.Errorf("marshal manifest: %w", err)
+	}
+
+	var archive bytes.Buffer
+	gzw := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gzw)
+
+	addFile := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: manifest.CreatedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return xerrors.Errorf("write tar header for %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return xerrors.Errorf("write tar data for %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if err := addFile("manifest.json", manifestData); err != nil {
+		return nil, err
+	}
+	for _, name := range manifest.Files {
+		if err := addFile(name, contents[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, xerrors.Errorf("close tar writer: %w", err)
+	}
+	if err := gzw.Close(); err != nil {
+		return nil, xerrors.Errorf("close gzip writer: %w", err)
+	}
+	return archive.Bytes(), nil
+}
+
+// deploymentBundleConfig returns the deployment config with secret values
+// removed.
+func (api *API) deploymentBundleConfig(_ context.Context) (any, error) {
+	values, err := api.DeploymentValues.WithoutSecrets()
+	if err != nil {
+		return nil, xerrors.Errorf("redact deployment values: %w", err)
+	}
+	return codersdk.DeploymentConfig{
+		Values:  values,
+		Options: api.DeploymentOptions,
+	}, nil
+}
+
+// deploymentBundleHealth returns the cached healthcheck report, or runs a
+// new healthcheck if the cached one is stale.
+func (api *API) deploymentBundleHealth(ctx context.Context, apiKey string) (any, error) {
+	if report := api.healthCheckCache.Load(); report != nil {
+		if time.Since(report.Time) < api.Options.HealthcheckRefresh {
+			return report, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, api.Options.HealthcheckTimeout)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+		return nil, xerrors.Errorf("healthcheck timed out: %s", api.healthCheckProgress.Summary())
+	case res := <-api.runHealthcheck(apiKey):
+		if res.Val == nil {
+			return nil, xerrors.New("nil report from healthcheck result channel")
+		}
+		return res.Val, nil
+	}
+}
+
+// deploymentBundleProvisionerDaemons returns the provisioner daemons of every
+// organization along with their status and current job.
+func (api *API) deploymentBundleProvisionerDaemons(ctx context.Context) (any, error) {
+	orgs, err := api.Database.GetOrganizations(ctx, database.GetOrganizationsParams{})
+	if err != nil {
+		return nil, xerrors.Errorf("get organizations: %w", err)
+	}
+
+	daemons := []codersdk.ProvisionerDaemon{}
+	for _, org := range orgs {
+		rows, err := api.Database.GetProvisionerDaemonsWithStatusByOrganization(ctx, database.GetProvisionerDaemonsWithStatusByOrganizationParams{
+			OrganizationID:  org.ID,
+			StaleIntervalMS: provisionerdserver.StaleInterval.Milliseconds(),
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get provisioner daemons of organization %s: %w", org.Name, err)
+		}
+		for _, row := range rows {
+			daemons = append(daemons, convertProvisionerDaemonWithStatus(row))
+		}
+	}
+	return daemons, nil
+}
+
+// deploymentBundleProvisionerJobs summarizes the provisioner jobs created
+// after since. Job inputs are never included as they may contain secrets.
+func (api *API) deploymentBundleProvisionerJobs(ctx context.Context, since time.Time) (any, error) {
+	jobs, err := api.Database.GetProvisionerJobsCreatedAfter(ctx, since)
+	if err != nil {
+		return nil, xerrors.Errorf("get provisioner jobs: %w", err)
+	}
+
+	summary := deploymentBundleJobs{
+		Since:             since,
+		Total:             len(jobs),
+		ByStatus:          map[codersdk.ProvisionerJobStatus]int{},
+		FailedByErrorCode: map[string]int{},
+	}
+	for _, job := range jobs {
+		summary.ByStatus[codersdk.ProvisionerJobStatus(job.JobStatus)]++
+		if job.JobStatus != database.ProvisionerJobStatusFailed {
+			continue
+		}
+		code := job.ErrorCode.String
+		if code == "" {
+			code = "unknown"
+		}
+		summary.FailedByErrorCode[code]++
+	}
+	if summary.Total > 0 {
+		summary.FailureRate = float64(summary.ByStatus[codersdk.ProvisionerJobFailed]) / float64(summary.Total)
+	}
+	return summary, nil
+}
+
+// deploymentBundleAPIErrors summarizes the API responses per route since the
+// server started, ordered by the number of server errors.
+func (api *API) deploymentBundleAPIErrors(_ context.Context) (any, error) {
+	family, err := api.gatherMetricFamily(apiRequestsProcessedMetric)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := map[string]*deploymentBundleRouteErrors{}
+	for _, metric := range family.GetMetric() {
+		labels := metricLabels(metric)
+		key := labels["method"] + " " + labels["path"]
+		route, ok := routes[key]
+		if !ok {
+			route = &deploymentBundleRouteErrors{
+				Method: labels["method"],
+				Path:   labels["path"],
+			}
+			routes[key] = route
+		}
+
+		count := uint64(metric.GetCounter().GetValue())
+		route.Requests += count
+		code, err := strconv.Atoi(labels["code"])
+		if err != nil {
+			continue
+		}
+		switch {
+		case code >= 500:
+			route.ServerErrors += count
+		case code >= 400:
+			route.ClientErrors += count
+		}
+	}
+
+	summary := make([]deploymentBundleRouteErrors, 0, len(routes))
+	for _, route := range routes {
+		if route.Requests > 0 {
+			route.ServerErrorRate = float64(route.ServerErrors) / float64(route.Requests)
+		}
+		summary = append(summary, *route)
+	}
+	slices.SortFunc(summary, func(a, b deploymentBundleRouteErrors) int {
+		return cmp.Or(
+			cmp.Compare(b.ServerErrors, a.ServerErrors),
+			cmp.Compare(a.Path, b.Path),
+			cmp.Compare(a.Method, b.Method),
+		)
+	})
+	return summary, nil
+}
+
+// deploymentBundleSlowQueries returns the database queries with the highest
+// mean latency since the server started. Query metrics are only collected
+// when CODER_PROMETHEUS_COLLECT_DB_METRICS is enabled.
+func (api *API) deploymentBundleSlowQueries(_ context.Context) (any, error) {
+	family, err := api.gatherMetricFamily(dbQueryLatenciesMetric)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]deploymentBundleQueryStats, 0, len(family.GetMetric()))
+	for _, metric := range family.GetMetric() {
+		histogram := metric.GetHistogram()
+		if histogram.GetSampleCount() == 0 {
+			continue
+		}
+		queries = append(queries, deploymentBundleQueryStats{
+			Query:        metricLabels(metric)["query"],
+			Count:        histogram.GetSampleCount(),
+			TotalSeconds: histogram.GetSampleSum(),
+			MeanSeconds:  histogram.GetSampleSum() / float64(histogram.GetSampleCount()),
+		})
+	}
+	slices.SortFunc(queries, func(a, b deploymentBundleQueryStats) int {
+		return cmp.Compare(b.MeanSeconds, a.MeanSeconds)
+	})
+	if len(queries) > deploymentBundleSlowQueries {
+		queries = queries[:deploymentBundleSlowQueries]
+	}
+	return queries, nil
+}
+
+// gatherMetricFamily returns the metric family with the given name from the
+// Prometheus registry of the server.
+func (api *API) gatherMetricFamily(name string) (*dto.MetricFamily, error) {
+	if api.Options.PrometheusRegistry == nil {
+		return nil, xerrors.New("prometheus registry is not configured")
+	}
+	families, err := api.Options.PrometheusRegistry.Gather()
+	if err != nil {
+		return nil, xerrors.Errorf("gather metrics: %w", err)
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			return family, nil
+		}
+	}
+	return nil, xerrors.Errorf("metric %q is not collected by this server", name)
+}
+
+func metricLabels(metric *dto.Metric) map[string]string {
+	labels := make(map[string]string, len(metric.GetLabel()))
+	for _, label := range metric.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	return labels
+}
@@ -0,0 +1,55 @@
+// Package deprecation evaluates template and template version deprecations.
+//
+// A deprecation has a message and an optional cutoff. Until the cutoff, the
+// deprecated template or version may still be used and users are warned at
+// build time. Without a cutoff, or once it passed, new usage is blocked.
+package deprecation
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Blocked returns true if the deprecation blocks new usage at now.
+func Blocked(message string, cutoff sql.NullTime, now time.Time) bool {
+	if message == "" {
+		return false
+	}
+	return !cutoff.Valid || !now.Before(cutoff.Time)
+}
+
+// Warnings returns the deprecation warnings of the template and the template
+// version. It returns nil if neither is deprecated.
+func Warnings(template database.Template, version database.TemplateVersion) []codersdk.DeprecationWarning {
+	var warnings []codersdk.DeprecationWarning
+	if template.Deprecated != "" {
+		warnings = append(warnings, codersdk.DeprecationWarning{
+			Subject:  codersdk.DeprecationSubjectTemplate,
+			ID:       template.ID,
+			Name:     template.Name,
+			Message:  template.Deprecated,
+			CutoffAt: CutoffPtr(template.DeprecationCutoff),
+		})
+	}
+	if version.Deprecated != "" {
+		warnings = append(warnings, codersdk.DeprecationWarning{
+			Subject:  codersdk.DeprecationSubjectTemplateVersion,
+			ID:       version.ID,
+			Name:     version.Name,
+			Message:  version.Deprecated,
+			CutoffAt: CutoffPtr(version.DeprecationCutoff),
+		})
+	}
+	return warnings
+}
+
+// CutoffPtr returns the cutoff as a pointer, or nil if there is none.
+func CutoffPtr(cutoff sql.NullTime) *time.Time {
+	if !cutoff.Valid {
+		return nil
+	}
+	return &cutoff.Time
+}
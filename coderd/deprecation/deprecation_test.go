@@ -0,0 +1,66 @@
+package deprecation_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/deprecation"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestBlocked(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name    string
+		message string
+		cutoff  sql.NullTime
+		blocked bool
+	}{
+		{name: "NotDeprecated", blocked: false},
+		{name: "NoCutoff", message: "gone", blocked: true},
+		{name: "BeforeCutoff", message: "going", cutoff: sql.NullTime{Time: now.Add(time.Hour), Valid: true}, blocked: false},
+		{name: "AtCutoff", message: "gone", cutoff: sql.NullTime{Time: now, Valid: true}, blocked: true},
+		{name: "AfterCutoff", message: "gone", cutoff: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}, blocked: true},
+		{name: "CutoffWithoutMessage", cutoff: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}, blocked: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.blocked, deprecation.Blocked(tc.message, tc.cutoff, now))
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	t.Parallel()
+
+	cutoff := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	template := database.Template{ID: uuid.New(), Name: "tpl"}
+	version := database.TemplateVersion{ID: uuid.New(), Name: "v1"}
+	require.Nil(t, deprecation.Warnings(template, version))
+
+	template.Deprecated = "use tpl2"
+	version.Deprecated = "use v2"
+	version.DeprecationCutoff = sql.NullTime{Time: cutoff, Valid: true}
+	require.Equal(t, []codersdk.DeprecationWarning{
+		{
+			Subject: codersdk.DeprecationSubjectTemplate,
+			ID:      template.ID,
+			Name:    "tpl",
+			Message: "use tpl2",
+		},
+		{
+			Subject:  codersdk.DeprecationSubjectTemplateVersion,
+			ID:       version.ID,
+			Name:     "v1",
+			Message:  "use v2",
+			CutoffAt: &cutoff,
+		},
+	}, deprecation.Warnings(template, version))
+}
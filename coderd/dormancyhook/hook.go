@@ -0,0 +1,97 @@
+package dormancyhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/util/xio"
+)
+
+// Workspace identifies the workspace a hook is invoked for.
+type Workspace struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	OwnerID        uuid.UUID `json:"owner_id"`
+	OwnerName      string    `json:"owner_name"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	TemplateID     uuid.UUID `json:"template_id"`
+}
+
+// Hook moves the storage of workspaces between tiers. Implementations must be
+// idempotent: a hook may be invoked again for the same event if coderd
+// restarts while it is running.
+type Hook interface {
+	// Dormant is invoked after a workspace became dormant, e.g. to snapshot
+	// its disks to cold storage and detach them.
+	Dormant(ctx context.Context, workspace Workspace) error
+	// Activate is invoked before a dormant workspace is activated, e.g. to
+	// restore its disks. The workspace stays dormant until Activate succeeds.
+	Activate(ctx context.Context, workspace Workspace) error
+}
+
+// Event is the lifecycle event a WebhookHook notifies the webhook about.
+type Event string
+
+const (
+	EventDormant  Event = "dormant"
+	EventActivate Event = "activate"
+)
+
+// WebhookRequest is the body POSTed to the dormancy hook webhook.
+type WebhookRequest struct {
+	Event     Event     `json:"event"`
+	Workspace Workspace `json:"workspace"`
+}
+
+// WebhookHook is a Hook that delegates every event to a webhook, so any
+// storage backend can be integrated without changes to Coder.
+type WebhookHook struct {
+	client *http.Client
+	url    string
+}
+
+var _ Hook = (*WebhookHook)(nil)
+
+// NewWebhookHook returns a Hook that POSTs a WebhookRequest to url for every
+// event. Any 2xx response is a success. The webhook should only respond once
+// the storage operation has completed.
+func NewWebhookHook(client *http.Client, url string) *WebhookHook {
+	return &WebhookHook{
+		client: client,
+		url:    url,
+	}
+}
+
+func (h *WebhookHook) Dormant(ctx context.Context, workspace Workspace) error {
+	return h.send(ctx, WebhookRequest{Event: EventDormant, Workspace: workspace})
+}
+
+func (h *WebhookHook) Activate(ctx context.Context, workspace Workspace) error {
+	return h.send(ctx, WebhookRequest{Event: EventActivate, Workspace: workspace})
+}
+
+func (h *WebhookHook) send(ctx context.Context, req WebhookRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return xerrors.Errorf("marshal dormancy hook request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create dormancy hook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	res, err := h.client.Do(httpReq)
+	if err != nil {
+		return xerrors.Errorf("send dormancy hook request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return xerrors.Errorf("dormancy hook responded with status %d: %s", res.StatusCode, xio.ReadErrorBody(res.Body))
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+// Package dormancyhook invokes a pluggable lifecycle hook when a workspace
+// becomes dormant and before it is activated again, so the storage of
+// dormant workspaces can be moved to a cheaper tier and restored on demand.
+//
+// Invocations are recorded in the database by the API and the lifecycle
+// executor, and run asynchronously by the Runner. A dormant workspace with
+// an activate hook stays dormant until the hook succeeds.
+package dormancyhook
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+const (
+	// PollInterval is how often the runner looks for pending hooks.
+	PollInterval = 15 * time.Second
+
+	// HookTimeout bounds a single invocation of the hook. Snapshotting or
+	// restoring large disks can take a while.
+	HookTimeout = 30 * time.Minute
+
+	// StaleRunningDuration is how long a hook may be running before it is
+	// assumed to be interrupted and invoked again.
+	StaleRunningDuration = HookTimeout + 5*time.Minute
+
+	// MaxHooksPerRun is the maximum number of hooks invoked in a single run.
+	MaxHooksPerRun = 20
+)
+
+// Runner invokes pending dormancy hooks on every tick from its channel.
+type Runner struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db    database.Store
+	log   slog.Logger
+	hook  Hook
+	tick  <-chan time.Time
+	stats chan<- Stats
+}
+
+// Stats contains statistics about the last run of the runner.
+type Stats struct {
+	// SucceededWorkspaceIDs contains the IDs of all workspaces whose hook
+	// succeeded.
+	SucceededWorkspaceIDs []uuid.UUID
+	// FailedWorkspaceIDs contains the IDs of all workspaces whose hook
+	// failed.
+	FailedWorkspaceIDs []uuid.UUID
+	// Error is set if claiming hooks failed or a hook result could not
+	// be stored. Failed hooks are listed in FailedWorkspaceIDs instead.
+	Error error
+}
+
+// New returns a new runner that invokes hook for pending dormancy hooks.
+func New(ctx context.Context, db database.Store, log slog.Logger, hook Hook, tick <-chan time.Time) *Runner {
+	//nolint:gocritic // The runner manages dormancy hooks of all workspaces.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Runner{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		db:     db,
+		log:    log,
+		hook:   hook,
+		tick:   tick,
+		stats:  nil,
+	}
+}
+
+// WithStatsChannel will cause Runner to push a Stats to ch after every tick.
+// This push is blocking, so if ch is not read, the runner will hang. This
+// should only be used in tests.
+func (r *Runner) WithStatsChannel(ch chan<- Stats) *Runner {
+	r.stats = ch
+	return r
+}
+
+// Start will cause the runner to invoke pending hooks on every tick from its
+// channel. It will stop when its context is Done, or when its channel is
+// closed.
+//
+// Start should only be called once.
+func (r *Runner) Start() {
+	go func() {
+		defer close(r.done)
+		defer r.cancel()
+
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case t, ok := <-r.tick:
+				if !ok {
+					return
+				}
+				stats := r.run(t)
+				if stats.Error != nil {
+					r.log.Warn(r.ctx, "error running dormancy hooks once", slog.Error(stats.Error))
+				}
+				if r.stats != nil {
+					select {
+					case <-r.ctx.Done():
+						return
+					case r.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the runner.
+func (r *Runner) Close() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *Runner) run(t time.Time) Stats {
+	stats := Stats{
+		SucceededWorkspaceIDs: []uuid.UUID{},
+		FailedWorkspaceIDs:    []uuid.UUID{},
+	}
+
+	now := dbtime.Time(t)
+	hooks, err := r.db.ClaimWorkspaceDormancyHooks(r.ctx, database.ClaimWorkspaceDormancyHooksParams{
+		Now:         now,
+		StaleBefore: now.Add(-StaleRunningDuration),
+		LimitCount:  MaxHooksPerRun,
+	})
+	if err != nil {
+		stats.Error = xerrors.Errorf("claim workspace dormancy hooks: %w", err)
+		return stats
+	}
+
+	for _, hook := range hooks {
+		log := r.log.With(slog.F("workspace_id", hook.WorkspaceID), slog.F("event", hook.Event))
+		hookErr := r.invoke(hook)
+		if hookErr != nil {
+			log.Warn(r.ctx, "dormancy hook failed", slog.Error(hookErr))
+		}
+		err := r.complete(hook, hookErr)
+		if err != nil {
+			stats.Error = xerrors.Errorf("complete dormancy hook of workspace %s: %w", hook.WorkspaceID, err)
+			return stats
+		}
+		if hookErr != nil {
+			stats.FailedWorkspaceIDs = append(stats.FailedWorkspaceIDs, hook.WorkspaceID)
+			continue
+		}
+		log.Debug(r.ctx, "dormancy hook succeeded")
+		stats.SucceededWorkspaceIDs = append(stats.SucceededWorkspaceIDs, hook.WorkspaceID)
+	}
+
+	return stats
+}
+
+func (r *Runner) invoke(hook database.WorkspaceDormancyHook) error {
+	ws, err := r.db.GetWorkspaceByID(r.ctx, hook.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	workspace := Workspace{
+		ID:             ws.ID,
+		Name:           ws.Name,
+		OwnerID:        ws.OwnerID,
+		OwnerName:      ws.OwnerUsername,
+		OrganizationID: ws.OrganizationID,
+		TemplateID:     ws.TemplateID,
+	}
+
+	ctx, cancel := context.WithTimeout(r.ctx, HookTimeout)
+	defer cancel()
+	switch hook.Event {
+	case database.WorkspaceDormancyHookEventDormant:
+		return r.hook.Dormant(ctx, workspace)
+	case database.WorkspaceDormancyHookEventActivate:
+		return r.hook.Activate(ctx, workspace)
+	default:
+		return xerrors.Errorf("unknown dormancy hook event %q", hook.Event)
+	}
+}
+
+// complete records the outcome of a hook. A successful activate hook also
+// clears the dormancy of the workspace, unless the hook was replaced by
+// another event while it was running.
+func (r *Runner) complete(hook database.WorkspaceDormancyHook, hookErr error) error {
+	return r.db.InTx(func(tx database.Store) error {
+		arg := database.UpdateWorkspaceDormancyHookStatusParams{
+			Status:      database.WorkspaceDormancyHookStatusSucceeded,
+			Now:         dbtime.Now(),
+			WorkspaceID: hook.WorkspaceID,
+			Event:       hook.Event,
+		}
+		if hookErr != nil {
+			arg.Status = database.WorkspaceDormancyHookStatusFailed
+			arg.Error = hookErr.Error()
+		}
+		updated, err := tx.UpdateWorkspaceDormancyHookStatus(r.ctx, arg)
+		if err != nil {
+			return xerrors.Errorf("update dormancy hook status: %w", err)
+		}
+		if updated == 0 || hookErr != nil || hook.Event != database.WorkspaceDormancyHookEventActivate {
+			return nil
+		}
+		_, err = tx.UpdateWorkspaceDormantDeletingAt(r.ctx, database.UpdateWorkspaceDormantDeletingAtParams{
+			ID:        hook.WorkspaceID,
+			DormantAt: sql.NullTime{Valid: false},
+		})
+		if err != nil {
+			return xerrors.Errorf("activate workspace: %w", err)
+		}
+		return nil
+	}, nil)
+}
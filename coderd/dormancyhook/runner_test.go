@@ -0,0 +1,198 @@
+package dormancyhook_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/dormancyhook"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestRunner(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{Username: "alice"})
+		ws   = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			Name:           "dev",
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+			DormantAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Seed(database.WorkspaceBuild{
+			Transition: database.WorkspaceTransitionStop,
+		}).Do().Workspace
+		now = dbtime.Now()
+	)
+
+	var (
+		mu       sync.Mutex
+		requests []dormancyhook.WebhookRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var req dormancyhook.WebhookRequest
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	takeRequests := func() []dormancyhook.WebhookRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		reqs := requests
+		requests = nil
+		return reqs
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	authzDB := dbauthz.New(db, rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry()), log, coderdtest.AccessControlStorePointer())
+	hook := dormancyhook.NewWebhookHook(srv.Client(), srv.URL)
+	tickCh := make(chan time.Time)
+	statsCh := make(chan dormancyhook.Stats)
+	runner := dormancyhook.New(ctx, authzDB, log, hook, tickCh).WithStatsChannel(statsCh)
+	runner.Start()
+	t.Cleanup(runner.Close)
+
+	want := dormancyhook.Workspace{
+		ID:             ws.ID,
+		Name:           "dev",
+		OwnerID:        user.ID,
+		OwnerName:      "alice",
+		OrganizationID: org.ID,
+		TemplateID:     ws.TemplateID,
+	}
+
+	// Nothing happens without a pending hook.
+	tickCh <- now
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.SucceededWorkspaceIDs)
+	require.Empty(t, takeRequests())
+
+	// A dormant hook does not change the dormancy of the workspace.
+	_, err := db.UpsertWorkspaceDormancyHook(ctx, database.UpsertWorkspaceDormancyHookParams{
+		WorkspaceID: ws.ID,
+		Event:       database.WorkspaceDormancyHookEventDormant,
+		Now:         now,
+	})
+	require.NoError(t, err)
+	tickCh <- now.Add(time.Minute)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{ws.ID}, stats.SucceededWorkspaceIDs)
+	require.Equal(t, []dormancyhook.WebhookRequest{{Event: dormancyhook.EventDormant, Workspace: want}}, takeRequests())
+	got, err := db.GetWorkspaceByID(ctx, ws.ID)
+	require.NoError(t, err)
+	require.True(t, got.DormantAt.Valid)
+
+	// A successful activate hook clears the dormancy of the workspace.
+	_, err = db.UpsertWorkspaceDormancyHook(ctx, database.UpsertWorkspaceDormancyHookParams{
+		WorkspaceID: ws.ID,
+		Event:       database.WorkspaceDormancyHookEventActivate,
+		Now:         now.Add(2 * time.Minute),
+	})
+	require.NoError(t, err)
+	tickCh <- now.Add(2 * time.Minute)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{ws.ID}, stats.SucceededWorkspaceIDs)
+	require.Equal(t, []dormancyhook.WebhookRequest{{Event: dormancyhook.EventActivate, Workspace: want}}, takeRequests())
+	got, err = db.GetWorkspaceByID(ctx, ws.ID)
+	require.NoError(t, err)
+	require.False(t, got.DormantAt.Valid)
+	status, err := db.GetWorkspaceDormancyHookByWorkspaceID(ctx, ws.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.WorkspaceDormancyHookStatusSucceeded, status.Status)
+}
+
+func TestRunnerHookError(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org = dbgen.Organization(t, db, database.Organization{})
+		ws  = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        dbgen.User(t, db, database.User{}).ID,
+			OrganizationID: org.ID,
+			DormantAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Seed(database.WorkspaceBuild{
+			Transition: database.WorkspaceTransitionStop,
+		}).Do().Workspace
+		now = dbtime.Now()
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+		_, _ = rw.Write([]byte("snapshot not found"))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	authzDB := dbauthz.New(db, rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry()), log, coderdtest.AccessControlStorePointer())
+	hook := dormancyhook.NewWebhookHook(srv.Client(), srv.URL)
+	tickCh := make(chan time.Time)
+	statsCh := make(chan dormancyhook.Stats)
+	runner := dormancyhook.New(ctx, authzDB, log, hook, tickCh).WithStatsChannel(statsCh)
+	runner.Start()
+	t.Cleanup(runner.Close)
+
+	_, err := db.UpsertWorkspaceDormancyHook(ctx, database.UpsertWorkspaceDormancyHookParams{
+		WorkspaceID: ws.ID,
+		Event:       database.WorkspaceDormancyHookEventActivate,
+		Now:         now,
+	})
+	require.NoError(t, err)
+
+	// A failed activate hook keeps the workspace dormant and is not retried.
+	for i := range 2 {
+		tickCh <- now.Add(time.Duration(i) * time.Minute)
+		stats := testutil.RequireReceive(ctx, t, statsCh)
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.SucceededWorkspaceIDs)
+		if i == 0 {
+			require.Equal(t, []uuid.UUID{ws.ID}, stats.FailedWorkspaceIDs)
+		} else {
+			require.Empty(t, stats.FailedWorkspaceIDs)
+		}
+	}
+	got, err := db.GetWorkspaceByID(ctx, ws.ID)
+	require.NoError(t, err)
+	require.True(t, got.DormantAt.Valid)
+	status, err := db.GetWorkspaceDormancyHookByWorkspaceID(ctx, ws.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.WorkspaceDormancyHookStatusFailed, status.Status)
+	require.Contains(t, status.Error, "snapshot not found")
+}
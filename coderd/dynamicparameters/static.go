@@ -89,6 +89,9 @@ func TemplateVersionParameter(it database.TemplateVersionParameter) previewtypes
 		Value:       previewtypes.StringLiteral(it.DefaultValue),
 		Diagnostics: make(previewtypes.Diagnostics, 0),
 	}
+	if it.Sensitive {
+		param.Styling.MaskInput = ptr.Ref(true)
+	}
 
 	if it.ValidationError != "" || it.ValidationRegex != "" || it.ValidationMonotonic != "" {
 		var reg *string
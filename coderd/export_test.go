@@ -11,3 +11,6 @@ var ChatStartWorkspace = (*API).chatStartWorkspace
 
 // ChatStopWorkspace exposes chatStopWorkspace for external tests.
 var ChatStopWorkspace = (*API).chatStopWorkspace
+
+// WorkspaceFields exposes workspaceFields for external tests.
+var WorkspaceFields = workspaceFields
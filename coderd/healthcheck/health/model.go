@@ -45,6 +45,8 @@ const (
 	CodeProvisionerDaemonAPIMajorVersionDeprecated Code = `EPD03`
 
 	CodeInterfaceSmallMTU = `EIF01`
+
+	CodeAgentClockSkew = `ECLK01`
 )
 
 // Default docs URL
@@ -31,8 +31,9 @@ import (
 )
 
 type (
-	apiKeyContextKey           struct{}
-	apiKeyPrecheckedContextKey struct{}
+	apiKeyContextKey            struct{}
+	apiKeyPrecheckedContextKey  struct{}
+	userImpersonationContextKey struct{}
 )
 
 // ValidateAPIKeyConfig holds the settings needed for API key
@@ -84,7 +85,13 @@ type APIKeyPrechecked struct {
 
 // APIKeyOptional may return an API key from the ExtractAPIKey handler.
 func APIKeyOptional(r *http.Request) (database.APIKey, bool) {
-	key, ok := r.Context().Value(apiKeyContextKey{}).(database.APIKey)
+	return APIKeyFromContext(r.Context())
+}
+
+// APIKeyFromContext may return an API key from the ExtractAPIKey handler for
+// callers that only have access to the request context.
+func APIKeyFromContext(ctx context.Context) (database.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(database.APIKey)
 	return key, ok
 }
 
@@ -97,6 +104,14 @@ func APIKey(r *http.Request) database.APIKey {
 	return key
 }
 
+// UserImpersonationFromContext returns the impersonation the request's API key
+// was issued for, if the request is made with an impersonation token. Depends
+// on the ExtractAPIKey handler.
+func UserImpersonationFromContext(ctx context.Context) (database.UserImpersonation, bool) {
+	impersonation, ok := ctx.Value(userImpersonationContextKey{}).(database.UserImpersonation)
+	return impersonation, ok
+}
+
 // UserAuthorizationOptional may return the roles and scope used for
 // authorization. Depends on the ExtractAPIKey handler.
 func UserAuthorizationOptional(ctx context.Context) (rbac.Subject, bool) {
@@ -191,6 +206,24 @@ func ExtractAPIKeyMW(cfg ExtractAPIKeyConfig) func(http.Handler) http.Handler {
 			// Actor is the user's authorization context.
 			ctx := r.Context()
 			ctx = context.WithValue(ctx, apiKeyContextKey{}, key)
+
+			// Requests made with an impersonation token carry the
+			// impersonation, so that their audit logs name the impersonator.
+			if key.LoginType == database.LoginTypeToken && strings.HasPrefix(key.TokenName, apikey.ImpersonationTokenNamePrefix) {
+				//nolint:gocritic // The token's scopes do not cover reading its impersonation.
+				impersonation, err := cfg.DB.GetUserImpersonationByAPIKeyID(dbauthz.AsSystemRestricted(ctx), key.ID)
+				if err != nil && !errors.Is(err, sql.ErrNoRows) {
+					httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+						Message: internalErrorMessage,
+						Detail:  fmt.Sprintf("Internal error fetching impersonation: %s", err.Error()),
+					})
+					return
+				}
+				if err == nil {
+					ctx = context.WithValue(ctx, userImpersonationContextKey{}, impersonation)
+				}
+			}
+
 			// Set the auth context for the user.
 			ctx = dbauthz.As(ctx, authz)
 
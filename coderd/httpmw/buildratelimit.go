@@ -0,0 +1,178 @@
+package httpmw
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/httprate"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	buildRateLimitScopeToken = "token"
+	buildRateLimitScopeIP    = "ip"
+)
+
+// BuildRateLimitConfig configures BuildRateLimit.
+type BuildRateLimitConfig struct {
+	// PerMinute is the sustained number of builds allowed per minute for
+	// each token and each IP address. Zero or negative disables the limit.
+	PerMinute int
+	// Burst is the number of builds allowed in quick succession before the
+	// sustained rate applies. Defaults to PerMinute.
+	Burst int
+	// AllowedTokenIDs are API key IDs that are exempt from the limit, for
+	// example tokens used by trusted automation.
+	AllowedTokenIDs []string
+	// Limited counts rejected requests by scope. Optional.
+	Limited *prometheus.CounterVec
+	Clock   quartz.Clock
+}
+
+// NewBuildRateLimitMetrics registers and returns the counter of requests
+// rejected by BuildRateLimit. It is kept separate from the API rate limits so
+// runaway build automation can be told apart from general API abuse.
+func NewBuildRateLimitMetrics(reg prometheus.Registerer) *prometheus.CounterVec {
+	return promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "api",
+		Name:      "workspace_build_rate_limited_total",
+		Help:      "The total number of workspace build requests rejected by the build rate limit.",
+	}, []string{"scope"})
+}
+
+// BuildRateLimit returns a handler that limits requests to build-creating
+// endpoints with a token bucket per API key and per IP address. A request is
+// only admitted if both buckets have capacity. Unlike RateLimit, the limit is
+// shared across all endpoints using the middleware, so a client cannot avoid
+// it by alternating between them.
+//
+// It must run after ExtractAPIKeyMW.
+func BuildRateLimit(cfg BuildRateLimitConfig) func(http.Handler) http.Handler {
+	if cfg.PerMinute <= 0 {
+		return func(handler http.Handler) http.Handler {
+			return handler
+		}
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = cfg.PerMinute
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = quartz.NewReal()
+	}
+
+	limiter := &buildRateLimiter{
+		clock:   cfg.Clock,
+		rate:    float64(cfg.PerMinute) / time.Minute.Seconds(),
+		burst:   float64(cfg.Burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			keys := make(map[string]string, 2)
+			if apiKey, ok := APIKeyOptional(r); ok {
+				if slices.Contains(cfg.AllowedTokenIDs, apiKey.ID) {
+					next.ServeHTTP(rw, r)
+					return
+				}
+				keys[buildRateLimitScopeToken] = apiKey.ID
+			}
+			ip, err := httprate.KeyByIP(r)
+			if err == nil {
+				keys[buildRateLimitScopeIP] = ip
+			}
+
+			scope, retryAfter := limiter.take(keys)
+			if scope != "" {
+				if cfg.Limited != nil {
+					cfg.Limited.WithLabelValues(scope).Inc()
+				}
+				rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				httpapi.Write(r.Context(), rw, http.StatusTooManyRequests, codersdk.Response{
+					Message: "You've been rate limited for creating too many workspace builds.",
+					Detail:  fmt.Sprintf("The %s limit allows %d builds per minute with a burst of %d.", scope, cfg.PerMinute, cfg.Burst),
+				})
+				return
+			}
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type buildRateLimiter struct {
+	clock quartz.Clock
+	// rate is the number of tokens added per second.
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+}
+
+// take consumes one token from the bucket of every scope in keys. If any
+// bucket is empty, nothing is consumed and the limiting scope is returned
+// along with the time until a token is available.
+func (l *buildRateLimiter) take(keys map[string]string) (string, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.prune(now)
+
+	buckets := make(map[string]*tokenBucket, len(keys))
+	// Check the token scope first so that a limited token is reported as
+	// such even if its IP address is limited too.
+	for _, scope := range []string{buildRateLimitScopeToken, buildRateLimitScopeIP} {
+		key, ok := keys[scope]
+		if !ok {
+			continue
+		}
+		bucketKey := scope + ":" + key
+		b, ok := l.buckets[bucketKey]
+		if !ok {
+			b = &tokenBucket{tokens: l.burst, last: now}
+			l.buckets[bucketKey] = b
+		}
+		b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+		b.last = now
+		if b.tokens < 1 {
+			return scope, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		}
+		buckets[scope] = b
+	}
+	for _, b := range buckets {
+		b.tokens--
+	}
+	return "", 0
+}
+
+// prune drops buckets that have refilled completely, since they are
+// indistinguishable from new ones. It runs at most once a minute.
+func (l *buildRateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+	l.lastPrune = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
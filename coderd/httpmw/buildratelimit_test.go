@@ -0,0 +1,135 @@
+package httpmw_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+func TestBuildRateLimit(t *testing.T) {
+	t.Parallel()
+
+	newRouter := func(t *testing.T, db database.Store, cfg httpmw.BuildRateLimitConfig) http.Handler {
+		t.Helper()
+		rtr := chi.NewRouter()
+		if db != nil {
+			rtr.Use(httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+				DB:       db,
+				Optional: false,
+			}))
+		}
+		rtr.Use(httpmw.BuildRateLimit(cfg))
+		rtr.Post("/*", func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		})
+		return rtr
+	}
+
+	do := func(t *testing.T, h http.Handler, path, token, remoteAddr string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest("POST", path, nil)
+		if token != "" {
+			req.Header.Set(codersdk.SessionTokenHeader, token)
+		}
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		resp := rec.Result()
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		rtr := newRouter(t, nil, httpmw.BuildRateLimitConfig{PerMinute: -1})
+		for i := 0; i < 10; i++ {
+			resp := do(t, rtr, "/", "", "1.2.3.4:1234")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	t.Run("BurstThenSustained", func(t *testing.T) {
+		t.Parallel()
+		clock := quartz.NewMock(t)
+		limited := httpmw.NewBuildRateLimitMetrics(prometheus.NewRegistry())
+		rtr := newRouter(t, nil, httpmw.BuildRateLimitConfig{
+			PerMinute: 6,
+			Burst:     3,
+			Limited:   limited,
+			Clock:     clock,
+		})
+
+		for i := 0; i < 3; i++ {
+			resp := do(t, rtr, "/", "", "1.2.3.4:1234")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+		// The limit is shared across endpoints.
+		resp := do(t, rtr, "/other", "", "1.2.3.4:1234")
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.Equal(t, "10", resp.Header.Get("Retry-After"))
+		require.Equal(t, float64(1), promtest.ToFloat64(limited.WithLabelValues("ip")))
+
+		// Another IP address has its own bucket.
+		resp = do(t, rtr, "/", "", "5.6.7.8:1234")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// One token is added every 10 seconds.
+		clock.Advance(10 * time.Second)
+		resp = do(t, rtr, "/", "", "1.2.3.4:1234")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = do(t, rtr, "/", "", "1.2.3.4:1234")
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	})
+
+	t.Run("PerToken", func(t *testing.T) {
+		t.Parallel()
+		db, _ := dbtestutil.NewDB(t)
+		u := dbgen.User(t, db, database.User{})
+		_, key := dbgen.APIKey(t, db, database.APIKey{UserID: u.ID})
+		limited := httpmw.NewBuildRateLimitMetrics(prometheus.NewRegistry())
+		rtr := newRouter(t, db, httpmw.BuildRateLimitConfig{
+			PerMinute: 1,
+			Limited:   limited,
+			Clock:     quartz.NewMock(t),
+		})
+
+		resp := do(t, rtr, "/", key, randRemoteAddr())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		// Changing the IP address does not reset the token's bucket.
+		resp = do(t, rtr, "/", key, randRemoteAddr())
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.Equal(t, float64(1), promtest.ToFloat64(limited.WithLabelValues("token")))
+	})
+
+	t.Run("Allowlist", func(t *testing.T) {
+		t.Parallel()
+		db, _ := dbtestutil.NewDB(t)
+		u := dbgen.User(t, db, database.User{})
+		_, key := dbgen.APIKey(t, db, database.APIKey{UserID: u.ID})
+		keyID, _, _ := strings.Cut(key, "-")
+		rtr := newRouter(t, db, httpmw.BuildRateLimitConfig{
+			PerMinute:       1,
+			AllowedTokenIDs: []string{keyID},
+			Clock:           quartz.NewMock(t),
+		})
+
+		for i := 0; i < 5; i++ {
+			resp := do(t, rtr, "/", key, "1.2.3.4:1234")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	})
+}
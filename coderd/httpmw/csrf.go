@@ -45,6 +45,8 @@ func CSRF(cookieCfg codersdk.HTTPCookieConfig) func(next http.Handler) http.Hand
 		// Exempt all requests that do not require CSRF protection.
 		// All GET requests are exempt by default.
 		mw.ExemptPath("/api/v2/csp/reports")
+		// VCS webhooks are authenticated by their signature.
+		mw.ExemptPath("/api/v2/integrations/vcs/events")
 
 		// This should not be required?
 		mw.ExemptRegexp(regexp.MustCompile("/api/v2/users/first"))
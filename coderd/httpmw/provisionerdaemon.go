@@ -3,10 +3,13 @@ package httpmw
 import (
 	"context"
 	"crypto/subtle"
+	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/provisionerkey"
 	"github.com/coder/coder/v2/codersdk"
@@ -87,13 +90,32 @@ func ExtractProvisionerDaemonAuthenticated(opts ExtractProvisionerAuthConfig) fu
 				return
 			}
 
-			if provisionerkey.Compare(pk.HashedSecret, hashedKey) {
+			now := dbtime.Now()
+			if !provisionerkey.ValidSecret(pk, hashedKey, now) {
 				handleOptional(http.StatusUnauthorized, codersdk.Response{
 					Message: "provisioner daemon key invalid",
 				})
 				return
 			}
 
+			// Only update the last used time every minute to avoid writing
+			// to the database on every request.
+			if !pk.LastUsedAt.Valid || now.Sub(pk.LastUsedAt.Time) > time.Minute {
+				pk.LastUsedAt = sql.NullTime{Time: now, Valid: true}
+				// nolint:gocritic // System must record the use of the provisioner key.
+				err = opts.DB.UpdateProvisionerKeyLastUsedAt(dbauthz.AsSystemRestricted(ctx), database.UpdateProvisionerKeyLastUsedAtParams{
+					ID:         pk.ID,
+					LastUsedAt: pk.LastUsedAt,
+				})
+				if err != nil {
+					handleOptional(http.StatusInternalServerError, codersdk.Response{
+						Message: "update provisioner daemon key",
+						Detail:  err.Error(),
+					})
+					return
+				}
+			}
+
 			// The provisioner key does not indicate a specific provisioner daemon. So just
 			// store a boolean so the caller can check if the request is from an
 			// authenticated provisioner daemon.
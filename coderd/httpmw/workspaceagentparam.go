@@ -41,6 +41,12 @@ func ExtractWorkspaceAgentAndWorkspaceParam(db database.Store) func(http.Handler
 				})
 				return
 			}
+			if key, ok := APIKeyOptional(r); ok && !key.AllowsTemplate(agentWithWorkspace.WorkspaceTable.TemplateID) {
+				httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+					Message: "Agent doesn't exist with that id, or you do not have access to it.",
+				})
+				return
+			}
 
 			ctx = context.WithValue(ctx, workspaceAgentAndWorkspaceParamContextKey{}, agentWithWorkspace)
 			chi.RouteContext(ctx).URLParams.Add("workspace", agentWithWorkspace.WorkspaceTable.ID.String())
@@ -43,6 +43,12 @@ func ExtractWorkspaceParam(db database.Store) func(http.Handler) http.Handler {
 				})
 				return
 			}
+			// Template restricted API keys must not be able to see
+			// workspaces built from other templates.
+			if key, ok := APIKeyOptional(r); ok && !key.AllowsTemplate(workspace.TemplateID) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
 
 			ctx = context.WithValue(ctx, workspaceParamContextKey{}, workspace)
 
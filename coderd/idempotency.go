@@ -0,0 +1,232 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpapi/httperror"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// idempotencyKeyTTL is how long a retried request with the same
+	// idempotency key returns the result of the original request.
+	idempotencyKeyTTL = 24 * time.Hour
+	// maxIdempotencyKeyLength caps the length of idempotency keys.
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotencyKeyFromRequest returns the idempotency key of the request, set
+// with either the Idempotency-Key header or the idempotency_key field of the
+// request body. An empty key means the request is not idempotent.
+func idempotencyKeyFromRequest(r *http.Request, field string) (string, error) {
+	key := r.Header.Get(codersdk.IdempotencyKeyHeader)
+	if key != "" && field != "" && key != field {
+		return "", httperror.NewResponseError(http.StatusBadRequest, codersdk.Response{
+			Message: "Conflicting idempotency keys.",
+			Detail:  fmt.Sprintf("The %s header and the idempotency_key field must match when both are set.", codersdk.IdempotencyKeyHeader),
+		})
+	}
+	if key == "" {
+		key = field
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return "", httperror.NewResponseError(http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid idempotency key.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "idempotency_key",
+				Detail: fmt.Sprintf("Must be at most %d characters.", maxIdempotencyKeyLength),
+			}},
+		})
+	}
+	return key, nil
+}
+
+// idempotencyRequestHash hashes the parts of a request that must be the same
+// for a retried request to be considered the same request.
+func idempotencyRequestHash(parts ...any) ([]byte, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, part := range parts {
+		if err := enc.Encode(part); err != nil {
+			return nil, xerrors.Errorf("encode request: %w", err)
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// lookupIdempotencyKey returns the unexpired idempotency key of the user, if
+// any. It fails if the key was used for a different request.
+func (api *API) lookupIdempotencyKey(ctx context.Context, userID uuid.UUID, operation database.IdempotencyKeyOperation, key string, requestHash []byte) (database.IdempotencyKey, bool, error) {
+	idempotencyKey, err := api.Database.GetIdempotencyKey(ctx, database.GetIdempotencyKeyParams{
+		UserID:    userID,
+		Operation: operation,
+		Key:       key,
+		Now:       dbtime.Time(api.Clock.Now()),
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return database.IdempotencyKey{}, false, nil
+	}
+	if err != nil {
+		return database.IdempotencyKey{}, false, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching idempotency key.",
+			Detail:  err.Error(),
+		})
+	}
+	if !bytes.Equal(idempotencyKey.RequestHash, requestHash) {
+		return database.IdempotencyKey{}, false, httperror.NewResponseError(http.StatusUnprocessableEntity, codersdk.Response{
+			Message: "Idempotency key was already used for a different request.",
+			Detail:  "Use a new idempotency key for each distinct request.",
+		})
+	}
+	return idempotencyKey, true, nil
+}
+
+// recordIdempotencyKey stores the idempotency key of a request that created
+// a workspace build, so retries of the request return the build instead of
+// creating another one. Failing to store the key does not fail the request,
+// as the build was already created.
+func (api *API) recordIdempotencyKey(ctx context.Context, userID uuid.UUID, operation database.IdempotencyKeyOperation, key string, requestHash []byte, workspaceID, workspaceBuildID uuid.UUID) {
+	now := dbtime.Time(api.Clock.Now())
+	_, err := api.Database.InsertIdempotencyKey(ctx, database.InsertIdempotencyKeyParams{
+		UserID:           userID,
+		Operation:        operation,
+		Key:              key,
+		RequestHash:      requestHash,
+		WorkspaceID:      workspaceID,
+		WorkspaceBuildID: workspaceBuildID,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(idempotencyKeyTTL),
+	})
+	if err != nil {
+		// sql.ErrNoRows means a concurrent request with the same key won the
+		// race. Creating a workspace with the same name or a build while
+		// another is pending fails, so it created the only one.
+		api.Logger.Warn(ctx, "failed to store idempotency key",
+			slog.F("user_id", userID),
+			slog.F("operation", operation),
+			slog.F("workspace_build_id", workspaceBuildID),
+			slog.Error(err),
+		)
+	}
+}
+
+// idempotentWorkspace returns the workspace created by the original request
+// of a replayed idempotency key.
+func (api *API) idempotentWorkspace(ctx context.Context, initiatorID uuid.UUID, idempotencyKey database.IdempotencyKey) (codersdk.Workspace, error) {
+	workspace, err := api.Database.GetWorkspaceByID(ctx, idempotencyKey.WorkspaceID)
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			return codersdk.Workspace{}, httperror.ErrResourceNotFound
+		}
+		return codersdk.Workspace{}, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+	}
+
+	data, err := api.workspaceData(ctx, []database.Workspace{workspace})
+	if err != nil {
+		return codersdk.Workspace{}, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+	}
+	if len(data.builds) == 0 || len(data.templates) == 0 {
+		return codersdk.Workspace{}, httperror.ErrResourceNotFound
+	}
+
+	appStatus := codersdk.WorkspaceAppStatus{}
+	if len(data.appStatuses) > 0 {
+		appStatus = data.appStatuses[0]
+	}
+
+	w, err := convertWorkspace(
+		ctx,
+		api.Logger,
+		initiatorID,
+		workspace,
+		data.builds[0],
+		data.templates[0],
+		api.Options.AllowWorkspaceRenames,
+		appStatus,
+	)
+	if err != nil {
+		return codersdk.Workspace{}, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace.",
+			Detail:  err.Error(),
+		})
+	}
+	data.setWorkspaceFields(&w)
+	return w, nil
+}
+
+// idempotentWorkspaceBuild returns the workspace build created by the
+// original request of a replayed idempotency key.
+func (api *API) idempotentWorkspaceBuild(ctx context.Context, workspace database.Workspace, idempotencyKey database.IdempotencyKey) (codersdk.WorkspaceBuild, error) {
+	workspaceBuild, err := api.Database.GetWorkspaceBuildByID(ctx, idempotencyKey.WorkspaceBuildID)
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			return codersdk.WorkspaceBuild{}, httperror.ErrResourceNotFound
+		}
+		return codersdk.WorkspaceBuild{}, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build.",
+			Detail:  err.Error(),
+		})
+	}
+
+	data, err := api.workspaceBuildsData(ctx, []database.WorkspaceBuild{workspaceBuild})
+	if err != nil {
+		return codersdk.WorkspaceBuild{}, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error getting workspace build data.",
+			Detail:  err.Error(),
+		})
+	}
+	if len(data.jobs) == 0 || len(data.templateVersions) == 0 {
+		return codersdk.WorkspaceBuild{}, httperror.ErrResourceNotFound
+	}
+
+	apiBuild, err := api.convertWorkspaceBuild(
+		workspaceBuild,
+		workspace,
+		data.jobs[0],
+		data.resources,
+		data.metadata,
+		data.agents,
+		data.apps,
+		data.appStatuses,
+		data.scripts,
+		data.logSources,
+		data.bootstrapProgress,
+		data.networkPolicyViolations,
+		data.crashLoops,
+		data.memoryEvents,
+		data.services,
+		data.serviceTransitions,
+		data.templateVersions[0],
+		data.templates,
+		nil,
+		data.logArchives,
+	)
+	if err != nil {
+		return codersdk.WorkspaceBuild{}, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		})
+	}
+	return apiBuild, nil
+}
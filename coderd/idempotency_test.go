@@ -0,0 +1,120 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateWorkspace", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		req := codersdk.CreateWorkspaceRequest{
+			TemplateID:     template.ID,
+			Name:           "retried",
+			IdempotencyKey: "create-retried",
+		}
+		workspace, err := client.CreateUserWorkspace(ctx, codersdk.Me, req)
+		require.NoError(t, err)
+
+		// A retry returns the original workspace instead of failing because
+		// the name is taken.
+		replayed, err := client.CreateUserWorkspace(ctx, codersdk.Me, req)
+		require.NoError(t, err)
+		require.Equal(t, workspace.ID, replayed.ID)
+		require.Equal(t, workspace.LatestBuild.ID, replayed.LatestBuild.ID)
+
+		workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{Owner: codersdk.Me})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+
+		// The key cannot be reused for a different request.
+		req.Name = "different"
+		_, err = client.CreateUserWorkspace(ctx, codersdk.Me, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode())
+	})
+
+	t.Run("Header", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		create := func(req codersdk.CreateWorkspaceRequest) *http.Response {
+			res, err := client.Request(ctx, http.MethodPost, "/api/v2/users/me/workspaces", req, func(r *http.Request) {
+				r.Header.Set(codersdk.IdempotencyKeyHeader, "header-key")
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+			return res
+		}
+
+		req := codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "header",
+		}
+		require.Equal(t, http.StatusCreated, create(req).StatusCode)
+		// The request field is equivalent to the header.
+		req.IdempotencyKey = "header-key"
+		require.Equal(t, http.StatusCreated, create(req).StatusCode)
+
+		req.IdempotencyKey = "other-key"
+		require.Equal(t, http.StatusBadRequest, create(req).StatusCode)
+	})
+
+	t.Run("CreateWorkspaceBuild", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		req := codersdk.CreateWorkspaceBuildRequest{
+			Transition:     codersdk.WorkspaceTransitionStop,
+			IdempotencyKey: "stop",
+		}
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, req)
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+		replayed, err := client.CreateWorkspaceBuild(ctx, workspace.ID, req)
+		require.NoError(t, err)
+		require.Equal(t, build.ID, replayed.ID)
+
+		builds, err := client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{WorkspaceID: workspace.ID})
+		require.NoError(t, err)
+		require.Len(t, builds, 2)
+
+		req.Transition = codersdk.WorkspaceTransitionStart
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode())
+	})
+}
@@ -41,6 +41,13 @@ var fallbackIcons = map[uuid.UUID]string{
 	notifications.TemplateWorkspaceManualBuildFailed: codersdk.InboxNotificationFallbackIconWorkspace,
 	notifications.TemplateWorkspaceOutOfMemory:       codersdk.InboxNotificationFallbackIconWorkspace,
 	notifications.TemplateWorkspaceOutOfDisk:         codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceBudgetExceeded:    codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceSupportBundle:     codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceExpiring:          codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceAgentCrashLooping: codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceBuildFailed:       codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceOutOfMemoryKill:   codersdk.InboxNotificationFallbackIconWorkspace,
+	notifications.TemplateWorkspaceSummaryReport:     codersdk.InboxNotificationFallbackIconWorkspace,
 
 	// account related notifications
 	notifications.TemplateUserAccountCreated:           codersdk.InboxNotificationFallbackIconAccount,
@@ -55,6 +62,7 @@ var fallbackIcons = map[uuid.UUID]string{
 	notifications.TemplateTemplateDeleted:             codersdk.InboxNotificationFallbackIconTemplate,
 	notifications.TemplateTemplateDeprecated:          codersdk.InboxNotificationFallbackIconTemplate,
 	notifications.TemplateWorkspaceBuildsFailedReport: codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateTemplateHealthReport:        codersdk.InboxNotificationFallbackIconTemplate,
 
 	// chat related notifications
 	notifications.TemplateChatAutoArchiveDigest: codersdk.InboxNotificationFallbackIconOther,
@@ -3,9 +3,11 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/csv"
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -200,6 +203,540 @@ func (api *API) insightsUserActivity(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary Get insights about workspace egress
+// @ID get-insights-about-workspace-egress
+// @Security CoderSessionToken
+// @Produce json,text/csv
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param format query string false "Response format" Enums(json,csv)
+// @Success 200 {object} codersdk.WorkspaceEgressInsightsResponse
+// @Router /api/v2/insights/workspace-egress [get]
+func (api *API) insightsWorkspaceEgress(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		format          = p.String(vals, "json", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if format != "json" && format != "csv" {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "format",
+			Detail: fmt.Sprintf("Query param %q must be one of json or csv, got %q", "format", format),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetWorkspaceEgressInsights(ctx, database.GetWorkspaceEgressInsightsParams{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace egress.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	egress := make([]codersdk.WorkspaceEgress, 0, len(rows))
+	for _, row := range rows {
+		egress = append(egress, codersdk.WorkspaceEgress{
+			Date:            row.Date,
+			WorkspaceID:     row.WorkspaceID,
+			WorkspaceName:   row.WorkspaceName,
+			OwnerID:         row.OwnerID,
+			OwnerUsername:   row.OwnerUsername,
+			TemplateID:      row.TemplateID,
+			TemplateName:    row.TemplateName,
+			SSH:             codersdk.EgressBytes{RxBytes: row.RxBytesSsh, TxBytes: row.TxBytesSsh},
+			ReconnectingPTY: codersdk.EgressBytes{RxBytes: row.RxBytesReconnectingPty, TxBytes: row.TxBytesReconnectingPty},
+			PortForward:     codersdk.EgressBytes{RxBytes: row.RxBytesPortForward, TxBytes: row.TxBytesPortForward},
+		})
+	}
+
+	if format == "csv" {
+		writeWorkspaceEgressCSV(ctx, api.Logger, rw, egress)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceEgressInsightsResponse{
+		Report: codersdk.WorkspaceEgressInsightsReport{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: templateIDs,
+			Workspaces:  egress,
+		},
+	})
+}
+
+// writeWorkspaceEgressCSV writes one row per workspace and day, for import
+// into chargeback tooling.
+func writeWorkspaceEgressCSV(ctx context.Context, logger slog.Logger, rw http.ResponseWriter, egress []codersdk.WorkspaceEgress) {
+	rw.Header().Set("Content-Type", "text/csv")
+	rw.Header().Set("Content-Disposition", `attachment; filename="workspace-egress.csv"`)
+	rw.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(rw)
+	_ = w.Write([]string{
+		"date", "workspace_id", "workspace_name", "owner_username", "template_name",
+		"ssh_rx_bytes", "ssh_tx_bytes",
+		"reconnecting_pty_rx_bytes", "reconnecting_pty_tx_bytes",
+		"port_forward_rx_bytes", "port_forward_tx_bytes",
+	})
+	for _, e := range egress {
+		_ = w.Write([]string{
+			e.Date.Format(time.DateOnly),
+			e.WorkspaceID.String(),
+			e.WorkspaceName,
+			e.OwnerUsername,
+			e.TemplateName,
+			strconv.FormatInt(e.SSH.RxBytes, 10),
+			strconv.FormatInt(e.SSH.TxBytes, 10),
+			strconv.FormatInt(e.ReconnectingPTY.RxBytes, 10),
+			strconv.FormatInt(e.ReconnectingPTY.TxBytes, 10),
+			strconv.FormatInt(e.PortForward.RxBytes, 10),
+			strconv.FormatInt(e.PortForward.TxBytes, 10),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		logger.Debug(ctx, "write workspace egress csv", slog.Error(err))
+	}
+}
+
+// @Summary Get insights about prebuilt workspace claims
+// @ID get-insights-about-prebuilt-workspace-claims
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Success 200 {object} codersdk.PrebuildClaimInsightsResponse
+// @Router /api/v2/insights/prebuilds [get]
+func (api *API) insightsPrebuilds(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetPrebuildClaimInsights(ctx, database.GetPrebuildClaimInsightsParams{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching prebuild claim insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	presets := make([]codersdk.PrebuildPresetClaimInsight, 0, len(rows))
+	for _, row := range rows {
+		var successRate float64
+		if row.ClaimAttempts > 0 {
+			successRate = float64(row.Claimed) / float64(row.ClaimAttempts)
+		}
+		presets = append(presets, codersdk.PrebuildPresetClaimInsight{
+			OrganizationName:      row.OrganizationName,
+			TemplateID:            row.TemplateID,
+			TemplateName:          row.TemplateName,
+			TemplateVersionName:   row.TemplateVersionName,
+			PresetID:              row.PresetID,
+			PresetName:            row.PresetName,
+			ClaimAttempts:         row.ClaimAttempts,
+			Claimed:               row.Claimed,
+			ClaimSuccessRate:      successRate,
+			TimeToReadyP50Seconds: row.TimeToReadyP50Seconds,
+			TimeToReadyP95Seconds: row.TimeToReadyP95Seconds,
+			Misses: map[codersdk.PrebuildClaimMissReason]int64{
+				codersdk.PrebuildClaimMissReasonReconciliationLag: row.MissedReconciliationLag,
+				codersdk.PrebuildClaimMissReasonCapacity:          row.MissedCapacity,
+				codersdk.PrebuildClaimMissReasonFailures:          row.MissedFailures,
+			},
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.PrebuildClaimInsightsResponse{
+		Report: codersdk.PrebuildClaimInsightsReport{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: templateIDs,
+			Presets:     presets,
+		},
+	})
+}
+
+// @Summary Get insights about autostop effectiveness
+// @ID get-insights-about-autostop-effectiveness
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Success 200 {object} codersdk.AutostopInsightsResponse
+// @Router /api/v2/insights/autostop [get]
+func (api *API) insightsAutostop(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetAutostopInsights(ctx, database.GetAutostopInsightsParams{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching autostop insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	templates := make([]codersdk.AutostopTemplateInsight, 0, len(rows))
+	organizations := make([]codersdk.AutostopOrganizationInsight, 0)
+	// Rows are ordered by organization, so the rows of an organization are
+	// adjacent.
+	var orgRows []database.GetAutostopInsightsRow
+	flushOrg := func() {
+		if len(orgRows) == 0 {
+			return
+		}
+		var running, autostop, dormancy, failureTTL int64
+		for _, row := range orgRows {
+			running += row.RunningSeconds
+			autostop += row.AutostopSeconds
+			dormancy += row.DormancySeconds
+			failureTTL += row.FailureTtlSeconds
+		}
+		organizations = append(organizations, codersdk.AutostopOrganizationInsight{
+			AutostopSavings:  autostopSavings(running, autostop, dormancy, failureTTL),
+			OrganizationID:   orgRows[0].OrganizationID,
+			OrganizationName: orgRows[0].OrganizationName,
+		})
+		orgRows = nil
+	}
+	for _, row := range rows {
+		if len(orgRows) > 0 && orgRows[0].OrganizationID != row.OrganizationID {
+			flushOrg()
+		}
+		orgRows = append(orgRows, row)
+		templates = append(templates, codersdk.AutostopTemplateInsight{
+			AutostopSavings:     autostopSavings(row.RunningSeconds, row.AutostopSeconds, row.DormancySeconds, row.FailureTtlSeconds),
+			OrganizationID:      row.OrganizationID,
+			OrganizationName:    row.OrganizationName,
+			TemplateID:          row.TemplateID,
+			TemplateName:        row.TemplateName,
+			TemplateDisplayName: row.TemplateDisplayName,
+		})
+	}
+	flushOrg()
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.AutostopInsightsResponse{
+		Report: codersdk.AutostopInsightsReport{
+			StartTime:     startTime,
+			EndTime:       endTime,
+			TemplateIDs:   templateIDs,
+			Templates:     templates,
+			Organizations: organizations,
+		},
+	})
+}
+
+// autostopSavings converts the running and stopped seconds of workspaces to
+// hours.
+func autostopSavings(running, autostop, dormancy, failureTTL int64) codersdk.AutostopSavings {
+	saved := autostop + dormancy + failureTTL
+	var savedRatio float64
+	if running+saved > 0 {
+		savedRatio = float64(saved) / float64(running+saved)
+	}
+	hours := func(seconds int64) float64 {
+		return time.Duration(seconds * int64(time.Second)).Hours()
+	}
+	return codersdk.AutostopSavings{
+		RunningHours:      hours(running),
+		AutostopHours:     hours(autostop),
+		DormancyHours:     hours(dormancy),
+		FailureTTLHours:   hours(failureTTL),
+		SavedHours:        hours(saved),
+		WouldHaveRunHours: hours(running + saved),
+		SavedRatio:        savedRatio,
+	}
+}
+
+// @Summary Get insights about active developer days
+// @ID get-insights-about-active-developer-days
+// @Security CoderSessionToken
+// @Produce json,text/csv
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param organization_id query string false "Organization ID" format(uuid)
+// @Param min_active_minutes query int false "Minutes of usage a user needs on a day to count as active"
+// @Param format query string false "Response format" Enums(json,csv)
+// @Success 200 {object} codersdk.ActiveDeveloperDaysInsightsResponse
+// @Router /api/v2/insights/developer-days [get]
+func (api *API) insightsActiveDeveloperDays(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString  = p.String(vals, "", "start_time")
+		endTimeString    = p.String(vals, "", "end_time")
+		templateIDs      = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		organizationID   = p.UUID(vals, uuid.Nil, "organization_id")
+		minActiveMinutes = p.PositiveInt64(vals, 1, "min_active_minutes")
+		format           = p.String(vals, "json", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if format != "json" && format != "csv" {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "format",
+			Detail: fmt.Sprintf("Query param %q must be one of json or csv, got %q", "format", format),
+		})
+	}
+	if minActiveMinutes < 1 || minActiveMinutes > 24*60 {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "min_active_minutes",
+			Detail: fmt.Sprintf("Query param %q must be between 1 and %d", "min_active_minutes", 24*60),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetTemplateActiveDeveloperDays(ctx, database.GetTemplateActiveDeveloperDaysParams{
+		StartTime:      startTime,
+		EndTime:        endTime,
+		TemplateIDs:    templateIDs,
+		OrganizationID: organizationID,
+		MinUsageMins:   int32(minActiveMinutes),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching active developer days.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	report := convertActiveDeveloperDays(rows)
+	for i := range report.Days {
+		// Days start at the time of day of start_time, so report them in
+		// its time zone.
+		report.Days[i].Date = report.Days[i].Date.In(startTime.Location())
+	}
+	if format == "csv" {
+		writeActiveDeveloperDaysCSV(ctx, api.Logger, rw, report.Days)
+		return
+	}
+
+	report.StartTime = startTime
+	report.EndTime = endTime
+	report.TemplateIDs = templateIDs
+	report.OrganizationID = organizationID
+	report.MinActiveMinutes = minActiveMinutes
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.ActiveDeveloperDaysInsightsResponse{
+		Report: report,
+	})
+}
+
+// convertActiveDeveloperDays aggregates the active users per day and template
+// into daily counts and totals per template and organization. The rows are
+// ordered by day, so the days and totals keep that order.
+func convertActiveDeveloperDays(rows []database.GetTemplateActiveDeveloperDaysRow) codersdk.ActiveDeveloperDaysInsightsReport {
+	report := codersdk.ActiveDeveloperDaysInsightsReport{
+		Days:          []codersdk.TemplateActiveDevelopers{},
+		Templates:     []codersdk.TemplateActiveDeveloperDays{},
+		Organizations: []codersdk.OrganizationActiveDeveloperDays{},
+	}
+	type orgUserDay struct {
+		date           time.Time
+		organizationID uuid.UUID
+		userID         uuid.UUID
+	}
+	var (
+		templateIdx = make(map[uuid.UUID]int)
+		orgIdx      = make(map[uuid.UUID]int)
+		orgUserDays = make(map[orgUserDay]struct{})
+	)
+	for _, row := range rows {
+		last := len(report.Days) - 1
+		if last < 0 || !report.Days[last].Date.Equal(row.Date) || report.Days[last].TemplateID != row.TemplateID {
+			report.Days = append(report.Days, codersdk.TemplateActiveDevelopers{
+				Date:             row.Date,
+				TemplateID:       row.TemplateID,
+				TemplateName:     row.TemplateName,
+				OrganizationID:   row.OrganizationID,
+				OrganizationName: row.OrganizationName,
+			})
+			last++
+		}
+		report.Days[last].ActiveDevelopers++
+
+		i, ok := templateIdx[row.TemplateID]
+		if !ok {
+			i = len(report.Templates)
+			templateIdx[row.TemplateID] = i
+			report.Templates = append(report.Templates, codersdk.TemplateActiveDeveloperDays{
+				TemplateID:       row.TemplateID,
+				TemplateName:     row.TemplateName,
+				OrganizationID:   row.OrganizationID,
+				OrganizationName: row.OrganizationName,
+			})
+		}
+		report.Templates[i].ActiveDeveloperDays++
+
+		key := orgUserDay{date: row.Date, organizationID: row.OrganizationID, userID: row.UserID}
+		if _, ok := orgUserDays[key]; ok {
+			continue
+		}
+		orgUserDays[key] = struct{}{}
+		i, ok = orgIdx[row.OrganizationID]
+		if !ok {
+			i = len(report.Organizations)
+			orgIdx[row.OrganizationID] = i
+			report.Organizations = append(report.Organizations, codersdk.OrganizationActiveDeveloperDays{
+				OrganizationID:   row.OrganizationID,
+				OrganizationName: row.OrganizationName,
+			})
+		}
+		report.Organizations[i].ActiveDeveloperDays++
+	}
+	return report
+}
+
+// writeActiveDeveloperDaysCSV writes one row per day and template.
+func writeActiveDeveloperDaysCSV(ctx context.Context, logger slog.Logger, rw http.ResponseWriter, days []codersdk.TemplateActiveDevelopers) {
+	rw.Header().Set("Content-Type", "text/csv")
+	rw.Header().Set("Content-Disposition", `attachment; filename="active-developer-days.csv"`)
+	rw.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(rw)
+	_ = w.Write([]string{
+		"date", "organization_id", "organization_name", "template_id", "template_name", "active_developers",
+	})
+	for _, d := range days {
+		_ = w.Write([]string{
+			d.Date.Format(time.DateOnly),
+			d.OrganizationID.String(),
+			d.OrganizationName,
+			d.TemplateID.String(),
+			d.TemplateName,
+			strconv.FormatInt(d.ActiveDevelopers, 10),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		logger.Debug(ctx, "write active developer days csv", slog.Error(err))
+	}
+}
+
 // @Summary Get insights about user latency
 // @ID get-insights-about-user-latency
 // @Security CoderSessionToken
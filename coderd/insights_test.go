@@ -2,6 +2,8 @@ package coderd_test
 
 import (
 	"context"
+	"database/sql"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,9 +27,11 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbrollup"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspacestats"
@@ -381,6 +385,283 @@ func TestUserActivityInsights_BadRequest(t *testing.T) {
 	assert.Error(t, err, "want error for end time before start time")
 }
 
+func TestWorkspaceEgressInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OwnerID:        owner.UserID,
+		OrganizationID: owner.OrganizationID,
+	}).Do().Workspace
+
+	y, m, d := time.Now().UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	// Two reports on the same day accumulate into a single row.
+	//nolint:gocritic // Inserting egress is a system operation.
+	sysCtx := dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitLong))
+	for range 2 {
+		err := db.UpsertWorkspaceEgressDaily(sysCtx, database.UpsertWorkspaceEgressDailyParams{
+			WorkspaceID:        ws.ID,
+			TemplateID:         ws.TemplateID,
+			OwnerID:            ws.OwnerID,
+			Date:               today,
+			RxBytesSsh:         100,
+			TxBytesSsh:         200,
+			RxBytesPortForward: 10,
+			TxBytesPortForward: 20,
+		})
+		require.NoError(t, err)
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	req := codersdk.WorkspaceEgressInsightsRequest{
+		StartTime: today.AddDate(0, 0, -1),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour),
+	}
+	resp, err := client.WorkspaceEgressInsights(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Report.Workspaces, 1)
+	egress := resp.Report.Workspaces[0]
+	require.Equal(t, ws.ID, egress.WorkspaceID)
+	require.Equal(t, codersdk.EgressBytes{RxBytes: 200, TxBytes: 400}, egress.SSH)
+	require.Equal(t, codersdk.EgressBytes{RxBytes: 20, TxBytes: 40}, egress.PortForward)
+	require.Equal(t, codersdk.EgressBytes{}, egress.ReconnectingPTY)
+
+	body, err := client.WorkspaceEgressInsightsCSV(ctx, req)
+	require.NoError(t, err)
+	defer body.Close()
+	records, err := csv.NewReader(body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, today.Format(time.DateOnly), records[1][0])
+	require.Equal(t, ws.ID.String(), records[1][1])
+	require.Equal(t, "200", records[1][5])
+
+	// Days outside the window are excluded.
+	resp, err = client.WorkspaceEgressInsights(ctx, codersdk.WorkspaceEgressInsightsRequest{
+		StartTime: today.AddDate(0, 0, -3),
+		EndTime:   today.AddDate(0, 0, -1),
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Report.Workspaces)
+}
+
+func TestPrebuildClaimInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OwnerID:        owner.UserID,
+		OrganizationID: owner.OrganizationID,
+	}).Do()
+	preset := dbgen.Preset(t, db, database.InsertPresetParams{
+		TemplateVersionID: r.TemplateVersion.ID,
+		DesiredInstances:  sql.NullInt32{Int32: 1, Valid: true},
+	})
+
+	//nolint:gocritic // Recording claim attempts is a system operation.
+	sysCtx := dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitLong))
+	now := dbtime.Now()
+	for _, params := range []database.InsertPrebuildClaimAttemptParams{
+		{WorkspaceID: uuid.NullUUID{UUID: r.Workspace.ID, Valid: true}, Claimed: true},
+		{MissReason: database.NullPrebuildClaimMissReason{PrebuildClaimMissReason: database.PrebuildClaimMissReasonCapacity, Valid: true}},
+		{MissReason: database.NullPrebuildClaimMissReason{PrebuildClaimMissReason: database.PrebuildClaimMissReasonCapacity, Valid: true}},
+		{MissReason: database.NullPrebuildClaimMissReason{PrebuildClaimMissReason: database.PrebuildClaimMissReasonFailures, Valid: true}},
+	} {
+		params.ID = uuid.New()
+		params.PresetID = preset.ID
+		params.CreatedAt = now
+		_, err := db.InsertPrebuildClaimAttempt(sysCtx, params)
+		require.NoError(t, err)
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	req := codersdk.PrebuildClaimInsightsRequest{
+		StartTime: now.Truncate(time.Hour).Add(-time.Hour),
+		EndTime:   now.Truncate(time.Hour).Add(time.Hour),
+	}
+	resp, err := client.PrebuildClaimInsights(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Report.Presets, 1)
+	insight := resp.Report.Presets[0]
+	require.Equal(t, preset.ID, insight.PresetID)
+	require.Equal(t, r.Template.ID, insight.TemplateID)
+	require.EqualValues(t, 4, insight.ClaimAttempts)
+	require.EqualValues(t, 1, insight.Claimed)
+	require.InDelta(t, 0.25, insight.ClaimSuccessRate, 0.001)
+	require.Equal(t, map[codersdk.PrebuildClaimMissReason]int64{
+		codersdk.PrebuildClaimMissReasonReconciliationLag: 0,
+		codersdk.PrebuildClaimMissReasonCapacity:          2,
+		codersdk.PrebuildClaimMissReasonFailures:          1,
+	}, insight.Misses)
+
+	// Attempts outside the window are excluded.
+	resp, err = client.PrebuildClaimInsights(ctx, codersdk.PrebuildClaimInsightsRequest{
+		StartTime: req.StartTime.AddDate(0, 0, -2),
+		EndTime:   req.StartTime.AddDate(0, 0, -1),
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Report.Presets)
+
+	// Members can't view insights.
+	_, err = member.PrebuildClaimInsights(ctx, req)
+	require.Error(t, err)
+}
+
+func TestAutostopInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OwnerID:        owner.UserID,
+		OrganizationID: owner.OrganizationID,
+	}).Do()
+	ws := dbgen.Workspace(t, db, database.WorkspaceTable{
+		OwnerID:        owner.UserID,
+		OrganizationID: owner.OrganizationID,
+		TemplateID:     r.Template.ID,
+	})
+
+	y, m, d := time.Now().UTC().Date()
+	startTime := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -3)
+	endTime := startTime.AddDate(0, 0, 1)
+	for i, b := range []struct {
+		completedAt time.Time
+		transition  database.WorkspaceTransition
+		reason      database.BuildReason
+	}{
+		// Started before the report period, only the running time within
+		// the period counts.
+		{startTime.Add(-2 * time.Hour), database.WorkspaceTransitionStart, database.BuildReasonInitiator},
+		{startTime.Add(2 * time.Hour), database.WorkspaceTransitionStop, database.BuildReasonAutostop},
+		{startTime.Add(10 * time.Hour), database.WorkspaceTransitionStart, database.BuildReasonAutostart},
+		// Stopped until the end of the report period.
+		{startTime.Add(12 * time.Hour), database.WorkspaceTransitionStop, database.BuildReasonDormancy},
+	} {
+		job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: owner.OrganizationID,
+			StartedAt:      sql.NullTime{Time: b.completedAt.Add(-time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: b.completedAt, Valid: true},
+		})
+		dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       ws.ID,
+			TemplateVersionID: r.TemplateVersion.ID,
+			JobID:             job.ID,
+			BuildNumber:       int32(i) + 1,
+			Transition:        b.transition,
+			Reason:            b.reason,
+		})
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	req := codersdk.AutostopInsightsRequest{
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+	resp, err := client.AutostopInsights(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Report.Templates, 1)
+	insight := resp.Report.Templates[0]
+	require.Equal(t, r.Template.ID, insight.TemplateID)
+	require.InDelta(t, 4, insight.RunningHours, 0.001)
+	require.InDelta(t, 8, insight.AutostopHours, 0.001)
+	require.InDelta(t, 12, insight.DormancyHours, 0.001)
+	require.Zero(t, insight.FailureTTLHours)
+	require.InDelta(t, 20, insight.SavedHours, 0.001)
+	require.InDelta(t, 24, insight.WouldHaveRunHours, 0.001)
+	require.InDelta(t, 20.0/24.0, insight.SavedRatio, 0.001)
+	require.Len(t, resp.Report.Organizations, 1)
+	require.Equal(t, owner.OrganizationID, resp.Report.Organizations[0].OrganizationID)
+	require.Equal(t, insight.AutostopSavings, resp.Report.Organizations[0].AutostopSavings)
+
+	// Builds after the report period are excluded.
+	resp, err = client.AutostopInsights(ctx, codersdk.AutostopInsightsRequest{
+		StartTime: startTime.AddDate(0, 0, -2),
+		EndTime:   startTime.AddDate(0, 0, -1),
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Report.Templates)
+	require.Empty(t, resp.Report.Organizations)
+
+	// Members can't view insights.
+	_, err = member.AutostopInsights(ctx, req)
+	require.Error(t, err)
+}
+
+func TestActiveDeveloperDaysInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	// The same user is active in workspaces of two templates.
+	var workspaces []database.WorkspaceTable
+	for range 2 {
+		workspaces = append(workspaces, dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        owner.UserID,
+			OrganizationID: owner.OrganizationID,
+		}).Do().Workspace)
+	}
+	for _, ws := range workspaces {
+		dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+			CreatedAt:       dbtime.Now().Add(-time.Minute),
+			UserID:          ws.OwnerID,
+			TemplateID:      ws.TemplateID,
+			WorkspaceID:     ws.ID,
+			ConnectionCount: 1,
+			SessionCountSSH: 1,
+		})
+	}
+	//nolint:gocritic // Rolling up usage stats is a system operation.
+	err := db.UpsertTemplateUsageStats(dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitLong)))
+	require.NoError(t, err)
+
+	y, m, d := time.Now().UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	req := codersdk.ActiveDeveloperDaysInsightsRequest{
+		StartTime: today.AddDate(0, 0, -1),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour),
+	}
+	resp, err := client.ActiveDeveloperDaysInsights(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Report.Days, 2)
+	for _, day := range resp.Report.Days {
+		require.EqualValues(t, 1, day.ActiveDevelopers)
+	}
+	require.Len(t, resp.Report.Templates, 2)
+	for _, tpl := range resp.Report.Templates {
+		require.EqualValues(t, 1, tpl.ActiveDeveloperDays)
+	}
+	// The user is counted once for the organization.
+	require.Len(t, resp.Report.Organizations, 1)
+	require.Equal(t, owner.OrganizationID, resp.Report.Organizations[0].OrganizationID)
+	require.EqualValues(t, 1, resp.Report.Organizations[0].ActiveDeveloperDays)
+
+	body, err := client.ActiveDeveloperDaysInsightsCSV(ctx, req)
+	require.NoError(t, err)
+	defer body.Close()
+	records, err := csv.NewReader(body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, "active_developers", records[0][5])
+	require.Equal(t, "1", records[1][5])
+
+	// A single minute of usage is not enough when more is required.
+	req.MinActiveMinutes = 5
+	resp, err = client.ActiveDeveloperDaysInsights(ctx, req)
+	require.NoError(t, err)
+	require.Empty(t, resp.Report.Days)
+	require.Empty(t, resp.Report.Organizations)
+}
+
 func TestTemplateInsights_Golden(t *testing.T) {
 	t.Parallel()
 
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt" //#nosec // this is only used for shuffling an array to pick random jobs to unhang
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,7 +15,9 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
 )
 
@@ -35,9 +38,20 @@ const (
 	// time after failing to send an update to the job.
 	HungJobExitTimeout = 3 * time.Minute
 
+	// OrphanedJobDuration is the duration of time since the last update to a
+	// RUNNING job, and since the last heartbeat of the provisioner daemon
+	// running it, before the job is considered orphaned. Provisioner daemons
+	// heartbeat every minute, so this tolerates a single missed heartbeat.
+	OrphanedJobDuration = 2 * time.Minute
+
 	// MaxJobsPerRun is the maximum number of hung jobs that the detector will
 	// terminate in a single run.
 	MaxJobsPerRun = 10
+
+	// MaxJobRequeues is the number of times a reaped workspace build is
+	// requeued before it is terminated. Requeueing only happens for templates
+	// that opt in.
+	MaxJobRequeues = 1
 )
 
 // jobLogMessages are written to provisioner job logs when a job is reaped
@@ -51,6 +65,32 @@ func JobLogMessages(reapType ReapType, threshold time.Duration) []string {
 	}
 }
 
+// RequeueLogMessages are written to provisioner job logs when a job is
+// reaped and returned to the queue instead of being terminated.
+func RequeueLogMessages(reapType ReapType, threshold time.Duration) []string {
+	return []string{
+		"",
+		"====================",
+		fmt.Sprintf("Coder: Build has been detected as %s for %.0f minutes and will be requeued.", reapType, threshold.Minutes()),
+		"====================",
+		"",
+	}
+}
+
+// countRequeues returns the number of times the reaper has requeued the job
+// according to its logs.
+func countRequeues(logs []database.ProvisionerJobLog) int {
+	count := 0
+	for _, line := range logs {
+		if line.Source == database.LogSourceProvisionerDaemon &&
+			strings.HasPrefix(line.Output, "Coder: Build has been detected as ") &&
+			strings.HasSuffix(line.Output, " and will be requeued.") {
+			count++
+		}
+	}
+	return count
+}
+
 type jobToReap struct {
 	ID        uuid.UUID
 	Threshold time.Duration
@@ -62,8 +102,24 @@ type ReapType string
 const (
 	Pending ReapType = "pending"
 	Hung    ReapType = "hung"
+	// Orphaned jobs are running on a provisioner daemon that has stopped
+	// sending heartbeats.
+	Orphaned ReapType = "orphaned"
 )
 
+// ErrorCode returns the error code a job terminated for this reason is
+// marked with.
+func (t ReapType) ErrorCode() codersdk.JobErrorCode {
+	switch t {
+	case Pending:
+		return codersdk.JobPendingTimeout
+	case Orphaned:
+		return codersdk.ProvisionerLost
+	default:
+		return codersdk.JobHung
+	}
+}
+
 // acquireLockError is returned when the detector fails to acquire a lock and
 // cancels the current run.
 type acquireLockError struct{}
@@ -103,6 +159,9 @@ type Stats struct {
 	// TerminatedJobIDs contains the IDs of all jobs that were detected as hung and
 	// terminated.
 	TerminatedJobIDs []uuid.UUID
+	// RequeuedJobIDs contains the IDs of all jobs that were detected as hung or
+	// orphaned and returned to the queue.
+	RequeuedJobIDs []uuid.UUID
 	// Error is the fatal error that occurred during the last run of the
 	// detector, if any. Error may be set to AcquireLockError if the detector
 	// failed to acquire a lock.
@@ -185,14 +244,16 @@ func (d *Detector) run(t time.Time) Stats {
 
 	stats := Stats{
 		TerminatedJobIDs: []uuid.UUID{},
+		RequeuedJobIDs:   []uuid.UUID{},
 		Error:            nil,
 	}
 
 	// Find all provisioner jobs to be reaped
 	jobs, err := d.db.GetProvisionerJobsToBeReaped(ctx, database.GetProvisionerJobsToBeReapedParams{
-		PendingSince: t.Add(-PendingJobDuration),
-		HungSince:    t.Add(-HungJobDuration),
-		MaxJobs:      MaxJobsPerRun,
+		PendingSince:  t.Add(-PendingJobDuration),
+		HungSince:     t.Add(-HungJobDuration),
+		OrphanedSince: t.Add(-OrphanedJobDuration),
+		MaxJobs:       MaxJobsPerRun,
 	})
 	if err != nil {
 		stats.Error = xerrors.Errorf("get provisioner jobs to be reaped: %w", err)
@@ -205,12 +266,18 @@ func (d *Detector) run(t time.Time) Stats {
 		j := &jobToReap{
 			ID: job.ID,
 		}
-		if job.JobStatus == database.ProvisionerJobStatusPending {
+		switch {
+		case job.JobStatus == database.ProvisionerJobStatusPending:
 			j.Threshold = PendingJobDuration
 			j.Type = Pending
-		} else {
+		case job.UpdatedAt.Before(t.Add(-HungJobDuration)):
 			j.Threshold = HungJobDuration
 			j.Type = Hung
+		default:
+			// The job was only returned because its provisioner daemon
+			// stopped sending heartbeats.
+			j.Threshold = OrphanedJobDuration
+			j.Type = Orphaned
 		}
 		jobsToReap = append(jobsToReap, j)
 	}
@@ -220,7 +287,7 @@ func (d *Detector) run(t time.Time) Stats {
 	for _, job := range jobsToReap {
 		log := d.log.With(slog.F("job_id", job.ID))
 
-		err := reapJob(ctx, log, d.db, d.pubsub, job)
+		requeued, err := reapJob(ctx, log, d.db, d.pubsub, job)
 		if err != nil {
 			if !(xerrors.As(err, &acquireLockError{}) || xerrors.As(err, &jobIneligibleError{})) {
 				log.Error(ctx, "error forcefully terminating provisioner job", slog.F("type", job.Type), slog.Error(err))
@@ -228,14 +295,25 @@ func (d *Detector) run(t time.Time) Stats {
 			continue
 		}
 
+		if requeued {
+			stats.RequeuedJobIDs = append(stats.RequeuedJobIDs, job.ID)
+			continue
+		}
 		stats.TerminatedJobIDs = append(stats.TerminatedJobIDs, job.ID)
 	}
 
 	return stats
 }
 
-func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub.Pubsub, jobToReap *jobToReap) error {
-	var lowestLogID int64
+// reapJob terminates the job, or returns it to the queue if the template of
+// the workspace build opts in to requeueing. It reports whether the job was
+// requeued.
+func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub.Pubsub, jobToReap *jobToReap) (bool, error) {
+	var (
+		lowestLogID int64
+		requeued    bool
+		reapedJob   database.ProvisionerJob
+	)
 
 	err := db.InTx(func(db database.Store) error {
 		// Refetch the job while we hold the lock.
@@ -258,11 +336,7 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 			}
 		}
 
-		log.Warn(
-			ctx, "forcefully terminating provisioner job",
-			slog.F("type", jobToReap.Type),
-			slog.F("threshold", jobToReap.Threshold),
-		)
+		reapedJob = job
 
 		// First, get the latest logs from the build so we can make sure
 		// our messages are in the latest stage.
@@ -273,6 +347,27 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 		if err != nil {
 			return xerrors.Errorf("get logs for %s job: %w", jobToReap.Type, err)
 		}
+
+		// Only workspace builds that were picked up by a provisioner are
+		// requeued. Pending jobs are already in the queue, and canceled jobs
+		// should not run again.
+		if job.Type == database.ProvisionerJobTypeWorkspaceBuild &&
+			jobToReap.Type != Pending &&
+			!job.CanceledAt.Valid &&
+			countRequeues(logs) < MaxJobRequeues {
+			requeued, err = requeueEnabled(ctx, db, job.ID)
+			if err != nil {
+				return xerrors.Errorf("check requeue enabled: %w", err)
+			}
+		}
+
+		log.Warn(
+			ctx, "forcefully terminating provisioner job",
+			slog.F("type", jobToReap.Type),
+			slog.F("threshold", jobToReap.Threshold),
+			slog.F("requeue", requeued),
+		)
+
 		logStage := ""
 		if len(logs) != 0 {
 			logStage = logs[len(logs)-1].Stage
@@ -290,8 +385,12 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 			Stage:     nil,
 			Output:    nil,
 		}
+		messages := JobLogMessages(jobToReap.Type, jobToReap.Threshold)
+		if requeued {
+			messages = RequeueLogMessages(jobToReap.Type, jobToReap.Threshold)
+		}
 		now := dbtime.Now()
-		for i, msg := range JobLogMessages(jobToReap.Type, jobToReap.Threshold) {
+		for i, msg := range messages {
 			// Set the created at in a way that ensures each message has
 			// a unique timestamp so they will be sorted correctly.
 			insertParams.CreatedAt = append(insertParams.CreatedAt, now.Add(time.Millisecond*time.Duration(i)))
@@ -306,6 +405,17 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 		}
 		lowestLogID = newLogs[0].ID
 
+		if requeued {
+			err = db.UpdateProvisionerJobWithRequeueByID(ctx, database.UpdateProvisionerJobWithRequeueByIDParams{
+				ID:        job.ID,
+				UpdatedAt: dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("requeue job: %w", err)
+			}
+			return nil
+		}
+
 		// Mark the job as failed.
 		now = dbtime.Now()
 
@@ -329,7 +439,8 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 				Valid:  true,
 			},
 			ErrorCode: sql.NullString{
-				Valid: false,
+				String: string(jobToReap.Type.ErrorCode()),
+				Valid:  true,
 			},
 			StartedAt: job.StartedAt,
 		})
@@ -381,22 +492,51 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 		return nil
 	}, nil)
 	if err != nil {
-		return xerrors.Errorf("in tx: %w", err)
+		return false, xerrors.Errorf("in tx: %w", err)
 	}
 
 	// Publish the new log notification to pubsub. Use the lowest log ID
 	// inserted so the log stream will fetch everything after that point.
+	// Requeued jobs keep streaming logs once another provisioner acquires
+	// them.
 	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{
 		CreatedAfter: lowestLogID - 1,
-		EndOfLogs:    true,
+		EndOfLogs:    !requeued,
 	})
 	if err != nil {
-		return xerrors.Errorf("marshal log notification: %w", err)
+		return false, xerrors.Errorf("marshal log notification: %w", err)
 	}
 	err = pub.Publish(provisionersdk.ProvisionerJobLogsNotifyChannel(jobToReap.ID), data)
 	if err != nil {
-		return xerrors.Errorf("publish log notification: %w", err)
+		return false, xerrors.Errorf("publish log notification: %w", err)
 	}
 
-	return nil
+	if requeued {
+		// Wake up idle provisioner daemons so the job is picked up without
+		// waiting for their next poll.
+		err = provisionerjobs.PostJob(pub, reapedJob)
+		if err != nil {
+			return false, xerrors.Errorf("post requeued job: %w", err)
+		}
+	}
+
+	return requeued, nil
+}
+
+// requeueEnabled reports whether the template of the workspace build run by
+// the job opts in to requeueing reaped builds.
+func requeueEnabled(ctx context.Context, db database.Store, jobID uuid.UUID) (bool, error) {
+	build, err := db.GetWorkspaceBuildByJobID(ctx, jobID)
+	if err != nil {
+		return false, xerrors.Errorf("get workspace build by job id: %w", err)
+	}
+	workspace, err := db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return false, xerrors.Errorf("get workspace: %w", err)
+	}
+	template, err := db.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		return false, xerrors.Errorf("get template: %w", err)
+	}
+	return template.RequeueReapedBuilds, nil
 }
@@ -98,7 +98,8 @@ func requireTerminatedJob(ctx context.Context, t *testing.T, db database.Store,
 	}
 	require.True(t, job.Error.Valid)
 	require.Contains(t, job.Error.String, fmt.Sprintf("Build has been detected as %s", reapType))
-	require.False(t, job.ErrorCode.Valid)
+	require.True(t, job.ErrorCode.Valid)
+	require.Equal(t, string(reapType.ErrorCode()), job.ErrorCode.String)
 }
 
 func TestDetectorNoJobs(t *testing.T) {
@@ -601,6 +602,137 @@ func TestDetectorHungCanceledJob(t *testing.T) {
 	requireTerminatedJob(ctx, t, env.DB, templateImportJob.ID, now, jobreaper.Hung)
 }
 
+func TestDetectorOrphanedJob(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	env := newDetectorTestEnv(ctx, t)
+	defer env.close()
+
+	var (
+		now          = time.Now()
+		tenMinAgo    = now.Add(-time.Minute * 10)
+		threeMinAgo  = now.Add(-time.Minute * 3)
+		org          = dbgen.Organization(t, env.DB, database.Organization{})
+		user         = dbgen.User(t, env.DB, database.User{})
+		file         = dbgen.File(t, env.DB, database.File{})
+		staleDaemon  = dbgen.ProvisionerDaemon(t, env.DB, database.ProvisionerDaemon{OrganizationID: org.ID, LastSeenAt: sql.NullTime{Time: tenMinAgo, Valid: true}})
+		activeDaemon = dbgen.ProvisionerDaemon(t, env.DB, database.ProvisionerDaemon{OrganizationID: org.ID, LastSeenAt: sql.NullTime{Time: now, Valid: true}})
+	)
+
+	// Both jobs were last updated recently enough not to be considered hung,
+	// but only one of them is running on a daemon that is still alive.
+	jobs := make(map[uuid.UUID]database.ProvisionerJob)
+	for _, daemon := range []database.ProvisionerDaemon{staleDaemon, activeDaemon} {
+		job := dbgen.ProvisionerJob(t, env.DB, env.Pubsub, database.ProvisionerJob{
+			CreatedAt: tenMinAgo,
+			UpdatedAt: threeMinAgo,
+			StartedAt: sql.NullTime{
+				Time:  tenMinAgo,
+				Valid: true,
+			},
+			WorkerID:       uuid.NullUUID{UUID: daemon.ID, Valid: true},
+			OrganizationID: org.ID,
+			InitiatorID:    user.ID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			Input:          []byte("{}"),
+		})
+		jobs[daemon.ID] = job
+	}
+
+	stats := env.tick(ctx, now)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{jobs[staleDaemon.ID].ID}, stats.TerminatedJobIDs)
+	requireTerminatedJob(ctx, t, env.DB, jobs[staleDaemon.ID].ID, now, jobreaper.Orphaned)
+
+	job, err := env.DB.GetProvisionerJobByID(ctx, jobs[activeDaemon.ID].ID)
+	require.NoError(t, err)
+	require.False(t, job.CompletedAt.Valid)
+}
+
+func TestDetectorRequeueWorkspaceBuild(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	env := newDetectorTestEnv(ctx, t)
+	defer env.close()
+
+	var (
+		now       = time.Now()
+		tenMinAgo = now.Add(-time.Minute * 10)
+		sixMinAgo = now.Add(-time.Minute * 6)
+		org       = dbgen.Organization(t, env.DB, database.Organization{})
+		user      = dbgen.User(t, env.DB, database.User{})
+	)
+
+	build := dbfake.WorkspaceBuild(t, env.DB, database.WorkspaceTable{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	}).Pubsub(env.Pubsub).
+		Starting(dbfake.WithJobStartedAt(tenMinAgo), dbfake.WithJobUpdatedAt(sixMinAgo)).
+		Do()
+
+	template, err := env.DB.GetTemplateByID(ctx, build.Workspace.TemplateID)
+	require.NoError(t, err)
+	err = env.DB.UpdateTemplateMetaByID(ctx, database.UpdateTemplateMetaByIDParams{
+		ID:                  template.ID,
+		UpdatedAt:           now,
+		Name:                template.Name,
+		GroupACL:            template.GroupACL,
+		MaxPortSharingLevel: template.MaxPortSharingLevel,
+		CorsBehavior:        template.CorsBehavior,
+		RequeueReapedBuilds: true,
+	})
+	require.NoError(t, err)
+
+	// The first reap returns the job to the queue.
+	stats := env.tick(ctx, now)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.TerminatedJobIDs)
+	require.Equal(t, []uuid.UUID{build.Build.JobID}, stats.RequeuedJobIDs)
+
+	job, err := env.DB.GetProvisionerJobByID(ctx, build.Build.JobID)
+	require.NoError(t, err)
+	require.Equal(t, database.ProvisionerJobStatusPending, job.JobStatus)
+	require.False(t, job.StartedAt.Valid)
+	require.False(t, job.WorkerID.Valid)
+
+	logs, err := env.DB.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{JobID: job.ID})
+	require.NoError(t, err)
+	outputs := make([]string, 0, len(logs))
+	for _, log := range logs {
+		outputs = append(outputs, log.Output)
+	}
+	require.Subset(t, outputs, jobreaper.RequeueLogMessages(jobreaper.Hung, jobreaper.HungJobDuration))
+
+	// Another provisioner acquires the job and hangs as well. The job has
+	// already been requeued once, so this time it is terminated.
+	tags, err := json.Marshal(job.Tags)
+	require.NoError(t, err)
+	_, err = env.DB.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+		OrganizationID:  org.ID,
+		StartedAt:       sql.NullTime{Time: tenMinAgo, Valid: true},
+		WorkerID:        uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		Types:           []database.ProvisionerType{database.ProvisionerTypeEcho},
+		ProvisionerTags: tags,
+	})
+	require.NoError(t, err)
+	err = env.DB.UpdateProvisionerJobByID(ctx, database.UpdateProvisionerJobByIDParams{
+		ID:        job.ID,
+		UpdatedAt: sixMinAgo,
+	})
+	require.NoError(t, err)
+
+	stats = env.tick(ctx, now)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.RequeuedJobIDs)
+	require.Equal(t, []uuid.UUID{build.Build.JobID}, stats.TerminatedJobIDs)
+	requireTerminatedJob(ctx, t, env.DB, build.Build.JobID, now, jobreaper.Hung)
+}
+
 func TestDetectorPushesLogs(t *testing.T) {
 	t.Parallel()
 
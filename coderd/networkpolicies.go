@@ -0,0 +1,512 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+const (
+	// networkPolicyViolationHealthWindow is how far back violations are
+	// counted when computing agent health.
+	networkPolicyViolationHealthWindow = 15 * time.Minute
+	// networkPolicyViolationsWindow is how far back violations are listed by
+	// the workspace violations endpoint.
+	networkPolicyViolationsWindow = 24 * time.Hour
+	// maxNetworkPolicyEntries caps the number of domains and CIDRs of a
+	// single policy.
+	maxNetworkPolicyEntries = 256
+	// maxNetworkPolicyViolationsPerRequest caps the number of violations an
+	// agent can report at once.
+	maxNetworkPolicyViolationsPerRequest = 100
+)
+
+// networkPolicyDomainRegex matches a lowercase hostname, optionally prefixed
+// with "*." to match any subdomain.
+var networkPolicyDomainRegex = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// @Summary Get template network policy
+// @ID get-template-network-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateNetworkPolicy
+// @Router /api/v2/templates/{template}/network-policy [get]
+func (api *API) templateNetworkPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	np, err := api.Database.GetTemplateNetworkPolicy(ctx, template.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+				Message: "Template does not declare a network policy.",
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateNetworkPolicy(np))
+}
+
+// @Summary Update template network policy
+// @ID update-template-network-policy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.UpdateNetworkPolicyRequest true "Network policy request"
+// @Success 200 {object} codersdk.TemplateNetworkPolicy
+// @Router /api/v2/templates/{template}/network-policy [put]
+func (api *API) putTemplateNetworkPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	var req codersdk.UpdateNetworkPolicyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	domains, cidrs, validations := normalizeNetworkPolicy(req)
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid network policy.",
+			Validations: validations,
+		})
+		return
+	}
+
+	np, err := api.Database.UpsertTemplateNetworkPolicy(ctx, database.UpsertTemplateNetworkPolicyParams{
+		TemplateID:     template.ID,
+		AllowedDomains: domains,
+		AllowedCIDRs:   cidrs,
+		UpdatedAt:      dbtime.Now(),
+	})
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateNetworkPolicy(np))
+}
+
+// @Summary Delete template network policy
+// @ID delete-template-network-policy
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 204
+// @Router /api/v2/templates/{template}/network-policy [delete]
+func (api *API) deleteTemplateNetworkPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	err := api.Database.DeleteTemplateNetworkPolicy(ctx, template.ID)
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get workspace network policy
+// @ID get-workspace-network-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceNetworkPolicy
+// @Router /api/v2/workspaces/{workspace}/network-policy [get]
+func (api *API) workspaceNetworkPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	np, err := api.resolveWorkspaceNetworkPolicy(ctx, workspace)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, np)
+}
+
+// @Summary Update workspace network policy
+// @ID update-workspace-network-policy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateNetworkPolicyRequest true "Network policy request"
+// @Success 200 {object} codersdk.WorkspaceNetworkPolicy
+// @Router /api/v2/workspaces/{workspace}/network-policy [put]
+func (api *API) putWorkspaceNetworkPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.UpdateNetworkPolicyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	domains, cidrs, validations := normalizeNetworkPolicy(req)
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid network policy.",
+			Validations: validations,
+		})
+		return
+	}
+
+	np, err := api.Database.UpsertWorkspaceNetworkPolicy(ctx, database.UpsertWorkspaceNetworkPolicyParams{
+		WorkspaceID:    workspace.ID,
+		AllowedDomains: domains,
+		AllowedCIDRs:   cidrs,
+		UpdatedAt:      dbtime.Now(),
+	})
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Only template administrators can override the network policy of a workspace.",
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceNetworkPolicy(np))
+}
+
+// @Summary Delete workspace network policy
+// @ID delete-workspace-network-policy
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 204
+// @Router /api/v2/workspaces/{workspace}/network-policy [delete]
+func (api *API) deleteWorkspaceNetworkPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	err := api.Database.DeleteWorkspaceNetworkPolicy(ctx, workspace.ID)
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Only template administrators can override the network policy of a workspace.",
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get workspace network policy violations
+// @ID get-workspace-network-policy-violations
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceAgentNetworkPolicyViolation
+// @Router /api/v2/workspaces/{workspace}/network-policy/violations [get]
+func (api *API) workspaceNetworkPolicyViolations(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	agentIDs := make([]uuid.UUID, 0, len(agents))
+	for _, agent := range agents {
+		agentIDs = append(agentIDs, agent.ID)
+	}
+
+	// nolint:gocritic // The workspace has been authorized by the middleware.
+	violations, err := api.Database.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDs(dbauthz.AsSystemRestricted(ctx), database.GetWorkspaceAgentNetworkPolicyViolationsByAgentIDsParams{
+		IDs:          agentIDs,
+		CreatedAfter: dbtime.Now().Add(-networkPolicyViolationsWindow),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	apiViolations := make([]codersdk.WorkspaceAgentNetworkPolicyViolation, 0, len(violations))
+	for _, violation := range violations {
+		apiViolations = append(apiViolations, codersdk.WorkspaceAgentNetworkPolicyViolation{
+			ID:          violation.ID,
+			AgentID:     violation.WorkspaceAgentID,
+			CreatedAt:   violation.CreatedAt,
+			Destination: violation.Destination,
+			Port:        violation.Port,
+			Count:       violation.Count,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiViolations)
+}
+
+// @Summary Get workspace agent network policy
+// @ID get-workspace-agent-network-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {object} codersdk.WorkspaceNetworkPolicy
+// @Router /api/v2/workspaceagents/me/network-policy [get]
+func (api *API) workspaceAgentNetworkPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	np, err := api.resolveWorkspaceNetworkPolicy(ctx, workspace)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, np)
+}
+
+// @Summary Post workspace agent network policy violations
+// @ID post-workspace-agent-network-policy-violations
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostNetworkPolicyViolationsRequest true "Network policy violations request"
+// @Success 204
+// @Router /api/v2/workspaceagents/me/network-policy-violations [post]
+func (api *API) workspaceAgentPostNetworkPolicyViolations(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req agentsdk.PostNetworkPolicyViolationsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if len(req.Violations) > maxNetworkPolicyViolationsPerRequest {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid network policy violations.",
+			Validations: []codersdk.ValidationError{
+				{Field: "violations", Detail: fmt.Sprintf("Must not contain more than %d violations.", maxNetworkPolicyViolationsPerRequest)},
+			},
+		})
+		return
+	}
+	if len(req.Violations) == 0 {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	arg := database.InsertWorkspaceAgentNetworkPolicyViolationsParams{
+		WorkspaceAgentID: workspaceAgent.ID,
+		CreatedAt:        dbtime.Now(),
+	}
+	for i, violation := range req.Violations {
+		if violation.Port < 0 || violation.Port > 65535 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid network policy violations.",
+				Validations: []codersdk.ValidationError{
+					{Field: fmt.Sprintf("violations[%d].port", i), Detail: "Must be between 0 and 65535."},
+				},
+			})
+			return
+		}
+		if violation.Count < 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid network policy violations.",
+				Validations: []codersdk.ValidationError{
+					{Field: fmt.Sprintf("violations[%d].count", i), Detail: "Must not be negative."},
+				},
+			})
+			return
+		}
+		count := violation.Count
+		if count == 0 {
+			count = 1
+		}
+		arg.ID = append(arg.ID, uuid.New())
+		arg.Destination = append(arg.Destination, violation.Destination)
+		arg.Port = append(arg.Port, violation.Port)
+		arg.Count = append(arg.Count, count)
+	}
+
+	err := api.Database.InsertWorkspaceAgentNetworkPolicyViolations(ctx, arg)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	// Violations affect the agent health, so watchers need to refresh.
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindAgentNetworkPolicyViolation,
+		WorkspaceID: workspace.ID,
+		AgentID:     &workspaceAgent.ID,
+	})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// resolveWorkspaceNetworkPolicy returns the effective network policy of a
+// workspace. The workspace policy takes precedence over the template policy.
+func (api *API) resolveWorkspaceNetworkPolicy(ctx context.Context, workspace database.Workspace) (codersdk.WorkspaceNetworkPolicy, error) {
+	wnp, err := api.Database.GetWorkspaceNetworkPolicy(ctx, workspace.ID)
+	if err == nil {
+		return convertWorkspaceNetworkPolicy(wnp), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.WorkspaceNetworkPolicy{}, xerrors.Errorf("get workspace network policy: %w", err)
+	}
+
+	// Reading the workspace is enough to know the policy that applies to it,
+	// even if the caller cannot read the template.
+	// nolint:gocritic // The workspace has been authorized by the caller.
+	tnp, err := api.Database.GetTemplateNetworkPolicy(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err == nil {
+		return codersdk.WorkspaceNetworkPolicy{
+			WorkspaceID:    workspace.ID,
+			Source:         codersdk.NetworkPolicySourceTemplate,
+			AllowedDomains: tnp.AllowedDomains,
+			AllowedCIDRs:   tnp.AllowedCIDRs,
+			UpdatedAt:      ptr.Ref(tnp.UpdatedAt),
+		}, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.WorkspaceNetworkPolicy{}, xerrors.Errorf("get template network policy: %w", err)
+	}
+
+	return codersdk.WorkspaceNetworkPolicy{
+		WorkspaceID:    workspace.ID,
+		Source:         codersdk.NetworkPolicySourceNone,
+		AllowedDomains: []string{},
+		AllowedCIDRs:   []string{},
+	}, nil
+}
+
+// normalizeNetworkPolicy validates the domains and CIDRs of a policy and
+// returns them lowercased, canonicalized and deduplicated.
+func normalizeNetworkPolicy(req codersdk.UpdateNetworkPolicyRequest) ([]string, []string, []codersdk.ValidationError) {
+	var validations []codersdk.ValidationError
+	if len(req.AllowedDomains) > maxNetworkPolicyEntries {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "allowed_domains",
+			Detail: fmt.Sprintf("Must not contain more than %d domains.", maxNetworkPolicyEntries),
+		})
+	}
+	if len(req.AllowedCIDRs) > maxNetworkPolicyEntries {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "allowed_cidrs",
+			Detail: fmt.Sprintf("Must not contain more than %d CIDRs.", maxNetworkPolicyEntries),
+		})
+	}
+	if len(validations) > 0 {
+		return nil, nil, validations
+	}
+
+	domains := make([]string, 0, len(req.AllowedDomains))
+	seen := make(map[string]struct{})
+	for i, domain := range req.AllowedDomains {
+		domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+		if len(domain) > 253 || !networkPolicyDomainRegex.MatchString(domain) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  fmt.Sprintf("allowed_domains[%d]", i),
+				Detail: fmt.Sprintf("%q is not a valid hostname or \"*.\" wildcard.", req.AllowedDomains[i]),
+			})
+			continue
+		}
+		if _, ok := seen[domain]; ok {
+			continue
+		}
+		seen[domain] = struct{}{}
+		domains = append(domains, domain)
+	}
+
+	cidrs := make([]string, 0, len(req.AllowedCIDRs))
+	clear(seen)
+	for i, cidr := range req.AllowedCIDRs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  fmt.Sprintf("allowed_cidrs[%d]", i),
+				Detail: fmt.Sprintf("%q is not a valid CIDR.", cidr),
+			})
+			continue
+		}
+		canonical := prefix.Masked().String()
+		if _, ok := seen[canonical]; ok {
+			continue
+		}
+		seen[canonical] = struct{}{}
+		cidrs = append(cidrs, canonical)
+	}
+
+	return domains, cidrs, validations
+}
+
+// networkPolicyViolationsHealthReason explains why an agent that reported
+// network policy violations is unhealthy.
+func networkPolicyViolationsHealthReason(violations int64) string {
+	return fmt.Sprintf("agent reported %d outbound network policy violations in the last %d minutes", violations, int(networkPolicyViolationHealthWindow.Minutes()))
+}
+
+func convertTemplateNetworkPolicy(np database.TemplateNetworkPolicy) codersdk.TemplateNetworkPolicy {
+	return codersdk.TemplateNetworkPolicy{
+		TemplateID:     np.TemplateID,
+		AllowedDomains: np.AllowedDomains,
+		AllowedCIDRs:   np.AllowedCIDRs,
+		UpdatedAt:      np.UpdatedAt,
+	}
+}
+
+func convertWorkspaceNetworkPolicy(np database.WorkspaceNetworkPolicy) codersdk.WorkspaceNetworkPolicy {
+	return codersdk.WorkspaceNetworkPolicy{
+		WorkspaceID:    np.WorkspaceID,
+		Source:         codersdk.NetworkPolicySourceWorkspace,
+		AllowedDomains: np.AllowedDomains,
+		AllowedCIDRs:   np.AllowedCIDRs,
+		UpdatedAt:      ptr.Ref(np.UpdatedAt),
+	}
+}
@@ -0,0 +1,171 @@
+package coderd_test
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNetworkPolicy(t *testing.T) {
+	t.Parallel()
+
+	ownerClient, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	agentClient := agentsdk.New(ownerClient.URL, agentsdk.WithFixedToken(r.AgentToken))
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Outbound traffic is not restricted until a policy is declared.
+	np, err := client.WorkspaceNetworkPolicy(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.NetworkPolicySourceNone, np.Source)
+	require.Nil(t, np.UpdatedAt)
+
+	_, err = ownerClient.TemplateNetworkPolicy(ctx, r.Workspace.TemplateID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	// Invalid domains and CIDRs are rejected.
+	_, err = ownerClient.UpdateTemplateNetworkPolicy(ctx, r.Workspace.TemplateID, codersdk.UpdateNetworkPolicyRequest{
+		AllowedDomains: []string{"github.com", "not a domain"},
+		AllowedCIDRs:   []string{"10.0.0.0/33"},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Len(t, apiErr.Validations, 2)
+
+	tnp, err := ownerClient.UpdateTemplateNetworkPolicy(ctx, r.Workspace.TemplateID, codersdk.UpdateNetworkPolicyRequest{
+		AllowedDomains: []string{"GitHub.com", "*.npmjs.org", "github.com"},
+		AllowedCIDRs:   []string{"10.1.2.3/8"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"github.com", "*.npmjs.org"}, tnp.AllowedDomains)
+	require.Equal(t, []string{"10.0.0.0/8"}, tnp.AllowedCIDRs)
+
+	// The template policy applies to the workspace.
+	np, err = client.WorkspaceNetworkPolicy(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.NetworkPolicySourceTemplate, np.Source)
+	require.Equal(t, tnp.AllowedDomains, np.AllowedDomains)
+
+	// Workspace owners cannot loosen their own policy.
+	_, err = client.UpdateWorkspaceNetworkPolicy(ctx, r.Workspace.ID, codersdk.UpdateNetworkPolicyRequest{
+		AllowedCIDRs: []string{"0.0.0.0/0"},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	np, err = ownerClient.UpdateWorkspaceNetworkPolicy(ctx, r.Workspace.ID, codersdk.UpdateNetworkPolicyRequest{
+		AllowedDomains: []string{"github.com"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.NetworkPolicySourceWorkspace, np.Source)
+	require.Empty(t, np.AllowedCIDRs)
+
+	// Enforcement agents read the effective policy through the agent API.
+	np, err = agentClient.NetworkPolicy(ctx)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.NetworkPolicySourceWorkspace, np.Source)
+	require.Equal(t, []string{"github.com"}, np.AllowedDomains)
+
+	// Removing the override falls back to the template policy.
+	err = ownerClient.DeleteWorkspaceNetworkPolicy(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	np, err = agentClient.NetworkPolicy(ctx)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.NetworkPolicySourceTemplate, np.Source)
+
+	err = ownerClient.DeleteTemplateNetworkPolicy(ctx, r.Workspace.TemplateID)
+	require.NoError(t, err)
+	np, err = agentClient.NetworkPolicy(ctx)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.NetworkPolicySourceNone, np.Source)
+}
+
+func TestWorkspaceAgentNetworkPolicyViolations(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	agentClient := agentsdk.New(client.URL, agentsdk.WithFixedToken(r.AgentToken))
+	agentID := r.Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Mark the agent as connected and ready so it would otherwise be healthy.
+	now := dbtime.Now()
+	err := db.UpdateWorkspaceAgentConnectionByID(ctx, database.UpdateWorkspaceAgentConnectionByIDParams{
+		ID:               agentID,
+		FirstConnectedAt: sql.NullTime{Time: now, Valid: true},
+		LastConnectedAt:  sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:        now,
+	})
+	require.NoError(t, err)
+	err = db.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+		ID:             agentID,
+		LifecycleState: database.WorkspaceAgentLifecycleStateReady,
+		StartedAt:      sql.NullTime{Time: now, Valid: true},
+		ReadyAt:        sql.NullTime{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.True(t, workspace.Health.Healthy)
+
+	// Ports must be valid.
+	err = agentClient.PostNetworkPolicyViolations(ctx, agentsdk.PostNetworkPolicyViolationsRequest{
+		Violations: []agentsdk.NetworkPolicyViolation{{Destination: "example.com", Port: 70000}},
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	err = agentClient.PostNetworkPolicyViolations(ctx, agentsdk.PostNetworkPolicyViolationsRequest{
+		Violations: []agentsdk.NetworkPolicyViolation{
+			{Destination: "example.com", Port: 443, Count: 3},
+			{Destination: "203.0.113.7"},
+		},
+	})
+	require.NoError(t, err)
+
+	violations, err := client.WorkspaceNetworkPolicyViolations(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+	for _, violation := range violations {
+		require.Equal(t, agentID, violation.AgentID)
+		if violation.Destination == "203.0.113.7" {
+			require.EqualValues(t, 1, violation.Count)
+		}
+	}
+
+	// Recent violations mark the agent and the workspace as unhealthy.
+	workspace, err = client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.False(t, workspace.Health.Healthy)
+	require.Equal(t, []uuid.UUID{agentID}, workspace.Health.FailingAgents)
+	agent := workspace.LatestBuild.Resources[0].Agents[0]
+	require.False(t, agent.Health.Healthy)
+	require.Contains(t, agent.Health.Reason, "4 outbound network policy violations")
+}
@@ -342,6 +342,174 @@ func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.R
 	httpapi.Write(ctx, rw, http.StatusOK, out)
 }
 
+// @Summary Get user scoped notification preferences
+// @ID get-user-scoped-notification-preferences
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.ScopedNotificationPreference
+// @Router /api/v2/users/{user}/notification-preferences [get]
+func (api *API) userScopedNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	prefs, err := api.Database.GetUserNotificationScopedPreferences(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to retrieve user scoped notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertScopedNotificationPreferences(prefs))
+}
+
+// @Summary Update user scoped notification preferences
+// @ID update-user-scoped-notification-preferences
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Notifications
+// @Param request body codersdk.UpdateScopedNotificationPreferences true "Preferences"
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.ScopedNotificationPreference
+// @Router /api/v2/users/{user}/notification-preferences [put]
+func (api *API) putUserScopedNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		logger = api.Logger.Named("notifications.preferences").With(slog.F("user_id", user.ID))
+	)
+
+	var req codersdk.UpdateScopedNotificationPreferences
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	type preferenceKey struct {
+		notificationTemplateID uuid.UUID
+		targetID               uuid.UUID
+	}
+	var (
+		seen   = make(map[preferenceKey]struct{}, len(req.Preferences))
+		upsert = database.UpsertUserNotificationScopedPreferencesParams{UserID: user.ID}
+		remove = database.DeleteUserNotificationScopedPreferencesParams{UserID: user.ID}
+	)
+	for i, pref := range req.Preferences {
+		field := fmt.Sprintf("preferences[%d]", i)
+		scope := database.NotificationPreferenceScope(pref.Scope)
+		if !scope.Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid notification preference.",
+				Validations: []codersdk.ValidationError{{
+					Field:  field + ".scope",
+					Detail: fmt.Sprintf("Scope must be one of %q or %q.", codersdk.NotificationPreferenceScopeWorkspace, codersdk.NotificationPreferenceScopeTemplate),
+				}},
+			})
+			return
+		}
+		if pref.NotificationTemplateID == uuid.Nil || pref.TargetID == uuid.Nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid notification preference.",
+				Detail:  fmt.Sprintf("%s must have a notification template ID and a target ID.", field),
+			})
+			return
+		}
+		key := preferenceKey{notificationTemplateID: pref.NotificationTemplateID, targetID: pref.TargetID}
+		if _, ok := seen[key]; ok {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid notification preference.",
+				Detail:  fmt.Sprintf("%s is a duplicate of an earlier preference.", field),
+			})
+			return
+		}
+		seen[key] = struct{}{}
+
+		if pref.Disabled == nil {
+			remove.NotificationTemplateIds = append(remove.NotificationTemplateIds, pref.NotificationTemplateID)
+			remove.TargetIds = append(remove.TargetIds, pref.TargetID)
+			continue
+		}
+
+		// Only allow preferences for resources that the caller can see, so
+		// that the IDs of other resources cannot be probed.
+		var err error
+		switch scope {
+		case database.NotificationPreferenceScopeWorkspace:
+			_, err = api.Database.GetWorkspaceByID(ctx, pref.TargetID)
+		case database.NotificationPreferenceScopeTemplate:
+			_, err = api.Database.GetTemplateByID(ctx, pref.TargetID)
+		}
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid notification preference.",
+				Validations: []codersdk.ValidationError{{
+					Field:  field + ".target_id",
+					Detail: fmt.Sprintf("No %s exists with ID %q.", scope, pref.TargetID),
+				}},
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: fmt.Sprintf("Failed to fetch %s.", scope),
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		upsert.NotificationTemplateIds = append(upsert.NotificationTemplateIds, pref.NotificationTemplateID)
+		upsert.Scopes = append(upsert.Scopes, scope)
+		upsert.TargetIds = append(upsert.TargetIds, pref.TargetID)
+		upsert.Disableds = append(upsert.Disableds, *pref.Disabled)
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		if len(upsert.TargetIds) > 0 {
+			if _, err := tx.UpsertUserNotificationScopedPreferences(ctx, upsert); err != nil {
+				return err
+			}
+		}
+		if len(remove.TargetIds) > 0 {
+			if _, err := tx.DeleteUserNotificationScopedPreferences(ctx, remove); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil)
+	if database.IsForeignKeyViolation(err, database.ForeignKeyNotificationScopedPreferencesNotificationTemplateID) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid notification preference.",
+			Detail:  "One or more notification templates do not exist.",
+		})
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to update scoped preferences", slog.Error(err))
+
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update user scoped notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	prefs, err := api.Database.GetUserNotificationScopedPreferences(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to retrieve user scoped notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertScopedNotificationPreferences(prefs))
+}
+
 // @Summary Send a custom notification
 // @ID send-a-custom-notification
 // @Security CoderSessionToken
@@ -456,3 +624,17 @@ func convertNotificationPreferences(in []database.NotificationPreference) (out [
 
 	return out
 }
+
+func convertScopedNotificationPreferences(in []database.NotificationScopedPreference) []codersdk.ScopedNotificationPreference {
+	out := make([]codersdk.ScopedNotificationPreference, 0, len(in))
+	for _, pref := range in {
+		out = append(out, codersdk.ScopedNotificationPreference{
+			NotificationTemplateID: pref.NotificationTemplateID,
+			Scope:                  codersdk.NotificationPreferenceScope(pref.Scope),
+			TargetID:               pref.TargetID,
+			Disabled:               pref.Disabled,
+			UpdatedAt:              pref.UpdatedAt,
+		})
+	}
+	return out
+}
@@ -3,6 +3,10 @@ package dispatch
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,15 +18,27 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/notifications/types"
 	markdown "github.com/coder/coder/v2/coderd/render"
 	"github.com/coder/coder/v2/codersdk"
 )
 
+// WebhookSignatureHeader carries the HMAC-SHA256 signature of the request
+// body, hex-encoded and prefixed with "sha256=", when the notification
+// template has a signing secret.
+const WebhookSignatureHeader = "X-Coder-Signature"
+
+// WebhookStore fetches the webhook overrides of notification templates.
+type WebhookStore interface {
+	GetNotificationTemplateWebhookByTemplateID(ctx context.Context, notificationTemplateID uuid.UUID) (database.NotificationTemplateWebhook, error)
+}
+
 // WebhookHandler dispatches notification messages via an HTTP POST webhook.
 type WebhookHandler struct {
-	cfg codersdk.NotificationsWebhookConfig
-	log slog.Logger
+	cfg   codersdk.NotificationsWebhookConfig
+	log   slog.Logger
+	store WebhookStore
 
 	cl *http.Client
 }
@@ -38,7 +54,7 @@ type WebhookPayload struct {
 	BodyMarkdown  string               `json:"body_markdown"`
 }
 
-func NewWebhookHandler(cfg codersdk.NotificationsWebhookConfig, log slog.Logger) *WebhookHandler {
+func NewWebhookHandler(cfg codersdk.NotificationsWebhookConfig, log slog.Logger, store WebhookStore) *WebhookHandler {
 	// Create a new transport in favor of reusing the default, since other http clients may interfere.
 	// http.Transport maintains its own connection pool, and we want to avoid cross-contamination.
 	var rt http.RoundTripper
@@ -54,14 +70,29 @@ func NewWebhookHandler(cfg codersdk.NotificationsWebhookConfig, log slog.Logger)
 		rt = t.Clone()
 	}
 
-	return &WebhookHandler{cfg: cfg, log: log, cl: &http.Client{Transport: rt}}
+	return &WebhookHandler{cfg: cfg, log: log, store: store, cl: &http.Client{Transport: rt}}
 }
 
-func (w *WebhookHandler) Dispatcher(payload types.MessagePayload, titleMarkdown, bodyMarkdown string, _ template.FuncMap) (DeliveryFunc, error) {
-	if w.cfg.Endpoint.String() == "" {
-		return nil, xerrors.New("webhook endpoint not defined")
+// ParseWebhookPayloadTemplate parses a Go template which renders the request
+// body of a webhook from a WebhookPayload. Besides the standard functions,
+// templates may use "json" to encode a value, e.g. {{ json .Title }}.
+func ParseWebhookPayloadTemplate(in string) (*template.Template, error) {
+	tmpl, err := template.New("payload").
+		Funcs(template.FuncMap{
+			"json": func(v any) (string, error) {
+				b, err := json.Marshal(v)
+				return string(b), err
+			},
+		}).
+		Option("missingkey=error").
+		Parse(in)
+	if err != nil {
+		return nil, xerrors.Errorf("template parse: %w", err)
 	}
+	return tmpl, nil
+}
 
+func (w *WebhookHandler) Dispatcher(payload types.MessagePayload, titleMarkdown, bodyMarkdown string, _ template.FuncMap) (DeliveryFunc, error) {
 	titlePlaintext, err := markdown.PlaintextFromMarkdown(titleMarkdown)
 	if err != nil {
 		return nil, xerrors.Errorf("render title: %w", err)
@@ -71,11 +102,40 @@ func (w *WebhookHandler) Dispatcher(payload types.MessagePayload, titleMarkdown,
 		return nil, xerrors.Errorf("render body: %w", err)
 	}
 
-	return w.dispatch(payload, titlePlaintext, titleMarkdown, bodyPlaintext, bodyMarkdown, w.cfg.Endpoint.String()), nil
+	return w.dispatch(payload, titlePlaintext, titleMarkdown, bodyPlaintext, bodyMarkdown), nil
+}
+
+// templateWebhook returns the webhook overrides of the notification template
+// of the message, if any.
+func (w *WebhookHandler) templateWebhook(ctx context.Context, msgPayload types.MessagePayload) (database.NotificationTemplateWebhook, error) {
+	templateID, err := uuid.Parse(msgPayload.NotificationTemplateID)
+	if err != nil {
+		// Messages enqueued before the template ID was part of the payload
+		// use the deployment configuration.
+		return database.NotificationTemplateWebhook{}, nil
+	}
+	webhook, err := w.store.GetNotificationTemplateWebhookByTemplateID(ctx, templateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.NotificationTemplateWebhook{}, nil
+	}
+	return webhook, err
 }
 
-func (w *WebhookHandler) dispatch(msgPayload types.MessagePayload, titlePlaintext, titleMarkdown, bodyPlaintext, bodyMarkdown, endpoint string) DeliveryFunc {
+func (w *WebhookHandler) dispatch(msgPayload types.MessagePayload, titlePlaintext, titleMarkdown, bodyPlaintext, bodyMarkdown string) DeliveryFunc {
 	return func(ctx context.Context, msgID uuid.UUID) (retryable bool, err error) {
+		webhook, err := w.templateWebhook(ctx, msgPayload)
+		if err != nil {
+			return true, xerrors.Errorf("get notification template webhook: %w", err)
+		}
+
+		endpoint := w.cfg.Endpoint.String()
+		if webhook.Endpoint != "" {
+			endpoint = webhook.Endpoint
+		}
+		if endpoint == "" {
+			return false, xerrors.New("webhook endpoint not defined")
+		}
+
 		// Prepare payload.
 		payload := WebhookPayload{
 			Version:       "1.1",
@@ -86,9 +146,22 @@ func (w *WebhookHandler) dispatch(msgPayload types.MessagePayload, titlePlaintex
 			BodyMarkdown:  bodyMarkdown,
 			Payload:       msgPayload,
 		}
-		m, err := json.Marshal(payload)
-		if err != nil {
-			return false, xerrors.Errorf("marshal payload: %v", err)
+		var m []byte
+		if webhook.PayloadTemplate != "" {
+			tmpl, err := ParseWebhookPayloadTemplate(webhook.PayloadTemplate)
+			if err != nil {
+				return false, xerrors.Errorf("parse payload template: %w", err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, payload); err != nil {
+				return false, xerrors.Errorf("render payload template: %w", err)
+			}
+			m = buf.Bytes()
+		} else {
+			m, err = json.Marshal(payload)
+			if err != nil {
+				return false, xerrors.Errorf("marshal payload: %v", err)
+			}
 		}
 
 		// Prepare request.
@@ -99,6 +172,11 @@ func (w *WebhookHandler) dispatch(msgPayload types.MessagePayload, titlePlaintex
 		}
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("X-Message-Id", msgID.String())
+		if webhook.SigningSecret != "" {
+			mac := hmac.New(sha256.New, []byte(webhook.SigningSecret))
+			_, _ = mac.Write(m)
+			req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
 
 		// Send request.
 		resp, err := w.cl.Do(req)
@@ -2,8 +2,13 @@ package dispatch_test
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -16,6 +21,7 @@ import (
 
 	"cdr.dev/slog/v3"
 	"cdr.dev/slog/v3/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/notifications/dispatch"
 	"github.com/coder/coder/v2/coderd/notifications/types"
 	"github.com/coder/coder/v2/codersdk"
@@ -139,7 +145,7 @@ func TestWebhook(t *testing.T) {
 			cfg := codersdk.NotificationsWebhookConfig{
 				Endpoint: *serpent.URLOf(endpoint),
 			}
-			handler := dispatch.NewWebhookHandler(cfg, logger.With(slog.F("test", tc.name)))
+			handler := dispatch.NewWebhookHandler(cfg, logger.With(slog.F("test", tc.name)), fakeWebhookStore{})
 			deliveryFn, err := handler.Dispatcher(msgPayload, titleMarkdown, bodyMarkdown, helpers())
 			require.NoError(t, err)
 
@@ -155,3 +161,82 @@ func TestWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhookTemplateOverrides(t *testing.T) {
+	t.Parallel()
+
+	const secret = "s3cr3t"
+	templateID := uuid.New()
+	msgID := uuid.New()
+
+	type received struct {
+		body      []byte
+		signature string
+	}
+	receivedCh := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		receivedCh <- received{body: body, signature: r.Header.Get(dispatch.WebhookSignatureHeader)}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	store := fakeWebhookStore{
+		templateID: database.NotificationTemplateWebhook{
+			NotificationTemplateID: templateID,
+			// The deployment has no webhook endpoint, the template provides one.
+			Endpoint:        server.URL,
+			PayloadTemplate: `{"text": {{ json .Title }}, "workspace": {{ json .Payload.Labels.name }}}`,
+			SigningSecret:   secret,
+		},
+	}
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+	handler := dispatch.NewWebhookHandler(codersdk.NotificationsWebhookConfig{}, logger, store)
+
+	deliveryFn, err := handler.Dispatcher(types.MessagePayload{
+		NotificationTemplateID: templateID.String(),
+		Labels:                 map[string]string{"name": "my \"workspace\""},
+	}, "Workspace *dormant*", "body", helpers())
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	retryable, err := deliveryFn(ctx, msgID)
+	require.NoError(t, err)
+	require.False(t, retryable)
+
+	got := testutil.RequireReceive(ctx, t, receivedCh)
+	require.JSONEq(t, `{"text": "Workspace dormant", "workspace": "my \"workspace\""}`, string(got.body))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(got.body)
+	require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), got.signature)
+
+	// Templates referencing missing labels fail permanently.
+	deliveryFn, err = handler.Dispatcher(types.MessagePayload{
+		NotificationTemplateID: templateID.String(),
+	}, "title", "body", helpers())
+	require.NoError(t, err)
+	retryable, err = deliveryFn(ctx, msgID)
+	require.ErrorContains(t, err, "render payload template")
+	require.False(t, retryable)
+
+	// Without an override or a deployment endpoint there is nowhere to deliver to.
+	deliveryFn, err = handler.Dispatcher(types.MessagePayload{
+		NotificationTemplateID: uuid.NewString(),
+	}, "title", "body", helpers())
+	require.NoError(t, err)
+	retryable, err = deliveryFn(ctx, msgID)
+	require.ErrorContains(t, err, "webhook endpoint not defined")
+	require.False(t, retryable)
+}
+
+type fakeWebhookStore map[uuid.UUID]database.NotificationTemplateWebhook
+
+func (f fakeWebhookStore) GetNotificationTemplateWebhookByTemplateID(_ context.Context, notificationTemplateID uuid.UUID) (database.NotificationTemplateWebhook, error) {
+	webhook, ok := f[notificationTemplateID]
+	if !ok {
+		return database.NotificationTemplateWebhook{}, sql.ErrNoRows
+	}
+	return webhook, nil
+}
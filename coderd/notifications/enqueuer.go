@@ -137,8 +137,9 @@ func (s *StoreEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID
 		})
 		if err != nil {
 			// We have a trigger on the notification_messages table named `inhibit_enqueue_if_disabled` which prevents messages
-			// from being enqueued if the user has disabled them via notification_preferences. The trigger will fail the insertion
-			// with the message "cannot enqueue message: user has disabled this notification".
+			// from being enqueued if the user has disabled them via notification_preferences, or via notification_scoped_preferences
+			// for one of the targets of the message (e.g. the workspace or template it is about). The trigger will fail the
+			// insertion with the message "cannot enqueue message: notification is not enabled".
 			//
 			// This is more efficient than fetching the user's preferences for each enqueue, and centralizes the business logic.
 			if strings.Contains(err.Error(), ErrCannotEnqueueDisabledNotification.Error()) {
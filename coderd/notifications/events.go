@@ -19,6 +19,13 @@ var (
 	TemplateWorkspaceManualBuildFailed = uuid.MustParse("2faeee0f-26cb-4e96-821c-85ccb9f71513")
 	TemplateWorkspaceOutOfMemory       = uuid.MustParse("a9d027b4-ac49-4fb1-9f6d-45af15f64e7a")
 	TemplateWorkspaceOutOfDisk         = uuid.MustParse("f047f6a3-5713-40f7-85aa-0394cce9fa3a")
+	TemplateWorkspaceBudgetExceeded    = uuid.MustParse("b9fa160a-a261-4135-8f68-fb60fc019457")
+	TemplateWorkspaceSupportBundle     = uuid.MustParse("5e2fb2a8-5b43-4d2c-b8f5-0a6c3f3d1b7e")
+	TemplateWorkspaceExpiring          = uuid.MustParse("939d8a0f-98b3-44f7-8c6a-3bf2b273f814")
+	TemplateWorkspaceAgentCrashLooping = uuid.MustParse("e9a80589-5ad5-415d-816d-d6943f27938d")
+	TemplateWorkspaceBuildFailed       = uuid.MustParse("7c1a8d3e-5f2b-4e69-9a0d-3b6e4f81c527")
+	TemplateWorkspaceOutOfMemoryKill   = uuid.MustParse("2229d63e-e11d-481b-8b20-8d6e30b430b6")
+	TemplateWorkspaceSummaryReport     = uuid.MustParse("e3b7c9d2-5a1f-4c8e-b6d0-9f2a4e7c1b85")
 )
 
 // Account-related events.
@@ -41,6 +48,7 @@ var (
 
 	TemplateWorkspaceBuildsFailedReport = uuid.MustParse("34a20db2-e9cc-4a93-b0e4-8569699d7a00")
 	TemplateWorkspaceResourceReplaced   = uuid.MustParse("89d9745a-816e-4695-a17f-3d0a229e2b8d")
+	TemplateTemplateHealthReport        = uuid.MustParse("b5e4a1c7-3d62-4f8e-9a05-6c2d8e7f1b39")
 )
 
 // Prebuilds-related events.
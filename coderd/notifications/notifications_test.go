@@ -441,7 +441,7 @@ func TestRetries(t *testing.T) {
 	cfg.RetryInterval = serpent.Duration(time.Second) // query uses second-precision
 	cfg.FetchInterval = serpent.Duration(time.Millisecond * 100)
 
-	handler := newDispatchInterceptor(dispatch.NewWebhookHandler(cfg.Webhook, logger.Named("webhook")))
+	handler := newDispatchInterceptor(dispatch.NewWebhookHandler(cfg.Webhook, logger.Named("webhook"), store))
 
 	// Intercept calls to submit the buffered updates to the store.
 	storeInterceptor := &syncInterceptor{Store: store}
@@ -856,6 +856,104 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TemplateWorkspaceBudgetExceeded",
+			id:   notifications.TemplateWorkspaceBudgetExceeded,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"workspace":   "bobby-workspace",
+					"budget_kind": "workspace",
+					"budget":      "100",
+					"cost":        "101.50",
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceSupportBundle",
+			id:   notifications.TemplateWorkspaceSupportBundle,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"workspace":                "bobby-workspace",
+					"workspace_owner_username": "bobby",
+					"initiator":                "alice",
+					"message":                  "The startup script hangs after the latest template update.",
+					"file_id":                  "c6b9e3f1-4c6a-4f0e-9d2b-8a1f5e7d3c20",
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceExpiring",
+			id:   notifications.TemplateWorkspaceExpiring,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"workspace":     "bobby-workspace",
+					"action":        "deleted",
+					"timeTilExpiry": "in 3 days",
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceAgentCrashLooping",
+			id:   notifications.TemplateWorkspaceAgentCrashLooping,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"workspace":   "bobby-workspace",
+					"agent":       "main",
+					"connections": "5",
+					"last_exit":   "ping timeout (lifecycle state start_error)",
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceBuildFailed",
+			id:   notifications.TemplateWorkspaceBuildFailed,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"name":                     "bobby-workspace",
+					"template_name":            "bobby-template",
+					"transition":               "start",
+					"reason":                   "autostart",
+					"initiator":                "bobby",
+					"workspace_owner_username": "bobby",
+					"workspace_build_number":   "3",
+					"failure_summary":          "docker_container.workspace: Unable to start container",
+				},
+				Data: map[string]any{
+					"log_lines": []string{
+						"docker_container.workspace: Creating...",
+						"Error: Unable to start container: no space left on device",
+					},
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceOutOfMemoryKill",
+			id:   notifications.TemplateWorkspaceOutOfMemoryKill,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"workspace": "bobby-workspace",
+					"oom_kills": "2",
+				},
+			},
+		},
 		{
 			name: "TemplateUserAccountCreated",
 			id:   notifications.TemplateUserAccountCreated,
@@ -1078,6 +1176,80 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TemplateTemplateHealthReport",
+			id:   notifications.TemplateTemplateHealthReport,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels:       map[string]string{},
+				// We need to use floats as `json.Unmarshal` unmarshal numbers in `map[string]any` to floats.
+				Data: map[string]any{
+					"report_frequency": "week",
+					"templates": []map[string]any{
+						{
+							"name":                  "bobby-first-template",
+							"display_name":          "Bobby First Template",
+							"total_builds":          55.0,
+							"failed_builds":         4.0,
+							"failure_rate":          7.3,
+							"average_start_seconds": 42.5,
+							"total_workspaces":      20.0,
+							"outdated_workspaces":   3.0,
+							"dormant_workspaces":    1.0,
+						},
+						{
+							"name":                  "bobby-second-template",
+							"display_name":          "Bobby Second Template",
+							"total_builds":          12.0,
+							"failed_builds":         0.0,
+							"failure_rate":          0.0,
+							"average_start_seconds": 18.0,
+							"total_workspaces":      6.0,
+							"outdated_workspaces":   0.0,
+							"dormant_workspaces":    2.0,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceSummaryReport",
+			id:   notifications.TemplateWorkspaceSummaryReport,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels:       map[string]string{},
+				// We need to use floats as `json.Unmarshal` unmarshal numbers in `map[string]any` to floats.
+				Data: map[string]any{
+					"report_frequency": "week",
+					"total_builds":     14.0,
+					"failed_builds":    2.0,
+					"upcoming_deletions": []map[string]any{
+						{
+							"name":        "bobby-old-workspace",
+							"deleting_at": "October 14, 2024",
+						},
+						{
+							"name":        "bobby-stale-workspace",
+							"deleting_at": "October 17, 2024",
+						},
+					},
+					"dormancy_warnings": []map[string]any{
+						{
+							"name":       "bobby-workspace",
+							"dormant_at": "October 15, 2024",
+						},
+						{
+							"name":       "bobby-other-workspace",
+							"dormant_at": "October 16, 2024",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "TemplateUserRequestedOneTimePasscode",
 			id:   notifications.TemplateUserRequestedOneTimePasscode,
@@ -1887,6 +2059,68 @@ func TestDisabledBeforeEnqueue(t *testing.T) {
 	require.Empty(t, notifIDs)
 }
 
+// TestScopedPreferencesBeforeEnqueue ensures that workspace and template scoped preferences take precedence over the
+// preference for the notification template, and that workspace preferences take precedence over template ones.
+func TestScopedPreferencesBeforeEnqueue(t *testing.T) {
+	t.Parallel()
+
+	ctx := dbauthz.AsNotifier(testutil.Context(t, testutil.WaitSuperLong))
+	store, _ := dbtestutil.NewDB(t)
+	logger := testutil.Logger(t)
+
+	// GIVEN: an enqueuer & a sample user
+	cfg := defaultNotificationsConfig(database.NotificationMethodSmtp)
+	enq, err := notifications.NewStoreEnqueuer(cfg, store, defaultHelpers(), logger.Named("enqueuer"), quartz.NewReal())
+	require.NoError(t, err)
+	user := createSampleUser(t, store)
+
+	var (
+		templateID       = notifications.TemplateWorkspaceDormant
+		wsTemplate       = uuid.New()
+		mutedWorkspace   = uuid.New()
+		unmutedWorkspace = uuid.New()
+		otherWorkspace   = uuid.New()
+	)
+
+	// WHEN: the user mutes dormancy notifications for a workspace and for a template, but unmutes them for one of the
+	// workspaces of that template
+	n, err := store.UpsertUserNotificationScopedPreferences(ctx, database.UpsertUserNotificationScopedPreferencesParams{
+		UserID:                  user.ID,
+		NotificationTemplateIds: []uuid.UUID{templateID, templateID, templateID},
+		Scopes: []database.NotificationPreferenceScope{
+			database.NotificationPreferenceScopeWorkspace,
+			database.NotificationPreferenceScopeTemplate,
+			database.NotificationPreferenceScopeWorkspace,
+		},
+		TargetIds: []uuid.UUID{mutedWorkspace, wsTemplate, unmutedWorkspace},
+		Disableds: []bool{true, true, false},
+	})
+	require.NoError(t, err, "failed to set scoped preferences")
+	require.EqualValues(t, 3, n, "unexpected number of affected rows")
+
+	// THEN: only the notifications about muted targets are not enqueued
+	for _, tc := range []struct {
+		name       string
+		templateID uuid.UUID
+		targets    []uuid.UUID
+		enqueued   bool
+	}{
+		{name: "MutedWorkspace", templateID: templateID, targets: []uuid.UUID{mutedWorkspace, user.ID, uuid.New()}, enqueued: false},
+		{name: "MutedTemplate", templateID: templateID, targets: []uuid.UUID{uuid.New(), user.ID, wsTemplate}, enqueued: false},
+		{name: "UnmutedWorkspace", templateID: templateID, targets: []uuid.UUID{unmutedWorkspace, user.ID, wsTemplate}, enqueued: true},
+		{name: "OtherWorkspace", templateID: templateID, targets: []uuid.UUID{otherWorkspace, user.ID, uuid.New()}, enqueued: true},
+		{name: "OtherNotification", templateID: notifications.TemplateWorkspaceBuildFailed, targets: []uuid.UUID{mutedWorkspace, user.ID, wsTemplate}, enqueued: true},
+	} {
+		notifIDs, err := enq.Enqueue(ctx, user.ID, tc.templateID, map[string]string{}, "test", tc.targets...)
+		require.NoError(t, err, tc.name)
+		if tc.enqueued {
+			require.Len(t, notifIDs, 1, tc.name)
+		} else {
+			require.Empty(t, notifIDs, tc.name)
+		}
+	}
+}
+
 // TestDisabledAfterEnqueue ensures that notifications enqueued before a notification template was disabled will not be
 // sent, and will instead be marked as "inhibited".
 func TestDisabledAfterEnqueue(t *testing.T) {
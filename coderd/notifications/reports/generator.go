@@ -54,6 +54,16 @@ func NewReportGenerator(ctx context.Context, logger slog.Logger, db database.Sto
 				return xerrors.Errorf("unable to generate reports with failed workspace builds: %w", err)
 			}
 
+			err = reportTemplateHealth(ctx, logger, tx, enqueuer, clk)
+			if err != nil {
+				return xerrors.Errorf("unable to generate template health reports: %w", err)
+			}
+
+			err = reportUserWorkspaces(ctx, logger, tx, enqueuer, clk)
+			if err != nil {
+				return xerrors.Errorf("unable to generate user workspace reports: %w", err)
+			}
+
 			logger.Info(ctx, "report generator finished", slog.F("duration", clk.Since(start)))
 
 			return nil
@@ -157,7 +167,7 @@ func reportFailedWorkspaceBuilds(ctx context.Context, logger slog.Logger, db dat
 		}
 
 		// Fetch template admins with org access to the templates
-		templateAdmins, err := findTemplateAdmins(ctx, db, stats)
+		templateAdmins, err := findTemplateAdmins(ctx, db, stats.TemplateOrganizationID)
 		if err != nil {
 			logger.Error(ctx, "unable to find template admins for template", slog.F("template_id", stats.TemplateID), slog.Error(err))
 			continue
@@ -295,7 +305,7 @@ func buildDataForReportFailedWorkspaceBuilds(reports []adminReport) map[string]a
 	}
 }
 
-func findTemplateAdmins(ctx context.Context, db database.Store, stats database.GetWorkspaceBuildStatsByTemplatesRow) ([]database.GetUsersRow, error) {
+func findTemplateAdmins(ctx context.Context, db database.Store, organizationID uuid.UUID) ([]database.GetUsersRow, error) {
 	users, err := db.GetUsers(ctx, database.GetUsersParams{
 		RbacRole: []string{codersdk.RoleTemplateAdmin},
 	})
@@ -321,7 +331,7 @@ func findTemplateAdmins(ctx context.Context, db database.Store, stats database.G
 	}
 
 	for _, entry := range orgIDsByMemberIDs {
-		if slices.Contains(entry.OrganizationIDs, stats.TemplateOrganizationID) {
+		if slices.Contains(entry.OrganizationIDs, organizationID) {
 			templateAdmins = append(templateAdmins, usersByIDs[entry.UserID])
 		}
 	}
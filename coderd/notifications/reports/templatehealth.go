@@ -0,0 +1,163 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/quartz"
+)
+
+const (
+	templateHealthReportFrequency      = 7 * 24 * time.Hour
+	templateHealthReportFrequencyLabel = "week"
+)
+
+// reportTemplateHealth stores a health report for every active template once
+// a week, and sends the reports of the templates of their organizations to
+// template admins.
+func reportTemplateHealth(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock) error {
+	now := dbtime.Time(clk.Now()).UTC()
+	since := now.Add(-templateHealthReportFrequency)
+
+	reportLog, err := db.GetNotificationReportGeneratorLogByTemplate(ctx, notifications.TemplateTemplateHealthReport)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("unable to read report generator log: %w", err)
+	}
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// First run? Check-in the job, and get back after one week, so that
+		// the first report covers a full period.
+		logger.Info(ctx, "report generator is executing the job for the first time", slog.F("notification_template_id", notifications.TemplateTemplateHealthReport))
+
+		err = db.UpsertNotificationReportGeneratorLog(ctx, database.UpsertNotificationReportGeneratorLogParams{
+			NotificationTemplateID: notifications.TemplateTemplateHealthReport,
+			LastGeneratedAt:        now,
+		})
+		if err != nil {
+			return xerrors.Errorf("unable to update report generator logs (first time execution): %w", err)
+		}
+		return nil
+	}
+	if !reportLog.LastGeneratedAt.IsZero() && reportLog.LastGeneratedAt.Add(templateHealthReportFrequency).After(now) {
+		return nil // reports generated recently
+	}
+
+	templateStatsRows, err := db.GetTemplateHealthStats(ctx, since)
+	if err != nil {
+		return xerrors.Errorf("unable to fetch template health stats: %w", err)
+	}
+
+	reports := make(map[uuid.UUID][]database.GetTemplateHealthStatsRow)
+	for _, stats := range templateStatsRows {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// Reports are stored whether or not anyone receives them, so that
+		// they can be fetched through the API.
+		_, err := db.InsertTemplateHealthReport(ctx, database.InsertTemplateHealthReportParams{
+			ID:                  uuid.New(),
+			TemplateID:          stats.TemplateID,
+			PeriodStart:         since,
+			PeriodEnd:           now,
+			TotalBuilds:         stats.TotalBuilds,
+			FailedBuilds:        stats.FailedBuilds,
+			AverageStartSeconds: stats.AverageStartSeconds,
+			TotalWorkspaces:     stats.TotalWorkspaces,
+			OutdatedWorkspaces:  stats.OutdatedWorkspaces,
+			DormantWorkspaces:   stats.DormantWorkspaces,
+			CreatedAt:           now,
+		})
+		if err != nil {
+			return xerrors.Errorf("unable to insert template health report: %w", err)
+		}
+
+		templateAdmins, err := findTemplateAdmins(ctx, db, stats.TemplateOrganizationID)
+		if err != nil {
+			logger.Error(ctx, "unable to find template admins for template", slog.F("template_id", stats.TemplateID), slog.Error(err))
+			continue
+		}
+		for _, templateAdmin := range templateAdmins {
+			reports[templateAdmin.ID] = append(reports[templateAdmin.ID], stats)
+		}
+	}
+
+	for templateAdmin, reports := range reports {
+		if ctx.Err() != nil {
+			break
+		}
+
+		targets := []uuid.UUID{}
+		for _, report := range reports {
+			targets = append(targets, report.TemplateID, report.TemplateOrganizationID)
+		}
+
+		if _, err := enqueuer.EnqueueWithData(ctx, templateAdmin, notifications.TemplateTemplateHealthReport,
+			map[string]string{},
+			buildDataForReportTemplateHealth(reports),
+			"report_generator",
+			slice.Unique(targets)...,
+		); err != nil {
+			logger.Warn(ctx, "failed to send a template health report", slog.Error(err))
+		}
+	}
+
+	if xerrors.Is(ctx.Err(), context.Canceled) {
+		logger.Error(ctx, "report generator job is canceled")
+		return ctx.Err()
+	}
+
+	err = db.UpsertNotificationReportGeneratorLog(ctx, database.UpsertNotificationReportGeneratorLogParams{
+		NotificationTemplateID: notifications.TemplateTemplateHealthReport,
+		LastGeneratedAt:        now,
+	})
+	if err != nil {
+		return xerrors.Errorf("unable to update report generator logs: %w", err)
+	}
+	return nil
+}
+
+func buildDataForReportTemplateHealth(reports []database.GetTemplateHealthStatsRow) map[string]any {
+	templates := []map[string]any{}
+	for _, report := range reports {
+		displayName := report.TemplateDisplayName
+		if displayName == "" {
+			displayName = report.TemplateName
+		}
+
+		var failureRate float64
+		if report.TotalBuilds > 0 {
+			failureRate = float64(report.FailedBuilds) * 100 / float64(report.TotalBuilds)
+		}
+
+		templates = append(templates, map[string]any{
+			"name":                  report.TemplateName,
+			"display_name":          displayName,
+			"total_builds":          report.TotalBuilds,
+			"failed_builds":         report.FailedBuilds,
+			"failure_rate":          roundTenth(failureRate),
+			"average_start_seconds": roundTenth(report.AverageStartSeconds),
+			"total_workspaces":      report.TotalWorkspaces,
+			"outdated_workspaces":   report.OutdatedWorkspaces,
+			"dormant_workspaces":    report.DormantWorkspaces,
+		})
+	}
+
+	return map[string]any{
+		"report_frequency": templateHealthReportFrequencyLabel,
+		"templates":        templates,
+	}
+}
+
+func roundTenth(f float64) float64 {
+	return math.Round(f*10) / 10
+}
@@ -0,0 +1,140 @@
+package reports
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+func TestReportTemplateHealth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FirstRun_NoReport", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, _, notifEnq, clk := setup(t)
+
+		// When: first run
+		err := reportTemplateHealth(ctx, logger, db, notifEnq, clk)
+
+		// Then: the job only checks in
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+		reportLog, err := db.GetNotificationReportGeneratorLogByTemplate(ctx, notifications.TemplateTemplateHealthReport)
+		require.NoError(t, err)
+		require.WithinDuration(t, clk.Now(), reportLog.LastGeneratedAt, time.Second)
+	})
+
+	t.Run("SecondRun_Report_ThirdRunTooEarly_NoReport", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, ps, notifEnq, clk := setup(t)
+
+		// Given
+		org := dbgen.Organization(t, db, database.Organization{})
+		templateAdmin := dbgen.User(t, db, database.User{Username: "template-admin", RBACRoles: []string{rbac.RoleTemplateAdmin().Name}})
+		_ = dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: templateAdmin.ID, OrganizationID: org.ID})
+		user := dbgen.User(t, db, database.User{})
+		_ = dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: user.ID, OrganizationID: org.ID})
+
+		t1 := dbgen.Template(t, db, database.Template{Name: "template-1", DisplayName: "First Template", CreatedBy: templateAdmin.ID, OrganizationID: org.ID})
+		t1v1 := dbgen.TemplateVersion(t, db, database.TemplateVersion{CreatedBy: templateAdmin.ID, OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true}, JobID: uuid.New()})
+		t1v2 := dbgen.TemplateVersion(t, db, database.TemplateVersion{CreatedBy: templateAdmin.ID, OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true}, JobID: uuid.New()})
+		err := db.UpdateTemplateActiveVersionByID(ctx, database.UpdateTemplateActiveVersionByIDParams{ID: t1.ID, ActiveVersionID: t1v2.ID, UpdatedAt: clk.Now()})
+		require.NoError(t, err)
+		// Templates without builds or workspaces are not reported.
+		_ = dbgen.Template(t, db, database.Template{Name: "template-2", CreatedBy: templateAdmin.ID, OrganizationID: org.ID})
+
+		w1 := dbgen.Workspace(t, db, database.WorkspaceTable{TemplateID: t1.ID, OwnerID: user.ID, OrganizationID: org.ID})
+		w2 := dbgen.Workspace(t, db, database.WorkspaceTable{TemplateID: t1.ID, OwnerID: user.ID, OrganizationID: org.ID})
+		w3 := dbgen.Workspace(t, db, database.WorkspaceTable{TemplateID: t1.ID, OwnerID: user.ID, OrganizationID: org.ID, DormantAt: sql.NullTime{Time: clk.Now(), Valid: true}})
+
+		// When: first run
+		err = reportTemplateHealth(ctx, logger, db, notifEnq, clk)
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+
+		// One week later...
+		clk.Advance(templateHealthReportFrequency + time.Minute)
+		now := clk.Now()
+
+		_ = dbfake.WorkspaceBuild(t, db, w1).
+			Pubsub(ps).
+			Seed(database.WorkspaceBuild{BuildNumber: 1, TemplateVersionID: t1v2.ID, CreatedAt: now.Add(-2 * dayDuration), Transition: database.WorkspaceTransitionStart, Reason: database.BuildReasonInitiator}).
+			Succeeded(dbfake.WithJobCreatedAt(now.Add(-2*dayDuration)), dbfake.WithJobCompletedAt(now.Add(-2*dayDuration+40*time.Second))).
+			Do()
+		_ = dbfake.WorkspaceBuild(t, db, w1).
+			Pubsub(ps).
+			Seed(database.WorkspaceBuild{BuildNumber: 2, TemplateVersionID: t1v2.ID, CreatedAt: now.Add(-dayDuration), Transition: database.WorkspaceTransitionStart, Reason: database.BuildReasonInitiator}).
+			Failed(dbfake.WithJobError(jobError.String), dbfake.WithJobCreatedAt(now.Add(-dayDuration)), dbfake.WithJobCompletedAt(now.Add(-dayDuration+time.Minute))).
+			Do()
+		// w2 is outdated.
+		_ = dbfake.WorkspaceBuild(t, db, w2).
+			Pubsub(ps).
+			Seed(database.WorkspaceBuild{BuildNumber: 1, TemplateVersionID: t1v1.ID, CreatedAt: now.Add(-3 * dayDuration), Transition: database.WorkspaceTransitionStart, Reason: database.BuildReasonInitiator}).
+			Succeeded(dbfake.WithJobCreatedAt(now.Add(-3*dayDuration)), dbfake.WithJobCompletedAt(now.Add(-3*dayDuration+20*time.Second))).
+			Do()
+		// The build of w3 is older than the period.
+		_ = dbfake.WorkspaceBuild(t, db, w3).
+			Pubsub(ps).
+			Seed(database.WorkspaceBuild{BuildNumber: 1, TemplateVersionID: t1v2.ID, CreatedAt: now.Add(-10 * dayDuration), Transition: database.WorkspaceTransitionStart, Reason: database.BuildReasonInitiator}).
+			Succeeded(dbfake.WithJobCreatedAt(now.Add(-10*dayDuration)), dbfake.WithJobCompletedAt(now.Add(-10*dayDuration+time.Minute))).
+			Do()
+
+		// When: second run
+		notifEnq.Clear()
+		err = reportTemplateHealth(ctx, logger, authedDB(t, db, logger), notifEnq, clk)
+
+		// Then
+		require.NoError(t, err)
+		sent := notifEnq.Sent()
+		require.Len(t, sent, 1)
+		require.Equal(t, templateAdmin.ID, sent[0].UserID)
+		require.Equal(t, notifications.TemplateTemplateHealthReport, sent[0].TemplateID)
+		require.Equal(t, "week", sent[0].Data["report_frequency"])
+		require.Equal(t, []map[string]any{
+			{
+				"name":                  t1.Name,
+				"display_name":          t1.DisplayName,
+				"total_builds":          int64(3),
+				"failed_builds":         int64(1),
+				"failure_rate":          33.3,
+				"average_start_seconds": 30.0,
+				"total_workspaces":      int64(3),
+				"outdated_workspaces":   int64(1),
+				"dormant_workspaces":    int64(1),
+			},
+		}, sent[0].Data["templates"])
+
+		reports, err := db.GetTemplateHealthReportsByTemplateID(ctx, database.GetTemplateHealthReportsByTemplateIDParams{TemplateID: t1.ID})
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		require.EqualValues(t, 3, reports[0].TotalBuilds)
+		require.EqualValues(t, 1, reports[0].FailedBuilds)
+		require.InDelta(t, 30, reports[0].AverageStartSeconds, 0.001)
+		require.EqualValues(t, 1, reports[0].OutdatedWorkspaces)
+		require.EqualValues(t, 1, reports[0].DormantWorkspaces)
+
+		// Given: a few days later
+		clk.Advance(3 * dayDuration)
+
+		// When: third run
+		notifEnq.Clear()
+		err = reportTemplateHealth(ctx, logger, authedDB(t, db, logger), notifEnq, clk)
+
+		// Then: the report was sent recently
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+	})
+}
@@ -0,0 +1,184 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	userWorkspaceReportFrequency      = 7 * 24 * time.Hour
+	userWorkspaceReportFrequencyLabel = "week"
+	userWorkspaceReportDateFormat     = "January 2, 2006"
+)
+
+// reportUserWorkspaces stores a summary of their workspaces for every user
+// once a week, and sends it to them. Summaries cover the builds of the past
+// week, and the workspaces that will be deleted or become dormant in the next
+// one. Users opt out of the notification through their notification
+// preferences, but their summaries are stored regardless.
+func reportUserWorkspaces(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock) error {
+	now := dbtime.Time(clk.Now()).UTC()
+	since := now.Add(-userWorkspaceReportFrequency)
+	until := now.Add(userWorkspaceReportFrequency)
+
+	reportLog, err := db.GetNotificationReportGeneratorLogByTemplate(ctx, notifications.TemplateWorkspaceSummaryReport)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("unable to read report generator log: %w", err)
+	}
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// First run? Check-in the job, and get back after one week, so that
+		// the first summary covers a full period.
+		logger.Info(ctx, "report generator is executing the job for the first time", slog.F("notification_template_id", notifications.TemplateWorkspaceSummaryReport))
+
+		err = db.UpsertNotificationReportGeneratorLog(ctx, database.UpsertNotificationReportGeneratorLogParams{
+			NotificationTemplateID: notifications.TemplateWorkspaceSummaryReport,
+			LastGeneratedAt:        now,
+		})
+		if err != nil {
+			return xerrors.Errorf("unable to update report generator logs (first time execution): %w", err)
+		}
+		return nil
+	}
+	if !reportLog.LastGeneratedAt.IsZero() && reportLog.LastGeneratedAt.Add(userWorkspaceReportFrequency).After(now) {
+		return nil // reports generated recently
+	}
+
+	workspaceStatsRows, err := db.GetUserWorkspaceReportStats(ctx, database.GetUserWorkspaceReportStatsParams{
+		Since: since,
+		Until: until,
+	})
+	if err != nil {
+		return xerrors.Errorf("unable to fetch user workspace stats: %w", err)
+	}
+
+	// Rows are ordered by owner, so the workspaces of a user are adjacent.
+	var (
+		userIDs   []uuid.UUID
+		summaries = make(map[uuid.UUID]*codersdk.UserWorkspaceReportSummary)
+	)
+	for _, stats := range workspaceStatsRows {
+		summary, ok := summaries[stats.OwnerID]
+		if !ok {
+			summary = &codersdk.UserWorkspaceReportSummary{
+				Workspaces: []codersdk.UserWorkspaceReportWorkspace{},
+			}
+			summaries[stats.OwnerID] = summary
+			userIDs = append(userIDs, stats.OwnerID)
+		}
+		summary.TotalBuilds += stats.TotalBuilds
+		summary.FailedBuilds += stats.FailedBuilds
+
+		workspace := codersdk.UserWorkspaceReportWorkspace{
+			ID:                  stats.WorkspaceID,
+			Name:                stats.WorkspaceName,
+			TemplateName:        stats.TemplateName,
+			TemplateDisplayName: stats.TemplateDisplayName,
+			TotalBuilds:         stats.TotalBuilds,
+			FailedBuilds:        stats.FailedBuilds,
+		}
+		if stats.DeletingAt.Valid && stats.DeletingAt.Time.Before(until) {
+			workspace.DeletingAt = ptr.Ref(stats.DeletingAt.Time)
+		}
+		if !stats.DormantAt.Valid && stats.TimeTilDormant > 0 {
+			dormantAt := stats.LastUsedAt.Add(time.Duration(stats.TimeTilDormant))
+			if dormantAt.Before(until) {
+				workspace.DormantAt = &dormantAt
+			}
+		}
+		summary.Workspaces = append(summary.Workspaces, workspace)
+	}
+
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		summary := summaries[userID]
+		rawSummary, err := json.Marshal(summary)
+		if err != nil {
+			return xerrors.Errorf("unable to marshal workspace summary: %w", err)
+		}
+
+		// Summaries are stored whether or not the user receives them, so
+		// that they can be fetched through the API.
+		_, err = db.InsertUserWorkspaceReport(ctx, database.InsertUserWorkspaceReportParams{
+			ID:          uuid.New(),
+			UserID:      userID,
+			PeriodStart: since,
+			PeriodEnd:   now,
+			Summary:     rawSummary,
+			CreatedAt:   now,
+		})
+		if err != nil {
+			return xerrors.Errorf("unable to insert user workspace report: %w", err)
+		}
+
+		targets := []uuid.UUID{}
+		for _, workspace := range summary.Workspaces {
+			targets = append(targets, workspace.ID)
+		}
+
+		if _, err := enqueuer.EnqueueWithData(ctx, userID, notifications.TemplateWorkspaceSummaryReport,
+			map[string]string{},
+			buildDataForReportUserWorkspaces(summary),
+			"report_generator",
+			targets...,
+		); err != nil {
+			logger.Warn(ctx, "failed to send a workspace summary report", slog.Error(err))
+		}
+	}
+
+	if xerrors.Is(ctx.Err(), context.Canceled) {
+		logger.Error(ctx, "report generator job is canceled")
+		return ctx.Err()
+	}
+
+	err = db.UpsertNotificationReportGeneratorLog(ctx, database.UpsertNotificationReportGeneratorLogParams{
+		NotificationTemplateID: notifications.TemplateWorkspaceSummaryReport,
+		LastGeneratedAt:        now,
+	})
+	if err != nil {
+		return xerrors.Errorf("unable to update report generator logs: %w", err)
+	}
+	return nil
+}
+
+func buildDataForReportUserWorkspaces(summary *codersdk.UserWorkspaceReportSummary) map[string]any {
+	upcomingDeletions := []map[string]any{}
+	dormancyWarnings := []map[string]any{}
+	for _, workspace := range summary.Workspaces {
+		if workspace.DeletingAt != nil {
+			upcomingDeletions = append(upcomingDeletions, map[string]any{
+				"name":        workspace.Name,
+				"deleting_at": workspace.DeletingAt.Format(userWorkspaceReportDateFormat),
+			})
+		}
+		if workspace.DormantAt != nil {
+			dormancyWarnings = append(dormancyWarnings, map[string]any{
+				"name":       workspace.Name,
+				"dormant_at": workspace.DormantAt.Format(userWorkspaceReportDateFormat),
+			})
+		}
+	}
+
+	return map[string]any{
+		"report_frequency":   userWorkspaceReportFrequencyLabel,
+		"total_builds":       summary.TotalBuilds,
+		"failed_builds":      summary.FailedBuilds,
+		"upcoming_deletions": upcomingDeletions,
+		"dormancy_warnings":  dormancyWarnings,
+	}
+}
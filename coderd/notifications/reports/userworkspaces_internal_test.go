@@ -0,0 +1,148 @@
+package reports
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestReportUserWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FirstRun_NoReport", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, _, notifEnq, clk := setup(t)
+
+		// When: first run
+		err := reportUserWorkspaces(ctx, logger, db, notifEnq, clk)
+
+		// Then: the job only checks in
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+		reportLog, err := db.GetNotificationReportGeneratorLogByTemplate(ctx, notifications.TemplateWorkspaceSummaryReport)
+		require.NoError(t, err)
+		require.WithinDuration(t, clk.Now(), reportLog.LastGeneratedAt, time.Second)
+	})
+
+	t.Run("SecondRun_Report_ThirdRunTooEarly_NoReport", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, ps, notifEnq, clk := setup(t)
+
+		// Given
+		org := dbgen.Organization(t, db, database.Organization{})
+		templateAdmin := dbgen.User(t, db, database.User{Username: "template-admin"})
+		user := dbgen.User(t, db, database.User{})
+		_ = dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: user.ID, OrganizationID: org.ID})
+		// Users without workspaces do not get a summary.
+		idleUser := dbgen.User(t, db, database.User{})
+		_ = dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: idleUser.ID, OrganizationID: org.ID})
+
+		t1 := dbgen.Template(t, db, database.Template{Name: "template-1", DisplayName: "First Template", CreatedBy: templateAdmin.ID, OrganizationID: org.ID})
+		t1v1 := dbgen.TemplateVersion(t, db, database.TemplateVersion{CreatedBy: templateAdmin.ID, OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true}, JobID: uuid.New()})
+		err := db.UpdateTemplateScheduleByID(ctx, database.UpdateTemplateScheduleByIDParams{
+			ID:                       t1.ID,
+			UpdatedAt:                clk.Now(),
+			TimeTilDormant:           int64(14 * dayDuration),
+			TimeTilDormantAutoDelete: int64(7 * dayDuration),
+		})
+		require.NoError(t, err)
+
+		// When: first run
+		err = reportUserWorkspaces(ctx, logger, db, notifEnq, clk)
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+
+		// One week later...
+		clk.Advance(userWorkspaceReportFrequency + time.Minute)
+		now := clk.Now()
+
+		// w1 had builds, and becomes dormant in 4 days.
+		w1 := dbgen.Workspace(t, db, database.WorkspaceTable{Name: "workspace-1", TemplateID: t1.ID, OwnerID: user.ID, OrganizationID: org.ID, LastUsedAt: now.Add(-10 * dayDuration)})
+		// w2 is dormant, and will be deleted in 5 days.
+		w2 := dbgen.Workspace(t, db, database.WorkspaceTable{Name: "workspace-2", TemplateID: t1.ID, OwnerID: user.ID, OrganizationID: org.ID, LastUsedAt: now.Add(-16 * dayDuration), DormantAt: sql.NullTime{Time: now.Add(-2 * dayDuration), Valid: true}})
+		// w3 is in use, and had no builds.
+		_ = dbgen.Workspace(t, db, database.WorkspaceTable{Name: "workspace-3", TemplateID: t1.ID, OwnerID: user.ID, OrganizationID: org.ID, LastUsedAt: now})
+
+		_ = dbfake.WorkspaceBuild(t, db, w1).
+			Pubsub(ps).
+			Seed(database.WorkspaceBuild{BuildNumber: 1, TemplateVersionID: t1v1.ID, CreatedAt: now.Add(-2 * dayDuration), Transition: database.WorkspaceTransitionStart, Reason: database.BuildReasonInitiator}).
+			Succeeded(dbfake.WithJobCreatedAt(now.Add(-2*dayDuration)), dbfake.WithJobCompletedAt(now.Add(-2*dayDuration+time.Minute))).
+			Do()
+		_ = dbfake.WorkspaceBuild(t, db, w1).
+			Pubsub(ps).
+			Seed(database.WorkspaceBuild{BuildNumber: 2, TemplateVersionID: t1v1.ID, CreatedAt: now.Add(-dayDuration), Transition: database.WorkspaceTransitionStart, Reason: database.BuildReasonInitiator}).
+			Failed(dbfake.WithJobError(jobError.String), dbfake.WithJobCreatedAt(now.Add(-dayDuration)), dbfake.WithJobCompletedAt(now.Add(-dayDuration+time.Minute))).
+			Do()
+
+		// Compare with the times as stored.
+		stored1, err := db.GetWorkspaceByID(ctx, w1.ID)
+		require.NoError(t, err)
+		stored2, err := db.GetWorkspaceByID(ctx, w2.ID)
+		require.NoError(t, err)
+		require.True(t, stored2.DeletingAt.Valid)
+		dormantAt := stored1.LastUsedAt.Add(14 * dayDuration)
+
+		// When: second run
+		notifEnq.Clear()
+		err = reportUserWorkspaces(ctx, logger, authedDB(t, db, logger), notifEnq, clk)
+
+		// Then
+		require.NoError(t, err)
+		sent := notifEnq.Sent()
+		require.Len(t, sent, 1)
+		require.Equal(t, user.ID, sent[0].UserID)
+		require.Equal(t, notifications.TemplateWorkspaceSummaryReport, sent[0].TemplateID)
+		require.Equal(t, "week", sent[0].Data["report_frequency"])
+		require.Equal(t, int64(2), sent[0].Data["total_builds"])
+		require.Equal(t, int64(1), sent[0].Data["failed_builds"])
+		require.Equal(t, []map[string]any{
+			{"name": w2.Name, "deleting_at": stored2.DeletingAt.Time.Format(userWorkspaceReportDateFormat)},
+		}, sent[0].Data["upcoming_deletions"])
+		require.Equal(t, []map[string]any{
+			{"name": w1.Name, "dormant_at": dormantAt.Format(userWorkspaceReportDateFormat)},
+		}, sent[0].Data["dormancy_warnings"])
+
+		report, err := db.GetLatestUserWorkspaceReportByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		var summary codersdk.UserWorkspaceReportSummary
+		require.NoError(t, json.Unmarshal(report.Summary, &summary))
+		require.EqualValues(t, 2, summary.TotalBuilds)
+		require.EqualValues(t, 1, summary.FailedBuilds)
+		require.Len(t, summary.Workspaces, 2)
+		require.Equal(t, w1.ID, summary.Workspaces[0].ID)
+		require.EqualValues(t, 2, summary.Workspaces[0].TotalBuilds)
+		require.NotNil(t, summary.Workspaces[0].DormantAt)
+		require.Nil(t, summary.Workspaces[0].DeletingAt)
+		require.Equal(t, w2.ID, summary.Workspaces[1].ID)
+		require.NotNil(t, summary.Workspaces[1].DeletingAt)
+		require.Nil(t, summary.Workspaces[1].DormantAt)
+
+		_, err = db.GetLatestUserWorkspaceReportByUserID(ctx, idleUser.ID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		// Given: a few days later
+		clk.Advance(3 * dayDuration)
+
+		// When: third run
+		notifEnq.Clear()
+		err = reportUserWorkspaces(ctx, logger, authedDB(t, db, logger), notifEnq, clk)
+
+		// Then: the report was sent recently
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+	})
+}
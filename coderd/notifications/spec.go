@@ -22,6 +22,7 @@ type Store interface {
 	EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) error
 	FetchNewMessageMetadata(ctx context.Context, arg database.FetchNewMessageMetadataParams) (database.FetchNewMessageMetadataRow, error)
 	GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error)
+	GetNotificationTemplateWebhookByTemplateID(ctx context.Context, notificationTemplateID uuid.UUID) (database.NotificationTemplateWebhook, error)
 	GetNotificationsSettings(ctx context.Context) (string, error)
 	GetApplicationName(ctx context.Context) (string, error)
 	GetLogoURL(ctx context.Context) (string, error)
@@ -5,13 +5,16 @@ import (
 	"slices"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 	"github.com/coder/serpent"
@@ -270,6 +273,133 @@ func TestNotificationPreferences(t *testing.T) {
 	})
 }
 
+func TestScopedNotificationPreferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Mute workspace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api, db := coderdtest.NewWithDatabase(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+
+		// Given: a member with a workspace and no scoped preferences.
+		memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        member.ID,
+			OrganizationID: firstUser.OrganizationID,
+		}).Do()
+		prefs, err := memberClient.ScopedNotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, prefs, 0)
+
+		// When: muting dormancy notifications for the workspace, and
+		// build failure notifications for its template.
+		prefs, err = memberClient.UpdateScopedNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateScopedNotificationPreferences{
+			Preferences: []codersdk.UpdateScopedNotificationPreference{
+				{
+					NotificationTemplateID: notifications.TemplateWorkspaceDormant,
+					Scope:                  codersdk.NotificationPreferenceScopeWorkspace,
+					TargetID:               r.Workspace.ID,
+					Disabled:               ptr.Ref(true),
+				},
+				{
+					NotificationTemplateID: notifications.TemplateWorkspaceBuildFailed,
+					Scope:                  codersdk.NotificationPreferenceScopeTemplate,
+					TargetID:               r.Workspace.TemplateID,
+					Disabled:               ptr.Ref(true),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		// Then: both preferences are returned.
+		require.Len(t, prefs, 2)
+		for _, pref := range prefs {
+			require.True(t, pref.Disabled)
+			switch pref.Scope {
+			case codersdk.NotificationPreferenceScopeWorkspace:
+				require.Equal(t, notifications.TemplateWorkspaceDormant, pref.NotificationTemplateID)
+				require.Equal(t, r.Workspace.ID, pref.TargetID)
+			case codersdk.NotificationPreferenceScopeTemplate:
+				require.Equal(t, notifications.TemplateWorkspaceBuildFailed, pref.NotificationTemplateID)
+				require.Equal(t, r.Workspace.TemplateID, pref.TargetID)
+			default:
+				t.Fatalf("unexpected scope %q", pref.Scope)
+			}
+		}
+
+		// When: removing the workspace preference.
+		prefs, err = memberClient.UpdateScopedNotificationPreferences(ctx, member.ID.String(), codersdk.UpdateScopedNotificationPreferences{
+			Preferences: []codersdk.UpdateScopedNotificationPreference{{
+				NotificationTemplateID: notifications.TemplateWorkspaceDormant,
+				Scope:                  codersdk.NotificationPreferenceScopeWorkspace,
+				TargetID:               r.Workspace.ID,
+			}},
+		})
+		require.NoError(t, err)
+
+		// Then: only the template preference remains.
+		require.Len(t, prefs, 1)
+		require.Equal(t, codersdk.NotificationPreferenceScopeTemplate, prefs[0].Scope)
+	})
+
+	t.Run("Inaccessible workspace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api, db := coderdtest.NewWithDatabase(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+
+		// Given: a workspace of another member.
+		memberClient, _ := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+		_, otherMember := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        otherMember.ID,
+			OrganizationID: firstUser.OrganizationID,
+		}).Do()
+
+		// When: muting notifications for that workspace.
+		_, err := memberClient.UpdateScopedNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateScopedNotificationPreferences{
+			Preferences: []codersdk.UpdateScopedNotificationPreference{{
+				NotificationTemplateID: notifications.TemplateWorkspaceDormant,
+				Scope:                  codersdk.NotificationPreferenceScopeWorkspace,
+				TargetID:               r.Workspace.ID,
+				Disabled:               ptr.Ref(true),
+			}},
+		})
+
+		// Then: the API should reject the request.
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+	})
+
+	t.Run("Unknown notification template", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api, db := coderdtest.NewWithDatabase(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        firstUser.UserID,
+			OrganizationID: firstUser.OrganizationID,
+		}).Do()
+
+		_, err := api.UpdateScopedNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateScopedNotificationPreferences{
+			Preferences: []codersdk.UpdateScopedNotificationPreference{{
+				NotificationTemplateID: uuid.New(),
+				Scope:                  codersdk.NotificationPreferenceScopeWorkspace,
+				TargetID:               r.Workspace.ID,
+				Disabled:               ptr.Ref(true),
+			}},
+		})
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+	})
+}
+
 func TestNotificationDispatchMethods(t *testing.T) {
 	t.Parallel()
 
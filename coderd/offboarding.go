@@ -0,0 +1,238 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxOffboardingPreviewUsers limits the number of users of a single preview,
+// since every user is looked up separately.
+const maxOffboardingPreviewUsers = 1000
+
+// An offboarding preview lists everything that is affected by offboarding a
+// set of users, so admins can make an informed decision before suspending or
+// deleting them. Nothing is changed by a preview.
+//
+// @Summary Preview offboarding of users
+// @ID preview-offboarding-of-users
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param request body codersdk.OffboardingPreviewRequest true "Users to offboard"
+// @Success 200 {object} codersdk.OffboardingPreview
+// @Router /api/v2/admin/offboarding/preview [post]
+func (api *API) postOffboardingPreview(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req codersdk.OffboardingPreviewRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionDelete, rbac.ResourceUser) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only user administrators may preview offboarding.",
+		})
+		return
+	}
+	if len(req.UserIDs) == 0 && req.GroupID == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Either user_ids or group_id is required.",
+		})
+		return
+	}
+
+	// The preview reads the resources of every selected user, which the
+	// caller is allowed to offboard.
+	//nolint:gocritic // Authorized above.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	userIDs := slices.Clone(req.UserIDs)
+	if req.GroupID != nil {
+		_, err := api.Database.GetGroupByID(sysCtx, *req.GroupID)
+		if errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Group %q does not exist.", req.GroupID.String()),
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		members, err := api.Database.GetGroupMembersByGroupID(sysCtx, database.GetGroupMembersByGroupIDParams{
+			GroupID:       *req.GroupID,
+			IncludeSystem: false,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group members.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		for _, member := range members {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+	slices.SortFunc(userIDs, func(a, b uuid.UUID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	userIDs = slices.Compact(userIDs)
+	if len(userIDs) > maxOffboardingPreviewUsers {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("At most %d users can be previewed at once, got %d.", maxOffboardingPreviewUsers, len(userIDs)),
+		})
+		return
+	}
+
+	users, err := api.Database.GetUsersByIDs(sysCtx, userIDs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching users.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(users) != len(userIDs) {
+		found := make(map[uuid.UUID]bool, len(users))
+		for _, user := range users {
+			found[user.ID] = true
+		}
+		var validErrs []codersdk.ValidationError
+		for _, id := range userIDs {
+			if !found[id] {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "user_ids", Detail: fmt.Sprintf("User %q does not exist.", id.String())})
+			}
+		}
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Unknown users.",
+			Validations: validErrs,
+		})
+		return
+	}
+	slices.SortFunc(users, func(a, b database.User) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	preview := codersdk.OffboardingPreview{
+		GeneratedAt: dbtime.Now(),
+		Users:       make([]codersdk.OffboardingPreviewUser, 0, len(users)),
+	}
+	for _, user := range users {
+		previewUser, err := api.offboardingPreviewUser(sysCtx, user)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error previewing offboarding.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		preview.Users = append(preview.Users, previewUser)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, preview)
+}
+
+// offboardingPreviewUser lists the resources of the user that are affected by
+// offboarding them.
+func (api *API) offboardingPreviewUser(ctx context.Context, user database.User) (codersdk.OffboardingPreviewUser, error) {
+	preview := codersdk.OffboardingPreviewUser{
+		UserID:            user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		Status:            codersdk.UserStatus(user.Status),
+		Workspaces:        []codersdk.OffboardingPreviewWorkspace{},
+		Templates:         []codersdk.OffboardingPreviewTemplate{},
+		Tokens:            []codersdk.OffboardingPreviewToken{},
+		ExternalAuthLinks: []codersdk.OffboardingPreviewExternalAuthLink{},
+	}
+
+	workspaces, err := api.Database.GetWorkspaces(ctx, database.GetWorkspacesParams{
+		OwnerID: user.ID,
+	})
+	if err != nil {
+		return codersdk.OffboardingPreviewUser{}, xerrors.Errorf("get workspaces: %w", err)
+	}
+	for _, ws := range workspaces {
+		var dormantAt *time.Time
+		if ws.DormantAt.Valid {
+			dormantAt = &ws.DormantAt.Time
+		}
+		preview.Workspaces = append(preview.Workspaces, codersdk.OffboardingPreviewWorkspace{
+			ID:                    ws.ID,
+			Name:                  ws.Name,
+			OrganizationName:      ws.OrganizationName,
+			TemplateName:          ws.TemplateName,
+			LatestBuildTransition: codersdk.WorkspaceTransition(ws.LatestBuildTransition),
+			LastUsedAt:            ws.LastUsedAt,
+			DormantAt:             dormantAt,
+		})
+	}
+
+	templates, err := api.Database.GetTemplatesWithFilter(ctx, database.GetTemplatesWithFilterParams{
+		AuthorID: user.ID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.OffboardingPreviewUser{}, xerrors.Errorf("get templates: %w", err)
+	}
+	for _, template := range templates {
+		preview.Templates = append(preview.Templates, codersdk.OffboardingPreviewTemplate{
+			ID:             template.ID,
+			Name:           template.Name,
+			DisplayName:    template.DisplayName,
+			OrganizationID: template.OrganizationID,
+		})
+	}
+
+	keys, err := api.Database.GetAPIKeysByUserID(ctx, database.GetAPIKeysByUserIDParams{
+		LoginType: database.LoginTypeToken,
+		UserID:    user.ID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.OffboardingPreviewUser{}, xerrors.Errorf("get tokens: %w", err)
+	}
+	for _, key := range keys {
+		preview.Tokens = append(preview.Tokens, codersdk.OffboardingPreviewToken{
+			ID:        key.ID,
+			TokenName: key.TokenName,
+			LastUsed:  key.LastUsed,
+			ExpiresAt: key.ExpiresAt,
+		})
+	}
+
+	links, err := api.Database.GetExternalAuthLinksByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.OffboardingPreviewUser{}, xerrors.Errorf("get external auth links: %w", err)
+	}
+	for _, link := range links {
+		preview.ExternalAuthLinks = append(preview.ExternalAuthLinks, codersdk.OffboardingPreviewExternalAuthLink{
+			ProviderID: link.ProviderID,
+			CreatedAt:  link.CreatedAt,
+			UpdatedAt:  link.UpdatedAt,
+		})
+	}
+
+	return preview, nil
+}
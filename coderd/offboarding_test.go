@@ -0,0 +1,84 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOffboardingPreview(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	userAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        member.ID,
+	}).Do()
+	token, err := memberClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{TokenName: "ci"})
+	require.NoError(t, err)
+	dbgen.ExternalAuthLink(t, db, database.ExternalAuthLink{
+		ProviderID: "github",
+		UserID:     member.ID,
+	})
+
+	preview, err := userAdminClient.OffboardingPreview(ctx, codersdk.OffboardingPreviewRequest{
+		UserIDs: []uuid.UUID{member.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, preview.Users, 1)
+	user := preview.Users[0]
+	require.Equal(t, member.ID, user.UserID)
+	require.Len(t, user.Workspaces, 1)
+	require.Equal(t, r.Workspace.ID, user.Workspaces[0].ID)
+	require.Equal(t, r.Workspace.LastUsedAt.UnixMilli(), user.Workspaces[0].LastUsedAt.UnixMilli())
+	// The template of the workspace was created by the member.
+	require.Len(t, user.Templates, 1)
+	require.Equal(t, r.Workspace.TemplateID, user.Templates[0].ID)
+	require.Len(t, user.Tokens, 1)
+	require.Equal(t, "ci", user.Tokens[0].TokenName)
+	require.Contains(t, token.Key, user.Tokens[0].ID)
+	require.Len(t, user.ExternalAuthLinks, 1)
+	require.Equal(t, "github", user.ExternalAuthLinks[0].ProviderID)
+
+	// Members of a group are previewed as well. The Everyone group has the
+	// ID of the organization.
+	preview, err = userAdminClient.OffboardingPreview(ctx, codersdk.OffboardingPreviewRequest{
+		UserIDs: []uuid.UUID{member.ID},
+		GroupID: &owner.OrganizationID,
+	})
+	require.NoError(t, err)
+	require.Len(t, preview.Users, 3)
+
+	var apiErr *codersdk.Error
+	_, err = userAdminClient.OffboardingPreview(ctx, codersdk.OffboardingPreviewRequest{
+		UserIDs: []uuid.UUID{uuid.New()},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = userAdminClient.OffboardingPreview(ctx, codersdk.OffboardingPreviewRequest{})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Only user administrators can preview offboarding.
+	_, err = memberClient.OffboardingPreview(ctx, codersdk.OffboardingPreviewRequest{
+		UserIDs: []uuid.UUID{member.ID},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
@@ -0,0 +1,295 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpapi/httperror"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get orphaned workspaces
+// @Description Workspaces are orphaned when their owner is removed outside of the normal
+// @Description offboarding flow, such as by a database cleanup or by merging accounts in
+// @Description the identity provider. Nobody can manage them until an admin adopts them
+// @Description to another owner or deletes them.
+// @ID get-orphaned-workspaces
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Success 200 {array} codersdk.OrphanedWorkspace
+// @Router /api/v2/admin/orphaned-workspaces [get]
+func (api *API) orphanedWorkspaces(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rows, err := api.Database.GetOrphanedWorkspaces(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching orphaned workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	workspaces := make([]codersdk.OrphanedWorkspace, 0, len(rows))
+	for _, row := range rows {
+		reason := codersdk.OrphanedWorkspaceReasonOwnerNotMember
+		if row.OwnerDeleted {
+			reason = codersdk.OrphanedWorkspaceReasonOwnerDeleted
+		}
+		var dormantAt *time.Time
+		if row.WorkspaceTable.DormantAt.Valid {
+			dormantAt = &row.WorkspaceTable.DormantAt.Time
+		}
+		workspaces = append(workspaces, codersdk.OrphanedWorkspace{
+			ID:               row.WorkspaceTable.ID,
+			Name:             row.WorkspaceTable.Name,
+			OrganizationID:   row.WorkspaceTable.OrganizationID,
+			OrganizationName: row.OrganizationName,
+			TemplateID:       row.WorkspaceTable.TemplateID,
+			TemplateName:     row.TemplateName,
+			OwnerID:          row.WorkspaceTable.OwnerID,
+			OwnerUsername:    row.OwnerUsername,
+			Reason:           reason,
+			CreatedAt:        row.WorkspaceTable.CreatedAt,
+			LastUsedAt:       row.WorkspaceTable.LastUsedAt,
+			DormantAt:        dormantAt,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, workspaces)
+}
+
+// @Summary Adopt orphaned workspace
+// @Description Transfers an orphaned workspace to a member of its organization.
+// @ID adopt-orphaned-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.AdoptOrphanedWorkspaceRequest true "Adopt orphaned workspace request"
+// @Success 204
+// @Router /api/v2/admin/orphaned-workspaces/{workspace}/adopt [post]
+func (api *API) postAdoptOrphanedWorkspace(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace.WorkspaceTable()
+
+	var req codersdk.AdoptOrphanedWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceWorkspace.InOrg(workspace.OrganizationID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	orphaned, err := api.workspaceOrphaned(ctx, workspace.OwnerID, workspace.OrganizationID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if !orphaned {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q is not orphaned.", workspace.Name),
+		})
+		return
+	}
+
+	name := workspace.Name
+	if req.Name != "" {
+		name = req.Name
+	}
+	if err := codersdk.NameValid(name); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid workspace name.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: err.Error(),
+			}},
+		})
+		return
+	}
+
+	// The new owner may not be visible to the caller, who is nonetheless
+	// allowed to manage every workspace of the organization.
+	//nolint:gocritic // Authorized above.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	owner, err := api.Database.GetUserByID(sysCtx, req.OwnerID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (owner.Deleted || owner.IsSystem)) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q does not exist.", req.OwnerID.String()),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	member, err := api.userIsOrganizationMember(sysCtx, owner.ID, workspace.OrganizationID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if !member {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q is not a member of the organization of the workspace.", owner.Username),
+		})
+		return
+	}
+
+	newWorkspace, err := api.Database.UpdateWorkspaceOwnerByID(ctx, database.UpdateWorkspaceOwnerByIDParams{
+		ID:        workspace.ID,
+		OwnerID:   owner.ID,
+		Name:      name,
+		UpdatedAt: dbtime.Now(),
+	})
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusMethodNotAllowed, codersdk.Response{
+				Message: fmt.Sprintf("Workspace %q is deleted and cannot be adopted.", workspace.Name),
+			})
+			return
+		}
+		if database.IsUniqueViolation(err) {
+			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("User %q already has a workspace named %q.", owner.Username, name),
+				Validations: []codersdk.ValidationError{{
+					Field:  "name",
+					Detail: "This value is already in use and should be unique.",
+				}},
+			})
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error adopting workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, owner.ID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindMetadataUpdate,
+		WorkspaceID: workspace.ID,
+	})
+
+	aReq.New = newWorkspace
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Delete orphaned workspace
+// @Description Starts a build that deletes an orphaned workspace.
+// @ID delete-orphaned-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 201 {object} codersdk.WorkspaceBuild
+// @Router /api/v2/admin/orphaned-workspaces/{workspace}/delete [post]
+func (api *API) postDeleteOrphanedWorkspace(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	orphaned, err := api.workspaceOrphaned(ctx, workspace.OwnerID, workspace.OrganizationID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if !orphaned {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q is not orphaned.", workspace.Name),
+		})
+		return
+	}
+
+	build, err := api.postWorkspaceBuildsInternal(
+		ctx,
+		apiKey,
+		workspace,
+		codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		},
+		func(action policy.Action, object rbac.Objecter) bool {
+			return api.Authorize(r, action, object)
+		},
+		audit.WorkspaceBuildBaggageFromRequest(r),
+	)
+	if err != nil {
+		httperror.WriteWorkspaceBuildError(ctx, rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, build)
+}
+
+// workspaceOrphaned reports whether the owner of a workspace was deleted, or
+// is no longer a member of the organization of the workspace. It matches the
+// conditions of GetOrphanedWorkspaces.
+func (api *API) workspaceOrphaned(ctx context.Context, ownerID, organizationID uuid.UUID) (bool, error) {
+	// The caller may not be able to read the former owner.
+	//nolint:gocritic // The workspace has been authorized by the caller.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	owner, err := api.Database.GetUserByID(ctx, ownerID)
+	if err != nil {
+		return false, xerrors.Errorf("get owner: %w", err)
+	}
+	if owner.IsSystem {
+		return false, nil
+	}
+	if owner.Deleted {
+		return true, nil
+	}
+	member, err := api.userIsOrganizationMember(ctx, ownerID, organizationID)
+	if err != nil {
+		return false, err
+	}
+	return !member, nil
+}
+
+func (api *API) userIsOrganizationMember(ctx context.Context, userID, organizationID uuid.UUID) (bool, error) {
+	members, err := api.Database.OrganizationMembers(ctx, database.OrganizationMembersParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		IncludeSystem:  false,
+	})
+	if err != nil {
+		return false, xerrors.Errorf("get organization membership: %w", err)
+	}
+	return len(members) > 0, nil
+}
@@ -0,0 +1,108 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOrphanedWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, deletedUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	_, formerMember := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	deleted := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        deletedUser.ID,
+		Name:           "dev",
+	}).Do()
+	left := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        formerMember.ID,
+	}).Do()
+	owned := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        member.ID,
+		Name:           "dev",
+	}).Do()
+
+	// Remove the users behind the back of the API, as a database cleanup or
+	// an account merge in the identity provider would.
+	err := db.UpdateUserDeletedByID(ctx, deletedUser.ID)
+	require.NoError(t, err)
+	err = db.DeleteOrganizationMember(ctx, database.DeleteOrganizationMemberParams{
+		OrganizationID: owner.OrganizationID,
+		UserID:         formerMember.ID,
+	})
+	require.NoError(t, err)
+
+	orphans, err := client.OrphanedWorkspaces(ctx)
+	require.NoError(t, err)
+	require.Len(t, orphans, 2)
+	reasons := map[string]codersdk.OrphanedWorkspaceReason{}
+	for _, orphan := range orphans {
+		reasons[orphan.ID.String()] = orphan.Reason
+	}
+	require.Equal(t, codersdk.OrphanedWorkspaceReasonOwnerDeleted, reasons[deleted.Workspace.ID.String()])
+	require.Equal(t, codersdk.OrphanedWorkspaceReasonOwnerNotMember, reasons[left.Workspace.ID.String()])
+
+	// Members only see orphaned workspaces they can read.
+	orphans, err = memberClient.OrphanedWorkspaces(ctx)
+	require.NoError(t, err)
+	require.Empty(t, orphans)
+
+	var apiErr *codersdk.Error
+	err = memberClient.AdoptOrphanedWorkspace(ctx, deleted.Workspace.ID, codersdk.AdoptOrphanedWorkspaceRequest{OwnerID: member.ID})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	// Workspaces with an active owner cannot be adopted.
+	err = client.AdoptOrphanedWorkspace(ctx, owned.Workspace.ID, codersdk.AdoptOrphanedWorkspaceRequest{OwnerID: owner.UserID})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	// The new owner must be a member of the organization.
+	err = client.AdoptOrphanedWorkspace(ctx, deleted.Workspace.ID, codersdk.AdoptOrphanedWorkspaceRequest{OwnerID: formerMember.ID})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// The member already has a workspace with the same name.
+	err = client.AdoptOrphanedWorkspace(ctx, deleted.Workspace.ID, codersdk.AdoptOrphanedWorkspaceRequest{OwnerID: member.ID})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	err = client.AdoptOrphanedWorkspace(ctx, deleted.Workspace.ID, codersdk.AdoptOrphanedWorkspaceRequest{
+		OwnerID: member.ID,
+		Name:    "adopted",
+	})
+	require.NoError(t, err)
+	workspace, err := memberClient.Workspace(ctx, deleted.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, member.ID, workspace.OwnerID)
+	require.Equal(t, "adopted", workspace.Name)
+
+	build, err := client.DeleteOrphanedWorkspace(ctx, left.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceTransitionDelete, build.Transition)
+
+	_, err = client.DeleteOrphanedWorkspace(ctx, owned.Workspace.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	orphans, err = client.OrphanedWorkspaces(ctx)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	require.Equal(t, left.Workspace.ID, orphans[0].ID)
+}
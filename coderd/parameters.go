@@ -34,6 +34,12 @@ func (api *API) templateVersionDynamicParametersEvaluate(rw http.ResponseWriter,
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
 	}
+	if req.OwnerID == uuid.Nil {
+		req.OwnerID = httpmw.APIKey(r).UserID
+	}
+	if req.Inputs == nil {
+		req.Inputs = map[string]string{}
+	}
 
 	api.templateVersionDynamicParameters(false, req)(rw, r)
 }
@@ -115,10 +121,11 @@ func (api *API) templateVersionDynamicParameters(listen bool, initial codersdk.D
 func (*API) handleParameterEvaluate(rw http.ResponseWriter, r *http.Request, initial codersdk.DynamicParametersRequest, render dynamicparameters.Renderer) {
 	ctx := r.Context()
 
-	// Send an initial form state, computed without any user input.
+	// Evaluate the form once against the given inputs. Parameters hidden by
+	// the inputs are omitted from the response.
 	result, diagnostics := render.Render(ctx, initial.OwnerID, initial.Inputs)
 	response := codersdk.DynamicParametersResponse{
-		ID:          0,
+		ID:          initial.ID,
 		Diagnostics: db2sdk.HCLDiagnostics(diagnostics),
 	}
 	if result != nil {
@@ -72,6 +72,68 @@ func TestDynamicParametersOwnerSSHPublicKey(t *testing.T) {
 	require.Equal(t, sshKey.PublicKey, preview.Parameters[0].Value.Value)
 }
 
+func TestDynamicParametersEvaluate(t *testing.T) {
+	t.Parallel()
+
+	ownerClient := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	member, _ := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+
+	// The "region" parameter only appears when "enable_region" is true.
+	_, version := coderdtest.DynamicParameterTemplate(t, ownerClient, owner.OrganizationID, coderdtest.DynamicParameterTemplateParams{
+		MainTF: `
+			terraform {
+			  required_providers {
+			    coder = {
+			      source = "coder/coder"
+			    }
+			  }
+			}
+			data "coder_workspace_owner" "me" {}
+			data "coder_parameter" "enable_region" {
+			  name    = "enable_region"
+			  order   = 1
+			  type    = "bool"
+			  default = "false"
+			}
+			data "coder_parameter" "region" {
+			  name  = "region"
+			  count = data.coder_parameter.enable_region.value == "true" ? 1 : 0
+			  order = 2
+			  type  = "string"
+			  option {
+			    name  = "US East"
+			    value = "us-east"
+			  }
+			  option {
+			    name  = "EU West"
+			    value = "eu-west"
+			  }
+			}
+		`,
+	})
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	// Omitting the owner evaluates the parameters for the caller.
+	res, err := member.EvaluateTemplateVersion(ctx, version.ID, uuid.Nil, map[string]string{})
+	require.NoError(t, err)
+	require.Empty(t, res.Diagnostics)
+	require.Len(t, res.Parameters, 1)
+	require.Equal(t, "enable_region", res.Parameters[0].Name)
+
+	res, err = member.EvaluateTemplateVersion(ctx, version.ID, uuid.Nil, map[string]string{
+		"enable_region": "true",
+		"region":        "ap-south",
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Parameters, 2)
+	region := res.Parameters[1]
+	require.Equal(t, "region", region.Name)
+	require.Len(t, region.Options, 2)
+	require.NotEmpty(t, region.Diagnostics, "value is not one of the options")
+}
+
 // TestDynamicParametersWithTerraformValues is for testing the websocket flow of
 // dynamic parameters. No workspaces are created.
 func TestDynamicParametersWithTerraformValues(t *testing.T) {
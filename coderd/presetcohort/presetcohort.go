@@ -0,0 +1,78 @@
+// Package presetcohort decides which presets of a template are available to a
+// user. A template administrator can restrict a preset, by name, to a cohort
+// of users who are members of given groups or have given roles. Presets
+// without a cohort are available to everyone who can use the template.
+package presetcohort
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+// Filter returns a function that reports whether the preset with the given
+// name is available to the user. Template administrators are not exempt;
+// callers that want them to see every preset must check that themselves.
+func Filter(ctx context.Context, db database.Store, templateID, organizationID, userID uuid.UUID) (func(presetName string) bool, error) {
+	// Users must be able to tell which presets they can use without being
+	// able to read the cohorts of the template, nor their own roles.
+	// nolint:gocritic // The template has been authorized by the caller.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	cohorts, err := db.GetTemplatePresetCohortsByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template preset cohorts: %w", err)
+	}
+	if len(cohorts) == 0 {
+		return func(string) bool { return true }, nil
+	}
+
+	row, err := db.GetAuthorizationUserRoles(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf("get user roles: %w", err)
+	}
+	roleNames, err := row.RoleNames()
+	if err != nil {
+		return nil, xerrors.Errorf("expand roles: %w", err)
+	}
+	roles := make(map[string]struct{}, len(roleNames))
+	for _, role := range roleNames {
+		// Site-wide roles and the roles of the organization of the template
+		// are matched by name alone.
+		if role.OrganizationID != uuid.Nil && role.OrganizationID != organizationID {
+			continue
+		}
+		roles[role.Name] = struct{}{}
+	}
+	groups := make(map[string]struct{}, len(row.Groups))
+	for _, group := range row.Groups {
+		groups[group] = struct{}{}
+	}
+
+	available := make(map[string]bool, len(cohorts))
+	for _, cohort := range cohorts {
+		available[cohort.PresetName] = inCohort(cohort, roles, groups)
+	}
+	return func(presetName string) bool {
+		ok, restricted := available[presetName]
+		return !restricted || ok
+	}, nil
+}
+
+func inCohort(cohort database.TemplatePresetCohort, roles, groups map[string]struct{}) bool {
+	for _, role := range cohort.Roles {
+		if _, ok := roles[role]; ok {
+			return true
+		}
+	}
+	for _, group := range cohort.GroupIds {
+		if _, ok := groups[group.String()]; ok {
+			return true
+		}
+	}
+	return false
+}
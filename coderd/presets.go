@@ -3,13 +3,19 @@ package coderd
 import (
 	"database/sql"
 	"net/http"
+	"slices"
 
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/presetcohort"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/codersdk"
 )
 
 // @Summary Get template version presets
+// @Description Presets targeted at a cohort the caller is not part of are left out, unless
+// @Description the caller administers the template.
 // @ID get-template-version-presets
 // @Security CoderSessionToken
 // @Produce json
@@ -30,6 +36,30 @@ func (api *API) templateVersionPresets(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if templateVersion.TemplateID.Valid {
+		template, err := api.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !api.Authorize(r, policy.ActionUpdate, template.RBACObject()) {
+			available, err := presetcohort.Filter(ctx, api.Database, template.ID, template.OrganizationID, httpmw.APIKey(r).UserID)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching template version presets.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			presets = slices.DeleteFunc(presets, func(preset database.TemplateVersionPreset) bool {
+				return !available(preset.Name)
+			})
+		}
+	}
+
 	presetParams, err := api.Database.GetPresetParametersByTemplateVersionID(ctx, templateVersion.ID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -39,6 +69,10 @@ func (api *API) templateVersionPresets(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	httpapi.Write(ctx, rw, http.StatusOK, convertPresets(presets, presetParams))
+}
+
+func convertPresets(presets []database.TemplateVersionPreset, presetParams []database.TemplateVersionPresetParameter) []codersdk.Preset {
 	convertPrebuildInstances := func(desiredInstances sql.NullInt32) *int {
 		if desiredInstances.Valid {
 			value := int(desiredInstances.Int32)
@@ -70,5 +104,5 @@ func (api *API) templateVersionPresets(rw http.ResponseWriter, r *http.Request)
 		res = append(res, sdkPreset)
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, res)
+	return res
 }
@@ -294,6 +294,17 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 		return nil, err
 	}
 
+	agentsClockSkewGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agents",
+		Name:      "clock_skew_seconds",
+		Help:      "The offset of the agent clock from the coderd clock in seconds, as last reported by the agent. Positive values mean the agent clock is ahead.",
+	}, []string{agentmetrics.LabelAgentName, agentmetrics.LabelUsername, agentmetrics.LabelWorkspaceName, agentmetrics.LabelTemplateName}))
+	err = registerer.Register(agentsClockSkewGauge)
+	if err != nil {
+		return nil, err
+	}
+
 	metricsCollectorAgents := prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: "coderd",
 		Subsystem: "prometheusmetrics",
@@ -401,6 +412,11 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 					}
 				}
 
+				if agent.WorkspaceAgent.ClockSkewMs.Valid {
+					clockSkew := time.Duration(agent.WorkspaceAgent.ClockSkewMs.Int64) * time.Millisecond
+					agentsClockSkewGauge.WithLabelValues(VectorOperationSet, clockSkew.Seconds(), agent.WorkspaceAgent.Name, agent.OwnerUsername, agent.WorkspaceName, agent.TemplateName)
+				}
+
 				// Collect information about registered applications
 				for _, app := range appsByAgentID[agent.WorkspaceAgent.ID] {
 					agentsAppsGauge.WithLabelValues(VectorOperationAdd, 1, agent.WorkspaceAgent.Name, agent.OwnerUsername, agent.WorkspaceName, app.DisplayName, string(app.Health))
@@ -411,6 +427,7 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 			agentsConnectionsGauge.Commit()
 			agentsConnectionLatenciesGauge.Commit()
 			agentsAppsGauge.Commit()
+			agentsClockSkewGauge.Commit()
 		}
 
 		for {
@@ -24,6 +24,7 @@ import (
 	"github.com/coder/coder/v2/coderd/agentmetrics"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -566,7 +567,13 @@ func TestAgents(t *testing.T) {
 	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
 	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
 	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
-	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	// nolint:gocritic // Agents report their clock skew, set it directly.
+	err := db.UpdateWorkspaceAgentClockSkewByID(dbauthz.AsSystemRestricted(context.Background()), database.UpdateWorkspaceAgentClockSkewByIDParams{
+		ID:          build.Resources[0].Agents[0].ID,
+		ClockSkewMs: sql.NullInt64{Int64: 90000, Valid: true},
+	})
+	require.NoError(t, err)
 
 	// given
 	derpMap, _ := tailnettest.RunDERPAndSTUN(t)
@@ -593,6 +600,7 @@ func TestAgents(t *testing.T) {
 	var agentsUp bool
 	var agentsConnections bool
 	var agentsApps bool
+	var agentsClockSkew bool
 	var agentsExecutionInSeconds bool
 	require.Eventually(t, func() bool {
 		metrics, err := registry.Gather()
@@ -628,13 +636,20 @@ func TestAgents(t *testing.T) {
 				assert.Equal(t, workspace.Name, metric.Metric[0].Label[4].GetValue())     // Workspace name
 				assert.Equal(t, 1, int(metric.Metric[0].Gauge.GetValue()))                // Metric value
 				agentsApps = true
+			case "coderd_agents_clock_skew_seconds":
+				assert.Equal(t, "testagent", metric.Metric[0].Label[0].GetValue())    // Agent name
+				assert.Equal(t, template.Name, metric.Metric[0].Label[1].GetValue())  // Template name
+				assert.Equal(t, "testuser", metric.Metric[0].Label[2].GetValue())     // Username
+				assert.Equal(t, workspace.Name, metric.Metric[0].Label[3].GetValue()) // Workspace name
+				assert.Equal(t, 90.0, metric.Metric[0].Gauge.GetValue())              // Metric value
+				agentsClockSkew = true
 			case "coderd_prometheusmetrics_agents_execution_seconds":
 				agentsExecutionInSeconds = true
 			default:
 				require.FailNowf(t, "unexpected metric collected", "metric: %s", metric.GetName())
 			}
 		}
-		return agentsUp && agentsConnections && agentsApps && agentsExecutionInSeconds
+		return agentsUp && agentsConnections && agentsApps && agentsClockSkew && agentsExecutionInSeconds
 	}, testutil.WaitShort, testutil.IntervalFast)
 }
 
@@ -0,0 +1,102 @@
+package coderd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get provisioner cache settings
+// @ID get-provisioner-cache-settings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.ProvisionerCacheSettings
+// @Router /api/v2/deployment/provisioner-cache [get]
+func (api *API) provisionerCacheSettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	settingsJSON, err := api.Database.GetProvisionerCacheSettings(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch current provisioner cache settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var settings codersdk.ProvisionerCacheSettings
+	if len(settingsJSON) > 0 {
+		err = json.Unmarshal([]byte(settingsJSON), &settings)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to unmarshal provisioner cache settings.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, settings)
+}
+
+// @Summary Update provisioner cache settings
+// @ID update-provisioner-cache-settings
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags General
+// @Param request body codersdk.ProvisionerCacheSettings true "Provisioner cache settings request"
+// @Success 200 {object} codersdk.ProvisionerCacheSettings
+// @Success 304
+// @Router /api/v2/deployment/provisioner-cache [put]
+func (api *API) putProvisionerCacheSettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var settings codersdk.ProvisionerCacheSettings
+	if !httpapi.Read(ctx, rw, r, &settings) {
+		return
+	}
+
+	settingsJSON, err := json.Marshal(&settings)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to marshal provisioner cache settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	currentSettingsJSON, err := api.Database.GetProvisionerCacheSettings(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch current provisioner cache settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if bytes.Equal(settingsJSON, []byte(currentSettingsJSON)) {
+		// See: https://www.rfc-editor.org/rfc/rfc7232#section-4.1
+		httpapi.Write(ctx, rw, http.StatusNotModified, nil)
+		return
+	}
+
+	err = api.Database.UpsertProvisionerCacheSettings(ctx, string(settingsJSON))
+	if err != nil {
+		if rbac.IsUnauthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update provisioner cache settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, settings)
+}
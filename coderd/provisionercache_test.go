@@ -0,0 +1,69 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestProvisionerCacheSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		firstUser := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		// Both caches are enabled until an admin changes the settings, and
+		// anyone can read them.
+		settings, err := memberClient.ProvisionerCacheSettings(ctx)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerCacheSettings{}, settings)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		expected := codersdk.ProvisionerCacheSettings{
+			DisableProviderCache: true,
+		}
+		err := client.PutProvisionerCacheSettings(ctx, expected)
+		require.NoError(t, err)
+
+		actual, err := client.ProvisionerCacheSettings(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+
+		// Applying the same settings again is not a modification.
+		err = client.PutProvisionerCacheSettings(ctx, expected)
+		require.NoError(t, err)
+	})
+
+	t.Run("PermissionDenied", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		firstUser := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		err := memberClient.PutProvisionerCacheSettings(ctx, codersdk.ProvisionerCacheSettings{
+			DisableModuleCache: true,
+		})
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusForbidden, sdkError.StatusCode())
+	})
+}
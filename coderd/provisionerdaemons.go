@@ -82,34 +82,36 @@ func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, slice.List(daemons, func(dbDaemon database.GetProvisionerDaemonsWithStatusByOrganizationRow) codersdk.ProvisionerDaemon {
-		pd := db2sdk.ProvisionerDaemon(dbDaemon.ProvisionerDaemon)
-		var currentJob, previousJob *codersdk.ProvisionerDaemonJob
-		if dbDaemon.CurrentJobID.Valid {
-			currentJob = &codersdk.ProvisionerDaemonJob{
-				ID:                  dbDaemon.CurrentJobID.UUID,
-				Status:              codersdk.ProvisionerJobStatus(dbDaemon.CurrentJobStatus.ProvisionerJobStatus),
-				TemplateName:        dbDaemon.CurrentJobTemplateName,
-				TemplateIcon:        dbDaemon.CurrentJobTemplateIcon,
-				TemplateDisplayName: dbDaemon.CurrentJobTemplateDisplayName,
-			}
+	httpapi.Write(ctx, rw, http.StatusOK, slice.List(daemons, convertProvisionerDaemonWithStatus))
+}
+
+func convertProvisionerDaemonWithStatus(dbDaemon database.GetProvisionerDaemonsWithStatusByOrganizationRow) codersdk.ProvisionerDaemon {
+	pd := db2sdk.ProvisionerDaemon(dbDaemon.ProvisionerDaemon)
+	var currentJob, previousJob *codersdk.ProvisionerDaemonJob
+	if dbDaemon.CurrentJobID.Valid {
+		currentJob = &codersdk.ProvisionerDaemonJob{
+			ID:                  dbDaemon.CurrentJobID.UUID,
+			Status:              codersdk.ProvisionerJobStatus(dbDaemon.CurrentJobStatus.ProvisionerJobStatus),
+			TemplateName:        dbDaemon.CurrentJobTemplateName,
+			TemplateIcon:        dbDaemon.CurrentJobTemplateIcon,
+			TemplateDisplayName: dbDaemon.CurrentJobTemplateDisplayName,
 		}
-		if dbDaemon.PreviousJobID.Valid {
-			previousJob = &codersdk.ProvisionerDaemonJob{
-				ID:                  dbDaemon.PreviousJobID.UUID,
-				Status:              codersdk.ProvisionerJobStatus(dbDaemon.PreviousJobStatus.ProvisionerJobStatus),
-				TemplateName:        dbDaemon.PreviousJobTemplateName,
-				TemplateIcon:        dbDaemon.PreviousJobTemplateIcon,
-				TemplateDisplayName: dbDaemon.PreviousJobTemplateDisplayName,
-			}
+	}
+	if dbDaemon.PreviousJobID.Valid {
+		previousJob = &codersdk.ProvisionerDaemonJob{
+			ID:                  dbDaemon.PreviousJobID.UUID,
+			Status:              codersdk.ProvisionerJobStatus(dbDaemon.PreviousJobStatus.ProvisionerJobStatus),
+			TemplateName:        dbDaemon.PreviousJobTemplateName,
+			TemplateIcon:        dbDaemon.PreviousJobTemplateIcon,
+			TemplateDisplayName: dbDaemon.PreviousJobTemplateDisplayName,
 		}
+	}
 
-		// Add optional fields.
-		pd.KeyName = &dbDaemon.KeyName
-		pd.Status = ptr.Ref(codersdk.ProvisionerDaemonStatus(dbDaemon.Status))
-		pd.CurrentJob = currentJob
-		pd.PreviousJob = previousJob
+	// Add optional fields.
+	pd.KeyName = &dbDaemon.KeyName
+	pd.Status = ptr.Ref(codersdk.ProvisionerDaemonStatus(dbDaemon.Status))
+	pd.CurrentJob = currentJob
+	pd.PreviousJob = previousJob
 
-		return pd
-	}))
+	return pd
 }
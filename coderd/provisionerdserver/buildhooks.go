@@ -0,0 +1,143 @@
+package provisionerdserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/buildhook"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+const (
+	preBuildHookStage  = "Pre-build hook"
+	postBuildHookStage = "Post-build hook"
+)
+
+// runPreBuildHook invokes the pre-build hook of the template, if any, before
+// a workspace build is handed to a provisioner. The build must not proceed
+// if an error is returned; its message is shown to the user.
+func (s *server) runPreBuildHook(ctx context.Context, job database.ProvisionerJob, template database.Template, workspace database.Workspace, build database.WorkspaceBuild) error {
+	if template.PreBuildHookURL == "" {
+		return nil
+	}
+
+	s.insertBuildHookLogs(ctx, job.ID, database.LogLevelInfo, preBuildHookStage, "Running pre-build hook")
+	resp, err := s.BuildHooks.Invoke(ctx, template.PreBuildHookURL, buildHookRequest(buildhook.EventPreBuild, template, workspace, build))
+	if err != nil {
+		var rejected *buildhook.RejectedError
+		if errors.As(err, &rejected) {
+			s.insertBuildHookLogs(ctx, job.ID, database.LogLevelError, preBuildHookStage, "Build rejected: "+rejected.Message)
+			return xerrors.Errorf("pre-build hook rejected the build: %s", rejected.Message)
+		}
+		s.insertBuildHookLogs(ctx, job.ID, database.LogLevelError, preBuildHookStage, err.Error())
+		return xerrors.Errorf("pre-build hook: %w", err)
+	}
+	s.insertBuildHookLogs(ctx, job.ID, database.LogLevelInfo, preBuildHookStage, buildHookStepLines(resp.Steps)...)
+	return nil
+}
+
+// runPostBuildHook invokes the post-build hook of the template, if any, after
+// a workspace build succeeded. The build already succeeded, so failures are
+// only recorded as warnings in the build logs.
+func (s *server) runPostBuildHook(ctx context.Context, job database.ProvisionerJob, build database.WorkspaceBuild) {
+	logger := s.Logger.With(slog.F("job_id", job.ID), slog.F("workspace_build_id", build.ID))
+	workspace, err := s.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		logger.Error(ctx, "get workspace for post-build hook", slog.Error(err))
+		return
+	}
+	template, err := s.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		logger.Error(ctx, "get template for post-build hook", slog.Error(err))
+		return
+	}
+	if template.PostBuildHookURL == "" {
+		return
+	}
+
+	s.insertBuildHookLogs(ctx, job.ID, database.LogLevelInfo, postBuildHookStage, "Running post-build hook")
+	resp, err := s.BuildHooks.Invoke(ctx, template.PostBuildHookURL, buildHookRequest(buildhook.EventPostBuild, template, workspace, build))
+	if err != nil {
+		logger.Warn(ctx, "post-build hook failed", slog.Error(err))
+		s.insertBuildHookLogs(ctx, job.ID, database.LogLevelWarn, postBuildHookStage, "Post-build hook failed: "+err.Error())
+		return
+	}
+	s.insertBuildHookLogs(ctx, job.ID, database.LogLevelInfo, postBuildHookStage, buildHookStepLines(resp.Steps)...)
+}
+
+func buildHookRequest(event buildhook.Event, template database.Template, workspace database.Workspace, build database.WorkspaceBuild) buildhook.Request {
+	return buildhook.Request{
+		Event: event,
+		Workspace: buildhook.Workspace{
+			ID:             workspace.ID,
+			Name:           workspace.Name,
+			OwnerID:        workspace.OwnerID,
+			OwnerName:      workspace.OwnerUsername,
+			OrganizationID: workspace.OrganizationID,
+			TemplateID:     template.ID,
+			TemplateName:   template.Name,
+		},
+		Build: buildhook.Build{
+			ID:                build.ID,
+			BuildNumber:       build.BuildNumber,
+			Transition:        codersdk.WorkspaceTransition(build.Transition),
+			Reason:            string(build.Reason),
+			TemplateVersionID: build.TemplateVersionID,
+			InitiatorID:       build.InitiatorID,
+		},
+	}
+}
+
+func buildHookStepLines(steps []buildhook.Step) []string {
+	lines := make([]string, 0, len(steps))
+	for _, step := range steps {
+		line := step.Name
+		if step.Detail != "" {
+			line = fmt.Sprintf("%s: %s", step.Name, step.Detail)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// insertBuildHookLogs appends lines to the logs of a build and notifies
+// log followers. Failures are logged, since build hook logs are
+// informational.
+func (s *server) insertBuildHookLogs(ctx context.Context, jobID uuid.UUID, level database.LogLevel, stage string, lines ...string) {
+	if len(lines) == 0 {
+		return
+	}
+	now := s.timeNow()
+	params := database.InsertProvisionerJobLogsParams{JobID: jobID}
+	for _, line := range lines {
+		params.CreatedAt = append(params.CreatedAt, now)
+		params.Source = append(params.Source, database.LogSourceProvisionerDaemon)
+		params.Level = append(params.Level, level)
+		params.Stage = append(params.Stage, stage)
+		params.Output = append(params.Output, line)
+	}
+	logs, err := s.Database.InsertProvisionerJobLogs(ctx, params)
+	if err != nil {
+		s.Logger.Error(ctx, "insert build hook logs", slog.F("job_id", jobID), slog.Error(err))
+		return
+	}
+	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{
+		CreatedAfter: logs[0].ID - 1,
+	})
+	if err != nil {
+		s.Logger.Error(ctx, "marshal build hook logs notification", slog.F("job_id", jobID), slog.Error(err))
+		return
+	}
+	err = s.Pubsub.Publish(provisionersdk.ProvisionerJobLogsNotifyChannel(jobID), data)
+	if err != nil {
+		s.Logger.Error(ctx, "publish build hook logs", slog.F("job_id", jobID), slog.Error(err))
+	}
+}
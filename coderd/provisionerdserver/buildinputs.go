@@ -0,0 +1,140 @@
+package provisionerdserver
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionerd/proto"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// recordWorkspaceBuildInputs persists the inputs a workspace build is
+// provisioned with, so the build can be reproduced in another deployment.
+// Tokens and keys passed to the provisioner, and the values of sensitive
+// template variables and parameters, are never recorded. Inputs are immutable, a retried
+// acquisition keeps the inputs recorded first.
+func (s *server) recordWorkspaceBuildInputs(ctx context.Context, job database.ProvisionerJob, sourceHash string, acquired *proto.AcquiredJob_WorkspaceBuild) error {
+	buildID, err := uuid.Parse(acquired.GetWorkspaceBuildId())
+	if err != nil {
+		return xerrors.Errorf("parse workspace build id: %w", err)
+	}
+	build, err := s.Database.GetWorkspaceBuildByID(ctx, buildID)
+	if err != nil {
+		return xerrors.Errorf("get workspace build: %w", err)
+	}
+	templateVersionParams, err := s.Database.GetTemplateVersionParameters(ctx, build.TemplateVersionID)
+	if err != nil {
+		return xerrors.Errorf("get template version parameters: %w", err)
+	}
+	sensitive := make(map[string]bool)
+	for _, param := range templateVersionParams {
+		if param.Sensitive {
+			sensitive[param.Name] = true
+		}
+	}
+
+	metadata := acquired.GetMetadata()
+	inputs := codersdk.WorkspaceBuildInputs{
+		WorkspaceBuildID:             build.ID,
+		TemplateVersionID:            build.TemplateVersionID,
+		TemplateVersionHash:          sourceHash,
+		TemplateVersionModulesFileID: metadata.GetTemplateVersionModulesFile(),
+		Transition:                   codersdk.WorkspaceTransition(build.Transition),
+		Reason:                       codersdk.BuildReason(build.Reason),
+		LogLevel:                     acquired.GetLogLevel(),
+		Parameters:                   buildInputParameters(acquired.GetRichParameterValues(), sensitive),
+		PreviousParameters:           buildInputParameters(acquired.GetPreviousParameterValues(), sensitive),
+		Variables:                    make([]codersdk.WorkspaceBuildInputVariable, 0, len(acquired.GetVariableValues())),
+		ProvisionerTags:              job.Tags,
+		TargetResources:              metadata.GetTargetResources(),
+		Metadata: codersdk.WorkspaceBuildInputMetadata{
+			AccessURL:               metadata.GetCoderUrl(),
+			WorkspaceName:           metadata.GetWorkspaceName(),
+			WorkspaceOwnerUsername:  metadata.GetWorkspaceOwner(),
+			WorkspaceOwnerName:      metadata.GetWorkspaceOwnerName(),
+			WorkspaceOwnerEmail:     metadata.GetWorkspaceOwnerEmail(),
+			WorkspaceOwnerLoginType: metadata.GetWorkspaceOwnerLoginType(),
+			WorkspaceOwnerGroups:    metadata.GetWorkspaceOwnerGroups(),
+			WorkspaceOwnerRoles:     make([]codersdk.SlimRole, 0, len(metadata.GetWorkspaceOwnerRbacRoles())),
+			TemplateName:            metadata.GetTemplateName(),
+			TemplateVersionName:     metadata.GetTemplateVersion(),
+			ExternalAuthProviders:   make([]string, 0, len(acquired.GetExternalAuthProviders())),
+			TaskPrompt:              metadata.GetTaskPrompt(),
+		},
+		Deployment: codersdk.WorkspaceBuildInputDeployment{
+			CoderVersion:        buildinfo.Version(),
+			OrganizationID:      job.OrganizationID,
+			Provisioner:         codersdk.ProvisionerType(job.Provisioner),
+			ProvisionerDaemonID: s.ID,
+		},
+	}
+	// Template, workspace and owner IDs are taken from the metadata the
+	// provisioner received, they are always set for workspace builds.
+	inputs.TemplateID, _ = uuid.Parse(metadata.GetTemplateId())
+	inputs.Metadata.WorkspaceID, _ = uuid.Parse(metadata.GetWorkspaceId())
+	inputs.Metadata.WorkspaceOwnerID, _ = uuid.Parse(metadata.GetWorkspaceOwnerId())
+	if taskID := metadata.GetTaskId(); taskID != uuid.Nil.String() {
+		inputs.Metadata.TaskID = taskID
+	}
+	if stage := metadata.GetPrebuiltWorkspaceBuildStage(); stage != sdkproto.PrebuiltWorkspaceBuildStage_NONE {
+		inputs.Metadata.PrebuiltWorkspaceBuildStage = strings.ToLower(stage.String())
+	}
+	for _, v := range acquired.GetVariableValues() {
+		variable := codersdk.WorkspaceBuildInputVariable{
+			Name:      v.GetName(),
+			Value:     v.GetValue(),
+			Sensitive: v.GetSensitive(),
+		}
+		if variable.Sensitive {
+			variable.Value = ""
+		}
+		inputs.Variables = append(inputs.Variables, variable)
+	}
+	for _, role := range metadata.GetWorkspaceOwnerRbacRoles() {
+		inputs.Metadata.WorkspaceOwnerRoles = append(inputs.Metadata.WorkspaceOwnerRoles, codersdk.SlimRole{
+			Name:           role.GetName(),
+			OrganizationID: role.GetOrgId(),
+		})
+	}
+	for _, provider := range acquired.GetExternalAuthProviders() {
+		inputs.Metadata.ExternalAuthProviders = append(inputs.Metadata.ExternalAuthProviders, provider.GetId())
+	}
+
+	raw, err := json.Marshal(inputs)
+	if err != nil {
+		return xerrors.Errorf("marshal inputs: %w", err)
+	}
+	err = s.Database.InsertWorkspaceBuildInputs(ctx, database.InsertWorkspaceBuildInputsParams{
+		WorkspaceBuildID: build.ID,
+		Inputs:           raw,
+		CreatedAt:        s.timeNow(),
+	})
+	if err != nil {
+		return xerrors.Errorf("insert inputs: %w", err)
+	}
+	return nil
+}
+
+// buildInputParameters converts the parameter values, leaving the values
+// of sensitive parameters empty.
+func buildInputParameters(values []*sdkproto.RichParameterValue, sensitive map[string]bool) []codersdk.WorkspaceBuildParameter {
+	parameters := make([]codersdk.WorkspaceBuildParameter, 0, len(values))
+	for _, v := range values {
+		parameter := codersdk.WorkspaceBuildParameter{
+			Name:  v.GetName(),
+			Value: v.GetValue(),
+		}
+		if sensitive[parameter.Name] {
+			parameter.Value = ""
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
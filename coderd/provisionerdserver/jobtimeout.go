@@ -0,0 +1,104 @@
+package provisionerdserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+const (
+	// MinJobTimeout and MaxJobTimeout bound the per-template plan and apply
+	// timeouts. Anything shorter than a minute is almost certainly a mistake,
+	// and anything longer than a day is better served by the hung job reaper.
+	MinJobTimeout = time.Minute
+	MaxJobTimeout = 24 * time.Hour
+)
+
+// jobTimeoutStage is the stage name used for the log line emitted when a job
+// exceeds its template's timeout.
+const jobTimeoutStage = "Timeout"
+
+// jobTimeout returns the maximum duration of the job as configured on its
+// template. Workspace builds are bound by the apply timeout, template version
+// imports and dry-runs by the plan timeout. A zero duration means the job is
+// not subject to a timeout. It is only called when the job is acquired, and
+// the result is stored on the job.
+func (s *server) jobTimeout(ctx context.Context, job database.ProvisionerJob) (time.Duration, error) {
+	var templateID uuid.NullUUID
+	switch job.Type {
+	case database.ProvisionerJobTypeWorkspaceBuild:
+		var input WorkspaceProvisionJob
+		if err := json.Unmarshal(job.Input, &input); err != nil {
+			return 0, xerrors.Errorf("unmarshal workspace provision input: %w", err)
+		}
+		build, err := s.Database.GetWorkspaceBuildByID(ctx, input.WorkspaceBuildID)
+		if err != nil {
+			return 0, xerrors.Errorf("get workspace build: %w", err)
+		}
+		workspace, err := s.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+		if err != nil {
+			return 0, xerrors.Errorf("get workspace: %w", err)
+		}
+		template, err := s.Database.GetTemplateByID(ctx, workspace.TemplateID)
+		if err != nil {
+			return 0, xerrors.Errorf("get template: %w", err)
+		}
+		return time.Duration(template.ProvisionerApplyTimeout), nil
+	case database.ProvisionerJobTypeTemplateVersionImport:
+		var input TemplateVersionImportJob
+		if err := json.Unmarshal(job.Input, &input); err != nil {
+			return 0, xerrors.Errorf("unmarshal template version import input: %w", err)
+		}
+		templateID = input.TemplateID
+		if !templateID.Valid {
+			templateVersion, err := s.Database.GetTemplateVersionByID(ctx, input.TemplateVersionID)
+			if err != nil {
+				return 0, xerrors.Errorf("get template version: %w", err)
+			}
+			templateID = templateVersion.TemplateID
+		}
+	case database.ProvisionerJobTypeTemplateVersionDryRun:
+		var input TemplateVersionDryRunJob
+		if err := json.Unmarshal(job.Input, &input); err != nil {
+			return 0, xerrors.Errorf("unmarshal template version dry-run input: %w", err)
+		}
+		templateVersion, err := s.Database.GetTemplateVersionByID(ctx, input.TemplateVersionID)
+		if err != nil {
+			return 0, xerrors.Errorf("get template version: %w", err)
+		}
+		templateID = templateVersion.TemplateID
+	}
+
+	// Template versions are not required to belong to a template.
+	if !templateID.Valid || templateID.UUID == uuid.Nil {
+		return 0, nil
+	}
+	template, err := s.Database.GetTemplateByID(ctx, templateID.UUID)
+	if err != nil {
+		return 0, xerrors.Errorf("get template: %w", err)
+	}
+	return time.Duration(template.ProvisionerPlanTimeout), nil
+}
+
+// jobTimedOut reports whether the running job has exceeded the timeout that
+// was recorded on it when it was acquired.
+func jobTimedOut(job database.ProvisionerJob, now time.Time) bool {
+	if !job.StartedAt.Valid || job.Timeout <= 0 {
+		return false
+	}
+	return now.Sub(job.StartedAt.Time) > time.Duration(job.Timeout)
+}
+
+func jobTimeoutMessage(job database.ProvisionerJob) string {
+	kind := "plan"
+	if job.Type == database.ProvisionerJobTypeWorkspaceBuild {
+		kind = "apply"
+	}
+	return fmt.Sprintf("Coder: Job exceeded the template's %s timeout of %s and has been canceled.", kind, time.Duration(job.Timeout))
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
 )
 
 type Metrics struct {
@@ -14,6 +16,8 @@ type Metrics struct {
 	workspaceCreationTimings *prometheus.HistogramVec
 	workspaceClaimTimings    *prometheus.HistogramVec
 	jobQueueWait             *prometheus.HistogramVec
+	workspaceBuildQueueWait  *prometheus.HistogramVec
+	workspaceBuildsCompleted *prometheus.CounterVec
 }
 
 type WorkspaceTimingType int
@@ -30,6 +34,13 @@ const (
 	workspaceTypePrebuild = "prebuild"
 )
 
+// Metric label values for the status of a completed workspace build.
+const (
+	BuildStatusSucceeded = "succeeded"
+	BuildStatusFailed    = "failed"
+	BuildStatusCanceled  = "canceled"
+)
+
 // BuildReasonPrebuild is the build_reason metric label value for prebuild
 // operations. This is distinct from database.BuildReason values since prebuilds
 // use BuildReasonInitiator in the database but we want to track them separately
@@ -121,6 +132,35 @@ func NewMetrics(logger slog.Logger) *Metrics {
 			NativeHistogramZeroThreshold:    0,
 			NativeHistogramMaxZeroThreshold: 0,
 		}, []string{"provisioner_type", "job_type", "transition", "build_reason"}),
+		workspaceBuildQueueWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "coderd",
+			Name:      "workspace_build_queue_wait_seconds",
+			Help:      "Time from workspace build creation to acquisition by a provisioner daemon, by organization and template.",
+			Buckets: []float64{
+				0.1,  // 100ms
+				0.5,  // 500ms
+				1,    // 1s
+				5,    // 5s
+				10,   // 10s
+				30,   // 30s
+				60,   // 1m
+				120,  // 2m
+				300,  // 5m
+				600,  // 10m
+				900,  // 15m
+				1800, // 30m
+			},
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  100,
+			NativeHistogramMinResetDuration: time.Hour,
+			NativeHistogramZeroThreshold:    0,
+			NativeHistogramMaxZeroThreshold: 0,
+		}, []string{"organization_name", "template_name", "transition", "build_reason"}),
+		workspaceBuildsCompleted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Name:      "workspace_builds_completed_total",
+			Help:      "Total number of completed workspace builds by organization, template, transition, build reason, and status (succeeded, failed or canceled).",
+		}, []string{"organization_name", "template_name", "transition", "build_reason", "status"}),
 	}
 }
 
@@ -131,7 +171,13 @@ func (m *Metrics) Register(reg prometheus.Registerer) error {
 	if err := reg.Register(m.workspaceClaimTimings); err != nil {
 		return err
 	}
-	return reg.Register(m.jobQueueWait)
+	if err := reg.Register(m.jobQueueWait); err != nil {
+		return err
+	}
+	if err := reg.Register(m.workspaceBuildQueueWait); err != nil {
+		return err
+	}
+	return reg.Register(m.workspaceBuildsCompleted)
 }
 
 // IsTrackable returns true if the workspace build should be tracked in metrics.
@@ -202,3 +248,26 @@ func (m *Metrics) UpdateWorkspaceTimingsMetrics(
 func (m *Metrics) ObserveJobQueueWait(provisionerType, jobType, transition, buildReason string, waitSeconds float64) {
 	m.jobQueueWait.WithLabelValues(provisionerType, jobType, transition, buildReason).Observe(waitSeconds)
 }
+
+// ObserveWorkspaceBuildQueueWait records the time a workspace build job spent
+// waiting in the queue, labeled by the organization and template it belongs to.
+func (m *Metrics) ObserveWorkspaceBuildQueueWait(organizationName, templateName, transition, buildReason string, waitSeconds float64) {
+	m.workspaceBuildQueueWait.WithLabelValues(organizationName, templateName, transition, buildReason).Observe(waitSeconds)
+}
+
+// RecordWorkspaceBuildCompleted counts a workspace build that reached a
+// terminal state. status is one of BuildStatusSucceeded, BuildStatusFailed or
+// BuildStatusCanceled.
+func (m *Metrics) RecordWorkspaceBuildCompleted(organizationName, templateName, transition, buildReason, status string) {
+	m.workspaceBuildsCompleted.WithLabelValues(organizationName, templateName, transition, buildReason, status).Inc()
+}
+
+// buildReasonLabel returns the build_reason label value for a workspace build.
+// Prebuilds use BuildReasonInitiator in the database, so they are detected by
+// the initiator and reported separately.
+func buildReasonLabel(initiatorID uuid.UUID, reason database.BuildReason) string {
+	if initiatorID == database.PrebuildsSystemUserID {
+		return BuildReasonPrebuild
+	}
+	return string(reason)
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -31,6 +32,9 @@ import (
 	"github.com/coder/coder/v2/coderd/aiseats"
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/buildfailure"
+	"github.com/coder/coder/v2/coderd/buildhook"
+	"github.com/coder/coder/v2/coderd/buildtrace"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -78,6 +82,12 @@ type Options struct {
 	OIDCConfig          promoauth.OAuth2Config
 	ExternalAuthConfigs []*externalauth.Config
 	AISeatTracker       aiseats.SeatTracker
+	// BuildHooks invokes the pre-build and post-build hooks of templates.
+	// Defaults to a client using http.DefaultClient.
+	BuildHooks *buildhook.Client
+	// BuildTraces exports finished workspace builds as traces. Builds are
+	// not exported if it is nil.
+	BuildTraces *buildtrace.Exporter
 
 	// Clock for testing
 	Clock quartz.Clock
@@ -94,6 +104,11 @@ type Options struct {
 	// The default function just calls UpdateProvisionerDaemonLastSeenAt.
 	// This is mainly used for testing.
 	HeartbeatFn func(context.Context) error
+
+	// Drain stops the daemon from acquiring new jobs once it's closed, for
+	// example because the key it authenticated with was revoked. Jobs it
+	// acquired before still complete.
+	Drain <-chan struct{}
 }
 
 type server struct {
@@ -123,6 +138,8 @@ type server struct {
 	PrebuildsOrchestrator       *atomic.Pointer[prebuilds.ReconciliationOrchestrator]
 	UsageInserter               *atomic.Pointer[usage.Inserter]
 	AISeatTracker               aiseats.SeatTracker
+	BuildHooks                  *buildhook.Client
+	BuildTraces                 *buildtrace.Exporter
 	Experiments                 codersdk.Experiments
 
 	OIDCConfig promoauth.OAuth2Config
@@ -130,6 +147,7 @@ type server struct {
 	Clock quartz.Clock
 
 	acquireJobLongPollDur time.Duration
+	drain                 <-chan struct{}
 
 	heartbeatInterval time.Duration
 	heartbeatFn       func(ctx context.Context) error
@@ -221,6 +239,9 @@ func NewServer(
 	if options.AISeatTracker == nil {
 		options.AISeatTracker = aiseats.Noop{}
 	}
+	if options.BuildHooks == nil {
+		options.BuildHooks = buildhook.New(http.DefaultClient)
+	}
 	if options.AcquireJobLongPollDur == 0 {
 		options.AcquireJobLongPollDur = DefaultAcquireJobLongPollDur
 	}
@@ -255,11 +276,14 @@ func NewServer(
 		OIDCConfig:                  options.OIDCConfig,
 		Clock:                       options.Clock,
 		acquireJobLongPollDur:       options.AcquireJobLongPollDur,
+		drain:                       options.Drain,
 		heartbeatInterval:           options.HeartbeatInterval,
 		heartbeatFn:                 options.HeartbeatFn,
 		PrebuildsOrchestrator:       prebuildsOrchestrator,
 		UsageInserter:               usageInserter,
 		AISeatTracker:               options.AISeatTracker,
+		BuildHooks:                  options.BuildHooks,
+		BuildTraces:                 options.BuildTraces,
 		metrics:                     metrics,
 		Experiments:                 experiments,
 	}
@@ -272,6 +296,16 @@ func NewServer(
 	return s, nil
 }
 
+// draining returns true once the daemon must stop acquiring new jobs.
+func (s *server) draining() bool {
+	select {
+	case <-s.drain:
+		return true
+	default:
+		return false
+	}
+}
+
 // timeNow should be used when trying to get the current time for math
 // calculations regarding workspace start and stop time.
 func (s *server) timeNow(tags ...string) time.Time {
@@ -339,6 +373,12 @@ func (s *server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Acquire
 	// database.
 	acqCtx, acqCancel := context.WithTimeout(ctx, s.acquireJobLongPollDur)
 	defer acqCancel()
+	if s.draining() {
+		// Hold the request for the long poll duration, so that the drained
+		// daemon doesn't poll in a tight loop.
+		<-acqCtx.Done()
+		return &proto.AcquiredJob{}, nil
+	}
 	job, err := s.Acquirer.AcquireJob(acqCtx, s.OrganizationID, s.ID, s.Provisioners, s.Tags)
 	if database.IsQueryCanceledError(err) {
 		s.Logger.Debug(ctx, "successful cancel")
@@ -374,6 +414,15 @@ func (s *server) AcquireJobWithCancel(stream proto.DRPCProvisionerDaemon_Acquire
 		_, err := stream.Recv() // cancel is the only message
 		recvCh <- err
 	}()
+	if s.draining() {
+		// A drained daemon doesn't acquire jobs, so hold the request until
+		// the daemon cancels it.
+		s.Logger.Debug(streamCtx, "daemon is draining, not acquiring job")
+		if err := <-recvCh; err != nil {
+			return err
+		}
+		return stream.Send(&proto.AcquiredJob{})
+	}
 	jec := make(chan jobAndErr, 1)
 	go func() {
 		job, err := s.Acquirer.AcquireJob(acqCtx, s.OrganizationID, s.ID, s.Provisioners, s.Tags)
@@ -385,6 +434,9 @@ func (s *server) AcquireJobWithCancel(stream proto.DRPCProvisionerDaemon_Acquire
 	case recvErr = <-recvCh:
 		acqCancel()
 		je = <-jec
+	case <-s.drain:
+		acqCancel()
+		je = <-jec
 	case je = <-jec:
 	}
 	if database.IsQueryCanceledError(je.err) {
@@ -469,6 +521,21 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		return nil, failJob(fmt.Sprintf("get user: %s", err))
 	}
 
+	// The timeout is worked out once here and stored on the job, so that
+	// UpdateJob and FailJob only have to compare times.
+	timeout, err := s.jobTimeout(ctx, job)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to determine job timeout", slog.F("job_id", job.ID), slog.Error(err))
+	} else if timeout > 0 {
+		err = s.Database.UpdateProvisionerJobTimeoutByID(ctx, database.UpdateProvisionerJobTimeoutByIDParams{
+			ID:      job.ID,
+			Timeout: int64(timeout),
+		})
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("update job timeout: %s", err))
+		}
+	}
+
 	jobTraceMetadata := map[string]string{}
 	if job.TraceMetadata.Valid {
 		err := json.Unmarshal(job.TraceMetadata.RawMessage, &jobTraceMetadata)
@@ -477,6 +544,18 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		}
 	}
 
+	var cacheSettings codersdk.ProvisionerCacheSettings
+	cacheSettingsJSON, err := s.Database.GetProvisionerCacheSettings(ctx)
+	if err != nil {
+		return nil, failJob(fmt.Sprintf("get provisioner cache settings: %s", err))
+	}
+	if len(cacheSettingsJSON) > 0 {
+		err = json.Unmarshal([]byte(cacheSettingsJSON), &cacheSettings)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("unmarshal provisioner cache settings: %s", err))
+		}
+	}
+
 	protoJob := &proto.AcquiredJob{
 		JobId:         job.ID.String(),
 		CreatedAt:     job.CreatedAt.UnixMilli(),
@@ -485,9 +564,12 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		TraceMetadata: jobTraceMetadata,
 	}
 
-	// jobTransition and jobBuildReason are used for metrics; only set for workspace builds.
+	// jobTransition, jobBuildReason, jobOrganizationName and jobTemplateName
+	// are used for metrics; only set for workspace builds.
 	var jobTransition string
 	var jobBuildReason string
+	var jobOrganizationName string
+	var jobTemplateName string
 
 	switch job.Type {
 	case database.ProvisionerJobTypeWorkspaceBuild:
@@ -520,10 +602,13 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get owner: %s", err))
 		}
+		if err := s.runPreBuildHook(ctx, job, template, workspace, workspaceBuild); err != nil {
+			return nil, failJob(err.Error())
+		}
 
 		// Fetch the file id of the cached module files if it exists.
 		versionModulesFile := ""
-		if !template.DisableModuleCache {
+		if !template.DisableModuleCache && !cacheSettings.DisableModuleCache {
 			tfvals, err := s.Database.GetTemplateVersionTerraformValues(ctx, templateVersion.ID)
 			if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
 				// Older templates (before dynamic parameters) will not have cached module files.
@@ -596,14 +681,9 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 			return nil, failJob(fmt.Sprintf("convert workspace transition: %s", err))
 		}
 		jobTransition = string(workspaceBuild.Transition)
-		// Prebuilds use BuildReasonInitiator in the database but we want to
-		// track them separately in metrics. Check the initiator ID to detect
-		// prebuild jobs.
-		if job.InitiatorID == database.PrebuildsSystemUserID {
-			jobBuildReason = BuildReasonPrebuild
-		} else {
-			jobBuildReason = string(workspaceBuild.Reason)
-		}
+		jobBuildReason = buildReasonLabel(job.InitiatorID, workspaceBuild.Reason)
+		jobOrganizationName = workspace.OrganizationName
+		jobTemplateName = workspace.TemplateName
 
 		// A previous workspace build exists
 		var lastWorkspaceBuildParameters []database.WorkspaceBuildParameter
@@ -772,6 +852,9 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 					TaskId:                        task.ID.String(),
 					TaskPrompt:                    task.Prompt,
 					TemplateVersionModulesFile:    versionModulesFile,
+					TargetResources:               input.TargetResources,
+					WorkspaceArchive:              workspaceBuild.Reason == database.BuildReasonArchive,
+					DisableProviderCache:          template.DisableProviderCache || cacheSettings.DisableProviderCache,
 				},
 				LogLevel: input.LogLevel,
 			},
@@ -802,6 +885,7 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 					// There is no owner for a template import, but we can assume
 					// the "Everyone" group as a placeholder.
 					WorkspaceOwnerGroups: []string{database.EveryoneGroup},
+					DisableProviderCache: cacheSettings.DisableProviderCache,
 				},
 			},
 		}
@@ -832,10 +916,12 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 					WorkspaceOwnerGroups: []string{database.EveryoneGroup},
 					TemplateId:           templateID,
 					TemplateVersionId:    input.TemplateVersionID.String(),
+					DisableProviderCache: cacheSettings.DisableProviderCache,
 				},
 			},
 		}
 	}
+	var sourceHash string
 	switch job.StorageMethod {
 	case database.ProvisionerStorageMethodFile:
 		file, err := s.Database.GetFileByID(ctx, job.FileID)
@@ -843,12 +929,19 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 			return nil, failJob(fmt.Sprintf("get file by id: %s", err))
 		}
 		protoJob.TemplateSourceArchive = file.Data
+		sourceHash = file.Hash
 	default:
 		return nil, failJob(fmt.Sprintf("unsupported storage method: %s", job.StorageMethod))
 	}
 	if protobuf.Size(protoJob) > drpcsdk.MaxMessageSize {
 		return nil, failJob(fmt.Sprintf("payload was too big: %d > %d", protobuf.Size(protoJob), drpcsdk.MaxMessageSize))
 	}
+	if workspaceBuild := protoJob.GetWorkspaceBuild(); workspaceBuild != nil {
+		err = s.recordWorkspaceBuildInputs(ctx, job, sourceHash, workspaceBuild)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("record workspace build inputs: %s", err))
+		}
+	}
 
 	// Record the time the job spent waiting in the queue.
 	if s.metrics != nil && job.StartedAt.Valid && job.Provisioner.Valid() {
@@ -858,6 +951,9 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		// delta while acknowledging there's a non-zero queue time.
 		queueWaitSeconds := max(job.StartedAt.Time.Sub(job.CreatedAt).Seconds(), 0.001)
 		s.metrics.ObserveJobQueueWait(string(job.Provisioner), string(job.Type), jobTransition, jobBuildReason, queueWaitSeconds)
+		if job.Type == database.ProvisionerJobTypeWorkspaceBuild {
+			s.metrics.ObserveWorkspaceBuildQueueWait(jobOrganizationName, jobTemplateName, jobTransition, jobBuildReason, queueWaitSeconds)
+		}
 	}
 
 	return protoJob, err
@@ -970,6 +1066,28 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 	if job.WorkerID.UUID.String() != s.ID.String() {
 		return nil, xerrors.New("you don't own this job")
 	}
+
+	// Jobs that run past their template's timeout are canceled the same way
+	// user cancellations are: provisionerd is told to cancel on the next
+	// update and reports the failure through FailJob.
+	canceled := job.CanceledAt.Valid
+	if !canceled && jobTimedOut(job, s.timeNow()) {
+		canceled = true
+		timeout := time.Duration(job.Timeout)
+		// Only log the timeout the first time it is noticed.
+		if !job.UpdatedAt.After(job.StartedAt.Time.Add(timeout)) {
+			s.Logger.Info(ctx, "job exceeded template timeout, canceling",
+				slog.F("job_id", parsedID), slog.F("timeout", timeout))
+			request.Logs = append(request.Logs, &proto.Log{
+				Source:    proto.LogSource_PROVISIONER_DAEMON,
+				Level:     sdkproto.LogLevel_ERROR,
+				CreatedAt: s.timeNow().UnixMilli(),
+				Stage:     jobTimeoutStage,
+				Output:    jobTimeoutMessage(job),
+			})
+		}
+	}
+
 	err = s.Database.UpdateProvisionerJobByID(ctx, database.UpdateProvisionerJobByIDParams{
 		ID:        parsedID,
 		UpdatedAt: s.timeNow(),
@@ -1060,7 +1178,7 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 					s.Logger.Error(ctx, "failed to set logs overflowed flag", slog.F("job_id", parsedID), slog.Error(err))
 				}
 				return &proto.UpdateJobResponse{
-					Canceled: job.CanceledAt.Valid,
+					Canceled: canceled,
 				}, nil
 			}
 			s.Logger.Error(ctx, "failed to update logs length", slog.F("job_id", parsedID), slog.Error(err))
@@ -1171,13 +1289,13 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 		}
 
 		return &proto.UpdateJobResponse{
-			Canceled:       job.CanceledAt.Valid,
+			Canceled:       canceled,
 			VariableValues: variableValues,
 		}, nil
 	}
 
 	return &proto.UpdateJobResponse{
-		Canceled: job.CanceledAt.Valid,
+		Canceled: canceled,
 	}, nil
 }
 
@@ -1210,6 +1328,14 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 		String: failJob.Error,
 		Valid:  failJob.Error != "",
 	}
+	// A job canceled for running past its template's timeout reports a
+	// generic cancellation error, so replace it with something actionable.
+	if !job.CanceledAt.Valid && jobTimedOut(job, s.timeNow()) {
+		job.Error = sql.NullString{
+			String: jobTimeoutMessage(job),
+			Valid:  true,
+		}
+	}
 	job.ErrorCode = sql.NullString{
 		String: failJob.ErrorCode,
 		Valid:  failJob.ErrorCode != "",
@@ -1250,6 +1376,19 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 				return xerrors.Errorf("get workspace: %w", err)
 			}
 
+			// Record the timings of the stages that ran, so that the logs
+			// of the failed stage can be located.
+			if len(jobType.WorkspaceBuild.Timings) > 0 {
+				err = insertProvisionerJobTimings(ctx, db, s.Logger.With(
+					slog.F("job_id", jobID.String()),
+					slog.F("workspace_id", workspace.ID),
+					slog.F("workspace_build_id", build.ID),
+				), jobID, jobType.WorkspaceBuild.Timings)
+				if err != nil {
+					return err
+				}
+			}
+
 			if jobType.WorkspaceBuild.State != nil {
 				err = db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
 					ID:               input.WorkspaceBuildID,
@@ -1283,7 +1422,17 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 			return nil, err
 		}
 
-		s.notifyWorkspaceBuildFailed(ctx, workspace, build)
+		if s.metrics != nil {
+			status := BuildStatusFailed
+			if job.CanceledAt.Valid {
+				status = BuildStatusCanceled
+			}
+			s.metrics.RecordWorkspaceBuildCompleted(workspace.OrganizationName, workspace.TemplateName,
+				string(build.Transition), buildReasonLabel(job.InitiatorID, build.Reason), status)
+		}
+
+		s.notifyWorkspaceBuildFailed(ctx, workspace, build, job)
+		s.BuildTraces.ExportAsync(build.ID)
 
 		// Wake the orchestrator before the workspace event publish
 		// below, which returns on error, so a failed UI event cannot
@@ -1328,6 +1477,11 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 		if err != nil {
 			s.Logger.Error(ctx, "audit log - get build", slog.Error(err))
 		} else {
+			s.Logger.Debug(ctx, "marked workspace build job as failed",
+				slog.F("job_id", job.ID),
+				slog.F("workspace_build_id", build.ID),
+				slog.F("correlation_id", build.ID),
+			)
 			auditAction := auditActionFromTransition(build.Transition)
 			workspace, err := s.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
 			if err != nil {
@@ -1371,6 +1525,9 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 					New:              build,
 					Status:           http.StatusInternalServerError,
 					AdditionalFields: wriBytes,
+					CorrelationID:    build.ID,
+					ImpersonationID:  bag.ImpersonationID,
+					ImpersonatorID:   bag.ImpersonatorID,
 				})
 			}
 		}
@@ -1388,19 +1545,31 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 	return &proto.Empty{}, nil
 }
 
-func (s *server) notifyWorkspaceBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild) {
+func (s *server) notifyWorkspaceBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild, job database.ProvisionerJob) {
+	failureSummary := s.buildFailureSummary(ctx, job)
+	notifiedOwner := s.notifyWorkspaceBuildFailedWithLogs(ctx, workspace, build, job, failureSummary)
+
 	var reason string
 	if build.Reason.Valid() && build.Reason == database.BuildReasonInitiator {
-		s.notifyWorkspaceManualBuildFailed(ctx, workspace, build)
+		s.notifyWorkspaceManualBuildFailed(ctx, workspace, build, failureSummary)
+		return
+	}
+	if notifiedOwner {
+		// The owner was already notified with the logs of the build.
 		return
 	}
 	reason = string(build.Reason)
 
+	labels := map[string]string{
+		"name":           workspace.Name,
+		"reason":         reason,
+		"correlation_id": build.ID.String(),
+	}
+	if failureSummary != "" {
+		labels["failure_summary"] = failureSummary
+	}
 	if _, err := s.NotificationsEnqueuer.Enqueue(ctx, workspace.OwnerID, notifications.TemplateWorkspaceAutobuildFailed,
-		map[string]string{
-			"name":   workspace.Name,
-			"reason": reason,
-		}, "provisionerdserver",
+		labels, "provisionerdserver",
 		// Associate this notification with all the related entities.
 		workspace.ID, workspace.OwnerID, workspace.TemplateID, workspace.OrganizationID,
 	); err != nil {
@@ -1408,7 +1577,70 @@ func (s *server) notifyWorkspaceBuildFailed(ctx context.Context, workspace datab
 	}
 }
 
-func (s *server) notifyWorkspaceManualBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild) {
+// notifyWorkspaceBuildFailedWithLogs notifies the owner of the workspace, and
+// the initiator of the build if it is someone else, that the build failed
+// along with an excerpt of its logs, if the template of the workspace has
+// notify_build_failures set. It reports whether the owner was notified.
+func (s *server) notifyWorkspaceBuildFailedWithLogs(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild, job database.ProvisionerJob, failureSummary string) bool {
+	// Canceled builds did not fail, and nobody reads the notifications of
+	// prebuilt workspaces.
+	if job.CanceledAt.Valid || workspace.IsPrebuild() {
+		return false
+	}
+	template, err := s.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to get template of failed build", slog.F("template_id", workspace.TemplateID), slog.Error(err))
+		return false
+	}
+	if !template.NotifyBuildFailures {
+		return false
+	}
+
+	logs, err := s.Database.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+		JobID: job.ID,
+	})
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to get logs of failed build", slog.F("job_id", job.ID), slog.Error(err))
+	}
+
+	templateNameLabel := template.DisplayName
+	if templateNameLabel == "" {
+		templateNameLabel = template.Name
+	}
+	labels := map[string]string{
+		"name":                     workspace.Name,
+		"template_name":            templateNameLabel,
+		"transition":               string(build.Transition),
+		"reason":                   string(build.Reason),
+		"initiator":                build.InitiatorByUsername,
+		"workspace_owner_username": workspace.OwnerUsername,
+		"workspace_build_number":   strconv.Itoa(int(build.BuildNumber)),
+		"correlation_id":           build.ID.String(),
+	}
+	if failureSummary != "" {
+		labels["failure_summary"] = failureSummary
+	}
+	data := map[string]any{
+		"log_lines": buildfailure.Excerpt(logs, buildfailure.ExcerptLines),
+	}
+
+	recipients := []uuid.UUID{workspace.OwnerID}
+	if build.InitiatorID != workspace.OwnerID {
+		recipients = append(recipients, build.InitiatorID)
+	}
+	for _, userID := range recipients {
+		if _, err := s.NotificationsEnqueuer.EnqueueWithData(ctx, userID, notifications.TemplateWorkspaceBuildFailed,
+			labels, data, "provisionerdserver",
+			// Associate this notification with all the related entities.
+			workspace.ID, workspace.OwnerID, workspace.TemplateID, workspace.OrganizationID,
+		); err != nil {
+			s.Logger.Warn(ctx, "failed to notify of failed workspace build", slog.F("user_id", userID), slog.Error(err))
+		}
+	}
+	return true
+}
+
+func (s *server) notifyWorkspaceManualBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild, failureSummary string) {
 	templateAdmins, template, templateVersion, workspaceOwner, err := s.prepareForNotifyWorkspaceManualBuildFailed(ctx, workspace, build)
 	if err != nil {
 		s.Logger.Error(ctx, "unable to collect data for manual build failed notification", slog.Error(err))
@@ -1427,6 +1659,10 @@ func (s *server) notifyWorkspaceManualBuildFailed(ctx context.Context, workspace
 			"initiator":                build.InitiatorByUsername,
 			"workspace_owner_username": workspaceOwner.Username,
 			"workspace_build_number":   strconv.Itoa(int(build.BuildNumber)),
+			"correlation_id":           build.ID.String(),
+		}
+		if failureSummary != "" {
+			labels["failure_summary"] = failureSummary
 		}
 		if _, err := s.NotificationsEnqueuer.Enqueue(ctx, templateAdmin.ID, notifications.TemplateWorkspaceManualBuildFailed,
 			labels, "provisionerdserver",
@@ -1438,6 +1674,21 @@ func (s *server) notifyWorkspaceManualBuildFailed(ctx context.Context, workspace
 	}
 }
 
+// buildFailureSummary summarizes the root cause of a failed build job from its
+// logs, so notifications show an actionable error. An empty string is returned
+// if there is nothing to summarize.
+func (s *server) buildFailureSummary(ctx context.Context, job database.ProvisionerJob) string {
+	logs, err := s.Database.GetProvisionerJobErrorLogsByJobIDs(ctx, []uuid.UUID{job.ID})
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to get error logs of failed build", slog.F("job_id", job.ID), slog.Error(err))
+	}
+	summary := buildfailure.Summarize(job.Error.String, logs)
+	if summary == nil {
+		return ""
+	}
+	return summary.String()
+}
+
 // prepareForNotifyWorkspaceManualBuildFailed collects data required to build notifications for template admins.
 // The template `notifications.TemplateWorkspaceManualBuildFailed` is quite detailed as it requires information about the template,
 // template version, workspace, workspace build, etc.
@@ -1756,6 +2007,9 @@ func (s *server) completeTemplateImportJob(ctx context.Context, job database.Pro
 		}
 
 		// Process rich parameters
+		if err := validateRichParameterGroups(jobType.TemplateImport.RichParameters); err != nil {
+			return err
+		}
 		for _, richParameter := range jobType.TemplateImport.RichParameters {
 			s.Logger.Info(ctx, "inserting template import job parameter",
 				slog.F("job_id", job.ID.String()),
@@ -1812,6 +2066,10 @@ func (s *server) completeTemplateImportJob(ctx context.Context, job database.Pro
 				Required:            richParameter.Required,
 				DisplayOrder:        richParameter.Order,
 				Ephemeral:           richParameter.Ephemeral,
+				Sensitive:           richParameter.Sensitive,
+				GroupName:           richParameter.Group,
+				GroupOrder:          richParameter.GroupOrder,
+				Page:                richParameter.Page,
 			})
 			if err != nil {
 				return xerrors.Errorf("insert parameter: %w", err)
@@ -2003,6 +2261,10 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 		return xerrors.Errorf("get workspace build: %w", err)
 	}
 
+	// Run the post-build hook before the job is completed, so the steps it
+	// reports are part of the build logs followers receive.
+	s.runPostBuildHook(ctx, job, workspaceBuild)
+
 	var workspace database.Workspace
 	var getWorkspaceError error
 
@@ -2153,6 +2415,22 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 				s.warnWorkspaceAppRebindRejected(ctx, jobID, err)
 				return xerrors.Errorf("insert provisioner job: %w", err)
 			}
+
+			// Record the cloud identifier of the resource, so that it can be
+			// verified to be gone once the workspace is deleted.
+			if cloudID := protoResource.GetCloudId(); cloudID != "" {
+				err = db.UpsertWorkspaceCloudResource(ctx, database.UpsertWorkspaceCloudResourceParams{
+					WorkspaceID:      workspaceBuild.WorkspaceID,
+					ResourceType:     protoResource.GetType(),
+					CloudID:          cloudID,
+					ResourceName:     protoResource.GetName(),
+					WorkspaceBuildID: workspaceBuild.ID,
+					Now:              now,
+				})
+				if err != nil {
+					return xerrors.Errorf("upsert workspace cloud resource: %w", err)
+				}
+			}
 		}
 
 		// Soft-delete agents from prior builds now that this build's
@@ -2168,6 +2446,11 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 			return xerrors.Errorf("soft delete prior workspace agents: %w", err)
 		}
 
+		err = attachSharedVolumes(ctx, db, workspace.WorkspaceTable(), workspaceBuild.ID, jobType.WorkspaceBuild.Resources, now)
+		if err != nil {
+			return xerrors.Errorf("attach shared volumes: %w", err)
+		}
+
 		for _, module := range jobType.WorkspaceBuild.Modules {
 			if err := InsertWorkspaceModule(ctx, db, job.ID, workspaceBuild.Transition, module, telemetrySnapshot); err != nil {
 				return xerrors.Errorf("insert provisioner job module: %w", err)
@@ -2300,49 +2583,14 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 		}
 
 		// Insert timings inside the transaction now
-		// nolint:exhaustruct // The other fields are set further down.
-		params := database.InsertProvisionerJobTimingsParams{
-			JobID: jobID,
-		}
-		for _, t := range jobType.WorkspaceBuild.Timings {
-			start := t.GetStart()
-			if !start.IsValid() || start.AsTime().IsZero() {
-				s.Logger.Warn(ctx, "timings entry has nil or zero start time", slog.F("job_id", job.ID.String()), slog.F("workspace_id", workspace.ID), slog.F("workspace_build_id", workspaceBuild.ID), slog.F("user_id", workspace.OwnerID))
-				continue
-			}
-
-			end := t.GetEnd()
-			if !end.IsValid() || end.AsTime().IsZero() {
-				s.Logger.Warn(ctx, "timings entry has nil or zero end time, skipping", slog.F("job_id", job.ID.String()), slog.F("workspace_id", workspace.ID), slog.F("workspace_build_id", workspaceBuild.ID), slog.F("user_id", workspace.OwnerID))
-				continue
-			}
-
-			var stg database.ProvisionerJobTimingStage
-			if err := stg.Scan(t.Stage); err != nil {
-				s.Logger.Warn(ctx, "failed to parse timings stage, skipping", slog.F("value", t.Stage))
-				continue
-			}
-
-			// Scan does not guarantee validity
-			if !stg.Valid() {
-				s.Logger.Warn(ctx, "invalid stage, will fail insert based one enum", slog.F("value", t.Stage))
-				continue
-			}
-
-			params.Stage = append(params.Stage, stg)
-			params.Source = append(params.Source, t.Source)
-			params.Resource = append(params.Resource, t.Resource)
-			params.Action = append(params.Action, t.Action)
-			params.StartedAt = append(params.StartedAt, t.Start.AsTime())
-			params.EndedAt = append(params.EndedAt, t.End.AsTime())
-		}
-		_, err = db.InsertProvisionerJobTimings(ctx, params)
+		err = insertProvisionerJobTimings(ctx, db, s.Logger.With(
+			slog.F("job_id", job.ID.String()),
+			slog.F("workspace_id", workspace.ID),
+			slog.F("workspace_build_id", workspaceBuild.ID),
+			slog.F("user_id", workspace.OwnerID),
+		), jobID, jobType.WorkspaceBuild.Timings)
 		if err != nil {
-			// A database error here will "fail" this transaction. Making this error fatal.
-			// If this error is seen, add checks above to validate the insert parameters. In
-			// production, timings should not be a fatal error.
-			s.Logger.Warn(ctx, "failed to update provisioner job timings", slog.F("job_id", jobID), slog.Error(err))
-			return xerrors.Errorf("update provisioner job timings: %w", err)
+			return err
 		}
 
 		// On start, we want to ensure that workspace agents timeout statuses
@@ -2408,6 +2656,19 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 			return nil
 		}
 
+		// Archiving destroys the resources of the workspace, but keeps the
+		// workspace itself so it can be unarchived later.
+		if workspaceBuild.Reason == database.BuildReasonArchive {
+			err = db.UpdateWorkspaceArchivedAt(ctx, database.UpdateWorkspaceArchivedAtParams{
+				ID:         workspaceBuild.WorkspaceID,
+				ArchivedAt: sql.NullTime{Time: now, Valid: true},
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace archived at: %w", err)
+			}
+			return nil
+		}
+
 		err = db.UpdateWorkspaceDeletedByID(ctx, database.UpdateWorkspaceDeletedByIDParams{
 			ID:      workspaceBuild.WorkspaceID,
 			Deleted: true,
@@ -2445,13 +2706,19 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 		return xerrors.Errorf("complete job: %w", err)
 	}
 
+	s.Logger.Debug(ctx, "marked workspace build job as completed",
+		slog.F("job_id", jobID),
+		slog.F("workspace_build_id", workspaceBuild.ID),
+		slog.F("correlation_id", workspaceBuild.ID),
+	)
+
 	// Post-transaction operations (operations that do not require transactions or
 	// are external to the database, like audit logging, notifications, etc.)
 
 	// audit the outcome of the workspace build
 	if getWorkspaceError == nil {
 		// If the workspace has been deleted, notify the owner about it.
-		if workspaceBuild.Transition == database.WorkspaceTransitionDelete {
+		if workspaceBuild.Transition == database.WorkspaceTransitionDelete && workspaceBuild.Reason != database.BuildReasonArchive {
 			s.notifyWorkspaceDeleted(ctx, workspace, workspaceBuild)
 		}
 
@@ -2495,6 +2762,8 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 			New:              workspaceBuild,
 			Status:           http.StatusOK,
 			AdditionalFields: wriBytes,
+			ImpersonationID:  bag.ImpersonationID,
+			ImpersonatorID:   bag.ImpersonatorID,
 		})
 	}
 
@@ -2513,6 +2782,11 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 		}
 	}
 
+	if s.metrics != nil {
+		s.metrics.RecordWorkspaceBuildCompleted(workspace.OrganizationName, workspace.TemplateName,
+			string(workspaceBuild.Transition), buildReasonLabel(job.InitiatorID, workspaceBuild.Reason), BuildStatusSucceeded)
+	}
+
 	// Update workspace (regular and prebuild) timing metrics
 	// Only consider 'start' workspace builds
 	if s.metrics != nil && workspaceBuild.Transition == database.WorkspaceTransitionStart {
@@ -2559,6 +2833,11 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 		}
 	}
 
+	// Builds that start agents are exported once the agents are ready.
+	if workspaceBuild.Transition != database.WorkspaceTransitionStart || !hasAgents(jobType.WorkspaceBuild.Resources) {
+		s.BuildTraces.ExportAsync(workspaceBuild.ID)
+	}
+
 	// Wake the orchestrator before the workspace event publish below,
 	// which returns on error, so a failed UI event cannot skip the
 	// wake.
@@ -2818,6 +3097,127 @@ func InsertWorkspacePresetAndParameters(ctx context.Context, db database.Store,
 	return nil
 }
 
+// insertProvisionerJobTimings records the timings reported by the
+// provisioner for a job. Invalid entries are skipped.
+func insertProvisionerJobTimings(ctx context.Context, db database.Store, logger slog.Logger, jobID uuid.UUID, timings []*sdkproto.Timing) error {
+	// nolint:exhaustruct // The other fields are set further down.
+	params := database.InsertProvisionerJobTimingsParams{
+		JobID: jobID,
+	}
+	for _, t := range timings {
+		start := t.GetStart()
+		if !start.IsValid() || start.AsTime().IsZero() {
+			logger.Warn(ctx, "timings entry has nil or zero start time")
+			continue
+		}
+
+		end := t.GetEnd()
+		if !end.IsValid() || end.AsTime().IsZero() {
+			logger.Warn(ctx, "timings entry has nil or zero end time, skipping")
+			continue
+		}
+
+		var stg database.ProvisionerJobTimingStage
+		if err := stg.Scan(t.Stage); err != nil {
+			logger.Warn(ctx, "failed to parse timings stage, skipping", slog.F("value", t.Stage))
+			continue
+		}
+
+		// Scan does not guarantee validity
+		if !stg.Valid() {
+			logger.Warn(ctx, "invalid stage, will fail insert based one enum", slog.F("value", t.Stage))
+			continue
+		}
+
+		params.Stage = append(params.Stage, stg)
+		params.Source = append(params.Source, t.Source)
+		params.Resource = append(params.Resource, t.Resource)
+		params.Action = append(params.Action, t.Action)
+		params.StartedAt = append(params.StartedAt, t.Start.AsTime())
+		params.EndedAt = append(params.EndedAt, t.End.AsTime())
+	}
+	_, err := db.InsertProvisionerJobTimings(ctx, params)
+	if err != nil {
+		// A database error here will "fail" this transaction. Making this error fatal.
+		// If this error is seen, add checks above to validate the insert parameters. In
+		// production, timings should not be a fatal error.
+		logger.Warn(ctx, "failed to update provisioner job timings", slog.Error(err))
+		return xerrors.Errorf("update provisioner job timings: %w", err)
+	}
+	return nil
+}
+
+// attachSharedVolumes replaces the shared volume attachments of a workspace
+// with the ones declared by the agents of its latest build. Volumes that do
+// not exist yet are created in the organization of the workspace. Builds
+// without agents, such as stop and delete builds, release every attachment.
+func attachSharedVolumes(ctx context.Context, db database.Store, workspace database.WorkspaceTable, buildID uuid.UUID, resources []*sdkproto.Resource, now time.Time) error {
+	err := db.DetachSharedVolumeAttachmentsByWorkspaceID(ctx, database.DetachSharedVolumeAttachmentsByWorkspaceIDParams{
+		DetachedAt:  now,
+		WorkspaceID: workspace.ID,
+	})
+	if err != nil {
+		return xerrors.Errorf("detach prior attachments: %w", err)
+	}
+
+	for _, protoResource := range resources {
+		for _, protoAgent := range protoResource.GetAgents() {
+			if protoAgent == nil {
+				continue
+			}
+			for _, protoVolume := range protoAgent.GetSharedVolumes() {
+				if err := codersdk.NameValid(protoVolume.GetName()); err != nil {
+					return xerrors.Errorf("shared volume %q has an invalid name: %w", protoVolume.GetName(), err)
+				}
+				accessMode := database.SharedVolumeAccessMode(protoVolume.GetAccessMode())
+				if !accessMode.Valid() {
+					return xerrors.Errorf("shared volume %q has an invalid access mode %q", protoVolume.GetName(), protoVolume.GetAccessMode())
+				}
+				agentID, err := uuid.Parse(protoAgent.GetId())
+				if err != nil {
+					return xerrors.Errorf("parse agent id: %w", err)
+				}
+
+				volume, err := db.GetSharedVolumeByOrganizationIDAndName(ctx, database.GetSharedVolumeByOrganizationIDAndNameParams{
+					OrganizationID: workspace.OrganizationID,
+					Name:           protoVolume.GetName(),
+				})
+				if errors.Is(err, sql.ErrNoRows) {
+					volume, err = db.InsertSharedVolume(ctx, database.InsertSharedVolumeParams{
+						ID:             uuid.New(),
+						OrganizationID: workspace.OrganizationID,
+						Name:           protoVolume.GetName(),
+						TemplateID:     uuid.NullUUID{UUID: workspace.TemplateID, Valid: true},
+						CreatedAt:      now,
+						UpdatedAt:      now,
+					})
+				}
+				if err != nil {
+					return xerrors.Errorf("get shared volume %q: %w", protoVolume.GetName(), err)
+				}
+
+				_, err = db.InsertSharedVolumeAttachment(ctx, database.InsertSharedVolumeAttachmentParams{
+					ID:               uuid.New(),
+					SharedVolumeID:   volume.ID,
+					WorkspaceID:      workspace.ID,
+					WorkspaceBuildID: buildID,
+					WorkspaceAgentID: agentID,
+					MountPath:        protoVolume.GetMountPath(),
+					AccessMode:       accessMode,
+					AttachedAt:       now,
+				})
+				if database.IsUniqueViolation(err, database.UniqueSharedVolumeAttachmentsReadWriteLockIndex) {
+					return xerrors.Errorf("shared volume %q is attached read-write by another workspace", protoVolume.GetName())
+				}
+				if err != nil {
+					return xerrors.Errorf("insert shared volume attachment: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 type insertWorkspaceResourceOptions struct {
 	useAgentIDsFromProto bool
 }
@@ -3085,6 +3485,39 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			return xerrors.Errorf("insert agent scripts and log sources: %w", err)
 		}
 
+		if services := prAgent.GetServices(); len(services) > 0 {
+			var (
+				serviceNames           = make([]string, 0, len(services))
+				serviceDisplayNames    = make([]string, 0, len(services))
+				serviceCommands        = make([]string, 0, len(services))
+				serviceRestartPolicies = make([]database.WorkspaceAgentServiceRestartPolicy, 0, len(services))
+			)
+			for _, svc := range services {
+				if slices.Contains(serviceNames, svc.GetName()) {
+					return xerrors.Errorf("duplicate service name %q on agent %q", svc.GetName(), prAgent.Name)
+				}
+				restartPolicy := database.WorkspaceAgentServiceRestartPolicy(svc.GetRestartPolicy())
+				if !restartPolicy.Valid() {
+					return xerrors.Errorf("service %q has invalid restart policy %q", svc.GetName(), svc.GetRestartPolicy())
+				}
+				serviceNames = append(serviceNames, svc.GetName())
+				serviceDisplayNames = append(serviceDisplayNames, svc.GetDisplayName())
+				serviceCommands = append(serviceCommands, svc.GetCommand())
+				serviceRestartPolicies = append(serviceRestartPolicies, restartPolicy)
+			}
+			_, err = db.InsertWorkspaceAgentServices(ctx, database.InsertWorkspaceAgentServicesParams{
+				WorkspaceAgentID: agentID,
+				Name:             serviceNames,
+				DisplayName:      serviceDisplayNames,
+				Command:          serviceCommands,
+				RestartPolicy:    serviceRestartPolicies,
+				CreatedAt:        dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert agent services: %w", err)
+			}
+		}
+
 		for _, app := range prAgent.Apps {
 			if err := insertAgentApp(ctx, db, dbAgent.ID, app, appSlugs, snapshot); err != nil {
 				return xerrors.Errorf("insert agent app: %w", err)
@@ -3355,6 +3788,8 @@ type WorkspaceProvisionJob struct {
 	DryRun                      bool                                 `json:"dry_run"`
 	LogLevel                    string                               `json:"log_level,omitempty"`
 	PrebuiltWorkspaceBuildStage sdkproto.PrebuiltWorkspaceBuildStage `json:"prebuilt_workspace_stage,omitempty"`
+	// TargetResources limits the build to these Terraform resource addresses.
+	TargetResources []string `json:"target_resources,omitempty"`
 }
 
 // TemplateVersionDryRunJob is the payload for the "template_version_dry_run" job type.
@@ -3725,6 +4160,15 @@ func insertAgentApp(ctx context.Context, db database.Store, agentID uuid.UUID, a
 		openIn = database.WorkspaceAppOpenInSlimWindow
 	}
 
+	for name, seconds := range map[string]int64{
+		"max_session_duration_seconds": app.MaxSessionDurationSeconds,
+		"session_idle_timeout_seconds": app.SessionIdleTimeoutSeconds,
+	} {
+		if seconds < 0 || seconds > math.MaxInt32 {
+			return xerrors.Errorf("app %q has invalid %s %d", slug, name, seconds)
+		}
+	}
+
 	var appID string
 	if app.Id == "" || app.Id == uuid.Nil.String() {
 		appID = uuid.NewString()
@@ -3765,6 +4209,10 @@ func insertAgentApp(ctx context.Context, db database.Store, agentID uuid.UUID, a
 		Hidden:       app.Hidden,
 		OpenIn:       openIn,
 		Tooltip:      app.Tooltip,
+		// #nosec G115 - Session limits are validated to fit in int32 above.
+		MaxSessionDurationSeconds: int32(app.MaxSessionDurationSeconds),
+		// #nosec G115 - Session limits are validated to fit in int32 above.
+		SessionIdleTimeoutSeconds: int32(app.SessionIdleTimeoutSeconds),
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -3785,3 +4233,49 @@ func insertAgentApp(ctx context.Context, db database.Store, agentID uuid.UUID, a
 
 	return nil
 }
+
+// hasAgents returns whether any of the resources has an agent.
+func hasAgents(resources []*sdkproto.Resource) bool {
+	for _, resource := range resources {
+		if len(resource.GetAgents()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRichParameterGroups checks that the parameters of a group are
+// presented together: they must share the order and page of the group.
+// Ungrouped parameters may only set a page.
+func validateRichParameterGroups(params []*sdkproto.RichParameter) error {
+	groups := make(map[string]*sdkproto.RichParameter)
+	for _, param := range params {
+		if param.Page < 0 {
+			return xerrors.Errorf("parameter %q field 'page' must not be negative", param.Name)
+		}
+		if param.GroupOrder < 0 {
+			return xerrors.Errorf("parameter %q field 'group_order' must not be negative", param.Name)
+		}
+		if param.Group == "" {
+			if param.GroupOrder != 0 {
+				return xerrors.Errorf("parameter %q sets field 'group_order' without a group", param.Name)
+			}
+			continue
+		}
+
+		first, ok := groups[param.Group]
+		if !ok {
+			groups[param.Group] = param
+			continue
+		}
+		if param.GroupOrder != first.GroupOrder {
+			return xerrors.Errorf("parameters %q and %q of group %q have different group orders %d and %d",
+				first.Name, param.Name, param.Group, first.GroupOrder, param.GroupOrder)
+		}
+		if param.Page != first.Page {
+			return xerrors.Errorf("parameters %q and %q of group %q are on different pages %d and %d",
+				first.Name, param.Name, param.Group, first.Page, param.Page)
+		}
+	}
+	return nil
+}
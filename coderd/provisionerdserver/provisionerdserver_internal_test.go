@@ -13,6 +13,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -167,3 +168,65 @@ func TestObtainOIDCAccessToken(t *testing.T) {
 		require.Equal(t, "token", link.OAuthAccessToken)
 	})
 }
+
+func TestValidateRichParameterGroups(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		params []*sdkproto.RichParameter
+		err    string
+	}{
+		{
+			name: "Consistent",
+			params: []*sdkproto.RichParameter{
+				{Name: "region", Group: "Infrastructure", GroupOrder: 1, Page: 1},
+				{Name: "instance_type", Group: "Infrastructure", GroupOrder: 1, Page: 1},
+				{Name: "dotfiles", Group: "Personalization", GroupOrder: 2, Page: 2},
+				{Name: "ide", Page: 2},
+				{Name: "image"},
+			},
+		},
+		{
+			name: "DifferentGroupOrders",
+			params: []*sdkproto.RichParameter{
+				{Name: "region", Group: "Infrastructure", GroupOrder: 1},
+				{Name: "instance_type", Group: "Infrastructure", GroupOrder: 2},
+			},
+			err: "different group orders",
+		},
+		{
+			name: "DifferentPages",
+			params: []*sdkproto.RichParameter{
+				{Name: "region", Group: "Infrastructure", Page: 1},
+				{Name: "instance_type", Group: "Infrastructure", Page: 2},
+			},
+			err: "different pages",
+		},
+		{
+			name: "GroupOrderWithoutGroup",
+			params: []*sdkproto.RichParameter{
+				{Name: "region", GroupOrder: 1},
+			},
+			err: "without a group",
+		},
+		{
+			name: "NegativePage",
+			params: []*sdkproto.RichParameter{
+				{Name: "region", Group: "Infrastructure", Page: -1},
+			},
+			err: "must not be negative",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateRichParameterGroups(tc.params)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -676,6 +676,23 @@ func TestAcquireJob(t *testing.T) {
 
 				require.JSONEq(t, string(want), string(got))
 
+				// The inputs of the build are recorded, without secrets.
+				inputsRow, err := db.GetWorkspaceBuildInputsByBuildID(ctx, build.ID)
+				require.NoError(t, err)
+				var inputs codersdk.WorkspaceBuildInputs
+				require.NoError(t, json.Unmarshal(inputsRow.Inputs, &inputs))
+				require.Equal(t, file.Hash, inputs.TemplateVersionHash)
+				require.Equal(t, version.ID, inputs.TemplateVersionID)
+				require.Equal(t, workspace.ID, inputs.Metadata.WorkspaceID)
+				require.Equal(t, pd.ID, inputs.Deployment.ProvisionerDaemonID)
+				require.Equal(t, []codersdk.WorkspaceBuildInputVariable{
+					{Name: "first", Sensitive: true},
+					{Name: "second", Value: "second_value"},
+				}, inputs.Variables)
+				require.Equal(t, []string{gitAuthProvider.Id}, inputs.Metadata.ExternalAuthProviders)
+				require.NotContains(t, string(inputsRow.Inputs), "access_token")
+				require.NotContains(t, string(inputsRow.Inputs), "first_value")
+
 				stopbuildID := uuid.New()
 				stopJob := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
 					ID:            stopbuildID,
@@ -814,6 +831,45 @@ func TestAcquireJob(t *testing.T) {
 			require.NoError(t, err)
 			require.JSONEq(t, string(want), string(got))
 		})
+		t.Run(tc.name+"_TemplateTimeout", func(t *testing.T) {
+			t.Parallel()
+			srv, db, ps, pd := setup(t, false, nil)
+			ctx := context.Background()
+
+			user := dbgen.User(t, db, database.User{})
+			template := dbgen.Template(t, db, database.Template{
+				OrganizationID:         pd.OrganizationID,
+				CreatedBy:              user.ID,
+				ProvisionerPlanTimeout: int64(provisionerdserver.MinJobTimeout),
+			})
+			version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+				TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+				CreatedBy:      user.ID,
+				OrganizationID: pd.OrganizationID,
+			})
+			file := dbgen.File(t, db, database.File{CreatedBy: user.ID})
+			created := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+				FileID:        file.ID,
+				InitiatorID:   user.ID,
+				Provisioner:   database.ProvisionerTypeEcho,
+				StorageMethod: database.ProvisionerStorageMethodFile,
+				Type:          database.ProvisionerJobTypeTemplateVersionImport,
+				Input: must(json.Marshal(provisionerdserver.TemplateVersionImportJob{
+					TemplateID:        uuid.NullUUID{UUID: template.ID, Valid: true},
+					TemplateVersionID: version.ID,
+				})),
+				Tags: pd.Tags,
+			})
+
+			_, err := tc.acquire(ctx, srv)
+			require.NoError(t, err)
+
+			// The timeout is recorded on the job, so that updates don't
+			// have to look up the template again.
+			job, err := db.GetProvisionerJobByID(ctx, created.ID)
+			require.NoError(t, err)
+			require.Equal(t, int64(provisionerdserver.MinJobTimeout), job.Timeout)
+		})
 		t.Run(tc.name+"_TemplateVersionImportWithUserVariable", func(t *testing.T) {
 			t.Parallel()
 			srv, db, ps, pd := setup(t, false, nil)
@@ -1007,6 +1063,82 @@ func TestUpdateJob(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("TemplateTimeout", func(t *testing.T) {
+		t.Parallel()
+		srv, db, _, pd := setup(t, false, &overrides{})
+		user := dbgen.User(t, db, database.User{})
+		template := dbgen.Template(t, db, database.Template{
+			OrganizationID:         pd.OrganizationID,
+			CreatedBy:              user.ID,
+			ProvisionerPlanTimeout: int64(provisionerdserver.MinJobTimeout),
+		})
+		version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+			CreatedBy:      user.ID,
+			OrganizationID: pd.OrganizationID,
+		})
+		job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			ID:             version.JobID,
+			OrganizationID: pd.OrganizationID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			Input: must(json.Marshal(provisionerdserver.TemplateVersionImportJob{
+				TemplateID:        uuid.NullUUID{UUID: template.ID, Valid: true},
+				TemplateVersionID: version.ID,
+			})),
+			Tags: pd.Tags,
+		})
+		require.NoError(t, err)
+		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  pd.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+			// Started long enough ago to have exceeded the plan timeout.
+			StartedAt: sql.NullTime{
+				Time:  dbtime.Now().Add(-2 * provisionerdserver.MinJobTimeout),
+				Valid: true,
+			},
+			OrganizationID:  pd.OrganizationID,
+			ProvisionerTags: must(json.Marshal(job.Tags)),
+		})
+		require.NoError(t, err)
+		// AcquireJob records the template's timeout on the job.
+		err = db.UpdateProvisionerJobTimeoutByID(ctx, database.UpdateProvisionerJobTimeoutByIDParams{
+			ID:      job.ID,
+			Timeout: template.ProvisionerPlanTimeout,
+		})
+		require.NoError(t, err)
+
+		resp, err := srv.UpdateJob(ctx, &proto.UpdateJobRequest{
+			JobId: job.ID.String(),
+		})
+		require.NoError(t, err)
+		require.True(t, resp.Canceled)
+
+		logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+			JobID: job.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, database.LogLevelError, logs[0].Level)
+		require.Contains(t, logs[0].Output, "plan timeout")
+
+		// The timeout is only logged once.
+		resp, err = srv.UpdateJob(ctx, &proto.UpdateJobRequest{
+			JobId: job.ID.String(),
+		})
+		require.NoError(t, err)
+		require.True(t, resp.Canceled)
+		logs, err = db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+			JobID: job.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+	})
+
 	t.Run("Logs", func(t *testing.T) {
 		t.Parallel()
 		srv, db, ps, pd := setup(t, false, &overrides{})
@@ -1490,6 +1622,14 @@ func TestFailJob(t *testing.T) {
 			Type: &proto.FailedJob_WorkspaceBuild_{
 				WorkspaceBuild: &proto.FailedJob_WorkspaceBuild{
 					State: []byte("some state"),
+					Timings: []*sdkproto.Timing{{
+						Stage:    "apply",
+						Source:   "test-source",
+						Resource: "test-resource",
+						Action:   "test-action",
+						Start:    timestamppb.Now(),
+						End:      timestamppb.Now(),
+					}},
 				},
 			},
 		})
@@ -1503,6 +1643,12 @@ func TestFailJob(t *testing.T) {
 		require.Equal(t, "some state", string(provisionerStateRow.ProvisionerState))
 		require.Len(t, auditor.AuditLogs(), 1)
 
+		// The timings of a failed build are recorded as well.
+		timings, err := db.GetProvisionerJobTimingsByJobID(ctx, job.ID)
+		require.NoError(t, err)
+		require.Len(t, timings, 1)
+		require.Equal(t, database.ProvisionerJobTimingStageApply, timings[0].Stage)
+
 		// Assert that the workspace_id field get populated
 		var additionalFields audit.AdditionalFields
 		err = json.Unmarshal(auditor.AuditLogs()[0].AdditionalFields, &additionalFields)
@@ -5009,10 +5155,19 @@ func TestNotifications(t *testing.T) {
 			StartedAt:       sql.NullTime{Time: job.CreatedAt, Valid: true},
 		})
 		require.NoError(t, err)
+		_, err = db.InsertProvisionerJobLogs(ctx, database.InsertProvisionerJobLogsParams{
+			JobID:     job.ID,
+			CreatedAt: []time.Time{dbtime.Now(), dbtime.Now()},
+			Source:    []database.LogSource{database.LogSourceProvisioner, database.LogSourceProvisioner},
+			Level:     []database.LogLevel{database.LogLevelError, database.LogLevelError},
+			Stage:     []string{"Destroying workspace", "Destroying workspace"},
+			Output:    []string{"Error: deleting volume: volume is in use", `on main.tf line 12, in resource "docker_volume" "home":`},
+		})
+		require.NoError(t, err)
 
 		// when
 		_, err = srv.FailJob(ctx, &proto.FailedJob{
-			JobId: job.ID.String(), Type: &proto.FailedJob_WorkspaceBuild_{WorkspaceBuild: &proto.FailedJob_WorkspaceBuild{State: []byte{}}},
+			JobId: job.ID.String(), Error: "exit status 1", Type: &proto.FailedJob_WorkspaceBuild_{WorkspaceBuild: &proto.FailedJob_WorkspaceBuild{State: []byte{}}},
 		})
 		require.NoError(t, err)
 
@@ -5031,6 +5186,128 @@ func TestNotifications(t *testing.T) {
 		assert.Equal(t, user.Username, sent[0].Labels["initiator"])
 		assert.Equal(t, user.Username, sent[0].Labels["workspace_owner_username"])
 		assert.Equal(t, strconv.Itoa(int(build.BuildNumber)), sent[0].Labels["workspace_build_number"])
+		assert.Equal(t, build.ID.String(), sent[0].Labels["correlation_id"])
+		assert.Equal(t, "docker_volume.home: deleting volume: volume is in use", sent[0].Labels["failure_summary"])
+	})
+
+	t.Run("Workspace build failed, template notifies build failures", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name string
+
+			buildReason        database.BuildReason
+			initiatedByAnother bool
+			expectedRecipients int
+		}{
+			{
+				name:               "initiated by autostart",
+				buildReason:        database.BuildReasonAutostart,
+				expectedRecipients: 1,
+			},
+			{
+				name:               "initiated by another user",
+				buildReason:        database.BuildReasonInitiator,
+				initiatedByAnother: true,
+				expectedRecipients: 2,
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				ctx := testutil.Context(t, testutil.WaitShort)
+				notifEnq := &notificationstest.FakeEnqueuer{}
+				srv, db, ps, pd := setup(t, true /* ignoreLogErrors */, &overrides{notificationEnqueuer: notifEnq})
+
+				user := dbgen.User(t, db, database.User{})
+				initiator := user
+				if tc.initiatedByAnother {
+					initiator = dbgen.User(t, db, database.User{})
+				}
+				template := dbgen.Template(t, db, database.Template{
+					CreatedBy:      user.ID,
+					Provisioner:    database.ProvisionerTypeEcho,
+					OrganizationID: pd.OrganizationID,
+				})
+				err := db.UpdateTemplateMetaByID(ctx, database.UpdateTemplateMetaByIDParams{
+					ID:                  template.ID,
+					UpdatedAt:           dbtime.Now(),
+					Name:                template.Name,
+					DisplayName:         template.DisplayName,
+					GroupACL:            template.GroupACL,
+					MaxPortSharingLevel: template.MaxPortSharingLevel,
+					CorsBehavior:        template.CorsBehavior,
+					NotifyBuildFailures: true,
+				})
+				require.NoError(t, err)
+				workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+					TemplateID: template.ID, OwnerID: user.ID, OrganizationID: pd.OrganizationID,
+				})
+				version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+					CreatedBy:      user.ID,
+					OrganizationID: pd.OrganizationID, TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true}, JobID: uuid.New(),
+				})
+				wsBuildID := uuid.New()
+				job := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+					FileID:         dbgen.File(t, db, database.File{CreatedBy: user.ID}).ID,
+					InitiatorID:    initiator.ID,
+					Type:           database.ProvisionerJobTypeWorkspaceBuild,
+					Input:          must(json.Marshal(provisionerdserver.WorkspaceProvisionJob{WorkspaceBuildID: wsBuildID})),
+					OrganizationID: pd.OrganizationID,
+				})
+				build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+					ID:          wsBuildID,
+					JobID:       job.ID,
+					WorkspaceID: workspace.ID, TemplateVersionID: version.ID, InitiatorID: initiator.ID, Transition: database.WorkspaceTransitionStart, Reason: tc.buildReason,
+				})
+				_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+					OrganizationID:  pd.OrganizationID,
+					WorkerID:        uuid.NullUUID{UUID: pd.ID, Valid: true},
+					Types:           []database.ProvisionerType{database.ProvisionerTypeEcho},
+					ProvisionerTags: must(json.Marshal(job.Tags)),
+					StartedAt:       sql.NullTime{Time: job.CreatedAt, Valid: true},
+				})
+				require.NoError(t, err)
+				_, err = db.InsertProvisionerJobLogs(ctx, database.InsertProvisionerJobLogsParams{
+					JobID:     job.ID,
+					CreatedAt: []time.Time{dbtime.Now(), dbtime.Now(), dbtime.Now()},
+					Source:    []database.LogSource{database.LogSourceProvisioner, database.LogSourceProvisioner, database.LogSourceProvisioner},
+					Level:     []database.LogLevel{database.LogLevelInfo, database.LogLevelDebug, database.LogLevelError},
+					Stage:     []string{"Starting workspace", "Starting workspace", "Starting workspace"},
+					Output:    []string{"docker_container.dev: Creating...", "provider: plugin started", "Error: Unable to start container"},
+				})
+				require.NoError(t, err)
+
+				_, err = srv.FailJob(ctx, &proto.FailedJob{
+					JobId: job.ID.String(), Error: "exit status 1", Type: &proto.FailedJob_WorkspaceBuild_{WorkspaceBuild: &proto.FailedJob_WorkspaceBuild{State: []byte{}}},
+				})
+				require.NoError(t, err)
+
+				// The notification supersedes the autobuild failure
+				// notification, and no template admin is in the organization.
+				sent := notifEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceBuildFailed))
+				require.Len(t, sent, tc.expectedRecipients)
+				require.Len(t, notifEnq.Sent(), tc.expectedRecipients)
+				require.Equal(t, user.ID, sent[0].UserID)
+				if tc.initiatedByAnother {
+					require.Equal(t, initiator.ID, sent[1].UserID)
+				}
+				for _, notif := range sent {
+					assert.Contains(t, notif.Targets, workspace.ID)
+					assert.Equal(t, workspace.Name, notif.Labels["name"])
+					assert.Equal(t, template.DisplayName, notif.Labels["template_name"])
+					assert.Equal(t, "start", notif.Labels["transition"])
+					assert.Equal(t, string(tc.buildReason), notif.Labels["reason"])
+					assert.Equal(t, initiator.Username, notif.Labels["initiator"])
+					assert.Equal(t, user.Username, notif.Labels["workspace_owner_username"])
+					assert.Equal(t, strconv.Itoa(int(build.BuildNumber)), notif.Labels["workspace_build_number"])
+					assert.Equal(t, "Unable to start container", notif.Labels["failure_summary"])
+					assert.Equal(t, []string{"docker_container.dev: Creating...", "Error: Unable to start container"}, notif.Data["log_lines"])
+				}
+			})
+		}
 	})
 }
 
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"sort"
@@ -90,6 +91,68 @@ func (api *API) provisionerJobs(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, slice.List(jobs, convertProvisionerJobWithQueuePosition))
 }
 
+// @Summary Get user provisioner jobs
+// @ID get-user-provisioner-jobs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.UserProvisionerJob
+// @Router /api/v2/users/{user}/jobs [get]
+func (api *API) userProvisionerJobs(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	jobs, err := api.Database.GetProvisionerJobsByInitiatorWithQueuePosition(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	res := make([]codersdk.UserProvisionerJob, 0, len(jobs))
+	for _, job := range jobs {
+		sdkJob := convertProvisionerJobWithQueuePosition(database.GetProvisionerJobsByOrganizationAndStatusWithQueuePositionAndProvisionerRow(job))
+		res = append(res, codersdk.UserProvisionerJob{
+			ProvisionerJob: sdkJob,
+			CancelURL:      provisionerJobCancelURL(sdkJob),
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// provisionerJobCancelURL returns the API path that cancels the job, or an
+// empty string if the job can no longer be canceled.
+func provisionerJobCancelURL(job codersdk.ProvisionerJob) string {
+	if job.Status != codersdk.ProvisionerJobPending && job.Status != codersdk.ProvisionerJobRunning {
+		return ""
+	}
+	switch job.Type {
+	case codersdk.ProvisionerJobTypeWorkspaceBuild:
+		if job.Input.WorkspaceBuildID != nil {
+			return fmt.Sprintf("/api/v2/workspacebuilds/%s/cancel", *job.Input.WorkspaceBuildID)
+		}
+	case codersdk.ProvisionerJobTypeTemplateVersionImport:
+		if job.Input.TemplateVersionID != nil {
+			return fmt.Sprintf("/api/v2/templateversions/%s/cancel", *job.Input.TemplateVersionID)
+		}
+	case codersdk.ProvisionerJobTypeTemplateVersionDryRun:
+		if job.Input.TemplateVersionID != nil {
+			return fmt.Sprintf("/api/v2/templateversions/%s/dry-run/%s/cancel", *job.Input.TemplateVersionID, job.ID)
+		}
+	}
+	return ""
+}
+
 // handleAuthAndFetchProvisionerJobs is an internal method shared by
 // provisionerJob and provisionerJobs. If ok is false the caller should
 // return immediately because the response has already been written.
@@ -453,6 +516,12 @@ func fetchAndWriteLogs(ctx context.Context, db database.Store, jobID uuid.UUID,
 		})
 		return
 	}
+	writeProvisionerJobLogs(ctx, rw, logs, format)
+}
+
+// writeProvisionerJobLogs writes logs in the given format, either "json" or
+// "text".
+func writeProvisionerJobLogs(ctx context.Context, rw http.ResponseWriter, logs []database.ProvisionerJobLog, format string) {
 	if logs == nil {
 		logs = []database.ProvisionerJobLog{}
 	}
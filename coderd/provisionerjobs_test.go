@@ -83,6 +83,32 @@ func TestProvisionerJobs(t *testing.T) {
 			})
 		}
 
+		t.Run("User", func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitMedium)
+
+			// Members can list the in-flight jobs they started themselves.
+			jobs, err := memberClient.UserProvisionerJobs(ctx, codersdk.Me)
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			assert.Equal(t, job.ID, jobs[0].ID)
+			assert.Equal(t, codersdk.ProvisionerJobRunning, jobs[0].Status)
+			assert.Equal(t, &w.ID, jobs[0].Metadata.WorkspaceID)
+			assert.Equal(t, "/api/v2/workspacebuilds/"+wbID.String()+"/cancel", jobs[0].CancelURL)
+
+			// Completed jobs are not included.
+			jobs, err = client.UserProvisionerJobs(ctx, codersdk.Me)
+			require.NoError(t, err)
+			for _, j := range jobs {
+				assert.NotEqual(t, workspace.LatestBuild.Job.ID, j.ID)
+				assert.NotEqual(t, version.Job.ID, j.ID)
+			}
+
+			// Members cannot list jobs of other users.
+			_, err = memberClient.UserProvisionerJobs(ctx, owner.UserID.String())
+			require.Error(t, err)
+		})
+
 		t.Run("Single", func(t *testing.T) {
 			t.Parallel()
 			t.Run("Workspace", func(t *testing.T) {
@@ -2,6 +2,9 @@ package provisionerkey
 
 import (
 	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -35,6 +38,25 @@ func New(organizationID uuid.UUID, name string, tags map[string]string) (databas
 	}, secret, nil
 }
 
+// Rotate generates a new secret for the key with the given ID. The current
+// secret of the key stays valid for gracePeriod, so that daemons can be moved
+// to the new secret without downtime.
+func Rotate(id uuid.UUID, gracePeriod time.Duration) (database.RotateProvisionerKeyParams, string, error) {
+	secret, hashed, err := apikey.GenerateSecret(secretLength)
+	if err != nil {
+		return database.RotateProvisionerKeyParams{}, "", xerrors.Errorf("generate secret: %w", err)
+	}
+
+	return database.RotateProvisionerKeyParams{
+		ID:           id,
+		HashedSecret: hashed,
+		PreviousSecretExpiresAt: sql.NullTime{
+			Time:  dbtime.Now().Add(gracePeriod),
+			Valid: true,
+		},
+	}, secret, nil
+}
+
 func Validate(token string) error {
 	if len(token) != secretLength {
 		return xerrors.Errorf("must be %d characters", secretLength)
@@ -50,3 +72,21 @@ func HashSecret(secret string) []byte {
 func Compare(a []byte, b []byte) bool {
 	return subtle.ConstantTimeCompare(a, b) != 1
 }
+
+// ValidSecret returns true if hashedSecret is the secret of the key, or the
+// secret it was rotated away from before the grace period of the rotation
+// ended.
+func ValidSecret(pk database.ProvisionerKey, hashedSecret []byte, now time.Time) bool {
+	if subtle.ConstantTimeCompare(pk.HashedSecret, hashedSecret) == 1 {
+		return true
+	}
+	return pk.PreviousSecretExpiresAt.Valid && now.Before(pk.PreviousSecretExpiresAt.Time) &&
+		subtle.ConstantTimeCompare(pk.PreviousHashedSecret, hashedSecret) == 1
+}
+
+// EventChannel returns the pubsub channel that is published to when the key
+// with the given ID is rotated or deleted, so that daemons connected with a
+// secret that is no longer valid are drained.
+func EventChannel(id uuid.UUID) string {
+	return fmt.Sprintf("provisioner_key:%s", id)
+}
@@ -386,6 +386,7 @@ var (
 	// Valid Actions
 	//  - "ActionCreate" :: create a new user
 	//  - "ActionDelete" :: delete an existing user
+	//  - "ActionImpersonate" :: issue time-boxed tokens to act as the user
 	//  - "ActionRead" :: read user data
 	//  - "ActionReadPersonal" :: read personal user data like user settings and auth links
 	//  - "ActionUpdate" :: update an existing user
@@ -430,6 +431,7 @@ var (
 	//  - "ActionCreateAgent" :: create a new workspace agent
 	//  - "ActionDelete" :: delete workspace
 	//  - "ActionDeleteAgent" :: delete an existing workspace agent
+	//  - "ActionWorkspaceExtend" :: extend the deadline of a running workspace, including through activity
 	//  - "ActionRead" :: read workspace data to view on the UI
 	//  - "ActionShare" :: share a workspace with other users or groups
 	//  - "ActionSSH" :: ssh into a given workspace
@@ -474,6 +476,7 @@ var (
 	//  - "ActionCreateAgent" :: create a new workspace agent
 	//  - "ActionDelete" :: delete workspace
 	//  - "ActionDeleteAgent" :: delete an existing workspace agent
+	//  - "ActionWorkspaceExtend" :: extend the deadline of a running workspace, including through activity
 	//  - "ActionRead" :: read workspace data to view on the UI
 	//  - "ActionShare" :: share a workspace with other users or groups
 	//  - "ActionSSH" :: ssh into a given workspace
@@ -560,6 +563,7 @@ func AllActions() []policy.Action {
 		policy.ActionCreateAgent,
 		policy.ActionDelete,
 		policy.ActionDeleteAgent,
+		policy.ActionImpersonate,
 		policy.ActionRead,
 		policy.ActionReadPersonal,
 		policy.ActionSSH,
@@ -570,6 +574,7 @@ func AllActions() []policy.Action {
 		policy.ActionUpdatePersonal,
 		policy.ActionUse,
 		policy.ActionViewInsights,
+		policy.ActionWorkspaceExtend,
 		policy.ActionWorkspaceStart,
 		policy.ActionWorkspaceStop,
 	}
@@ -16,14 +16,16 @@ const (
 	ActionApplicationConnect Action = "application_connect"
 	ActionViewInsights       Action = "view_insights"
 
-	ActionWorkspaceStart Action = "start"
-	ActionWorkspaceStop  Action = "stop"
+	ActionWorkspaceStart  Action = "start"
+	ActionWorkspaceStop   Action = "stop"
+	ActionWorkspaceExtend Action = "extend"
 
 	ActionAssign   Action = "assign"
 	ActionUnassign Action = "unassign"
 
 	ActionReadPersonal   Action = "read_personal"
 	ActionUpdatePersonal Action = "update_personal"
+	ActionImpersonate    Action = "impersonate"
 
 	ActionCreateAgent Action = "create_agent"
 	ActionDeleteAgent Action = "delete_agent"
@@ -58,6 +60,10 @@ var workspaceActions = map[Action]ActionDefinition{
 	ActionWorkspaceStart: "allows starting a workspace",
 	ActionWorkspaceStop:  "allows stopping a workspace",
 
+	// Extending is separate from update so roles can allow using a workspace
+	// without being able to postpone its autostop.
+	ActionWorkspaceExtend: "extend the deadline of a running workspace, including through activity",
+
 	// Running a workspace
 	ActionSSH:                "ssh into a given workspace",
 	ActionApplicationConnect: "connect to workspace apps via browser",
@@ -103,6 +109,10 @@ var RBACPermissions = map[string]PermissionDefinition{
 
 			ActionReadPersonal:   "read personal user data like user settings and auth links",
 			ActionUpdatePersonal: "update personal data",
+
+			// Impersonating is separate from update so user admins cannot
+			// act as the users they manage.
+			ActionImpersonate: "issue time-boxed tokens to act as the user",
 		},
 	},
 	"workspace": {
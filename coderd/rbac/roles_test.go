@@ -686,6 +686,15 @@ func TestRolePermissions(t *testing.T) {
 				false: {setOtherOrg, setOrgNotMe, templateAdmin},
 			},
 		},
+		{
+			Name:     "UserImpersonate",
+			Actions:  []policy.Action{policy.ActionImpersonate},
+			Resource: rbac.ResourceUserObject(currentUser),
+			AuthorizeMap: map[bool][]hasAuthSubjects{
+				true:  {owner},
+				false: {setOtherOrg, setOrgNotMe, memberMe, agentsAccessUser, orgWorkspaceAccessUser, templateAdmin, userAdmin},
+			},
+		},
 		{
 			Name:     "ManageOrgMember",
 			Actions:  []policy.Action{policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete},
@@ -781,7 +790,7 @@ func TestRolePermissions(t *testing.T) {
 		},
 		{
 			Name:     "WorkspaceDormantUse",
-			Actions:  []policy.Action{policy.ActionWorkspaceStart, policy.ActionApplicationConnect, policy.ActionSSH},
+			Actions:  []policy.Action{policy.ActionWorkspaceStart, policy.ActionWorkspaceExtend, policy.ActionApplicationConnect, policy.ActionSSH},
 			Resource: rbac.ResourceWorkspaceDormant.WithID(uuid.New()).InOrg(orgID).WithOwner(memberMe.Actor.ID),
 			AuthorizeMap: map[bool][]hasAuthSubjects{
 				true:  {},
@@ -790,7 +799,7 @@ func TestRolePermissions(t *testing.T) {
 		},
 		{
 			Name:     "WorkspaceBuild",
-			Actions:  []policy.Action{policy.ActionWorkspaceStart, policy.ActionWorkspaceStop},
+			Actions:  []policy.Action{policy.ActionWorkspaceStart, policy.ActionWorkspaceStop, policy.ActionWorkspaceExtend},
 			Resource: rbac.ResourceWorkspace.WithID(uuid.New()).InOrg(orgID).WithOwner(memberMe.Actor.ID),
 			AuthorizeMap: map[bool][]hasAuthSubjects{
 				true:  {owner, orgAdmin, orgWorkspaceAccessUser},
@@ -17,6 +17,7 @@ var externalLowLevel = map[ScopeName]struct{}{
 	"workspace:ssh":                 {},
 	"workspace:start":               {},
 	"workspace:stop":                {},
+	"workspace:extend":              {},
 	"workspace:application_connect": {},
 	"workspace:*":                   {},
 
@@ -133,6 +133,7 @@ const (
 	ScopeUsageEventUpdate                    ScopeName = "usage_event:update"
 	ScopeUserCreate                          ScopeName = "user:create"
 	ScopeUserDelete                          ScopeName = "user:delete"
+	ScopeUserImpersonate                     ScopeName = "user:impersonate"
 	ScopeUserRead                            ScopeName = "user:read"
 	ScopeUserReadPersonal                    ScopeName = "user:read_personal"
 	ScopeUserUpdate                          ScopeName = "user:update"
@@ -153,6 +154,7 @@ const (
 	ScopeWorkspaceCreateAgent                ScopeName = "workspace:create_agent"
 	ScopeWorkspaceDelete                     ScopeName = "workspace:delete"
 	ScopeWorkspaceDeleteAgent                ScopeName = "workspace:delete_agent"
+	ScopeWorkspaceExtend                     ScopeName = "workspace:extend"
 	ScopeWorkspaceRead                       ScopeName = "workspace:read"
 	ScopeWorkspaceShare                      ScopeName = "workspace:share"
 	ScopeWorkspaceSsh                        ScopeName = "workspace:ssh"
@@ -173,6 +175,7 @@ const (
 	ScopeWorkspaceDormantCreateAgent         ScopeName = "workspace_dormant:create_agent"
 	ScopeWorkspaceDormantDelete              ScopeName = "workspace_dormant:delete"
 	ScopeWorkspaceDormantDeleteAgent         ScopeName = "workspace_dormant:delete_agent"
+	ScopeWorkspaceDormantExtend              ScopeName = "workspace_dormant:extend"
 	ScopeWorkspaceDormantRead                ScopeName = "workspace_dormant:read"
 	ScopeWorkspaceDormantShare               ScopeName = "workspace_dormant:share"
 	ScopeWorkspaceDormantSsh                 ScopeName = "workspace_dormant:ssh"
@@ -321,6 +324,7 @@ func (e ScopeName) Valid() bool {
 		ScopeUsageEventUpdate,
 		ScopeUserCreate,
 		ScopeUserDelete,
+		ScopeUserImpersonate,
 		ScopeUserRead,
 		ScopeUserReadPersonal,
 		ScopeUserUpdate,
@@ -341,6 +345,7 @@ func (e ScopeName) Valid() bool {
 		ScopeWorkspaceCreateAgent,
 		ScopeWorkspaceDelete,
 		ScopeWorkspaceDeleteAgent,
+		ScopeWorkspaceExtend,
 		ScopeWorkspaceRead,
 		ScopeWorkspaceShare,
 		ScopeWorkspaceSsh,
@@ -361,6 +366,7 @@ func (e ScopeName) Valid() bool {
 		ScopeWorkspaceDormantCreateAgent,
 		ScopeWorkspaceDormantDelete,
 		ScopeWorkspaceDormantDeleteAgent,
+		ScopeWorkspaceDormantExtend,
 		ScopeWorkspaceDormantRead,
 		ScopeWorkspaceDormantShare,
 		ScopeWorkspaceDormantSsh,
@@ -510,6 +516,7 @@ func AllScopeNameValues() []ScopeName {
 		ScopeUsageEventUpdate,
 		ScopeUserCreate,
 		ScopeUserDelete,
+		ScopeUserImpersonate,
 		ScopeUserRead,
 		ScopeUserReadPersonal,
 		ScopeUserUpdate,
@@ -530,6 +537,7 @@ func AllScopeNameValues() []ScopeName {
 		ScopeWorkspaceCreateAgent,
 		ScopeWorkspaceDelete,
 		ScopeWorkspaceDeleteAgent,
+		ScopeWorkspaceExtend,
 		ScopeWorkspaceRead,
 		ScopeWorkspaceShare,
 		ScopeWorkspaceSsh,
@@ -550,6 +558,7 @@ func AllScopeNameValues() []ScopeName {
 		ScopeWorkspaceDormantCreateAgent,
 		ScopeWorkspaceDormantDelete,
 		ScopeWorkspaceDormantDeleteAgent,
+		ScopeWorkspaceDormantExtend,
 		ScopeWorkspaceDormantRead,
 		ScopeWorkspaceDormantShare,
 		ScopeWorkspaceDormantSsh,
@@ -0,0 +1,318 @@
+package coderd
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// regionLoadPenaltyMS is added to the score of a region in proportion to
+	// its share of the assigned workspaces. A region every workspace is
+	// assigned to scores as if it were this much further away.
+	regionLoadPenaltyMS = 50
+	// regionWarningPenaltyMS is added to the score of a region with health
+	// check warnings.
+	regionWarningPenaltyMS = 25
+)
+
+// @Summary Get region recommendation
+// @ID get-region-recommendation
+// @Security CoderSessionToken
+// @Produce json
+// @Tags WorkspaceProxies
+// @Param workspace query string false "Workspace ID to include the latencies of its agents for" format(uuid)
+// @Param latency query []string false "Client latency to a region as <region_id>:<milliseconds>"
+// @Success 200 {object} codersdk.RegionRecommendation
+// @Router /api/v2/regions/recommendation [get]
+func (api *API) regionRecommendation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vals := r.URL.Query()
+	parser := httpapi.NewQueryParamParser()
+	workspaceID := parser.UUID(vals, uuid.Nil, "workspace")
+	clientLatencies := parseRegionLatencies(parser, vals, "latency")
+	parser.ErrorExcessParams(vals)
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	var workspace database.Workspace
+	if workspaceID != uuid.Nil {
+		var err error
+		workspace, err = api.Database.GetWorkspaceByID(ctx, workspaceID)
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+
+	//nolint:gocritic // Like /regions, this route intentionally requests
+	// resources that users cannot usually access in order to consider every
+	// region.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	regions, err := api.recommendationRegions(sysCtx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	assigned, err := api.regionAssignmentCounts(sysCtx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	var workspaceLatencies map[uuid.UUID]int64
+	if workspaceID != uuid.Nil {
+		workspaceLatencies, err = api.workspaceRegionLatencies(ctx, workspace.ID, regions)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+
+	recommendation, ok := recommendRegion(regions, clientLatencies, workspaceLatencies, assigned)
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusServiceUnavailable, codersdk.Response{
+			Message: "No healthy region is available.",
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, recommendation)
+}
+
+// assignWorkspaceRegion assigns a newly created workspace the region
+// recommended for the latencies reported by the client that created it.
+func (api *API) assignWorkspaceRegion(ctx context.Context, workspaceID uuid.UUID, clientLatencies map[uuid.UUID]int64) (uuid.UUID, error) {
+	//nolint:gocritic // Users cannot usually read every region, see
+	// regionRecommendation.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	regions, err := api.recommendationRegions(sysCtx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	assigned, err := api.regionAssignmentCounts(sysCtx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	recommendation, ok := recommendRegion(regions, clientLatencies, nil, assigned)
+	if !ok {
+		return uuid.Nil, xerrors.New("no healthy region is available")
+	}
+	assignment, err := api.Database.UpsertWorkspaceRegionAssignment(ctx, database.UpsertWorkspaceRegionAssignmentParams{
+		WorkspaceID: workspaceID,
+		RegionID:    recommendation.Recommended.ID,
+		CreatedAt:   dbtime.Now(),
+	})
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("upsert workspace region assignment: %w", err)
+	}
+	return assignment.RegionID, nil
+}
+
+// recommendationRegions returns every region along with its health. Without
+// workspace proxies, the primary is the only region.
+func (api *API) recommendationRegions(ctx context.Context) ([]codersdk.WorkspaceProxy, error) {
+	proxies, err := (*api.WorkspaceProxiesFetchUpdater.Load()).Fetch(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("fetch workspace proxies: %w", err)
+	}
+	if len(proxies.Regions) > 0 {
+		return proxies.Regions, nil
+	}
+
+	primary, err := api.PrimaryRegion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []codersdk.WorkspaceProxy{{
+		Region: primary,
+		Status: codersdk.WorkspaceProxyStatus{Status: codersdk.ProxyHealthy},
+	}}, nil
+}
+
+func (api *API) regionAssignmentCounts(ctx context.Context) (map[uuid.UUID]int64, error) {
+	rows, err := api.Database.GetWorkspaceRegionAssignmentCounts(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace region assignment counts: %w", err)
+	}
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.RegionID] = row.Count
+	}
+	return counts, nil
+}
+
+// workspaceRegionLatencies returns the lowest latency in milliseconds the
+// agents of a workspace report to the DERP relay of each region, keyed by
+// region ID. The primary region is served by the embedded relay, workspace
+// proxies by the relay they register.
+func (api *API) workspaceRegionLatencies(ctx context.Context, workspaceID uuid.UUID, regions []codersdk.WorkspaceProxy) (map[uuid.UUID]int64, error) {
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspaceID)
+	if err != nil && !httpapi.Is404Error(err) {
+		return nil, xerrors.Errorf("get workspace agents: %w", err)
+	}
+
+	var primaryID uuid.UUID
+	regionsByCode := make(map[string]uuid.UUID, len(regions))
+	for _, region := range regions {
+		if region.Name == "primary" {
+			primaryID = region.ID
+			continue
+		}
+		regionsByCode["coder_"+strings.ToLower(region.Name)] = region.ID
+	}
+	derpRegions := map[int]uuid.UUID{}
+	for derpRegionID, derpRegion := range api.DERPMap().Regions {
+		if derpRegion.EmbeddedRelay {
+			derpRegions[derpRegionID] = primaryID
+			continue
+		}
+		if regionID, ok := regionsByCode[derpRegion.RegionCode]; ok {
+			derpRegions[derpRegionID] = regionID
+		}
+	}
+
+	latencies := map[uuid.UUID]int64{}
+	coordinator := *api.TailnetCoordinator.Load()
+	for _, agent := range agents {
+		node := coordinator.Node(agent.ID)
+		if node == nil {
+			continue
+		}
+		for rawRegion, seconds := range node.DERPLatency {
+			derpRegionIDStr, _, _ := strings.Cut(rawRegion, "-")
+			derpRegionID, err := strconv.Atoi(derpRegionIDStr)
+			if err != nil {
+				continue
+			}
+			regionID, ok := derpRegions[derpRegionID]
+			if !ok {
+				continue
+			}
+			ms := int64(seconds * 1000)
+			if current, ok := latencies[regionID]; !ok || ms < current {
+				latencies[regionID] = ms
+			}
+		}
+	}
+	return latencies, nil
+}
+
+// parseRegionLatencies parses latencies formatted as
+// <region_id>:<milliseconds>.
+func parseRegionLatencies(parser *httpapi.QueryParamParser, vals url.Values, queryParam string) map[uuid.UUID]int64 {
+	type regionLatency struct {
+		regionID uuid.UUID
+		ms       int64
+	}
+	parsed := httpapi.ParseCustomList(parser, vals, nil, queryParam, func(v string) (regionLatency, error) {
+		rawID, rawMS, ok := strings.Cut(v, ":")
+		if !ok {
+			return regionLatency{}, xerrors.Errorf("%q must be formatted as <region_id>:<milliseconds>", v)
+		}
+		regionID, err := uuid.Parse(rawID)
+		if err != nil {
+			return regionLatency{}, xerrors.Errorf("invalid region ID %q: %w", rawID, err)
+		}
+		ms, err := strconv.ParseInt(rawMS, 10, 64)
+		if err != nil || ms < 0 {
+			return regionLatency{}, xerrors.Errorf("invalid latency %q: must be a non-negative number of milliseconds", rawMS)
+		}
+		return regionLatency{regionID: regionID, ms: ms}, nil
+	})
+	latencies := make(map[uuid.UUID]int64, len(parsed))
+	for _, l := range parsed {
+		latencies[l.regionID] = l.ms
+	}
+	return latencies
+}
+
+// recommendRegion ranks the healthy regions that serve workspace traffic by
+// the latency of the client and of the workspace to them, their share of
+// assigned workspaces and their health check warnings. Regions the client
+// reported a latency for are always ranked first, since the client may not
+// be able to reach the others. False is returned if no region is healthy.
+func recommendRegion(regions []codersdk.WorkspaceProxy, clientLatencies, workspaceLatencies, assigned map[uuid.UUID]int64) (codersdk.RegionRecommendation, bool) {
+	candidates := make([]codersdk.RegionCandidate, 0, len(regions))
+	var totalAssigned int64
+	for _, region := range regions {
+		if region.Deleted || region.DerpOnly || !region.Healthy {
+			continue
+		}
+		candidate := codersdk.RegionCandidate{
+			Region:             region.Region,
+			AssignedWorkspaces: assigned[region.ID],
+			Warnings:           region.Status.Report.Warnings,
+		}
+		if candidate.Warnings == nil {
+			candidate.Warnings = []string{}
+		}
+		if ms, ok := clientLatencies[region.ID]; ok {
+			candidate.ClientLatencyMS = &ms
+		}
+		if ms, ok := workspaceLatencies[region.ID]; ok {
+			candidate.WorkspaceLatencyMS = &ms
+		}
+		totalAssigned += candidate.AssignedWorkspaces
+		candidates = append(candidates, candidate)
+	}
+	if len(candidates) == 0 {
+		return codersdk.RegionRecommendation{}, false
+	}
+
+	for i := range candidates {
+		c := &candidates[i]
+		if c.ClientLatencyMS != nil {
+			c.Score += float64(*c.ClientLatencyMS)
+		}
+		if c.WorkspaceLatencyMS != nil {
+			c.Score += float64(*c.WorkspaceLatencyMS)
+		}
+		if totalAssigned > 0 {
+			c.Score += float64(c.AssignedWorkspaces) / float64(totalAssigned) * regionLoadPenaltyMS
+		}
+		if len(c.Warnings) > 0 {
+			c.Score += regionWarningPenaltyMS
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b codersdk.RegionCandidate) int {
+		if (a.ClientLatencyMS == nil) != (b.ClientLatencyMS == nil) {
+			if a.ClientLatencyMS != nil {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(
+			cmp.Compare(a.Score, b.Score),
+			cmp.Compare(a.Region.Name, b.Region.Name),
+		)
+	})
+
+	return codersdk.RegionRecommendation{
+		Recommended: candidates[0].Region,
+		Candidates:  candidates,
+	}, true
+}
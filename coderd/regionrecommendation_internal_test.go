@@ -0,0 +1,137 @@
+package coderd
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestRecommendRegion(t *testing.T) {
+	t.Parallel()
+
+	region := func(name string, status codersdk.ProxyHealthStatus, warnings ...string) codersdk.WorkspaceProxy {
+		return codersdk.WorkspaceProxy{
+			Region: codersdk.Region{
+				ID:      uuid.New(),
+				Name:    name,
+				Healthy: status == codersdk.ProxyHealthy,
+			},
+			Status: codersdk.WorkspaceProxyStatus{
+				Status: status,
+				Report: codersdk.ProxyHealthReport{Warnings: warnings},
+			},
+		}
+	}
+	var (
+		primary     = region("primary", codersdk.ProxyHealthy)
+		europe      = region("europe", codersdk.ProxyHealthy)
+		asia        = region("asia", codersdk.ProxyHealthy, "proxy is running an old version")
+		unreachable = region("australia", codersdk.ProxyUnreachable)
+		derpOnly    = region("derp", codersdk.ProxyHealthy)
+	)
+	derpOnly.DerpOnly = true
+	regions := []codersdk.WorkspaceProxy{primary, europe, asia, unreachable, derpOnly}
+
+	candidateNames := func(r codersdk.RegionRecommendation) []string {
+		names := make([]string, 0, len(r.Candidates))
+		for _, c := range r.Candidates {
+			names = append(names, c.Region.Name)
+		}
+		return names
+	}
+
+	t.Run("ClientLatency", func(t *testing.T) {
+		t.Parallel()
+		r, ok := recommendRegion(regions, map[uuid.UUID]int64{
+			primary.ID:     120,
+			europe.ID:      20,
+			asia.ID:        250,
+			unreachable.ID: 5,
+			derpOnly.ID:    5,
+		}, nil, nil)
+		require.True(t, ok)
+		require.Equal(t, europe.Region, r.Recommended)
+		// Unhealthy and DERP-only regions are never candidates.
+		require.Equal(t, []string{"europe", "primary", "asia"}, candidateNames(r))
+		require.EqualValues(t, 20, *r.Candidates[0].ClientLatencyMS)
+		require.EqualValues(t, 250+regionWarningPenaltyMS, r.Candidates[2].Score)
+	})
+
+	t.Run("UnknownClientLatencyLast", func(t *testing.T) {
+		t.Parallel()
+		r, ok := recommendRegion(regions, map[uuid.UUID]int64{
+			asia.ID: 300,
+		}, nil, nil)
+		require.True(t, ok)
+		require.Equal(t, asia.Region, r.Recommended)
+		require.Equal(t, []string{"asia", "europe", "primary"}, candidateNames(r))
+		require.Nil(t, r.Candidates[1].ClientLatencyMS)
+	})
+
+	t.Run("WorkspaceLatency", func(t *testing.T) {
+		t.Parallel()
+		r, ok := recommendRegion(regions, map[uuid.UUID]int64{
+			primary.ID: 40,
+			europe.ID:  30,
+		}, map[uuid.UUID]int64{
+			primary.ID: 5,
+			europe.ID:  80,
+		}, nil)
+		require.True(t, ok)
+		require.Equal(t, primary.Region, r.Recommended)
+		require.EqualValues(t, 45, r.Candidates[0].Score)
+	})
+
+	t.Run("Load", func(t *testing.T) {
+		t.Parallel()
+		r, ok := recommendRegion(regions, map[uuid.UUID]int64{
+			primary.ID: 30,
+			europe.ID:  20,
+		}, nil, map[uuid.UUID]int64{
+			europe.ID: 9,
+			asia.ID:   1,
+		})
+		require.True(t, ok)
+		// Europe has 90% of the assigned workspaces, which outweighs its
+		// lower latency.
+		require.Equal(t, primary.Region, r.Recommended)
+		require.EqualValues(t, 9, r.Candidates[1].AssignedWorkspaces)
+		require.InDelta(t, 20+0.9*regionLoadPenaltyMS, r.Candidates[1].Score, 0.001)
+	})
+
+	t.Run("NoHealthyRegion", func(t *testing.T) {
+		t.Parallel()
+		_, ok := recommendRegion([]codersdk.WorkspaceProxy{unreachable, derpOnly}, nil, nil, nil)
+		require.False(t, ok)
+	})
+}
+
+func TestParseRegionLatencies(t *testing.T) {
+	t.Parallel()
+
+	regionA, regionB := uuid.New(), uuid.New()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		parser := httpapi.NewQueryParamParser()
+		latencies := parseRegionLatencies(parser, url.Values{
+			"latency": {regionA.String() + ":42", regionB.String() + ":7"},
+		}, "latency")
+		require.Empty(t, parser.Errors)
+		require.Equal(t, map[uuid.UUID]int64{regionA: 42, regionB: 7}, latencies)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, v := range []string{"42", "not-a-uuid:42", regionA.String() + ":fast", regionA.String() + ":-1"} {
+			parser := httpapi.NewQueryParamParser()
+			_ = parseRegionLatencies(parser, url.Values{"latency": {v}}, "latency")
+			require.Len(t, parser.Errors, 1, v)
+		}
+	})
+}
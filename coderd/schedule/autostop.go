@@ -236,6 +236,31 @@ func CalculateAutostop(ctx context.Context, params CalculateAutostopParams) (Aut
 		}
 	}
 
+	// Enforce the template nightly stop. Unlike the autostop requirement it
+	// applies every day, so the max deadline is capped at the next occurrence
+	// after the build completed. Activity bumps never extend past it.
+	if templateSchedule.NightlyStop.Enabled() {
+		userQuietHoursSchedule, err := params.UserQuietHoursScheduleStore.Get(ctx, db, workspace.OwnerID)
+		if err != nil {
+			return autostop, xerrors.Errorf("get user quiet hours schedule options: %w", err)
+		}
+
+		// The nightly stop is interpreted in the timezone of the user's quiet
+		// hours schedule. If the deployment isn't entitled to use quiet hours,
+		// fall back to UTC.
+		loc := time.UTC
+		if userQuietHoursSchedule.Schedule != nil {
+			loc = userQuietHoursSchedule.Schedule.Location()
+		}
+		nightlyStop, err := templateSchedule.NightlyStop.Next(buildCompletedAt, loc)
+		if err != nil {
+			return autostop, xerrors.Errorf("calculate next nightly stop: %w", err)
+		}
+		if autostop.MaxDeadline.IsZero() || nightlyStop.Before(autostop.MaxDeadline) {
+			autostop.MaxDeadline = nightlyStop
+		}
+	}
+
 	// If the workspace doesn't have a deadline or the max deadline is sooner
 	// than the workspace deadline, use the max deadline as the actual deadline.
 	if !autostop.MaxDeadline.IsZero() && (autostop.Deadline.IsZero() || autostop.MaxDeadline.Before(autostop.Deadline)) {
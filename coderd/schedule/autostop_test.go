@@ -85,6 +85,7 @@ func TestCalculateAutoStop(t *testing.T) {
 		templateAllowAutostop       bool
 		templateDefaultTTL          time.Duration
 		templateAutostopRequirement schedule.TemplateAutostopRequirement
+		templateNightlyStop         schedule.TemplateNightlyStop
 		userQuietHoursSchedule      string
 		// workspaceTTL is usually copied from the template's TTL when the
 		// workspace is made, so it takes precedence unless
@@ -483,6 +484,45 @@ func TestCalculateAutoStop(t *testing.T) {
 			expectedMaxDeadline: time.Date(pastDateNight.Year(), pastDateNight.Month(), pastDateNight.Day()+1, 11, 0, 0, 0, chicago),
 			errContains:         "",
 		},
+		{
+			name:                  "NightlyStopCapsTTL",
+			buildCompletedAt:      fridayEveningSydney,
+			templateAllowAutostop: true,
+			templateNightlyStop:   schedule.TemplateNightlyStop{Time: "00:00"},
+			// The nightly stop uses the timezone of the quiet hours.
+			userQuietHoursSchedule: sydneyQuietHours,
+			workspaceTTL:           8 * time.Hour,
+			// expectedDeadline is copied from expectedMaxDeadline.
+			expectedMaxDeadline: saturdayMidnightSydney,
+		},
+		{
+			name:                   "NightlyStopAfterTTL",
+			buildCompletedAt:       fridayEveningSydney,
+			templateAllowAutostop:  true,
+			templateNightlyStop:    schedule.TemplateNightlyStop{Time: "00:00"},
+			userQuietHoursSchedule: sydneyQuietHours,
+			workspaceTTL:           time.Hour,
+			expectedDeadline:       fridayEveningSydney.Add(time.Hour),
+			expectedMaxDeadline:    saturdayMidnightSydney,
+		},
+		{
+			name:                  "NightlyStopNoQuietHours",
+			buildCompletedAt:      wednesdayMidnightUTC.Add(20 * time.Hour),
+			templateAllowAutostop: true,
+			templateNightlyStop:   schedule.TemplateNightlyStop{Time: "23:30"},
+			// Without quiet hours the nightly stop is in UTC.
+			userQuietHoursSchedule: "",
+			workspaceTTL:           0,
+			expectedMaxDeadline:    wednesdayMidnightUTC.Add(23*time.Hour + 30*time.Minute),
+		},
+		{
+			name:                  "NightlyStopPassedToday",
+			buildCompletedAt:      wednesdayMidnightUTC.Add(23*time.Hour + 45*time.Minute),
+			templateAllowAutostop: true,
+			templateNightlyStop:   schedule.TemplateNightlyStop{Time: "23:30"},
+			workspaceTTL:          0,
+			expectedMaxDeadline:   wednesdayMidnightUTC.Add(47*time.Hour + 30*time.Minute),
+		},
 	}
 
 	for _, c := range cases {
@@ -500,6 +540,7 @@ func TestCalculateAutoStop(t *testing.T) {
 						DefaultTTL:           c.templateDefaultTTL,
 						AutostopRequirement:  c.templateAutostopRequirement,
 						AutostartRequirement: c.templateAutoStart,
+						NightlyStop:          c.templateNightlyStop,
 					}, nil
 				},
 			}
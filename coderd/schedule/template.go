@@ -2,6 +2,8 @@ package schedule
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -116,6 +118,150 @@ func VerifyTemplateAutostartRequirement(days uint8) error {
 	return nil
 }
 
+// nightlyStopTimeLayout is the format of TemplateNightlyStop.Time.
+const nightlyStopTimeLayout = "15:04"
+
+// TemplateNightlyStop dictates a time of day at which running workspaces are
+// stopped. Unlike the TTL it is not extended by activity, and unlike the
+// autostop requirement it applies every day.
+type TemplateNightlyStop struct {
+	// Time is the time of day in HH:MM format. It is interpreted in the
+	// timezone of the workspace owner's quiet hours schedule, so users in
+	// different timezones are all stopped at their local time. If empty,
+	// workspaces are not stopped nightly.
+	Time string
+}
+
+// Enabled returns true if workspaces must be stopped nightly.
+func (r TemplateNightlyStop) Enabled() bool {
+	return r.Time != ""
+}
+
+// Next returns the first nightly stop after t in the given location.
+func (r TemplateNightlyStop) Next(t time.Time, loc *time.Location) (time.Time, error) {
+	tod, err := time.Parse(nightlyStopTimeLayout, r.Time)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("parse nightly stop time %q: %w", r.Time, err)
+	}
+	t = t.In(loc)
+	yy, mm, dd := t.Date()
+	next := time.Date(yy, mm, dd, tod.Hour(), tod.Minute(), 0, 0, loc)
+	if !next.After(t) {
+		// time.Date will correctly normalize the day if it's past the end of
+		// the month.
+		next = time.Date(yy, mm, dd+1, tod.Hour(), tod.Minute(), 0, 0, loc)
+	}
+	return next, nil
+}
+
+// NormalizeTemplateNightlyStopTime validates a nightly stop time and returns
+// it in HH:MM format. The empty string disables the nightly stop.
+func NormalizeTemplateNightlyStopTime(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	tod, err := time.Parse(nightlyStopTimeLayout, value)
+	if err != nil {
+		return "", xerrors.New("invalid nightly stop time, must be in HH:MM format")
+	}
+	return tod.Format(nightlyStopTimeLayout), nil
+}
+
+// TemplateMaxLifetime dictates how long workspaces may exist, measured from
+// their creation. Unlike the TTL and the dormancy thresholds it is not
+// extended by activity.
+type TemplateMaxLifetime struct {
+	// Duration is the maximum lifetime. If zero, workspaces do not expire.
+	Duration time.Duration
+	// Action is what happens to workspaces past their maximum lifetime.
+	Action database.MaxLifetimeAction
+}
+
+// MaxLifetimeWarningDays are the number of days before a workspace reaches
+// its maximum lifetime at which its owner is warned.
+var MaxLifetimeWarningDays = []int{7, 3, 1}
+
+// Enabled returns true if workspaces have a maximum lifetime.
+func (l TemplateMaxLifetime) Enabled() bool {
+	return l.Duration > 0
+}
+
+// ExpiresAt returns the time at which a workspace created at createdAt
+// reaches its maximum lifetime. The boolean is false if the lifetime is
+// unlimited.
+func (l TemplateMaxLifetime) ExpiresAt(createdAt time.Time) (time.Time, bool) {
+	if !l.Enabled() {
+		return time.Time{}, false
+	}
+	return createdAt.Add(l.Duration), true
+}
+
+// VerifyTemplateMaxLifetime returns an error if the max lifetime is invalid.
+func VerifyTemplateMaxLifetime(l TemplateMaxLifetime) error {
+	if l.Duration < 0 {
+		return xerrors.New("invalid max lifetime, must not be negative")
+	}
+	if l.Enabled() && l.Duration < 24*time.Hour {
+		return xerrors.New("invalid max lifetime, must be 0 (disabled) or at least one day")
+	}
+	if !l.Action.Valid() {
+		return xerrors.Errorf("invalid max lifetime action %q, must be one of %v", l.Action, database.AllMaxLifetimeActionValues())
+	}
+	return nil
+}
+
+// TemplateIdleReclaim dictates how quickly idle workspaces running expensive
+// resources, such as GPUs, are stopped. It applies to workspaces with a
+// resource whose metadata matches the resource selector, and is typically
+// much shorter than the default TTL so that CPU workspaces keep a longer grace
+// period.
+type TemplateIdleReclaim struct {
+	// TTL is how long a running workspace may be idle before it is stopped. If
+	// zero, idle reclaim is disabled.
+	TTL time.Duration
+	// ResourceSelector is a key=value pair matched against the metadata of the
+	// workspace resources, e.g. "gpu=true".
+	ResourceSelector string
+}
+
+// Enabled returns true if idle workspaces are reclaimed.
+func (r TemplateIdleReclaim) Enabled() bool {
+	return r.TTL > 0
+}
+
+// Matches returns true if any of the resource metadata matches the resource
+// selector.
+func (r TemplateIdleReclaim) Matches(metadata []database.WorkspaceResourceMetadatum) bool {
+	key, value, ok := strings.Cut(r.ResourceSelector, "=")
+	if !ok {
+		return false
+	}
+	for _, m := range metadata {
+		if m.Key == key && m.Value.Valid && m.Value.String == value {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyTemplateIdleReclaim returns an error if the idle reclaim policy is
+// invalid.
+func VerifyTemplateIdleReclaim(r TemplateIdleReclaim) error {
+	if r.TTL < 0 {
+		return xerrors.New("invalid idle reclaim ttl, must not be negative")
+	}
+	if !r.Enabled() {
+		return nil
+	}
+	if r.TTL < time.Minute {
+		return xerrors.New("invalid idle reclaim ttl, must be 0 (disabled) or at least one minute")
+	}
+	if key, _, ok := strings.Cut(r.ResourceSelector, "="); !ok || key == "" {
+		return xerrors.Errorf("invalid idle reclaim resource selector %q, must be in the form key=value", r.ResourceSelector)
+	}
+	return nil
+}
+
 type TemplateScheduleOptions struct {
 	UserAutostartEnabled bool
 	UserAutostopEnabled  bool
@@ -123,6 +269,12 @@ type TemplateScheduleOptions struct {
 	// ActivityBump dictates the duration to bump the workspace's deadline by if
 	// Coder detects activity from the user. A value of 0 means no bumping.
 	ActivityBump time.Duration
+	// ActivityBumpConnectionTypes restricts the types of connections that bump
+	// the workspace's deadline. Empty means all connections bump it.
+	ActivityBumpConnectionTypes []string
+	// ActivityBumpMaxPerDay caps how much activity may bump the workspace's
+	// deadline per day. A value of 0 means unlimited.
+	ActivityBumpMaxPerDay time.Duration
 	// TimeTilAutostopNotify dictates how long before the workspace's autostop
 	// deadline a reminder notification should be sent. A value of 0 means
 	// disabled.
@@ -132,6 +284,15 @@ type TemplateScheduleOptions struct {
 	AutostopRequirement TemplateAutostopRequirement
 	// AutostartRequirement dictates when the workspace can be auto started.
 	AutostartRequirement TemplateAutostartRequirement
+	// NightlyStop dictates a time of day at which the workspace is stopped
+	// regardless of activity.
+	NightlyStop TemplateNightlyStop
+	// MaxLifetime dictates how long after their creation workspaces are
+	// stopped or deleted regardless of activity.
+	MaxLifetime TemplateMaxLifetime
+	// IdleReclaim dictates how long workspaces with expensive resources may be
+	// idle before they are stopped.
+	IdleReclaim TemplateIdleReclaim
 	// FailureTTL dictates the duration after which failed workspaces will be
 	// stopped automatically.
 	FailureTTL time.Duration
@@ -182,13 +343,16 @@ func (*agplTemplateScheduleStore) Get(ctx context.Context, db database.Store, te
 	return TemplateScheduleOptions{
 		// Disregard the values in the database, since user scheduling is an
 		// enterprise feature.
-		UserAutostartEnabled:  true,
-		UserAutostopEnabled:   true,
-		DefaultTTL:            time.Duration(tpl.DefaultTTL),
-		ActivityBump:          time.Duration(tpl.ActivityBump),
-		TimeTilAutostopNotify: time.Duration(tpl.TimeTilAutostopNotify),
+		UserAutostartEnabled:        true,
+		UserAutostopEnabled:         true,
+		DefaultTTL:                  time.Duration(tpl.DefaultTTL),
+		ActivityBump:                time.Duration(tpl.ActivityBump),
+		ActivityBumpConnectionTypes: tpl.ActivityBumpConnectionTypes,
+		ActivityBumpMaxPerDay:       time.Duration(tpl.ActivityBumpMaxPerDay),
+		TimeTilAutostopNotify:       time.Duration(tpl.TimeTilAutostopNotify),
 		// Disregard the values in the database, since AutostopRequirement,
-		// FailureTTL, TimeTilDormant, and TimeTilDormantAutoDelete are enterprise features.
+		// NightlyStop, MaxLifetime, IdleReclaim, FailureTTL, TimeTilDormant,
+		// and TimeTilDormantAutoDelete are enterprise features.
 		AutostartRequirement: TemplateAutostartRequirement{
 			// Default to allowing all days for AGPL
 			DaysOfWeek: 0b01111111,
@@ -199,6 +363,9 @@ func (*agplTemplateScheduleStore) Get(ctx context.Context, db database.Store, te
 			DaysOfWeek: 0,
 			Weeks:      1,
 		},
+		MaxLifetime: TemplateMaxLifetime{
+			Action: database.MaxLifetimeActionDelete,
+		},
 		FailureTTL:               0,
 		TimeTilDormant:           0,
 		TimeTilDormantAutoDelete: 0,
@@ -209,8 +376,16 @@ func (*agplTemplateScheduleStore) Set(ctx context.Context, db database.Store, tp
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
+	// The column is not nullable, so an omitted list means all connection
+	// types bump the deadline.
+	if opts.ActivityBumpConnectionTypes == nil {
+		opts.ActivityBumpConnectionTypes = []string{}
+	}
+
 	if int64(opts.DefaultTTL) == tpl.DefaultTTL &&
 		int64(opts.ActivityBump) == tpl.ActivityBump &&
+		slices.Equal(opts.ActivityBumpConnectionTypes, tpl.ActivityBumpConnectionTypes) &&
+		int64(opts.ActivityBumpMaxPerDay) == tpl.ActivityBumpMaxPerDay &&
 		int64(opts.TimeTilAutostopNotify) == tpl.TimeTilAutostopNotify {
 		// Avoid updating the UpdatedAt timestamp if nothing will be changed.
 		return tpl, nil
@@ -219,11 +394,13 @@ func (*agplTemplateScheduleStore) Set(ctx context.Context, db database.Store, tp
 	var template database.Template
 	err := db.InTx(func(db database.Store) error {
 		err := db.UpdateTemplateScheduleByID(ctx, database.UpdateTemplateScheduleByIDParams{
-			ID:                    tpl.ID,
-			UpdatedAt:             dbtime.Now(),
-			DefaultTTL:            int64(opts.DefaultTTL),
-			ActivityBump:          int64(opts.ActivityBump),
-			TimeTilAutostopNotify: int64(opts.TimeTilAutostopNotify),
+			ID:                          tpl.ID,
+			UpdatedAt:                   dbtime.Now(),
+			DefaultTTL:                  int64(opts.DefaultTTL),
+			ActivityBump:                int64(opts.ActivityBump),
+			ActivityBumpConnectionTypes: opts.ActivityBumpConnectionTypes,
+			ActivityBumpMaxPerDay:       int64(opts.ActivityBumpMaxPerDay),
+			TimeTilAutostopNotify:       int64(opts.TimeTilAutostopNotify),
 			// Don't allow changing these settings, but keep the value in the DB (to
 			// avoid clearing settings if the license has an issue).
 			AutostopRequirementDaysOfWeek: tpl.AutostopRequirementDaysOfWeek,
@@ -234,6 +411,11 @@ func (*agplTemplateScheduleStore) Set(ctx context.Context, db database.Store, tp
 			FailureTTL:                    tpl.FailureTTL,
 			TimeTilDormant:                tpl.TimeTilDormant,
 			TimeTilDormantAutoDelete:      tpl.TimeTilDormantAutoDelete,
+			NightlyStopTime:               tpl.NightlyStopTime,
+			MaxLifetime:                   tpl.MaxLifetime,
+			MaxLifetimeAction:             tpl.MaxLifetimeAction,
+			IdleReclaimTTL:                tpl.IdleReclaimTTL,
+			IdleReclaimResourceSelector:   tpl.IdleReclaimResourceSelector,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template schedule: %w", err)
@@ -24,6 +24,7 @@ import (
 // Supported query parameters:
 //
 //   - request_id: UUID (can be used to search for associated audits e.g. connect/disconnect or open/close)
+//   - correlation_id: UUID (the workspace build ID shared by all audits caused by one build)
 //   - resource_id: UUID
 //   - resource_target: string
 //   - username: string
@@ -52,6 +53,7 @@ func AuditLogs(ctx context.Context, db database.Store, query string) (database.G
 	parser := httpapi.NewQueryParamParser()
 	filter := database.GetAuditLogsOffsetParams{
 		RequestID:      parser.UUID(values, uuid.Nil, "request_id"),
+		CorrelationID:  parser.UUID(values, uuid.Nil, "correlation_id"),
 		ResourceID:     parser.UUID(values, uuid.Nil, "resource_id"),
 		ResourceTarget: parser.String(values, "", "resource_target"),
 		Username:       parser.String(values, "", "username"),
@@ -71,6 +73,7 @@ func AuditLogs(ctx context.Context, db database.Store, query string) (database.G
 	// nolint:exhaustruct // UserID and CountCap are not obtained from the query parameters.
 	countFilter := database.CountAuditLogsParams{
 		RequestID:      filter.RequestID,
+		CorrelationID:  filter.CorrelationID,
 		ResourceID:     filter.ResourceID,
 		ResourceTarget: filter.ResourceTarget,
 		Username:       filter.Username,
@@ -262,6 +265,18 @@ func Workspaces(ctx context.Context, db database.Store, query string, page coder
 	filter.Dormant = parser.Boolean(values, false, "dormant")
 	filter.LastUsedAfter = parser.Time3339Nano(values, time.Time{}, "last_used_after")
 	filter.LastUsedBefore = parser.Time3339Nano(values, time.Time{}, "last_used_before")
+	filter.CreatedAfter = parser.Time3339Nano(values, time.Time{}, "created_after")
+	filter.CreatedBefore = parser.Time3339Nano(values, time.Time{}, "created_before")
+	// The template version of the latest build can be matched by ID or by
+	// name. Names aren't unique across templates, so combine a name with the
+	// template filter to match a single version.
+	if templateVersion := parser.String(values, "", "template_version"); templateVersion != "" {
+		if id, err := uuid.Parse(templateVersion); err == nil {
+			filter.TemplateVersionID = id
+		} else {
+			filter.TemplateVersionName = templateVersion
+		}
+	}
 	filter.UsingActive = sql.NullBool{
 		// Invert the value of the query parameter to get the correct value.
 		// UsingActive returns if the workspace is on the latest template active version.
@@ -276,6 +291,7 @@ func Workspaces(ctx context.Context, db database.Store, query string, page coder
 	filter.Shared = parser.NullableBoolean(values, sql.NullBool{}, "shared")
 	filter.SharedWithUserID = parseUser(ctx, db, parser, values, "shared_with_user", actorID)
 	filter.SharedWithGroupID = parseGroup(ctx, db, parser, values, "shared_with_group")
+	filter.Sort = string(httpapi.ParseCustom(parser, values, "", "sort", httpapi.ParseEnum[codersdk.WorkspaceSort]))
 	// Translate healthy filter to has-agent statuses
 	// healthy:true = connected, healthy:false = disconnected or timeout
 	if healthy := parser.NullableBoolean(values, sql.NullBool{}, "healthy"); healthy.Valid {
@@ -432,8 +432,48 @@ func TestSearchWorkspace(t *testing.T) {
 				SharedWithGroupID: uuid.MustParse("a7d1ba00-53c7-4aa6-92ea-83157dd57480"),
 			},
 		},
+		{
+			Name:  "Sort",
+			Query: "sort:last_used_desc",
+			Expected: database.GetWorkspacesParams{
+				Sort: "last_used_desc",
+			},
+		},
+		{
+			Name:  "CreatedBeforeAfter",
+			Query: "created_after:2023-01-01T00:00:00Z created_before:2024-01-01T00:00:00Z",
+			Expected: database.GetWorkspacesParams{
+				CreatedAfter:  time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Name:  "TemplateVersionName",
+			Query: "template:docker template_version:V42",
+			Expected: database.GetWorkspacesParams{
+				TemplateName:        "docker",
+				TemplateVersionName: "v42",
+			},
+		},
+		{
+			Name:  "TemplateVersionID",
+			Query: "template_version:1D0D4E56-7D86-4B53-9F4C-0A6C8B0E2F3D",
+			Expected: database.GetWorkspacesParams{
+				TemplateVersionID: uuid.MustParse("1d0d4e56-7d86-4b53-9f4c-0a6c8b0e2f3d"),
+			},
+		},
 
 		// Failures
+		{
+			Name:                  "CreatedAfterInvalid",
+			Query:                 "created_after:yesterday",
+			ExpectedErrorContains: "must be a valid date format",
+		},
+		{
+			Name:                  "SortInvalid",
+			Query:                 "sort:size_desc",
+			ExpectedErrorContains: `"size_desc" is not a valid value`,
+		},
 		{
 			Name:                  "ParamExcessValue",
 			Query:                 "param:foo=bar=baz",
@@ -582,6 +622,16 @@ func TestSearchAudit(t *testing.T) {
 			Query:                 "request_id:foo",
 			ExpectedErrorContains: "valid uuid",
 		},
+		{
+			Name:  "CorrelationID",
+			Query: "correlation_id:0d6c7bb6-d5d5-4b4f-9b2a-2c1e8ff4e9a3",
+			Expected: database.GetAuditLogsOffsetParams{
+				CorrelationID: uuid.MustParse("0d6c7bb6-d5d5-4b4f-9b2a-2c1e8ff4e9a3"),
+			},
+			ExpectedCountParams: database.CountAuditLogsParams{
+				CorrelationID: uuid.MustParse("0d6c7bb6-d5d5-4b4f-9b2a-2c1e8ff4e9a3"),
+			},
+		},
 	}
 
 	for _, c := range testCases {
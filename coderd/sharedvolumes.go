@@ -0,0 +1,282 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get shared volumes by organization
+// @Description Shared volumes are attached by templates through the coder_shared_volume
+// @Description resource. Any number of workspaces can attach a volume read-only, but only
+// @Description one at a time read-write. The lock is enforced when the build that attaches
+// @Description the volume completes.
+// @ID get-shared-volumes-by-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.SharedVolume
+// @Router /api/v2/organizations/{organization}/shared-volumes [get]
+func (api *API) sharedVolumes(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	rows, err := api.Database.GetSharedVolumesByOrganizationID(ctx, organization.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching shared volumes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	volumes := make([]codersdk.SharedVolume, 0, len(rows))
+	for _, row := range rows {
+		volume := convertSharedVolume(row.SharedVolume)
+		volume.AttachmentCount = row.AttachmentCount
+		if row.LockWorkspaceID.Valid {
+			volume.LockedByWorkspaceID = &row.LockWorkspaceID.UUID
+		}
+		volumes = append(volumes, volume)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, volumes)
+}
+
+// @Summary Create shared volume
+// @ID create-shared-volume
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateSharedVolumeRequest true "Create shared volume request"
+// @Success 201 {object} codersdk.SharedVolume
+// @Router /api/v2/organizations/{organization}/shared-volumes [post]
+func (api *API) postSharedVolume(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	var req codersdk.CreateSharedVolumeRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceTemplate.InOrg(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var templateID uuid.NullUUID
+	if req.TemplateID != nil {
+		template, err := api.Database.GetTemplateByID(ctx, *req.TemplateID)
+		if httpapi.Is404Error(err) || (err == nil && template.OrganizationID != organization.ID) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Template %q not found in the organization.", req.TemplateID.String()),
+			})
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		templateID = uuid.NullUUID{UUID: template.ID, Valid: true}
+	}
+
+	now := dbtime.Now()
+	volume, err := api.Database.InsertSharedVolume(ctx, database.InsertSharedVolumeParams{
+		ID:             uuid.New(),
+		OrganizationID: organization.ID,
+		Name:           req.Name,
+		DisplayName:    req.DisplayName,
+		Description:    req.Description,
+		TemplateID:     templateID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if database.IsUniqueViolation(err, database.UniqueSharedVolumesOrganizationIDNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Shared volume %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating shared volume.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertSharedVolume(volume))
+}
+
+// @Summary Get shared volume attachments
+// @ID get-shared-volume-attachments
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param sharedvolume path string true "Shared volume ID" format(uuid)
+// @Success 200 {array} codersdk.SharedVolumeAttachment
+// @Router /api/v2/organizations/{organization}/shared-volumes/{sharedvolume}/attachments [get]
+func (api *API) sharedVolumeAttachments(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	volume, ok := api.sharedVolumeParam(rw, r)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetActiveSharedVolumeAttachmentsBySharedVolumeID(ctx, volume.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching shared volume attachments.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	attachments := make([]codersdk.SharedVolumeAttachment, 0, len(rows))
+	for _, row := range rows {
+		attachment := row.SharedVolumeAttachment
+		attachments = append(attachments, codersdk.SharedVolumeAttachment{
+			ID:                 attachment.ID,
+			SharedVolumeID:     attachment.SharedVolumeID,
+			WorkspaceID:        attachment.WorkspaceID,
+			WorkspaceName:      row.WorkspaceName,
+			WorkspaceOwnerName: row.WorkspaceOwnerUsername,
+			WorkspaceBuildID:   attachment.WorkspaceBuildID,
+			WorkspaceAgentID:   attachment.WorkspaceAgentID,
+			WorkspaceAgentName: row.WorkspaceAgentName,
+			MountPath:          attachment.MountPath,
+			AccessMode:         codersdk.SharedVolumeAccessMode(attachment.AccessMode),
+			AttachedAt:         attachment.AttachedAt,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, attachments)
+}
+
+// @Summary Force-detach shared volume attachment
+// @Description Force-detaching only releases the attachment and its read-write lock in
+// @Description coderd. The volume stays mounted in the workspace until its next build, so
+// @Description this is meant to recover the lock from a workspace that is stuck.
+// @ID force-detach-shared-volume-attachment
+// @Security CoderSessionToken
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param sharedvolume path string true "Shared volume ID" format(uuid)
+// @Param attachment path string true "Attachment ID" format(uuid)
+// @Success 204
+// @Router /api/v2/organizations/{organization}/shared-volumes/{sharedvolume}/attachments/{attachment} [delete]
+func (api *API) deleteSharedVolumeAttachment(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+
+	volume, ok := api.sharedVolumeParam(rw, r)
+	if !ok {
+		return
+	}
+	attachmentID, ok := httpmw.ParseUUIDParam(rw, r, "attachment")
+	if !ok {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceTemplate.InOrg(volume.OrganizationID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	attachment, err := api.Database.GetSharedVolumeAttachmentByID(ctx, attachmentID)
+	if httpapi.Is404Error(err) || (err == nil && (attachment.SharedVolumeID != volume.ID || attachment.DetachedAt.Valid)) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	_, err = api.Database.DetachSharedVolumeAttachmentByID(ctx, database.DetachSharedVolumeAttachmentByIDParams{
+		DetachedAt: dbtime.Now(),
+		DetachedBy: apiKey.UserID,
+		ID:         attachment.ID,
+	})
+	if httpapi.Is404Error(err) {
+		// The attachment was detached concurrently.
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error detaching shared volume.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// sharedVolumeParam fetches the shared volume in the URL, which must belong
+// to the organization in the URL.
+func (api *API) sharedVolumeParam(rw http.ResponseWriter, r *http.Request) (database.SharedVolume, bool) {
+	ctx := r.Context()
+	organization := httpmw.OrganizationParam(r)
+
+	volumeID, ok := httpmw.ParseUUIDParam(rw, r, "sharedvolume")
+	if !ok {
+		return database.SharedVolume{}, false
+	}
+	volume, err := api.Database.GetSharedVolumeByID(ctx, volumeID)
+	if httpapi.Is404Error(err) || (err == nil && volume.OrganizationID != organization.ID) {
+		httpapi.ResourceNotFound(rw)
+		return database.SharedVolume{}, false
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return database.SharedVolume{}, false
+	}
+	return volume, true
+}
+
+func convertSharedVolume(volume database.SharedVolume) codersdk.SharedVolume {
+	var templateID *uuid.UUID
+	if volume.TemplateID.Valid {
+		templateID = &volume.TemplateID.UUID
+	}
+	return codersdk.SharedVolume{
+		ID:             volume.ID,
+		OrganizationID: volume.OrganizationID,
+		Name:           volume.Name,
+		DisplayName:    volume.DisplayName,
+		Description:    volume.Description,
+		TemplateID:     templateID,
+		CreatedAt:      volume.CreatedAt,
+		UpdatedAt:      volume.UpdatedAt,
+	}
+}
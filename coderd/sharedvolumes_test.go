@@ -0,0 +1,102 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSharedVolumes(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	volume, err := client.CreateSharedVolume(ctx, owner.OrganizationID, codersdk.CreateSharedVolumeRequest{
+		Name:        "datasets",
+		DisplayName: "Datasets",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "datasets", volume.Name)
+
+	var apiErr *codersdk.Error
+	_, err = client.CreateSharedVolume(ctx, owner.OrganizationID, codersdk.CreateSharedVolumeRequest{Name: "datasets"})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	// Members can list volumes, but not manage them.
+	_, err = memberClient.CreateSharedVolume(ctx, owner.OrganizationID, codersdk.CreateSharedVolumeRequest{Name: "scratch"})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	writer := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        member.ID,
+	}).WithAgent().Do()
+	reader := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        member.ID,
+	}).WithAgent().Do()
+
+	attach := func(r dbfake.WorkspaceResponse, mode database.SharedVolumeAccessMode) (database.SharedVolumeAttachment, error) {
+		return db.InsertSharedVolumeAttachment(ctx, database.InsertSharedVolumeAttachmentParams{
+			ID:               uuid.New(),
+			SharedVolumeID:   volume.ID,
+			WorkspaceID:      r.Workspace.ID,
+			WorkspaceBuildID: r.Build.ID,
+			WorkspaceAgentID: r.Agents[0].ID,
+			MountPath:        "/data",
+			AccessMode:       mode,
+			AttachedAt:       dbtime.Now(),
+		})
+	}
+	locked, err := attach(writer, database.SharedVolumeAccessModeReadWrite)
+	require.NoError(t, err)
+	_, err = attach(reader, database.SharedVolumeAccessModeReadWrite)
+	require.True(t, database.IsUniqueViolation(err, database.UniqueSharedVolumeAttachmentsReadWriteLockIndex))
+	_, err = attach(reader, database.SharedVolumeAccessModeReadOnly)
+	require.NoError(t, err)
+
+	volumes, err := memberClient.SharedVolumes(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.Len(t, volumes, 1)
+	require.EqualValues(t, 2, volumes[0].AttachmentCount)
+	require.NotNil(t, volumes[0].LockedByWorkspaceID)
+	require.Equal(t, writer.Workspace.ID, *volumes[0].LockedByWorkspaceID)
+
+	attachments, err := client.SharedVolumeAttachments(ctx, owner.OrganizationID, volume.ID)
+	require.NoError(t, err)
+	require.Len(t, attachments, 2)
+	require.Equal(t, locked.ID, attachments[0].ID)
+	require.Equal(t, codersdk.SharedVolumeAccessModeReadWrite, attachments[0].AccessMode)
+
+	err = memberClient.DetachSharedVolumeAttachment(ctx, owner.OrganizationID, volume.ID, locked.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Force-detaching releases the lock.
+	err = client.DetachSharedVolumeAttachment(ctx, owner.OrganizationID, volume.ID, locked.ID)
+	require.NoError(t, err)
+	err = client.DetachSharedVolumeAttachment(ctx, owner.OrganizationID, volume.ID, locked.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	volumes, err = client.SharedVolumes(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.Len(t, volumes, 1)
+	require.EqualValues(t, 1, volumes[0].AttachmentCount)
+	require.Nil(t, volumes[0].LockedByWorkspaceID)
+	_, err = attach(reader, database.SharedVolumeAccessModeReadWrite)
+	require.NoError(t, err)
+}
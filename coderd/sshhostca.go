@@ -0,0 +1,203 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/cryptokeys"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// @Summary Get SSH host certificate authorities
+// @ID get-ssh-host-certificate-authorities
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.SSHHostCAResponse
+// @Router /api/v2/deployment/ssh-host-ca [get]
+func (api *API) sshHostCA(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Only the public halves of the CA keys are returned, which any
+	// authenticated user may see.
+	//nolint:gocritic // Reading crypto keys requires the key reader role.
+	keys, err := api.Database.GetCryptoKeysByFeature(dbauthz.AsKeyReader(ctx), database.CryptoKeyFeatureSSHHostCA)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching SSH host certificate authorities.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	now := api.Clock.Now()
+	patterns := sshHostCAPatterns(api.SSHConfig)
+	resp := codersdk.SSHHostCAResponse{
+		Keys: make([]codersdk.SSHHostCAKey, 0, len(keys)),
+	}
+	var knownHosts strings.Builder
+	for _, key := range keys {
+		if key.DeletesAt.Valid && !now.Before(key.DeletesAt.Time) {
+			continue
+		}
+		caPublicKey, err := cryptokeys.SSHHostCAPublicKey(key.Secret.String)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error decoding SSH host certificate authority.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(caPublicKey)))
+		caKey := codersdk.SSHHostCAKey{
+			PublicKey: publicKey,
+			Sequence:  key.Sequence,
+			StartsAt:  key.StartsAt,
+		}
+		if key.DeletesAt.Valid {
+			caKey.DeletesAt = &key.DeletesAt.Time
+		}
+		resp.Keys = append(resp.Keys, caKey)
+		if patterns != "" {
+			_, _ = fmt.Fprintf(&knownHosts, "@cert-authority %s %s\n", patterns, publicKey)
+		}
+	}
+	resp.KnownHosts = knownHosts.String()
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Issue SSH host certificate for workspace agent
+// @ID issue-ssh-host-certificate-for-workspace-agent
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.PostSSHHostCertificateRequest true "Host public key"
+// @Success 200 {object} agentsdk.SSHHostCertificateResponse
+// @Router /api/v2/workspaceagents/me/ssh-host-certificate [post]
+func (api *API) workspaceAgentSSHHostCertificate(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostSSHHostCertificateRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(req.PublicKey))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid host public key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if _, ok := hostKey.(*ssh.Certificate); ok {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Host public key must be a plain key, not a certificate.",
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	_, secret, err := api.SSHHostCAKeyCache.SigningKey(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching SSH host certificate authority.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	ca, err := cryptokeys.SSHHostCASigner(secret)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error decoding SSH host certificate authority.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	now := api.Clock.Now()
+	principals := sshHostPrincipals(api.SSHConfig, workspace.OwnerUsername, workspace.Name, workspaceAgent.Name)
+	keyID := fmt.Sprintf("%s/%s.%s", workspace.OwnerUsername, workspace.Name, workspaceAgent.Name)
+	cert, err := cryptokeys.SignSSHHostCertificate(ca, hostKey, keyID, principals, now)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error signing SSH host certificate.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Keep a record of every issued certificate so a compromised host key
+	// can be traced back to the certificates minted for it.
+	api.Logger.Info(ctx, "issued ssh host certificate",
+		slog.F("workspace_id", workspace.ID),
+		slog.F("agent_id", workspaceAgent.ID),
+		slog.F("key_id", keyID),
+		slog.F("serial", cert.Serial),
+		slog.F("host_key_fingerprint", ssh.FingerprintSHA256(hostKey)),
+		slog.F("principals", principals),
+	)
+
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.SSHHostCertificateResponse{
+		Certificate: strings.TrimSpace(string(ssh.MarshalAuthorizedKey(cert))),
+		ExpiresAt:   time.Unix(int64(cert.ValidBefore), 0).UTC(), //nolint:gosec // ValidBefore is a Unix time we set.
+	})
+}
+
+// sshHostPrincipals returns the host names an agent's certificate is valid
+// for. They mirror the Host entries that `coder config-ssh` writes, so that
+// OpenSSH checks the certificate against the name the user typed.
+func sshHostPrincipals(cfg codersdk.SSHConfigResponse, owner, workspace, agent string) []string {
+	var principals []string
+	if cfg.HostnamePrefix != "" {
+		principals = append(principals,
+			cfg.HostnamePrefix+workspace,
+			cfg.HostnamePrefix+workspace+"."+agent,
+		)
+	}
+	if cfg.HostnameSuffix != "" {
+		principals = append(principals,
+			workspace+"."+cfg.HostnameSuffix,
+			workspace+"."+owner+"."+cfg.HostnameSuffix,
+			agent+"."+workspace+"."+cfg.HostnameSuffix,
+			agent+"."+workspace+"."+owner+"."+cfg.HostnameSuffix,
+		)
+	}
+	if len(principals) == 0 {
+		principals = append(principals, workspace, agent+"."+workspace)
+	}
+	return principals
+}
+
+// sshHostCAPatterns returns the known_hosts host patterns the CA is trusted
+// for, or an empty string if the deployment has no SSH hostname scheme.
+func sshHostCAPatterns(cfg codersdk.SSHConfigResponse) string {
+	var patterns []string
+	if cfg.HostnamePrefix != "" {
+		patterns = append(patterns, cfg.HostnamePrefix+"*")
+	}
+	if cfg.HostnameSuffix != "" {
+		patterns = append(patterns, "*."+cfg.HostnameSuffix)
+	}
+	return strings.Join(patterns, ",")
+}
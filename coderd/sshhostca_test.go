@@ -0,0 +1,79 @@
+package coderd_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSSHHostCertificate(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionGraph: echo.ProvisionGraphWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL, agentsdk.WithFixedToken(authToken))
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	require.NoError(t, err)
+
+	resp, err := agentClient.PostSSHHostCertificate(ctx, agentsdk.PostSSHHostCertificateRequest{
+		PublicKey: string(ssh.MarshalAuthorizedKey(hostSigner.PublicKey())),
+	})
+	require.NoError(t, err)
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(resp.Certificate))
+	require.NoError(t, err)
+	cert, ok := parsed.(*ssh.Certificate)
+	require.True(t, ok)
+	require.Equal(t, uint32(ssh.HostCert), cert.CertType)
+	require.NotEmpty(t, cert.ValidPrincipals)
+
+	ca, err := client.SSHHostCA(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, ca.Keys)
+
+	trusted := make(map[string]bool)
+	for _, key := range ca.Keys {
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+		require.NoError(t, err)
+		trusted[string(pub.Marshal())] = true
+	}
+	checker := &ssh.CertChecker{
+		IsHostAuthority: func(auth ssh.PublicKey, _ string) bool {
+			return trusted[string(auth.Marshal())]
+		},
+	}
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 22}
+	require.NoError(t, checker.CheckHostKey(net.JoinHostPort(cert.ValidPrincipals[0], "22"), addr, cert))
+
+	// Certificates are not accepted as host keys to sign.
+	_, err = agentClient.PostSSHHostCertificate(ctx, agentsdk.PostSSHHostCertificateRequest{
+		PublicKey: resp.Certificate,
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template health reports
+// @Description Health reports are generated weekly by the report generator, which also
+// @Description sends them to the template admins of the organization.
+// @ID get-template-health-reports
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param limit query int false "Page limit"
+// @Success 200 {array} codersdk.TemplateHealthReport
+// @Router /api/v2/templates/{template}/health-reports [get]
+func (api *API) templateHealthReports(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	qp := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	limit := p.PositiveInt32(qp, 0, "limit")
+	p.ErrorExcessParams(qp)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	reports, err := api.Database.GetTemplateHealthReportsByTemplateID(ctx, database.GetTemplateHealthReportsByTemplateIDParams{
+		TemplateID: template.ID,
+		LimitOpt:   limit,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template health reports.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	sdkReports := make([]codersdk.TemplateHealthReport, 0, len(reports))
+	for _, report := range reports {
+		sdkReports = append(sdkReports, codersdk.TemplateHealthReport{
+			ID:                  report.ID,
+			TemplateID:          report.TemplateID,
+			PeriodStart:         report.PeriodStart,
+			PeriodEnd:           report.PeriodEnd,
+			TotalBuilds:         report.TotalBuilds,
+			FailedBuilds:        report.FailedBuilds,
+			AverageStartSeconds: report.AverageStartSeconds,
+			TotalWorkspaces:     report.TotalWorkspaces,
+			OutdatedWorkspaces:  report.OutdatedWorkspaces,
+			DormantWorkspaces:   report.DormantWorkspaces,
+			CreatedAt:           report.CreatedAt,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, sdkReports)
+}
@@ -0,0 +1,70 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateHealthReports(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	reports, err := client.TemplateHealthReports(ctx, template.ID, 0)
+	require.NoError(t, err)
+	require.Empty(t, reports)
+
+	now := dbtime.Now()
+	for week := 2; week > 0; week-- {
+		end := now.Add(-time.Duration(week-1) * 7 * 24 * time.Hour)
+		_, err := db.InsertTemplateHealthReport(ctx, database.InsertTemplateHealthReportParams{
+			ID:                  uuid.New(),
+			TemplateID:          template.ID,
+			PeriodStart:         end.Add(-7 * 24 * time.Hour),
+			PeriodEnd:           end,
+			TotalBuilds:         int64(10 * week),
+			FailedBuilds:        int64(week),
+			AverageStartSeconds: 42.5,
+			TotalWorkspaces:     5,
+			OutdatedWorkspaces:  2,
+			DormantWorkspaces:   1,
+			CreatedAt:           end,
+		})
+		require.NoError(t, err)
+	}
+
+	// The most recent report comes first.
+	reports, err = client.TemplateHealthReports(ctx, template.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	require.EqualValues(t, 10, reports[0].TotalBuilds)
+	require.EqualValues(t, 1, reports[0].FailedBuilds)
+	require.InDelta(t, 42.5, reports[0].AverageStartSeconds, 0.001)
+	require.True(t, reports[0].PeriodEnd.After(reports[1].PeriodEnd))
+
+	reports, err = client.TemplateHealthReports(ctx, template.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+
+	// Members can use the template, but not view its health.
+	var apiErr *codersdk.Error
+	_, err = memberClient.TemplateHealthReports(ctx, template.ID, 0)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
@@ -0,0 +1,216 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxTemplatePresetCohorts caps the number of cohorts of a single template.
+const maxTemplatePresetCohorts = 128
+
+// @Summary Get template preset cohorts
+// @ID get-template-preset-cohorts
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplatePresetCohort
+// @Router /api/v2/templates/{template}/preset-cohorts [get]
+func (api *API) templatePresetCohorts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	cohorts, err := api.Database.GetTemplatePresetCohortsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplatePresetCohorts(cohorts))
+}
+
+// @Summary Update template preset cohorts
+// @Description Targeting presets at cohorts lets one template serve several teams with
+// @Description curated defaults. Cohorts are keyed by preset name, so they apply to every
+// @Description version of the template.
+// @ID update-template-preset-cohorts
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.UpdateTemplatePresetCohortsRequest true "Preset cohorts request"
+// @Success 200 {array} codersdk.TemplatePresetCohort
+// @Router /api/v2/templates/{template}/preset-cohorts [put]
+func (api *API) putTemplatePresetCohorts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	var req codersdk.UpdateTemplatePresetCohortsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionUpdate, template.RBACObject()) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	validations, err := api.validateTemplatePresetCohorts(r, template, req.Cohorts)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid preset cohorts.",
+			Validations: validations,
+		})
+		return
+	}
+
+	cohorts := make([]database.TemplatePresetCohort, 0, len(req.Cohorts))
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteTemplatePresetCohortsByTemplateID(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("delete template preset cohorts: %w", err)
+		}
+		now := dbtime.Now()
+		for _, cohort := range req.Cohorts {
+			groupIDs := cohort.GroupIDs
+			if groupIDs == nil {
+				groupIDs = []uuid.UUID{}
+			}
+			roles := cohort.Roles
+			if roles == nil {
+				roles = []string{}
+			}
+			inserted, err := tx.InsertTemplatePresetCohort(ctx, database.InsertTemplatePresetCohortParams{
+				TemplateID: template.ID,
+				PresetName: cohort.PresetName,
+				GroupIds:   groupIDs,
+				Roles:      roles,
+				CreatedAt:  now,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert template preset cohort %q: %w", cohort.PresetName, err)
+			}
+			cohorts = append(cohorts, inserted)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplatePresetCohorts(cohorts))
+}
+
+// validateTemplatePresetCohorts checks that every cohort names a distinct
+// preset and targets at least one group or role, and that the groups belong
+// to the organization of the template.
+func (api *API) validateTemplatePresetCohorts(r *http.Request, template database.Template, cohorts []codersdk.TemplatePresetCohort) ([]codersdk.ValidationError, error) {
+	if len(cohorts) > maxTemplatePresetCohorts {
+		return []codersdk.ValidationError{{
+			Field:  "cohorts",
+			Detail: fmt.Sprintf("Must not contain more than %d cohorts.", maxTemplatePresetCohorts),
+		}}, nil
+	}
+
+	var (
+		validations []codersdk.ValidationError
+		names       = make(map[string]struct{}, len(cohorts))
+		groupIDs    = make(map[uuid.UUID]struct{})
+	)
+	for i, cohort := range cohorts {
+		if cohort.PresetName == "" {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  fmt.Sprintf("cohorts[%d].preset_name", i),
+				Detail: "Must not be empty.",
+			})
+		} else if _, ok := names[cohort.PresetName]; ok {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  fmt.Sprintf("cohorts[%d].preset_name", i),
+				Detail: fmt.Sprintf("Preset %q has more than one cohort.", cohort.PresetName),
+			})
+		}
+		names[cohort.PresetName] = struct{}{}
+
+		if len(cohort.GroupIDs) == 0 && len(cohort.Roles) == 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  fmt.Sprintf("cohorts[%d]", i),
+				Detail: "Must target at least one group or role.",
+			})
+		}
+		for j, role := range cohort.Roles {
+			if err := codersdk.NameValid(role); err != nil {
+				validations = append(validations, codersdk.ValidationError{
+					Field:  fmt.Sprintf("cohorts[%d].roles[%d]", i, j),
+					Detail: fmt.Sprintf("%q is not a valid role name.", role),
+				})
+			}
+		}
+		for _, groupID := range cohort.GroupIDs {
+			groupIDs[groupID] = struct{}{}
+		}
+	}
+	if len(groupIDs) == 0 {
+		return validations, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(groupIDs))
+	for id := range groupIDs {
+		ids = append(ids, id)
+	}
+	// Template administrators may target groups they cannot read.
+	// nolint:gocritic // The caller is authorized to update the template.
+	groups, err := api.Database.GetGroups(dbauthz.AsSystemRestricted(r.Context()), database.GetGroupsParams{
+		OrganizationID: template.OrganizationID,
+		GroupIds:       ids,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get groups: %w", err)
+	}
+	for _, group := range groups {
+		delete(groupIDs, group.Group.ID)
+	}
+	for i, cohort := range cohorts {
+		for j, groupID := range cohort.GroupIDs {
+			if _, ok := groupIDs[groupID]; ok {
+				validations = append(validations, codersdk.ValidationError{
+					Field:  fmt.Sprintf("cohorts[%d].group_ids[%d]", i, j),
+					Detail: fmt.Sprintf("Group %s does not exist in the organization of the template.", groupID),
+				})
+			}
+		}
+	}
+	return validations, nil
+}
+
+func convertTemplatePresetCohorts(cohorts []database.TemplatePresetCohort) []codersdk.TemplatePresetCohort {
+	res := make([]codersdk.TemplatePresetCohort, 0, len(cohorts))
+	for _, cohort := range cohorts {
+		res = append(res, codersdk.TemplatePresetCohort{
+			PresetName: cohort.PresetName,
+			GroupIDs:   cohort.GroupIds,
+			Roles:      cohort.Roles,
+		})
+	}
+	return res
+}
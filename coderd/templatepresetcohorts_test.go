@@ -0,0 +1,178 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplatePresetCohorts(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, database.Store, codersdk.CreateFirstUserResponse, codersdk.Template, codersdk.TemplateVersion) {
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		dbgen.Preset(t, db, database.InsertPresetParams{TemplateVersionID: version.ID, Name: "ML team"})
+		dbgen.Preset(t, db, database.InsertPresetParams{TemplateVersionID: version.ID, Name: "General"})
+		return client, db, owner, template, version
+	}
+
+	presetNames := func(presets []codersdk.Preset) []string {
+		names := make([]string, 0, len(presets))
+		for _, preset := range presets {
+			names = append(names, preset.Name)
+		}
+		return names
+	}
+
+	t.Run("FilterByGroup", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		client, db, owner, template, version := setup(t)
+		group := dbgen.Group(t, db, database.Group{OrganizationID: owner.OrganizationID})
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		mlClient, mlUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		dbgen.GroupMember(t, db, database.GroupMemberTable{GroupID: group.ID, UserID: mlUser.ID})
+
+		cohorts, err := client.UpdateTemplatePresetCohorts(ctx, template.ID, codersdk.UpdateTemplatePresetCohortsRequest{
+			Cohorts: []codersdk.TemplatePresetCohort{{PresetName: "ML team", GroupIDs: []uuid.UUID{group.ID}}},
+		})
+		require.NoError(t, err)
+		require.Len(t, cohorts, 1)
+		require.Equal(t, []uuid.UUID{group.ID}, cohorts[0].GroupIDs)
+
+		presets, err := memberClient.TemplateVersionPresets(ctx, version.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"General"}, presetNames(presets))
+
+		presets, err = mlClient.TemplateVersionPresets(ctx, version.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"ML team", "General"}, presetNames(presets))
+
+		// Template administrators see every preset.
+		presets, err = client.TemplateVersionPresets(ctx, version.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"ML team", "General"}, presetNames(presets))
+
+		// Builds with a preset outside the cohort of the owner are rejected.
+		var mlPresetID uuid.UUID
+		for _, preset := range presets {
+			if preset.Name == "ML team" {
+				mlPresetID = preset.ID
+			}
+		}
+		_, err = memberClient.CreateUserWorkspace(ctx, member.Username, codersdk.CreateWorkspaceRequest{
+			TemplateID:              template.ID,
+			Name:                    "ml",
+			TemplateVersionPresetID: mlPresetID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		workspace, err := mlClient.CreateUserWorkspace(ctx, mlUser.Username, codersdk.CreateWorkspaceRequest{
+			TemplateID:              template.ID,
+			Name:                    "ml",
+			TemplateVersionPresetID: mlPresetID,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, mlClient, workspace.LatestBuild.ID)
+	})
+
+	t.Run("FilterByRole", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		client, _, owner, template, version := setup(t)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		auditorClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleAuditor())
+
+		_, err := client.UpdateTemplatePresetCohorts(ctx, template.ID, codersdk.UpdateTemplatePresetCohortsRequest{
+			Cohorts: []codersdk.TemplatePresetCohort{{PresetName: "ML team", Roles: []string{rbac.RoleAuditor().Name}}},
+		})
+		require.NoError(t, err)
+
+		presets, err := memberClient.TemplateVersionPresets(ctx, version.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"General"}, presetNames(presets))
+
+		presets, err = auditorClient.TemplateVersionPresets(ctx, version.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"ML team", "General"}, presetNames(presets))
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		client, _, owner, template, version := setup(t)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		_, err := client.UpdateTemplatePresetCohorts(ctx, template.ID, codersdk.UpdateTemplatePresetCohortsRequest{
+			Cohorts: []codersdk.TemplatePresetCohort{{PresetName: "ML team", Roles: []string{rbac.RoleAuditor().Name}}},
+		})
+		require.NoError(t, err)
+
+		cohorts, err := client.UpdateTemplatePresetCohorts(ctx, template.ID, codersdk.UpdateTemplatePresetCohortsRequest{})
+		require.NoError(t, err)
+		require.Empty(t, cohorts)
+
+		cohorts, err = client.TemplatePresetCohorts(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, cohorts)
+
+		presets, err := memberClient.TemplateVersionPresets(ctx, version.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"ML team", "General"}, presetNames(presets))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		client, _, _, template, _ := setup(t)
+
+		for _, cohorts := range [][]codersdk.TemplatePresetCohort{
+			{{PresetName: "ML team"}},
+			{{PresetName: "ML team", GroupIDs: []uuid.UUID{uuid.New()}}},
+			{{PresetName: "ML team", Roles: []string{"not a role"}}},
+			{
+				{PresetName: "ML team", Roles: []string{rbac.RoleAuditor().Name}},
+				{PresetName: "ML team", Roles: []string{rbac.RoleMember().Name}},
+			},
+		} {
+			_, err := client.UpdateTemplatePresetCohorts(ctx, template.ID, codersdk.UpdateTemplatePresetCohortsRequest{Cohorts: cohorts})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+
+	t.Run("MemberCannotUpdate", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		client, _, owner, template, _ := setup(t)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		_, err := memberClient.UpdateTemplatePresetCohorts(ctx, template.ID, codersdk.UpdateTemplatePresetCohortsRequest{
+			Cohorts: []codersdk.TemplatePresetCohort{{PresetName: "ML team", Roles: []string{rbac.RoleMember().Name}}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,397 @@
+// Package templaterestart restarts the outdated running workspaces of a
+// template in batches.
+//
+// A restart is created by the API after a version of a template was promoted
+// to the active version. On every tick, the Controller starts a build on the
+// active version for the next batch of running workspaces that use another
+// version, once the builds of the previous batch are completed and the batch
+// interval has passed. Workspaces can be restricted to the quiet hours of
+// their owner. The restart is paused once the failure rate of its builds
+// exceeds the maximum, and aborted if the active version changes.
+package templaterestart
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/files"
+	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+)
+
+const (
+	// PollInterval is how often the controller evaluates in-progress
+	// restarts.
+	PollInterval = time.Minute
+	// QuietHoursWindow is how long after the start of the quiet hours of
+	// their owner workspaces can be restarted, if the restart honors quiet
+	// hours.
+	QuietHoursWindow = 4 * time.Hour
+)
+
+// Controller evaluates in-progress restarts on every tick from its channel.
+type Controller struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db                database.Store
+	pubsub            pubsub.Pubsub
+	fileCache         *files.Cache
+	buildUsageChecker *atomic.Pointer[wsbuilder.UsageChecker]
+	quietHoursStore   *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
+	log               slog.Logger
+	tick              <-chan time.Time
+	stats             chan<- Stats
+}
+
+// Stats contains statistics about the last run of the controller.
+type Stats struct {
+	// RestartedWorkspaceIDs contains the IDs of all workspaces a restart
+	// build was created for.
+	RestartedWorkspaceIDs []uuid.UUID
+	// PausedRestartIDs contains the IDs of all restarts that exceeded their
+	// maximum failure rate.
+	PausedRestartIDs []uuid.UUID
+	// CompletedRestartIDs contains the IDs of all restarts without any
+	// outdated running workspaces left.
+	CompletedRestartIDs []uuid.UUID
+	// AbortedRestartIDs contains the IDs of all restarts aborted because the
+	// active version of their template changed.
+	AbortedRestartIDs []uuid.UUID
+	// Error is set if the in-progress restarts could not be loaded or one of
+	// them could not be evaluated, which stops the run.
+	Error error
+}
+
+// New returns a new controller that evaluates in-progress restarts.
+func New(ctx context.Context, db database.Store, ps pubsub.Pubsub, fc *files.Cache, buildUsageChecker *atomic.Pointer[wsbuilder.UsageChecker], quietHoursStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore], log slog.Logger, tick <-chan time.Time) *Controller {
+	//nolint:gocritic // The controller manages the restarts of all templates.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Controller{
+		ctx:               ctx,
+		cancel:            cancel,
+		done:              make(chan struct{}),
+		db:                db,
+		pubsub:            ps,
+		fileCache:         fc,
+		buildUsageChecker: buildUsageChecker,
+		quietHoursStore:   quietHoursStore,
+		log:               log,
+		tick:              tick,
+		stats:             nil,
+	}
+}
+
+// WithStatsChannel will cause Controller to push a Stats to ch after every
+// tick. This push is blocking, so if ch is not read, the controller will hang.
+// This should only be used in tests.
+func (c *Controller) WithStatsChannel(ch chan<- Stats) *Controller {
+	c.stats = ch
+	return c
+}
+
+// Start will cause the controller to evaluate in-progress restarts on every
+// tick from its channel. It will stop when its context is Done, or when its
+// channel is closed.
+//
+// Start should only be called once.
+func (c *Controller) Start() {
+	go func() {
+		defer close(c.done)
+		defer c.cancel()
+
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case t, ok := <-c.tick:
+				if !ok {
+					return
+				}
+				stats := c.run(t)
+				if stats.Error != nil {
+					c.log.Warn(c.ctx, "error evaluating template workspace restarts once", slog.Error(stats.Error))
+				}
+				if c.stats != nil {
+					select {
+					case <-c.ctx.Done():
+						return
+					case c.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the controller.
+func (c *Controller) Close() {
+	c.cancel()
+	<-c.done
+}
+
+func (c *Controller) run(t time.Time) Stats {
+	stats := Stats{
+		RestartedWorkspaceIDs: []uuid.UUID{},
+		PausedRestartIDs:      []uuid.UUID{},
+		CompletedRestartIDs:   []uuid.UUID{},
+		AbortedRestartIDs:     []uuid.UUID{},
+	}
+
+	restarts, err := c.db.GetInProgressTemplateWorkspaceRestarts(c.ctx)
+	if err != nil {
+		stats.Error = xerrors.Errorf("get in-progress template workspace restarts: %w", err)
+		return stats
+	}
+
+	now := dbtime.Time(t)
+	for _, restart := range restarts {
+		outcome, restarted, err := c.evaluate(restart, now)
+		stats.RestartedWorkspaceIDs = append(stats.RestartedWorkspaceIDs, restarted...)
+		if err != nil {
+			stats.Error = xerrors.Errorf("evaluate restart %s: %w", restart.ID, err)
+			return stats
+		}
+		switch outcome {
+		case outcomePaused:
+			stats.PausedRestartIDs = append(stats.PausedRestartIDs, restart.ID)
+		case outcomeCompleted:
+			stats.CompletedRestartIDs = append(stats.CompletedRestartIDs, restart.ID)
+		case outcomeAborted:
+			stats.AbortedRestartIDs = append(stats.AbortedRestartIDs, restart.ID)
+		}
+	}
+
+	return stats
+}
+
+type outcome int
+
+const (
+	outcomeWaiting outcome = iota
+	outcomeRestarted
+	outcomePaused
+	outcomeCompleted
+	outcomeAborted
+)
+
+// evaluate continues a restart. It returns the IDs of the workspaces a build
+// was created for, even if an error occurred after some of the builds were
+// created.
+func (c *Controller) evaluate(restart database.TemplateWorkspaceRestart, now time.Time) (outcome, []uuid.UUID, error) {
+	log := c.log.With(slog.F("restart_id", restart.ID), slog.F("template_id", restart.TemplateID), slog.F("template_version_id", restart.TemplateVersionID))
+
+	template, err := c.db.GetTemplateByID(c.ctx, restart.TemplateID)
+	if err != nil {
+		return outcomeWaiting, nil, xerrors.Errorf("get template: %w", err)
+	}
+	if template.ActiveVersionID != restart.TemplateVersionID {
+		log.Info(c.ctx, "aborting template workspace restart, the active version changed")
+		return c.finish(restart, database.TemplateWorkspaceRestartStatusAborted,
+			fmt.Sprintf("The active version of the template was changed to %s.", template.ActiveVersionID), now, outcomeAborted)
+	}
+
+	buildStats, err := c.db.GetTemplateWorkspaceRestartBuildStats(c.ctx, database.GetTemplateWorkspaceRestartBuildStatsParams{
+		RestartID: restart.ID,
+		Since:     restart.StatsSince,
+	})
+	if err != nil {
+		return outcomeWaiting, nil, xerrors.Errorf("get build stats: %w", err)
+	}
+	if ExceedsFailureRate(restart, buildStats) {
+		log.Warn(c.ctx, "pausing template workspace restart", slog.F("completed", buildStats.CompletedSince), slog.F("failed", buildStats.FailedSince))
+		return c.finish(restart, database.TemplateWorkspaceRestartStatusPaused,
+			fmt.Sprintf("%d of %d restarts failed, exceeding the maximum failure rate of %g%%.",
+				buildStats.FailedSince, buildStats.CompletedSince, restart.MaxFailureRate*100), now, outcomePaused)
+	}
+
+	// The next batch is only started once the previous batch is completed,
+	// so the failure rate reflects all of its builds.
+	if buildStats.Pending > 0 {
+		return outcomeWaiting, nil, nil
+	}
+	if restart.LastBatchAt.Valid && now.Before(restart.LastBatchAt.Time.Add(time.Duration(restart.BatchInterval))) {
+		return outcomeWaiting, nil, nil
+	}
+
+	candidates, err := c.db.GetTemplateWorkspaceRestartCandidates(c.ctx, database.GetTemplateWorkspaceRestartCandidatesParams{
+		RestartID:         restart.ID,
+		TemplateID:        restart.TemplateID,
+		TemplateVersionID: restart.TemplateVersionID,
+	})
+	if err != nil {
+		return outcomeWaiting, nil, xerrors.Errorf("get candidates: %w", err)
+	}
+	if len(candidates) == 0 {
+		log.Info(c.ctx, "template workspace restart completed", slog.F("restarted", buildStats.Restarted))
+		return c.finish(restart, database.TemplateWorkspaceRestartStatusCompleted,
+			fmt.Sprintf("Restarted %d workspaces, %d of them failed.", buildStats.Restarted, buildStats.Failed), now, outcomeCompleted)
+	}
+
+	// Owners with several outdated workspaces are only looked up once.
+	inQuietHours := map[uuid.UUID]bool{}
+	restarted := []uuid.UUID{}
+	for _, candidate := range candidates {
+		if len(restarted) >= int(restart.BatchSize) {
+			break
+		}
+		if restart.HonorQuietHours {
+			eligible, ok := inQuietHours[candidate.OwnerID]
+			if !ok {
+				opts, err := (*c.quietHoursStore.Load()).Get(c.ctx, c.db, candidate.OwnerID)
+				if err != nil {
+					return outcomeWaiting, restarted, xerrors.Errorf("get quiet hours schedule of user %s: %w", candidate.OwnerID, err)
+				}
+				eligible = InQuietHours(opts.Schedule, now)
+				inQuietHours[candidate.OwnerID] = eligible
+			}
+			if !eligible {
+				continue
+			}
+		}
+
+		err := c.restartWorkspace(restart, candidate.ID, now)
+		if err != nil {
+			return outcomeWaiting, restarted, xerrors.Errorf("restart workspace %s: %w", candidate.ID, err)
+		}
+		restarted = append(restarted, candidate.ID)
+	}
+	if len(restarted) == 0 {
+		// None of the owners are in their quiet hours.
+		return outcomeWaiting, restarted, nil
+	}
+
+	err = c.db.UpdateTemplateWorkspaceRestartLastBatchAt(c.ctx, database.UpdateTemplateWorkspaceRestartLastBatchAtParams{
+		ID:          restart.ID,
+		LastBatchAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return outcomeWaiting, restarted, xerrors.Errorf("update last batch: %w", err)
+	}
+	log.Info(c.ctx, "restarted batch of workspaces", slog.F("count", len(restarted)))
+	return outcomeRestarted, restarted, nil
+}
+
+// restartWorkspace creates a start build of the workspace on the version of
+// the restart and records it. If the build can't be created, for example
+// because a required parameter of the version has no value, the error is
+// recorded instead and counts as a failed restart.
+func (c *Controller) restartWorkspace(restart database.TemplateWorkspaceRestart, workspaceID uuid.UUID, now time.Time) error {
+	var (
+		workspace database.Workspace
+		job       *database.ProvisionerJob
+	)
+	err := c.db.InTx(func(tx database.Store) error {
+		var err error
+		workspace, err = tx.GetWorkspaceByID(c.ctx, workspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart, *c.buildUsageChecker.Load()).
+			Initiator(restart.CreatedBy).
+			VersionID(restart.TemplateVersionID)
+		build, buildJob, _, err := builder.Build(c.ctx, tx, c.fileCache,
+			// nil authorization function skips the builder's RBAC checks.
+			// The restart was authorized when it was created.
+			nil,
+			// The build is created by a background worker, so there is no
+			// request IP to attach.
+			audit.WorkspaceBuildBaggage{},
+		)
+		if err != nil {
+			return err
+		}
+		job = buildJob
+		return tx.InsertTemplateWorkspaceRestartBuild(c.ctx, database.InsertTemplateWorkspaceRestartBuildParams{
+			RestartID:        restart.ID,
+			WorkspaceID:      workspaceID,
+			WorkspaceBuildID: uuid.NullUUID{UUID: build.ID, Valid: true},
+			CreatedAt:        now,
+		})
+	}, nil)
+	if buildErr, ok := errors.AsType[wsbuilder.BuildError](err); ok {
+		c.log.Warn(c.ctx, "failed to create restart build", slog.F("restart_id", restart.ID), slog.F("workspace_id", workspaceID), slog.Error(err))
+		_, response := buildErr.Response()
+		message := response.Message
+		if response.Detail != "" {
+			message = fmt.Sprintf("%s: %s", response.Message, response.Detail)
+		}
+		return c.db.InsertTemplateWorkspaceRestartBuild(c.ctx, database.InsertTemplateWorkspaceRestartBuildParams{
+			RestartID:   restart.ID,
+			WorkspaceID: workspaceID,
+			Error:       message,
+			CreatedAt:   now,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := provisionerjobs.PostJob(c.pubsub, *job); err != nil {
+		c.log.Error(c.ctx, "failed to post provisioner job to pubsub", slog.F("workspace_id", workspaceID), slog.Error(err))
+	}
+	err = wspubsub.PublishWorkspaceEvent(c.ctx, c.pubsub, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		c.log.Warn(c.ctx, "failed to publish workspace update", slog.F("workspace_id", workspaceID), slog.Error(err))
+	}
+	return nil
+}
+
+// finish sets the status of a restart. Restarts that were already finished
+// concurrently are left alone.
+func (c *Controller) finish(restart database.TemplateWorkspaceRestart, status database.TemplateWorkspaceRestartStatus, message string, now time.Time, o outcome) (outcome, []uuid.UUID, error) {
+	_, err := c.db.UpdateTemplateWorkspaceRestartStatus(c.ctx, database.UpdateTemplateWorkspaceRestartStatusParams{
+		ID:            restart.ID,
+		Status:        status,
+		StatusMessage: message,
+		UpdatedAt:     now,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return outcomeWaiting, nil, nil
+	}
+	if err != nil {
+		return outcomeWaiting, nil, xerrors.Errorf("update status: %w", err)
+	}
+	return o, nil, nil
+}
+
+// ExceedsFailureRate reports whether the restarts since the stats of a
+// restart were last reset exceed its maximum failure rate. The failure rate
+// is only considered once there are at least the minimum number of completed
+// restarts.
+func ExceedsFailureRate(restart database.TemplateWorkspaceRestart, stats database.GetTemplateWorkspaceRestartBuildStatsRow) bool {
+	if stats.CompletedSince == 0 || stats.CompletedSince < int64(restart.MinBuilds) {
+		return false
+	}
+	return float64(stats.FailedSince)/float64(stats.CompletedSince) > restart.MaxFailureRate
+}
+
+// InQuietHours reports whether t is within QuietHoursWindow of the start of
+// the quiet hours of the schedule. Without a schedule, for example because
+// quiet hours are not entitled, any time is within the quiet hours.
+func InQuietHours(sched *cron.Schedule, t time.Time) bool {
+	if sched == nil {
+		return true
+	}
+	return !sched.Next(t.Add(-QuietHoursWindow)).After(t)
+}
@@ -0,0 +1,128 @@
+package templaterestart_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
+	"github.com/coder/coder/v2/coderd/templaterestart"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestController(t *testing.T) {
+	t.Parallel()
+
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	log := testutil.Logger(t)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	running := make(map[uuid.UUID]bool)
+	for range 3 {
+		ws := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
+		running[ws.ID] = true
+	}
+	// Stopped workspaces are not restarted.
+	stopped := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, stopped.LatestBuild.ID)
+	coderdtest.MustTransitionWorkspace(t, client, stopped.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
+
+	next := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, next.ID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+	err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{ID: next.ID})
+	require.NoError(t, err)
+
+	restart, err := client.CreateTemplateWorkspaceRestart(ctx, template.ID, codersdk.CreateTemplateWorkspaceRestartRequest{
+		BatchSize:           2,
+		BatchIntervalMillis: time.Hour.Milliseconds(),
+		MaxFailureRate:      0.5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), restart.Remaining)
+
+	tickCh := make(chan time.Time)
+	statsCh := make(chan templaterestart.Stats)
+	controller := templaterestart.New(ctx, api.Database, api.Pubsub, api.FileCache, api.BuildUsageChecker, api.UserQuietHoursScheduleStore, log, tickCh).WithStatsChannel(statsCh)
+	controller.Start()
+	t.Cleanup(controller.Close)
+
+	// awaitRestarted waits for the restart builds of the workspaces and
+	// checks they use the new version.
+	awaitRestarted := func(workspaceIDs []uuid.UUID) {
+		for _, id := range workspaceIDs {
+			require.True(t, running[id], "only running workspaces are restarted")
+			delete(running, id)
+			ws, err := client.Workspace(ctx, id)
+			require.NoError(t, err)
+			require.Equal(t, next.ID, ws.LatestBuild.TemplateVersionID)
+			require.Equal(t, codersdk.WorkspaceTransitionStart, ws.LatestBuild.Transition)
+			coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
+		}
+	}
+
+	now := time.Now()
+	tickCh <- now
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.RestartedWorkspaceIDs, 2)
+	awaitRestarted(stats.RestartedWorkspaceIDs)
+
+	// The next batch waits for the batch interval.
+	tickCh <- now.Add(time.Minute)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.RestartedWorkspaceIDs)
+
+	tickCh <- now.Add(time.Hour)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.RestartedWorkspaceIDs, 1)
+	awaitRestarted(stats.RestartedWorkspaceIDs)
+	require.Empty(t, running)
+
+	tickCh <- now.Add(2 * time.Hour)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{restart.ID}, stats.CompletedRestartIDs)
+
+	restart, err = client.TemplateWorkspaceRestart(ctx, template.ID, restart.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.TemplateWorkspaceRestartStatusCompleted, restart.Status)
+	require.Equal(t, int64(3), restart.Restarted)
+	require.Equal(t, int64(3), restart.Succeeded)
+	require.Zero(t, restart.Failed)
+	require.Zero(t, restart.Remaining)
+	require.NotNil(t, restart.CompletedAt)
+
+	stopped, err = client.Workspace(ctx, stopped.ID)
+	require.NoError(t, err)
+	require.Equal(t, version.ID, stopped.LatestBuild.TemplateVersionID)
+}
+
+func TestInQuietHours(t *testing.T) {
+	t.Parallel()
+
+	sched, err := cron.Daily("CRON_TZ=UTC 0 22 * * *")
+	require.NoError(t, err)
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.True(t, templaterestart.InQuietHours(nil, day.Add(12*time.Hour)), "without a schedule, any time is within the quiet hours")
+	require.False(t, templaterestart.InQuietHours(sched, day.Add(21*time.Hour)))
+	require.True(t, templaterestart.InQuietHours(sched, day.Add(22*time.Hour)))
+	require.True(t, templaterestart.InQuietHours(sched, day.Add(25*time.Hour)))
+	require.False(t, templaterestart.InQuietHours(sched, day.Add(26*time.Hour+time.Minute)))
+}
@@ -0,0 +1,80 @@
+package templaterollout
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hash/fnv"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+// StagePercentage returns the percentage of workspaces in the cohort of the
+// current stage of a rollout. Rollouts without stages only target the members
+// of their group.
+func StagePercentage(rollout database.TemplateVersionRollout) int32 {
+	if int(rollout.CurrentStage) >= len(rollout.Stages) {
+		return 0
+	}
+	return rollout.Stages[rollout.CurrentStage]
+}
+
+// InCohort reports whether a workspace is part of the cohort of the current
+// stage of a rollout. Workspaces are assigned a stable bucket per rollout, so
+// the cohort of a stage includes the cohorts of the previous stages.
+func InCohort(rollout database.TemplateVersionRollout, workspaceID uuid.UUID, ownerInGroup bool) bool {
+	if ownerInGroup && rollout.GroupID.Valid {
+		return true
+	}
+	return int32(bucket(rollout.ID, workspaceID)) < StagePercentage(rollout)
+}
+
+// bucket hashes a workspace into one of 100 buckets. The rollout ID is part
+// of the hash so every rollout targets a different set of workspaces.
+func bucket(rolloutID, workspaceID uuid.UUID) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write(rolloutID[:])
+	_, _ = h.Write(workspaceID[:])
+	return h.Sum32() % 100
+}
+
+// VersionForWorkspace returns the template version a build of workspace that
+// uses the active version of template should use: the version of an
+// in-progress rollout if the workspace is part of its cohort, and the active
+// version otherwise.
+func VersionForWorkspace(ctx context.Context, db database.Store, template database.Template, workspace database.Workspace) (uuid.UUID, error) {
+	rollout, err := db.GetActiveTemplateVersionRolloutByTemplateID(ctx, template.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return template.ActiveVersionID, nil
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get active template version rollout: %w", err)
+	}
+	if rollout.PreviousVersionID != template.ActiveVersionID {
+		// The active version changed since the rollout was created, and the
+		// controller will abort it.
+		return template.ActiveVersionID, nil
+	}
+
+	ownerInGroup := false
+	if rollout.GroupID.Valid {
+		//nolint:gocritic // The owner of the workspace may not be able to
+		// read the group, and only its membership is checked.
+		groups, err := db.GetGroups(dbauthz.AsSystemRestricted(ctx), database.GetGroupsParams{
+			HasMemberID: workspace.OwnerID,
+			GroupIds:    []uuid.UUID{rollout.GroupID.UUID},
+		})
+		if err != nil {
+			return uuid.Nil, xerrors.Errorf("get rollout group: %w", err)
+		}
+		ownerInGroup = len(groups) > 0
+	}
+	if InCohort(rollout, workspace.ID, ownerInGroup) {
+		return rollout.TemplateVersionID, nil
+	}
+	return template.ActiveVersionID, nil
+}
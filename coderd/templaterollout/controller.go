@@ -0,0 +1,280 @@
+// Package templaterollout promotes template versions in stages.
+//
+// A rollout is created by the API for a version of a template. While it is in
+// progress, builds of workspaces in the cohort of its current stage that use
+// the active version get the rolled out version instead. The Controller
+// observes the failure rate of the start builds of the version in each stage
+// for a soak window, and then either continues with the next stage, promotes
+// the version to the active version after the last stage, or rolls the
+// rollout back.
+package templaterollout
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+// PollInterval is how often the controller evaluates in-progress rollouts.
+const PollInterval = time.Minute
+
+// Controller evaluates in-progress rollouts on every tick from its channel.
+type Controller struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db    database.Store
+	log   slog.Logger
+	tick  <-chan time.Time
+	stats chan<- Stats
+}
+
+// Stats contains statistics about the last run of the controller.
+type Stats struct {
+	// AdvancedRolloutIDs contains the IDs of all rollouts that continued with
+	// their next stage.
+	AdvancedRolloutIDs []uuid.UUID
+	// CompletedRolloutIDs contains the IDs of all rollouts whose version was
+	// promoted.
+	CompletedRolloutIDs []uuid.UUID
+	// RolledBackRolloutIDs contains the IDs of all rollouts that exceeded
+	// their maximum failure rate.
+	RolledBackRolloutIDs []uuid.UUID
+	// AbortedRolloutIDs contains the IDs of all rollouts aborted because the
+	// active version of their template changed.
+	AbortedRolloutIDs []uuid.UUID
+	// Error is set if the active rollouts could not be loaded or one of them
+	// could not be evaluated, which stops the run.
+	Error error
+}
+
+// New returns a new controller that evaluates in-progress rollouts.
+func New(ctx context.Context, db database.Store, log slog.Logger, tick <-chan time.Time) *Controller {
+	//nolint:gocritic // The controller manages the rollouts of all templates.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Controller{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		db:     db,
+		log:    log,
+		tick:   tick,
+		stats:  nil,
+	}
+}
+
+// WithStatsChannel will cause Controller to push a Stats to ch after every
+// tick. This push is blocking, so if ch is not read, the controller will hang.
+// This should only be used in tests.
+func (c *Controller) WithStatsChannel(ch chan<- Stats) *Controller {
+	c.stats = ch
+	return c
+}
+
+// Start will cause the controller to evaluate in-progress rollouts on every
+// tick from its channel. It will stop when its context is Done, or when its
+// channel is closed.
+//
+// Start should only be called once.
+func (c *Controller) Start() {
+	go func() {
+		defer close(c.done)
+		defer c.cancel()
+
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case t, ok := <-c.tick:
+				if !ok {
+					return
+				}
+				stats := c.run(t)
+				if stats.Error != nil {
+					c.log.Warn(c.ctx, "error evaluating template version rollouts once", slog.Error(stats.Error))
+				}
+				if c.stats != nil {
+					select {
+					case <-c.ctx.Done():
+						return
+					case c.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the controller.
+func (c *Controller) Close() {
+	c.cancel()
+	<-c.done
+}
+
+func (c *Controller) run(t time.Time) Stats {
+	stats := Stats{
+		AdvancedRolloutIDs:   []uuid.UUID{},
+		CompletedRolloutIDs:  []uuid.UUID{},
+		RolledBackRolloutIDs: []uuid.UUID{},
+		AbortedRolloutIDs:    []uuid.UUID{},
+	}
+
+	rollouts, err := c.db.GetActiveTemplateVersionRollouts(c.ctx)
+	if err != nil {
+		stats.Error = xerrors.Errorf("get active template version rollouts: %w", err)
+		return stats
+	}
+
+	now := dbtime.Time(t)
+	for _, rollout := range rollouts {
+		outcome, err := c.evaluate(rollout, now)
+		if err != nil {
+			stats.Error = xerrors.Errorf("evaluate rollout %s: %w", rollout.ID, err)
+			return stats
+		}
+		switch outcome {
+		case outcomeAdvanced:
+			stats.AdvancedRolloutIDs = append(stats.AdvancedRolloutIDs, rollout.ID)
+		case outcomeCompleted:
+			stats.CompletedRolloutIDs = append(stats.CompletedRolloutIDs, rollout.ID)
+		case outcomeRolledBack:
+			stats.RolledBackRolloutIDs = append(stats.RolledBackRolloutIDs, rollout.ID)
+		case outcomeAborted:
+			stats.AbortedRolloutIDs = append(stats.AbortedRolloutIDs, rollout.ID)
+		}
+	}
+
+	return stats
+}
+
+type outcome int
+
+const (
+	outcomeSoaking outcome = iota
+	outcomeAdvanced
+	outcomeCompleted
+	outcomeRolledBack
+	outcomeAborted
+)
+
+func (c *Controller) evaluate(rollout database.TemplateVersionRollout, now time.Time) (outcome, error) {
+	log := c.log.With(slog.F("rollout_id", rollout.ID), slog.F("template_id", rollout.TemplateID), slog.F("template_version_id", rollout.TemplateVersionID))
+
+	template, err := c.db.GetTemplateByID(c.ctx, rollout.TemplateID)
+	if err != nil {
+		return outcomeSoaking, xerrors.Errorf("get template: %w", err)
+	}
+	if template.ActiveVersionID != rollout.PreviousVersionID {
+		log.Info(c.ctx, "aborting template version rollout, the active version changed")
+		return c.finish(rollout, database.TemplateVersionRolloutStatusAborted,
+			fmt.Sprintf("The active version of the template was changed to %s.", template.ActiveVersionID), now, outcomeAborted)
+	}
+
+	buildStats, err := c.db.GetTemplateVersionRolloutBuildStats(c.ctx, database.GetTemplateVersionRolloutBuildStatsParams{
+		TemplateVersionID: rollout.TemplateVersionID,
+		Since:             rollout.StageStartedAt,
+	})
+	if err != nil {
+		return outcomeSoaking, xerrors.Errorf("get build stats: %w", err)
+	}
+	if ExceedsFailureRate(rollout, buildStats) {
+		log.Warn(c.ctx, "rolling back template version rollout", slog.F("builds", buildStats.Builds), slog.F("failed_builds", buildStats.FailedBuilds))
+		return c.finish(rollout, database.TemplateVersionRolloutStatusRolledBack,
+			fmt.Sprintf("%d of %d builds failed in stage %d, exceeding the maximum failure rate of %g%%.",
+				buildStats.FailedBuilds, buildStats.Builds, rollout.CurrentStage+1, rollout.MaxFailureRate*100), now, outcomeRolledBack)
+	}
+
+	if now.Before(rollout.StageStartedAt.Add(time.Duration(rollout.SoakDuration))) {
+		return outcomeSoaking, nil
+	}
+
+	if int(rollout.CurrentStage)+1 < len(rollout.Stages) {
+		_, err := c.db.UpdateTemplateVersionRolloutStage(c.ctx, database.UpdateTemplateVersionRolloutStageParams{
+			ID:             rollout.ID,
+			CurrentStage:   rollout.CurrentStage + 1,
+			StageStartedAt: now,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// The rollout was aborted concurrently.
+			return outcomeSoaking, nil
+		}
+		if err != nil {
+			return outcomeSoaking, xerrors.Errorf("update stage: %w", err)
+		}
+		log.Info(c.ctx, "template version rollout continued with the next stage", slog.F("stage", rollout.CurrentStage+1))
+		return outcomeAdvanced, nil
+	}
+
+	var promoted bool
+	err = c.db.InTx(func(tx database.Store) error {
+		_, err := tx.UpdateTemplateVersionRolloutStatus(c.ctx, database.UpdateTemplateVersionRolloutStatusParams{
+			ID:            rollout.ID,
+			Status:        database.TemplateVersionRolloutStatusCompleted,
+			StatusMessage: "The version was promoted to the active version.",
+			UpdatedAt:     now,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("update status: %w", err)
+		}
+		err = tx.UpdateTemplateActiveVersionByID(c.ctx, database.UpdateTemplateActiveVersionByIDParams{
+			ID:              rollout.TemplateID,
+			ActiveVersionID: rollout.TemplateVersionID,
+			UpdatedAt:       now,
+		})
+		if err != nil {
+			return xerrors.Errorf("update active version: %w", err)
+		}
+		promoted = true
+		return nil
+	}, nil)
+	if err != nil {
+		return outcomeSoaking, err
+	}
+	if !promoted {
+		return outcomeSoaking, nil
+	}
+	log.Info(c.ctx, "template version rollout completed, promoted the version")
+	return outcomeCompleted, nil
+}
+
+// finish finishes a rollout with status. Rollouts that were already finished
+// concurrently are left alone.
+func (c *Controller) finish(rollout database.TemplateVersionRollout, status database.TemplateVersionRolloutStatus, message string, now time.Time, o outcome) (outcome, error) {
+	_, err := c.db.UpdateTemplateVersionRolloutStatus(c.ctx, database.UpdateTemplateVersionRolloutStatusParams{
+		ID:            rollout.ID,
+		Status:        status,
+		StatusMessage: message,
+		UpdatedAt:     now,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return outcomeSoaking, nil
+	}
+	if err != nil {
+		return outcomeSoaking, xerrors.Errorf("update status: %w", err)
+	}
+	return o, nil
+}
+
+// ExceedsFailureRate reports whether the builds of the current stage of a
+// rollout exceed its maximum failure rate. The failure rate is only considered
+// once the stage has at least the minimum number of builds.
+func ExceedsFailureRate(rollout database.TemplateVersionRollout, stats database.GetTemplateVersionRolloutBuildStatsRow) bool {
+	if stats.Builds == 0 || stats.Builds < int64(rollout.MinBuilds) {
+		return false
+	}
+	return float64(stats.FailedBuilds)/float64(stats.Builds) > rollout.MaxFailureRate
+}
@@ -0,0 +1,247 @@
+package templaterollout_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/templaterollout"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestController(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{})
+		now  = dbtime.Now()
+	)
+	// newTemplate returns a template with an active version and a new
+	// version to roll out.
+	newTemplate := func() (database.Template, database.TemplateVersion) {
+		active := dbfake.TemplateVersion(t, db).Seed(database.TemplateVersion{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		}).Do()
+		next := dbfake.TemplateVersion(t, db).Seed(database.TemplateVersion{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+			TemplateID:     uuid.NullUUID{UUID: active.Template.ID, Valid: true},
+		}).SkipCreateTemplate().Do()
+		return active.Template, next.TemplateVersion
+	}
+	newRollout := func(template database.Template, version database.TemplateVersion, stages []int32, stageStartedAt time.Time) database.TemplateVersionRollout {
+		rollout, err := db.InsertTemplateVersionRollout(dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort)), database.InsertTemplateVersionRolloutParams{
+			ID:                uuid.New(),
+			TemplateID:        template.ID,
+			TemplateVersionID: version.ID,
+			PreviousVersionID: template.ActiveVersionID,
+			Stages:            stages,
+			SoakDuration:      int64(time.Hour),
+			MaxFailureRate:    0.25,
+			MinBuilds:         2,
+			CreatedBy:         user.ID,
+			CreatedAt:         stageStartedAt,
+		})
+		require.NoError(t, err)
+		return rollout
+	}
+
+	soakingTemplate, soakingVersion := newTemplate()
+	soaking := newRollout(soakingTemplate, soakingVersion, []int32{10, 50}, now.Add(-10*time.Minute))
+
+	advancingTemplate, advancingVersion := newTemplate()
+	advancing := newRollout(advancingTemplate, advancingVersion, []int32{10, 50}, now.Add(-2*time.Hour))
+
+	completingTemplate, completingVersion := newTemplate()
+	completing := newRollout(completingTemplate, completingVersion, []int32{10}, now.Add(-2*time.Hour))
+
+	// Half of the builds of the version failed, which exceeds the maximum
+	// failure rate before the stage is over.
+	failingTemplate, failingVersion := newTemplate()
+	failing := newRollout(failingTemplate, failingVersion, []int32{10, 50}, now.Add(-10*time.Minute))
+	for _, failed := range []bool{false, true} {
+		build := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+			TemplateID:     failingTemplate.ID,
+		}).Seed(database.WorkspaceBuild{TemplateVersionID: failingVersion.ID})
+		if failed {
+			build = build.Failed()
+		}
+		build.Do()
+	}
+
+	// The active version was changed after the rollout was created.
+	changedTemplate, changedVersion := newTemplate()
+	changed := newRollout(changedTemplate, changedVersion, []int32{10}, now.Add(-2*time.Hour))
+	err := db.UpdateTemplateActiveVersionByID(dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort)), database.UpdateTemplateActiveVersionByIDParams{
+		ID:              changedTemplate.ID,
+		ActiveVersionID: changedVersion.ID,
+		UpdatedAt:       now,
+	})
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	authzDB := dbauthz.New(db, rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry()), log, coderdtest.AccessControlStorePointer())
+	tickCh := make(chan time.Time)
+	statsCh := make(chan templaterollout.Stats)
+	controller := templaterollout.New(ctx, authzDB, log, tickCh).WithStatsChannel(statsCh)
+	controller.Start()
+	t.Cleanup(controller.Close)
+
+	tickCh <- now
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{advancing.ID}, stats.AdvancedRolloutIDs)
+	require.Equal(t, []uuid.UUID{completing.ID}, stats.CompletedRolloutIDs)
+	require.Equal(t, []uuid.UUID{failing.ID}, stats.RolledBackRolloutIDs)
+	require.Equal(t, []uuid.UUID{changed.ID}, stats.AbortedRolloutIDs)
+
+	got, err := db.GetTemplateVersionRolloutByID(ctx, soaking.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.TemplateVersionRolloutStatusInProgress, got.Status)
+	require.EqualValues(t, 0, got.CurrentStage)
+
+	got, err = db.GetTemplateVersionRolloutByID(ctx, advancing.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.TemplateVersionRolloutStatusInProgress, got.Status)
+	require.EqualValues(t, 1, got.CurrentStage)
+	require.WithinDuration(t, now, got.StageStartedAt, time.Second)
+
+	got, err = db.GetTemplateVersionRolloutByID(ctx, completing.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.TemplateVersionRolloutStatusCompleted, got.Status)
+	require.True(t, got.CompletedAt.Valid)
+	template, err := db.GetTemplateByID(ctx, completingTemplate.ID)
+	require.NoError(t, err)
+	require.Equal(t, completingVersion.ID, template.ActiveVersionID)
+
+	got, err = db.GetTemplateVersionRolloutByID(ctx, failing.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.TemplateVersionRolloutStatusRolledBack, got.Status)
+	require.Contains(t, got.StatusMessage, "1 of 2 builds failed")
+	template, err = db.GetTemplateByID(ctx, failingTemplate.ID)
+	require.NoError(t, err)
+	require.Equal(t, failingTemplate.ActiveVersionID, template.ActiveVersionID)
+
+	got, err = db.GetTemplateVersionRolloutByID(ctx, changed.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.TemplateVersionRolloutStatusAborted, got.Status)
+
+	// Finished rollouts are not evaluated again.
+	tickCh <- now.Add(time.Minute)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.CompletedRolloutIDs)
+	require.Empty(t, stats.RolledBackRolloutIDs)
+	require.Empty(t, stats.AbortedRolloutIDs)
+}
+
+func TestVersionForWorkspace(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	ctx := dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitLong))
+
+	var (
+		org      = dbgen.Organization(t, db, database.Organization{})
+		member   = dbgen.User(t, db, database.User{})
+		outsider = dbgen.User(t, db, database.User{})
+		group    = dbgen.Group(t, db, database.Group{OrganizationID: org.ID})
+		active   = dbfake.TemplateVersion(t, db).Seed(database.TemplateVersion{
+			OrganizationID: org.ID,
+			CreatedBy:      member.ID,
+		}).Do()
+		template = active.Template
+		next     = dbfake.TemplateVersion(t, db).Seed(database.TemplateVersion{
+			OrganizationID: org.ID,
+			CreatedBy:      member.ID,
+			TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+		}).SkipCreateTemplate().Do().TemplateVersion
+	)
+	dbgen.GroupMember(t, db, database.GroupMemberTable{UserID: member.ID, GroupID: group.ID})
+	memberWorkspace := database.Workspace{ID: uuid.New(), OwnerID: member.ID, TemplateID: template.ID}
+	outsiderWorkspace := database.Workspace{ID: uuid.New(), OwnerID: outsider.ID, TemplateID: template.ID}
+
+	// Without a rollout, the active version is used.
+	versionID, err := templaterollout.VersionForWorkspace(ctx, db, template, memberWorkspace)
+	require.NoError(t, err)
+	require.Equal(t, template.ActiveVersionID, versionID)
+
+	// A rollout without stages only targets the members of its group.
+	_, err = db.InsertTemplateVersionRollout(ctx, database.InsertTemplateVersionRolloutParams{
+		ID:                uuid.New(),
+		TemplateID:        template.ID,
+		TemplateVersionID: next.ID,
+		PreviousVersionID: template.ActiveVersionID,
+		GroupID:           uuid.NullUUID{UUID: group.ID, Valid: true},
+		Stages:            []int32{},
+		SoakDuration:      int64(time.Hour),
+		MaxFailureRate:    0.1,
+		CreatedBy:         member.ID,
+		CreatedAt:         dbtime.Now(),
+	})
+	require.NoError(t, err)
+
+	versionID, err = templaterollout.VersionForWorkspace(ctx, db, template, memberWorkspace)
+	require.NoError(t, err)
+	require.Equal(t, next.ID, versionID)
+	versionID, err = templaterollout.VersionForWorkspace(ctx, db, template, outsiderWorkspace)
+	require.NoError(t, err)
+	require.Equal(t, template.ActiveVersionID, versionID)
+}
+
+func TestInCohort(t *testing.T) {
+	t.Parallel()
+
+	rollout := database.TemplateVersionRollout{
+		ID:     uuid.New(),
+		Stages: []int32{10, 50},
+	}
+	next := rollout
+	next.CurrentStage = 1
+
+	var first, second int
+	for range 1000 {
+		workspaceID := uuid.New()
+		inFirst := templaterollout.InCohort(rollout, workspaceID, false)
+		inSecond := templaterollout.InCohort(next, workspaceID, false)
+		// The cohort of a stage includes the cohorts of the previous stages.
+		if inFirst {
+			require.True(t, inSecond)
+			first++
+		}
+		if inSecond {
+			second++
+		}
+		// Owners in the group are only part of the cohort if the rollout
+		// has a group.
+		require.Equal(t, inFirst, templaterollout.InCohort(rollout, workspaceID, true))
+	}
+	require.InDelta(t, 100, first, 50)
+	require.InDelta(t, 500, second, 100)
+
+	rollout.GroupID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	require.True(t, templaterollout.InCohort(rollout, uuid.New(), true))
+}
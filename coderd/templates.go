@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 	"time"
@@ -20,9 +21,11 @@ import (
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/deprecation"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/schedule"
@@ -54,6 +57,105 @@ func (api *API) template(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplate(template))
 }
 
+// @Summary Get workspace creation schema by template ID
+// @ID get-workspace-creation-schema-by-template-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateCreationSchema
+// @Router /api/v2/templates/{template}/creation-schema [get]
+func (api *API) templateCreationSchema(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	templateVersion, err := api.Database.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching active template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	dbParameters, err := api.Database.GetTemplateVersionParameters(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	parameters, err := db2sdk.TemplateVersionParameters(dbParameters)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template version parameter.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	presets, err := api.Database.GetPresetsByTemplateVersionID(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version presets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	presetParams, err := api.Database.GetPresetParametersByTemplateVersionID(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version presets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// The schedule store reflects entitlements, so the editable flags may
+	// differ from the values stored on the template.
+	templateSchedule, err := (*api.TemplateScheduleStore.Load()).Get(ctx, api.Database, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error getting template schedule options.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	templateAccessControl := (*(api.Options.AccessControlStore.Load())).GetTemplateAccessControl(template)
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TemplateCreationSchema{
+		TemplateID:           template.ID,
+		TemplateVersionID:    templateVersion.ID,
+		RequireActiveVersion: templateAccessControl.RequireActiveVersion,
+		Parameters:           parameters,
+		Presets:              convertPresets(presets, presetParams),
+		NamePolicy: codersdk.WorkspaceNamePolicy{
+			Pattern:       codersdk.UsernameValidRegex.String(),
+			MaxLength:     codersdk.NameMaxLength,
+			ReservedNames: codersdk.ReservedNames(),
+		},
+		TTL: codersdk.WorkspaceTTLPolicy{
+			DefaultMillis:           templateSchedule.DefaultTTL.Milliseconds(),
+			MinMillis:               ttlMinimum.Milliseconds(),
+			MaxMillis:               ttlMaximum.Milliseconds(),
+			ActivityBumpMillis:      templateSchedule.ActivityBump.Milliseconds(),
+			TrialWorkspaceTTLMillis: time.Duration(template.TrialWorkspaceTTL).Milliseconds(),
+		},
+		Schedule: codersdk.WorkspaceSchedulePolicy{
+			AutostartEditable: templateSchedule.UserAutostartEnabled,
+			AutostopEditable:  templateSchedule.UserAutostopEnabled,
+			AutostartRequirement: codersdk.TemplateAutostartRequirement{
+				DaysOfWeek: codersdk.BitmapToWeekdays(templateSchedule.AutostartRequirement.DaysOfWeek),
+			},
+			AutostopRequirement: codersdk.TemplateAutostopRequirement{
+				DaysOfWeek: codersdk.BitmapToWeekdays(templateSchedule.AutostopRequirement.DaysOfWeek),
+				Weeks:      max(templateSchedule.AutostopRequirement.Weeks, 1),
+			},
+		},
+	})
+}
+
 // @Summary Delete template by ID
 // @ID delete-template-by-id
 // @Security CoderSessionToken
@@ -304,12 +406,21 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 		dormantTTL                     time.Duration
 		dormantAutoDeletionTTL         time.Duration
 		timeTilAutostopNotify          time.Duration
+		provisionerPlanTimeout         time.Duration
+		provisionerApplyTimeout        time.Duration
+		trialWorkspaceTTL              time.Duration
 	)
 	if createTemplate.DefaultTTLMillis != nil {
 		defaultTTL = time.Duration(*createTemplate.DefaultTTLMillis) * time.Millisecond
+	} else if maxTTL := api.DeploymentValues.TemplateDefaultTTLMax.Value(); maxTTL > 0 {
+		// Templates must stop workspaces within the maximum of the
+		// deployment, so default to the longest TTL it allows.
+		defaultTTL = maxTTL
 	}
 	if createTemplate.ActivityBumpMillis != nil {
 		activityBump = time.Duration(*createTemplate.ActivityBumpMillis) * time.Millisecond
+	} else if maxBump := api.DeploymentValues.TemplateActivityBumpMax.Value(); maxBump > 0 && activityBump > maxBump {
+		activityBump = maxBump
 	}
 	if createTemplate.TimeTilAutostopNotifyMillis != nil {
 		timeTilAutostopNotify = time.Duration(*createTemplate.TimeTilAutostopNotifyMillis) * time.Millisecond
@@ -333,6 +444,15 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	if createTemplate.TimeTilDormantAutoDeleteMillis != nil {
 		dormantAutoDeletionTTL = time.Duration(*createTemplate.TimeTilDormantAutoDeleteMillis) * time.Millisecond
 	}
+	if createTemplate.ProvisionerPlanTimeoutMillis != nil {
+		provisionerPlanTimeout = time.Duration(*createTemplate.ProvisionerPlanTimeoutMillis) * time.Millisecond
+	}
+	if createTemplate.ProvisionerApplyTimeoutMillis != nil {
+		provisionerApplyTimeout = time.Duration(*createTemplate.ProvisionerApplyTimeoutMillis) * time.Millisecond
+	}
+	if createTemplate.TrialWorkspaceTTLMillis != nil {
+		trialWorkspaceTTL = time.Duration(*createTemplate.TrialWorkspaceTTLMillis) * time.Millisecond
+	}
 
 	var (
 		validErrs                            []codersdk.ValidationError
@@ -403,6 +523,21 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	if dormantAutoDeletionTTL < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_dormant_autodeletion_ms", Detail: "Must be a positive integer."})
 	}
+	if !validProvisionerJobTimeout(provisionerPlanTimeout) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "provisioner_plan_timeout_ms", Detail: provisionerJobTimeoutDetail})
+	}
+	if !validProvisionerJobTimeout(provisionerApplyTimeout) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "provisioner_apply_timeout_ms", Detail: provisionerJobTimeoutDetail})
+	}
+	if !validTrialWorkspaceTTL(trialWorkspaceTTL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "trial_workspace_ttl_ms", Detail: trialWorkspaceTTLDetail})
+	}
+	if detail := templateDefaultTTLBoundsDetail(api.DeploymentValues, defaultTTL); detail != "" {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "default_ttl_ms", Detail: detail})
+	}
+	if detail := templateActivityBumpBoundsDetail(api.DeploymentValues, activityBump); detail != "" {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "activity_bump_ms", Detail: detail})
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -447,6 +582,9 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 			MaxPortSharingLevel:          maxPortShareLevel,
 			UseClassicParameterFlow:      useClassicParameterFlow,
 			CorsBehavior:                 corsBehavior,
+			ProvisionerPlanTimeout:       int64(provisionerPlanTimeout),
+			ProvisionerApplyTimeout:      int64(provisionerApplyTimeout),
+			TrialWorkspaceTTL:            int64(trialWorkspaceTTL),
 		})
 		if err != nil {
 			return xerrors.Errorf("insert template: %s", err)
@@ -695,6 +833,9 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if resolved.activityBumpMillis < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "activity_bump_ms", Detail: "Must be a positive integer."})
 	}
+	if resolved.activityBumpMaxPerDayMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "activity_bump_max_per_day_ms", Detail: "Must be a positive integer."})
+	}
 	if resolved.timeTilAutostopNotifyMillis < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_autostop_notify_ms", Detail: "Must be a positive integer."})
 	} else if resolved.timeTilAutostopNotifyMillis != 0 && time.Duration(resolved.timeTilAutostopNotifyMillis)*time.Millisecond < time.Minute {
@@ -726,6 +867,45 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if resolved.timeTilDormantAutoDeleteMillis < 0 || (resolved.timeTilDormantAutoDeleteMillis > 0 && resolved.timeTilDormantAutoDeleteMillis < minTTL) {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_dormant_autodelete_ms", Detail: "Value must be at least one minute."})
 	}
+	if !validProvisionerJobTimeout(time.Duration(resolved.provisionerPlanTimeoutMillis) * time.Millisecond) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "provisioner_plan_timeout_ms", Detail: provisionerJobTimeoutDetail})
+	}
+	if !validProvisionerJobTimeout(time.Duration(resolved.provisionerApplyTimeoutMillis) * time.Millisecond) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "provisioner_apply_timeout_ms", Detail: provisionerJobTimeoutDetail})
+	}
+	if !validTrialWorkspaceTTL(time.Duration(resolved.trialWorkspaceTTLMillis) * time.Millisecond) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "trial_workspace_ttl_ms", Detail: trialWorkspaceTTLDetail})
+	}
+	if resolved.buildLogRetentionMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "build_log_retention_ms", Detail: "Must be a positive integer."})
+	}
+	if !validBuildHookURL(resolved.preBuildHookURL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "pre_build_hook_url", Detail: buildHookURLDetail})
+	}
+	if !validBuildHookURL(resolved.postBuildHookURL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "post_build_hook_url", Detail: buildHookURLDetail})
+	}
+	if resolved.maxLifetimeMillis < 0 || (resolved.maxLifetimeMillis > 0 && time.Duration(resolved.maxLifetimeMillis)*time.Millisecond < 24*time.Hour) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "max_lifetime_ms", Detail: "Must be 0 (disabled) or at least one day."})
+	}
+	if resolved.idleReclaimTTLMillis < 0 || (resolved.idleReclaimTTLMillis > 0 && resolved.idleReclaimTTLMillis < minTTL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "idle_reclaim_ttl_ms", Detail: "Must be 0 (disabled) or at least one minute."})
+	}
+	if resolved.idleReclaimTTLMillis > 0 && resolved.idleReclaimResourceSelector == "" {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "idle_reclaim_resource_selector", Detail: "Required when idle reclaim is enabled."})
+	}
+	// Only changed values are checked against the bounds of the deployment,
+	// so templates that predate the bounds can still be updated otherwise.
+	if resolved.defaultTTLMillis != scheduleOpts.DefaultTTL.Milliseconds() {
+		if detail := templateDefaultTTLBoundsDetail(api.DeploymentValues, time.Duration(resolved.defaultTTLMillis)*time.Millisecond); detail != "" {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "default_ttl_ms", Detail: detail})
+		}
+	}
+	if resolved.activityBumpMillis != scheduleOpts.ActivityBump.Milliseconds() {
+		if detail := templateActivityBumpBoundsDetail(api.DeploymentValues, time.Duration(resolved.activityBumpMillis)*time.Millisecond); detail != "" {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "activity_bump_ms", Detail: detail})
+		}
+	}
 
 	// MaxPortShareLevel resolution depends on the (potentially licensed)
 	// PortSharer interface, so it stays out of the pure resolver.
@@ -778,15 +958,31 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			UseClassicParameterFlow:      resolved.useClassicTemplateFlow,
 			CorsBehavior:                 resolved.corsBehavior,
 			DisableModuleCache:           resolved.disableModuleCache,
+			DisableProviderCache:         resolved.disableProviderCache,
+			ProvisionerPlanTimeout:       int64(time.Duration(resolved.provisionerPlanTimeoutMillis) * time.Millisecond),
+			ProvisionerApplyTimeout:      int64(time.Duration(resolved.provisionerApplyTimeoutMillis) * time.Millisecond),
+			TrialWorkspaceTTL:            int64(time.Duration(resolved.trialWorkspaceTTLMillis) * time.Millisecond),
+			BuildLogRetention:            int64(time.Duration(resolved.buildLogRetentionMillis) * time.Millisecond),
+			PreBuildHookURL:              resolved.preBuildHookURL,
+			PostBuildHookURL:             resolved.postBuildHookURL,
+			AutoAssignRegion:             resolved.autoAssignRegion,
+			NotifyBuildFailures:          resolved.notifyBuildFailures,
+			RequeueReapedBuilds:          resolved.requeueReapedBuilds,
+			AgentRolloutChannel:          resolved.agentRolloutChannel,
+			AllowTargetedBuilds:          resolved.allowTargetedBuilds,
+			ReconfirmParameters:          resolved.reconfirmParameters,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
 		}
 
-		if template.RequireActiveVersion != resolved.requireActiveVersion || resolved.deprecationMessage != template.Deprecated {
+		if template.RequireActiveVersion != resolved.requireActiveVersion ||
+			resolved.deprecationMessage != template.Deprecated ||
+			resolved.deprecationCutoff != template.DeprecationCutoff {
 			err = (*api.AccessControlStore.Load()).SetTemplateAccessControl(ctx, tx, template.ID, dbauthz.TemplateAccessControl{
 				RequireActiveVersion: resolved.requireActiveVersion,
 				Deprecated:           resolved.deprecationMessage,
+				DeprecationCutoff:    resolved.deprecationCutoff,
 			})
 			if err != nil {
 				return xerrors.Errorf("set template access control: %w", err)
@@ -816,11 +1012,13 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			// Some of these values are enterprise-only, but the
 			// TemplateScheduleStore will handle avoiding setting them if
 			// unlicensed.
-			UserAutostartEnabled:  resolved.allowUserAutostart,
-			UserAutostopEnabled:   resolved.allowUserAutostop,
-			DefaultTTL:            defaultTTL,
-			ActivityBump:          activityBump,
-			TimeTilAutostopNotify: timeTilAutostopNotify,
+			UserAutostartEnabled:        resolved.allowUserAutostart,
+			UserAutostopEnabled:         resolved.allowUserAutostop,
+			DefaultTTL:                  defaultTTL,
+			ActivityBump:                activityBump,
+			ActivityBumpConnectionTypes: resolved.activityBumpConnectionTypes,
+			ActivityBumpMaxPerDay:       time.Duration(resolved.activityBumpMaxPerDayMillis) * time.Millisecond,
+			TimeTilAutostopNotify:       timeTilAutostopNotify,
 			AutostopRequirement: schedule.TemplateAutostopRequirement{
 				DaysOfWeek: resolved.autostopRequirementDaysOfWeekParsed,
 				Weeks:      resolved.autostopRequirementWeeks,
@@ -828,6 +1026,17 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			AutostartRequirement: schedule.TemplateAutostartRequirement{
 				DaysOfWeek: resolved.autostartRequirementDaysOfWeekParsed,
 			},
+			NightlyStop: schedule.TemplateNightlyStop{
+				Time: resolved.nightlyStopTime,
+			},
+			MaxLifetime: schedule.TemplateMaxLifetime{
+				Duration: time.Duration(resolved.maxLifetimeMillis) * time.Millisecond,
+				Action:   resolved.maxLifetimeAction,
+			},
+			IdleReclaim: schedule.TemplateIdleReclaim{
+				TTL:              time.Duration(resolved.idleReclaimTTLMillis) * time.Millisecond,
+				ResourceSelector: resolved.idleReclaimResourceSelector,
+			},
 			FailureTTL:                failureTTL,
 			TimeTilDormant:            inactivityTTL,
 			TimeTilDormantAutoDelete:  timeTilDormantAutoDelete,
@@ -1029,7 +1238,14 @@ func (api *API) convertTemplate(
 		Icon:                           template.Icon,
 		DefaultTTLMillis:               time.Duration(template.DefaultTTL).Milliseconds(),
 		ActivityBumpMillis:             time.Duration(template.ActivityBump).Milliseconds(),
+		ActivityBumpConnectionTypes:    slice.StringEnums[codersdk.ActivityBumpConnectionType](template.ActivityBumpConnectionTypes),
+		ActivityBumpMaxPerDayMillis:    time.Duration(template.ActivityBumpMaxPerDay).Milliseconds(),
 		TimeTilAutostopNotifyMillis:    time.Duration(template.TimeTilAutostopNotify).Milliseconds(),
+		NightlyStopTime:                template.NightlyStopTime,
+		MaxLifetimeMillis:              time.Duration(template.MaxLifetime).Milliseconds(),
+		MaxLifetimeAction:              codersdk.MaxLifetimeAction(template.MaxLifetimeAction),
+		IdleReclaimTTLMillis:           time.Duration(template.IdleReclaimTTL).Milliseconds(),
+		IdleReclaimResourceSelector:    template.IdleReclaimResourceSelector,
 		CreatedByID:                    template.CreatedBy,
 		CreatedByName:                  template.CreatedByUsername,
 		AllowUserAutostart:             template.AllowUserAutostart,
@@ -1038,6 +1254,18 @@ func (api *API) convertTemplate(
 		FailureTTLMillis:               time.Duration(template.FailureTTL).Milliseconds(),
 		TimeTilDormantMillis:           time.Duration(template.TimeTilDormant).Milliseconds(),
 		TimeTilDormantAutoDeleteMillis: time.Duration(template.TimeTilDormantAutoDelete).Milliseconds(),
+		ProvisionerPlanTimeoutMillis:   time.Duration(template.ProvisionerPlanTimeout).Milliseconds(),
+		ProvisionerApplyTimeoutMillis:  time.Duration(template.ProvisionerApplyTimeout).Milliseconds(),
+		TrialWorkspaceTTLMillis:        time.Duration(template.TrialWorkspaceTTL).Milliseconds(),
+		BuildLogRetentionMillis:        time.Duration(template.BuildLogRetention).Milliseconds(),
+		PreBuildHookURL:                template.PreBuildHookURL,
+		PostBuildHookURL:               template.PostBuildHookURL,
+		AutoAssignRegion:               template.AutoAssignRegion,
+		NotifyBuildFailures:            template.NotifyBuildFailures,
+		RequeueReapedBuilds:            template.RequeueReapedBuilds,
+		AgentRolloutChannel:            codersdk.AgentRolloutChannel(template.AgentRolloutChannel),
+		AllowTargetedBuilds:            template.AllowTargetedBuilds,
+		ReconfirmParameters:            template.ReconfirmParameters,
 		AutostopRequirement: codersdk.TemplateAutostopRequirement{
 			DaysOfWeek: codersdk.BitmapToWeekdays(uint8(template.AutostopRequirementDaysOfWeek)), // #nosec G115 - Safe conversion as AutostopRequirementDaysOfWeek is a 7-bit bitmap
 			Weeks:      autostopRequirementWeeks,
@@ -1049,11 +1277,13 @@ func (api *API) convertTemplate(
 		RequireActiveVersion:    templateAccessControl.RequireActiveVersion,
 		Deprecated:              templateAccessControl.IsDeprecated(),
 		DeprecationMessage:      templateAccessControl.Deprecated,
+		DeprecationCutoff:       deprecation.CutoffPtr(templateAccessControl.DeprecationCutoff),
 		Deleted:                 template.Deleted,
 		MaxPortShareLevel:       maxPortShareLevel,
 		UseClassicParameterFlow: template.UseClassicParameterFlow,
 		CORSBehavior:            codersdk.CORSBehavior(template.CorsBehavior),
 		DisableModuleCache:      template.DisableModuleCache,
+		DisableProviderCache:    template.DisableProviderCache,
 	}
 }
 
@@ -1067,3 +1297,59 @@ func findTemplateAdmins(ctx context.Context, store database.Store) ([]database.G
 	}
 	return templateAdmins, nil
 }
+
+const provisionerJobTimeoutDetail = "Must be 0 (disabled) or between one minute and 24 hours."
+
+// validProvisionerJobTimeout reports whether d is an acceptable plan or apply
+// timeout for a template.
+func validProvisionerJobTimeout(d time.Duration) bool {
+	return d == 0 || (d >= provisionerdserver.MinJobTimeout && d <= provisionerdserver.MaxJobTimeout)
+}
+
+const trialWorkspaceTTLDetail = "Must be 0 (disabled) or at least one hour."
+
+// validTrialWorkspaceTTL reports whether d is an acceptable hard lifetime for
+// workspaces created from a template.
+func validTrialWorkspaceTTL(d time.Duration) bool {
+	return d == 0 || d >= time.Hour
+}
+
+// templateDefaultTTLBoundsDetail returns why d is outside the bounds the
+// deployment sets on the default TTL of templates, or an empty string if it is
+// within them.
+func templateDefaultTTLBoundsDetail(vals *codersdk.DeploymentValues, d time.Duration) string {
+	minTTL, maxTTL := vals.TemplateDefaultTTLMin.Value(), vals.TemplateDefaultTTLMax.Value()
+	switch {
+	case maxTTL > 0 && d == 0:
+		return fmt.Sprintf("Must be set, the deployment requires workspaces to stop within %s.", maxTTL)
+	case maxTTL > 0 && d > maxTTL:
+		return fmt.Sprintf("Must be at most %s, the maximum allowed by the deployment.", maxTTL)
+	case minTTL > 0 && d > 0 && d < minTTL:
+		return fmt.Sprintf("Must be at least %s, the minimum allowed by the deployment.", minTTL)
+	}
+	return ""
+}
+
+// templateActivityBumpBoundsDetail returns why d is above the maximum activity
+// bump of the deployment, or an empty string if it is not.
+func templateActivityBumpBoundsDetail(vals *codersdk.DeploymentValues, d time.Duration) string {
+	if maxBump := vals.TemplateActivityBumpMax.Value(); maxBump > 0 && d > maxBump {
+		return fmt.Sprintf("Must be at most %s, the maximum allowed by the deployment.", maxBump)
+	}
+	return ""
+}
+
+const buildHookURLDetail = "Must be empty (disabled) or an absolute http or https URL."
+
+// validBuildHookURL reports whether s is an acceptable pre-build or
+// post-build hook of a template.
+func validBuildHookURL(s string) bool {
+	if s == "" {
+		return true
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -1,6 +1,8 @@
 package coderd
 
 import (
+	"database/sql"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,21 +24,42 @@ type templateMetaUpdate struct {
 	icon                                 string
 	defaultTTLMillis                     int64
 	activityBumpMillis                   int64
+	activityBumpConnectionTypes          []string
+	activityBumpMaxPerDayMillis          int64
 	timeTilAutostopNotifyMillis          int64
 	failureTTLMillis                     int64
 	timeTilDormantMillis                 int64
 	timeTilDormantAutoDeleteMillis       int64
+	provisionerPlanTimeoutMillis         int64
+	provisionerApplyTimeoutMillis        int64
+	trialWorkspaceTTLMillis              int64
+	buildLogRetentionMillis              int64
+	preBuildHookURL                      string
+	postBuildHookURL                     string
+	autoAssignRegion                     bool
+	notifyBuildFailures                  bool
+	requeueReapedBuilds                  bool
+	allowTargetedBuilds                  bool
+	reconfirmParameters                  []string
+	agentRolloutChannel                  database.AgentRolloutChannel
 	allowUserAutostart                   bool
 	allowUserAutostop                    bool
 	allowUserCancelWorkspaceJobs         bool
 	requireActiveVersion                 bool
 	deprecationMessage                   string
+	deprecationCutoff                    sql.NullTime
 	useClassicTemplateFlow               bool
 	disableModuleCache                   bool
+	disableProviderCache                 bool
 	corsBehavior                         database.CorsBehavior
 	autostopRequirementDaysOfWeekParsed  uint8
 	autostartRequirementDaysOfWeekParsed uint8
 	autostopRequirementWeeks             int64
+	nightlyStopTime                      string
+	maxLifetimeMillis                    int64
+	maxLifetimeAction                    database.MaxLifetimeAction
+	idleReclaimTTLMillis                 int64
+	idleReclaimResourceSelector          string
 	groupACL                             database.TemplateACL
 
 	// updateWorkspaceLastUsedAtIntent and updateWorkspaceDormantAtIntent are one-shot
@@ -74,10 +97,23 @@ func resolveTemplateMetaUpdate(
 		icon:                           ptr.NilToDefault(req.Icon, template.Icon),
 		defaultTTLMillis:               ptr.NilToDefault(req.DefaultTTLMillis, time.Duration(template.DefaultTTL).Milliseconds()),
 		activityBumpMillis:             ptr.NilToDefault(req.ActivityBumpMillis, time.Duration(template.ActivityBump).Milliseconds()),
+		activityBumpConnectionTypes:    scheduleOpts.ActivityBumpConnectionTypes,
+		activityBumpMaxPerDayMillis:    ptr.NilToDefault(req.ActivityBumpMaxPerDayMillis, scheduleOpts.ActivityBumpMaxPerDay.Milliseconds()),
 		timeTilAutostopNotifyMillis:    ptr.NilToDefault(req.TimeTilAutostopNotifyMillis, time.Duration(template.TimeTilAutostopNotify).Milliseconds()),
 		failureTTLMillis:               ptr.NilToDefault(req.FailureTTLMillis, time.Duration(template.FailureTTL).Milliseconds()),
 		timeTilDormantMillis:           ptr.NilToDefault(req.TimeTilDormantMillis, time.Duration(template.TimeTilDormant).Milliseconds()),
 		timeTilDormantAutoDeleteMillis: ptr.NilToDefault(req.TimeTilDormantAutoDeleteMillis, time.Duration(template.TimeTilDormantAutoDelete).Milliseconds()),
+		provisionerPlanTimeoutMillis:   ptr.NilToDefault(req.ProvisionerPlanTimeoutMillis, time.Duration(template.ProvisionerPlanTimeout).Milliseconds()),
+		provisionerApplyTimeoutMillis:  ptr.NilToDefault(req.ProvisionerApplyTimeoutMillis, time.Duration(template.ProvisionerApplyTimeout).Milliseconds()),
+		trialWorkspaceTTLMillis:        ptr.NilToDefault(req.TrialWorkspaceTTLMillis, time.Duration(template.TrialWorkspaceTTL).Milliseconds()),
+		buildLogRetentionMillis:        ptr.NilToDefault(req.BuildLogRetentionMillis, time.Duration(template.BuildLogRetention).Milliseconds()),
+		preBuildHookURL:                ptr.NilToDefault(req.PreBuildHookURL, template.PreBuildHookURL),
+		postBuildHookURL:               ptr.NilToDefault(req.PostBuildHookURL, template.PostBuildHookURL),
+		autoAssignRegion:               ptr.NilToDefault(req.AutoAssignRegion, template.AutoAssignRegion),
+		notifyBuildFailures:            ptr.NilToDefault(req.NotifyBuildFailures, template.NotifyBuildFailures),
+		requeueReapedBuilds:            ptr.NilToDefault(req.RequeueReapedBuilds, template.RequeueReapedBuilds),
+		allowTargetedBuilds:            ptr.NilToDefault(req.AllowTargetedBuilds, template.AllowTargetedBuilds),
+		reconfirmParameters:            template.ReconfirmParameters,
 		allowUserAutostart:             ptr.NilToDefault(req.AllowUserAutostart, template.AllowUserAutostart),
 		allowUserAutostop:              ptr.NilToDefault(req.AllowUserAutostop, template.AllowUserAutostop),
 		allowUserCancelWorkspaceJobs:   ptr.NilToDefault(req.AllowUserCancelWorkspaceJobs, template.AllowUserCancelWorkspaceJobs),
@@ -85,13 +121,21 @@ func resolveTemplateMetaUpdate(
 		deprecationMessage:             ptr.NilToDefault(req.DeprecationMessage, template.Deprecated),
 		useClassicTemplateFlow:         ptr.NilToDefault(req.UseClassicParameterFlow, template.UseClassicParameterFlow),
 		disableModuleCache:             ptr.NilToDefault(req.DisableModuleCache, template.DisableModuleCache),
+		disableProviderCache:           ptr.NilToDefault(req.DisableProviderCache, template.DisableProviderCache),
 		groupACL:                       template.GroupACL,
 
 		// Default to the original values
 		corsBehavior:                         template.CorsBehavior,
+		deprecationCutoff:                    template.DeprecationCutoff,
+		agentRolloutChannel:                  template.AgentRolloutChannel,
 		autostopRequirementDaysOfWeekParsed:  scheduleOpts.AutostopRequirement.DaysOfWeek,
 		autostopRequirementWeeks:             scheduleOpts.AutostopRequirement.Weeks,
 		autostartRequirementDaysOfWeekParsed: scheduleOpts.AutostartRequirement.DaysOfWeek,
+		nightlyStopTime:                      scheduleOpts.NightlyStop.Time,
+		maxLifetimeMillis:                    ptr.NilToDefault(req.MaxLifetimeMillis, scheduleOpts.MaxLifetime.Duration.Milliseconds()),
+		maxLifetimeAction:                    scheduleOpts.MaxLifetime.Action,
+		idleReclaimTTLMillis:                 ptr.NilToDefault(req.IdleReclaimTTLMillis, scheduleOpts.IdleReclaim.TTL.Milliseconds()),
+		idleReclaimResourceSelector:          scheduleOpts.IdleReclaim.ResourceSelector,
 		updateWorkspaceLastUsedAtIntent:      false,
 		updateWorkspaceDormantAtIntent:       false,
 	}
@@ -134,6 +178,18 @@ func resolveTemplateMetaUpdate(
 		}
 	}
 
+	if req.NightlyStopTime != nil {
+		nightlyStopTime, err := schedule.NormalizeTemplateNightlyStopTime(*req.NightlyStopTime)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "nightly_stop_time",
+				Detail: err.Error(),
+			})
+		} else {
+			out.nightlyStopTime = nightlyStopTime
+		}
+	}
+
 	// Resolve CORS behavior. An empty string is treated as "do not
 	// change" because the existing UI-driven flow used to send empty
 	// strings for unset values. A non-empty invalid value is a
@@ -151,6 +207,89 @@ func resolveTemplateMetaUpdate(
 		}
 	}
 
+	if req.MaxLifetimeAction != nil {
+		val := database.MaxLifetimeAction(*req.MaxLifetimeAction)
+		if !val.Valid() {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field: "max_lifetime_action",
+				Detail: "Invalid max lifetime action \"" + string(*req.MaxLifetimeAction) +
+					"\". Must be one of [" + strings.Join(slice.ToStrings(database.AllMaxLifetimeActionValues()), ", ") + "]",
+			})
+		} else {
+			out.maxLifetimeAction = val
+		}
+	}
+
+	if req.IdleReclaimResourceSelector != nil {
+		selector := strings.TrimSpace(*req.IdleReclaimResourceSelector)
+		if key, _, ok := strings.Cut(selector, "="); selector != "" && (!ok || strings.TrimSpace(key) == "") {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "idle_reclaim_resource_selector",
+				Detail: "Invalid resource selector \"" + *req.IdleReclaimResourceSelector + "\". Must be in the form key=value, e.g. gpu=true.",
+			})
+		} else {
+			out.idleReclaimResourceSelector = selector
+		}
+	}
+
+	if req.AgentRolloutChannel != nil {
+		val := database.AgentRolloutChannel(*req.AgentRolloutChannel)
+		if !val.Valid() {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field: "agent_rollout_channel",
+				Detail: "Invalid agent rollout channel \"" + string(*req.AgentRolloutChannel) +
+					"\". Must be one of [" + strings.Join(slice.ToStrings(database.AllAgentRolloutChannelValues()), ", ") + "]",
+			})
+		} else {
+			out.agentRolloutChannel = val
+		}
+	}
+
+	if req.ActivityBumpConnectionTypes != nil {
+		types := make([]string, 0, len(*req.ActivityBumpConnectionTypes))
+		for _, connectionType := range *req.ActivityBumpConnectionTypes {
+			if !slices.Contains(codersdk.AllActivityBumpConnectionTypes, connectionType) {
+				validErrs = append(validErrs, codersdk.ValidationError{
+					Field: "activity_bump_connection_types",
+					Detail: "Invalid connection type \"" + string(connectionType) +
+						"\". Must be one of [" + strings.Join(slice.ToStrings(codersdk.AllActivityBumpConnectionTypes), ", ") + "]",
+				})
+				continue
+			}
+			if !slices.Contains(types, string(connectionType)) {
+				types = append(types, string(connectionType))
+			}
+		}
+		out.activityBumpConnectionTypes = types
+	}
+
+	if req.ReconfirmParameters != nil {
+		names := make([]string, 0, len(*req.ReconfirmParameters))
+		for _, name := range *req.ReconfirmParameters {
+			if name == "" {
+				validErrs = append(validErrs, codersdk.ValidationError{
+					Field:  "reconfirm_parameters",
+					Detail: "Parameter names must not be empty.",
+				})
+				continue
+			}
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		out.reconfirmParameters = names
+	}
+
+	// The deprecation cutoff is only meaningful alongside a deprecation
+	// message, so it is replaced whenever the message is set. Clearing the
+	// message also clears the cutoff.
+	if req.DeprecationMessage != nil {
+		out.deprecationCutoff = sql.NullTime{}
+		if req.DeprecationCutoff != nil && *req.DeprecationMessage != "" {
+			out.deprecationCutoff = sql.NullTime{Time: *req.DeprecationCutoff, Valid: true}
+		}
+	}
+
 	if req.DisableEveryoneGroupAccess != nil && *req.DisableEveryoneGroupAccess {
 		// Remove the "everyone" group from the template. If this is set to false, the
 		// user needs to explicitly add the "everyone" group back to the ACL via the
@@ -43,6 +43,7 @@ func baselineTemplate() database.Template {
 		UseClassicParameterFlow:       true,
 		CorsBehavior:                  database.CorsBehaviorPassthru,
 		DisableModuleCache:            true,
+		AgentRolloutChannel:           database.AgentRolloutChannelBeta,
 		GroupACL: database.TemplateACL{
 			orgID.String(): {"read"},
 		},
@@ -60,6 +61,12 @@ func baselineScheduleOpts() schedule.TemplateScheduleOptions {
 		AutostartRequirement: schedule.TemplateAutostartRequirement{
 			DaysOfWeek: 0b1000000,
 		},
+		NightlyStop: schedule.TemplateNightlyStop{
+			Time: "00:00",
+		},
+		MaxLifetime: schedule.TemplateMaxLifetime{
+			Action: database.MaxLifetimeActionDelete,
+		},
 	}
 }
 
@@ -86,9 +93,12 @@ func baselineResolved() templateMetaUpdate {
 		useClassicTemplateFlow:               tpl.UseClassicParameterFlow,
 		disableModuleCache:                   tpl.DisableModuleCache,
 		corsBehavior:                         tpl.CorsBehavior,
+		agentRolloutChannel:                  tpl.AgentRolloutChannel,
 		autostopRequirementDaysOfWeekParsed:  0b0000001,
 		autostartRequirementDaysOfWeekParsed: 0b1000000,
 		autostopRequirementWeeks:             tpl.AutostopRequirementWeeks,
+		nightlyStopTime:                      "00:00",
+		maxLifetimeAction:                    database.MaxLifetimeActionDelete,
 		groupACL:                             tpl.GroupACL,
 	}
 }
@@ -246,6 +256,76 @@ func TestResolveTemplateMetaUpdate(t *testing.T) {
 				r.timeTilDormantAutoDeleteMillis = 14_400_000
 			}},
 		},
+		{
+			name: "TrialWorkspaceTTLMillis",
+			req:  codersdk.UpdateTemplateMeta{TrialWorkspaceTTLMillis: ptr.Ref(int64(259_200_000))},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.trialWorkspaceTTLMillis = 259_200_000
+			}},
+		},
+		{
+			name: "BuildLogRetentionMillis",
+			req:  codersdk.UpdateTemplateMeta{BuildLogRetentionMillis: ptr.Ref(int64(604_800_000))},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.buildLogRetentionMillis = 604_800_000
+			}},
+		},
+		{
+			name: "BuildHookURLs",
+			req: codersdk.UpdateTemplateMeta{
+				PreBuildHookURL:  ptr.Ref("https://ipam.example.com/allocate"),
+				PostBuildHookURL: ptr.Ref("https://ipam.example.com/record"),
+			},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.preBuildHookURL = "https://ipam.example.com/allocate"
+				r.postBuildHookURL = "https://ipam.example.com/record"
+			}},
+		},
+		{
+			name: "AutoAssignRegion",
+			req:  codersdk.UpdateTemplateMeta{AutoAssignRegion: ptr.Ref(true)},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.autoAssignRegion = true
+			}},
+		},
+		{
+			name: "NotifyBuildFailures",
+			req:  codersdk.UpdateTemplateMeta{NotifyBuildFailures: ptr.Ref(true)},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.notifyBuildFailures = true
+			}},
+		},
+		{
+			name: "RequeueReapedBuilds",
+			req:  codersdk.UpdateTemplateMeta{RequeueReapedBuilds: ptr.Ref(true)},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.requeueReapedBuilds = true
+			}},
+		},
+		{
+			name: "AllowTargetedBuilds",
+			req:  codersdk.UpdateTemplateMeta{AllowTargetedBuilds: ptr.Ref(true)},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.allowTargetedBuilds = true
+			}},
+		},
+		{
+			name: "ReconfirmParameters",
+			req:  codersdk.UpdateTemplateMeta{ReconfirmParameters: ptr.Ref([]string{"region", "region", "image"})},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.reconfirmParameters = []string{"region", "image"}
+			}},
+		},
+		{
+			name: "ReconfirmParametersEmptyName",
+			req:  codersdk.UpdateTemplateMeta{ReconfirmParameters: ptr.Ref([]string{""})},
+			expected: expected{
+				override: func(r *templateMetaUpdate) {
+					r.reconfirmParameters = []string{}
+				},
+				validErrFields: []string{"reconfirm_parameters"},
+			},
+		},
 		{
 			name: "RequireActiveVersion",
 			req:  codersdk.UpdateTemplateMeta{RequireActiveVersion: ptr.Ref(false)},
@@ -281,6 +361,13 @@ func TestResolveTemplateMetaUpdate(t *testing.T) {
 				r.disableModuleCache = false
 			}},
 		},
+		{
+			name: "DisableProviderCache",
+			req:  codersdk.UpdateTemplateMeta{DisableProviderCache: ptr.Ref(true)},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.disableProviderCache = true
+			}},
+		},
 
 		// CORS behavior.
 		{
@@ -314,6 +401,73 @@ func TestResolveTemplateMetaUpdate(t *testing.T) {
 			},
 		},
 
+		// Max lifetime.
+		{
+			name: "MaxLifetime",
+			req: codersdk.UpdateTemplateMeta{
+				MaxLifetimeMillis: ptr.Ref(int64(30 * 24 * 60 * 60 * 1000)),
+				MaxLifetimeAction: ptr.Ref(codersdk.MaxLifetimeActionStop),
+			},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.maxLifetimeMillis = 30 * 24 * 60 * 60 * 1000
+				r.maxLifetimeAction = database.MaxLifetimeActionStop
+			}},
+		},
+		{
+			name: "MaxLifetimeActionInvalid",
+			req: codersdk.UpdateTemplateMeta{
+				MaxLifetimeAction: ptr.Ref(codersdk.MaxLifetimeAction("archive")),
+			},
+			expected: expected{
+				override:       func(*templateMetaUpdate) {},
+				validErrFields: []string{"max_lifetime_action"},
+			},
+		},
+
+		// Idle reclaim.
+		{
+			name: "IdleReclaim",
+			req: codersdk.UpdateTemplateMeta{
+				IdleReclaimTTLMillis:        ptr.Ref(int64(15 * 60 * 1000)),
+				IdleReclaimResourceSelector: ptr.Ref(" gpu=true "),
+			},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.idleReclaimTTLMillis = 15 * 60 * 1000
+				r.idleReclaimResourceSelector = "gpu=true"
+			}},
+		},
+		{
+			name: "IdleReclaimResourceSelectorInvalid",
+			req: codersdk.UpdateTemplateMeta{
+				IdleReclaimResourceSelector: ptr.Ref("gpu"),
+			},
+			expected: expected{
+				override:       func(*templateMetaUpdate) {},
+				validErrFields: []string{"idle_reclaim_resource_selector"},
+			},
+		},
+
+		// Agent rollout channel.
+		{
+			name: "AgentRolloutChannelChange",
+			req: codersdk.UpdateTemplateMeta{
+				AgentRolloutChannel: ptr.Ref(codersdk.AgentRolloutChannelStable),
+			},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.agentRolloutChannel = database.AgentRolloutChannelStable
+			}},
+		},
+		{
+			name: "AgentRolloutChannelInvalid",
+			req: codersdk.UpdateTemplateMeta{
+				AgentRolloutChannel: ptr.Ref(codersdk.AgentRolloutChannel("nightly")),
+			},
+			expected: expected{
+				override:       func(*templateMetaUpdate) {},
+				validErrFields: []string{"agent_rollout_channel"},
+			},
+		},
+
 		// Autostop / autostart requirement bitmaps.
 		{
 			name: "AutostopRequirementChange",
@@ -382,6 +536,28 @@ func TestResolveTemplateMetaUpdate(t *testing.T) {
 				validErrFields: []string{"autostart_requirement.days_of_week"},
 			},
 		},
+		{
+			name: "NightlyStopTimeChange",
+			req:  codersdk.UpdateTemplateMeta{NightlyStopTime: ptr.Ref("9:30")},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.nightlyStopTime = "09:30"
+			}},
+		},
+		{
+			name: "NightlyStopTimeDisable",
+			req:  codersdk.UpdateTemplateMeta{NightlyStopTime: ptr.Ref("")},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.nightlyStopTime = ""
+			}},
+		},
+		{
+			name: "NightlyStopTimeInvalid",
+			req:  codersdk.UpdateTemplateMeta{NightlyStopTime: ptr.Ref("25:00")},
+			expected: expected{
+				override:       func(*templateMetaUpdate) {},
+				validErrFields: []string{"nightly_stop_time"},
+			},
+		},
 
 		// One-shot intent flags. nil and false should both result in
 		// the corresponding *Intent field being false; only true triggers it.
@@ -31,7 +31,9 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
 )
 
 // TestTemplatesListSingleAuthorizePrepare guards against reintroducing the
@@ -88,6 +90,59 @@ func TestTemplate(t *testing.T) {
 	})
 }
 
+func TestTemplateCreationSchema(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionGraph: []*proto.Response{{
+			Type: &proto.Response_Graph{
+				Graph: &proto.GraphComplete{
+					Parameters: []*proto.RichParameter{
+						{
+							Name:         "region",
+							Type:         "string",
+							DefaultValue: "us",
+							FormType:     proto.ParameterFormType_INPUT,
+						},
+					},
+					Presets: []*proto.Preset{
+						{
+							Name: "europe",
+							Parameters: []*proto.PresetParameter{
+								{Name: "region", Value: "eu"},
+							},
+						},
+					},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+		ctr.DefaultTTLMillis = ptr.Ref((8 * time.Hour).Milliseconds())
+	})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	schema, err := client.TemplateCreationSchema(ctx, template.ID)
+	require.NoError(t, err)
+	require.Equal(t, template.ID, schema.TemplateID)
+	require.Equal(t, version.ID, schema.TemplateVersionID)
+	require.Len(t, schema.Parameters, 1)
+	require.Equal(t, "region", schema.Parameters[0].Name)
+	require.Len(t, schema.Presets, 1)
+	require.Equal(t, "europe", schema.Presets[0].Name)
+	require.Equal(t, codersdk.NameMaxLength, schema.NamePolicy.MaxLength)
+	require.Regexp(t, schema.NamePolicy.Pattern, "my-workspace")
+	require.Equal(t, (8 * time.Hour).Milliseconds(), schema.TTL.DefaultMillis)
+	require.True(t, schema.Schedule.AutostartEditable)
+	require.True(t, schema.Schedule.AutostopEditable)
+}
+
 func TestPostTemplateByOrganization(t *testing.T) {
 	t.Parallel()
 	t.Run("Create", func(t *testing.T) {
@@ -239,6 +294,45 @@ func TestPostTemplateByOrganization(t *testing.T) {
 		assert.Equal(t, "Must be a positive integer.", apiErr.Validations[0].Detail)
 	})
 
+	t.Run("ProvisionerTimeouts", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		got, err := client.CreateTemplate(ctx, user.OrganizationID, codersdk.CreateTemplateRequest{
+			Name:                          "testing",
+			VersionID:                     version.ID,
+			ProvisionerPlanTimeoutMillis:  ptr.Ref((5 * time.Minute).Milliseconds()),
+			ProvisionerApplyTimeoutMillis: ptr.Ref((2 * time.Hour).Milliseconds()),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, (5 * time.Minute).Milliseconds(), got.ProvisionerPlanTimeoutMillis)
+		assert.Equal(t, (2 * time.Hour).Milliseconds(), got.ProvisionerApplyTimeoutMillis)
+	})
+
+	t.Run("ProvisionerTimeoutsOutOfRange", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateTemplate(ctx, user.OrganizationID, codersdk.CreateTemplateRequest{
+			Name:                          "testing",
+			VersionID:                     version.ID,
+			ProvisionerPlanTimeoutMillis:  ptr.Ref(int64(30_000)),
+			ProvisionerApplyTimeoutMillis: ptr.Ref((48 * time.Hour).Milliseconds()),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+		assert.Equal(t, "provisioner_plan_timeout_ms", apiErr.Validations[0].Field)
+		assert.Equal(t, "provisioner_apply_timeout_ms", apiErr.Validations[1].Field)
+	})
+
 	t.Run("NoDefaultTTL", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
@@ -255,6 +349,46 @@ func TestPostTemplateByOrganization(t *testing.T) {
 		require.Zero(t, got.DefaultTTLMillis)
 	})
 
+	t.Run("DefaultTTLBounds", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.TemplateDefaultTTLMin = serpent.Duration(time.Hour)
+		dv.TemplateDefaultTTLMax = serpent.Duration(12 * time.Hour)
+		dv.TemplateActivityBumpMax = serpent.Duration(30 * time.Minute)
+		client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		for _, tc := range []struct {
+			req    codersdk.CreateTemplateRequest
+			detail string
+		}{
+			{codersdk.CreateTemplateRequest{DefaultTTLMillis: ptr.Ref(int64(0))}, "default_ttl_ms: Must be set"},
+			{codersdk.CreateTemplateRequest{DefaultTTLMillis: ptr.Ref((30 * 24 * time.Hour).Milliseconds())}, "default_ttl_ms: Must be at most 12h0m0s"},
+			{codersdk.CreateTemplateRequest{DefaultTTLMillis: ptr.Ref(time.Minute.Milliseconds())}, "default_ttl_ms: Must be at least 1h0m0s"},
+			{codersdk.CreateTemplateRequest{ActivityBumpMillis: ptr.Ref(time.Hour.Milliseconds())}, "activity_bump_ms: Must be at most 30m0s"},
+		} {
+			tc.req.Name = "testing"
+			tc.req.VersionID = version.ID
+			_, err := client.CreateTemplate(ctx, user.OrganizationID, tc.req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+			require.Contains(t, err.Error(), tc.detail)
+		}
+
+		// Templates created without a default TTL or activity bump get the
+		// maximum of the deployment.
+		got, err := client.CreateTemplate(ctx, user.OrganizationID, codersdk.CreateTemplateRequest{
+			Name:      "testing",
+			VersionID: version.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, (12 * time.Hour).Milliseconds(), got.DefaultTTLMillis)
+		require.Equal(t, (30 * time.Minute).Milliseconds(), got.ActivityBumpMillis)
+	})
+
 	t.Run("DisableEveryone", func(t *testing.T) {
 		t.Parallel()
 		auditor := audit.NewMock()
@@ -1224,6 +1358,42 @@ func TestPatchTemplateMeta(t *testing.T) {
 		assert.False(t, updated.Deprecated)
 	})
 
+	t.Run("DefaultTTLBounds", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.TemplateDefaultTTLMax = serpent.Duration(12 * time.Hour)
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{DeploymentValues: dv})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DefaultTTLMillis: ptr.Ref((30 * 24 * time.Hour).Milliseconds()),
+		})
+		require.ErrorContains(t, err, "default_ttl_ms: Must be at most 12h0m0s")
+
+		// Templates that predate the bounds can still be updated, as long as
+		// their default TTL is left alone.
+		//nolint:gocritic // Test setup.
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		store := schedule.NewAGPLTemplateScheduleStore()
+		opts, err := store.Get(sysCtx, db, template.ID)
+		require.NoError(t, err)
+		opts.DefaultTTL = 30 * 24 * time.Hour
+		tpl, err := db.GetTemplateByID(sysCtx, template.ID)
+		require.NoError(t, err)
+		_, err = store.Set(sysCtx, db, tpl, opts)
+		require.NoError(t, err)
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: ptr.Ref("Predates the bounds."),
+		})
+		require.NoError(t, err)
+		require.Equal(t, (30 * 24 * time.Hour).Milliseconds(), updated.DefaultTTLMillis)
+	})
+
 	t.Run("CleanupTTLs", func(t *testing.T) {
 		t.Parallel()
 
@@ -1536,6 +1706,102 @@ func TestPatchTemplateMeta(t *testing.T) {
 		assert.Equal(t, "Must be a positive integer.", apiErr.Validations[0].Detail)
 	})
 
+	t.Run("ActivityBumpTuning", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Empty(t, template.ActivityBumpConnectionTypes)
+		require.Zero(t, template.ActivityBumpMaxPerDayMillis)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ActivityBumpConnectionTypes: &[]codersdk.ActivityBumpConnectionType{
+				codersdk.ActivityBumpConnectionTypeSSH,
+				codersdk.ActivityBumpConnectionTypeVSCode,
+				codersdk.ActivityBumpConnectionTypeSSH,
+			},
+			ActivityBumpMaxPerDayMillis: ptr.Ref((4 * time.Hour).Milliseconds()),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []codersdk.ActivityBumpConnectionType{
+			codersdk.ActivityBumpConnectionTypeSSH,
+			codersdk.ActivityBumpConnectionTypeVSCode,
+		}, updated.ActivityBumpConnectionTypes)
+		assert.Equal(t, (4 * time.Hour).Milliseconds(), updated.ActivityBumpMaxPerDayMillis)
+
+		// Omitted fields are preserved.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: ptr.Ref("updated description"),
+		})
+		require.NoError(t, err)
+		assert.Len(t, updated.ActivityBumpConnectionTypes, 2)
+		assert.Equal(t, (4 * time.Hour).Milliseconds(), updated.ActivityBumpMaxPerDayMillis)
+
+		// An empty list means all connection types bump the deadline.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ActivityBumpConnectionTypes: &[]codersdk.ActivityBumpConnectionType{},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, updated.ActivityBumpConnectionTypes)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ActivityBumpConnectionTypes: &[]codersdk.ActivityBumpConnectionType{"telnet"},
+			ActivityBumpMaxPerDayMillis: ptr.Ref(int64(-1)),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Validations, 2)
+		assert.Equal(t, "activity_bump_connection_types", apiErr.Validations[0].Field)
+		assert.Equal(t, "activity_bump_max_per_day_ms", apiErr.Validations[1].Field)
+	})
+
+	t.Run("ProvisionerTimeouts", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.ProvisionerPlanTimeoutMillis)
+		require.Zero(t, template.ProvisionerApplyTimeoutMillis)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ProvisionerApplyTimeoutMillis: ptr.Ref(time.Hour.Milliseconds()),
+		})
+		require.NoError(t, err)
+		assert.Zero(t, updated.ProvisionerPlanTimeoutMillis)
+		assert.Equal(t, time.Hour.Milliseconds(), updated.ProvisionerApplyTimeoutMillis)
+
+		// Omitting the timeouts leaves them untouched.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: ptr.Ref("updated description"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour.Milliseconds(), updated.ProvisionerApplyTimeoutMillis)
+
+		// Timeouts under a minute are rejected.
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ProvisionerPlanTimeoutMillis: ptr.Ref(int64(1_000)),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Validations, 1)
+		assert.Equal(t, "provisioner_plan_timeout_ms", apiErr.Validations[0].Field)
+
+		// Setting 0 removes the timeout.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ProvisionerApplyTimeoutMillis: ptr.Ref(int64(0)),
+		})
+		require.NoError(t, err)
+		assert.Zero(t, updated.ProvisionerApplyTimeoutMillis)
+	})
+
 	t.Run("TimeTilAutostopNotifyDisableAfterEnable", func(t *testing.T) {
 		t.Parallel()
 
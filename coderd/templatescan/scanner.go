@@ -0,0 +1,282 @@
+// Package templatescan submits newly imported template versions to an
+// external scanner and records the findings it reports.
+//
+// The scanner is a webhook. For every template version whose import job
+// succeeded, coderd POSTs a Request to the configured URL and expects a
+// Response in return. Findings are stored on the template version and may
+// block its promotion to the active version.
+package templatescan
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/util/xio"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// PollInterval is how often the scanner looks for template versions to
+	// scan.
+	PollInterval = 10 * time.Second
+
+	// ScanTimeout bounds a single request to the scanner webhook.
+	ScanTimeout = 2 * time.Minute
+
+	// StaleScanDuration is how long a claimed scan may run before another
+	// replica is allowed to claim it again, e.g. because the replica that
+	// claimed it shut down mid-scan.
+	StaleScanDuration = 10 * time.Minute
+
+	// LookbackDuration limits scanning to template versions imported this
+	// recently, so enabling the scanner does not submit every historical
+	// version.
+	LookbackDuration = 24 * time.Hour
+
+	// MaxScansPerRun is the maximum number of template versions scanned in a
+	// single run.
+	MaxScansPerRun = 10
+)
+
+// Request is the body POSTed to the scanner webhook.
+type Request struct {
+	TemplateVersionID   uuid.UUID  `json:"template_version_id"`
+	TemplateVersionName string     `json:"template_version_name"`
+	TemplateID          *uuid.UUID `json:"template_id,omitempty"`
+	OrganizationID      uuid.UUID  `json:"organization_id"`
+	// FilesMimetype is the mimetype of Files, usually application/x-tar.
+	FilesMimetype string `json:"files_mimetype"`
+	// Files is the uploaded template source archive, base64 encoded.
+	Files []byte `json:"files"`
+	// Plan is the Terraform plan JSON produced while importing the version.
+	// It is omitted for provisioners that don't produce one.
+	Plan json.RawMessage `json:"plan,omitempty"`
+}
+
+// Response is the body the scanner webhook must respond with.
+type Response struct {
+	Findings []codersdk.TemplateVersionScanFinding `json:"findings"`
+}
+
+// Scanner submits template versions to the scanner webhook on every tick from
+// its channel and stores the findings.
+type Scanner struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db     database.Store
+	log    slog.Logger
+	client *http.Client
+	url    string
+	tick   <-chan time.Time
+	stats  chan<- Stats
+}
+
+// Stats contains statistics about the last run of the scanner.
+type Stats struct {
+	// ScannedVersionIDs contains the IDs of all template versions whose scan
+	// finished, successfully or not.
+	ScannedVersionIDs []uuid.UUID
+	// Error is set if claiming scans failed or a scan result could not
+	// be stored. Scanner failures are stored on the scan instead.
+	Error error
+}
+
+// New returns a new template scanner that submits versions to url.
+func New(ctx context.Context, db database.Store, log slog.Logger, client *http.Client, url string, tick <-chan time.Time) *Scanner {
+	//nolint:gocritic // The scanner reads template files on behalf of the system.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Scanner{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		db:     db,
+		log:    log,
+		client: client,
+		url:    url,
+		tick:   tick,
+		stats:  nil,
+	}
+}
+
+// WithStatsChannel will cause Scanner to push a Stats to ch after every tick.
+// This push is blocking, so if ch is not read, the scanner will hang. This
+// should only be used in tests.
+func (s *Scanner) WithStatsChannel(ch chan<- Stats) *Scanner {
+	s.stats = ch
+	return s
+}
+
+// Start will cause the scanner to scan template versions on every tick from
+// its channel. It will stop when its context is Done, or when its channel is
+// closed.
+//
+// Start should only be called once.
+func (s *Scanner) Start() {
+	go func() {
+		defer close(s.done)
+		defer s.cancel()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case t, ok := <-s.tick:
+				if !ok {
+					return
+				}
+				stats := s.run(t)
+				if stats.Error != nil {
+					s.log.Warn(s.ctx, "error running template scanner once", slog.Error(stats.Error))
+				}
+				if s.stats != nil {
+					select {
+					case <-s.ctx.Done():
+						return
+					case s.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the scanner.
+func (s *Scanner) Close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *Scanner) run(t time.Time) Stats {
+	stats := Stats{
+		ScannedVersionIDs: []uuid.UUID{},
+	}
+
+	scans, err := s.db.ClaimTemplateVersionScans(s.ctx, database.ClaimTemplateVersionScansParams{
+		Now:            dbtime.Time(t),
+		CompletedAfter: dbtime.Time(t.Add(-LookbackDuration)),
+		StaleBefore:    dbtime.Time(t.Add(-StaleScanDuration)),
+		MaxScans:       MaxScansPerRun,
+	})
+	if err != nil {
+		stats.Error = xerrors.Errorf("claim template version scans: %w", err)
+		return stats
+	}
+
+	for _, scan := range scans {
+		log := s.log.With(slog.F("template_version_id", scan.TemplateVersionID))
+
+		update := database.UpdateTemplateVersionScanByTemplateVersionIDParams{
+			TemplateVersionID: scan.TemplateVersionID,
+			Status:            database.TemplateVersionScanStatusCompleted,
+			Findings:          json.RawMessage("[]"),
+		}
+		findings, err := s.scan(scan.TemplateVersionID)
+		if err == nil {
+			update.Findings, err = json.Marshal(findings)
+		}
+		if err != nil {
+			log.Warn(s.ctx, "template version scan failed", slog.Error(err))
+			update.Status = database.TemplateVersionScanStatusFailed
+			update.Error = err.Error()
+		}
+		update.CompletedAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
+
+		err = s.db.UpdateTemplateVersionScanByTemplateVersionID(s.ctx, update)
+		if err != nil {
+			stats.Error = xerrors.Errorf("update template version scan %s: %w", scan.TemplateVersionID, err)
+			return stats
+		}
+		log.Debug(s.ctx, "scanned template version", slog.F("status", update.Status), slog.F("findings", len(findings)))
+		stats.ScannedVersionIDs = append(stats.ScannedVersionIDs, scan.TemplateVersionID)
+	}
+
+	return stats
+}
+
+// scan submits a single template version to the webhook and returns the
+// findings it reported.
+func (s *Scanner) scan(templateVersionID uuid.UUID) ([]codersdk.TemplateVersionScanFinding, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, ScanTimeout)
+	defer cancel()
+
+	version, err := s.db.GetTemplateVersionByID(ctx, templateVersionID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template version: %w", err)
+	}
+	job, err := s.db.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		return nil, xerrors.Errorf("get import job: %w", err)
+	}
+	file, err := s.db.GetFileByID(ctx, job.FileID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template files: %w", err)
+	}
+
+	req := Request{
+		TemplateVersionID:   version.ID,
+		TemplateVersionName: version.Name,
+		OrganizationID:      version.OrganizationID,
+		FilesMimetype:       file.Mimetype,
+		Files:               file.Data,
+	}
+	if version.TemplateID.Valid {
+		req.TemplateID = &version.TemplateID.UUID
+	}
+	values, err := s.db.GetTemplateVersionTerraformValues(ctx, version.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get template version plan: %w", err)
+	}
+	if err == nil && len(values.CachedPlan) > 0 {
+		req.Plan = values.CachedPlan
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal scan request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, xerrors.Errorf("create scan request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, xerrors.Errorf("send scan request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, xerrors.Errorf("scanner responded with status %d: %s", res.StatusCode, xio.ReadErrorBody(res.Body))
+	}
+
+	var resp Response
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, xerrors.Errorf("decode scan response: %w", err)
+	}
+	findings := make([]codersdk.TemplateVersionScanFinding, 0, len(resp.Findings))
+	for i, finding := range resp.Findings {
+		switch finding.Severity {
+		case codersdk.TemplateVersionScanSeverityCritical,
+			codersdk.TemplateVersionScanSeverityHigh,
+			codersdk.TemplateVersionScanSeverityMedium,
+			codersdk.TemplateVersionScanSeverityLow,
+			codersdk.TemplateVersionScanSeverityInfo:
+		default:
+			return nil, xerrors.Errorf("finding %d has invalid severity %q", i, finding.Severity)
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
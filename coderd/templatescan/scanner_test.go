@@ -0,0 +1,177 @@
+package templatescan_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/templatescan"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestScanner(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{})
+		file = dbgen.File(t, db, database.File{CreatedBy: user.ID, Data: []byte("template source")})
+		now  = dbtime.Now()
+	)
+	newVersion := func(completedAt time.Time, jobErr string) database.TemplateVersion {
+		job := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			InitiatorID:    user.ID,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			StartedAt:      sql.NullTime{Time: completedAt.Add(-time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: completedAt, Valid: true},
+			Error:          sql.NullString{String: jobErr, Valid: jobErr != ""},
+		})
+		return dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+			JobID:          job.ID,
+		})
+	}
+	var (
+		critical = newVersion(now.Add(-time.Minute), "")
+		failed   = newVersion(now.Add(-time.Minute), "import failed")
+		old      = newVersion(now.Add(-2*templatescan.LookbackDuration), "")
+	)
+
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req templatescan.Request
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, critical.ID, req.TemplateVersionID)
+		assert.Equal(t, org.ID, req.OrganizationID)
+		assert.Equal(t, []byte("template source"), req.Files)
+		_ = json.NewEncoder(rw).Encode(templatescan.Response{
+			Findings: []codersdk.TemplateVersionScanFinding{{
+				Severity: codersdk.TemplateVersionScanSeverityCritical,
+				RuleID:   "privileged-container",
+				Message:  "Container runs privileged.",
+				Path:     "main.tf",
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	authzDB := dbauthz.New(db, rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry()), log, coderdtest.AccessControlStorePointer())
+	tickCh := make(chan time.Time)
+	statsCh := make(chan templatescan.Stats)
+	scanner := templatescan.New(ctx, authzDB, log, srv.Client(), srv.URL, tickCh).WithStatsChannel(statsCh)
+	scanner.Start()
+	t.Cleanup(scanner.Close)
+
+	tickCh <- now
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{critical.ID}, stats.ScannedVersionIDs)
+	require.EqualValues(t, 1, requests.Load())
+
+	scan, err := db.GetTemplateVersionScanByTemplateVersionID(ctx, critical.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.TemplateVersionScanStatusCompleted, scan.Status)
+	require.True(t, scan.CompletedAt.Valid)
+	var findings []codersdk.TemplateVersionScanFinding
+	require.NoError(t, json.Unmarshal(scan.Findings, &findings))
+	require.Len(t, findings, 1)
+	require.Equal(t, codersdk.TemplateVersionScanSeverityCritical, findings[0].Severity)
+
+	// Failed imports and versions older than the lookback are not scanned.
+	for _, id := range []uuid.UUID{failed.ID, old.ID} {
+		_, err = db.GetTemplateVersionScanByTemplateVersionID(ctx, id)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	}
+
+	// Finished scans are never claimed again.
+	tickCh <- now.Add(templatescan.StaleScanDuration * 2)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.ScannedVersionIDs)
+	require.EqualValues(t, 1, requests.Load())
+}
+
+func TestScannerWebhookError(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{})
+		file = dbgen.File(t, db, database.File{CreatedBy: user.ID})
+		now  = dbtime.Now()
+		job  = dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			InitiatorID:    user.ID,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			StartedAt:      sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+			CompletedAt:    sql.NullTime{Time: now, Valid: true},
+		})
+		version = dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+			JobID:          job.ID,
+		})
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+		_, _ = rw.Write([]byte("scanner unavailable"))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	authzDB := dbauthz.New(db, rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry()), log, coderdtest.AccessControlStorePointer())
+	tickCh := make(chan time.Time)
+	statsCh := make(chan templatescan.Stats)
+	scanner := templatescan.New(ctx, authzDB, log, srv.Client(), srv.URL, tickCh).WithStatsChannel(statsCh)
+	scanner.Start()
+	t.Cleanup(scanner.Close)
+
+	tickCh <- now
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{version.ID}, stats.ScannedVersionIDs)
+
+	scan, err := db.GetTemplateVersionScanByTemplateVersionID(ctx, version.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.TemplateVersionScanStatusFailed, scan.Status)
+	require.Contains(t, scan.Error, "502")
+	require.Contains(t, scan.Error, "scanner unavailable")
+}
@@ -0,0 +1,185 @@
+package coderd
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Simulating a schedule policy mirrors how the lifecycle executor handles
+// dormancy, deletion of dormant workspaces and cleanup of failed builds, so
+// admins can see the blast radius of a change before applying it.
+//
+// @Summary Simulate template schedule policy
+// @ID simulate-template-schedule-policy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.SimulateTemplateSchedulePolicyRequest true "Proposed schedule policy"
+// @Success 200 {object} codersdk.TemplateSchedulePolicySimulation
+// @Router /api/v2/templates/{template}/schedule-policy/simulate [post]
+func (api *API) postTemplateSchedulePolicySimulation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	var req codersdk.SimulateTemplateSchedulePolicyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionUpdate, template.RBACObject()) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only template administrators may simulate schedule policies.",
+		})
+		return
+	}
+
+	var (
+		failureTTLMillis               = time.Duration(template.FailureTTL).Milliseconds()
+		timeTilDormantMillis           = time.Duration(template.TimeTilDormant).Milliseconds()
+		timeTilDormantAutoDeleteMillis = time.Duration(template.TimeTilDormantAutoDelete).Milliseconds()
+	)
+	if req.FailureTTLMillis != nil {
+		failureTTLMillis = *req.FailureTTLMillis
+	}
+	if req.TimeTilDormantMillis != nil {
+		timeTilDormantMillis = *req.TimeTilDormantMillis
+	}
+	if req.TimeTilDormantAutoDeleteMillis != nil {
+		timeTilDormantAutoDeleteMillis = *req.TimeTilDormantAutoDeleteMillis
+	}
+
+	// Use the same validation as updating the template, so a simulation
+	// can't be run against settings that could never be applied.
+	const minTTL = 1000 * 60
+	var validErrs []codersdk.ValidationError
+	if failureTTLMillis < 0 || (failureTTLMillis > 0 && failureTTLMillis < minTTL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "failure_ttl_ms", Detail: "Value must be at least one minute."})
+	}
+	if timeTilDormantMillis < 0 || (timeTilDormantMillis > 0 && timeTilDormantMillis < minTTL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_dormant_ms", Detail: "Value must be at least one minute."})
+	}
+	if timeTilDormantAutoDeleteMillis < 0 || (timeTilDormantAutoDeleteMillis > 0 && timeTilDormantAutoDeleteMillis < minTTL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_dormant_autodelete_ms", Detail: "Value must be at least one minute."})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid schedule policy.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	workspaces, err := api.Database.GetWorkspaces(ctx, database.GetWorkspacesParams{
+		TemplateIDs: []uuid.UUID{template.ID},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var (
+		now                      = dbtime.Now()
+		failureTTL               = time.Duration(failureTTLMillis) * time.Millisecond
+		timeTilDormant           = time.Duration(timeTilDormantMillis) * time.Millisecond
+		timeTilDormantAutoDelete = time.Duration(timeTilDormantAutoDeleteMillis) * time.Millisecond
+		simulation               = codersdk.TemplateSchedulePolicySimulation{
+			SimulatedAt: now,
+			Workspaces:  []codersdk.TemplateSchedulePolicySimulationWorkspace{},
+		}
+	)
+	// Events in the past happen on the next run of the lifecycle executor.
+	clamp := func(t time.Time) *time.Time {
+		if t.Before(now) {
+			t = now
+		}
+		return &t
+	}
+	for _, ws := range workspaces {
+		// Prebuilt workspaces are not subject to the lifecycle executor.
+		if ws.OwnerID == database.PrebuildsSystemUserID {
+			continue
+		}
+		affected := codersdk.TemplateSchedulePolicySimulationWorkspace{
+			WorkspaceID:   ws.ID,
+			WorkspaceName: ws.Name,
+			OwnerID:       ws.OwnerID,
+			OwnerName:     ws.OwnerUsername,
+			LastUsedAt:    ws.LastUsedAt,
+		}
+
+		dormantAt := ws.DormantAt
+		if !dormantAt.Valid && timeTilDormant > 0 {
+			at := ws.LastUsedAt.Add(timeTilDormant)
+			exemption, err := api.Database.GetWorkspaceDormancyExemptionByWorkspaceID(ctx, ws.ID)
+			if err != nil && !httpapi.Is404Error(err) {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching dormancy exemption.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			if err == nil && exemption.ExpiresAt.After(at) {
+				at = exemption.ExpiresAt
+			}
+			affected.DormantAt = clamp(at)
+			dormantAt.Time, dormantAt.Valid = *affected.DormantAt, true
+		}
+		// The deletion time of dormant workspaces is recomputed when the
+		// template is updated.
+		if dormantAt.Valid && timeTilDormantAutoDelete > 0 {
+			affected.DeletingAt = clamp(dormantAt.Time.Add(timeTilDormantAutoDelete))
+		}
+		if failureTTL > 0 &&
+			ws.LatestBuildStatus == database.ProvisionerJobStatusFailed &&
+			(ws.LatestBuildTransition == database.WorkspaceTransitionStart ||
+				ws.LatestBuildTransition == database.WorkspaceTransitionStop) &&
+			ws.LatestBuildCompletedAt.Valid {
+			affected.FailedBuildStoppedAt = clamp(ws.LatestBuildCompletedAt.Time.Add(failureTTL))
+		}
+
+		if affected.DormantAt == nil && affected.DeletingAt == nil && affected.FailedBuildStoppedAt == nil {
+			continue
+		}
+		if affected.DormantAt != nil && affected.DormantAt.Equal(now) {
+			simulation.DormantImmediately++
+		}
+		if affected.DeletingAt != nil && affected.DeletingAt.Equal(now) {
+			simulation.DeletedImmediately++
+		}
+		if affected.FailedBuildStoppedAt != nil && affected.FailedBuildStoppedAt.Equal(now) {
+			simulation.StoppedImmediately++
+		}
+		simulation.Workspaces = append(simulation.Workspaces, affected)
+	}
+	// The workspaces affected first are listed first.
+	slices.SortStableFunc(simulation.Workspaces, func(a, b codersdk.TemplateSchedulePolicySimulationWorkspace) int {
+		return firstSimulatedEvent(a).Compare(firstSimulatedEvent(b))
+	})
+
+	httpapi.Write(ctx, rw, http.StatusOK, simulation)
+}
+
+func firstSimulatedEvent(ws codersdk.TemplateSchedulePolicySimulationWorkspace) time.Time {
+	var first time.Time
+	for _, t := range []*time.Time{ws.DormantAt, ws.DeletingAt, ws.FailedBuildStoppedAt} {
+		if t != nil && (first.IsZero() || t.Before(first)) {
+			first = *t
+		}
+	}
+	return first
+}
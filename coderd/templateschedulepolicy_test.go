@@ -0,0 +1,113 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSimulateTemplateSchedulePolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		now := dbtime.Now()
+		stale := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+			LastUsedAt:     now.Add(-30 * 24 * time.Hour),
+		}).Do()
+		recent := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+			TemplateID:     stale.Workspace.TemplateID,
+			LastUsedAt:     now.Add(-24 * time.Hour),
+		}).Do()
+
+		// The current settings of the template don't affect any workspace.
+		simulation, err := client.SimulateTemplateSchedulePolicy(ctx, stale.Workspace.TemplateID, codersdk.SimulateTemplateSchedulePolicyRequest{})
+		require.NoError(t, err)
+		require.Empty(t, simulation.Workspaces)
+
+		simulation, err = client.SimulateTemplateSchedulePolicy(ctx, stale.Workspace.TemplateID, codersdk.SimulateTemplateSchedulePolicyRequest{
+			TimeTilDormantMillis:           ptr.Ref((7 * 24 * time.Hour).Milliseconds()),
+			TimeTilDormantAutoDeleteMillis: ptr.Ref((14 * 24 * time.Hour).Milliseconds()),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, simulation.DormantImmediately)
+		require.Equal(t, 0, simulation.DeletedImmediately)
+		require.Len(t, simulation.Workspaces, 2)
+
+		// The workspace affected first is listed first.
+		require.Equal(t, stale.Workspace.ID, simulation.Workspaces[0].WorkspaceID)
+		require.NotNil(t, simulation.Workspaces[0].DormantAt)
+		require.True(t, simulation.Workspaces[0].DormantAt.Equal(simulation.SimulatedAt))
+		require.NotNil(t, simulation.Workspaces[0].DeletingAt)
+		require.True(t, simulation.Workspaces[0].DeletingAt.Equal(simulation.SimulatedAt.Add(14*24*time.Hour)))
+
+		require.Equal(t, recent.Workspace.ID, simulation.Workspaces[1].WorkspaceID)
+		require.NotNil(t, simulation.Workspaces[1].DormantAt)
+		require.WithinDuration(t, now.Add(6*24*time.Hour), *simulation.Workspaces[1].DormantAt, time.Minute)
+
+		// Nothing is applied by a simulation.
+		template, err := client.Template(ctx, stale.Workspace.TemplateID)
+		require.NoError(t, err)
+		require.Zero(t, template.TimeTilDormantMillis)
+		workspace, err := client.Workspace(ctx, stale.Workspace.ID)
+		require.NoError(t, err)
+		require.Nil(t, workspace.DormantAt)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		}).Do()
+
+		_, err := client.SimulateTemplateSchedulePolicy(ctx, r.Workspace.TemplateID, codersdk.SimulateTemplateSchedulePolicyRequest{
+			TimeTilDormantMillis: ptr.Ref(int64(1)),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "time_til_dormant_ms", apiErr.Validations[0].Field)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		}).Do()
+
+		_, err := member.SimulateTemplateSchedulePolicy(ctx, r.Workspace.TemplateID, codersdk.SimulateTemplateSchedulePolicyRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
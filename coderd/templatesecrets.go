@@ -0,0 +1,400 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// @Summary Get template secrets
+// @Description Returns the secrets of a template. Secret values are never
+// @Description returned.
+// @ID get-template-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateSecret
+// @Router /api/v2/templates/{template}/secrets [get]
+func (api *API) templateSecrets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	secrets, err := api.Database.GetTemplateSecretsByTemplateID(ctx, template.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := make([]codersdk.TemplateSecret, 0, len(secrets))
+	for _, secret := range secrets {
+		resp = append(resp, convertTemplateSecret(secret))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Create template secret
+// @Description Declares a secret on a template. Agent scripts of the
+// @Description template's workspaces that reference the secret by name as an
+// @Description environment variable get its value in their environment.
+// @ID create-template-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateTemplateSecretRequest true "Create secret request"
+// @Success 201 {object} codersdk.TemplateSecret
+// @Router /api/v2/templates/{template}/secrets [post]
+func (api *API) postTemplateSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateSecret](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateTemplateSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	var validations []codersdk.ValidationError
+	if err := codersdk.UserSecretEnvNameValid(req.Name); err != nil {
+		validations = append(validations, codersdk.ValidationError{Field: "name", Detail: err.Error()})
+	}
+	if err := codersdk.UserSecretValueValid(req.Value); err != nil {
+		validations = append(validations, codersdk.ValidationError{Field: "value", Detail: err.Error()})
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid template secret.",
+			Validations: validations,
+		})
+		return
+	}
+
+	secret, err := api.Database.InsertTemplateSecret(ctx, database.InsertTemplateSecretParams{
+		ID:          uuid.New(),
+		TemplateID:  template.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		Value:       req.Value,
+		ValueKeyID:  sql.NullString{},
+		CreatedAt:   dbtime.Time(api.Clock.Now()),
+	})
+	if database.IsUniqueViolation(err, database.UniqueTemplateSecretsTemplateIDNameIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "A secret with this name already exists for the template.",
+		})
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = secret
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertTemplateSecret(secret))
+}
+
+// @Summary Update template secret
+// @ID update-template-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param name path string true "Secret name"
+// @Param request body codersdk.UpdateTemplateSecretRequest true "Update secret request"
+// @Success 200 {object} codersdk.TemplateSecret
+// @Router /api/v2/templates/{template}/secrets/{name} [patch]
+func (api *API) patchTemplateSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		name              = chi.URLParam(r, "name")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateSecret](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.UpdateTemplateSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Value == nil && req.Description == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "At least one field must be provided.",
+		})
+		return
+	}
+
+	params := database.UpdateTemplateSecretByTemplateIDAndNameParams{
+		TemplateID:        template.ID,
+		Name:              name,
+		UpdateValue:       req.Value != nil,
+		ValueKeyID:        sql.NullString{},
+		UpdateDescription: req.Description != nil,
+		UpdatedAt:         dbtime.Time(api.Clock.Now()),
+	}
+	if req.Value != nil {
+		if err := codersdk.UserSecretValueValid(*req.Value); err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Invalid template secret.",
+				Validations: []codersdk.ValidationError{{Field: "value", Detail: err.Error()}},
+			})
+			return
+		}
+		params.Value = *req.Value
+	}
+	if req.Description != nil {
+		params.Description = *req.Description
+	}
+
+	var secret database.TemplateSecret
+	err := api.Database.InTx(func(tx database.Store) error {
+		old, err := tx.GetTemplateSecretByTemplateIDAndName(ctx, database.GetTemplateSecretByTemplateIDAndNameParams{
+			TemplateID: template.ID,
+			Name:       name,
+		})
+		if err != nil {
+			return xerrors.Errorf("fetch template secret: %w", err)
+		}
+		aReq.Old = old
+
+		secret, err = tx.UpdateTemplateSecretByTemplateIDAndName(ctx, params)
+		if err != nil {
+			return xerrors.Errorf("update template secret: %w", err)
+		}
+		aReq.New = secret
+		return nil
+	}, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateSecret(secret))
+}
+
+// @Summary Delete template secret
+// @ID delete-template-secret
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param name path string true "Secret name"
+// @Success 204
+// @Router /api/v2/templates/{template}/secrets/{name} [delete]
+func (api *API) deleteTemplateSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		name              = chi.URLParam(r, "name")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateSecret](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionDelete,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	deleted, err := api.Database.DeleteTemplateSecretByTemplateIDAndName(ctx, database.DeleteTemplateSecretByTemplateIDAndNameParams{
+		TemplateID: template.ID,
+		Name:       name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.Old = deleted
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// templateSecretAccessAudit is recorded in the additional fields of the audit
+// log written when an agent fetches a template secret.
+type templateSecretAccessAudit struct {
+	WorkspaceID       uuid.UUID `json:"workspace_id"`
+	WorkspaceName     string    `json:"workspace_name"`
+	AgentName         string    `json:"agent_name"`
+	ScriptID          uuid.UUID `json:"script_id"`
+	ScriptDisplayName string    `json:"script_display_name"`
+}
+
+// @Summary Get secrets for workspace agent script
+// @Description Returns the values of the template secrets referenced by an
+// @Description agent script. Every secret returned is recorded in the audit
+// @Description log.
+// @ID get-secrets-for-workspace-agent-script
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.ScriptSecretsRequest true "Script secrets request"
+// @Success 200 {object} agentsdk.ScriptSecretsResponse
+// @Router /api/v2/workspaceagents/me/script-secrets [post]
+func (api *API) workspaceAgentScriptSecrets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.ScriptSecretsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// The agent can only read the secrets referenced by its own scripts.
+	//nolint:gocritic // Agents can't read scripts or template secrets directly.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(sysCtx, []uuid.UUID{workspaceAgent.ID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	var script database.GetWorkspaceAgentScriptsByAgentIDsRow
+	for _, s := range scripts {
+		if s.ID == req.ScriptID {
+			script = s
+			break
+		}
+	}
+	if script.ID == uuid.Nil {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	secrets, err := api.Database.GetTemplateSecretsByTemplateID(sysCtx, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	additionalFields, err := json.Marshal(templateSecretAccessAudit{
+		WorkspaceID:       workspace.ID,
+		WorkspaceName:     workspace.Name,
+		AgentName:         workspaceAgent.Name,
+		ScriptID:          script.ID,
+		ScriptDisplayName: script.DisplayName,
+	})
+	if err != nil {
+		api.Logger.Error(ctx, "marshal template secret audit fields", slog.Error(err))
+	}
+
+	auditor := api.Auditor.Load()
+	requestID := httpmw.RequestID(r)
+	auditCtx := context.WithoutCancel(ctx)
+	resp := agentsdk.ScriptSecretsResponse{Env: map[string]string{}}
+	for _, secret := range secrets {
+		if !templateSecretReferenced(script.Script, secret.Name) {
+			continue
+		}
+		resp.Env[secret.Name] = secret.Value
+		audit.BackgroundAudit(auditCtx, &audit.BackgroundAuditParams[database.TemplateSecret]{
+			Audit:            *auditor,
+			Log:              api.Logger,
+			UserID:           workspace.OwnerID,
+			RequestID:        requestID,
+			Status:           http.StatusOK,
+			Action:           database.AuditActionDownload,
+			OrganizationID:   workspace.OrganizationID,
+			IP:               r.RemoteAddr,
+			UserAgent:        r.UserAgent(),
+			AdditionalFields: additionalFields,
+			New:              secret,
+			Old:              secret,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// templateSecretReferenced reports whether a script references the
+// environment variable of a secret, in either shell ($NAME, ${NAME}),
+// PowerShell ($env:NAME) or cmd (%NAME%) syntax. Secrets that aren't
+// referenced are never sent to the agent.
+func templateSecretReferenced(script, name string) bool {
+	quoted := regexp.QuoteMeta(name)
+	return regexp.MustCompile(`\$(?:\{|env:)?` + quoted + `(?:[^A-Za-z0-9_]|$)|%` + quoted + `%`).MatchString(script)
+}
+
+func convertTemplateSecret(secret database.TemplateSecret) codersdk.TemplateSecret {
+	return codersdk.TemplateSecret{
+		ID:          secret.ID,
+		TemplateID:  secret.TemplateID,
+		Name:        secret.Name,
+		Description: secret.Description,
+		CreatedAt:   secret.CreatedAt,
+		UpdatedAt:   secret.UpdatedAt,
+	}
+}
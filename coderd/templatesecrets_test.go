@@ -0,0 +1,140 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateSecrets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		secret, err := client.CreateTemplateSecret(ctx, template.ID, codersdk.CreateTemplateSecretRequest{
+			Name:        "DATABASE_PASSWORD",
+			Description: "Password of the shared database.",
+			Value:       "hunter2",
+		})
+		require.NoError(t, err)
+		require.Equal(t, template.ID, secret.TemplateID)
+		require.Equal(t, "DATABASE_PASSWORD", secret.Name)
+
+		_, err = client.CreateTemplateSecret(ctx, template.ID, codersdk.CreateTemplateSecretRequest{
+			Name:  "DATABASE_PASSWORD",
+			Value: "hunter3",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// Names must be valid environment variables, and CODER_ is reserved.
+		for _, name := range []string{"1PASSWORD", "MY-SECRET", "CODER_TOKEN"} {
+			_, err = client.CreateTemplateSecret(ctx, template.ID, codersdk.CreateTemplateSecretRequest{
+				Name:  name,
+				Value: "value",
+			})
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+
+		description := "Rotated."
+		value := "correct-horse"
+		updated, err := client.UpdateTemplateSecret(ctx, template.ID, secret.Name, codersdk.UpdateTemplateSecretRequest{
+			Description: &description,
+			Value:       &value,
+		})
+		require.NoError(t, err)
+		require.Equal(t, description, updated.Description)
+
+		secrets, err := client.TemplateSecrets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, secret.ID, secrets[0].ID)
+
+		// Template secrets are only visible to users that can update the
+		// template.
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err = member.TemplateSecrets(ctx, template.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = client.DeleteTemplateSecret(ctx, template.ID, secret.Name)
+		require.NoError(t, err)
+		err = client.DeleteTemplateSecret(ctx, template.ID, secret.Name)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("AgentScript", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		auditor := audit.NewMock()
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+			agents[0].Scripts = []*proto.Script{{
+				DisplayName: "Startup",
+				Script:      "psql \"postgres://app:${DATABASE_PASSWORD}@db/app\"\necho $API_TOKEN_SUFFIX",
+				RunOnStart:  true,
+			}}
+			return agents
+		}).Do()
+
+		for name, value := range map[string]string{
+			"DATABASE_PASSWORD": "hunter2",
+			"API_TOKEN":         "unreferenced",
+		} {
+			_, err := client.CreateTemplateSecret(ctx, r.Workspace.TemplateID, codersdk.CreateTemplateSecretRequest{
+				Name:  name,
+				Value: value,
+			})
+			require.NoError(t, err)
+		}
+
+		scripts, err := db.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{r.Agents[0].ID})
+		require.NoError(t, err)
+		require.Len(t, scripts, 1)
+
+		auditor.ResetLogs()
+		agentClient := agentsdk.New(client.URL, agentsdk.WithFixedToken(r.AgentToken))
+		resp, err := agentClient.ScriptSecrets(ctx, agentsdk.ScriptSecretsRequest{ScriptID: scripts[0].ID})
+		require.NoError(t, err)
+		// Only referenced secrets are returned.
+		require.Equal(t, map[string]string{"DATABASE_PASSWORD": "hunter2"}, resp.Env)
+
+		logs := auditor.AuditLogs()
+		require.Len(t, logs, 1)
+		require.Equal(t, database.AuditActionDownload, logs[0].Action)
+		require.Equal(t, database.ResourceTypeTemplateSecret, logs[0].ResourceType)
+		require.Equal(t, "DATABASE_PASSWORD", logs[0].ResourceTarget)
+		require.Equal(t, user.UserID, logs[0].UserID)
+
+		_, err = agentClient.ScriptSecrets(ctx, agentsdk.ScriptSecretsRequest{ScriptID: uuid.New()})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,297 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpapi/httperror"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template user presets
+// @Description Returns the presets the authenticated user saved for the
+// @Description template. They are private to the user.
+// @ID get-template-user-presets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.UserPreset
+// @Router /api/v2/templates/{template}/user-presets [get]
+func (api *API) templateUserPresets(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		apiKey   = httpmw.APIKey(r)
+	)
+
+	presets, err := api.Database.GetTemplateUserPresetsByTemplateIDAndUserID(ctx, database.GetTemplateUserPresetsByTemplateIDAndUserIDParams{
+		TemplateID: template.ID,
+		UserID:     apiKey.UserID,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	sdkPresets := make([]codersdk.UserPreset, 0, len(presets))
+	for _, preset := range presets {
+		sdkPreset, err := convertUserPreset(preset)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		sdkPresets = append(sdkPresets, sdkPreset)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, sdkPresets)
+}
+
+// @Summary Create template user preset
+// @Description Saves a named set of parameter values of the authenticated
+// @Description user for the template, which can be applied when creating a
+// @Description workspace. The parameters are validated against the active
+// @Description version of the template. Saving a preset with the name of an
+// @Description existing one replaces its parameters.
+// @ID create-template-user-preset
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateUserPresetRequest true "Create user preset request"
+// @Success 201 {object} codersdk.UserPreset
+// @Router /api/v2/templates/{template}/user-presets [post]
+func (api *API) postTemplateUserPreset(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		apiKey   = httpmw.APIKey(r)
+	)
+
+	var req codersdk.CreateUserPresetRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	richParameters, err := api.templateVersionRichParameters(ctx, template.ActiveVersionID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if err := validateUserPresetParameters(template, richParameters, req.Parameters); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid user preset.",
+			Validations: []codersdk.ValidationError{{Field: "parameters", Detail: err.Error()}},
+		})
+		return
+	}
+
+	parameters, err := json.Marshal(req.Parameters)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	preset, err := api.Database.UpsertTemplateUserPreset(ctx, database.UpsertTemplateUserPresetParams{
+		ID:         uuid.New(),
+		TemplateID: template.ID,
+		UserID:     apiKey.UserID,
+		Name:       req.Name,
+		Parameters: parameters,
+		Now:        dbtime.Time(api.Clock.Now()),
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	sdkPreset, err := convertUserPreset(preset)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, sdkPreset)
+}
+
+// @Summary Delete template user preset
+// @ID delete-template-user-preset
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param preset path string true "User preset ID" format(uuid)
+// @Success 204
+// @Router /api/v2/templates/{template}/user-presets/{preset} [delete]
+func (api *API) deleteTemplateUserPreset(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	presetID, ok := httpmw.ParseUUIDParam(rw, r, "preset")
+	if !ok {
+		return
+	}
+
+	preset, err := api.Database.GetTemplateUserPresetByID(ctx, presetID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if preset.TemplateID != template.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	err = api.Database.DeleteTemplateUserPresetByID(ctx, preset.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// applyUserPreset returns the parameter values of a workspace created with
+// the given user preset. The values in parameters take precedence over the
+// values of the preset, which are validated against the template version the
+// workspace is created with.
+func (api *API) applyUserPreset(ctx context.Context, template database.Template, templateVersionID, presetID uuid.UUID, parameters []codersdk.WorkspaceBuildParameter) ([]codersdk.WorkspaceBuildParameter, error) {
+	preset, err := api.Database.GetTemplateUserPresetByID(ctx, presetID)
+	if httpapi.Is404Error(err) || (err == nil && preset.TemplateID != template.ID) {
+		return nil, httperror.NewResponseError(http.StatusBadRequest, codersdk.Response{
+			Message:     "User preset not found.",
+			Validations: []codersdk.ValidationError{{Field: "user_preset_id", Detail: "The template has no user preset with this ID."}},
+		})
+	}
+	if err != nil {
+		return nil, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user preset.",
+			Detail:  err.Error(),
+		})
+	}
+
+	var presetParameters []codersdk.WorkspaceBuildParameter
+	if err := json.Unmarshal(preset.Parameters, &presetParameters); err != nil {
+		return nil, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error decoding user preset parameters.",
+			Detail:  err.Error(),
+		})
+	}
+	richParameters, err := api.templateVersionRichParameters(ctx, templateVersionID)
+	if err != nil {
+		return nil, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version parameters.",
+			Detail:  err.Error(),
+		})
+	}
+	// The template version may have changed since the preset was saved.
+	if err := validateUserPresetParameters(template, richParameters, presetParameters); err != nil {
+		return nil, httperror.NewResponseError(http.StatusBadRequest, codersdk.Response{
+			Message:     fmt.Sprintf("User preset %q is not valid for the template version.", preset.Name),
+			Validations: []codersdk.ValidationError{{Field: "user_preset_id", Detail: err.Error()}},
+		})
+	}
+
+	merged := make([]codersdk.WorkspaceBuildParameter, 0, len(parameters)+len(presetParameters))
+	merged = append(merged, parameters...)
+	for _, presetParameter := range presetParameters {
+		overridden := false
+		for _, parameter := range parameters {
+			if parameter.Name == presetParameter.Name {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, presetParameter)
+		}
+	}
+	return merged, nil
+}
+
+func (api *API) templateVersionRichParameters(ctx context.Context, templateVersionID uuid.UUID) ([]codersdk.TemplateVersionParameter, error) {
+	dbParameters, err := api.Database.GetTemplateVersionParameters(ctx, templateVersionID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template version parameters: %w", err)
+	}
+	parameters, err := db2sdk.TemplateVersionParameters(dbParameters)
+	if err != nil {
+		return nil, xerrors.Errorf("convert template version parameters: %w", err)
+	}
+	return parameters, nil
+}
+
+// validateUserPresetParameters validates the parameters of a user preset
+// against the parameters of a template version. Presets may omit parameters,
+// which are then prompted for or defaulted when the workspace is created.
+func validateUserPresetParameters(template database.Template, richParameters []codersdk.TemplateVersionParameter, parameters []codersdk.WorkspaceBuildParameter) error {
+	if len(parameters) == 0 {
+		return xerrors.New("at least one parameter is required")
+	}
+	seen := make(map[string]struct{}, len(parameters))
+	for _, parameter := range parameters {
+		if _, ok := seen[parameter.Name]; ok {
+			return xerrors.Errorf("parameter %q is specified more than once", parameter.Name)
+		}
+		seen[parameter.Name] = struct{}{}
+
+		var richParameter *codersdk.TemplateVersionParameter
+		for i := range richParameters {
+			if richParameters[i].Name == parameter.Name {
+				richParameter = &richParameters[i]
+				break
+			}
+		}
+		if richParameter == nil {
+			return xerrors.Errorf("parameter %q does not exist in the template version", parameter.Name)
+		}
+		if richParameter.Ephemeral {
+			return xerrors.Errorf("parameter %q is ephemeral and can't be saved", parameter.Name)
+		}
+		// The options and validation of dynamic parameters may depend on the
+		// values of other parameters, so their values are validated when the
+		// workspace is built.
+		if !template.UseClassicParameterFlow {
+			continue
+		}
+		if err := codersdk.ValidateWorkspaceBuildParameter(*richParameter, &parameter, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertUserPreset(preset database.TemplateUserPreset) (codersdk.UserPreset, error) {
+	var parameters []codersdk.WorkspaceBuildParameter
+	if err := json.Unmarshal(preset.Parameters, &parameters); err != nil {
+		return codersdk.UserPreset{}, xerrors.Errorf("unmarshal user preset parameters: %w", err)
+	}
+	return codersdk.UserPreset{
+		ID:         preset.ID,
+		TemplateID: preset.TemplateID,
+		Name:       preset.Name,
+		Parameters: parameters,
+		CreatedAt:  preset.CreatedAt,
+		UpdatedAt:  preset.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateUserPresets(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	otherMember, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionGraph: []*proto.Response{{
+			Type: &proto.Response_Graph{
+				Graph: &proto.GraphComplete{
+					Parameters: []*proto.RichParameter{{
+						Name:         "region",
+						Type:         "string",
+						DefaultValue: "us",
+						Mutable:      true,
+						Options: []*proto.RichParameterOption{
+							{Name: "US", Value: "us"},
+							{Name: "Europe", Value: "eu"},
+						},
+					}, {
+						Name:          "cpu",
+						Type:          "number",
+						DefaultValue:  "2",
+						Mutable:       true,
+						ValidationMin: ptr.Ref(int32(1)),
+						ValidationMax: ptr.Ref(int32(8)),
+					}, {
+						Name:         "reset",
+						Type:         "bool",
+						DefaultValue: "false",
+						Mutable:      true,
+						Ephemeral:    true,
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+		ctr.UseClassicParameterFlow = ptr.Ref(true)
+	})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Parameters are validated against the active version.
+	for _, parameters := range [][]codersdk.WorkspaceBuildParameter{
+		nil,
+		{{Name: "zone", Value: "a"}},
+		{{Name: "cpu", Value: "16"}},
+		{{Name: "region", Value: "asia"}},
+		{{Name: "reset", Value: "true"}},
+		{{Name: "cpu", Value: "4"}, {Name: "cpu", Value: "2"}},
+	} {
+		_, err := member.CreateTemplateUserPreset(ctx, template.ID, codersdk.CreateUserPresetRequest{
+			Name:       "invalid",
+			Parameters: parameters,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	}
+
+	_, err := member.CreateTemplateUserPreset(ctx, template.ID, codersdk.CreateUserPresetRequest{
+		Name:       "my usual config",
+		Parameters: []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "us"}},
+	})
+	require.NoError(t, err)
+	// Saving a preset with the same name replaces its parameters.
+	preset, err := member.CreateTemplateUserPreset(ctx, template.ID, codersdk.CreateUserPresetRequest{
+		Name:       "my usual config",
+		Parameters: []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}, {Name: "cpu", Value: "4"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, template.ID, preset.TemplateID)
+
+	presets, err := member.TemplateUserPresets(ctx, template.ID)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+	require.Equal(t, preset.ID, presets[0].ID)
+	require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}, {Name: "cpu", Value: "4"}}, presets[0].Parameters)
+
+	// Presets are private to the user.
+	presets, err = otherMember.TemplateUserPresets(ctx, template.ID)
+	require.NoError(t, err)
+	require.Empty(t, presets)
+	_, err = otherMember.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID:   template.ID,
+		Name:         "other",
+		UserPresetID: preset.ID,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// The preset is applied when creating a workspace, and explicit values
+	// take precedence.
+	workspace := coderdtest.CreateWorkspace(t, member, template.ID, func(req *codersdk.CreateWorkspaceRequest) {
+		req.UserPresetID = preset.ID
+		req.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: "cpu", Value: "2"}}
+	})
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+	parameters, err := member.WorkspaceBuildParameters(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	values := make(map[string]string, len(parameters))
+	for _, parameter := range parameters {
+		values[parameter.Name] = parameter.Value
+	}
+	require.Equal(t, "eu", values["region"])
+	require.Equal(t, "2", values["cpu"])
+
+	err = member.DeleteTemplateUserPreset(ctx, template.ID, preset.ID)
+	require.NoError(t, err)
+	presets, err = member.TemplateUserPresets(ctx, template.ID)
+	require.NoError(t, err)
+	require.Empty(t, presets)
+}
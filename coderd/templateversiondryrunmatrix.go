@@ -0,0 +1,340 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"cdr.dev/slog"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// templateVersionDryRunMatrixMaxJobs limits the number of dry-runs of a
+// matrix, as each of them occupies a provisioner daemon.
+const templateVersionDryRunMatrixMaxJobs = 20
+
+// @Summary Create template version dry-run matrix
+// @Description Runs a dry-run of the template version on each group of
+// @Description provisioner daemons, to verify the template version is
+// @Description compatible with them before upgrading them.
+// @ID create-template-version-dry-run-matrix
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param request body codersdk.CreateTemplateVersionDryRunMatrixRequest true "Dry-run matrix request"
+// @Success 201 {object} codersdk.TemplateVersionDryRunMatrix
+// @Router /api/v2/templateversions/{templateversion}/dry-run-matrix [post]
+func (api *API) postTemplateVersionDryRunMatrix(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var (
+		apiKey          = httpmw.APIKey(r)
+		templateVersion = httpmw.TemplateVersionParam(r)
+	)
+
+	// We use the workspace RBAC check since we don't want to allow dry runs if
+	// the user can't create workspaces.
+	if !api.Authorize(r, policy.ActionCreate,
+		rbac.ResourceWorkspace.InOrg(templateVersion.OrganizationID).WithOwner(apiKey.UserID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.CreateTemplateVersionDryRunMatrixRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if len(req.ProvisionerTags) > templateVersionDryRunMatrixMaxJobs {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("A dry-run matrix can have at most %d sets of provisioner tags.", templateVersionDryRunMatrixMaxJobs),
+		})
+		return
+	}
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !job.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusTooEarly, codersdk.Response{
+			Message: "Template version import job hasn't completed!",
+		})
+		return
+	}
+
+	targets := req.ProvisionerTags
+	if len(targets) == 0 {
+		targets, err = api.templateVersionDryRunMatrixTargets(ctx, job)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching provisioner daemons by organization.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if len(targets) == 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "No provisioner daemons are able to build this template version.",
+			})
+			return
+		}
+		if len(targets) > templateVersionDryRunMatrixMaxJobs {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Provisioner daemons have more than %d sets of tags, specify the tags to run dry-runs on.", templateVersionDryRunMatrixMaxJobs),
+			})
+			return
+		}
+	}
+
+	provisionerJobs := make([]database.ProvisionerJob, 0, len(targets))
+	err = api.Database.InTx(func(tx database.Store) error {
+		for _, target := range targets {
+			// The tags of the template version are kept, so the dry-run is
+			// only picked up by provisioner daemons able to build it.
+			tags := maps.Clone(job.Tags)
+			if tags == nil {
+				tags = database.StringMap{}
+			}
+			maps.Copy(tags, target)
+			provisionerJob, err := api.insertTemplateVersionDryRunJob(ctx, tx, apiKey.UserID, templateVersion, job, req.WorkspaceName, req.RichParameterValues, tags)
+			if err != nil {
+				return err
+			}
+			provisionerJobs = append(provisionerJobs, provisionerJob)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rows := make([]database.GetProvisionerJobsByIDsWithQueuePositionRow, 0, len(provisionerJobs))
+	for _, provisionerJob := range provisionerJobs {
+		err = provisionerjobs.PostJob(api.Pubsub, provisionerJob)
+		if err != nil {
+			// Client probably doesn't care about this error, so just log it.
+			api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+		}
+		rows = append(rows, database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: provisionerJob,
+		})
+	}
+
+	matrix, err := api.convertTemplateVersionDryRunMatrix(ctx, rows)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner daemons by organization.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, matrix)
+}
+
+// @Summary Get template version dry-run matrix
+// @ID get-template-version-dry-run-matrix
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param job_ids query []string true "Dry-run job IDs" format(uuid) collectionFormat(csv)
+// @Success 200 {object} codersdk.TemplateVersionDryRunMatrix
+// @Router /api/v2/templateversions/{templateversion}/dry-run-matrix [get]
+func (api *API) templateVersionDryRunMatrix(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx             = r.Context()
+		templateVersion = httpmw.TemplateVersionParam(r)
+	)
+
+	qp := r.URL.Query()
+	p := httpapi.NewQueryParamParser().RequiredNotEmpty("job_ids")
+	jobIDs := p.UUIDs(qp, nil, "job_ids")
+	p.ErrorExcessParams(qp)
+	if len(jobIDs) > templateVersionDryRunMatrixMaxJobs {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "job_ids",
+			Detail: fmt.Sprintf("At most %d job IDs can be specified.", templateVersionDryRunMatrixMaxJobs),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	jobs, err := api.Database.GetProvisionerJobsByIDsWithQueuePosition(ctx, database.GetProvisionerJobsByIDsWithQueuePositionParams{
+		IDs:             jobIDs,
+		StaleIntervalMS: provisionerdserver.StaleInterval.Milliseconds(),
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	jobsByID := make(map[uuid.UUID]database.GetProvisionerJobsByIDsWithQueuePositionRow, len(jobs))
+	for _, job := range jobs {
+		jobsByID[job.ProvisionerJob.ID] = job
+	}
+
+	// Results are returned in the order of the request.
+	rows := make([]database.GetProvisionerJobsByIDsWithQueuePositionRow, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		job, ok := jobsByID[jobID]
+		if !ok || job.ProvisionerJob.Type != database.ProvisionerJobTypeTemplateVersionDryRun {
+			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+				Message: fmt.Sprintf("Provisioner job %q not found.", jobID),
+			})
+			return
+		}
+
+		// Do a workspace resource check since it's basically a workspace dry-run.
+		if !api.Authorize(r, policy.ActionRead,
+			rbac.ResourceWorkspace.InOrg(templateVersion.OrganizationID).WithOwner(job.ProvisionerJob.InitiatorID.String())) {
+			httpapi.Forbidden(rw)
+			return
+		}
+
+		// Verify that the template version is the one used in the request.
+		var input provisionerdserver.TemplateVersionDryRunJob
+		err = json.Unmarshal(job.ProvisionerJob.Input, &input)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error unmarshalling job metadata.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if input.TemplateVersionID != templateVersion.ID {
+			httpapi.Forbidden(rw)
+			return
+		}
+		rows = append(rows, job)
+	}
+
+	matrix, err := api.convertTemplateVersionDryRunMatrix(ctx, rows)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner daemons by organization.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, matrix)
+}
+
+// templateVersionDryRunMatrixTargets returns the distinct sets of tags of the
+// provisioner daemons able to run the jobs of a template version, ordered by
+// their string representation.
+func (api *API) templateVersionDryRunMatrixTargets(ctx context.Context, importJob database.ProvisionerJob) ([]map[string]string, error) {
+	daemons, err := api.templateVersionDryRunMatrixDaemons(ctx, importJob.OrganizationID, importJob.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := map[string]map[string]string{}
+	for _, daemon := range daemons {
+		if !daemon.LastSeenAt.Valid || !slices.Contains(daemon.Provisioners, importJob.Provisioner) {
+			continue
+		}
+		pairs := make([]string, 0, len(daemon.Tags))
+		for k, v := range daemon.Tags {
+			pairs = append(pairs, k+"="+v)
+		}
+		sort.Strings(pairs)
+		targets[strings.Join(pairs, " ")] = daemon.Tags
+	}
+
+	keys := slices.Sorted(maps.Keys(targets))
+	tags := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, targets[key])
+	}
+	return tags, nil
+}
+
+// convertTemplateVersionDryRunMatrix reports the dry-run jobs along with the
+// versions of the provisioner daemons able to run them.
+func (api *API) convertTemplateVersionDryRunMatrix(ctx context.Context, jobs []database.GetProvisionerJobsByIDsWithQueuePositionRow) (codersdk.TemplateVersionDryRunMatrix, error) {
+	now := dbtime.Now()
+	matrix := codersdk.TemplateVersionDryRunMatrix{
+		Results: make([]codersdk.TemplateVersionDryRunMatrixResult, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		daemons, err := api.templateVersionDryRunMatrixDaemons(ctx, job.ProvisionerJob.OrganizationID, job.ProvisionerJob.Tags)
+		if err != nil {
+			return codersdk.TemplateVersionDryRunMatrix{}, err
+		}
+
+		result := codersdk.TemplateVersionDryRunMatrixResult{
+			Job:                 convertProvisionerJob(job),
+			MatchedProvisioners: db2sdk.MatchedProvisioners(daemons, now, provisionerdserver.StaleInterval),
+			ProvisionerVersions: []string{},
+		}
+		for _, daemon := range daemons {
+			if daemon.Version != "" && !slices.Contains(result.ProvisionerVersions, daemon.Version) {
+				result.ProvisionerVersions = append(result.ProvisionerVersions, daemon.Version)
+			}
+			if job.ProvisionerJob.WorkerID.Valid && daemon.ID == job.ProvisionerJob.WorkerID.UUID {
+				result.WorkerVersion = daemon.Version
+				result.WorkerAPIVersion = daemon.APIVersion
+			}
+		}
+		sort.Strings(result.ProvisionerVersions)
+
+		switch result.Job.Status {
+		case codersdk.ProvisionerJobSucceeded:
+			result.Compatible = ptr.Ref(true)
+		case codersdk.ProvisionerJobFailed:
+			result.Compatible = ptr.Ref(false)
+		}
+		matrix.Results = append(matrix.Results, result)
+	}
+	return matrix, nil
+}
+
+func (api *API) templateVersionDryRunMatrixDaemons(ctx context.Context, organizationID uuid.UUID, tags database.StringMap) ([]database.ProvisionerDaemon, error) {
+	// nolint:gocritic // The user may not have permissions to read all
+	// provisioner daemons in the org.
+	daemons, err := api.Database.GetProvisionerDaemonsByOrganization(dbauthz.AsSystemReadProvisionerDaemons(ctx), database.GetProvisionerDaemonsByOrganizationParams{
+		OrganizationID: organizationID,
+		WantTags:       tags,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get provisioner daemons: %w", err)
+	}
+	return daemons, nil
+}
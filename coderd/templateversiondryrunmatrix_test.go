@@ -0,0 +1,67 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionDryRunMatrix(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// A dry-run is run on each set of tags of the provisioner daemons.
+		matrix, err := client.CreateTemplateVersionDryRunMatrix(ctx, version.ID, codersdk.CreateTemplateVersionDryRunMatrixRequest{
+			WorkspaceName: "test",
+		})
+		require.NoError(t, err)
+		require.Len(t, matrix.Results, 1)
+		require.Equal(t, 1, matrix.Results[0].MatchedProvisioners.Count)
+		require.Len(t, matrix.Results[0].ProvisionerVersions, 1)
+		jobID := matrix.Results[0].Job.ID
+
+		require.Eventually(t, func() bool {
+			matrix, err = client.TemplateVersionDryRunMatrix(ctx, version.ID, []uuid.UUID{jobID})
+			if !assert.NoError(t, err) {
+				return false
+			}
+			return matrix.Results[0].Compatible != nil
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.True(t, *matrix.Results[0].Compatible)
+		require.Equal(t, matrix.Results[0].ProvisionerVersions[0], matrix.Results[0].WorkerVersion)
+		require.NotEmpty(t, matrix.Results[0].WorkerAPIVersion)
+	})
+
+	t.Run("NoMatchingProvisioners", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		matrix, err := client.CreateTemplateVersionDryRunMatrix(ctx, version.ID, codersdk.CreateTemplateVersionDryRunMatrixRequest{
+			WorkspaceName:   "test",
+			ProvisionerTags: []map[string]string{{"terraform": "1.99"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, matrix.Results, 1)
+		require.Equal(t, "1.99", matrix.Results[0].Job.Tags["terraform"])
+		require.Zero(t, matrix.Results[0].MatchedProvisioners.Count)
+		require.Empty(t, matrix.Results[0].ProvisionerVersions)
+		require.Nil(t, matrix.Results[0].Compatible)
+	})
+}
@@ -0,0 +1,304 @@
+package coderd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/archive"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// templateVersionFileLanguages maps file extensions to the syntax
+// highlighting hint returned for template version files.
+var templateVersionFileLanguages = map[string]string{
+	".tf":     "hcl",
+	".tfvars": "hcl",
+	".tftpl":  "hcl",
+	".hcl":    "hcl",
+	".md":     "markdown",
+	".sh":     "shell",
+	".bash":   "shell",
+	".ps1":    "powershell",
+	".json":   "json",
+	".yaml":   "yaml",
+	".yml":    "yaml",
+	".toml":   "toml",
+	".py":     "python",
+	".go":     "go",
+	".js":     "javascript",
+	".ts":     "typescript",
+	".xml":    "xml",
+}
+
+// templateVersionFileNameLanguages maps well-known file names without a
+// meaningful extension to their syntax highlighting hint.
+var templateVersionFileNameLanguages = map[string]string{
+	"Dockerfile": "dockerfile",
+	"Makefile":   "makefile",
+}
+
+// templateVersionSourceEntry is a file or directory of a template version
+// source archive.
+type templateVersionSourceEntry struct {
+	codersdk.TemplateVersionFile
+	data []byte
+}
+
+// @Summary List template version files
+// @ID list-template-version-files
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateVersionFile
+// @Router /api/v2/templateversions/{templateversion}/files [get]
+func (api *API) templateVersionFiles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entries, ok := api.templateVersionSource(rw, r)
+	if !ok {
+		return
+	}
+
+	files := make([]codersdk.TemplateVersionFile, 0, len(entries))
+	for _, entry := range entries {
+		files = append(files, entry.TemplateVersionFile)
+	}
+	slices.SortFunc(files, func(a, b codersdk.TemplateVersionFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	data, err := json.Marshal(files)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error encoding template version files.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	serveTemplateVersionContent(rw, r, "application/json", data)
+}
+
+// @Summary Get template version file
+// @ID get-template-version-file
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param path path string true "Path of the file relative to the root of the template source"
+// @Success 200
+// @Success 304
+// @Router /api/v2/templateversions/{templateversion}/files/{path} [get]
+func (api *API) templateVersionFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	name, ok := cleanTemplateVersionFilePath(chi.URLParam(r, "*"))
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid file path.",
+			Detail:  "The path must be relative to the root of the template source.",
+		})
+		return
+	}
+
+	entries, ok := api.templateVersionSource(rw, r)
+	if !ok {
+		return
+	}
+	entry, ok := entries[name]
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("File %q does not exist in the template version.", name),
+		})
+		return
+	}
+	if entry.Type == codersdk.TemplateVersionFileTypeDirectory {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is a directory.", name),
+			Detail:  "List the files of the template version to browse directories.",
+		})
+		return
+	}
+
+	serveTemplateVersionContent(rw, r, entry.ContentType, entry.data)
+}
+
+// templateVersionSource reads the source archive of the template version in
+// the request. Reading the source requires permission to read the file or to
+// update a template that uses it.
+func (api *API) templateVersionSource(rw http.ResponseWriter, r *http.Request) (map[string]templateVersionSourceEntry, bool) {
+	var (
+		ctx             = r.Context()
+		templateVersion = httpmw.TemplateVersionParam(r)
+	)
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	file, err := api.Database.GetFileByID(ctx, job.FileID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return nil, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version source.",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+
+	entries, err := readTemplateVersionSource(file)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading template version source.",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	return entries, true
+}
+
+// readTemplateVersionSource indexes the files and directories of a source
+// archive by path. Directories that only exist implicitly as the parent of a
+// file are included. Entries that are neither regular files nor directories,
+// or that escape the root of the archive, are skipped.
+func readTemplateVersionSource(file database.File) (map[string]templateVersionSourceEntry, error) {
+	data := file.Data
+	if file.Mimetype == zipMimeType || file.Mimetype == windowsZipMimeType {
+		zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, xerrors.Errorf("read zip archive: %w", err)
+		}
+		data, err = archive.CreateTarFromZip(zipReader, HTTPFileMaxBytes)
+		if err != nil {
+			return nil, xerrors.Errorf("convert zip archive: %w", err)
+		}
+	}
+
+	entries := map[string]templateVersionSourceEntry{}
+	addDirectory := func(name string) {
+		for ; name != "."; name = path.Dir(name) {
+			if _, ok := entries[name]; ok {
+				return
+			}
+			entries[name] = templateVersionSourceEntry{
+				TemplateVersionFile: codersdk.TemplateVersionFile{
+					Path: name,
+					Type: codersdk.TemplateVersionFileTypeDirectory,
+				},
+			}
+		}
+	}
+
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("read tar archive: %w", err)
+		}
+		name, ok := cleanTemplateVersionFilePath(header.Name)
+		if !ok {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			addDirectory(name)
+		case tar.TypeReg:
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, xerrors.Errorf("read %s: %w", name, err)
+			}
+			addDirectory(path.Dir(name))
+			language := templateVersionFileLanguage(name)
+			entries[name] = templateVersionSourceEntry{
+				TemplateVersionFile: codersdk.TemplateVersionFile{
+					Path:        name,
+					Type:        codersdk.TemplateVersionFileTypeFile,
+					Size:        int64(len(content)),
+					ContentType: templateVersionFileContentType(language, content),
+					Language:    language,
+				},
+				data: content,
+			}
+		}
+	}
+	return entries, nil
+}
+
+// cleanTemplateVersionFilePath normalizes a path of a template version source
+// archive. It returns false for the root and for paths that escape it.
+func cleanTemplateVersionFilePath(name string) (string, bool) {
+	name = path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimPrefix(name, "/")
+	if name == "" || name == "." {
+		return "", false
+	}
+	return name, true
+}
+
+// templateVersionFileLanguage returns the syntax highlighting hint of a file
+// based on its name.
+func templateVersionFileLanguage(name string) string {
+	base := path.Base(name)
+	if language, ok := templateVersionFileNameLanguages[base]; ok {
+		return language
+	}
+	return templateVersionFileLanguages[strings.ToLower(path.Ext(base))]
+}
+
+// templateVersionFileContentType returns the media type a file is served
+// with. Template sources are user-provided, so files are only ever served as
+// inert text or as an opaque download: never as HTML, SVG or other content a
+// browser would render.
+func templateVersionFileContentType(language string, content []byte) string {
+	switch {
+	case language == "json":
+		return "application/json"
+	case language == "markdown":
+		return "text/markdown; charset=utf-8"
+	case language != "",
+		strings.HasPrefix(http.DetectContentType(content), "text/") && utf8.Valid(content):
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// serveTemplateVersionContent writes content with an ETag derived from its
+// hash, so clients can cache template sources and revalidate them with
+// If-None-Match.
+func serveTemplateVersionContent(rw http.ResponseWriter, r *http.Request, contentType string, content []byte) {
+	hash := sha256.Sum256(content)
+	rw.Header().Set("ETag", fmt.Sprintf("%q", hex.EncodeToString(hash[:])))
+	rw.Header().Set("Cache-Control", "private, no-cache")
+	rw.Header().Set("Content-Type", contentType)
+	http.ServeContent(rw, r, "", time.Time{}, bytes.NewReader(content))
+}
@@ -0,0 +1,100 @@
+package coderd_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionFiles(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, echo.WithExtraFiles(map[string][]byte{
+		"main.tf":          []byte(`resource "null_resource" "example" {}`),
+		"README.md":        []byte("# Example"),
+		"scripts/setup.sh": []byte("#!/bin/sh\necho hello\n"),
+	}))
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+	t.Run("Tree", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		files, err := client.TemplateVersionFiles(ctx, version.ID)
+		require.NoError(t, err)
+
+		byPath := map[string]codersdk.TemplateVersionFile{}
+		for _, file := range files {
+			byPath[file.Path] = file
+		}
+		require.Equal(t, codersdk.TemplateVersionFileTypeDirectory, byPath["scripts"].Type)
+		require.Equal(t, codersdk.TemplateVersionFile{
+			Path:        "scripts/setup.sh",
+			Type:        codersdk.TemplateVersionFileTypeFile,
+			Size:        int64(len("#!/bin/sh\necho hello\n")),
+			ContentType: "text/plain; charset=utf-8",
+			Language:    "shell",
+		}, byPath["scripts/setup.sh"])
+		require.Equal(t, "hcl", byPath["main.tf"].Language)
+		require.Equal(t, "markdown", byPath["README.md"].Language)
+	})
+
+	t.Run("Contents", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		data, contentType, err := client.TemplateVersionFile(ctx, version.ID, "scripts/setup.sh")
+		require.NoError(t, err)
+		require.Equal(t, "#!/bin/sh\necho hello\n", string(data))
+		require.Equal(t, "text/plain; charset=utf-8", contentType)
+	})
+
+	t.Run("ETag", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/templateversions/"+version.ID.String()+"/files/main.tf", nil)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		etag := res.Header.Get("ETag")
+		require.NotEmpty(t, etag)
+
+		res, err = client.Request(ctx, http.MethodGet, "/api/v2/templateversions/"+version.ID.String()+"/files/main.tf", nil, func(r *http.Request) {
+			r.Header.Set("If-None-Match", etag)
+		})
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusNotModified, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, _, err := client.TemplateVersionFile(ctx, version.ID, "missing.tf")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, _, err := client.TemplateVersionFile(ctx, version.ID, "scripts")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
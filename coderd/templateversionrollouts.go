@@ -0,0 +1,374 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxTemplateVersionRolloutStages caps the number of stages of a rollout.
+const maxTemplateVersionRolloutStages = 10
+
+// @Summary Create template version rollout
+// @Description Promotes a template version in stages. Builds of workspaces in
+// @Description the cohort of the current stage that use the active version
+// @Description get the rolled out version instead. Each stage is observed for
+// @Description the soak duration before the rollout continues, and the
+// @Description version is promoted after the last stage. The rollout is rolled
+// @Description back once the failure rate of the start builds of the version
+// @Description in a stage exceeds the maximum.
+// @ID create-template-version-rollout
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateTemplateVersionRolloutRequest true "Create rollout request"
+// @Success 201 {object} codersdk.TemplateVersionRollout
+// @Router /api/v2/templates/{template}/rollouts [post]
+func (api *API) postTemplateVersionRollout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		apiKey   = httpmw.APIKey(r)
+	)
+
+	var req codersdk.CreateTemplateVersionRolloutRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if validations := validateTemplateVersionRollout(req); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid rollout.",
+			Validations: validations,
+		})
+		return
+	}
+
+	version, err := api.Database.GetTemplateVersionByID(ctx, req.TemplateVersionID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Template version not found.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if version.TemplateID.UUID != template.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version doesn't belong to the specified template.",
+		})
+		return
+	}
+	if version.ID == template.ActiveVersionID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is already the active version.",
+		})
+		return
+	}
+	if version.Archived {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is archived.",
+		})
+		return
+	}
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only versions that have been built successfully can be rolled out.",
+			Detail:  fmt.Sprintf("Attempted to roll out a version with a %s build", job.JobStatus),
+		})
+		return
+	}
+	if !api.templateVersionScanAllowsPromotion(ctx, rw, version.ID) {
+		return
+	}
+
+	var groupID uuid.NullUUID
+	if req.GroupID != nil {
+		group, err := api.Database.GetGroupByID(ctx, *req.GroupID)
+		if err != nil && !httpapi.Is404Error(err) {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if err != nil || group.OrganizationID != template.OrganizationID {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid rollout.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "group_id",
+					Detail: "Group not found in the organization of the template.",
+				}},
+			})
+			return
+		}
+		groupID = uuid.NullUUID{UUID: group.ID, Valid: true}
+	}
+
+	stages := req.Stages
+	if stages == nil {
+		stages = []int32{}
+	}
+	rollout, err := api.Database.InsertTemplateVersionRollout(ctx, database.InsertTemplateVersionRolloutParams{
+		ID:                uuid.New(),
+		TemplateID:        template.ID,
+		TemplateVersionID: version.ID,
+		PreviousVersionID: template.ActiveVersionID,
+		GroupID:           groupID,
+		Stages:            stages,
+		SoakDuration:      int64(time.Duration(req.SoakDurationMillis) * time.Millisecond),
+		MaxFailureRate:    req.MaxFailureRate,
+		MinBuilds:         req.MinBuilds,
+		CreatedBy:         apiKey.UserID,
+		CreatedAt:         dbtime.Time(api.Clock.Now()),
+	})
+	if database.IsUniqueViolation(err, database.UniqueTemplateVersionRolloutsInProgressIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The template already has a rollout in progress.",
+		})
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertTemplateVersionRollout(rollout, database.GetTemplateVersionRolloutBuildStatsRow{}))
+}
+
+// @Summary Get template version rollouts
+// @ID get-template-version-rollouts
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateVersionRollout
+// @Router /api/v2/templates/{template}/rollouts [get]
+func (api *API) templateVersionRollouts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	rollouts, err := api.Database.GetTemplateVersionRolloutsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	sdk := make([]codersdk.TemplateVersionRollout, 0, len(rollouts))
+	for _, rollout := range rollouts {
+		converted, err := api.convertTemplateVersionRolloutWithStats(ctx, rollout)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		sdk = append(sdk, converted)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, sdk)
+}
+
+// @Summary Get template version rollout
+// @ID get-template-version-rollout
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param rollout path string true "Rollout ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionRollout
+// @Router /api/v2/templates/{template}/rollouts/{rollout} [get]
+func (api *API) templateVersionRollout(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rollout, ok := api.templateVersionRolloutParam(rw, r)
+	if !ok {
+		return
+	}
+
+	converted, err := api.convertTemplateVersionRolloutWithStats(ctx, rollout)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Abort template version rollout
+// @Description Aborts an in-progress rollout. The active version of the
+// @Description template is left unchanged.
+// @ID abort-template-version-rollout
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param rollout path string true "Rollout ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionRollout
+// @Router /api/v2/templates/{template}/rollouts/{rollout}/abort [post]
+func (api *API) postAbortTemplateVersionRollout(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rollout, ok := api.templateVersionRolloutParam(rw, r)
+	if !ok {
+		return
+	}
+
+	aborted, err := api.Database.UpdateTemplateVersionRolloutStatus(ctx, database.UpdateTemplateVersionRolloutStatusParams{
+		ID:            rollout.ID,
+		Status:        database.TemplateVersionRolloutStatusAborted,
+		StatusMessage: "The rollout was aborted.",
+		UpdatedAt:     dbtime.Time(api.Clock.Now()),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only rollouts in progress can be aborted.",
+		})
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersionRollout(aborted, database.GetTemplateVersionRolloutBuildStatsRow{}))
+}
+
+// templateVersionRolloutParam returns the rollout of the template in the
+// URL. False is returned if a response was written.
+func (api *API) templateVersionRolloutParam(rw http.ResponseWriter, r *http.Request) (database.TemplateVersionRollout, bool) {
+	template := httpmw.TemplateParam(r)
+	rolloutID, ok := httpmw.ParseUUIDParam(rw, r, "rollout")
+	if !ok {
+		return database.TemplateVersionRollout{}, false
+	}
+
+	rollout, err := api.Database.GetTemplateVersionRolloutByID(r.Context(), rolloutID)
+	if httpapi.Is404Error(err) || (err == nil && rollout.TemplateID != template.ID) {
+		httpapi.ResourceNotFound(rw)
+		return database.TemplateVersionRollout{}, false
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return database.TemplateVersionRollout{}, false
+	}
+	return rollout, true
+}
+
+func validateTemplateVersionRollout(req codersdk.CreateTemplateVersionRolloutRequest) []codersdk.ValidationError {
+	var validations []codersdk.ValidationError
+	if len(req.Stages) == 0 && req.GroupID == nil {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "stages",
+			Detail: "A rollout must have at least one stage or a group.",
+		})
+	}
+	if len(req.Stages) > maxTemplateVersionRolloutStages {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "stages",
+			Detail: fmt.Sprintf("A rollout may have at most %d stages.", maxTemplateVersionRolloutStages),
+		})
+	}
+	for i, percentage := range req.Stages {
+		if percentage <= 0 || percentage >= 100 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "stages",
+				Detail: fmt.Sprintf("Stage %d must target between 1 and 99 percent of workspaces, the version is promoted after the last stage.", i+1),
+			})
+			break
+		}
+		if i > 0 && percentage <= req.Stages[i-1] {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "stages",
+				Detail: "Stages must target an increasing percentage of workspaces.",
+			})
+			break
+		}
+	}
+	if req.SoakDurationMillis <= 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "soak_duration_ms",
+			Detail: "Must be a positive duration.",
+		})
+	}
+	if req.MaxFailureRate < 0 || req.MaxFailureRate > 1 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "max_failure_rate",
+			Detail: "Must be between 0 and 1.",
+		})
+	}
+	if req.MinBuilds < 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "min_builds",
+			Detail: "Must not be negative.",
+		})
+	}
+	return validations
+}
+
+// convertTemplateVersionRolloutWithStats converts a rollout along with the
+// build stats of its current stage, if it is in progress.
+func (api *API) convertTemplateVersionRolloutWithStats(ctx context.Context, rollout database.TemplateVersionRollout) (codersdk.TemplateVersionRollout, error) {
+	var stats database.GetTemplateVersionRolloutBuildStatsRow
+	if rollout.Status == database.TemplateVersionRolloutStatusInProgress {
+		var err error
+		stats, err = api.Database.GetTemplateVersionRolloutBuildStats(ctx, database.GetTemplateVersionRolloutBuildStatsParams{
+			TemplateVersionID: rollout.TemplateVersionID,
+			Since:             rollout.StageStartedAt,
+		})
+		if err != nil {
+			return codersdk.TemplateVersionRollout{}, err
+		}
+	}
+	return convertTemplateVersionRollout(rollout, stats), nil
+}
+
+func convertTemplateVersionRollout(rollout database.TemplateVersionRollout, stats database.GetTemplateVersionRolloutBuildStatsRow) codersdk.TemplateVersionRollout {
+	sdk := codersdk.TemplateVersionRollout{
+		ID:                 rollout.ID,
+		TemplateID:         rollout.TemplateID,
+		TemplateVersionID:  rollout.TemplateVersionID,
+		PreviousVersionID:  rollout.PreviousVersionID,
+		Stages:             rollout.Stages,
+		CurrentStage:       rollout.CurrentStage,
+		SoakDurationMillis: time.Duration(rollout.SoakDuration).Milliseconds(),
+		MaxFailureRate:     rollout.MaxFailureRate,
+		MinBuilds:          rollout.MinBuilds,
+		Status:             codersdk.TemplateVersionRolloutStatus(rollout.Status),
+		StatusMessage:      rollout.StatusMessage,
+		CreatedBy:          rollout.CreatedBy,
+		CreatedAt:          rollout.CreatedAt,
+		UpdatedAt:          rollout.UpdatedAt,
+		StageStartedAt:     rollout.StageStartedAt,
+		Builds:             stats.Builds,
+		FailedBuilds:       stats.FailedBuilds,
+	}
+	if sdk.Stages == nil {
+		sdk.Stages = []int32{}
+	}
+	if rollout.GroupID.Valid {
+		sdk.GroupID = &rollout.GroupID.UUID
+	}
+	if rollout.CompletedAt.Valid {
+		sdk.CompletedAt = &rollout.CompletedAt.Time
+	}
+	return sdk
+}
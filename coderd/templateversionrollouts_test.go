@@ -0,0 +1,134 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionRollouts(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	// setup returns a template and a new version of it that can be rolled
+	// out.
+	setup := func(t *testing.T) (codersdk.Template, codersdk.TemplateVersion) {
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		next := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, next.ID)
+		return template, next
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template, next := setup(t)
+
+		rollout, err := client.CreateTemplateVersionRollout(ctx, template.ID, codersdk.CreateTemplateVersionRolloutRequest{
+			TemplateVersionID:  next.ID,
+			Stages:             []int32{10, 50},
+			SoakDurationMillis: time.Hour.Milliseconds(),
+			MaxFailureRate:     0.1,
+			MinBuilds:          5,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionRolloutStatusInProgress, rollout.Status)
+		require.Equal(t, template.ActiveVersionID, rollout.PreviousVersionID)
+		require.Equal(t, []int32{10, 50}, rollout.Stages)
+		require.EqualValues(t, 0, rollout.CurrentStage)
+
+		// Only one rollout can be in progress.
+		_, err = client.CreateTemplateVersionRollout(ctx, template.ID, codersdk.CreateTemplateVersionRolloutRequest{
+			TemplateVersionID:  next.ID,
+			Stages:             []int32{50},
+			SoakDurationMillis: time.Hour.Milliseconds(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		rollouts, err := client.TemplateVersionRollouts(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, rollouts, 1)
+		require.Equal(t, rollout.ID, rollouts[0].ID)
+
+		got, err := client.TemplateVersionRollout(ctx, template.ID, rollout.ID)
+		require.NoError(t, err)
+		require.Equal(t, rollout.ID, got.ID)
+
+		aborted, err := client.AbortTemplateVersionRollout(ctx, template.ID, rollout.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionRolloutStatusAborted, aborted.Status)
+		require.NotNil(t, aborted.CompletedAt)
+
+		// The active version is unchanged.
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, rollout.PreviousVersionID, template.ActiveVersionID)
+
+		_, err = client.AbortTemplateVersionRollout(ctx, template.ID, rollout.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template, next := setup(t)
+
+		// The Everyone group has the ID of the organization.
+		groupID := user.OrganizationID
+		rollout, err := client.CreateTemplateVersionRollout(ctx, template.ID, codersdk.CreateTemplateVersionRolloutRequest{
+			TemplateVersionID:  next.ID,
+			GroupID:            &groupID,
+			SoakDurationMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, &groupID, rollout.GroupID)
+		require.Empty(t, rollout.Stages)
+
+		unknown := uuid.New()
+		_, err = client.CreateTemplateVersionRollout(ctx, template.ID, codersdk.CreateTemplateVersionRolloutRequest{
+			TemplateVersionID:  next.ID,
+			GroupID:            &unknown,
+			SoakDurationMillis: time.Hour.Milliseconds(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template, next := setup(t)
+
+		for _, req := range []codersdk.CreateTemplateVersionRolloutRequest{
+			// Stages must be ascending.
+			{TemplateVersionID: next.ID, Stages: []int32{50, 10}, SoakDurationMillis: 1000},
+			// Stages must be below 100.
+			{TemplateVersionID: next.ID, Stages: []int32{100}, SoakDurationMillis: 1000},
+			// Either stages or a group is required.
+			{TemplateVersionID: next.ID, SoakDurationMillis: 1000},
+			{TemplateVersionID: next.ID, Stages: []int32{10}, SoakDurationMillis: 1000, MaxFailureRate: 2},
+			// The active version can't be rolled out.
+			{TemplateVersionID: template.ActiveVersionID, Stages: []int32{10}, SoakDurationMillis: 1000},
+		} {
+			_, err := client.CreateTemplateVersionRollout(ctx, template.ID, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+}
@@ -13,6 +13,7 @@ import (
 	stdslog "log/slog"
 	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -28,6 +29,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/deprecation"
 	"github.com/coder/coder/v2/coderd/dynamicparameters"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -167,6 +169,23 @@ func (api *API) patchTemplateVersion(rw http.ResponseWriter, r *http.Request) {
 			return xerrors.Errorf("error on patching template version: %v", err)
 		}
 
+		if params.DeprecationMessage != nil {
+			deprecationParams := database.UpdateTemplateVersionDeprecationByIDParams{
+				ID:         updateParams.ID,
+				UpdatedAt:  updateParams.UpdatedAt,
+				Deprecated: *params.DeprecationMessage,
+			}
+			// A cutoff without a deprecation message has no effect, so it
+			// is dropped when the deprecation is cleared.
+			if params.DeprecationCutoff != nil && *params.DeprecationMessage != "" {
+				deprecationParams.DeprecationCutoff = sql.NullTime{Time: *params.DeprecationCutoff, Valid: true}
+			}
+			err = tx.UpdateTemplateVersionDeprecationByID(ctx, deprecationParams)
+			if err != nil {
+				return xerrors.Errorf("error on updating template version deprecation: %v", err)
+			}
+		}
+
 		updatedTemplateVersion, err = tx.GetTemplateVersionByID(ctx, updateParams.ID)
 		if err != nil {
 			return xerrors.Errorf("error on fetching patched template version: %v", err)
@@ -323,6 +342,153 @@ func (api *API) templateVersionRichParameters(rw http.ResponseWriter, r *http.Re
 	httpapi.Write(ctx, rw, http.StatusOK, templateVersionParameters)
 }
 
+// @Summary Get JSON schema of rich parameters by template version
+// @ID get-json-schema-of-rich-parameters-by-template-version
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionParametersSchema
+// @Router /api/v2/templateversions/{templateversion}/parameters/schema [get]
+func (api *API) templateVersionParametersSchema(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !job.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusTooEarly, codersdk.Response{
+			Message: "Template version job has not finished",
+		})
+		return
+	}
+	dbTemplateVersionParameters, err := api.Database.GetTemplateVersionParameters(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	templateVersionParameters, err := db2sdk.TemplateVersionParameters(dbTemplateVersionParameters)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template version parameter.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	schema, err := templateVersionParametersJSONSchema(templateVersion.Name, templateVersionParameters)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template version parameters to a JSON schema.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, schema)
+}
+
+// templateVersionParametersJSONSchema describes rich parameters as a JSON
+// Schema document. Options and defaults are decoded from the string encoding
+// of parameter values to the JSON type of the parameter.
+func templateVersionParametersJSONSchema(title string, params []codersdk.TemplateVersionParameter) (codersdk.TemplateVersionParametersSchema, error) {
+	schema := codersdk.TemplateVersionParametersSchema{
+		Schema:     "https://json-schema.org/draft/2020-12/schema",
+		Title:      title,
+		Type:       "object",
+		Properties: make(map[string]codersdk.TemplateVersionParameterSchema, len(params)),
+		Required:   []string{},
+	}
+	for _, param := range params {
+		property := codersdk.TemplateVersionParameterSchema{
+			Title:       param.DisplayName,
+			Description: param.DescriptionPlaintext,
+			WriteOnly:   param.Sensitive,
+		}
+		switch codersdk.OptionType(param.Type) {
+		case codersdk.OptionTypeString:
+			property.Type = "string"
+			property.Pattern = param.ValidationRegex
+		case codersdk.OptionTypeNumber:
+			property.Type = "number"
+			property.Minimum = param.ValidationMin
+			property.Maximum = param.ValidationMax
+		case codersdk.OptionTypeBoolean:
+			property.Type = "boolean"
+		case codersdk.OptionTypeListString:
+			property.Type = "array"
+			property.Items = &codersdk.TemplateVersionParameterSchemaItems{Type: "string"}
+		default:
+			return codersdk.TemplateVersionParametersSchema{}, xerrors.Errorf("parameter %q has unsupported type %q", param.Name, param.Type)
+		}
+
+		for _, option := range param.Options {
+			// The options of a list are the values its elements may take.
+			if property.Items != nil {
+				property.Items.Enum = append(property.Items.Enum, option.Value)
+				property.UniqueItems = true
+				continue
+			}
+			value, err := parameterJSONValue(param.Type, option.Value)
+			if err != nil {
+				return codersdk.TemplateVersionParametersSchema{}, xerrors.Errorf("option %q of parameter %q: %w", option.Name, param.Name, err)
+			}
+			property.Enum = append(property.Enum, value)
+		}
+
+		// Parameters are required exactly when they have no default.
+		if param.Required {
+			schema.Required = append(schema.Required, param.Name)
+		} else {
+			value, err := parameterJSONValue(param.Type, param.DefaultValue)
+			if err != nil {
+				return codersdk.TemplateVersionParametersSchema{}, xerrors.Errorf("default of parameter %q: %w", param.Name, err)
+			}
+			property.Default = value
+		}
+		schema.Properties[param.Name] = property
+	}
+	return schema, nil
+}
+
+// parameterJSONValue decodes the string encoding of a parameter value.
+func parameterJSONValue(typ string, value string) (any, error) {
+	switch codersdk.OptionType(typ) {
+	case codersdk.OptionTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, xerrors.Errorf("parse number %q: %w", value, err)
+		}
+		return number, nil
+	case codersdk.OptionTypeBoolean:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, xerrors.Errorf("parse bool %q: %w", value, err)
+		}
+		return boolean, nil
+	case codersdk.OptionTypeListString:
+		list := []string{}
+		if value == "" {
+			return list, nil
+		}
+		err := json.Unmarshal([]byte(value), &list)
+		if err != nil {
+			return nil, xerrors.Errorf("parse list %q: %w", value, err)
+		}
+		return list, nil
+	default:
+		return value, nil
+	}
+}
+
 // @Summary Get external auth by template version
 // @ID get-external-auth-by-template-version
 // @Security CoderSessionToken
@@ -492,6 +658,44 @@ func (api *API) templateVersionVariables(rw http.ResponseWriter, r *http.Request
 	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersionVariables(dbTemplateVersionVariables))
 }
 
+// @Summary Get template version scan
+// @ID get-template-version-scan
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionScan
+// @Router /api/v2/templateversions/{templateversion}/scan [get]
+func (api *API) templateVersionScan(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+
+	scan, err := api.Database.GetTemplateVersionScanByTemplateVersionID(ctx, templateVersion.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Template version has not been scanned.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version scan.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	sdkScan, err := convertTemplateVersionScan(scan)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template version scan.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sdkScan)
+}
+
 // @Summary Create template version dry-run
 // @ID create-template-version-dry-run
 // @Security CoderSessionToken
@@ -537,8 +741,32 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	richParameterValues := make([]database.WorkspaceBuildParameter, len(req.RichParameterValues))
-	for i, v := range req.RichParameterValues {
+	// Copy tags from the previous run.
+	provisionerJob, err := api.insertTemplateVersionDryRunJob(ctx, api.Database, apiKey.UserID, templateVersion, job, req.WorkspaceName, req.RichParameterValues, job.Tags)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	err = provisionerjobs.PostJob(api.Pubsub, provisionerJob)
+	if err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertProvisionerJob(database.GetProvisionerJobsByIDsWithQueuePositionRow{
+		ProvisionerJob: provisionerJob,
+		QueuePosition:  0,
+	}))
+}
+
+// insertTemplateVersionDryRunJob inserts a dry-run job of the template version
+// imported by importJob, to be run by the provisioner daemons matching tags.
+func (api *API) insertTemplateVersionDryRunJob(ctx context.Context, db database.Store, initiatorID uuid.UUID, templateVersion database.TemplateVersion, importJob database.ProvisionerJob, workspaceName string, parameters []codersdk.WorkspaceBuildParameter, tags database.StringMap) (database.ProvisionerJob, error) {
+	richParameterValues := make([]database.WorkspaceBuildParameter, len(parameters))
+	for i, v := range parameters {
 		richParameterValues[i] = database.WorkspaceBuildParameter{
 			WorkspaceBuildID: uuid.Nil,
 			Name:             v.Name,
@@ -550,41 +778,30 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 	// request.
 	input, err := json.Marshal(provisionerdserver.TemplateVersionDryRunJob{
 		TemplateVersionID:   templateVersion.ID,
-		WorkspaceName:       req.WorkspaceName,
+		WorkspaceName:       workspaceName,
 		RichParameterValues: richParameterValues,
 	})
 	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error unmarshalling provisioner job.",
-			Detail:  err.Error(),
-		})
-		return
+		return database.ProvisionerJob{}, xerrors.Errorf("marshal job input: %w", err)
 	}
 
 	metadataRaw, err := json.Marshal(tracing.MetadataFromContext(ctx))
 	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error unmarshalling metadata.",
-			Detail:  err.Error(),
-		})
-		return
+		return database.ProvisionerJob{}, xerrors.Errorf("marshal trace metadata: %w", err)
 	}
 
-	// Create a dry-run job
-	jobID := uuid.New()
-	provisionerJob, err := api.Database.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
-		ID:             jobID,
+	provisionerJob, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+		ID:             uuid.New(),
 		CreatedAt:      dbtime.Now(),
 		UpdatedAt:      dbtime.Now(),
 		OrganizationID: templateVersion.OrganizationID,
-		InitiatorID:    apiKey.UserID,
-		Provisioner:    job.Provisioner,
-		StorageMethod:  job.StorageMethod,
-		FileID:         job.FileID,
+		InitiatorID:    initiatorID,
+		Provisioner:    importJob.Provisioner,
+		StorageMethod:  importJob.StorageMethod,
+		FileID:         importJob.FileID,
 		Type:           database.ProvisionerJobTypeTemplateVersionDryRun,
 		Input:          input,
-		// Copy tags from the previous run.
-		Tags: job.Tags,
+		Tags:           tags,
 		TraceMetadata: pqtype.NullRawMessage{
 			Valid:      true,
 			RawMessage: metadataRaw,
@@ -592,22 +809,9 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		LogsOverflowed: false,
 	})
 	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error inserting provisioner job.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-	err = provisionerjobs.PostJob(api.Pubsub, provisionerJob)
-	if err != nil {
-		// Client probably doesn't care about this error, so just log it.
-		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+		return database.ProvisionerJob{}, xerrors.Errorf("insert provisioner job: %w", err)
 	}
-
-	httpapi.Write(ctx, rw, http.StatusCreated, convertProvisionerJob(database.GetProvisionerJobsByIDsWithQueuePositionRow{
-		ProvisionerJob: provisionerJob,
-		QueuePosition:  0,
-	}))
+	return provisionerJob, nil
 }
 
 // @Summary Get template version dry-run by job ID
@@ -1445,6 +1649,9 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	if !api.templateVersionScanAllowsPromotion(ctx, rw, version.ID) {
+		return
+	}
 
 	err = api.Database.InTx(func(store database.Store) error {
 		err = store.UpdateTemplateActiveVersionByID(ctx, database.UpdateTemplateActiveVersionByIDParams{
@@ -2016,6 +2223,8 @@ func convertTemplateVersion(version database.TemplateVersion, job codersdk.Provi
 		Warnings:            warnings,
 		MatchedProvisioners: matchedProvisioners,
 		HasExternalAgent:    version.HasExternalAgent.Bool,
+		DeprecationMessage:  version.Deprecated,
+		DeprecationCutoff:   deprecation.CutoffPtr(version.DeprecationCutoff),
 	}
 }
 
@@ -2057,3 +2266,79 @@ func (api *API) publishTemplateUpdate(ctx context.Context, templateID uuid.UUID)
 			slog.F("template_id", templateID), slog.Error(err))
 	}
 }
+
+func convertTemplateVersionScan(scan database.TemplateVersionScan) (codersdk.TemplateVersionScan, error) {
+	sdkScan := codersdk.TemplateVersionScan{
+		TemplateVersionID: scan.TemplateVersionID,
+		Status:            codersdk.TemplateVersionScanStatus(scan.Status),
+		Findings:          []codersdk.TemplateVersionScanFinding{},
+		Error:             scan.Error,
+		StartedAt:         scan.StartedAt,
+	}
+	if len(scan.Findings) > 0 {
+		if err := json.Unmarshal(scan.Findings, &sdkScan.Findings); err != nil {
+			return codersdk.TemplateVersionScan{}, xerrors.Errorf("unmarshal findings: %w", err)
+		}
+	}
+	if scan.CompletedAt.Valid {
+		sdkScan.CompletedAt = &scan.CompletedAt.Time
+	}
+	return sdkScan, nil
+}
+
+// templateVersionScanAllowsPromotion writes an error response and returns
+// false if the deployment blocks promotion on critical scan findings and the
+// template version has not passed its scan.
+func (api *API) templateVersionScanAllowsPromotion(ctx context.Context, rw http.ResponseWriter, templateVersionID uuid.UUID) bool {
+	if api.DeploymentValues.Provisioner.TemplateScannerURL.String() == "" ||
+		!api.DeploymentValues.Provisioner.TemplateScannerBlockCritical.Value() {
+		return true
+	}
+
+	scan, err := api.Database.GetTemplateVersionScanByTemplateVersionID(ctx, templateVersionID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only versions that have been scanned can be promoted.",
+			Detail:  "The template version scan has not started yet. Try again shortly.",
+		})
+		return false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version scan.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	sdkScan, err := convertTemplateVersionScan(scan)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template version scan.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+
+	switch sdkScan.Status {
+	case codersdk.TemplateVersionScanStatusRunning:
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only versions that have been scanned can be promoted.",
+			Detail:  "The template version scan is still running. Try again shortly.",
+		})
+		return false
+	case codersdk.TemplateVersionScanStatusFailed:
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only versions that have been scanned can be promoted.",
+			Detail:  fmt.Sprintf("The template version scan failed: %s", sdkScan.Error),
+		})
+		return false
+	}
+	if critical := sdkScan.CriticalFindings(); critical > 0 {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Versions with critical scan findings cannot be promoted.",
+			Detail:  fmt.Sprintf("The template version scan reported %d critical finding(s).", critical),
+		})
+		return false
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package coderd_test
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -25,10 +27,12 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
 	"github.com/coder/coder/v2/provisioner/echo"
@@ -1476,6 +1480,86 @@ func TestPatchActiveTemplateVersion(t *testing.T) {
 		require.Contains(t, apiErr.Detail, "pending")
 	})
 
+	t.Run("ScanBlocksCritical", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			DeploymentValues: coderdtest.DeploymentValues(t, func(dv *codersdk.DeploymentValues) {
+				require.NoError(t, dv.Provisioner.TemplateScannerURL.Set("http://scanner.invalid"))
+				dv.Provisioner.TemplateScannerBlockCritical = true
+			}),
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		version = coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Not scanned yet.
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = client.TemplateVersionScan(ctx, version.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		//nolint:gocritic // Scans are recorded by the system.
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		now := dbtime.Now()
+		_, err = db.ClaimTemplateVersionScans(sysCtx, database.ClaimTemplateVersionScansParams{
+			Now:            now,
+			CompletedAfter: now.Add(-time.Hour),
+			StaleBefore:    now.Add(-time.Hour),
+			MaxScans:       10,
+		})
+		require.NoError(t, err)
+		findings := []codersdk.TemplateVersionScanFinding{{
+			Severity: codersdk.TemplateVersionScanSeverityCritical,
+			RuleID:   "privileged-container",
+			Message:  "Container runs privileged.",
+		}}
+		err = db.UpdateTemplateVersionScanByTemplateVersionID(sysCtx, database.UpdateTemplateVersionScanByTemplateVersionIDParams{
+			TemplateVersionID: version.ID,
+			Status:            database.TemplateVersionScanStatusCompleted,
+			Findings:          must(json.Marshal(findings)),
+			CompletedAt:       sql.NullTime{Time: now, Valid: true},
+		})
+		require.NoError(t, err)
+
+		scan, err := client.TemplateVersionScan(ctx, version.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionScanStatusCompleted, scan.Status)
+		require.Equal(t, findings, scan.Findings)
+		require.NotNil(t, scan.CompletedAt)
+
+		err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		require.Contains(t, apiErr.Detail, "1 critical finding")
+
+		// Findings below critical don't block promotion.
+		findings[0].Severity = codersdk.TemplateVersionScanSeverityHigh
+		err = db.UpdateTemplateVersionScanByTemplateVersionID(sysCtx, database.UpdateTemplateVersionScanByTemplateVersionIDParams{
+			TemplateVersionID: version.ID,
+			Status:            database.TemplateVersionScanStatusCompleted,
+			Findings:          must(json.Marshal(findings)),
+			CompletedAt:       sql.NullTime{Time: now, Valid: true},
+		})
+		require.NoError(t, err)
+		err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		require.NoError(t, err)
+	})
+
 	t.Run("DoesNotBelong", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
@@ -2301,7 +2385,7 @@ func TestTemplateVersionVariables(t *testing.T) {
 func TestTemplateVersionPatch(t *testing.T) {
 	t.Parallel()
 
-	// Single instance shared across all 9 sub-tests. Each sub-test
+	// Single instance shared across all 10 sub-tests. Each sub-test
 	// creates its own template version(s) and template(s) with
 	// unique IDs so parallel execution is safe.
 	client := coderdtest.New(t, nil)
@@ -2469,6 +2553,36 @@ func TestTemplateVersionPatch(t *testing.T) {
 		})
 		require.Error(t, err)
 	})
+
+	t.Run("Deprecate the version", func(t *testing.T) {
+		t.Parallel()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		cutoff := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+		updatedVersion, err := client.UpdateTemplateVersion(ctx, version.ID, codersdk.PatchTemplateVersionRequest{
+			DeprecationMessage: ptr.Ref("Contains a broken image"),
+			DeprecationCutoff:  &cutoff,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Contains a broken image", updatedVersion.DeprecationMessage)
+		require.NotNil(t, updatedVersion.DeprecationCutoff)
+		assert.True(t, cutoff.Equal(*updatedVersion.DeprecationCutoff))
+		// Other fields are left untouched.
+		assert.Equal(t, version.Name, updatedVersion.Name)
+
+		// Clearing the message also clears the cutoff.
+		updatedVersion, err = client.UpdateTemplateVersion(ctx, version.ID, codersdk.PatchTemplateVersionRequest{
+			DeprecationMessage: ptr.Ref(""),
+			DeprecationCutoff:  &cutoff,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, updatedVersion.DeprecationMessage)
+		assert.Nil(t, updatedVersion.DeprecationCutoff)
+	})
 }
 
 func TestTemplateVersionParameters_Order(t *testing.T) {
@@ -2557,6 +2671,105 @@ func TestTemplateVersionParameters_Order(t *testing.T) {
 	require.Equal(t, thirdParameterName, templateRichParameters[4].Name)
 }
 
+func TestTemplateVersionParametersSchema(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionGraph: []*proto.Response{
+			{
+				Type: &proto.Response_Graph{
+					Graph: &proto.GraphComplete{
+						Parameters: []*proto.RichParameter{
+							{
+								Name:        "region",
+								DisplayName: "Region",
+								Description: "Region of the workspace.",
+								Type:        "string",
+								Options: []*proto.RichParameterOption{
+									{Name: "US", Value: "us"},
+									{Name: "EU", Value: "eu"},
+								},
+								DefaultValue: "us",
+							},
+							{
+								Name:            "cpu",
+								Type:            "number",
+								Required:        true,
+								ValidationMin:   ptr.Ref[int32](1),
+								ValidationMax:   ptr.Ref[int32](8),
+								ValidationError: "Choose between 1 and 8 CPUs.",
+							},
+							{
+								Name:         "dotfiles",
+								Type:         "bool",
+								DefaultValue: "true",
+							},
+							{
+								Name: "ides",
+								Type: "list(string)",
+								Options: []*proto.RichParameterOption{
+									{Name: "VS Code", Value: "vscode"},
+									{Name: "JetBrains", Value: "jetbrains"},
+								},
+								DefaultValue: `["vscode"]`,
+							},
+							{
+								Name:            "branch",
+								Type:            "string",
+								ValidationRegex: "^[a-z-]+$",
+								DefaultValue:    "main",
+								Sensitive:       true,
+							},
+						},
+					},
+				},
+			},
+		},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	schema, err := client.TemplateVersionParametersSchema(ctx, version.ID)
+	require.NoError(t, err)
+	require.Equal(t, "object", schema.Type)
+	require.Equal(t, version.Name, schema.Title)
+	require.False(t, schema.AdditionalProperties)
+	require.Equal(t, []string{"cpu"}, schema.Required)
+	require.Len(t, schema.Properties, 5)
+
+	region := schema.Properties["region"]
+	require.Equal(t, "string", region.Type)
+	require.Equal(t, "Region", region.Title)
+	require.Equal(t, "Region of the workspace.", region.Description)
+	require.Equal(t, []any{"us", "eu"}, region.Enum)
+	require.Equal(t, "us", region.Default)
+
+	cpu := schema.Properties["cpu"]
+	require.Equal(t, "number", cpu.Type)
+	require.Equal(t, ptr.Ref[int32](1), cpu.Minimum)
+	require.Equal(t, ptr.Ref[int32](8), cpu.Maximum)
+	require.Nil(t, cpu.Default)
+
+	dotfiles := schema.Properties["dotfiles"]
+	require.Equal(t, "boolean", dotfiles.Type)
+	require.Equal(t, true, dotfiles.Default)
+
+	ides := schema.Properties["ides"]
+	require.Equal(t, "array", ides.Type)
+	require.NotNil(t, ides.Items)
+	require.Equal(t, []string{"vscode", "jetbrains"}, ides.Items.Enum)
+	require.True(t, ides.UniqueItems)
+	require.Equal(t, []any{"vscode"}, ides.Default)
+
+	branch := schema.Properties["branch"]
+	require.Equal(t, "^[a-z-]+$", branch.Pattern)
+	require.True(t, branch.WriteOnly)
+}
+
 func TestTemplateArchiveVersions(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,223 @@
+package coderd
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	stdslog "log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/xerrors"
+
+	archivefs "github.com/coder/coder/v2/archive/fs"
+	"github.com/coder/coder/v2/coderd/dynamicparameters"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/preview"
+)
+
+// postValidateTemplateVersion parses and statically validates the source code
+// of a template without creating a template version or queueing a provisioner
+// job. The template is evaluated the same way as when a template version is
+// imported, so tags, parameters and presets are checked as well.
+//
+// @Summary Validate template version source code
+// @ID validate-template-version-source-code
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.ValidateTemplateVersionRequest true "Validate template version request"
+// @Success 200 {object} codersdk.ValidateTemplateVersionResponse
+// @Router /api/v2/organizations/{organization}/templateversions/validate [post]
+func (api *API) postValidateTemplateVersion(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		apiKey       = httpmw.APIKey(r)
+		organization = httpmw.OrganizationParam(r)
+		req          codersdk.ValidateTemplateVersionRequest
+	)
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// Validating a template version is the same permission as creating one.
+	if !api.Authorize(r, policy.ActionCreate, rbac.ResourceTemplate.InOrg(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	file, err := api.Database.GetFileByID(ctx, req.FileID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "File not found.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching file.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var files fs.FS
+	switch file.Mimetype {
+	case "application/x-tar":
+		files = archivefs.FromTarReader(bytes.NewBuffer(file.Data))
+	case "application/zip":
+		files, err = archivefs.FromZipReader(bytes.NewReader(file.Data), int64(len(file.Data)))
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid zip archive.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Unsupported file type.",
+			Detail:  fmt.Sprintf("Mimetype %q is not supported for template validation", file.Mimetype),
+		})
+		return
+	}
+
+	// Syntax errors are skipped when the module is evaluated, so the files are
+	// parsed on their own first to report them with their positions.
+	diags, err := parseTemplateFiles(files)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Unable to read template files.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if diags.HasErrors() {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.ValidateTemplateVersionResponse{
+			Valid:       false,
+			Diagnostics: convertTemplateVersionDiagnostics(diags),
+		})
+		return
+	}
+
+	ownerData, err := dynamicparameters.WorkspaceOwner(ctx, api.Database, organization.ID, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching owner data.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	tfVarValues := make(map[string]cty.Value)
+	for _, variable := range req.UserVariableValues {
+		tfVarValues[variable.Name] = cty.StringVal(variable.Value)
+	}
+
+	input := preview.Input{
+		PlanJSON:        nil, // Validation happens before `terraform plan`
+		ParameterValues: nil,
+		Owner:           *ownerData,
+		Logger:          stdslog.New(stdslog.DiscardHandler),
+		TFVars:          tfVarValues,
+	}
+	output, previewDiags := preview.Preview(ctx, input, files)
+	diags = diags.Extend(previewDiags)
+	if output != nil {
+		preview.ValidatePrebuilds(ctx, input, output.Presets, files)
+		for _, param := range output.Parameters {
+			diags = diags.Extend(hcl.Diagnostics(param.Diagnostics))
+		}
+	}
+	// Only the keyed diagnostics are collected from the checks, the top level
+	// diagnostics have been added above.
+	for _, de := range []*dynamicparameters.DiagnosticError{
+		dynamicparameters.CheckTags(output, nil),
+		dynamicparameters.CheckPresets(output, nil),
+	} {
+		if de == nil {
+			continue
+		}
+		for _, key := range slices.Sorted(maps.Keys(de.KeyedDiagnostics)) {
+			diags = diags.Extend(de.KeyedDiagnostics[key])
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.ValidateTemplateVersionResponse{
+		Valid:       !diags.HasErrors(),
+		Diagnostics: convertTemplateVersionDiagnostics(diags),
+	})
+}
+
+// parseTemplateFiles parses the Terraform files in the root of the template
+// and returns any syntax diagnostics.
+func parseTemplateFiles(files fs.FS) (hcl.Diagnostics, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, xerrors.Errorf("read template directory: %w", err)
+	}
+
+	parser := hclparse.NewParser()
+	var diags hcl.Diagnostics
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".tf") && !strings.HasSuffix(name, ".tf.json") {
+			continue
+		}
+		src, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", name, err)
+		}
+		var parseDiags hcl.Diagnostics
+		if strings.HasSuffix(name, ".json") {
+			_, parseDiags = parser.ParseJSON(src, name)
+		} else {
+			_, parseDiags = parser.ParseHCL(src, name)
+		}
+		diags = diags.Extend(parseDiags)
+	}
+	return diags, nil
+}
+
+func convertTemplateVersionDiagnostics(diags hcl.Diagnostics) []codersdk.TemplateVersionDiagnostic {
+	converted := make([]codersdk.TemplateVersionDiagnostic, 0, len(diags))
+	for _, diag := range diags {
+		if diag == nil {
+			continue
+		}
+		severity := codersdk.DiagnosticSeverityError
+		if diag.Severity == hcl.DiagWarning {
+			severity = codersdk.DiagnosticSeverityWarning
+		}
+		d := codersdk.TemplateVersionDiagnostic{
+			Severity: severity,
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+		}
+		if diag.Subject != nil {
+			d.Range = &codersdk.TemplateVersionDiagnosticRange{
+				Filename:    diag.Subject.Filename,
+				StartLine:   diag.Subject.Start.Line,
+				StartColumn: diag.Subject.Start.Column,
+				EndLine:     diag.Subject.End.Line,
+				EndColumn:   diag.Subject.End.Column,
+			}
+		}
+		converted = append(converted, d)
+	}
+	return converted
+}
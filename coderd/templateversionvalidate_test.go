@@ -0,0 +1,106 @@
+package coderd_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestValidateTemplateVersion(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	upload := func(t *testing.T, files map[string]string) codersdk.UploadResponse {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		fi, err := templateAdmin.Upload(ctx, "application/x-tar", bytes.NewReader(testutil.CreateTar(t, files)))
+		require.NoError(t, err)
+		return fi
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		fi := upload(t, map[string]string{
+			"main.tf": `
+				data "coder_parameter" "region" {
+				  name    = "region"
+				  type    = "string"
+				  default = "us"
+				}`,
+		})
+		res, err := templateAdmin.ValidateTemplateVersion(ctx, owner.OrganizationID, codersdk.ValidateTemplateVersionRequest{
+			FileID: fi.ID,
+		})
+		require.NoError(t, err)
+		require.True(t, res.Valid)
+		require.Empty(t, res.Diagnostics)
+	})
+
+	t.Run("SyntaxError", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		fi := upload(t, map[string]string{
+			"main.tf": "resource \"null_resource\" \"a\" {\n",
+		})
+		res, err := templateAdmin.ValidateTemplateVersion(ctx, owner.OrganizationID, codersdk.ValidateTemplateVersionRequest{
+			FileID: fi.ID,
+		})
+		require.NoError(t, err)
+		require.False(t, res.Valid)
+		require.NotEmpty(t, res.Diagnostics)
+		require.Equal(t, codersdk.DiagnosticSeverityError, res.Diagnostics[0].Severity)
+		require.NotNil(t, res.Diagnostics[0].Range)
+		require.Equal(t, "main.tf", res.Diagnostics[0].Range.Filename)
+		require.Equal(t, 1, res.Diagnostics[0].Range.StartLine)
+	})
+
+	t.Run("InvalidParameterDefault", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		fi := upload(t, map[string]string{
+			"main.tf": `
+				data "coder_parameter" "count" {
+				  name    = "count"
+				  type    = "number"
+				  default = 10
+				  validation {
+				    min = 1
+				    max = 5
+				  }
+				}`,
+		})
+		res, err := templateAdmin.ValidateTemplateVersion(ctx, owner.OrganizationID, codersdk.ValidateTemplateVersionRequest{
+			FileID: fi.ID,
+		})
+		require.NoError(t, err)
+		require.False(t, res.Valid)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		fi := upload(t, map[string]string{"main.tf": ""})
+		_, err := member.ValidateTemplateVersion(ctx, owner.OrganizationID, codersdk.ValidateTemplateVersionRequest{
+			FileID: fi.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,345 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxTemplateWorkspaceRestartBatchSize caps the number of workspaces that are
+// restarted at once, so a restart can't overload the provisioners.
+const maxTemplateWorkspaceRestartBatchSize = 100
+
+// @Summary Create template workspace restart
+// @Description Restarts the outdated running workspaces of a template on its
+// @Description active version, in batches. The next batch is started once the
+// @Description builds of the previous batch are completed and the batch
+// @Description interval has passed. The restart is paused once the failure
+// @Description rate of its builds exceeds the maximum, and aborted if the
+// @Description active version changes.
+// @ID create-template-workspace-restart
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateTemplateWorkspaceRestartRequest true "Create restart request"
+// @Success 201 {object} codersdk.TemplateWorkspaceRestart
+// @Router /api/v2/templates/{template}/restarts [post]
+func (api *API) postTemplateWorkspaceRestart(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		apiKey   = httpmw.APIKey(r)
+	)
+
+	var req codersdk.CreateTemplateWorkspaceRestartRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if validations := validateTemplateWorkspaceRestart(req); len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid restart.",
+			Validations: validations,
+		})
+		return
+	}
+
+	restart, err := api.Database.InsertTemplateWorkspaceRestart(ctx, database.InsertTemplateWorkspaceRestartParams{
+		ID:                uuid.New(),
+		TemplateID:        template.ID,
+		TemplateVersionID: template.ActiveVersionID,
+		BatchSize:         req.BatchSize,
+		BatchInterval:     int64(time.Duration(req.BatchIntervalMillis) * time.Millisecond),
+		HonorQuietHours:   req.HonorQuietHours,
+		MaxFailureRate:    req.MaxFailureRate,
+		MinBuilds:         req.MinBuilds,
+		CreatedBy:         apiKey.UserID,
+		CreatedAt:         dbtime.Time(api.Clock.Now()),
+	})
+	if database.IsUniqueViolation(err, database.UniqueTemplateWorkspaceRestartsActiveIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The template already has an unfinished workspace restart.",
+		})
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	converted, err := api.convertTemplateWorkspaceRestartWithStats(ctx, restart)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, converted)
+}
+
+// @Summary Get template workspace restarts
+// @ID get-template-workspace-restarts
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateWorkspaceRestart
+// @Router /api/v2/templates/{template}/restarts [get]
+func (api *API) templateWorkspaceRestarts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	restarts, err := api.Database.GetTemplateWorkspaceRestartsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	sdk := make([]codersdk.TemplateWorkspaceRestart, 0, len(restarts))
+	for _, restart := range restarts {
+		converted, err := api.convertTemplateWorkspaceRestartWithStats(ctx, restart)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		sdk = append(sdk, converted)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, sdk)
+}
+
+// @Summary Get template workspace restart
+// @ID get-template-workspace-restart
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param restart path string true "Restart ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateWorkspaceRestart
+// @Router /api/v2/templates/{template}/restarts/{restart} [get]
+func (api *API) templateWorkspaceRestart(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restart, ok := api.templateWorkspaceRestartParam(rw, r)
+	if !ok {
+		return
+	}
+
+	converted, err := api.convertTemplateWorkspaceRestartWithStats(ctx, restart)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Abort template workspace restart
+// @Description Aborts an in-progress or paused restart. Builds that were
+// @Description already started are not canceled.
+// @ID abort-template-workspace-restart
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param restart path string true "Restart ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateWorkspaceRestart
+// @Router /api/v2/templates/{template}/restarts/{restart}/abort [post]
+func (api *API) postAbortTemplateWorkspaceRestart(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restart, ok := api.templateWorkspaceRestartParam(rw, r)
+	if !ok {
+		return
+	}
+
+	aborted, err := api.Database.UpdateTemplateWorkspaceRestartStatus(ctx, database.UpdateTemplateWorkspaceRestartStatusParams{
+		ID:            restart.ID,
+		Status:        database.TemplateWorkspaceRestartStatusAborted,
+		StatusMessage: "The restart was aborted.",
+		UpdatedAt:     dbtime.Time(api.Clock.Now()),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only restarts in progress or paused can be aborted.",
+		})
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	converted, err := api.convertTemplateWorkspaceRestartWithStats(ctx, aborted)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Resume template workspace restart
+// @Description Resumes a restart that was paused because its failure rate was
+// @Description exceeded. Earlier failures no longer count towards the failure
+// @Description rate.
+// @ID resume-template-workspace-restart
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param restart path string true "Restart ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateWorkspaceRestart
+// @Router /api/v2/templates/{template}/restarts/{restart}/resume [post]
+func (api *API) postResumeTemplateWorkspaceRestart(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restart, ok := api.templateWorkspaceRestartParam(rw, r)
+	if !ok {
+		return
+	}
+
+	resumed, err := api.Database.ResumeTemplateWorkspaceRestart(ctx, database.ResumeTemplateWorkspaceRestartParams{
+		ID:        restart.ID,
+		UpdatedAt: dbtime.Time(api.Clock.Now()),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only paused restarts can be resumed.",
+		})
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	converted, err := api.convertTemplateWorkspaceRestartWithStats(ctx, resumed)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// templateWorkspaceRestartParam returns the workspace restart of the template
+// in the URL. False is returned if a response was written.
+func (api *API) templateWorkspaceRestartParam(rw http.ResponseWriter, r *http.Request) (database.TemplateWorkspaceRestart, bool) {
+	template := httpmw.TemplateParam(r)
+	restartID, ok := httpmw.ParseUUIDParam(rw, r, "restart")
+	if !ok {
+		return database.TemplateWorkspaceRestart{}, false
+	}
+
+	restart, err := api.Database.GetTemplateWorkspaceRestartByID(r.Context(), restartID)
+	if httpapi.Is404Error(err) || (err == nil && restart.TemplateID != template.ID) {
+		httpapi.ResourceNotFound(rw)
+		return database.TemplateWorkspaceRestart{}, false
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return database.TemplateWorkspaceRestart{}, false
+	}
+	return restart, true
+}
+
+func validateTemplateWorkspaceRestart(req codersdk.CreateTemplateWorkspaceRestartRequest) []codersdk.ValidationError {
+	var validations []codersdk.ValidationError
+	if req.BatchSize <= 0 || req.BatchSize > maxTemplateWorkspaceRestartBatchSize {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "batch_size",
+			Detail: fmt.Sprintf("Must be between 1 and %d.", maxTemplateWorkspaceRestartBatchSize),
+		})
+	}
+	if req.BatchIntervalMillis < 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "batch_interval_ms",
+			Detail: "Must not be negative.",
+		})
+	}
+	if req.MaxFailureRate < 0 || req.MaxFailureRate > 1 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "max_failure_rate",
+			Detail: "Must be between 0 and 1.",
+		})
+	}
+	if req.MinBuilds < 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "min_builds",
+			Detail: "Must not be negative.",
+		})
+	}
+	return validations
+}
+
+// convertTemplateWorkspaceRestartWithStats converts a restart along with the
+// stats of its builds. The remaining workspaces are only counted for
+// unfinished restarts.
+func (api *API) convertTemplateWorkspaceRestartWithStats(ctx context.Context, restart database.TemplateWorkspaceRestart) (codersdk.TemplateWorkspaceRestart, error) {
+	stats, err := api.Database.GetTemplateWorkspaceRestartBuildStats(ctx, database.GetTemplateWorkspaceRestartBuildStatsParams{
+		RestartID: restart.ID,
+		Since:     restart.StatsSince,
+	})
+	if err != nil {
+		return codersdk.TemplateWorkspaceRestart{}, err
+	}
+	var remaining int64
+	if restart.Status == database.TemplateWorkspaceRestartStatusInProgress || restart.Status == database.TemplateWorkspaceRestartStatusPaused {
+		candidates, err := api.Database.GetTemplateWorkspaceRestartCandidates(ctx, database.GetTemplateWorkspaceRestartCandidatesParams{
+			RestartID:         restart.ID,
+			TemplateID:        restart.TemplateID,
+			TemplateVersionID: restart.TemplateVersionID,
+		})
+		if err != nil {
+			return codersdk.TemplateWorkspaceRestart{}, err
+		}
+		remaining = int64(len(candidates))
+	}
+	return convertTemplateWorkspaceRestart(restart, stats, remaining), nil
+}
+
+func convertTemplateWorkspaceRestart(restart database.TemplateWorkspaceRestart, stats database.GetTemplateWorkspaceRestartBuildStatsRow, remaining int64) codersdk.TemplateWorkspaceRestart {
+	sdk := codersdk.TemplateWorkspaceRestart{
+		ID:                  restart.ID,
+		TemplateID:          restart.TemplateID,
+		TemplateVersionID:   restart.TemplateVersionID,
+		BatchSize:           restart.BatchSize,
+		BatchIntervalMillis: time.Duration(restart.BatchInterval).Milliseconds(),
+		HonorQuietHours:     restart.HonorQuietHours,
+		MaxFailureRate:      restart.MaxFailureRate,
+		MinBuilds:           restart.MinBuilds,
+		Status:              codersdk.TemplateWorkspaceRestartStatus(restart.Status),
+		StatusMessage:       restart.StatusMessage,
+		CreatedBy:           restart.CreatedBy,
+		CreatedAt:           restart.CreatedAt,
+		UpdatedAt:           restart.UpdatedAt,
+		Restarted:           stats.Restarted,
+		Succeeded:           stats.Succeeded,
+		Failed:              stats.Failed,
+		Pending:             stats.Pending,
+		Remaining:           remaining,
+	}
+	if restart.LastBatchAt.Valid {
+		sdk.LastBatchAt = &restart.LastBatchAt.Time
+	}
+	if restart.CompletedAt.Valid {
+		sdk.CompletedAt = &restart.CompletedAt.Time
+	}
+	return sdk
+}
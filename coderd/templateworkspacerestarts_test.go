@@ -0,0 +1,130 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateWorkspaceRestarts(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	// setup returns a template with a running workspace on a version that is
+	// no longer active.
+	setup := func(t *testing.T) codersdk.Template {
+		ctx := testutil.Context(t, testutil.WaitLong)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		ws := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
+		next := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, next.ID)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{ID: next.ID})
+		require.NoError(t, err)
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		return template
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template := setup(t)
+
+		restart, err := client.CreateTemplateWorkspaceRestart(ctx, template.ID, codersdk.CreateTemplateWorkspaceRestartRequest{
+			BatchSize:           10,
+			BatchIntervalMillis: time.Hour.Milliseconds(),
+			HonorQuietHours:     true,
+			MaxFailureRate:      0.1,
+			MinBuilds:           5,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateWorkspaceRestartStatusInProgress, restart.Status)
+		require.Equal(t, template.ActiveVersionID, restart.TemplateVersionID)
+		require.Equal(t, time.Hour.Milliseconds(), restart.BatchIntervalMillis)
+		require.True(t, restart.HonorQuietHours)
+		require.EqualValues(t, 1, restart.Remaining)
+		require.Zero(t, restart.Restarted)
+
+		// Only one restart can be unfinished.
+		_, err = client.CreateTemplateWorkspaceRestart(ctx, template.ID, codersdk.CreateTemplateWorkspaceRestartRequest{
+			BatchSize: 10,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		restarts, err := client.TemplateWorkspaceRestarts(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, restarts, 1)
+		require.Equal(t, restart.ID, restarts[0].ID)
+
+		got, err := client.TemplateWorkspaceRestart(ctx, template.ID, restart.ID)
+		require.NoError(t, err)
+		require.Equal(t, restart.ID, got.ID)
+
+		// Only paused restarts can be resumed.
+		_, err = client.ResumeTemplateWorkspaceRestart(ctx, template.ID, restart.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		aborted, err := client.AbortTemplateWorkspaceRestart(ctx, template.ID, restart.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateWorkspaceRestartStatusAborted, aborted.Status)
+		require.NotNil(t, aborted.CompletedAt)
+		require.Zero(t, aborted.Remaining)
+
+		_, err = client.AbortTemplateWorkspaceRestart(ctx, template.ID, restart.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// A new restart can be created once the previous one is finished.
+		_, err = client.CreateTemplateWorkspaceRestart(ctx, template.ID, codersdk.CreateTemplateWorkspaceRestartRequest{
+			BatchSize: 10,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template := setup(t)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		_, err := member.CreateTemplateWorkspaceRestart(ctx, template.ID, codersdk.CreateTemplateWorkspaceRestartRequest{
+			BatchSize: 10,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template := setup(t)
+
+		for _, req := range []codersdk.CreateTemplateWorkspaceRestartRequest{
+			{BatchSize: 0},
+			{BatchSize: 1000},
+			{BatchSize: 10, BatchIntervalMillis: -1},
+			{BatchSize: 10, MaxFailureRate: 2},
+			{BatchSize: 10, MinBuilds: -1},
+		} {
+			_, err := client.CreateTemplateWorkspaceRestart(ctx, template.ID, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+}
@@ -127,6 +127,40 @@ func TracerProvider(ctx context.Context, service string, opts TracerOpts) (*sdkt
 	}, nil
 }
 
+// EndpointTracerProvider creates a trace provider that exports to the OTLP gRPC
+// endpoint, e.g. "otel-collector:4317". Unlike TracerProvider, it is not
+// registered as the global trace provider. Caller is responsible for calling
+// the returned function to flush and shut down the provider.
+func EndpointTracerProvider(ctx context.Context, service string, endpoint string) (*sdktrace.TracerProvider, func(context.Context) error, error) {
+	exporter, err := otlptrace.New(ctx, otlptracegrpc.NewClient(
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithInsecure(),
+	))
+	if err != nil {
+		return nil, nil, xerrors.Errorf("create otlp exporter: %w", err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(service),
+		)),
+		sdktrace.WithBatcher(exporter),
+	)
+	return tracerProvider, func(ctx context.Context) error {
+		var merr error
+		err := tracerProvider.ForceFlush(ctx)
+		if err != nil {
+			merr = multierror.Append(merr, xerrors.Errorf("tracerProvider.ForceFlush(): %w", err))
+		}
+		err = tracerProvider.Shutdown(ctx)
+		if err != nil {
+			merr = multierror.Append(merr, xerrors.Errorf("tracerProvider.Shutdown(): %w", err))
+		}
+		return merr
+	}, nil
+}
+
 func DefaultExporter(ctx context.Context) (*otlptrace.Exporter, error) {
 	exporter, err := otlptrace.New(ctx, otlptracegrpc.NewClient(otlptracegrpc.WithInsecure()))
 	if err != nil {
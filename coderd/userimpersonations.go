@@ -0,0 +1,396 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// userImpersonationDefaultLifetime is the lifetime of impersonation
+	// tokens when none is requested.
+	userImpersonationDefaultLifetime = time.Hour
+	// userImpersonationRequestExpiry is how long after an impersonation
+	// request was made it can be consented to and redeemed.
+	userImpersonationRequestExpiry = 24 * time.Hour
+)
+
+// userImpersonationScopes restricts impersonation tokens to debugging the
+// workspaces of the impersonated user.
+var userImpersonationScopes = database.APIKeyScopes{
+	database.ApiKeyScopeCoderWorkspacesaccess,
+	database.ApiKeyScopeCoderWorkspacesoperate,
+}
+
+// userImpersonationAudit is attached to the audit log of impersonations so
+// the reason and outcome show up alongside the impersonated user.
+type userImpersonationAudit struct {
+	ImpersonationID uuid.UUID  `json:"impersonation_id"`
+	Reason          string     `json:"reason"`
+	Status          string     `json:"status"`
+	ConsentedAt     *time.Time `json:"consented_at,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+}
+
+// Impersonating a user issues a token that acts as the user, restricted to
+// accessing and operating their workspaces. When the deployment requires
+// consent, the first request is left pending until the user consents, after
+// which the impersonator redeems it by passing its ID.
+//
+// @Summary Impersonate user
+// @ID impersonate-user
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.ImpersonateUserRequest true "Impersonation request"
+// @Success 201 {object} codersdk.ImpersonateUserResponse
+// @Router /api/v2/users/{user}/impersonate [post]
+func (api *API) postUserImpersonation(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionImpersonate,
+		})
+	)
+	// Every attempt is audited against the impersonated user, including the
+	// ones that are rejected.
+	aReq.Old = user
+	aReq.New = user
+	defer commitAudit()
+
+	var req codersdk.ImpersonateUserRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if !api.Authorize(r, policy.ActionImpersonate, user) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if user.IsSystem {
+		api.Logger.Warn(ctx, "disallowed impersonating system user", slog.F("user_id", user.ID))
+		httpapi.Forbidden(rw)
+		return
+	}
+	if user.ID == apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot impersonate yourself.",
+		})
+		return
+	}
+
+	var impersonation database.UserImpersonation
+	if req.ImpersonationID != nil {
+		var err error
+		impersonation, err = api.Database.GetUserImpersonationByID(ctx, *req.ImpersonationID)
+		if httpapi.Is404Error(err) || (err == nil && (impersonation.UserID != user.ID || impersonation.ImpersonatorID != apiKey.UserID)) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching impersonation request.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		switch {
+		case impersonation.IssuedAt.Valid:
+			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+				Message: "A token was already issued for this impersonation request.",
+			})
+			return
+		case dbtime.Now().Sub(impersonation.CreatedAt) > userImpersonationRequestExpiry:
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "This impersonation request has expired.",
+				Detail:  fmt.Sprintf("Impersonation requests must be redeemed within %s.", userImpersonationRequestExpiry),
+			})
+			return
+		case impersonation.ConsentRequired && !impersonation.ConsentedAt.Valid:
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "The user has not consented to this impersonation request yet.",
+			})
+			return
+		}
+	} else {
+		if req.Reason == "" {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "A reason is required to impersonate a user.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "reason",
+					Detail: "This value is required.",
+				}},
+			})
+			return
+		}
+		maxLifetime := api.DeploymentValues.Sessions.MaximumImpersonationDuration.Value()
+		lifetime := req.Lifetime
+		if lifetime == 0 {
+			lifetime = min(userImpersonationDefaultLifetime, maxLifetime)
+		}
+		if lifetime <= 0 || lifetime > maxLifetime {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid impersonation lifetime.",
+				Detail:  fmt.Sprintf("The lifetime must be positive and at most %s.", maxLifetime),
+				Validations: []codersdk.ValidationError{{
+					Field:  "lifetime",
+					Detail: fmt.Sprintf("must be positive and at most %s", maxLifetime),
+				}},
+			})
+			return
+		}
+
+		var err error
+		impersonation, err = api.Database.InsertUserImpersonation(ctx, database.InsertUserImpersonationParams{
+			ID:              uuid.New(),
+			UserID:          user.ID,
+			ImpersonatorID:  apiKey.UserID,
+			Reason:          req.Reason,
+			LifetimeSeconds: int64(lifetime.Seconds()),
+			ConsentRequired: api.DeploymentValues.Sessions.RequireImpersonationConsent.Value(),
+			CreatedAt:       dbtime.Now(),
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error creating impersonation request.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	auditFields := &userImpersonationAudit{
+		ImpersonationID: impersonation.ID,
+		Reason:          impersonation.Reason,
+		Status:          "pending_consent",
+	}
+	if impersonation.ConsentedAt.Valid {
+		auditFields.ConsentedAt = &impersonation.ConsentedAt.Time
+	}
+	aReq.SetAdditionalFields(auditFields)
+
+	if impersonation.ConsentRequired && !impersonation.ConsentedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusCreated, codersdk.ImpersonateUserResponse{
+			Impersonation: db2sdk.UserImpersonation(impersonation),
+		})
+		return
+	}
+
+	impersonation, key, token, err := api.issueUserImpersonationToken(ctx, impersonation)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "A token was already issued for this impersonation request.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error issuing impersonation token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	auditFields.Status = "issued"
+	auditFields.ExpiresAt = &key.ExpiresAt
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.ImpersonateUserResponse{
+		Impersonation: db2sdk.UserImpersonation(impersonation),
+		Key:           token,
+	})
+}
+
+// issueUserImpersonationToken creates the token for an impersonation request
+// and marks the request as granted. sql.ErrNoRows is returned when a token
+// was already issued for the request.
+func (api *API) issueUserImpersonationToken(ctx context.Context, impersonation database.UserImpersonation) (database.UserImpersonation, database.APIKey, string, error) {
+	params, token, err := apikey.Generate(apikey.CreateParams{
+		UserID:          impersonation.UserID,
+		LoginType:       database.LoginTypeToken,
+		ExpiresAt:       dbtime.Now().Add(time.Duration(impersonation.LifetimeSeconds) * time.Second),
+		LifetimeSeconds: impersonation.LifetimeSeconds,
+		Scopes:          userImpersonationScopes,
+		TokenName:       apikey.ImpersonationTokenNamePrefix + impersonation.ID.String(),
+	})
+	if err != nil {
+		return database.UserImpersonation{}, database.APIKey{}, "", xerrors.Errorf("generate API key: %w", err)
+	}
+
+	var key database.APIKey
+	err = api.Database.InTx(func(tx database.Store) error {
+		// The impersonate permission is what allows creating a token for
+		// another user, so the key is inserted as the system.
+		// nolint:gocritic // Impersonators need not manage the user's tokens.
+		key, err = tx.InsertAPIKey(dbauthz.AsSystemRestricted(ctx), params)
+		if err != nil {
+			return xerrors.Errorf("insert API key: %w", err)
+		}
+		impersonation, err = tx.UpdateUserImpersonationIssued(ctx, database.UpdateUserImpersonationIssuedParams{
+			ID:       impersonation.ID,
+			APIKeyID: sql.NullString{String: key.ID, Valid: true},
+			IssuedAt: sql.NullTime{Time: key.CreatedAt, Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("mark impersonation issued: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return database.UserImpersonation{}, database.APIKey{}, "", err
+	}
+
+	api.Telemetry.Report(&telemetry.Snapshot{
+		APIKeys: []telemetry.APIKey{telemetry.ConvertAPIKey(key)},
+	})
+	return impersonation, key, token, nil
+}
+
+// @Summary Get user impersonation requests
+// @ID get-user-impersonation-requests
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.UserImpersonation
+// @Router /api/v2/users/{user}/impersonations [get]
+func (api *API) userImpersonations(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	impersonations, err := api.Database.GetUserImpersonationsByUserID(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching impersonation requests.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, slice.List(impersonations, db2sdk.UserImpersonation))
+}
+
+// @Summary Consent to user impersonation
+// @ID consent-to-user-impersonation
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param impersonation path string true "Impersonation ID" format(uuid)
+// @Success 200 {object} codersdk.UserImpersonation
+// @Router /api/v2/users/{user}/impersonations/{impersonation}/consent [post]
+func (api *API) postUserImpersonationConsent(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionImpersonate,
+		})
+	)
+	// Consent attempts are audited against the impersonated user, like the
+	// impersonation itself.
+	aReq.Old = user
+	aReq.New = user
+	defer commitAudit()
+
+	impersonationID, ok := httpmw.ParseUUIDParam(rw, r, "impersonation")
+	if !ok {
+		return
+	}
+	auditFields := &userImpersonationAudit{
+		ImpersonationID: impersonationID,
+		Status:          "pending_consent",
+	}
+	aReq.SetAdditionalFields(auditFields)
+	// Consent must come from the impersonated user, never from an admin
+	// acting on their behalf.
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only the impersonated user can consent to being impersonated.",
+		})
+		return
+	}
+
+	impersonation, err := api.Database.GetUserImpersonationByID(ctx, impersonationID)
+	if httpapi.Is404Error(err) || (err == nil && impersonation.UserID != user.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching impersonation request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	auditFields.Reason = impersonation.Reason
+	if !impersonation.ConsentRequired {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "This impersonation request does not require consent.",
+		})
+		return
+	}
+	if dbtime.Now().Sub(impersonation.CreatedAt) > userImpersonationRequestExpiry {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "This impersonation request has expired.",
+		})
+		return
+	}
+
+	impersonation, err = api.Database.UpdateUserImpersonationConsentedAt(ctx, database.UpdateUserImpersonationConsentedAtParams{
+		ID:          impersonation.ID,
+		ConsentedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "This impersonation request was already consented to.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error consenting to impersonation request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	auditFields.Status = "consented"
+	auditFields.ConsentedAt = &impersonation.ConsentedAt.Time
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.UserImpersonation(impersonation))
+}
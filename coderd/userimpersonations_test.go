@@ -0,0 +1,184 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestImpersonateUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := client.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{
+			Reason:   "debugging a failing startup script",
+			Lifetime: 30 * time.Minute,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Key)
+		require.Equal(t, member.ID, resp.Impersonation.UserID)
+		require.Equal(t, owner.UserID, resp.Impersonation.ImpersonatorID)
+		require.False(t, resp.Impersonation.ConsentRequired)
+		require.NotNil(t, resp.Impersonation.IssuedAt)
+
+		// The token acts as the user, and is restricted to their workspaces.
+		key, err := client.APIKeyByID(ctx, member.ID.String(), strings.Split(resp.Key, "-")[0])
+		require.NoError(t, err)
+		require.Equal(t, member.ID, key.UserID)
+		require.ElementsMatch(t, []codersdk.APIKeyScope{codersdk.APIKeyScopeCoderWorkspacesAccess, codersdk.APIKeyScopeCoderWorkspacesOperate}, key.Scopes)
+		require.WithinDuration(t, time.Now().Add(30*time.Minute), key.ExpiresAt, time.Minute)
+
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionImpersonate,
+			ResourceType: database.ResourceTypeUser,
+			ResourceID:   member.ID,
+			UserID:       owner.UserID,
+		}))
+
+		impersonations, err := client.UserImpersonations(ctx, member.ID.String())
+		require.NoError(t, err)
+		require.Len(t, impersonations, 1)
+		require.Equal(t, resp.Impersonation.ID, impersonations[0].ID)
+
+		// Actions taken with the token are audited as the user, and name the
+		// impersonation and the impersonator.
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        member.ID,
+			OrganizationID: owner.OrganizationID,
+		}).Do()
+		impersonatorClient := codersdk.New(client.URL)
+		impersonatorClient.SetSessionToken(resp.Key)
+		err = impersonatorClient.UpdateWorkspaceTTL(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceTTLRequest{
+			TTLMillis: ptr.Ref(time.Hour.Milliseconds()),
+		})
+		require.NoError(t, err)
+
+		var found bool
+		for _, log := range auditor.AuditLogs() {
+			if log.ResourceID != r.Workspace.ID || log.Action != database.AuditActionWrite {
+				continue
+			}
+			found = true
+			require.Equal(t, member.ID, log.UserID)
+			var fields map[string]any
+			require.NoError(t, json.Unmarshal(log.AdditionalFields, &fields))
+			require.Equal(t, resp.Impersonation.ID.String(), fields["impersonation_id"])
+			require.Equal(t, owner.UserID.String(), fields["impersonator_id"])
+		}
+		require.True(t, found, "no audit log for the workspace update")
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		userAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := userAdmin.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{
+			Reason: "debugging",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, tc := range []struct {
+			name string
+			user string
+			req  codersdk.ImpersonateUserRequest
+		}{
+			{name: "Self", user: codersdk.Me, req: codersdk.ImpersonateUserRequest{Reason: "debugging"}},
+			{name: "NoReason", user: member.ID.String(), req: codersdk.ImpersonateUserRequest{}},
+			{name: "LifetimeTooLong", user: member.ID.String(), req: codersdk.ImpersonateUserRequest{Reason: "debugging", Lifetime: 30 * 24 * time.Hour}},
+		} {
+			_, err := client.ImpersonateUser(ctx, tc.user, tc.req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, tc.name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), tc.name)
+		}
+	})
+
+	t.Run("RequireConsent", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.Sessions.RequireImpersonationConsent = true
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv, Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := client.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{
+			Reason: "debugging",
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.Key)
+		require.True(t, resp.Impersonation.ConsentRequired)
+		require.Nil(t, resp.Impersonation.IssuedAt)
+		redeem := codersdk.ImpersonateUserRequest{ImpersonationID: &resp.Impersonation.ID}
+
+		// No token is issued until the user consents.
+		_, err = client.ImpersonateUser(ctx, member.ID.String(), redeem)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Only the user can consent.
+		_, err = client.ConsentToUserImpersonation(ctx, member.ID.String(), resp.Impersonation.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		pending, err := memberClient.UserImpersonations(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		consented, err := memberClient.ConsentToUserImpersonation(ctx, codersdk.Me, pending[0].ID)
+		require.NoError(t, err)
+		require.NotNil(t, consented.ConsentedAt)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionImpersonate,
+			ResourceType: database.ResourceTypeUser,
+			ResourceID:   member.ID,
+			UserID:       member.ID,
+			StatusCode:   http.StatusOK,
+		}))
+
+		redeemed, err := client.ImpersonateUser(ctx, member.ID.String(), redeem)
+		require.NoError(t, err)
+		require.NotEmpty(t, redeemed.Key)
+		require.NotNil(t, redeemed.Impersonation.IssuedAt)
+
+		// Each request issues a single token.
+		_, err = client.ImpersonateUser(ctx, member.ID.String(), redeem)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+}
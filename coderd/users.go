@@ -1341,12 +1341,22 @@ func (api *API) userPreferenceSettings(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	selfRequestedBuildsAllowed, err := api.Database.GetUserWorkspaceSelfRequestedBuildsAllowed(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error reading user preference settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserPreferenceSettings{
-		TaskNotificationAlertDismissed: taskAlertDismissed,
-		ThinkingDisplayMode:            sanitizeThinkingDisplayMode(thinkingMode),
-		ShellToolDisplayMode:           sanitizeShellToolDisplayMode(shellToolMode),
-		CodeDiffDisplayMode:            sanitizeAgentDisplayMode(codeDiffMode),
-		AgentChatSendShortcut:          sanitizeAgentChatSendShortcut(agentChatSendShortcut),
+		TaskNotificationAlertDismissed:      taskAlertDismissed,
+		ThinkingDisplayMode:                 sanitizeThinkingDisplayMode(thinkingMode),
+		ShellToolDisplayMode:                sanitizeShellToolDisplayMode(shellToolMode),
+		CodeDiffDisplayMode:                 sanitizeAgentDisplayMode(codeDiffMode),
+		AgentChatSendShortcut:               sanitizeAgentChatSendShortcut(agentChatSendShortcut),
+		WorkspaceSelfRequestedBuildsAllowed: selfRequestedBuildsAllowed,
 	})
 }
 
@@ -1496,6 +1506,21 @@ func (api *API) putUserPreferenceSettings(rw http.ResponseWriter, r *http.Reques
 			}
 			settings.AgentChatSendShortcut = sanitizeAgentChatSendShortcut(stored)
 		}
+
+		if params.WorkspaceSelfRequestedBuildsAllowed != nil {
+			settings.WorkspaceSelfRequestedBuildsAllowed, err = tx.UpdateUserWorkspaceSelfRequestedBuildsAllowed(ctx, database.UpdateUserWorkspaceSelfRequestedBuildsAllowedParams{
+				UserID:                              user.ID,
+				WorkspaceSelfRequestedBuildsAllowed: *params.WorkspaceSelfRequestedBuildsAllowed,
+			})
+			if err != nil {
+				return newUserPreferenceSettingsAPIError("Internal error updating workspace self-requested builds allowed.", err)
+			}
+		} else {
+			settings.WorkspaceSelfRequestedBuildsAllowed, err = tx.GetUserWorkspaceSelfRequestedBuildsAllowed(ctx, user.ID)
+			if err != nil {
+				return newUserPreferenceSettingsAPIError("Error reading workspace self-requested builds allowed.", err)
+			}
+		}
 		return nil
 	}, database.DefaultTXOptions().WithID("user_preference_settings"))
 	if err != nil {
@@ -2154,5 +2179,6 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		LifetimeSeconds: k.LifetimeSeconds,
 		TokenName:       k.TokenName,
 		AllowList:       slice.List(k.AllowList, db2sdk.APIAllowListTarget),
+		TemplateIDs:     k.TemplateIDs,
 	}
 }
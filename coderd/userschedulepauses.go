@@ -0,0 +1,156 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxSchedulePauseDuration bounds schedule pauses, so that a forgotten pause
+// doesn't disable autostart for good.
+const maxSchedulePauseDuration = 365 * 24 * time.Hour
+
+// @Summary Get schedule pause of user
+// @ID get-schedule-pause-of-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserSchedulePause
+// @Router /api/v2/users/{user}/schedule-pause [get]
+func (api *API) userSchedulePause(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	pause, err := api.Database.GetUserSchedulePause(ctx, user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserSchedulePause{})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching schedule pause.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertUserSchedulePause(pause, dbtime.Now()))
+}
+
+// @Summary Pause schedules of user
+// @Description Autostart stays suspended for all workspaces of the user until the pause
+// @Description ends. Autostarts that were due during the pause are skipped, so workspaces
+// @Description start on their first scheduled time after it.
+// @ID pause-schedules-of-user
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateUserSchedulePauseRequest true "Schedule pause"
+// @Success 200 {object} codersdk.UserSchedulePause
+// @Router /api/v2/users/{user}/schedule-pause [put]
+func (api *API) putUserSchedulePause(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var req codersdk.UpdateUserSchedulePauseRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	now := dbtime.Now()
+	if !req.PausedUntil.After(now) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The end of the pause must be in the future.",
+			Validations: []codersdk.ValidationError{
+				{Field: "paused_until", Detail: "must be in the future"},
+			},
+		})
+		return
+	}
+	if req.PausedUntil.Sub(now) > maxSchedulePauseDuration {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Schedules can be paused for up to a year.",
+			Validations: []codersdk.ValidationError{
+				{Field: "paused_until", Detail: "must be within a year"},
+			},
+		})
+		return
+	}
+
+	pause, err := api.Database.UpsertUserSchedulePause(ctx, database.UpsertUserSchedulePauseParams{
+		UserID:        user.ID,
+		PausedUntil:   dbtime.Time(req.PausedUntil),
+		PauseAutostop: req.PauseAutostop,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error pausing schedules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertUserSchedulePause(pause, now))
+}
+
+// @Summary Resume schedules of user
+// @ID resume-schedules-of-user
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /api/v2/users/{user}/schedule-pause [delete]
+func (api *API) deleteUserSchedulePause(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	err := api.Database.DeleteUserSchedulePause(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resuming schedules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertUserSchedulePause(pause database.UserSchedulePause, now time.Time) codersdk.UserSchedulePause {
+	return codersdk.UserSchedulePause{
+		Paused:        now.Before(pause.PausedUntil),
+		PausedUntil:   &pause.PausedUntil,
+		PauseAutostop: pause.PauseAutostop,
+	}
+}
@@ -0,0 +1,60 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserSchedulePause(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	pause, err := client.UserSchedulePause(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.False(t, pause.Paused)
+	require.Nil(t, pause.PausedUntil)
+
+	var apiErr *codersdk.Error
+	for _, until := range []time.Time{
+		time.Now().Add(-time.Hour),
+		time.Now().Add(400 * 24 * time.Hour),
+	} {
+		_, err = client.UpdateUserSchedulePause(ctx, codersdk.Me, codersdk.UpdateUserSchedulePauseRequest{
+			PausedUntil: until,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	}
+
+	until := time.Now().Add(14 * 24 * time.Hour).Truncate(time.Second)
+	pause, err = client.UpdateUserSchedulePause(ctx, codersdk.Me, codersdk.UpdateUserSchedulePauseRequest{
+		PausedUntil:   until,
+		PauseAutostop: true,
+	})
+	require.NoError(t, err)
+	require.True(t, pause.Paused)
+	require.NotNil(t, pause.PausedUntil)
+	require.WithinDuration(t, until, *pause.PausedUntil, time.Second)
+	require.True(t, pause.PauseAutostop)
+
+	pause, err = client.UserSchedulePause(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.True(t, pause.Paused)
+	require.True(t, pause.PauseAutostop)
+
+	err = client.DeleteUserSchedulePause(ctx, codersdk.Me)
+	require.NoError(t, err)
+	pause, err = client.UserSchedulePause(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.False(t, pause.Paused)
+}
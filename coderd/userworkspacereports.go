@@ -0,0 +1,67 @@
+package coderd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get latest workspace report of user
+// @Description Workspace reports are generated weekly by the report generator, which also
+// @Description sends them to the user unless they disabled the notification.
+// @ID get-latest-workspace-report-of-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserWorkspaceReport
+// @Router /api/v2/users/{user}/workspace-report [get]
+func (api *API) userWorkspaceReport(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	report, err := api.Database.GetLatestUserWorkspaceReportByUserID(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "No workspace report has been generated for this user yet.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace report.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var summary codersdk.UserWorkspaceReportSummary
+	if err := json.Unmarshal(report.Summary, &summary); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace report.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserWorkspaceReport{
+		ID:          report.ID,
+		UserID:      report.UserID,
+		PeriodStart: report.PeriodStart,
+		PeriodEnd:   report.PeriodEnd,
+		Summary:     summary,
+		CreatedAt:   report.CreatedAt,
+	})
+}
@@ -0,0 +1,75 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserWorkspaceReport(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	orgAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.ScopedRoleOrgAdmin(owner.OrganizationID))
+
+	var apiErr *codersdk.Error
+	_, err := memberClient.UserWorkspaceReport(ctx, codersdk.Me)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	now := dbtime.Now()
+	workspaceID := uuid.New()
+	for week := 2; week > 0; week-- {
+		end := now.Add(-time.Duration(week-1) * 7 * 24 * time.Hour)
+		summary, err := json.Marshal(codersdk.UserWorkspaceReportSummary{
+			TotalBuilds:  int64(10 * week),
+			FailedBuilds: int64(week),
+			Workspaces: []codersdk.UserWorkspaceReportWorkspace{{
+				ID:          workspaceID,
+				Name:        "workspace",
+				TotalBuilds: int64(10 * week),
+				DeletingAt:  &end,
+			}},
+		})
+		require.NoError(t, err)
+		_, err = db.InsertUserWorkspaceReport(ctx, database.InsertUserWorkspaceReportParams{
+			ID:          uuid.New(),
+			UserID:      member.ID,
+			PeriodStart: end.Add(-7 * 24 * time.Hour),
+			PeriodEnd:   end,
+			Summary:     summary,
+			CreatedAt:   end,
+		})
+		require.NoError(t, err)
+	}
+
+	// The most recent report is returned.
+	report, err := memberClient.UserWorkspaceReport(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, member.ID, report.UserID)
+	require.EqualValues(t, 10, report.Summary.TotalBuilds)
+	require.EqualValues(t, 1, report.Summary.FailedBuilds)
+	require.Len(t, report.Summary.Workspaces, 1)
+	require.Equal(t, workspaceID, report.Summary.Workspaces[0].ID)
+	require.NotNil(t, report.Summary.Workspaces[0].DeletingAt)
+	require.Nil(t, report.Summary.Workspaces[0].DormantAt)
+
+	// Organization admins can see the user, but not the report.
+	_, err = orgAdminClient.UserWorkspaceReport(ctx, member.ID.String())
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
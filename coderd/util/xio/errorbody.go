@@ -0,0 +1,17 @@
+package xio
+
+import (
+	"bytes"
+	"io"
+)
+
+// MaxErrorBodySize is the most ReadErrorBody reads from a response body.
+const MaxErrorBodySize = 4 << 10
+
+// ReadErrorBody reads the start of a failed HTTP response body so it can be
+// included in an error message. Read errors are ignored because the body is
+// only informational.
+func ReadErrorBody(r io.Reader) string {
+	b, _ := io.ReadAll(io.LimitReader(r, MaxErrorBodySize))
+	return string(bytes.TrimSpace(b))
+}
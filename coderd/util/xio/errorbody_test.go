@@ -0,0 +1,25 @@
+package xio_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/util/xio"
+)
+
+func TestReadErrorBody(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TrimsSpace", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, "bad request", xio.ReadErrorBody(strings.NewReader("  bad request\n")))
+	})
+
+	t.Run("Truncates", func(t *testing.T) {
+		t.Parallel()
+		body := strings.Repeat("a", xio.MaxErrorBodySize+100)
+		require.Len(t, xio.ReadErrorBody(strings.NewReader(body)), xio.MaxErrorBodySize)
+	})
+}
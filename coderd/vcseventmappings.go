@@ -0,0 +1,483 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/vcsevents"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// maxVCSEventBodyBytes bounds the webhook deliveries that are read. Push
+	// payloads list the commits pushed, but are far smaller than this.
+	maxVCSEventBodyBytes = 1 << 20
+
+	// The workspace restarts started by the update_workspaces action restart
+	// a few workspaces at a time, and pause once half of the first builds
+	// fail.
+	vcsEventRestartBatchSize      = 10
+	vcsEventRestartBatchInterval  = time.Minute
+	vcsEventRestartMaxFailureRate = 0.5
+	vcsEventRestartMinBuilds      = 3
+)
+
+// @Summary Get template VCS event mappings
+// @Description Returns the VCS event mappings of a template. Signing secrets
+// @Description are never returned.
+// @ID get-template-vcs-event-mappings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.VCSEventMapping
+// @Router /api/v2/templates/{template}/vcs-mappings [get]
+func (api *API) templateVCSEventMappings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	mappings, err := api.Database.GetVCSEventMappingsByTemplateID(ctx, template.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := make([]codersdk.VCSEventMapping, 0, len(mappings))
+	for _, mapping := range mappings {
+		resp = append(resp, convertVCSEventMapping(mapping))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Create template VCS event mapping
+// @Description Maps the pushes to a repository to an action on the workspaces
+// @Description of a template. The action is performed as the user creating
+// @Description the mapping whenever a webhook delivery signed with the
+// @Description signing secret is received.
+// @ID create-template-vcs-event-mapping
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateVCSEventMappingRequest true "Create mapping request"
+// @Success 201 {object} codersdk.VCSEventMapping
+// @Router /api/v2/templates/{template}/vcs-mappings [post]
+func (api *API) postTemplateVCSEventMapping(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.VCSEventMapping](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateVCSEventMappingRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	req.Repository = strings.TrimSpace(req.Repository)
+	req.Branch = strings.TrimSpace(req.Branch)
+
+	var validations []codersdk.ValidationError
+	if err := codersdk.NameValid(req.Name); err != nil {
+		validations = append(validations, codersdk.ValidationError{Field: "name", Detail: err.Error()})
+	}
+	if owner, name, ok := strings.Cut(req.Repository, "/"); !ok || owner == "" || name == "" {
+		validations = append(validations, codersdk.ValidationError{Field: "repository", Detail: `Must be the full name of the repository, e.g. "coder/coder".`})
+	}
+	if !req.Action.Valid() {
+		validations = append(validations, codersdk.ValidationError{Field: "action", Detail: fmt.Sprintf("Unknown action %q.", req.Action)})
+	}
+	switch {
+	case req.Action == codersdk.VCSEventActionStartWorkspace && req.WorkspaceID == nil:
+		validations = append(validations, codersdk.ValidationError{Field: "workspace_id", Detail: "Required for the start_workspace action."})
+	case req.Action != codersdk.VCSEventActionStartWorkspace && req.WorkspaceID != nil:
+		validations = append(validations, codersdk.ValidationError{Field: "workspace_id", Detail: "Only allowed for the start_workspace action."})
+	case req.WorkspaceID != nil:
+		workspace, err := api.Database.GetWorkspaceByID(ctx, *req.WorkspaceID)
+		if err != nil && !httpapi.Is404Error(err) {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if err != nil || workspace.TemplateID != template.ID {
+			validations = append(validations, codersdk.ValidationError{Field: "workspace_id", Detail: "Must be a workspace of the template."})
+		}
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid VCS event mapping.",
+			Validations: validations,
+		})
+		return
+	}
+
+	var workspaceID uuid.NullUUID
+	if req.WorkspaceID != nil {
+		workspaceID = uuid.NullUUID{UUID: *req.WorkspaceID, Valid: true}
+	}
+	mapping, err := api.Database.InsertVCSEventMapping(ctx, database.InsertVCSEventMappingParams{
+		ID:            uuid.New(),
+		TemplateID:    template.ID,
+		Name:          req.Name,
+		Repository:    req.Repository,
+		Branch:        req.Branch,
+		Action:        database.VCSEventAction(req.Action),
+		WorkspaceID:   workspaceID,
+		SigningSecret: req.SigningSecret,
+		CreatedBy:     apiKey.UserID,
+		CreatedAt:     dbtime.Time(api.Clock.Now()),
+	})
+	if database.IsUniqueViolation(err, database.UniqueVcsEventMappingsTemplateIDNameIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "A VCS event mapping with this name already exists for the template.",
+		})
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = mapping
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertVCSEventMapping(mapping))
+}
+
+// @Summary Delete template VCS event mapping
+// @ID delete-template-vcs-event-mapping
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param mapping path string true "Mapping ID" format(uuid)
+// @Success 204
+// @Router /api/v2/templates/{template}/vcs-mappings/{mapping} [delete]
+func (api *API) deleteTemplateVCSEventMapping(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.VCSEventMapping](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionDelete,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	mappingID, ok := httpmw.ParseUUIDParam(rw, r, "mapping")
+	if !ok {
+		return
+	}
+
+	deleted, err := api.Database.DeleteVCSEventMappingByTemplateIDAndID(ctx, database.DeleteVCSEventMappingByTemplateIDAndIDParams{
+		TemplateID: template.ID,
+		ID:         mappingID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.Old = deleted
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// vcsEventAudit is recorded in the additional fields of the audit log written
+// when an event triggers a mapping.
+type vcsEventAudit struct {
+	Repository string                   `json:"repository"`
+	Ref        string                   `json:"ref"`
+	Commit     string                   `json:"commit"`
+	Outcome    codersdk.VCSEventOutcome `json:"outcome"`
+	Message    string                   `json:"message"`
+}
+
+// @Summary Receive VCS event
+// @Description Receives the push webhooks of GitHub and GitLab. The delivery
+// @Description must be signed with the signing secret of a mapping of the
+// @Description repository: GitHub deliveries through the X-Hub-Signature-256
+// @Description header, GitLab deliveries through the X-Gitlab-Token header.
+// @Description Pushes run the action of every such mapping whose branch
+// @Description matches, and other events are only acknowledged.
+// @ID receive-vcs-event
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Success 200 {object} codersdk.VCSEventResponse
+// @Failure 401 {object} codersdk.Response
+// @Router /api/v2/integrations/vcs/events [post]
+func (api *API) postVCSEvent(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	r.Body = http.MaxBytesReader(rw, r.Body, maxVCSEventBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to read request body.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	event, err := vcsevents.Parse(r.Header, body)
+	if errors.Is(err, vcsevents.ErrUnknownProvider) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only GitHub and GitLab webhooks are supported.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid webhook payload.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// The sender isn't a user: it's authenticated by the signing secret of
+	// the mappings it signed the delivery for.
+	//nolint:gocritic // Mappings must be read to verify the delivery.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	mappings, err := api.Database.GetVCSEventMappingsByRepository(sysCtx, event.Repository)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	var verified []database.VCSEventMapping
+	for _, mapping := range mappings {
+		if vcsevents.Verify(event.Provider, r.Header, body, mapping.SigningSecret) {
+			verified = append(verified, mapping)
+		}
+	}
+	if len(verified) == 0 {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "The webhook isn't signed for any VCS event mapping of the repository.",
+		})
+		return
+	}
+
+	resp := codersdk.VCSEventResponse{Results: []codersdk.VCSEventMappingResult{}}
+	if !event.IsBranchPush() {
+		httpapi.Write(ctx, rw, http.StatusOK, resp)
+		return
+	}
+
+	auditor := api.Auditor.Load()
+	requestID := httpmw.RequestID(r)
+	auditCtx := context.WithoutCancel(ctx)
+	for _, mapping := range verified {
+		if mapping.Branch != "" && mapping.Branch != event.Branch {
+			continue
+		}
+
+		template, err := api.Database.GetTemplateByID(sysCtx, mapping.TemplateID)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		outcome, message := api.runVCSEventMapping(ctx, template, mapping)
+		resp.Results = append(resp.Results, codersdk.VCSEventMappingResult{
+			MappingID: mapping.ID,
+			Name:      mapping.Name,
+			Action:    codersdk.VCSEventAction(mapping.Action),
+			Outcome:   outcome,
+			Message:   message,
+		})
+
+		err = api.Database.UpdateVCSEventMappingLastTriggeredAt(sysCtx, database.UpdateVCSEventMappingLastTriggeredAtParams{
+			ID:              mapping.ID,
+			LastTriggeredAt: sql.NullTime{Time: dbtime.Time(api.Clock.Now()), Valid: true},
+		})
+		if err != nil {
+			api.Logger.Warn(ctx, "update vcs event mapping last triggered at", slog.F("mapping_id", mapping.ID), slog.Error(err))
+		}
+
+		additionalFields, err := json.Marshal(vcsEventAudit{
+			Repository: event.Repository,
+			Ref:        event.Ref,
+			Commit:     event.Commit,
+			Outcome:    outcome,
+			Message:    message,
+		})
+		if err != nil {
+			api.Logger.Error(ctx, "marshal vcs event audit fields", slog.Error(err))
+		}
+		status := http.StatusOK
+		if outcome == codersdk.VCSEventOutcomeFailed {
+			status = http.StatusInternalServerError
+		}
+		audit.BackgroundAudit(auditCtx, &audit.BackgroundAuditParams[database.VCSEventMapping]{
+			Audit:            *auditor,
+			Log:              api.Logger,
+			UserID:           mapping.CreatedBy,
+			RequestID:        requestID,
+			Status:           status,
+			Action:           database.AuditActionStart,
+			OrganizationID:   template.OrganizationID,
+			IP:               r.RemoteAddr,
+			UserAgent:        r.UserAgent(),
+			AdditionalFields: additionalFields,
+			New:              mapping,
+			Old:              mapping,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// runVCSEventMapping performs the action of a mapping as the user that
+// created it, so a mapping can't do anything its creator couldn't.
+func (api *API) runVCSEventMapping(ctx context.Context, template database.Template, mapping database.VCSEventMapping) (codersdk.VCSEventOutcome, string) {
+	logger := api.Logger.With(slog.F("mapping_id", mapping.ID), slog.F("template_id", template.ID))
+
+	actor, status, err := httpmw.UserRBACSubject(ctx, api.Database, mapping.CreatedBy, rbac.ScopeAll)
+	if err != nil {
+		logger.Error(ctx, "load vcs event mapping creator authorization", slog.Error(err))
+		return codersdk.VCSEventOutcomeFailed, "Failed to load the authorization of the creator of the mapping."
+	}
+	if status == database.UserStatusSuspended {
+		return codersdk.VCSEventOutcomeFailed, "The creator of the mapping is suspended."
+	}
+	ctx = dbauthz.As(ctx, actor)
+
+	switch mapping.Action {
+	case database.VCSEventActionStartWorkspace:
+		workspace, err := api.Database.GetWorkspaceByID(ctx, mapping.WorkspaceID.UUID)
+		if err != nil {
+			logger.Warn(ctx, "get vcs event mapping workspace", slog.Error(err))
+			return codersdk.VCSEventOutcomeFailed, "Failed to fetch the workspace."
+		}
+		build, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+		if err != nil {
+			logger.Warn(ctx, "get vcs event mapping workspace build", slog.Error(err))
+			return codersdk.VCSEventOutcomeFailed, "Failed to fetch the latest build of the workspace."
+		}
+		job, err := api.Database.GetProvisionerJobByID(ctx, build.JobID)
+		if err != nil {
+			logger.Warn(ctx, "get vcs event mapping workspace build job", slog.Error(err))
+			return codersdk.VCSEventOutcomeFailed, "Failed to fetch the latest build of the workspace."
+		}
+		if build.Transition == database.WorkspaceTransitionStart {
+			switch job.JobStatus {
+			case database.ProvisionerJobStatusPending, database.ProvisionerJobStatusRunning, database.ProvisionerJobStatusSucceeded:
+				return codersdk.VCSEventOutcomeSkipped, "The workspace is already started."
+			}
+		}
+
+		created, err := api.postWorkspaceBuildsInternal(
+			ctx,
+			database.APIKey{UserID: mapping.CreatedBy},
+			workspace,
+			codersdk.CreateWorkspaceBuildRequest{
+				Transition: codersdk.WorkspaceTransitionStart,
+			},
+			func(action policy.Action, object rbac.Objecter) bool {
+				return api.HTTPAuth.Authorizer.Authorize(ctx, actor, action, object.RBACObject()) == nil
+			},
+			audit.WorkspaceBuildBaggage{},
+		)
+		if err != nil {
+			logger.Warn(ctx, "start vcs event mapping workspace", slog.Error(err))
+			return codersdk.VCSEventOutcomeFailed, "Failed to start the workspace."
+		}
+		return codersdk.VCSEventOutcomeTriggered, fmt.Sprintf("Started build #%d of the workspace.", created.BuildNumber)
+
+	case database.VCSEventActionRefreshPrebuilds:
+		presets, err := api.Database.UpdatePresetsLastInvalidatedAt(ctx, database.UpdatePresetsLastInvalidatedAtParams{
+			TemplateID:        template.ID,
+			LastInvalidatedAt: sql.NullTime{Time: api.Clock.Now(), Valid: true},
+		})
+		if err != nil {
+			logger.Warn(ctx, "invalidate vcs event mapping presets", slog.Error(err))
+			return codersdk.VCSEventOutcomeFailed, "Failed to invalidate the presets of the template."
+		}
+		return codersdk.VCSEventOutcomeTriggered, fmt.Sprintf("Invalidated %d presets.", len(presets))
+
+	case database.VCSEventActionUpdateWorkspaces:
+		_, err := api.Database.InsertTemplateWorkspaceRestart(ctx, database.InsertTemplateWorkspaceRestartParams{
+			ID:                uuid.New(),
+			TemplateID:        template.ID,
+			TemplateVersionID: template.ActiveVersionID,
+			BatchSize:         vcsEventRestartBatchSize,
+			BatchInterval:     int64(vcsEventRestartBatchInterval),
+			MaxFailureRate:    vcsEventRestartMaxFailureRate,
+			MinBuilds:         vcsEventRestartMinBuilds,
+			CreatedBy:         mapping.CreatedBy,
+			CreatedAt:         dbtime.Time(api.Clock.Now()),
+		})
+		if database.IsUniqueViolation(err, database.UniqueTemplateWorkspaceRestartsActiveIndex) {
+			return codersdk.VCSEventOutcomeSkipped, "The template already has an unfinished workspace restart."
+		}
+		if err != nil {
+			logger.Warn(ctx, "restart vcs event mapping workspaces", slog.Error(err))
+			return codersdk.VCSEventOutcomeFailed, "Failed to restart the workspaces of the template."
+		}
+		return codersdk.VCSEventOutcomeTriggered, "Started restarting the workspaces of the template on its active version."
+
+	default:
+		return codersdk.VCSEventOutcomeFailed, fmt.Sprintf("Unknown action %q.", mapping.Action)
+	}
+}
+
+func convertVCSEventMapping(mapping database.VCSEventMapping) codersdk.VCSEventMapping {
+	converted := codersdk.VCSEventMapping{
+		ID:         mapping.ID,
+		TemplateID: mapping.TemplateID,
+		Name:       mapping.Name,
+		Repository: mapping.Repository,
+		Branch:     mapping.Branch,
+		Action:     codersdk.VCSEventAction(mapping.Action),
+		CreatedBy:  mapping.CreatedBy,
+		CreatedAt:  mapping.CreatedAt,
+		UpdatedAt:  mapping.UpdatedAt,
+	}
+	if mapping.WorkspaceID.Valid {
+		converted.WorkspaceID = &mapping.WorkspaceID.UUID
+	}
+	if mapping.LastTriggeredAt.Valid {
+		converted.LastTriggeredAt = &mapping.LastTriggeredAt.Time
+	}
+	return converted
+}
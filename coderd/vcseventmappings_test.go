@@ -0,0 +1,220 @@
+package coderd_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/vcsevents"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVCSEventMappings(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	mapping, err := client.CreateTemplateVCSEventMapping(ctx, template.ID, codersdk.CreateVCSEventMappingRequest{
+		Name:          "main",
+		Repository:    "coder/coder",
+		Branch:        "main",
+		Action:        codersdk.VCSEventActionRefreshPrebuilds,
+		SigningSecret: "secret",
+	})
+	require.NoError(t, err)
+	require.Equal(t, template.ID, mapping.TemplateID)
+	require.Equal(t, user.UserID, mapping.CreatedBy)
+	require.Nil(t, mapping.LastTriggeredAt)
+
+	var apiErr *codersdk.Error
+	_, err = client.CreateTemplateVCSEventMapping(ctx, template.ID, codersdk.CreateVCSEventMappingRequest{
+		Name:          "main",
+		Repository:    "coder/coder",
+		Action:        codersdk.VCSEventActionUpdateWorkspaces,
+		SigningSecret: "secret",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	for _, req := range []codersdk.CreateVCSEventMappingRequest{
+		// The repository must include its owner.
+		{Name: "invalid", Repository: "coder", Action: codersdk.VCSEventActionRefreshPrebuilds, SigningSecret: "secret"},
+		// Starting a workspace requires one.
+		{Name: "invalid", Repository: "coder/coder", Action: codersdk.VCSEventActionStartWorkspace, SigningSecret: "secret"},
+		// The workspace must be a workspace of the template.
+		{Name: "invalid", Repository: "coder/coder", Action: codersdk.VCSEventActionStartWorkspace, WorkspaceID: &template.ID, SigningSecret: "secret"},
+		{Name: "invalid", Repository: "coder/coder", Action: "rebuild", SigningSecret: "secret"},
+	} {
+		_, err = client.CreateTemplateVCSEventMapping(ctx, template.ID, req)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	}
+
+	mappings, err := client.TemplateVCSEventMappings(ctx, template.ID)
+	require.NoError(t, err)
+	require.Len(t, mappings, 1)
+	require.Equal(t, mapping.ID, mappings[0].ID)
+
+	// Mappings are only visible to users that can update the template.
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	_, err = member.TemplateVCSEventMappings(ctx, template.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = client.DeleteTemplateVCSEventMapping(ctx, template.ID, mapping.ID)
+	require.NoError(t, err)
+	err = client.DeleteTemplateVCSEventMapping(ctx, template.ID, mapping.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestPostVCSEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitSuperLong)
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, Auditor: auditor})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	stop := coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
+
+	start, err := client.CreateTemplateVCSEventMapping(ctx, template.ID, codersdk.CreateVCSEventMappingRequest{
+		Name:          "start",
+		Repository:    "coder/coder",
+		Branch:        "main",
+		Action:        codersdk.VCSEventActionStartWorkspace,
+		WorkspaceID:   &workspace.ID,
+		SigningSecret: "github-secret",
+	})
+	require.NoError(t, err)
+	refresh, err := client.CreateTemplateVCSEventMapping(ctx, template.ID, codersdk.CreateVCSEventMappingRequest{
+		Name:          "refresh",
+		Repository:    "coder/coder",
+		Action:        codersdk.VCSEventActionRefreshPrebuilds,
+		SigningSecret: "github-secret",
+	})
+	require.NoError(t, err)
+	update, err := client.CreateTemplateVCSEventMapping(ctx, template.ID, codersdk.CreateVCSEventMappingRequest{
+		Name:          "update",
+		Repository:    "Coder/Coder",
+		Branch:        "release",
+		Action:        codersdk.VCSEventActionUpdateWorkspaces,
+		SigningSecret: "gitlab-secret",
+	})
+	require.NoError(t, err)
+
+	postGitHub := func(t *testing.T, event string, payload any, secret string) *http.Response {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		res, err := client.Request(ctx, http.MethodPost, "/api/v2/integrations/vcs/events", bytes.NewReader(body), func(r *http.Request) {
+			r.Header.Set("X-GitHub-Event", event)
+			r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(vcsevents.Sign(body, secret)))
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = res.Body.Close() })
+		return res
+	}
+	readResponse := func(t *testing.T, res *http.Response) codersdk.VCSEventResponse {
+		t.Helper()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var resp codersdk.VCSEventResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&resp))
+		return resp
+	}
+	push := func(ref string) map[string]any {
+		return map[string]any{
+			"ref":        ref,
+			"after":      "abc123",
+			"repository": map[string]any{"full_name": "coder/coder"},
+		}
+	}
+
+	// Deliveries that aren't signed for a mapping are rejected.
+	res := postGitHub(t, "push", push("refs/heads/main"), "wrong-secret")
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// Events other than pushes are acknowledged.
+	resp := readResponse(t, postGitHub(t, "ping", push(""), "github-secret"))
+	require.Empty(t, resp.Results)
+
+	// A push to main starts the stopped workspace, and refreshes the
+	// prebuilds of every branch.
+	resp = readResponse(t, postGitHub(t, "push", push("refs/heads/main"), "github-secret"))
+	require.Len(t, resp.Results, 2)
+	outcomes := map[uuid.UUID]codersdk.VCSEventOutcome{}
+	for _, result := range resp.Results {
+		outcomes[result.MappingID] = result.Outcome
+	}
+	require.Equal(t, codersdk.VCSEventOutcomeTriggered, outcomes[start.ID], resp.Results)
+	require.Equal(t, codersdk.VCSEventOutcomeTriggered, outcomes[refresh.ID], resp.Results)
+
+	workspace, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, stop.LatestBuild.BuildNumber+1, workspace.LatestBuild.BuildNumber)
+	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	require.Equal(t, user.UserID, workspace.LatestBuild.InitiatorID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	// The workspace is started already.
+	resp = readResponse(t, postGitHub(t, "push", push("refs/heads/main"), "github-secret"))
+	for _, result := range resp.Results {
+		if result.MappingID == start.ID {
+			require.Equal(t, codersdk.VCSEventOutcomeSkipped, result.Outcome)
+		}
+	}
+
+	// GitLab deliveries carry the secret as a token. Repositories match
+	// case-insensitively.
+	body, err := json.Marshal(map[string]any{
+		"ref":     "refs/heads/release",
+		"after":   "def456",
+		"project": map[string]any{"path_with_namespace": "coder/coder"},
+	})
+	require.NoError(t, err)
+	res, err = client.Request(ctx, http.MethodPost, "/api/v2/integrations/vcs/events", bytes.NewReader(body), func(r *http.Request) {
+		r.Header.Set("X-Gitlab-Event", "Push Hook")
+		r.Header.Set("X-Gitlab-Token", "gitlab-secret")
+	})
+	require.NoError(t, err)
+	defer res.Body.Close()
+	resp = readResponse(t, res)
+	require.Len(t, resp.Results, 1)
+	require.Equal(t, update.ID, resp.Results[0].MappingID)
+	require.Equal(t, codersdk.VCSEventOutcomeTriggered, resp.Results[0].Outcome, resp.Results[0].Message)
+
+	restarts, err := client.TemplateWorkspaceRestarts(ctx, template.ID)
+	require.NoError(t, err)
+	require.Len(t, restarts, 1)
+
+	mappings, err := client.TemplateVCSEventMappings(ctx, template.ID)
+	require.NoError(t, err)
+	for _, mapping := range mappings {
+		require.NotNil(t, mapping.LastTriggeredAt, mapping.Name)
+	}
+
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		Action:       database.AuditActionStart,
+		ResourceType: database.ResourceTypeVCSEventMapping,
+		ResourceID:   update.ID,
+		UserID:       user.UserID,
+	}))
+}
@@ -0,0 +1,150 @@
+// Package vcsevents parses and verifies the webhook deliveries of version
+// control systems, so that pushes to a repository can trigger workspace
+// actions.
+package vcsevents
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+type Provider string
+
+const (
+	ProviderGitHub Provider = "github"
+	ProviderGitLab Provider = "gitlab"
+)
+
+const (
+	githubEventHeader     = "X-GitHub-Event"
+	githubSignatureHeader = "X-Hub-Signature-256"
+	gitlabEventHeader     = "X-Gitlab-Event"
+	gitlabTokenHeader     = "X-Gitlab-Token"
+
+	// zeroCommit is reported as the new commit of a deleted branch.
+	zeroCommit = "0000000000000000000000000000000000000000"
+)
+
+// ErrUnknownProvider is returned for deliveries that come from neither GitHub
+// nor GitLab.
+var ErrUnknownProvider = xerrors.New("unknown vcs provider")
+
+// Event is a webhook delivery. Only pushes to a branch trigger actions; other
+// events, such as the ping sent when a webhook is created, are verified and
+// acknowledged.
+type Event struct {
+	Provider Provider
+	// Repository is the full name of the repository, e.g. "coder/coder".
+	Repository string
+	// Branch is set for pushes to a branch that wasn't deleted.
+	Branch string
+	Ref    string
+	Commit string
+}
+
+// IsBranchPush reports whether the event is a push to a branch.
+func (e Event) IsBranchPush() bool {
+	return e.Branch != ""
+}
+
+type githubPayload struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type gitlabPayload struct {
+	Ref     string `json:"ref"`
+	After   string `json:"after"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+}
+
+// Parse parses a webhook delivery from its headers and body. The delivery
+// must be verified with Verify before it's acted upon.
+func Parse(header http.Header, body []byte) (Event, error) {
+	switch {
+	case header.Get(githubEventHeader) != "":
+		var payload githubPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return Event{}, xerrors.Errorf("decode github payload: %w", err)
+		}
+		event := Event{
+			Provider:   ProviderGitHub,
+			Repository: payload.Repository.FullName,
+			Ref:        payload.Ref,
+			Commit:     payload.After,
+		}
+		if header.Get(githubEventHeader) == "push" && !payload.Deleted {
+			event.Branch = branchFromRef(payload.Ref)
+		}
+		return event, nil
+	case header.Get(gitlabEventHeader) != "":
+		var payload gitlabPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return Event{}, xerrors.Errorf("decode gitlab payload: %w", err)
+		}
+		event := Event{
+			Provider:   ProviderGitLab,
+			Repository: payload.Project.PathWithNamespace,
+			Ref:        payload.Ref,
+			Commit:     payload.After,
+		}
+		if header.Get(gitlabEventHeader) == "Push Hook" && payload.After != zeroCommit {
+			event.Branch = branchFromRef(payload.Ref)
+		}
+		return event, nil
+	default:
+		return Event{}, ErrUnknownProvider
+	}
+}
+
+// Verify reports whether a delivery was signed with the secret. GitHub signs
+// the body with an HMAC, while GitLab sends the secret as a token.
+func Verify(provider Provider, header http.Header, body []byte, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	switch provider {
+	case ProviderGitHub:
+		signature, ok := strings.CutPrefix(header.Get(githubSignatureHeader), "sha256=")
+		if !ok {
+			return false
+		}
+		got, err := hex.DecodeString(signature)
+		if err != nil {
+			return false
+		}
+		return hmac.Equal(got, Sign(body, secret))
+	case ProviderGitLab:
+		return subtle.ConstantTimeCompare([]byte(header.Get(gitlabTokenHeader)), []byte(secret)) == 1
+	default:
+		return false
+	}
+}
+
+// Sign returns the HMAC-SHA256 of a body, as sent by GitHub.
+func Sign(body []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
+
+func branchFromRef(ref string) string {
+	branch, ok := strings.CutPrefix(ref, "refs/heads/")
+	if !ok {
+		return ""
+	}
+	return branch
+}
@@ -0,0 +1,118 @@
+package vcsevents_test
+
+import (
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/vcsevents"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GitHubPush", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{"X-Github-Event": []string{"push"}}
+		event, err := vcsevents.Parse(header, []byte(`{"ref":"refs/heads/main","after":"abc123","repository":{"full_name":"coder/coder"}}`))
+		require.NoError(t, err)
+		require.Equal(t, vcsevents.Event{
+			Provider:   vcsevents.ProviderGitHub,
+			Repository: "coder/coder",
+			Branch:     "main",
+			Ref:        "refs/heads/main",
+			Commit:     "abc123",
+		}, event)
+		require.True(t, event.IsBranchPush())
+	})
+
+	t.Run("GitHubDeletedBranch", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{"X-Github-Event": []string{"push"}}
+		event, err := vcsevents.Parse(header, []byte(`{"ref":"refs/heads/main","deleted":true,"repository":{"full_name":"coder/coder"}}`))
+		require.NoError(t, err)
+		require.False(t, event.IsBranchPush())
+	})
+
+	t.Run("GitHubTag", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{"X-Github-Event": []string{"push"}}
+		event, err := vcsevents.Parse(header, []byte(`{"ref":"refs/tags/v1.0.0","repository":{"full_name":"coder/coder"}}`))
+		require.NoError(t, err)
+		require.False(t, event.IsBranchPush())
+	})
+
+	t.Run("GitHubPing", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{"X-Github-Event": []string{"ping"}}
+		event, err := vcsevents.Parse(header, []byte(`{"zen":"Keep it simple.","repository":{"full_name":"coder/coder"}}`))
+		require.NoError(t, err)
+		require.Equal(t, "coder/coder", event.Repository)
+		require.False(t, event.IsBranchPush())
+	})
+
+	t.Run("GitLabPush", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{"X-Gitlab-Event": []string{"Push Hook"}}
+		event, err := vcsevents.Parse(header, []byte(`{"ref":"refs/heads/dev","after":"def456","project":{"path_with_namespace":"group/project"}}`))
+		require.NoError(t, err)
+		require.Equal(t, vcsevents.ProviderGitLab, event.Provider)
+		require.Equal(t, "group/project", event.Repository)
+		require.Equal(t, "dev", event.Branch)
+		require.Equal(t, "def456", event.Commit)
+	})
+
+	t.Run("GitLabDeletedBranch", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{"X-Gitlab-Event": []string{"Push Hook"}}
+		event, err := vcsevents.Parse(header, []byte(`{"ref":"refs/heads/dev","after":"0000000000000000000000000000000000000000","project":{"path_with_namespace":"group/project"}}`))
+		require.NoError(t, err)
+		require.False(t, event.IsBranchPush())
+	})
+
+	t.Run("UnknownProvider", func(t *testing.T) {
+		t.Parallel()
+		_, err := vcsevents.Parse(http.Header{}, []byte(`{}`))
+		require.ErrorIs(t, err, vcsevents.ErrUnknownProvider)
+	})
+
+	t.Run("InvalidBody", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{"X-Github-Event": []string{"push"}}
+		_, err := vcsevents.Parse(header, []byte(`not json`))
+		require.Error(t, err)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"ref":"refs/heads/main"}`)
+
+	t.Run("GitHub", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{}
+		header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(vcsevents.Sign(body, "secret")))
+		require.True(t, vcsevents.Verify(vcsevents.ProviderGitHub, header, body, "secret"))
+		require.False(t, vcsevents.Verify(vcsevents.ProviderGitHub, header, body, "other"))
+		require.False(t, vcsevents.Verify(vcsevents.ProviderGitHub, header, []byte(`{}`), "secret"))
+		require.False(t, vcsevents.Verify(vcsevents.ProviderGitHub, http.Header{}, body, "secret"))
+	})
+
+	t.Run("GitLab", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{}
+		header.Set("X-Gitlab-Token", "secret")
+		require.True(t, vcsevents.Verify(vcsevents.ProviderGitLab, header, body, "secret"))
+		require.False(t, vcsevents.Verify(vcsevents.ProviderGitLab, header, body, "other"))
+	})
+
+	t.Run("EmptySecret", func(t *testing.T) {
+		t.Parallel()
+		header := http.Header{}
+		header.Set("X-Gitlab-Token", "")
+		require.False(t, vcsevents.Verify(vcsevents.ProviderGitLab, header, body, ""))
+	})
+}
@@ -0,0 +1,147 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/notifications"
+)
+
+const (
+	// agentCrashLoopStableAfter is how long an agent connection must last
+	// before it no longer counts towards a crash loop.
+	agentCrashLoopStableAfter = 5 * time.Minute
+	// agentCrashLoopWindow is how soon an agent must reconnect after a short
+	// connection for the connections to be counted as consecutive.
+	agentCrashLoopWindow = 15 * time.Minute
+	// agentCrashLoopThreshold is the number of consecutive short
+	// connections after which an agent is considered crash looping.
+	agentCrashLoopThreshold = 5
+	// agentCrashLoopBaseBackoff is how long a crash looping agent must wait
+	// before reconnecting. It doubles with every further short connection.
+	agentCrashLoopBaseBackoff = 5 * time.Second
+	agentCrashLoopMaxBackoff  = 5 * time.Minute
+)
+
+// nextAgentCrashLoop records a short agent connection on top of the previous
+// crash loop tracking of the agent, if any. started is true when this
+// connection is the one that made the agent crash looping.
+func nextAgentCrashLoop(prev *database.WorkspaceAgentCrashLoop, connectedAt, disconnectedAt time.Time) (next database.WorkspaceAgentCrashLoop, started bool) {
+	next.ShortConnections = 1
+	if prev != nil && connectedAt.Sub(prev.LastDisconnectedAt) <= agentCrashLoopWindow {
+		next.ShortConnections = prev.ShortConnections + 1
+		next.CrashLoopingSince = prev.CrashLoopingSince
+	}
+	next.LastDisconnectedAt = disconnectedAt
+	if next.ShortConnections >= agentCrashLoopThreshold && !next.CrashLoopingSince.Valid {
+		next.CrashLoopingSince = sql.NullTime{Time: disconnectedAt, Valid: true}
+		started = true
+	}
+	return next, started
+}
+
+// agentCrashLoopBackoff returns how long a crash looping agent must wait
+// after its last disconnect before it may reconnect.
+func agentCrashLoopBackoff(loop database.WorkspaceAgentCrashLoop) time.Duration {
+	if !loop.CrashLoopingSince.Valid {
+		return 0
+	}
+	backoff := agentCrashLoopBaseBackoff
+	for i := int32(agentCrashLoopThreshold); i < loop.ShortConnections; i++ {
+		backoff *= 2
+		if backoff >= agentCrashLoopMaxBackoff {
+			return agentCrashLoopMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// agentCrashLoopHealthReason explains why a crash looping agent is
+// unhealthy.
+func agentCrashLoopHealthReason(loop database.WorkspaceAgentCrashLoop) string {
+	return fmt.Sprintf("agent is crash looping: it disconnected %d times in a row shortly after connecting", loop.ShortConnections)
+}
+
+// recordShortConnection tracks a connection of the agent that ended before it
+// became stable, and notifies the workspace owner once the agent is crash
+// looping.
+func (m *agentConnectionMonitor) recordShortConnection(ctx context.Context, reason string) error {
+	// Connections closed because coderd is stopping, or because the agent
+	// belongs to an outdated build, are not crashes.
+	if m.apiCtx.Err() != nil || reason == errBuildIsOutdated.Error() {
+		return nil
+	}
+	agent, err := m.db.GetWorkspaceAgentByID(ctx, m.workspaceAgent.ID)
+	if err != nil {
+		return xerrors.Errorf("get workspace agent: %w", err)
+	}
+	// Agents that are shutting down disconnect on purpose.
+	switch agent.LifecycleState {
+	case database.WorkspaceAgentLifecycleStateShuttingDown,
+		database.WorkspaceAgentLifecycleStateShutdownTimeout,
+		database.WorkspaceAgentLifecycleStateShutdownError,
+		database.WorkspaceAgentLifecycleStateOff:
+		return nil
+	}
+
+	var prev *database.WorkspaceAgentCrashLoop
+	loop, err := m.db.GetWorkspaceAgentCrashLoopByAgentID(ctx, m.workspaceAgent.ID)
+	if err == nil {
+		prev = &loop
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get workspace agent crash loop: %w", err)
+	}
+
+	if reason == "canceled" {
+		reason = "connection closed"
+	}
+	next, started := nextAgentCrashLoop(prev, m.connectedAt, m.disconnectedAt.Time)
+	loop, err = m.db.UpsertWorkspaceAgentCrashLoop(ctx, database.UpsertWorkspaceAgentCrashLoopParams{
+		AgentID:              m.workspaceAgent.ID,
+		ShortConnections:     next.ShortConnections,
+		LastDisconnectedAt:   next.LastDisconnectedAt,
+		LastDisconnectReason: reason,
+		LastLifecycleState:   agent.LifecycleState,
+		CrashLoopingSince:    next.CrashLoopingSince,
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert workspace agent crash loop: %w", err)
+	}
+	if !started {
+		return nil
+	}
+
+	m.logger.Warn(ctx, "workspace agent is crash looping",
+		slog.F("short_connections", loop.ShortConnections),
+		slog.F("reason", reason),
+		slog.F("lifecycle_state", agent.LifecycleState))
+	if m.notificationsEnqueuer == nil {
+		return nil
+	}
+	if _, err := m.notificationsEnqueuer.Enqueue(
+		// nolint:gocritic // Need notifier actor to enqueue notifications.
+		dbauthz.AsNotifier(ctx),
+		m.workspace.OwnerID,
+		notifications.TemplateWorkspaceAgentCrashLooping,
+		map[string]string{
+			"workspace":   m.workspace.Name,
+			"agent":       m.workspaceAgent.Name,
+			"connections": strconv.Itoa(int(loop.ShortConnections)),
+			"last_exit":   fmt.Sprintf("%s (lifecycle state %s)", reason, agent.LifecycleState),
+		},
+		"workspace-agent-monitor",
+		m.workspace.ID, m.workspace.OwnerID, m.workspace.TemplateID, m.workspace.OrganizationID,
+	); err != nil {
+		return xerrors.Errorf("notify of crash looping agent: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package coderd
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/websocket"
+)
+
+func TestNextAgentCrashLoop(t *testing.T) {
+	t.Parallel()
+
+	now := dbtime.Now()
+	since := sql.NullTime{Time: now.Add(-time.Minute), Valid: true}
+	testCases := []struct {
+		name        string
+		prev        *database.WorkspaceAgentCrashLoop
+		connectedAt time.Time
+		connections int32
+		started     bool
+		looping     bool
+	}{
+		{
+			name:        "FirstShortConnection",
+			connectedAt: now.Add(-time.Minute),
+			connections: 1,
+		},
+		{
+			name:        "Consecutive",
+			prev:        &database.WorkspaceAgentCrashLoop{ShortConnections: 2, LastDisconnectedAt: now.Add(-2 * time.Minute)},
+			connectedAt: now.Add(-time.Minute),
+			connections: 3,
+		},
+		{
+			name:        "ReachesThreshold",
+			prev:        &database.WorkspaceAgentCrashLoop{ShortConnections: agentCrashLoopThreshold - 1, LastDisconnectedAt: now.Add(-2 * time.Minute)},
+			connectedAt: now.Add(-time.Minute),
+			connections: agentCrashLoopThreshold,
+			started:     true,
+			looping:     true,
+		},
+		{
+			name:        "AlreadyLooping",
+			prev:        &database.WorkspaceAgentCrashLoop{ShortConnections: agentCrashLoopThreshold, LastDisconnectedAt: now.Add(-2 * time.Minute), CrashLoopingSince: since},
+			connectedAt: now.Add(-time.Minute),
+			connections: agentCrashLoopThreshold + 1,
+			looping:     true,
+		},
+		{
+			name:        "ResetsAfterWindow",
+			prev:        &database.WorkspaceAgentCrashLoop{ShortConnections: agentCrashLoopThreshold, LastDisconnectedAt: now.Add(-time.Hour), CrashLoopingSince: since},
+			connectedAt: now.Add(-time.Minute),
+			connections: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			next, started := nextAgentCrashLoop(tc.prev, tc.connectedAt, now)
+			require.Equal(t, tc.connections, next.ShortConnections)
+			require.Equal(t, now, next.LastDisconnectedAt)
+			require.Equal(t, tc.started, started)
+			require.Equal(t, tc.looping, next.CrashLoopingSince.Valid)
+		})
+	}
+}
+
+func TestAgentCrashLoopBackoff(t *testing.T) {
+	t.Parallel()
+
+	since := sql.NullTime{Time: dbtime.Now(), Valid: true}
+	require.Zero(t, agentCrashLoopBackoff(database.WorkspaceAgentCrashLoop{ShortConnections: 3}))
+	require.Equal(t, agentCrashLoopBaseBackoff, agentCrashLoopBackoff(database.WorkspaceAgentCrashLoop{ShortConnections: agentCrashLoopThreshold, CrashLoopingSince: since}))
+	require.Equal(t, 4*agentCrashLoopBaseBackoff, agentCrashLoopBackoff(database.WorkspaceAgentCrashLoop{ShortConnections: agentCrashLoopThreshold + 2, CrashLoopingSince: since}))
+	require.Equal(t, agentCrashLoopMaxBackoff, agentCrashLoopBackoff(database.WorkspaceAgentCrashLoop{ShortConnections: 100, CrashLoopingSince: since}))
+}
+
+func TestAgentConnectionMonitor_CrashLoop(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	now := dbtime.Now()
+	fConn := &fakePingerCloser{}
+	ctrl := gomock.NewController(t)
+	mDB := dbmock.NewMockStore(ctrl)
+	fUpdater := &fakeUpdater{}
+	enqueuer := notificationstest.NewFakeEnqueuer()
+	logger := testutil.Logger(t)
+	agent := database.WorkspaceAgent{
+		ID:   uuid.New(),
+		Name: "main",
+		FirstConnectedAt: sql.NullTime{
+			Time:  now.Add(-time.Minute),
+			Valid: true,
+		},
+	}
+	workspace := database.Workspace{
+		ID:      uuid.New(),
+		OwnerID: uuid.New(),
+		Name:    "bobby-workspace",
+	}
+	build := database.WorkspaceBuild{
+		ID:          uuid.New(),
+		WorkspaceID: workspace.ID,
+	}
+	replicaID := uuid.New()
+
+	uut := &agentConnectionMonitor{
+		apiCtx:                ctx,
+		workspace:             workspace,
+		workspaceAgent:        agent,
+		workspaceBuild:        build,
+		conn:                  fConn,
+		db:                    mDB,
+		replicaID:             replicaID,
+		updater:               fUpdater,
+		logger:                logger,
+		pingPeriod:            testutil.IntervalFast,
+		disconnectTimeout:     testutil.WaitShort,
+		trackCrashLoops:       true,
+		notificationsEnqueuer: enqueuer,
+	}
+	uut.init()
+	// set the last ping to the past, so we go thru the timeout
+	uut.lastPing.Store(ptr.Ref(now.Add(-time.Hour)))
+
+	mDB.EXPECT().UpdateWorkspaceAgentConnectionByID(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(nil)
+	mDB.EXPECT().GetLatestWorkspaceBuildByWorkspaceID(gomock.Any(), build.WorkspaceID).
+		AnyTimes().
+		Return(database.WorkspaceBuild{ID: build.ID}, nil)
+	mDB.EXPECT().GetWorkspaceAgentByID(gomock.Any(), agent.ID).
+		Times(1).
+		Return(database.WorkspaceAgent{ID: agent.ID, LifecycleState: database.WorkspaceAgentLifecycleStateStartError}, nil)
+	// The agent already disconnected shortly after connecting one time
+	// less than the threshold.
+	mDB.EXPECT().GetWorkspaceAgentCrashLoopByAgentID(gomock.Any(), agent.ID).
+		Times(1).
+		Return(database.WorkspaceAgentCrashLoop{
+			AgentID:            agent.ID,
+			ShortConnections:   agentCrashLoopThreshold - 1,
+			LastDisconnectedAt: now.Add(-time.Minute),
+		}, nil)
+	mDB.EXPECT().UpsertWorkspaceAgentCrashLoop(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg database.UpsertWorkspaceAgentCrashLoopParams) (database.WorkspaceAgentCrashLoop, error) {
+			require.Equal(t, agent.ID, arg.AgentID)
+			require.EqualValues(t, agentCrashLoopThreshold, arg.ShortConnections)
+			require.Equal(t, "ping timeout", arg.LastDisconnectReason)
+			require.True(t, arg.CrashLoopingSince.Valid)
+			return database.WorkspaceAgentCrashLoop(arg), nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		uut.monitor(ctx)
+		close(done)
+	}()
+	fConn.requireEventuallyClosed(t, websocket.StatusGoingAway, "ping timeout")
+	_ = testutil.TryReceive(ctx, t, done) // ensure monitor() exits before mDB assertions are checked.
+
+	sent := enqueuer.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceAgentCrashLooping))
+	require.Len(t, sent, 1)
+	require.Equal(t, workspace.OwnerID, sent[0].UserID)
+	require.Equal(t, "main", sent[0].Labels["agent"])
+	require.Equal(t, "5", sent[0].Labels["connections"])
+	require.Equal(t, "ping timeout (lifecycle state start_error)", sent[0].Labels["last_exit"])
+}
@@ -0,0 +1,311 @@
+package coderd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// workspaceAgentFileAudit is recorded as the additional fields of the audit
+// log entry for a file transferred to or from a workspace agent.
+type workspaceAgentFileAudit struct {
+	WorkspaceName string    `json:"workspace_name"`
+	WorkspaceID   uuid.UUID `json:"workspace_id"`
+	AgentName     string    `json:"agent_name"`
+	Path          string    `json:"path"`
+	SizeBytes     int64     `json:"size_bytes"`
+}
+
+// @Summary Download file from workspace agent
+// @ID download-file-from-workspace-agent
+// @Security CoderSessionToken
+// @Produce octet-stream
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "Absolute path of the file in the workspace"
+// @Success 200
+// @Router /api/v2/workspaceagents/{workspaceagent}/files [get]
+func (api *API) workspaceAgentDownloadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+
+	fileAudit := &workspaceAgentFileAudit{
+		WorkspaceName: waws.WorkspaceTable.Name,
+		WorkspaceID:   waws.WorkspaceTable.ID,
+		AgentName:     waws.WorkspaceAgent.Name,
+	}
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionDownload,
+		OrganizationID:   waws.WorkspaceTable.OrganizationID,
+		AdditionalFields: fileAudit,
+	})
+	defer commitAudit()
+
+	// Reading arbitrary files from a workspace is equivalent to having a
+	// shell in it.
+	if !api.Authorize(r, policy.ActionSSH, waws.WorkspaceTable) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = waws.WorkspaceTable
+	aReq.New = waws.WorkspaceTable
+
+	path, ok := parseWorkspaceAgentFilePath(rw, r)
+	if !ok {
+		return
+	}
+	fileAudit.Path = path
+
+	agentConn, release, ok := api.dialWorkspaceAgentForFiles(rw, r, waws)
+	if !ok {
+		return
+	}
+	defer release()
+
+	// Probe for a byte past the limit so oversized files are rejected before
+	// anything is written to the response.
+	probe, _, err := agentConn.ReadFile(ctx, path, codersdk.WorkspaceAgentFileTransferMaxBytes, 1)
+	if err != nil {
+		writeWorkspaceAgentFileError(ctx, rw, err, "Internal error reading file from workspace agent.")
+		return
+	}
+	n, err := io.Copy(io.Discard, probe)
+	_ = probe.Close()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading file from workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if n > 0 {
+		httpapi.Write(ctx, rw, http.StatusRequestEntityTooLarge, codersdk.Response{
+			Message: fmt.Sprintf("File exceeds the maximum transfer size of %d bytes.", codersdk.WorkspaceAgentFileTransferMaxBytes),
+		})
+		return
+	}
+
+	body, mimeType, err := agentConn.ReadFile(ctx, path, 0, codersdk.WorkspaceAgentFileTransferMaxBytes)
+	if err != nil {
+		writeWorkspaceAgentFileError(ctx, rw, err, "Internal error reading file from workspace agent.")
+		return
+	}
+	defer body.Close()
+
+	rw.Header().Set("Content-Type", mimeType)
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", strconv.Quote(filepath.Base(path))))
+	rw.WriteHeader(http.StatusOK)
+	fileAudit.SizeBytes, err = io.Copy(rw, body)
+	if err != nil && ctx.Err() == nil {
+		api.Logger.Warn(ctx, "stream file from workspace agent",
+			slog.F("workspace_agent_id", waws.WorkspaceAgent.ID),
+			slog.F("path", path),
+			slog.Error(err),
+		)
+	}
+}
+
+// @Summary Upload file to workspace agent
+// @ID upload-file-to-workspace-agent
+// @Security CoderSessionToken
+// @Accept octet-stream
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "Absolute path of the file in the workspace"
+// @Success 200 {object} codersdk.Response
+// @Router /api/v2/workspaceagents/{workspaceagent}/files [post]
+func (api *API) workspaceAgentUploadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+
+	fileAudit := &workspaceAgentFileAudit{
+		WorkspaceName: waws.WorkspaceTable.Name,
+		WorkspaceID:   waws.WorkspaceTable.ID,
+		AgentName:     waws.WorkspaceAgent.Name,
+	}
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionUpload,
+		OrganizationID:   waws.WorkspaceTable.OrganizationID,
+		AdditionalFields: fileAudit,
+	})
+	defer commitAudit()
+
+	// Writing arbitrary files to a workspace is equivalent to having a shell
+	// in it.
+	if !api.Authorize(r, policy.ActionSSH, waws.WorkspaceTable) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = waws.WorkspaceTable
+	aReq.New = waws.WorkspaceTable
+
+	path, ok := parseWorkspaceAgentFilePath(rw, r)
+	if !ok {
+		return
+	}
+	fileAudit.Path = path
+
+	if r.ContentLength > codersdk.WorkspaceAgentFileTransferMaxBytes {
+		httpapi.Write(ctx, rw, http.StatusRequestEntityTooLarge, codersdk.Response{
+			Message: fmt.Sprintf("File exceeds the maximum transfer size of %d bytes.", codersdk.WorkspaceAgentFileTransferMaxBytes),
+		})
+		return
+	}
+
+	agentConn, release, ok := api.dialWorkspaceAgentForFiles(rw, r, waws)
+	if !ok {
+		return
+	}
+	defer release()
+
+	body := &countingReader{r: http.MaxBytesReader(rw, r.Body, codersdk.WorkspaceAgentFileTransferMaxBytes)}
+	err := agentConn.WriteFile(ctx, path, body)
+	fileAudit.SizeBytes = body.n
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			httpapi.Write(ctx, rw, http.StatusRequestEntityTooLarge, codersdk.Response{
+				Message: fmt.Sprintf("File exceeds the maximum transfer size of %d bytes.", codersdk.WorkspaceAgentFileTransferMaxBytes),
+			})
+			return
+		}
+		writeWorkspaceAgentFileError(ctx, rw, err, "Internal error writing file to workspace agent.")
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: fmt.Sprintf("Successfully uploaded %d bytes to %q.", body.n, path),
+	})
+}
+
+func parseWorkspaceAgentFilePath(rw http.ResponseWriter, r *http.Request) (string, bool) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	parser := httpapi.NewQueryParamParser().RequiredNotEmpty("path")
+	path := parser.String(query, "", "path")
+	parser.ErrorExcessParams(query)
+	if len(parser.Errors) == 0 && !filepath.IsAbs(path) && !isWindowsAbsPath(path) {
+		parser.Errors = append(parser.Errors, codersdk.ValidationError{
+			Field:  "path",
+			Detail: "Path must be absolute.",
+		})
+	}
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: parser.Errors,
+		})
+		return "", false
+	}
+	return path, true
+}
+
+// isWindowsAbsPath reports whether path looks like an absolute Windows path
+// (e.g. C:\Users\coder). coderd may not run on the same OS as the agent, so
+// filepath.IsAbs alone is not enough.
+func isWindowsAbsPath(path string) bool {
+	if len(path) < 3 || path[1] != ':' || (path[2] != '\\' && path[2] != '/') {
+		return false
+	}
+	c := path[0]
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// dialWorkspaceAgentForFiles checks that the agent is connected and dials
+// it. On failure the response has already been written.
+func (api *API) dialWorkspaceAgentForFiles(rw http.ResponseWriter, r *http.Request, waws database.GetWorkspaceAgentAndWorkspaceByIDRow) (workspacesdk.AgentConn, func(), bool) {
+	ctx := r.Context()
+
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(),
+		*api.TailnetCoordinator.Load(),
+		waws.WorkspaceAgent,
+		nil,
+		nil,
+		nil,
+		api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return nil, nil, false
+	}
+
+	// If the agent is unreachable, the request will hang. Assume that if we
+	// don't get a response after 30s that the agent is unreachable.
+	dialCtx, dialCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer dialCancel()
+	agentConn, release, err := api.agentProvider.AgentConn(dialCtx, waws.WorkspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	return agentConn, release, true
+}
+
+func writeWorkspaceAgentFileError(ctx context.Context, rw http.ResponseWriter, err error, message string) {
+	if errors.Is(err, context.Canceled) {
+		httpapi.Write(ctx, rw, http.StatusRequestTimeout, codersdk.Response{
+			Message: message,
+			Detail:  "Request timed out.",
+		})
+		return
+	}
+	// If the agent returns a codersdk.Error, we can return that directly.
+	if cerr, ok := codersdk.AsError(err); ok {
+		httpapi.Write(ctx, rw, cerr.StatusCode(), cerr.Response)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+		Message: message,
+		Detail:  err.Error(),
+	})
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,89 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// memoryEventHealthWindow is how far back memory events are counted
+	// when computing agent health.
+	memoryEventHealthWindow = 15 * time.Minute
+	// memoryEventsWindow is how far back memory events are listed by the
+	// workspace memory events endpoint.
+	memoryEventsWindow = 24 * time.Hour
+)
+
+// @Summary Get workspace memory events
+// @ID get-workspace-memory-events
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceAgentMemoryEvent
+// @Router /api/v2/workspaces/{workspace}/memory-events [get]
+func (api *API) workspaceMemoryEvents(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	agentIDs := make([]uuid.UUID, 0, len(agents))
+	for _, agent := range agents {
+		agentIDs = append(agentIDs, agent.ID)
+	}
+
+	// nolint:gocritic // The workspace has been authorized by the middleware.
+	events, err := api.Database.GetWorkspaceAgentMemoryEventsByAgentIDs(dbauthz.AsSystemRestricted(ctx), database.GetWorkspaceAgentMemoryEventsByAgentIDsParams{
+		IDs:          agentIDs,
+		CreatedAfter: dbtime.Now().Add(-memoryEventsWindow),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	apiEvents := make([]codersdk.WorkspaceAgentMemoryEvent, 0, len(events))
+	for _, event := range events {
+		apiEvents = append(apiEvents, codersdk.WorkspaceAgentMemoryEvent{
+			ID:             event.ID,
+			AgentID:        event.WorkspaceAgentID,
+			CreatedAt:      event.CreatedAt,
+			Kind:           codersdk.WorkspaceAgentMemoryEventKind(event.Kind),
+			OOMKills:       event.OomKills,
+			MemoryPressure: event.MemoryPressure,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiEvents)
+}
+
+func agentMemoryEventsHealthReason(counts database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow) string {
+	minutes := int(memoryEventHealthWindow.Minutes())
+	var reasons []string
+	switch {
+	case counts.OomKills == 1:
+		reasons = append(reasons, "1 process was killed for running out of memory")
+	case counts.OomKills > 1:
+		reasons = append(reasons, fmt.Sprintf("%d processes were killed for running out of memory", counts.OomKills))
+	}
+	if counts.MemoryPressureEvents > 0 {
+		reasons = append(reasons, "the workspace was under severe memory pressure")
+	}
+	return fmt.Sprintf("%s in the last %d minutes", strings.Join(reasons, " and "), minutes)
+}
@@ -0,0 +1,101 @@
+package coderd_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceMemoryEvents(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	agentID := r.Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Mark the agent as connected and ready so it would otherwise be healthy.
+	now := dbtime.Now()
+	err := db.UpdateWorkspaceAgentConnectionByID(ctx, database.UpdateWorkspaceAgentConnectionByIDParams{
+		ID:               agentID,
+		FirstConnectedAt: sql.NullTime{Time: now, Valid: true},
+		LastConnectedAt:  sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:        now,
+	})
+	require.NoError(t, err)
+	err = db.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+		ID:             agentID,
+		LifecycleState: database.WorkspaceAgentLifecycleStateReady,
+		StartedAt:      sql.NullTime{Time: now, Valid: true},
+		ReadyAt:        sql.NullTime{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// An old OOM kill shows up in the timeline but does not affect health.
+	_, err = db.InsertWorkspaceAgentMemoryEvent(ctx, database.InsertWorkspaceAgentMemoryEventParams{
+		ID:               uuid.New(),
+		WorkspaceAgentID: agentID,
+		CreatedAt:        now.Add(-time.Hour),
+		Kind:             database.WorkspaceAgentMemoryEventKindOomKill,
+		OomKills:         1,
+	})
+	require.NoError(t, err)
+
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.True(t, workspace.Health.Healthy)
+
+	_, err = db.InsertWorkspaceAgentMemoryEvent(ctx, database.InsertWorkspaceAgentMemoryEventParams{
+		ID:               uuid.New(),
+		WorkspaceAgentID: agentID,
+		CreatedAt:        now.Add(-time.Minute),
+		Kind:             database.WorkspaceAgentMemoryEventKindMemoryPressure,
+		MemoryPressure:   42.5,
+	})
+	require.NoError(t, err)
+	_, err = db.InsertWorkspaceAgentMemoryEvent(ctx, database.InsertWorkspaceAgentMemoryEventParams{
+		ID:               uuid.New(),
+		WorkspaceAgentID: agentID,
+		CreatedAt:        now,
+		Kind:             database.WorkspaceAgentMemoryEventKindOomKill,
+		OomKills:         3,
+		MemoryPressure:   50,
+	})
+	require.NoError(t, err)
+
+	events, err := client.WorkspaceMemoryEvents(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, codersdk.WorkspaceAgentMemoryEventKindOOMKill, events[0].Kind)
+	require.EqualValues(t, 3, events[0].OOMKills)
+	require.Equal(t, codersdk.WorkspaceAgentMemoryEventKindMemoryPressure, events[1].Kind)
+	require.Equal(t, 42.5, events[1].MemoryPressure)
+	for _, event := range events {
+		require.Equal(t, agentID, event.AgentID)
+	}
+
+	// Recent events mark the agent and the workspace as unhealthy.
+	workspace, err = client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.False(t, workspace.Health.Healthy)
+	require.Equal(t, []uuid.UUID{agentID}, workspace.Health.FailingAgents)
+	agent := workspace.LatestBuild.Resources[0].Agents[0]
+	require.False(t, agent.Health.Healthy)
+	require.Contains(t, agent.Health.Reason, "3 processes were killed for running out of memory")
+	require.Contains(t, agent.Health.Reason, "severe memory pressure")
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"slices"
@@ -42,10 +43,12 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	maputil "github.com/coder/coder/v2/coderd/util/maps"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/coderd/x/gitsync"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/codersdk/healthsdk"
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/coder/v2/codersdk/wsjson"
 	"github.com/coder/coder/v2/tailnet"
@@ -788,6 +791,78 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusOK, portsResponse)
 }
 
+// @Summary Run network diagnostics for workspace agent
+// @ID run-network-diagnostics-for-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} healthsdk.AgentNetworkDiagnosticsReport
+// @Router /api/v2/workspaceagents/{workspaceagent}/netcheck [post]
+func (api *API) postWorkspaceAgentNetcheck(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+
+	if !api.Authorize(r, policy.ActionUpdate, waws.WorkspaceTable) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	// The agent probes every DERP region and STUN server, which takes a few
+	// seconds. If the agent is unreachable, the request will hang, so give up
+	// after 30s.
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(), *api.TailnetCoordinator.Load(), waws.WorkspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	agentConn, release, err := api.agentProvider.AgentConn(ctx, waws.WorkspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	report, err := agentConn.RunNetcheck(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error running network diagnostics.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Agents that are too old to report their time leave it empty.
+	if !report.Time.IsZero() {
+		clockSkew := healthsdk.NewClockSkewReport(report.Time.Sub(dbtime.Now()))
+		report.ClockSkew = &clockSkew
+		if clockSkew.Severity.Value() > report.Severity.Value() {
+			report.Severity = clockSkew.Severity
+		}
+		report.Warnings = append(report.Warnings, clockSkew.Warnings...)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, report)
+}
+
 // @Summary Watch workspace agent for container updates.
 // @ID watch-workspace-agent-for-container-updates
 // @Security CoderSessionToken
@@ -1173,6 +1248,120 @@ func (api *API) workspaceAgentRecreateDevcontainer(rw http.ResponseWriter, r *ht
 	httpapi.Write(ctx, rw, http.StatusAccepted, m)
 }
 
+// @Summary Rebuild devcontainer for workspace agent by name
+// @Description Rebuilds the devcontainer image without the build cache and
+// @Description recreates the container, without a workspace build. Progress
+// @Description is streamed to the agent logs.
+// @ID rebuild-devcontainer-for-workspace-agent-by-name
+// @Security CoderSessionToken
+// @Tags Agents
+// @Produce json
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param name path string true "Devcontainer name"
+// @Success 202 {object} codersdk.Response
+// @Router /api/v2/workspaceagents/{workspaceagent}/containers/{name}/recreate [post]
+func (api *API) workspaceAgentRebuildDevcontainer(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+
+	if !api.Authorize(r, policy.ActionUpdate, waws.WorkspaceTable) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Devcontainer name is required.",
+			Validations: []codersdk.ValidationError{
+				{Field: "name", Detail: "Devcontainer name is required."},
+			},
+		})
+		return
+	}
+
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(),
+		*api.TailnetCoordinator.Load(),
+		waws.WorkspaceAgent,
+		nil,
+		nil,
+		nil,
+		api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	// If the agent is unreachable, the request will hang. Assume that if we
+	// don't get a response after 30s that the agent is unreachable.
+	dialCtx, dialCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer dialCancel()
+	agentConn, release, err := api.agentProvider.AgentConn(dialCtx, waws.WorkspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(dialCtx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	// The agent addresses devcontainers by ID, which changes when the agent
+	// restarts, so resolve the stable name first.
+	containers, err := agentConn.ListContainers(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching devcontainers.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	idx := slices.IndexFunc(containers.Devcontainers, func(dc codersdk.WorkspaceAgentDevcontainer) bool {
+		return dc.Name == name
+	})
+	if idx < 0 {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Devcontainer %q not found.", name),
+		})
+		return
+	}
+
+	m, err := agentConn.RebuildDevcontainer(ctx, containers.Devcontainers[idx].ID.String())
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			httpapi.Write(ctx, rw, http.StatusRequestTimeout, codersdk.Response{
+				Message: "Failed to rebuild devcontainer from agent.",
+				Detail:  "Request timed out.",
+			})
+			return
+		}
+		// If the agent returns a codersdk.Error, we can return that directly.
+		if cerr, ok := codersdk.AsError(err); ok {
+			httpapi.Write(ctx, rw, cerr.StatusCode(), cerr.Response)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error rebuilding devcontainer.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, m)
+}
+
 // @Summary Get connection info for workspace agent
 // @ID get-connection-info-for-workspace-agent
 // @Security CoderSessionToken
@@ -1348,6 +1537,28 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Users the workspace is shared with may be restricted to some ports,
+	// which only the agent can enforce.
+	var (
+		allowedPorts []uint16
+		restricted   bool
+	)
+	apiKey, ok := httpmw.APIKeyOptional(r)
+	if ok && apiKey.UserID != waws.WorkspaceTable.OwnerID {
+		allowedPorts, restricted, err = workspacePortForwardRestriction(ctx, api.Database, waws.WorkspaceTable.ID, apiKey.UserID)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if restricted && !agentEnforcesAllowedPorts(waws.WorkspaceAgent.APIVersion) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "You may only connect to some ports of this workspace, but its agent does not support port restrictions.",
+				Detail:  fmt.Sprintf("The agent uses API version %q, but at least %q is required. Update the agent.", waws.WorkspaceAgent.APIVersion, allowedPortsAPIVersion.String()),
+			})
+			return
+		}
+	}
+
 	peerID, err := api.handleResumeToken(ctx, rw, r)
 	if err != nil {
 		// handleResumeToken has already written the response.
@@ -1372,12 +1583,20 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 
 	ctx = api.wsWatcher.Watch(ctx, api.Logger, conn)
 
+	if restricted {
+		// Restricted sessions are logged, since they are not logged by the
+		// agent for port forwarding.
+		api.logPortForwardSession(ctx, r, waws, apiKey.UserID, peerID, allowedPorts, database.ConnectionStatusConnected)
+		defer api.logPortForwardSession(context.WithoutCancel(ctx), r, waws, apiKey.UserID, peerID, allowedPorts, database.ConnectionStatusDisconnected)
+	}
+
 	defer conn.Close(websocket.StatusNormalClosure, "")
 	err = api.TailnetClientService.ServeClient(ctx, version, wsNetConn, tailnet.StreamID{
 		Name: "client",
 		ID:   peerID,
 		Auth: tailnet.ClientCoordinateeAuth{
-			AgentID: waws.WorkspaceAgent.ID,
+			AgentID:      waws.WorkspaceAgent.ID,
+			AllowedPorts: allowedPorts,
 		},
 	})
 	if err != nil && !xerrors.Is(err, io.EOF) && !xerrors.Is(err, context.Canceled) {
@@ -1465,6 +1684,101 @@ func (api *API) workspaceAgentPostLogSource(rw http.ResponseWriter, r *http.Requ
 	httpapi.Write(ctx, rw, http.StatusCreated, apiSource)
 }
 
+// @Summary Post workspace agent bootstrap progress
+// @ID post-workspace-agent-bootstrap-progress
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.PostBootstrapProgressRequest true "Bootstrap progress request"
+// @Success 201 {object} codersdk.WorkspaceAgentBootstrapProgress
+// @Router /api/v2/workspaceagents/me/bootstrap-progress [post]
+func (api *API) workspaceAgentPostBootstrapProgress(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req agentsdk.PostBootstrapProgressRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Percent != nil && (*req.Percent < 0 || *req.Percent > 100) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid bootstrap progress.",
+			Validations: []codersdk.ValidationError{
+				{Field: "percent", Detail: "Must be between 0 and 100."},
+			},
+		})
+		return
+	}
+
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var percent sql.NullInt32
+	if req.Percent != nil {
+		percent = sql.NullInt32{Int32: *req.Percent, Valid: true}
+	}
+	progress, err := api.Database.InsertWorkspaceAgentBootstrapProgress(ctx, database.InsertWorkspaceAgentBootstrapProgressParams{
+		ID:               uuid.New(),
+		WorkspaceAgentID: workspaceAgent.ID,
+		CreatedAt:        dbtime.Now(),
+		Name:             req.Name,
+		Percent:          percent,
+		Message:          req.Message,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindAgentBootstrapUpdate,
+		WorkspaceID: workspace.ID,
+		AgentID:     &workspaceAgent.ID,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWorkspaceAgentBootstrapProgress(progress))
+}
+
+// @Summary Get workspace agent bootstrap progress
+// @ID get-workspace-agent-bootstrap-progress
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceAgentBootstrapProgress
+// @Router /api/v2/workspaceagents/{workspaceagent}/bootstrap-progress [get]
+func (api *API) workspaceAgentBootstrapProgress(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+
+	progress, err := api.Database.GetWorkspaceAgentBootstrapProgressByAgentID(ctx, waws.WorkspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	apiProgress := make([]codersdk.WorkspaceAgentBootstrapProgress, 0, len(progress))
+	for _, p := range progress {
+		apiProgress = append(apiProgress, convertWorkspaceAgentBootstrapProgress(p))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiProgress)
+}
+
+func convertWorkspaceAgentBootstrapProgress(progress database.WorkspaceAgentBootstrapProgress) codersdk.WorkspaceAgentBootstrapProgress {
+	apiProgress := codersdk.WorkspaceAgentBootstrapProgress{
+		Name:      progress.Name,
+		Message:   progress.Message,
+		CreatedAt: progress.CreatedAt,
+	}
+	if progress.Percent.Valid {
+		apiProgress.Percent = ptr.Ref(progress.Percent.Int32)
+	}
+	return apiProgress
+}
+
 // @Summary Get workspace agent reinitialization
 // @ID get-workspace-agent-reinitialization
 // @Security CoderSessionToken
@@ -1944,6 +2258,102 @@ func convertWorkspaceAgentMetadata(db []database.WorkspaceAgentMetadatum) []code
 // workspaceAgentsExternalAuth returns an access token for a given URL
 // or finds a provider by ID.
 //
+// maxWorkspaceAgentMetadataHistoryPoints bounds the values returned by the
+// metadata history endpoint. Longer histories are downsampled to fit.
+const maxWorkspaceAgentMetadataHistoryPoints = 1000
+
+// @Summary Get workspace agent metadata history
+// @ID get-workspace-agent-metadata-history
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param key path string true "Metadata key"
+// @Param since query string false "Only return values collected at or after this time, defaults to one hour ago" format(date-time)
+// @Param interval query string false "Downsample to the latest value per interval, e.g. 1m"
+// @Success 200 {object} codersdk.WorkspaceAgentMetadataHistory
+// @Router /api/v2/workspaceagents/{workspaceagent}/metadata/{key}/history [get]
+func (api *API) workspaceAgentMetadataHistory(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+	key := chi.URLParam(r, "key")
+
+	if api.DeploymentValues.Retention.WorkspaceAgentMetadataHistory.Value() <= 0 {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Workspace agent metadata history is disabled.",
+			Detail:  "Set a workspace agent metadata history retention to record it.",
+		})
+		return
+	}
+
+	now := api.Clock.Now()
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	since := p.Time3339Nano(vals, now.Add(-time.Hour), "since")
+	interval := p.Duration(vals, 0, "interval")
+	p.ErrorExcessParams(vals)
+	if interval < 0 {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "interval",
+			Detail: "Query param \"interval\" must not be negative.",
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	metadata, err := api.Database.GetWorkspaceAgentMetadata(ctx, database.GetWorkspaceAgentMetadataParams{
+		WorkspaceAgentID: waws.WorkspaceAgent.ID,
+		Keys:             []string{key},
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if len(metadata) == 0 {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Workspace agent has no metadata with key %q.", key),
+		})
+		return
+	}
+
+	// Downsample long histories so graphs stay responsive.
+	if minInterval := now.Sub(since) / maxWorkspaceAgentMetadataHistoryPoints; interval < minInterval {
+		interval = minInterval
+	}
+	bucketSeconds := int64(math.Ceil(interval.Seconds()))
+
+	rows, err := api.Database.GetWorkspaceAgentMetadataHistory(ctx, database.GetWorkspaceAgentMetadataHistoryParams{
+		WorkspaceAgentID: waws.WorkspaceAgent.ID,
+		Key:              key,
+		Since:            since,
+		BucketSeconds:    bucketSeconds,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	values := make([]codersdk.WorkspaceAgentMetadataHistoryValue, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, codersdk.WorkspaceAgentMetadataHistoryValue{
+			CollectedAt: row.CollectedAt,
+			Value:       row.Value,
+			Error:       row.Error,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentMetadataHistory{
+		Key:             key,
+		Since:           since,
+		IntervalSeconds: bucketSeconds,
+		Values:          values,
+	})
+}
+
 // @Summary Get workspace agent external auth
 // @ID get-workspace-agent-external-auth
 // @Security CoderSessionToken
@@ -2335,6 +2745,7 @@ func (api *API) tailnetRPCConn(rw http.ResponseWriter, r *http.Request) {
 			Auth: &rbacAuthorizer{
 				sshPrep: sshPrep,
 				db:      api.Database,
+				userID:  apiKey.UserID,
 			},
 		},
 	})
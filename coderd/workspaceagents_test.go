@@ -41,6 +41,7 @@ import (
 	"github.com/coder/coder/v2/agent/agenttest"
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/agentapi/metadatabatcher"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
 	"github.com/coder/coder/v2/coderd/database"
@@ -52,6 +53,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
 	"github.com/coder/coder/v2/coderd/jwtutils"
 	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -59,6 +61,7 @@ import (
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/codersdk/healthsdk"
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
@@ -1359,6 +1362,30 @@ func TestWorkspaceAgentListeningPorts(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentNetcheck(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.NewWorkspaceAgentWaiter(t, client, r.Workspace.ID).Wait()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	report, err := healthsdk.New(client).WorkspaceAgentNetcheck(ctx, resources[0].Agents[0].ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Severity)
+	// The agent probes the embedded DERP server of coderd.
+	require.NotEmpty(t, report.DERP.Regions)
+	require.False(t, report.DirectConnection.Disabled)
+	// The agent runs on the same clock as coderd.
+	require.NotNil(t, report.ClockSkew)
+	require.Equal(t, health.SeverityOK, report.ClockSkew.Severity)
+}
+
 func TestWorkspaceAgentContainers(t *testing.T) {
 	t.Parallel()
 
@@ -1879,6 +1906,97 @@ func TestWorkspaceAgentRecreateDevcontainer(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentRebuildDevcontainer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		workspaceFolder = t.TempDir()
+		configFile      = filepath.Join(workspaceFolder, ".devcontainer", "devcontainer.json")
+		devContainer    = codersdk.WorkspaceAgentContainer{
+			ID:           uuid.NewString(),
+			CreatedAt:    dbtime.Now(),
+			FriendlyName: testutil.GetRandomName(t),
+			Image:        "busybox:latest",
+			Labels: map[string]string{
+				agentcontainers.DevcontainerLocalFolderLabel: workspaceFolder,
+				agentcontainers.DevcontainerConfigFileLabel:  configFile,
+			},
+			Running: true,
+			Status:  "running",
+		}
+		devcontainer = codersdk.WorkspaceAgentDevcontainer{
+			ID:              uuid.New(),
+			Name:            "test-devcontainer",
+			WorkspaceFolder: workspaceFolder,
+			ConfigPath:      configFile,
+			Status:          codersdk.WorkspaceAgentDevcontainerStatusRunning,
+			Container:       &devContainer,
+		}
+
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		mCtrl      = gomock.NewController(t)
+		mCCLI      = acmock.NewMockContainerCLI(mCtrl)
+		mDCCLI     = acmock.NewMockDevcontainerCLI(mCtrl)
+		logger     = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+		client, db = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			Logger: &logger,
+		})
+		user = coderdtest.CreateFirstUser(t, client)
+		r    = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+			return agents
+		}).Do()
+	)
+
+	mCCLI.EXPECT().List(gomock.Any()).Return(codersdk.WorkspaceAgentListContainersResponse{
+		Containers: []codersdk.WorkspaceAgentContainer{devContainer},
+	}, nil).AnyTimes()
+	// DetectArchitecture always returns "<none>" for this test to disable agent injection.
+	mCCLI.EXPECT().DetectArchitecture(gomock.Any(), devContainer.ID).Return("<none>", nil).AnyTimes()
+	mDCCLI.EXPECT().ReadConfig(gomock.Any(), workspaceFolder, configFile, gomock.Any()).Return(agentcontainers.DevcontainerConfig{}, nil).AnyTimes()
+
+	upArgs := make(chan []string, 1)
+	mDCCLI.EXPECT().Up(gomock.Any(), workspaceFolder, configFile, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, opts ...agentcontainers.DevcontainerCLIUpOptions) (string, error) {
+			var conf agentcontainers.DevcontainerCLIUpConfig
+			for _, opt := range opts {
+				opt(&conf)
+			}
+			upArgs <- conf.Args
+			return "someid", nil
+		}).Times(1)
+
+	_ = agenttest.New(t, client.URL, r.AgentToken, func(o *agent.Options) {
+		o.Logger = logger.Named("agent")
+		o.Devcontainers = true
+		o.DevcontainerAPIOptions = []agentcontainers.Option{
+			agentcontainers.WithContainerCLI(mCCLI),
+			agentcontainers.WithDevcontainerCLI(mDCCLI),
+			agentcontainers.WithWatcher(watcher.NewNoop()),
+			agentcontainers.WithDevcontainers([]codersdk.WorkspaceAgentDevcontainer{devcontainer}, nil),
+		}
+	})
+	resources := coderdtest.NewWorkspaceAgentWaiter(t, client, r.Workspace.ID).Wait()
+	require.Len(t, resources, 1, "expected one resource")
+	require.Len(t, resources[0].Agents, 1, "expected one agent")
+	agentID := resources[0].Agents[0].ID
+
+	// Unknown names are rejected.
+	_, err := client.WorkspaceAgentRebuildDevcontainer(ctx, agentID, "does-not-exist")
+	cerr, ok := codersdk.AsError(err)
+	require.True(t, ok, "expected error to be a coder error")
+	require.Equal(t, http.StatusNotFound, cerr.StatusCode())
+
+	// The devcontainer is rebuilt without the build cache.
+	_, err = client.WorkspaceAgentRebuildDevcontainer(ctx, agentID, devcontainer.Name)
+	require.NoError(t, err, "failed to rebuild devcontainer")
+	args := testutil.TryReceive(ctx, t, upArgs)
+	require.Contains(t, args, "--remove-existing-container")
+	require.Contains(t, args, "--build-no-cache")
+}
+
 func TestWorkspaceAgentRecreateDevcontainerAuthorization(t *testing.T) {
 	t.Parallel()
 
@@ -2075,6 +2193,81 @@ func TestWorkspaceAgentDeleteDevcontainer(t *testing.T) {
 	}
 }
 
+func TestWorkspaceAgentFiles(t *testing.T) {
+	t.Parallel()
+
+	auditor := audit.NewMock()
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.NewWorkspaceAgentWaiter(t, client, r.Workspace.ID).Wait()
+	agentID := resources[0].Agents[0].ID
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		path := filepath.Join(t.TempDir(), "nested", "hello.txt")
+		err := client.WorkspaceAgentUploadFile(ctx, agentID, path, strings.NewReader("hello world"))
+		require.NoError(t, err)
+
+		body, _, err := client.WorkspaceAgentDownloadFile(ctx, agentID, path)
+		require.NoError(t, err)
+		got, err := io.ReadAll(body)
+		_ = body.Close()
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(got))
+
+		require.Eventually(t, func() bool {
+			var uploaded, downloaded bool
+			for _, log := range auditor.AuditLogs() {
+				if log.ResourceID != r.Workspace.ID || !strings.Contains(string(log.AdditionalFields), path) {
+					continue
+				}
+				uploaded = uploaded || log.Action == database.AuditActionUpload
+				downloaded = downloaded || log.Action == database.AuditActionDownload
+			}
+			return uploaded && downloaded
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("RelativePath", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		err := client.WorkspaceAgentUploadFile(ctx, agentID, "relative.txt", strings.NewReader("data"))
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, _, err := client.WorkspaceAgentDownloadFile(ctx, agentID, filepath.Join(t.TempDir(), "missing.txt"))
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, _, err := otherClient.WorkspaceAgentDownloadFile(ctx, agentID, filepath.Join(t.TempDir(), "hello.txt"))
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
 func TestWorkspaceAgentAppHealth(t *testing.T) {
 	t.Parallel()
 	client, db := coderdtest.NewWithDatabase(t, nil)
@@ -2212,6 +2405,59 @@ func TestWorkspaceAgentPostLogSource(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentBootstrapProgress(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	agentClient := agentsdk.New(client.URL, agentsdk.WithFixedToken(r.AgentToken))
+	agentID := r.Agents[0].ID
+
+	// Percent must be a percentage.
+	_, err := agentClient.PostBootstrapProgress(ctx, agentsdk.PostBootstrapProgressRequest{
+		Name:    "cloning repo",
+		Percent: ptr.Ref[int32](101),
+	})
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+	_, err = agentClient.PostBootstrapProgress(ctx, agentsdk.PostBootstrapProgressRequest{
+		Name:    "cloning repo",
+		Percent: ptr.Ref[int32](40),
+	})
+	require.NoError(t, err)
+	res, err := agentClient.PostBootstrapProgress(ctx, agentsdk.PostBootstrapProgressRequest{
+		Name:    "installing dependencies",
+		Message: "npm install",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "installing dependencies", res.Name)
+	require.Nil(t, res.Percent)
+
+	progress, err := client.WorkspaceAgentBootstrapProgress(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, progress, 2)
+	require.Equal(t, "cloning repo", progress[0].Name)
+	require.NotNil(t, progress[0].Percent)
+	require.EqualValues(t, 40, *progress[0].Percent)
+	require.Equal(t, "installing dependencies", progress[1].Name)
+	require.Equal(t, "npm install", progress[1].Message)
+
+	// The workspace reports the latest milestone of each agent.
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agent := workspace.LatestBuild.Resources[0].Agents[0]
+	require.NotNil(t, agent.BootstrapProgress)
+	require.Equal(t, "installing dependencies", agent.BootstrapProgress.Name)
+}
+
 func TestWorkspaceAgent_LifecycleState(t *testing.T) {
 	t.Parallel()
 
@@ -2541,6 +2787,69 @@ func TestWorkspaceAgent_Metadata_DisplayOrder(t *testing.T) {
 	require.Equal(t, "Fourth Meta", update[3].Description.DisplayName)
 }
 
+func TestWorkspaceAgent_MetadataHistory(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	err := dv.Retention.WorkspaceAgentMetadataHistory.Set("24h")
+	require.NoError(t, err)
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		DeploymentValues: dv,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Metadata = []*proto.Agent_Metadata{{
+			DisplayName: "GPU Temperature",
+			Key:         "gpu_temperature",
+			Script:      "nvidia-smi --query-gpu=temperature.gpu --format=csv,noheader",
+			Interval:    10,
+			Timeout:     3,
+		}}
+		return agents
+	}).Do()
+	agentID := r.Agents[0].ID
+
+	now := dbtime.Now()
+	//nolint:gocritic // Recording history is done by the metadata batcher.
+	err = db.InsertWorkspaceAgentMetadataHistory(dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort)), database.InsertWorkspaceAgentMetadataHistoryParams{
+		WorkspaceAgentID: []uuid.UUID{agentID, agentID, agentID},
+		Key:              []string{"gpu_temperature", "gpu_temperature", "gpu_temperature"},
+		Value:            []string{"65", "68", "71"},
+		Error:            []string{"", "", ""},
+		CollectedAt:      []time.Time{now.Add(-3 * time.Minute), now.Add(-2*time.Minute - 30*time.Second), now.Add(-time.Minute)},
+	})
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	history, err := client.WorkspaceAgentMetadataHistory(ctx, agentID, "gpu_temperature", codersdk.WorkspaceAgentMetadataHistoryRequest{
+		Since: now.Add(-10 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, history.Values, 3)
+	require.Equal(t, "65", history.Values[0].Value)
+	require.Equal(t, "71", history.Values[2].Value)
+
+	// Downsampling keeps the latest value of each bucket.
+	history, err = client.WorkspaceAgentMetadataHistory(ctx, agentID, "gpu_temperature", codersdk.WorkspaceAgentMetadataHistoryRequest{
+		Since:    now.Add(-10 * time.Minute),
+		Interval: time.Hour,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 3600, history.IntervalSeconds)
+	require.NotEmpty(t, history.Values)
+	require.LessOrEqual(t, len(history.Values), 2)
+	require.Equal(t, "71", history.Values[len(history.Values)-1].Value)
+
+	// Unknown keys are not found.
+	_, err = client.WorkspaceAgentMetadataHistory(ctx, agentID, "unknown", codersdk.WorkspaceAgentMetadataHistoryRequest{})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
 type testWAMErrorStore struct {
 	database.Store
 	err atomic.Pointer[error]
@@ -0,0 +1,326 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// @Summary Post workspace agent service status
+// @ID post-workspace-agent-service-status
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param name path string true "Service name"
+// @Param request body agentsdk.PostServiceStatusRequest true "Service status request"
+// @Success 204
+// @Router /api/v2/workspaceagents/me/services/{name}/status [post]
+func (api *API) workspaceAgentPostServiceStatus(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req agentsdk.PostServiceStatusRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	status := database.WorkspaceAgentServiceStatus(req.Status)
+	if !status.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid service status.",
+			Validations: []codersdk.ValidationError{
+				{Field: "status", Detail: fmt.Sprintf("%q is not a valid service status.", req.Status)},
+			},
+		})
+		return
+	}
+
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	name := chi.URLParam(r, "name")
+
+	// nolint:gocritic // Agents may read the services declared for them.
+	services, err := api.Database.GetWorkspaceAgentServicesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if !slices.ContainsFunc(services, func(service database.WorkspaceAgentService) bool {
+		return service.Name == name
+	}) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Service %q not found.", name),
+		})
+		return
+	}
+
+	var exitCode sql.NullInt32
+	if req.ExitCode != nil {
+		exitCode = sql.NullInt32{Int32: *req.ExitCode, Valid: true}
+	}
+	_, err = api.Database.InsertWorkspaceAgentServiceTransition(ctx, database.InsertWorkspaceAgentServiceTransitionParams{
+		ID:               uuid.New(),
+		WorkspaceAgentID: workspaceAgent.ID,
+		ServiceName:      name,
+		CreatedAt:        dbtime.Now(),
+		Status:           status,
+		Message:          req.Message,
+		ExitCode:         exitCode,
+		RestartCount:     req.RestartCount,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindAgentServiceUpdate,
+		WorkspaceID: workspace.ID,
+		AgentID:     &workspaceAgent.ID,
+	})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get workspace agent services
+// @ID get-workspace-agent-services
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceAgentService
+// @Router /api/v2/workspaceagents/{workspaceagent}/services [get]
+func (api *API) workspaceAgentServices(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+
+	services, transitions, err := api.workspaceAgentServicesData(ctx, []uuid.UUID{waws.WorkspaceAgent.ID})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceAgentServices(services, transitions))
+}
+
+// @Summary Restart workspace agent service
+// @Description Restarts a service supervised by the agent, regardless of its
+// @Description restart policy. The restart happens in the background and is
+// @Description reported through the status of the service.
+// @ID restart-workspace-agent-service
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param name path string true "Service name"
+// @Success 202 {object} codersdk.Response
+// @Router /api/v2/workspaceagents/{workspaceagent}/services/{name}/restart [post]
+func (api *API) workspaceAgentRestartService(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+
+	if !api.Authorize(r, policy.ActionUpdate, waws.WorkspaceTable) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(),
+		*api.TailnetCoordinator.Load(),
+		waws.WorkspaceAgent,
+		nil,
+		nil,
+		nil,
+		api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	// If the agent is unreachable, the request will hang. Assume that if we
+	// don't get a response after 30s that the agent is unreachable.
+	dialCtx, dialCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer dialCancel()
+	agentConn, release, err := api.agentProvider.AgentConn(dialCtx, waws.WorkspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(dialCtx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	m, err := agentConn.RestartService(ctx, name)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			httpapi.Write(ctx, rw, http.StatusRequestTimeout, codersdk.Response{
+				Message: "Failed to restart service from agent.",
+				Detail:  "Request timed out.",
+			})
+			return
+		}
+		// If the agent returns a codersdk.Error, we can return that directly.
+		if cerr, ok := codersdk.AsError(err); ok {
+			httpapi.Write(ctx, rw, cerr.StatusCode(), cerr.Response)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error restarting service.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, m)
+}
+
+// workspaceAgentServicesData returns the services of the given agents and the
+// latest status transition of each.
+func (api *API) workspaceAgentServicesData(ctx context.Context, agentIDs []uuid.UUID) ([]database.WorkspaceAgentService, []database.WorkspaceAgentServiceTransition, error) {
+	// nolint:gocritic // Getting workspace agent services by agent IDs is a system function.
+	services, err := api.Database.GetWorkspaceAgentServicesByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	// nolint:gocritic // Getting workspace agent service transitions by agent IDs is a system function.
+	transitions, err := api.Database.GetLatestWorkspaceAgentServiceTransitionsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return services, transitions, nil
+}
+
+// convertWorkspaceAgentServices converts services along with their latest
+// status transition. Services the agent did not report yet are pending.
+func convertWorkspaceAgentServices(services []database.WorkspaceAgentService, latestTransitions []database.WorkspaceAgentServiceTransition) []codersdk.WorkspaceAgentService {
+	type serviceKey struct {
+		agentID uuid.UUID
+		name    string
+	}
+	transitionsByService := make(map[serviceKey]database.WorkspaceAgentServiceTransition, len(latestTransitions))
+	for _, transition := range latestTransitions {
+		transitionsByService[serviceKey{transition.WorkspaceAgentID, transition.ServiceName}] = transition
+	}
+
+	apiServices := make([]codersdk.WorkspaceAgentService, 0, len(services))
+	for _, service := range services {
+		apiService := codersdk.WorkspaceAgentService{
+			Name:          service.Name,
+			DisplayName:   service.DisplayName,
+			Command:       service.Command,
+			RestartPolicy: codersdk.WorkspaceAgentServiceRestartPolicy(service.RestartPolicy),
+			Status:        codersdk.WorkspaceAgentServiceStatusPending,
+		}
+		if transition, ok := transitionsByService[serviceKey{service.WorkspaceAgentID, service.Name}]; ok {
+			apiService.Status = codersdk.WorkspaceAgentServiceStatus(transition.Status)
+			apiService.Message = transition.Message
+			if transition.ExitCode.Valid {
+				apiService.ExitCode = ptr.Ref(transition.ExitCode.Int32)
+			}
+			apiService.RestartCount = transition.RestartCount
+			apiService.UpdatedAt = ptr.Ref(transition.CreatedAt)
+		}
+		apiServices = append(apiServices, apiService)
+	}
+	return apiServices
+}
+
+// agentServicesHealthReason returns why the services of an agent make it
+// unhealthy, or an empty string if they don't.
+func agentServicesHealthReason(services []codersdk.WorkspaceAgentService) string {
+	var failed []string
+	for _, service := range services {
+		if service.Status == codersdk.WorkspaceAgentServiceStatusFailed {
+			failed = append(failed, fmt.Sprintf("%q", service.Name))
+		}
+	}
+	switch len(failed) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("service %s failed", failed[0])
+	default:
+		return fmt.Sprintf("services %s failed", strings.Join(failed, ", "))
+	}
+}
+
+// agentServiceTimings returns the time each service of the given agents spent
+// in each status. Spans of services that are still up end at now.
+func agentServiceTimings(agents []database.WorkspaceAgent, transitions []database.WorkspaceAgentServiceTransition, now time.Time) []codersdk.AgentServiceTiming {
+	agentNames := make(map[uuid.UUID]string, len(agents))
+	for _, agent := range agents {
+		agentNames[agent.ID] = agent.Name
+	}
+
+	// Transitions are ordered oldest first, so each one ends the span of
+	// the previous transition of the same service.
+	type serviceKey struct {
+		agentID uuid.UUID
+		name    string
+	}
+	open := make(map[serviceKey]int)
+	timings := make([]codersdk.AgentServiceTiming, 0, len(transitions))
+	for _, transition := range transitions {
+		agentName, ok := agentNames[transition.WorkspaceAgentID]
+		if !ok {
+			continue
+		}
+		key := serviceKey{transition.WorkspaceAgentID, transition.ServiceName}
+		if i, ok := open[key]; ok {
+			timings[i].EndedAt = transition.CreatedAt
+			delete(open, key)
+		}
+		timings = append(timings, codersdk.AgentServiceTiming{
+			StartedAt:          transition.CreatedAt,
+			EndedAt:            transition.CreatedAt,
+			Status:             codersdk.WorkspaceAgentServiceStatus(transition.Status),
+			ServiceName:        transition.ServiceName,
+			RestartCount:       transition.RestartCount,
+			WorkspaceAgentID:   transition.WorkspaceAgentID.String(),
+			WorkspaceAgentName: agentName,
+		})
+		switch transition.Status {
+		case database.WorkspaceAgentServiceStatusStopped, database.WorkspaceAgentServiceStatusFailed:
+		default:
+			open[key] = len(timings) - 1
+		}
+	}
+	for _, i := range open {
+		timings[i].EndedAt = now
+	}
+	return timings
+}
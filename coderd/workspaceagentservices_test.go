@@ -0,0 +1,122 @@
+package coderd_test
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentServices(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Services = []*proto.Service{{
+			Name:          "web",
+			DisplayName:   "Web",
+			Command:       "npm run dev",
+			RestartPolicy: "on_failure",
+		}}
+		return agents
+	}).Do()
+	agentClient := agentsdk.New(client.URL, agentsdk.WithFixedToken(r.AgentToken))
+	agentID := r.Agents[0].ID
+
+	// Services are pending until the agent reports them.
+	services, err := client.WorkspaceAgentServices(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, "Web", services[0].DisplayName)
+	require.Equal(t, codersdk.WorkspaceAgentServiceRestartPolicyOnFailure, services[0].RestartPolicy)
+	require.Equal(t, codersdk.WorkspaceAgentServiceStatusPending, services[0].Status)
+
+	// Restarting requires a connected agent.
+	var sdkErr *codersdk.Error
+	_, err = client.WorkspaceAgentRestartService(ctx, agentID, "web")
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+	// Mark the agent as connected and ready so it would otherwise be healthy.
+	now := dbtime.Now()
+	err = db.UpdateWorkspaceAgentConnectionByID(ctx, database.UpdateWorkspaceAgentConnectionByIDParams{
+		ID:               agentID,
+		FirstConnectedAt: sql.NullTime{Time: now, Valid: true},
+		LastConnectedAt:  sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:        now,
+	})
+	require.NoError(t, err)
+	err = db.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+		ID:             agentID,
+		LifecycleState: database.WorkspaceAgentLifecycleStateReady,
+		StartedAt:      sql.NullTime{Time: now, Valid: true},
+		ReadyAt:        sql.NullTime{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+
+	err = agentClient.PostServiceStatus(ctx, "web", agentsdk.PostServiceStatusRequest{
+		Status: codersdk.WorkspaceAgentServiceStatusPending,
+	})
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+	err = agentClient.PostServiceStatus(ctx, "db", agentsdk.PostServiceStatusRequest{
+		Status: codersdk.WorkspaceAgentServiceStatusRunning,
+	})
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+
+	err = agentClient.PostServiceStatus(ctx, "web", agentsdk.PostServiceStatusRequest{
+		Status: codersdk.WorkspaceAgentServiceStatusRunning,
+	})
+	require.NoError(t, err)
+	err = agentClient.PostServiceStatus(ctx, "web", agentsdk.PostServiceStatusRequest{
+		Status:       codersdk.WorkspaceAgentServiceStatusFailed,
+		Message:      "exited with code 1",
+		ExitCode:     ptr.Ref[int32](1),
+		RestartCount: 3,
+	})
+	require.NoError(t, err)
+
+	services, err = client.WorkspaceAgentServices(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, codersdk.WorkspaceAgentServiceStatusFailed, services[0].Status)
+	require.Equal(t, "exited with code 1", services[0].Message)
+	require.NotNil(t, services[0].ExitCode)
+	require.EqualValues(t, 1, *services[0].ExitCode)
+	require.EqualValues(t, 3, services[0].RestartCount)
+
+	// A failed service makes the agent unhealthy.
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agent := workspace.LatestBuild.Resources[0].Agents[0]
+	require.Len(t, agent.Services, 1)
+	require.False(t, agent.Health.Healthy)
+	require.Contains(t, agent.Health.Reason, `service "web" failed`)
+
+	// Every status shows up in the timeline.
+	timings, err := client.WorkspaceBuildTimings(ctx, r.Build.ID)
+	require.NoError(t, err)
+	require.Len(t, timings.AgentServiceTimings, 2)
+	require.Equal(t, codersdk.WorkspaceAgentServiceStatusRunning, timings.AgentServiceTimings[0].Status)
+	require.Equal(t, timings.AgentServiceTimings[1].StartedAt, timings.AgentServiceTimings[0].EndedAt)
+	require.Equal(t, codersdk.WorkspaceAgentServiceStatusFailed, timings.AgentServiceTimings[1].Status)
+}
@@ -3,9 +3,12 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/wspubsub"
@@ -93,6 +97,29 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		slog.F("agent_name", workspaceAgent.Name),
 	)
 
+	if monitorConnection {
+		// Slow down reconnects of crash looping agents instead of letting
+		// them flap between connected and disconnected.
+		crashLoop, err := api.Database.GetWorkspaceAgentCrashLoopByAgentID(ctx, workspaceAgent.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace agent crash loop.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		retryAfter := time.Until(crashLoop.LastDisconnectedAt.Add(agentCrashLoopBackoff(crashLoop)))
+		if crashLoop.CrashLoopingSince.Valid && retryAfter > 0 {
+			logger.Debug(ctx, "backing off crash looping agent", slog.F("retry_after", retryAfter))
+			rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			httpapi.Write(ctx, rw, http.StatusTooManyRequests, codersdk.Response{
+				Message: "Workspace agent is crash looping, reconnect later.",
+				Detail:  fmt.Sprintf("The agent disconnected %d times in a row shortly after connecting.", crashLoop.ShortConnections),
+			})
+			return
+		}
+	}
+
 	conn, err := websocket.Accept(rw, r, nil)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -158,6 +185,7 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		AgentID:           workspaceAgent.ID,
 		OwnerID:           workspace.OwnerID,
 		WorkspaceID:       workspace.ID,
+		WorkspaceBuildID:  build.ID,
 		OrganizationID:    workspace.OrganizationID,
 		TemplateVersionID: build.TemplateVersionID,
 
@@ -188,10 +216,12 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		ExternalAuthConfigs:       api.ExternalAuthConfigs,
 		Experiments:               api.Experiments,
 		LifecycleMetrics:          api.lifecycleMetrics,
+		BuildTraces:               api.BuildTraces,
 
 		// Optional:
-		UpdateAgentMetricsFn: api.UpdateAgentMetrics,
-		ContextDirtyMarker:   contextDirtyMarker,
+		UpdateAgentMetricsFn:         api.UpdateAgentMetrics,
+		ContextDirtyMarker:           contextDirtyMarker,
+		RequestWorkspaceTransitionFn: api.requestWorkspaceTransition,
 	}, workspace, workspaceAgent)
 
 	streamID := tailnet.StreamID{
@@ -273,6 +303,9 @@ func (api *API) startAgentYamuxMonitor(ctx context.Context,
 		updater:           api,
 		disconnectTimeout: api.AgentInactiveDisconnectTimeout,
 		metrics:           api.workspaceAgentRPCMetrics,
+
+		trackCrashLoops:       true,
+		notificationsEnqueuer: api.NotificationsEnqueuer,
 		logger: api.Logger.With(
 			slog.F("workspace_id", workspaceBuild.WorkspaceID),
 			slog.F("agent_id", workspaceAgent.ID),
@@ -308,6 +341,11 @@ type agentConnectionMonitor struct {
 	pingPeriod     time.Duration
 	metrics        *WorkspaceAgentRPCMetrics
 
+	// trackCrashLoops records connections that end shortly after they
+	// started, to detect crash looping agents.
+	trackCrashLoops       bool
+	notificationsEnqueuer notifications.Enqueuer
+
 	// state manipulated by both sendPings() and monitor() goroutines: needs to be threadsafe
 	lastPing atomic.Pointer[time.Time]
 
@@ -316,6 +354,8 @@ type agentConnectionMonitor struct {
 	lastConnectedAt   sql.NullTime
 	disconnectedAt    sql.NullTime
 	disconnectTimeout time.Duration
+	connectedAt       time.Time
+	stable            bool
 }
 
 // sendPings sends websocket pings.
@@ -385,6 +425,7 @@ func (m *agentConnectionMonitor) init() {
 		Time:  now,
 		Valid: true,
 	}
+	m.connectedAt = now
 	m.disconnectedAt = m.workspaceAgent.DisconnectedAt
 	m.lastPing.Store(ptr.Ref(time.Now())) // Since the agent initiated the request, assume it's alive.
 }
@@ -438,6 +479,12 @@ func (m *agentConnectionMonitor) monitor(ctx context.Context) {
 				)
 			}
 		}
+		if m.trackCrashLoops && !m.stable {
+			err = m.recordShortConnection(finalCtx, reason)
+			if err != nil && !xerrors.Is(err, context.Canceled) && !database.IsQueryCanceledError(err) {
+				m.logger.Error(finalCtx, "failed to record short agent connection", slog.Error(err))
+			}
+		}
 		m.updater.publishWorkspaceUpdate(finalCtx, m.workspace.OwnerID, wspubsub.WorkspaceEvent{
 			Kind:        wspubsub.WorkspaceEventKindAgentConnectionUpdate,
 			WorkspaceID: m.workspaceBuild.WorkspaceID,
@@ -490,6 +537,25 @@ func (m *agentConnectionMonitor) monitor(ctx context.Context) {
 		// connected. Since all we've done is updated lastConnectedAt, the workspace is still connected and hasn't
 		// changed status. We don't expect to get updates just for the times changing.
 
+		if m.trackCrashLoops && !m.stable && m.lastConnectedAt.Time.Sub(m.connectedAt) >= agentCrashLoopStableAfter {
+			// The agent stayed connected, so it is no longer crash looping.
+			//nolint:gocritic // We only update the agent we are minding.
+			err = m.db.DeleteWorkspaceAgentCrashLoop(dbauthz.AsSystemRestricted(ctx), m.workspaceAgent.ID)
+			if err != nil {
+				reason = err.Error()
+				if !database.IsQueryCanceledError(err) {
+					m.logger.Error(ctx, "failed to clear agent crash loop", slog.Error(err))
+				}
+				return
+			}
+			m.stable = true
+			m.updater.publishWorkspaceUpdate(ctx, m.workspace.OwnerID, wspubsub.WorkspaceEvent{
+				Kind:        wspubsub.WorkspaceEventKindAgentConnectionUpdate,
+				WorkspaceID: m.workspaceBuild.WorkspaceID,
+				AgentID:     &m.workspaceAgent.ID,
+			})
+		}
+
 		ctx, err := dbauthz.WithWorkspaceRBAC(ctx, m.workspace.RBACObject())
 		if err != nil {
 			// Don't error level log here, will exit the function. We want to fall back to GetWorkspaceByAgentID.
@@ -510,13 +576,15 @@ func (m *agentConnectionMonitor) close() {
 	m.wg.Wait()
 }
 
+var errBuildIsOutdated = xerrors.New("build is outdated")
+
 func checkBuildIsLatest(ctx context.Context, db database.Store, build database.WorkspaceBuild) error {
 	latestBuild, err := db.GetLatestWorkspaceBuildByWorkspaceID(ctx, build.WorkspaceID)
 	if err != nil {
 		return err
 	}
 	if build.ID != latestBuild.ID {
-		return xerrors.New("build is outdated")
+		return errBuildIsOutdated
 	}
 	return nil
 }
@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"fmt"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"path"
 	"slices"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -111,6 +113,15 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		Request: appReq,
 	}
 
+	// Capture when the session was last used before the API key middleware
+	// refreshes it, so that idle timeouts of apps can be enforced.
+	var sessionLastUsed time.Time
+	if keyID, _, err := httpmw.SplitAPIToken(issueReq.SessionToken); err == nil {
+		if key, err := p.Database.GetAPIKeyByID(dangerousSystemCtx, keyID); err == nil {
+			sessionLastUsed = key.LastUsed
+		}
+	}
+
 	// We use the regular API apiKey extraction middleware fn here to avoid any
 	// differences in behavior between the two.
 	apiKey, authz, ok := httpmw.ExtractAPIKey(rw, r, httpmw.ExtractAPIKeyConfig{
@@ -148,6 +159,42 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 
 	aReq.dbReq = dbReq // Update audit request.
 
+	// Enforce the session limits of the app. Expired sessions are deleted so
+	// that the user has to reauthenticate, which is handled by the same flow
+	// as unauthenticated requests below.
+	if apiKey != nil {
+		if sessionLastUsed.IsZero() {
+			sessionLastUsed = apiKey.LastUsed
+		}
+		now := dbtime.Now()
+		if appSessionExpired(dbReq.App, *apiKey, sessionLastUsed, now) {
+			err = p.Database.DeleteAPIKeyByID(dangerousSystemCtx, apiKey.ID)
+			if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+				WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "delete expired app session")
+				return nil, "", false
+			}
+			apiKey, authz = nil, nil
+			aReq.apiKey = nil
+		} else if dbReq.App.SessionIdleTimeoutSeconds > 0 && now.Sub(apiKey.LastUsed) > time.Minute {
+			// The API key middleware only refreshes LastUsed hourly, which
+			// is too coarse for idle timeouts.
+			err = p.Database.UpdateAPIKeyByID(dangerousSystemCtx, database.UpdateAPIKeyByIDParams{
+				ID:        apiKey.ID,
+				LastUsed:  now,
+				ExpiresAt: apiKey.ExpiresAt,
+				IPAddress: apiKey.IPAddress,
+			})
+			if err != nil {
+				WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "update app session last used")
+				return nil, "", false
+			}
+		}
+		if apiKey != nil && dbReq.App.MaxSessionDurationSeconds > 0 {
+			expiresAt := apiKey.CreatedAt.Add(time.Duration(dbReq.App.MaxSessionDurationSeconds) * time.Second)
+			token.SessionExpiresAt = &expiresAt
+		}
+	}
+
 	token.UserID = dbReq.UserID
 	token.WorkspaceID = dbReq.Workspace.ID
 	token.AgentID = dbReq.Agent.ID
@@ -162,6 +209,20 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "verify authz")
 		return nil, "", false
 	}
+	if !authed && apiKey == nil && issueReq.ShareLinkSession != "" {
+		// Visitors of a share link don't have a Coder account, they are
+		// authorized by the session they received after authenticating with
+		// the OIDC provider.
+		link, ok, err := p.authorizeShareLink(dangerousSystemCtx, issueReq.ShareLinkSession, dbReq)
+		if err != nil {
+			WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "verify share link session")
+			return nil, "", false
+		}
+		if ok {
+			authed = true
+			token.SessionExpiresAt = &link.ExpiresAt
+		}
+	}
 	if !authed {
 		if apiKey != nil {
 			// The request has a valid API key but insufficient permissions.
@@ -248,8 +309,12 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		return nil, "", false
 	}
 
+	expiry := time.Now().Add(DefaultTokenExpiry)
+	if token.SessionExpiresAt != nil && token.SessionExpiresAt.Before(expiry) {
+		expiry = *token.SessionExpiresAt
+	}
 	token.RegisteredClaims = jwtutils.RegisteredClaims{
-		Expiry: jwt.NewNumericDate(time.Now().Add(DefaultTokenExpiry)),
+		Expiry: jwt.NewNumericDate(expiry),
 	}
 	// Sign the token.
 	tokenStr, err := jwtutils.Sign(ctx, p.Keycache, token)
@@ -261,6 +326,53 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 	return &token, tokenStr, true
 }
 
+// appSessionExpired returns true if the session used to access the app has
+// exceeded the maximum session duration or idle timeout of the app.
+func appSessionExpired(app database.WorkspaceApp, key database.APIKey, lastUsed time.Time, now time.Time) bool {
+	if app.MaxSessionDurationSeconds > 0 &&
+		now.Sub(key.CreatedAt) > time.Duration(app.MaxSessionDurationSeconds)*time.Second {
+		return true
+	}
+	if app.SessionIdleTimeoutSeconds > 0 &&
+		now.Sub(lastUsed) > time.Duration(app.SessionIdleTimeoutSeconds)*time.Second {
+		return true
+	}
+	return false
+}
+
+// authorizeShareLink returns true if the share link session is valid for the
+// app of the request. Sessions of revoked or expired share links are rejected,
+// so visitors lose access once their current signed app token expires.
+func (p *DBTokenProvider) authorizeShareLink(ctx context.Context, session string, dbReq *databaseRequest) (database.WorkspaceAppShareLink, bool, error) {
+	if dbReq.AccessMethod != AccessMethodSubdomain || dbReq.App.ID == uuid.Nil {
+		return database.WorkspaceAppShareLink{}, false, nil
+	}
+
+	var claims ShareLinkSession
+	err := jwtutils.Verify(ctx, p.Keycache, session, &claims, jwtutils.WithVerifyExpected(jwt.Expected{
+		Time: dbtime.Now(),
+	}))
+	if err != nil {
+		p.Logger.Debug(ctx, "invalid share link session", slog.Error(err))
+		return database.WorkspaceAppShareLink{}, false, nil
+	}
+
+	link, err := p.Database.GetWorkspaceAppShareLinkByID(ctx, claims.ShareLinkID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return database.WorkspaceAppShareLink{}, false, nil
+	}
+	if err != nil {
+		return database.WorkspaceAppShareLink{}, false, xerrors.Errorf("get share link: %w", err)
+	}
+	if link.RevokedAt.Valid || !dbtime.Now().Before(link.ExpiresAt) {
+		return database.WorkspaceAppShareLink{}, false, nil
+	}
+	if link.WorkspaceID != dbReq.Workspace.ID || link.AgentName != dbReq.Agent.Name || link.AppSlug != dbReq.App.Slug {
+		return database.WorkspaceAppShareLink{}, false, nil
+	}
+	return link, true, nil
+}
+
 // authorizeRequest returns true if the request is authorized. The returned []string
 // are warnings that aid in debugging. These messages do not prevent authorization,
 // but may indicate that the request is not configured correctly.
@@ -350,7 +462,16 @@ func (p *DBTokenProvider) authorizeRequest(ctx context.Context, roles *rbac.Subj
 	// scope allows it).
 	err := p.Authorizer.Authorize(ctx, *roles, rbacAction, rbacResource)
 	if err == nil {
-		return true, []string{}, nil
+		// Users the workspace is shared with may be restricted to some
+		// ports. If the port isn't allowed, the sharing level of the app may
+		// still grant access below.
+		allowed, err := p.portForwardAllowed(ctx, roles, dbReq)
+		if err != nil {
+			return false, warnings, xerrors.Errorf("check port-forward ACL: %w", err)
+		}
+		if allowed {
+			return true, []string{}, nil
+		}
 	}
 
 	switch sharingLevel {
@@ -407,6 +528,54 @@ type connLogRequest struct {
 //
 // A session is unique to the agent, app, user and users IP. If any of these
 // values change, a new session and connect log is created.
+// portForwardAllowed returns whether the ports of the workspace the user may
+// connect to allow the request. Users that are not restricted to some ports
+// are always allowed.
+func (p *DBTokenProvider) portForwardAllowed(ctx context.Context, roles *rbac.Subject, dbReq *databaseRequest) (bool, error) {
+	if dbReq.Workspace.OwnerID.String() == roles.ID {
+		return true, nil
+	}
+	userID, err := uuid.Parse(roles.ID)
+	if err != nil {
+		return false, xerrors.Errorf("parse user ID: %w", err)
+	}
+	// nolint:gocritic // The restrictions of the user may not be readable by
+	// the user.
+	acl, err := p.Database.GetWorkspacePortForwardACL(dbauthz.AsSystemRestricted(ctx), database.GetWorkspacePortForwardACLParams{
+		WorkspaceID: dbReq.Workspace.ID,
+		UserID:      userID,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get workspace port-forward ACL: %w", err)
+	}
+
+	// Terminals and apps that aren't served by the workspace itself can't be
+	// restricted to a port.
+	if dbReq.AccessMethod == AccessMethodTerminal || dbReq.AppURL == nil {
+		return false, nil
+	}
+	host := dbReq.AppURL.Hostname()
+	if ip, err := netip.ParseAddr(host); host != "localhost" && (err != nil || !ip.IsLoopback()) {
+		return false, nil
+	}
+	portStr := dbReq.AppURL.Port()
+	if portStr == "" {
+		portStr = "80"
+		if dbReq.AppURL.Scheme == "https" {
+			portStr = "443"
+		}
+	}
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil {
+		return false, nil
+	}
+	// #nosec G115 - Safe conversion as the port is parsed as a 32-bit integer.
+	return slices.Contains(acl.Ports, int32(port)), nil
+}
+
 func (p *DBTokenProvider) connLogInitRequest(w http.ResponseWriter, r *http.Request) (aReq *connLogRequest, commit func()) {
 	// Get the status writer from the request context so we can figure
 	// out the HTTP status and autocommit the audit log.
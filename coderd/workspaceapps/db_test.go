@@ -24,6 +24,7 @@ import (
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/connectionlog"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/jwtutils"
@@ -49,6 +50,8 @@ func Test_ResolveRequest(t *testing.T) {
 		appNameUnhealthy    = "app-unhealthy"
 		appNameInitializing = "app-initializing"
 		appNameEndsInS      = "app-ends-in-s"
+		// Sessions used to access this app are limited.
+		appNameSessionLimited = "app-session-limited"
 
 		// This agent will never connect, so it will never become "connected".
 		// Users cannot access unhealthy agents.
@@ -188,6 +191,14 @@ func Test_ResolveRequest(t *testing.T) {
 										SharingLevel: proto.AppSharingLevel_OWNER,
 										Url:          appURL,
 									},
+									{
+										Slug:                      appNameSessionLimited,
+										DisplayName:               appNameSessionLimited,
+										SharingLevel:              proto.AppSharingLevel_OWNER,
+										Url:                       appURL,
+										MaxSessionDurationSeconds: 3600,
+										SessionIdleTimeoutSeconds: 600,
+									},
 								},
 							},
 							{
@@ -1018,6 +1029,67 @@ func Test_ResolveRequest(t *testing.T) {
 		require.Equal(t, "/some-path", redirectURI.Path)
 	})
 
+	t.Run("SessionLimits", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		req := (workspaceapps.Request{
+			AccessMethod:      workspaceapps.AccessMethodSubdomain,
+			BasePath:          "/",
+			UsernameOrID:      me.Username,
+			WorkspaceNameOrID: workspace.Name,
+			AgentNameOrID:     agentName,
+			AppSlugOrPort:     appNameSessionLimited,
+		}).Normalize()
+
+		resolve := func(sessionToken string) (*workspaceapps.SignedToken, *http.Response) {
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set(codersdk.SessionTokenHeader, sessionToken)
+			r.RemoteAddr = testutil.RandomIPv6(t)
+			token, _ := workspaceappsResolveRequest(t, connectionlog.NewFake(), rw, r, workspaceapps.ResolveRequestOptions{
+				Logger:              api.Logger,
+				SignedTokenProvider: api.WorkspaceAppsProvider,
+				DashboardURL:        api.AccessURL,
+				PathAppBaseURL:      api.AccessURL,
+				AppHostname:         api.AppHostname,
+				AppRequest:          req,
+			})
+			w := rw.Result()
+			_ = w.Body.Close()
+			return token, w
+		}
+
+		// A fresh session is accepted, and the token carries the session
+		// expiry so that connections can be terminated.
+		key, err := client.CreateAPIKey(ctx, codersdk.Me)
+		require.NoError(t, err)
+		keyID, _, err := httpmw.SplitAPIToken(key.Key)
+		require.NoError(t, err)
+		token, _ := resolve(key.Key)
+		require.NotNil(t, token)
+		require.NotNil(t, token.SessionExpiresAt)
+		require.WithinDuration(t, dbtime.Now().Add(time.Hour), *token.SessionExpiresAt, time.Minute)
+
+		// An idle session is deleted, and the user has to reauthenticate.
+		//nolint:gocritic // Test needs to age the API key.
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		apiKey, err := api.Database.GetAPIKeyByID(sysCtx, keyID)
+		require.NoError(t, err)
+		err = api.Database.UpdateAPIKeyByID(sysCtx, database.UpdateAPIKeyByIDParams{
+			ID:        apiKey.ID,
+			LastUsed:  dbtime.Now().Add(-time.Hour),
+			ExpiresAt: apiKey.ExpiresAt,
+			IPAddress: apiKey.IPAddress,
+		})
+		require.NoError(t, err)
+		token, w := resolve(key.Key)
+		require.Nil(t, token)
+		require.Equal(t, http.StatusSeeOther, w.StatusCode)
+		_, err = api.Database.GetAPIKeyByID(sysCtx, keyID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
 	t.Run("UnhealthyAgent", func(t *testing.T) {
 		t.Parallel()
 
@@ -233,6 +233,34 @@ func (s *Server) handleAPIKeySmuggling(rw http.ResponseWriter, r *http.Request,
 		return false
 	}
 
+	if payload.ShareLinkSession != "" {
+		// Share link sessions are only valid for the app of the share link, so
+		// the cookie is set on the current app subdomain only.
+		if accessMethod != AccessMethodSubdomain {
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:      http.StatusBadRequest,
+				Title:       "Bad Request",
+				Description: "Share links are only supported for subdomain apps.",
+				Actions: []site.Action{
+					{
+						URL:  s.DashboardURL.String(),
+						Text: "Back to site",
+					},
+				},
+			})
+			return false
+		}
+		http.SetCookie(rw, s.CookiesConfig.Apply(&http.Cookie{
+			Name:     codersdk.AppShareLinkSessionCookie,
+			Value:    payload.ShareLinkSession,
+			Path:     "/",
+			MaxAge:   0,
+			HttpOnly: true,
+		}))
+		redirectWithoutAPIKeyParam(rw, r)
+		return false
+	}
+
 	// Set the cookie. For subdomain apps, we set the cookie on the whole
 	// wildcard so users don't need to re-auth for every subdomain app they
 	// access. For path apps (only on proxies, see above) we just set it on the
@@ -278,10 +306,15 @@ func (s *Server) handleAPIKeySmuggling(rw http.ResponseWriter, r *http.Request,
 		HttpOnly: true,
 	}))
 
-	// Strip the smuggled API key query parameter and redirect back to the same
-	// path. r.URL.Path is attacker-controlled and can smuggle a separate host
-	// (e.g. "//evil.com"); originLocalURL keeps the redirect on the current
-	// origin.
+	redirectWithoutAPIKeyParam(rw, r)
+	return false
+}
+
+// redirectWithoutAPIKeyParam strips the smuggled API key query parameter and
+// redirects back to the same path. r.URL.Path is attacker-controlled and can
+// smuggle a separate host (e.g. "//evil.com"); originLocalURL keeps the
+// redirect on the current origin.
+func redirectWithoutAPIKeyParam(rw http.ResponseWriter, r *http.Request) {
 	redirectURL := originLocalURL(r.URL.Path)
 
 	q := r.URL.Query()
@@ -289,7 +322,6 @@ func (s *Server) handleAPIKeySmuggling(rw http.ResponseWriter, r *http.Request,
 	redirectURL.RawQuery = q.Encode()
 
 	http.Redirect(rw, r, redirectURL.String(), http.StatusSeeOther)
-	return false
 }
 
 // workspaceAppsProxyPath proxies requests to a workspace application
@@ -705,6 +737,14 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 	// end span so we don't get long lived trace data
 	tracing.EndHTTPSpan(r, http.StatusOK, trace.SpanFromContext(ctx))
 
+	// Terminate long-lived connections, such as WebSockets, once the session
+	// used to access the app expires.
+	if appToken.SessionExpiresAt != nil {
+		sessionCtx, cancel := context.WithDeadline(ctx, *appToken.SessionExpiresAt)
+		defer cancel()
+		r = r.WithContext(sessionCtx)
+	}
+
 	report := newStatsReportFromSignedToken(appToken)
 	defer func() {
 		// We must use defer here because ServeHTTP may panic.
@@ -42,6 +42,9 @@ type IssueTokenRequest struct {
 	AppQuery string `json:"app_query"`
 	// SessionToken is the session token provided by the user.
 	SessionToken string `json:"session_token"`
+	// ShareLinkSession is the signed session of a share link visitor, if
+	// provided by the user.
+	ShareLinkSession string `json:"share_link_session,omitempty"`
 }
 
 // AppBaseURL returns the base URL of this specific app request. An error is
@@ -27,6 +27,9 @@ type SignedToken struct {
 	AgentID      uuid.UUID             `json:"agent_id"`
 	AppURL       string                `json:"app_url"`
 	CORSBehavior codersdk.CORSBehavior `json:"cors_behavior"`
+	// SessionExpiresAt is set when the app limits the duration of the session
+	// used to access it. Connections to the app are terminated at this time.
+	SessionExpiresAt *time.Time `json:"session_expires_at,omitempty"`
 }
 
 // MatchesRequest returns true if the token matches the request. Any token that
@@ -53,6 +56,9 @@ func (t SignedToken) MatchesRequest(req Request) bool {
 type EncryptedAPIKeyPayload struct {
 	jwtutils.RegisteredClaims
 	APIKey string `json:"api_key"`
+	// ShareLinkSession is set instead of APIKey for visitors of a workspace
+	// app share link.
+	ShareLinkSession string `json:"share_link_session,omitempty"`
 }
 
 func (e *EncryptedAPIKeyPayload) Fill(now time.Time) {
@@ -73,6 +79,26 @@ func (e EncryptedAPIKeyPayload) Validate(ex jwt.Expected) error {
 	return e.RegisteredClaims.Validate(ex)
 }
 
+// ShareLinkSession is the signed session of a visitor that authenticated
+// through a workspace app share link. It is stored in a cookie on the app
+// subdomain and is only valid for the app of the share link.
+type ShareLinkSession struct {
+	jwtutils.RegisteredClaims
+	ShareLinkID uuid.UUID `json:"share_link_id"`
+	Email       string    `json:"email"`
+}
+
+func (s ShareLinkSession) Validate(ex jwt.Expected) error {
+	if s.Expiry == nil {
+		return xerrors.Errorf("expiry is required")
+	}
+
+	ex.Issuer = "coderd"
+	ex.AnyAudience = jwt.Audience{"share-link"}
+
+	return s.RegisteredClaims.Validate(ex)
+}
+
 // FromRequest returns the signed token from the request, if it exists and is
 // valid. The caller must check that the token matches the request.
 func FromRequest(r *http.Request, mgr cryptokeys.SigningKeycache) (*SignedToken, bool) {
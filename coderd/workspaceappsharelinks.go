@@ -0,0 +1,452 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/jwtutils"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/site"
+)
+
+const (
+	// workspaceAppShareLinkDefaultLifetime is the lifetime of share links
+	// when none is requested.
+	workspaceAppShareLinkDefaultLifetime = 24 * time.Hour
+	// workspaceAppShareLinkMaxLifetime is the maximum lifetime of share links.
+	workspaceAppShareLinkMaxLifetime = 7 * 24 * time.Hour
+	// workspaceAppShareLinkSecretParam is the query parameter of share link
+	// URLs containing the secret of the link.
+	workspaceAppShareLinkSecretParam = "secret"
+	// workspaceAppShareLinkPath is the path of share link URLs, followed by
+	// the ID of the link.
+	workspaceAppShareLinkPath = "/api/v2/applications/share-links/"
+)
+
+// workspaceAppShareLinkAudit is attached to the audit log of share link
+// logins, since visitors don't have a Coder account.
+type workspaceAppShareLinkAudit struct {
+	Email string `json:"email"`
+}
+
+// @Summary Create workspace app share link
+// @ID create-workspace-app-share-link
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Applications
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceAppShareLinkRequest true "Create share link request"
+// @Success 201 {object} codersdk.CreateWorkspaceAppShareLinkResponse
+// @Router /api/v2/workspaces/{workspace}/share-links [post]
+func (api *API) postWorkspaceAppShareLink(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceAppShareLink](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateWorkspaceAppShareLinkRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if api.OIDCConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Share links require OIDC authentication to be configured.",
+		})
+		return
+	}
+	if api.AppHostname == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Share links require a wildcard access URL to be configured.",
+		})
+		return
+	}
+
+	lifetime := req.Lifetime
+	if lifetime == 0 {
+		lifetime = workspaceAppShareLinkDefaultLifetime
+	}
+	if lifetime < 0 || lifetime > workspaceAppShareLinkMaxLifetime {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid share link lifetime.",
+			Detail:  fmt.Sprintf("The lifetime must be positive and at most %s.", workspaceAppShareLinkMaxLifetime),
+			Validations: []codersdk.ValidationError{{
+				Field:  "lifetime",
+				Detail: fmt.Sprintf("must be positive and at most %s", workspaceAppShareLinkMaxLifetime),
+			}},
+		})
+		return
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	var agent *database.WorkspaceAgent
+	for i := range agents {
+		if agents[i].Name == req.AgentName || (req.AgentName == "" && len(agents) == 1) {
+			agent = &agents[i]
+			break
+		}
+	}
+	if agent == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Agent not found.",
+			Detail:  "The agent name is required if the workspace has more than one agent.",
+		})
+		return
+	}
+
+	app, err := api.Database.GetWorkspaceAppByAgentIDAndSlug(ctx, database.GetWorkspaceAppByAgentIDAndSlugParams{
+		AgentID: agent.ID,
+		Slug:    req.AppSlug,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "App not found.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if !app.Subdomain || app.External {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only subdomain apps can be shared with a link.",
+		})
+		return
+	}
+
+	secret, hashed, err := apikey.GenerateSecret(32)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	now := dbtime.Now()
+	link, err := api.Database.InsertWorkspaceAppShareLink(ctx, database.InsertWorkspaceAppShareLinkParams{
+		ID:           uuid.New(),
+		WorkspaceID:  workspace.ID,
+		AgentName:    agent.Name,
+		AppSlug:      app.Slug,
+		HashedSecret: hashed,
+		CreatedBy:    apiKey.UserID,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(lifetime),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = link
+
+	u := api.AccessURL.JoinPath(workspaceAppShareLinkPath, link.ID.String())
+	u.RawQuery = url.Values{workspaceAppShareLinkSecretParam: {secret}}.Encode()
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateWorkspaceAppShareLinkResponse{
+		ShareLink: convertWorkspaceAppShareLink(link),
+		URL:       u.String(),
+	})
+}
+
+// @Summary Get workspace app share links
+// @ID get-workspace-app-share-links
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Applications
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceAppShareLink
+// @Router /api/v2/workspaces/{workspace}/share-links [get]
+func (api *API) workspaceAppShareLinks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	links, err := api.Database.GetWorkspaceAppShareLinksByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	converted := make([]codersdk.WorkspaceAppShareLink, 0, len(links))
+	for _, link := range links {
+		converted = append(converted, convertWorkspaceAppShareLink(link))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Revoke workspace app share link
+// @ID revoke-workspace-app-share-link
+// @Security CoderSessionToken
+// @Tags Applications
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param sharelink path string true "Share link ID" format(uuid)
+// @Success 204
+// @Router /api/v2/workspaces/{workspace}/share-links/{sharelink} [delete]
+func (api *API) deleteWorkspaceAppShareLink(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceAppShareLink](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionDelete,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	linkID, ok := httpmw.ParseUUIDParam(rw, r, "sharelink")
+	if !ok {
+		return
+	}
+
+	link, err := api.Database.GetWorkspaceAppShareLinkByID(ctx, linkID)
+	if httpapi.Is404Error(err) || (err == nil && link.WorkspaceID != workspace.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.Old = link
+
+	revoked, err := api.Database.RevokeWorkspaceAppShareLink(ctx, database.RevokeWorkspaceAppShareLinkParams{
+		RevokedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		ID:        link.ID,
+	})
+	switch {
+	case xerrors.Is(err, sql.ErrNoRows):
+		// The link was already revoked.
+		aReq.New = link
+	case err != nil:
+		httpapi.InternalServerError(rw, err)
+		return
+	default:
+		aReq.New = revoked
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// workspaceAppShareLink is visited by the recipients of a share link. After
+// the link is validated the visitor is asked to authenticate with the OIDC
+// provider of the deployment.
+//
+// @Summary Visit workspace app share link
+// @ID visit-workspace-app-share-link
+// @Tags Applications
+// @Param sharelink path string true "Share link ID" format(uuid)
+// @Param secret query string true "Share link secret"
+// @Success 307
+// @Router /api/v2/applications/share-links/{sharelink} [get]
+func (api *API) workspaceAppShareLink(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // Visitors of share links are not authenticated.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	_, ok := api.validWorkspaceAppShareLink(ctx, rw, r, chi.URLParam(r, "sharelink"), r.URL.Query().Get(workspaceAppShareLinkSecretParam))
+	if !ok {
+		return
+	}
+
+	u := api.AccessURL.JoinPath(workspaceAppShareLinkPath, "oidc/callback")
+	u.RawQuery = url.Values{"redirect": {r.URL.RequestURI()}}.Encode()
+	http.Redirect(rw, r, u.String(), http.StatusTemporaryRedirect)
+}
+
+// @Summary Workspace app share link OpenID Connect callback
+// @ID workspace-app-share-link-openid-connect-callback
+// @Tags Applications
+// @Success 307
+// @Router /api/v2/applications/share-links/oidc/callback [get]
+func (api *API) workspaceAppShareLinkOIDC(rw http.ResponseWriter, r *http.Request) {
+	var (
+		//nolint:gocritic // Visitors of share links are not authenticated.
+		ctx               = dbauthz.AsSystemRestricted(r.Context())
+		state             = httpmw.OAuth2(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceAppShareLink](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	defer commitAudit()
+
+	// The share link is carried through the OIDC flow in the redirect.
+	redirect, err := url.Parse(state.Redirect)
+	if err != nil || !strings.HasPrefix(redirect.Path, workspaceAppShareLinkPath) {
+		renderShareLinkError(rw, r, http.StatusBadRequest, "The share link is invalid.")
+		return
+	}
+	link, ok := api.validWorkspaceAppShareLink(ctx, rw, r, path.Base(redirect.Path), redirect.Query().Get(workspaceAppShareLinkSecretParam))
+	if !ok {
+		return
+	}
+	workspace, err := api.Database.GetWorkspaceByID(ctx, link.WorkspaceID)
+	if err != nil {
+		api.Logger.Error(ctx, "get workspace of share link", slog.F("share_link_id", link.ID), slog.Error(err))
+		renderShareLinkError(rw, r, http.StatusInternalServerError, "Failed to get the shared workspace.")
+		return
+	}
+	// Visitors don't have a Coder account, so logins are attributed to the
+	// creator of the link. The visitor is recorded in the additional fields.
+	aReq.UserID = link.CreatedBy
+	aReq.Old = link
+	aReq.New = link
+	aReq.UpdateOrganizationID(workspace.OrganizationID)
+
+	rawIDToken, ok := state.Token.Extra("id_token").(string)
+	if !ok {
+		renderShareLinkError(rw, r, http.StatusBadRequest, "id_token not found in response payload. Ensure your OIDC callback is configured correctly!")
+		return
+	}
+	idToken, err := api.OIDCConfig.Verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		renderShareLinkError(rw, r, http.StatusBadRequest, "Failed to verify OIDC token.")
+		return
+	}
+	claims := map[string]interface{}{}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		renderShareLinkError(rw, r, http.StatusInternalServerError, "Failed to extract OIDC claims.")
+		return
+	}
+	email, _ := claims[api.OIDCConfig.EmailField].(string)
+	if email == "" {
+		renderShareLinkError(rw, r, http.StatusBadRequest, "Your OIDC account doesn't have an email address.")
+		return
+	}
+	if verifiedRaw, ok := claims["email_verified"]; ok && !api.OIDCConfig.IgnoreEmailVerified {
+		if verified, _ := coerceEmailVerified(verifiedRaw); !verified {
+			renderShareLinkError(rw, r, http.StatusForbidden, fmt.Sprintf("Verify the %q email address on your OIDC provider to access the app.", email))
+			return
+		}
+	}
+	aReq.SetAdditionalFields(workspaceAppShareLinkAudit{Email: email})
+
+	session, err := jwtutils.Sign(ctx, api.AppSigningKeyCache, workspaceapps.ShareLinkSession{
+		RegisteredClaims: jwtutils.RegisteredClaims{
+			Issuer:   "coderd",
+			Audience: jwt.Audience{"share-link"},
+			Expiry:   jwt.NewNumericDate(link.ExpiresAt),
+		},
+		ShareLinkID: link.ID,
+		Email:       email,
+	})
+	if err != nil {
+		api.Logger.Error(ctx, "sign share link session", slog.F("share_link_id", link.ID), slog.Error(err))
+		renderShareLinkError(rw, r, http.StatusInternalServerError, "Failed to create a session for the app.")
+		return
+	}
+	payload := workspaceapps.EncryptedAPIKeyPayload{
+		ShareLinkSession: session,
+	}
+	payload.Fill(api.Clock.Now())
+	encrypted, err := jwtutils.Encrypt(ctx, api.AppEncryptionKeyCache, payload)
+	if err != nil {
+		api.Logger.Error(ctx, "encrypt share link session", slog.F("share_link_id", link.ID), slog.Error(err))
+		renderShareLinkError(rw, r, http.StatusInternalServerError, "Failed to create a session for the app.")
+		return
+	}
+
+	// Share links are only created for subdomain apps, the session is
+	// smuggled to the app subdomain like API keys are.
+	subdomain := db2sdk.AppSubdomain(database.WorkspaceApp{Slug: link.AppSlug, Subdomain: true}, link.AgentName, workspace.Name, workspace.OwnerUsername)
+	u := url.URL{
+		Scheme:   api.AccessURL.Scheme,
+		Host:     strings.Replace(appurl.SubdomainAppHost(api.AppHostname, api.AccessURL), "*", subdomain, 1),
+		Path:     "/",
+		RawQuery: url.Values{workspaceapps.SubdomainProxyAPIKeyParam: {encrypted}}.Encode(),
+	}
+	http.Redirect(rw, r, u.String(), http.StatusSeeOther)
+}
+
+// validWorkspaceAppShareLink returns the share link if the secret matches and
+// the link was not revoked and has not expired. An error page is rendered
+// otherwise.
+func (api *API) validWorkspaceAppShareLink(ctx context.Context, rw http.ResponseWriter, r *http.Request, rawID, secret string) (database.WorkspaceAppShareLink, bool) {
+	id, err := uuid.Parse(rawID)
+	if err != nil || secret == "" {
+		renderShareLinkError(rw, r, http.StatusNotFound, "The share link is invalid.")
+		return database.WorkspaceAppShareLink{}, false
+	}
+	link, err := api.Database.GetWorkspaceAppShareLinkByID(ctx, id)
+	if httpapi.Is404Error(err) || (err == nil && !apikey.ValidateHash(link.HashedSecret, secret)) {
+		renderShareLinkError(rw, r, http.StatusNotFound, "The share link is invalid.")
+		return database.WorkspaceAppShareLink{}, false
+	}
+	if err != nil {
+		api.Logger.Error(ctx, "get share link", slog.F("share_link_id", id), slog.Error(err))
+		renderShareLinkError(rw, r, http.StatusInternalServerError, "Failed to get the share link.")
+		return database.WorkspaceAppShareLink{}, false
+	}
+	if link.RevokedAt.Valid || !dbtime.Now().Before(link.ExpiresAt) {
+		renderShareLinkError(rw, r, http.StatusGone, "The share link was revoked or has expired. Ask the owner of the app for a new link.")
+		return database.WorkspaceAppShareLink{}, false
+	}
+	return link, true
+}
+
+// renderShareLinkError renders an error page for visitors of share links,
+// who can't be sent back to the dashboard.
+func renderShareLinkError(rw http.ResponseWriter, r *http.Request, status int, description string) {
+	site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+		Status:      status,
+		Title:       http.StatusText(status),
+		Description: description,
+		HideStatus:  true,
+	})
+}
+
+func convertWorkspaceAppShareLink(link database.WorkspaceAppShareLink) codersdk.WorkspaceAppShareLink {
+	converted := codersdk.WorkspaceAppShareLink{
+		ID:          link.ID,
+		WorkspaceID: link.WorkspaceID,
+		AgentName:   link.AgentName,
+		AppSlug:     link.AppSlug,
+		CreatedBy:   link.CreatedBy,
+		CreatedAt:   link.CreatedAt,
+		ExpiresAt:   link.ExpiresAt,
+	}
+	if link.RevokedAt.Valid {
+		converted.RevokedAt = &link.RevokedAt.Time
+	}
+	return converted
+}
@@ -0,0 +1,158 @@
+package coderd_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAppShareLinks(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, oidc bool) (*codersdk.Client, *audit.MockAuditor, database.WorkspaceTable) {
+		t.Helper()
+		auditor := audit.NewMock()
+		opts := &coderdtest.Options{
+			Auditor:     auditor,
+			AppHostname: "*.test.coder.com",
+		}
+		if oidc {
+			fake := oidctest.NewFakeIDP(t, oidctest.WithServing())
+			opts.OIDCConfig = fake.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+				cfg.AllowSignups = true
+			})
+		}
+		client, db := coderdtest.NewWithDatabase(t, opts)
+		owner := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+			agents[0].Apps = []*proto.App{{
+				Slug:      "preview",
+				Url:       "http://localhost:3000",
+				Subdomain: true,
+			}, {
+				Slug: "docs",
+				Url:  "http://localhost:8080",
+			}}
+			return agents
+		}).Do()
+		return client, auditor, r.Workspace
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client, auditor, workspace := setup(t, true)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := client.CreateWorkspaceAppShareLink(ctx, workspace.ID, codersdk.CreateWorkspaceAppShareLinkRequest{
+			AppSlug:  "preview",
+			Lifetime: time.Hour,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "preview", resp.ShareLink.AppSlug)
+		require.NotEmpty(t, resp.ShareLink.AgentName)
+		require.WithinDuration(t, time.Now().Add(time.Hour), resp.ShareLink.ExpiresAt, time.Minute)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionCreate,
+			ResourceType: database.ResourceTypeWorkspaceAppShareLink,
+			ResourceID:   resp.ShareLink.ID,
+		}))
+
+		links, err := client.WorkspaceAppShareLinks(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		require.Equal(t, resp.ShareLink.ID, links[0].ID)
+		require.Nil(t, links[0].RevokedAt)
+
+		// Visitors are asked to authenticate with the OIDC provider.
+		httpClient := &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		visit := func(link string) *http.Response {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+			require.NoError(t, err)
+			res, err := httpClient.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+			return res
+		}
+		res := visit(resp.URL)
+		require.Equal(t, http.StatusTemporaryRedirect, res.StatusCode)
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "/api/v2/applications/share-links/oidc/callback", location.Path)
+
+		// The secret must match.
+		invalid, err := url.Parse(resp.URL)
+		require.NoError(t, err)
+		invalid.RawQuery = url.Values{"secret": {"invalid"}}.Encode()
+		require.Equal(t, http.StatusNotFound, visit(invalid.String()).StatusCode)
+
+		err = client.RevokeWorkspaceAppShareLink(ctx, workspace.ID, resp.ShareLink.ID)
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionDelete,
+			ResourceType: database.ResourceTypeWorkspaceAppShareLink,
+			ResourceID:   resp.ShareLink.ID,
+		}))
+		links, err = client.WorkspaceAppShareLinks(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		require.NotNil(t, links[0].RevokedAt)
+
+		// Revoked links can't be visited anymore.
+		require.Equal(t, http.StatusGone, visit(resp.URL).StatusCode)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+		client, _, workspace := setup(t, true)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, tc := range []struct {
+			name string
+			req  codersdk.CreateWorkspaceAppShareLinkRequest
+		}{
+			{name: "NoApp", req: codersdk.CreateWorkspaceAppShareLinkRequest{}},
+			{name: "UnknownApp", req: codersdk.CreateWorkspaceAppShareLinkRequest{AppSlug: "unknown"}},
+			{name: "PathApp", req: codersdk.CreateWorkspaceAppShareLinkRequest{AppSlug: "docs"}},
+			{name: "UnknownAgent", req: codersdk.CreateWorkspaceAppShareLinkRequest{AgentName: "unknown", AppSlug: "preview"}},
+			{name: "LifetimeTooLong", req: codersdk.CreateWorkspaceAppShareLinkRequest{AppSlug: "preview", Lifetime: 30 * 24 * time.Hour}},
+		} {
+			_, err := client.CreateWorkspaceAppShareLink(ctx, workspace.ID, tc.req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, tc.name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), tc.name)
+		}
+	})
+
+	t.Run("NoOIDC", func(t *testing.T) {
+		t.Parallel()
+		client, _, workspace := setup(t, false)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWorkspaceAppShareLink(ctx, workspace.ID, codersdk.CreateWorkspaceAppShareLinkRequest{
+			AppSlug: "preview",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,139 @@
+package coderd
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// workspaceBuildAnnotationAudit is recorded as the additional fields of the
+// audit log entry for an annotated build, so the note is visible next to the
+// build in the audit log.
+type workspaceBuildAnnotationAudit struct {
+	audit.AdditionalFields
+	Annotation string `json:"annotation"`
+}
+
+// @Summary Create workspace build annotation
+// @ID create-workspace-build-annotation
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceBuildAnnotationRequest true "Annotation request"
+// @Success 201 {object} codersdk.WorkspaceBuildAnnotation
+// @Router /api/v2/workspacebuilds/{workspacebuild}/annotations [post]
+func (api *API) postWorkspaceBuildAnnotation(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx            = r.Context()
+		apiKey         = httpmw.APIKey(r)
+		workspaceBuild = httpmw.WorkspaceBuildParam(r)
+		workspace      = httpmw.WorkspaceParam(r)
+		annotationInfo = &workspaceBuildAnnotationAudit{
+			AdditionalFields: audit.AdditionalFields{
+				WorkspaceName:  workspace.Name,
+				BuildNumber:    strconv.Itoa(int(workspaceBuild.BuildNumber)),
+				BuildReason:    workspaceBuild.Reason,
+				WorkspaceOwner: workspace.OwnerUsername,
+				WorkspaceID:    workspace.ID,
+			},
+		}
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceBuild](rw, &audit.RequestParams{
+			Audit:            *api.Auditor.Load(),
+			Log:              api.Logger,
+			Request:          r,
+			Action:           database.AuditActionWrite,
+			OrganizationID:   workspace.OrganizationID,
+			AdditionalFields: annotationInfo,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspaceBuild
+
+	if !api.Authorize(r, policy.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateWorkspaceBuildAnnotationRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	annotationInfo.Annotation = req.Note
+
+	annotation, err := api.Database.InsertWorkspaceBuildAnnotation(ctx, database.InsertWorkspaceBuildAnnotationParams{
+		ID:               uuid.New(),
+		WorkspaceBuildID: workspaceBuild.ID,
+		UserID:           apiKey.UserID,
+		Note:             req.Note,
+		CreatedAt:        dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating workspace build annotation.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = workspaceBuild
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.WorkspaceBuildAnnotation{
+		ID:               annotation.ID,
+		WorkspaceBuildID: annotation.WorkspaceBuildID,
+		UserID:           annotation.UserID,
+		Username:         httpmw.UserAuthorization(ctx).FriendlyName,
+		Note:             annotation.Note,
+		CreatedAt:        annotation.CreatedAt,
+	})
+}
+
+// attachWorkspaceBuildAnnotations populates the annotations of the given
+// builds. Callers must have already authorized reading the builds.
+func (api *API) attachWorkspaceBuildAnnotations(ctx context.Context, builds []codersdk.WorkspaceBuild) error {
+	if len(builds) == 0 {
+		return nil
+	}
+	buildIDs := make([]uuid.UUID, 0, len(builds))
+	for _, build := range builds {
+		buildIDs = append(buildIDs, build.ID)
+	}
+
+	// nolint:gocritic // Annotations are readable by anyone that can read the build.
+	annotations, err := api.Database.GetWorkspaceBuildAnnotationsByBuildIDs(dbauthz.AsSystemRestricted(ctx), buildIDs)
+	if err != nil {
+		return xerrors.Errorf("get workspace build annotations: %w", err)
+	}
+
+	byBuildID := make(map[uuid.UUID][]codersdk.WorkspaceBuildAnnotation, len(builds))
+	for _, annotation := range annotations {
+		byBuildID[annotation.WorkspaceBuildID] = append(byBuildID[annotation.WorkspaceBuildID], codersdk.WorkspaceBuildAnnotation{
+			ID:               annotation.ID,
+			WorkspaceBuildID: annotation.WorkspaceBuildID,
+			UserID:           annotation.UserID,
+			Username:         annotation.Username,
+			Note:             annotation.Note,
+			CreatedAt:        annotation.CreatedAt,
+		})
+	}
+	for i := range builds {
+		builds[i].Annotations = byBuildID[builds[i].ID]
+	}
+	return nil
+}
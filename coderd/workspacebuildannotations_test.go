@@ -0,0 +1,81 @@
+package coderd_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceBuildAnnotations(t *testing.T) {
+	t.Parallel()
+
+	auditor := audit.NewMock()
+	ownerClient, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	otherClient, _ := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	req := codersdk.CreateWorkspaceBuildAnnotationRequest{Note: "rebuilt to pick up CVE fix"}
+
+	// A note is required.
+	_, err := client.CreateWorkspaceBuildAnnotation(ctx, r.Build.ID, codersdk.CreateWorkspaceBuildAnnotationRequest{})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Users that cannot see the workspace cannot annotate its builds.
+	_, err = otherClient.CreateWorkspaceBuildAnnotation(ctx, r.Build.ID, req)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	annotation, err := client.CreateWorkspaceBuildAnnotation(ctx, r.Build.ID, req)
+	require.NoError(t, err)
+	require.Equal(t, r.Build.ID, annotation.WorkspaceBuildID)
+	require.Equal(t, user.ID, annotation.UserID)
+	require.Equal(t, user.Username, annotation.Username)
+	require.Equal(t, req.Note, annotation.Note)
+
+	// Template admins can add context to builds too.
+	second, err := ownerClient.CreateWorkspaceBuildAnnotation(ctx, r.Build.ID, codersdk.CreateWorkspaceBuildAnnotationRequest{
+		Note: "confirmed fixed in incident retro",
+	})
+	require.NoError(t, err)
+
+	build, err := client.WorkspaceBuild(ctx, r.Build.ID)
+	require.NoError(t, err)
+	require.Len(t, build.Annotations, 2)
+	require.Equal(t, annotation.ID, build.Annotations[0].ID)
+	require.Equal(t, second.ID, build.Annotations[1].ID)
+
+	builds, err := client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{WorkspaceID: r.Workspace.ID})
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+	require.Len(t, builds[0].Annotations, 2)
+	require.Equal(t, req.Note, builds[0].Annotations[0].Note)
+
+	// The note is recorded with the build in the audit log.
+	require.Eventually(t, func() bool {
+		for _, log := range auditor.AuditLogs() {
+			if log.ResourceType == database.ResourceTypeWorkspaceBuild &&
+				log.Action == database.AuditActionWrite &&
+				strings.Contains(string(log.AdditionalFields), req.Note) {
+				return true
+			}
+		}
+		return false
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
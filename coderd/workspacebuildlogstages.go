@@ -0,0 +1,205 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace build log stages
+// @Description The stages of a build are derived from the provisioner timings, which are
+// @Description recorded once the build completed or failed. The logs of a stage are the
+// @Description ones written between the start of its first timing and the end of its
+// @Description last one.
+// @ID get-workspace-build-log-stages
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID"
+// @Success 200 {array} codersdk.WorkspaceBuildLogStage
+// @Router /api/v2/workspacebuilds/{workspacebuild}/logs/stages [get]
+func (api *API) workspaceBuildLogStages(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx            = r.Context()
+		workspaceBuild = httpmw.WorkspaceBuildParam(r)
+	)
+
+	spans, logs, ok := api.workspaceBuildStageSpans(rw, r, workspaceBuild.JobID, 0)
+	if !ok {
+		return
+	}
+
+	stages := make([]codersdk.WorkspaceBuildLogStage, 0, len(spans))
+	for _, span := range spans {
+		stage := codersdk.WorkspaceBuildLogStage{
+			Stage:     codersdk.TimingStage(span.stage),
+			StartedAt: span.startedAt,
+			EndedAt:   span.endedAt,
+		}
+		for _, log := range logs {
+			if !span.contains(log.CreatedAt) {
+				continue
+			}
+			if stage.FirstLogID == nil {
+				stage.FirstLogID = &log.ID
+			}
+			stage.LastLogID = &log.ID
+			stage.LogCount++
+		}
+		stages = append(stages, stage)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, stages)
+}
+
+// workspaceBuildStageLogs writes the logs of a build that were written during
+// the stage in the "stage" query parameter.
+func (api *API) workspaceBuildStageLogs(rw http.ResponseWriter, r *http.Request, job database.ProvisionerJob) {
+	var (
+		ctx      = r.Context()
+		stage    = database.ProvisionerJobTimingStage(r.URL.Query().Get("stage"))
+		afterRaw = r.URL.Query().Get("after")
+		format   = r.URL.Query().Get("format")
+	)
+
+	if !stage.Valid() {
+		stages := make([]string, 0, len(database.AllProvisionerJobTimingStageValues()))
+		for _, s := range database.AllProvisionerJobTimingStageValues() {
+			stages = append(stages, strconv.Quote(string(s)))
+		}
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid stage parameter.",
+			Detail:  fmt.Sprintf("Allowed values are %s.", strings.Join(stages, ", ")),
+		})
+		return
+	}
+	if r.URL.Query().Has("follow") {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Stage filtering is not supported with follow mode.",
+			Detail:  "Stages are only known once the build completed. Omit the follow parameter.",
+		})
+		return
+	}
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "text" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid format parameter.",
+			Detail:  "Allowed values are \"json\" and \"text\".",
+		})
+		return
+	}
+	var after int64
+	if afterRaw != "" {
+		var err error
+		after, err = strconv.ParseInt(afterRaw, 10, 64)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Query param \"after\" must be an integer.",
+				Validations: []codersdk.ValidationError{
+					{Field: "after", Detail: "Must be an integer"},
+				},
+			})
+			return
+		}
+	}
+
+	spans, logs, ok := api.workspaceBuildStageSpans(rw, r, job.ID, after)
+	if !ok {
+		return
+	}
+	// A stage that did not run, or of a build that is still running, has no
+	// logs.
+	filtered := []database.ProvisionerJobLog{}
+	for _, span := range spans {
+		if span.stage != stage {
+			continue
+		}
+		for _, log := range logs {
+			if span.contains(log.CreatedAt) {
+				filtered = append(filtered, log)
+			}
+		}
+	}
+	writeProvisionerJobLogs(ctx, rw, filtered, format)
+}
+
+// workspaceBuildStageSpans fetches the stage spans of a job along with its
+// logs after the given log ID.
+func (api *API) workspaceBuildStageSpans(rw http.ResponseWriter, r *http.Request, jobID uuid.UUID, after int64) ([]provisionerStageSpan, []database.ProvisionerJobLog, bool) {
+	ctx := r.Context()
+
+	timings, err := api.Database.GetProvisionerJobTimingsByJobID(ctx, jobID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job timings.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	logs, err := api.Database.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+		JobID:        jobID,
+		CreatedAfter: after,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner logs.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	return provisionerStageSpans(timings), logs, true
+}
+
+// provisionerStageSpan is the time span of a provisioning stage, from the
+// start of its first timing to the end of its last one.
+type provisionerStageSpan struct {
+	stage     database.ProvisionerJobTimingStage
+	startedAt time.Time
+	endedAt   time.Time
+}
+
+func (s provisionerStageSpan) contains(t time.Time) bool {
+	return !t.Before(s.startedAt) && !t.After(s.endedAt)
+}
+
+// provisionerStageSpans folds the timings of a job into one span per stage,
+// ordered by start.
+func provisionerStageSpans(timings []database.ProvisionerJobTiming) []provisionerStageSpan {
+	spans := make([]provisionerStageSpan, 0)
+	index := make(map[database.ProvisionerJobTimingStage]int)
+	for _, timing := range timings {
+		i, ok := index[timing.Stage]
+		if !ok {
+			index[timing.Stage] = len(spans)
+			spans = append(spans, provisionerStageSpan{
+				stage:     timing.Stage,
+				startedAt: timing.StartedAt,
+				endedAt:   timing.EndedAt,
+			})
+			continue
+		}
+		if timing.StartedAt.Before(spans[i].startedAt) {
+			spans[i].startedAt = timing.StartedAt
+		}
+		if timing.EndedAt.After(spans[i].endedAt) {
+			spans[i].endedAt = timing.EndedAt
+		}
+	}
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].startedAt.Before(spans[j].startedAt)
+	})
+	return spans
+}
@@ -0,0 +1,85 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceBuildLogStages(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).Do()
+	jobID := r.Build.JobID
+
+	start := dbtime.Now().Add(-time.Hour)
+	at := func(d time.Duration) time.Time {
+		return start.Add(d)
+	}
+	_, err := db.InsertProvisionerJobTimings(ctx, database.InsertProvisionerJobTimingsParams{
+		JobID:     jobID,
+		StartedAt: []time.Time{at(0), at(10 * time.Second), at(20 * time.Second), at(25 * time.Second)},
+		EndedAt:   []time.Time{at(9 * time.Second), at(19 * time.Second), at(30 * time.Second), at(40 * time.Second)},
+		Stage: []database.ProvisionerJobTimingStage{
+			database.ProvisionerJobTimingStageInit,
+			database.ProvisionerJobTimingStagePlan,
+			database.ProvisionerJobTimingStageApply,
+			database.ProvisionerJobTimingStageApply,
+		},
+		Source:   []string{"terraform", "terraform", "terraform", "terraform"},
+		Action:   []string{"init", "read", "create", "create"},
+		Resource: []string{"state file", "data.coder_workspace.me", "docker_container.main", "docker_volume.home"},
+	})
+	require.NoError(t, err)
+	for _, d := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 35 * time.Second} {
+		dbgen.ProvisionerJobLog(t, db, database.ProvisionerJobLog{
+			JobID:     jobID,
+			CreatedAt: at(d),
+		})
+	}
+
+	stages, err := client.WorkspaceBuildLogStages(ctx, r.Build.ID)
+	require.NoError(t, err)
+	require.Len(t, stages, 3)
+	require.Equal(t, codersdk.TimingStageInit, stages[0].Stage)
+	require.EqualValues(t, 3, stages[0].LogCount)
+	require.Equal(t, codersdk.TimingStagePlan, stages[1].Stage)
+	require.Zero(t, stages[1].LogCount)
+	require.Nil(t, stages[1].FirstLogID)
+	// The spans of a stage are merged.
+	require.Equal(t, codersdk.TimingStageApply, stages[2].Stage)
+	require.WithinDuration(t, at(20*time.Second), stages[2].StartedAt, time.Millisecond)
+	require.WithinDuration(t, at(40*time.Second), stages[2].EndedAt, time.Millisecond)
+	require.EqualValues(t, 1, stages[2].LogCount)
+
+	logs, err := client.WorkspaceBuildLogsByStage(ctx, r.Build.ID, codersdk.TimingStageApply)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.NotNil(t, stages[2].FirstLogID)
+	require.Equal(t, *stages[2].FirstLogID, logs[0].ID)
+
+	logs, err = client.WorkspaceBuildLogsByStage(ctx, r.Build.ID, codersdk.TimingStageGraph)
+	require.NoError(t, err)
+	require.Empty(t, logs)
+
+	var apiErr *codersdk.Error
+	_, err = client.WorkspaceBuildLogsByStage(ctx, r.Build.ID, codersdk.TimingStageStart)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
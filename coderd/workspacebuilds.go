@@ -20,11 +20,13 @@ import (
 
 	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/buildlogarchive"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/deprecation"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpapi/httperror"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -32,6 +34,8 @@ import (
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
@@ -88,8 +92,16 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 		data.appStatuses,
 		data.scripts,
 		data.logSources,
+		data.bootstrapProgress,
+		data.networkPolicyViolations,
+		data.crashLoops,
+		data.memoryEvents,
+		data.services,
+		data.serviceTransitions,
 		data.templateVersions[0],
+		data.templates,
 		nil,
+		data.logArchives,
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -99,7 +111,23 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, apiBuild)
+	apiBuilds := []codersdk.WorkspaceBuild{apiBuild}
+	if err := api.attachWorkspaceBuildAnnotations(ctx, apiBuilds); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build annotations.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err := api.attachWorkspaceBuildParameterChanges(ctx, apiBuilds); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build parameter changes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, apiBuilds[0])
 }
 
 // @Summary Get workspace builds by workspace ID
@@ -207,8 +235,16 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		data.appStatuses,
 		data.scripts,
 		data.logSources,
+		data.bootstrapProgress,
+		data.networkPolicyViolations,
+		data.crashLoops,
+		data.memoryEvents,
+		data.services,
+		data.serviceTransitions,
 		data.templateVersions,
+		data.templates,
 		data.provisionerDaemons,
+		data.logArchives,
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -218,6 +254,21 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := api.attachWorkspaceBuildAnnotations(ctx, apiBuilds); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build annotations.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err := api.attachWorkspaceBuildParameterChanges(ctx, apiBuilds); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build parameter changes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	httpapi.Write(ctx, rw, http.StatusOK, apiBuilds)
 }
 
@@ -235,6 +286,7 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 	ctx := r.Context()
 	mems := httpmw.OrganizationMembersParam(r)
 	workspaceName := chi.URLParam(r, "workspacename")
+	apiKey := httpmw.APIKey(r)
 	buildNumber, err := strconv.ParseInt(chi.URLParam(r, "buildnumber"), 10, 32)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -259,6 +311,10 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 		})
 		return
 	}
+	if !apiKey.AllowsTemplate(workspace.TemplateID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
 
 	workspaceBuild, err := api.Database.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
 		WorkspaceID: workspace.ID,
@@ -298,8 +354,16 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 		data.appStatuses,
 		data.scripts,
 		data.logSources,
+		data.bootstrapProgress,
+		data.networkPolicyViolations,
+		data.crashLoops,
+		data.memoryEvents,
+		data.services,
+		data.serviceTransitions,
 		data.templateVersions[0],
+		data.templates,
 		data.provisionerDaemons,
+		data.logArchives,
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -309,7 +373,23 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, apiBuild)
+	apiBuilds := []codersdk.WorkspaceBuild{apiBuild}
+	if err := api.attachWorkspaceBuildAnnotations(ctx, apiBuilds); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build annotations.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err := api.attachWorkspaceBuildParameterChanges(ctx, apiBuilds); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build parameter changes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, apiBuilds[0])
 }
 
 // Azure supports instance identity verification:
@@ -334,6 +414,37 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	idempotencyKey, err := idempotencyKeyFromRequest(r, createBuild.IdempotencyKey)
+	if err != nil {
+		httperror.WriteResponseError(ctx, rw, err)
+		return
+	}
+	var requestHash []byte
+	if idempotencyKey != "" {
+		// The key is not part of the request it identifies.
+		hashReq := createBuild
+		hashReq.IdempotencyKey = ""
+		requestHash, err = idempotencyRequestHash(workspace.ID, hashReq)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		key, ok, err := api.lookupIdempotencyKey(ctx, apiKey.UserID, database.IdempotencyKeyOperationCreateWorkspaceBuild, idempotencyKey, requestHash)
+		if err != nil {
+			httperror.WriteResponseError(ctx, rw, err)
+			return
+		}
+		if ok {
+			apiBuild, err := api.idempotentWorkspaceBuild(ctx, workspace, key)
+			if err != nil {
+				httperror.WriteResponseError(ctx, rw, err)
+				return
+			}
+			httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+			return
+		}
+	}
+
 	// We want to allow a delete build for a deleted workspace, but not a start or stop build.
 	if workspace.Deleted && createBuild.Transition != codersdk.WorkspaceTransitionDelete {
 		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
@@ -358,6 +469,10 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if idempotencyKey != "" {
+		api.recordIdempotencyKey(ctx, apiKey.UserID, database.IdempotencyKeyOperationCreateWorkspaceBuild, idempotencyKey, requestHash, workspace.ID, apiBuild.ID)
+	}
+
 	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
 }
 
@@ -379,6 +494,30 @@ func (api *API) postWorkspaceBuildsInternal(
 		return codersdk.WorkspaceBuild{}, err
 	}
 
+	// With a dormancy hook configured, a dormant workspace can only be started
+	// once the activate hook has restored its storage.
+	if workspace.DormantAt.Valid && createBuild.Transition == codersdk.WorkspaceTransitionStart && api.dormancyHooksEnabled() {
+		if _, err := api.scheduleWorkspaceActivation(ctx, api.Database, workspace); err != nil {
+			return codersdk.WorkspaceBuild{}, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error scheduling workspace activation.",
+				Detail:  err.Error(),
+			})
+		}
+		return codersdk.WorkspaceBuild{}, httperror.NewResponseError(http.StatusConflict, codersdk.Response{
+			Message: "Workspace is dormant and its storage is being restored.",
+			Detail:  "Start the workspace again once the dormancy hook of the workspace has succeeded.",
+		})
+	}
+
+	// An archived workspace can be deleted, but must be unarchived before it
+	// can be started or stopped again.
+	if workspace.ArchivedAt.Valid && createBuild.Transition != codersdk.WorkspaceTransitionDelete && createBuild.Reason != codersdk.CreateWorkspaceBuildReasonUnarchive {
+		return codersdk.WorkspaceBuild{}, httperror.NewResponseError(http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Cannot %s an archived workspace.", createBuild.Transition),
+			Detail:  "Unarchive the workspace to provision its resources again.",
+		})
+	}
+
 	var childParameterValuesJSON json.RawMessage
 	if createBuild.OnSuccess != nil {
 		childParameterValues := createBuild.OnSuccess.RichParameterValues
@@ -407,11 +546,15 @@ func (api *API) postWorkspaceBuildsInternal(
 		DeploymentValues(api.Options.DeploymentValues).
 		Experiments(api.Experiments).
 		TemplateVersionPresetID(createBuild.TemplateVersionPresetID).
+		TargetResources(createBuild.TargetResources).
 		BuildMetrics(api.WorkspaceBuilderMetrics)
 
 	if (transition == database.WorkspaceTransitionStart || transition == database.WorkspaceTransitionStop) && createBuild.Reason != "" {
 		builder = builder.Reason(database.BuildReason(createBuild.Reason))
 	}
+	if transition == database.WorkspaceTransitionDelete && createBuild.Reason == codersdk.CreateWorkspaceBuildReasonArchive {
+		builder = builder.Reason(database.BuildReasonArchive)
+	}
 
 	var (
 		previousWorkspaceBuild database.WorkspaceBuild
@@ -457,6 +600,20 @@ func (api *API) postWorkspaceBuildsInternal(
 			})
 		}
 
+		// Starting an archived workspace reprovisions its resources, so it
+		// is no longer archived.
+		if workspace.ArchivedAt.Valid && transition == database.WorkspaceTransitionStart {
+			if err := tx.UpdateWorkspaceArchivedAt(ctx, database.UpdateWorkspaceArchivedAtParams{
+				ID:         workspace.ID,
+				ArchivedAt: sql.NullTime{Valid: false},
+			}); err != nil {
+				return httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error unsetting workspace archived status",
+					Detail:  err.Error(),
+				})
+			}
+		}
+
 		previousWorkspaceBuild, err = tx.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
 		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
 			api.Logger.Error(ctx, "failed fetching previous workspace build", slog.F("workspace_id", workspace.ID), slog.Error(err))
@@ -468,6 +625,21 @@ func (api *API) postWorkspaceBuildsInternal(
 
 		if createBuild.TemplateVersionID != uuid.Nil {
 			builder = builder.VersionID(createBuild.TemplateVersionID)
+			if transition == database.WorkspaceTransitionStart {
+				// Updates to the active version go through the in-progress
+				// rollout of the template, so workspaces in its cohort get
+				// the rolled out version instead.
+				template, err := tx.GetTemplateByID(ctx, workspace.TemplateID)
+				if err != nil {
+					return httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+						Message: "Internal error fetching template.",
+						Detail:  err.Error(),
+					})
+				}
+				if template.ActiveVersionID == createBuild.TemplateVersionID {
+					builder = builder.ActiveVersion()
+				}
+			}
 		}
 
 		if createBuild.Orphan {
@@ -608,10 +780,23 @@ func (api *API) postWorkspaceBuildsInternal(
 				New:              *workspaceBuild,
 				Status:           http.StatusOK,
 				AdditionalFields: briBytes,
+				CorrelationID:    workspaceBuild.ID,
 			})
 		}
 	}
 
+	// nolint:gocritic // The template is only used to report the build timeout.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		return codersdk.WorkspaceBuild{}, httperror.NewResponseError(
+			http.StatusInternalServerError,
+			codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			},
+		)
+	}
+
 	apiBuild, err := api.convertWorkspaceBuild(
 		*workspaceBuild,
 		workspace,
@@ -623,8 +808,16 @@ func (api *API) postWorkspaceBuildsInternal(
 		[]database.WorkspaceAppStatus{},
 		[]database.GetWorkspaceAgentScriptsByAgentIDsRow{},
 		[]database.WorkspaceAgentLogSource{},
+		[]database.WorkspaceAgentBootstrapProgress{},
+		[]database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow{},
+		[]database.WorkspaceAgentCrashLoop{},
+		[]database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow{},
+		[]database.WorkspaceAgentService{},
+		[]database.WorkspaceAgentServiceTransition{},
 		database.TemplateVersion{},
+		[]database.Template{template},
 		provisionerDaemons,
+		nil,
 	)
 	if err != nil {
 		return codersdk.WorkspaceBuild{}, httperror.NewResponseError(
@@ -939,12 +1132,33 @@ func verifyUserCanCancelWorkspaceBuilds(ctx context.Context, store database.Stor
 // @Security CoderSessionToken
 // @Produce json
 // @Tags Builds
+// @Description Values of sensitive parameters are redacted, unless
+// @Description include_sensitive is set by a user that can update the
+// @Description workspace, such as to rebuild it with the same values.
 // @Param workspacebuild path string true "Workspace build ID"
+// @Param include_sensitive query bool false "Include the values of sensitive parameters"
 // @Success 200 {array} codersdk.WorkspaceBuildParameter
 // @Router /api/v2/workspacebuilds/{workspacebuild}/parameters [get]
 func (api *API) workspaceBuildParameters(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	workspaceBuild := httpmw.WorkspaceBuildParam(r)
+	workspace := httpmw.WorkspaceParam(r)
+
+	parser := httpapi.NewQueryParamParser()
+	includeSensitive := parser.Boolean(r.URL.Query(), false, "include_sensitive")
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+	if includeSensitive && !api.Authorize(r, policy.ActionUpdate, workspace) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only users that can update the workspace can view the values of sensitive parameters.",
+		})
+		return
+	}
 
 	parameters, err := api.Database.GetWorkspaceBuildParameters(ctx, workspaceBuild.ID)
 	if err != nil {
@@ -955,9 +1169,58 @@ func (api *API) workspaceBuildParameters(rw http.ResponseWriter, r *http.Request
 		return
 	}
 	apiParameters := db2sdk.WorkspaceBuildParameters(parameters)
+	if !includeSensitive {
+		for i, param := range parameters {
+			if param.Sensitive {
+				apiParameters[i].Value = redacted
+			}
+		}
+	}
 	httpapi.Write(ctx, rw, http.StatusOK, apiParameters)
 }
 
+// @Summary Get inputs of workspace build
+// @ID get-inputs-of-workspace-build
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID"
+// @Success 200 {object} codersdk.WorkspaceBuildInputs
+// @Router /api/v2/workspacebuilds/{workspacebuild}/inputs [get]
+func (api *API) workspaceBuildInputs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceBuild := httpmw.WorkspaceBuildParam(r)
+
+	row, err := api.Database.GetWorkspaceBuildInputsByBuildID(ctx, workspaceBuild.ID)
+	if httpapi.Is404Error(err) {
+		// Inputs are recorded once a provisioner acquires the build.
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "No inputs were recorded for the workspace build.",
+			Detail:  "Inputs are recorded when a provisioner starts the build.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build inputs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var inputs codersdk.WorkspaceBuildInputs
+	err = json.Unmarshal(row.Inputs, &inputs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error decoding workspace build inputs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	inputs.CreatedAt = row.CreatedAt
+	httpapi.Write(ctx, rw, http.StatusOK, inputs)
+}
+
 // @Summary Get workspace build logs
 // @ID get-workspace-build-logs
 // @Security CoderSessionToken
@@ -968,6 +1231,7 @@ func (api *API) workspaceBuildParameters(rw http.ResponseWriter, r *http.Request
 // @Param after query int false "After log id"
 // @Param follow query bool false "Follow log stream"
 // @Param format query string false "Log output format. Accepted: 'json' (default), 'text' (plain text with RFC3339 timestamps and ANSI colors). Not supported with follow=true." Enums(json,text)
+// @Param stage query string false "Only return the logs written during a provisioning stage. Not supported with follow=true." Enums(init,plan,graph,apply)
 // @Success 200 {array} codersdk.ProvisionerJobLog
 // @Router /api/v2/workspacebuilds/{workspacebuild}/logs [get]
 func (api *API) workspaceBuildLogs(rw http.ResponseWriter, r *http.Request) {
@@ -982,9 +1246,65 @@ func (api *API) workspaceBuildLogs(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if r.URL.Query().Has("stage") {
+		api.workspaceBuildStageLogs(rw, r, job)
+		return
+	}
 	api.provisionerJobLogs(rw, r, job)
 }
 
+// @Summary Get archived workspace build logs
+// @ID get-archived-workspace-build-logs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID"
+// @Success 200 {array} codersdk.ProvisionerJobLog
+// @Router /api/v2/workspacebuilds/{workspacebuild}/logs/archive [get]
+func (api *API) workspaceBuildArchivedLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceBuild := httpmw.WorkspaceBuildParam(r)
+
+	// The dbauthz layer authorizes reading the archive through the job.
+	archive, err := api.Database.GetProvisionerJobLogArchiveByJobID(ctx, workspaceBuild.JobID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The logs of this workspace build have not been archived.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching build log archive.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if archive.ObjectKey == "" {
+		httpapi.Write(ctx, rw, http.StatusGone, codersdk.Response{
+			Message: "The logs of this workspace build were deleted by the build log retention.",
+		})
+		return
+	}
+	if api.Options.BuildLogArchive == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Build log archiving is not configured.",
+			Detail:  "Set --build-logs-archive-dir to retrieve archived build logs.",
+		})
+		return
+	}
+
+	logs, err := buildlogarchive.Read(ctx, api.Options.BuildLogArchive, archive.ObjectKey)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading archived build logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, logs)
+}
+
 // @Summary Get provisioner state for workspace build
 // @ID get-provisioner-state-for-workspace-build
 // @Security CoderSessionToken
@@ -1100,16 +1420,32 @@ func (api *API) workspaceBuildTimings(rw http.ResponseWriter, r *http.Request) {
 }
 
 type workspaceBuildsData struct {
-	jobs               []database.GetProvisionerJobsByIDsWithQueuePositionRow
-	templateVersions   []database.TemplateVersion
-	resources          []database.WorkspaceResource
-	metadata           []database.WorkspaceResourceMetadatum
-	agents             []database.WorkspaceAgent
-	apps               []database.WorkspaceApp
-	appStatuses        []database.WorkspaceAppStatus
-	scripts            []database.GetWorkspaceAgentScriptsByAgentIDsRow
-	logSources         []database.WorkspaceAgentLogSource
+	jobs              []database.GetProvisionerJobsByIDsWithQueuePositionRow
+	templateVersions  []database.TemplateVersion
+	templates         []database.Template
+	resources         []database.WorkspaceResource
+	metadata          []database.WorkspaceResourceMetadatum
+	agents            []database.WorkspaceAgent
+	apps              []database.WorkspaceApp
+	appStatuses       []database.WorkspaceAppStatus
+	scripts           []database.GetWorkspaceAgentScriptsByAgentIDsRow
+	logSources        []database.WorkspaceAgentLogSource
+	bootstrapProgress []database.WorkspaceAgentBootstrapProgress
+	// networkPolicyViolations are the recent outbound network policy
+	// violation counts of the agents, used to compute agent health.
+	networkPolicyViolations []database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow
+	// crashLoops track agents that keep disconnecting shortly after
+	// connecting, used to compute agent health.
+	crashLoops []database.WorkspaceAgentCrashLoop
+	// memoryEvents are the recent OOM kill and memory pressure counts of
+	// the agents, used to compute agent health.
+	memoryEvents []database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow
+	// services are the services supervised by the agents, and
+	// serviceTransitions the latest status transition of each.
+	services           []database.WorkspaceAgentService
+	serviceTransitions []database.WorkspaceAgentServiceTransition
 	provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow
+	logArchives        []database.ProvisionerJobLogArchive
 }
 
 func (api *API) workspaceBuildsData(ctx context.Context, workspaceBuilds []database.WorkspaceBuild) (workspaceBuildsData, error) {
@@ -1147,6 +1483,32 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaceBuilds []datab
 		return workspaceBuildsData{}, xerrors.Errorf("get template versions: %w", err)
 	}
 
+	templateIDs := make([]uuid.UUID, 0, len(templateVersions))
+	for _, templateVersion := range templateVersions {
+		if templateVersion.TemplateID.Valid {
+			templateIDs = append(templateIDs, templateVersion.TemplateID.UUID)
+		}
+	}
+
+	// An empty ID filter matches every template, so only query when there
+	// is something to look up.
+	var templates []database.Template
+	if len(templateIDs) > 0 {
+		// nolint:gocritic // Getting templates by ID is a system function.
+		templates, err = api.Database.GetTemplatesWithFilter(dbauthz.AsSystemRestricted(ctx), database.GetTemplatesWithFilterParams{
+			IDs: slice.Unique(templateIDs),
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return workspaceBuildsData{}, xerrors.Errorf("get templates: %w", err)
+		}
+	}
+
+	// nolint:gocritic // Getting build log archives by job ID is a system function.
+	logArchives, err := api.Database.GetProvisionerJobLogArchivesByJobIDs(dbauthz.AsSystemRestricted(ctx), jobIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return workspaceBuildsData{}, xerrors.Errorf("get provisioner job log archives: %w", err)
+	}
+
 	// nolint:gocritic // Getting workspace resources by job ID is a system function.
 	resources, err := api.Database.GetWorkspaceResourcesByJobIDs(dbauthz.AsSystemRestricted(ctx), jobIDs)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -1157,7 +1519,9 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaceBuilds []datab
 		return workspaceBuildsData{
 			jobs:               jobs,
 			templateVersions:   templateVersions,
+			templates:          templates,
 			provisionerDaemons: pendingJobProvisioners,
+			logArchives:        logArchives,
 		}, nil
 	}
 
@@ -1182,9 +1546,11 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaceBuilds []datab
 		return workspaceBuildsData{
 			jobs:               jobs,
 			templateVersions:   templateVersions,
+			templates:          templates,
 			resources:          resources,
 			metadata:           metadata,
 			provisionerDaemons: pendingJobProvisioners,
+			logArchives:        logArchives,
 		}, nil
 	}
 
@@ -1194,9 +1560,15 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaceBuilds []datab
 	}
 
 	var (
-		apps       []database.WorkspaceApp
-		scripts    []database.GetWorkspaceAgentScriptsByAgentIDsRow
-		logSources []database.WorkspaceAgentLogSource
+		apps                    []database.WorkspaceApp
+		scripts                 []database.GetWorkspaceAgentScriptsByAgentIDsRow
+		logSources              []database.WorkspaceAgentLogSource
+		bootstrapProgress       []database.WorkspaceAgentBootstrapProgress
+		networkPolicyViolations []database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow
+		crashLoops              []database.WorkspaceAgentCrashLoop
+		memoryEvents            []database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow
+		services                []database.WorkspaceAgentService
+		serviceTransitions      []database.WorkspaceAgentServiceTransition
 	)
 
 	var eg errgroup.Group
@@ -1215,6 +1587,36 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaceBuilds []datab
 		logSources, err = api.Database.GetWorkspaceAgentLogSourcesByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
 		return err
 	})
+	eg.Go(func() (err error) {
+		// nolint:gocritic // Getting workspace agent bootstrap progress by agent IDs is a system function.
+		bootstrapProgress, err = api.Database.GetLatestWorkspaceAgentBootstrapProgressByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+		return err
+	})
+	eg.Go(func() (err error) {
+		// nolint:gocritic // Getting network policy violation counts by agent IDs is a system function.
+		networkPolicyViolations, err = api.Database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDs(dbauthz.AsSystemRestricted(ctx), database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsParams{
+			IDs:          agentIDs,
+			CreatedAfter: dbtime.Now().Add(-networkPolicyViolationHealthWindow),
+		})
+		return err
+	})
+	eg.Go(func() (err error) {
+		// nolint:gocritic // Getting agent crash loops by agent IDs is a system function.
+		crashLoops, err = api.Database.GetWorkspaceAgentCrashLoopsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+		return err
+	})
+	eg.Go(func() (err error) {
+		// nolint:gocritic // Getting memory event counts by agent IDs is a system function.
+		memoryEvents, err = api.Database.GetWorkspaceAgentMemoryEventCountsByAgentIDs(dbauthz.AsSystemRestricted(ctx), database.GetWorkspaceAgentMemoryEventCountsByAgentIDsParams{
+			IDs:          agentIDs,
+			CreatedAfter: dbtime.Now().Add(-memoryEventHealthWindow),
+		})
+		return err
+	})
+	eg.Go(func() (err error) {
+		services, serviceTransitions, err = api.workspaceAgentServicesData(ctx, agentIDs)
+		return err
+	})
 	err = eg.Wait()
 	if err != nil {
 		return workspaceBuildsData{}, err
@@ -1232,16 +1634,24 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaceBuilds []datab
 	}
 
 	return workspaceBuildsData{
-		jobs:               jobs,
-		templateVersions:   templateVersions,
-		resources:          resources,
-		metadata:           metadata,
-		agents:             agents,
-		apps:               apps,
-		appStatuses:        statuses,
-		scripts:            scripts,
-		logSources:         logSources,
-		provisionerDaemons: pendingJobProvisioners,
+		jobs:                    jobs,
+		templateVersions:        templateVersions,
+		templates:               templates,
+		resources:               resources,
+		metadata:                metadata,
+		agents:                  agents,
+		apps:                    apps,
+		appStatuses:             statuses,
+		scripts:                 scripts,
+		logSources:              logSources,
+		bootstrapProgress:       bootstrapProgress,
+		networkPolicyViolations: networkPolicyViolations,
+		crashLoops:              crashLoops,
+		memoryEvents:            memoryEvents,
+		services:                services,
+		serviceTransitions:      serviceTransitions,
+		provisionerDaemons:      pendingJobProvisioners,
+		logArchives:             logArchives,
 	}, nil
 }
 
@@ -1256,8 +1666,16 @@ func (api *API) convertWorkspaceBuilds(
 	agentAppStatuses []database.WorkspaceAppStatus,
 	agentScripts []database.GetWorkspaceAgentScriptsByAgentIDsRow,
 	agentLogSources []database.WorkspaceAgentLogSource,
+	agentBootstrapProgress []database.WorkspaceAgentBootstrapProgress,
+	agentNetworkPolicyViolations []database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow,
+	agentCrashLoops []database.WorkspaceAgentCrashLoop,
+	agentMemoryEvents []database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow,
+	agentServices []database.WorkspaceAgentService,
+	agentServiceTransitions []database.WorkspaceAgentServiceTransition,
 	templateVersions []database.TemplateVersion,
+	templates []database.Template,
 	provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow,
+	logArchives []database.ProvisionerJobLogArchive,
 ) ([]codersdk.WorkspaceBuild, error) {
 	workspaceByID := map[uuid.UUID]database.Workspace{}
 	for _, workspace := range workspaces {
@@ -1299,8 +1717,16 @@ func (api *API) convertWorkspaceBuilds(
 			agentAppStatuses,
 			agentScripts,
 			agentLogSources,
+			agentBootstrapProgress,
+			agentNetworkPolicyViolations,
+			agentCrashLoops,
+			agentMemoryEvents,
+			agentServices,
+			agentServiceTransitions,
 			templateVersion,
+			templates,
 			provisionerDaemons,
+			logArchives,
 		)
 		if err != nil {
 			return nil, xerrors.Errorf("converting workspace build: %w", err)
@@ -1323,8 +1749,16 @@ func (api *API) convertWorkspaceBuild(
 	agentAppStatuses []database.WorkspaceAppStatus,
 	agentScripts []database.GetWorkspaceAgentScriptsByAgentIDsRow,
 	agentLogSources []database.WorkspaceAgentLogSource,
+	agentBootstrapProgress []database.WorkspaceAgentBootstrapProgress,
+	agentNetworkPolicyViolations []database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow,
+	agentCrashLoops []database.WorkspaceAgentCrashLoop,
+	agentMemoryEvents []database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow,
+	agentServices []database.WorkspaceAgentService,
+	agentServiceTransitions []database.WorkspaceAgentServiceTransition,
 	templateVersion database.TemplateVersion,
+	templates []database.Template,
 	provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow,
+	logArchives []database.ProvisionerJobLogArchive,
 ) (codersdk.WorkspaceBuild, error) {
 	resourcesByJobID := map[uuid.UUID][]database.WorkspaceResource{}
 	for _, resource := range workspaceResources {
@@ -1350,6 +1784,26 @@ func (api *API) convertWorkspaceBuild(
 	for _, logSource := range agentLogSources {
 		logSourcesByAgentID[logSource.WorkspaceAgentID] = append(logSourcesByAgentID[logSource.WorkspaceAgentID], logSource)
 	}
+	bootstrapProgressByAgentID := map[uuid.UUID]database.WorkspaceAgentBootstrapProgress{}
+	for _, progress := range agentBootstrapProgress {
+		bootstrapProgressByAgentID[progress.WorkspaceAgentID] = progress
+	}
+	networkPolicyViolationsByAgentID := map[uuid.UUID]int64{}
+	for _, violations := range agentNetworkPolicyViolations {
+		networkPolicyViolationsByAgentID[violations.WorkspaceAgentID] = violations.Violations
+	}
+	crashLoopsByAgentID := map[uuid.UUID]database.WorkspaceAgentCrashLoop{}
+	for _, loop := range agentCrashLoops {
+		crashLoopsByAgentID[loop.AgentID] = loop
+	}
+	memoryEventsByAgentID := map[uuid.UUID]database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow{}
+	for _, events := range agentMemoryEvents {
+		memoryEventsByAgentID[events.WorkspaceAgentID] = events
+	}
+	servicesByAgentID := map[uuid.UUID][]database.WorkspaceAgentService{}
+	for _, service := range agentServices {
+		servicesByAgentID[service.WorkspaceAgentID] = append(servicesByAgentID[service.WorkspaceAgentID], service)
+	}
 	provisionerDaemonsForThisWorkspaceBuild := []database.ProvisionerDaemon{}
 	for _, provisionerDaemon := range provisionerDaemons {
 		if provisionerDaemon.JobID != job.ProvisionerJob.ID {
@@ -1358,11 +1812,37 @@ func (api *API) convertWorkspaceBuild(
 		provisionerDaemonsForThisWorkspaceBuild = append(provisionerDaemonsForThisWorkspaceBuild, provisionerDaemon.ProvisionerDaemon)
 	}
 	matchedProvisioners := db2sdk.MatchedProvisioners(provisionerDaemonsForThisWorkspaceBuild, job.ProvisionerJob.CreatedAt, provisionerdserver.StaleInterval)
+	var logsArchive *codersdk.WorkspaceBuildLogsArchive
+	for _, archive := range logArchives {
+		if archive.JobID != job.ProvisionerJob.ID {
+			continue
+		}
+		logsArchive = &codersdk.WorkspaceBuildLogsArchive{
+			RemovedAt: archive.ArchivedAt,
+			Archived:  archive.ObjectKey != "",
+		}
+		if logsArchive.Archived {
+			logsArchive.Path = fmt.Sprintf("/api/v2/workspacebuilds/%s/logs/archive", build.ID)
+		}
+		break
+	}
 	statusesByAgentID := map[uuid.UUID][]database.WorkspaceAppStatus{}
 	for _, status := range agentAppStatuses {
 		statusesByAgentID[status.AgentID] = append(statusesByAgentID[status.AgentID], status)
 	}
 
+	var provisionerTimeout time.Duration
+	agentRolloutChannel := codersdk.AgentRolloutChannelStable
+	var buildTemplate database.Template
+	for _, template := range templates {
+		if template.ID == workspace.TemplateID {
+			buildTemplate = template
+			provisionerTimeout = time.Duration(template.ProvisionerApplyTimeout)
+			agentRolloutChannel = api.agentRolloutChannel(template.AgentRolloutChannel, workspace.ID)
+			break
+		}
+	}
+
 	resources := resourcesByJobID[job.ProvisionerJob.ID]
 	apiResources := make([]codersdk.WorkspaceResource, 0)
 	resourceAgentsMinOrder := map[uuid.UUID]int32{} // map[resource.ID]minOrder
@@ -1392,6 +1872,39 @@ func (api *API) convertWorkspaceBuild(
 			if err != nil {
 				return codersdk.WorkspaceBuild{}, xerrors.Errorf("converting workspace agent: %w", err)
 			}
+			apiAgent.RolloutChannel = agentRolloutChannel
+			if progress, ok := bootstrapProgressByAgentID[agent.ID]; ok {
+				apiAgent.BootstrapProgress = ptr.Ref(convertWorkspaceAgentBootstrapProgress(progress))
+			}
+			if violations := networkPolicyViolationsByAgentID[agent.ID]; violations > 0 && apiAgent.Health.Healthy {
+				apiAgent.Health = codersdk.WorkspaceAgentHealth{
+					Healthy: false,
+					Reason:  networkPolicyViolationsHealthReason(violations),
+				}
+			}
+			if events, ok := memoryEventsByAgentID[agent.ID]; ok && apiAgent.Health.Healthy {
+				apiAgent.Health = codersdk.WorkspaceAgentHealth{
+					Healthy: false,
+					Reason:  agentMemoryEventsHealthReason(events),
+				}
+			}
+			if services := servicesByAgentID[agent.ID]; len(services) > 0 {
+				apiAgent.Services = convertWorkspaceAgentServices(services, agentServiceTransitions)
+				if reason := agentServicesHealthReason(apiAgent.Services); reason != "" && apiAgent.Health.Healthy {
+					apiAgent.Health = codersdk.WorkspaceAgentHealth{
+						Healthy: false,
+						Reason:  reason,
+					}
+				}
+			}
+			// A crash looping agent is the more actionable problem, so it
+			// takes precedence over other health reasons.
+			if loop, ok := crashLoopsByAgentID[agent.ID]; ok && loop.CrashLoopingSince.Valid {
+				apiAgent.Health = codersdk.WorkspaceAgentHealth{
+					Healthy: false,
+					Reason:  agentCrashLoopHealthReason(loop),
+				}
+			}
 			apiAgents = append(apiAgents, apiAgent)
 		}
 		metadata := append(make([]database.WorkspaceResourceMetadatum, 0), metadataByResourceID[resource.ID]...)
@@ -1419,34 +1932,46 @@ func (api *API) convertWorkspaceBuild(
 		hasExternalAgent = &build.HasExternalAgent.Bool
 	}
 
+	// The log level is recorded in the input of the provisioner job, so
+	// that it is applied when the job is acquired. A malformed input is
+	// already reported by the job, so it's ignored here.
+	var input provisionerdserver.WorkspaceProvisionJob
+	if len(job.ProvisionerJob.Input) > 0 {
+		_ = json.Unmarshal(job.ProvisionerJob.Input, &input)
+	}
+
 	apiJob := convertProvisionerJob(job)
 	transition := codersdk.WorkspaceTransition(build.Transition)
 	return codersdk.WorkspaceBuild{
-		ID:                      build.ID,
-		CreatedAt:               build.CreatedAt,
-		UpdatedAt:               build.UpdatedAt,
-		WorkspaceOwnerID:        workspace.OwnerID,
-		WorkspaceOwnerName:      workspace.OwnerUsername,
-		WorkspaceOwnerAvatarURL: workspace.OwnerAvatarUrl,
-		WorkspaceID:             build.WorkspaceID,
-		WorkspaceName:           workspace.Name,
-		TemplateVersionID:       build.TemplateVersionID,
-		TemplateVersionName:     templateVersion.Name,
-		BuildNumber:             build.BuildNumber,
-		Transition:              transition,
-		InitiatorID:             build.InitiatorID,
-		InitiatorUsername:       build.InitiatorByUsername,
-		Job:                     apiJob,
-		Deadline:                codersdk.NewNullTime(build.Deadline, !build.Deadline.IsZero()),
-		MaxDeadline:             codersdk.NewNullTime(build.MaxDeadline, !build.MaxDeadline.IsZero()),
-		Reason:                  codersdk.BuildReason(build.Reason),
-		Resources:               apiResources,
-		Status:                  codersdk.ConvertWorkspaceStatus(apiJob.Status, transition),
-		DailyCost:               build.DailyCost,
-		MatchedProvisioners:     &matchedProvisioners,
-		TemplateVersionPresetID: presetID,
-		HasAITask:               hasAITask,
-		HasExternalAgent:        hasExternalAgent,
+		ID:                       build.ID,
+		CreatedAt:                build.CreatedAt,
+		UpdatedAt:                build.UpdatedAt,
+		WorkspaceOwnerID:         workspace.OwnerID,
+		WorkspaceOwnerName:       workspace.OwnerUsername,
+		WorkspaceOwnerAvatarURL:  workspace.OwnerAvatarUrl,
+		WorkspaceID:              build.WorkspaceID,
+		WorkspaceName:            workspace.Name,
+		TemplateVersionID:        build.TemplateVersionID,
+		TemplateVersionName:      templateVersion.Name,
+		BuildNumber:              build.BuildNumber,
+		Transition:               transition,
+		InitiatorID:              build.InitiatorID,
+		InitiatorUsername:        build.InitiatorByUsername,
+		Job:                      apiJob,
+		Deadline:                 codersdk.NewNullTime(build.Deadline, !build.Deadline.IsZero()),
+		MaxDeadline:              codersdk.NewNullTime(build.MaxDeadline, !build.MaxDeadline.IsZero()),
+		Reason:                   codersdk.BuildReason(build.Reason),
+		Resources:                apiResources,
+		Status:                   codersdk.ConvertWorkspaceStatus(apiJob.Status, transition),
+		DailyCost:                build.DailyCost,
+		MatchedProvisioners:      &matchedProvisioners,
+		TemplateVersionPresetID:  presetID,
+		HasAITask:                hasAITask,
+		HasExternalAgent:         hasExternalAgent,
+		ProvisionerTimeoutMillis: provisionerTimeout.Milliseconds(),
+		DeprecationWarnings:      deprecation.Warnings(buildTemplate, templateVersion),
+		LogsArchive:              logsArchive,
+		LogLevel:                 codersdk.ProvisionerLogLevel(input.LogLevel),
 	}, nil
 }
 
@@ -1501,10 +2026,22 @@ func (api *API) buildTimings(ctx context.Context, build database.WorkspaceBuild)
 		return codersdk.WorkspaceBuildTimings{}, xerrors.Errorf("fetching workspace agents: %w", err)
 	}
 
+	agentIDs := make([]uuid.UUID, 0, len(agents))
+	for _, agent := range agents {
+		agentIDs = append(agentIDs, agent.ID)
+	}
+	//nolint:gocritic // Already checked if the build can be fetched.
+	serviceTransitions, err := api.Database.GetWorkspaceAgentServiceTransitionsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.WorkspaceBuildTimings{}, xerrors.Errorf("fetching workspace agent service transitions: %w", err)
+	}
+
 	res := codersdk.WorkspaceBuildTimings{
 		ProvisionerTimings:     make([]codersdk.ProvisionerTiming, 0, len(provisionerTimings)),
 		AgentScriptTimings:     make([]codersdk.AgentScriptTiming, 0, len(agentScriptTimings)),
 		AgentConnectionTimings: make([]codersdk.AgentConnectionTiming, 0, len(agents)),
+		AgentServiceTimings:    agentServiceTimings(agents, serviceTransitions, dbtime.Now()),
+		CacheStats:             provisionerCacheStats(provisionerTimings),
 	}
 
 	for _, t := range provisionerTimings {
@@ -1571,3 +2108,62 @@ func (api *API) buildTimings(ctx context.Context, build database.WorkspaceBuild)
 
 	return res, nil
 }
+
+// provisionerCacheStats counts the cache hits and misses that provisioners
+// reported as init stage timings, per kind of artifact. Every kind is
+// included so that clients can tell a build without hits from an unknown one.
+func provisionerCacheStats(timings []database.ProvisionerJobTiming) []codersdk.ProvisionerCacheStats {
+	stats := make([]codersdk.ProvisionerCacheStats, 0, len(codersdk.ProvisionerCacheArtifacts))
+	for _, artifact := range codersdk.ProvisionerCacheArtifacts {
+		stats = append(stats, codersdk.ProvisionerCacheStats{Artifact: artifact})
+	}
+	for _, t := range timings {
+		if t.Stage != database.ProvisionerJobTimingStageInit {
+			continue
+		}
+		for i := range stats {
+			if string(stats[i].Artifact) != t.Source {
+				continue
+			}
+			switch t.Action {
+			case codersdk.ProvisionerTimingActionCacheHit:
+				stats[i].Hits++
+			case codersdk.ProvisionerTimingActionCacheMiss:
+				stats[i].Misses++
+			}
+		}
+	}
+	return stats
+}
+
+// attachWorkspaceBuildParameterChanges sets the ParameterChanges of each build.
+func (api *API) attachWorkspaceBuildParameterChanges(ctx context.Context, builds []codersdk.WorkspaceBuild) error {
+	if len(builds) == 0 {
+		return nil
+	}
+	buildIDs := make([]uuid.UUID, 0, len(builds))
+	for _, build := range builds {
+		buildIDs = append(buildIDs, build.ID)
+	}
+
+	// nolint:gocritic // Parameter changes are readable by anyone that can read the build.
+	changes, err := api.Database.GetWorkspaceBuildParameterChangesByBuildIDs(dbauthz.AsSystemRestricted(ctx), buildIDs)
+	if err != nil {
+		return xerrors.Errorf("get workspace build parameter changes: %w", err)
+	}
+
+	byBuildID := make(map[uuid.UUID][]codersdk.WorkspaceBuildParameterChange, len(builds))
+	for _, change := range changes {
+		byBuildID[change.WorkspaceBuildID] = append(byBuildID[change.WorkspaceBuildID], codersdk.WorkspaceBuildParameterChange{
+			Name:          change.Name,
+			Kind:          codersdk.WorkspaceBuildParameterChangeKind(change.Kind),
+			PreviousValue: change.PreviousValue,
+			NewValue:      change.NewValue,
+			Redacted:      change.Redacted,
+		})
+	}
+	for i := range builds {
+		builds[i].ParameterChanges = byBuildID[builds[i].ID]
+	}
+	return nil
+}
@@ -3,13 +3,18 @@ package coderd_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"slices"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -23,6 +28,8 @@ import (
 	"cdr.dev/slog/v3"
 	"cdr.dev/slog/v3/sloggers/slogtest"
 	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/buildhook"
+	"github.com/coder/coder/v2/coderd/buildlogarchive"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
 	"github.com/coder/coder/v2/coderd/database"
@@ -36,6 +43,7 @@ import (
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
@@ -1094,6 +1102,56 @@ func TestWorkspaceBuildLogs(t *testing.T) {
 	require.Fail(t, "example message never happened")
 }
 
+func TestWorkspaceBuildArchivedLogs(t *testing.T) {
+	t.Parallel()
+
+	store, err := buildlogarchive.NewDirStore(t.TempDir())
+	require.NoError(t, err)
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{BuildLogArchive: store})
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Logs that are still in the database are not reported as archived.
+	build, err := client.WorkspaceBuild(ctx, r.Build.ID)
+	require.NoError(t, err)
+	require.Nil(t, build.LogsArchive)
+	_, err = client.WorkspaceBuildArchivedLogs(ctx, r.Build.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	key := buildlogarchive.ObjectKey(r.Build.JobID)
+	err = buildlogarchive.Write(ctx, store, key, []codersdk.ProvisionerJobLog{{
+		ID:     1,
+		Stage:  "Planning",
+		Output: "archived output",
+	}})
+	require.NoError(t, err)
+	//nolint:gocritic // The archiver runs as the system.
+	err = db.InsertProvisionerJobLogArchive(dbauthz.AsSystemRestricted(ctx), database.InsertProvisionerJobLogArchiveParams{
+		JobID:      r.Build.JobID,
+		ArchivedAt: dbtime.Now(),
+		ObjectKey:  key,
+	})
+	require.NoError(t, err)
+
+	build, err = client.WorkspaceBuild(ctx, r.Build.ID)
+	require.NoError(t, err)
+	require.NotNil(t, build.LogsArchive)
+	require.True(t, build.LogsArchive.Archived)
+	require.Equal(t, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs/archive", r.Build.ID), build.LogsArchive.Path)
+
+	logs, err := client.WorkspaceBuildArchivedLogs(ctx, r.Build.ID)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, "archived output", logs[0].Output)
+}
+
 func TestWorkspaceBuildLogsFormat(t *testing.T) {
 	t.Parallel()
 
@@ -1213,6 +1271,124 @@ func TestWorkspaceBuildState(t *testing.T) {
 	require.Equal(t, wantState, gotState)
 }
 
+func TestWorkspaceBuildInputs(t *testing.T) {
+	t.Parallel()
+	const parameterName = "region"
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionGraph: []*proto.Response{{
+			Type: &proto.Response_Graph{
+				Graph: &proto.GraphComplete{
+					Parameters: []*proto.RichParameter{{
+						Name:         parameterName,
+						Type:         "string",
+						DefaultValue: "us",
+						Mutable:      true,
+						FormType:     proto.ParameterFormType_INPUT,
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: parameterName, Value: "eu"}}
+	})
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	source, _, err := client.Download(ctx, version.Job.FileID)
+	require.NoError(t, err)
+	hash := sha256.Sum256(source)
+
+	inputs, err := client.WorkspaceBuildInputs(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, workspace.LatestBuild.ID, inputs.WorkspaceBuildID)
+	require.Equal(t, template.ID, inputs.TemplateID)
+	require.Equal(t, version.ID, inputs.TemplateVersionID)
+	require.Equal(t, hex.EncodeToString(hash[:]), inputs.TemplateVersionHash)
+	require.Equal(t, codersdk.WorkspaceTransitionStart, inputs.Transition)
+	require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: parameterName, Value: "eu"}}, inputs.Parameters)
+	require.Equal(t, workspace.ID, inputs.Metadata.WorkspaceID)
+	require.Equal(t, user.UserID, inputs.Metadata.WorkspaceOwnerID)
+	require.Equal(t, codersdk.ProvisionerTypeEcho, inputs.Deployment.Provisioner)
+	require.NotZero(t, inputs.CreatedAt)
+}
+
+func TestWorkspaceBuildParametersSensitive(t *testing.T) {
+	t.Parallel()
+	const (
+		parameterName = "api_key"
+		//nolint:gosec // test credentials
+		parameterValue = "super-secret"
+	)
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateAdmin())
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionGraph: []*proto.Response{{
+			Type: &proto.Response_Graph{
+				Graph: &proto.GraphComplete{
+					Parameters: []*proto.RichParameter{{
+						Name:      parameterName,
+						Type:      "string",
+						Mutable:   true,
+						Sensitive: true,
+						FormType:  proto.ParameterFormType_INPUT,
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: parameterName, Value: parameterValue}}
+	})
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	richParameters, err := client.TemplateVersionRichParameters(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, richParameters, 1)
+	require.True(t, richParameters[0].Sensitive)
+
+	params, err := client.WorkspaceBuildParameters(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: parameterName, Value: "*redacted*"}}, params)
+
+	params, err = client.WorkspaceBuildParametersForRebuild(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: parameterName, Value: parameterValue}}, params)
+
+	// Sensitive values are not recorded in the build inputs.
+	inputs, err := client.WorkspaceBuildInputs(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: parameterName}}, inputs.Parameters)
+
+	// Users that can only read the workspace can't view sensitive values.
+	params, err = templateAdmin.WorkspaceBuildParameters(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, "*redacted*", params[0].Value)
+	_, err = templateAdmin.WorkspaceBuildParametersForRebuild(ctx, workspace.LatestBuild.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Rebuilding the workspace keeps the sensitive value.
+	coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStop, codersdk.WorkspaceTransitionStart)
+	params, err = client.WorkspaceBuildParametersForRebuild(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: parameterName, Value: parameterValue}}, params)
+}
+
 func TestWorkspaceBuildStatus(t *testing.T) {
 	t.Parallel()
 
@@ -1476,6 +1652,35 @@ func TestWorkspaceBuildDebugMode(t *testing.T) {
 		require.True(t, isSdkError)
 		require.Contains(t, sdkError.Message, "Workspace builds with a custom log level are restricted to administrators only.")
 	})
+	t.Run("AsAuditor", func(t *testing.T) {
+		t.Parallel()
+
+		deploymentValues := coderdtest.DeploymentValues(t)
+		deploymentValues.EnableTerraformDebugMode = true
+
+		adminClient := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, DeploymentValues: deploymentValues})
+		owner := coderdtest.CreateFirstUser(t, adminClient)
+		auditorClient, _ := coderdtest.CreateAnotherUser(t, adminClient, owner.OrganizationID, rbac.RoleAuditor())
+
+		version := coderdtest.CreateTemplateVersion(t, adminClient, owner.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, adminClient, owner.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, adminClient, version.ID)
+
+		// Auditors can read the deployment config, but may not request debug
+		// logs for their own workspaces.
+		workspace := coderdtest.CreateWorkspace(t, auditorClient, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, auditorClient, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := auditorClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			TemplateVersionID: workspace.LatestBuild.TemplateVersionID,
+			Transition:        codersdk.WorkspaceTransitionStart,
+			LogLevel:          codersdk.ProvisionerLogLevelDebug,
+		})
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		require.Contains(t, sdkError.Message, "Workspace builds with a custom log level are restricted to administrators only.")
+	})
 	t.Run("AsAdmin", func(t *testing.T) {
 		t.Parallel()
 
@@ -1538,6 +1743,9 @@ func TestWorkspaceBuildDebugMode(t *testing.T) {
 		require.Nil(t, err)
 
 		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, adminClient, build.ID)
+		// The log level is recorded on the build.
+		require.Equal(t, codersdk.ProvisionerLogLevelDebug, build.LogLevel)
+		require.Empty(t, workspace.LatestBuild.LogLevel)
 
 		// Watch for incoming logs
 		logs, closer, err := adminClient.WorkspaceBuildLogsAfter(ctx, build.ID, 0)
@@ -1962,6 +2170,121 @@ func TestPostWorkspaceBuild(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, codersdk.BuildReasonDashboard, build.Reason)
 	})
+	t.Run("TargetResources", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		req := codersdk.CreateWorkspaceBuildRequest{
+			Transition:      codersdk.WorkspaceTransitionStart,
+			TargetResources: []string{"docker_container.workspace[0]"},
+		}
+
+		// Targeted builds are opt-in per template.
+		_, err := client.CreateWorkspaceBuild(ctx, workspace.ID, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		allow := true
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			AllowTargetedBuilds: &allow,
+		})
+		require.NoError(t, err)
+
+		// Only managed resource addresses are accepted.
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:      codersdk.WorkspaceTransitionStart,
+			TargetResources: []string{"data.coder_workspace.me"},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Targets only apply to start builds.
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:      codersdk.WorkspaceTransitionStop,
+			TargetResources: req.TargetResources,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, req)
+		require.NoError(t, err)
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+	})
+	t.Run("ReconfirmParameters", func(t *testing.T) {
+		t.Parallel()
+		const parameterName = "region"
+		responses := &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionGraph: []*proto.Response{{
+				Type: &proto.Response_Graph{
+					Graph: &proto.GraphComplete{
+						Parameters: []*proto.RichParameter{{
+							Name:         parameterName,
+							Type:         "string",
+							DefaultValue: "us",
+							Mutable:      true,
+							FormType:     proto.ParameterFormType_INPUT,
+						}},
+					},
+				},
+			}},
+			ProvisionApply: echo.ApplyComplete,
+		}
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, responses)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: parameterName, Value: "eu"}}
+		})
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ReconfirmParameters: &[]string{parameterName},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{parameterName}, updated.ReconfirmParameters)
+
+		// Stopping on the same version keeps the stored value.
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+		version2 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, responses, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+
+		// Updating to the new version requires the value to be set again.
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:        codersdk.WorkspaceTransitionStart,
+			TemplateVersionID: version2.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, parameterName)
+
+		build, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:          codersdk.WorkspaceTransitionStart,
+			TemplateVersionID:   version2.ID,
+			RichParameterValues: []codersdk.WorkspaceBuildParameter{{Name: parameterName, Value: "eu"}},
+		})
+		require.NoError(t, err)
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+	})
 	t.Run("DeletedWorkspace", func(t *testing.T) {
 		t.Parallel()
 
@@ -2144,6 +2467,54 @@ func TestWorkspaceBuildTimings(t *testing.T) {
 		}
 	})
 
+	t.Run("CacheStats", func(t *testing.T) {
+		t.Parallel()
+
+		// Given: a build whose init reported cache hits and misses
+		build := makeBuild(t)
+		now := dbtime.Now()
+		_, err := db.InsertProvisionerJobTimings(context.Background(), database.InsertProvisionerJobTimingsParams{
+			JobID:     build.JobID,
+			StartedAt: []time.Time{now, now, now, now, now},
+			EndedAt:   []time.Time{now, now, now, now, now},
+			Stage: []database.ProvisionerJobTimingStage{
+				database.ProvisionerJobTimingStageInit,
+				database.ProvisionerJobTimingStageInit,
+				database.ProvisionerJobTimingStageInit,
+				database.ProvisionerJobTimingStageInit,
+				database.ProvisionerJobTimingStageInit,
+			},
+			Source: []string{
+				string(codersdk.ProvisionerCacheArtifactProviderPlugin),
+				string(codersdk.ProvisionerCacheArtifactProviderPlugin),
+				string(codersdk.ProvisionerCacheArtifactProviderPlugin),
+				string(codersdk.ProvisionerCacheArtifactModule),
+				"",
+			},
+			Action: []string{
+				codersdk.ProvisionerTimingActionCacheHit,
+				codersdk.ProvisionerTimingActionCacheHit,
+				codersdk.ProvisionerTimingActionCacheMiss,
+				codersdk.ProvisionerTimingActionCacheMiss,
+				"load",
+			},
+			Resource: []string{"hashicorp/http v3.5.0", "coder/coder v2.11.0", "kreuzwerker/docker v3.6.2", "cursor", "modules"},
+		})
+		require.NoError(t, err)
+
+		// When: fetching timings for the build
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		t.Cleanup(cancel)
+		res, err := client.WorkspaceBuildTimings(ctx, build.ID)
+		require.NoError(t, err)
+
+		// Then: the hits and misses are counted per kind of artifact
+		require.Equal(t, []codersdk.ProvisionerCacheStats{
+			{Artifact: codersdk.ProvisionerCacheArtifactProviderPlugin, Hits: 2, Misses: 1},
+			{Artifact: codersdk.ProvisionerCacheArtifactModule, Hits: 0, Misses: 1},
+		}, res.CacheStats)
+	})
+
 	t.Run("MultipleTimingsForSameAgentScript", func(t *testing.T) {
 		t.Parallel()
 
@@ -2327,3 +2698,83 @@ func TestWorkspaceBuildTimings(t *testing.T) {
 		require.Len(t, res.AgentConnectionTimings, 5)
 	})
 }
+
+func TestWorkspaceBuildHooks(t *testing.T) {
+	t.Parallel()
+
+	var reject atomic.Bool
+	reject.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var req buildhook.Request
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch req.Event {
+		case buildhook.EventPreBuild:
+			if reject.Load() {
+				rw.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(rw).Encode(buildhook.Response{Message: "no addresses left in pool"})
+				return
+			}
+			_ = json.NewEncoder(rw).Encode(buildhook.Response{
+				Steps: []buildhook.Step{{Name: "allocate ip", Detail: "10.0.0.7"}},
+			})
+		case buildhook.EventPostBuild:
+			_ = json.NewEncoder(rw).Encode(buildhook.Response{
+				Steps: []buildhook.Step{{Name: "register dns"}},
+			})
+		}
+	}))
+	defer srv.Close()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		PreBuildHookURL:  ptr.Ref(srv.URL + "/pre"),
+		PostBuildHookURL: ptr.Ref(srv.URL + "/post"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/pre", template.PreBuildHookURL)
+	require.Equal(t, srv.URL+"/post", template.PostBuildHookURL)
+
+	// A rejected build fails with the message of the hook.
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	require.Equal(t, codersdk.ProvisionerJobFailed, build.Job.Status)
+	require.Contains(t, build.Job.Error, "no addresses left in pool")
+
+	// The steps reported by both hooks are recorded in the build logs.
+	reject.Store(false)
+	build = coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStart)
+	build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+	require.Equal(t, codersdk.ProvisionerJobSucceeded, build.Job.Status)
+
+	logs, closer, err := client.WorkspaceBuildLogsAfter(ctx, build.ID, 0)
+	require.NoError(t, err)
+	defer closer.Close()
+	var outputs []string
+	for log := range logs {
+		outputs = append(outputs, log.Output)
+	}
+	require.Contains(t, outputs, "allocate ip: 10.0.0.7")
+	require.Contains(t, outputs, "register dns")
+
+	t.Run("InvalidURL", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			PreBuildHookURL: ptr.Ref("ftp://ipam.example.com"),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "pre_build_hook_url", apiErr.Validations[0].Field)
+	})
+}
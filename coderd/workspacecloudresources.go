@@ -0,0 +1,96 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace cloud resources
+// @Description Returns the cloud resources reported by the builds of a
+// @Description workspace. Once the workspace is deleted, each resource is
+// @Description checked with the configured cloud resource checker to detect
+// @Description resources that were leaked by the deletion.
+// @ID get-workspace-cloud-resources
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceCloudResource
+// @Router /api/v2/workspaces/{workspace}/cloud-resources [get]
+func (api *API) workspaceCloudResources(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	resources, err := api.Database.GetWorkspaceCloudResourcesByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, slice.List(resources, convertWorkspaceCloudResource))
+}
+
+// @Summary Get leaked cloud resources
+// @Description Returns the cloud resources that still existed at their cloud
+// @Description provider after the workspace that created them was deleted.
+// @ID get-leaked-cloud-resources
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Success 200 {array} codersdk.LeakedCloudResource
+// @Router /api/v2/workspaces/leaked-cloud-resources [get]
+func (api *API) leakedCloudResources(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rows, err := api.Database.GetLeakedWorkspaceCloudResources(ctx)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resources := make([]codersdk.LeakedCloudResource, 0, len(rows))
+	for _, row := range rows {
+		resources = append(resources, codersdk.LeakedCloudResource{
+			Resource:       convertWorkspaceCloudResource(row.WorkspaceCloudResource),
+			WorkspaceName:  row.WorkspaceName,
+			OwnerID:        row.OwnerID,
+			OwnerName:      row.OwnerUsername,
+			OrganizationID: row.OrganizationID,
+			TemplateID:     row.TemplateID,
+			TemplateName:   row.TemplateName,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resources)
+}
+
+func convertWorkspaceCloudResource(resource database.WorkspaceCloudResource) codersdk.WorkspaceCloudResource {
+	sdkResource := codersdk.WorkspaceCloudResource{
+		WorkspaceID:        resource.WorkspaceID,
+		Type:               resource.ResourceType,
+		Name:               resource.ResourceName,
+		CloudID:            resource.CloudID,
+		WorkspaceBuildID:   resource.WorkspaceBuildID,
+		CreatedAt:          resource.CreatedAt,
+		UpdatedAt:          resource.UpdatedAt,
+		VerificationStatus: codersdk.CloudResourceVerificationStatus(resource.VerificationStatus),
+		VerificationError:  resource.VerificationError,
+	}
+	if resource.VerificationBuildID.Valid {
+		sdkResource.VerificationBuildID = ptr.Ref(resource.VerificationBuildID.UUID)
+	}
+	if resource.VerifiedAt.Valid {
+		sdkResource.VerifiedAt = ptr.Ref(resource.VerifiedAt.Time)
+	}
+	return sdkResource
+}
@@ -0,0 +1,68 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceCloudResources(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionGraph: []*proto.Response{{
+			Type: &proto.Response_Graph{
+				Graph: &proto.GraphComplete{
+					Resources: []*proto.Resource{{
+						Name:    "dev",
+						Type:    "aws_instance",
+						CloudId: "i-0123456789abcdef0",
+					}, {
+						// Resources without a cloud ID are not recorded.
+						Name: "config",
+						Type: "null_resource",
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	resources, err := member.WorkspaceCloudResources(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	require.Equal(t, workspace.ID, resources[0].WorkspaceID)
+	require.Equal(t, "aws_instance", resources[0].Type)
+	require.Equal(t, "dev", resources[0].Name)
+	require.Equal(t, "i-0123456789abcdef0", resources[0].CloudID)
+	require.Equal(t, workspace.LatestBuild.ID, resources[0].WorkspaceBuildID)
+	require.Equal(t, codersdk.CloudResourceVerificationStatusUnverified, resources[0].VerificationStatus)
+	require.Nil(t, resources[0].VerificationBuildID)
+	require.Nil(t, resources[0].VerifiedAt)
+
+	leaked, err := client.LeakedCloudResources(ctx)
+	require.NoError(t, err)
+	require.Empty(t, leaked)
+
+	// Only users that can read all workspaces can list leaked resources.
+	_, err = member.LeakedCloudResources(ctx)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
@@ -0,0 +1,105 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace cost
+// @ID get-workspace-cost
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceCost
+// @Router /api/v2/workspaces/{workspace}/cost [get]
+func (api *API) workspaceCost(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	month := costMonth(api.Clock.Now())
+	cost, err := api.Database.GetWorkspaceMonthlyCost(ctx, database.GetWorkspaceMonthlyCostParams{
+		WorkspaceID: workspace.ID,
+		Month:       month,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing accrued yet this month.
+		cost = database.WorkspaceMonthlyCost{
+			WorkspaceID: workspace.ID,
+			Month:       month,
+			Source:      database.WorkspaceCostSourceEstimate,
+		}
+		err = nil
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceCost(cost))
+}
+
+// @Summary Import workspace cost
+// @ID import-workspace-cost
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.PutWorkspaceCostRequest true "Workspace cost request"
+// @Success 200 {object} codersdk.WorkspaceCost
+// @Router /api/v2/workspaces/{workspace}/cost [put]
+func (api *API) putWorkspaceCost(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.PutWorkspaceCostRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	cost, err := api.Database.UpsertWorkspaceMonthlyCost(ctx, database.UpsertWorkspaceMonthlyCostParams{
+		WorkspaceID: workspace.ID,
+		Month:       costMonth(req.Month),
+		Cost:        req.Cost,
+		Now:         dbtime.Time(api.Clock.Now()),
+	})
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Only deployment administrators can import workspace costs.",
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceCost(cost))
+}
+
+// costMonth returns the start of the UTC calendar month containing t, which
+// is the period workspace costs are accrued and budgeted in.
+func costMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func convertWorkspaceCost(cost database.WorkspaceMonthlyCost) codersdk.WorkspaceCost {
+	return codersdk.WorkspaceCost{
+		WorkspaceID: cost.WorkspaceID,
+		Month:       cost.Month,
+		Cost:        cost.Cost,
+		Source:      codersdk.WorkspaceCostSource(cost.Source),
+		UpdatedAt:   cost.UpdatedAt,
+	}
+}
@@ -0,0 +1,59 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceCost(t *testing.T) {
+	t.Parallel()
+
+	ownerClient, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Nothing has been accrued or imported yet.
+	cost, err := client.WorkspaceCost(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, r.Workspace.ID, cost.WorkspaceID)
+	require.Zero(t, cost.Cost)
+	require.Equal(t, codersdk.WorkspaceCostSourceEstimate, cost.Source)
+
+	req := codersdk.PutWorkspaceCostRequest{
+		Month: time.Now(),
+		Cost:  42.5,
+	}
+
+	// Workspace owners cannot import the cost of their own workspaces.
+	_, err = client.PutWorkspaceCost(ctx, r.Workspace.ID, req)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	imported, err := ownerClient.PutWorkspaceCost(ctx, r.Workspace.ID, req)
+	require.NoError(t, err)
+	require.Equal(t, req.Cost, imported.Cost)
+	require.Equal(t, codersdk.WorkspaceCostSourceBillingExport, imported.Source)
+	require.Equal(t, 1, imported.Month.Day())
+
+	// The workspace owner can see the imported cost.
+	cost, err = client.WorkspaceCost(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, req.Cost, cost.Cost)
+	require.Equal(t, codersdk.WorkspaceCostSourceBillingExport, cost.Source)
+}
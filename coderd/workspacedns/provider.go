@@ -0,0 +1,93 @@
+package workspacedns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/util/xio"
+)
+
+// Record is a DNS name registered for a workspace.
+type Record struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	// Name is the fully qualified DNS name, e.g.
+	// "dev--alice.apps.example.com".
+	Name string `json:"name"`
+	// Target is the host the name should resolve to, usually the host of the
+	// deployment's access URL.
+	Target string `json:"target"`
+}
+
+// Provider creates and removes DNS records. Implementations must be
+// idempotent: registering an existing record or deregistering a missing one
+// is not an error.
+type Provider interface {
+	Register(ctx context.Context, record Record) error
+	Deregister(ctx context.Context, record Record) error
+}
+
+// Action is the operation a WebhookProvider asks the webhook to perform.
+type Action string
+
+const (
+	ActionRegister   Action = "register"
+	ActionDeregister Action = "deregister"
+)
+
+// WebhookRequest is the body POSTed to the DNS provider webhook.
+type WebhookRequest struct {
+	Action Action `json:"action"`
+	Record
+}
+
+// WebhookProvider is a Provider that delegates every change to a webhook, so
+// any DNS service can be integrated without changes to Coder.
+type WebhookProvider struct {
+	client *http.Client
+	url    string
+}
+
+var _ Provider = (*WebhookProvider)(nil)
+
+// NewWebhookProvider returns a Provider that POSTs a WebhookRequest to url for
+// every registered and deregistered record. Any 2xx response is a success.
+func NewWebhookProvider(client *http.Client, url string) *WebhookProvider {
+	return &WebhookProvider{
+		client: client,
+		url:    url,
+	}
+}
+
+func (p *WebhookProvider) Register(ctx context.Context, record Record) error {
+	return p.send(ctx, WebhookRequest{Action: ActionRegister, Record: record})
+}
+
+func (p *WebhookProvider) Deregister(ctx context.Context, record Record) error {
+	return p.send(ctx, WebhookRequest{Action: ActionDeregister, Record: record})
+}
+
+func (p *WebhookProvider) send(ctx context.Context, req WebhookRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return xerrors.Errorf("marshal dns request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create dns request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	res, err := p.client.Do(httpReq)
+	if err != nil {
+		return xerrors.Errorf("send dns request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return xerrors.Errorf("dns provider responded with status %d: %s", res.StatusCode, xio.ReadErrorBody(res.Body))
+	}
+	return nil
+}
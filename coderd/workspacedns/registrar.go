@@ -0,0 +1,210 @@
+// Package workspacedns registers a stable DNS name for every running
+// workspace with a pluggable DNS provider, and removes it again once the
+// workspace stops or is deleted.
+//
+// Names have the form "<workspace>--<owner>.<domain>". The registrar
+// periodically compares running workspaces against the records it
+// registered before, so missed or failed changes are retried on the next
+// tick.
+package workspacedns
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+const (
+	// PollInterval is how often the registrar reconciles DNS records.
+	PollInterval = 15 * time.Second
+
+	// ProviderTimeout bounds a single call to the DNS provider.
+	ProviderTimeout = 30 * time.Second
+
+	// MaxChangesPerRun is the maximum number of workspaces whose record is
+	// changed in a single run.
+	MaxChangesPerRun = 100
+)
+
+// Registrar keeps the DNS records of the provider in sync with the running
+// workspaces on every tick from its channel.
+type Registrar struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db       database.Store
+	log      slog.Logger
+	provider Provider
+	domain   string
+	target   string
+	tick     <-chan time.Time
+	stats    chan<- Stats
+}
+
+// Stats contains statistics about the last run of the registrar.
+type Stats struct {
+	// RegisteredWorkspaceIDs contains the IDs of all workspaces whose record
+	// was registered or updated.
+	RegisteredWorkspaceIDs []uuid.UUID
+	// DeregisteredWorkspaceIDs contains the IDs of all workspaces whose
+	// record was removed.
+	DeregisteredWorkspaceIDs []uuid.UUID
+	// Error is set if the pending record changes could not be loaded or a
+	// record could not be written, which stops the run.
+	Error error
+}
+
+// New returns a new registrar that registers names under domain pointing at
+// target with provider.
+func New(ctx context.Context, db database.Store, log slog.Logger, provider Provider, domain, target string, tick <-chan time.Time) *Registrar {
+	//nolint:gocritic // The registrar manages DNS records of all workspaces.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Registrar{
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		db:       db,
+		log:      log,
+		provider: provider,
+		domain:   domain,
+		target:   target,
+		tick:     tick,
+		stats:    nil,
+	}
+}
+
+// WithStatsChannel will cause Registrar to push a Stats to ch after every
+// tick. This push is blocking, so if ch is not read, the registrar will hang.
+// This should only be used in tests.
+func (r *Registrar) WithStatsChannel(ch chan<- Stats) *Registrar {
+	r.stats = ch
+	return r
+}
+
+// Start will cause the registrar to reconcile DNS records on every tick from
+// its channel. It will stop when its context is Done, or when its channel is
+// closed.
+//
+// Start should only be called once.
+func (r *Registrar) Start() {
+	go func() {
+		defer close(r.done)
+		defer r.cancel()
+
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case t, ok := <-r.tick:
+				if !ok {
+					return
+				}
+				stats := r.run(t)
+				if stats.Error != nil {
+					r.log.Warn(r.ctx, "error running workspace dns registrar once", slog.Error(stats.Error))
+				}
+				if r.stats != nil {
+					select {
+					case <-r.ctx.Done():
+						return
+					case r.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the registrar.
+func (r *Registrar) Close() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *Registrar) run(t time.Time) Stats {
+	stats := Stats{
+		RegisteredWorkspaceIDs:   []uuid.UUID{},
+		DeregisteredWorkspaceIDs: []uuid.UUID{},
+	}
+
+	changes, err := r.db.GetWorkspaceDNSRecordChanges(r.ctx, database.GetWorkspaceDNSRecordChangesParams{
+		Domain:     r.domain,
+		Target:     r.target,
+		MaxChanges: MaxChangesPerRun,
+	})
+	if err != nil {
+		stats.Error = xerrors.Errorf("get workspace dns record changes: %w", err)
+		return stats
+	}
+
+	// Remove stale names before registering new ones, so a name freed by a
+	// deleted workspace can be taken over by a new workspace in the same run.
+	for _, change := range changes {
+		if change.RegisteredName == "" || change.RegisteredName == change.DesiredName {
+			continue
+		}
+		log := r.log.With(slog.F("workspace_id", change.WorkspaceID), slog.F("name", change.RegisteredName))
+		err := r.call(r.provider.Deregister, Record{
+			WorkspaceID: change.WorkspaceID,
+			Name:        change.RegisteredName,
+			Target:      change.RegisteredTarget,
+		})
+		if err != nil {
+			log.Warn(r.ctx, "deregister workspace dns record", slog.Error(err))
+			continue
+		}
+		if change.DesiredName == "" {
+			err = r.db.DeleteWorkspaceDNSRecordByWorkspaceID(r.ctx, change.WorkspaceID)
+			if err != nil {
+				stats.Error = xerrors.Errorf("delete workspace dns record %s: %w", change.WorkspaceID, err)
+				return stats
+			}
+		}
+		log.Debug(r.ctx, "deregistered workspace dns record")
+		stats.DeregisteredWorkspaceIDs = append(stats.DeregisteredWorkspaceIDs, change.WorkspaceID)
+	}
+
+	for _, change := range changes {
+		if change.DesiredName == "" {
+			continue
+		}
+		log := r.log.With(slog.F("workspace_id", change.WorkspaceID), slog.F("name", change.DesiredName))
+		err := r.call(r.provider.Register, Record{
+			WorkspaceID: change.WorkspaceID,
+			Name:        change.DesiredName,
+			Target:      r.target,
+		})
+		if err != nil {
+			log.Warn(r.ctx, "register workspace dns record", slog.Error(err))
+			continue
+		}
+		_, err = r.db.UpsertWorkspaceDNSRecord(r.ctx, database.UpsertWorkspaceDNSRecordParams{
+			WorkspaceID: change.WorkspaceID,
+			Name:        change.DesiredName,
+			Target:      r.target,
+			Now:         dbtime.Time(t),
+		})
+		if err != nil {
+			stats.Error = xerrors.Errorf("upsert workspace dns record %s: %w", change.WorkspaceID, err)
+			return stats
+		}
+		log.Debug(r.ctx, "registered workspace dns record")
+		stats.RegisteredWorkspaceIDs = append(stats.RegisteredWorkspaceIDs, change.WorkspaceID)
+	}
+
+	return stats
+}
+
+func (r *Registrar) call(fn func(context.Context, Record) error, record Record) error {
+	ctx, cancel := context.WithTimeout(r.ctx, ProviderTimeout)
+	defer cancel()
+	return fn(ctx, record)
+}
@@ -0,0 +1,167 @@
+package workspacedns_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/workspacedns"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestRegistrar(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org     = dbgen.Organization(t, db, database.Organization{})
+		user    = dbgen.User(t, db, database.User{Username: "alice"})
+		running = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			Name:           "dev",
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+		}).Do().Workspace
+		_ = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			Name:           "stopped",
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+		}).Seed(database.WorkspaceBuild{
+			Transition: database.WorkspaceTransitionStop,
+		}).Do()
+		now = dbtime.Now()
+	)
+
+	var (
+		mu       sync.Mutex
+		requests []workspacedns.WebhookRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var req workspacedns.WebhookRequest
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	takeRequests := func() []workspacedns.WebhookRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		reqs := requests
+		requests = nil
+		return reqs
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	authzDB := dbauthz.New(db, rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry()), log, coderdtest.AccessControlStorePointer())
+	provider := workspacedns.NewWebhookProvider(srv.Client(), srv.URL)
+	tickCh := make(chan time.Time)
+	statsCh := make(chan workspacedns.Stats)
+	registrar := workspacedns.New(ctx, authzDB, log, provider, "apps.example.com", "coder.example.com", tickCh).WithStatsChannel(statsCh)
+	registrar.Start()
+	t.Cleanup(registrar.Close)
+
+	// Only the running workspace is registered.
+	tickCh <- now
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{running.ID}, stats.RegisteredWorkspaceIDs)
+	require.Empty(t, stats.DeregisteredWorkspaceIDs)
+	want := workspacedns.Record{
+		WorkspaceID: running.ID,
+		Name:        "dev--alice.apps.example.com",
+		Target:      "coder.example.com",
+	}
+	require.Equal(t, []workspacedns.WebhookRequest{{Action: workspacedns.ActionRegister, Record: want}}, takeRequests())
+	records, err := db.GetWorkspaceDNSRecordsByWorkspaceIDs(ctx, []uuid.UUID{running.ID})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, want.Name, records[0].Name)
+
+	// Registered records are left alone.
+	tickCh <- now.Add(time.Minute)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.RegisteredWorkspaceIDs)
+	require.Empty(t, stats.DeregisteredWorkspaceIDs)
+	require.Empty(t, takeRequests())
+
+	// Stopping the workspace removes its record.
+	dbfake.WorkspaceBuild(t, db, running).Seed(database.WorkspaceBuild{
+		Transition:  database.WorkspaceTransitionStop,
+		BuildNumber: 2,
+	}).Do()
+	tickCh <- now.Add(2 * time.Minute)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.RegisteredWorkspaceIDs)
+	require.Equal(t, []uuid.UUID{running.ID}, stats.DeregisteredWorkspaceIDs)
+	require.Equal(t, []workspacedns.WebhookRequest{{Action: workspacedns.ActionDeregister, Record: want}}, takeRequests())
+	records, err = db.GetWorkspaceDNSRecordsByWorkspaceIDs(ctx, []uuid.UUID{running.ID})
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestRegistrarProviderError(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org = dbgen.Organization(t, db, database.Organization{})
+		ws  = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        dbgen.User(t, db, database.User{}).ID,
+			OrganizationID: org.ID,
+		}).Do().Workspace
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	authzDB := dbauthz.New(db, rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry()), log, coderdtest.AccessControlStorePointer())
+	provider := workspacedns.NewWebhookProvider(srv.Client(), srv.URL)
+	tickCh := make(chan time.Time)
+	statsCh := make(chan workspacedns.Stats)
+	registrar := workspacedns.New(ctx, authzDB, log, provider, "apps.example.com", "coder.example.com", tickCh).WithStatsChannel(statsCh)
+	registrar.Start()
+	t.Cleanup(registrar.Close)
+
+	// Provider failures are not fatal and are retried on the next tick.
+	tickCh <- dbtime.Now()
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.RegisteredWorkspaceIDs)
+	records, err := db.GetWorkspaceDNSRecordsByWorkspaceIDs(ctx, []uuid.UUID{ws.ID})
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
@@ -0,0 +1,139 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace dormancy exemption
+// @ID get-workspace-dormancy-exemption
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceDormancyExemption
+// @Router /api/v2/workspaces/{workspace}/dormancy-exemption [get]
+func (api *API) workspaceDormancyExemption(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	exemption, err := api.Database.GetWorkspaceDormancyExemptionByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+				Message: "Workspace is not exempt from dormancy.",
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceDormancyExemption(exemption))
+}
+
+// @Summary Upsert workspace dormancy exemption
+// @ID upsert-workspace-dormancy-exemption
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.PutWorkspaceDormancyExemptionRequest true "Dormancy exemption request"
+// @Success 200 {object} codersdk.WorkspaceDormancyExemption
+// @Router /api/v2/workspaces/{workspace}/dormancy-exemption [put]
+func (api *API) putWorkspaceDormancyExemption(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	apiKey := httpmw.APIKey(r)
+
+	var req codersdk.PutWorkspaceDormancyExemptionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// Prebuilds are managed by the reconciliation loop and are not subject
+	// to dormancy.
+	if workspace.IsPrebuild() {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "Dormancy exemptions are not supported for prebuilt workspaces.",
+		})
+		return
+	}
+
+	now := api.Clock.Now()
+	if !req.ExpiresAt.After(now) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid dormancy exemption.",
+			Validations: []codersdk.ValidationError{
+				{Field: "expires_at", Detail: "Must be in the future."},
+			},
+		})
+		return
+	}
+
+	exemption, err := api.Database.UpsertWorkspaceDormancyExemption(ctx, database.UpsertWorkspaceDormancyExemptionParams{
+		WorkspaceID: workspace.ID,
+		Reason:      req.Reason,
+		ExpiresAt:   dbtime.Time(req.ExpiresAt),
+		ApprovedBy:  apiKey.UserID,
+		CreatedAt:   dbtime.Time(now),
+	})
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Only template administrators can exempt workspaces from dormancy.",
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceDormancyExemption(exemption))
+}
+
+// @Summary Delete workspace dormancy exemption
+// @ID delete-workspace-dormancy-exemption
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 204
+// @Router /api/v2/workspaces/{workspace}/dormancy-exemption [delete]
+func (api *API) deleteWorkspaceDormancyExemption(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	err := api.Database.DeleteWorkspaceDormancyExemption(ctx, workspace.ID)
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Only template administrators can revoke dormancy exemptions.",
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertWorkspaceDormancyExemption(exemption database.WorkspaceDormancyExemption) codersdk.WorkspaceDormancyExemption {
+	return codersdk.WorkspaceDormancyExemption{
+		WorkspaceID: exemption.WorkspaceID,
+		Reason:      exemption.Reason,
+		ExpiresAt:   exemption.ExpiresAt,
+		ApprovedBy:  exemption.ApprovedBy,
+		CreatedAt:   exemption.CreatedAt,
+		UpdatedAt:   exemption.UpdatedAt,
+	}
+}
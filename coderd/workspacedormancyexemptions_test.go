@@ -0,0 +1,71 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceDormancyExemption(t *testing.T) {
+	t.Parallel()
+
+	ownerClient, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	req := codersdk.PutWorkspaceDormancyExemptionRequest{
+		Reason:    "Long-running research job",
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+
+	// Workspace owners cannot exempt their own workspaces.
+	_, err := client.PutWorkspaceDormancyExemption(ctx, r.Workspace.ID, req)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Expiry must be in the future.
+	_, err = ownerClient.PutWorkspaceDormancyExemption(ctx, r.Workspace.ID, codersdk.PutWorkspaceDormancyExemptionRequest{
+		Reason:    req.Reason,
+		ExpiresAt: time.Now().Add(-time.Hour),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	exemption, err := ownerClient.PutWorkspaceDormancyExemption(ctx, r.Workspace.ID, req)
+	require.NoError(t, err)
+	require.Equal(t, r.Workspace.ID, exemption.WorkspaceID)
+	require.Equal(t, req.Reason, exemption.Reason)
+	require.Equal(t, owner.UserID, exemption.ApprovedBy)
+	require.WithinDuration(t, req.ExpiresAt, exemption.ExpiresAt, time.Second)
+
+	// The workspace owner can see the exemption.
+	got, err := client.WorkspaceDormancyExemption(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, exemption.Reason, got.Reason)
+
+	// Only template administrators may revoke it.
+	err = client.DeleteWorkspaceDormancyExemption(ctx, r.Workspace.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = ownerClient.DeleteWorkspaceDormancyExemption(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+
+	_, err = client.WorkspaceDormancyExemption(ctx, r.Workspace.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
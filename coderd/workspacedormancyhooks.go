@@ -0,0 +1,76 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace dormancy hook
+// @ID get-workspace-dormancy-hook
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceDormancyHook
+// @Router /api/v2/workspaces/{workspace}/dormancy-hook [get]
+func (api *API) workspaceDormancyHook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	hook, err := api.Database.GetWorkspaceDormancyHookByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+				Message: "No dormancy hook has been invoked for this workspace.",
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceDormancyHook(hook))
+}
+
+// dormancyHooksEnabled returns whether a dormancy hook is configured.
+func (api *API) dormancyHooksEnabled() bool {
+	return api.DeploymentValues.WorkspaceDormancyHookURL.String() != ""
+}
+
+// scheduleWorkspaceActivation schedules the activate hook of a dormant
+// workspace, unless one is already pending or running. The workspace stays
+// dormant until the hook runner reports that the hook succeeded.
+func (api *API) scheduleWorkspaceActivation(ctx context.Context, db database.Store, workspace database.Workspace) (database.WorkspaceDormancyHook, error) {
+	hook, err := db.GetWorkspaceDormancyHookByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return database.WorkspaceDormancyHook{}, err
+	}
+	if err == nil && hook.Event == database.WorkspaceDormancyHookEventActivate &&
+		(hook.Status == database.WorkspaceDormancyHookStatusPending || hook.Status == database.WorkspaceDormancyHookStatusRunning) {
+		return hook, nil
+	}
+	return db.UpsertWorkspaceDormancyHook(ctx, database.UpsertWorkspaceDormancyHookParams{
+		WorkspaceID: workspace.ID,
+		Event:       database.WorkspaceDormancyHookEventActivate,
+		Now:         dbtime.Time(api.Clock.Now()),
+	})
+}
+
+func convertWorkspaceDormancyHook(hook database.WorkspaceDormancyHook) codersdk.WorkspaceDormancyHook {
+	return codersdk.WorkspaceDormancyHook{
+		WorkspaceID: hook.WorkspaceID,
+		Event:       codersdk.WorkspaceDormancyHookEvent(hook.Event),
+		Status:      codersdk.WorkspaceDormancyHookStatus(hook.Status),
+		Error:       hook.Error,
+		CreatedAt:   hook.CreatedAt,
+		UpdatedAt:   hook.UpdatedAt,
+	}
+}
@@ -0,0 +1,65 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceDormancyHook(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	require.NoError(t, dv.WorkspaceDormancyHookURL.Set("http://hooks.example.com/dormancy"))
+	ownerClient, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{DeploymentValues: dv})
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).Seed(database.WorkspaceBuild{
+		Transition: database.WorkspaceTransitionStop,
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// No hook has been invoked yet.
+	_, err := client.WorkspaceDormancyHook(ctx, r.Workspace.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	// Marking the workspace dormant schedules the dormant hook.
+	err = client.UpdateWorkspaceDormancy(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceDormancy{Dormant: true})
+	require.NoError(t, err)
+	hook, err := client.WorkspaceDormancyHook(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceDormancyHookEventDormant, hook.Event)
+	require.Equal(t, codersdk.WorkspaceDormancyHookStatusPending, hook.Status)
+
+	// Activating the workspace schedules the activate hook, but the
+	// workspace stays dormant until the hook succeeded.
+	err = client.UpdateWorkspaceDormancy(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceDormancy{Dormant: false})
+	require.NoError(t, err)
+	hook, err = client.WorkspaceDormancyHook(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceDormancyHookEventActivate, hook.Event)
+	require.Equal(t, codersdk.WorkspaceDormancyHookStatusPending, hook.Status)
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.NotNil(t, workspace.DormantAt)
+
+	// Starting the workspace is rejected until then.
+	_, err = client.CreateWorkspaceBuild(ctx, r.Workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStart,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+}
@@ -0,0 +1,62 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Refresh workspace environment
+// @Description Re-resolves the environment variables and user secrets of all
+// @Description agents of the workspace and delivers them to the running agents.
+// @Description Sessions started after the refresh see the new values; existing
+// @Description sessions are left untouched.
+// @ID refresh-workspace-environment
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceRefreshEnvResponse
+// @Router /api/v2/workspaces/{workspace}/refresh-env [post]
+func (api *API) postWorkspaceRefreshEnv(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	if !api.Authorize(r, policy.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	conns, release, ok := api.dialWorkspaceAgents(ctx, rw, workspace, true)
+	if !ok {
+		return
+	}
+	defer release()
+
+	resp := codersdk.WorkspaceRefreshEnvResponse{
+		Agents: make([]codersdk.WorkspaceAgentRefreshEnv, 0, len(conns)),
+	}
+	for agentID, conn := range conns {
+		res, err := conn.RefreshEnv(ctx)
+		if err != nil {
+			if cerr, ok := codersdk.AsError(err); ok {
+				httpapi.Write(ctx, rw, cerr.StatusCode(), cerr.Response)
+				return
+			}
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error refreshing workspace agent environment.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		resp.Agents = append(resp.Agents, codersdk.WorkspaceAgentRefreshEnv{
+			AgentID:              agentID,
+			EnvironmentVariables: res.EnvironmentVariables,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
@@ -0,0 +1,52 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceRefreshEnv(t *testing.T) {
+	t.Parallel()
+
+	var (
+		client, db = coderdtest.NewWithDatabase(t, nil)
+		user       = coderdtest.CreateFirstUser(t, client)
+		r          = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+	)
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.NewWorkspaceAgentWaiter(t, client, r.Workspace.ID).Wait()
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	res, err := client.RefreshWorkspaceEnv(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, res.Agents, 1)
+	require.Equal(t, agentID, res.Agents[0].AgentID)
+	require.NotContains(t, res.Agents[0].EnvironmentVariables, "GITHUB_TOKEN")
+
+	// Secrets created after the agent started are picked up without a
+	// restart.
+	_, err = client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+		Name:    "github-token",
+		Value:   "ghp_rotated",
+		EnvName: "GITHUB_TOKEN",
+	})
+	require.NoError(t, err)
+
+	res, err = client.RefreshWorkspaceEnv(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, res.Agents, 1)
+	require.Contains(t, res.Agents[0].EnvironmentVariables, "GITHUB_TOKEN")
+}
@@ -0,0 +1,154 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// workspaceFields are the JSON field names of codersdk.Workspace that can be
+// selected with the fields query parameter of the workspaces list.
+var workspaceFields = func() map[string]bool {
+	fields := map[string]bool{}
+	typ := reflect.TypeOf(codersdk.Workspace{})
+	for i := range typ.NumField() {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// workspaceFieldsFromBuilds are the fields of codersdk.Workspace that are
+// derived from the latest build, its resources or its apps, or that are
+// loaded alongside them. Sparse responses without them skip loading that data
+// entirely.
+var workspaceFieldsFromBuilds = map[string]bool{
+	"latest_build":                 true,
+	"latest_app_status":            true,
+	"outdated":                     true,
+	"health":                       true,
+	"dns_name":                     true,
+	"deprecation_warnings":         true,
+	"latest_build_failure_summary": true,
+	"assigned_region_id":           true,
+}
+
+// parseWorkspaceFields parses a comma-separated list of workspace fields. An
+// empty list returns nil, which selects all fields.
+func parseWorkspaceFields(raw string) (map[string]bool, []codersdk.ValidationError) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	fields := map[string]bool{}
+	var errs []codersdk.ValidationError
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !workspaceFields[field] {
+			errs = append(errs, codersdk.ValidationError{
+				Field:  "fields",
+				Detail: fmt.Sprintf("%q is not a workspace field", field),
+			})
+			continue
+		}
+		fields[field] = true
+	}
+	return fields, errs
+}
+
+func workspaceFieldsRequireBuilds(fields map[string]bool) bool {
+	for field := range fields {
+		if workspaceFieldsFromBuilds[field] {
+			return true
+		}
+	}
+	return false
+}
+
+// sparseWorkspacesResponse is a codersdk.WorkspacesResponse that only
+// contains the selected fields of every workspace.
+type sparseWorkspacesResponse struct {
+	Workspaces []map[string]json.RawMessage `json:"workspaces"`
+	Count      int                          `json:"count"`
+}
+
+// sparseWorkspaces strips all but the selected fields from workspaces.
+func sparseWorkspaces(workspaces []codersdk.Workspace, fields map[string]bool) ([]map[string]json.RawMessage, error) {
+	sparse := make([]map[string]json.RawMessage, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		raw, err := json.Marshal(workspace)
+		if err != nil {
+			return nil, xerrors.Errorf("marshal workspace: %w", err)
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &all); err != nil {
+			return nil, xerrors.Errorf("unmarshal workspace: %w", err)
+		}
+		selected := make(map[string]json.RawMessage, len(fields))
+		for field := range fields {
+			if value, ok := all[field]; ok {
+				selected[field] = value
+			}
+		}
+		sparse = append(sparse, selected)
+	}
+	return sparse, nil
+}
+
+// convertWorkspacesWithoutBuilds converts workspaces without loading their
+// latest builds. Fields in workspaceFieldsFromBuilds are not populated.
+func (api *API) convertWorkspacesWithoutBuilds(ctx context.Context, requesterID uuid.UUID, workspaces []database.Workspace) ([]codersdk.Workspace, error) {
+	templateIDs := make([]uuid.UUID, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		templateIDs = append(templateIDs, workspace.TemplateID)
+	}
+	templates, err := api.Database.GetTemplatesWithFilter(ctx, database.GetTemplatesWithFilterParams{
+		IDs: templateIDs,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get templates: %w", err)
+	}
+	templateByID := make(map[uuid.UUID]database.Template, len(templates))
+	for _, template := range templates {
+		templateByID[template.ID] = template
+	}
+
+	apiWorkspaces := make([]codersdk.Workspace, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		// Like convertWorkspaces, skip workspaces whose template cannot be
+		// read.
+		template, ok := templateByID[workspace.TemplateID]
+		if !ok {
+			continue
+		}
+		w, err := convertWorkspace(
+			ctx,
+			api.Logger,
+			requesterID,
+			workspace,
+			codersdk.WorkspaceBuild{},
+			template,
+			api.Options.AllowWorkspaceRenames,
+			codersdk.WorkspaceAppStatus{},
+		)
+		if err != nil {
+			return nil, xerrors.Errorf("convert workspace: %w", err)
+		}
+		apiWorkspaces = append(apiWorkspaces, w)
+	}
+	return apiWorkspaces, nil
+}
@@ -0,0 +1,122 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Post workspace heartbeat
+// @Description Heartbeats let IDE plugins and other external integrations keep
+// @Description a workspace alive while it is in use. They are rate limited per
+// @Description user and workspace.
+// @ID post-workspace-heartbeat
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Accept json
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.PostWorkspaceHeartbeatRequest true "Heartbeat request"
+// @Success 204
+// @Router /api/v2/workspaces/{workspace}/heartbeat [post]
+func (api *API) postWorkspaceHeartbeat(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+	if !api.Authorize(r, policy.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.PostWorkspaceHeartbeatRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !slices.Contains(codersdk.AllowedAppNames, req.AppName) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid request",
+			Validations: []codersdk.ValidationError{{
+				Field:  "app_name",
+				Detail: fmt.Sprintf("must be one of %v", codersdk.AllowedAppNames),
+			}},
+		})
+		return
+	}
+	stat, err := workspaceUsageStats(req.AppName)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !httpapi.Is404Error(err) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	agent, ok := heartbeatAgent(agents, req.AgentID)
+	if !ok {
+		detail := "must be the ID of an agent in the latest build of the workspace"
+		if req.AgentID == uuid.Nil {
+			detail = "must be set when the workspace does not have exactly one agent"
+		}
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid request",
+			Validations: []codersdk.ValidationError{{
+				Field:  "agent_id",
+				Detail: detail,
+			}},
+		})
+		return
+	}
+
+	api.Logger.Debug(ctx, "workspace heartbeat",
+		slog.F("workspace_id", workspace.ID),
+		slog.F("agent_id", agent.ID),
+		slog.F("app_name", req.AppName),
+		slog.F("plugin", req.Plugin),
+	)
+
+	api.statsReporter.TrackUsage(workspace.ID)
+
+	if !api.Experiments.Enabled(codersdk.ExperimentWorkspaceUsage) {
+		// Without the experiment, usage reported through the API only bumps
+		// last_used_at, the same as postWorkspaceUsage.
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	err = api.statsReporter.ReportAgentStats(ctx, dbtime.Now(), database.WorkspaceIdentityFromWorkspace(workspace), agent.ID, agent.Name, stat, true)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// heartbeatAgent returns the agent a heartbeat is attributed to. If no agent
+// ID is given, the workspace must have exactly one agent.
+func heartbeatAgent(agents []database.WorkspaceAgent, agentID uuid.UUID) (database.WorkspaceAgent, bool) {
+	if agentID == uuid.Nil {
+		if len(agents) != 1 {
+			return database.WorkspaceAgent{}, false
+		}
+		return agents[0], true
+	}
+	for _, agent := range agents {
+		if agent.ID == agentID {
+			return agent, true
+		}
+	}
+	return database.WorkspaceAgent{}, false
+}
@@ -0,0 +1,205 @@
+package coderd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpapi/httperror"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// importedTerraformState is the subset of the Terraform state file format
+// that is needed to validate imported state.
+type importedTerraformState struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Module string `json:"module"`
+		Mode   string `json:"mode"`
+		Type   string `json:"type"`
+		Name   string `json:"name"`
+	} `json:"resources"`
+}
+
+// Import existing infrastructure as a new workspace.
+//
+// @Summary Import workspace from Terraform state
+// @Description Create a workspace whose first build adopts the resources in
+// @Description a Terraform state file. Every managed resource in the state
+// @Description must be declared by the template version. Only template
+// @Description administrators may import state.
+// @ID import-workspace-from-terraform-state
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param request body codersdk.ImportWorkspaceStateRequest true "Import workspace state request"
+// @Success 201 {object} codersdk.Workspace
+// @Router /api/v2/workspaces/import-state [post]
+func (api *API) postImportWorkspaceState(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		apiKey  = httpmw.APIKey(r)
+		auditor = api.Auditor.Load()
+	)
+
+	var req codersdk.ImportWorkspaceStateRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.OwnerID == uuid.Nil {
+		req.OwnerID = apiKey.UserID
+	}
+
+	template, err := requestTemplate(ctx, req.Workspace, api.Database)
+	if err != nil {
+		httperror.WriteResponseError(ctx, rw, err)
+		return
+	}
+	// Imported state can adopt arbitrary infrastructure, so the same
+	// permission is required as for builds with custom state.
+	if !api.Authorize(r, policy.ActionUpdate, template.RBACObject()) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only template administrators may import workspace state.",
+		})
+		return
+	}
+
+	members, err := api.Database.OrganizationMembers(ctx, database.OrganizationMembersParams{
+		OrganizationID: template.OrganizationID,
+		UserID:         req.OwnerID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace owner.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(members) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The workspace owner must be a member of the template's organization.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "owner_id",
+				Detail: fmt.Sprintf("user %q is not a member of the organization", req.OwnerID),
+			}},
+		})
+		return
+	}
+
+	templateVersionID := req.Workspace.TemplateVersionID
+	if templateVersionID == uuid.Nil {
+		templateVersionID = template.ActiveVersionID
+	}
+	templateVersion, err := api.Database.GetTemplateVersionByID(ctx, templateVersionID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(ctx, templateVersion.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	err = validateImportedState(req.State, resources)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The Terraform state does not match the template version.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "state",
+				Detail: err.Error(),
+			}},
+		})
+		return
+	}
+
+	owner := workspaceOwner{
+		ID:        req.OwnerID,
+		Username:  members[0].Username,
+		AvatarURL: members[0].AvatarURL,
+	}
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+		AdditionalFields: audit.AdditionalFields{
+			WorkspaceOwner: owner.Username,
+		},
+		OrganizationID: template.OrganizationID,
+	})
+	defer commitAudit()
+
+	w, err := createWorkspace(ctx, aReq, apiKey.UserID, api, owner, req.Workspace, &createWorkspaceOptions{
+		remoteAddr: r.RemoteAddr,
+		state:      req.State,
+	})
+	if err != nil {
+		httperror.WriteResponseError(ctx, rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, w)
+}
+
+// validateImportedState checks that every managed resource in the Terraform
+// state is declared by the template version, so that the first build adopts
+// the resources instead of planning to replace or destroy them.
+func validateImportedState(raw []byte, templateResources []database.WorkspaceResource) error {
+	if len(raw) == 0 {
+		return xerrors.New("state is required")
+	}
+	var state importedTerraformState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return xerrors.Errorf("parse state: %w", err)
+	}
+	if state.Version != 4 {
+		return xerrors.Errorf("unsupported state format version %d, expected 4", state.Version)
+	}
+
+	declared := make([]string, 0, len(templateResources))
+	for _, resource := range templateResources {
+		declared = append(declared, importedResourceAddress(resource.ModulePath.String, resource.Type, resource.Name))
+	}
+
+	var adopted int
+	for _, resource := range state.Resources {
+		// Data sources and Coder resources are recreated by every build.
+		if resource.Mode != "managed" || strings.HasPrefix(resource.Type, "coder_") {
+			continue
+		}
+		address := importedResourceAddress(resource.Module, resource.Type, resource.Name)
+		if !slices.Contains(declared, address) {
+			return xerrors.Errorf("resource %q is not declared by the template version", address)
+		}
+		adopted++
+	}
+	if adopted == 0 {
+		return xerrors.New("state does not contain any resources to import")
+	}
+	return nil
+}
+
+func importedResourceAddress(module, resourceType, name string) string {
+	address := resourceType + "." + name
+	if module != "" {
+		address = module + "." + address
+	}
+	return address
+}
@@ -0,0 +1,69 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestImportWorkspaceState(t *testing.T) {
+	t.Parallel()
+
+	client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, echo.WithResources([]*proto.Resource{{
+		Name: "dev",
+		Type: "aws_instance",
+	}}))
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	// Keep the imported build pending so that its state can be inspected.
+	require.NoError(t, closeDaemon.Close())
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	state := []byte(`{"version":4,"resources":[` +
+		`{"mode":"managed","type":"aws_instance","name":"dev"},` +
+		`{"mode":"managed","type":"coder_agent","name":"main"},` +
+		`{"mode":"data","type":"coder_workspace","name":"me"}]}`)
+
+	// Only template administrators may import state.
+	_, err := member.ImportWorkspaceState(ctx, codersdk.ImportWorkspaceStateRequest{
+		Workspace: codersdk.CreateWorkspaceRequest{TemplateID: template.ID, Name: "denied"},
+		State:     state,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Resources that the template does not declare are rejected.
+	_, err = client.ImportWorkspaceState(ctx, codersdk.ImportWorkspaceStateRequest{
+		OwnerID:   memberUser.ID,
+		Workspace: codersdk.CreateWorkspaceRequest{TemplateID: template.ID, Name: "mismatch"},
+		State:     []byte(`{"version":4,"resources":[{"mode":"managed","type":"aws_instance","name":"other"}]}`),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Len(t, apiErr.Validations, 1)
+	require.Equal(t, "state", apiErr.Validations[0].Field)
+
+	workspace, err := client.ImportWorkspaceState(ctx, codersdk.ImportWorkspaceStateRequest{
+		OwnerID:   memberUser.ID,
+		Workspace: codersdk.CreateWorkspaceRequest{TemplateID: template.ID, Name: "imported"},
+		State:     state,
+	})
+	require.NoError(t, err)
+	require.Equal(t, memberUser.ID, workspace.OwnerID)
+	require.EqualValues(t, 1, workspace.LatestBuild.BuildNumber)
+
+	gotState, err := client.WorkspaceBuildState(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, state, gotState)
+}
@@ -0,0 +1,214 @@
+// Package workspacelease tears down ephemeral workspaces whose lease expired.
+//
+// Leases are created by the API for CI jobs and are normally released
+// explicitly once the job finishes. The Reaper guarantees teardown for jobs
+// that never release their lease, e.g. because the runner was killed.
+package workspacelease
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/files"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+)
+
+const (
+	// PollInterval is how often the reaper looks for expired leases.
+	PollInterval = 30 * time.Second
+
+	// MaxLeasesPerRun is the maximum number of leases torn down in a single
+	// run.
+	MaxLeasesPerRun = 50
+)
+
+// Reaper deletes the workspaces of expired leases on every tick from its
+// channel.
+type Reaper struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db                database.Store
+	ps                pubsub.Pubsub
+	fileCache         *files.Cache
+	buildUsageChecker *atomic.Pointer[wsbuilder.UsageChecker]
+	log               slog.Logger
+	tick              <-chan time.Time
+	stats             chan<- Stats
+}
+
+// Stats contains statistics about the last run of the reaper.
+type Stats struct {
+	// ReleasedLeaseIDs contains the IDs of all leases released by the run.
+	ReleasedLeaseIDs []uuid.UUID
+	// Error is set if the expired leases could not be loaded. Leases that
+	// fail to be torn down are logged and retried on the next run.
+	Error error
+}
+
+// New returns a new reaper that tears down the workspaces of expired leases.
+func New(ctx context.Context, db database.Store, ps pubsub.Pubsub, fc *files.Cache, buildUsageChecker *atomic.Pointer[wsbuilder.UsageChecker], log slog.Logger, tick <-chan time.Time) *Reaper {
+	//nolint:gocritic // The reaper tears down leased workspaces of all users.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Reaper{
+		ctx:               ctx,
+		cancel:            cancel,
+		done:              make(chan struct{}),
+		db:                db,
+		ps:                ps,
+		fileCache:         fc,
+		buildUsageChecker: buildUsageChecker,
+		log:               log,
+		tick:              tick,
+		stats:             nil,
+	}
+}
+
+// WithStatsChannel will cause Reaper to push a Stats to ch after every tick.
+// This push is blocking, so if ch is not read, the reaper will hang. This
+// should only be used in tests.
+func (r *Reaper) WithStatsChannel(ch chan<- Stats) *Reaper {
+	r.stats = ch
+	return r
+}
+
+// Start will cause the reaper to tear down expired leases on every tick from
+// its channel. It will stop when its context is Done, or when its channel is
+// closed.
+//
+// Start should only be called once.
+func (r *Reaper) Start() {
+	go func() {
+		defer close(r.done)
+		defer r.cancel()
+
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case t, ok := <-r.tick:
+				if !ok {
+					return
+				}
+				stats := r.run(t)
+				if stats.Error != nil {
+					r.log.Warn(r.ctx, "error reaping workspace leases once", slog.Error(stats.Error))
+				}
+				if r.stats != nil {
+					select {
+					case <-r.ctx.Done():
+						return
+					case r.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the reaper.
+func (r *Reaper) Close() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *Reaper) run(t time.Time) Stats {
+	stats := Stats{
+		ReleasedLeaseIDs: []uuid.UUID{},
+	}
+
+	leases, err := r.db.GetExpiredWorkspaceLeases(r.ctx, database.GetExpiredWorkspaceLeasesParams{
+		Now:       dbtime.Time(t),
+		MaxLeases: MaxLeasesPerRun,
+	})
+	if err != nil {
+		stats.Error = xerrors.Errorf("get expired workspace leases: %w", err)
+		return stats
+	}
+
+	for _, lease := range leases {
+		log := r.log.With(slog.F("lease_id", lease.ID), slog.F("workspace_id", lease.WorkspaceID))
+		released, err := r.release(t, lease)
+		if err != nil {
+			// Leave the lease unreleased so the next run retries it, e.g.
+			// once an in-progress build of the workspace completed.
+			log.Warn(r.ctx, "failed to tear down expired workspace lease", slog.Error(err))
+			continue
+		}
+		if !released {
+			continue
+		}
+		log.Info(r.ctx, "tore down expired workspace lease")
+		stats.ReleasedLeaseIDs = append(stats.ReleasedLeaseIDs, lease.ID)
+	}
+
+	return stats
+}
+
+// release marks the lease as released, revokes its credential and schedules
+// the deletion of its workspace. It returns false if the lease was released
+// concurrently.
+func (r *Reaper) release(t time.Time, lease database.WorkspaceLease) (bool, error) {
+	var job *database.ProvisionerJob
+	err := r.db.InTx(func(tx database.Store) error {
+		_, err := tx.ReleaseWorkspaceLease(r.ctx, database.ReleaseWorkspaceLeaseParams{
+			ID:         lease.ID,
+			ReleasedAt: sql.NullTime{Time: dbtime.Time(t), Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("release lease: %w", err)
+		}
+
+		if lease.CredentialKeyID != "" {
+			err = tx.DeleteAPIKeyByID(r.ctx, lease.CredentialKeyID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("delete lease credential: %w", err)
+			}
+		}
+
+		ws, err := tx.GetWorkspaceByID(r.ctx, lease.WorkspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+		if ws.Deleted {
+			return nil
+		}
+
+		_, job, _, err = wsbuilder.New(ws, database.WorkspaceTransitionDelete, *r.buildUsageChecker.Load()).
+			Reason(database.BuildReasonAutodelete).
+			Build(r.ctx, tx, r.fileCache, nil, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+		if err != nil {
+			return xerrors.Errorf("build workspace with transition delete: %w", err)
+		}
+		return nil
+	}, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if job != nil {
+		err = provisionerjobs.PostJob(r.ps, *job)
+		if err != nil {
+			// Client probably doesn't care about this error, so just log it.
+			r.log.Error(r.ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+		}
+	}
+	return true, nil
+}
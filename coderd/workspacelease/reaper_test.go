@@ -0,0 +1,104 @@
+package workspacelease_test
+
+import (
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/files"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/workspacelease"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestReaper(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	log := testutil.Logger(t)
+
+	var (
+		org  = dbgen.Organization(t, db, database.Organization{})
+		user = dbgen.User(t, db, database.User{})
+		now  = dbtime.Now()
+	)
+	newLease := func(expiresAt time.Time) (database.WorkspaceLease, database.APIKey) {
+		ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+		}).Do().Workspace
+		credential, _ := dbgen.APIKey(t, db, database.APIKey{UserID: user.ID})
+		lease, err := db.InsertWorkspaceLease(dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort)), database.InsertWorkspaceLeaseParams{
+			ID:              uuid.New(),
+			WorkspaceID:     ws.ID,
+			TemplateID:      ws.TemplateID,
+			OwnerID:         user.ID,
+			APIKeyID:        "ci-token",
+			CredentialKeyID: credential.ID,
+			CreatedAt:       now.Add(-time.Hour),
+			ExpiresAt:       expiresAt,
+		})
+		require.NoError(t, err)
+		return lease, credential
+	}
+	expired, expiredCredential := newLease(now.Add(-time.Minute))
+	active, activeCredential := newLease(now.Add(time.Hour))
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	authz := rbac.NewStrictCachingAuthorizer(prometheus.NewRegistry())
+	authzDB := dbauthz.New(db, authz, log, coderdtest.AccessControlStorePointer())
+	var usageChecker atomic.Pointer[wsbuilder.UsageChecker]
+	var noop wsbuilder.UsageChecker = wsbuilder.NoopUsageChecker{}
+	usageChecker.Store(&noop)
+	tickCh := make(chan time.Time)
+	statsCh := make(chan workspacelease.Stats)
+	reaper := workspacelease.New(ctx, authzDB, ps, files.New(prometheus.NewRegistry(), authz), &usageChecker, log, tickCh).WithStatsChannel(statsCh)
+	reaper.Start()
+	t.Cleanup(reaper.Close)
+
+	// Only the expired lease is torn down.
+	tickCh <- now
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{expired.ID}, stats.ReleasedLeaseIDs)
+
+	got, err := db.GetWorkspaceLeaseByID(ctx, expired.ID)
+	require.NoError(t, err)
+	require.True(t, got.ReleasedAt.Valid)
+	build, err := db.GetLatestWorkspaceBuildByWorkspaceID(ctx, expired.WorkspaceID)
+	require.NoError(t, err)
+	require.Equal(t, database.WorkspaceTransitionDelete, build.Transition)
+	require.Equal(t, database.BuildReasonAutodelete, build.Reason)
+	_, err = db.GetAPIKeyByID(ctx, expiredCredential.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	got, err = db.GetWorkspaceLeaseByID(ctx, active.ID)
+	require.NoError(t, err)
+	require.False(t, got.ReleasedAt.Valid)
+	_, err = db.GetAPIKeyByID(ctx, activeCredential.ID)
+	require.NoError(t, err)
+
+	// Released leases are not torn down again.
+	tickCh <- now.Add(time.Minute)
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.ReleasedLeaseIDs)
+}
@@ -0,0 +1,308 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpapi/httperror"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Create workspace lease
+// @Description Creates an ephemeral workspace from the template, or claims a
+// @Description prebuilt workspace of the preset, that is deleted once the
+// @Description lease expires or is released.
+// @ID create-workspace-lease
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceLeaseRequest true "Create workspace lease request"
+// @Success 201 {object} codersdk.CreateWorkspaceLeaseResponse
+// @Router /api/v2/templates/{template}/leases [post]
+func (api *API) postWorkspaceLease(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		apiKey   = httpmw.APIKey(r)
+		template = httpmw.TemplateParam(r)
+		auditor  = api.Auditor.Load()
+	)
+
+	var req codersdk.CreateWorkspaceLeaseRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	maxPerToken := api.DeploymentValues.WorkspaceLeaseMaxPerToken.Value()
+	if maxPerToken <= 0 {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Workspace leases are disabled on this deployment.",
+		})
+		return
+	}
+
+	ttl := time.Duration(req.TTLMillis) * time.Millisecond
+	maxTTL := api.DeploymentValues.WorkspaceLeaseMaxTTL.Value()
+	if ttl <= 0 || (maxTTL > 0 && ttl > maxTTL) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid lease TTL.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "ttl_ms",
+				Detail: fmt.Sprintf("Must be positive and at most %s.", maxTTL),
+			}},
+		})
+		return
+	}
+
+	// The caller may hold a narrowly scoped token that cannot read API keys,
+	// and the cap applies to the token regardless of its scope.
+	//nolint:gocritic // Counting the leases of the caller's own token.
+	active, err := api.Database.CountActiveWorkspaceLeasesByAPIKeyID(dbauthz.AsSystemRestricted(ctx), apiKey.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if active >= maxPerToken {
+		httpapi.Write(ctx, rw, http.StatusTooManyRequests, codersdk.Response{
+			Message: "Too many active workspace leases for this token.",
+			Detail:  fmt.Sprintf("Release one of the %d active leases before creating another.", active),
+		})
+		return
+	}
+
+	user, err := api.Database.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+		AdditionalFields: audit.AdditionalFields{
+			WorkspaceOwner: user.Username,
+		},
+	})
+	defer commitAudit()
+
+	leaseID := uuid.New()
+	name := req.Name
+	if name == "" {
+		name = "lease-" + leaseID.String()[:8]
+	}
+	now := dbtime.Time(api.Clock.Now())
+	expiresAt := now.Add(ttl)
+
+	var (
+		lease        database.WorkspaceLease
+		sessionToken string
+	)
+	workspace, err := createWorkspace(ctx, aReq, apiKey.UserID, api, workspaceOwner{
+		ID:        user.ID,
+		Username:  user.Username,
+		AvatarURL: user.AvatarURL,
+	}, codersdk.CreateWorkspaceRequest{
+		TemplateID:              template.ID,
+		Name:                    name,
+		RichParameterValues:     req.RichParameterValues,
+		TemplateVersionPresetID: req.TemplateVersionPresetID,
+	}, &createWorkspaceOptions{
+		remoteAddr: r.RemoteAddr,
+		postCreateInTX: func(ctx context.Context, tx database.Store, ws database.Workspace) error {
+			// The lease and its credential are bookkeeping for the workspace
+			// the caller was just authorized to create. The credential can
+			// only access this workspace, so it never grants more than the
+			// caller already holds.
+			//nolint:gocritic // See above.
+			sysCtx := dbauthz.AsSystemRestricted(ctx)
+			key, token, err := apikey.Generate(apikey.CreateParams{
+				UserID:          user.ID,
+				LoginType:       database.LoginTypeToken,
+				ExpiresAt:       expiresAt,
+				LifetimeSeconds: int64(ttl.Seconds()),
+				Scopes:          database.APIKeyScopes{database.ApiKeyScopeCoderWorkspacesaccess},
+				TokenName:       "lease-" + leaseID.String(),
+				RemoteAddr:      r.RemoteAddr,
+				AllowList: database.AllowList{
+					{Type: rbac.ResourceWorkspace.Type, ID: ws.ID.String()},
+				},
+			})
+			if err != nil {
+				return xerrors.Errorf("generate lease credential: %w", err)
+			}
+			credential, err := tx.InsertAPIKey(sysCtx, key)
+			if err != nil {
+				return xerrors.Errorf("insert lease credential: %w", err)
+			}
+			lease, err = tx.InsertWorkspaceLease(sysCtx, database.InsertWorkspaceLeaseParams{
+				ID:              leaseID,
+				WorkspaceID:     ws.ID,
+				TemplateID:      ws.TemplateID,
+				OwnerID:         ws.OwnerID,
+				APIKeyID:        apiKey.ID,
+				CredentialKeyID: credential.ID,
+				CreatedAt:       now,
+				ExpiresAt:       expiresAt,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert workspace lease: %w", err)
+			}
+			sessionToken = token
+			return nil
+		},
+	})
+	if err != nil {
+		httperror.WriteResponseError(ctx, rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateWorkspaceLeaseResponse{
+		Lease:        convertWorkspaceLease(lease),
+		Workspace:    workspace,
+		SessionToken: sessionToken,
+	})
+}
+
+// @Summary Get workspace lease
+// @ID get-workspace-lease
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspacelease path string true "Workspace lease ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceLease
+// @Router /api/v2/workspaceleases/{workspacelease} [get]
+func (api *API) workspaceLease(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	leaseID, ok := httpmw.ParseUUIDParam(rw, r, "workspacelease")
+	if !ok {
+		return
+	}
+
+	lease, err := api.Database.GetWorkspaceLeaseByID(ctx, leaseID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceLease(lease))
+}
+
+// @Summary Release workspace lease
+// @Description Releases the lease, revokes its session token and deletes
+// @Description the leased workspace. Releasing a released lease is a no-op.
+// @ID release-workspace-lease
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspacelease path string true "Workspace lease ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceLease
+// @Router /api/v2/workspaceleases/{workspacelease} [delete]
+func (api *API) releaseWorkspaceLease(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	leaseID, ok := httpmw.ParseUUIDParam(rw, r, "workspacelease")
+	if !ok {
+		return
+	}
+
+	lease, err := api.Database.GetWorkspaceLeaseByID(ctx, leaseID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if lease.ReleasedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceLease(lease))
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, lease.WorkspaceID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	if !workspace.Deleted {
+		_, err = api.postWorkspaceBuildsInternal(
+			ctx,
+			apiKey,
+			workspace,
+			codersdk.CreateWorkspaceBuildRequest{
+				Transition: codersdk.WorkspaceTransitionDelete,
+			},
+			func(action policy.Action, object rbac.Objecter) bool {
+				return api.Authorize(r, action, object)
+			},
+			audit.WorkspaceBuildBaggageFromRequest(r),
+		)
+		if err != nil {
+			httperror.WriteWorkspaceBuildError(ctx, rw, err)
+			return
+		}
+	}
+
+	released, err := api.Database.ReleaseWorkspaceLease(ctx, database.ReleaseWorkspaceLeaseParams{
+		ID:         lease.ID,
+		ReleasedAt: sql.NullTime{Time: dbtime.Time(api.Clock.Now()), Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released concurrently, e.g. by the lease reaper.
+		released, err = api.Database.GetWorkspaceLeaseByID(ctx, lease.ID)
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	if lease.CredentialKeyID != "" {
+		//nolint:gocritic // Revoking the credential minted for the lease.
+		err = api.Database.DeleteAPIKeyByID(dbauthz.AsSystemRestricted(ctx), lease.CredentialKeyID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceLease(released))
+}
+
+func convertWorkspaceLease(lease database.WorkspaceLease) codersdk.WorkspaceLease {
+	sdk := codersdk.WorkspaceLease{
+		ID:          lease.ID,
+		WorkspaceID: lease.WorkspaceID,
+		TemplateID:  lease.TemplateID,
+		OwnerID:     lease.OwnerID,
+		CreatedAt:   lease.CreatedAt,
+		ExpiresAt:   lease.ExpiresAt,
+	}
+	if lease.ReleasedAt.Valid {
+		sdk.ReleasedAt = &lease.ReleasedAt.Time
+	}
+	return sdk
+}
@@ -0,0 +1,77 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceLeases(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	require.NoError(t, dv.WorkspaceLeaseMaxPerToken.Set("1"))
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues:         dv,
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// The TTL is bounded by the deployment.
+	_, err := client.CreateWorkspaceLease(ctx, template.ID, codersdk.CreateWorkspaceLeaseRequest{
+		TTLMillis: (48 * time.Hour).Milliseconds(),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	created, err := client.CreateWorkspaceLease(ctx, template.ID, codersdk.CreateWorkspaceLeaseRequest{
+		TTLMillis: time.Hour.Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.SessionToken)
+	require.Equal(t, created.Workspace.ID, created.Lease.WorkspaceID)
+	require.Equal(t, template.ID, created.Lease.TemplateID)
+	require.WithinDuration(t, created.Lease.CreatedAt.Add(time.Hour), created.Lease.ExpiresAt, time.Second)
+	require.Nil(t, created.Lease.ReleasedAt)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, created.Workspace.LatestBuild.ID)
+
+	// The token may only hold one unreleased lease.
+	_, err = client.CreateWorkspaceLease(ctx, template.ID, codersdk.CreateWorkspaceLeaseRequest{
+		TTLMillis: time.Hour.Milliseconds(),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode())
+
+	// Releasing the lease deletes the workspace.
+	released, err := client.ReleaseWorkspaceLease(ctx, created.Lease.ID)
+	require.NoError(t, err)
+	require.NotNil(t, released.ReleasedAt)
+	workspace, err := client.Workspace(ctx, created.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceTransitionDelete, workspace.LatestBuild.Transition)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	// Releasing again is a no-op.
+	again, err := client.ReleaseWorkspaceLease(ctx, created.Lease.ID)
+	require.NoError(t, err)
+	require.Equal(t, released.ReleasedAt, again.ReleasedAt)
+
+	// The released lease no longer counts towards the cap.
+	next, err := client.CreateWorkspaceLease(ctx, template.ID, codersdk.CreateWorkspaceLeaseRequest{
+		TTLMillis: time.Hour.Milliseconds(),
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, next.Workspace.LatestBuild.ID)
+}
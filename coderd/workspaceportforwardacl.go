@@ -0,0 +1,252 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+
+	"github.com/coder/coder/v2/apiversion"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxPortForwardACLPorts caps the number of ports a single user may be
+// allowed to connect to.
+const maxPortForwardACLPorts = 64
+
+// allowedPortsAPIVersion is the first Agent API version of agents that enforce
+// the allowed ports of clients.
+var allowedPortsAPIVersion = apiversion.New(2, 11)
+
+// @Summary Get workspace port-forward ACL
+// @ID get-workspace-port-forward-acl
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspacePortForwardACL
+// @Router /api/v2/workspaces/{workspace}/port-forward-acl [get]
+func (api *API) workspacePortForwardACL(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	acls, err := api.Database.GetWorkspacePortForwardACLsByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(acls))
+	for _, acl := range acls {
+		userIDs = append(userIDs, acl.UserID)
+	}
+	// Users are returned as MinimalUser, which contains no PII, so it is safe
+	// to fetch them under the System context like the workspace ACL does.
+	// nolint:gocritic
+	dbUsers, err := api.Database.GetUsersByIDs(dbauthz.AsSystemRestricted(ctx), userIDs)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	usersByID := make(map[uuid.UUID]database.User, len(dbUsers))
+	for _, user := range dbUsers {
+		usersByID[user.ID] = user
+	}
+
+	users := make([]codersdk.WorkspacePortForwardACLUser, 0, len(acls))
+	for _, acl := range acls {
+		user, ok := usersByID[acl.UserID]
+		if !ok {
+			continue
+		}
+		users = append(users, codersdk.WorkspacePortForwardACLUser{
+			MinimalUser: db2sdk.MinimalUser(user),
+			Ports:       acl.Ports,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspacePortForwardACL{
+		Users: users,
+	})
+}
+
+// @Summary Update workspace port-forward ACL
+// @ID update-workspace-port-forward-acl
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspacePortForwardACL true "Update workspace port-forward ACL request"
+// @Success 204
+// @Router /api/v2/workspaces/{workspace}/port-forward-acl [patch]
+func (api *API) patchWorkspacePortForwardACL(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	if !api.allowWorkspaceSharing(ctx, rw, workspace.OrganizationID) {
+		return
+	}
+
+	var req codersdk.UpdateWorkspacePortForwardACL
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	userPorts := make(map[uuid.UUID][]int32, len(req.UserPorts))
+	var validations []codersdk.ValidationError
+	for userIDStr, ports := range req.UserPorts {
+		field := fmt.Sprintf("user_ports[%s]", userIDStr)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			validations = append(validations, codersdk.ValidationError{Field: field, Detail: "Invalid user ID."})
+			continue
+		}
+		if _, ok := workspace.UserACL[userID.String()]; !ok && len(ports) > 0 {
+			validations = append(validations, codersdk.ValidationError{Field: field, Detail: "The workspace is not shared with the user."})
+			continue
+		}
+		if len(ports) > maxPortForwardACLPorts {
+			validations = append(validations, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("At most %d ports are allowed.", maxPortForwardACLPorts)})
+			continue
+		}
+		for _, port := range ports {
+			if port < 1 || port > 65535 {
+				validations = append(validations, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Port %d must be between 1 and 65535.", port)})
+			}
+		}
+		ports = slices.Clone(ports)
+		slices.Sort(ports)
+		userPorts[userID] = slices.Compact(ports)
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update workspace port-forward ACL.",
+			Validations: validations,
+		})
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		now := dbtime.Now()
+		for userID, ports := range userPorts {
+			if len(ports) == 0 {
+				err := tx.DeleteWorkspacePortForwardACL(ctx, database.DeleteWorkspacePortForwardACLParams{
+					WorkspaceID: workspace.ID,
+					UserID:      userID,
+				})
+				if err != nil {
+					return xerrors.Errorf("delete port-forward ACL of user %s: %w", userID, err)
+				}
+				continue
+			}
+			_, err := tx.UpsertWorkspacePortForwardACL(ctx, database.UpsertWorkspacePortForwardACLParams{
+				WorkspaceID: workspace.ID,
+				UserID:      userID,
+				Ports:       ports,
+				CreatedAt:   now,
+			})
+			if err != nil {
+				return xerrors.Errorf("upsert port-forward ACL of user %s: %w", userID, err)
+			}
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// workspacePortForwardRestriction returns the ports of the workspace the user
+// may connect to. ok is false if the user is not restricted to some ports.
+func workspacePortForwardRestriction(ctx context.Context, db database.Store, workspaceID, userID uuid.UUID) (ports []uint16, ok bool, err error) {
+	// nolint:gocritic // The restrictions of the user are needed to authorize
+	// their connections, which the user may not be able to read.
+	acl, err := db.GetWorkspacePortForwardACL(dbauthz.AsSystemRestricted(ctx), database.GetWorkspacePortForwardACLParams{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, xerrors.Errorf("get workspace port-forward ACL: %w", err)
+	}
+	ports = make([]uint16, 0, len(acl.Ports))
+	for _, port := range acl.Ports {
+		// #nosec G115 - Safe conversion as ports are validated to be between 1 and 65535.
+		ports = append(ports, uint16(port))
+	}
+	return ports, true, nil
+}
+
+// agentEnforcesAllowedPorts returns whether an agent connected with the given
+// API version enforces the allowed ports of clients.
+func agentEnforcesAllowedPorts(agentAPIVersion string) bool {
+	major, minor, err := apiversion.Parse(agentAPIVersion)
+	if err != nil {
+		return false
+	}
+	return apiversion.New(major, minor).Validate(allowedPortsAPIVersion.String()) == nil
+}
+
+// logPortForwardSession records a connection log entry for a coordination
+// session of a user restricted to some ports of the workspace. The peer ID
+// identifies the session, so the disconnect updates the same entry.
+func (api *API) logPortForwardSession(ctx context.Context, r *http.Request, waws database.GetWorkspaceAgentAndWorkspaceByIDRow, userID, peerID uuid.UUID, ports []uint16, status database.ConnectionStatus) {
+	portStrs := make([]string, 0, len(ports))
+	for _, port := range ports {
+		portStrs = append(portStrs, strconv.Itoa(int(port)))
+	}
+	slugOrPort := strings.Join(portStrs, ",")
+	userAgent := r.UserAgent()
+
+	connLogger := *api.ConnectionLogger.Load()
+	err := connLogger.Upsert(ctx, database.UpsertConnectionLogParams{
+		ID:               uuid.New(),
+		Time:             dbtime.Now(),
+		OrganizationID:   waws.WorkspaceTable.OrganizationID,
+		WorkspaceOwnerID: waws.WorkspaceTable.OwnerID,
+		WorkspaceID:      waws.WorkspaceTable.ID,
+		WorkspaceName:    waws.WorkspaceTable.Name,
+		AgentName:        waws.WorkspaceAgent.Name,
+		Type:             database.ConnectionTypePortForwarding,
+		IP:               database.ParseIP(r.RemoteAddr),
+		UserAgent:        sql.NullString{Valid: userAgent != "", String: userAgent},
+		UserID:           uuid.NullUUID{UUID: userID, Valid: true},
+		SlugOrPort:       sql.NullString{Valid: slugOrPort != "", String: slugOrPort},
+		ConnectionStatus: status,
+		ConnectionID:     uuid.NullUUID{UUID: peerID, Valid: true},
+
+		// N/A
+		Code:             sql.NullInt32{},
+		DisconnectReason: sql.NullString{},
+	})
+	if err != nil {
+		api.Logger.Error(ctx, "upsert port-forward connection log failed",
+			slog.F("workspace_id", waws.WorkspaceTable.ID),
+			slog.F("user_id", userID),
+			slog.Error(err),
+		)
+	}
+}
@@ -0,0 +1,183 @@
+package coderd_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspacePortForwardACL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		adminClient, db := coderdtest.NewWithDatabase(t, nil)
+		adminUser := coderdtest.CreateFirstUser(t, adminClient)
+		client, owner := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+		_, friend := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: adminUser.OrganizationID,
+			OwnerID:        owner.ID,
+		}).Do()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		err := client.UpdateWorkspaceACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				friend.ID.String(): codersdk.WorkspaceRoleUse,
+			},
+		})
+		require.NoError(t, err)
+
+		err = client.UpdateWorkspacePortForwardACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspacePortForwardACL{
+			UserPorts: map[string][]int32{
+				friend.ID.String(): {8080, 3000, 8080},
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := client.WorkspacePortForwardACL(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, friend.ID, acl.Users[0].ID)
+		require.Equal(t, []int32{3000, 8080}, acl.Users[0].Ports)
+
+		// An empty list allows the user to connect to any port again.
+		err = client.UpdateWorkspacePortForwardACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspacePortForwardACL{
+			UserPorts: map[string][]int32{
+				friend.ID.String(): {},
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err = client.WorkspacePortForwardACL(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, acl.Users)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+
+		adminClient, db := coderdtest.NewWithDatabase(t, nil)
+		adminUser := coderdtest.CreateFirstUser(t, adminClient)
+		client, owner := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+		_, friend := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+		_, stranger := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: adminUser.OrganizationID,
+			OwnerID:        owner.ID,
+		}).Do()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		err := client.UpdateWorkspaceACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				friend.ID.String(): codersdk.WorkspaceRoleUse,
+			},
+		})
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			name  string
+			user  string
+			ports []int32
+		}{
+			{name: "InvalidUserID", user: "not-a-uuid", ports: []int32{8080}},
+			{name: "NotShared", user: stranger.ID.String(), ports: []int32{8080}},
+			{name: "PortTooLow", user: friend.ID.String(), ports: []int32{0}},
+			{name: "PortTooHigh", user: friend.ID.String(), ports: []int32{65536}},
+		} {
+			err := client.UpdateWorkspacePortForwardACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspacePortForwardACL{
+				UserPorts: map[string][]int32{tc.user: tc.ports},
+			})
+			cerr, ok := codersdk.AsError(err)
+			require.True(t, ok, tc.name)
+			require.Equal(t, http.StatusBadRequest, cerr.StatusCode(), tc.name)
+			require.Len(t, cerr.Validations, 1, tc.name)
+			require.Equal(t, fmt.Sprintf("user_ports[%s]", tc.user), cerr.Validations[0].Field, tc.name)
+		}
+	})
+
+	t.Run("RemovedFromWorkspaceACL", func(t *testing.T) {
+		t.Parallel()
+
+		adminClient, db := coderdtest.NewWithDatabase(t, nil)
+		adminUser := coderdtest.CreateFirstUser(t, adminClient)
+		client, owner := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+		_, friend := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: adminUser.OrganizationID,
+			OwnerID:        owner.ID,
+		}).Do()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		err := client.UpdateWorkspaceACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				friend.ID.String(): codersdk.WorkspaceRoleUse,
+			},
+		})
+		require.NoError(t, err)
+		err = client.UpdateWorkspacePortForwardACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspacePortForwardACL{
+			UserPorts: map[string][]int32{
+				friend.ID.String(): {8080},
+			},
+		})
+		require.NoError(t, err)
+
+		err = client.UpdateWorkspaceACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				friend.ID.String(): codersdk.WorkspaceRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := client.WorkspacePortForwardACL(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, acl.Users)
+	})
+
+	t.Run("OutdatedAgent", func(t *testing.T) {
+		t.Parallel()
+
+		adminClient, db := coderdtest.NewWithDatabase(t, nil)
+		adminUser := coderdtest.CreateFirstUser(t, adminClient)
+		client, owner := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+		friendClient, friend := coderdtest.CreateAnotherUser(t, adminClient, adminUser.OrganizationID)
+
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: adminUser.OrganizationID,
+			OwnerID:        owner.ID,
+		}).WithAgent().Do()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		err := client.UpdateWorkspaceACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				friend.ID.String(): codersdk.WorkspaceRoleUse,
+			},
+		})
+		require.NoError(t, err)
+		err = client.UpdateWorkspacePortForwardACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspacePortForwardACL{
+			UserPorts: map[string][]int32{
+				friend.ID.String(): {8080},
+			},
+		})
+		require.NoError(t, err)
+
+		// The agent never connected, so it cannot enforce the allowed ports
+		// and restricted users may not connect to it.
+		res, err := friendClient.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", r.Agents[0].ID), nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+}
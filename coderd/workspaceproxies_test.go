@@ -2,6 +2,7 @@ package coderd_test
 
 import (
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/google/uuid"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -66,3 +68,94 @@ func TestRegions(t *testing.T) {
 		require.Empty(t, regions)
 	})
 }
+
+func TestRegionRecommendation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Primary", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		regions, err := client.Regions(ctx)
+		require.NoError(t, err)
+		require.Len(t, regions, 1)
+
+		recommendation, err := client.RegionRecommendation(ctx, codersdk.RegionRecommendationRequest{
+			ClientLatenciesMS: map[uuid.UUID]int64{regions[0].ID: 42},
+		})
+		require.NoError(t, err)
+		require.Equal(t, regions[0], recommendation.Recommended)
+		require.Len(t, recommendation.Candidates, 1)
+		require.EqualValues(t, 42, *recommendation.Candidates[0].ClientLatencyMS)
+		require.Nil(t, recommendation.Candidates[0].WorkspaceLatencyMS)
+	})
+
+	t.Run("InvalidLatency", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/regions/recommendation?latency=primary:fast", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, codersdk.ReadBodyAsError(res), &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "latency", apiErr.Validations[0].Field)
+	})
+
+	t.Run("WorkspaceNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.RegionRecommendation(ctx, codersdk.RegionRecommendationRequest{
+			WorkspaceID: uuid.New(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("AutoAssign", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		regions, err := client.Regions(ctx)
+		require.NoError(t, err)
+
+		// Workspaces are not assigned a region by default.
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		require.Nil(t, workspace.AssignedRegionID)
+
+		template = coderdtest.UpdateTemplateMeta(t, client, template.ID, codersdk.UpdateTemplateMeta{
+			AutoAssignRegion: ptr.Ref(true),
+		})
+		require.True(t, template.AutoAssignRegion)
+
+		workspace = coderdtest.CreateWorkspace(t, client, template.ID, func(req *codersdk.CreateWorkspaceRequest) {
+			req.RegionLatenciesMS = map[uuid.UUID]int64{regions[0].ID: 42}
+		})
+		require.NotNil(t, workspace.AssignedRegionID)
+		require.Equal(t, regions[0].ID, *workspace.AssignedRegionID)
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.NotNil(t, workspace.AssignedRegionID)
+		require.Equal(t, regions[0].ID, *workspace.AssignedRegionID)
+	})
+}
@@ -0,0 +1,205 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// @Summary Quiesce workspace
+// @Description Flushes filesystems and runs the quiesce scripts of all agents
+// @Description of the workspace, returning once it is safe to back up. The
+// @Description agents resume automatically once the timeout elapsed.
+// @ID quiesce-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.QuiesceWorkspaceRequest true "Quiesce workspace request"
+// @Success 200 {object} codersdk.QuiesceWorkspaceResponse
+// @Router /api/v2/workspaces/{workspace}/quiesce [post]
+func (api *API) postWorkspaceQuiesce(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	if !api.Authorize(r, policy.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.QuiesceWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	conns, release, ok := api.dialWorkspaceAgents(ctx, rw, workspace, false)
+	if !ok {
+		return
+	}
+	defer release()
+
+	resp := codersdk.QuiesceWorkspaceResponse{
+		AgentIDs: make([]uuid.UUID, 0, len(conns)),
+	}
+	for agentID, conn := range conns {
+		res, err := conn.Quiesce(ctx, workspacesdk.QuiesceRequest{
+			TimeoutSeconds: req.TimeoutSeconds,
+		})
+		if err != nil {
+			// Don't leave part of the workspace paused.
+			for _, id := range resp.AgentIDs {
+				if rerr := conns[id].Resume(ctx); rerr != nil {
+					api.Logger.Warn(ctx, "resume agent after failed quiesce", slog.F("agent_id", id), slog.Error(rerr))
+				}
+			}
+			if cerr, ok := codersdk.AsError(err); ok {
+				httpapi.Write(ctx, rw, cerr.StatusCode(), cerr.Response)
+				return
+			}
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error quiescing workspace agent.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		resp.AgentIDs = append(resp.AgentIDs, agentID)
+		if resp.ResumesAt.IsZero() || res.ResumesAt.Before(resp.ResumesAt) {
+			resp.ResumesAt = res.ResumesAt
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Resume quiesced workspace
+// @ID resume-quiesced-workspace
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 204
+// @Router /api/v2/workspaces/{workspace}/quiesce [delete]
+func (api *API) deleteWorkspaceQuiesce(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	if !api.Authorize(r, policy.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	conns, release, ok := api.dialWorkspaceAgents(ctx, rw, workspace, false)
+	if !ok {
+		return
+	}
+	defer release()
+
+	for _, conn := range conns {
+		if err := conn.Resume(ctx); err != nil {
+			if cerr, ok := codersdk.AsError(err); ok {
+				httpapi.Write(ctx, rw, cerr.StatusCode(), cerr.Response)
+				return
+			}
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error resuming workspace agent.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// dialWorkspaceAgents dials the agents of the latest build of the workspace.
+// Dev container sub-agents are skipped unless subAgents is set; they share
+// their parent's volumes, so quiescing the parent covers them. All agents
+// must be connected, since a partially quiesced workspace is not safe to
+// back up.
+func (api *API) dialWorkspaceAgents(ctx context.Context, rw http.ResponseWriter, workspace database.Workspace, subAgents bool) (map[uuid.UUID]workspacesdk.AgentConn, func(), bool) {
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+
+	var (
+		conns    = make(map[uuid.UUID]workspacesdk.AgentConn)
+		releases []func()
+	)
+	release := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+	for _, agent := range agents {
+		if agent.ParentID.Valid && !subAgents {
+			continue
+		}
+
+		apiAgent, err := db2sdk.WorkspaceAgent(
+			api.DERPMap(),
+			*api.TailnetCoordinator.Load(),
+			agent,
+			nil,
+			nil,
+			nil,
+			api.AgentInactiveDisconnectTimeout,
+			api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+		)
+		if err != nil {
+			release()
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error reading workspace agent.",
+				Detail:  err.Error(),
+			})
+			return nil, nil, false
+		}
+		if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+			release()
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Agent %q state is %q, it must be in the %q state.", agent.Name, apiAgent.Status, codersdk.WorkspaceAgentConnected),
+			})
+			return nil, nil, false
+		}
+
+		// If the agent is unreachable, the request will hang. Assume that if
+		// we don't get a response after 30s that the agent is unreachable.
+		dialCtx, dialCancel := context.WithTimeout(ctx, 30*time.Second)
+		releases = append(releases, dialCancel)
+		conn, connRelease, err := api.agentProvider.AgentConn(dialCtx, agent.ID)
+		if err != nil {
+			release()
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: fmt.Sprintf("Internal error dialing workspace agent %q.", agent.Name),
+				Detail:  err.Error(),
+			})
+			return nil, nil, false
+		}
+		conns[agent.ID] = conn
+		releases = append(releases, connRelease)
+	}
+	if len(conns) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Workspace has no agents.",
+			Detail:  "The workspace must be running.",
+		})
+		return nil, nil, false
+	}
+	return conns, release, true
+}
@@ -0,0 +1,72 @@
+package coderd_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentquiesce"
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceQuiesce(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("quiesce scripts are run with sh")
+	}
+
+	var (
+		dir        = t.TempDir()
+		marker     = filepath.Join(dir, "quiesced")
+		client, db = coderdtest.NewWithDatabase(t, nil)
+		user       = coderdtest.CreateFirstUser(t, client)
+		r          = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+	)
+	_ = agenttest.New(t, client.URL, r.AgentToken, func(o *agent.Options) {
+		o.Quiesce = agentquiesce.Config{
+			QuiesceScript: fmt.Sprintf("touch %q", marker),
+			ResumeScript:  fmt.Sprintf("rm %q", marker),
+		}
+	})
+	resources := coderdtest.NewWorkspaceAgentWaiter(t, client, r.Workspace.ID).Wait()
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	res, err := client.QuiesceWorkspace(ctx, r.Workspace.ID, codersdk.QuiesceWorkspaceRequest{
+		TimeoutSeconds: 600,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{agentID}, res.AgentIDs)
+	require.WithinDuration(t, time.Now().Add(10*time.Minute), res.ResumesAt, time.Minute)
+	require.FileExists(t, marker)
+
+	err = client.ResumeWorkspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	_, err = os.Stat(marker)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// The timeout is bounded by the agent.
+	_, err = client.QuiesceWorkspace(ctx, r.Workspace.ID, codersdk.QuiesceWorkspaceRequest{
+		TimeoutSeconds: int64((2 * agentquiesce.MaxTimeout).Seconds()),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
@@ -0,0 +1,90 @@
+package coderd
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Update workspace read-only mode
+// @Description Places a workspace in read-only mode during a security
+// @Description investigation, or lifts the mode. Users can still connect to a
+// @Description read-only workspace, but its agents block outbound git pushes and
+// @Description no new builds can be created for it, so its state is preserved.
+// @Description Only users that can update all workspaces of the organization
+// @Description may toggle the mode. Running agents apply it to sessions started
+// @Description after they reconnect or their environment is refreshed.
+// @ID update-workspace-read-only-mode
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceReadOnly true "Place a workspace in read-only mode or lift it"
+// @Success 204
+// @Router /api/v2/workspaces/{workspace}/read-only [put]
+func (api *API) putWorkspaceReadOnly(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		oldWorkspace      = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: oldWorkspace.OrganizationID,
+		})
+	)
+	aReq.Old = oldWorkspace.WorkspaceTable()
+	defer commitAudit()
+
+	var req codersdk.UpdateWorkspaceReadOnly
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// The owner of a workspace can update it, but must not be able to lift
+	// the read-only mode placed on it during an investigation.
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceWorkspace.InOrg(oldWorkspace.OrganizationID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	if oldWorkspace.ReadOnlyAt.Valid == req.ReadOnly {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	readOnlyAt := sql.NullTime{
+		Valid: req.ReadOnly,
+	}
+	if req.ReadOnly {
+		readOnlyAt.Time = dbtime.Time(api.Clock.Now())
+	}
+	newWorkspace, err := api.Database.UpdateWorkspaceReadOnlyAt(ctx, database.UpdateWorkspaceReadOnlyAtParams{
+		ID:         oldWorkspace.ID,
+		ReadOnlyAt: readOnlyAt,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace read-only mode.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = newWorkspace
+
+	rw.WriteHeader(http.StatusNoContent)
+}
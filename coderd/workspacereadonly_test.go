@@ -0,0 +1,82 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceReadOnly(t *testing.T) {
+	t.Parallel()
+
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+	require.Nil(t, workspace.ReadOnlyAt)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// The owner of a workspace can't toggle the read-only mode.
+	err := member.UpdateWorkspaceReadOnly(ctx, workspace.ID, codersdk.UpdateWorkspaceReadOnly{ReadOnly: true})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	auditor.ResetLogs()
+	err = client.UpdateWorkspaceReadOnly(ctx, workspace.ID, codersdk.UpdateWorkspaceReadOnly{ReadOnly: true})
+	require.NoError(t, err)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypeWorkspace,
+		ResourceID:   workspace.ID,
+		Action:       database.AuditActionWrite,
+		StatusCode:   http.StatusNoContent,
+	}))
+	// Placing a read-only workspace in read-only mode again is a no-op.
+	err = client.UpdateWorkspaceReadOnly(ctx, workspace.ID, codersdk.UpdateWorkspaceReadOnly{ReadOnly: true})
+	require.NoError(t, err)
+
+	workspace, err = member.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.NotNil(t, workspace.ReadOnlyAt)
+
+	// No builds can be created, including by admins, and the workspace can't
+	// be lifted out of read-only mode by its owner.
+	for _, transition := range []codersdk.WorkspaceTransition{
+		codersdk.WorkspaceTransitionStop,
+		codersdk.WorkspaceTransitionDelete,
+	} {
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: transition,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	}
+	err = member.UpdateWorkspaceReadOnly(ctx, workspace.ID, codersdk.UpdateWorkspaceReadOnly{ReadOnly: false})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = client.UpdateWorkspaceReadOnly(ctx, workspace.ID, codersdk.UpdateWorkspaceReadOnly{ReadOnly: false})
+	require.NoError(t, err)
+	workspace, err = member.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Nil(t, workspace.ReadOnlyAt)
+
+	build, err := member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+}
@@ -21,6 +21,7 @@ import (
 	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/buildfailure"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
@@ -132,6 +133,7 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	data.setWorkspaceFields(&w)
 	httpapi.Write(ctx, rw, http.StatusOK, w)
 }
 
@@ -143,9 +145,10 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 // @Security CoderSessionToken
 // @Produce json
 // @Tags Workspaces
-// @Param q query string false "Search query in the format `key:value`. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before, has-ai-task, has_external_agent, healthy."
+// @Param q query string false "Search query in the format `key:value`. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before, created_after, created_before, template_version, has-ai-task, has_external_agent, healthy, sort."
 // @Param limit query int false "Page limit"
 // @Param offset query int false "Page offset"
+// @Param fields query string false "Comma-separated list of workspace fields to return, e.g. `id,name,owner_name`. Omitting fields that depend on the latest build skips loading builds, resources, agents and apps."
 // @Success 200 {object} codersdk.WorkspacesResponse
 // @Router /api/v2/workspaces [get]
 func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
@@ -157,6 +160,15 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, errs := parseWorkspaceFields(r.URL.Query().Get("fields"))
+	if len(errs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace fields.",
+			Validations: errs,
+		})
+		return
+	}
+
 	queryStr := r.URL.Query().Get("q")
 	filter, errs := searchquery.Workspaces(ctx, api.Database, queryStr, page, api.AgentInactiveDisconnectTimeout, apiKey.UserID)
 	if len(errs) > 0 {
@@ -176,6 +188,12 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 	// the workspace owner_id when ordering the rows.
 	filter.RequesterID = apiKey.UserID
 
+	// Template restricted API keys only see workspaces built from their
+	// templates, regardless of the template filter in the query.
+	if len(apiKey.TemplateIDs) > 0 {
+		filter.TemplateIDs = restrictTemplateIDs(filter.TemplateIDs, apiKey.TemplateIDs)
+	}
+
 	// We need the technical row to present the correct count on every page.
 	filter.WithSummary = true
 
@@ -224,26 +242,54 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := api.workspaceData(ctx, workspaces)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching workspace resources.",
-			Detail:  err.Error(),
-		})
-		return
+	var wss []codersdk.Workspace
+	if fields != nil && !workspaceFieldsRequireBuilds(fields) {
+		wss, err = api.convertWorkspacesWithoutBuilds(ctx, apiKey.UserID, workspaces)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error converting workspaces.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	} else {
+		data, err := api.workspaceData(ctx, workspaces)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace resources.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		wss, err = convertWorkspaces(
+			ctx,
+			api.Logger,
+			apiKey.UserID,
+			workspaces,
+			data,
+		)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error converting workspaces.",
+				Detail:  err.Error(),
+			})
+			return
+		}
 	}
 
-	wss, err := convertWorkspaces(
-		ctx,
-		api.Logger,
-		apiKey.UserID,
-		workspaces,
-		data,
-	)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error converting workspaces.",
-			Detail:  err.Error(),
+	if fields != nil {
+		sparse, err := sparseWorkspaces(wss, fields)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error selecting workspace fields.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusOK, sparseWorkspacesResponse{
+			Workspaces: sparse,
+			Count:      int(workspaceRows[0].Count),
 		})
 		return
 	}
@@ -308,6 +354,10 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	if !apiKey.AllowsTemplate(workspace.TemplateID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
 
 	data, err := api.workspaceData(ctx, []database.Workspace{workspace})
 	if err != nil {
@@ -345,6 +395,7 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	data.setWorkspaceFields(&w)
 	httpapi.Write(ctx, rw, http.StatusOK, w)
 }
 
@@ -394,6 +445,12 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	idempotencyKey, err := idempotencyKeyFromRequest(r, req.IdempotencyKey)
+	if err != nil {
+		httperror.WriteResponseError(ctx, rw, err)
+		return
+	}
+
 	owner := workspaceOwner{
 		ID:        member.UserID,
 		Username:  member.Username,
@@ -401,7 +458,8 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 	}
 
 	w, err := createWorkspace(ctx, aReq, apiKey.UserID, api, owner, req, &createWorkspaceOptions{
-		remoteAddr: r.RemoteAddr,
+		remoteAddr:     r.RemoteAddr,
+		idempotencyKey: idempotencyKey,
 	})
 	if err != nil {
 		httperror.WriteResponseError(ctx, rw, err)
@@ -440,6 +498,12 @@ func (api *API) postUserWorkspaces(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	idempotencyKey, err := idempotencyKeyFromRequest(r, req.IdempotencyKey)
+	if err != nil {
+		httperror.WriteResponseError(ctx, rw, err)
+		return
+	}
+
 	var owner workspaceOwner
 	if mems.User != nil {
 		// This user fetch is an optimization path for the most common case of creating a
@@ -499,7 +563,8 @@ func (api *API) postUserWorkspaces(rw http.ResponseWriter, r *http.Request) {
 	defer commitAudit()
 
 	w, err := createWorkspace(ctx, aReq, apiKey.UserID, api, owner, req, &createWorkspaceOptions{
-		remoteAddr: r.RemoteAddr,
+		remoteAddr:     r.RemoteAddr,
+		idempotencyKey: idempotencyKey,
 	})
 	if err != nil {
 		httperror.WriteResponseError(ctx, rw, err)
@@ -526,6 +591,14 @@ type createWorkspaceOptions struct {
 	// audit logging. HTTP handlers should pass r.RemoteAddr;
 	// programmatic callers may leave it empty.
 	remoteAddr string
+	// state is the Terraform state used for the first build, so that it
+	// adopts existing infrastructure. Prebuilt workspaces are never claimed
+	// when it is set.
+	state []byte
+	// idempotencyKey makes retries of the request return the workspace
+	// created by the original request. HTTP handlers should pass the key
+	// returned by idempotencyKeyFromRequest.
+	idempotencyKey string
 }
 
 func createWorkspace(
@@ -541,6 +614,30 @@ func createWorkspace(
 		opts = &createWorkspaceOptions{}
 	}
 
+	var requestHash []byte
+	if opts.idempotencyKey != "" {
+		// The key is not part of the request it identifies.
+		hashReq := req
+		hashReq.IdempotencyKey = ""
+		var err error
+		requestHash, err = idempotencyRequestHash(owner.ID, hashReq)
+		if err != nil {
+			return codersdk.Workspace{}, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error hashing request.",
+				Detail:  err.Error(),
+			})
+		}
+		idempotencyKey, ok, err := api.lookupIdempotencyKey(ctx, initiatorID, database.IdempotencyKeyOperationCreateWorkspace, opts.idempotencyKey, requestHash)
+		if err != nil {
+			return codersdk.Workspace{}, err
+		}
+		if ok {
+			// The workspace was created and audited by the original
+			// request, so the replay leaves the audit log alone.
+			return api.idempotentWorkspace(ctx, initiatorID, idempotencyKey)
+		}
+	}
+
 	template, err := api.preflightWorkspaceCreate(ctx, owner.ID, req)
 	if err != nil {
 		return codersdk.Workspace{}, err
@@ -570,6 +667,13 @@ func createWorkspace(
 		return codersdk.Workspace{}, err
 	}
 
+	if req.UserPresetID != uuid.Nil {
+		req.RichParameterValues, err = api.applyUserPreset(ctx, template, templateVersion.ID, req.UserPresetID, req.RichParameterValues)
+		if err != nil {
+			return codersdk.Workspace{}, err
+		}
+	}
+
 	dbAutostartSchedule, err := validWorkspaceSchedule(req.AutostartSchedule)
 	if err != nil {
 		return codersdk.Workspace{}, httperror.NewResponseError(http.StatusBadRequest, codersdk.Response{
@@ -685,7 +789,7 @@ func createWorkspace(
 		}
 
 		// Try to claim a prebuilt workspace.
-		if templateVersionPresetID != uuid.Nil {
+		if templateVersionPresetID != uuid.Nil && len(opts.state) == 0 {
 			// Try and claim an eligible prebuild, if available.
 			// On successful claim, initialize all lifecycle fields from template and workspace-level config
 			// so the newly claimed workspace is properly managed by the lifecycle executor.
@@ -719,6 +823,11 @@ func createWorkspace(
 
 		// No prebuild found; regular flow.
 		if claimedWorkspace == nil {
+			// Trial workspaces get a hard expiry that activity does not extend.
+			var expiresAt sql.NullTime
+			if template.TrialWorkspaceTTL > 0 {
+				expiresAt = sql.NullTime{Valid: true, Time: now.Add(time.Duration(template.TrialWorkspaceTTL))}
+			}
 			// Workspaces are created without any versions.
 			minimumWorkspace, err := db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
 				ID:                uuid.New(),
@@ -735,6 +844,7 @@ func createWorkspace(
 				// have the newly created workspace at the top of the list!
 				LastUsedAt:       now,
 				AutomaticUpdates: dbAU,
+				ExpiresAt:        expiresAt,
 			})
 			if err != nil {
 				return xerrors.Errorf("insert workspace: %w", err)
@@ -779,6 +889,9 @@ func createWorkspace(
 		if claimedWorkspace != nil {
 			builder = builder.MarkPrebuiltWorkspaceClaim()
 		}
+		if len(opts.state) > 0 {
+			builder = builder.State(opts.state)
+		}
 
 		workspaceBuild, provisionerJob, provisionerDaemons, err = builder.Build(
 			ctx,
@@ -787,7 +900,7 @@ func createWorkspace(
 			func(action policy.Action, object rbac.Objecter) bool {
 				return api.HTTPAuth.AuthorizeContext(ctx, action, object)
 			},
-			audit.WorkspaceBuildBaggage{IP: opts.remoteAddr},
+			audit.WorkspaceBuildBaggage{IP: opts.remoteAddr}.WithImpersonation(ctx),
 		)
 		return err
 	}, nil)
@@ -817,12 +930,25 @@ func createWorkspace(
 	}
 
 	auditReq.New = workspace.WorkspaceTable()
+	auditReq.CorrelationID = workspaceBuild.ID
 
 	api.Telemetry.Report(&telemetry.Snapshot{
 		Workspaces:      []telemetry.Workspace{telemetry.ConvertWorkspace(workspace)},
 		WorkspaceBuilds: []telemetry.WorkspaceBuild{telemetry.ConvertWorkspaceBuild(*workspaceBuild)},
 	})
 
+	var assignedRegionID *uuid.UUID
+	if template.AutoAssignRegion {
+		regionID, err := api.assignWorkspaceRegion(ctx, workspace.ID, req.RegionLatenciesMS)
+		if err != nil {
+			// The workspace was created successfully, a missing assignment
+			// only means clients fall back to their own region selection.
+			api.Logger.Warn(ctx, "failed to assign workspace region", slog.F("workspace_id", workspace.ID), slog.Error(err))
+		} else {
+			assignedRegionID = &regionID
+		}
+	}
+
 	apiBuild, err := api.convertWorkspaceBuild(
 		*workspaceBuild,
 		workspace,
@@ -837,8 +963,16 @@ func createWorkspace(
 		[]database.WorkspaceAppStatus{},
 		[]database.GetWorkspaceAgentScriptsByAgentIDsRow{},
 		[]database.WorkspaceAgentLogSource{},
+		[]database.WorkspaceAgentBootstrapProgress{},
+		[]database.GetWorkspaceAgentNetworkPolicyViolationCountsByAgentIDsRow{},
+		[]database.WorkspaceAgentCrashLoop{},
+		[]database.GetWorkspaceAgentMemoryEventCountsByAgentIDsRow{},
+		[]database.WorkspaceAgentService{},
+		[]database.WorkspaceAgentServiceTransition{},
 		database.TemplateVersion{},
+		[]database.Template{template},
 		provisionerDaemons,
+		nil,
 	)
 	if err != nil {
 		return codersdk.Workspace{}, httperror.NewResponseError(http.StatusInternalServerError, codersdk.Response{
@@ -863,6 +997,11 @@ func createWorkspace(
 			Detail:  err.Error(),
 		})
 	}
+	w.AssignedRegionID = assignedRegionID
+
+	if opts.idempotencyKey != "" {
+		api.recordIdempotencyKey(ctx, initiatorID, database.IdempotencyKeyOperationCreateWorkspace, opts.idempotencyKey, requestHash, workspace.ID, workspaceBuild.ID)
+	}
 
 	return w, nil
 }
@@ -918,7 +1057,7 @@ func (api *API) requireWorkspaceOwnerExternalAuth(ctx context.Context, templateV
 //   - resolve the template (requestTemplate)
 //   - ActionCreate on a workspace in the template's organization for the owner
 //   - ActionUse on the template
-//   - reject deprecated templates
+//   - reject deprecated templates past their deprecation cutoff
 //
 // It deliberately does not validate required external auth (that mutates the
 // owner's external auth links and is enforced separately) and does not touch
@@ -931,6 +1070,13 @@ func (api *API) preflightWorkspaceCreate(ctx context.Context, ownerID uuid.UUID,
 		return database.Template{}, err
 	}
 
+	if apiKey, ok := httpmw.APIKeyFromContext(ctx); ok && !apiKey.AllowsTemplate(template.ID) {
+		return database.Template{}, httperror.NewResponseError(http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Unauthorized to create a workspace from the template %q.", template.Name),
+			Detail:  "The API key used for this request is restricted to other templates.",
+		})
+	}
+
 	// This is a premature auth check to avoid doing unnecessary work if the
 	// user doesn't have permission to create a workspace.
 	if !api.HTTPAuth.AuthorizeContext(ctx, policy.ActionCreate,
@@ -958,7 +1104,7 @@ func (api *API) preflightWorkspaceCreate(ctx context.Context, ownerID uuid.UUID,
 	}
 
 	templateAccessControl := (*(api.AccessControlStore.Load())).GetTemplateAccessControl(template)
-	if templateAccessControl.IsDeprecated() {
+	if templateAccessControl.BlocksCreation(api.Clock.Now()) {
 		return database.Template{}, httperror.NewResponseError(http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf("Template %q has been deprecated, and cannot be used to create a new workspace.", template.Name),
 			// Pass the deprecated message to the user.
@@ -969,6 +1115,26 @@ func (api *API) preflightWorkspaceCreate(ctx context.Context, ownerID uuid.UUID,
 	return template, nil
 }
 
+// restrictTemplateIDs narrows a requested template filter to the templates an
+// API key is restricted to. If no template was requested, all of the key's
+// templates are returned. If none of the requested templates are allowed, a
+// filter that matches no workspaces is returned.
+func restrictTemplateIDs(requested, allowed []uuid.UUID) []uuid.UUID {
+	if len(requested) == 0 {
+		return allowed
+	}
+	restricted := make([]uuid.UUID, 0, len(requested))
+	for _, id := range requested {
+		if slices.Contains(allowed, id) {
+			restricted = append(restricted, id)
+		}
+	}
+	if len(restricted) == 0 {
+		return []uuid.UUID{uuid.Nil}
+	}
+	return restricted
+}
+
 func requestTemplate(ctx context.Context, req codersdk.CreateWorkspaceRequest, db database.Store) (database.Template, error) {
 	// If we were given a `TemplateVersionID`, we need to determine the `TemplateID` from it.
 	templateID := req.TemplateID
@@ -1355,6 +1521,13 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Disabling autostop moves the deadline of a running build out to its
+	// max deadline, which is an extension.
+	if req.TTLMillis == nil && !api.Authorize(r, policy.ActionWorkspaceExtend, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
 	var dbTTL sql.NullInt64
 
 	err := api.Database.InTx(func(s database.Store) error {
@@ -1451,6 +1624,7 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 // @Param workspace path string true "Workspace ID" format(uuid)
 // @Param request body codersdk.UpdateWorkspaceDormancy true "Make a workspace dormant or active"
 // @Success 200 {object} codersdk.Workspace
+// @Success 202 {object} codersdk.Workspace
 // @Router /api/v2/workspaces/{workspace}/dormant [put]
 func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 	var (
@@ -1500,16 +1674,50 @@ func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 		dormantAt.Time = dbtime.Time(now)
 	}
 
-	newWorkspace, err := api.Database.UpdateWorkspaceDormantDeletingAt(ctx, database.UpdateWorkspaceDormantDeletingAtParams{
-		ID:        oldWorkspace.ID,
-		DormantAt: dormantAt,
-	})
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error updating workspace locked status.",
-			Detail:  err.Error(),
+	var (
+		newWorkspace = oldWorkspace.WorkspaceTable()
+		status       = http.StatusOK
+		err          error
+	)
+	if !req.Dormant && api.dormancyHooksEnabled() {
+		// The workspace stays dormant until the activate hook has restored
+		// its storage.
+		_, err = api.scheduleWorkspaceActivation(ctx, api.Database, oldWorkspace)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error scheduling workspace activation.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		status = http.StatusAccepted
+	} else {
+		newWorkspace, err = api.Database.UpdateWorkspaceDormantDeletingAt(ctx, database.UpdateWorkspaceDormantDeletingAtParams{
+			ID:        oldWorkspace.ID,
+			DormantAt: dormantAt,
 		})
-		return
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error updating workspace locked status.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	if req.Dormant && api.dormancyHooksEnabled() {
+		_, err = api.Database.UpsertWorkspaceDormancyHook(ctx, database.UpsertWorkspaceDormancyHookParams{
+			WorkspaceID: newWorkspace.ID,
+			Event:       database.WorkspaceDormancyHookEventDormant,
+			Now:         dbtime.Time(now),
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error scheduling workspace dormancy hook.",
+				Detail:  err.Error(),
+			})
+			return
+		}
 	}
 
 	// We don't need to notify the owner if they are the one making the request.
@@ -1606,7 +1814,8 @@ func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	httpapi.Write(ctx, rw, http.StatusOK, w)
+	data.setWorkspaceFields(&w)
+	httpapi.Write(ctx, rw, status, w)
 }
 
 // @Summary Extend workspace deadline by ID
@@ -1623,6 +1832,13 @@ func (api *API) putExtendWorkspace(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	workspace := httpmw.WorkspaceParam(r)
 
+	// Extending is authorized separately from updating the workspace so that
+	// roles can allow using a workspace without postponing its autostop.
+	if !api.Authorize(r, policy.ActionWorkspaceExtend, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
 	var req codersdk.PutExtendWorkspaceRequest
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
@@ -1718,6 +1934,133 @@ func (api *API) putExtendWorkspace(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, code, resp)
 }
 
+// @Summary Bump workspace deadline by ID
+// @Description Unlike extending, bumping postpones the deadline relative to the current
+// @Description one, and is capped by the max deadline of the build instead of rejected.
+// @ID bump-workspace-deadline-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.PostWorkspaceBumpRequest true "Bump deadline request"
+// @Success 200 {object} codersdk.WorkspaceBumpResponse
+// @Router /api/v2/workspaces/{workspace}/bump [post]
+func (api *API) postWorkspaceBump(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	if !api.Authorize(r, policy.ActionWorkspaceExtend, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.PostWorkspaceBumpRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	// Bumps are capped like workspace TTLs, which also keeps the deadline
+	// arithmetic below from overflowing.
+	if req.DurationMillis > ttlMaximum.Milliseconds() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid bump duration.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "duration_ms",
+				Detail: "Must be at most 30 days.",
+			}},
+		})
+		return
+	}
+
+	if workspace.IsPrebuild() {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "Deadline bumps are not supported for prebuilt workspaces.",
+		})
+		return
+	}
+
+	code := http.StatusOK
+	resp := codersdk.Response{}
+	var bumped codersdk.WorkspaceBumpResponse
+
+	err := api.Database.InTx(func(s database.Store) error {
+		build, err := s.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+		if err != nil {
+			code = http.StatusInternalServerError
+			resp.Message = "Error fetching workspace build."
+			return xerrors.Errorf("get latest workspace build: %w", err)
+		}
+
+		job, err := s.GetProvisionerJobByID(ctx, build.JobID)
+		if err != nil {
+			code = http.StatusInternalServerError
+			resp.Message = "Error fetching workspace provisioner job."
+			return xerrors.Errorf("get provisioner job: %w", err)
+		}
+
+		if build.Transition != database.WorkspaceTransitionStart {
+			code = http.StatusConflict
+			resp.Message = "Workspace must be started, current status: " + string(build.Transition)
+			return xerrors.Errorf("workspace must be started, current status: %s", build.Transition)
+		}
+		if !job.CompletedAt.Valid {
+			code = http.StatusConflict
+			resp.Message = "Workspace is still building!"
+			return xerrors.New("workspace is still building")
+		}
+		if build.Deadline.IsZero() {
+			code = http.StatusConflict
+			resp.Message = "Workspace shutdown is manual."
+			return xerrors.New("workspace shutdown is manual")
+		}
+
+		now := api.Clock.Now()
+		// A deadline that passed is bumped from now, since the workspace
+		// is about to be stopped.
+		base := build.Deadline
+		if base.Before(now) {
+			base = now
+		}
+		newDeadline := base.Add(time.Duration(req.DurationMillis) * time.Millisecond).UTC()
+		if !build.MaxDeadline.IsZero() && newDeadline.After(build.MaxDeadline) {
+			if !build.MaxDeadline.After(build.Deadline) {
+				code = http.StatusConflict
+				resp.Message = "Workspace deadline is already at its max deadline."
+				return xerrors.New("workspace deadline is already at its max deadline")
+			}
+			newDeadline = build.MaxDeadline.UTC()
+		}
+
+		if err := s.UpdateWorkspaceBuildDeadlineByID(ctx, database.UpdateWorkspaceBuildDeadlineByIDParams{
+			ID:          build.ID,
+			UpdatedAt:   dbtime.Time(now),
+			Deadline:    newDeadline,
+			MaxDeadline: build.MaxDeadline,
+		}); err != nil {
+			code = http.StatusInternalServerError
+			resp.Message = "Failed to bump workspace deadline."
+			return xerrors.Errorf("update workspace build: %w", err)
+		}
+
+		bumped.Deadline = newDeadline
+		if !build.MaxDeadline.IsZero() {
+			bumped.MaxDeadline = ptr.Ref(build.MaxDeadline.UTC())
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		api.Logger.Info(ctx, "bumping workspace deadline", slog.Error(err))
+		httpapi.Write(ctx, rw, code, resp)
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindMetadataUpdate,
+		WorkspaceID: workspace.ID,
+	})
+	httpapi.Write(ctx, rw, http.StatusOK, bumped)
+}
+
 // @Summary Post Workspace Usage by ID
 // @ID post-workspace-usage-by-id
 // @Security CoderSessionToken
@@ -1790,22 +2133,9 @@ func (api *API) postWorkspaceUsage(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stat := &proto.Stats{
-		ConnectionCount: 1,
-	}
-	switch req.AppName {
-	case codersdk.UsageAppNameVscode:
-		stat.SessionCountVscode = 1
-	case codersdk.UsageAppNameJetbrains:
-		stat.SessionCountJetbrains = 1
-	case codersdk.UsageAppNameReconnectingPty:
-		stat.SessionCountReconnectingPty = 1
-	case codersdk.UsageAppNameSSH:
-		stat.SessionCountSsh = 1
-	default:
-		// This means the app_name is in the codersdk.AllowedAppNames but not being
-		// handled by this switch statement.
-		httpapi.InternalServerError(rw, xerrors.Errorf("unknown app_name %q", req.AppName))
+	stat, err := workspaceUsageStats(req.AppName)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
 		return
 	}
 
@@ -1834,6 +2164,30 @@ func (api *API) postWorkspaceUsage(rw http.ResponseWriter, r *http.Request) {
 	rw.WriteHeader(http.StatusNoContent)
 }
 
+// workspaceUsageStats returns the agent stats that record a single session of
+// the given app, so usage reported through the API bumps workspace activity
+// the same way sessions reported by the agent do.
+func workspaceUsageStats(appName codersdk.UsageAppName) (*proto.Stats, error) {
+	stat := &proto.Stats{
+		ConnectionCount: 1,
+	}
+	switch appName {
+	case codersdk.UsageAppNameVscode:
+		stat.SessionCountVscode = 1
+	case codersdk.UsageAppNameJetbrains:
+		stat.SessionCountJetbrains = 1
+	case codersdk.UsageAppNameReconnectingPty:
+		stat.SessionCountReconnectingPty = 1
+	case codersdk.UsageAppNameSSH:
+		stat.SessionCountSsh = 1
+	default:
+		// This means the app_name is in the codersdk.AllowedAppNames but not being
+		// handled by this switch statement.
+		return nil, xerrors.Errorf("unknown app_name %q", appName)
+	}
+	return stat, nil
+}
+
 // @Summary Favorite workspace by ID.
 // @ID favorite-workspace-by-id
 // @Security CoderSessionToken
@@ -2543,16 +2897,32 @@ func (api *API) patchWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var aclChanges []audit.ACLChange
 	err := api.Database.InTx(func(tx database.Store) error {
 		var err error
 		workspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
 		if err != nil {
 			return xerrors.Errorf("get template by ID: %w", err)
 		}
+		oldUserRoles := workspaceACLRoles(workspace.UserACL)
+		oldGroupRoles := workspaceACLRoles(workspace.GroupACL)
 
 		for id, role := range req.UserRoles {
 			if role == codersdk.WorkspaceRoleDeleted {
 				delete(workspace.UserACL, id)
+				// The port-forward restrictions of the user only apply to
+				// this share, so they must not outlive it.
+				userID, err := uuid.Parse(id)
+				if err != nil {
+					return xerrors.Errorf("parse user ID: %w", err)
+				}
+				err = tx.DeleteWorkspacePortForwardACL(ctx, database.DeleteWorkspacePortForwardACLParams{
+					WorkspaceID: workspace.ID,
+					UserID:      userID,
+				})
+				if err != nil {
+					return xerrors.Errorf("delete workspace port-forward ACL: %w", err)
+				}
 				continue
 			}
 			workspace.UserACL[id] = database.WorkspaceACLEntry{
@@ -2582,6 +2952,10 @@ func (api *API) patchWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			return xerrors.Errorf("get updated workspace by ID: %w", err)
 		}
+		aclChanges = append(
+			audit.DiffACL(audit.ACLSubjectUser, oldUserRoles, workspaceACLRoles(workspace.UserACL)),
+			audit.DiffACL(audit.ACLSubjectGroup, oldGroupRoles, workspaceACLRoles(workspace.GroupACL))...,
+		)
 		return nil
 	}, nil)
 	if err != nil {
@@ -2594,6 +2968,7 @@ func (api *API) patchWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	aReq.New = workspace.WorkspaceTable()
+	aReq.SetAdditionalFields(audit.ACLAdditionalFields{ACLChanges: aclChanges})
 
 	rw.WriteHeader(http.StatusNoContent)
 }
@@ -2603,6 +2978,26 @@ type workspaceData struct {
 	builds       []codersdk.WorkspaceBuild
 	appStatuses  []codersdk.WorkspaceAppStatus
 	allowRenames bool
+	// dnsNames maps workspace IDs to their registered DNS name. It is only
+	// populated when a workspace DNS domain is configured.
+	dnsNames map[uuid.UUID]string
+	// failureSummaries maps workspace IDs to the failure summary of their
+	// latest build. It is only populated for failed builds.
+	failureSummaries map[uuid.UUID]*codersdk.WorkspaceBuildFailureSummary
+	// assignedRegions maps workspace IDs to the region they were assigned at
+	// creation. It is only populated for workspaces of templates with
+	// auto_assign_region set.
+	assignedRegions map[uuid.UUID]uuid.UUID
+}
+
+// setWorkspaceFields fills in the fields of w that are not derived by
+// convertWorkspace.
+func (d workspaceData) setWorkspaceFields(w *codersdk.Workspace) {
+	w.DNSName = d.dnsNames[w.ID]
+	w.LatestBuildFailureSummary = d.failureSummaries[w.ID]
+	if regionID, ok := d.assignedRegions[w.ID]; ok {
+		w.AssignedRegionID = &regionID
+	}
 }
 
 // @Summary Completely clears the workspace's user and group ACLs.
@@ -2633,16 +3028,28 @@ func (api *API) deleteWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var aclChanges []audit.ACLChange
 	err := api.Database.InTx(func(tx database.Store) error {
+		oldUserRoles := workspaceACLRoles(workspace.UserACL)
+		oldGroupRoles := workspaceACLRoles(workspace.GroupACL)
+
 		err := tx.DeleteWorkspaceACLByID(ctx, workspace.ID)
 		if err != nil {
 			return xerrors.Errorf("delete workspace by ID: %w", err)
 		}
+		err = tx.DeleteWorkspacePortForwardACLsByWorkspaceID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("delete workspace port-forward ACLs: %w", err)
+		}
 
 		workspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
 		if err != nil {
 			return xerrors.Errorf("get updated workspace by ID: %w", err)
 		}
+		aclChanges = append(
+			audit.DiffACL(audit.ACLSubjectUser, oldUserRoles, workspaceACLRoles(workspace.UserACL)),
+			audit.DiffACL(audit.ACLSubjectGroup, oldGroupRoles, workspaceACLRoles(workspace.GroupACL))...,
+		)
 
 		return nil
 	}, nil)
@@ -2652,6 +3059,7 @@ func (api *API) deleteWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	aReq.New = workspace.WorkspaceTable()
+	aReq.SetAdditionalFields(audit.ACLAdditionalFields{ACLChanges: aclChanges})
 
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
@@ -2693,6 +3101,8 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		templates   []database.Template
 		builds      []database.WorkspaceBuild
 		appStatuses []database.WorkspaceAppStatus
+		dnsRecords  []database.WorkspaceDNSRecord
+		assignments []database.WorkspaceRegionAssignment
 		eg          errgroup.Group
 	)
 	eg.Go(func() (err error) {
@@ -2722,6 +3132,26 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		}
 		return nil
 	})
+	if api.DeploymentValues.WorkspaceDNSDomain.String() != "" {
+		eg.Go(func() (err error) {
+			// This query must be run as system restricted to be efficient.
+			// nolint:gocritic
+			dnsRecords, err = api.Database.GetWorkspaceDNSRecordsByWorkspaceIDs(dbauthz.AsSystemRestricted(ctx), workspaceIDs)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("get workspace dns records: %w", err)
+			}
+			return nil
+		})
+	}
+	eg.Go(func() (err error) {
+		// This query must be run as system restricted to be efficient.
+		// nolint:gocritic
+		assignments, err = api.Database.GetWorkspaceRegionAssignmentsByWorkspaceIDs(dbauthz.AsSystemRestricted(ctx), workspaceIDs)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get workspace region assignments: %w", err)
+		}
+		return nil
+	})
 	err := eg.Wait()
 	if err != nil {
 		return workspaceData{}, err
@@ -2743,21 +3173,82 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		data.appStatuses,
 		data.scripts,
 		data.logSources,
+		data.bootstrapProgress,
+		data.networkPolicyViolations,
+		data.crashLoops,
+		data.memoryEvents,
+		data.services,
+		data.serviceTransitions,
 		data.templateVersions,
+		data.templates,
 		data.provisionerDaemons,
+		data.logArchives,
 	)
 	if err != nil {
 		return workspaceData{}, xerrors.Errorf("convert workspace builds: %w", err)
 	}
 
+	dnsNames := make(map[uuid.UUID]string, len(dnsRecords))
+	for _, record := range dnsRecords {
+		dnsNames[record.WorkspaceID] = record.Name
+	}
+
+	assignedRegions := make(map[uuid.UUID]uuid.UUID, len(assignments))
+	for _, assignment := range assignments {
+		assignedRegions[assignment.WorkspaceID] = assignment.RegionID
+	}
+
+	failureSummaries, err := api.buildFailureSummaries(ctx, apiBuilds)
+	if err != nil {
+		return workspaceData{}, xerrors.Errorf("get build failure summaries: %w", err)
+	}
+
 	return workspaceData{
-		templates:    templates,
-		appStatuses:  db2sdk.WorkspaceAppStatuses(appStatuses),
-		builds:       apiBuilds,
-		allowRenames: api.Options.AllowWorkspaceRenames,
+		templates:        templates,
+		appStatuses:      db2sdk.WorkspaceAppStatuses(appStatuses),
+		builds:           apiBuilds,
+		allowRenames:     api.Options.AllowWorkspaceRenames,
+		dnsNames:         dnsNames,
+		failureSummaries: failureSummaries,
+		assignedRegions:  assignedRegions,
 	}, nil
 }
 
+// buildFailureSummaries summarizes the root cause of the failed builds in
+// builds, keyed by workspace ID. Only the error-level logs of failed builds
+// are fetched, so this is cheap for lists of workspaces.
+func (api *API) buildFailureSummaries(ctx context.Context, builds []codersdk.WorkspaceBuild) (map[uuid.UUID]*codersdk.WorkspaceBuildFailureSummary, error) {
+	var jobIDs []uuid.UUID
+	for _, build := range builds {
+		if build.Job.Status == codersdk.ProvisionerJobFailed {
+			jobIDs = append(jobIDs, build.Job.ID)
+		}
+	}
+	if len(jobIDs) == 0 {
+		return nil, nil
+	}
+
+	// This query must be run as system restricted to be efficient.
+	// nolint:gocritic
+	logs, err := api.Database.GetProvisionerJobErrorLogsByJobIDs(dbauthz.AsSystemRestricted(ctx), jobIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	logsByJobID := make(map[uuid.UUID][]database.ProvisionerJobLog, len(jobIDs))
+	for _, log := range logs {
+		logsByJobID[log.JobID] = append(logsByJobID[log.JobID], log)
+	}
+
+	summaries := make(map[uuid.UUID]*codersdk.WorkspaceBuildFailureSummary, len(jobIDs))
+	for _, build := range builds {
+		if build.Job.Status != codersdk.ProvisionerJobFailed {
+			continue
+		}
+		summaries[build.WorkspaceID] = buildfailure.Summarize(build.Job.Error, logsByJobID[build.Job.ID])
+	}
+	return summaries, nil
+}
+
 func convertWorkspaces(
 	ctx context.Context,
 	logger slog.Logger,
@@ -2808,6 +3299,7 @@ func convertWorkspaces(
 		if err != nil {
 			return nil, xerrors.Errorf("convert workspace: %w", err)
 		}
+		data.setWorkspaceFields(&w)
 
 		apiWorkspaces = append(apiWorkspaces, w)
 	}
@@ -2847,6 +3339,31 @@ func convertWorkspace(
 		nextStartAt = &workspace.NextStartAt.Time
 	}
 
+	var expiresAt *time.Time
+	if workspace.ExpiresAt.Valid {
+		expiresAt = &workspace.ExpiresAt.Time
+	}
+
+	maxLifetime := schedule.TemplateMaxLifetime{
+		Duration: time.Duration(template.MaxLifetime),
+		Action:   template.MaxLifetimeAction,
+	}
+	if lifetimeExpiresAt, ok := maxLifetime.ExpiresAt(workspace.CreatedAt); ok {
+		if expiresAt == nil || lifetimeExpiresAt.Before(*expiresAt) {
+			expiresAt = &lifetimeExpiresAt
+		}
+	}
+
+	var archivedAt *time.Time
+	if workspace.ArchivedAt.Valid {
+		archivedAt = &workspace.ArchivedAt.Time
+	}
+
+	var readOnlyAt *time.Time
+	if workspace.ReadOnlyAt.Valid {
+		readOnlyAt = &workspace.ReadOnlyAt.Time
+	}
+
 	failingAgents := []uuid.UUID{}
 	for _, resource := range workspaceBuild.Resources {
 		for _, agent := range resource.Agents {
@@ -2908,13 +3425,17 @@ func convertWorkspace(
 			Healthy:       len(failingAgents) == 0,
 			FailingAgents: failingAgents,
 		},
-		AutomaticUpdates: codersdk.AutomaticUpdates(workspace.AutomaticUpdates),
-		AllowRenames:     allowRenames,
-		Favorite:         requesterFavorite,
-		NextStartAt:      nextStartAt,
-		IsPrebuild:       workspace.IsPrebuild(),
-		TaskID:           workspace.TaskID,
-		SharedWith:       sharedWorkspaceActors(ctx, logger, workspace),
+		AutomaticUpdates:    codersdk.AutomaticUpdates(workspace.AutomaticUpdates),
+		AllowRenames:        allowRenames,
+		Favorite:            requesterFavorite,
+		NextStartAt:         nextStartAt,
+		ExpiresAt:           expiresAt,
+		ArchivedAt:          archivedAt,
+		ReadOnlyAt:          readOnlyAt,
+		IsPrebuild:          workspace.IsPrebuild(),
+		TaskID:              workspace.TaskID,
+		SharedWith:          sharedWorkspaceActors(ctx, logger, workspace),
+		DeprecationWarnings: workspaceBuild.DeprecationWarnings,
 	}, nil
 }
 
@@ -3086,6 +3607,15 @@ func (WorkspaceACLUpdateValidator) ValidateRole(role codersdk.WorkspaceRole) err
 	return nil
 }
 
+// workspaceACLRoles maps the subjects of a workspace ACL to their role names.
+func workspaceACLRoles(acl database.WorkspaceACL) map[string]string {
+	roles := make(map[string]string, len(acl))
+	for id, entry := range acl {
+		roles[id] = string(convertToWorkspaceRole(entry.Permissions))
+	}
+	return roles
+}
+
 func convertToWorkspaceRole(actions []policy.Action) codersdk.WorkspaceRole {
 	switch {
 	case slice.SameElements(actions, db2sdk.WorkspaceRoleActions(codersdk.WorkspaceRoleAdmin)):
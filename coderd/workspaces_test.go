@@ -1025,6 +1025,185 @@ func TestResolveAutostart(t *testing.T) {
 	require.False(t, resolveResp.ParameterMismatch)
 }
 
+func TestWorkspacesSparseFields(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		Name:           "sparse",
+		OwnerID:        owner.UserID,
+		OrganizationID: owner.OrganizationID,
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	list := func(fields string) map[string]any {
+		t.Helper()
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/workspaces?fields="+fields, nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var body struct {
+			Workspaces []map[string]any `json:"workspaces"`
+			Count      int              `json:"count"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.Equal(t, 1, body.Count)
+		require.Len(t, body.Workspaces, 1)
+		return body.Workspaces[0]
+	}
+
+	// Only the selected fields are returned.
+	ws := list("id,name,owner_name")
+	require.Equal(t, map[string]any{
+		"id":         r.Workspace.ID.String(),
+		"name":       "sparse",
+		"owner_name": coderdtest.FirstUserParams.Username,
+	}, ws)
+
+	// Fields derived from the latest build are still available.
+	ws = list("id,latest_build")
+	require.Len(t, ws, 2)
+	build, ok := ws["latest_build"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, r.Build.ID.String(), build["id"])
+
+	// The SDK decodes sparse responses into zero-valued workspaces.
+	res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{Fields: []string{"id", "template_name"}})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 1)
+	require.Equal(t, r.Workspace.ID, res.Workspaces[0].ID)
+	require.Empty(t, res.Workspaces[0].Name)
+	require.NotEmpty(t, res.Workspaces[0].TemplateName)
+
+	// Unknown fields are rejected.
+	_, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{Fields: []string{"id", "secrets"}})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
+// TestWorkspacesSparseFieldsMatchFull selects every workspace field on its
+// own and checks it matches the full response, so a field that needs the
+// latest build but is missing from workspaceFieldsFromBuilds is caught.
+func TestWorkspacesSparseFieldsMatchFull(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		Name:           "sparse",
+		OwnerID:        owner.UserID,
+		OrganizationID: owner.OrganizationID,
+	}).WithAgent().Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	list := func(query string) map[string]json.RawMessage {
+		t.Helper()
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/workspaces"+query, nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var body struct {
+			Workspaces []map[string]json.RawMessage `json:"workspaces"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.Len(t, body.Workspaces, 1)
+		return body.Workspaces[0]
+	}
+
+	full := list("")
+	for field := range coderd.WorkspaceFields {
+		sparse := list("?fields=" + field)
+		want, ok := full[field]
+		if !ok {
+			require.Empty(t, sparse, "field %q", field)
+			continue
+		}
+		require.Len(t, sparse, 1, "field %q", field)
+		require.JSONEq(t, string(want), string(sparse[field]), "field %q", field)
+	}
+}
+
+func TestWorkspacesSparseFieldsFromBuilds(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	// sparseField lists the workspace with only its ID and the given field
+	// selected, and returns the field.
+	sparseField := func(ctx context.Context, t *testing.T, workspace database.WorkspaceTable, field string) any {
+		t.Helper()
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/workspaces?q=name:"+workspace.Name+"&fields=id,"+field, nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var body struct {
+			Workspaces []map[string]any `json:"workspaces"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.Len(t, body.Workspaces, 1)
+		require.Equal(t, workspace.ID.String(), body.Workspaces[0]["id"])
+		return body.Workspaces[0][field]
+	}
+
+	t.Run("DeprecationWarnings", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			Name:           "deprecated",
+			OwnerID:        owner.UserID,
+			OrganizationID: owner.OrganizationID,
+		}).Do()
+		err := db.UpdateTemplateAccessControlByID(ctx, database.UpdateTemplateAccessControlByIDParams{
+			ID:         r.Workspace.TemplateID,
+			Deprecated: "Use another template.",
+		})
+		require.NoError(t, err)
+
+		warnings, ok := sparseField(ctx, t, r.Workspace, "deprecation_warnings").([]any)
+		require.True(t, ok)
+		require.Len(t, warnings, 1)
+	})
+
+	t.Run("LatestBuildFailureSummary", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			Name:           "failed",
+			OwnerID:        owner.UserID,
+			OrganizationID: owner.OrganizationID,
+		}).Failed().Do()
+
+		summary, ok := sparseField(ctx, t, r.Workspace, "latest_build_failure_summary").(map[string]any)
+		require.True(t, ok)
+		require.Equal(t, "failed", summary["message"])
+	})
+
+	t.Run("AssignedRegionID", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			Name:           "assigned",
+			OwnerID:        owner.UserID,
+			OrganizationID: owner.OrganizationID,
+		}).Do()
+		regionID := uuid.New()
+		_, err := db.UpsertWorkspaceRegionAssignment(ctx, database.UpsertWorkspaceRegionAssignmentParams{
+			WorkspaceID: r.Workspace.ID,
+			RegionID:    regionID,
+			CreatedAt:   dbtime.Now(),
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, regionID.String(), sparseField(ctx, t, r.Workspace, "assigned_region_id"))
+	})
+}
+
 func TestWorkspacesSortOrder(t *testing.T) {
 	t.Parallel()
 
@@ -1109,6 +1288,58 @@ func TestWorkspacesSortOrder(t *testing.T) {
 	assert.Equal(t, expectedNames, actualNames)
 }
 
+func TestWorkspacesExplicitSort(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	now := dbtime.Now()
+
+	newWorkspace := func(name string, createdAt, lastUsedAt time.Time) dbfake.WorkspaceResponse {
+		return dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			Name:           name,
+			OwnerID:        user.UserID,
+			OrganizationID: user.OrganizationID,
+			CreatedAt:      createdAt,
+			LastUsedAt:     lastUsedAt,
+		}).Do()
+	}
+	a := newWorkspace("a-workspace", now.Add(-3*time.Hour), now.Add(-time.Hour))
+	b := newWorkspace("b-workspace", now.Add(-time.Hour), now.Add(-3*time.Hour))
+	c := newWorkspace("c-workspace", now.Add(-2*time.Hour), now.Add(-2*time.Hour))
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	// An explicit sort overrides the favorite-first default.
+	require.NoError(t, client.FavoriteWorkspace(ctx, c.Workspace.ID))
+
+	for _, tc := range []struct {
+		sort     codersdk.WorkspaceSort
+		expected []string
+	}{
+		{codersdk.WorkspaceSortNameAsc, []string{a.Workspace.Name, b.Workspace.Name, c.Workspace.Name}},
+		{codersdk.WorkspaceSortNameDesc, []string{c.Workspace.Name, b.Workspace.Name, a.Workspace.Name}},
+		{codersdk.WorkspaceSortLastUsedDesc, []string{a.Workspace.Name, c.Workspace.Name, b.Workspace.Name}},
+		{codersdk.WorkspaceSortLastUsedAsc, []string{b.Workspace.Name, c.Workspace.Name, a.Workspace.Name}},
+		{codersdk.WorkspaceSortCreatedAtDesc, []string{b.Workspace.Name, c.Workspace.Name, a.Workspace.Name}},
+		{codersdk.WorkspaceSortCreatedAtAsc, []string{a.Workspace.Name, c.Workspace.Name, b.Workspace.Name}},
+	} {
+		res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{Sort: tc.sort})
+		require.NoError(t, err, tc.sort)
+		actual := make([]string, 0, len(res.Workspaces))
+		for _, w := range res.Workspaces {
+			actual = append(actual, w.Name)
+		}
+		assert.Equal(t, tc.expected, actual, tc.sort)
+	}
+
+	// Pagination is applied after sorting.
+	res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{Sort: codersdk.WorkspaceSortNameDesc, Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 1)
+	require.Equal(t, b.Workspace.Name, res.Workspaces[0].Name)
+	require.Equal(t, 3, res.Count)
+}
+
 func TestPostWorkspacesByOrganization(t *testing.T) {
 	t.Parallel()
 	t.Run("InvalidTemplate", func(t *testing.T) {
@@ -2868,6 +3099,53 @@ func TestWorkspaceFilterManual(t *testing.T) {
 		require.Len(t, res.Workspaces, 1)
 		require.Equal(t, workspace.ID, res.Workspaces[0].ID)
 	})
+	t.Run("CreatedAndTemplateVersion", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		oldWorkspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, oldWorkspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		between := dbtime.Now()
+		newTv := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil, func(request *codersdk.CreateTemplateVersionRequest) {
+			request.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, newTv.ID)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: newTv.ID,
+		})
+		require.NoError(t, err)
+		newWorkspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, newWorkspace.LatestBuild.ID)
+
+		for _, tc := range []struct {
+			query    string
+			expected uuid.UUID
+		}{
+			{query: fmt.Sprintf("created_before:%q", between.Format(time.RFC3339Nano)), expected: oldWorkspace.ID},
+			{query: fmt.Sprintf("created_after:%q", between.Format(time.RFC3339Nano)), expected: newWorkspace.ID},
+			{query: fmt.Sprintf("template_version:%q", version.Name), expected: oldWorkspace.ID},
+			{query: fmt.Sprintf("template_version:%s", newTv.ID), expected: newWorkspace.ID},
+			{query: fmt.Sprintf("template_version:%q created_before:%q", newTv.Name, between.Format(time.RFC3339Nano)), expected: uuid.Nil},
+		} {
+			res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+				FilterQuery: tc.query,
+			})
+			require.NoError(t, err, tc.query)
+			if tc.expected == uuid.Nil {
+				require.Empty(t, res.Workspaces, tc.query)
+				continue
+			}
+			require.Len(t, res.Workspaces, 1, tc.query)
+			require.Equal(t, tc.expected, res.Workspaces[0].ID, tc.query)
+		}
+	})
 
 	t.Run("HealthyFilter", func(t *testing.T) {
 		t.Parallel()
@@ -3801,6 +4079,67 @@ func TestWorkspaceExtend(t *testing.T) {
 	require.WithinDuration(t, oldDeadline.Add(-time.Hour), updated.LatestBuild.Deadline.Time, time.Minute)
 }
 
+func TestWorkspaceBump(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+
+	now := dbtime.Now()
+	deadline := now.Add(time.Hour)
+	maxDeadline := now.Add(4 * time.Hour)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).Seed(database.WorkspaceBuild{
+		Deadline:    deadline,
+		MaxDeadline: maxDeadline,
+	}).Do()
+
+	// The duration is added to the current deadline.
+	bumped, err := client.PostWorkspaceBump(ctx, r.Workspace.ID, codersdk.PostWorkspaceBumpRequest{
+		DurationMillis: (2 * time.Hour).Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, deadline.Add(2*time.Hour), bumped.Deadline, time.Second)
+	require.NotNil(t, bumped.MaxDeadline)
+	require.WithinDuration(t, maxDeadline, *bumped.MaxDeadline, time.Second)
+
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.WithinDuration(t, bumped.Deadline, workspace.LatestBuild.Deadline.Time, time.Second)
+
+	// The deadline is capped by the max deadline.
+	bumped, err = client.PostWorkspaceBump(ctx, r.Workspace.ID, codersdk.PostWorkspaceBumpRequest{
+		DurationMillis: (2 * time.Hour).Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, maxDeadline, bumped.Deadline, time.Second)
+
+	// Once at the max deadline, the workspace cannot be bumped.
+	_, err = client.PostWorkspaceBump(ctx, r.Workspace.ID, codersdk.PostWorkspaceBumpRequest{
+		DurationMillis: time.Hour.Milliseconds(),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	// The duration is required.
+	_, err = client.PostWorkspaceBump(ctx, r.Workspace.ID, codersdk.PostWorkspaceBumpRequest{})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Durations that would overflow the deadline are rejected.
+	_, err = client.PostWorkspaceBump(ctx, r.Workspace.ID, codersdk.PostWorkspaceBumpRequest{
+		DurationMillis: math.MaxInt64,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Len(t, apiErr.Validations, 1)
+	require.Equal(t, "duration_ms", apiErr.Validations[0].Field)
+}
+
 func TestWorkspaceUpdateAutomaticUpdates_OK(t *testing.T) {
 	t.Parallel()
 
@@ -4807,6 +5146,11 @@ func TestWorkspaceWithEphemeralRichParameters(t *testing.T) {
 	}
 	require.ElementsMatch(t, expectedBuildParameters, workspaceBuildParameters)
 
+	// Ephemeral values are not exposed in the parameter changes.
+	require.Equal(t, []codersdk.WorkspaceBuildParameterChange{
+		{Name: ephemeralParameterName, Kind: codersdk.WorkspaceBuildParameterChangeKindModified, Redacted: true},
+	}, workspaceBuild.ParameterChanges)
+
 	// Trigger workspace build one more time without the ephemeral parameter
 	workspaceBuild, err = client.CreateWorkspaceBuild(ctx, workspaceBuild.WorkspaceID, codersdk.CreateWorkspaceBuildRequest{
 		Transition: codersdk.WorkspaceTransitionStart,
@@ -4830,6 +5174,11 @@ func TestWorkspaceWithEphemeralRichParameters(t *testing.T) {
 		{Name: ephemeralParameterName, Value: ephemeralParameterDefaultValue},
 	}
 	require.ElementsMatch(t, expectedBuildParameters, workspaceBuildParameters)
+
+	require.Equal(t, []codersdk.WorkspaceBuildParameterChange{
+		{Name: firstParameterName, Kind: codersdk.WorkspaceBuildParameterChangeKindModified, PreviousValue: firstParameterDefaultValue, NewValue: firstParameterValue},
+		{Name: ephemeralParameterName, Kind: codersdk.WorkspaceBuildParameterChangeKindModified, Redacted: true},
+	}, workspaceBuild.ParameterChanges)
 }
 
 func TestWorkspaceDormant(t *testing.T) {
@@ -5175,6 +5524,107 @@ func TestWorkspaceUsageTracking(t *testing.T) {
 	})
 }
 
+func TestWorkspaceHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		dv := coderdtest.DeploymentValues(t)
+		dv.Experiments = []string{string(codersdk.ExperimentWorkspaceUsage)}
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		templateVersion := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: user.OrganizationID,
+			CreatedBy:      user.UserID,
+		})
+		template := dbgen.Template(t, db, database.Template{
+			OrganizationID:  user.OrganizationID,
+			ActiveVersionID: templateVersion.ID,
+			CreatedBy:       user.UserID,
+			DefaultTTL:      int64(8 * time.Hour),
+		})
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ActivityBumpMillis: ptr.Ref(8 * time.Hour.Milliseconds()),
+		})
+		require.NoError(t, err)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+			TemplateID:     template.ID,
+			Ttl:            sql.NullInt64{Valid: true, Int64: int64(8 * time.Hour)},
+		}).WithAgent().Do()
+
+		workspace, err := client.Workspace(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+
+		// The agent may be omitted when the workspace has a single agent.
+		err = client.PostWorkspaceHeartbeat(ctx, r.Workspace.ID, codersdk.PostWorkspaceHeartbeatRequest{
+			AppName: codersdk.UsageAppNameVscode,
+			Plugin:  "coder-neovim/0.3.1",
+		})
+		require.NoError(t, err)
+
+		newWorkspace, err := client.Workspace(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.True(t, newWorkspace.LatestBuild.Deadline.Valid)
+		require.Greater(t, newWorkspace.LatestBuild.Deadline.Time, workspace.LatestBuild.Deadline.Time)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+
+		err := client.PostWorkspaceHeartbeat(ctx, r.Workspace.ID, codersdk.PostWorkspaceHeartbeatRequest{
+			AppName: codersdk.UsageAppNameVscode,
+		})
+		require.ErrorContains(t, err, "plugin")
+		err = client.PostWorkspaceHeartbeat(ctx, r.Workspace.ID, codersdk.PostWorkspaceHeartbeatRequest{
+			AppName: "unknown",
+			Plugin:  "coder-neovim/0.3.1",
+		})
+		require.ErrorContains(t, err, "app_name")
+		err = client.PostWorkspaceHeartbeat(ctx, r.Workspace.ID, codersdk.PostWorkspaceHeartbeatRequest{
+			AgentID: uuid.New(),
+			AppName: codersdk.UsageAppNameVscode,
+			Plugin:  "coder-neovim/0.3.1",
+		})
+		require.ErrorContains(t, err, "agent_id")
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			WorkspaceHeartbeatRateLimit: 1,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+
+		req := codersdk.PostWorkspaceHeartbeatRequest{
+			AppName: codersdk.UsageAppNameJetbrains,
+			Plugin:  "coder-gateway/2.0.0",
+		}
+		err := client.PostWorkspaceHeartbeat(ctx, r.Workspace.ID, req)
+		require.NoError(t, err)
+		err = client.PostWorkspaceHeartbeat(ctx, r.Workspace.ID, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode())
+	})
+}
+
 func TestWorkspaceNotifications(t *testing.T) {
 	t.Parallel()
 
@@ -6635,6 +7085,53 @@ func TestProvisionerJobQueueWaitMetric(t *testing.T) {
 	require.Greater(t, buildHistogram.GetSampleSum(), 0.0, "workspace build job queue wait should be non-zero")
 }
 
+func TestWorkspaceBuildLifecycleMetrics(t *testing.T) {
+	t.Parallel()
+
+	logger := testutil.Logger(t)
+	reg := prometheus.NewRegistry()
+	metrics := provisionerdserver.NewMetrics(logger)
+	err := metrics.Register(reg)
+	require.NoError(t, err)
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon:  true,
+		ProvisionerdServerMetrics: metrics,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	org, err := client.Organization(ctx, user.OrganizationID)
+	require.NoError(t, err)
+
+	queueWait := promhelp.HistogramValue(t, reg, "coderd_workspace_build_queue_wait_seconds", prometheus.Labels{
+		"organization_name": org.Name,
+		"template_name":     template.Name,
+		"transition":        string(database.WorkspaceTransitionStart),
+		"build_reason":      string(database.BuildReasonInitiator),
+	})
+	require.Equal(t, uint64(1), queueWait.GetSampleCount())
+
+	// The counter is incremented after the job is marked completed, so it may
+	// lag slightly behind the build status.
+	completedLabels := prometheus.Labels{
+		"organization_name": org.Name,
+		"template_name":     template.Name,
+		"transition":        string(database.WorkspaceTransitionStart),
+		"build_reason":      string(database.BuildReasonInitiator),
+		"status":            provisionerdserver.BuildStatusSucceeded,
+	}
+	require.Eventually(t, func() bool {
+		return promhelp.MetricValue(t, reg, "coderd_workspace_builds_completed_total", completedLabels) != nil
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_workspace_builds_completed_total", completedLabels))
+}
+
 func TestWorkspaceBuildsEnqueuedMetric(t *testing.T) {
 	t.Parallel()
 
@@ -6715,3 +7212,51 @@ func mustSchedule(t *testing.T, s string) *cron.Schedule {
 	require.NoError(t, err)
 	return sched
 }
+
+func TestWorkspaceLatestBuildFailureSummary(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	errorLog := func(output string) *proto.Response {
+		return &proto.Response{Type: &proto.Response_Log{Log: &proto.Log{Level: proto.LogLevel_ERROR, Output: output}}}
+	}
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionApplyMap: map[proto.WorkspaceTransition][]*proto.Response{
+			proto.WorkspaceTransition_START: append([]*proto.Response{
+				errorLog("Error: creating EC2 Instance: UnauthorizedOperation"),
+				errorLog(`on main.tf line 42, in resource "aws_instance" "dev":`),
+				errorLog(`  42: resource "aws_instance" "dev" {`),
+			}, echo.ApplyFailed...),
+			proto.WorkspaceTransition_STOP: echo.ApplyComplete,
+		},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	expected := &codersdk.WorkspaceBuildFailureSummary{
+		Message:         "creating EC2 Instance: UnauthorizedOperation",
+		ResourceAddress: "aws_instance.dev",
+	}
+
+	workspace, err := client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, expected, workspace.LatestBuildFailureSummary)
+
+	res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 1)
+	require.Equal(t, expected, res.Workspaces[0].LatestBuildFailureSummary)
+
+	// A successful build clears the summary.
+	build = coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStop)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+	workspace, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Nil(t, workspace.LatestBuildFailureSummary)
+}
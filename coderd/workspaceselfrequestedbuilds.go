@@ -0,0 +1,65 @@
+package coderd
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// requestWorkspaceTransition stops a workspace on behalf of its owner at the
+// request of its agent, and starts it again afterwards with restart. The
+// builds are initiated by the owner, with the reason "self_requested".
+func (api *API) requestWorkspaceTransition(ctx context.Context, ownerID, workspaceID uuid.UUID, restart bool) (uuid.UUID, error) {
+	actor, _, err := httpmw.UserRBACSubject(ctx, api.Database, ownerID, rbac.ScopeAll)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("load user authorization: %w", err)
+	}
+	ctx = dbauthz.As(ctx, actor)
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get workspace: %w", err)
+	}
+
+	req := codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+		Reason:     codersdk.CreateWorkspaceBuildReasonSelfRequested,
+	}
+	if restart {
+		req.OnSuccess = &codersdk.CreateWorkspaceBuildOnSuccessRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		}
+	}
+
+	// Build a synthetic API key so postWorkspaceBuildsInternal can
+	// record the correct initiator.
+	syntheticKey := database.APIKey{
+		UserID: ownerID,
+	}
+
+	build, err := api.postWorkspaceBuildsInternal(
+		ctx,
+		syntheticKey,
+		workspace,
+		req,
+		func(action policy.Action, object rbac.Objecter) bool {
+			authErr := api.HTTPAuth.Authorizer.Authorize(ctx, actor, action, object.RBACObject())
+			return authErr == nil
+		},
+		audit.WorkspaceBuildBaggage{},
+	)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("create workspace build: %w", err)
+	}
+
+	return build.ID, nil
+}
@@ -9,6 +9,9 @@ import (
 
 	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 // ActivityBumpReason represents the reason for an activity bump.
@@ -51,15 +54,29 @@ const (
 // A way to avoid this is to configure the max deadline to something that will not
 // span more than 1 day. This will force the workspace to restart and reset the deadline
 // each morning when it autostarts.
-func ActivityBumpWorkspace(ctx context.Context, log slog.Logger, db database.Store, workspaceID uuid.UUID, nextAutostart time.Time, reason ActivityBumpReason) {
+//
+// connectionTypes are the types of connections the activity came from. The
+// workspace is only bumped if the template allows one of them to bump the
+// deadline, and by no more than the template's daily maximum.
+func ActivityBumpWorkspace(ctx context.Context, log slog.Logger, db database.Store, workspaceID uuid.UUID, nextAutostart time.Time, reason ActivityBumpReason, connectionTypes ...codersdk.ActivityBumpConnectionType) {
 	// We set a short timeout so if the app is under load, these
 	// low priority operations fail first.
 	ctx, cancel := context.WithTimeout(ctx, time.Second*15)
 	defer cancel()
 	err := db.ActivityBumpWorkspace(ctx, database.ActivityBumpWorkspaceParams{
-		NextAutostart: nextAutostart.UTC(),
-		WorkspaceID:   workspaceID,
+		NextAutostart:   nextAutostart.UTC(),
+		ConnectionTypes: slice.ToStrings(connectionTypes),
+		WorkspaceID:     workspaceID,
 	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		// The actor's roles do not allow extending the workspace,
+		// so activity does not postpone autostop.
+		log.Debug(ctx, "skipped activity bump, not allowed to extend workspace",
+			slog.F("workspace_id", workspaceID),
+			slog.F("reason", reason),
+		)
+		return
+	}
 	if err != nil {
 		if !xerrors.Is(err, context.Canceled) && !database.IsQueryCanceledError(err) {
 			// Bump will fail if the context is canceled, but this is ok.
@@ -15,6 +15,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/workspacestats"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -41,6 +42,9 @@ func Test_ActivityBumpWorkspace(t *testing.T) {
 		templateTTL                   time.Duration
 		templateActivityBump          time.Duration
 		templateDisallowsUserAutostop bool
+		templateConnectionTypes       []string
+		templateMaxBumpPerDay         time.Duration
+		connectionTypes               []codersdk.ActivityBumpConnectionType
 		expectedBump                  time.Duration
 		// If the tests get queued, we need to be able to set the next autostart
 		// based on the actual time the unit test is running.
@@ -156,6 +160,39 @@ func Test_ActivityBumpWorkspace(t *testing.T) {
 			templateActivityBump: -1, // negative values get changed to 0 in the test
 			expectedBump:         0,
 		},
+		{
+			// The template only allows SSH connections to bump the deadline.
+			name:                    "TemplateConnectionTypeAllowed",
+			transition:              database.WorkspaceTransitionStart,
+			jobCompletedAt:          sql.NullTime{Valid: true, Time: dbtime.Now().Add(-30 * time.Minute)},
+			buildDeadlineOffset:     ptr.Ref(-30 * time.Minute),
+			workspaceTTL:            8 * time.Hour,
+			templateConnectionTypes: []string{"ssh"},
+			connectionTypes:         []codersdk.ActivityBumpConnectionType{codersdk.ActivityBumpConnectionTypeSSH},
+			expectedBump:            time.Hour,
+		},
+		{
+			// Web app activity does not bump the deadline if the template only
+			// allows SSH connections to bump it.
+			name:                    "TemplateConnectionTypeNotAllowed",
+			transition:              database.WorkspaceTransitionStart,
+			jobCompletedAt:          sql.NullTime{Valid: true, Time: dbtime.Now().Add(-30 * time.Minute)},
+			buildDeadlineOffset:     ptr.Ref(-30 * time.Minute),
+			workspaceTTL:            8 * time.Hour,
+			templateConnectionTypes: []string{"ssh"},
+			connectionTypes:         []codersdk.ActivityBumpConnectionType{codersdk.ActivityBumpConnectionTypeApp},
+			expectedBump:            0,
+		},
+		{
+			// The bump is capped by the daily maximum of the template.
+			name:                  "TemplateMaxBumpPerDay",
+			transition:            database.WorkspaceTransitionStart,
+			jobCompletedAt:        sql.NullTime{Valid: true, Time: dbtime.Now().Add(-30 * time.Minute)},
+			buildDeadlineOffset:   ptr.Ref(-30 * time.Minute),
+			workspaceTTL:          8 * time.Hour,
+			templateMaxBumpPerDay: 20 * time.Minute,
+			expectedBump:          20 * time.Minute,
+		},
 	} {
 		for _, tz := range timezones {
 			t.Run(tt.name+"/"+tz, func(t *testing.T) {
@@ -216,6 +253,9 @@ func Test_ActivityBumpWorkspace(t *testing.T) {
 					AllowUserAutostop: !tt.templateDisallowsUserAutostop,
 					DefaultTTL:        int64(tt.templateTTL),
 					ActivityBump:      int64(activityBump),
+					// The column is not nullable.
+					ActivityBumpConnectionTypes: append([]string{}, tt.templateConnectionTypes...),
+					ActivityBumpMaxPerDay:       int64(tt.templateMaxBumpPerDay),
 				}), "unexpected error updating template schedule")
 
 				var buildNumber int32 = 1
@@ -268,7 +308,7 @@ func Test_ActivityBumpWorkspace(t *testing.T) {
 
 				// Bump duration is measured from the time of the bump, so we measure from here.
 				start := dbtime.Now()
-				workspacestats.ActivityBumpWorkspace(ctx, log, db, bld.WorkspaceID, nextAutostart(start), workspacestats.ActivityBumpReasonWorkspaceStats)
+				workspacestats.ActivityBumpWorkspace(ctx, log, db, bld.WorkspaceID, nextAutostart(start), workspacestats.ActivityBumpReasonWorkspaceStats, tt.connectionTypes...)
 				end := dbtime.Now()
 
 				// Validate our state after bump
@@ -297,6 +337,64 @@ func Test_ActivityBumpWorkspace(t *testing.T) {
 	}
 }
 
+func Test_ActivityBumpWorkspace_MaxPerDay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = testutil.Context(t, testutil.WaitLong)
+		log   = testutil.Logger(t)
+		db, _ = dbtestutil.NewDB(t)
+		org   = dbgen.Organization(t, db, database.Organization{})
+		user  = dbgen.User(t, db, database.User{})
+		tv    = dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		template = dbgen.Template(t, db, database.Template{
+			OrganizationID:  org.ID,
+			ActiveVersionID: tv.ID,
+			CreatedBy:       user.ID,
+		})
+		ws = dbgen.Workspace(t, db, database.WorkspaceTable{
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+			TemplateID:     template.ID,
+			Ttl:            sql.NullInt64{Valid: true, Int64: int64(8 * time.Hour)},
+		})
+		job = dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			CompletedAt:    sql.NullTime{Valid: true, Time: dbtime.Now().Add(-30 * time.Minute)},
+		})
+	)
+	require.NoError(t, db.UpdateTemplateScheduleByID(ctx, database.UpdateTemplateScheduleByIDParams{
+		ID:                          template.ID,
+		UpdatedAt:                   dbtime.Now(),
+		AllowUserAutostop:           true,
+		ActivityBump:                int64(time.Hour),
+		ActivityBumpConnectionTypes: []string{},
+		ActivityBumpMaxPerDay:       int64(20 * time.Minute),
+	}))
+	bld := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+		WorkspaceID:       ws.ID,
+		JobID:             job.ID,
+		TemplateVersionID: tv.ID,
+		Transition:        database.WorkspaceTransitionStart,
+		Deadline:          dbtime.Now().Add(-30 * time.Minute),
+	})
+
+	// The first bump uses up the daily limit.
+	workspacestats.ActivityBumpWorkspace(ctx, log, db, ws.ID, time.Time{}, workspacestats.ActivityBumpReasonWorkspaceStats)
+	bumped, err := db.GetWorkspaceBuildByID(ctx, bld.ID)
+	require.NoError(t, err)
+	require.WithinDuration(t, dbtime.Now().Add(20*time.Minute), bumped.Deadline, time.Minute)
+
+	// Further activity on the same day does not bump the deadline.
+	workspacestats.ActivityBumpWorkspace(ctx, log, db, ws.ID, time.Time{}, workspacestats.ActivityBumpReasonWorkspaceStats)
+	notBumped, err := db.GetWorkspaceBuildByID(ctx, bld.ID)
+	require.NoError(t, err)
+	require.Equal(t, bumped.Deadline.UTC(), notBumped.Deadline.UTC())
+}
+
 func insertPrevWorkspaceBuild(t *testing.T, db database.Store, orgID, tvID, workspaceID uuid.UUID, transition database.WorkspaceTransition, buildNumber int32) {
 	t.Helper()
 
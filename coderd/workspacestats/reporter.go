@@ -12,6 +12,7 @@ import (
 	"cdr.dev/slog/v3"
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
@@ -19,6 +20,7 @@ import (
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 // TODO: There are currently two paths for reporting activity, both of which are
@@ -141,6 +143,7 @@ func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspac
 	// update agent stats
 	if !r.opts.DisableDatabaseInserts {
 		r.opts.StatsBatcher.Add(now, agentID, workspace.TemplateID, workspace.OwnerID, workspace.ID, stats, usage)
+		r.reportEgress(ctx, now, workspace, stats)
 	}
 
 	// update prometheus metrics (even if template insights are disabled)
@@ -194,7 +197,7 @@ func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspac
 		}
 
 		// bump workspace activity
-		ActivityBumpWorkspace(ctx, r.opts.Logger.Named("activity_bump"), r.opts.Database, workspace.ID, nextAutostart, ActivityBumpReasonWorkspaceStats)
+		ActivityBumpWorkspace(ctx, r.opts.Logger.Named("activity_bump"), r.opts.Database, workspace.ID, nextAutostart, ActivityBumpReasonWorkspaceStats, activityBumpConnectionTypes(stats)...)
 	}
 
 	// bump workspace last_used_at
@@ -217,6 +220,35 @@ func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspac
 	return nil
 }
 
+// reportEgress adds the per connection class byte counts of an agent stats
+// report to the workspace's daily egress total. Failures are logged rather
+// than returned, as egress accounting must not interfere with activity bumps.
+func (r *Reporter) reportEgress(ctx context.Context, now time.Time, workspace database.WorkspaceIdentity, stats *agentproto.Stats) {
+	if stats.RxBytesSsh == 0 && stats.TxBytesSsh == 0 &&
+		stats.RxBytesReconnectingPty == 0 && stats.TxBytesReconnectingPty == 0 &&
+		stats.RxBytesPortForward == 0 && stats.TxBytesPortForward == 0 {
+		return
+	}
+
+	//nolint:gocritic // Egress accounting is a system write on behalf of the agent.
+	err := r.opts.Database.UpsertWorkspaceEgressDaily(dbauthz.AsSystemRestricted(ctx), database.UpsertWorkspaceEgressDailyParams{
+		WorkspaceID:            workspace.ID,
+		TemplateID:             workspace.TemplateID,
+		OwnerID:                workspace.OwnerID,
+		Date:                   now.UTC().Truncate(24 * time.Hour),
+		RxBytesSsh:             stats.RxBytesSsh,
+		TxBytesSsh:             stats.TxBytesSsh,
+		RxBytesReconnectingPty: stats.RxBytesReconnectingPty,
+		TxBytesReconnectingPty: stats.TxBytesReconnectingPty,
+		RxBytesPortForward:     stats.RxBytesPortForward,
+		TxBytesPortForward:     stats.TxBytesPortForward,
+	})
+	if err != nil && !database.IsQueryCanceledError(err) {
+		r.opts.Logger.Warn(ctx, "failed to record workspace egress",
+			slog.F("workspace_id", workspace.ID), slog.Error(err))
+	}
+}
+
 type UpdateTemplateWorkspacesLastUsedAtFunc func(ctx context.Context, db database.Store, templateID uuid.UUID, lastUsedAt time.Time) error
 
 func UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, db database.Store, templateID uuid.UUID, lastUsedAt time.Time) error {
@@ -237,3 +269,23 @@ func (r *Reporter) TrackUsage(workspaceID uuid.UUID) {
 func (r *Reporter) Close() error {
 	return r.opts.UsageTracker.Close()
 }
+
+// activityBumpConnectionTypes returns the types of the sessions reported in
+// stats. Legacy stats only report a connection count, so their connections
+// are of no known type.
+func activityBumpConnectionTypes(stats *agentproto.Stats) []codersdk.ActivityBumpConnectionType {
+	var types []codersdk.ActivityBumpConnectionType
+	if stats.SessionCountSsh > 0 {
+		types = append(types, codersdk.ActivityBumpConnectionTypeSSH)
+	}
+	if stats.SessionCountVscode > 0 {
+		types = append(types, codersdk.ActivityBumpConnectionTypeVSCode)
+	}
+	if stats.SessionCountJetbrains > 0 {
+		types = append(types, codersdk.ActivityBumpConnectionTypeJetBrains)
+	}
+	if stats.SessionCountReconnectingPty > 0 {
+		types = append(types, codersdk.ActivityBumpConnectionTypeReconnectingPTY)
+	}
+	return types
+}
@@ -0,0 +1,254 @@
+package coderd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// supportBundleCollectTimeout bounds how long a single agent may take to
+// collect its bundle.
+const supportBundleCollectTimeout = 2 * time.Minute
+
+// @Summary Create workspace support bundle
+// @Description Asks the agents of the workspace to collect diagnostics
+// @Description (startup logs, network checks, listening ports, environment and
+// @Description disk usage) and stores them as a zip file that can be
+// @Description downloaded from the files API. Administrators are notified.
+// @ID create-workspace-support-bundle
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceSupportBundleRequest true "Create support bundle request"
+// @Success 201 {object} codersdk.WorkspaceSupportBundle
+// @Router /api/v2/workspaces/{workspace}/support-bundle [post]
+func (api *API) postWorkspaceSupportBundle(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+		apiKey    = httpmw.APIKey(r)
+	)
+
+	// The bundle exposes agent logs and environment, which is as much as
+	// connecting to the agent does.
+	if !api.Authorize(r, policy.ActionSSH, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateWorkspaceSupportBundleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(agents) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Workspace has no agents to collect a support bundle from.",
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	bundle := codersdk.WorkspaceSupportBundle{
+		CreatedAt: now,
+		Agents:    make([]codersdk.WorkspaceSupportBundleAgent, 0, len(agents)),
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, agent := range agents {
+		result := codersdk.WorkspaceSupportBundleAgent{
+			ID:   agent.ID,
+			Name: agent.Name,
+		}
+		data, err := api.collectAgentSupportBundle(ctx, agent)
+		if err == nil {
+			err = copyZipEntries(zw, "agents/"+agent.Name+"/", data)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Collected = true
+		}
+		bundle.Agents = append(bundle.Agents, result)
+	}
+
+	w, err := zw.Create("workspace.json")
+	if err == nil {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(map[string]any{
+			"workspace_id":   workspace.ID,
+			"workspace_name": workspace.Name,
+			"owner_username": workspace.OwnerUsername,
+			"template_name":  workspace.TemplateName,
+			"created_at":     now,
+			"agents":         bundle.Agents,
+		})
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating support bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	data := buf.Bytes()
+	hash := sha256.Sum256(data)
+	file, err := api.Database.InsertFile(ctx, database.InsertFileParams{
+		ID:        uuid.New(),
+		Hash:      hex.EncodeToString(hash[:]),
+		CreatedBy: apiKey.UserID,
+		CreatedAt: now,
+		Mimetype:  zipMimeType,
+		Data:      data,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error saving support bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	bundle.FileID = file.ID
+
+	api.notifySupportBundleCreated(ctx, workspace, apiKey.UserID, file.ID, req.Message)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, bundle)
+}
+
+// collectAgentSupportBundle dials the agent and returns its support bundle.
+func (api *API) collectAgentSupportBundle(ctx context.Context, agent database.WorkspaceAgent) ([]byte, error) {
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(),
+		*api.TailnetCoordinator.Load(),
+		agent,
+		nil,
+		nil,
+		nil,
+		api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("convert agent: %w", err)
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		return nil, xerrors.Errorf("agent state is %q, it must be in the %q state", apiAgent.Status, codersdk.WorkspaceAgentConnected)
+	}
+
+	// If the agent is unreachable, the request will hang. Assume that if
+	// we don't get a response after 30s that the agent is unreachable.
+	dialCtx, dialCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer dialCancel()
+	conn, release, err := api.agentProvider.AgentConn(dialCtx, agent.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("dial agent: %w", err)
+	}
+	defer release()
+
+	collectCtx, collectCancel := context.WithTimeout(ctx, supportBundleCollectTimeout)
+	defer collectCancel()
+	return conn.SupportBundle(collectCtx)
+}
+
+// copyZipEntries copies the entries of the zip archive in data into zw,
+// prefixing their names.
+func copyZipEntries(zw *zip.Writer, prefix string, data []byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return xerrors.Errorf("read agent bundle: %w", err)
+	}
+	for _, f := range zr.File {
+		if err := copyZipEntry(zw, prefix, f); err != nil {
+			return xerrors.Errorf("copy %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func copyZipEntry(zw *zip.Writer, prefix string, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	w, err := zw.Create(prefix + f.Name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+// notifySupportBundleCreated notifies owners about a new support bundle so
+// it can be picked up like a support ticket.
+func (api *API) notifySupportBundleCreated(ctx context.Context, workspace database.Workspace, initiatorID uuid.UUID, fileID uuid.UUID, message string) {
+	initiator, err := api.Database.GetUserByID(ctx, initiatorID)
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to fetch initiator for support bundle notification", slog.F("initiator_id", initiatorID), slog.Error(err))
+		return
+	}
+
+	//nolint:gocritic // Members creating a bundle cannot list the owners.
+	owners, err := api.Database.GetUsers(dbauthz.AsSystemRestricted(ctx), database.GetUsersParams{
+		RbacRole: []string{codersdk.RoleOwner},
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to fetch owners for support bundle notification", slog.Error(err))
+		return
+	}
+
+	for _, owner := range owners {
+		// Don't send notification to user which initiated the event.
+		if owner.ID == initiatorID {
+			continue
+		}
+		// nolint:gocritic // Need notifier actor to enqueue notifications
+		if _, err := api.NotificationsEnqueuer.Enqueue(dbauthz.AsNotifier(ctx), owner.ID, notifications.TemplateWorkspaceSupportBundle,
+			map[string]string{
+				"workspace":                workspace.Name,
+				"workspace_owner_username": workspace.OwnerUsername,
+				"initiator":                initiator.Username,
+				"message":                  message,
+				"file_id":                  fileID.String(),
+			}, "api-workspaces-support-bundle",
+			// Associate this notification with all the related entities.
+			workspace.ID, workspace.OwnerID, workspace.TemplateID, workspace.OrganizationID,
+		); err != nil {
+			api.Logger.Warn(ctx, "failed to notify of support bundle", slog.F("workspace_id", workspace.ID), slog.F("owner_id", owner.ID), slog.Error(err))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package coderd_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSupportBundle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		notifyEnq  = &notificationstest.FakeEnqueuer{}
+		client, db = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			NotificationsEnqueuer: notifyEnq,
+		})
+		owner        = coderdtest.CreateFirstUser(t, client)
+		member, user = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		r            = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        user.ID,
+		}).WithAgent().Do()
+	)
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.NewWorkspaceAgentWaiter(t, member, r.Workspace.ID).Wait()
+	agent := resources[0].Agents[0]
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	bundle, err := member.CreateWorkspaceSupportBundle(ctx, r.Workspace.ID, codersdk.CreateWorkspaceSupportBundleRequest{
+		Message: "The startup script hangs.",
+	})
+	require.NoError(t, err)
+	require.Len(t, bundle.Agents, 1)
+	require.Equal(t, agent.ID, bundle.Agents[0].ID)
+	require.True(t, bundle.Agents[0].Collected, bundle.Agents[0].Error)
+
+	data, contentType, err := member.Download(ctx, bundle.FileID)
+	require.NoError(t, err)
+	require.Equal(t, "application/zip", contentType)
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	prefix := "agents/" + agent.Name + "/"
+	require.Contains(t, names, "workspace.json")
+	require.Contains(t, names, prefix+"manifest.json")
+	require.Contains(t, names, prefix+"environment.json")
+
+	// The owner can download the bundle from the notification.
+	sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceSupportBundle))
+	require.Len(t, sent, 1)
+	require.Equal(t, owner.UserID, sent[0].UserID)
+	require.Equal(t, bundle.FileID.String(), sent[0].Labels["file_id"])
+	require.Equal(t, "The startup script hangs.", sent[0].Labels["message"])
+	_, _, err = client.Download(ctx, bundle.FileID)
+	require.NoError(t, err)
+}
@@ -2,6 +2,7 @@ package coderd
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"sync"
 
@@ -24,6 +25,7 @@ type UpdatesQuerier interface {
 	// GetAuthorizedWorkspacesAndAgentsByOwnerID requires a context with an actor set
 	GetWorkspacesAndAgentsByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.GetWorkspacesAndAgentsByOwnerIDRow, error)
 	GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (database.Workspace, error)
+	GetWorkspacePortForwardACL(ctx context.Context, arg database.GetWorkspacePortForwardACLParams) (database.WorkspacePortForwardACL, error)
 }
 
 type workspacesByID = map[uuid.UUID]ownedWorkspace
@@ -297,6 +299,7 @@ func convertRows(rows []database.GetWorkspacesAndAgentsByOwnerIDRow) workspacesB
 type rbacAuthorizer struct {
 	sshPrep rbac.PreparedAuthorized
 	db      UpdatesQuerier
+	userID  uuid.UUID
 }
 
 func (r *rbacAuthorizer) AuthorizeTunnel(ctx context.Context, agentID uuid.UUID) error {
@@ -305,7 +308,28 @@ func (r *rbacAuthorizer) AuthorizeTunnel(ctx context.Context, agentID uuid.UUID)
 		return xerrors.Errorf("get workspace by agent ID: %w", err)
 	}
 	// Authorizes against `ActionSSH`
-	return r.sshPrep.Authorize(ctx, ws.RBACObject())
+	if err := r.sshPrep.Authorize(ctx, ws.RBACObject()); err != nil {
+		return err
+	}
+	if ws.OwnerID == r.userID {
+		return nil
+	}
+	// The allowed ports of a client apply to all of its tunnels, so users
+	// restricted to some ports of a workspace must coordinate with its agent
+	// alone.
+	// nolint:gocritic // The restrictions of the user may not be readable by
+	// the user.
+	_, err = r.db.GetWorkspacePortForwardACL(dbauthz.AsSystemRestricted(ctx), database.GetWorkspacePortForwardACLParams{
+		WorkspaceID: ws.ID,
+		UserID:      r.userID,
+	})
+	if err == nil {
+		return xerrors.New("user may only connect to some ports of the workspace")
+	}
+	if !xerrors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get workspace port-forward ACL: %w", err)
+	}
+	return nil
 }
 
 var _ tailnet.TunnelAuthorizer = (*rbacAuthorizer)(nil)
@@ -2,6 +2,7 @@ package coderd_test
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"slices"
 	"strings"
@@ -324,6 +325,11 @@ func (*mockWorkspaceStore) GetWorkspaceByAgentID(context.Context, uuid.UUID) (da
 	return database.Workspace{}, nil
 }
 
+// GetWorkspacePortForwardACL implements coderd.UpdatesQuerier.
+func (*mockWorkspaceStore) GetWorkspacePortForwardACL(context.Context, database.GetWorkspacePortForwardACLParams) (database.WorkspacePortForwardACL, error) {
+	return database.WorkspacePortForwardACL{}, sql.ErrNoRows
+}
+
 var _ coderd.UpdatesQuerier = (*mockWorkspaceStore)(nil)
 
 type mockPubsub struct {
@@ -9,6 +9,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,14 +25,17 @@ import (
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/deprecation"
 	"github.com/coder/coder/v2/coderd/dynamicparameters"
 	"github.com/coder/coder/v2/coderd/files"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpapi/httperror"
 	"github.com/coder/coder/v2/coderd/prebuilds"
+	"github.com/coder/coder/v2/coderd/presetcohort"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/templaterollout"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/util/slice"
@@ -64,6 +70,7 @@ type Builder struct {
 	initiator               uuid.UUID
 	reason                  database.BuildReason
 	templateVersionPresetID uuid.UUID
+	targetResources         []string
 
 	// used during build, makes function arguments less verbose
 	ctx       context.Context
@@ -195,6 +202,15 @@ func (b Builder) Experiments(exp codersdk.Experiments) Builder {
 	return b
 }
 
+// TargetResources limits the build to the given Terraform resource addresses,
+// which are replaced while every other resource keeps its state. It is only
+// valid for start builds of templates that allow targeted builds.
+func (b Builder) TargetResources(addrs []string) Builder {
+	// nolint: revive
+	b.targetResources = slices.Clone(addrs)
+	return b
+}
+
 func (b Builder) Initiator(u uuid.UUID) Builder {
 	// nolint: revive
 	b.initiator = u
@@ -378,7 +394,11 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 			return nil, nil, nil, err
 		}
 	}
-	err := b.checkTemplateVersionMatchesTemplate()
+	err := b.checkReadOnly()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = b.checkTemplateVersionMatchesTemplate()
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -394,6 +414,22 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	err = b.checkTargetResources()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = b.checkParameterReconfirmation()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = b.checkTemplateVersionDeprecation()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = b.checkPresetCohort()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	template, err := b.getTemplate()
 	if err != nil {
@@ -421,6 +457,7 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 		WorkspaceBuildID:            workspaceBuildID,
 		LogLevel:                    b.logLevel,
 		PrebuiltWorkspaceBuildStage: b.prebuiltWorkspaceBuildStage,
+		TargetResources:             b.targetResources,
 	})
 	if err != nil {
 		return nil, nil, nil, BuildError{
@@ -551,15 +588,36 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 			}
 		}
 
+		sensitive, err := b.getSensitiveParameters()
+		if err != nil {
+			return BuildError{http.StatusInternalServerError, "get sensitive parameters", err}
+		}
+		sensitiveValues := make([]bool, len(names))
+		for i, name := range names {
+			sensitiveValues[i] = sensitive[name]
+		}
 		err = store.InsertWorkspaceBuildParameters(b.ctx, database.InsertWorkspaceBuildParametersParams{
 			WorkspaceBuildID: workspaceBuildID,
 			Name:             names,
 			Value:            values,
+			Sensitive:        sensitiveValues,
 		})
 		if err != nil {
 			return BuildError{http.StatusInternalServerError, "insert workspace build parameters: %w", err}
 		}
 
+		changes, err := b.getParameterChanges(names, values)
+		if err != nil {
+			return BuildError{http.StatusInternalServerError, "compute workspace build parameter changes", err}
+		}
+		if len(changes.Name) > 0 {
+			changes.WorkspaceBuildID = workspaceBuildID
+			err = store.InsertWorkspaceBuildParameterChanges(b.ctx, changes)
+			if err != nil {
+				return BuildError{http.StatusInternalServerError, "insert workspace build parameter changes", err}
+			}
+		}
+
 		workspaceBuild, err = store.GetWorkspaceBuildByID(b.ctx, workspaceBuildID)
 		if err != nil {
 			return BuildError{http.StatusInternalServerError, "get workspace build", err}
@@ -680,7 +738,20 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 		if err != nil {
 			return uuid.Nil, xerrors.Errorf("get template so we can get active version: %w", err)
 		}
-		return t.ActiveVersionID, nil
+		// New workspaces are always built from the active version, since
+		// their parameters and presets were validated against it.
+		first, err := b.firstBuild()
+		if err != nil {
+			return uuid.Nil, xerrors.Errorf("check if first build: %w", err)
+		}
+		if first {
+			return t.ActiveVersionID, nil
+		}
+		id, err := templaterollout.VersionForWorkspace(b.ctx, b.store, *t, b.workspace)
+		if err != nil {
+			return uuid.Nil, xerrors.Errorf("get version of template version rollout: %w", err)
+		}
+		return id, nil
 	}
 	// default is prior version
 	bld, err := b.getLastBuild()
@@ -995,6 +1066,86 @@ func (b *Builder) getLastBuildParameters() ([]database.WorkspaceBuildParameter,
 	return values, nil
 }
 
+// getParameterChanges diffs the parameters of the build being created against
+// the previous build. Values of ephemeral parameters are not recorded, since
+// they are only meant to apply to a single build, and neither are values of
+// sensitive parameters. The first build of a workspace has no changes.
+func (b *Builder) getParameterChanges(names, values []string) (database.InsertWorkspaceBuildParameterChangesParams, error) {
+	var changes database.InsertWorkspaceBuildParameterChangesParams
+	firstBuild, err := b.firstBuild()
+	if err != nil || firstBuild {
+		return changes, err
+	}
+	lastBuildParameters, err := b.getLastBuildParameters()
+	if err != nil {
+		return changes, err
+	}
+
+	sensitive, err := b.getSensitiveParameters()
+	if err != nil {
+		return changes, err
+	}
+	previous := make(map[string]string, len(lastBuildParameters))
+	for _, p := range lastBuildParameters {
+		previous[p.Name] = p.Value
+		if p.Sensitive {
+			sensitive[p.Name] = true
+		}
+	}
+	current := make(map[string]string, len(names))
+	for i, name := range names {
+		current[name] = values[i]
+	}
+
+	type change struct {
+		name, previousValue, newValue string
+		kind                          database.WorkspaceBuildParameterChangeKind
+	}
+	var diff []change
+	for name, value := range current {
+		previousValue, ok := previous[name]
+		switch {
+		case !ok:
+			diff = append(diff, change{name: name, newValue: value, kind: database.WorkspaceBuildParameterChangeKindAdded})
+		case previousValue != value:
+			diff = append(diff, change{name: name, previousValue: previousValue, newValue: value, kind: database.WorkspaceBuildParameterChangeKindModified})
+		}
+	}
+	for name, value := range previous {
+		if _, ok := current[name]; !ok {
+			diff = append(diff, change{name: name, previousValue: value, kind: database.WorkspaceBuildParameterChangeKindRemoved})
+		}
+	}
+	if len(diff) == 0 {
+		return changes, nil
+	}
+	slices.SortFunc(diff, func(a, b change) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	tvp, err := b.getTemplateVersionParameters()
+	if err != nil {
+		return changes, err
+	}
+	ephemeral := make(map[string]bool, len(tvp))
+	for _, p := range tvp {
+		ephemeral[p.Name] = p.Ephemeral
+	}
+
+	for _, c := range diff {
+		redacted := ephemeral[c.name] || sensitive[c.name]
+		if redacted {
+			c.previousValue, c.newValue = "", ""
+		}
+		changes.Name = append(changes.Name, c.name)
+		changes.Kind = append(changes.Kind, c.kind)
+		changes.PreviousValue = append(changes.PreviousValue, c.previousValue)
+		changes.NewValue = append(changes.NewValue, c.newValue)
+		changes.Redacted = append(changes.Redacted, redacted)
+	}
+	return changes, nil
+}
+
 func (b *Builder) getTemplateVersionParameters() ([]previewtypes.Parameter, error) {
 	if b.templateVersionParameters != nil {
 		return *b.templateVersionParameters, nil
@@ -1011,6 +1162,22 @@ func (b *Builder) getTemplateVersionParameters() ([]previewtypes.Parameter, erro
 	return *b.templateVersionParameters, nil
 }
 
+// getSensitiveParameters returns the names of the template version parameters
+// whose values are sensitive.
+func (b *Builder) getSensitiveParameters() (map[string]bool, error) {
+	tvp, err := b.getTemplateVersionParameters()
+	if err != nil {
+		return nil, err
+	}
+	sensitive := make(map[string]bool)
+	for _, p := range tvp {
+		if ptr.NilToEmpty(p.Styling.MaskInput) {
+			sensitive[p.Name] = true
+		}
+	}
+	return sensitive, nil
+}
+
 func (b *Builder) getTemplateVersionVariables() ([]database.TemplateVersionVariable, error) {
 	if b.templateVersionVariables != nil {
 		return *b.templateVersionVariables, nil
@@ -1309,7 +1476,10 @@ func (b *Builder) authorize(authFunc func(action policy.Action, object rbac.Obje
 		}
 	}
 
-	if b.logLevel != "" && !authFunc(policy.ActionRead, rbac.ResourceDeploymentConfig) {
+	// Debug logs may contain secrets of the template, so only deployment
+	// administrators may request them. Reading the deployment config isn't
+	// enough, since auditors can do that.
+	if b.logLevel != "" && !authFunc(policy.ActionUpdate, rbac.ResourceDeploymentConfig) {
 		return BuildError{
 			http.StatusBadRequest,
 			"Workspace builds with a custom log level are restricted to administrators only.",
@@ -1433,6 +1603,184 @@ func (b *Builder) checkRunningBuild() error {
 	return nil
 }
 
+// targetResourceAddressRegex matches Terraform managed resource addresses,
+// optionally inside modules and with a count or for_each index.
+var targetResourceAddressRegex = regexp.MustCompile(`^(module\.[A-Za-z_][\w-]*(\[(\d+|"[^"]+")\])?\.)*[A-Za-z_][\w-]*\.[A-Za-z_][\w-]*(\[(\d+|"[^"]+")\])?$`)
+
+func (b *Builder) checkTargetResources() error {
+	if len(b.targetResources) == 0 {
+		return nil
+	}
+	if b.trans != database.WorkspaceTransitionStart {
+		msg := "Target resources can only be set for start builds."
+		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+	}
+	template, err := b.getTemplate()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template", err}
+	}
+	if !template.AllowTargetedBuilds {
+		msg := "The template does not allow targeted builds."
+		return BuildError{http.StatusForbidden, msg, xerrors.New(msg)}
+	}
+	first, err := b.firstBuild()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to check existing workspace builds", err}
+	}
+	if first {
+		msg := "Target resources require an existing build of the workspace."
+		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+	}
+	for _, addr := range b.targetResources {
+		if strings.HasPrefix(addr, "data.") || !targetResourceAddressRegex.MatchString(addr) {
+			msg := fmt.Sprintf("Invalid target resource %q. Must be the address of a managed Terraform resource, e.g. \"docker_container.workspace[0]\".", addr)
+			return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+		}
+	}
+	return nil
+}
+
+// checkParameterReconfirmation enforces the template's ReconfirmParameters: when
+// the build moves the workspace to a different template version, every listed
+// parameter that has a value from a previous build must be set explicitly in
+// the build request or by the preset. Otherwise the stale value would be
+// carried over silently.
+func (b *Builder) checkParameterReconfirmation() error {
+	template, err := b.getTemplate()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template", err}
+	}
+	if len(template.ReconfirmParameters) == 0 {
+		return nil
+	}
+	first, err := b.firstBuild()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to check existing workspace builds", err}
+	}
+	if first {
+		return nil
+	}
+	lastBuild, err := b.getLastBuild()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch last build", err}
+	}
+	templateVersionID, err := b.getTemplateVersionID()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to get template version ID", err}
+	}
+	if templateVersionID == lastBuild.TemplateVersionID {
+		return nil
+	}
+
+	templateVersionParameters, err := b.getTemplateVersionParameters()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template version parameters", err}
+	}
+	lastBuildParameters, err := b.getLastBuildParameters()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch last build parameters", err}
+	}
+	presetParameterValues, err := b.getPresetParameterValues()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch preset parameter values", err}
+	}
+
+	for _, name := range template.ReconfirmParameters {
+		inVersion := slices.ContainsFunc(templateVersionParameters, func(p previewtypes.Parameter) bool {
+			return p.Name == name
+		})
+		stored := slices.ContainsFunc(lastBuildParameters, func(p database.WorkspaceBuildParameter) bool {
+			return p.Name == name
+		})
+		if !inVersion || !stored {
+			continue
+		}
+		if b.findNewBuildParameterValue(name, presetParameterValues) != nil {
+			continue
+		}
+		msg := fmt.Sprintf("Parameter %q must be re-confirmed when updating the workspace to a new template version.", name)
+		return BuildError{http.StatusBadRequest, msg, errors.Join(ErrParameterValidation, xerrors.New(msg))}
+	}
+	return nil
+}
+
+// checkTemplateVersionDeprecation rejects start builds that move the workspace
+// onto a deprecated template version once its deprecation cutoff has passed.
+// Restarts on the current version and stop or delete builds are not affected,
+// so existing workspaces keep working until they are updated.
+// checkReadOnly rejects all builds of a workspace in read-only mode. The
+// workspace is being investigated, so its resources must be left as they are,
+// including by deletions that would destroy evidence.
+func (b *Builder) checkReadOnly() error {
+	if !b.workspace.ReadOnlyAt.Valid {
+		return nil
+	}
+	msg := fmt.Sprintf("Cannot %s a workspace in read-only mode.", b.trans)
+	return BuildError{http.StatusConflict, msg, xerrors.New(msg)}
+}
+
+func (b *Builder) checkTemplateVersionDeprecation() error {
+	if b.trans != database.WorkspaceTransitionStart {
+		return nil
+	}
+	templateVersion, err := b.getTemplateVersion()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template version", err}
+	}
+	if !deprecation.Blocked(templateVersion.Deprecated, templateVersion.DeprecationCutoff, dbtime.Now()) {
+		return nil
+	}
+	first, err := b.firstBuild()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to check existing workspace builds", err}
+	}
+	if !first {
+		lastBuild, err := b.getLastBuild()
+		if err != nil {
+			return BuildError{http.StatusInternalServerError, "failed to fetch last build", err}
+		}
+		if lastBuild.TemplateVersionID == templateVersion.ID {
+			return nil
+		}
+	}
+	msg := fmt.Sprintf("Template version %q has been deprecated and can no longer be used for new builds: %s", templateVersion.Name, templateVersion.Deprecated)
+	return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+}
+
+// checkPresetCohort rejects start builds that use a preset targeted at a
+// cohort the workspace owner is not part of. Presets matched from the
+// parameter values are not checked, and neither are prebuilds, which have no
+// owner yet; the owner that claims one is checked instead.
+func (b *Builder) checkPresetCohort() error {
+	if b.trans != database.WorkspaceTransitionStart || b.templateVersionPresetID == uuid.Nil {
+		return nil
+	}
+	if b.prebuiltWorkspaceBuildStage == sdkproto.PrebuiltWorkspaceBuildStage_CREATE {
+		return nil
+	}
+	preset, err := b.store.GetPresetByID(b.ctx, b.templateVersionPresetID)
+	if err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			// The preset is validated when the build is inserted.
+			return nil
+		}
+		return BuildError{http.StatusInternalServerError, "failed to fetch preset", err}
+	}
+	template, err := b.getTemplate()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template", err}
+	}
+	available, err := presetcohort.Filter(b.ctx, b.store, template.ID, template.OrganizationID, b.workspace.OwnerID)
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to check preset cohort", err}
+	}
+	if available(preset.Name) {
+		return nil
+	}
+	msg := fmt.Sprintf("Preset %q is not available to the owner of the workspace.", preset.Name)
+	return BuildError{http.StatusForbidden, msg, xerrors.New(msg)}
+}
+
 func (b *Builder) usingDynamicParameters() bool {
 	tpl, err := b.getTemplate()
 	if err != nil {
@@ -480,6 +480,13 @@ func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
 			withInTx,
 			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			expectBuildParameterChanges(func(params database.InsertWorkspaceBuildParameterChangesParams) {
+				asrt.Equal([]string{secondParameterName}, params.Name)
+				asrt.Equal([]database.WorkspaceBuildParameterChangeKind{database.WorkspaceBuildParameterChangeKindModified}, params.Kind)
+				asrt.Equal([]string{secondParameterValue}, params.PreviousValue)
+				asrt.Equal([]string{updatedParameterValue}, params.NewValue)
+				asrt.Equal([]bool{false}, params.Redacted)
+			}),
 			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
 				asrt.Len(params.Name, len(expectedParams))
 				for i := range params.Name {
@@ -854,6 +861,7 @@ func TestWorkspaceBuildWithPreset(t *testing.T) {
 		// is tested at the API layer, in TestWorkspace. Here, it is sufficient to
 		// test that the preset is used when provided.
 		withTemplateVersionPresetParameters(presetID, nil),
+		withPresetCohorts(presetID, nil),
 		withLastBuildNotFound,
 		withTemplateVersionVariables(activeVersionID, nil),
 		withParameterSchemas(activeJobID, nil),
@@ -1379,6 +1387,17 @@ func withTemplateVersionPresetParameters(presetID uuid.UUID, params []database.T
 	}
 }
 
+func withPresetCohorts(presetID uuid.UUID, cohorts []database.TemplatePresetCohort) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetPresetByID(gomock.Any(), presetID).
+			Times(1).
+			Return(database.GetPresetByIDRow{ID: presetID, TemplateVersionID: activeVersionID, Name: "preset"}, nil)
+		mTx.EXPECT().GetTemplatePresetCohortsByTemplateID(gomock.Any(), templateID).
+			Times(1).
+			Return(cohorts, nil)
+	}
+}
+
 func withLastBuildFound(mTx *dbmock.MockStore) {
 	mTx.EXPECT().GetLatestWorkspaceBuildByWorkspaceID(gomock.Any(), workspaceID).
 		Times(1).
@@ -1573,7 +1592,7 @@ func expectBuild(
 }
 
 // expectBuildParameters captures a call to InsertWorkspaceBuildParameters and runs the provided assertions
-// against it.
+// against it. Any parameter changes against the previous build are accepted without assertions.
 func expectBuildParameters(
 	assertions func(database.InsertWorkspaceBuildParametersParams),
 ) func(mTx *dbmock.MockStore) {
@@ -1586,6 +1605,26 @@ func expectBuildParameters(
 					return nil
 				},
 			)
+		mTx.EXPECT().InsertWorkspaceBuildParameterChanges(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return(nil)
+	}
+}
+
+// expectBuildParameterChanges captures a call to InsertWorkspaceBuildParameterChanges and runs the provided
+// assertions against it. It must come before expectBuildParameters, which accepts any changes.
+func expectBuildParameterChanges(
+	assertions func(database.InsertWorkspaceBuildParameterChangesParams),
+) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().InsertWorkspaceBuildParameterChanges(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(
+				func(ctx context.Context, params database.InsertWorkspaceBuildParameterChangesParams) error {
+					assertions(params)
+					return nil
+				},
+			)
 	}
 }
 
@@ -125,6 +125,16 @@ const (
 	WorkspaceEventKindAgentLogsOverflow     WorkspaceEventKind = "agt_logs_overflow"
 	WorkspaceEventKindAgentTimeout          WorkspaceEventKind = "agt_timeout"
 	WorkspaceEventKindAgentAppStatusUpdate  WorkspaceEventKind = "agt_app_status_update"
+	WorkspaceEventKindAgentBootstrapUpdate  WorkspaceEventKind = "agt_bootstrap_update"
+	// WorkspaceEventKindAgentNetworkPolicyViolation is published when an
+	// agent reports blocked outbound connections, which affect its health.
+	WorkspaceEventKindAgentNetworkPolicyViolation WorkspaceEventKind = "agt_network_policy_violation"
+	// WorkspaceEventKindAgentMemoryEvent is published when an agent reports
+	// OOM kills or severe memory pressure, which affect its health.
+	WorkspaceEventKindAgentMemoryEvent WorkspaceEventKind = "agt_memory_event"
+	// WorkspaceEventKindAgentServiceUpdate is published when an agent reports
+	// a status transition of a service, which may affect its health.
+	WorkspaceEventKindAgentServiceUpdate WorkspaceEventKind = "agt_service_update"
 )
 
 func (w *WorkspaceEvent) Validate() error {
@@ -0,0 +1,17 @@
+package codersdk
+
+// AgentRolloutChannel is the channel workspace agents download their binary
+// from when the workspace starts.
+type AgentRolloutChannel string
+
+const (
+	// AgentRolloutChannelStable serves the agent bundled with the server.
+	AgentRolloutChannelStable AgentRolloutChannel = "stable"
+	// AgentRolloutChannelBeta serves the agent from the deployment's beta
+	// binaries directory to the configured percentage of workspaces.
+	AgentRolloutChannelBeta AgentRolloutChannel = "beta"
+)
+
+// AgentRolloutChannelHeader is set on agent binary downloads to the channel
+// the binary was served from.
+const AgentRolloutChannelHeader = "X-Coder-Agent-Rollout-Channel"
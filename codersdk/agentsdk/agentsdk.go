@@ -88,6 +88,61 @@ func (c *Client) GitSSHKey(ctx context.Context) (GitSSHKey, error) {
 	return gitSSHKey, json.NewDecoder(res.Body).Decode(&gitSSHKey)
 }
 
+type PostSSHHostCertificateRequest struct {
+	// PublicKey is the agent's SSH host public key in authorized_keys format.
+	PublicKey string `json:"public_key"`
+}
+
+type SSHHostCertificateResponse struct {
+	// Certificate is the signed host certificate in authorized_keys format.
+	Certificate string    `json:"certificate"`
+	ExpiresAt   time.Time `json:"expires_at" format:"date-time"`
+}
+
+// PostSSHHostCertificate asks coderd to sign the agent's SSH host key so that
+// clients trusting the deployment's SSH host CA can verify it.
+func (c *Client) PostSSHHostCertificate(ctx context.Context, req PostSSHHostCertificateRequest) (SSHHostCertificateResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/ssh-host-certificate", req)
+	if err != nil {
+		return SSHHostCertificateResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return SSHHostCertificateResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp SSHHostCertificateResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+type ScriptSecretsRequest struct {
+	ScriptID uuid.UUID `json:"script_id" format:"uuid"`
+}
+
+type ScriptSecretsResponse struct {
+	// Env maps environment variable names to the values of the template
+	// secrets referenced by the script.
+	Env map[string]string `json:"env"`
+}
+
+// ScriptSecrets fetches the template secrets referenced by a script so that
+// they can be injected into the environment of the script process.
+func (c *Client) ScriptSecrets(ctx context.Context, req ScriptSecretsRequest) (ScriptSecretsResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/script-secrets", req)
+	if err != nil {
+		return ScriptSecretsResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ScriptSecretsResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp ScriptSecretsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 type Metadata struct {
 	Key string `json:"key"`
 	codersdk.WorkspaceAgentMetadataResult
@@ -135,6 +190,11 @@ type Manifest struct {
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
 	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
 	Devcontainers            []codersdk.WorkspaceAgentDevcontainer        `json:"devcontainers"`
+	// ReadOnly is true for workspaces placed in read-only mode during an
+	// investigation. Sessions started by the agent block outbound git pushes.
+	ReadOnly bool `json:"read_only"`
+	// Services only carry the definition of each service, not its status.
+	Services []codersdk.WorkspaceAgentService `json:"services"`
 }
 
 // WorkspaceSecret is a user secret for injection into a workspace.
@@ -362,6 +422,32 @@ func (c *Client) ConnectRPC210WithRole(ctx context.Context, role string) (
 	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
 }
 
+// ConnectRPC211 returns a dRPC client to the Agent API v2.11. It is useful when
+// you want to be maximally compatible with newer Coderd Release Versions that
+// restrict shared workspace clients to some ports.
+func (c *Client) ConnectRPC211(ctx context.Context) (
+	proto.DRPCAgentClient211, tailnetproto.DRPCTailnetClient28, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 11), "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
+// ConnectRPC211WithRole is like ConnectRPC211 but sends an explicit role
+// query parameter to the server. Use "agent" for workspace agents to
+// enable connection monitoring.
+func (c *Client) ConnectRPC211WithRole(ctx context.Context, role string) (
+	proto.DRPCAgentClient211, tailnetproto.DRPCTailnetClient28, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 11), role)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
 // ConnectRPC connects to the workspace agent API and tailnet API.
 // It does not send a role query parameter, so the server will apply
 // its default behavior (currently: enable connection monitoring for
@@ -719,6 +805,98 @@ func (c *Client) PostLogSource(ctx context.Context, req PostLogSourceRequest) (c
 	return logSource, json.NewDecoder(res.Body).Decode(&logSource)
 }
 
+// PostBootstrapProgressRequest reports a bootstrap milestone of the workspace.
+type PostBootstrapProgressRequest struct {
+	Name string `json:"name" validate:"required"`
+	// Percent must be between 0 and 100 when set.
+	Percent *int32 `json:"percent,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// PostBootstrapProgress records a bootstrap milestone of the workspace. It is
+// intended to be called from startup scripts.
+func (c *Client) PostBootstrapProgress(ctx context.Context, req PostBootstrapProgressRequest) (codersdk.WorkspaceAgentBootstrapProgress, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/bootstrap-progress", req)
+	if err != nil {
+		return codersdk.WorkspaceAgentBootstrapProgress{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return codersdk.WorkspaceAgentBootstrapProgress{}, codersdk.ReadBodyAsError(res)
+	}
+	var progress codersdk.WorkspaceAgentBootstrapProgress
+	return progress, json.NewDecoder(res.Body).Decode(&progress)
+}
+
+// NetworkPolicy returns the effective outbound network policy of the
+// workspace. Enforcement agents running alongside the workspace agent use it
+// to configure egress filtering.
+func (c *Client) NetworkPolicy(ctx context.Context) (codersdk.WorkspaceNetworkPolicy, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/network-policy", nil)
+	if err != nil {
+		return codersdk.WorkspaceNetworkPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.WorkspaceNetworkPolicy{}, codersdk.ReadBodyAsError(res)
+	}
+	var np codersdk.WorkspaceNetworkPolicy
+	return np, json.NewDecoder(res.Body).Decode(&np)
+}
+
+// NetworkPolicyViolation is an outbound connection that was blocked because
+// the destination is not allowed by the workspace network policy.
+type NetworkPolicyViolation struct {
+	Destination string `json:"destination" validate:"required"`
+	Port        int32  `json:"port,omitempty"`
+	// Count is the number of blocked connections. Defaults to 1.
+	Count int32 `json:"count,omitempty"`
+}
+
+// PostNetworkPolicyViolationsRequest reports blocked outbound connections.
+type PostNetworkPolicyViolationsRequest struct {
+	Violations []NetworkPolicyViolation `json:"violations"`
+}
+
+// PostNetworkPolicyViolations reports blocked outbound connections. Recent
+// violations mark the agent as unhealthy.
+func (c *Client) PostNetworkPolicyViolations(ctx context.Context, req PostNetworkPolicyViolationsRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/network-policy-violations", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// PostServiceStatusRequest reports a status transition of a service
+// supervised by the agent.
+type PostServiceStatusRequest struct {
+	Status  codersdk.WorkspaceAgentServiceStatus `json:"status" validate:"required"`
+	Message string                               `json:"message,omitempty"`
+	// ExitCode is set when the transition was caused by the process of the
+	// service exiting.
+	ExitCode     *int32 `json:"exit_code,omitempty"`
+	RestartCount int32  `json:"restart_count"`
+}
+
+// PostServiceStatus reports a status transition of the service with the given
+// name.
+func (c *Client) PostServiceStatus(ctx context.Context, name string, req PostServiceStatusRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/me/services/%s/status", url.PathEscape(name)), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 type ExternalAuthResponse struct {
 	AccessToken string                 `json:"access_token"`
 	TokenExtra  map[string]interface{} `json:"token_extra"`
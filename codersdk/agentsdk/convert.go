@@ -48,6 +48,10 @@ func ManifestFromProto(manifest *proto.Manifest) (Manifest, error) {
 	if err != nil {
 		return Manifest{}, xerrors.Errorf("error converting workspace agent devcontainers: %w", err)
 	}
+	services, err := ServicesFromProto(manifest.Services)
+	if err != nil {
+		return Manifest{}, xerrors.Errorf("error converting workspace agent services: %w", err)
+	}
 	return Manifest{
 		ParentID:                 parentID,
 		AgentID:                  agentID,
@@ -67,6 +71,8 @@ func ManifestFromProto(manifest *proto.Manifest) (Manifest, error) {
 		DisableDirectConnections: manifest.DisableDirectConnections,
 		Metadata:                 MetadataDescriptionsFromProto(manifest.Metadata),
 		Devcontainers:            devcontainers,
+		ReadOnly:                 manifest.ReadOnly,
+		Services:                 services,
 	}, nil
 }
 
@@ -98,6 +104,8 @@ func ProtoFromManifest(manifest Manifest) (*proto.Manifest, error) {
 		Apps:                     apps,
 		Metadata:                 ProtoFromMetadataDescriptions(manifest.Metadata),
 		Devcontainers:            ProtoFromDevcontainers(manifest.Devcontainers),
+		ReadOnly:                 manifest.ReadOnly,
+		Services:                 ProtoFromServices(manifest.Services),
 	}, nil
 }
 
@@ -473,6 +481,40 @@ func ProtoFromDevcontainer(dc codersdk.WorkspaceAgentDevcontainer) *proto.Worksp
 	}
 }
 
+func ServicesFromProto(pss []*proto.WorkspaceAgentService) ([]codersdk.WorkspaceAgentService, error) {
+	ret := make([]codersdk.WorkspaceAgentService, len(pss))
+	for i, ps := range pss {
+		restartPolicy := codersdk.WorkspaceAgentServiceRestartPolicy(ps.RestartPolicy)
+		switch restartPolicy {
+		case codersdk.WorkspaceAgentServiceRestartPolicyAlways,
+			codersdk.WorkspaceAgentServiceRestartPolicyOnFailure,
+			codersdk.WorkspaceAgentServiceRestartPolicyNever:
+		default:
+			return nil, xerrors.Errorf("parse service %q: unknown restart policy %q", ps.Name, ps.RestartPolicy)
+		}
+		ret[i] = codersdk.WorkspaceAgentService{
+			Name:          ps.Name,
+			DisplayName:   ps.DisplayName,
+			Command:       ps.Command,
+			RestartPolicy: restartPolicy,
+		}
+	}
+	return ret, nil
+}
+
+func ProtoFromServices(services []codersdk.WorkspaceAgentService) []*proto.WorkspaceAgentService {
+	ret := make([]*proto.WorkspaceAgentService, len(services))
+	for i, service := range services {
+		ret[i] = &proto.WorkspaceAgentService{
+			Name:          service.Name,
+			DisplayName:   service.DisplayName,
+			Command:       service.Command,
+			RestartPolicy: string(service.RestartPolicy),
+		}
+	}
+	return ret
+}
+
 func ProtoFromPatchAppStatus(pas PatchAppStatus) (*proto.UpdateAppStatusRequest, error) {
 	state, ok := proto.UpdateAppStatusRequest_AppStatusState_value[strings.ToUpper(string(pas.State))]
 	if !ok {
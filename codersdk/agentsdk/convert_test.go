@@ -139,6 +139,15 @@ func TestManifest(t *testing.T) {
 				SubagentID:      uuid.NullUUID{Valid: true, UUID: uuid.New()},
 			},
 		},
+		ReadOnly: true,
+		Services: []codersdk.WorkspaceAgentService{
+			{
+				Name:          "dev-server",
+				DisplayName:   "Dev Server",
+				Command:       "npm run dev",
+				RestartPolicy: codersdk.WorkspaceAgentServiceRestartPolicyOnFailure,
+			},
+		},
 	}
 	p, err := agentsdk.ProtoFromManifest(manifest)
 	require.NoError(t, err)
@@ -163,6 +172,8 @@ func TestManifest(t *testing.T) {
 	require.Equal(t, manifest.Metadata, back.Metadata)
 	require.Equal(t, manifest.Scripts, back.Scripts)
 	require.Equal(t, manifest.Devcontainers, back.Devcontainers)
+	require.Equal(t, manifest.ReadOnly, back.ReadOnly)
+	require.Equal(t, manifest.Services, back.Services)
 }
 
 func TestSubsystems(t *testing.T) {
@@ -24,6 +24,9 @@ type APIKey struct {
 	TokenName       string               `json:"token_name" validate:"required"`
 	LifetimeSeconds int64                `json:"lifetime_seconds" validate:"required"`
 	AllowList       []APIAllowListTarget `json:"allow_list"`
+	// TemplateIDs restricts workspace operations performed with the key to
+	// workspaces built from these templates. Empty means unrestricted.
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
 }
 
 // LoginType is the type of login used to create the API key.
@@ -53,6 +56,17 @@ type CreateTokenRequest struct {
 	Scopes    []APIKeyScope        `json:"scopes,omitempty"`
 	TokenName string               `json:"token_name"`
 	AllowList []APIAllowListTarget `json:"allow_list,omitempty"`
+	// TemplateIDs restricts the token to workspaces built from the given
+	// templates. Creating, reading, starting, and stopping workspaces from
+	// any other template is rejected. When set without Scopes, the token
+	// defaults to the "coder:workspaces.create" scope rather than
+	// "coder:all".
+	TemplateIDs []uuid.UUID `json:"template_ids,omitempty" format:"uuid"`
+	// WorkspaceID restricts the token to a single workspace owned by the
+	// token's user. The token can only read, build, and connect to that
+	// workspace, and can only be created by the workspace owner. It cannot
+	// be combined with Scopes, AllowList, or TemplateIDs.
+	WorkspaceID uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
@@ -182,6 +182,7 @@ const (
 	APIKeyScopeUserAll                             APIKeyScope = "user:*"
 	APIKeyScopeUserCreate                          APIKeyScope = "user:create"
 	APIKeyScopeUserDelete                          APIKeyScope = "user:delete"
+	APIKeyScopeUserImpersonate                     APIKeyScope = "user:impersonate"
 	APIKeyScopeUserRead                            APIKeyScope = "user:read"
 	APIKeyScopeUserReadPersonal                    APIKeyScope = "user:read_personal"
 	APIKeyScopeUserUpdate                          APIKeyScope = "user:update"
@@ -206,6 +207,7 @@ const (
 	APIKeyScopeWorkspaceCreateAgent                APIKeyScope = "workspace:create_agent"
 	APIKeyScopeWorkspaceDelete                     APIKeyScope = "workspace:delete"
 	APIKeyScopeWorkspaceDeleteAgent                APIKeyScope = "workspace:delete_agent"
+	APIKeyScopeWorkspaceExtend                     APIKeyScope = "workspace:extend"
 	APIKeyScopeWorkspaceRead                       APIKeyScope = "workspace:read"
 	APIKeyScopeWorkspaceShare                      APIKeyScope = "workspace:share"
 	APIKeyScopeWorkspaceSsh                        APIKeyScope = "workspace:ssh"
@@ -230,6 +232,7 @@ const (
 	APIKeyScopeWorkspaceDormantCreateAgent         APIKeyScope = "workspace_dormant:create_agent"
 	APIKeyScopeWorkspaceDormantDelete              APIKeyScope = "workspace_dormant:delete"
 	APIKeyScopeWorkspaceDormantDeleteAgent         APIKeyScope = "workspace_dormant:delete_agent"
+	APIKeyScopeWorkspaceDormantExtend              APIKeyScope = "workspace_dormant:extend"
 	APIKeyScopeWorkspaceDormantRead                APIKeyScope = "workspace_dormant:read"
 	APIKeyScopeWorkspaceDormantShare               APIKeyScope = "workspace_dormant:share"
 	APIKeyScopeWorkspaceDormantSsh                 APIKeyScope = "workspace_dormant:ssh"
@@ -296,6 +299,7 @@ var PublicAPIKeyScopes = []APIKeyScope{
 	APIKeyScopeWorkspaceApplicationConnect,
 	APIKeyScopeWorkspaceCreate,
 	APIKeyScopeWorkspaceDelete,
+	APIKeyScopeWorkspaceExtend,
 	APIKeyScopeWorkspaceRead,
 	APIKeyScopeWorkspaceSsh,
 	APIKeyScopeWorkspaceStart,
@@ -43,17 +43,21 @@ const (
 	ResourceTypeWorkspaceAgent ResourceType = "workspace_agent"
 	// Deprecated: Workspace App connections are now included in the
 	// connection log.
-	ResourceTypeWorkspaceApp         ResourceType = "workspace_app"
-	ResourceTypeTask                 ResourceType = "task"
-	ResourceTypeAISeat               ResourceType = "ai_seat"
-	ResourceTypeAIProvider           ResourceType = "ai_provider"
-	ResourceTypeAIProviderKey        ResourceType = "ai_provider_key"
-	ResourceTypeAIGatewayKey         ResourceType = "ai_gateway_key"
-	ResourceTypeGroupAIBudget        ResourceType = "group_ai_budget"
-	ResourceTypeUserAIBudgetOverride ResourceType = "user_ai_budget_override"
-	ResourceTypeChat                 ResourceType = "chat"
-	ResourceTypeUserSecret           ResourceType = "user_secret"
-	ResourceTypeUserSkill            ResourceType = "user_skill"
+	ResourceTypeWorkspaceApp          ResourceType = "workspace_app"
+	ResourceTypeTask                  ResourceType = "task"
+	ResourceTypeAISeat                ResourceType = "ai_seat"
+	ResourceTypeAIProvider            ResourceType = "ai_provider"
+	ResourceTypeAIProviderKey         ResourceType = "ai_provider_key"
+	ResourceTypeAIGatewayKey          ResourceType = "ai_gateway_key"
+	ResourceTypeGroupAIBudget         ResourceType = "group_ai_budget"
+	ResourceTypeUserAIBudgetOverride  ResourceType = "user_ai_budget_override"
+	ResourceTypeChat                  ResourceType = "chat"
+	ResourceTypeUserSecret            ResourceType = "user_secret"
+	ResourceTypeUserSkill             ResourceType = "user_skill"
+	ResourceTypeWorkspaceAppShareLink ResourceType = "workspace_app_share_link"
+	ResourceTypeTemplateSecret        ResourceType = "template_secret"
+	ResourceTypeDeploymentBundle      ResourceType = "deployment_bundle"
+	ResourceTypeVCSEventMapping       ResourceType = "vcs_event_mapping"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -130,6 +134,14 @@ func (r ResourceType) FriendlyString() string {
 		return "user secret"
 	case ResourceTypeUserSkill:
 		return "user skill"
+	case ResourceTypeWorkspaceAppShareLink:
+		return "workspace app share link"
+	case ResourceTypeTemplateSecret:
+		return "template secret"
+	case ResourceTypeDeploymentBundle:
+		return "deployment bundle"
+	case ResourceTypeVCSEventMapping:
+		return "VCS event mapping"
 	default:
 		return "unknown"
 	}
@@ -158,6 +170,13 @@ const (
 	AuditActionOpen AuditAction = "open"
 	// Deprecated: This action is unused.
 	AuditActionClose AuditAction = "close"
+	// AuditActionUpload and AuditActionDownload record files transferred to
+	// and from a workspace agent.
+	AuditActionUpload   AuditAction = "upload"
+	AuditActionDownload AuditAction = "download"
+	// AuditActionImpersonate records support staff requesting to act as a
+	// user.
+	AuditActionImpersonate AuditAction = "impersonate"
 )
 
 func (a AuditAction) Friendly() string {
@@ -188,6 +207,12 @@ func (a AuditAction) Friendly() string {
 		return "opened"
 	case AuditActionClose:
 		return "closed"
+	case AuditActionUpload:
+		return "uploaded a file to"
+	case AuditActionDownload:
+		return "downloaded a file from"
+	case AuditActionImpersonate:
+		return "impersonated"
 	default:
 		return "unknown"
 	}
@@ -219,6 +244,9 @@ type AuditLog struct {
 	Description      string          `json:"description"`
 	ResourceLink     string          `json:"resource_link"`
 	IsDeleted        bool            `json:"is_deleted"`
+	// CorrelationID is the ID of the workspace build this entry relates to.
+	// All audit entries caused by the same build share this ID.
+	CorrelationID *uuid.UUID `json:"correlation_id,omitempty" format:"uuid"`
 
 	// Deprecated: Use 'organization.id' instead.
 	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
@@ -248,6 +276,7 @@ type CreateTestAuditLogRequest struct {
 	BuildReason      BuildReason     `json:"build_reason,omitempty" enums:"autostart,autostop,initiator"`
 	OrganizationID   uuid.UUID       `json:"organization_id,omitempty" format:"uuid"`
 	RequestID        uuid.UUID       `json:"request_id,omitempty" format:"uuid"`
+	CorrelationID    uuid.UUID       `json:"correlation_id,omitempty" format:"uuid"`
 }
 
 // AuditLogs retrieves audit logs from the given page.
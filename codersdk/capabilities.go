@@ -0,0 +1,90 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// CapabilityFlag is a named capability of the deployment that clients can
+// feature-detect.
+type CapabilityFlag string
+
+const (
+	// CapabilityFlagDynamicParameters indicates that template parameters can
+	// be resolved dynamically over a WebSocket.
+	CapabilityFlagDynamicParameters CapabilityFlag = "dynamic_parameters"
+	// CapabilityFlagPrebuilds indicates that workspaces can be claimed from
+	// prebuilt workspace pools.
+	CapabilityFlagPrebuilds CapabilityFlag = "prebuilds"
+	// CapabilityFlagPathApps indicates that workspace apps can be accessed
+	// via a path on the access URL.
+	CapabilityFlagPathApps CapabilityFlag = "path_apps"
+	// CapabilityFlagSubdomainApps indicates that a wildcard access URL is
+	// configured, so workspace apps can be accessed via subdomains.
+	CapabilityFlagSubdomainApps CapabilityFlag = "subdomain_apps"
+	// CapabilityFlagWorkspaceSharing indicates that workspaces can be shared
+	// with other users and groups.
+	CapabilityFlagWorkspaceSharing CapabilityFlag = "workspace_sharing"
+)
+
+// CapabilityFlags lists all capability flags reported by the deployment.
+var CapabilityFlags = []CapabilityFlag{
+	CapabilityFlagDynamicParameters,
+	CapabilityFlagPrebuilds,
+	CapabilityFlagPathApps,
+	CapabilityFlagSubdomainApps,
+	CapabilityFlagWorkspaceSharing,
+}
+
+// Capabilities is a machine-readable description of what the deployment
+// supports. Clients should prefer it over inspecting error messages to
+// detect whether a feature is available.
+type Capabilities struct {
+	// APIVersions lists the versions of the API surfaces served by the
+	// deployment.
+	APIVersions CapabilitiesAPIVersions `json:"api_versions"`
+	// Experiments lists the enabled experiments.
+	Experiments Experiments `json:"experiments"`
+	// Features maps every licensed feature to whether it is enabled.
+	Features map[FeatureName]bool `json:"features"`
+	// Flags maps every capability flag to whether it is enabled.
+	Flags map[CapabilityFlag]bool `json:"flags"`
+}
+
+type CapabilitiesAPIVersions struct {
+	// Server is the version of the Coder server.
+	Server string `json:"server"`
+	// Agent is the current version of the agent API.
+	Agent string `json:"agent"`
+	// Provisioner is the current version of the provisioner daemon API.
+	Provisioner string `json:"provisioner"`
+	// Tailnet is the current version of the tailnet coordination API.
+	Tailnet string `json:"tailnet"`
+}
+
+// Enabled returns true if the deployment reports the flag as enabled.
+// Unknown flags, for example ones added in newer versions of Coder, are
+// reported as disabled.
+func (c Capabilities) Enabled(flag CapabilityFlag) bool {
+	return c.Flags[flag]
+}
+
+// FeatureEnabled returns true if the licensed feature is enabled.
+func (c Capabilities) FeatureEnabled(feature FeatureName) bool {
+	return c.Features[feature]
+}
+
+// Capabilities returns the capabilities of the deployment.
+func (c *Client) Capabilities(ctx context.Context) (Capabilities, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/capabilities", nil)
+	if err != nil {
+		return Capabilities{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Capabilities{}, ReadBodyAsError(res)
+	}
+	var capabilities Capabilities
+	return capabilities, json.NewDecoder(res.Body).Decode(&capabilities)
+}
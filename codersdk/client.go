@@ -66,6 +66,11 @@ const (
 	// JWT that can be used to authenticate instead of the app session token.
 	//nolint:gosec
 	SignedAppTokenCookie = "coder_signed_app_token"
+	// AppShareLinkSessionCookie is the name of the cookie that stores the
+	// session of a visitor of a shared workspace app. It is only set on the
+	// subdomain of the shared app.
+	//nolint:gosec
+	AppShareLinkSessionCookie = "coder_app_share_link_session"
 	// SignedAppTokenQueryParameter is the name of the query parameter that
 	// stores a temporary JWT that can be used to authenticate instead of the
 	// session token. This is only acceptable on reconnecting-pty requests, not
@@ -109,6 +114,11 @@ const (
 
 	// EntitlementsWarnings contains active warnings for the user's entitlements.
 	EntitlementsWarningHeader = "X-Coder-Entitlements-Warning"
+
+	// IdempotencyKeyHeader contains a client-generated key that makes
+	// retries of a workspace or workspace build creation request return the
+	// result of the original request instead of creating another one.
+	IdempotencyKeyHeader = "Idempotency-Key"
 )
 
 // loggableMimeTypes is a list of MIME types that are safe to log
@@ -2,12 +2,14 @@ package codersdk
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 )
 
@@ -54,3 +56,92 @@ func (c *Client) DebugCollectProfile(ctx context.Context, opts DebugProfileOptio
 
 	return resp.Body, nil
 }
+
+// DeploymentBundle fetches a tar.gz archive of server-side diagnostics for
+// attaching to support tickets. Secrets are redacted by the server. The
+// caller is responsible for closing the returned ReadCloser.
+func (c *Client) DeploymentBundle(ctx context.Context) (io.ReadCloser, error) {
+	resp, err := c.Request(ctx, http.MethodGet, "/api/v2/debug/deployment-bundle", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("request deployment bundle: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, ReadBodyAsError(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// LifecycleExecutorDeferReason is the reason the lifecycle executor could not
+// run a transition that was due.
+type LifecycleExecutorDeferReason string
+
+const (
+	// LifecycleExecutorDeferReasonNoProvisioners is used when no provisioner
+	// daemon matching the template's tags has been seen recently.
+	LifecycleExecutorDeferReasonNoProvisioners LifecycleExecutorDeferReason = "no_provisioners"
+	// LifecycleExecutorDeferReasonCostBudgetExceeded is used when a workspace
+	// is not autostarted because it is over its monthly cost budget.
+	LifecycleExecutorDeferReasonCostBudgetExceeded LifecycleExecutorDeferReason = "cost_budget_exceeded"
+)
+
+// AllLifecycleExecutorDeferReasons lists every LifecycleExecutorDeferReason.
+var AllLifecycleExecutorDeferReasons = []LifecycleExecutorDeferReason{
+	LifecycleExecutorDeferReasonNoProvisioners,
+	LifecycleExecutorDeferReasonCostBudgetExceeded,
+}
+
+// LifecycleExecutorStatus describes the most recent run of the lifecycle
+// executor, which autostarts, autostops, and marks workspaces dormant. Every
+// replica runs its own executor, so the status is that of the replica that
+// served the request.
+type LifecycleExecutorStatus struct {
+	ReplicaID uuid.UUID `json:"replica_id" format:"uuid"`
+	// LastTickAt is the time the last run started. It is unset until the
+	// executor has completed its first run.
+	LastTickAt           *time.Time `json:"last_tick_at,omitempty" format:"date-time"`
+	LastTickDurationMS   int64      `json:"last_tick_duration_ms"`
+	WorkspacesEvaluated  int        `json:"workspaces_evaluated"`
+	TransitionsAttempted int        `json:"transitions_attempted"`
+	TransitionsSucceeded int        `json:"transitions_succeeded"`
+	TransitionsFailed    int        `json:"transitions_failed"`
+	// Failures lists the workspaces the last run failed to evaluate or
+	// transition.
+	Failures []LifecycleExecutorFailure `json:"failures"`
+	// Deferred lists the workspaces with a transition due that the last run
+	// could not attempt. They are retried on the next run.
+	Deferred []LifecycleExecutorDeferral `json:"deferred"`
+}
+
+// LifecycleExecutorFailure is a workspace the lifecycle executor failed to
+// evaluate or transition.
+type LifecycleExecutorFailure struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	// BuildReason is the reason of the transition that failed. It is empty
+	// when the workspace failed before a transition was attempted.
+	BuildReason BuildReason `json:"build_reason,omitempty"`
+	Error       string      `json:"error"`
+}
+
+// LifecycleExecutorDeferral is a workspace with a transition due that the
+// lifecycle executor could not attempt.
+type LifecycleExecutorDeferral struct {
+	WorkspaceID uuid.UUID                    `json:"workspace_id" format:"uuid"`
+	Reason      LifecycleExecutorDeferReason `json:"reason"`
+}
+
+// DebugLifecycleExecutor returns the status of the lifecycle executor of the
+// replica that serves the request.
+func (c *Client) DebugLifecycleExecutor(ctx context.Context) (LifecycleExecutorStatus, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/debug/lifecycle-executor", nil)
+	if err != nil {
+		return LifecycleExecutorStatus{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return LifecycleExecutorStatus{}, ReadBodyAsError(res)
+	}
+	var status LifecycleExecutorStatus
+	return status, json.NewDecoder(res.Body).Decode(&status)
+}
@@ -628,10 +628,22 @@ type DeploymentValues struct {
 	WildcardAccessURL   serpent.String `json:"wildcard_access_url,omitempty"`
 	DocsURL             serpent.URL    `json:"docs_url,omitempty"`
 	RedirectToAccessURL serpent.Bool   `json:"redirect_to_access_url,omitempty"`
+	// WorkspaceDNSDomain and WorkspaceDNSProviderURL configure the registration
+	// of a stable DNS name for every running workspace.
+	WorkspaceDNSDomain      serpent.String `json:"workspace_dns_domain,omitempty"`
+	WorkspaceDNSProviderURL serpent.URL    `json:"workspace_dns_provider_url,omitempty"`
 	// HTTPAddress is a string because it may be set to zero to disable.
 	HTTPAddress                             serpent.String                       `json:"http_address,omitempty" typescript:",notnull"`
 	AutobuildPollInterval                   serpent.Duration                     `json:"autobuild_poll_interval,omitempty"`
 	JobReaperDetectorInterval               serpent.Duration                     `json:"job_hang_detector_interval,omitempty"`
+	WorkspaceMonthlyCostBudget              serpent.Int64                        `json:"workspace_monthly_cost_budget,omitempty" typescript:",notnull"`
+	UserMonthlyCostBudget                   serpent.Int64                        `json:"user_monthly_cost_budget,omitempty" typescript:",notnull"`
+	WorkspaceDormancyHookURL                serpent.URL                          `json:"workspace_dormancy_hook_url,omitempty"`
+	WorkspaceLeaseMaxPerToken               serpent.Int64                        `json:"workspace_lease_max_per_token,omitempty" typescript:",notnull"`
+	WorkspaceLeaseMaxTTL                    serpent.Duration                     `json:"workspace_lease_max_ttl,omitempty" typescript:",notnull"`
+	TemplateDefaultTTLMin                   serpent.Duration                     `json:"template_default_ttl_min,omitempty" typescript:",notnull"`
+	TemplateDefaultTTLMax                   serpent.Duration                     `json:"template_default_ttl_max,omitempty" typescript:",notnull"`
+	TemplateActivityBumpMax                 serpent.Duration                     `json:"template_activity_bump_max,omitempty" typescript:",notnull"`
 	Cluster                                 ClusterConfig                        `json:"cluster,omitempty" typescript:",notnull"`
 	DERP                                    DERP                                 `json:"derp,omitempty" typescript:",notnull"`
 	Prometheus                              PrometheusConfig                     `json:"prometheus,omitempty" typescript:",notnull"`
@@ -656,6 +668,8 @@ type DeploymentValues struct {
 	MetricsCacheRefreshInterval             serpent.Duration                     `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
 	AgentStatRefreshInterval                serpent.Duration                     `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
 	AgentFallbackTroubleshootingURL         serpent.URL                          `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
+	AgentBetaBinariesDir                    serpent.String                       `json:"agent_beta_binaries_dir,omitempty" typescript:",notnull"`
+	AgentBetaRolloutPercent                 serpent.Int64                        `json:"agent_beta_rollout_percent,omitempty" typescript:",notnull"`
 	BrowserOnly                             serpent.Bool                         `json:"browser_only,omitempty" typescript:",notnull"`
 	SCIMAPIKey                              serpent.String                       `json:"scim_api_key,omitempty" typescript:",notnull"`
 	UseLegacySCIM                           serpent.Bool                         `json:"scim_use_legacy,omitempty" typescript:",notnull"`
@@ -869,6 +883,14 @@ type SessionLifetime struct {
 	MaximumTokenDuration serpent.Duration `json:"max_token_lifetime,omitempty" typescript:",notnull"`
 
 	MaximumAdminTokenDuration serpent.Duration `json:"max_admin_token_lifetime,omitempty" typescript:",notnull"`
+
+	// MaximumImpersonationDuration is the maximum lifetime of tokens issued
+	// to impersonate users.
+	MaximumImpersonationDuration serpent.Duration `json:"max_impersonation_lifetime,omitempty" typescript:",notnull"`
+
+	// RequireImpersonationConsent requires users to consent before tokens
+	// impersonating them are issued.
+	RequireImpersonationConsent serpent.Bool `json:"require_impersonation_consent,omitempty" typescript:",notnull"`
 }
 
 type DERP struct {
@@ -1025,6 +1047,11 @@ type TraceConfig struct {
 	HoneycombAPIKey serpent.String `json:"honeycomb_api_key" typescript:",notnull"`
 	CaptureLogs     serpent.Bool   `json:"capture_logs" typescript:",notnull"`
 	DataDog         serpent.Bool   `json:"data_dog" typescript:",notnull"`
+	// WorkspaceBuilds enables exporting the timings of workspace builds as
+	// traces. WorkspaceBuildsEndpoint optionally sends them to a dedicated
+	// OTLP endpoint instead of the application tracing backend.
+	WorkspaceBuilds         serpent.Bool   `json:"workspace_builds" typescript:",notnull"`
+	WorkspaceBuildsEndpoint serpent.String `json:"workspace_builds_endpoint" typescript:",notnull"`
 }
 
 const cookieHostPrefix = "__Host-"
@@ -1177,11 +1204,24 @@ type ProvisionerConfig struct {
 	DaemonPollJitter    serpent.Duration    `json:"daemon_poll_jitter" typescript:",notnull"`
 	ForceCancelInterval serpent.Duration    `json:"force_cancel_interval" typescript:",notnull"`
 	DaemonPSK           serpent.String      `json:"daemon_psk" typescript:",notnull"`
+	// TemplateScannerURL is a webhook that newly imported template versions
+	// are submitted to for security scanning. Scanning is disabled when unset.
+	TemplateScannerURL           serpent.URL  `json:"template_scanner_url" typescript:",notnull"`
+	TemplateScannerBlockCritical serpent.Bool `json:"template_scanner_block_critical" typescript:",notnull"`
+	// CloudResourceCheckerURL is a webhook that checks whether the cloud
+	// resources of deleted workspaces still exist. Verification is disabled
+	// when unset.
+	CloudResourceCheckerURL serpent.URL `json:"cloud_resource_checker_url" typescript:",notnull"`
 }
 
 type RateLimitConfig struct {
 	DisableAll serpent.Bool  `json:"disable_all" typescript:",notnull"`
 	API        serpent.Int64 `json:"api" typescript:",notnull"`
+	// WorkspaceBuilds is the sustained number of builds per minute allowed
+	// per token and per IP address on build-creating endpoints.
+	WorkspaceBuilds          serpent.Int64       `json:"workspace_builds" typescript:",notnull"`
+	WorkspaceBuildsBurst     serpent.Int64       `json:"workspace_builds_burst" typescript:",notnull"`
+	WorkspaceBuildsAllowlist serpent.StringArray `json:"workspace_builds_allowlist" typescript:",notnull"`
 }
 
 type SwaggerConfig struct {
@@ -1241,6 +1281,18 @@ type RetentionConfig struct {
 	// deletion (keep indefinitely). Adjust to match your
 	// organization's regulatory requirements.
 	BoundaryLogs serpent.Duration `json:"boundary_logs" typescript:",notnull"`
+	// WorkspaceAgentMetadataHistory controls how long historical values of
+	// workspace agent metadata are retained. Set to 0 to disable recording
+	// metadata history.
+	WorkspaceAgentMetadataHistory serpent.Duration `json:"workspace_agent_metadata_history" typescript:",notnull"`
+	// BuildLogs controls how long provisioner job logs are retained before
+	// they are archived or deleted. Logs of the latest build of each
+	// workspace are always retained. Templates may override this value.
+	// Set to 0 to disable.
+	BuildLogs serpent.Duration `json:"build_logs" typescript:",notnull"`
+	// BuildLogsArchiveDir is the directory expired build logs are archived
+	// to. When unset, expired build logs are deleted instead.
+	BuildLogsArchiveDir serpent.String `json:"build_logs_archive_dir" typescript:",notnull"`
 }
 
 type NotificationsConfig struct {
@@ -2276,6 +2328,24 @@ communicating directly.`,
 			YAML:        "docsURL",
 			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Workspace DNS Domain",
+			Description: "Domain under which a stable DNS name is registered for every running workspace, in the form \"<workspace>--<owner>.<domain>\". Names are removed when the workspace stops or is deleted. Requires a workspace DNS provider URL.",
+			Flag:        "workspace-dns-domain",
+			Env:         "CODER_WORKSPACE_DNS_DOMAIN",
+			Value:       &c.WorkspaceDNSDomain,
+			Group:       &deploymentGroupNetworking,
+			YAML:        "workspaceDNSDomain",
+		},
+		{
+			Name:        "Workspace DNS Provider URL",
+			Description: "URL of a webhook that creates and removes workspace DNS records. Coder POSTs the action (register or deregister), the record name and the target host as JSON.",
+			Flag:        "workspace-dns-provider-url",
+			Env:         "CODER_WORKSPACE_DNS_PROVIDER_URL",
+			Value:       &c.WorkspaceDNSProviderURL,
+			Group:       &deploymentGroupNetworking,
+			YAML:        "workspaceDNSProviderURL",
+		},
 		redirectToAccessURL,
 		{
 			Name:        "Autobuild Poll Interval",
@@ -2299,6 +2369,77 @@ communicating directly.`,
 			YAML:        "jobHangDetectorInterval",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name:        "Workspace Monthly Cost Budget",
+			Description: "The maximum cost a single workspace may accrue per UTC calendar month, in the same units as the daily cost of workspace resources. Running workspaces over budget are stopped. Costs are estimated from the daily cost of the running build unless imported from a billing export. 0 disables the budget.",
+			Flag:        "workspace-monthly-cost-budget",
+			Env:         "CODER_WORKSPACE_MONTHLY_COST_BUDGET",
+			Default:     "0",
+			Value:       &c.WorkspaceMonthlyCostBudget,
+			YAML:        "workspaceMonthlyCostBudget",
+		},
+		{
+			Name:        "User Monthly Cost Budget",
+			Description: "The maximum cost all workspaces of a user may accrue together per UTC calendar month, in the same units as the daily cost of workspace resources. Running workspaces of users over budget are stopped. 0 disables the budget.",
+			Flag:        "user-monthly-cost-budget",
+			Env:         "CODER_USER_MONTHLY_COST_BUDGET",
+			Default:     "0",
+			Value:       &c.UserMonthlyCostBudget,
+			YAML:        "userMonthlyCostBudget",
+		},
+		{
+			Name:        "Workspace Dormancy Hook URL",
+			Description: "URL of a webhook invoked when a workspace becomes dormant and before it is activated, e.g. to move its disks to cold storage and restore them. Coder POSTs the event (dormant or activate) and the workspace as JSON. Dormant workspaces are only activated once the activate hook succeeded.",
+			Flag:        "workspace-dormancy-hook-url",
+			Env:         "CODER_WORKSPACE_DORMANCY_HOOK_URL",
+			Value:       &c.WorkspaceDormancyHookURL,
+			YAML:        "workspaceDormancyHookURL",
+		},
+		{
+			Name:        "Workspace Lease Max Per Token",
+			Description: "The maximum number of unreleased workspace leases a single API token may hold at once. Leases are ephemeral workspaces for CI jobs. 0 disables workspace leases.",
+			Flag:        "workspace-lease-max-per-token",
+			Env:         "CODER_WORKSPACE_LEASE_MAX_PER_TOKEN",
+			Default:     "5",
+			Value:       &c.WorkspaceLeaseMaxPerToken,
+			YAML:        "workspaceLeaseMaxPerToken",
+		},
+		{
+			Name:        "Workspace Lease Max TTL",
+			Description: "The maximum lifetime of a workspace lease. Workspaces are deleted once their lease expires, even if it was never released.",
+			Flag:        "workspace-lease-max-ttl",
+			Env:         "CODER_WORKSPACE_LEASE_MAX_TTL",
+			Default:     (24 * time.Hour).String(),
+			Value:       &c.WorkspaceLeaseMaxTTL,
+			YAML:        "workspaceLeaseMaxTTL",
+		},
+		{
+			Name:        "Template Default TTL Min",
+			Description: "The minimum default autostop TTL templates may configure. Templates that disable autostop are not affected. 0 disables the minimum.",
+			Flag:        "template-default-ttl-min",
+			Env:         "CODER_TEMPLATE_DEFAULT_TTL_MIN",
+			Default:     "0",
+			Value:       &c.TemplateDefaultTTLMin,
+			YAML:        "templateDefaultTTLMin",
+		},
+		{
+			Name:        "Template Default TTL Max",
+			Description: "The maximum default autostop TTL templates may configure. When set, templates must stop workspaces automatically, and templates created without a default TTL use this value. 0 disables the maximum.",
+			Flag:        "template-default-ttl-max",
+			Env:         "CODER_TEMPLATE_DEFAULT_TTL_MAX",
+			Default:     "0",
+			Value:       &c.TemplateDefaultTTLMax,
+			YAML:        "templateDefaultTTLMax",
+		},
+		{
+			Name:        "Template Activity Bump Max",
+			Description: "The maximum duration templates may extend the autostop deadline of a workspace by when it is used. 0 disables the maximum.",
+			Flag:        "template-activity-bump-max",
+			Env:         "CODER_TEMPLATE_ACTIVITY_BUMP_MAX",
+			Default:     "0",
+			Value:       &c.TemplateActivityBumpMax,
+			YAML:        "templateActivityBumpMax",
+		},
 		httpAddress,
 		tlsBindAddress,
 		{
@@ -3172,6 +3313,24 @@ communicating directly.`,
 			Default:     "false",
 			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Trace Workspace Builds",
+			Description: "Export the lifecycle of workspace builds (queue wait, plan, apply, agent connect and scripts) as traces, so build performance can be analyzed in existing tracing tools. Traces are sent to the application tracing backend unless --trace-workspace-builds-endpoint is set.",
+			Flag:        "trace-workspace-builds",
+			Env:         "CODER_TRACE_WORKSPACE_BUILDS",
+			Value:       &c.Trace.WorkspaceBuilds,
+			Group:       &deploymentGroupIntrospectionTracing,
+			YAML:        "workspaceBuilds",
+		},
+		{
+			Name:        "Trace Workspace Builds Endpoint",
+			Description: "The OTLP gRPC endpoint to export workspace build traces to, e.g. otel-collector:4317. If unset, workspace build traces are sent to the application tracing backend, which requires --trace to be enabled.",
+			Flag:        "trace-workspace-builds-endpoint",
+			Env:         "CODER_TRACE_WORKSPACE_BUILDS_ENDPOINT",
+			Value:       &c.Trace.WorkspaceBuildsEndpoint,
+			Group:       &deploymentGroupIntrospectionTracing,
+			YAML:        "workspaceBuildsEndpoint",
+		},
 		// Provisioner settings
 		{
 			Name:        "Provisioner Daemons",
@@ -3242,6 +3401,34 @@ communicating directly.`,
 			YAML:        "forceCancelInterval",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name:        "Template Scanner URL",
+			Description: "URL of a webhook that scans the files and plan of every newly imported template version. Findings are stored on the template version. Scanning is disabled when unset.",
+			Flag:        "provisioner-template-scanner-url",
+			Env:         "CODER_PROVISIONER_TEMPLATE_SCANNER_URL",
+			Value:       &c.Provisioner.TemplateScannerURL,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "templateScannerURL",
+		},
+		{
+			Name:        "Template Scanner Block Critical",
+			Description: "Prevent promoting a template version to active unless its scan completed without critical findings. Requires a template scanner URL.",
+			Flag:        "provisioner-template-scanner-block-critical",
+			Env:         "CODER_PROVISIONER_TEMPLATE_SCANNER_BLOCK_CRITICAL",
+			Default:     "false",
+			Value:       &c.Provisioner.TemplateScannerBlockCritical,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "templateScannerBlockCritical",
+		},
+		{
+			Name:        "Cloud Resource Checker URL",
+			Description: "URL of a webhook that checks whether the cloud resources of deleted workspaces still exist. Coder POSTs the type, name and cloud ID of each resource as JSON, and flags the resources that still exist as leaked. Verification is disabled when unset.",
+			Flag:        "provisioner-cloud-resource-checker-url",
+			Env:         "CODER_PROVISIONER_CLOUD_RESOURCE_CHECKER_URL",
+			Value:       &c.Provisioner.CloudResourceCheckerURL,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "cloudResourceCheckerURL",
+		},
 		{
 			Name:        "Provisioner Daemon Pre-shared Key (PSK)",
 			Description: "Pre-shared key to authenticate external provisioner daemons to Coder server.",
@@ -3274,6 +3461,32 @@ communicating directly.`,
 			Hidden:      true,
 			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Workspace Build Rate Limit",
+			Description: "Maximum number of workspace builds per minute allowed per API token, and separately per IP address. Applies to endpoints that create workspaces or workspace builds. Negative values mean no rate limit, which is the default.",
+			Flag:        "workspace-build-rate-limit",
+			Env:         "CODER_WORKSPACE_BUILD_RATE_LIMIT",
+			Default:     "-1",
+			Value:       &c.RateLimit.WorkspaceBuilds,
+			Hidden:      true,
+		},
+		{
+			Name:        "Workspace Build Rate Limit Burst",
+			Description: "Number of workspace builds allowed in quick succession before the workspace build rate limit applies. Defaults to the rate limit when unset.",
+			Flag:        "workspace-build-rate-limit-burst",
+			Env:         "CODER_WORKSPACE_BUILD_RATE_LIMIT_BURST",
+			Default:     "0",
+			Value:       &c.RateLimit.WorkspaceBuildsBurst,
+			Hidden:      true,
+		},
+		{
+			Name:        "Workspace Build Rate Limit Allowlist",
+			Description: "IDs of API tokens that are exempt from the workspace build rate limit, such as tokens used by trusted automation. The ID is the part of the token before the dash.",
+			Flag:        "workspace-build-rate-limit-allowlist",
+			Env:         "CODER_WORKSPACE_BUILD_RATE_LIMIT_ALLOWLIST",
+			Value:       &c.RateLimit.WorkspaceBuildsAllowlist,
+			Hidden:      true,
+		},
 		// Logging settings
 		{
 			Name:          "Verbose",
@@ -3419,6 +3632,25 @@ communicating directly.`,
 			YAML:        "maxAdminTokenLifetime",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name:        "Maximum Impersonation Lifetime",
+			Description: "The maximum lifetime of tokens issued to impersonate users.",
+			Flag:        "max-impersonation-lifetime",
+			Env:         "CODER_MAX_IMPERSONATION_LIFETIME",
+			Default:     (8 * time.Hour).String(),
+			Value:       &c.Sessions.MaximumImpersonationDuration,
+			Hidden:      true,
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Require Impersonation Consent",
+			Description: "Require users to consent before tokens impersonating them are issued.",
+			Flag:        "require-impersonation-consent",
+			Env:         "CODER_REQUIRE_IMPERSONATION_CONSENT",
+			Default:     "false",
+			Value:       &c.Sessions.RequireImpersonationConsent,
+			Hidden:      true,
+		},
 		{
 			Name:        "Default Token Lifetime",
 			Description: "The default lifetime duration for API tokens. This value is used when creating a token without specifying a duration, such as when authenticating the CLI or an IDE plugin.",
@@ -3637,6 +3869,23 @@ communicating directly.`,
 			Value:       &c.AgentFallbackTroubleshootingURL,
 			YAML:        "agentFallbackTroubleshootingURL",
 		},
+		{
+			Name:        "Agent Beta Binaries Directory",
+			Description: "Directory containing agent binaries for the beta rollout channel, named like the binaries served from /bin (e.g. coder-linux-amd64). Workspaces of templates on the beta channel download their agent from here on start. If unset, every workspace receives the stable agent bundled with this server.",
+			Flag:        "agent-beta-binaries-dir",
+			Env:         "CODER_AGENT_BETA_BINARIES_DIR",
+			Value:       &c.AgentBetaBinariesDir,
+			YAML:        "agentBetaBinariesDir",
+		},
+		{
+			Name:        "Agent Beta Rollout Percent",
+			Description: "Percentage of workspaces of templates on the beta rollout channel that receive the beta agent. The remaining workspaces receive the stable agent. Workspaces are bucketed by ID, so a workspace stays on the same channel as the percentage grows.",
+			Flag:        "agent-beta-rollout-percent",
+			Env:         "CODER_AGENT_BETA_ROLLOUT_PERCENT",
+			Default:     "100",
+			Value:       &c.AgentBetaRolloutPercent,
+			YAML:        "agentBetaRolloutPercent",
+		},
 		{
 			Name:        "Browser Only",
 			Description: "Whether Coder only allows connections to workspaces via the browser.",
@@ -4835,6 +5084,37 @@ Write out the current server config as YAML to stdout.`,
 			YAML:        "boundary_logs",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name:        "Workspace Agent Metadata History Retention",
+			Description: "How long historical values of workspace agent metadata are retained, so they can be queried and graphed. Set to 0 to disable recording metadata history.",
+			Flag:        "workspace-agent-metadata-history-retention",
+			Env:         "CODER_WORKSPACE_AGENT_METADATA_HISTORY_RETENTION",
+			Value:       &c.Retention.WorkspaceAgentMetadataHistory,
+			Default:     "0",
+			Group:       &deploymentGroupRetention,
+			YAML:        "workspace_agent_metadata_history",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Build Logs Retention",
+			Description: "How long provisioner job logs are retained before they are archived or deleted. Logs of the latest build of each workspace are always retained. Templates can override this value. Set to 0 to disable.",
+			Flag:        "build-logs-retention",
+			Env:         "CODER_BUILD_LOGS_RETENTION",
+			Value:       &c.Retention.BuildLogs,
+			Default:     "0",
+			Group:       &deploymentGroupRetention,
+			YAML:        "build_logs",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Build Logs Archive Directory",
+			Description: "Directory expired build logs are archived to, e.g. a mounted object storage bucket. Archived logs remain retrievable through the API. When unset, expired build logs are deleted.",
+			Flag:        "build-logs-archive-dir",
+			Env:         "CODER_BUILD_LOGS_ARCHIVE_DIR",
+			Value:       &c.Retention.BuildLogsArchiveDir,
+			Group:       &deploymentGroupRetention,
+			YAML:        "build_logs_archive_dir",
+		},
 		{
 			Name: "Enable Authorization Recordings",
 			Description: "All api requests will have a header including all authorization calls made during the request. " +
@@ -5525,6 +5805,46 @@ func (c *Client) SSHConfiguration(ctx context.Context) (SSHConfigResponse, error
 	return sshConfig, json.NewDecoder(res.Body).Decode(&sshConfig)
 }
 
+// SSHHostCAKey is the public half of a CA that signs workspace agent SSH host
+// certificates.
+type SSHHostCAKey struct {
+	// PublicKey is in OpenSSH authorized_keys format.
+	PublicKey string    `json:"public_key"`
+	Sequence  int32     `json:"sequence"`
+	StartsAt  time.Time `json:"starts_at" format:"date-time"`
+	// DeletesAt is set once the CA has been rotated out. Clients should keep
+	// trusting it until then, as certificates it signed may still be valid.
+	DeletesAt *time.Time `json:"deletes_at,omitempty" format:"date-time"`
+}
+
+// SSHHostCAResponse lists every CA currently trusted to sign workspace host
+// certificates. Clients that trust these keys can verify a workspace's host
+// identity instead of prompting on first use.
+type SSHHostCAResponse struct {
+	Keys []SSHHostCAKey `json:"keys"`
+	// KnownHosts contains one @cert-authority line per key, scoped to the
+	// deployment's SSH hostname patterns, ready to append to a known_hosts
+	// file.
+	KnownHosts string `json:"known_hosts"`
+}
+
+// SSHHostCA returns the public keys of the SSH host certificate authorities
+// for the Coder instance.
+func (c *Client) SSHHostCA(ctx context.Context) (SSHHostCAResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/ssh-host-ca", nil)
+	if err != nil {
+		return SSHHostCAResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return SSHHostCAResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp SSHHostCAResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 type CryptoKeyFeature string
 
 const (
@@ -5539,6 +5859,11 @@ const (
 	// served over the API. It is deliberately excluded from
 	// whitelistedCryptoKeyFeatures in enterprise/coderd/workspaceproxy.go.
 	CryptoKeyFeatureNATSCA CryptoKeyFeature = "nats_ca"
+	// CryptoKeyFeatureSSHHostCA is the CA that signs workspace agent SSH host
+	// certificates. Only its public half is published (see
+	// /api/v2/deployment/ssh-host-ca); the secret must never be served over
+	// the API.
+	CryptoKeyFeatureSSHHostCA CryptoKeyFeature = "ssh_host_ca"
 )
 
 type CryptoKey struct {
@@ -3,10 +3,12 @@ package healthsdk
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
 	"tailscale.com/net/netcheck"
@@ -96,6 +98,21 @@ func (c *HealthClient) PutHealthSettings(ctx context.Context, settings HealthSet
 	return nil
 }
 
+// WorkspaceAgentNetcheck asks the workspace agent to run a network
+// diagnostic and returns the report. This may take several seconds.
+func (c *HealthClient) WorkspaceAgentNetcheck(ctx context.Context, agentID uuid.UUID) (AgentNetworkDiagnosticsReport, error) {
+	res, err := c.client.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/netcheck", agentID), nil)
+	if err != nil {
+		return AgentNetworkDiagnosticsReport{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return AgentNetworkDiagnosticsReport{}, codersdk.ReadBodyAsError(res)
+	}
+	var rpt AgentNetworkDiagnosticsReport
+	return rpt, json.NewDecoder(res.Body).Decode(&rpt)
+}
+
 // HealthcheckReport contains information about the health status of a Coder deployment.
 type HealthcheckReport struct {
 	// Time is the time the report was generated at.
@@ -280,3 +297,76 @@ type AgentNetcheckReport struct {
 	NetInfo    *tailcfg.NetInfo `json:"net_info"`
 	Interfaces InterfacesReport `json:"interfaces"`
 }
+
+// AgentNetworkDiagnosticsReport is the result of a network diagnostic run on
+// demand by a workspace agent.
+// @typescript-ignore AgentNetworkDiagnosticsReport
+type AgentNetworkDiagnosticsReport struct {
+	BaseReport
+	// DERP contains the latency to each DERP region and the STUN
+	// reachability of each node, as seen from the agent.
+	DERP DERPHealthReport `json:"derp"`
+	// Interfaces contains the network interfaces of the agent, including
+	// warnings for interfaces with a small MTU.
+	Interfaces       InterfacesReport       `json:"interfaces"`
+	DirectConnection DirectConnectionReport `json:"direct_connection"`
+	// Time is the time the agent generated the report at, by its own clock.
+	Time time.Time `json:"time" format:"date-time"`
+	// ClockSkew compares Time to the clock of the Coder server when it
+	// received the report. It is only populated by the Coder server, and is
+	// nil if the agent does not report its time.
+	ClockSkew *ClockSkewReport `json:"clock_skew,omitempty"`
+}
+
+// ClockSkewReport shows how far the clock of a workspace agent is from the
+// clock of the Coder server.
+// @typescript-ignore ClockSkewReport
+type ClockSkewReport struct {
+	BaseReport
+	// SkewMilliseconds is the offset of the clock of the agent from the clock
+	// of the Coder server. Positive values mean the clock of the agent is
+	// ahead.
+	SkewMilliseconds int64 `json:"skew_ms"`
+}
+
+// NewClockSkewReport reports a warning if skew exceeds
+// codersdk.WorkspaceAgentClockSkewThreshold.
+func NewClockSkewReport(skew time.Duration) ClockSkewReport {
+	report := ClockSkewReport{
+		BaseReport: BaseReport{
+			Severity: health.SeverityOK,
+			Warnings: []health.Message{},
+		},
+		SkewMilliseconds: skew.Milliseconds(),
+	}
+	if skew.Abs() > codersdk.WorkspaceAgentClockSkewThreshold {
+		direction := "ahead of"
+		if skew < 0 {
+			direction = "behind"
+		}
+		report.Severity = health.SeverityWarning
+		report.Warnings = append(report.Warnings, health.Messagef(health.CodeAgentClockSkew,
+			"The clock of the agent is %s %s the Coder server (more than %s), which may break TLS connections, "+
+				"token validation and build caches.", skew.Abs().Round(time.Second), direction, codersdk.WorkspaceAgentClockSkewThreshold))
+	}
+	return report
+}
+
+// DirectConnectionReport shows whether peers are likely to be able to
+// establish a direct connection to the agent instead of relaying traffic
+// through DERP.
+// @typescript-ignore DirectConnectionReport
+type DirectConnectionReport struct {
+	Feasible bool `json:"feasible"`
+	// Disabled is true if direct connections are disabled on the agent.
+	Disabled bool `json:"disabled"`
+	// UDP is true if the agent could reach a STUN server over UDP.
+	UDP bool `json:"udp"`
+	// HardNAT is true if STUN servers returned different addresses for the
+	// agent.
+	HardNAT bool `json:"hard_nat"`
+	// PortMapping is true if a port mapping protocol (UPnP, NAT-PMP or PCP)
+	// is available on the network of the agent.
+	PortMapping   bool `json:"port_mapping"`
+	PreferredDERP int  `json:"preferred_derp"`
+}
@@ -2,6 +2,7 @@ package healthsdk_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -159,3 +160,33 @@ func TestSummarize(t *testing.T) {
 		})
 	}
 }
+
+func TestNewClockSkewReport(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name     string
+		skew     time.Duration
+		severity health.Severity
+		warning  string
+	}{
+		{"InSync", 150 * time.Millisecond, health.SeverityOK, ""},
+		{"Ahead", 3 * time.Minute, health.SeverityWarning, "3m0s ahead of"},
+		{"Behind", -90 * time.Second, health.SeverityWarning, "1m30s behind"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			report := healthsdk.NewClockSkewReport(tt.skew)
+			assert.Equal(t, tt.severity, report.Severity)
+			assert.Equal(t, tt.skew.Milliseconds(), report.SkewMilliseconds)
+			if tt.warning == "" {
+				assert.Empty(t, report.Warnings)
+				return
+			}
+			if assert.Len(t, report.Warnings, 1) {
+				assert.Equal(t, health.Code(health.CodeAgentClockSkew), report.Warnings[0].Code)
+				assert.Contains(t, report.Warnings[0].Message, tt.warning)
+			}
+		})
+	}
+}
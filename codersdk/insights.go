@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -320,3 +321,384 @@ func (c *Client) GetUserStatusCounts(ctx context.Context, req GetUserStatusCount
 	var result GetUserStatusCountsResponse
 	return result, json.NewDecoder(resp.Body).Decode(&result)
 }
+
+// WorkspaceEgressInsightsResponse is the response from the workspace egress
+// insights endpoint.
+type WorkspaceEgressInsightsResponse struct {
+	Report WorkspaceEgressInsightsReport `json:"report"`
+}
+
+// WorkspaceEgressInsightsReport lists the bytes moved by workspace agents per
+// workspace and UTC day, split by connection class.
+type WorkspaceEgressInsightsReport struct {
+	StartTime   time.Time         `json:"start_time" format:"date-time"`
+	EndTime     time.Time         `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID       `json:"template_ids" format:"uuid"`
+	Workspaces  []WorkspaceEgress `json:"workspaces"`
+}
+
+// WorkspaceEgress is the traffic of a single workspace on a single day. Rx is
+// traffic received by the workspace, Tx is traffic sent by it.
+type WorkspaceEgress struct {
+	Date          time.Time `json:"date" format:"date"`
+	WorkspaceID   uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName string    `json:"workspace_name"`
+	OwnerID       uuid.UUID `json:"owner_id" format:"uuid"`
+	OwnerUsername string    `json:"owner_username"`
+	TemplateID    uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName  string    `json:"template_name"`
+	// SSH covers SSH sessions, including VS Code and JetBrains.
+	SSH EgressBytes `json:"ssh"`
+	// ReconnectingPTY covers the web terminal.
+	ReconnectingPTY EgressBytes `json:"reconnecting_pty"`
+	// PortForward covers port forwarding and workspace apps.
+	PortForward EgressBytes `json:"port_forward"`
+}
+
+type EgressBytes struct {
+	RxBytes int64 `json:"rx_bytes"`
+	TxBytes int64 `json:"tx_bytes"`
+}
+
+type WorkspaceEgressInsightsRequest struct {
+	StartTime   time.Time   `json:"start_time" format:"date-time"`
+	EndTime     time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+}
+
+func (c *Client) WorkspaceEgressInsights(ctx context.Context, req WorkspaceEgressInsightsRequest) (WorkspaceEgressInsightsResponse, error) {
+	reqURL := fmt.Sprintf("/api/v2/insights/workspace-egress?%s", req.queryParams().Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return WorkspaceEgressInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return WorkspaceEgressInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result WorkspaceEgressInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// WorkspaceEgressInsightsCSV returns the same data as WorkspaceEgressInsights
+// as CSV, one row per workspace and day. The caller must close the reader.
+func (c *Client) WorkspaceEgressInsightsCSV(ctx context.Context, req WorkspaceEgressInsightsRequest) (io.ReadCloser, error) {
+	qp := req.queryParams()
+	qp.Add("format", "csv")
+	reqURL := fmt.Sprintf("/api/v2/insights/workspace-egress?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, ReadBodyAsError(resp)
+	}
+	return resp.Body, nil
+}
+
+func (req WorkspaceEgressInsightsRequest) queryParams() url.Values {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+	return qp
+}
+
+// PrebuildClaimInsightsResponse is the response from the prebuild claim
+// insights endpoint.
+type PrebuildClaimInsightsResponse struct {
+	Report PrebuildClaimInsightsReport `json:"report"`
+}
+
+// PrebuildClaimInsightsReport reports, per preset, how often a prebuilt
+// workspace could be claimed when a workspace was created with the preset,
+// how long claimed workspaces took to become ready, and why the pool was empty
+// when no prebuilt workspace could be claimed.
+type PrebuildClaimInsightsReport struct {
+	StartTime   time.Time                    `json:"start_time" format:"date-time"`
+	EndTime     time.Time                    `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID                  `json:"template_ids" format:"uuid"`
+	Presets     []PrebuildPresetClaimInsight `json:"presets"`
+}
+
+// PrebuildClaimMissReason is the reason no prebuilt workspace could be claimed.
+type PrebuildClaimMissReason string
+
+const (
+	// PrebuildClaimMissReasonReconciliationLag means prebuilt workspaces were
+	// being provisioned but none were ready to be claimed yet.
+	PrebuildClaimMissReasonReconciliationLag PrebuildClaimMissReason = "reconciliation_lag"
+	// PrebuildClaimMissReasonCapacity means every prebuilt workspace had been
+	// claimed, so demand exceeded the desired instances of the preset.
+	PrebuildClaimMissReasonCapacity PrebuildClaimMissReason = "capacity"
+	// PrebuildClaimMissReasonFailures means prebuilt workspaces of the preset
+	// recently failed to build, or the preset is hard-limited.
+	PrebuildClaimMissReasonFailures PrebuildClaimMissReason = "failures"
+)
+
+// PrebuildPresetClaimInsight is the claim performance of a single preset.
+type PrebuildPresetClaimInsight struct {
+	OrganizationName    string    `json:"organization_name"`
+	TemplateID          uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName        string    `json:"template_name"`
+	TemplateVersionName string    `json:"template_version_name"`
+	PresetID            uuid.UUID `json:"preset_id" format:"uuid"`
+	PresetName          string    `json:"preset_name"`
+	// ClaimAttempts is the number of workspaces created with the preset.
+	ClaimAttempts int64 `json:"claim_attempts"`
+	// Claimed is the number of those workspaces that claimed a prebuilt
+	// workspace.
+	Claimed int64 `json:"claimed"`
+	// ClaimSuccessRate is Claimed divided by ClaimAttempts, between 0 and 1.
+	ClaimSuccessRate float64 `json:"claim_success_rate"`
+	// TimeToReadyP50Seconds and TimeToReadyP95Seconds are percentiles of the
+	// time from a claim until all agents of the workspace were ready. They
+	// are zero when no claimed workspace has become ready.
+	TimeToReadyP50Seconds float64 `json:"time_to_ready_p50_seconds"`
+	TimeToReadyP95Seconds float64 `json:"time_to_ready_p95_seconds"`
+	// Misses counts the claim attempts that found no prebuilt workspace, by
+	// the reason the pool was empty.
+	Misses map[PrebuildClaimMissReason]int64 `json:"misses"`
+}
+
+type PrebuildClaimInsightsRequest struct {
+	StartTime   time.Time   `json:"start_time" format:"date-time"`
+	EndTime     time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+}
+
+func (c *Client) PrebuildClaimInsights(ctx context.Context, req PrebuildClaimInsightsRequest) (PrebuildClaimInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/prebuilds?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return PrebuildClaimInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return PrebuildClaimInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result PrebuildClaimInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// AutostopInsightsResponse is the response from the autostop insights
+// endpoint.
+type AutostopInsightsResponse struct {
+	Report AutostopInsightsReport `json:"report"`
+}
+
+// AutostopInsightsReport quantifies the compute saved by scheduling policies,
+// per template and organization. A workspace stopped by autostop, dormancy or
+// the failure TTL is assumed to have kept running until it was started again
+// without the policy.
+type AutostopInsightsReport struct {
+	StartTime     time.Time                     `json:"start_time" format:"date-time"`
+	EndTime       time.Time                     `json:"end_time" format:"date-time"`
+	TemplateIDs   []uuid.UUID                   `json:"template_ids" format:"uuid"`
+	Templates     []AutostopTemplateInsight     `json:"templates"`
+	Organizations []AutostopOrganizationInsight `json:"organizations"`
+}
+
+// AutostopSavings is the compute time of workspaces during the report period,
+// in hours.
+type AutostopSavings struct {
+	// RunningHours is the time workspaces were running.
+	RunningHours float64 `json:"running_hours"`
+	// AutostopHours, DormancyHours and FailureTTLHours are the time workspaces
+	// were stopped by autostop, dormancy and the failure TTL, respectively.
+	AutostopHours   float64 `json:"autostop_hours"`
+	DormancyHours   float64 `json:"dormancy_hours"`
+	FailureTTLHours float64 `json:"failure_ttl_hours"`
+	// SavedHours is the time workspaces were stopped by any of the policies.
+	SavedHours float64 `json:"saved_hours"`
+	// WouldHaveRunHours is the time workspaces would have run without the
+	// policies, the sum of RunningHours and SavedHours.
+	WouldHaveRunHours float64 `json:"would_have_run_hours"`
+	// SavedRatio is SavedHours divided by WouldHaveRunHours, between 0 and 1.
+	SavedRatio float64 `json:"saved_ratio"`
+}
+
+// AutostopTemplateInsight is the compute saved for the workspaces of a single
+// template.
+type AutostopTemplateInsight struct {
+	AutostopSavings
+	OrganizationID      uuid.UUID `json:"organization_id" format:"uuid"`
+	OrganizationName    string    `json:"organization_name"`
+	TemplateID          uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName        string    `json:"template_name"`
+	TemplateDisplayName string    `json:"template_display_name"`
+}
+
+// AutostopOrganizationInsight is the compute saved for the workspaces of the
+// reported templates of a single organization.
+type AutostopOrganizationInsight struct {
+	AutostopSavings
+	OrganizationID   uuid.UUID `json:"organization_id" format:"uuid"`
+	OrganizationName string    `json:"organization_name"`
+}
+
+type AutostopInsightsRequest struct {
+	StartTime   time.Time   `json:"start_time" format:"date-time"`
+	EndTime     time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+}
+
+func (c *Client) AutostopInsights(ctx context.Context, req AutostopInsightsRequest) (AutostopInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/autostop?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return AutostopInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return AutostopInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result AutostopInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// ActiveDeveloperDaysInsightsResponse is the response from the active
+// developer-days insights endpoint.
+type ActiveDeveloperDaysInsightsResponse struct {
+	Report ActiveDeveloperDaysInsightsReport `json:"report"`
+}
+
+// ActiveDeveloperDaysInsightsReport counts the distinct users with meaningful
+// workspace activity per day, to measure the adoption of templates. A user is
+// active on a day when their SSH, IDE, web terminal and app usage of a template
+// adds up to at least MinActiveMinutes.
+type ActiveDeveloperDaysInsightsReport struct {
+	StartTime        time.Time   `json:"start_time" format:"date-time"`
+	EndTime          time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs      []uuid.UUID `json:"template_ids" format:"uuid"`
+	OrganizationID   uuid.UUID   `json:"organization_id,omitempty" format:"uuid"`
+	MinActiveMinutes int64       `json:"min_active_minutes"`
+	// Days has one entry per day and template with at least one active user.
+	Days []TemplateActiveDevelopers `json:"days"`
+	// Templates sums the active developers of each template over the period.
+	Templates []TemplateActiveDeveloperDays `json:"templates"`
+	// Organizations sums the active developers of each organization over the
+	// period. A user active in several templates of an organization on the
+	// same day is counted once.
+	Organizations []OrganizationActiveDeveloperDays `json:"organizations"`
+}
+
+// TemplateActiveDevelopers is the number of users active in workspaces of a
+// template on a single day.
+type TemplateActiveDevelopers struct {
+	Date             time.Time `json:"date" format:"date-time"`
+	TemplateID       uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName     string    `json:"template_name"`
+	OrganizationID   uuid.UUID `json:"organization_id" format:"uuid"`
+	OrganizationName string    `json:"organization_name"`
+	ActiveDevelopers int64     `json:"active_developers"`
+}
+
+type TemplateActiveDeveloperDays struct {
+	TemplateID          uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName        string    `json:"template_name"`
+	OrganizationID      uuid.UUID `json:"organization_id" format:"uuid"`
+	OrganizationName    string    `json:"organization_name"`
+	ActiveDeveloperDays int64     `json:"active_developer_days"`
+}
+
+type OrganizationActiveDeveloperDays struct {
+	OrganizationID      uuid.UUID `json:"organization_id" format:"uuid"`
+	OrganizationName    string    `json:"organization_name"`
+	ActiveDeveloperDays int64     `json:"active_developer_days"`
+}
+
+type ActiveDeveloperDaysInsightsRequest struct {
+	StartTime      time.Time   `json:"start_time" format:"date-time"`
+	EndTime        time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs    []uuid.UUID `json:"template_ids" format:"uuid"`
+	OrganizationID uuid.UUID   `json:"organization_id" format:"uuid"`
+	// MinActiveMinutes is the usage a user needs on a day to count as active.
+	// Defaults to 1 minute when zero.
+	MinActiveMinutes int64 `json:"min_active_minutes"`
+}
+
+func (c *Client) ActiveDeveloperDaysInsights(ctx context.Context, req ActiveDeveloperDaysInsightsRequest) (ActiveDeveloperDaysInsightsResponse, error) {
+	reqURL := fmt.Sprintf("/api/v2/insights/developer-days?%s", req.queryParams().Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return ActiveDeveloperDaysInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ActiveDeveloperDaysInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result ActiveDeveloperDaysInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// ActiveDeveloperDaysInsightsCSV returns the days of
+// ActiveDeveloperDaysInsights as CSV, one row per day and template. The caller
+// must close the reader.
+func (c *Client) ActiveDeveloperDaysInsightsCSV(ctx context.Context, req ActiveDeveloperDaysInsightsRequest) (io.ReadCloser, error) {
+	qp := req.queryParams()
+	qp.Add("format", "csv")
+	reqURL := fmt.Sprintf("/api/v2/insights/developer-days?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, ReadBodyAsError(resp)
+	}
+	return resp.Body, nil
+}
+
+func (req ActiveDeveloperDaysInsightsRequest) queryParams() url.Values {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+	if req.OrganizationID != uuid.Nil {
+		qp.Add("organization_id", req.OrganizationID.String())
+	}
+	if req.MinActiveMinutes > 0 {
+		qp.Add("min_active_minutes", strconv.FormatInt(req.MinActiveMinutes, 10))
+	}
+	return qp
+}
@@ -3,6 +3,7 @@ package codersdk
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"golang.org/x/xerrors"
@@ -10,6 +11,18 @@ import (
 	"github.com/coder/coder/v2/coderd/util/namesgenerator"
 )
 
+// NameMaxLength is the maximum length of a name accepted by NameValid.
+const NameMaxLength = 32
+
+// reservedNames cannot be used as names because they conflict with routes
+// like /templates/new and /groups/create.
+var reservedNames = []string{"new", "create"}
+
+// ReservedNames returns the names rejected by NameValid.
+func ReservedNames() []string {
+	return slices.Clone(reservedNames)
+}
+
 var (
 	UsernameValidRegex = regexp.MustCompile("^[a-zA-Z0-9]+(?:-[a-zA-Z0-9]+)*$")
 	usernameReplace    = regexp.MustCompile("[^a-zA-Z0-9-]*")
@@ -42,14 +55,13 @@ func UsernameFrom(str string) string {
 // NameValid returns whether the input string is a valid name.
 // It is a generic validator for any name (user, workspace, template, role name, etc.).
 func NameValid(str string) error {
-	if len(str) > 32 {
-		return xerrors.New("must be <= 32 characters")
+	if len(str) > NameMaxLength {
+		return xerrors.Errorf("must be <= %d characters", NameMaxLength)
 	}
 	if len(str) < 1 {
 		return xerrors.New("must be >= 1 character")
 	}
-	// Avoid conflicts with routes like /templates/new and /groups/create.
-	if str == "new" || str == "create" {
+	if slices.Contains(reservedNames, str) {
 		return xerrors.Errorf("cannot use %q as a name", str)
 	}
 	matched := UsernameValidRegex.MatchString(str)
@@ -0,0 +1,170 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NetworkPolicySource describes where the effective outbound network policy
+// of a workspace is declared.
+type NetworkPolicySource string
+
+const (
+	// NetworkPolicySourceNone means no policy is declared and outbound
+	// traffic is not restricted.
+	NetworkPolicySourceNone      NetworkPolicySource = "none"
+	NetworkPolicySourceTemplate  NetworkPolicySource = "template"
+	NetworkPolicySourceWorkspace NetworkPolicySource = "workspace"
+)
+
+// TemplateNetworkPolicy is the default outbound network policy of the
+// workspaces created from a template.
+type TemplateNetworkPolicy struct {
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	// AllowedDomains are hostnames workspaces may connect to. A leading
+	// "*." matches any subdomain.
+	AllowedDomains []string  `json:"allowed_domains"`
+	AllowedCIDRs   []string  `json:"allowed_cidrs"`
+	UpdatedAt      time.Time `json:"updated_at" format:"date-time"`
+}
+
+// WorkspaceNetworkPolicy is the effective outbound network policy of a
+// workspace. Enforcement agents such as CNI plugins or egress proxies should
+// deny any destination that is not allowed, unless Source is "none".
+type WorkspaceNetworkPolicy struct {
+	WorkspaceID    uuid.UUID           `json:"workspace_id" format:"uuid"`
+	Source         NetworkPolicySource `json:"source" enums:"none,template,workspace"`
+	AllowedDomains []string            `json:"allowed_domains"`
+	AllowedCIDRs   []string            `json:"allowed_cidrs"`
+	// UpdatedAt is omitted when Source is "none".
+	UpdatedAt *time.Time `json:"updated_at,omitempty" format:"date-time"`
+}
+
+// UpdateNetworkPolicyRequest replaces the outbound network policy of a
+// template or workspace. An empty policy denies all outbound traffic.
+type UpdateNetworkPolicyRequest struct {
+	AllowedDomains []string `json:"allowed_domains"`
+	AllowedCIDRs   []string `json:"allowed_cidrs"`
+}
+
+// WorkspaceAgentNetworkPolicyViolation is a connection to a destination that
+// is not allowed by the workspace network policy, as reported by the agent.
+type WorkspaceAgentNetworkPolicyViolation struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	AgentID   uuid.UUID `json:"agent_id" format:"uuid"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	// Destination is the hostname or IP address of the blocked connection.
+	Destination string `json:"destination"`
+	Port        int32  `json:"port,omitempty"`
+	// Count is the number of blocked connections to the destination.
+	Count int32 `json:"count"`
+}
+
+// TemplateNetworkPolicy returns the outbound network policy of a template.
+func (c *Client) TemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) (TemplateNetworkPolicy, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/network-policy", templateID), nil)
+	if err != nil {
+		return TemplateNetworkPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateNetworkPolicy{}, ReadBodyAsError(res)
+	}
+	var np TemplateNetworkPolicy
+	return np, json.NewDecoder(res.Body).Decode(&np)
+}
+
+// UpdateTemplateNetworkPolicy declares or replaces the outbound network
+// policy of a template.
+func (c *Client) UpdateTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID, req UpdateNetworkPolicyRequest) (TemplateNetworkPolicy, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/network-policy", templateID), req)
+	if err != nil {
+		return TemplateNetworkPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateNetworkPolicy{}, ReadBodyAsError(res)
+	}
+	var np TemplateNetworkPolicy
+	return np, json.NewDecoder(res.Body).Decode(&np)
+}
+
+// DeleteTemplateNetworkPolicy removes the outbound network policy of a
+// template.
+func (c *Client) DeleteTemplateNetworkPolicy(ctx context.Context, templateID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/network-policy", templateID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceNetworkPolicy returns the effective outbound network policy of a
+// workspace.
+func (c *Client) WorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) (WorkspaceNetworkPolicy, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/network-policy", workspaceID), nil)
+	if err != nil {
+		return WorkspaceNetworkPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceNetworkPolicy{}, ReadBodyAsError(res)
+	}
+	var np WorkspaceNetworkPolicy
+	return np, json.NewDecoder(res.Body).Decode(&np)
+}
+
+// UpdateWorkspaceNetworkPolicy declares or replaces the outbound network
+// policy of a workspace, overriding the template policy. Only template
+// administrators may override the template policy.
+func (c *Client) UpdateWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID, req UpdateNetworkPolicyRequest) (WorkspaceNetworkPolicy, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaces/%s/network-policy", workspaceID), req)
+	if err != nil {
+		return WorkspaceNetworkPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceNetworkPolicy{}, ReadBodyAsError(res)
+	}
+	var np WorkspaceNetworkPolicy
+	return np, json.NewDecoder(res.Body).Decode(&np)
+}
+
+// DeleteWorkspaceNetworkPolicy removes the workspace override, so the
+// template policy applies again.
+func (c *Client) DeleteWorkspaceNetworkPolicy(ctx context.Context, workspaceID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/network-policy", workspaceID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceNetworkPolicyViolations returns the violations reported by the
+// agents of the latest build of a workspace within the last day, newest
+// first.
+func (c *Client) WorkspaceNetworkPolicyViolations(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentNetworkPolicyViolation, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/network-policy/violations", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var violations []WorkspaceAgentNetworkPolicyViolation
+	return violations, json.NewDecoder(res.Body).Decode(&violations)
+}
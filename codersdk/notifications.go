@@ -40,6 +40,27 @@ type NotificationPreference struct {
 	UpdatedAt              time.Time `json:"updated_at" format:"date-time"`
 }
 
+// NotificationPreferenceScope is the kind of resource that a scoped
+// notification preference applies to.
+type NotificationPreferenceScope string
+
+const (
+	NotificationPreferenceScopeWorkspace NotificationPreferenceScope = "workspace"
+	NotificationPreferenceScopeTemplate  NotificationPreferenceScope = "template"
+)
+
+// ScopedNotificationPreference enables or disables a notification template
+// for the messages about a single workspace or template. It takes precedence
+// over the NotificationPreference of the template, and a workspace preference
+// takes precedence over the preference for its template.
+type ScopedNotificationPreference struct {
+	NotificationTemplateID uuid.UUID                   `json:"notification_template_id" format:"uuid"`
+	Scope                  NotificationPreferenceScope `json:"scope" enums:"workspace,template"`
+	TargetID               uuid.UUID                   `json:"target_id" format:"uuid"`
+	Disabled               bool                        `json:"disabled"`
+	UpdatedAt              time.Time                   `json:"updated_at" format:"date-time"`
+}
+
 // GetNotificationsSettings retrieves the notifications settings, which currently just describes whether all
 // notifications are paused from sending.
 func (c *Client) GetNotificationsSettings(ctx context.Context) (NotificationsSettings, error) {
@@ -169,6 +190,41 @@ func (c *Client) UpdateUserNotificationPreferences(ctx context.Context, userID u
 	return prefs, nil
 }
 
+// ScopedNotificationPreferences retrieves the workspace and template scoped
+// notification preferences of a user.
+func (c *Client) ScopedNotificationPreferences(ctx context.Context, user string) ([]ScopedNotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notification-preferences", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var prefs []ScopedNotificationPreference
+	return prefs, json.NewDecoder(res.Body).Decode(&prefs)
+}
+
+// UpdateScopedNotificationPreferences creates, modifies or removes workspace
+// and template scoped notification preferences of a user, and returns all of
+// them.
+func (c *Client) UpdateScopedNotificationPreferences(ctx context.Context, user string, req UpdateScopedNotificationPreferences) ([]ScopedNotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notification-preferences", user), req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var prefs []ScopedNotificationPreference
+	return prefs, json.NewDecoder(res.Body).Decode(&prefs)
+}
+
 // GetNotificationDispatchMethods the available and default notification dispatch methods.
 func (c *Client) GetNotificationDispatchMethods(ctx context.Context) (NotificationMethodsResponse, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/notifications/dispatch-methods", nil)
@@ -215,6 +271,20 @@ type UpdateUserNotificationPreferences struct {
 	TemplateDisabledMap map[string]bool `json:"template_disabled_map"`
 }
 
+type UpdateScopedNotificationPreference struct {
+	NotificationTemplateID uuid.UUID                   `json:"notification_template_id" format:"uuid"`
+	Scope                  NotificationPreferenceScope `json:"scope" enums:"workspace,template"`
+	TargetID               uuid.UUID                   `json:"target_id" format:"uuid"`
+	// Disabled mutes the notification template for the target. Omitting it
+	// removes the preference, so that the preference for the notification
+	// template applies again.
+	Disabled *bool `json:"disabled,omitempty"`
+}
+
+type UpdateScopedNotificationPreferences struct {
+	Preferences []UpdateScopedNotificationPreference `json:"preferences"`
+}
+
 type WebpushMessageAction struct {
 	Label string `json:"label"`
 	URL   string `json:"url"`
@@ -0,0 +1,144 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationTemplateWebhook overrides how the messages of a notification
+// template are delivered by the webhook method.
+type NotificationTemplateWebhook struct {
+	NotificationTemplateID uuid.UUID `json:"notification_template_id" format:"uuid"`
+	// Endpoint receives the messages of the template. Empty uses the
+	// deployment webhook endpoint.
+	Endpoint string `json:"endpoint"`
+	// PayloadTemplate is a Go template rendering the request body from the
+	// default webhook payload. Empty sends the default payload.
+	PayloadTemplate string `json:"payload_template"`
+	// HasSigningSecret is true when request bodies are signed. The secret
+	// itself is never returned.
+	HasSigningSecret bool      `json:"has_signing_secret"`
+	UpdatedAt        time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpdateNotificationTemplateWebhookRequest replaces the webhook overrides of
+// a notification template.
+type UpdateNotificationTemplateWebhookRequest struct {
+	Endpoint        string `json:"endpoint,omitempty" format:"uri"`
+	PayloadTemplate string `json:"payload_template,omitempty"`
+	// SigningSecret is used to sign request bodies with HMAC-SHA256. The
+	// signature is sent in the X-Coder-Signature header as "sha256=<hex>".
+	// Omit it to keep the current secret, or set it to an empty string to
+	// stop signing requests.
+	SigningSecret *string `json:"signing_secret,omitempty"`
+}
+
+type NotificationMessageStatus string
+
+const (
+	NotificationMessageStatusPending          NotificationMessageStatus = "pending"
+	NotificationMessageStatusLeased           NotificationMessageStatus = "leased"
+	NotificationMessageStatusSent             NotificationMessageStatus = "sent"
+	NotificationMessageStatusPermanentFailure NotificationMessageStatus = "permanent_failure"
+	NotificationMessageStatusTemporaryFailure NotificationMessageStatus = "temporary_failure"
+	NotificationMessageStatusUnknown          NotificationMessageStatus = "unknown"
+	NotificationMessageStatusInhibited        NotificationMessageStatus = "inhibited"
+)
+
+// NotificationMessageDelivery is the delivery status of a notification
+// message. Messages failing temporarily are retried until the deployment
+// maximum number of send attempts is reached.
+type NotificationMessageDelivery struct {
+	ID           uuid.UUID                 `json:"id" format:"uuid"`
+	UserID       uuid.UUID                 `json:"user_id" format:"uuid"`
+	Method       string                    `json:"method" enums:"smtp,webhook,inbox"`
+	Status       NotificationMessageStatus `json:"status" enums:"pending,leased,sent,permanent_failure,temporary_failure,unknown,inhibited"`
+	StatusReason string                    `json:"status_reason,omitempty"`
+	AttemptCount int32                     `json:"attempt_count"`
+	CreatedAt    time.Time                 `json:"created_at" format:"date-time"`
+	UpdatedAt    *time.Time                `json:"updated_at,omitempty" format:"date-time"`
+	// NextRetryAfter is set when a failed delivery will be retried.
+	NextRetryAfter *time.Time `json:"next_retry_after,omitempty" format:"date-time"`
+}
+
+// NotificationMessageDeliveriesRequest filters the messages returned by
+// NotificationTemplateDeliveries.
+type NotificationMessageDeliveriesRequest struct {
+	// Method only returns the messages delivered by the given method.
+	Method string
+	// Limit defaults to 50 and may not exceed 500.
+	Limit int
+}
+
+// NotificationTemplateWebhook returns the webhook overrides of a
+// notification template.
+func (c *Client) NotificationTemplateWebhook(ctx context.Context, notificationTemplateID uuid.UUID) (NotificationTemplateWebhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/notifications/templates/%s/webhook", notificationTemplateID), nil)
+	if err != nil {
+		return NotificationTemplateWebhook{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return NotificationTemplateWebhook{}, ReadBodyAsError(res)
+	}
+	var webhook NotificationTemplateWebhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+// UpdateNotificationTemplateWebhook declares or replaces the webhook
+// overrides of a notification template.
+func (c *Client) UpdateNotificationTemplateWebhook(ctx context.Context, notificationTemplateID uuid.UUID, req UpdateNotificationTemplateWebhookRequest) (NotificationTemplateWebhook, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/notifications/templates/%s/webhook", notificationTemplateID), req)
+	if err != nil {
+		return NotificationTemplateWebhook{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return NotificationTemplateWebhook{}, ReadBodyAsError(res)
+	}
+	var webhook NotificationTemplateWebhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+// DeleteNotificationTemplateWebhook removes the webhook overrides of a
+// notification template, so the deployment configuration applies again.
+func (c *Client) DeleteNotificationTemplateWebhook(ctx context.Context, notificationTemplateID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/notifications/templates/%s/webhook", notificationTemplateID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// NotificationTemplateDeliveries returns the delivery status of the most
+// recent messages of a notification template, newest first.
+func (c *Client) NotificationTemplateDeliveries(ctx context.Context, notificationTemplateID uuid.UUID, req NotificationMessageDeliveriesRequest) ([]NotificationMessageDelivery, error) {
+	q := url.Values{}
+	if req.Method != "" {
+		q.Set("method", req.Method)
+	}
+	if req.Limit > 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/notifications/templates/%s/deliveries?%s", notificationTemplateID, q.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var deliveries []NotificationMessageDelivery
+	return deliveries, json.NewDecoder(res.Body).Decode(&deliveries)
+}
@@ -0,0 +1,88 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OffboardingPreviewRequest selects the users to preview offboarding for.
+// Users can be selected by ID, by the membership of a group, such as a group
+// synced from the identity provider, or both.
+type OffboardingPreviewRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids,omitempty" format:"uuid"`
+	GroupID *uuid.UUID  `json:"group_id,omitempty" format:"uuid"`
+}
+
+// OffboardingPreview lists everything that is affected by offboarding the
+// selected users. Nothing is changed by a preview.
+type OffboardingPreview struct {
+	GeneratedAt time.Time                `json:"generated_at" format:"date-time"`
+	Users       []OffboardingPreviewUser `json:"users"`
+}
+
+// OffboardingPreviewUser lists the resources of a single user that are
+// affected by offboarding them.
+type OffboardingPreviewUser struct {
+	UserID   uuid.UUID  `json:"user_id" format:"uuid"`
+	Username string     `json:"username"`
+	Email    string     `json:"email"`
+	Status   UserStatus `json:"status"`
+	// Workspaces are the workspaces owned by the user.
+	Workspaces []OffboardingPreviewWorkspace `json:"workspaces"`
+	// Templates are the templates created by the user.
+	Templates []OffboardingPreviewTemplate `json:"templates"`
+	// Tokens are the unexpired API tokens of the user.
+	Tokens []OffboardingPreviewToken `json:"tokens"`
+	// ExternalAuthLinks are the external auth providers, such as git
+	// providers, the user has linked.
+	ExternalAuthLinks []OffboardingPreviewExternalAuthLink `json:"external_auth_links"`
+}
+
+type OffboardingPreviewWorkspace struct {
+	ID                    uuid.UUID           `json:"id" format:"uuid"`
+	Name                  string              `json:"name"`
+	OrganizationName      string              `json:"organization_name"`
+	TemplateName          string              `json:"template_name"`
+	LatestBuildTransition WorkspaceTransition `json:"latest_build_transition" enums:"start,stop,delete"`
+	LastUsedAt            time.Time           `json:"last_used_at" format:"date-time"`
+	DormantAt             *time.Time          `json:"dormant_at,omitempty" format:"date-time"`
+}
+
+type OffboardingPreviewTemplate struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	Name           string    `json:"name"`
+	DisplayName    string    `json:"display_name"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+}
+
+type OffboardingPreviewToken struct {
+	ID        string    `json:"id"`
+	TokenName string    `json:"token_name"`
+	LastUsed  time.Time `json:"last_used" format:"date-time"`
+	ExpiresAt time.Time `json:"expires_at" format:"date-time"`
+}
+
+type OffboardingPreviewExternalAuthLink struct {
+	ProviderID string    `json:"provider_id"`
+	CreatedAt  time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt  time.Time `json:"updated_at" format:"date-time"`
+}
+
+// OffboardingPreview returns everything that would be affected by offboarding
+// the selected users, without changing anything.
+func (c *Client) OffboardingPreview(ctx context.Context, req OffboardingPreviewRequest) (OffboardingPreview, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/admin/offboarding/preview", req)
+	if err != nil {
+		return OffboardingPreview{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OffboardingPreview{}, ReadBodyAsError(res)
+	}
+	var preview OffboardingPreview
+	return preview, json.NewDecoder(res.Body).Decode(&preview)
+}
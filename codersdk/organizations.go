@@ -229,6 +229,19 @@ type CreateTemplateRequest struct {
 
 	// CORSBehavior allows optionally specifying the CORS behavior for all shared ports.
 	CORSBehavior *CORSBehavior `json:"cors_behavior"`
+
+	// ProvisionerPlanTimeoutMillis allows optionally limiting the duration of
+	// template version import and dry-run jobs for the template.
+	ProvisionerPlanTimeoutMillis *int64 `json:"provisioner_plan_timeout_ms,omitempty"`
+
+	// ProvisionerApplyTimeoutMillis allows optionally limiting the duration of
+	// workspace build jobs for the template.
+	ProvisionerApplyTimeoutMillis *int64 `json:"provisioner_apply_timeout_ms,omitempty"`
+
+	// TrialWorkspaceTTLMillis allows optionally giving workspaces created from
+	// the template a hard lifetime, after which they are stopped and then
+	// deleted regardless of activity.
+	TrialWorkspaceTTLMillis *int64 `json:"trial_workspace_ttl_ms,omitempty"`
 }
 
 // CreateWorkspaceRequest provides options for creating a new workspace.
@@ -256,6 +269,19 @@ type CreateWorkspaceRequest struct {
 	RichParameterValues     []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
 	AutomaticUpdates        AutomaticUpdates          `json:"automatic_updates,omitempty"`
 	TemplateVersionPresetID uuid.UUID                 `json:"template_version_preset_id,omitempty" format:"uuid"`
+	// UserPresetID applies the parameter values of a preset the user saved
+	// for the template. Values in RichParameterValues take precedence.
+	UserPresetID uuid.UUID `json:"user_preset_id,omitempty" format:"uuid"`
+	// RegionLatenciesMS are the latencies in milliseconds the client measured
+	// to each region, keyed by region ID. If the template has
+	// auto_assign_region set, they are used to assign a region to the
+	// workspace.
+	RegionLatenciesMS map[uuid.UUID]int64 `json:"region_latencies_ms,omitempty"`
+	// IdempotencyKey makes retries of the request with the same key return
+	// the workspace created by the original request for 24 hours, instead
+	// of creating another one. It may also be set with the Idempotency-Key
+	// header.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 func (c *Client) OrganizationByName(ctx context.Context, name string) (Organization, error) {
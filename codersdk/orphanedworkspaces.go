@@ -0,0 +1,95 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrphanedWorkspaceReason is the reason a workspace can no longer be managed
+// by its owner.
+type OrphanedWorkspaceReason string
+
+const (
+	// OrphanedWorkspaceReasonOwnerDeleted is used when the owner of the
+	// workspace has been deleted.
+	OrphanedWorkspaceReasonOwnerDeleted OrphanedWorkspaceReason = "owner_deleted"
+	// OrphanedWorkspaceReasonOwnerNotMember is used when the owner of the
+	// workspace is no longer a member of the organization of the workspace,
+	// such as after their identity provider account was merged into another.
+	OrphanedWorkspaceReasonOwnerNotMember OrphanedWorkspaceReason = "owner_not_member"
+)
+
+// OrphanedWorkspace is a workspace whose owner was removed outside of the
+// normal offboarding flow. It keeps its resources until it is adopted by
+// another user or deleted.
+type OrphanedWorkspace struct {
+	ID               uuid.UUID               `json:"id" format:"uuid"`
+	Name             string                  `json:"name"`
+	OrganizationID   uuid.UUID               `json:"organization_id" format:"uuid"`
+	OrganizationName string                  `json:"organization_name"`
+	TemplateID       uuid.UUID               `json:"template_id" format:"uuid"`
+	TemplateName     string                  `json:"template_name"`
+	OwnerID          uuid.UUID               `json:"owner_id" format:"uuid"`
+	OwnerUsername    string                  `json:"owner_username"`
+	Reason           OrphanedWorkspaceReason `json:"reason" enums:"owner_deleted,owner_not_member"`
+	CreatedAt        time.Time               `json:"created_at" format:"date-time"`
+	LastUsedAt       time.Time               `json:"last_used_at" format:"date-time"`
+	DormantAt        *time.Time              `json:"dormant_at,omitempty" format:"date-time"`
+}
+
+// AdoptOrphanedWorkspaceRequest transfers an orphaned workspace to a new
+// owner.
+type AdoptOrphanedWorkspaceRequest struct {
+	OwnerID uuid.UUID `json:"owner_id" validate:"required" format:"uuid"`
+	// Name is the name of the workspace after the transfer. It defaults to
+	// the current name, and must be set when the new owner already has a
+	// workspace with that name.
+	Name string `json:"name,omitempty"`
+}
+
+// OrphanedWorkspaces returns the workspaces whose owner was deleted, or is no
+// longer a member of the organization of the workspace.
+func (c *Client) OrphanedWorkspaces(ctx context.Context) ([]OrphanedWorkspace, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/admin/orphaned-workspaces", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var workspaces []OrphanedWorkspace
+	return workspaces, json.NewDecoder(res.Body).Decode(&workspaces)
+}
+
+// AdoptOrphanedWorkspace transfers an orphaned workspace to a new owner.
+func (c *Client) AdoptOrphanedWorkspace(ctx context.Context, id uuid.UUID, req AdoptOrphanedWorkspaceRequest) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/admin/orphaned-workspaces/%s/adopt", id), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// DeleteOrphanedWorkspace starts a build that deletes an orphaned workspace.
+func (c *Client) DeleteOrphanedWorkspace(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/admin/orphaned-workspaces/%s/delete", id), nil)
+	if err != nil {
+		return WorkspaceBuild{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var build WorkspaceBuild
+	return build, json.NewDecoder(res.Body).Decode(&build)
+}
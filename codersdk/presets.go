@@ -38,3 +38,49 @@ func (c *Client) TemplateVersionPresets(ctx context.Context, templateVersionID u
 	var presets []Preset
 	return presets, json.NewDecoder(res.Body).Decode(&presets)
 }
+
+// TemplatePresetCohort restricts the presets with a given name, in every
+// version of a template, to users who are members of any of the groups or
+// have any of the roles. Presets without a cohort are available to everyone
+// who can use the template.
+type TemplatePresetCohort struct {
+	PresetName string      `json:"preset_name" validate:"required"`
+	GroupIDs   []uuid.UUID `json:"group_ids" format:"uuid"`
+	// Roles are names of site-wide roles, or of roles in the organization of
+	// the template.
+	Roles []string `json:"roles"`
+}
+
+// UpdateTemplatePresetCohortsRequest replaces the preset cohorts of a
+// template. Presets left out are made available to everyone.
+type UpdateTemplatePresetCohortsRequest struct {
+	Cohorts []TemplatePresetCohort `json:"cohorts"`
+}
+
+// TemplatePresetCohorts returns the preset cohorts of a template.
+func (c *Client) TemplatePresetCohorts(ctx context.Context, templateID uuid.UUID) ([]TemplatePresetCohort, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/preset-cohorts", templateID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var cohorts []TemplatePresetCohort
+	return cohorts, json.NewDecoder(res.Body).Decode(&cohorts)
+}
+
+// UpdateTemplatePresetCohorts replaces the preset cohorts of a template.
+func (c *Client) UpdateTemplatePresetCohorts(ctx context.Context, templateID uuid.UUID, req UpdateTemplatePresetCohortsRequest) ([]TemplatePresetCohort, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/preset-cohorts", templateID), req)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var cohorts []TemplatePresetCohort
+	return cohorts, json.NewDecoder(res.Body).Decode(&cohorts)
+}
@@ -0,0 +1,87 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ProvisionerCacheArtifact is a kind of artifact that provisioners can reuse
+// from a cache instead of downloading it during every build.
+type ProvisionerCacheArtifact string
+
+const (
+	// ProvisionerCacheArtifactProviderPlugin is a Terraform provider plugin,
+	// cached in the shared plugin cache directory of a provisioner.
+	ProvisionerCacheArtifactProviderPlugin ProvisionerCacheArtifact = "provider_plugin"
+	// ProvisionerCacheArtifactModule is a Terraform module, cached in coderd
+	// when a template version is imported.
+	ProvisionerCacheArtifactModule ProvisionerCacheArtifact = "module"
+)
+
+// ProvisionerCacheArtifacts lists every kind of cacheable artifact.
+var ProvisionerCacheArtifacts = []ProvisionerCacheArtifact{
+	ProvisionerCacheArtifactProviderPlugin,
+	ProvisionerCacheArtifactModule,
+}
+
+// Provisioners report the artifacts they obtain during the init stage of a
+// build as provisioner timings, with the kind of the artifact as the source
+// and one of these actions.
+const (
+	ProvisionerTimingActionCacheHit  = "cache hit"
+	ProvisionerTimingActionCacheMiss = "cache miss"
+)
+
+// ProvisionerCacheSettings controls the artifact caches used by the workspace
+// builds of every template. Templates can disable each cache individually.
+type ProvisionerCacheSettings struct {
+	// DisableProviderCache makes provisioners install Terraform providers
+	// from their registries instead of their shared plugin cache.
+	DisableProviderCache bool `json:"disable_provider_cache"`
+	// DisableModuleCache makes provisioners download Terraform modules
+	// instead of using the modules cached when template versions are
+	// imported.
+	DisableModuleCache bool `json:"disable_module_cache"`
+}
+
+// ProvisionerCacheStats counts the artifacts of a kind that were reused from
+// a cache, and the ones that had to be downloaded, during a build.
+type ProvisionerCacheStats struct {
+	Artifact ProvisionerCacheArtifact `json:"artifact" enums:"provider_plugin,module"`
+	Hits     int64                    `json:"hits"`
+	Misses   int64                    `json:"misses"`
+}
+
+// ProvisionerCacheSettings returns the deployment-wide provisioner cache
+// settings.
+func (c *Client) ProvisionerCacheSettings(ctx context.Context) (ProvisionerCacheSettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/provisioner-cache", nil)
+	if err != nil {
+		return ProvisionerCacheSettings{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ProvisionerCacheSettings{}, ReadBodyAsError(res)
+	}
+	var settings ProvisionerCacheSettings
+	return settings, json.NewDecoder(res.Body).Decode(&settings)
+}
+
+// PutProvisionerCacheSettings modifies the deployment-wide provisioner cache
+// settings. They apply to jobs acquired after the change.
+func (c *Client) PutProvisionerCacheSettings(ctx context.Context, settings ProvisionerCacheSettings) error {
+	res, err := c.Request(ctx, http.MethodPut, "/api/v2/deployment/provisioner-cache", settings)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -168,6 +168,11 @@ type JobErrorCode string
 const (
 	RequiredTemplateVariables JobErrorCode = "REQUIRED_TEMPLATE_VARIABLES"
 	InsufficientQuota         JobErrorCode = "INSUFFICIENT_QUOTA"
+	// JobHung, JobPendingTimeout, and ProvisionerLost are set by the job
+	// reaper when it terminates a job that stopped making progress.
+	JobHung           JobErrorCode = "JOB_HUNG"
+	JobPendingTimeout JobErrorCode = "JOB_PENDING_TIMEOUT"
+	ProvisionerLost   JobErrorCode = "PROVISIONER_LOST"
 )
 
 // JobIsMissingParameterErrorCode returns whether the error is a missing parameter error.
@@ -197,7 +202,7 @@ type ProvisionerJob struct {
 	CompletedAt      *time.Time             `json:"completed_at,omitempty" format:"date-time" table:"completed at"`
 	CanceledAt       *time.Time             `json:"canceled_at,omitempty" format:"date-time" table:"canceled at"`
 	Error            string                 `json:"error,omitempty" table:"error"`
-	ErrorCode        JobErrorCode           `json:"error_code,omitempty" enums:"REQUIRED_TEMPLATE_VARIABLES,INSUFFICIENT_QUOTA" table:"error code"`
+	ErrorCode        JobErrorCode           `json:"error_code,omitempty" enums:"REQUIRED_TEMPLATE_VARIABLES,INSUFFICIENT_QUOTA,JOB_HUNG,JOB_PENDING_TIMEOUT,PROVISIONER_LOST" table:"error code"`
 	Status           ProvisionerJobStatus   `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed" table:"status"`
 	WorkerID         *uuid.UUID             `json:"worker_id,omitempty" format:"uuid" table:"worker id"`
 	WorkerName       string                 `json:"worker_name,omitempty" table:"worker name"`
@@ -375,6 +380,12 @@ type ProvisionerKey struct {
 	OrganizationID uuid.UUID          `json:"organization" table:"-" format:"uuid"`
 	Name           string             `json:"name" table:"name,default_sort"`
 	Tags           ProvisionerKeyTags `json:"tags" table:"tags"`
+	// LastUsedAt is the last time a provisioner daemon authenticated with
+	// the key.
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" table:"last used at" format:"date-time"`
+	// PreviousKeyExpiresAt is set while the key the provisioner key was
+	// rotated away from is still valid.
+	PreviousKeyExpiresAt *time.Time `json:"previous_key_expires_at,omitempty" table:"-" format:"date-time"`
 	// HashedSecret - never include the access token in the API response
 }
 
@@ -492,7 +503,40 @@ func (c *Client) ListProvisionerKeyDaemons(ctx context.Context, organizationID u
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
-// DeleteProvisionerKey deletes a provisioner key.
+// RotateProvisionerKeyRequest rotates the secret of a provisioner key.
+type RotateProvisionerKeyRequest struct {
+	// GracePeriodMillis is how long the previous key stays valid for, so
+	// that daemons can be moved to the new key without downtime. Daemons
+	// still connected with the previous key are drained once it expires.
+	// Zero invalidates the previous key immediately.
+	GracePeriodMillis int64 `json:"grace_period_ms"`
+}
+
+type RotateProvisionerKeyResponse struct {
+	Key                  string    `json:"key"`
+	PreviousKeyExpiresAt time.Time `json:"previous_key_expires_at" format:"date-time"`
+}
+
+// RotateProvisionerKey replaces the secret of a provisioner key.
+func (c *Client) RotateProvisionerKey(ctx context.Context, organizationID uuid.UUID, name string, req RotateProvisionerKeyRequest) (RotateProvisionerKeyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys/%s/rotate", organizationID.String(), name),
+		req,
+	)
+	if err != nil {
+		return RotateProvisionerKeyResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return RotateProvisionerKeyResponse{}, ReadBodyAsError(res)
+	}
+	var resp RotateProvisionerKeyResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteProvisionerKey deletes a provisioner key. Daemons connected with the
+// key finish the jobs they're running and are disconnected.
 func (c *Client) DeleteProvisionerKey(ctx context.Context, organizationID uuid.UUID, name string) error {
 	res, err := c.Request(ctx, http.MethodDelete,
 		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys/%s", organizationID.String(), name),
@@ -542,3 +586,55 @@ func ConvertWorkspaceStatus(jobStatus ProvisionerJobStatus, transition Workspace
 	// return error status since we should never get here
 	return WorkspaceStatusFailed
 }
+
+// ProvisionerDaemonSettings are the limits imposed on the provisioner daemons
+// registered in an organization.
+type ProvisionerDaemonSettings struct {
+	// TagNamespace, when set, requires every tag key of a registering daemon,
+	// apart from the reserved scope and owner tags, to be prefixed with
+	// "<namespace>/".
+	TagNamespace string `json:"tag_namespace"`
+	// DaemonQuota is the maximum number of daemons that may be connected to
+	// the organization at once. Zero means unlimited.
+	DaemonQuota int32 `json:"daemon_quota"`
+	// ConnectedDaemons is the number of daemons currently counted against
+	// the quota.
+	ConnectedDaemons int `json:"connected_daemons"`
+}
+
+type UpdateProvisionerDaemonSettingsRequest struct {
+	TagNamespace string `json:"tag_namespace"`
+	DaemonQuota  int32  `json:"daemon_quota" validate:"min=0"`
+}
+
+// ProvisionerDaemonSettings retrieves the provisioner daemon limits of an
+// organization.
+func (c *Client) ProvisionerDaemonSettings(ctx context.Context, orgID uuid.UUID) (ProvisionerDaemonSettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/settings/provisioner-daemons", orgID), nil)
+	if err != nil {
+		return ProvisionerDaemonSettings{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ProvisionerDaemonSettings{}, ReadBodyAsError(res)
+	}
+	var resp ProvisionerDaemonSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// PatchProvisionerDaemonSettings modifies the provisioner daemon limits of an
+// organization.
+func (c *Client) PatchProvisionerDaemonSettings(ctx context.Context, orgID uuid.UUID, req UpdateProvisionerDaemonSettingsRequest) (ProvisionerDaemonSettings, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s/settings/provisioner-daemons", orgID), req)
+	if err != nil {
+		return ProvisionerDaemonSettings{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ProvisionerDaemonSettings{}, ReadBodyAsError(res)
+	}
+	var resp ProvisionerDaemonSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
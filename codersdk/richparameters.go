@@ -14,6 +14,12 @@ import (
 	"github.com/coder/terraform-provider-coder/v2/provider"
 )
 
+// EvaluateTemplateVersion renders the dynamic parameters of a template version
+// once against the given inputs. The response contains the parameters that are
+// visible for the inputs, with their computed options and validation
+// diagnostics. It is the single round trip equivalent of
+// TemplateVersionDynamicParameters. If ownerID is uuid.Nil, the parameters are
+// evaluated for the authenticated user.
 func (c *Client) EvaluateTemplateVersion(ctx context.Context, templateVersionID uuid.UUID, ownerID uuid.UUID, inputs map[string]string) (DynamicParametersResponse, error) {
 	res, err := c.Request(ctx, http.MethodPost,
 		fmt.Sprintf("/api/v2/templateversions/%s/dynamic-parameters/evaluate", templateVersionID),
@@ -0,0 +1,121 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SharedVolumeAccessMode is how a workspace mounts a shared volume. Only one
+// workspace at a time can attach a volume read-write.
+type SharedVolumeAccessMode string
+
+const (
+	SharedVolumeAccessModeReadOnly  SharedVolumeAccessMode = "read_only"
+	SharedVolumeAccessModeReadWrite SharedVolumeAccessMode = "read_write"
+)
+
+// SharedVolume is a persistent volume of an organization that multiple
+// workspaces can attach, such as a dataset shared by a team.
+type SharedVolume struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	Name           string    `json:"name"`
+	DisplayName    string    `json:"display_name"`
+	Description    string    `json:"description"`
+	// TemplateID is the template that declared the volume, if any.
+	TemplateID      *uuid.UUID `json:"template_id,omitempty" format:"uuid"`
+	AttachmentCount int64      `json:"attachment_count"`
+	// LockedByWorkspaceID is the workspace that attached the volume
+	// read-write, if any.
+	LockedByWorkspaceID *uuid.UUID `json:"locked_by_workspace_id,omitempty" format:"uuid"`
+	CreatedAt           time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt           time.Time  `json:"updated_at" format:"date-time"`
+}
+
+// SharedVolumeAttachment is a shared volume mounted into a workspace agent.
+type SharedVolumeAttachment struct {
+	ID                 uuid.UUID              `json:"id" format:"uuid"`
+	SharedVolumeID     uuid.UUID              `json:"shared_volume_id" format:"uuid"`
+	WorkspaceID        uuid.UUID              `json:"workspace_id" format:"uuid"`
+	WorkspaceName      string                 `json:"workspace_name"`
+	WorkspaceOwnerName string                 `json:"workspace_owner_name"`
+	WorkspaceBuildID   uuid.UUID              `json:"workspace_build_id" format:"uuid"`
+	WorkspaceAgentID   uuid.UUID              `json:"workspace_agent_id" format:"uuid"`
+	WorkspaceAgentName string                 `json:"workspace_agent_name"`
+	MountPath          string                 `json:"mount_path"`
+	AccessMode         SharedVolumeAccessMode `json:"access_mode" enums:"read_only,read_write"`
+	AttachedAt         time.Time              `json:"attached_at" format:"date-time"`
+}
+
+// CreateSharedVolumeRequest declares a shared volume ahead of the templates
+// that attach it. Templates attach volumes by name, and create them on their
+// first build otherwise.
+type CreateSharedVolumeRequest struct {
+	Name        string `json:"name" validate:"required,template_name"`
+	DisplayName string `json:"display_name,omitempty" validate:"omitempty,template_display_name"`
+	Description string `json:"description,omitempty" validate:"lt=128"`
+	// TemplateID associates the volume with a template of the organization.
+	TemplateID *uuid.UUID `json:"template_id,omitempty" format:"uuid"`
+}
+
+// SharedVolumes returns the shared volumes of an organization.
+func (c *Client) SharedVolumes(ctx context.Context, organizationID uuid.UUID) ([]SharedVolume, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/shared-volumes", organizationID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var volumes []SharedVolume
+	return volumes, json.NewDecoder(res.Body).Decode(&volumes)
+}
+
+// CreateSharedVolume creates a shared volume in an organization.
+func (c *Client) CreateSharedVolume(ctx context.Context, organizationID uuid.UUID, req CreateSharedVolumeRequest) (SharedVolume, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/shared-volumes", organizationID), req)
+	if err != nil {
+		return SharedVolume{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return SharedVolume{}, ReadBodyAsError(res)
+	}
+	var volume SharedVolume
+	return volume, json.NewDecoder(res.Body).Decode(&volume)
+}
+
+// SharedVolumeAttachments returns the active attachments of a shared volume.
+func (c *Client) SharedVolumeAttachments(ctx context.Context, organizationID, volumeID uuid.UUID) ([]SharedVolumeAttachment, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/shared-volumes/%s/attachments", organizationID, volumeID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var attachments []SharedVolumeAttachment
+	return attachments, json.NewDecoder(res.Body).Decode(&attachments)
+}
+
+// DetachSharedVolumeAttachment force-detaches a shared volume from a
+// workspace, releasing its read-write lock. The volume stays mounted until
+// the next build of the workspace.
+func (c *Client) DetachSharedVolumeAttachment(ctx context.Context, organizationID, volumeID, attachmentID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/shared-volumes/%s/attachments/%s", organizationID, volumeID, attachmentID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
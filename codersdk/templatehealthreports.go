@@ -0,0 +1,54 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateHealthReport is the health of a template over a period, as sent
+// weekly to template admins.
+type TemplateHealthReport struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	TemplateID  uuid.UUID `json:"template_id" format:"uuid"`
+	PeriodStart time.Time `json:"period_start" format:"date-time"`
+	PeriodEnd   time.Time `json:"period_end" format:"date-time"`
+	// TotalBuilds and FailedBuilds count the builds that completed during
+	// the period.
+	TotalBuilds  int64 `json:"total_builds"`
+	FailedBuilds int64 `json:"failed_builds"`
+	// AverageStartSeconds is the average time successful start builds took
+	// to complete, from when they were queued.
+	AverageStartSeconds float64 `json:"average_start_seconds"`
+	// TotalWorkspaces, OutdatedWorkspaces and DormantWorkspaces count the
+	// workspaces of the template at the end of the period.
+	TotalWorkspaces    int64     `json:"total_workspaces"`
+	OutdatedWorkspaces int64     `json:"outdated_workspaces"`
+	DormantWorkspaces  int64     `json:"dormant_workspaces"`
+	CreatedAt          time.Time `json:"created_at" format:"date-time"`
+}
+
+// TemplateHealthReports returns the past health reports of a template, most
+// recent first. A limit of zero returns all of them.
+func (c *Client) TemplateHealthReports(ctx context.Context, templateID uuid.UUID, limit int) ([]TemplateHealthReport, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/health-reports", templateID), nil, func(r *http.Request) {
+		if limit > 0 {
+			q := r.URL.Query()
+			q.Set("limit", fmt.Sprintf("%d", limit))
+			r.URL.RawQuery = q.Encode()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var reports []TemplateHealthReport
+	return reports, json.NewDecoder(res.Body).Decode(&reports)
+}
@@ -32,14 +32,42 @@ type Template struct {
 	Description        string                 `json:"description"`
 	Deprecated         bool                   `json:"deprecated"`
 	DeprecationMessage string                 `json:"deprecation_message"`
-	Deleted            bool                   `json:"deleted"`
-	Icon               string                 `json:"icon"`
-	DefaultTTLMillis   int64                  `json:"default_ttl_ms"`
-	ActivityBumpMillis int64                  `json:"activity_bump_ms"`
+	// DeprecationCutoff is the time after which the deprecated template can
+	// no longer be used to create workspaces. Until then, builds return a
+	// deprecation warning.
+	DeprecationCutoff  *time.Time `json:"deprecation_cutoff,omitempty" format:"date-time"`
+	Deleted            bool       `json:"deleted"`
+	Icon               string     `json:"icon"`
+	DefaultTTLMillis   int64      `json:"default_ttl_ms"`
+	ActivityBumpMillis int64      `json:"activity_bump_ms"`
+	// ActivityBumpConnectionTypes are the types of connections that bump the
+	// deadline of workspaces. Empty means all connections bump the deadline.
+	ActivityBumpConnectionTypes []ActivityBumpConnectionType `json:"activity_bump_connection_types"`
+	// ActivityBumpMaxPerDayMillis is the maximum cumulative amount activity
+	// may extend the deadline of a workspace per day (UTC). 0 means unlimited.
+	ActivityBumpMaxPerDayMillis int64 `json:"activity_bump_max_per_day_ms"`
 	// TimeTilAutostopNotifyMillis is the duration before the workspace's
 	// autostop deadline at which a reminder notification is sent. 0 disables
 	// the notification.
 	TimeTilAutostopNotifyMillis int64 `json:"time_til_autostop_notify_ms"`
+	// NightlyStopTime is the time of day (HH:MM) at which running workspaces
+	// are stopped regardless of activity. It is interpreted in the timezone of
+	// each owner's quiet hours schedule. Empty means disabled. This is an
+	// enterprise feature.
+	NightlyStopTime string `json:"nightly_stop_time"`
+	// MaxLifetimeMillis is how long after their creation workspaces are
+	// stopped or deleted, according to MaxLifetimeAction, regardless of
+	// activity. 0 means disabled. This is an enterprise feature.
+	MaxLifetimeMillis int64             `json:"max_lifetime_ms"`
+	MaxLifetimeAction MaxLifetimeAction `json:"max_lifetime_action" enums:"delete,stop"`
+	// IdleReclaimTTLMillis is how long running workspaces with a resource
+	// matching IdleReclaimResourceSelector may be idle before they are
+	// stopped. It reclaims expensive resources, such as GPUs, well before the
+	// default TTL. 0 means disabled. This is an enterprise feature.
+	IdleReclaimTTLMillis int64 `json:"idle_reclaim_ttl_ms"`
+	// IdleReclaimResourceSelector is a key=value pair matched against the
+	// metadata of workspace resources, e.g. "gpu=true".
+	IdleReclaimResourceSelector string `json:"idle_reclaim_resource_selector"`
 	// AutostopRequirement and AutostartRequirement are enterprise features. Its
 	// value is only used if your license is entitled to use the advanced template
 	// scheduling feature.
@@ -73,6 +101,50 @@ type Template struct {
 	// DisableModuleCache disables the use of cached Terraform modules during
 	// provisioning.
 	DisableModuleCache bool `json:"disable_module_cache"`
+	// DisableProviderCache disables the use of the shared Terraform provider
+	// cache of provisioners during workspace builds.
+	DisableProviderCache bool `json:"disable_provider_cache"`
+
+	// ProvisionerPlanTimeoutMillis limits the duration of template version
+	// import and dry-run jobs. ProvisionerApplyTimeoutMillis limits the
+	// duration of workspace build jobs. 0 means no timeout.
+	ProvisionerPlanTimeoutMillis  int64 `json:"provisioner_plan_timeout_ms"`
+	ProvisionerApplyTimeoutMillis int64 `json:"provisioner_apply_timeout_ms"`
+
+	// TrialWorkspaceTTLMillis is the hard lifetime of workspaces created from
+	// the template. Expired workspaces are stopped and then deleted regardless
+	// of activity. 0 disables the expiry.
+	TrialWorkspaceTTLMillis int64 `json:"trial_workspace_ttl_ms"`
+	// BuildLogRetentionMillis is how long the logs of workspace builds of the
+	// template are kept before they are archived or deleted. The logs of the
+	// latest build of each workspace are always kept. 0 uses the
+	// deployment-wide retention.
+	BuildLogRetentionMillis int64 `json:"build_log_retention_ms"`
+	// PreBuildHookURL is POSTed to before every workspace build of the
+	// template is provisioned. The hook may reject the build.
+	PreBuildHookURL string `json:"pre_build_hook_url"`
+	// PostBuildHookURL is POSTed to after every successful workspace build
+	// of the template.
+	PostBuildHookURL string `json:"post_build_hook_url"`
+	// AutoAssignRegion assigns workspaces created from the template the
+	// region recommended for the latencies reported by the creating client.
+	AutoAssignRegion bool `json:"auto_assign_region"`
+	// NotifyBuildFailures notifies workspace owners, and the initiators of
+	// builds, of every failed build along with an excerpt of its logs.
+	NotifyBuildFailures bool `json:"notify_build_failures"`
+	// RequeueReapedBuilds requeues workspace builds once when the job reaper
+	// terminates them because their provisioner stopped responding.
+	RequeueReapedBuilds bool `json:"requeue_reaped_builds"`
+	// AgentRolloutChannel is the channel workspace agents of the template
+	// download their binary from on start.
+	AgentRolloutChannel AgentRolloutChannel `json:"agent_rollout_channel" enums:"stable,beta"`
+	// AllowTargetedBuilds allows workspace builds that only replace the
+	// Terraform resources listed in the build request.
+	AllowTargetedBuilds bool `json:"allow_targeted_builds"`
+	// ReconfirmParameters lists the parameters users must explicitly set
+	// again when updating a workspace to a new template version, even if a
+	// value from a previous build exists.
+	ReconfirmParameters []string `json:"reconfirm_parameters"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -182,6 +254,7 @@ type TemplateRole string
 
 const (
 	TemplateRoleAdmin   TemplateRole = "admin"
+	TemplateRoleManage  TemplateRole = "manage"
 	TemplateRoleUse     TemplateRole = "use"
 	TemplateRoleDeleted TemplateRole = ""
 )
@@ -193,12 +266,12 @@ type TemplateACL struct {
 
 type TemplateGroup struct {
 	Group
-	Role TemplateRole `json:"role" enums:"admin,use"`
+	Role TemplateRole `json:"role" enums:"admin,manage,use"`
 }
 
 type TemplateUser struct {
 	User
-	Role TemplateRole `json:"role" enums:"admin,use"`
+	Role TemplateRole `json:"role" enums:"admin,manage,use"`
 }
 
 type UpdateTemplateACL struct {
@@ -219,6 +292,39 @@ type ACLAvailable struct {
 	Groups []Group       `json:"groups"`
 }
 
+// MaxLifetimeAction is what happens to workspaces that exceed the max
+// lifetime of their template.
+type MaxLifetimeAction string
+
+const (
+	MaxLifetimeActionDelete MaxLifetimeAction = "delete"
+	MaxLifetimeActionStop   MaxLifetimeAction = "stop"
+)
+
+// ActivityBumpConnectionType is a type of connection to a workspace that may
+// bump its deadline.
+type ActivityBumpConnectionType string
+
+const (
+	ActivityBumpConnectionTypeSSH             ActivityBumpConnectionType = "ssh"
+	ActivityBumpConnectionTypeVSCode          ActivityBumpConnectionType = "vscode"
+	ActivityBumpConnectionTypeJetBrains       ActivityBumpConnectionType = "jetbrains"
+	ActivityBumpConnectionTypeReconnectingPTY ActivityBumpConnectionType = "reconnecting_pty"
+	ActivityBumpConnectionTypeApp             ActivityBumpConnectionType = "app"
+	ActivityBumpConnectionTypeChat            ActivityBumpConnectionType = "chat"
+)
+
+// AllActivityBumpConnectionTypes contains every ActivityBumpConnectionType
+// value.
+var AllActivityBumpConnectionTypes = []ActivityBumpConnectionType{
+	ActivityBumpConnectionTypeSSH,
+	ActivityBumpConnectionTypeVSCode,
+	ActivityBumpConnectionTypeJetBrains,
+	ActivityBumpConnectionTypeReconnectingPTY,
+	ActivityBumpConnectionTypeApp,
+	ActivityBumpConnectionTypeChat,
+}
+
 // UpdateTemplateMeta is the request body for the PATCH /templates/{template}
 // endpoint. All fields are optional. Fields that are nil are not modified.
 type UpdateTemplateMeta struct {
@@ -231,11 +337,42 @@ type UpdateTemplateMeta struct {
 	// duration for all workspaces created from this template. Defaults to 1h
 	// but can be set to 0 to disable activity bumping.
 	ActivityBumpMillis *int64 `json:"activity_bump_ms,omitempty"`
+	// ActivityBumpConnectionTypes restricts the types of connections that
+	// bump the deadline of workspaces, e.g. to bump on SSH but not on web
+	// apps. Set to an empty list to bump on all connections.
+	ActivityBumpConnectionTypes *[]ActivityBumpConnectionType `json:"activity_bump_connection_types,omitempty"`
+	// ActivityBumpMaxPerDayMillis is the maximum cumulative amount activity
+	// may extend the deadline of a workspace per day (UTC). Set to 0 for no
+	// limit.
+	ActivityBumpMaxPerDayMillis *int64 `json:"activity_bump_max_per_day_ms,omitempty"`
 	// TimeTilAutostopNotifyMillis allows optionally specifying the duration
 	// before the autostop deadline at which a reminder notification is sent for
 	// workspaces created from this template. Defaults to 0 (disabled). Omitting
 	// the field keeps the existing value.
 	TimeTilAutostopNotifyMillis *int64 `json:"time_til_autostop_notify_ms,omitempty"`
+	// NightlyStopTime is the time of day (HH:MM) at which running workspaces
+	// are stopped regardless of activity. Set to the empty string to disable
+	// it. It can only be set if your license includes the advanced template
+	// scheduling feature.
+	NightlyStopTime *string `json:"nightly_stop_time,omitempty"`
+	// MaxLifetimeMillis is how long after their creation workspaces are
+	// stopped or deleted regardless of activity. It must be 0 (disabled) or at
+	// least one day, and applies to existing workspaces too. Owners are warned
+	// 7, 3 and 1 days before. It can only be set if your license includes the
+	// advanced template scheduling feature.
+	MaxLifetimeMillis *int64             `json:"max_lifetime_ms,omitempty"`
+	MaxLifetimeAction *MaxLifetimeAction `json:"max_lifetime_action,omitempty" enums:"delete,stop"`
+	// IdleReclaimTTLMillis is how long running workspaces with a resource
+	// matching IdleReclaimResourceSelector may be idle before they are
+	// stopped. It must be 0 (disabled) or at least one minute, and requires a
+	// resource selector. Idleness is measured from the usage the workspace
+	// agents report. It can only be set if your license includes the advanced
+	// template scheduling feature.
+	IdleReclaimTTLMillis *int64 `json:"idle_reclaim_ttl_ms,omitempty"`
+	// IdleReclaimResourceSelector is a key=value pair matched against the
+	// metadata of workspace resources, e.g. "gpu=true", declared with the
+	// coder_metadata resource.
+	IdleReclaimResourceSelector *string `json:"idle_reclaim_resource_selector,omitempty"`
 	// AutostopRequirement and AutostartRequirement can only be set if your license
 	// includes the advanced template scheduling feature. If you attempt to set this
 	// value while unlicensed, it will be ignored.
@@ -266,6 +403,11 @@ type UpdateTemplateMeta struct {
 	// If passed an empty string, will remove the deprecated message, making
 	// the template usable for new workspaces again.
 	DeprecationMessage *string `json:"deprecation_message,omitempty"`
+	// DeprecationCutoff is applied together with DeprecationMessage. If set,
+	// the deprecated template may still be used to create workspaces until
+	// the cutoff, and builds return a deprecation warning. If unset, new
+	// workspaces are blocked immediately.
+	DeprecationCutoff *time.Time `json:"deprecation_cutoff,omitempty" format:"date-time"`
 	// DisableEveryoneGroupAccess allows optionally disabling the default
 	// behavior of granting the 'everyone' group access to use the template.
 	// If this is set to true, the template will not be available to all users,
@@ -283,6 +425,47 @@ type UpdateTemplateMeta struct {
 	// DisableModuleCache disables the using of cached Terraform modules during
 	// provisioning. It is recommended not to disable this.
 	DisableModuleCache *bool `json:"disable_module_cache,omitempty"`
+	// DisableProviderCache disables the use of the shared Terraform provider
+	// cache of provisioners during workspace builds. Providers are then
+	// installed from their registries on every build.
+	DisableProviderCache *bool `json:"disable_provider_cache,omitempty"`
+	// ProvisionerPlanTimeoutMillis and ProvisionerApplyTimeoutMillis override
+	// the maximum duration of provisioner jobs for the template. 0 removes
+	// the timeout.
+	ProvisionerPlanTimeoutMillis  *int64 `json:"provisioner_plan_timeout_ms,omitempty"`
+	ProvisionerApplyTimeoutMillis *int64 `json:"provisioner_apply_timeout_ms,omitempty"`
+	// TrialWorkspaceTTLMillis overrides the hard lifetime of workspaces
+	// created from the template. It only applies to workspaces created after
+	// the change. 0 disables the expiry.
+	TrialWorkspaceTTLMillis *int64 `json:"trial_workspace_ttl_ms,omitempty"`
+	// BuildLogRetentionMillis overrides how long the logs of workspace builds
+	// of the template are kept. 0 uses the deployment-wide retention.
+	BuildLogRetentionMillis *int64 `json:"build_log_retention_ms,omitempty"`
+	// PreBuildHookURL sets the webhook invoked before every workspace build
+	// of the template. An empty string disables the hook.
+	PreBuildHookURL *string `json:"pre_build_hook_url,omitempty"`
+	// PostBuildHookURL sets the webhook invoked after every successful
+	// workspace build of the template. An empty string disables the hook.
+	PostBuildHookURL *string `json:"post_build_hook_url,omitempty"`
+	// AutoAssignRegion controls whether workspaces created from the template
+	// are assigned the region recommended for the creating client.
+	AutoAssignRegion *bool `json:"auto_assign_region,omitempty"`
+	// NotifyBuildFailures controls whether workspace owners, and the
+	// initiators of builds, are notified of every failed build.
+	NotifyBuildFailures *bool `json:"notify_build_failures,omitempty"`
+	// RequeueReapedBuilds controls whether workspace builds terminated by the
+	// job reaper are automatically requeued once.
+	RequeueReapedBuilds *bool `json:"requeue_reaped_builds,omitempty"`
+	// AgentRolloutChannel moves the workspace agents of the template to
+	// another rollout channel. Running agents pick it up on their next start.
+	AgentRolloutChannel *AgentRolloutChannel `json:"agent_rollout_channel,omitempty" enums:"stable,beta"`
+	// AllowTargetedBuilds controls whether workspace builds of the template
+	// may set TargetResources.
+	AllowTargetedBuilds *bool `json:"allow_targeted_builds,omitempty"`
+	// ReconfirmParameters replaces the list of parameters users must
+	// explicitly set again when updating a workspace to a new template
+	// version.
+	ReconfirmParameters *[]string `json:"reconfirm_parameters,omitempty"`
 }
 
 type TemplateExample struct {
@@ -295,6 +478,50 @@ type TemplateExample struct {
 	Markdown    string   `json:"markdown"`
 }
 
+// TemplateCreationSchema describes everything a client needs to build a
+// workspace creation form for a template: the active version's parameters
+// and presets, the rules a workspace name must satisfy, and the scheduling
+// limits that apply to new workspaces.
+type TemplateCreationSchema struct {
+	TemplateID           uuid.UUID                  `json:"template_id" format:"uuid"`
+	TemplateVersionID    uuid.UUID                  `json:"template_version_id" format:"uuid"`
+	RequireActiveVersion bool                       `json:"require_active_version"`
+	Parameters           []TemplateVersionParameter `json:"parameters"`
+	Presets              []Preset                   `json:"presets"`
+	NamePolicy           WorkspaceNamePolicy        `json:"name_policy"`
+	TTL                  WorkspaceTTLPolicy         `json:"ttl"`
+	Schedule             WorkspaceSchedulePolicy    `json:"schedule"`
+}
+
+// WorkspaceNamePolicy describes the names accepted for new workspaces.
+// Names must also be unique among the owner's workspaces.
+type WorkspaceNamePolicy struct {
+	Pattern       string   `json:"pattern"`
+	MaxLength     int      `json:"max_length"`
+	ReservedNames []string `json:"reserved_names"`
+}
+
+// WorkspaceTTLPolicy describes the TTL values accepted for new workspaces.
+// DefaultMillis is applied when no TTL is provided.
+type WorkspaceTTLPolicy struct {
+	DefaultMillis      int64 `json:"default_ms"`
+	MinMillis          int64 `json:"min_ms"`
+	MaxMillis          int64 `json:"max_ms"`
+	ActivityBumpMillis int64 `json:"activity_bump_ms"`
+	// TrialWorkspaceTTLMillis is the hard lifetime of new workspaces. 0 means
+	// workspaces do not expire.
+	TrialWorkspaceTTLMillis int64 `json:"trial_workspace_ttl_ms"`
+}
+
+// WorkspaceSchedulePolicy describes whether users may configure the
+// schedule of workspaces created from the template.
+type WorkspaceSchedulePolicy struct {
+	AutostartEditable    bool                         `json:"autostart_editable"`
+	AutostopEditable     bool                         `json:"autostop_editable"`
+	AutostartRequirement TemplateAutostartRequirement `json:"autostart_requirement"`
+	AutostopRequirement  TemplateAutostopRequirement  `json:"autostop_requirement"`
+}
+
 // Template returns a single template.
 func (c *Client) Template(ctx context.Context, template uuid.UUID) (Template, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s", template), nil)
@@ -309,6 +536,21 @@ func (c *Client) Template(ctx context.Context, template uuid.UUID) (Template, er
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// TemplateCreationSchema returns the schema for creating a workspace from
+// the template's active version.
+func (c *Client) TemplateCreationSchema(ctx context.Context, template uuid.UUID) (TemplateCreationSchema, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/creation-schema", template), nil)
+	if err != nil {
+		return TemplateCreationSchema{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateCreationSchema{}, ReadBodyAsError(res)
+	}
+	var resp TemplateCreationSchema
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 func (c *Client) ArchiveTemplateVersions(ctx context.Context, template uuid.UUID, all bool) (ArchiveTemplateVersionsResponse, error) {
 	res, err := c.Request(ctx, http.MethodPost,
 		fmt.Sprintf("/api/v2/templates/%s/versions/archive", template),
@@ -421,6 +663,70 @@ func (c *Client) TemplateACL(ctx context.Context, templateID uuid.UUID) (Templat
 	return acl, json.NewDecoder(res.Body).Decode(&acl)
 }
 
+// ACLSubjectType is the type of subject whose role in an access control list
+// changed.
+type ACLSubjectType string
+
+const (
+	ACLSubjectTypeUser  ACLSubjectType = "user"
+	ACLSubjectTypeGroup ACLSubjectType = "group"
+)
+
+// TemplateACLChange is a change of a user's or group's role on a template,
+// as recorded in the audit log.
+type TemplateACLChange struct {
+	AuditLogID  uuid.UUID      `json:"audit_log_id" format:"uuid"`
+	Time        time.Time      `json:"time" format:"date-time"`
+	Actor       MinimalUser    `json:"actor"`
+	SubjectType ACLSubjectType `json:"subject_type" enums:"user,group"`
+	SubjectID   uuid.UUID      `json:"subject_id" format:"uuid"`
+	// OldRole is empty if the subject gained access to the template.
+	OldRole TemplateRole `json:"old_role" enums:"admin,manage,use,"`
+	// NewRole is empty if the subject lost access to the template.
+	NewRole TemplateRole `json:"new_role" enums:"admin,manage,use,"`
+}
+
+type TemplateACLChangesRequest struct {
+	// Since limits the changes to those made after this time. Defaults to 30
+	// days ago.
+	Since time.Time `json:"since,omitempty" format:"date-time"`
+	// Granted limits the changes to those that granted access to a subject
+	// that previously had none.
+	Granted bool `json:"granted,omitempty"`
+}
+
+func (req TemplateACLChangesRequest) asRequestOption() RequestOption {
+	return func(r *http.Request) {
+		q := r.URL.Query()
+		if !req.Since.IsZero() {
+			q.Set("since", req.Since.Format(time.RFC3339))
+		}
+		if req.Granted {
+			q.Set("granted", "true")
+		}
+		r.URL.RawQuery = q.Encode()
+	}
+}
+
+// TemplateACLChanges returns the changes to the template's access control
+// list, newest first.
+func (c *Client) TemplateACLChanges(ctx context.Context, templateID uuid.UUID, req TemplateACLChangesRequest) ([]TemplateACLChange, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/templates/%s/acl/changes", templateID),
+		nil,
+		req.asRequestOption(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var changes []TemplateACLChange
+	return changes, json.NewDecoder(res.Body).Decode(&changes)
+}
+
 // UpdateActiveTemplateVersion updates the active template version to the ID provided.
 // The template version must be attached to the template.
 func (c *Client) UpdateActiveTemplateVersion(ctx context.Context, template uuid.UUID, req UpdateActiveTemplateVersion) error {
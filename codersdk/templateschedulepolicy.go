@@ -0,0 +1,68 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SimulateTemplateSchedulePolicyRequest contains proposed dormancy and
+// deletion settings of a template. Fields that are nil keep the current value
+// of the template.
+type SimulateTemplateSchedulePolicyRequest struct {
+	FailureTTLMillis               *int64 `json:"failure_ttl_ms,omitempty"`
+	TimeTilDormantMillis           *int64 `json:"time_til_dormant_ms,omitempty"`
+	TimeTilDormantAutoDeleteMillis *int64 `json:"time_til_dormant_autodelete_ms,omitempty"`
+}
+
+// TemplateSchedulePolicySimulation lists the existing workspaces of a
+// template that would be affected by the proposed settings. Nothing is
+// changed by a simulation.
+type TemplateSchedulePolicySimulation struct {
+	SimulatedAt time.Time `json:"simulated_at" format:"date-time"`
+	// DormantImmediately, DeletedImmediately and StoppedImmediately count the
+	// workspaces that would be affected by the next run of the lifecycle
+	// executor if the settings were applied now.
+	DormantImmediately int                                         `json:"dormant_immediately"`
+	DeletedImmediately int                                         `json:"deleted_immediately"`
+	StoppedImmediately int                                         `json:"stopped_immediately"`
+	Workspaces         []TemplateSchedulePolicySimulationWorkspace `json:"workspaces"`
+}
+
+// TemplateSchedulePolicySimulationWorkspace is a workspace affected by the
+// simulated settings, and when it would be affected. Times in the past are
+// clamped to the time of the simulation.
+type TemplateSchedulePolicySimulationWorkspace struct {
+	WorkspaceID   uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName string    `json:"workspace_name"`
+	OwnerID       uuid.UUID `json:"owner_id" format:"uuid"`
+	OwnerName     string    `json:"owner_name"`
+	LastUsedAt    time.Time `json:"last_used_at" format:"date-time"`
+	// DormantAt is set if the workspace would become dormant.
+	DormantAt *time.Time `json:"dormant_at,omitempty" format:"date-time"`
+	// DeletingAt is set if the workspace would be deleted.
+	DeletingAt *time.Time `json:"deleting_at,omitempty" format:"date-time"`
+	// FailedBuildStoppedAt is set if the workspace would be stopped because
+	// its latest build failed.
+	FailedBuildStoppedAt *time.Time `json:"failed_build_stopped_at,omitempty" format:"date-time"`
+}
+
+// SimulateTemplateSchedulePolicy returns which existing workspaces of the
+// template would become dormant, be deleted or be stopped with the proposed
+// settings, without applying them.
+func (c *Client) SimulateTemplateSchedulePolicy(ctx context.Context, templateID uuid.UUID, req SimulateTemplateSchedulePolicyRequest) (TemplateSchedulePolicySimulation, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/schedule-policy/simulate", templateID), req)
+	if err != nil {
+		return TemplateSchedulePolicySimulation{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateSchedulePolicySimulation{}, ReadBodyAsError(res)
+	}
+	var simulation TemplateSchedulePolicySimulation
+	return simulation, json.NewDecoder(res.Body).Decode(&simulation)
+}
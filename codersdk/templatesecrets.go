@@ -0,0 +1,92 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateSecret is a secret declared on a template. Startup scripts of the
+// template's workspaces reference it by name as an environment variable, and
+// the agent fetches its value when the script runs. The value is never
+// returned by the API.
+type TemplateSecret struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	TemplateID  uuid.UUID `json:"template_id" format:"uuid"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt   time.Time `json:"updated_at" format:"date-time"`
+}
+
+type CreateTemplateSecretRequest struct {
+	// Name is the environment variable the secret is exposed as. It must be
+	// a valid environment variable name and can't start with CODER_.
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value" validate:"required"`
+}
+
+type UpdateTemplateSecretRequest struct {
+	Description *string `json:"description,omitempty"`
+	Value       *string `json:"value,omitempty"`
+}
+
+// TemplateSecrets returns the secrets of a template, without their values.
+func (c *Client) TemplateSecrets(ctx context.Context, templateID uuid.UUID) ([]TemplateSecret, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/secrets", templateID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var secrets []TemplateSecret
+	return secrets, json.NewDecoder(res.Body).Decode(&secrets)
+}
+
+// CreateTemplateSecret declares a secret on a template.
+func (c *Client) CreateTemplateSecret(ctx context.Context, templateID uuid.UUID, req CreateTemplateSecretRequest) (TemplateSecret, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/secrets", templateID), req)
+	if err != nil {
+		return TemplateSecret{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateSecret{}, ReadBodyAsError(res)
+	}
+	var secret TemplateSecret
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+// UpdateTemplateSecret updates the description or value of a template secret.
+func (c *Client) UpdateTemplateSecret(ctx context.Context, templateID uuid.UUID, name string, req UpdateTemplateSecretRequest) (TemplateSecret, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s/secrets/%s", templateID, name), req)
+	if err != nil {
+		return TemplateSecret{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateSecret{}, ReadBodyAsError(res)
+	}
+	var secret TemplateSecret
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+// DeleteTemplateSecret deletes a template secret.
+func (c *Client) DeleteTemplateSecret(ctx context.Context, templateID uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/secrets/%s", templateID, name), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserPreset is a named set of parameter values that a user saved for a
+// template. Unlike template version presets, it is private to the user.
+type UserPreset struct {
+	ID         uuid.UUID                 `json:"id" format:"uuid"`
+	TemplateID uuid.UUID                 `json:"template_id" format:"uuid"`
+	Name       string                    `json:"name"`
+	Parameters []WorkspaceBuildParameter `json:"parameters"`
+	CreatedAt  time.Time                 `json:"created_at" format:"date-time"`
+	UpdatedAt  time.Time                 `json:"updated_at" format:"date-time"`
+}
+
+// CreateUserPresetRequest saves a named set of parameter values for a
+// template. Saving a preset with the name of an existing one replaces its
+// parameters.
+type CreateUserPresetRequest struct {
+	Name string `json:"name" validate:"required,max=64"`
+	// Parameters are validated against the active version of the template.
+	Parameters []WorkspaceBuildParameter `json:"parameters"`
+}
+
+// TemplateUserPresets returns the presets the authenticated user saved for a
+// template.
+func (c *Client) TemplateUserPresets(ctx context.Context, templateID uuid.UUID) ([]UserPreset, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/user-presets", templateID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var presets []UserPreset
+	return presets, json.NewDecoder(res.Body).Decode(&presets)
+}
+
+// CreateTemplateUserPreset saves a preset of the authenticated user for a
+// template.
+func (c *Client) CreateTemplateUserPreset(ctx context.Context, templateID uuid.UUID, req CreateUserPresetRequest) (UserPreset, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/user-presets", templateID), req)
+	if err != nil {
+		return UserPreset{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return UserPreset{}, ReadBodyAsError(res)
+	}
+	var preset UserPreset
+	return preset, json.NewDecoder(res.Body).Decode(&preset)
+}
+
+// DeleteTemplateUserPreset deletes a preset of the authenticated user.
+func (c *Client) DeleteTemplateUserPreset(ctx context.Context, templateID, presetID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/user-presets/%s", templateID, presetID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
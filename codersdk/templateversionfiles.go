@@ -0,0 +1,76 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// TemplateVersionFileType is the type of an entry in the source of a
+// template version.
+type TemplateVersionFileType string
+
+const (
+	TemplateVersionFileTypeFile      TemplateVersionFileType = "file"
+	TemplateVersionFileTypeDirectory TemplateVersionFileType = "directory"
+)
+
+// TemplateVersionFile is a file or directory in the source of a template
+// version.
+type TemplateVersionFile struct {
+	// Path is the slash-separated path of the entry relative to the root of
+	// the template source.
+	Path string                  `json:"path"`
+	Type TemplateVersionFileType `json:"type" enums:"file,directory"`
+	// Size is the size of the file in bytes. It is zero for directories.
+	Size int64 `json:"size"`
+	// ContentType is the media type the file is served with. It is empty
+	// for directories.
+	ContentType string `json:"content_type,omitempty"`
+	// Language is a syntax highlighting hint such as "hcl" or "markdown".
+	// It is empty for directories and files in an unknown language.
+	Language string `json:"language,omitempty"`
+}
+
+// TemplateVersionFiles lists the files and directories in the source of a
+// template version, sorted by path.
+func (c *Client) TemplateVersionFiles(ctx context.Context, version uuid.UUID) ([]TemplateVersionFile, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/files", version), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var files []TemplateVersionFile
+	return files, json.NewDecoder(res.Body).Decode(&files)
+}
+
+// TemplateVersionFile returns the contents and content type of a single
+// file in the source of a template version.
+func (c *Client) TemplateVersionFile(ctx context.Context, version uuid.UUID, path string) ([]byte, string, error) {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/files/%s", version, strings.Join(segments, "/")), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, "", ReadBodyAsError(res)
+	}
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, res.Header.Get("Content-Type"), nil
+}
@@ -0,0 +1,135 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TemplateVersionRolloutStatus string
+
+const (
+	TemplateVersionRolloutStatusInProgress TemplateVersionRolloutStatus = "in_progress"
+	TemplateVersionRolloutStatusCompleted  TemplateVersionRolloutStatus = "completed"
+	TemplateVersionRolloutStatusRolledBack TemplateVersionRolloutStatus = "rolled_back"
+	TemplateVersionRolloutStatusAborted    TemplateVersionRolloutStatus = "aborted"
+)
+
+// CreateTemplateVersionRolloutRequest promotes a template version in stages.
+// Each stage targets a larger share of the workspaces of the template and is
+// observed for the soak duration. The version is promoted to the active
+// version after the last stage, unless the failure rate of its builds exceeds
+// the maximum first, in which case the rollout is rolled back.
+type CreateTemplateVersionRolloutRequest struct {
+	TemplateVersionID uuid.UUID `json:"template_version_id" validate:"required" format:"uuid"`
+	// GroupID is a group whose members' workspaces are part of every stage,
+	// e.g. a group of early adopters.
+	GroupID *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+	// Stages are the percentages of workspaces targeted by each stage, in
+	// ascending order. A rollout without stages only targets the group.
+	Stages []int32 `json:"stages,omitempty"`
+	// SoakDurationMillis is how long each stage is observed before the
+	// rollout continues.
+	SoakDurationMillis int64 `json:"soak_duration_ms" validate:"required"`
+	// MaxFailureRate is the share of failed start builds of the version, from
+	// 0 to 1, above which the rollout is rolled back.
+	MaxFailureRate float64 `json:"max_failure_rate"`
+	// MinBuilds is the number of completed start builds in a stage before its
+	// failure rate is considered.
+	MinBuilds int32 `json:"min_builds,omitempty"`
+}
+
+// TemplateVersionRollout is a staged promotion of a template version. While
+// it is in progress, builds of workspaces in the cohort of the current stage
+// that use the active version get the rolled out version instead.
+type TemplateVersionRollout struct {
+	ID                uuid.UUID `json:"id" format:"uuid"`
+	TemplateID        uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateVersionID uuid.UUID `json:"template_version_id" format:"uuid"`
+	// PreviousVersionID is the active version of the template when the
+	// rollout was created.
+	PreviousVersionID  uuid.UUID                    `json:"previous_version_id" format:"uuid"`
+	GroupID            *uuid.UUID                   `json:"group_id,omitempty" format:"uuid"`
+	Stages             []int32                      `json:"stages"`
+	CurrentStage       int32                        `json:"current_stage"`
+	SoakDurationMillis int64                        `json:"soak_duration_ms"`
+	MaxFailureRate     float64                      `json:"max_failure_rate"`
+	MinBuilds          int32                        `json:"min_builds"`
+	Status             TemplateVersionRolloutStatus `json:"status" enums:"in_progress,completed,rolled_back,aborted"`
+	// StatusMessage explains why a rollout was completed, rolled back or
+	// aborted.
+	StatusMessage  string     `json:"status_message"`
+	CreatedBy      uuid.UUID  `json:"created_by" format:"uuid"`
+	CreatedAt      time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt      time.Time  `json:"updated_at" format:"date-time"`
+	StageStartedAt time.Time  `json:"stage_started_at" format:"date-time"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty" format:"date-time"`
+	// Builds is the number of completed start builds of the version in the
+	// current stage.
+	Builds int64 `json:"builds"`
+	// FailedBuilds is the number of failed start builds of the version in the
+	// current stage.
+	FailedBuilds int64 `json:"failed_builds"`
+}
+
+// CreateTemplateVersionRollout starts a staged promotion of a template
+// version. A template can only have one rollout in progress.
+func (c *Client) CreateTemplateVersionRollout(ctx context.Context, templateID uuid.UUID, req CreateTemplateVersionRolloutRequest) (TemplateVersionRollout, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/rollouts", templateID), req)
+	if err != nil {
+		return TemplateVersionRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateVersionRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateVersionRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
+
+// TemplateVersionRollouts returns the rollouts of a template, newest first.
+func (c *Client) TemplateVersionRollouts(ctx context.Context, templateID uuid.UUID) ([]TemplateVersionRollout, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/rollouts", templateID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var rollouts []TemplateVersionRollout
+	return rollouts, json.NewDecoder(res.Body).Decode(&rollouts)
+}
+
+// TemplateVersionRollout returns a rollout of a template by ID.
+func (c *Client) TemplateVersionRollout(ctx context.Context, templateID, rolloutID uuid.UUID) (TemplateVersionRollout, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/rollouts/%s", templateID, rolloutID), nil)
+	if err != nil {
+		return TemplateVersionRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateVersionRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
+
+// AbortTemplateVersionRollout aborts an in-progress rollout. The active
+// version of the template is left unchanged.
+func (c *Client) AbortTemplateVersionRollout(ctx context.Context, templateID, rolloutID uuid.UUID) (TemplateVersionRollout, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/rollouts/%s/abort", templateID, rolloutID), nil)
+	if err != nil {
+		return TemplateVersionRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateVersionRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,6 +37,32 @@ type TemplateVersion struct {
 	MatchedProvisioners *MatchedProvisioners     `json:"matched_provisioners,omitempty"`
 
 	HasExternalAgent bool `json:"has_external_agent"`
+
+	// DeprecationMessage is set if the template version is deprecated.
+	DeprecationMessage string `json:"deprecation_message,omitempty"`
+	// DeprecationCutoff is the time after which the deprecated template
+	// version can no longer be used for new workspaces or updates.
+	DeprecationCutoff *time.Time `json:"deprecation_cutoff,omitempty" format:"date-time"`
+}
+
+// DeprecationSubject is the kind of resource a deprecation warning refers to.
+type DeprecationSubject string
+
+const (
+	DeprecationSubjectTemplate        DeprecationSubject = "template"
+	DeprecationSubjectTemplateVersion DeprecationSubject = "template_version"
+)
+
+// DeprecationWarning is returned with workspaces and builds that use a
+// deprecated template or template version.
+type DeprecationWarning struct {
+	Subject DeprecationSubject `json:"subject" enums:"template,template_version"`
+	ID      uuid.UUID          `json:"id" format:"uuid"`
+	Name    string             `json:"name"`
+	Message string             `json:"message"`
+	// CutoffAt is the time after which the template or version can no
+	// longer be used for new workspaces. If unset, it already cannot.
+	CutoffAt *time.Time `json:"cutoff_at,omitempty" format:"date-time"`
 }
 
 type TemplateVersionExternalAuth struct {
@@ -76,6 +103,16 @@ type TemplateVersionParameter struct {
 	ValidationMonotonic ValidationMonotonicOrder         `json:"validation_monotonic,omitempty" enums:"increasing,decreasing"`
 	Required            bool                             `json:"required"`
 	Ephemeral           bool                             `json:"ephemeral"`
+	Sensitive           bool                             `json:"sensitive"`
+	// Group is the section in which to present the parameter. Parameters
+	// without a group are presented on their own.
+	Group string `json:"group,omitempty"`
+	// GroupOrder orders the groups of a form. All parameters of a group share
+	// it.
+	GroupOrder int32 `json:"group_order,omitempty"`
+	// Page is the page of a multi-page form on which to present the
+	// parameter. All parameters of a group share it.
+	Page int32 `json:"page,omitempty"`
 }
 
 // TemplateVersionParameterOption represents a selectable option for a template parameter.
@@ -86,6 +123,42 @@ type TemplateVersionParameterOption struct {
 	Icon        string `json:"icon"`
 }
 
+// TemplateVersionParametersSchema is a JSON Schema document describing the
+// rich parameters of a template version. Parameter values are expected as an
+// object keyed by parameter name, as in a rich parameter file.
+type TemplateVersionParametersSchema struct {
+	Schema               string                                    `json:"$schema"`
+	Title                string                                    `json:"title"`
+	Type                 string                                    `json:"type"`
+	Properties           map[string]TemplateVersionParameterSchema `json:"properties"`
+	Required             []string                                  `json:"required"`
+	AdditionalProperties bool                                      `json:"additionalProperties"`
+}
+
+// TemplateVersionParameterSchema is the JSON Schema of a single rich
+// parameter. Values use the JSON type of the parameter rather than the string
+// encoding used by workspace builds.
+type TemplateVersionParameterSchema struct {
+	Title       string                               `json:"title,omitempty"`
+	Description string                               `json:"description,omitempty"`
+	Type        string                               `json:"type" enums:"string,number,boolean,array"`
+	Items       *TemplateVersionParameterSchemaItems `json:"items,omitempty"`
+	UniqueItems bool                                 `json:"uniqueItems,omitempty"`
+	Enum        []any                                `json:"enum,omitempty"`
+	Default     any                                  `json:"default,omitempty"`
+	Minimum     *int32                               `json:"minimum,omitempty"`
+	Maximum     *int32                               `json:"maximum,omitempty"`
+	Pattern     string                               `json:"pattern,omitempty"`
+	WriteOnly   bool                                 `json:"writeOnly,omitempty"`
+}
+
+// TemplateVersionParameterSchemaItems is the JSON Schema of the elements of a
+// list(string) parameter.
+type TemplateVersionParameterSchemaItems struct {
+	Type string   `json:"type" enums:"string"`
+	Enum []string `json:"enum,omitempty"`
+}
+
 // TemplateVersionVariable represents a managed template variable.
 type TemplateVersionVariable struct {
 	Name         string `json:"name"`
@@ -100,6 +173,13 @@ type TemplateVersionVariable struct {
 type PatchTemplateVersionRequest struct {
 	Name    string  `json:"name" validate:"omitempty,template_version_name"`
 	Message *string `json:"message,omitempty" validate:"omitempty,lt=1048577"`
+	// DeprecationMessage if set, will mark the template version as
+	// deprecated. If passed an empty string, the deprecation is removed.
+	DeprecationMessage *string `json:"deprecation_message,omitempty"`
+	// DeprecationCutoff is applied together with DeprecationMessage. If set,
+	// the deprecated version may still be used until the cutoff. If unset,
+	// new workspaces and updates using the version are blocked immediately.
+	DeprecationCutoff *time.Time `json:"deprecation_cutoff,omitempty" format:"date-time"`
 }
 
 // TemplateVersion returns a template version by ID.
@@ -143,6 +223,21 @@ func (c *Client) TemplateVersionRichParameters(ctx context.Context, version uuid
 	return params, json.NewDecoder(res.Body).Decode(&params)
 }
 
+// TemplateVersionParametersSchema returns a JSON Schema document describing
+// the rich parameters of a template version.
+func (c *Client) TemplateVersionParametersSchema(ctx context.Context, version uuid.UUID) (TemplateVersionParametersSchema, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/parameters/schema", version), nil)
+	if err != nil {
+		return TemplateVersionParametersSchema{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionParametersSchema{}, ReadBodyAsError(res)
+	}
+	var schema TemplateVersionParametersSchema
+	return schema, json.NewDecoder(res.Body).Decode(&schema)
+}
+
 // TemplateVersionExternalAuth returns authentication providers for the requested template version.
 func (c *Client) TemplateVersionExternalAuth(ctx context.Context, version uuid.UUID, opts ...RequestOption) ([]TemplateVersionExternalAuth, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/external-auth", version), nil, opts...)
@@ -281,6 +376,75 @@ func (c *Client) CancelTemplateVersionDryRun(ctx context.Context, version, job u
 	return nil
 }
 
+// CreateTemplateVersionDryRunMatrixRequest defines the request parameters for
+// CreateTemplateVersionDryRunMatrix.
+type CreateTemplateVersionDryRunMatrixRequest struct {
+	WorkspaceName       string                    `json:"workspace_name"`
+	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values"`
+	// ProvisionerTags lists the tags of each group of provisioner daemons to
+	// run a dry-run on. They are added to the tags of the template version.
+	// If empty, a dry-run is run on every distinct set of tags of the
+	// provisioner daemons able to build the template version.
+	ProvisionerTags []map[string]string `json:"provisioner_tags,omitempty"`
+}
+
+// TemplateVersionDryRunMatrix reports the dry-runs of a template version on
+// several groups of provisioner daemons.
+type TemplateVersionDryRunMatrix struct {
+	Results []TemplateVersionDryRunMatrixResult `json:"results"`
+}
+
+// TemplateVersionDryRunMatrixResult reports a dry-run of a template version
+// on the provisioner daemons matching its tags.
+type TemplateVersionDryRunMatrixResult struct {
+	Job                 ProvisionerJob      `json:"job"`
+	MatchedProvisioners MatchedProvisioners `json:"matched_provisioners"`
+	// ProvisionerVersions are the distinct versions of the provisioner
+	// daemons matching the tags of the job.
+	ProvisionerVersions []string `json:"provisioner_versions"`
+	// WorkerVersion and WorkerAPIVersion are the versions of the provisioner
+	// daemon that ran the dry-run.
+	WorkerVersion    string `json:"worker_version,omitempty"`
+	WorkerAPIVersion string `json:"worker_api_version,omitempty"`
+	// Compatible is set once the dry-run has completed, and is true if it
+	// succeeded. It is unset for canceled dry-runs.
+	Compatible *bool `json:"compatible,omitempty"`
+}
+
+// CreateTemplateVersionDryRunMatrix begins a dry-run provisioner job against
+// the given template version for each group of provisioner daemons.
+func (c *Client) CreateTemplateVersionDryRunMatrix(ctx context.Context, version uuid.UUID, req CreateTemplateVersionDryRunMatrixRequest) (TemplateVersionDryRunMatrix, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templateversions/%s/dry-run-matrix", version), req)
+	if err != nil {
+		return TemplateVersionDryRunMatrix{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateVersionDryRunMatrix{}, ReadBodyAsError(res)
+	}
+
+	var matrix TemplateVersionDryRunMatrix
+	return matrix, json.NewDecoder(res.Body).Decode(&matrix)
+}
+
+// TemplateVersionDryRunMatrix returns the current state of the given
+// template version dry-run jobs.
+func (c *Client) TemplateVersionDryRunMatrix(ctx context.Context, version uuid.UUID, jobs []uuid.UUID) (TemplateVersionDryRunMatrix, error) {
+	qp := url.Values{}
+	qp.Add("job_ids", joinSliceStringer(jobs))
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/dry-run-matrix?%s", version, qp.Encode()), nil)
+	if err != nil {
+		return TemplateVersionDryRunMatrix{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionDryRunMatrix{}, ReadBodyAsError(res)
+	}
+
+	var matrix TemplateVersionDryRunMatrix
+	return matrix, json.NewDecoder(res.Body).Decode(&matrix)
+}
+
 // ErrNoPreviousVersion is returned when no previous template version
 // exists (the server responds with 204 No Content).
 var ErrNoPreviousVersion = xerrors.New("no previous template version")
@@ -313,3 +477,55 @@ func (c *Client) UpdateTemplateVersion(ctx context.Context, versionID uuid.UUID,
 	var version TemplateVersion
 	return version, json.NewDecoder(res.Body).Decode(&version)
 }
+
+// ValidateTemplateVersionRequest statically validates uploaded template
+// source code without creating a template version.
+type ValidateTemplateVersionRequest struct {
+	FileID             uuid.UUID       `json:"file_id" validate:"required" format:"uuid"`
+	UserVariableValues []VariableValue `json:"user_variable_values,omitempty"`
+}
+
+// ValidateTemplateVersionResponse contains the diagnostics found in the
+// template source code. Valid is false if any diagnostic is an error.
+type ValidateTemplateVersionResponse struct {
+	Valid       bool                        `json:"valid"`
+	Diagnostics []TemplateVersionDiagnostic `json:"diagnostics"`
+}
+
+type TemplateVersionDiagnostic struct {
+	Severity DiagnosticSeverityString `json:"severity"`
+	Summary  string                   `json:"summary"`
+	Detail   string                   `json:"detail"`
+	// Range is the position in the template source code the diagnostic
+	// refers to, if known.
+	Range *TemplateVersionDiagnosticRange `json:"range,omitempty"`
+}
+
+// TemplateVersionDiagnosticRange is a range within a template file. Lines and
+// columns start at 1.
+type TemplateVersionDiagnosticRange struct {
+	Filename    string `json:"filename"`
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	EndLine     int    `json:"end_line"`
+	EndColumn   int    `json:"end_column"`
+}
+
+// ValidateTemplateVersion parses and statically validates the template source
+// code in the uploaded file. No template version or provisioner job is
+// created.
+func (c *Client) ValidateTemplateVersion(ctx context.Context, organizationID uuid.UUID, req ValidateTemplateVersionRequest) (ValidateTemplateVersionResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/templateversions/validate", organizationID.String()),
+		req,
+	)
+	if err != nil {
+		return ValidateTemplateVersionResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ValidateTemplateVersionResponse{}, ReadBodyAsError(res)
+	}
+	var resp ValidateTemplateVersionResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -0,0 +1,77 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TemplateVersionScanStatus string
+
+const (
+	TemplateVersionScanStatusRunning   TemplateVersionScanStatus = "running"
+	TemplateVersionScanStatusCompleted TemplateVersionScanStatus = "completed"
+	TemplateVersionScanStatusFailed    TemplateVersionScanStatus = "failed"
+)
+
+type TemplateVersionScanSeverity string
+
+const (
+	TemplateVersionScanSeverityCritical TemplateVersionScanSeverity = "critical"
+	TemplateVersionScanSeverityHigh     TemplateVersionScanSeverity = "high"
+	TemplateVersionScanSeverityMedium   TemplateVersionScanSeverity = "medium"
+	TemplateVersionScanSeverityLow      TemplateVersionScanSeverity = "low"
+	TemplateVersionScanSeverityInfo     TemplateVersionScanSeverity = "info"
+)
+
+// TemplateVersionScanFinding is a single issue reported by the template
+// scanner.
+type TemplateVersionScanFinding struct {
+	Severity TemplateVersionScanSeverity `json:"severity" enums:"critical,high,medium,low,info"`
+	RuleID   string                      `json:"rule_id"`
+	Message  string                      `json:"message"`
+	// Path is the file in the template version the finding refers to, if any.
+	Path string `json:"path,omitempty"`
+}
+
+// TemplateVersionScan is the result of submitting a template version's files
+// and plan output to the deployment's template scanner.
+type TemplateVersionScan struct {
+	TemplateVersionID uuid.UUID                    `json:"template_version_id" format:"uuid"`
+	Status            TemplateVersionScanStatus    `json:"status" enums:"running,completed,failed"`
+	Findings          []TemplateVersionScanFinding `json:"findings"`
+	// Error is set when the scanner could not be reached or returned an
+	// invalid response.
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at" format:"date-time"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" format:"date-time"`
+}
+
+// CriticalFindings returns the number of findings with critical severity.
+func (s TemplateVersionScan) CriticalFindings() int {
+	count := 0
+	for _, finding := range s.Findings {
+		if finding.Severity == TemplateVersionScanSeverityCritical {
+			count++
+		}
+	}
+	return count
+}
+
+// TemplateVersionScan returns the scan result of a template version.
+func (c *Client) TemplateVersionScan(ctx context.Context, version uuid.UUID) (TemplateVersionScan, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/scan", version), nil)
+	if err != nil {
+		return TemplateVersionScan{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionScan{}, ReadBodyAsError(res)
+	}
+	var scan TemplateVersionScan
+	return scan, json.NewDecoder(res.Body).Decode(&scan)
+}
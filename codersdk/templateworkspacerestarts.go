@@ -0,0 +1,152 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TemplateWorkspaceRestartStatus string
+
+const (
+	TemplateWorkspaceRestartStatusInProgress TemplateWorkspaceRestartStatus = "in_progress"
+	TemplateWorkspaceRestartStatusPaused     TemplateWorkspaceRestartStatus = "paused"
+	TemplateWorkspaceRestartStatusCompleted  TemplateWorkspaceRestartStatus = "completed"
+	TemplateWorkspaceRestartStatusAborted    TemplateWorkspaceRestartStatus = "aborted"
+)
+
+// CreateTemplateWorkspaceRestartRequest restarts the outdated running
+// workspaces of a template on its active version, in batches. A workspace is
+// outdated if its latest build does not use the active version. The restart
+// is paused once the failure rate of its builds exceeds the maximum, and
+// aborted if the active version changes before every workspace is restarted.
+type CreateTemplateWorkspaceRestartRequest struct {
+	// BatchSize is the maximum number of workspaces restarted at once.
+	BatchSize int32 `json:"batch_size" validate:"required"`
+	// BatchIntervalMillis is the minimum time between two batches. The next
+	// batch is also only started once the builds of the previous batch are
+	// completed.
+	BatchIntervalMillis int64 `json:"batch_interval_ms"`
+	// HonorQuietHours only restarts workspaces during the quiet hours of
+	// their owner. Quiet hours are only available with a premium license,
+	// without one workspaces can be restarted at any time.
+	HonorQuietHours bool `json:"honor_quiet_hours"`
+	// MaxFailureRate is the share of failed restarts, from 0 to 1, above
+	// which the restart is paused.
+	MaxFailureRate float64 `json:"max_failure_rate"`
+	// MinBuilds is the number of completed restarts before the failure rate
+	// is considered.
+	MinBuilds int32 `json:"min_builds,omitempty"`
+}
+
+// TemplateWorkspaceRestart is a batched restart of the outdated running
+// workspaces of a template on the version that was active when it was
+// created.
+type TemplateWorkspaceRestart struct {
+	ID                  uuid.UUID                      `json:"id" format:"uuid"`
+	TemplateID          uuid.UUID                      `json:"template_id" format:"uuid"`
+	TemplateVersionID   uuid.UUID                      `json:"template_version_id" format:"uuid"`
+	BatchSize           int32                          `json:"batch_size"`
+	BatchIntervalMillis int64                          `json:"batch_interval_ms"`
+	HonorQuietHours     bool                           `json:"honor_quiet_hours"`
+	MaxFailureRate      float64                        `json:"max_failure_rate"`
+	MinBuilds           int32                          `json:"min_builds"`
+	Status              TemplateWorkspaceRestartStatus `json:"status" enums:"in_progress,paused,completed,aborted"`
+	// StatusMessage explains why a restart was paused, completed or aborted.
+	StatusMessage string     `json:"status_message"`
+	CreatedBy     uuid.UUID  `json:"created_by" format:"uuid"`
+	CreatedAt     time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt     time.Time  `json:"updated_at" format:"date-time"`
+	LastBatchAt   *time.Time `json:"last_batch_at,omitempty" format:"date-time"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty" format:"date-time"`
+	// Restarted is the number of workspaces the restart has started a build
+	// for, including builds that could not be created.
+	Restarted int64 `json:"restarted"`
+	Succeeded int64 `json:"succeeded"`
+	Failed    int64 `json:"failed"`
+	// Pending is the number of restart builds that are not completed yet.
+	Pending int64 `json:"pending"`
+	// Remaining is the number of outdated running workspaces that have not
+	// been restarted yet.
+	Remaining int64 `json:"remaining"`
+}
+
+// CreateTemplateWorkspaceRestart starts restarting the outdated running
+// workspaces of a template on its active version. A template can only have
+// one unfinished restart.
+func (c *Client) CreateTemplateWorkspaceRestart(ctx context.Context, templateID uuid.UUID, req CreateTemplateWorkspaceRestartRequest) (TemplateWorkspaceRestart, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/restarts", templateID), req)
+	if err != nil {
+		return TemplateWorkspaceRestart{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateWorkspaceRestart{}, ReadBodyAsError(res)
+	}
+	var restart TemplateWorkspaceRestart
+	return restart, json.NewDecoder(res.Body).Decode(&restart)
+}
+
+// TemplateWorkspaceRestarts returns the workspace restarts of a template,
+// newest first.
+func (c *Client) TemplateWorkspaceRestarts(ctx context.Context, templateID uuid.UUID) ([]TemplateWorkspaceRestart, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/restarts", templateID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var restarts []TemplateWorkspaceRestart
+	return restarts, json.NewDecoder(res.Body).Decode(&restarts)
+}
+
+// TemplateWorkspaceRestart returns a workspace restart of a template by ID.
+func (c *Client) TemplateWorkspaceRestart(ctx context.Context, templateID, restartID uuid.UUID) (TemplateWorkspaceRestart, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/restarts/%s", templateID, restartID), nil)
+	if err != nil {
+		return TemplateWorkspaceRestart{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateWorkspaceRestart{}, ReadBodyAsError(res)
+	}
+	var restart TemplateWorkspaceRestart
+	return restart, json.NewDecoder(res.Body).Decode(&restart)
+}
+
+// AbortTemplateWorkspaceRestart aborts an in-progress or paused restart.
+// Builds that were already started are not canceled.
+func (c *Client) AbortTemplateWorkspaceRestart(ctx context.Context, templateID, restartID uuid.UUID) (TemplateWorkspaceRestart, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/restarts/%s/abort", templateID, restartID), nil)
+	if err != nil {
+		return TemplateWorkspaceRestart{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateWorkspaceRestart{}, ReadBodyAsError(res)
+	}
+	var restart TemplateWorkspaceRestart
+	return restart, json.NewDecoder(res.Body).Decode(&restart)
+}
+
+// ResumeTemplateWorkspaceRestart resumes a restart that was paused because
+// its failure rate was exceeded. Earlier failures no longer count towards the
+// failure rate.
+func (c *Client) ResumeTemplateWorkspaceRestart(ctx context.Context, templateID, restartID uuid.UUID) (TemplateWorkspaceRestart, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/restarts/%s/resume", templateID, restartID), nil)
+	if err != nil {
+		return TemplateWorkspaceRestart{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateWorkspaceRestart{}, ReadBodyAsError(res)
+	}
+	var restart TemplateWorkspaceRestart
+	return restart, json.NewDecoder(res.Body).Decode(&restart)
+}
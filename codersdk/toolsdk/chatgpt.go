@@ -161,6 +161,9 @@ To pick what you want to search for, use the following query formats:
 	- "outdated:<true|false>" - Filter workspaces using outdated template versions. Example: "outdated:true"
 	- "last_used_after:<timestamp>" - Filter workspaces last used after a specific date. Example: "last_used_after:2023-12-01T00:00:00Z"
 	- "last_used_before:<timestamp>" - Filter workspaces last used before a specific date. Example: "last_used_before:2023-12-31T23:59:59Z"
+	- "created_after:<timestamp>" - Filter workspaces created after a specific date. Example: "created_after:2023-12-01T00:00:00Z"
+	- "created_before:<timestamp>" - Filter workspaces created before a specific date. Example: "created_before:2023-12-31T23:59:59Z"
+	- "template_version:<name|id>" - Filter workspaces whose latest build uses a template version, by name or ID. Example: "template_version:v42"
 	- "has-ai-task:<true|false>" - Filter workspaces with AI tasks. Example: "has-ai-task:true"
 	- "param:<name>" or "param:<name>=<value>" - Match workspaces by build parameters. Example: "param:environment=production" or "param:gpu"
 
@@ -0,0 +1,93 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserImpersonation is a request of support staff to act as a user. A token
+// impersonating the user is issued once the request is granted.
+type UserImpersonation struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	UserID         uuid.UUID `json:"user_id" format:"uuid"`
+	ImpersonatorID uuid.UUID `json:"impersonator_id" format:"uuid"`
+	Reason         string    `json:"reason"`
+	// Lifetime is how long the token issued for the request is valid.
+	Lifetime time.Duration `json:"lifetime"`
+	// ConsentRequired is true when the impersonated user must consent to the
+	// request before a token is issued.
+	ConsentRequired bool       `json:"consent_required"`
+	ConsentedAt     *time.Time `json:"consented_at,omitempty" format:"date-time"`
+	IssuedAt        *time.Time `json:"issued_at,omitempty" format:"date-time"`
+	CreatedAt       time.Time  `json:"created_at" format:"date-time"`
+}
+
+// ImpersonateUserRequest requests a token to act as a user.
+type ImpersonateUserRequest struct {
+	// Reason is recorded in the audit log and shown to the impersonated user.
+	// It is required unless an earlier request is redeemed.
+	Reason string `json:"reason,omitempty"`
+	// Lifetime defaults to one hour, and may not exceed the maximum
+	// impersonation lifetime of the deployment.
+	Lifetime time.Duration `json:"lifetime,omitempty"`
+	// ImpersonationID redeems an earlier request that the impersonated user
+	// has consented to. Reason and Lifetime are taken from that request.
+	ImpersonationID *uuid.UUID `json:"impersonation_id,omitempty" format:"uuid"`
+}
+
+// ImpersonateUserResponse is returned when impersonating a user. Key is only
+// set once the request is granted, which requires the consent of the
+// impersonated user when the deployment enforces it.
+type ImpersonateUserResponse struct {
+	Impersonation UserImpersonation `json:"impersonation"`
+	Key           string            `json:"key,omitempty"`
+}
+
+// ImpersonateUser requests a time-boxed token to act as the user.
+func (c *Client) ImpersonateUser(ctx context.Context, user string, req ImpersonateUserRequest) (ImpersonateUserResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/impersonate", user), req)
+	if err != nil {
+		return ImpersonateUserResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return ImpersonateUserResponse{}, ReadBodyAsError(res)
+	}
+	var resp ImpersonateUserResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UserImpersonations returns the impersonation requests for the user, most
+// recent first.
+func (c *Client) UserImpersonations(ctx context.Context, user string) ([]UserImpersonation, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/impersonations", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var impersonations []UserImpersonation
+	return impersonations, json.NewDecoder(res.Body).Decode(&impersonations)
+}
+
+// ConsentToUserImpersonation allows the impersonator to redeem the request.
+// Only the impersonated user may consent.
+func (c *Client) ConsentToUserImpersonation(ctx context.Context, user string, impersonationID uuid.UUID) (UserImpersonation, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/impersonations/%s/consent", user, impersonationID), nil)
+	if err != nil {
+		return UserImpersonation{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserImpersonation{}, ReadBodyAsError(res)
+	}
+	var impersonation UserImpersonation
+	return impersonation, json.NewDecoder(res.Body).Decode(&impersonation)
+}
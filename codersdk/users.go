@@ -312,14 +312,19 @@ type UserPreferenceSettings struct {
 	ShellToolDisplayMode           AgentDisplayMode      `json:"shell_tool_display_mode"`
 	CodeDiffDisplayMode            AgentDisplayMode      `json:"code_diff_display_mode"`
 	AgentChatSendShortcut          AgentChatSendShortcut `json:"agent_chat_send_shortcut"`
+	// WorkspaceSelfRequestedBuildsAllowed allows the agents of the workspaces
+	// of the user to stop or restart their own workspace, on behalf of
+	// tooling running inside it.
+	WorkspaceSelfRequestedBuildsAllowed bool `json:"workspace_self_requested_builds_allowed"`
 }
 
 type UpdateUserPreferenceSettingsRequest struct {
-	TaskNotificationAlertDismissed *bool                 `json:"task_notification_alert_dismissed,omitempty"`
-	ThinkingDisplayMode            ThinkingDisplayMode   `json:"thinking_display_mode,omitempty"`
-	ShellToolDisplayMode           AgentDisplayMode      `json:"shell_tool_display_mode,omitempty"`
-	CodeDiffDisplayMode            AgentDisplayMode      `json:"code_diff_display_mode,omitempty"`
-	AgentChatSendShortcut          AgentChatSendShortcut `json:"agent_chat_send_shortcut,omitempty"`
+	TaskNotificationAlertDismissed      *bool                 `json:"task_notification_alert_dismissed,omitempty"`
+	ThinkingDisplayMode                 ThinkingDisplayMode   `json:"thinking_display_mode,omitempty"`
+	ShellToolDisplayMode                AgentDisplayMode      `json:"shell_tool_display_mode,omitempty"`
+	CodeDiffDisplayMode                 AgentDisplayMode      `json:"code_diff_display_mode,omitempty"`
+	AgentChatSendShortcut               AgentChatSendShortcut `json:"agent_chat_send_shortcut,omitempty"`
+	WorkspaceSelfRequestedBuildsAllowed *bool                 `json:"workspace_self_requested_builds_allowed,omitempty"`
 }
 
 type AgentChatSendShortcut string
@@ -502,6 +507,32 @@ func (c *Client) UserAutofillParameters(ctx context.Context, user string, templa
 	return params, json.NewDecoder(res.Body).Decode(&params)
 }
 
+// UserProvisionerJob is a pending or running provisioner job started by a
+// user.
+type UserProvisionerJob struct {
+	ProvisionerJob
+	// CancelURL is the API path used to cancel the job. It is empty if the
+	// job can no longer be canceled.
+	CancelURL string `json:"cancel_url,omitempty"`
+}
+
+// UserProvisionerJobs returns the in-flight provisioner jobs started by the
+// given user across all workspaces and template versions.
+func (c *Client) UserProvisionerJobs(ctx context.Context, user string) ([]UserProvisionerJob, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/jobs", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var jobs []UserProvisionerJob
+	return jobs, json.NewDecoder(res.Body).Decode(&jobs)
+}
+
 // HasFirstUser returns whether the first user has been created.
 func (c *Client) HasFirstUser(ctx context.Context) (bool, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/first", nil)
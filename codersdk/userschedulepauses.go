@@ -0,0 +1,69 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// UserSchedulePause suspends the autostart of all workspaces of a user until a
+// time, for example while they are on leave. Autostarts that were due during
+// the pause are skipped rather than run once it ends.
+type UserSchedulePause struct {
+	// Paused is true while the pause is in effect.
+	Paused bool `json:"paused"`
+	// PausedUntil is nil if the user never paused their schedules.
+	PausedUntil *time.Time `json:"paused_until,omitempty" format:"date-time"`
+	// PauseAutostop pauses the autostop of workspaces at the deadline of their
+	// builds as well. Dormancy and deletion are never paused.
+	PauseAutostop bool `json:"pause_autostop"`
+}
+
+type UpdateUserSchedulePauseRequest struct {
+	PausedUntil   time.Time `json:"paused_until" validate:"required" format:"date-time"`
+	PauseAutostop bool      `json:"pause_autostop"`
+}
+
+// UserSchedulePause returns the schedule pause of a user.
+func (c *Client) UserSchedulePause(ctx context.Context, user string) (UserSchedulePause, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/schedule-pause", user), nil)
+	if err != nil {
+		return UserSchedulePause{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserSchedulePause{}, ReadBodyAsError(res)
+	}
+	var pause UserSchedulePause
+	return pause, json.NewDecoder(res.Body).Decode(&pause)
+}
+
+// UpdateUserSchedulePause pauses the schedules of the workspaces of a user, or
+// changes the end of an existing pause.
+func (c *Client) UpdateUserSchedulePause(ctx context.Context, user string, req UpdateUserSchedulePauseRequest) (UserSchedulePause, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/schedule-pause", user), req)
+	if err != nil {
+		return UserSchedulePause{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserSchedulePause{}, ReadBodyAsError(res)
+	}
+	var pause UserSchedulePause
+	return pause, json.NewDecoder(res.Body).Decode(&pause)
+}
+
+// DeleteUserSchedulePause resumes the schedules of the workspaces of a user.
+func (c *Client) DeleteUserSchedulePause(ctx context.Context, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/schedule-pause", user), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserWorkspaceReport summarizes the workspaces of a user over a period, as
+// sent weekly to the user.
+type UserWorkspaceReport struct {
+	ID          uuid.UUID                  `json:"id" format:"uuid"`
+	UserID      uuid.UUID                  `json:"user_id" format:"uuid"`
+	PeriodStart time.Time                  `json:"period_start" format:"date-time"`
+	PeriodEnd   time.Time                  `json:"period_end" format:"date-time"`
+	Summary     UserWorkspaceReportSummary `json:"summary"`
+	CreatedAt   time.Time                  `json:"created_at" format:"date-time"`
+}
+
+type UserWorkspaceReportSummary struct {
+	// TotalBuilds and FailedBuilds count the builds of the workspaces of the
+	// user that completed during the period.
+	TotalBuilds  int64 `json:"total_builds"`
+	FailedBuilds int64 `json:"failed_builds"`
+	// Workspaces are the workspaces that had builds during the period, or
+	// that will be deleted or become dormant in the week after it.
+	Workspaces []UserWorkspaceReportWorkspace `json:"workspaces"`
+}
+
+type UserWorkspaceReportWorkspace struct {
+	ID                  uuid.UUID `json:"id" format:"uuid"`
+	Name                string    `json:"name"`
+	TemplateName        string    `json:"template_name"`
+	TemplateDisplayName string    `json:"template_display_name"`
+	TotalBuilds         int64     `json:"total_builds"`
+	FailedBuilds        int64     `json:"failed_builds"`
+	// DeletingAt is when the dormant workspace will be deleted, if that is
+	// soon.
+	DeletingAt *time.Time `json:"deleting_at,omitempty" format:"date-time"`
+	// DormantAt is when the workspace will become dormant unless it is used,
+	// if that is soon.
+	DormantAt *time.Time `json:"dormant_at,omitempty" format:"date-time"`
+}
+
+// UserWorkspaceReport returns the latest summary of the workspaces of a user.
+func (c *Client) UserWorkspaceReport(ctx context.Context, user string) (UserWorkspaceReport, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/workspace-report", user), nil)
+	if err != nil {
+		return UserWorkspaceReport{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserWorkspaceReport{}, ReadBodyAsError(res)
+	}
+	var report UserWorkspaceReport
+	return report, json.NewDecoder(res.Body).Decode(&report)
+}
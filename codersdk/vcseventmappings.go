@@ -0,0 +1,124 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type VCSEventAction string
+
+const (
+	VCSEventActionStartWorkspace   VCSEventAction = "start_workspace"
+	VCSEventActionRefreshPrebuilds VCSEventAction = "refresh_prebuilds"
+	VCSEventActionUpdateWorkspaces VCSEventAction = "update_workspaces"
+)
+
+func (a VCSEventAction) Valid() bool {
+	switch a {
+	case VCSEventActionStartWorkspace, VCSEventActionRefreshPrebuilds, VCSEventActionUpdateWorkspaces:
+		return true
+	default:
+		return false
+	}
+}
+
+// VCSEventMapping maps the pushes to a repository to an action on the
+// workspaces of a template. Pushes are received from GitHub or GitLab
+// webhooks, which must be signed with the signing secret of the mapping. The
+// secret is never returned by the API.
+type VCSEventMapping struct {
+	ID         uuid.UUID `json:"id" format:"uuid"`
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	Name       string    `json:"name"`
+	// Repository is the full name of the repository, e.g. "coder/coder".
+	Repository string `json:"repository"`
+	// Branch restricts the mapping to the pushes to a branch. Pushes to any
+	// branch match when it's empty.
+	Branch          string         `json:"branch"`
+	Action          VCSEventAction `json:"action" enums:"start_workspace,refresh_prebuilds,update_workspaces"`
+	WorkspaceID     *uuid.UUID     `json:"workspace_id,omitempty" format:"uuid"`
+	CreatedBy       uuid.UUID      `json:"created_by" format:"uuid"`
+	CreatedAt       time.Time      `json:"created_at" format:"date-time"`
+	UpdatedAt       time.Time      `json:"updated_at" format:"date-time"`
+	LastTriggeredAt *time.Time     `json:"last_triggered_at,omitempty" format:"date-time"`
+}
+
+type CreateVCSEventMappingRequest struct {
+	Name       string         `json:"name" validate:"required"`
+	Repository string         `json:"repository" validate:"required"`
+	Branch     string         `json:"branch,omitempty"`
+	Action     VCSEventAction `json:"action" validate:"required" enums:"start_workspace,refresh_prebuilds,update_workspaces"`
+	// WorkspaceID is the workspace started by the start_workspace action. It
+	// must be a workspace of the template.
+	WorkspaceID   *uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
+	SigningSecret string     `json:"signing_secret" validate:"required"`
+}
+
+type VCSEventOutcome string
+
+const (
+	VCSEventOutcomeTriggered VCSEventOutcome = "triggered"
+	VCSEventOutcomeSkipped   VCSEventOutcome = "skipped"
+	VCSEventOutcomeFailed    VCSEventOutcome = "failed"
+)
+
+// VCSEventResponse lists the outcome of the event for every mapping it was
+// signed for.
+type VCSEventResponse struct {
+	Results []VCSEventMappingResult `json:"results"`
+}
+
+type VCSEventMappingResult struct {
+	MappingID uuid.UUID       `json:"mapping_id" format:"uuid"`
+	Name      string          `json:"name"`
+	Action    VCSEventAction  `json:"action" enums:"start_workspace,refresh_prebuilds,update_workspaces"`
+	Outcome   VCSEventOutcome `json:"outcome" enums:"triggered,skipped,failed"`
+	Message   string          `json:"message,omitempty"`
+}
+
+// TemplateVCSEventMappings returns the VCS event mappings of a template.
+func (c *Client) TemplateVCSEventMappings(ctx context.Context, templateID uuid.UUID) ([]VCSEventMapping, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/vcs-mappings", templateID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var mappings []VCSEventMapping
+	return mappings, json.NewDecoder(res.Body).Decode(&mappings)
+}
+
+// CreateTemplateVCSEventMapping maps the pushes to a repository to an action
+// on the workspaces of a template.
+func (c *Client) CreateTemplateVCSEventMapping(ctx context.Context, templateID uuid.UUID, req CreateVCSEventMappingRequest) (VCSEventMapping, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/vcs-mappings", templateID), req)
+	if err != nil {
+		return VCSEventMapping{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return VCSEventMapping{}, ReadBodyAsError(res)
+	}
+	var mapping VCSEventMapping
+	return mapping, json.NewDecoder(res.Body).Decode(&mapping)
+}
+
+// DeleteTemplateVCSEventMapping deletes a VCS event mapping of a template.
+func (c *Client) DeleteTemplateVCSEventMapping(ctx context.Context, templateID, mappingID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/vcs-mappings/%s", templateID, mappingID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
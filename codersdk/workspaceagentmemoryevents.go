@@ -0,0 +1,55 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceAgentMemoryEventKind is the kind of a memory event reported by a
+// workspace agent.
+type WorkspaceAgentMemoryEventKind string
+
+const (
+	// WorkspaceAgentMemoryEventKindOOMKill means the kernel killed processes
+	// of the workspace because it ran out of memory.
+	WorkspaceAgentMemoryEventKindOOMKill WorkspaceAgentMemoryEventKind = "oom_kill"
+	// WorkspaceAgentMemoryEventKindMemoryPressure means the processes of the
+	// workspace started spending a significant amount of time stalled on
+	// memory.
+	WorkspaceAgentMemoryEventKindMemoryPressure WorkspaceAgentMemoryEventKind = "memory_pressure"
+)
+
+// WorkspaceAgentMemoryEvent is an OOM kill or a period of severe memory
+// pressure reported by a workspace agent.
+type WorkspaceAgentMemoryEvent struct {
+	ID        uuid.UUID                     `json:"id" format:"uuid"`
+	AgentID   uuid.UUID                     `json:"agent_id" format:"uuid"`
+	CreatedAt time.Time                     `json:"created_at" format:"date-time"`
+	Kind      WorkspaceAgentMemoryEventKind `json:"kind" enums:"oom_kill,memory_pressure"`
+	// OOMKills is the number of processes killed by the OOM killer. It is
+	// zero for memory pressure events.
+	OOMKills int32 `json:"oom_kills"`
+	// MemoryPressure is the percentage of time in the last 10 seconds during
+	// which all non-idle processes of the workspace were stalled on memory.
+	MemoryPressure float64 `json:"memory_pressure"`
+}
+
+// WorkspaceMemoryEvents returns the memory events reported by the agents of
+// the latest build of a workspace in the last 24 hours, newest first.
+func (c *Client) WorkspaceMemoryEvents(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentMemoryEvent, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/memory-events", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var events []WorkspaceAgentMemoryEvent
+	return events, json.NewDecoder(res.Body).Decode(&events)
+}
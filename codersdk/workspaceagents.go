@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -126,6 +127,85 @@ type WorkspaceAgentMetadata struct {
 	Description WorkspaceAgentMetadataDescription `json:"description"`
 }
 
+// WorkspaceAgentMetadataHistory lists the values a metadata key reported over
+// time, oldest first. History is only recorded when the deployment retains it.
+type WorkspaceAgentMetadataHistory struct {
+	Key   string    `json:"key"`
+	Since time.Time `json:"since" format:"date-time"`
+	// IntervalSeconds is the width of the buckets the values were downsampled
+	// to, keeping the latest value of each. Zero means every recorded value is
+	// returned.
+	IntervalSeconds int64                                `json:"interval_seconds"`
+	Values          []WorkspaceAgentMetadataHistoryValue `json:"values"`
+}
+
+type WorkspaceAgentMetadataHistoryValue struct {
+	CollectedAt time.Time `json:"collected_at" format:"date-time"`
+	Value       string    `json:"value"`
+	Error       string    `json:"error"`
+}
+
+type WorkspaceAgentMetadataHistoryRequest struct {
+	// Since defaults to one hour ago.
+	Since time.Time `json:"since" format:"date-time"`
+	// Interval downsamples the history to the latest value per interval. Long
+	// ranges are downsampled regardless.
+	Interval time.Duration `json:"interval"`
+}
+
+// WorkspaceAgentBootstrapProgress is a named milestone reported by a startup
+// script while the workspace is being bootstrapped, e.g. "cloning repo" at
+// 40%.
+type WorkspaceAgentBootstrapProgress struct {
+	Name string `json:"name"`
+	// Percent is omitted when the script did not report one.
+	Percent   *int32    `json:"percent,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+}
+
+// WorkspaceAgentServiceRestartPolicy decides whether the agent restarts a
+// service after its process exited.
+type WorkspaceAgentServiceRestartPolicy string
+
+const (
+	WorkspaceAgentServiceRestartPolicyAlways    WorkspaceAgentServiceRestartPolicy = "always"
+	WorkspaceAgentServiceRestartPolicyOnFailure WorkspaceAgentServiceRestartPolicy = "on_failure"
+	WorkspaceAgentServiceRestartPolicyNever     WorkspaceAgentServiceRestartPolicy = "never"
+)
+
+// WorkspaceAgentServiceStatus is the status of a service supervised by a
+// workspace agent.
+type WorkspaceAgentServiceStatus string
+
+const (
+	// WorkspaceAgentServiceStatusPending is used until the agent reports the
+	// service, which it starts once the startup scripts finished.
+	WorkspaceAgentServiceStatusPending    WorkspaceAgentServiceStatus = "pending"
+	WorkspaceAgentServiceStatusStarting   WorkspaceAgentServiceStatus = "starting"
+	WorkspaceAgentServiceStatusRunning    WorkspaceAgentServiceStatus = "running"
+	WorkspaceAgentServiceStatusRestarting WorkspaceAgentServiceStatus = "restarting"
+	WorkspaceAgentServiceStatusStopped    WorkspaceAgentServiceStatus = "stopped"
+	WorkspaceAgentServiceStatusFailed     WorkspaceAgentServiceStatus = "failed"
+)
+
+// WorkspaceAgentService is a long-running process, such as a dev server or a
+// database, that is declared by the template and supervised by the agent.
+type WorkspaceAgentService struct {
+	Name          string                             `json:"name"`
+	DisplayName   string                             `json:"display_name"`
+	Command       string                             `json:"command"`
+	RestartPolicy WorkspaceAgentServiceRestartPolicy `json:"restart_policy" enums:"always,on_failure,never"`
+	Status        WorkspaceAgentServiceStatus        `json:"status" enums:"pending,starting,running,restarting,stopped,failed"`
+	Message       string                             `json:"message,omitempty"`
+	// ExitCode is the exit code of the last process of the service, if it
+	// exited.
+	ExitCode     *int32 `json:"exit_code,omitempty"`
+	RestartCount int32  `json:"restart_count"`
+	// UpdatedAt is when the agent last reported the status of the service.
+	UpdatedAt *time.Time `json:"updated_at,omitempty" format:"date-time"`
+}
+
 type DisplayApp string
 
 const (
@@ -160,7 +240,24 @@ type WorkspaceAgent struct {
 	ExpandedDirectory    string                  `json:"expanded_directory,omitempty"`
 	Version              string                  `json:"version"`
 	APIVersion           string                  `json:"api_version"`
-	Apps                 []WorkspaceApp          `json:"apps"`
+	// RolloutChannel is the channel the agent binary is downloaded from on
+	// the next workspace start. Compare Version against the channel's version
+	// to find agents that have not restarted since a rollout. It is only
+	// populated by the workspace and workspace build endpoints.
+	RolloutChannel AgentRolloutChannel `json:"rollout_channel,omitempty" enums:"stable,beta"`
+	// BootstrapProgress is the latest milestone reported by the agent's
+	// startup scripts. It is only populated by the workspace and workspace
+	// build endpoints.
+	BootstrapProgress *WorkspaceAgentBootstrapProgress `json:"bootstrap_progress,omitempty"`
+	// ClockSkewMilliseconds is the offset of the clock of the agent from the
+	// clock of the Coder server, as last reported by the agent. Positive
+	// values mean the clock of the agent is ahead. It is nil if the agent has
+	// not reported it.
+	ClockSkewMilliseconds *int64 `json:"clock_skew_ms,omitempty"`
+	// Services are the long-running processes supervised by the agent. They
+	// are only populated by the workspace and workspace build endpoints.
+	Services []WorkspaceAgentService `json:"services,omitempty"`
+	Apps     []WorkspaceApp          `json:"apps"`
 	// DERPLatency is mapped by region name (e.g. "New York City", "Seattle").
 	DERPLatency              map[string]DERPRegion     `json:"latency,omitempty"`
 	ConnectionTimeoutSeconds int32                     `json:"connection_timeout_seconds"`
@@ -210,6 +307,11 @@ type WorkspaceAgentScript struct {
 	Status           *WorkspaceAgentScriptStatus `json:"status,omitempty"`
 }
 
+// WorkspaceAgentClockSkewThreshold is the clock skew beyond which a workspace
+// agent is unhealthy. Clocks that drift further break TLS certificate
+// validation, token expiry and build caches in the workspace.
+const WorkspaceAgentClockSkewThreshold = time.Minute
+
 type WorkspaceAgentHealth struct {
 	Healthy bool   `json:"healthy" example:"false"`                              // Healthy is true if the agent is healthy.
 	Reason  string `json:"reason,omitempty" example:"agent has lost connection"` // Reason is a human-readable explanation of the agent's health. It is empty if Healthy is true.
@@ -265,6 +367,77 @@ func (s AgentSubsystem) Valid() bool {
 	}
 }
 
+// WorkspaceAgentMetadataHistory returns the recorded values of a metadata key
+// of a workspace agent.
+func (c *Client) WorkspaceAgentMetadataHistory(ctx context.Context, id uuid.UUID, key string, req WorkspaceAgentMetadataHistoryRequest) (WorkspaceAgentMetadataHistory, error) {
+	qp := url.Values{}
+	if !req.Since.IsZero() {
+		qp.Add("since", req.Since.Format(time.RFC3339Nano))
+	}
+	if req.Interval > 0 {
+		qp.Add("interval", req.Interval.String())
+	}
+	reqURL := fmt.Sprintf("/api/v2/workspaceagents/%s/metadata/%s/history?%s", id, url.PathEscape(key), qp.Encode())
+	res, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return WorkspaceAgentMetadataHistory{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentMetadataHistory{}, ReadBodyAsError(res)
+	}
+	var history WorkspaceAgentMetadataHistory
+	return history, json.NewDecoder(res.Body).Decode(&history)
+}
+
+// WorkspaceAgentBootstrapProgress returns the bootstrap milestones reported by
+// a workspace agent, oldest first.
+func (c *Client) WorkspaceAgentBootstrapProgress(ctx context.Context, id uuid.UUID) ([]WorkspaceAgentBootstrapProgress, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/bootstrap-progress", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var progress []WorkspaceAgentBootstrapProgress
+	return progress, json.NewDecoder(res.Body).Decode(&progress)
+}
+
+// WorkspaceAgentServices returns the services supervised by a workspace agent.
+func (c *Client) WorkspaceAgentServices(ctx context.Context, id uuid.UUID) ([]WorkspaceAgentService, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/services", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var services []WorkspaceAgentService
+	return services, json.NewDecoder(res.Body).Decode(&services)
+}
+
+// WorkspaceAgentRestartService asks the agent to restart the service with the
+// given name. The restart happens in the background and is reported through
+// the status of the service.
+func (c *Client) WorkspaceAgentRestartService(ctx context.Context, agentID uuid.UUID, name string) (Response, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/services/%s/restart", agentID, url.PathEscape(name)), nil)
+	if err != nil {
+		return Response{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return Response{}, ReadBodyAsError(res)
+	}
+	var m Response
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		return Response{}, xerrors.Errorf("decode response body: %w", err)
+	}
+	return m, nil
+}
+
 // WatchWorkspaceAgentMetadata watches the metadata of a workspace agent.
 // The returned channel will be closed when the context is canceled. Exactly
 // one error will be sent on the error channel. The metadata channel is never closed.
@@ -649,6 +822,62 @@ func (c *Client) WorkspaceAgentRecreateDevcontainer(ctx context.Context, agentID
 	return m, nil
 }
 
+// WorkspaceAgentRebuildDevcontainer rebuilds the image of the devcontainer
+// with the given name without the build cache and recreates it. Progress is
+// streamed to the agent logs.
+func (c *Client) WorkspaceAgentRebuildDevcontainer(ctx context.Context, agentID uuid.UUID, name string) (Response, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/containers/%s/recreate", agentID, url.PathEscape(name)), nil)
+	if err != nil {
+		return Response{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return Response{}, ReadBodyAsError(res)
+	}
+	var m Response
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		return Response{}, xerrors.Errorf("decode response body: %w", err)
+	}
+	return m, nil
+}
+
+// WorkspaceAgentFileTransferMaxBytes is the largest file that can be
+// uploaded to or downloaded from a workspace agent through coderd.
+const WorkspaceAgentFileTransferMaxBytes int64 = 100 << 20 // 100 MiB
+
+// WorkspaceAgentDownloadFile downloads the file at the absolute path in the
+// workspace agent's filesystem. The caller must close the returned reader.
+func (c *Client) WorkspaceAgentDownloadFile(ctx context.Context, agentID uuid.UUID, path string) (io.ReadCloser, string, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files", agentID), nil, WithQueryParam("path", path))
+	if err != nil {
+		return nil, "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, "", ReadBodyAsError(res)
+	}
+	return res.Body, res.Header.Get("Content-Type"), nil
+}
+
+// WorkspaceAgentUploadFile writes the contents of r to the absolute path in
+// the workspace agent's filesystem, creating parent directories as needed.
+func (c *Client) WorkspaceAgentUploadFile(ctx context.Context, agentID uuid.UUID, path string, r io.Reader) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/files", agentID), r,
+		WithQueryParam("path", path),
+		func(r *http.Request) {
+			r.Header.Set("Content-Type", "application/octet-stream")
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 //nolint:revive // Follow is a control flag on the server as well.
 func (c *Client) WorkspaceAgentLogsAfter(ctx context.Context, agentID uuid.UUID, after int64, follow bool) (<-chan []WorkspaceAgentLog, io.Closer, error) {
 	var queryParams []string
@@ -92,6 +92,13 @@ type WorkspaceApp struct {
 	// Tooltip is an optional markdown supported field that is displayed
 	// when hovering over workspace apps in the UI.
 	Tooltip string `json:"tooltip,omitempty"`
+	// MaxSessionDurationSeconds is the maximum lifetime of a session used to
+	// access the app. Once exceeded, the user has to reauthenticate. Zero
+	// means unlimited.
+	MaxSessionDurationSeconds int32 `json:"max_session_duration_seconds,omitempty"`
+	// SessionIdleTimeoutSeconds is the duration of inactivity after which a
+	// session used to access the app expires. Zero means unlimited.
+	SessionIdleTimeoutSeconds int32 `json:"session_idle_timeout_seconds,omitempty"`
 
 	// Statuses is a list of statuses for the app.
 	Statuses []WorkspaceAppStatus `json:"statuses"`
@@ -0,0 +1,85 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceAppShareLink grants visitors access to a workspace app without a
+// Coder account. Visitors must authenticate with the OIDC provider of the
+// deployment.
+type WorkspaceAppShareLink struct {
+	ID          uuid.UUID  `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID  `json:"workspace_id" format:"uuid"`
+	AgentName   string     `json:"agent_name"`
+	AppSlug     string     `json:"app_slug"`
+	CreatedBy   uuid.UUID  `json:"created_by" format:"uuid"`
+	CreatedAt   time.Time  `json:"created_at" format:"date-time"`
+	ExpiresAt   time.Time  `json:"expires_at" format:"date-time"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" format:"date-time"`
+}
+
+// CreateWorkspaceAppShareLinkRequest shares a subdomain app of the workspace.
+type CreateWorkspaceAppShareLinkRequest struct {
+	// AgentName is not required if the workspace has only one agent.
+	AgentName string `json:"agent_name,omitempty"`
+	AppSlug   string `json:"app_slug" validate:"required"`
+	// Lifetime defaults to one day, and may not exceed seven days.
+	Lifetime time.Duration `json:"lifetime,omitempty"`
+}
+
+// CreateWorkspaceAppShareLinkResponse is returned when creating a share link.
+// URL contains the secret of the link and is only returned once.
+type CreateWorkspaceAppShareLinkResponse struct {
+	ShareLink WorkspaceAppShareLink `json:"share_link"`
+	URL       string                `json:"url"`
+}
+
+// CreateWorkspaceAppShareLink creates an expiring link to share a workspace
+// app with visitors that do not have a Coder account.
+func (c *Client) CreateWorkspaceAppShareLink(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspaceAppShareLinkRequest) (CreateWorkspaceAppShareLinkResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/share-links", workspaceID), req)
+	if err != nil {
+		return CreateWorkspaceAppShareLinkResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return CreateWorkspaceAppShareLinkResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateWorkspaceAppShareLinkResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAppShareLinks returns the share links of the workspace, most
+// recent first.
+func (c *Client) WorkspaceAppShareLinks(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAppShareLink, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/share-links", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var links []WorkspaceAppShareLink
+	return links, json.NewDecoder(res.Body).Decode(&links)
+}
+
+// RevokeWorkspaceAppShareLink revokes a share link. Visitors lose access to
+// the app within a minute.
+func (c *Client) RevokeWorkspaceAppShareLink(ctx context.Context, workspaceID, shareLinkID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/share-links/%s", workspaceID, shareLinkID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
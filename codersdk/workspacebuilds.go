@@ -72,6 +72,19 @@ const (
 	// BuildReasonTaskResume "task_resume" is used when a build to
 	// start a task workspace is triggered by a user.
 	BuildReasonTaskResume BuildReason = "task_resume"
+	// BuildReasonBudgetExceeded "budget_exceeded" is used when a build to
+	// stop a workspace is triggered because a monthly cost budget was exceeded.
+	BuildReasonBudgetExceeded BuildReason = "budget_exceeded"
+	// BuildReasonArchive "archive" is used when a build to destroy the
+	// resources of a workspace is triggered by archiving the workspace.
+	BuildReasonArchive BuildReason = "archive"
+	// BuildReasonUnarchive "unarchive" is used when a build to start a
+	// workspace is triggered by unarchiving the workspace.
+	BuildReasonUnarchive BuildReason = "unarchive"
+	// BuildReasonSelfRequested "self_requested" is used when a build to stop
+	// or restart a workspace is requested by its agent, on behalf of tooling
+	// running inside the workspace.
+	BuildReasonSelfRequested BuildReason = "self_requested"
 )
 
 // WorkspaceBuild is an at-point representation of a workspace state.
@@ -104,6 +117,37 @@ type WorkspaceBuild struct {
 	// Deprecated: This field has been deprecated in favor of Task WorkspaceID.
 	HasAITask        *bool `json:"has_ai_task,omitempty"`
 	HasExternalAgent *bool `json:"has_external_agent,omitempty"`
+	// ProvisionerTimeoutMillis is the apply timeout of the build's template
+	// at the time of the request. 0 means the build is not subject to a
+	// template timeout.
+	ProvisionerTimeoutMillis int64 `json:"provisioner_timeout_ms"`
+	// Annotations are notes users attached to the build, oldest first. They
+	// are only populated by the workspace build endpoints.
+	Annotations []WorkspaceBuildAnnotation `json:"annotations,omitempty"`
+	// ParameterChanges lists the rich parameters whose values differ from the
+	// previous build of the workspace. It is empty for the first build.
+	ParameterChanges []WorkspaceBuildParameterChange `json:"parameter_changes,omitempty"`
+	// DeprecationWarnings lists deprecations of the build's template and
+	// template version.
+	DeprecationWarnings []DeprecationWarning `json:"deprecation_warnings,omitempty"`
+	// LogsArchive is set once the build log retention removed the logs of
+	// the build from the database.
+	LogsArchive *WorkspaceBuildLogsArchive `json:"logs_archive,omitempty"`
+	// LogLevel is the provisioner log level the build was requested with.
+	// It is empty for builds with the default verbosity.
+	LogLevel ProvisionerLogLevel `json:"log_level,omitempty"`
+}
+
+// WorkspaceBuildLogsArchive describes the logs of a workspace build that were
+// removed from the database by the build log retention.
+type WorkspaceBuildLogsArchive struct {
+	RemovedAt time.Time `json:"removed_at" format:"date-time"`
+	// Archived is true if the logs were archived before they were removed.
+	// Otherwise they were deleted and cannot be retrieved.
+	Archived bool `json:"archived"`
+	// Path is the API path the archived logs can be retrieved from. It is
+	// empty if the logs were not archived.
+	Path string `json:"path,omitempty"`
 }
 
 // WorkspaceResource describes resources used to create a workspace, for instance:
@@ -135,6 +179,118 @@ type WorkspaceBuildParameter struct {
 	Value string `json:"value"`
 }
 
+type WorkspaceBuildParameterChangeKind string
+
+const (
+	WorkspaceBuildParameterChangeKindAdded    WorkspaceBuildParameterChangeKind = "added"
+	WorkspaceBuildParameterChangeKindRemoved  WorkspaceBuildParameterChangeKind = "removed"
+	WorkspaceBuildParameterChangeKindModified WorkspaceBuildParameterChangeKind = "modified"
+)
+
+// WorkspaceBuildParameterChange describes how a rich parameter value changed
+// between a build and the previous build of the same workspace.
+type WorkspaceBuildParameterChange struct {
+	Name          string                            `json:"name"`
+	Kind          WorkspaceBuildParameterChangeKind `json:"kind" enums:"added,removed,modified"`
+	PreviousValue string                            `json:"previous_value"`
+	NewValue      string                            `json:"new_value"`
+	// Redacted is true when the values are withheld because the parameter
+	// is ephemeral or sensitive. PreviousValue and NewValue are empty in
+	// that case.
+	Redacted bool `json:"redacted"`
+}
+
+// WorkspaceBuildInputs is an immutable record of everything a workspace build
+// was provisioned with, captured when its provisioner job is acquired. It
+// allows reproducing a build in another deployment. Secrets are never
+// included: sensitive template variables are redacted, and the tokens and
+// keys passed to the provisioner are left out.
+type WorkspaceBuildInputs struct {
+	WorkspaceBuildID  uuid.UUID `json:"workspace_build_id" format:"uuid"`
+	TemplateID        uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateVersionID uuid.UUID `json:"template_version_id" format:"uuid"`
+	// TemplateVersionHash is the SHA256 hash of the template version source
+	// archive.
+	TemplateVersionHash string `json:"template_version_hash"`
+	// TemplateVersionModulesFileID is the file of cached Terraform modules,
+	// if the template version has one.
+	TemplateVersionModulesFileID string              `json:"template_version_modules_file_id,omitempty"`
+	Transition                   WorkspaceTransition `json:"transition" enums:"start,stop,delete"`
+	Reason                       BuildReason         `json:"reason"`
+	LogLevel                     string              `json:"log_level,omitempty"`
+	// Parameters are the parameter values of the build. The values of
+	// sensitive parameters are empty, here and in PreviousParameters.
+	Parameters []WorkspaceBuildParameter `json:"parameters"`
+	// PreviousParameters are the parameter values of the previous build of
+	// the workspace.
+	PreviousParameters []WorkspaceBuildParameter     `json:"previous_parameters"`
+	Variables          []WorkspaceBuildInputVariable `json:"variables"`
+	ProvisionerTags    map[string]string             `json:"provisioner_tags"`
+	// TargetResources limits the build to these Terraform resource addresses.
+	TargetResources []string                      `json:"target_resources,omitempty"`
+	Metadata        WorkspaceBuildInputMetadata   `json:"metadata"`
+	Deployment      WorkspaceBuildInputDeployment `json:"deployment"`
+	CreatedAt       time.Time                     `json:"created_at" format:"date-time"`
+}
+
+// WorkspaceBuildInputVariable is the value of a template variable used by a
+// workspace build.
+type WorkspaceBuildInputVariable struct {
+	Name string `json:"name"`
+	// Value is empty for sensitive variables.
+	Value     string `json:"value"`
+	Sensitive bool   `json:"sensitive"`
+}
+
+// WorkspaceBuildInputMetadata is the workspace metadata a build exposed to the
+// template through the coder_workspace, coder_workspace_owner and coder_task
+// data sources.
+type WorkspaceBuildInputMetadata struct {
+	AccessURL               string     `json:"access_url"`
+	WorkspaceID             uuid.UUID  `json:"workspace_id" format:"uuid"`
+	WorkspaceName           string     `json:"workspace_name"`
+	WorkspaceOwnerID        uuid.UUID  `json:"workspace_owner_id" format:"uuid"`
+	WorkspaceOwnerUsername  string     `json:"workspace_owner_username"`
+	WorkspaceOwnerName      string     `json:"workspace_owner_name"`
+	WorkspaceOwnerEmail     string     `json:"workspace_owner_email"`
+	WorkspaceOwnerLoginType string     `json:"workspace_owner_login_type"`
+	WorkspaceOwnerGroups    []string   `json:"workspace_owner_groups"`
+	WorkspaceOwnerRoles     []SlimRole `json:"workspace_owner_roles"`
+	TemplateName            string     `json:"template_name"`
+	TemplateVersionName     string     `json:"template_version_name"`
+	// ExternalAuthProviders are the external auth providers the owner was
+	// authenticated with. Their tokens are not recorded.
+	ExternalAuthProviders       []string `json:"external_auth_providers"`
+	PrebuiltWorkspaceBuildStage string   `json:"prebuilt_workspace_build_stage,omitempty"`
+	TaskID                      string   `json:"task_id,omitempty"`
+	TaskPrompt                  string   `json:"task_prompt,omitempty"`
+}
+
+// WorkspaceBuildInputDeployment describes the deployment a workspace build
+// was provisioned by.
+type WorkspaceBuildInputDeployment struct {
+	CoderVersion        string          `json:"coder_version"`
+	OrganizationID      uuid.UUID       `json:"organization_id" format:"uuid"`
+	Provisioner         ProvisionerType `json:"provisioner"`
+	ProvisionerDaemonID uuid.UUID       `json:"provisioner_daemon_id" format:"uuid"`
+}
+
+// WorkspaceBuildAnnotation is a note a user attached to a workspace build,
+// e.g. "rebuilt to pick up CVE fix".
+type WorkspaceBuildAnnotation struct {
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	WorkspaceBuildID uuid.UUID `json:"workspace_build_id" format:"uuid"`
+	UserID           uuid.UUID `json:"user_id" format:"uuid"`
+	Username         string    `json:"username"`
+	Note             string    `json:"note"`
+	CreatedAt        time.Time `json:"created_at" format:"date-time"`
+}
+
+// CreateWorkspaceBuildAnnotationRequest attaches a note to a workspace build.
+type CreateWorkspaceBuildAnnotationRequest struct {
+	Note string `json:"note" validate:"required,max=1024"`
+}
+
 // WorkspaceBuild returns a single workspace build for a workspace.
 // If history is "", the latest version is returned.
 func (c *Client) WorkspaceBuild(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
@@ -188,6 +344,51 @@ func (c *Client) WorkspaceBuildLogsAfter(ctx context.Context, build uuid.UUID, a
 	return c.provisionerJobLogsAfter(ctx, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs", build), after)
 }
 
+// WorkspaceBuildArchivedLogs returns the logs of a build that were archived
+// by the build log retention.
+func (c *Client) WorkspaceBuildArchivedLogs(ctx context.Context, build uuid.UUID) ([]ProvisionerJobLog, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs/archive", build), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var logs []ProvisionerJobLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
+}
+
+// WorkspaceBuildLogsByStage returns the logs of a build that were written
+// during a provisioning stage, such as TimingStageApply.
+func (c *Client) WorkspaceBuildLogsByStage(ctx context.Context, build uuid.UUID, stage TimingStage) ([]ProvisionerJobLog, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs?stage=%s", build, stage), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var logs []ProvisionerJobLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
+}
+
+// WorkspaceBuildLogStages returns the provisioning stages of a build along
+// with the range of their logs.
+func (c *Client) WorkspaceBuildLogStages(ctx context.Context, build uuid.UUID) ([]WorkspaceBuildLogStage, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs/stages", build), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var stages []WorkspaceBuildLogStage
+	return stages, json.NewDecoder(res.Body).Decode(&stages)
+}
+
 // WorkspaceBuildState returns the provisioner state of the build.
 func (c *Client) WorkspaceBuildState(ctx context.Context, build uuid.UUID) ([]byte, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/state", build), nil)
@@ -236,8 +437,22 @@ func (c *Client) WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(ctx cont
 	return workspaceBuild, json.NewDecoder(res.Body).Decode(&workspaceBuild)
 }
 
+// WorkspaceBuildParameters returns the parameters of a workspace build. The
+// values of sensitive parameters are redacted.
 func (c *Client) WorkspaceBuildParameters(ctx context.Context, build uuid.UUID) ([]WorkspaceBuildParameter, error) {
-	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/parameters", build), nil)
+	return c.workspaceBuildParameters(ctx, build)
+}
+
+// WorkspaceBuildParametersForRebuild returns the parameters of a workspace
+// build including the values of sensitive parameters, so the workspace can
+// be rebuilt with the same values. It requires permission to update the
+// workspace.
+func (c *Client) WorkspaceBuildParametersForRebuild(ctx context.Context, build uuid.UUID) ([]WorkspaceBuildParameter, error) {
+	return c.workspaceBuildParameters(ctx, build, WithQueryParam("include_sensitive", "true"))
+}
+
+func (c *Client) workspaceBuildParameters(ctx context.Context, build uuid.UUID, opts ...RequestOption) ([]WorkspaceBuildParameter, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/parameters", build), nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -249,6 +464,35 @@ func (c *Client) WorkspaceBuildParameters(ctx context.Context, build uuid.UUID)
 	return params, json.NewDecoder(res.Body).Decode(&params)
 }
 
+// WorkspaceBuildInputs returns the inputs a workspace build was provisioned
+// with. Builds whose provisioner job was not acquired yet have no inputs.
+func (c *Client) WorkspaceBuildInputs(ctx context.Context, build uuid.UUID) (WorkspaceBuildInputs, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/inputs", build), nil)
+	if err != nil {
+		return WorkspaceBuildInputs{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildInputs{}, ReadBodyAsError(res)
+	}
+	var inputs WorkspaceBuildInputs
+	return inputs, json.NewDecoder(res.Body).Decode(&inputs)
+}
+
+// CreateWorkspaceBuildAnnotation attaches a note to a workspace build.
+func (c *Client) CreateWorkspaceBuildAnnotation(ctx context.Context, build uuid.UUID, req CreateWorkspaceBuildAnnotationRequest) (WorkspaceBuildAnnotation, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspacebuilds/%s/annotations", build), req)
+	if err != nil {
+		return WorkspaceBuildAnnotation{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuildAnnotation{}, ReadBodyAsError(res)
+	}
+	var annotation WorkspaceBuildAnnotation
+	return annotation, json.NewDecoder(res.Body).Decode(&annotation)
+}
+
 type TimingStage string
 
 const (
@@ -265,6 +509,19 @@ const (
 	TimingStageConnect TimingStage = "connect"
 )
 
+// WorkspaceBuildLogStage is a provisioning stage of a build, and the range
+// of the logs written during it.
+type WorkspaceBuildLogStage struct {
+	Stage     TimingStage `json:"stage" enums:"init,plan,graph,apply"`
+	StartedAt time.Time   `json:"started_at" format:"date-time"`
+	EndedAt   time.Time   `json:"ended_at" format:"date-time"`
+	// FirstLogID and LastLogID are the IDs of the first and last logs of the
+	// stage. They are omitted if the stage wrote no logs.
+	FirstLogID *int64 `json:"first_log_id,omitempty"`
+	LastLogID  *int64 `json:"last_log_id,omitempty"`
+	LogCount   int64  `json:"log_count"`
+}
+
 type ProvisionerTiming struct {
 	JobID     uuid.UUID   `json:"job_id" format:"uuid"`
 	StartedAt time.Time   `json:"started_at" format:"date-time"`
@@ -294,12 +551,28 @@ type AgentConnectionTiming struct {
 	WorkspaceAgentName string      `json:"workspace_agent_name"`
 }
 
+// AgentServiceTiming is the time a service supervised by an agent spent in a
+// status. The span of the current status ends at the time of the request.
+type AgentServiceTiming struct {
+	StartedAt          time.Time                   `json:"started_at" format:"date-time"`
+	EndedAt            time.Time                   `json:"ended_at" format:"date-time"`
+	Status             WorkspaceAgentServiceStatus `json:"status" enums:"starting,running,restarting,stopped,failed"`
+	ServiceName        string                      `json:"service_name"`
+	RestartCount       int32                       `json:"restart_count"`
+	WorkspaceAgentID   string                      `json:"workspace_agent_id"`
+	WorkspaceAgentName string                      `json:"workspace_agent_name"`
+}
+
 type WorkspaceBuildTimings struct {
 	ProvisionerTimings []ProvisionerTiming `json:"provisioner_timings"`
 	// TODO: Consolidate agent-related timing metrics into a single struct when
 	// updating the API version
 	AgentScriptTimings     []AgentScriptTiming     `json:"agent_script_timings"`
 	AgentConnectionTimings []AgentConnectionTiming `json:"agent_connection_timings"`
+	AgentServiceTimings    []AgentServiceTiming    `json:"agent_service_timings"`
+	// CacheStats summarizes the provisioner timings of artifacts obtained
+	// during the init stage by whether they were reused from a cache.
+	CacheStats []ProvisionerCacheStats `json:"cache_stats"`
 }
 
 func (c *Client) WorkspaceBuildTimings(ctx context.Context, build uuid.UUID) (WorkspaceBuildTimings, error) {
@@ -0,0 +1,94 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CloudResourceVerificationStatus is the outcome of checking whether a cloud
+// resource of a deleted workspace still exists at its cloud provider.
+type CloudResourceVerificationStatus string
+
+const (
+	// CloudResourceVerificationStatusUnverified means the workspace was not
+	// deleted yet, or the resource was not checked yet.
+	CloudResourceVerificationStatusUnverified CloudResourceVerificationStatus = "unverified"
+	// CloudResourceVerificationStatusDeleted means the resource no longer
+	// exists at its cloud provider.
+	CloudResourceVerificationStatusDeleted CloudResourceVerificationStatus = "deleted"
+	// CloudResourceVerificationStatusLeaked means the resource still existed
+	// at its cloud provider after the workspace was deleted.
+	CloudResourceVerificationStatusLeaked CloudResourceVerificationStatus = "leaked"
+	// CloudResourceVerificationStatusUnknown means the resource could not be
+	// checked, e.g. because the checker does not support its type.
+	CloudResourceVerificationStatusUnknown CloudResourceVerificationStatus = "unknown"
+)
+
+// WorkspaceCloudResource is a resource at a cloud provider that was reported
+// by a build of a workspace.
+type WorkspaceCloudResource struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	Type        string    `json:"type"`
+	Name        string    `json:"name"`
+	// CloudID is the identifier of the resource at its cloud provider.
+	CloudID string `json:"cloud_id"`
+	// WorkspaceBuildID is the latest build that reported the resource.
+	WorkspaceBuildID   uuid.UUID                       `json:"workspace_build_id" format:"uuid"`
+	CreatedAt          time.Time                       `json:"created_at" format:"date-time"`
+	UpdatedAt          time.Time                       `json:"updated_at" format:"date-time"`
+	VerificationStatus CloudResourceVerificationStatus `json:"verification_status" enums:"unverified,deleted,leaked,unknown"`
+	// VerificationBuildID is the deletion build after which the resource was
+	// verified.
+	VerificationBuildID *uuid.UUID `json:"verification_build_id,omitempty" format:"uuid"`
+	// VerificationError explains why the verification status is unknown.
+	VerificationError string     `json:"verification_error,omitempty"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty" format:"date-time"`
+}
+
+// LeakedCloudResource is a cloud resource that still existed at its cloud
+// provider after the workspace that created it was deleted.
+type LeakedCloudResource struct {
+	Resource       WorkspaceCloudResource `json:"resource"`
+	WorkspaceName  string                 `json:"workspace_name"`
+	OwnerID        uuid.UUID              `json:"owner_id" format:"uuid"`
+	OwnerName      string                 `json:"owner_name"`
+	OrganizationID uuid.UUID              `json:"organization_id" format:"uuid"`
+	TemplateID     uuid.UUID              `json:"template_id" format:"uuid"`
+	TemplateName   string                 `json:"template_name"`
+}
+
+// WorkspaceCloudResources returns the cloud resources reported by the builds
+// of a workspace, along with their verification status once the workspace is
+// deleted.
+func (c *Client) WorkspaceCloudResources(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceCloudResource, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/cloud-resources", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resources []WorkspaceCloudResource
+	return resources, json.NewDecoder(res.Body).Decode(&resources)
+}
+
+// LeakedCloudResources returns the cloud resources of all deleted workspaces
+// that still existed at their cloud provider after the deletion.
+func (c *Client) LeakedCloudResources(ctx context.Context) ([]LeakedCloudResource, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/workspaces/leaked-cloud-resources", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resources []LeakedCloudResource
+	return resources, json.NewDecoder(res.Body).Decode(&resources)
+}
@@ -0,0 +1,73 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceCostSource is where the cost of a workspace comes from.
+type WorkspaceCostSource string
+
+const (
+	// WorkspaceCostSourceEstimate costs accrue the daily cost of the running
+	// workspace build.
+	WorkspaceCostSourceEstimate WorkspaceCostSource = "estimate"
+	// WorkspaceCostSourceBillingExport costs were imported from the billing
+	// export of the infrastructure provider and replace the estimate.
+	WorkspaceCostSourceBillingExport WorkspaceCostSource = "billing_export"
+)
+
+// WorkspaceCost is the cost a workspace accrued in a UTC calendar month, in
+// the same units as the daily cost of workspace resources. It is enforced
+// against the deployment's monthly cost budgets.
+type WorkspaceCost struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	// Month is the start of the UTC calendar month.
+	Month     time.Time           `json:"month" format:"date-time"`
+	Cost      float64             `json:"cost"`
+	Source    WorkspaceCostSource `json:"source" enums:"estimate,billing_export"`
+	UpdatedAt time.Time           `json:"updated_at" format:"date-time"`
+}
+
+// PutWorkspaceCostRequest imports the cost of a workspace for a month from a
+// billing export.
+type PutWorkspaceCostRequest struct {
+	// Month is any time within the UTC calendar month the cost was accrued in.
+	Month time.Time `json:"month" validate:"required" format:"date-time"`
+	Cost  float64   `json:"cost" validate:"gte=0"`
+}
+
+// WorkspaceCost returns the cost of a workspace in the current month.
+func (c *Client) WorkspaceCost(ctx context.Context, workspaceID uuid.UUID) (WorkspaceCost, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/cost", workspaceID), nil)
+	if err != nil {
+		return WorkspaceCost{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceCost{}, ReadBodyAsError(res)
+	}
+	var cost WorkspaceCost
+	return cost, json.NewDecoder(res.Body).Decode(&cost)
+}
+
+// PutWorkspaceCost imports the cost of a workspace for a month from a billing
+// export, replacing the estimate. Only deployment administrators may import
+// costs.
+func (c *Client) PutWorkspaceCost(ctx context.Context, workspaceID uuid.UUID, req PutWorkspaceCostRequest) (WorkspaceCost, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaces/%s/cost", workspaceID), req)
+	if err != nil {
+		return WorkspaceCost{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceCost{}, ReadBodyAsError(res)
+	}
+	var cost WorkspaceCost
+	return cost, json.NewDecoder(res.Body).Decode(&cost)
+}
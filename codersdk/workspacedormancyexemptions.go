@@ -0,0 +1,73 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceDormancyExemption exempts a workspace from the template's
+// time_til_dormant policy until it expires.
+type WorkspaceDormancyExemption struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	Reason      string    `json:"reason"`
+	ExpiresAt   time.Time `json:"expires_at" format:"date-time"`
+	// ApprovedBy is the template administrator that granted the exemption.
+	ApprovedBy uuid.UUID `json:"approved_by" format:"uuid"`
+	CreatedAt  time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt  time.Time `json:"updated_at" format:"date-time"`
+}
+
+// PutWorkspaceDormancyExemptionRequest grants or replaces the dormancy
+// exemption of a workspace.
+type PutWorkspaceDormancyExemptionRequest struct {
+	Reason    string    `json:"reason" validate:"required"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required" format:"date-time"`
+}
+
+// WorkspaceDormancyExemption returns the dormancy exemption of a workspace.
+func (c *Client) WorkspaceDormancyExemption(ctx context.Context, workspaceID uuid.UUID) (WorkspaceDormancyExemption, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/dormancy-exemption", workspaceID), nil)
+	if err != nil {
+		return WorkspaceDormancyExemption{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceDormancyExemption{}, ReadBodyAsError(res)
+	}
+	var exemption WorkspaceDormancyExemption
+	return exemption, json.NewDecoder(res.Body).Decode(&exemption)
+}
+
+// PutWorkspaceDormancyExemption grants or replaces the dormancy exemption of
+// a workspace. Only template administrators may grant exemptions.
+func (c *Client) PutWorkspaceDormancyExemption(ctx context.Context, workspaceID uuid.UUID, req PutWorkspaceDormancyExemptionRequest) (WorkspaceDormancyExemption, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaces/%s/dormancy-exemption", workspaceID), req)
+	if err != nil {
+		return WorkspaceDormancyExemption{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceDormancyExemption{}, ReadBodyAsError(res)
+	}
+	var exemption WorkspaceDormancyExemption
+	return exemption, json.NewDecoder(res.Body).Decode(&exemption)
+}
+
+// DeleteWorkspaceDormancyExemption revokes the dormancy exemption of a
+// workspace.
+func (c *Client) DeleteWorkspaceDormancyExemption(ctx context.Context, workspaceID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/dormancy-exemption", workspaceID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceDormancyHookEvent is the lifecycle event a dormancy hook is invoked
+// for.
+type WorkspaceDormancyHookEvent string
+
+const (
+	WorkspaceDormancyHookEventDormant  WorkspaceDormancyHookEvent = "dormant"
+	WorkspaceDormancyHookEventActivate WorkspaceDormancyHookEvent = "activate"
+)
+
+type WorkspaceDormancyHookStatus string
+
+const (
+	WorkspaceDormancyHookStatusPending   WorkspaceDormancyHookStatus = "pending"
+	WorkspaceDormancyHookStatusRunning   WorkspaceDormancyHookStatus = "running"
+	WorkspaceDormancyHookStatusSucceeded WorkspaceDormancyHookStatus = "succeeded"
+	WorkspaceDormancyHookStatusFailed    WorkspaceDormancyHookStatus = "failed"
+)
+
+// WorkspaceDormancyHook is the latest invocation of the deployment's dormancy
+// hook for a workspace. A dormant workspace is only activated once its
+// activate hook succeeded.
+type WorkspaceDormancyHook struct {
+	WorkspaceID uuid.UUID                   `json:"workspace_id" format:"uuid"`
+	Event       WorkspaceDormancyHookEvent  `json:"event" enums:"dormant,activate"`
+	Status      WorkspaceDormancyHookStatus `json:"status" enums:"pending,running,succeeded,failed"`
+	// Error is the error returned by the hook if it failed.
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+// WorkspaceDormancyHook returns the latest dormancy hook invocation of a
+// workspace.
+func (c *Client) WorkspaceDormancyHook(ctx context.Context, workspaceID uuid.UUID) (WorkspaceDormancyHook, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/dormancy-hook", workspaceID), nil)
+	if err != nil {
+		return WorkspaceDormancyHook{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceDormancyHook{}, ReadBodyAsError(res)
+	}
+	var hook WorkspaceDormancyHook
+	return hook, json.NewDecoder(res.Body).Decode(&hook)
+}
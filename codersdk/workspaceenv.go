@@ -0,0 +1,41 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceRefreshEnvResponse is returned once all agents of the workspace
+// have refreshed their environment.
+type WorkspaceRefreshEnvResponse struct {
+	Agents []WorkspaceAgentRefreshEnv `json:"agents"`
+}
+
+// WorkspaceAgentRefreshEnv describes the environment of a single agent after
+// a refresh.
+type WorkspaceAgentRefreshEnv struct {
+	AgentID uuid.UUID `json:"agent_id" format:"uuid"`
+	// EnvironmentVariables are the names of the variables exported to new
+	// sessions. Values are never returned.
+	EnvironmentVariables []string `json:"environment_variables"`
+}
+
+// RefreshWorkspaceEnv re-resolves the environment variables and user
+// secrets of the agents of a running workspace, e.g. after a token was
+// rotated. Only sessions started after the refresh see the new values.
+func (c *Client) RefreshWorkspaceEnv(ctx context.Context, id uuid.UUID) (WorkspaceRefreshEnvResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/refresh-env", id), nil)
+	if err != nil {
+		return WorkspaceRefreshEnvResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceRefreshEnvResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceRefreshEnvResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -0,0 +1,89 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWorkspaceLeaseRequest creates an ephemeral workspace from a template
+// that is deleted once the lease expires or is released. If the preset has
+// prebuilt workspaces, one of them is claimed instead of building a new one.
+type CreateWorkspaceLeaseRequest struct {
+	// Name is the name of the leased workspace. A random name is generated if
+	// empty.
+	Name string `json:"name,omitempty" validate:"omitempty,workspace_name"`
+	// TTLMillis is the lifetime of the lease. It may not exceed the
+	// deployment's maximum lease TTL.
+	TTLMillis               int64                     `json:"ttl_ms" validate:"required"`
+	TemplateVersionPresetID uuid.UUID                 `json:"template_version_preset_id,omitempty" format:"uuid"`
+	RichParameterValues     []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
+}
+
+// WorkspaceLease is a time-bound lease on an ephemeral workspace, typically
+// held by a CI job.
+type WorkspaceLease struct {
+	ID          uuid.UUID  `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID  `json:"workspace_id" format:"uuid"`
+	TemplateID  uuid.UUID  `json:"template_id" format:"uuid"`
+	OwnerID     uuid.UUID  `json:"owner_id" format:"uuid"`
+	CreatedAt   time.Time  `json:"created_at" format:"date-time"`
+	ExpiresAt   time.Time  `json:"expires_at" format:"date-time"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty" format:"date-time"`
+}
+
+// CreateWorkspaceLeaseResponse is returned when a lease is created. The
+// session token is only returned once.
+type CreateWorkspaceLeaseResponse struct {
+	Lease     WorkspaceLease `json:"lease"`
+	Workspace Workspace      `json:"workspace"`
+	// SessionToken is an API token that may only access the leased
+	// workspace, e.g. via `coder ssh`. It expires with the lease.
+	SessionToken string `json:"session_token"`
+}
+
+// CreateWorkspaceLease leases an ephemeral workspace built from the template.
+func (c *Client) CreateWorkspaceLease(ctx context.Context, templateID uuid.UUID, req CreateWorkspaceLeaseRequest) (CreateWorkspaceLeaseResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/leases", templateID), req)
+	if err != nil {
+		return CreateWorkspaceLeaseResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return CreateWorkspaceLeaseResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateWorkspaceLeaseResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceLease returns a workspace lease by ID.
+func (c *Client) WorkspaceLease(ctx context.Context, id uuid.UUID) (WorkspaceLease, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceleases/%s", id), nil)
+	if err != nil {
+		return WorkspaceLease{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceLease{}, ReadBodyAsError(res)
+	}
+	var lease WorkspaceLease
+	return lease, json.NewDecoder(res.Body).Decode(&lease)
+}
+
+// ReleaseWorkspaceLease releases a workspace lease and deletes its workspace.
+func (c *Client) ReleaseWorkspaceLease(ctx context.Context, id uuid.UUID) (WorkspaceLease, error) {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaceleases/%s", id), nil)
+	if err != nil {
+		return WorkspaceLease{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceLease{}, ReadBodyAsError(res)
+	}
+	var lease WorkspaceLease
+	return lease, json.NewDecoder(res.Body).Decode(&lease)
+}
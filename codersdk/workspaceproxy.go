@@ -219,3 +219,73 @@ func (c *Client) Regions(ctx context.Context) ([]Region, error) {
 	var regions RegionsResponse[Region]
 	return regions.Regions, json.NewDecoder(res.Body).Decode(&regions)
 }
+
+// RegionRecommendationRequest are the inputs of a region recommendation.
+type RegionRecommendationRequest struct {
+	// WorkspaceID optionally includes the latencies the agents of a workspace
+	// report to each region.
+	WorkspaceID uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
+	// ClientLatenciesMS are the latencies in milliseconds the client measured
+	// to each region, keyed by region ID.
+	ClientLatenciesMS map[uuid.UUID]int64 `json:"client_latencies_ms,omitempty"`
+}
+
+func (r RegionRecommendationRequest) asRequestOption() RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		if r.WorkspaceID != uuid.Nil {
+			q.Set("workspace", r.WorkspaceID.String())
+		}
+		for regionID, latency := range r.ClientLatenciesMS {
+			q.Add("latency", fmt.Sprintf("%s:%d", regionID, latency))
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// RegionCandidate is a region considered for a recommendation along with the
+// inputs of its score.
+type RegionCandidate struct {
+	Region Region `json:"region"`
+	// ClientLatencyMS is the latency the client reported to the region. It is
+	// omitted if the client did not report one.
+	ClientLatencyMS *int64 `json:"client_latency_ms,omitempty"`
+	// WorkspaceLatencyMS is the lowest latency the agents of the workspace
+	// report to the DERP relay of the region.
+	WorkspaceLatencyMS *int64 `json:"workspace_latency_ms,omitempty"`
+	// AssignedWorkspaces is the number of workspaces assigned to the region.
+	AssignedWorkspaces int64 `json:"assigned_workspaces"`
+	// Warnings are the health check warnings of the region.
+	Warnings []string `json:"warnings"`
+	// Score ranks the candidates, lower is better. Candidates with a client
+	// latency are always ranked before candidates without one.
+	Score float64 `json:"score"`
+}
+
+// RegionRecommendation is the region recommended for a client.
+type RegionRecommendation struct {
+	Recommended Region `json:"recommended"`
+	// Candidates are the healthy regions, best first.
+	Candidates []RegionCandidate `json:"candidates"`
+}
+
+// RegionRecommendation returns the region recommended for the latencies
+// reported by the client, taking the health and load of regions into account.
+func (c *Client) RegionRecommendation(ctx context.Context, req RegionRecommendationRequest) (RegionRecommendation, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		"/api/v2/regions/recommendation",
+		nil,
+		req.asRequestOption(),
+	)
+	if err != nil {
+		return RegionRecommendation{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return RegionRecommendation{}, ReadBodyAsError(res)
+	}
+
+	var recommendation RegionRecommendation
+	return recommendation, json.NewDecoder(res.Body).Decode(&recommendation)
+}
@@ -0,0 +1,58 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuiesceWorkspaceRequest quiesces the agents of a running workspace so
+// that its volumes can be backed up consistently.
+type QuiesceWorkspaceRequest struct {
+	// TimeoutSeconds is how long the agents stay quiesced before they
+	// resume automatically. Zero uses the agent default of five minutes.
+	TimeoutSeconds int64 `json:"timeout_seconds,omitempty"`
+}
+
+// QuiesceWorkspaceResponse is returned once all agents of the workspace are
+// quiesced.
+type QuiesceWorkspaceResponse struct {
+	// ResumesAt is the earliest time at which an agent resumes
+	// automatically. Backups must complete before then.
+	ResumesAt time.Time   `json:"resumes_at" format:"date-time"`
+	AgentIDs  []uuid.UUID `json:"agent_ids" format:"uuid"`
+}
+
+// QuiesceWorkspace flushes filesystems and runs the quiesce scripts of all
+// agents of the workspace. It returns once the workspace is safe to back
+// up. Quiescing a quiesced workspace extends the timeout.
+func (c *Client) QuiesceWorkspace(ctx context.Context, id uuid.UUID, req QuiesceWorkspaceRequest) (QuiesceWorkspaceResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/quiesce", id), req)
+	if err != nil {
+		return QuiesceWorkspaceResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return QuiesceWorkspaceResponse{}, ReadBodyAsError(res)
+	}
+	var resp QuiesceWorkspaceResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ResumeWorkspace resumes the agents of a quiesced workspace before the
+// timeout elapsed.
+func (c *Client) ResumeWorkspace(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/quiesce", id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -69,6 +69,24 @@ type Workspace struct {
 	AllowRenames     bool             `json:"allow_renames"`
 	Favorite         bool             `json:"favorite"`
 	NextStartAt      *time.Time       `json:"next_start_at" format:"date-time"`
+	// ExpiresAt is set for workspaces created from a template with a trial
+	// workspace TTL or a max lifetime, and is the earliest of the two. Once it
+	// passes, the workspace is stopped or deleted regardless of activity.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
+	// ArchivedAt being non-nil indicates a workspace that is archived. The
+	// resources of an archived workspace have been destroyed, but its
+	// parameters and metadata are kept. It must be unarchived before it can
+	// be started.
+	ArchivedAt *time.Time `json:"archived_at,omitempty" format:"date-time"`
+	// ReadOnlyAt being non-nil indicates a workspace that was placed in
+	// read-only mode during an investigation. Users can still connect to it,
+	// but its agents block outbound git pushes and no new builds can be
+	// created until the mode is lifted.
+	ReadOnlyAt *time.Time `json:"read_only_at,omitempty" format:"date-time"`
+	// DNSName is the stable DNS name registered for the workspace while it is
+	// running. It is only set when the deployment has a workspace DNS domain
+	// and the name has been registered with the DNS provider.
+	DNSName string `json:"dns_name,omitempty"`
 	// IsPrebuild indicates whether the workspace is a prebuilt workspace.
 	// Prebuilt workspaces are owned by the prebuilds system user and have specific behavior,
 	// such as being managed differently from regular workspaces.
@@ -78,12 +96,42 @@ type Workspace struct {
 	// TaskID, if set, indicates that the workspace is relevant to the given codersdk.Task.
 	TaskID     uuid.NullUUID          `json:"task_id,omitempty"`
 	SharedWith []SharedWorkspaceActor `json:"shared_with,omitempty"`
+	// DeprecationWarnings lists deprecations of the workspace's template and
+	// the template version of its latest build.
+	DeprecationWarnings []DeprecationWarning `json:"deprecation_warnings,omitempty"`
+	// LatestBuildFailureSummary is set if the latest build failed. It
+	// summarizes the root cause of the failure from the build logs, which is
+	// usually more actionable than the error of the build job.
+	LatestBuildFailureSummary *WorkspaceBuildFailureSummary `json:"latest_build_failure_summary,omitempty"`
+	// AssignedRegionID is the region the workspace was assigned when it was
+	// created from a template with auto_assign_region set. It is the ID of a
+	// workspace proxy, or the deployment ID for the primary region.
+	AssignedRegionID *uuid.UUID `json:"assigned_region_id,omitempty" format:"uuid"`
 }
 
 func (w Workspace) FullName() string {
 	return fmt.Sprintf("%s/%s", w.OwnerName, w.Name)
 }
 
+// WorkspaceBuildFailureSummary is the root cause of a failed workspace build.
+type WorkspaceBuildFailureSummary struct {
+	// Message is the most relevant error of the build, e.g. the error
+	// reported by a Terraform provider.
+	Message string `json:"message"`
+	// Detail elaborates on Message, if available.
+	Detail string `json:"detail,omitempty"`
+	// ResourceAddress is the address of the resource the build failed for,
+	// if known, e.g. "aws_instance.dev".
+	ResourceAddress string `json:"resource_address,omitempty"`
+}
+
+func (s WorkspaceBuildFailureSummary) String() string {
+	if s.ResourceAddress == "" {
+		return s.Message
+	}
+	return fmt.Sprintf("%s: %s", s.ResourceAddress, s.Message)
+}
+
 type WorkspaceHealth struct {
 	Healthy       bool        `json:"healthy" example:"false"`      // Healthy is true if the workspace is healthy.
 	FailingAgents []uuid.UUID `json:"failing_agents" format:"uuid"` // FailingAgents lists the IDs of the agents that are failing, if any.
@@ -115,6 +163,9 @@ const (
 	CreateWorkspaceBuildReasonJetbrainsConnection CreateWorkspaceBuildReason = "jetbrains_connection"
 	CreateWorkspaceBuildReasonTaskManualPause     CreateWorkspaceBuildReason = "task_manual_pause"
 	CreateWorkspaceBuildReasonTaskResume          CreateWorkspaceBuildReason = "task_resume"
+	CreateWorkspaceBuildReasonArchive             CreateWorkspaceBuildReason = "archive"
+	CreateWorkspaceBuildReasonUnarchive           CreateWorkspaceBuildReason = "unarchive"
+	CreateWorkspaceBuildReasonSelfRequested       CreateWorkspaceBuildReason = "self_requested"
 )
 
 // CreateWorkspaceBuildRequest provides options to update the latest workspace build.
@@ -140,6 +191,16 @@ type CreateWorkspaceBuildRequest struct {
 	// It currently supports restarting a workspace by starting it after a
 	// successful stop build.
 	OnSuccess *CreateWorkspaceBuildOnSuccessRequest `json:"on_success,omitempty"`
+	// TargetResources limits a start build to the listed Terraform resource
+	// addresses, e.g. "docker_container.workspace[0]". Each is destroyed and
+	// recreated, while every other resource, such as volumes, keeps its
+	// current state. The template must allow targeted builds.
+	TargetResources []string `json:"target_resources,omitempty"`
+	// IdempotencyKey makes retries of the request with the same key return
+	// the build created by the original request for 24 hours, instead of
+	// queuing another one. It may also be set with the Idempotency-Key
+	// header.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 // CreateWorkspaceBuildOnSuccessRequest queues a follow-up build that
@@ -296,6 +357,34 @@ func (c *Client) WatchWorkspace(ctx context.Context, id uuid.UUID) (<-chan Works
 	return wc, nil
 }
 
+// ImportWorkspaceStateRequest creates a workspace that adopts existing
+// infrastructure described by a Terraform state file.
+type ImportWorkspaceStateRequest struct {
+	// OwnerID is the user the workspace is created for. Defaults to the
+	// authenticated user.
+	OwnerID uuid.UUID `json:"owner_id,omitempty" format:"uuid"`
+	// Workspace specifies the template, name and parameters of the workspace.
+	Workspace CreateWorkspaceRequest `json:"workspace"`
+	// State is the Terraform state file. Every managed resource in the state
+	// must be declared by the template version.
+	State []byte `json:"state"`
+}
+
+// ImportWorkspaceState creates a workspace whose first build adopts the
+// resources in the given Terraform state.
+func (c *Client) ImportWorkspaceState(ctx context.Context, req ImportWorkspaceStateRequest) (Workspace, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaces/import-state", req)
+	if err != nil {
+		return Workspace{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Workspace{}, ReadBodyAsError(res)
+	}
+	var workspace Workspace
+	return workspace, json.NewDecoder(res.Body).Decode(&workspace)
+}
+
 type UpdateWorkspaceRequest struct {
 	Name string `json:"name,omitempty" validate:"username"`
 }
@@ -376,6 +465,39 @@ func (c *Client) PutExtendWorkspace(ctx context.Context, id uuid.UUID, req PutEx
 	return nil
 }
 
+// PostWorkspaceBumpRequest postpones the deadline of the active workspace
+// build by a duration.
+type PostWorkspaceBumpRequest struct {
+	// DurationMillis is added to the current deadline, or to the current
+	// time if the deadline passed. The result is capped by the max deadline
+	// of the build. It must be at most 30 days.
+	DurationMillis int64 `json:"duration_ms" validate:"required,gt=0"`
+}
+
+// WorkspaceBumpResponse is the deadline of the active workspace build after a
+// bump.
+type WorkspaceBumpResponse struct {
+	Deadline time.Time `json:"deadline" format:"date-time"`
+	// MaxDeadline is the deadline the workspace cannot be bumped past, if
+	// any.
+	MaxDeadline *time.Time `json:"max_deadline,omitempty" format:"date-time"`
+}
+
+// PostWorkspaceBump postpones the deadline of the latest workspace build by
+// the given duration, and returns the resulting deadline.
+func (c *Client) PostWorkspaceBump(ctx context.Context, id uuid.UUID, req PostWorkspaceBumpRequest) (WorkspaceBumpResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/bump", id), req)
+	if err != nil {
+		return WorkspaceBumpResponse{}, xerrors.Errorf("bump workspace deadline: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBumpResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceBumpResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 type PostWorkspaceUsageRequest struct {
 	AgentID uuid.UUID    `json:"agent_id" format:"uuid"`
 	AppName UsageAppName `json:"app_name"`
@@ -426,6 +548,36 @@ func (c *Client) PostWorkspaceUsage(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// PostWorkspaceHeartbeatRequest reports that an IDE plugin or another external
+// integration is actively using a workspace.
+type PostWorkspaceHeartbeatRequest struct {
+	// AgentID is the agent the integration is connected to. It may be omitted
+	// when the workspace has a single agent.
+	AgentID uuid.UUID `json:"agent_id,omitempty" format:"uuid"`
+	// AppName is the kind of session the integration provides. Heartbeats
+	// bump workspace activity the same way sessions of the built-in app do.
+	AppName UsageAppName `json:"app_name"`
+	// Plugin identifies the integration sending the heartbeat, e.g.
+	// "coder-neovim/0.3.1".
+	Plugin string `json:"plugin" validate:"required,max=128"`
+}
+
+// PostWorkspaceHeartbeat marks the workspace as being used by an external
+// integration. Heartbeats are rate limited per user and workspace, so
+// integrations should send one at most every minute while in use.
+func (c *Client) PostWorkspaceHeartbeat(ctx context.Context, id uuid.UUID, req PostWorkspaceHeartbeatRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/heartbeat", id.String())
+	res, err := c.Request(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return xerrors.Errorf("post workspace heartbeat: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // UpdateWorkspaceUsageWithBodyContext periodically posts workspace usage for the workspace
 // with the given id and app name in the background.
 // The caller is responsible for calling the returned function to stop the background
@@ -514,12 +666,63 @@ func (c *Client) UpdateWorkspaceDormancy(ctx context.Context, id uuid.UUID, req
 		return xerrors.Errorf("update workspace lock: %w", err)
 	}
 	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotModified {
+	// Accepted means the workspace is activated once the deployment's
+	// dormancy hook has restored its storage.
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted && res.StatusCode != http.StatusNotModified {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// UpdateWorkspaceReadOnly is a request to place a workspace in read-only mode
+// or lift the mode.
+type UpdateWorkspaceReadOnly struct {
+	ReadOnly bool `json:"read_only"`
+}
+
+// UpdateWorkspaceReadOnly places a workspace in read-only mode if
+// read_only=true and lifts the mode if read_only=false.
+func (c *Client) UpdateWorkspaceReadOnly(ctx context.Context, id uuid.UUID, req UpdateWorkspaceReadOnly) error {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaces/%s/read-only", id), req)
+	if err != nil {
+		return xerrors.Errorf("update workspace read-only mode: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusNotModified {
 		return ReadBodyAsError(res)
 	}
 	return nil
 }
 
+// ArchiveWorkspace destroys the resources of a workspace, keeping its
+// parameters and metadata so it can be unarchived later.
+func (c *Client) ArchiveWorkspace(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/archive", id), nil)
+	if err != nil {
+		return WorkspaceBuild{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var build WorkspaceBuild
+	return build, json.NewDecoder(res.Body).Decode(&build)
+}
+
+// UnarchiveWorkspace reprovisions the resources of an archived workspace.
+func (c *Client) UnarchiveWorkspace(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/unarchive", id), nil)
+	if err != nil {
+		return WorkspaceBuild{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var build WorkspaceBuild
+	return build, json.NewDecoder(res.Body).Decode(&build)
+}
+
 // UpdateWorkspaceAutomaticUpdatesRequest is a request to updates a workspace's automatic updates setting.
 type UpdateWorkspaceAutomaticUpdatesRequest struct {
 	AutomaticUpdates AutomaticUpdates `json:"automatic_updates"`
@@ -560,8 +763,41 @@ type WorkspaceFilter struct {
 	SharedWithUser string `json:"shared_with_user,omitempty" typescript:"-"`
 	// SharedWithGroup is the group name, group ID, or <org name>/<group name> of the group that the workspace is shared with
 	SharedWithGroup string `json:"shared_with_group,omitempty" typescript:"-"`
+	// Sort overrides the default ordering of the returned workspaces.
+	Sort WorkspaceSort `json:"sort,omitempty" typescript:"-"`
 	// FilterQuery supports a raw filter query string
 	FilterQuery string `json:"q,omitempty"`
+	// Fields limits the returned workspaces to the given JSON fields, e.g.
+	// "id", "name" and "owner_name". Other fields are left at their zero
+	// value. Omitting fields derived from the latest build (latest_build,
+	// latest_app_status, outdated, health and dns_name) makes listing
+	// considerably cheaper.
+	Fields []string `json:"fields,omitempty" typescript:"-"`
+}
+
+// WorkspaceSort is an ordering of workspaces, set with the "sort:" term of the
+// workspaces filter query. By default, favorite workspaces of the requester
+// come first, followed by running workspaces, ordered by owner and name.
+type WorkspaceSort string
+
+const (
+	WorkspaceSortLastUsedAsc   WorkspaceSort = "last_used_asc"
+	WorkspaceSortLastUsedDesc  WorkspaceSort = "last_used_desc"
+	WorkspaceSortNameAsc       WorkspaceSort = "name_asc"
+	WorkspaceSortNameDesc      WorkspaceSort = "name_desc"
+	WorkspaceSortCreatedAtAsc  WorkspaceSort = "created_at_asc"
+	WorkspaceSortCreatedAtDesc WorkspaceSort = "created_at_desc"
+)
+
+func (s WorkspaceSort) Valid() bool {
+	switch s {
+	case WorkspaceSortLastUsedAsc, WorkspaceSortLastUsedDesc,
+		WorkspaceSortNameAsc, WorkspaceSortNameDesc,
+		WorkspaceSortCreatedAtAsc, WorkspaceSortCreatedAtDesc:
+		return true
+	default:
+		return false
+	}
 }
 
 // asRequestOption returns a function that can be used in (*Client).Request.
@@ -595,6 +831,9 @@ func (f WorkspaceFilter) asRequestOption() RequestOption {
 		if f.SharedWithGroup != "" {
 			params = append(params, fmt.Sprintf("shared_with_group:%q", f.SharedWithGroup))
 		}
+		if f.Sort != "" {
+			params = append(params, fmt.Sprintf("sort:%s", f.Sort))
+		}
 		if f.FilterQuery != "" {
 			// If custom stuff is added, just add it on here.
 			params = append(params, f.FilterQuery)
@@ -602,6 +841,9 @@ func (f WorkspaceFilter) asRequestOption() RequestOption {
 
 		q := r.URL.Query()
 		q.Set("q", strings.Join(params, " "))
+		if len(f.Fields) > 0 {
+			q.Set("fields", strings.Join(f.Fields, ","))
+		}
 		r.URL.RawQuery = q.Encode()
 	}
 }
@@ -852,6 +1094,49 @@ func (c *Client) DeleteWorkspaceACL(ctx context.Context, workspaceID uuid.UUID)
 	return nil
 }
 
+// WorkspacePortForwardACL lists the users of a shared workspace that may only
+// connect to some ports of the workspace.
+type WorkspacePortForwardACL struct {
+	Users []WorkspacePortForwardACLUser `json:"users"`
+}
+
+type WorkspacePortForwardACLUser struct {
+	MinimalUser
+	Ports []int32 `json:"ports"`
+}
+
+type UpdateWorkspacePortForwardACL struct {
+	// UserPorts is a mapping from user UUIDs to the ports they may connect to.
+	// The workspace must be shared with the users. To allow a user to connect
+	// to any port again, use an empty list.
+	UserPorts map[string][]int32 `json:"user_ports"`
+}
+
+func (c *Client) WorkspacePortForwardACL(ctx context.Context, workspaceID uuid.UUID) (WorkspacePortForwardACL, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/port-forward-acl", workspaceID), nil)
+	if err != nil {
+		return WorkspacePortForwardACL{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspacePortForwardACL{}, ReadBodyAsError(res)
+	}
+	var acl WorkspacePortForwardACL
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
+func (c *Client) UpdateWorkspacePortForwardACL(ctx context.Context, workspaceID uuid.UUID, req UpdateWorkspacePortForwardACL) error {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/workspaces/%s/port-forward-acl", workspaceID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // ExternalAgentCredentials contains the credentials needed for an external agent to connect to Coder.
 type ExternalAgentCredentials struct {
 	Command    string `json:"command"`
@@ -108,12 +108,19 @@ type AgentConn interface {
 	ListProcesses(ctx context.Context) (ListProcessesResponse, error)
 	ListeningPorts(ctx context.Context) (codersdk.WorkspaceAgentListeningPortsResponse, error)
 	Netcheck(ctx context.Context) (healthsdk.AgentNetcheckReport, error)
+	RunNetcheck(ctx context.Context) (healthsdk.AgentNetworkDiagnosticsReport, error)
 	Ping(ctx context.Context) (time.Duration, bool, *ipnstate.PingResult, error)
 	ProcessOutput(ctx context.Context, id string, opts *ProcessOutputOptions) (ProcessOutputResponse, error)
 	PrometheusMetrics(ctx context.Context) ([]byte, error)
 	ReconnectingPTY(ctx context.Context, id uuid.UUID, height uint16, width uint16, command string, initOpts ...AgentReconnectingPTYInitOption) (net.Conn, error)
 	DeleteDevcontainer(ctx context.Context, devcontainerID string) error
 	RecreateDevcontainer(ctx context.Context, devcontainerID string) (codersdk.Response, error)
+	RebuildDevcontainer(ctx context.Context, devcontainerID string) (codersdk.Response, error)
+	Quiesce(ctx context.Context, req QuiesceRequest) (QuiesceResponse, error)
+	Resume(ctx context.Context) error
+	RestartService(ctx context.Context, name string) (codersdk.Response, error)
+	RefreshEnv(ctx context.Context) (RefreshEnvResponse, error)
+	SupportBundle(ctx context.Context) ([]byte, error)
 	SignalProcess(ctx context.Context, id string, signal string) error
 	StartProcess(ctx context.Context, req StartProcessRequest) (StartProcessResponse, error)
 	LS(ctx context.Context, path string, req LSRequest) (LSResponse, error)
@@ -424,6 +431,24 @@ func (c *agentConn) Netcheck(ctx context.Context) (healthsdk.AgentNetcheckReport
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// RunNetcheck asks the workspace agent to run a network diagnostic, probing
+// every DERP region and STUN server from the agent.
+func (c *agentConn) RunNetcheck(ctx context.Context) (healthsdk.AgentNetworkDiagnosticsReport, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/netcheck", nil)
+	if err != nil {
+		return healthsdk.AgentNetworkDiagnosticsReport{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return healthsdk.AgentNetworkDiagnosticsReport{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp healthsdk.AgentNetworkDiagnosticsReport
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // DebugMagicsock makes a request to the workspace agent's magicsock debug endpoint.
 func (c *agentConn) DebugMagicsock(ctx context.Context) ([]byte, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -889,7 +914,90 @@ func (c *agentConn) DeleteDevcontainer(ctx context.Context, devcontainerID strin
 func (c *agentConn) RecreateDevcontainer(ctx context.Context, devcontainerID string) (codersdk.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
-	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/containers/devcontainers/"+devcontainerID+"/recreate", nil)
+	return c.recreateDevcontainer(ctx, devcontainerID, false)
+}
+
+// RebuildDevcontainer recreates a devcontainer after rebuilding its image
+// without the build cache, so that base images are pulled and features are
+// re-run. Progress is streamed through the agent's devcontainer log source.
+func (c *agentConn) RebuildDevcontainer(ctx context.Context, devcontainerID string) (codersdk.Response, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	return c.recreateDevcontainer(ctx, devcontainerID, true)
+}
+
+func (c *agentConn) recreateDevcontainer(ctx context.Context, devcontainerID string, noCache bool) (codersdk.Response, error) {
+	path := "/api/v0/containers/devcontainers/" + devcontainerID + "/recreate"
+	if noCache {
+		path += "?no_cache=true"
+	}
+	res, err := c.apiRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return codersdk.Response{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return codersdk.Response{}, codersdk.ReadBodyAsError(res)
+	}
+	var m codersdk.Response
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		return codersdk.Response{}, xerrors.Errorf("decode response body: %w", err)
+	}
+	return m, nil
+}
+
+// QuiesceRequest is the request body for quiescing the workspace
+// agent.
+type QuiesceRequest struct {
+	// TimeoutSeconds is how long the agent stays quiesced before it
+	// resumes automatically. Zero uses the agent's default.
+	TimeoutSeconds int64 `json:"timeout_seconds,omitempty"`
+}
+
+// QuiesceResponse is returned once the workspace agent is quiesced.
+type QuiesceResponse struct {
+	ResumesAt time.Time `json:"resumes_at" format:"date-time"`
+}
+
+// Quiesce flushes filesystems and runs the template's quiesce script.
+// It returns once the workspace is safe to back up.
+func (c *agentConn) Quiesce(ctx context.Context, req QuiesceRequest) (QuiesceResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/quiesce", req)
+	if err != nil {
+		return QuiesceResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return QuiesceResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp QuiesceResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// Resume runs the template's resume script if the workspace is
+// quiesced.
+func (c *agentConn) Resume(ctx context.Context) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodDelete, "/api/v0/quiesce", nil)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// RestartService restarts a service supervised by the agent. The restart
+// happens in the background.
+func (c *agentConn) RestartService(ctx context.Context, name string) (codersdk.Response, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/services/"+neturl.PathEscape(name)+"/restart", nil)
 	if err != nil {
 		return codersdk.Response{}, xerrors.Errorf("do request: %w", err)
 	}
@@ -904,6 +1012,83 @@ func (c *agentConn) RecreateDevcontainer(ctx context.Context, devcontainerID str
 	return m, nil
 }
 
+// RefreshEnvResponse is returned once the workspace agent has refreshed
+// its environment variables and secrets.
+type RefreshEnvResponse struct {
+	// EnvironmentVariables are the names of the variables exported to new
+	// sessions. Values are never returned.
+	EnvironmentVariables []string `json:"environment_variables"`
+}
+
+// RefreshEnv re-resolves the environment variables and secrets of the
+// agent. Only sessions started after the refresh see the new values.
+func (c *agentConn) RefreshEnv(ctx context.Context) (RefreshEnvResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/env/refresh", nil)
+	if err != nil {
+		return RefreshEnvResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return RefreshEnvResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp RefreshEnvResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// SupportBundleManifest describes the contents of an agent support
+// bundle. It is stored as manifest.json in the archive.
+type SupportBundleManifest struct {
+	CollectedAt time.Time `json:"collected_at" format:"date-time"`
+	// Logs are the names of the log files included under logs/.
+	Logs []string `json:"logs"`
+	// Errors maps sections of the bundle that could not be collected to
+	// the reason.
+	Errors map[string]string `json:"errors"`
+}
+
+// SupportBundleEnvironment is a sanity check of the agent's environment.
+type SupportBundleEnvironment struct {
+	Variables map[string]string `json:"variables"`
+	Problems  []string          `json:"problems"`
+}
+
+// SupportBundleDiskUsage is the usage of the volume holding Path.
+type SupportBundleDiskUsage struct {
+	Path       string `json:"path"`
+	UsedBytes  int64  `json:"used_bytes"`
+	TotalBytes int64  `json:"total_bytes"`
+	Error      string `json:"error,omitempty"`
+}
+
+// supportBundleResponseMaxBytes guards against a misbehaving agent; the
+// agent itself caps its logs well below this.
+const supportBundleResponseMaxBytes int64 = 10 * 1024 * 1024
+
+// SupportBundle asks the agent to collect a diagnostics bundle. It returns
+// a zip archive.
+func (c *agentConn) SupportBundle(ctx context.Context) ([]byte, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/support-bundle", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	bs, err := io.ReadAll(io.LimitReader(res.Body, supportBundleResponseMaxBytes+1))
+	if err != nil {
+		return nil, xerrors.Errorf("read response body: %w", err)
+	}
+	if int64(len(bs)) > supportBundleResponseMaxBytes {
+		return nil, xerrors.Errorf("response exceeds %d bytes", supportBundleResponseMaxBytes)
+	}
+	return bs, nil
+}
+
 // StartProcessRequest is the request body for starting a
 // process on the workspace agent.
 type StartProcessRequest struct {
@@ -403,6 +403,21 @@ func (mr *MockAgentConnMockRecorder) PrometheusMetrics(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrometheusMetrics", reflect.TypeOf((*MockAgentConn)(nil).PrometheusMetrics), ctx)
 }
 
+// Quiesce mocks base method.
+func (m *MockAgentConn) Quiesce(ctx context.Context, req workspacesdk.QuiesceRequest) (workspacesdk.QuiesceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quiesce", ctx, req)
+	ret0, _ := ret[0].(workspacesdk.QuiesceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quiesce indicates an expected call of Quiesce.
+func (mr *MockAgentConnMockRecorder) Quiesce(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quiesce", reflect.TypeOf((*MockAgentConn)(nil).Quiesce), ctx, req)
+}
+
 // ReadFile mocks base method.
 func (m *MockAgentConn) ReadFile(ctx context.Context, path string, offset, limit int64) (io.ReadCloser, string, error) {
 	m.ctrl.T.Helper()
@@ -434,6 +449,21 @@ func (mr *MockAgentConnMockRecorder) ReadFileLines(ctx, path, offset, limit, lim
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadFileLines", reflect.TypeOf((*MockAgentConn)(nil).ReadFileLines), ctx, path, offset, limit, limits)
 }
 
+// RebuildDevcontainer mocks base method.
+func (m *MockAgentConn) RebuildDevcontainer(ctx context.Context, devcontainerID string) (codersdk.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildDevcontainer", ctx, devcontainerID)
+	ret0, _ := ret[0].(codersdk.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildDevcontainer indicates an expected call of RebuildDevcontainer.
+func (mr *MockAgentConnMockRecorder) RebuildDevcontainer(ctx, devcontainerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildDevcontainer", reflect.TypeOf((*MockAgentConn)(nil).RebuildDevcontainer), ctx, devcontainerID)
+}
+
 // ReconnectingPTY mocks base method.
 func (m *MockAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID, height, width uint16, command string, initOpts ...workspacesdk.AgentReconnectingPTYInitOption) (net.Conn, error) {
 	m.ctrl.T.Helper()
@@ -469,6 +499,21 @@ func (mr *MockAgentConnMockRecorder) RecreateDevcontainer(ctx, devcontainerID an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecreateDevcontainer", reflect.TypeOf((*MockAgentConn)(nil).RecreateDevcontainer), ctx, devcontainerID)
 }
 
+// RefreshEnv mocks base method.
+func (m *MockAgentConn) RefreshEnv(ctx context.Context) (workspacesdk.RefreshEnvResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshEnv", ctx)
+	ret0, _ := ret[0].(workspacesdk.RefreshEnvResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshEnv indicates an expected call of RefreshEnv.
+func (mr *MockAgentConnMockRecorder) RefreshEnv(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshEnv", reflect.TypeOf((*MockAgentConn)(nil).RefreshEnv), ctx)
+}
+
 // ResolvePath mocks base method.
 func (m *MockAgentConn) ResolvePath(ctx context.Context, path string) (string, error) {
 	m.ctrl.T.Helper()
@@ -484,6 +529,50 @@ func (mr *MockAgentConnMockRecorder) ResolvePath(ctx, path any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolvePath", reflect.TypeOf((*MockAgentConn)(nil).ResolvePath), ctx, path)
 }
 
+// Resume mocks base method.
+func (m *MockAgentConn) Resume(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockAgentConnMockRecorder) Resume(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockAgentConn)(nil).Resume), ctx)
+}
+
+// RestartService mocks base method.
+func (m *MockAgentConn) RestartService(ctx context.Context, name string) (codersdk.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartService", ctx, name)
+	ret0, _ := ret[0].(codersdk.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestartService indicates an expected call of RestartService.
+func (mr *MockAgentConnMockRecorder) RestartService(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartService", reflect.TypeOf((*MockAgentConn)(nil).RestartService), ctx, name)
+}
+
+// RunNetcheck mocks base method.
+func (m *MockAgentConn) RunNetcheck(ctx context.Context) (healthsdk.AgentNetworkDiagnosticsReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunNetcheck", ctx)
+	ret0, _ := ret[0].(healthsdk.AgentNetworkDiagnosticsReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunNetcheck indicates an expected call of RunNetcheck.
+func (mr *MockAgentConnMockRecorder) RunNetcheck(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunNetcheck", reflect.TypeOf((*MockAgentConn)(nil).RunNetcheck), ctx)
+}
+
 // SSH mocks base method.
 func (m *MockAgentConn) SSH(ctx context.Context) (*gonet.TCPConn, error) {
 	m.ctrl.T.Helper()
@@ -629,6 +718,21 @@ func (mr *MockAgentConnMockRecorder) StopDesktopRecording(ctx, req any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopDesktopRecording", reflect.TypeOf((*MockAgentConn)(nil).StopDesktopRecording), ctx, req)
 }
 
+// SupportBundle mocks base method.
+func (m *MockAgentConn) SupportBundle(ctx context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SupportBundle", ctx)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SupportBundle indicates an expected call of SupportBundle.
+func (mr *MockAgentConnMockRecorder) SupportBundle(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupportBundle", reflect.TypeOf((*MockAgentConn)(nil).SupportBundle), ctx)
+}
+
 // TailnetConn mocks base method.
 func (m *MockAgentConn) TailnetConn() *tailnet.Conn {
 	m.ctrl.T.Helper()
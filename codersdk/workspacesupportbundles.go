@@ -0,0 +1,53 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWorkspaceSupportBundleRequest is the request to collect a support
+// bundle from the agents of a workspace.
+type CreateWorkspaceSupportBundleRequest struct {
+	// Message describes the problem. It is included in the notification sent
+	// to administrators.
+	Message string `json:"message,omitempty" validate:"lte=4096"`
+}
+
+// WorkspaceSupportBundleAgent is the collection result of a single agent.
+type WorkspaceSupportBundleAgent struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	Name      string    `json:"name"`
+	Collected bool      `json:"collected"`
+	// Error is set if the bundle of the agent could not be collected.
+	Error string `json:"error,omitempty"`
+}
+
+// WorkspaceSupportBundle is a diagnostics bundle collected from the agents
+// of a workspace. The zip archive can be downloaded with Client.Download
+// using FileID.
+type WorkspaceSupportBundle struct {
+	FileID    uuid.UUID                     `json:"file_id" format:"uuid"`
+	CreatedAt time.Time                     `json:"created_at" format:"date-time"`
+	Agents    []WorkspaceSupportBundleAgent `json:"agents"`
+}
+
+// CreateWorkspaceSupportBundle asks the agents of the workspace to collect
+// diagnostics and stores them as a downloadable bundle. Administrators are
+// notified about the bundle.
+func (c *Client) CreateWorkspaceSupportBundle(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspaceSupportBundleRequest) (WorkspaceSupportBundle, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/support-bundle", workspaceID), req)
+	if err != nil {
+		return WorkspaceSupportBundle{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceSupportBundle{}, ReadBodyAsError(res)
+	}
+	var bundle WorkspaceSupportBundle
+	return bundle, json.NewDecoder(res.Body).Decode(&bundle)
+}
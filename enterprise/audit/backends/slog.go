@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/fatih/structs"
+	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 
 	"cdr.dev/slog/v3"
@@ -45,6 +46,11 @@ func (*SlogExporter) fieldToSlog(field *structs.Field) slog.Field {
 		val = ty.IPNet.IP.String()
 	case sql.NullString:
 		val = ty.String
+	case uuid.NullUUID:
+		val = ""
+		if ty.Valid {
+			val = ty.UUID.String()
+		}
 	}
 
 	return slog.F(field.Name(), val)
@@ -21,9 +21,9 @@ var AuditActionMap = map[string][]codersdk.AuditAction{
 	"GitSSHKey":                     {codersdk.AuditActionCreate},
 	"Template":                      {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 	"TemplateVersion":               {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":                          {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":                     {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":                {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"User":                          {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionImpersonate},
+	"Workspace":                     {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionUpload, codersdk.AuditActionDownload},
+	"WorkspaceBuild":                {codersdk.AuditActionStart, codersdk.AuditActionStop, codersdk.AuditActionWrite},
 	"Group":                         {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 	"APIKey":                        {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 	"License":                       {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
@@ -37,6 +37,10 @@ var AuditActionMap = map[string][]codersdk.AuditAction{
 	"Chat":                          {codersdk.AuditActionCreate, codersdk.AuditActionWrite}, // chats get 'archived' by users, not deleted.
 	"UserSecret":                    {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 	"UserSkill":                     {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceAppShareLink":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete, codersdk.AuditActionLogin},
+	"TemplateSecret":                {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionDownload},
+	"DeploymentBundle":              {codersdk.AuditActionDownload},
+	"VCSEventMapping":               {codersdk.AuditActionCreate, codersdk.AuditActionDelete, codersdk.AuditActionStart},
 }
 
 type Action string
@@ -131,6 +135,27 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"cors_behavior":                     ActionTrack,
 		"disable_module_cache":              ActionTrack,
 		"time_til_autostop_notify":          ActionTrack,
+		"provisioner_plan_timeout":          ActionTrack,
+		"provisioner_apply_timeout":         ActionTrack,
+		"trial_workspace_ttl":               ActionTrack,
+		"build_log_retention":               ActionTrack,
+		"requeue_reaped_builds":             ActionTrack,
+		"agent_rollout_channel":             ActionTrack,
+		"allow_targeted_builds":             ActionTrack,
+		"reconfirm_parameters":              ActionTrack,
+		"deprecation_cutoff":                ActionTrack,
+		"nightly_stop_time":                 ActionTrack,
+		"max_lifetime":                      ActionTrack,
+		"max_lifetime_action":               ActionTrack,
+		"idle_reclaim_ttl":                  ActionTrack,
+		"idle_reclaim_resource_selector":    ActionTrack,
+		"activity_bump_connection_types":    ActionTrack,
+		"activity_bump_max_per_day":         ActionTrack,
+		"pre_build_hook_url":                ActionTrack,
+		"post_build_hook_url":               ActionTrack,
+		"auto_assign_region":                ActionTrack,
+		"notify_build_failures":             ActionTrack,
+		"disable_provider_cache":            ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                      ActionTrack,
@@ -151,6 +176,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"source_example_id":       ActionIgnore, // Never changes.
 		"has_ai_task":             ActionIgnore, // Never changes.
 		"has_external_agent":      ActionIgnore, // Never changes.
+		"deprecated":              ActionTrack,
+		"deprecation_cutoff":      ActionTrack,
 	},
 	&database.User{}: {
 		"id":                           ActionTrack,
@@ -193,6 +220,9 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"next_start_at":      ActionTrack,
 		"group_acl":          ActionTrack,
 		"user_acl":           ActionTrack,
+		"expires_at":         ActionTrack,
+		"archived_at":        ActionTrack,
+		"read_only_at":       ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                         ActionIgnore,
@@ -259,6 +289,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"scopes":           ActionIgnore,
 		"allow_list":       ActionIgnore,
 		"token_name":       ActionIgnore,
+		"template_ids":     ActionTrack,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -271,6 +302,11 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"id":                     ActionIgnore,
 		"dismissed_healthchecks": ActionTrack,
 	},
+	&database.DeploymentBundle{}: {
+		"id":       ActionIgnore,
+		"filename": ActionTrack,
+		"files":    ActionTrack,
+	},
 	&database.NotificationsSettings{}: {
 		"id":              ActionIgnore,
 		"notifier_paused": ActionTrack,
@@ -355,6 +391,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"icon":                       ActionTrack,
 		"shareable_workspace_owners": ActionTrack,
 		"default_org_member_roles":   ActionTrack,
+		"provisioner_tag_namespace":  ActionTrack,
+		"provisioner_daemon_quota":   ActionTrack,
 	},
 	&database.NotificationTemplate{}: {
 		"id":                 ActionIgnore,
@@ -487,6 +525,17 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"requires_action_deadline_at": ActionIgnore, // Internal pending-action deadline.
 		"compaction_requested_at":     ActionIgnore, // Internal one-shot manual compaction signal.
 	},
+	&database.WorkspaceAppShareLink{}: {
+		"id":            ActionTrack,
+		"workspace_id":  ActionTrack,
+		"agent_name":    ActionTrack,
+		"app_slug":      ActionTrack,
+		"hashed_secret": ActionSecret,
+		"created_by":    ActionTrack,
+		"created_at":    ActionIgnore,
+		"expires_at":    ActionTrack,
+		"revoked_at":    ActionTrack,
+	},
 	&database.UserSkill{}: {
 		"id":          ActionTrack,
 		"user_id":     ActionTrack,
@@ -510,6 +559,35 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"created_at":   ActionIgnore,
 		"updated_at":   ActionIgnore,
 	},
+	&database.TemplateSecret{}: {
+		"id":          ActionTrack,
+		"template_id": ActionTrack,
+		"name":        ActionTrack,
+		"description": ActionTrack,
+
+		"value": ActionSecret,
+
+		"value_key_id": ActionIgnore,
+		"created_at":   ActionIgnore,
+		"updated_at":   ActionIgnore,
+	},
+	&database.VCSEventMapping{}: {
+		"id":           ActionTrack,
+		"template_id":  ActionTrack,
+		"name":         ActionTrack,
+		"repository":   ActionTrack,
+		"branch":       ActionTrack,
+		"action":       ActionTrack,
+		"workspace_id": ActionTrack,
+		"created_by":   ActionTrack,
+
+		"signing_secret": ActionSecret,
+
+		"signing_secret_key_id": ActionIgnore,
+		"created_at":            ActionIgnore,
+		"updated_at":            ActionIgnore,
+		"last_triggered_at":     ActionIgnore,
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
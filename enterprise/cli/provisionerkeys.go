@@ -97,7 +97,7 @@ func (r *RootCmd) provisionerKeysList() *serpent.Command {
 	var (
 		orgContext = agpl.NewOrganizationContext()
 		formatter  = cliui.NewOutputFormatter(
-			cliui.TableFormat([]codersdk.ProvisionerKey{}, []string{"created at", "name", "tags"}),
+			cliui.TableFormat([]codersdk.ProvisionerKey{}, []string{"created at", "name", "tags", "last used at"}),
 			cliui.JSONFormat(),
 		)
 	)
@@ -479,6 +479,12 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 					r.Get("/", api.workspaceSharingSettings)
 					r.Patch("/", api.patchWorkspaceSharingSettings)
 				})
+
+				r.Route("/provisioner-daemons", func(r chi.Router) {
+					r.Use(api.RequireFeatureMW(codersdk.FeatureExternalProvisionerDaemons))
+					r.Get("/", api.provisionerDaemonSettings)
+					r.Patch("/", api.patchProvisionerDaemonSettings)
+				})
 			})
 		})
 
@@ -553,6 +559,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 					httpmw.ExtractProvisionerKeyParam(options.Database),
 				)
 				r.Delete("/", api.deleteProvisionerKey)
+				r.Post("/rotate", api.rotateProvisionerKey)
 			})
 		})
 		// TODO: provisioner daemons are not scoped to organizations in the database, so placing them
@@ -587,6 +594,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				httpmw.ExtractTemplateParam(api.Database),
 			)
 			r.Get("/available", api.templateAvailablePermissions)
+			r.Get("/changes", api.templateACLChanges)
 			r.Get("/", api.templateACL)
 			r.Patch("/", api.patchTemplateACL)
 		})
@@ -697,11 +705,16 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		})
 		// The /notifications base route is mounted by the AGPL router, so we can't group it here.
 		// Additionally, because we have a static route for /notifications/templates/system which conflicts
-		// with the below route, we need to register this route without any mounts or groups to make both work.
-		r.With(
+		// with the below routes, we need to register them without any mounts or groups to make both work.
+		notificationTemplateRouter := r.With(
 			apiKeyMiddleware,
 			httpmw.ExtractNotificationTemplateParam(options.Database),
-		).Put("/notifications/templates/{notification_template}/method", api.updateNotificationTemplateMethod)
+		)
+		notificationTemplateRouter.Put("/notifications/templates/{notification_template}/method", api.updateNotificationTemplateMethod)
+		notificationTemplateRouter.Get("/notifications/templates/{notification_template}/webhook", api.notificationTemplateWebhook)
+		notificationTemplateRouter.Put("/notifications/templates/{notification_template}/webhook", api.putNotificationTemplateWebhook)
+		notificationTemplateRouter.Delete("/notifications/templates/{notification_template}/webhook", api.deleteNotificationTemplateWebhook)
+		notificationTemplateRouter.Get("/notifications/templates/{notification_template}/deliveries", api.notificationTemplateDeliveries)
 
 		r.Route("/workspaces/{workspace}/external-agent", func(r chi.Router) {
 			r.Use(
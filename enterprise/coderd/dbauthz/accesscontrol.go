@@ -16,6 +16,7 @@ func (EnterpriseTemplateAccessControlStore) GetTemplateAccessControl(t database.
 	return agpldbz.TemplateAccessControl{
 		RequireActiveVersion: t.RequireActiveVersion,
 		Deprecated:           t.Deprecated,
+		DeprecationCutoff:    t.DeprecationCutoff,
 	}
 }
 
@@ -24,6 +25,7 @@ func (EnterpriseTemplateAccessControlStore) SetTemplateAccessControl(ctx context
 		ID:                   id,
 		RequireActiveVersion: opts.RequireActiveVersion,
 		Deprecated:           opts.Deprecated,
+		DeprecationCutoff:    opts.DeprecationCutoff,
 	})
 	if err != nil {
 		return xerrors.Errorf("update template access control: %w", err)
@@ -0,0 +1,236 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	defaultNotificationDeliveriesLimit = 50
+	maxNotificationDeliveriesLimit     = 500
+)
+
+// @Summary Get notification template webhook
+// @ID get-notification-template-webhook
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param notification_template path string true "Notification template UUID" format(uuid)
+// @Success 200 {object} codersdk.NotificationTemplateWebhook
+// @Router /api/v2/notifications/templates/{notification_template}/webhook [get]
+func (api *API) notificationTemplateWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.NotificationTemplateParam(r)
+	)
+
+	webhook, err := api.Database.GetNotificationTemplateWebhookByTemplateID(ctx, template.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The notification template has no webhook overrides.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationTemplateWebhook(webhook))
+}
+
+// @Summary Update notification template webhook
+// @ID update-notification-template-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param notification_template path string true "Notification template UUID" format(uuid)
+// @Param request body codersdk.UpdateNotificationTemplateWebhookRequest true "Webhook overrides"
+// @Success 200 {object} codersdk.NotificationTemplateWebhook
+// @Router /api/v2/notifications/templates/{notification_template}/webhook [put]
+func (api *API) putNotificationTemplateWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.NotificationTemplateParam(r)
+	)
+
+	var req codersdk.UpdateNotificationTemplateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var validations []codersdk.ValidationError
+	if req.Endpoint != "" {
+		u, err := url.Parse(req.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "endpoint",
+				Detail: fmt.Sprintf("%q is not a valid HTTP(S) URL", req.Endpoint),
+			})
+		}
+	}
+	if req.PayloadTemplate != "" {
+		if _, err := dispatch.ParseWebhookPayloadTemplate(req.PayloadTemplate); err != nil {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "payload_template",
+				Detail: err.Error(),
+			})
+		}
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid notification template webhook.",
+			Validations: validations,
+		})
+		return
+	}
+
+	var webhook database.NotificationTemplateWebhook
+	err := api.Database.InTx(func(tx database.Store) error {
+		signingSecret := ""
+		if req.SigningSecret != nil {
+			signingSecret = *req.SigningSecret
+		} else {
+			existing, err := tx.GetNotificationTemplateWebhookByTemplateID(ctx, template.ID)
+			if err != nil && !httpapi.Is404Error(err) {
+				return err
+			}
+			signingSecret = existing.SigningSecret
+		}
+
+		var err error
+		webhook, err = tx.UpsertNotificationTemplateWebhook(ctx, database.UpsertNotificationTemplateWebhookParams{
+			NotificationTemplateID: template.ID,
+			Endpoint:               req.Endpoint,
+			PayloadTemplate:        req.PayloadTemplate,
+			SigningSecret:          signingSecret,
+			UpdatedAt:              dbtime.Now(),
+		})
+		return err
+	}, nil)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationTemplateWebhook(webhook))
+}
+
+// @Summary Delete notification template webhook
+// @ID delete-notification-template-webhook
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param notification_template path string true "Notification template UUID" format(uuid)
+// @Success 204
+// @Router /api/v2/notifications/templates/{notification_template}/webhook [delete]
+func (api *API) deleteNotificationTemplateWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.NotificationTemplateParam(r)
+	)
+
+	err := api.Database.DeleteNotificationTemplateWebhook(ctx, template.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get notification template delivery status
+// @ID get-notification-template-delivery-status
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param notification_template path string true "Notification template UUID" format(uuid)
+// @Param method query string false "Delivery method" Enums(smtp,webhook,inbox)
+// @Param limit query int false "Maximum number of messages, 50 by default"
+// @Success 200 {array} codersdk.NotificationMessageDelivery
+// @Router /api/v2/notifications/templates/{notification_template}/deliveries [get]
+func (api *API) notificationTemplateDeliveries(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.NotificationTemplateParam(r)
+	)
+
+	qp := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	method := p.String(qp, "", "method")
+	limit := p.PositiveInt32(qp, defaultNotificationDeliveriesLimit, "limit")
+	p.ErrorExcessParams(qp)
+	var nm database.NullNotificationMethod
+	if method != "" {
+		nm = database.NullNotificationMethod{NotificationMethod: database.NotificationMethod(method), Valid: true}
+		if !nm.NotificationMethod.Valid() {
+			p.Errors = append(p.Errors, codersdk.ValidationError{
+				Field:  "method",
+				Detail: fmt.Sprintf("%q is not a valid notification method", method),
+			})
+		}
+	}
+	if limit == 0 || limit > maxNotificationDeliveriesLimit {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "limit",
+			Detail: fmt.Sprintf("must be between 1 and %d", maxNotificationDeliveriesLimit),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	messages, err := api.Database.GetNotificationMessagesByTemplateID(ctx, database.GetNotificationMessagesByTemplateIDParams{
+		NotificationTemplateID: template.ID,
+		Method:                 nm,
+		Limit:                  limit,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	deliveries := make([]codersdk.NotificationMessageDelivery, 0, len(messages))
+	for _, msg := range messages {
+		delivery := codersdk.NotificationMessageDelivery{
+			ID:           msg.ID,
+			UserID:       msg.UserID,
+			Method:       string(msg.Method),
+			Status:       codersdk.NotificationMessageStatus(msg.Status),
+			StatusReason: msg.StatusReason.String,
+			AttemptCount: msg.AttemptCount.Int32,
+			CreatedAt:    msg.CreatedAt,
+		}
+		if msg.UpdatedAt.Valid {
+			delivery.UpdatedAt = &msg.UpdatedAt.Time
+		}
+		if msg.NextRetryAfter.Valid && msg.Status == database.NotificationMessageStatusTemporaryFailure {
+			delivery.NextRetryAfter = &msg.NextRetryAfter.Time
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, deliveries)
+}
+
+func convertNotificationTemplateWebhook(webhook database.NotificationTemplateWebhook) codersdk.NotificationTemplateWebhook {
+	return codersdk.NotificationTemplateWebhook{
+		NotificationTemplateID: webhook.NotificationTemplateID,
+		Endpoint:               webhook.Endpoint,
+		PayloadTemplate:        webhook.PayloadTemplate,
+		HasSigningSecret:       webhook.SigningSecret != "",
+		UpdatedAt:              webhook.UpdatedAt,
+	}
+}
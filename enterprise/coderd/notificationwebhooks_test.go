@@ -0,0 +1,122 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNotificationTemplateWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, firstUser := coderdenttest.New(t, createOpts(t))
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+	templateID := notifications.TemplateWorkspaceDormant
+
+	// Without overrides the deployment configuration applies.
+	_, err := client.NotificationTemplateWebhook(ctx, templateID)
+	var sdkError *codersdk.Error
+	require.ErrorAs(t, err, &sdkError)
+	require.Equal(t, http.StatusNotFound, sdkError.StatusCode())
+
+	// Endpoints and payload templates are validated.
+	_, err = client.UpdateNotificationTemplateWebhook(ctx, templateID, codersdk.UpdateNotificationTemplateWebhookRequest{
+		Endpoint:        "ftp://hooks.example.com",
+		PayloadTemplate: `{"text": {{ .Title }`,
+	})
+	require.ErrorAs(t, err, &sdkError)
+	require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+	require.Len(t, sdkError.Validations, 2)
+
+	webhook, err := client.UpdateNotificationTemplateWebhook(ctx, templateID, codersdk.UpdateNotificationTemplateWebhookRequest{
+		Endpoint:        "https://hooks.slack.com/services/T000/B000/XXXX",
+		PayloadTemplate: `{"text": {{ json .Title }}}`,
+		SigningSecret:   ptr.Ref("s3cr3t"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, templateID, webhook.NotificationTemplateID)
+	require.True(t, webhook.HasSigningSecret)
+
+	// Omitting the signing secret keeps the current one.
+	webhook, err = client.UpdateNotificationTemplateWebhook(ctx, templateID, codersdk.UpdateNotificationTemplateWebhookRequest{
+		Endpoint: "https://hooks.example.com/coder",
+	})
+	require.NoError(t, err)
+	require.Empty(t, webhook.PayloadTemplate)
+	require.True(t, webhook.HasSigningSecret)
+
+	webhook, err = client.UpdateNotificationTemplateWebhook(ctx, templateID, codersdk.UpdateNotificationTemplateWebhookRequest{
+		Endpoint:      "https://hooks.example.com/coder",
+		SigningSecret: ptr.Ref(""),
+	})
+	require.NoError(t, err)
+	require.False(t, webhook.HasSigningSecret)
+
+	got, err := client.NotificationTemplateWebhook(ctx, templateID)
+	require.NoError(t, err)
+	require.Equal(t, webhook, got)
+
+	// Members cannot see or change the overrides.
+	_, err = memberClient.NotificationTemplateWebhook(ctx, templateID)
+	require.ErrorAs(t, err, &sdkError)
+	require.Equal(t, http.StatusNotFound, sdkError.StatusCode())
+
+	err = client.DeleteNotificationTemplateWebhook(ctx, templateID)
+	require.NoError(t, err)
+	_, err = client.NotificationTemplateWebhook(ctx, templateID)
+	require.ErrorAs(t, err, &sdkError)
+	require.Equal(t, http.StatusNotFound, sdkError.StatusCode())
+}
+
+func TestNotificationTemplateDeliveries(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, db, firstUser := coderdenttest.NewWithDatabase(t, createOpts(t))
+	templateID := notifications.TemplateWorkspaceDormant
+
+	for _, method := range []database.NotificationMethod{database.NotificationMethodWebhook, database.NotificationMethodSmtp} {
+		err := db.EnqueueNotificationMessage(ctx, database.EnqueueNotificationMessageParams{
+			ID:                     uuid.New(),
+			NotificationTemplateID: templateID,
+			UserID:                 firstUser.UserID,
+			Method:                 method,
+			Payload:                []byte("{}"),
+			CreatedBy:              "test",
+			CreatedAt:              dbtime.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	deliveries, err := client.NotificationTemplateDeliveries(ctx, templateID, codersdk.NotificationMessageDeliveriesRequest{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+
+	deliveries, err = client.NotificationTemplateDeliveries(ctx, templateID, codersdk.NotificationMessageDeliveriesRequest{
+		Method: string(database.NotificationMethodWebhook),
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "webhook", deliveries[0].Method)
+	require.Equal(t, codersdk.NotificationMessageStatusPending, deliveries[0].Status)
+	require.Nil(t, deliveries[0].NextRetryAfter)
+
+	_, err = client.NotificationTemplateDeliveries(ctx, templateID, codersdk.NotificationMessageDeliveriesRequest{
+		Method: "carrier-pigeon",
+	})
+	var sdkError *codersdk.Error
+	require.ErrorAs(t, err, &sdkError)
+	require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+}
@@ -10,6 +10,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/prebuilds"
 )
 
@@ -43,13 +44,85 @@ func (EnterpriseClaimer) Claim(
 		switch {
 		// No eligible prebuilds found
 		case errors.Is(err, sql.ErrNoRows):
+			if err := recordClaimMiss(ctx, store, now, presetID); err != nil {
+				return nil, err
+			}
 			return nil, prebuilds.ErrNoClaimablePrebuiltWorkspaces
 		default:
 			return nil, xerrors.Errorf("claim prebuild for user %q: %w", userID.String(), err)
 		}
 	}
 
+	//nolint:gocritic // Recording claim attempts is a system function.
+	_, err = store.InsertPrebuildClaimAttempt(dbauthz.AsSystemRestricted(ctx), database.InsertPrebuildClaimAttemptParams{
+		ID:          uuid.New(),
+		PresetID:    presetID,
+		WorkspaceID: uuid.NullUUID{UUID: result.ID, Valid: true},
+		Claimed:     true,
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("record prebuild claim: %w", err)
+	}
+
 	return &result.ID, nil
 }
 
+// claimMissFailureLookback is how far back failed prebuilds are considered
+// when explaining a claim miss. It matches the default reconciliation backoff
+// lookback.
+const claimMissFailureLookback = time.Hour
+
+// recordClaimMiss records that no prebuilt workspace of the preset could be
+// claimed, along with the reason the pool was empty. Presets without a
+// prebuild configuration are not recorded, as they never have a pool.
+func recordClaimMiss(ctx context.Context, store database.Store, now time.Time, presetID uuid.UUID) error {
+	//nolint:gocritic // Recording claim attempts is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	state, err := store.GetPresetPrebuildPoolState(ctx, database.GetPresetPrebuildPoolStateParams{
+		FailedSince: now.Add(-claimMissFailureLookback),
+		PresetID:    presetID,
+	})
+	if err != nil {
+		return xerrors.Errorf("get prebuild pool state: %w", err)
+	}
+	if !state.DesiredInstances.Valid {
+		return nil
+	}
+
+	_, err = store.InsertPrebuildClaimAttempt(ctx, database.InsertPrebuildClaimAttemptParams{
+		ID:       uuid.New(),
+		PresetID: presetID,
+		Claimed:  false,
+		MissReason: database.NullPrebuildClaimMissReason{
+			PrebuildClaimMissReason: ClaimMissReason(state),
+			Valid:                   true,
+		},
+		CreatedAt: now,
+	})
+	if err != nil {
+		return xerrors.Errorf("record prebuild claim miss: %w", err)
+	}
+	return nil
+}
+
+// ClaimMissReason explains why no prebuilt workspace could be claimed from a
+// pool in the given state:
+//   - failures: the preset is hard-limited or its prebuilds failed recently.
+//   - reconciliation_lag: prebuilt workspaces exist but are still being
+//     provisioned or their agents are not ready yet.
+//   - capacity: every prebuilt workspace was claimed and none are being
+//     provisioned, so demand exceeded the desired instances.
+func ClaimMissReason(state database.GetPresetPrebuildPoolStateRow) database.PrebuildClaimMissReason {
+	switch {
+	case state.PrebuildStatus == database.PrebuildStatusHardLimited || state.RecentlyFailed > 0:
+		return database.PrebuildClaimMissReasonFailures
+	case state.InProgress > 0 || state.Running > 0:
+		return database.PrebuildClaimMissReasonReconciliationLag
+	default:
+		return database.PrebuildClaimMissReasonCapacity
+	}
+}
+
 var _ prebuilds.Claimer = &EnterpriseClaimer{}
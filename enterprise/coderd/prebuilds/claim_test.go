@@ -416,6 +416,49 @@ func TestClaimPrebuild(t *testing.T) {
 	}
 }
 
+func TestClaimMissReason(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		state    database.GetPresetPrebuildPoolStateRow
+		expected database.PrebuildClaimMissReason
+	}{
+		{
+			name:     "empty pool",
+			state:    database.GetPresetPrebuildPoolStateRow{PrebuildStatus: database.PrebuildStatusHealthy},
+			expected: database.PrebuildClaimMissReasonCapacity,
+		},
+		{
+			name:     "prebuilds in progress",
+			state:    database.GetPresetPrebuildPoolStateRow{PrebuildStatus: database.PrebuildStatusHealthy, InProgress: 1},
+			expected: database.PrebuildClaimMissReasonReconciliationLag,
+		},
+		{
+			name:     "agents not ready",
+			state:    database.GetPresetPrebuildPoolStateRow{PrebuildStatus: database.PrebuildStatusHealthy, Running: 1},
+			expected: database.PrebuildClaimMissReasonReconciliationLag,
+		},
+		{
+			name:     "recent failures",
+			state:    database.GetPresetPrebuildPoolStateRow{PrebuildStatus: database.PrebuildStatusHealthy, InProgress: 1, RecentlyFailed: 1},
+			expected: database.PrebuildClaimMissReasonFailures,
+		},
+		{
+			name:     "hard limited",
+			state:    database.GetPresetPrebuildPoolStateRow{PrebuildStatus: database.PrebuildStatusHardLimited},
+			expected: database.PrebuildClaimMissReasonFailures,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, prebuilds.ClaimMissReason(tc.state))
+		})
+	}
+}
+
 func templateWithAgentAndPresetsWithPrebuilds(desiredInstances int32) *echo.Responses {
 	return &echo.Responses{
 		Parse: echo.ParseComplete,
@@ -30,6 +30,8 @@ const (
 	MetricPresetValidationFailedGauge = namespace + "preset_validation_failed"
 	MetricLastUpdatedGauge            = namespace + "metrics_last_updated"
 	MetricReconciliationPausedGauge   = namespace + "reconciliation_paused"
+	MetricClaimMissesCount            = namespace + "claim_misses_total"
+	MetricClaimTimeToReady            = namespace + "claim_time_to_ready_seconds"
 )
 
 var (
@@ -102,6 +104,19 @@ var (
 		[]string{},
 		nil,
 	)
+	claimMissesDesc = prometheus.NewDesc(
+		MetricClaimMissesCount,
+		"Total number of workspace creations with a preset selected for which no prebuilt workspace could be claimed, "+
+			"by the reason the pool was empty: reconciliation_lag, capacity or failures.",
+		[]string{"template_name", "preset_name", "organization_name", "reason"},
+		nil,
+	)
+	claimTimeToReadyDesc = prometheus.NewDesc(
+		MetricClaimTimeToReady,
+		"Time in seconds from a prebuilt workspace being claimed until all of its agents were ready.",
+		labels,
+		nil,
+	)
 	reconciliationPausedDesc = prometheus.NewDesc(
 		MetricReconciliationPausedGauge,
 		"Indicates whether prebuilds reconciliation is currently paused (1 = paused, 0 = not paused).",
@@ -160,6 +175,8 @@ func (*MetricsCollector) Describe(descCh chan<- *prometheus.Desc) {
 	descCh <- eligiblePrebuildsDesc
 	descCh <- presetHardLimitedDesc
 	descCh <- presetValidationFailedDesc
+	descCh <- claimMissesDesc
+	descCh <- claimTimeToReadyDesc
 	descCh <- lastUpdateDesc
 	descCh <- reconciliationPausedDesc
 }
@@ -190,6 +207,13 @@ func (mc *MetricsCollector) Collect(metricsCh chan<- prometheus.Metric) {
 		metricsCh <- prometheus.MustNewConstMetric(claimedPrebuildsDesc, prometheus.CounterValue, float64(metric.ClaimedCount), metric.TemplateName, metric.PresetName, metric.OrganizationName)
 	}
 
+	for key, claims := range currentState.claimInsights {
+		metricsCh <- prometheus.MustNewConstMetric(claimMissesDesc, prometheus.CounterValue, float64(claims.missedReconciliationLag), key.templateName, key.presetName, key.orgName, string(database.PrebuildClaimMissReasonReconciliationLag))
+		metricsCh <- prometheus.MustNewConstMetric(claimMissesDesc, prometheus.CounterValue, float64(claims.missedCapacity), key.templateName, key.presetName, key.orgName, string(database.PrebuildClaimMissReasonCapacity))
+		metricsCh <- prometheus.MustNewConstMetric(claimMissesDesc, prometheus.CounterValue, float64(claims.missedFailures), key.templateName, key.presetName, key.orgName, string(database.PrebuildClaimMissReasonFailures))
+		metricsCh <- prometheus.MustNewConstSummary(claimTimeToReadyDesc, uint64(claims.readyCount), claims.timeToReadySumSeconds, nil, key.templateName, key.presetName, key.orgName) // nolint:gosec // Count is never negative.
+	}
+
 	mc.replacementsCounterMu.Lock()
 	for key, val := range mc.replacementsCounter {
 		metricsCh <- prometheus.MustNewConstMetric(resourceReplacementsDesc, prometheus.CounterValue, val, key.templateName, key.presetName, key.orgName)
@@ -244,10 +268,40 @@ func (mc *MetricsCollector) Collect(metricsCh chan<- prometheus.Metric) {
 
 type metricsState struct {
 	prebuildMetrics []database.GetPrebuildMetricsRow
+	claimInsights   map[claimInsightsKey]claimInsights
 	snapshot        *prebuilds.GlobalSnapshot
 	createdAt       time.Time
 }
 
+type claimInsightsKey struct {
+	orgName, templateName, presetName string
+}
+
+type claimInsights struct {
+	missedReconciliationLag int64
+	missedCapacity          int64
+	missedFailures          int64
+	readyCount              int64
+	timeToReadySumSeconds   float64
+}
+
+// aggregateClaimInsights sums the claim insights of presets sharing the same
+// name across template versions, as they share the same metric labels.
+func aggregateClaimInsights(rows []database.GetPrebuildClaimInsightsRow) map[claimInsightsKey]claimInsights {
+	aggregated := make(map[claimInsightsKey]claimInsights, len(rows))
+	for _, row := range rows {
+		key := claimInsightsKey{orgName: row.OrganizationName, templateName: row.TemplateName, presetName: row.PresetName}
+		claims := aggregated[key]
+		claims.missedReconciliationLag += row.MissedReconciliationLag
+		claims.missedCapacity += row.MissedCapacity
+		claims.missedFailures += row.MissedFailures
+		claims.readyCount += row.ReadyCount
+		claims.timeToReadySumSeconds += row.TimeToReadySumSeconds
+		aggregated[key] = claims
+	}
+	return aggregated
+}
+
 // BackgroundFetch updates the metrics state every given interval.
 func (mc *MetricsCollector) BackgroundFetch(ctx context.Context, updateInterval, updateTimeout time.Duration) {
 	tick := time.NewTicker(time.Nanosecond)
@@ -279,6 +333,14 @@ func (mc *MetricsCollector) UpdateState(ctx context.Context, timeout time.Durati
 		return xerrors.Errorf("fetch prebuild metrics: %w", err)
 	}
 
+	claimInsightsRows, err := mc.database.GetPrebuildClaimInsights(fetchCtx, database.GetPrebuildClaimInsightsParams{
+		StartTime: time.Time{},
+		EndTime:   dbtime.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("fetch prebuild claim insights: %w", err)
+	}
+
 	snapshot, err := mc.snapshotter.SnapshotState(fetchCtx, mc.database)
 	if err != nil {
 		return xerrors.Errorf("snapshot state: %w", err)
@@ -287,6 +349,7 @@ func (mc *MetricsCollector) UpdateState(ctx context.Context, timeout time.Durati
 
 	mc.latestState.Store(&metricsState{
 		prebuildMetrics: prebuildMetrics,
+		claimInsights:   aggregateClaimInsights(claimInsightsRows),
 		snapshot:        snapshot,
 		createdAt:       dbtime.Now(),
 	})
@@ -1,12 +1,14 @@
 package coderd
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -25,6 +27,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/httpmw/loggermw"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/provisionerkey"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/telemetry"
@@ -34,6 +37,7 @@ import (
 	"github.com/coder/coder/v2/codersdk/drpcsdk"
 	"github.com/coder/coder/v2/provisionerd/proto"
 	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/quartz"
 	"github.com/coder/websocket"
 )
 
@@ -54,6 +58,9 @@ type provisiionerDaemonAuthResponse struct {
 	keyID uuid.UUID
 	orgID uuid.UUID
 	tags  map[string]string
+	// hashedSecret is the secret of the provisioner key the daemon
+	// authenticated with. It's nil for other authentication methods.
+	hashedSecret []byte
 }
 
 type provisionerDaemonAuth struct {
@@ -86,9 +93,10 @@ func (p *provisionerDaemonAuth) authorize(r *http.Request, org database.Organiza
 		// Use the provisioner key tags here.
 		tags = provisionersdk.MutateTags(uuid.Nil, pk.Tags)
 		return provisiionerDaemonAuthResponse{
-			keyID: pk.ID,
-			orgID: pk.OrganizationID,
-			tags:  tags,
+			keyID:        pk.ID,
+			orgID:        pk.OrganizationID,
+			tags:         tags,
+			hashedSecret: provisionerkey.HashSecret(r.Header.Get(codersdk.ProvisionerDaemonKey)),
 		}, nil
 	}
 
@@ -223,6 +231,24 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	org := httpmw.OrganizationParam(r)
+	if org.ID != authRes.orgID {
+		//nolint:gocritic // Provisioner key auth has no actor to fetch the key's organization with.
+		org, err = api.Database.GetOrganizationByID(dbauthz.AsSystemRestricted(ctx), authRes.orgID)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+	if err := api.checkProvisionerDaemonLimits(ctx, org, name, tags); err != nil {
+		api.Logger.Warn(ctx, "provisioner daemon exceeds organization limits", slog.F("tags", tags), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Provisioner daemon is not allowed to register in this organization.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	provisioners := make([]database.ProvisionerType, 0, len(provisionersMap))
 	for p := range provisionersMap {
 		switch p {
@@ -331,6 +357,12 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 	srvCtx, srvCancel := context.WithCancel(ctx)
 	defer srvCancel()
 	logger.Info(ctx, "starting external provisioner daemon")
+	// Daemons connected with a provisioner key are drained once the key is
+	// revoked, or once the grace period of its rotation ends.
+	var drain <-chan struct{}
+	if authRes.hashedSecret != nil {
+		drain = api.watchProvisionerKey(srvCtx, logger, authRes.keyID, authRes.hashedSecret)
+	}
 	srv, err := provisionerdserver.NewServer(
 		srvCtx,
 		daemon.APIVersion,
@@ -356,6 +388,8 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 			OIDCConfig:          api.OIDCConfig,
 			AISeatTracker:       api.AGPL.AISeatTracker,
 			Clock:               api.Clock,
+			BuildTraces:         api.AGPL.BuildTraces,
+			Drain:               drain,
 		},
 		api.NotificationsEnqueuer,
 		&api.AGPL.PrebuildsReconciler,
@@ -389,8 +423,25 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		rl.WriteLog(ctx, http.StatusAccepted)
 	}
 
-	err = server.Serve(ctx, session)
+	serveCtx, serveCancel := context.WithCancel(ctx)
+	defer serveCancel()
+	var drained atomic.Bool
+	if drain != nil {
+		go func() {
+			if api.awaitProvisionerDaemonDrained(srvCtx, logger, drain, daemon.ID) {
+				drained.Store(true)
+				serveCancel()
+			}
+		}()
+	}
+
+	err = server.Serve(serveCtx, session)
 	srvCancel()
+	if drained.Load() {
+		logger.Info(ctx, "provisioner daemon drained")
+		_ = conn.Close(websocket.StatusGoingAway, "provisioner key is no longer valid")
+		return
+	}
 	logger.Info(ctx, "provisioner daemon disconnected", slog.Error(err))
 	if err != nil && !xerrors.Is(err, io.EOF) {
 		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("serve: %s", err))
@@ -398,3 +449,103 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 	}
 	_ = conn.Close(websocket.StatusGoingAway, "")
 }
+
+// provisionerDaemonDrainInterval is how often a draining provisioner daemon is
+// checked for running jobs.
+const provisionerDaemonDrainInterval = 5 * time.Second
+
+// watchProvisionerKey returns a channel that is closed once the secret of the
+// provisioner key with the given ID stops being valid, because the key was
+// deleted, or rotated and the grace period of the rotation ended.
+func (api *API) watchProvisionerKey(ctx context.Context, logger slog.Logger, keyID uuid.UUID, hashedSecret []byte) <-chan struct{} {
+	invalid := make(chan struct{})
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	cancel, err := api.Pubsub.Subscribe(provisionerkey.EventChannel(keyID), func(context.Context, []byte) {
+		notify()
+	})
+	if err != nil {
+		logger.Warn(ctx, "failed to subscribe to provisioner key events", slog.Error(err))
+		return invalid
+	}
+
+	go func() {
+		defer cancel()
+		var expiry *quartz.Timer
+		defer func() {
+			if expiry != nil {
+				expiry.Stop()
+			}
+		}()
+		for {
+			// nolint:gocritic // System must check whether the provisioner key is still valid.
+			pk, err := api.Database.GetProvisionerKeyByID(dbauthz.AsSystemRestricted(ctx), keyID)
+			switch {
+			case httpapi.Is404Error(err):
+				close(invalid)
+				return
+			case err != nil:
+				if ctx.Err() != nil {
+					return
+				}
+				logger.Warn(ctx, "failed to get provisioner key", slog.Error(err))
+			case !provisionerkey.ValidSecret(pk, hashedSecret, api.Clock.Now()):
+				close(invalid)
+				return
+			case !bytes.Equal(pk.HashedSecret, hashedSecret) && expiry == nil:
+				// The daemon is connected with the previous secret of the
+				// key, so check it again once the grace period ends.
+				expiry = api.Clock.AfterFunc(api.Clock.Until(pk.PreviousSecretExpiresAt.Time), notify, "provisionerKey", "expiry")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			}
+		}
+	}()
+	return invalid
+}
+
+// awaitProvisionerDaemonDrained returns true once drain is closed and the
+// provisioner daemon completed the jobs it's running, or false if ctx is
+// canceled first.
+func (api *API) awaitProvisionerDaemonDrained(ctx context.Context, logger slog.Logger, drain <-chan struct{}, daemonID uuid.UUID) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-drain:
+	}
+	logger.Info(ctx, "provisioner key is no longer valid, draining provisioner daemon")
+
+	// Jobs are counted after the first tick, so that a job the daemon was
+	// acquiring when the drain started is counted as well.
+	ticker := api.Clock.NewTicker(provisionerDaemonDrainInterval, "provisionerDaemon", "drain")
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+
+		// nolint:gocritic // System must check for the running jobs of the daemon.
+		running, err := api.Database.CountRunningProvisionerJobsByWorkerID(dbauthz.AsSystemRestricted(ctx), daemonID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return false
+			}
+			logger.Warn(ctx, "failed to count running jobs of provisioner daemon", slog.Error(err))
+			continue
+		}
+		if running == 0 {
+			return true
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+var provisionerTagNamespaceRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,62}$`)
+
+// @Summary Get provisioner daemon settings for organization
+// @ID get-provisioner-daemon-settings-for-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.ProvisionerDaemonSettings
+// @Router /api/v2/organizations/{organization}/settings/provisioner-daemons [get]
+func (api *API) provisionerDaemonSettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceProvisionerDaemon.InOrg(org.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	settings, err := api.convertProvisionerDaemonSettings(ctx, org)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, settings)
+}
+
+// @Summary Update provisioner daemon settings for organization
+// @ID update-provisioner-daemon-settings-for-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Accept json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.UpdateProvisionerDaemonSettingsRequest true "Provisioner daemon settings"
+// @Success 200 {object} codersdk.ProvisionerDaemonSettings
+// @Router /api/v2/organizations/{organization}/settings/provisioner-daemons [patch]
+func (api *API) patchProvisionerDaemonSettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	auditor := *api.AGPL.Auditor.Load()
+	aReq, commitAudit := audit.InitRequest[database.Organization](rw, &audit.RequestParams{
+		Audit:          auditor,
+		Log:            api.Logger,
+		Request:        r,
+		Action:         database.AuditActionWrite,
+		OrganizationID: org.ID,
+	})
+	aReq.Old = org
+	defer commitAudit()
+
+	// The limits protect organizations from each other, so organization
+	// admins must not be able to lift them on their own.
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateProvisionerDaemonSettingsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.TagNamespace != "" && !provisionerTagNamespaceRegex.MatchString(req.TagNamespace) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid provisioner tag namespace.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "tag_namespace",
+				Detail: fmt.Sprintf("%q must match %s", req.TagNamespace, provisionerTagNamespaceRegex.String()),
+			}},
+		})
+		return
+	}
+
+	org, err := api.Database.UpdateOrganizationProvisionerDaemonSettings(ctx, database.UpdateOrganizationProvisionerDaemonSettingsParams{
+		ID:                      org.ID,
+		ProvisionerTagNamespace: req.TagNamespace,
+		ProvisionerDaemonQuota:  req.DaemonQuota,
+		UpdatedAt:               dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating provisioner daemon settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = org
+
+	settings, err := api.convertProvisionerDaemonSettings(ctx, org)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, settings)
+}
+
+func (api *API) convertProvisionerDaemonSettings(ctx context.Context, org database.Organization) (codersdk.ProvisionerDaemonSettings, error) {
+	//nolint:gocritic // Counting the daemons of the organization is a system function.
+	daemons, err := api.Database.GetProvisionerDaemonsByOrganization(dbauthz.AsSystemRestricted(ctx), database.GetProvisionerDaemonsByOrganizationParams{
+		OrganizationID: org.ID,
+	})
+	if err != nil {
+		return codersdk.ProvisionerDaemonSettings{}, xerrors.Errorf("get provisioner daemons: %w", err)
+	}
+	return codersdk.ProvisionerDaemonSettings{
+		TagNamespace:     org.ProvisionerTagNamespace,
+		DaemonQuota:      org.ProvisionerDaemonQuota,
+		ConnectedDaemons: countConnectedProvisionerDaemons(daemons, dbtime.Now(), ""),
+	}, nil
+}
+
+// checkProvisionerDaemonLimits verifies that a daemon registering with the
+// given name and tags stays within the namespace and quota of the
+// organization.
+func (api *API) checkProvisionerDaemonLimits(ctx context.Context, org database.Organization, name string, tags map[string]string) error {
+	if org.ProvisionerTagNamespace != "" {
+		prefix := org.ProvisionerTagNamespace + "/"
+		for key := range tags {
+			if key == provisionersdk.TagScope || key == provisionersdk.TagOwner {
+				continue
+			}
+			if !strings.HasPrefix(key, prefix) {
+				return xerrors.Errorf("tag %q is outside of the organization's tag namespace %q", key, org.ProvisionerTagNamespace)
+			}
+		}
+	}
+
+	if org.ProvisionerDaemonQuota > 0 {
+		//nolint:gocritic // Counting the daemons of the organization is a system function.
+		daemons, err := api.Database.GetProvisionerDaemonsByOrganization(dbauthz.AsSystemRestricted(ctx), database.GetProvisionerDaemonsByOrganizationParams{
+			OrganizationID: org.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("get provisioner daemons: %w", err)
+		}
+		// A daemon reconnecting under the same name replaces its previous
+		// registration, so it does not count against the quota.
+		if countConnectedProvisionerDaemons(daemons, dbtime.Now(), name) >= int(org.ProvisionerDaemonQuota) {
+			return xerrors.Errorf("the organization has reached its quota of %d provisioner daemons", org.ProvisionerDaemonQuota)
+		}
+	}
+	return nil
+}
+
+// countConnectedProvisionerDaemons counts the daemons that have been seen
+// within the stale interval, ignoring the daemon named exclude.
+func countConnectedProvisionerDaemons(daemons []database.ProvisionerDaemon, now time.Time, exclude string) int {
+	var count int
+	for _, daemon := range daemons {
+		if exclude != "" && daemon.Name == exclude {
+			continue
+		}
+		if !daemon.LastSeenAt.Valid || now.Sub(daemon.LastSeenAt.Time) > provisionerdserver.StaleInterval {
+			continue
+		}
+		count++
+	}
+	return count
+}
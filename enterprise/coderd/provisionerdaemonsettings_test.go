@@ -0,0 +1,86 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestProvisionerDaemonSettings(t *testing.T) {
+	t.Parallel()
+
+	client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureExternalProvisionerDaemons: 1,
+		},
+	}})
+	orgAdmin, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.ScopedRoleOrgAdmin(user.OrganizationID))
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	settings, err := orgAdmin.ProvisionerDaemonSettings(ctx, user.OrganizationID)
+	require.NoError(t, err)
+	require.Empty(t, settings.TagNamespace)
+	require.Zero(t, settings.DaemonQuota)
+
+	// Organization admins cannot lift their own limits.
+	_, err = orgAdmin.PatchProvisionerDaemonSettings(ctx, user.OrganizationID, codersdk.UpdateProvisionerDaemonSettingsRequest{
+		DaemonQuota: 10,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	_, err = client.PatchProvisionerDaemonSettings(ctx, user.OrganizationID, codersdk.UpdateProvisionerDaemonSettingsRequest{
+		TagNamespace: "not/valid",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	settings, err = client.PatchProvisionerDaemonSettings(ctx, user.OrganizationID, codersdk.UpdateProvisionerDaemonSettingsRequest{
+		TagNamespace: "acme",
+		DaemonQuota:  1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "acme", settings.TagNamespace)
+	require.EqualValues(t, 1, settings.DaemonQuota)
+
+	serve := func(name string, tags map[string]string) error {
+		tags[provisionersdk.TagScope] = provisionersdk.ScopeOrganization
+		_, err := orgAdmin.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			Name:         name,
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{codersdk.ProvisionerTypeEcho},
+			Tags:         tags,
+		})
+		return err
+	}
+
+	// Tags outside of the namespace are rejected.
+	err = serve("first", map[string]string{"env": "prod"})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = serve("first", map[string]string{"acme/env": "prod"})
+	require.NoError(t, err)
+
+	// The quota is exhausted for new daemons, but the registered daemon can
+	// reconnect.
+	err = serve("second", map[string]string{"acme/env": "prod"})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	err = serve("first", map[string]string{"acme/env": "prod"})
+	require.NoError(t, err)
+
+	settings, err = orgAdmin.ProvisionerDaemonSettings(ctx, user.OrganizationID)
+	require.NoError(t, err)
+	require.Equal(t, 1, settings.ConnectedDaemons)
+}
@@ -1,12 +1,16 @@
 package coderd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
+	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -210,10 +214,89 @@ func (api *API) deleteProvisionerKey(rw http.ResponseWriter, r *http.Request) {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
+	api.publishProvisionerKeyEvent(ctx, provisionerKey.ID)
 
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// maxProvisionerKeyGracePeriod bounds how long the previous key of a rotated
+// provisioner key stays valid.
+const maxProvisionerKeyGracePeriod = 7 * 24 * time.Hour
+
+// @Summary Rotate provisioner key
+// @Description Daemons connected with the previous key keep running until it expires, and
+// @Description are then drained: they finish the jobs they're running and are disconnected.
+// @ID rotate-provisioner-key
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID"
+// @Param provisionerkey path string true "Provisioner key name"
+// @Param request body codersdk.RotateProvisionerKeyRequest true "Rotate provisioner key request"
+// @Success 200 {object} codersdk.RotateProvisionerKeyResponse
+// @Router /api/v2/organizations/{organization}/provisionerkeys/{provisionerkey}/rotate [post]
+func (api *API) rotateProvisionerKey(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	provisionerKey := httpmw.ProvisionerKeyParam(r)
+
+	var req codersdk.RotateProvisionerKeyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if provisionerKey.ID.String() == codersdk.ProvisionerKeyIDBuiltIn ||
+		provisionerKey.ID.String() == codersdk.ProvisionerKeyIDUserAuth ||
+		provisionerKey.ID.String() == codersdk.ProvisionerKeyIDPSK {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Cannot rotate reserved '%s' provisioner key", provisionerKey.Name),
+		})
+		return
+	}
+
+	gracePeriod := time.Duration(req.GracePeriodMillis) * time.Millisecond
+	if gracePeriod < 0 || gracePeriod > maxProvisionerKeyGracePeriod {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Grace period must be between 0 and 7 days",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "grace_period_ms",
+					Detail: "Grace period must be between 0 and 7 days",
+				},
+			},
+		})
+		return
+	}
+
+	params, token, err := provisionerkey.Rotate(provisionerKey.ID, gracePeriod)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	pk, err := api.Database.RotateProvisionerKey(ctx, params)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	api.publishProvisionerKeyEvent(ctx, pk.ID)
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.RotateProvisionerKeyResponse{
+		Key:                  token,
+		PreviousKeyExpiresAt: pk.PreviousSecretExpiresAt.Time,
+	})
+}
+
+// publishProvisionerKeyEvent notifies the daemons connected with a provisioner
+// key that it changed, so that they are drained if the secret they're
+// connected with is no longer valid.
+func (api *API) publishProvisionerKeyEvent(ctx context.Context, id uuid.UUID) {
+	err := api.Pubsub.Publish(provisionerkey.EventChannel(id), []byte(id.String()))
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to publish provisioner key event", slog.F("provisioner_key_id", id), slog.Error(err))
+	}
+}
+
 // @Summary Fetch provisioner key details
 // @ID fetch-provisioner-key-details
 // @Security CoderProvisionerKey
@@ -238,7 +321,7 @@ func (*API) fetchProvisionerKey(rw http.ResponseWriter, r *http.Request) {
 }
 
 func convertProvisionerKey(dbKey database.ProvisionerKey) codersdk.ProvisionerKey {
-	return codersdk.ProvisionerKey{
+	key := codersdk.ProvisionerKey{
 		ID:             dbKey.ID,
 		CreatedAt:      dbKey.CreatedAt,
 		OrganizationID: dbKey.OrganizationID,
@@ -246,6 +329,14 @@ func convertProvisionerKey(dbKey database.ProvisionerKey) codersdk.ProvisionerKe
 		Tags:           codersdk.ProvisionerKeyTags(dbKey.Tags),
 		// HashedSecret - never include the access token in the API response
 	}
+	if dbKey.LastUsedAt.Valid {
+		key.LastUsedAt = &dbKey.LastUsedAt.Time
+	}
+	// The previous key is only reported while it's still valid.
+	if dbKey.PreviousSecretExpiresAt.Valid && time.Now().Before(dbKey.PreviousSecretExpiresAt.Time) {
+		key.PreviousKeyExpiresAt = &dbKey.PreviousSecretExpiresAt.Time
+	}
+	return key
 }
 
 func convertProvisionerKeys(dbKeys []database.ProvisionerKey) []codersdk.ProvisionerKey {
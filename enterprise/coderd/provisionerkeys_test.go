@@ -3,6 +3,7 @@ package coderd_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -266,3 +267,92 @@ func TestGetProvisionerKey(t *testing.T) {
 		require.Empty(t, fetchedKey)
 	})
 }
+
+func TestRotateProvisionerKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		},
+	})
+
+	//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+	key, err := client.CreateProvisionerKey(ctx, owner.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+		Name: "my-test-key",
+	})
+	require.NoError(t, err)
+
+	keys, err := client.ListProvisionerKeys(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Nil(t, keys[0].LastUsedAt)
+	require.Nil(t, keys[0].PreviousKeyExpiresAt)
+
+	// Using the key records when it was last used.
+	_, err = client.GetProvisionerKey(ctx, key.Key)
+	require.NoError(t, err)
+	keys, err = client.ListProvisionerKeys(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.NotNil(t, keys[0].LastUsedAt)
+
+	// The previous key stays valid during the grace period.
+	//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+	rotated, err := client.RotateProvisionerKey(ctx, owner.OrganizationID, "my-test-key", codersdk.RotateProvisionerKeyRequest{
+		GracePeriodMillis: time.Hour.Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, key.Key, rotated.Key)
+	require.WithinDuration(t, time.Now().Add(time.Hour), rotated.PreviousKeyExpiresAt, time.Minute)
+
+	_, err = client.GetProvisionerKey(ctx, key.Key)
+	require.NoError(t, err)
+	_, err = client.GetProvisionerKey(ctx, rotated.Key)
+	require.NoError(t, err)
+
+	keys, err = client.ListProvisionerKeys(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.NotNil(t, keys[0].PreviousKeyExpiresAt)
+	require.WithinDuration(t, rotated.PreviousKeyExpiresAt, *keys[0].PreviousKeyExpiresAt, time.Second)
+
+	// Without a grace period, the previous key is invalidated immediately.
+	//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+	rotatedAgain, err := client.RotateProvisionerKey(ctx, owner.OrganizationID, "my-test-key", codersdk.RotateProvisionerKeyRequest{})
+	require.NoError(t, err)
+
+	_, err = client.GetProvisionerKey(ctx, rotated.Key)
+	require.ErrorContains(t, err, "provisioner daemon key invalid")
+	_, err = client.GetProvisionerKey(ctx, key.Key)
+	require.ErrorContains(t, err, "provisioner daemon key invalid")
+	_, err = client.GetProvisionerKey(ctx, rotatedAgain.Key)
+	require.NoError(t, err)
+
+	keys, err = client.ListProvisionerKeys(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.Nil(t, keys[0].PreviousKeyExpiresAt)
+
+	// The grace period is limited to a week.
+	//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+	_, err = client.RotateProvisionerKey(ctx, owner.OrganizationID, "my-test-key", codersdk.RotateProvisionerKeyRequest{
+		GracePeriodMillis: (8 * 24 * time.Hour).Milliseconds(),
+	})
+	require.ErrorContains(t, err, "Grace period")
+	//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+	_, err = client.RotateProvisionerKey(ctx, owner.OrganizationID, "my-test-key", codersdk.RotateProvisionerKeyRequest{
+		GracePeriodMillis: -1,
+	})
+	require.ErrorContains(t, err, "Grace period")
+
+	// Reserved provisioner keys cannot be rotated.
+	//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+	_, err = client.RotateProvisionerKey(ctx, owner.OrganizationID, codersdk.ProvisionerKeyNameBuiltIn, codersdk.RotateProvisionerKeyRequest{})
+	require.ErrorContains(t, err, "reserved")
+
+	// Keys that don't exist cannot be rotated.
+	//nolint:gocritic // ignore This client is operating as the owner user, which has unrestricted permissions
+	_, err = client.RotateProvisionerKey(ctx, owner.OrganizationID, "key", codersdk.RotateProvisionerKeyRequest{})
+	require.ErrorContains(t, err, "Resource not found")
+}
@@ -3,6 +3,7 @@ package schedule
 import (
 	"context"
 	"database/sql"
+	"slices"
 	"sync/atomic"
 	"time"
 
@@ -84,11 +85,13 @@ func (*EnterpriseTemplateScheduleStore) Get(ctx context.Context, db database.Sto
 	}
 
 	return agpl.TemplateScheduleOptions{
-		UserAutostartEnabled:  tpl.AllowUserAutostart,
-		UserAutostopEnabled:   tpl.AllowUserAutostop,
-		DefaultTTL:            time.Duration(tpl.DefaultTTL),
-		ActivityBump:          time.Duration(tpl.ActivityBump),
-		TimeTilAutostopNotify: time.Duration(tpl.TimeTilAutostopNotify),
+		UserAutostartEnabled:        tpl.AllowUserAutostart,
+		UserAutostopEnabled:         tpl.AllowUserAutostop,
+		DefaultTTL:                  time.Duration(tpl.DefaultTTL),
+		ActivityBump:                time.Duration(tpl.ActivityBump),
+		ActivityBumpConnectionTypes: tpl.ActivityBumpConnectionTypes,
+		ActivityBumpMaxPerDay:       time.Duration(tpl.ActivityBumpMaxPerDay),
+		TimeTilAutostopNotify:       time.Duration(tpl.TimeTilAutostopNotify),
 		AutostopRequirement: agpl.TemplateAutostopRequirement{
 			// #nosec G115 - Safe conversion as we've verified tpl.AutostopRequirementDaysOfWeek is <= 255
 			DaysOfWeek: uint8(tpl.AutostopRequirementDaysOfWeek),
@@ -97,6 +100,17 @@ func (*EnterpriseTemplateScheduleStore) Get(ctx context.Context, db database.Sto
 		AutostartRequirement: agpl.TemplateAutostartRequirement{
 			DaysOfWeek: tpl.AutostartAllowedDays(),
 		},
+		NightlyStop: agpl.TemplateNightlyStop{
+			Time: tpl.NightlyStopTime,
+		},
+		MaxLifetime: agpl.TemplateMaxLifetime{
+			Duration: time.Duration(tpl.MaxLifetime),
+			Action:   tpl.MaxLifetimeAction,
+		},
+		IdleReclaim: agpl.TemplateIdleReclaim{
+			TTL:              time.Duration(tpl.IdleReclaimTTL),
+			ResourceSelector: tpl.IdleReclaimResourceSelector,
+		},
 		FailureTTL:               time.Duration(tpl.FailureTTL),
 		TimeTilDormant:           time.Duration(tpl.TimeTilDormant),
 		TimeTilDormantAutoDelete: time.Duration(tpl.TimeTilDormantAutoDelete),
@@ -114,13 +128,29 @@ func (s *EnterpriseTemplateScheduleStore) Set(ctx context.Context, db database.S
 	if tpl.AutostopRequirementWeeks <= 0 {
 		tpl.AutostopRequirementWeeks = 1
 	}
+	if opts.MaxLifetime.Action == "" {
+		opts.MaxLifetime.Action = database.MaxLifetimeActionDelete
+	}
+
+	// The column is not nullable, so an omitted list means all connection
+	// types bump the deadline.
+	if opts.ActivityBumpConnectionTypes == nil {
+		opts.ActivityBumpConnectionTypes = []string{}
+	}
 
 	if int64(opts.DefaultTTL) == tpl.DefaultTTL &&
 		int64(opts.ActivityBump) == tpl.ActivityBump &&
+		slices.Equal(opts.ActivityBumpConnectionTypes, tpl.ActivityBumpConnectionTypes) &&
+		int64(opts.ActivityBumpMaxPerDay) == tpl.ActivityBumpMaxPerDay &&
 		int64(opts.TimeTilAutostopNotify) == tpl.TimeTilAutostopNotify &&
 		int16(opts.AutostopRequirement.DaysOfWeek) == tpl.AutostopRequirementDaysOfWeek &&
 		opts.AutostartRequirement.DaysOfWeek == tpl.AutostartAllowedDays() &&
 		opts.AutostopRequirement.Weeks == tpl.AutostopRequirementWeeks &&
+		opts.NightlyStop.Time == tpl.NightlyStopTime &&
+		int64(opts.MaxLifetime.Duration) == tpl.MaxLifetime &&
+		opts.MaxLifetime.Action == tpl.MaxLifetimeAction &&
+		int64(opts.IdleReclaim.TTL) == tpl.IdleReclaimTTL &&
+		opts.IdleReclaim.ResourceSelector == tpl.IdleReclaimResourceSelector &&
 		int64(opts.FailureTTL) == tpl.FailureTTL &&
 		int64(opts.TimeTilDormant) == tpl.TimeTilDormant &&
 		int64(opts.TimeTilDormantAutoDelete) == tpl.TimeTilDormantAutoDelete &&
@@ -140,6 +170,21 @@ func (s *EnterpriseTemplateScheduleStore) Set(ctx context.Context, db database.S
 		return database.Template{}, xerrors.Errorf("verify autostart requirement: %w", err)
 	}
 
+	opts.NightlyStop.Time, err = agpl.NormalizeTemplateNightlyStopTime(opts.NightlyStop.Time)
+	if err != nil {
+		return database.Template{}, xerrors.Errorf("verify nightly stop: %w", err)
+	}
+
+	err = agpl.VerifyTemplateMaxLifetime(opts.MaxLifetime)
+	if err != nil {
+		return database.Template{}, xerrors.Errorf("verify max lifetime: %w", err)
+	}
+
+	err = agpl.VerifyTemplateIdleReclaim(opts.IdleReclaim)
+	if err != nil {
+		return database.Template{}, xerrors.Errorf("verify idle reclaim: %w", err)
+	}
+
 	var (
 		template                 database.Template
 		dormantWorkspacesUpdated []database.WorkspaceTable
@@ -155,15 +200,22 @@ func (s *EnterpriseTemplateScheduleStore) Set(ctx context.Context, db database.S
 			AllowUserAutostop:             opts.UserAutostopEnabled,
 			DefaultTTL:                    int64(opts.DefaultTTL),
 			ActivityBump:                  int64(opts.ActivityBump),
+			ActivityBumpConnectionTypes:   opts.ActivityBumpConnectionTypes,
+			ActivityBumpMaxPerDay:         int64(opts.ActivityBumpMaxPerDay),
 			TimeTilAutostopNotify:         int64(opts.TimeTilAutostopNotify),
 			AutostopRequirementDaysOfWeek: int16(opts.AutostopRequirement.DaysOfWeek),
 			AutostopRequirementWeeks:      opts.AutostopRequirement.Weeks,
 			// Database stores the inverse of the allowed days of the week.
 			// Make sure the 8th bit is always zeroed out, as there is no 8th day of the week.
-			AutostartBlockDaysOfWeek: int16(^opts.AutostartRequirement.DaysOfWeek & 0b01111111),
-			FailureTTL:               int64(opts.FailureTTL),
-			TimeTilDormant:           int64(opts.TimeTilDormant),
-			TimeTilDormantAutoDelete: int64(opts.TimeTilDormantAutoDelete),
+			AutostartBlockDaysOfWeek:    int16(^opts.AutostartRequirement.DaysOfWeek & 0b01111111),
+			FailureTTL:                  int64(opts.FailureTTL),
+			TimeTilDormant:              int64(opts.TimeTilDormant),
+			TimeTilDormantAutoDelete:    int64(opts.TimeTilDormantAutoDelete),
+			NightlyStopTime:             opts.NightlyStop.Time,
+			MaxLifetime:                 int64(opts.MaxLifetime.Duration),
+			MaxLifetimeAction:           opts.MaxLifetime.Action,
+			IdleReclaimTTL:              int64(opts.IdleReclaim.TTL),
+			IdleReclaimResourceSelector: opts.IdleReclaim.ResourceSelector,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template schedule: %w", err)
@@ -2,8 +2,10 @@ package coderd
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -14,6 +16,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac/acl"
@@ -244,12 +247,20 @@ func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Template managers can delegate access to the template, but only those
+	// who can delete it may grant or revoke the admin role.
+	canDeleteTemplate := api.Authorize(r, policy.ActionDelete, template)
+
+	var aclChanges []audit.ACLChange
 	err := api.Database.InTx(func(tx database.Store) error {
 		var err error
 		template, err = tx.GetTemplateByID(ctx, template.ID)
 		if err != nil {
 			return xerrors.Errorf("get template by ID: %w", err)
 		}
+		oldUserRoles := templateACLRoles(template.UserACL)
+		oldGroupRoles := templateACLRoles(template.GroupACL)
+		changesAdmins := changesTemplateAdmins(template, req)
 
 		for id, role := range req.UserPerms {
 			if role == codersdk.TemplateRoleDeleted {
@@ -275,24 +286,135 @@ func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			return xerrors.Errorf("update template ACL by ID: %w", err)
 		}
+		if changesAdmins && !canDeleteTemplate {
+			return errTemplateAdminChange
+		}
 		template, err = tx.GetTemplateByID(ctx, template.ID)
 		if err != nil {
 			return xerrors.Errorf("get updated template by ID: %w", err)
 		}
+		aclChanges = append(
+			audit.DiffACL(audit.ACLSubjectUser, oldUserRoles, templateACLRoles(template.UserACL)),
+			audit.DiffACL(audit.ACLSubjectGroup, oldGroupRoles, templateACLRoles(template.GroupACL))...,
+		)
 		return nil
 	}, nil)
+	if xerrors.Is(err, errTemplateAdminChange) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only template admins can grant or revoke the admin role.",
+		})
+		return
+	}
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
 
 	aReq.New = template
+	aReq.SetAdditionalFields(audit.ACLAdditionalFields{ACLChanges: aclChanges})
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "Successfully updated template ACL list.",
 	})
 }
 
+// templateACLChangesPageSize is the number of audit logs read per query when
+// collecting template ACL changes.
+const templateACLChangesPageSize = 500
+
+// @Summary Get template ACL changes
+// @Description Returns the changes to the template's access control list
+// @Description recorded in the audit log, newest first. Requires permission
+// @Description to read the audit log.
+// @ID get-template-acl-changes
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param template path string true "Template ID" format(uuid)
+// @Param since query string false "Only return changes after this time (RFC 3339). Defaults to 30 days ago." format(date-time)
+// @Param granted query bool false "Only return changes that granted access to a subject without access"
+// @Success 200 {array} codersdk.TemplateACLChange
+// @Router /api/v2/templates/{template}/acl/changes [get]
+func (api *API) templateACLChanges(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		qp       = r.URL.Query()
+	)
+
+	p := httpapi.NewQueryParamParser()
+	since := p.Time(qp, dbtime.Now().AddDate(0, 0, -30), "since", time.RFC3339)
+	granted := p.Boolean(qp, false, "granted")
+	p.ErrorExcessParams(qp)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	changes := []codersdk.TemplateACLChange{}
+	for offset := int32(0); ; offset += templateACLChangesPageSize {
+		rows, err := api.Database.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{
+			ResourceType: string(database.ResourceTypeTemplate),
+			ResourceID:   template.ID,
+			Action:       string(database.AuditActionWrite),
+			DateFrom:     since,
+			OffsetOpt:    offset,
+			LimitOpt:     templateACLChangesPageSize,
+		})
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		for _, row := range rows {
+			changes = append(changes, convertTemplateACLChanges(row, granted)...)
+		}
+		if len(rows) < templateACLChangesPageSize {
+			break
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, changes)
+}
+
+// convertTemplateACLChanges extracts the ACL changes recorded in the
+// additional fields of a template audit log. Audit logs of other template
+// updates have none.
+func convertTemplateACLChanges(row database.GetAuditLogsOffsetRow, grantedOnly bool) []codersdk.TemplateACLChange {
+	var fields audit.ACLAdditionalFields
+	if err := json.Unmarshal(row.AuditLog.AdditionalFields, &fields); err != nil {
+		return nil
+	}
+
+	changes := make([]codersdk.TemplateACLChange, 0, len(fields.ACLChanges))
+	for _, change := range fields.ACLChanges {
+		if grantedOnly && (change.OldRole != "" || change.NewRole == "") {
+			continue
+		}
+		subjectID, err := uuid.Parse(change.SubjectID)
+		if err != nil {
+			continue
+		}
+		changes = append(changes, codersdk.TemplateACLChange{
+			AuditLogID: row.AuditLog.ID,
+			Time:       row.AuditLog.Time,
+			Actor: codersdk.MinimalUser{
+				ID:        row.AuditLog.UserID,
+				Username:  row.UserUsername.String,
+				Name:      row.UserName.String,
+				AvatarURL: row.UserAvatarUrl.String,
+			},
+			SubjectType: codersdk.ACLSubjectType(change.SubjectType),
+			SubjectID:   subjectID,
+			OldRole:     codersdk.TemplateRole(change.OldRole),
+			NewRole:     codersdk.TemplateRole(change.NewRole),
+		})
+	}
+	return changes
+}
+
 type TemplateACLUpdateValidator codersdk.UpdateTemplateACL
 
 var (
@@ -333,10 +455,38 @@ func convertTemplateUsers(tus []database.TemplateUser, orgIDsByUserIDs map[uuid.
 	return users
 }
 
+// templateACLRoles maps the subjects of a template ACL to their role names.
+func templateACLRoles(acl database.TemplateACL) map[string]string {
+	roles := make(map[string]string, len(acl))
+	for id, actions := range acl {
+		roles[id] = string(convertToTemplateRole(actions))
+	}
+	return roles
+}
+
+var errTemplateAdminChange = xerrors.New("only template admins can grant or revoke the admin role")
+
+// changesTemplateAdmins reports whether the update grants the admin role to a
+// user or group, or changes the role of one that has it.
+func changesTemplateAdmins(template database.Template, req codersdk.UpdateTemplateACL) bool {
+	changes := func(acl database.TemplateACL, perms map[string]codersdk.TemplateRole) bool {
+		for id, role := range perms {
+			current := convertToTemplateRole(acl[id])
+			if role != current && (role == codersdk.TemplateRoleAdmin || current == codersdk.TemplateRoleAdmin) {
+				return true
+			}
+		}
+		return false
+	}
+	return changes(template.UserACL, req.UserPerms) || changes(template.GroupACL, req.GroupPerms)
+}
+
 func convertToTemplateRole(actions []policy.Action) codersdk.TemplateRole {
 	switch {
 	case slice.SameElements(actions, db2sdk.TemplateRoleActions(codersdk.TemplateRoleAdmin)):
 		return codersdk.TemplateRoleAdmin
+	case slice.SameElements(actions, db2sdk.TemplateRoleActions(codersdk.TemplateRoleManage)):
+		return codersdk.TemplateRoleManage
 	case slice.SameElements(actions, db2sdk.TemplateRoleActions(codersdk.TemplateRoleUse)):
 		return codersdk.TemplateRoleUse
 	}
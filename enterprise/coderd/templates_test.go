@@ -18,12 +18,15 @@ import (
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
+	entaudit "github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
 	"github.com/coder/coder/v2/enterprise/coderd/schedule"
@@ -126,6 +129,71 @@ func TestTemplates(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("DeprecatedWithCutoff", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAccessControl: 1,
+				},
+			},
+		})
+		client, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID, rbac.RoleTemplateAdmin())
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Before the cutoff, workspaces can still be created but carry a
+		// deprecation warning.
+		cutoff := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DeprecationMessage: ptr.Ref("Move to the new template"),
+			DeprecationCutoff:  &cutoff,
+		})
+		require.NoError(t, err)
+		assert.True(t, updated.Deprecated)
+		require.NotNil(t, updated.DeprecationCutoff)
+		assert.True(t, cutoff.Equal(*updated.DeprecationCutoff))
+
+		workspace, err := client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "before-cutoff",
+		})
+		require.NoError(t, err)
+		require.Len(t, workspace.LatestBuild.DeprecationWarnings, 1)
+		warning := workspace.LatestBuild.DeprecationWarnings[0]
+		assert.Equal(t, codersdk.DeprecationSubjectTemplate, warning.Subject)
+		assert.Equal(t, template.ID, warning.ID)
+		assert.Equal(t, "Move to the new template", warning.Message)
+		require.NotNil(t, warning.CutoffAt)
+		assert.True(t, cutoff.Equal(*warning.CutoffAt))
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, workspace.DeprecationWarnings, 1)
+
+		// Once the cutoff passed, creation is blocked.
+		past := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DeprecationMessage: ptr.Ref("Move to the new template"),
+			DeprecationCutoff:  &past,
+		})
+		require.NoError(t, err)
+
+		_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "after-cutoff",
+		})
+		require.ErrorContains(t, err, "deprecated")
+	})
+
 	t.Run("MaxPortShareLevel", func(t *testing.T) {
 		t.Parallel()
 
@@ -373,6 +441,151 @@ func TestTemplates(t *testing.T) {
 		require.False(t, template.Deprecated)
 	})
 
+	t.Run("SetNightlyStop", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAdvancedTemplateScheduling: 1,
+				},
+			},
+		})
+		anotherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateAdmin())
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Empty(t, template.NightlyStopTime)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		updated, err := anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			NightlyStopTime: ptr.Ref("0:00"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "00:00", updated.NightlyStopTime)
+
+		_, err = anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			NightlyStopTime: ptr.Ref("24:00"),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "nightly_stop_time", apiErr.Validations[0].Field)
+
+		updated, err = anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			NightlyStopTime: ptr.Ref(""),
+		})
+		require.NoError(t, err)
+		require.Empty(t, updated.NightlyStopTime)
+	})
+
+	t.Run("SetMaxLifetime", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAdvancedTemplateScheduling: 1,
+				},
+			},
+		})
+		anotherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateAdmin())
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.MaxLifetimeMillis)
+		require.Equal(t, codersdk.MaxLifetimeActionDelete, template.MaxLifetimeAction)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		maxLifetime := (30 * 24 * time.Hour).Milliseconds()
+		updated, err := anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxLifetimeMillis: ptr.Ref(maxLifetime),
+			MaxLifetimeAction: ptr.Ref(codersdk.MaxLifetimeActionStop),
+		})
+		require.NoError(t, err)
+		require.Equal(t, maxLifetime, updated.MaxLifetimeMillis)
+		require.Equal(t, codersdk.MaxLifetimeActionStop, updated.MaxLifetimeAction)
+
+		// Workspaces report when they reach the max lifetime.
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		require.NotNil(t, workspace.ExpiresAt)
+		require.WithinDuration(t, workspace.CreatedAt.Add(30*24*time.Hour), *workspace.ExpiresAt, time.Second)
+
+		_, err = anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxLifetimeMillis: ptr.Ref(time.Hour.Milliseconds()),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "max_lifetime_ms", apiErr.Validations[0].Field)
+
+		updated, err = anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxLifetimeMillis: ptr.Ref(int64(0)),
+		})
+		require.NoError(t, err)
+		require.Zero(t, updated.MaxLifetimeMillis)
+		require.Equal(t, codersdk.MaxLifetimeActionStop, updated.MaxLifetimeAction)
+	})
+
+	t.Run("SetIdleReclaim", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAdvancedTemplateScheduling: 1,
+				},
+			},
+		})
+		anotherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateAdmin())
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.IdleReclaimTTLMillis)
+		require.Empty(t, template.IdleReclaimResourceSelector)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		idleReclaim := (15 * time.Minute).Milliseconds()
+		updated, err := anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			IdleReclaimTTLMillis:        ptr.Ref(idleReclaim),
+			IdleReclaimResourceSelector: ptr.Ref("gpu=true"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, idleReclaim, updated.IdleReclaimTTLMillis)
+		require.Equal(t, "gpu=true", updated.IdleReclaimResourceSelector)
+
+		// A resource selector is required.
+		_, err = anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			IdleReclaimResourceSelector: ptr.Ref(""),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "idle_reclaim_resource_selector", apiErr.Validations[0].Field)
+
+		updated, err = anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			IdleReclaimTTLMillis: ptr.Ref(int64(0)),
+		})
+		require.NoError(t, err)
+		require.Zero(t, updated.IdleReclaimTTLMillis)
+		require.Equal(t, "gpu=true", updated.IdleReclaimResourceSelector)
+	})
+
 	t.Run("CleanupTTLs", func(t *testing.T) {
 		t.Run("OK", func(t *testing.T) {
 			t.Parallel()
@@ -1770,6 +1983,83 @@ func TestUpdateTemplateACL(t *testing.T) {
 		require.True(t, found, "user not found in acl")
 	})
 
+	t.Run("ManagerCanAdministerTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+
+		client1, user1 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, user2 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:gocritic // test setup
+		err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				user1.ID.String(): codersdk.TemplateRoleManage,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := client1.TemplateACL(ctx, template.ID)
+		require.NoError(t, err)
+		require.Contains(t, acl.Users, codersdk.TemplateUser{
+			User: user1,
+			Role: codersdk.TemplateRoleManage,
+		})
+
+		// Managers can change the template's settings...
+		updated, err := client1.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: ptr.Ref("Managed by a template manager"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Managed by a template manager", updated.Description)
+
+		// ...push template versions...
+		data, err := echo.Tar(nil)
+		require.NoError(t, err)
+		file, err := client1.Upload(ctx, codersdk.ContentTypeTar, bytes.NewReader(data))
+		require.NoError(t, err)
+		_, err = client1.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			Name:          "managed",
+			TemplateID:    template.ID,
+			FileID:        file.ID,
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.NoError(t, err)
+
+		// ...and delegate access to the template.
+		err = client1.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				user2.ID.String(): codersdk.TemplateRoleUse,
+			},
+		})
+		require.NoError(t, err)
+
+		// But they cannot grant the admin role, not even to themselves.
+		for _, id := range []uuid.UUID{user1.ID, user2.ID} {
+			err = client1.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+				UserPerms: map[string]codersdk.TemplateRole{
+					id.String(): codersdk.TemplateRoleAdmin,
+				},
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		}
+
+		// Nor can they delete the template.
+		err = client1.DeleteTemplate(ctx, template.ID)
+		require.Error(t, err)
+	})
+
 	t.Run("allUsersGroup", func(t *testing.T) {
 		t.Parallel()
 
@@ -1908,6 +2198,64 @@ func TestUpdateTemplateACL(t *testing.T) {
 	})
 }
 
+func TestTemplateACLChanges(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	auditor := entaudit.NewAuditor(db, entaudit.DefaultFilter, backends.NewPostgres(db, true))
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		AuditLogging: true,
+		Options: &coderdtest.Options{
+			Database: db,
+			Pubsub:   ps,
+			Auditor:  auditor,
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAuditLog:     1,
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		},
+	})
+	_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		UserPerms: map[string]codersdk.TemplateRole{user.ID.String(): codersdk.TemplateRoleUse},
+	})
+	require.NoError(t, err)
+	err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		UserPerms: map[string]codersdk.TemplateRole{user.ID.String(): codersdk.TemplateRoleAdmin},
+	})
+	require.NoError(t, err)
+
+	changes, err := client.TemplateACLChanges(ctx, template.ID, codersdk.TemplateACLChangesRequest{})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	// Newest first.
+	require.Equal(t, owner.UserID, changes[0].Actor.ID)
+	require.Equal(t, codersdk.ACLSubjectTypeUser, changes[0].SubjectType)
+	require.Equal(t, user.ID, changes[0].SubjectID)
+	require.Equal(t, codersdk.TemplateRoleUse, changes[0].OldRole)
+	require.Equal(t, codersdk.TemplateRoleAdmin, changes[0].NewRole)
+	require.Equal(t, codersdk.TemplateRoleDeleted, changes[1].OldRole)
+	require.Equal(t, codersdk.TemplateRoleUse, changes[1].NewRole)
+
+	// Only the change that granted access.
+	granted, err := client.TemplateACLChanges(ctx, template.ID, codersdk.TemplateACLChangesRequest{Granted: true})
+	require.NoError(t, err)
+	require.Len(t, granted, 1)
+	require.Equal(t, changes[1], granted[0])
+
+	// Changes before the window are omitted.
+	later, err := client.TemplateACLChanges(ctx, template.ID, codersdk.TemplateACLChangesRequest{Since: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Empty(t, later)
+}
+
 func TestReadFileWithTemplateUpdate(t *testing.T) {
 	t.Parallel()
 	t.Run("HasTemplateUpdate", func(t *testing.T) {
@@ -205,6 +205,67 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 		log.Debug(ctx, "encrypted user ai provider key", slog.F("user_ai_provider_key_id", key.ID), slog.F("ai_provider_id", key.AIProviderID), slog.F("user_id", key.UserID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	templateSecrets, err := cryptDB.GetTemplateSecrets(ctx)
+	if err != nil {
+		return xerrors.Errorf("get template secrets: %w", err)
+	}
+	log.Info(ctx, "encrypting template secrets", slog.F("secret_count", len(templateSecrets)))
+	for idx, secret := range templateSecrets {
+		if secret.ValueKeyID.Valid && secret.ValueKeyID.String == ciphers[0].HexDigest() {
+			log.Debug(ctx, "skipping template secret", slog.F("template_secret_id", secret.ID), slog.F("template_id", secret.TemplateID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+			continue
+		}
+		if _, err := cryptDB.UpdateEncryptedTemplateSecret(ctx, database.UpdateEncryptedTemplateSecretParams{
+			ID:         secret.ID,
+			Value:      secret.Value,
+			ValueKeyID: sql.NullString{}, // dbcrypt will update as required
+		}); err != nil {
+			return xerrors.Errorf("update template secret id=%s template_id=%s: %w", secret.ID, secret.TemplateID, err)
+		}
+		log.Debug(ctx, "encrypted template secret", slog.F("template_secret_id", secret.ID), slog.F("template_id", secret.TemplateID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+	}
+
+	buildParams, err := cryptDB.GetSensitiveWorkspaceBuildParameters(ctx)
+	if err != nil {
+		return xerrors.Errorf("get sensitive workspace build parameters: %w", err)
+	}
+	log.Info(ctx, "encrypting sensitive workspace build parameters", slog.F("parameter_count", len(buildParams)))
+	for idx, param := range buildParams {
+		if param.ValueKeyID.Valid && param.ValueKeyID.String == ciphers[0].HexDigest() {
+			log.Debug(ctx, "skipping workspace build parameter", slog.F("workspace_build_id", param.WorkspaceBuildID), slog.F("name", param.Name), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+			continue
+		}
+		if err := cryptDB.UpdateEncryptedWorkspaceBuildParameter(ctx, database.UpdateEncryptedWorkspaceBuildParameterParams{
+			WorkspaceBuildID: param.WorkspaceBuildID,
+			Name:             param.Name,
+			Value:            param.Value,
+			ValueKeyID:       sql.NullString{}, // dbcrypt will update as required
+		}); err != nil {
+			return xerrors.Errorf("update workspace build parameter workspace_build_id=%s name=%s: %w", param.WorkspaceBuildID, param.Name, err)
+		}
+		log.Debug(ctx, "encrypted workspace build parameter", slog.F("workspace_build_id", param.WorkspaceBuildID), slog.F("name", param.Name), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+	}
+
+	vcsEventMappings, err := cryptDB.GetVCSEventMappings(ctx)
+	if err != nil {
+		return xerrors.Errorf("get vcs event mappings: %w", err)
+	}
+	log.Info(ctx, "encrypting vcs event mapping signing secrets", slog.F("mapping_count", len(vcsEventMappings)))
+	for idx, mapping := range vcsEventMappings {
+		if mapping.SigningSecretKeyID.Valid && mapping.SigningSecretKeyID.String == ciphers[0].HexDigest() {
+			log.Debug(ctx, "skipping vcs event mapping", slog.F("vcs_event_mapping_id", mapping.ID), slog.F("template_id", mapping.TemplateID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+			continue
+		}
+		if _, err := cryptDB.UpdateEncryptedVCSEventMapping(ctx, database.UpdateEncryptedVCSEventMappingParams{
+			ID:                 mapping.ID,
+			SigningSecret:      mapping.SigningSecret,
+			SigningSecretKeyID: sql.NullString{}, // dbcrypt will update as required
+		}); err != nil {
+			return xerrors.Errorf("update vcs event mapping id=%s template_id=%s: %w", mapping.ID, mapping.TemplateID, err)
+		}
+		log.Debug(ctx, "encrypted vcs event mapping", slog.F("vcs_event_mapping_id", mapping.ID), slog.F("template_id", mapping.TemplateID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+	}
+
 	// Revoke old keys
 	for _, c := range ciphers[1:] {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -400,6 +461,67 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 		log.Debug(ctx, "decrypted user ai provider key", slog.F("user_ai_provider_key_id", key.ID), slog.F("ai_provider_id", key.AIProviderID), slog.F("user_id", key.UserID), slog.F("current", idx+1))
 	}
 
+	templateSecrets, err := cryptDB.GetTemplateSecrets(ctx)
+	if err != nil {
+		return xerrors.Errorf("get template secrets: %w", err)
+	}
+	log.Info(ctx, "decrypting template secrets", slog.F("secret_count", len(templateSecrets)))
+	for idx, secret := range templateSecrets {
+		if !secret.ValueKeyID.Valid {
+			log.Debug(ctx, "skipping template secret", slog.F("template_secret_id", secret.ID), slog.F("template_id", secret.TemplateID), slog.F("current", idx+1))
+			continue
+		}
+		if _, err := cryptDB.UpdateEncryptedTemplateSecret(ctx, database.UpdateEncryptedTemplateSecretParams{
+			ID:         secret.ID,
+			Value:      secret.Value,
+			ValueKeyID: sql.NullString{}, // explicitly clear the key id
+		}); err != nil {
+			return xerrors.Errorf("decrypt template secret id=%s template_id=%s: %w", secret.ID, secret.TemplateID, err)
+		}
+		log.Debug(ctx, "decrypted template secret", slog.F("template_secret_id", secret.ID), slog.F("template_id", secret.TemplateID), slog.F("current", idx+1))
+	}
+
+	buildParams, err := cryptDB.GetSensitiveWorkspaceBuildParameters(ctx)
+	if err != nil {
+		return xerrors.Errorf("get sensitive workspace build parameters: %w", err)
+	}
+	log.Info(ctx, "decrypting sensitive workspace build parameters", slog.F("parameter_count", len(buildParams)))
+	for idx, param := range buildParams {
+		if !param.ValueKeyID.Valid {
+			log.Debug(ctx, "skipping workspace build parameter", slog.F("workspace_build_id", param.WorkspaceBuildID), slog.F("name", param.Name), slog.F("current", idx+1))
+			continue
+		}
+		if err := cryptDB.UpdateEncryptedWorkspaceBuildParameter(ctx, database.UpdateEncryptedWorkspaceBuildParameterParams{
+			WorkspaceBuildID: param.WorkspaceBuildID,
+			Name:             param.Name,
+			Value:            param.Value,
+			ValueKeyID:       sql.NullString{}, // explicitly clear the key id
+		}); err != nil {
+			return xerrors.Errorf("decrypt workspace build parameter workspace_build_id=%s name=%s: %w", param.WorkspaceBuildID, param.Name, err)
+		}
+		log.Debug(ctx, "decrypted workspace build parameter", slog.F("workspace_build_id", param.WorkspaceBuildID), slog.F("name", param.Name), slog.F("current", idx+1))
+	}
+
+	vcsEventMappings, err := cryptDB.GetVCSEventMappings(ctx)
+	if err != nil {
+		return xerrors.Errorf("get vcs event mappings: %w", err)
+	}
+	log.Info(ctx, "decrypting vcs event mapping signing secrets", slog.F("mapping_count", len(vcsEventMappings)))
+	for idx, mapping := range vcsEventMappings {
+		if !mapping.SigningSecretKeyID.Valid {
+			log.Debug(ctx, "skipping vcs event mapping", slog.F("vcs_event_mapping_id", mapping.ID), slog.F("template_id", mapping.TemplateID), slog.F("current", idx+1))
+			continue
+		}
+		if _, err := cryptDB.UpdateEncryptedVCSEventMapping(ctx, database.UpdateEncryptedVCSEventMappingParams{
+			ID:                 mapping.ID,
+			SigningSecret:      mapping.SigningSecret,
+			SigningSecretKeyID: sql.NullString{}, // explicitly clear the key id
+		}); err != nil {
+			return xerrors.Errorf("decrypt vcs event mapping id=%s template_id=%s: %w", mapping.ID, mapping.TemplateID, err)
+		}
+		log.Debug(ctx, "decrypted vcs event mapping", slog.F("vcs_event_mapping_id", mapping.ID), slog.F("template_id", mapping.TemplateID), slog.F("current", idx+1))
+	}
+
 	// Revoke _all_ keys
 	for _, c := range ciphers {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -424,6 +546,10 @@ DELETE FROM user_ai_provider_keys
 	WHERE api_key_key_id IS NOT NULL;
 DELETE FROM user_secrets
 	WHERE value_key_id IS NOT NULL;
+DELETE FROM template_secrets
+	WHERE value_key_id IS NOT NULL;
+DELETE FROM vcs_event_mappings
+	WHERE signing_secret_key_id IS NOT NULL;
 -- gitsshkeys has no delete path in product code: rows are inserted on
 -- user creation and only ever mutated by regenerate. dbcrypt's 'delete'
 -- command is the one operation that needs to wipe encrypted content,
@@ -433,6 +559,12 @@ UPDATE gitsshkeys
 	SET private_key = '',
 		private_key_key_id = NULL
 	WHERE private_key_key_id IS NOT NULL;
+-- Workspace build parameters are cleared rather than deleted, so the
+-- workspace keeps its other parameter values.
+UPDATE workspace_build_parameters
+	SET value = '',
+		value_key_id = NULL
+	WHERE value_key_id IS NOT NULL;
 UPDATE ai_providers
 	SET settings = NULL,
 		settings_key_id = NULL
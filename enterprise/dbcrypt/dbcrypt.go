@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -966,6 +967,212 @@ func (db *dbCrypt) UpdateUserSecretByUserIDAndName(ctx context.Context, arg data
 	return secret, nil
 }
 
+func (db *dbCrypt) decryptTemplateSecrets(secrets []database.TemplateSecret) error {
+	for i := range secrets {
+		if err := db.decryptField(&secrets[i].Value, secrets[i].ValueKeyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *dbCrypt) InsertTemplateSecret(ctx context.Context, params database.InsertTemplateSecretParams) (database.TemplateSecret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	secret, err := db.Store.InsertTemplateSecret(ctx, params)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetTemplateSecretByID(ctx context.Context, id uuid.UUID) (database.TemplateSecret, error) {
+	secret, err := db.Store.GetTemplateSecretByID(ctx, id)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.GetTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	secret, err := db.Store.GetTemplateSecretByTemplateIDAndName(ctx, arg)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateSecret, error) {
+	secrets, err := db.Store.GetTemplateSecretsByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.decryptTemplateSecrets(secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func (db *dbCrypt) GetTemplateSecrets(ctx context.Context) ([]database.TemplateSecret, error) {
+	secrets, err := db.Store.GetTemplateSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.decryptTemplateSecrets(secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func (db *dbCrypt) UpdateTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.UpdateTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	if arg.UpdateValue {
+		if err := db.encryptField(&arg.Value, &arg.ValueKeyID); err != nil {
+			return database.TemplateSecret{}, err
+		}
+	}
+	secret, err := db.Store.UpdateTemplateSecretByTemplateIDAndName(ctx, arg)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+// UpdateEncryptedTemplateSecret re-encrypts the value of a secret, so that
+// dbcrypt key rotation can move every FK reference to a new key digest before
+// old keys are revoked.
+func (db *dbCrypt) UpdateEncryptedTemplateSecret(ctx context.Context, params database.UpdateEncryptedTemplateSecretParams) (database.TemplateSecret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	secret, err := db.Store.UpdateEncryptedTemplateSecret(ctx, params)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) DeleteTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.DeleteTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	secret, err := db.Store.DeleteTemplateSecretByTemplateIDAndName(ctx, arg)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) decryptVCSEventMappings(mappings []database.VCSEventMapping) error {
+	for i := range mappings {
+		if err := db.decryptField(&mappings[i].SigningSecret, mappings[i].SigningSecretKeyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *dbCrypt) InsertVCSEventMapping(ctx context.Context, params database.InsertVCSEventMappingParams) (database.VCSEventMapping, error) {
+	if err := db.encryptField(&params.SigningSecret, &params.SigningSecretKeyID); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	mapping, err := db.Store.InsertVCSEventMapping(ctx, params)
+	if err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	if err := db.decryptField(&mapping.SigningSecret, mapping.SigningSecretKeyID); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	return mapping, nil
+}
+
+func (db *dbCrypt) GetVCSEventMappingByID(ctx context.Context, id uuid.UUID) (database.VCSEventMapping, error) {
+	mapping, err := db.Store.GetVCSEventMappingByID(ctx, id)
+	if err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	if err := db.decryptField(&mapping.SigningSecret, mapping.SigningSecretKeyID); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	return mapping, nil
+}
+
+func (db *dbCrypt) GetVCSEventMappings(ctx context.Context) ([]database.VCSEventMapping, error) {
+	mappings, err := db.Store.GetVCSEventMappings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.decryptVCSEventMappings(mappings); err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+func (db *dbCrypt) GetVCSEventMappingsByRepository(ctx context.Context, repository string) ([]database.VCSEventMapping, error) {
+	mappings, err := db.Store.GetVCSEventMappingsByRepository(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.decryptVCSEventMappings(mappings); err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+func (db *dbCrypt) GetVCSEventMappingsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.VCSEventMapping, error) {
+	mappings, err := db.Store.GetVCSEventMappingsByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.decryptVCSEventMappings(mappings); err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// UpdateEncryptedVCSEventMapping re-encrypts the signing secret of a mapping,
+// so that dbcrypt key rotation can move every FK reference to a new key digest
+// before old keys are revoked.
+func (db *dbCrypt) UpdateEncryptedVCSEventMapping(ctx context.Context, params database.UpdateEncryptedVCSEventMappingParams) (database.VCSEventMapping, error) {
+	if err := db.encryptField(&params.SigningSecret, &params.SigningSecretKeyID); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	mapping, err := db.Store.UpdateEncryptedVCSEventMapping(ctx, params)
+	if err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	if err := db.decryptField(&mapping.SigningSecret, mapping.SigningSecretKeyID); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	return mapping, nil
+}
+
+func (db *dbCrypt) DeleteVCSEventMappingByTemplateIDAndID(ctx context.Context, arg database.DeleteVCSEventMappingByTemplateIDAndIDParams) (database.VCSEventMapping, error) {
+	mapping, err := db.Store.DeleteVCSEventMappingByTemplateIDAndID(ctx, arg)
+	if err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	if err := db.decryptField(&mapping.SigningSecret, mapping.SigningSecretKeyID); err != nil {
+		return database.VCSEventMapping{}, err
+	}
+	return mapping, nil
+}
+
 func (db *dbCrypt) InsertGitSSHKey(ctx context.Context, params database.InsertGitSSHKeyParams) (database.GitSSHKey, error) {
 	if err := db.encryptField(&params.PrivateKey, &params.PrivateKeyKeyID); err != nil {
 		return database.GitSSHKey{}, err
@@ -1005,6 +1212,63 @@ func (db *dbCrypt) UpdateGitSSHKey(ctx context.Context, params database.UpdateGi
 	return key, nil
 }
 
+func (db *dbCrypt) decryptWorkspaceBuildParameters(params []database.WorkspaceBuildParameter) error {
+	for i := range params {
+		if err := db.decryptField(&params[i].Value, params[i].ValueKeyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertWorkspaceBuildParameters encrypts the values of sensitive parameters.
+// Other values are inserted as they are.
+func (db *dbCrypt) InsertWorkspaceBuildParameters(ctx context.Context, params database.InsertWorkspaceBuildParametersParams) error {
+	// Don't modify the values of the caller.
+	params.Value = slices.Clone(params.Value)
+	params.ValueKeyID = make([]string, len(params.Value))
+	for i := range params.Value {
+		if i >= len(params.Sensitive) || !params.Sensitive[i] {
+			continue
+		}
+		var keyID sql.NullString
+		if err := db.encryptField(&params.Value[i], &keyID); err != nil {
+			return err
+		}
+		params.ValueKeyID[i] = keyID.String
+	}
+	return db.Store.InsertWorkspaceBuildParameters(ctx, params)
+}
+
+func (db *dbCrypt) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
+	params, err := db.Store.GetWorkspaceBuildParameters(ctx, workspaceBuildID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.decryptWorkspaceBuildParameters(params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func (db *dbCrypt) GetSensitiveWorkspaceBuildParameters(ctx context.Context) ([]database.WorkspaceBuildParameter, error) {
+	params, err := db.Store.GetSensitiveWorkspaceBuildParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.decryptWorkspaceBuildParameters(params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func (db *dbCrypt) UpdateEncryptedWorkspaceBuildParameter(ctx context.Context, params database.UpdateEncryptedWorkspaceBuildParameterParams) error {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return err
+	}
+	return db.Store.UpdateEncryptedWorkspaceBuildParameter(ctx, params)
+}
+
 func (db *dbCrypt) encryptField(field *string, digest *sql.NullString) error {
 	// If no cipher is loaded, then we can't encrypt anything!
 	if db.ciphers == nil || db.primaryCipherDigest == "" {
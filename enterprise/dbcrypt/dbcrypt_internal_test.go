@@ -1827,6 +1827,232 @@ func TestUserSecrets(t *testing.T) {
 	})
 }
 
+func TestTemplateSecrets(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	const (
+		//nolint:gosec // test credentials
+		initialValue = "super-secret-value-initial"
+		//nolint:gosec // test credentials
+		updatedValue = "super-secret-value-updated"
+	)
+
+	insertTemplateSecret := func(t *testing.T, crypt *dbCrypt) database.TemplateSecret {
+		t.Helper()
+		org := dbgen.Organization(t, crypt, database.Organization{})
+		user := dbgen.User(t, crypt, database.User{})
+		template := dbgen.Template(t, crypt, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		secret, err := crypt.InsertTemplateSecret(ctx, database.InsertTemplateSecretParams{
+			ID:         uuid.New(),
+			TemplateID: template.ID,
+			Name:       "DATABASE_PASSWORD",
+			Value:      initialValue,
+			CreatedAt:  dbtime.Now(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, initialValue, secret.Value)
+		return secret
+	}
+
+	t.Run("InsertTemplateSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		secret := insertTemplateSecret(t, crypt)
+		require.Equal(t, ciphers[0].HexDigest(), secret.ValueKeyID.String)
+
+		got, err := crypt.GetTemplateSecretsByTemplateID(ctx, secret.TemplateID)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, initialValue, got[0].Value)
+
+		raw, err := db.GetTemplateSecretByID(ctx, secret.ID)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], raw.Value, initialValue)
+	})
+
+	t.Run("UpdateTemplateSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		secret := insertTemplateSecret(t, crypt)
+
+		updated, err := crypt.UpdateTemplateSecretByTemplateIDAndName(ctx, database.UpdateTemplateSecretByTemplateIDAndNameParams{
+			TemplateID:  secret.TemplateID,
+			Name:        secret.Name,
+			UpdateValue: true,
+			Value:       updatedValue,
+			UpdatedAt:   dbtime.Now(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, updatedValue, updated.Value)
+
+		raw, err := db.GetTemplateSecretByID(ctx, secret.ID)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], raw.Value, updatedValue)
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		secret := insertTemplateSecret(t, crypt)
+		_, err := db.UpdateEncryptedTemplateSecret(ctx, database.UpdateEncryptedTemplateSecretParams{
+			ID:         secret.ID,
+			Value:      fakeBase64RandomData(t, 32),
+			ValueKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+		})
+		require.NoError(t, err)
+
+		_, err = crypt.GetTemplateSecretsByTemplateID(ctx, secret.TemplateID)
+		require.Error(t, err)
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr)
+	})
+}
+
+func TestWorkspaceBuildParameters(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	const (
+		//nolint:gosec // test credentials
+		sensitiveValue = "super-secret-api-key"
+		plainValue     = "us-east-1"
+	)
+
+	insertParameters := func(t *testing.T, db database.Store, crypt *dbCrypt) uuid.UUID {
+		t.Helper()
+		dbtestutil.DisableForeignKeysAndTriggers(t, db)
+		params := dbgen.WorkspaceBuildParameters(t, crypt, []database.WorkspaceBuildParameter{
+			{Name: "api_key", Value: sensitiveValue, Sensitive: true},
+			{Name: "region", Value: plainValue},
+		})
+		require.Len(t, params, 2)
+		return params[0].WorkspaceBuildID
+	}
+
+	t.Run("InsertWorkspaceBuildParameters", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		buildID := insertParameters(t, db, crypt)
+
+		got, err := crypt.GetWorkspaceBuildParameters(ctx, buildID)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		for _, param := range got {
+			switch param.Name {
+			case "api_key":
+				require.Equal(t, sensitiveValue, param.Value)
+				require.Equal(t, ciphers[0].HexDigest(), param.ValueKeyID.String)
+			case "region":
+				require.Equal(t, plainValue, param.Value)
+				require.False(t, param.ValueKeyID.Valid)
+			}
+		}
+
+		// Only sensitive values are encrypted.
+		raw, err := db.GetWorkspaceBuildParameters(ctx, buildID)
+		require.NoError(t, err)
+		for _, param := range raw {
+			switch param.Name {
+			case "api_key":
+				requireEncryptedEquals(t, ciphers[0], param.Value, sensitiveValue)
+			case "region":
+				require.Equal(t, plainValue, param.Value)
+			}
+		}
+
+		sensitive, err := crypt.GetSensitiveWorkspaceBuildParameters(ctx)
+		require.NoError(t, err)
+		require.Len(t, sensitive, 1)
+		require.Equal(t, sensitiveValue, sensitive[0].Value)
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		buildID := insertParameters(t, db, crypt)
+		err := db.UpdateEncryptedWorkspaceBuildParameter(ctx, database.UpdateEncryptedWorkspaceBuildParameterParams{
+			WorkspaceBuildID: buildID,
+			Name:             "api_key",
+			Value:            fakeBase64RandomData(t, 32),
+			ValueKeyID:       sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+		})
+		require.NoError(t, err)
+
+		_, err = crypt.GetWorkspaceBuildParameters(ctx, buildID)
+		require.Error(t, err)
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr)
+	})
+}
+
+func TestVCSEventMappings(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	//nolint:gosec // test credentials
+	const signingSecret = "super-secret-signing-secret"
+
+	insertVCSEventMapping := func(t *testing.T, crypt *dbCrypt) database.VCSEventMapping {
+		t.Helper()
+		org := dbgen.Organization(t, crypt, database.Organization{})
+		user := dbgen.User(t, crypt, database.User{})
+		template := dbgen.Template(t, crypt, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		mapping, err := crypt.InsertVCSEventMapping(ctx, database.InsertVCSEventMappingParams{
+			ID:            uuid.New(),
+			TemplateID:    template.ID,
+			Name:          "main",
+			Repository:    "coder/coder",
+			Action:        database.VCSEventActionRefreshPrebuilds,
+			SigningSecret: signingSecret,
+			CreatedBy:     user.ID,
+			CreatedAt:     dbtime.Now(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, signingSecret, mapping.SigningSecret)
+		return mapping
+	}
+
+	t.Run("InsertVCSEventMapping", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		mapping := insertVCSEventMapping(t, crypt)
+		require.Equal(t, ciphers[0].HexDigest(), mapping.SigningSecretKeyID.String)
+
+		got, err := crypt.GetVCSEventMappingsByRepository(ctx, mapping.Repository)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, signingSecret, got[0].SigningSecret)
+
+		raw, err := db.GetVCSEventMappingByID(ctx, mapping.ID)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], raw.SigningSecret, signingSecret)
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		mapping := insertVCSEventMapping(t, crypt)
+		_, err := db.UpdateEncryptedVCSEventMapping(ctx, database.UpdateEncryptedVCSEventMappingParams{
+			ID:                 mapping.ID,
+			SigningSecret:      fakeBase64RandomData(t, 32),
+			SigningSecretKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+		})
+		require.NoError(t, err)
+
+		_, err = crypt.GetVCSEventMappingsByRepository(ctx, mapping.Repository)
+		require.Error(t, err)
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr)
+	})
+}
+
 func TestGitSSHKey(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
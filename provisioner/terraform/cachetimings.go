@@ -0,0 +1,152 @@
+package terraform
+
+import (
+	"strings"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// cacheSpanFromLog derives a cache timing span from a `terraform init` log
+// message that reports the installation of a provider or the download of a
+// module. Providers linked from the shared plugin cache directory are cache
+// hits; anything that terraform has to fetch is a cache miss, measured from
+// the start to the end of its download. Modules restored from the archive
+// cached by coderd do not produce any log, see ingestModuleCacheHits.
+//
+// Both the human-readable messages of Terraform before v1.9.0 and the JSON
+// messages of later versions are recognized. Nil is returned for any other
+// message.
+func cacheSpanFromLog(message string) *timingSpan {
+	switch {
+	case strings.Contains(message, "from the shared cache directory"):
+		return providerCacheSpan(timingCacheHit, message)
+	case strings.HasPrefix(message, "Installing provider version: "),
+		strings.HasPrefix(message, "- Installing "):
+		return providerCacheSpan(timingDownloadStart, message)
+	case strings.HasPrefix(message, "Installed provider version: "),
+		strings.HasPrefix(message, "- Installed "):
+		return providerCacheSpan(timingDownloadComplete, message)
+	case strings.HasPrefix(message, "Downloading "):
+		// e.g. "Downloading registry.coder.com/coder/cursor/coder 1.3.2 for cursor..."
+		idx := strings.LastIndex(message, " for ")
+		if idx == -1 {
+			return nil
+		}
+		return moduleCacheSpan(timingDownloadStart, strings.TrimSuffix(message[idx+len(" for "):], "..."))
+	case strings.HasPrefix(message, "- ") && strings.Contains(message, " in .terraform/modules/"):
+		// e.g. "- cursor in .terraform/modules/cursor"
+		key, _, _ := strings.Cut(strings.TrimPrefix(message, "- "), " in .terraform/modules/")
+		return moduleCacheSpan(timingDownloadComplete, key)
+	default:
+		return nil
+	}
+}
+
+func providerCacheSpan(kind timingKind, message string) *timingSpan {
+	resource := providerVersion(message)
+	if resource == "" {
+		return nil
+	}
+	return &timingSpan{
+		kind:     kind,
+		provider: string(codersdk.ProvisionerCacheArtifactProviderPlugin),
+		resource: resource,
+	}
+}
+
+func moduleCacheSpan(kind timingKind, key string) *timingSpan {
+	if key == "" {
+		return nil
+	}
+	return &timingSpan{
+		kind:     kind,
+		provider: string(codersdk.ProvisionerCacheArtifactModule),
+		resource: key,
+	}
+}
+
+// providerVersion extracts the provider address and version from an init log
+// message, e.g. "hashicorp/http v3.5.0" from
+// "Installed provider version: hashicorp/http v3.5.0 (signed by HashiCorp)".
+func providerVersion(message string) string {
+	fields := strings.Fields(message)
+	for i, field := range fields {
+		addr := strings.TrimSuffix(field, ":")
+		if !strings.Contains(addr, "/") {
+			continue
+		}
+		if i+1 < len(fields) {
+			version := strings.TrimRight(strings.TrimSuffix(fields[i+1], ":"), ".")
+			if strings.HasPrefix(version, "v") {
+				return addr + " " + version
+			}
+		}
+		return addr
+	}
+	return ""
+}
+
+// ingestCacheTiming records a cache hit or the download of an artifact that
+// missed the cache. Cache hits are instantaneous, so both their start and end
+// are recorded at once.
+func (t *timingAggregator) ingestCacheTiming(ts time.Time, s *timingSpan) {
+	// Artifacts are only obtained by `terraform init`.
+	s.stage = database.ProvisionerJobTimingStageInit
+
+	switch s.kind {
+	case timingCacheHit:
+		s.action = codersdk.ProvisionerTimingActionCacheHit
+		started := *s
+		started.start = ts
+		started.state = proto.TimingState_STARTED
+		s.end = ts
+		s.state = proto.TimingState_COMPLETED
+
+		t.lookupMu.Lock()
+		t.stateLookup[started.hashByState(started.state)] = &started
+		t.lookupMu.Unlock()
+	case timingDownloadStart:
+		s.action = codersdk.ProvisionerTimingActionCacheMiss
+		s.start = ts
+		s.state = proto.TimingState_STARTED
+	case timingDownloadComplete:
+		s.action = codersdk.ProvisionerTimingActionCacheMiss
+		s.end = ts
+		s.state = proto.TimingState_COMPLETED
+	default:
+		return
+	}
+
+	t.lookupMu.Lock()
+	t.stateLookup[s.hashByState(s.state)] = s
+	t.lookupMu.Unlock()
+}
+
+// ingestModuleCacheHits records a cache hit for every remote module that was
+// restored from the module archive instead of being downloaded by
+// `terraform init`.
+func (t *timingAggregator) ingestModuleCacheHits(ts time.Time, modules []*proto.Module) {
+	for _, module := range modules {
+		// Local modules are part of the template files.
+		if !strings.HasPrefix(module.Dir, ".terraform/modules/") {
+			continue
+		}
+
+		download := moduleCacheSpan(timingDownloadStart, module.Key)
+		if download == nil {
+			continue
+		}
+		download.action = codersdk.ProvisionerTimingActionCacheMiss
+		t.lookupMu.Lock()
+		_, downloaded := t.stateLookup[download.hashByState(proto.TimingState_STARTED)]
+		t.lookupMu.Unlock()
+		if downloaded {
+			continue
+		}
+
+		t.ingestCacheTiming(ts, moduleCacheSpan(timingCacheHit, module.Key))
+	}
+}
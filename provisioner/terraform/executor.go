@@ -42,6 +42,9 @@ type executor struct {
 	files         tfpath.Layout
 	// used to capture execution times at various stages
 	timings *timingAggregator
+	// disableProviderCache omits the shared plugin cache directory, so that
+	// providers are installed from their registries.
+	disableProviderCache bool
 }
 
 func (e *executor) basicEnv() []string {
@@ -50,7 +53,7 @@ func (e *executor) basicEnv() []string {
 	env := safeEnviron()
 	// Only Linux reliably works with the Terraform plugin
 	// cache directory. It's unknown why this is.
-	if e.cachePath != "" && runtime.GOOS == "linux" {
+	if e.cachePath != "" && !e.disableProviderCache && runtime.GOOS == "linux" {
 		env = append(env, "TF_PLUGIN_CACHE_DIR="+e.cachePath)
 	}
 	if e.cliConfigPath != "" {
@@ -310,6 +313,14 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 	if destroy {
 		args = append(args, "-destroy")
 	}
+	if targets := metadata.GetTargetResources(); len(targets) > 0 && !destroy {
+		for _, addr := range targets {
+			args = append(args, "-replace="+addr, "-target="+addr)
+		}
+		logr.ProvisionLog(proto.LogLevel_WARN, fmt.Sprintf("This is a targeted build. Only %s will be recreated; "+
+			"all other resources keep their current state and are only changed if the targets depend on them.",
+			strings.Join(targets, ", ")))
+	}
 	for _, variable := range vars {
 		args = append(args, "-var", variable)
 	}
@@ -738,6 +749,12 @@ func extractTimingSpan(log *terraformProvisionLog) (time.Time, *timingSpan, erro
 		ts = time.Now()
 	}
 
+	if typ == timingLog {
+		if span := cacheSpanFromLog(log.Message); span != nil {
+			return ts, span, nil
+		}
+	}
+
 	return ts, &timingSpan{
 		kind:        typ,
 		messageCode: log.MessageCode,
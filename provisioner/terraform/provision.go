@@ -19,6 +19,7 @@ import (
 
 	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/provisionersdk/proto"
@@ -78,6 +79,7 @@ func (s *server) Init(
 	defer kill()
 
 	e := s.executor(sess.Files, database.ProvisionerJobTimingStageInit)
+	e.disableProviderCache = request.GetDisableProviderCache()
 	if err := e.checkMinVersion(ctx); err != nil {
 		return provisionersdk.InitErrorf("%s", err.Error())
 	}
@@ -130,6 +132,9 @@ func (s *server) Init(
 		// for telemetry purposes now.
 		s.logger.Error(ctx, "failed to get modules from disk", slog.Error(err))
 	}
+	if len(request.ModuleArchive) > 0 {
+		e.timings.ingestModuleCacheHits(dbtime.Now(), modules)
+	}
 
 	var moduleFiles []byte
 	// Skipping modules archiving is useful if the caller does not need it, eg during
@@ -400,6 +405,11 @@ func provisionEnv(
 	if metadata.GetPrebuiltWorkspaceBuildStage().IsPrebuiltWorkspaceClaim() {
 		env = append(env, provider.IsPrebuildClaimEnvironmentVariable()+"=true")
 	}
+	// Templates can use this to snapshot persistent volumes instead of
+	// discarding them when the workspace is archived.
+	if metadata.GetWorkspaceArchive() {
+		env = append(env, "CODER_WORKSPACE_ARCHIVE=true")
+	}
 
 	for key, value := range provisionersdk.AgentScriptEnv() {
 		env = append(env, key+"="+value)
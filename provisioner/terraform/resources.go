@@ -3,6 +3,7 @@ package terraform
 import (
 	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"slices"
@@ -116,6 +117,10 @@ type agentAppAttributes struct {
 	Hidden      bool                       `mapstructure:"hidden"`
 	OpenIn      string                     `mapstructure:"open_in"`
 	Tooltip     string                     `mapstructure:"tooltip"`
+	// MaxSessionDurationSeconds and SessionIdleTimeoutSeconds bound the
+	// sessions used to access the app through the app proxy.
+	MaxSessionDurationSeconds int64 `mapstructure:"max_session_duration_seconds"`
+	SessionIdleTimeoutSeconds int64 `mapstructure:"session_idle_timeout_seconds"`
 }
 
 type agentEnvAttributes struct {
@@ -138,6 +143,23 @@ type agentScriptAttributes struct {
 	TimeoutSeconds   int32  `mapstructure:"timeout"`
 }
 
+// A mapping of attributes on the "coder_service" resource.
+type agentServiceAttributes struct {
+	AgentID       string `mapstructure:"agent_id"`
+	Name          string `mapstructure:"name"`
+	DisplayName   string `mapstructure:"display_name"`
+	Command       string `mapstructure:"command"`
+	RestartPolicy string `mapstructure:"restart_policy"`
+}
+
+// A mapping of attributes on the "coder_shared_volume" resource.
+type agentSharedVolumeAttributes struct {
+	AgentID    string `mapstructure:"agent_id"`
+	Name       string `mapstructure:"name"`
+	Path       string `mapstructure:"path"`
+	AccessMode string `mapstructure:"access_mode"`
+}
+
 // A mapping of attributes on the "healthcheck" resource.
 type appHealthcheckAttributes struct {
 	URL       string `mapstructure:"url"`
@@ -580,6 +602,9 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 			Hidden:       attrs.Hidden,
 			OpenIn:       openIn,
 			Tooltip:      attrs.Tooltip,
+
+			MaxSessionDurationSeconds: attrs.MaxSessionDurationSeconds,
+			SessionIdleTimeoutSeconds: attrs.SessionIdleTimeoutSeconds,
 		}
 
 	appAgentLoop:
@@ -678,6 +703,71 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 			}
 		}
 	}
+
+	// Associate services with agents.
+	for _, resource := range sortedResources["coder_service"] {
+		var attrs agentServiceAttributes
+		err = mapstructure.Decode(resource.AttributeValues, &attrs)
+		if err != nil {
+			return nil, xerrors.Errorf("decode service attributes: %w", err)
+		}
+		name := attrs.Name
+		if name == "" {
+			// Default to the resource name if none is set!
+			name = resource.Name
+		}
+		restartPolicy := attrs.RestartPolicy
+		if restartPolicy == "" {
+			restartPolicy = "on_failure"
+		}
+
+	serviceAgentLoop:
+		for _, agents := range resourceAgents {
+			for _, agent := range agents {
+				if dependsOnAgent(graph, agent, attrs.AgentID, resource) {
+					agent.Services = append(agent.Services, &proto.Service{
+						Name:          name,
+						DisplayName:   attrs.DisplayName,
+						Command:       attrs.Command,
+						RestartPolicy: restartPolicy,
+					})
+					break serviceAgentLoop
+				}
+			}
+		}
+	}
+
+	// Associate shared volumes with agents.
+	for _, resource := range sortedResources["coder_shared_volume"] {
+		var attrs agentSharedVolumeAttributes
+		err = mapstructure.Decode(resource.AttributeValues, &attrs)
+		if err != nil {
+			return nil, xerrors.Errorf("decode shared volume attributes: %w", err)
+		}
+		name := attrs.Name
+		if name == "" {
+			// Default to the resource name if none is set!
+			name = resource.Name
+		}
+		accessMode := attrs.AccessMode
+		if accessMode == "" {
+			accessMode = "read_write"
+		}
+
+	sharedVolumeAgentLoop:
+		for _, agents := range resourceAgents {
+			for _, agent := range agents {
+				if dependsOnAgent(graph, agent, attrs.AgentID, resource) {
+					agent.SharedVolumes = append(agent.SharedVolumes, &proto.SharedVolume{
+						Name:       name,
+						MountPath:  attrs.Path,
+						AccessMode: accessMode,
+					})
+					break sharedVolumeAgentLoop
+				}
+			}
+		}
+	}
 	// Associate metadata blocks with resources.
 	resourceMetadata := map[string][]*proto.Resource_Metadata{}
 	resourceHidden := map[string]bool{}
@@ -778,6 +868,7 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 			DailyCost:    resourceCost[label],
 			InstanceType: applyInstanceType(resource),
 			ModulePath:   modulePath,
+			CloudId:      cloudID(resource),
 		})
 	}
 
@@ -799,6 +890,7 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 			return nil, xerrors.Errorf("decode form_type for coder_parameter.%s: %w", resource.Name, err)
 		}
 
+		styling := parameterStyling(resource.AttributeValues)
 		protoParam := &proto.RichParameter{
 			Name:         param.Name,
 			DisplayName:  param.DisplayName,
@@ -810,8 +902,12 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 			Icon:         param.Icon,
 			Required:     !param.Optional,
 			// #nosec G115 - Safe conversion as parameter order value is expected to be within int32 range
-			Order:     int32(param.Order),
-			Ephemeral: param.Ephemeral,
+			Order:      int32(param.Order),
+			Ephemeral:  param.Ephemeral,
+			Sensitive:  styling.MaskInput,
+			Group:      styling.Group,
+			GroupOrder: styling.GroupOrder,
+			Page:       styling.Page,
 		}
 		if len(param.Validation) == 1 {
 			protoParam.ValidationRegex = param.Validation[0].Regex
@@ -1129,9 +1225,9 @@ func sortResourcesByType(tfResourcesByLabel map[string]map[string]*tfjson.StateR
 // map from sortResourcesByType.
 func managedNonCoderResources(byType map[string][]*tfjson.StateResource) []*tfjson.StateResource {
 	skip := map[string]bool{
-		"coder_script": true, "coder_agent": true,
+		"coder_script": true, "coder_agent": true, "coder_service": true,
 		"coder_agent_instance": true, "coder_app": true,
-		"coder_metadata": true,
+		"coder_metadata": true, "coder_shared_volume": true,
 	}
 	var result []*tfjson.StateResource
 	for resourceType, resources := range byType {
@@ -1251,6 +1347,17 @@ func applyInstanceType(resource *tfjson.StateResource) string {
 	return instanceType
 }
 
+// cloudID returns the identifier of the resource at its cloud provider. By
+// convention, every Terraform resource has an "id" attribute, which is only
+// known once the resource was created.
+func cloudID(resource *tfjson.StateResource) string {
+	id, ok := resource.AttributeValues["id"].(string)
+	if !ok {
+		return ""
+	}
+	return id
+}
+
 // applyAutomaticInstanceID checks if the resource is one of a set of *magical* IDs
 // that automatically index their identifier for automatic authentication.
 func applyAutomaticInstanceID(resource *tfjson.StateResource, agents []*proto.Agent) {
@@ -1335,3 +1442,29 @@ func findResourcesInGraph(graph *gographviz.Graph, tfResourcesByLabel map[string
 
 	return graphResources
 }
+
+// parameterStylingAttributes are the attributes of the styling of a
+// coder_parameter that are persisted with the parameter.
+type parameterStylingAttributes struct {
+	// MaskInput marks the values of the parameter as sensitive.
+	MaskInput bool `json:"mask_input"`
+	// Group, GroupOrder and Page present the parameter in a section of a
+	// multi-page form.
+	Group      string `json:"group"`
+	GroupOrder int32  `json:"group_order"`
+	Page       int32  `json:"page"`
+}
+
+// parameterStyling decodes the styling of a coder_parameter. A missing or
+// invalid styling is ignored.
+func parameterStyling(attributes map[string]interface{}) parameterStylingAttributes {
+	var styling parameterStylingAttributes
+	raw, ok := attributes["styling"].(string)
+	if !ok || raw == "" {
+		return styling
+	}
+	if err := json.Unmarshal([]byte(raw), &styling); err != nil {
+		return parameterStylingAttributes{}
+	}
+	return styling
+}
@@ -1108,6 +1108,8 @@ func TestConvertResources(t *testing.T) {
 				sortExternalAuthProviders(state.ExternalAuthProviders)
 
 				for _, resource := range state.Resources {
+					// Cloud IDs are generated by the providers.
+					resource.CloudId = ""
 					for _, agent := range resource.Agents {
 						agent.Id = ""
 						if agent.GetToken() != "" {
@@ -1188,6 +1190,8 @@ func TestConvertResources(t *testing.T) {
 				sortResources(state.Resources)
 				sortExternalAuthProviders(state.ExternalAuthProviders)
 				for _, resource := range state.Resources {
+					// Cloud IDs are generated by the providers.
+					resource.CloudId = ""
 					for _, agent := range resource.Agents {
 						agent.Id = ""
 						if agent.GetToken() != "" {
@@ -1611,6 +1615,47 @@ func TestInstanceTypeAssociation(t *testing.T) {
 	}
 }
 
+func TestCloudID(t *testing.T) {
+	t.Parallel()
+	ctx, logger := ctxAndLogger(t)
+	state, err := terraform.ConvertState(ctx, []*tfjson.StateModule{{
+		Resources: []*tfjson.StateResource{{
+			Address: "aws_instance.dev",
+			Type:    "aws_instance",
+			Name:    "dev",
+			Mode:    tfjson.ManagedResourceMode,
+			AttributeValues: map[string]interface{}{
+				"id": "i-0123456789abcdef0",
+			},
+		}, {
+			Address: "aws_ebs_volume.home",
+			Type:    "aws_ebs_volume",
+			Name:    "home",
+			Mode:    tfjson.ManagedResourceMode,
+			// The ID is unknown until the resource is created.
+			AttributeValues: map[string]interface{}{},
+		}},
+		// This is manually created to join the edges.
+	}}, `digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] aws_instance.dev" [label = "aws_instance.dev", shape = "box"]
+		"[root] aws_ebs_volume.home" [label = "aws_ebs_volume.home", shape = "box"]
+	}
+}`, logger)
+	require.NoError(t, err)
+	require.Len(t, state.Resources, 2)
+	cloudIDs := map[string]string{}
+	for _, resource := range state.Resources {
+		cloudIDs[resource.Name] = resource.GetCloudId()
+	}
+	require.Equal(t, map[string]string{
+		"dev":  "i-0123456789abcdef0",
+		"home": "",
+	}, cloudIDs)
+}
+
 func TestInstanceIDAssociation(t *testing.T) {
 	t.Parallel()
 	type tc struct {
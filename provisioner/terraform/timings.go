@@ -47,6 +47,11 @@ const (
 	timingGraphStart    timingKind = "graph_start"
 	timingGraphComplete timingKind = "graph_complete"
 	timingGraphErrored  timingKind = "graph_errored"
+	// These are derived from init "log" messages about the installation of
+	// providers and modules, see cacheSpanFromLog.
+	timingCacheHit         timingKind = "cache_hit"
+	timingDownloadStart    timingKind = "download_start"
+	timingDownloadComplete timingKind = "download_complete"
 	// Other terraform log types which we ignore.
 	timingLog        timingKind = "log"
 	timingInitOutput timingKind = "init_output"
@@ -126,6 +131,9 @@ func (t *timingAggregator) ingest(ts time.Time, s *timingSpan) {
 		// init timings are based on the init message code.
 		t.ingestInitTiming(ts, s)
 		return
+	case timingCacheHit, timingDownloadStart, timingDownloadComplete:
+		t.ingestCacheTiming(ts, s)
+		return
 	default:
 		// We just want start/end timings, ignore all other events.
 		return
@@ -228,6 +236,9 @@ func (l timingKind) Valid() bool {
 		timingGraphStart,
 		timingGraphComplete,
 		timingGraphErrored,
+		timingCacheHit,
+		timingDownloadStart,
+		timingDownloadComplete,
 		timingLog,
 		timingInitOutput,
 	}, l)
@@ -244,6 +255,8 @@ func (l timingKind) Category() string {
 		return "init"
 	case timingGraphStart, timingGraphComplete, timingGraphErrored:
 		return "graph"
+	case timingCacheHit, timingDownloadStart, timingDownloadComplete:
+		return "cache"
 	case timingApplyStart, timingApplyProgress, timingApplyComplete, timingApplyErrored:
 		return "apply"
 	case timingProvisionStart, timingProvisionProgress, timingProvisionComplete, timingProvisionErrored:
@@ -24,6 +24,8 @@ var (
 	inputInit []byte
 	//go:embed testdata/timings-aggregation/initupgrade.txtar
 	inputInitUpgrade []byte
+	//go:embed testdata/timings-aggregation/initcached.txtar
+	inputInitCached []byte
 	//go:embed testdata/timings-aggregation/error.txtar
 	inputError []byte
 	//go:embed testdata/timings-aggregation/complete.txtar
@@ -51,6 +53,10 @@ func TestAggregation(t *testing.T) {
 			name:  "initupgrade",
 			input: inputInitUpgrade,
 		},
+		{
+			name:  "initcached",
+			input: inputInitCached,
+		},
 		{
 			name:  "simple",
 			input: inputSimple,
@@ -110,7 +110,13 @@ func TestTimingsFromProvision(t *testing.T) {
 	// NOTE: These timings have been encoded to JSON format to make the tests more readable.
 	initTimings := terraform_internal.ParseTimingLines(t, []byte(`{"start":"2025-10-22T17:48:29Z","end":"2025-10-22T17:48:31Z","action":"load","resource":"modules","stage":"init","state":"COMPLETED"}
 {"start":"2025-10-22T17:48:29Z","end":"2025-10-22T17:48:29Z","action":"load","resource":"backend","stage":"init","state":"COMPLETED"}
-{"start":"2025-10-22T17:48:31Z","end":"2025-10-22T17:48:34Z","action":"load","resource":"provider plugins","stage":"init","state":"COMPLETED"}`))
+{"start":"2025-10-22T17:48:31Z","end":"2025-10-22T17:48:34Z","action":"load","resource":"provider plugins","stage":"init","state":"COMPLETED"}
+{"start":"2025-10-22T17:48:29.780639Z","end":"2025-10-22T17:48:29.982904Z","action":"cache miss","source":"module","resource":"cursor","stage":"init","state":"COMPLETED"}
+{"start":"2025-10-22T17:48:30.039894Z","end":"2025-10-22T17:48:30.202355Z","action":"cache miss","source":"module","resource":"jetbrains","stage":"init","state":"COMPLETED"}
+{"start":"2025-10-22T17:48:30.202394Z","end":"2025-10-22T17:48:31.799988Z","action":"cache miss","source":"module","resource":"large-5mb-module","stage":"init","state":"COMPLETED"}
+{"start":"2025-10-22T17:48:32.081468Z","end":"2025-10-22T17:48:32.375580Z","action":"cache miss","source":"provider_plugin","resource":"hashicorp/http v3.5.0","stage":"init","state":"COMPLETED"}
+{"start":"2025-10-22T17:48:32.869110Z","end":"2025-10-22T17:48:33.350069Z","action":"cache miss","source":"provider_plugin","resource":"coder/coder v2.11.0","stage":"init","state":"COMPLETED"}
+{"start":"2025-10-22T17:48:33.572112Z","end":"2025-10-22T17:48:34.458153Z","action":"cache miss","source":"provider_plugin","resource":"kreuzwerker/docker v3.6.2","stage":"init","state":"COMPLETED"}`))
 	planTimings := terraform_internal.ParseTimingLines(t, []byte(`{"start":"2024-08-15T08:26:39.194726Z", "end":"2024-08-15T08:26:39.195836Z", "action":"read", "source":"coder", "resource":"data.coder_parameter.memory_size", "stage":"plan", "state":"COMPLETED"}
 {"start":"2024-08-15T08:26:39.194726Z", "end":"2024-08-15T08:26:39.195712Z", "action":"read", "source":"coder", "resource":"data.coder_provisioner.me", "stage":"plan", "state":"COMPLETED"}
 {"start":"2024-08-15T08:26:39.194726Z", "end":"2024-08-15T08:26:39.195820Z", "action":"read", "source":"coder", "resource":"data.coder_workspace.me", "stage":"plan", "state":"COMPLETED"}`))
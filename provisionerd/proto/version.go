@@ -92,9 +92,46 @@ import "github.com/coder/coder/v2/apiversion"
 //     `coder_secret` Terraform integration is being removed; user secrets are
 //     still delivered to running workspaces via the agent manifest path, which
 //     is independent of this proto.
+//
+// API v1.19:
+//   - Added `target_resources` to `provisioner.Metadata` for builds limited to
+//     a set of Terraform resources.
+//
+// API v1.20:
+//   - Added `max_session_duration_seconds` and `session_idle_timeout_seconds`
+//     to `provisioner.App`.
+//
+// API v1.21:
+//   - Added `workspace_archive` to `provisioner.Metadata` for builds that
+//     archive a workspace.
+//
+// API v1.22:
+//   - Added `sensitive` to `provisioner.RichParameter` for parameters whose
+//     values are encrypted at rest and redacted in API responses.
+//
+// API v1.23:
+//   - Added `cloud_id` to `provisioner.Resource` to record the identifier of
+//     the resource at its cloud provider.
+//
+// API v1.24:
+//   - Added `disable_provider_cache` to `provisioner.Metadata` and
+//     `provisioner.InitRequest` for builds that must not use the shared
+//     Terraform plugin cache.
+//
+// API v1.25:
+//   - Added `services` to `provisioner.Agent` for long-running processes
+//     supervised by the agent.
+//
+// API v1.26:
+//   - Added `shared_volumes` to `provisioner.Agent` for organization-wide
+//     volumes attached to the workspace.
+//
+// API v1.27:
+//   - Added `group`, `group_order` and `page` to `provisioner.RichParameter`
+//     for presenting parameters in sections and multi-page forms.
 const (
 	CurrentMajor = 1
-	CurrentMinor = 18
+	CurrentMinor = 27
 )
 
 // CurrentVersion is the current provisionerd API version.
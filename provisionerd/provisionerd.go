@@ -189,11 +189,14 @@ func NewMetrics(reg prometheus.Registerer) Metrics {
 					60 * 60, // 1hr
 				},
 			}, []string{"provisioner", "status"}),
+			// Deprecated: coderd_workspace_builds_completed_total, recorded by
+			// coderd, replaces this counter. It is still emitted so existing
+			// dashboards and alerts keep working until it is removed.
 			WorkspaceBuilds: auto.NewCounterVec(prometheus.CounterOpts{
 				Namespace: "coderd",
 				Subsystem: "", // Explicitly empty to make this a top-level metric.
 				Name:      "workspace_builds_total",
-				Help:      "The number of workspaces started, updated, or deleted.",
+				Help:      "Deprecated: use coderd_workspace_builds_completed_total instead. The number of workspaces started, updated, or deleted.",
 			}, []string{"workspace_owner", "workspace_name", "template_name", "template_version", "workspace_transition", "status"}),
 			WorkspaceBuildTimings: auto.NewHistogramVec(prometheus.HistogramOpts{
 				Namespace: "coderd",
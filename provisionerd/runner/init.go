@@ -12,7 +12,7 @@ import (
 )
 
 //nolint:revive
-func (r *Runner) init(ctx context.Context, omitModules bool, templateArchive []byte, moduleTar []byte) (*sdkproto.InitComplete, *proto.FailedJob) {
+func (r *Runner) init(ctx context.Context, omitModules bool, disableProviderCache bool, templateArchive []byte, moduleTar []byte) (*sdkproto.InitComplete, *proto.FailedJob) {
 	ctx, span := r.startTrace(ctx, tracing.FuncName())
 	defer span.End()
 
@@ -33,6 +33,7 @@ func (r *Runner) init(ctx context.Context, omitModules bool, templateArchive []b
 		TemplateSourceArchive: templateArchive,
 		OmitModuleFiles:       omitModules,
 		InitialModuleTarHash:  hash,
+		DisableProviderCache:  disableProviderCache,
 	}}})
 	if err != nil {
 		return nil, r.failedJobf("send init request: %v", err)
@@ -532,7 +532,7 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 	}
 
 	// Initialize the Terraform working directory
-	initResp, failedInit := r.init(ctx, false, r.job.GetTemplateSourceArchive(), nil)
+	initResp, failedInit := r.init(ctx, false, r.job.GetTemplateImport().GetMetadata().GetDisableProviderCache(), r.job.GetTemplateSourceArchive(), nil)
 	if failedInit != nil {
 		return nil, failedInit
 	}
@@ -795,7 +795,7 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 	}
 
 	// Initialize the Terraform working directory
-	initResp, failedJob := r.init(ctx, false, r.job.GetTemplateSourceArchive(), nil)
+	initResp, failedJob := r.init(ctx, false, metadata.GetDisableProviderCache(), r.job.GetTemplateSourceArchive(), nil)
 	if failedJob != nil {
 		return nil, failedJob
 	}
@@ -930,7 +930,7 @@ func (r *Runner) runWorkspaceBuild(ctx context.Context) (*proto.CompletedJob, *p
 	}
 
 	// Initialize the Terraform working directory
-	initComplete, failedJob := r.init(ctx, true, r.job.GetTemplateSourceArchive(), cachedModulesTar)
+	initComplete, failedJob := r.init(ctx, true, r.job.GetWorkspaceBuild().GetMetadata().GetDisableProviderCache(), r.job.GetTemplateSourceArchive(), cachedModulesTar)
 	if failedJob != nil {
 		return nil, failedJob
 	}
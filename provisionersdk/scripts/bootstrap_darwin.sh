@@ -10,19 +10,33 @@ waitonexit() {
 trap waitonexit EXIT
 BINARY_DIR=$(mktemp -d -t coder.XXXXXX)
 BINARY_NAME=coder
-BINARY_URL=${ACCESS_URL}bin/coder-darwin-${ARCH}
+STABLE_BINARY_URL=${ACCESS_URL}bin/coder-darwin-${ARCH}
+BINARY_URL=${STABLE_BINARY_URL}
+# Agents authenticating with a token download the binary of the rollout
+# channel their workspace is assigned to. Tracing is paused while the token is
+# in use so it does not end up in the logs.
+set +x
+AUTH_HEADER="Coder-Session-Token:"
+if [ "${AUTH_TYPE}" = "token" ] && [ -n "${CODER_AGENT_TOKEN:-}" ]; then
+	BINARY_URL=${ACCESS_URL}api/v2/workspaceagents/me/binary/coder-darwin-${ARCH}
+	AUTH_HEADER="Coder-Session-Token: ${CODER_AGENT_TOKEN}"
+fi
 cd "$BINARY_DIR"
 # Attempt to download the coder agent.
 # This could fail for a number of reasons, many of which are likely transient.
 # So just keep trying!
 while :; do
-	curl -fsSL --compressed "${BINARY_URL}" -o "${BINARY_NAME}" && break
+	curl -fsSL --compressed -H "${AUTH_HEADER}" "${BINARY_URL}" -o "${BINARY_NAME}" && break
 	status=$?
 	echo "error: failed to download coder agent using curl"
 	echo "curl exit code: ${status}"
+	# Older servers do not serve rollout channels, so fall back to the
+	# binary bundled with the server.
+	BINARY_URL=${STABLE_BINARY_URL}
 	echo "Trying again in 30 seconds..."
 	sleep 30
 done
+set -x
 
 if ! chmod +x $BINARY_NAME; then
 	echo "Failed to make $BINARY_NAME executable"
@@ -4,6 +4,15 @@ trap {
 	Start-Sleep -Seconds 86400
 }
 
+# Agents authenticating with a token download the binary of the rollout
+# channel their workspace is assigned to.
+$BINARY_URL="${ACCESS_URL}/bin/coder-windows-${ARCH}.exe"
+$HEADERS = @{}
+if ("${AUTH_TYPE}" -eq "token" -and $env:CODER_AGENT_TOKEN) {
+	$BINARY_URL="${ACCESS_URL}api/v2/workspaceagents/me/binary/coder-windows-${ARCH}.exe"
+	$HEADERS = @{ "Coder-Session-Token" = $env:CODER_AGENT_TOKEN }
+}
+
 # Attempt to download the coder agent.
 # This could fail for a number of reasons, many of which are likely transient.
 # So just keep trying!
@@ -13,13 +22,16 @@ while ($true) {
 		# On Windows, VS Code Remote requires a parent process of the
 		# executing shell to be named "sshd", otherwise it fails. See:
 		# https://github.com/microsoft/vscode-remote-release/issues/5699
-		$BINARY_URL="${ACCESS_URL}/bin/coder-windows-${ARCH}.exe"
 		Write-Output "$(Get-Date) Fetching coder agent from ${BINARY_URL}"
-		Invoke-WebRequest -Uri "${BINARY_URL}" -OutFile $env:TEMP\sshd.exe
+		Invoke-WebRequest -Uri "${BINARY_URL}" -Headers $HEADERS -OutFile $env:TEMP\sshd.exe
 		break
 	} catch {
 		Write-Output "$(Get-Date) error: unhandled exception fetching coder agent:"
 		Write-Output $_
+		# Older servers do not serve rollout channels, so fall back to the
+		# binary bundled with the server.
+		$BINARY_URL="${ACCESS_URL}/bin/coder-windows-${ARCH}.exe"
+		$HEADERS = @{}
 		Write-Output "$(Get-Date) trying again in 30 seconds..."
 		Start-Sleep -Seconds 30
 	}
@@ -742,6 +742,8 @@ const createTemplateVersionTar = async (
 								url: "",
 								group: "",
 								tooltip: "",
+								maxSessionDurationSeconds: 0,
+								sessionIdleTimeoutSeconds: 0,
 								...app,
 							} as App;
 						});
@@ -768,6 +770,8 @@ const createTemplateVersionTar = async (
 						token: randomUUID(),
 						devcontainers: [],
 						apiKeyScope: "all",
+						services: [],
+						sharedVolumes: [],
 						...agent,
 					} as Agent;
 
@@ -800,6 +804,7 @@ const createTemplateVersionTar = async (
 			name: "dev",
 			type: "echo",
 			modulePath: "",
+			cloudId: "",
 			...resource,
 		} as Resource;
 	};
@@ -136,6 +136,19 @@ export interface RichParameter {
   order: number;
   ephemeral: boolean;
   formType: ParameterFormType;
+  /**
+   * True if the values of the parameter are sensitive. They are encrypted
+   * at rest and redacted in API responses.
+   */
+  sensitive: boolean;
+  /**
+   * The group of the parameter, used to present parameters in sections.
+   * All parameters of a group must share its group_order and page.
+   */
+  group: string;
+  groupOrder: number;
+  /** The page of a multi-page form on which to present the parameter. */
+  page: number;
 }
 
 /** RichParameterValue holds the key/value mapping of a parameter. */
@@ -244,6 +257,8 @@ export interface Agent {
   resourcesMonitoring: ResourcesMonitoring | undefined;
   devcontainers: Devcontainer[];
   apiKeyScope: string;
+  services: Service[];
+  sharedVolumes: SharedVolume[];
 }
 
 export interface Agent_Metadata {
@@ -319,6 +334,23 @@ export interface Devcontainer {
   envs: Env[];
 }
 
+/** Service is a long-running process supervised by the agent. */
+export interface Service {
+  name: string;
+  displayName: string;
+  command: string;
+  /** restart_policy is one of "always", "on_failure" or "never". */
+  restartPolicy: string;
+}
+
+/** SharedVolume is an organization-wide volume mounted into the agent. */
+export interface SharedVolume {
+  name: string;
+  mountPath: string;
+  /** access_mode is one of "read_only" or "read_write". */
+  accessMode: string;
+}
+
 /** App represents a dev-accessible application on the workspace. */
 export interface App {
   /**
@@ -341,6 +373,12 @@ export interface App {
   /** If nil, new UUID will be generated. */
   id: string;
   tooltip: string;
+  /**
+   * Session limits enforced by the workspace app proxy. Zero means
+   * unlimited.
+   */
+  maxSessionDurationSeconds: number;
+  sessionIdleTimeoutSeconds: number;
 }
 
 /** Healthcheck represents configuration for checking for app readiness. */
@@ -361,6 +399,11 @@ export interface Resource {
   instanceType: string;
   dailyCost: number;
   modulePath: string;
+  /**
+   * Identifier of the resource at its cloud provider, e.g. the `id`
+   * attribute of a Terraform resource.
+   */
+  cloudId: string;
 }
 
 export interface Resource_Metadata {
@@ -425,6 +468,21 @@ export interface Metadata {
   taskPrompt: string;
   templateVersionId: string;
   templateVersionModulesFile: string;
+  /**
+   * Terraform resource addresses to limit the build to. Each is replaced
+   * and only resources it depends on are changed.
+   */
+  targetResources: string[];
+  /**
+   * True if the build destroys the resources of a workspace that is being
+   * archived, so templates can keep snapshots of persistent volumes.
+   */
+  workspaceArchive: boolean;
+  /**
+   * True if provisioners must install Terraform providers from their
+   * registries instead of reusing them from the shared plugin cache.
+   */
+  disableProviderCache: boolean;
 }
 
 /** Config represents execution configuration shared by all subsequent requests in the Session */
@@ -467,6 +525,8 @@ export interface InitRequest {
   omitModuleFiles: boolean;
   /** initial_module_tar is the hash of the tar of the terraform module files located in .terraform/modules */
   initialModuleTarHash: Uint8Array;
+  /** If true, providers are installed without the shared plugin cache. */
+  disableProviderCache: boolean;
 }
 
 export interface InitComplete {
@@ -713,6 +773,18 @@ export const RichParameter = {
     if (message.formType !== 0) {
       writer.uint32(144).int32(message.formType);
     }
+    if (message.sensitive !== false) {
+      writer.uint32(152).bool(message.sensitive);
+    }
+    if (message.group !== "") {
+      writer.uint32(162).string(message.group);
+    }
+    if (message.groupOrder !== 0) {
+      writer.uint32(168).int32(message.groupOrder);
+    }
+    if (message.page !== 0) {
+      writer.uint32(176).int32(message.page);
+    }
     return writer;
   },
 };
@@ -947,6 +1019,12 @@ export const Agent = {
     if (message.apiKeyScope !== "") {
       writer.uint32(210).string(message.apiKeyScope);
     }
+    for (const v of message.services) {
+      Service.encode(v!, writer.uint32(218).fork()).ldelim();
+    }
+    for (const v of message.sharedVolumes) {
+      SharedVolume.encode(v!, writer.uint32(226).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -1125,6 +1203,39 @@ export const Devcontainer = {
   },
 };
 
+export const Service = {
+  encode(message: Service, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    if (message.displayName !== "") {
+      writer.uint32(18).string(message.displayName);
+    }
+    if (message.command !== "") {
+      writer.uint32(26).string(message.command);
+    }
+    if (message.restartPolicy !== "") {
+      writer.uint32(34).string(message.restartPolicy);
+    }
+    return writer;
+  },
+};
+
+export const SharedVolume = {
+  encode(message: SharedVolume, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    if (message.mountPath !== "") {
+      writer.uint32(18).string(message.mountPath);
+    }
+    if (message.accessMode !== "") {
+      writer.uint32(26).string(message.accessMode);
+    }
+    return writer;
+  },
+};
+
 export const App = {
   encode(message: App, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
     if (message.slug !== "") {
@@ -1172,6 +1283,12 @@ export const App = {
     if (message.tooltip !== "") {
       writer.uint32(122).string(message.tooltip);
     }
+    if (message.maxSessionDurationSeconds !== 0) {
+      writer.uint32(128).int64(message.maxSessionDurationSeconds);
+    }
+    if (message.sessionIdleTimeoutSeconds !== 0) {
+      writer.uint32(136).int64(message.sessionIdleTimeoutSeconds);
+    }
     return writer;
   },
 };
@@ -1220,6 +1337,9 @@ export const Resource = {
     if (message.modulePath !== "") {
       writer.uint32(74).string(message.modulePath);
     }
+    if (message.cloudId !== "") {
+      writer.uint32(82).string(message.cloudId);
+    }
     return writer;
   },
 };
@@ -1385,6 +1505,15 @@ export const Metadata = {
     if (message.templateVersionModulesFile !== "") {
       writer.uint32(202).string(message.templateVersionModulesFile);
     }
+    for (const v of message.targetResources) {
+      writer.uint32(210).string(v!);
+    }
+    if (message.workspaceArchive !== false) {
+      writer.uint32(216).bool(message.workspaceArchive);
+    }
+    if (message.disableProviderCache !== false) {
+      writer.uint32(224).bool(message.disableProviderCache);
+    }
     return writer;
   },
 };
@@ -1451,6 +1580,9 @@ export const InitRequest = {
     if (message.initialModuleTarHash.length !== 0) {
       writer.uint32(34).bytes(message.initialModuleTarHash);
     }
+    if (message.disableProviderCache !== false) {
+      writer.uint32(40).bool(message.disableProviderCache);
+    }
     return writer;
   },
 };
@@ -2636,11 +2636,17 @@ class ApiMethods {
 		return this.startWorkspace(workspace.id, template.active_version_id);
 	};
 
+	/**
+	 * The values of sensitive parameters are redacted, unless includeSensitive
+	 * is set. It requires permission to update the workspace.
+	 */
 	getWorkspaceBuildParameters = async (
 		workspaceBuildId: TypesGen.WorkspaceBuild["id"],
+		includeSensitive = false,
 	): Promise<TypesGen.WorkspaceBuildParameter[]> => {
 		const response = await this.axios.get<TypesGen.WorkspaceBuildParameter[]>(
 			`/api/v2/workspacebuilds/${workspaceBuildId}/parameters`,
+			{ params: includeSensitive ? { include_sensitive: true } : undefined },
 		);
 
 		return response.data;
@@ -2685,6 +2691,7 @@ class ApiMethods {
 			...p,
 			description_plaintext: p.description || "",
 			default_value: p.default_value?.valid ? p.default_value.value : "",
+			sensitive: p.styling.mask_input ?? false,
 			options: p.options
 				? p.options.map((opt) => ({
 						...opt,
@@ -2730,6 +2737,7 @@ class ApiMethods {
 	): Promise<TypesGen.WorkspaceBuild> => {
 		const currentBuildParameters = await this.getWorkspaceBuildParameters(
 			workspace.latest_build.id,
+			true,
 		);
 
 		let templateParameters: TypesGen.TemplateVersionParameter[] = [];
@@ -2780,7 +2788,7 @@ class ApiMethods {
 	): Promise<TypesGen.WorkspaceBuild> => {
 		const [template, oldBuildParameters] = await Promise.all([
 			this.getTemplate(workspace.template_id),
-			this.getWorkspaceBuildParameters(workspace.latest_build.id),
+			this.getWorkspaceBuildParameters(workspace.latest_build.id, true),
 		]);
 
 		const activeVersionId = template.active_version_id;
@@ -2961,6 +2969,24 @@ class ApiMethods {
 		return res.data;
 	};
 
+	getUserScopedNotificationPreferences = async (userId: string) => {
+		const res = await this.axios.get<TypesGen.ScopedNotificationPreference[]>(
+			`/api/v2/users/${userId}/notification-preferences`,
+		);
+		return res.data;
+	};
+
+	putUserScopedNotificationPreferences = async (
+		userId: string,
+		req: TypesGen.UpdateScopedNotificationPreferences,
+	) => {
+		const res = await this.axios.put<TypesGen.ScopedNotificationPreference[]>(
+			`/api/v2/users/${userId}/notification-preferences`,
+			req,
+		);
+		return res.data;
+	};
+
 	getSystemNotificationTemplates = async () => {
 		const res = await this.axios.get<TypesGen.NotificationTemplate[]>(
 			"/api/v2/notifications/templates/system",
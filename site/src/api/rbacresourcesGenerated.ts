@@ -215,6 +215,7 @@ export const RBACResourceActions: Partial<
 	user: {
 		create: "create a new user",
 		delete: "delete an existing user",
+		impersonate: "issue time-boxed tokens to act as the user",
 		read: "read user data",
 		read_personal: "read personal user data like user settings and auth links",
 		update: "update an existing user",
@@ -243,6 +244,7 @@ export const RBACResourceActions: Partial<
 		create_agent: "create a new workspace agent",
 		delete: "delete workspace",
 		delete_agent: "delete an existing workspace agent",
+		extend: "extend the deadline of a running workspace, including through activity",
 		read: "read workspace data to view on the UI",
 		share: "share a workspace with other users or groups",
 		ssh: "ssh into a given workspace",
@@ -271,6 +273,7 @@ export const RBACResourceActions: Partial<
 		create_agent: "create a new workspace agent",
 		delete: "delete workspace",
 		delete_agent: "delete an existing workspace agent",
+		extend: "extend the deadline of a running workspace, including through activity",
 		read: "read workspace data to view on the UI",
 		share: "share a workspace with other users or groups",
 		ssh: "ssh into a given workspace",
@@ -10,6 +10,15 @@ export interface ACLAvailable {
 	readonly groups: readonly Group[];
 }
 
+// From codersdk/templates.go
+/**
+ * ACLSubjectType is the type of subject whose role in an access control list
+ * changed.
+ */
+export type ACLSubjectType = "group" | "user";
+
+export const ACLSubjectTypes: ACLSubjectType[] = ["group", "user"];
+
 // From codersdk/aibridge.go
 /**
  * AIBridgeAgenticAction represents a tool call with associated
@@ -578,6 +587,11 @@ export interface APIKey {
 	readonly token_name: string;
 	readonly lifetime_seconds: number;
 	readonly allow_list: readonly APIAllowListTarget[];
+	/**
+	 * TemplateIDs restricts workspace operations performed with the key to
+	 * workspaces built from these templates. Empty means unrestricted.
+	 */
+	readonly template_ids: readonly string[];
 }
 
 // From codersdk/apikey.go
@@ -760,6 +774,7 @@ export type APIKeyScope =
 	| "user:*"
 	| "user:create"
 	| "user:delete"
+	| "user:impersonate"
 	| "user:read"
 	| "user:read_personal"
 	| "user_secret:*"
@@ -801,6 +816,7 @@ export type APIKeyScope =
 	| "workspace_dormant:create_agent"
 	| "workspace_dormant:delete"
 	| "workspace_dormant:delete_agent"
+	| "workspace_dormant:extend"
 	| "workspace_dormant:read"
 	| "workspace_dormant:share"
 	| "workspace_dormant:ssh"
@@ -808,6 +824,7 @@ export type APIKeyScope =
 	| "workspace_dormant:stop"
 	| "workspace_dormant:update"
 	| "workspace_dormant:update_agent"
+	| "workspace:extend"
 	| "workspace_proxy:*"
 	| "workspace_proxy:create"
 	| "workspace_proxy:delete"
@@ -1000,6 +1017,7 @@ export const APIKeyScopes: APIKeyScope[] = [
 	"user:*",
 	"user:create",
 	"user:delete",
+	"user:impersonate",
 	"user:read",
 	"user:read_personal",
 	"user_secret:*",
@@ -1041,6 +1059,7 @@ export const APIKeyScopes: APIKeyScope[] = [
 	"workspace_dormant:create_agent",
 	"workspace_dormant:delete",
 	"workspace_dormant:delete_agent",
+	"workspace_dormant:extend",
 	"workspace_dormant:read",
 	"workspace_dormant:share",
 	"workspace_dormant:ssh",
@@ -1048,6 +1067,7 @@ export const APIKeyScopes: APIKeyScope[] = [
 	"workspace_dormant:stop",
 	"workspace_dormant:update",
 	"workspace_dormant:update_agent",
+	"workspace:extend",
 	"workspace_proxy:*",
 	"workspace_proxy:create",
 	"workspace_proxy:delete",
@@ -1082,6 +1102,79 @@ export interface AccessURLReport extends BaseReport {
 	readonly healthz_response: string;
 }
 
+// From codersdk/insights.go
+/**
+ * ActiveDeveloperDaysInsightsReport counts the distinct users with meaningful
+ * workspace activity per day, to measure the adoption of templates. A user is
+ * active on a day when their SSH, IDE, web terminal and app usage of a template
+ * adds up to at least MinActiveMinutes.
+ */
+export interface ActiveDeveloperDaysInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly organization_id?: string;
+	readonly min_active_minutes: number;
+	/**
+	 * Days has one entry per day and template with at least one active user.
+	 */
+	readonly days: readonly TemplateActiveDevelopers[];
+	/**
+	 * Templates sums the active developers of each template over the period.
+	 */
+	readonly templates: readonly TemplateActiveDeveloperDays[];
+	/**
+	 * Organizations sums the active developers of each organization over the
+	 * period. A user active in several templates of an organization on the
+	 * same day is counted once.
+	 */
+	readonly organizations: readonly OrganizationActiveDeveloperDays[];
+}
+
+// From codersdk/insights.go
+export interface ActiveDeveloperDaysInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly organization_id: string;
+	/**
+	 * MinActiveMinutes is the usage a user needs on a day to count as active.
+	 * Defaults to 1 minute when zero.
+	 */
+	readonly min_active_minutes: number;
+}
+
+// From codersdk/insights.go
+/**
+ * ActiveDeveloperDaysInsightsResponse is the response from the active
+ * developer-days insights endpoint.
+ */
+export interface ActiveDeveloperDaysInsightsResponse {
+	readonly report: ActiveDeveloperDaysInsightsReport;
+}
+
+// From codersdk/templates.go
+/**
+ * ActivityBumpConnectionType is a type of connection to a workspace that may
+ * bump its deadline.
+ */
+export type ActivityBumpConnectionType =
+	| "app"
+	| "chat"
+	| "jetbrains"
+	| "reconnecting_pty"
+	| "ssh"
+	| "vscode";
+
+export const ActivityBumpConnectionTypes: ActivityBumpConnectionType[] = [
+	"app",
+	"chat",
+	"jetbrains",
+	"reconnecting_pty",
+	"ssh",
+	"vscode",
+];
+
 // From codersdk/licenses.go
 export interface AddLicenseRequest {
 	readonly license: string;
@@ -1092,6 +1185,21 @@ export type Addon = "ai_governance";
 
 export const Addons: Addon[] = ["ai_governance"];
 
+// From codersdk/orphanedworkspaces.go
+/**
+ * AdoptOrphanedWorkspaceRequest transfers an orphaned workspace to a new
+ * owner.
+ */
+export interface AdoptOrphanedWorkspaceRequest {
+	readonly owner_id: string;
+	/**
+	 * Name is the name of the workspace after the transfer. It defaults to
+	 * the current name, and must be set when the new owner already has a
+	 * workspace with that name.
+	 */
+	readonly name?: string;
+}
+
 // From codersdk/chats.go
 /**
  * AdvisorConfig is the deployment-wide runtime configuration for the
@@ -1217,6 +1325,18 @@ export interface AgentFirewallSessionLogsResponse {
 	readonly results: readonly AgentFirewallLog[];
 }
 
+// From codersdk/agentrollout.go
+export type AgentRolloutChannel = "beta" | "stable";
+
+export const AgentRolloutChannels: AgentRolloutChannel[] = ["beta", "stable"];
+
+// From codersdk/agentrollout.go
+/**
+ * AgentRolloutChannelHeader is set on agent binary downloads to the channel
+ * the binary was served from.
+ */
+export const AgentRolloutChannelHeader = "X-Coder-Agent-Rollout-Channel";
+
 // From codersdk/workspacebuilds.go
 export interface AgentScriptTiming {
 	readonly started_at: string;
@@ -1229,6 +1349,21 @@ export interface AgentScriptTiming {
 	readonly workspace_agent_name: string;
 }
 
+// From codersdk/workspacebuilds.go
+/**
+ * AgentServiceTiming is the time a service supervised by an agent spent in a
+ * status. The span of the current status ends at the time of the request.
+ */
+export interface AgentServiceTiming {
+	readonly started_at: string;
+	readonly ended_at: string;
+	readonly status: WorkspaceAgentServiceStatus;
+	readonly service_name: string;
+	readonly restart_count: number;
+	readonly workspace_agent_id: string;
+	readonly workspace_agent_name: string;
+}
+
 // From codersdk/templates.go
 /**
  * AgentStatsReportResponse is returned for each report
@@ -1278,6 +1413,15 @@ export interface AppHostResponse {
 	readonly host: string;
 }
 
+// From codersdk/client.go
+/**
+ * AppShareLinkSessionCookie is the name of the cookie that stores the
+ * session of a visitor of a shared workspace app. It is only set on the
+ * subdomain of the shared app.
+ *nolint:gosec
+ */
+export const AppShareLinkSessionCookie = "coder_app_share_link_session";
+
 // From codersdk/deployment.go
 export interface AppearanceConfig {
 	readonly application_name: string;
@@ -1322,6 +1466,8 @@ export type AuditAction =
 	| "create"
 	| "delete"
 	| "disconnect"
+	| "download"
+	| "impersonate"
 	| "login"
 	| "logout"
 	| "open"
@@ -1329,6 +1475,7 @@ export type AuditAction =
 	| "request_password_reset"
 	| "start"
 	| "stop"
+	| "upload"
 	| "write";
 
 export const AuditActions: AuditAction[] = [
@@ -1337,6 +1484,8 @@ export const AuditActions: AuditAction[] = [
 	"create",
 	"delete",
 	"disconnect",
+	"download",
+	"impersonate",
 	"login",
 	"logout",
 	"open",
@@ -1344,6 +1493,7 @@ export const AuditActions: AuditAction[] = [
 	"request_password_reset",
 	"start",
 	"stop",
+	"upload",
 	"write",
 ];
 
@@ -1380,6 +1530,11 @@ export interface AuditLog {
 	readonly description: string;
 	readonly resource_link: string;
 	readonly is_deleted: boolean;
+	/**
+	 * CorrelationID is the ID of the workspace build this entry relates to.
+	 * All audit entries caused by the same build share this ID.
+	 */
+	readonly correlation_id?: string;
 	/**
 	 * @deprecated Use 'organization.id' instead.
 	 */
@@ -1498,6 +1653,92 @@ export type AutomaticUpdates = "always" | "never";
 
 export const AutomaticUpdateses: AutomaticUpdates[] = ["always", "never"];
 
+// From codersdk/insights.go
+/**
+ * AutostopInsightsReport quantifies the compute saved by scheduling policies,
+ * per template and organization. A workspace stopped by autostop, dormancy or
+ * the failure TTL is assumed to have kept running until it was started again
+ * without the policy.
+ */
+export interface AutostopInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly templates: readonly AutostopTemplateInsight[];
+	readonly organizations: readonly AutostopOrganizationInsight[];
+}
+
+// From codersdk/insights.go
+export interface AutostopInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+}
+
+// From codersdk/insights.go
+/**
+ * AutostopInsightsResponse is the response from the autostop insights
+ * endpoint.
+ */
+export interface AutostopInsightsResponse {
+	readonly report: AutostopInsightsReport;
+}
+
+// From codersdk/insights.go
+/**
+ * AutostopOrganizationInsight is the compute saved for the workspaces of the
+ * reported templates of a single organization.
+ */
+export interface AutostopOrganizationInsight extends AutostopSavings {
+	readonly organization_id: string;
+	readonly organization_name: string;
+}
+
+// From codersdk/insights.go
+/**
+ * AutostopSavings is the compute time of workspaces during the report period,
+ * in hours.
+ */
+export interface AutostopSavings {
+	/**
+	 * RunningHours is the time workspaces were running.
+	 */
+	readonly running_hours: number;
+	/**
+	 * AutostopHours, DormancyHours and FailureTTLHours are the time workspaces
+	 * were stopped by autostop, dormancy and the failure TTL, respectively.
+	 */
+	readonly autostop_hours: number;
+	readonly dormancy_hours: number;
+	readonly failure_ttl_hours: number;
+	/**
+	 * SavedHours is the time workspaces were stopped by any of the policies.
+	 */
+	readonly saved_hours: number;
+	/**
+	 * WouldHaveRunHours is the time workspaces would have run without the
+	 * policies, the sum of RunningHours and SavedHours.
+	 */
+	readonly would_have_run_hours: number;
+	/**
+	 * SavedRatio is SavedHours divided by WouldHaveRunHours, between 0 and 1.
+	 */
+	readonly saved_ratio: number;
+}
+
+// From codersdk/insights.go
+/**
+ * AutostopTemplateInsight is the compute saved for the workspaces of a single
+ * template.
+ */
+export interface AutostopTemplateInsight extends AutostopSavings {
+	readonly organization_id: string;
+	readonly organization_name: string;
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly template_display_name: string;
+}
+
 // From codersdk/deployment.go
 /**
  * AvailableExperiments is an expandable type that returns all safe experiments
@@ -1576,31 +1817,39 @@ export interface BuildInfoResponse {
 
 // From codersdk/workspacebuilds.go
 export type BuildReason =
+	| "archive"
 	| "autostart"
 	| "autostop"
+	| "budget_exceeded"
 	| "cli"
 	| "dashboard"
 	| "dormancy"
 	| "initiator"
 	| "jetbrains_connection"
+	| "self_requested"
 	| "ssh_connection"
 	| "task_auto_pause"
 	| "task_manual_pause"
 	| "task_resume"
+	| "unarchive"
 	| "vscode_connection";
 
 export const BuildReasons: BuildReason[] = [
+	"archive",
 	"autostart",
 	"autostop",
+	"budget_exceeded",
 	"cli",
 	"dashboard",
 	"dormancy",
 	"initiator",
 	"jetbrains_connection",
+	"self_requested",
 	"ssh_connection",
 	"task_auto_pause",
 	"task_manual_pause",
 	"task_resume",
+	"unarchive",
 	"vscode_connection",
 ];
 
@@ -1647,6 +1896,68 @@ export const CancelWorkspaceBuildStatuses: CancelWorkspaceBuildStatus[] = [
 	"running",
 ];
 
+// From codersdk/capabilities.go
+/**
+ * Capabilities is a machine-readable description of what the deployment
+ * supports. Clients should prefer it over inspecting error messages to
+ * detect whether a feature is available.
+ */
+export interface Capabilities {
+	/**
+	 * APIVersions lists the versions of the API surfaces served by the
+	 * deployment.
+	 */
+	readonly api_versions: CapabilitiesAPIVersions;
+	/**
+	 * Experiments lists the enabled experiments.
+	 */
+	readonly experiments: readonly Experiment[];
+	/**
+	 * Features maps every licensed feature to whether it is enabled.
+	 */
+	readonly features: Record<FeatureName, boolean>;
+	/**
+	 * Flags maps every capability flag to whether it is enabled.
+	 */
+	readonly flags: Record<CapabilityFlag, boolean>;
+}
+
+// From codersdk/capabilities.go
+export interface CapabilitiesAPIVersions {
+	/**
+	 * Server is the version of the Coder server.
+	 */
+	readonly server: string;
+	/**
+	 * Agent is the current version of the agent API.
+	 */
+	readonly agent: string;
+	/**
+	 * Provisioner is the current version of the provisioner daemon API.
+	 */
+	readonly provisioner: string;
+	/**
+	 * Tailnet is the current version of the tailnet coordination API.
+	 */
+	readonly tailnet: string;
+}
+
+// From codersdk/capabilities.go
+export type CapabilityFlag =
+	| "dynamic_parameters"
+	| "path_apps"
+	| "prebuilds"
+	| "subdomain_apps"
+	| "workspace_sharing";
+
+export const CapabilityFlags: CapabilityFlag[] = [
+	"dynamic_parameters",
+	"path_apps",
+	"prebuilds",
+	"subdomain_apps",
+	"workspace_sharing",
+];
+
 // From codersdk/users.go
 /**
  * ChangePasswordWithOneTimePasscodeRequest enables callers to change their password when they've forgotten it.
@@ -3473,6 +3784,16 @@ export interface ChatWorkspaceTTLResponse {
 	readonly workspace_ttl_ms: number;
 }
 
+// From codersdk/workspacecloudresources.go
+export type CloudResourceVerificationStatus =
+	| "deleted"
+	| "leaked"
+	| "unknown"
+	| "unverified";
+
+export const CloudResourceVerificationStatuses: CloudResourceVerificationStatus[] =
+	["deleted", "leaked", "unknown", "unverified"];
+
 // From codersdk/deployment.go
 export interface ClusterConfig {
 	readonly host: string;
@@ -3857,6 +4178,22 @@ export interface CreateProvisionerKeyResponse {
 	readonly key: string;
 }
 
+// From codersdk/sharedvolumes.go
+/**
+ * CreateSharedVolumeRequest declares a shared volume ahead of the templates
+ * that attach it. Templates attach volumes by name, and create them on their
+ * first build otherwise.
+ */
+export interface CreateSharedVolumeRequest {
+	readonly name: string;
+	readonly display_name?: string;
+	readonly description?: string;
+	/**
+	 * TemplateID associates the volume with a template of the organization.
+	 */
+	readonly template_id?: string;
+}
+
 // From codersdk/aitasks.go
 /**
  * CreateTaskRequest represents the request to create a new task.
@@ -3990,6 +4327,50 @@ export interface CreateTemplateRequest {
 	 * CORSBehavior allows optionally specifying the CORS behavior for all shared ports.
 	 */
 	readonly cors_behavior: CORSBehavior | null;
+	/**
+	 * ProvisionerPlanTimeoutMillis allows optionally limiting the duration of
+	 * template version import and dry-run jobs for the template.
+	 */
+	readonly provisioner_plan_timeout_ms?: number;
+	/**
+	 * ProvisionerApplyTimeoutMillis allows optionally limiting the duration of
+	 * workspace build jobs for the template.
+	 */
+	readonly provisioner_apply_timeout_ms?: number;
+	/**
+	 * TrialWorkspaceTTLMillis allows optionally giving workspaces created from
+	 * the template a hard lifetime, after which they are stopped and then
+	 * deleted regardless of activity.
+	 */
+	readonly trial_workspace_ttl_ms?: number;
+}
+
+// From codersdk/templatesecrets.go
+export interface CreateTemplateSecretRequest {
+	/**
+	 * Name is the environment variable the secret is exposed as. It must be
+	 * a valid environment variable name and can't start with CODER_.
+	 */
+	readonly name: string;
+	readonly description?: string;
+	readonly value: string;
+}
+
+// From codersdk/templateversions.go
+/**
+ * CreateTemplateVersionDryRunMatrixRequest defines the request parameters for
+ * CreateTemplateVersionDryRunMatrix.
+ */
+export interface CreateTemplateVersionDryRunMatrixRequest {
+	readonly workspace_name: string;
+	readonly rich_parameter_values: readonly WorkspaceBuildParameter[];
+	/**
+	 * ProvisionerTags lists the tags of each group of provisioner daemons to
+	 * run a dry-run on. They are added to the tags of the template version.
+	 * If empty, a dry-run is run on every distinct set of tags of the
+	 * provisioner daemons able to build the template version.
+	 */
+	readonly provisioner_tags?: readonly Record<string, string>[];
 }
 
 // From codersdk/templateversions.go
@@ -4022,6 +4403,80 @@ export interface CreateTemplateVersionRequest {
 	readonly user_variable_values?: readonly VariableValue[];
 }
 
+// From codersdk/templateversionrollouts.go
+/**
+ * CreateTemplateVersionRolloutRequest promotes a template version in stages.
+ * Each stage targets a larger share of the workspaces of the template and is
+ * observed for the soak duration. The version is promoted to the active
+ * version after the last stage, unless the failure rate of its builds exceeds
+ * the maximum first, in which case the rollout is rolled back.
+ */
+export interface CreateTemplateVersionRolloutRequest {
+	readonly template_version_id: string;
+	/**
+	 * GroupID is a group whose members' workspaces are part of every stage,
+	 * e.g. a group of early adopters.
+	 */
+	readonly group_id?: string;
+	/**
+	 * Stages are the percentages of workspaces targeted by each stage, in
+	 * ascending order. A rollout without stages only targets the group.
+	 */
+	readonly stages?: readonly number[];
+	/**
+	 * SoakDurationMillis is how long each stage is observed before the
+	 * rollout continues.
+	 */
+	readonly soak_duration_ms: number;
+	/**
+	 * MaxFailureRate is the share of failed start builds of the version, from
+	 * 0 to 1, above which the rollout is rolled back.
+	 */
+	readonly max_failure_rate: number;
+	/**
+	 * MinBuilds is the number of completed start builds in a stage before its
+	 * failure rate is considered.
+	 */
+	readonly min_builds?: number;
+}
+
+// From codersdk/templateworkspacerestarts.go
+/**
+ * CreateTemplateWorkspaceRestartRequest restarts the outdated running
+ * workspaces of a template on its active version, in batches. A workspace is
+ * outdated if its latest build does not use the active version. The restart
+ * is paused once the failure rate of its builds exceeds the maximum, and
+ * aborted if the active version changes before every workspace is restarted.
+ */
+export interface CreateTemplateWorkspaceRestartRequest {
+	/**
+	 * BatchSize is the maximum number of workspaces restarted at once.
+	 */
+	readonly batch_size: number;
+	/**
+	 * BatchIntervalMillis is the minimum time between two batches. The next
+	 * batch is also only started once the builds of the previous batch are
+	 * completed.
+	 */
+	readonly batch_interval_ms: number;
+	/**
+	 * HonorQuietHours only restarts workspaces during the quiet hours of
+	 * their owner. Quiet hours are only available with a premium license,
+	 * without one workspaces can be restarted at any time.
+	 */
+	readonly honor_quiet_hours: boolean;
+	/**
+	 * MaxFailureRate is the share of failed restarts, from 0 to 1, above
+	 * which the restart is paused.
+	 */
+	readonly max_failure_rate: number;
+	/**
+	 * MinBuilds is the number of completed restarts before the failure rate
+	 * is considered.
+	 */
+	readonly min_builds?: number;
+}
+
 // From codersdk/audit.go
 export interface CreateTestAuditLogRequest {
 	readonly action?: AuditAction;
@@ -4032,6 +4487,7 @@ export interface CreateTestAuditLogRequest {
 	readonly build_reason?: BuildReason;
 	readonly organization_id?: string;
 	readonly request_id?: string;
+	readonly correlation_id?: string;
 }
 
 // From codersdk/apikey.go
@@ -4041,6 +4497,21 @@ export interface CreateTokenRequest {
 	readonly scopes?: readonly APIKeyScope[];
 	readonly token_name: string;
 	readonly allow_list?: readonly APIAllowListTarget[];
+	/**
+	 * TemplateIDs restricts the token to workspaces built from the given
+	 * templates. Creating, reading, starting, and stopping workspaces from
+	 * any other template is rejected. When set without Scopes, the token
+	 * defaults to the "coder:workspaces.create" scope rather than
+	 * "coder:all".
+	 */
+	readonly template_ids?: readonly string[];
+	/**
+	 * WorkspaceID restricts the token to a single workspace owned by the
+	 * token's user. The token can only read, build, and connect to that
+	 * workspace, and can only be created by the workspace owner. It cannot
+	 * be combined with Scopes, AllowList, or TemplateIDs.
+	 */
+	readonly workspace_id?: string;
 }
 
 // From codersdk/chats.go
@@ -4061,6 +4532,20 @@ export interface CreateUserChatProviderKeyRequest {
 	readonly api_key: string;
 }
 
+// From codersdk/templateuserpresets.go
+/**
+ * CreateUserPresetRequest saves a named set of parameter values for a
+ * template. Saving a preset with the name of an existing one replaces its
+ * parameters.
+ */
+export interface CreateUserPresetRequest {
+	readonly name: string;
+	/**
+	 * Parameters are validated against the active version of the template.
+	 */
+	readonly parameters: readonly WorkspaceBuildParameter[];
+}
+
 // From codersdk/users.go
 export interface CreateUserRequestWithOrgs {
 	readonly email: string;
@@ -4116,6 +4601,46 @@ export interface CreateUserSkillRequest {
 	readonly content: string;
 }
 
+// From codersdk/vcseventmappings.go
+export interface CreateVCSEventMappingRequest {
+	readonly name: string;
+	readonly repository: string;
+	readonly branch?: string;
+	readonly action: VCSEventAction;
+	/**
+	 * WorkspaceID is the workspace started by the start_workspace action. It
+	 * must be a workspace of the template.
+	 */
+	readonly workspace_id?: string;
+	readonly signing_secret: string;
+}
+
+// From codersdk/workspaceappsharelinks.go
+/**
+ * CreateWorkspaceAppShareLinkRequest shares a subdomain app of the workspace.
+ */
+export interface CreateWorkspaceAppShareLinkRequest {
+	/**
+	 * AgentName is not required if the workspace has only one agent.
+	 */
+	readonly agent_name?: string;
+	readonly app_slug: string;
+	/**
+	 * Lifetime defaults to one day, and may not exceed seven days.
+	 */
+	readonly lifetime?: number;
+}
+
+// From codersdk/workspaceappsharelinks.go
+/**
+ * CreateWorkspaceAppShareLinkResponse is returned when creating a share link.
+ * URL contains the secret of the link and is only returned once.
+ */
+export interface CreateWorkspaceAppShareLinkResponse {
+	readonly share_link: WorkspaceAppShareLink;
+	readonly url: string;
+}
+
 // From codersdk/workspaces.go
 /**
  * CreateWorkspaceBuildOnSuccessRequest queues a follow-up build that
@@ -4151,23 +4676,37 @@ export interface CreateWorkspaceBuildOnSuccessRequest {
 	readonly template_version_preset_id?: string;
 }
 
-// From codersdk/workspaces.go
-export type CreateWorkspaceBuildReason =
+// From codersdk/workspacebuilds.go
+/**
+ * CreateWorkspaceBuildAnnotationRequest attaches a note to a workspace build.
+ */
+export interface CreateWorkspaceBuildAnnotationRequest {
+	readonly note: string;
+}
+
+// From codersdk/workspaces.go
+export type CreateWorkspaceBuildReason =
+	| "archive"
 	| "cli"
 	| "dashboard"
 	| "jetbrains_connection"
+	| "self_requested"
 	| "ssh_connection"
 	| "task_manual_pause"
 	| "task_resume"
+	| "unarchive"
 	| "vscode_connection";
 
 export const CreateWorkspaceBuildReasons: CreateWorkspaceBuildReason[] = [
+	"archive",
 	"cli",
 	"dashboard",
 	"jetbrains_connection",
+	"self_requested",
 	"ssh_connection",
 	"task_manual_pause",
 	"task_resume",
+	"unarchive",
 	"vscode_connection",
 ];
 
@@ -4208,6 +4747,56 @@ export interface CreateWorkspaceBuildRequest {
 	 * successful stop build.
 	 */
 	readonly on_success?: CreateWorkspaceBuildOnSuccessRequest;
+	/**
+	 * TargetResources limits a start build to the listed Terraform resource
+	 * addresses, e.g. "docker_container.workspace[0]". Each is destroyed and
+	 * recreated, while every other resource, such as volumes, keeps its
+	 * current state. The template must allow targeted builds.
+	 */
+	readonly target_resources?: readonly string[];
+	/**
+	 * IdempotencyKey makes retries of the request with the same key return
+	 * the build created by the original request for 24 hours, instead of
+	 * queuing another one. It may also be set with the Idempotency-Key
+	 * header.
+	 */
+	readonly idempotency_key?: string;
+}
+
+// From codersdk/workspaceleases.go
+/**
+ * CreateWorkspaceLeaseRequest creates an ephemeral workspace from a template
+ * that is deleted once the lease expires or is released. If the preset has
+ * prebuilt workspaces, one of them is claimed instead of building a new one.
+ */
+export interface CreateWorkspaceLeaseRequest {
+	/**
+	 * Name is the name of the leased workspace. A random name is generated if
+	 * empty.
+	 */
+	readonly name?: string;
+	/**
+	 * TTLMillis is the lifetime of the lease. It may not exceed the
+	 * deployment's maximum lease TTL.
+	 */
+	readonly ttl_ms: number;
+	readonly template_version_preset_id?: string;
+	readonly rich_parameter_values?: readonly WorkspaceBuildParameter[];
+}
+
+// From codersdk/workspaceleases.go
+/**
+ * CreateWorkspaceLeaseResponse is returned when a lease is created. The
+ * session token is only returned once.
+ */
+export interface CreateWorkspaceLeaseResponse {
+	readonly lease: WorkspaceLease;
+	readonly workspace: Workspace;
+	/**
+	 * SessionToken is an API token that may only access the leased
+	 * workspace, e.g. via `coder ssh`. It expires with the lease.
+	 */
+	readonly session_token: string;
 }
 
 // From codersdk/workspaceproxy.go
@@ -4251,6 +4840,38 @@ export interface CreateWorkspaceRequest {
 	readonly rich_parameter_values?: readonly WorkspaceBuildParameter[];
 	readonly automatic_updates?: AutomaticUpdates;
 	readonly template_version_preset_id?: string;
+	/**
+	 * UserPresetID applies the parameter values of a preset the user saved
+	 * for the template. Values in RichParameterValues take precedence.
+	 */
+	readonly user_preset_id?: string;
+	/**
+	 * RegionLatenciesMS are the latencies in milliseconds the client measured
+	 * to each region, keyed by region ID. If the template has
+	 * auto_assign_region set, they are used to assign a region to the
+	 * workspace.
+	 */
+	readonly region_latencies_ms?: Record<string, number>;
+	/**
+	 * IdempotencyKey makes retries of the request with the same key return
+	 * the workspace created by the original request for 24 hours, instead
+	 * of creating another one. It may also be set with the Idempotency-Key
+	 * header.
+	 */
+	readonly idempotency_key?: string;
+}
+
+// From codersdk/workspacesupportbundles.go
+/**
+ * CreateWorkspaceSupportBundleRequest is the request to collect a support
+ * bundle from the agents of a workspace.
+ */
+export interface CreateWorkspaceSupportBundleRequest {
+	/**
+	 * Message describes the problem. It is included in the notification sent
+	 * to administrators.
+	 */
+	readonly message?: string;
 }
 
 // From codersdk/deployment.go
@@ -4266,6 +4887,7 @@ export interface CryptoKey {
 export type CryptoKeyFeature =
 	| "nats_ca"
 	| "oidc_convert"
+	| "ssh_host_ca"
 	| "tailnet_resume"
 	| "workspace_apps_api_key"
 	| "workspace_apps_token";
@@ -4273,6 +4895,7 @@ export type CryptoKeyFeature =
 export const CryptoKeyFeatures: CryptoKeyFeature[] = [
 	"nats_ca",
 	"oidc_convert",
+	"ssh_host_ca",
 	"tailnet_resume",
 	"workspace_apps_api_key",
 	"workspace_apps_token",
@@ -4538,12 +5161,26 @@ export interface DeploymentValues {
 	readonly wildcard_access_url?: string;
 	readonly docs_url?: string;
 	readonly redirect_to_access_url?: boolean;
+	/**
+	 * WorkspaceDNSDomain and WorkspaceDNSProviderURL configure the registration
+	 * of a stable DNS name for every running workspace.
+	 */
+	readonly workspace_dns_domain?: string;
+	readonly workspace_dns_provider_url?: string;
 	/**
 	 * HTTPAddress is a string because it may be set to zero to disable.
 	 */
 	readonly http_address?: string;
 	readonly autobuild_poll_interval?: number;
 	readonly job_hang_detector_interval?: number;
+	readonly workspace_monthly_cost_budget?: number;
+	readonly user_monthly_cost_budget?: number;
+	readonly workspace_dormancy_hook_url?: string;
+	readonly workspace_lease_max_per_token?: number;
+	readonly workspace_lease_max_ttl?: number;
+	readonly template_default_ttl_min?: number;
+	readonly template_default_ttl_max?: number;
+	readonly template_activity_bump_max?: number;
 	readonly cluster?: ClusterConfig;
 	readonly derp?: DERP;
 	readonly prometheus?: PrometheusConfig;
@@ -4568,6 +5205,8 @@ export interface DeploymentValues {
 	readonly metrics_cache_refresh_interval?: number;
 	readonly agent_stat_refresh_interval?: number;
 	readonly agent_fallback_troubleshooting_url?: string;
+	readonly agent_beta_binaries_dir?: string;
+	readonly agent_beta_rollout_percent?: number;
 	readonly browser_only?: boolean;
 	readonly scim_api_key?: string;
 	readonly scim_use_legacy?: boolean;
@@ -4616,6 +5255,31 @@ export interface DeploymentValues {
 	readonly address?: string;
 }
 
+// From codersdk/templateversions.go
+export type DeprecationSubject = "template" | "template_version";
+
+export const DeprecationSubjects: DeprecationSubject[] = [
+	"template",
+	"template_version",
+];
+
+// From codersdk/templateversions.go
+/**
+ * DeprecationWarning is returned with workspaces and builds that use a
+ * deprecated template or template version.
+ */
+export interface DeprecationWarning {
+	readonly subject: DeprecationSubject;
+	readonly id: string;
+	readonly name: string;
+	readonly message: string;
+	/**
+	 * CutoffAt is the time after which the template or version can no
+	 * longer be used for new workspaces. If unset, it already cannot.
+	 */
+	readonly cutoff_at?: string;
+}
+
 // From codersdk/parameters.go
 export interface DiagnosticExtra {
 	readonly code: string;
@@ -4771,6 +5435,12 @@ export interface EditChatMessageResponse {
 	readonly warnings?: readonly string[];
 }
 
+// From codersdk/insights.go
+export interface EgressBytes {
+	readonly rx_bytes: number;
+	readonly tx_bytes: number;
+}
+
 // From codersdk/externalauth.go
 export type EnhancedExternalAuthProvider =
 	| "azure-devops"
@@ -5324,6 +5994,9 @@ export type HealthCode =
 	| "EWS02"
 	| "EWS03";
 
+// From health/model.go
+export const HealthCodeAgentClockSkew = "ECLK01";
+
 // From health/model.go
 export const HealthCodeInterfaceSmallMTU = "EIF01";
 
@@ -5459,6 +6132,47 @@ export interface IDPSyncMapping<ResourceIdType extends string> {
 	readonly Gets: ResourceIdType;
 }
 
+// From codersdk/client.go
+/**
+ * IdempotencyKeyHeader contains a client-generated key that makes
+ * retries of a workspace or workspace build creation request return the
+ * result of the original request instead of creating another one.
+ */
+export const IdempotencyKeyHeader = "Idempotency-Key";
+
+// From codersdk/userimpersonations.go
+/**
+ * ImpersonateUserRequest requests a token to act as a user.
+ */
+export interface ImpersonateUserRequest {
+	/**
+	 * Reason is recorded in the audit log and shown to the impersonated user.
+	 * It is required unless an earlier request is redeemed.
+	 */
+	readonly reason?: string;
+	/**
+	 * Lifetime defaults to one hour, and may not exceed the maximum
+	 * impersonation lifetime of the deployment.
+	 */
+	readonly lifetime?: number;
+	/**
+	 * ImpersonationID redeems an earlier request that the impersonated user
+	 * has consented to. Reason and Lifetime are taken from that request.
+	 */
+	readonly impersonation_id?: string;
+}
+
+// From codersdk/userimpersonations.go
+/**
+ * ImpersonateUserResponse is returned when impersonating a user. Key is only
+ * set once the request is granted, which requires the consent of the
+ * impersonated user when the deployment enforces it.
+ */
+export interface ImpersonateUserResponse {
+	readonly impersonation: UserImpersonation;
+	readonly key?: string;
+}
+
 // From codersdk/usersecrets.go
 /**
  * ImportUserSecretsRequest is the payload for the bulk secret import
@@ -5469,6 +6183,28 @@ export interface ImportUserSecretsRequest {
 	readonly content: string;
 }
 
+// From codersdk/workspaces.go
+/**
+ * ImportWorkspaceStateRequest creates a workspace that adopts existing
+ * infrastructure described by a Terraform state file.
+ */
+export interface ImportWorkspaceStateRequest {
+	/**
+	 * OwnerID is the user the workspace is created for. Defaults to the
+	 * authenticated user.
+	 */
+	readonly owner_id?: string;
+	/**
+	 * Workspace specifies the template, name and parameters of the workspace.
+	 */
+	readonly workspace: CreateWorkspaceRequest;
+	/**
+	 * State is the Terraform state file. Every managed resource in the state
+	 * must be declared by the template version.
+	 */
+	readonly state: string;
+}
+
 // From codersdk/inboxnotification.go
 export interface InboxNotification {
 	readonly id: string;
@@ -5536,13 +6272,36 @@ export interface IssueReconnectingPTYSignedTokenResponse {
 }
 
 // From codersdk/provisionerdaemons.go
-export type JobErrorCode = "INSUFFICIENT_QUOTA" | "REQUIRED_TEMPLATE_VARIABLES";
+export type JobErrorCode =
+	| "INSUFFICIENT_QUOTA"
+	| "JOB_HUNG"
+	| "JOB_PENDING_TIMEOUT"
+	| "PROVISIONER_LOST"
+	| "REQUIRED_TEMPLATE_VARIABLES";
 
 export const JobErrorCodes: JobErrorCode[] = [
 	"INSUFFICIENT_QUOTA",
+	"JOB_HUNG",
+	"JOB_PENDING_TIMEOUT",
+	"PROVISIONER_LOST",
 	"REQUIRED_TEMPLATE_VARIABLES",
 ];
 
+// From codersdk/workspacecloudresources.go
+/**
+ * LeakedCloudResource is a cloud resource that still existed at its cloud
+ * provider after the workspace that created it was deleted.
+ */
+export interface LeakedCloudResource {
+	readonly resource: WorkspaceCloudResource;
+	readonly workspace_name: string;
+	readonly owner_id: string;
+	readonly owner_name: string;
+	readonly organization_id: string;
+	readonly template_id: string;
+	readonly template_name: string;
+}
+
 // From codersdk/licenses.go
 export interface License {
 	readonly id: number;
@@ -5577,6 +6336,76 @@ export const LicenseManagedAgentLimitExceededWarningText =
 export const LicenseTelemetryRequiredErrorText =
 	"License requires telemetry but telemetry is disabled";
 
+// From codersdk/debug.go
+/**
+ * LifecycleExecutorDeferReason is the reason the lifecycle executor could not
+ * run a transition that was due.
+ */
+export type LifecycleExecutorDeferReason =
+	| "cost_budget_exceeded"
+	| "no_provisioners";
+
+export const LifecycleExecutorDeferReasons: LifecycleExecutorDeferReason[] = [
+	"cost_budget_exceeded",
+	"no_provisioners",
+];
+
+// From codersdk/debug.go
+/**
+ * LifecycleExecutorDeferral is a workspace with a transition due that the
+ * lifecycle executor could not attempt.
+ */
+export interface LifecycleExecutorDeferral {
+	readonly workspace_id: string;
+	readonly reason: LifecycleExecutorDeferReason;
+}
+
+// From codersdk/debug.go
+/**
+ * LifecycleExecutorFailure is a workspace the lifecycle executor failed to
+ * evaluate or transition.
+ */
+export interface LifecycleExecutorFailure {
+	readonly workspace_id: string;
+	/**
+	 * BuildReason is the reason of the transition that failed. It is empty
+	 * when the workspace failed before a transition was attempted.
+	 */
+	readonly build_reason?: BuildReason;
+	readonly error: string;
+}
+
+// From codersdk/debug.go
+/**
+ * LifecycleExecutorStatus describes the most recent run of the lifecycle
+ * executor, which autostarts, autostops, and marks workspaces dormant. Every
+ * replica runs its own executor, so the status is that of the replica that
+ * served the request.
+ */
+export interface LifecycleExecutorStatus {
+	readonly replica_id: string;
+	/**
+	 * LastTickAt is the time the last run started. It is unset until the
+	 * executor has completed its first run.
+	 */
+	readonly last_tick_at?: string;
+	readonly last_tick_duration_ms: number;
+	readonly workspaces_evaluated: number;
+	readonly transitions_attempted: number;
+	readonly transitions_succeeded: number;
+	readonly transitions_failed: number;
+	/**
+	 * Failures lists the workspaces the last run failed to evaluate or
+	 * transition.
+	 */
+	readonly failures: readonly LifecycleExecutorFailure[];
+	/**
+	 * Deferred lists the workspaces with a transition due that the last run
+	 * could not attempt. They are retried on the next run.
+	 */
+	readonly deferred: readonly LifecycleExecutorDeferral[];
+}
+
 // From codersdk/deployment.go
 export interface LinkConfig {
 	readonly name: string;
@@ -5789,6 +6618,15 @@ export const MaxChatFileIDs = 50;
  */
 export const MaxChatFileSizeBytes = 10485760;
 
+// From codersdk/templates.go
+/**
+ * MaxLifetimeAction is what happens to workspaces that exceed the max
+ * lifetime of their template.
+ */
+export type MaxLifetimeAction = "delete" | "stop";
+
+export const MaxLifetimeActions: MaxLifetimeAction[] = ["delete", "stop"];
+
 // From codersdk/usersecretsimport.go
 /**
  * MaxSecretsFileBytes bounds the raw size of a secrets file before parsing.
@@ -5926,6 +6764,12 @@ export interface ModelCostConfig {
 	readonly cache_write_price_per_million_tokens?: string;
 }
 
+// From codersdk/name.go
+/**
+ * NameMaxLength is the maximum length of a name accepted by NameValid.
+ */
+export const NameMaxLength = 32;
+
 // From netcheck/netcheck.go
 /**
  * Report contains the result of a single netcheck.
@@ -5977,6 +6821,72 @@ export interface NetcheckReport {
 	readonly CaptivePortal: boolean | null;
 }
 
+// From codersdk/networkpolicies.go
+export type NetworkPolicySource = "none" | "template" | "workspace";
+
+export const NetworkPolicySources: NetworkPolicySource[] = [
+	"none",
+	"template",
+	"workspace",
+];
+
+// From codersdk/notificationwebhooks.go
+/**
+ * NotificationMessageDeliveriesRequest filters the messages returned by
+ * NotificationTemplateDeliveries.
+ */
+export interface NotificationMessageDeliveriesRequest {
+	/**
+	 * Method only returns the messages delivered by the given method.
+	 */
+	readonly Method: string;
+	/**
+	 * Limit defaults to 50 and may not exceed 500.
+	 */
+	readonly Limit: number;
+}
+
+// From codersdk/notificationwebhooks.go
+/**
+ * NotificationMessageDelivery is the delivery status of a notification
+ * message. Messages failing temporarily are retried until the deployment
+ * maximum number of send attempts is reached.
+ */
+export interface NotificationMessageDelivery {
+	readonly id: string;
+	readonly user_id: string;
+	readonly method: string;
+	readonly status: NotificationMessageStatus;
+	readonly status_reason?: string;
+	readonly attempt_count: number;
+	readonly created_at: string;
+	readonly updated_at?: string;
+	/**
+	 * NextRetryAfter is set when a failed delivery will be retried.
+	 */
+	readonly next_retry_after?: string;
+}
+
+// From codersdk/notificationwebhooks.go
+export type NotificationMessageStatus =
+	| "inhibited"
+	| "leased"
+	| "pending"
+	| "permanent_failure"
+	| "sent"
+	| "temporary_failure"
+	| "unknown";
+
+export const NotificationMessageStatuses: NotificationMessageStatus[] = [
+	"inhibited",
+	"leased",
+	"pending",
+	"permanent_failure",
+	"sent",
+	"temporary_failure",
+	"unknown",
+];
+
 // From codersdk/notifications.go
 export interface NotificationMethodsResponse {
 	readonly available: readonly string[];
@@ -5990,6 +6900,18 @@ export interface NotificationPreference {
 	readonly updated_at: string;
 }
 
+// From codersdk/notifications.go
+/**
+ * NotificationPreferenceScope is the kind of resource that a scoped
+ * notification preference applies to.
+ */
+export type NotificationPreferenceScope = "template" | "workspace";
+
+export const NotificationPreferenceScopes: NotificationPreferenceScope[] = [
+	"template",
+	"workspace",
+];
+
 // From codersdk/notifications.go
 export interface NotificationTemplate {
 	readonly id: string;
@@ -6003,6 +6925,31 @@ export interface NotificationTemplate {
 	readonly enabled_by_default: boolean;
 }
 
+// From codersdk/notificationwebhooks.go
+/**
+ * NotificationTemplateWebhook overrides how the messages of a notification
+ * template are delivered by the webhook method.
+ */
+export interface NotificationTemplateWebhook {
+	readonly notification_template_id: string;
+	/**
+	 * Endpoint receives the messages of the template. Empty uses the
+	 * deployment webhook endpoint.
+	 */
+	readonly endpoint: string;
+	/**
+	 * PayloadTemplate is a Go template rendering the request body from the
+	 * default webhook payload. Empty sends the default payload.
+	 */
+	readonly payload_template: string;
+	/**
+	 * HasSigningSecret is true when request bodies are signed. The secret
+	 * itself is never returned.
+	 */
+	readonly has_signing_secret: boolean;
+	readonly updated_at: string;
+}
+
 // From codersdk/deployment.go
 export interface NotificationsConfig {
 	/**
@@ -6614,6 +7561,90 @@ export interface OIDCConfig {
 	readonly redirect_allowed_hosts: string;
 }
 
+// From codersdk/offboarding.go
+/**
+ * OffboardingPreview lists everything that is affected by offboarding the
+ * selected users. Nothing is changed by a preview.
+ */
+export interface OffboardingPreview {
+	readonly generated_at: string;
+	readonly users: readonly OffboardingPreviewUser[];
+}
+
+// From codersdk/offboarding.go
+export interface OffboardingPreviewExternalAuthLink {
+	readonly provider_id: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/offboarding.go
+/**
+ * OffboardingPreviewRequest selects the users to preview offboarding for.
+ * Users can be selected by ID, by the membership of a group, such as a group
+ * synced from the identity provider, or both.
+ */
+export interface OffboardingPreviewRequest {
+	readonly user_ids?: readonly string[];
+	readonly group_id?: string;
+}
+
+// From codersdk/offboarding.go
+export interface OffboardingPreviewTemplate {
+	readonly id: string;
+	readonly name: string;
+	readonly display_name: string;
+	readonly organization_id: string;
+}
+
+// From codersdk/offboarding.go
+export interface OffboardingPreviewToken {
+	readonly id: string;
+	readonly token_name: string;
+	readonly last_used: string;
+	readonly expires_at: string;
+}
+
+// From codersdk/offboarding.go
+/**
+ * OffboardingPreviewUser lists the resources of a single user that are
+ * affected by offboarding them.
+ */
+export interface OffboardingPreviewUser {
+	readonly user_id: string;
+	readonly username: string;
+	readonly email: string;
+	readonly status: UserStatus;
+	/**
+	 * Workspaces are the workspaces owned by the user.
+	 */
+	readonly workspaces: readonly OffboardingPreviewWorkspace[];
+	/**
+	 * Templates are the templates created by the user.
+	 */
+	readonly templates: readonly OffboardingPreviewTemplate[];
+	/**
+	 * Tokens are the unexpired API tokens of the user.
+	 */
+	readonly tokens: readonly OffboardingPreviewToken[];
+	/**
+	 * ExternalAuthLinks are the external auth providers, such as git
+	 * providers, the user has linked.
+	 */
+	readonly external_auth_links: readonly OffboardingPreviewExternalAuthLink[];
+}
+
+// From codersdk/offboarding.go
+export interface OffboardingPreviewWorkspace {
+	readonly id: string;
+	readonly name: string;
+	readonly organization_name: string;
+	readonly template_name: string;
+	readonly latest_build_transition: WorkspaceTransition;
+	readonly last_used_at: string;
+	readonly dormant_at?: string;
+}
+
 // From codersdk/parameters.go
 export type OptionType = "bool" | "list(string)" | "number" | "string";
 
@@ -6641,6 +7672,13 @@ export interface Organization extends MinimalOrganization {
 	readonly default_org_member_roles: readonly string[];
 }
 
+// From codersdk/insights.go
+export interface OrganizationActiveDeveloperDays {
+	readonly organization_id: string;
+	readonly organization_name: string;
+	readonly active_developer_days: number;
+}
+
 // From codersdk/aibridge.go
 /**
  * OrganizationGroupAISpend is the current AI spend snapshot for a group
@@ -6743,7 +7781,40 @@ export interface OrganizationSyncSettings {
 	readonly organization_assign_default: boolean;
 }
 
-// From codersdk/organizations.go
+// From codersdk/orphanedworkspaces.go
+/**
+ * OrphanedWorkspace is a workspace whose owner was removed outside of the
+ * normal offboarding flow. It keeps its resources until it is adopted by
+ * another user or deleted.
+ */
+export interface OrphanedWorkspace {
+	readonly id: string;
+	readonly name: string;
+	readonly organization_id: string;
+	readonly organization_name: string;
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly owner_id: string;
+	readonly owner_username: string;
+	readonly reason: OrphanedWorkspaceReason;
+	readonly created_at: string;
+	readonly last_used_at: string;
+	readonly dormant_at?: string;
+}
+
+// From codersdk/orphanedworkspaces.go
+/**
+ * OrphanedWorkspaceReason is the reason a workspace can no longer be managed
+ * by its owner.
+ */
+export type OrphanedWorkspaceReason = "owner_deleted" | "owner_not_member";
+
+export const OrphanedWorkspaceReasons: OrphanedWorkspaceReason[] = [
+	"owner_deleted",
+	"owner_not_member",
+];
+
+// From codersdk/organizations.go
 export interface PaginatedMembersRequest {
 	readonly limit?: number;
 	readonly offset?: number;
@@ -6870,6 +7941,17 @@ export interface PatchRoleIDPSyncMappingRequest {
 export interface PatchTemplateVersionRequest {
 	readonly name: string;
 	readonly message?: string;
+	/**
+	 * DeprecationMessage if set, will mark the template version as
+	 * deprecated. If passed an empty string, the deprecation is removed.
+	 */
+	readonly deprecation_message?: string;
+	/**
+	 * DeprecationCutoff is applied together with DeprecationMessage. If set,
+	 * the deprecated version may still be used until the cutoff. If unset,
+	 * new workspaces and updates using the version are blocked immediately.
+	 */
+	readonly deprecation_cutoff?: string;
 }
 
 // From codersdk/workspaceproxy.go
@@ -6917,6 +7999,43 @@ export interface PostOAuth2ProviderAppRequest {
 	readonly icon: string;
 }
 
+// From codersdk/workspaces.go
+/**
+ * PostWorkspaceBumpRequest postpones the deadline of the active workspace
+ * build by a duration.
+ */
+export interface PostWorkspaceBumpRequest {
+	/**
+	 * DurationMillis is added to the current deadline, or to the current
+	 * time if the deadline passed. The result is capped by the max deadline
+	 * of the build. It must be at most 30 days.
+	 */
+	readonly duration_ms: number;
+}
+
+// From codersdk/workspaces.go
+/**
+ * PostWorkspaceHeartbeatRequest reports that an IDE plugin or another external
+ * integration is actively using a workspace.
+ */
+export interface PostWorkspaceHeartbeatRequest {
+	/**
+	 * AgentID is the agent the integration is connected to. It may be omitted
+	 * when the workspace has a single agent.
+	 */
+	readonly agent_id?: string;
+	/**
+	 * AppName is the kind of session the integration provides. Heartbeats
+	 * bump workspace activity the same way sessions of the built-in app do.
+	 */
+	readonly app_name: UsageAppName;
+	/**
+	 * Plugin identifies the integration sending the heartbeat, e.g.
+	 * "coder-neovim/0.3.1".
+	 */
+	readonly plugin: string;
+}
+
 // From codersdk/workspaces.go
 export interface PostWorkspaceUsageRequest {
 	readonly agent_id: string;
@@ -6941,6 +8060,86 @@ export interface PprofConfig {
 	readonly address: string;
 }
 
+// From codersdk/insights.go
+/**
+ * PrebuildClaimInsightsReport reports, per preset, how often a prebuilt
+ * workspace could be claimed when a workspace was created with the preset,
+ * how long claimed workspaces took to become ready, and why the pool was empty
+ * when no prebuilt workspace could be claimed.
+ */
+export interface PrebuildClaimInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly presets: readonly PrebuildPresetClaimInsight[];
+}
+
+// From codersdk/insights.go
+export interface PrebuildClaimInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+}
+
+// From codersdk/insights.go
+/**
+ * PrebuildClaimInsightsResponse is the response from the prebuild claim
+ * insights endpoint.
+ */
+export interface PrebuildClaimInsightsResponse {
+	readonly report: PrebuildClaimInsightsReport;
+}
+
+// From codersdk/insights.go
+export type PrebuildClaimMissReason =
+	| "capacity"
+	| "failures"
+	| "reconciliation_lag";
+
+export const PrebuildClaimMissReasons: PrebuildClaimMissReason[] = [
+	"capacity",
+	"failures",
+	"reconciliation_lag",
+];
+
+// From codersdk/insights.go
+/**
+ * PrebuildPresetClaimInsight is the claim performance of a single preset.
+ */
+export interface PrebuildPresetClaimInsight {
+	readonly organization_name: string;
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly template_version_name: string;
+	readonly preset_id: string;
+	readonly preset_name: string;
+	/**
+	 * ClaimAttempts is the number of workspaces created with the preset.
+	 */
+	readonly claim_attempts: number;
+	/**
+	 * Claimed is the number of those workspaces that claimed a prebuilt
+	 * workspace.
+	 */
+	readonly claimed: number;
+	/**
+	 * ClaimSuccessRate is Claimed divided by ClaimAttempts, between 0 and 1.
+	 */
+	readonly claim_success_rate: number;
+	/**
+	 * TimeToReadyP50Seconds and TimeToReadyP95Seconds are percentiles of the
+	 * time from a claim until all agents of the workspace were ready. They
+	 * are zero when no claimed workspace has become ready.
+	 */
+	readonly time_to_ready_p50_seconds: number;
+	readonly time_to_ready_p95_seconds: number;
+	/**
+	 * Misses counts the claim attempts that found no prebuilt workspace, by
+	 * the reason the pool was empty.
+	 */
+	readonly misses: Record<PrebuildClaimMissReason, number>;
+}
+
 // From codersdk/deployment.go
 export interface PrebuildsConfig {
 	/**
@@ -7070,6 +8269,44 @@ export interface ProposeChatTitleResponse {
 	readonly title: string;
 }
 
+// From codersdk/provisionercache.go
+export type ProvisionerCacheArtifact = "module" | "provider_plugin";
+
+export const ProvisionerCacheArtifacts: ProvisionerCacheArtifact[] = [
+	"module",
+	"provider_plugin",
+];
+
+// From codersdk/provisionercache.go
+/**
+ * ProvisionerCacheSettings controls the artifact caches used by the workspace
+ * builds of every template. Templates can disable each cache individually.
+ */
+export interface ProvisionerCacheSettings {
+	/**
+	 * DisableProviderCache makes provisioners install Terraform providers
+	 * from their registries instead of their shared plugin cache.
+	 */
+	readonly disable_provider_cache: boolean;
+	/**
+	 * DisableModuleCache makes provisioners download Terraform modules
+	 * instead of using the modules cached when template versions are
+	 * imported.
+	 */
+	readonly disable_module_cache: boolean;
+}
+
+// From codersdk/provisionercache.go
+/**
+ * ProvisionerCacheStats counts the artifacts of a kind that were reused from
+ * a cache, and the ones that had to be downloaded, during a build.
+ */
+export interface ProvisionerCacheStats {
+	readonly artifact: ProvisionerCacheArtifact;
+	readonly hits: number;
+	readonly misses: number;
+}
+
 // From codersdk/deployment.go
 export interface ProvisionerConfig {
 	/**
@@ -7081,6 +8318,18 @@ export interface ProvisionerConfig {
 	readonly daemon_poll_jitter: number;
 	readonly force_cancel_interval: number;
 	readonly daemon_psk: string;
+	/**
+	 * TemplateScannerURL is a webhook that newly imported template versions
+	 * are submitted to for security scanning. Scanning is disabled when unset.
+	 */
+	readonly template_scanner_url: string;
+	readonly template_scanner_block_critical: boolean;
+	/**
+	 * CloudResourceCheckerURL is a webhook that checks whether the cloud
+	 * resources of deleted workspaces still exist. Verification is disabled
+	 * when unset.
+	 */
+	readonly cloud_resource_checker_url: string;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -7125,6 +8374,30 @@ export const ProvisionerDaemonKey = "Coder-Provisioner-Daemon-Key";
  */
 export const ProvisionerDaemonPSK = "Coder-Provisioner-Daemon-PSK";
 
+// From codersdk/provisionerdaemons.go
+/**
+ * ProvisionerDaemonSettings are the limits imposed on the provisioner daemons
+ * registered in an organization.
+ */
+export interface ProvisionerDaemonSettings {
+	/**
+	 * TagNamespace, when set, requires every tag key of a registering daemon,
+	 * apart from the reserved scope and owner tags, to be prefixed with
+	 * "<namespace>/".
+	 */
+	readonly tag_namespace: string;
+	/**
+	 * DaemonQuota is the maximum number of daemons that may be connected to
+	 * the organization at once. Zero means unlimited.
+	 */
+	readonly daemon_quota: number;
+	/**
+	 * ConnectedDaemons is the number of daemons currently counted against
+	 * the quota.
+	 */
+	readonly connected_daemons: number;
+}
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerDaemonStatus = "busy" | "idle" | "offline";
 
@@ -7253,6 +8526,16 @@ export interface ProvisionerKey {
 	readonly organization: string;
 	readonly name: string;
 	readonly tags: ProvisionerKeyTags;
+	/**
+	 * LastUsedAt is the last time a provisioner daemon authenticated with
+	 * the key.
+	 */
+	readonly last_used_at?: string;
+	/**
+	 * PreviousKeyExpiresAt is set while the key the provisioner key was
+	 * rotated away from is still valid.
+	 */
+	readonly previous_key_expires_at?: string;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -7303,6 +8586,12 @@ export interface ProvisionerTiming {
 	readonly resource: string;
 }
 
+// From codersdk/provisionercache.go
+export const ProvisionerTimingActionCacheHit = "cache hit";
+
+// From codersdk/provisionercache.go
+export const ProvisionerTimingActionCacheMiss = "cache miss";
+
 // From codersdk/organizations.go
 export type ProvisionerType = "echo" | "terraform";
 
@@ -7355,6 +8644,56 @@ export interface PutOAuth2ProviderAppRequest {
 	readonly icon: string;
 }
 
+// From codersdk/workspacecosts.go
+/**
+ * PutWorkspaceCostRequest imports the cost of a workspace for a month from a
+ * billing export.
+ */
+export interface PutWorkspaceCostRequest {
+	/**
+	 * Month is any time within the UTC calendar month the cost was accrued in.
+	 */
+	readonly month: string;
+	readonly cost: number;
+}
+
+// From codersdk/workspacedormancyexemptions.go
+/**
+ * PutWorkspaceDormancyExemptionRequest grants or replaces the dormancy
+ * exemption of a workspace.
+ */
+export interface PutWorkspaceDormancyExemptionRequest {
+	readonly reason: string;
+	readonly expires_at: string;
+}
+
+// From codersdk/workspacequiesce.go
+/**
+ * QuiesceWorkspaceRequest quiesces the agents of a running workspace so
+ * that its volumes can be backed up consistently.
+ */
+export interface QuiesceWorkspaceRequest {
+	/**
+	 * TimeoutSeconds is how long the agents stay quiesced before they
+	 * resume automatically. Zero uses the agent default of five minutes.
+	 */
+	readonly timeout_seconds?: number;
+}
+
+// From codersdk/workspacequiesce.go
+/**
+ * QuiesceWorkspaceResponse is returned once all agents of the workspace are
+ * quiesced.
+ */
+export interface QuiesceWorkspaceResponse {
+	/**
+	 * ResumesAt is the earliest time at which an agent resumes
+	 * automatically. Backups must complete before then.
+	 */
+	readonly resumes_at: string;
+	readonly agent_ids: readonly string[];
+}
+
 // From codersdk/rbacresources_gen.go
 export type RBACAction =
 	| "application_connect"
@@ -7363,6 +8702,7 @@ export type RBACAction =
 	| "create_agent"
 	| "delete"
 	| "delete_agent"
+	| "impersonate"
 	| "read"
 	| "read_personal"
 	| "ssh"
@@ -7373,6 +8713,7 @@ export type RBACAction =
 	| "update_personal"
 	| "use"
 	| "view_insights"
+	| "extend"
 	| "start"
 	| "stop";
 
@@ -7383,6 +8724,7 @@ export const RBACActions: RBACAction[] = [
 	"create_agent",
 	"delete",
 	"delete_agent",
+	"impersonate",
 	"read",
 	"read_personal",
 	"ssh",
@@ -7393,6 +8735,7 @@ export const RBACActions: RBACAction[] = [
 	"update_personal",
 	"use",
 	"view_insights",
+	"extend",
 	"start",
 	"stop",
 ];
@@ -7509,6 +8852,13 @@ export const RBACResources: RBACResource[] = [
 export interface RateLimitConfig {
 	readonly disable_all: boolean;
 	readonly api: number;
+	/**
+	 * WorkspaceBuilds is the sustained number of builds per minute allowed
+	 * per token and per IP address on build-creating endpoints.
+	 */
+	readonly workspace_builds: number;
+	readonly workspace_builds_burst: number;
+	readonly workspace_builds_allowlist: string;
 }
 
 // From codersdk/users.go
@@ -7555,6 +8905,67 @@ export interface Region {
 	readonly wildcard_hostname: string;
 }
 
+// From codersdk/workspaceproxy.go
+/**
+ * RegionCandidate is a region considered for a recommendation along with the
+ * inputs of its score.
+ */
+export interface RegionCandidate {
+	readonly region: Region;
+	/**
+	 * ClientLatencyMS is the latency the client reported to the region. It is
+	 * omitted if the client did not report one.
+	 */
+	readonly client_latency_ms?: number;
+	/**
+	 * WorkspaceLatencyMS is the lowest latency the agents of the workspace
+	 * report to the DERP relay of the region.
+	 */
+	readonly workspace_latency_ms?: number;
+	/**
+	 * AssignedWorkspaces is the number of workspaces assigned to the region.
+	 */
+	readonly assigned_workspaces: number;
+	/**
+	 * Warnings are the health check warnings of the region.
+	 */
+	readonly warnings: readonly string[];
+	/**
+	 * Score ranks the candidates, lower is better. Candidates with a client
+	 * latency are always ranked before candidates without one.
+	 */
+	readonly score: number;
+}
+
+// From codersdk/workspaceproxy.go
+/**
+ * RegionRecommendation is the region recommended for a client.
+ */
+export interface RegionRecommendation {
+	readonly recommended: Region;
+	/**
+	 * Candidates are the healthy regions, best first.
+	 */
+	readonly candidates: readonly RegionCandidate[];
+}
+
+// From codersdk/workspaceproxy.go
+/**
+ * RegionRecommendationRequest are the inputs of a region recommendation.
+ */
+export interface RegionRecommendationRequest {
+	/**
+	 * WorkspaceID optionally includes the latencies the agents of a workspace
+	 * report to each region.
+	 */
+	readonly workspace_id?: string;
+	/**
+	 * ClientLatenciesMS are the latencies in milliseconds the client measured
+	 * to each region, keyed by region ID.
+	 */
+	readonly client_latencies_ms?: Record<string, number>;
+}
+
 // From codersdk/workspaceproxy.go
 export type RegionTypes = Region | WorkspaceProxy;
 
@@ -7618,6 +9029,7 @@ export type ResourceType =
 	| "chat"
 	| "convert_login"
 	| "custom_role"
+	| "deployment_bundle"
 	| "git_ssh_key"
 	| "group"
 	| "group_ai_budget"
@@ -7635,14 +9047,17 @@ export type ResourceType =
 	| "prebuilds_settings"
 	| "task"
 	| "template"
+	| "template_secret"
 	| "template_version"
 	| "user"
 	| "user_ai_budget_override"
 	| "user_secret"
 	| "user_skill"
+	| "vcs_event_mapping"
 	| "workspace"
 	| "workspace_agent"
 	| "workspace_app"
+	| "workspace_app_share_link"
 	| "workspace_build"
 	| "workspace_proxy";
 
@@ -7655,6 +9070,7 @@ export const ResourceTypes: ResourceType[] = [
 	"chat",
 	"convert_login",
 	"custom_role",
+	"deployment_bundle",
 	"git_ssh_key",
 	"group",
 	"group_ai_budget",
@@ -7672,14 +9088,17 @@ export const ResourceTypes: ResourceType[] = [
 	"prebuilds_settings",
 	"task",
 	"template",
+	"template_secret",
 	"template_version",
 	"user",
 	"user_ai_budget_override",
 	"user_secret",
 	"user_skill",
+	"vcs_event_mapping",
 	"workspace",
 	"workspace_agent",
 	"workspace_app",
+	"workspace_app_share_link",
 	"workspace_build",
 	"workspace_proxy",
 ];
@@ -7760,6 +9179,24 @@ export interface RetentionConfig {
 	 * organization's regulatory requirements.
 	 */
 	readonly boundary_logs: number;
+	/**
+	 * WorkspaceAgentMetadataHistory controls how long historical values of
+	 * workspace agent metadata are retained. Set to 0 to disable recording
+	 * metadata history.
+	 */
+	readonly workspace_agent_metadata_history: number;
+	/**
+	 * BuildLogs controls how long provisioner job logs are retained before
+	 * they are archived or deleted. Logs of the latest build of each
+	 * workspace are always retained. Templates may override this value.
+	 * Set to 0 to disable.
+	 */
+	readonly build_logs: number;
+	/**
+	 * BuildLogsArchiveDir is the directory expired build logs are archived
+	 * to. When unset, expired build logs are deleted instead.
+	 */
+	readonly build_logs_archive_dir: string;
 }
 
 // From codersdk/roles.go
@@ -7874,6 +9311,26 @@ export const RoleTemplateAdmin = "template-admin";
  */
 export const RoleUserAdmin = "user-admin";
 
+// From codersdk/provisionerdaemons.go
+/**
+ * RotateProvisionerKeyRequest rotates the secret of a provisioner key.
+ */
+export interface RotateProvisionerKeyRequest {
+	/**
+	 * GracePeriodMillis is how long the previous key stays valid for, so
+	 * that daemons can be moved to the new key without downtime. Daemons
+	 * still connected with the previous key are drained once it expires.
+	 * Zero invalidates the previous key immediately.
+	 */
+	readonly grace_period_ms: number;
+}
+
+// From codersdk/provisionerdaemons.go
+export interface RotateProvisionerKeyResponse {
+	readonly key: string;
+	readonly previous_key_expires_at: string;
+}
+
 // From codersdk/deployment.go
 /**
  * SSHConfig is configuration the cli & vscode extension use for configuring
@@ -7905,6 +9362,41 @@ export interface SSHConfigResponse {
 	readonly ssh_config_options: Record<string, string>;
 }
 
+// From codersdk/deployment.go
+/**
+ * SSHHostCAKey is the public half of a CA that signs workspace agent SSH host
+ * certificates.
+ */
+export interface SSHHostCAKey {
+	/**
+	 * PublicKey is in OpenSSH authorized_keys format.
+	 */
+	readonly public_key: string;
+	readonly sequence: number;
+	readonly starts_at: string;
+	/**
+	 * DeletesAt is set once the CA has been rotated out. Clients should keep
+	 * trusting it until then, as certificates it signed may still be valid.
+	 */
+	readonly deletes_at?: string;
+}
+
+// From codersdk/deployment.go
+/**
+ * SSHHostCAResponse lists every CA currently trusted to sign workspace host
+ * certificates. Clients that trust these keys can verify a workspace's host
+ * identity instead of prompting on first use.
+ */
+export interface SSHHostCAResponse {
+	readonly keys: readonly SSHHostCAKey[];
+	/**
+	 * KnownHosts contains one @cert-authority line per key, scoped to the
+	 * deployment's SSH hostname patterns, ready to append to a known_hosts
+	 * file.
+	 */
+	readonly known_hosts: string;
+}
+
 // From healthsdk/healthsdk.go
 /**
  * STUNReport contains information about a given node's STUN capabilities.
@@ -7915,6 +9407,21 @@ export interface STUNReport {
 	readonly Error: string | null;
 }
 
+// From codersdk/notifications.go
+/**
+ * ScopedNotificationPreference enables or disables a notification template
+ * for the messages about a single workspace or template. It takes precedence
+ * over the NotificationPreference of the template, and a workspace preference
+ * takes precedence over the preference for its template.
+ */
+export interface ScopedNotificationPreference {
+	readonly notification_template_id: string;
+	readonly scope: NotificationPreferenceScope;
+	readonly target_id: string;
+	readonly disabled: boolean;
+	readonly updated_at: string;
+}
+
 // From codersdk/usersecretsimport.go
 export type SecretsFileFormat = "env" | "json" | "yaml";
 
@@ -8109,6 +9616,16 @@ export interface SessionLifetime {
 	readonly default_token_lifetime?: number;
 	readonly max_token_lifetime?: number;
 	readonly max_admin_token_lifetime?: number;
+	/**
+	 * MaximumImpersonationDuration is the maximum lifetime of tokens issued
+	 * to impersonate users.
+	 */
+	readonly max_impersonation_lifetime?: number;
+	/**
+	 * RequireImpersonationConsent requires users to consent before tokens
+	 * impersonating them are issued.
+	 */
+	readonly require_impersonation_consent?: boolean;
 }
 
 // From codersdk/client.go
@@ -8126,28 +9643,83 @@ export const ShareableWorkspaceOwnerses: ShareableWorkspaceOwners[] = [
 	"service_accounts",
 ];
 
-// From codersdk/workspaces.go
-export interface SharedWorkspaceActor {
+// From codersdk/sharedvolumes.go
+/**
+ * SharedVolume is a persistent volume of an organization that multiple
+ * workspaces can attach, such as a dataset shared by a team.
+ */
+export interface SharedVolume {
 	readonly id: string;
-	readonly actor_type: SharedWorkspaceActorType;
+	readonly organization_id: string;
 	readonly name: string;
-	readonly avatar_url?: string;
-	readonly roles: readonly WorkspaceRole[];
+	readonly display_name: string;
+	readonly description: string;
+	/**
+	 * TemplateID is the template that declared the volume, if any.
+	 */
+	readonly template_id?: string;
+	readonly attachment_count: number;
+	/**
+	 * LockedByWorkspaceID is the workspace that attached the volume
+	 * read-write, if any.
+	 */
+	readonly locked_by_workspace_id?: string;
+	readonly created_at: string;
+	readonly updated_at: string;
 }
 
-// From codersdk/workspaces.go
-export type SharedWorkspaceActorType = "group" | "user";
+// From codersdk/sharedvolumes.go
+/**
+ * SharedVolumeAccessMode is how a workspace mounts a shared volume. Only one
+ * workspace at a time can attach a volume read-write.
+ */
+export type SharedVolumeAccessMode = "read_only" | "read_write";
 
-export const SharedWorkspaceActorTypes: SharedWorkspaceActorType[] = [
-	"group",
-	"user",
+export const SharedVolumeAccessModes: SharedVolumeAccessMode[] = [
+	"read_only",
+	"read_write",
 ];
 
-// From codersdk/client.go
+// From codersdk/sharedvolumes.go
 /**
- * SignedAppTokenCookie is the name of the cookie that stores a temporary
- * JWT that can be used to authenticate instead of the app session token.
- *nolint:gosec
+ * SharedVolumeAttachment is a shared volume mounted into a workspace agent.
+ */
+export interface SharedVolumeAttachment {
+	readonly id: string;
+	readonly shared_volume_id: string;
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly workspace_owner_name: string;
+	readonly workspace_build_id: string;
+	readonly workspace_agent_id: string;
+	readonly workspace_agent_name: string;
+	readonly mount_path: string;
+	readonly access_mode: SharedVolumeAccessMode;
+	readonly attached_at: string;
+}
+
+// From codersdk/workspaces.go
+export interface SharedWorkspaceActor {
+	readonly id: string;
+	readonly actor_type: SharedWorkspaceActorType;
+	readonly name: string;
+	readonly avatar_url?: string;
+	readonly roles: readonly WorkspaceRole[];
+}
+
+// From codersdk/workspaces.go
+export type SharedWorkspaceActorType = "group" | "user";
+
+export const SharedWorkspaceActorTypes: SharedWorkspaceActorType[] = [
+	"group",
+	"user",
+];
+
+// From codersdk/client.go
+/**
+ * SignedAppTokenCookie is the name of the cookie that stores a temporary
+ * JWT that can be used to authenticate instead of the app session token.
+ *nolint:gosec
  */
 export const SignedAppTokenCookie = "coder_signed_app_token";
 
@@ -8164,6 +9736,18 @@ export const SignedAppTokenCookie = "coder_signed_app_token";
  */
 export const SignedAppTokenQueryParameter = "coder_signed_app_token_23db1dde";
 
+// From codersdk/templateschedulepolicy.go
+/**
+ * SimulateTemplateSchedulePolicyRequest contains proposed dormancy and
+ * deletion settings of a template. Fields that are nil keep the current value
+ * of the template.
+ */
+export interface SimulateTemplateSchedulePolicyRequest {
+	readonly failure_ttl_ms?: number;
+	readonly time_til_dormant_ms?: number;
+	readonly time_til_dormant_autodelete_ms?: number;
+}
+
 // From codersdk/roles.go
 /**
  * SlimRole omits permission information from a role.
@@ -8568,16 +10152,58 @@ export interface Template {
 	readonly description: string;
 	readonly deprecated: boolean;
 	readonly deprecation_message: string;
+	/**
+	 * DeprecationCutoff is the time after which the deprecated template can
+	 * no longer be used to create workspaces. Until then, builds return a
+	 * deprecation warning.
+	 */
+	readonly deprecation_cutoff?: string;
 	readonly deleted: boolean;
 	readonly icon: string;
 	readonly default_ttl_ms: number;
 	readonly activity_bump_ms: number;
+	/**
+	 * ActivityBumpConnectionTypes are the types of connections that bump the
+	 * deadline of workspaces. Empty means all connections bump the deadline.
+	 */
+	readonly activity_bump_connection_types: readonly ActivityBumpConnectionType[];
+	/**
+	 * ActivityBumpMaxPerDayMillis is the maximum cumulative amount activity
+	 * may extend the deadline of a workspace per day (UTC). 0 means unlimited.
+	 */
+	readonly activity_bump_max_per_day_ms: number;
 	/**
 	 * TimeTilAutostopNotifyMillis is the duration before the workspace's
 	 * autostop deadline at which a reminder notification is sent. 0 disables
 	 * the notification.
 	 */
 	readonly time_til_autostop_notify_ms: number;
+	/**
+	 * NightlyStopTime is the time of day (HH:MM) at which running workspaces
+	 * are stopped regardless of activity. It is interpreted in the timezone of
+	 * each owner's quiet hours schedule. Empty means disabled. This is an
+	 * enterprise feature.
+	 */
+	readonly nightly_stop_time: string;
+	/**
+	 * MaxLifetimeMillis is how long after their creation workspaces are
+	 * stopped or deleted, according to MaxLifetimeAction, regardless of
+	 * activity. 0 means disabled. This is an enterprise feature.
+	 */
+	readonly max_lifetime_ms: number;
+	readonly max_lifetime_action: MaxLifetimeAction;
+	/**
+	 * IdleReclaimTTLMillis is how long running workspaces with a resource
+	 * matching IdleReclaimResourceSelector may be idle before they are
+	 * stopped. It reclaims expensive resources, such as GPUs, well before the
+	 * default TTL. 0 means disabled. This is an enterprise feature.
+	 */
+	readonly idle_reclaim_ttl_ms: number;
+	/**
+	 * IdleReclaimResourceSelector is a key=value pair matched against the
+	 * metadata of workspace resources, e.g. "gpu=true".
+	 */
+	readonly idle_reclaim_resource_selector: string;
 	/**
 	 * AutostopRequirement and AutostartRequirement are enterprise features. Its
 	 * value is only used if your license is entitled to use the advanced template
@@ -8616,6 +10242,72 @@ export interface Template {
 	 * provisioning.
 	 */
 	readonly disable_module_cache: boolean;
+	/**
+	 * DisableProviderCache disables the use of the shared Terraform provider
+	 * cache of provisioners during workspace builds.
+	 */
+	readonly disable_provider_cache: boolean;
+	/**
+	 * ProvisionerPlanTimeoutMillis limits the duration of template version
+	 * import and dry-run jobs. ProvisionerApplyTimeoutMillis limits the
+	 * duration of workspace build jobs. 0 means no timeout.
+	 */
+	readonly provisioner_plan_timeout_ms: number;
+	readonly provisioner_apply_timeout_ms: number;
+	/**
+	 * TrialWorkspaceTTLMillis is the hard lifetime of workspaces created from
+	 * the template. Expired workspaces are stopped and then deleted regardless
+	 * of activity. 0 disables the expiry.
+	 */
+	readonly trial_workspace_ttl_ms: number;
+	/**
+	 * BuildLogRetentionMillis is how long the logs of workspace builds of the
+	 * template are kept before they are archived or deleted. The logs of the
+	 * latest build of each workspace are always kept. 0 uses the
+	 * deployment-wide retention.
+	 */
+	readonly build_log_retention_ms: number;
+	/**
+	 * PreBuildHookURL is POSTed to before every workspace build of the
+	 * template is provisioned. The hook may reject the build.
+	 */
+	readonly pre_build_hook_url: string;
+	/**
+	 * PostBuildHookURL is POSTed to after every successful workspace build
+	 * of the template.
+	 */
+	readonly post_build_hook_url: string;
+	/**
+	 * AutoAssignRegion assigns workspaces created from the template the
+	 * region recommended for the latencies reported by the creating client.
+	 */
+	readonly auto_assign_region: boolean;
+	/**
+	 * NotifyBuildFailures notifies workspace owners, and the initiators of
+	 * builds, of every failed build along with an excerpt of its logs.
+	 */
+	readonly notify_build_failures: boolean;
+	/**
+	 * RequeueReapedBuilds requeues workspace builds once when the job reaper
+	 * terminates them because their provisioner stopped responding.
+	 */
+	readonly requeue_reaped_builds: boolean;
+	/**
+	 * AgentRolloutChannel is the channel workspace agents of the template
+	 * download their binary from on start.
+	 */
+	readonly agent_rollout_channel: AgentRolloutChannel;
+	/**
+	 * AllowTargetedBuilds allows workspace builds that only replace the
+	 * Terraform resources listed in the build request.
+	 */
+	readonly allow_targeted_builds: boolean;
+	/**
+	 * ReconfirmParameters lists the parameters users must explicitly set
+	 * again when updating a workspace to a new template version, even if a
+	 * value from a previous build exists.
+	 */
+	readonly reconfirm_parameters: readonly string[];
 }
 
 // From codersdk/templates.go
@@ -8624,6 +10316,64 @@ export interface TemplateACL {
 	readonly group: readonly TemplateGroup[];
 }
 
+// From codersdk/templates.go
+/**
+ * TemplateACLChange is a change of a user's or group's role on a template,
+ * as recorded in the audit log.
+ */
+export interface TemplateACLChange {
+	readonly audit_log_id: string;
+	readonly time: string;
+	readonly actor: MinimalUser;
+	readonly subject_type: ACLSubjectType;
+	readonly subject_id: string;
+	/**
+	 * OldRole is empty if the subject gained access to the template.
+	 */
+	readonly old_role: TemplateRole;
+	/**
+	 * NewRole is empty if the subject lost access to the template.
+	 */
+	readonly new_role: TemplateRole;
+}
+
+// From codersdk/templates.go
+export interface TemplateACLChangesRequest {
+	/**
+	 * Since limits the changes to those made after this time. Defaults to 30
+	 * days ago.
+	 */
+	readonly since?: string;
+	/**
+	 * Granted limits the changes to those that granted access to a subject
+	 * that previously had none.
+	 */
+	readonly granted?: boolean;
+}
+
+// From codersdk/insights.go
+export interface TemplateActiveDeveloperDays {
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly organization_id: string;
+	readonly organization_name: string;
+	readonly active_developer_days: number;
+}
+
+// From codersdk/insights.go
+/**
+ * TemplateActiveDevelopers is the number of users active in workspaces of a
+ * template on a single day.
+ */
+export interface TemplateActiveDevelopers {
+	readonly date: string;
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly organization_id: string;
+	readonly organization_name: string;
+	readonly active_developers: number;
+}
+
 // From codersdk/insights.go
 /**
  * TemplateAppUsage shows the usage of an app for one or more templates.
@@ -8831,6 +10581,24 @@ export const TemplateBuiltinAppDisplayNameVSCode = "Visual Studio Code";
  */
 export const TemplateBuiltinAppDisplayNameWebTerminal = "Web Terminal";
 
+// From codersdk/templates.go
+/**
+ * TemplateCreationSchema describes everything a client needs to build a
+ * workspace creation form for a template: the active version's parameters
+ * and presets, the rules a workspace name must satisfy, and the scheduling
+ * limits that apply to new workspaces.
+ */
+export interface TemplateCreationSchema {
+	readonly template_id: string;
+	readonly template_version_id: string;
+	readonly require_active_version: boolean;
+	readonly parameters: readonly TemplateVersionParameter[];
+	readonly presets: readonly Preset[];
+	readonly name_policy: WorkspaceNamePolicy;
+	readonly ttl: WorkspaceTTLPolicy;
+	readonly schedule: WorkspaceSchedulePolicy;
+}
+
 // From codersdk/templates.go
 export interface TemplateExample {
 	readonly id: string;
@@ -8852,6 +10620,37 @@ export interface TemplateGroup extends Group {
 	readonly role: TemplateRole;
 }
 
+// From codersdk/templatehealthreports.go
+/**
+ * TemplateHealthReport is the health of a template over a period, as sent
+ * weekly to template admins.
+ */
+export interface TemplateHealthReport {
+	readonly id: string;
+	readonly template_id: string;
+	readonly period_start: string;
+	readonly period_end: string;
+	/**
+	 * TotalBuilds and FailedBuilds count the builds that completed during
+	 * the period.
+	 */
+	readonly total_builds: number;
+	readonly failed_builds: number;
+	/**
+	 * AverageStartSeconds is the average time successful start builds took
+	 * to complete, from when they were queued.
+	 */
+	readonly average_start_seconds: number;
+	/**
+	 * TotalWorkspaces, OutdatedWorkspaces and DormantWorkspaces count the
+	 * workspaces of the template at the end of the period.
+	 */
+	readonly total_workspaces: number;
+	readonly outdated_workspaces: number;
+	readonly dormant_workspaces: number;
+	readonly created_at: string;
+}
+
 // From codersdk/insights.go
 /**
  * TemplateInsightsIntervalReport is the report from the template insights
@@ -8904,6 +10703,22 @@ export const TemplateInsightsSections: TemplateInsightsSection[] = [
 	"report",
 ];
 
+// From codersdk/networkpolicies.go
+/**
+ * TemplateNetworkPolicy is the default outbound network policy of the
+ * workspaces created from a template.
+ */
+export interface TemplateNetworkPolicy {
+	readonly template_id: string;
+	/**
+	 * AllowedDomains are hostnames workspaces may connect to. A leading
+	 * "*." matches any subdomain.
+	 */
+	readonly allowed_domains: readonly string[];
+	readonly allowed_cidrs: readonly string[];
+	readonly updated_at: string;
+}
+
 // From codersdk/insights.go
 /**
  * TemplateParameterUsage shows the usage of a parameter for one or more
@@ -8929,10 +10744,89 @@ export interface TemplateParameterValue {
 	readonly count: number;
 }
 
+// From codersdk/presets.go
+/**
+ * TemplatePresetCohort restricts the presets with a given name, in every
+ * version of a template, to users who are members of any of the groups or
+ * have any of the roles. Presets without a cohort are available to everyone
+ * who can use the template.
+ */
+export interface TemplatePresetCohort {
+	readonly preset_name: string;
+	readonly group_ids: readonly string[];
+	/**
+	 * Roles are names of site-wide roles, or of roles in the organization of
+	 * the template.
+	 */
+	readonly roles: readonly string[];
+}
+
 // From codersdk/templates.go
-export type TemplateRole = "admin" | "" | "use";
+export type TemplateRole = "admin" | "" | "manage" | "use";
+
+export const TemplateRoles: TemplateRole[] = ["admin", "", "manage", "use"];
+
+// From codersdk/templateschedulepolicy.go
+/**
+ * TemplateSchedulePolicySimulation lists the existing workspaces of a
+ * template that would be affected by the proposed settings. Nothing is
+ * changed by a simulation.
+ */
+export interface TemplateSchedulePolicySimulation {
+	readonly simulated_at: string;
+	/**
+	 * DormantImmediately, DeletedImmediately and StoppedImmediately count the
+	 * workspaces that would be affected by the next run of the lifecycle
+	 * executor if the settings were applied now.
+	 */
+	readonly dormant_immediately: number;
+	readonly deleted_immediately: number;
+	readonly stopped_immediately: number;
+	readonly workspaces: readonly TemplateSchedulePolicySimulationWorkspace[];
+}
+
+// From codersdk/templateschedulepolicy.go
+/**
+ * TemplateSchedulePolicySimulationWorkspace is a workspace affected by the
+ * simulated settings, and when it would be affected. Times in the past are
+ * clamped to the time of the simulation.
+ */
+export interface TemplateSchedulePolicySimulationWorkspace {
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly owner_id: string;
+	readonly owner_name: string;
+	readonly last_used_at: string;
+	/**
+	 * DormantAt is set if the workspace would become dormant.
+	 */
+	readonly dormant_at?: string;
+	/**
+	 * DeletingAt is set if the workspace would be deleted.
+	 */
+	readonly deleting_at?: string;
+	/**
+	 * FailedBuildStoppedAt is set if the workspace would be stopped because
+	 * its latest build failed.
+	 */
+	readonly failed_build_stopped_at?: string;
+}
 
-export const TemplateRoles: TemplateRole[] = ["admin", "", "use"];
+// From codersdk/templatesecrets.go
+/**
+ * TemplateSecret is a secret declared on a template. Startup scripts of the
+ * template's workspaces reference it by name as an environment variable, and
+ * the agent fetches its value when the script runs. The value is never
+ * returned by the API.
+ */
+export interface TemplateSecret {
+	readonly id: string;
+	readonly template_id: string;
+	readonly name: string;
+	readonly description: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
 
 // From codersdk/templates.go
 export interface TemplateUser extends User {
@@ -8958,6 +10852,75 @@ export interface TemplateVersion {
 	readonly warnings?: readonly TemplateVersionWarning[];
 	readonly matched_provisioners?: MatchedProvisioners;
 	readonly has_external_agent: boolean;
+	/**
+	 * DeprecationMessage is set if the template version is deprecated.
+	 */
+	readonly deprecation_message?: string;
+	/**
+	 * DeprecationCutoff is the time after which the deprecated template
+	 * version can no longer be used for new workspaces or updates.
+	 */
+	readonly deprecation_cutoff?: string;
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionDiagnostic {
+	readonly severity: DiagnosticSeverityString;
+	readonly summary: string;
+	readonly detail: string;
+	/**
+	 * Range is the position in the template source code the diagnostic
+	 * refers to, if known.
+	 */
+	readonly range?: TemplateVersionDiagnosticRange;
+}
+
+// From codersdk/templateversions.go
+/**
+ * TemplateVersionDiagnosticRange is a range within a template file. Lines and
+ * columns start at 1.
+ */
+export interface TemplateVersionDiagnosticRange {
+	readonly filename: string;
+	readonly start_line: number;
+	readonly start_column: number;
+	readonly end_line: number;
+	readonly end_column: number;
+}
+
+// From codersdk/templateversions.go
+/**
+ * TemplateVersionDryRunMatrix reports the dry-runs of a template version on
+ * several groups of provisioner daemons.
+ */
+export interface TemplateVersionDryRunMatrix {
+	readonly results: readonly TemplateVersionDryRunMatrixResult[];
+}
+
+// From codersdk/templateversions.go
+/**
+ * TemplateVersionDryRunMatrixResult reports a dry-run of a template version
+ * on the provisioner daemons matching its tags.
+ */
+export interface TemplateVersionDryRunMatrixResult {
+	readonly job: ProvisionerJob;
+	readonly matched_provisioners: MatchedProvisioners;
+	/**
+	 * ProvisionerVersions are the distinct versions of the provisioner
+	 * daemons matching the tags of the job.
+	 */
+	readonly provisioner_versions: readonly string[];
+	/**
+	 * WorkerVersion and WorkerAPIVersion are the versions of the provisioner
+	 * daemon that ran the dry-run.
+	 */
+	readonly worker_version?: string;
+	readonly worker_api_version?: string;
+	/**
+	 * Compatible is set once the dry-run has completed, and is true if it
+	 * succeeded. It is unset for canceled dry-runs.
+	 */
+	readonly compatible?: boolean;
 }
 
 // From codersdk/templateversions.go
@@ -8971,6 +10934,46 @@ export interface TemplateVersionExternalAuth {
 	readonly optional?: boolean;
 }
 
+// From codersdk/templateversionfiles.go
+/**
+ * TemplateVersionFile is a file or directory in the source of a template
+ * version.
+ */
+export interface TemplateVersionFile {
+	/**
+	 * Path is the slash-separated path of the entry relative to the root of
+	 * the template source.
+	 */
+	readonly path: string;
+	readonly type: TemplateVersionFileType;
+	/**
+	 * Size is the size of the file in bytes. It is zero for directories.
+	 */
+	readonly size: number;
+	/**
+	 * ContentType is the media type the file is served with. It is empty
+	 * for directories.
+	 */
+	readonly content_type?: string;
+	/**
+	 * Language is a syntax highlighting hint such as "hcl" or "markdown".
+	 * It is empty for directories and files in an unknown language.
+	 */
+	readonly language?: string;
+}
+
+// From codersdk/templateversionfiles.go
+/**
+ * TemplateVersionFileType is the type of an entry in the source of a
+ * template version.
+ */
+export type TemplateVersionFileType = "directory" | "file";
+
+export const TemplateVersionFileTypes: TemplateVersionFileType[] = [
+	"directory",
+	"file",
+];
+
 // From codersdk/templateversions.go
 /**
  * TemplateVersionParameter represents a parameter for a template version.
@@ -8997,6 +11000,22 @@ export interface TemplateVersionParameter {
 	readonly validation_monotonic?: ValidationMonotonicOrder;
 	readonly required: boolean;
 	readonly ephemeral: boolean;
+	readonly sensitive: boolean;
+	/**
+	 * Group is the section in which to present the parameter. Parameters
+	 * without a group are presented on their own.
+	 */
+	readonly group?: string;
+	/**
+	 * GroupOrder orders the groups of a form. All parameters of a group share
+	 * it.
+	 */
+	readonly group_order?: number;
+	/**
+	 * Page is the page of a multi-page form on which to present the
+	 * parameter. All parameters of a group share it.
+	 */
+	readonly page?: number;
 }
 
 // From codersdk/templateversions.go
@@ -9012,15 +11031,176 @@ export interface TemplateVersionParameterOption {
 
 // From codersdk/templateversions.go
 /**
- * TemplateVersionVariable represents a managed template variable.
+ * TemplateVersionParameterSchema is the JSON Schema of a single rich
+ * parameter. Values use the JSON type of the parameter rather than the string
+ * encoding used by workspace builds.
  */
-export interface TemplateVersionVariable {
-	readonly name: string;
-	readonly description: string;
+export interface TemplateVersionParameterSchema {
+	readonly title?: string;
+	readonly description?: string;
 	readonly type: string;
-	readonly value: string;
-	readonly default_value: string;
-	readonly required: boolean;
+	readonly items?: TemplateVersionParameterSchemaItems;
+	readonly uniqueItems?: boolean;
+	readonly enum?: readonly unknown[];
+	readonly default?: unknown;
+	readonly minimum?: number;
+	readonly maximum?: number;
+	readonly pattern?: string;
+	readonly writeOnly?: boolean;
+}
+
+// From codersdk/templateversions.go
+/**
+ * TemplateVersionParameterSchemaItems is the JSON Schema of the elements of a
+ * list(string) parameter.
+ */
+export interface TemplateVersionParameterSchemaItems {
+	readonly type: string;
+	readonly enum?: readonly string[];
+}
+
+// From codersdk/templateversions.go
+/**
+ * TemplateVersionParametersSchema is a JSON Schema document describing the
+ * rich parameters of a template version. Parameter values are expected as an
+ * object keyed by parameter name, as in a rich parameter file.
+ */
+export interface TemplateVersionParametersSchema {
+	readonly $schema: string;
+	readonly title: string;
+	readonly type: string;
+	readonly properties: Record<string, TemplateVersionParameterSchema>;
+	readonly required: readonly string[];
+	readonly additionalProperties: boolean;
+}
+
+// From codersdk/templateversionrollouts.go
+/**
+ * TemplateVersionRollout is a staged promotion of a template version. While
+ * it is in progress, builds of workspaces in the cohort of the current stage
+ * that use the active version get the rolled out version instead.
+ */
+export interface TemplateVersionRollout {
+	readonly id: string;
+	readonly template_id: string;
+	readonly template_version_id: string;
+	/**
+	 * PreviousVersionID is the active version of the template when the
+	 * rollout was created.
+	 */
+	readonly previous_version_id: string;
+	readonly group_id?: string;
+	readonly stages: readonly number[];
+	readonly current_stage: number;
+	readonly soak_duration_ms: number;
+	readonly max_failure_rate: number;
+	readonly min_builds: number;
+	readonly status: TemplateVersionRolloutStatus;
+	/**
+	 * StatusMessage explains why a rollout was completed, rolled back or
+	 * aborted.
+	 */
+	readonly status_message: string;
+	readonly created_by: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly stage_started_at: string;
+	readonly completed_at?: string;
+	/**
+	 * Builds is the number of completed start builds of the version in the
+	 * current stage.
+	 */
+	readonly builds: number;
+	/**
+	 * FailedBuilds is the number of failed start builds of the version in the
+	 * current stage.
+	 */
+	readonly failed_builds: number;
+}
+
+// From codersdk/templateversionrollouts.go
+export type TemplateVersionRolloutStatus =
+	| "aborted"
+	| "completed"
+	| "in_progress"
+	| "rolled_back";
+
+export const TemplateVersionRolloutStatuses: TemplateVersionRolloutStatus[] = [
+	"aborted",
+	"completed",
+	"in_progress",
+	"rolled_back",
+];
+
+// From codersdk/templateversionscans.go
+/**
+ * TemplateVersionScan is the result of submitting a template version's files
+ * and plan output to the deployment's template scanner.
+ */
+export interface TemplateVersionScan {
+	readonly template_version_id: string;
+	readonly status: TemplateVersionScanStatus;
+	readonly findings: readonly TemplateVersionScanFinding[];
+	/**
+	 * Error is set when the scanner could not be reached or returned an
+	 * invalid response.
+	 */
+	readonly error?: string;
+	readonly started_at: string;
+	readonly completed_at?: string;
+}
+
+// From codersdk/templateversionscans.go
+/**
+ * TemplateVersionScanFinding is a single issue reported by the template
+ * scanner.
+ */
+export interface TemplateVersionScanFinding {
+	readonly severity: TemplateVersionScanSeverity;
+	readonly rule_id: string;
+	readonly message: string;
+	/**
+	 * Path is the file in the template version the finding refers to, if any.
+	 */
+	readonly path?: string;
+}
+
+// From codersdk/templateversionscans.go
+export type TemplateVersionScanSeverity =
+	| "critical"
+	| "high"
+	| "info"
+	| "low"
+	| "medium";
+
+export const TemplateVersionScanSeverities: TemplateVersionScanSeverity[] = [
+	"critical",
+	"high",
+	"info",
+	"low",
+	"medium",
+];
+
+// From codersdk/templateversionscans.go
+export type TemplateVersionScanStatus = "completed" | "failed" | "running";
+
+export const TemplateVersionScanStatuses: TemplateVersionScanStatus[] = [
+	"completed",
+	"failed",
+	"running",
+];
+
+// From codersdk/templateversions.go
+/**
+ * TemplateVersionVariable represents a managed template variable.
+ */
+export interface TemplateVersionVariable {
+	readonly name: string;
+	readonly description: string;
+	readonly type: string;
+	readonly value: string;
+	readonly default_value: string;
+	readonly required: boolean;
 	readonly sensitive: boolean;
 }
 
@@ -9041,6 +11221,59 @@ export interface TemplateVersionsByTemplateRequest extends Pagination {
 	readonly include_archived: boolean;
 }
 
+// From codersdk/templateworkspacerestarts.go
+/**
+ * TemplateWorkspaceRestart is a batched restart of the outdated running
+ * workspaces of a template on the version that was active when it was
+ * created.
+ */
+export interface TemplateWorkspaceRestart {
+	readonly id: string;
+	readonly template_id: string;
+	readonly template_version_id: string;
+	readonly batch_size: number;
+	readonly batch_interval_ms: number;
+	readonly honor_quiet_hours: boolean;
+	readonly max_failure_rate: number;
+	readonly min_builds: number;
+	readonly status: TemplateWorkspaceRestartStatus;
+	/**
+	 * StatusMessage explains why a restart was paused, completed or aborted.
+	 */
+	readonly status_message: string;
+	readonly created_by: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly last_batch_at?: string;
+	readonly completed_at?: string;
+	/**
+	 * Restarted is the number of workspaces the restart has started a build
+	 * for, including builds that could not be created.
+	 */
+	readonly restarted: number;
+	readonly succeeded: number;
+	readonly failed: number;
+	/**
+	 * Pending is the number of restart builds that are not completed yet.
+	 */
+	readonly pending: number;
+	/**
+	 * Remaining is the number of outdated running workspaces that have not
+	 * been restarted yet.
+	 */
+	readonly remaining: number;
+}
+
+// From codersdk/templateworkspacerestarts.go
+export type TemplateWorkspaceRestartStatus =
+	| "aborted"
+	| "completed"
+	| "in_progress"
+	| "paused";
+
+export const TemplateWorkspaceRestartStatuses: TemplateWorkspaceRestartStatus[] =
+	["aborted", "completed", "in_progress", "paused"];
+
 // From codersdk/users.go
 export type TerminalFontName =
 	| "fira-code"
@@ -9127,6 +11360,13 @@ export interface TraceConfig {
 	readonly honeycomb_api_key: string;
 	readonly capture_logs: boolean;
 	readonly data_dog: boolean;
+	/**
+	 * WorkspaceBuilds enables exporting the timings of workspace builds as
+	 * traces. WorkspaceBuildsEndpoint optionally sends them to a dedicated
+	 * OTLP endpoint instead of the application tracing backend.
+	 */
+	readonly workspace_builds: boolean;
+	readonly workspace_builds_endpoint: string;
 }
 
 // From codersdk/templates.go
@@ -9457,11 +11697,38 @@ export interface UpdateMCPServerConfigRequest {
 	readonly forward_coder_headers?: boolean;
 }
 
+// From codersdk/networkpolicies.go
+/**
+ * UpdateNetworkPolicyRequest replaces the outbound network policy of a
+ * template or workspace. An empty policy denies all outbound traffic.
+ */
+export interface UpdateNetworkPolicyRequest {
+	readonly allowed_domains: readonly string[];
+	readonly allowed_cidrs: readonly string[];
+}
+
 // From codersdk/notifications.go
 export interface UpdateNotificationTemplateMethod {
 	readonly method?: string;
 }
 
+// From codersdk/notificationwebhooks.go
+/**
+ * UpdateNotificationTemplateWebhookRequest replaces the webhook overrides of
+ * a notification template.
+ */
+export interface UpdateNotificationTemplateWebhookRequest {
+	readonly endpoint?: string;
+	readonly payload_template?: string;
+	/**
+	 * SigningSecret is used to sign request bodies with HMAC-SHA256. The
+	 * signature is sent in the X-Coder-Signature header as "sha256=<hex>".
+	 * Omit it to keep the current secret, or set it to an empty string to
+	 * stop signing requests.
+	 */
+	readonly signing_secret?: string;
+}
+
 // From codersdk/organizations.go
 export interface UpdateOrganizationRequest {
 	readonly name?: string;
@@ -9475,11 +11742,35 @@ export interface UpdateOrganizationRequest {
 	readonly default_org_member_roles?: string[];
 }
 
+// From codersdk/provisionerdaemons.go
+export interface UpdateProvisionerDaemonSettingsRequest {
+	readonly tag_namespace: string;
+	readonly daemon_quota: number;
+}
+
 // From codersdk/users.go
 export interface UpdateRoles {
 	readonly roles: readonly string[];
 }
 
+// From codersdk/notifications.go
+export interface UpdateScopedNotificationPreference {
+	readonly notification_template_id: string;
+	readonly scope: NotificationPreferenceScope;
+	readonly target_id: string;
+	/**
+	 * Disabled mutes the notification template for the target. Omitting it
+	 * removes the preference, so that the preference for the notification
+	 * template applies again.
+	 */
+	readonly disabled?: boolean;
+}
+
+// From codersdk/notifications.go
+export interface UpdateScopedNotificationPreferences {
+	readonly preferences: readonly UpdateScopedNotificationPreference[];
+}
+
 // From codersdk/aitasks.go
 /**
  * UpdateTaskInputRequest is used to update a task's input.
@@ -9521,6 +11812,18 @@ export interface UpdateTemplateMeta {
 	 * but can be set to 0 to disable activity bumping.
 	 */
 	readonly activity_bump_ms?: number;
+	/**
+	 * ActivityBumpConnectionTypes restricts the types of connections that
+	 * bump the deadline of workspaces, e.g. to bump on SSH but not on web
+	 * apps. Set to an empty list to bump on all connections.
+	 */
+	readonly activity_bump_connection_types?: readonly ActivityBumpConnectionType[];
+	/**
+	 * ActivityBumpMaxPerDayMillis is the maximum cumulative amount activity
+	 * may extend the deadline of a workspace per day (UTC). Set to 0 for no
+	 * limit.
+	 */
+	readonly activity_bump_max_per_day_ms?: number;
 	/**
 	 * TimeTilAutostopNotifyMillis allows optionally specifying the duration
 	 * before the autostop deadline at which a reminder notification is sent for
@@ -9528,6 +11831,37 @@ export interface UpdateTemplateMeta {
 	 * the field keeps the existing value.
 	 */
 	readonly time_til_autostop_notify_ms?: number;
+	/**
+	 * NightlyStopTime is the time of day (HH:MM) at which running workspaces
+	 * are stopped regardless of activity. Set to the empty string to disable
+	 * it. It can only be set if your license includes the advanced template
+	 * scheduling feature.
+	 */
+	readonly nightly_stop_time?: string;
+	/**
+	 * MaxLifetimeMillis is how long after their creation workspaces are
+	 * stopped or deleted regardless of activity. It must be 0 (disabled) or at
+	 * least one day, and applies to existing workspaces too. Owners are warned
+	 * 7, 3 and 1 days before. It can only be set if your license includes the
+	 * advanced template scheduling feature.
+	 */
+	readonly max_lifetime_ms?: number;
+	readonly max_lifetime_action?: MaxLifetimeAction;
+	/**
+	 * IdleReclaimTTLMillis is how long running workspaces with a resource
+	 * matching IdleReclaimResourceSelector may be idle before they are
+	 * stopped. It must be 0 (disabled) or at least one minute, and requires a
+	 * resource selector. Idleness is measured from the usage the workspace
+	 * agents report. It can only be set if your license includes the advanced
+	 * template scheduling feature.
+	 */
+	readonly idle_reclaim_ttl_ms?: number;
+	/**
+	 * IdleReclaimResourceSelector is a key=value pair matched against the
+	 * metadata of workspace resources, e.g. "gpu=true", declared with the
+	 * coder_metadata resource.
+	 */
+	readonly idle_reclaim_resource_selector?: string;
 	/**
 	 * AutostopRequirement and AutostartRequirement can only be set if your license
 	 * includes the advanced template scheduling feature. If you attempt to set this
@@ -9568,6 +11902,13 @@ export interface UpdateTemplateMeta {
 	 * the template usable for new workspaces again.
 	 */
 	readonly deprecation_message?: string;
+	/**
+	 * DeprecationCutoff is applied together with DeprecationMessage. If set,
+	 * the deprecated template may still be used to create workspaces until
+	 * the cutoff, and builds return a deprecation warning. If unset, new
+	 * workspaces are blocked immediately.
+	 */
+	readonly deprecation_cutoff?: string;
 	/**
 	 * DisableEveryoneGroupAccess allows optionally disabling the default
 	 * behavior of granting the 'everyone' group access to use the template.
@@ -9591,6 +11932,86 @@ export interface UpdateTemplateMeta {
 	 * provisioning. It is recommended not to disable this.
 	 */
 	readonly disable_module_cache?: boolean;
+	/**
+	 * DisableProviderCache disables the use of the shared Terraform provider
+	 * cache of provisioners during workspace builds. Providers are then
+	 * installed from their registries on every build.
+	 */
+	readonly disable_provider_cache?: boolean;
+	/**
+	 * ProvisionerPlanTimeoutMillis and ProvisionerApplyTimeoutMillis override
+	 * the maximum duration of provisioner jobs for the template. 0 removes
+	 * the timeout.
+	 */
+	readonly provisioner_plan_timeout_ms?: number;
+	readonly provisioner_apply_timeout_ms?: number;
+	/**
+	 * TrialWorkspaceTTLMillis overrides the hard lifetime of workspaces
+	 * created from the template. It only applies to workspaces created after
+	 * the change. 0 disables the expiry.
+	 */
+	readonly trial_workspace_ttl_ms?: number;
+	/**
+	 * BuildLogRetentionMillis overrides how long the logs of workspace builds
+	 * of the template are kept. 0 uses the deployment-wide retention.
+	 */
+	readonly build_log_retention_ms?: number;
+	/**
+	 * PreBuildHookURL sets the webhook invoked before every workspace build
+	 * of the template. An empty string disables the hook.
+	 */
+	readonly pre_build_hook_url?: string;
+	/**
+	 * PostBuildHookURL sets the webhook invoked after every successful
+	 * workspace build of the template. An empty string disables the hook.
+	 */
+	readonly post_build_hook_url?: string;
+	/**
+	 * AutoAssignRegion controls whether workspaces created from the template
+	 * are assigned the region recommended for the creating client.
+	 */
+	readonly auto_assign_region?: boolean;
+	/**
+	 * NotifyBuildFailures controls whether workspace owners, and the
+	 * initiators of builds, are notified of every failed build.
+	 */
+	readonly notify_build_failures?: boolean;
+	/**
+	 * RequeueReapedBuilds controls whether workspace builds terminated by the
+	 * job reaper are automatically requeued once.
+	 */
+	readonly requeue_reaped_builds?: boolean;
+	/**
+	 * AgentRolloutChannel moves the workspace agents of the template to
+	 * another rollout channel. Running agents pick it up on their next start.
+	 */
+	readonly agent_rollout_channel?: AgentRolloutChannel;
+	/**
+	 * AllowTargetedBuilds controls whether workspace builds of the template
+	 * may set TargetResources.
+	 */
+	readonly allow_targeted_builds?: boolean;
+	/**
+	 * ReconfirmParameters replaces the list of parameters users must
+	 * explicitly set again when updating a workspace to a new template
+	 * version.
+	 */
+	readonly reconfirm_parameters?: readonly string[];
+}
+
+// From codersdk/presets.go
+/**
+ * UpdateTemplatePresetCohortsRequest replaces the preset cohorts of a
+ * template. Presets left out are made available to everyone.
+ */
+export interface UpdateTemplatePresetCohortsRequest {
+	readonly cohorts: readonly TemplatePresetCohort[];
+}
+
+// From codersdk/templatesecrets.go
+export interface UpdateTemplateSecretRequest {
+	readonly description?: string;
+	readonly value?: string;
 }
 
 // From codersdk/users.go
@@ -9668,6 +12089,7 @@ export interface UpdateUserPreferenceSettingsRequest {
 	readonly shell_tool_display_mode?: AgentDisplayMode;
 	readonly code_diff_display_mode?: AgentDisplayMode;
 	readonly agent_chat_send_shortcut?: AgentChatSendShortcut;
+	readonly workspace_self_requested_builds_allowed?: boolean;
 }
 
 // From codersdk/users.go
@@ -9700,6 +12122,12 @@ export interface UpdateUserQuietHoursScheduleRequest {
 	readonly schedule: string;
 }
 
+// From codersdk/userschedulepauses.go
+export interface UpdateUserSchedulePauseRequest {
+	readonly paused_until: string;
+	readonly pause_autostop: boolean;
+}
+
 // From codersdk/usersecrets.go
 /**
  * UpdateUserSecretRequest is the payload for partially updating a
@@ -9782,12 +12210,31 @@ export interface UpdateWorkspaceDormancy {
 	readonly dormant: boolean;
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspacePortForwardACL {
+	/**
+	 * UserPorts is a mapping from user UUIDs to the ports they may connect to.
+	 * The workspace must be shared with the users. To allow a user to connect
+	 * to any port again, use an empty list.
+	 */
+	readonly user_ports: Record<string, readonly number[]>;
+}
+
 // From codersdk/workspaceproxy.go
 export interface UpdateWorkspaceProxyResponse {
 	readonly proxy: WorkspaceProxy;
 	readonly proxy_token: string;
 }
 
+// From codersdk/workspaces.go
+/**
+ * UpdateWorkspaceReadOnly is a request to place a workspace in read-only mode
+ * or lift the mode.
+ */
+export interface UpdateWorkspaceReadOnly {
+	readonly read_only: boolean;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceRequest {
 	readonly name?: string;
@@ -10106,6 +12553,30 @@ export interface UserChatProviderConfig {
 	readonly byok_enabled: boolean;
 }
 
+// From codersdk/userimpersonations.go
+/**
+ * UserImpersonation is a request of support staff to act as a user. A token
+ * impersonating the user is issued once the request is granted.
+ */
+export interface UserImpersonation {
+	readonly id: string;
+	readonly user_id: string;
+	readonly impersonator_id: string;
+	readonly reason: string;
+	/**
+	 * Lifetime is how long the token issued for the request is valid.
+	 */
+	readonly lifetime: number;
+	/**
+	 * ConsentRequired is true when the impersonated user must consent to the
+	 * request before a token is issued.
+	 */
+	readonly consent_required: boolean;
+	readonly consented_at?: string;
+	readonly issued_at?: string;
+	readonly created_at: string;
+}
+
 // From codersdk/insights.go
 /**
  * UserLatency shows the connection latency for a user.
@@ -10164,6 +12635,39 @@ export interface UserPreferenceSettings {
 	readonly shell_tool_display_mode: AgentDisplayMode;
 	readonly code_diff_display_mode: AgentDisplayMode;
 	readonly agent_chat_send_shortcut: AgentChatSendShortcut;
+	/**
+	 * WorkspaceSelfRequestedBuildsAllowed allows the agents of the workspaces
+	 * of the user to stop or restart their own workspace, on behalf of
+	 * tooling running inside it.
+	 */
+	readonly workspace_self_requested_builds_allowed: boolean;
+}
+
+// From codersdk/templateuserpresets.go
+/**
+ * UserPreset is a named set of parameter values that a user saved for a
+ * template. Unlike template version presets, it is private to the user.
+ */
+export interface UserPreset {
+	readonly id: string;
+	readonly template_id: string;
+	readonly name: string;
+	readonly parameters: readonly WorkspaceBuildParameter[];
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/users.go
+/**
+ * UserProvisionerJob is a pending or running provisioner job started by a
+ * user.
+ */
+export interface UserProvisionerJob extends ProvisionerJob {
+	/**
+	 * CancelURL is the API path used to cancel the job. It is empty if the
+	 * job can no longer be canceled.
+	 */
+	readonly cancel_url?: string;
 }
 
 // From codersdk/deployment.go
@@ -10204,6 +12708,28 @@ export interface UserRoles {
 	readonly organization_roles: Record<string, string[]>;
 }
 
+// From codersdk/userschedulepauses.go
+/**
+ * UserSchedulePause suspends the autostart of all workspaces of a user until a
+ * time, for example while they are on leave. Autostarts that were due during
+ * the pause are skipped rather than run once it ends.
+ */
+export interface UserSchedulePause {
+	/**
+	 * Paused is true while the pause is in effect.
+	 */
+	readonly paused: boolean;
+	/**
+	 * PausedUntil is nil if the user never paused their schedules.
+	 */
+	readonly paused_until?: string;
+	/**
+	 * PauseAutostop pauses the autostop of workspaces at the deadline of their
+	 * builds as well. Dormancy and deletion are never paused.
+	 */
+	readonly pause_autostop: boolean;
+}
+
 // From codersdk/usersecrets.go
 /**
  * UserSecret represents a user secret's metadata. The secret value
@@ -10282,20 +12808,156 @@ export interface UserStatusChangeCount {
 
 export const UserStatuses: UserStatus[] = ["active", "dormant", "suspended"];
 
-// From codersdk/users.go
-export interface UsersRequest extends Pagination {
-	readonly q?: string;
+// From codersdk/userworkspacereports.go
+/**
+ * UserWorkspaceReport summarizes the workspaces of a user over a period, as
+ * sent weekly to the user.
+ */
+export interface UserWorkspaceReport {
+	readonly id: string;
+	readonly user_id: string;
+	readonly period_start: string;
+	readonly period_end: string;
+	readonly summary: UserWorkspaceReportSummary;
+	readonly created_at: string;
 }
 
-// From codersdk/users.go
-export interface ValidateUserPasswordRequest {
-	readonly password: string;
+// From codersdk/userworkspacereports.go
+export interface UserWorkspaceReportSummary {
+	/**
+	 * TotalBuilds and FailedBuilds count the builds of the workspaces of the
+	 * user that completed during the period.
+	 */
+	readonly total_builds: number;
+	readonly failed_builds: number;
+	/**
+	 * Workspaces are the workspaces that had builds during the period, or
+	 * that will be deleted or become dormant in the week after it.
+	 */
+	readonly workspaces: readonly UserWorkspaceReportWorkspace[];
 }
 
-// From codersdk/users.go
-export interface ValidateUserPasswordResponse {
-	readonly valid: boolean;
-	readonly details: string;
+// From codersdk/userworkspacereports.go
+export interface UserWorkspaceReportWorkspace {
+	readonly id: string;
+	readonly name: string;
+	readonly template_name: string;
+	readonly template_display_name: string;
+	readonly total_builds: number;
+	readonly failed_builds: number;
+	/**
+	 * DeletingAt is when the dormant workspace will be deleted, if that is
+	 * soon.
+	 */
+	readonly deleting_at?: string;
+	/**
+	 * DormantAt is when the workspace will become dormant unless it is used,
+	 * if that is soon.
+	 */
+	readonly dormant_at?: string;
+}
+
+// From codersdk/users.go
+export interface UsersRequest extends Pagination {
+	readonly q?: string;
+}
+
+// From codersdk/vcseventmappings.go
+export type VCSEventAction =
+	| "refresh_prebuilds"
+	| "start_workspace"
+	| "update_workspaces";
+
+export const VCSEventActions: VCSEventAction[] = [
+	"refresh_prebuilds",
+	"start_workspace",
+	"update_workspaces",
+];
+
+// From codersdk/vcseventmappings.go
+/**
+ * VCSEventMapping maps the pushes to a repository to an action on the
+ * workspaces of a template. Pushes are received from GitHub or GitLab
+ * webhooks, which must be signed with the signing secret of the mapping. The
+ * secret is never returned by the API.
+ */
+export interface VCSEventMapping {
+	readonly id: string;
+	readonly template_id: string;
+	readonly name: string;
+	/**
+	 * Repository is the full name of the repository, e.g. "coder/coder".
+	 */
+	readonly repository: string;
+	/**
+	 * Branch restricts the mapping to the pushes to a branch. Pushes to any
+	 * branch match when it's empty.
+	 */
+	readonly branch: string;
+	readonly action: VCSEventAction;
+	readonly workspace_id?: string;
+	readonly created_by: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly last_triggered_at?: string;
+}
+
+// From codersdk/vcseventmappings.go
+export interface VCSEventMappingResult {
+	readonly mapping_id: string;
+	readonly name: string;
+	readonly action: VCSEventAction;
+	readonly outcome: VCSEventOutcome;
+	readonly message?: string;
+}
+
+// From codersdk/vcseventmappings.go
+export type VCSEventOutcome = "failed" | "skipped" | "triggered";
+
+export const VCSEventOutcomes: VCSEventOutcome[] = [
+	"failed",
+	"skipped",
+	"triggered",
+];
+
+// From codersdk/vcseventmappings.go
+/**
+ * VCSEventResponse lists the outcome of the event for every mapping it was
+ * signed for.
+ */
+export interface VCSEventResponse {
+	readonly results: readonly VCSEventMappingResult[];
+}
+
+// From codersdk/templateversions.go
+/**
+ * ValidateTemplateVersionRequest statically validates uploaded template
+ * source code without creating a template version.
+ */
+export interface ValidateTemplateVersionRequest {
+	readonly file_id: string;
+	readonly user_variable_values?: readonly VariableValue[];
+}
+
+// From codersdk/templateversions.go
+/**
+ * ValidateTemplateVersionResponse contains the diagnostics found in the
+ * template source code. Valid is false if any diagnostic is an error.
+ */
+export interface ValidateTemplateVersionResponse {
+	readonly valid: boolean;
+	readonly diagnostics: readonly TemplateVersionDiagnostic[];
+}
+
+// From codersdk/users.go
+export interface ValidateUserPasswordRequest {
+	readonly password: string;
+}
+
+// From codersdk/users.go
+export interface ValidateUserPasswordResponse {
+	readonly valid: boolean;
+	readonly details: string;
 }
 
 // From codersdk/client.go
@@ -10411,6 +13073,32 @@ export interface Workspace {
 	readonly allow_renames: boolean;
 	readonly favorite: boolean;
 	readonly next_start_at: string | null;
+	/**
+	 * ExpiresAt is set for workspaces created from a template with a trial
+	 * workspace TTL or a max lifetime, and is the earliest of the two. Once it
+	 * passes, the workspace is stopped or deleted regardless of activity.
+	 */
+	readonly expires_at?: string;
+	/**
+	 * ArchivedAt being non-nil indicates a workspace that is archived. The
+	 * resources of an archived workspace have been destroyed, but its
+	 * parameters and metadata are kept. It must be unarchived before it can
+	 * be started.
+	 */
+	readonly archived_at?: string;
+	/**
+	 * ReadOnlyAt being non-nil indicates a workspace that was placed in
+	 * read-only mode during an investigation. Users can still connect to it,
+	 * but its agents block outbound git pushes and no new builds can be
+	 * created until the mode is lifted.
+	 */
+	readonly read_only_at?: string;
+	/**
+	 * DNSName is the stable DNS name registered for the workspace while it is
+	 * running. It is only set when the deployment has a workspace DNS domain
+	 * and the name has been registered with the DNS provider.
+	 */
+	readonly dns_name?: string;
 	/**
 	 * IsPrebuild indicates whether the workspace is a prebuilt workspace.
 	 * Prebuilt workspaces are owned by the prebuilds system user and have specific behavior,
@@ -10424,6 +13112,23 @@ export interface Workspace {
 	 */
 	readonly task_id?: string;
 	readonly shared_with?: readonly SharedWorkspaceActor[];
+	/**
+	 * DeprecationWarnings lists deprecations of the workspace's template and
+	 * the template version of its latest build.
+	 */
+	readonly deprecation_warnings?: readonly DeprecationWarning[];
+	/**
+	 * LatestBuildFailureSummary is set if the latest build failed. It
+	 * summarizes the root cause of the failure from the build logs, which is
+	 * usually more actionable than the error of the build job.
+	 */
+	readonly latest_build_failure_summary?: WorkspaceBuildFailureSummary;
+	/**
+	 * AssignedRegionID is the region the workspace was assigned when it was
+	 * created from a template with auto_assign_region set. It is the ID of a
+	 * workspace proxy, or the deployment ID for the primary region.
+	 */
+	readonly assigned_region_id?: string;
 }
 
 // From codersdk/workspaces.go
@@ -10457,6 +13162,31 @@ export interface WorkspaceAgent {
 	readonly expanded_directory?: string;
 	readonly version: string;
 	readonly api_version: string;
+	/**
+	 * RolloutChannel is the channel the agent binary is downloaded from on
+	 * the next workspace start. Compare Version against the channel's version
+	 * to find agents that have not restarted since a rollout. It is only
+	 * populated by the workspace and workspace build endpoints.
+	 */
+	readonly rollout_channel?: AgentRolloutChannel;
+	/**
+	 * BootstrapProgress is the latest milestone reported by the agent's
+	 * startup scripts. It is only populated by the workspace and workspace
+	 * build endpoints.
+	 */
+	readonly bootstrap_progress?: WorkspaceAgentBootstrapProgress;
+	/**
+	 * ClockSkewMilliseconds is the offset of the clock of the agent from the
+	 * clock of the Coder server, as last reported by the agent. Positive
+	 * values mean the clock of the agent is ahead. It is nil if the agent has
+	 * not reported it.
+	 */
+	readonly clock_skew_ms?: number;
+	/**
+	 * Services are the long-running processes supervised by the agent. They
+	 * are only populated by the workspace and workspace build endpoints.
+	 */
+	readonly services?: readonly WorkspaceAgentService[];
 	readonly apps: readonly WorkspaceApp[];
 	/**
 	 * DERPLatency is mapped by region name (e.g. "New York City", "Seattle").
@@ -10477,6 +13207,22 @@ export interface WorkspaceAgent {
 	readonly startup_script_behavior: WorkspaceAgentStartupScriptBehavior;
 }
 
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentBootstrapProgress is a named milestone reported by a startup
+ * script while the workspace is being bootstrapped, e.g. "cloning repo" at
+ * 40%.
+ */
+export interface WorkspaceAgentBootstrapProgress {
+	readonly name: string;
+	/**
+	 * Percent is omitted when the script did not report one.
+	 */
+	readonly percent?: number;
+	readonly message?: string;
+	readonly created_at: string;
+}
+
 // From codersdk/workspaceagents.go
 /**
  * WorkspaceAgentContainer describes a devcontainer of some sort
@@ -10594,6 +13340,13 @@ export type WorkspaceAgentDevcontainerStatus =
 export const WorkspaceAgentDevcontainerStatuses: WorkspaceAgentDevcontainerStatus[] =
 	["deleting", "error", "running", "starting", "stopped", "stopping"];
 
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentFileTransferMaxBytes is the largest file that can be
+ * uploaded to or downloaded from a workspace agent through coderd.
+ */
+export const WorkspaceAgentFileTransferMaxBytes = 104857600; // 100 MiB
+
 // From codersdk/workspaceagents.go
 /**
  * WorkspaceAgentGitClientMessage is a message sent from the client to
@@ -10714,6 +13467,40 @@ export interface WorkspaceAgentLogSource {
 	readonly icon: string;
 }
 
+// From codersdk/workspaceagentmemoryevents.go
+/**
+ * WorkspaceAgentMemoryEvent is an OOM kill or a period of severe memory
+ * pressure reported by a workspace agent.
+ */
+export interface WorkspaceAgentMemoryEvent {
+	readonly id: string;
+	readonly agent_id: string;
+	readonly created_at: string;
+	readonly kind: WorkspaceAgentMemoryEventKind;
+	/**
+	 * OOMKills is the number of processes killed by the OOM killer. It is
+	 * zero for memory pressure events.
+	 */
+	readonly oom_kills: number;
+	/**
+	 * MemoryPressure is the percentage of time in the last 10 seconds during
+	 * which all non-idle processes of the workspace were stalled on memory.
+	 */
+	readonly memory_pressure: number;
+}
+
+// From codersdk/workspaceagentmemoryevents.go
+/**
+ * WorkspaceAgentMemoryEventKind is the kind of a memory event reported by a
+ * workspace agent.
+ */
+export type WorkspaceAgentMemoryEventKind = "memory_pressure" | "oom_kill";
+
+export const WorkspaceAgentMemoryEventKinds: WorkspaceAgentMemoryEventKind[] = [
+	"memory_pressure",
+	"oom_kill",
+];
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentMetadata {
 	readonly result: WorkspaceAgentMetadataResult;
@@ -10734,6 +13521,43 @@ export interface WorkspaceAgentMetadataDescription {
 	readonly timeout: number;
 }
 
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentMetadataHistory lists the values a metadata key reported over
+ * time, oldest first. History is only recorded when the deployment retains it.
+ */
+export interface WorkspaceAgentMetadataHistory {
+	readonly key: string;
+	readonly since: string;
+	/**
+	 * IntervalSeconds is the width of the buckets the values were downsampled
+	 * to, keeping the latest value of each. Zero means every recorded value is
+	 * returned.
+	 */
+	readonly interval_seconds: number;
+	readonly values: readonly WorkspaceAgentMetadataHistoryValue[];
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentMetadataHistoryRequest {
+	/**
+	 * Since defaults to one hour ago.
+	 */
+	readonly since: string;
+	/**
+	 * Interval downsamples the history to the latest value per interval. Long
+	 * ranges are downsampled regardless.
+	 */
+	readonly interval: number;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentMetadataHistoryValue {
+	readonly collected_at: string;
+	readonly value: string;
+	readonly error: string;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentMetadataResult {
 	readonly collected_at: string;
@@ -10746,6 +13570,26 @@ export interface WorkspaceAgentMetadataResult {
 	readonly error: string;
 }
 
+// From codersdk/networkpolicies.go
+/**
+ * WorkspaceAgentNetworkPolicyViolation is a connection to a destination that
+ * is not allowed by the workspace network policy, as reported by the agent.
+ */
+export interface WorkspaceAgentNetworkPolicyViolation {
+	readonly id: string;
+	readonly agent_id: string;
+	readonly created_at: string;
+	/**
+	 * Destination is the hostname or IP address of the blocked connection.
+	 */
+	readonly destination: string;
+	readonly port?: number;
+	/**
+	 * Count is the number of blocked connections to the destination.
+	 */
+	readonly count: number;
+}
+
 // From codersdk/workspaceagentportshare.go
 export interface WorkspaceAgentPortShare {
 	readonly workspace_id: string;
@@ -10780,6 +13624,20 @@ export interface WorkspaceAgentPortShares {
 	readonly shares: readonly WorkspaceAgentPortShare[];
 }
 
+// From codersdk/workspaceenv.go
+/**
+ * WorkspaceAgentRefreshEnv describes the environment of a single agent after
+ * a refresh.
+ */
+export interface WorkspaceAgentRefreshEnv {
+	readonly agent_id: string;
+	/**
+	 * EnvironmentVariables are the names of the variables exported to new
+	 * sessions. Values are never returned.
+	 */
+	readonly environment_variables: readonly string[];
+}
+
 // From codersdk/workspaceagents.go
 /**
  * WorkspaceAgentRepoChanges describes the current state of a single
@@ -10825,6 +13683,57 @@ export const WorkspaceAgentScriptStatuses: WorkspaceAgentScriptStatus[] = [
 	"timed_out",
 ];
 
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentService is a long-running process, such as a dev server or a
+ * database, that is declared by the template and supervised by the agent.
+ */
+export interface WorkspaceAgentService {
+	readonly name: string;
+	readonly display_name: string;
+	readonly command: string;
+	readonly restart_policy: WorkspaceAgentServiceRestartPolicy;
+	readonly status: WorkspaceAgentServiceStatus;
+	readonly message?: string;
+	/**
+	 * ExitCode is the exit code of the last process of the service, if it
+	 * exited.
+	 */
+	readonly exit_code?: number;
+	readonly restart_count: number;
+	/**
+	 * UpdatedAt is when the agent last reported the status of the service.
+	 */
+	readonly updated_at?: string;
+}
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentServiceRestartPolicy =
+	| "always"
+	| "never"
+	| "on_failure";
+
+export const WorkspaceAgentServiceRestartPolicies: WorkspaceAgentServiceRestartPolicy[] =
+	["always", "never", "on_failure"];
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentServiceStatus =
+	| "failed"
+	| "pending"
+	| "restarting"
+	| "running"
+	| "starting"
+	| "stopped";
+
+export const WorkspaceAgentServiceStatuses: WorkspaceAgentServiceStatus[] = [
+	"failed",
+	"pending",
+	"restarting",
+	"running",
+	"starting",
+	"stopped",
+];
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentStartupScriptBehavior = "blocking" | "non-blocking";
 
@@ -10897,6 +13806,17 @@ export interface WorkspaceApp {
 	 * when hovering over workspace apps in the UI.
 	 */
 	readonly tooltip?: string;
+	/**
+	 * MaxSessionDurationSeconds is the maximum lifetime of a session used to
+	 * access the app. Once exceeded, the user has to reauthenticate. Zero
+	 * means unlimited.
+	 */
+	readonly max_session_duration_seconds?: number;
+	/**
+	 * SessionIdleTimeoutSeconds is the duration of inactivity after which a
+	 * session used to access the app expires. Zero means unlimited.
+	 */
+	readonly session_idle_timeout_seconds?: number;
 	/**
 	 * Statuses is a list of statuses for the app.
 	 */
@@ -10922,6 +13842,23 @@ export type WorkspaceAppOpenIn = "slim-window" | "tab";
 
 export const WorkspaceAppOpenIns: WorkspaceAppOpenIn[] = ["slim-window", "tab"];
 
+// From codersdk/workspaceappsharelinks.go
+/**
+ * WorkspaceAppShareLink grants visitors access to a workspace app without a
+ * Coder account. Visitors must authenticate with the OIDC provider of the
+ * deployment.
+ */
+export interface WorkspaceAppShareLink {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly agent_name: string;
+	readonly app_slug: string;
+	readonly created_by: string;
+	readonly created_at: string;
+	readonly expires_at: string;
+	readonly revoked_at?: string;
+}
+
 // From codersdk/workspaceapps.go
 export type WorkspaceAppSharingLevel =
 	| "authenticated"
@@ -11014,6 +13951,210 @@ export interface WorkspaceBuild {
 	 */
 	readonly has_ai_task?: boolean;
 	readonly has_external_agent?: boolean;
+	/**
+	 * ProvisionerTimeoutMillis is the apply timeout of the build's template
+	 * at the time of the request. 0 means the build is not subject to a
+	 * template timeout.
+	 */
+	readonly provisioner_timeout_ms: number;
+	/**
+	 * Annotations are notes users attached to the build, oldest first. They
+	 * are only populated by the workspace build endpoints.
+	 */
+	readonly annotations?: readonly WorkspaceBuildAnnotation[];
+	/**
+	 * ParameterChanges lists the rich parameters whose values differ from the
+	 * previous build of the workspace. It is empty for the first build.
+	 */
+	readonly parameter_changes?: readonly WorkspaceBuildParameterChange[];
+	/**
+	 * DeprecationWarnings lists deprecations of the build's template and
+	 * template version.
+	 */
+	readonly deprecation_warnings?: readonly DeprecationWarning[];
+	/**
+	 * LogsArchive is set once the build log retention removed the logs of
+	 * the build from the database.
+	 */
+	readonly logs_archive?: WorkspaceBuildLogsArchive;
+	/**
+	 * LogLevel is the provisioner log level the build was requested with.
+	 * It is empty for builds with the default verbosity.
+	 */
+	readonly log_level?: ProvisionerLogLevel;
+}
+
+// From codersdk/workspacebuilds.go
+/**
+ * WorkspaceBuildAnnotation is a note a user attached to a workspace build,
+ * e.g. "rebuilt to pick up CVE fix".
+ */
+export interface WorkspaceBuildAnnotation {
+	readonly id: string;
+	readonly workspace_build_id: string;
+	readonly user_id: string;
+	readonly username: string;
+	readonly note: string;
+	readonly created_at: string;
+}
+
+// From codersdk/workspaces.go
+/**
+ * WorkspaceBuildFailureSummary is the root cause of a failed workspace build.
+ */
+export interface WorkspaceBuildFailureSummary {
+	/**
+	 * Message is the most relevant error of the build, e.g. the error
+	 * reported by a Terraform provider.
+	 */
+	readonly message: string;
+	/**
+	 * Detail elaborates on Message, if available.
+	 */
+	readonly detail?: string;
+	/**
+	 * ResourceAddress is the address of the resource the build failed for,
+	 * if known, e.g. "aws_instance.dev".
+	 */
+	readonly resource_address?: string;
+}
+
+// From codersdk/workspacebuilds.go
+/**
+ * WorkspaceBuildInputDeployment describes the deployment a workspace build
+ * was provisioned by.
+ */
+export interface WorkspaceBuildInputDeployment {
+	readonly coder_version: string;
+	readonly organization_id: string;
+	readonly provisioner: ProvisionerType;
+	readonly provisioner_daemon_id: string;
+}
+
+// From codersdk/workspacebuilds.go
+/**
+ * WorkspaceBuildInputMetadata is the workspace metadata a build exposed to the
+ * template through the coder_workspace, coder_workspace_owner and coder_task
+ * data sources.
+ */
+export interface WorkspaceBuildInputMetadata {
+	readonly access_url: string;
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly workspace_owner_id: string;
+	readonly workspace_owner_username: string;
+	readonly workspace_owner_name: string;
+	readonly workspace_owner_email: string;
+	readonly workspace_owner_login_type: string;
+	readonly workspace_owner_groups: readonly string[];
+	readonly workspace_owner_roles: readonly SlimRole[];
+	readonly template_name: string;
+	readonly template_version_name: string;
+	/**
+	 * ExternalAuthProviders are the external auth providers the owner was
+	 * authenticated with. Their tokens are not recorded.
+	 */
+	readonly external_auth_providers: readonly string[];
+	readonly prebuilt_workspace_build_stage?: string;
+	readonly task_id?: string;
+	readonly task_prompt?: string;
+}
+
+// From codersdk/workspacebuilds.go
+/**
+ * WorkspaceBuildInputVariable is the value of a template variable used by a
+ * workspace build.
+ */
+export interface WorkspaceBuildInputVariable {
+	readonly name: string;
+	/**
+	 * Value is empty for sensitive variables.
+	 */
+	readonly value: string;
+	readonly sensitive: boolean;
+}
+
+// From codersdk/workspacebuilds.go
+/**
+ * WorkspaceBuildInputs is an immutable record of everything a workspace build
+ * was provisioned with, captured when its provisioner job is acquired. It
+ * allows reproducing a build in another deployment. Secrets are never
+ * included: sensitive template variables are redacted, and the tokens and
+ * keys passed to the provisioner are left out.
+ */
+export interface WorkspaceBuildInputs {
+	readonly workspace_build_id: string;
+	readonly template_id: string;
+	readonly template_version_id: string;
+	/**
+	 * TemplateVersionHash is the SHA256 hash of the template version source
+	 * archive.
+	 */
+	readonly template_version_hash: string;
+	/**
+	 * TemplateVersionModulesFileID is the file of cached Terraform modules,
+	 * if the template version has one.
+	 */
+	readonly template_version_modules_file_id?: string;
+	readonly transition: WorkspaceTransition;
+	readonly reason: BuildReason;
+	readonly log_level?: string;
+	/**
+	 * Parameters are the parameter values of the build. The values of
+	 * sensitive parameters are empty, here and in PreviousParameters.
+	 */
+	readonly parameters: readonly WorkspaceBuildParameter[];
+	/**
+	 * PreviousParameters are the parameter values of the previous build of
+	 * the workspace.
+	 */
+	readonly previous_parameters: readonly WorkspaceBuildParameter[];
+	readonly variables: readonly WorkspaceBuildInputVariable[];
+	readonly provisioner_tags: Record<string, string>;
+	/**
+	 * TargetResources limits the build to these Terraform resource addresses.
+	 */
+	readonly target_resources?: readonly string[];
+	readonly metadata: WorkspaceBuildInputMetadata;
+	readonly deployment: WorkspaceBuildInputDeployment;
+	readonly created_at: string;
+}
+
+// From codersdk/workspacebuilds.go
+/**
+ * WorkspaceBuildLogStage is a provisioning stage of a build, and the range
+ * of the logs written during it.
+ */
+export interface WorkspaceBuildLogStage {
+	readonly stage: TimingStage;
+	readonly started_at: string;
+	readonly ended_at: string;
+	/**
+	 * FirstLogID and LastLogID are the IDs of the first and last logs of the
+	 * stage. They are omitted if the stage wrote no logs.
+	 */
+	readonly first_log_id?: number;
+	readonly last_log_id?: number;
+	readonly log_count: number;
+}
+
+// From codersdk/workspacebuilds.go
+/**
+ * WorkspaceBuildLogsArchive describes the logs of a workspace build that were
+ * removed from the database by the build log retention.
+ */
+export interface WorkspaceBuildLogsArchive {
+	readonly removed_at: string;
+	/**
+	 * Archived is true if the logs were archived before they were removed.
+	 * Otherwise they were deleted and cannot be retrieved.
+	 */
+	readonly archived: boolean;
+	/**
+	 * Path is the API path the archived logs can be retrieved from. It is
+	 * empty if the logs were not archived.
+	 */
+	readonly path?: string;
 }
 
 // From codersdk/workspacebuilds.go
@@ -11025,6 +14166,30 @@ export interface WorkspaceBuildParameter {
 	readonly value: string;
 }
 
+// From codersdk/workspacebuilds.go
+/**
+ * WorkspaceBuildParameterChange describes how a rich parameter value changed
+ * between a build and the previous build of the same workspace.
+ */
+export interface WorkspaceBuildParameterChange {
+	readonly name: string;
+	readonly kind: WorkspaceBuildParameterChangeKind;
+	readonly previous_value: string;
+	readonly new_value: string;
+	/**
+	 * Redacted is true when the values are withheld because the parameter
+	 * is ephemeral or sensitive. PreviousValue and NewValue are empty in
+	 * that case.
+	 */
+	readonly redacted: boolean;
+}
+
+// From codersdk/workspacebuilds.go
+export type WorkspaceBuildParameterChangeKind = "added" | "modified" | "removed";
+
+export const WorkspaceBuildParameterChangeKinds: WorkspaceBuildParameterChangeKind[] =
+	["added", "modified", "removed"];
+
 // From codersdk/workspacebuilds.go
 export interface WorkspaceBuildTimings {
 	readonly provisioner_timings: readonly ProvisionerTiming[];
@@ -11034,6 +14199,12 @@ export interface WorkspaceBuildTimings {
 	 */
 	readonly agent_script_timings: readonly AgentScriptTiming[];
 	readonly agent_connection_timings: readonly AgentConnectionTiming[];
+	readonly agent_service_timings: readonly AgentServiceTiming[];
+	/**
+	 * CacheStats summarizes the provisioner timings of artifacts obtained
+	 * during the init stage by whether they were reused from a cache.
+	 */
+	readonly cache_stats: readonly ProvisionerCacheStats[];
 }
 
 // From codersdk/workspaces.go
@@ -11063,12 +14234,83 @@ export interface WorkspaceBuildsRequest extends Pagination {
 	readonly since?: string;
 }
 
+// From codersdk/workspaces.go
+/**
+ * WorkspaceBumpResponse is the deadline of the active workspace build after a
+ * bump.
+ */
+export interface WorkspaceBumpResponse {
+	readonly deadline: string;
+	/**
+	 * MaxDeadline is the deadline the workspace cannot be bumped past, if
+	 * any.
+	 */
+	readonly max_deadline?: string;
+}
+
+// From codersdk/workspacecloudresources.go
+/**
+ * WorkspaceCloudResource is a resource at a cloud provider that was reported
+ * by a build of a workspace.
+ */
+export interface WorkspaceCloudResource {
+	readonly workspace_id: string;
+	readonly type: string;
+	readonly name: string;
+	/**
+	 * CloudID is the identifier of the resource at its cloud provider.
+	 */
+	readonly cloud_id: string;
+	/**
+	 * WorkspaceBuildID is the latest build that reported the resource.
+	 */
+	readonly workspace_build_id: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly verification_status: CloudResourceVerificationStatus;
+	/**
+	 * VerificationBuildID is the deletion build after which the resource was
+	 * verified.
+	 */
+	readonly verification_build_id?: string;
+	/**
+	 * VerificationError explains why the verification status is unknown.
+	 */
+	readonly verification_error?: string;
+	readonly verified_at?: string;
+}
+
 // From codersdk/deployment.go
 export interface WorkspaceConnectionLatencyMS {
 	readonly P50: number;
 	readonly P95: number;
 }
 
+// From codersdk/workspacecosts.go
+/**
+ * WorkspaceCost is the cost a workspace accrued in a UTC calendar month, in
+ * the same units as the daily cost of workspace resources. It is enforced
+ * against the deployment's monthly cost budgets.
+ */
+export interface WorkspaceCost {
+	readonly workspace_id: string;
+	/**
+	 * Month is the start of the UTC calendar month.
+	 */
+	readonly month: string;
+	readonly cost: number;
+	readonly source: WorkspaceCostSource;
+	readonly updated_at: string;
+}
+
+// From codersdk/workspacecosts.go
+export type WorkspaceCostSource = "billing_export" | "estimate";
+
+export const WorkspaceCostSources: WorkspaceCostSource[] = [
+	"billing_export",
+	"estimate",
+];
+
 // From codersdk/deployment.go
 export interface WorkspaceDeploymentStats {
 	readonly pending: number;
@@ -11081,6 +14323,118 @@ export interface WorkspaceDeploymentStats {
 	readonly tx_bytes: number;
 }
 
+// From codersdk/workspacedormancyexemptions.go
+/**
+ * WorkspaceDormancyExemption exempts a workspace from the template's
+ * time_til_dormant policy until it expires.
+ */
+export interface WorkspaceDormancyExemption {
+	readonly workspace_id: string;
+	readonly reason: string;
+	readonly expires_at: string;
+	/**
+	 * ApprovedBy is the template administrator that granted the exemption.
+	 */
+	readonly approved_by: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/workspacedormancyhooks.go
+/**
+ * WorkspaceDormancyHook is the latest invocation of the deployment's dormancy
+ * hook for a workspace. A dormant workspace is only activated once its
+ * activate hook succeeded.
+ */
+export interface WorkspaceDormancyHook {
+	readonly workspace_id: string;
+	readonly event: WorkspaceDormancyHookEvent;
+	readonly status: WorkspaceDormancyHookStatus;
+	/**
+	 * Error is the error returned by the hook if it failed.
+	 */
+	readonly error?: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/workspacedormancyhooks.go
+export type WorkspaceDormancyHookEvent = "activate" | "dormant";
+
+export const WorkspaceDormancyHookEvents: WorkspaceDormancyHookEvent[] = [
+	"activate",
+	"dormant",
+];
+
+// From codersdk/workspacedormancyhooks.go
+export type WorkspaceDormancyHookStatus =
+	| "failed"
+	| "pending"
+	| "running"
+	| "succeeded";
+
+export const WorkspaceDormancyHookStatuses: WorkspaceDormancyHookStatus[] = [
+	"failed",
+	"pending",
+	"running",
+	"succeeded",
+];
+
+// From codersdk/insights.go
+/**
+ * WorkspaceEgress is the traffic of a single workspace on a single day. Rx is
+ * traffic received by the workspace, Tx is traffic sent by it.
+ */
+export interface WorkspaceEgress {
+	readonly date: string;
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly owner_id: string;
+	readonly owner_username: string;
+	readonly template_id: string;
+	readonly template_name: string;
+	/**
+	 * SSH covers SSH sessions, including VS Code and JetBrains.
+	 */
+	readonly ssh: EgressBytes;
+	/**
+	 * ReconnectingPTY covers the web terminal.
+	 */
+	readonly reconnecting_pty: EgressBytes;
+	/**
+	 * PortForward covers port forwarding and workspace apps.
+	 */
+	readonly port_forward: EgressBytes;
+}
+
+// From codersdk/insights.go
+/**
+ * WorkspaceEgressInsightsReport lists the bytes moved by workspace agents per
+ * workspace and UTC day, split by connection class.
+ */
+export interface WorkspaceEgressInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly workspaces: readonly WorkspaceEgress[];
+}
+
+// From codersdk/insights.go
+export interface WorkspaceEgressInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+}
+
+// From codersdk/insights.go
+/**
+ * WorkspaceEgressInsightsResponse is the response from the workspace egress
+ * insights endpoint.
+ */
+export interface WorkspaceEgressInsightsResponse {
+	readonly report: WorkspaceEgressInsightsReport;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceFilter {
 	/**
@@ -11100,11 +14454,68 @@ export interface WorkspaceHealth {
 	readonly failing_agents: readonly string[]; // FailingAgents lists the IDs of the agents that are failing, if any.
 }
 
+// From codersdk/workspaceleases.go
+/**
+ * WorkspaceLease is a time-bound lease on an ephemeral workspace, typically
+ * held by a CI job.
+ */
+export interface WorkspaceLease {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly template_id: string;
+	readonly owner_id: string;
+	readonly created_at: string;
+	readonly expires_at: string;
+	readonly released_at?: string;
+}
+
+// From codersdk/templates.go
+/**
+ * WorkspaceNamePolicy describes the names accepted for new workspaces.
+ * Names must also be unique among the owner's workspaces.
+ */
+export interface WorkspaceNamePolicy {
+	readonly pattern: string;
+	readonly max_length: number;
+	readonly reserved_names: readonly string[];
+}
+
+// From codersdk/networkpolicies.go
+/**
+ * WorkspaceNetworkPolicy is the effective outbound network policy of a
+ * workspace. Enforcement agents such as CNI plugins or egress proxies should
+ * deny any destination that is not allowed, unless Source is "none".
+ */
+export interface WorkspaceNetworkPolicy {
+	readonly workspace_id: string;
+	readonly source: NetworkPolicySource;
+	readonly allowed_domains: readonly string[];
+	readonly allowed_cidrs: readonly string[];
+	/**
+	 * UpdatedAt is omitted when Source is "none".
+	 */
+	readonly updated_at?: string;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceOptions {
 	readonly include_deleted?: boolean;
 }
 
+// From codersdk/workspaces.go
+/**
+ * WorkspacePortForwardACL lists the users of a shared workspace that may only
+ * connect to some ports of the workspace.
+ */
+export interface WorkspacePortForwardACL {
+	readonly users: readonly WorkspacePortForwardACLUser[];
+}
+
+// From codersdk/workspaces.go
+export interface WorkspacePortForwardACLUser extends MinimalUser {
+	readonly ports: readonly number[];
+}
+
 // From codersdk/workspaceproxy.go
 export interface WorkspaceProxy extends Region {
 	readonly derp_enabled: boolean;
@@ -11161,6 +14572,15 @@ export interface WorkspaceQuota {
 	readonly budget: number;
 }
 
+// From codersdk/workspaceenv.go
+/**
+ * WorkspaceRefreshEnvResponse is returned once all agents of the workspace
+ * have refreshed their environment.
+ */
+export interface WorkspaceRefreshEnvResponse {
+	readonly agents: readonly WorkspaceAgentRefreshEnv[];
+}
+
 // From codersdk/workspacebuilds.go
 /**
  * WorkspaceResource describes resources used to create a workspace, for instance:
@@ -11195,6 +14615,18 @@ export type WorkspaceRole = "admin" | "" | "use";
 
 export const WorkspaceRoles: WorkspaceRole[] = ["admin", "", "use"];
 
+// From codersdk/templates.go
+/**
+ * WorkspaceSchedulePolicy describes whether users may configure the
+ * schedule of workspaces created from the template.
+ */
+export interface WorkspaceSchedulePolicy {
+	readonly autostart_editable: boolean;
+	readonly autostop_editable: boolean;
+	readonly autostart_requirement: TemplateAutostartRequirement;
+	readonly autostop_requirement: TemplateAutostopRequirement;
+}
+
 // From codersdk/workspacesharing.go
 /**
  * WorkspaceSharingSettings represents workspace sharing settings affecting an
@@ -11219,6 +14651,29 @@ export interface WorkspaceSharingSettings {
 	readonly shareable_workspace_owners: ShareableWorkspaceOwners;
 }
 
+// From codersdk/workspaces.go
+/**
+ * WorkspaceSort is an ordering of workspaces, set with the "sort:" term of the
+ * workspaces filter query. By default, favorite workspaces of the requester
+ * come first, followed by running workspaces, ordered by owner and name.
+ */
+export type WorkspaceSort =
+	| "created_at_asc"
+	| "created_at_desc"
+	| "last_used_asc"
+	| "last_used_desc"
+	| "name_asc"
+	| "name_desc";
+
+export const WorkspaceSorts: WorkspaceSort[] = [
+	"created_at_asc",
+	"created_at_desc",
+	"last_used_asc",
+	"last_used_desc",
+	"name_asc",
+	"name_desc",
+];
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
 	| "canceled"
@@ -11245,6 +14700,49 @@ export const WorkspaceStatuses: WorkspaceStatus[] = [
 	"stopping",
 ];
 
+// From codersdk/workspacesupportbundles.go
+/**
+ * WorkspaceSupportBundle is a diagnostics bundle collected from the agents
+ * of a workspace. The zip archive can be downloaded with Client.Download
+ * using FileID.
+ */
+export interface WorkspaceSupportBundle {
+	readonly file_id: string;
+	readonly created_at: string;
+	readonly agents: readonly WorkspaceSupportBundleAgent[];
+}
+
+// From codersdk/workspacesupportbundles.go
+/**
+ * WorkspaceSupportBundleAgent is the collection result of a single agent.
+ */
+export interface WorkspaceSupportBundleAgent {
+	readonly id: string;
+	readonly name: string;
+	readonly collected: boolean;
+	/**
+	 * Error is set if the bundle of the agent could not be collected.
+	 */
+	readonly error?: string;
+}
+
+// From codersdk/templates.go
+/**
+ * WorkspaceTTLPolicy describes the TTL values accepted for new workspaces.
+ * DefaultMillis is applied when no TTL is provided.
+ */
+export interface WorkspaceTTLPolicy {
+	readonly default_ms: number;
+	readonly min_ms: number;
+	readonly max_ms: number;
+	readonly activity_bump_ms: number;
+	/**
+	 * TrialWorkspaceTTLMillis is the hard lifetime of new workspaces. 0 means
+	 * workspaces do not expire.
+	 */
+	readonly trial_workspace_ttl_ms: number;
+}
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceTransition = "delete" | "start" | "stop";
 
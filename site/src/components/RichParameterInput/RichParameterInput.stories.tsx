@@ -32,6 +32,7 @@ const createTemplateVersionParameter = (
 		description_plaintext: "",
 		required: true,
 		ephemeral: false,
+		sensitive: false,
 		...partial,
 	};
 };
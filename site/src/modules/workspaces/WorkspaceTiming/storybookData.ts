@@ -456,4 +456,9 @@ export const WorkspaceTimingsResponse: WorkspaceBuildTimings = {
 			workspace_agent_name: "dev",
 		},
 	],
+	agent_service_timings: [],
+	cache_stats: [
+		{ artifact: "provider_plugin", hits: 2, misses: 1 },
+		{ artifact: "module", hits: 0, misses: 0 },
+	],
 };
@@ -13,6 +13,7 @@ const preferencesData = {
 	shell_tool_display_mode: "auto" as const,
 	code_diff_display_mode: "auto" as const,
 	agent_chat_send_shortcut: "enter" as const,
+	workspace_self_requested_builds_allowed: false,
 };
 
 const baseArgs: AgentSettingsGeneralPageViewProps = {
@@ -2240,6 +2240,7 @@ export const ToolDisplayModesFromPreferences: Story = {
 					shell_tool_display_mode: "always_collapsed" as const,
 					code_diff_display_mode: "always_collapsed" as const,
 					agent_chat_send_shortcut: "enter" as const,
+					workspace_self_requested_builds_allowed: false,
 				},
 			},
 		],
@@ -2339,6 +2340,7 @@ export const ThinkingBlockAlwaysExpanded: Story = {
 					shell_tool_display_mode: "auto" as const,
 					code_diff_display_mode: "auto" as const,
 					agent_chat_send_shortcut: "enter" as const,
+					workspace_self_requested_builds_allowed: false,
 				},
 			},
 		],
@@ -2400,6 +2402,7 @@ export const ThinkingBlockAlwaysCollapsed: Story = {
 					shell_tool_display_mode: "auto" as const,
 					code_diff_display_mode: "auto" as const,
 					agent_chat_send_shortcut: "enter" as const,
+					workspace_self_requested_builds_allowed: false,
 				},
 			},
 		],
@@ -2608,6 +2611,7 @@ export const ThinkingBlockWithToolCall: Story = {
 					shell_tool_display_mode: "auto" as const,
 					code_diff_display_mode: "auto" as const,
 					agent_chat_send_shortcut: "enter" as const,
+					workspace_self_requested_builds_allowed: false,
 				},
 			},
 		],
@@ -2685,6 +2689,7 @@ export const ThinkingBlockWithShellTools: Story = {
 					shell_tool_display_mode: "always_collapsed" as const,
 					code_diff_display_mode: "auto" as const,
 					agent_chat_send_shortcut: "enter" as const,
+					workspace_self_requested_builds_allowed: false,
 				},
 			},
 		],
@@ -2796,6 +2801,7 @@ export const ThinkingBlockAutoMode: Story = {
 					shell_tool_display_mode: "auto" as const,
 					code_diff_display_mode: "auto" as const,
 					agent_chat_send_shortcut: "enter" as const,
+					workspace_self_requested_builds_allowed: false,
 				},
 			},
 		],
@@ -2850,6 +2856,7 @@ export const ThinkingBlockPreviewMode: Story = {
 					shell_tool_display_mode: "auto" as const,
 					code_diff_display_mode: "auto" as const,
 					agent_chat_send_shortcut: "enter" as const,
+					workspace_self_requested_builds_allowed: false,
 				},
 			},
 		],
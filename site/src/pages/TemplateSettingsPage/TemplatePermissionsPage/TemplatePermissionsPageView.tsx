@@ -164,6 +164,16 @@ const RoleSelect: FC<RoleSelectProps> = ({
 						Can read and use this template to create workspaces.
 					</div>
 				</SelectItem>
+				<SelectItem
+					value="manage"
+					className="w-[250px] flex-col items-start py-2"
+				>
+					<div className="text-content-primary">Manage</div>
+					<div className="text-xs leading-[140%] text-content-secondary">
+						Can modify this template including permissions, settings, and
+						template versions, but cannot delete it.
+					</div>
+				</SelectItem>
 				<SelectItem
 					value="admin"
 					className="w-[250px] flex-col items-start py-2"
@@ -218,6 +218,7 @@ export const EnablingTaskNotificationClearsAlertDismissal: Story = {
 					shell_tool_display_mode: "auto" as const,
 					code_diff_display_mode: "auto" as const,
 					agent_chat_send_shortcut: "enter" as const,
+					workspace_self_requested_builds_allowed: false,
 				},
 			},
 		],
@@ -244,6 +245,7 @@ export const EnablingTaskNotificationClearsAlertDismissal: Story = {
 			shell_tool_display_mode: "auto",
 			code_diff_display_mode: "auto",
 			agent_chat_send_shortcut: "enter" as const,
+			workspace_self_requested_builds_allowed: false,
 		});
 
 		await step("Enable Task Idle notification", async () => {
@@ -40,12 +40,24 @@ const WorkspaceParametersPage: FC = () => {
 
 	// autofill the form with the workspace build parameters from the latest build
 	//  CLEANUP(bad-vibes): raw query options
+	// Sensitive values are only included for users that can rebuild the
+	// workspace, otherwise they are redacted.
+	const includeSensitive = Boolean(permissions?.updateWorkspace);
 	const {
 		data: latestBuildParameters,
 		isLoading: latestBuildParametersLoading,
 	} = useQuery({
-		queryKey: ["workspaceBuilds", workspace.latest_build.id, "parameters"],
-		queryFn: () => API.getWorkspaceBuildParameters(workspace.latest_build.id),
+		queryKey: [
+			"workspaceBuilds",
+			workspace.latest_build.id,
+			"parameters",
+			includeSensitive,
+		],
+		queryFn: () =>
+			API.getWorkspaceBuildParameters(
+				workspace.latest_build.id,
+				includeSensitive,
+			),
 	});
 
 	const [latestResponse, setLatestResponse] =
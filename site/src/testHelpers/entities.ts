@@ -89,6 +89,7 @@ export const MockToken: TypesGen.APIKeyWithOwner = {
 	scope: "all",
 	scopes: ["coder:all"],
 	allow_list: [{ type: "*", id: "*" }],
+	template_ids: [],
 	lifetime_seconds: 2592000,
 	token_name: "token-one",
 	username: "admin",
@@ -107,6 +108,7 @@ export const MockTokens: TypesGen.APIKeyWithOwner[] = [
 		scope: "all",
 		scopes: ["coder:all"],
 		allow_list: [{ type: "*", id: "*" }],
+		template_ids: [],
 		lifetime_seconds: 2592000,
 		token_name: "token-two",
 		username: "admin",
@@ -900,7 +902,14 @@ export const MockTemplate: TypesGen.Template = {
 	description: "This is a test description.",
 	default_ttl_ms: 24 * 60 * 60 * 1000,
 	activity_bump_ms: 1 * 60 * 60 * 1000,
+	activity_bump_connection_types: [],
+	activity_bump_max_per_day_ms: 0,
 	time_til_autostop_notify_ms: 0,
+	nightly_stop_time: "",
+	max_lifetime_ms: 0,
+	max_lifetime_action: "delete",
+	idle_reclaim_ttl_ms: 0,
+	idle_reclaim_resource_selector: "",
 	autostop_requirement: {
 		days_of_week: ["sunday"],
 		weeks: 1,
@@ -933,6 +942,19 @@ export const MockTemplate: TypesGen.Template = {
 	use_classic_parameter_flow: false,
 	cors_behavior: "simple",
 	disable_module_cache: false,
+	disable_provider_cache: false,
+	provisioner_plan_timeout_ms: 0,
+	provisioner_apply_timeout_ms: 0,
+	trial_workspace_ttl_ms: 0,
+	build_log_retention_ms: 0,
+	pre_build_hook_url: "",
+	post_build_hook_url: "",
+	auto_assign_region: false,
+	notify_build_failures: false,
+	requeue_reaped_builds: false,
+	agent_rollout_channel: "stable",
+	allow_targeted_builds: false,
+	reconfirm_parameters: [],
 };
 
 const _MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -1465,6 +1487,7 @@ export const MockWorkspaceBuild: TypesGen.WorkspaceBuild = {
 		available: 1,
 	},
 	template_version_preset_id: null,
+	provisioner_timeout_ms: 0,
 };
 
 const MockWorkspaceBuildAutostart: TypesGen.WorkspaceBuild = {
@@ -1489,6 +1512,7 @@ const MockWorkspaceBuildAutostart: TypesGen.WorkspaceBuild = {
 	status: "running",
 	daily_cost: 20,
 	template_version_preset_id: null,
+	provisioner_timeout_ms: 0,
 };
 
 const MockWorkspaceBuildAutostop: TypesGen.WorkspaceBuild = {
@@ -1513,6 +1537,7 @@ const MockWorkspaceBuildAutostop: TypesGen.WorkspaceBuild = {
 	status: "running",
 	daily_cost: 20,
 	template_version_preset_id: null,
+	provisioner_timeout_ms: 0,
 };
 
 export const MockFailedWorkspaceBuild = (
@@ -1539,6 +1564,7 @@ export const MockFailedWorkspaceBuild = (
 	status: "failed",
 	daily_cost: 20,
 	template_version_preset_id: null,
+	provisioner_timeout_ms: 0,
 });
 
 export const MockWorkspaceBuildStop: TypesGen.WorkspaceBuild = {
@@ -1866,6 +1892,7 @@ export const MockTemplateVersionParameter1: TypesGen.TemplateVersionParameter =
 		options: [],
 		required: true,
 		ephemeral: false,
+		sensitive: false,
 	};
 
 export const MockTemplateVersionParameter2: TypesGen.TemplateVersionParameter =
@@ -1884,6 +1911,7 @@ export const MockTemplateVersionParameter2: TypesGen.TemplateVersionParameter =
 		validation_monotonic: "increasing",
 		required: true,
 		ephemeral: false,
+		sensitive: false,
 	};
 
 export const MockTemplateVersionParameter3: TypesGen.TemplateVersionParameter =
@@ -1901,6 +1929,7 @@ export const MockTemplateVersionParameter3: TypesGen.TemplateVersionParameter =
 		validation_regex: "^[a-z]{3}$",
 		required: true,
 		ephemeral: false,
+		sensitive: false,
 	};
 
 const MockTemplateVersionParameter4: TypesGen.TemplateVersionParameter = {
@@ -1915,6 +1944,7 @@ const MockTemplateVersionParameter4: TypesGen.TemplateVersionParameter = {
 	options: [],
 	required: true,
 	ephemeral: false,
+	sensitive: false,
 };
 
 const MockTemplateVersionParameter5: TypesGen.TemplateVersionParameter = {
@@ -1932,6 +1962,7 @@ const MockTemplateVersionParameter5: TypesGen.TemplateVersionParameter = {
 	validation_monotonic: "decreasing",
 	required: true,
 	ephemeral: false,
+	sensitive: false,
 };
 
 export const MockTemplateVersionParameter6: TypesGen.TemplateVersionParameter =
@@ -1947,6 +1978,7 @@ export const MockTemplateVersionParameter6: TypesGen.TemplateVersionParameter =
 		options: [],
 		required: true,
 		ephemeral: true,
+		sensitive: false,
 	};
 
 // Not required and the default is a blank string.
@@ -1965,6 +1997,7 @@ const MockTemplateVersionParameter7: TypesGen.TemplateVersionParameter = {
 	validation_monotonic: "decreasing",
 	required: false,
 	ephemeral: false,
+	sensitive: false,
 };
 
 export const MockTemplateVersionVariable1: TypesGen.TemplateVersionVariable = {
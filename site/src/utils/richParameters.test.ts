@@ -41,6 +41,7 @@ test("getInitialRichParameterValues return default value when default build para
 			],
 			required: false,
 			ephemeral: false,
+			sensitive: false,
 		},
 	];
 
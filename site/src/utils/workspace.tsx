@@ -33,9 +33,13 @@ export const getDisplayWorkspaceBuildInitiatedBy = (
 		case "jetbrains_connection":
 		case "task_manual_pause":
 		case "task_resume":
+		case "archive":
+		case "unarchive":
+		case "self_requested":
 			return build.initiator_name;
 		case "autostart":
 		case "autostop":
+		case "budget_exceeded":
 		case "dormancy":
 		case "task_auto_pause":
 			return "Coder";
@@ -46,6 +50,7 @@ export const getDisplayWorkspaceBuildInitiatedBy = (
 export const systemBuildReasons = [
 	"autostart",
 	"autostop",
+	"budget_exceeded",
 	"dormancy",
 	"task_auto_pause",
 	"task_manual_pause",
@@ -60,10 +65,14 @@ export const buildReasonLabels: Record<TypesGen.BuildReason, string> = {
 	ssh_connection: "SSH Connection",
 	vscode_connection: "VSCode Connection",
 	jetbrains_connection: "JetBrains Connection",
+	archive: "Archive",
+	unarchive: "Unarchive",
+	self_requested: "Self-Requested",
 
 	// System build reasons
 	autostart: "Autostart",
 	autostop: "Autostop",
+	budget_exceeded: "Budget Exceeded",
 	dormancy: "Dormancy",
 	task_auto_pause: "Task Auto-Pause",
 	task_manual_pause: "Task Manual Pause",
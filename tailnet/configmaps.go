@@ -34,6 +34,11 @@ import (
 
 const lostTimeout = 15 * time.Minute
 
+var allPrefixes = []netip.Prefix{
+	netip.PrefixFrom(netip.AddrFrom4([4]byte{}), 0),
+	netip.PrefixFrom(netip.AddrFrom16([16]byte{}), 0),
+}
+
 // CoderDNSSuffix is the default DNS suffix that we append to Coder DNS
 // records.
 const (
@@ -171,6 +176,20 @@ func (c *configMaps) configLoop() {
 				c.engine.SetDERPMap(derpMap)
 			})
 		}
+		var setFilter func()
+		if c.filterDirty {
+			f := c.filterLocked()
+			setFilter = func() {
+				c.logger.Debug(context.Background(), "updating engine filter", slog.F("filter", f))
+				c.engine.SetFilter(f)
+			}
+		}
+		// Peers restricted to some ports must never be reachable without
+		// their restrictions, so the filter is set before the network map.
+		filterFirst := setFilter != nil && c.hasRestrictedPeersLocked()
+		if filterFirst {
+			actions = append(actions, setFilter)
+		}
 		if c.netmapDirty {
 			nm := c.netMapLocked()
 			hosts := c.hostsLocked()
@@ -180,12 +199,8 @@ func (c *configMaps) configLoop() {
 				c.reconfig(nm, hosts)
 			})
 		}
-		if c.filterDirty {
-			f := c.filterLocked()
-			actions = append(actions, func() {
-				c.logger.Debug(context.Background(), "updating engine filter", slog.F("filter", f))
-				c.engine.SetFilter(f)
-			})
+		if setFilter != nil && !filterFirst {
+			actions = append(actions, setFilter)
 		}
 
 		c.netmapDirty = false
@@ -383,7 +398,7 @@ func (c *configMaps) filterLocked() *filter.Filter {
 	logIPSet := netipx.IPSetBuilder{}
 	logIPs, _ := logIPSet.IPSet()
 	return filter.New(
-		c.static.PacketFilter,
+		c.packetFilterLocked(),
 		localIPs,
 		logIPs,
 		nil,
@@ -391,6 +406,58 @@ func (c *configMaps) filterLocked() *filter.Filter {
 	)
 }
 
+// packetFilterLocked returns the packet filter matches. Peers with allowed
+// ports are excluded from the static filter, and may only connect to those
+// ports.  c.L must be held.
+func (c *configMaps) packetFilterLocked() []filter.Match {
+	var (
+		restrictedIPSet = netipx.IPSetBuilder{}
+		matches         []filter.Match
+	)
+	for _, lc := range c.peers {
+		if lc.node == nil || len(lc.allowedPorts) == 0 {
+			continue
+		}
+		srcs := slices.Concat(lc.node.Addresses, lc.node.AllowedIPs)
+		for _, src := range srcs {
+			restrictedIPSet.AddPrefix(src)
+		}
+		dsts := make([]filter.NetPortRange, 0, 2*len(lc.allowedPorts))
+		for _, port := range lc.allowedPorts {
+			for _, dst := range allPrefixes {
+				dsts = append(dsts, filter.NetPortRange{
+					Net:   dst,
+					Ports: filter.PortRange{First: port, Last: port},
+				})
+			}
+		}
+		matches = append(matches, filter.Match{
+			IPProto: []ipproto.Proto{ipproto.TCP, ipproto.UDP, ipproto.ICMPv4, ipproto.ICMPv6, ipproto.SCTP},
+			Srcs:    srcs,
+			Dsts:    dsts,
+			Caps:    []filter.CapMatch{},
+		})
+	}
+	if len(matches) == 0 {
+		return c.static.PacketFilter
+	}
+
+	unrestrictedIPSet := netipx.IPSetBuilder{}
+	for _, prefix := range allPrefixes {
+		unrestrictedIPSet.AddPrefix(prefix)
+	}
+	restrictedIPs, _ := restrictedIPSet.IPSet()
+	unrestrictedIPSet.RemoveSet(restrictedIPs)
+	unrestrictedIPs, _ := unrestrictedIPSet.IPSet()
+
+	out := make([]filter.Match, 0, len(c.static.PacketFilter)+len(matches))
+	for _, m := range c.static.PacketFilter {
+		m.Srcs = unrestrictedIPs.Prefixes()
+		out = append(out, m)
+	}
+	return append(out, matches...)
+}
+
 // updatePeers handles protocol updates about peers from the coordinator.  c.L MUST NOT be held.
 func (c *configMaps) updatePeers(updates []*proto.CoordinateResponse_PeerUpdate) {
 	status := c.status()
@@ -413,7 +480,7 @@ func (c *configMaps) updatePeers(updates []*proto.CoordinateResponse_PeerUpdate)
 			c.netmapDirty = true
 		}
 	}
-	if c.netmapDirty {
+	if c.netmapDirty || c.filterDirty {
 		c.Broadcast()
 	}
 }
@@ -465,6 +532,7 @@ func (c *configMaps) updatePeerLocked(update *proto.CoordinateResponse_PeerUpdat
 			lost:          false,
 		}
 		c.peers[id] = lc
+		c.setAllowedPortsLocked(lc, update.Node.GetAllowedPorts())
 		logger.Debug(context.Background(), "adding new peer")
 		return lc.validForWireguard()
 	case peerOk && update.Kind == proto.CoordinateResponse_PeerUpdate_NODE:
@@ -473,7 +541,12 @@ func (c *configMaps) updatePeerLocked(update *proto.CoordinateResponse_PeerUpdat
 			node.Created = lc.node.Created
 		}
 		dirty = !lc.node.Equal(node)
+		if dirty && len(lc.allowedPorts) > 0 {
+			// The addresses of the peer may have changed.
+			c.filterDirty = true
+		}
 		lc.node = node
+		c.setAllowedPortsLocked(lc, update.Node.GetAllowedPorts())
 		// validForWireguard checks that the node is non-nil, so should be
 		// called after we update the node.
 		dirty = dirty && lc.validForWireguard()
@@ -520,6 +593,9 @@ func (c *configMaps) updatePeerLocked(update *proto.CoordinateResponse_PeerUpdat
 	case update.Kind == proto.CoordinateResponse_PeerUpdate_DISCONNECTED:
 		lc.resetLostTimer()
 		delete(c.peers, id)
+		if len(lc.allowedPorts) > 0 {
+			c.filterDirty = true
+		}
 		logger.Debug(context.Background(), "disconnected peer")
 		return true
 	case update.Kind == proto.CoordinateResponse_PeerUpdate_LOST:
@@ -597,6 +673,9 @@ func (c *configMaps) peerLostTimeout(id uuid.UUID) {
 			context.Background(), "removing lost peer")
 		delete(c.peers, id)
 		c.netmapDirty = true
+		if len(lc.allowedPorts) > 0 {
+			c.filterDirty = true
+		}
 		c.Broadcast()
 		return
 	}
@@ -605,6 +684,42 @@ func (c *configMaps) peerLostTimeout(id uuid.UUID) {
 	lc.setLostTimer(c)
 }
 
+// hasRestrictedPeersLocked returns whether any peer may only connect to some
+// ports.  c.L must be held.
+func (c *configMaps) hasRestrictedPeersLocked() bool {
+	for _, lc := range c.peers {
+		if len(lc.allowedPorts) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// setAllowedPortsLocked sets the ports the peer may connect to, and marks the
+// filter dirty if they changed.  c.L must be held.
+func (c *configMaps) setAllowedPortsLocked(lc *peerLifecycle, ports []uint32) {
+	allowed := make([]uint16, 0, len(ports))
+	for _, port := range ports {
+		if port == 0 || port > 65535 {
+			c.logger.Warn(context.Background(), "ignoring invalid allowed port",
+				slog.F("peer_id", lc.peerID), slog.F("port", port))
+			continue
+		}
+		// #nosec G115 - Safe conversion as the port is checked above.
+		allowed = append(allowed, uint16(port))
+	}
+	if len(ports) > 0 && len(allowed) == 0 {
+		// Never widen the access of a restricted peer because of invalid
+		// ports. Port 0 is never served, so this denies all connections.
+		allowed = append(allowed, 0)
+	}
+	if slices.Equal(lc.allowedPorts, allowed) {
+		return
+	}
+	lc.allowedPorts = allowed
+	c.filterDirty = true
+}
+
 func (c *configMaps) protoNodeToTailcfg(p *proto.Node) (*tailcfg.Node, error) {
 	node, err := ProtoToNode(p)
 	if err != nil {
@@ -713,7 +828,10 @@ type peerLifecycle struct {
 	isDestination bool
 	// node is the tailcfg.Node for the peer. It may be nil until we receive a
 	// NODE update for it.
-	node                   *tailcfg.Node
+	node *tailcfg.Node
+	// allowedPorts are the only ports the peer may connect to. Empty allows
+	// all ports.
+	allowedPorts           []uint16
 	lost                   bool
 	lastHandshake          time.Time
 	lostTimer              *quartz.Timer
@@ -556,6 +556,84 @@ func TestConfigMaps_updatePeers_disconnect(t *testing.T) {
 	_ = testutil.TryReceive(ctx, t, done)
 }
 
+func TestConfigMaps_updatePeers_allowedPorts(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := testutil.Logger(t)
+	fEng := newFakeEngineConfigurable()
+	nodePrivateKey := key.NewNode()
+	nodeID := tailcfg.NodeID(5)
+	discoKey := key.NewDisco()
+	uut := newConfigMaps(logger, fEng, nodeID, nodePrivateKey, discoKey.Public(), CoderDNSSuffixFQDN)
+	defer uut.close()
+
+	localAddr := netip.MustParseAddr("fd60:627a:a42b::1")
+	uut.L.Lock()
+	uut.addresses = []netip.Prefix{netip.PrefixFrom(localAddr, 128)}
+	uut.L.Unlock()
+
+	p1ID := uuid.UUID{1}
+	p1Addr := netip.MustParseAddr("fd60:627a:a42b::2")
+	p1Node := newTestNode(1)
+	p1Node.Addresses = []netip.Prefix{netip.PrefixFrom(p1Addr, 128)}
+	p1Node.AllowedIPs = p1Node.Addresses
+	p1n, err := NodeToProto(p1Node)
+	require.NoError(t, err)
+	p1n.AllowedPorts = []uint32{8080}
+	otherAddr := netip.MustParseAddr("fd60:627a:a42b::3")
+
+	go func() {
+		<-fEng.status
+		fEng.statusDone <- struct{}{}
+	}()
+
+	// When: a peer restricted to some ports connects
+	uut.updatePeers([]*proto.CoordinateResponse_PeerUpdate{
+		{
+			Id:   p1ID[:],
+			Kind: proto.CoordinateResponse_PeerUpdate_NODE,
+			Node: p1n,
+		},
+	})
+
+	// Then: the filter is set before the peer is programmed, and the peer may
+	// only connect to the allowed ports.
+	f := testutil.TryReceive(ctx, t, fEng.filter)
+	require.Equal(t, filter.Accept, f.CheckTCP(p1Addr, localAddr, 8080))
+	require.Equal(t, filter.Drop, f.CheckTCP(p1Addr, localAddr, 22))
+	require.Equal(t, filter.Accept, f.CheckTCP(otherAddr, localAddr, 22))
+	nm := testutil.TryReceive(ctx, t, fEng.setNetworkMap)
+	require.Len(t, nm.Peers, 1)
+	_ = testutil.TryReceive(ctx, t, fEng.reconfig)
+
+	go func() {
+		<-fEng.status
+		fEng.statusDone <- struct{}{}
+	}()
+
+	// When: the peer disconnects
+	uut.updatePeers([]*proto.CoordinateResponse_PeerUpdate{
+		{
+			Id:   p1ID[:],
+			Kind: proto.CoordinateResponse_PeerUpdate_DISCONNECTED,
+		},
+	})
+
+	// Then: the peer is removed before the restrictions are lifted.
+	nm = testutil.TryReceive(ctx, t, fEng.setNetworkMap)
+	require.Len(t, nm.Peers, 0)
+	_ = testutil.TryReceive(ctx, t, fEng.reconfig)
+	f = testutil.TryReceive(ctx, t, fEng.filter)
+	require.Equal(t, filter.Accept, f.CheckTCP(p1Addr, localAddr, 22))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		uut.close()
+	}()
+	_ = testutil.TryReceive(ctx, t, done)
+}
+
 func TestConfigMaps_updatePeers_lost(t *testing.T) {
 	t.Parallel()
 	ctx := testutil.Context(t, testutil.WaitShort)
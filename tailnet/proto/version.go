@@ -81,9 +81,29 @@ import (
 //     deployments remain interoperable. Real persistence,
 //     KindMCPServer provider, and chatd hydration land in
 //     CODAGT-569.
+//
+// API v2.11:
+//   - Added per connection class rx/tx byte counters to Stats on the Agent
+//     API. Older coderd deployments ignore them.
+//   - Added allowed_ports to Node on the Tailnet API. Coderd sets it on the
+//     nodes of clients that may only forward some ports of a shared workspace,
+//     and agents drop connections from those clients to any other port.
+//   - Added read_only to Manifest on the Agent API. Agents of workspaces in
+//     read-only mode block outbound git pushes from new sessions.
+//   - Added oom_kills and memory_pressure to
+//     PushResourcesMonitoringUsageRequest.Datapoint on the Agent API. Older
+//     coderd deployments ignore them.
+//   - Added services to Manifest on the Agent API. Agents supervise them and
+//     report their status over the HTTP API.
+//   - Added RequestWorkspaceTransition RPC on the Agent API, which lets
+//     tooling inside a workspace stop or restart it through the agent if the
+//     owner allows self-requested builds.
+//   - Added sent_at to UpdateStatsRequest on the Agent API. Coderd compares it
+//     to its own clock to measure the clock skew of the agent. Older coderd
+//     deployments ignore it.
 const (
 	CurrentMajor = 2
-	CurrentMinor = 10
+	CurrentMinor = 11
 )
 
 var CurrentVersion = apiversion.New(CurrentMajor, CurrentMinor)
@@ -43,6 +43,10 @@ func (SingleTailnetCoordinateeAuth) Authorize(context.Context, *proto.Coordinate
 // ClientCoordinateeAuth allows connecting to a single, given agent
 type ClientCoordinateeAuth struct {
 	AgentID uuid.UUID
+	// AllowedPorts restricts the client to the given ports of the agent. It
+	// is stamped on every node update of the client, which the agent
+	// enforces. Empty allows all ports.
+	AllowedPorts []uint16
 }
 
 func (c ClientCoordinateeAuth) Authorize(_ context.Context, req *proto.CoordinateRequest) error {
@@ -57,6 +61,28 @@ func (c ClientCoordinateeAuth) Authorize(_ context.Context, req *proto.Coordinat
 		}
 	}
 
+	if upd := req.GetUpdateSelf(); upd != nil && upd.Node != nil {
+		// Always overwrite the allowed ports, since clients must not be able
+		// to set their own.
+		upd.Node.AllowedPorts = nil
+		for _, port := range c.AllowedPorts {
+			upd.Node.AllowedPorts = append(upd.Node.AllowedPorts, uint32(port))
+		}
+		if len(c.AllowedPorts) > 0 {
+			// Agents restrict traffic from the allowed IPs of the node, so a
+			// restricted client must not claim more than its own addresses.
+			for _, prefixStr := range upd.Node.AllowedIps {
+				pre, err := netip.ParsePrefix(prefixStr)
+				if err != nil {
+					return xerrors.Errorf("parse node allowed ip: %w", err)
+				}
+				if pre.Bits() != 128 {
+					return InvalidAddressBitsError{pre.Bits()}
+				}
+			}
+		}
+	}
+
 	return handleClientNodeRequests(req)
 }
 
@@ -114,6 +140,10 @@ type ClientUserCoordinateeAuth struct {
 }
 
 func (a ClientUserCoordinateeAuth) Authorize(ctx context.Context, req *proto.CoordinateRequest) error {
+	if upd := req.GetUpdateSelf(); upd != nil && upd.Node != nil {
+		// Clients must not be able to set their own allowed ports.
+		upd.Node.AllowedPorts = nil
+	}
 	if tun := req.GetAddTunnel(); tun != nil {
 		uid, err := uuid.FromBytes(tun.Id)
 		if err != nil {
@@ -1,10 +1,13 @@
 package tailnet
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/tailnet/proto"
 )
 
 func TestTunnelStore_Bidir(t *testing.T) {
@@ -58,3 +61,26 @@ func TestTunnelStore_TunnelExists(t *testing.T) {
 	require.False(t, uut.tunnelExists(p1, p2))
 	require.False(t, uut.tunnelExists(p2, p1))
 }
+
+func TestClientCoordinateeAuth_AllowedPorts(t *testing.T) {
+	t.Parallel()
+	agentID := uuid.UUID{1}
+
+	// Clients can't set their own allowed ports.
+	req := &proto.CoordinateRequest{UpdateSelf: &proto.CoordinateRequest_UpdateSelf{Node: &proto.Node{
+		Addresses:    []string{"fd60:627a:a42b::1/128"},
+		AllowedPorts: []uint32{22},
+	}}}
+	err := ClientCoordinateeAuth{AgentID: agentID}.Authorize(context.Background(), req)
+	require.NoError(t, err)
+	require.Empty(t, req.UpdateSelf.Node.AllowedPorts)
+
+	err = ClientCoordinateeAuth{AgentID: agentID, AllowedPorts: []uint16{80, 8080}}.Authorize(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{80, 8080}, req.UpdateSelf.Node.AllowedPorts)
+
+	// Restricted clients can't claim more than their own addresses.
+	req.UpdateSelf.Node.AllowedIps = []string{"::/0"}
+	err = ClientCoordinateeAuth{AgentID: agentID, AllowedPorts: []uint16{80}}.Authorize(context.Background(), req)
+	require.ErrorAs(t, err, &InvalidAddressBitsError{})
+}